@@ -24,6 +24,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"go.uber.org/atomic"
@@ -38,11 +39,14 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/retry"
 
+	"github.com/coder/coder/v2/agent/agentdevcontainer"
+	"github.com/coder/coder/v2/agent/agentgpu"
 	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/agentscripts"
 	"github.com/coder/coder/v2/agent/agentssh"
 	"github.com/coder/coder/v2/agent/reconnectingpty"
 	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/cli/clistat"
 	"github.com/coder/coder/v2/cli/gitauth"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
@@ -78,8 +82,20 @@ type Options struct {
 	Addresses                    []netip.Prefix
 	PrometheusRegistry           *prometheus.Registry
 	ReportMetadataInterval       time.Duration
+	ReportListeningPortsInterval time.Duration
 	ServiceBannerRefreshInterval time.Duration
 	Syscaller                    agentproc.Syscaller
+	// SSHMaxFileSize is the max size in bytes of files transferred over SSH
+	// with SFTP or SCP, or with the file endpoints. Zero is unlimited.
+	SSHMaxFileSize int64
+	// Devcontainer configures the dev container started after the startup
+	// scripts.
+	Devcontainer agentdevcontainer.Settings
+	// TokenRotationInterval is how often the agent rotates its session token.
+	// Zero disables rotation.
+	TokenRotationInterval time.Duration
+	// TokenRotated is called with the new session token after a rotation.
+	TokenRotated func(token string)
 	// ModifiedProcesses is used for testing process priority management.
 	ModifiedProcesses chan []*agentproc.Process
 	// ProcessManagementTick is used for testing process priority management.
@@ -93,10 +109,13 @@ type Client interface {
 	ReportStats(ctx context.Context, log slog.Logger, statsChan <-chan *agentsdk.Stats, setInterval func(time.Duration)) (io.Closer, error)
 	PostLifecycle(ctx context.Context, state agentsdk.PostLifecycleRequest) error
 	PostAppHealth(ctx context.Context, req agentsdk.PostAppHealthsRequest) error
+	PostListeningPorts(ctx context.Context, req agentsdk.PostListeningPortsRequest) error
 	PostStartup(ctx context.Context, req agentsdk.PostStartupRequest) error
 	PostMetadata(ctx context.Context, req agentsdk.PostMetadataRequest) error
 	PatchLogs(ctx context.Context, req agentsdk.PatchLogs) error
+	PostLogSource(ctx context.Context, req agentsdk.PostLogSource) (codersdk.WorkspaceAgentLogSource, error)
 	GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error)
+	RotateToken(ctx context.Context) (agentsdk.RotateTokenResponse, error)
 }
 
 type Agent interface {
@@ -127,6 +146,9 @@ func New(options Options) Agent {
 	if options.ReportMetadataInterval == 0 {
 		options.ReportMetadataInterval = time.Second
 	}
+	if options.ReportListeningPortsInterval == 0 {
+		options.ReportListeningPortsInterval = 5 * time.Second
+	}
 	if options.ServiceBannerRefreshInterval == 0 {
 		options.ServiceBannerRefreshInterval = 2 * time.Minute
 	}
@@ -163,8 +185,13 @@ func New(options Options) Agent {
 		portCacheDuration:            options.PortCacheDuration,
 		connStatsChan:                make(chan *agentsdk.Stats, 1),
 		reportMetadataInterval:       options.ReportMetadataInterval,
+		reportListeningPortsInterval: options.ReportListeningPortsInterval,
 		serviceBannerRefreshInterval: options.ServiceBannerRefreshInterval,
+		tokenRotationInterval:        options.TokenRotationInterval,
+		tokenRotated:                 options.TokenRotated,
 		sshMaxTimeout:                options.SSHMaxTimeout,
+		sshMaxFileSize:               options.SSHMaxFileSize,
+		devcontainerSettings:         options.Devcontainer,
 		subsystems:                   options.Subsystems,
 		addresses:                    options.Addresses,
 		syscaller:                    options.Syscaller,
@@ -205,12 +232,20 @@ type agent struct {
 
 	manifest                     atomic.Pointer[agentsdk.Manifest] // manifest is atomic because values can change after reconnection.
 	reportMetadataInterval       time.Duration
+	reportListeningPortsInterval time.Duration
 	scriptRunner                 *agentscripts.Runner
 	serviceBanner                atomic.Pointer[codersdk.ServiceBannerConfig] // serviceBanner is atomic because it is periodically updated.
 	serviceBannerRefreshInterval time.Duration
 	sessionToken                 atomic.Pointer[string]
+	tokenRotationInterval        time.Duration
+	tokenRotated                 func(token string)
 	sshServer                    *agentssh.Server
 	sshMaxTimeout                time.Duration
+	sshMaxFileSize               int64
+	devcontainerSettings         agentdevcontainer.Settings
+	// devcontainerPorts are the labels of the ports forwarded from the dev
+	// container, which are reported instead of the process name.
+	devcontainerPorts atomic.Pointer[map[uint16]string]
 
 	lifecycleUpdate   chan struct{}
 	lifecycleReported chan codersdk.WorkspaceAgentLifecycle
@@ -221,6 +256,9 @@ type agent struct {
 	addresses     []netip.Prefix
 	connStatsChan chan *agentsdk.Stats
 	latestStat    atomic.Pointer[agentsdk.Stats]
+	// statter is only used by the stats reporting callback, which is
+	// never run concurrently.
+	statter *clistat.Statter
 
 	connCountReconnectingPTY atomic.Int64
 
@@ -249,6 +287,7 @@ func (a *agent) init(ctx context.Context) {
 	sshSrv.AgentToken = func() string { return *a.sessionToken.Load() }
 	sshSrv.Manifest = &a.manifest
 	sshSrv.ServiceBanner = &a.serviceBanner
+	sshSrv.MaxFileSize = a.sshMaxFileSize
 	a.sshServer = sshSrv
 	a.scriptRunner = agentscripts.New(agentscripts.Options{
 		LogDir:     a.logDir,
@@ -270,7 +309,9 @@ func (a *agent) init(ctx context.Context) {
 func (a *agent) runLoop(ctx context.Context) {
 	go a.reportLifecycleLoop(ctx)
 	go a.reportMetadataLoop(ctx)
+	go a.reportListeningPortsLoop(ctx)
 	go a.fetchServiceBannerLoop(ctx)
+	go a.rotateTokenLoop(ctx)
 	go a.manageProcessPriorityLoop(ctx)
 
 	for retrier := retry.New(100*time.Millisecond, 10*time.Second); retrier.Wait(ctx); {
@@ -584,6 +625,82 @@ func (a *agent) reportMetadataLoop(ctx context.Context) {
 	}
 }
 
+// startDevcontainer builds and starts the dev container of the workspace, and
+// labels the ports it forwards.
+func (a *agent) startDevcontainer(ctx context.Context) error {
+	runner := agentdevcontainer.New(agentdevcontainer.Options{
+		Settings:      a.devcontainerSettings,
+		Logger:        a.logger.Named("devcontainer"),
+		Filesystem:    a.filesystem,
+		PatchLogs:     a.client.PatchLogs,
+		PostLogSource: a.client.PostLogSource,
+	})
+	ports, err := runner.Start(ctx)
+	if err != nil {
+		return xerrors.Errorf("start dev container: %w", err)
+	}
+	labels := make(map[uint16]string, len(ports))
+	for _, port := range ports {
+		labels[port.Port] = port.Label
+	}
+	a.devcontainerPorts.Store(&labels)
+	return nil
+}
+
+// reportListeningPortsLoop periodically scans the TCP ports listening in the
+// workspace, and reports them to coderd when they change. Coderd exposes them
+// as discovered apps.
+func (a *agent) reportListeningPortsLoop(ctx context.Context) {
+	lp := &listeningPortsHandler{
+		ignorePorts:   a.ignorePorts,
+		cacheDuration: a.portCacheDuration,
+	}
+	ticker := time.NewTicker(a.reportListeningPortsInterval)
+	defer ticker.Stop()
+
+	// The ports are always reported once, to clear the ports of a previous
+	// run of the agent.
+	var reported []codersdk.WorkspaceAgentListeningPort
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ports, err := lp.getListeningPorts()
+		if err != nil {
+			a.logger.Debug(ctx, "failed to scan listening ports", slog.Error(err))
+			continue
+		}
+		if labels := a.devcontainerPorts.Load(); labels != nil {
+			for i, port := range ports {
+				// Ports published by docker are listened on by
+				// docker-proxy, so the label is more helpful.
+				if label := (*labels)[port.Port]; label != "" {
+					ports[i].ProcessName = label
+				}
+			}
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			return ports[i].Port < ports[j].Port
+		})
+		if reported != nil && slices.Equal(ports, reported) {
+			continue
+		}
+
+		err = a.client.PostListeningPorts(ctx, agentsdk.PostListeningPortsRequest{Ports: ports})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Error(ctx, "failed to report listening ports", slog.Error(err))
+			continue
+		}
+		reported = ports
+	}
+}
+
 // reportLifecycleLoop reports the current lifecycle state once. All state
 // changes are reported in order.
 func (a *agent) reportLifecycleLoop(ctx context.Context) {
@@ -684,6 +801,37 @@ func (a *agent) fetchServiceBannerLoop(ctx context.Context) {
 	}
 }
 
+// rotateTokenLoop replaces the agent's session token on an interval. The
+// previous token keeps working for a while, so connections that were opened
+// with it are not interrupted.
+func (a *agent) rotateTokenLoop(ctx context.Context) {
+	if a.tokenRotationInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(a.tokenRotationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			resp, err := a.client.RotateToken(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				a.logger.Error(ctx, "failed to rotate session token", slog.Error(err))
+				continue
+			}
+			a.sessionToken.Store(&resp.SessionToken)
+			a.logger.Info(ctx, "rotated session token", slog.F("previous_token_expires_at", resp.PreviousTokenExpiresAt))
+			if a.tokenRotated != nil {
+				a.tokenRotated(resp.SessionToken)
+			}
+		}
+	}
+}
+
 func (a *agent) run(ctx context.Context) error {
 	// This allows the agent to refresh it's token if necessary.
 	// For instance identity this is required, since the instance
@@ -723,6 +871,7 @@ func (a *agent) run(ctx context.Context) error {
 		Version:           buildinfo.Version(),
 		ExpandedDirectory: manifest.Directory,
 		Subsystems:        a.subsystems,
+		GPUs:              agentgpu.Detect(ctx, a.logger, a.filesystem),
 	})
 	if err != nil {
 		return xerrors.Errorf("update workspace agent version: %w", err)
@@ -759,6 +908,17 @@ func (a *agent) run(ctx context.Context) error {
 			})
 			// Measure the time immediately after the script has finished
 			dur := time.Since(start).Seconds()
+			label := "false"
+			if err == nil {
+				label = "true"
+			}
+			a.metrics.startupScriptSeconds.WithLabelValues(label).Set(dur)
+
+			// The dev container is started after the startup scripts,
+			// which usually clone its repository.
+			if err == nil && a.devcontainerSettings.Enabled() {
+				err = a.startDevcontainer(ctx)
+			}
 			if err != nil {
 				a.logger.Warn(ctx, "startup script(s) failed", slog.Error(err))
 				if errors.Is(err, agentscripts.ErrTimeout) {
@@ -770,11 +930,6 @@ func (a *agent) run(ctx context.Context) error {
 				a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleReady)
 			}
 
-			label := "false"
-			if err == nil {
-				label = "true"
-			}
-			a.metrics.startupScriptSeconds.WithLabelValues(label).Set(dur)
 			a.scriptRunner.StartCron()
 		})
 		if err != nil {
@@ -942,23 +1097,10 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 			}()
 			go func() {
 				defer close(closed)
-				// This cannot use a JSON decoder, since that can
-				// buffer additional data that is required for the PTY.
-				rawLen := make([]byte, 2)
-				_, err = conn.Read(rawLen)
+				msg, err := readReconnectingPTYInit(conn)
 				if err != nil {
-					return
-				}
-				length := binary.LittleEndian.Uint16(rawLen)
-				data := make([]byte, length)
-				_, err = conn.Read(data)
-				if err != nil {
-					return
-				}
-				var msg codersdk.WorkspaceAgentReconnectingPTYInit
-				err = json.Unmarshal(data, &msg)
-				if err != nil {
-					logger.Warn(ctx, "failed to unmarshal init", slog.F("raw", data))
+					logger.Warn(ctx, "failed to read init", slog.Error(err))
+					_ = conn.Close()
 					return
 				}
 				_ = a.handleReconnectingPTY(ctx, clog, msg, conn)
@@ -969,6 +1111,47 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		return nil, err
 	}
 
+	reconnectingPTYMuxListener, err := network.Listen("tcp", ":"+strconv.Itoa(codersdk.WorkspaceAgentReconnectingPTYMuxPort))
+	if err != nil {
+		return nil, xerrors.Errorf("listen for multiplexed reconnecting pty: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = reconnectingPTYMuxListener.Close()
+		}
+	}()
+	if err = a.trackConnGoroutine(func() {
+		logger := a.logger.Named("reconnecting-pty-mux")
+		var wg sync.WaitGroup
+		for {
+			conn, err := reconnectingPTYMuxListener.Accept()
+			if err != nil {
+				if !a.isClosed() {
+					logger.Debug(ctx, "accept pty mux failed", slog.Error(err))
+				}
+				break
+			}
+			clog := logger.With(
+				slog.F("remote", conn.RemoteAddr().String()),
+				slog.F("local", conn.LocalAddr().String()))
+			clog.Info(ctx, "accepted conn")
+			session, err := yamux.Server(conn, codersdk.ReconnectingPTYMuxConfig())
+			if err != nil {
+				clog.Warn(ctx, "failed to multiplex conn", slog.Error(err))
+				_ = conn.Close()
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a.serveReconnectingPTYMux(ctx, clog, session)
+			}()
+		}
+		wg.Wait()
+	}); err != nil {
+		return nil, err
+	}
+
 	speedtestListener, err := network.Listen("tcp", ":"+strconv.Itoa(codersdk.WorkspaceAgentSpeedtestPort))
 	if err != nil {
 		return nil, xerrors.Errorf("listen for speedtest: %w", err)
@@ -1060,7 +1243,7 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	conn, err := a.client.Listen(ctx)
+	conn, err := a.listen(ctx)
 	if err != nil {
 		return err
 	}
@@ -1092,6 +1275,51 @@ func (a *agent) runCoordinator(ctx context.Context, network *tailnet.Conn) error
 	}
 }
 
+// quicDialTimeout bounds how long the agent tries to connect over QUIC
+// before falling back to a WebSocket.
+const quicDialTimeout = 10 * time.Second
+
+// quicClient is implemented by clients that can connect to the agent API of
+// coderd over QUIC.
+type quicClient interface {
+	ListenQUIC(ctx context.Context, address string) (drpc.Conn, error)
+}
+
+// listen connects to the agent API of coderd. QUIC is preferred when coderd
+// advertises it in the manifest. If it can't be reached, e.g. because UDP is
+// blocked, the agent falls back to the WebSocket transport.
+func (a *agent) listen(ctx context.Context) (drpc.Conn, error) {
+	manifest := a.manifest.Load()
+	qc, ok := a.client.(quicClient)
+	if ok && manifest != nil && manifest.QUICAddress != "" {
+		start := time.Now()
+		dialCtx, cancel := context.WithTimeout(ctx, quicDialTimeout)
+		conn, err := qc.ListenQUIC(dialCtx, manifest.QUICAddress)
+		cancel()
+		if err == nil {
+			a.metrics.coderdConnectSeconds.WithLabelValues("quic").Set(time.Since(start).Seconds())
+			a.logger.Debug(ctx, "connected to coderd over QUIC", slog.F("address", manifest.QUICAddress))
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		a.metrics.quicFallbacks.Add(1)
+		a.logger.Warn(ctx, "failed to connect to coderd over QUIC, falling back to websocket",
+			slog.F("address", manifest.QUICAddress),
+			slog.Error(err),
+		)
+	}
+
+	start := time.Now()
+	conn, err := a.client.Listen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.metrics.coderdConnectSeconds.WithLabelValues("websocket").Set(time.Since(start).Seconds())
+	return conn, nil
+}
+
 // runDERPMapSubscriber runs a coordinator and returns if a reconnect should occur.
 func (a *agent) runDERPMapSubscriber(ctx context.Context, network *tailnet.Conn) error {
 	ctx, cancel := context.WithCancel(ctx)
@@ -1120,6 +1348,64 @@ func (a *agent) runDERPMapSubscriber(ctx context.Context, network *tailnet.Conn)
 	}
 }
 
+// readReconnectingPTYInit reads the length-prefixed init message of a
+// reconnecting PTY session. This cannot use a JSON decoder, since that can
+// buffer additional data that is required for the PTY.
+func readReconnectingPTYInit(conn net.Conn) (codersdk.WorkspaceAgentReconnectingPTYInit, error) {
+	var msg codersdk.WorkspaceAgentReconnectingPTYInit
+	rawLen := make([]byte, 2)
+	_, err := io.ReadFull(conn, rawLen)
+	if err != nil {
+		return msg, xerrors.Errorf("read length: %w", err)
+	}
+	data := make([]byte, binary.LittleEndian.Uint16(rawLen))
+	_, err = io.ReadFull(conn, data)
+	if err != nil {
+		return msg, xerrors.Errorf("read init: %w", err)
+	}
+	err = json.Unmarshal(data, &msg)
+	if err != nil {
+		return msg, xerrors.Errorf("unmarshal init %q: %w", data, err)
+	}
+	return msg, nil
+}
+
+// serveReconnectingPTYMux serves every stream of a multiplexed connection as
+// a reconnecting PTY session, until the connection or the agent is closed.
+func (a *agent) serveReconnectingPTYMux(ctx context.Context, logger slog.Logger, session *yamux.Session) {
+	defer session.Close()
+	go func() {
+		select {
+		case <-session.CloseChan():
+		case <-a.closed:
+			_ = session.Close()
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		stream, err := session.AcceptStream()
+		if err != nil {
+			if !a.isClosed() && !session.IsClosed() {
+				logger.Debug(ctx, "accept pty stream failed", slog.Error(err))
+			}
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg, err := readReconnectingPTYInit(stream)
+			if err != nil {
+				logger.Warn(ctx, "failed to read init", slog.Error(err))
+				_ = stream.Close()
+				return
+			}
+			_ = a.handleReconnectingPTY(ctx, logger.With(slog.F("stream_id", stream.StreamID())), msg, stream)
+		}()
+	}
+}
+
 func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, msg codersdk.WorkspaceAgentReconnectingPTYInit, conn net.Conn) (retErr error) {
 	defer conn.Close()
 	a.metrics.connectionsTotal.Add(1)
@@ -1278,6 +1564,9 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 		a.logger.Debug(ctx, "collecting agent metrics for stats")
 		stats.Metrics = a.collectMetrics(metricsCtx)
 
+		a.logger.Debug(ctx, "collecting resource usage for stats")
+		a.collectResourceUsage(ctx, stats)
+
 		a.latestStat.Store(stats)
 
 		a.logger.Debug(ctx, "about to send stats")
@@ -1292,7 +1581,8 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 	// Report statistics from the created network.
 	cl, err := a.client.ReportStats(ctx, a.logger, a.connStatsChan, func(d time.Duration) {
 		a.network.SetConnStatsCallback(d, 2048,
-			func(_, _ time.Time, virtual, _ map[netlogtype.Connection]netlogtype.Counts) {
+			func(_, _ time.Time, virtual, physical map[netlogtype.Connection]netlogtype.Counts) {
+				a.metrics.observePhysicalTraffic(physical)
 				reportStats(virtual)
 			},
 		)
@@ -28,6 +28,7 @@ import (
 
 	"github.com/bramvdbogaerde/go-scp"
 	"github.com/google/uuid"
+	"github.com/hashicorp/yamux"
 	"github.com/pion/udp"
 	"github.com/pkg/sftp"
 	"github.com/prometheus/client_golang/prometheus"
@@ -836,7 +837,7 @@ func TestAgent_SFTP(t *testing.T) {
 		home = "/" + strings.ReplaceAll(home, "\\", "/")
 	}
 	//nolint:dogsled
-	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
 	sshClient, err := conn.SSHClient(ctx)
 	require.NoError(t, err)
 	defer sshClient.Close()
@@ -857,7 +858,8 @@ func TestAgent_SFTP(t *testing.T) {
 	require.NoError(t, err)
 	err = file.Close()
 	require.NoError(t, err)
-	_, err = os.Stat(tempFile)
+	// Transfers go through the agent's filesystem.
+	_, err = fs.Stat(tempFile)
 	require.NoError(t, err)
 }
 
@@ -868,7 +870,7 @@ func TestAgent_SCP(t *testing.T) {
 	defer cancel()
 
 	//nolint:dogsled
-	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0)
 	sshClient, err := conn.SSHClient(ctx)
 	require.NoError(t, err)
 	defer sshClient.Close()
@@ -879,8 +881,10 @@ func TestAgent_SCP(t *testing.T) {
 	content := "hello world"
 	err = scpClient.CopyFile(context.Background(), strings.NewReader(content), tempFile, "0755")
 	require.NoError(t, err)
-	_, err = os.Stat(tempFile)
+	// Transfers go through the agent's filesystem.
+	got, err := afero.ReadFile(fs, tempFile)
 	require.NoError(t, err)
+	require.Equal(t, content, string(got))
 }
 
 func TestAgent_EnvironmentVariables(t *testing.T) {
@@ -1122,6 +1126,43 @@ func TestAgentMetadata_Timing(t *testing.T) {
 	}
 }
 
+func TestAgent_ReportListeningPorts(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("Listening ports are only scanned on Linux in this test")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := uint16(l.Addr().(*net.TCPAddr).Port)
+
+	//nolint:dogsled
+	_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+		o.ReportListeningPortsInterval = testutil.IntervalFast
+		o.PortCacheDuration = time.Millisecond
+	})
+
+	require.Eventually(t, func() bool {
+		for _, p := range client.GetListeningPorts() {
+			if p.Port == port {
+				return true
+			}
+		}
+		return false
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	_ = l.Close()
+	require.Eventually(t, func() bool {
+		for _, p := range client.GetListeningPorts() {
+			if p.Port == port {
+				return false
+			}
+		}
+		return true
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgent_Lifecycle(t *testing.T) {
 	t.Parallel()
 
@@ -1587,6 +1628,40 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYMultiplexed(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+	// Sessions are streams of the same connection, and don't interfere with
+	// each other.
+	netConn1, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, "echo one")
+	require.NoError(t, err)
+	defer netConn1.Close()
+	netConn2, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, "echo two")
+	require.NoError(t, err)
+	defer netConn2.Close()
+
+	stream1, ok := netConn1.(*yamux.Stream)
+	require.True(t, ok, "expected a multiplexed stream, got %T", netConn1)
+	stream2, ok := netConn2.(*yamux.Stream)
+	require.True(t, ok, "expected a multiplexed stream, got %T", netConn2)
+	require.NotEqual(t, stream1.StreamID(), stream2.StreamID())
+
+	output2, err := io.ReadAll(netConn2)
+	require.NoError(t, err)
+	require.Contains(t, string(output2), "two")
+	output1, err := io.ReadAll(netConn1)
+	require.NoError(t, err)
+	require.Contains(t, string(output1), "one")
+	require.NotContains(t, string(output1), "two")
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -1806,6 +1881,62 @@ func TestAgent_Speedtest(t *testing.T) {
 	t.Logf("%.2f MBits/s", res[len(res)-1].MBitsPerSecond())
 }
 
+func TestAgent_Files(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Traversal", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+
+		_, err := conn.ListFiles(ctx, "../..")
+		requireFilesStatus(t, err, http.StatusBadRequest)
+		_, err = conn.DownloadFile(ctx, "~/../../etc/passwd")
+		requireFilesStatus(t, err, http.StatusBadRequest)
+		_, err = conn.UploadFile(ctx, "dir/../../outside.txt", strings.NewReader("content"))
+		requireFilesStatus(t, err, http.StatusBadRequest)
+
+		// Paths that stay in the home directory are accepted.
+		file, err := conn.UploadFile(ctx, "dir/../inside.txt", strings.NewReader("content"))
+		require.NoError(t, err)
+		require.Equal(t, "inside.txt", file.Name)
+	})
+
+	t.Run("MaxFileSize", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:dogsled
+		conn, _, _, fs, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			o.SSHMaxFileSize = 4
+		})
+
+		_, err := conn.UploadFile(ctx, "large.txt", strings.NewReader("too large"))
+		requireFilesStatus(t, err, http.StatusRequestEntityTooLarge)
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		_, err = fs.Stat(filepath.Join(home, "large.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		_, err = conn.UploadFile(ctx, "small.txt", strings.NewReader("tiny"))
+		require.NoError(t, err)
+
+		err = afero.WriteFile(fs, filepath.Join(home, "written.txt"), []byte("too large"), 0o600)
+		require.NoError(t, err)
+		_, err = conn.DownloadFile(ctx, "written.txt")
+		requireFilesStatus(t, err, http.StatusRequestEntityTooLarge)
+	})
+}
+
+func requireFilesStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, status, sdkErr.StatusCode())
+}
+
 func TestAgent_Reconnect(t *testing.T) {
 	t.Parallel()
 	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
@@ -2220,6 +2351,22 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 	require.NoError(t, err)
 
 	expected := []agentsdk.AgentMetric{
+		{
+			Name:  "agent_coderd_connect_seconds",
+			Type:  agentsdk.AgentMetricTypeGauge,
+			Value: 0,
+			Labels: []agentsdk.AgentMetricLabel{
+				{
+					Name:  "transport",
+					Value: "websocket",
+				},
+			},
+		},
+		{
+			Name:  "agent_coderd_quic_fallbacks_total",
+			Type:  agentsdk.AgentMetricTypeCounter,
+			Value: 0,
+		},
 		{
 			Name:  "agent_reconnecting_pty_connections_total",
 			Type:  agentsdk.AgentMetricTypeCounter,
@@ -2245,6 +2392,16 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 			Type:  agentsdk.AgentMetricTypeCounter,
 			Value: 0,
 		},
+		{
+			Name:  "agent_ssh_server_scp_connections_total",
+			Type:  agentsdk.AgentMetricTypeCounter,
+			Value: 0,
+		},
+		{
+			Name:  "agent_ssh_server_scp_errors_total",
+			Type:  agentsdk.AgentMetricTypeCounter,
+			Value: 0,
+		},
 		{
 			Name:  "agent_ssh_server_sftp_connections_total",
 			Type:  agentsdk.AgentMetricTypeCounter,
@@ -2270,10 +2427,18 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 
 	var actual []*promgo.MetricFamily
 	assert.Eventually(t, func() bool {
-		actual, err = registry.Gather()
+		gathered, err := registry.Gather()
 		if err != nil {
 			return false
 		}
+		// The traffic counters depend on whether the connection is relayed
+		// through DERP, so they're checked separately.
+		actual = nil
+		for _, family := range gathered {
+			if family.GetName() != "coderd_agentstats_tailnet_bytes_total" {
+				actual = append(actual, family)
+			}
+		}
 
 		if len(expected) != len(actual) {
 			return false
@@ -2286,6 +2451,24 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 	collected := verifyCollectedMetrics(t, expected, actual)
 	require.True(t, collected, "expected metrics were not collected")
 
+	require.Eventually(t, func() bool {
+		gathered, err := registry.Gather()
+		if err != nil {
+			return false
+		}
+		for _, family := range gathered {
+			if family.GetName() != "coderd_agentstats_tailnet_bytes_total" {
+				continue
+			}
+			var total float64
+			for _, m := range family.GetMetric() {
+				total += m.GetCounter().GetValue()
+			}
+			return total > 0
+		}
+		return false
+	}, testutil.WaitLong, testutil.IntervalFast, "tailnet traffic was not counted")
+
 	_ = stdin.Close()
 	err = session.Wait()
 	require.NoError(t, err)
@@ -0,0 +1,394 @@
+package agentdevcontainer
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+const (
+	BuilderDocker     = "docker"
+	BuilderEnvbuilder = "envbuilder"
+
+	// EnvbuilderImage is the image used to build dev containers with
+	// envbuilder.
+	EnvbuilderImage = "ghcr.io/coder/envbuilder:latest"
+	// envbuilderReadyLog is logged by envbuilder once the dev container is
+	// built and its init script is started.
+	envbuilderReadyLog = "=== Running the init command"
+)
+
+// LogSourceID is the statically-defined ID of the log source of the dev
+// container. It's the same for every agent, so restarts of the agent don't
+// create a new log source.
+var LogSourceID = uuid.MustParse("a8a1f5c9-1e2b-4d7a-9c1b-6f7b0d0c3e54")
+
+// Settings configure the dev container started by the agent. Templates set
+// them with environment variables of the agent.
+type Settings struct {
+	// WorkspaceFolder is the folder of the repository containing
+	// devcontainer.json. The dev container is disabled when it's empty.
+	WorkspaceFolder string
+	// ConfigPath is the path of devcontainer.json, relative to the
+	// workspace folder. By default, .devcontainer/devcontainer.json and
+	// .devcontainer.json are tried.
+	ConfigPath string
+	// Builder is BuilderDocker or BuilderEnvbuilder.
+	Builder string
+}
+
+func (s Settings) Enabled() bool {
+	return s.WorkspaceFolder != ""
+}
+
+type Options struct {
+	Settings
+	Logger        slog.Logger
+	Filesystem    afero.Fs
+	PatchLogs     func(ctx context.Context, req agentsdk.PatchLogs) error
+	PostLogSource func(ctx context.Context, req agentsdk.PostLogSource) (codersdk.WorkspaceAgentLogSource, error)
+	// Command runs docker. It's exec.CommandContext by default.
+	Command func(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// Runner builds and starts a dev container.
+type Runner struct {
+	Options
+}
+
+func New(opts Options) *Runner {
+	if opts.Builder == "" {
+		opts.Builder = BuilderDocker
+	}
+	if opts.Command == nil {
+		opts.Command = exec.CommandContext
+	}
+	return &Runner{Options: opts}
+}
+
+// Start builds and starts the dev container, and runs its lifecycle commands.
+// The progress is sent to the startup logs of the agent. It returns the ports
+// forwarded from the dev container.
+func (r *Runner) Start(ctx context.Context) ([]ForwardedPort, error) {
+	_, err := r.PostLogSource(ctx, agentsdk.PostLogSource{
+		ID:          LogSourceID,
+		DisplayName: "Dev Container",
+		Icon:        "/icon/container.svg",
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("create log source: %w", err)
+	}
+
+	send, flushAndClose := agentsdk.LogsSender(LogSourceID, r.PatchLogs, r.Logger)
+	infoW := agentsdk.LogsWriter(ctx, send, LogSourceID, codersdk.LogLevelInfo)
+	errW := agentsdk.LogsWriter(ctx, send, LogSourceID, codersdk.LogLevelError)
+	defer func() {
+		_ = infoW.Close()
+		_ = errW.Close()
+		err := flushAndClose(ctx)
+		if err != nil {
+			r.Logger.Warn(ctx, "flush dev container logs", slog.Error(err))
+		}
+	}()
+
+	ports, err := r.start(ctx, infoW, errW)
+	if err != nil {
+		_, _ = fmt.Fprintf(errW, "Failed to start the dev container: %s\n", err)
+		return nil, err
+	}
+	_, _ = fmt.Fprintln(infoW, "The dev container is running.")
+	return ports, nil
+}
+
+func (r *Runner) start(ctx context.Context, infoW, errW io.Writer) ([]ForwardedPort, error) {
+	configPath, data, err := r.readConfig()
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(infoW, "Using %s with %s.\n", configPath, r.Builder)
+	config, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := config.ForwardedPorts()
+	if err != nil {
+		return nil, err
+	}
+
+	name := containerName(r.WorkspaceFolder)
+	run := func(args ...string) error {
+		cmd := r.Command(ctx, "docker", args...)
+		cmd.Stdout = infoW
+		cmd.Stderr = errW
+		err := cmd.Run()
+		if err != nil {
+			return xerrors.Errorf("docker %s: %w", args[0], err)
+		}
+		return nil
+	}
+
+	// The container is kept when the agent restarts, like the dev containers
+	// of VS Code.
+	running, exists := r.inspect(ctx, name)
+	if exists {
+		_, _ = fmt.Fprintf(infoW, "Starting the existing dev container %s...\n", name)
+		if !running {
+			err = run("start", name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if r.Builder == BuilderDocker {
+			err = r.runCommand(ctx, run, name, config, "postStartCommand", config.PostStartCommand)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return ports, nil
+	}
+
+	switch r.Builder {
+	case BuilderDocker:
+		image := config.Image
+		if config.Build != nil || config.DockerFile != "" {
+			image = name
+			_, _ = fmt.Fprintln(infoW, "Building the dev container image...")
+			err = run(buildArgs(config, filepath.Dir(configPath), image)...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, _ = fmt.Fprintln(infoW, "Creating the dev container...")
+		err = run(dockerRunArgs(config, ports, r.WorkspaceFolder, name, image)...)
+		if err != nil {
+			return nil, err
+		}
+		err = r.runCommand(ctx, run, name, config, "postCreateCommand", config.PostCreateCommand)
+		if err != nil {
+			return nil, err
+		}
+		err = r.runCommand(ctx, run, name, config, "postStartCommand", config.PostStartCommand)
+		if err != nil {
+			return nil, err
+		}
+	case BuilderEnvbuilder:
+		_, _ = fmt.Fprintln(infoW, "Building the dev container with envbuilder...")
+		err = run(envbuilderRunArgs(ports, r.WorkspaceFolder, configPath, name)...)
+		if err != nil {
+			return nil, err
+		}
+		err = r.followEnvbuilder(ctx, name, infoW)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, xerrors.Errorf("unknown dev container builder %q", r.Builder)
+	}
+	return ports, nil
+}
+
+// readConfig returns the path and content of devcontainer.json.
+func (r *Runner) readConfig() (string, []byte, error) {
+	paths := []string{
+		filepath.Join(".devcontainer", "devcontainer.json"),
+		".devcontainer.json",
+	}
+	if r.ConfigPath != "" {
+		paths = []string{r.ConfigPath}
+	}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(r.WorkspaceFolder, p)
+		}
+		data, err := afero.ReadFile(r.Filesystem, p)
+		if err == nil {
+			return p, data, nil
+		}
+		if r.ConfigPath != "" {
+			return "", nil, xerrors.Errorf("read %s: %w", p, err)
+		}
+	}
+	return "", nil, xerrors.Errorf("no devcontainer.json found in %s", r.WorkspaceFolder)
+}
+
+// inspect returns whether the container exists and is running.
+func (r *Runner) inspect(ctx context.Context, name string) (running bool, exists bool) {
+	out, err := r.Command(ctx, "docker", "container", "inspect", "--format", "{{.State.Running}}", name).Output()
+	if err != nil {
+		return false, false
+	}
+	return strings.TrimSpace(string(out)) == "true", true
+}
+
+func (r *Runner) runCommand(ctx context.Context, run func(args ...string) error, name string, config Config, field string, command Command) error {
+	if len(command) == 0 {
+		return nil
+	}
+	r.Logger.Debug(ctx, "run dev container command", slog.F("field", field), slog.F("command", command))
+	args := []string{"exec", "--workdir", containerWorkspaceFolder(config, r.WorkspaceFolder)}
+	if config.RemoteUser != "" {
+		args = append(args, "--user", config.RemoteUser)
+	}
+	args = append(args, name)
+	args = append(args, command...)
+	err := run(args...)
+	if err != nil {
+		return xerrors.Errorf("run %s: %w", field, err)
+	}
+	return nil
+}
+
+// followEnvbuilder copies the logs of envbuilder until the dev container is
+// built.
+func (r *Runner) followEnvbuilder(ctx context.Context, name string, infoW io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cmd := r.Command(ctx, "docker", "logs", "--follow", name)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	// Envbuilder logs to stderr.
+	cmd.Stderr = cmd.Stdout
+	err = cmd.Start()
+	if err != nil {
+		return xerrors.Errorf("docker logs: %w", err)
+	}
+	defer func() {
+		cancel()
+		_ = cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		_, _ = fmt.Fprintln(infoW, line)
+		if strings.Contains(line, envbuilderReadyLog) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return xerrors.Errorf("read envbuilder logs: %w", err)
+	}
+	return xerrors.New("envbuilder exited before the dev container was built")
+}
+
+// containerName returns a name for the dev container that is stable across
+// restarts of the agent.
+func containerName(workspaceFolder string) string {
+	sum := sha256.Sum256([]byte(workspaceFolder))
+	base := strings.ToLower(filepath.Base(workspaceFolder))
+	base = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, base)
+	return fmt.Sprintf("coder-devcontainer-%s-%s", base, hex.EncodeToString(sum[:4]))
+}
+
+// containerWorkspaceFolder is the path of the workspace folder inside the
+// dev container.
+func containerWorkspaceFolder(config Config, workspaceFolder string) string {
+	if config.WorkspaceFolder != "" {
+		return config.WorkspaceFolder
+	}
+	return path.Join("/workspaces", filepath.Base(workspaceFolder))
+}
+
+func buildArgs(config Config, configDir, image string) []string {
+	dockerfile, buildContext := config.DockerFile, "."
+	args := []string{"build", "--tag", image}
+	if config.Build != nil {
+		if config.Build.Dockerfile != "" {
+			dockerfile = config.Build.Dockerfile
+		}
+		if config.Build.Context != "" {
+			buildContext = config.Build.Context
+		}
+		if config.Build.Target != "" {
+			args = append(args, "--target", config.Build.Target)
+		}
+		for _, key := range sortedKeys(config.Build.Args) {
+			args = append(args, "--build-arg", key+"="+config.Build.Args[key])
+		}
+	}
+	// Paths in devcontainer.json are relative to the file.
+	return append(args,
+		"--file", filepath.Join(configDir, dockerfile),
+		filepath.Join(configDir, buildContext),
+	)
+}
+
+func dockerRunArgs(config Config, ports []ForwardedPort, workspaceFolder, name, image string) []string {
+	folder := containerWorkspaceFolder(config, workspaceFolder)
+	args := []string{
+		"run", "--detach",
+		"--name", name,
+		"--label", "coder.devcontainer.folder=" + workspaceFolder,
+		"--volume", workspaceFolder + ":" + folder,
+		"--workdir", folder,
+	}
+	for _, key := range sortedKeys(config.ContainerEnv) {
+		args = append(args, "--env", key+"="+config.ContainerEnv[key])
+	}
+	for _, port := range ports {
+		// Ports are published on localhost, where the agent forwards them.
+		args = append(args, "--publish", fmt.Sprintf("127.0.0.1:%d:%d", port.Port, port.Port))
+	}
+	args = append(args, config.RunArgs...)
+	args = append(args, image)
+	if config.OverrideCommand == nil || *config.OverrideCommand {
+		// Keep the container running, like the dev containers CLI.
+		args = append(args, "/bin/sh", "-c", "trap 'exit 0' TERM; sleep infinity & wait")
+	}
+	return args
+}
+
+func envbuilderRunArgs(ports []ForwardedPort, workspaceFolder, configPath, name string) []string {
+	folder := path.Join("/workspaces", filepath.Base(workspaceFolder))
+	relConfigPath, err := filepath.Rel(workspaceFolder, configPath)
+	if err != nil {
+		relConfigPath = configPath
+	}
+	args := []string{
+		"run", "--detach",
+		"--name", name,
+		"--label", "coder.devcontainer.folder=" + workspaceFolder,
+		"--volume", workspaceFolder + ":" + folder,
+		"--env", "WORKSPACE_FOLDER=" + folder,
+		// The path of devcontainer.json is relative to its directory.
+		"--env", "DEVCONTAINER_DIR=" + filepath.ToSlash(filepath.Dir(relConfigPath)),
+		"--env", "DEVCONTAINER_JSON_PATH=" + filepath.Base(relConfigPath),
+		"--env", "INIT_SCRIPT=sleep infinity",
+	}
+	for _, port := range ports {
+		args = append(args, "--publish", fmt.Sprintf("127.0.0.1:%d:%d", port.Port, port.Port))
+	}
+	return append(args, EnvbuilderImage)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,48 @@
+package agentdevcontainer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerArgs(t *testing.T) {
+	t.Parallel()
+
+	config, err := Parse([]byte(`{
+	"build": {"dockerfile": "Dockerfile", "context": "..", "args": {"B": "2", "A": "1"}},
+	"containerEnv": {"EDITOR": "vim"},
+	"runArgs": ["--cap-add=SYS_PTRACE"]
+}`))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"build", "--tag", "image",
+		"--build-arg", "A=1",
+		"--build-arg", "B=2",
+		"--file", "/home/coder/project/.devcontainer/Dockerfile",
+		"/home/coder/project",
+	}, buildArgs(config, "/home/coder/project/.devcontainer", "image"))
+
+	require.Equal(t, []string{
+		"run", "--detach",
+		"--name", "container",
+		"--label", "coder.devcontainer.folder=/home/coder/project",
+		"--volume", "/home/coder/project:/workspaces/project",
+		"--workdir", "/workspaces/project",
+		"--env", "EDITOR=vim",
+		"--publish", "127.0.0.1:8080:8080",
+		"--cap-add=SYS_PTRACE",
+		"image",
+		"/bin/sh", "-c", "trap 'exit 0' TERM; sleep infinity & wait",
+	}, dockerRunArgs(config, []ForwardedPort{{Port: 8080}}, "/home/coder/project", "container", "image"))
+}
+
+func TestContainerName(t *testing.T) {
+	t.Parallel()
+
+	name := containerName("/home/coder/My Project")
+	require.Regexp(t, `^coder-devcontainer-my-project-[0-9a-f]{8}$`, name)
+	require.Equal(t, name, containerName("/home/coder/My Project"))
+	require.NotEqual(t, name, containerName("/home/other/My Project"))
+}
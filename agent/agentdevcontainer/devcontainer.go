@@ -0,0 +1,179 @@
+// Package agentdevcontainer builds and starts the dev container described by
+// the devcontainer.json of a repository in the workspace.
+package agentdevcontainer
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+)
+
+// Config is the subset of devcontainer.json supported by the agent. See
+// https://containers.dev/implementors/json_reference/.
+type Config struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	Build *struct {
+		Dockerfile string            `json:"dockerfile"`
+		Context    string            `json:"context"`
+		Args       map[string]string `json:"args"`
+		Target     string            `json:"target"`
+	} `json:"build"`
+	// DockerFile is the legacy location of build.dockerfile.
+	DockerFile        string                    `json:"dockerFile"`
+	ForwardPorts      []json.RawMessage         `json:"forwardPorts"`
+	PortsAttributes   map[string]PortAttributes `json:"portsAttributes"`
+	ContainerEnv      map[string]string         `json:"containerEnv"`
+	RunArgs           []string                  `json:"runArgs"`
+	WorkspaceFolder   string                    `json:"workspaceFolder"`
+	RemoteUser        string                    `json:"remoteUser"`
+	OverrideCommand   *bool                     `json:"overrideCommand"`
+	PostCreateCommand Command                   `json:"postCreateCommand"`
+	PostStartCommand  Command                   `json:"postStartCommand"`
+}
+
+type PortAttributes struct {
+	Label string `json:"label"`
+}
+
+// Command is a lifecycle command, which is either a string run by a shell or
+// an array of arguments run without a shell.
+type Command []string
+
+func (c *Command) UnmarshalJSON(data []byte) error {
+	var shell string
+	if err := json.Unmarshal(data, &shell); err == nil {
+		*c = nil
+		if shell != "" {
+			*c = Command{"/bin/sh", "-c", shell}
+		}
+		return nil
+	}
+	var args []string
+	if err := json.Unmarshal(data, &args); err != nil {
+		return xerrors.Errorf("command must be a string or an array of strings: %w", err)
+	}
+	*c = args
+	return nil
+}
+
+// ForwardedPort is a port of the dev container that is published in the
+// workspace.
+type ForwardedPort struct {
+	Port  uint16
+	Label string
+}
+
+// Parse parses a devcontainer.json file, which may contain comments and
+// trailing commas.
+func Parse(data []byte) (Config, error) {
+	var config Config
+	err := json.Unmarshal(standardizeJSON(data), &config)
+	if err != nil {
+		return Config{}, xerrors.Errorf("parse devcontainer.json: %w", err)
+	}
+	if config.Image == "" && config.Build == nil && config.DockerFile == "" {
+		return Config{}, xerrors.New("devcontainer.json must set image or build.dockerfile")
+	}
+	return config, nil
+}
+
+// ForwardedPorts returns the ports forwarded from the dev container. Ports on
+// other hosts, e.g. "db:5432", can't be published and are skipped.
+func (c Config) ForwardedPorts() ([]ForwardedPort, error) {
+	ports := make([]ForwardedPort, 0, len(c.ForwardPorts))
+	for _, raw := range c.ForwardPorts {
+		var value interface{}
+		err := json.Unmarshal(raw, &value)
+		if err != nil {
+			return nil, xerrors.Errorf("parse forwarded port: %w", err)
+		}
+		var portString string
+		switch value := value.(type) {
+		case float64:
+			portString = strconv.FormatFloat(value, 'f', -1, 64)
+		case string:
+			host, port, ok := strings.Cut(value, ":")
+			if !ok {
+				port = host
+			} else if host != "localhost" && host != "127.0.0.1" {
+				continue
+			}
+			portString = port
+		default:
+			return nil, xerrors.Errorf("invalid forwarded port %s", raw)
+		}
+		port, err := strconv.ParseUint(portString, 10, 16)
+		if err != nil || port == 0 {
+			return nil, xerrors.Errorf("invalid forwarded port %s", raw)
+		}
+		ports = append(ports, ForwardedPort{
+			Port:  uint16(port),
+			Label: c.PortsAttributes[portString].Label,
+		})
+	}
+	slices.SortFunc(ports, func(a, b ForwardedPort) int {
+		return int(a.Port) - int(b.Port)
+	})
+	return slices.CompactFunc(ports, func(a, b ForwardedPort) bool {
+		return a.Port == b.Port
+	}), nil
+}
+
+// standardizeJSON removes the comments and trailing commas allowed in
+// devcontainer.json, which is JSON with comments.
+func standardizeJSON(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	// comma is the index in out of a comma that may be trailing.
+	comma := -1
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(data) {
+				i = len(data) - 1
+			}
+			out = append(out, data[start:i+1]...)
+			comma = -1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := strings.Index(string(data[i+2:]), "*/")
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+			out = append(out, ' ')
+		case c == ',':
+			comma = len(out)
+			out = append(out, c)
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				out[comma] = ' '
+			}
+			comma = -1
+			out = append(out, c)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			out = append(out, c)
+		default:
+			comma = -1
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package agentdevcontainer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agentdevcontainer"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := agentdevcontainer.Parse([]byte(`{
+	// Comments and trailing commas are allowed.
+	"name": "Go // not a comment",
+	"build": {
+		"dockerfile": "Dockerfile", /* The image. */
+		"args": {"VARIANT": "1.21",},
+	},
+	"forwardPorts": [8080, "localhost:3000", "db:5432", 8080],
+	"portsAttributes": {
+		"8080": {"label": "Web"},
+	},
+	"postCreateCommand": "go mod download",
+	"postStartCommand": ["make", "dev"],
+}`))
+		require.NoError(t, err)
+		require.Equal(t, "Go // not a comment", config.Name)
+		require.Equal(t, "Dockerfile", config.Build.Dockerfile)
+		require.Equal(t, map[string]string{"VARIANT": "1.21"}, config.Build.Args)
+		require.Equal(t, agentdevcontainer.Command{"/bin/sh", "-c", "go mod download"}, config.PostCreateCommand)
+		require.Equal(t, agentdevcontainer.Command{"make", "dev"}, config.PostStartCommand)
+
+		ports, err := config.ForwardedPorts()
+		require.NoError(t, err)
+		require.Equal(t, []agentdevcontainer.ForwardedPort{
+			{Port: 3000},
+			{Port: 8080, Label: "Web"},
+		}, ports)
+	})
+
+	t.Run("NoImage", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := agentdevcontainer.Parse([]byte(`{"name": "empty"}`))
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidPort", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := agentdevcontainer.Parse([]byte(`{"image": "ubuntu", "forwardPorts": [70000]}`))
+		require.NoError(t, err)
+		_, err = config.ForwardedPorts()
+		require.Error(t, err)
+	})
+}
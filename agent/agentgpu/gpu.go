@@ -0,0 +1,164 @@
+// Package agentgpu detects the GPUs available in the workspace of an agent.
+package agentgpu
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	VendorNVIDIA = "nvidia"
+	VendorAMD    = "amd"
+
+	// nvidiaSMITimeout bounds nvidia-smi, which can hang when the driver is
+	// in a bad state.
+	nvidiaSMITimeout = 10 * time.Second
+	// amdPCIVendorID is the PCI vendor ID of AMD (ATI) GPUs.
+	amdPCIVendorID = "0x1002"
+	drmClassDir    = "/sys/class/drm"
+	amdgpuVersion  = "/sys/module/amdgpu/version"
+)
+
+// Detect returns the NVIDIA and AMD GPUs in the workspace. Detection is best
+// effort: failures are logged, and no GPUs are returned for the vendor.
+func Detect(ctx context.Context, logger slog.Logger, fs afero.Fs) []codersdk.WorkspaceAgentGPU {
+	gpus := make([]codersdk.WorkspaceAgentGPU, 0)
+
+	nvidia, err := detectNVIDIA(ctx)
+	if err != nil {
+		logger.Debug(ctx, "detect nvidia gpus", slog.Error(err))
+	}
+	gpus = append(gpus, nvidia...)
+
+	amd, err := detectAMD(fs)
+	if err != nil {
+		logger.Debug(ctx, "detect amd gpus", slog.Error(err))
+	}
+	gpus = append(gpus, amd...)
+
+	return gpus
+}
+
+func detectNVIDIA(ctx context.Context) ([]codersdk.WorkspaceAgentGPU, error) {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		// The driver isn't installed, so there are no usable GPUs.
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, nvidiaSMITimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	//nolint:gosec // The path is found on $PATH and the arguments are constant.
+	cmd := exec.CommandContext(ctx, path,
+		"--query-gpu=index,name,memory.total,driver_version",
+		"--format=csv,noheader,nounits",
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return nil, xerrors.Errorf("run nvidia-smi: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseNVIDIASMI(stdout.Bytes())
+}
+
+// parseNVIDIASMI parses the CSV output of nvidia-smi, which reports memory in
+// MiB.
+func parseNVIDIASMI(out []byte) ([]codersdk.WorkspaceAgentGPU, error) {
+	reader := csv.NewReader(bytes.NewReader(out))
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, xerrors.Errorf("parse nvidia-smi output: %w", err)
+	}
+
+	gpus := make([]codersdk.WorkspaceAgentGPU, 0, len(records))
+	for _, record := range records {
+		index, err := strconv.ParseInt(strings.TrimSpace(record[0]), 10, 32)
+		if err != nil {
+			return nil, xerrors.Errorf("parse index %q: %w", record[0], err)
+		}
+		gpu := codersdk.WorkspaceAgentGPU{
+			Index:         int32(index),
+			Vendor:        VendorNVIDIA,
+			Model:         strings.TrimSpace(record[1]),
+			DriverVersion: strings.TrimSpace(record[3]),
+		}
+		// Memory is "[N/A]" when it can't be queried, e.g. for some vGPUs.
+		memoryMiB, err := strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64)
+		if err == nil {
+			gpu.MemoryBytes = memoryMiB << 20
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
+
+// detectAMD finds AMD GPUs driven by amdgpu from sysfs. Cards are indexed in
+// the order of their DRM minor number, like rocm-smi.
+func detectAMD(fs afero.Fs) ([]codersdk.WorkspaceAgentGPU, error) {
+	cards, err := afero.Glob(fs, filepath.Join(drmClassDir, "card*"))
+	if err != nil {
+		return nil, xerrors.Errorf("list drm cards: %w", err)
+	}
+
+	driverVersion := readSysfs(fs, amdgpuVersion)
+	gpus := make([]codersdk.WorkspaceAgentGPU, 0)
+	for _, card := range cards {
+		// Connectors, e.g. card0-DP-1, are listed next to the cards.
+		minor, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(card), "card"))
+		if err != nil {
+			continue
+		}
+		device := filepath.Join(card, "device")
+		if readSysfs(fs, filepath.Join(device, "vendor")) != amdPCIVendorID {
+			continue
+		}
+		gpu := codersdk.WorkspaceAgentGPU{
+			Index:         int32(minor),
+			Vendor:        VendorAMD,
+			Model:         readSysfs(fs, filepath.Join(device, "product_name")),
+			DriverVersion: driverVersion,
+		}
+		if gpu.Model == "" {
+			// product_name is only available on some GPUs.
+			gpu.Model = "AMD GPU " + readSysfs(fs, filepath.Join(device, "device"))
+		}
+		memoryBytes, err := strconv.ParseInt(readSysfs(fs, filepath.Join(device, "mem_info_vram_total")), 10, 64)
+		if err == nil {
+			gpu.MemoryBytes = memoryBytes
+		}
+		gpus = append(gpus, gpu)
+	}
+	// Glob sorts lexically, so card10 would come before card2.
+	slices.SortFunc(gpus, func(a, b codersdk.WorkspaceAgentGPU) int {
+		return slice.Ascending(a.Index, b.Index)
+	})
+	return gpus, nil
+}
+
+// readSysfs returns the trimmed content of a sysfs attribute, or an empty
+// string if it can't be read.
+func readSysfs(fs afero.Fs, path string) string {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
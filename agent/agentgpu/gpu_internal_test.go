@@ -0,0 +1,78 @@
+package agentgpu
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestParseNVIDIASMI(t *testing.T) {
+	t.Parallel()
+
+	gpus, err := parseNVIDIASMI([]byte("0, NVIDIA A100-SXM4-40GB, 40960, 535.104.05\n1, NVIDIA GRID T4-1B, [N/A], 535.104.05\n"))
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceAgentGPU{{
+		Index:         0,
+		Vendor:        VendorNVIDIA,
+		Model:         "NVIDIA A100-SXM4-40GB",
+		MemoryBytes:   40960 << 20,
+		DriverVersion: "535.104.05",
+	}, {
+		Index:         1,
+		Vendor:        VendorNVIDIA,
+		Model:         "NVIDIA GRID T4-1B",
+		DriverVersion: "535.104.05",
+	}}, gpus)
+
+	gpus, err = parseNVIDIASMI(nil)
+	require.NoError(t, err)
+	require.Empty(t, gpus)
+
+	_, err = parseNVIDIASMI([]byte("not csv"))
+	require.Error(t, err)
+}
+
+func TestDetectAMD(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	writeFile := func(path, content string) {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content+"\n"), 0o644))
+	}
+	writeFile(amdgpuVersion, "6.2.4")
+	// An AMD GPU with a product name.
+	writeFile("/sys/class/drm/card10/device/vendor", amdPCIVendorID)
+	writeFile("/sys/class/drm/card10/device/device", "0x740f")
+	writeFile("/sys/class/drm/card10/device/product_name", "AMD Instinct MI210")
+	writeFile("/sys/class/drm/card10/device/mem_info_vram_total", "68702699520")
+	// An AMD GPU without a product name.
+	writeFile("/sys/class/drm/card2/device/vendor", amdPCIVendorID)
+	writeFile("/sys/class/drm/card2/device/device", "0x73bf")
+	writeFile("/sys/class/drm/card2/device/mem_info_vram_total", "17163091968")
+	// A connector and an Intel GPU are ignored.
+	writeFile("/sys/class/drm/card2-DP-1/status", "connected")
+	writeFile("/sys/class/drm/card0/device/vendor", "0x8086")
+
+	gpus, err := detectAMD(fs)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceAgentGPU{{
+		Index:         2,
+		Vendor:        VendorAMD,
+		Model:         "AMD GPU 0x73bf",
+		MemoryBytes:   17163091968,
+		DriverVersion: "6.2.4",
+	}, {
+		Index:         10,
+		Vendor:        VendorAMD,
+		Model:         "AMD Instinct MI210",
+		MemoryBytes:   68702699520,
+		DriverVersion: "6.2.4",
+	}}, gpus)
+
+	gpus, err = detectAMD(afero.NewMemMapFs())
+	require.NoError(t, err)
+	require.Empty(t, gpus)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/afero"
+	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
@@ -147,7 +148,9 @@ func (r *Runner) StartCron() {
 	}
 }
 
-// Execute runs a set of scripts according to a filter.
+// Execute runs a set of scripts according to a filter. Scripts run phase by
+// phase, and the scripts of a phase in parallel. A failing phase doesn't stop
+// the following phases, so stop scripts still run if a pre-stop hook fails.
 func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.WorkspaceAgentScript) bool) error {
 	if filter == nil {
 		// Execute em' all!
@@ -155,21 +158,59 @@ func (r *Runner) Execute(ctx context.Context, filter func(script codersdk.Worksp
 			return true
 		}
 	}
-	var eg errgroup.Group
+	phases := make([][]codersdk.WorkspaceAgentScript, len(phaseOrder))
 	for _, script := range r.scripts {
 		if !filter(script) {
 			continue
 		}
-		script := script
-		eg.Go(func() error {
-			err := r.trackRun(ctx, script)
-			if err != nil {
-				return xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err)
-			}
-			return nil
-		})
+		phase := phaseIndex(script)
+		phases[phase] = append(phases[phase], script)
+	}
+
+	var errs []error
+	for _, scripts := range phases {
+		var eg errgroup.Group
+		for _, script := range scripts {
+			script := script
+			eg.Go(func() error {
+				err := r.trackRun(ctx, script)
+				if err != nil {
+					return xerrors.Errorf("run agent script %q: %w", script.LogSourceID, err)
+				}
+				return nil
+			})
+		}
+		err := eg.Wait()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// phaseOrder is the order in which the phases of the start and stop
+// lifecycles run.
+var phaseOrder = []codersdk.WorkspaceAgentScriptPhase{
+	codersdk.WorkspaceAgentScriptPhasePreStart,
+	codersdk.WorkspaceAgentScriptPhaseStart,
+	codersdk.WorkspaceAgentScriptPhasePostStart,
+	codersdk.WorkspaceAgentScriptPhasePreStop,
+	codersdk.WorkspaceAgentScriptPhaseStop,
+}
+
+// phaseIndex returns the index in phaseOrder of the phase of the script.
+// Older versions of coderd don't send a phase, so it's inferred from when
+// the script runs. Unknown phases run with the start scripts.
+func phaseIndex(script codersdk.WorkspaceAgentScript) int {
+	phase := script.Phase
+	if phase == "" && script.RunOnStop && !script.RunOnStart {
+		phase = codersdk.WorkspaceAgentScriptPhaseStop
+	}
+	index := slices.Index(phaseOrder, phase)
+	if index < 0 {
+		return slices.Index(phaseOrder, codersdk.WorkspaceAgentScriptPhaseStart)
 	}
-	return eg.Wait()
+	return index
 }
 
 // trackRun wraps "run" with metrics.
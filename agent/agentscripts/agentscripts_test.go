@@ -41,6 +41,42 @@ func TestExecuteBasic(t *testing.T) {
 	require.Equal(t, "hello", log.Logs[0].Output)
 }
 
+func TestExecutePhases(t *testing.T) {
+	t.Parallel()
+	logs := make(chan agentsdk.PatchLogs, 3)
+	runner := setup(t, func(ctx context.Context, req agentsdk.PatchLogs) error {
+		logs <- req
+		return nil
+	})
+	defer runner.Close()
+	err := runner.Init([]codersdk.WorkspaceAgentScript{{
+		Script:     "echo post_start",
+		RunOnStart: true,
+		Phase:      codersdk.WorkspaceAgentScriptPhasePostStart,
+	}, {
+		Script:     "echo start",
+		RunOnStart: true,
+		Phase:      codersdk.WorkspaceAgentScriptPhaseStart,
+	}, {
+		Script:     "echo pre_start",
+		RunOnStart: true,
+		Phase:      codersdk.WorkspaceAgentScriptPhasePreStart,
+	}, {
+		Script:    "echo stop",
+		RunOnStop: true,
+		Phase:     codersdk.WorkspaceAgentScriptPhaseStop,
+	}})
+	require.NoError(t, err)
+	require.NoError(t, runner.Execute(context.Background(), func(script codersdk.WorkspaceAgentScript) bool {
+		return script.RunOnStart
+	}))
+	for _, want := range []string{"pre_start", "start", "post_start"} {
+		log := <-logs
+		require.Equal(t, want, log.Logs[0].Output)
+	}
+	require.Empty(t, logs)
+}
+
 func TestTimeout(t *testing.T) {
 	t.Parallel()
 	runner := setup(t, nil)
@@ -74,6 +74,9 @@ type Server struct {
 	AgentToken    func() string
 	Manifest      *atomic.Pointer[agentsdk.Manifest]
 	ServiceBanner *atomic.Pointer[codersdk.ServiceBannerConfig]
+	// MaxFileSize is the max size in bytes of files transferred with SFTP
+	// or SCP. Zero is unlimited.
+	MaxFileSize int64
 
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
@@ -242,6 +245,15 @@ func (s *Server) sessionHandler(session ssh.Session) {
 		return
 	}
 
+	// Legacy SCP runs scp on the remote host, which may not be installed in
+	// the workspace, so it's handled by the agent.
+	if _, _, isPty := session.Pty(); !isPty {
+		if cmd, ok := parseSCPCommand(session.RawCommand()); ok {
+			s.scpHandler(logger, session, cmd)
+			return
+		}
+	}
+
 	err := s.sessionStart(logger, session, extraEnv)
 	var exitError *exec.ExitError
 	if xerrors.As(err, &exitError) {
@@ -539,27 +551,34 @@ func (s *Server) sftpHandler(logger slog.Logger, session ssh.Session) {
 	// `RequestTTY force` in their SSH config.
 	session.DisablePTYEmulation()
 
-	var opts []sftp.ServerOption
+	var opts []sftp.RequestServerOption
 	// Change current working directory to the users home
 	// directory so that SFTP connections land there.
 	homedir, err := userHomeDir()
 	if err != nil {
 		logger.Warn(ctx, "get sftp working directory failed, unable to get home dir", slog.Error(err))
 	} else {
-		opts = append(opts, sftp.WithServerWorkingDirectory(homedir))
+		opts = append(opts, sftp.WithStartDirectory(sftpRemotePath(homedir)))
 	}
 
-	server, err := sftp.NewServer(session, opts...)
-	if err != nil {
-		logger.Debug(ctx, "initialize sftp server", slog.Error(err))
-		return
+	handlers := &sftpHandlers{
+		ctx:         ctx,
+		logger:      logger.With(slog.F("protocol", "sftp")),
+		fs:          s.fs,
+		maxFileSize: s.MaxFileSize,
 	}
+	server := sftp.NewRequestServer(session, sftp.Handlers{
+		FileGet:  handlers,
+		FilePut:  handlers,
+		FileCmd:  handlers,
+		FileList: handlers,
+	}, opts...)
 	defer server.Close()
 
 	err = server.Serve()
-	if errors.Is(err, io.EOF) {
+	if err == nil || errors.Is(err, io.EOF) {
 		// Unless we call `session.Exit(0)` here, the client won't
-		// receive `exit-status` because `(*sftp.Server).Close()`
+		// receive `exit-status` because `(*sftp.RequestServer).Close()`
 		// calls `Close()` on the underlying connection (session),
 		// which actually calls `channel.Close()` because it isn't
 		// wrapped. This causes sftp clients to receive a non-zero
@@ -7,12 +7,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"runtime"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/bramvdbogaerde/go-scp"
+	"github.com/pkg/sftp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
@@ -316,6 +320,100 @@ func TestNewServer_Signal(t *testing.T) {
 	})
 }
 
+func TestNewServer_FileTransfer(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("the in-memory filesystem doesn't use Windows paths")
+	}
+
+	ctx := context.Background()
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	fs := afero.NewMemMapFs()
+	s, err := agentssh.NewServer(ctx, logger, prometheus.NewRegistry(), fs, 0, "")
+	require.NoError(t, err)
+	defer s.Close()
+
+	s.AgentToken = func() string { return "" }
+	s.Manifest = atomic.NewPointer(&agentsdk.Manifest{})
+	s.MaxFileSize = 10
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := s.Serve(ln)
+		assert.Error(t, err) // Server is closed.
+	}()
+
+	c := sshClient(t, ln.Addr().String())
+
+	t.Run("SFTP", func(t *testing.T) {
+		client, err := sftp.NewClient(c)
+		require.NoError(t, err)
+		defer client.Close()
+
+		f, err := client.Create("/sftp")
+		require.NoError(t, err)
+		_, err = f.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		content, err := afero.ReadFile(fs, "/sftp")
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(content))
+
+		f, err = client.Open("/sftp")
+		require.NoError(t, err)
+		content, err = io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.Equal(t, "hello", string(content))
+
+		// Files larger than the max file size can't be written.
+		f, err = client.Create("/sftp-large")
+		require.NoError(t, err)
+		_, err = f.Write([]byte("hello world"))
+		closeErr := f.Close()
+		require.True(t, err != nil || closeErr != nil, "write should fail")
+	})
+
+	t.Run("SCP", func(t *testing.T) {
+		client, err := scp.NewClientBySSH(c)
+		require.NoError(t, err)
+		defer client.Close()
+
+		err = client.CopyFile(ctx, strings.NewReader("hello"), "/scp", "0644")
+		require.NoError(t, err)
+		content, err := afero.ReadFile(fs, "/scp")
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(content))
+
+		client, err = scp.NewClientBySSH(c)
+		require.NoError(t, err)
+		defer client.Close()
+
+		var b bytes.Buffer
+		err = client.CopyFromRemotePassThru(ctx, &b, "/scp", nil)
+		require.NoError(t, err)
+		require.Equal(t, "hello", b.String())
+
+		client, err = scp.NewClientBySSH(c)
+		require.NoError(t, err)
+		defer client.Close()
+
+		// Files larger than the max file size are rejected.
+		err = client.CopyFile(ctx, strings.NewReader("hello world"), "/scp-large", "0644")
+		require.Error(t, err)
+		_, err = fs.Stat("/scp-large")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	err = s.Close()
+	require.NoError(t, err)
+	<-done
+}
+
 func sshClient(t *testing.T, addr string) *ssh.Client {
 	conn, err := net.Dial("tcp", addr)
 	require.NoError(t, err)
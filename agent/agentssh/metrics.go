@@ -8,6 +8,8 @@ import (
 
 type sshServerMetrics struct {
 	failedConnectionsTotal prometheus.Counter
+	scpConnectionsTotal    prometheus.Counter
+	scpErrors              prometheus.Counter
 	sftpConnectionsTotal   prometheus.Counter
 	sftpServerErrors       prometheus.Counter
 	x11HandlerErrors       *prometheus.CounterVec
@@ -21,6 +23,16 @@ func newSSHServerMetrics(registerer prometheus.Registerer) *sshServerMetrics {
 	})
 	registerer.MustRegister(failedConnectionsTotal)
 
+	scpConnectionsTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agent", Subsystem: "ssh_server", Name: "scp_connections_total",
+	})
+	registerer.MustRegister(scpConnectionsTotal)
+
+	scpErrors := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agent", Subsystem: "ssh_server", Name: "scp_errors_total",
+	})
+	registerer.MustRegister(scpErrors)
+
 	sftpConnectionsTotal := prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "agent", Subsystem: "ssh_server", Name: "sftp_connections_total",
 	})
@@ -63,6 +75,8 @@ func newSSHServerMetrics(registerer prometheus.Registerer) *sshServerMetrics {
 
 	return &sshServerMetrics{
 		failedConnectionsTotal: failedConnectionsTotal,
+		scpConnectionsTotal:    scpConnectionsTotal,
+		scpErrors:              scpErrors,
 		sftpConnectionsTotal:   sftpConnectionsTotal,
 		sftpServerErrors:       sftpServerErrors,
 		x11HandlerErrors:       x11HandlerErrors,
@@ -0,0 +1,523 @@
+package agentssh
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// scpCommand is a legacy SCP command, which the scp client runs on the remote
+// host when it doesn't use the SFTP protocol. The agent handles it itself, so
+// scp works in workspaces without an scp binary.
+type scpCommand struct {
+	// sink is set by -t, when the client sends files.
+	sink bool
+	// source is set by -f, when the client receives files.
+	source    bool
+	recursive bool
+	preserve  bool
+	targetDir bool
+	paths     []string
+}
+
+// parseSCPCommand returns the SCP command run by an SSH session, if any.
+// Commands with options the agent doesn't understand are run as usual.
+func parseSCPCommand(rawCommand string) (scpCommand, bool) {
+	args, err := shellquote.Split(rawCommand)
+	if err != nil || len(args) < 2 || path.Base(args[0]) != "scp" {
+		return scpCommand{}, false
+	}
+
+	var cmd scpCommand
+	i := 1
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			break
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 't':
+				cmd.sink = true
+			case 'f':
+				cmd.source = true
+			case 'r':
+				cmd.recursive = true
+			case 'p':
+				cmd.preserve = true
+			case 'd':
+				cmd.targetDir = true
+			case 'v', 'q':
+				// Verbosity doesn't change the protocol.
+			default:
+				return scpCommand{}, false
+			}
+		}
+	}
+	cmd.paths = args[i:]
+
+	if cmd.sink == cmd.source || len(cmd.paths) == 0 || (cmd.sink && len(cmd.paths) != 1) {
+		return scpCommand{}, false
+	}
+	return cmd, true
+}
+
+func (s *Server) scpHandler(logger slog.Logger, session ssh.Session, cmd scpCommand) {
+	s.metrics.scpConnectionsTotal.Add(1)
+
+	ctx := session.Context()
+	t := &scpTransfer{
+		ctx:         ctx,
+		logger:      logger,
+		fs:          s.fs,
+		maxFileSize: s.MaxFileSize,
+		cmd:         cmd,
+		in:          bufio.NewReader(session),
+		out:         session,
+	}
+	var err error
+	if cmd.sink {
+		err = t.receive()
+	} else {
+		err = t.send()
+	}
+	if err != nil {
+		logger.Warn(ctx, "scp transfer failed", slog.Error(err))
+		s.metrics.scpErrors.Add(1)
+		_ = session.Exit(1)
+		return
+	}
+	if t.failed {
+		_ = session.Exit(1)
+		return
+	}
+	_ = session.Exit(0)
+}
+
+// scpTransfer implements both sides of the SCP protocol. Errors with a single
+// file are reported to the client as warnings, and the transfer continues
+// with the next file.
+type scpTransfer struct {
+	ctx         context.Context
+	logger      slog.Logger
+	fs          afero.Fs
+	maxFileSize int64
+	cmd         scpCommand
+	in          *bufio.Reader
+	out         io.Writer
+
+	failed bool
+}
+
+// receive writes the files sent by the client to the target of the command.
+func (t *scpTransfer) receive() error {
+	target, err := t.resolvePath(t.cmd.paths[0])
+	if err != nil {
+		return t.fatal(err)
+	}
+	info, err := t.fs.Stat(target)
+	targetIsDir := err == nil && info.IsDir()
+	if t.cmd.targetDir && !targetIsDir {
+		return t.fatal(xerrors.Errorf("%s: not a directory", target))
+	}
+	err = t.ack()
+	if err != nil {
+		return err
+	}
+	return t.receiveDir(target, targetIsDir, 0)
+}
+
+// receiveDir reads records until the end of a directory, or of the input at
+// the top level. Files are created in target if it is a directory, or at
+// target otherwise.
+func (t *scpTransfer) receiveDir(target string, targetIsDir bool, depth int) error {
+	var times *scpTimes
+	for {
+		line, err := t.in.ReadString('\n')
+		if xerrors.Is(err, io.EOF) && line == "" && depth == 0 {
+			return nil
+		}
+		if err != nil {
+			return xerrors.Errorf("read record: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return t.fatal(xerrors.New("empty record"))
+		}
+
+		switch line[0] {
+		case '\x01', '\x02':
+			return xerrors.Errorf("client error: %s", line[1:])
+		case 'E':
+			if depth == 0 {
+				return t.fatal(xerrors.New("unexpected end of directory"))
+			}
+			return t.ack()
+		case 'T':
+			times, err = parseSCPTimes(line[1:])
+			if err != nil {
+				return t.fatal(err)
+			}
+			err = t.ack()
+			if err != nil {
+				return err
+			}
+			continue
+		case 'C', 'D':
+		default:
+			return t.fatal(xerrors.Errorf("unknown record %q", line))
+		}
+
+		mode, size, name, err := parseSCPEntry(line[1:])
+		if err != nil {
+			return t.fatal(err)
+		}
+		path := target
+		if targetIsDir {
+			path = filepath.Join(target, name)
+		}
+		if line[0] == 'D' {
+			err = t.receiveSubdir(path, mode, times, depth)
+		} else {
+			err = t.receiveFile(path, mode, size, times)
+		}
+		if err != nil {
+			return err
+		}
+		times = nil
+	}
+}
+
+func (t *scpTransfer) receiveSubdir(path string, mode os.FileMode, times *scpTimes, depth int) error {
+	if !t.cmd.recursive {
+		return t.fatal(xerrors.New("received directory without -r"))
+	}
+	info, err := t.fs.Stat(path)
+	switch {
+	case err == nil && !info.IsDir():
+		return t.fatal(xerrors.Errorf("%s: not a directory", path))
+	case err != nil:
+		err = t.fs.Mkdir(path, mode|0o700)
+		if err != nil {
+			return t.fatal(err)
+		}
+	}
+	err = t.ack()
+	if err != nil {
+		return err
+	}
+	err = t.receiveDir(path, true, depth+1)
+	if err != nil {
+		return err
+	}
+	if t.cmd.preserve {
+		t.setAttributes(path, mode, times)
+	}
+	return nil
+}
+
+func (t *scpTransfer) receiveFile(path string, mode os.FileMode, size int64, times *scpTimes) error {
+	err := checkFileSize(size, t.maxFileSize)
+	if err != nil {
+		// The client skips the content of the file after a warning.
+		return t.warn(xerrors.Errorf("%s: %w", path, err))
+	}
+	f, err := t.fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return t.warn(err)
+	}
+	err = t.ack()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	written, err := io.CopyN(f, t.in, size)
+	closeErr := f.Close()
+	t.logTransfer(path, 0, written, err)
+	if err != nil {
+		return xerrors.Errorf("copy content: %w", err)
+	}
+	// The client ends the content of the file with a status.
+	err = t.readStatus()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return t.warn(closeErr)
+	}
+	if t.cmd.preserve {
+		t.setAttributes(path, mode, times)
+	}
+	return t.ack()
+}
+
+func (t *scpTransfer) setAttributes(path string, mode os.FileMode, times *scpTimes) {
+	err := t.fs.Chmod(path, mode)
+	if err != nil {
+		t.logger.Debug(t.ctx, "scp chmod", slog.F("path", path), slog.Error(err))
+	}
+	if times == nil {
+		return
+	}
+	err = t.fs.Chtimes(path, times.atime, times.mtime)
+	if err != nil {
+		t.logger.Debug(t.ctx, "scp chtimes", slog.F("path", path), slog.Error(err))
+	}
+}
+
+// send sends the paths of the command to the client.
+func (t *scpTransfer) send() error {
+	// The client starts the transfer once it's ready.
+	err := t.readStatus()
+	if err != nil {
+		return err
+	}
+	for _, p := range t.cmd.paths {
+		path, err := t.resolvePath(p)
+		if err != nil {
+			err = t.warn(err)
+		} else {
+			err = t.sendPath(path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *scpTransfer) sendPath(path string) error {
+	info, err := t.fs.Stat(path)
+	if err != nil {
+		return t.warn(err)
+	}
+	if info.IsDir() {
+		if !t.cmd.recursive {
+			return t.warn(xerrors.Errorf("%s: not a regular file", path))
+		}
+		return t.sendDir(path, info)
+	}
+	return t.sendFile(path, info)
+}
+
+func (t *scpTransfer) sendDir(path string, info os.FileInfo) error {
+	infos, err := afero.ReadDir(t.fs, path)
+	if err != nil {
+		return t.warn(err)
+	}
+	err = t.sendTimes(info)
+	if err != nil {
+		return err
+	}
+	err = t.sendRecord("D%04o 0 %s\n", info.Mode().Perm(), info.Name())
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		err = t.sendPath(filepath.Join(path, info.Name()))
+		if err != nil {
+			return err
+		}
+	}
+	return t.sendRecord("E\n")
+}
+
+func (t *scpTransfer) sendFile(path string, info os.FileInfo) error {
+	err := checkFileSize(info.Size(), t.maxFileSize)
+	if err != nil {
+		return t.warn(xerrors.Errorf("%s: %w", path, err))
+	}
+	f, err := t.fs.Open(path)
+	if err != nil {
+		return t.warn(err)
+	}
+	defer f.Close()
+
+	err = t.sendTimes(info)
+	if err != nil {
+		return err
+	}
+	err = t.sendRecord("C%04o %d %s\n", info.Mode().Perm(), info.Size(), info.Name())
+	if err != nil {
+		return err
+	}
+	read, err := io.CopyN(t.out, f, info.Size())
+	t.logTransfer(path, read, 0, err)
+	if err != nil {
+		return xerrors.Errorf("copy content: %w", err)
+	}
+	err = t.ack()
+	if err != nil {
+		return err
+	}
+	return t.readStatus()
+}
+
+func (t *scpTransfer) sendTimes(info os.FileInfo) error {
+	if !t.cmd.preserve {
+		return nil
+	}
+	// afero doesn't expose the access time, so the modification time is
+	// sent for both.
+	mtime := info.ModTime().Unix()
+	return t.sendRecord("T%d 0 %d 0\n", mtime, mtime)
+}
+
+// sendRecord sends a record to the client and waits for its status. A
+// warning from the client is not returned.
+func (t *scpTransfer) sendRecord(format string, args ...interface{}) error {
+	_, err := fmt.Fprintf(t.out, format, args...)
+	if err != nil {
+		return xerrors.Errorf("write record: %w", err)
+	}
+	return t.readStatus()
+}
+
+// readStatus reads a status byte from the client. Warnings are logged and
+// fail the session, but don't stop the transfer.
+func (t *scpTransfer) readStatus() error {
+	status, err := t.in.ReadByte()
+	if err != nil {
+		return xerrors.Errorf("read status: %w", err)
+	}
+	switch status {
+	case 0:
+		return nil
+	case 1, 2:
+		message, _ := t.in.ReadString('\n')
+		message = strings.TrimSuffix(message, "\n")
+		if status == 2 {
+			return xerrors.Errorf("client error: %s", message)
+		}
+		t.logger.Warn(t.ctx, "scp client warning", slog.F("message", message))
+		t.failed = true
+		return nil
+	default:
+		return xerrors.Errorf("unknown status %d", status)
+	}
+}
+
+func (t *scpTransfer) ack() error {
+	_, err := t.out.Write([]byte{0})
+	if err != nil {
+		return xerrors.Errorf("write status: %w", err)
+	}
+	return nil
+}
+
+// warn reports an error to the client without stopping the transfer.
+func (t *scpTransfer) warn(err error) error {
+	t.logger.Warn(t.ctx, "scp transfer error", slog.Error(err))
+	t.failed = true
+	_, writeErr := fmt.Fprintf(t.out, "\x01scp: %s\n", scpMessage(err))
+	if writeErr != nil {
+		return xerrors.Errorf("write status: %w", writeErr)
+	}
+	return nil
+}
+
+// fatal reports an error to the client and returns it, which stops the
+// transfer.
+func (t *scpTransfer) fatal(err error) error {
+	_, _ = fmt.Fprintf(t.out, "\x02scp: %s\n", scpMessage(err))
+	return err
+}
+
+func (t *scpTransfer) logTransfer(path string, read, written int64, err error) {
+	t.logger.Info(t.ctx, "file transfer",
+		slog.F("protocol", "scp"),
+		slog.F("path", path),
+		slog.F("bytes_read", read),
+		slog.F("bytes_written", written),
+		slog.Error(err),
+	)
+}
+
+// resolvePath returns the absolute path of a path in an SCP command. The
+// command isn't run by a shell, so "~" is expanded here.
+func (*scpTransfer) resolvePath(p string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		p = strings.TrimLeft(p[1:], "/")
+	}
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p), nil
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", xerrors.Errorf("get home dir: %w", err)
+	}
+	return filepath.Join(home, p), nil
+}
+
+// scpMessage returns the message of an error on a single line, as required
+// by the protocol.
+func scpMessage(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", " ")
+}
+
+type scpTimes struct {
+	mtime time.Time
+	atime time.Time
+}
+
+// parseSCPTimes parses a "T<mtime> 0 <atime> 0" record.
+func parseSCPTimes(record string) (*scpTimes, error) {
+	fields := strings.Fields(record)
+	if len(fields) != 4 {
+		return nil, xerrors.Errorf("invalid times record %q", record)
+	}
+	mtime, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid mtime: %w", err)
+	}
+	atime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid atime: %w", err)
+	}
+	return &scpTimes{
+		mtime: time.Unix(mtime, 0),
+		atime: time.Unix(atime, 0),
+	}, nil
+}
+
+// parseSCPEntry parses the "<mode> <size> <name>" of a file or directory
+// record.
+func parseSCPEntry(record string) (os.FileMode, int64, string, error) {
+	parts := strings.SplitN(record, " ", 3)
+	if len(parts) != 3 {
+		return 0, 0, "", xerrors.Errorf("invalid record %q", record)
+	}
+	mode, err := strconv.ParseUint(parts[0], 8, 32)
+	if err != nil {
+		return 0, 0, "", xerrors.Errorf("invalid mode: %w", err)
+	}
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, 0, "", xerrors.Errorf("invalid size %q", parts[1])
+	}
+	name := parts[2]
+	// Names must not escape the target directory.
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return 0, 0, "", xerrors.Errorf("invalid name %q", name)
+	}
+	return os.FileMode(mode).Perm(), size, name, nil
+}
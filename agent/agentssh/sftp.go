@@ -0,0 +1,311 @@
+package agentssh
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/spf13/afero"
+	"go.uber.org/atomic"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+// sftpHandlers serves SFTP requests from the filesystem of the server. Files
+// larger than maxFileSize can't be read or written, and every transfer and
+// change to the filesystem is logged.
+type sftpHandlers struct {
+	ctx         context.Context
+	logger      slog.Logger
+	fs          afero.Fs
+	maxFileSize int64
+}
+
+var (
+	_ sftp.FileReader     = (*sftpHandlers)(nil)
+	_ sftp.OpenFileWriter = (*sftpHandlers)(nil)
+	_ sftp.FileCmder      = (*sftpHandlers)(nil)
+	_ sftp.FileLister     = (*sftpHandlers)(nil)
+)
+
+func (h *sftpHandlers) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	f, err := h.fs.Open(sftpLocalPath(r.Filepath))
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		err = checkFileSize(info.Size(), h.maxFileSize)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return h.trackFile(f, r.Filepath), nil
+}
+
+func (h *sftpHandlers) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	return h.openFile(r)
+}
+
+func (h *sftpHandlers) OpenFile(r *sftp.Request) (sftp.WriterAtReaderAt, error) {
+	return h.openFile(r)
+}
+
+func (h *sftpHandlers) openFile(r *sftp.Request) (*sftpFile, error) {
+	pflags := r.Pflags()
+	flags := os.O_RDONLY
+	switch {
+	case pflags.Read && pflags.Write:
+		flags = os.O_RDWR
+	case pflags.Write:
+		flags = os.O_WRONLY
+	}
+	// O_APPEND is intentionally ignored, because files opened with it don't
+	// support WriteAt. Clients send the offset of appended data anyway.
+	if pflags.Creat {
+		flags |= os.O_CREATE
+	}
+	if pflags.Trunc {
+		flags |= os.O_TRUNC
+	}
+	if pflags.Excl {
+		flags |= os.O_EXCL
+	}
+
+	f, err := h.fs.OpenFile(sftpLocalPath(r.Filepath), flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return h.trackFile(f, r.Filepath), nil
+}
+
+func (h *sftpHandlers) Filecmd(r *sftp.Request) error {
+	path := sftpLocalPath(r.Filepath)
+	var err error
+	switch r.Method {
+	case "Setstat":
+		err = h.setstat(path, r)
+	case "Rename", "PosixRename":
+		err = h.fs.Rename(path, sftpLocalPath(r.Target))
+	case "Rmdir", "Remove":
+		err = h.fs.Remove(path)
+	case "Mkdir":
+		err = h.fs.Mkdir(path, 0o755)
+	case "Symlink":
+		// The request path is the target of the link, and the request
+		// target is the path of the link itself.
+		linker, ok := h.fs.(afero.Linker)
+		if !ok {
+			return sftp.ErrSSHFxOpUnsupported
+		}
+		err = linker.SymlinkIfPossible(path, sftpLocalPath(r.Target))
+	default:
+		// Hard links aren't supported by afero.
+		return sftp.ErrSSHFxOpUnsupported
+	}
+	if err != nil {
+		return err
+	}
+	h.logger.Info(h.ctx, "sftp file command",
+		slog.F("method", r.Method),
+		slog.F("path", r.Filepath),
+		slog.F("target", r.Target),
+	)
+	return nil
+}
+
+func (h *sftpHandlers) setstat(path string, r *sftp.Request) error {
+	attrs := r.Attributes()
+	flags := r.AttrFlags()
+	if flags.Size {
+		err := checkFileSize(int64(attrs.Size), h.maxFileSize)
+		if err != nil {
+			return err
+		}
+		f, err := h.fs.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		err = f.Truncate(int64(attrs.Size))
+		closeErr := f.Close()
+		if err != nil {
+			return err
+		}
+		if closeErr != nil {
+			return closeErr
+		}
+	}
+	if flags.Permissions {
+		err := h.fs.Chmod(path, os.FileMode(attrs.Mode).Perm())
+		if err != nil {
+			return err
+		}
+	}
+	if flags.Acmodtime {
+		err := h.fs.Chtimes(path, time.Unix(int64(attrs.Atime), 0), time.Unix(int64(attrs.Mtime), 0))
+		if err != nil {
+			return err
+		}
+	}
+	if flags.UidGid {
+		err := h.fs.Chown(path, int(attrs.UID), int(attrs.GID))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *sftpHandlers) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	path := sftpLocalPath(r.Filepath)
+	switch r.Method {
+	case "List":
+		infos, err := afero.ReadDir(h.fs, path)
+		if err != nil {
+			return nil, err
+		}
+		return sftpListerAt(infos), nil
+	case "Stat":
+		info, err := h.fs.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return sftpListerAt{info}, nil
+	case "Lstat":
+		lstater, ok := h.fs.(afero.Lstater)
+		if !ok {
+			info, err := h.fs.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			return sftpListerAt{info}, nil
+		}
+		info, _, err := lstater.LstatIfPossible(path)
+		if err != nil {
+			return nil, err
+		}
+		return sftpListerAt{info}, nil
+	case "Readlink":
+		reader, ok := h.fs.(afero.LinkReader)
+		if !ok {
+			return nil, sftp.ErrSSHFxOpUnsupported
+		}
+		target, err := reader.ReadlinkIfPossible(path)
+		if err != nil {
+			return nil, err
+		}
+		// The link target is returned as the name of the file.
+		return sftpListerAt{sftpLinkTarget(filepath.ToSlash(target))}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+func (h *sftpHandlers) trackFile(f afero.File, path string) *sftpFile {
+	return &sftpFile{
+		file:        f,
+		handlers:    h,
+		path:        path,
+		maxFileSize: h.maxFileSize,
+	}
+}
+
+// sftpFile counts the bytes transferred to and from a file, and logs them
+// when the client closes the file.
+type sftpFile struct {
+	file        afero.File
+	handlers    *sftpHandlers
+	path        string
+	maxFileSize int64
+
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+func (f *sftpFile) ReadAt(p []byte, off int64) (int, error) {
+	n, err := f.file.ReadAt(p, off)
+	f.read.Add(int64(n))
+	return n, err
+}
+
+func (f *sftpFile) WriteAt(p []byte, off int64) (int, error) {
+	err := checkFileSize(off+int64(len(p)), f.maxFileSize)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.file.WriteAt(p, off)
+	f.written.Add(int64(n))
+	return n, err
+}
+
+func (f *sftpFile) Close() error {
+	err := f.file.Close()
+	f.handlers.logger.Info(f.handlers.ctx, "file transfer",
+		slog.F("protocol", "sftp"),
+		slog.F("path", f.path),
+		slog.F("bytes_read", f.read.Load()),
+		slog.F("bytes_written", f.written.Load()),
+		slog.Error(err),
+	)
+	return err
+}
+
+// sftpListerAt lists files for the SFTP request server.
+type sftpListerAt []os.FileInfo
+
+func (l sftpListerAt) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(ls, l[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// sftpLinkTarget is the result of a Readlink request, which the request
+// server reads from the name of the file.
+type sftpLinkTarget string
+
+func (t sftpLinkTarget) Name() string     { return string(t) }
+func (sftpLinkTarget) Size() int64        { return 0 }
+func (sftpLinkTarget) Mode() os.FileMode  { return os.ModeSymlink }
+func (sftpLinkTarget) ModTime() time.Time { return time.Time{} }
+func (sftpLinkTarget) IsDir() bool        { return false }
+func (sftpLinkTarget) Sys() interface{}   { return nil }
+
+// sftpLocalPath converts an SFTP path, which always uses forward slashes, to
+// a path on the local filesystem. On Windows, SFTP paths look like
+// "/C:/Users/...".
+func sftpLocalPath(path string) string {
+	if runtime.GOOS == "windows" {
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.FromSlash(path)
+}
+
+// sftpRemotePath is the inverse of sftpLocalPath.
+func sftpRemotePath(path string) string {
+	path = filepath.ToSlash(path)
+	if runtime.GOOS == "windows" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
+// checkFileSize returns an error if a file of the given size exceeds the max
+// file size of the server. A max file size of zero is unlimited.
+func checkFileSize(size, maxFileSize int64) error {
+	if maxFileSize > 0 && size > maxFileSize {
+		return xerrors.Errorf("file size %d exceeds the max file size of %d bytes", size, maxFileSize)
+	}
+	return nil
+}
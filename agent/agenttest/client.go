@@ -80,11 +80,14 @@ type Client struct {
 	LastWorkspaceAgent   func()
 	PatchWorkspaceLogs   func() error
 	GetServiceBannerFunc func() (codersdk.ServiceBannerConfig, error)
+	RotateTokenFunc      func() (agentsdk.RotateTokenResponse, error)
 
 	mu              sync.Mutex // Protects following.
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	startup         agentsdk.PostStartupRequest
+	listeningPorts  []codersdk.WorkspaceAgentListeningPort
 	logs            []agentsdk.Log
+	logSources      []agentsdk.PostLogSource
 	derpMapUpdates  chan agentsdk.DERPMapUpdate
 }
 
@@ -168,6 +171,20 @@ func (c *Client) PostAppHealth(ctx context.Context, req agentsdk.PostAppHealthsR
 	return nil
 }
 
+func (c *Client) GetListeningPorts() []codersdk.WorkspaceAgentListeningPort {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listeningPorts
+}
+
+func (c *Client) PostListeningPorts(ctx context.Context, req agentsdk.PostListeningPortsRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeningPorts = req.Ports
+	c.logger.Debug(ctx, "post listening ports", slog.F("req", req))
+	return nil
+}
+
 func (c *Client) GetStartup() agentsdk.PostStartupRequest {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -218,6 +235,25 @@ func (c *Client) PatchLogs(ctx context.Context, logs agentsdk.PatchLogs) error {
 	return nil
 }
 
+func (c *Client) GetLogSources() []agentsdk.PostLogSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.logSources
+}
+
+func (c *Client) PostLogSource(ctx context.Context, req agentsdk.PostLogSource) (codersdk.WorkspaceAgentLogSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logSources = append(c.logSources, req)
+	c.logger.Debug(ctx, "post log source", slog.F("req", req))
+	return codersdk.WorkspaceAgentLogSource{
+		WorkspaceAgentID: c.agentID,
+		ID:               req.ID,
+		DisplayName:      req.DisplayName,
+		Icon:             req.Icon,
+	}, nil
+}
+
 func (c *Client) SetServiceBannerFunc(f func() (codersdk.ServiceBannerConfig, error)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -235,6 +271,16 @@ func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerCo
 	return codersdk.ServiceBannerConfig{}, nil
 }
 
+func (c *Client) RotateToken(ctx context.Context) (agentsdk.RotateTokenResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logger.Debug(ctx, "rotate token")
+	if c.RotateTokenFunc != nil {
+		return c.RotateTokenFunc()
+	}
+	return agentsdk.RotateTokenResponse{}, xerrors.New("token rotation not supported")
+}
+
 func (c *Client) PushDERPMapUpdate(update agentsdk.DERPMapUpdate) error {
 	timer := time.NewTimer(testutil.WaitShort)
 	defer timer.Stop()
@@ -36,6 +36,10 @@ func (a *agent) apiHandler() http.Handler {
 		cacheDuration: cacheDuration,
 	}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/netcheck", a.handleNetcheck)
+	r.Get("/api/v0/files/list", a.handleListFiles)
+	r.Get("/api/v0/files/download", a.handleDownloadFile)
+	r.Post("/api/v0/files/upload", a.handleUploadFile)
 
 	return r
 }
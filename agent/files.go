@@ -0,0 +1,228 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// The file endpoints give the same access to the workspace as SSH, so they
+// are only proxied by coderd for users that may open a terminal in it, and
+// transfers are limited to the same max file size.
+
+// errPathOutsideHome is returned for relative paths that traverse out of the
+// home directory.
+var errPathOutsideHome = xerrors.New("relative paths must not leave the home directory")
+
+func (a *agent) handleListFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not resolve path.")
+		return
+	}
+
+	info, err := a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not read directory.")
+		return
+	}
+	if !info.IsDir() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Path is not a directory.",
+		})
+		return
+	}
+
+	infos, err := afero.ReadDir(a.filesystem, path)
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not read directory.")
+		return
+	}
+	files := make([]codersdk.WorkspaceAgentFile, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, workspaceAgentFile(filepath.Join(path, info.Name()), info))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentListFilesResponse{
+		Path:  path,
+		Files: files,
+	})
+}
+
+func (a *agent) handleDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not resolve path.")
+		return
+	}
+
+	f, err := a.filesystem.Open(path)
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not open file.")
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not open file.")
+		return
+	}
+	if info.IsDir() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Path is a directory.",
+		})
+		return
+	}
+	if a.sshMaxFileSize > 0 && info.Size() > a.sshMaxFileSize {
+		httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+			Message: fmt.Sprintf("File exceeds the max file size of %d bytes.", a.sshMaxFileSize),
+		})
+		return
+	}
+
+	http.ServeContent(rw, r, info.Name(), info.ModTime(), f)
+}
+
+func (a *agent) handleUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, err := resolveFilePath(r.URL.Query().Get("path"))
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not resolve path.")
+		return
+	}
+
+	content := r.Body
+	if a.sshMaxFileSize > 0 {
+		if r.ContentLength > a.sshMaxFileSize {
+			httpapi.Write(ctx, rw, http.StatusRequestEntityTooLarge, codersdk.Response{
+				Message: fmt.Sprintf("File exceeds the max file size of %d bytes.", a.sshMaxFileSize),
+			})
+			return
+		}
+		content = http.MaxBytesReader(rw, r.Body, a.sshMaxFileSize)
+	}
+
+	var mode fs.FileMode = 0o644
+	info, err := a.filesystem.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Path is a directory.",
+		})
+		return
+	case err == nil:
+		mode = info.Mode().Perm()
+	case !errors.Is(err, fs.ErrNotExist):
+		writeFileError(ctx, rw, err, "Could not write file.")
+		return
+	}
+
+	dir := filepath.Dir(path)
+	err = a.filesystem.MkdirAll(dir, 0o755)
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not create directory.")
+		return
+	}
+
+	// The upload is written to a temporary file first, so a failed upload
+	// doesn't leave a partial file behind.
+	tmp, err := afero.TempFile(a.filesystem, dir, "."+filepath.Base(path)+".upload-*")
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not write file.")
+		return
+	}
+	err = writeUpload(a.filesystem, tmp, content, mode)
+	if err == nil {
+		err = a.filesystem.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = a.filesystem.Remove(tmp.Name())
+		writeFileError(ctx, rw, err, "Could not write file.")
+		return
+	}
+
+	info, err = a.filesystem.Stat(path)
+	if err != nil {
+		writeFileError(ctx, rw, err, "Could not write file.")
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, workspaceAgentFile(path, info))
+}
+
+func writeUpload(filesystem afero.Fs, f afero.File, content io.Reader, mode fs.FileMode) error {
+	_, err := io.Copy(f, content)
+	closeErr := f.Close()
+	if err != nil {
+		return xerrors.Errorf("copy content: %w", err)
+	}
+	if closeErr != nil {
+		return xerrors.Errorf("close file: %w", closeErr)
+	}
+	return filesystem.Chmod(f.Name(), mode)
+}
+
+// resolveFilePath returns the absolute path of a file requested over the
+// HTTP API. Relative paths are relative to the home directory, and may not
+// traverse out of it.
+func resolveFilePath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = strings.TrimLeft(path[1:], "/")
+	}
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path = filepath.Join(home, path)
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errPathOutsideHome
+	}
+	return path, nil
+}
+
+func workspaceAgentFile(path string, info os.FileInfo) codersdk.WorkspaceAgentFile {
+	return codersdk.WorkspaceAgentFile{
+		Name:       info.Name(),
+		Path:       path,
+		Size:       info.Size(),
+		Mode:       info.Mode().String(),
+		IsDir:      info.IsDir(),
+		ModifiedAt: info.ModTime(),
+	}
+}
+
+func writeFileError(ctx context.Context, rw http.ResponseWriter, err error, message string) {
+	status := http.StatusInternalServerError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, errPathOutsideHome):
+		status = http.StatusBadRequest
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, fs.ErrNotExist):
+		status = http.StatusNotFound
+	case errors.Is(err, fs.ErrPermission):
+		status = http.StatusForbidden
+	}
+	httpapi.Write(ctx, rw, status, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
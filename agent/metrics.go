@@ -7,6 +7,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	prompb "github.com/prometheus/client_model/go"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/netlogtype"
 	"tailscale.com/util/clientmetric"
 
 	"cdr.dev/slog"
@@ -20,6 +22,15 @@ type agentMetrics struct {
 	// startupScriptSeconds is the time in seconds that the start script(s)
 	// took to run. This is reported once per agent.
 	startupScriptSeconds *prometheus.GaugeVec
+	// tailnetBytes is the number of bytes exchanged with peers, by whether
+	// they were relayed through DERP or sent directly.
+	tailnetBytes *prometheus.CounterVec
+	// coderdConnectSeconds is the time the latest connection to the agent
+	// API of coderd took to establish, by transport.
+	coderdConnectSeconds *prometheus.GaugeVec
+	// quicFallbacks counts the connections made over a WebSocket because
+	// QUIC failed.
+	quicFallbacks prometheus.Counter
 }
 
 func newAgentMetrics(registerer prometheus.Registerer) *agentMetrics {
@@ -46,10 +57,50 @@ func newAgentMetrics(registerer prometheus.Registerer) *agentMetrics {
 	}, []string{"success"})
 	registerer.MustRegister(startupScriptSeconds)
 
+	tailnetBytes := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "tailnet_bytes_total",
+		Help:      "Bytes exchanged with peers, by whether they were relayed through DERP or sent directly.",
+	}, []string{"connection_type", "direction"})
+	registerer.MustRegister(tailnetBytes)
+
+	coderdConnectSeconds := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "agent",
+		Subsystem: "coderd",
+		Name:      "connect_seconds",
+		Help:      "Time taken by the latest connection to the agent API of coderd, by transport.",
+	}, []string{"transport"})
+	registerer.MustRegister(coderdConnectSeconds)
+
+	quicFallbacks := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "agent",
+		Subsystem: "coderd",
+		Name:      "quic_fallbacks_total",
+		Help:      "Connections to coderd made over a WebSocket because QUIC failed.",
+	})
+	registerer.MustRegister(quicFallbacks)
+
 	return &agentMetrics{
 		connectionsTotal:      connectionsTotal,
 		reconnectingPTYErrors: reconnectingPTYErrors,
 		startupScriptSeconds:  startupScriptSeconds,
+		tailnetBytes:          tailnetBytes,
+		coderdConnectSeconds:  coderdConnectSeconds,
+		quicFallbacks:         quicFallbacks,
+	}
+}
+
+// observePhysicalTraffic counts the bytes of the physical connections to
+// peers. Connections relayed through DERP are sent to the DERP magic IP.
+func (m *agentMetrics) observePhysicalTraffic(physical map[netlogtype.Connection]netlogtype.Counts) {
+	for conn, counts := range physical {
+		connectionType := "direct"
+		if conn.Dst.Addr() == tailcfg.DerpMagicIPAddr {
+			connectionType = "derp"
+		}
+		m.tailnetBytes.WithLabelValues(connectionType, "rx").Add(float64(counts.RxBytes))
+		m.tailnetBytes.WithLabelValues(connectionType, "tx").Add(float64(counts.TxBytes))
 	}
 }
 
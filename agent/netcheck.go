@@ -0,0 +1,59 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"tailscale.com/net/netcheck"
+	"tailscale.com/net/portmapper"
+	tslogger "tailscale.com/types/logger"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// handleNetcheck runs a netcheck against the DERP map of the agent, so
+// connectivity problems can be diagnosed from inside the workspace.
+func (a *agent) handleNetcheck(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	a.closeMutex.Lock()
+	network := a.network
+	a.closeMutex.Unlock()
+	if network == nil {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "The agent network is not ready yet.",
+		})
+		return
+	}
+
+	var (
+		mu   sync.Mutex
+		resp = codersdk.WorkspaceAgentNetcheckResponse{
+			NetcheckLogs: []string{},
+		}
+	)
+	logf := func(format string, args ...interface{}) {
+		mu.Lock()
+		resp.NetcheckLogs = append(resp.NetcheckLogs, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+	nc := &netcheck.Client{
+		PortMapper: portmapper.NewClient(tslogger.WithPrefix(logf, "portmap: "), nil, nil, nil),
+		Logf:       tslogger.WithPrefix(logf, "netcheck: "),
+	}
+	report, err := nc.GetReport(ctx, network.DERPMap())
+
+	mu.Lock()
+	defer mu.Unlock()
+	resp.Netcheck = report
+	if err != nil {
+		resp.NetcheckErr = ptr.Ref(err.Error())
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
@@ -54,6 +54,7 @@ func SDKAgentScriptFromProto(protoScript *WorkspaceAgentScript) (codersdk.Worksp
 		RunOnStop:        protoScript.RunOnStop,
 		StartBlocksLogin: protoScript.StartBlocksLogin,
 		Timeout:          protoScript.Timeout.AsDuration(),
+		Phase:            codersdk.WorkspaceAgentScriptPhase(protoScript.Phase),
 	}, nil
 }
 
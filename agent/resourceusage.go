@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"os"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/cli/clistat"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// collectResourceUsage fills in the CPU, memory and disk usage of the
+// workspace. Container (cgroup) limits take precedence over host values
+// when the agent is running inside a container. Failures are logged and
+// leave the corresponding fields zeroed.
+func (a *agent) collectResourceUsage(ctx context.Context, stats *agentsdk.Stats) {
+	if a.statter == nil {
+		statter, err := clistat.New(clistat.WithFS(a.filesystem))
+		if err != nil {
+			a.logger.Warn(ctx, "create resource usage statter", slog.Error(err))
+			return
+		}
+		a.statter = statter
+	}
+
+	containerized, err := clistat.IsContainerized(a.filesystem)
+	if err != nil {
+		a.logger.Debug(ctx, "check if containerized", slog.Error(err))
+	}
+
+	cpu, err := a.statter.ContainerCPU()
+	if err != nil {
+		a.logger.Debug(ctx, "collect container cpu usage", slog.Error(err))
+	}
+	if cpu == nil {
+		cpu, err = a.statter.HostCPU()
+		if err != nil {
+			a.logger.Debug(ctx, "collect host cpu usage", slog.Error(err))
+		}
+	}
+	if cpu != nil {
+		stats.CPUUsedCores = cpu.Used
+		if cpu.Total != nil {
+			stats.CPUTotalCores = *cpu.Total
+		}
+	}
+
+	var mem *clistat.Result
+	if containerized {
+		mem, err = a.statter.ContainerMemory(clistat.PrefixDefault)
+		if err != nil {
+			a.logger.Debug(ctx, "collect container memory usage", slog.Error(err))
+		}
+	}
+	if mem == nil {
+		mem, err = a.statter.HostMemory(clistat.PrefixDefault)
+		if err != nil {
+			a.logger.Debug(ctx, "collect host memory usage", slog.Error(err))
+		}
+	}
+	if mem != nil {
+		stats.MemoryUsedBytes = int64(mem.Used)
+		if mem.Total != nil {
+			stats.MemoryTotalBytes = int64(*mem.Total)
+		}
+	}
+
+	// An empty path makes Disk fall back to the root of the filesystem.
+	home, _ := os.UserHomeDir()
+	disk, err := a.statter.Disk(clistat.PrefixDefault, home)
+	if err != nil {
+		a.logger.Debug(ctx, "collect disk usage", slog.Error(err))
+	}
+	if disk != nil {
+		stats.DiskUsedBytes = int64(disk.Used)
+		if disk.Total != nil {
+			stats.DiskTotalBytes = int64(*disk.Total)
+		}
+	}
+}
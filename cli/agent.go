@@ -28,6 +28,7 @@ import (
 	"cdr.dev/slog/sloggers/slogjson"
 	"cdr.dev/slog/sloggers/slogstackdriver"
 	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agentdevcontainer"
 	"github.com/coder/coder/v2/agent/agentproc"
 	"github.com/coder/coder/v2/agent/reaper"
 	"github.com/coder/coder/v2/buildinfo"
@@ -43,12 +44,19 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		pprofAddress        string
 		noReap              bool
 		sshMaxTimeout       time.Duration
+		sshMaxFileSize      int64
 		tailnetListenPort   int64
 		prometheusAddress   string
 		debugAddress        string
 		slogHumanPath       string
 		slogJSONPath        string
 		slogStackdriverPath string
+
+		tokenRotationInterval time.Duration
+
+		devcontainerWorkspaceFolder string
+		devcontainerConfig          string
+		devcontainerBuilder         string
 	)
 	cmd := &clibase.Cmd{
 		Use:   "agent",
@@ -197,11 +205,14 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			// This is abstracted to allow for the same looping condition
 			// regardless of instance identity auth type.
 			var exchangeToken func(context.Context) (agentsdk.AuthenticateResponse, error)
+			// tokenFile is rewritten when the token is rotated, so a
+			// restarted agent picks up the new token.
+			var tokenFile string
 			switch auth {
 			case "token":
 				token, _ := inv.ParsedFlags().GetString(varAgentToken)
 				if token == "" {
-					tokenFile, _ := inv.ParsedFlags().GetString(varAgentTokenFile)
+					tokenFile, _ = inv.ParsedFlags().GetString(varAgentTokenFile)
 					if tokenFile != "" {
 						tokenBytes, err := os.ReadFile(tokenFile)
 						if err != nil {
@@ -213,6 +224,11 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				if token == "" {
 					return xerrors.Errorf("CODER_AGENT_TOKEN or CODER_AGENT_TOKEN_FILE must be set for token auth")
 				}
+				// A rotated token that is not written back is lost when the
+				// agent restarts, and the original token expires shortly after.
+				if tokenFile == "" && tokenRotationInterval > 0 {
+					return xerrors.Errorf("CODER_AGENT_TOKEN_FILE must be set to rotate the session token with token auth")
+				}
 				client.SetSessionToken(token)
 			case "google-instance-identity":
 				// This is *only* done for testing to mock client authentication.
@@ -300,9 +316,26 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 					"GIT_ASKPASS":         executablePath,
 					agent.EnvProcPrioMgmt: os.Getenv(agent.EnvProcPrioMgmt),
 				},
-				IgnorePorts:   ignorePorts,
-				SSHMaxTimeout: sshMaxTimeout,
-				Subsystems:    subsystems,
+				IgnorePorts:    ignorePorts,
+				SSHMaxTimeout:  sshMaxTimeout,
+				SSHMaxFileSize: sshMaxFileSize,
+				Subsystems:     subsystems,
+				Devcontainer: agentdevcontainer.Settings{
+					WorkspaceFolder: devcontainerWorkspaceFolder,
+					ConfigPath:      devcontainerConfig,
+					Builder:         devcontainerBuilder,
+				},
+
+				TokenRotationInterval: tokenRotationInterval,
+				TokenRotated: func(token string) {
+					if tokenFile == "" {
+						return
+					}
+					err := os.WriteFile(tokenFile, []byte(token), 0o600)
+					if err != nil {
+						logger.Error(ctx, "write rotated token to file", slog.F("path", tokenFile), slog.Error(err))
+					}
+				},
 
 				PrometheusRegistry: prometheusRegistry,
 				Syscaller:          agentproc.NewSyscaller(),
@@ -359,6 +392,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Description: "Specify the max timeout for a SSH connection, it is advisable to set it to a minimum of 60s, but no more than 72h.",
 			Value:       clibase.DurationOf(&sshMaxTimeout),
 		},
+		{
+			Flag:        "ssh-max-file-size",
+			Default:     "0",
+			Env:         "CODER_AGENT_SSH_MAX_FILE_SIZE",
+			Description: "The max size in bytes of files transferred over SSH with SFTP or SCP, or uploaded and downloaded through the workspace file endpoints. Set to 0 to disable the limit.",
+			Value:       clibase.Int64Of(&sshMaxFileSize),
+		},
 		{
 			Flag:        "tailnet-listen-port",
 			Default:     "0",
@@ -380,6 +420,32 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Value:       clibase.StringOf(&debugAddress),
 			Description: "The bind address to serve a debug HTTP server.",
 		},
+		{
+			Flag:        "token-rotation-interval",
+			Default:     "0",
+			Env:         "CODER_AGENT_TOKEN_ROTATION_INTERVAL",
+			Description: "How often the agent rotates its session token. The previous token stays valid for 15 minutes after each rotation. With token auth, the token must be read from CODER_AGENT_TOKEN_FILE so the rotated token can be written back. Set to 0 to disable rotation.",
+			Value:       clibase.DurationOf(&tokenRotationInterval),
+		},
+		{
+			Flag:        "devcontainer-workspace-folder",
+			Env:         "CODER_AGENT_DEVCONTAINER_WORKSPACE_FOLDER",
+			Description: "The folder of a repository with a devcontainer.json. If set, the dev container is built and started after the startup scripts.",
+			Value:       clibase.StringOf(&devcontainerWorkspaceFolder),
+		},
+		{
+			Flag:        "devcontainer-config",
+			Env:         "CODER_AGENT_DEVCONTAINER_CONFIG",
+			Description: "The path of devcontainer.json, relative to the dev container workspace folder. Defaults to .devcontainer/devcontainer.json or .devcontainer.json.",
+			Value:       clibase.StringOf(&devcontainerConfig),
+		},
+		{
+			Flag:        "devcontainer-builder",
+			Default:     agentdevcontainer.BuilderDocker,
+			Env:         "CODER_AGENT_DEVCONTAINER_BUILDER",
+			Description: "How the dev container is built: with docker, or with envbuilder running in docker.",
+			Value:       clibase.EnumOf(&devcontainerBuilder, agentdevcontainer.BuilderDocker, agentdevcontainer.BuilderEnvbuilder),
+		},
 		{
 			Name:        "Human Log Location",
 			Description: "Output human-readable logs to a given file.",
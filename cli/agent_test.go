@@ -220,4 +220,21 @@ func TestWorkspaceAgent(t *testing.T) {
 		require.Equal(t, codersdk.AgentSubsystemEnvbox, resources[0].Agents[0].Subsystems[0])
 		require.Equal(t, codersdk.AgentSubsystemExectrace, resources[0].Agents[0].Subsystems[1])
 	})
+
+	t.Run("TokenRotationRequiresTokenFile", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		inv, _ := clitest.New(t,
+			"agent",
+			"--auth", "token",
+			"--agent-token", uuid.NewString(),
+			"--agent-url", client.URL.String(),
+			"--log-dir", t.TempDir(),
+			"--token-rotation-interval", "1h",
+		)
+
+		err := inv.Run()
+		require.ErrorContains(t, err, "CODER_AGENT_TOKEN_FILE must be set")
+	})
 }
@@ -1,6 +1,7 @@
 package cliui
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,10 +9,22 @@ import (
 	"strings"
 
 	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
 
 	"github.com/coder/coder/v2/cli/clibase"
 )
 
+// OutputSchemaVersion is the version of the structured (JSON and YAML) output
+// of commands. Adding fields to the output is not a breaking change, so the
+// version is only bumped when fields are removed, renamed or change type.
+//
+// The formatters add it as the first field of the output, or of every element
+// when the output is a list, so lists stay lists.
+const OutputSchemaVersion = 1
+
+// outputSchemaVersionField is the field OutputSchemaVersion is reported in.
+const outputSchemaVersionField = "output_schema_version"
+
 type OutputFormat interface {
 	ID() string
 	AttachOptions(opts *clibase.OptionSet)
@@ -70,6 +83,12 @@ func (f *OutputFormatter) AttachOptions(opts *clibase.OptionSet) {
 	)
 }
 
+// FormatID returns the ID of the format specified by the --output flag, or of
+// the default format.
+func (f *OutputFormatter) FormatID() string {
+	return f.formatID
+}
+
 // Format formats the given data using the format specified by the --output
 // flag. If the flag is not set, the default format is used.
 func (f *OutputFormatter) Format(ctx context.Context, data any) (string, error) {
@@ -165,12 +184,115 @@ func (jsonFormat) AttachOptions(_ *clibase.OptionSet) {}
 
 // Format implements OutputFormat.
 func (jsonFormat) Format(_ context.Context, data any) (string, error) {
-	outBytes, err := json.MarshalIndent(data, "", "  ")
+	outBytes, err := marshalVersioned(data)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, outBytes, "", "  ")
+	if err != nil {
+		return "", xerrors.Errorf("indent JSON output: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// marshalVersioned marshals data to JSON, and adds OutputSchemaVersion to the
+// output if it's an object, or to each object in it if it's a list.
+func marshalVersioned(data any) ([]byte, error) {
+	outBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal output to JSON: %w", err)
+	}
+	outBytes = bytes.TrimSpace(outBytes)
+	if len(outBytes) == 0 || outBytes[0] != '[' {
+		return addSchemaVersion(outBytes), nil
+	}
+
+	var list []json.RawMessage
+	err = json.Unmarshal(outBytes, &list)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal JSON output: %w", err)
+	}
+	for i, elem := range list {
+		list[i] = addSchemaVersion(bytes.TrimSpace(elem))
+	}
+	outBytes, err = json.Marshal(list)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal output to JSON: %w", err)
+	}
+	return outBytes, nil
+}
+
+// addSchemaVersion adds OutputSchemaVersion as the first field of a JSON
+// object. Anything else is returned unchanged.
+func addSchemaVersion(obj []byte) []byte {
+	if len(obj) < 2 || obj[0] != '{' {
+		return obj
+	}
+	field := fmt.Sprintf("{%q:%d", outputSchemaVersionField, OutputSchemaVersion)
+	rest := bytes.TrimSpace(obj[1:])
+	if rest[0] != '}' {
+		field += ","
+	}
+	return append([]byte(field), rest...)
+}
+
+type yamlFormat struct{}
+
+var _ OutputFormat = yamlFormat{}
+
+// YAMLFormat creates a YAML formatter. The output uses the same field names
+// and field order as JSONFormat.
+func YAMLFormat() OutputFormat {
+	return yamlFormat{}
+}
+
+// ID implements OutputFormat.
+func (yamlFormat) ID() string {
+	return "yaml"
+}
+
+// AttachOptions implements OutputFormat.
+func (yamlFormat) AttachOptions(_ *clibase.OptionSet) {}
+
+// Format implements OutputFormat.
+func (yamlFormat) Format(_ context.Context, data any) (string, error) {
+	// Go through JSON so the json struct tags and marshalers of the data are
+	// respected, and both formats have the same schema.
+	jsonBytes, err := marshalVersioned(data)
+	if err != nil {
+		return "", err
+	}
+	var node yaml.Node
+	err = yaml.Unmarshal(jsonBytes, &node)
+	if err != nil {
+		return "", xerrors.Errorf("unmarshal JSON output: %w", err)
+	}
+	// JSON is parsed as flow style with quoted strings, reset it so the
+	// output is regular block style YAML.
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err = enc.Encode(&node)
+	if err != nil {
+		return "", xerrors.Errorf("marshal output to YAML: %w", err)
+	}
+	err = enc.Close()
 	if err != nil {
-		return "", xerrors.Errorf("marshal output to JSON: %w", err)
+		return "", xerrors.Errorf("marshal output to YAML: %w", err)
 	}
 
-	return string(outBytes), nil
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
 }
 
 type textFormat struct{}
@@ -137,3 +137,59 @@ func Test_OutputFormatter(t *testing.T) {
 		require.EqualValues(t, 1, atomic.LoadInt64(&called))
 	})
 }
+
+func Test_YAMLFormat(t *testing.T) {
+	t.Parallel()
+
+	data := struct {
+		Name    string   `json:"name"`
+		Tags    []string `json:"tags"`
+		Count   int      `json:"count"`
+		Missing *string  `json:"missing"`
+		Skipped string   `json:"-"`
+	}{
+		Name:    "dean",
+		Tags:    []string{"a", "b"},
+		Count:   3,
+		Skipped: "skipped",
+	}
+
+	out, err := cliui.YAMLFormat().Format(context.Background(), data)
+	require.NoError(t, err)
+	require.Equal(t, `output_schema_version: 1
+name: dean
+tags:
+  - a
+  - b
+count: 3
+missing: null`, out)
+}
+
+func Test_JSONFormatSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	// Objects, and every object in a list, start with the schema version.
+	out, err := cliui.JSONFormat().Format(context.Background(), []any{item{Name: "dean"}, struct{}{}, "hi"})
+	require.NoError(t, err)
+	require.Equal(t, `[
+  {
+    "output_schema_version": 1,
+    "name": "dean"
+  },
+  {
+    "output_schema_version": 1
+  },
+  "hi"
+]`, out)
+
+	out, err = cliui.JSONFormat().Format(context.Background(), item{Name: "dean"})
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "output_schema_version": 1,
+  "name": "dean"
+}`, out)
+}
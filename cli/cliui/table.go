@@ -52,7 +52,11 @@ func filterTableColumns(header table.Row, columns []string) []table.ColumnConfig
 // containing the name of the column in the outputted table.
 //
 // If `sort` is not specified, the field with the `table:"$NAME,default_sort"`
-// tag will be used to sort. An error will be returned if no field has this tag.
+// tag will be used to sort, or `table:"$NAME,default_sort_desc"` to sort in
+// descending order. A struct inlined with `recursive_inline` provides its
+// default sort column if the outer struct doesn't have one. An error will be
+// returned if no field has either tag. A sort column prefixed with "-" is
+// sorted in descending order.
 //
 // Nested structs are processed if the field has the `table:"$NAME,recursive"`
 // tag and their fields will be named as `$PARENT_NAME $NAME`. If the tag is
@@ -80,6 +84,11 @@ func DisplayTable(out any, sort string, filterColumns []string) (string, error)
 	if sort == "" {
 		sort = defaultSort
 	}
+	sortMode := table.Asc
+	if strings.HasPrefix(sort, "-") {
+		sort = strings.TrimPrefix(sort, "-")
+		sortMode = table.Dsc
+	}
 	headers := make(table.Row, len(headersRaw))
 	for i, header := range headersRaw {
 		headers[i] = header
@@ -138,6 +147,7 @@ func DisplayTable(out any, sort string, filterColumns []string) (string, error)
 	if sort != "" {
 		tw.SortBy([]table.SortBy{{
 			Name: sort,
+			Mode: sortMode,
 		}})
 	}
 
@@ -187,26 +197,30 @@ func DisplayTable(out any, sort string, filterColumns []string) (string, error)
 // struct tag. If the table tag does not exist or is "-", an empty string is
 // returned. If the table tag is malformed, an error is returned.
 //
-// The returned name is transformed from "snake_case" to "normal text".
-func parseTableStructTag(field reflect.StructField) (name string, defaultSort, recursive bool, skipParentName bool, err error) {
+// The returned name is transformed from "snake_case" to "normal text". If the
+// field is the default sort column, defaultSort is the name, prefixed with "-"
+// for a descending sort.
+func parseTableStructTag(field reflect.StructField) (name string, defaultSort string, recursive bool, skipParentName bool, err error) {
 	tags, err := structtag.Parse(string(field.Tag))
 	if err != nil {
-		return "", false, false, false, xerrors.Errorf("parse struct field tag %q: %w", string(field.Tag), err)
+		return "", "", false, false, xerrors.Errorf("parse struct field tag %q: %w", string(field.Tag), err)
 	}
 
 	tag, err := tags.Get("table")
 	if err != nil || tag.Name == "-" {
 		// tags.Get only returns an error if the tag is not found.
-		return "", false, false, false, nil
+		return "", "", false, false, nil
 	}
 
-	defaultSortOpt := false
+	defaultSortOpt := ""
 	recursiveOpt := false
 	skipParentNameOpt := false
 	for _, opt := range tag.Options {
 		switch opt {
 		case "default_sort":
-			defaultSortOpt = true
+			defaultSortOpt = strings.ReplaceAll(tag.Name, "_", " ")
+		case "default_sort_desc":
+			defaultSortOpt = "-" + strings.ReplaceAll(tag.Name, "_", " ")
 		case "recursive":
 			recursiveOpt = true
 		case "recursive_inline":
@@ -216,7 +230,7 @@ func parseTableStructTag(field reflect.StructField) (name string, defaultSort, r
 			recursiveOpt = true
 			skipParentNameOpt = true
 		default:
-			return "", false, false, false, xerrors.Errorf("unknown option %q in struct field tag", opt)
+			return "", "", false, false, xerrors.Errorf("unknown option %q in struct field tag", opt)
 		}
 	}
 
@@ -240,6 +254,9 @@ func typeToTableHeaders(t reflect.Type) ([]string, string, error) {
 
 	headers := []string{}
 	defaultSortName := ""
+	// inlineDefaultSortName is the default sort column of an inlined struct,
+	// which is used if the type doesn't mark one itself.
+	inlineDefaultSortName := ""
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		name, defaultSort, recursive, skip, err := parseTableStructTag(field)
@@ -249,11 +266,11 @@ func typeToTableHeaders(t reflect.Type) ([]string, string, error) {
 		if name == "" {
 			continue
 		}
-		if defaultSort {
+		if defaultSort != "" {
 			if defaultSortName != "" {
 				return nil, "", xerrors.Errorf("multiple fields marked as default sort in type %q", t.String())
 			}
-			defaultSortName = name
+			defaultSortName = defaultSort
 		}
 
 		fieldType := field.Type
@@ -262,10 +279,13 @@ func typeToTableHeaders(t reflect.Type) ([]string, string, error) {
 				return nil, "", xerrors.Errorf("field %q in type %q is marked as recursive but does not contain a struct or a pointer to a struct", field.Name, t.String())
 			}
 
-			childNames, _, err := typeToTableHeaders(fieldType)
+			childNames, childDefaultSort, err := typeToTableHeaders(fieldType)
 			if err != nil {
 				return nil, "", xerrors.Errorf("get child field header names for field %q in type %q: %w", field.Name, fieldType.String(), err)
 			}
+			if skip && inlineDefaultSortName == "" {
+				inlineDefaultSortName = childDefaultSort
+			}
 			for _, childName := range childNames {
 				fullName := fmt.Sprintf("%s %s", name, childName)
 				if skip {
@@ -279,6 +299,9 @@ func typeToTableHeaders(t reflect.Type) ([]string, string, error) {
 		headers = append(headers, name)
 	}
 
+	if defaultSortName == "" {
+		defaultSortName = inlineDefaultSortName
+	}
 	if defaultSortName == "" {
 		return nil, "", xerrors.Errorf("no field marked as default_sort in type %q", t.String())
 	}
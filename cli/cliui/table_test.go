@@ -54,6 +54,16 @@ type tableTest4 struct {
 	SortField string     `table:"sort_field,default_sort"`
 }
 
+type tableTest5 struct {
+	Inline tableTest2 `table:"ignored,recursive_inline"`
+	Extra  string     `table:"extra"`
+}
+
+type tableTest6 struct {
+	Name string `table:"name"`
+	Age  int    `table:"age,default_sort_desc"`
+}
+
 func Test_DisplayTable(t *testing.T) {
 	t.Parallel()
 
@@ -218,6 +228,58 @@ Alice   25
 		compareTables(t, expected, out)
 	})
 
+	t.Run("InlineDefaultSort", func(t *testing.T) {
+		t.Parallel()
+
+		expected := `
+NAME    AGE  EXTRA
+Alice   25   a
+Bob     30   b
+		`
+
+		inlineIn := []tableTest5{
+			{Inline: tableTest2{Name: stringWrapper{str: "Bob"}, Age: 30}, Extra: "b"},
+			{Inline: tableTest2{Name: stringWrapper{str: "Alice"}, Age: 25}, Extra: "a"},
+		}
+		out, err := cliui.DisplayTable(inlineIn, "", nil)
+		log.Println("rendered table:\n" + out)
+		require.NoError(t, err)
+		compareTables(t, expected, out)
+	})
+
+	t.Run("DefaultSortDescending", func(t *testing.T) {
+		t.Parallel()
+
+		expected := `
+NAME   AGE
+Bob    30
+Alice  25
+Carol  20
+		`
+
+		descIn := []tableTest6{
+			{Name: "Alice", Age: 25},
+			{Name: "Carol", Age: 20},
+			{Name: "Bob", Age: 30},
+		}
+		out, err := cliui.DisplayTable(descIn, "", nil)
+		log.Println("rendered table:\n" + out)
+		require.NoError(t, err)
+		compareTables(t, expected, out)
+
+		// A sort column prefixed with "-" is descending too.
+		expected = `
+NAME   AGE
+Carol  20
+Bob    30
+Alice  25
+		`
+		out, err = cliui.DisplayTable(descIn, "-name", nil)
+		log.Println("rendered table:\n" + out)
+		require.NoError(t, err)
+		compareTables(t, expected, out)
+	})
+
 	// This test ensures that safeties against invalid use of `table` tags
 	// causes errors (even without data).
 	t.Run("Errors", func(t *testing.T) {
@@ -46,6 +46,13 @@ func (r Root) Organization() File {
 	return File(filepath.Join(string(r), "organization"))
 }
 
+// Region is the name of the workspace proxy region the user selected to open
+// apps through. If absent, the region with the lowest latency is used.
+func (r Root) Region() File {
+	r.mustNotEmpty()
+	return File(filepath.Join(string(r), "region"))
+}
+
 func (r Root) DotfilesURL() File {
 	r.mustNotEmpty()
 	return File(filepath.Join(string(r), "dotfilesurl"))
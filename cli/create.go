@@ -29,6 +29,8 @@ func (r *RootCmd) create() *clibase.Cmd {
 		parameterFlags     workspaceParameterFlags
 		autoUpdates        string
 		copyParametersFrom string
+		ephemeral          bool
+		presetName         string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -173,6 +175,21 @@ func (r *RootCmd) create() *clibase.Cmd {
 				}
 			}
 
+			var presetParameters []codersdk.WorkspaceBuildParameter
+			if presetName != "" {
+				presets, err := client.TemplateVersionPresets(inv.Context(), templateVersionID)
+				if err != nil {
+					return xerrors.Errorf("get template version presets: %w", err)
+				}
+				i := slices.IndexFunc(presets, func(preset codersdk.TemplateVersionPreset) bool {
+					return preset.Name == presetName
+				})
+				if i < 0 {
+					return xerrors.Errorf("template version has no preset named %q", presetName)
+				}
+				presetParameters = presets[i].Parameters
+			}
+
 			richParameters, err := prepWorkspaceBuild(inv, client, prepWorkspaceBuildArgs{
 				Action:            WorkspaceCreate,
 				TemplateVersionID: templateVersionID,
@@ -180,6 +197,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 
 				RichParameterFile: parameterFlags.richParameterFile,
 				RichParameters:    cliBuildParameters,
+				PresetParameters:  presetParameters,
 
 				SourceWorkspaceParameters: sourceWorkspaceParameters,
 			})
@@ -207,6 +225,8 @@ func (r *RootCmd) create() *clibase.Cmd {
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
 				AutomaticUpdates:    codersdk.AutomaticUpdates(autoUpdates),
+				Ephemeral:           ephemeral,
+				Preset:              presetName,
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -259,6 +279,18 @@ func (r *RootCmd) create() *clibase.Cmd {
 			Description: "Specify the source workspace name to copy parameters from.",
 			Value:       clibase.StringOf(&copyParametersFrom),
 		},
+		clibase.Option{
+			Flag:        "ephemeral",
+			Env:         "CODER_WORKSPACE_EPHEMERAL",
+			Description: "Delete the workspace when the --stop-after duration has passed since it was created, or when the API token used to create it expires.",
+			Value:       clibase.BoolOf(&ephemeral),
+		},
+		clibase.Option{
+			Flag:        "preset",
+			Env:         "CODER_WORKSPACE_PRESET",
+			Description: "Specify the name of a preset of the template version to use for the parameters that aren't given explicitly.",
+			Value:       clibase.StringOf(&presetName),
+		},
 		cliui.SkipPromptOption(),
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
@@ -279,6 +311,7 @@ type prepWorkspaceBuildArgs struct {
 	PromptRichParameters bool
 	RichParameters       []codersdk.WorkspaceBuildParameter
 	RichParameterFile    string
+	PresetParameters     []codersdk.WorkspaceBuildParameter
 }
 
 // prepWorkspaceBuild will ensure a workspace build will succeed on the latest template version.
@@ -311,6 +344,7 @@ func prepWorkspaceBuild(inv *clibase.Invocation, client *codersdk.Client, args p
 		WithBuildOptions(args.BuildOptions).
 		WithPromptRichParameters(args.PromptRichParameters).
 		WithRichParameters(args.RichParameters).
+		WithPresetParameters(args.PresetParameters).
 		WithRichParametersFile(parameterFile)
 	buildParameters, err := resolver.Resolve(inv, args.Action, templateVersionParameters)
 	if err != nil {
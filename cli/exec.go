@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) exec() *clibase.Cmd {
+	var (
+		waitEnum         string
+		disableAutostart bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "exec <workspace> -- <command> [args...]",
+		Short:       "Run a command in a workspace",
+		Long: formatExamples(
+			example{
+				Description: "Run a command, starting the workspace if it isn't running",
+				Command:     "coder exec my-workspace -- make -C ~/project test",
+			},
+			example{
+				Description: "Run a command on a specific agent of a workspace",
+				Command:     "coder exec my-workspace.main -- uname -a",
+			},
+		),
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(2, -1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, stop := inv.SignalNotifyContext(inv.Context(), InterruptSignals...)
+			defer stop()
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, !disableAutostart, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return err
+			}
+			wait, err := waitForStartupScripts(waitEnum, workspaceAgent)
+			if err != nil {
+				return err
+			}
+			err = cliui.Agent(ctx, inv.Stderr, workspaceAgent.ID, cliui.AgentOptions{
+				Fetch:     client.WorkspaceAgent,
+				FetchLogs: client.WorkspaceAgentLogsAfter,
+				Wait:      wait,
+			})
+			if err != nil {
+				if xerrors.Is(err, context.Canceled) {
+					return cliui.Canceled
+				}
+				return err
+			}
+
+			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
+				Logger:         inv.Logger,
+				BlockEndpoints: r.disableDirect,
+				ConnectionType: codersdk.ConnectionTypeSSH,
+			})
+			if err != nil {
+				return xerrors.Errorf("dial agent: %w", err)
+			}
+			defer conn.Close()
+			conn.AwaitReachable(ctx)
+
+			stopPolling := tryPollWorkspaceAutostop(ctx, client, workspace)
+			defer stopPolling()
+
+			sshClient, err := conn.SSHClient(ctx)
+			if err != nil {
+				return xerrors.Errorf("ssh client: %w", err)
+			}
+			defer sshClient.Close()
+			sshSession, err := sshClient.NewSession()
+			if err != nil {
+				return xerrors.Errorf("ssh session: %w", err)
+			}
+			defer sshSession.Close()
+
+			sshSession.Stdin = inv.Stdin
+			sshSession.Stdout = inv.Stdout
+			sshSession.Stderr = inv.Stderr
+			err = sshSession.Start(strings.Join(inv.Args[1:], " "))
+			if err != nil {
+				return xerrors.Errorf("start command: %w", err)
+			}
+			// Closing the session stops the command when interrupted.
+			go func() {
+				<-ctx.Done()
+				_ = sshSession.Close()
+			}()
+
+			err = sshSession.Wait()
+			if err != nil {
+				if exitErr := (&gossh.ExitError{}); errors.As(err, &exitErr) {
+					return ExitError(exitErr.ExitStatus(), nil)
+				}
+				if ctx.Err() != nil {
+					return cliui.Canceled
+				}
+				if errors.Is(err, &gossh.ExitMissingError{}) {
+					return ExitError(255, xerrors.New("SSH connection ended unexpectedly"))
+				}
+				return xerrors.Errorf("session ended: %w", err)
+			}
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "wait",
+			Env:         "CODER_EXEC_WAIT",
+			Description: "Specifies whether or not to wait for the startup scripts to finish executing before running the command. Auto means that the agent startup script behavior configured in the workspace template is used.",
+			Default:     "auto",
+			Value:       clibase.EnumOf(&waitEnum, "yes", "no", "auto"),
+		},
+		{
+			Flag:        "disable-autostart",
+			Env:         "CODER_EXEC_DISABLE_AUTOSTART",
+			Description: "Disable starting the workspace automatically to run the command.",
+			Default:     "false",
+			Value:       clibase.BoolOf(&disableAutostart),
+		},
+	}
+	return cmd
+}
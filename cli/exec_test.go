@@ -0,0 +1,62 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Output", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+
+		inv, root := clitest.New(t, "exec", workspace.Name, "--", "echo", "hello")
+		clitest.SetupConfig(t, client, root)
+		stdout := new(bytes.Buffer)
+		inv.Stdout = stdout
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		err := inv.WithContext(ctx).Run()
+		require.NoError(t, err)
+		require.Equal(t, "hello", strings.TrimSpace(stdout.String()))
+	})
+
+	t.Run("ExitCode", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, agentToken := setupWorkspaceForAgent(t)
+		_ = agenttest.New(t, client.URL, agentToken)
+
+		inv, root := clitest.New(t, "exec", workspace.Name, "--", "exit", "3")
+		clitest.SetupConfig(t, client, root)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		err := inv.WithContext(ctx).Run()
+		require.ErrorContains(t, err, "exit code 3")
+	})
+
+	t.Run("RequiresCommand", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, _ := setupWorkspaceForAgent(t)
+		inv, root := clitest.New(t, "exec", workspace.Name)
+		clitest.SetupConfig(t, client, root)
+
+		err := inv.Run()
+		require.ErrorContains(t, err, "wanted at least 2 args")
+	})
+}
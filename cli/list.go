@@ -34,6 +34,7 @@ type workspaceListRow struct {
 	StopsAfter     string `json:"-" table:"stops after"`
 	StopsNext      string `json:"-" table:"stops next"`
 	DailyCost      string `json:"-" table:"daily cost"`
+	Favorite       bool   `json:"-" table:"favorite"`
 }
 
 func workspaceListRowFromWorkspace(now time.Time, workspace codersdk.Workspace) workspaceListRow {
@@ -60,12 +61,14 @@ func workspaceListRowFromWorkspace(now time.Time, workspace codersdk.Workspace)
 		StopsAfter:     schedRow.StopsAfter,
 		StopsNext:      schedRow.StopsNext,
 		DailyCost:      strconv.Itoa(int(workspace.LatestBuild.DailyCost)),
+		Favorite:       workspace.Favorite,
 	}
 }
 
 func (r *RootCmd) list() *clibase.Cmd {
 	var (
 		filter    cliui.WorkspaceFilter
+		watch     bool
 		formatter = cliui.NewOutputFormatter(
 			cliui.TableFormat(
 				[]workspaceListRow{},
@@ -82,6 +85,7 @@ func (r *RootCmd) list() *clibase.Cmd {
 				},
 			),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 		)
 	)
 	client := new(codersdk.Client)
@@ -108,6 +112,21 @@ func (r *RootCmd) list() *clibase.Cmd {
 				return nil
 			}
 
+			if watch {
+				workspaces := make([]codersdk.Workspace, 0, len(res))
+				for _, row := range res {
+					workspaces = append(workspaces, row.Workspace)
+				}
+				return watchWorkspaces(inv, client, workspaces, func(workspaces []codersdk.Workspace) (string, error) {
+					now := time.Now()
+					rows := make([]workspaceListRow, 0, len(workspaces))
+					for _, workspace := range workspaces {
+						rows = append(rows, workspaceListRowFromWorkspace(now, workspace))
+					}
+					return formatter.Format(inv.Context(), rows)
+				})
+			}
+
 			out, err := formatter.Format(inv.Context(), res)
 			if err != nil {
 				return err
@@ -116,6 +135,13 @@ func (r *RootCmd) list() *clibase.Cmd {
 			_, err = fmt.Fprintln(inv.Stdout, out)
 			return err
 		},
+		Options: clibase.OptionSet{
+			{
+				Flag:        "watch",
+				Description: "Watch the listed workspaces and print them again every time one of them changes. Workspaces created after the command started aren't included.",
+				Value:       clibase.BoolOf(&watch),
+			},
+		},
 	}
 	filter.AttachOptions(&cmd.Options)
 	formatter.AttachOptions(&cmd.Options)
@@ -139,3 +165,84 @@ func queryConvertWorkspaces[T any](ctx context.Context, client *codersdk.Client,
 	}
 	return converted, nil
 }
+
+// watchWorkspaces prints the output of render for the given workspaces, and
+// prints it again every time one of them changes until the command is
+// interrupted. When writing to a terminal the screen is cleared first, so the
+// output is updated in place.
+func watchWorkspaces(inv *clibase.Invocation, client *codersdk.Client, workspaces []codersdk.Workspace, render func([]codersdk.Workspace) (string, error)) error {
+	ctx, stop := inv.SignalNotifyContext(inv.Context(), InterruptSignals...)
+	defer stop()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type workspaceUpdate struct {
+		index     int
+		workspace codersdk.Workspace
+		closed    bool
+	}
+	updates := make(chan workspaceUpdate)
+	for i, workspace := range workspaces {
+		watch, err := client.WatchWorkspace(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("watch workspace %q: %w", workspace.Name, err)
+		}
+		go func(index int, watch <-chan codersdk.Workspace) {
+			for updated := range watch {
+				select {
+				case <-ctx.Done():
+					return
+				case updates <- workspaceUpdate{index: index, workspace: updated}:
+				}
+			}
+			select {
+			case <-ctx.Done():
+			case updates <- workspaceUpdate{index: index, closed: true}:
+			}
+		}(i, watch)
+	}
+
+	apply := func(update workspaceUpdate) error {
+		if update.closed {
+			return xerrors.Errorf("watch workspace %q: connection closed", workspaces[update.index].Name)
+		}
+		workspaces[update.index] = update.workspace
+		return nil
+	}
+	for {
+		out, err := render(workspaces)
+		if err != nil {
+			return err
+		}
+		if isTTYOut(inv) {
+			_, _ = fmt.Fprint(inv.Stdout, "\033[H\033[2J")
+		}
+		_, err = fmt.Fprintln(inv.Stdout, out)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case update := <-updates:
+			err = apply(update)
+			if err != nil {
+				return err
+			}
+		}
+		// Apply the updates that arrived together before printing again.
+	drain:
+		for {
+			select {
+			case update := <-updates:
+				err = apply(update)
+				if err != nil {
+					return err
+				}
+			default:
+				break drain
+			}
+		}
+	}
+}
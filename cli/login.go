@@ -331,6 +331,19 @@ func (r *RootCmd) login() *clibase.Cmd {
 			}
 
 			_, _ = fmt.Fprintf(inv.Stdout, Caret+"Welcome to Coder, %s! You're authenticated.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, resp.Username))
+
+			// Older deployments don't have an inbox, so errors are ignored.
+			inbox, err := client.InboxNotifications(ctx, codersdk.Me, codersdk.InboxNotificationsRequest{
+				UnreadOnly: true,
+				Pagination: codersdk.Pagination{Limit: 1},
+			})
+			if err == nil && inbox.UnreadCount > 0 {
+				noun := "notifications"
+				if inbox.UnreadCount == 1 {
+					noun = "notification"
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, Caret+"You have %d unread %s. View them at %s\n", inbox.UnreadCount, noun, serverURL.String())
+			}
 			return nil
 		},
 	}
@@ -243,6 +243,20 @@ func TestLogin(t *testing.T) {
 		<-doneChan
 	})
 
+	t.Run("UnreadNotifications", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		// Creating the account adds a notification to the inbox of the member.
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		root, _ := clitest.New(t, "login", client.URL.String(), "--token", member.SessionToken())
+		pty := ptytest.New(t).Attach(root)
+		clitest.Start(t, root)
+
+		pty.ExpectMatch("Welcome to Coder")
+		pty.ExpectMatch("You have 1 unread notification.")
+	})
+
 	// TokenFlag should generate a new session token and store it in the session file.
 	t.Run("TokenFlag", func(t *testing.T) {
 		t.Parallel()
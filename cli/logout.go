@@ -60,6 +60,12 @@ func (r *RootCmd) logout() *clibase.Cmd {
 				errors = append(errors, xerrors.Errorf("remove organization file: %w", err))
 			}
 
+			err = config.Region().Delete()
+			// The region preference belongs to the logged in user
+			if err != nil && !os.IsNotExist(err) {
+				errors = append(errors, xerrors.Errorf("remove region file: %w", err))
+			}
+
 			if len(errors) > 0 {
 				var errorStringBuilder strings.Builder
 				for _, err := range errors {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -25,6 +26,7 @@ func (r *RootCmd) open() *clibase.Cmd {
 			return inv.Command.HelpHandler(inv)
 		},
 		Children: []*clibase.Cmd{
+			r.openApp(),
 			r.openVSCode(),
 		},
 	}
@@ -208,6 +210,134 @@ func (r *RootCmd) openVSCode() *clibase.Cmd {
 	return cmd
 }
 
+func (r *RootCmd) openApp() *clibase.Cmd {
+	var (
+		regionName    string
+		testOpenError bool
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "app <workspace> <app slug>",
+		Short:       "Open a workspace application",
+		Long: "The app is opened through the region selected with \"coder regions use\", " +
+			"or the region with the lowest latency from this machine if none is selected.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx, cancel := context.WithCancel(inv.Context())
+			defer cancel()
+
+			workspace, workspaceAgent, err := getWorkspaceAndAgent(ctx, inv, client, true, codersdk.Me, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace and agent: %w", err)
+			}
+
+			var app codersdk.WorkspaceApp
+			slugs := make([]string, 0, len(workspaceAgent.Apps))
+			for _, a := range workspaceAgent.Apps {
+				if a.Slug == inv.Args[1] {
+					app = a
+				}
+				slugs = append(slugs, a.Slug)
+			}
+			if app.Slug == "" {
+				return xerrors.Errorf("app %q not found in %s.%s, available apps: %s", inv.Args[1], workspace.Name, workspaceAgent.Name, strings.Join(slugs, ", "))
+			}
+
+			var appURL string
+			if app.External {
+				appURL = app.URL
+			} else {
+				regions, err := client.Regions(ctx)
+				if err != nil {
+					return xerrors.Errorf("list regions: %w", err)
+				}
+				var region codersdk.Region
+				if regionName != "" {
+					var ok bool
+					region, ok = findRegion(regions, regionName)
+					if !ok {
+						return xerrors.Errorf("region %q not found", regionName)
+					}
+				} else {
+					region, err = r.preferredRegion(regions, func() []codersdk.RegionLatency {
+						return codersdk.RegionLatencies(ctx, client.HTTPClient, regions)
+					})
+					if err != nil {
+						return err
+					}
+				}
+				appURL, err = buildAppURL(region, workspace, workspaceAgent, app)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !testOpenError {
+				err = open.Run(appURL)
+			} else {
+				err = xerrors.New("test.open-error")
+			}
+			if err != nil {
+				_, _ = fmt.Fprintf(inv.Stderr, "Could not automatically open %s: %s\n", app.Slug, err)
+				_, _ = fmt.Fprintf(inv.Stderr, "Please open the following URL instead:\n\n")
+			} else {
+				_, _ = fmt.Fprintf(inv.Stderr, "Opening %s\n", app.Slug)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "%s\n", appURL)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "region",
+			Env:         "CODER_OPEN_APP_REGION",
+			Description: "Region to open the app through. Defaults to the region selected with \"coder regions use\", or the region with the lowest latency.",
+			Value:       clibase.StringOf(&regionName),
+		},
+		{
+			Flag:        "test.open-error",
+			Description: "Don't run the open command.",
+			Value:       clibase.BoolOf(&testOpenError),
+			Hidden:      true, // This is for testing!
+		},
+	}
+
+	return cmd
+}
+
+// buildAppURL returns the URL of an app served by the given region.
+func buildAppURL(region codersdk.Region, workspace codersdk.Workspace, agent codersdk.WorkspaceAgent, app codersdk.WorkspaceApp) (string, error) {
+	baseURL, err := url.Parse(region.PathAppURL)
+	if err != nil {
+		return "", xerrors.Errorf("parse region %q url: %w", region.Name, err)
+	}
+	if app.Subdomain {
+		if region.WildcardHostname == "" {
+			return "", xerrors.Errorf("region %q does not serve subdomain apps", region.Name)
+		}
+		appHost := appurl.ApplicationURL{
+			AppSlugOrPort: app.Slug,
+			AgentName:     agent.Name,
+			WorkspaceName: workspace.Name,
+			Username:      workspace.OwnerName,
+		}
+		u := &url.URL{
+			Scheme: baseURL.Scheme,
+			Host:   strings.Replace(appurl.SubdomainAppHost(region.WildcardHostname, baseURL), "*", appHost.String(), 1),
+			Path:   "/",
+		}
+		return u.String(), nil
+	}
+	u := baseURL.JoinPath(fmt.Sprintf("@%s", workspace.OwnerName), fmt.Sprintf("%s.%s", workspace.Name, agent.Name), "apps", app.Slug)
+	return u.String() + "/", nil
+}
+
 // waitForAgentCond uses the watch workspace API to update the agent information
 // until the condition is met.
 func waitForAgentCond(ctx context.Context, client *codersdk.Client, workspace codersdk.Workspace, workspaceAgent codersdk.WorkspaceAgent, cond func(codersdk.WorkspaceAgent) bool) (codersdk.Workspace, codersdk.WorkspaceAgent, error) {
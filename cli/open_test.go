@@ -283,3 +283,59 @@ func TestOpenVSCode_NoAgentDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenApp(t *testing.T) {
+	t.Parallel()
+
+	agentName := "agent1"
+	client, workspace, _ := setupWorkspaceForAgent(t, func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = agentName
+		agents[0].Apps = []*proto.App{
+			{
+				Slug: "code-server",
+				Url:  "http://localhost:13337",
+			},
+		}
+		return agents
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "open", "app", "--test.open-error", workspace.Name, "code-server")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t)
+		inv.Stdout = pty.Output()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		w := clitest.StartWithWaiter(t, inv.WithContext(ctx))
+
+		me, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+
+		line := pty.ReadLine(ctx)
+		want := client.URL.JoinPath("@"+me.Username, workspace.Name+"."+agentName, "apps", "code-server").String() + "/"
+		assert.Equal(t, want, line)
+		w.RequireSuccess()
+	})
+
+	t.Run("UnknownApp", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "open", "app", "--test.open-error", workspace.Name, "unknown")
+		clitest.SetupConfig(t, client, root)
+
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.ErrorContains(t, err, "available apps: code-server")
+	})
+
+	t.Run("UnknownRegion", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "open", "app", "--test.open-error", "--region", "mars", workspace.Name, "code-server")
+		clitest.SetupConfig(t, client, root)
+
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.ErrorContains(t, err, `region "mars" not found`)
+	})
+}
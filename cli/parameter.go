@@ -97,6 +97,35 @@ func parseParameterMapFile(parameterFile string) (map[string]string, error) {
 		return nil, err
 	}
 
+	return parameterMapValues(mapStringInterface)
+}
+
+// parseParameterMatrixFile parses a YAML list of parameter maps. Each map is
+// one combination of parameter values a template version is tested with.
+func parseParameterMatrixFile(matrixFile string) ([]map[string]string, error) {
+	matrixFileContents, err := os.ReadFile(matrixFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var combinations []map[string]interface{}
+	err = yaml.Unmarshal(matrixFileContents, &combinations)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := make([]map[string]string, 0, len(combinations))
+	for i, combination := range combinations {
+		parameterMap, err := parameterMapValues(combination)
+		if err != nil {
+			return nil, xerrors.Errorf("combination %d: %w", i+1, err)
+		}
+		matrix = append(matrix, parameterMap)
+	}
+	return matrix, nil
+}
+
+func parameterMapValues(mapStringInterface map[string]interface{}) (map[string]string, error) {
 	parameterMap := map[string]string{}
 	for k, v := range mapStringInterface {
 		switch val := v.(type) {
@@ -58,6 +58,39 @@ func TestCreateParameterMapFromFile(t *testing.T) {
 	})
 }
 
+func TestParseParameterMatrixFile(t *testing.T) {
+	t.Parallel()
+	t.Run("Combinations", func(t *testing.T) {
+		t.Parallel()
+		tempDir := t.TempDir()
+		matrixFile, _ := os.CreateTemp(tempDir, "testMatrixFile*.yaml")
+		_, _ = matrixFile.WriteString("- region: eu\n  disk: 20\n- region: us\n  gpu: true\n")
+
+		matrix, err := parseParameterMatrixFile(matrixFile.Name())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []map[string]string{
+			{"region": "eu", "disk": "20"},
+			{"region": "us", "gpu": "true"},
+		}, matrix)
+
+		removeTmpDirUntilSuccess(t, tempDir)
+	})
+	t.Run("WithInvalidValue", func(t *testing.T) {
+		t.Parallel()
+		tempDir := t.TempDir()
+		matrixFile, _ := os.CreateTemp(tempDir, "testMatrixFile*.yaml")
+		_, _ = matrixFile.WriteString("- region: eu\n- region:\n    name: us\n")
+
+		matrix, err := parseParameterMatrixFile(matrixFile.Name())
+
+		assert.Nil(t, matrix)
+		assert.EqualError(t, err, "combination 2: invalid parameter type: map[string]interface {}")
+
+		removeTmpDirUntilSuccess(t, tempDir)
+	})
+}
+
 // Need this for Windows because of a known issue with Go:
 // https://github.com/golang/go/issues/52986
 func removeTmpDirUntilSuccess(t *testing.T, tempDir string) {
@@ -25,6 +25,7 @@ const (
 type ParameterResolver struct {
 	lastBuildParameters       []codersdk.WorkspaceBuildParameter
 	sourceWorkspaceParameters []codersdk.WorkspaceBuildParameter
+	presetParameters          []codersdk.WorkspaceBuildParameter
 
 	richParameters     []codersdk.WorkspaceBuildParameter
 	richParametersFile map[string]string
@@ -44,6 +45,11 @@ func (pr *ParameterResolver) WithSourceWorkspaceParameters(params []codersdk.Wor
 	return pr
 }
 
+func (pr *ParameterResolver) WithPresetParameters(params []codersdk.WorkspaceBuildParameter) *ParameterResolver {
+	pr.presetParameters = params
+	return pr
+}
+
 func (pr *ParameterResolver) WithRichParameters(params []codersdk.WorkspaceBuildParameter) *ParameterResolver {
 	pr.richParameters = params
 	return pr
@@ -75,6 +81,7 @@ func (pr *ParameterResolver) Resolve(inv *clibase.Invocation, action WorkspaceCL
 
 	staged = pr.resolveWithParametersMapFile(staged)
 	staged = pr.resolveWithCommandLineOrEnv(staged)
+	staged = pr.resolveWithPresetParameters(staged)
 	staged = pr.resolveWithSourceBuildParameters(staged, templateVersionParameters)
 	staged = pr.resolveWithLastBuildParameters(staged, templateVersionParameters)
 	if err = pr.verifyConstraints(staged, action, templateVersionParameters); err != nil {
@@ -131,6 +138,22 @@ nextBuildOption:
 	return resolved
 }
 
+// resolveWithPresetParameters adds the values of the selected preset, unless
+// they were given explicitly.
+func (pr *ParameterResolver) resolveWithPresetParameters(resolved []codersdk.WorkspaceBuildParameter) []codersdk.WorkspaceBuildParameter {
+next:
+	for _, presetParameter := range pr.presetParameters {
+		for _, r := range resolved {
+			if r.Name == presetParameter.Name {
+				continue next
+			}
+		}
+
+		resolved = append(resolved, presetParameter)
+	}
+	return resolved
+}
+
 func (pr *ParameterResolver) resolveWithLastBuildParameters(resolved []codersdk.WorkspaceBuildParameter, templateVersionParameters []codersdk.TemplateVersionParameter) []codersdk.WorkspaceBuildParameter {
 	if pr.promptRichParameters {
 		return resolved // don't pull parameters from last build
@@ -217,6 +240,16 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 		}
 		// Parameter has not been resolved yet, so CLI needs to determine if user should input it.
 
+		// Hidden parameters get their default value during the build, so
+		// there's no point in asking for them.
+		displayed, err := codersdk.ParameterDisplayed(tvp, parameterValues(resolved, templateVersionParameters))
+		if err != nil {
+			return nil, err
+		}
+		if !displayed {
+			continue
+		}
+
 		firstTimeUse := pr.isFirstTimeUse(tvp.Name)
 		promptParameterOption := pr.isLastBuildParameterInvalidOption(tvp)
 
@@ -243,6 +276,19 @@ func (pr *ParameterResolver) resolveWithInput(resolved []codersdk.WorkspaceBuild
 	return resolved, nil
 }
 
+// parameterValues returns the values of the parameters resolved so far, falling
+// back to the default values of the template version parameters.
+func parameterValues(resolved []codersdk.WorkspaceBuildParameter, templateVersionParameters []codersdk.TemplateVersionParameter) map[string]string {
+	values := make(map[string]string, len(templateVersionParameters))
+	for _, tvp := range templateVersionParameters {
+		values[tvp.Name] = tvp.DefaultValue
+	}
+	for _, r := range resolved {
+		values[r.Name] = r.Value
+	}
+	return values
+}
+
 func (pr *ParameterResolver) isFirstTimeUse(parameterName string) bool {
 	return findWorkspaceBuildParameter(parameterName, pr.lastBuildParameters) == nil
 }
@@ -3,9 +3,14 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/sloghuman"
@@ -14,6 +19,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -22,6 +28,21 @@ func (r *RootCmd) ping() *clibase.Cmd {
 		pingNum     int64
 		pingTimeout time.Duration
 		pingWait    time.Duration
+		diagnose    bool
+
+		workspaceName string
+		derpMap       *tailcfg.DERPMap
+	)
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			stats, ok := data.(codersdk.WorkspaceAgentPingStats)
+			if !ok {
+				return nil, xerrors.Errorf("expected type %T, got %T", stats, data)
+			}
+			return formatPingStats(workspaceName, derpMap, stats), nil
+		}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 
 	client := new(codersdk.Client)
@@ -37,7 +58,7 @@ func (r *RootCmd) ping() *clibase.Cmd {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
 
-			workspaceName := inv.Args[0]
+			workspaceName = inv.Args[0]
 			_, workspaceAgent, err := getWorkspaceAndAgent(
 				ctx, inv, client,
 				false, // Do not autostart for a ping.
@@ -64,15 +85,36 @@ func (r *RootCmd) ping() *clibase.Cmd {
 			}
 			defer conn.Close()
 
-			derpMap := conn.DERPMap()
-			_ = derpMap
+			derpMap = conn.DERPMap()
+
+			// The statistics are the only output written to stdout in the
+			// machine readable formats.
+			out := inv.Stdout
+			if formatter.FormatID() != "text" {
+				out = inv.Stderr
+			}
+
+			if diagnose {
+				_, _ = fmt.Fprint(inv.Stderr, "Running network diagnostics in the workspace. This may take a few seconds...\n\n")
+				netcheck, err := client.WorkspaceAgentNetcheck(ctx, workspaceAgent.ID)
+				if err != nil {
+					return xerrors.Errorf("run netcheck in workspace: %w", err)
+				}
+				writeAgentNetcheck(out, derpMap, netcheck)
+			}
 
+			var stats codersdk.WorkspaceAgentPingStats
 			n := 0
 			didP2p := false
 			start := time.Now()
+		pingLoop:
 			for {
 				if n > 0 {
-					time.Sleep(time.Second)
+					select {
+					case <-ctx.Done():
+						break pingLoop
+					case <-time.After(pingWait):
+					}
 				}
 				n++
 
@@ -81,32 +123,35 @@ func (r *RootCmd) ping() *clibase.Cmd {
 				cancel()
 				if err != nil {
 					if xerrors.Is(err, context.DeadlineExceeded) {
-						_, _ = fmt.Fprintf(inv.Stdout, "ping to %q timed out \n", workspaceName)
+						stats.AddPing(0, false, nil)
+						_, _ = fmt.Fprintf(out, "ping to %q timed out \n", workspaceName)
 						if n == int(pingNum) {
-							return nil
+							break pingLoop
 						}
 						continue
 					}
 					if xerrors.Is(err, context.Canceled) {
-						return nil
+						break pingLoop
 					}
 
 					if err.Error() == "no matching peer" {
 						continue
 					}
 
-					_, _ = fmt.Fprintf(inv.Stdout, "ping to %q failed %s\n", workspaceName, err.Error())
+					stats.AddPing(0, false, nil)
+					_, _ = fmt.Fprintf(out, "ping to %q failed %s\n", workspaceName, err.Error())
 					if n == int(pingNum) {
-						return nil
+						break pingLoop
 					}
 					continue
 				}
+				stats.AddPing(dur, p2p, pong)
 
 				dur = dur.Round(time.Millisecond)
 				var via string
 				if p2p {
 					if !didP2p {
-						_, _ = fmt.Fprintln(inv.Stdout, "p2p connection established in",
+						_, _ = fmt.Fprintln(out, "p2p connection established in",
 							pretty.Sprint(cliui.DefaultStyles.DateTimeStamp, time.Since(start).Round(time.Millisecond).String()),
 						)
 					}
@@ -117,27 +162,30 @@ func (r *RootCmd) ping() *clibase.Cmd {
 						pretty.Sprint(cliui.DefaultStyles.Code, pong.Endpoint),
 					)
 				} else {
-					derpName := "unknown"
-					derpRegion, ok := derpMap.Regions[pong.DERPRegionID]
-					if ok {
-						derpName = derpRegion.RegionName
-					}
 					via = fmt.Sprintf("%s via %s",
 						pretty.Sprint(cliui.DefaultStyles.Fuchsia, "proxied"),
-						pretty.Sprint(cliui.DefaultStyles.Code, fmt.Sprintf("DERP(%s)", derpName)),
+						pretty.Sprint(cliui.DefaultStyles.Code, fmt.Sprintf("DERP(%s)", derpRegionName(derpMap, pong.DERPRegionID))),
 					)
 				}
 
-				_, _ = fmt.Fprintf(inv.Stdout, "pong from %s %s in %s\n",
+				_, _ = fmt.Fprintf(out, "pong from %s %s in %s\n",
 					pretty.Sprint(cliui.DefaultStyles.Keyword, workspaceName),
 					via,
 					pretty.Sprint(cliui.DefaultStyles.DateTimeStamp, dur.String()),
 				)
 
 				if n == int(pingNum) {
-					return nil
+					break pingLoop
 				}
 			}
+
+			stats.DERPLatencies = conn.DERPLatencies()
+			output, err := formatter.Format(inv.Context(), stats)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, output)
+			return nil
 		},
 	}
 
@@ -159,9 +207,95 @@ func (r *RootCmd) ping() *clibase.Cmd {
 			Flag:          "num",
 			FlagShorthand: "n",
 			Default:       "10",
-			Description:   "Specifies the number of pings to perform.",
+			Description:   "Specifies the number of pings to perform. Zero pings until interrupted.",
 			Value:         clibase.Int64Of(&pingNum),
 		},
+		{
+			Flag:        "diagnose",
+			Description: "Run a netcheck from inside the workspace before pinging, to report its NAT type, UDP reachability and DERP latencies.",
+			Value:       clibase.BoolOf(&diagnose),
+		},
 	}
+	formatter.AttachOptions(&cmd.Options)
 	return cmd
 }
+
+// formatPingStats formats the summary written after the pings, similar to the
+// one of the ping command.
+func formatPingStats(workspaceName string, derpMap *tailcfg.DERPMap, stats codersdk.WorkspaceAgentPingStats) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "\n--- %s ping statistics ---\n", workspaceName)
+	_, _ = fmt.Fprintf(&sb, "%d pings sent, %d received, %.1f%% packet loss\n", stats.Sent, stats.Received, stats.PacketLoss)
+	if stats.Received > 0 {
+		_, _ = fmt.Fprintf(&sb, "latency min/avg/max = %.1f/%.1f/%.1f ms\n", stats.MinLatencyMS, stats.AvgLatencyMS, stats.MaxLatencyMS)
+		if stats.P2P {
+			_, _ = fmt.Fprintf(&sb, "path: p2p via %s\n", stats.Endpoint)
+		} else {
+			_, _ = fmt.Fprintf(&sb, "path: proxied via DERP(%s)\n", derpRegionName(derpMap, stats.DERPRegionID))
+		}
+	}
+	if len(stats.DERPLatencies) > 0 {
+		_, _ = fmt.Fprintln(&sb, "DERP latency:")
+	}
+	for _, latency := range stats.DERPLatencies {
+		name := latency.RegionName
+		if name == "" {
+			name = fmt.Sprintf("unknown (%d)", latency.RegionID)
+		}
+		_, _ = fmt.Fprintf(&sb, "  %s: %.1f ms\n", name, latency.LatencyMS)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func derpRegionName(derpMap *tailcfg.DERPMap, regionID int) string {
+	if derpMap != nil {
+		if region, ok := derpMap.Regions[regionID]; ok {
+			return region.RegionName
+		}
+	}
+	return "unknown"
+}
+
+// writeAgentNetcheck writes a summary of a netcheck that was run by a
+// workspace agent.
+func writeAgentNetcheck(w io.Writer, derpMap *tailcfg.DERPMap, resp codersdk.WorkspaceAgentNetcheckResponse) {
+	_, _ = fmt.Fprintln(w, "Workspace network:")
+	if resp.NetcheckErr != nil {
+		_, _ = fmt.Fprintf(w, "  netcheck failed: %s\n\n", *resp.NetcheckErr)
+		return
+	}
+	report := resp.Netcheck
+	if report == nil {
+		_, _ = fmt.Fprint(w, "  netcheck returned no report\n\n")
+		return
+	}
+
+	nat := "unknown"
+	if varies, ok := report.MappingVariesByDestIP.Get(); ok {
+		nat = "easy"
+		if varies {
+			nat = "hard (direct connections are unlikely)"
+		}
+	}
+	preferred := "none"
+	if report.PreferredDERP != 0 {
+		preferred = derpRegionName(derpMap, report.PreferredDERP)
+	}
+	_, _ = fmt.Fprintf(w, "  UDP: %t\n", report.UDP)
+	_, _ = fmt.Fprintf(w, "  IPv4: %t %s\n", report.IPv4, report.GlobalV4)
+	_, _ = fmt.Fprintf(w, "  IPv6: %t %s\n", report.IPv6, report.GlobalV6)
+	_, _ = fmt.Fprintf(w, "  NAT: %s\n", nat)
+	_, _ = fmt.Fprintf(w, "  Preferred DERP: %s\n", preferred)
+
+	regionIDs := maps.Keys(report.RegionLatency)
+	slices.SortFunc(regionIDs, func(a, b int) int {
+		return slice.Ascending(report.RegionLatency[a], report.RegionLatency[b])
+	})
+	if len(regionIDs) > 0 {
+		_, _ = fmt.Fprintln(w, "  DERP latency:")
+	}
+	for _, id := range regionIDs {
+		_, _ = fmt.Fprintf(w, "    %s: %s\n", derpRegionName(derpMap, id), report.RegionLatency[id].Round(time.Millisecond))
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"net/netip"
 	"os"
 	"os/signal"
@@ -98,6 +99,25 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 				return xerrors.Errorf("await agent: %w", err)
 			}
 
+			// Ask the deployment whether the template allows the remote ports
+			// to be forwarded. Denied attempts are audited.
+			for _, spec := range specs {
+				remote, err := netip.ParseAddrPort(spec.dialAddress)
+				if err != nil {
+					return xerrors.Errorf("parse remote address %q: %w", spec.dialAddress, err)
+				}
+				err = client.AuthorizeWorkspaceAgentPortForward(ctx, workspaceAgent.ID, remote.Port())
+				if err != nil {
+					var sdkErr *codersdk.Error
+					if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+						// Older deployments don't have port forwarding
+						// policies.
+						break
+					}
+					return xerrors.Errorf("port-forward '%v://%v': %w", spec.dialNetwork, spec.dialAddress, err)
+				}
+			}
+
 			logger := inv.Logger
 			if r.verbose {
 				logger = logger.AppendSinks(sloghuman.Sink(inv.Stdout)).Leveled(slog.LevelDebug)
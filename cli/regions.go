@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) regions() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "regions",
+		Short: "Choose the workspace proxy region that apps are opened through",
+		Long: "By default the region with the lowest latency from this machine is used. " +
+			"Selecting a region with \"coder regions use\" overrides this until it is reset.",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.regionsList(),
+			r.regionsUse(),
+			r.regionsReset(),
+		},
+	}
+	return cmd
+}
+
+type regionTableRow struct {
+	Region   codersdk.Region `json:"region" table:"region,recursive_inline"`
+	Latency  string          `json:"latency" table:"latency"`
+	Selected bool            `json:"selected" table:"selected"`
+}
+
+func (r *RootCmd) regionsList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]regionTableRow{}, []string{"name", "url", "healthy", "latency", "selected"}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Measure the latency to every region",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			regions, err := client.Regions(ctx)
+			if err != nil {
+				return xerrors.Errorf("list regions: %w", err)
+			}
+			latencies := codersdk.RegionLatencies(ctx, client.HTTPClient, regions)
+			selected, err := r.preferredRegion(regions, func() []codersdk.RegionLatency {
+				return latencies
+			})
+			if err != nil {
+				return err
+			}
+
+			rows := make([]regionTableRow, 0, len(latencies))
+			for _, l := range latencies {
+				row := regionTableRow{
+					Region:   l.Region,
+					Latency:  "unreachable",
+					Selected: l.Region.ID == selected.ID,
+				}
+				if l.Err == nil {
+					row.Latency = l.Latency.Round(time.Millisecond).String()
+				}
+				rows = append(rows, row)
+			}
+
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) regionsUse() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "use <name>",
+		Short: "Always open apps through a region",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			regions, err := client.Regions(inv.Context())
+			if err != nil {
+				return xerrors.Errorf("list regions: %w", err)
+			}
+			region, ok := findRegion(regions, inv.Args[0])
+			if !ok {
+				return xerrors.Errorf("region %q not found", inv.Args[0])
+			}
+			if !region.Healthy {
+				cliui.Warnf(inv.Stderr, "Region %q is currently unhealthy.", region.Name)
+			}
+
+			err = r.createConfig().Region().Write(region.Name)
+			if err != nil {
+				return xerrors.Errorf("write region: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Apps will be opened through %s.\n", cliui.Keyword(region.Name))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) regionsReset() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "reset",
+		Short: "Open apps through the region with the lowest latency",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			err := r.createConfig().Region().Delete()
+			if err != nil && !os.IsNotExist(err) {
+				return xerrors.Errorf("remove region: %w", err)
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, "Apps will be opened through the region with the lowest latency.")
+			return nil
+		},
+	}
+	return cmd
+}
+
+// preferredRegion returns the region to open apps through. The region selected
+// with "coder regions use" is returned if it exists, otherwise latencies is
+// called to probe every region and the fastest one is returned.
+func (r *RootCmd) preferredRegion(regions []codersdk.Region, latencies func() []codersdk.RegionLatency) (codersdk.Region, error) {
+	if len(regions) == 0 {
+		return codersdk.Region{}, xerrors.New("no regions found")
+	}
+	name, err := r.createConfig().Region().Read()
+	if err == nil {
+		if region, ok := findRegion(regions, name); ok {
+			return region, nil
+		}
+	}
+	// The latencies are sorted, so the first region is the fastest. If no
+	// region could be reached, it is the first region returned by the
+	// server, which is always the primary.
+	return latencies()[0].Region, nil
+}
+
+func findRegion(regions []codersdk.Region, name string) (codersdk.Region, bool) {
+	for _, region := range regions {
+		if strings.EqualFold(region.Name, strings.TrimSpace(name)) {
+			return region, true
+		}
+	}
+	return codersdk.Region{}, false
+}
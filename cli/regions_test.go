@@ -0,0 +1,74 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRegions(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	t.Run("List", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "regions", "list", "--output", "json")
+		clitest.SetupConfig(t, client, root)
+		var stdout bytes.Buffer
+		inv.Stdout = &stdout
+
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.NoError(t, err)
+
+		var rows []struct {
+			Region struct {
+				Name string `json:"name"`
+			} `json:"region"`
+			Latency  string `json:"latency"`
+			Selected bool   `json:"selected"`
+		}
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &rows))
+		require.Len(t, rows, 1)
+		require.Equal(t, "primary", rows[0].Region.Name)
+		require.NotEqual(t, "unreachable", rows[0].Latency)
+		require.True(t, rows[0].Selected)
+	})
+
+	t.Run("UseAndReset", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "regions", "use", "primary")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.NoError(t, err)
+
+		region, err := root.Region().Read()
+		require.NoError(t, err)
+		require.Equal(t, "primary", region)
+
+		inv, _ = clitest.New(t, "regions", "reset", "--global-config", string(root))
+		err = inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.NoError(t, err)
+
+		_, err = root.Region().Read()
+		require.Error(t, err)
+	})
+
+	t.Run("UseUnknown", func(t *testing.T) {
+		t.Parallel()
+
+		inv, root := clitest.New(t, "regions", "use", "mars")
+		clitest.SetupConfig(t, client, root)
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.ErrorContains(t, err, `region "mars" not found`)
+	})
+}
@@ -88,6 +88,7 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.netcheck(),
 		r.portForward(),
 		r.publickey(),
+		r.regions(),
 		r.resetPassword(),
 		r.state(),
 		r.templates(),
@@ -100,13 +101,16 @@ func (r *RootCmd) Core() []*clibase.Cmd {
 		r.configSSH(),
 		r.create(),
 		r.deleteWorkspace(),
+		r.exec(),
 		r.list(),
 		r.open(),
 		r.ping(),
 		r.rename(),
 		r.restart(),
 		r.schedules(),
+		r.share(),
 		r.show(),
+		r.snapshot(),
 		r.speedtest(),
 		r.ssh(),
 		r.start(),
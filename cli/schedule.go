@@ -88,6 +88,7 @@ func (r *RootCmd) scheduleShow() *clibase.Cmd {
 				},
 			),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 		)
 	)
 	client := new(codersdk.Client)
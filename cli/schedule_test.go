@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
@@ -221,13 +222,16 @@ func TestScheduleShow(t *testing.T) {
 		assert.NoError(t, <-errC)
 
 		// Then: they should see all workspace schedules in JSON format
-		var parsed []map[string]string
+		var parsed []map[string]any
 		require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
 		require.Len(t, parsed, 4)
+		for _, row := range parsed {
+			assert.EqualValues(t, cliui.OutputSchemaVersion, row["output_schema_version"])
+		}
 		// Ensure same order as in CLI output
 		sort.Slice(parsed, func(i, j int) bool {
-			a := parsed[i]["workspace"]
-			b := parsed[j]["workspace"]
+			a, _ := parsed[i]["workspace"].(string)
+			b, _ := parsed[j]["workspace"].(string)
 			return a < b
 		})
 		// 1st workspace: a-owner-ws1 has both autostart and autostop enabled.
@@ -68,32 +68,45 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/coderd/database/dbmetrics"
 	"github.com/coder/coder/v2/coderd/database/dbpurge"
+	"github.com/coder/coder/v2/coderd/database/dbreplica"
+	"github.com/coder/coder/v2/coderd/database/dbtrace"
 	"github.com/coder/coder/v2/coderd/database/migrations"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/devtunnel"
 	"github.com/coder/coder/v2/coderd/externalauth"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
+	"github.com/coder/coder/v2/coderd/groupsync"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/ldapauth"
+	"github.com/coder/coder/v2/coderd/logarchive"
+	"github.com/coder/coder/v2/coderd/metricsexport"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics/insights"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/unhanger"
 	"github.com/coder/coder/v2/coderd/updatecheck"
+	"github.com/coder/coder/v2/coderd/useractivity"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	stringutil "github.com/coder/coder/v2/coderd/util/strings"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/workspaceapps/appurl"
+	"github.com/coder/coder/v2/coderd/workspacedrift"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisioner/kubernetes"
+	"github.com/coder/coder/v2/provisioner/pulumi"
 	"github.com/coder/coder/v2/provisioner/terraform"
 	"github.com/coder/coder/v2/provisionerd"
 	"github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/tailnet"
@@ -152,35 +165,56 @@ func createOIDCConfig(ctx context.Context, vals *codersdk.DeploymentValues) (*co
 	if len(vals.OIDC.GroupAllowList) > 0 && vals.OIDC.GroupField == "" {
 		return nil, xerrors.Errorf("'oidc-group-field' must be set if 'oidc-allowed-groups' is set. Either unset 'oidc-allowed-groups' or set 'oidc-group-field'")
 	}
+	if len(vals.OIDC.GroupOrganizationRoleMapping.Value) > 0 && vals.OIDC.GroupField == "" {
+		return nil, xerrors.Errorf("'oidc-group-field' must be set if 'oidc-group-organization-role-mapping' is set. Either unset 'oidc-group-organization-role-mapping' or set 'oidc-group-field'")
+	}
+
+	if vals.OIDC.GroupSyncInterval.Value() > 0 && vals.OIDC.NestedGroupsGraphClientID == "" {
+		return nil, xerrors.Errorf("'oidc-nested-groups-graph-client-id' must be set if 'oidc-group-sync-interval' is set. Either unset 'oidc-group-sync-interval' or configure nested groups")
+	}
 
 	groupAllowList := make(map[string]bool)
 	for _, group := range vals.OIDC.GroupAllowList.Value() {
 		groupAllowList[group] = true
 	}
 
+	var groupDirectory groupsync.Directory
+	if vals.OIDC.NestedGroupsGraphClientID != "" {
+		groupDirectory = groupsync.NewGraphDirectory(ctx, groupsync.GraphOptions{
+			TenantID:     vals.OIDC.NestedGroupsGraphTenantID.String(),
+			ClientID:     vals.OIDC.NestedGroupsGraphClientID.String(),
+			ClientSecret: vals.OIDC.NestedGroupsGraphClientSecret.String(),
+		})
+	}
+
 	return &coderd.OIDCConfig{
 		OAuth2Config: useCfg,
 		Provider:     oidcProvider,
 		Verifier: oidcProvider.Verifier(&oidc.Config{
 			ClientID: vals.OIDC.ClientID.String(),
 		}),
-		EmailDomain:         vals.OIDC.EmailDomain,
-		AllowSignups:        vals.OIDC.AllowSignups.Value(),
-		UsernameField:       vals.OIDC.UsernameField.String(),
-		EmailField:          vals.OIDC.EmailField.String(),
-		AuthURLParams:       vals.OIDC.AuthURLParams.Value,
-		IgnoreUserInfo:      vals.OIDC.IgnoreUserInfo.Value(),
-		GroupField:          vals.OIDC.GroupField.String(),
-		GroupFilter:         vals.OIDC.GroupRegexFilter.Value(),
-		GroupAllowList:      groupAllowList,
-		CreateMissingGroups: vals.OIDC.GroupAutoCreate.Value(),
-		GroupMapping:        vals.OIDC.GroupMapping.Value,
-		UserRoleField:       vals.OIDC.UserRoleField.String(),
-		UserRoleMapping:     vals.OIDC.UserRoleMapping.Value,
-		UserRolesDefault:    vals.OIDC.UserRolesDefault.GetSlice(),
-		SignInText:          vals.OIDC.SignInText.String(),
-		IconURL:             vals.OIDC.IconURL.String(),
-		IgnoreEmailVerified: vals.OIDC.IgnoreEmailVerified.Value(),
+		EmailDomain:                  vals.OIDC.EmailDomain,
+		AllowSignups:                 vals.OIDC.AllowSignups.Value(),
+		UsernameField:                vals.OIDC.UsernameField.String(),
+		EmailField:                   vals.OIDC.EmailField.String(),
+		AuthURLParams:                vals.OIDC.AuthURLParams.Value,
+		IgnoreUserInfo:               vals.OIDC.IgnoreUserInfo.Value(),
+		GroupField:                   vals.OIDC.GroupField.String(),
+		GroupFilter:                  vals.OIDC.GroupRegexFilter.Value(),
+		GroupAllowList:               groupAllowList,
+		CreateMissingGroups:          vals.OIDC.GroupAutoCreate.Value(),
+		GroupMapping:                 vals.OIDC.GroupMapping.Value,
+		UserRoleField:                vals.OIDC.UserRoleField.String(),
+		UserRoleMapping:              vals.OIDC.UserRoleMapping.Value,
+		UserRolesDefault:             vals.OIDC.UserRolesDefault.GetSlice(),
+		GroupOrganizationRoleMapping: vals.OIDC.GroupOrganizationRoleMapping.Value,
+		GroupPrefixStrip:             vals.OIDC.GroupPrefixStrip.Value(),
+		GroupDirectory:               groupDirectory,
+		GroupNestingDepth:            int(vals.OIDC.NestedGroupsMaxDepth.Value()),
+		GroupSyncInterval:            vals.OIDC.GroupSyncInterval.Value(),
+		SignInText:                   vals.OIDC.SignInText.String(),
+		IconURL:                      vals.OIDC.IconURL.String(),
+		IgnoreEmailVerified:          vals.OIDC.IgnoreEmailVerified.Value(),
 	}, nil
 }
 
@@ -212,6 +246,18 @@ func enablePrometheus(
 	}
 	afterCtx(ctx, closeWorkspacesFunc)
 
+	closeWorkspaceCountsFunc, err := prometheusmetrics.WorkspaceCounts(ctx, logger, options.PrometheusRegistry, options.Database, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("register workspace counts prometheus metric: %w", err)
+	}
+	afterCtx(ctx, closeWorkspaceCountsFunc)
+
+	closeProvisionerDaemonsFunc, err := prometheusmetrics.ProvisionerDaemons(ctx, logger, options.PrometheusRegistry, options.Database, unhanger.StaleDaemonDuration, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("register provisioner daemons prometheus metric: %w", err)
+	}
+	afterCtx(ctx, closeProvisionerDaemonsFunc)
+
 	insightsMetricsCollector, err := insights.NewMetricsCollector(options.Database, options.Logger, 0, 0)
 	if err != nil {
 		return nil, xerrors.Errorf("unable to initialize insights metrics collector: %w", err)
@@ -234,6 +280,12 @@ func enablePrometheus(
 		}
 		afterCtx(ctx, closeAgentStatsFunc)
 
+		closeAgentResourceUsageFunc, err := prometheusmetrics.AgentResourceUsage(ctx, logger, options.PrometheusRegistry, options.Database, time.Now(), 0)
+		if err != nil {
+			return nil, xerrors.Errorf("register agent resource usage prometheus metric: %w", err)
+		}
+		afterCtx(ctx, closeAgentResourceUsageFunc)
+
 		metricsAggregator, err := prometheusmetrics.NewMetricsAggregator(logger, options.PrometheusRegistry, 0)
 		if err != nil {
 			return nil, xerrors.Errorf("can't initialize metrics aggregator: %w", err)
@@ -317,10 +369,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			// was specified.
 			loginRateLimit := 60
 			filesRateLimit := 12
+			apiRateLimitOverrides, err := httpmw.ParseRateLimitOverrides(vals.RateLimit.Endpoints.Value(), vals.RateLimit.Users.Value())
+			if err != nil {
+				return xerrors.Errorf("parse rate limit overrides: %w", err)
+			}
 			if vals.RateLimit.DisableAll {
 				vals.RateLimit.API = -1
 				loginRateLimit = -1
 				filesRateLimit = -1
+				apiRateLimitOverrides = httpmw.RateLimitOverrides{}
 			}
 
 			PrintLogo(inv, "Coder")
@@ -578,6 +635,8 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				APIRateLimit:                int(vals.RateLimit.API.Value()),
 				LoginRateLimit:              loginRateLimit,
 				FilesRateLimit:              filesRateLimit,
+				APIRateLimitOverrides:       apiRateLimitOverrides,
+				SharedRateLimits:            vals.RateLimit.Shared.Value(),
 				HTTPClient:                  httpClient,
 				TemplateScheduleStore:       &atomic.Pointer[schedule.TemplateScheduleStore]{},
 				UserQuietHoursScheduleStore: &atomic.Pointer[schedule.UserQuietHoursScheduleStore]{},
@@ -591,6 +650,17 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
 
+			if vals.AgentQUICAddress.String() != "" {
+				if httpServers.TLSConfig == nil {
+					return xerrors.New("agent QUIC address requires TLS to be enabled")
+				}
+				options.AgentQUICListener, err = coderd.ListenAgentQUIC(vals.AgentQUICAddress.String(), httpServers.TLSConfig)
+				if err != nil {
+					return xerrors.Errorf("listen for agent QUIC connections on %q: %w", vals.AgentQUICAddress.String(), err)
+				}
+				defer options.AgentQUICListener.Close()
+			}
+
 			if vals.StrictTransportSecurity > 0 {
 				options.StrictTransportSecurityCfg, err = httpmw.HSTSConfigOptions(
 					int(vals.StrictTransportSecurity.Value()), vals.StrictTransportSecurityOptions,
@@ -619,6 +689,40 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 			}
 
+			if vals.Provisioner.SharedCache.Value() {
+				cacheOpts := tfcache.Options{
+					Dir:        filepath.Join(cacheDir, "shared-cache"),
+					Registerer: options.PrometheusRegistry,
+				}
+				if bucket := vals.Provisioner.SharedCacheS3Bucket.Value(); bucket != "" {
+					cacheOpts.S3 = &tfcache.S3StoreOptions{Bucket: bucket}
+				}
+				options.ProvisionerCache, err = tfcache.New(ctx, cacheOpts)
+				if err != nil {
+					return xerrors.Errorf("create provisioner shared cache: %w", err)
+				}
+			}
+
+			if storageURL := vals.SessionRecordingStorageURL.String(); storageURL != "" {
+				options.SessionRecordingStore, err = sessionrecording.NewStore(ctx, storageURL, httpClient)
+				if err != nil {
+					return xerrors.Errorf("create session recording store: %w", err)
+				}
+			}
+
+			if email := vals.Notifications.Email; email.Smarthost.String() != "" {
+				options.NotificationSender = &notifications.SMTPSender{
+					Smarthost: email.Smarthost.String(),
+					From:      email.From.String(),
+					Username:  email.Username.String(),
+					Password:  email.Password.String(),
+				}
+			}
+			options.NotificationRoutes, err = configureNotificationRoutes(vals.Notifications, httpClient)
+			if err != nil {
+				return xerrors.Errorf("configure notification routes: %w", err)
+			}
+
 			if vals.OAuth2.Github.ClientSecret != "" {
 				options.GithubOAuth2Config, err = configureGithubOAuth2(
 					oauthInstrument,
@@ -654,6 +758,21 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				options.OIDCConfig = oc
 			}
 
+			var ldapAuthenticator *ldapauth.Authenticator
+			if vals.LDAP.URL != "" {
+				ldapAuthenticator, err = createLDAPAuthenticator(vals.LDAP)
+				if err != nil {
+					return xerrors.Errorf("create ldap authenticator: %w", err)
+				}
+				defer ldapAuthenticator.Close()
+				options.LDAPConfig = &coderd.LDAPConfig{
+					Authenticate:        ldapAuthenticator.Authenticate,
+					AllowSignups:        vals.LDAP.AllowSignups.Value(),
+					UsingGroups:         ldapAuthenticator.GroupsEnabled(),
+					CreateMissingGroups: vals.LDAP.GroupAutoCreate.Value(),
+				}
+			}
+
 			if vals.InMemoryDatabase {
 				// This is only used for testing.
 				options.Database = dbmem.New()
@@ -673,7 +792,30 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}()
 
 				options.Database = database.New(sqlDB)
-				options.Pubsub, err = pubsub.New(ctx, sqlDB, dbURL)
+				if len(vals.PostgresReplicaURLs.Value()) > 0 {
+					replicaURLs := make([]string, 0, len(vals.PostgresReplicaURLs.Value()))
+					for _, u := range vals.PostgresReplicaURLs.Value() {
+						replicaURL, err := escapePostgresURLUserInfo(u)
+						if err != nil {
+							return xerrors.Errorf("escaping postgres replica URL: %w", err)
+						}
+						replicaURLs = append(replicaURLs, replicaURL)
+					}
+					// Listings are sent to healthy replicas, everything else
+					// still uses the primary database.
+					replicaStore, err := dbreplica.Open(ctx, sqlDriver, sqlDB, replicaURLs, dbreplica.Options{
+						Logger: logger.Named("dbreplica"),
+						MaxLag: vals.PostgresReplicaMaxLag.Value(),
+					})
+					if err != nil {
+						return xerrors.Errorf("open postgres replicas: %w", err)
+					}
+					defer func() {
+						_ = replicaStore.Close()
+					}()
+					options.Database = replicaStore
+				}
+				options.Pubsub, err = newPubsub(ctx, logger, vals, sqlDB, dbURL)
 				if err != nil {
 					return xerrors.Errorf("create pubsub: %w", err)
 				}
@@ -683,6 +825,9 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if options.DeploymentValues.Prometheus.Enable && options.DeploymentValues.Prometheus.CollectDBMetrics {
 				options.Database = dbmetrics.New(options.Database, options.PrometheusRegistry)
 			}
+			if tracingEnabled(vals) {
+				options.Database = dbtrace.New(options.Database, tracerProvider)
+			}
 
 			var deploymentID string
 			err = options.Database.InTx(func(tx database.Store) error {
@@ -878,6 +1023,26 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 					return xerrors.Errorf("register agents prometheus metric: %w", err)
 				}
 				defer closeAgentsFunc()
+
+				if vals.DERP.Server.Enable {
+					err = prometheusmetrics.DERPServer(options.PrometheusRegistry, coderAPI.DERPServer, int(vals.DERP.Server.RegionID.Value()), vals.DERP.Server.RegionName.String())
+					if err != nil {
+						return xerrors.Errorf("register derp server prometheus metric: %w", err)
+					}
+				}
+			}
+
+			metricsExportSinks, err := configureMetricsExportSinks(vals.MetricsExport, httpClient)
+			if err != nil {
+				return xerrors.Errorf("configure metrics export: %w", err)
+			}
+			if len(metricsExportSinks) > 0 {
+				metricsExporter := metricsexport.New(ctx, logger, options.Database, metricsExportSinks, metricsexport.Options{
+					Interval:   vals.MetricsExport.Interval.Value(),
+					BatchSize:  int(vals.MetricsExport.BatchSize.Value()),
+					Registerer: options.PrometheusRegistry,
+				})
+				defer metricsExporter.Close()
 			}
 
 			client := codersdk.New(localURL)
@@ -943,6 +1108,58 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			purger := dbpurge.New(ctx, logger, options.Database)
 			defer purger.Close()
 
+			// Compresses the logs of old provisioner jobs, since they are
+			// rarely read but take up a lot of space.
+			if vals.Provisioner.JobLogArchiveAge.Value() > 0 {
+				archiver := logarchive.New(ctx, logger.Named("logarchive"), options.Database, logarchive.Options{
+					After:      vals.Provisioner.JobLogArchiveAge.Value(),
+					Registerer: options.PrometheusRegistry,
+				})
+				defer archiver.Close()
+			}
+
+			if vals.SuspendInactiveUsersAfter.Value() > 0 {
+				stopSuspending := useractivity.SuspendInactiveUsers(ctx, logger, options.Database, vals.SuspendInactiveUsersAfter.Value())
+				defer stopSuspending()
+			}
+
+			// Syncs the groups of OIDC users from the nested groups directory,
+			// so changes in the identity provider apply before users log in
+			// again.
+			if oidcCfg := options.OIDCConfig; oidcCfg != nil && oidcCfg.GroupDirectory != nil && oidcCfg.GroupSyncInterval > 0 {
+				stopGroupSync := groupsync.SyncInBackground(ctx, logger, options.Database, groupsync.BackgroundOptions{
+					Config:              oidcCfg.GroupSync(),
+					Interval:            oidcCfg.GroupSyncInterval,
+					CreateMissingGroups: oidcCfg.CreateMissingGroups,
+					SetGroups:           coderAPI.Options.SetUserGroups,
+				})
+				defer stopGroupSync()
+			}
+
+			// Suspends LDAP users whose directory account was deleted or
+			// disabled, so they lose access before their sessions expire.
+			if ldapAuthenticator != nil && vals.LDAP.SyncInterval.Value() > 0 {
+				stopLDAPSync := ldapauth.SyncUserStatus(ctx, logger, options.Database, ldapAuthenticator, &coderAPI.Auditor, vals.LDAP.SyncInterval.Value())
+				defer stopLDAPSync()
+			}
+
+			// Plans running workspaces to find resources that were changed
+			// outside of Coder.
+			if vals.Provisioner.DriftDetectionInterval.Value() > 0 {
+				detector := workspacedrift.New(ctx, logger.Named("workspacedrift"), options.Database, options.Pubsub, workspacedrift.Options{
+					Interval:   vals.Provisioner.DriftDetectionInterval.Value(),
+					Registerer: options.PrometheusRegistry,
+				})
+				defer detector.Close()
+			}
+
+			// Deletes web terminal recordings once they are past the retention
+			// of their template.
+			if options.SessionRecordingStore != nil {
+				recordingPurger := sessionrecording.NewPurger(ctx, logger.Named("sessionrecording"), options.Database, options.SessionRecordingStore)
+				defer recordingPurger.Close()
+			}
+
 			// Wrap the server in middleware that redirects to the access URL if
 			// the request is not to a local IP.
 			var handler http.Handler = coderAPI.RootHandler
@@ -1007,12 +1224,16 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			autobuildTicker := time.NewTicker(vals.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
 			autobuildExecutor := autobuild.NewExecutor(
-				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C)
+				ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, &coderAPI.Auditor, coderAPI.AccessControlStore, logger, autobuildTicker.C).
+				WithWebhooks(coderAPI.Webhooks).
+				WithNotifications(coderAPI.Notifications)
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(vals.JobHangDetectorInterval.Value())
 			defer hangDetectorTicker.Stop()
-			hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, logger, hangDetectorTicker.C)
+			hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, logger, hangDetectorTicker.C).
+				WithAuditor(&coderAPI.Auditor).
+				WithPrometheusRegistry(options.PrometheusRegistry)
 			hangDetector.Start()
 			defer hangDetector.Close()
 
@@ -1181,7 +1402,7 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 	serverCmd.Children = append(
 		serverCmd.Children,
-		createAdminUserCmd, postgresBuiltinURLCmd, postgresBuiltinServeCmd,
+		createAdminUserCmd, r.dbsnapshotCmd(), postgresBuiltinURLCmd, postgresBuiltinServeCmd,
 	)
 
 	return serverCmd
@@ -1314,6 +1535,9 @@ func newProvisionerDaemon(
 	}
 
 	connector := provisionerd.LocalProvisioners{}
+	provisionerTypes := []database.ProvisionerType{
+		database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform,
+	}
 	if cfg.Provisioner.DaemonsEcho {
 		echoClient, echoServer := drpc.MemTransportPipe()
 		wg.Add(1)
@@ -1369,6 +1593,7 @@ func newProvisionerDaemon(
 					WorkDirectory: workDir,
 				},
 				CachePath: tfDir,
+				Cache:     coderAPI.ProvisionerCache,
 				Tracer:    tracer,
 			})
 			if err != nil && !xerrors.Is(err, context.Canceled) {
@@ -1380,12 +1605,99 @@ func newProvisionerDaemon(
 		}()
 
 		connector[string(database.ProvisionerTypeTerraform)] = sdkproto.NewDRPCProvisionerClient(terraformClient)
+
+		// Unlike Terraform, Pulumi is not installed automatically, so its
+		// jobs are only acquired when it is already available.
+		pulumiPath, err := pulumi.BinaryPath(ctx)
+		if err != nil {
+			logger.Debug(ctx, "pulumi provisioner disabled", slog.Error(err))
+		} else {
+			pulumiDir := filepath.Join(cacheDir, "pulumi")
+			err = os.MkdirAll(pulumiDir, 0o700)
+			if err != nil {
+				return nil, xerrors.Errorf("mkdir pulumi dir: %w", err)
+			}
+
+			pulumiClient, pulumiServer := drpc.MemTransportPipe()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-ctx.Done()
+				_ = pulumiClient.Close()
+				_ = pulumiServer.Close()
+			}()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer cancel()
+
+				err := pulumi.Serve(ctx, &pulumi.ServeOptions{
+					ServeOptions: &provisionersdk.ServeOptions{
+						Listener:      pulumiServer,
+						Logger:        logger.Named("pulumi"),
+						WorkDirectory: workDir,
+					},
+					BinaryPath: pulumiPath,
+					CachePath:  pulumiDir,
+					Tracer:     tracer,
+				})
+				if err != nil && !xerrors.Is(err, context.Canceled) {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}()
+
+			connector[string(database.ProvisionerTypePulumi)] = sdkproto.NewDRPCProvisionerClient(pulumiClient)
+			provisionerTypes = append(provisionerTypes, database.ProvisionerTypePulumi)
+		}
+
+		// Kubernetes manifests are applied with the service account of the
+		// pod, so they are only supported when running in a cluster.
+		kubernetesConfig, err := kubernetes.InClusterConfig()
+		if err != nil {
+			logger.Debug(ctx, "kubernetes provisioner disabled", slog.Error(err))
+		} else {
+			kubernetesClient, kubernetesServer := drpc.MemTransportPipe()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-ctx.Done()
+				_ = kubernetesClient.Close()
+				_ = kubernetesServer.Close()
+			}()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer cancel()
+
+				err := kubernetes.Serve(ctx, &kubernetes.ServeOptions{
+					ServeOptions: &provisionersdk.ServeOptions{
+						Listener:      kubernetesServer,
+						Logger:        logger.Named("kubernetes"),
+						WorkDirectory: workDir,
+					},
+					Config: kubernetesConfig,
+					Tracer: tracer,
+				})
+				if err != nil && !xerrors.Is(err, context.Canceled) {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}()
+
+			connector[string(database.ProvisionerTypeKubernetes)] = sdkproto.NewDRPCProvisionerClient(kubernetesClient)
+			provisionerTypes = append(provisionerTypes, database.ProvisionerTypeKubernetes)
+		}
 	}
 
 	return provisionerd.New(func(dialCtx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
 		// This debounces calls to listen every second. Read the comment
 		// in provisionerdserver.go to learn more!
-		return coderAPI.CreateInMemoryProvisionerDaemon(dialCtx, name)
+		return coderAPI.CreateInMemoryProvisionerDaemon(dialCtx, name, provisionerTypes)
 	}, &provisionerd.Options{
 		Logger:              logger.Named(fmt.Sprintf("provisionerd-%s", name)),
 		UpdateInterval:      time.Second,
@@ -1732,6 +2044,34 @@ func configureOIDCPKI(orig *oauth2.Config, keyFile string, certFile string) (*oa
 	})
 }
 
+func createLDAPAuthenticator(cfg codersdk.LDAPConfig) (*ldapauth.Authenticator, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		data, err := os.ReadFile(cfg.CAFile.String())
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", cfg.CAFile.String(), err)
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return nil, xerrors.Errorf("failed to parse CA certificate in ldap-ca-file")
+		}
+		tlsConfig.RootCAs = caPool
+	}
+	return ldapauth.New(ldapauth.Config{
+		URL:               cfg.URL.String(),
+		StartTLS:          cfg.StartTLS.Value(),
+		TLSConfig:         tlsConfig,
+		BindDN:            cfg.BindDN.String(),
+		BindPassword:      cfg.BindPassword.String(),
+		SearchBase:        cfg.UserSearchBase.String(),
+		UserFilter:        cfg.UserFilter.String(),
+		UsernameAttribute: cfg.UsernameAttribute.String(),
+		EmailAttribute:    cfg.EmailAttribute.String(),
+		GroupsAttribute:   cfg.GroupsAttribute.String(),
+		PoolSize:          int(cfg.PoolSize.Value()),
+	})
+}
+
 func configureCAPool(tlsClientCAFile string, tlsConfig *tls.Config) error {
 	if tlsClientCAFile != "" {
 		caPool := x509.NewCertPool()
@@ -1747,6 +2087,76 @@ func configureCAPool(tlsClientCAFile string, tlsConfig *tls.Config) error {
 	return nil
 }
 
+// configureNotificationRoutes returns a route for every chat webhook that is
+// configured.
+func configureNotificationRoutes(cfg codersdk.NotificationsConfig, httpClient *http.Client) ([]notifications.Route, error) {
+	var routes []notifications.Route
+	for _, webhook := range []struct {
+		method codersdk.NotificationMethod
+		cfg    codersdk.NotificationsWebhookConfig
+		poster func(webhookURL string) notifications.Poster
+	}{
+		{
+			method: codersdk.NotificationMethodSlack,
+			cfg:    cfg.Slack,
+			poster: func(webhookURL string) notifications.Poster {
+				return &notifications.SlackPoster{WebhookURL: webhookURL, HTTPClient: httpClient}
+			},
+		},
+		{
+			method: codersdk.NotificationMethodTeams,
+			cfg:    cfg.Teams,
+			poster: func(webhookURL string) notifications.Poster {
+				return &notifications.TeamsPoster{WebhookURL: webhookURL, HTTPClient: httpClient}
+			},
+		},
+	} {
+		webhookURL := webhook.cfg.WebhookURL.String()
+		if webhookURL == "" {
+			continue
+		}
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, xerrors.Errorf("%s webhook url must be an http(s) url", webhook.method)
+		}
+		templates := make([]codersdk.NotificationTemplate, 0, len(webhook.cfg.Templates))
+		for _, name := range webhook.cfg.Templates {
+			template := codersdk.NotificationTemplate(name)
+			if !template.Valid() {
+				return nil, xerrors.Errorf("unknown %s notification template %q, must be one of %v", webhook.method, name, codersdk.NotificationTemplates)
+			}
+			templates = append(templates, template)
+		}
+		routes = append(routes, notifications.Route{
+			Method:    webhook.method,
+			Poster:    webhook.poster(webhookURL),
+			Templates: templates,
+		})
+	}
+	return routes, nil
+}
+
+// configureMetricsExportSinks returns a sink for every metrics export endpoint
+// that is configured.
+func configureMetricsExportSinks(cfg codersdk.MetricsExportConfig, httpClient *http.Client) ([]metricsexport.Sink, error) {
+	var sinks []metricsexport.Sink
+	if cfg.StatsdAddress.String() != "" {
+		sink, err := metricsexport.NewStatsdSink(cfg.StatsdAddress.String())
+		if err != nil {
+			return nil, xerrors.Errorf("create statsd sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.OTLPEndpoint.String() != "" {
+		sink, err := metricsexport.NewOTLPSink(cfg.OTLPEndpoint.Value(), httpClient)
+		if err != nil {
+			return nil, xerrors.Errorf("create otlp sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
 //nolint:revive // Ignore flag-parameter: parameter 'allowEveryone' seems to be a control flag, avoid control coupling (revive)
 func configureGithubOAuth2(instrument *promoauth.Factory, accessURL *url.URL, clientID, clientSecret string, allowSignups, allowEveryone bool, allowOrgs []string, rawTeams []string, enterpriseBaseURL string) (*coderd.GithubOAuth2Config, error) {
 	redirectURL, err := accessURL.Parse("/api/v2/users/oauth2/github/callback")
@@ -1759,9 +2169,10 @@ func configureGithubOAuth2(instrument *promoauth.Factory, accessURL *url.URL, cl
 	if allowEveryone && len(rawTeams) > 0 {
 		return nil, xerrors.New("allow everyone and allowed teams cannot be used together")
 	}
-	if !allowEveryone && len(allowOrgs) == 0 {
-		return nil, xerrors.New("allowed orgs is empty: must specify at least one org or allow everyone")
+	if !allowEveryone && len(allowOrgs) == 0 && len(rawTeams) == 0 {
+		return nil, xerrors.New("allowed orgs and teams are empty: must specify at least one org, one team or allow everyone")
 	}
+	allowOrgs = append([]string(nil), allowOrgs...)
 	allowTeams := make([]coderd.GithubOAuth2Team, 0, len(rawTeams))
 	for _, rawTeam := range rawTeams {
 		parts := strings.SplitN(rawTeam, "/", 2)
@@ -1772,6 +2183,12 @@ func configureGithubOAuth2(instrument *promoauth.Factory, accessURL *url.URL, cl
 			Organization: parts[0],
 			Slug:         parts[1],
 		})
+		// Members of an allowed team must also be members of its
+		// organization, so teams can be allowed without listing their
+		// organizations separately.
+		if !slice.Contains(allowOrgs, parts[0]) {
+			allowOrgs = append(allowOrgs, parts[0])
+		}
 	}
 	createClient := func(client *http.Client) (*github.Client, error) {
 		if enterpriseBaseURL != "" {
@@ -2026,6 +2443,25 @@ func IsLocalhost(host string) bool {
 	return host == "localhost" || host == "127.0.0.1" || host == "::1"
 }
 
+// newPubsub creates the pubsub for the configured backend. PostgreSQL is used
+// by default.
+func newPubsub(ctx context.Context, logger slog.Logger, vals *codersdk.DeploymentValues, sqlDB *sql.DB, dbURL string) (pubsub.Pubsub, error) {
+	backend := vals.PubsubBackend.String()
+	if backend != "" && backend != "postgres" && vals.PubsubURL.String() == "" {
+		return nil, xerrors.Errorf("--pubsub-url is required when --pubsub-backend is %q", backend)
+	}
+	switch backend {
+	case "", "postgres":
+		return pubsub.New(ctx, sqlDB, dbURL)
+	case "redis":
+		return pubsub.NewRedis(ctx, logger, vals.PubsubURL.String())
+	case "nats":
+		return pubsub.NewNATS(ctx, logger, vals.PubsubURL.String())
+	default:
+		return nil, xerrors.Errorf("unknown pubsub backend %q, must be one of \"postgres\", \"redis\", or \"nats\"", backend)
+	}
+}
+
 func ConnectToPostgres(ctx context.Context, logger slog.Logger, driver string, dbURL string) (sqlDB *sql.DB, err error) {
 	logger.Debug(ctx, "connecting to postgresql")
 
@@ -2160,6 +2596,11 @@ func (s *HTTPServers) Close() {
 	}
 }
 
+// tracingEnabled returns true if any trace exporter is configured.
+func tracingEnabled(cfg *codersdk.DeploymentValues) bool {
+	return cfg.Trace.Enable.Value() || cfg.Trace.DataDog.Value() || cfg.Trace.HoneycombAPIKey != ""
+}
+
 func ConfigureTraceProvider(
 	ctx context.Context,
 	logger slog.Logger,
@@ -2178,7 +2619,7 @@ func ConfigureTraceProvider(
 		),
 	)
 
-	if cfg.Trace.Enable.Value() || cfg.Trace.DataDog.Value() || cfg.Trace.HoneycombAPIKey != "" {
+	if tracingEnabled(cfg) {
 		sdkTracerProvider, _closeTracing, err := tracing.TracerProvider(ctx, "coderd", tracing.TracerOpts{
 			Default:   cfg.Trace.Enable.Value(),
 			DataDog:   cfg.Trace.DataDog.Value(),
@@ -0,0 +1,157 @@
+//go:build !slim
+
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/sloghuman"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/database/dbsnapshot"
+)
+
+func (r *RootCmd) dbsnapshotCmd() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:   "dbsnapshot",
+		Short: "Export and import the state of a deployment to move it between PostgreSQL instances.",
+		Long: "Snapshots contain users, organizations, groups, templates and their " +
+			"versions and files, and workspaces with their builds. Workspace " +
+			"agents and logs aren't included, so workspaces must be restarted " +
+			"after an import. External auth tokens are exported as stored, so " +
+			"they stay encrypted if database encryption is enabled.",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+	}
+	cmd.AddSubcommands(
+		r.dbsnapshotExportCmd(),
+		r.dbsnapshotImportCmd(),
+	)
+	return cmd
+}
+
+func (r *RootCmd) dbsnapshotExportCmd() *clibase.Cmd {
+	var postgresURL string
+	cmd := &clibase.Cmd{
+		Use:        "export <file>",
+		Short:      "Export a consistent snapshot of the database to a file.",
+		Middleware: clibase.RequireNArgs(1),
+		Handler: func(inv *clibase.Invocation) error {
+			f, err := os.OpenFile(inv.Args[0], os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+			if err != nil {
+				return xerrors.Errorf("create snapshot file: %w", err)
+			}
+			defer f.Close()
+
+			summary, err := r.withSnapshotDB(inv, postgresURL, func(ctx context.Context, sqlDB *sql.DB) (dbsnapshot.Summary, error) {
+				return dbsnapshot.Export(ctx, sqlDB, f)
+			})
+			if err != nil {
+				_ = os.Remove(inv.Args[0])
+				return err
+			}
+			err = f.Close()
+			if err != nil {
+				return xerrors.Errorf("close snapshot file: %w", err)
+			}
+
+			printSnapshotSummary(inv.Stdout, summary)
+			cliui.Infof(inv.Stdout, "Exported snapshot to %s.", inv.Args[0])
+			return nil
+		},
+	}
+	cmd.Options.Add(postgresURLOption(&postgresURL))
+	return cmd
+}
+
+func (r *RootCmd) dbsnapshotImportCmd() *clibase.Cmd {
+	var postgresURL string
+	cmd := &clibase.Cmd{
+		Use:        "import <file>",
+		Short:      "Import a snapshot into the database of a new deployment.",
+		Long:       "The database is migrated first, and must not have any users or organizations. Import a snapshot with the Coder version it was exported with.",
+		Middleware: clibase.RequireNArgs(1),
+		Handler: func(inv *clibase.Invocation) error {
+			f, err := os.Open(inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("open snapshot file: %w", err)
+			}
+			defer f.Close()
+
+			summary, err := r.withSnapshotDB(inv, postgresURL, func(ctx context.Context, sqlDB *sql.DB) (dbsnapshot.Summary, error) {
+				return dbsnapshot.Import(ctx, sqlDB, f)
+			})
+			if err != nil {
+				return err
+			}
+
+			printSnapshotSummary(inv.Stdout, summary)
+			cliui.Infof(inv.Stdout, "Imported snapshot from %s. Restart running workspaces to reconnect their agents.", inv.Args[0])
+			return nil
+		},
+	}
+	cmd.Options.Add(postgresURLOption(&postgresURL))
+	return cmd
+}
+
+func postgresURLOption(postgresURL *string) clibase.Option {
+	return clibase.Option{
+		Env:         "CODER_PG_CONNECTION_URL",
+		Flag:        "postgres-url",
+		Description: "URL of a PostgreSQL database. If empty, the built-in PostgreSQL deployment will be used (Coder must not be already running in this case).",
+		Value:       clibase.StringOf(postgresURL),
+	}
+}
+
+// withSnapshotDB connects to the database, starting the built-in PostgreSQL
+// deployment if no URL is given, and calls fn with it.
+func (r *RootCmd) withSnapshotDB(inv *clibase.Invocation, postgresURL string, fn func(ctx context.Context, sqlDB *sql.DB) (dbsnapshot.Summary, error)) (dbsnapshot.Summary, error) {
+	cfg := r.createConfig()
+	logger := inv.Logger.AppendSinks(sloghuman.Sink(inv.Stderr))
+	if r.verbose {
+		logger = logger.Leveled(slog.LevelDebug)
+	}
+
+	ctx, cancel := inv.SignalNotifyContext(inv.Context(), InterruptSignals...)
+	defer cancel()
+
+	if postgresURL == "" {
+		cliui.Infof(inv.Stdout, "Using built-in PostgreSQL (%s)", cfg.PostgresPath())
+		url, closePg, err := startBuiltinPostgres(ctx, cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = closePg()
+		}()
+		postgresURL = url
+	}
+
+	sqlDB, err := ConnectToPostgres(ctx, logger, "postgres", postgresURL)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to postgres: %w", err)
+	}
+	defer func() {
+		_ = sqlDB.Close()
+	}()
+
+	return fn(ctx, sqlDB)
+}
+
+func printSnapshotSummary(w io.Writer, summary dbsnapshot.Summary) {
+	tables := maps.Keys(summary)
+	slices.Sort(tables)
+	for _, table := range tables {
+		_, _ = fmt.Fprintf(w, "%-30s %d\n", table, summary[table])
+	}
+}
@@ -347,3 +347,82 @@ func TestEscapePostgresURLUserInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureNotificationRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Routes", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.NotificationsConfig
+		cfg.Slack.WebhookURL = "https://hooks.slack.com/services/T/B/X"
+		cfg.Slack.Templates = clibase.StringArray{string(codersdk.NotificationTemplateWorkspaceBuildFailed)}
+		routes, err := configureNotificationRoutes(cfg, nil)
+		require.NoError(t, err)
+		require.Len(t, routes, 1)
+		require.Equal(t, codersdk.NotificationMethodSlack, routes[0].Method)
+		require.Equal(t, []codersdk.NotificationTemplate{codersdk.NotificationTemplateWorkspaceBuildFailed}, routes[0].Templates)
+	})
+
+	t.Run("UnknownTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.NotificationsConfig
+		cfg.Teams.WebhookURL = "https://example.webhook.office.com/webhookb2/x"
+		cfg.Teams.Templates = clibase.StringArray{"unknown"}
+		_, err := configureNotificationRoutes(cfg, nil)
+		require.ErrorContains(t, err, `unknown teams notification template "unknown"`)
+	})
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.NotificationsConfig
+		cfg.Slack.WebhookURL = "hooks.slack.com"
+		_, err := configureNotificationRoutes(cfg, nil)
+		require.ErrorContains(t, err, "slack webhook url must be an http(s) url")
+	})
+}
+
+func TestConfigureMetricsExportSinks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("None", func(t *testing.T) {
+		t.Parallel()
+
+		sinks, err := configureMetricsExportSinks(codersdk.MetricsExportConfig{}, nil)
+		require.NoError(t, err)
+		require.Empty(t, sinks)
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.MetricsExportConfig
+		require.NoError(t, cfg.StatsdAddress.Set("127.0.0.1:8125"))
+		require.NoError(t, cfg.OTLPEndpoint.Set("http://otel-collector:4318"))
+		sinks, err := configureMetricsExportSinks(cfg, nil)
+		require.NoError(t, err)
+		require.Len(t, sinks, 2)
+		require.Equal(t, "statsd", sinks[0].Name())
+		require.Equal(t, "otlp", sinks[1].Name())
+	})
+
+	t.Run("InvalidStatsdAddress", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.MetricsExportConfig
+		require.NoError(t, cfg.StatsdAddress.Set("127.0.0.1"))
+		_, err := configureMetricsExportSinks(cfg, nil)
+		require.ErrorContains(t, err, "create statsd sink")
+	})
+
+	t.Run("InvalidOTLPScheme", func(t *testing.T) {
+		t.Parallel()
+
+		var cfg codersdk.MetricsExportConfig
+		require.NoError(t, cfg.OTLPEndpoint.Set("grpc://otel-collector:4317"))
+		_, err := configureMetricsExportSinks(cfg, nil)
+		require.ErrorContains(t, err, "unsupported OTLP scheme")
+	})
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) share() *clibase.Cmd {
+	var remove bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "share <workspace> <user>",
+		Short:       "Share a workspace with another user",
+		Long:        "Shared users can use SSH and apps, but cannot modify the workspace.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+			user := inv.Args[1]
+
+			role := codersdk.WorkspaceRoleUse
+			if remove {
+				role = codersdk.WorkspaceRoleDeleted
+			}
+			err = client.UpdateWorkspaceACL(inv.Context(), workspace.ID, codersdk.UpdateWorkspaceACL{
+				UserRoles: map[string]codersdk.WorkspaceRole{
+					user: role,
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("update workspace acl: %w", err)
+			}
+
+			if remove {
+				_, _ = fmt.Fprintf(inv.Stdout, "Stopped sharing workspace %s with %s\n", cliui.Keyword(workspace.Name), cliui.Keyword(user))
+				return nil
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Shared workspace %s with %s\n", cliui.Keyword(workspace.Name), cliui.Keyword(user))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "remove",
+			Description: "Stop sharing the workspace with the user.",
+			Value:       clibase.BoolOf(&remove),
+		},
+	}
+	return cmd
+}
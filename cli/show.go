@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -9,8 +12,31 @@ import (
 )
 
 func (r *RootCmd) show() *clibase.Cmd {
+	var (
+		serverVersion string
+		watch         bool
+	)
+	formatter := cliui.NewOutputFormatter(
+		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
+			workspace, ok := data.(codersdk.Workspace)
+			if !ok {
+				return nil, xerrors.Errorf("expected type %T, got %T", workspace, data)
+			}
+			var out strings.Builder
+			err := cliui.WorkspaceResources(&out, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
+				WorkspaceName: workspace.Name,
+				ServerVersion: serverVersion,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return strings.TrimSuffix(out.String(), "\n"), nil
+		}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
 	client := new(codersdk.Client)
-	return &clibase.Cmd{
+	cmd := &clibase.Cmd{
 		Use:   "show <workspace>",
 		Short: "Display details of a workspace's resources and agents",
 		Middleware: clibase.Chain(
@@ -22,14 +48,33 @@ func (r *RootCmd) show() *clibase.Cmd {
 			if err != nil {
 				return xerrors.Errorf("get server version: %w", err)
 			}
+			serverVersion = buildInfo.Version
 			workspace, err := namedWorkspace(inv.Context(), client, inv.Args[0])
 			if err != nil {
 				return xerrors.Errorf("get workspace: %w", err)
 			}
-			return cliui.WorkspaceResources(inv.Stdout, workspace.LatestBuild.Resources, cliui.WorkspaceResourcesOptions{
-				WorkspaceName: workspace.Name,
-				ServerVersion: buildInfo.Version,
-			})
+			if watch {
+				return watchWorkspaces(inv, client, []codersdk.Workspace{workspace}, func(workspaces []codersdk.Workspace) (string, error) {
+					return formatter.Format(inv.Context(), workspaces[0])
+				})
+			}
+
+			out, err := formatter.Format(inv.Context(), workspace)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+		Options: clibase.OptionSet{
+			{
+				Flag:        "watch",
+				Description: "Watch the workspace and print its details again every time it changes.",
+				Value:       clibase.BoolOf(&watch),
+			},
 		},
 	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
 }
@@ -1,13 +1,18 @@
 package cli_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clitest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
 )
 
 func TestShow(t *testing.T) {
@@ -52,4 +57,28 @@ func TestShow(t *testing.T) {
 		}
 		<-doneChan
 	})
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, completeWithAgent())
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		inv, root := clitest.New(t, "show", workspace.Name, "--output=json")
+		clitest.SetupConfig(t, member, root)
+		out := bytes.NewBuffer(nil)
+		inv.Stdout = out
+		err := inv.WithContext(testutil.Context(t, testutil.WaitLong)).Run()
+		require.NoError(t, err)
+
+		var got codersdk.Workspace
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		require.Equal(t, workspace.ID, got.ID)
+		require.Len(t, got.LatestBuild.Resources, 1)
+		require.Len(t, got.LatestBuild.Resources[0].Agents, 1)
+	})
 }
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) snapshot() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Annotations: workspaceCommand,
+		Use:         "snapshot",
+		Short:       "Take and restore snapshots of workspaces",
+		Long:        "Templates choose which resources are snapshotted with the \"snapshot\" attribute of \"coder_metadata\".",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.snapshotCreate(),
+			r.snapshotList(),
+			r.snapshotRestore(),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) snapshotCreate() *clibase.Cmd {
+	var name string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <workspace>",
+		Short: "Take a snapshot of a workspace",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+				Name: name,
+			})
+			if err != nil {
+				return xerrors.Errorf("create snapshot: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, snapshot.BuildID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout,
+				"\nThe snapshot %s of the %s workspace has been taken at %s!\n",
+				cliui.Keyword(snapshot.Name), cliui.Keyword(workspace.Name), cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "name",
+			Description: "Name of the snapshot. Defaults to a name derived from the current time.",
+			Value:       clibase.StringOf(&name),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) snapshotList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]snapshotRow{}, []string{"Name", "Created At", "Status", "Resources"}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:     "list <workspace>",
+		Short:   "List the snapshots of a workspace",
+		Aliases: []string{"ls"},
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get snapshots: %w", err)
+			}
+
+			rows := make([]snapshotRow, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				rows = append(rows, snapshotRow{
+					WorkspaceSnapshot: snapshot,
+					Name:              snapshot.Name,
+					CreatedAt:         snapshot.CreatedAt,
+					Status:            strings.Title(string(snapshot.Status)),
+					Resources:         strings.Join(snapshot.Resources, ", "),
+				})
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type snapshotRow struct {
+	// For json format:
+	WorkspaceSnapshot codersdk.WorkspaceSnapshot `table:"-"`
+
+	// For table format:
+	Name      string    `json:"-" table:"name,default_sort"`
+	CreatedAt time.Time `json:"-" table:"created at"`
+	Status    string    `json:"-" table:"status"`
+	Resources string    `json:"-" table:"resources"`
+}
+
+func (r *RootCmd) snapshotRestore() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "restore <workspace> <snapshot>",
+		Short: "Restore a workspace from a snapshot",
+		Long:  "The workspace is started with its snapshotted resources recreated from the snapshot.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Options: clibase.OptionSet{cliui.SkipPromptOption()},
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			workspace, err := namedWorkspace(ctx, client, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get workspace: %w", err)
+			}
+
+			snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get snapshots: %w", err)
+			}
+			var snapshot *codersdk.WorkspaceSnapshot
+			for i := range snapshots {
+				if snapshots[i].Name == inv.Args[1] {
+					snapshot = &snapshots[i]
+					break
+				}
+			}
+			if snapshot == nil {
+				return xerrors.Errorf("snapshot %q of workspace %q not found", inv.Args[1], workspace.Name)
+			}
+
+			_, err = cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Restore workspace %s from snapshot %s? The current state of its snapshotted resources will be lost.", cliui.Keyword(workspace.Name), cliui.Keyword(snapshot.Name)),
+				IsConfirm: true,
+			})
+			if err != nil {
+				return err
+			}
+
+			build, err := client.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.ID)
+			if err != nil {
+				return xerrors.Errorf("restore snapshot: %w", err)
+			}
+
+			err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout,
+				"\nThe %s workspace has been restored from %s at %s!\n",
+				cliui.Keyword(workspace.Name), cliui.Keyword(snapshot.Name), cliui.Timestamp(time.Now()),
+			)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name:     "home",
+						Type:     "docker_volume",
+						Snapshot: true,
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "snapshot", "create", workspace.Name, "--name", "before-upgrade")
+	clitest.SetupConfig(t, member, root)
+	var buf bytes.Buffer
+	inv.Stdout = &buf
+	err := inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "has been taken")
+
+	inv, root = clitest.New(t, "snapshot", "list", workspace.Name)
+	clitest.SetupConfig(t, member, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "before-upgrade")
+	require.Contains(t, buf.String(), "docker_volume.home")
+
+	inv, root = clitest.New(t, "snapshot", "restore", workspace.Name, "before-upgrade", "--yes")
+	clitest.SetupConfig(t, member, root)
+	buf.Reset()
+	inv.Stdout = &buf
+	err = inv.WithContext(ctx).Run()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "has been restored")
+
+	workspace, err = member.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, workspace.LatestBuild.Transition)
+
+	inv, root = clitest.New(t, "snapshot", "restore", workspace.Name, "missing", "--yes")
+	clitest.SetupConfig(t, member, root)
+	err = inv.WithContext(ctx).Run()
+	require.ErrorContains(t, err, "not found")
+}
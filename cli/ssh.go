@@ -48,6 +48,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		stdio            bool
 		forwardAgent     bool
 		forwardGPG       bool
+		forwardX11       bool
 		identityAgent    string
 		wsPollInterval   time.Duration
 		waitEnum         string
@@ -153,21 +154,9 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			}
 
 			// Select the startup script behavior based on template configuration or flags.
-			var wait bool
-			switch waitEnum {
-			case "yes":
-				wait = true
-			case "no":
-				wait = false
-			case "auto":
-				for _, script := range workspaceAgent.Scripts {
-					if script.StartBlocksLogin {
-						wait = true
-						break
-					}
-				}
-			default:
-				return xerrors.Errorf("unknown wait value %q", waitEnum)
+			wait, err := waitForStartupScripts(waitEnum, workspaceAgent)
+			if err != nil {
+				return err
 			}
 			// The `--no-wait` flag is deprecated, but for now, check it.
 			if noWait {
@@ -216,6 +205,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ConnectionType: codersdk.ConnectionTypeSSH,
 			})
 			if err != nil {
 				return xerrors.Errorf("dial agent: %w", err)
@@ -313,6 +303,17 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 				}
 			}
 
+			if forwardX11 {
+				if workspaceAgent.OperatingSystem == "windows" {
+					return xerrors.New("X11 forwarding is not supported for Windows workspaces")
+				}
+
+				err = sshForwardX11(ctx, inv.Stderr, sshClient, sshSession, inv.Environ.Get("DISPLAY"))
+				if err != nil {
+					return xerrors.Errorf("forward X11: %w", err)
+				}
+			}
+
 			if len(remoteForwards) > 0 {
 				for _, remoteForward := range remoteForwards {
 					localAddr, remoteAddr, err := parseRemoteForward(remoteForward)
@@ -431,6 +432,13 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			Description:   "Specifies whether to forward the GPG agent. Unsupported on Windows workspaces, but supports all clients. Requires gnupg (gpg, gpgconf) on both the client and workspace. The GPG agent must already be running locally and will not be started for you. If a GPG agent is already running in the workspace, it will be attempted to be killed.",
 			Value:         clibase.BoolOf(&forwardGPG),
 		},
+		{
+			Flag:          "forward-x11",
+			FlagShorthand: "X",
+			Env:           "CODER_SSH_FORWARD_X11",
+			Description:   "Specifies whether to forward X11 connections from the workspace to the local display in $DISPLAY. Unsupported on Windows workspaces.",
+			Value:         clibase.BoolOf(&forwardX11),
+		},
 		{
 			Flag:        "identity-agent",
 			Env:         "CODER_SSH_IDENTITY_AGENT",
@@ -610,6 +618,27 @@ func getWorkspaceAndAgent(ctx context.Context, inv *clibase.Invocation, client *
 	return workspace, workspaceAgent, nil
 }
 
+// waitForStartupScripts returns whether to wait for the startup scripts of the
+// agent to finish before connecting, for a "yes", "no" or "auto" wait value.
+// Auto waits if any of the scripts blocks login.
+func waitForStartupScripts(waitEnum string, agent codersdk.WorkspaceAgent) (bool, error) {
+	switch waitEnum {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	case "auto":
+		for _, script := range agent.Scripts {
+			if script.StartBlocksLogin {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, xerrors.Errorf("unknown wait value %q", waitEnum)
+	}
+}
+
 func getWorkspaceAgent(workspace codersdk.Workspace, agentName string) (workspaceAgent codersdk.WorkspaceAgent, err error) {
 	resources := workspace.LatestBuild.Resources
 
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"net/url"
 	"testing"
@@ -136,3 +137,65 @@ func (c *fakeCloser) Close() error {
 	*c.closes = append(*c.closes, c)
 	return c.err
 }
+
+func TestParseX11Display(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		display string
+		want    x11Display
+		wantErr bool
+	}{
+		{display: ":0", want: x11Display{network: "unix", address: "/tmp/.X11-unix/X0"}},
+		{display: "unix:1.2", want: x11Display{network: "unix", address: "/tmp/.X11-unix/X1", screen: 2}},
+		{display: "localhost:10.0", want: x11Display{network: "tcp", address: "localhost:6010"}},
+		{display: "/private/tmp/com.apple.launchd.abc/org.xquartz:0", want: x11Display{network: "unix", address: "/private/tmp/com.apple.launchd.abc/org.xquartz:0"}},
+		{display: "", wantErr: true},
+		{display: "localhost", wantErr: true},
+		{display: ":abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.display, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseX11Display(tt.display)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplaceX11Cookie(t *testing.T) {
+	t.Parallel()
+
+	fakeCookie := bytes.Repeat([]byte{1}, 16)
+	realCookie := bytes.Repeat([]byte{2}, 16)
+	setup := func(cookie []byte) []byte {
+		// A little endian connection setup for X11 version 11.0, followed
+		// by the padded auth protocol and cookie.
+		packet := []byte{'l', 0, 11, 0, 0, 0, byte(len(x11AuthProtocol)), 0, byte(len(cookie)), 0, 0, 0}
+		packet = append(packet, x11AuthProtocol...)
+		packet = append(packet, make([]byte, x11Pad(len(x11AuthProtocol))-len(x11AuthProtocol))...)
+		return append(packet, cookie...)
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := replaceX11Cookie(bytes.NewReader(setup(fakeCookie)), fakeCookie, realCookie)
+		require.NoError(t, err)
+		require.Equal(t, setup(realCookie), got)
+	})
+
+	t.Run("WrongCookie", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := replaceX11Cookie(bytes.NewReader(setup(realCookie)), fakeCookie, realCookie)
+		require.Error(t, err)
+	})
+}
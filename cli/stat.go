@@ -39,6 +39,7 @@ func (r *RootCmd) stat() *clibase.Cmd {
 				"container_memory",
 			}),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 		)
 	)
 	cmd := &clibase.Cmd{
@@ -134,7 +135,7 @@ func (*RootCmd) statCPU(fs afero.Fs) *clibase.Cmd {
 	var (
 		hostArg   bool
 		st        *clistat.Statter
-		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat())
+		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat(), cliui.YAMLFormat())
 	)
 	cmd := &clibase.Cmd{
 		Use:        "cpu",
@@ -176,7 +177,7 @@ func (*RootCmd) statMem(fs afero.Fs) *clibase.Cmd {
 		hostArg   bool
 		prefixArg string
 		st        *clistat.Statter
-		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat())
+		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat(), cliui.YAMLFormat())
 	)
 	cmd := &clibase.Cmd{
 		Use:        "mem",
@@ -230,7 +231,7 @@ func (*RootCmd) statDisk(fs afero.Fs) *clibase.Cmd {
 		pathArg   string
 		prefixArg string
 		st        *clistat.Statter
-		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat())
+		formatter = cliui.NewOutputFormatter(cliui.TextFormat(), cliui.JSONFormat(), cliui.YAMLFormat())
 	)
 	cmd := &clibase.Cmd{
 		Use:        "disk",
@@ -1,28 +1,59 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
 	"github.com/coder/coder/v2/codersdk"
 )
 
 func (r *RootCmd) stop() *clibase.Cmd {
+	var (
+		all          bool
+		templateName string
+	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
 		Annotations: workspaceCommand,
 		Use:         "stop <workspace>",
 		Short:       "Stop a workspace",
 		Middleware: clibase.Chain(
-			clibase.RequireNArgs(1),
+			clibase.RequireRangeArgs(0, 1),
 			r.InitClient(client),
 		),
 		Options: clibase.OptionSet{
 			cliui.SkipPromptOption(),
+			{
+				Flag:        "all",
+				Description: "Stop all running workspaces you have access to, instead of a single workspace.",
+				Value:       clibase.BoolOf(&all),
+			},
+			{
+				Flag:        "template",
+				Description: "Only stop workspaces using this template. Requires --all.",
+				Value:       clibase.StringOf(&templateName),
+			},
 		},
 		Handler: func(inv *clibase.Invocation) error {
+			if all {
+				if len(inv.Args) > 0 {
+					return xerrors.New("a workspace name cannot be used with --all")
+				}
+				return stopAllWorkspaces(inv, client, templateName)
+			}
+			if templateName != "" {
+				return xerrors.New("--template can only be used with --all")
+			}
+			if len(inv.Args) == 0 {
+				return xerrors.New("a workspace name is required unless --all is set")
+			}
+
 			_, err := cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "Confirm stop workspace?",
 				IsConfirm: true,
@@ -58,3 +89,90 @@ func (r *RootCmd) stop() *clibase.Cmd {
 	}
 	return cmd
 }
+
+// stopAllWorkspaces queues a stop build for every running workspace,
+// optionally limited to a single template. Builds are not waited on.
+func stopAllWorkspaces(inv *clibase.Invocation, client *codersdk.Client, templateName string) error {
+	ctx := inv.Context()
+
+	workspaces, err := runningWorkspaces(ctx, client, templateName)
+	if err != nil {
+		return err
+	}
+	if len(workspaces) == 0 {
+		_, _ = fmt.Fprintln(inv.Stdout, "No running workspaces found.")
+		return nil
+	}
+
+	_, err = cliui.Prompt(inv, cliui.PromptOptions{
+		Text:      fmt.Sprintf("Confirm stop %d workspaces?", len(workspaces)),
+		IsConfirm: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make(map[uuid.UUID]string, len(workspaces))
+	ids := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		names[workspace.ID] = workspace.OwnerName + "/" + workspace.Name
+		ids = append(ids, workspace.ID)
+	}
+
+	failed := 0
+	for start := 0; start < len(ids); start += codersdk.MaxBatchWorkspaceBuilds {
+		end := start + codersdk.MaxBatchWorkspaceBuilds
+		if end > len(ids) {
+			end = len(ids)
+		}
+		resp, err := client.BatchWorkspaceBuilds(ctx, codersdk.BatchWorkspaceBuildRequest{
+			WorkspaceIDs: ids[start:end],
+			Transition:   codersdk.WorkspaceTransitionStop,
+		})
+		if err != nil {
+			return xerrors.Errorf("stop workspaces: %w", err)
+		}
+		for _, result := range resp.Results {
+			name := names[result.WorkspaceID]
+			if result.Error != nil {
+				failed++
+				cliui.Errorf(inv.Stderr, "Failed to stop %s: %s", cliui.Keyword(name), result.Error.Message)
+				continue
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Stopping %s\n", cliui.Keyword(name))
+		}
+	}
+
+	if failed > 0 {
+		return xerrors.Errorf("failed to stop %d of %d workspaces", failed, len(ids))
+	}
+	_, _ = fmt.Fprintf(inv.Stdout, "\nQueued stop builds for %d workspaces at %s!\n", len(ids), cliui.Timestamp(time.Now()))
+	return nil
+}
+
+func runningWorkspaces(ctx context.Context, client *codersdk.Client, templateName string) ([]codersdk.Workspace, error) {
+	var (
+		pageNumber = 0
+		limit      = 100
+		workspaces []codersdk.Workspace
+	)
+
+	for {
+		page, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Template: templateName,
+			Status:   string(codersdk.WorkspaceStatusRunning),
+			Offset:   pageNumber * limit,
+			Limit:    limit,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("fetch workspaces page %d: %w", pageNumber, err)
+		}
+
+		pageNumber++
+		if len(page.Workspaces) == 0 {
+			break
+		}
+		workspaces = append(workspaces, page.Workspaces...)
+	}
+	return workspaces, nil
+}
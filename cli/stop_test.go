@@ -0,0 +1,72 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/pty/ptytest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestStop(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AllByTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		otherVersion := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, otherVersion.ID)
+		otherTemplate := coderdtest.CreateTemplate(t, client, owner.OrganizationID, otherVersion.ID)
+
+		ownerWorkspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		memberWorkspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+		otherWorkspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, otherTemplate.ID)
+		for _, workspace := range []codersdk.Workspace{ownerWorkspace, memberWorkspace, otherWorkspace} {
+			coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		}
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inv, root := clitest.New(t, "stop", "--all", "--template", template.Name, "--yes")
+		clitest.SetupConfig(t, client, root)
+		pty := ptytest.New(t).Attach(inv)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- inv.WithContext(ctx).Run()
+		}()
+		pty.ExpectMatch("Queued stop builds for 2 workspaces")
+		require.NoError(t, <-done)
+
+		for _, workspace := range []codersdk.Workspace{ownerWorkspace, memberWorkspace} {
+			updated, err := client.Workspace(ctx, workspace.ID)
+			require.NoError(t, err)
+			require.Equal(t, codersdk.WorkspaceTransitionStop, updated.LatestBuild.Transition)
+		}
+		otherWorkspace, err := client.Workspace(ctx, otherWorkspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, otherWorkspace.LatestBuild.Transition)
+	})
+
+	t.Run("TemplateRequiresAll", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		inv, root := clitest.New(t, "stop", "--template", "foo")
+		clitest.SetupConfig(t, client, root)
+		err := inv.Run()
+		require.ErrorContains(t, err, "--template can only be used with --all")
+	})
+}
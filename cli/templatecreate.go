@@ -18,12 +18,13 @@ import (
 
 func (r *RootCmd) templateCreate() *clibase.Cmd {
 	var (
-		provisioner          string
-		provisionerTags      []string
-		variablesFile        string
-		commandLineVariables []string
-		disableEveryone      bool
-		requireActiveVersion bool
+		provisioner             string
+		provisionerTags         []string
+		provisionerRequirements []string
+		variablesFile           string
+		commandLineVariables    []string
+		disableEveryone         bool
+		requireActiveVersion    bool
 
 		defaultTTL           time.Duration
 		failureTTL           time.Duration
@@ -107,6 +108,11 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 				}
 			}
 
+			provisionerType, err := uploadFlags.provisionerType(provisioner)
+			if err != nil {
+				return err
+			}
+
 			// Confirm upload of the directory.
 			resp, err := uploadFlags.upload(inv, client)
 			if err != nil {
@@ -127,13 +133,14 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 			}
 
 			job, err := createValidTemplateVersion(inv, createValidTemplateVersionArgs{
-				Message:            message,
-				Client:             client,
-				Organization:       organization,
-				Provisioner:        codersdk.ProvisionerType(provisioner),
-				FileID:             resp.ID,
-				ProvisionerTags:    tags,
-				UserVariableValues: userVariableValues,
+				Message:                 message,
+				Client:                  client,
+				Organization:            organization,
+				Provisioner:             provisionerType,
+				FileID:                  resp.ID,
+				ProvisionerTags:         tags,
+				ProvisionerRequirements: provisionerRequirements,
+				UserVariableValues:      userVariableValues,
 			})
 			if err != nil {
 				return err
@@ -205,6 +212,11 @@ func (r *RootCmd) templateCreate() *clibase.Cmd {
 			Description: "Specify a set of tags to target provisioner daemons.",
 			Value:       clibase.StringArrayOf(&provisionerTags),
 		},
+		{
+			Flag:        "provisioner-requirement",
+			Description: "Specify requirements on the tags of provisioner daemons, e.g. \"gpu=true\" or \"region in [eu-west, eu-central]\". Values may contain * wildcards.",
+			Value:       clibase.StringArrayOf(&provisionerRequirements),
+		},
 		{
 			Flag:        "default-ttl",
 			Description: "Specify a default TTL for workspaces created from this template. It is the default time before shutdown - workspaces created from this template default to this value. Maps to \"Default autostop\" in the UI.",
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -36,6 +38,13 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		requireActiveVersion           bool
 		deprecationMessage             string
 		disableEveryone                bool
+		maxRunningWorkspaces           int64
+		denyPortForwarding             bool
+		allowedPorts                   []string
+		recordSessions                 bool
+		sessionRecordingRetention      time.Duration
+		buildRetryMaxAttempts          int64
+		buildRetryBackoff              time.Duration
 	)
 	client := new(codersdk.Client)
 
@@ -167,6 +176,60 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				disableEveryoneGroup = disableEveryone
 			}
 
+			var maxRunning *int32
+			if userSetOption(inv, "max-running-workspaces") {
+				if maxRunningWorkspaces < 0 || maxRunningWorkspaces > math.MaxInt32 {
+					return xerrors.Errorf("--max-running-workspaces must be between 0 and %d", math.MaxInt32)
+				}
+				maxRunning = ptr.Ref(int32(maxRunningWorkspaces))
+			}
+
+			var portForwardingPolicy *codersdk.TemplatePortForwardingPolicy
+			if userSetOption(inv, "deny-port-forwarding") || userSetOption(inv, "allowed-ports") {
+				policy := template.PortForwardingPolicy
+				if userSetOption(inv, "deny-port-forwarding") {
+					policy.DenyByDefault = denyPortForwarding
+				}
+				if userSetOption(inv, "allowed-ports") {
+					policy.AllowedPorts = []uint16{}
+					for _, port := range allowedPorts {
+						if port == "none" {
+							continue
+						}
+						parsed, err := parsePort(port)
+						if err != nil {
+							return xerrors.Errorf("--allowed-ports: %w", err)
+						}
+						policy.AllowedPorts = append(policy.AllowedPorts, parsed)
+					}
+				}
+				portForwardingPolicy = &policy
+			}
+
+			var sessionRecording *codersdk.TemplateSessionRecording
+			if userSetOption(inv, "session-recording") || userSetOption(inv, "session-recording-retention") {
+				recording := template.SessionRecording
+				if userSetOption(inv, "session-recording") {
+					recording.Enabled = recordSessions
+				}
+				if userSetOption(inv, "session-recording-retention") {
+					recording.RetentionMillis = sessionRecordingRetention.Milliseconds()
+				}
+				sessionRecording = &recording
+			}
+
+			var buildRetryPolicy *codersdk.TemplateBuildRetryPolicy
+			if userSetOption(inv, "build-retry-max-attempts") || userSetOption(inv, "build-retry-backoff") {
+				policy := template.BuildRetryPolicy
+				if userSetOption(inv, "build-retry-max-attempts") {
+					policy.MaxAttempts = int32(buildRetryMaxAttempts)
+				}
+				if userSetOption(inv, "build-retry-backoff") {
+					policy.BackoffMillis = buildRetryBackoff.Milliseconds()
+				}
+				buildRetryPolicy = &policy
+			}
+
 			req := codersdk.UpdateTemplateMeta{
 				Name:             name,
 				DisplayName:      displayName,
@@ -190,6 +253,10 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				RequireActiveVersion:           requireActiveVersion,
 				DeprecationMessage:             deprecated,
 				DisableEveryoneGroupAccess:     disableEveryoneGroup,
+				MaxRunningWorkspaces:           maxRunning,
+				PortForwardingPolicy:           portForwardingPolicy,
+				SessionRecording:               sessionRecording,
+				BuildRetryPolicy:               buildRetryPolicy,
 			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
@@ -278,6 +345,41 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Hidden: true,
 			Value:  clibase.Int64Of(&autostopRequirementWeeks),
 		},
+		{
+			Flag:        "max-running-workspaces",
+			Description: "Specify the maximum number of workspaces created from this template that can run at the same time. Pass 0 to remove the limit.",
+			Value:       clibase.Int64Of(&maxRunningWorkspaces),
+		},
+		{
+			Flag:        "deny-port-forwarding",
+			Description: "Deny forwarding and sharing the ports of workspaces created from this template, except for the ports passed to --allowed-ports. Denied attempts are audited.",
+			Value:       clibase.BoolOf(&denyPortForwarding),
+		},
+		{
+			Flag:        "allowed-ports",
+			Description: "Edit the ports that may be forwarded and shared when --deny-port-forwarding is set. To remove all allowed ports, pass 'none'.",
+			Value:       clibase.StringArrayOf(&allowedPorts),
+		},
+		{
+			Flag:        "session-recording",
+			Description: "Record the web terminal sessions of workspaces created from this template. Sessions are refused if the deployment has no --session-recording-storage-url.",
+			Value:       clibase.BoolOf(&recordSessions),
+		},
+		{
+			Flag:        "session-recording-retention",
+			Description: "Delete session recordings of this template after this duration. Pass 0 to keep them forever.",
+			Value:       clibase.DurationOf(&sessionRecordingRetention),
+		},
+		{
+			Flag:        "build-retry-max-attempts",
+			Description: "Specify how many times a workspace build that failed transiently is attempted, including the first attempt. Pass 0 to disable retries.",
+			Value:       clibase.Int64Of(&buildRetryMaxAttempts),
+		},
+		{
+			Flag:        "build-retry-backoff",
+			Description: "Specify how long to wait before retrying a failed workspace build. The delay doubles with every attempt.",
+			Value:       clibase.DurationOf(&buildRetryBackoff),
+		},
 		{
 			Flag:        "failure-ttl",
 			Description: "Specify a failure TTL for workspaces created from this template. It is the amount of time after a failed \"start\" build before coder automatically schedules a \"stop\" build to cleanup.This licensed feature's default is 0h (off). Maps to \"Failure cleanup\" in the UI.",
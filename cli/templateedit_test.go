@@ -56,6 +56,13 @@ func TestTemplateEdit(t *testing.T) {
 			"--icon", icon,
 			"--default-ttl", defaultTTL.String(),
 			"--allow-user-cancel-workspace-jobs=" + strconv.FormatBool(allowUserCancelWorkspaceJobs),
+			"--max-running-workspaces", "3",
+			"--deny-port-forwarding",
+			"--allowed-ports", "8080,3000",
+			"--session-recording",
+			"--session-recording-retention", "720h",
+			"--build-retry-max-attempts", "3",
+			"--build-retry-backoff", "1m",
 		}
 		inv, root := clitest.New(t, cmdArgs...)
 		clitest.SetupConfig(t, templateAdmin, root)
@@ -74,6 +81,13 @@ func TestTemplateEdit(t *testing.T) {
 		assert.Equal(t, icon, updated.Icon)
 		assert.Equal(t, defaultTTL.Milliseconds(), updated.DefaultTTLMillis)
 		assert.Equal(t, allowUserCancelWorkspaceJobs, updated.AllowUserCancelWorkspaceJobs)
+		assert.EqualValues(t, 3, updated.MaxRunningWorkspaces)
+		assert.True(t, updated.PortForwardingPolicy.DenyByDefault)
+		assert.Equal(t, []uint16{3000, 8080}, updated.PortForwardingPolicy.AllowedPorts)
+		assert.True(t, updated.SessionRecording.Enabled)
+		assert.Equal(t, (720 * time.Hour).Milliseconds(), updated.SessionRecording.RetentionMillis)
+		assert.EqualValues(t, 3, updated.BuildRetryPolicy.MaxAttempts)
+		assert.Equal(t, time.Minute.Milliseconds(), updated.BuildRetryPolicy.BackoffMillis)
 	})
 	t.Run("FirstEmptyThenNotModified", func(t *testing.T) {
 		t.Parallel()
@@ -14,6 +14,7 @@ func (r *RootCmd) templateList() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
 		cliui.TableFormat([]templateTableRow{}, []string{"name", "last updated", "used by"}),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 
 	client := new(codersdk.Client)
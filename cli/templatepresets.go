@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) templatePresets() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "presets",
+		Short:   "Manage the parameter presets of a template version",
+		Aliases: []string{"preset"},
+		Long: formatExamples(
+			example{
+				Description: "Create a preset for the active version of a template",
+				Command:     "coder templates presets create my-template large --parameter cpu=8",
+			},
+			example{
+				Description: "Create a workspace with the values of a preset",
+				Command:     "coder create --template my-template --preset large my-workspace",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.templatePresetsCreate(),
+			r.templatePresetsDelete(),
+			r.templatePresetsList(),
+		},
+	}
+
+	return cmd
+}
+
+type templatePresetRow struct {
+	// For json format:
+	TemplateVersionPreset codersdk.TemplateVersionPreset `table:"-"`
+
+	// For table format:
+	Name       string    `json:"-" table:"name,default_sort"`
+	Parameters string    `json:"-" table:"parameters"`
+	CreatedAt  time.Time `json:"-" table:"created at"`
+}
+
+func (r *RootCmd) templatePresetsList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]templatePresetRow{}, []string{"name", "parameters", "created at"}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
+	var versionName string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "list <template>",
+		Short: "List the parameter presets of a template version",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			version, err := templatePresetsVersion(inv, client, inv.Args[0], versionName)
+			if err != nil {
+				return err
+			}
+			presets, err := client.TemplateVersionPresets(inv.Context(), version.ID)
+			if err != nil {
+				return xerrors.Errorf("get template version presets: %w", err)
+			}
+
+			if len(presets) == 0 {
+				_, _ = fmt.Fprintf(inv.Stderr, "No presets are defined for version %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, version.Name))
+				return nil
+			}
+
+			rows := make([]templatePresetRow, 0, len(presets))
+			for _, preset := range presets {
+				values := make([]string, 0, len(preset.Parameters))
+				for _, parameter := range preset.Parameters {
+					values = append(values, parameter.Name+"="+parameter.Value)
+				}
+				rows = append(rows, templatePresetRow{
+					TemplateVersionPreset: preset,
+					Name:                  preset.Name,
+					Parameters:            strings.Join(values, ", "),
+					CreatedAt:             preset.CreatedAt,
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = clibase.OptionSet{templatePresetsVersionOption(&versionName)}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templatePresetsCreate() *clibase.Cmd {
+	var (
+		versionName string
+		parameters  []string
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <template> <name>",
+		Short: "Create a parameter preset for a template version",
+		Long:  "The values must satisfy the validation of the parameters of the template version.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			values, err := asWorkspaceBuildParameters(parameters)
+			if err != nil {
+				return xerrors.Errorf("can't parse given parameter values: %w", err)
+			}
+			if len(values) == 0 {
+				return xerrors.Errorf("at least one --parameter is required")
+			}
+			version, err := templatePresetsVersion(inv, client, inv.Args[0], versionName)
+			if err != nil {
+				return err
+			}
+
+			preset, err := client.CreateTemplateVersionPreset(inv.Context(), version.ID, codersdk.CreateTemplateVersionPresetRequest{
+				Name:       inv.Args[1],
+				Parameters: values,
+			})
+			if err != nil {
+				return xerrors.Errorf("create template version preset: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Created preset %s for version %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, preset.Name), pretty.Sprint(cliui.DefaultStyles.Keyword, version.Name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		templatePresetsVersionOption(&versionName),
+		{
+			Flag:        "parameter",
+			Description: "Parameter value of the preset in the format \"name=value\".",
+			Value:       clibase.StringArrayOf(&parameters),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) templatePresetsDelete() *clibase.Cmd {
+	var versionName string
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <template> <name>",
+		Short: "Delete a parameter preset of a template version",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			version, err := templatePresetsVersion(inv, client, inv.Args[0], versionName)
+			if err != nil {
+				return err
+			}
+			err = client.DeleteTemplateVersionPreset(inv.Context(), version.ID, inv.Args[1])
+			if err != nil {
+				return xerrors.Errorf("delete template version preset: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Deleted preset %s of version %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, inv.Args[1]), pretty.Sprint(cliui.DefaultStyles.Keyword, version.Name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{templatePresetsVersionOption(&versionName)}
+	return cmd
+}
+
+func templatePresetsVersionOption(versionName *string) clibase.Option {
+	return clibase.Option{
+		Flag:        "template-version",
+		Description: "Name of the template version. Defaults to the active version of the template.",
+		Value:       clibase.StringOf(versionName),
+	}
+}
+
+// templatePresetsVersion returns the named version of the template, or its
+// active version if no name is given.
+func templatePresetsVersion(inv *clibase.Invocation, client *codersdk.Client, templateName, versionName string) (codersdk.TemplateVersion, error) {
+	organization, err := CurrentOrganization(inv, client)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("get current organization: %w", err)
+	}
+	if versionName != "" {
+		version, err := client.TemplateVersionByOrganizationAndName(inv.Context(), organization.ID, templateName, versionName)
+		if err != nil {
+			return codersdk.TemplateVersion{}, xerrors.Errorf("get template version by name %q: %w", versionName, err)
+		}
+		return version, nil
+	}
+	template, err := client.TemplateByName(inv.Context(), organization.ID, templateName)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("get template by name: %w", err)
+	}
+	version, err := client.TemplateVersion(inv.Context(), template.ActiveVersionID)
+	if err != nil {
+		return codersdk.TemplateVersion{}, xerrors.Errorf("get template version: %w", err)
+	}
+	return version, nil
+}
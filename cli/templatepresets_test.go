@@ -0,0 +1,84 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplatePresets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "cpu", Type: "number", DefaultValue: "2", Mutable: true, ValidationMin: ptr.Ref(int32(1)), ValidationMax: ptr.Ref(int32(16))},
+						{Name: "region", Type: "string", DefaultValue: "us", Mutable: true},
+					},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "templates", "presets", "create", template.Name, "large", "--parameter", "cpu=16")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	// Values are validated against the parameters of the version.
+	inv, root = clitest.New(t, "templates", "presets", "create", template.Name, "huge", "--parameter", "cpu=64")
+	clitest.SetupConfig(t, client, root)
+	require.Error(t, inv.WithContext(ctx).Run())
+
+	inv, root = clitest.New(t, "templates", "presets", "list", template.Name, "--template-version", version.Name)
+	clitest.SetupConfig(t, client, root)
+	stdout := new(bytes.Buffer)
+	inv.Stdout = stdout
+	require.NoError(t, inv.WithContext(ctx).Run())
+	require.Contains(t, stdout.String(), "cpu=16")
+	require.NotContains(t, stdout.String(), "huge")
+
+	inv, root = clitest.New(t, "create", "my-workspace", "--template", template.Name, "--preset", "large", "--parameter", "region=eu", "--yes")
+	clitest.SetupConfig(t, member, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	workspace, err := member.WorkspaceByOwnerAndName(ctx, codersdk.Me, "my-workspace", codersdk.WorkspaceOptions{})
+	require.NoError(t, err)
+	workspaceBuildParameters, err := member.WorkspaceBuildParameters(ctx, workspace.LatestBuild.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+		{Name: "cpu", Value: "16"},
+		{Name: "region", Value: "eu"},
+	}, workspaceBuildParameters)
+
+	inv, root = clitest.New(t, "templates", "presets", "delete", template.Name, "large")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Empty(t, presets)
+
+	inv, root = clitest.New(t, "create", "other-workspace", "--template", template.Name, "--preset", "large", "--yes")
+	clitest.SetupConfig(t, member, root)
+	require.ErrorContains(t, inv.WithContext(ctx).Run(), `no preset named "large"`)
+}
@@ -7,6 +7,7 @@ import (
 	"sort"
 
 	"github.com/codeclysm/extract/v3"
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/cli/clibase"
@@ -18,6 +19,7 @@ func (r *RootCmd) templatePull() *clibase.Cmd {
 	var (
 		tarMode     bool
 		versionName string
+		example     bool
 	)
 
 	client := new(codersdk.Client)
@@ -44,68 +46,21 @@ func (r *RootCmd) templatePull() *clibase.Cmd {
 				return xerrors.Errorf("get current organization: %w", err)
 			}
 
-			template, err := client.TemplateByName(ctx, organization.ID, templateName)
-			if err != nil {
-				return xerrors.Errorf("get template by name: %w", err)
-			}
-
-			var latestVersion codersdk.TemplateVersion
-			{
-				// Determine the latest template version and compare with the
-				// active version. If they aren't the same, warn the user.
-				versions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
-					TemplateID: template.ID,
-				})
-				if err != nil {
-					return xerrors.Errorf("template versions by template: %w", err)
+			var raw []byte
+			if example {
+				if versionName != "" {
+					return xerrors.New("--version can't be used with --example")
 				}
-
-				if len(versions) == 0 {
-					return xerrors.Errorf("no template versions for template %q", templateName)
-				}
-
-				// Sort the slice from newest to oldest template.
-				sort.SliceStable(versions, func(i, j int) bool {
-					return versions[i].CreatedAt.After(versions[j].CreatedAt)
-				})
-
-				latestVersion = versions[0]
-			}
-
-			var templateVersion codersdk.TemplateVersion
-			switch versionName {
-			case "", "active":
-				activeVersion, err := client.TemplateVersion(ctx, template.ActiveVersionID)
+				cliui.Info(inv.Stderr, "Pulling starter template "+cliui.Bold(templateName)+"...")
+				raw, err = client.TemplateExampleArchive(ctx, organization.ID, templateName)
 				if err != nil {
-					return xerrors.Errorf("get active template version: %w", err)
-				}
-				if versionName == "" && activeVersion.ID != latestVersion.ID {
-					cliui.Warn(inv.Stderr,
-						"A newer template version than the active version exists. Pulling the active version instead.",
-						"Use "+cliui.Code("--version latest")+" to pull the latest version.",
-					)
+					return xerrors.Errorf("download starter template: %w", err)
 				}
-				templateVersion = activeVersion
-			case "latest":
-				templateVersion = latestVersion
-			default:
-				version, err := client.TemplateVersionByName(ctx, template.ID, versionName)
+			} else {
+				raw, err = downloadTemplateVersion(inv, client, organization.ID, templateName, versionName)
 				if err != nil {
-					return xerrors.Errorf("get template version: %w", err)
+					return err
 				}
-				templateVersion = version
-			}
-
-			cliui.Info(inv.Stderr, "Pulling template version "+cliui.Bold(templateVersion.Name)+"...")
-
-			// Download the tar archive.
-			raw, ctype, err := client.Download(ctx, templateVersion.Job.FileID)
-			if err != nil {
-				return xerrors.Errorf("download template: %w", err)
-			}
-
-			if ctype != codersdk.ContentTypeTar {
-				return xerrors.Errorf("unexpected Content-Type %q, expecting %q", ctype, codersdk.ContentTypeTar)
 			}
 
 			if tarMode {
@@ -158,8 +113,80 @@ func (r *RootCmd) templatePull() *clibase.Cmd {
 
 			Value: clibase.StringOf(&versionName),
 		},
+		{
+			Description: "Pull the starter template with the given ID instead of a template. Starter templates are downloaded through the Coder deployment, from its registry mirror if one is configured.",
+			Flag:        "example",
+
+			Value: clibase.BoolOf(&example),
+		},
 		cliui.SkipPromptOption(),
 	}
 
 	return cmd
 }
+
+// downloadTemplateVersion returns the tar archive of the active, latest or
+// named version of a template.
+func downloadTemplateVersion(inv *clibase.Invocation, client *codersdk.Client, organizationID uuid.UUID, templateName, versionName string) ([]byte, error) {
+	ctx := inv.Context()
+	template, err := client.TemplateByName(ctx, organizationID, templateName)
+	if err != nil {
+		return nil, xerrors.Errorf("get template by name: %w", err)
+	}
+
+	var latestVersion codersdk.TemplateVersion
+	{
+		// Determine the latest template version and compare with the
+		// active version. If they aren't the same, warn the user.
+		versions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: template.ID,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("template versions by template: %w", err)
+		}
+
+		if len(versions) == 0 {
+			return nil, xerrors.Errorf("no template versions for template %q", templateName)
+		}
+
+		// Sort the slice from newest to oldest template.
+		sort.SliceStable(versions, func(i, j int) bool {
+			return versions[i].CreatedAt.After(versions[j].CreatedAt)
+		})
+
+		latestVersion = versions[0]
+	}
+
+	var templateVersion codersdk.TemplateVersion
+	switch versionName {
+	case "", "active":
+		activeVersion, err := client.TemplateVersion(ctx, template.ActiveVersionID)
+		if err != nil {
+			return nil, xerrors.Errorf("get active template version: %w", err)
+		}
+		if versionName == "" && activeVersion.ID != latestVersion.ID {
+			cliui.Warn(inv.Stderr,
+				"A newer template version than the active version exists. Pulling the active version instead.",
+				"Use "+cliui.Code("--version latest")+" to pull the latest version.",
+			)
+		}
+		templateVersion = activeVersion
+	case "latest":
+		templateVersion = latestVersion
+	default:
+		version, err := client.TemplateVersionByName(ctx, template.ID, versionName)
+		if err != nil {
+			return nil, xerrors.Errorf("get template version: %w", err)
+		}
+		templateVersion = version
+	}
+
+	cliui.Info(inv.Stderr, "Pulling template version "+cliui.Bold(templateVersion.Name)+"...")
+
+	// Download the tar archive exactly as it was pushed.
+	raw, err := client.TemplateVersionArchive(ctx, templateVersion.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("download template: %w", err)
+	}
+	return raw, nil
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -16,8 +17,11 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
+
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/pretty"
@@ -25,15 +29,19 @@ import (
 
 func (r *RootCmd) templatePush() *clibase.Cmd {
 	var (
-		versionName          string
-		provisioner          string
-		workdir              string
-		variablesFile        string
-		commandLineVariables []string
-		alwaysPrompt         bool
-		provisionerTags      []string
-		uploadFlags          templateUploadFlags
-		activate             bool
+		versionName             string
+		provisioner             string
+		workdir                 string
+		variablesFile           string
+		commandLineVariables    []string
+		alwaysPrompt            bool
+		provisionerTags         []string
+		provisionerRequirements []string
+		uploadFlags             templateUploadFlags
+		activate                bool
+		test                    bool
+		testMatrixFile          string
+		testTimeout             time.Duration
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -71,6 +79,17 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 				createTemplate = true
 			}
 
+			var testMatrix []map[string]string
+			if testMatrixFile != "" {
+				if !test {
+					return xerrors.New("--test-matrix can only be used with --test")
+				}
+				testMatrix, err = parseParameterMatrixFile(testMatrixFile)
+				if err != nil {
+					return xerrors.Errorf("parse test matrix: %w", err)
+				}
+			}
+
 			err = uploadFlags.checkForLockfile(inv)
 			if err != nil {
 				return xerrors.Errorf("check for lockfile: %w", err)
@@ -90,6 +109,11 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 				}
 			}
 
+			provisionerType, err := uploadFlags.provisionerType(provisioner)
+			if err != nil {
+				return err
+			}
+
 			resp, err := uploadFlags.upload(inv, client)
 			if err != nil {
 				return err
@@ -108,14 +132,18 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 				return err
 			}
 
+			git := uploadFlags.gitMetadata(inv)
+
 			args := createValidTemplateVersionArgs{
-				Message:            message,
-				Client:             client,
-				Organization:       organization,
-				Provisioner:        codersdk.ProvisionerType(provisioner),
-				FileID:             resp.ID,
-				ProvisionerTags:    tags,
-				UserVariableValues: userVariableValues,
+				Message:                 message,
+				Client:                  client,
+				Organization:            organization,
+				Provisioner:             provisionerType,
+				FileID:                  resp.ID,
+				ProvisionerTags:         tags,
+				ProvisionerRequirements: provisionerRequirements,
+				UserVariableValues:      userVariableValues,
+				Git:                     git,
 			}
 
 			if !createTemplate {
@@ -123,6 +151,12 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 				args.Template = &template
 				args.ReuseParameters = !alwaysPrompt
 			}
+			if versionName == "" && git != nil {
+				args.Name, err = gitVersionName(inv, client, args.Template, *git)
+				if err != nil {
+					return err
+				}
+			}
 
 			job, err := createValidTemplateVersion(inv, args)
 			if err != nil {
@@ -146,7 +180,19 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 					inv.Stdout, "\n"+cliui.Wrap(
 						"The "+cliui.Keyword(name)+" template has been created at "+cliui.Timestamp(time.Now())+"! "+
 							"Developers can provision a workspace with this template using:")+"\n")
-			} else if activate {
+			}
+
+			// The test workspaces need a template, so a new template is
+			// created before its first version is tested. Versions of an
+			// existing template are only activated if they pass.
+			if test {
+				err = testTemplateVersion(inv, client, organization.ID, *job, testMatrix, testTimeout)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !createTemplate && activate {
 				err = client.UpdateActiveTemplateVersion(inv.Context(), template.ID, codersdk.UpdateActiveTemplateVersion{
 					ID: job.ID,
 				})
@@ -197,9 +243,14 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 			Description: "Specify a set of tags to target provisioner daemons.",
 			Value:       clibase.StringArrayOf(&provisionerTags),
 		},
+		{
+			Flag:        "provisioner-requirement",
+			Description: "Specify requirements on the tags of provisioner daemons, e.g. \"gpu=true\" or \"region in [eu-west, eu-central]\". Values may contain * wildcards.",
+			Value:       clibase.StringArrayOf(&provisionerRequirements),
+		},
 		{
 			Flag:        "name",
-			Description: "Specify a name for the new template version. It will be automatically generated if not provided.",
+			Description: "Specify a name for the new template version. If not provided, it is the git tag or the short commit SHA of the template directory, or it is automatically generated.",
 			Value:       clibase.StringOf(&versionName),
 		},
 		{
@@ -213,6 +264,22 @@ func (r *RootCmd) templatePush() *clibase.Cmd {
 			Default:     "true",
 			Value:       clibase.BoolOf(&activate),
 		},
+		{
+			Flag:        "test",
+			Description: "Build a throwaway workspace from the new version, wait for its agents to be ready and delete it again before the version is activated. Fails if any build fails.",
+			Value:       clibase.BoolOf(&test),
+		},
+		{
+			Flag:        "test-matrix",
+			Description: "Specify a YAML file with a list of parameter value combinations to test the new version with. One workspace is built for each combination. Defaults to a single workspace with the default parameter values.",
+			Value:       clibase.StringOf(&testMatrixFile),
+		},
+		{
+			Flag:        "test-timeout",
+			Description: "The time each test workspace may take to build and become ready. Test workspaces are deleted by the server once it has passed.",
+			Default:     "30m",
+			Value:       clibase.DurationOf(&testTimeout),
+		},
 		cliui.SkipPromptOption(),
 	}
 	cmd.Options = append(cmd.Options, uploadFlags.options()...)
@@ -223,6 +290,7 @@ type templateUploadFlags struct {
 	directory      string
 	ignoreLockfile bool
 	message        string
+	provisioner    string
 }
 
 func (pf *templateUploadFlags) options() []clibase.Option {
@@ -242,6 +310,14 @@ func (pf *templateUploadFlags) options() []clibase.Option {
 		FlagShorthand: "m",
 		Description:   "Specify a message describing the changes in this version of the template. Messages longer than 72 characters will be displayed as truncated.",
 		Value:         clibase.StringOf(&pf.message),
+	}, {
+		Flag:        "provisioner",
+		Description: "Specify the provisioner backend of the template. Defaults to pulumi if the directory contains a Pulumi.yaml file, kubernetes if it contains a coder-kubernetes.yaml file, and terraform otherwise.",
+		Value: clibase.EnumOf(&pf.provisioner,
+			string(codersdk.ProvisionerTypeTerraform),
+			string(codersdk.ProvisionerTypePulumi),
+			string(codersdk.ProvisionerTypeKubernetes),
+		),
 	}}
 }
 
@@ -297,12 +373,47 @@ func (pf *templateUploadFlags) upload(inv *clibase.Invocation, client *codersdk.
 	return &resp, nil
 }
 
+// provisionerType returns the provisioner backend of the template. The
+// --provisioner flag takes precedence, then a Pulumi or Kubernetes project in
+// the directory, and then the given fallback.
+func (pf *templateUploadFlags) provisionerType(fallback string) (codersdk.ProvisionerType, error) {
+	if pf.provisioner != "" {
+		return codersdk.ProvisionerType(pf.provisioner), nil
+	}
+	if !pf.stdin() {
+		isPulumi, err := provisionersdk.DirHasPulumiProject(pf.directory)
+		if err != nil {
+			return "", xerrors.Errorf("dir has pulumi project: %w", err)
+		}
+		if isPulumi {
+			return codersdk.ProvisionerTypePulumi, nil
+		}
+		isKubernetes, err := provisionersdk.DirHasKubernetesProject(pf.directory)
+		if err != nil {
+			return "", xerrors.Errorf("dir has kubernetes project: %w", err)
+		}
+		if isKubernetes {
+			return codersdk.ProvisionerTypeKubernetes, nil
+		}
+	}
+	return codersdk.ProvisionerType(fallback), nil
+}
+
 func (pf *templateUploadFlags) checkForLockfile(inv *clibase.Invocation) error {
 	if pf.stdin() || pf.ignoreLockfile {
 		// Just assume there's a lockfile if reading from stdin.
 		return nil
 	}
 
+	provisioner, err := pf.provisionerType(string(codersdk.ProvisionerTypeTerraform))
+	if err != nil {
+		return err
+	}
+	if provisioner != codersdk.ProvisionerTypeTerraform {
+		// Only Terraform has a lockfile.
+		return nil
+	}
+
 	hasLockfile, err := provisionersdk.DirHasLockfile(pf.directory)
 	if err != nil {
 		return xerrors.Errorf("dir has lockfile: %w", err)
@@ -331,6 +442,57 @@ func (pf *templateUploadFlags) templateMessage(inv *clibase.Invocation) string {
 	return "Uploaded from the CLI"
 }
 
+// gitMetadata returns the git commit checked out in the template directory, or
+// nil if the directory isn't a git repository or git isn't installed.
+func (pf *templateUploadFlags) gitMetadata(inv *clibase.Invocation) *codersdk.TemplateVersionGitMetadata {
+	if pf.stdin() {
+		return nil
+	}
+	git := func(args ...string) (string, error) {
+		//nolint:gosec
+		cmd := exec.CommandContext(inv.Context(), "git", append([]string{"-C", pf.directory}, args...)...)
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	sha, err := git("rev-parse", "--verify", "HEAD")
+	if err != nil {
+		inv.Logger.Debug(inv.Context(), "template directory has no git commit", slog.Error(err))
+		return nil
+	}
+	metadata := &codersdk.TemplateVersionGitMetadata{CommitSHA: sha}
+	// This fails for a detached HEAD, which has no branch.
+	metadata.Branch, _ = git("symbolic-ref", "--short", "-q", "HEAD")
+	tags, _ := git("tag", "--points-at", "HEAD", "--sort=-version:refname")
+	if tags != "" {
+		metadata.Tag = strings.SplitN(tags, "\n", 2)[0]
+	}
+	return metadata
+}
+
+// gitVersionName returns the name of a template version pushed from a git
+// commit: the tag of the commit, or its short SHA. It returns an empty string,
+// so the name is generated, if the template already has a version with the
+// name, e.g. when the same commit is pushed twice.
+func gitVersionName(inv *clibase.Invocation, client *codersdk.Client, template *codersdk.Template, git codersdk.TemplateVersionGitMetadata) (string, error) {
+	name := git.ShortCommitSHA()
+	if git.Tag != "" && httpapi.TemplateVersionNameValid(git.Tag) == nil {
+		name = git.Tag
+	}
+	if template == nil {
+		return name, nil
+	}
+	_, err := client.TemplateVersionByName(inv.Context(), template.ID, name)
+	if err == nil {
+		return "", nil
+	}
+	var apiErr *codersdk.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode() == http.StatusNotFound {
+		return name, nil
+	}
+	return "", xerrors.Errorf("get template version %q: %w", name, err)
+}
+
 func (pf *templateUploadFlags) templateName(args []string) (string, error) {
 	if pf.stdin() {
 		// Can't infer name from directory if none provided.
@@ -365,22 +527,28 @@ type createValidTemplateVersionArgs struct {
 	// ReuseParameters will attempt to reuse params from the Template field
 	// before prompting the user. Set to false to always prompt for param
 	// values.
-	ReuseParameters    bool
-	ProvisionerTags    map[string]string
-	UserVariableValues []codersdk.VariableValue
+	ReuseParameters bool
+	ProvisionerTags map[string]string
+	// ProvisionerRequirements are validated by the server.
+	ProvisionerRequirements []string
+	UserVariableValues      []codersdk.VariableValue
+	// Git is the git commit the template is pushed from, if any.
+	Git *codersdk.TemplateVersionGitMetadata
 }
 
 func createValidTemplateVersion(inv *clibase.Invocation, args createValidTemplateVersionArgs) (*codersdk.TemplateVersion, error) {
 	client := args.Client
 
 	req := codersdk.CreateTemplateVersionRequest{
-		Name:               args.Name,
-		Message:            args.Message,
-		StorageMethod:      codersdk.ProvisionerStorageMethodFile,
-		FileID:             args.FileID,
-		Provisioner:        args.Provisioner,
-		ProvisionerTags:    args.ProvisionerTags,
-		UserVariableValues: args.UserVariableValues,
+		Name:                    args.Name,
+		Message:                 args.Message,
+		StorageMethod:           codersdk.ProvisionerStorageMethodFile,
+		FileID:                  args.FileID,
+		Provisioner:             args.Provisioner,
+		ProvisionerTags:         args.ProvisionerTags,
+		ProvisionerRequirements: args.ProvisionerRequirements,
+		UserVariableValues:      args.UserVariableValues,
+		Git:                     args.Git,
 	}
 	if args.Template != nil {
 		req.TemplateID = args.Template.ID
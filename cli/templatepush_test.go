@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -304,6 +305,163 @@ func TestTemplatePush(t *testing.T) {
 		require.NotEqual(t, "example", templateVersions[0].Name)
 	})
 
+	t.Run("Test", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		source := clitest.CreateTemplateVersionSource(t, prepareEchoResponses([]*proto.RichParameter{
+			{Name: "region", Type: "string", DefaultValue: "eu", Mutable: true},
+		}))
+		matrixFile := filepath.Join(t.TempDir(), "matrix.yaml")
+		require.NoError(t, os.WriteFile(matrixFile, []byte("- region: us\n- region: ap\n"), 0o600))
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "push", template.Name,
+			"--directory", source,
+			"--test.provisioner", string(database.ProvisionerTypeEcho),
+			"--name", "example",
+			"--test",
+			"--test-matrix", matrixFile,
+			"--yes",
+		)
+		clitest.SetupConfig(t, templateAdmin, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv = inv.WithContext(ctx)
+		w := clitest.StartWithWaiter(t, inv)
+
+		pty.ExpectMatchContext(ctx, "region=us")
+		pty.ExpectMatchContext(ctx, "region=ap")
+		pty.ExpectMatchContext(ctx, "passed")
+		w.RequireSuccess()
+
+		// The version passed, so it's active.
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		activeVersion, err := client.TemplateVersion(ctx, updated.ActiveVersionID)
+		require.NoError(t, err)
+		require.Equal(t, "example", activeVersion.Name)
+
+		// The test workspaces are gone.
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("TestFailed", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		// Only starting the workspace fails, so it can still be deleted.
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+			ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+				proto.WorkspaceTransition_START: echo.ApplyFailed,
+			},
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		inv, root := clitest.New(t, "templates", "push", template.Name,
+			"--directory", source,
+			"--test.provisioner", string(database.ProvisionerTypeEcho),
+			"--name", "example",
+			"--test",
+			"--yes",
+		)
+		clitest.SetupConfig(t, templateAdmin, root)
+		pty := ptytest.New(t).Attach(inv)
+		inv = inv.WithContext(ctx)
+		w := clitest.StartWithWaiter(t, inv)
+
+		pty.ExpectMatchContext(ctx, "(defaults)")
+		pty.ExpectMatchContext(ctx, "failed")
+		w.RequireContains("1 of 1 test combinations failed")
+
+		// The version failed, so it isn't activated.
+		updated, err := client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ActiveVersionID, updated.ActiveVersionID)
+
+		workspaces, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+		require.NoError(t, err)
+		require.Empty(t, workspaces.Workspaces)
+	})
+
+	t.Run("GitMetadata", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git is not installed")
+		}
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		source := clitest.CreateTemplateVersionSource(t, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		git := func(args ...string) string {
+			args = append([]string{"-C", source, "-c", "user.name=coder", "-c", "user.email=coder@coder.com"}, args...)
+			out, err := exec.Command("git", args...).Output()
+			require.NoError(t, err, "git %s", strings.Join(args, " "))
+			return strings.TrimSpace(string(out))
+		}
+		git("init")
+		git("checkout", "-b", "main")
+		git("add", ".")
+		git("commit", "-m", "initial commit")
+		git("tag", "v1.0.0")
+		sha := git("rev-parse", "HEAD")
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		push := func() {
+			inv, root := clitest.New(t, "templates", "push", template.Name, "--directory", source, "--test.provisioner", string(database.ProvisionerTypeEcho), "--yes")
+			clitest.SetupConfig(t, templateAdmin, root)
+			require.NoError(t, inv.WithContext(ctx).Run())
+		}
+		push()
+
+		templateVersions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, templateVersions, 2)
+		require.Equal(t, "v1.0.0", templateVersions[1].Name)
+		require.Equal(t, &codersdk.TemplateVersionGitMetadata{
+			CommitSHA: sha,
+			Branch:    "main",
+			Tag:       "v1.0.0",
+		}, templateVersions[1].Git)
+
+		// Pushing the same commit again doesn't reuse the name.
+		push()
+		templateVersions, err = client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: template.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, templateVersions, 3)
+		require.NotEqual(t, "v1.0.0", templateVersions[2].Name)
+		require.Equal(t, sha, templateVersions[2].Git.CommitSHA)
+	})
+
 	t.Run("UseWorkingDir", func(t *testing.T) {
 		t.Parallel()
 
@@ -35,7 +35,9 @@ func (r *RootCmd) templates() *clibase.Cmd {
 			r.templateEdit(),
 			r.templateInit(),
 			r.templateList(),
+			r.templatePresets(),
 			r.templatePush(),
+			r.templateVariables(),
 			r.templateVersions(),
 			r.templateDelete(),
 			r.templatePull(),
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// templateTestResult is the outcome of building a workspace from a template
+// version with one combination of parameter values.
+type templateTestResult struct {
+	Workspace  string `table:"workspace,default_sort"`
+	Parameters string `table:"parameters"`
+	Result     string `table:"result"`
+	Duration   string `table:"duration"`
+	Error      string `table:"error"`
+}
+
+// testTemplateVersion builds a throwaway workspace from the template version
+// for every combination of parameter values in matrix, waits for its agents to
+// be ready and deletes it again. The workspaces are ephemeral, so the server
+// deletes them once the timeout has passed if the CLI dies before it can.
+// Without a matrix, the version is tested with the default parameter values.
+func testTemplateVersion(inv *clibase.Invocation, client *codersdk.Client, organizationID uuid.UUID, version codersdk.TemplateVersion, matrix []map[string]string, timeout time.Duration) error {
+	if len(matrix) == 0 {
+		matrix = []map[string]string{{}}
+	}
+
+	results := make([]templateTestResult, 0, len(matrix))
+	failed := 0
+	for i, values := range matrix {
+		params := make([]codersdk.WorkspaceBuildParameter, 0, len(values))
+		for name, value := range values {
+			params = append(params, codersdk.WorkspaceBuildParameter{Name: name, Value: value})
+		}
+		sort.Slice(params, func(i, j int) bool {
+			return params[i].Name < params[j].Name
+		})
+		described := make([]string, 0, len(params))
+		for _, param := range params {
+			described = append(described, param.Name+"="+param.Value)
+		}
+
+		result := templateTestResult{
+			Workspace:  fmt.Sprintf("test-%s-%d", strings.ReplaceAll(version.ID.String(), "-", "")[:8], i+1),
+			Parameters: strings.Join(described, ", "),
+			Result:     "passed",
+		}
+		if result.Parameters == "" {
+			result.Parameters = "(defaults)"
+		}
+		_, _ = fmt.Fprintf(inv.Stdout, "\nTesting %s with %s...\n", cliui.Keyword(result.Workspace), result.Parameters)
+
+		start := time.Now()
+		err := runTemplateTest(inv, client, organizationID, version.ID, result.Workspace, params, timeout)
+		result.Duration = time.Since(start).Round(time.Second).String()
+		if err != nil {
+			failed++
+			result.Result = "failed"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	out, err := cliui.DisplayTable(results, "", nil)
+	if err != nil {
+		return xerrors.Errorf("render table: %w", err)
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, "\n"+out)
+
+	if failed > 0 {
+		return xerrors.Errorf("%d of %d test combinations failed", failed, len(matrix))
+	}
+	return nil
+}
+
+// runTemplateTest builds a single test workspace and deletes it afterwards,
+// even if the build failed or timed out.
+func runTemplateTest(inv *clibase.Invocation, client *codersdk.Client, organizationID, versionID uuid.UUID, name string, params []codersdk.WorkspaceBuildParameter, timeout time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(inv.Context(), timeout)
+	defer cancel()
+
+	ttl := timeout.Milliseconds()
+	workspace, err := client.CreateWorkspace(ctx, organizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateVersionID:   versionID,
+		Name:                name,
+		TTLMillis:           &ttl,
+		RichParameterValues: params,
+		Ephemeral:           true,
+	})
+	if err != nil {
+		return xerrors.Errorf("create workspace: %w", err)
+	}
+	defer func() {
+		deleteErr := deleteTestWorkspace(inv, client, workspace)
+		if err == nil && deleteErr != nil {
+			err = xerrors.Errorf("delete workspace: %w", deleteErr)
+		}
+	}()
+
+	err = cliui.WorkspaceBuild(ctx, inv.Stdout, client, workspace.LatestBuild.ID)
+	if err != nil {
+		return xerrors.Errorf("build workspace: %w", err)
+	}
+
+	workspace, err = client.Workspace(ctx, workspace.ID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	for _, resource := range workspace.LatestBuild.Resources {
+		for _, agent := range resource.Agents {
+			err = cliui.Agent(ctx, inv.Stdout, agent.ID, cliui.AgentOptions{
+				Fetch:     client.WorkspaceAgent,
+				FetchLogs: client.WorkspaceAgentLogsAfter,
+				Wait:      true,
+			})
+			if err != nil {
+				return xerrors.Errorf("wait for agent %q: %w", agent.Name, err)
+			}
+			// cliui.Agent returns once the startup scripts are done, even
+			// if they failed or timed out.
+			agent, err = client.WorkspaceAgent(ctx, agent.ID)
+			if err != nil {
+				return xerrors.Errorf("get agent: %w", err)
+			}
+			if agent.LifecycleState != codersdk.WorkspaceAgentLifecycleReady {
+				return xerrors.Errorf("agent %q is %s", agent.Name, agent.LifecycleState)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteTestWorkspace cancels the build of the workspace if it's still
+// running and deletes the workspace.
+func deleteTestWorkspace(inv *clibase.Invocation, client *codersdk.Client, workspace codersdk.Workspace) error {
+	ctx := inv.Context()
+	build, err := client.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
+	if err != nil {
+		return err
+	}
+	if build.Job.Status.Active() {
+		err = client.CancelWorkspaceBuild(ctx, build.ID)
+		if err != nil {
+			return xerrors.Errorf("cancel build: %w", err)
+		}
+		// Waiting fails because the build is canceled.
+		_ = cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+	}
+
+	build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+	})
+	if err != nil {
+		return err
+	}
+	return cliui.WorkspaceBuild(ctx, inv.Stdout, client, build.ID)
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) templateVariables() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "variables",
+		Short:   "Manage the variable values of a template without pushing a new version",
+		Aliases: []string{"variable"},
+		Long: formatExamples(
+			example{
+				Description: "Set a variable of a template, used by the next builds of its workspaces",
+				Command:     "coder templates variables set my-template region eu-west-1",
+			},
+			example{
+				Description: "Set a sensitive variable, encrypted if database encryption is enabled",
+				Command:     "coder templates variables set --sensitive my-template token \"$TOKEN\"",
+			},
+			example{
+				Description: "Set the default value of a variable for all templates of the organization",
+				Command:     "coder templates variables set --organization-default region eu-west-1",
+			},
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.templateVariablesList(),
+			r.templateVariablesSet(),
+			r.templateVariablesUnset(),
+		},
+	}
+
+	return cmd
+}
+
+type templateVariableValueRow struct {
+	// For json format:
+	TemplateVariableValue codersdk.TemplateVariableValue `table:"-"`
+
+	// For table format:
+	Name      string    `json:"-" table:"name,default_sort"`
+	Value     string    `json:"-" table:"value"`
+	Sensitive bool      `json:"-" table:"sensitive"`
+	UpdatedAt time.Time `json:"-" table:"updated at"`
+}
+
+func (r *RootCmd) templateVariablesList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]templateVariableValueRow{}, []string{"name", "value", "sensitive", "updated at"}),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
+	var organizationDefault bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "list [template]",
+		Short: "List the variable values set on a template",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(0, 1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if err := checkTemplateVariablesArgs(inv, organizationDefault, 0); err != nil {
+				return err
+			}
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+
+			var values []codersdk.TemplateVariableValue
+			if organizationDefault {
+				values, err = client.OrganizationTemplateVariableValues(inv.Context(), organization.ID)
+				if err != nil {
+					return xerrors.Errorf("get organization template variable values: %w", err)
+				}
+			} else {
+				template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+				if err != nil {
+					return xerrors.Errorf("get template by name: %w", err)
+				}
+				values, err = client.TemplateVariableValues(inv.Context(), template.ID)
+				if err != nil {
+					return xerrors.Errorf("get template variable values: %w", err)
+				}
+			}
+
+			if len(values) == 0 {
+				_, _ = fmt.Fprintln(inv.Stderr, "No variable values are set.")
+				return nil
+			}
+
+			rows := make([]templateVariableValueRow, 0, len(values))
+			for _, value := range values {
+				rows = append(rows, templateVariableValueRow{
+					TemplateVariableValue: value,
+					Name:                  value.Name,
+					Value:                 value.Value,
+					Sensitive:             value.Sensitive,
+					UpdatedAt:             value.UpdatedAt,
+				})
+			}
+			out, err := formatter.Format(inv.Context(), rows)
+			if err != nil {
+				return xerrors.Errorf("render table: %w", err)
+			}
+
+			_, err = fmt.Fprintln(inv.Stdout, out)
+			return err
+		},
+	}
+	cmd.Options = clibase.OptionSet{organizationDefaultOption(&organizationDefault)}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+func (r *RootCmd) templateVariablesSet() *clibase.Cmd {
+	var (
+		organizationDefault bool
+		sensitive           bool
+	)
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "set [template] <name> <value>",
+		Short: "Set the value of a variable of a template",
+		Long:  "The value is used by the next builds of the workspaces of the template. Values of lists, sets and tuples are JSON arrays, and values of maps and objects are JSON objects.",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(2, 3),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if err := checkTemplateVariablesArgs(inv, organizationDefault, 2); err != nil {
+				return err
+			}
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			args := inv.Args[len(inv.Args)-2:]
+			req := codersdk.UpsertTemplateVariableValueRequest{
+				Value:     args[1],
+				Sensitive: sensitive,
+			}
+
+			if organizationDefault {
+				_, err = client.UpsertOrganizationTemplateVariableValue(inv.Context(), organization.ID, args[0], req)
+				if err != nil {
+					return xerrors.Errorf("set organization template variable value: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Set the default value of variable %s for organization %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, args[0]), pretty.Sprint(cliui.DefaultStyles.Keyword, organization.Name))
+				return nil
+			}
+
+			template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			_, err = client.UpsertTemplateVariableValue(inv.Context(), template.ID, args[0], req)
+			if err != nil {
+				return xerrors.Errorf("set template variable value: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Set variable %s of template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, args[0]), pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		organizationDefaultOption(&organizationDefault),
+		{
+			Flag:        "sensitive",
+			Description: "Mark the value as sensitive. Sensitive values are redacted, and encrypted if database encryption is enabled. Values of variables marked as sensitive in the template are always sensitive.",
+			Value:       clibase.BoolOf(&sensitive),
+		},
+	}
+	return cmd
+}
+
+func (r *RootCmd) templateVariablesUnset() *clibase.Cmd {
+	var organizationDefault bool
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "unset [template] <name>",
+		Short: "Unset the value of a variable of a template",
+		Middleware: clibase.Chain(
+			clibase.RequireRangeArgs(1, 2),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			if err := checkTemplateVariablesArgs(inv, organizationDefault, 1); err != nil {
+				return err
+			}
+			organization, err := CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("get current organization: %w", err)
+			}
+			name := inv.Args[len(inv.Args)-1]
+
+			if organizationDefault {
+				err = client.DeleteOrganizationTemplateVariableValue(inv.Context(), organization.ID, name)
+				if err != nil {
+					return xerrors.Errorf("unset organization template variable value: %w", err)
+				}
+				_, _ = fmt.Fprintf(inv.Stdout, "Unset the default value of variable %s for organization %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, organization.Name))
+				return nil
+			}
+
+			template, err := client.TemplateByName(inv.Context(), organization.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("get template by name: %w", err)
+			}
+			err = client.DeleteTemplateVariableValue(inv.Context(), template.ID, name)
+			if err != nil {
+				return xerrors.Errorf("unset template variable value: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Unset variable %s of template %s.\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name), pretty.Sprint(cliui.DefaultStyles.Keyword, template.Name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{organizationDefaultOption(&organizationDefault)}
+	return cmd
+}
+
+func organizationDefaultOption(organizationDefault *bool) clibase.Option {
+	return clibase.Option{
+		Flag:        "organization-default",
+		Description: "Manage the default values of the organization, used by all of its templates, instead of the values of a template. The template argument is omitted.",
+		Value:       clibase.BoolOf(organizationDefault),
+	}
+}
+
+// checkTemplateVariablesArgs checks that the template argument is given unless
+// the default values of the organization are managed.
+func checkTemplateVariablesArgs(inv *clibase.Invocation, organizationDefault bool, n int) error {
+	if organizationDefault && len(inv.Args) != n {
+		return xerrors.Errorf("the template argument can't be used with --organization-default")
+	}
+	if !organizationDefault && len(inv.Args) != n+1 {
+		return xerrors.Errorf("a template is required, or --organization-default")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clitest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: []*proto.Response{{
+			Type: &proto.Response_Parse{
+				Parse: &proto.ParseComplete{
+					TemplateVariables: []*proto.TemplateVariable{
+						{Name: "region", Type: "string", DefaultValue: "us-east-1"},
+					},
+				},
+			},
+		}},
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	_ = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	inv, root := clitest.New(t, "templates", "variables", "set", template.Name, "region", "eu-west-1")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	values, err := client.TemplateVariableValues(ctx, template.ID)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, "eu-west-1", values[0].Value)
+
+	inv, root = clitest.New(t, "templates", "variables", "list", template.Name)
+	clitest.SetupConfig(t, client, root)
+	stdout := new(bytes.Buffer)
+	inv.Stdout = stdout
+	require.NoError(t, inv.WithContext(ctx).Run())
+	require.Contains(t, stdout.String(), "eu-west-1")
+
+	inv, root = clitest.New(t, "templates", "variables", "unset", template.Name, "region")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	values, err = client.TemplateVariableValues(ctx, template.ID)
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	// The template argument can't be used with --organization-default.
+	inv, root = clitest.New(t, "templates", "variables", "set", "--organization-default", template.Name, "region", "eu-west-1")
+	clitest.SetupConfig(t, client, root)
+	require.ErrorContains(t, inv.WithContext(ctx).Run(), "--organization-default")
+
+	inv, root = clitest.New(t, "templates", "variables", "set", "--organization-default", "region", "eu-west-1")
+	clitest.SetupConfig(t, client, root)
+	require.NoError(t, inv.WithContext(ctx).Run())
+
+	values, err = client.OrganizationTemplateVariableValues(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, "eu-west-1", values[0].Value)
+}
@@ -43,12 +43,14 @@ func (r *RootCmd) templateVersionsList() *clibase.Cmd {
 		"Name",
 		"Created At",
 		"Created By",
+		"Commit",
 		"Status",
 		"Active",
 	}
 	formatter := cliui.NewOutputFormatter(
 		cliui.TableFormat([]templateVersionRow{}, defaultColumns),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 	client := new(codersdk.Client)
 
@@ -134,6 +136,8 @@ type templateVersionRow struct {
 	Name      string    `json:"-" table:"name,default_sort"`
 	CreatedAt time.Time `json:"-" table:"created at"`
 	CreatedBy string    `json:"-" table:"created by"`
+	Commit    string    `json:"-" table:"commit"`
+	Branch    string    `json:"-" table:"branch"`
 	Status    string    `json:"-" table:"status"`
 	Active    string    `json:"-" table:"active"`
 	Archived  string    `json:"-" table:"archived"`
@@ -154,11 +158,22 @@ func templateVersionsToRows(activeVersionID uuid.UUID, templateVersions ...coder
 			archivedStatus = pretty.Sprint(cliui.DefaultStyles.Warn, "Archived")
 		}
 
+		var commit, branch string
+		if git := templateVersion.Git; git != nil {
+			commit = git.ShortCommitSHA()
+			if git.Tag != "" {
+				commit += " (" + git.Tag + ")"
+			}
+			branch = git.Branch
+		}
+
 		rows[i] = templateVersionRow{
 			TemplateVersion: templateVersion,
 			Name:            templateVersion.Name,
 			CreatedAt:       templateVersion.CreatedAt,
 			CreatedBy:       templateVersion.CreatedBy.Username,
+			Commit:          commit,
+			Branch:          branch,
 			Status:          strings.Title(string(templateVersion.Job.Status)),
 			Active:          activeStatus,
 			Archived:        archivedStatus,
@@ -48,6 +48,7 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 	var (
 		tokenLifetime time.Duration
 		name          string
+		ipAllowlist   []string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -59,8 +60,9 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 		),
 		Handler: func(inv *clibase.Invocation) error {
 			res, err := client.CreateToken(inv.Context(), codersdk.Me, codersdk.CreateTokenRequest{
-				Lifetime:  tokenLifetime,
-				TokenName: name,
+				Lifetime:    tokenLifetime,
+				TokenName:   name,
+				IPAllowlist: ipAllowlist,
 			})
 			if err != nil {
 				return xerrors.Errorf("create tokens: %w", err)
@@ -87,6 +89,12 @@ func (r *RootCmd) createToken() *clibase.Cmd {
 			Description:   "Specify a human-readable name.",
 			Value:         clibase.StringOf(&name),
 		},
+		{
+			Flag:        "ip-allowlist",
+			Env:         "CODER_TOKEN_IP_ALLOWLIST",
+			Description: "Limit the token to the given CIDR ranges or IP addresses.",
+			Value:       clibase.StringArrayOf(&ipAllowlist),
+		},
 	}
 
 	return cmd
@@ -131,6 +139,7 @@ func (r *RootCmd) listTokens() *clibase.Cmd {
 		formatter     = cliui.NewOutputFormatter(
 			cliui.TableFormat([]tokenListRow{}, defaultCols),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 		)
 	)
 
@@ -17,6 +17,7 @@ func (r *RootCmd) userList() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
 		cliui.TableFormat([]codersdk.User{}, []string{"username", "email", "created_at", "status"}),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 	client := new(codersdk.Client)
 
@@ -51,6 +52,7 @@ func (r *RootCmd) userSingle() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
 		&userShowFormat{},
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 	client := new(codersdk.Client)
 
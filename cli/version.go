@@ -66,6 +66,7 @@ func (*RootCmd) version(versionInfo func() *versionInfo) *clibase.Cmd {
 		formatter = cliui.NewOutputFormatter(
 			cliui.TextFormat(),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 		)
 		vi = versionInfo()
 	)
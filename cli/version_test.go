@@ -21,6 +21,7 @@ https://github.com/coder/coder
 Full build of Coder, supports the server subcommand.
 `
 	expectedJSON := `{
+  "output_schema_version": 1,
   "version": "v0.0.0-devel",
   "build_time": "0001-01-01T00:00:00Z",
   "external_url": "https://github.com/coder/coder",
@@ -28,6 +29,14 @@ Full build of Coder, supports the server subcommand.
   "agpl": false,
   "boring_crypto": false
 }
+`
+	expectedYAML := `output_schema_version: 1
+version: v0.0.0-devel
+build_time: "0001-01-01T00:00:00Z"
+external_url: https://github.com/coder/coder
+slim: false
+agpl: false
+boring_crypto: false
 `
 	for _, tt := range []struct {
 		Name     string
@@ -44,6 +53,11 @@ Full build of Coder, supports the server subcommand.
 			Args:     []string{"version", "--output=json"},
 			Expected: expectedJSON,
 		},
+		{
+			Name:     "YAML output",
+			Args:     []string{"version", "--output=yaml"},
+			Expected: expectedYAML,
+		},
 		{
 			Name:     "Text output",
 			Args:     []string{"version", "--output=text"},
@@ -155,6 +155,7 @@ func (r *RootCmd) vscodeSSH() *clibase.Cmd {
 			agentConn, err := client.DialWorkspaceAgent(ctx, agent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ConnectionType: codersdk.ConnectionTypeSSH,
 			})
 			if err != nil {
 				return xerrors.Errorf("dial workspace agent: %w", err)
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/agent/agentssh"
+)
+
+const x11AuthProtocol = "MIT-MAGIC-COOKIE-1"
+
+// x11Display is the address of a local X11 display.
+type x11Display struct {
+	network string
+	address string
+	screen  uint32
+}
+
+// parseX11Display parses $DISPLAY, e.g. ":0", "localhost:10.0" or the path
+// of the socket used by XQuartz on macOS.
+func parseX11Display(display string) (x11Display, error) {
+	if display == "" {
+		return x11Display{}, xerrors.New("DISPLAY is not set")
+	}
+	if strings.HasPrefix(display, "/") {
+		return x11Display{network: "unix", address: display}, nil
+	}
+
+	i := strings.LastIndex(display, ":")
+	if i < 0 {
+		return x11Display{}, xerrors.Errorf("invalid display %q", display)
+	}
+	host := display[:i]
+	number, screen, _ := strings.Cut(display[i+1:], ".")
+	displayNumber, err := strconv.ParseUint(number, 10, 16)
+	if err != nil {
+		return x11Display{}, xerrors.Errorf("invalid display number in %q", display)
+	}
+	var screenNumber uint64
+	if screen != "" {
+		screenNumber, err = strconv.ParseUint(screen, 10, 32)
+		if err != nil {
+			return x11Display{}, xerrors.Errorf("invalid screen number in %q", display)
+		}
+	}
+
+	if host == "" || host == "unix" {
+		return x11Display{
+			network: "unix",
+			address: fmt.Sprintf("/tmp/.X11-unix/X%d", displayNumber),
+			screen:  uint32(screenNumber),
+		}, nil
+	}
+	return x11Display{
+		network: "tcp",
+		address: net.JoinHostPort(host, strconv.FormatUint(6000+displayNumber, 10)),
+		screen:  uint32(screenNumber),
+	}, nil
+}
+
+// sshForwardX11 requests X11 forwarding for the session, and forwards the X11
+// connections opened in the workspace to the local display.
+//
+// Like OpenSSH, the workspace is given a fake cookie, which is replaced with
+// the real cookie of the local display in each connection. The real cookie
+// never leaves the local machine.
+func sshForwardX11(ctx context.Context, stderr io.Writer, sshClient *gossh.Client, sshSession *gossh.Session, display string) error {
+	local, err := parseX11Display(display)
+	if err != nil {
+		return err
+	}
+
+	realCookie, err := localX11Cookie(ctx, display)
+	if err != nil {
+		// The display may not require authentication, e.g. when xauth
+		// isn't installed.
+		_, _ = fmt.Fprintf(stderr, "Warning: No xauth data for display %s, forwarding X11 without authentication: %v\n", display, err)
+	}
+	fakeCookie := make([]byte, 16)
+	if len(realCookie) > 0 {
+		fakeCookie = make([]byte, len(realCookie))
+	}
+	_, err = rand.Read(fakeCookie)
+	if err != nil {
+		return xerrors.Errorf("generate cookie: %w", err)
+	}
+	if len(realCookie) == 0 {
+		realCookie = fakeCookie
+	}
+
+	channels := sshClient.HandleChannelOpen("x11")
+	if channels == nil {
+		return xerrors.New("X11 channels are already handled")
+	}
+	ok, err := sshSession.SendRequest("x11-req", true, gossh.Marshal(struct {
+		SingleConnection bool
+		AuthProtocol     string
+		AuthCookie       string
+		ScreenNumber     uint32
+	}{
+		SingleConnection: false,
+		AuthProtocol:     x11AuthProtocol,
+		AuthCookie:       hex.EncodeToString(fakeCookie),
+		ScreenNumber:     local.screen,
+	}))
+	if err != nil {
+		return xerrors.Errorf("request X11 forwarding: %w", err)
+	}
+	if !ok {
+		return xerrors.New("the workspace rejected X11 forwarding")
+	}
+
+	go func() {
+		for newChannel := range channels {
+			go forwardX11Channel(ctx, stderr, newChannel, local, fakeCookie, realCookie)
+		}
+	}()
+	return nil
+}
+
+func forwardX11Channel(ctx context.Context, stderr io.Writer, newChannel gossh.NewChannel, local x11Display, fakeCookie, realCookie []byte) {
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Accept X11 channel: %+v\n", err)
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+
+	setup, err := replaceX11Cookie(channel, fakeCookie, realCookie)
+	if err != nil {
+		_ = channel.Close()
+		_, _ = fmt.Fprintf(stderr, "Rejected X11 connection: %+v\n", err)
+		return
+	}
+	localConn, err := net.Dial(local.network, local.address)
+	if err != nil {
+		_ = channel.Close()
+		_, _ = fmt.Fprintf(stderr, "Dial X11 display %s: %+v\n", local.address, err)
+		return
+	}
+	_, err = localConn.Write(setup)
+	if err != nil {
+		_ = channel.Close()
+		_ = localConn.Close()
+		_, _ = fmt.Fprintf(stderr, "Write X11 connection setup: %+v\n", err)
+		return
+	}
+	agentssh.Bicopy(ctx, localConn, channel)
+}
+
+// localX11Cookie returns the MIT-MAGIC-COOKIE-1 cookie of the local display
+// from xauth.
+func localX11Cookie(ctx context.Context, display string) ([]byte, error) {
+	out, err := runLocal(ctx, nil, "xauth", "list", display)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[1] != x11AuthProtocol {
+			continue
+		}
+		cookie, err := hex.DecodeString(fields[2])
+		if err != nil {
+			continue
+		}
+		return cookie, nil
+	}
+	return nil, xerrors.Errorf("no %s cookie", x11AuthProtocol)
+}
+
+// replaceX11Cookie reads the connection setup of an X11 client, checks that
+// it authenticates with the fake cookie, and returns the setup with the real
+// cookie. Both cookies have the same length.
+func replaceX11Cookie(r io.Reader, fakeCookie, realCookie []byte) ([]byte, error) {
+	header := make([]byte, 12)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, xerrors.Errorf("read setup: %w", err)
+	}
+	var order binary.ByteOrder
+	switch header[0] {
+	case 'B':
+		order = binary.BigEndian
+	case 'l':
+		order = binary.LittleEndian
+	default:
+		return nil, xerrors.Errorf("invalid byte order %q", header[0])
+	}
+
+	protocolLen := int(order.Uint16(header[6:8]))
+	cookieLen := int(order.Uint16(header[8:10]))
+	body := make([]byte, x11Pad(protocolLen)+x11Pad(cookieLen))
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return nil, xerrors.Errorf("read setup: %w", err)
+	}
+
+	protocol := string(body[:protocolLen])
+	cookie := body[x11Pad(protocolLen) : x11Pad(protocolLen)+cookieLen]
+	if protocol != x11AuthProtocol || subtle.ConstantTimeCompare(cookie, fakeCookie) != 1 {
+		return nil, xerrors.New("invalid authentication cookie")
+	}
+	copy(cookie, realCookie)
+	return append(header, body...), nil
+}
+
+// x11Pad rounds n up to the 4 byte alignment of the X11 protocol.
+func x11Pad(n int) int {
+	return (n + 3) &^ 3
+}
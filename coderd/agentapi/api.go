@@ -70,6 +70,7 @@ type Options struct {
 	AgentInactiveDisconnectTimeout  time.Duration
 	AgentFallbackTroubleshootingURL string
 	AgentStatsRefreshInterval       time.Duration
+	AgentMetadataHistory            int64
 	DisableDirectConnections        bool
 	DerpForceWebSockets             bool
 	DerpMapUpdateFrequency          time.Duration
@@ -127,16 +128,18 @@ func New(opts Options) *API {
 
 	api.AppsAPI = &AppsAPI{
 		AgentFn:                  api.agent,
+		WorkspaceIDFn:            api.workspaceID,
 		Database:                 opts.Database,
 		Log:                      opts.Log,
 		PublishWorkspaceUpdateFn: api.publishWorkspaceUpdate,
 	}
 
 	api.MetadataAPI = &MetadataAPI{
-		AgentFn:  api.agent,
-		Database: opts.Database,
-		Pubsub:   opts.Pubsub,
-		Log:      opts.Log,
+		AgentFn:     api.agent,
+		Database:    opts.Database,
+		Pubsub:      opts.Pubsub,
+		Log:         opts.Log,
+		HistorySize: opts.AgentMetadataHistory,
 	}
 
 	api.LogsAPI = &LogsAPI{
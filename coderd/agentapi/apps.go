@@ -9,10 +9,12 @@ import (
 	"cdr.dev/slog"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 )
 
 type AppsAPI struct {
 	AgentFn                  func(context.Context) (database.WorkspaceAgent, error)
+	WorkspaceIDFn            func(context.Context, *database.WorkspaceAgent) (uuid.UUID, error)
 	Database                 database.Store
 	Log                      slog.Logger
 	PublishWorkspaceUpdateFn func(context.Context, *database.WorkspaceAgent) error
@@ -33,7 +35,10 @@ func (a *AppsAPI) BatchUpdateAppHealths(ctx context.Context, req *agentproto.Bat
 		return nil, xerrors.Errorf("get workspace apps by agent ID %q: %w", workspaceAgent.ID, err)
 	}
 
-	var newApps []database.WorkspaceApp
+	var (
+		newApps    []database.WorkspaceApp
+		oldHealths []database.WorkspaceAppHealth
+	)
 	for _, update := range req.Updates {
 		updateID, err := uuid.FromBytes(update.Id)
 		if err != nil {
@@ -75,6 +80,7 @@ func (a *AppsAPI) BatchUpdateAppHealths(ctx context.Context, req *agentproto.Bat
 		if old.Health == newHealth {
 			continue
 		}
+		oldHealths = append(oldHealths, old.Health)
 		old.Health = newHealth
 
 		newApps = append(newApps, *old)
@@ -90,6 +96,33 @@ func (a *AppsAPI) BatchUpdateAppHealths(ctx context.Context, req *agentproto.Bat
 		}
 	}
 
+	if len(newApps) > 0 {
+		workspaceID, err := a.WorkspaceIDFn(ctx, &workspaceAgent)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace ID: %w", err)
+		}
+		for i, app := range newApps {
+			// The health has already been updated, so a missing event
+			// isn't worth failing the request over.
+			err = a.Database.InsertWorkspaceEvent(ctx, database.InsertWorkspaceEventParams{
+				ID:          uuid.New(),
+				WorkspaceID: workspaceID,
+				CreatedAt:   dbtime.Now(),
+				Type:        database.WorkspaceEventTypeAppHealthChanged,
+				AgentID:     uuid.NullUUID{UUID: workspaceAgent.ID, Valid: true},
+				AppID:       uuid.NullUUID{UUID: app.ID, Valid: true},
+				OldValue:    string(oldHealths[i]),
+				NewValue:    string(app.Health),
+			})
+			if err != nil {
+				a.Log.Warn(ctx, "failed to insert workspace app health event",
+					slog.F("app_id", app.ID),
+					slog.Error(err),
+				)
+			}
+		}
+	}
+
 	err = a.PublishWorkspaceUpdateFn(ctx, &workspaceAgent)
 	if err != nil {
 		return nil, xerrors.Errorf("publish workspace update: %w", err)
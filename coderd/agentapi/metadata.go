@@ -20,6 +20,9 @@ type MetadataAPI struct {
 	Database database.Store
 	Pubsub   pubsub.Pubsub
 	Log      slog.Logger
+	// HistorySize is the number of values kept per metadatum. Zero disables
+	// the history.
+	HistorySize int64
 }
 
 func (a *MetadataAPI) BatchUpdateMetadata(ctx context.Context, req *agentproto.BatchUpdateMetadataRequest) (*agentproto.BatchUpdateMetadataResponse, error) {
@@ -90,6 +93,20 @@ func (a *MetadataAPI) BatchUpdateMetadata(ctx context.Context, req *agentproto.B
 		return nil, xerrors.Errorf("update workspace agent metadata in database: %w", err)
 	}
 
+	if a.HistorySize > 0 {
+		err = a.Database.InsertWorkspaceAgentMetadataHistory(ctx, database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: workspaceAgent.ID,
+			Key:              dbUpdate.Key,
+			Value:            dbUpdate.Value,
+			Error:            dbUpdate.Error,
+			CollectedAt:      dbUpdate.CollectedAt,
+			Keep:             int32(a.HistorySize),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("insert workspace agent metadata history in database: %w", err)
+		}
+	}
+
 	err = a.Pubsub.Publish(WatchWorkspaceAgentMetadataChannel(workspaceAgent.ID), payload)
 	if err != nil {
 		return nil, xerrors.Errorf("publish workspace agent metadata: %w", err)
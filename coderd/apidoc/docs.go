@@ -235,6 +235,12 @@ const docTemplate = `{
                         "description": "Page offset",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -342,6 +348,51 @@ const docTemplate = `{
                 }
             }
         },
+        "/connectionlogs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get connection logs",
+                "operationId": "get-connection-logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ConnectionLogResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/csp/reports": {
             "post": {
                 "security": [
@@ -397,6 +448,49 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/group-sync-runs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Get group sync runs",
+                "operationId": "get-group-sync-runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only return the login runs of the user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs to return, defaults to 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.GroupSyncRun"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/debug/health": {
             "get": {
                 "security": [
@@ -514,6 +608,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/tailnet/rotate-keys": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Rotate tailnet node keys",
+                "operationId": "rotate-tailnet-node-keys",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/debug/ws": {
             "get": {
                 "security": [
@@ -1652,6 +1771,88 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Delete organization",
+                "operationId": "delete-organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/organizations/{organization}/costs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get organization costs",
+                "operationId": "get-organization-costs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Start date, inclusive. Defaults to 29 days before the end date.",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "End date, inclusive. Defaults to today.",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OrganizationCosts"
+                        }
+                    }
+                }
             }
         },
         "/organizations/{organization}/groups": {
@@ -1778,7 +1979,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{organization}/members/roles": {
+        "/organizations/{organization}/members": {
             "get": {
                 "security": [
                     {
@@ -1791,8 +1992,8 @@ const docTemplate = `{
                 "tags": [
                     "Members"
                 ],
-                "summary": "Get member roles by organization",
-                "operationId": "get-member-roles-by-organization",
+                "summary": "List organization members",
+                "operationId": "list-organization-members",
                 "parameters": [
                     {
                         "type": "string",
@@ -1809,84 +2010,66 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                                "$ref": "#/definitions/codersdk.OrganizationMemberWithName"
                             }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/{user}/roles": {
-            "put": {
+        "/organizations/{organization}/members/roles": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Members"
                 ],
-                "summary": "Assign role to organization member",
-                "operationId": "assign-role-to-organization-member",
+                "summary": "Get member roles by organization",
+                "operationId": "get-member-roles-by-organization",
                 "parameters": [
                     {
                         "type": "string",
+                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update roles request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.OrganizationMember"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/{user}/workspaces": {
+        "/organizations/{organization}/members/{user}": {
             "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Members"
                 ],
-                "summary": "Create user workspace by organization",
-                "operationId": "create-user-workspace-by-organization",
+                "summary": "Add organization member",
+                "operationId": "add-organization-member",
                 "parameters": [
                     {
                         "type": "string",
@@ -1898,33 +2081,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Username, UUID, or me",
+                        "description": "User ID, name, or me",
                         "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create workspace request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.OrganizationMember"
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/provisionerdaemons": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1934,10 +2106,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Members"
                 ],
-                "summary": "Get provisioner daemons",
-                "operationId": "get-provisioner-daemons",
+                "summary": "Remove organization member",
+                "operationId": "remove-organization-member",
                 "parameters": [
                     {
                         "type": "string",
@@ -1946,87 +2118,79 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons/serve": {
-            "get": {
+        "/organizations/{organization}/members/{user}/roles": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Serve provisioner daemon",
-                "operationId": "serve-provisioner-daemon",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
-                    }
-                }
-            }
-        },
-        "/organizations/{organization}/templates": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
+                "summary": "Assign role to organization member",
+                "operationId": "assign-role-to-organization-member",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Template"
-                            }
+                            "$ref": "#/definitions/codersdk.OrganizationMember"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/organizations/{organization}/members/{user}/workspaces": {
             "post": {
                 "security": [
                     {
@@ -2040,39 +2204,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Workspaces"
                 ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
+                "summary": "Create user workspace by organization",
+                "operationId": "create-user-workspace-by-organization",
                 "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
-                        }
-                    },
                     {
                         "type": "string",
+                        "format": "uuid",
                         "description": "Organization ID",
                         "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username, UUID, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/examples": {
+        "/organizations/{organization}/provisionerdaemons": {
             "get": {
                 "security": [
                     {
@@ -2083,10 +2255,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template examples by organization",
-                "operationId": "get-template-examples-by-organization",
+                "summary": "Get provisioner daemons",
+                "operationId": "get-provisioner-daemons",
                 "parameters": [
                     {
                         "type": "string",
@@ -2103,28 +2275,25 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateExample"
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
                             }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}": {
+        "/organizations/{organization}/provisionerdaemons/serve": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get templates by organization and template name",
-                "operationId": "get-templates-by-organization-and-template-name",
+                "summary": "Serve provisioner daemon",
+                "operationId": "serve-provisioner-daemon",
                 "parameters": [
                     {
                         "type": "string",
@@ -2135,38 +2304,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Only acquire jobs from the organization",
+                        "name": "organization_scoped",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
-            "get": {
+        "/organizations/{organization}/provisionerjobs/{job}/priority": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Get template version by organization, template, and name",
-                "operationId": "get-template-version-by-organization-template-and-name",
+                "summary": "Update provisioner job priority",
+                "operationId": "update-provisioner-job-priority",
                 "parameters": [
                     {
                         "type": "string",
@@ -2178,30 +2346,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "job",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update priority request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateProvisionerJobPriorityRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
+        "/organizations/{organization}/provisionerkeys": {
             "get": {
                 "security": [
                     {
@@ -2212,10 +2383,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get previous template version by organization, template, and name",
-                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "summary": "List provisioner keys",
+                "operationId": "list-provisioner-keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -2224,33 +2395,20 @@ const docTemplate = `{
                         "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerKey"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/templateversions": {
+            },
             "post": {
                 "security": [
                     {
@@ -2264,10 +2422,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template version by organization",
-                "operationId": "create-template-version-by-organization",
+                "summary": "Create provisioner key",
+                "operationId": "create-provisioner-key",
                 "parameters": [
                     {
                         "type": "string",
@@ -2278,12 +2436,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create template version request",
+                        "description": "Create provisioner key request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                            "$ref": "#/definitions/codersdk.CreateProvisionerKeyRequest"
                         }
                     }
                 ],
@@ -2291,39 +2449,50 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.CreateProvisionerKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/regions": {
-            "get": {
+        "/organizations/{organization}/provisionerkeys/{provisionerkey}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "WorkspaceProxies"
+                    "Enterprise"
+                ],
+                "summary": "Delete provisioner key",
+                "operationId": "delete-provisioner-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provisioner key name",
+                        "name": "provisionerkey",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get site-wide regions for workspace connections",
-                "operationId": "get-site-wide-regions-for-workspace-connections",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/replicas": {
-            "get": {
+        "/organizations/{organization}/provisionerkeys/{provisionerkey}/rotate": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2335,22 +2504,36 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
+                "summary": "Rotate provisioner key",
+                "operationId": "rotate-provisioner-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provisioner key name",
+                        "name": "provisionerkey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Replica"
-                            }
+                            "$ref": "#/definitions/codersdk.CreateProvisionerKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users": {
+        "/organizations/{organization}/quota": {
             "get": {
                 "security": [
                     {
@@ -2358,41 +2541,65 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
-                "responses": {
+                "summary": "Get organization quota",
+                "operationId": "get-organization-quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OrganizationQuota"
+                        }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "Update organization quota",
+                "operationId": "update-organization-quota",
                 "parameters": [
                     {
-                        "description": "New user",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Organization quota",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.OrganizationQuota"
                         }
                     }
                 ],
@@ -2400,13 +2607,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.OrganizationQuota"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
+        "/organizations/{organization}/template-variables": {
             "get": {
                 "security": [
                     {
@@ -2414,59 +2621,77 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "Get organization template variable values",
+                "operationId": "get-organization-template-variable-values",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVariableValue"
+                            }
+                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/template-variables/{variable}": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "SCIM 2.0: Update user account",
-                "operationId": "scim-update-user-status",
+                "summary": "Upsert organization template variable value",
+                "operationId": "upsert-organization-template-variable-value",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update user request",
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "variable",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upsert template variable value request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.UpsertTemplateVariableValueRequest"
                         }
                     }
                 ],
@@ -2474,47 +2699,48 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVariableValue"
                         }
                     }
                 }
-            }
-        },
-        "/templates/{template}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template metadata by ID",
-                "operationId": "get-template-metadata-by-id",
+                "summary": "Delete organization template variable value",
+                "operationId": "delete-organization-template-variable-value",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "variable",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/organizations/{organization}/templates": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2526,14 +2752,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Delete template by ID",
-                "operationId": "delete-template-by-id",
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2542,31 +2768,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Template"
+                            }
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Update template metadata by ID",
-                "operationId": "update-template-metadata-by-id",
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
                 "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2581,7 +2821,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/templates/{template}/acl": {
+        "/organizations/{organization}/templates/examples": {
             "get": {
                 "security": [
                     {
@@ -2592,16 +2832,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get template ACLs",
-                "operationId": "get-template-acls",
+                "summary": "Get template examples by organization",
+                "operationId": "get-template-examples-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2612,59 +2852,50 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateUser"
+                                "$ref": "#/definitions/codersdk.TemplateExample"
                             }
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/templates/examples/{example}/archive": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Update template ACL",
-                "operationId": "update-template-acl",
+                "summary": "Get template example archive by organization",
+                "operationId": "get-template-example-archive-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update template request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
-                        }
+                        "type": "string",
+                        "description": "Example ID",
+                        "name": "example",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/templates/{template}/acl/available": {
+        "/organizations/{organization}/templates/{templatename}": {
             "get": {
                 "security": [
                     {
@@ -2675,16 +2906,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get template available acl users/groups",
-                "operationId": "get-template-available-acl-usersgroups",
+                "summary": "Get templates by organization and template name",
+                "operationId": "get-templates-by-organization-and-template-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
                         "in": "path",
                         "required": true
                     }
@@ -2693,16 +2931,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ACLAvailable"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/daus": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
@@ -2715,14 +2950,28 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
+                "summary": "Get template version by organization, template, and name",
+                "operationId": "get-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -2731,13 +2980,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
             "get": {
                 "security": [
                     {
@@ -2750,56 +2999,44 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "List template versions by template ID",
-                "operationId": "list-template-versions-by-template-id",
+                "summary": "Get previous template version by organization, template, and name",
+                "operationId": "get-previous-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Include archived versions in the list",
-                        "name": "include_archived",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/templateversions": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2814,85 +3051,71 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Update active template version by template ID",
-                "operationId": "update-active-template-version-by-template-id",
+                "summary": "Create template version by organization",
+                "operationId": "create-template-version-by-organization",
                 "parameters": [
                     {
-                        "description": "Modified template version",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create template version request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions/archive": {
-            "post": {
+        "/provisionerdaemons/cache": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Builds"
                 ],
-                "summary": "Archive template unused versions by template id",
-                "operationId": "archive-template-unused-versions-by-template-id",
+                "summary": "Purge provisioner cache",
+                "operationId": "purge-provisioner-cache",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Archive request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.ArchiveTemplateVersionsRequest"
-                        }
+                        "description": "Only purge entries cached longer ago than this duration, e.g. 720h",
+                        "name": "older_than",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.PurgeProvisionerCacheResponse"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions/{templateversionname}": {
+        "/regions": {
             "get": {
                 "security": [
                     {
@@ -2903,41 +3126,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "WorkspaceProxies"
                 ],
-                "summary": "Get template version by template ID and name",
-                "operationId": "get-template-version-by-template-id-and-name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
+                "summary": "Get site-wide regions for workspace connections",
+                "operationId": "get-site-wide-regions-for-workspace-connections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
+                        }
+                    }
+                }
+            }
+        },
+        "/replicas": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                                "$ref": "#/definitions/codersdk.Replica"
                             }
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}": {
+        "/scim/v2/Groups": {
             "get": {
                 "security": [
                     {
@@ -2945,65 +3176,41 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template version by ID",
-                "operationId": "get-template-version-by-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "SCIM 2.0: Get groups",
+                "operationId": "scim-get-groups",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
-                        }
+                        "description": "OK"
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Patch template version by ID",
-                "operationId": "patch-template-version-by-id",
+                "summary": "SCIM 2.0: Create new group",
+                "operationId": "scim-create-new-group",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Patch template version request",
+                        "description": "New group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 ],
@@ -3011,33 +3218,33 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/archive": {
-            "post": {
+        "/scim/v2/Groups/{id}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Archive template version",
-                "operationId": "archive-template-version",
+                "summary": "SCIM 2.0: Get group by ID",
+                "operationId": "scim-get-group-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -3046,95 +3253,82 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/cancel": {
-            "patch": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version by ID",
-                "operationId": "cancel-template-version-by-id",
+                "summary": "SCIM 2.0: Delete group",
+                "operationId": "scim-delete-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Create template version dry-run",
-                "operationId": "create-template-version-dry-run",
+                "summary": "SCIM 2.0: Update group",
+                "operationId": "scim-update-group",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dry-run request",
+                        "description": "Patch operations",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
+                            "$ref": "#/definitions/coderd.SCIMPatchOp"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}": {
+        "/scim/v2/Users": {
             "get": {
                 "security": [
                     {
@@ -3142,43 +3336,20 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template version dry-run by job ID",
-                "operationId": "get-template-version-dry-run-by-job-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
-                        }
+                        "description": "OK"
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
-            "patch": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3188,39 +3359,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version dry-run by job ID",
-                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "New user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
+        "/scim/v2/Users/{id}": {
             "get": {
                 "security": [
                     {
@@ -3228,63 +3392,73 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version dry-run logs by job ID",
-                "operationId": "get-template-version-dry-run-logs-by-job-id",
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
+                    }
+                ],
+                "responses": {
+                    "404": {
+                        "description": "Not Found"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/scim+json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Update user account",
+                "operationId": "scim-update-user-status",
+                "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
+                        "description": "Update user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+        "/sessionrecordings": {
             "get": {
                 "security": [
                     {
@@ -3295,25 +3469,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Audit"
                 ],
-                "summary": "Get template version dry-run resources by job ID",
-                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "summary": "Get session recordings of workspace",
+                "operationId": "get-session-recordings-of-workspace",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
-                        "in": "path",
+                        "description": "Workspace ID",
+                        "name": "workspace_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -3323,52 +3489,43 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                                "$ref": "#/definitions/codersdk.SessionRecording"
                             }
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/external-auth": {
+        "/sessionrecordings/{sessionrecording}": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Templates"
+                    "Audit"
                 ],
-                "summary": "Get external auth by template version",
-                "operationId": "get-external-auth-by-template-version",
+                "summary": "Get session recording",
+                "operationId": "get-session-recording",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Session recording ID",
+                        "name": "sessionrecording",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionExternalAuth"
-                            }
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/logs": {
+        "/templates/{template}": {
             "get": {
                 "security": [
                     {
@@ -3381,80 +3538,61 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get logs by template version",
-                "operationId": "get-logs-by-template-version",
+                "summary": "Get template metadata by ID",
+                "operationId": "get-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/parameters": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Removed: Get parameters by template version",
-                "operationId": "removed-get-parameters-by-template-version",
+                "summary": "Delete template by ID",
+                "operationId": "delete-template-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/resources": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3466,14 +3604,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get resources by template version",
-                "operationId": "get-resources-by-template-version",
+                "summary": "Update template metadata by ID",
+                "operationId": "update-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3482,16 +3620,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/rich-parameters": {
+        "/templates/{template}/acl": {
             "get": {
                 "security": [
                     {
@@ -3502,16 +3637,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get rich parameters by template version",
-                "operationId": "get-rich-parameters-by-template-version",
+                "summary": "Get template ACLs",
+                "operationId": "get-template-acls",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3522,44 +3657,60 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                                "$ref": "#/definitions/codersdk.TemplateUser"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/schema": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Removed: Get schema by template version",
-                "operationId": "removed-get-schema-by-template-version",
+                "summary": "Update template ACL",
+                "operationId": "update-template-acl",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update template request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/unarchive": {
-            "post": {
+        "/templates/{template}/acl/available": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3569,16 +3720,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Unarchive template version",
-                "operationId": "unarchive-template-version",
+                "summary": "Get template available acl users/groups",
+                "operationId": "get-template-available-acl-usersgroups",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3587,13 +3738,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ACLAvailable"
+                            }
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/variables": {
+        "/templates/{template}/daus": {
             "get": {
                 "security": [
                     {
@@ -3606,14 +3760,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template variables by template version",
-                "operationId": "get-template-variables-by-template-version",
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3622,36 +3776,64 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
-                            }
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
                         }
                     }
                 }
             }
         },
-        "/updatecheck": {
+        "/templates/{template}/insights": {
             "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Insights"
+                ],
+                "summary": "Get insights about a template",
+                "operationId": "get-insights-about-a-template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Update check",
-                "operationId": "update-check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
+                            "$ref": "#/definitions/codersdk.TemplateSummaryInsightsResponse"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/templates/{template}/variables": {
             "get": {
                 "security": [
                     {
@@ -3662,47 +3844,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get users",
-                "operationId": "get-users",
+                "summary": "Get template variable values",
+                "operationId": "get-template-variable-values",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVariableValue"
+                            }
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/templates/{template}/variables/{variable}": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3715,57 +3885,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create new user",
-                "operationId": "create-new-user",
+                "summary": "Upsert template variable value",
+                "operationId": "upsert-template-variable-value",
                 "parameters": [
                     {
-                        "description": "Create user request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "variable",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upsert template variable value request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                            "$ref": "#/definitions/codersdk.UpsertTemplateVariableValueRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVariableValue"
                         }
                     }
                 }
-            }
-        },
-        "/users/authmethods": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "Delete template variable value",
+                "operationId": "delete-template-variable-value",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "variable",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get authentication methods",
-                "operationId": "get-authentication-methods",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.AuthMethods"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/users/first": {
+        "/templates/{template}/versions": {
             "get": {
                 "security": [
                     {
@@ -3776,20 +3970,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
+                ],
+                "summary": "List template versions by template ID",
+                "operationId": "list-template-versions-by-template-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include archived versions in the list",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Check initial user created",
-                "operationId": "check-initial-user-created",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3802,33 +4034,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create initial user",
-                "operationId": "create-initial-user",
+                "summary": "Update active template version by template ID",
+                "operationId": "update-active-template-version-by-template-id",
                 "parameters": [
                     {
-                        "description": "First user request",
+                        "description": "Modified template version",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
+        "/templates/{template}/versions/archive": {
             "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -3836,46 +4081,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authorization"
+                    "Templates"
                 ],
-                "summary": "Log in user",
-                "operationId": "log-in-user",
+                "summary": "Archive template unused versions by template id",
+                "operationId": "archive-template-unused-versions-by-template-id",
                 "parameters": [
                     {
-                        "description": "Login request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Archive request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                            "$ref": "#/definitions/codersdk.ArchiveTemplateVersionsRequest"
                         }
                     }
-                }
-            }
-        },
-        "/users/logout": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
                 ],
-                "summary": "Log out user",
-                "operationId": "log-out-user",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -3886,73 +4114,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/oauth2/github/callback": {
+        "/templates/{template}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "OAuth 2.0 GitHub Callback",
-                "operationId": "oauth-20-github-callback",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
-                    }
-                }
-            }
-        },
-        "/users/oidc/callback": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "OpenID Connect Callback",
-                "operationId": "openid-connect-callback",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
-                    }
-                }
-            }
-        },
-        "/users/roles": {
-            "get": {
-                "security": [
+                "summary": "Get template version by template ID and name",
+                "operationId": "get-template-version-by-template-id-and-name",
+                "parameters": [
                     {
-                        "CoderSessionToken": []
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Members"
-                ],
-                "summary": "Get site member roles",
-                "operationId": "get-site-member-roles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
                             }
                         }
                     }
                 }
             }
         },
-        "/users/{user}": {
+        "/templates/{template}/workspace-name": {
             "get": {
                 "security": [
                     {
@@ -3963,15 +4170,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user by name",
-                "operationId": "get-user-by-name",
+                "summary": "Generate workspace name for template",
+                "operationId": "generate-workspace-name-for-template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, username, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3980,12 +4188,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.GenerateWorkspaceNameResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/templateversions/{templateversion}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3995,15 +4205,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Delete user",
-                "operationId": "delete-user",
+                "summary": "Get template version by ID",
+                "operationId": "get-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4012,14 +4223,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/appearance": {
-            "put": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4032,25 +4241,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Update user appearance settings",
-                "operationId": "update-user-appearance-settings",
+                "summary": "Patch template version by ID",
+                "operationId": "patch-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New appearance settings",
+                        "description": "Patch template version request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserAppearanceSettingsRequest"
+                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
                         }
                     }
                 ],
@@ -4058,60 +4268,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/convert-login": {
-            "post": {
+        "/templateversions/{templateversion}/archive": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Authorization"
+                    "Templates"
                 ],
-                "summary": "Convert user from password to oauth authentication",
-                "operationId": "convert-user-from-password-to-oauth-authentication",
+                "summary": "Get template version archive",
+                "operationId": "get-template-version-archive",
                 "parameters": [
-                    {
-                        "description": "Convert request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.ConvertLoginRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.OAuthConversionResponse"
-                        }
+                    "200": {
+                        "description": "OK"
                     }
                 }
-            }
-        },
-        "/users/{user}/gitsshkey": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4121,15 +4312,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user Git SSH key",
-                "operationId": "get-user-git-ssh-key",
+                "summary": "Archive template version",
+                "operationId": "archive-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4138,12 +4330,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/templateversions/{templateversion}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4153,15 +4347,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Regenerate user SSH key",
-                "operationId": "regenerate-user-ssh-key",
+                "summary": "Cancel template version by ID",
+                "operationId": "cancel-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4170,14 +4365,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys": {
-            "post": {
+        "/templateversions/{templateversion}/diff": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4187,65 +4382,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create new session key",
-                "operationId": "create-new-session-key",
+                "summary": "Get template version diff",
+                "operationId": "get-template-version-diff",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{user}/keys/tokens": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get user tokens",
-                "operationId": "get-user-tokens",
-                "parameters": [
+                    },
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "format": "uuid",
+                        "description": "Base template version ID, defaults to the active version of the template",
+                        "name": "base",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.APIKey"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateVersionDiff"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/templateversions/{templateversion}/dry-run": {
             "post": {
                 "security": [
                     {
@@ -4259,25 +4427,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create token API key",
-                "operationId": "create-token-api-key",
+                "summary": "Create template version dry-run",
+                "operationId": "create-template-version-dry-run",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create token request",
+                        "description": "Dry-run request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
                         }
                     }
                 ],
@@ -4285,13 +4454,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/tokenconfig": {
+        "/templateversions/{templateversion}/dry-run/{jobID}": {
             "get": {
                 "security": [
                     {
@@ -4302,15 +4471,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Templates"
                 ],
-                "summary": "Get token config",
-                "operationId": "get-token-config",
+                "summary": "Get template version dry-run by job ID",
+                "operationId": "get-template-version-dry-run-by-job-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
                         "in": "path",
                         "required": true
                     }
@@ -4319,14 +4497,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TokenConfig"
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/{keyname}": {
-            "get": {
+        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4336,23 +4514,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get API key by token name",
-                "operationId": "get-api-key-by-token-name",
+                "summary": "Cancel template version dry-run by job ID",
+                "operationId": "cancel-template-version-dry-run-by-job-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "Key Name",
-                        "name": "keyname",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4361,13 +4540,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/{keyid}": {
+        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
             "get": {
                 "security": [
                     {
@@ -4378,72 +4557,106 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get API key by ID",
-                "operationId": "get-api-key-by-id",
+                "summary": "Get template version dry-run logs by job ID",
+                "operationId": "get-template-version-dry-run-logs-by-job-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
+                        "description": "Job ID",
+                        "name": "jobID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
+                "summary": "Get template version dry-run resources by job ID",
+                "operationId": "get-template-version-dry-run-resources-by-job-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
+                        "description": "Job ID",
+                        "name": "jobID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/login-type": {
+        "/templateversions/{templateversion}/external-auth": {
             "get": {
                 "security": [
                     {
@@ -4454,15 +4667,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user login type",
-                "operationId": "get-user-login-type",
+                "summary": "Get external auth by template version",
+                "operationId": "get-external-auth-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4471,13 +4685,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserLoginType"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionExternalAuth"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations": {
+        "/templateversions/{templateversion}/logs": {
             "get": {
                 "security": [
                     {
@@ -4488,17 +4705,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Get logs by template version",
+                "operationId": "get-logs-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4507,96 +4743,80 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
                             }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations/{organizationname}": {
+        "/templateversions/{templateversion}/parameters": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Removed: Get parameters by template version",
+                "operationId": "removed-get-parameters-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/users/{user}/password": {
-            "put": {
+        "/templateversions/{templateversion}/presets": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Get template version presets",
+                "operationId": "get-template-version-presets",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update password request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionPreset"
+                            }
+                        }
                     }
                 }
-            }
-        },
-        "/users/{user}/profile": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4609,109 +4829,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Create template version preset",
+                "operationId": "create-template-version-preset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated profile",
+                        "description": "Create template version preset request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionPresetRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersionPreset"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/quiet-hours": {
-            "get": {
+        "/templateversions/{templateversion}/presets/{preset}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get user quiet hours schedule",
-                "operationId": "get-user-quiet-hours-schedule",
+                "summary": "Delete template version preset",
+                "operationId": "delete-template-version-preset",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "user",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "preset",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/templateversions/{templateversion}/resources": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Update user quiet hours schedule",
-                "operationId": "update-user-quiet-hours-schedule",
+                "summary": "Get resources by template version",
+                "operationId": "get-resources-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "user",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update schedule request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -4720,14 +4929,14 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
                             }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/roles": {
+        "/templateversions/{templateversion}/rich-parameters": {
             "get": {
                 "security": [
                     {
@@ -4738,15 +4947,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
+                "summary": "Get rich parameters by template version",
+                "operationId": "get-rich-parameters-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4755,58 +4965,46 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/templateversions/{templateversion}/schema": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Assign role to user",
-                "operationId": "assign-role-to-user",
+                "summary": "Removed: Get schema by template version",
+                "operationId": "removed-get-schema-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update roles request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/users/{user}/status/activate": {
-            "put": {
+        "/templateversions/{templateversion}/unarchive": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4816,15 +5014,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Activate user account",
-                "operationId": "activate-user-account",
+                "summary": "Unarchive template version",
+                "operationId": "unarchive-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4833,14 +5032,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/suspend": {
-            "put": {
+        "/templateversions/{templateversion}/variables": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4850,15 +5049,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Suspend user account",
-                "operationId": "suspend-user-account",
+                "summary": "Get template variables by template version",
+                "operationId": "get-template-variables-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4867,13 +5067,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}": {
+        "/two-factor/policies": {
             "get": {
                 "security": [
                     {
@@ -4884,165 +5087,166 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
-                ],
-                "summary": "Get workspace metadata by user and workspace name",
-                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
-                    }
+                    "Authorization"
                 ],
+                "summary": "Get two-factor policies",
+                "operationId": "get-two-factor-policies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TwoFactorPolicy"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
-            "get": {
+        "/two-factor/policies/{role}": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Authorization"
                 ],
-                "summary": "Get workspace build by user, workspace name, and build number",
-                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "summary": "Upsert two-factor policy",
+                "operationId": "upsert-two-factor-policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
+                        "description": "Site role name",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "number",
-                        "description": "Build number",
-                        "name": "buildnumber",
-                        "in": "path",
-                        "required": true
+                        "description": "Upsert two-factor policy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpsertTwoFactorPolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.TwoFactorPolicy"
                         }
                     }
                 }
-            }
-        },
-        "/workspace-quota/{user}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Authorization"
                 ],
-                "summary": "Get workspace quota by user",
-                "operationId": "get-workspace-quota-by-user",
+                "summary": "Delete two-factor policy",
+                "operationId": "delete-two-factor-policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "description": "Site role name",
+                        "name": "role",
                         "in": "path",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/updatecheck": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Update check",
+                "operationId": "update-check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/aws-instance-identity": {
-            "post": {
+        "/users": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Authenticate agent on AWS instance",
-                "operationId": "authenticate-agent-on-aws-instance",
+                "summary": "Get users",
+                "operationId": "get-users",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
-                        }
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.GetUsersResponse"
                         }
                     }
                 }
-            }
-        },
-        "/workspaceagents/azure-instance-identity": {
+            },
             "post": {
                 "security": [
                     {
@@ -5056,32 +5260,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Authenticate agent on Azure instance",
-                "operationId": "authenticate-agent-on-azure-instance",
+                "summary": "Create new user",
+                "operationId": "create-new-user",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "description": "Create user request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.CreateUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/connection": {
+        "/users/authmethods": {
             "get": {
                 "security": [
                     {
@@ -5092,63 +5296,44 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get connection info for workspace agent generic",
-                "operationId": "get-connection-info-for-workspace-agent-generic",
+                "summary": "Get authentication methods",
+                "operationId": "get-authentication-methods",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.AuthMethods"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/google-instance-identity": {
-            "post": {
+        "/users/first": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Authenticate agent on Google Cloud instance",
-                "operationId": "authenticate-agent-on-google-cloud-instance",
-                "parameters": [
-                    {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
-                        }
-                    }
+                    "Users"
                 ],
+                "summary": "Check initial user created",
+                "operationId": "check-initial-user-created",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            }
-        },
-        "/workspaceagents/me/app-health": {
+            },
             "post": {
                 "security": [
                     {
@@ -5162,96 +5347,99 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Submit workspace agent application health",
-                "operationId": "submit-workspace-agent-application-health",
+                "summary": "Create initial user",
+                "operationId": "create-initial-user",
                 "parameters": [
                     {
-                        "description": "Application health request",
+                        "description": "First user request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
+                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/coordinate": {
+        "/users/inactive": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Users"
+                ],
+                "summary": "Get inactive users",
+                "operationId": "get-inactive-users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Days without activity, defaults to 30",
+                        "name": "days",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Coordinate workspace agent via Tailnet",
-                "operationId": "coordinate-workspace-agent-via-tailnet",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.InactiveUsersResponse"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/external-auth": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
+        "/users/ldap/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Authorization"
                 ],
-                "summary": "Get workspace agent external auth",
-                "operationId": "get-workspace-agent-external-auth",
+                "summary": "Log in user with LDAP",
+                "operationId": "log-in-user-with-ldap",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Match",
-                        "name": "match",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Provider ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Wait for a new token to be issued",
-                        "name": "listen",
-                        "in": "query"
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithLDAPRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.ExternalAuthResponse"
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitauth": {
+        "/users/lockouts": {
             "get": {
                 "security": [
                     {
@@ -5262,74 +5450,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Removed: Get workspace agent git auth",
-                "operationId": "removed-get-workspace-agent-git-auth",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Match",
-                        "name": "match",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Provider ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Wait for a new token to be issued",
-                        "name": "listen",
-                        "in": "query"
-                    }
+                    "Users"
                 ],
+                "summary": "Get locked out users",
+                "operationId": "get-locked-out-users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.ExternalAuthResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserLockout"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitsshkey": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Authorization"
+                ],
+                "summary": "Log in user",
+                "operationId": "log-in-user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
                 ],
-                "summary": "Get workspace agent Git SSH key",
-                "operationId": "get-workspace-agent-git-ssh-key",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/logs": {
-            "patch": {
-                "security": [
+        "/users/login/passkey-enrollment": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Log in user with passkey enrollment",
+                "operationId": "log-in-user-with-passkey-enrollment",
+                "parameters": [
                     {
-                        "CoderSessionToken": []
+                        "description": "Create passkey request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreatePasskeyRequest"
+                        }
                     }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/second-factor": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -5337,33 +5544,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Authorization"
                 ],
-                "summary": "Patch workspace agent logs",
-                "operationId": "patch-workspace-agent-logs",
+                "summary": "Log in user with second factor",
+                "operationId": "log-in-user-with-second-factor",
                 "parameters": [
                     {
-                        "description": "logs",
+                        "description": "Second factor login request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                            "$ref": "#/definitions/codersdk.LoginWithSecondFactorRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/manifest": {
-            "get": {
+        "/users/logout": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5373,141 +5580,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get authorized workspace agent manifest",
-                "operationId": "get-authorized-workspace-agent-manifest",
+                "summary": "Log out user",
+                "operationId": "log-out-user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Manifest"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/metadata": {
-            "post": {
+        "/users/oauth2/github/callback": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Submit workspace agent metadata",
-                "operationId": "submit-workspace-agent-metadata",
-                "parameters": [
-                    {
-                        "description": "Workspace agent metadata request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/agentsdk.PostMetadataRequest"
-                            }
-                        }
-                    }
+                    "Users"
                 ],
+                "summary": "OAuth 2.0 GitHub Callback",
+                "operationId": "oauth-20-github-callback",
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "307": {
+                        "description": "Temporary Redirect"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/metadata/{key}": {
-            "post": {
+        "/users/oidc/callback": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Removed: Submit workspace agent metadata",
-                "operationId": "removed-submit-workspace-agent-metadata",
-                "parameters": [
-                    {
-                        "description": "Workspace agent metadata request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostMetadataRequestDeprecated"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "metadata key",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Users"
                 ],
+                "summary": "OpenID Connect Callback",
+                "operationId": "openid-connect-callback",
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "307": {
+                        "description": "Temporary Redirect"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-lifecycle": {
-            "post": {
+        "/users/roles": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Members"
                 ],
-                "summary": "Submit workspace agent lifecycle state",
-                "operationId": "submit-workspace-agent-lifecycle-state",
-                "parameters": [
-                    {
-                        "description": "Workspace agent lifecycle request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get site member roles",
+                "operationId": "get-site-member-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
                         }
                     }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "Success"
-                    }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspaceagents/me/report-stats": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5520,18 +5672,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Members"
                 ],
-                "summary": "Submit workspace agent stats",
-                "operationId": "submit-workspace-agent-stats",
+                "summary": "Upsert a custom site role",
+                "operationId": "upsert-a-custom-site-role",
                 "parameters": [
                     {
-                        "description": "Stats request",
+                        "description": "Upsert role request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Stats"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 ],
@@ -5539,114 +5691,74 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                            "$ref": "#/definitions/codersdk.CustomRole"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/rpc": {
+        "/users/roles/custom": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Members"
                 ],
-                "summary": "Workspace agent RPC API",
-                "operationId": "workspace-agent-rpc-api",
+                "summary": "Get custom site roles",
+                "operationId": "get-custom-site-roles",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.CustomRole"
+                            }
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Agents"
-                ],
-                "summary": "Submit workspace agent startup",
-                "operationId": "submit-workspace-agent-startup",
-                "parameters": [
-                    {
-                        "description": "Startup request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                },
-                "x-apidocgen": {
-                    "skip": true
-                }
-            }
-        },
-        "/workspaceagents/me/startup-logs": {
-            "patch": {
+        "/users/{user}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Removed: Patch workspace agent logs",
-                "operationId": "removed-patch-workspace-agent-logs",
+                "summary": "Get user by name",
+                "operationId": "get-user-by-name",
                 "parameters": [
                     {
-                        "description": "logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
-                        }
+                        "type": "string",
+                        "description": "User ID, username, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
-            }
-        },
-        "/workspaceagents/{workspaceagent}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5656,16 +5768,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get workspace agent by ID",
-                "operationId": "get-workspace-agent-by-id",
+                "summary": "Delete user",
+                "operationId": "delete-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -5674,77 +5785,105 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/connection": {
-            "get": {
+        "/users/{user}/appearance": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get connection info for workspace agent",
-                "operationId": "get-connection-info-for-workspace-agent",
+                "summary": "Update user appearance settings",
+                "operationId": "update-user-appearance-settings",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New appearance settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserAppearanceSettingsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/coordinate": {
-            "get": {
+        "/users/{user}/convert-login": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Authorization"
                 ],
-                "summary": "Coordinate workspace agent",
-                "operationId": "coordinate-workspace-agent",
+                "summary": "Convert user from password to oauth authentication",
+                "operationId": "convert-user-from-password-to-oauth-authentication",
                 "parameters": [
+                    {
+                        "description": "Convert request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ConvertLoginRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuthConversionResponse"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/legacy": {
+        "/users/{user}/gitsshkey": {
             "get": {
                 "security": [
                     {
@@ -5755,16 +5894,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Agent is legacy",
-                "operationId": "agent-is-legacy",
+                "summary": "Get user Git SSH key",
+                "operationId": "get-user-git-ssh-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace Agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -5773,17 +5911,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.AgentIsLegacyResponse"
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspaceagents/{workspaceagent}/listening-ports": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5793,16 +5926,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get listening ports for workspace agent",
-                "operationId": "get-listening-ports-for-workspace-agent",
+                "summary": "Regenerate user SSH key",
+                "operationId": "regenerate-user-ssh-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -5811,13 +5943,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/logs": {
+        "/users/{user}/inbox": {
             "get": {
                 "security": [
                     {
@@ -5828,41 +5960,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Get logs by workspace agent",
-                "operationId": "get-logs-by-workspace-agent",
+                "summary": "Get user inbox notifications",
+                "operationId": "get-user-inbox-notifications",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
+                        "type": "boolean",
+                        "description": "Only return unread notifications",
+                        "name": "unread_only",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Disable compression for WebSocket connection",
-                        "name": "no_compression",
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
                         "in": "query"
                     }
                 ],
@@ -5870,174 +6002,161 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
-                            }
+                            "$ref": "#/definitions/codersdk.InboxNotificationsResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/pty": {
-            "get": {
+        "/users/{user}/inbox/read": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Open PTY to workspace agent",
-                "operationId": "open-pty-to-workspace-agent",
+                "summary": "Mark user inbox notifications as read",
+                "operationId": "mark-user-inbox-notifications-as-read",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Notifications to mark as read",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.MarkInboxNotificationsReadRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/startup-logs": {
+        "/users/{user}/inbox/watch": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "It accepts a WebSocket connection and writes an event for\nevery notification that is added to the inbox of the user.",
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Removed: Get logs by workspace agent",
-                "operationId": "removed-get-logs-by-workspace-agent",
+                "summary": "Watch user inbox notifications",
+                "operationId": "watch-user-inbox-notifications",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Disable compression for WebSocket connection",
-                        "name": "no_compression",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
-                            }
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/watch-metadata": {
+        "/users/{user}/ip-allowlist": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Watch for workspace agent metadata updates",
-                "operationId": "watch-for-workspace-agent-metadata-updates",
+                "summary": "Get user IP allowlist",
+                "operationId": "get-user-ip-allowlist",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UserIPAllowlist"
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Get workspace build",
-                "operationId": "get-workspace-build",
+                "summary": "Update user IP allowlist",
+                "operationId": "update-user-ip-allowlist",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New IP allowlist",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserIPAllowlistRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.UserIPAllowlist"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/cancel": {
-            "patch": {
+        "/users/{user}/keys": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6047,30 +6166,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Cancel workspace build",
-                "operationId": "cancel-workspace-build",
+                "summary": "Create new session key",
+                "operationId": "create-new-session-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/logs": {
+        "/users/{user}/keys/tokens": {
             "get": {
                 "security": [
                     {
@@ -6081,35 +6200,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Get workspace build logs",
-                "operationId": "get-workspace-build-logs",
+                "summary": "Get user tokens",
+                "operationId": "get-user-tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -6118,51 +6219,58 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                                "$ref": "#/definitions/codersdk.APIKey"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/parameters": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Get build parameters for workspace build",
-                "operationId": "get-build-parameters-for-workspace-build",
+                "summary": "Create token API key",
+                "operationId": "create-token-api-key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
-                            }
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/resources": {
+        "/users/{user}/keys/tokens/tokenconfig": {
             "get": {
                 "security": [
                     {
@@ -6173,15 +6281,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "General"
                 ],
-                "summary": "Get workspace resources for workspace build",
-                "operationId": "get-workspace-resources-for-workspace-build",
+                "summary": "Get token config",
+                "operationId": "get-token-config",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -6190,16 +6298,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.TokenConfig"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/state": {
+        "/users/{user}/keys/tokens/{keyname}": {
             "get": {
                 "security": [
                     {
@@ -6210,15 +6315,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Users"
                 ],
-                "summary": "Get provisioner state for workspace build",
-                "operationId": "get-provisioner-state-for-workspace-build",
+                "summary": "Get API key by token name",
+                "operationId": "get-api-key-by-token-name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Key Name",
+                        "name": "keyname",
                         "in": "path",
                         "required": true
                     }
@@ -6227,13 +6340,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
             }
         },
-        "/workspaceproxies": {
+        "/users/{user}/keys/{keyid}": {
             "get": {
                 "security": [
                     {
@@ -6244,156 +6357,207 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
+                ],
+                "summary": "Get API key by ID",
+                "operationId": "get-api-key-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get workspace proxies",
-                "operationId": "get-workspace-proxies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_WorkspaceProxy"
-                            }
+                            "$ref": "#/definitions/codersdk.APIKey"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Create workspace proxy",
-                "operationId": "create-workspace-proxy",
-                "parameters": [
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
+                "parameters": [
                     {
-                        "description": "Create workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaceproxies/me/app-stats": {
-            "post": {
+        "/users/{user}/lockout": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Report workspace app stats",
-                "operationId": "report-workspace-app-stats",
+                "summary": "Unlock user",
+                "operationId": "unlock-user",
                 "parameters": [
                     {
-                        "description": "Report app stats request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.ReportAppStatsRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "204": {
                         "description": "No Content"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/coordinate": {
+        "/users/{user}/login-type": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
+                ],
+                "summary": "Get user login type",
+                "operationId": "get-user-login-type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Workspace Proxy Coordinate",
-                "operationId": "workspace-proxy-coordinate",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UserLoginType"
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/deregister": {
-            "post": {
+        "/users/{user}/notifications": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Deregister workspace proxy",
-                "operationId": "deregister-workspace-proxy",
+                "summary": "Get user notifications",
+                "operationId": "get-user-notifications",
                 "parameters": [
                     {
-                        "description": "Deregister workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.DeregisterWorkspaceProxyRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationMessage"
+                            }
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/issue-signed-app-token": {
-            "post": {
+        "/users/{user}/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user notification preferences",
+                "operationId": "get-user-notification-preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6406,35 +6570,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Issue signed workspace app token",
-                "operationId": "issue-signed-workspace-app-token",
+                "summary": "Update user notification preferences",
+                "operationId": "update-user-notification-preferences",
                 "parameters": [
                     {
-                        "description": "Issue signed app token request",
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification preferences",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                            "$ref": "#/definitions/codersdk.UpdateNotificationPreferencesRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.NotificationPreference"
+                            }
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/register": {
+        "/users/{user}/offboard": {
             "post": {
                 "security": [
                     {
@@ -6448,35 +6619,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Register workspace proxy",
-                "operationId": "register-workspace-proxy",
+                "summary": "Offboard user",
+                "operationId": "offboard-user",
                 "parameters": [
                     {
-                        "description": "Register workspace proxy request",
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Offboard user request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyRequest"
+                            "$ref": "#/definitions/codersdk.OffboardUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyResponse"
+                            "$ref": "#/definitions/codersdk.OffboardUserResponse"
                         }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/{workspaceproxy}": {
+        "/users/{user}/organizations": {
             "get": {
                 "security": [
                     {
@@ -6487,16 +6662,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Get workspace proxy",
-                "operationId": "get-workspace-proxy",
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -6505,12 +6679,17 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/users/{user}/organizations/{organizationname}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6520,16 +6699,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Delete workspace proxy",
-                "operationId": "delete-workspace-proxy",
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization name",
+                        "name": "organizationname",
                         "in": "path",
                         "required": true
                     }
@@ -6538,104 +6723,95 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/users/{user}/passkeys": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Update workspace proxy",
-                "operationId": "update-workspace-proxy",
+                "summary": "Get passkeys by user",
+                "operationId": "get-passkeys-by-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.PatchWorkspaceProxy"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Passkey"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/workspaces": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "List workspaces",
-                "operationId": "list-workspaces",
+                "summary": "Create passkey",
+                "operationId": "create-passkey",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query in the format ` + "`" + `key:value` + "`" + `. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before.",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Create passkey request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreatePasskeyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
+                            "$ref": "#/definitions/codersdk.Passkey"
                         }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}": {
-            "get": {
+        "/users/{user}/passkeys/challenge": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6645,66 +6821,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Get workspace metadata by ID",
-                "operationId": "get-workspace-metadata-by-id",
+                "summary": "Start passkey registration",
+                "operationId": "start-passkey-registration",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.PasskeyRegistration"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/users/{user}/passkeys/{passkey}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Update workspace metadata by ID",
-                "operationId": "update-workspace-metadata-by-id",
+                "summary": "Delete passkey",
+                "operationId": "delete-passkey",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Metadata update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Passkey ID",
+                        "name": "passkey",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -6714,7 +6880,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspace}/autostart": {
+        "/users/{user}/password": {
             "put": {
                 "security": [
                     {
@@ -6725,26 +6891,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Update workspace autostart schedule by ID",
-                "operationId": "update-workspace-autostart-schedule-by-id",
+                "summary": "Update user password",
+                "operationId": "update-user-password",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Schedule update request",
+                        "description": "Update password request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
                         }
                     }
                 ],
@@ -6755,7 +6920,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspace}/autoupdates": {
+        "/users/{user}/profile": {
             "put": {
                 "security": [
                     {
@@ -6765,38 +6930,43 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Update workspace automatic updates by ID",
-                "operationId": "update-workspace-automatic-updates-by-id",
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Automatic updates request",
+                        "description": "Updated profile",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutomaticUpdatesRequest"
+                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/builds": {
+        "/users/{user}/quiet-hours": {
             "get": {
                 "security": [
                     {
@@ -6807,44 +6977,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get workspace builds by workspace ID",
-                "operationId": "get-workspace-builds-by-workspace-id",
+                "summary": "Get user quiet hours schedule",
+                "operationId": "get-user-quiet-hours-schedule",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "Since timestamp",
-                        "name": "since",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -6853,13 +6997,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
                             }
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6872,26 +7016,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Create workspace build",
-                "operationId": "create-workspace-build",
+                "summary": "Update user quiet hours schedule",
+                "operationId": "update-user-quiet-hours-schedule",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create workspace build request",
+                        "description": "Update schedule request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
                         }
                     }
                 ],
@@ -6899,60 +7043,48 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/dormant": {
-            "put": {
+        "/users/{user}/roles": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Update workspace dormancy status by id.",
-                "operationId": "update-workspace-dormancy-status-by-id",
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Make a workspace dormant or active",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancy"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspace}/extend": {
+            },
             "put": {
                 "security": [
                     {
@@ -6966,26 +7098,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Extend workspace deadline by ID",
-                "operationId": "extend-workspace-deadline-by-id",
+                "summary": "Assign role to user",
+                "operationId": "assign-role-to-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Extend deadline update request",
+                        "description": "Update roles request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
                         }
                     }
                 ],
@@ -6993,13 +7124,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/resolve-autostart": {
+        "/users/{user}/sessions": {
             "get": {
                 "security": [
                     {
@@ -7010,16 +7141,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Resolve workspace autostart by id.",
-                "operationId": "resolve-workspace-autostart-by-id",
+                "summary": "Get active sessions of user",
+                "operationId": "get-active-sessions-of-user",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -7028,54 +7158,110 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ResolveAutostartResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.APIKey"
+                            }
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete all sessions and API keys of user",
+                "operationId": "delete-all-sessions-and-api-keys-of-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/workspaces/{workspace}/ttl": {
+        "/users/{user}/status/activate": {
             "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Update workspace TTL by ID",
-                "operationId": "update-workspace-ttl-by-id",
+                "summary": "Activate user account",
+                "operationId": "activate-user-account",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Workspace TTL update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceTTLRequest"
+                            "$ref": "#/definitions/codersdk.User"
                         }
                     }
+                }
+            }
+        },
+        "/users/{user}/status/suspend": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Suspend user account",
+                "operationId": "suspend-user-account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/watch": {
+        "/users/{user}/two-factor": {
             "get": {
                 "security": [
                     {
@@ -7083,19 +7269,18 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "text/event-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Users"
                 ],
-                "summary": "Watch workspace by ID",
-                "operationId": "watch-workspace-by-id",
+                "summary": "Get user two-factor status",
+                "operationId": "get-user-two-factor-status",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "User ID, name, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
                     }
@@ -7104,2687 +7289,8030 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.UserTwoFactor"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "agentsdk.AWSInstanceIdentityToken": {
-            "type": "object",
-            "required": [
-                "document",
-                "signature"
-            ],
-            "properties": {
-                "document": {
-                    "type": "string"
-                },
-                "signature": {
-                    "type": "string"
-                }
-            }
         },
-        "agentsdk.AgentMetric": {
-            "type": "object",
-            "required": [
-                "name",
-                "type",
-                "value"
-            ],
-            "properties": {
-                "labels": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/agentsdk.AgentMetricLabel"
+        "/users/{user}/two-factor/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
                     }
-                },
-                "name": {
-                    "type": "string"
-                },
-                "type": {
-                    "enum": [
-                        "counter",
-                        "gauge"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/agentsdk.AgentMetricType"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate recovery codes",
+                "operationId": "regenerate-recovery-codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RecoveryCodes"
                         }
-                    ]
-                },
-                "value": {
-                    "type": "number"
+                    }
                 }
             }
         },
-        "agentsdk.AgentMetricLabel": {
-            "type": "object",
-            "required": [
-                "name",
-                "value"
-            ],
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
+        "/users/{user}/workspace/{workspacename}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace metadata by user and workspace name",
+                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
                 }
             }
         },
-        "agentsdk.AgentMetricType": {
-            "type": "string",
-            "enum": [
-                "counter",
-                "gauge"
-            ],
-            "x-enum-varnames": [
-                "AgentMetricTypeCounter",
-                "AgentMetricTypeGauge"
-            ]
-        },
+        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build by user, workspace name, and build number",
+                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "Build number",
+                        "name": "buildnumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhooks",
+                "operationId": "get-webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Webhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhook",
+                "operationId": "create-webhook",
+                "parameters": [
+                    {
+                        "description": "Create webhook request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Webhook"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook}": {
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook",
+                "operationId": "delete-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook deliveries",
+                "operationId": "get-webhook-deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WebhookDelivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook}/deliveries/{delivery}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Replay webhook delivery",
+                "operationId": "replay-webhook-delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Webhook ID",
+                        "name": "webhook",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Delivery ID",
+                        "name": "delivery",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WebhookDelivery"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspace-quota/{user}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace quota by user",
+                "operationId": "get-workspace-quota-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/aws-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on AWS instance",
+                "operationId": "authenticate-agent-on-aws-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/azure-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Azure instance",
+                "operationId": "authenticate-agent-on-azure-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent generic",
+                "operationId": "get-connection-info-for-workspace-agent-generic",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/google-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Google Cloud instance",
+                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/app-health": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent application health",
+                "operationId": "submit-workspace-agent-application-health",
+                "parameters": [
+                    {
+                        "description": "Application health request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent via Tailnet",
+                "operationId": "coordinate-workspace-agent-via-tailnet",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/external-auth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent external auth",
+                "operationId": "get-workspace-agent-external-auth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Match",
+                        "name": "match",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.ExternalAuthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitauth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Get workspace agent git auth",
+                "operationId": "removed-get-workspace-agent-git-auth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Match",
+                        "name": "match",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.ExternalAuthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitsshkey": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git SSH key",
+                "operationId": "get-workspace-agent-git-ssh-key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/listening-ports": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent listening ports",
+                "operationId": "submit-workspace-agent-listening-ports",
+                "parameters": [
+                    {
+                        "description": "Listening ports request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostListeningPortsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Patch workspace agent logs",
+                "operationId": "patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get authorized workspace agent manifest",
+                "operationId": "get-authorized-workspace-agent-manifest",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Manifest"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/metadata": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent metadata",
+                "operationId": "submit-workspace-agent-metadata",
+                "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/metadata/{key}": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Submit workspace agent metadata",
+                "operationId": "removed-submit-workspace-agent-metadata",
+                "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostMetadataRequestDeprecated"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-lifecycle": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent lifecycle state",
+                "operationId": "submit-workspace-agent-lifecycle-state",
+                "parameters": [
+                    {
+                        "description": "Workspace agent lifecycle request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-stats": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent stats",
+                "operationId": "submit-workspace-agent-stats",
+                "parameters": [
+                    {
+                        "description": "Stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Stats"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/rotate-token": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Rotate workspace agent token",
+                "operationId": "rotate-workspace-agent-token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.RotateTokenResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/rpc": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Workspace agent RPC API",
+                "operationId": "workspace-agent-rpc-api",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent startup",
+                "operationId": "submit-workspace-agent-startup",
+                "parameters": [
+                    {
+                        "description": "Startup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup-logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Patch workspace agent logs",
+                "operationId": "removed-patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent by ID",
+                "operationId": "get-workspace-agent-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent",
+                "operationId": "get-connection-info-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent",
+                "operationId": "coordinate-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/files/download": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Download file from workspace agent",
+                "operationId": "download-file-from-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path, relative to the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/files/list": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "List files in workspace agent",
+                "operationId": "list-files-in-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Directory path, relative to the home directory",
+                        "name": "path",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListFilesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/files/upload": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Upload file to workspace agent",
+                "operationId": "upload-file-to-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path, relative to the home directory",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/legacy": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Agent is legacy",
+                "operationId": "agent-is-legacy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace Agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.AgentIsLegacyResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/listening-ports": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get listening ports for workspace agent",
+                "operationId": "get-listening-ports-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get logs by workspace agent",
+                "operationId": "get-logs-by-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disable compression for WebSocket connection",
+                        "name": "no_compression",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/metadata-history": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent metadata history",
+                "operationId": "get-workspace-agent-metadata-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated metadata keys",
+                        "name": "keys",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only values collected after this time",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentMetadataHistory"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/netcheck": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "The agent probes the DERP regions of the deployment, and reports\nthe NAT type and UDP reachability of the workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Run netcheck from workspace agent",
+                "operationId": "run-netcheck-from-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentNetcheckResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/port-forward": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authorize port forwarding for workspace agent",
+                "operationId": "authorize-port-forwarding-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Port forward request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuthorizeWorkspaceAgentPortForwardRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Open PTY to workspace agent",
+                "operationId": "open-pty-to-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/resource-usage": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent resource usage",
+                "operationId": "get-workspace-agent-resource-usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentResourceUsage"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/revoke-token": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Revoke workspace agent token",
+                "operationId": "revoke-workspace-agent-token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/startup-logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Get logs by workspace agent",
+                "operationId": "removed-get-logs-by-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disable compression for WebSocket connection",
+                        "name": "no_compression",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/watch-metadata": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Watch for workspace agent metadata updates",
+                "operationId": "watch-for-workspace-agent-metadata-updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build",
+                "operationId": "get-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Cancel workspace build",
+                "operationId": "cancel-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build logs",
+                "operationId": "get-workspace-build-logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get build parameters for workspace build",
+                "operationId": "get-build-parameters-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace resources for workspace build",
+                "operationId": "get-workspace-resources-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/state": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get provisioner state for workspace build",
+                "operationId": "get-provisioner-state-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceproxies": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace proxies",
+                "operationId": "get-workspace-proxies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_WorkspaceProxy"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Create workspace proxy",
+                "operationId": "create-workspace-proxy",
+                "parameters": [
+                    {
+                        "description": "Create workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceproxies/me/app-stats": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Report workspace app stats",
+                "operationId": "report-workspace-app-stats",
+                "parameters": [
+                    {
+                        "description": "Report app stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.ReportAppStatsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceproxies/me/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Workspace Proxy Coordinate",
+                "operationId": "workspace-proxy-coordinate",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceproxies/me/deregister": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Deregister workspace proxy",
+                "operationId": "deregister-workspace-proxy",
+                "parameters": [
+                    {
+                        "description": "Deregister workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.DeregisterWorkspaceProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceproxies/me/issue-signed-app-token": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Issue signed workspace app token",
+                "operationId": "issue-signed-workspace-app-token",
+                "parameters": [
+                    {
+                        "description": "Issue signed app token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceproxies/me/register": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Register workspace proxy",
+                "operationId": "register-workspace-proxy",
+                "parameters": [
+                    {
+                        "description": "Register workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceproxies/{workspaceproxy}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace proxy",
+                "operationId": "get-workspace-proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Delete workspace proxy",
+                "operationId": "delete-workspace-proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update workspace proxy",
+                "operationId": "update-workspace-proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PatchWorkspaceProxy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "List workspaces",
+                "operationId": "list-workspaces",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query in the format ` + "`" + `key:value` + "`" + `. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, favorite.",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/batch-builds": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Create workspace builds in batch",
+                "operationId": "create-workspace-builds-in-batch",
+                "parameters": [
+                    {
+                        "description": "Batch workspace build request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.BatchWorkspaceBuildRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.BatchWorkspaceBuildResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace metadata by ID",
+                "operationId": "get-workspace-metadata-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace metadata by ID",
+                "operationId": "update-workspace-metadata-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metadata update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/acl": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace ACL",
+                "operationId": "get-workspace-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceACL"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace ACL",
+                "operationId": "update-workspace-acl",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update workspace ACL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceACL"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/autostart": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace autostart schedule by ID",
+                "operationId": "update-workspace-autostart-schedule-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/autoupdates": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace automatic updates by ID",
+                "operationId": "update-workspace-automatic-updates-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automatic updates request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutomaticUpdatesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/builds": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace builds by workspace ID",
+                "operationId": "get-workspace-builds-by-workspace-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Since timestamp",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "initiator",
+                            "autostart",
+                            "autostop",
+                            "template_update",
+                            "batch",
+                            "retry"
+                        ],
+                        "type": "string",
+                        "description": "Build reason",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Create workspace build",
+                "operationId": "create-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace build request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/costs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace costs",
+                "operationId": "get-workspace-costs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "Start date, inclusive. Defaults to 29 days before the end date.",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "description": "End date, inclusive. Defaults to today.",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceCosts"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dormancy-exempt": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace dormancy exemption by ID",
+                "operationId": "update-workspace-dormancy-exemption-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dormancy exemption request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancyExemptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dormant": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace dormancy status by id.",
+                "operationId": "update-workspace-dormancy-status-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Make a workspace dormant or active",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceDormancy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/drift": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace drift",
+                "operationId": "get-workspace-drift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceDrift"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dry-run/{jobID}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build dry-run by job ID",
+                "operationId": "get-workspace-build-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dry-run/{jobID}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Cancel workspace build dry-run by job ID",
+                "operationId": "cancel-workspace-build-dry-run-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dry-run/{jobID}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build dry-run logs by job ID",
+                "operationId": "get-workspace-build-dry-run-logs-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/dry-run/{jobID}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build dry-run resources by job ID",
+                "operationId": "get-workspace-build-dry-run-resources-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildDryRunResources"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/extend": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Extend workspace deadline by ID",
+                "operationId": "extend-workspace-deadline-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Extend deadline update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/favorite": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Favorite workspace by ID",
+                "operationId": "favorite-workspace-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Unfavorite workspace by ID",
+                "operationId": "unfavorite-workspace-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/maintenance-opt-out": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace maintenance opt-out by ID",
+                "operationId": "update-workspace-maintenance-opt-out-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance opt-out request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceMaintenanceOptOutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/parameters/history": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace parameter history",
+                "operationId": "get-workspace-parameter-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceParameterChange"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/port-share": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace agent port shares",
+                "operationId": "get-workspace-agent-port-shares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentPortShare"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Upsert workspace agent port share",
+                "operationId": "upsert-workspace-agent-port-share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Upsert port sharing level request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpsertWorkspaceAgentPortShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShare"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Delete workspace agent port share",
+                "operationId": "delete-workspace-agent-port-share",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delete port sharing level request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DeleteWorkspaceAgentPortShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/resolve-autostart": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Resolve workspace autostart by id.",
+                "operationId": "resolve-workspace-autostart-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ResolveAutostartResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/snapshots": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace snapshots",
+                "operationId": "get-workspace-snapshots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceSnapshot"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Create workspace snapshot",
+                "operationId": "create-workspace-snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create workspace snapshot request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceSnapshotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceSnapshot"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/snapshots/{snapshot}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Restore workspace snapshot",
+                "operationId": "restore-workspace-snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Snapshot ID",
+                        "name": "snapshot",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace timeline",
+                "operationId": "get-workspace-timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceTimelineEvent"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/ttl": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Update workspace TTL by ID",
+                "operationId": "update-workspace-ttl-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Workspace TTL update request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceTTLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaces/{workspace}/watch": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Watch workspace by ID",
+                "operationId": "watch-workspace-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "agentsdk.AWSInstanceIdentityToken": {
+            "type": "object",
+            "required": [
+                "document",
+                "signature"
+            ],
+            "properties": {
+                "document": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.AgentMetric": {
+            "type": "object",
+            "required": [
+                "name",
+                "type",
+                "value"
+            ],
+            "properties": {
+                "labels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.AgentMetricLabel"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "enum": [
+                        "counter",
+                        "gauge"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/agentsdk.AgentMetricType"
+                        }
+                    ]
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "agentsdk.AgentMetricLabel": {
+            "type": "object",
+            "required": [
+                "name",
+                "value"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.AgentMetricType": {
+            "type": "string",
+            "enum": [
+                "counter",
+                "gauge"
+            ],
+            "x-enum-varnames": [
+                "AgentMetricTypeCounter",
+                "AgentMetricTypeGauge"
+            ]
+        },
         "agentsdk.AuthenticateResponse": {
             "type": "object",
             "properties": {
-                "session_token": {
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.AzureInstanceIdentityToken": {
+            "type": "object",
+            "required": [
+                "encoding",
+                "signature"
+            ],
+            "properties": {
+                "encoding": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.ExternalAuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "token_extra": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Deprecated: Only supported on ` + "`" + `/workspaceagents/me/gitauth` + "`" + `\nfor backwards compatibility.",
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.GitSSHKey": {
+            "type": "object",
+            "properties": {
+                "private_key": {
+                    "type": "string"
+                },
+                "public_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.GoogleInstanceIdentityToken": {
+            "type": "object",
+            "required": [
+                "json_web_token"
+            ],
+            "properties": {
+                "json_web_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.Log": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "level": {
+                    "$ref": "#/definitions/codersdk.LogLevel"
+                },
+                "output": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.Manifest": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "apps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceApp"
+                    }
+                },
+                "derp_force_websockets": {
+                    "type": "boolean"
+                },
+                "derpmap": {
+                    "$ref": "#/definitions/tailcfg.DERPMap"
+                },
+                "directory": {
+                    "type": "string"
+                },
+                "disable_direct_connections": {
+                    "type": "boolean"
+                },
+                "environment_variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "git_auth_configs": {
+                    "description": "GitAuthConfigs stores the number of Git configurations\nthe Coder deployment has. If this number is \u003e0, we\nset up special configuration in the workspace.",
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentMetadataDescription"
+                    }
+                },
+                "motd_file": {
+                    "type": "string"
+                },
+                "owner_name": {
+                    "description": "OwnerName and WorkspaceID are used by an open-source user to identify the workspace.\nWe do not provide insurance that this will not be removed in the future,\nbut if it's easy to persist lets keep it around.",
+                    "type": "string"
+                },
+                "quic_address": {
+                    "description": "QUICAddress is the UDP address agents can dial to reach coderd over\nQUIC instead of a WebSocket. It is empty when the deployment does not\naccept QUIC connections.",
+                    "type": "string"
+                },
+                "scripts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
+                    }
+                },
+                "vscode_port_proxy_uri": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.Metadata": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Age is the number of seconds since the metadata was collected.\nIt is provided in addition to CollectedAt to protect against clock skew.",
+                    "type": "integer"
+                },
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.PatchLogs": {
+            "type": "object",
+            "properties": {
+                "log_source_id": {
+                    "type": "string"
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.Log"
+                    }
+                }
+            }
+        },
+        "agentsdk.PostAppHealthsRequest": {
+            "type": "object",
+            "properties": {
+                "healths": {
+                    "description": "Healths is a map of the workspace app name and the health of the app.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAppHealth"
+                    }
+                }
+            }
+        },
+        "agentsdk.PostLifecycleRequest": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentLifecycle"
+                }
+            }
+        },
+        "agentsdk.PostListeningPortsRequest": {
+            "type": "object",
+            "properties": {
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPort"
+                    }
+                }
+            }
+        },
+        "agentsdk.PostMetadataRequest": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.Metadata"
+                    }
+                }
+            }
+        },
+        "agentsdk.PostMetadataRequestDeprecated": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Age is the number of seconds since the metadata was collected.\nIt is provided in addition to CollectedAt to protect against clock skew.",
+                    "type": "integer"
+                },
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.PostStartupRequest": {
+            "type": "object",
+            "properties": {
+                "expanded_directory": {
+                    "type": "string"
+                },
+                "gpus": {
+                    "description": "GPUs are the GPUs detected in the workspace. They replace the GPUs\npreviously reported by the agent.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentGPU"
+                    }
+                },
+                "subsystems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AgentSubsystem"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.RotateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "previous_token_expires_at": {
+                    "description": "PreviousTokenExpiresAt is when the token the agent used before the\nrotation stops being accepted.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "agentsdk.Stats": {
+            "type": "object",
+            "properties": {
+                "connection_count": {
+                    "description": "ConnectionCount is the number of connections received by an agent.",
+                    "type": "integer"
+                },
+                "connection_median_latency_ms": {
+                    "description": "ConnectionMedianLatencyMS is the median latency of all connections in milliseconds.",
+                    "type": "number"
+                },
+                "connections_by_proto": {
+                    "description": "ConnectionsByProto is a count of connections by protocol.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cpu_total_cores": {
+                    "description": "CPUTotalCores is the number of CPU cores available to the workspace.",
+                    "type": "number"
+                },
+                "cpu_used_cores": {
+                    "description": "CPUUsedCores is the number of CPU cores in use by the workspace.",
+                    "type": "number"
+                },
+                "disk_total_bytes": {
+                    "description": "DiskTotalBytes is the size of the agent's home volume.",
+                    "type": "integer"
+                },
+                "disk_used_bytes": {
+                    "description": "DiskUsedBytes is the disk space in use on the agent's home volume.",
+                    "type": "integer"
+                },
+                "memory_total_bytes": {
+                    "description": "MemoryTotalBytes is the memory available to the workspace.",
+                    "type": "integer"
+                },
+                "memory_used_bytes": {
+                    "description": "MemoryUsedBytes is the memory in use by the workspace.",
+                    "type": "integer"
+                },
+                "metrics": {
+                    "description": "Metrics collected by the agent",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.AgentMetric"
+                    }
+                },
+                "rx_bytes": {
+                    "description": "RxBytes is the number of received bytes.",
+                    "type": "integer"
+                },
+                "rx_packets": {
+                    "description": "RxPackets is the number of received packets.",
+                    "type": "integer"
+                },
+                "session_count_jetbrains": {
+                    "description": "SessionCountJetBrains is the number of connections received by an agent\nthat are from our JetBrains extension.",
+                    "type": "integer"
+                },
+                "session_count_reconnecting_pty": {
+                    "description": "SessionCountReconnectingPTY is the number of connections received by an agent\nthat are from the reconnecting web terminal.",
+                    "type": "integer"
+                },
+                "session_count_ssh": {
+                    "description": "SessionCountSSH is the number of connections received by an agent\nthat are normal, non-tagged SSH sessions.",
+                    "type": "integer"
+                },
+                "session_count_vscode": {
+                    "description": "SessionCountVSCode is the number of connections received by an agent\nthat are from our VS Code extension.",
+                    "type": "integer"
+                },
+                "tx_bytes": {
+                    "description": "TxBytes is the number of transmitted bytes.",
+                    "type": "integer"
+                },
+                "tx_packets": {
+                    "description": "TxPackets is the number of transmitted bytes.",
+                    "type": "integer"
+                }
+            }
+        },
+        "agentsdk.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "report_interval": {
+                    "description": "ReportInterval is the duration after which the agent should send stats\nagain.",
+                    "type": "integer"
+                }
+            }
+        },
+        "clibase.Annotations": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "clibase.Group": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent": {
+                    "$ref": "#/definitions/clibase.Group"
+                },
+                "yaml": {
+                    "type": "string"
+                }
+            }
+        },
+        "clibase.HostPort": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "string"
+                }
+            }
+        },
+        "clibase.Option": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "Annotations enable extensions to clibase higher up in the stack. It's useful for\nhelp formatting and documentation generation.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.Annotations"
+                        }
+                    ]
+                },
+                "default": {
+                    "description": "Default is parsed into Value if set.",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "env": {
+                    "description": "Env is the environment variable used to configure this option. If unset,\nenvironment configuring is disabled.",
+                    "type": "string"
+                },
+                "flag": {
+                    "description": "Flag is the long name of the flag used to configure this option. If unset,\nflag configuring is disabled.",
+                    "type": "string"
+                },
+                "flag_shorthand": {
+                    "description": "FlagShorthand is the one-character shorthand for the flag. If unset, no\nshorthand is used.",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "Group is a group hierarchy that helps organize this option in help, configs\nand other documentation.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.Group"
+                        }
+                    ]
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "description": "Required means this value must be set by some means. It requires\n` + "`" + `ValueSource != ValueSourceNone` + "`" + `\nIf ` + "`" + `Default` + "`" + ` is set, then ` + "`" + `Required` + "`" + ` is ignored.",
+                    "type": "boolean"
+                },
+                "use_instead": {
+                    "description": "UseInstead is a list of options that should be used instead of this one.\nThe field is used to generate a deprecation warning.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clibase.Option"
+                    }
+                },
+                "value": {
+                    "description": "Value includes the types listed in values.go."
+                },
+                "value_source": {
+                    "$ref": "#/definitions/clibase.ValueSource"
+                },
+                "yaml": {
+                    "description": "YAML is the YAML key used to configure this option. If unset, YAML\nconfiguring is disabled.",
+                    "type": "string"
+                }
+            }
+        },
+        "clibase.Regexp": {
+            "type": "object"
+        },
+        "clibase.Struct-array_codersdk_ExternalAuthConfig": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ExternalAuthConfig"
+                    }
+                }
+            }
+        },
+        "clibase.Struct-array_codersdk_LinkConfig": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.LinkConfig"
+                    }
+                }
+            }
+        },
+        "clibase.URL": {
+            "type": "object",
+            "properties": {
+                "forceQuery": {
+                    "description": "append a query ('?') even if RawQuery is empty",
+                    "type": "boolean"
+                },
+                "fragment": {
+                    "description": "fragment for references, without '#'",
+                    "type": "string"
+                },
+                "host": {
+                    "description": "host or host:port",
+                    "type": "string"
+                },
+                "omitHost": {
+                    "description": "do not emit empty host (authority)",
+                    "type": "boolean"
+                },
+                "opaque": {
+                    "description": "encoded opaque data",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "path (relative paths may omit leading slash)",
+                    "type": "string"
+                },
+                "rawFragment": {
+                    "description": "encoded fragment hint (see EscapedFragment method)",
+                    "type": "string"
+                },
+                "rawPath": {
+                    "description": "encoded path hint (see EscapedPath method)",
+                    "type": "string"
+                },
+                "rawQuery": {
+                    "description": "encoded query values, without '?'",
+                    "type": "string"
+                },
+                "scheme": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "username and password information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/url.Userinfo"
+                        }
+                    ]
+                }
+            }
+        },
+        "clibase.ValueSource": {
+            "type": "string",
+            "enum": [
+                "",
+                "flag",
+                "env",
+                "yaml",
+                "default"
+            ],
+            "x-enum-varnames": [
+                "ValueSourceNone",
+                "ValueSourceFlag",
+                "ValueSourceEnv",
+                "ValueSourceYAML",
+                "ValueSourceDefault"
+            ]
+        },
+        "coderd.SCIMGroup": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroupMember"
+                    }
+                },
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "resourceType": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMGroupMember": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "coderd.SCIMPatchOp": {
+            "type": "object",
+            "properties": {
+                "Operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMPatchOperation"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMPatchOperation": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMUser": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "emails": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "display": {
+                                "type": "string"
+                            },
+                            "primary": {
+                                "type": "boolean"
+                            },
+                            "type": {
+                                "type": "string"
+                            },
+                            "value": {
+                                "type": "string",
+                                "format": "email"
+                            }
+                        }
+                    }
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {}
+                },
+                "id": {
+                    "type": "string"
+                },
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "resourceType": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "name": {
+                    "type": "object",
+                    "properties": {
+                        "familyName": {
+                            "type": "string"
+                        },
+                        "givenName": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "coderd.cspViolation": {
+            "type": "object",
+            "properties": {
+                "csp-report": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "codersdk.ACLAvailable": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Group"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.User"
+                    }
+                }
+            }
+        },
+        "codersdk.APIKey": {
+            "type": "object",
+            "required": [
+                "created_at",
+                "expires_at",
+                "id",
+                "last_used",
+                "lifetime_seconds",
+                "login_type",
+                "scope",
+                "token_name",
+                "updated_at",
+                "user_id"
+            ],
+            "properties": {
+                "allowed_workspace_ids": {
+                    "description": "AllowedWorkspaceIDs are the workspaces a key with fine-grained scopes\nis limited to. If empty, the key is not limited to specific workspaces.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_allowlist": {
+                    "description": "IPAllowlist holds the CIDR ranges the key can be used from. If empty,\nthe key is only limited by the IP allowlist of its user.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "last_used": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "lifetime_seconds": {
+                    "type": "integer"
+                },
+                "login_type": {
+                    "enum": [
+                        "password",
+                        "github",
+                        "oidc",
+                        "token",
+                        "ldap"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.LoginType"
+                        }
+                    ]
+                },
+                "scope": {
+                    "enum": [
+                        "all",
+                        "application_connect"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.APIKeyScope"
+                        }
+                    ]
+                },
+                "scopes": {
+                    "description": "Scopes are the fine-grained scopes the key is limited to. If empty,\nthe key is only limited by its scope.",
+                    "type": "array",
+                    "items": {
+                        "enum": [
+                            "workspace:read",
+                            "workspace:write",
+                            "template:read",
+                            "template:write",
+                            "user:read"
+                        ],
+                        "$ref": "#/definitions/codersdk.APITokenScope"
+                    }
+                },
+                "token_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.APIKeyScope": {
+            "type": "string",
+            "enum": [
+                "all",
+                "application_connect"
+            ],
+            "x-enum-varnames": [
+                "APIKeyScopeAll",
+                "APIKeyScopeApplicationConnect"
+            ]
+        },
+        "codersdk.APITokenScope": {
+            "type": "string",
+            "enum": [
+                "workspace:read",
+                "workspace:write",
+                "template:read",
+                "template:write",
+                "user:read"
+            ],
+            "x-enum-varnames": [
+                "APITokenScopeWorkspaceRead",
+                "APITokenScopeWorkspaceWrite",
+                "APITokenScopeTemplateRead",
+                "APITokenScopeTemplateWrite",
+                "APITokenScopeUserRead"
+            ]
+        },
+        "codersdk.APITokenScopeDescription": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "enum": [
+                        "workspace:read",
+                        "workspace:write",
+                        "template:read",
+                        "template:write",
+                        "user:read"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.APITokenScope"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.AddLicenseRequest": {
+            "type": "object",
+            "required": [
+                "license"
+            ],
+            "properties": {
+                "license": {
                     "type": "string"
                 }
             }
         },
-        "agentsdk.AzureInstanceIdentityToken": {
+        "codersdk.AgentSubsystem": {
+            "type": "string",
+            "enum": [
+                "envbox",
+                "envbuilder",
+                "exectrace"
+            ],
+            "x-enum-varnames": [
+                "AgentSubsystemEnvbox",
+                "AgentSubsystemEnvbuilder",
+                "AgentSubsystemExectrace"
+            ]
+        },
+        "codersdk.AppHostResponse": {
             "type": "object",
-            "required": [
-                "encoding",
-                "signature"
+            "properties": {
+                "host": {
+                    "description": "Host is the externally accessible URL for the Coder instance.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AppearanceConfig": {
+            "type": "object",
+            "properties": {
+                "application_name": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "service_banner": {
+                    "$ref": "#/definitions/codersdk.ServiceBannerConfig"
+                },
+                "support_links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.LinkConfig"
+                    }
+                }
+            }
+        },
+        "codersdk.ArchiveTemplateVersionsRequest": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "description": "By default, only failed versions are archived. Set this to true\nto archive all unused versions regardless of job status.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.AssignableRoles": {
+            "type": "object",
+            "properties": {
+                "assignable": {
+                    "type": "boolean"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "write",
+                "delete",
+                "start",
+                "stop",
+                "login",
+                "logout",
+                "register",
+                "port_forward",
+                "ip_rejected"
             ],
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionWrite",
+                "AuditActionDelete",
+                "AuditActionStart",
+                "AuditActionStop",
+                "AuditActionLogin",
+                "AuditActionLogout",
+                "AuditActionRegister",
+                "AuditActionPortForward",
+                "AuditActionIPRejected"
+            ]
+        },
+        "codersdk.AuditDiff": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/codersdk.AuditDiffField"
+            }
+        },
+        "codersdk.AuditDiffField": {
+            "type": "object",
             "properties": {
-                "encoding": {
+                "new": {},
+                "old": {},
+                "secret": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/codersdk.AuditAction"
+                },
+                "additional_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
                     "type": "string"
                 },
-                "signature": {
+                "diff": {
+                    "$ref": "#/definitions/codersdk.AuditDiff"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "request_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource_icon": {
+                    "type": "string"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource_link": {
+                    "type": "string"
+                },
+                "resource_target": {
+                    "description": "ResourceTarget is the name of the resource.",
+                    "type": "string"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.ResourceType"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user": {
+                    "$ref": "#/definitions/codersdk.User"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.AuditLogExportConfig": {
+            "type": "object",
+            "properties": {
+                "buffer_size": {
+                    "type": "integer"
+                },
+                "s3_bucket": {
+                    "type": "string"
+                },
+                "s3_prefix": {
+                    "type": "string"
+                },
+                "s3_region": {
+                    "type": "string"
+                },
+                "syslog_address": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "webhook_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                }
+            }
+        },
+        "codersdk.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "audit_logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AuditLog"
+                    }
+                },
+                "count": {
+                    "description": "Count is the total number of matching audit logs. It is not computed\nwhen paginating with a cursor.",
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "NextCursor can be passed as the cursor to fetch the next page. It is\nempty if there are no more audit logs.",
                     "type": "string"
                 }
             }
         },
-        "agentsdk.ExternalAuthResponse": {
+        "codersdk.AuthMethod": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.AuthMethods": {
+            "type": "object",
+            "properties": {
+                "github": {
+                    "$ref": "#/definitions/codersdk.AuthMethod"
+                },
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.AuthMethod"
+                },
+                "oidc": {
+                    "$ref": "#/definitions/codersdk.OIDCAuthMethod"
+                },
+                "password": {
+                    "$ref": "#/definitions/codersdk.AuthMethod"
+                }
+            }
+        },
+        "codersdk.AuthorizationCheck": {
+            "description": "AuthorizationCheck is used to check if the currently authenticated user (or the specified user) can do a given action to a given set of objects.",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete"
+                    ]
+                },
+                "object": {
+                    "description": "Object can represent a \"set\" of objects, such as: all workspaces in an organization, all workspaces owned by me, and all workspaces across the entire product.\nWhen defining an object, use the most specific language when possible to\nproduce the smallest set. Meaning to set as many fields on 'Object' as\nyou can. Example, if you want to check if you can update all workspaces\nowned by 'me', try to also add an 'OrganizationID' to the settings.\nOmitting the 'OrganizationID' could produce the incorrect value, as\nworkspaces have both ` + "`" + `user` + "`" + ` and ` + "`" + `organization` + "`" + ` owners.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AuthorizationObject"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.AuthorizationObject": {
+            "description": "AuthorizationObject can represent a \"set\" of objects, such as: all workspaces in an organization, all workspaces owned by me, all workspaces across the entire product.",
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
-                },
-                "password": {
+                "organization_id": {
+                    "description": "OrganizationID (optional) adds the set constraint to all resources owned by a given organization.",
                     "type": "string"
                 },
-                "token_extra": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "type": {
+                "owner_id": {
+                    "description": "OwnerID (optional) adds the set constraint to all resources owned by a given user.",
                     "type": "string"
                 },
-                "url": {
+                "resource_id": {
+                    "description": "ResourceID (optional) reduces the set to a singular resource. This assigns\na resource ID to the resource type, eg: a single workspace.\nThe rbac library will not fetch the resource from the database, so if you\nare using this option, you should also set the owner ID and organization ID\nif possible. Be as specific as possible using all the fields relevant.",
                     "type": "string"
                 },
-                "username": {
-                    "description": "Deprecated: Only supported on ` + "`" + `/workspaceagents/me/gitauth` + "`" + `\nfor backwards compatibility.",
-                    "type": "string"
+                "resource_type": {
+                    "description": "ResourceType is the name of the resource.\n` + "`" + `./coderd/rbac/object.go` + "`" + ` has the list of valid resource types.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.RBACResource"
+                        }
+                    ]
                 }
             }
         },
-        "agentsdk.GitSSHKey": {
+        "codersdk.AuthorizationRequest": {
             "type": "object",
             "properties": {
-                "private_key": {
-                    "type": "string"
-                },
-                "public_key": {
-                    "type": "string"
+                "checks": {
+                    "description": "Checks is a map keyed with an arbitrary string to a permission check.\nThe key can be any string that is helpful to the caller, and allows\nmultiple permission checks to be run in a single request.\nThe key ensures that each permission check has the same key in the\nresponse.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.AuthorizationCheck"
+                    }
                 }
             }
         },
-        "agentsdk.GoogleInstanceIdentityToken": {
+        "codersdk.AuthorizationResponse": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "boolean"
+            }
+        },
+        "codersdk.AuthorizeWorkspaceAgentPortForwardRequest": {
             "type": "object",
             "required": [
-                "json_web_token"
+                "port"
             ],
             "properties": {
-                "json_web_token": {
-                    "type": "string"
+                "port": {
+                    "type": "integer"
                 }
             }
         },
-        "agentsdk.Log": {
+        "codersdk.AutomaticUpdates": {
+            "type": "string",
+            "enum": [
+                "always",
+                "never"
+            ],
+            "x-enum-varnames": [
+                "AutomaticUpdatesAlways",
+                "AutomaticUpdatesNever"
+            ]
+        },
+        "codersdk.BatchWorkspaceBuildRequest": {
             "type": "object",
+            "required": [
+                "transition",
+                "workspace_ids"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "level": {
-                    "$ref": "#/definitions/codersdk.LogLevel"
+                "transition": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
                 },
-                "output": {
-                    "type": "string"
+                "workspace_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
                 }
             }
         },
-        "agentsdk.Manifest": {
+        "codersdk.BatchWorkspaceBuildResponse": {
             "type": "object",
             "properties": {
-                "agent_id": {
-                    "type": "string"
-                },
-                "agent_name": {
-                    "type": "string"
-                },
-                "apps": {
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceApp"
+                        "$ref": "#/definitions/codersdk.BatchWorkspaceBuildResult"
                     }
+                }
+            }
+        },
+        "codersdk.BatchWorkspaceBuildResult": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "$ref": "#/definitions/codersdk.WorkspaceBuild"
                 },
-                "derp_force_websockets": {
-                    "type": "boolean"
-                },
-                "derpmap": {
-                    "$ref": "#/definitions/tailcfg.DERPMap"
+                "error": {
+                    "$ref": "#/definitions/codersdk.Response"
                 },
-                "directory": {
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.BuildInfoResponse": {
+            "type": "object",
+            "properties": {
+                "agent_api_version": {
+                    "description": "AgentAPIVersion is the current version of the Agent API (back versions\nMAY still be supported).",
                     "type": "string"
                 },
-                "disable_direct_connections": {
-                    "type": "boolean"
-                },
-                "environment_variables": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "git_auth_configs": {
-                    "description": "GitAuthConfigs stores the number of Git configurations\nthe Coder deployment has. If this number is \u003e0, we\nset up special configuration in the workspace.",
-                    "type": "integer"
-                },
-                "metadata": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceAgentMetadataDescription"
-                    }
-                },
-                "motd_file": {
+                "dashboard_url": {
+                    "description": "DashboardURL is the URL to hit the deployment's dashboard.\nFor external workspace proxies, this is the coderd they are connected\nto.",
                     "type": "string"
                 },
-                "owner_name": {
-                    "description": "OwnerName and WorkspaceID are used by an open-source user to identify the workspace.\nWe do not provide insurance that this will not be removed in the future,\nbut if it's easy to persist lets keep it around.",
+                "external_url": {
+                    "description": "ExternalURL references the current Coder version.\nFor production builds, this will link directly to a release. For development builds, this will link to a commit.",
                     "type": "string"
                 },
-                "scripts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceAgentScript"
-                    }
-                },
-                "vscode_port_proxy_uri": {
+                "version": {
+                    "description": "Version returns the semantic version of the build.",
                     "type": "string"
                 },
-                "workspace_id": {
-                    "type": "string"
+                "workspace_proxy": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.BuildReason": {
+            "type": "string",
+            "enum": [
+                "initiator",
+                "autostart",
+                "autostop",
+                "template_update",
+                "batch",
+                "retry"
+            ],
+            "x-enum-varnames": [
+                "BuildReasonInitiator",
+                "BuildReasonAutostart",
+                "BuildReasonAutostop",
+                "BuildReasonTemplateUpdate",
+                "BuildReasonBatch",
+                "BuildReasonRetry"
+            ]
+        },
+        "codersdk.ConnectionLatency": {
+            "type": "object",
+            "properties": {
+                "p50": {
+                    "type": "number",
+                    "example": 31.312
                 },
-                "workspace_name": {
-                    "type": "string"
+                "p95": {
+                    "type": "number",
+                    "example": 119.832
                 }
             }
         },
-        "agentsdk.Metadata": {
+        "codersdk.ConnectionLog": {
             "type": "object",
             "properties": {
-                "age": {
-                    "description": "Age is the number of seconds since the metadata was collected.\nIt is provided in addition to CollectedAt to protect against clock skew.",
+                "agent_name": {
+                    "type": "string"
+                },
+                "api_key_id": {
+                    "description": "APIKeyID is the ID of the API key that authenticated the connection.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "port": {
+                    "description": "Port is the forwarded port of port_forwarding connections.",
                     "type": "integer"
                 },
-                "collected_at": {
+                "time": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "error": {
-                    "type": "string"
+                "type": {
+                    "enum": [
+                        "ssh",
+                        "reconnecting_pty",
+                        "port_forwarding"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ConnectionType"
+                        }
+                    ]
                 },
-                "key": {
+                "user_agent": {
                     "type": "string"
                 },
-                "value": {
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
                     "type": "string"
-                }
-            }
-        },
-        "agentsdk.PatchLogs": {
-            "type": "object",
-            "properties": {
-                "log_source_id": {
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
                     "type": "string"
                 },
-                "logs": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/agentsdk.Log"
-                    }
+                "workspace_owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_owner_username": {
+                    "type": "string"
                 }
             }
         },
-        "agentsdk.PostAppHealthsRequest": {
+        "codersdk.ConnectionLogResponse": {
             "type": "object",
             "properties": {
-                "healths": {
-                    "description": "Healths is a map of the workspace app name and the health of the app.",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/codersdk.WorkspaceAppHealth"
+                "connection_logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ConnectionLog"
                     }
+                },
+                "count": {
+                    "description": "Count is the total number of matching connection logs.",
+                    "type": "integer"
                 }
             }
         },
-        "agentsdk.PostLifecycleRequest": {
+        "codersdk.ConnectionType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty",
+                "port_forwarding"
+            ],
+            "x-enum-varnames": [
+                "ConnectionTypeSSH",
+                "ConnectionTypeReconnectingPTY",
+                "ConnectionTypePortForwarding"
+            ]
+        },
+        "codersdk.ConvertLoginRequest": {
             "type": "object",
+            "required": [
+                "password",
+                "to_type"
+            ],
             "properties": {
-                "changed_at": {
+                "password": {
                     "type": "string"
                 },
-                "state": {
-                    "$ref": "#/definitions/codersdk.WorkspaceAgentLifecycle"
+                "to_type": {
+                    "description": "ToType is the login type to convert to.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.LoginType"
+                        }
+                    ]
                 }
             }
         },
-        "agentsdk.PostMetadataRequest": {
+        "codersdk.CostTotal": {
             "type": "object",
             "properties": {
-                "metadata": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/agentsdk.Metadata"
-                    }
+                "currency": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "agentsdk.PostMetadataRequestDeprecated": {
+        "codersdk.CreateFirstUserRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
             "properties": {
-                "age": {
-                    "description": "Age is the number of seconds since the metadata was collected.\nIt is provided in addition to CollectedAt to protect against clock skew.",
-                    "type": "integer"
-                },
-                "collected_at": {
-                    "type": "string",
-                    "format": "date-time"
+                "email": {
+                    "type": "string"
                 },
-                "error": {
+                "password": {
                     "type": "string"
                 },
-                "value": {
+                "trial": {
+                    "type": "boolean"
+                },
+                "trial_info": {
+                    "$ref": "#/definitions/codersdk.CreateFirstUserTrialInfo"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "agentsdk.PostStartupRequest": {
+        "codersdk.CreateFirstUserResponse": {
             "type": "object",
             "properties": {
-                "expanded_directory": {
-                    "type": "string"
-                },
-                "subsystems": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.AgentSubsystem"
-                    }
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "version": {
-                    "type": "string"
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "agentsdk.Stats": {
+        "codersdk.CreateFirstUserTrialInfo": {
             "type": "object",
             "properties": {
-                "connection_count": {
-                    "description": "ConnectionCount is the number of connections received by an agent.",
-                    "type": "integer"
-                },
-                "connection_median_latency_ms": {
-                    "description": "ConnectionMedianLatencyMS is the median latency of all connections in milliseconds.",
-                    "type": "number"
-                },
-                "connections_by_proto": {
-                    "description": "ConnectionsByProto is a count of connections by protocol.",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "company_name": {
+                    "type": "string"
                 },
-                "metrics": {
-                    "description": "Metrics collected by the agent",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/agentsdk.AgentMetric"
-                    }
+                "country": {
+                    "type": "string"
                 },
-                "rx_bytes": {
-                    "description": "RxBytes is the number of received bytes.",
-                    "type": "integer"
+                "developers": {
+                    "type": "string"
                 },
-                "rx_packets": {
-                    "description": "RxPackets is the number of received packets.",
-                    "type": "integer"
+                "first_name": {
+                    "type": "string"
                 },
-                "session_count_jetbrains": {
-                    "description": "SessionCountJetBrains is the number of connections received by an agent\nthat are from our JetBrains extension.",
-                    "type": "integer"
+                "job_title": {
+                    "type": "string"
                 },
-                "session_count_reconnecting_pty": {
-                    "description": "SessionCountReconnectingPTY is the number of connections received by an agent\nthat are from the reconnecting web terminal.",
-                    "type": "integer"
+                "last_name": {
+                    "type": "string"
                 },
-                "session_count_ssh": {
-                    "description": "SessionCountSSH is the number of connections received by an agent\nthat are normal, non-tagged SSH sessions.",
-                    "type": "integer"
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
                 },
-                "session_count_vscode": {
-                    "description": "SessionCountVSCode is the number of connections received by an agent\nthat are from our VS Code extension.",
-                    "type": "integer"
+                "display_name": {
+                    "type": "string"
                 },
-                "tx_bytes": {
-                    "description": "TxBytes is the number of transmitted bytes.",
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "tx_packets": {
-                    "description": "TxPackets is the number of transmitted bytes.",
+                "quota_allowance": {
                     "type": "integer"
                 }
             }
         },
-        "agentsdk.StatsResponse": {
+        "codersdk.CreateOrganizationRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "report_interval": {
-                    "description": "ReportInterval is the duration after which the agent should send stats\nagain.",
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "clibase.Annotations": {
+        "codersdk.CreatePasskeyRequest": {
             "type": "object",
-            "additionalProperties": {
-                "type": "string"
+            "required": [
+                "attestation_object",
+                "challenge_id",
+                "client_data_json",
+                "name"
+            ],
+            "properties": {
+                "attestation_object": {
+                    "type": "string"
+                },
+                "challenge_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "client_data_json": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
             }
         },
-        "clibase.Group": {
+        "codersdk.CreateProvisionerKeyRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
                 "name": {
                     "type": "string"
                 },
-                "parent": {
-                    "$ref": "#/definitions/clibase.Group"
-                },
-                "yaml": {
-                    "type": "string"
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "clibase.HostPort": {
+        "codersdk.CreateProvisionerKeyResponse": {
             "type": "object",
             "properties": {
-                "host": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "key": {
                     "type": "string"
                 },
-                "port": {
+                "name": {
                     "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "rotated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "clibase.Option": {
+        "codersdk.CreateTemplateRequest": {
             "type": "object",
+            "required": [
+                "name",
+                "template_version_id"
+            ],
             "properties": {
-                "annotations": {
-                    "description": "Annotations enable extensions to clibase higher up in the stack. It's useful for\nhelp formatting and documentation generation.",
+                "allow_user_autostart": {
+                    "description": "AllowUserAutostart allows users to set a schedule for autostarting their\nworkspace. By default this is true. This can only be disabled when using\nan enterprise license.",
+                    "type": "boolean"
+                },
+                "allow_user_autostop": {
+                    "description": "AllowUserAutostop allows users to set a custom workspace TTL to use in\nplace of the template's DefaultTTL field. By default this is true. If\nfalse, the DefaultTTL will always be used. This can only be disabled when\nusing an enterprise license.",
+                    "type": "boolean"
+                },
+                "allow_user_cancel_workspace_jobs": {
+                    "description": "Allow users to cancel in-progress workspace jobs.\n*bool as the default value is \"true\".",
+                    "type": "boolean"
+                },
+                "autostart_requirement": {
+                    "description": "AutostartRequirement allows optionally specifying the autostart allowed days\nfor workspaces created from this template. This is an enterprise feature.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateAutostartRequirement"
+                        }
+                    ]
+                },
+                "autostop_requirement": {
+                    "description": "AutostopRequirement allows optionally specifying the autostop requirement\nfor workspaces created from this template. This is an enterprise feature.\nOnly one of MaxTTLMillis or AutostopRequirement can be specified.",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/clibase.Annotations"
+                            "$ref": "#/definitions/codersdk.TemplateAutostopRequirement"
                         }
                     ]
                 },
-                "default": {
-                    "description": "Default is parsed into Value if set.",
-                    "type": "string"
+                "default_ttl_ms": {
+                    "description": "DefaultTTLMillis allows optionally specifying the default TTL\nfor all workspaces created from this template.",
+                    "type": "integer"
+                },
+                "delete_ttl_ms": {
+                    "description": "TimeTilDormantAutoDeleteMillis allows optionally specifying the max lifetime before Coder\npermanently deletes dormant workspaces created from this template.",
+                    "type": "integer"
                 },
                 "description": {
+                    "description": "Description is a description of what the template contains. It must be\nless than 128 bytes.",
                     "type": "string"
                 },
-                "env": {
-                    "description": "Env is the environment variable used to configure this option. If unset,\nenvironment configuring is disabled.",
-                    "type": "string"
+                "disable_everyone_group_access": {
+                    "description": "DisableEveryoneGroupAccess allows optionally disabling the default\nbehavior of granting the 'everyone' group access to use the template.\nIf this is set to true, the template will not be available to all users,\nand must be explicitly granted to users or groups in the permissions settings\nof the template.",
+                    "type": "boolean"
                 },
-                "flag": {
-                    "description": "Flag is the long name of the flag used to configure this option. If unset,\nflag configuring is disabled.",
+                "display_name": {
+                    "description": "DisplayName is the displayed name of the template.",
                     "type": "string"
                 },
-                "flag_shorthand": {
-                    "description": "FlagShorthand is the one-character shorthand for the flag. If unset, no\nshorthand is used.",
-                    "type": "string"
+                "dormant_ttl_ms": {
+                    "description": "TimeTilDormantMillis allows optionally specifying the max lifetime before Coder\nlocks inactive workspaces created from this template.",
+                    "type": "integer"
                 },
-                "group": {
-                    "description": "Group is a group hierarchy that helps organize this option in help, configs\nand other documentation.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/clibase.Group"
-                        }
-                    ]
+                "failure_ttl_ms": {
+                    "description": "FailureTTLMillis allows optionally specifying the max lifetime before Coder\nstops all resources for failed workspaces created from this template.",
+                    "type": "integer"
                 },
-                "hidden": {
-                    "type": "boolean"
+                "icon": {
+                    "description": "Icon is a relative path or external URL that specifies\nan icon to be displayed in the dashboard.",
+                    "type": "string"
+                },
+                "max_ttl_ms": {
+                    "description": "TODO(@dean): remove max_ttl once autostop_requirement is matured\nOnly one of MaxTTLMillis or AutostopRequirement can be specified.",
+                    "type": "integer"
                 },
                 "name": {
+                    "description": "Name is the name of the template.",
                     "type": "string"
                 },
-                "required": {
-                    "description": "Required means this value must be set by some means. It requires\n` + "`" + `ValueSource != ValueSourceNone` + "`" + `\nIf ` + "`" + `Default` + "`" + ` is set, then ` + "`" + `Required` + "`" + ` is ignored.",
+                "require_active_version": {
+                    "description": "RequireActiveVersion mandates that workspaces are built with the active\ntemplate version.",
                     "type": "boolean"
                 },
-                "use_instead": {
-                    "description": "UseInstead is a list of options that should be used instead of this one.\nThe field is used to generate a deprecation warning.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/clibase.Option"
-                    }
-                },
-                "value": {
-                    "description": "Value includes the types listed in values.go."
-                },
-                "value_source": {
-                    "$ref": "#/definitions/clibase.ValueSource"
+                "template_version_id": {
+                    "description": "VersionID is an in-progress or completed job to use as an initial version\nof the template.\n\nThis is required on creation to enable a user-flow of validating a\ntemplate works. There is no reason the data-model cannot support empty\ntemplates, but it doesn't make sense for users.",
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "yaml": {
-                    "description": "YAML is the YAML key used to configure this option. If unset, YAML\nconfiguring is disabled.",
-                    "type": "string"
+                "visibility": {
+                    "description": "Visibility restricts who can see the template. Defaults to public.",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVisibility"
+                        }
+                    ]
                 }
             }
         },
-        "clibase.Regexp": {
-            "type": "object"
-        },
-        "clibase.Struct-array_codersdk_ExternalAuthConfig": {
+        "codersdk.CreateTemplateVersionDryRunRequest": {
             "type": "object",
             "properties": {
-                "value": {
+                "rich_parameter_values": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.ExternalAuthConfig"
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                },
+                "user_variable_values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.VariableValue"
                     }
+                },
+                "workspace_name": {
+                    "type": "string"
                 }
             }
         },
-        "clibase.Struct-array_codersdk_LinkConfig": {
+        "codersdk.CreateTemplateVersionPresetRequest": {
             "type": "object",
+            "required": [
+                "name",
+                "parameters"
+            ],
             "properties": {
-                "value": {
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "description": "Parameters must satisfy the validation of the parameters of the template\nversion.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.LinkConfig"
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
                     }
                 }
             }
         },
-        "clibase.URL": {
+        "codersdk.CreateTemplateVersionRequest": {
             "type": "object",
+            "required": [
+                "provisioner",
+                "storage_method"
+            ],
             "properties": {
-                "forceQuery": {
-                    "description": "append a query ('?') even if RawQuery is empty",
-                    "type": "boolean"
-                },
-                "fragment": {
-                    "description": "fragment for references, without '#'",
-                    "type": "string"
-                },
-                "host": {
-                    "description": "host or host:port",
-                    "type": "string"
-                },
-                "omitHost": {
-                    "description": "do not emit empty host (authority)",
-                    "type": "boolean"
-                },
-                "opaque": {
-                    "description": "encoded opaque data",
-                    "type": "string"
-                },
-                "path": {
-                    "description": "path (relative paths may omit leading slash)",
-                    "type": "string"
-                },
-                "rawFragment": {
-                    "description": "encoded fragment hint (see EscapedFragment method)",
-                    "type": "string"
-                },
-                "rawPath": {
-                    "description": "encoded path hint (see EscapedPath method)",
-                    "type": "string"
-                },
-                "rawQuery": {
-                    "description": "encoded query values, without '?'",
+                "example_id": {
                     "type": "string"
                 },
-                "scheme": {
-                    "type": "string"
+                "file_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "user": {
-                    "description": "username and password information",
+                "git": {
+                    "description": "Git optionally records the git commit the version was created from.",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/url.Userinfo"
+                            "$ref": "#/definitions/codersdk.TemplateVersionGitMetadata"
                         }
                     ]
-                }
-            }
-        },
-        "clibase.ValueSource": {
-            "type": "string",
-            "enum": [
-                "",
-                "flag",
-                "env",
-                "yaml",
-                "default"
-            ],
-            "x-enum-varnames": [
-                "ValueSourceNone",
-                "ValueSourceFlag",
-                "ValueSourceEnv",
-                "ValueSourceYAML",
-                "ValueSourceDefault"
-            ]
-        },
-        "coderd.SCIMUser": {
-            "type": "object",
-            "properties": {
-                "active": {
-                    "type": "boolean"
-                },
-                "emails": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "display": {
-                                "type": "string"
-                            },
-                            "primary": {
-                                "type": "boolean"
-                            },
-                            "type": {
-                                "type": "string"
-                            },
-                            "value": {
-                                "type": "string",
-                                "format": "email"
-                            }
-                        }
-                    }
-                },
-                "groups": {
-                    "type": "array",
-                    "items": {}
                 },
-                "id": {
+                "message": {
                     "type": "string"
                 },
-                "meta": {
-                    "type": "object",
-                    "properties": {
-                        "resourceType": {
-                            "type": "string"
-                        }
-                    }
-                },
                 "name": {
-                    "type": "object",
-                    "properties": {
-                        "familyName": {
-                            "type": "string"
-                        },
-                        "givenName": {
-                            "type": "string"
-                        }
-                    }
+                    "type": "string"
                 },
-                "schemas": {
+                "provisioner": {
+                    "type": "string",
+                    "enum": [
+                        "terraform",
+                        "echo",
+                        "pulumi",
+                        "kubernetes"
+                    ]
+                },
+                "provisioner_requirements": {
+                    "description": "ProvisionerRequirements constrain the tags of the provisioner daemons\nthat may build the template version, e.g. \"region in [eu-west, eu-*]\" or\n\"gpu=true\". Jobs of workspaces built from the version inherit them.",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "userName": {
-                    "type": "string"
-                }
-            }
-        },
-        "coderd.cspViolation": {
-            "type": "object",
-            "properties": {
-                "csp-report": {
+                "storage_method": {
+                    "enum": [
+                        "file"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerStorageMethod"
+                        }
+                    ]
+                },
+                "tags": {
                     "type": "object",
-                    "additionalProperties": true
-                }
-            }
-        },
-        "codersdk.ACLAvailable": {
-            "type": "object",
-            "properties": {
-                "groups": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.Group"
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "users": {
+                "template_id": {
+                    "description": "TemplateID optionally associates a version with a template.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_variable_values": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.User"
+                        "$ref": "#/definitions/codersdk.VariableValue"
                     }
                 }
             }
         },
-        "codersdk.APIKey": {
+        "codersdk.CreateTestAuditLogRequest": {
             "type": "object",
-            "required": [
-                "created_at",
-                "expires_at",
-                "id",
-                "last_used",
-                "lifetime_seconds",
-                "login_type",
-                "scope",
-                "token_name",
-                "updated_at",
-                "user_id"
-            ],
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "format": "date-time"
-                },
-                "expires_at": {
-                    "type": "string",
-                    "format": "date-time"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "last_used": {
-                    "type": "string",
-                    "format": "date-time"
+                "action": {
+                    "enum": [
+                        "create",
+                        "write",
+                        "delete",
+                        "start",
+                        "stop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AuditAction"
+                        }
+                    ]
                 },
-                "lifetime_seconds": {
-                    "type": "integer"
+                "additional_fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "login_type": {
+                "build_reason": {
                     "enum": [
-                        "password",
-                        "github",
-                        "oidc",
-                        "token"
+                        "autostart",
+                        "autostop",
+                        "initiator"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.LoginType"
+                            "$ref": "#/definitions/codersdk.BuildReason"
                         }
                     ]
                 },
-                "scope": {
+                "resource_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "resource_type": {
                     "enum": [
-                        "all",
-                        "application_connect"
+                        "template",
+                        "template_version",
+                        "user",
+                        "workspace",
+                        "workspace_build",
+                        "git_ssh_key",
+                        "auditable_group"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.APIKeyScope"
+                            "$ref": "#/definitions/codersdk.ResourceType"
                         }
                     ]
                 },
-                "token_name": {
-                    "type": "string"
-                },
-                "updated_at": {
+                "time": {
                     "type": "string",
                     "format": "date-time"
-                },
-                "user_id": {
-                    "type": "string",
-                    "format": "uuid"
-                }
-            }
-        },
-        "codersdk.APIKeyScope": {
-            "type": "string",
-            "enum": [
-                "all",
-                "application_connect"
-            ],
-            "x-enum-varnames": [
-                "APIKeyScopeAll",
-                "APIKeyScopeApplicationConnect"
-            ]
-        },
-        "codersdk.AddLicenseRequest": {
-            "type": "object",
-            "required": [
-                "license"
-            ],
-            "properties": {
-                "license": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.AgentSubsystem": {
-            "type": "string",
-            "enum": [
-                "envbox",
-                "envbuilder",
-                "exectrace"
-            ],
-            "x-enum-varnames": [
-                "AgentSubsystemEnvbox",
-                "AgentSubsystemEnvbuilder",
-                "AgentSubsystemExectrace"
-            ]
-        },
-        "codersdk.AppHostResponse": {
-            "type": "object",
-            "properties": {
-                "host": {
-                    "description": "Host is the externally accessible URL for the Coder instance.",
-                    "type": "string"
                 }
             }
         },
-        "codersdk.AppearanceConfig": {
+        "codersdk.CreateTokenRequest": {
             "type": "object",
             "properties": {
-                "application_name": {
-                    "type": "string"
+                "allowed_workspace_ids": {
+                    "description": "AllowedWorkspaceIDs limits a token with fine-grained scopes to the\ngiven workspaces.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
                 },
-                "logo_url": {
-                    "type": "string"
+                "ip_allowlist": {
+                    "description": "IPAllowlist limits the token to the given CIDR ranges or IP addresses.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "service_banner": {
-                    "$ref": "#/definitions/codersdk.ServiceBannerConfig"
+                "lifetime": {
+                    "type": "integer"
                 },
-                "support_links": {
+                "scope": {
+                    "enum": [
+                        "all",
+                        "application_connect"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.APIKeyScope"
+                        }
+                    ]
+                },
+                "scopes": {
+                    "description": "Scopes limits the token to the given fine-grained scopes. They cannot\nbe combined with the application_connect scope.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.LinkConfig"
+                        "enum": [
+                            "workspace:read",
+                            "workspace:write",
+                            "template:read",
+                            "template:write",
+                            "user:read"
+                        ],
+                        "$ref": "#/definitions/codersdk.APITokenScope"
                     }
+                },
+                "token_name": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.ArchiveTemplateVersionsRequest": {
-            "type": "object",
-            "properties": {
-                "all": {
-                    "description": "By default, only failed versions are archived. Set this to true\nto archive all unused versions regardless of job status.",
-                    "type": "boolean"
-                }
-            }
-        },
-        "codersdk.AssignableRoles": {
+        "codersdk.CreateUserRequest": {
             "type": "object",
+            "required": [
+                "email",
+                "username"
+            ],
             "properties": {
-                "assignable": {
+                "disable_login": {
+                    "description": "DisableLogin sets the user's login type to 'none'. This prevents the user\nfrom being able to use a password or any other authentication method to login.\nDeprecated: Set UserLoginType=LoginTypeDisabled instead.",
                     "type": "boolean"
                 },
-                "display_name": {
-                    "type": "string"
+                "email": {
+                    "type": "string",
+                    "format": "email"
                 },
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.AuditAction": {
-            "type": "string",
-            "enum": [
-                "create",
-                "write",
-                "delete",
-                "start",
-                "stop",
-                "login",
-                "logout",
-                "register"
-            ],
-            "x-enum-varnames": [
-                "AuditActionCreate",
-                "AuditActionWrite",
-                "AuditActionDelete",
-                "AuditActionStart",
-                "AuditActionStop",
-                "AuditActionLogin",
-                "AuditActionLogout",
-                "AuditActionRegister"
-            ]
-        },
-        "codersdk.AuditDiff": {
-            "type": "object",
-            "additionalProperties": {
-                "$ref": "#/definitions/codersdk.AuditDiffField"
+                "login_type": {
+                    "description": "UserLoginType defaults to LoginTypePassword.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.LoginType"
+                        }
+                    ]
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
             }
         },
-        "codersdk.AuditDiffField": {
+        "codersdk.CreateWebhookRequest": {
             "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
             "properties": {
-                "new": {},
-                "old": {},
-                "secret": {
-                    "type": "boolean"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.AuditLog": {
+        "codersdk.CreateWorkspaceBuildRequest": {
             "type": "object",
+            "required": [
+                "transition"
+            ],
             "properties": {
-                "action": {
-                    "$ref": "#/definitions/codersdk.AuditAction"
+                "dry_run": {
+                    "description": "DryRun plans the build without applying it. A provisioner job is\nreturned instead of a workspace build, and the workspace is unchanged.",
+                    "type": "boolean"
                 },
-                "additional_fields": {
+                "log_level": {
+                    "description": "Log level changes the default logging verbosity of a provider (\"info\" if empty).",
+                    "enum": [
+                        "debug"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerLogLevel"
+                        }
+                    ]
+                },
+                "orphan": {
+                    "description": "Orphan may be set for the Destroy transition.",
+                    "type": "boolean"
+                },
+                "rich_parameter_values": {
+                    "description": "ParameterValues are optional. It will write params to the 'workspace' scope.\nThis will overwrite any existing parameters with the same name.\nThis will not delete old params not included in this list.",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
                     }
                 },
-                "description": {
-                    "type": "string"
-                },
-                "diff": {
-                    "$ref": "#/definitions/codersdk.AuditDiff"
+                "state": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "id": {
+                "template_version_id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "ip": {
+                "transition": {
+                    "enum": [
+                        "create",
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.CreateWorkspaceProxyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "display_name": {
                     "type": "string"
                 },
-                "is_deleted": {
-                    "type": "boolean"
-                },
-                "organization_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "icon": {
+                    "type": "string"
                 },
-                "request_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateWorkspaceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "automatic_updates": {
+                    "$ref": "#/definitions/codersdk.AutomaticUpdates"
                 },
-                "resource_icon": {
+                "autostart_schedule": {
                     "type": "string"
                 },
-                "resource_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "ephemeral": {
+                    "description": "Ephemeral creates a workspace that is deleted, rather than stopped,\nonce it outlives its TTL or the API token used to create it expires.",
+                    "type": "boolean"
                 },
-                "resource_link": {
+                "name": {
                     "type": "string"
                 },
-                "resource_target": {
-                    "description": "ResourceTarget is the name of the resource.",
+                "preset": {
+                    "description": "Preset is the name of a preset of the template version. Its values are\nused for the parameters that aren't in RichParameterValues.",
                     "type": "string"
                 },
-                "resource_type": {
-                    "$ref": "#/definitions/codersdk.ResourceType"
-                },
-                "status_code": {
-                    "type": "integer"
+                "rich_parameter_values": {
+                    "description": "RichParameterValues allows for additional parameters to be provided\nduring the initial provision.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
                 },
-                "time": {
+                "template_id": {
+                    "description": "TemplateID specifies which template should be used for creating the workspace.",
                     "type": "string",
-                    "format": "date-time"
+                    "format": "uuid"
                 },
-                "user": {
-                    "$ref": "#/definitions/codersdk.User"
+                "template_version_id": {
+                    "description": "TemplateVersionID can be used to specify a specific version of a template for creating the workspace.",
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "user_agent": {
+                "ttl_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.CreateWorkspaceSnapshotRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name defaults to a name derived from the current time.",
                     "type": "string"
                 }
             }
         },
-        "codersdk.AuditLogResponse": {
+        "codersdk.CustomRole": {
             "type": "object",
             "properties": {
-                "audit_logs": {
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "site_permissions": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.AuditLog"
+                        "$ref": "#/definitions/codersdk.Permission"
                     }
-                },
-                "count": {
-                    "type": "integer"
                 }
             }
         },
-        "codersdk.AuthMethod": {
+        "codersdk.DAUEntry": {
             "type": "object",
             "properties": {
-                "enabled": {
-                    "type": "boolean"
+                "amount": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
-        "codersdk.AuthMethods": {
+        "codersdk.DAUsResponse": {
             "type": "object",
             "properties": {
-                "github": {
-                    "$ref": "#/definitions/codersdk.AuthMethod"
-                },
-                "oidc": {
-                    "$ref": "#/definitions/codersdk.OIDCAuthMethod"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DAUEntry"
+                    }
                 },
-                "password": {
-                    "$ref": "#/definitions/codersdk.AuthMethod"
+                "tz_hour_offset": {
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.AuthorizationCheck": {
-            "description": "AuthorizationCheck is used to check if the currently authenticated user (or the specified user) can do a given action to a given set of objects.",
+        "codersdk.DERP": {
             "type": "object",
             "properties": {
-                "action": {
-                    "type": "string",
-                    "enum": [
-                        "create",
-                        "read",
-                        "update",
-                        "delete"
-                    ]
+                "config": {
+                    "$ref": "#/definitions/codersdk.DERPConfig"
                 },
-                "object": {
-                    "description": "Object can represent a \"set\" of objects, such as: all workspaces in an organization, all workspaces owned by me, and all workspaces across the entire product.\nWhen defining an object, use the most specific language when possible to\nproduce the smallest set. Meaning to set as many fields on 'Object' as\nyou can. Example, if you want to check if you can update all workspaces\nowned by 'me', try to also add an 'OrganizationID' to the settings.\nOmitting the 'OrganizationID' could produce the incorrect value, as\nworkspaces have both ` + "`" + `user` + "`" + ` and ` + "`" + `organization` + "`" + ` owners.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.AuthorizationObject"
-                        }
-                    ]
+                "server": {
+                    "$ref": "#/definitions/codersdk.DERPServerConfig"
                 }
             }
         },
-        "codersdk.AuthorizationObject": {
-            "description": "AuthorizationObject can represent a \"set\" of objects, such as: all workspaces in an organization, all workspaces owned by me, all workspaces across the entire product.",
+        "codersdk.DERPConfig": {
             "type": "object",
             "properties": {
-                "organization_id": {
-                    "description": "OrganizationID (optional) adds the set constraint to all resources owned by a given organization.",
-                    "type": "string"
+                "block_direct": {
+                    "type": "boolean"
                 },
-                "owner_id": {
-                    "description": "OwnerID (optional) adds the set constraint to all resources owned by a given user.",
-                    "type": "string"
+                "force_websockets": {
+                    "type": "boolean"
                 },
-                "resource_id": {
-                    "description": "ResourceID (optional) reduces the set to a singular resource. This assigns\na resource ID to the resource type, eg: a single workspace.\nThe rbac library will not fetch the resource from the database, so if you\nare using this option, you should also set the owner ID and organization ID\nif possible. Be as specific as possible using all the fields relevant.",
+                "health_check_interval": {
+                    "type": "integer"
+                },
+                "node_key_rotation_interval": {
+                    "type": "integer"
+                },
+                "path": {
                     "type": "string"
                 },
-                "resource_type": {
-                    "description": "ResourceType is the name of the resource.\n` + "`" + `./coderd/rbac/object.go` + "`" + ` has the list of valid resource types.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.RBACResource"
-                        }
-                    ]
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.AuthorizationRequest": {
+        "codersdk.DERPRegion": {
             "type": "object",
             "properties": {
-                "checks": {
-                    "description": "Checks is a map keyed with an arbitrary string to a permission check.\nThe key can be any string that is helpful to the caller, and allows\nmultiple permission checks to be run in a single request.\nThe key ensures that each permission check has the same key in the\nresponse.",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/codersdk.AuthorizationCheck"
-                    }
-                }
-            }
-        },
-        "codersdk.AuthorizationResponse": {
-            "type": "object",
-            "additionalProperties": {
-                "type": "boolean"
+                "latency_ms": {
+                    "type": "number"
+                },
+                "preferred": {
+                    "type": "boolean"
+                }
             }
         },
-        "codersdk.AutomaticUpdates": {
-            "type": "string",
-            "enum": [
-                "always",
-                "never"
-            ],
-            "x-enum-varnames": [
-                "AutomaticUpdatesAlways",
-                "AutomaticUpdatesNever"
-            ]
-        },
-        "codersdk.BuildInfoResponse": {
+        "codersdk.DERPServerConfig": {
             "type": "object",
             "properties": {
-                "agent_api_version": {
-                    "description": "AgentAPIVersion is the current version of the Agent API (back versions\nMAY still be supported).",
-                    "type": "string"
+                "enable": {
+                    "type": "boolean"
                 },
-                "dashboard_url": {
-                    "description": "DashboardURL is the URL to hit the deployment's dashboard.\nFor external workspace proxies, this is the coderd they are connected\nto.",
+                "region_code": {
                     "type": "string"
                 },
-                "external_url": {
-                    "description": "ExternalURL references the current Coder version.\nFor production builds, this will link directly to a release. For development builds, this will link to a commit.",
-                    "type": "string"
+                "region_id": {
+                    "type": "integer"
                 },
-                "version": {
-                    "description": "Version returns the semantic version of the build.",
+                "region_name": {
                     "type": "string"
                 },
-                "workspace_proxy": {
-                    "type": "boolean"
+                "relay_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "stun_addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "codersdk.BuildReason": {
-            "type": "string",
-            "enum": [
-                "initiator",
-                "autostart",
-                "autostop"
-            ],
-            "x-enum-varnames": [
-                "BuildReasonInitiator",
-                "BuildReasonAutostart",
-                "BuildReasonAutostop"
-            ]
-        },
-        "codersdk.ConnectionLatency": {
+        "codersdk.DangerousConfig": {
             "type": "object",
             "properties": {
-                "p50": {
-                    "type": "number",
-                    "example": 31.312
+                "allow_all_cors": {
+                    "type": "boolean"
                 },
-                "p95": {
-                    "type": "number",
-                    "example": 119.832
+                "allow_path_app_sharing": {
+                    "type": "boolean"
+                },
+                "allow_path_app_site_owner_access": {
+                    "type": "boolean"
                 }
             }
         },
-        "codersdk.ConvertLoginRequest": {
+        "codersdk.DeleteWorkspaceAgentPortShareRequest": {
             "type": "object",
             "required": [
-                "password",
-                "to_type"
+                "agent_name",
+                "port"
             ],
             "properties": {
-                "password": {
+                "agent_name": {
                     "type": "string"
                 },
-                "to_type": {
-                    "description": "ToType is the login type to convert to.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.LoginType"
-                        }
-                    ]
+                "port": {
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.CreateFirstUserRequest": {
+        "codersdk.DeploymentConfig": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "trial": {
-                    "type": "boolean"
-                },
-                "trial_info": {
-                    "$ref": "#/definitions/codersdk.CreateFirstUserTrialInfo"
+                "config": {
+                    "$ref": "#/definitions/codersdk.DeploymentValues"
                 },
-                "username": {
-                    "type": "string"
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clibase.Option"
+                    }
                 }
             }
         },
-        "codersdk.CreateFirstUserResponse": {
+        "codersdk.DeploymentStats": {
             "type": "object",
             "properties": {
-                "organization_id": {
+                "aggregated_from": {
+                    "description": "AggregatedFrom is the time in which stats are aggregated from.\nThis might be back in time a specific duration or interval.",
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
                 },
-                "user_id": {
+                "collected_at": {
+                    "description": "CollectedAt is the time in which stats are collected at.",
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
+                },
+                "next_update_at": {
+                    "description": "NextUpdateAt is the time when the next batch of stats will\nbe updated.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "session_count": {
+                    "$ref": "#/definitions/codersdk.SessionCountDeploymentStats"
+                },
+                "workspaces": {
+                    "$ref": "#/definitions/codersdk.WorkspaceDeploymentStats"
                 }
             }
         },
-        "codersdk.CreateFirstUserTrialInfo": {
+        "codersdk.DeploymentValues": {
             "type": "object",
             "properties": {
-                "company_name": {
-                    "type": "string"
+                "access_url": {
+                    "$ref": "#/definitions/clibase.URL"
                 },
-                "country": {
-                    "type": "string"
+                "address": {
+                    "description": "DEPRECATED: Use HTTPAddress or TLS.Address instead.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.HostPort"
+                        }
+                    ]
                 },
-                "developers": {
-                    "type": "string"
+                "agent_fallback_troubleshooting_url": {
+                    "$ref": "#/definitions/clibase.URL"
                 },
-                "first_name": {
-                    "type": "string"
+                "agent_metadata_history": {
+                    "type": "integer"
                 },
-                "job_title": {
+                "agent_quic_address": {
                     "type": "string"
                 },
-                "last_name": {
-                    "type": "string"
+                "agent_stat_refresh_interval": {
+                    "type": "integer"
                 },
-                "phone_number": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.CreateGroupRequest": {
-            "type": "object",
-            "properties": {
-                "avatar_url": {
+                "allow_workspace_renames": {
+                    "type": "boolean"
+                },
+                "audit_log_export": {
+                    "$ref": "#/definitions/codersdk.AuditLogExportConfig"
+                },
+                "autobuild_poll_interval": {
+                    "type": "integer"
+                },
+                "browser_only": {
+                    "type": "boolean"
+                },
+                "cache_directory": {
                     "type": "string"
                 },
-                "display_name": {
+                "config": {
                     "type": "string"
                 },
-                "name": {
+                "config_ssh": {
+                    "$ref": "#/definitions/codersdk.SSHConfig"
+                },
+                "dangerous": {
+                    "$ref": "#/definitions/codersdk.DangerousConfig"
+                },
+                "derp": {
+                    "$ref": "#/definitions/codersdk.DERP"
+                },
+                "disable_owner_workspace_exec": {
+                    "type": "boolean"
+                },
+                "disable_password_auth": {
+                    "type": "boolean"
+                },
+                "disable_path_apps": {
+                    "type": "boolean"
+                },
+                "disable_session_expiry_refresh": {
+                    "type": "boolean"
+                },
+                "docs_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "enable_terraform_debug_mode": {
+                    "type": "boolean"
+                },
+                "examples_registry_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "experiments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_auth": {
+                    "$ref": "#/definitions/clibase.Struct-array_codersdk_ExternalAuthConfig"
+                },
+                "external_token_encryption_keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_token_encryption_kms_token": {
                     "type": "string"
                 },
-                "quota_allowance": {
-                    "type": "integer"
-                }
-            }
-        },
-        "codersdk.CreateOrganizationRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "name": {
+                "external_token_encryption_kms_url": {
+                    "type": "string"
+                },
+                "healthcheck": {
+                    "$ref": "#/definitions/codersdk.HealthcheckConfig"
+                },
+                "http_address": {
+                    "description": "HTTPAddress is a string because it may be set to zero to disable.",
                     "type": "string"
-                }
-            }
-        },
-        "codersdk.CreateTemplateRequest": {
-            "type": "object",
-            "required": [
-                "name",
-                "template_version_id"
-            ],
-            "properties": {
-                "allow_user_autostart": {
-                    "description": "AllowUserAutostart allows users to set a schedule for autostarting their\nworkspace. By default this is true. This can only be disabled when using\nan enterprise license.",
-                    "type": "boolean"
                 },
-                "allow_user_autostop": {
-                    "description": "AllowUserAutostop allows users to set a custom workspace TTL to use in\nplace of the template's DefaultTTL field. By default this is true. If\nfalse, the DefaultTTL will always be used. This can only be disabled when\nusing an enterprise license.",
+                "in_memory_database": {
                     "type": "boolean"
                 },
-                "allow_user_cancel_workspace_jobs": {
-                    "description": "Allow users to cancel in-progress workspace jobs.\n*bool as the default value is \"true\".",
-                    "type": "boolean"
+                "job_hang_detector_interval": {
+                    "type": "integer"
                 },
-                "autostart_requirement": {
-                    "description": "AutostartRequirement allows optionally specifying the autostart allowed days\nfor workspaces created from this template. This is an enterprise feature.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.TemplateAutostartRequirement"
-                        }
-                    ]
+                "ldap": {
+                    "$ref": "#/definitions/codersdk.LDAPConfig"
                 },
-                "autostop_requirement": {
-                    "description": "AutostopRequirement allows optionally specifying the autostop requirement\nfor workspaces created from this template. This is an enterprise feature.\nOnly one of MaxTTLMillis or AutostopRequirement can be specified.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.TemplateAutostopRequirement"
-                        }
-                    ]
+                "license_grace_period": {
+                    "type": "integer"
                 },
-                "default_ttl_ms": {
-                    "description": "DefaultTTLMillis allows optionally specifying the default TTL\nfor all workspaces created from this template.",
+                "logging": {
+                    "$ref": "#/definitions/codersdk.LoggingConfig"
+                },
+                "login_throttle": {
+                    "$ref": "#/definitions/codersdk.LoginThrottleConfig"
+                },
+                "max_concurrent_sessions": {
                     "type": "integer"
                 },
-                "delete_ttl_ms": {
-                    "description": "TimeTilDormantAutoDeleteMillis allows optionally specifying the max lifetime before Coder\npermanently deletes dormant workspaces created from this template.",
+                "max_session_expiry": {
                     "type": "integer"
                 },
-                "description": {
-                    "description": "Description is a description of what the template contains. It must be\nless than 128 bytes.",
-                    "type": "string"
+                "max_token_lifetime": {
+                    "type": "integer"
                 },
-                "disable_everyone_group_access": {
-                    "description": "DisableEveryoneGroupAccess allows optionally disabling the default\nbehavior of granting the 'everyone' group access to use the template.\nIf this is set to true, the template will not be available to all users,\nand must be explicitly granted to users or groups in the permissions settings\nof the template.",
-                    "type": "boolean"
+                "metrics_cache_refresh_interval": {
+                    "type": "integer"
                 },
-                "display_name": {
-                    "description": "DisplayName is the displayed name of the template.",
-                    "type": "string"
+                "metrics_export": {
+                    "$ref": "#/definitions/codersdk.MetricsExportConfig"
                 },
-                "dormant_ttl_ms": {
-                    "description": "TimeTilDormantMillis allows optionally specifying the max lifetime before Coder\nlocks inactive workspaces created from this template.",
+                "notifications": {
+                    "$ref": "#/definitions/codersdk.NotificationsConfig"
+                },
+                "oauth2": {
+                    "$ref": "#/definitions/codersdk.OAuth2Config"
+                },
+                "oidc": {
+                    "$ref": "#/definitions/codersdk.OIDCConfig"
+                },
+                "owner_disable_session_expiry_refresh": {
+                    "type": "boolean"
+                },
+                "owner_max_session_expiry": {
                     "type": "integer"
                 },
-                "failure_ttl_ms": {
-                    "description": "FailureTTLMillis allows optionally specifying the max lifetime before Coder\nstops all resources for failed workspaces created from this template.",
+                "owner_max_token_lifetime": {
                     "type": "integer"
                 },
-                "icon": {
-                    "description": "Icon is a relative path or external URL that specifies\nan icon to be displayed in the dashboard.",
+                "pg_connection_url": {
                     "type": "string"
                 },
-                "max_ttl_ms": {
-                    "description": "TODO(@dean): remove max_ttl once autostop_requirement is matured\nOnly one of MaxTTLMillis or AutostopRequirement can be specified.",
+                "pg_replica_connection_urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pg_replica_max_lag": {
                     "type": "integer"
                 },
-                "name": {
-                    "description": "Name is the name of the template.",
-                    "type": "string"
+                "pprof": {
+                    "$ref": "#/definitions/codersdk.PprofConfig"
                 },
-                "require_active_version": {
-                    "description": "RequireActiveVersion mandates that workspaces are built with the active\ntemplate version.",
-                    "type": "boolean"
+                "prometheus": {
+                    "$ref": "#/definitions/codersdk.PrometheusConfig"
                 },
-                "template_version_id": {
-                    "description": "VersionID is an in-progress or completed job to use as an initial version\nof the template.\n\nThis is required on creation to enable a user-flow of validating a\ntemplate works. There is no reason the data-model cannot support empty\ntemplates, but it doesn't make sense for users.",
-                    "type": "string",
-                    "format": "uuid"
-                }
-            }
-        },
-        "codersdk.CreateTemplateVersionDryRunRequest": {
-            "type": "object",
-            "properties": {
-                "rich_parameter_values": {
+                "provisioner": {
+                    "$ref": "#/definitions/codersdk.ProvisionerConfig"
+                },
+                "proxy_health_status_interval": {
+                    "type": "integer"
+                },
+                "proxy_trusted_headers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                        "type": "string"
                     }
                 },
-                "user_variable_values": {
+                "proxy_trusted_origins": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.VariableValue"
+                        "type": "string"
                     }
                 },
-                "workspace_name": {
+                "pubsub_backend": {
                     "type": "string"
-                }
-            }
-        },
-        "codersdk.CreateTemplateVersionRequest": {
-            "type": "object",
-            "required": [
-                "provisioner",
-                "storage_method"
-            ],
-            "properties": {
-                "example_id": {
+                },
+                "pubsub_url": {
                     "type": "string"
                 },
-                "file_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "rate_limit": {
+                    "$ref": "#/definitions/codersdk.RateLimitConfig"
                 },
-                "message": {
-                    "type": "string"
+                "redirect_to_access_url": {
+                    "type": "boolean"
                 },
-                "name": {
+                "scim_api_key": {
                     "type": "string"
                 },
-                "provisioner": {
-                    "type": "string",
-                    "enum": [
-                        "terraform",
-                        "echo"
-                    ]
+                "secure_auth_cookie": {
+                    "type": "boolean"
                 },
-                "storage_method": {
-                    "enum": [
-                        "file"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.ProvisionerStorageMethod"
-                        }
-                    ]
+                "session_recording_storage_url": {
+                    "type": "string"
                 },
-                "tags": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "ssh_keygen_algorithm": {
+                    "type": "string"
                 },
-                "template_id": {
-                    "description": "TemplateID optionally associates a version with a template.",
-                    "type": "string",
-                    "format": "uuid"
+                "strict_transport_security": {
+                    "type": "integer"
                 },
-                "user_variable_values": {
+                "strict_transport_security_options": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.VariableValue"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "codersdk.CreateTestAuditLogRequest": {
-            "type": "object",
-            "properties": {
-                "action": {
-                    "enum": [
-                        "create",
-                        "write",
-                        "delete",
-                        "start",
-                        "stop"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.AuditAction"
-                        }
-                    ]
                 },
-                "additional_fields": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "support": {
+                    "$ref": "#/definitions/codersdk.SupportConfig"
                 },
-                "build_reason": {
-                    "enum": [
-                        "autostart",
-                        "autostop",
-                        "initiator"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.BuildReason"
-                        }
-                    ]
+                "suspend_inactive_users_after": {
+                    "type": "integer"
                 },
-                "resource_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "swagger": {
+                    "$ref": "#/definitions/codersdk.SwaggerConfig"
                 },
-                "resource_type": {
-                    "enum": [
-                        "template",
-                        "template_version",
-                        "user",
-                        "workspace",
-                        "workspace_build",
-                        "git_ssh_key",
-                        "auditable_group"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.ResourceType"
-                        }
-                    ]
+                "telemetry": {
+                    "$ref": "#/definitions/codersdk.TelemetryConfig"
                 },
-                "time": {
-                    "type": "string",
-                    "format": "date-time"
-                }
-            }
-        },
-        "codersdk.CreateTokenRequest": {
-            "type": "object",
-            "properties": {
-                "lifetime": {
-                    "type": "integer"
+                "tls": {
+                    "$ref": "#/definitions/codersdk.TLSConfig"
                 },
-                "scope": {
-                    "enum": [
-                        "all",
-                        "application_connect"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.APIKeyScope"
-                        }
-                    ]
+                "trace": {
+                    "$ref": "#/definitions/codersdk.TraceConfig"
+                },
+                "truncate_token_lifetimes": {
+                    "type": "boolean"
+                },
+                "update_check": {
+                    "type": "boolean"
+                },
+                "user_quiet_hours_schedule": {
+                    "$ref": "#/definitions/codersdk.UserQuietHoursScheduleConfig"
+                },
+                "verbose": {
+                    "type": "boolean"
                 },
-                "token_name": {
+                "web_terminal_renderer": {
+                    "type": "string"
+                },
+                "webhooks_allow_private_addresses": {
+                    "type": "boolean"
+                },
+                "wgtunnel_host": {
+                    "type": "string"
+                },
+                "wildcard_access_url": {
                     "type": "string"
+                },
+                "write_config": {
+                    "type": "boolean"
                 }
             }
         },
-        "codersdk.CreateUserRequest": {
-            "type": "object",
-            "required": [
-                "email",
-                "username"
+        "codersdk.DisplayApp": {
+            "type": "string",
+            "enum": [
+                "vscode",
+                "vscode_insiders",
+                "web_terminal",
+                "port_forwarding_helper",
+                "ssh_helper"
+            ],
+            "x-enum-varnames": [
+                "DisplayAppVSCodeDesktop",
+                "DisplayAppVSCodeInsiders",
+                "DisplayAppWebTerminal",
+                "DisplayAppPortForward",
+                "DisplayAppSSH"
+            ]
+        },
+        "codersdk.Entitlement": {
+            "type": "string",
+            "enum": [
+                "entitled",
+                "grace_period",
+                "not_entitled"
             ],
+            "x-enum-varnames": [
+                "EntitlementEntitled",
+                "EntitlementGracePeriod",
+                "EntitlementNotEntitled"
+            ]
+        },
+        "codersdk.Entitlements": {
+            "type": "object",
             "properties": {
-                "disable_login": {
-                    "description": "DisableLogin sets the user's login type to 'none'. This prevents the user\nfrom being able to use a password or any other authentication method to login.\nDeprecated: Set UserLoginType=LoginTypeDisabled instead.",
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.Feature"
+                    }
+                },
+                "has_license": {
                     "type": "boolean"
                 },
-                "email": {
+                "read_only_until": {
+                    "description": "ReadOnlyUntil is set while every license has expired but enterprise\nfeatures stay available read-only for the license grace period.",
                     "type": "string",
-                    "format": "email"
-                },
-                "login_type": {
-                    "description": "UserLoginType defaults to LoginTypePassword.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.LoginType"
-                        }
-                    ]
+                    "format": "date-time"
                 },
-                "organization_id": {
+                "refreshed_at": {
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
                 },
-                "password": {
-                    "type": "string"
+                "require_telemetry": {
+                    "type": "boolean"
                 },
-                "username": {
-                    "type": "string"
+                "trial": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "codersdk.CreateWorkspaceBuildRequest": {
-            "type": "object",
-            "required": [
-                "transition"
+        "codersdk.Experiment": {
+            "type": "string",
+            "enum": [
+                "example"
             ],
+            "x-enum-comments": {
+                "ExperimentExample": "This isn't used for anything."
+            },
+            "x-enum-varnames": [
+                "ExperimentExample"
+            ]
+        },
+        "codersdk.ExternalAuth": {
+            "type": "object",
             "properties": {
-                "dry_run": {
+                "app_install_url": {
+                    "description": "AppInstallURL is the URL to install the app.",
+                    "type": "string"
+                },
+                "app_installable": {
+                    "description": "AppInstallable is true if the request for app installs was successful.",
                     "type": "boolean"
                 },
-                "log_level": {
-                    "description": "Log level changes the default logging verbosity of a provider (\"info\" if empty).",
-                    "enum": [
-                        "debug"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.ProvisionerLogLevel"
-                        }
-                    ]
+                "authenticated": {
+                    "type": "boolean"
                 },
-                "orphan": {
-                    "description": "Orphan may be set for the Destroy transition.",
+                "device": {
                     "type": "boolean"
                 },
-                "rich_parameter_values": {
-                    "description": "ParameterValues are optional. It will write params to the 'workspace' scope.\nThis will overwrite any existing parameters with the same name.\nThis will not delete old params not included in this list.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
-                    }
+                "display_name": {
+                    "type": "string"
                 },
-                "state": {
+                "installations": {
+                    "description": "AppInstallations are the installations that the user has access to.",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/codersdk.ExternalAuthAppInstallation"
                     }
                 },
-                "template_version_id": {
-                    "type": "string",
-                    "format": "uuid"
-                },
-                "transition": {
-                    "enum": [
-                        "create",
-                        "start",
-                        "stop",
-                        "delete"
-                    ],
+                "user": {
+                    "description": "User is the user that authenticated with the provider.",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                            "$ref": "#/definitions/codersdk.ExternalAuthUser"
                         }
                     ]
                 }
             }
         },
-        "codersdk.CreateWorkspaceProxyRequest": {
+        "codersdk.ExternalAuthAppInstallation": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "display_name": {
-                    "type": "string"
+                "account": {
+                    "$ref": "#/definitions/codersdk.ExternalAuthUser"
                 },
-                "icon": {
+                "configure_url": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.CreateWorkspaceRequest": {
+        "codersdk.ExternalAuthConfig": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "automatic_updates": {
-                    "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                "app_install_url": {
+                    "type": "string"
                 },
-                "autostart_schedule": {
+                "app_installations_url": {
                     "type": "string"
                 },
-                "name": {
+                "auth_url": {
                     "type": "string"
                 },
-                "rich_parameter_values": {
-                    "description": "RichParameterValues allows for additional parameters to be provided\nduring the initial provision.",
+                "client_id": {
+                    "type": "string"
+                },
+                "device_code_url": {
+                    "type": "string"
+                },
+                "device_flow": {
+                    "type": "boolean"
+                },
+                "display_icon": {
+                    "description": "DisplayIcon is a URL to an icon to display in the UI.",
+                    "type": "string"
+                },
+                "display_name": {
+                    "description": "DisplayName is shown in the UI to identify the auth config.",
+                    "type": "string"
+                },
+                "extra_token_keys": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                        "type": "string"
                     }
                 },
-                "template_id": {
-                    "description": "TemplateID specifies which template should be used for creating the workspace.",
-                    "type": "string",
-                    "format": "uuid"
+                "id": {
+                    "description": "ID is a unique identifier for the auth config.\nIt defaults to ` + "`" + `type` + "`" + ` when not provided.",
+                    "type": "string"
                 },
-                "template_version_id": {
-                    "description": "TemplateVersionID can be used to specify a specific version of a template for creating the workspace.",
-                    "type": "string",
-                    "format": "uuid"
+                "no_refresh": {
+                    "type": "boolean"
                 },
-                "ttl_ms": {
-                    "type": "integer"
-                }
-            }
-        },
-        "codersdk.DAUEntry": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "integer"
+                "regex": {
+                    "description": "Regex allows API requesters to match an auth config by\na string (e.g. coder.com) instead of by it's type.\n\nGit clone makes use of this by parsing the URL from:\n'Username for \"https://github.com\":'\nAnd sending it to the Coder server to match against the Regex.",
+                    "type": "string"
                 },
-                "date": {
-                    "type": "string",
-                    "format": "date-time"
-                }
-            }
-        },
-        "codersdk.DAUsResponse": {
-            "type": "object",
-            "properties": {
-                "entries": {
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.DAUEntry"
+                        "type": "string"
                     }
                 },
-                "tz_hour_offset": {
-                    "type": "integer"
+                "token_url": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is the type of external auth config.",
+                    "type": "string"
+                },
+                "validate_url": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.DERP": {
+        "codersdk.ExternalAuthDevice": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/codersdk.DERPConfig"
+                "device_code": {
+                    "type": "string"
                 },
-                "server": {
-                    "$ref": "#/definitions/codersdk.DERPServerConfig"
+                "expires_in": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "user_code": {
+                    "type": "string"
+                },
+                "verification_uri": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.DERPConfig": {
+        "codersdk.ExternalAuthLink": {
             "type": "object",
             "properties": {
-                "block_direct": {
+                "authenticated": {
                     "type": "boolean"
                 },
-                "force_websockets": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "expires": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "has_refresh_token": {
                     "type": "boolean"
                 },
-                "path": {
+                "provider_id": {
                     "type": "string"
                 },
-                "url": {
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "validate_error": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.DERPRegion": {
+        "codersdk.ExternalAuthUser": {
             "type": "object",
             "properties": {
-                "latency_ms": {
-                    "type": "number"
+                "avatar_url": {
+                    "type": "string"
                 },
-                "preferred": {
-                    "type": "boolean"
+                "login": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profile_url": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.DERPServerConfig": {
+        "codersdk.Feature": {
             "type": "object",
             "properties": {
-                "enable": {
+                "actual": {
+                    "type": "integer"
+                },
+                "enabled": {
                     "type": "boolean"
                 },
-                "region_code": {
-                    "type": "string"
+                "entitlement": {
+                    "$ref": "#/definitions/codersdk.Entitlement"
                 },
-                "region_id": {
+                "limit": {
                     "type": "integer"
-                },
-                "region_name": {
+                }
+            }
+        },
+        "codersdk.GenerateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
                     "type": "string"
-                },
-                "relay_url": {
-                    "$ref": "#/definitions/clibase.URL"
-                },
-                "stun_addresses": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 }
             }
         },
-        "codersdk.DangerousConfig": {
+        "codersdk.GenerateWorkspaceNameResponse": {
             "type": "object",
             "properties": {
-                "allow_all_cors": {
-                    "type": "boolean"
-                },
-                "allow_path_app_sharing": {
-                    "type": "boolean"
-                },
-                "allow_path_app_site_owner_access": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.DeploymentConfig": {
+        "codersdk.GetUsersResponse": {
             "type": "object",
             "properties": {
-                "config": {
-                    "$ref": "#/definitions/codersdk.DeploymentValues"
+                "count": {
+                    "type": "integer"
                 },
-                "options": {
+                "users": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/clibase.Option"
+                        "$ref": "#/definitions/codersdk.User"
                     }
                 }
             }
         },
-        "codersdk.DeploymentStats": {
+        "codersdk.GitSSHKey": {
             "type": "object",
             "properties": {
-                "aggregated_from": {
-                    "description": "AggregatedFrom is the time in which stats are aggregated from.\nThis might be back in time a specific duration or interval.",
+                "created_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "collected_at": {
-                    "description": "CollectedAt is the time in which stats are collected at.",
-                    "type": "string",
-                    "format": "date-time"
+                "public_key": {
+                    "type": "string"
                 },
-                "next_update_at": {
-                    "description": "NextUpdateAt is the time when the next batch of stats will\nbe updated.",
+                "updated_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "session_count": {
-                    "$ref": "#/definitions/codersdk.SessionCountDeploymentStats"
-                },
-                "workspaces": {
-                    "$ref": "#/definitions/codersdk.WorkspaceDeploymentStats"
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.DeploymentValues": {
+        "codersdk.Group": {
             "type": "object",
-            "properties": {
-                "access_url": {
-                    "$ref": "#/definitions/clibase.URL"
-                },
-                "address": {
-                    "description": "DEPRECATED: Use HTTPAddress or TLS.Address instead.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/clibase.HostPort"
-                        }
-                    ]
-                },
-                "agent_fallback_troubleshooting_url": {
-                    "$ref": "#/definitions/clibase.URL"
-                },
-                "agent_stat_refresh_interval": {
-                    "type": "integer"
-                },
-                "allow_workspace_renames": {
-                    "type": "boolean"
-                },
-                "autobuild_poll_interval": {
-                    "type": "integer"
-                },
-                "browser_only": {
-                    "type": "boolean"
-                },
-                "cache_directory": {
-                    "type": "string"
-                },
-                "config": {
-                    "type": "string"
-                },
-                "config_ssh": {
-                    "$ref": "#/definitions/codersdk.SSHConfig"
-                },
-                "dangerous": {
-                    "$ref": "#/definitions/codersdk.DangerousConfig"
-                },
-                "derp": {
-                    "$ref": "#/definitions/codersdk.DERP"
-                },
-                "disable_owner_workspace_exec": {
-                    "type": "boolean"
-                },
-                "disable_password_auth": {
-                    "type": "boolean"
-                },
-                "disable_path_apps": {
-                    "type": "boolean"
-                },
-                "disable_session_expiry_refresh": {
-                    "type": "boolean"
-                },
-                "docs_url": {
-                    "$ref": "#/definitions/clibase.URL"
-                },
-                "enable_terraform_debug_mode": {
-                    "type": "boolean"
-                },
-                "experiments": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "external_auth": {
-                    "$ref": "#/definitions/clibase.Struct-array_codersdk_ExternalAuthConfig"
-                },
-                "external_token_encryption_keys": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "healthcheck": {
-                    "$ref": "#/definitions/codersdk.HealthcheckConfig"
-                },
-                "http_address": {
-                    "description": "HTTPAddress is a string because it may be set to zero to disable.",
-                    "type": "string"
-                },
-                "in_memory_database": {
-                    "type": "boolean"
-                },
-                "job_hang_detector_interval": {
-                    "type": "integer"
-                },
-                "logging": {
-                    "$ref": "#/definitions/codersdk.LoggingConfig"
-                },
-                "max_session_expiry": {
-                    "type": "integer"
-                },
-                "max_token_lifetime": {
-                    "type": "integer"
-                },
-                "metrics_cache_refresh_interval": {
-                    "type": "integer"
-                },
-                "oauth2": {
-                    "$ref": "#/definitions/codersdk.OAuth2Config"
-                },
-                "oidc": {
-                    "$ref": "#/definitions/codersdk.OIDCConfig"
-                },
-                "pg_connection_url": {
-                    "type": "string"
-                },
-                "pprof": {
-                    "$ref": "#/definitions/codersdk.PprofConfig"
-                },
-                "prometheus": {
-                    "$ref": "#/definitions/codersdk.PrometheusConfig"
-                },
-                "provisioner": {
-                    "$ref": "#/definitions/codersdk.ProvisionerConfig"
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
                 },
-                "proxy_health_status_interval": {
-                    "type": "integer"
+                "display_name": {
+                    "type": "string"
                 },
-                "proxy_trusted_headers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "proxy_trusted_origins": {
+                "members": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.User"
                     }
                 },
-                "rate_limit": {
-                    "$ref": "#/definitions/codersdk.RateLimitConfig"
-                },
-                "redirect_to_access_url": {
-                    "type": "boolean"
-                },
-                "scim_api_key": {
+                "name": {
                     "type": "string"
                 },
-                "secure_auth_cookie": {
-                    "type": "boolean"
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "ssh_keygen_algorithm": {
+                "quota_allowance": {
+                    "type": "integer"
+                },
+                "source": {
+                    "$ref": "#/definitions/codersdk.GroupSource"
+                }
+            }
+        },
+        "codersdk.GroupSource": {
+            "type": "string",
+            "enum": [
+                "user",
+                "oidc",
+                "scim"
+            ],
+            "x-enum-varnames": [
+                "GroupSourceUser",
+                "GroupSourceOIDC",
+                "GroupSourceSCIM"
+            ]
+        },
+        "codersdk.GroupSyncRun": {
+            "type": "object",
+            "properties": {
+                "error": {
                     "type": "string"
                 },
-                "strict_transport_security": {
-                    "type": "integer"
+                "finished_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "strict_transport_security_options": {
+                "groups": {
+                    "description": "Groups are the Coder groups the user was synced to at login.",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "support": {
-                    "$ref": "#/definitions/codersdk.SupportConfig"
-                },
-                "swagger": {
-                    "$ref": "#/definitions/codersdk.SwaggerConfig"
-                },
-                "telemetry": {
-                    "$ref": "#/definitions/codersdk.TelemetryConfig"
-                },
-                "tls": {
-                    "$ref": "#/definitions/codersdk.TLSConfig"
-                },
-                "trace": {
-                    "$ref": "#/definitions/codersdk.TraceConfig"
-                },
-                "update_check": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "user_quiet_hours_schedule": {
-                    "$ref": "#/definitions/codersdk.UserQuietHoursScheduleConfig"
+                "idp_groups": {
+                    "description": "IDPGroups are the groups the identity provider returned at login,\nbefore nested groups were resolved.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "verbose": {
-                    "type": "boolean"
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "web_terminal_renderer": {
-                    "type": "string"
+                "trigger": {
+                    "enum": [
+                        "login",
+                        "background"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.GroupSyncTrigger"
+                        }
+                    ]
                 },
-                "wgtunnel_host": {
-                    "type": "string"
+                "user_id": {
+                    "description": "UserID is the user that was synced at login.",
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "wildcard_access_url": {
-                    "type": "string"
+                "users_failed": {
+                    "type": "integer"
                 },
-                "write_config": {
-                    "type": "boolean"
+                "users_synced": {
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.DisplayApp": {
+        "codersdk.GroupSyncTrigger": {
             "type": "string",
             "enum": [
-                "vscode",
-                "vscode_insiders",
-                "web_terminal",
-                "port_forwarding_helper",
-                "ssh_helper"
+                "login",
+                "background"
             ],
             "x-enum-varnames": [
-                "DisplayAppVSCodeDesktop",
-                "DisplayAppVSCodeInsiders",
-                "DisplayAppWebTerminal",
-                "DisplayAppPortForward",
-                "DisplayAppSSH"
+                "GroupSyncTriggerLogin",
+                "GroupSyncTriggerBackground"
             ]
         },
-        "codersdk.Entitlement": {
+        "codersdk.HealthSection": {
             "type": "string",
             "enum": [
-                "entitled",
-                "grace_period",
-                "not_entitled"
+                "DERP",
+                "AccessURL",
+                "Websocket",
+                "Database",
+                "Pubsub",
+                "WorkspaceProxy",
+                "ProvisionerDaemons"
             ],
             "x-enum-varnames": [
-                "EntitlementEntitled",
-                "EntitlementGracePeriod",
-                "EntitlementNotEntitled"
+                "HealthSectionDERP",
+                "HealthSectionAccessURL",
+                "HealthSectionWebsocket",
+                "HealthSectionDatabase",
+                "HealthSectionPubsub",
+                "HealthSectionWorkspaceProxy",
+                "HealthSectionProvisionerDaemons"
             ]
         },
-        "codersdk.Entitlements": {
+        "codersdk.HealthSettings": {
             "type": "object",
             "properties": {
-                "errors": {
+                "dismissed_healthchecks": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.HealthSection"
                     }
+                }
+            }
+        },
+        "codersdk.Healthcheck": {
+            "type": "object",
+            "properties": {
+                "interval": {
+                    "description": "Interval specifies the seconds between each health check.",
+                    "type": "integer"
                 },
-                "features": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/codersdk.Feature"
+                "threshold": {
+                    "description": "Threshold specifies the number of consecutive failed health checks before returning \"unhealthy\".",
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "URL specifies the endpoint to check for the app health.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.HealthcheckConfig": {
+            "type": "object",
+            "properties": {
+                "refresh": {
+                    "type": "integer"
+                },
+                "threshold_database": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.InactiveUser": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "email": {
+                    "type": "string",
+                    "format": "email"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_activity": {
+                    "description": "LastActivity is the most recent API activity of the user, if it is still\nretained. Activity is kept for 90 days.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UserAPIActivity"
+                        }
+                    ]
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "enum": [
+                        "active",
+                        "dormant"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.UserStatus"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.InactiveUsersResponse": {
+            "type": "object",
+            "properties": {
+                "inactive_since": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.InactiveUser"
                     }
+                }
+            }
+        },
+        "codersdk.InboxNotification": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
                 },
-                "has_license": {
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "refreshed_at": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "read_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "require_telemetry": {
-                    "type": "boolean"
+                "template": {
+                    "$ref": "#/definitions/codersdk.NotificationTemplate"
                 },
-                "trial": {
-                    "type": "boolean"
+                "title": {
+                    "type": "string"
                 },
-                "warnings": {
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.InboxNotificationsResponse": {
+            "type": "object",
+            "properties": {
+                "notifications": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.InboxNotification"
                     }
+                },
+                "unread_count": {
+                    "description": "UnreadCount is the number of unread notifications in the inbox, which\nmay exceed the number of notifications returned.",
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.Experiment": {
+        "codersdk.InsightsReportInterval": {
             "type": "string",
             "enum": [
-                "example"
+                "day",
+                "week"
             ],
-            "x-enum-comments": {
-                "ExperimentExample": "This isn't used for anything."
-            },
             "x-enum-varnames": [
-                "ExperimentExample"
+                "InsightsReportIntervalDay",
+                "InsightsReportIntervalWeek"
             ]
         },
-        "codersdk.ExternalAuth": {
+        "codersdk.IssueReconnectingPTYSignedTokenRequest": {
             "type": "object",
+            "required": [
+                "agentID",
+                "url"
+            ],
             "properties": {
-                "app_install_url": {
-                    "description": "AppInstallURL is the URL to install the app.",
-                    "type": "string"
-                },
-                "app_installable": {
-                    "description": "AppInstallable is true if the request for app installs was successful.",
-                    "type": "boolean"
-                },
-                "authenticated": {
-                    "type": "boolean"
-                },
-                "device": {
-                    "type": "boolean"
+                "agentID": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "display_name": {
+                "url": {
+                    "description": "URL is the URL of the reconnecting-pty endpoint you are connecting to.",
                     "type": "string"
-                },
-                "installations": {
-                    "description": "AppInstallations are the installations that the user has access to.",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.ExternalAuthAppInstallation"
-                    }
-                },
-                "user": {
-                    "description": "User is the user that authenticated with the provider.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/codersdk.ExternalAuthUser"
-                        }
-                    ]
                 }
             }
         },
-        "codersdk.ExternalAuthAppInstallation": {
+        "codersdk.IssueReconnectingPTYSignedTokenResponse": {
             "type": "object",
             "properties": {
-                "account": {
-                    "$ref": "#/definitions/codersdk.ExternalAuthUser"
-                },
-                "configure_url": {
+                "signed_token": {
                     "type": "string"
-                },
-                "id": {
-                    "type": "integer"
                 }
             }
         },
-        "codersdk.ExternalAuthConfig": {
+        "codersdk.JobErrorCode": {
+            "type": "string",
+            "enum": [
+                "REQUIRED_TEMPLATE_VARIABLES"
+            ],
+            "x-enum-varnames": [
+                "RequiredTemplateVariables"
+            ]
+        },
+        "codersdk.LDAPConfig": {
             "type": "object",
             "properties": {
-                "app_install_url": {
-                    "type": "string"
+                "allow_signups": {
+                    "type": "boolean"
                 },
-                "app_installations_url": {
+                "bind_dn": {
                     "type": "string"
                 },
-                "auth_url": {
+                "bind_password": {
                     "type": "string"
                 },
-                "client_id": {
+                "ca_file": {
                     "type": "string"
                 },
-                "device_code_url": {
+                "email_attribute": {
                     "type": "string"
                 },
-                "device_flow": {
+                "group_auto_create": {
                     "type": "boolean"
                 },
-                "display_icon": {
-                    "description": "DisplayIcon is a URL to an icon to display in the UI.",
-                    "type": "string"
-                },
-                "display_name": {
-                    "description": "DisplayName is shown in the UI to identify the auth config.",
+                "groups_attribute": {
                     "type": "string"
                 },
-                "extra_token_keys": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "id": {
-                    "description": "ID is a unique identifier for the auth config.\nIt defaults to ` + "`" + `type` + "`" + ` when not provided.",
-                    "type": "string"
+                "pool_size": {
+                    "type": "integer"
                 },
-                "no_refresh": {
+                "start_tls": {
                     "type": "boolean"
                 },
-                "regex": {
-                    "description": "Regex allows API requesters to match an auth config by\na string (e.g. coder.com) instead of by it's type.\n\nGit clone makes use of this by parsing the URL from:\n'Username for \"https://github.com\":'\nAnd sending it to the Coder server to match against the Regex.",
-                    "type": "string"
+                "sync_interval": {
+                    "type": "integer"
                 },
-                "scopes": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "url": {
+                    "type": "string"
                 },
-                "token_url": {
+                "user_filter": {
                     "type": "string"
                 },
-                "type": {
-                    "description": "Type is the type of external auth config.",
+                "user_search_base": {
                     "type": "string"
                 },
-                "validate_url": {
+                "username_attribute": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.ExternalAuthDevice": {
+        "codersdk.License": {
             "type": "object",
             "properties": {
-                "device_code": {
-                    "type": "string"
+                "claims": {
+                    "description": "Claims are the JWT claims asserted by the license.  Here we use\na generic string map to ensure that all data from the server is\nparsed verbatim, not just the fields this version of Coder\nunderstands.",
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "expires_in": {
+                "id": {
                     "type": "integer"
                 },
-                "interval": {
-                    "type": "integer"
+                "uploaded_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "user_code": {
+                "uuid": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.LinkConfig": {
+            "type": "object",
+            "properties": {
+                "icon": {
+                    "type": "string",
+                    "enum": [
+                        "bug",
+                        "chat",
+                        "docs"
+                    ]
+                },
+                "name": {
                     "type": "string"
                 },
-                "verification_uri": {
+                "target": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.ExternalAuthLink": {
+        "codersdk.LogLevel": {
+            "type": "string",
+            "enum": [
+                "trace",
+                "debug",
+                "info",
+                "warn",
+                "error"
+            ],
+            "x-enum-varnames": [
+                "LogLevelTrace",
+                "LogLevelDebug",
+                "LogLevelInfo",
+                "LogLevelWarn",
+                "LogLevelError"
+            ]
+        },
+        "codersdk.LogSource": {
+            "type": "string",
+            "enum": [
+                "provisioner_daemon",
+                "provisioner"
+            ],
+            "x-enum-varnames": [
+                "LogSourceProvisionerDaemon",
+                "LogSourceProvisioner"
+            ]
+        },
+        "codersdk.LoggingConfig": {
             "type": "object",
             "properties": {
-                "authenticated": {
-                    "type": "boolean"
+                "human": {
+                    "type": "string"
                 },
-                "created_at": {
-                    "type": "string",
-                    "format": "date-time"
+                "json": {
+                    "type": "string"
                 },
-                "expires": {
-                    "type": "string",
-                    "format": "date-time"
+                "log_filter": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "has_refresh_token": {
-                    "type": "boolean"
+                "stackdriver": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.LoginThrottleConfig": {
+            "type": "object",
+            "properties": {
+                "backoff_max": {
+                    "type": "integer"
                 },
-                "provider_id": {
+                "backoff_threshold": {
+                    "type": "integer"
+                },
+                "lockout_duration": {
+                    "type": "integer"
+                },
+                "lockout_threshold": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.LoginType": {
+            "type": "string",
+            "enum": [
+                "",
+                "password",
+                "github",
+                "oidc",
+                "token",
+                "ldap",
+                "none"
+            ],
+            "x-enum-varnames": [
+                "LoginTypeUnknown",
+                "LoginTypePassword",
+                "LoginTypeGithub",
+                "LoginTypeOIDC",
+                "LoginTypeToken",
+                "LoginTypeLDAP",
+                "LoginTypeNone"
+            ]
+        },
+        "codersdk.LoginWithLDAPRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
                     "type": "string"
                 },
-                "updated_at": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.LoginWithPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
                     "type": "string",
-                    "format": "date-time"
+                    "format": "email"
                 },
-                "validate_error": {
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.ExternalAuthUser": {
+        "codersdk.LoginWithPasswordResponse": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
-                },
-                "login": {
-                    "type": "string"
+                "passkey_enrollment": {
+                    "description": "PasskeyEnrollment is set instead of the session token when a policy\nrequires the user to have a second factor, but they have no passkey.\nThe login is finished by registering one with LoginWithPasskeyEnrollment.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.PasskeyRegistration"
+                        }
+                    ]
                 },
-                "name": {
-                    "type": "string"
+                "second_factor": {
+                    "description": "SecondFactor is set instead of the session token when the user has a\npasskey. The login is finished with LoginWithSecondFactor.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.SecondFactorChallenge"
+                        }
+                    ]
                 },
-                "profile_url": {
+                "session_token": {
+                    "description": "SessionToken is empty when a second factor is required.",
                     "type": "string"
                 }
             }
         },
-        "codersdk.Feature": {
+        "codersdk.LoginWithSecondFactorRequest": {
             "type": "object",
+            "required": [
+                "challenge_id"
+            ],
             "properties": {
-                "actual": {
-                    "type": "integer"
-                },
-                "enabled": {
-                    "type": "boolean"
+                "challenge_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "entitlement": {
-                    "$ref": "#/definitions/codersdk.Entitlement"
+                "passkey": {
+                    "$ref": "#/definitions/codersdk.PasskeyAssertion"
                 },
-                "limit": {
-                    "type": "integer"
+                "recovery_code": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.GenerateAPIKeyResponse": {
+        "codersdk.MarkInboxNotificationsReadRequest": {
             "type": "object",
             "properties": {
-                "key": {
-                    "type": "string"
+                "ids": {
+                    "description": "IDs are the notifications to mark as read. All notifications are\nmarked as read if it is empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
                 }
             }
         },
-        "codersdk.GetUsersResponse": {
+        "codersdk.MetricsExportConfig": {
             "type": "object",
             "properties": {
-                "count": {
+                "batch_size": {
                     "type": "integer"
                 },
-                "users": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.User"
-                    }
+                "interval": {
+                    "type": "integer"
+                },
+                "otlp_endpoint": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "statsd_address": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.GitSSHKey": {
+        "codersdk.MinimalUser": {
             "type": "object",
+            "required": [
+                "id",
+                "username"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "format": "date-time"
-                },
-                "public_key": {
-                    "type": "string"
-                },
-                "updated_at": {
+                "avatar_url": {
                     "type": "string",
-                    "format": "date-time"
+                    "format": "uri"
                 },
-                "user_id": {
+                "id": {
                     "type": "string",
                     "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.Group": {
+        "codersdk.NotificationMessage": {
             "type": "object",
             "properties": {
-                "avatar_url": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
-                "display_name": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "members": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.User"
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
                 },
-                "name": {
+                "last_error": {
                     "type": "string"
                 },
-                "organization_id": {
+                "method": {
+                    "enum": [
+                        "smtp",
+                        "slack",
+                        "teams"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NotificationMethod"
+                        }
+                    ]
+                },
+                "sent_at": {
                     "type": "string",
-                    "format": "uuid"
+                    "format": "date-time"
                 },
-                "quota_allowance": {
-                    "type": "integer"
+                "status": {
+                    "enum": [
+                        "pending",
+                        "sent",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NotificationMessageStatus"
+                        }
+                    ]
                 },
-                "source": {
-                    "$ref": "#/definitions/codersdk.GroupSource"
+                "template": {
+                    "$ref": "#/definitions/codersdk.NotificationTemplate"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.GroupSource": {
+        "codersdk.NotificationMessageStatus": {
             "type": "string",
             "enum": [
-                "user",
-                "oidc"
+                "pending",
+                "sent",
+                "failed"
             ],
             "x-enum-varnames": [
-                "GroupSourceUser",
-                "GroupSourceOIDC"
+                "NotificationMessageStatusPending",
+                "NotificationMessageStatusSent",
+                "NotificationMessageStatusFailed"
             ]
         },
-        "codersdk.HealthSection": {
+        "codersdk.NotificationMethod": {
             "type": "string",
             "enum": [
-                "DERP",
-                "AccessURL",
-                "Websocket",
-                "Database",
-                "WorkspaceProxy",
-                "ProvisionerDaemons"
+                "smtp",
+                "slack",
+                "teams"
             ],
             "x-enum-varnames": [
-                "HealthSectionDERP",
-                "HealthSectionAccessURL",
-                "HealthSectionWebsocket",
-                "HealthSectionDatabase",
-                "HealthSectionWorkspaceProxy",
-                "HealthSectionProvisionerDaemons"
+                "NotificationMethodSMTP",
+                "NotificationMethodSlack",
+                "NotificationMethodTeams"
             ]
         },
-        "codersdk.HealthSettings": {
+        "codersdk.NotificationPreference": {
             "type": "object",
             "properties": {
-                "dismissed_healthchecks": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.HealthSection"
-                    }
+                "disabled": {
+                    "type": "boolean"
+                },
+                "template": {
+                    "$ref": "#/definitions/codersdk.NotificationTemplate"
                 }
             }
         },
-        "codersdk.Healthcheck": {
+        "codersdk.NotificationTemplate": {
+            "type": "string",
+            "enum": [
+                "workspace_deleting",
+                "workspace_build_failed",
+                "user_account_created"
+            ],
+            "x-enum-varnames": [
+                "NotificationTemplateWorkspaceDeleting",
+                "NotificationTemplateWorkspaceBuildFailed",
+                "NotificationTemplateUserAccountCreated"
+            ]
+        },
+        "codersdk.NotificationsConfig": {
             "type": "object",
             "properties": {
-                "interval": {
-                    "description": "Interval specifies the seconds between each health check.",
+                "email": {
+                    "$ref": "#/definitions/codersdk.NotificationsEmailConfig"
+                },
+                "max_send_attempts": {
                     "type": "integer"
                 },
-                "threshold": {
-                    "description": "Threshold specifies the number of consecutive failed health checks before returning \"unhealthy\".",
+                "retry_interval": {
                     "type": "integer"
                 },
-                "url": {
-                    "description": "URL specifies the endpoint to check for the app health.",
+                "slack": {
+                    "$ref": "#/definitions/codersdk.NotificationsWebhookConfig"
+                },
+                "teams": {
+                    "$ref": "#/definitions/codersdk.NotificationsWebhookConfig"
+                }
+            }
+        },
+        "codersdk.NotificationsEmailConfig": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "smarthost": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.HealthcheckConfig": {
+        "codersdk.NotificationsWebhookConfig": {
             "type": "object",
             "properties": {
-                "refresh": {
-                    "type": "integer"
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "threshold_database": {
-                    "type": "integer"
+                "webhook_url": {
+                    "type": "string"
                 }
             }
         },
-        "codersdk.InsightsReportInterval": {
-            "type": "string",
-            "enum": [
-                "day",
-                "week"
-            ],
-            "x-enum-varnames": [
-                "InsightsReportIntervalDay",
-                "InsightsReportIntervalWeek"
-            ]
+        "codersdk.OAuth2Config": {
+            "type": "object",
+            "properties": {
+                "github": {
+                    "$ref": "#/definitions/codersdk.OAuth2GithubConfig"
+                }
+            }
+        },
+        "codersdk.OAuth2GithubConfig": {
+            "type": "object",
+            "properties": {
+                "allow_everyone": {
+                    "type": "boolean"
+                },
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "allowed_orgs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "allowed_teams": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "enterprise_base_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.OAuth2ProviderApp": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
         },
-        "codersdk.IssueReconnectingPTYSignedTokenRequest": {
+        "codersdk.OAuth2ProviderAppSecret": {
             "type": "object",
-            "required": [
-                "agentID",
-                "url"
-            ],
             "properties": {
-                "agentID": {
+                "client_secret_truncated": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "url": {
-                    "description": "URL is the URL of the reconnecting-pty endpoint you are connecting to.",
+                "last_used_at": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.IssueReconnectingPTYSignedTokenResponse": {
+        "codersdk.OAuth2ProviderAppSecretFull": {
             "type": "object",
             "properties": {
-                "signed_token": {
+                "client_secret_full": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.JobErrorCode": {
-            "type": "string",
-            "enum": [
-                "REQUIRED_TEMPLATE_VARIABLES"
-            ],
-            "x-enum-varnames": [
-                "RequiredTemplateVariables"
-            ]
-        },
-        "codersdk.License": {
+        "codersdk.OAuthConversionResponse": {
             "type": "object",
             "properties": {
-                "claims": {
-                    "description": "Claims are the JWT claims asserted by the license.  Here we use\na generic string map to ensure that all data from the server is\nparsed verbatim, not just the fields this version of Coder\nunderstands.",
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "uploaded_at": {
+                "expires_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "uuid": {
+                "state_string": {
+                    "type": "string"
+                },
+                "to_type": {
+                    "$ref": "#/definitions/codersdk.LoginType"
+                },
+                "user_id": {
                     "type": "string",
                     "format": "uuid"
                 }
             }
         },
-        "codersdk.LinkConfig": {
+        "codersdk.OIDCAuthMethod": {
             "type": "object",
             "properties": {
-                "icon": {
-                    "type": "string",
-                    "enum": [
-                        "bug",
-                        "chat",
-                        "docs"
-                    ]
+                "enabled": {
+                    "type": "boolean"
                 },
-                "name": {
+                "iconUrl": {
                     "type": "string"
                 },
-                "target": {
+                "signInText": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.LogLevel": {
-            "type": "string",
-            "enum": [
-                "trace",
-                "debug",
-                "info",
-                "warn",
-                "error"
-            ],
-            "x-enum-varnames": [
-                "LogLevelTrace",
-                "LogLevelDebug",
-                "LogLevelInfo",
-                "LogLevelWarn",
-                "LogLevelError"
-            ]
-        },
-        "codersdk.LogSource": {
-            "type": "string",
-            "enum": [
-                "provisioner_daemon",
-                "provisioner"
-            ],
-            "x-enum-varnames": [
-                "LogSourceProvisionerDaemon",
-                "LogSourceProvisioner"
-            ]
-        },
-        "codersdk.LoggingConfig": {
+        "codersdk.OIDCConfig": {
             "type": "object",
             "properties": {
-                "human": {
+                "allow_signups": {
+                    "type": "boolean"
+                },
+                "auth_url_params": {
+                    "type": "object"
+                },
+                "client_cert_file": {
                     "type": "string"
                 },
-                "json": {
+                "client_id": {
                     "type": "string"
                 },
-                "log_filter": {
+                "client_key_file": {
+                    "description": "ClientKeyFile \u0026 ClientCertFile are used in place of ClientSecret for PKI auth.",
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "email_domain": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "stackdriver": {
+                "email_field": {
+                    "type": "string"
+                },
+                "group_allow_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group_auto_create": {
+                    "type": "boolean"
+                },
+                "group_mapping": {
+                    "type": "object"
+                },
+                "group_organization_role_mapping": {
+                    "type": "object"
+                },
+                "group_prefix_strip": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group_regex_filter": {
+                    "$ref": "#/definitions/clibase.Regexp"
+                },
+                "group_sync_interval": {
+                    "type": "integer"
+                },
+                "groups_field": {
+                    "type": "string"
+                },
+                "icon_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "ignore_email_verified": {
+                    "type": "boolean"
+                },
+                "ignore_user_info": {
+                    "type": "boolean"
+                },
+                "issuer_url": {
+                    "type": "string"
+                },
+                "nested_groups_graph_client_id": {
+                    "type": "string"
+                },
+                "nested_groups_graph_client_secret": {
+                    "type": "string"
+                },
+                "nested_groups_graph_tenant_id": {
+                    "type": "string"
+                },
+                "nested_groups_max_depth": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sign_in_text": {
+                    "type": "string"
+                },
+                "user_role_field": {
+                    "type": "string"
+                },
+                "user_role_mapping": {
+                    "type": "object"
+                },
+                "user_roles_default": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "username_field": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.LoginType": {
+        "codersdk.OffboardUserRequest": {
+            "type": "object",
+            "required": [
+                "workspace_action"
+            ],
+            "properties": {
+                "dry_run": {
+                    "description": "DryRun reports what would change without changing anything.",
+                    "type": "boolean"
+                },
+                "transfer_to": {
+                    "description": "TransferTo is the user that receives the workspaces. It is required\nwhen the workspace action is \"transfer\".",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_action": {
+                    "enum": [
+                        "transfer",
+                        "archive"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OffboardWorkspaceAction"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.OffboardUserResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "removed_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.OffboardedGroup"
+                    }
+                },
+                "revoked_api_key_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revoked_external_auth_providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/codersdk.User"
+                },
+                "workspace_action": {
+                    "enum": [
+                        "transfer",
+                        "archive"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.OffboardWorkspaceAction"
+                        }
+                    ]
+                },
+                "workspaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.OffboardedWorkspace"
+                    }
+                }
+            }
+        },
+        "codersdk.OffboardWorkspaceAction": {
             "type": "string",
-            "enum": [
-                "",
-                "password",
-                "github",
-                "oidc",
-                "token",
-                "none"
+            "enum": [
+                "transfer",
+                "archive"
             ],
             "x-enum-varnames": [
-                "LoginTypeUnknown",
-                "LoginTypePassword",
-                "LoginTypeGithub",
-                "LoginTypeOIDC",
-                "LoginTypeToken",
-                "LoginTypeNone"
+                "OffboardWorkspaceActionTransfer",
+                "OffboardWorkspaceActionArchive"
             ]
         },
-        "codersdk.LoginWithPasswordRequest": {
+        "codersdk.OffboardedGroup": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "id": {
                     "type": "string",
-                    "format": "email"
+                    "format": "uuid"
                 },
-                "password": {
+                "name": {
                     "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.LoginWithPasswordResponse": {
+        "codersdk.OffboardedWorkspace": {
             "type": "object",
-            "required": [
-                "session_token"
-            ],
             "properties": {
-                "session_token": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
                     "type": "string"
+                },
+                "new_owner_id": {
+                    "description": "NewOwnerID is set if the workspace was transferred.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.MinimalUser": {
+        "codersdk.Organization": {
             "type": "object",
             "required": [
+                "created_at",
                 "id",
-                "username"
+                "name",
+                "updated_at"
             ],
             "properties": {
-                "avatar_url": {
+                "created_at": {
                     "type": "string",
-                    "format": "uri"
+                    "format": "date-time"
                 },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "username": {
+                "name": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
-        "codersdk.OAuth2Config": {
+        "codersdk.OrganizationCostRollup": {
             "type": "object",
             "properties": {
-                "github": {
-                    "$ref": "#/definitions/codersdk.OAuth2GithubConfig"
+                "currency": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "total_cost": {
+                    "type": "integer"
+                },
+                "workspace_count": {
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.OAuth2GithubConfig": {
+        "codersdk.OrganizationCosts": {
             "type": "object",
             "properties": {
-                "allow_everyone": {
-                    "type": "boolean"
+                "end_date": {
+                    "type": "string",
+                    "format": "date"
                 },
-                "allow_signups": {
-                    "type": "boolean"
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "allowed_orgs": {
+                "rollups": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.OrganizationCostRollup"
                     }
                 },
-                "allowed_teams": {
+                "start_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "totals": {
+                    "description": "Totals is the cost of all workspaces over the whole date range, per\ncurrency.",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.CostTotal"
                     }
-                },
-                "client_id": {
-                    "type": "string"
-                },
-                "client_secret": {
-                    "type": "string"
-                },
-                "enterprise_base_url": {
-                    "type": "string"
                 }
             }
         },
-        "codersdk.OAuth2ProviderApp": {
+        "codersdk.OrganizationMember": {
             "type": "object",
             "properties": {
-                "callback_url": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "id": {
+                "organization_id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "name": {
-                    "type": "string"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Role"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.OAuth2ProviderAppSecret": {
+        "codersdk.OrganizationMemberWithName": {
             "type": "object",
             "properties": {
-                "client_secret_truncated": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
                 },
-                "id": {
+                "organization_id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "last_used_at": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Role"
+                    }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.OAuth2ProviderAppSecretFull": {
+        "codersdk.OrganizationQuota": {
             "type": "object",
             "properties": {
-                "client_secret_full": {
-                    "type": "string"
+                "max_daily_cost": {
+                    "description": "MaxDailyCost is the maximum total daily cost of the workspaces in the\norganization.",
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string",
-                    "format": "uuid"
+                "max_running_workspaces_per_user": {
+                    "description": "MaxRunningWorkspacesPerUser is the maximum number of workspaces each\nuser can run at the same time in the organization.",
+                    "type": "integer"
                 }
             }
         },
-        "codersdk.OAuthConversionResponse": {
+        "codersdk.Passkey": {
             "type": "object",
             "properties": {
-                "expires_at": {
+                "created_at": {
                     "type": "string",
                     "format": "date-time"
                 },
-                "state_string": {
-                    "type": "string"
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "to_type": {
-                    "$ref": "#/definitions/codersdk.LoginType"
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
                 },
                 "user_id": {
                     "type": "string",
@@ -9792,157 +15320,159 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.OIDCAuthMethod": {
+        "codersdk.PasskeyAssertion": {
             "type": "object",
+            "required": [
+                "authenticator_data",
+                "client_data_json",
+                "credential_id",
+                "signature"
+            ],
             "properties": {
-                "enabled": {
-                    "type": "boolean"
+                "authenticator_data": {
+                    "type": "string"
                 },
-                "iconUrl": {
+                "client_data_json": {
                     "type": "string"
                 },
-                "signInText": {
+                "credential_id": {
+                    "type": "string"
+                },
+                "signature": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.OIDCConfig": {
+        "codersdk.PasskeyAuthenticatorSelection": {
             "type": "object",
             "properties": {
-                "allow_signups": {
-                    "type": "boolean"
-                },
-                "auth_url_params": {
-                    "type": "object"
-                },
-                "client_cert_file": {
-                    "type": "string"
-                },
-                "client_id": {
+                "residentKey": {
                     "type": "string"
                 },
-                "client_key_file": {
-                    "description": "ClientKeyFile \u0026 ClientCertFile are used in place of ClientSecret for PKI auth.",
+                "userVerification": {
                     "type": "string"
-                },
-                "client_secret": {
+                }
+            }
+        },
+        "codersdk.PasskeyCreationOptions": {
+            "type": "object",
+            "properties": {
+                "attestation": {
                     "type": "string"
                 },
-                "email_domain": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/codersdk.PasskeyAuthenticatorSelection"
                 },
-                "email_field": {
+                "challenge": {
                     "type": "string"
                 },
-                "group_allow_list": {
+                "excludeCredentials": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.PasskeyCredentialDescriptor"
                     }
                 },
-                "group_auto_create": {
-                    "type": "boolean"
-                },
-                "group_mapping": {
-                    "type": "object"
-                },
-                "group_regex_filter": {
-                    "$ref": "#/definitions/clibase.Regexp"
-                },
-                "groups_field": {
-                    "type": "string"
-                },
-                "icon_url": {
-                    "$ref": "#/definitions/clibase.URL"
-                },
-                "ignore_email_verified": {
-                    "type": "boolean"
-                },
-                "ignore_user_info": {
-                    "type": "boolean"
-                },
-                "issuer_url": {
-                    "type": "string"
-                },
-                "scopes": {
+                "pubKeyCredParams": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.PasskeyCredentialParameter"
                     }
                 },
-                "sign_in_text": {
-                    "type": "string"
+                "rp": {
+                    "$ref": "#/definitions/codersdk.PasskeyRelyingParty"
                 },
-                "user_role_field": {
-                    "type": "string"
+                "timeout": {
+                    "type": "integer"
                 },
-                "user_role_mapping": {
-                    "type": "object"
+                "user": {
+                    "$ref": "#/definitions/codersdk.PasskeyUser"
+                }
+            }
+        },
+        "codersdk.PasskeyCredentialDescriptor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
                 },
-                "user_roles_default": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PasskeyCredentialParameter": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "Alg is a COSE algorithm identifier.",
+                    "type": "integer"
                 },
-                "username_field": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "codersdk.Organization": {
+        "codersdk.PasskeyRegistration": {
             "type": "object",
-            "required": [
-                "created_at",
-                "id",
-                "name",
-                "updated_at"
-            ],
             "properties": {
-                "created_at": {
+                "challenge_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "expires_at": {
                     "type": "string",
                     "format": "date-time"
                 },
+                "options": {
+                    "$ref": "#/definitions/codersdk.PasskeyCreationOptions"
+                }
+            }
+        },
+        "codersdk.PasskeyRelyingParty": {
+            "type": "object",
+            "properties": {
                 "id": {
-                    "type": "string",
-                    "format": "uuid"
+                    "type": "string"
                 },
                 "name": {
                     "type": "string"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "format": "date-time"
                 }
             }
         },
-        "codersdk.OrganizationMember": {
+        "codersdk.PasskeyRequestOptions": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "format": "date-time"
-                },
-                "organization_id": {
-                    "type": "string",
-                    "format": "uuid"
-                },
-                "roles": {
+                "allowCredentials": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/codersdk.Role"
+                        "$ref": "#/definitions/codersdk.PasskeyCredentialDescriptor"
                     }
                 },
-                "updated_at": {
-                    "type": "string",
-                    "format": "date-time"
+                "challenge": {
+                    "type": "string"
                 },
-                "user_id": {
-                    "type": "string",
-                    "format": "uuid"
+                "rpId": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "userVerification": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PasskeyUser": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
@@ -10013,6 +15543,20 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.Permission": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "negate": {
+                    "type": "boolean"
+                },
+                "resource_type": {
+                    "$ref": "#/definitions/codersdk.RBACResource"
+                }
+            }
+        },
         "codersdk.PostOAuth2ProviderAppRequest": {
             "type": "object",
             "required": [
@@ -10077,8 +15621,20 @@ const docTemplate = `{
                 "daemons_echo": {
                     "type": "boolean"
                 },
+                "drift_detection_interval": {
+                    "type": "integer"
+                },
                 "force_cancel_interval": {
                     "type": "integer"
+                },
+                "job_log_archive_age": {
+                    "type": "integer"
+                },
+                "shared_cache": {
+                    "type": "boolean"
+                },
+                "shared_cache_s3_bucket": {
+                    "type": "string"
                 }
             }
         },
@@ -10103,6 +15659,11 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "organization_id": {
+                    "description": "OrganizationID is set if the daemon only acquires jobs from one organization.",
+                    "type": "string",
+                    "format": "uuid"
+                },
                 "provisioners": {
                     "type": "array",
                     "items": {
@@ -10156,6 +15717,9 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "priority": {
+                    "type": "integer"
+                },
                 "queue_position": {
                     "type": "integer"
                 },
@@ -10249,6 +15813,36 @@ const docTemplate = `{
                 "ProvisionerJobUnknown"
             ]
         },
+        "codersdk.ProvisionerKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "rotated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.ProvisionerLogLevel": {
             "type": "string",
             "enum": [
@@ -10301,6 +15895,17 @@ const docTemplate = `{
                 "ProxyUnregistered"
             ]
         },
+        "codersdk.PurgeProvisionerCacheResponse": {
+            "type": "object",
+            "properties": {
+                "deleted_bytes": {
+                    "type": "integer"
+                },
+                "deleted_objects": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.PutExtendWorkspaceRequest": {
             "type": "object",
             "required": [
@@ -10392,6 +15997,32 @@ const docTemplate = `{
                 },
                 "disable_all": {
                     "type": "boolean"
+                },
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -10504,7 +16135,11 @@ const docTemplate = `{
                 "convert_login",
                 "health_settings",
                 "workspace_proxy",
-                "organization"
+                "organization",
+                "passkey",
+                "two_factor_policy",
+                "webhook",
+                "organization_member"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -10519,7 +16154,11 @@ const docTemplate = `{
                 "ResourceTypeConvertLogin",
                 "ResourceTypeHealthSettings",
                 "ResourceTypeWorkspaceProxy",
-                "ResourceTypeOrganization"
+                "ResourceTypeOrganization",
+                "ResourceTypePasskey",
+                "ResourceTypeTwoFactorPolicy",
+                "ResourceTypeWebhook",
+                "ResourceTypeOrganizationMember"
             ]
         },
         "codersdk.Response": {
@@ -10583,6 +16222,22 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SecondFactorChallenge": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "options": {
+                    "$ref": "#/definitions/codersdk.PasskeyRequestOptions"
+                }
+            }
+        },
         "codersdk.ServiceBannerConfig": {
             "type": "object",
             "properties": {
@@ -10614,6 +16269,52 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SessionRecording": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ended_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt is when the recording is deleted, if the template has a\nretention period.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_id": {
+                    "description": "UserID is the user that opened the session.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.SupportConfig": {
             "type": "object",
             "properties": {
@@ -10726,6 +16427,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "build_retry_policy": {
+                    "description": "BuildRetryPolicy configures retrying workspace builds of the template\nthat failed transiently.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateBuildRetryPolicy"
+                        }
+                    ]
+                },
                 "build_time_stats": {
                     "$ref": "#/definitions/codersdk.TemplateBuildTimeStats"
                 },
@@ -10766,6 +16475,22 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "maintenance_window": {
+                    "description": "MaintenanceWindow is when running workspaces on an outdated template\nversion are stopped and rebuilt on the active version.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateMaintenanceWindow"
+                        }
+                    ]
+                },
+                "max_build_duration_ms": {
+                    "description": "MaxBuildDurationMillis is how long workspace builds may run before they\nare failed and aborted. Zero means builds never time out.",
+                    "type": "integer"
+                },
+                "max_running_workspaces": {
+                    "description": "MaxRunningWorkspaces is the maximum number of workspaces of the template\nthat can run at the same time. Zero means no limit.",
+                    "type": "integer"
+                },
                 "max_ttl_ms": {
                     "description": "TODO(@dean): remove max_ttl once autostop_requirement is matured",
                     "type": "integer"
@@ -10777,6 +16502,14 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "port_forwarding_policy": {
+                    "description": "PortForwardingPolicy restricts which agent ports of workspaces created\nfrom the template can be forwarded and shared.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplatePortForwardingPolicy"
+                        }
+                    ]
+                },
                 "provisioner": {
                     "type": "string",
                     "enum": [
@@ -10787,6 +16520,14 @@ const docTemplate = `{
                     "description": "RequireActiveVersion mandates that workspaces are built with the active\ntemplate version.",
                     "type": "boolean"
                 },
+                "session_recording": {
+                    "description": "SessionRecording configures recording the web terminal sessions of\nworkspaces created from the template.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateSessionRecording"
+                        }
+                    ]
+                },
                 "time_til_dormant_autodelete_ms": {
                     "type": "integer"
                 },
@@ -10800,18 +16541,52 @@ const docTemplate = `{
                 "use_max_ttl": {
                     "description": "UseMaxTTL picks whether to use the deprecated max TTL for the template or\nthe new autostop requirement.",
                     "type": "boolean"
+                },
+                "visibility": {
+                    "description": "Visibility restricts who can see the template, on top of its ACL.",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVisibility"
+                        }
+                    ]
+                },
+                "workspace_name_policy": {
+                    "description": "WorkspaceNamePolicy restricts the names of workspaces created from the\ntemplate.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateWorkspaceNamePolicy"
+                        }
+                    ]
                 }
             }
         },
         "codersdk.TemplateAppUsage": {
             "type": "object",
             "properties": {
+                "active_users": {
+                    "type": "integer",
+                    "example": 12
+                },
                 "display_name": {
                     "type": "string",
                     "example": "Visual Studio Code"
                 },
-                "icon": {
-                    "type": "string"
+                "icon": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "requests": {
+                    "description": "Requests, ActiveUsers and LastUsedAt are only reported for template\napps, they're recorded by the workspace app stats reporter.",
+                    "type": "integer",
+                    "example": 1200
                 },
                 "seconds": {
                     "type": "integer",
@@ -10895,6 +16670,45 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateBuildInsights": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "description": "BuildTime is the median and 95th percentile duration of the successful\nbuilds in milliseconds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TransitionStats"
+                        }
+                    ]
+                },
+                "failed_builds": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "success_rate": {
+                    "description": "SuccessRate is the share of builds that succeeded, between 0 and 1. It\nis 0 if there were no builds.",
+                    "type": "number",
+                    "example": 0.95
+                },
+                "total_builds": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "codersdk.TemplateBuildRetryPolicy": {
+            "type": "object",
+            "properties": {
+                "backoff_ms": {
+                    "description": "BackoffMillis is how long to wait before the first retry. The delay\ndoubles with every attempt.",
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "description": "MaxAttempts is the maximum number of attempts of a workspace build,\nincluding the first one. Values below 2 disable retries. Builds that\nwere canceled or failed because of the template are never retried.",
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.TemplateBuildTimeStats": {
             "type": "object",
             "additionalProperties": {
@@ -11013,6 +16827,19 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateMaintenanceWindow": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "description": "DurationMillis is how long the window stays open after each start.",
+                    "type": "integer"
+                },
+                "schedule": {
+                    "description": "Schedule is a weekly cron expression for the start of the window, with\na timezone specified via a CRON_TZ prefix (otherwise UTC will be used).\nIf empty, no maintenance happens.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TemplateParameterUsage": {
             "type": "object",
             "properties": {
@@ -11060,6 +16887,22 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplatePortForwardingPolicy": {
+            "type": "object",
+            "properties": {
+                "allowed_ports": {
+                    "description": "AllowedPorts are the ports that may still be forwarded and shared when\nDenyByDefault is set.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "deny_by_default": {
+                    "description": "DenyByDefault denies forwarding and sharing agent ports with\n` + "`" + `coder port-forward` + "`" + ` and port-based workspace app URLs. Attempts to\nforward a denied port are audited.",
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.TemplateRole": {
             "type": "string",
             "enum": [
@@ -11073,6 +16916,51 @@ const docTemplate = `{
                 "TemplateRoleDeleted"
             ]
         },
+        "codersdk.TemplateSessionRecording": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Enabled records the web terminal sessions of workspaces created from\nthe template. Sessions are refused if the deployment has no session\nrecording storage.",
+                    "type": "boolean"
+                },
+                "retention_ms": {
+                    "description": "RetentionMillis is how long recordings are kept for. Zero keeps them\nforever.",
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.TemplateSummaryInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "builds": {
+                    "$ref": "#/definitions/codersdk.TemplateBuildInsights"
+                },
+                "daily_active_users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateInsightsIntervalReport"
+                    }
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "parameters_usage": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateParameterUsage"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.TemplateUser": {
             "type": "object",
             "required": [
@@ -11151,6 +17039,25 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVariableValue": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "sensitive": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "value": {
+                    "description": "Value is redacted for sensitive values.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TemplateVersion": {
             "type": "object",
             "properties": {
@@ -11164,6 +17071,14 @@ const docTemplate = `{
                 "created_by": {
                     "$ref": "#/definitions/codersdk.MinimalUser"
                 },
+                "git": {
+                    "description": "Git is the git commit the version was created from, if it is known.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionGitMetadata"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
@@ -11203,6 +17118,50 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionDiff": {
+            "type": "object",
+            "properties": {
+                "added_parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                    }
+                },
+                "base_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "changed_parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionParameterChange"
+                    }
+                },
+                "files": {
+                    "description": "Files is null if the user is not allowed to read the source of both\ntemplate versions.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionFileDiff"
+                    }
+                },
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionMetadataChange"
+                    }
+                },
+                "removed_parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                    }
+                },
+                "target_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.TemplateVersionExternalAuth": {
             "type": "object",
             "properties": {
@@ -11226,6 +17185,78 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionFileDiff": {
+            "type": "object",
+            "properties": {
+                "binary": {
+                    "type": "boolean"
+                },
+                "diff": {
+                    "description": "Diff is the unified diff of the file. It is empty for binary files and\nfiles larger than 1 MiB.",
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "added",
+                        "removed",
+                        "modified"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionFileStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.TemplateVersionFileStatus": {
+            "type": "string",
+            "enum": [
+                "added",
+                "removed",
+                "modified"
+            ],
+            "x-enum-varnames": [
+                "TemplateVersionFileAdded",
+                "TemplateVersionFileRemoved",
+                "TemplateVersionFileModified"
+            ]
+        },
+        "codersdk.TemplateVersionGitMetadata": {
+            "type": "object",
+            "required": [
+                "commit_sha"
+            ],
+            "properties": {
+                "branch": {
+                    "description": "Branch is empty if the commit was pushed from a detached HEAD.",
+                    "type": "string"
+                },
+                "commit_sha": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TemplateVersionMetadataChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TemplateVersionParameter": {
             "type": "object",
             "properties": {
@@ -11238,6 +17269,10 @@ const docTemplate = `{
                 "description_plaintext": {
                     "type": "string"
                 },
+                "display_condition": {
+                    "description": "DisplayCondition is an expression over the values of other parameters,\ne.g. ` + "`" + `machine == \"gpu\"` + "`" + `. The parameter is only displayed and validated\nif the condition is met. See ParameterDisplayCondition.",
+                    "type": "string"
+                },
                 "display_name": {
                     "type": "string"
                 },
@@ -11296,6 +17331,20 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionParameterChange": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "new": {
+                    "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                },
+                "old": {
+                    "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                }
+            }
+        },
         "codersdk.TemplateVersionParameterOption": {
             "type": "object",
             "properties": {
@@ -11313,6 +17362,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionPreset": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                    }
+                }
+            }
+        },
         "codersdk.TemplateVersionVariable": {
             "type": "object",
             "properties": {
@@ -11336,7 +17403,8 @@ const docTemplate = `{
                     "enum": [
                         "string",
                         "number",
-                        "bool"
+                        "bool",
+                        "list(string)"
                     ]
                 },
                 "value": {
@@ -11353,11 +17421,44 @@ const docTemplate = `{
                 "TemplateVersionWarningUnsupportedWorkspaces"
             ]
         },
+        "codersdk.TemplateVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "unlisted",
+                "public"
+            ],
+            "x-enum-varnames": [
+                "TemplateVisibilityPrivate",
+                "TemplateVisibilityUnlisted",
+                "TemplateVisibilityPublic"
+            ]
+        },
+        "codersdk.TemplateWorkspaceNamePolicy": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description explains the naming convention to users whose workspace\nname doesn't match the pattern.",
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "Pattern is a regular expression that the names of new and renamed\nworkspaces must match. If empty, any valid name is allowed.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.TokenConfig": {
             "type": "object",
             "properties": {
                 "max_token_lifetime": {
                     "type": "integer"
+                },
+                "scopes": {
+                    "description": "Scopes are the fine-grained scopes that can be set on a token.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.APITokenScopeDescription"
+                    }
                 }
             }
         },
@@ -11385,9 +17486,34 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 123
                 },
-                "p95": {
-                    "type": "integer",
-                    "example": 146
+                "p95": {
+                    "type": "integer",
+                    "example": 146
+                }
+            }
+        },
+        "codersdk.TwoFactorPolicy": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "enforce_after": {
+                    "description": "EnforceAfter is when password logins without a second factor start\nbeing rejected, which gives users time to register a passkey.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
@@ -11445,6 +17571,28 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateNotificationPreferencesRequest": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NotificationPreference"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateProvisionerJobPriorityRequest": {
+            "type": "object",
+            "properties": {
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.UpdateRoles": {
             "type": "object",
             "properties": {
@@ -11494,6 +17642,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateUserIPAllowlistRequest": {
+            "type": "object",
+            "properties": {
+                "ip_allowlist": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateUserPasswordRequest": {
             "type": "object",
             "required": [
@@ -11534,6 +17693,21 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceACL": {
+            "type": "object",
+            "properties": {
+                "user_roles": {
+                    "description": "UserRoles maps a user ID or username to a role. An empty role stops\nsharing the workspace with the user.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.WorkspaceRole"
+                    },
+                    "example": {
+                        "4df59e74-c027-470b-ab4d-cbba8963a5e9": "use"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutomaticUpdatesRequest": {
             "type": "object",
             "properties": {
@@ -11558,6 +17732,22 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceDormancyExemptRequest": {
+            "type": "object",
+            "properties": {
+                "exempt": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.UpdateWorkspaceMaintenanceOptOutRequest": {
+            "type": "object",
+            "properties": {
+                "opt_out": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceRequest": {
             "type": "object",
             "properties": {
@@ -11583,6 +17773,56 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpsertTemplateVariableValueRequest": {
+            "type": "object",
+            "properties": {
+                "sensitive": {
+                    "description": "Sensitive values are encrypted at rest if database encryption is\nenabled, and are redacted by the API. Values of variables marked as\nsensitive in the template are always sensitive.",
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpsertTwoFactorPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "enforce_after": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.UpsertWorkspaceAgentPortShareRequest": {
+            "type": "object",
+            "required": [
+                "agent_name",
+                "port",
+                "share_level"
+            ],
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "share_level": {
+                    "enum": [
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShareLevel"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.User": {
             "type": "object",
             "required": [
@@ -11650,6 +17890,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserAPIActivity": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Category is the API the user made requests to, e.g. \"workspaces\".",
+                    "type": "string"
+                },
+                "hour": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "workspace_id": {
+                    "description": "WorkspaceID is set if the requests were about a workspace.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.UserActivity": {
             "type": "object",
             "properties": {
@@ -11711,6 +17969,18 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserIPAllowlist": {
+            "type": "object",
+            "properties": {
+                "ip_allowlist": {
+                    "description": "IPAllowlist holds CIDR ranges. If empty, the keys of the user can be used\nfrom any address.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.UserLatency": {
             "type": "object",
             "properties": {
@@ -11771,6 +18041,29 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserLockout": {
+            "type": "object",
+            "properties": {
+                "failed_attempts": {
+                    "type": "integer"
+                },
+                "locked_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "locked_until": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UserLoginType": {
             "type": "object",
             "properties": {
@@ -11805,70 +18098,201 @@ const docTemplate = `{
                     "description": "Time is the time of day that the quiet hours window starts in the given\nTimezone each day.",
                     "type": "string"
                 },
-                "timezone": {
-                    "description": "raw format from the cron expression, UTC if unspecified",
-                    "type": "string"
+                "timezone": {
+                    "description": "raw format from the cron expression, UTC if unspecified",
+                    "type": "string"
+                },
+                "user_can_set": {
+                    "description": "UserCanSet is true if the user is allowed to set their own quiet hours\nschedule. If false, the user cannot set a custom schedule and the default\nschedule will always be used.",
+                    "type": "boolean"
+                },
+                "user_set": {
+                    "description": "UserSet is true if the user has set their own quiet hours schedule. If\nfalse, the user is using the default schedule.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.UserStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "dormant",
+                "suspended"
+            ],
+            "x-enum-varnames": [
+                "UserStatusActive",
+                "UserStatusDormant",
+                "UserStatusSuspended"
+            ]
+        },
+        "codersdk.UserTwoFactor": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Enabled is true if the user has registered a passkey.",
+                    "type": "boolean"
+                },
+                "enforce_after": {
+                    "description": "EnforceAfter is when the earliest policy that applies to the user is\nenforced.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "recovery_codes_remaining": {
+                    "type": "integer"
+                },
+                "required": {
+                    "description": "Required is true if a policy requires the user to use a second factor.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.ValidationError": {
+            "type": "object",
+            "required": [
+                "detail",
+                "field"
+            ],
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ValidationMonotonicOrder": {
+            "type": "string",
+            "enum": [
+                "increasing",
+                "decreasing"
+            ],
+            "x-enum-varnames": [
+                "MonotonicOrderIncreasing",
+                "MonotonicOrderDecreasing"
+            ]
+        },
+        "codersdk.VariableValue": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WebhookEvent"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "secret": {
+                    "description": "Secret is used to sign deliveries. It is only returned when the\nwebhook is created.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "event": {
+                    "$ref": "#/definitions/codersdk.WebhookEvent"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WebhookDeliveryStatus"
+                        }
+                    ]
                 },
-                "user_can_set": {
-                    "description": "UserCanSet is true if the user is allowed to set their own quiet hours\nschedule. If false, the user cannot set a custom schedule and the default\nschedule will always be used.",
-                    "type": "boolean"
+                "status_code": {
+                    "description": "StatusCode is the HTTP status code of the last attempt. It is zero if\nthe endpoint could not be reached.",
+                    "type": "integer"
                 },
-                "user_set": {
-                    "description": "UserSet is true if the user has set their own quiet hours schedule. If\nfalse, the user is using the default schedule.",
-                    "type": "boolean"
+                "webhook_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
-        "codersdk.UserStatus": {
+        "codersdk.WebhookDeliveryStatus": {
             "type": "string",
             "enum": [
-                "active",
-                "dormant",
-                "suspended"
+                "pending",
+                "delivered",
+                "failed"
             ],
             "x-enum-varnames": [
-                "UserStatusActive",
-                "UserStatusDormant",
-                "UserStatusSuspended"
+                "WebhookDeliveryStatusPending",
+                "WebhookDeliveryStatusDelivered",
+                "WebhookDeliveryStatusFailed"
             ]
         },
-        "codersdk.ValidationError": {
-            "type": "object",
-            "required": [
-                "detail",
-                "field"
-            ],
-            "properties": {
-                "detail": {
-                    "type": "string"
-                },
-                "field": {
-                    "type": "string"
-                }
-            }
-        },
-        "codersdk.ValidationMonotonicOrder": {
+        "codersdk.WebhookEvent": {
             "type": "string",
             "enum": [
-                "increasing",
-                "decreasing"
+                "workspace_build.started",
+                "workspace_build.succeeded",
+                "workspace_build.failed",
+                "user.created",
+                "template.updated",
+                "workspace.maintenance",
+                "workspace.dormant"
             ],
             "x-enum-varnames": [
-                "MonotonicOrderIncreasing",
-                "MonotonicOrderDecreasing"
+                "WebhookEventWorkspaceBuildStarted",
+                "WebhookEventWorkspaceBuildSucceeded",
+                "WebhookEventWorkspaceBuildFailed",
+                "WebhookEventUserCreated",
+                "WebhookEventTemplateUpdated",
+                "WebhookEventWorkspaceMaintenance",
+                "WebhookEventWorkspaceDormant"
             ]
         },
-        "codersdk.VariableValue": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                },
-                "value": {
-                    "type": "string"
-                }
-            }
-        },
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
@@ -11898,11 +18322,23 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "dormancy_exempt": {
+                    "description": "DormancyExempt is true if the workspace never becomes dormant or is\ndeleted for inactivity.",
+                    "type": "boolean"
+                },
                 "dormant_at": {
                     "description": "DormantAt being non-nil indicates a workspace that is dormant.\nA dormant workspace is no longer accessible must be activated.\nIt is subject to deletion if it breaches\nthe duration of the time_til_ field on its template.",
                     "type": "string",
                     "format": "date-time"
                 },
+                "ephemeral": {
+                    "description": "Ephemeral workspaces are deleted, rather than stopped, once they\noutlive their TTL or the API token that created them expires.",
+                    "type": "boolean"
+                },
+                "favorite": {
+                    "description": "Favorite is true if the requesting user has pinned this workspace.",
+                    "type": "boolean"
+                },
                 "health": {
                     "description": "Health shows the health of the workspace and information about\nwhat is causing an unhealthy status.",
                     "allOf": [
@@ -11922,6 +18358,10 @@ const docTemplate = `{
                 "latest_build": {
                     "$ref": "#/definitions/codersdk.WorkspaceBuild"
                 },
+                "maintenance_opt_out": {
+                    "description": "MaintenanceOptOut is true if the template's maintenance window should\nnot stop and rebuild this workspace.",
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -11971,6 +18411,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceACL": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceUser"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceAgent": {
             "type": "object",
             "properties": {
@@ -12000,6 +18451,13 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "discovered_apps": {
+                    "description": "DiscoveredApps are the ports found listening in the workspace that are\nnot served by one of the agent's apps.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentDiscoveredApp"
+                    }
+                },
                 "display_apps": {
                     "type": "array",
                     "items": {
@@ -12019,6 +18477,13 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "gpus": {
+                    "description": "GPUs are the GPUs the agent detected in the workspace on startup.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentGPU"
+                    }
+                },
                 "health": {
                     "description": "Health reports the health of the agent.",
                     "allOf": [
@@ -12127,6 +18592,72 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentDiscoveredApp": {
+            "type": "object",
+            "properties": {
+                "discovered_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "process_name": {
+                    "type": "string"
+                },
+                "subdomain_name": {
+                    "description": "SubdomainName is the port forwarding domain of the port on the\n` + "`" + `coder server` + "`" + `.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentFile": {
+            "type": "object",
+            "properties": {
+                "is_dir": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "modified_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is the absolute path of the file.",
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentGPU": {
+            "type": "object",
+            "properties": {
+                "driver_version": {
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Index is the index of the GPU reported by the driver.",
+                    "type": "integer"
+                },
+                "memory_bytes": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "vendor": {
+                    "description": "Vendor is \"nvidia\" or \"amd\".",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentHealth": {
             "type": "object",
             "properties": {
@@ -12167,6 +18698,22 @@ const docTemplate = `{
                 "WorkspaceAgentLifecycleOff"
             ]
         },
+        "codersdk.WorkspaceAgentListFilesResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Files are sorted by name.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentFile"
+                    }
+                },
+                "path": {
+                    "description": "Path is the absolute path of the directory.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspaceAgentListeningPort": {
             "type": "object",
             "properties": {
@@ -12208,54 +18755,169 @@ const docTemplate = `{
                 "level": {
                     "$ref": "#/definitions/codersdk.LogLevel"
                 },
-                "output": {
+                "output": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentLogSource": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentMetadataDescription": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "script": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentMetadataHistory": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceAgentMetadataHistoryValue"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentMetadataHistoryValue": {
+            "type": "object",
+            "properties": {
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentNetcheckResponse": {
+            "type": "object",
+            "properties": {
+                "netcheck": {
+                    "$ref": "#/definitions/netcheck.Report"
+                },
+                "netcheck_err": {
+                    "type": "string"
+                },
+                "netcheck_logs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentPortShare": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "share_level": {
+                    "enum": [
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentPortShareLevel"
+                        }
+                    ]
+                },
+                "subdomain_name": {
+                    "description": "SubdomainName is the name of the subdomain the port is shared on. Append\nthe app hostname of the deployment to get the URL of the port.",
                     "type": "string"
                 },
-                "source_id": {
+                "workspace_id": {
                     "type": "string",
                     "format": "uuid"
                 }
             }
         },
-        "codersdk.WorkspaceAgentLogSource": {
+        "codersdk.WorkspaceAgentPortShareLevel": {
+            "type": "string",
+            "enum": [
+                "authenticated",
+                "public"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentPortShareLevelAuthenticated",
+                "WorkspaceAgentPortShareLevelPublic"
+            ]
+        },
+        "codersdk.WorkspaceAgentResourceUsage": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "collected_at": {
+                    "description": "CollectedAt is empty if the agent has not reported stats yet.",
                     "type": "string",
                     "format": "date-time"
                 },
-                "display_name": {
-                    "type": "string"
-                },
-                "icon": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string",
-                    "format": "uuid"
+                "cpu_total_cores": {
+                    "type": "number"
                 },
-                "workspace_agent_id": {
-                    "type": "string",
-                    "format": "uuid"
-                }
-            }
-        },
-        "codersdk.WorkspaceAgentMetadataDescription": {
-            "type": "object",
-            "properties": {
-                "display_name": {
-                    "type": "string"
+                "cpu_used_cores": {
+                    "type": "number"
                 },
-                "interval": {
+                "disk_total_bytes": {
                     "type": "integer"
                 },
-                "key": {
-                    "type": "string"
+                "disk_used_bytes": {
+                    "type": "integer"
                 },
-                "script": {
-                    "type": "string"
+                "memory_total_bytes": {
+                    "type": "integer"
                 },
-                "timeout": {
+                "memory_used_bytes": {
                     "type": "integer"
                 }
             }
@@ -12273,6 +18935,21 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "phase": {
+                    "description": "Phase orders the script within the start or stop lifecycle of the\nagent.",
+                    "enum": [
+                        "pre_start",
+                        "start",
+                        "post_start",
+                        "pre_stop",
+                        "stop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentScriptPhase"
+                        }
+                    ]
+                },
                 "run_on_start": {
                     "type": "boolean"
                 },
@@ -12290,6 +18967,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentScriptPhase": {
+            "type": "string",
+            "enum": [
+                "pre_start",
+                "start",
+                "post_start",
+                "pre_stop",
+                "stop"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentScriptPhasePreStart",
+                "WorkspaceAgentScriptPhaseStart",
+                "WorkspaceAgentScriptPhasePostStart",
+                "WorkspaceAgentScriptPhasePreStop",
+                "WorkspaceAgentScriptPhaseStop"
+            ]
+        },
         "codersdk.WorkspaceAgentStartupScriptBehavior": {
             "type": "string",
             "enum": [
@@ -12410,6 +19104,10 @@ const docTemplate = `{
         "codersdk.WorkspaceBuild": {
             "type": "object",
             "properties": {
+                "attempt": {
+                    "description": "Attempt counts the automatic retries of a failed build, starting at 1.",
+                    "type": "integer"
+                },
                 "build_number": {
                     "type": "integer"
                 },
@@ -12446,7 +19144,10 @@ const docTemplate = `{
                     "enum": [
                         "initiator",
                         "autostart",
-                        "autostop"
+                        "autostop",
+                        "template_update",
+                        "batch",
+                        "retry"
                     ],
                     "allOf": [
                         {
@@ -12460,6 +19161,11 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.WorkspaceResource"
                     }
                 },
+                "retry_at": {
+                    "description": "RetryAt is when the failed build is retried automatically. It is\nunset if the build did not fail, or failed for good.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "status": {
                     "enum": [
                         "pending",
@@ -12518,6 +19224,51 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceBuildDryRunResource": {
+            "type": "object",
+            "properties": {
+                "daily_cost": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceBuildDryRunResources": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildDryRunResource"
+                    }
+                },
+                "daily_cost": {
+                    "description": "DailyCost is the daily cost of all planned resources.",
+                    "type": "integer"
+                },
+                "daily_cost_change": {
+                    "description": "DailyCostChange is the difference to the daily cost of the latest build.",
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildDryRunResource"
+                    }
+                },
+                "unchanged": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildDryRunResource"
+                    }
+                }
+            }
+        },
         "codersdk.WorkspaceBuildParameter": {
             "type": "object",
             "properties": {
@@ -12540,6 +19291,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceCosts": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceResourceCost"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "totals": {
+                    "description": "Totals is the cost over the whole date range, per currency.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.CostTotal"
+                    }
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceDeploymentStats": {
             "type": "object",
             "properties": {
@@ -12569,6 +19350,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceDrift": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "BuildID is the build that was checked.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "checked_at": {
+                    "description": "CheckedAt is unset until the first drift check completes.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "drifted_resources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "job_id": {
+                    "description": "JobID is the dry-run job of the latest drift check. It may still be\nrunning, in which case CheckedAt refers to the previous check.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceHealth": {
             "type": "object",
             "properties": {
@@ -12587,6 +19398,39 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceParameterChange": {
+            "type": "object",
+            "properties": {
+                "build_number": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "initiator_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "initiator_username": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "description": "OldValue is unset if the parameter wasn't set by the previous build.",
+                    "type": "string"
+                },
+                "workspace_build_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceProxy": {
             "type": "object",
             "properties": {
@@ -12651,6 +19495,14 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "derp_reachable": {
+                    "description": "DERPReachable is true if DERP is enabled on the workspace proxy and\ncoderd can reach it.",
+                    "type": "boolean"
+                },
+                "latency_ms": {
+                    "description": "LatencyMS is the round trip time in milliseconds of the health check\nrequest from coderd to the workspace proxy.",
+                    "type": "integer"
+                },
                 "report": {
                     "description": "Report provides more information about the health of the workspace proxy.",
                     "allOf": [
@@ -12661,6 +19513,10 @@ const docTemplate = `{
                 },
                 "status": {
                     "$ref": "#/definitions/codersdk.ProxyHealthStatus"
+                },
+                "version_skew": {
+                    "description": "VersionSkew is true if the major or minor version of the workspace\nproxy differs from coderd.",
+                    "type": "boolean"
                 }
             }
         },
@@ -12701,47 +19557,131 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
-                "job_id": {
+                "job_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceResourceMetadata"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "workspace_transition": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.WorkspaceResourceCost": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "daily_cost": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string",
+                    "format": "date"
+                },
+                "resource_name": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceResourceMetadata": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "sensitive": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceRole": {
+            "type": "string",
+            "enum": [
+                "use",
+                ""
+            ],
+            "x-enum-varnames": [
+                "WorkspaceRoleUse",
+                "WorkspaceRoleDeleted"
+            ]
+        },
+        "codersdk.WorkspaceSnapshot": {
+            "type": "object",
+            "properties": {
+                "build_id": {
+                    "description": "BuildID is the workspace build that takes the snapshot.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "metadata": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/codersdk.WorkspaceResourceMetadata"
-                    }
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
                 "name": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string"
+                "resources": {
+                    "description": "Resources are the snapshot-able resources captured by the snapshot, as\n\"\u003ctype\u003e.\u003cname\u003e\". They are known once the snapshot has succeeded.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "workspace_transition": {
+                "status": {
+                    "description": "Status is the status of the build that takes the snapshot. Only\nsucceeded snapshots can be restored.",
                     "enum": [
-                        "start",
-                        "stop",
-                        "delete"
+                        "pending",
+                        "running",
+                        "succeeded",
+                        "canceling",
+                        "canceled",
+                        "failed"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                            "$ref": "#/definitions/codersdk.ProvisionerJobStatus"
                         }
                     ]
-                }
-            }
-        },
-        "codersdk.WorkspaceResourceMetadata": {
-            "type": "object",
-            "properties": {
-                "key": {
-                    "type": "string"
-                },
-                "sensitive": {
-                    "type": "boolean"
                 },
-                "value": {
-                    "type": "string"
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -12772,6 +19712,159 @@ const docTemplate = `{
                 "WorkspaceStatusDeleted"
             ]
         },
+        "codersdk.WorkspaceTimelineEvent": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "description": "AgentID is set for agent and app events.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "app_id": {
+                    "description": "AppID is set for app_health_changed events.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "app_slug": {
+                    "type": "string"
+                },
+                "build_id": {
+                    "description": "BuildID is set for build_started and build_completed events.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "build_number": {
+                    "type": "integer"
+                },
+                "build_reason": {
+                    "enum": [
+                        "initiator",
+                        "autostart",
+                        "autostop",
+                        "template_update",
+                        "batch",
+                        "retry"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.BuildReason"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "job_status": {
+                    "description": "JobStatus is the current status of the build, not its status at the\ntime of the event.",
+                    "enum": [
+                        "pending",
+                        "running",
+                        "succeeded",
+                        "canceling",
+                        "canceled",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobStatus"
+                        }
+                    ]
+                },
+                "new_health": {
+                    "enum": [
+                        "disabled",
+                        "initializing",
+                        "healthy",
+                        "unhealthy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppHealth"
+                        }
+                    ]
+                },
+                "new_ttl_ms": {
+                    "description": "NewTTLMillis is the TTL after a ttl_changed event. It is unset if\nautostop was disabled.",
+                    "type": "integer"
+                },
+                "old_health": {
+                    "enum": [
+                        "disabled",
+                        "initializing",
+                        "healthy",
+                        "unhealthy"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppHealth"
+                        }
+                    ]
+                },
+                "old_ttl_ms": {
+                    "description": "OldTTLMillis is the TTL before a ttl_changed event. It is unset if\nautostop was disabled.",
+                    "type": "integer"
+                },
+                "transition": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
+                },
+                "type": {
+                    "enum": [
+                        "build_started",
+                        "build_completed",
+                        "agent_connected",
+                        "agent_disconnected",
+                        "ttl_changed",
+                        "app_health_changed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTimelineEventType"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "description": "UserID is the user who caused the event. It is unset for events caused\nby the workspace itself, like agents connecting.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceTimelineEventType": {
+            "type": "string",
+            "enum": [
+                "build_started",
+                "build_completed",
+                "agent_connected",
+                "agent_disconnected",
+                "ttl_changed",
+                "app_health_changed"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceTimelineEventTypeBuildStarted",
+                "WorkspaceTimelineEventTypeBuildCompleted",
+                "WorkspaceTimelineEventTypeAgentConnected",
+                "WorkspaceTimelineEventTypeAgentDisconnected",
+                "WorkspaceTimelineEventTypeTTLChanged",
+                "WorkspaceTimelineEventTypeAppHealthChanged"
+            ]
+        },
         "codersdk.WorkspaceTransition": {
             "type": "string",
             "enum": [
@@ -12785,6 +19878,36 @@ const docTemplate = `{
                 "WorkspaceTransitionDelete"
             ]
         },
+        "codersdk.WorkspaceUser": {
+            "type": "object",
+            "required": [
+                "id",
+                "username"
+            ],
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "role": {
+                    "enum": [
+                        "use"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceRole"
+                        }
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspacesResponse": {
             "type": "object",
             "properties": {
@@ -12812,6 +19935,38 @@ const docTemplate = `{
                 }
             }
         },
+        "derphealth.FailingNode": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "excluded": {
+                    "description": "Excluded is true if the node is excluded from the DERP map until it\nrecovers.",
+                    "type": "boolean"
+                },
+                "failing_since": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "host_name": {
+                    "type": "string"
+                },
+                "node_name": {
+                    "type": "string"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Source is where the node was probed from: \"coderd\", or the name of the\nworkspace proxy that reported it.",
+                    "type": "string"
+                }
+            }
+        },
         "derphealth.NodeReport": {
             "type": "object",
             "properties": {
@@ -12929,6 +20084,13 @@ const docTemplate = `{
                 "error": {
                     "type": "string"
                 },
+                "failing_nodes": {
+                    "description": "FailingNodes are the nodes that failed the continuous health checks run\nby coderd and the workspace proxies.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/derphealth.FailingNode"
+                    }
+                },
                 "healthy": {
                     "description": "Healthy is deprecated and left for backward compatibility purposes, use ` + "`" + `Severity` + "`" + ` instead.",
                     "type": "boolean"
@@ -12995,6 +20157,10 @@ const docTemplate = `{
                 "EWP04",
                 "EDB01",
                 "EDB02",
+                "EPS01",
+                "EPS02",
+                "EPS03",
+                "EPS04",
                 "EWS01",
                 "EWS02",
                 "EWS03",
@@ -13004,6 +20170,7 @@ const docTemplate = `{
                 "EACS04",
                 "EDERP01",
                 "EDERP02",
+                "EDERP03",
                 "EPD01",
                 "EPD02",
                 "EPD03"
@@ -13016,6 +20183,10 @@ const docTemplate = `{
                 "CodeProxyUnhealthy",
                 "CodeDatabasePingFailed",
                 "CodeDatabasePingSlow",
+                "CodePubsubSubscribe",
+                "CodePubsubPublish",
+                "CodePubsubNotReceived",
+                "CodePubsubSlow",
                 "CodeWebsocketDial",
                 "CodeWebsocketEcho",
                 "CodeWebsocketMsg",
@@ -13025,6 +20196,7 @@ const docTemplate = `{
                 "CodeAccessURLNotOK",
                 "CodeDERPNodeUsesWebsocket",
                 "CodeDERPOneNodeUnhealthy",
+                "CodeDERPNodeExcluded",
                 "CodeProvisionerDaemonsNoProvisionerDaemons",
                 "CodeProvisionerDaemonVersionMismatch",
                 "CodeProvisionerDaemonAPIMajorVersionDeprecated"
@@ -13184,6 +20356,48 @@ const docTemplate = `{
                 }
             }
         },
+        "healthcheck.PubsubReport": {
+            "type": "object",
+            "properties": {
+                "dismissed": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "description": "Healthy is deprecated and left for backward compatibility purposes, use ` + "`" + `Severity` + "`" + ` instead.",
+                    "type": "boolean"
+                },
+                "latency": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "severity": {
+                    "enum": [
+                        "ok",
+                        "warning",
+                        "error"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Severity"
+                        }
+                    ]
+                },
+                "threshold_ms": {
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.Message"
+                    }
+                }
+            }
+        },
         "healthcheck.Report": {
             "type": "object",
             "properties": {
@@ -13214,6 +20428,9 @@ const docTemplate = `{
                 "provisioner_daemons": {
                     "$ref": "#/definitions/healthcheck.ProvisionerDaemonsReport"
                 },
+                "pubsub": {
+                    "$ref": "#/definitions/healthcheck.PubsubReport"
+                },
                 "severity": {
                     "description": "Severity indicates the status of Coder health.",
                     "enum": [
@@ -13666,6 +20883,13 @@ const docTemplate = `{
                     "description": "DerpEnabled indicates whether the proxy should be included in the DERP\nmap or not.",
                     "type": "boolean"
                 },
+                "derp_failing_nodes": {
+                    "description": "DERPFailingNodes are the DERP nodes that are failing the health checks\nrun by the proxy. They are surfaced in the deployment health report.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/derphealth.FailingNode"
+                    }
+                },
                 "derp_only": {
                     "description": "DerpOnly indicates whether the proxy should only be included in the DERP\nmap and should not be used for serving apps.",
                     "type": "boolean"
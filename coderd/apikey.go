@@ -3,8 +3,10 @@ package coderd
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -12,9 +14,11 @@ import (
 	"github.com/moby/moby/pkg/namesgenerator"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -56,12 +60,81 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	scope := database.APIKeyScopeAll
-	if scope != "" {
+	if createToken.Scope != "" {
 		scope = database.APIKeyScope(createToken.Scope)
 	}
 
-	// default lifetime is 30 days
+	scopes := make([]rbac.ScopeName, 0, len(createToken.Scopes))
+	for _, name := range createToken.Scopes {
+		if !rbac.IsFineGrainedScope(rbac.ScopeName(name)) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid token scope %q.", name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "scopes",
+					Detail: "Must be one of the scopes listed by the token config.",
+				}},
+			})
+			return
+		}
+		scopes = append(scopes, rbac.ScopeName(name))
+	}
+	if len(scopes) > 0 && scope != database.APIKeyScopeAll {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Fine-grained scopes cannot be combined with the %q scope.", scope),
+		})
+		return
+	}
+	if len(createToken.AllowedWorkspaceIDs) > 0 && len(scopes) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Allowed workspaces require at least one fine-grained scope.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "allowed_workspace_ids",
+				Detail: "Set at least one scope to limit the token to workspaces.",
+			}},
+		})
+		return
+	}
+	for _, id := range createToken.AllowedWorkspaceIDs {
+		_, err := api.Database.GetWorkspaceByID(ctx, id)
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Workspace %q does not exist.", id),
+				Validations: []codersdk.ValidationError{{
+					Field:  "allowed_workspace_ids",
+					Detail: "Must only contain workspaces you can access.",
+				}},
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	ipAllowlist, err := apikey.NormalizeIPAllowlist(createToken.IPAllowlist)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid IP allowlist.",
+			Detail:  err.Error(),
+			Validations: []codersdk.ValidationError{{
+				Field:  "ip_allowlist",
+				Detail: "Must only contain CIDR ranges or IP addresses.",
+			}},
+		})
+		return
+	}
+
+	// default lifetime is 30 days, or the maximum lifetime allowed for the
+	// user if it is shorter.
+	policy := api.LifetimePolicy(user.RBACRoles)
 	lifeTime := 30 * 24 * time.Hour
+	if lifeTime > policy.MaxTokenLifetime {
+		lifeTime = policy.MaxTokenLifetime
+	}
 	if createToken.Lifetime != 0 {
 		lifeTime = createToken.Lifetime
 	}
@@ -72,7 +145,7 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 		tokenName = createToken.TokenName
 	}
 
-	err := api.validateAPIKeyLifetime(lifeTime)
+	err = validateAPIKeyLifetime(lifeTime, policy)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Failed to validate create API key request.",
@@ -82,13 +155,16 @@ func (api *API) postToken(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	cookie, key, err := api.createAPIKey(ctx, apikey.CreateParams{
-		UserID:           user.ID,
-		LoginType:        database.LoginTypeToken,
-		DeploymentValues: api.DeploymentValues,
-		ExpiresAt:        dbtime.Now().Add(lifeTime),
-		Scope:            scope,
-		LifetimeSeconds:  int64(lifeTime.Seconds()),
-		TokenName:        tokenName,
+		UserID:              user.ID,
+		LoginType:           database.LoginTypeToken,
+		DeploymentValues:    api.DeploymentValues,
+		ExpiresAt:           dbtime.Now().Add(lifeTime),
+		Scope:               scope,
+		LifetimeSeconds:     int64(lifeTime.Seconds()),
+		TokenName:           tokenName,
+		Scopes:              scopes,
+		AllowedWorkspaceIDs: createToken.AllowedWorkspaceIDs,
+		IPAllowlist:         ipAllowlist,
 	})
 	if err != nil {
 		if database.IsUniqueViolation(err, database.UniqueIndexAPIKeyName) {
@@ -345,29 +421,43 @@ func (api *API) deleteAPIKey(rw http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} codersdk.TokenConfig
 // @Router /users/{user}/keys/tokens/tokenconfig [get]
 func (api *API) tokenConfig(rw http.ResponseWriter, r *http.Request) {
-	values, err := api.DeploymentValues.WithoutSecrets()
-	if err != nil {
-		httpapi.InternalServerError(rw, err)
-		return
-	}
+	user := httpmw.UserParam(r)
 
 	httpapi.Write(
 		r.Context(), rw, http.StatusOK,
 		codersdk.TokenConfig{
-			MaxTokenLifetime: values.MaxTokenLifetime.Value(),
+			MaxTokenLifetime: api.LifetimePolicy(user.RBACRoles).MaxTokenLifetime,
+			Scopes:           convertTokenScopes(rbac.FineGrainedScopes()),
 		},
 	)
 }
 
-func (api *API) validateAPIKeyLifetime(lifetime time.Duration) error {
+func convertTokenScopes(scopes []rbac.FineGrainedScope) []codersdk.APITokenScopeDescription {
+	converted := make([]codersdk.APITokenScopeDescription, 0, len(scopes))
+	for _, scope := range scopes {
+		converted = append(converted, codersdk.APITokenScopeDescription{
+			Name:        codersdk.APITokenScope(scope.Name),
+			DisplayName: scope.DisplayName,
+		})
+	}
+	return converted
+}
+
+// LifetimePolicy returns the lifetime policy of the API keys of a user with
+// the given site roles.
+func (api *API) LifetimePolicy(roles []string) apikey.LifetimePolicy {
+	return apikey.PolicyForRoles(api.DeploymentValues, roles)
+}
+
+func validateAPIKeyLifetime(lifetime time.Duration, policy apikey.LifetimePolicy) error {
 	if lifetime <= 0 {
 		return xerrors.New("lifetime must be positive number greater than 0")
 	}
 
-	if lifetime > api.DeploymentValues.MaxTokenLifetime.Value() {
+	if lifetime > policy.MaxTokenLifetime {
 		return xerrors.Errorf(
 			"lifetime must be less than %v",
-			api.DeploymentValues.MaxTokenLifetime,
+			policy.MaxTokenLifetime,
 		)
 	}
 
@@ -389,6 +479,11 @@ func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*
 		APIKeys: []telemetry.APIKey{telemetry.ConvertAPIKey(newkey)},
 	})
 
+	err = api.limitConcurrentSessions(ctx, newkey)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("limit concurrent sessions: %w", err)
+	}
+
 	return &http.Cookie{
 		Name:     codersdk.SessionTokenCookie,
 		Value:    sessionToken,
@@ -398,3 +493,215 @@ func (api *API) createAPIKey(ctx context.Context, params apikey.CreateParams) (*
 		Secure:   api.SecureAuthCookie,
 	}, &newkey, nil
 }
+
+// limitConcurrentSessions signs the user of a new session out of their oldest
+// sessions, so they have at most the maximum number of concurrent sessions.
+// Each evicted session is audited as a deleted API key.
+func (api *API) limitConcurrentSessions(ctx context.Context, session database.APIKey) error {
+	limit := api.DeploymentValues.MaxConcurrentSessions.Value()
+	if limit <= 0 || session.Scope != database.APIKeyScopeAll {
+		return nil
+	}
+	switch session.LoginType {
+	case database.LoginTypePassword, database.LoginTypeGithub, database.LoginTypeOIDC, database.LoginTypeLDAP:
+	default:
+		return nil
+	}
+
+	//nolint:gocritic // The system signs users out of their sessions.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	sessions, err := api.Database.GetActiveSessionsByUserID(ctx, database.GetActiveSessionsByUserIDParams{
+		UserID: session.UserID,
+		Now:    dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("get active sessions: %w", err)
+	}
+	// Sessions are ordered newest first, and the new session is always kept.
+	kept := int64(1)
+	for _, old := range sessions {
+		if old.ID == session.ID {
+			continue
+		}
+		if kept < limit {
+			kept++
+			continue
+		}
+		err = api.Database.DeleteAPIKeyByID(ctx, old.ID)
+		if httpapi.Is404Error(err) {
+			// Another request already signed the user out of it.
+			continue
+		}
+		if err != nil {
+			return xerrors.Errorf("delete session %s: %w", old.ID, err)
+		}
+		api.Logger.Info(ctx, "signed user out of session over the concurrent session limit",
+			slog.F("user_id", session.UserID),
+			slog.F("api_key_id", old.ID),
+			slog.F("limit", limit),
+		)
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.APIKey]{
+			Audit:  *api.Auditor.Load(),
+			Log:    api.Logger,
+			UserID: session.UserID,
+			Status: http.StatusOK,
+			Action: database.AuditActionDelete,
+			IP:     session.IPAddress.IPNet.IP.String(),
+			Old:    old,
+			New:    database.APIKey{},
+		})
+	}
+	return nil
+}
+
+// @Summary Get active sessions of user
+// @ID get-active-sessions-of-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.APIKey
+// @Router /users/{user}/sessions [get]
+func (api *API) userSessions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	sessions, err := api.Database.GetActiveSessionsByUserID(ctx, database.GetActiveSessionsByUserIDParams{
+		UserID: user.ID,
+		Now:    dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching sessions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.APIKey, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, convertAPIKey(session))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// Signs the user out of all of their sessions and deletes all of their API
+// keys, including tokens.
+//
+// @Summary Delete all sessions and API keys of user
+// @ID delete-all-sessions-and-api-keys-of-user
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/sessions [delete]
+func (api *API) deleteUserSessions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	deleted, err := api.Database.DeleteAPIKeysByUserIDReturning(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting API keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Every key is its own audited resource, so each one is audited as
+	// deleted.
+	for _, key := range deleted {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.APIKey]{
+			Audit:     *api.Auditor.Load(),
+			Log:       api.Logger,
+			UserID:    httpmw.APIKey(r).UserID,
+			RequestID: httpmw.RequestID(r),
+			Status:    http.StatusNoContent,
+			Action:    database.AuditActionDelete,
+			IP:        r.RemoteAddr,
+			Old:       key,
+			New:       database.APIKey{},
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// rejectedIPAuditInterval is how long requests of an API key from an IP
+// address aren't audited again once one of them was rejected. Clients retry
+// rejected requests, and auditing every attempt would flood the audit log.
+const rejectedIPAuditInterval = 10 * time.Minute
+
+// rejectedIPAudits remembers when a rejected request of an API key from an IP
+// address was last audited. It's kept per replica, so each replica audits a
+// key and address at most once per interval.
+type rejectedIPAudits struct {
+	mu   sync.Mutex
+	last map[rejectedIPAuditKey]time.Time
+}
+
+type rejectedIPAuditKey struct {
+	keyID string
+	ip    string
+}
+
+// allow reports whether a rejected request of the key from the address should
+// be audited, and if so remembers that it was.
+func (a *rejectedIPAudits) allow(keyID, ip string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	k := rejectedIPAuditKey{keyID: keyID, ip: ip}
+	if last, ok := a.last[k]; ok && now.Sub(last) < rejectedIPAuditInterval {
+		return false
+	}
+	if a.last == nil {
+		a.last = make(map[rejectedIPAuditKey]time.Time)
+	}
+	// Forget expired entries, so keys and addresses seen once don't pile up.
+	for other, last := range a.last {
+		if now.Sub(last) >= rejectedIPAuditInterval {
+			delete(a.last, other)
+		}
+	}
+	a.last[k] = now
+	return true
+}
+
+// AuditRejectedIP audits a request that was rejected because it was made from
+// outside the IP allowlist of its API key or the key's user. Further rejected
+// requests of the key from the same address are only audited again after
+// rejectedIPAuditInterval.
+func (api *API) AuditRejectedIP(r *http.Request, key database.APIKey) {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !api.rejectedIPAudits.allow(key.ID, ip, dbtime.Now()) {
+		return
+	}
+
+	// The request is rejected before it is authenticated, so the system
+	// records it on behalf of the key's user.
+	//nolint:gocritic // System needs to insert the audit log.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.APIKey]{
+		Audit:     *api.Auditor.Load(),
+		Log:       api.Logger,
+		UserID:    key.UserID,
+		RequestID: httpmw.RequestID(r),
+		Status:    http.StatusForbidden,
+		Action:    database.AuditActionIpRejected,
+		IP:        r.RemoteAddr,
+		Old:       key,
+		New:       key,
+	})
+}
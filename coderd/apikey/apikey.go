@@ -4,14 +4,18 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"net"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 )
@@ -27,6 +31,18 @@ type CreateParams struct {
 	Scope           database.APIKeyScope
 	TokenName       string
 	RemoteAddr      string
+	// Roles are the site roles of the user. They pick the session duration
+	// of the user's lifetime policy when no expiry or lifetime is given.
+	Roles []string
+	// Scopes limits the key to the given fine-grained scopes. It can only be
+	// used with the "all" scope.
+	Scopes []rbac.ScopeName
+	// AllowedWorkspaceIDs limits a key with fine-grained scopes to the given
+	// workspaces.
+	AllowedWorkspaceIDs []uuid.UUID
+	// IPAllowlist limits the addresses the key can be used from to the given
+	// CIDR ranges or addresses.
+	IPAllowlist []string
 }
 
 // Generate generates an API key, returning the key as a string as well as the
@@ -46,8 +62,9 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		if params.LifetimeSeconds != 0 {
 			params.ExpiresAt = dbtime.Now().Add(time.Duration(params.LifetimeSeconds) * time.Second)
 		} else {
-			params.ExpiresAt = dbtime.Now().Add(params.DeploymentValues.SessionDuration.Value())
-			params.LifetimeSeconds = int64(params.DeploymentValues.SessionDuration.Value().Seconds())
+			sessionDuration := PolicyForRoles(params.DeploymentValues, params.Roles).SessionDuration
+			params.ExpiresAt = dbtime.Now().Add(sessionDuration)
+			params.LifetimeSeconds = int64(sessionDuration.Seconds())
 		}
 	}
 	if params.LifetimeSeconds == 0 {
@@ -71,6 +88,28 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", scope)
 	}
 
+	scopes := make([]string, 0, len(params.Scopes))
+	for _, name := range params.Scopes {
+		if !rbac.IsFineGrainedScope(name) {
+			return database.InsertAPIKeyParams{}, "", xerrors.Errorf("invalid API key scope: %q", name)
+		}
+		scopes = append(scopes, string(name))
+	}
+	if len(scopes) > 0 && scope != database.APIKeyScopeAll {
+		return database.InsertAPIKeyParams{}, "", xerrors.Errorf("fine-grained scopes cannot be combined with the %q scope", scope)
+	}
+	if len(params.AllowedWorkspaceIDs) > 0 && len(scopes) == 0 {
+		return database.InsertAPIKeyParams{}, "", xerrors.New("allowed workspaces require at least one fine-grained scope")
+	}
+	allowedWorkspaceIDs := params.AllowedWorkspaceIDs
+	if allowedWorkspaceIDs == nil {
+		allowedWorkspaceIDs = []uuid.UUID{}
+	}
+	ipAllowlist, err := NormalizeIPAllowlist(params.IPAllowlist)
+	if err != nil {
+		return database.InsertAPIKeyParams{}, "", err
+	}
+
 	token := fmt.Sprintf("%s-%s", keyID, keySecret)
 
 	return database.InsertAPIKeyParams{
@@ -86,16 +125,48 @@ func Generate(params CreateParams) (database.InsertAPIKeyParams, string, error)
 			Valid: true,
 		},
 		// Make sure in UTC time for common time zone
-		ExpiresAt:    params.ExpiresAt.UTC(),
-		CreatedAt:    dbtime.Now(),
-		UpdatedAt:    dbtime.Now(),
-		HashedSecret: hashed[:],
-		LoginType:    params.LoginType,
-		Scope:        scope,
-		TokenName:    params.TokenName,
+		ExpiresAt:           params.ExpiresAt.UTC(),
+		CreatedAt:           dbtime.Now(),
+		UpdatedAt:           dbtime.Now(),
+		HashedSecret:        hashed[:],
+		LoginType:           params.LoginType,
+		Scope:               scope,
+		TokenName:           params.TokenName,
+		Scopes:              scopes,
+		AllowedWorkspaceIDs: allowedWorkspaceIDs,
+		IPAllowlist:         ipAllowlist,
 	}, token, nil
 }
 
+// NormalizeIPAllowlist validates an allowlist of CIDR ranges and addresses,
+// returning it as masked CIDR ranges without duplicates. A bare address is
+// treated as a range holding only that address.
+func NormalizeIPAllowlist(allowlist []string) ([]string, error) {
+	normalized := make([]string, 0, len(allowlist))
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		var prefix netip.Prefix
+		if strings.Contains(entry, "/") {
+			parsed, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid IP allowlist entry %q: %w", entry, err)
+			}
+			prefix = parsed.Masked()
+		} else {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid IP allowlist entry %q: %w", entry, err)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		if !slices.Contains(normalized, prefix.String()) {
+			normalized = append(normalized, prefix.String())
+		}
+	}
+	return normalized, nil
+}
+
 // generateKey a new ID and secret for an API key.
 func generateKey() (id string, secret string, err error) {
 	// Length of an API Key ID.
@@ -14,6 +14,7 @@ import (
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -95,6 +96,84 @@ func TestGenerate(t *testing.T) {
 				Scope:            "",
 			},
 		},
+		{
+			name: "FineGrainedScopes",
+			params: apikey.CreateParams{
+				UserID:              uuid.New(),
+				LoginType:           database.LoginTypeToken,
+				DeploymentValues:    &codersdk.DeploymentValues{},
+				ExpiresAt:           time.Now().Add(time.Hour),
+				LifetimeSeconds:     int64(time.Hour.Seconds()),
+				TokenName:           "hello",
+				Scopes:              []rbac.ScopeName{rbac.ScopeWorkspaceRead, rbac.ScopeTemplateRead},
+				AllowedWorkspaceIDs: []uuid.UUID{uuid.New()},
+			},
+		},
+		{
+			name: "InvalidFineGrainedScope",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				Scopes:           []rbac.ScopeName{"workspace:delete"},
+			},
+			fail: true,
+		},
+		{
+			name: "FineGrainedScopesWithApplicationConnect",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				Scope:            database.APIKeyScopeApplicationConnect,
+				Scopes:           []rbac.ScopeName{rbac.ScopeWorkspaceRead},
+			},
+			fail: true,
+		},
+		{
+			name: "AllowedWorkspacesWithoutScopes",
+			params: apikey.CreateParams{
+				UserID:              uuid.New(),
+				LoginType:           database.LoginTypeToken,
+				DeploymentValues:    &codersdk.DeploymentValues{},
+				ExpiresAt:           time.Now().Add(time.Hour),
+				LifetimeSeconds:     int64(time.Hour.Seconds()),
+				TokenName:           "hello",
+				AllowedWorkspaceIDs: []uuid.UUID{uuid.New()},
+			},
+			fail: true,
+		},
+		{
+			name: "IPAllowlist",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				IPAllowlist:      []string{"10.0.0.0/8", "192.168.1.1"},
+			},
+		},
+		{
+			name: "InvalidIPAllowlist",
+			params: apikey.CreateParams{
+				UserID:           uuid.New(),
+				LoginType:        database.LoginTypeToken,
+				DeploymentValues: &codersdk.DeploymentValues{},
+				ExpiresAt:        time.Now().Add(time.Hour),
+				LifetimeSeconds:  int64(time.Hour.Seconds()),
+				TokenName:        "hello",
+				IPAllowlist:      []string{"10.0.0.0/33"},
+			},
+			fail: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -160,6 +239,38 @@ func TestGenerate(t *testing.T) {
 			if tc.params.LoginType != "" {
 				assert.Equal(t, tc.params.LoginType, key.LoginType)
 			}
+
+			assert.Len(t, key.Scopes, len(tc.params.Scopes))
+			for i, scope := range tc.params.Scopes {
+				assert.Equal(t, string(scope), key.Scopes[i])
+			}
+			assert.NotNil(t, key.AllowedWorkspaceIDs)
+			assert.ElementsMatch(t, tc.params.AllowedWorkspaceIDs, key.AllowedWorkspaceIDs)
+			assert.NotNil(t, key.IPAllowlist)
+			assert.Len(t, key.IPAllowlist, len(tc.params.IPAllowlist))
 		})
 	}
 }
+
+func TestNormalizeIPAllowlist(t *testing.T) {
+	t.Parallel()
+
+	allowlist, err := apikey.NormalizeIPAllowlist([]string{
+		"10.1.2.3/8",
+		" 192.168.1.1 ",
+		"2001:db8::1/32",
+		"::ffff:172.16.0.1",
+		"10.0.0.0/8",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/8", "192.168.1.1/32", "2001:db8::/32", "172.16.0.1/32"}, allowlist)
+
+	allowlist, err = apikey.NormalizeIPAllowlist(nil)
+	require.NoError(t, err)
+	require.Empty(t, allowlist)
+
+	for _, entry := range []string{"", "example.com", "10.0.0.0/33", "10.0.0.256"} {
+		_, err = apikey.NormalizeIPAllowlist([]string{entry})
+		require.Error(t, err, entry)
+	}
+}
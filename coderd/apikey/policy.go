@@ -0,0 +1,79 @@
+package apikey
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// LifetimePolicy limits the lifetime of the API keys of a user. Users with
+// the owner role can be given a stricter or looser policy than other users
+// through the owner deployment options.
+type LifetimePolicy struct {
+	// MaxTokenLifetime is the longest lifetime a token can be created with.
+	MaxTokenLifetime time.Duration
+	// SessionDuration is the lifetime of new sessions. Sessions are extended
+	// while they are used unless DisableExpiryRefresh is set, so it is also
+	// how long a session can stay idle.
+	SessionDuration      time.Duration
+	DisableExpiryRefresh bool
+	// Truncate shortens existing keys to the lifetimes of the policy when
+	// they are used. Otherwise keys keep the expiry they were created with.
+	Truncate bool
+}
+
+// PolicyForRoles returns the lifetime policy of a user with the given site
+// roles.
+func PolicyForRoles(values *codersdk.DeploymentValues, roles []string) LifetimePolicy {
+	policy := LifetimePolicy{
+		MaxTokenLifetime:     values.MaxTokenLifetime.Value(),
+		SessionDuration:      values.SessionDuration.Value(),
+		DisableExpiryRefresh: values.DisableSessionExpiryRefresh.Value(),
+		Truncate:             values.TruncateTokenLifetimes.Value(),
+	}
+	if !slices.Contains(roles, rbac.RoleOwner()) {
+		return policy
+	}
+	if values.OwnerMaxTokenLifetime.Value() > 0 {
+		policy.MaxTokenLifetime = values.OwnerMaxTokenLifetime.Value()
+	}
+	if values.OwnerSessionDuration.Value() > 0 {
+		policy.SessionDuration = values.OwnerSessionDuration.Value()
+	}
+	if values.OwnerDisableExpiryRefresh.Value() {
+		policy.DisableExpiryRefresh = true
+	}
+	return policy
+}
+
+// MaxLifetime returns the longest lifetime the policy allows for the key.
+func (p LifetimePolicy) MaxLifetime(key database.APIKey) time.Duration {
+	if key.LoginType == database.LoginTypeToken {
+		return p.MaxTokenLifetime
+	}
+	return p.SessionDuration
+}
+
+// TruncatedExpiry returns when the key expires under the policy. Tokens
+// can't outlive the maximum token lifetime counted from their creation, and
+// sessions can't be valid for longer than the session duration from now.
+// The expiry of the key is returned as is if the policy doesn't truncate
+// keys.
+func (p LifetimePolicy) TruncatedExpiry(key database.APIKey, now time.Time) time.Time {
+	maxLifetime := p.MaxLifetime(key)
+	if !p.Truncate || maxLifetime <= 0 {
+		return key.ExpiresAt
+	}
+	limit := now.Add(maxLifetime)
+	if key.LoginType == database.LoginTypeToken {
+		limit = key.CreatedAt.Add(maxLifetime)
+	}
+	if key.ExpiresAt.After(limit) {
+		return limit
+	}
+	return key.ExpiresAt
+}
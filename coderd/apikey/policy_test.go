@@ -0,0 +1,75 @@
+package apikey_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestPolicyForRoles(t *testing.T) {
+	t.Parallel()
+
+	values := &codersdk.DeploymentValues{
+		MaxTokenLifetime:          clibase.Duration(30 * 24 * time.Hour),
+		SessionDuration:           clibase.Duration(24 * time.Hour),
+		OwnerMaxTokenLifetime:     clibase.Duration(7 * 24 * time.Hour),
+		OwnerDisableExpiryRefresh: true,
+		TruncateTokenLifetimes:    true,
+	}
+
+	member := apikey.PolicyForRoles(values, []string{rbac.RoleMember()})
+	require.Equal(t, apikey.LifetimePolicy{
+		MaxTokenLifetime: 30 * 24 * time.Hour,
+		SessionDuration:  24 * time.Hour,
+		Truncate:         true,
+	}, member)
+
+	// Unset owner options fall back to the options of all users.
+	owner := apikey.PolicyForRoles(values, []string{rbac.RoleOwner(), rbac.RoleMember()})
+	require.Equal(t, apikey.LifetimePolicy{
+		MaxTokenLifetime:     7 * 24 * time.Hour,
+		SessionDuration:      24 * time.Hour,
+		DisableExpiryRefresh: true,
+		Truncate:             true,
+	}, owner)
+}
+
+func TestTruncatedExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := dbtime.Now()
+	policy := apikey.LifetimePolicy{
+		MaxTokenLifetime: 7 * 24 * time.Hour,
+		SessionDuration:  time.Hour,
+	}
+	token := database.APIKey{
+		LoginType: database.LoginTypeToken,
+		CreatedAt: now.Add(-24 * time.Hour),
+		ExpiresAt: now.Add(30 * 24 * time.Hour),
+	}
+	session := database.APIKey{
+		LoginType: database.LoginTypePassword,
+		CreatedAt: now.Add(-24 * time.Hour),
+		ExpiresAt: now.Add(24 * time.Hour),
+	}
+
+	// Keys are grandfathered by default.
+	require.Equal(t, token.ExpiresAt, policy.TruncatedExpiry(token, now))
+	require.Equal(t, session.ExpiresAt, policy.TruncatedExpiry(session, now))
+
+	policy.Truncate = true
+	require.Equal(t, token.CreatedAt.Add(policy.MaxTokenLifetime), policy.TruncatedExpiry(token, now))
+	require.Equal(t, now.Add(policy.SessionDuration), policy.TruncatedExpiry(session, now))
+
+	// Keys within the policy are left alone.
+	session.ExpiresAt = now.Add(time.Minute)
+	require.Equal(t, session.ExpiresAt, policy.TruncatedExpiry(session, now))
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -85,6 +86,198 @@ func TestTokenScoped(t *testing.T) {
 	require.Equal(t, keys[0].Scope, codersdk.APIKeyScopeApplicationConnect)
 }
 
+func TestTokenFineGrainedScopes(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	allowed := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, allowed.LatestBuild.ID)
+	other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, other.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	config, err := client.GetTokenConfig(ctx, codersdk.Me)
+	require.NoError(t, err)
+	names := make([]codersdk.APITokenScope, 0, len(config.Scopes))
+	for _, scope := range config.Scopes {
+		require.NotEmpty(t, scope.DisplayName)
+		names = append(names, scope.Name)
+	}
+	require.Contains(t, names, codersdk.APITokenScopeWorkspaceRead)
+
+	t.Run("WorkspaceRead", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName: "workspace-read",
+			Scopes:    []codersdk.APITokenScope{codersdk.APITokenScopeWorkspaceRead},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		key, err := client.APIKeyByName(ctx, codersdk.Me, "workspace-read")
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.APITokenScope{codersdk.APITokenScopeWorkspaceRead}, key.Scopes)
+		require.Empty(t, key.AllowedWorkspaceIDs)
+
+		_, err = scoped.Workspace(ctx, allowed.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, other.ID)
+		require.NoError(t, err)
+
+		// The scope does not allow creating other tokens.
+		_, err = scoped.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+		require.Error(t, err)
+	})
+
+	t.Run("AllowedWorkspaces", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName:           "allowed-workspaces",
+			Scopes:              []codersdk.APITokenScope{codersdk.APITokenScopeWorkspaceRead},
+			AllowedWorkspaceIDs: []uuid.UUID{allowed.ID},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		_, err = scoped.Workspace(ctx, allowed.ID)
+		require.NoError(t, err)
+		_, err = scoped.Workspace(ctx, other.ID)
+		require.Error(t, err)
+	})
+
+	t.Run("DeletedWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		deleted := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, deleted.LatestBuild.ID)
+		res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			TokenName:           "deleted-workspace",
+			Scopes:              []codersdk.APITokenScope{codersdk.APITokenScopeWorkspaceRead},
+			AllowedWorkspaceIDs: []uuid.UUID{deleted.ID},
+		})
+		require.NoError(t, err)
+		scoped := codersdk.New(client.URL)
+		scoped.SetSessionToken(res.Key)
+
+		build, err := client.CreateWorkspaceBuild(ctx, deleted.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		// The key fails closed instead of losing access to the workspace
+		// silently.
+		_, err = scoped.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+		require.Contains(t, apiErr.Detail, "has been deleted")
+	})
+
+	t.Run("InvalidScope", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scopes: []codersdk.APITokenScope{"workspace:delete"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("ApplicationConnect", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Scope:  codersdk.APIKeyScopeApplicationConnect,
+			Scopes: []codersdk.APITokenScope{codersdk.APITokenScopeWorkspaceRead},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("AllowedWorkspacesWithoutScopes", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			AllowedWorkspaceIDs: []uuid.UUID{allowed.ID},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestTokenIPAllowlist(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	// The test client connects over loopback.
+	res, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		TokenName:   "loopback",
+		IPAllowlist: []string{"127.0.0.1"},
+	})
+	require.NoError(t, err)
+	key, err := client.APIKeyByName(ctx, codersdk.Me, "loopback")
+	require.NoError(t, err)
+	require.Equal(t, []string{"127.0.0.1/32"}, key.IPAllowlist)
+	allowed := codersdk.New(client.URL)
+	allowed.SetSessionToken(res.Key)
+	_, err = allowed.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	res, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		TokenName:   "remote",
+		IPAllowlist: []string{"192.0.2.0/24"},
+	})
+	require.NoError(t, err)
+	denied := codersdk.New(client.URL)
+	denied.SetSessionToken(res.Key)
+	auditor.ResetLogs()
+	_, err = denied.User(ctx, codersdk.Me)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		UserID:       user.UserID,
+		ResourceType: database.ResourceTypeApiKey,
+		Action:       database.AuditActionIpRejected,
+		StatusCode:   http.StatusForbidden,
+	}))
+
+	// Retries from the same address aren't audited again.
+	_, err = denied.User(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	require.Len(t, auditor.AuditLogs(), 1)
+
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		TokenName:   "invalid",
+		IPAllowlist: []string{"192.0.2.0/33"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
 func TestUserSetTokenDuration(t *testing.T) {
 	t.Parallel()
 
@@ -144,6 +337,43 @@ func TestTokenUserSetMaxLifetime(t *testing.T) {
 	require.ErrorContains(t, err, "lifetime must be less")
 }
 
+func TestTokenOwnerMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+	dc := coderdtest.DeploymentValues(t)
+	dc.OwnerMaxTokenLifetime = clibase.Duration(time.Hour * 24 * 7)
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dc,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	config, err := client.GetTokenConfig(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Equal(t, dc.OwnerMaxTokenLifetime.Value(), config.MaxTokenLifetime)
+
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 8,
+	})
+	require.ErrorContains(t, err, "lifetime must be less")
+
+	// The default lifetime is shortened to the maximum of the owner.
+	_, err = client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+	require.NoError(t, err)
+	keys, err := client.Tokens(ctx, codersdk.Me, codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Less(t, keys[0].ExpiresAt, time.Now().Add(time.Hour*8*24))
+
+	// Members are limited by the maximum token lifetime of all users.
+	_, err = memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+		Lifetime: time.Hour * 24 * 8,
+	})
+	require.NoError(t, err)
+}
+
 func TestSessionExpiry(t *testing.T) {
 	t.Parallel()
 
@@ -196,6 +426,72 @@ func TestSessionExpiry(t *testing.T) {
 	}
 }
 
+func TestUserSessions(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dc := coderdtest.DeploymentValues(t)
+	dc.MaxConcurrentSessions = 2
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues: dc,
+		Auditor:          auditor,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	login := func() *codersdk.Client {
+		c := codersdk.New(client.URL)
+		res, err := c.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    coderdtest.FirstUserParams.Email,
+			Password: coderdtest.FirstUserParams.Password,
+		})
+		require.NoError(t, err)
+		c.SetSessionToken(res.SessionToken)
+		return c
+	}
+	_ = login()
+	newest := login()
+
+	// Signing in beyond the limit signs the user out of their oldest session.
+	_, err := client.User(ctx, codersdk.Me)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeApiKey,
+		ResourceID:   owner.UserID,
+		Action:       database.AuditActionDelete,
+	}))
+
+	// Tokens aren't sessions.
+	_, err = newest.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+	require.NoError(t, err)
+	sessions, err := newest.UserSessions(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, strings.Split(newest.SessionToken(), "-")[0], sessions[0].ID)
+
+	memberClient, member := coderdtest.CreateAnotherUser(t, newest, owner.OrganizationID)
+	_, err = memberClient.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{})
+	require.NoError(t, err)
+	err = newest.DeleteUserSessions(ctx, member.ID.String())
+	require.NoError(t, err)
+	_, err = memberClient.User(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	tokens, err := newest.Tokens(ctx, member.ID.String(), codersdk.TokensFilter{})
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeApiKey,
+		ResourceID:   member.ID,
+		Action:       database.AuditActionDelete,
+	}))
+
+	// Members can't sign other users out.
+	err = memberClient.DeleteUserSessions(ctx, owner.UserID.String())
+	require.Error(t, err)
+}
+
 func TestAPIKey_OK(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
@@ -3,11 +3,14 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
 	"net/netip"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,6 +36,7 @@ import (
 // @Param q query string false "Search query"
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
 // @Success 200 {object} codersdk.AuditLogResponse
 // @Router /audit [get]
 func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
@@ -61,6 +65,25 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 		filter.Username = ""
 	}
 
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		if page.Offset > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Cannot use both cursor and offset pagination.",
+			})
+			return
+		}
+		cursorTime, cursorID, err := decodeAuditLogCursor(cursor)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid audit log cursor.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		api.auditLogsKeyset(rw, r, filter, cursorTime, cursorID)
+		return
+	}
+
 	dblogs, err := api.Database.GetAuditLogsOffset(ctx, filter)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
@@ -76,10 +99,103 @@ func (api *API) auditLogs(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AuditLogResponse{
+	resp := codersdk.AuditLogResponse{
 		AuditLogs: api.convertAuditLogs(ctx, dblogs),
 		Count:     dblogs[0].Count,
+	}
+	if filter.Limit > 0 && len(dblogs) == int(filter.Limit) {
+		last := dblogs[len(dblogs)-1]
+		resp.NextCursor = encodeAuditLogCursor(last.Time, last.ID)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// auditLogsKeyset returns the page of audit logs after the cursor. The total
+// count is not computed because it requires scanning every matching log.
+func (api *API) auditLogsKeyset(rw http.ResponseWriter, r *http.Request, filter database.GetAuditLogsOffsetParams, cursorTime time.Time, cursorID uuid.UUID) {
+	ctx := r.Context()
+
+	dblogs, err := api.Database.GetAuditLogsKeyset(ctx, database.GetAuditLogsKeysetParams{
+		ResourceType:   filter.ResourceType,
+		ResourceID:     filter.ResourceID,
+		ResourceTarget: filter.ResourceTarget,
+		Action:         filter.Action,
+		UserID:         filter.UserID,
+		Username:       filter.Username,
+		Email:          filter.Email,
+		DateFrom:       filter.DateFrom,
+		DateTo:         filter.DateTo,
+		BuildReason:    filter.BuildReason,
+		CursorID:       cursorID,
+		CursorTime:     cursorTime,
+		LimitOpt:       filter.Limit,
 	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rows := make([]database.GetAuditLogsOffsetRow, 0, len(dblogs))
+	for _, dblog := range dblogs {
+		rows = append(rows, database.GetAuditLogsOffsetRow{
+			ID:               dblog.ID,
+			Time:             dblog.Time,
+			UserID:           dblog.UserID,
+			OrganizationID:   dblog.OrganizationID,
+			Ip:               dblog.Ip,
+			UserAgent:        dblog.UserAgent,
+			ResourceType:     dblog.ResourceType,
+			ResourceID:       dblog.ResourceID,
+			ResourceTarget:   dblog.ResourceTarget,
+			Action:           dblog.Action,
+			Diff:             dblog.Diff,
+			StatusCode:       dblog.StatusCode,
+			AdditionalFields: dblog.AdditionalFields,
+			RequestID:        dblog.RequestID,
+			ResourceIcon:     dblog.ResourceIcon,
+			UserUsername:     dblog.UserUsername,
+			UserEmail:        dblog.UserEmail,
+			UserCreatedAt:    dblog.UserCreatedAt,
+			UserStatus:       dblog.UserStatus,
+			UserRoles:        dblog.UserRoles,
+			UserAvatarUrl:    dblog.UserAvatarUrl,
+		})
+	}
+
+	resp := codersdk.AuditLogResponse{
+		AuditLogs: api.convertAuditLogs(ctx, rows),
+	}
+	if filter.Limit > 0 && len(dblogs) == int(filter.Limit) {
+		last := dblogs[len(dblogs)-1]
+		resp.NextCursor = encodeAuditLogCursor(last.Time, last.ID)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// encodeAuditLogCursor returns an opaque cursor pointing after the audit log
+// with the given time and ID.
+func encodeAuditLogCursor(t time.Time, id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", t.UnixNano(), id)))
+}
+
+func decodeAuditLogCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, xerrors.Errorf("decode cursor: %w", err)
+	}
+	nanos, idStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, uuid.Nil, xerrors.New("malformed cursor")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, xerrors.Errorf("parse cursor time: %w", err)
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, xerrors.Errorf("parse cursor id: %w", err)
+	}
+	return time.Unix(0, n), id, nil
 }
 
 // @Summary Generate fake audit log
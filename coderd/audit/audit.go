@@ -21,6 +21,12 @@ type AdditionalFields struct {
 	BuildNumber    string               `json:"build_number"`
 	BuildReason    database.BuildReason `json:"build_reason"`
 	WorkspaceOwner string               `json:"workspace_owner"`
+	// AgentName is only set for port forwarding attempts and agent token
+	// changes.
+	AgentName string `json:"agent_name,omitempty"`
+	Port      uint16 `json:"port,omitempty"`
+	// AgentToken is "rotated" or "revoked" when an agent's token is changed.
+	AgentToken string `json:"agent_token,omitempty"`
 }
 
 func NewNop() Auditor {
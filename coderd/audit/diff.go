@@ -19,7 +19,12 @@ type Auditable interface {
 		database.License |
 		database.WorkspaceProxy |
 		database.AuditOAuthConvertState |
-		database.HealthSettings
+		database.HealthSettings |
+		database.Passkey |
+		database.TwoFactorPolicy |
+		database.Webhook |
+		database.Organization |
+		database.AuditableOrganizationMember
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
@@ -95,6 +95,16 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return string(typed.ToLoginType)
 	case database.HealthSettings:
 		return "" // no target?
+	case database.Passkey:
+		return typed.Name
+	case database.TwoFactorPolicy:
+		return typed.Role
+	case database.Webhook:
+		return typed.Url
+	case database.Organization:
+		return typed.Name
+	case database.AuditableOrganizationMember:
+		return typed.Username
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -128,6 +138,16 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 	case database.HealthSettings:
 		// Artificial ID for auditing purposes
 		return typed.ID
+	case database.Passkey:
+		return typed.ID
+	case database.TwoFactorPolicy:
+		return typed.ID
+	case database.Webhook:
+		return typed.ID
+	case database.Organization:
+		return typed.ID
+	case database.AuditableOrganizationMember:
+		return typed.UserID
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -159,6 +179,16 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeConvertLogin
 	case database.HealthSettings:
 		return database.ResourceTypeHealthSettings
+	case database.Passkey:
+		return database.ResourceTypePasskey
+	case database.TwoFactorPolicy:
+		return database.ResourceTypeTwoFactorPolicy
+	case database.Webhook:
+		return database.ResourceTypeWebhook
+	case database.Organization:
+		return database.ResourceTypeOrganization
+	case database.AuditableOrganizationMember:
+		return database.ResourceTypeOrganizationMember
 	default:
 		panic(fmt.Sprintf("unknown resource %T", typed))
 	}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/audit"
@@ -84,6 +86,95 @@ func TestAuditLogs(t *testing.T) {
 		require.Equal(t, auditLogs.AuditLogs[0].ResourceLink, fmt.Sprintf("/@%s/%s/builds/%s",
 			workspace.OwnerName, workspace.Name, buildNumberString))
 	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		now := time.Now()
+		for i := 0; i < 3; i++ {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				ResourceID: user.UserID,
+				Time:       now.Add(-time.Duration(i) * time.Minute),
+			})
+			require.NoError(t, err)
+		}
+
+		first, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Pagination: codersdk.Pagination{
+				Limit: 2,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, first.AuditLogs, 2)
+		require.NotEmpty(t, first.NextCursor)
+
+		second, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Cursor: first.NextCursor,
+			Pagination: codersdk.Pagination{
+				Limit: 2,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, second.AuditLogs, 1)
+		require.Empty(t, second.NextCursor)
+		require.True(t, second.AuditLogs[0].Time.Before(first.AuditLogs[1].Time))
+
+		_, err = client.AuditLogs(ctx, codersdk.AuditLogsRequest{
+			Cursor: first.NextCursor,
+			Pagination: codersdk.Pagination{
+				Limit:  2,
+				Offset: 1,
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("CursorSameTime", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		// Creating the first user adds audit logs too, so these logs are
+		// newer to make them the first pages.
+		now := time.Now().Add(time.Hour)
+		for i := 0; i < 5; i++ {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				ResourceID: user.UserID,
+				Time:       now,
+			})
+			require.NoError(t, err)
+		}
+
+		// Pages of two straddle the logs that share a timestamp. The first
+		// page uses offset pagination and the rest use the cursor.
+		seen := map[uuid.UUID]bool{}
+		req := codersdk.AuditLogsRequest{
+			Pagination: codersdk.Pagination{
+				Limit: 2,
+			},
+		}
+		for len(seen) < 5 {
+			page, err := client.AuditLogs(ctx, req)
+			require.NoError(t, err)
+			require.NotEmpty(t, page.AuditLogs)
+			for _, alog := range page.AuditLogs {
+				require.False(t, seen[alog.ID], "audit log %s returned twice", alog.ID)
+				seen[alog.ID] = true
+			}
+			if len(seen) < 5 {
+				require.NotEmpty(t, page.NextCursor)
+			}
+			req.Cursor = page.NextCursor
+		}
+	})
 }
 
 func TestAuditLogsFilter(t *testing.T) {
@@ -3,6 +3,7 @@ package autobuild
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -19,9 +20,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
 )
 
 // Executor automatically starts or stops workspaces.
@@ -35,6 +39,8 @@ type Executor struct {
 	log                   slog.Logger
 	tick                  <-chan time.Time
 	statsCh               chan<- Stats
+	webhooks              webhooks.Publisher
+	notifications         notifications.Enqueuer
 }
 
 // Stats contains information about one run of Executor.
@@ -56,6 +62,8 @@ func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *
 		log:                   log.Named("autobuild"),
 		auditor:               auditor,
 		accessControlStore:    acs,
+		webhooks:              webhooks.NewNoop(),
+		notifications:         notifications.NewNoop(),
 	}
 	return le
 }
@@ -67,6 +75,20 @@ func (e *Executor) WithStatsChannel(ch chan<- Stats) *Executor {
 	return e
 }
 
+// WithWebhooks will cause Executor to notify webhooks when a template
+// maintenance window stops a workspace.
+func (e *Executor) WithWebhooks(p webhooks.Publisher) *Executor {
+	e.webhooks = p
+	return e
+}
+
+// WithNotifications will cause Executor to notify owners of workspaces that
+// become dormant and are scheduled for deletion.
+func (e *Executor) WithNotifications(n notifications.Enqueuer) *Executor {
+	e.notifications = n
+	return e
+}
+
 // Run will cause executor to start or stop workspaces on every
 // tick from its channel. It will stop when its context is Done, or when
 // its channel is closed.
@@ -136,6 +158,8 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			err := func() error {
 				var job *database.ProvisionerJob
 				var auditLog *auditParams
+				var maintenance *codersdk.WebhookWorkspaceMaintenanceData
+				var dormant *codersdk.WebhookWorkspaceDormantData
 				err := e.db.InTx(func(tx database.Store) error {
 					// Re-check eligibility since the first check was outside the
 					// transaction and the workspace settings may have changed.
@@ -172,7 +196,12 @@ func (e *Executor) runOnce(t time.Time) Stats {
 
 					accessControl := (*(e.accessControlStore.Load())).GetTemplateAccessControl(template)
 
-					nextTransition, reason, err := getNextTransition(user, ws, latestBuild, latestJob, templateSchedule, currentTick)
+					tokenExpired, err := isEphemeralTokenExpired(e.ctx, tx, ws, currentTick)
+					if err != nil {
+						return xerrors.Errorf("check ephemeral workspace token: %w", err)
+					}
+
+					nextTransition, reason, err := getNextTransition(user, ws, template, latestBuild, latestJob, templateSchedule, tokenExpired, currentTick)
 					if err != nil {
 						log.Debug(e.ctx, "skipping workspace", slog.Error(err))
 						// err is used to indicate that a workspace is not eligible
@@ -189,7 +218,7 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							Reason(reason)
 						log.Debug(e.ctx, "auto building workspace", slog.F("transition", nextTransition))
 						if nextTransition == database.WorkspaceTransitionStart &&
-							useActiveVersion(accessControl, ws) {
+							(useActiveVersion(accessControl, ws) || reason == database.BuildReasonMaintenance) {
 							log.Debug(e.ctx, "autostarting with active version")
 							builder = builder.ActiveVersion()
 						}
@@ -220,6 +249,19 @@ func (e *Executor) runOnce(t time.Time) Stats {
 							return xerrors.Errorf("update workspace dormant deleting at: %w", err)
 						}
 
+						dormant = &codersdk.WebhookWorkspaceDormantData{
+							WorkspaceID:   ws.ID,
+							WorkspaceName: ws.Name,
+							OwnerID:       ws.OwnerID,
+							OwnerUsername: user.Username,
+							OwnerEmail:    user.Email,
+							TemplateID:    ws.TemplateID,
+							DormantAt:     ws.DormantAt.Time,
+						}
+						if ws.DeletingAt.Valid {
+							dormant.DeletingAt = &ws.DeletingAt.Time
+						}
+
 						log.Info(e.ctx, "dormant workspace",
 							slog.F("last_used_at", ws.LastUsedAt),
 							slog.F("time_til_dormant", templateSchedule.TimeTilDormant),
@@ -227,7 +269,31 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						)
 					}
 
-					if reason == database.BuildReasonAutodelete {
+					if reason == database.BuildReasonMaintenance && nextTransition == database.WorkspaceTransitionStop {
+						maintenance = &codersdk.WebhookWorkspaceMaintenanceData{
+							WorkspaceID:     ws.ID,
+							WorkspaceName:   ws.Name,
+							OwnerID:         ws.OwnerID,
+							TemplateID:      template.ID,
+							ActiveVersionID: template.ActiveVersionID,
+						}
+						log.Info(e.ctx, "stopping workspace for maintenance",
+							slog.F("template_version_id", latestBuild.TemplateVersionID),
+							slog.F("active_version_id", template.ActiveVersionID),
+						)
+					}
+
+					if reason == database.BuildReasonAutodelete && ws.Ephemeral {
+						auditLog = &auditParams{
+							Action: database.AuditActionDelete,
+							Old:    ws,
+							New:    ws,
+						}
+						log.Info(e.ctx, "deleting ephemeral workspace",
+							slog.F("ttl", time.Duration(ws.Ttl.Int64)),
+							slog.F("token_expired", tokenExpired),
+						)
+					} else if reason == database.BuildReasonAutodelete {
 						log.Info(e.ctx, "deleted workspace",
 							slog.F("dormant_at", ws.DormantAt.Time),
 							slog.F("time_til_dormant_autodelete", templateSchedule.TimeTilDormantAutoDelete),
@@ -251,7 +317,10 @@ func (e *Executor) runOnce(t time.Time) Stats {
 
 					// Run with RepeatableRead isolation so that the build process sees the same data
 					// as our calculation that determines whether an autobuild is necessary.
-				}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+				}, &database.TxOptions{
+					Isolation:    sql.LevelRepeatableRead,
+					TxIdentifier: "lifecycle",
+				})
 				if auditLog != nil {
 					// If the transition didn't succeed then updating the workspace
 					// to indicate dormant didn't either.
@@ -271,6 +340,18 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						return xerrors.Errorf("post provisioner job to pubsub: %w", err)
 					}
 				}
+				if maintenance != nil {
+					e.webhooks.Publish(e.ctx, codersdk.WebhookEventWorkspaceMaintenance, *maintenance)
+				}
+				if dormant != nil {
+					e.webhooks.Publish(e.ctx, codersdk.WebhookEventWorkspaceDormant, *dormant)
+					if dormant.DeletingAt != nil {
+						e.notifications.Enqueue(e.ctx, dormant.OwnerID, codersdk.NotificationTemplateWorkspaceDeleting, map[string]string{
+							"workspace_name": dormant.WorkspaceName,
+							"deleting_at":    dormant.DeletingAt.Format(time.RFC1123),
+						})
+					}
+				}
 				return nil
 			}()
 			if err != nil {
@@ -303,9 +384,11 @@ func (e *Executor) runOnce(t time.Time) Stats {
 func getNextTransition(
 	user database.User,
 	ws database.Workspace,
+	template database.Template,
 	latestBuild database.WorkspaceBuild,
 	latestJob database.ProvisionerJob,
 	templateSchedule schedule.TemplateScheduleOptions,
+	tokenExpired bool,
 	currentTick time.Time,
 ) (
 	database.WorkspaceTransition,
@@ -313,10 +396,18 @@ func getNextTransition(
 	error,
 ) {
 	switch {
+	case isEligibleForEphemeralDelete(ws, latestBuild, latestJob, tokenExpired, currentTick):
+		return database.WorkspaceTransitionDelete, database.BuildReasonAutodelete, nil
 	case isEligibleForAutostop(ws, latestBuild, latestJob, currentTick):
 		return database.WorkspaceTransitionStop, database.BuildReasonAutostop, nil
+	case isEligibleForMaintenanceStop(user, ws, template, latestBuild, latestJob, currentTick):
+		return database.WorkspaceTransitionStop, database.BuildReasonMaintenance, nil
+	case isEligibleForMaintenanceStart(user, ws, latestBuild, latestJob):
+		return database.WorkspaceTransitionStart, database.BuildReasonMaintenance, nil
 	case isEligibleForAutostart(user, ws, latestBuild, latestJob, templateSchedule, currentTick):
 		return database.WorkspaceTransitionStart, database.BuildReasonAutostart, nil
+	case isEligibleForRetry(user, ws, latestBuild, latestJob, currentTick):
+		return latestBuild.Transition, database.BuildReasonRetry, nil
 	case isEligibleForFailedStop(latestBuild, latestJob, templateSchedule, currentTick):
 		return database.WorkspaceTransitionStop, database.BuildReasonAutostop, nil
 	case isEligibleForDormantStop(ws, templateSchedule, currentTick):
@@ -417,8 +508,8 @@ func isEligibleForAutostop(ws database.Workspace, build database.WorkspaceBuild,
 // isEligibleForDormantStop returns true if the workspace should be dormant
 // for breaching the inactivity threshold of the template.
 func isEligibleForDormantStop(ws database.Workspace, templateSchedule schedule.TemplateScheduleOptions, currentTick time.Time) bool {
-	// Only attempt against workspaces not already dormant.
-	return !ws.DormantAt.Valid &&
+	// Only attempt against workspaces not already dormant or exempt.
+	return !ws.DormantAt.Valid && !ws.DormancyExempt &&
 		// The template must specify an time_til_dormant value.
 		templateSchedule.TimeTilDormant > 0 &&
 		// The workspace must breach the time_til_dormant value.
@@ -426,7 +517,7 @@ func isEligibleForDormantStop(ws database.Workspace, templateSchedule schedule.T
 }
 
 func isEligibleForDelete(ws database.Workspace, templateSchedule schedule.TemplateScheduleOptions, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, currentTick time.Time) bool {
-	eligible := ws.DormantAt.Valid && ws.DeletingAt.Valid &&
+	eligible := ws.DormantAt.Valid && ws.DeletingAt.Valid && !ws.DormancyExempt &&
 		// Dormant workspaces should only be deleted if a time_til_dormant_autodelete value is specified.
 		templateSchedule.TimeTilDormantAutoDelete > 0 &&
 		// The workspace must breach the time_til_dormant_autodelete value.
@@ -442,6 +533,47 @@ func isEligibleForDelete(ws database.Workspace, templateSchedule schedule.Templa
 	return eligible
 }
 
+// isEligibleForEphemeralDelete returns true if the ephemeral workspace has
+// outlived its TTL, which unlike the autostop deadline isn't bumped by
+// activity, or the API token that created it has expired.
+func isEligibleForEphemeralDelete(ws database.Workspace, lastBuild database.WorkspaceBuild, lastJob database.ProvisionerJob, tokenExpired bool, currentTick time.Time) bool {
+	if !ws.Ephemeral {
+		return false
+	}
+
+	// Let the current build finish before deleting the workspace.
+	if !lastJob.Finished() {
+		return false
+	}
+
+	// If the last delete job failed we should wait 24 hours before trying
+	// again, like dormant workspaces.
+	if lastBuild.Transition == database.WorkspaceTransitionDelete &&
+		(lastJob.JobStatus != database.ProvisionerJobStatusFailed || currentTick.Sub(lastJob.FinishedAt()) <= time.Hour*24) {
+		return false
+	}
+
+	outlived := ws.Ttl.Valid && ws.Ttl.Int64 > 0 &&
+		!currentTick.Before(ws.CreatedAt.Add(time.Duration(ws.Ttl.Int64)))
+	return outlived || tokenExpired
+}
+
+// isEphemeralTokenExpired returns true if the API token that created the
+// ephemeral workspace has expired or was deleted.
+func isEphemeralTokenExpired(ctx context.Context, db database.Store, ws database.Workspace, currentTick time.Time) (bool, error) {
+	if !ws.Ephemeral || !ws.EphemeralAPIKeyID.Valid {
+		return false, nil
+	}
+	key, err := db.GetAPIKeyByID(ctx, ws.EphemeralAPIKeyID.String)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, xerrors.Errorf("get api key: %w", err)
+	}
+	return !currentTick.Before(key.ExpiresAt), nil
+}
+
 // isEligibleForFailedStop returns true if the workspace is eligible to be stopped
 // due to a failed build.
 func isEligibleForFailedStop(build database.WorkspaceBuild, job database.ProvisionerJob, templateSchedule schedule.TemplateScheduleOptions, currentTick time.Time) bool {
@@ -455,7 +587,83 @@ func isEligibleForFailedStop(build database.WorkspaceBuild, job database.Provisi
 		currentTick.Sub(job.CompletedAt.Time) > templateSchedule.FailureTTL
 }
 
+// isEligibleForRetry returns true if the workspace build failed transiently
+// and its retry is due.
+func isEligibleForRetry(user database.User, ws database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob, currentTick time.Time) bool {
+	// Workspaces of suspended users could not be started anyway.
+	if user.Status != database.UserStatusActive && build.Transition == database.WorkspaceTransitionStart {
+		return false
+	}
+
+	if ws.DormantAt.Valid {
+		return false
+	}
+
+	return job.JobStatus == database.ProvisionerJobStatusFailed &&
+		build.RetryAt.Valid &&
+		!currentTick.Before(build.RetryAt.Time)
+}
+
+// isEligibleForMaintenanceStop returns true if the workspace is running an
+// outdated template version while the template's maintenance window is open.
+func isEligibleForMaintenanceStop(user database.User, ws database.Workspace, template database.Template, build database.WorkspaceBuild, job database.ProvisionerJob, currentTick time.Time) bool {
+	// Leave workspaces of suspended users alone, as they could not be
+	// started again afterwards.
+	if user.Status != database.UserStatusActive {
+		return false
+	}
+
+	if ws.MaintenanceOptOut || ws.DormantAt.Valid {
+		return false
+	}
+
+	if build.Transition != database.WorkspaceTransitionStart ||
+		job.JobStatus != database.ProvisionerJobStatusSucceeded {
+		return false
+	}
+
+	if build.TemplateVersionID == template.ActiveVersionID {
+		return false
+	}
+
+	return InMaintenanceWindow(template.MaintenanceWindowSchedule, time.Duration(template.MaintenanceWindowDuration), currentTick)
+}
+
+// isEligibleForMaintenanceStart returns true if the workspace was stopped by
+// a maintenance window and should be started again on the active version.
+func isEligibleForMaintenanceStart(user database.User, ws database.Workspace, build database.WorkspaceBuild, job database.ProvisionerJob) bool {
+	if user.Status != database.UserStatusActive {
+		return false
+	}
+
+	if ws.DormantAt.Valid {
+		return false
+	}
+
+	return build.Transition == database.WorkspaceTransitionStop &&
+		build.Reason == database.BuildReasonMaintenance &&
+		job.JobStatus == database.ProvisionerJobStatusSucceeded
+}
+
+// InMaintenanceWindow returns true if t falls within a maintenance window
+// starting at the times given by the weekly cron schedule and lasting for
+// duration. An empty or invalid schedule never matches.
+func InMaintenanceWindow(schedule string, duration time.Duration, t time.Time) bool {
+	if schedule == "" || duration <= 0 {
+		return false
+	}
+	sched, err := cron.Weekly(schedule)
+	if err != nil {
+		return false
+	}
+	// The window is open if it started no earlier than duration ago.
+	start := sched.Next(t.Add(-duration))
+	return !start.After(t)
+}
+
 type auditParams struct {
+	// Action defaults to write.
+	Action  database.AuditAction
 	Old     database.Workspace
 	New     database.Workspace
 	Success bool
@@ -466,6 +674,10 @@ func auditBuild(ctx context.Context, log slog.Logger, auditor audit.Auditor, par
 	if params.Success {
 		status = http.StatusOK
 	}
+	action := params.Action
+	if action == "" {
+		action = database.AuditActionWrite
+	}
 
 	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.Workspace]{
 		Audit:          auditor,
@@ -475,7 +687,7 @@ func auditBuild(ctx context.Context, log slog.Logger, auditor audit.Auditor, par
 		// Right now there's no request associated with an autobuild
 		// operation.
 		RequestID: uuid.Nil,
-		Action:    database.AuditActionWrite,
+		Action:    action,
 		Old:       params.Old,
 		New:       params.New,
 		Status:    status,
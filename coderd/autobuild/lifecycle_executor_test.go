@@ -940,6 +940,68 @@ func TestExecutorFailedWorkspace(t *testing.T) {
 	})
 }
 
+func TestExecutorRetryFailedBuild(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = testutil.Context(t, testutil.WaitLong)
+		ticker = make(chan time.Time)
+		statCh = make(chan autobuild.Stats)
+		logger = slogtest.Make(t, &slogtest.Options{
+			// We ignore errors here since we expect to fail
+			// builds.
+			IgnoreErrors: true,
+		})
+		client = coderdtest.New(t, &coderdtest.Options{
+			Logger:                   &logger,
+			AutobuildTicker:          ticker,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statCh,
+		})
+	)
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyFailed,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		BuildRetryPolicy: &codersdk.TemplateBuildRetryPolicy{
+			MaxAttempts:   2,
+			BackoffMillis: time.Minute.Milliseconds(),
+		},
+	})
+	require.NoError(t, err)
+
+	ws := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+	require.EqualValues(t, 1, build.Attempt)
+	require.True(t, build.RetryAt.Valid)
+
+	// Nothing happens before the backoff elapsed. Ticks are truncated to the
+	// minute, so the retry is due from the minute after it elapses.
+	ticker <- build.RetryAt.Time.Add(-time.Minute)
+	stats := <-statCh
+	require.Len(t, stats.Transitions, 0)
+
+	ticker <- build.RetryAt.Time.Add(time.Minute)
+	stats = <-statCh
+	require.Len(t, stats.Errors, 0)
+	require.Len(t, stats.Transitions, 1)
+	require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[ws.ID])
+
+	// The retry fails as well, which was the last attempt.
+	ws = coderdtest.MustWorkspace(t, client, ws.ID)
+	build = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+	require.Equal(t, codersdk.BuildReasonRetry, build.Reason)
+	require.EqualValues(t, 2, build.Attempt)
+	require.False(t, build.RetryAt.Valid)
+}
+
 // TestExecutorInactiveWorkspace test AGPL functionality which mainly
 // ensures that autostop actions as a result of an inactive workspace
 // do not trigger.
@@ -990,6 +1052,186 @@ func TestExecutorInactiveWorkspace(t *testing.T) {
 	})
 }
 
+func TestExecutorEphemeralWorkspace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TTL", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan autobuild.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			})
+			// Given: we have an ephemeral workspace with a TTL
+			workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+				cwr.Ephemeral = true
+				cwr.TTLMillis = ptr.Ref(time.Hour.Milliseconds())
+			})
+		)
+		require.True(t, workspace.Ephemeral)
+
+		// When: the autobuild executor ticks after the TTL has passed
+		go func() {
+			tickCh <- workspace.CreatedAt.Add(time.Hour + time.Minute)
+			close(tickCh)
+		}()
+
+		// Then: the workspace should be deleted rather than stopped
+		stats := <-statsCh
+		assert.Len(t, stats.Errors, 0)
+		assert.Len(t, stats.Transitions, 1)
+		assert.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+	})
+
+	t.Run("TokenExpired", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			tickCh  = make(chan time.Time)
+			statsCh = make(chan autobuild.Stats)
+			client  = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          tickCh,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statsCh,
+			})
+			ctx = testutil.Context(t, testutil.WaitLong)
+		)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		// Given: we have an ephemeral workspace created with an API token
+		token, err := client.CreateToken(ctx, codersdk.Me, codersdk.CreateTokenRequest{
+			Lifetime: time.Hour,
+		})
+		require.NoError(t, err)
+		tokenClient := codersdk.New(client.URL)
+		tokenClient.SetSessionToken(token.Key)
+		workspace := coderdtest.CreateWorkspace(t, tokenClient, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.Ephemeral = true
+			cwr.TTLMillis = ptr.Ref((24 * time.Hour).Milliseconds())
+		})
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// When: the autobuild executor ticks after the token has expired but
+		// before the TTL has passed
+		go func() {
+			tickCh <- time.Now().Add(2 * time.Hour)
+			close(tickCh)
+		}()
+
+		// Then: the workspace should be deleted
+		stats := <-statsCh
+		assert.Len(t, stats.Errors, 0)
+		assert.Len(t, stats.Transitions, 1)
+		assert.Equal(t, database.WorkspaceTransitionDelete, stats.Transitions[workspace.ID])
+	})
+}
+
+func TestExecutorMaintenanceWindow(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, optOut bool) (*codersdk.Client, chan time.Time, chan autobuild.Stats, codersdk.Workspace, codersdk.TemplateVersion, *cron.Schedule) {
+		var (
+			sched  = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+			ticker = make(chan time.Time)
+			statCh = make(chan autobuild.Stats)
+			client = coderdtest.New(t, &coderdtest.Options{
+				AutobuildTicker:          ticker,
+				IncludeProvisionerDaemon: true,
+				AutobuildStats:           statCh,
+			})
+			// Given: a running workspace on the current active version.
+			ws = mustProvisionWorkspace(t, client)
+		)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Given: the template has a new active version and a maintenance
+		// window that opens every hour.
+		template, err := client.Template(ctx, ws.TemplateID)
+		require.NoError(t, err)
+		newVersion := coderdtest.CreateTemplateVersion(t, client, template.OrganizationID, nil, func(ctvr *codersdk.CreateTemplateVersionRequest) {
+			ctvr.TemplateID = template.ID
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{ID: newVersion.ID})
+		require.NoError(t, err)
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaintenanceWindow: &codersdk.TemplateMaintenanceWindow{
+				Schedule:       sched.String(),
+				DurationMillis: (30 * time.Minute).Milliseconds(),
+			},
+		})
+		require.NoError(t, err)
+
+		if optOut {
+			err = client.UpdateWorkspaceMaintenanceOptOut(ctx, ws.ID, codersdk.UpdateWorkspaceMaintenanceOptOutRequest{OptOut: true})
+			require.NoError(t, err)
+		}
+		return client, ticker, statCh, ws, newVersion, sched
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, ticker, statCh, ws, newVersion, sched := setup(t, false)
+		require.NotEqual(t, newVersion.ID, ws.LatestBuild.TemplateVersionID)
+
+		// When: the executor ticks while the window is open.
+		tick := sched.Next(ws.LatestBuild.CreatedAt)
+		ticker <- tick
+		stats := <-statCh
+
+		// Then: the workspace is stopped for maintenance.
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStop, stats.Transitions[ws.ID])
+		ws = coderdtest.MustWorkspace(t, client, ws.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, ws.LatestBuild.ID)
+
+		// When: the executor ticks again.
+		ticker <- tick.Add(time.Minute)
+		stats = <-statCh
+
+		// Then: the workspace is started on the active version.
+		require.Len(t, stats.Errors, 0)
+		require.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[ws.ID])
+		ws = coderdtest.MustWorkspace(t, client, ws.ID)
+		require.Equal(t, newVersion.ID, ws.LatestBuild.TemplateVersionID)
+	})
+
+	t.Run("OptOut", func(t *testing.T) {
+		t.Parallel()
+
+		_, ticker, statCh, ws, _, sched := setup(t, true)
+
+		ticker <- sched.Next(ws.LatestBuild.CreatedAt)
+		stats := <-statCh
+
+		// Then: the workspace is left alone.
+		require.Len(t, stats.Errors, 0)
+		require.Len(t, stats.Transitions, 0)
+	})
+
+	t.Run("WindowClosed", func(t *testing.T) {
+		t.Parallel()
+
+		_, ticker, statCh, ws, _, sched := setup(t, false)
+
+		// When: the executor ticks after the window has closed.
+		ticker <- sched.Next(ws.LatestBuild.CreatedAt).Add(45 * time.Minute)
+		stats := <-statCh
+
+		// Then: the workspace is left alone.
+		require.Len(t, stats.Errors, 0)
+		require.Len(t, stats.Transitions, 0)
+	})
+}
+
 func mustProvisionWorkspace(t *testing.T, client *codersdk.Client, mut ...func(*codersdk.CreateWorkspaceRequest)) codersdk.Workspace {
 	t.Helper()
 	user := coderdtest.CreateFirstUser(t, client)
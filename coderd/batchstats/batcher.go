@@ -161,6 +161,12 @@ func (b *Batcher) Add(
 	b.buf.SessionCountReconnectingPTY = append(b.buf.SessionCountReconnectingPTY, st.SessionCountReconnectingPty)
 	b.buf.SessionCountSSH = append(b.buf.SessionCountSSH, st.SessionCountSsh)
 	b.buf.ConnectionMedianLatencyMS = append(b.buf.ConnectionMedianLatencyMS, st.ConnectionMedianLatencyMs)
+	b.buf.CPUUsedCores = append(b.buf.CPUUsedCores, st.CpuUsedCores)
+	b.buf.CPUTotalCores = append(b.buf.CPUTotalCores, st.CpuTotalCores)
+	b.buf.MemoryUsedBytes = append(b.buf.MemoryUsedBytes, st.MemoryUsedBytes)
+	b.buf.MemoryTotalBytes = append(b.buf.MemoryTotalBytes, st.MemoryTotalBytes)
+	b.buf.DiskUsedBytes = append(b.buf.DiskUsedBytes, st.DiskUsedBytes)
+	b.buf.DiskTotalBytes = append(b.buf.DiskTotalBytes, st.DiskTotalBytes)
 
 	// If the buffer is over 80% full, signal the flusher to flush immediately.
 	// We want to trigger flushes early to reduce the likelihood of
@@ -274,6 +280,12 @@ func (b *Batcher) initBuf(size int) {
 		SessionCountReconnectingPTY: make([]int64, 0, b.batchSize),
 		SessionCountSSH:             make([]int64, 0, b.batchSize),
 		ConnectionMedianLatencyMS:   make([]float64, 0, b.batchSize),
+		CPUUsedCores:                make([]float64, 0, b.batchSize),
+		CPUTotalCores:               make([]float64, 0, b.batchSize),
+		MemoryUsedBytes:             make([]int64, 0, b.batchSize),
+		MemoryTotalBytes:            make([]int64, 0, b.batchSize),
+		DiskUsedBytes:               make([]int64, 0, b.batchSize),
+		DiskTotalBytes:              make([]int64, 0, b.batchSize),
 	}
 
 	b.connectionsByProto = make([]map[string]int64, 0, size)
@@ -297,5 +309,11 @@ func (b *Batcher) resetBuf() {
 	b.buf.SessionCountReconnectingPTY = b.buf.SessionCountReconnectingPTY[:0]
 	b.buf.SessionCountSSH = b.buf.SessionCountSSH[:0]
 	b.buf.ConnectionMedianLatencyMS = b.buf.ConnectionMedianLatencyMS[:0]
+	b.buf.CPUUsedCores = b.buf.CPUUsedCores[:0]
+	b.buf.CPUTotalCores = b.buf.CPUTotalCores[:0]
+	b.buf.MemoryUsedBytes = b.buf.MemoryUsedBytes[:0]
+	b.buf.MemoryTotalBytes = b.buf.MemoryTotalBytes[:0]
+	b.buf.DiskUsedBytes = b.buf.DiskUsedBytes[:0]
+	b.buf.DiskTotalBytes = b.buf.DiskTotalBytes[:0]
 	b.connectionsByProto = b.connectionsByProto[:0]
 }
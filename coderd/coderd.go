@@ -27,6 +27,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/klauspost/compress/zstd"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quic-go/quic-go"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
@@ -59,18 +60,24 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
+	"github.com/coder/coder/v2/coderd/useractivity"
 	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wsconncache"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/examples"
 	"github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/site"
 	"github.com/coder/coder/v2/tailnet"
@@ -105,6 +112,9 @@ type Options struct {
 
 	// CacheDir is used for caching files served by the API.
 	CacheDir string
+	// ProvisionerCache is the shared cache of Terraform providers and
+	// modules used by the built-in provisioner daemons, if enabled.
+	ProvisionerCache *tfcache.Cache
 
 	Auditor                        audit.Auditor
 	AgentConnectionUpdateFrequency time.Duration
@@ -115,6 +125,7 @@ type Options struct {
 	GoogleTokenValidator           *idtoken.Validator
 	GithubOAuth2Config             *GithubOAuth2Config
 	OIDCConfig                     *OIDCConfig
+	LDAPConfig                     *LDAPConfig
 	PrometheusRegistry             *prometheus.Registry
 	SecureAuthCookie               bool
 	StrictTransportSecurityCfg     httpmw.HSTSConfig
@@ -125,7 +136,10 @@ type Options struct {
 	RealIPConfig                   *httpmw.RealIPConfig
 	TrialGenerator                 func(ctx context.Context, body codersdk.LicensorTrialRequest) error
 	// TLSCertificates is used to mesh DERP servers securely.
-	TLSCertificates    []tls.Certificate
+	TLSCertificates []tls.Certificate
+	// AgentQUICListener accepts workspace agent connections over QUIC.
+	// Agents only connect over a WebSocket if it is nil.
+	AgentQUICListener  *quic.Listener
 	TailnetCoordinator tailnet.Coordinator
 	DERPServer         *derp.Server
 	// BaseDERPMap is used as the base DERP map for all clients and agents.
@@ -135,6 +149,7 @@ type Options struct {
 	SwaggerEndpoint             bool
 	SetUserGroups               func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, groupNames []string, createMissingGroups bool) error
 	SetUserSiteRoles            func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, roles []string) error
+	SetUserOrganizationRoles    func(ctx context.Context, logger slog.Logger, tx database.Store, userID uuid.UUID, roles []string) error
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	AccessControlStore          *atomic.Pointer[dbauthz.AccessControlStore]
@@ -158,6 +173,12 @@ type Options struct {
 	APIRateLimit   int
 	LoginRateLimit int
 	FilesRateLimit int
+	// APIRateLimitOverrides replace APIRateLimit for specific endpoints and
+	// users.
+	APIRateLimitOverrides httpmw.RateLimitOverrides
+	// SharedRateLimits counts requests in the database so rate limits apply
+	// across all replicas, instead of per replica.
+	SharedRateLimits bool
 
 	MetricsCacheRefreshInterval time.Duration
 	AgentStatsRefreshInterval   time.Duration
@@ -174,6 +195,16 @@ type Options struct {
 
 	HTTPClient *http.Client
 
+	// SessionRecordingStore keeps the recordings of web terminal sessions of
+	// templates with session recording enabled. Such sessions are refused if
+	// it is nil.
+	SessionRecordingStore sessionrecording.Store
+	// NotificationSender delivers notifications to users. Notifications are
+	// not sent to users if it is nil.
+	NotificationSender notifications.Sender
+	// NotificationRoutes post notifications to chat channels.
+	NotificationRoutes []notifications.Route
+
 	UpdateAgentMetrics func(ctx context.Context, labels prometheusmetrics.AgentMetricLabels, metrics []*agentproto.Stats_Metric)
 	StatsBatcher       *batchstats.Batcher
 
@@ -315,6 +346,14 @@ func New(options *Options) *API {
 			return nil
 		}
 	}
+	if options.SetUserOrganizationRoles == nil {
+		options.SetUserOrganizationRoles = func(ctx context.Context, logger slog.Logger, _ database.Store, userID uuid.UUID, roles []string) error {
+			logger.Warn(ctx, "attempted to assign OIDC organization roles without enterprise license",
+				slog.F("user_id", userID), slog.F("roles", roles),
+			)
+			return nil
+		}
+	}
 	if options.TemplateScheduleStore == nil {
 		options.TemplateScheduleStore = &atomic.Pointer[schedule.TemplateScheduleStore]{}
 	}
@@ -388,16 +427,6 @@ func New(options *Options) *API {
 			Authorizer: options.Authorizer,
 			Logger:     options.Logger,
 		},
-		WorkspaceAppsProvider: workspaceapps.NewDBTokenProvider(
-			options.Logger.Named("workspaceapps"),
-			options.AccessURL,
-			options.Authorizer,
-			options.Database,
-			options.DeploymentValues,
-			oauthConfigs,
-			options.AgentInactiveDisconnectTimeout,
-			options.AppSecurityKey,
-		),
 		metricsCache:                metricsCache,
 		Auditor:                     atomic.Pointer[audit.Auditor]{},
 		TailnetCoordinator:          atomic.Pointer[tailnet.Coordinator]{},
@@ -411,6 +440,34 @@ func New(options *Options) *API {
 			options.Logger.Named("acquirer"),
 			options.Database,
 			options.Pubsub),
+		Webhooks: webhooks.New(
+			ctx,
+			options.Database,
+			options.Logger.Named("webhooks"),
+			webhooks.Options{
+				HTTPClient:            options.HTTPClient,
+				AllowPrivateAddresses: options.DeploymentValues.WebhooksAllowPrivateAddresses.Value(),
+			},
+		),
+		UserActivity: useractivity.New(
+			ctx,
+			options.Database,
+			options.Logger.Named("user_activity"),
+			useractivity.Options{},
+		),
+		Notifications: notifications.New(
+			ctx,
+			options.Database,
+			options.Logger.Named("notifications"),
+			notifications.Options{
+				Sender:        options.NotificationSender,
+				Routes:        options.NotificationRoutes,
+				Pubsub:        options.Pubsub,
+				AccessURL:     options.AccessURL,
+				MaxAttempts:   int32(options.DeploymentValues.Notifications.MaxSendAttempts.Value()),
+				RetryInterval: options.DeploymentValues.Notifications.RetryInterval.Value(),
+			},
+		),
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -420,6 +477,19 @@ func New(options *Options) *API {
 		)
 	}
 
+	if mirrorURL := options.DeploymentValues.ExamplesRegistryURL.Value(); mirrorURL.String() != "" {
+		api.examplesMirror = examples.NewMirror(mirrorURL, options.HTTPClient)
+	}
+
+	if interval := options.DeploymentValues.DERP.Config.HealthCheckInterval.Value(); interval > 0 {
+		api.DERPMonitor = derphealth.NewMonitor(derphealth.MonitorOptions{
+			Logger:          options.Logger.Named("derp_monitor"),
+			DERPMap:         api.unfilteredDERPMap,
+			Interval:        interval,
+			ForceWebSockets: options.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
+		})
+	}
+
 	if options.WorkspaceProxiesFetchUpdater == nil {
 		options.WorkspaceProxiesFetchUpdater = &atomic.Pointer[healthcheck.WorkspaceProxiesFetchUpdater]{}
 		var wpfu healthcheck.WorkspaceProxiesFetchUpdater = &healthcheck.AGPLWorkspaceProxiesFetchUpdater{}
@@ -435,6 +505,9 @@ func New(options *Options) *API {
 					DB:        options.Database,
 					Threshold: options.DeploymentValues.Healthcheck.ThresholdDatabase.Value(),
 				},
+				Pubsub: healthcheck.PubsubReportOptions{
+					Pubsub: options.Pubsub,
+				},
 				Websocket: healthcheck.WebsocketReportOptions{
 					AccessURL: options.AccessURL,
 					APIKey:    apiKey,
@@ -443,7 +516,10 @@ func New(options *Options) *API {
 					AccessURL: options.AccessURL,
 				},
 				DerpHealth: derphealth.ReportOptions{
-					DERPMap: api.DERPMap(),
+					// Check every node, including the excluded ones, so
+					// the report shows why they are failing.
+					DERPMap: api.unfilteredDERPMap(),
+					Monitor: api.DERPMonitor,
 				},
 				WorkspaceProxy: healthcheck.WorkspaceProxyReportOptions{
 					CurrentVersion:               buildinfo.Version(),
@@ -472,7 +548,34 @@ func New(options *Options) *API {
 	}
 
 	api.Auditor.Store(&options.Auditor)
+	api.WorkspaceAppsProvider = workspaceapps.NewDBTokenProvider(
+		options.Logger.Named("workspaceapps"),
+		options.AccessURL,
+		options.Authorizer,
+		options.Database,
+		options.DeploymentValues,
+		oauthConfigs,
+		options.AgentInactiveDisconnectTimeout,
+		options.AppSecurityKey,
+		&api.Auditor,
+	)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
+	api.nodeKeyRotator, err = newNodeKeyRotator(
+		options.Logger.Named("node_key_rotator"),
+		options.Pubsub,
+		&api.TailnetCoordinator,
+		options.PrometheusRegistry,
+		options.DeploymentValues.DERP.Config.NodeKeyRotationInterval.Value(),
+	)
+	if err != nil {
+		panic("failed to setup node key rotation: " + err.Error())
+	}
+	api.loginThrottler = newLoginThrottler(
+		options.Logger.Named("login_throttler"),
+		options.Database,
+		options.PrometheusRegistry,
+		options.DeploymentValues.LoginThrottle,
+	)
 	api.agentProvider, err = NewServerTailnet(api.ctx,
 		options.Logger,
 		options.DERPServer,
@@ -519,6 +622,8 @@ func New(options *Options) *API {
 		AgentProvider:       api.agentProvider,
 		AppSecurityKey:      options.AppSecurityKey,
 		StatsCollector:      workspaceapps.NewStatsCollector(options.WorkspaceAppsStatsCollectorOptions),
+		ConnectionLogger:    terminalConnectionLogger{api: api},
+		TerminalRecorder:    terminalRecorder{api: api},
 
 		DisablePathApps:  options.DeploymentValues.DisablePathApps.Value(),
 		SecureAuthCookie: options.DeploymentValues.SecureAuthCookie.Value(),
@@ -529,8 +634,11 @@ func New(options *Options) *API {
 		OAuth2Configs:               oauthConfigs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy:              api.LifetimePolicy,
 		Optional:                    false,
 		SessionTokenFunc:            nil, // Default behavior
+		IPRejected:                  api.AuditRejectedIP,
+		ActivityTracker:             api.UserActivity,
 	})
 	// Same as above but it redirects to the login page.
 	apiKeyMiddlewareRedirect := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -538,8 +646,11 @@ func New(options *Options) *API {
 		OAuth2Configs:               oauthConfigs,
 		RedirectToLogin:             true,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy:              api.LifetimePolicy,
 		Optional:                    false,
 		SessionTokenFunc:            nil, // Default behavior
+		IPRejected:                  api.AuditRejectedIP,
+		ActivityTracker:             api.UserActivity,
 	})
 	// Same as the first but it's optional.
 	apiKeyMiddlewareOptional := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
@@ -547,13 +658,28 @@ func New(options *Options) *API {
 		OAuth2Configs:               oauthConfigs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy:              api.LifetimePolicy,
 		Optional:                    true,
 		SessionTokenFunc:            nil, // Default behavior
+		IPRejected:                  api.AuditRejectedIP,
+		ActivityTracker:             api.UserActivity,
 	})
 
-	// API rate limit middleware. The counter is local and not shared between
-	// replicas or instances of this middleware.
-	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute)
+	// Rate limit counters are local to each replica unless they are shared
+	// through the database.
+	var rateLimitDB database.Store
+	if options.SharedRateLimits {
+		rateLimitDB = options.Database
+	}
+	rateLimit := func(count int, overrides httpmw.RateLimitOverrides) func(http.Handler) http.Handler {
+		return httpmw.RateLimitWithOptions(httpmw.RateLimitOptions{
+			Count:     count,
+			Window:    time.Minute,
+			DB:        rateLimitDB,
+			Overrides: overrides,
+		})
+	}
+	apiRateLimiter := rateLimit(options.APIRateLimit, options.APIRateLimitOverrides)
 
 	derpHandler := derphttp.Handler(api.DERPServer)
 	derpHandler, api.derpCloseFunc = tailnet.WithWebsocketSupport(api.DERPServer, derpHandler)
@@ -677,14 +803,35 @@ func New(options *Options) *API {
 			r.Get("/", api.auditLogs)
 			r.Post("/testgenerate", api.generateFakeAuditLog)
 		})
+		r.Route("/connectionlogs", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+
+			r.Get("/", api.connectionLogs)
+		})
+		r.Route("/sessionrecordings", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+
+			r.Get("/", api.sessionRecordings)
+			r.Get("/{sessionrecording}", api.sessionRecording)
+		})
 		r.Route("/files", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
-				httpmw.RateLimit(options.FilesRateLimit, time.Minute),
+				rateLimit(options.FilesRateLimit, httpmw.RateLimitOverrides{}),
 			)
 			r.Get("/{fileID}", api.fileByID)
 			r.Post("/", api.postFile)
 		})
+		r.Route("/provisionerdaemons", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+			)
+			r.Delete("/cache", api.deleteProvisionerCache)
+		})
 		r.Route("/external-auth", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -711,11 +858,13 @@ func New(options *Options) *API {
 					httpmw.ExtractOrganizationParam(options.Database),
 				)
 				r.Get("/", api.organization)
+				r.Delete("/", api.deleteOrganization)
 				r.Post("/templateversions", api.postTemplateVersionsByOrganization)
 				r.Route("/templates", func(r chi.Router) {
 					r.Post("/", api.postTemplateByOrganization)
 					r.Get("/", api.templatesByOrganization)
 					r.Get("/examples", api.templateExamples)
+					r.Get("/examples/{example}/archive", api.templateExampleArchive)
 					r.Route("/{templatename}", func(r chi.Router) {
 						r.Get("/", api.templateByOrganizationAndName)
 						r.Route("/versions/{templateversionname}", func(r chi.Router) {
@@ -724,14 +873,27 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/template-variables", func(r chi.Router) {
+					r.Get("/", api.organizationTemplateVariableValues)
+					r.Put("/{variable}", api.putOrganizationTemplateVariableValue)
+					r.Delete("/{variable}", api.deleteOrganizationTemplateVariableValue)
+				})
+				r.Put("/provisionerjobs/{job}/priority", api.putProvisionerJobPriority)
 				r.Route("/members", func(r chi.Router) {
+					r.Get("/", api.listMembers)
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
-						r.Use(
-							httpmw.ExtractOrganizationMemberParam(options.Database),
-						)
-						r.Put("/roles", api.putMemberRoles)
-						r.Post("/workspaces", api.postWorkspacesByOrganization)
+						r.With(
+							httpmw.ExtractOrganizationNonMemberParam(options.Database),
+						).Post("/", api.postOrganizationMember)
+						r.Group(func(r chi.Router) {
+							r.Use(
+								httpmw.ExtractOrganizationMemberParam(options.Database),
+							)
+							r.Delete("/", api.deleteOrganizationMember)
+							r.Put("/roles", api.putMemberRoles)
+							r.Post("/workspaces", api.postWorkspacesByOrganization)
+						})
 					})
 				})
 			})
@@ -742,9 +904,16 @@ func New(options *Options) *API {
 				httpmw.ExtractTemplateParam(options.Database),
 			)
 			r.Get("/daus", api.templateDAUs)
+			r.Get("/insights", api.templateSummaryInsights)
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
+			r.Get("/workspace-name", api.templateWorkspaceName)
+			r.Route("/variables", func(r chi.Router) {
+				r.Get("/", api.templateVariableValues)
+				r.Put("/{variable}", api.putTemplateVariableValue)
+				r.Delete("/{variable}", api.deleteTemplateVariableValue)
+			})
 			r.Route("/versions", func(r chi.Router) {
 				r.Post("/archive", api.postArchiveTemplateVersions)
 				r.Get("/", api.templateVersionsByTemplate)
@@ -760,6 +929,7 @@ func New(options *Options) *API {
 			r.Get("/", api.templateVersion)
 			r.Patch("/", api.patchTemplateVersion)
 			r.Patch("/cancel", api.patchCancelTemplateVersion)
+			r.Get("/archive", api.templateVersionArchive)
 			r.Post("/archive", api.postArchiveTemplateVersion())
 			r.Post("/unarchive", api.postUnarchiveTemplateVersion())
 			// Old agents may expect a non-error response from /schema and /parameters endpoints.
@@ -767,9 +937,15 @@ func New(options *Options) *API {
 			r.Get("/schema", templateVersionSchemaDeprecated)
 			r.Get("/parameters", templateVersionParametersDeprecated)
 			r.Get("/rich-parameters", api.templateVersionRichParameters)
+			r.Get("/diff", api.templateVersionDiff)
 			r.Get("/external-auth", api.templateVersionExternalAuth)
 			r.Get("/variables", api.templateVersionVariables)
 			r.Get("/resources", api.templateVersionResources)
+			r.Route("/presets", func(r chi.Router) {
+				r.Get("/", api.templateVersionPresets)
+				r.Post("/", api.postTemplateVersionPreset)
+				r.Delete("/{preset}", api.deleteTemplateVersionPreset)
+			})
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
 				r.Post("/", api.postTemplateVersionDryRun)
@@ -790,8 +966,11 @@ func New(options *Options) *API {
 				// attacks.
 				//
 				// This value is intentionally increased during tests.
-				r.Use(httpmw.RateLimit(options.LoginRateLimit, time.Minute))
+				r.Use(rateLimit(options.LoginRateLimit, httpmw.RateLimitOverrides{}))
 				r.Post("/login", api.postLogin)
+				r.Post("/ldap/login", api.postLDAPLogin)
+				r.Post("/login/second-factor", api.postLoginSecondFactor)
+				r.Post("/login/passkey-enrollment", api.postLoginPasskeyEnrollment)
 				r.Route("/oauth2", func(r chi.Router) {
 					r.Route("/github", func(r chi.Router) {
 						r.Use(
@@ -813,10 +992,14 @@ func New(options *Options) *API {
 				)
 				r.Post("/", api.postUser)
 				r.Get("/", api.users)
+				r.Get("/inactive", api.inactiveUsers)
+				r.Get("/lockouts", api.userLockouts)
 				r.Post("/logout", api.postLogout)
 				// These routes query information about site wide roles.
 				r.Route("/roles", func(r chi.Router) {
 					r.Get("/", api.assignableSiteRoles)
+					r.Patch("/", api.patchRole)
+					r.Get("/custom", api.customSiteRoles)
 				})
 				r.Route("/{user}", func(r chi.Router) {
 					r.Use(httpmw.ExtractUserParam(options.Database))
@@ -829,10 +1012,26 @@ func New(options *Options) *API {
 						r.Put("/suspend", api.putSuspendUserAccount())
 						r.Put("/activate", api.putActivateUserAccount())
 					})
+					r.Post("/offboard", api.postUserOffboard)
 					r.Put("/appearance", api.putUserAppearanceSettings)
+					r.Route("/ip-allowlist", func(r chi.Router) {
+						r.Get("/", api.userIPAllowlist)
+						r.Put("/", api.putUserIPAllowlist)
+					})
 					r.Route("/password", func(r chi.Router) {
 						r.Put("/", api.putUserPassword)
 					})
+					r.Delete("/lockout", api.deleteUserLockout)
+					r.Route("/passkeys", func(r chi.Router) {
+						r.Get("/", api.passkeys)
+						r.Post("/", api.postPasskey)
+						r.Post("/challenge", api.postPasskeyChallenge)
+						r.Delete("/{passkey}", api.deletePasskey)
+					})
+					r.Route("/two-factor", func(r chi.Router) {
+						r.Get("/", api.userTwoFactor)
+						r.Post("/recovery-codes", api.postRecoveryCodes)
+					})
 					// These roles apply to the site wide permissions.
 					r.Put("/roles", api.putUserRoles)
 					r.Get("/roles", api.userRoles)
@@ -852,6 +1051,20 @@ func New(options *Options) *API {
 							r.Delete("/", api.deleteAPIKey)
 						})
 					})
+					r.Route("/sessions", func(r chi.Router) {
+						r.Get("/", api.userSessions)
+						r.Delete("/", api.deleteUserSessions)
+					})
+					r.Route("/notifications", func(r chi.Router) {
+						r.Get("/", api.userNotifications)
+						r.Get("/preferences", api.userNotificationPreferences)
+						r.Put("/preferences", api.putUserNotificationPreferences)
+					})
+					r.Route("/inbox", func(r chi.Router) {
+						r.Get("/", api.userInboxNotifications)
+						r.Get("/watch", api.watchUserInboxNotifications)
+						r.Put("/read", api.markUserInboxNotificationsRead)
+					})
 
 					r.Route("/organizations", func(r chi.Router) {
 						r.Get("/", api.organizationsByUser)
@@ -892,6 +1105,7 @@ func New(options *Options) *API {
 				r.Patch("/startup-logs", api.patchWorkspaceAgentLogsDeprecated)
 				r.Patch("/logs", api.patchWorkspaceAgentLogs)
 				r.Post("/app-health", api.postWorkspaceAppHealth)
+				r.Post("/listening-ports", api.postWorkspaceAgentListeningPorts)
 				// Deprecated: Required to support legacy agents
 				r.Get("/gitauth", api.workspaceAgentsGitAuth)
 				r.Get("/external-auth", api.workspaceAgentsExternalAuth)
@@ -899,6 +1113,7 @@ func New(options *Options) *API {
 				r.Get("/coordinate", api.workspaceAgentCoordinate)
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
+				r.Post("/rotate-token", api.workspaceAgentRotateToken)
 				r.Post("/metadata", api.workspaceAgentPostMetadata)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadataDeprecated)
 			})
@@ -917,9 +1132,14 @@ func New(options *Options) *API {
 				)
 				r.Get("/", api.workspaceAgent)
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
+				r.Get("/metadata-history", api.workspaceAgentMetadataHistory)
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.Get("/netcheck", api.workspaceAgentNetcheck)
+				r.Post("/port-forward", api.workspaceAgentAuthorizePortForward)
+				r.Post("/revoke-token", api.workspaceAgentRevokeToken)
+				r.Get("/resource-usage", api.workspaceAgentResourceUsage)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
 
@@ -931,6 +1151,7 @@ func New(options *Options) *API {
 				apiKeyMiddleware,
 			)
 			r.Get("/", api.workspaces)
+			r.Post("/batch-builds", api.postBatchWorkspaceBuilds)
 			r.Route("/{workspace}", func(r chi.Router) {
 				r.Use(
 					httpmw.ExtractWorkspaceParam(options.Database),
@@ -941,6 +1162,13 @@ func New(options *Options) *API {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
 				})
+				r.Get("/drift", api.workspaceDrift)
+				r.Route("/dry-run/{jobID}", func(r chi.Router) {
+					r.Get("/", api.workspaceBuildDryRun)
+					r.Get("/logs", api.workspaceBuildDryRunLogs)
+					r.Get("/resources", api.workspaceBuildDryRunResources)
+					r.Patch("/cancel", api.patchWorkspaceBuildDryRunCancel)
+				})
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
 				})
@@ -951,6 +1179,28 @@ func New(options *Options) *API {
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Put("/dormant", api.putWorkspaceDormant)
 				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
+				r.Put("/maintenance-opt-out", api.putWorkspaceMaintenanceOptOut)
+				r.Put("/dormancy-exempt", api.putWorkspaceDormancyExempt)
+				r.Route("/acl", func(r chi.Router) {
+					r.Get("/", api.workspaceACL)
+					r.Patch("/", api.patchWorkspaceACL)
+				})
+				r.Route("/favorite", func(r chi.Router) {
+					r.Put("/", api.putFavoriteWorkspace)
+					r.Delete("/", api.deleteFavoriteWorkspace)
+				})
+				r.Get("/parameters/history", api.workspaceParameterHistory)
+				r.Route("/port-share", func(r chi.Router) {
+					r.Get("/", api.workspaceAgentPortShares)
+					r.Post("/", api.postWorkspaceAgentPortShare)
+					r.Delete("/", api.deleteWorkspaceAgentPortShare)
+				})
+				r.Route("/snapshots", func(r chi.Router) {
+					r.Get("/", api.workspaceSnapshots)
+					r.Post("/", api.postWorkspaceSnapshot)
+					r.Post("/{snapshot}/restore", api.postWorkspaceSnapshotRestore)
+				})
+				r.Get("/timeline", api.workspaceTimeline)
 				r.Get("/resolve-autostart", api.resolveAutostart)
 			})
 		})
@@ -993,6 +1243,24 @@ func New(options *Options) *API {
 			r.Get("/user-latency", api.insightsUserLatency)
 			r.Get("/templates", api.insightsTemplates)
 		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.webhooks)
+			r.Post("/", api.postWebhook)
+			r.Route("/{webhook}", func(r chi.Router) {
+				r.Delete("/", api.deleteWebhook)
+				r.Get("/deliveries", api.webhookDeliveries)
+				r.Post("/deliveries/{delivery}/replay", api.replayWebhookDelivery)
+			})
+		})
+		r.Route("/two-factor/policies", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.twoFactorPolicies)
+			r.Route("/{role}", func(r chi.Router) {
+				r.Put("/", api.putTwoFactorPolicy)
+				r.Delete("/", api.deleteTwoFactorPolicy)
+			})
+		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -1011,6 +1279,8 @@ func New(options *Options) *API {
 
 			r.Get("/coordinator", api.debugCoordinator)
 			r.Get("/tailnet", api.debugTailnet)
+			r.Post("/tailnet/rotate-keys", api.debugRotateNodeKeys)
+			r.Get("/group-sync-runs", api.debugGroupSyncRuns)
 			r.Route("/health", func(r chi.Router) {
 				r.Get("/", api.debugDeploymentHealth)
 				r.Route("/settings", func(r chi.Router) {
@@ -1064,6 +1334,10 @@ func New(options *Options) *API {
 	rootRouter.Mount("/", r)
 	api.RootHandler = rootRouter
 
+	if options.AgentQUICListener != nil {
+		go api.serveAgentQUIC(options.AgentQUICListener)
+	}
+
 	return api
 }
 
@@ -1098,6 +1372,9 @@ type API struct {
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	// DERPMapper mutates the DERPMap to include workspace proxies.
 	DERPMapper atomic.Pointer[func(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap]
+	// DERPMonitor probes the DERP nodes and excludes failing ones from the
+	// DERP map. It is nil if DERP health checks are disabled.
+	DERPMonitor *derphealth.Monitor
 	// AccessControlStore is a pointer to an atomic pointer since it is
 	// passed to dbauthz.
 	AccessControlStore *atomic.Pointer[dbauthz.AccessControlStore]
@@ -1118,6 +1395,9 @@ type API struct {
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
+	nodeKeyRotator        *nodeKeyRotator
+	loginThrottler        *loginThrottler
+	rejectedIPAudits      rejectedIPAudits
 	WorkspaceAppsProvider workspaceapps.SignedTokenProvider
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
@@ -1132,12 +1412,26 @@ type API struct {
 	statsBatcher *batchstats.Batcher
 
 	Acquirer *provisionerdserver.Acquirer
+
+	// Webhooks delivers lifecycle events to registered webhooks.
+	Webhooks *webhooks.Dispatcher
+	// Notifications sends notifications to users.
+	Notifications *notifications.Dispatcher
+	// UserActivity records the API activity of users, aggregated by hour.
+	UserActivity *useractivity.Tracker
+
+	// examplesMirror serves the starter templates instead of the embedded
+	// examples when a registry mirror is configured.
+	examplesMirror *examples.Mirror
 }
 
 // Close waits for all WebSocket connections to drain before returning.
 func (api *API) Close() error {
 	api.cancel()
 	api.derpCloseFunc()
+	if api.AgentQUICListener != nil {
+		_ = api.AgentQUICListener.Close()
+	}
 
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Wait()
@@ -1147,12 +1441,19 @@ func (api *API) Close() error {
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
 	}
+	if api.DERPMonitor != nil {
+		_ = api.DERPMonitor.Close()
+	}
 	_ = api.workspaceAppServer.Close()
+	_ = api.nodeKeyRotator.Close()
 	coordinator := api.TailnetCoordinator.Load()
 	if coordinator != nil {
 		_ = (*coordinator).Close()
 	}
 	_ = api.agentProvider.Close()
+	_ = api.Webhooks.Close()
+	_ = api.Notifications.Close()
+	_ = api.UserActivity.Close()
 	return nil
 }
 
@@ -1182,8 +1483,9 @@ func compressHandler(h http.Handler) http.Handler {
 }
 
 // CreateInMemoryProvisionerDaemon is an in-memory connection to a provisionerd.
-// Useful when starting coderd and provisionerd in the same process.
-func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name string) (client proto.DRPCProvisionerDaemonClient, err error) {
+// Useful when starting coderd and provisionerd in the same process. The
+// daemon acquires jobs for the given provisioner types.
+func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name string, provisionerTypes []database.ProvisionerType) (client proto.DRPCProvisionerDaemonClient, err error) {
 	tracer := api.TracerProvider.Tracer(tracing.TracerName)
 	clientSession, serverSession := drpc.MemTransportPipe()
 	defer func() {
@@ -1195,15 +1497,13 @@ func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name st
 
 	//nolint:gocritic // in-memory provisioners are owned by system
 	daemon, err := api.Database.UpsertProvisionerDaemon(dbauthz.AsSystemRestricted(dialCtx), database.UpsertProvisionerDaemonParams{
-		Name:      name,
-		CreatedAt: dbtime.Now(),
-		Provisioners: []database.ProvisionerType{
-			database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform,
-		},
-		Tags:       provisionersdk.MutateTags(uuid.Nil, nil),
-		LastSeenAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
-		Version:    buildinfo.Version(),
-		APIVersion: provisionersdk.VersionCurrent.String(),
+		Name:         name,
+		CreatedAt:    dbtime.Now(),
+		Provisioners: provisionerTypes,
+		Tags:         provisionersdk.MutateTags(uuid.Nil, nil),
+		LastSeenAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+		Version:      buildinfo.Version(),
+		APIVersion:   provisionersdk.VersionCurrent.String(),
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create in-memory provisioner daemon: %w", err)
@@ -1232,6 +1532,8 @@ func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name st
 		provisionerdserver.Options{
 			OIDCConfig:          api.OIDCConfig,
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
+			Webhooks:            api.Webhooks,
+			Notifications:       api.Notifications,
 		},
 	)
 	if err != nil {
@@ -1274,7 +1576,17 @@ func (api *API) CreateInMemoryProvisionerDaemon(dialCtx context.Context, name st
 	return proto.NewDRPCProvisionerDaemonClient(clientSession), nil
 }
 
+// DERPMap returns the DERP map given to clients and agents. Nodes failing the
+// DERP health checks are excluded.
 func (api *API) DERPMap() *tailcfg.DERPMap {
+	derpMap := api.unfilteredDERPMap()
+	if api.DERPMonitor != nil {
+		return api.DERPMonitor.Filter(derpMap)
+	}
+	return derpMap
+}
+
+func (api *API) unfilteredDERPMap() *tailcfg.DERPMap {
 	fn := api.DERPMapper.Load()
 	if fn != nil {
 		return (*fn)(api.Options.BaseDERPMap)
@@ -36,6 +36,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/quic-go/quic-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
@@ -63,8 +64,10 @@ import (
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/unhanger"
 	"github.com/coder/coder/v2/coderd/updatecheck"
@@ -78,6 +81,7 @@ import (
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionerd"
 	provisionerdproto "github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/tailnet"
@@ -100,6 +104,7 @@ type Options struct {
 	GithubOAuth2Config    *coderd.GithubOAuth2Config
 	RealIPConfig          *httpmw.RealIPConfig
 	OIDCConfig            *coderd.OIDCConfig
+	LDAPConfig            *coderd.LDAPConfig
 	GoogleTokenValidator  *idtoken.Validator
 	SSHKeygenAlgorithm    gitsshkey.Algorithm
 	AutobuildTicker       <-chan time.Time
@@ -110,6 +115,10 @@ type Options struct {
 	TrialGenerator        func(ctx context.Context, body codersdk.LicensorTrialRequest) error
 	TemplateScheduleStore schedule.TemplateScheduleStore
 	Coordinator           tailnet.Coordinator
+	ProvisionerCache      *tfcache.Cache
+	SessionRecordingStore sessionrecording.Store
+	NotificationSender    notifications.Sender
+	NotificationRoutes    []notifications.Route
 
 	HealthcheckFunc    func(ctx context.Context, apiKey string) *healthcheck.Report
 	HealthcheckTimeout time.Duration
@@ -138,6 +147,9 @@ type Options struct {
 	ConfigSSH codersdk.SSHConfigResponse
 
 	SwaggerEndpoint bool
+	// AgentQUIC accepts workspace agent connections over QUIC. It requires
+	// TLSCertificates.
+	AgentQUIC bool
 	// Logger should only be overridden if you expect errors
 	// as part of your test.
 	Logger       *slog.Logger
@@ -302,7 +314,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 
 	hangDetectorTicker := time.NewTicker(options.DeploymentValues.JobHangDetectorInterval.Value())
 	defer hangDetectorTicker.Stop()
-	hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, options.Logger.Named("unhanger.detector"), hangDetectorTicker.C)
+	hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, options.Logger.Named("unhanger.detector"), hangDetectorTicker.C).WithAuditor(&auditor)
 	hangDetector.Start()
 	t.Cleanup(hangDetector.Close)
 
@@ -337,6 +349,16 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 	require.NoError(t, err)
 	serverURL.Host = fmt.Sprintf("localhost:%d", tcpAddr.Port)
 
+	var agentQUICListener *quic.Listener
+	if options.AgentQUIC {
+		require.NotNil(t, srv.TLS, "agent QUIC requires TLS certificates")
+		agentQUICListener, err = coderd.ListenAgentQUIC("127.0.0.1:0", srv.TLS)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = agentQUICListener.Close()
+		})
+	}
+
 	derpPort, err := strconv.Atoi(serverURL.Port())
 	require.NoError(t, err)
 
@@ -423,6 +445,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			GithubOAuth2Config:                 options.GithubOAuth2Config,
 			RealIPConfig:                       options.RealIPConfig,
 			OIDCConfig:                         options.OIDCConfig,
+			LDAPConfig:                         options.LDAPConfig,
 			GoogleTokenValidator:               options.GoogleTokenValidator,
 			SSHKeygenAlgorithm:                 options.SSHKeygenAlgorithm,
 			DERPServer:                         derpServer,
@@ -434,6 +457,7 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			TemplateScheduleStore:              &templateScheduleStore,
 			AccessControlStore:                 accessControlStore,
 			TLSCertificates:                    options.TLSCertificates,
+			AgentQUICListener:                  agentQUICListener,
 			TrialGenerator:                     options.TrialGenerator,
 			TailnetCoordinator:                 options.Coordinator,
 			BaseDERPMap:                        derpMap,
@@ -453,6 +477,10 @@ func NewOptions(t testing.TB, options *Options) (func(http.Handler), context.Can
 			WorkspaceAppsStatsCollectorOptions: options.WorkspaceAppsStatsCollectorOptions,
 			AllowWorkspaceRenames:              options.AllowWorkspaceRenames,
 			NewTicker:                          options.NewTicker,
+			ProvisionerCache:                   options.ProvisionerCache,
+			SessionRecordingStore:              options.SessionRecordingStore,
+			NotificationSender:                 options.NotificationSender,
+			NotificationRoutes:                 options.NotificationRoutes,
 		}
 }
 
@@ -535,7 +563,9 @@ func NewProvisionerDaemon(t testing.TB, coderAPI *coderd.API) io.Closer {
 	}()
 
 	daemon := provisionerd.New(func(dialCtx context.Context) (provisionerdproto.DRPCProvisionerDaemonClient, error) {
-		return coderAPI.CreateInMemoryProvisionerDaemon(dialCtx, "test")
+		return coderAPI.CreateInMemoryProvisionerDaemon(dialCtx, "test", []database.ProvisionerType{
+			database.ProvisionerTypeEcho, database.ProvisionerTypeTerraform,
+		})
 	}, &provisionerd.Options{
 		Logger:              coderAPI.Logger.Named("provisionerd").Leveled(slog.LevelDebug),
 		UpdateInterval:      250 * time.Millisecond,
@@ -0,0 +1,184 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/searchquery"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get connection logs
+// @ID get-connection-logs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param q query string false "Search query"
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} codersdk.ConnectionLogResponse
+// @Router /connectionlogs [get]
+func (api *API) connectionLogs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	page, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	filter, errs := searchquery.ConnectionLogs(r.URL.Query().Get("q"))
+	if len(errs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid connection log search query.",
+			Validations: errs,
+		})
+		return
+	}
+	filter.OffsetOpt = int32(page.Offset)
+	filter.LimitOpt = int32(page.Limit)
+
+	if filter.Username == "me" {
+		filter.UserID = apiKey.UserID
+		filter.Username = ""
+	}
+
+	dblogs, err := api.Database.GetConnectionLogsOffset(ctx, filter)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := codersdk.ConnectionLogResponse{
+		ConnectionLogs: make([]codersdk.ConnectionLog, 0, len(dblogs)),
+	}
+	// The count is computed with a window function, so it is only known if
+	// there is at least one log.
+	if len(dblogs) > 0 {
+		resp.Count = dblogs[0].Count
+	}
+	for _, dblog := range dblogs {
+		resp.ConnectionLogs = append(resp.ConnectionLogs, convertConnectionLog(dblog))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// recordConnectionLog records a connection opened to an agent of the
+// workspace. Connections that aren't authenticated by an API key, like the
+// ones proxied by workspace proxies, aren't recorded. Failures are logged and
+// never interrupt the connection.
+func (api *API) recordConnectionLog(ctx context.Context, r *http.Request, apiKey database.APIKey, workspace database.Workspace, agentName string, connectionType database.ConnectionType, port sql.NullInt32) {
+	// nolint:gocritic // Connection logs are recorded by the system on behalf
+	//                 // of the user that opened the connection.
+	_, err := api.Database.InsertConnectionLog(dbauthz.AsSystemRestricted(ctx), database.InsertConnectionLogParams{
+		ID:               uuid.New(),
+		Time:             dbtime.Now(),
+		OrganizationID:   workspace.OrganizationID,
+		WorkspaceOwnerID: workspace.OwnerID,
+		WorkspaceID:      workspace.ID,
+		WorkspaceName:    workspace.Name,
+		AgentName:        agentName,
+		Type:             connectionType,
+		Port:             port,
+		Ip:               parseConnectionIP(r.RemoteAddr),
+		UserAgent:        r.UserAgent(),
+		UserID:           apiKey.UserID,
+		APIKeyID:         apiKey.ID,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "record connection log",
+			slog.F("workspace_id", workspace.ID),
+			slog.F("agent_name", agentName),
+			slog.F("type", connectionType),
+			slog.Error(err),
+		)
+	}
+}
+
+// terminalConnectionLogger records the web terminals opened through the
+// workspace app server of coderd.
+type terminalConnectionLogger struct {
+	api *API
+}
+
+var _ workspaceapps.ConnectionLogger = terminalConnectionLogger{}
+
+func (l terminalConnectionLogger) LogTerminalConnection(r *http.Request, token workspaceapps.SignedToken) {
+	ctx := r.Context()
+	// nolint:gocritic // The request was already authorized by the token.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+
+	// The token was issued for a user, but the request must still carry the
+	// API key of that user to be attributed to it.
+	apiKey, _, ok := httpmw.APIKeyFromRequest(sysCtx, l.api.Database, nil, r)
+	if !ok || apiKey.UserID != token.UserID {
+		return
+	}
+	workspace, err := l.api.Database.GetWorkspaceByID(sysCtx, token.WorkspaceID)
+	if err != nil {
+		l.api.Logger.Warn(ctx, "get workspace for connection log", slog.F("workspace_id", token.WorkspaceID), slog.Error(err))
+		return
+	}
+	agent, err := l.api.Database.GetWorkspaceAgentByID(sysCtx, token.AgentID)
+	if err != nil {
+		l.api.Logger.Warn(ctx, "get workspace agent for connection log", slog.F("agent_id", token.AgentID), slog.Error(err))
+		return
+	}
+	l.api.recordConnectionLog(ctx, r, *apiKey, workspace, agent.Name, database.ConnectionTypeReconnectingPty, sql.NullInt32{})
+}
+
+func parseConnectionIP(ipStr string) pqtype.Inet {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return pqtype.Inet{}
+	}
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+		},
+		Valid: true,
+	}
+}
+
+func convertConnectionLog(dblog database.GetConnectionLogsOffsetRow) codersdk.ConnectionLog {
+	ip, _ := netip.AddrFromSlice(dblog.Ip.IPNet.IP)
+	clog := codersdk.ConnectionLog{
+		ID:                     dblog.ID,
+		Time:                   dblog.Time,
+		OrganizationID:         dblog.OrganizationID,
+		WorkspaceOwnerID:       dblog.WorkspaceOwnerID,
+		WorkspaceOwnerUsername: dblog.WorkspaceOwnerUsername,
+		WorkspaceID:            dblog.WorkspaceID,
+		WorkspaceName:          dblog.WorkspaceName,
+		AgentName:              dblog.AgentName,
+		Type:                   codersdk.ConnectionType(dblog.Type),
+		IP:                     ip.Unmap(),
+		UserAgent:              dblog.UserAgent,
+		UserID:                 dblog.UserID,
+		Username:               dblog.UserUsername,
+		APIKeyID:               dblog.APIKeyID,
+	}
+	if dblog.Port.Valid {
+		port := dblog.Port.Int32
+		clog.Port = &port
+	}
+	return clog
+}
@@ -0,0 +1,83 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestConnectionLogs(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+	apiKeyID := strings.Split(client.SessionToken(), "-")[0]
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	err := client.AuthorizeWorkspaceAgentPortForward(ctx, agentID, 8080)
+	require.NoError(t, err)
+
+	conn, err := client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+		ConnectionType: codersdk.ConnectionTypeSSH,
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.AwaitReachable(ctx)
+
+	// Connections without a type aren't recorded.
+	other, err := client.DialWorkspaceAgent(ctx, agentID, nil)
+	require.NoError(t, err)
+	defer other.Close()
+	other.AwaitReachable(ctx)
+
+	require.Eventually(t, func() bool {
+		res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{SearchQuery: "ssh"})
+		return err == nil && res.Count == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	res, err := client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, res.Count)
+	for _, clog := range res.ConnectionLogs {
+		require.Equal(t, r.Workspace.ID, clog.WorkspaceID)
+		require.Equal(t, r.Workspace.Name, clog.WorkspaceName)
+		require.Equal(t, resources[0].Agents[0].Name, clog.AgentName)
+		require.Equal(t, user.UserID, clog.UserID)
+		require.Equal(t, apiKeyID, clog.APIKeyID)
+		require.True(t, clog.IP.IsValid())
+	}
+
+	res, err = client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{SearchQuery: "type:port_forwarding username:me"})
+	require.NoError(t, err)
+	require.Len(t, res.ConnectionLogs, 1)
+	require.NotNil(t, res.ConnectionLogs[0].Port)
+	require.EqualValues(t, 8080, *res.ConnectionLogs[0].Port)
+
+	// Only users that can read audit logs can read connection logs.
+	_, err = member.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	_, err = client.ConnectionLogs(ctx, codersdk.ConnectionLogsRequest{SearchQuery: "type:telnet"})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
@@ -16,6 +16,8 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cryptorand"
 )
 
 // Store contains all queryable database functions.
@@ -28,7 +30,7 @@ type Store interface {
 	wrapper
 
 	Ping(ctx context.Context) (time.Duration, error)
-	InTx(func(Store) error, *sql.TxOptions) error
+	InTx(func(Store) error, *TxOptions) error
 }
 
 type wrapper interface {
@@ -80,38 +82,118 @@ func (q *sqlQuerier) Ping(ctx context.Context) (time.Duration, error) {
 	return time.Since(start), err
 }
 
-func (q *sqlQuerier) InTx(function func(Store) error, txOpts *sql.TxOptions) error {
+// TxOptions configures a transaction started with InTx. Callers can inspect
+// the options after InTx returns to see how many times the transaction was
+// executed.
+type TxOptions struct {
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+
+	// RetryOnConflict retries the transaction with jittered backoff if it
+	// fails because of a serialization failure or a deadlock. The function
+	// passed to InTx must be safe to execute more than once. Transactions at
+	// serializable isolation are always retried.
+	RetryOnConflict bool
+	// TxIdentifier is used to label the transaction in metrics.
+	TxIdentifier string
+
+	// executionCount is set by InTx to the number of times the transaction
+	// function was executed.
+	executionCount int
+}
+
+// DefaultTXOptions returns the options used when InTx is called with nil
+// options.
+func DefaultTXOptions() *TxOptions {
+	return &TxOptions{
+		Isolation: sql.LevelDefault,
+		ReadOnly:  false,
+	}
+}
+
+// WithID sets the transaction identifier used in metrics.
+func (o *TxOptions) WithID(id string) *TxOptions {
+	o.TxIdentifier = id
+	return o
+}
+
+// ExecutionCount returns the number of times the transaction function was
+// executed. A value greater than one means the transaction was retried.
+func (o TxOptions) ExecutionCount() int {
+	return o.executionCount
+}
+
+func (o *TxOptions) sqlOptions() *sql.TxOptions {
+	if o == nil {
+		return nil
+	}
+	return &sql.TxOptions{
+		Isolation: o.Isolation,
+		ReadOnly:  o.ReadOnly,
+	}
+}
+
+const (
+	// txRetryAttempts is an arbitrarily chosen number.
+	txRetryAttempts = 3
+	// txRetryBaseDelay is the delay before the first retry. It is doubled for
+	// every subsequent attempt.
+	txRetryBaseDelay = 25 * time.Millisecond
+)
+
+func (q *sqlQuerier) InTx(function func(Store) error, txOpts *TxOptions) error {
 	_, inTx := q.db.(*sqlx.Tx)
-	isolation := sql.LevelDefault
-	if txOpts != nil {
-		isolation = txOpts.Isolation
+	if txOpts == nil {
+		// Always have non-nil options so the execution count can be reported.
+		txOpts = DefaultTXOptions()
 	}
 
-	// If we are not already in a transaction, and we are running in serializable
-	// mode, we need to run the transaction in a retry loop. The caller should be
-	// prepared to allow retries if using serializable mode.
-	// If we are in a transaction already, the parent InTx call will handle the retry.
-	// We do not want to duplicate those retries.
-	if !inTx && isolation == sql.LevelSerializable {
-		// This is an arbitrarily chosen number.
-		const retryAmount = 3
+	// If we are in a transaction already, the parent InTx call will handle the
+	// retry and count the executions. We do not want to duplicate those.
+	if inTx {
+		return q.runTx(function, txOpts.sqlOptions())
+	}
+	txOpts.executionCount = 0
+
+	// If we are running in serializable mode, or the caller opted in, we need
+	// to run the transaction in a retry loop. The caller should be prepared to
+	// allow retries in that case.
+	if txOpts.Isolation == sql.LevelSerializable || txOpts.RetryOnConflict {
 		var err error
 		attempts := 0
-		for attempts = 0; attempts < retryAmount; attempts++ {
-			err = q.runTx(function, txOpts)
+		for attempts = 0; attempts < txRetryAttempts; attempts++ {
+			if attempts > 0 {
+				time.Sleep(txRetryDelay(attempts))
+			}
+			txOpts.executionCount++
+			err = q.runTx(function, txOpts.sqlOptions())
 			if err == nil {
 				// Transaction succeeded.
 				return nil
 			}
-			if err != nil && !IsSerializedError(err) {
-				// We should only retry if the error is a serialization error.
+			if !IsSerializedError(err) && !IsDeadlockError(err) {
+				// We should only retry if the transaction conflicted with
+				// another one.
 				return err
 			}
 		}
-		// Transaction kept failing in serializable mode.
+		// Transaction kept failing.
 		return xerrors.Errorf("transaction failed after %d attempts: %w", attempts, err)
 	}
-	return q.runTx(function, txOpts)
+	txOpts.executionCount++
+	return q.runTx(function, txOpts.sqlOptions())
+}
+
+// txRetryDelay returns a random delay between zero and an exponentially
+// growing upper bound, so that conflicting transactions do not retry in
+// lockstep.
+func txRetryDelay(attempt int) time.Duration {
+	upper := txRetryBaseDelay << (attempt - 1)
+	n, err := cryptorand.Intn(int(upper))
+	if err != nil {
+		return upper
+	}
+	return time.Duration(n)
 }
 
 // InTx performs database operations inside a transaction.
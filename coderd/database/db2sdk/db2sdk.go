@@ -4,6 +4,7 @@ package db2sdk
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -113,6 +114,7 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		DisplayCondition:     param.DisplayCondition,
 	}, nil
 }
 
@@ -133,7 +135,12 @@ func User(user database.User, organizationIDs []uuid.UUID) codersdk.User {
 	}
 
 	for _, roleName := range user.RBACRoles {
-		rbacRole, _ := rbac.RoleByName(roleName)
+		rbacRole, err := rbac.RoleByName(roleName)
+		if err != nil {
+			// Custom roles are stored in the database, so only the name is
+			// known here.
+			rbacRole = rbac.Role{Name: roleName}
+		}
 		convertedUser.Roles = append(convertedUser.Roles, Role(rbacRole))
 	}
 
@@ -243,6 +250,118 @@ func OAuth2ProviderApps(dbApps []database.OAuth2ProviderApp) []codersdk.OAuth2Pr
 	return apps
 }
 
+func Webhook(dbWebhook database.Webhook) codersdk.Webhook {
+	events := make([]codersdk.WebhookEvent, 0, len(dbWebhook.Events))
+	for _, event := range dbWebhook.Events {
+		events = append(events, codersdk.WebhookEvent(event))
+	}
+	return codersdk.Webhook{
+		ID:        dbWebhook.ID,
+		URL:       dbWebhook.Url,
+		Events:    events,
+		CreatedAt: dbWebhook.CreatedAt,
+	}
+}
+
+func Webhooks(dbWebhooks []database.Webhook) []codersdk.Webhook {
+	webhooks := []codersdk.Webhook{}
+	for _, dbWebhook := range dbWebhooks {
+		webhooks = append(webhooks, Webhook(dbWebhook))
+	}
+	return webhooks
+}
+
+func WebhookDelivery(dbDelivery database.WebhookDelivery) codersdk.WebhookDelivery {
+	delivery := codersdk.WebhookDelivery{
+		ID:         dbDelivery.ID,
+		WebhookID:  dbDelivery.WebhookID,
+		Event:      codersdk.WebhookEvent(dbDelivery.Event),
+		Status:     codersdk.WebhookDeliveryStatusPending,
+		Attempts:   dbDelivery.Attempts,
+		StatusCode: dbDelivery.StatusCode.Int32,
+		LastError:  dbDelivery.LastError,
+		CreatedAt:  dbDelivery.CreatedAt,
+	}
+	if dbDelivery.DeliveredAt.Valid {
+		delivery.Status = codersdk.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = &dbDelivery.DeliveredAt.Time
+	}
+	if dbDelivery.NextAttemptAt.Valid {
+		delivery.NextAttemptAt = &dbDelivery.NextAttemptAt.Time
+	} else if !dbDelivery.DeliveredAt.Valid {
+		// Deliveries that are neither delivered nor scheduled have
+		// exhausted their attempts.
+		delivery.Status = codersdk.WebhookDeliveryStatusFailed
+	}
+	return delivery
+}
+
+func WebhookDeliveries(dbDeliveries []database.WebhookDelivery) []codersdk.WebhookDelivery {
+	deliveries := []codersdk.WebhookDelivery{}
+	for _, dbDelivery := range dbDeliveries {
+		deliveries = append(deliveries, WebhookDelivery(dbDelivery))
+	}
+	return deliveries
+}
+
+func NotificationMessage(dbMsg database.NotificationMessage) codersdk.NotificationMessage {
+	labels := map[string]string{}
+	// Labels are always written as a JSON object, and showing the message
+	// without them is preferable to failing the request.
+	_ = json.Unmarshal(dbMsg.Labels, &labels)
+	msg := codersdk.NotificationMessage{
+		ID:        dbMsg.ID,
+		UserID:    dbMsg.UserID,
+		Template:  codersdk.NotificationTemplate(dbMsg.Template),
+		Method:    codersdk.NotificationMethod(dbMsg.Method),
+		Labels:    labels,
+		Status:    codersdk.NotificationMessageStatusPending,
+		Attempts:  dbMsg.Attempts,
+		LastError: dbMsg.LastError,
+		CreatedAt: dbMsg.CreatedAt,
+	}
+	if dbMsg.SentAt.Valid {
+		msg.Status = codersdk.NotificationMessageStatusSent
+		msg.SentAt = &dbMsg.SentAt.Time
+	} else if !dbMsg.NextAttemptAt.Valid {
+		// Messages that are neither sent nor scheduled have exhausted their
+		// attempts.
+		msg.Status = codersdk.NotificationMessageStatusFailed
+	}
+	return msg
+}
+
+func NotificationMessages(dbMsgs []database.NotificationMessage) []codersdk.NotificationMessage {
+	msgs := []codersdk.NotificationMessage{}
+	for _, dbMsg := range dbMsgs {
+		msgs = append(msgs, NotificationMessage(dbMsg))
+	}
+	return msgs
+}
+
+func InboxNotification(dbNotif database.InboxNotification) codersdk.InboxNotification {
+	notif := codersdk.InboxNotification{
+		ID:        dbNotif.ID,
+		UserID:    dbNotif.UserID,
+		Template:  codersdk.NotificationTemplate(dbNotif.Template),
+		Title:     dbNotif.Title,
+		Content:   dbNotif.Content,
+		CreatedAt: dbNotif.CreatedAt,
+	}
+	if dbNotif.ReadAt.Valid {
+		notif.ReadAt = &dbNotif.ReadAt.Time
+	}
+	return notif
+}
+
+func InboxNotifications(dbNotifs []database.InboxNotification) []codersdk.InboxNotification {
+	notifs := []codersdk.InboxNotification{}
+	for _, dbNotif := range dbNotifs {
+		notifs = append(notifs, InboxNotification(dbNotif))
+	}
+	return notifs
+}
+
 func convertDisplayApps(apps []database.DisplayApp) []codersdk.DisplayApp {
 	dapps := make([]codersdk.DisplayApp, 0, len(apps))
 	for _, app := range apps {
@@ -311,6 +430,8 @@ func WorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator,
 		LifecycleState:           codersdk.WorkspaceAgentLifecycle(dbAgent.LifecycleState),
 		Subsystems:               subsystems,
 		DisplayApps:              convertDisplayApps(dbAgent.DisplayApps),
+		DiscoveredApps:           []codersdk.WorkspaceAgentDiscoveredApp{},
+		GPUs:                     []codersdk.WorkspaceAgentGPU{},
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -418,6 +539,60 @@ func Apps(dbApps []database.WorkspaceApp, agent database.WorkspaceAgent, ownerNa
 	return apps
 }
 
+// DiscoveredApps converts the listening ports of an agent into apps. Ports that
+// are already served by one of the apps of the agent are left out.
+func DiscoveredApps(dbPorts []database.WorkspaceAgentPort, dbApps []database.WorkspaceApp, agent database.WorkspaceAgent, ownerName string, workspace database.Workspace) []codersdk.WorkspaceAgentDiscoveredApp {
+	appPorts := make(map[int32]struct{}, len(dbApps))
+	for _, dbApp := range dbApps {
+		u, err := url.Parse(dbApp.Url.String)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.ParseUint(u.Port(), 10, 16)
+		if err != nil {
+			continue
+		}
+		appPorts[int32(port)] = struct{}{}
+	}
+
+	apps := make([]codersdk.WorkspaceAgentDiscoveredApp, 0)
+	for _, dbPort := range dbPorts {
+		if _, ok := appPorts[dbPort.Port]; ok {
+			continue
+		}
+		app := codersdk.WorkspaceAgentDiscoveredApp{
+			Port:         uint16(dbPort.Port),
+			ProcessName:  dbPort.ProcessName,
+			DiscoveredAt: dbPort.DiscoveredAt,
+		}
+		if agent.Name != "" && ownerName != "" && workspace.Name != "" {
+			app.SubdomainName = appurl.ApplicationURL{
+				AppSlugOrPort: strconv.Itoa(int(dbPort.Port)),
+				AgentName:     agent.Name,
+				WorkspaceName: workspace.Name,
+				Username:      ownerName,
+			}.String()
+		}
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+// WorkspaceAgentGPUs converts the GPUs an agent detected in its workspace.
+func WorkspaceAgentGPUs(dbGPUs []database.WorkspaceAgentGPU) []codersdk.WorkspaceAgentGPU {
+	gpus := make([]codersdk.WorkspaceAgentGPU, 0, len(dbGPUs))
+	for _, dbGPU := range dbGPUs {
+		gpus = append(gpus, codersdk.WorkspaceAgentGPU{
+			Index:         dbGPU.Index,
+			Vendor:        dbGPU.Vendor,
+			Model:         dbGPU.Model,
+			MemoryBytes:   dbGPU.MemoryBytes,
+			DriverVersion: dbGPU.DriverVersion,
+		})
+	}
+	return gpus
+}
+
 func ProvisionerDaemon(dbDaemon database.ProvisionerDaemon) codersdk.ProvisionerDaemon {
 	result := codersdk.ProvisionerDaemon{
 		ID:         dbDaemon.ID,
@@ -428,8 +603,22 @@ func ProvisionerDaemon(dbDaemon database.ProvisionerDaemon) codersdk.Provisioner
 		Version:    dbDaemon.Version,
 		APIVersion: dbDaemon.APIVersion,
 	}
+	if dbDaemon.OrganizationID.Valid {
+		result.OrganizationID = &dbDaemon.OrganizationID.UUID
+	}
 	for _, provisionerType := range dbDaemon.Provisioners {
 		result.Provisioners = append(result.Provisioners, codersdk.ProvisionerType(provisionerType))
 	}
 	return result
 }
+
+func ProvisionerKey(key database.ProvisionerKey) codersdk.ProvisionerKey {
+	return codersdk.ProvisionerKey{
+		ID:             key.ID,
+		CreatedAt:      key.CreatedAt,
+		OrganizationID: key.OrganizationID,
+		Name:           key.Name,
+		Tags:           key.Tags,
+		RotatedAt:      codersdk.NullTime{NullTime: key.RotatedAt},
+	}
+}
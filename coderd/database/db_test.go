@@ -27,7 +27,7 @@ func TestSerializedRetry(t *testing.T) {
 	db := database.New(sqlDB)
 
 	called := 0
-	txOpts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	txOpts := &database.TxOptions{Isolation: sql.LevelSerializable}
 	err := db.InTx(func(tx database.Store) error {
 		// Test nested error
 		return tx.InTx(func(tx database.Store) error {
@@ -47,6 +47,32 @@ func TestSerializedRetry(t *testing.T) {
 	require.Equal(t, called, 3, "should retry 3 times")
 }
 
+func TestRetryOnConflict(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	sqlDB := testSQLDB(t)
+	db := database.New(sqlDB)
+
+	called := 0
+	txOpts := &database.TxOptions{RetryOnConflict: true}
+	err := db.InTx(func(tx database.Store) error {
+		called++
+		if called < 3 {
+			return &pq.Error{
+				Code:    "40P01",
+				Message: "deadlock_detected",
+			}
+		}
+		return nil
+	}, txOpts)
+	require.NoError(t, err)
+	require.Equal(t, 3, called, "should retry until success")
+	require.Equal(t, 3, txOpts.ExecutionCount())
+}
+
 func TestNestedInTx(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
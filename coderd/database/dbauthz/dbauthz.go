@@ -21,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi/httpapiconstraints"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -125,6 +126,28 @@ func New(db database.Store, authorizer rbac.Authorizer, logger slog.Logger, acs
 	}
 }
 
+func (q *querier) DeleteUnknownLoginFailure(ctx context.Context, arg database.DeleteUnknownLoginFailureParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteUnknownLoginFailure(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentAuthTokenByIDForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// The row is locked to replace the token, so this needs the same
+	// permission as updating it.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return uuid.Nil, err
+	}
+
+	return q.db.GetWorkspaceAgentAuthTokenByIDForUpdate(ctx, id)
+}
+
 func (q *querier) Wrappers() []string {
 	return append(q.db.Wrappers(), wrapname)
 }
@@ -163,6 +186,7 @@ var (
 				Site: rbac.Permissions(map[string][]rbac.Action{
 					// TODO: Add ProvisionerJob resource type.
 					rbac.ResourceFile.Type:           {rbac.ActionRead},
+					rbac.ResourceOrganization.Type:   {rbac.ActionRead},
 					rbac.ResourceSystem.Type:         {rbac.WildcardSymbol},
 					rbac.ResourceTemplate.Type:       {rbac.ActionRead, rbac.ActionUpdate},
 					rbac.ResourceUser.Type:           {rbac.ActionRead},
@@ -190,6 +214,7 @@ var (
 					rbac.ResourceWorkspace.Type:      {rbac.ActionRead, rbac.ActionUpdate},
 					rbac.ResourceWorkspaceBuild.Type: {rbac.ActionRead, rbac.ActionUpdate, rbac.ActionDelete},
 					rbac.ResourceUser.Type:           {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:         {rbac.ActionRead},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -224,21 +249,24 @@ var (
 				Name:        "system",
 				DisplayName: "Coder",
 				Site: rbac.Permissions(map[string][]rbac.Action{
-					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
-					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
-					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
-					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
-					rbac.ResourceOrganizationMember.Type: {rbac.ActionCreate},
-					rbac.ResourceOrgRoleAssignment.Type:  {rbac.ActionCreate},
-					rbac.ResourceProvisionerDaemon.Type:  {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceUser.Type:               {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceUserData.Type:           {rbac.ActionCreate, rbac.ActionUpdate},
-					rbac.ResourceWorkspace.Type:          {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceBuild.Type:     {rbac.ActionUpdate},
-					rbac.ResourceWorkspaceExecution.Type: {rbac.ActionCreate},
-					rbac.ResourceWorkspaceProxy.Type:     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWildcard.Type:                   {rbac.ActionRead},
+					rbac.ResourceAPIKey.Type:                     {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceGroup.Type:                      {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceOAuth2ProviderAppCodeToken.Type: {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceOAuth2ProviderAppSecret.Type:    {rbac.ActionUpdate},
+					rbac.ResourceRoleAssignment.Type:             {rbac.ActionCreate, rbac.ActionDelete},
+					rbac.ResourceSystem.Type:                     {rbac.WildcardSymbol},
+					rbac.ResourceOrganization.Type:               {rbac.ActionCreate},
+					rbac.ResourceOrganizationMember.Type:         {rbac.ActionCreate},
+					rbac.ResourceOrgRoleAssignment.Type:          {rbac.ActionCreate},
+					rbac.ResourceProvisionerDaemon.Type:          {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceUser.Type:                       {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceUserData.Type:                   {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceWebhook.Type:                    {rbac.ActionCreate},
+					rbac.ResourceWorkspace.Type:                  {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceBuild.Type:             {rbac.ActionUpdate},
+					rbac.ResourceWorkspaceExecution.Type:         {rbac.ActionCreate},
+					rbac.ResourceWorkspaceProxy.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
 				}),
 				Org:  map[string][]rbac.Permission{},
 				User: []rbac.Permission{},
@@ -515,7 +543,7 @@ func (q *querier) Ping(ctx context.Context) (time.Duration, error) {
 }
 
 // InTx runs the given function in a transaction.
-func (q *querier) InTx(function func(querier database.Store) error, txOpts *sql.TxOptions) error {
+func (q *querier) InTx(function func(querier database.Store) error, txOpts *database.TxOptions) error {
 	return q.db.InTx(func(tx database.Store) error {
 		// Wrap the transaction store in a querier.
 		wrapped := New(tx, q.auth, q.log, q.acs)
@@ -559,6 +587,7 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	}
 
 	grantedRoles := append(added, removed...)
+	customRoles := make(map[string]struct{})
 	// Validate that the roles being assigned are valid.
 	for _, r := range grantedRoles {
 		_, isOrgRole := rbac.IsOrgRole(r)
@@ -569,9 +598,30 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 			return xerrors.Errorf("Must only update site wide roles")
 		}
 
-		// All roles should be valid roles
+		// Roles that are not built in must be custom site wide roles.
 		if _, err := rbac.RoleByName(r); err != nil {
-			return xerrors.Errorf("%q is not a supported role", r)
+			if isOrgRole {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
+			customRoles[r] = struct{}{}
+		}
+	}
+
+	if len(customRoles) > 0 {
+		lookup := make([]string, 0, len(customRoles))
+		for r := range customRoles {
+			lookup = append(lookup, r)
+		}
+		found, err := q.db.CustomRolesByName(ctx, lookup)
+		if err != nil {
+			return xerrors.Errorf("fetch custom roles: %w", err)
+		}
+		for _, r := range lookup {
+			if !slices.ContainsFunc(found, func(role database.CustomRole) bool {
+				return role.Name == r
+			}) {
+				return xerrors.Errorf("%q is not a supported role", r)
+			}
 		}
 	}
 
@@ -588,7 +638,13 @@ func (q *querier) canAssignRoles(ctx context.Context, orgID *uuid.UUID, added, r
 	}
 
 	for _, roleName := range grantedRoles {
-		if !rbac.CanAssignRole(actor.Roles, roleName) {
+		checkName := roleName
+		if _, ok := customRoles[roleName]; ok {
+			// Custom roles are not known to the rbac package, so check
+			// against the placeholder for all of them.
+			checkName = rbac.CustomSiteRole()
+		}
+		if !rbac.CanAssignRole(actor.Roles, checkName) {
 			return xerrors.Errorf("not authorized to assign role %q", roleName)
 		}
 	}
@@ -656,10 +712,30 @@ func authorizedTemplateVersionFromJob(ctx context.Context, q *querier, job datab
 	}
 }
 
+// workspaceIDFromDryRunJob returns the workspace a workspace build dry-run job
+// plans for.
+func workspaceIDFromDryRunJob(job database.ProvisionerJob) (uuid.UUID, error) {
+	tmp := struct {
+		WorkspaceID uuid.UUID `json:"workspace_id"`
+	}{}
+	err := json.Unmarshal(job.Input, &tmp)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("workspace dry-run unmarshal: %w", err)
+	}
+	return tmp.WorkspaceID, nil
+}
+
 func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
 
+func (q *querier) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireNotificationMessages(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -668,6 +744,13 @@ func (q *querier) AcquireProvisionerJob(ctx context.Context, arg database.Acquir
 	return q.db.AcquireProvisionerJob(ctx, arg)
 }
 
+func (q *querier) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.AcquireWebhookDeliveries(ctx, arg)
+}
+
 func (q *querier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	fetch := func(ctx context.Context, arg database.ActivityBumpWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
@@ -725,10 +808,42 @@ func (q *querier) CleanTailnetTunnels(ctx context.Context) error {
 	return q.db.CleanTailnetTunnels(ctx)
 }
 
+func (q *querier) CountLoginFailuresByUserID(ctx context.Context, arg database.CountLoginFailuresByUserIDParams) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.CountLoginFailuresByUserID(ctx, arg)
+}
+
+func (q *querier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return 0, err
+	}
+	return q.db.CountUnreadInboxNotificationsByUserID(ctx, userID)
+}
+
+func (q *querier) CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return 0, err
+	}
+	return q.db.CountUnusedUserRecoveryCodes(ctx, userID)
+}
+
+func (q *querier) CustomRolesByName(ctx context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceRoleAssignment); err != nil {
+		return nil, err
+	}
+	return q.db.CustomRolesByName(ctx, lookupRoles)
+}
+
 func (q *querier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	return deleteQ(q.log, q.auth, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByID)(ctx, id)
 }
 
+func (q *querier) DeleteAPIKeyByIDReturning(ctx context.Context, id string) (database.APIKey, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetAPIKeyByID, q.db.DeleteAPIKeyByIDReturning)(ctx, id)
+}
+
 func (q *querier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
 	// TODO: This is not 100% correct because it omits apikey IDs.
 	err := q.authorizeContext(ctx, rbac.ActionDelete,
@@ -739,6 +854,16 @@ func (q *querier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) e
 	return q.db.DeleteAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteAPIKeysByUserIDReturning(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	// TODO: This is not 100% correct because it omits apikey IDs.
+	err := q.authorizeContext(ctx, rbac.ActionDelete,
+		rbac.ResourceAPIKey.WithOwner(userID.String()))
+	if err != nil {
+		return nil, err
+	}
+	return q.db.DeleteAPIKeysByUserIDReturning(ctx, userID)
+}
+
 func (q *querier) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -770,6 +895,13 @@ func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	return q.db.DeleteCoordinator(ctx, id)
 }
 
+func (q *querier) DeleteExpiredTwoFactorChallenges(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteExpiredTwoFactorChallenges(ctx, beforeTime)
+}
+
 func (q *querier) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	return deleteQ(q.log, q.auth, func(ctx context.Context, arg database.DeleteExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 		//nolint:gosimple
@@ -814,6 +946,13 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteLoginFailure(ctx context.Context, arg database.DeleteLoginFailureParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteLoginFailure(ctx, arg)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return err
@@ -821,6 +960,10 @@ func (q *querier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID)
 	return q.db.DeleteOAuth2ProviderAppByID(ctx, id)
 }
 
+func (q *querier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetchAndQuery(q.log, q.auth, rbac.ActionDelete, q.db.GetOAuth2ProviderAppCodeByID, q.db.DeleteOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
 func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOAuth2ProviderAppSecret); err != nil {
 		return err
@@ -828,6 +971,20 @@ func (q *querier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid
 	return q.db.DeleteOAuth2ProviderAppSecretByID(ctx, id)
 }
 
+func (q *querier) DeleteOldGroupSyncRuns(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldGroupSyncRuns(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldLoginFailures(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldLoginFailures(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -835,6 +992,20 @@ func (q *querier) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	return q.db.DeleteOldProvisionerDaemons(ctx)
 }
 
+func (q *querier) DeleteOldRateLimitCounters(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldRateLimitCounters(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOldUserActivity(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldUserActivity(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -842,6 +1013,13 @@ func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentLogs(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentMetadataHistory(ctx, beforeTime)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -849,6 +1027,67 @@ func (q *querier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	return q.db.DeleteOldWorkspaceAgentStats(ctx)
 }
 
+func (q *querier) DeleteOldWorkspaceEvents(ctx context.Context, beforeTime time.Time) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceEvents(ctx, beforeTime)
+}
+
+func (q *querier) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	// Deleting an organization is not scoped to the organization, so
+	// organization admins can't delete their own organization.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceOrganization.WithID(id)); err != nil {
+		return err
+	}
+	return q.db.DeleteOrganization(ctx, id)
+}
+
+func (q *querier) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	// Authorized fetch will check that the actor has read access to the org member.
+	member, err := q.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+		OrganizationID: arg.OrganizationID,
+		UserID:         arg.UserID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Removing a member removes all of their roles in the organization.
+	removed := append(member.Roles, rbac.RoleOrgMember(arg.OrganizationID))
+	err = q.canAssignRoles(ctx, &arg.OrganizationID, []string{}, removed)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, member); err != nil {
+		return err
+	}
+	return q.db.DeleteOrganizationMember(ctx, arg)
+}
+
+func (q *querier) DeleteOrganizationTemplateVariableValue(ctx context.Context, arg database.DeleteOrganizationTemplateVariableValueParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.DeleteOrganizationTemplateVariableValue(ctx, arg)
+}
+
+func (q *querier) DeletePasskeyByID(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetPasskeyByID, q.db.DeletePasskeyByID)(ctx, id)
+}
+
+func (q *querier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+}
+
+func (q *querier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	return deleteQ(q.log, q.auth, q.db.GetProvisionerKeyByID, q.db.DeleteProvisionerKey)(ctx, id)
+}
+
 func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -856,6 +1095,14 @@ func (q *querier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt tim
 	return q.db.DeleteReplicasUpdatedBefore(ctx, updatedAt)
 }
 
+func (q *querier) DeleteSessionRecordingByID(ctx context.Context, id uuid.UUID) error {
+	// Recordings are only deleted by the retention purge.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteSessionRecordingByID(ctx, id)
+}
+
 func (q *querier) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
 		return database.DeleteTailnetAgentRow{}, err
@@ -891,6 +1138,107 @@ func (q *querier) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) DeleteTemplateVariableValue(ctx context.Context, arg database.DeleteTemplateVariableValueParams) error {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVariableValue(ctx, arg)
+}
+
+func (q *querier) DeleteTemplateVersionPreset(ctx context.Context, arg database.DeleteTemplateVersionPresetParams) error {
+	// An actor is allowed to manage the presets of a template version if they
+	// are authorized to update the template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, arg.TemplateVersionID)
+	if err != nil {
+		return err
+	}
+	var obj rbac.Objecter
+	if !tv.TemplateID.Valid {
+		obj = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		tpl, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+		if err != nil {
+			return err
+		}
+		obj = tpl
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, obj); err != nil {
+		return err
+	}
+	return q.db.DeleteTemplateVersionPreset(ctx, arg)
+}
+
+func (q *querier) DeleteTwoFactorChallengeByID(ctx context.Context, id uuid.UUID) (database.TwoFactorChallenge, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return database.TwoFactorChallenge{}, err
+	}
+	return q.db.DeleteTwoFactorChallengeByID(ctx, id)
+}
+
+func (q *querier) DeleteTwoFactorPolicyByRole(ctx context.Context, role string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceDeploymentValues); err != nil {
+		return err
+	}
+	return q.db.DeleteTwoFactorPolicyByRole(ctx, role)
+}
+
+func (q *querier) DeleteUserLockout(ctx context.Context, userID uuid.UUID) error {
+	// Unlocking a user is an administrative change to their account.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserLockout(ctx, userID)
+}
+
+func (q *querier) DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return err
+	}
+	return q.db.DeleteUserRecoveryCodesByUserID(ctx, userID)
+}
+
+func (q *querier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceWebhook); err != nil {
+		return err
+	}
+	return q.db.DeleteWebhookByID(ctx, id)
+}
+
+func (q *querier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx context.Context, agentID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx, agentID)
+}
+
+func (q *querier) DeleteWorkspaceFavorite(ctx context.Context, arg database.DeleteWorkspaceFavoriteParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceFavorite(ctx, arg)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -911,6 +1259,10 @@ func (q *querier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Tim
 	return fetchWithPostFilter(q.auth, q.db.GetAPIKeysLastUsedAfter)(ctx, lastUsed)
 }
 
+func (q *querier) GetActiveSessionsByUserID(ctx context.Context, arg database.GetActiveSessionsByUserIDParams) ([]database.APIKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetActiveSessionsByUserID)(ctx, arg)
+}
+
 func (q *querier) GetActiveUserCount(ctx context.Context) (int64, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return 0, err
@@ -964,6 +1316,14 @@ func (q *querier) GetApplicationName(ctx context.Context) (string, error) {
 	return q.db.GetApplicationName(ctx)
 }
 
+func (q *querier) GetAuditLogsKeyset(ctx context.Context, arg database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	// Like GetAuditLogsOffset, only check the global audit log permission once.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsKeyset(ctx, arg)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// To optimize audit logs, we only check the global audit log permission once.
 	// This is because we expect a large unbounded set of audit logs, and applying a SQL
@@ -981,6 +1341,14 @@ func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUI
 	return q.db.GetAuthorizationUserRoles(ctx, userID)
 }
 
+func (q *querier) GetConnectionLogsOffset(ctx context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	// Like GetAuditLogsOffset, only check the global audit log permission once.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetConnectionLogsOffset(ctx, arg)
+}
+
 func (q *querier) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1021,6 +1389,20 @@ func (q *querier) GetDeploymentWorkspaceStats(ctx context.Context) (database.Get
 	return q.db.GetDeploymentWorkspaceStats(ctx)
 }
 
+func (q *querier) GetDriftedWorkspaceCount(ctx context.Context) (int64, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.GetDriftedWorkspaceCount(ctx)
+}
+
+func (q *querier) GetExpiredSessionRecordings(ctx context.Context, arg database.GetExpiredSessionRecordingsParams) ([]database.SessionRecording, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetExpiredSessionRecordings(ctx, arg)
+}
+
 func (q *querier) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	return fetch(q.log, q.auth, q.db.GetExternalAuthLink)(ctx, arg)
 }
@@ -1029,6 +1411,13 @@ func (q *querier) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.
 	return fetchWithPostFilter(q.auth, q.db.GetExternalAuthLinksByUserID)(ctx, userID)
 }
 
+func (q *querier) GetFavoriteWorkspaceIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetFavoriteWorkspaceIDsByUserID(ctx, userID)
+}
+
 func (q *querier) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	file, err := q.db.GetFileByHashAndCreator(ctx, arg)
 	if err != nil {
@@ -1087,6 +1476,18 @@ func (q *querier) GetGroupMembers(ctx context.Context, id uuid.UUID) ([]database
 	return q.db.GetGroupMembers(ctx, id)
 }
 
+func (q *querier) GetGroupSyncRuns(ctx context.Context, arg database.GetGroupSyncRunsParams) ([]database.GroupSyncRun, error) {
+	// Sync runs are only exposed on the debug routes.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceDebugInfo); err != nil {
+		return nil, err
+	}
+	return q.db.GetGroupSyncRuns(ctx, arg)
+}
+
+func (q *querier) GetGroupsByOrganizationAndUserID(ctx context.Context, arg database.GetGroupsByOrganizationAndUserIDParams) ([]database.Group, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetGroupsByOrganizationAndUserID)(ctx, arg)
+}
+
 func (q *querier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetGroupsByOrganizationID)(ctx, organizationID)
 }
@@ -1104,6 +1505,21 @@ func (q *querier) GetHungProvisionerJobs(ctx context.Context, hungSince time.Tim
 	return q.db.GetHungProvisionerJobs(ctx, hungSince)
 }
 
+func (q *querier) GetInactiveUsers(ctx context.Context, inactiveSince time.Time) ([]database.GetInactiveUsersRow, error) {
+	// The report lists every user, so it needs site-wide read access to users.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUser); err != nil {
+		return nil, err
+	}
+	return q.db.GetInactiveUsers(ctx, inactiveSince)
+}
+
+func (q *querier) GetInboxNotificationsByUserID(ctx context.Context, arg database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetInboxNotificationsByUserID(ctx, arg)
+}
+
 func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1111,10 +1527,24 @@ func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	return q.db.GetLastUpdateCheck(ctx)
 }
 
-func (q *querier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
-	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
-		return database.WorkspaceBuild{}, err
-	}
+func (q *querier) GetLatestWorkspaceAgentStatByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentStat, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, agentID)
+	if err != nil {
+		return database.WorkspaceAgentStat{}, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace)
+	if err != nil {
+		return database.WorkspaceAgentStat{}, err
+	}
+
+	return q.db.GetLatestWorkspaceAgentStatByAgentID(ctx, agentID)
+}
+
+func (q *querier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceBuild{}, err
+	}
 	return q.db.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
 }
 
@@ -1142,6 +1572,13 @@ func (q *querier) GetLicenseByID(ctx context.Context, id int32) (database.Licens
 	return fetch(q.log, q.auth, q.db.GetLicenseByID)(ctx, id)
 }
 
+func (q *querier) GetLicenseGracePeriodStartedAt(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return "", err
+	}
+	return q.db.GetLicenseGracePeriodStartedAt(ctx)
+}
+
 func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.License, error) {
 		return q.db.GetLicenses(ctx)
@@ -1149,11 +1586,32 @@ func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) GetLoginFailure(ctx context.Context, arg database.GetLoginFailureParams) (database.LoginFailure, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.LoginFailure{}, err
+	}
+	return q.db.GetLoginFailure(ctx, arg)
+}
+
 func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetNotificationMessagesByUserID(ctx context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationMessagesByUserID(ctx, arg)
+}
+
+func (q *querier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetNotificationPreferencesByUserID(ctx, userID)
+}
+
 func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return database.OAuth2ProviderApp{}, err
@@ -1161,6 +1619,14 @@ func (q *querier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (d
 	return q.db.GetOAuth2ProviderAppByID(ctx, id)
 }
 
+func (q *querier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByID)(ctx, id)
+}
+
+func (q *querier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppCode, error) {
+	return fetch(q.log, q.auth, q.db.GetOAuth2ProviderAppCodeByPrefix)(ctx, secretPrefix)
+}
+
 func (q *querier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -1175,6 +1641,22 @@ func (q *querier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID
 	return q.db.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
 }
 
+func (q *querier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
+	token, err := q.db.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	// The token is owned by the user the API key belongs to.
+	key, err := q.db.GetAPIKeyByID(ctx, token.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return token, nil
+}
+
 func (q *querier) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return []database.OAuth2ProviderApp{}, err
@@ -1200,17 +1682,77 @@ func (q *querier) GetOrganizationByName(ctx context.Context, name string) (datab
 func (q *querier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetOrganizationIDsByMemberIDsRow, error) {
 	// TODO: This should be rewritten to return a list of database.OrganizationMember for consistent RBAC objects.
 	// Currently this row returns a list of org ids per user, which is challenging to check against the RBAC system.
-	return fetchWithPostFilter(q.auth, q.db.GetOrganizationIDsByMemberIDs)(ctx, ids)
+	rows, err := fetchWithPostFilter(q.auth, q.db.GetOrganizationIDsByMemberIDs)(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Being able to read a user does not mean being able to read all of their
+	// memberships. Only return the organizations whose membership the actor can
+	// read, so organization admins don't see other organizations.
+	act, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, NoActorError
+	}
+	memberships := make([]database.OrganizationMember, 0, len(rows))
+	for _, row := range rows {
+		for _, orgID := range row.OrganizationIDs {
+			memberships = append(memberships, database.OrganizationMember{
+				UserID:         row.UserID,
+				OrganizationID: orgID,
+			})
+		}
+	}
+	memberships, err = rbac.Filter(ctx, q.auth, act, rbac.ActionRead, memberships)
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[uuid.UUID][]uuid.UUID, len(rows))
+	for _, membership := range memberships {
+		visible[membership.UserID] = append(visible[membership.UserID], membership.OrganizationID)
+	}
+	for i := range rows {
+		rows[i].OrganizationIDs = append([]uuid.UUID{}, visible[rows[i].UserID]...)
+	}
+	return rows, nil
 }
 
 func (q *querier) GetOrganizationMemberByUserID(ctx context.Context, arg database.GetOrganizationMemberByUserIDParams) (database.OrganizationMember, error) {
 	return fetch(q.log, q.auth, q.db.GetOrganizationMemberByUserID)(ctx, arg)
 }
 
+func (q *querier) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOrganizationMembers)(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetOrganizationMembershipsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (database.OrganizationQuota, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceOrganization.WithID(organizationID).InOrg(organizationID)); err != nil {
+		return database.OrganizationQuota{}, err
+	}
+	return q.db.GetOrganizationQuota(ctx, organizationID)
+}
+
+func (q *querier) GetOrganizationResourceCostRollup(ctx context.Context, arg database.GetOrganizationResourceCostRollupParams) ([]database.GetOrganizationResourceCostRollupRow, error) {
+	// The rollup covers every workspace in the organization.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWorkspace.InOrg(arg.OrganizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetOrganizationResourceCostRollup(ctx, arg)
+}
+
+func (q *querier) GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
+	// The values may be secrets, so only those who can update the templates of
+	// the organization can read them.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.InOrg(organizationID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetOrganizationTemplateVariableValues(ctx, organizationID)
+}
+
 func (q *querier) GetOrganizations(ctx context.Context) ([]database.Organization, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.Organization, error) {
 		return q.db.GetOrganizations(ctx)
@@ -1222,6 +1764,13 @@ func (q *querier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID
 	return fetchWithPostFilter(q.auth, q.db.GetOrganizationsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetOrphanedProvisionerJobs(ctx context.Context, staleSince time.Time) ([]database.ProvisionerJob, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetOrphanedProvisionerJobs(ctx, staleSince)
+}
+
 func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	version, err := q.db.GetTemplateVersionByJobID(ctx, jobID)
 	if err != nil {
@@ -1243,6 +1792,17 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPasskeyByID(ctx context.Context, id uuid.UUID) (database.Passkey, error) {
+	return fetch(q.log, q.auth, q.db.GetPasskeyByID)(ctx, id)
+}
+
+func (q *querier) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.Passkey, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetPasskeysByUserID(ctx, userID)
+}
+
 func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	// An actor can read the previous template version if they can read the related template.
 	// If no linked template exists, we check if the actor can read *a* template.
@@ -1264,6 +1824,10 @@ func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.Provisi
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetProvisionerDaemonsByOrganization)(ctx, organizationID)
+}
+
 func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	job, err := q.db.GetProvisionerJobByID(ctx, id)
 	if err != nil {
@@ -1278,6 +1842,17 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 		if err != nil {
 			return database.ProvisionerJob{}, err
 		}
+	case database.ProvisionerJobTypeWorkspaceBuildDryRun:
+		// Authorized call to get the workspace. If we can read the workspace,
+		// we can read its dry-runs.
+		workspaceID, err := workspaceIDFromDryRunJob(job)
+		if err != nil {
+			return database.ProvisionerJob{}, err
+		}
+		_, err = q.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return database.ProvisionerJob{}, err
+		}
 	case database.ProvisionerJobTypeTemplateVersionDryRun, database.ProvisionerJobTypeTemplateVersionImport:
 		// Authorized call to get template version.
 		_, err := authorizedTemplateVersionFromJob(ctx, q, job)
@@ -1291,6 +1866,29 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 	return job, nil
 }
 
+func (q *querier) GetProvisionerJobIDsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobIDsToArchiveLogs(ctx, arg)
+}
+
+func (q *querier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	// Authorized read on job lets the actor also read the logs.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+	return q.db.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+}
+
+func (q *querier) GetProvisionerJobLogArchiveStats(ctx context.Context) (database.GetProvisionerJobLogArchiveStatsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetProvisionerJobLogArchiveStatsRow{}, err
+	}
+	return q.db.GetProvisionerJobLogArchiveStats(ctx)
+}
+
 // TODO: we need to add a provisioner job resource
 func (q *querier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -1312,6 +1910,23 @@ func (q *querier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 }
 
+// GetProvisionerKeyByHashedSecret authenticates provisioner daemons, which have
+// no actor until the key is found.
+func (q *querier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+	return q.db.GetProvisionerKeyByHashedSecret(ctx, hashedSecret)
+}
+
+func (q *querier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByID)(ctx, id)
+}
+
+func (q *querier) GetProvisionerKeyByName(ctx context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	return fetch(q.log, q.auth, q.db.GetProvisionerKeyByName)(ctx, arg)
+}
+
 func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	// Authorized read on job lets the actor also read the logs.
 	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
@@ -1329,6 +1944,14 @@ func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID
 	return q.db.GetQuotaAllowanceForUser(ctx, userID)
 }
 
+func (q *querier) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	// The consumed quota covers every workspace in the organization.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWorkspace.InOrg(organizationID)); err != nil {
+		return -1, err
+	}
+	return q.db.GetQuotaConsumedForOrganization(ctx, organizationID)
+}
+
 func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
 	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
 	if err != nil {
@@ -1351,11 +1974,36 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) GetRunningWorkspaceCounts(ctx context.Context, arg database.GetRunningWorkspaceCountsParams) (database.GetRunningWorkspaceCountsRow, error) {
+	// The counts cover the workspaces of other users, so they are only used
+	// by the system when enforcing quotas.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetRunningWorkspaceCountsRow{}, err
+	}
+	return q.db.GetRunningWorkspaceCounts(ctx, arg)
+}
+
 func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetServiceBanner(ctx)
 }
 
+func (q *querier) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	// Session recordings are audit records, so they are only readable by those
+	// who can read the audit logs.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return database.SessionRecording{}, err
+	}
+	return q.db.GetSessionRecordingByID(ctx, id)
+}
+
+func (q *querier) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceTailnetCoordinator); err != nil {
 		return nil, err
@@ -1430,6 +2078,23 @@ func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.
 	return q.db.GetTemplateAverageBuildTime(ctx, arg)
 }
 
+func (q *querier) GetTemplateBuildInsights(ctx context.Context, arg database.GetTemplateBuildInsightsParams) (database.GetTemplateBuildInsightsRow, error) {
+	// Used by the template insights endpoint.
+	// For auditors, check read template_insights, and fall back to update template.
+	if authErr := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceTemplateInsights); authErr != nil {
+		// Do not leak the existence of the template to callers that are
+		// not allowed to read insights.
+		template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+		if err != nil {
+			return database.GetTemplateBuildInsightsRow{}, authErr
+		}
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+			return database.GetTemplateBuildInsightsRow{}, authErr
+		}
+	}
+	return q.db.GetTemplateBuildInsights(ctx, arg)
+}
+
 func (q *querier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	return fetch(q.log, q.auth, q.db.GetTemplateByID)(ctx, id)
 }
@@ -1523,6 +2188,19 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVariableValue, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	// The values may be secrets, so only those who can update the template can
+	// read them.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVariableValuesByTemplateID(ctx, templateID)
+}
+
 func (q *querier) GetTemplateVersionByID(ctx context.Context, tvid uuid.UUID) (database.TemplateVersion, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, tvid)
 	if err != nil {
@@ -1598,6 +2276,30 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	// An actor can read template version presets if they can read the related template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var object rbac.Objecter
+	template, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		object = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		object = tv.RBACObject(template)
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionRead, object); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionPresets(ctx, templateVersionID)
+}
+
 func (q *querier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
 	if err != nil {
@@ -1667,6 +2369,27 @@ func (q *querier) GetTemplatesWithFilter(ctx context.Context, arg database.GetTe
 	return q.db.GetAuthorizedTemplates(ctx, arg, prep)
 }
 
+func (q *querier) GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]database.ProvisionerJob, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetTimedOutProvisionerJobs(ctx, now)
+}
+
+func (q *querier) GetTwoFactorPolicies(ctx context.Context) ([]database.TwoFactorPolicy, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceDeploymentValues); err != nil {
+		return nil, err
+	}
+	return q.db.GetTwoFactorPolicies(ctx)
+}
+
+func (q *querier) GetTwoFactorPolicyByRole(ctx context.Context, role string) (database.TwoFactorPolicy, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceDeploymentValues); err != nil {
+		return database.TwoFactorPolicy{}, err
+	}
+	return q.db.GetTwoFactorPolicyByRole(ctx, role)
+}
+
 func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1674,6 +2397,27 @@ func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License,
 	return q.db.GetUnexpiredLicenses(ctx)
 }
 
+func (q *querier) GetUnknownLoginFailure(ctx context.Context, arg database.GetUnknownLoginFailureParams) (database.UnknownLoginFailure, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.UnknownLoginFailure{}, err
+	}
+	return q.db.GetUnknownLoginFailure(ctx, arg)
+}
+
+func (q *querier) GetUnknownLoginFailureTotals(ctx context.Context, arg database.GetUnknownLoginFailureTotalsParams) (database.GetUnknownLoginFailureTotalsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.GetUnknownLoginFailureTotalsRow{}, err
+	}
+	return q.db.GetUnknownLoginFailureTotals(ctx, arg)
+}
+
+func (q *querier) GetUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]database.UserRecoveryCode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(userID.String()).WithID(userID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUnusedUserRecoveryCodes(ctx, userID)
+}
+
 func (q *querier) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	// Used by insights endpoints. Need to check both for auditors and for regular users with template acl perms.
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceTemplateInsights); err != nil {
@@ -1754,6 +2498,25 @@ func (q *querier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([
 	return q.db.GetUserLinksByUserID(ctx, userID)
 }
 
+func (q *querier) GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	return fetch(q.log, q.auth, q.db.GetUserLockoutByUserID)(ctx, userID)
+}
+
+func (q *querier) GetUserLockouts(ctx context.Context, now time.Time) ([]database.GetUserLockoutsRow, error) {
+	// Like the inactive users report, this lists users from the whole site.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUser); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserLockouts(ctx, now)
+}
+
+func (q *querier) GetUserSCIMExternalIDByExternalID(ctx context.Context, externalID string) (database.UserSCIMExternalID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.UserSCIMExternalID{}, err
+	}
+	return q.db.GetUserSCIMExternalIDByExternalID(ctx, externalID)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceUser.Type)
@@ -1775,6 +2538,41 @@ func (q *querier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]databas
 	return q.db.GetUsersByIDs(ctx, ids)
 }
 
+func (q *querier) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.GetWebhookByID(ctx, id)
+}
+
+func (q *querier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhookDeliveriesByWebhookID(ctx, arg)
+}
+
+func (q *querier) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.GetWebhookDeliveryByID(ctx, id)
+}
+
+func (q *querier) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhooks(ctx)
+}
+
+func (q *querier) GetWebhooksByEvent(ctx context.Context, event string) ([]database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceWebhook); err != nil {
+		return nil, err
+	}
+	return q.db.GetWebhooksByEvent(ctx, event)
+}
+
 func (q *querier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	// This is a system function
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
@@ -1806,6 +2604,20 @@ func (q *querier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanc
 	return agent, nil
 }
 
+func (q *querier) GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentCountsByLifecycleState(ctx)
+}
+
+func (q *querier) GetWorkspaceAgentGPUsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentGPU, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentGPUsByAgentIDs(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, id)
 	if err != nil {
@@ -1843,6 +2655,45 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, arg database.Ge
 	return q.db.GetWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	return q.db.GetWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentPortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentPortSharesByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceAgentPortsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentPort, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentPortsByAgentIDs(ctx, ids)
+}
+
+func (q *querier) GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	return q.db.GetWorkspaceAgentResourceUsageAndLabels(ctx, createdAfter)
+}
+
 func (q *querier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1892,7 +2743,14 @@ func (q *querier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg datab
 	return q.db.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
 }
 
-func (q *querier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
+func (q *querier) GetWorkspaceAppCountsByHealth(ctx context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAppCountsByHealth(ctx)
+}
+
+func (q *querier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
 	if _, err := q.GetWorkspaceByAgentID(ctx, agentID); err != nil {
 		return nil, err
 	}
@@ -1990,6 +2848,35 @@ func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceApp
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceCountsByTemplateAndStatus(ctx)
+}
+
+func (q *querier) GetWorkspaceDriftByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDrift, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceDrift{}, err
+	}
+	return q.db.GetWorkspaceDriftByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceIDsForDriftCheck(ctx, lastCheckBefore)
+}
+
+func (q *querier) GetWorkspaceParameterChanges(ctx context.Context, arg database.GetWorkspaceParameterChangesParams) ([]database.GetWorkspaceParameterChangesRow, error) {
+	// The history of a workspace is visible to anyone who can read it.
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceParameterChanges(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -2011,6 +2898,13 @@ func (q *querier) GetWorkspaceProxyByName(ctx context.Context, name string) (dat
 	return fetch(q.log, q.auth, q.db.GetWorkspaceProxyByName)(ctx, name)
 }
 
+func (q *querier) GetWorkspaceProxyHealth(ctx context.Context) ([]database.WorkspaceProxyHealth, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceProxyHealth(ctx)
+}
+
 func (q *querier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	// TODO: Optimize this
 	resource, err := q.db.GetWorkspaceResourceByID(ctx, id)
@@ -2026,6 +2920,13 @@ func (q *querier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (d
 	return resource, nil
 }
 
+func (q *querier) GetWorkspaceResourceCosts(ctx context.Context, arg database.GetWorkspaceResourceCostsParams) ([]database.WorkspaceResourceCost, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceResourceCosts(ctx, arg)
+}
+
 // GetWorkspaceResourceMetadataByResourceIDs is only used for build data.
 // The workspace/job is already fetched.
 func (q *querier) GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
@@ -2077,6 +2978,16 @@ func (q *querier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.U
 			return nil, err
 		}
 		obj = workspace
+	case database.ProvisionerJobTypeWorkspaceBuildDryRun:
+		workspaceID, err := workspaceIDFromDryRunJob(job)
+		if err != nil {
+			return nil, err
+		}
+		workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		obj = workspace
 	default:
 		return nil, xerrors.Errorf("unknown job type: %s", job.Type)
 	}
@@ -2104,6 +3015,31 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	snapshot, err := q.db.GetWorkspaceSnapshotByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	if _, err := q.GetWorkspaceByID(ctx, snapshot.WorkspaceID); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (q *querier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceTimeline(ctx context.Context, arg database.GetWorkspaceTimelineParams) ([]database.GetWorkspaceTimelineRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceTimeline(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -2119,10 +3055,21 @@ func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesP
 	return q.db.GetAuthorizedWorkspaces(ctx, arg, prep)
 }
 
+func (q *querier) GetWorkspacesByIDs(ctx context.Context, ids []uuid.UUID) ([]database.Workspace, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetWorkspacesByIDs)(ctx, ids)
+}
+
 func (q *querier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	return q.db.GetWorkspacesEligibleForTransition(ctx, now)
 }
 
+func (q *querier) IncrementRateLimitCounter(ctx context.Context, arg database.IncrementRateLimitCounterParams) (int32, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return 0, err
+	}
+	return q.db.IncrementRateLimitCounter(ctx, arg)
+}
+
 func (q *querier) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	return insert(q.log, q.auth,
 		rbac.ResourceAPIKey.WithOwner(arg.UserID.String()),
@@ -2138,6 +3085,14 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertConnectionLog(ctx context.Context, arg database.InsertConnectionLogParams) (database.ConnectionLog, error) {
+	// Connection logs are only recorded by coderd itself.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.ConnectionLog{}, err
+	}
+	return q.db.InsertConnectionLog(ctx, arg)
+}
+
 func (q *querier) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2182,6 +3137,20 @@ func (q *querier) InsertGroupMember(ctx context.Context, arg database.InsertGrou
 	return update(q.log, q.auth, fetch, q.db.InsertGroupMember)(ctx, arg)
 }
 
+func (q *querier) InsertGroupSyncRun(ctx context.Context, arg database.InsertGroupSyncRunParams) (database.GroupSyncRun, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.GroupSyncRun{}, err
+	}
+	return q.db.InsertGroupSyncRun(ctx, arg)
+}
+
+func (q *querier) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.InboxNotification{}, err
+	}
+	return q.db.InsertInboxNotification(ctx, arg)
+}
+
 func (q *querier) InsertLicense(ctx context.Context, arg database.InsertLicenseParams) (database.License, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceLicense); err != nil {
 		return database.License{}, err
@@ -2196,6 +3165,13 @@ func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMi
 	return q.db.InsertMissingGroups(ctx, arg)
 }
 
+func (q *querier) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.NotificationMessage{}, err
+	}
+	return q.db.InsertNotificationMessage(ctx, arg)
+}
+
 func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return database.OAuth2ProviderApp{}, err
@@ -2203,6 +3179,13 @@ func (q *querier) InsertOAuth2ProviderApp(ctx context.Context, arg database.Inse
 	return q.db.InsertOAuth2ProviderApp(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(arg.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppCode(ctx, arg)
+}
+
 func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOAuth2ProviderAppSecret); err != nil {
 		return database.OAuth2ProviderAppSecret{}, err
@@ -2210,6 +3193,17 @@ func (q *querier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg databas
 	return q.db.InsertOAuth2ProviderAppSecret(ctx, arg)
 }
 
+func (q *querier) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	key, err := q.db.GetAPIKeyByID(ctx, arg.APIKeyID)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, xerrors.Errorf("get api key by id: %w", err)
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(key.UserID.String())); err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+	return q.db.InsertOAuth2ProviderAppToken(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -2226,6 +3220,10 @@ func (q *querier) InsertOrganizationMember(ctx context.Context, arg database.Ins
 	return insert(q.log, q.auth, obj, q.db.InsertOrganizationMember)(ctx, arg)
 }
 
+func (q *querier) InsertPasskey(ctx context.Context, arg database.InsertPasskeyParams) (database.Passkey, error) {
+	return insert(q.log, q.auth, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID), q.db.InsertPasskey)(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
@@ -2234,6 +3232,13 @@ func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertP
 	return q.db.InsertProvisionerJob(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+	return q.db.InsertProvisionerJobLogArchive(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
@@ -2242,6 +3247,10 @@ func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.Ins
 	return q.db.InsertProvisionerJobLogs(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	return insert(q.log, q.auth, rbac.ResourceProvisionerDaemon.InOrg(arg.OrganizationID), q.db.InsertProvisionerKey)(ctx, arg)
+}
+
 func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2249,6 +3258,14 @@ func (q *querier) InsertReplica(ctx context.Context, arg database.InsertReplicaP
 	return q.db.InsertReplica(ctx, arg)
 }
 
+func (q *querier) InsertSessionRecording(ctx context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	// Session recordings are only recorded by coderd itself.
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.SessionRecording{}, err
+	}
+	return q.db.InsertSessionRecording(ctx, arg)
+}
+
 func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	obj := rbac.ResourceTemplate.InOrg(arg.OrganizationID)
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, obj); err != nil {
@@ -2287,6 +3304,29 @@ func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg databa
 	return q.db.InsertTemplateVersionParameter(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	// An actor is allowed to manage the presets of a template version if they
+	// are authorized to update the template.
+	tv, err := q.db.GetTemplateVersionByID(ctx, arg.TemplateVersionID)
+	if err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	var obj rbac.Objecter
+	if !tv.TemplateID.Valid {
+		obj = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
+	} else {
+		tpl, err := q.db.GetTemplateByID(ctx, tv.TemplateID.UUID)
+		if err != nil {
+			return database.TemplateVersionPreset{}, err
+		}
+		obj = tpl
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, obj); err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+	return q.db.InsertTemplateVersionPreset(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -2294,6 +3334,13 @@ func (q *querier) InsertTemplateVersionVariable(ctx context.Context, arg databas
 	return q.db.InsertTemplateVersionVariable(ctx, arg)
 }
 
+func (q *querier) InsertTwoFactorChallenge(ctx context.Context, arg database.InsertTwoFactorChallengeParams) (database.TwoFactorChallenge, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.TwoFactorChallenge{}, err
+	}
+	return q.db.InsertTwoFactorChallenge(ctx, arg)
+}
+
 func (q *querier) InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error) {
 	// Always check if the assigned roles can actually be assigned by this actor.
 	impliedRoles := append([]string{rbac.RoleMember()}, arg.RBACRoles...)
@@ -2323,6 +3370,27 @@ func (q *querier) InsertUserLink(ctx context.Context, arg database.InsertUserLin
 	return q.db.InsertUserLink(ctx, arg)
 }
 
+func (q *querier) InsertUserRecoveryCode(ctx context.Context, arg database.InsertUserRecoveryCodeParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.InsertUserRecoveryCode(ctx, arg)
+}
+
+func (q *querier) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWebhook); err != nil {
+		return database.Webhook{}, err
+	}
+	return q.db.InsertWebhook(ctx, arg)
+}
+
+func (q *querier) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceWebhook); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+	return q.db.InsertWebhookDelivery(ctx, arg)
+}
+
 func (q *querier) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	obj := rbac.ResourceWorkspace.WithOwner(arg.OwnerID.String()).InOrg(arg.OrganizationID)
 	return insert(q.log, q.auth, obj, q.db.InsertWorkspace)(ctx, arg)
@@ -2355,6 +3423,20 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return []database.WorkspaceAgentScript{}, err
@@ -2457,6 +3539,31 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceEvent(ctx context.Context, arg database.InsertWorkspaceEventParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceEvent(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceFavorite(ctx context.Context, arg database.InsertWorkspaceFavoriteParams) error {
+	// Users can only favorite workspaces they can read.
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.InsertWorkspaceFavorite(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -2475,6 +3582,35 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+	return q.db.InsertWorkspaceSnapshot(ctx, arg)
+}
+
+func (q *querier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	return fetchWithPostFilter(q.auth, q.db.ListProvisionerKeysByOrganization)(ctx, organizationID)
+}
+
+func (q *querier) RecordLoginAttempt(ctx context.Context, arg database.RecordLoginAttemptParams) (database.LoginFailure, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.LoginFailure{}, err
+	}
+	return q.db.RecordLoginAttempt(ctx, arg)
+}
+
+func (q *querier) RecordUnknownLoginAttempt(ctx context.Context, arg database.RecordUnknownLoginAttemptParams) (database.UnknownLoginFailure, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.UnknownLoginFailure{}, err
+	}
+	return q.db.RecordUnknownLoginAttempt(ctx, arg)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2482,6 +3618,13 @@ func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.Regis
 	return updateWithReturn(q.log, q.auth, fetch, q.db.RegisterWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) RequeueProvisionerJobByID(ctx context.Context, arg database.RequeueProvisionerJobByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.RequeueProvisionerJobByID(ctx, arg)
+}
+
 func (q *querier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2544,6 +3687,20 @@ func (q *querier) UpdateInactiveUsersToDormant(ctx context.Context, lastSeenAfte
 	return q.db.UpdateInactiveUsersToDormant(ctx, lastSeenAfter)
 }
 
+func (q *querier) UpdateInactiveUsersToSuspended(ctx context.Context, arg database.UpdateInactiveUsersToSuspendedParams) ([]database.UpdateInactiveUsersToSuspendedRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.UpdateInactiveUsersToSuspended(ctx, arg)
+}
+
+func (q *querier) UpdateInboxNotificationsReadByUserID(ctx context.Context, arg database.UpdateInboxNotificationsReadByUserIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.UpdateInboxNotificationsReadByUserID(ctx, arg)
+}
+
 func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	// Authorized fetch will check that the actor has read access to the org member since the org member is returned.
 	member, err := q.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
@@ -2565,6 +3722,13 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateNotificationMessageByID(ctx, arg)
+}
+
 func (q *querier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceOAuth2ProviderApp); err != nil {
 		return database.OAuth2ProviderApp{}, err
@@ -2579,6 +3743,13 @@ func (q *querier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg dat
 	return q.db.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
 }
 
+func (q *querier) UpdatePasskeyUsage(ctx context.Context, arg database.UpdatePasskeyUsageParams) error {
+	fetch := func(ctx context.Context, arg database.UpdatePasskeyUsageParams) (database.Passkey, error) {
+		return q.db.GetPasskeyByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdatePasskeyUsage)(ctx, arg)
+}
+
 func (q *querier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon); err != nil {
 		return err
@@ -2594,6 +3765,19 @@ func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.Upd
 	return q.db.UpdateProvisionerJobByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerJobPriorityByID(ctx context.Context, arg database.UpdateProvisionerJobPriorityByIDParams) error {
+	job, err := q.db.GetProvisionerJobByID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+	// Reordering the queue affects every user of the organization's
+	// provisioners, so it requires managing provisioner daemons.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon.InOrg(job.OrganizationID)); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobPriorityByID(ctx, arg)
+}
+
 func (q *querier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	job, err := q.db.GetProvisionerJobByID(ctx, arg.ID)
 	if err != nil {
@@ -2629,6 +3813,21 @@ func (q *querier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg da
 			}
 		}
 
+		err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+		if err != nil {
+			return err
+		}
+	case database.ProvisionerJobTypeWorkspaceBuildDryRun:
+		// Dry-runs never change the workspace, so they can be canceled
+		// regardless of the template's cancel setting.
+		workspaceID, err := workspaceIDFromDryRunJob(job)
+		if err != nil {
+			return err
+		}
+		workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return err
+		}
 		err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
 		if err != nil {
 			return err
@@ -2669,6 +3868,13 @@ func (q *querier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg
 	return q.db.UpdateProvisionerJobWithCompleteByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerKeySecretByID(ctx context.Context, arg database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+	fetch := func(ctx context.Context, arg database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+		return q.db.GetProvisionerKeyByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateProvisionerKeySecretByID)(ctx, arg)
+}
+
 func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2676,6 +3882,13 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTailnetCoordinator); err != nil {
+		return err
+	}
+	return q.db.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -2699,11 +3912,39 @@ func (q *querier) UpdateTemplateActiveVersionByID(ctx context.Context, arg datab
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateActiveVersionByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateBuildRetryPolicyByID(ctx context.Context, arg database.UpdateTemplateBuildRetryPolicyByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateBuildRetryPolicyByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateBuildRetryPolicyByID)(ctx, arg)
+}
+
 // Deprecated: use SoftDeleteTemplateByID instead.
 func (q *querier) UpdateTemplateDeletedByID(ctx context.Context, arg database.UpdateTemplateDeletedByIDParams) error {
 	return q.SoftDeleteTemplateByID(ctx, arg.ID)
 }
 
+func (q *querier) UpdateTemplateMaintenanceWindowByID(ctx context.Context, arg database.UpdateTemplateMaintenanceWindowByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateMaintenanceWindowByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMaintenanceWindowByID)(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateMaxBuildDurationByID(ctx context.Context, arg database.UpdateTemplateMaxBuildDurationByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateMaxBuildDurationByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMaxBuildDurationByID)(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateMaxRunningWorkspacesByID(ctx context.Context, arg database.UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateMaxRunningWorkspacesByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMaxRunningWorkspacesByID)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -2711,6 +3952,13 @@ func (q *querier) UpdateTemplateMetaByID(ctx context.Context, arg database.Updat
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateMetaByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplatePortForwardingPolicyByID(ctx context.Context, arg database.UpdateTemplatePortForwardingPolicyByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplatePortForwardingPolicyByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplatePortForwardingPolicyByID)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
@@ -2718,6 +3966,13 @@ func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.U
 	return update(q.log, q.auth, fetch, q.db.UpdateTemplateScheduleByID)(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateSessionRecordingByID(ctx context.Context, arg database.UpdateTemplateSessionRecordingByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateSessionRecordingByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateSessionRecordingByID)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	// An actor is allowed to update the template version if they are authorized to update the template.
 	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
@@ -2784,6 +4039,20 @@ func (q *querier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.
 	return q.db.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVisibilityByID(ctx context.Context, arg database.UpdateTemplateVisibilityByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateVisibilityByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateVisibilityByID)(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateWorkspaceNamePolicyByID(ctx context.Context, arg database.UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspaceNamePolicyByIDParams) (database.Template, error) {
+		return q.db.GetTemplateByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateTemplateWorkspaceNamePolicyByID)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -2833,6 +4102,19 @@ func (q *querier) UpdateUserHashedPassword(ctx context.Context, arg database.Upd
 	return q.db.UpdateUserHashedPassword(ctx, arg)
 }
 
+// UpdateUserIPAllowlist is limited to admins, so users can't lift a
+// restriction placed on their own keys.
+func (q *querier) UpdateUserIPAllowlist(ctx context.Context, arg database.UpdateUserIPAllowlistParams) (database.User, error) {
+	u, err := q.db.GetUserByID(ctx, arg.ID)
+	if err != nil {
+		return database.User{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, u.RBACObject()); err != nil {
+		return database.User{}, err
+	}
+	return q.db.UpdateUserIPAllowlist(ctx, arg)
+}
+
 func (q *querier) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	fetch := func(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 		return q.db.GetUserByID(ctx, arg.ID)
@@ -2916,6 +4198,20 @@ func (q *querier) UpdateUserStatus(ctx context.Context, arg database.UpdateUserS
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateUserStatus)(ctx, arg)
 }
 
+func (q *querier) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookDeliveryByID(ctx, arg)
+}
+
+func (q *querier) UpdateWebhookSecretByID(ctx context.Context, arg database.UpdateWebhookSecretByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceWebhook); err != nil {
+		return err
+	}
+	return q.db.UpdateWebhookSecretByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2923,6 +4219,28 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	// Users who a workspace is shared with are never granted update, so they
+	// cannot share it further.
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceACLByID)(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentAuthTokenByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3060,6 +4378,14 @@ func (q *querier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context,
 	return q.db.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceBuildRetryAtByID(ctx context.Context, arg database.UpdateWorkspaceBuildRetryAtByIDParams) error {
+	// Retries are only scheduled by provisionerd when a build fails.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceBuildRetryAtByID(ctx, arg)
+}
+
 // Deprecated: Use SoftDeleteWorkspaceByID
 func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	// TODO deleteQ me, placeholder for database.Store
@@ -3070,6 +4396,19 @@ func (q *querier) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.U
 	return deleteQ(q.log, q.auth, fetch, q.db.UpdateWorkspaceDeletedByID)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDormancyExempt(ctx context.Context, arg database.UpdateWorkspaceDormancyExemptParams) error {
+	// Exempting a workspace overrides the dormancy settings of its template,
+	// so it requires permission to update the template.
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDormancyExemptParams) (database.Template, error) {
+		workspace, err := q.db.GetWorkspaceByID(ctx, arg.ID)
+		if err != nil {
+			return database.Template{}, err
+		}
+		return q.db.GetTemplateByID(ctx, workspace.TemplateID)
+	}
+	return fetchAndExec(q.log, q.auth, rbac.ActionUpdate, fetch, q.db.UpdateWorkspaceDormancyExempt)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.Workspace, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -3077,6 +4416,13 @@ func (q *querier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg data
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceDormantDeletingAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceDriftResult(ctx context.Context, arg database.UpdateWorkspaceDriftResultParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceDriftResult(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -3084,6 +4430,20 @@ func (q *querier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.Up
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceLastUsedAt)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceMaintenanceOptOut(ctx context.Context, arg database.UpdateWorkspaceMaintenanceOptOutParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceMaintenanceOptOutParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceMaintenanceOptOut)(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceOwnerByID)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -3125,6 +4485,47 @@ func (q *querier) UpsertApplicationName(ctx context.Context, value string) error
 	return q.db.UpsertApplicationName(ctx, value)
 }
 
+func (q *querier) UpsertCustomRole(ctx context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	act, ok := ActorFromContext(ctx)
+	if !ok {
+		return database.CustomRole{}, NoActorError
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceRoleAssignment); err != nil {
+		return database.CustomRole{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceRoleAssignment); err != nil {
+		return database.CustomRole{}, err
+	}
+	if _, err := rbac.RoleByName(arg.Name); err == nil {
+		return database.CustomRole{}, xerrors.Errorf("%q is a built in role", arg.Name)
+	}
+
+	role, err := rolestore.ConvertDBRole(database.CustomRole{
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		SitePermissions: arg.SitePermissions,
+	})
+	if err != nil {
+		return database.CustomRole{}, xerrors.Errorf("invalid custom role: %w", err)
+	}
+
+	// A role cannot grant anything the actor does not have themselves,
+	// otherwise anyone able to create roles could escalate their own
+	// permissions. Negated permissions only ever take permissions away.
+	for _, perm := range role.Site {
+		if perm.Negate {
+			continue
+		}
+		err := q.auth.Authorize(ctx, act, perm.Action, rbac.Object{Type: perm.ResourceType})
+		if err != nil {
+			return database.CustomRole{}, logNotAuthorizedError(ctx, q.log,
+				xerrors.Errorf("cannot grant %q on %q: %w", perm.Action, perm.ResourceType, err))
+		}
+	}
+
+	return q.db.UpsertCustomRole(ctx, arg)
+}
+
 func (q *querier) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3146,6 +4547,13 @@ func (q *querier) UpsertLastUpdateCheck(ctx context.Context, value string) error
 	return q.db.UpsertLastUpdateCheck(ctx, value)
 }
 
+func (q *querier) UpsertLicenseGracePeriodStartedAt(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertLicenseGracePeriodStartedAt(ctx, value)
+}
+
 func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
 		return err
@@ -3153,6 +4561,13 @@ func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	return q.db.UpsertLogoURL(ctx, value)
 }
 
+func (q *querier) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.NotificationPreference{}, err
+	}
+	return q.db.UpsertNotificationPreference(ctx, arg)
+}
+
 func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -3160,11 +4575,28 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 	return q.db.UpsertOAuthSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertOrganizationQuota(ctx context.Context, arg database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceOrganization.WithID(arg.OrganizationID).InOrg(arg.OrganizationID)); err != nil {
+		return database.OrganizationQuota{}, err
+	}
+	return q.db.UpsertOrganizationQuota(ctx, arg)
+}
+
+func (q *querier) UpsertOrganizationTemplateVariableValue(ctx context.Context, arg database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.InOrg(arg.OrganizationID)); err != nil {
+		return database.OrganizationTemplateVariableValue{}, err
+	}
+	return q.db.UpsertOrganizationTemplateVariableValue(ctx, arg)
+}
+
 func (q *querier) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	res := rbac.ResourceProvisionerDaemon.All()
 	if arg.Tags[provisionersdk.TagScope] == provisionersdk.ScopeUser {
 		res.Owner = arg.Tags[provisionersdk.TagOwner]
 	}
+	if arg.OrganizationID.Valid {
+		res = res.InOrg(arg.OrganizationID.UUID)
+	}
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, res); err != nil {
 		return database.ProvisionerDaemon{}, err
 	}
@@ -3220,6 +4652,123 @@ func (q *querier) UpsertTailnetTunnel(ctx context.Context, arg database.UpsertTa
 	return q.db.UpsertTailnetTunnel(ctx, arg)
 }
 
+func (q *querier) UpsertTemplateVariableValue(ctx context.Context, arg database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateVariableValue{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.TemplateVariableValue{}, err
+	}
+	return q.db.UpsertTemplateVariableValue(ctx, arg)
+}
+
+func (q *querier) UpsertTwoFactorPolicy(ctx context.Context, arg database.UpsertTwoFactorPolicyParams) (database.TwoFactorPolicy, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
+		return database.TwoFactorPolicy{}, err
+	}
+	return q.db.UpsertTwoFactorPolicy(ctx, arg)
+}
+
+func (q *querier) UpsertUserActivity(ctx context.Context, arg database.UpsertUserActivityParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertUserActivity(ctx, arg)
+}
+
+func (q *querier) UpsertUserLockout(ctx context.Context, arg database.UpsertUserLockoutParams) (database.UserLockout, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.UserLockout{}, err
+	}
+	return q.db.UpsertUserLockout(ctx, arg)
+}
+
+func (q *querier) UpsertUserSCIMExternalID(ctx context.Context, arg database.UpsertUserSCIMExternalIDParams) (database.UserSCIMExternalID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.UserSCIMExternalID{}, err
+	}
+	return q.db.UpsertUserSCIMExternalID(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceAgentGPUs(ctx context.Context, arg database.UpsertWorkspaceAgentGPUsParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentGPUs(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+	return q.db.UpsertWorkspaceAgentPortShare(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceAgentPorts(ctx context.Context, arg database.UpsertWorkspaceAgentPortsParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentPorts(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceAgentPreviousAuthToken(ctx context.Context, arg database.UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpsertWorkspaceAgentPreviousAuthToken(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceDriftJob(ctx context.Context, arg database.UpsertWorkspaceDriftJobParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceDriftJob(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceProxyHealth(ctx context.Context, arg database.UpsertWorkspaceProxyHealthParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceProxyHealth(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceResourceCosts(ctx context.Context, arg database.UpsertWorkspaceResourceCostsParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspaceResourceCosts(ctx, arg)
+}
+
+func (q *querier) UseUserRecoveryCode(ctx context.Context, arg database.UseUserRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.UserRecoveryCode{}, err
+	}
+	return q.db.UseUserRecoveryCode(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
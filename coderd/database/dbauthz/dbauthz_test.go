@@ -4,11 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"net"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -96,6 +99,44 @@ func TestInTX(t *testing.T) {
 	require.True(t, dbauthz.IsNotAuthorizedError(err), "must be an authorized error")
 }
 
+// TestGetTemplateBuildInsightsTemplateFallback checks that callers without
+// site-wide insights access can still read build insights for templates they
+// are allowed to update.
+func TestGetTemplateBuildInsightsTemplateFallback(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	q := dbauthz.New(db, rbac.NewAuthorizer(prometheus.NewRegistry()), slog.Make(), coderdtest.AccessControlStorePointer())
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	otherOrg := dbgen.Organization(t, db, database.Organization{})
+	tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID})
+	otherTpl := dbgen.Template(t, db, database.Template{OrganizationID: otherOrg.ID})
+
+	actor := rbac.Subject{
+		ID:     uuid.NewString(),
+		Roles:  rbac.RoleNames{rbac.RoleMember(), rbac.RoleOrgAdmin(org.ID)},
+		Groups: []string{},
+		Scope:  rbac.ScopeAll,
+	}
+	ctx := dbauthz.As(context.Background(), actor)
+
+	_, err := q.GetTemplateBuildInsights(ctx, database.GetTemplateBuildInsightsParams{
+		TemplateID: tpl.ID,
+	})
+	require.NoError(t, err, "org admin can update the template")
+
+	_, err = q.GetTemplateBuildInsights(ctx, database.GetTemplateBuildInsightsParams{
+		TemplateID: otherTpl.ID,
+	})
+	require.True(t, dbauthz.IsNotAuthorizedError(err), "template in another org")
+
+	_, err = q.GetTemplateBuildInsights(ctx, database.GetTemplateBuildInsightsParams{
+		TemplateID: uuid.New(),
+	})
+	require.True(t, dbauthz.IsNotAuthorizedError(err), "missing template")
+}
+
 // TestNew should not double wrap a querier.
 func TestNew(t *testing.T) {
 	t.Parallel()
@@ -169,6 +210,10 @@ func (s *MethodTestSuite) TestAPIKey() {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
 		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns()
 	}))
+	s.Run("DeleteAPIKeyByIDReturning", s.Subtest(func(db database.Store, check *expects) {
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
+		check.Args(key.ID).Asserts(key, rbac.ActionDelete).Returns(key)
+	}))
 	s.Run("GetAPIKeyByID", s.Subtest(func(db database.Store, check *expects) {
 		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{})
 		check.Args(key.ID).Asserts(key, rbac.ActionRead).Returns(key)
@@ -211,6 +256,14 @@ func (s *MethodTestSuite) TestAPIKey() {
 			Asserts(a, rbac.ActionRead, b, rbac.ActionRead).
 			Returns(slice.New(a, b))
 	}))
+	s.Run("GetActiveSessionsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		a, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, ExpiresAt: time.Now().Add(time.Hour)})
+		_, _ = dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID, LoginType: database.LoginTypeToken, ExpiresAt: time.Now().Add(time.Hour)})
+		check.Args(database.GetActiveSessionsByUserIDParams{UserID: u.ID, Now: time.Now()}).
+			Asserts(a, rbac.ActionRead).
+			Returns(slice.New(a))
+	}))
 	s.Run("InsertAPIKey", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.InsertAPIKeyParams{
@@ -261,6 +314,54 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			Limit: 10,
 		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
 	}))
+	s.Run("GetAuditLogsKeyset", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetAuditLogsKeysetParams{
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestConnectionLogs() {
+	s.Run("InsertConnectionLog", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertConnectionLogParams{
+			Type: database.ConnectionTypeSsh,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetConnectionLogsOffset", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.ConnectionLog(s.T(), db, database.ConnectionLog{})
+		_ = dbgen.ConnectionLog(s.T(), db, database.ConnectionLog{})
+		check.Args(database.GetConnectionLogsOffsetParams{
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+}
+
+func (s *MethodTestSuite) TestSessionRecordings() {
+	s.Run("InsertSessionRecording", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertSessionRecordingParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.SessionRecording(s.T(), db, database.SessionRecording{})
+		check.Args(r.ID).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns(r)
+	}))
+	s.Run("GetSessionRecordingsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.SessionRecording(s.T(), db, database.SessionRecording{})
+		check.Args(r.WorkspaceID).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns([]database.SessionRecording{r})
+	}))
+	s.Run("GetExpiredSessionRecordings", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetExpiredSessionRecordingsParams{
+			Now:      dbtime.Now(),
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		r := dbgen.SessionRecording(s.T(), db, database.SessionRecording{})
+		check.Args(r.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 }
 
 func (s *MethodTestSuite) TestFile() {
@@ -502,6 +603,20 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 			JobID: j.ID,
 		}).Asserts(w, rbac.ActionRead).Returns([]database.ProvisionerJobLog{})
 	}))
+	s.Run("GetProvisionerJobLogArchiveByJobID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID})
+		archive, err := db.InsertProvisionerJobLogArchive(context.Background(), database.InsertProvisionerJobLogArchiveParams{
+			JobID:          j.ID,
+			CreatedAt:      dbtime.Now(),
+			CompressedLogs: []byte{},
+		})
+		require.NoError(s.T(), err)
+		check.Args(j.ID).Asserts(w, rbac.ActionRead).Returns(archive)
+	}))
 }
 
 func (s *MethodTestSuite) TestLicense() {
@@ -559,6 +674,18 @@ func (s *MethodTestSuite) TestLicense() {
 }
 
 func (s *MethodTestSuite) TestOrganization() {
+	s.Run("GetGroupsByOrganizationAndUserID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		a := dbgen.Group(s.T(), db, database.Group{OrganizationID: o.ID})
+		_ = dbgen.Group(s.T(), db, database.Group{OrganizationID: o.ID})
+		dbgen.GroupMember(s.T(), db, database.GroupMember{GroupID: a.ID, UserID: u.ID})
+		check.Args(database.GetGroupsByOrganizationAndUserIDParams{
+			UserID:         u.ID,
+			OrganizationID: o.ID,
+		}).Asserts(a, rbac.ActionRead).
+			Returns([]database.Group{a})
+	}))
 	s.Run("GetGroupsByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		a := dbgen.Group(s.T(), db, database.Group{OrganizationID: o.ID})
@@ -574,13 +701,44 @@ func (s *MethodTestSuite) TestOrganization() {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(o.Name).Asserts(o, rbac.ActionRead).Returns(o)
 	}))
+	s.Run("GetOrganizationResourceCostRollup", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.GetOrganizationResourceCostRollupParams{
+			OrganizationID: o.ID,
+			StartDate:      dbtime.Now().Add(-24 * time.Hour),
+			EndDate:        dbtime.Now(),
+		}).Asserts(rbac.ResourceWorkspace.InOrg(o.ID), rbac.ActionRead)
+	}))
+	s.Run("GetOrganizationQuota", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		q, err := db.UpsertOrganizationQuota(context.Background(), database.UpsertOrganizationQuotaParams{
+			OrganizationID: o.ID,
+			MaxDailyCost:   100,
+			UpdatedAt:      dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(o.ID).Asserts(o, rbac.ActionRead).Returns(q)
+	}))
+	s.Run("GetQuotaConsumedForOrganization", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceWorkspace.InOrg(o.ID), rbac.ActionRead).Returns(int64(0))
+	}))
+	s.Run("UpsertOrganizationQuota", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationQuotaParams{
+			OrganizationID:              o.ID,
+			MaxRunningWorkspacesPerUser: 2,
+			UpdatedAt:                   dbtime.Now(),
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
 	s.Run("GetOrganizationIDsByMemberIDs", s.Subtest(func(db database.Store, check *expects) {
 		oa := dbgen.Organization(s.T(), db, database.Organization{})
 		ob := dbgen.Organization(s.T(), db, database.Organization{})
 		ma := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{OrganizationID: oa.ID})
 		mb := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{OrganizationID: ob.ID})
 		check.Args([]uuid.UUID{ma.UserID, mb.UserID}).
-			Asserts(rbac.ResourceUserObject(ma.UserID), rbac.ActionRead, rbac.ResourceUserObject(mb.UserID), rbac.ActionRead)
+			Asserts(rbac.ResourceUserObject(ma.UserID), rbac.ActionRead, rbac.ResourceUserObject(mb.UserID), rbac.ActionRead,
+				ma, rbac.ActionRead, mb, rbac.ActionRead)
 	}))
 	s.Run("GetOrganizationMemberByUserID", s.Subtest(func(db database.Store, check *expects) {
 		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{})
@@ -589,6 +747,41 @@ func (s *MethodTestSuite) TestOrganization() {
 			UserID:         mem.UserID,
 		}).Asserts(mem, rbac.ActionRead).Returns(mem)
 	}))
+	s.Run("GetOrganizationMembers", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{OrganizationID: o.ID, UserID: u.ID})
+		row := database.GetOrganizationMembersRow{
+			UserID:         mem.UserID,
+			OrganizationID: mem.OrganizationID,
+			CreatedAt:      mem.CreatedAt,
+			UpdatedAt:      mem.UpdatedAt,
+			Roles:          mem.Roles,
+			Username:       u.Username,
+		}
+		check.Args(o.ID).Asserts(row, rbac.ActionRead).Returns(slice.New(row))
+	}))
+	s.Run("DeleteOrganizationMember", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		u := dbgen.User(s.T(), db, database.User{})
+		mem := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{
+			OrganizationID: o.ID,
+			UserID:         u.ID,
+			Roles:          []string{rbac.RoleOrgAdmin(o.ID)},
+		})
+		check.Args(database.DeleteOrganizationMemberParams{
+			OrganizationID: o.ID,
+			UserID:         u.ID,
+		}).Asserts(
+			mem, rbac.ActionRead,
+			rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionDelete,
+			mem, rbac.ActionDelete,
+		).Returns()
+	}))
+	s.Run("DeleteOrganization", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceOrganization.WithID(o.ID), rbac.ActionDelete).Returns()
+	}))
 	s.Run("GetOrganizationMembershipsByUserID", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		a := dbgen.OrganizationMember(s.T(), db, database.OrganizationMember{UserID: u.ID})
@@ -669,6 +862,19 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(p.Name).Asserts(p, rbac.ActionRead).Returns(p)
 	}))
+	s.Run("GetWorkspaceProxyHealth", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceProxyHealth", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(database.UpsertWorkspaceProxyHealthParams{
+			ProxyID:   p.ID,
+			Status:    "ok",
+			Errors:    []string{},
+			Warnings:  []string{},
+			CheckedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceProxyDeleted", s.Subtest(func(db database.Store, check *expects) {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(database.UpdateWorkspaceProxyDeletedParams{
@@ -689,6 +895,56 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 	}))
 }
 
+func (s *MethodTestSuite) TestProvisionerKeys() {
+	s.Run("InsertProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.InsertProvisionerKeyParams{
+			ID:             uuid.New(),
+			OrganizationID: o.ID,
+			Name:           "isolated",
+			HashedSecret:   []byte("secret"),
+			Tags:           database.StringMap{},
+		}).Asserts(rbac.ResourceProvisionerDaemon.InOrg(o.ID), rbac.ActionCreate)
+	}))
+	s.Run("GetProvisionerKeyByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID})
+		check.Args(k.ID).Asserts(k, rbac.ActionRead).Returns(k)
+	}))
+	s.Run("GetProvisionerKeyByHashedSecret", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID})
+		check.Args(k.HashedSecret).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(k)
+	}))
+	s.Run("GetProvisionerKeyByName", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID})
+		check.Args(database.GetProvisionerKeyByNameParams{
+			OrganizationID: o.ID,
+			Name:           k.Name,
+		}).Asserts(k, rbac.ActionRead).Returns(k)
+	}))
+	s.Run("ListProvisionerKeysByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k1, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID, Name: "a"})
+		k2, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID, Name: "b"})
+		check.Args(o.ID).Asserts(k1, rbac.ActionRead, k2, rbac.ActionRead).Returns(slice.New(k1, k2))
+	}))
+	s.Run("UpdateProvisionerKeySecretByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID})
+		check.Args(database.UpdateProvisionerKeySecretByIDParams{
+			ID:           k.ID,
+			HashedSecret: []byte("rotated"),
+		}).Asserts(k, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteProvisionerKey", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		k, _ := dbgen.ProvisionerKey(s.T(), db, database.ProvisionerKey{OrganizationID: o.ID})
+		check.Args(k.ID).Asserts(k, rbac.ActionDelete).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetPreviousTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
 		tvid := uuid.New()
@@ -755,6 +1011,13 @@ func (s *MethodTestSuite) TestTemplate() {
 		})
 		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionParameter{})
 	}))
+	s.Run("GetTemplateVersionPresets", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(tv.ID).Asserts(t1, rbac.ActionRead).Returns([]database.TemplateVersionPreset{})
+	}))
 	s.Run("GetTemplateVersionVariables", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -824,6 +1087,7 @@ func (s *MethodTestSuite) TestTemplate() {
 		check.Args(database.InsertTemplateParams{
 			Provisioner:    "echo",
 			OrganizationID: orgID,
+			Visibility:     database.TemplateVisibilityPublic,
 		}).Asserts(rbac.ResourceTemplate.InOrg(orgID), rbac.ActionCreate)
 	}))
 	s.Run("InsertTemplateVersion", s.Subtest(func(db database.Store, check *expects) {
@@ -849,12 +1113,119 @@ func (s *MethodTestSuite) TestTemplate() {
 			ID: t1.ID,
 		}).Asserts(t1, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateTemplateMaintenanceWindowByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateMaintenanceWindowByIDParams{
+			ID: t1.ID,
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateMaxBuildDurationByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateMaxBuildDurationByIDParams{
+			ID: t1.ID,
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateTemplateScheduleByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(database.UpdateTemplateScheduleByIDParams{
 			ID: t1.ID,
 		}).Asserts(t1, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateTemplateVisibilityByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateVisibilityByIDParams{
+			ID:         t1.ID,
+			Visibility: database.TemplateVisibilityPrivate,
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateMaxRunningWorkspacesByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateMaxRunningWorkspacesByIDParams{
+			ID:                   t1.ID,
+			MaxRunningWorkspaces: 5,
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplatePortForwardingPolicyByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplatePortForwardingPolicyByIDParams{
+			ID:                          t1.ID,
+			PortForwardingDenyByDefault: true,
+			PortForwardingAllowedPorts:  []int32{8080},
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateBuildRetryPolicyByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateBuildRetryPolicyByIDParams{
+			ID:                    t1.ID,
+			BuildRetryMaxAttempts: 3,
+			BuildRetryBackoff:     int64(time.Minute),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateSessionRecordingByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateSessionRecordingByIDParams{
+			ID:                        t1.ID,
+			SessionRecordingEnabled:   true,
+			SessionRecordingRetention: int64(time.Hour),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateWorkspaceNamePolicyByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpdateTemplateWorkspaceNamePolicyByIDParams{
+			ID:                   t1.ID,
+			WorkspaceNamePattern: "^dev-",
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("GetTemplateVariableValuesByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		v, err := db.UpsertTemplateVariableValue(context.Background(), database.UpsertTemplateVariableValueParams{
+			TemplateID: t1.ID,
+			Name:       "region",
+			Value:      "eu-west-1",
+			CreatedAt:  dbtime.Now(),
+			UpdatedAt:  dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate).Returns([]database.TemplateVariableValue{v})
+	}))
+	s.Run("UpsertTemplateVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.UpsertTemplateVariableValueParams{
+			TemplateID: t1.ID,
+			Name:       "region",
+			Value:      "eu-west-1",
+			CreatedAt:  dbtime.Now(),
+			UpdatedAt:  dbtime.Now(),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.DeleteTemplateVariableValueParams{
+			TemplateID: t1.ID,
+			Name:       "region",
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetOrganizationTemplateVariableValues", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(o.ID).Asserts(rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionUpdate)
+	}))
+	s.Run("UpsertOrganizationTemplateVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertOrganizationTemplateVariableValueParams{
+			OrganizationID: o.ID,
+			Name:           "registry",
+			Value:          "registry.example.com",
+			CreatedAt:      dbtime.Now(),
+			UpdatedAt:      dbtime.Now(),
+		}).Asserts(rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteOrganizationTemplateVariableValue", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.DeleteOrganizationTemplateVariableValueParams{
+			OrganizationID: o.ID,
+			Name:           "registry",
+		}).Asserts(rbac.ResourceTemplate.InOrg(o.ID), rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateTemplateWorkspacesLastUsedAt", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(database.UpdateTemplateWorkspacesLastUsedAtParams{
@@ -893,6 +1264,27 @@ func (s *MethodTestSuite) TestTemplate() {
 			ID: t1.ID,
 		}).Asserts(t1, rbac.ActionUpdate)
 	}))
+	s.Run("InsertTemplateVersionPreset", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.InsertTemplateVersionPresetParams{
+			TemplateVersionID: tv.ID,
+			Name:              "large",
+			Parameters:        json.RawMessage("[]"),
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteTemplateVersionPreset", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
+			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
+		})
+		check.Args(database.DeleteTemplateVersionPresetParams{
+			TemplateVersionID: tv.ID,
+			Name:              "large",
+		}).Asserts(t1, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateTemplateVersionByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		tv := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{
@@ -941,6 +1333,12 @@ func (s *MethodTestSuite) TestTemplate() {
 	s.Run("GetTemplateParameterInsights", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.GetTemplateParameterInsightsParams{}).Asserts(rbac.ResourceTemplateInsights, rbac.ActionRead)
 	}))
+	s.Run("GetTemplateBuildInsights", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.GetTemplateBuildInsightsParams{
+			TemplateID: tpl.ID,
+		}).Asserts(rbac.ResourceTemplateInsights, rbac.ActionRead)
+	}))
 	s.Run("GetTemplateInsightsByInterval", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.GetTemplateInsightsByIntervalParams{}).Asserts(rbac.ResourceTemplateInsights, rbac.ActionRead)
 	}))
@@ -966,6 +1364,11 @@ func (s *MethodTestSuite) TestUser() {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(rbac.ResourceAPIKey.WithOwner(u.ID.String()), rbac.ActionDelete).Returns()
 	}))
+	s.Run("DeleteAPIKeysByUserIDReturning", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceAPIKey.WithOwner(u.ID.String()), rbac.ActionDelete).Returns([]database.APIKey{key})
+	}))
 	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(int64(0))
@@ -1035,6 +1438,15 @@ func (s *MethodTestSuite) TestUser() {
 			ID: u.ID,
 		}).Asserts(u.UserDataRBACObject(), rbac.ActionUpdate)
 	}))
+	s.Run("UpdateUserIPAllowlist", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		u.IPAllowlist = []string{"10.0.0.0/8"}
+		check.Args(database.UpdateUserIPAllowlistParams{
+			ID:          u.ID,
+			IPAllowlist: u.IPAllowlist,
+			UpdatedAt:   u.UpdatedAt,
+		}).Asserts(u, rbac.ActionUpdate).Returns(u)
+	}))
 	s.Run("UpdateUserLastSeenAt", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.UpdateUserLastSeenAtParams{
@@ -1138,6 +1550,19 @@ func (s *MethodTestSuite) TestUser() {
 			rbac.ResourceRoleAssignment, rbac.ActionDelete,
 		).Returns(o)
 	}))
+	s.Run("CustomRolesByName", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]string{"custom-role"}).Asserts(rbac.ResourceRoleAssignment, rbac.ActionRead)
+	}))
+	s.Run("UpsertCustomRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertCustomRoleParams{
+			Name:            "custom-role",
+			DisplayName:     "Custom Role",
+			SitePermissions: []byte(`[]`),
+		}).Asserts(
+			rbac.ResourceRoleAssignment, rbac.ActionCreate,
+			rbac.ResourceRoleAssignment, rbac.ActionUpdate,
+		)
+	}))
 	s.Run("AllUserIDs", s.Subtest(func(db database.Store, check *expects) {
 		a := dbgen.User(s.T(), db, database.User{})
 		b := dbgen.User(s.T(), db, database.User{})
@@ -1150,6 +1575,10 @@ func (s *MethodTestSuite) TestWorkspace() {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspacesByIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args([]uuid.UUID{ws.ID}).Asserts(ws, rbac.ActionRead).Returns([]database.Workspace{ws})
+	}))
 	s.Run("GetWorkspaces", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1162,6 +1591,26 @@ func (s *MethodTestSuite) TestWorkspace() {
 		// No asserts here because SQLFilter.
 		check.Args(database.GetWorkspacesParams{}, emptyPreparedAuthorized{}).Asserts()
 	}))
+	s.Run("GetFavoriteWorkspaceIDsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithID(u.ID).WithOwner(u.ID.String()), rbac.ActionRead)
+	}))
+	s.Run("InsertWorkspaceFavorite", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceFavoriteParams{
+			UserID:      u.ID,
+			WorkspaceID: ws.ID,
+		}).Asserts(ws, rbac.ActionRead, rbac.ResourceUserData.WithID(u.ID).WithOwner(u.ID.String()), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceFavorite", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.DeleteWorkspaceFavoriteParams{
+			UserID:      u.ID,
+			WorkspaceID: ws.ID,
+		}).Asserts(rbac.ResourceUserData.WithID(u.ID).WithOwner(u.ID.String()), rbac.ActionUpdate)
+	}))
 	s.Run("GetLatestWorkspaceBuildByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		b := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID})
@@ -1205,6 +1654,33 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Keys:             []string{"test"},
 		}).Asserts(ws, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentMetadataHistory", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agt.ID,
+			Keys:             []string{"test"},
+		}).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetLatestWorkspaceAgentStatByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		stat := dbgen.WorkspaceAgentStat(s.T(), db, database.WorkspaceAgentStat{
+			AgentID:     agt.ID,
+			WorkspaceID: ws.ID,
+		})
+		check.Args(agt.ID).Asserts(ws, rbac.ActionRead).Returns(stat)
+	}))
 	s.Run("GetWorkspaceAgentByInstanceID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
@@ -1228,7 +1704,7 @@ func (s *MethodTestSuite) TestWorkspace() {
 			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
-	s.Run("UpdateWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetWorkspaceAgentAuthTokenByIDForUpdate", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1236,11 +1712,9 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.UpdateWorkspaceAgentMetadataParams{
-			WorkspaceAgentID: agt.ID,
-		}).Asserts(ws, rbac.ActionUpdate).Returns()
+		check.Args(agt.ID).Asserts(ws, rbac.ActionUpdate).Returns(agt.AuthToken)
 	}))
-	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpdateWorkspaceAgentAuthTokenByID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1248,12 +1722,12 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.UpdateWorkspaceAgentLogOverflowByIDParams{
-			ID:             agt.ID,
-			LogsOverflowed: true,
+		check.Args(database.UpdateWorkspaceAgentAuthTokenByIDParams{
+			ID:        agt.ID,
+			AuthToken: uuid.New(),
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
-	s.Run("UpdateWorkspaceAgentStartupByID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertWorkspaceAgentPreviousAuthToken", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1261,14 +1735,13 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.UpdateWorkspaceAgentStartupByIDParams{
-			ID: agt.ID,
-			Subsystems: []database.WorkspaceAgentSubsystem{
-				database.WorkspaceAgentSubsystemEnvbox,
-			},
+		check.Args(database.UpsertWorkspaceAgentPreviousAuthTokenParams{
+			AgentID:   agt.ID,
+			AuthToken: agt.AuthToken,
+			ExpiresAt: dbtime.Now().Add(time.Minute),
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAgentLogsAfter", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertWorkspaceAgentGPUs", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1276,11 +1749,16 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		check.Args(database.GetWorkspaceAgentLogsAfterParams{
-			AgentID: agt.ID,
-		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentLog{})
+		check.Args(database.UpsertWorkspaceAgentGPUsParams{
+			AgentID:       agt.ID,
+			Index:         []int32{0},
+			Vendor:        []string{"nvidia"},
+			Model:         []string{"NVIDIA A100-SXM4-40GB"},
+			MemoryBytes:   []int64{42949672960},
+			DriverVersion: []string{"535.104.05"},
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAppByAgentIDAndSlug", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("UpsertWorkspaceAgentPorts", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1288,14 +1766,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		app := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
-
-		check.Args(database.GetWorkspaceAppByAgentIDAndSlugParams{
-			AgentID: agt.ID,
-			Slug:    app.Slug,
-		}).Asserts(ws, rbac.ActionRead).Returns(app)
+		check.Args(database.UpsertWorkspaceAgentPortsParams{
+			AgentID:      agt.ID,
+			Port:         []int32{3000},
+			ProcessName:  []string{"node"},
+			DiscoveredAt: dbtime.Now(),
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
-	s.Run("GetWorkspaceAppsByAgentID", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("DeleteWorkspaceAgentPreviousAuthTokenByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
 			TemplateID: tpl.ID,
@@ -1303,10 +1781,100 @@ func (s *MethodTestSuite) TestWorkspace() {
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
 		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
-		a := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
-		b := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
-
-		check.Args(agt.ID).Asserts(ws, rbac.ActionRead).Returns(slice.New(a, b))
+		check.Args(agt.ID).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceAgentMetadata", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentMetadataParams{
+			WorkspaceAgentID: agt.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceAgentMetadataHistory", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agt.ID,
+			Keep:             10,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentLogOverflowByIDParams{
+			ID:             agt.ID,
+			LogsOverflowed: true,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("UpdateWorkspaceAgentStartupByID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentStartupByIDParams{
+			ID: agt.ID,
+			Subsystems: []database.WorkspaceAgentSubsystem{
+				database.WorkspaceAgentSubsystemEnvbox,
+			},
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentLogsAfter", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentLogsAfterParams{
+			AgentID: agt.ID,
+		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentLog{})
+	}))
+	s.Run("GetWorkspaceAppByAgentIDAndSlug", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		app := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
+
+		check.Args(database.GetWorkspaceAppByAgentIDAndSlugParams{
+			AgentID: agt.ID,
+			Slug:    app.Slug,
+		}).Asserts(ws, rbac.ActionRead).Returns(app)
+	}))
+	s.Run("GetWorkspaceAppsByAgentID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{
+			TemplateID: tpl.ID,
+		})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		a := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
+		b := dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{AgentID: agt.ID})
+
+		check.Args(agt.ID).Asserts(ws, rbac.ActionRead).Returns(slice.New(a, b))
 	}))
 	s.Run("GetWorkspaceBuildByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1339,6 +1907,109 @@ func (s *MethodTestSuite) TestWorkspace() {
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, BuildNumber: 3})
 		check.Args(database.GetWorkspaceBuildsByWorkspaceIDParams{WorkspaceID: ws.ID}).Asserts(ws, rbac.ActionRead) // ordering
 	}))
+	s.Run("GetWorkspaceParameterChanges", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.GetWorkspaceParameterChangesParams{WorkspaceID: ws.ID}).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceResourceCosts", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.GetWorkspaceResourceCostsParams{
+			WorkspaceID: ws.ID,
+			StartDate:   dbtime.Now().Add(-24 * time.Hour),
+			EndDate:     dbtime.Now(),
+		}).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceDriftByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		err := db.UpsertWorkspaceDriftJob(context.Background(), database.UpsertWorkspaceDriftJobParams{
+			WorkspaceID: ws.ID,
+			JobID:       uuid.New(),
+			Now:         dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceSnapshotByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID})
+		snapshot, err := db.InsertWorkspaceSnapshot(context.Background(), database.InsertWorkspaceSnapshotParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			BuildID:     build.ID,
+			Name:        "snapshot",
+			CreatedBy:   u.ID,
+			CreatedAt:   dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(snapshot.ID).Asserts(ws, rbac.ActionRead).Returns(snapshot)
+	}))
+	s.Run("GetWorkspaceSnapshotsByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceTimeline", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.GetWorkspaceTimelineParams{WorkspaceID: ws.ID}).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("InsertWorkspaceEvent", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceEventParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			CreatedAt:   dbtime.Now(),
+			Type:        database.WorkspaceEventTypeTtlChanged,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceSnapshot", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID})
+		check.Args(database.InsertWorkspaceSnapshotParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			BuildID:     build.ID,
+			Name:        "snapshot",
+			CreatedBy:   u.ID,
+			CreatedAt:   dbtime.Now(),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("GetWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		share, err := db.UpsertWorkspaceAgentPortShare(context.Background(), database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelPublic,
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+		}).Asserts(ws, rbac.ActionRead).Returns(share)
+	}))
+	s.Run("GetWorkspaceAgentPortSharesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpsertWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+			ShareLevel:  database.AppSharingLevelAuthenticated,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceAgentPortShare", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.DeleteWorkspaceAgentPortShareParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "dev",
+			Port:        8080,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetWorkspaceByAgentID", s.Subtest(func(db database.Store, check *expects) {
 		tpl := dbgen.Template(s.T(), db, database.Template{})
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{
@@ -1486,6 +2157,14 @@ func (s *MethodTestSuite) TestWorkspace() {
 			ID: w.ID,
 		}).Asserts(w, rbac.ActionUpdate).Returns(expected)
 	}))
+	s.Run("UpdateWorkspaceOwnerByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateWorkspaceOwnerByIDParams{
+			ID:      w.ID,
+			OwnerID: u.ID,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceDormantDeletingAt", s.Subtest(func(db database.Store, check *expects) {
 		w := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceDormantDeletingAtParams{
@@ -1499,6 +2178,27 @@ func (s *MethodTestSuite) TestWorkspace() {
 			AutomaticUpdates: database.AutomaticUpdatesAlways,
 		}).Asserts(w, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateWorkspaceMaintenanceOptOut", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceMaintenanceOptOutParams{
+			ID:                w.ID,
+			MaintenanceOptOut: true,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceDormancyExempt", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		w := dbgen.Workspace(s.T(), db, database.Workspace{TemplateID: tpl.ID})
+		check.Args(database.UpdateWorkspaceDormancyExemptParams{
+			ID:             w.ID,
+			DormancyExempt: true,
+		}).Asserts(tpl, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceACLByID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceACLByIDParams{
+			ID: w.ID,
+		}).Asserts(w, rbac.ActionUpdate)
+	}))
 	s.Run("InsertWorkspaceAgentStat", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.InsertWorkspaceAgentStatParams{
@@ -1590,6 +2290,17 @@ func (s *MethodTestSuite) TestExtraMethods() {
 		s.NoError(err, "insert provisioner daemon")
 		check.Args().Asserts(d, rbac.ActionRead)
 	}))
+	s.Run("GetProvisionerDaemonsByOrganization", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		d, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
+			Tags: database.StringMap(map[string]string{
+				provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+			}),
+			OrganizationID: uuid.NullUUID{UUID: org.ID, Valid: true},
+		})
+		s.NoError(err, "insert provisioner daemon")
+		check.Args(org.ID).Asserts(d, rbac.ActionRead).Returns(slice.New(d))
+	}))
 	s.Run("DeleteOldProvisionerDaemons", s.Subtest(func(db database.Store, check *expects) {
 		_, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
 			Tags: database.StringMap(map[string]string{
@@ -1716,6 +2427,13 @@ func (s *MethodTestSuite) TestTailnetFunctions() {
 			Asserts(rbac.ResourceTailnetCoordinator, rbac.ActionRead).
 			Errors(dbmem.ErrUnimplemented)
 	}))
+	s.Run("UpdateTailnetPeerStatusByCoordinator", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateTailnetPeerStatusByCoordinatorParams{
+			Status: database.TailnetStatusLost,
+		}).
+			Asserts(rbac.ResourceTailnetCoordinator, rbac.ActionUpdate).
+			Errors(dbmem.ErrUnimplemented)
+	}))
 	s.Run("UpsertTailnetAgent", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpsertTailnetAgentParams{}).
 			Asserts(rbac.ResourceTailnetCoordinator, rbac.ActionUpdate).
@@ -1872,6 +2590,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ProvisionerState: []byte("testing"),
 		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateWorkspaceBuildRetryAtByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		check.Args(database.UpdateWorkspaceBuildRetryAtByIDParams{
+			ID:      build.ID,
+			RetryAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpsertLastUpdateCheck", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("value").Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
@@ -1880,6 +2606,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		require.NoError(s.T(), err)
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("UpsertLicenseGracePeriodStartedAt", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("2024-01-01T00:00:00Z").Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetLicenseGracePeriodStartedAt", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetWorkspaceBuildsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
@@ -1903,6 +2635,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspaceEvents", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -2037,6 +2772,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			UpdatedAt: time.Now(),
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionUpdate*/ )
 	}))
+	s.Run("UpdateProvisionerJobPriorityByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.UpdateProvisionerJobPriorityByIDParams{
+			ID:        j.ID,
+			Priority:  1,
+			UpdatedAt: time.Now(),
+		}).Asserts(rbac.ResourceProvisionerDaemon.InOrg(j.OrganizationID), rbac.ActionUpdate)
+	}))
 	s.Run("InsertProvisionerJob", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		check.Args(database.InsertProvisionerJobParams{
@@ -2067,6 +2810,15 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			}),
 		}).Asserts(pd.WithOwner("11111111-1111-1111-1111-111111111111"), rbac.ActionCreate)
 	}))
+	s.Run("Organization/UpsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
+		org := dbgen.Organization(s.T(), db, database.Organization{})
+		check.Args(database.UpsertProvisionerDaemonParams{
+			Tags: database.StringMap(map[string]string{
+				provisionersdk.TagScope: provisionersdk.ScopeOrganization,
+			}),
+			OrganizationID: uuid.NullUUID{UUID: org.ID, Valid: true},
+		}).Asserts(rbac.ResourceProvisionerDaemon.All().InOrg(org.ID), rbac.ActionCreate)
+	}))
 	s.Run("InsertTemplateVersionParameter", s.Subtest(func(db database.Store, check *expects) {
 		v := dbgen.TemplateVersion(s.T(), db, database.TemplateVersion{})
 		check.Args(database.InsertTemplateVersionParameterParams{
@@ -2083,6 +2835,74 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("DeleteOldWorkspaceAgentLogs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("DeleteOldWorkspaceAgentMetadataHistory", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldRateLimitCounters", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("IncrementRateLimitCounter", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.IncrementRateLimitCounterParams{
+			Key:         "127.0.0.1:/api/v2/users/login",
+			WindowStart: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns(int32(1))
+	}))
+	s.Run("DeleteProvisionerJobLogsByJobID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(j.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetProvisionerJobIDsToArchiveLogs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetProvisionerJobIDsToArchiveLogsParams{
+			CompletedBefore: dbtime.Now(),
+			LimitOpt:        10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetProvisionerJobLogArchiveStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("InsertProvisionerJobLogArchive", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobLogArchiveParams{
+			JobID:          j.ID,
+			CreatedAt:      dbtime.Now(),
+			CompressedLogs: []byte{},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("UpsertWorkspaceResourceCosts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertWorkspaceResourceCostsParams{
+			Date:            dbtime.Now(),
+			DefaultCurrency: "USD",
+			UpdatedAt:       dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetRunningWorkspaceCounts", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetRunningWorkspaceCountsParams{
+			OwnerID:        uuid.New(),
+			OrganizationID: uuid.New(),
+			TemplateID:     uuid.New(),
+			WorkspaceID:    uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(database.GetRunningWorkspaceCountsRow{})
+	}))
+	s.Run("GetDriftedWorkspaceCount", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceIDsForDriftCheck", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("UpdateWorkspaceDriftResult", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWorkspaceDriftResultParams{
+			WorkspaceID: uuid.New(),
+			JobID:       uuid.New(),
+			CheckedAt:   dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpsertWorkspaceDriftJob", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertWorkspaceDriftJobParams{
+			WorkspaceID: uuid.New(),
+			JobID:       uuid.New(),
+			Now:         dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("InsertWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentStatsParams{}).Asserts(rbac.ResourceSystem, rbac.ActionCreate).Errors(errMatchAny)
 	}))
@@ -2141,6 +2961,18 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetHungProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(time.Time{}).Asserts()
 	}))
+	s.Run("GetOrphanedProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetTimedOutProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("RequeueProvisionerJobByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, nil, database.ProvisionerJob{})
+		check.Args(database.RequeueProvisionerJobByIDParams{
+			ID: j.ID,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpsertOAuthSigningKey", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("foo").Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
@@ -2164,6 +2996,9 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(time.Time{}).Asserts()
 	}))
+	s.Run("GetWorkspaceAgentResourceUsageAndLabels", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Time{}).Asserts()
+	}))
 	s.Run("GetWorkspaceProxyByHostname", s.Subtest(func(db database.Store, check *expects) {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{
 			WildcardHostname: "*.example.com",
@@ -2185,15 +3020,61 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpdateInactiveUsersToDormant", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpdateInactiveUsersToDormantParams{}).Asserts(rbac.ResourceSystem, rbac.ActionCreate).Errors(sql.ErrNoRows)
 	}))
+	s.Run("UpdateInactiveUsersToSuspended", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateInactiveUsersToSuspendedParams{}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetInactiveUsers", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceUser, rbac.ActionRead)
+	}))
+	s.Run("UpsertUserActivity", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserActivityParams{
+			UserID:      []uuid.UUID{u.ID},
+			Hour:        []time.Time{dbtime.Now().Truncate(time.Hour)},
+			Category:    []string{"workspaces"},
+			WorkspaceID: []uuid.UUID{uuid.Nil},
+			Count:       []int32{1},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("DeleteOldUserActivity", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertGroupSyncRun", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertGroupSyncRunParams{
+			ID:      uuid.New(),
+			Trigger: database.GroupSyncTriggerBackground,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetGroupSyncRuns", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetGroupSyncRunsParams{LimitCount: 10}).Asserts(rbac.ResourceDebugInfo, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldGroupSyncRuns", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetWorkspaceUniqueOwnerCountByTemplateIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspaceCountsByTemplateAndStatus", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentCountsByLifecycleState", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceAppCountsByHealth", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetWorkspaceAgentScriptsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
 	s.Run("GetWorkspaceAgentLogSourcesByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentGPUsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetWorkspaceAgentPortsByAgentIDs", s.Subtest(func(db database.Store, check *expects) {
+		check.Args([]uuid.UUID{uuid.New()}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetProvisionerJobsByIDsWithQueuePosition", s.Subtest(func(db database.Store, check *expects) {
 		check.Args([]uuid.UUID{}).Asserts()
 	}))
@@ -2206,6 +3087,25 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetUserLinksByUserID", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(uuid.New()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("GetUserSCIMExternalIDByExternalID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		id, err := db.UpsertUserSCIMExternalID(context.Background(), database.UpsertUserSCIMExternalIDParams{
+			UserID:     u.ID,
+			ExternalID: "external-id",
+		})
+		require.NoError(s.T(), err)
+		check.Args("external-id").Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(id)
+	}))
+	s.Run("UpsertUserSCIMExternalID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserSCIMExternalIDParams{
+			UserID:     u.ID,
+			ExternalID: "external-id",
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns(database.UserSCIMExternalID{
+			UserID:     u.ID,
+			ExternalID: "external-id",
+		})
+	}))
 }
 
 func (s *MethodTestSuite) TestOAuth2ProviderApps() {
@@ -2290,3 +3190,457 @@ func (s *MethodTestSuite) TestOAuth2ProviderAppSecrets() {
 		check.Args(secret.ID).Asserts(rbac.ResourceOAuth2ProviderAppSecret, rbac.ActionDelete)
 	}))
 }
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppCodes() {
+	s.Run("GetOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.ID).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("GetOAuth2ProviderAppCodeByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.SecretPrefix).Asserts(code, rbac.ActionRead).Returns(code)
+	}))
+	s.Run("InsertOAuth2ProviderAppCode", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		check.Args(database.InsertOAuth2ProviderAppCodeParams{
+			AppID:  app.ID,
+			UserID: user.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("DeleteOAuth2ProviderAppCodeByID", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		code := dbgen.OAuth2ProviderAppCode(s.T(), db, database.OAuth2ProviderAppCode{
+			AppID:  app.ID,
+			UserID: user.ID,
+		})
+		check.Args(code.ID).Asserts(code, rbac.ActionDelete).Returns(code)
+	}))
+}
+
+func (s *MethodTestSuite) TestOAuth2ProviderAppTokens() {
+	s.Run("InsertOAuth2ProviderAppToken", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		check.Args(database.InsertOAuth2ProviderAppTokenParams{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		}).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionCreate)
+	}))
+	s.Run("GetOAuth2ProviderAppTokenByPrefix", s.Subtest(func(db database.Store, check *expects) {
+		user := dbgen.User(s.T(), db, database.User{})
+		key, _ := dbgen.APIKey(s.T(), db, database.APIKey{
+			UserID: user.ID,
+		})
+		app := dbgen.OAuth2ProviderApp(s.T(), db, database.OAuth2ProviderApp{})
+		secret := dbgen.OAuth2ProviderAppSecret(s.T(), db, database.OAuth2ProviderAppSecret{
+			AppID: app.ID,
+		})
+		token := dbgen.OAuth2ProviderAppToken(s.T(), db, database.OAuth2ProviderAppToken{
+			AppSecretID: secret.ID,
+			APIKeyID:    key.ID,
+		})
+		check.Args(token.HashPrefix).Asserts(rbac.ResourceOAuth2ProviderAppCodeToken.WithOwner(user.ID.String()), rbac.ActionRead).Returns(token)
+	}))
+}
+
+func (s *MethodTestSuite) TestWebhooks() {
+	s.Run("GetWebhooks", s.Subtest(func(db database.Store, check *expects) {
+		webhooks := []database.Webhook{
+			dbgen.Webhook(s.T(), db, database.Webhook{CreatedAt: time.Now().Add(-time.Hour)}),
+			dbgen.Webhook(s.T(), db, database.Webhook{}),
+		}
+		check.Args().Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(webhooks)
+	}))
+	s.Run("GetWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(webhook.ID).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(webhook)
+	}))
+	s.Run("GetWebhooksByEvent", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{Events: []string{"user.created"}})
+		_ = dbgen.Webhook(s.T(), db, database.Webhook{Events: []string{"template.updated"}})
+		check.Args("user.created").Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns([]database.Webhook{webhook})
+	}))
+	s.Run("InsertWebhook", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWebhookParams{
+			ID:     uuid.New(),
+			Events: []string{},
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("DeleteWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(webhook.ID).Asserts(rbac.ResourceWebhook, rbac.ActionDelete)
+	}))
+	s.Run("InsertWebhookDelivery", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.InsertWebhookDeliveryParams{
+			ID:        uuid.New(),
+			WebhookID: webhook.ID,
+			Payload:   json.RawMessage("{}"),
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionCreate)
+	}))
+	s.Run("GetWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		delivery := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: webhook.ID})
+		check.Args(delivery.ID).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns(delivery)
+	}))
+	s.Run("GetWebhookDeliveriesByWebhookID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		delivery := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: webhook.ID})
+		check.Args(database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionRead).Returns([]database.WebhookDelivery{delivery})
+	}))
+	s.Run("AcquireWebhookDeliveries", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireWebhookDeliveriesParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			Now:        time.Now(),
+			LimitOpt:   10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookDeliveryByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		delivery := dbgen.WebhookDelivery(s.T(), db, database.WebhookDelivery{WebhookID: webhook.ID})
+		check.Args(database.UpdateWebhookDeliveryByIDParams{
+			ID:       delivery.ID,
+			Attempts: 1,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWebhookSecretByID", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.Webhook(s.T(), db, database.Webhook{})
+		check.Args(database.UpdateWebhookSecretByIDParams{
+			ID:     webhook.ID,
+			Secret: "new-secret",
+		}).Asserts(rbac.ResourceWebhook, rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestNotifications() {
+	s.Run("InsertNotificationMessage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertNotificationMessageParams{
+			ID:       uuid.New(),
+			UserID:   u.ID,
+			Template: "user_account_created",
+			Labels:   json.RawMessage("{}"),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionCreate)
+	}))
+	s.Run("GetNotificationMessagesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		msg := dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(database.GetNotificationMessagesByUserIDParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.NotificationMessage{msg})
+	}))
+	s.Run("AcquireNotificationMessages", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.AcquireNotificationMessagesParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			Now:        time.Now(),
+			LimitOpt:   10,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateNotificationMessageByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		msg := dbgen.NotificationMessage(s.T(), db, database.NotificationMessage{UserID: u.ID})
+		check.Args(database.UpdateNotificationMessageByIDParams{
+			ID:       msg.ID,
+			Attempts: 1,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetNotificationPreferencesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		pref, err := db.UpsertNotificationPreference(context.Background(), database.UpsertNotificationPreferenceParams{
+			UserID:   u.ID,
+			Template: "user_account_created",
+			Disabled: true,
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.NotificationPreference{pref})
+	}))
+	s.Run("UpsertNotificationPreference", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertNotificationPreferenceParams{
+			UserID:   u.ID,
+			Template: "user_account_created",
+			Disabled: true,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("InsertInboxNotification", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertInboxNotificationParams{
+			ID:       uuid.New(),
+			UserID:   u.ID,
+			Template: "user_account_created",
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionCreate)
+	}))
+	s.Run("GetInboxNotificationsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		notif := dbgen.InboxNotification(s.T(), db, database.InboxNotification{UserID: u.ID})
+		check.Args(database.GetInboxNotificationsByUserIDParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.InboxNotification{notif})
+	}))
+	s.Run("CountUnreadInboxNotificationsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		_ = dbgen.InboxNotification(s.T(), db, database.InboxNotification{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns(int64(1))
+	}))
+	s.Run("UpdateInboxNotificationsReadByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpdateInboxNotificationsReadByUserIDParams{
+			UserID: u.ID,
+			ReadAt: time.Now(),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestTwoFactor() {
+	s.Run("InsertPasskey", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertPasskeyParams{
+			ID:           uuid.New(),
+			UserID:       u.ID,
+			CredentialID: []byte("credential"),
+			PublicKey:    []byte("key"),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionCreate)
+	}))
+	s.Run("GetPasskeysByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		passkey := dbgen.Passkey(s.T(), db, database.Passkey{UserID: u.ID})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.Passkey{passkey})
+	}))
+	s.Run("GetPasskeyByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		passkey := dbgen.Passkey(s.T(), db, database.Passkey{UserID: u.ID})
+		check.Args(passkey.ID).Asserts(passkey, rbac.ActionRead).Returns(passkey)
+	}))
+	s.Run("UpdatePasskeyUsage", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		passkey := dbgen.Passkey(s.T(), db, database.Passkey{UserID: u.ID})
+		check.Args(database.UpdatePasskeyUsageParams{
+			ID:        passkey.ID,
+			SignCount: 1,
+		}).Asserts(passkey, rbac.ActionUpdate)
+	}))
+	s.Run("DeletePasskeyByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		passkey := dbgen.Passkey(s.T(), db, database.Passkey{UserID: u.ID})
+		check.Args(passkey.ID).Asserts(passkey, rbac.ActionDelete)
+	}))
+	s.Run("InsertTwoFactorChallenge", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertTwoFactorChallengeParams{
+			ID:        uuid.New(),
+			UserID:    u.ID,
+			Purpose:   database.TwoFactorChallengePurposeLogin,
+			Challenge: []byte("challenge"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("DeleteTwoFactorChallengeByID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		challenge, err := db.InsertTwoFactorChallenge(context.Background(), database.InsertTwoFactorChallengeParams{
+			ID:        uuid.New(),
+			UserID:    u.ID,
+			Purpose:   database.TwoFactorChallengePurposeLogin,
+			Challenge: []byte("challenge"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(challenge.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete).Returns(challenge)
+	}))
+	s.Run("DeleteExpiredTwoFactorChallenges", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertUserRecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserRecoveryCodeParams{
+			ID:         uuid.New(),
+			UserID:     u.ID,
+			HashedCode: []byte("code"),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionCreate)
+	}))
+	s.Run("UseUserRecoveryCode", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		codeID := uuid.New()
+		err := db.InsertUserRecoveryCode(context.Background(), database.InsertUserRecoveryCodeParams{
+			ID:         codeID,
+			UserID:     u.ID,
+			HashedCode: []byte("code"),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.UseUserRecoveryCodeParams{
+			ID:     codeID,
+			UserID: u.ID,
+			UsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("GetUnusedUserRecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead)
+	}))
+	s.Run("CountUnusedUserRecoveryCodes", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns(int64(0))
+	}))
+	s.Run("DeleteUserRecoveryCodesByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionDelete)
+	}))
+	s.Run("UpsertTwoFactorPolicy", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertTwoFactorPolicyParams{
+			ID:   uuid.New(),
+			Role: rbac.RoleOwner(),
+		}).Asserts(rbac.ResourceDeploymentValues, rbac.ActionCreate)
+	}))
+	s.Run("GetTwoFactorPolicies", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceDeploymentValues, rbac.ActionRead)
+	}))
+	s.Run("GetTwoFactorPolicyByRole", s.Subtest(func(db database.Store, check *expects) {
+		policy, err := db.UpsertTwoFactorPolicy(context.Background(), database.UpsertTwoFactorPolicyParams{
+			ID:   uuid.New(),
+			Role: rbac.RoleOwner(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(policy.Role).Asserts(rbac.ResourceDeploymentValues, rbac.ActionRead).Returns(policy)
+	}))
+	s.Run("DeleteTwoFactorPolicyByRole", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(rbac.RoleOwner()).Asserts(rbac.ResourceDeploymentValues, rbac.ActionDelete)
+	}))
+}
+
+func (s *MethodTestSuite) TestLoginFailures() {
+	ip := pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   net.IPv4(127, 0, 0, 1),
+			Mask: net.CIDRMask(32, 32),
+		},
+		Valid: true,
+	}
+	s.Run("RecordLoginAttempt", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.RecordLoginAttemptParams{
+			UserID:      u.ID,
+			IPAddress:   ip,
+			AttemptedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetLoginFailure", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		failure, err := db.RecordLoginAttempt(context.Background(), database.RecordLoginAttemptParams{
+			UserID:      u.ID,
+			IPAddress:   ip,
+			AttemptedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetLoginFailureParams{
+			UserID:    u.ID,
+			IPAddress: ip,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(failure)
+	}))
+	s.Run("CountLoginFailuresByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.CountLoginFailuresByUserIDParams{
+			UserID: u.ID,
+			Since:  dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(int64(0))
+	}))
+	s.Run("DeleteLoginFailure", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.DeleteLoginFailureParams{
+			UserID:    u.ID,
+			IPAddress: ip,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("RecordUnknownLoginAttempt", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.RecordUnknownLoginAttemptParams{
+			LoginHash:   []byte("hash"),
+			IPAddress:   ip,
+			AttemptedAt: dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetUnknownLoginFailure", s.Subtest(func(db database.Store, check *expects) {
+		failure, err := db.RecordUnknownLoginAttempt(context.Background(), database.RecordUnknownLoginAttemptParams{
+			LoginHash:   []byte("hash"),
+			IPAddress:   ip,
+			AttemptedAt: dbtime.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetUnknownLoginFailureParams{
+			LoginHash: []byte("hash"),
+			IPAddress: ip,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(failure)
+	}))
+	s.Run("DeleteUnknownLoginFailure", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.DeleteUnknownLoginFailureParams{
+			LoginHash: []byte("hash"),
+			IPAddress: ip,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("GetUnknownLoginFailureTotals", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUnknownLoginFailureTotalsParams{
+			LoginHash: []byte("hash"),
+			Since:     dbtime.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteOldLoginFailures", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("UpsertUserLockout", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserLockoutParams{
+			UserID:         u.ID,
+			FailedAttempts: 10,
+			LockedAt:       dbtime.Now(),
+			LockedUntil:    dbtime.Now().Add(time.Hour),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetUserLockoutByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		lockout, err := db.UpsertUserLockout(context.Background(), database.UpsertUserLockoutParams{
+			UserID:         u.ID,
+			FailedAttempts: 10,
+			LockedAt:       dbtime.Now(),
+			LockedUntil:    dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(lockout, rbac.ActionRead).Returns(lockout)
+	}))
+	s.Run("GetUserLockouts", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		lockout, err := db.UpsertUserLockout(context.Background(), database.UpsertUserLockoutParams{
+			UserID:         u.ID,
+			FailedAttempts: 10,
+			LockedAt:       dbtime.Now(),
+			LockedUntil:    dbtime.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		row := database.GetUserLockoutsRow{
+			UserID:         lockout.UserID,
+			FailedAttempts: lockout.FailedAttempts,
+			LockedAt:       lockout.LockedAt,
+			LockedUntil:    lockout.LockedUntil,
+			Username:       u.Username,
+		}
+		check.Args(dbtime.Now()).Asserts(rbac.ResourceUser, rbac.ActionRead).Returns([]database.GetUserLockoutsRow{row})
+	}))
+	s.Run("DeleteUserLockout", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, rbac.ActionUpdate)
+	}))
+}
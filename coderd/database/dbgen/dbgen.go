@@ -64,6 +64,49 @@ func AuditLog(t testing.TB, db database.Store, seed database.AuditLog) database.
 	return log
 }
 
+func ConnectionLog(t testing.TB, db database.Store, seed database.ConnectionLog) database.ConnectionLog {
+	log, err := db.InsertConnectionLog(genCtx, database.InsertConnectionLogParams{
+		ID:               takeFirst(seed.ID, uuid.New()),
+		Time:             takeFirst(seed.Time, dbtime.Now()),
+		OrganizationID:   takeFirst(seed.OrganizationID, uuid.New()),
+		WorkspaceOwnerID: takeFirst(seed.WorkspaceOwnerID, uuid.New()),
+		WorkspaceID:      takeFirst(seed.WorkspaceID, uuid.New()),
+		WorkspaceName:    takeFirst(seed.WorkspaceName, namesgenerator.GetRandomName(1)),
+		AgentName:        takeFirst(seed.AgentName, "main"),
+		Type:             takeFirst(seed.Type, database.ConnectionTypeSsh),
+		Port:             seed.Port,
+		Ip: pqtype.Inet{
+			IPNet: takeFirstIP(seed.Ip.IPNet, net.IPNet{}),
+			Valid: takeFirst(seed.Ip.Valid, false),
+		},
+		UserAgent: takeFirst(seed.UserAgent, ""),
+		UserID:    takeFirst(seed.UserID, uuid.New()),
+		APIKeyID:  takeFirst(seed.APIKeyID, "abcdefghij"),
+	})
+	require.NoError(t, err, "insert connection log")
+	return log
+}
+
+func SessionRecording(t testing.TB, db database.Store, seed database.SessionRecording) database.SessionRecording {
+	id := takeFirst(seed.ID, uuid.New())
+	workspaceID := takeFirst(seed.WorkspaceID, uuid.New())
+	recording, err := db.InsertSessionRecording(genCtx, database.InsertSessionRecordingParams{
+		ID:             id,
+		OrganizationID: takeFirst(seed.OrganizationID, uuid.New()),
+		TemplateID:     takeFirst(seed.TemplateID, uuid.New()),
+		WorkspaceID:    workspaceID,
+		AgentID:        takeFirst(seed.AgentID, uuid.New()),
+		UserID:         takeFirst(seed.UserID, uuid.New()),
+		StartedAt:      takeFirst(seed.StartedAt, dbtime.Now()),
+		EndedAt:        takeFirst(seed.EndedAt, dbtime.Now()),
+		ObjectKey:      takeFirst(seed.ObjectKey, workspaceID.String()+"/"+id.String()+".cast"),
+		SizeBytes:      takeFirst(seed.SizeBytes, 1024),
+		ExpiresAt:      seed.ExpiresAt,
+	})
+	require.NoError(t, err, "insert session recording")
+	return recording
+}
+
 func Template(t testing.TB, db database.Store, seed database.Template) database.Template {
 	id := takeFirst(seed.ID, uuid.New())
 	if seed.GroupACL == nil {
@@ -90,6 +133,7 @@ func Template(t testing.TB, db database.Store, seed database.Template) database.
 		GroupACL:                     seed.GroupACL,
 		DisplayName:                  takeFirst(seed.DisplayName, namesgenerator.GetRandomName(1)),
 		AllowUserCancelWorkspaceJobs: seed.AllowUserCancelWorkspaceJobs,
+		Visibility:                   takeFirst(seed.Visibility, database.TemplateVisibilityPublic),
 	})
 	require.NoError(t, err, "insert template")
 
@@ -117,17 +161,20 @@ func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database
 	key, err := db.InsertAPIKey(genCtx, database.InsertAPIKeyParams{
 		ID: takeFirst(seed.ID, id),
 		// 0 defaults to 86400 at the db layer
-		LifetimeSeconds: takeFirst(seed.LifetimeSeconds, 0),
-		HashedSecret:    takeFirstSlice(seed.HashedSecret, hashed[:]),
-		IPAddress:       ip,
-		UserID:          takeFirst(seed.UserID, uuid.New()),
-		LastUsed:        takeFirst(seed.LastUsed, dbtime.Now()),
-		ExpiresAt:       takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
-		CreatedAt:       takeFirst(seed.CreatedAt, dbtime.Now()),
-		UpdatedAt:       takeFirst(seed.UpdatedAt, dbtime.Now()),
-		LoginType:       takeFirst(seed.LoginType, database.LoginTypePassword),
-		Scope:           takeFirst(seed.Scope, database.APIKeyScopeAll),
-		TokenName:       takeFirst(seed.TokenName),
+		LifetimeSeconds:     takeFirst(seed.LifetimeSeconds, 0),
+		HashedSecret:        takeFirstSlice(seed.HashedSecret, hashed[:]),
+		IPAddress:           ip,
+		UserID:              takeFirst(seed.UserID, uuid.New()),
+		LastUsed:            takeFirst(seed.LastUsed, dbtime.Now()),
+		ExpiresAt:           takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		CreatedAt:           takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:           takeFirst(seed.UpdatedAt, dbtime.Now()),
+		LoginType:           takeFirst(seed.LoginType, database.LoginTypePassword),
+		Scope:               takeFirst(seed.Scope, database.APIKeyScopeAll),
+		TokenName:           takeFirst(seed.TokenName),
+		Scopes:              takeFirstSlice(seed.Scopes, []string{}),
+		AllowedWorkspaceIDs: takeFirstSlice(seed.AllowedWorkspaceIDs, []uuid.UUID{}),
+		IPAllowlist:         takeFirstSlice(seed.IPAllowlist, []string{}),
 	})
 	require.NoError(t, err, "insert api key")
 	return key, fmt.Sprintf("%s-%s", key.ID, secret)
@@ -184,6 +231,8 @@ func Workspace(t testing.TB, db database.Store, orig database.Workspace) databas
 		AutostartSchedule: orig.AutostartSchedule,
 		Ttl:               orig.Ttl,
 		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
+		Ephemeral:         orig.Ephemeral,
+		EphemeralAPIKeyID: orig.EphemeralAPIKeyID,
 	})
 	require.NoError(t, err, "insert workspace")
 	return workspace
@@ -221,10 +270,21 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 			Deadline:          takeFirst(orig.Deadline, dbtime.Now().Add(time.Hour)),
 			MaxDeadline:       takeFirst(orig.MaxDeadline, time.Time{}),
 			Reason:            takeFirst(orig.Reason, database.BuildReasonInitiator),
+			Attempt:           takeFirst(orig.Attempt, 1),
 		})
 		if err != nil {
 			return err
 		}
+		if orig.RetryAt.Valid {
+			err = db.UpdateWorkspaceBuildRetryAtByID(genCtx, database.UpdateWorkspaceBuildRetryAtByIDParams{
+				ID:        buildID,
+				RetryAt:   orig.RetryAt,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return err
+			}
+		}
 		build, err = db.GetWorkspaceBuildByID(genCtx, buildID)
 		if err != nil {
 			return err
@@ -297,6 +357,15 @@ func User(t testing.TB, db database.Store, orig database.User) database.User {
 		require.NoError(t, err, "user last seen")
 	}
 
+	if len(orig.IPAllowlist) > 0 {
+		user, err = db.UpdateUserIPAllowlist(genCtx, database.UpdateUserIPAllowlistParams{
+			ID:          user.ID,
+			IPAllowlist: orig.IPAllowlist,
+			UpdatedAt:   user.UpdatedAt,
+		})
+		require.NoError(t, err, "user ip allowlist")
+	}
+
 	if orig.Deleted {
 		err = db.UpdateUserDeletedByID(genCtx, database.UpdateUserDeletedByIDParams{
 			ID:      user.ID,
@@ -387,18 +456,19 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 	}
 
 	job, err := db.InsertProvisionerJob(genCtx, database.InsertProvisionerJobParams{
-		ID:             jobID,
-		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
-		UpdatedAt:      takeFirst(orig.UpdatedAt, dbtime.Now()),
-		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
-		InitiatorID:    takeFirst(orig.InitiatorID, uuid.New()),
-		Provisioner:    takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
-		StorageMethod:  takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
-		FileID:         takeFirst(orig.FileID, uuid.New()),
-		Type:           takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
-		Input:          takeFirstSlice(orig.Input, []byte("{}")),
-		Tags:           orig.Tags,
-		TraceMetadata:  pqtype.NullRawMessage{},
+		ID:              jobID,
+		CreatedAt:       takeFirst(orig.CreatedAt, dbtime.Now()),
+		UpdatedAt:       takeFirst(orig.UpdatedAt, dbtime.Now()),
+		OrganizationID:  takeFirst(orig.OrganizationID, uuid.New()),
+		InitiatorID:     takeFirst(orig.InitiatorID, uuid.New()),
+		Provisioner:     takeFirst(orig.Provisioner, database.ProvisionerTypeEcho),
+		StorageMethod:   takeFirst(orig.StorageMethod, database.ProvisionerStorageMethodFile),
+		FileID:          takeFirst(orig.FileID, uuid.New()),
+		Type:            takeFirst(orig.Type, database.ProvisionerJobTypeWorkspaceBuild),
+		Input:           takeFirstSlice(orig.Input, []byte("{}")),
+		Tags:            orig.Tags,
+		TraceMetadata:   pqtype.NullRawMessage{},
+		TagRequirements: orig.TagRequirements,
 	})
 	require.NoError(t, err, "insert job")
 	if ps != nil {
@@ -410,7 +480,7 @@ func ProvisionerJob(t testing.TB, db database.Store, ps pubsub.Pubsub, orig data
 			StartedAt: orig.StartedAt,
 			Types:     []database.ProvisionerType{database.ProvisionerTypeEcho},
 			Tags:      must(json.Marshal(orig.Tags)),
-			WorkerID:  uuid.NullUUID{},
+			WorkerID:  orig.WorkerID,
 		})
 		require.NoError(t, err)
 		// There is no easy way to make sure we acquire the correct job.
@@ -485,6 +555,7 @@ func WorkspaceResource(t testing.TB, db database.Store, orig database.WorkspaceR
 			Valid:  takeFirst(orig.InstanceType.Valid, false),
 		},
 		DailyCost: takeFirst(orig.DailyCost, 0),
+		Snapshot:  takeFirst(orig.Snapshot, false),
 	})
 	require.NoError(t, err, "insert resource")
 	return resource
@@ -531,6 +602,27 @@ func WorkspaceProxy(t testing.TB, db database.Store, orig database.WorkspaceProx
 	return proxy, secret
 }
 
+func ProvisionerKey(t testing.TB, db database.Store, orig database.ProvisionerKey) (database.ProvisionerKey, string) {
+	secret, err := cryptorand.String(43)
+	require.NoError(t, err, "generate secret")
+	hashedSecret := sha256.Sum256([]byte(secret))
+
+	tags := orig.Tags
+	if tags == nil {
+		tags = database.StringMap{}
+	}
+	key, err := db.InsertProvisionerKey(genCtx, database.InsertProvisionerKeyParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		CreatedAt:      takeFirst(orig.CreatedAt, dbtime.Now()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		Name:           takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		HashedSecret:   hashedSecret[:],
+		Tags:           tags,
+	})
+	require.NoError(t, err, "insert provisioner key")
+	return key, secret
+}
+
 func File(t testing.TB, db database.Store, orig database.File) database.File {
 	file, err := db.InsertFile(genCtx, database.InsertFileParams{
 		ID:        takeFirst(orig.ID, uuid.New()),
@@ -595,6 +687,9 @@ func TemplateVersion(t testing.TB, db database.Store, orig database.TemplateVers
 			Readme:         takeFirst(orig.Readme, namesgenerator.GetRandomName(1)),
 			JobID:          takeFirst(orig.JobID, uuid.New()),
 			CreatedBy:      takeFirst(orig.CreatedBy, uuid.New()),
+			GitCommitSha:   orig.GitCommitSha,
+			GitBranch:      orig.GitBranch,
+			GitTag:         orig.GitTag,
 		})
 		if err != nil {
 			return err
@@ -647,6 +742,7 @@ func TemplateVersionParameter(t testing.TB, db database.Store, orig database.Tem
 		DisplayName:         takeFirst(orig.DisplayName, namesgenerator.GetRandomName(1)),
 		DisplayOrder:        takeFirst(orig.DisplayOrder, 0),
 		Ephemeral:           takeFirst(orig.Ephemeral, false),
+		DisplayCondition:    takeFirst(orig.DisplayCondition, ""),
 	})
 	require.NoError(t, err, "insert template version parameter")
 	return version
@@ -674,6 +770,12 @@ func WorkspaceAgentStat(t testing.TB, db database.Store, orig database.Workspace
 		SessionCountReconnectingPTY: takeFirst(orig.SessionCountReconnectingPTY, 0),
 		SessionCountSSH:             takeFirst(orig.SessionCountSSH, 0),
 		ConnectionMedianLatencyMS:   takeFirst(orig.ConnectionMedianLatencyMS, 0),
+		CPUUsedCores:                takeFirst(orig.CPUUsedCores, 0),
+		CPUTotalCores:               takeFirst(orig.CPUTotalCores, 0),
+		MemoryUsedBytes:             takeFirst(orig.MemoryUsedBytes, 0),
+		MemoryTotalBytes:            takeFirst(orig.MemoryTotalBytes, 0),
+		DiskUsedBytes:               takeFirst(orig.DiskUsedBytes, 0),
+		DiskTotalBytes:              takeFirst(orig.DiskTotalBytes, 0),
 	})
 	require.NoError(t, err, "insert workspace agent stat")
 	return scheme
@@ -704,6 +806,104 @@ func OAuth2ProviderAppSecret(t testing.TB, db database.Store, seed database.OAut
 	return app
 }
 
+func OAuth2ProviderAppCode(t testing.TB, db database.Store, seed database.OAuth2ProviderAppCode) database.OAuth2ProviderAppCode {
+	code, err := db.InsertOAuth2ProviderAppCode(genCtx, database.InsertOAuth2ProviderAppCodeParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:     takeFirst(seed.ExpiresAt, dbtime.Now().Add(10*time.Minute)),
+		SecretPrefix:  takeFirstSlice(seed.SecretPrefix, []byte("prefix")),
+		HashedSecret:  takeFirstSlice(seed.HashedSecret, []byte("hashed-secret")),
+		AppID:         takeFirst(seed.AppID, uuid.New()),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		Nonce:         seed.Nonce,
+		CodeChallenge: seed.CodeChallenge,
+	})
+	require.NoError(t, err, "insert oauth2 app code")
+	return code
+}
+
+func OAuth2ProviderAppToken(t testing.TB, db database.Store, seed database.OAuth2ProviderAppToken) database.OAuth2ProviderAppToken {
+	token, err := db.InsertOAuth2ProviderAppToken(genCtx, database.InsertOAuth2ProviderAppTokenParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		ExpiresAt:   takeFirst(seed.ExpiresAt, dbtime.Now().Add(time.Hour)),
+		HashPrefix:  takeFirstSlice(seed.HashPrefix, []byte("prefix")),
+		RefreshHash: takeFirstSlice(seed.RefreshHash, []byte("hashed-secret")),
+		AppSecretID: takeFirst(seed.AppSecretID, uuid.New()),
+		APIKeyID:    takeFirst(seed.APIKeyID, uuid.New().String()),
+	})
+	require.NoError(t, err, "insert oauth2 app token")
+	return token
+}
+
+func Webhook(t testing.TB, db database.Store, seed database.Webhook) database.Webhook {
+	webhook, err := db.InsertWebhook(genCtx, database.InsertWebhookParams{
+		ID:          takeFirst(seed.ID, uuid.New()),
+		Url:         takeFirst(seed.Url, "https://localhost/webhook"),
+		Secret:      takeFirst(seed.Secret, "secret"),
+		SecretKeyID: seed.SecretKeyID,
+		Events:      takeFirstSlice(seed.Events, []string{"workspace_build.succeeded"}),
+		CreatedAt:   takeFirst(seed.CreatedAt, dbtime.Now()),
+		UpdatedAt:   takeFirst(seed.UpdatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert webhook")
+	return webhook
+}
+
+func WebhookDelivery(t testing.TB, db database.Store, seed database.WebhookDelivery) database.WebhookDelivery {
+	delivery, err := db.InsertWebhookDelivery(genCtx, database.InsertWebhookDeliveryParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		WebhookID:     takeFirst(seed.WebhookID, uuid.New()),
+		Event:         takeFirst(seed.Event, "workspace_build.succeeded"),
+		Payload:       takeFirstSlice(seed.Payload, json.RawMessage("{}")),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		NextAttemptAt: seed.NextAttemptAt,
+	})
+	require.NoError(t, err, "insert webhook delivery")
+	return delivery
+}
+
+func NotificationMessage(t testing.TB, db database.Store, seed database.NotificationMessage) database.NotificationMessage {
+	msg, err := db.InsertNotificationMessage(genCtx, database.InsertNotificationMessageParams{
+		ID:            takeFirst(seed.ID, uuid.New()),
+		UserID:        takeFirst(seed.UserID, uuid.New()),
+		Template:      takeFirst(seed.Template, "user_account_created"),
+		Labels:        takeFirstSlice(seed.Labels, json.RawMessage("{}")),
+		CreatedAt:     takeFirst(seed.CreatedAt, dbtime.Now()),
+		NextAttemptAt: seed.NextAttemptAt,
+		Method:        takeFirst(seed.Method, "smtp"),
+	})
+	require.NoError(t, err, "insert notification message")
+	return msg
+}
+
+func InboxNotification(t testing.TB, db database.Store, seed database.InboxNotification) database.InboxNotification {
+	notif, err := db.InsertInboxNotification(genCtx, database.InsertInboxNotificationParams{
+		ID:        takeFirst(seed.ID, uuid.New()),
+		UserID:    takeFirst(seed.UserID, uuid.New()),
+		Template:  takeFirst(seed.Template, "user_account_created"),
+		Title:     takeFirst(seed.Title, "Your Coder account has been created"),
+		Content:   takeFirst(seed.Content, "An admin created a Coder account for you."),
+		CreatedAt: takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert inbox notification")
+	return notif
+}
+
+func Passkey(t testing.TB, db database.Store, seed database.Passkey) database.Passkey {
+	passkey, err := db.InsertPasskey(genCtx, database.InsertPasskeyParams{
+		ID:           takeFirst(seed.ID, uuid.New()),
+		UserID:       takeFirst(seed.UserID, uuid.New()),
+		Name:         takeFirst(seed.Name, namesgenerator.GetRandomName(1)),
+		CredentialID: takeFirstSlice(seed.CredentialID, []byte(must(cryptorand.String(16)))),
+		PublicKey:    takeFirstSlice(seed.PublicKey, []byte(must(cryptorand.String(32)))),
+		SignCount:    seed.SignCount,
+		CreatedAt:    takeFirst(seed.CreatedAt, dbtime.Now()),
+	})
+	require.NoError(t, err, "insert passkey")
+	return passkey
+}
+
 func must[V any](v V, err error) V {
 	if err != nil {
 		panic(err)
@@ -1,6 +1,7 @@
 package dbmem
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/provisionertags"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/rbac/regosql"
 	"github.com/coder/coder/v2/coderd/util/slice"
@@ -119,42 +121,81 @@ type data struct {
 	organizationMembers []database.OrganizationMember
 	users               []database.User
 	userLinks           []database.UserLink
+	userSCIMExternalIDs []database.UserSCIMExternalID
 
 	// New tables
-	workspaceAgentStats           []database.WorkspaceAgentStat
-	auditLogs                     []database.AuditLog
-	dbcryptKeys                   []database.DBCryptKey
-	files                         []database.File
-	externalAuthLinks             []database.ExternalAuthLink
-	gitSSHKey                     []database.GitSSHKey
-	groupMembers                  []database.GroupMember
-	groups                        []database.Group
-	licenses                      []database.License
-	oauth2ProviderApps            []database.OAuth2ProviderApp
-	oauth2ProviderAppSecrets      []database.OAuth2ProviderAppSecret
-	parameterSchemas              []database.ParameterSchema
-	provisionerDaemons            []database.ProvisionerDaemon
-	provisionerJobLogs            []database.ProvisionerJobLog
-	provisionerJobs               []database.ProvisionerJob
-	replicas                      []database.Replica
-	templateVersions              []database.TemplateVersionTable
-	templateVersionParameters     []database.TemplateVersionParameter
-	templateVersionVariables      []database.TemplateVersionVariable
-	templates                     []database.TemplateTable
-	workspaceAgents               []database.WorkspaceAgent
-	workspaceAgentMetadata        []database.WorkspaceAgentMetadatum
-	workspaceAgentLogs            []database.WorkspaceAgentLog
-	workspaceAgentLogSources      []database.WorkspaceAgentLogSource
-	workspaceAgentScripts         []database.WorkspaceAgentScript
-	workspaceApps                 []database.WorkspaceApp
-	workspaceAppStatsLastInsertID int64
-	workspaceAppStats             []database.WorkspaceAppStat
-	workspaceBuilds               []database.WorkspaceBuildTable
-	workspaceBuildParameters      []database.WorkspaceBuildParameter
-	workspaceResourceMetadata     []database.WorkspaceResourceMetadatum
-	workspaceResources            []database.WorkspaceResource
-	workspaces                    []database.Workspace
-	workspaceProxies              []database.WorkspaceProxy
+	workspaceAgentStats              []database.WorkspaceAgentStat
+	auditLogs                        []database.AuditLog
+	connectionLogs                   []database.ConnectionLog
+	customRoles                      []database.CustomRole
+	dbcryptKeys                      []database.DBCryptKey
+	files                            []database.File
+	externalAuthLinks                []database.ExternalAuthLink
+	gitSSHKey                        []database.GitSSHKey
+	groupMembers                     []database.GroupMember
+	groupSyncRuns                    []database.GroupSyncRun
+	groups                           []database.Group
+	inboxNotifications               []database.InboxNotification
+	licenses                         []database.License
+	loginFailures                    []database.LoginFailure
+	notificationMessages             []database.NotificationMessage
+	notificationPreferences          []database.NotificationPreference
+	oauth2ProviderApps               []database.OAuth2ProviderApp
+	oauth2ProviderAppSecrets         []database.OAuth2ProviderAppSecret
+	oauth2ProviderAppCodes           []database.OAuth2ProviderAppCode
+	oauth2ProviderAppTokens          []database.OAuth2ProviderAppToken
+	organizationQuotas               []database.OrganizationQuota
+	organizationTemplateVariables    []database.OrganizationTemplateVariableValue
+	parameterSchemas                 []database.ParameterSchema
+	passkeys                         []database.Passkey
+	provisionerDaemons               []database.ProvisionerDaemon
+	provisionerJobLogArchives        []database.ProvisionerJobLogArchive
+	provisionerJobLogs               []database.ProvisionerJobLog
+	provisionerJobs                  []database.ProvisionerJob
+	provisionerKeys                  []database.ProvisionerKey
+	rateLimitCounters                []database.RateLimitCounter
+	replicas                         []database.Replica
+	sessionRecordings                []database.SessionRecording
+	templateVariableValues           []database.TemplateVariableValue
+	templateVersions                 []database.TemplateVersionTable
+	templateVersionParameters        []database.TemplateVersionParameter
+	templateVersionPresets           []database.TemplateVersionPreset
+	templateVersionVariables         []database.TemplateVersionVariable
+	templates                        []database.TemplateTable
+	twoFactorChallenges              []database.TwoFactorChallenge
+	twoFactorPolicies                []database.TwoFactorPolicy
+	unknownLoginFailures             []database.UnknownLoginFailure
+	userActivity                     []database.UserActivity
+	userLockouts                     []database.UserLockout
+	userRecoveryCodes                []database.UserRecoveryCode
+	webhooks                         []database.Webhook
+	webhookDeliveries                []database.WebhookDelivery
+	workspaceAgents                  []database.WorkspaceAgent
+	workspaceAgentGPUs               []database.WorkspaceAgentGPU
+	workspaceAgentMetadata           []database.WorkspaceAgentMetadatum
+	workspaceAgentMetadataHistory    []database.WorkspaceAgentMetadataHistory
+	workspaceAgentLogs               []database.WorkspaceAgentLog
+	workspaceAgentPortShares         []database.WorkspaceAgentPortShare
+	workspaceAgentPorts              []database.WorkspaceAgentPort
+	workspaceAgentPreviousAuthTokens []database.WorkspaceAgentPreviousAuthToken
+	workspaceAgentLogSources         []database.WorkspaceAgentLogSource
+	workspaceAgentScripts            []database.WorkspaceAgentScript
+	workspaceApps                    []database.WorkspaceApp
+	workspaceAppStatsLastInsertID    int64
+	workspaceAppStats                []database.WorkspaceAppStat
+	workspaceBuilds                  []database.WorkspaceBuildTable
+	workspaceBuildParameters         []database.WorkspaceBuildParameter
+	workspaceDrift                   []database.WorkspaceDrift
+	workspaceEvents                  []database.WorkspaceEvent
+	workspaceParameterChanges        []database.WorkspaceParameterChange
+	workspaceResourceCosts           []database.WorkspaceResourceCost
+	workspaceResourceMetadata        []database.WorkspaceResourceMetadatum
+	workspaceResources               []database.WorkspaceResource
+	workspaceSnapshots               []database.WorkspaceSnapshot
+	workspaces                       []database.Workspace
+	workspaceFavorites               []database.WorkspaceFavorite
+	workspaceProxies                 []database.WorkspaceProxy
+	workspaceProxyHealth             []database.WorkspaceProxyHealth
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
 	locks                   map[int64]struct{}
@@ -168,6 +209,7 @@ type data struct {
 	appSecurityKey          string
 	oauthSigningKey         string
 	lastLicenseID           int32
+	licenseGraceStartedAt   string
 	defaultProxyDisplayName string
 	defaultProxyIconURL     string
 }
@@ -260,8 +302,37 @@ func (tx *fakeTx) releaseLocks() {
 	tx.locks = map[int64]struct{}{}
 }
 
+func (q *FakeQuerier) DeleteUnknownLoginFailure(_ context.Context, arg database.DeleteUnknownLoginFailureParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, failure := range q.unknownLoginFailures {
+		if bytes.Equal(failure.LoginHash, arg.LoginHash) && failure.IPAddress.IPNet.String() == arg.IPAddress.IPNet.String() {
+			q.unknownLoginFailures = append(q.unknownLoginFailures[:i], q.unknownLoginFailures[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentAuthTokenByIDForUpdate(_ context.Context, id uuid.UUID) (uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, agent := range q.workspaceAgents {
+		if agent.ID == id {
+			return agent.AuthToken, nil
+		}
+	}
+	return uuid.Nil, sql.ErrNoRows
+}
+
 // InTx doesn't rollback data properly for in-memory yet.
-func (q *FakeQuerier) InTx(fn func(database.Store) error, _ *sql.TxOptions) error {
+func (q *FakeQuerier) InTx(fn func(database.Store) error, _ *database.TxOptions) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 	tx := &fakeTx{
@@ -299,6 +370,7 @@ func convertUsers(users []database.User, count int64) []database.GetUsersRow {
 			AvatarURL:      u.AvatarURL,
 			Deleted:        u.Deleted,
 			LastSeenAt:     u.LastSeenAt,
+			IPAllowlist:    u.IPAllowlist,
 			Count:          count,
 		}
 	}
@@ -340,6 +412,43 @@ func mapAgentStatus(dbAgent database.WorkspaceAgent, agentInactiveDisconnectTime
 	return status
 }
 
+// deleteOAuth2ProviderAppTokensNoLock deletes the tokens matching the filter
+// along with the API keys they were issued as, like the database trigger does.
+func (q *FakeQuerier) deleteOAuth2ProviderAppTokensNoLock(filter func(database.OAuth2ProviderAppToken) bool) {
+	tokens := []database.OAuth2ProviderAppToken{}
+	keyIDs := []string{}
+	for _, token := range q.oauth2ProviderAppTokens {
+		if filter(token) {
+			keyIDs = append(keyIDs, token.APIKeyID)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	q.oauth2ProviderAppTokens = tokens
+	if len(keyIDs) == 0 {
+		return
+	}
+
+	keys := []database.APIKey{}
+	for _, key := range q.apiKeys {
+		if !slices.Contains(keyIDs, key.ID) {
+			keys = append(keys, key)
+		}
+	}
+	q.apiKeys = keys
+}
+
+// isPreviousWorkspaceAgentAuthTokenNoLock reports whether the token was
+// rotated out of the agent recently enough that it is still valid.
+func (q *FakeQuerier) isPreviousWorkspaceAgentAuthTokenNoLock(agentID uuid.UUID, authToken uuid.UUID) bool {
+	for _, token := range q.workspaceAgentPreviousAuthTokens {
+		if token.AgentID == agentID && token.AuthToken == authToken {
+			return dbtime.Now().Before(token.ExpiresAt)
+		}
+	}
+	return false
+}
+
 func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspaces []database.Workspace, count int64) []database.GetWorkspacesRow {
 	rows := make([]database.GetWorkspacesRow, 0, len(workspaces))
 	for _, w := range workspaces {
@@ -359,6 +468,11 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			DeletingAt:        w.DeletingAt,
 			Count:             count,
 			AutomaticUpdates:  w.AutomaticUpdates,
+			MaintenanceOptOut: w.MaintenanceOptOut,
+			UserACL:           w.UserACL,
+			Ephemeral:         w.Ephemeral,
+			EphemeralAPIKeyID: w.EphemeralAPIKeyID,
+			DormancyExempt:    w.DormancyExempt,
 		}
 
 		for _, t := range q.templates {
@@ -564,6 +678,38 @@ func (q *FakeQuerier) getWorkspaceAgentsByResourceIDsNoLock(_ context.Context, r
 	return workspaceAgents, nil
 }
 
+// getLatestBuildWorkspaceAgentsNoLock returns the agents in the latest build of
+// every workspace that is not deleted.
+func (q *FakeQuerier) getLatestBuildWorkspaceAgentsNoLock(ctx context.Context) ([]database.WorkspaceAgent, error) {
+	agents := make([]database.WorkspaceAgent, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("get latest workspace build: %w", err)
+		}
+		resources, err := q.getWorkspaceResourcesByJobIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace resources: %w", err)
+		}
+		resourceIDs := make([]uuid.UUID, 0, len(resources))
+		for _, resource := range resources {
+			resourceIDs = append(resourceIDs, resource.ID)
+		}
+		workspaceAgents, err := q.getWorkspaceAgentsByResourceIDsNoLock(ctx, resourceIDs)
+		if err != nil {
+			return nil, xerrors.Errorf("get workspace agents: %w", err)
+		}
+		agents = append(agents, workspaceAgents...)
+	}
+	return agents, nil
+}
+
 func (q *FakeQuerier) getWorkspaceAppByAgentIDAndSlugNoLock(_ context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	for _, app := range q.workspaceApps {
 		if app.AgentID != arg.AgentID {
@@ -591,6 +737,28 @@ func (q *FakeQuerier) getProvisionerJobByIDNoLock(_ context.Context, id uuid.UUI
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+// provisionerJobQueueOrderNoLock returns the indexes of q.provisionerJobs in
+// the order they are acquired, matching AcquireProvisionerJob.
+func (q *FakeQuerier) provisionerJobQueueOrderNoLock() []int {
+	order := make([]int, len(q.provisionerJobs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := q.provisionerJobs[order[i]], q.provisionerJobs[order[j]]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		aBuild := a.Type == database.ProvisionerJobTypeWorkspaceBuild
+		bBuild := b.Type == database.ProvisionerJobTypeWorkspaceBuild
+		if aBuild != bBuild {
+			return aBuild
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return order
+}
+
 func (q *FakeQuerier) getWorkspaceResourcesByJobIDNoLock(_ context.Context, jobID uuid.UUID) ([]database.WorkspaceResource, error) {
 	resources := make([]database.WorkspaceResource, 0)
 	for _, resource := range q.workspaceResources {
@@ -700,6 +868,22 @@ func minTime(t, u time.Time) time.Time {
 	return u
 }
 
+// truncateToDate mimics casting a timestamp to a date in the database.
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// userInactiveSince mimics the inactivity check of the user activity queries.
+// Users that have never been seen are only inactive if they were created
+// before the given time.
+func userInactiveSince(user database.User, inactiveSince time.Time) bool {
+	if !user.LastSeenAt.Before(inactiveSince) {
+		return false
+	}
+	return !user.LastSeenAt.IsZero() || user.CreatedAt.Before(inactiveSince)
+}
+
 func provisonerJobStatus(j database.ProvisionerJob) database.ProvisionerJobStatus {
 	if isNotNull(j.CompletedAt) {
 		if j.Error.String != "" {
@@ -734,6 +918,35 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+func (q *FakeQuerier) AcquireNotificationMessages(_ context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	due := []int{}
+	for index, msg := range q.notificationMessages {
+		if msg.NextAttemptAt.Valid && !msg.NextAttemptAt.Time.After(arg.Now) {
+			due = append(due, index)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return q.notificationMessages[due[i]].NextAttemptAt.Time.Before(q.notificationMessages[due[j]].NextAttemptAt.Time)
+	})
+	if len(due) > int(arg.LimitOpt) {
+		due = due[:arg.LimitOpt]
+	}
+
+	acquired := make([]database.NotificationMessage, 0, len(due))
+	for _, index := range due {
+		q.notificationMessages[index].NextAttemptAt = sql.NullTime{Time: arg.LeaseUntil, Valid: true}
+		acquired = append(acquired, q.notificationMessages[index])
+	}
+	return acquired, nil
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -742,7 +955,8 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for index, provisionerJob := range q.provisionerJobs {
+	for _, index := range q.provisionerJobQueueOrderNoLock() {
+		provisionerJob := q.provisionerJobs[index]
 		if provisionerJob.StartedAt.Valid {
 			continue
 		}
@@ -757,6 +971,9 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 		if !found {
 			continue
 		}
+		if arg.OrganizationID.Valid && provisionerJob.OrganizationID != arg.OrganizationID.UUID {
+			continue
+		}
 		tags := map[string]string{}
 		if arg.Tags != nil {
 			err := json.Unmarshal(arg.Tags, &tags)
@@ -765,19 +982,7 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 			}
 		}
 
-		missing := false
-		for key, value := range provisionerJob.Tags {
-			provided, found := tags[key]
-			if !found {
-				missing = true
-				break
-			}
-			if provided != value {
-				missing = true
-				break
-			}
-		}
-		if missing {
+		if !provisionertags.Matches(tags, provisionerJob.Tags, provisionerJob.TagRequirements) {
 			continue
 		}
 		provisionerJob.StartedAt = arg.StartedAt
@@ -794,6 +999,35 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 	return database.ProvisionerJob{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) AcquireWebhookDeliveries(_ context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	due := []int{}
+	for index, delivery := range q.webhookDeliveries {
+		if delivery.NextAttemptAt.Valid && !delivery.NextAttemptAt.Time.After(arg.Now) {
+			due = append(due, index)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return q.webhookDeliveries[due[i]].NextAttemptAt.Time.Before(q.webhookDeliveries[due[j]].NextAttemptAt.Time)
+	})
+	if len(due) > int(arg.LimitOpt) {
+		due = due[:arg.LimitOpt]
+	}
+
+	acquired := make([]database.WebhookDelivery, 0, len(due))
+	for _, index := range due {
+		q.webhookDeliveries[index].NextAttemptAt = sql.NullTime{Time: arg.LeaseUntil, Valid: true}
+		acquired = append(acquired, q.webhookDeliveries[index])
+	}
+	return acquired, nil
+}
+
 func (q *FakeQuerier) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1000,6 +1234,66 @@ func (*FakeQuerier) CleanTailnetTunnels(context.Context) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) CountLoginFailuresByUserID(_ context.Context, arg database.CountLoginFailuresByUserIDParams) (int64, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return 0, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, failure := range q.loginFailures {
+		if failure.UserID == arg.UserID && !failure.LastFailedAt.Before(arg.Since) {
+			count += int64(failure.FailedAttempts)
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) CountUnreadInboxNotificationsByUserID(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, notif := range q.inboxNotifications {
+		if notif.UserID == userID && !notif.ReadAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) CountUnusedUserRecoveryCodes(_ context.Context, userID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, code := range q.userRecoveryCodes {
+		if code.UserID == userID && !code.UsedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) CustomRolesByName(_ context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	roles := make([]database.CustomRole, 0)
+	for _, role := range q.customRoles {
+		if len(lookupRoles) > 0 && !slices.Contains(lookupRoles, role.Name) {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	slices.SortFunc(roles, func(a, b database.CustomRole) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return roles, nil
+}
+
 func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1010,24 +1304,68 @@ func (q *FakeQuerier) DeleteAPIKeyByID(_ context.Context, id string) error {
 		}
 		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
 		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
+		q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+			return token.APIKeyID == id
+		})
 		return nil
 	}
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteAPIKeyByIDReturning(_ context.Context, id string) (database.APIKey, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, apiKey := range q.apiKeys {
+		if apiKey.ID != id {
+			continue
+		}
+		q.apiKeys[index] = q.apiKeys[len(q.apiKeys)-1]
+		q.apiKeys = q.apiKeys[:len(q.apiKeys)-1]
+		q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+			return token.APIKeyID == id
+		})
+		return apiKey, nil
+	}
+	return database.APIKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteAPIKeysByUserID(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
 	for i := len(q.apiKeys) - 1; i >= 0; i-- {
 		if q.apiKeys[i].UserID == userID {
+			id := q.apiKeys[i].ID
 			q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
+			q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+				return token.APIKeyID == id
+			})
 		}
 	}
 
 	return nil
 }
 
+func (q *FakeQuerier) DeleteAPIKeysByUserIDReturning(_ context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deleted := make([]database.APIKey, 0)
+	for i := len(q.apiKeys) - 1; i >= 0; i-- {
+		if q.apiKeys[i].UserID == userID {
+			deleted = append(deleted, q.apiKeys[i])
+			id := q.apiKeys[i].ID
+			q.apiKeys = append(q.apiKeys[:i], q.apiKeys[i+1:]...)
+			q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+				return token.APIKeyID == id
+			})
+		}
+	}
+
+	return deleted, nil
+}
+
 func (*FakeQuerier) DeleteAllTailnetClientSubscriptions(_ context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1063,6 +1401,21 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteExpiredTwoFactorChallenges(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	challenges := make([]database.TwoFactorChallenge, 0, len(q.twoFactorChallenges))
+	for _, challenge := range q.twoFactorChallenges {
+		if challenge.ExpiresAt.Before(beforeTime) {
+			continue
+		}
+		challenges = append(challenges, challenge)
+	}
+	q.twoFactorChallenges = challenges
+	return nil
+}
+
 func (q *FakeQuerier) DeleteExternalAuthLink(_ context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -1171,6 +1524,23 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteLoginFailure(_ context.Context, arg database.DeleteLoginFailureParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, failure := range q.loginFailures {
+		if failure.UserID == arg.UserID && failure.IPAddress.IPNet.String() == arg.IPAddress.IPNet.String() {
+			q.loginFailures = append(q.loginFailures[:i], q.loginFailures[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1184,16 +1554,42 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppByID(_ context.Context, id uuid.UUI
 			for _, secret := range q.oauth2ProviderAppSecrets {
 				if secret.AppID != id {
 					secrets = append(secrets, secret)
+					continue
 				}
+				q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+					return token.AppSecretID == secret.ID
+				})
 			}
 			q.oauth2ProviderAppSecrets = secrets
 
+			codes := []database.OAuth2ProviderAppCode{}
+			for _, code := range q.oauth2ProviderAppCodes {
+				if code.AppID != id {
+					codes = append(codes, code)
+				}
+			}
+			q.oauth2ProviderAppCodes = codes
+
 			return nil
 		}
 	}
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			q.oauth2ProviderAppCodes[index] = q.oauth2ProviderAppCodes[len(q.oauth2ProviderAppCodes)-1]
+			q.oauth2ProviderAppCodes = q.oauth2ProviderAppCodes[:len(q.oauth2ProviderAppCodes)-1]
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1202,12 +1598,63 @@ func (q *FakeQuerier) DeleteOAuth2ProviderAppSecretByID(_ context.Context, id uu
 		if secret.ID == id {
 			q.oauth2ProviderAppSecrets[index] = q.oauth2ProviderAppSecrets[len(q.oauth2ProviderAppSecrets)-1]
 			q.oauth2ProviderAppSecrets = q.oauth2ProviderAppSecrets[:len(q.oauth2ProviderAppSecrets)-1]
+			q.deleteOAuth2ProviderAppTokensNoLock(func(token database.OAuth2ProviderAppToken) bool {
+				return token.AppSecretID == id
+			})
 			return nil
 		}
 	}
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteOldGroupSyncRuns(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	runs := make([]database.GroupSyncRun, 0, len(q.groupSyncRuns))
+	for _, run := range q.groupSyncRuns {
+		if run.StartedAt.Before(beforeTime) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	q.groupSyncRuns = runs
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldLoginFailures(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	failures := make([]database.LoginFailure, 0, len(q.loginFailures))
+	for _, failure := range q.loginFailures {
+		if failure.LastFailedAt.Before(beforeTime) {
+			continue
+		}
+		failures = append(failures, failure)
+	}
+	q.loginFailures = failures
+
+	lockouts := make([]database.UserLockout, 0, len(q.userLockouts))
+	for _, lockout := range q.userLockouts {
+		if lockout.LockedUntil.Before(beforeTime) {
+			continue
+		}
+		lockouts = append(lockouts, lockout)
+	}
+	q.userLockouts = lockouts
+
+	unknownFailures := make([]database.UnknownLoginFailure, 0, len(q.unknownLoginFailures))
+	for _, failure := range q.unknownLoginFailures {
+		if failure.LastFailedAt.Before(beforeTime) {
+			continue
+		}
+		unknownFailures = append(unknownFailures, failure)
+	}
+	q.unknownLoginFailures = unknownFailures
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1227,6 +1674,36 @@ func (q *FakeQuerier) DeleteOldProvisionerDaemons(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldRateLimitCounters(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	counters := make([]database.RateLimitCounter, 0, len(q.rateLimitCounters))
+	for _, counter := range q.rateLimitCounters {
+		if counter.WindowStart.Before(beforeTime) {
+			continue
+		}
+		counters = append(counters, counter)
+	}
+	q.rateLimitCounters = counters
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldUserActivity(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	activity := make([]database.UserActivity, 0, len(q.userActivity))
+	for _, a := range q.userActivity {
+		if a.Hour.Before(beforeTime) {
+			continue
+		}
+		activity = append(activity, a)
+	}
+	q.userActivity = activity
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1253,6 +1730,21 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	return nil
 }
 
+func (q *FakeQuerier) DeleteOldWorkspaceAgentMetadataHistory(_ context.Context, beforeTime time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	history := make([]database.WorkspaceAgentMetadataHistory, 0, len(q.workspaceAgentMetadataHistory))
+	for _, h := range q.workspaceAgentMetadataHistory {
+		if h.CollectedAt.Before(beforeTime) {
+			continue
+		}
+		history = append(history, h)
+	}
+	q.workspaceAgentMetadataHistory = history
+	return nil
+}
+
 func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -1272,19 +1764,167 @@ func (q *FakeQuerier) DeleteOldWorkspaceAgentStats(_ context.Context) error {
 	return nil
 }
 
-func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
+func (q *FakeQuerier) DeleteOldWorkspaceEvents(_ context.Context, beforeTime time.Time) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
-	for i, replica := range q.replicas {
-		if replica.UpdatedAt.Before(before) {
-			q.replicas = append(q.replicas[:i], q.replicas[i+1:]...)
+	events := make([]database.WorkspaceEvent, 0, len(q.workspaceEvents))
+	for _, e := range q.workspaceEvents {
+		if e.CreatedAt.Before(beforeTime) {
+			continue
+		}
+		events = append(events, e)
+	}
+	q.workspaceEvents = events
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOrganization(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, workspace := range q.workspaces {
+		if workspace.OrganizationID == id {
+			return errForeignKeyConstraint
+		}
+	}
+
+	for i, organization := range q.organizations {
+		if organization.ID != id {
+			continue
+		}
+		q.organizations = append(q.organizations[:i], q.organizations[i+1:]...)
+
+		members := q.organizationMembers[:0]
+		for _, member := range q.organizationMembers {
+			if member.OrganizationID != id {
+				members = append(members, member)
+			}
+		}
+		q.organizationMembers = members
+
+		groups := q.groups[:0]
+		for _, group := range q.groups {
+			if group.OrganizationID != id {
+				groups = append(groups, group)
+			}
+		}
+		q.groups = groups
+
+		templates := q.templates[:0]
+		for _, template := range q.templates {
+			if template.OrganizationID != id {
+				templates = append(templates, template)
+			}
+		}
+		q.templates = templates
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteOrganizationMember(_ context.Context, arg database.DeleteOrganizationMemberParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, member := range q.organizationMembers {
+		if member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID {
+			q.organizationMembers = append(q.organizationMembers[:i], q.organizationMembers[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOrganizationTemplateVariableValue(_ context.Context, arg database.DeleteOrganizationTemplateVariableValueParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, value := range q.organizationTemplateVariables {
+		if value.OrganizationID == arg.OrganizationID && value.Name == arg.Name {
+			q.organizationTemplateVariables = append(q.organizationTemplateVariables[:i], q.organizationTemplateVariables[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeletePasskeyByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, passkey := range q.passkeys {
+		if passkey.ID == id {
+			q.passkeys = append(q.passkeys[:i], q.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteProvisionerJobLogsByJobID(_ context.Context, jobID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	logs := make([]database.ProvisionerJobLog, 0, len(q.provisionerJobLogs))
+	for _, jobLog := range q.provisionerJobLogs {
+		if jobLog.JobID == jobID {
+			continue
+		}
+		logs = append(logs, jobLog)
+	}
+	q.provisionerJobLogs = logs
+	return nil
+}
+
+func (q *FakeQuerier) DeleteProvisionerKey(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID == id {
+			q.provisionerKeys = append(q.provisionerKeys[:i], q.provisionerKeys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteReplicasUpdatedBefore(_ context.Context, before time.Time) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, replica := range q.replicas {
+		if replica.UpdatedAt.Before(before) {
+			q.replicas = append(q.replicas[:i], q.replicas[i+1:]...)
 		}
 	}
 
 	return nil
 }
 
+func (q *FakeQuerier) DeleteSessionRecordingByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, recording := range q.sessionRecordings {
+		if recording.ID == id {
+			q.sessionRecordings = append(q.sessionRecordings[:i], q.sessionRecordings[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (*FakeQuerier) DeleteTailnetAgent(context.Context, database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	return database.DeleteTailnetAgentRow{}, ErrUnimplemented
 }
@@ -1315,6 +1955,176 @@ func (*FakeQuerier) DeleteTailnetTunnel(_ context.Context, arg database.DeleteTa
 	return database.DeleteTailnetTunnelRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteTemplateVariableValue(_ context.Context, arg database.DeleteTemplateVariableValueParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, value := range q.templateVariableValues {
+		if value.TemplateID == arg.TemplateID && value.Name == arg.Name {
+			q.templateVariableValues = append(q.templateVariableValues[:i], q.templateVariableValues[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTemplateVersionPreset(_ context.Context, arg database.DeleteTemplateVersionPresetParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == arg.TemplateVersionID && preset.Name == arg.Name {
+			q.templateVersionPresets = append(q.templateVersionPresets[:i], q.templateVersionPresets[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteTwoFactorChallengeByID(_ context.Context, id uuid.UUID) (database.TwoFactorChallenge, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, challenge := range q.twoFactorChallenges {
+		if challenge.ID == id {
+			q.twoFactorChallenges = append(q.twoFactorChallenges[:i], q.twoFactorChallenges[i+1:]...)
+			return challenge, nil
+		}
+	}
+	return database.TwoFactorChallenge{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) DeleteTwoFactorPolicyByRole(_ context.Context, role string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, policy := range q.twoFactorPolicies {
+		if policy.Role == role {
+			q.twoFactorPolicies = append(q.twoFactorPolicies[:i], q.twoFactorPolicies[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserLockout(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	failures := make([]database.LoginFailure, 0, len(q.loginFailures))
+	for _, failure := range q.loginFailures {
+		if failure.UserID != userID {
+			failures = append(failures, failure)
+		}
+	}
+	q.loginFailures = failures
+
+	for i, lockout := range q.userLockouts {
+		if lockout.UserID == userID {
+			q.userLockouts = append(q.userLockouts[:i], q.userLockouts[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserRecoveryCodesByUserID(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	codes := make([]database.UserRecoveryCode, 0, len(q.userRecoveryCodes))
+	for _, code := range q.userRecoveryCodes {
+		if code.UserID == userID {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	q.userRecoveryCodes = codes
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, webhook := range q.webhooks {
+		if webhook.ID != id {
+			continue
+		}
+		q.webhooks[index] = q.webhooks[len(q.webhooks)-1]
+		q.webhooks = q.webhooks[:len(q.webhooks)-1]
+
+		deliveries := []database.WebhookDelivery{}
+		for _, delivery := range q.webhookDeliveries {
+			if delivery.WebhookID != id {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		q.webhookDeliveries = deliveries
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPortShare(_ context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			q.workspaceAgentPortShares = append(q.workspaceAgentPortShares[:i], q.workspaceAgentPortShares[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(_ context.Context, agentID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, token := range q.workspaceAgentPreviousAuthTokens {
+		if token.AgentID == agentID {
+			q.workspaceAgentPreviousAuthTokens = append(q.workspaceAgentPreviousAuthTokens[:index], q.workspaceAgentPreviousAuthTokens[index+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceFavorite(_ context.Context, arg database.DeleteWorkspaceFavoriteParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, favorite := range q.workspaceFavorites {
+		if favorite.UserID == arg.UserID && favorite.WorkspaceID == arg.WorkspaceID {
+			q.workspaceFavorites = append(q.workspaceFavorites[:i], q.workspaceFavorites[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1385,6 +2195,39 @@ func (q *FakeQuerier) GetAPIKeysLastUsedAfter(_ context.Context, after time.Time
 	return apiKeys, nil
 }
 
+// sessionLoginTypes are the login types of keys users get by signing in.
+var sessionLoginTypes = []database.LoginType{
+	database.LoginTypePassword,
+	database.LoginTypeGithub,
+	database.LoginTypeOIDC,
+	database.LoginTypeLDAP,
+}
+
+func (q *FakeQuerier) GetActiveSessionsByUserID(_ context.Context, arg database.GetActiveSessionsByUserIDParams) ([]database.APIKey, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	sessions := make([]database.APIKey, 0)
+	for _, key := range q.apiKeys {
+		if key.UserID != arg.UserID || !slices.Contains(sessionLoginTypes, key.LoginType) || key.Scope != database.APIKeyScopeAll {
+			continue
+		}
+		if !key.ExpiresAt.After(arg.Now) {
+			continue
+		}
+		sessions = append(sessions, key)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
 func (q *FakeQuerier) GetActiveUserCount(_ context.Context) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1460,6 +2303,98 @@ func (q *FakeQuerier) GetApplicationName(_ context.Context) (string, error) {
 	return q.applicationName, nil
 }
 
+func (q *FakeQuerier) GetAuditLogsKeyset(_ context.Context, arg database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.GetAuditLogsKeysetRow, 0, arg.LimitOpt)
+
+	// q.auditLogs are already sorted by time DESC, so no need to sort after the fact.
+	for _, alog := range q.auditLogs {
+		if arg.CursorID != uuid.Nil {
+			if alog.Time.After(arg.CursorTime) {
+				continue
+			}
+			if alog.Time.Equal(arg.CursorTime) && bytes.Compare(alog.ID[:], arg.CursorID[:]) >= 0 {
+				continue
+			}
+		}
+		if arg.Action != "" && !strings.Contains(string(alog.Action), arg.Action) {
+			continue
+		}
+		if arg.ResourceType != "" && !strings.Contains(string(alog.ResourceType), arg.ResourceType) {
+			continue
+		}
+		if arg.ResourceID != uuid.Nil && alog.ResourceID != arg.ResourceID {
+			continue
+		}
+		if arg.Username != "" {
+			user, err := q.getUserByIDNoLock(alog.UserID)
+			if err == nil && !strings.EqualFold(arg.Username, user.Username) {
+				continue
+			}
+		}
+		if arg.Email != "" {
+			user, err := q.getUserByIDNoLock(alog.UserID)
+			if err == nil && !strings.EqualFold(arg.Email, user.Email) {
+				continue
+			}
+		}
+		if !arg.DateFrom.IsZero() {
+			if alog.Time.Before(arg.DateFrom) {
+				continue
+			}
+		}
+		if !arg.DateTo.IsZero() {
+			if alog.Time.After(arg.DateTo) {
+				continue
+			}
+		}
+		if arg.BuildReason != "" {
+			workspaceBuild, err := q.getWorkspaceBuildByIDNoLock(context.Background(), alog.ResourceID)
+			if err == nil && !strings.EqualFold(arg.BuildReason, string(workspaceBuild.Reason)) {
+				continue
+			}
+		}
+
+		user, err := q.getUserByIDNoLock(alog.UserID)
+		userValid := err == nil
+
+		logs = append(logs, database.GetAuditLogsKeysetRow{
+			ID:               alog.ID,
+			Time:             alog.Time,
+			RequestID:        alog.RequestID,
+			OrganizationID:   alog.OrganizationID,
+			Ip:               alog.Ip,
+			UserAgent:        alog.UserAgent,
+			ResourceType:     alog.ResourceType,
+			ResourceID:       alog.ResourceID,
+			ResourceTarget:   alog.ResourceTarget,
+			ResourceIcon:     alog.ResourceIcon,
+			Action:           alog.Action,
+			Diff:             alog.Diff,
+			StatusCode:       alog.StatusCode,
+			AdditionalFields: alog.AdditionalFields,
+			UserID:           alog.UserID,
+			UserUsername:     sql.NullString{String: user.Username, Valid: userValid},
+			UserEmail:        sql.NullString{String: user.Email, Valid: userValid},
+			UserCreatedAt:    sql.NullTime{Time: user.CreatedAt, Valid: userValid},
+			UserStatus:       database.NullUserStatus{UserStatus: user.Status, Valid: userValid},
+			UserRoles:        user.RBACRoles,
+		})
+
+		if arg.LimitOpt > 0 && len(logs) >= int(arg.LimitOpt) {
+			break
+		}
+	}
+
+	return logs, nil
+}
+
 func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -1520,6 +2455,7 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 		logs = append(logs, database.GetAuditLogsOffsetRow{
 			ID:               alog.ID,
 			RequestID:        alog.RequestID,
+			Time:             alog.Time,
 			OrganizationID:   alog.OrganizationID,
 			Ip:               alog.Ip,
 			UserAgent:        alog.UserAgent,
@@ -1575,25 +2511,101 @@ func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.U
 			roles = append(roles, "organization-member:"+mem.OrganizationID.String())
 		}
 	}
-
-	var groups []string
-	for _, member := range q.groupMembers {
-		if member.UserID == userID {
-			groups = append(groups, member.GroupID.String())
+
+	var groups []string
+	for _, member := range q.groupMembers {
+		if member.UserID == userID {
+			groups = append(groups, member.GroupID.String())
+		}
+	}
+
+	if user == nil {
+		return database.GetAuthorizationUserRolesRow{}, sql.ErrNoRows
+	}
+
+	return database.GetAuthorizationUserRolesRow{
+		ID:          userID,
+		Username:    user.Username,
+		Status:      user.Status,
+		IPAllowlist: user.IPAllowlist,
+		Roles:       roles,
+		Groups:      groups,
+	}, nil
+}
+
+func (q *FakeQuerier) GetConnectionLogsOffset(_ context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.GetConnectionLogsOffsetRow, 0)
+	// q.connectionLogs are already sorted by time DESC, so no need to sort after the fact.
+	for _, clog := range q.connectionLogs {
+		if arg.Type != "" && string(clog.Type) != arg.Type {
+			continue
+		}
+		if arg.WorkspaceID != uuid.Nil && clog.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if arg.UserID != uuid.Nil && clog.UserID != arg.UserID {
+			continue
+		}
+		var username, ownerUsername string
+		if user, err := q.getUserByIDNoLock(clog.UserID); err == nil {
+			username = user.Username
+		}
+		if owner, err := q.getUserByIDNoLock(clog.WorkspaceOwnerID); err == nil {
+			ownerUsername = owner.Username
+		}
+		if arg.Username != "" && !strings.EqualFold(arg.Username, username) {
+			continue
+		}
+		if arg.WorkspaceOwner != "" && !strings.EqualFold(arg.WorkspaceOwner, ownerUsername) {
+			continue
+		}
+		if !arg.DateFrom.IsZero() && clog.Time.Before(arg.DateFrom) {
+			continue
+		}
+		if !arg.DateTo.IsZero() && clog.Time.After(arg.DateTo) {
+			continue
+		}
+
+		logs = append(logs, database.GetConnectionLogsOffsetRow{
+			ID:                     clog.ID,
+			Time:                   clog.Time,
+			OrganizationID:         clog.OrganizationID,
+			WorkspaceOwnerID:       clog.WorkspaceOwnerID,
+			WorkspaceID:            clog.WorkspaceID,
+			WorkspaceName:          clog.WorkspaceName,
+			AgentName:              clog.AgentName,
+			Type:                   clog.Type,
+			Port:                   clog.Port,
+			Ip:                     clog.Ip,
+			UserAgent:              clog.UserAgent,
+			UserID:                 clog.UserID,
+			APIKeyID:               clog.APIKeyID,
+			UserUsername:           username,
+			WorkspaceOwnerUsername: ownerUsername,
+		})
+	}
+
+	count := int64(len(logs))
+	for i := range logs {
+		logs[i].Count = count
+	}
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(logs) {
+			return []database.GetConnectionLogsOffsetRow{}, nil
 		}
+		logs = logs[arg.OffsetOpt:]
 	}
-
-	if user == nil {
-		return database.GetAuthorizationUserRolesRow{}, sql.ErrNoRows
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(logs) {
+		logs = logs[:arg.LimitOpt]
 	}
-
-	return database.GetAuthorizationUserRolesRow{
-		ID:       userID,
-		Username: user.Username,
-		Status:   user.Status,
-		Roles:    roles,
-		Groups:   groups,
-	}, nil
+	return logs, nil
 }
 
 func (q *FakeQuerier) GetDBCryptKeys(_ context.Context) ([]database.DBCryptKey, error) {
@@ -1758,6 +2770,47 @@ func (q *FakeQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (database
 	return stat, nil
 }
 
+func (q *FakeQuerier) GetDriftedWorkspaceCount(_ context.Context) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var count int64
+	for _, drift := range q.workspaceDrift {
+		if len(drift.DriftedResources) == 0 {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), drift.WorkspaceID)
+		if err != nil || workspace.Deleted {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (q *FakeQuerier) GetExpiredSessionRecordings(_ context.Context, arg database.GetExpiredSessionRecordingsParams) ([]database.SessionRecording, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	expired := make([]database.SessionRecording, 0)
+	for _, recording := range q.sessionRecordings {
+		if recording.ExpiresAt.Valid && !recording.ExpiresAt.Time.After(arg.Now) {
+			expired = append(expired, recording)
+		}
+	}
+	slices.SortFunc(expired, func(a, b database.SessionRecording) int {
+		return a.ExpiresAt.Time.Compare(b.ExpiresAt.Time)
+	})
+	if len(expired) > int(arg.LimitOpt) {
+		expired = expired[:arg.LimitOpt]
+	}
+	return expired, nil
+}
+
 func (q *FakeQuerier) GetExternalAuthLink(_ context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ExternalAuthLink{}, err
@@ -1789,6 +2842,19 @@ func (q *FakeQuerier) GetExternalAuthLinksByUserID(_ context.Context, userID uui
 	return gals, nil
 }
 
+func (q *FakeQuerier) GetFavoriteWorkspaceIDsByUserID(_ context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	ids := make([]uuid.UUID, 0)
+	for _, favorite := range q.workspaceFavorites {
+		if favorite.UserID == userID {
+			ids = append(ids, favorite.WorkspaceID)
+		}
+	}
+	return ids, nil
+}
+
 func (q *FakeQuerier) GetFileByHashAndCreator(_ context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.File{}, err
@@ -1847,6 +2913,7 @@ func (q *FakeQuerier) GetFileTemplates(_ context.Context, id uuid.UUID) ([]datab
 								TemplateCreatedBy:      template.CreatedBy,
 								UserACL:                template.UserACL,
 								GroupACL:               template.GroupACL,
+								Visibility:             template.Visibility,
 							})
 						}
 					}
@@ -1924,6 +2991,59 @@ func (q *FakeQuerier) GetGroupMembers(_ context.Context, id uuid.UUID) ([]databa
 	return users, nil
 }
 
+func (q *FakeQuerier) GetGroupSyncRuns(_ context.Context, arg database.GetGroupSyncRunsParams) ([]database.GroupSyncRun, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	runs := make([]database.GroupSyncRun, 0)
+	for _, run := range q.groupSyncRuns {
+		if arg.UserID != uuid.Nil && run.UserID.UUID != arg.UserID {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	slices.SortFunc(runs, func(a, b database.GroupSyncRun) int {
+		if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+			return c
+		}
+		return slice.Descending(a.ID.String(), b.ID.String())
+	})
+	if int(arg.LimitCount) < len(runs) {
+		runs = runs[:arg.LimitCount]
+	}
+	return runs, nil
+}
+
+func (q *FakeQuerier) GetGroupsByOrganizationAndUserID(_ context.Context, arg database.GetGroupsByOrganizationAndUserIDParams) ([]database.Group, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	groupIDs := make(map[uuid.UUID]struct{})
+	for _, member := range q.groupMembers {
+		if member.UserID == arg.UserID {
+			groupIDs[member.GroupID] = struct{}{}
+		}
+	}
+
+	groups := make([]database.Group, 0)
+	for _, group := range q.groups {
+		if _, ok := groupIDs[group.ID]; ok && group.OrganizationID == arg.OrganizationID {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups, nil
+}
+
 func (q *FakeQuerier) GetGroupsByOrganizationID(_ context.Context, id uuid.UUID) ([]database.Group, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1966,6 +3086,99 @@ func (q *FakeQuerier) GetHungProvisionerJobs(_ context.Context, hungSince time.T
 	return hungJobs, nil
 }
 
+func (q *FakeQuerier) GetInactiveUsers(_ context.Context, inactiveSince time.Time) ([]database.GetInactiveUsersRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetInactiveUsersRow, 0)
+	for _, user := range q.users {
+		if user.Deleted || user.Status == database.UserStatusSuspended || !userInactiveSince(user, inactiveSince) {
+			continue
+		}
+		row := database.GetInactiveUsersRow{
+			ID:         user.ID,
+			Username:   user.Username,
+			Email:      user.Email,
+			AvatarURL:  user.AvatarURL,
+			Status:     user.Status,
+			LastSeenAt: user.LastSeenAt,
+		}
+		var last *database.UserActivity
+		for i, a := range q.userActivity {
+			if a.UserID != user.ID {
+				continue
+			}
+			if last == nil || a.Hour.After(last.Hour) || (a.Hour.Equal(last.Hour) && a.Count > last.Count) {
+				last = &q.userActivity[i]
+			}
+		}
+		if last != nil {
+			row.LastActivityCategory = sql.NullString{String: last.Category, Valid: true}
+			row.LastActivityWorkspaceID = uuid.NullUUID{UUID: last.WorkspaceID, Valid: true}
+			row.LastActivityHour = sql.NullTime{Time: last.Hour, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetInactiveUsersRow) int {
+		if c := a.LastSeenAt.Compare(b.LastSeenAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Username, b.Username)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetInboxNotificationsByUserID(_ context.Context, arg database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	notifs := []database.InboxNotification{}
+	for _, notif := range q.inboxNotifications {
+		if notif.UserID != arg.UserID {
+			continue
+		}
+		if arg.UnreadOnly && notif.ReadAt.Valid {
+			continue
+		}
+		notifs = append(notifs, notif)
+	}
+	// Newest first, matching the SQL query.
+	sort.Slice(notifs, func(i, j int) bool {
+		if notifs[i].CreatedAt.Equal(notifs[j].CreatedAt) {
+			return notifs[i].ID.String() > notifs[j].ID.String()
+		}
+		return notifs[i].CreatedAt.After(notifs[j].CreatedAt)
+	})
+
+	if arg.AfterID != uuid.Nil {
+		found := false
+		for i, notif := range notifs {
+			if notif.ID == arg.AfterID {
+				notifs = notifs[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []database.InboxNotification{}, nil
+		}
+	}
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(notifs) {
+			return []database.InboxNotification{}, nil
+		}
+		notifs = notifs[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(notifs) {
+		notifs = notifs[:arg.LimitOpt]
+	}
+	return notifs, nil
+}
+
 func (q *FakeQuerier) GetLastUpdateCheck(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1976,6 +3189,27 @@ func (q *FakeQuerier) GetLastUpdateCheck(_ context.Context) (string, error) {
 	return string(q.lastUpdateCheck), nil
 }
 
+func (q *FakeQuerier) GetLatestWorkspaceAgentStatByAgentID(_ context.Context, agentID uuid.UUID) (database.WorkspaceAgentStat, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var latest database.WorkspaceAgentStat
+	found := false
+	for _, stat := range q.workspaceAgentStats {
+		if stat.AgentID != agentID {
+			continue
+		}
+		if !found || stat.CreatedAt.After(latest.CreatedAt) {
+			latest = stat
+			found = true
+		}
+	}
+	if !found {
+		return database.WorkspaceAgentStat{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
 func (q *FakeQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2048,6 +3282,13 @@ func (q *FakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.Lice
 	return database.License{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetLicenseGracePeriodStartedAt(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.licenseGraceStartedAt, nil
+}
+
 func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2057,6 +3298,22 @@ func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error)
 	return results, nil
 }
 
+func (q *FakeQuerier) GetLoginFailure(_ context.Context, arg database.GetLoginFailureParams) (database.LoginFailure, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.LoginFailure{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, failure := range q.loginFailures {
+		if failure.UserID == arg.UserID && failure.IPAddress.IPNet.String() == arg.IPAddress.IPNet.String() {
+			return failure, nil
+		}
+	}
+	return database.LoginFailure{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2068,6 +3325,45 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetNotificationMessagesByUserID(_ context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	msgs := []database.NotificationMessage{}
+	for _, msg := range q.notificationMessages {
+		if msg.UserID == arg.UserID {
+			msgs = append(msgs, msg)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].CreatedAt.After(msgs[j].CreatedAt)
+	})
+	if arg.LimitOpt > 0 && len(msgs) > int(arg.LimitOpt) {
+		msgs = msgs[:arg.LimitOpt]
+	}
+	return msgs, nil
+}
+
+func (q *FakeQuerier) GetNotificationPreferencesByUserID(_ context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	prefs := []database.NotificationPreference{}
+	for _, pref := range q.notificationPreferences {
+		if pref.UserID == userID {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.Slice(prefs, func(i, j int) bool {
+		return prefs[i].Template < prefs[j].Template
+	})
+	return prefs, nil
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2080,6 +3376,30 @@ func (q *FakeQuerier) GetOAuth2ProviderAppByID(_ context.Context, id uuid.UUID)
 	return database.OAuth2ProviderApp{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if code.ID == id {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOAuth2ProviderAppCodeByPrefix(_ context.Context, secretPrefix []byte) (database.OAuth2ProviderAppCode, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.SecretPrefix, secretPrefix) {
+			return code, nil
+		}
+	}
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderAppSecretByID(_ context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2120,6 +3440,18 @@ func (q *FakeQuerier) GetOAuth2ProviderAppSecretsByAppID(_ context.Context, appI
 	return []database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOAuth2ProviderAppTokenByPrefix(_ context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if bytes.Equal(token.HashPrefix, hashPrefix) {
+			return token, nil
+		}
+	}
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetOAuth2ProviderApps(_ context.Context) ([]database.OAuth2ProviderApp, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -2204,6 +3536,34 @@ func (q *FakeQuerier) GetOrganizationMemberByUserID(_ context.Context, arg datab
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetOrganizationMembers(_ context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetOrganizationMembersRow, 0)
+	for _, member := range q.organizationMembers {
+		if member.OrganizationID != organizationID {
+			continue
+		}
+		user, err := q.getUserByIDNoLock(member.UserID)
+		if err != nil || user.Deleted {
+			continue
+		}
+		rows = append(rows, database.GetOrganizationMembersRow{
+			UserID:         member.UserID,
+			OrganizationID: member.OrganizationID,
+			CreatedAt:      member.CreatedAt,
+			UpdatedAt:      member.UpdatedAt,
+			Roles:          member.Roles,
+			Username:       user.Username,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetOrganizationMembersRow) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetOrganizationMembershipsByUserID(_ context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2219,6 +3579,94 @@ func (q *FakeQuerier) GetOrganizationMembershipsByUserID(_ context.Context, user
 	return memberships, nil
 }
 
+func (q *FakeQuerier) GetOrganizationQuota(_ context.Context, organizationID uuid.UUID) (database.OrganizationQuota, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, quota := range q.organizationQuotas {
+		if quota.OrganizationID == organizationID {
+			return quota, nil
+		}
+	}
+	return database.OrganizationQuota{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetOrganizationResourceCostRollup(_ context.Context, arg database.GetOrganizationResourceCostRollupParams) ([]database.GetOrganizationResourceCostRollupRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type rollupKey struct {
+		ownerID    uuid.UUID
+		templateID uuid.UUID
+		currency   string
+	}
+	startDate, endDate := truncateToDate(arg.StartDate), truncateToDate(arg.EndDate)
+	rollups := make(map[rollupKey]*database.GetOrganizationResourceCostRollupRow)
+	workspaceIDs := make(map[rollupKey]map[uuid.UUID]struct{})
+	for _, cost := range q.workspaceResourceCosts {
+		if cost.OrganizationID != arg.OrganizationID {
+			continue
+		}
+		if cost.Date.Before(startDate) || cost.Date.After(endDate) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), cost.WorkspaceID)
+		if err != nil {
+			continue
+		}
+		key := rollupKey{ownerID: workspace.OwnerID, templateID: workspace.TemplateID, currency: cost.Currency}
+		row, ok := rollups[key]
+		if !ok {
+			row = &database.GetOrganizationResourceCostRollupRow{
+				OwnerID:    workspace.OwnerID,
+				TemplateID: workspace.TemplateID,
+				Currency:   cost.Currency,
+			}
+			rollups[key] = row
+			workspaceIDs[key] = make(map[uuid.UUID]struct{})
+		}
+		row.TotalCost += int64(cost.DailyCost)
+		workspaceIDs[key][cost.WorkspaceID] = struct{}{}
+	}
+
+	rows := make([]database.GetOrganizationResourceCostRollupRow, 0, len(rollups))
+	for key, row := range rollups {
+		row.WorkspaceCount = int64(len(workspaceIDs[key]))
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetOrganizationResourceCostRollupRow) int {
+		if c := slice.Ascending(a.OwnerID.String(), b.OwnerID.String()); c != 0 {
+			return c
+		}
+		if c := slice.Ascending(a.TemplateID.String(), b.TemplateID.String()); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.Currency, b.Currency)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetOrganizationTemplateVariableValues(_ context.Context, organizationID uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	values := make([]database.OrganizationTemplateVariableValue, 0)
+	for _, value := range q.organizationTemplateVariables {
+		if value.OrganizationID == organizationID {
+			values = append(values, value)
+		}
+	}
+	slices.SortFunc(values, func(a, b database.OrganizationTemplateVariableValue) int {
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return values, nil
+}
+
 func (q *FakeQuerier) GetOrganizations(_ context.Context) ([]database.Organization, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2251,6 +3699,34 @@ func (q *FakeQuerier) GetOrganizationsByUserID(_ context.Context, userID uuid.UU
 	return organizations, nil
 }
 
+func (q *FakeQuerier) GetOrphanedProvisionerJobs(_ context.Context, staleSince time.Time) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	staleDaemons := map[uuid.UUID]struct{}{}
+	for _, daemon := range q.provisionerDaemons {
+		if daemon.LastSeenAt.Valid && daemon.LastSeenAt.Time.Before(staleSince) {
+			staleDaemons[daemon.ID] = struct{}{}
+		}
+	}
+
+	orphanedJobs := []database.ProvisionerJob{}
+	for _, provisionerJob := range q.provisionerJobs {
+		if !provisionerJob.StartedAt.Valid || provisionerJob.CompletedAt.Valid || !provisionerJob.WorkerID.Valid {
+			continue
+		}
+		if _, ok := staleDaemons[provisionerJob.WorkerID.UUID]; !ok {
+			continue
+		}
+		// clone the Tags before appending, since maps are reference types and
+		// we don't want the caller to be able to mutate the map we have inside
+		// dbmem!
+		provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
+		orphanedJobs = append(orphanedJobs, provisionerJob)
+	}
+	return orphanedJobs, nil
+}
+
 func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2271,6 +3747,37 @@ func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.U
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetPasskeyByID(_ context.Context, id uuid.UUID) (database.Passkey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, passkey := range q.passkeys {
+		if passkey.ID == id {
+			return passkey, nil
+		}
+	}
+	return database.Passkey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetPasskeysByUserID(_ context.Context, userID uuid.UUID) ([]database.Passkey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	passkeys := make([]database.Passkey, 0)
+	for _, passkey := range q.passkeys {
+		if passkey.UserID == userID {
+			passkeys = append(passkeys, passkey)
+		}
+	}
+	slices.SortFunc(passkeys, func(a, b database.Passkey) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return passkeys, nil
+}
+
 func (q *FakeQuerier) GetPreviousTemplateVersion(_ context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersion{}, err
@@ -2340,6 +3847,21 @@ func (q *FakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.Provi
 	return out, nil
 }
 
+func (q *FakeQuerier) GetProvisionerDaemonsByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	out := make([]database.ProvisionerDaemon, 0)
+	for _, d := range q.provisionerDaemons {
+		if d.OrganizationID.Valid && d.OrganizationID.UUID != organizationID {
+			continue
+		}
+		d.Tags = maps.Clone(d.Tags)
+		out = append(out, d)
+	}
+	return out, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2347,6 +3869,71 @@ func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (
 	return q.getProvisionerJobByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetProvisionerJobIDsToArchiveLogs(_ context.Context, arg database.GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	hasLogs := make(map[uuid.UUID]bool)
+	for _, jobLog := range q.provisionerJobLogs {
+		hasLogs[jobLog.JobID] = true
+	}
+	for _, archive := range q.provisionerJobLogArchives {
+		delete(hasLogs, archive.JobID)
+	}
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.CompletedAt.Valid || !job.CompletedAt.Time.Before(arg.CompletedBefore) {
+			continue
+		}
+		if !hasLogs[job.ID] {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b database.ProvisionerJob) int {
+		return a.CompletedAt.Time.Compare(b.CompletedAt.Time)
+	})
+	if len(jobs) > int(arg.LimitOpt) {
+		jobs = jobs[:arg.LimitOpt]
+	}
+
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids, nil
+}
+
+func (q *FakeQuerier) GetProvisionerJobLogArchiveByJobID(_ context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, archive := range q.provisionerJobLogArchives {
+		if archive.JobID == jobID {
+			return archive, nil
+		}
+	}
+	return database.ProvisionerJobLogArchive{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerJobLogArchiveStats(_ context.Context) (database.GetProvisionerJobLogArchiveStatsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var stats database.GetProvisionerJobLogArchiveStatsRow
+	for _, archive := range q.provisionerJobLogArchives {
+		stats.ArchiveCount++
+		stats.CompressedSize += int64(len(archive.CompressedLogs))
+		stats.UncompressedSize += archive.UncompressedSize
+	}
+	return stats, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobsByIDs(_ context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2375,34 +3962,28 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	jobs := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0)
-	queuePosition := int64(1)
-	for _, job := range q.provisionerJobs {
-		for _, id := range ids {
-			if id == job.ID {
-				// clone the Tags before appending, since maps are reference types and
-				// we don't want the caller to be able to mutate the map we have inside
-				// dbmem!
-				job.Tags = maps.Clone(job.Tags)
-				job := database.GetProvisionerJobsByIDsWithQueuePositionRow{
-					ProvisionerJob: job,
-				}
-				if !job.ProvisionerJob.StartedAt.Valid {
-					job.QueuePosition = queuePosition
-				}
-				jobs = append(jobs, job)
-				break
-			}
-		}
+	queuePositions := make(map[uuid.UUID]int64)
+	for _, index := range q.provisionerJobQueueOrderNoLock() {
+		job := q.provisionerJobs[index]
 		if !job.StartedAt.Valid {
-			queuePosition++
+			queuePositions[job.ID] = int64(len(queuePositions)) + 1
 		}
 	}
-	for _, job := range jobs {
-		if !job.ProvisionerJob.StartedAt.Valid {
-			// Set it to the max position!
-			job.QueueSize = queuePosition
+
+	jobs := make([]database.GetProvisionerJobsByIDsWithQueuePositionRow, 0)
+	for _, job := range q.provisionerJobs {
+		if !slices.Contains(ids, job.ID) {
+			continue
 		}
+		// clone the Tags before appending, since maps are reference types and
+		// we don't want the caller to be able to mutate the map we have inside
+		// dbmem!
+		job.Tags = maps.Clone(job.Tags)
+		jobs = append(jobs, database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: job,
+			QueuePosition:  queuePositions[job.ID],
+			QueueSize:      int64(len(queuePositions)),
+		})
 	}
 	return jobs, nil
 }
@@ -2424,6 +4005,46 @@ func (q *FakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after ti
 	return jobs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerKeyByHashedSecret(_ context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if bytes.Equal(key.HashedSecret, hashedSecret) {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerKeyByID(_ context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetProvisionerKeyByName(_ context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return key, nil
+		}
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -2471,6 +4092,33 @@ func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UU
 	return sum, nil
 }
 
+func (q *FakeQuerier) GetQuotaConsumedForOrganization(_ context.Context, organizationID uuid.UUID) (int64, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var sum int64
+	for _, workspace := range q.workspaces {
+		if workspace.OrganizationID != organizationID {
+			continue
+		}
+		if workspace.Deleted {
+			continue
+		}
+
+		var lastBuild database.WorkspaceBuildTable
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if build.CreatedAt.After(lastBuild.CreatedAt) {
+				lastBuild = build
+			}
+		}
+		sum += int64(lastBuild.DailyCost)
+	}
+	return sum, nil
+}
+
 func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUID) (int64, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2523,6 +4171,47 @@ func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 	return replicas, nil
 }
 
+func (q *FakeQuerier) GetRunningWorkspaceCounts(ctx context.Context, arg database.GetRunningWorkspaceCountsParams) (database.GetRunningWorkspaceCountsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.GetRunningWorkspaceCountsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var row database.GetRunningWorkspaceCountsRow
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID == arg.WorkspaceID {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return database.GetRunningWorkspaceCountsRow{}, err
+		}
+		if build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			return database.GetRunningWorkspaceCountsRow{}, err
+		}
+		if job.CanceledAt.Valid || job.Error.Valid {
+			continue
+		}
+
+		if workspace.OwnerID == arg.OwnerID && workspace.OrganizationID == arg.OrganizationID {
+			row.OwnerCount++
+		}
+		if workspace.TemplateID == arg.TemplateID {
+			row.TemplateCount++
+		}
+	}
+	return row, nil
+}
+
 func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2534,6 +4223,37 @@ func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
 	return string(q.serviceBanner), nil
 }
 
+func (q *FakeQuerier) GetSessionRecordingByID(_ context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, recording := range q.sessionRecordings {
+		if recording.ID == id {
+			return recording, nil
+		}
+	}
+	return database.SessionRecording{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetSessionRecordingsByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	recordings := make([]database.SessionRecording, 0)
+	for _, recording := range q.sessionRecordings {
+		if recording.WorkspaceID == workspaceID {
+			recordings = append(recordings, recording)
+		}
+	}
+	slices.SortFunc(recordings, func(a, b database.SessionRecording) int {
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return b.StartedAt.Compare(a.StartedAt)
+		}
+		return -bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return recordings, nil
+}
+
 func (*FakeQuerier) GetTailnetAgents(context.Context, uuid.UUID) ([]database.TailnetAgent, error) {
 	return nil, ErrUnimplemented
 }
@@ -2578,6 +4298,8 @@ func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.G
 	}
 
 	appUsageIntervalsByUserAgentApp := make(map[uniqueKey]map[time.Time]int64)
+	appRequests := make(map[appKey]int64)
+	appLastUsedAt := make(map[appKey]time.Time)
 	for _, s := range q.workspaceAppStats {
 		// (was.session_started_at >= ts.from_ AND was.session_started_at < ts.to_)
 		// OR (was.session_ended_at > ts.from_ AND was.session_ended_at < ts.to_)
@@ -2622,10 +4344,17 @@ func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.G
 		if t.Before(arg.StartTime) {
 			t = arg.StartTime
 		}
+		if !t.Before(s.SessionEndedAt) || !t.Before(arg.EndTime) {
+			continue
+		}
 		for t.Before(s.SessionEndedAt) && t.Before(arg.EndTime) {
 			appUsageIntervalsByUserAgentApp[key][t] = 60 // 1 minute.
 			t = t.Add(1 * time.Minute)
 		}
+		appRequests[key.AppKey] += int64(s.Requests)
+		if s.SessionEndedAt.After(appLastUsedAt[key.AppKey]) {
+			appLastUsedAt[key.AppKey] = s.SessionEndedAt
+		}
 	}
 
 	appUsageTemplateIDs := make(map[appKey]map[uuid.UUID]struct{})
@@ -2671,6 +4400,8 @@ func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.G
 			Icon:          sql.NullString{String: appKey.Icon, Valid: appKey.Icon != ""},
 			IsApp:         appKey.Slug != "",
 			UsageSeconds:  usage,
+			Requests:      appRequests[appKey],
+			LastUsedAt:    appLastUsedAt[appKey],
 		})
 	}
 
@@ -2833,6 +4564,55 @@ func (q *FakeQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	return row, nil
 }
 
+func (q *FakeQuerier) GetTemplateBuildInsights(ctx context.Context, arg database.GetTemplateBuildInsightsParams) (database.GetTemplateBuildInsightsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.GetTemplateBuildInsightsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var (
+		row             database.GetTemplateBuildInsightsRow
+		successfulTimes []float64
+	)
+	for _, wb := range q.workspaceBuilds {
+		if wb.CreatedAt.Before(arg.StartTime) || !wb.CreatedAt.Before(arg.EndTime) {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, wb.TemplateVersionID)
+		if err != nil {
+			return database.GetTemplateBuildInsightsRow{}, err
+		}
+		if !version.TemplateID.Valid || version.TemplateID.UUID != arg.TemplateID {
+			continue
+		}
+
+		job, err := q.getProvisionerJobByIDNoLock(ctx, wb.JobID)
+		if err != nil {
+			return database.GetTemplateBuildInsightsRow{}, err
+		}
+		switch provisonerJobStatus(job) {
+		case database.ProvisionerJobStatusSucceeded:
+			row.TotalBuilds++
+			successfulTimes = append(successfulTimes, job.CompletedAt.Time.Sub(job.StartedAt.Time).Seconds())
+		case database.ProvisionerJobStatusFailed:
+			row.TotalBuilds++
+			row.FailedBuilds++
+		}
+	}
+
+	tryPercentile := func(fs []float64, p float64) float64 {
+		if len(fs) == 0 {
+			return -1
+		}
+		sort.Float64s(fs)
+		return fs[int(float64(len(fs))*p/100)]
+	}
+	row.BuildTimeP50, row.BuildTimeP95 = tryPercentile(successfulTimes, 50), tryPercentile(successfulTimes, 95)
+	return row, nil
+}
+
 func (q *FakeQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3260,6 +5040,22 @@ func (q *FakeQuerier) GetTemplateParameterInsights(ctx context.Context, arg data
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplateVariableValuesByTemplateID(_ context.Context, templateID uuid.UUID) ([]database.TemplateVariableValue, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	values := make([]database.TemplateVariableValue, 0)
+	for _, value := range q.templateVariableValues {
+		if value.TemplateID == templateID {
+			values = append(values, value)
+		}
+	}
+	slices.SortFunc(values, func(a, b database.TemplateVariableValue) int {
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return values, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionByID(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersion, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3320,6 +5116,22 @@ func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionPresets(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	presets := make([]database.TemplateVersionPreset, 0)
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == templateVersionID {
+			presets = append(presets, preset)
+		}
+	}
+	slices.SortFunc(presets, func(a, b database.TemplateVersionPreset) int {
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return presets, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionVariables(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3459,6 +5271,71 @@ func (q *FakeQuerier) GetTemplatesWithFilter(ctx context.Context, arg database.G
 	return q.GetAuthorizedTemplates(ctx, arg, nil)
 }
 
+func (q *FakeQuerier) GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timedOutJobs := []database.ProvisionerJob{}
+	for _, provisionerJob := range q.provisionerJobs {
+		if !provisionerJob.StartedAt.Valid || provisionerJob.CompletedAt.Valid {
+			continue
+		}
+		var build *database.WorkspaceBuildTable
+		for i := range q.workspaceBuilds {
+			if q.workspaceBuilds[i].JobID == provisionerJob.ID {
+				build = &q.workspaceBuilds[i]
+				break
+			}
+		}
+		if build == nil {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+		if err != nil {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, workspace.TemplateID)
+		if err != nil {
+			continue
+		}
+		if template.MaxBuildDuration <= 0 {
+			continue
+		}
+		if !provisionerJob.StartedAt.Time.Before(now.Add(-time.Duration(template.MaxBuildDuration))) {
+			continue
+		}
+		// clone the Tags before appending, since maps are reference types and
+		// we don't want the caller to be able to mutate the map we have inside
+		// dbmem!
+		provisionerJob.Tags = maps.Clone(provisionerJob.Tags)
+		timedOutJobs = append(timedOutJobs, provisionerJob)
+	}
+	return timedOutJobs, nil
+}
+
+func (q *FakeQuerier) GetTwoFactorPolicies(_ context.Context) ([]database.TwoFactorPolicy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	policies := slices.Clone(q.twoFactorPolicies)
+	slices.SortFunc(policies, func(a, b database.TwoFactorPolicy) int {
+		return slice.Ascending(a.Role, b.Role)
+	})
+	return policies, nil
+}
+
+func (q *FakeQuerier) GetTwoFactorPolicyByRole(_ context.Context, role string) (database.TwoFactorPolicy, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, policy := range q.twoFactorPolicies {
+		if policy.Role == role {
+			return policy, nil
+		}
+	}
+	return database.TwoFactorPolicy{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.License, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3474,6 +5351,64 @@ func (q *FakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.Licens
 	return results, nil
 }
 
+func (q *FakeQuerier) GetUnknownLoginFailure(_ context.Context, arg database.GetUnknownLoginFailureParams) (database.UnknownLoginFailure, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UnknownLoginFailure{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, failure := range q.unknownLoginFailures {
+		if bytes.Equal(failure.LoginHash, arg.LoginHash) && failure.IPAddress.IPNet.String() == arg.IPAddress.IPNet.String() {
+			return failure, nil
+		}
+	}
+	return database.UnknownLoginFailure{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUnknownLoginFailureTotals(_ context.Context, arg database.GetUnknownLoginFailureTotalsParams) (database.GetUnknownLoginFailureTotalsRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.GetUnknownLoginFailureTotalsRow{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	row := database.GetUnknownLoginFailureTotalsRow{
+		LastFailedAt: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, failure := range q.unknownLoginFailures {
+		if !bytes.Equal(failure.LoginHash, arg.LoginHash) || failure.LastFailedAt.Before(arg.Since) {
+			continue
+		}
+		row.FailedAttempts += int64(failure.FailedAttempts)
+		if failure.LastFailedAt.After(row.LastFailedAt) {
+			row.LastFailedAt = failure.LastFailedAt
+		}
+	}
+	return row, nil
+}
+
+func (q *FakeQuerier) GetUnusedUserRecoveryCodes(_ context.Context, userID uuid.UUID) ([]database.UserRecoveryCode, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	codes := make([]database.UserRecoveryCode, 0)
+	for _, code := range q.userRecoveryCodes {
+		if code.UserID == userID && !code.UsedAt.Valid {
+			codes = append(codes, code)
+		}
+	}
+	slices.SortFunc(codes, func(a, b database.UserRecoveryCode) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return codes, nil
+}
+
 func (q *FakeQuerier) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -3758,6 +5693,57 @@ func (q *FakeQuerier) GetUserLinksByUserID(_ context.Context, userID uuid.UUID)
 	return uls, nil
 }
 
+func (q *FakeQuerier) GetUserLockoutByUserID(_ context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, lockout := range q.userLockouts {
+		if lockout.UserID == userID {
+			return lockout, nil
+		}
+	}
+	return database.UserLockout{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserLockouts(_ context.Context, now time.Time) ([]database.GetUserLockoutsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetUserLockoutsRow, 0)
+	for _, lockout := range q.userLockouts {
+		if !lockout.LockedUntil.After(now) {
+			continue
+		}
+		user, err := q.getUserByIDNoLock(lockout.UserID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetUserLockoutsRow{
+			UserID:         lockout.UserID,
+			FailedAttempts: lockout.FailedAttempts,
+			LockedAt:       lockout.LockedAt,
+			LockedUntil:    lockout.LockedUntil,
+			Username:       user.Username,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetUserLockoutsRow) int {
+		return b.LockedAt.Compare(a.LockedAt)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetUserSCIMExternalIDByExternalID(_ context.Context, externalID string) (database.UserSCIMExternalID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, id := range q.userSCIMExternalIDs {
+		if id.ExternalID == externalID {
+			return id, nil
+		}
+	}
+	return database.UserSCIMExternalID{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetUsers(_ context.Context, params database.GetUsersParams) ([]database.GetUsersRow, error) {
 	if err := validateDatabaseType(params); err != nil {
 		return nil, err
@@ -3890,6 +5876,80 @@ func (q *FakeQuerier) GetUsersByIDs(_ context.Context, ids []uuid.UUID) ([]datab
 	return users, nil
 }
 
+func (q *FakeQuerier) GetWebhookByID(_ context.Context, id uuid.UUID) (database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.webhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.Webhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhookDeliveriesByWebhookID(_ context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deliveries := []database.WebhookDelivery{}
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.WebhookID == arg.WebhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if arg.LimitOpt > 0 && len(deliveries) > int(arg.LimitOpt) {
+		deliveries = deliveries[:arg.LimitOpt]
+	}
+	return deliveries, nil
+}
+
+func (q *FakeQuerier) GetWebhookDeliveryByID(_ context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, delivery := range q.webhookDeliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return database.WebhookDelivery{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWebhooks(_ context.Context) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := append([]database.Webhook{}, q.webhooks...)
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks, nil
+}
+
+func (q *FakeQuerier) GetWebhooksByEvent(_ context.Context, event string) ([]database.Webhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := []database.Webhook{}
+	for _, webhook := range q.webhooks {
+		if slices.Contains(webhook.Events, event) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentAndOwnerByAuthToken(_ context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3901,7 +5961,7 @@ func (q *FakeQuerier) GetWorkspaceAgentAndOwnerByAuthToken(_ context.Context, au
 	var latestBuildNumber int32
 
 	for _, agt := range q.workspaceAgents {
-		if agt.AuthToken != authToken {
+		if agt.AuthToken != authToken && !q.isPreviousWorkspaceAgentAuthTokenNoLock(agt.ID, authToken) {
 			continue
 		}
 		// get the related workspace and user
@@ -3984,6 +6044,47 @@ func (q *FakeQuerier) GetWorkspaceAgentByInstanceID(_ context.Context, instanceI
 	return database.WorkspaceAgent{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	agents, err := q.getLatestBuildWorkspaceAgentsNoLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[database.WorkspaceAgentLifecycleState]int64)
+	for _, agent := range agents {
+		counts[agent.LifecycleState]++
+	}
+	rows := make([]database.GetWorkspaceAgentCountsByLifecycleStateRow, 0, len(counts))
+	for state, count := range counts {
+		rows = append(rows, database.GetWorkspaceAgentCountsByLifecycleStateRow{
+			LifecycleState: state,
+			Count:          count,
+		})
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentGPUsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentGPU, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	gpus := make([]database.WorkspaceAgentGPU, 0)
+	for _, gpu := range q.workspaceAgentGPUs {
+		if slices.Contains(ids, gpu.AgentID) {
+			gpus = append(gpus, gpu)
+		}
+	}
+	slices.SortFunc(gpus, func(a, b database.WorkspaceAgentGPU) int {
+		if a.Vendor != b.Vendor {
+			return slice.Ascending(a.Vendor, b.Vendor)
+		}
+		return slice.Ascending(a.Index, b.Index)
+	})
+	return gpus, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4056,6 +6157,133 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, arg database.
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentMetadataHistory(_ context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	history := make([]database.WorkspaceAgentMetadataHistory, 0)
+	for _, h := range q.workspaceAgentMetadataHistory {
+		if h.WorkspaceAgentID != arg.WorkspaceAgentID || !h.CollectedAt.After(arg.Since) {
+			continue
+		}
+		if len(arg.Keys) > 0 && !slices.Contains(arg.Keys, h.Key) {
+			continue
+		}
+		history = append(history, h)
+	}
+	slices.SortFunc(history, func(a, b database.WorkspaceAgentMetadataHistory) int {
+		if a.Key != b.Key {
+			return strings.Compare(a.Key, b.Key)
+		}
+		return a.CollectedAt.Compare(b.CollectedAt)
+	})
+	return history, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPortShare(_ context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == arg.WorkspaceID && share.AgentName == arg.AgentName && share.Port == arg.Port {
+			return share, nil
+		}
+	}
+	return database.WorkspaceAgentPortShare{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPortSharesByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	shares := make([]database.WorkspaceAgentPortShare, 0)
+	for _, share := range q.workspaceAgentPortShares {
+		if share.WorkspaceID == workspaceID {
+			shares = append(shares, share)
+		}
+	}
+	slices.SortFunc(shares, func(a, b database.WorkspaceAgentPortShare) int {
+		if a.AgentName != b.AgentName {
+			return slice.Ascending(a.AgentName, b.AgentName)
+		}
+		return slice.Ascending(a.Port, b.Port)
+	})
+	return shares, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentPortsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentPort, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	ports := make([]database.WorkspaceAgentPort, 0)
+	for _, port := range q.workspaceAgentPorts {
+		if slices.Contains(ids, port.AgentID) {
+			ports = append(ports, port)
+		}
+	}
+	slices.SortFunc(ports, func(a, b database.WorkspaceAgentPort) int {
+		return slice.Ascending(a.Port, b.Port)
+	})
+	return ports, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	latestAgentStats := map[uuid.UUID]database.WorkspaceAgentStat{}
+	for _, agentStat := range q.workspaceAgentStats {
+		if !agentStat.CreatedAt.After(createdAfter) {
+			continue
+		}
+		// Agents that don't report resource usage send zero totals.
+		if agentStat.CPUTotalCores <= 0 && agentStat.MemoryTotalBytes <= 0 && agentStat.DiskTotalBytes <= 0 {
+			continue
+		}
+		if latest, ok := latestAgentStats[agentStat.AgentID]; ok && latest.CreatedAt.After(agentStat.CreatedAt) {
+			continue
+		}
+		latestAgentStats[agentStat.AgentID] = agentStat
+	}
+
+	rows := make([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, 0, len(latestAgentStats))
+	for _, agentStat := range latestAgentStats {
+		user, err := q.getUserByIDNoLock(agentStat.UserID)
+		if err != nil {
+			return nil, err
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, agentStat.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		agent, err := q.getWorkspaceAgentByIDNoLock(ctx, agentStat.AgentID)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetWorkspaceAgentResourceUsageAndLabelsRow{
+			Username:         user.Username,
+			AgentName:        agent.Name,
+			WorkspaceName:    workspace.Name,
+			CPUUsedCores:     agentStat.CPUUsedCores,
+			CPUTotalCores:    agentStat.CPUTotalCores,
+			MemoryUsedBytes:  agentStat.MemoryUsedBytes,
+			MemoryTotalBytes: agentStat.MemoryTotalBytes,
+			DiskUsedBytes:    agentStat.DiskUsedBytes,
+			DiskTotalBytes:   agentStat.DiskTotalBytes,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentScriptsByAgentIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4283,6 +6511,35 @@ func (q *FakeQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg d
 	return q.getWorkspaceAppByAgentIDAndSlugNoLock(ctx, arg)
 }
 
+func (q *FakeQuerier) GetWorkspaceAppCountsByHealth(ctx context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	agents, err := q.getLatestBuildWorkspaceAgentsNoLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agentIDs := make(map[uuid.UUID]struct{}, len(agents))
+	for _, agent := range agents {
+		agentIDs[agent.ID] = struct{}{}
+	}
+	counts := make(map[database.WorkspaceAppHealth]int64)
+	for _, app := range q.workspaceApps {
+		if _, ok := agentIDs[app.AgentID]; !ok {
+			continue
+		}
+		counts[app.Health]++
+	}
+	rows := make([]database.GetWorkspaceAppCountsByHealthRow, 0, len(counts))
+	for health, count := range counts {
+		rows = append(rows, database.GetWorkspaceAppCountsByHealthRow{
+			Health: health,
+			Count:  count,
+		})
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAppsByAgentID(_ context.Context, id uuid.UUID) ([]database.WorkspaceApp, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4420,6 +6677,16 @@ func (q *FakeQuerier) GetWorkspaceBuildsByWorkspaceID(_ context.Context,
 		}
 	}
 
+	if params.Reason != "" {
+		filtered := make([]database.WorkspaceBuild, 0, len(history))
+		for _, v := range history {
+			if v.Reason == database.BuildReason(params.Reason) {
+				filtered = append(filtered, v)
+			}
+		}
+		history = filtered
+	}
+
 	if params.OffsetOpt > 0 {
 		if int(params.OffsetOpt) > len(history)-1 {
 			return nil, sql.ErrNoRows
@@ -4529,6 +6796,139 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	organizationNames := make(map[uuid.UUID]string, len(q.organizations))
+	for _, organization := range q.organizations {
+		organizationNames[organization.ID] = organization.Name
+	}
+
+	counts := make(map[database.GetWorkspaceCountsByTemplateAndStatusRow]int64)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		organizationName, ok := organizationNames[workspace.OrganizationID]
+		if !ok {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, workspace.TemplateID)
+		if err != nil {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			continue
+		}
+		counts[database.GetWorkspaceCountsByTemplateAndStatusRow{
+			TemplateName:     template.Name,
+			OrganizationName: organizationName,
+			Transition:       build.Transition,
+			JobStatus:        provisonerJobStatus(job),
+		}]++
+	}
+	rows := make([]database.GetWorkspaceCountsByTemplateAndStatusRow, 0, len(counts))
+	for row, count := range counts {
+		row.Count = count
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceDriftByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceDrift, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, drift := range q.workspaceDrift {
+		if drift.WorkspaceID == workspaceID {
+			return drift, nil
+		}
+	}
+	return database.WorkspaceDrift{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	ids := make([]uuid.UUID, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.DormantAt.Valid {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil || build.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil || provisonerJobStatus(job) != database.ProvisionerJobStatusSucceeded {
+			continue
+		}
+		due := true
+		for _, drift := range q.workspaceDrift {
+			if drift.WorkspaceID != workspace.ID {
+				continue
+			}
+			driftJob, err := q.getProvisionerJobByIDNoLock(ctx, drift.JobID)
+			due = err == nil && driftJob.CompletedAt.Valid && driftJob.CreatedAt.Before(lastCheckBefore)
+		}
+		if due {
+			ids = append(ids, workspace.ID)
+		}
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+	return ids, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceParameterChanges(_ context.Context, arg database.GetWorkspaceParameterChangesParams) ([]database.GetWorkspaceParameterChangesRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	changes := make([]database.GetWorkspaceParameterChangesRow, 0)
+	for _, change := range q.workspaceParameterChanges {
+		if change.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if arg.Name != "" && change.Name != arg.Name {
+			continue
+		}
+		row := database.GetWorkspaceParameterChangesRow{
+			ID:               change.ID,
+			WorkspaceID:      change.WorkspaceID,
+			WorkspaceBuildID: change.WorkspaceBuildID,
+			BuildNumber:      change.BuildNumber,
+			Name:             change.Name,
+			OldValue:         change.OldValue,
+			NewValue:         change.NewValue,
+			InitiatorID:      change.InitiatorID,
+			CreatedAt:        change.CreatedAt,
+		}
+		if user, err := q.getUserByIDNoLock(change.InitiatorID); err == nil {
+			row.InitiatorUsername = user.Username
+		}
+		changes = append(changes, row)
+	}
+	slices.SortFunc(changes, func(a, b database.GetWorkspaceParameterChangesRow) int {
+		if a.BuildNumber != b.BuildNumber {
+			return slice.Descending(a.BuildNumber, b.BuildNumber)
+		}
+		return slice.Ascending(a.Name, b.Name)
+	})
+	return changes, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4606,6 +7006,13 @@ func (q *FakeQuerier) GetWorkspaceProxyByName(_ context.Context, name string) (d
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceProxyHealth(_ context.Context) ([]database.WorkspaceProxyHealth, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return slices.Clone(q.workspaceProxyHealth), nil
+}
+
 func (q *FakeQuerier) GetWorkspaceResourceByID(_ context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4618,6 +7025,38 @@ func (q *FakeQuerier) GetWorkspaceResourceByID(_ context.Context, id uuid.UUID)
 	return database.WorkspaceResource{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceResourceCosts(_ context.Context, arg database.GetWorkspaceResourceCostsParams) ([]database.WorkspaceResourceCost, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	startDate, endDate := truncateToDate(arg.StartDate), truncateToDate(arg.EndDate)
+	costs := make([]database.WorkspaceResourceCost, 0)
+	for _, cost := range q.workspaceResourceCosts {
+		if cost.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		if cost.Date.Before(startDate) || cost.Date.After(endDate) {
+			continue
+		}
+		costs = append(costs, cost)
+	}
+	slices.SortFunc(costs, func(a, b database.WorkspaceResourceCost) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		if c := slice.Ascending(a.ResourceType, b.ResourceType); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.ResourceName, b.ResourceName)
+	})
+	return costs, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceResourceMetadataByResourceIDs(_ context.Context, ids []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4693,6 +7132,146 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceSnapshotByID(_ context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+	return database.WorkspaceSnapshot{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := []database.GetWorkspaceSnapshotsByWorkspaceIDRow{}
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID != workspaceID {
+			continue
+		}
+		build, err := q.getWorkspaceBuildByIDNoLock(ctx, snapshot.BuildID)
+		if err != nil {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceSnapshotsByWorkspaceIDRow{
+			WorkspaceSnapshot: snapshot,
+			JobID:             job.ID,
+			JobStatus:         provisonerJobStatus(job),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].WorkspaceSnapshot.CreatedAt.After(rows[j].WorkspaceSnapshot.CreatedAt)
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceTimeline(ctx context.Context, arg database.GetWorkspaceTimelineParams) ([]database.GetWorkspaceTimelineRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	timeline := make([]database.GetWorkspaceTimelineRow, 0)
+	for _, e := range q.workspaceEvents {
+		if e.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		row := database.GetWorkspaceTimelineRow{
+			ID:        e.ID,
+			CreatedAt: e.CreatedAt,
+			Type:      string(e.Type),
+			UserID:    e.UserID,
+			AgentID:   e.AgentID,
+			AppID:     e.AppID,
+			OldValue:  e.OldValue,
+			NewValue:  e.NewValue,
+		}
+		if e.AgentID.Valid {
+			if agent, err := q.getWorkspaceAgentByIDNoLock(ctx, e.AgentID.UUID); err == nil {
+				row.AgentName = agent.Name
+			}
+		}
+		if e.AppID.Valid {
+			for _, app := range q.workspaceApps {
+				if app.ID == e.AppID.UUID {
+					row.AppSlug = app.Slug
+					break
+				}
+			}
+		}
+		timeline = append(timeline, row)
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil {
+			continue
+		}
+		row := database.GetWorkspaceTimelineRow{
+			ID:          build.ID,
+			CreatedAt:   build.CreatedAt,
+			Type:        "build_started",
+			UserID:      uuid.NullUUID{UUID: build.InitiatorID, Valid: true},
+			BuildID:     uuid.NullUUID{UUID: build.ID, Valid: true},
+			BuildNumber: build.BuildNumber,
+			BuildReason: string(build.Reason),
+			Transition:  string(build.Transition),
+			JobStatus:   string(provisonerJobStatus(job)),
+		}
+		timeline = append(timeline, row)
+		if job.CompletedAt.Valid {
+			row.ID = job.ID
+			row.CreatedAt = job.CompletedAt.Time
+			row.Type = "build_completed"
+			timeline = append(timeline, row)
+		}
+	}
+
+	slices.SortFunc(timeline, func(a, b database.GetWorkspaceTimelineRow) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(b.ID[:], a.ID[:])
+	})
+
+	if arg.AfterID != uuid.Nil {
+		found := false
+		for i, row := range timeline {
+			if row.ID == arg.AfterID {
+				timeline = timeline[i+1:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []database.GetWorkspaceTimelineRow{}, nil
+		}
+	}
+
+	if arg.OffsetOpt > 0 {
+		if int(arg.OffsetOpt) > len(timeline) {
+			return []database.GetWorkspaceTimelineRow{}, nil
+		}
+		timeline = timeline[arg.OffsetOpt:]
+	}
+	if arg.LimitOpt > 0 && int(arg.LimitOpt) < len(timeline) {
+		timeline = timeline[:arg.LimitOpt]
+	}
+	return timeline, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(_ context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4733,6 +7312,19 @@ func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	return workspaceRows, err
 }
 
+func (q *FakeQuerier) GetWorkspacesByIDs(_ context.Context, ids []uuid.UUID) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := make([]database.Workspace, 0)
+	for _, workspace := range q.workspaces {
+		if slices.Contains(ids, workspace.ID) {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	return workspaces, nil
+}
+
 func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -4772,11 +7364,27 @@ func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, no
 		if err != nil {
 			return nil, xerrors.Errorf("get template by ID: %w", err)
 		}
-		if !workspace.DormantAt.Valid && template.TimeTilDormant > 0 {
+		if !workspace.DormantAt.Valid && template.TimeTilDormant > 0 && !workspace.DormancyExempt {
 			workspaces = append(workspaces, workspace)
 			continue
 		}
-		if workspace.DormantAt.Valid && template.TimeTilDormantAutoDelete > 0 {
+		if workspace.DormantAt.Valid && template.TimeTilDormantAutoDelete > 0 && !workspace.DormancyExempt {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+		if template.MaintenanceWindowSchedule != "" &&
+			build.Transition == database.WorkspaceTransitionStart &&
+			build.TemplateVersionID != template.ActiveVersionID &&
+			!workspace.MaintenanceOptOut {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+		if build.Transition == database.WorkspaceTransitionStop &&
+			build.Reason == database.BuildReasonMaintenance {
+			workspaces = append(workspaces, workspace)
+			continue
+		}
+		if workspace.Ephemeral {
 			workspaces = append(workspaces, workspace)
 			continue
 		}
@@ -4785,6 +7393,29 @@ func (q *FakeQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, no
 	return workspaces, nil
 }
 
+func (q *FakeQuerier) IncrementRateLimitCounter(_ context.Context, arg database.IncrementRateLimitCounterParams) (int32, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, counter := range q.rateLimitCounters {
+		if counter.Key == arg.Key && counter.WindowStart.Equal(arg.WindowStart) {
+			q.rateLimitCounters[i].Count++
+			return q.rateLimitCounters[i].Count, nil
+		}
+	}
+	q.rateLimitCounters = append(q.rateLimitCounters, database.RateLimitCounter{
+		Key:         arg.Key,
+		WindowStart: arg.WindowStart,
+		Count:       1,
+	})
+	return 1, nil
+}
+
 func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.APIKey{}, err
@@ -4796,6 +7427,15 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 	if arg.LifetimeSeconds == 0 {
 		arg.LifetimeSeconds = 86400
 	}
+	if arg.Scopes == nil {
+		arg.Scopes = []string{}
+	}
+	if arg.AllowedWorkspaceIDs == nil {
+		arg.AllowedWorkspaceIDs = []uuid.UUID{}
+	}
+	if arg.IPAllowlist == nil {
+		arg.IPAllowlist = []string{}
+	}
 
 	for _, u := range q.users {
 		if u.ID == arg.UserID && u.Deleted {
@@ -4805,18 +7445,21 @@ func (q *FakeQuerier) InsertAPIKey(_ context.Context, arg database.InsertAPIKeyP
 
 	//nolint:gosimple
 	key := database.APIKey{
-		ID:              arg.ID,
-		LifetimeSeconds: arg.LifetimeSeconds,
-		HashedSecret:    arg.HashedSecret,
-		IPAddress:       arg.IPAddress,
-		UserID:          arg.UserID,
-		ExpiresAt:       arg.ExpiresAt,
-		CreatedAt:       arg.CreatedAt,
-		UpdatedAt:       arg.UpdatedAt,
-		LastUsed:        arg.LastUsed,
-		LoginType:       arg.LoginType,
-		Scope:           arg.Scope,
-		TokenName:       arg.TokenName,
+		ID:                  arg.ID,
+		LifetimeSeconds:     arg.LifetimeSeconds,
+		HashedSecret:        arg.HashedSecret,
+		IPAddress:           arg.IPAddress,
+		UserID:              arg.UserID,
+		ExpiresAt:           arg.ExpiresAt,
+		CreatedAt:           arg.CreatedAt,
+		UpdatedAt:           arg.UpdatedAt,
+		LastUsed:            arg.LastUsed,
+		LoginType:           arg.LoginType,
+		Scope:               arg.Scope,
+		TokenName:           arg.TokenName,
+		Scopes:              arg.Scopes,
+		AllowedWorkspaceIDs: arg.AllowedWorkspaceIDs,
+		IPAllowlist:         arg.IPAllowlist,
 	}
 	q.apiKeys = append(q.apiKeys, key)
 	return key, nil
@@ -4844,18 +7487,45 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	alog := database.AuditLog(arg)
 
 	q.auditLogs = append(q.auditLogs, alog)
+	// Match the ORDER BY "time" DESC, id DESC of the audit log queries, so
+	// pages don't skip or repeat logs that share a timestamp.
 	slices.SortFunc(q.auditLogs, func(a, b database.AuditLog) int {
-		if a.Time.Before(b.Time) {
-			return -1
-		} else if a.Time.Equal(b.Time) {
-			return 0
+		if !a.Time.Equal(b.Time) {
+			if a.Time.After(b.Time) {
+				return -1
+			}
+			return 1
 		}
-		return 1
+		return -bytes.Compare(a.ID[:], b.ID[:])
 	})
 
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertConnectionLog(_ context.Context, arg database.InsertConnectionLogParams) (database.ConnectionLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ConnectionLog{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	clog := database.ConnectionLog(arg)
+
+	q.connectionLogs = append(q.connectionLogs, clog)
+	slices.SortFunc(q.connectionLogs, func(a, b database.ConnectionLog) int {
+		if !a.Time.Equal(b.Time) {
+			if a.Time.After(b.Time) {
+				return -1
+			}
+			return 1
+		}
+		return -bytes.Compare(a.ID[:], b.ID[:])
+	})
+
+	return clog, nil
+}
+
 func (q *FakeQuerier) InsertDBCryptKey(_ context.Context, arg database.InsertDBCryptKeyParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5009,7 +7679,56 @@ func (q *FakeQuerier) InsertGroupMember(_ context.Context, arg database.InsertGr
 		UserID:  arg.UserID,
 	})
 
-	return nil
+	return nil
+}
+
+func (q *FakeQuerier) InsertGroupSyncRun(_ context.Context, arg database.InsertGroupSyncRunParams) (database.GroupSyncRun, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.GroupSyncRun{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple
+	run := database.GroupSyncRun{
+		ID:          arg.ID,
+		Trigger:     arg.Trigger,
+		UserID:      arg.UserID,
+		StartedAt:   arg.StartedAt,
+		FinishedAt:  arg.FinishedAt,
+		UsersSynced: arg.UsersSynced,
+		UsersFailed: arg.UsersFailed,
+		IDPGroups:   arg.IDPGroups,
+		Groups:      arg.Groups,
+		Error:       arg.Error,
+	}
+	q.groupSyncRuns = append(q.groupSyncRuns, run)
+	return run, nil
+}
+
+func (q *FakeQuerier) InsertInboxNotification(_ context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.InboxNotification{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, err := q.getUserByIDNoLock(arg.UserID); err != nil {
+		return database.InboxNotification{}, errForeignKeyConstraint
+	}
+
+	notif := database.InboxNotification{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Template:  arg.Template,
+		Title:     arg.Title,
+		Content:   arg.Content,
+		CreatedAt: arg.CreatedAt,
+	}
+	q.inboxNotifications = append(q.inboxNotifications, notif)
+	return notif, nil
 }
 
 func (q *FakeQuerier) InsertLicense(
@@ -5072,6 +7791,31 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 	return newGroups, nil
 }
 
+func (q *FakeQuerier) InsertNotificationMessage(_ context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationMessage{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, err := q.getUserByIDNoLock(arg.UserID); err != nil {
+		return database.NotificationMessage{}, errForeignKeyConstraint
+	}
+
+	msg := database.NotificationMessage{
+		ID:            arg.ID,
+		UserID:        arg.UserID,
+		Template:      arg.Template,
+		Labels:        arg.Labels,
+		CreatedAt:     arg.CreatedAt,
+		NextAttemptAt: arg.NextAttemptAt,
+		Method:        arg.Method,
+	}
+	q.notificationMessages = append(q.notificationMessages, msg)
+	return msg, nil
+}
+
 func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5101,6 +7845,42 @@ func (q *FakeQuerier) InsertOAuth2ProviderApp(_ context.Context, arg database.In
 	return app, nil
 }
 
+func (q *FakeQuerier) InsertOAuth2ProviderAppCode(_ context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, code := range q.oauth2ProviderAppCodes {
+		if bytes.Equal(code.SecretPrefix, arg.SecretPrefix) {
+			return database.OAuth2ProviderAppCode{}, errDuplicateKey
+		}
+	}
+
+	for _, app := range q.oauth2ProviderApps {
+		if app.ID == arg.AppID {
+			code := database.OAuth2ProviderAppCode{
+				ID:            arg.ID,
+				CreatedAt:     arg.CreatedAt,
+				ExpiresAt:     arg.ExpiresAt,
+				SecretPrefix:  arg.SecretPrefix,
+				HashedSecret:  arg.HashedSecret,
+				UserID:        arg.UserID,
+				AppID:         arg.AppID,
+				Nonce:         arg.Nonce,
+				CodeChallenge: arg.CodeChallenge,
+			}
+			q.oauth2ProviderAppCodes = append(q.oauth2ProviderAppCodes, code)
+			return code, nil
+		}
+	}
+
+	return database.OAuth2ProviderAppCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5127,6 +7907,40 @@ func (q *FakeQuerier) InsertOAuth2ProviderAppSecret(_ context.Context, arg datab
 	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) InsertOAuth2ProviderAppToken(_ context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OAuth2ProviderAppToken{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, token := range q.oauth2ProviderAppTokens {
+		if bytes.Equal(token.HashPrefix, arg.HashPrefix) {
+			return database.OAuth2ProviderAppToken{}, errDuplicateKey
+		}
+	}
+
+	for _, secret := range q.oauth2ProviderAppSecrets {
+		if secret.ID == arg.AppSecretID {
+			token := database.OAuth2ProviderAppToken{
+				ID:          arg.ID,
+				CreatedAt:   arg.CreatedAt,
+				ExpiresAt:   arg.ExpiresAt,
+				HashPrefix:  arg.HashPrefix,
+				RefreshHash: arg.RefreshHash,
+				AppSecretID: arg.AppSecretID,
+				APIKeyID:    arg.APIKeyID,
+			}
+			q.oauth2ProviderAppTokens = append(q.oauth2ProviderAppTokens, token)
+			return token, nil
+		}
+	}
+
+	return database.OAuth2ProviderAppToken{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -5153,6 +7967,12 @@ func (q *FakeQuerier) InsertOrganizationMember(_ context.Context, arg database.I
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	for _, member := range q.organizationMembers {
+		if member.OrganizationID == arg.OrganizationID && member.UserID == arg.UserID {
+			return database.OrganizationMember{}, errDuplicateKey
+		}
+	}
+
 	//nolint:gosimple
 	organizationMember := database.OrganizationMember{
 		OrganizationID: arg.OrganizationID,
@@ -5165,6 +7985,34 @@ func (q *FakeQuerier) InsertOrganizationMember(_ context.Context, arg database.I
 	return organizationMember, nil
 }
 
+func (q *FakeQuerier) InsertPasskey(_ context.Context, arg database.InsertPasskeyParams) (database.Passkey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Passkey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, passkey := range q.passkeys {
+		if bytes.Equal(passkey.CredentialID, arg.CredentialID) {
+			return database.Passkey{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple // Keep the struct literal in sync with the table.
+	passkey := database.Passkey{
+		ID:           arg.ID,
+		UserID:       arg.UserID,
+		Name:         arg.Name,
+		CredentialID: arg.CredentialID,
+		PublicKey:    arg.PublicKey,
+		SignCount:    arg.SignCount,
+		CreatedAt:    arg.CreatedAt,
+	}
+	q.passkeys = append(q.passkeys, passkey)
+	return passkey, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -5174,24 +8022,52 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 	defer q.mutex.Unlock()
 
 	job := database.ProvisionerJob{
-		ID:             arg.ID,
-		CreatedAt:      arg.CreatedAt,
-		UpdatedAt:      arg.UpdatedAt,
-		OrganizationID: arg.OrganizationID,
-		InitiatorID:    arg.InitiatorID,
-		Provisioner:    arg.Provisioner,
-		StorageMethod:  arg.StorageMethod,
-		FileID:         arg.FileID,
-		Type:           arg.Type,
-		Input:          arg.Input,
-		Tags:           maps.Clone(arg.Tags),
-		TraceMetadata:  arg.TraceMetadata,
-	}
+		ID:              arg.ID,
+		CreatedAt:       arg.CreatedAt,
+		UpdatedAt:       arg.UpdatedAt,
+		OrganizationID:  arg.OrganizationID,
+		InitiatorID:     arg.InitiatorID,
+		Provisioner:     arg.Provisioner,
+		StorageMethod:   arg.StorageMethod,
+		FileID:          arg.FileID,
+		Type:            arg.Type,
+		Input:           arg.Input,
+		Tags:            maps.Clone(arg.Tags),
+		TraceMetadata:   arg.TraceMetadata,
+		TagRequirements: database.ProvisionerTagRequirements{},
+	}
+	// The column defaults to an empty array.
+	job.TagRequirements = append(job.TagRequirements, arg.TagRequirements...)
 	job.JobStatus = provisonerJobStatus(job)
 	q.provisionerJobs = append(q.provisionerJobs, job)
 	return job, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerJobLogArchive(_ context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerJobLogArchive{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, archive := range q.provisionerJobLogArchives {
+		if archive.JobID == arg.JobID {
+			return database.ProvisionerJobLogArchive{}, errDuplicateKey
+		}
+	}
+	//nolint:gosimple // Struct conversion is not always the same.
+	archive := database.ProvisionerJobLogArchive{
+		JobID:            arg.JobID,
+		CreatedAt:        arg.CreatedAt,
+		LogCount:         arg.LogCount,
+		UncompressedSize: arg.UncompressedSize,
+		CompressedLogs:   arg.CompressedLogs,
+	}
+	q.provisionerJobLogArchives = append(q.provisionerJobLogArchives, archive)
+	return archive, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -5221,6 +8097,36 @@ func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.I
 	return logs, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerKey(_ context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == arg.OrganizationID && strings.EqualFold(key.Name, arg.Name) {
+			return database.ProvisionerKey{}, errDuplicateKey
+		}
+		if bytes.Equal(key.HashedSecret, arg.HashedSecret) {
+			return database.ProvisionerKey{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	key := database.ProvisionerKey{
+		ID:             arg.ID,
+		CreatedAt:      arg.CreatedAt,
+		OrganizationID: arg.OrganizationID,
+		Name:           arg.Name,
+		HashedSecret:   arg.HashedSecret,
+		Tags:           arg.Tags,
+	}
+	q.provisionerKeys = append(q.provisionerKeys, key)
+	return key, nil
+}
+
 func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Replica{}, err
@@ -5245,6 +8151,19 @@ func (q *FakeQuerier) InsertReplica(_ context.Context, arg database.InsertReplic
 	return replica, nil
 }
 
+func (q *FakeQuerier) InsertSessionRecording(_ context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.SessionRecording{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	recording := database.SessionRecording(arg)
+	q.sessionRecordings = append(q.sessionRecordings, recording)
+	return recording, nil
+}
+
 func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTemplateParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5271,6 +8190,7 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserCancelWorkspaceJobs: arg.AllowUserCancelWorkspaceJobs,
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
+		Visibility:                   arg.Visibility,
 	}
 	q.templates = append(q.templates, template)
 	return nil
@@ -5300,6 +8220,9 @@ func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.Inse
 		Readme:         arg.Readme,
 		JobID:          arg.JobID,
 		CreatedBy:      arg.CreatedBy,
+		GitCommitSha:   arg.GitCommitSha,
+		GitBranch:      arg.GitBranch,
+		GitTag:         arg.GitTag,
 	}
 	q.templateVersions = append(q.templateVersions, version)
 	return nil
@@ -5332,11 +8255,42 @@ func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 		Required:            arg.Required,
 		DisplayOrder:        arg.DisplayOrder,
 		Ephemeral:           arg.Ephemeral,
+		DisplayCondition:    arg.DisplayCondition,
 	}
 	q.templateVersionParameters = append(q.templateVersionParameters, param)
 	return param, nil
 }
 
+func (q *FakeQuerier) InsertTemplateVersionPreset(_ context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVersionPreset{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, preset := range q.templateVersionPresets {
+		if preset.TemplateVersionID == arg.TemplateVersionID && preset.Name == arg.Name {
+			return database.TemplateVersionPreset{}, &pq.Error{
+				Code:       "23505",
+				Message:    "duplicate key value violates unique constraint",
+				Constraint: string(database.UniqueTemplateVersionPresetsPkey),
+			}
+		}
+	}
+
+	//nolint:gosimple
+	preset := database.TemplateVersionPreset{
+		TemplateVersionID: arg.TemplateVersionID,
+		Name:              arg.Name,
+		Parameters:        arg.Parameters,
+		CreatedAt:         arg.CreatedAt,
+	}
+	q.templateVersionPresets = append(q.templateVersionPresets, preset)
+	return preset, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -5360,6 +8314,27 @@ func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg datab
 	return variable, nil
 }
 
+func (q *FakeQuerier) InsertTwoFactorChallenge(_ context.Context, arg database.InsertTwoFactorChallengeParams) (database.TwoFactorChallenge, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TwoFactorChallenge{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple // Keep the struct literal in sync with the table.
+	challenge := database.TwoFactorChallenge{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		Purpose:   arg.Purpose,
+		Challenge: arg.Challenge,
+		CreatedAt: arg.CreatedAt,
+		ExpiresAt: arg.ExpiresAt,
+	}
+	q.twoFactorChallenges = append(q.twoFactorChallenges, challenge)
+	return challenge, nil
+}
+
 func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -5449,6 +8424,75 @@ func (q *FakeQuerier) InsertUserLink(_ context.Context, args database.InsertUser
 	return link, nil
 }
 
+func (q *FakeQuerier) InsertUserRecoveryCode(_ context.Context, arg database.InsertUserRecoveryCodeParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.userRecoveryCodes = append(q.userRecoveryCodes, database.UserRecoveryCode{
+		ID:         arg.ID,
+		UserID:     arg.UserID,
+		HashedCode: arg.HashedCode,
+		CreatedAt:  arg.CreatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertWebhook(_ context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Webhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	webhook := database.Webhook{
+		ID:          arg.ID,
+		Url:         arg.Url,
+		Secret:      arg.Secret,
+		SecretKeyID: arg.SecretKeyID,
+		Events:      arg.Events,
+		CreatedAt:   arg.CreatedAt,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	q.webhooks = append(q.webhooks, webhook)
+	return webhook, nil
+}
+
+func (q *FakeQuerier) InsertWebhookDelivery(_ context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WebhookDelivery{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	found := false
+	for _, webhook := range q.webhooks {
+		if webhook.ID == arg.WebhookID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return database.WebhookDelivery{}, errForeignKeyConstraint
+	}
+
+	delivery := database.WebhookDelivery{
+		ID:            arg.ID,
+		WebhookID:     arg.WebhookID,
+		Event:         arg.Event,
+		Payload:       arg.Payload,
+		CreatedAt:     arg.CreatedAt,
+		NextAttemptAt: arg.NextAttemptAt,
+	}
+	q.webhookDeliveries = append(q.webhookDeliveries, delivery)
+	return delivery, nil
+}
+
 func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -5470,6 +8514,8 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
 		AutomaticUpdates:  arg.AutomaticUpdates,
+		Ephemeral:         arg.Ephemeral,
+		EphemeralAPIKeyID: arg.EphemeralAPIKeyID,
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -5595,6 +8641,52 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentMetadataHistory(_ context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range arg.Key {
+		known := slices.ContainsFunc(q.workspaceAgentMetadata, func(m database.WorkspaceAgentMetadatum) bool {
+			return m.WorkspaceAgentID == arg.WorkspaceAgentID && m.Key == key
+		})
+		if !known {
+			continue
+		}
+
+		// Keep the newest values of the key, oldest first, with room for
+		// the new one.
+		var kept []database.WorkspaceAgentMetadataHistory
+		history := make([]database.WorkspaceAgentMetadataHistory, 0, len(q.workspaceAgentMetadataHistory)+1)
+		for _, h := range q.workspaceAgentMetadataHistory {
+			if h.WorkspaceAgentID == arg.WorkspaceAgentID && h.Key == key {
+				kept = append(kept, h)
+				continue
+			}
+			history = append(history, h)
+		}
+		slices.SortFunc(kept, func(a, b database.WorkspaceAgentMetadataHistory) int {
+			return a.CollectedAt.Compare(b.CollectedAt)
+		})
+		if keep := int(arg.Keep) - 1; len(kept) > keep {
+			kept = kept[len(kept)-max(keep, 0):]
+		}
+		history = append(history, kept...)
+		history = append(history, database.WorkspaceAgentMetadataHistory{
+			WorkspaceAgentID: arg.WorkspaceAgentID,
+			Key:              key,
+			Value:            arg.Value[i],
+			Error:            arg.Error[i],
+			CollectedAt:      arg.CollectedAt[i],
+		})
+		q.workspaceAgentMetadataHistory = history
+	}
+	return nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5616,6 +8708,7 @@ func (q *FakeQuerier) InsertWorkspaceAgentScripts(_ context.Context, arg databas
 			RunOnStart:       arg.RunOnStart[index],
 			RunOnStop:        arg.RunOnStop[index],
 			TimeoutSeconds:   arg.TimeoutSeconds[index],
+			Phase:            arg.Phase[index],
 			CreatedAt:        arg.CreatedAt,
 		}
 		scripts = append(scripts, script)
@@ -5650,6 +8743,12 @@ func (q *FakeQuerier) InsertWorkspaceAgentStat(_ context.Context, p database.Ins
 		SessionCountReconnectingPTY: p.SessionCountReconnectingPTY,
 		SessionCountSSH:             p.SessionCountSSH,
 		ConnectionMedianLatencyMS:   p.ConnectionMedianLatencyMS,
+		CPUUsedCores:                p.CPUUsedCores,
+		CPUTotalCores:               p.CPUTotalCores,
+		MemoryUsedBytes:             p.MemoryUsedBytes,
+		MemoryTotalBytes:            p.MemoryTotalBytes,
+		DiskUsedBytes:               p.DiskUsedBytes,
+		DiskTotalBytes:              p.DiskTotalBytes,
 	}
 	q.workspaceAgentStats = append(q.workspaceAgentStats, stat)
 	return stat, nil
@@ -5691,6 +8790,12 @@ func (q *FakeQuerier) InsertWorkspaceAgentStats(_ context.Context, arg database.
 			SessionCountReconnectingPTY: arg.SessionCountReconnectingPTY[i],
 			SessionCountSSH:             arg.SessionCountSSH[i],
 			ConnectionMedianLatencyMS:   arg.ConnectionMedianLatencyMS[i],
+			CPUUsedCores:                arg.CPUUsedCores[i],
+			CPUTotalCores:               arg.CPUTotalCores[i],
+			MemoryUsedBytes:             arg.MemoryUsedBytes[i],
+			MemoryTotalBytes:            arg.MemoryTotalBytes[i],
+			DiskUsedBytes:               arg.DiskUsedBytes[i],
+			DiskTotalBytes:              arg.DiskTotalBytes[i],
 		}
 		q.workspaceAgentStats = append(q.workspaceAgentStats, stat)
 	}
@@ -5792,6 +8897,7 @@ func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.Inser
 		Deadline:          arg.Deadline,
 		MaxDeadline:       arg.MaxDeadline,
 		Reason:            arg.Reason,
+		Attempt:           arg.Attempt,
 	}
 	q.workspaceBuilds = append(q.workspaceBuilds, workspaceBuild)
 	return nil
@@ -5805,16 +8911,99 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
+	// The value of each parameter is compared with the previous build, like
+	// the trigger that records the parameter changes.
+	var build database.WorkspaceBuildTable
+	for _, b := range q.workspaceBuilds {
+		if b.ID == arg.WorkspaceBuildID {
+			build = b
+		}
+	}
+	var previousBuildID uuid.UUID
+	var previousBuildNumber int32
+	for _, b := range q.workspaceBuilds {
+		if b.WorkspaceID == build.WorkspaceID && b.BuildNumber < build.BuildNumber && b.BuildNumber > previousBuildNumber {
+			previousBuildID = b.ID
+			previousBuildNumber = b.BuildNumber
+		}
+	}
+
 	for index, name := range arg.Name {
+		value := arg.Value[index]
 		q.workspaceBuildParameters = append(q.workspaceBuildParameters, database.WorkspaceBuildParameter{
 			WorkspaceBuildID: arg.WorkspaceBuildID,
 			Name:             name,
-			Value:            arg.Value[index],
+			Value:            value,
+		})
+
+		var oldValue sql.NullString
+		for _, param := range q.workspaceBuildParameters {
+			if param.WorkspaceBuildID == previousBuildID && param.Name == name {
+				oldValue = sql.NullString{String: param.Value, Valid: true}
+			}
+		}
+		if oldValue.Valid && oldValue.String == value {
+			continue
+		}
+		q.workspaceParameterChanges = append(q.workspaceParameterChanges, database.WorkspaceParameterChange{
+			ID:               uuid.New(),
+			WorkspaceID:      build.WorkspaceID,
+			WorkspaceBuildID: build.ID,
+			BuildNumber:      build.BuildNumber,
+			Name:             name,
+			OldValue:         oldValue,
+			NewValue:         value,
+			InitiatorID:      build.InitiatorID,
+			CreatedAt:        build.CreatedAt,
 		})
 	}
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceEvent(_ context.Context, arg database.InsertWorkspaceEventParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.workspaceEvents = append(q.workspaceEvents, database.WorkspaceEvent{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		CreatedAt:   arg.CreatedAt,
+		Type:        arg.Type,
+		UserID:      arg.UserID,
+		AgentID:     arg.AgentID,
+		AppID:       arg.AppID,
+		OldValue:    arg.OldValue,
+		NewValue:    arg.NewValue,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceFavorite(_ context.Context, arg database.InsertWorkspaceFavoriteParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, favorite := range q.workspaceFavorites {
+		if favorite.UserID == arg.UserID && favorite.WorkspaceID == arg.WorkspaceID {
+			return nil
+		}
+	}
+	q.workspaceFavorites = append(q.workspaceFavorites, database.WorkspaceFavorite{
+		UserID:      arg.UserID,
+		WorkspaceID: arg.WorkspaceID,
+		CreatedAt:   arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5865,6 +9054,7 @@ func (q *FakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.In
 		Hide:       arg.Hide,
 		Icon:       arg.Icon,
 		DailyCost:  arg.DailyCost,
+		Snapshot:   arg.Snapshot,
 	}
 	q.workspaceResources = append(q.workspaceResources, resource)
 	return resource, nil
@@ -5901,6 +9091,134 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceSnapshot(_ context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceSnapshot{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, snapshot := range q.workspaceSnapshots {
+		if snapshot.WorkspaceID == arg.WorkspaceID && snapshot.Name == arg.Name {
+			return database.WorkspaceSnapshot{}, errDuplicateKey
+		}
+	}
+	//nolint:gosimple
+	snapshot := database.WorkspaceSnapshot{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		BuildID:     arg.BuildID,
+		Name:        arg.Name,
+		CreatedBy:   arg.CreatedBy,
+		CreatedAt:   arg.CreatedAt,
+	}
+	q.workspaceSnapshots = append(q.workspaceSnapshots, snapshot)
+	return snapshot, nil
+}
+
+func (q *FakeQuerier) ListProvisionerKeysByOrganization(_ context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	keys := make([]database.ProvisionerKey, 0)
+	for _, key := range q.provisionerKeys {
+		if key.OrganizationID == organizationID {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b database.ProvisionerKey) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return keys, nil
+}
+
+// loginAttemptAllowed mirrors the condition RecordLoginAttempt and
+// RecordUnknownLoginAttempt use to decide whether an attempt is backing off.
+func loginAttemptAllowed(failedAttempts int32, lastFailedAt, attemptedAt, resetBefore time.Time, threshold, maxSeconds int64) bool {
+	if lastFailedAt.Before(resetBefore) || int64(failedAttempts) < threshold {
+		return true
+	}
+	exp := int64(failedAttempts) - threshold
+	if exp > 30 {
+		exp = 30
+	}
+	seconds := int64(1) << exp
+	if seconds > maxSeconds {
+		seconds = maxSeconds
+	}
+	return !lastFailedAt.Add(time.Duration(seconds) * time.Second).After(attemptedAt)
+}
+
+func (q *FakeQuerier) RecordLoginAttempt(_ context.Context, arg database.RecordLoginAttemptParams) (database.LoginFailure, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.LoginFailure{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, failure := range q.loginFailures {
+		if failure.UserID != arg.UserID || failure.IPAddress.IPNet.String() != arg.IPAddress.IPNet.String() {
+			continue
+		}
+		if !loginAttemptAllowed(failure.FailedAttempts, failure.LastFailedAt, arg.AttemptedAt, arg.ResetBefore, arg.BackoffThreshold, arg.BackoffMaxSeconds) {
+			return database.LoginFailure{}, sql.ErrNoRows
+		}
+		if failure.LastFailedAt.Before(arg.ResetBefore) {
+			failure.FailedAttempts = 1
+		} else {
+			failure.FailedAttempts++
+		}
+		failure.LastFailedAt = arg.AttemptedAt
+		q.loginFailures[i] = failure
+		return failure, nil
+	}
+	failure := database.LoginFailure{
+		UserID:         arg.UserID,
+		IPAddress:      arg.IPAddress,
+		FailedAttempts: 1,
+		LastFailedAt:   arg.AttemptedAt,
+	}
+	q.loginFailures = append(q.loginFailures, failure)
+	return failure, nil
+}
+
+func (q *FakeQuerier) RecordUnknownLoginAttempt(_ context.Context, arg database.RecordUnknownLoginAttemptParams) (database.UnknownLoginFailure, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UnknownLoginFailure{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, failure := range q.unknownLoginFailures {
+		if !bytes.Equal(failure.LoginHash, arg.LoginHash) || failure.IPAddress.IPNet.String() != arg.IPAddress.IPNet.String() {
+			continue
+		}
+		if !loginAttemptAllowed(failure.FailedAttempts, failure.LastFailedAt, arg.AttemptedAt, arg.ResetBefore, arg.BackoffThreshold, arg.BackoffMaxSeconds) {
+			return database.UnknownLoginFailure{}, sql.ErrNoRows
+		}
+		if failure.LastFailedAt.Before(arg.ResetBefore) {
+			failure.FailedAttempts = 1
+		} else {
+			failure.FailedAttempts++
+		}
+		failure.LastFailedAt = arg.AttemptedAt
+		q.unknownLoginFailures[i] = failure
+		return failure, nil
+	}
+	failure := database.UnknownLoginFailure{
+		LoginHash:      arg.LoginHash,
+		IPAddress:      arg.IPAddress,
+		FailedAttempts: 1,
+		LastFailedAt:   arg.AttemptedAt,
+	}
+	q.unknownLoginFailures = append(q.unknownLoginFailures, failure)
+	return failure, nil
+}
+
 func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5920,6 +9238,31 @@ func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.Reg
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) RequeueProvisionerJobByID(_ context.Context, arg database.RequeueProvisionerJobByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		if job.CompletedAt.Valid {
+			return nil
+		}
+		job.UpdatedAt = arg.UpdatedAt
+		job.StartedAt = sql.NullTime{}
+		job.WorkerID = uuid.NullUUID{}
+		job.JobStatus = provisonerJobStatus(job)
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) RevokeDBCryptKey(_ context.Context, activeKeyDigest string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5949,6 +9292,21 @@ func (q *FakeQuerier) RevokeDBCryptKey(_ context.Context, activeKeyDigest string
 				return errForeignKeyConstraint
 			}
 		}
+		for _, tvv := range q.templateVariableValues {
+			if tvv.ValueKeyID.Valid && tvv.ValueKeyID.String == activeKeyDigest {
+				return errForeignKeyConstraint
+			}
+		}
+		for _, otvv := range q.organizationTemplateVariables {
+			if otvv.ValueKeyID.Valid && otvv.ValueKeyID.String == activeKeyDigest {
+				return errForeignKeyConstraint
+			}
+		}
+		for _, webhook := range q.webhooks {
+			if webhook.SecretKeyID.Valid && webhook.SecretKeyID.String == activeKeyDigest {
+				return errForeignKeyConstraint
+			}
+		}
 
 		// Revoke the key.
 		q.dbcryptKeys[i].RevokedAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
@@ -6098,6 +9456,51 @@ func (q *FakeQuerier) UpdateInactiveUsersToDormant(_ context.Context, params dat
 	return updated, nil
 }
 
+func (q *FakeQuerier) UpdateInactiveUsersToSuspended(_ context.Context, arg database.UpdateInactiveUsersToSuspendedParams) ([]database.UpdateInactiveUsersToSuspendedRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var updated []database.UpdateInactiveUsersToSuspendedRow
+	for index, user := range q.users {
+		if user.Deleted || user.Status == database.UserStatusSuspended || slices.Contains(user.RBACRoles, rbac.RoleOwner()) ||
+			!userInactiveSince(user, arg.InactiveSince) {
+			continue
+		}
+		q.users[index].Status = database.UserStatusSuspended
+		q.users[index].UpdatedAt = arg.UpdatedAt
+		updated = append(updated, database.UpdateInactiveUsersToSuspendedRow{
+			ID:         user.ID,
+			Email:      user.Email,
+			LastSeenAt: user.LastSeenAt,
+		})
+	}
+	return updated, nil
+}
+
+func (q *FakeQuerier) UpdateInboxNotificationsReadByUserID(_ context.Context, arg database.UpdateInboxNotificationsReadByUserIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, notif := range q.inboxNotifications {
+		if notif.UserID != arg.UserID || notif.ReadAt.Valid {
+			continue
+		}
+		if len(arg.IDs) > 0 && !slices.Contains(arg.IDs, notif.ID) {
+			continue
+		}
+		q.inboxNotifications[i].ReadAt = sql.NullTime{Time: arg.ReadAt, Valid: true}
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.OrganizationMember{}, err
@@ -6128,6 +9531,28 @@ func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateNotificationMessageByID(_ context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, msg := range q.notificationMessages {
+		if msg.ID != arg.ID {
+			continue
+		}
+		msg.Attempts = arg.Attempts
+		msg.LastError = arg.LastError
+		msg.SentAt = arg.SentAt
+		msg.NextAttemptAt = arg.NextAttemptAt
+		q.notificationMessages[index] = msg
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateOAuth2ProviderAppByID(_ context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6186,6 +9611,25 @@ func (q *FakeQuerier) UpdateOAuth2ProviderAppSecretByID(_ context.Context, arg d
 	return database.OAuth2ProviderAppSecret{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdatePasskeyUsage(_ context.Context, arg database.UpdatePasskeyUsageParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, passkey := range q.passkeys {
+		if passkey.ID == arg.ID {
+			passkey.SignCount = arg.SignCount
+			passkey.LastUsedAt = arg.LastUsedAt
+			q.passkeys[i] = passkey
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerDaemonLastSeenAt(_ context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6228,6 +9672,26 @@ func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateProvisionerJobPriorityByID(_ context.Context, arg database.UpdateProvisionerJobPriorityByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		job.Priority = arg.Priority
+		job.UpdatedAt = arg.UpdatedAt
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobWithCancelByID(_ context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6272,6 +9736,26 @@ func (q *FakeQuerier) UpdateProvisionerJobWithCompleteByID(_ context.Context, ar
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateProvisionerKeySecretByID(_ context.Context, arg database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, key := range q.provisionerKeys {
+		if key.ID != arg.ID {
+			continue
+		}
+		key.HashedSecret = arg.HashedSecret
+		key.RotatedAt = arg.RotatedAt
+		q.provisionerKeys[i] = key
+		return key, nil
+	}
+	return database.ProvisionerKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Replica{}, err
@@ -6300,6 +9784,15 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (*FakeQuerier) UpdateTailnetPeerStatusByCoordinator(_ context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	return ErrUnimplemented
+}
+
 func (q *FakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6361,6 +9854,27 @@ func (q *FakeQuerier) UpdateTemplateActiveVersionByID(_ context.Context, arg dat
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateBuildRetryPolicyByID(_ context.Context, arg database.UpdateTemplateBuildRetryPolicyByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].BuildRetryMaxAttempts = arg.BuildRetryMaxAttempts
+		q.templates[idx].BuildRetryBackoff = arg.BuildRetryBackoff
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateDeletedByID(_ context.Context, arg database.UpdateTemplateDeletedByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6381,6 +9895,67 @@ func (q *FakeQuerier) UpdateTemplateDeletedByID(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateMaintenanceWindowByID(_ context.Context, arg database.UpdateTemplateMaintenanceWindowByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].MaintenanceWindowSchedule = arg.MaintenanceWindowSchedule
+		q.templates[idx].MaintenanceWindowDuration = arg.MaintenanceWindowDuration
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateTemplateMaxBuildDurationByID(_ context.Context, arg database.UpdateTemplateMaxBuildDurationByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].MaxBuildDuration = arg.MaxBuildDuration
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateTemplateMaxRunningWorkspacesByID(_ context.Context, arg database.UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].MaxRunningWorkspaces = arg.MaxRunningWorkspaces
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.UpdateTemplateMetaByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6407,6 +9982,27 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplatePortForwardingPolicyByID(_ context.Context, arg database.UpdateTemplatePortForwardingPolicyByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].PortForwardingDenyByDefault = arg.PortForwardingDenyByDefault
+		q.templates[idx].PortForwardingAllowedPorts = arg.PortForwardingAllowedPorts
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6438,6 +10034,27 @@ func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateSessionRecordingByID(_ context.Context, arg database.UpdateTemplateSessionRecordingByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].SessionRecordingEnabled = arg.SessionRecordingEnabled
+		q.templates[idx].SessionRecordingRetention = arg.SessionRecordingRetention
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateVersionByID(_ context.Context, arg database.UpdateTemplateVersionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -6500,6 +10117,47 @@ func (q *FakeQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(_ contex
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateVisibilityByID(_ context.Context, arg database.UpdateTemplateVisibilityByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].Visibility = arg.Visibility
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateTemplateWorkspaceNamePolicyByID(_ context.Context, arg database.UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, tpl := range q.templates {
+		if tpl.ID != arg.ID {
+			continue
+		}
+		q.templates[idx].WorkspaceNamePattern = arg.WorkspaceNamePattern
+		q.templates[idx].WorkspaceNameDescription = arg.WorkspaceNameDescription
+		q.templates[idx].UpdatedAt = arg.UpdatedAt
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateWorkspacesLastUsedAt(_ context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -6592,6 +10250,27 @@ func (q *FakeQuerier) UpdateUserHashedPassword(_ context.Context, arg database.U
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateUserIPAllowlist(_ context.Context, arg database.UpdateUserIPAllowlistParams) (database.User, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, user := range q.users {
+		if user.ID != arg.ID {
+			continue
+		}
+		user.IPAllowlist = arg.IPAllowlist
+		user.UpdatedAt = arg.UpdatedAt
+		q.users[index] = user
+		return user, nil
+	}
+	return database.User{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateUserLastSeenAt(_ context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -6773,6 +10452,49 @@ func (q *FakeQuerier) UpdateUserStatus(_ context.Context, arg database.UpdateUse
 	return database.User{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWebhookDeliveryByID(_ context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, delivery := range q.webhookDeliveries {
+		if delivery.ID != arg.ID {
+			continue
+		}
+		delivery.Attempts = arg.Attempts
+		delivery.StatusCode = arg.StatusCode
+		delivery.LastError = arg.LastError
+		delivery.DeliveredAt = arg.DeliveredAt
+		delivery.NextAttemptAt = arg.NextAttemptAt
+		q.webhookDeliveries[index] = delivery
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWebhookSecretByID(_ context.Context, arg database.UpdateWebhookSecretByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, webhook := range q.webhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		webhook.Secret = arg.Secret
+		webhook.SecretKeyID = arg.SecretKeyID
+		q.webhooks[index] = webhook
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -6803,6 +10525,46 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceACLByID(_ context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.UserACL = arg.UserACL
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceAgentAuthTokenByID(_ context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.workspaceAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+		agent.AuthToken = arg.AuthToken
+		agent.UpdatedAt = arg.UpdatedAt
+		q.workspaceAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7046,6 +10808,27 @@ func (q *FakeQuerier) UpdateWorkspaceBuildProvisionerStateByID(_ context.Context
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceBuildRetryAtByID(_ context.Context, arg database.UpdateWorkspaceBuildRetryAtByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for idx, build := range q.workspaceBuilds {
+		if build.ID != arg.ID {
+			continue
+		}
+		build.RetryAt = arg.RetryAt
+		build.UpdatedAt = arg.UpdatedAt
+		q.workspaceBuilds[idx] = build
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7065,6 +10848,26 @@ func (q *FakeQuerier) UpdateWorkspaceDeletedByID(_ context.Context, arg database
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceDormancyExempt(_ context.Context, arg database.UpdateWorkspaceDormancyExemptParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.DormancyExempt = arg.DormancyExempt
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceDormantDeletingAt(_ context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.Workspace, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Workspace{}, err
@@ -7104,6 +10907,28 @@ func (q *FakeQuerier) UpdateWorkspaceDormantDeletingAt(_ context.Context, arg da
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceDriftResult(_ context.Context, arg database.UpdateWorkspaceDriftResultParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, drift := range q.workspaceDrift {
+		if drift.WorkspaceID != arg.WorkspaceID || drift.JobID != arg.JobID {
+			continue
+		}
+		drift.BuildID = arg.BuildID
+		drift.CheckedAt = sql.NullTime{Time: arg.CheckedAt, Valid: true}
+		drift.DriftedResources = arg.DriftedResources
+		drift.UpdatedAt = arg.CheckedAt
+		q.workspaceDrift[i] = drift
+	}
+	return nil
+}
+
 func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -7124,6 +10949,56 @@ func (q *FakeQuerier) UpdateWorkspaceLastUsedAt(_ context.Context, arg database.
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceMaintenanceOptOut(_ context.Context, arg database.UpdateWorkspaceMaintenanceOptOutParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.MaintenanceOptOut = arg.MaintenanceOptOut
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceOwnerByID(_ context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, workspace := range q.workspaces {
+		if workspace.Deleted || workspace.ID != arg.ID {
+			continue
+		}
+		for _, other := range q.workspaces {
+			if other.Deleted || other.ID == workspace.ID || other.OwnerID != arg.OwnerID {
+				continue
+			}
+			if strings.EqualFold(other.Name, workspace.Name) {
+				return database.Workspace{}, errDuplicateKey
+			}
+		}
+
+		workspace.OwnerID = arg.OwnerID
+		workspace.UpdatedAt = arg.UpdatedAt
+		q.workspaces[i] = workspace
+		return workspace, nil
+	}
+
+	return database.Workspace{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -7238,6 +11113,36 @@ func (q *FakeQuerier) UpsertApplicationName(_ context.Context, data string) erro
 	return nil
 }
 
+func (q *FakeQuerier) UpsertCustomRole(_ context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.CustomRole{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, role := range q.customRoles {
+		if role.Name == arg.Name {
+			role.DisplayName = arg.DisplayName
+			role.SitePermissions = arg.SitePermissions
+			role.UpdatedAt = dbtime.Now()
+			q.customRoles[i] = role
+			return role, nil
+		}
+	}
+
+	role := database.CustomRole{
+		Name:            arg.Name,
+		DisplayName:     arg.DisplayName,
+		SitePermissions: arg.SitePermissions,
+		CreatedAt:       dbtime.Now(),
+		UpdatedAt:       dbtime.Now(),
+	}
+	q.customRoles = append(q.customRoles, role)
+	return role, nil
+}
+
 func (q *FakeQuerier) UpsertDefaultProxy(_ context.Context, arg database.UpsertDefaultProxyParams) error {
 	q.defaultProxyDisplayName = arg.DisplayName
 	q.defaultProxyIconURL = arg.IconUrl
@@ -7260,6 +11165,14 @@ func (q *FakeQuerier) UpsertLastUpdateCheck(_ context.Context, data string) erro
 	return nil
 }
 
+func (q *FakeQuerier) UpsertLicenseGracePeriodStartedAt(_ context.Context, value string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.licenseGraceStartedAt = value
+	return nil
+}
+
 func (q *FakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -7268,6 +11181,30 @@ func (q *FakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	return nil
 }
 
+func (q *FakeQuerier) UpsertNotificationPreference(_ context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NotificationPreference{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	pref := database.NotificationPreference{
+		UserID:    arg.UserID,
+		Template:  arg.Template,
+		Disabled:  arg.Disabled,
+		UpdatedAt: arg.UpdatedAt,
+	}
+	for index, existing := range q.notificationPreferences {
+		if existing.UserID == arg.UserID && existing.Template == arg.Template {
+			q.notificationPreferences[index] = pref
+			return pref, nil
+		}
+	}
+	q.notificationPreferences = append(q.notificationPreferences, pref)
+	return pref, nil
+}
+
 func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -7276,6 +11213,51 @@ func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) err
 	return nil
 }
 
+func (q *FakeQuerier) UpsertOrganizationQuota(_ context.Context, arg database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.OrganizationQuota{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	quota := database.OrganizationQuota{
+		OrganizationID:              arg.OrganizationID,
+		MaxRunningWorkspacesPerUser: arg.MaxRunningWorkspacesPerUser,
+		MaxDailyCost:                arg.MaxDailyCost,
+		UpdatedAt:                   arg.UpdatedAt,
+	}
+	for i, existing := range q.organizationQuotas {
+		if existing.OrganizationID == arg.OrganizationID {
+			q.organizationQuotas[i] = quota
+			return quota, nil
+		}
+	}
+	q.organizationQuotas = append(q.organizationQuotas, quota)
+	return quota, nil
+}
+
+func (q *FakeQuerier) UpsertOrganizationTemplateVariableValue(_ context.Context, arg database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.OrganizationTemplateVariableValue{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	value := database.OrganizationTemplateVariableValue(arg)
+	for i, existing := range q.organizationTemplateVariables {
+		if existing.OrganizationID == arg.OrganizationID && existing.Name == arg.Name {
+			value.CreatedAt = existing.CreatedAt
+			q.organizationTemplateVariables[i] = value
+			return value, nil
+		}
+	}
+	q.organizationTemplateVariables = append(q.organizationTemplateVariables, value)
+	return value, nil
+}
+
 func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -7296,19 +11278,21 @@ func (q *FakeQuerier) UpsertProvisionerDaemon(_ context.Context, arg database.Up
 			d.Tags = maps.Clone(arg.Tags)
 			d.Version = arg.Version
 			d.LastSeenAt = arg.LastSeenAt
+			d.OrganizationID = arg.OrganizationID
 			return d, nil
 		}
 	}
 	d := database.ProvisionerDaemon{
-		ID:           uuid.New(),
-		CreatedAt:    arg.CreatedAt,
-		Name:         arg.Name,
-		Provisioners: arg.Provisioners,
-		Tags:         maps.Clone(arg.Tags),
-		ReplicaID:    uuid.NullUUID{},
-		LastSeenAt:   arg.LastSeenAt,
-		Version:      arg.Version,
-		APIVersion:   arg.APIVersion,
+		ID:             uuid.New(),
+		CreatedAt:      arg.CreatedAt,
+		Name:           arg.Name,
+		Provisioners:   arg.Provisioners,
+		Tags:           maps.Clone(arg.Tags),
+		ReplicaID:      uuid.NullUUID{},
+		LastSeenAt:     arg.LastSeenAt,
+		Version:        arg.Version,
+		APIVersion:     arg.APIVersion,
+		OrganizationID: arg.OrganizationID,
 	}
 	q.provisionerDaemons = append(q.provisionerDaemons, d)
 	return d, nil
@@ -7356,6 +11340,397 @@ func (*FakeQuerier) UpsertTailnetTunnel(_ context.Context, arg database.UpsertTa
 	return database.TailnetTunnel{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) UpsertTemplateVariableValue(_ context.Context, arg database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVariableValue{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	value := database.TemplateVariableValue(arg)
+	for i, existing := range q.templateVariableValues {
+		if existing.TemplateID == arg.TemplateID && existing.Name == arg.Name {
+			value.CreatedAt = existing.CreatedAt
+			q.templateVariableValues[i] = value
+			return value, nil
+		}
+	}
+	q.templateVariableValues = append(q.templateVariableValues, value)
+	return value, nil
+}
+
+func (q *FakeQuerier) UpsertTwoFactorPolicy(_ context.Context, arg database.UpsertTwoFactorPolicyParams) (database.TwoFactorPolicy, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.TwoFactorPolicy{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, policy := range q.twoFactorPolicies {
+		if policy.Role == arg.Role {
+			policy.EnforceAfter = arg.EnforceAfter
+			policy.UpdatedAt = arg.UpdatedAt
+			q.twoFactorPolicies[i] = policy
+			return policy, nil
+		}
+	}
+	//nolint:gosimple // Keep the struct literal in sync with the table.
+	policy := database.TwoFactorPolicy{
+		ID:           arg.ID,
+		Role:         arg.Role,
+		EnforceAfter: arg.EnforceAfter,
+		CreatedAt:    arg.CreatedAt,
+		UpdatedAt:    arg.UpdatedAt,
+	}
+	q.twoFactorPolicies = append(q.twoFactorPolicies, policy)
+	return policy, nil
+}
+
+func (q *FakeQuerier) UpsertUserActivity(_ context.Context, arg database.UpsertUserActivityParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+NextActivity:
+	for i, userID := range arg.UserID {
+		for j, a := range q.userActivity {
+			if a.UserID == userID && a.Hour.Equal(arg.Hour[i]) && a.Category == arg.Category[i] && a.WorkspaceID == arg.WorkspaceID[i] {
+				q.userActivity[j].Count += arg.Count[i]
+				continue NextActivity
+			}
+		}
+		q.userActivity = append(q.userActivity, database.UserActivity{
+			UserID:      userID,
+			Hour:        arg.Hour[i],
+			Category:    arg.Category[i],
+			WorkspaceID: arg.WorkspaceID[i],
+			Count:       arg.Count[i],
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpsertUserLockout(_ context.Context, arg database.UpsertUserLockoutParams) (database.UserLockout, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserLockout{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple // Keep the struct literal in sync with the table.
+	lockout := database.UserLockout{
+		UserID:         arg.UserID,
+		FailedAttempts: arg.FailedAttempts,
+		LockedAt:       arg.LockedAt,
+		LockedUntil:    arg.LockedUntil,
+	}
+	for i, existing := range q.userLockouts {
+		if existing.UserID == arg.UserID {
+			q.userLockouts[i] = lockout
+			return lockout, nil
+		}
+	}
+	q.userLockouts = append(q.userLockouts, lockout)
+	return lockout, nil
+}
+
+func (q *FakeQuerier) UpsertUserSCIMExternalID(_ context.Context, arg database.UpsertUserSCIMExternalIDParams) (database.UserSCIMExternalID, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.UserSCIMExternalID{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, id := range q.userSCIMExternalIDs {
+		if id.ExternalID == arg.ExternalID && id.UserID != arg.UserID {
+			return database.UserSCIMExternalID{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	upserted := database.UserSCIMExternalID{
+		UserID:     arg.UserID,
+		ExternalID: arg.ExternalID,
+	}
+	for i, id := range q.userSCIMExternalIDs {
+		if id.UserID == arg.UserID {
+			q.userSCIMExternalIDs[i] = upserted
+			return upserted, nil
+		}
+	}
+	q.userSCIMExternalIDs = append(q.userSCIMExternalIDs, upserted)
+	return upserted, nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceAgentGPUs(_ context.Context, arg database.UpsertWorkspaceAgentGPUsParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+	n := len(arg.Index)
+	if len(arg.Vendor) != n || len(arg.Model) != n || len(arg.MemoryBytes) != n || len(arg.DriverVersion) != n {
+		return xerrors.Errorf("mismatched GPU field lengths")
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	gpus := make([]database.WorkspaceAgentGPU, 0, len(q.workspaceAgentGPUs)+n)
+	for _, gpu := range q.workspaceAgentGPUs {
+		if gpu.AgentID != arg.AgentID {
+			gpus = append(gpus, gpu)
+		}
+	}
+	for i, index := range arg.Index {
+		gpus = append(gpus, database.WorkspaceAgentGPU{
+			AgentID:       arg.AgentID,
+			Index:         index,
+			Vendor:        arg.Vendor[i],
+			Model:         arg.Model[i],
+			MemoryBytes:   arg.MemoryBytes[i],
+			DriverVersion: arg.DriverVersion[i],
+		})
+	}
+	q.workspaceAgentGPUs = gpus
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceAgentPortShare(_ context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentPortShare{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	share := database.WorkspaceAgentPortShare(arg)
+	for i, existing := range q.workspaceAgentPortShares {
+		if existing.WorkspaceID == arg.WorkspaceID && existing.AgentName == arg.AgentName && existing.Port == arg.Port {
+			q.workspaceAgentPortShares[i] = share
+			return share, nil
+		}
+	}
+	q.workspaceAgentPortShares = append(q.workspaceAgentPortShares, share)
+	return share, nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceAgentPorts(_ context.Context, arg database.UpsertWorkspaceAgentPortsParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+	if len(arg.Port) != len(arg.ProcessName) {
+		return xerrors.Errorf("%d ports but %d process names", len(arg.Port), len(arg.ProcessName))
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	discoveredAt := make(map[int32]time.Time)
+	ports := make([]database.WorkspaceAgentPort, 0, len(q.workspaceAgentPorts))
+	for _, port := range q.workspaceAgentPorts {
+		if port.AgentID == arg.AgentID {
+			discoveredAt[port.Port] = port.DiscoveredAt
+			continue
+		}
+		ports = append(ports, port)
+	}
+	for i, number := range arg.Port {
+		port := database.WorkspaceAgentPort{
+			AgentID:      arg.AgentID,
+			Port:         number,
+			ProcessName:  arg.ProcessName[i],
+			DiscoveredAt: arg.DiscoveredAt,
+		}
+		if previous, ok := discoveredAt[number]; ok {
+			port.DiscoveredAt = previous
+		}
+		ports = append(ports, port)
+	}
+	q.workspaceAgentPorts = ports
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceAgentPreviousAuthToken(_ context.Context, arg database.UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	token := database.WorkspaceAgentPreviousAuthToken(arg)
+	for index, existing := range q.workspaceAgentPreviousAuthTokens {
+		if existing.AgentID == arg.AgentID {
+			q.workspaceAgentPreviousAuthTokens[index] = token
+			return nil
+		}
+	}
+	q.workspaceAgentPreviousAuthTokens = append(q.workspaceAgentPreviousAuthTokens, token)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceDriftJob(_ context.Context, arg database.UpsertWorkspaceDriftJobParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, drift := range q.workspaceDrift {
+		if drift.WorkspaceID != arg.WorkspaceID {
+			continue
+		}
+		drift.JobID = arg.JobID
+		drift.UpdatedAt = arg.Now
+		q.workspaceDrift[i] = drift
+		return nil
+	}
+	q.workspaceDrift = append(q.workspaceDrift, database.WorkspaceDrift{
+		WorkspaceID:      arg.WorkspaceID,
+		JobID:            arg.JobID,
+		DriftedResources: []string{},
+		CreatedAt:        arg.Now,
+		UpdatedAt:        arg.Now,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceProxyHealth(_ context.Context, arg database.UpsertWorkspaceProxyHealthParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	health := database.WorkspaceProxyHealth{
+		ProxyID:       arg.ProxyID,
+		Status:        arg.Status,
+		LatencyMS:     arg.LatencyMS,
+		DerpReachable: arg.DerpReachable,
+		VersionSkew:   arg.VersionSkew,
+		Errors:        arg.Errors,
+		Warnings:      arg.Warnings,
+		CheckedAt:     arg.CheckedAt,
+	}
+	if health.Errors == nil {
+		health.Errors = []string{}
+	}
+	if health.Warnings == nil {
+		health.Warnings = []string{}
+	}
+	for i, h := range q.workspaceProxyHealth {
+		if h.ProxyID == arg.ProxyID {
+			q.workspaceProxyHealth[i] = health
+			return nil
+		}
+	}
+	q.workspaceProxyHealth = append(q.workspaceProxyHealth, health)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceResourceCosts(_ context.Context, arg database.UpsertWorkspaceResourceCostsParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	date := truncateToDate(arg.Date)
+	for _, workspace := range q.workspaces {
+		if workspace.Deleted {
+			continue
+		}
+		var latestBuild database.WorkspaceBuildTable
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID == workspace.ID && build.BuildNumber > latestBuild.BuildNumber {
+				latestBuild = build
+			}
+		}
+		if latestBuild.ID == uuid.Nil {
+			continue
+		}
+
+		costs := make(map[[2]string]*database.WorkspaceResourceCost)
+		for _, resource := range q.workspaceResources {
+			if resource.JobID != latestBuild.JobID || resource.DailyCost <= 0 {
+				continue
+			}
+			currency := arg.DefaultCurrency
+			for _, metadatum := range q.workspaceResourceMetadata {
+				if metadatum.WorkspaceResourceID == resource.ID && metadatum.Key == "currency" && metadatum.Value.Valid {
+					currency = metadatum.Value.String
+				}
+			}
+			key := [2]string{resource.Type, resource.Name}
+			cost, ok := costs[key]
+			if !ok {
+				cost = &database.WorkspaceResourceCost{
+					WorkspaceID:    workspace.ID,
+					OrganizationID: workspace.OrganizationID,
+					Date:           date,
+					ResourceType:   resource.Type,
+					ResourceName:   resource.Name,
+					Currency:       currency,
+					UpdatedAt:      arg.UpdatedAt,
+				}
+				costs[key] = cost
+			}
+			if currency > cost.Currency {
+				cost.Currency = currency
+			}
+			cost.DailyCost += resource.DailyCost
+		}
+
+		for _, cost := range costs {
+			replaced := false
+			for i, existing := range q.workspaceResourceCosts {
+				if existing.WorkspaceID == cost.WorkspaceID && existing.Date.Equal(cost.Date) &&
+					existing.ResourceType == cost.ResourceType && existing.ResourceName == cost.ResourceName {
+					q.workspaceResourceCosts[i] = *cost
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				q.workspaceResourceCosts = append(q.workspaceResourceCosts, *cost)
+			}
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UseUserRecoveryCode(_ context.Context, arg database.UseUserRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserRecoveryCode{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, code := range q.userRecoveryCodes {
+		if code.ID == arg.ID && code.UserID == arg.UserID && !code.UsedAt.Valid {
+			code.UsedAt = arg.UsedAt
+			q.userRecoveryCodes[i] = code
+			return code, nil
+		}
+	}
+	return database.UserRecoveryCode{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -7375,7 +11750,11 @@ func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.G
 	var templates []database.Template
 	for _, templateTable := range q.templates {
 		template := q.templateWithUserNoLock(templateTable)
-		if prepared != nil && prepared.Authorize(ctx, template.RBACObject()) != nil {
+		rbacObject := template.ListRBACObject()
+		if len(arg.IDs) > 0 {
+			rbacObject = template.RBACObject()
+		}
+		if prepared != nil && prepared.Authorize(ctx, rbacObject) != nil {
 			continue
 		}
 
@@ -7514,6 +11893,13 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 		}
 	}
 
+	favorites := make(map[uuid.UUID]bool)
+	for _, favorite := range q.workspaceFavorites {
+		if favorite.UserID == arg.RequesterID {
+			favorites[favorite.WorkspaceID] = true
+		}
+	}
+
 	workspaces := make([]database.Workspace, 0)
 	for _, workspace := range q.workspaces {
 		if arg.OwnerID != uuid.Nil && workspace.OwnerID != arg.OwnerID {
@@ -7554,6 +11940,10 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 			}
 		}
 
+		if arg.Favorite && !favorites[workspace.ID] {
+			continue
+		}
+
 		if arg.Status != "" {
 			build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
 			if err != nil {
@@ -7696,6 +12086,11 @@ func (q *FakeQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 		w1 := workspaces[i]
 		w2 := workspaces[j]
 
+		// Order by: favorites first
+		if favorites[w1.ID] != favorites[w2.ID] {
+			return favorites[w1.ID]
+		}
+
 		// Order by: running first
 		w1IsRunning := isRunning(preloadedWorkspaceBuilds[w1.ID], preloadedProvisionerJobs[w1.ID])
 		w2IsRunning := isRunning(preloadedWorkspaceBuilds[w2.ID], preloadedProvisionerJobs[w2.ID])
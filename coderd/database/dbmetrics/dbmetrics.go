@@ -6,9 +6,11 @@ package dbmetrics
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/exp/slices"
 
@@ -44,12 +46,37 @@ func New(s database.Store, reg prometheus.Registerer) database.Store {
 		Help:      "Duration of transactions in seconds.",
 		Buckets:   prometheus.DefBuckets,
 	})
+	txRetries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "db",
+		Name:      "tx_retries_total",
+		Help:      "Total number of times transactions were retried after a serialization failure or deadlock.",
+	}, []string{"tx_id"})
+	queryErrors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Total number of queries that returned an error, labelled by the Postgres error class.",
+	}, []string{"query", "class"})
+	queryRows := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "db",
+		Name:      "query_rows_returned",
+		Help:      "Number of rows returned by list queries.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+	}, []string{"query"})
 	reg.MustRegister(queryLatencies)
 	reg.MustRegister(txDuration)
+	reg.MustRegister(txRetries)
+	reg.MustRegister(queryErrors)
+	reg.MustRegister(queryRows)
 	return &metricsStore{
 		s:              s,
 		queryLatencies: queryLatencies,
 		txDuration:     txDuration,
+		txRetries:      txRetries,
+		queryErrors:    queryErrors,
+		queryRows:      queryRows,
 	}
 }
 
@@ -59,6 +86,53 @@ type metricsStore struct {
 	s              database.Store
 	queryLatencies *prometheus.HistogramVec
 	txDuration     prometheus.Histogram
+	txRetries      *prometheus.CounterVec
+	queryErrors    *prometheus.CounterVec
+	queryRows      *prometheus.HistogramVec
+}
+
+func (m metricsStore) DeleteUnknownLoginFailure(ctx context.Context, arg database.DeleteUnknownLoginFailureParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteUnknownLoginFailure(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteUnknownLoginFailure").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteUnknownLoginFailure", r0)
+	return r0
+}
+
+func (m metricsStore) GetWorkspaceAgentAuthTokenByIDForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentAuthTokenByIDForUpdate(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentAuthTokenByIDForUpdate").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentAuthTokenByIDForUpdate", r1)
+	return r0, r1
+}
+
+// observeError increments the error counter for the given query. Not found
+// errors are expected in normal operation and are not counted.
+func (m metricsStore) observeError(query string, err error) {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	m.queryErrors.WithLabelValues(query, errorClass(err)).Inc()
+}
+
+// observeRows records the number of rows returned by a list query.
+func (m metricsStore) observeRows(query string, rows int) {
+	m.queryRows.WithLabelValues(query).Observe(float64(rows))
+}
+
+// errorClass returns the Postgres error class name for err, e.g.
+// "integrity_constraint_violation". Errors that did not originate from
+// Postgres are classified as "canceled" or "unknown".
+func errorClass(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code.Class().Name()
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "canceled"
+	}
+	return "unknown"
 }
 
 func (m metricsStore) Wrappers() []string {
@@ -69,13 +143,25 @@ func (m metricsStore) Ping(ctx context.Context) (time.Duration, error) {
 	start := time.Now()
 	duration, err := m.s.Ping(ctx)
 	m.queryLatencies.WithLabelValues("Ping").Observe(time.Since(start).Seconds())
+	m.observeError("Ping", err)
 	return duration, err
 }
 
-func (m metricsStore) InTx(f func(database.Store) error, options *sql.TxOptions) error {
+func (m metricsStore) InTx(f func(database.Store) error, options *database.TxOptions) error {
+	if options == nil {
+		options = database.DefaultTXOptions()
+	}
+
 	start := time.Now()
 	err := m.s.InTx(f, options)
 	m.txDuration.Observe(time.Since(start).Seconds())
+	if retries := options.ExecutionCount() - 1; retries > 0 {
+		id := options.TxIdentifier
+		if id == "" {
+			id = "unlabeled"
+		}
+		m.txRetries.WithLabelValues(id).Add(float64(retries))
+	}
 	return err
 }
 
@@ -83,20 +169,41 @@ func (m metricsStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64)
 	start := time.Now()
 	err := m.s.AcquireLock(ctx, pgAdvisoryXactLock)
 	m.queryLatencies.WithLabelValues("AcquireLock").Observe(time.Since(start).Seconds())
+	m.observeError("AcquireLock", err)
 	return err
 }
 
+func (m metricsStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireNotificationMessages(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireNotificationMessages").Observe(time.Since(start).Seconds())
+	m.observeError("AcquireNotificationMessages", r1)
+	m.observeRows("AcquireNotificationMessages", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	provisionerJob, err := m.s.AcquireProvisionerJob(ctx, arg)
 	m.queryLatencies.WithLabelValues("AcquireProvisionerJob").Observe(time.Since(start).Seconds())
+	m.observeError("AcquireProvisionerJob", err)
 	return provisionerJob, err
 }
 
+func (m metricsStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.AcquireWebhookDeliveries(ctx, arg)
+	m.queryLatencies.WithLabelValues("AcquireWebhookDeliveries").Observe(time.Since(start).Seconds())
+	m.observeError("AcquireWebhookDeliveries", r1)
+	m.observeRows("AcquireWebhookDeliveries", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
 	start := time.Now()
 	r0 := m.s.ActivityBumpWorkspace(ctx, arg)
 	m.queryLatencies.WithLabelValues("ActivityBumpWorkspace").Observe(time.Since(start).Seconds())
+	m.observeError("ActivityBumpWorkspace", r0)
 	return r0
 }
 
@@ -104,6 +211,8 @@ func (m metricsStore) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
 	start := time.Now()
 	r0, r1 := m.s.AllUserIDs(ctx)
 	m.queryLatencies.WithLabelValues("AllUserIDs").Observe(time.Since(start).Seconds())
+	m.observeError("AllUserIDs", r1)
+	m.observeRows("AllUserIDs", len(r0))
 	return r0, r1
 }
 
@@ -111,6 +220,8 @@ func (m metricsStore) ArchiveUnusedTemplateVersions(ctx context.Context, arg dat
 	start := time.Now()
 	r0, r1 := m.s.ArchiveUnusedTemplateVersions(ctx, arg)
 	m.queryLatencies.WithLabelValues("ArchiveUnusedTemplateVersions").Observe(time.Since(start).Seconds())
+	m.observeError("ArchiveUnusedTemplateVersions", r1)
+	m.observeRows("ArchiveUnusedTemplateVersions", len(r0))
 	return r0, r1
 }
 
@@ -118,6 +229,7 @@ func (m metricsStore) BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg da
 	start := time.Now()
 	r0 := m.s.BatchUpdateWorkspaceLastUsedAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("BatchUpdateWorkspaceLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observeError("BatchUpdateWorkspaceLastUsedAt", r0)
 	return r0
 }
 
@@ -125,6 +237,7 @@ func (m metricsStore) CleanTailnetCoordinators(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.CleanTailnetCoordinators(ctx)
 	m.queryLatencies.WithLabelValues("CleanTailnetCoordinators").Observe(time.Since(start).Seconds())
+	m.observeError("CleanTailnetCoordinators", err)
 	return err
 }
 
@@ -132,6 +245,7 @@ func (m metricsStore) CleanTailnetLostPeers(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.CleanTailnetLostPeers(ctx)
 	m.queryLatencies.WithLabelValues("CleanTailnetLostPeers").Observe(time.Since(start).Seconds())
+	m.observeError("CleanTailnetLostPeers", r0)
 	return r0
 }
 
@@ -139,27 +253,81 @@ func (m metricsStore) CleanTailnetTunnels(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.CleanTailnetTunnels(ctx)
 	m.queryLatencies.WithLabelValues("CleanTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observeError("CleanTailnetTunnels", r0)
 	return r0
 }
 
+func (m metricsStore) CountLoginFailuresByUserID(ctx context.Context, arg database.CountLoginFailuresByUserIDParams) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountLoginFailuresByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("CountLoginFailuresByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("CountLoginFailuresByUserID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountUnreadInboxNotificationsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("CountUnreadInboxNotificationsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("CountUnreadInboxNotificationsByUserID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.CountUnusedUserRecoveryCodes(ctx, userID)
+	m.queryLatencies.WithLabelValues("CountUnusedUserRecoveryCodes").Observe(time.Since(start).Seconds())
+	m.observeError("CountUnusedUserRecoveryCodes", r1)
+	return r0, r1
+}
+
+func (m metricsStore) CustomRolesByName(ctx context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.CustomRolesByName(ctx, lookupRoles)
+	m.queryLatencies.WithLabelValues("CustomRolesByName").Observe(time.Since(start).Seconds())
+	m.observeError("CustomRolesByName", r1)
+	m.observeRows("CustomRolesByName", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	start := time.Now()
 	err := m.s.DeleteAPIKeyByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAPIKeyByID", err)
 	return err
 }
 
+func (m metricsStore) DeleteAPIKeyByIDReturning(ctx context.Context, id string) (database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteAPIKeyByIDReturning(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteAPIKeyByIDReturning").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAPIKeyByIDReturning", r1)
+	return r0, r1
+}
+
 func (m metricsStore) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteAPIKeysByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("DeleteAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAPIKeysByUserID", err)
 	return err
 }
 
+func (m metricsStore) DeleteAPIKeysByUserIDReturning(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteAPIKeysByUserIDReturning(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteAPIKeysByUserIDReturning").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAPIKeysByUserIDReturning", r1)
+	m.observeRows("DeleteAPIKeysByUserIDReturning", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteAllTailnetClientSubscriptions(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteAllTailnetClientSubscriptions").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAllTailnetClientSubscriptions", r0)
 	return r0
 }
 
@@ -167,6 +335,7 @@ func (m metricsStore) DeleteAllTailnetTunnels(ctx context.Context, arg database.
 	start := time.Now()
 	r0 := m.s.DeleteAllTailnetTunnels(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteAllTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteAllTailnetTunnels", r0)
 	return r0
 }
 
@@ -174,19 +343,31 @@ func (m metricsStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.Contex
 	start := time.Now()
 	err := m.s.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("DeleteApplicationConnectAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteApplicationConnectAPIKeysByUserID", err)
 	return err
 }
 
 func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("DeleteCoordinator").Observe(time.Since(start).Seconds())
-	return m.s.DeleteCoordinator(ctx, id)
+	r0 := m.s.DeleteCoordinator(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteCoordinator").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteCoordinator", r0)
+	return r0
+}
+
+func (m metricsStore) DeleteExpiredTwoFactorChallenges(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteExpiredTwoFactorChallenges(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteExpiredTwoFactorChallenges").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteExpiredTwoFactorChallenges", err)
+	return err
 }
 
 func (m metricsStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteExternalAuthLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteExternalAuthLink", r0)
 	return r0
 }
 
@@ -194,6 +375,7 @@ func (m metricsStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) err
 	start := time.Now()
 	err := m.s.DeleteGitSSHKey(ctx, userID)
 	m.queryLatencies.WithLabelValues("DeleteGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteGitSSHKey", err)
 	return err
 }
 
@@ -201,6 +383,7 @@ func (m metricsStore) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	err := m.s.DeleteGroupByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteGroupByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteGroupByID", err)
 	return err
 }
 
@@ -208,6 +391,7 @@ func (m metricsStore) DeleteGroupMemberFromGroup(ctx context.Context, arg databa
 	start := time.Now()
 	err := m.s.DeleteGroupMemberFromGroup(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteGroupMemberFromGroup").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteGroupMemberFromGroup", err)
 	return err
 }
 
@@ -215,6 +399,7 @@ func (m metricsStore) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg da
 	start := time.Now()
 	err := m.s.DeleteGroupMembersByOrgAndUser(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteGroupMembersByOrgAndUser").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteGroupMembersByOrgAndUser", err)
 	return err
 }
 
@@ -222,41 +407,159 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	start := time.Now()
 	licenseID, err := m.s.DeleteLicense(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteLicense").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteLicense", err)
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteLoginFailure(ctx context.Context, arg database.DeleteLoginFailureParams) error {
+	start := time.Now()
+	err := m.s.DeleteLoginFailure(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteLoginFailure").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteLoginFailure", err)
+	return err
+}
+
 func (m metricsStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOAuth2ProviderAppByID", r0)
 	return r0
 }
 
+func (m metricsStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOAuth2ProviderAppCodeByID", r1)
+	return r0, r1
+}
+
 func (m metricsStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	r0 := m.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
 	m.queryLatencies.WithLabelValues("DeleteOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOAuth2ProviderAppSecretByID", r0)
 	return r0
 }
 
+func (m metricsStore) DeleteOldGroupSyncRuns(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldGroupSyncRuns(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldGroupSyncRuns").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldGroupSyncRuns", err)
+	return err
+}
+
+func (m metricsStore) DeleteOldLoginFailures(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldLoginFailures(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldLoginFailures").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldLoginFailures", err)
+	return err
+}
+
 func (m metricsStore) DeleteOldProvisionerDaemons(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldProvisionerDaemons(ctx)
 	m.queryLatencies.WithLabelValues("DeleteOldProvisionerDaemons").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldProvisionerDaemons", r0)
 	return r0
 }
 
+func (m metricsStore) DeleteOldRateLimitCounters(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldRateLimitCounters(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldRateLimitCounters").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldRateLimitCounters", err)
+	return err
+}
+
+func (m metricsStore) DeleteOldUserActivity(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldUserActivity(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldUserActivity").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldUserActivity", err)
+	return err
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx)
 	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentLogs").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldWorkspaceAgentLogs", r0)
 	return r0
 }
 
+func (m metricsStore) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldWorkspaceAgentMetadataHistory(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldWorkspaceAgentMetadataHistory", err)
+	return err
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
 	start := time.Now()
 	err := m.s.DeleteOldWorkspaceAgentStats(ctx)
 	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldWorkspaceAgentStats", err)
+	return err
+}
+
+func (m metricsStore) DeleteOldWorkspaceEvents(ctx context.Context, beforeTime time.Time) error {
+	start := time.Now()
+	err := m.s.DeleteOldWorkspaceEvents(ctx, beforeTime)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceEvents").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOldWorkspaceEvents", err)
+	return err
+}
+
+func (m metricsStore) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteOrganization(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteOrganization").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOrganization", err)
+	return err
+}
+
+func (m metricsStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	start := time.Now()
+	err := m.s.DeleteOrganizationMember(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOrganizationMember").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOrganizationMember", err)
+	return err
+}
+
+func (m metricsStore) DeleteOrganizationTemplateVariableValue(ctx context.Context, arg database.DeleteOrganizationTemplateVariableValueParams) error {
+	start := time.Now()
+	err := m.s.DeleteOrganizationTemplateVariableValue(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteOrganizationTemplateVariableValue").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteOrganizationTemplateVariableValue", err)
+	return err
+}
+
+func (m metricsStore) DeletePasskeyByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeletePasskeyByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeletePasskeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeletePasskeyByID", err)
+	return err
+}
+
+func (m metricsStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("DeleteProvisionerJobLogsByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteProvisionerJobLogsByJobID", err)
+	return err
+}
+
+func (m metricsStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteProvisionerKey(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteProvisionerKey").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteProvisionerKey", err)
 	return err
 }
 
@@ -264,25 +567,39 @@ func (m metricsStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt
 	start := time.Now()
 	err := m.s.DeleteReplicasUpdatedBefore(ctx, updatedAt)
 	m.queryLatencies.WithLabelValues("DeleteReplicasUpdatedBefore").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteReplicasUpdatedBefore", err)
+	return err
+}
+
+func (m metricsStore) DeleteSessionRecordingByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteSessionRecordingByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteSessionRecordingByID", err)
 	return err
 }
 
 func (m metricsStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("DeleteTailnetAgent").Observe(time.Since(start).Seconds())
-	return m.s.DeleteTailnetAgent(ctx, arg)
+	r0, r1 := m.s.DeleteTailnetAgent(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTailnetAgent").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTailnetAgent", r1)
+	return r0, r1
 }
 
 func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.DeleteTailnetClientParams) (database.DeleteTailnetClientRow, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("DeleteTailnetClient").Observe(time.Since(start).Seconds())
-	return m.s.DeleteTailnetClient(ctx, arg)
+	r0, r1 := m.s.DeleteTailnetClient(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTailnetClient").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTailnetClient", r1)
+	return r0, r1
 }
 
 func (m metricsStore) DeleteTailnetClientSubscription(ctx context.Context, arg database.DeleteTailnetClientSubscriptionParams) error {
 	start := time.Now()
 	r0 := m.s.DeleteTailnetClientSubscription(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteTailnetClientSubscription").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTailnetClientSubscription", r0)
 	return r0
 }
 
@@ -290,6 +607,7 @@ func (m metricsStore) DeleteTailnetPeer(ctx context.Context, arg database.Delete
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetPeer(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteTailnetPeer").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTailnetPeer", r1)
 	return r0, r1
 }
 
@@ -297,13 +615,95 @@ func (m metricsStore) DeleteTailnetTunnel(ctx context.Context, arg database.Dele
 	start := time.Now()
 	r0, r1 := m.s.DeleteTailnetTunnel(ctx, arg)
 	m.queryLatencies.WithLabelValues("DeleteTailnetTunnel").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTailnetTunnel", r1)
+	return r0, r1
+}
+
+func (m metricsStore) DeleteTemplateVariableValue(ctx context.Context, arg database.DeleteTemplateVariableValueParams) error {
+	start := time.Now()
+	err := m.s.DeleteTemplateVariableValue(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateVariableValue").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTemplateVariableValue", err)
+	return err
+}
+
+func (m metricsStore) DeleteTemplateVersionPreset(ctx context.Context, arg database.DeleteTemplateVersionPresetParams) error {
+	start := time.Now()
+	err := m.s.DeleteTemplateVersionPreset(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteTemplateVersionPreset").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTemplateVersionPreset", err)
+	return err
+}
+
+func (m metricsStore) DeleteTwoFactorChallengeByID(ctx context.Context, id uuid.UUID) (database.TwoFactorChallenge, error) {
+	start := time.Now()
+	r0, r1 := m.s.DeleteTwoFactorChallengeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteTwoFactorChallengeByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTwoFactorChallengeByID", r1)
 	return r0, r1
 }
 
+func (m metricsStore) DeleteTwoFactorPolicyByRole(ctx context.Context, role string) error {
+	start := time.Now()
+	err := m.s.DeleteTwoFactorPolicyByRole(ctx, role)
+	m.queryLatencies.WithLabelValues("DeleteTwoFactorPolicyByRole").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteTwoFactorPolicyByRole", err)
+	return err
+}
+
+func (m metricsStore) DeleteUserLockout(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteUserLockout(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserLockout").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteUserLockout", err)
+	return err
+}
+
+func (m metricsStore) DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteUserRecoveryCodesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserRecoveryCodesByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteUserRecoveryCodesByUserID", err)
+	return err
+}
+
+func (m metricsStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWebhookByID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteWebhookByID", err)
+	return err
+}
+
+func (m metricsStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	start := time.Now()
+	err := m.s.DeleteWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteWorkspaceAgentPortShare", err)
+	return err
+}
+
+func (m metricsStore) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx context.Context, agentID uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceAgentPreviousAuthTokenByAgentID").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteWorkspaceAgentPreviousAuthTokenByAgentID", err)
+	return err
+}
+
+func (m metricsStore) DeleteWorkspaceFavorite(ctx context.Context, arg database.DeleteWorkspaceFavoriteParams) error {
+	start := time.Now()
+	err := m.s.DeleteWorkspaceFavorite(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceFavorite").Observe(time.Since(start).Seconds())
+	m.observeError("DeleteWorkspaceFavorite", err)
+	return err
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetAPIKeyByID", err)
 	return apiKey, err
 }
 
@@ -311,6 +711,7 @@ func (m metricsStore) GetAPIKeyByName(ctx context.Context, arg database.GetAPIKe
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByName(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetAPIKeyByName").Observe(time.Since(start).Seconds())
+	m.observeError("GetAPIKeyByName", err)
 	return apiKey, err
 }
 
@@ -318,6 +719,8 @@ func (m metricsStore) GetAPIKeysByLoginType(ctx context.Context, loginType datab
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByLoginType(ctx, loginType)
 	m.queryLatencies.WithLabelValues("GetAPIKeysByLoginType").Observe(time.Since(start).Seconds())
+	m.observeError("GetAPIKeysByLoginType", err)
+	m.observeRows("GetAPIKeysByLoginType", len(apiKeys))
 	return apiKeys, err
 }
 
@@ -325,6 +728,8 @@ func (m metricsStore) GetAPIKeysByUserID(ctx context.Context, arg database.GetAP
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysByUserID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetAPIKeysByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetAPIKeysByUserID", err)
+	m.observeRows("GetAPIKeysByUserID", len(apiKeys))
 	return apiKeys, err
 }
 
@@ -332,13 +737,25 @@ func (m metricsStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time
 	start := time.Now()
 	apiKeys, err := m.s.GetAPIKeysLastUsedAfter(ctx, lastUsed)
 	m.queryLatencies.WithLabelValues("GetAPIKeysLastUsedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetAPIKeysLastUsedAfter", err)
+	m.observeRows("GetAPIKeysLastUsedAfter", len(apiKeys))
 	return apiKeys, err
 }
 
+func (m metricsStore) GetActiveSessionsByUserID(ctx context.Context, arg database.GetActiveSessionsByUserIDParams) ([]database.APIKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetActiveSessionsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetActiveSessionsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetActiveSessionsByUserID", r1)
+	m.observeRows("GetActiveSessionsByUserID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetActiveUserCount(ctx context.Context) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetActiveUserCount(ctx)
 	m.queryLatencies.WithLabelValues("GetActiveUserCount").Observe(time.Since(start).Seconds())
+	m.observeError("GetActiveUserCount", err)
 	return count, err
 }
 
@@ -346,6 +763,8 @@ func (m metricsStore) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context,
 	start := time.Now()
 	r0, r1 := m.s.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
 	m.queryLatencies.WithLabelValues("GetActiveWorkspaceBuildsByTemplateID").Observe(time.Since(start).Seconds())
+	m.observeError("GetActiveWorkspaceBuildsByTemplateID", r1)
+	m.observeRows("GetActiveWorkspaceBuildsByTemplateID", len(r0))
 	return r0, r1
 }
 
@@ -353,6 +772,8 @@ func (m metricsStore) GetAllTailnetAgents(ctx context.Context) ([]database.Tailn
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetAgents(ctx)
 	m.queryLatencies.WithLabelValues("GetAllTailnetAgents").Observe(time.Since(start).Seconds())
+	m.observeError("GetAllTailnetAgents", r1)
+	m.observeRows("GetAllTailnetAgents", len(r0))
 	return r0, r1
 }
 
@@ -360,6 +781,8 @@ func (m metricsStore) GetAllTailnetCoordinators(ctx context.Context) ([]database
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetCoordinators(ctx)
 	m.queryLatencies.WithLabelValues("GetAllTailnetCoordinators").Observe(time.Since(start).Seconds())
+	m.observeError("GetAllTailnetCoordinators", r1)
+	m.observeRows("GetAllTailnetCoordinators", len(r0))
 	return r0, r1
 }
 
@@ -367,6 +790,8 @@ func (m metricsStore) GetAllTailnetPeers(ctx context.Context) ([]database.Tailne
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetPeers(ctx)
 	m.queryLatencies.WithLabelValues("GetAllTailnetPeers").Observe(time.Since(start).Seconds())
+	m.observeError("GetAllTailnetPeers", r1)
+	m.observeRows("GetAllTailnetPeers", len(r0))
 	return r0, r1
 }
 
@@ -374,6 +799,8 @@ func (m metricsStore) GetAllTailnetTunnels(ctx context.Context) ([]database.Tail
 	start := time.Now()
 	r0, r1 := m.s.GetAllTailnetTunnels(ctx)
 	m.queryLatencies.WithLabelValues("GetAllTailnetTunnels").Observe(time.Since(start).Seconds())
+	m.observeError("GetAllTailnetTunnels", r1)
+	m.observeRows("GetAllTailnetTunnels", len(r0))
 	return r0, r1
 }
 
@@ -381,6 +808,7 @@ func (m metricsStore) GetAppSecurityKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetAppSecurityKey(ctx)
 	m.queryLatencies.WithLabelValues("GetAppSecurityKey").Observe(time.Since(start).Seconds())
+	m.observeError("GetAppSecurityKey", err)
 	return key, err
 }
 
@@ -388,6 +816,16 @@ func (m metricsStore) GetApplicationName(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetApplicationName(ctx)
 	m.queryLatencies.WithLabelValues("GetApplicationName").Observe(time.Since(start).Seconds())
+	m.observeError("GetApplicationName", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetAuditLogsKeyset(ctx context.Context, arg database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetAuditLogsKeyset(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogsKeyset").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuditLogsKeyset", r1)
+	m.observeRows("GetAuditLogsKeyset", len(r0))
 	return r0, r1
 }
 
@@ -395,6 +833,8 @@ func (m metricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAu
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetAuditLogsOffset").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuditLogsOffset", err)
+	m.observeRows("GetAuditLogsOffset", len(rows))
 	return rows, err
 }
 
@@ -402,13 +842,25 @@ func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid
 	start := time.Now()
 	row, err := m.s.GetAuthorizationUserRoles(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetAuthorizationUserRoles").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuthorizationUserRoles", err)
 	return row, err
 }
 
+func (m metricsStore) GetConnectionLogsOffset(ctx context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetConnectionLogsOffset(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetConnectionLogsOffset").Observe(time.Since(start).Seconds())
+	m.observeError("GetConnectionLogsOffset", r1)
+	m.observeRows("GetConnectionLogsOffset", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetDBCryptKeys(ctx)
 	m.queryLatencies.WithLabelValues("GetDBCryptKeys").Observe(time.Since(start).Seconds())
+	m.observeError("GetDBCryptKeys", r1)
+	m.observeRows("GetDBCryptKeys", len(r0))
 	return r0, r1
 }
 
@@ -416,6 +868,7 @@ func (m metricsStore) GetDERPMeshKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	key, err := m.s.GetDERPMeshKey(ctx)
 	m.queryLatencies.WithLabelValues("GetDERPMeshKey").Observe(time.Since(start).Seconds())
+	m.observeError("GetDERPMeshKey", err)
 	return key, err
 }
 
@@ -423,6 +876,7 @@ func (m metricsStore) GetDefaultProxyConfig(ctx context.Context) (database.GetDe
 	start := time.Now()
 	resp, err := m.s.GetDefaultProxyConfig(ctx)
 	m.queryLatencies.WithLabelValues("GetDefaultProxyConfig").Observe(time.Since(start).Seconds())
+	m.observeError("GetDefaultProxyConfig", err)
 	return resp, err
 }
 
@@ -430,6 +884,8 @@ func (m metricsStore) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]
 	start := time.Now()
 	rows, err := m.s.GetDeploymentDAUs(ctx, tzOffset)
 	m.queryLatencies.WithLabelValues("GetDeploymentDAUs").Observe(time.Since(start).Seconds())
+	m.observeError("GetDeploymentDAUs", err)
+	m.observeRows("GetDeploymentDAUs", len(rows))
 	return rows, err
 }
 
@@ -437,6 +893,7 @@ func (m metricsStore) GetDeploymentID(ctx context.Context) (string, error) {
 	start := time.Now()
 	id, err := m.s.GetDeploymentID(ctx)
 	m.queryLatencies.WithLabelValues("GetDeploymentID").Observe(time.Since(start).Seconds())
+	m.observeError("GetDeploymentID", err)
 	return id, err
 }
 
@@ -444,6 +901,7 @@ func (m metricsStore) GetDeploymentWorkspaceAgentStats(ctx context.Context, crea
 	start := time.Now()
 	row, err := m.s.GetDeploymentWorkspaceAgentStats(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetDeploymentWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observeError("GetDeploymentWorkspaceAgentStats", err)
 	return row, err
 }
 
@@ -451,13 +909,32 @@ func (m metricsStore) GetDeploymentWorkspaceStats(ctx context.Context) (database
 	start := time.Now()
 	row, err := m.s.GetDeploymentWorkspaceStats(ctx)
 	m.queryLatencies.WithLabelValues("GetDeploymentWorkspaceStats").Observe(time.Since(start).Seconds())
+	m.observeError("GetDeploymentWorkspaceStats", err)
 	return row, err
 }
 
+func (m metricsStore) GetDriftedWorkspaceCount(ctx context.Context) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetDriftedWorkspaceCount(ctx)
+	m.queryLatencies.WithLabelValues("GetDriftedWorkspaceCount").Observe(time.Since(start).Seconds())
+	m.observeError("GetDriftedWorkspaceCount", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetExpiredSessionRecordings(ctx context.Context, arg database.GetExpiredSessionRecordingsParams) ([]database.SessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetExpiredSessionRecordings(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetExpiredSessionRecordings").Observe(time.Since(start).Seconds())
+	m.observeError("GetExpiredSessionRecordings", r1)
+	m.observeRows("GetExpiredSessionRecordings", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.GetExternalAuthLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observeError("GetExternalAuthLink", err)
 	return link, err
 }
 
@@ -465,6 +942,17 @@ func (m metricsStore) GetExternalAuthLinksByUserID(ctx context.Context, userID u
 	start := time.Now()
 	r0, r1 := m.s.GetExternalAuthLinksByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetExternalAuthLinksByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetExternalAuthLinksByUserID", r1)
+	m.observeRows("GetExternalAuthLinksByUserID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetFavoriteWorkspaceIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetFavoriteWorkspaceIDsByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetFavoriteWorkspaceIDsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetFavoriteWorkspaceIDsByUserID", r1)
+	m.observeRows("GetFavoriteWorkspaceIDsByUserID", len(r0))
 	return r0, r1
 }
 
@@ -472,6 +960,7 @@ func (m metricsStore) GetFileByHashAndCreator(ctx context.Context, arg database.
 	start := time.Now()
 	file, err := m.s.GetFileByHashAndCreator(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetFileByHashAndCreator").Observe(time.Since(start).Seconds())
+	m.observeError("GetFileByHashAndCreator", err)
 	return file, err
 }
 
@@ -479,6 +968,7 @@ func (m metricsStore) GetFileByID(ctx context.Context, id uuid.UUID) (database.F
 	start := time.Now()
 	file, err := m.s.GetFileByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetFileByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetFileByID", err)
 	return file, err
 }
 
@@ -486,6 +976,8 @@ func (m metricsStore) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([
 	start := time.Now()
 	rows, err := m.s.GetFileTemplates(ctx, fileID)
 	m.queryLatencies.WithLabelValues("GetFileTemplates").Observe(time.Since(start).Seconds())
+	m.observeError("GetFileTemplates", err)
+	m.observeRows("GetFileTemplates", len(rows))
 	return rows, err
 }
 
@@ -493,6 +985,7 @@ func (m metricsStore) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (datab
 	start := time.Now()
 	key, err := m.s.GetGitSSHKey(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observeError("GetGitSSHKey", err)
 	return key, err
 }
 
@@ -500,6 +993,7 @@ func (m metricsStore) GetGroupByID(ctx context.Context, id uuid.UUID) (database.
 	start := time.Now()
 	group, err := m.s.GetGroupByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetGroupByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupByID", err)
 	return group, err
 }
 
@@ -507,6 +1001,7 @@ func (m metricsStore) GetGroupByOrgAndName(ctx context.Context, arg database.Get
 	start := time.Now()
 	group, err := m.s.GetGroupByOrgAndName(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetGroupByOrgAndName").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupByOrgAndName", err)
 	return group, err
 }
 
@@ -514,13 +1009,35 @@ func (m metricsStore) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([
 	start := time.Now()
 	users, err := m.s.GetGroupMembers(ctx, groupID)
 	m.queryLatencies.WithLabelValues("GetGroupMembers").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupMembers", err)
+	m.observeRows("GetGroupMembers", len(users))
 	return users, err
 }
 
+func (m metricsStore) GetGroupSyncRuns(ctx context.Context, arg database.GetGroupSyncRunsParams) ([]database.GroupSyncRun, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupSyncRuns(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetGroupSyncRuns").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupSyncRuns", r1)
+	m.observeRows("GetGroupSyncRuns", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetGroupsByOrganizationAndUserID(ctx context.Context, arg database.GetGroupsByOrganizationAndUserIDParams) ([]database.Group, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupsByOrganizationAndUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetGroupsByOrganizationAndUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupsByOrganizationAndUserID", r1)
+	m.observeRows("GetGroupsByOrganizationAndUserID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	start := time.Now()
 	groups, err := m.s.GetGroupsByOrganizationID(ctx, organizationID)
 	m.queryLatencies.WithLabelValues("GetGroupsByOrganizationID").Observe(time.Since(start).Seconds())
+	m.observeError("GetGroupsByOrganizationID", err)
+	m.observeRows("GetGroupsByOrganizationID", len(groups))
 	return groups, err
 }
 
@@ -528,6 +1045,7 @@ func (m metricsStore) GetHealthSettings(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetHealthSettings(ctx)
 	m.queryLatencies.WithLabelValues("GetHealthSettings").Observe(time.Since(start).Seconds())
+	m.observeError("GetHealthSettings", r1)
 	return r0, r1
 }
 
@@ -535,20 +1053,50 @@ func (m metricsStore) GetHungProvisionerJobs(ctx context.Context, hungSince time
 	start := time.Now()
 	jobs, err := m.s.GetHungProvisionerJobs(ctx, hungSince)
 	m.queryLatencies.WithLabelValues("GetHungProvisionerJobs").Observe(time.Since(start).Seconds())
+	m.observeError("GetHungProvisionerJobs", err)
+	m.observeRows("GetHungProvisionerJobs", len(jobs))
 	return jobs, err
 }
 
+func (m metricsStore) GetInactiveUsers(ctx context.Context, inactiveSince time.Time) ([]database.GetInactiveUsersRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetInactiveUsers(ctx, inactiveSince)
+	m.queryLatencies.WithLabelValues("GetInactiveUsers").Observe(time.Since(start).Seconds())
+	m.observeError("GetInactiveUsers", r1)
+	m.observeRows("GetInactiveUsers", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetInboxNotificationsByUserID(ctx context.Context, arg database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetInboxNotificationsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetInboxNotificationsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetInboxNotificationsByUserID", r1)
+	m.observeRows("GetInboxNotificationsByUserID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	start := time.Now()
 	version, err := m.s.GetLastUpdateCheck(ctx)
 	m.queryLatencies.WithLabelValues("GetLastUpdateCheck").Observe(time.Since(start).Seconds())
+	m.observeError("GetLastUpdateCheck", err)
 	return version, err
 }
 
+func (m metricsStore) GetLatestWorkspaceAgentStatByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentStat, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceAgentStatByAgentID(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceAgentStatByAgentID").Observe(time.Since(start).Seconds())
+	m.observeError("GetLatestWorkspaceAgentStatByAgentID", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
 	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuildByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetLatestWorkspaceBuildByWorkspaceID", err)
 	return build, err
 }
 
@@ -556,6 +1104,8 @@ func (m metricsStore) GetLatestWorkspaceBuilds(ctx context.Context) ([]database.
 	start := time.Now()
 	builds, err := m.s.GetLatestWorkspaceBuilds(ctx)
 	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuilds").Observe(time.Since(start).Seconds())
+	m.observeError("GetLatestWorkspaceBuilds", err)
+	m.observeRows("GetLatestWorkspaceBuilds", len(builds))
 	return builds, err
 }
 
@@ -563,6 +1113,8 @@ func (m metricsStore) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context
 	start := time.Now()
 	builds, err := m.s.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetLatestWorkspaceBuildsByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetLatestWorkspaceBuildsByWorkspaceIDs", err)
+	m.observeRows("GetLatestWorkspaceBuildsByWorkspaceIDs", len(builds))
 	return builds, err
 }
 
@@ -570,27 +1122,82 @@ func (m metricsStore) GetLicenseByID(ctx context.Context, id int32) (database.Li
 	start := time.Now()
 	license, err := m.s.GetLicenseByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetLicenseByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetLicenseByID", err)
 	return license, err
 }
 
+func (m metricsStore) GetLicenseGracePeriodStartedAt(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLicenseGracePeriodStartedAt(ctx)
+	m.queryLatencies.WithLabelValues("GetLicenseGracePeriodStartedAt").Observe(time.Since(start).Seconds())
+	m.observeError("GetLicenseGracePeriodStartedAt", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetLicenses(ctx)
 	m.queryLatencies.WithLabelValues("GetLicenses").Observe(time.Since(start).Seconds())
+	m.observeError("GetLicenses", err)
+	m.observeRows("GetLicenses", len(licenses))
 	return licenses, err
 }
 
+func (m metricsStore) GetLoginFailure(ctx context.Context, arg database.GetLoginFailureParams) (database.LoginFailure, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLoginFailure(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetLoginFailure").Observe(time.Since(start).Seconds())
+	m.observeError("GetLoginFailure", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	start := time.Now()
 	url, err := m.s.GetLogoURL(ctx)
 	m.queryLatencies.WithLabelValues("GetLogoURL").Observe(time.Since(start).Seconds())
+	m.observeError("GetLogoURL", err)
 	return url, err
 }
 
+func (m metricsStore) GetNotificationMessagesByUserID(ctx context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationMessagesByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNotificationMessagesByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetNotificationMessagesByUserID", r1)
+	m.observeRows("GetNotificationMessagesByUserID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNotificationPreferencesByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetNotificationPreferencesByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetNotificationPreferencesByUserID", r1)
+	m.observeRows("GetNotificationPreferencesByUserID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppCodeByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppCodeByPrefix(ctx, secretPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppCodeByPrefix").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppCodeByPrefix", r1)
 	return r0, r1
 }
 
@@ -598,6 +1205,7 @@ func (m metricsStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uui
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppSecretByID", r1)
 	return r0, r1
 }
 
@@ -605,6 +1213,16 @@ func (m metricsStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, ap
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
 	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppSecretsByAppID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppSecretsByAppID", r1)
+	m.observeRows("GetOAuth2ProviderAppSecretsByAppID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
+	m.queryLatencies.WithLabelValues("GetOAuth2ProviderAppTokenByPrefix").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderAppTokenByPrefix", r1)
 	return r0, r1
 }
 
@@ -612,6 +1230,8 @@ func (m metricsStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAu
 	start := time.Now()
 	r0, r1 := m.s.GetOAuth2ProviderApps(ctx)
 	m.queryLatencies.WithLabelValues("GetOAuth2ProviderApps").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuth2ProviderApps", r1)
+	m.observeRows("GetOAuth2ProviderApps", len(r0))
 	return r0, r1
 }
 
@@ -619,6 +1239,7 @@ func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
 	m.queryLatencies.WithLabelValues("GetOAuthSigningKey").Observe(time.Since(start).Seconds())
+	m.observeError("GetOAuthSigningKey", r1)
 	return r0, r1
 }
 
@@ -626,6 +1247,7 @@ func (m metricsStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (da
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetOrganizationByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationByID", err)
 	return organization, err
 }
 
@@ -633,6 +1255,7 @@ func (m metricsStore) GetOrganizationByName(ctx context.Context, name string) (d
 	start := time.Now()
 	organization, err := m.s.GetOrganizationByName(ctx, name)
 	m.queryLatencies.WithLabelValues("GetOrganizationByName").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationByName", err)
 	return organization, err
 }
 
@@ -640,6 +1263,8 @@ func (m metricsStore) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []u
 	start := time.Now()
 	organizations, err := m.s.GetOrganizationIDsByMemberIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetOrganizationIDsByMemberIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationIDsByMemberIDs", err)
+	m.observeRows("GetOrganizationIDsByMemberIDs", len(organizations))
 	return organizations, err
 }
 
@@ -647,20 +1272,60 @@ func (m metricsStore) GetOrganizationMemberByUserID(ctx context.Context, arg dat
 	start := time.Now()
 	member, err := m.s.GetOrganizationMemberByUserID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetOrganizationMemberByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationMemberByUserID", err)
 	return member, err
 }
 
+func (m metricsStore) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationMembers(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationMembers").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationMembers", r1)
+	m.observeRows("GetOrganizationMembers", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
 	start := time.Now()
 	memberships, err := m.s.GetOrganizationMembershipsByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetOrganizationMembershipsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationMembershipsByUserID", err)
+	m.observeRows("GetOrganizationMembershipsByUserID", len(memberships))
 	return memberships, err
 }
 
+func (m metricsStore) GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (database.OrganizationQuota, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationQuota(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationQuota").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationQuota", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetOrganizationResourceCostRollup(ctx context.Context, arg database.GetOrganizationResourceCostRollupParams) ([]database.GetOrganizationResourceCostRollupRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationResourceCostRollup(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetOrganizationResourceCostRollup").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationResourceCostRollup", r1)
+	m.observeRows("GetOrganizationResourceCostRollup", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOrganizationTemplateVariableValues(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetOrganizationTemplateVariableValues").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationTemplateVariableValues", r1)
+	m.observeRows("GetOrganizationTemplateVariableValues", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetOrganizations(ctx context.Context) ([]database.Organization, error) {
 	start := time.Now()
 	organizations, err := m.s.GetOrganizations(ctx)
 	m.queryLatencies.WithLabelValues("GetOrganizations").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizations", err)
+	m.observeRows("GetOrganizations", len(organizations))
 	return organizations, err
 }
 
@@ -668,20 +1333,51 @@ func (m metricsStore) GetOrganizationsByUserID(ctx context.Context, userID uuid.
 	start := time.Now()
 	organizations, err := m.s.GetOrganizationsByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetOrganizationsByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrganizationsByUserID", err)
+	m.observeRows("GetOrganizationsByUserID", len(organizations))
 	return organizations, err
 }
 
+func (m metricsStore) GetOrphanedProvisionerJobs(ctx context.Context, staleSince time.Time) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	jobs, err := m.s.GetOrphanedProvisionerJobs(ctx, staleSince)
+	m.queryLatencies.WithLabelValues("GetOrphanedProvisionerJobs").Observe(time.Since(start).Seconds())
+	m.observeError("GetOrphanedProvisionerJobs", err)
+	m.observeRows("GetOrphanedProvisionerJobs", len(jobs))
+	return jobs, err
+}
+
 func (m metricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	start := time.Now()
 	schemas, err := m.s.GetParameterSchemasByJobID(ctx, jobID)
 	m.queryLatencies.WithLabelValues("GetParameterSchemasByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("GetParameterSchemasByJobID", err)
+	m.observeRows("GetParameterSchemasByJobID", len(schemas))
 	return schemas, err
 }
 
+func (m metricsStore) GetPasskeyByID(ctx context.Context, id uuid.UUID) (database.Passkey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPasskeyByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetPasskeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetPasskeyByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.Passkey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPasskeysByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetPasskeysByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetPasskeysByUserID", r1)
+	m.observeRows("GetPasskeysByUserID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetPreviousTemplateVersion(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetPreviousTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observeError("GetPreviousTemplateVersion", err)
 	return version, err
 }
 
@@ -689,20 +1385,59 @@ func (m metricsStore) GetProvisionerDaemons(ctx context.Context) ([]database.Pro
 	start := time.Now()
 	daemons, err := m.s.GetProvisionerDaemons(ctx)
 	m.queryLatencies.WithLabelValues("GetProvisionerDaemons").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerDaemons", err)
+	m.observeRows("GetProvisionerDaemons", len(daemons))
 	return daemons, err
 }
 
+func (m metricsStore) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerDaemonsByOrganization(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetProvisionerDaemonsByOrganization").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerDaemonsByOrganization", r1)
+	m.observeRows("GetProvisionerDaemonsByOrganization", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.GetProvisionerJobByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetProvisionerJobByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobByID", err)
 	return job, err
 }
 
+func (m metricsStore) GetProvisionerJobIDsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobIDsToArchiveLogs(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobIDsToArchiveLogs").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobIDsToArchiveLogs", r1)
+	m.observeRows("GetProvisionerJobIDsToArchiveLogs", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobLogArchiveByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobLogArchiveByJobID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetProvisionerJobLogArchiveStats(ctx context.Context) (database.GetProvisionerJobLogArchiveStatsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobLogArchiveStats(ctx)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobLogArchiveStats").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobLogArchiveStats", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	start := time.Now()
 	jobs, err := m.s.GetProvisionerJobsByIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetProvisionerJobsByIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobsByIDs", err)
+	m.observeRows("GetProvisionerJobsByIDs", len(jobs))
 	return jobs, err
 }
 
@@ -710,6 +1445,8 @@ func (m metricsStore) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Conte
 	start := time.Now()
 	r0, r1 := m.s.GetProvisionerJobsByIDsWithQueuePosition(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetProvisionerJobsByIDsWithQueuePosition").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobsByIDsWithQueuePosition", r1)
+	m.observeRows("GetProvisionerJobsByIDsWithQueuePosition", len(r0))
 	return r0, r1
 }
 
@@ -717,13 +1454,41 @@ func (m metricsStore) GetProvisionerJobsCreatedAfter(ctx context.Context, create
 	start := time.Now()
 	jobs, err := m.s.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetProvisionerJobsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerJobsCreatedAfter", err)
+	m.observeRows("GetProvisionerJobsCreatedAfter", len(jobs))
 	return jobs, err
 }
 
+func (m metricsStore) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerKeyByHashedSecret(ctx, hashedSecret)
+	m.queryLatencies.WithLabelValues("GetProvisionerKeyByHashedSecret").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerKeyByHashedSecret", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerKeyByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetProvisionerKeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerKeyByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetProvisionerKeyByName(ctx context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerKeyByName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetProvisionerKeyByName").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerKeyByName", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetProvisionerLogsAfterID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetProvisionerLogsAfterID").Observe(time.Since(start).Seconds())
+	m.observeError("GetProvisionerLogsAfterID", err)
+	m.observeRows("GetProvisionerLogsAfterID", len(logs))
 	return logs, err
 }
 
@@ -731,13 +1496,23 @@ func (m metricsStore) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.
 	start := time.Now()
 	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetQuotaAllowanceForUser").Observe(time.Since(start).Seconds())
+	m.observeError("GetQuotaAllowanceForUser", err)
 	return allowance, err
 }
 
+func (m metricsStore) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaConsumedForOrganization(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetQuotaConsumedForOrganization").Observe(time.Since(start).Seconds())
+	m.observeError("GetQuotaConsumedForOrganization", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
 	start := time.Now()
 	consumed, err := m.s.GetQuotaConsumedForUser(ctx, ownerID)
 	m.queryLatencies.WithLabelValues("GetQuotaConsumedForUser").Observe(time.Since(start).Seconds())
+	m.observeError("GetQuotaConsumedForUser", err)
 	return consumed, err
 }
 
@@ -745,6 +1520,7 @@ func (m metricsStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (databas
 	start := time.Now()
 	replica, err := m.s.GetReplicaByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetReplicaByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetReplicaByID", err)
 	return replica, err
 }
 
@@ -752,32 +1528,68 @@ func (m metricsStore) GetReplicasUpdatedAfter(ctx context.Context, updatedAt tim
 	start := time.Now()
 	replicas, err := m.s.GetReplicasUpdatedAfter(ctx, updatedAt)
 	m.queryLatencies.WithLabelValues("GetReplicasUpdatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetReplicasUpdatedAfter", err)
+	m.observeRows("GetReplicasUpdatedAfter", len(replicas))
 	return replicas, err
 }
 
+func (m metricsStore) GetRunningWorkspaceCounts(ctx context.Context, arg database.GetRunningWorkspaceCountsParams) (database.GetRunningWorkspaceCountsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetRunningWorkspaceCounts(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetRunningWorkspaceCounts").Observe(time.Since(start).Seconds())
+	m.observeError("GetRunningWorkspaceCounts", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetServiceBanner(ctx context.Context) (string, error) {
 	start := time.Now()
 	banner, err := m.s.GetServiceBanner(ctx)
 	m.queryLatencies.WithLabelValues("GetServiceBanner").Observe(time.Since(start).Seconds())
+	m.observeError("GetServiceBanner", err)
 	return banner, err
 }
 
+func (m metricsStore) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSessionRecordingByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetSessionRecordingByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetSessionRecordingByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetSessionRecordingsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetSessionRecordingsByWorkspaceID", r1)
+	m.observeRows("GetSessionRecordingsByWorkspaceID", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("GetTailnetAgents").Observe(time.Since(start).Seconds())
-	return m.s.GetTailnetAgents(ctx, id)
+	r0, r1 := m.s.GetTailnetAgents(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTailnetAgents").Observe(time.Since(start).Seconds())
+	m.observeError("GetTailnetAgents", r1)
+	m.observeRows("GetTailnetAgents", len(r0))
+	return r0, r1
 }
 
 func (m metricsStore) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]database.TailnetClient, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("GetTailnetClientsForAgent").Observe(time.Since(start).Seconds())
-	return m.s.GetTailnetClientsForAgent(ctx, agentID)
+	r0, r1 := m.s.GetTailnetClientsForAgent(ctx, agentID)
+	m.queryLatencies.WithLabelValues("GetTailnetClientsForAgent").Observe(time.Since(start).Seconds())
+	m.observeError("GetTailnetClientsForAgent", r1)
+	m.observeRows("GetTailnetClientsForAgent", len(r0))
+	return r0, r1
 }
 
 func (m metricsStore) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]database.TailnetPeer, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetPeers(ctx, id)
 	m.queryLatencies.WithLabelValues("GetTailnetPeers").Observe(time.Since(start).Seconds())
+	m.observeError("GetTailnetPeers", r1)
+	m.observeRows("GetTailnetPeers", len(r0))
 	return r0, r1
 }
 
@@ -785,6 +1597,8 @@ func (m metricsStore) GetTailnetTunnelPeerBindings(ctx context.Context, srcID uu
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetTunnelPeerBindings(ctx, srcID)
 	m.queryLatencies.WithLabelValues("GetTailnetTunnelPeerBindings").Observe(time.Since(start).Seconds())
+	m.observeError("GetTailnetTunnelPeerBindings", r1)
+	m.observeRows("GetTailnetTunnelPeerBindings", len(r0))
 	return r0, r1
 }
 
@@ -792,6 +1606,8 @@ func (m metricsStore) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UU
 	start := time.Now()
 	r0, r1 := m.s.GetTailnetTunnelPeerIDs(ctx, srcID)
 	m.queryLatencies.WithLabelValues("GetTailnetTunnelPeerIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetTailnetTunnelPeerIDs", r1)
+	m.observeRows("GetTailnetTunnelPeerIDs", len(r0))
 	return r0, r1
 }
 
@@ -799,6 +1615,8 @@ func (m metricsStore) GetTemplateAppInsights(ctx context.Context, arg database.G
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateAppInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateAppInsights", r1)
+	m.observeRows("GetTemplateAppInsights", len(r0))
 	return r0, r1
 }
 
@@ -806,6 +1624,8 @@ func (m metricsStore) GetTemplateAppInsightsByTemplate(ctx context.Context, arg
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsightsByTemplate(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateAppInsightsByTemplate").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateAppInsightsByTemplate", r1)
+	m.observeRows("GetTemplateAppInsightsByTemplate", len(r0))
 	return r0, r1
 }
 
@@ -813,13 +1633,23 @@ func (m metricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	start := time.Now()
 	buildTime, err := m.s.GetTemplateAverageBuildTime(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateAverageBuildTime").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateAverageBuildTime", err)
 	return buildTime, err
 }
 
+func (m metricsStore) GetTemplateBuildInsights(ctx context.Context, arg database.GetTemplateBuildInsightsParams) (database.GetTemplateBuildInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBuildInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateBuildInsights", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.GetTemplateByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetTemplateByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateByID", err)
 	return template, err
 }
 
@@ -827,6 +1657,7 @@ func (m metricsStore) GetTemplateByOrganizationAndName(ctx context.Context, arg
 	start := time.Now()
 	template, err := m.s.GetTemplateByOrganizationAndName(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateByOrganizationAndName").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateByOrganizationAndName", err)
 	return template, err
 }
 
@@ -834,6 +1665,8 @@ func (m metricsStore) GetTemplateDAUs(ctx context.Context, arg database.GetTempl
 	start := time.Now()
 	daus, err := m.s.GetTemplateDAUs(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateDAUs").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateDAUs", err)
+	m.observeRows("GetTemplateDAUs", len(daus))
 	return daus, err
 }
 
@@ -841,6 +1674,7 @@ func (m metricsStore) GetTemplateInsights(ctx context.Context, arg database.GetT
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsights(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateInsights", r1)
 	return r0, r1
 }
 
@@ -848,6 +1682,8 @@ func (m metricsStore) GetTemplateInsightsByInterval(ctx context.Context, arg dat
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsightsByInterval(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateInsightsByInterval").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateInsightsByInterval", r1)
+	m.observeRows("GetTemplateInsightsByInterval", len(r0))
 	return r0, r1
 }
 
@@ -855,6 +1691,8 @@ func (m metricsStore) GetTemplateInsightsByTemplate(ctx context.Context, arg dat
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateInsightsByTemplate(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateInsightsByTemplate").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateInsightsByTemplate", r1)
+	m.observeRows("GetTemplateInsightsByTemplate", len(r0))
 	return r0, r1
 }
 
@@ -862,6 +1700,17 @@ func (m metricsStore) GetTemplateParameterInsights(ctx context.Context, arg data
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateParameterInsights(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateParameterInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateParameterInsights", r1)
+	m.observeRows("GetTemplateParameterInsights", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVariableValue, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVariableValuesByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateVariableValuesByTemplateID").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVariableValuesByTemplateID", r1)
+	m.observeRows("GetTemplateVariableValuesByTemplateID", len(r0))
 	return r0, r1
 }
 
@@ -869,6 +1718,7 @@ func (m metricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID)
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionByID", err)
 	return version, err
 }
 
@@ -876,6 +1726,7 @@ func (m metricsStore) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByJobID(ctx, jobID)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionByJobID", err)
 	return version, err
 }
 
@@ -883,6 +1734,7 @@ func (m metricsStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context,
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByTemplateIDAndName(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionByTemplateIDAndName").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionByTemplateIDAndName", err)
 	return version, err
 }
 
@@ -890,13 +1742,26 @@ func (m metricsStore) GetTemplateVersionParameters(ctx context.Context, template
 	start := time.Now()
 	parameters, err := m.s.GetTemplateVersionParameters(ctx, templateVersionID)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionParameters").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionParameters", err)
+	m.observeRows("GetTemplateVersionParameters", len(parameters))
 	return parameters, err
 }
 
+func (m metricsStore) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPresets(ctx, templateVersionID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPresets").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionPresets", r1)
+	m.observeRows("GetTemplateVersionPresets", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variables, err := m.s.GetTemplateVersionVariables(ctx, templateVersionID)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionVariables").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionVariables", err)
+	m.observeRows("GetTemplateVersionVariables", len(variables))
 	return variables, err
 }
 
@@ -904,6 +1769,8 @@ func (m metricsStore) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.U
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsByIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionsByIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionsByIDs", err)
+	m.observeRows("GetTemplateVersionsByIDs", len(versions))
 	return versions, err
 }
 
@@ -911,6 +1778,8 @@ func (m metricsStore) GetTemplateVersionsByTemplateID(ctx context.Context, arg d
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsByTemplateID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionsByTemplateID").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionsByTemplateID", err)
+	m.observeRows("GetTemplateVersionsByTemplateID", len(versions))
 	return versions, err
 }
 
@@ -918,6 +1787,8 @@ func (m metricsStore) GetTemplateVersionsCreatedAfter(ctx context.Context, creat
 	start := time.Now()
 	versions, err := m.s.GetTemplateVersionsCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetTemplateVersionsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateVersionsCreatedAfter", err)
+	m.observeRows("GetTemplateVersionsCreatedAfter", len(versions))
 	return versions, err
 }
 
@@ -925,6 +1796,8 @@ func (m metricsStore) GetTemplates(ctx context.Context) ([]database.Template, er
 	start := time.Now()
 	templates, err := m.s.GetTemplates(ctx)
 	m.queryLatencies.WithLabelValues("GetTemplates").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplates", err)
+	m.observeRows("GetTemplates", len(templates))
 	return templates, err
 }
 
@@ -932,20 +1805,77 @@ func (m metricsStore) GetTemplatesWithFilter(ctx context.Context, arg database.G
 	start := time.Now()
 	templates, err := m.s.GetTemplatesWithFilter(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetTemplatesWithFilter").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplatesWithFilter", err)
+	m.observeRows("GetTemplatesWithFilter", len(templates))
 	return templates, err
 }
 
+func (m metricsStore) GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTimedOutProvisionerJobs(ctx, now)
+	m.queryLatencies.WithLabelValues("GetTimedOutProvisionerJobs").Observe(time.Since(start).Seconds())
+	m.observeError("GetTimedOutProvisionerJobs", r1)
+	m.observeRows("GetTimedOutProvisionerJobs", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetTwoFactorPolicies(ctx context.Context) ([]database.TwoFactorPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTwoFactorPolicies(ctx)
+	m.queryLatencies.WithLabelValues("GetTwoFactorPolicies").Observe(time.Since(start).Seconds())
+	m.observeError("GetTwoFactorPolicies", r1)
+	m.observeRows("GetTwoFactorPolicies", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetTwoFactorPolicyByRole(ctx context.Context, role string) (database.TwoFactorPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTwoFactorPolicyByRole(ctx, role)
+	m.queryLatencies.WithLabelValues("GetTwoFactorPolicyByRole").Observe(time.Since(start).Seconds())
+	m.observeError("GetTwoFactorPolicyByRole", r1)
+	return r0, r1
+}
+
 func (m metricsStore) GetUnexpiredLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetUnexpiredLicenses(ctx)
 	m.queryLatencies.WithLabelValues("GetUnexpiredLicenses").Observe(time.Since(start).Seconds())
+	m.observeError("GetUnexpiredLicenses", err)
+	m.observeRows("GetUnexpiredLicenses", len(licenses))
 	return licenses, err
 }
 
+func (m metricsStore) GetUnknownLoginFailure(ctx context.Context, arg database.GetUnknownLoginFailureParams) (database.UnknownLoginFailure, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnknownLoginFailure(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUnknownLoginFailure").Observe(time.Since(start).Seconds())
+	m.observeError("GetUnknownLoginFailure", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetUnknownLoginFailureTotals(ctx context.Context, arg database.GetUnknownLoginFailureTotalsParams) (database.GetUnknownLoginFailureTotalsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnknownLoginFailureTotals(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUnknownLoginFailureTotals").Observe(time.Since(start).Seconds())
+	m.observeError("GetUnknownLoginFailureTotals", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]database.UserRecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUnusedUserRecoveryCodes(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUnusedUserRecoveryCodes").Observe(time.Since(start).Seconds())
+	m.observeError("GetUnusedUserRecoveryCodes", r1)
+	m.observeRows("GetUnusedUserRecoveryCodes", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserActivityInsights(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUserActivityInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserActivityInsights", r1)
+	m.observeRows("GetUserActivityInsights", len(r0))
 	return r0, r1
 }
 
@@ -953,6 +1883,7 @@ func (m metricsStore) GetUserByEmailOrUsername(ctx context.Context, arg database
 	start := time.Now()
 	user, err := m.s.GetUserByEmailOrUsername(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUserByEmailOrUsername").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserByEmailOrUsername", err)
 	return user, err
 }
 
@@ -960,6 +1891,7 @@ func (m metricsStore) GetUserByID(ctx context.Context, id uuid.UUID) (database.U
 	start := time.Now()
 	user, err := m.s.GetUserByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetUserByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserByID", err)
 	return user, err
 }
 
@@ -967,6 +1899,7 @@ func (m metricsStore) GetUserCount(ctx context.Context) (int64, error) {
 	start := time.Now()
 	count, err := m.s.GetUserCount(ctx)
 	m.queryLatencies.WithLabelValues("GetUserCount").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserCount", err)
 	return count, err
 }
 
@@ -974,6 +1907,8 @@ func (m metricsStore) GetUserLatencyInsights(ctx context.Context, arg database.G
 	start := time.Now()
 	r0, r1 := m.s.GetUserLatencyInsights(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUserLatencyInsights").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLatencyInsights", r1)
+	m.observeRows("GetUserLatencyInsights", len(r0))
 	return r0, r1
 }
 
@@ -981,6 +1916,7 @@ func (m metricsStore) GetUserLinkByLinkedID(ctx context.Context, linkedID string
 	start := time.Now()
 	link, err := m.s.GetUserLinkByLinkedID(ctx, linkedID)
 	m.queryLatencies.WithLabelValues("GetUserLinkByLinkedID").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLinkByLinkedID", err)
 	return link, err
 }
 
@@ -988,6 +1924,7 @@ func (m metricsStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg data
 	start := time.Now()
 	link, err := m.s.GetUserLinkByUserIDLoginType(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUserLinkByUserIDLoginType").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLinkByUserIDLoginType", err)
 	return link, err
 }
 
@@ -995,6 +1932,33 @@ func (m metricsStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID
 	start := time.Now()
 	r0, r1 := m.s.GetUserLinksByUserID(ctx, userID)
 	m.queryLatencies.WithLabelValues("GetUserLinksByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLinksByUserID", r1)
+	m.observeRows("GetUserLinksByUserID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLockoutByUserID(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserLockoutByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLockoutByUserID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetUserLockouts(ctx context.Context, now time.Time) ([]database.GetUserLockoutsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserLockouts(ctx, now)
+	m.queryLatencies.WithLabelValues("GetUserLockouts").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserLockouts", r1)
+	m.observeRows("GetUserLockouts", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetUserSCIMExternalIDByExternalID(ctx context.Context, externalID string) (database.UserSCIMExternalID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserSCIMExternalIDByExternalID(ctx, externalID)
+	m.queryLatencies.WithLabelValues("GetUserSCIMExternalIDByExternalID").Observe(time.Since(start).Seconds())
+	m.observeError("GetUserSCIMExternalIDByExternalID", r1)
 	return r0, r1
 }
 
@@ -1002,6 +1966,8 @@ func (m metricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams)
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetUsers").Observe(time.Since(start).Seconds())
+	m.observeError("GetUsers", err)
+	m.observeRows("GetUsers", len(users))
 	return users, err
 }
 
@@ -1009,13 +1975,59 @@ func (m metricsStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]dat
 	start := time.Now()
 	users, err := m.s.GetUsersByIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetUsersByIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetUsersByIDs", err)
+	m.observeRows("GetUsersByIDs", len(users))
 	return users, err
 }
 
+func (m metricsStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWebhookByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveriesByWebhookID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWebhookDeliveriesByWebhookID", r1)
+	m.observeRows("GetWebhookDeliveriesByWebhookID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhookDeliveryByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWebhookDeliveryByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooks(ctx)
+	m.queryLatencies.WithLabelValues("GetWebhooks").Observe(time.Since(start).Seconds())
+	m.observeError("GetWebhooks", r1)
+	m.observeRows("GetWebhooks", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWebhooksByEvent(ctx context.Context, event string) ([]database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWebhooksByEvent(ctx, event)
+	m.queryLatencies.WithLabelValues("GetWebhooksByEvent").Observe(time.Since(start).Seconds())
+	m.observeError("GetWebhooksByEvent", r1)
+	m.observeRows("GetWebhooksByEvent", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentAndOwnerByAuthToken(ctx, authToken)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentAndOwnerByAuthToken").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentAndOwnerByAuthToken", r1)
 	return r0, r1
 }
 
@@ -1023,6 +2035,7 @@ func (m metricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentByID", err)
 	return agent, err
 }
 
@@ -1030,13 +2043,33 @@ func (m metricsStore) GetWorkspaceAgentByInstanceID(ctx context.Context, authIns
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByInstanceID(ctx, authInstanceID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentByInstanceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentByInstanceID", err)
 	return agent, err
 }
 
+func (m metricsStore) GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentCountsByLifecycleState(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentCountsByLifecycleState").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentCountsByLifecycleState", r1)
+	m.observeRows("GetWorkspaceAgentCountsByLifecycleState", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentGPUsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentGPU, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentGPUsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentGPUsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentGPUsByAgentIDs", r1)
+	m.observeRows("GetWorkspaceAgentGPUsByAgentIDs", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLifecycleStateByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLifecycleStateByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentLifecycleStateByID", r1)
 	return r0, r1
 }
 
@@ -1044,6 +2077,8 @@ func (m metricsStore) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context,
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogSourcesByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentLogSourcesByAgentIDs", r1)
+	m.observeRows("GetWorkspaceAgentLogSourcesByAgentIDs", len(r0))
 	return r0, r1
 }
 
@@ -1051,6 +2086,8 @@ func (m metricsStore) GetWorkspaceAgentLogsAfter(ctx context.Context, arg databa
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLogsAfter(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentLogsAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentLogsAfter", r1)
+	m.observeRows("GetWorkspaceAgentLogsAfter", len(r0))
 	return r0, r1
 }
 
@@ -1058,13 +2095,61 @@ func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAg
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentMetadata", err)
+	m.observeRows("GetWorkspaceAgentMetadata", len(metadata))
 	return metadata, err
 }
 
+func (m metricsStore) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentMetadataHistory", r1)
+	m.observeRows("GetWorkspaceAgentMetadataHistory", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentPortShare", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPortSharesByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortSharesByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentPortSharesByWorkspaceID", r1)
+	m.observeRows("GetWorkspaceAgentPortSharesByWorkspaceID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentPortsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentPort, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentPortsByAgentIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentPortsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentPortsByAgentIDs", r1)
+	m.observeRows("GetWorkspaceAgentPortsByAgentIDs", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentResourceUsageAndLabels(ctx, createdAt)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentResourceUsageAndLabels").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentResourceUsageAndLabels", r1)
+	m.observeRows("GetWorkspaceAgentResourceUsageAndLabels", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentScriptsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentScriptsByAgentIDs", r1)
+	m.observeRows("GetWorkspaceAgentScriptsByAgentIDs", len(r0))
 	return r0, r1
 }
 
@@ -1072,6 +2157,8 @@ func (m metricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStats(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentStats", err)
+	m.observeRows("GetWorkspaceAgentStats", len(stats))
 	return stats, err
 }
 
@@ -1079,6 +2166,8 @@ func (m metricsStore) GetWorkspaceAgentStatsAndLabels(ctx context.Context, creat
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStatsAndLabels(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentStatsAndLabels").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentStatsAndLabels", err)
+	m.observeRows("GetWorkspaceAgentStatsAndLabels", len(stats))
 	return stats, err
 }
 
@@ -1086,6 +2175,8 @@ func (m metricsStore) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids [
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsByResourceIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsByResourceIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentsByResourceIDs", err)
+	m.observeRows("GetWorkspaceAgentsByResourceIDs", len(agents))
 	return agents, err
 }
 
@@ -1093,6 +2184,8 @@ func (m metricsStore) GetWorkspaceAgentsCreatedAfter(ctx context.Context, create
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentsCreatedAfter", err)
+	m.observeRows("GetWorkspaceAgentsCreatedAfter", len(agents))
 	return agents, err
 }
 
@@ -1100,6 +2193,8 @@ func (m metricsStore) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.C
 	start := time.Now()
 	agents, err := m.s.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspaceID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsInLatestBuildByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAgentsInLatestBuildByWorkspaceID", err)
+	m.observeRows("GetWorkspaceAgentsInLatestBuildByWorkspaceID", len(agents))
 	return agents, err
 }
 
@@ -1107,13 +2202,25 @@ func (m metricsStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg d
 	start := time.Now()
 	app, err := m.s.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAppByAgentIDAndSlug").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAppByAgentIDAndSlug", err)
 	return app, err
 }
 
+func (m metricsStore) GetWorkspaceAppCountsByHealth(ctx context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAppCountsByHealth(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAppCountsByHealth").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAppCountsByHealth", r1)
+	m.observeRows("GetWorkspaceAppCountsByHealth", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsByAgentID(ctx, agentID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAppsByAgentID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAppsByAgentID", err)
+	m.observeRows("GetWorkspaceAppsByAgentID", len(apps))
 	return apps, err
 }
 
@@ -1121,6 +2228,8 @@ func (m metricsStore) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsByAgentIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAppsByAgentIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAppsByAgentIDs", err)
+	m.observeRows("GetWorkspaceAppsByAgentIDs", len(apps))
 	return apps, err
 }
 
@@ -1128,6 +2237,8 @@ func (m metricsStore) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdA
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceAppsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceAppsCreatedAfter", err)
+	m.observeRows("GetWorkspaceAppsCreatedAfter", len(apps))
 	return apps, err
 }
 
@@ -1135,6 +2246,7 @@ func (m metricsStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildByID", err)
 	return build, err
 }
 
@@ -1142,6 +2254,7 @@ func (m metricsStore) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.U
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByJobID(ctx, jobID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildByJobID", err)
 	return build, err
 }
 
@@ -1149,6 +2262,7 @@ func (m metricsStore) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.C
 	start := time.Now()
 	build, err := m.s.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildByWorkspaceIDAndBuildNumber").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildByWorkspaceIDAndBuildNumber", err)
 	return build, err
 }
 
@@ -1156,6 +2270,8 @@ func (m metricsStore) GetWorkspaceBuildParameters(ctx context.Context, workspace
 	start := time.Now()
 	params, err := m.s.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildParameters", err)
+	m.observeRows("GetWorkspaceBuildParameters", len(params))
 	return params, err
 }
 
@@ -1163,6 +2279,8 @@ func (m metricsStore) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg d
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildsByWorkspaceID", err)
+	m.observeRows("GetWorkspaceBuildsByWorkspaceID", len(builds))
 	return builds, err
 }
 
@@ -1170,6 +2288,8 @@ func (m metricsStore) GetWorkspaceBuildsCreatedAfter(ctx context.Context, create
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceBuildsCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceBuildsCreatedAfter", err)
+	m.observeRows("GetWorkspaceBuildsCreatedAfter", len(builds))
 	return builds, err
 }
 
@@ -1177,6 +2297,7 @@ func (m metricsStore) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UU
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByAgentID(ctx, agentID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceByAgentID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceByAgentID", err)
 	return workspace, err
 }
 
@@ -1184,6 +2305,7 @@ func (m metricsStore) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (datab
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceByID", err)
 	return workspace, err
 }
 
@@ -1191,6 +2313,7 @@ func (m metricsStore) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg data
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByOwnerIDAndName(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceByOwnerIDAndName").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceByOwnerIDAndName", err)
 	return workspace, err
 }
 
@@ -1198,13 +2321,51 @@ func (m metricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspac
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByWorkspaceAppID(ctx, workspaceAppID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceByWorkspaceAppID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceByWorkspaceAppID", err)
 	return workspace, err
 }
 
+func (m metricsStore) GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceCountsByTemplateAndStatus(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceCountsByTemplateAndStatus").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceCountsByTemplateAndStatus", r1)
+	m.observeRows("GetWorkspaceCountsByTemplateAndStatus", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceDriftByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDrift, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceDriftByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceDriftByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceDriftByWorkspaceID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceIDsForDriftCheck(ctx, lastCheckBefore)
+	m.queryLatencies.WithLabelValues("GetWorkspaceIDsForDriftCheck").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceIDsForDriftCheck", r1)
+	m.observeRows("GetWorkspaceIDsForDriftCheck", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceParameterChanges(ctx context.Context, arg database.GetWorkspaceParameterChangesParams) ([]database.GetWorkspaceParameterChangesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceParameterChanges(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceParameterChanges").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceParameterChanges", r1)
+	m.observeRows("GetWorkspaceParameterChanges", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
 	m.queryLatencies.WithLabelValues("GetWorkspaceProxies").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceProxies", err)
+	m.observeRows("GetWorkspaceProxies", len(proxies))
 	return proxies, err
 }
 
@@ -1212,6 +2373,7 @@ func (m metricsStore) GetWorkspaceProxyByHostname(ctx context.Context, arg datab
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByHostname(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByHostname").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceProxyByHostname", err)
 	return proxy, err
 }
 
@@ -1219,6 +2381,7 @@ func (m metricsStore) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceProxyByID", err)
 	return proxy, err
 }
 
@@ -1226,20 +2389,42 @@ func (m metricsStore) GetWorkspaceProxyByName(ctx context.Context, name string)
 	start := time.Now()
 	proxy, err := m.s.GetWorkspaceProxyByName(ctx, name)
 	m.queryLatencies.WithLabelValues("GetWorkspaceProxyByName").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceProxyByName", err)
 	return proxy, err
 }
 
+func (m metricsStore) GetWorkspaceProxyHealth(ctx context.Context) ([]database.WorkspaceProxyHealth, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceProxyHealth(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceProxyHealth").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceProxyHealth", r1)
+	m.observeRows("GetWorkspaceProxyHealth", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
 	start := time.Now()
 	resource, err := m.s.GetWorkspaceResourceByID(ctx, id)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourceByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourceByID", err)
 	return resource, err
 }
 
+func (m metricsStore) GetWorkspaceResourceCosts(ctx context.Context, arg database.GetWorkspaceResourceCostsParams) ([]database.WorkspaceResourceCost, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceResourceCosts(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceResourceCosts").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourceCosts", r1)
+	m.observeRows("GetWorkspaceResourceCosts", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceResourceMetadataByResourceIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourceMetadataByResourceIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourceMetadataByResourceIDs", err)
+	m.observeRows("GetWorkspaceResourceMetadataByResourceIDs", len(metadata))
 	return metadata, err
 }
 
@@ -1247,6 +2432,8 @@ func (m metricsStore) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Conte
 	start := time.Now()
 	metadata, err := m.s.GetWorkspaceResourceMetadataCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourceMetadataCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourceMetadataCreatedAfter", err)
+	m.observeRows("GetWorkspaceResourceMetadataCreatedAfter", len(metadata))
 	return metadata, err
 }
 
@@ -1254,6 +2441,8 @@ func (m metricsStore) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uu
 	start := time.Now()
 	resources, err := m.s.GetWorkspaceResourcesByJobID(ctx, jobID)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourcesByJobID", err)
+	m.observeRows("GetWorkspaceResourcesByJobID", len(resources))
 	return resources, err
 }
 
@@ -1261,6 +2450,8 @@ func (m metricsStore) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []u
 	start := time.Now()
 	resources, err := m.s.GetWorkspaceResourcesByJobIDs(ctx, ids)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesByJobIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourcesByJobIDs", err)
+	m.observeRows("GetWorkspaceResourcesByJobIDs", len(resources))
 	return resources, err
 }
 
@@ -1268,13 +2459,43 @@ func (m metricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, cre
 	start := time.Now()
 	resources, err := m.s.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 	m.queryLatencies.WithLabelValues("GetWorkspaceResourcesCreatedAfter").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceResourcesCreatedAfter", err)
+	m.observeRows("GetWorkspaceResourcesCreatedAfter", len(resources))
 	return resources, err
 }
 
+func (m metricsStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotByID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceSnapshotByID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceSnapshotsByWorkspaceID").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceSnapshotsByWorkspaceID", r1)
+	m.observeRows("GetWorkspaceSnapshotsByWorkspaceID", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceTimeline(ctx context.Context, arg database.GetWorkspaceTimelineParams) ([]database.GetWorkspaceTimelineRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceTimeline(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceTimeline").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceTimeline", r1)
+	m.observeRows("GetWorkspaceTimeline", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
 	m.queryLatencies.WithLabelValues("GetWorkspaceUniqueOwnerCountByTemplateIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaceUniqueOwnerCountByTemplateIDs", r1)
+	m.observeRows("GetWorkspaceUniqueOwnerCountByTemplateIDs", len(r0))
 	return r0, r1
 }
 
@@ -1282,20 +2503,42 @@ func (m metricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspa
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspaces(ctx, arg)
 	m.queryLatencies.WithLabelValues("GetWorkspaces").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspaces", err)
+	m.observeRows("GetWorkspaces", len(workspaces))
 	return workspaces, err
 }
 
+func (m metricsStore) GetWorkspacesByIDs(ctx context.Context, ids []uuid.UUID) ([]database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacesByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspacesByIDs").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspacesByIDs", r1)
+	m.observeRows("GetWorkspacesByIDs", len(r0))
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspacesEligibleForTransition(ctx, now)
-	m.queryLatencies.WithLabelValues("GetWorkspacesEligibleForAutoStartStop").Observe(time.Since(start).Seconds())
+	m.queryLatencies.WithLabelValues("GetWorkspacesEligibleForTransition").Observe(time.Since(start).Seconds())
+	m.observeError("GetWorkspacesEligibleForTransition", err)
+	m.observeRows("GetWorkspacesEligibleForTransition", len(workspaces))
 	return workspaces, err
 }
 
+func (m metricsStore) IncrementRateLimitCounter(ctx context.Context, arg database.IncrementRateLimitCounterParams) (int32, error) {
+	start := time.Now()
+	r0, r1 := m.s.IncrementRateLimitCounter(ctx, arg)
+	m.queryLatencies.WithLabelValues("IncrementRateLimitCounter").Observe(time.Since(start).Seconds())
+	m.observeError("IncrementRateLimitCounter", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
 	start := time.Now()
 	key, err := m.s.InsertAPIKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertAPIKey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertAPIKey", err)
 	return key, err
 }
 
@@ -1303,6 +2546,7 @@ func (m metricsStore) InsertAllUsersGroup(ctx context.Context, organizationID uu
 	start := time.Now()
 	group, err := m.s.InsertAllUsersGroup(ctx, organizationID)
 	m.queryLatencies.WithLabelValues("InsertAllUsersGroup").Observe(time.Since(start).Seconds())
+	m.observeError("InsertAllUsersGroup", err)
 	return group, err
 }
 
@@ -1310,13 +2554,23 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	start := time.Now()
 	log, err := m.s.InsertAuditLog(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertAuditLog").Observe(time.Since(start).Seconds())
+	m.observeError("InsertAuditLog", err)
 	return log, err
 }
 
+func (m metricsStore) InsertConnectionLog(ctx context.Context, arg database.InsertConnectionLogParams) (database.ConnectionLog, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertConnectionLog(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertConnectionLog").Observe(time.Since(start).Seconds())
+	m.observeError("InsertConnectionLog", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
 	start := time.Now()
 	r0 := m.s.InsertDBCryptKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertDBCryptKey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertDBCryptKey", r0)
 	return r0
 }
 
@@ -1324,6 +2578,7 @@ func (m metricsStore) InsertDERPMeshKey(ctx context.Context, value string) error
 	start := time.Now()
 	err := m.s.InsertDERPMeshKey(ctx, value)
 	m.queryLatencies.WithLabelValues("InsertDERPMeshKey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertDERPMeshKey", err)
 	return err
 }
 
@@ -1331,6 +2586,7 @@ func (m metricsStore) InsertDeploymentID(ctx context.Context, value string) erro
 	start := time.Now()
 	err := m.s.InsertDeploymentID(ctx, value)
 	m.queryLatencies.WithLabelValues("InsertDeploymentID").Observe(time.Since(start).Seconds())
+	m.observeError("InsertDeploymentID", err)
 	return err
 }
 
@@ -1338,6 +2594,7 @@ func (m metricsStore) InsertExternalAuthLink(ctx context.Context, arg database.I
 	start := time.Now()
 	link, err := m.s.InsertExternalAuthLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observeError("InsertExternalAuthLink", err)
 	return link, err
 }
 
@@ -1345,6 +2602,7 @@ func (m metricsStore) InsertFile(ctx context.Context, arg database.InsertFilePar
 	start := time.Now()
 	file, err := m.s.InsertFile(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertFile").Observe(time.Since(start).Seconds())
+	m.observeError("InsertFile", err)
 	return file, err
 }
 
@@ -1352,6 +2610,7 @@ func (m metricsStore) InsertGitSSHKey(ctx context.Context, arg database.InsertGi
 	start := time.Now()
 	key, err := m.s.InsertGitSSHKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertGitSSHKey", err)
 	return key, err
 }
 
@@ -1359,6 +2618,7 @@ func (m metricsStore) InsertGroup(ctx context.Context, arg database.InsertGroupP
 	start := time.Now()
 	group, err := m.s.InsertGroup(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertGroup").Observe(time.Since(start).Seconds())
+	m.observeError("InsertGroup", err)
 	return group, err
 }
 
@@ -1366,13 +2626,31 @@ func (m metricsStore) InsertGroupMember(ctx context.Context, arg database.Insert
 	start := time.Now()
 	err := m.s.InsertGroupMember(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertGroupMember").Observe(time.Since(start).Seconds())
+	m.observeError("InsertGroupMember", err)
 	return err
 }
 
+func (m metricsStore) InsertGroupSyncRun(ctx context.Context, arg database.InsertGroupSyncRunParams) (database.GroupSyncRun, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertGroupSyncRun(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertGroupSyncRun").Observe(time.Since(start).Seconds())
+	m.observeError("InsertGroupSyncRun", r1)
+	return r0, r1
+}
+
+func (m metricsStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertInboxNotification(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertInboxNotification").Observe(time.Since(start).Seconds())
+	m.observeError("InsertInboxNotification", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertLicense(ctx context.Context, arg database.InsertLicenseParams) (database.License, error) {
 	start := time.Now()
 	license, err := m.s.InsertLicense(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertLicense").Observe(time.Since(start).Seconds())
+	m.observeError("InsertLicense", err)
 	return license, err
 }
 
@@ -1380,6 +2658,16 @@ func (m metricsStore) InsertMissingGroups(ctx context.Context, arg database.Inse
 	start := time.Now()
 	r0, r1 := m.s.InsertMissingGroups(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertMissingGroups").Observe(time.Since(start).Seconds())
+	m.observeError("InsertMissingGroups", r1)
+	m.observeRows("InsertMissingGroups", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertNotificationMessage(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertNotificationMessage").Observe(time.Since(start).Seconds())
+	m.observeError("InsertNotificationMessage", r1)
 	return r0, r1
 }
 
@@ -1387,6 +2675,15 @@ func (m metricsStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderApp(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderApp").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOAuth2ProviderApp", r1)
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppCode").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOAuth2ProviderAppCode", r1)
 	return r0, r1
 }
 
@@ -1394,6 +2691,15 @@ func (m metricsStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg dat
 	start := time.Now()
 	r0, r1 := m.s.InsertOAuth2ProviderAppSecret(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppSecret").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOAuth2ProviderAppSecret", r1)
+	return r0, r1
+}
+
+func (m metricsStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertOAuth2ProviderAppToken").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOAuth2ProviderAppToken", r1)
 	return r0, r1
 }
 
@@ -1401,6 +2707,7 @@ func (m metricsStore) InsertOrganization(ctx context.Context, arg database.Inser
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertOrganization").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOrganization", err)
 	return organization, err
 }
 
@@ -1408,34 +2715,72 @@ func (m metricsStore) InsertOrganizationMember(ctx context.Context, arg database
 	start := time.Now()
 	member, err := m.s.InsertOrganizationMember(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertOrganizationMember").Observe(time.Since(start).Seconds())
+	m.observeError("InsertOrganizationMember", err)
 	return member, err
 }
 
+func (m metricsStore) InsertPasskey(ctx context.Context, arg database.InsertPasskeyParams) (database.Passkey, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertPasskey(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertPasskey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertPasskey", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.InsertProvisionerJob(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertProvisionerJob").Observe(time.Since(start).Seconds())
+	m.observeError("InsertProvisionerJob", err)
 	return job, err
 }
 
+func (m metricsStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerJobLogArchive(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerJobLogArchive").Observe(time.Since(start).Seconds())
+	m.observeError("InsertProvisionerJobLogArchive", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.InsertProvisionerJobLogs(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertProvisionerJobLogs").Observe(time.Since(start).Seconds())
+	m.observeError("InsertProvisionerJobLogs", err)
+	m.observeRows("InsertProvisionerJobLogs", len(logs))
 	return logs, err
 }
 
+func (m metricsStore) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerKey").Observe(time.Since(start).Seconds())
+	m.observeError("InsertProvisionerKey", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.InsertReplica(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertReplica").Observe(time.Since(start).Seconds())
+	m.observeError("InsertReplica", err)
 	return replica, err
 }
 
+func (m metricsStore) InsertSessionRecording(ctx context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertSessionRecording(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertSessionRecording").Observe(time.Since(start).Seconds())
+	m.observeError("InsertSessionRecording", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplate(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertTemplate").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTemplate", err)
 	return err
 }
 
@@ -1443,6 +2788,7 @@ func (m metricsStore) InsertTemplateVersion(ctx context.Context, arg database.In
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTemplateVersion", err)
 	return err
 }
 
@@ -1450,20 +2796,39 @@ func (m metricsStore) InsertTemplateVersionParameter(ctx context.Context, arg da
 	start := time.Now()
 	parameter, err := m.s.InsertTemplateVersionParameter(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertTemplateVersionParameter").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTemplateVersionParameter", err)
 	return parameter, err
 }
 
+func (m metricsStore) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionPreset(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionPreset").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTemplateVersionPreset", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variable, err := m.s.InsertTemplateVersionVariable(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertTemplateVersionVariable").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTemplateVersionVariable", err)
 	return variable, err
 }
 
+func (m metricsStore) InsertTwoFactorChallenge(ctx context.Context, arg database.InsertTwoFactorChallengeParams) (database.TwoFactorChallenge, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTwoFactorChallenge(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTwoFactorChallenge").Observe(time.Since(start).Seconds())
+	m.observeError("InsertTwoFactorChallenge", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.InsertUser(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertUser").Observe(time.Since(start).Seconds())
+	m.observeError("InsertUser", err)
 	return user, err
 }
 
@@ -1471,6 +2836,7 @@ func (m metricsStore) InsertUserGroupsByName(ctx context.Context, arg database.I
 	start := time.Now()
 	err := m.s.InsertUserGroupsByName(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertUserGroupsByName").Observe(time.Since(start).Seconds())
+	m.observeError("InsertUserGroupsByName", err)
 	return err
 }
 
@@ -1478,13 +2844,39 @@ func (m metricsStore) InsertUserLink(ctx context.Context, arg database.InsertUse
 	start := time.Now()
 	link, err := m.s.InsertUserLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertUserLink").Observe(time.Since(start).Seconds())
+	m.observeError("InsertUserLink", err)
 	return link, err
 }
 
+func (m metricsStore) InsertUserRecoveryCode(ctx context.Context, arg database.InsertUserRecoveryCodeParams) error {
+	start := time.Now()
+	err := m.s.InsertUserRecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserRecoveryCode").Observe(time.Since(start).Seconds())
+	m.observeError("InsertUserRecoveryCode", err)
+	return err
+}
+
+func (m metricsStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhook").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWebhook", r1)
+	return r0, r1
+}
+
+func (m metricsStore) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWebhookDelivery(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWebhookDelivery").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWebhookDelivery", r1)
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.InsertWorkspace(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspace").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspace", err)
 	return workspace, err
 }
 
@@ -1492,6 +2884,7 @@ func (m metricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.Ins
 	start := time.Now()
 	agent, err := m.s.InsertWorkspaceAgent(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgent").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgent", err)
 	return agent, err
 }
 
@@ -1499,6 +2892,8 @@ func (m metricsStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg da
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogSources(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogSources").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentLogSources", r1)
+	m.observeRows("InsertWorkspaceAgentLogSources", len(r0))
 	return r0, r1
 }
 
@@ -1506,6 +2901,8 @@ func (m metricsStore) InsertWorkspaceAgentLogs(ctx context.Context, arg database
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogs(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentLogs").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentLogs", r1)
+	m.observeRows("InsertWorkspaceAgentLogs", len(r0))
 	return r0, r1
 }
 
@@ -1513,6 +2910,15 @@ func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg data
 	start := time.Now()
 	err := m.s.InsertWorkspaceAgentMetadata(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentMetadata", err)
+	return err
+}
+
+func (m metricsStore) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	start := time.Now()
+	err := m.s.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentMetadataHistory", err)
 	return err
 }
 
@@ -1520,6 +2926,8 @@ func (m metricsStore) InsertWorkspaceAgentScripts(ctx context.Context, arg datab
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentScripts(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentScripts").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentScripts", r1)
+	m.observeRows("InsertWorkspaceAgentScripts", len(r0))
 	return r0, r1
 }
 
@@ -1527,6 +2935,7 @@ func (m metricsStore) InsertWorkspaceAgentStat(ctx context.Context, arg database
 	start := time.Now()
 	stat, err := m.s.InsertWorkspaceAgentStat(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentStat").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentStat", err)
 	return stat, err
 }
 
@@ -1534,6 +2943,7 @@ func (m metricsStore) InsertWorkspaceAgentStats(ctx context.Context, arg databas
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAgentStats(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentStats").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAgentStats", r0)
 	return r0
 }
 
@@ -1541,6 +2951,7 @@ func (m metricsStore) InsertWorkspaceApp(ctx context.Context, arg database.Inser
 	start := time.Now()
 	app, err := m.s.InsertWorkspaceApp(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceApp").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceApp", err)
 	return app, err
 }
 
@@ -1548,6 +2959,7 @@ func (m metricsStore) InsertWorkspaceAppStats(ctx context.Context, arg database.
 	start := time.Now()
 	r0 := m.s.InsertWorkspaceAppStats(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceAppStats").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceAppStats", r0)
 	return r0
 }
 
@@ -1555,6 +2967,7 @@ func (m metricsStore) InsertWorkspaceBuild(ctx context.Context, arg database.Ins
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuild(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceBuild").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceBuild", err)
 	return err
 }
 
@@ -1562,6 +2975,23 @@ func (m metricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg da
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuildParameters(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildParameters").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceBuildParameters", err)
+	return err
+}
+
+func (m metricsStore) InsertWorkspaceEvent(ctx context.Context, arg database.InsertWorkspaceEventParams) error {
+	start := time.Now()
+	err := m.s.InsertWorkspaceEvent(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceEvent").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceEvent", err)
+	return err
+}
+
+func (m metricsStore) InsertWorkspaceFavorite(ctx context.Context, arg database.InsertWorkspaceFavoriteParams) error {
+	start := time.Now()
+	err := m.s.InsertWorkspaceFavorite(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceFavorite").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceFavorite", err)
 	return err
 }
 
@@ -1569,6 +2999,7 @@ func (m metricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.Ins
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceProxy", err)
 	return proxy, err
 }
 
@@ -1576,6 +3007,7 @@ func (m metricsStore) InsertWorkspaceResource(ctx context.Context, arg database.
 	start := time.Now()
 	resource, err := m.s.InsertWorkspaceResource(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceResource").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceResource", err)
 	return resource, err
 }
 
@@ -1583,20 +3015,65 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	start := time.Now()
 	metadata, err := m.s.InsertWorkspaceResourceMetadata(ctx, arg)
 	m.queryLatencies.WithLabelValues("InsertWorkspaceResourceMetadata").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceResourceMetadata", err)
+	m.observeRows("InsertWorkspaceResourceMetadata", len(metadata))
 	return metadata, err
 }
 
+func (m metricsStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceSnapshot(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceSnapshot").Observe(time.Since(start).Seconds())
+	m.observeError("InsertWorkspaceSnapshot", r1)
+	return r0, r1
+}
+
+func (m metricsStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.ListProvisionerKeysByOrganization(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("ListProvisionerKeysByOrganization").Observe(time.Since(start).Seconds())
+	m.observeError("ListProvisionerKeysByOrganization", r1)
+	m.observeRows("ListProvisionerKeysByOrganization", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) RecordLoginAttempt(ctx context.Context, arg database.RecordLoginAttemptParams) (database.LoginFailure, error) {
+	start := time.Now()
+	r0, r1 := m.s.RecordLoginAttempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("RecordLoginAttempt").Observe(time.Since(start).Seconds())
+	m.observeError("RecordLoginAttempt", r1)
+	return r0, r1
+}
+
+func (m metricsStore) RecordUnknownLoginAttempt(ctx context.Context, arg database.RecordUnknownLoginAttemptParams) (database.UnknownLoginFailure, error) {
+	start := time.Now()
+	r0, r1 := m.s.RecordUnknownLoginAttempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("RecordUnknownLoginAttempt").Observe(time.Since(start).Seconds())
+	m.observeError("RecordUnknownLoginAttempt", r1)
+	return r0, r1
+}
+
 func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
 	m.queryLatencies.WithLabelValues("RegisterWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observeError("RegisterWorkspaceProxy", err)
 	return proxy, err
 }
 
+func (m metricsStore) RequeueProvisionerJobByID(ctx context.Context, arg database.RequeueProvisionerJobByIDParams) error {
+	start := time.Now()
+	err := m.s.RequeueProvisionerJobByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("RequeueProvisionerJobByID").Observe(time.Since(start).Seconds())
+	m.observeError("RequeueProvisionerJobByID", err)
+	return err
+}
+
 func (m metricsStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
 	start := time.Now()
 	r0 := m.s.RevokeDBCryptKey(ctx, activeKeyDigest)
 	m.queryLatencies.WithLabelValues("RevokeDBCryptKey").Observe(time.Since(start).Seconds())
+	m.observeError("RevokeDBCryptKey", r0)
 	return r0
 }
 
@@ -1604,6 +3081,7 @@ func (m metricsStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock
 	start := time.Now()
 	ok, err := m.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
 	m.queryLatencies.WithLabelValues("TryAcquireLock").Observe(time.Since(start).Seconds())
+	m.observeError("TryAcquireLock", err)
 	return ok, err
 }
 
@@ -1611,6 +3089,7 @@ func (m metricsStore) UnarchiveTemplateVersion(ctx context.Context, arg database
 	start := time.Now()
 	r0 := m.s.UnarchiveTemplateVersion(ctx, arg)
 	m.queryLatencies.WithLabelValues("UnarchiveTemplateVersion").Observe(time.Since(start).Seconds())
+	m.observeError("UnarchiveTemplateVersion", r0)
 	return r0
 }
 
@@ -1618,6 +3097,7 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	start := time.Now()
 	err := m.s.UpdateAPIKeyByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateAPIKeyByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateAPIKeyByID", err)
 	return err
 }
 
@@ -1625,6 +3105,7 @@ func (m metricsStore) UpdateExternalAuthLink(ctx context.Context, arg database.U
 	start := time.Now()
 	link, err := m.s.UpdateExternalAuthLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateExternalAuthLink").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateExternalAuthLink", err)
 	return link, err
 }
 
@@ -1632,6 +3113,7 @@ func (m metricsStore) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGi
 	start := time.Now()
 	key, err := m.s.UpdateGitSSHKey(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateGitSSHKey").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateGitSSHKey", err)
 	return key, err
 }
 
@@ -1639,6 +3121,7 @@ func (m metricsStore) UpdateGroupByID(ctx context.Context, arg database.UpdateGr
 	start := time.Now()
 	group, err := m.s.UpdateGroupByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateGroupByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateGroupByID", err)
 	return group, err
 }
 
@@ -1646,20 +3129,49 @@ func (m metricsStore) UpdateInactiveUsersToDormant(ctx context.Context, lastSeen
 	start := time.Now()
 	r0, r1 := m.s.UpdateInactiveUsersToDormant(ctx, lastSeenAfter)
 	m.queryLatencies.WithLabelValues("UpdateInactiveUsersToDormant").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateInactiveUsersToDormant", r1)
+	m.observeRows("UpdateInactiveUsersToDormant", len(r0))
+	return r0, r1
+}
+
+func (m metricsStore) UpdateInactiveUsersToSuspended(ctx context.Context, arg database.UpdateInactiveUsersToSuspendedParams) ([]database.UpdateInactiveUsersToSuspendedRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateInactiveUsersToSuspended(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateInactiveUsersToSuspended").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateInactiveUsersToSuspended", r1)
+	m.observeRows("UpdateInactiveUsersToSuspended", len(r0))
 	return r0, r1
 }
 
+func (m metricsStore) UpdateInboxNotificationsReadByUserID(ctx context.Context, arg database.UpdateInboxNotificationsReadByUserIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateInboxNotificationsReadByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateInboxNotificationsReadByUserID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateInboxNotificationsReadByUserID", err)
+	return err
+}
+
 func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	start := time.Now()
 	member, err := m.s.UpdateMemberRoles(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateMemberRoles").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateMemberRoles", err)
 	return member, err
 }
 
+func (m metricsStore) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateNotificationMessageByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateNotificationMessageByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateNotificationMessageByID", err)
+	return err
+}
+
 func (m metricsStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateOAuth2ProviderAppByID", r1)
 	return r0, r1
 }
 
@@ -1667,13 +3179,23 @@ func (m metricsStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg
 	start := time.Now()
 	r0, r1 := m.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateOAuth2ProviderAppSecretByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateOAuth2ProviderAppSecretByID", r1)
 	return r0, r1
 }
 
+func (m metricsStore) UpdatePasskeyUsage(ctx context.Context, arg database.UpdatePasskeyUsageParams) error {
+	start := time.Now()
+	err := m.s.UpdatePasskeyUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdatePasskeyUsage").Observe(time.Since(start).Seconds())
+	m.observeError("UpdatePasskeyUsage", err)
+	return err
+}
+
 func (m metricsStore) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateProvisionerDaemonLastSeenAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerDaemonLastSeenAt", r0)
 	return r0
 }
 
@@ -1681,6 +3203,15 @@ func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateProvisionerJobByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerJobByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateProvisionerJobPriorityByID(ctx context.Context, arg database.UpdateProvisionerJobPriorityByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateProvisionerJobPriorityByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerJobPriorityByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerJobPriorityByID", err)
 	return err
 }
 
@@ -1688,6 +3219,7 @@ func (m metricsStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, ar
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobWithCancelByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithCancelByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerJobWithCancelByID", err)
 	return err
 }
 
@@ -1695,20 +3227,39 @@ func (m metricsStore) UpdateProvisionerJobWithCompleteByID(ctx context.Context,
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobWithCompleteByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithCompleteByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerJobWithCompleteByID", err)
 	return err
 }
 
+func (m metricsStore) UpdateProvisionerKeySecretByID(ctx context.Context, arg database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateProvisionerKeySecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerKeySecretByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateProvisionerKeySecretByID", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.UpdateReplica(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateReplica").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateReplica", err)
 	return replica, err
 }
 
+func (m metricsStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
+	start := time.Now()
+	err := m.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTailnetPeerStatusByCoordinator").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTailnetPeerStatusByCoordinator", err)
+	return err
+}
+
 func (m metricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateACLByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateACLByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateACLByID", err)
 	return err
 }
 
@@ -1716,6 +3267,7 @@ func (m metricsStore) UpdateTemplateAccessControlByID(ctx context.Context, arg d
 	start := time.Now()
 	r0 := m.s.UpdateTemplateAccessControlByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateAccessControlByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateAccessControlByID", r0)
 	return r0
 }
 
@@ -1723,6 +3275,15 @@ func (m metricsStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg d
 	start := time.Now()
 	err := m.s.UpdateTemplateActiveVersionByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateActiveVersionByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateActiveVersionByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateBuildRetryPolicyByID(ctx context.Context, arg database.UpdateTemplateBuildRetryPolicyByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateBuildRetryPolicyByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateBuildRetryPolicyByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateBuildRetryPolicyByID", err)
 	return err
 }
 
@@ -1730,6 +3291,31 @@ func (m metricsStore) UpdateTemplateDeletedByID(ctx context.Context, arg databas
 	start := time.Now()
 	err := m.s.UpdateTemplateDeletedByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateDeletedByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateDeletedByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateMaintenanceWindowByID(ctx context.Context, arg database.UpdateTemplateMaintenanceWindowByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateMaintenanceWindowByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateMaintenanceWindowByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateMaintenanceWindowByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateMaxBuildDurationByID(ctx context.Context, arg database.UpdateTemplateMaxBuildDurationByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateMaxBuildDurationByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateMaxBuildDurationByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateMaxBuildDurationByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateMaxRunningWorkspacesByID(ctx context.Context, arg database.UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateMaxRunningWorkspacesByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateMaxRunningWorkspacesByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateMaxRunningWorkspacesByID", err)
 	return err
 }
 
@@ -1737,6 +3323,15 @@ func (m metricsStore) UpdateTemplateMetaByID(ctx context.Context, arg database.U
 	start := time.Now()
 	err := m.s.UpdateTemplateMetaByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateMetaByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateMetaByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplatePortForwardingPolicyByID(ctx context.Context, arg database.UpdateTemplatePortForwardingPolicyByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplatePortForwardingPolicyByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplatePortForwardingPolicyByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplatePortForwardingPolicyByID", err)
 	return err
 }
 
@@ -1744,6 +3339,15 @@ func (m metricsStore) UpdateTemplateScheduleByID(ctx context.Context, arg databa
 	start := time.Now()
 	err := m.s.UpdateTemplateScheduleByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateScheduleByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateScheduleByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateSessionRecordingByID(ctx context.Context, arg database.UpdateTemplateSessionRecordingByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateSessionRecordingByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateSessionRecordingByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateSessionRecordingByID", err)
 	return err
 }
 
@@ -1751,6 +3355,7 @@ func (m metricsStore) UpdateTemplateVersionByID(ctx context.Context, arg databas
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateVersionByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateVersionByID", err)
 	return err
 }
 
@@ -1758,6 +3363,7 @@ func (m metricsStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Contex
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionDescriptionByJobID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateVersionDescriptionByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateVersionDescriptionByJobID", err)
 	return err
 }
 
@@ -1765,6 +3371,23 @@ func (m metricsStore) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx cont
 	start := time.Now()
 	err := m.s.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateVersionExternalAuthProvidersByJobID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateVersionExternalAuthProvidersByJobID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateVisibilityByID(ctx context.Context, arg database.UpdateTemplateVisibilityByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateVisibilityByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVisibilityByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateVisibilityByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateTemplateWorkspaceNamePolicyByID(ctx context.Context, arg database.UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateTemplateWorkspaceNamePolicyByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateWorkspaceNamePolicyByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateWorkspaceNamePolicyByID", err)
 	return err
 }
 
@@ -1772,6 +3395,7 @@ func (m metricsStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, ar
 	start := time.Now()
 	r0 := m.s.UpdateTemplateWorkspacesLastUsedAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateTemplateWorkspacesLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateTemplateWorkspacesLastUsedAt", r0)
 	return r0
 }
 
@@ -1779,6 +3403,7 @@ func (m metricsStore) UpdateUserAppearanceSettings(ctx context.Context, arg data
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserAppearanceSettings(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserAppearanceSettings").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserAppearanceSettings", r1)
 	return r0, r1
 }
 
@@ -1786,6 +3411,7 @@ func (m metricsStore) UpdateUserDeletedByID(ctx context.Context, arg database.Up
 	start := time.Now()
 	err := m.s.UpdateUserDeletedByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserDeletedByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserDeletedByID", err)
 	return err
 }
 
@@ -1793,13 +3419,23 @@ func (m metricsStore) UpdateUserHashedPassword(ctx context.Context, arg database
 	start := time.Now()
 	err := m.s.UpdateUserHashedPassword(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserHashedPassword").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserHashedPassword", err)
 	return err
 }
 
+func (m metricsStore) UpdateUserIPAllowlist(ctx context.Context, arg database.UpdateUserIPAllowlistParams) (database.User, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateUserIPAllowlist(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateUserIPAllowlist").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserIPAllowlist", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.UpdateUserLastSeenAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserLastSeenAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserLastSeenAt", err)
 	return user, err
 }
 
@@ -1807,6 +3443,7 @@ func (m metricsStore) UpdateUserLink(ctx context.Context, arg database.UpdateUse
 	start := time.Now()
 	link, err := m.s.UpdateUserLink(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserLink").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserLink", err)
 	return link, err
 }
 
@@ -1814,6 +3451,7 @@ func (m metricsStore) UpdateUserLinkedID(ctx context.Context, arg database.Updat
 	start := time.Now()
 	link, err := m.s.UpdateUserLinkedID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserLinkedID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserLinkedID", err)
 	return link, err
 }
 
@@ -1821,6 +3459,7 @@ func (m metricsStore) UpdateUserLoginType(ctx context.Context, arg database.Upda
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserLoginType(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserLoginType").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserLoginType", r1)
 	return r0, r1
 }
 
@@ -1828,6 +3467,7 @@ func (m metricsStore) UpdateUserProfile(ctx context.Context, arg database.Update
 	start := time.Now()
 	user, err := m.s.UpdateUserProfile(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserProfile").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserProfile", err)
 	return user, err
 }
 
@@ -1835,6 +3475,7 @@ func (m metricsStore) UpdateUserQuietHoursSchedule(ctx context.Context, arg data
 	start := time.Now()
 	r0, r1 := m.s.UpdateUserQuietHoursSchedule(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserQuietHoursSchedule").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserQuietHoursSchedule", r1)
 	return r0, r1
 }
 
@@ -1842,6 +3483,7 @@ func (m metricsStore) UpdateUserRoles(ctx context.Context, arg database.UpdateUs
 	start := time.Now()
 	user, err := m.s.UpdateUserRoles(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserRoles").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserRoles", err)
 	return user, err
 }
 
@@ -1849,20 +3491,55 @@ func (m metricsStore) UpdateUserStatus(ctx context.Context, arg database.UpdateU
 	start := time.Now()
 	user, err := m.s.UpdateUserStatus(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateUserStatus").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateUserStatus", err)
 	return user, err
 }
 
+func (m metricsStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWebhookDeliveryByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookDeliveryByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWebhookDeliveryByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateWebhookSecretByID(ctx context.Context, arg database.UpdateWebhookSecretByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWebhookSecretByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWebhookSecretByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWebhookSecretByID", err)
+	return err
+}
+
 func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.UpdateWorkspace(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspace").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspace", err)
 	return workspace, err
 }
 
+func (m metricsStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceACLByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceACLByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceACLByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceAgentAuthTokenByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentAuthTokenByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentAuthTokenByID", err)
+	return err
+}
+
 func (m metricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentConnectionByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentConnectionByID", err)
 	return err
 }
 
@@ -1870,6 +3547,7 @@ func (m metricsStore) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAgentLifecycleStateByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentLifecycleStateByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentLifecycleStateByID", r0)
 	return r0
 }
 
@@ -1877,6 +3555,7 @@ func (m metricsStore) UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, a
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAgentLogOverflowByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentLogOverflowByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentLogOverflowByID", r0)
 	return r0
 }
 
@@ -1884,6 +3563,7 @@ func (m metricsStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg data
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentMetadata(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentMetadata").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentMetadata", err)
 	return err
 }
 
@@ -1891,6 +3571,7 @@ func (m metricsStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg d
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentStartupByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAgentStartupByID", err)
 	return err
 }
 
@@ -1898,6 +3579,7 @@ func (m metricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg data
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAppHealthByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAppHealthByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAppHealthByID", err)
 	return err
 }
 
@@ -1905,6 +3587,7 @@ func (m metricsStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg d
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutomaticUpdates").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAutomaticUpdates", r0)
 	return r0
 }
 
@@ -1912,6 +3595,7 @@ func (m metricsStore) UpdateWorkspaceAutostart(ctx context.Context, arg database
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAutostart(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutostart").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceAutostart", err)
 	return err
 }
 
@@ -1919,6 +3603,7 @@ func (m metricsStore) UpdateWorkspaceBuildCostByID(ctx context.Context, arg data
 	start := time.Now()
 	err := m.s.UpdateWorkspaceBuildCostByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildCostByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceBuildCostByID", err)
 	return err
 }
 
@@ -1926,6 +3611,7 @@ func (m metricsStore) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceBuildDeadlineByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildDeadlineByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceBuildDeadlineByID", r0)
 	return r0
 }
 
@@ -1933,13 +3619,31 @@ func (m metricsStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Conte
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildProvisionerStateByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceBuildProvisionerStateByID", r0)
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceBuildRetryAtByID(ctx context.Context, arg database.UpdateWorkspaceBuildRetryAtByIDParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceBuildRetryAtByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceBuildRetryAtByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceBuildRetryAtByID", err)
+	return err
+}
+
 func (m metricsStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceDeletedByID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceDeletedByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceDeletedByID", err)
+	return err
+}
+
+func (m metricsStore) UpdateWorkspaceDormancyExempt(ctx context.Context, arg database.UpdateWorkspaceDormancyExemptParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceDormancyExempt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceDormancyExempt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceDormancyExempt", err)
 	return err
 }
 
@@ -1947,20 +3651,47 @@ func (m metricsStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg
 	start := time.Now()
 	ws, r0 := m.s.UpdateWorkspaceDormantDeletingAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceDormantDeletingAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceDormantDeletingAt", r0)
 	return ws, r0
 }
 
+func (m metricsStore) UpdateWorkspaceDriftResult(ctx context.Context, arg database.UpdateWorkspaceDriftResultParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceDriftResult(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceDriftResult").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceDriftResult", err)
+	return err
+}
+
 func (m metricsStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceLastUsedAt(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceLastUsedAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceLastUsedAt", err)
+	return err
+}
+
+func (m metricsStore) UpdateWorkspaceMaintenanceOptOut(ctx context.Context, arg database.UpdateWorkspaceMaintenanceOptOutParams) error {
+	start := time.Now()
+	err := m.s.UpdateWorkspaceMaintenanceOptOut(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceMaintenanceOptOut").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceMaintenanceOptOut", err)
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceOwnerByID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceOwnerByID", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.UpdateWorkspaceProxy(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceProxy").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceProxy", err)
 	return proxy, err
 }
 
@@ -1968,6 +3699,7 @@ func (m metricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg datab
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceProxyDeleted(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceProxyDeleted").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceProxyDeleted", r0)
 	return r0
 }
 
@@ -1975,6 +3707,7 @@ func (m metricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.Updat
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceTTL(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspaceTTL").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspaceTTL", r0)
 	return r0
 }
 
@@ -1982,6 +3715,7 @@ func (m metricsStore) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.
 	start := time.Now()
 	r0 := m.s.UpdateWorkspacesDormantDeletingAtByTemplateID(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpdateWorkspacesDormantDeletingAtByTemplateID").Observe(time.Since(start).Seconds())
+	m.observeError("UpdateWorkspacesDormantDeletingAtByTemplateID", r0)
 	return r0
 }
 
@@ -1989,6 +3723,7 @@ func (m metricsStore) UpsertAppSecurityKey(ctx context.Context, value string) er
 	start := time.Now()
 	r0 := m.s.UpsertAppSecurityKey(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertAppSecurityKey").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertAppSecurityKey", r0)
 	return r0
 }
 
@@ -1996,13 +3731,23 @@ func (m metricsStore) UpsertApplicationName(ctx context.Context, value string) e
 	start := time.Now()
 	r0 := m.s.UpsertApplicationName(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertApplicationName").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertApplicationName", r0)
 	return r0
 }
 
+func (m metricsStore) UpsertCustomRole(ctx context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertCustomRole(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertCustomRole").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertCustomRole", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertDefaultProxy(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpsertDefaultProxy").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertDefaultProxy", r0)
 	return r0
 }
 
@@ -2010,6 +3755,7 @@ func (m metricsStore) UpsertHealthSettings(ctx context.Context, value string) er
 	start := time.Now()
 	r0 := m.s.UpsertHealthSettings(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertHealthSettings").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertHealthSettings", r0)
 	return r0
 }
 
@@ -2017,27 +3763,63 @@ func (m metricsStore) UpsertLastUpdateCheck(ctx context.Context, value string) e
 	start := time.Now()
 	r0 := m.s.UpsertLastUpdateCheck(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertLastUpdateCheck").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertLastUpdateCheck", r0)
 	return r0
 }
 
+func (m metricsStore) UpsertLicenseGracePeriodStartedAt(ctx context.Context, value string) error {
+	start := time.Now()
+	err := m.s.UpsertLicenseGracePeriodStartedAt(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertLicenseGracePeriodStartedAt").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertLicenseGracePeriodStartedAt", err)
+	return err
+}
+
 func (m metricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLogoURL(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertLogoURL").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertLogoURL", r0)
 	return r0
 }
 
+func (m metricsStore) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertNotificationPreference(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertNotificationPreference").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertNotificationPreference", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpsertOAuthSigningKey(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertOAuthSigningKey(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertOAuthSigningKey").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertOAuthSigningKey", r0)
 	return r0
 }
 
+func (m metricsStore) UpsertOrganizationQuota(ctx context.Context, arg database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationQuota(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertOrganizationQuota").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertOrganizationQuota", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertOrganizationTemplateVariableValue(ctx context.Context, arg database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertOrganizationTemplateVariableValue(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertOrganizationTemplateVariableValue").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertOrganizationTemplateVariableValue", r1)
+	return r0, r1
+}
+
 func (m metricsStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertProvisionerDaemon(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpsertProvisionerDaemon").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertProvisionerDaemon", r1)
 	return r0, r1
 }
 
@@ -2045,38 +3827,47 @@ func (m metricsStore) UpsertServiceBanner(ctx context.Context, value string) err
 	start := time.Now()
 	r0 := m.s.UpsertServiceBanner(ctx, value)
 	m.queryLatencies.WithLabelValues("UpsertServiceBanner").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertServiceBanner", r0)
 	return r0
 }
 
 func (m metricsStore) UpsertTailnetAgent(ctx context.Context, arg database.UpsertTailnetAgentParams) (database.TailnetAgent, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("UpsertTailnetAgent").Observe(time.Since(start).Seconds())
-	return m.s.UpsertTailnetAgent(ctx, arg)
+	r0, r1 := m.s.UpsertTailnetAgent(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTailnetAgent").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetAgent", r1)
+	return r0, r1
 }
 
 func (m metricsStore) UpsertTailnetClient(ctx context.Context, arg database.UpsertTailnetClientParams) (database.TailnetClient, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("UpsertTailnetClient").Observe(time.Since(start).Seconds())
-	return m.s.UpsertTailnetClient(ctx, arg)
+	r0, r1 := m.s.UpsertTailnetClient(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTailnetClient").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetClient", r1)
+	return r0, r1
 }
 
 func (m metricsStore) UpsertTailnetClientSubscription(ctx context.Context, arg database.UpsertTailnetClientSubscriptionParams) error {
 	start := time.Now()
 	r0 := m.s.UpsertTailnetClientSubscription(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpsertTailnetClientSubscription").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetClientSubscription", r0)
 	return r0
 }
 
 func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (database.TailnetCoordinator, error) {
 	start := time.Now()
-	defer m.queryLatencies.WithLabelValues("UpsertTailnetCoordinator").Observe(time.Since(start).Seconds())
-	return m.s.UpsertTailnetCoordinator(ctx, id)
+	r0, r1 := m.s.UpsertTailnetCoordinator(ctx, id)
+	m.queryLatencies.WithLabelValues("UpsertTailnetCoordinator").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetCoordinator", r1)
+	return r0, r1
 }
 
 func (m metricsStore) UpsertTailnetPeer(ctx context.Context, arg database.UpsertTailnetPeerParams) (database.TailnetPeer, error) {
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetPeer(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpsertTailnetPeer").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetPeer", r1)
 	return r0, r1
 }
 
@@ -2084,6 +3875,111 @@ func (m metricsStore) UpsertTailnetTunnel(ctx context.Context, arg database.Upse
 	start := time.Now()
 	r0, r1 := m.s.UpsertTailnetTunnel(ctx, arg)
 	m.queryLatencies.WithLabelValues("UpsertTailnetTunnel").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTailnetTunnel", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertTemplateVariableValue(ctx context.Context, arg database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTemplateVariableValue(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateVariableValue").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTemplateVariableValue", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertTwoFactorPolicy(ctx context.Context, arg database.UpsertTwoFactorPolicyParams) (database.TwoFactorPolicy, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertTwoFactorPolicy(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTwoFactorPolicy").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertTwoFactorPolicy", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserActivity(ctx context.Context, arg database.UpsertUserActivityParams) error {
+	start := time.Now()
+	err := m.s.UpsertUserActivity(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserActivity").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertUserActivity", err)
+	return err
+}
+
+func (m metricsStore) UpsertUserLockout(ctx context.Context, arg database.UpsertUserLockoutParams) (database.UserLockout, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserLockout(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserLockout").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertUserLockout", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserSCIMExternalID(ctx context.Context, arg database.UpsertUserSCIMExternalIDParams) (database.UserSCIMExternalID, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserSCIMExternalID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserSCIMExternalID").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertUserSCIMExternalID", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertWorkspaceAgentGPUs(ctx context.Context, arg database.UpsertWorkspaceAgentGPUsParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceAgentGPUs(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentGPUs").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceAgentGPUs", err)
+	return err
+}
+
+func (m metricsStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceAgentPortShare(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPortShare").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceAgentPortShare", r1)
+	return r0, r1
+}
+
+func (m metricsStore) UpsertWorkspaceAgentPorts(ctx context.Context, arg database.UpsertWorkspaceAgentPortsParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceAgentPorts(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPorts").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceAgentPorts", err)
+	return err
+}
+
+func (m metricsStore) UpsertWorkspaceAgentPreviousAuthToken(ctx context.Context, arg database.UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceAgentPreviousAuthToken(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceAgentPreviousAuthToken").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceAgentPreviousAuthToken", err)
+	return err
+}
+
+func (m metricsStore) UpsertWorkspaceDriftJob(ctx context.Context, arg database.UpsertWorkspaceDriftJobParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceDriftJob(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceDriftJob").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceDriftJob", err)
+	return err
+}
+
+func (m metricsStore) UpsertWorkspaceProxyHealth(ctx context.Context, arg database.UpsertWorkspaceProxyHealthParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceProxyHealth(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceProxyHealth").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceProxyHealth", err)
+	return err
+}
+
+func (m metricsStore) UpsertWorkspaceResourceCosts(ctx context.Context, arg database.UpsertWorkspaceResourceCostsParams) error {
+	start := time.Now()
+	err := m.s.UpsertWorkspaceResourceCosts(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceResourceCosts").Observe(time.Since(start).Seconds())
+	m.observeError("UpsertWorkspaceResourceCosts", err)
+	return err
+}
+
+func (m metricsStore) UseUserRecoveryCode(ctx context.Context, arg database.UseUserRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	start := time.Now()
+	r0, r1 := m.s.UseUserRecoveryCode(ctx, arg)
+	m.queryLatencies.WithLabelValues("UseUserRecoveryCode").Observe(time.Since(start).Seconds())
+	m.observeError("UseUserRecoveryCode", r1)
 	return r0, r1
 }
 
@@ -2091,6 +3987,8 @@ func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.G
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
 	m.queryLatencies.WithLabelValues("GetAuthorizedTemplates").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuthorizedTemplates", err)
+	m.observeRows("GetAuthorizedTemplates", len(templates))
 	return templates, err
 }
 
@@ -2098,6 +3996,8 @@ func (m metricsStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) (
 	start := time.Now()
 	roles, err := m.s.GetTemplateGroupRoles(ctx, id)
 	m.queryLatencies.WithLabelValues("GetTemplateGroupRoles").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateGroupRoles", err)
+	m.observeRows("GetTemplateGroupRoles", len(roles))
 	return roles, err
 }
 
@@ -2105,6 +4005,8 @@ func (m metricsStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([
 	start := time.Now()
 	roles, err := m.s.GetTemplateUserRoles(ctx, id)
 	m.queryLatencies.WithLabelValues("GetTemplateUserRoles").Observe(time.Since(start).Seconds())
+	m.observeError("GetTemplateUserRoles", err)
+	m.observeRows("GetTemplateUserRoles", len(roles))
 	return roles, err
 }
 
@@ -2112,6 +4014,8 @@ func (m metricsStore) GetAuthorizedWorkspaces(ctx context.Context, arg database.
 	start := time.Now()
 	workspaces, err := m.s.GetAuthorizedWorkspaces(ctx, arg, prepared)
 	m.queryLatencies.WithLabelValues("GetAuthorizedWorkspaces").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuthorizedWorkspaces", err)
+	m.observeRows("GetAuthorizedWorkspaces", len(workspaces))
 	return workspaces, err
 }
 
@@ -2119,5 +4023,7 @@ func (m metricsStore) GetAuthorizedUsers(ctx context.Context, arg database.GetUs
 	start := time.Now()
 	r0, r1 := m.s.GetAuthorizedUsers(ctx, arg, prepared)
 	m.queryLatencies.WithLabelValues("GetAuthorizedUsers").Observe(time.Since(start).Seconds())
+	m.observeError("GetAuthorizedUsers", r1)
+	m.observeRows("GetAuthorizedUsers", len(r0))
 	return r0, r1
 }
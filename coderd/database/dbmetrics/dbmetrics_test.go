@@ -0,0 +1,88 @@
+package dbmetrics_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbmetrics"
+)
+
+func TestMetricsStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ErrorClass", func(t *testing.T) {
+		t.Parallel()
+
+		reg := prometheus.NewRegistry()
+		db := dbmetrics.New(dbmem.New(), reg)
+		ctx := context.Background()
+
+		key := database.InsertDBCryptKeyParams{
+			Number:          1,
+			ActiveKeyDigest: "digest",
+			Test:            "test",
+		}
+		require.NoError(t, db.InsertDBCryptKey(ctx, key))
+		require.Error(t, db.InsertDBCryptKey(ctx, key))
+
+		// Not found errors are expected and not counted.
+		_, err := db.GetUserByID(ctx, uuid.New())
+		require.Error(t, err)
+
+		errors := findMetrics(t, reg, "coderd_db_query_errors_total")
+		require.Len(t, errors, 1)
+		require.Equal(t, map[string]string{
+			"query": "InsertDBCryptKey",
+			"class": "integrity_constraint_violation",
+		}, labels(errors[0]))
+		require.EqualValues(t, 1, errors[0].GetCounter().GetValue())
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		t.Parallel()
+
+		reg := prometheus.NewRegistry()
+		db := dbmetrics.New(dbmem.New(), reg)
+		ctx := context.Background()
+
+		first := dbgen.User(t, db, database.User{})
+		second := dbgen.User(t, db, database.User{})
+		users, err := db.GetUsersByIDs(ctx, []uuid.UUID{first.ID, second.ID})
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+
+		rows := findMetrics(t, reg, "coderd_db_query_rows_returned")
+		require.Len(t, rows, 1)
+		require.Equal(t, map[string]string{"query": "GetUsersByIDs"}, labels(rows[0]))
+		require.EqualValues(t, 1, rows[0].GetHistogram().GetSampleCount())
+		require.EqualValues(t, 2, rows[0].GetHistogram().GetSampleSum())
+	})
+}
+
+func findMetrics(t *testing.T, reg *prometheus.Registry, name string) []*dto.Metric {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()
+		}
+	}
+	return nil
+}
+
+func labels(metric *dto.Metric) map[string]string {
+	m := make(map[string]string)
+	for _, label := range metric.GetLabel() {
+		m[label.GetName()] = label.GetValue()
+	}
+	return m
+}
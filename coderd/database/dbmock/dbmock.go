@@ -11,7 +11,6 @@ package dbmock
 
 import (
 	context "context"
-	sql "database/sql"
 	reflect "reflect"
 	time "time"
 
@@ -39,6 +38,20 @@ func NewMockStore(ctrl *gomock.Controller) *MockStore {
 	return mock
 }
 
+// DeleteUnknownLoginFailure mocks base method.
+func (m *MockStore) DeleteUnknownLoginFailure(arg0 context.Context, arg1 database.DeleteUnknownLoginFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnknownLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnknownLoginFailure indicates an expected call of DeleteUnknownLoginFailure.
+func (mr *MockStoreMockRecorder) DeleteUnknownLoginFailure(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnknownLoginFailure", reflect.TypeOf((*MockStore)(nil).DeleteUnknownLoginFailure), arg0, arg1)
+}
+
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
@@ -58,6 +71,21 @@ func (mr *MockStoreMockRecorder) AcquireLock(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLock", reflect.TypeOf((*MockStore)(nil).AcquireLock), arg0, arg1)
 }
 
+// AcquireNotificationMessages mocks base method.
+func (m *MockStore) AcquireNotificationMessages(arg0 context.Context, arg1 database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireNotificationMessages", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireNotificationMessages indicates an expected call of AcquireNotificationMessages.
+func (mr *MockStoreMockRecorder) AcquireNotificationMessages(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireNotificationMessages", reflect.TypeOf((*MockStore)(nil).AcquireNotificationMessages), arg0, arg1)
+}
+
 // AcquireProvisionerJob mocks base method.
 func (m *MockStore) AcquireProvisionerJob(arg0 context.Context, arg1 database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -73,6 +101,21 @@ func (mr *MockStoreMockRecorder) AcquireProvisionerJob(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireProvisionerJob", reflect.TypeOf((*MockStore)(nil).AcquireProvisionerJob), arg0, arg1)
 }
 
+// AcquireWebhookDeliveries mocks base method.
+func (m *MockStore) AcquireWebhookDeliveries(arg0 context.Context, arg1 database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireWebhookDeliveries indicates an expected call of AcquireWebhookDeliveries.
+func (mr *MockStoreMockRecorder) AcquireWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).AcquireWebhookDeliveries), arg0, arg1)
+}
+
 // ActivityBumpWorkspace mocks base method.
 func (m *MockStore) ActivityBumpWorkspace(arg0 context.Context, arg1 database.ActivityBumpWorkspaceParams) error {
 	m.ctrl.T.Helper()
@@ -173,6 +216,66 @@ func (mr *MockStoreMockRecorder) CleanTailnetTunnels(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanTailnetTunnels", reflect.TypeOf((*MockStore)(nil).CleanTailnetTunnels), arg0)
 }
 
+// CountLoginFailuresByUserID mocks base method.
+func (m *MockStore) CountLoginFailuresByUserID(arg0 context.Context, arg1 database.CountLoginFailuresByUserIDParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLoginFailuresByUserID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLoginFailuresByUserID indicates an expected call of CountLoginFailuresByUserID.
+func (mr *MockStoreMockRecorder) CountLoginFailuresByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLoginFailuresByUserID", reflect.TypeOf((*MockStore)(nil).CountLoginFailuresByUserID), arg0, arg1)
+}
+
+// CountUnreadInboxNotificationsByUserID mocks base method.
+func (m *MockStore) CountUnreadInboxNotificationsByUserID(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadInboxNotificationsByUserID", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadInboxNotificationsByUserID indicates an expected call of CountUnreadInboxNotificationsByUserID.
+func (mr *MockStoreMockRecorder) CountUnreadInboxNotificationsByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).CountUnreadInboxNotificationsByUserID), arg0, arg1)
+}
+
+// CountUnusedUserRecoveryCodes mocks base method.
+func (m *MockStore) CountUnusedUserRecoveryCodes(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedUserRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedUserRecoveryCodes indicates an expected call of CountUnusedUserRecoveryCodes.
+func (mr *MockStoreMockRecorder) CountUnusedUserRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedUserRecoveryCodes", reflect.TypeOf((*MockStore)(nil).CountUnusedUserRecoveryCodes), arg0, arg1)
+}
+
+// CustomRolesByName mocks base method.
+func (m *MockStore) CustomRolesByName(arg0 context.Context, arg1 []string) ([]database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CustomRolesByName", arg0, arg1)
+	ret0, _ := ret[0].([]database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CustomRolesByName indicates an expected call of CustomRolesByName.
+func (mr *MockStoreMockRecorder) CustomRolesByName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomRolesByName", reflect.TypeOf((*MockStore)(nil).CustomRolesByName), arg0, arg1)
+}
+
 // DeleteAPIKeyByID mocks base method.
 func (m *MockStore) DeleteAPIKeyByID(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -187,6 +290,21 @@ func (mr *MockStoreMockRecorder) DeleteAPIKeyByID(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeyByID", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeyByID), arg0, arg1)
 }
 
+// DeleteAPIKeyByIDReturning mocks base method.
+func (m *MockStore) DeleteAPIKeyByIDReturning(arg0 context.Context, arg1 string) (database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKeyByIDReturning", arg0, arg1)
+	ret0, _ := ret[0].(database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAPIKeyByIDReturning indicates an expected call of DeleteAPIKeyByIDReturning.
+func (mr *MockStoreMockRecorder) DeleteAPIKeyByIDReturning(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeyByIDReturning", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeyByIDReturning), arg0, arg1)
+}
+
 // DeleteAPIKeysByUserID mocks base method.
 func (m *MockStore) DeleteAPIKeysByUserID(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -201,6 +319,21 @@ func (mr *MockStoreMockRecorder) DeleteAPIKeysByUserID(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeysByUserID), arg0, arg1)
 }
 
+// DeleteAPIKeysByUserIDReturning mocks base method.
+func (m *MockStore) DeleteAPIKeysByUserIDReturning(arg0 context.Context, arg1 uuid.UUID) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKeysByUserIDReturning", arg0, arg1)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAPIKeysByUserIDReturning indicates an expected call of DeleteAPIKeysByUserIDReturning.
+func (mr *MockStoreMockRecorder) DeleteAPIKeysByUserIDReturning(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKeysByUserIDReturning", reflect.TypeOf((*MockStore)(nil).DeleteAPIKeysByUserIDReturning), arg0, arg1)
+}
+
 // DeleteAllTailnetClientSubscriptions mocks base method.
 func (m *MockStore) DeleteAllTailnetClientSubscriptions(arg0 context.Context, arg1 database.DeleteAllTailnetClientSubscriptionsParams) error {
 	m.ctrl.T.Helper()
@@ -257,6 +390,20 @@ func (mr *MockStoreMockRecorder) DeleteCoordinator(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoordinator", reflect.TypeOf((*MockStore)(nil).DeleteCoordinator), arg0, arg1)
 }
 
+// DeleteExpiredTwoFactorChallenges mocks base method.
+func (m *MockStore) DeleteExpiredTwoFactorChallenges(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredTwoFactorChallenges", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredTwoFactorChallenges indicates an expected call of DeleteExpiredTwoFactorChallenges.
+func (mr *MockStoreMockRecorder) DeleteExpiredTwoFactorChallenges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredTwoFactorChallenges", reflect.TypeOf((*MockStore)(nil).DeleteExpiredTwoFactorChallenges), arg0, arg1)
+}
+
 // DeleteExternalAuthLink mocks base method.
 func (m *MockStore) DeleteExternalAuthLink(arg0 context.Context, arg1 database.DeleteExternalAuthLinkParams) error {
 	m.ctrl.T.Helper()
@@ -342,6 +489,20 @@ func (mr *MockStoreMockRecorder) DeleteLicense(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicense", reflect.TypeOf((*MockStore)(nil).DeleteLicense), arg0, arg1)
 }
 
+// DeleteLoginFailure mocks base method.
+func (m *MockStore) DeleteLoginFailure(arg0 context.Context, arg1 database.DeleteLoginFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginFailure indicates an expected call of DeleteLoginFailure.
+func (mr *MockStoreMockRecorder) DeleteLoginFailure(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginFailure", reflect.TypeOf((*MockStore)(nil).DeleteLoginFailure), arg0, arg1)
+}
+
 // DeleteOAuth2ProviderAppByID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppByID(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -356,6 +517,21 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppByID(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppByID), arg0, arg1)
 }
 
+// DeleteOAuth2ProviderAppCodeByID mocks base method.
+func (m *MockStore) DeleteOAuth2ProviderAppCodeByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2ProviderAppCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuth2ProviderAppCodeByID indicates an expected call of DeleteOAuth2ProviderAppCodeByID.
+func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppCodeByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppCodeByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppCodeByID), arg0, arg1)
+}
+
 // DeleteOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) DeleteOAuth2ProviderAppSecretByID(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -370,6 +546,34 @@ func (mr *MockStoreMockRecorder) DeleteOAuth2ProviderAppSecretByID(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).DeleteOAuth2ProviderAppSecretByID), arg0, arg1)
 }
 
+// DeleteOldGroupSyncRuns mocks base method.
+func (m *MockStore) DeleteOldGroupSyncRuns(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldGroupSyncRuns", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldGroupSyncRuns indicates an expected call of DeleteOldGroupSyncRuns.
+func (mr *MockStoreMockRecorder) DeleteOldGroupSyncRuns(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldGroupSyncRuns", reflect.TypeOf((*MockStore)(nil).DeleteOldGroupSyncRuns), arg0, arg1)
+}
+
+// DeleteOldLoginFailures mocks base method.
+func (m *MockStore) DeleteOldLoginFailures(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldLoginFailures indicates an expected call of DeleteOldLoginFailures.
+func (mr *MockStoreMockRecorder) DeleteOldLoginFailures(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldLoginFailures", reflect.TypeOf((*MockStore)(nil).DeleteOldLoginFailures), arg0, arg1)
+}
+
 // DeleteOldProvisionerDaemons mocks base method.
 func (m *MockStore) DeleteOldProvisionerDaemons(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -384,6 +588,34 @@ func (mr *MockStoreMockRecorder) DeleteOldProvisionerDaemons(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).DeleteOldProvisionerDaemons), arg0)
 }
 
+// DeleteOldRateLimitCounters mocks base method.
+func (m *MockStore) DeleteOldRateLimitCounters(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldRateLimitCounters", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldRateLimitCounters indicates an expected call of DeleteOldRateLimitCounters.
+func (mr *MockStoreMockRecorder) DeleteOldRateLimitCounters(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldRateLimitCounters", reflect.TypeOf((*MockStore)(nil).DeleteOldRateLimitCounters), arg0, arg1)
+}
+
+// DeleteOldUserActivity mocks base method.
+func (m *MockStore) DeleteOldUserActivity(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldUserActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldUserActivity indicates an expected call of DeleteOldUserActivity.
+func (mr *MockStoreMockRecorder) DeleteOldUserActivity(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldUserActivity", reflect.TypeOf((*MockStore)(nil).DeleteOldUserActivity), arg0, arg1)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -398,6 +630,20 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentLogs(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentLogs", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentLogs), arg0)
 }
 
+// DeleteOldWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentMetadataHistory(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentMetadataHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentMetadataHistory indicates an expected call of DeleteOldWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentMetadataHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentMetadataHistory), arg0, arg1)
+}
+
 // DeleteOldWorkspaceAgentStats mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentStats(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -412,6 +658,104 @@ func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentStats(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentStats), arg0)
 }
 
+// DeleteOldWorkspaceEvents mocks base method.
+func (m *MockStore) DeleteOldWorkspaceEvents(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceEvents indicates an expected call of DeleteOldWorkspaceEvents.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceEvents(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceEvents", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceEvents), arg0, arg1)
+}
+
+// DeleteOrganization mocks base method.
+func (m *MockStore) DeleteOrganization(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganization", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganization indicates an expected call of DeleteOrganization.
+func (mr *MockStoreMockRecorder) DeleteOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganization", reflect.TypeOf((*MockStore)(nil).DeleteOrganization), arg0, arg1)
+}
+
+// DeleteOrganizationMember mocks base method.
+func (m *MockStore) DeleteOrganizationMember(arg0 context.Context, arg1 database.DeleteOrganizationMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganizationMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganizationMember indicates an expected call of DeleteOrganizationMember.
+func (mr *MockStoreMockRecorder) DeleteOrganizationMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationMember", reflect.TypeOf((*MockStore)(nil).DeleteOrganizationMember), arg0, arg1)
+}
+
+// DeleteOrganizationTemplateVariableValue mocks base method.
+func (m *MockStore) DeleteOrganizationTemplateVariableValue(arg0 context.Context, arg1 database.DeleteOrganizationTemplateVariableValueParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrganizationTemplateVariableValue", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOrganizationTemplateVariableValue indicates an expected call of DeleteOrganizationTemplateVariableValue.
+func (mr *MockStoreMockRecorder) DeleteOrganizationTemplateVariableValue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrganizationTemplateVariableValue", reflect.TypeOf((*MockStore)(nil).DeleteOrganizationTemplateVariableValue), arg0, arg1)
+}
+
+// DeletePasskeyByID mocks base method.
+func (m *MockStore) DeletePasskeyByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasskeyByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasskeyByID indicates an expected call of DeletePasskeyByID.
+func (mr *MockStoreMockRecorder) DeletePasskeyByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasskeyByID", reflect.TypeOf((*MockStore)(nil).DeletePasskeyByID), arg0, arg1)
+}
+
+// DeleteProvisionerJobLogsByJobID mocks base method.
+func (m *MockStore) DeleteProvisionerJobLogsByJobID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerJobLogsByJobID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProvisionerJobLogsByJobID indicates an expected call of DeleteProvisionerJobLogsByJobID.
+func (mr *MockStoreMockRecorder) DeleteProvisionerJobLogsByJobID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerJobLogsByJobID", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerJobLogsByJobID), arg0, arg1)
+}
+
+// DeleteProvisionerKey mocks base method.
+func (m *MockStore) DeleteProvisionerKey(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProvisionerKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProvisionerKey indicates an expected call of DeleteProvisionerKey.
+func (mr *MockStoreMockRecorder) DeleteProvisionerKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProvisionerKey", reflect.TypeOf((*MockStore)(nil).DeleteProvisionerKey), arg0, arg1)
+}
+
 // DeleteReplicasUpdatedBefore mocks base method.
 func (m *MockStore) DeleteReplicasUpdatedBefore(arg0 context.Context, arg1 time.Time) error {
 	m.ctrl.T.Helper()
@@ -426,6 +770,20 @@ func (mr *MockStoreMockRecorder) DeleteReplicasUpdatedBefore(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReplicasUpdatedBefore", reflect.TypeOf((*MockStore)(nil).DeleteReplicasUpdatedBefore), arg0, arg1)
 }
 
+// DeleteSessionRecordingByID mocks base method.
+func (m *MockStore) DeleteSessionRecordingByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionRecordingByID indicates an expected call of DeleteSessionRecordingByID.
+func (mr *MockStoreMockRecorder) DeleteSessionRecordingByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).DeleteSessionRecordingByID), arg0, arg1)
+}
+
 // DeleteTailnetAgent mocks base method.
 func (m *MockStore) DeleteTailnetAgent(arg0 context.Context, arg1 database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
 	m.ctrl.T.Helper()
@@ -500,6 +858,147 @@ func (mr *MockStoreMockRecorder) DeleteTailnetTunnel(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetTunnel", reflect.TypeOf((*MockStore)(nil).DeleteTailnetTunnel), arg0, arg1)
 }
 
+// DeleteTemplateVariableValue mocks base method.
+func (m *MockStore) DeleteTemplateVariableValue(arg0 context.Context, arg1 database.DeleteTemplateVariableValueParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateVariableValue", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateVariableValue indicates an expected call of DeleteTemplateVariableValue.
+func (mr *MockStoreMockRecorder) DeleteTemplateVariableValue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateVariableValue", reflect.TypeOf((*MockStore)(nil).DeleteTemplateVariableValue), arg0, arg1)
+}
+
+// DeleteTemplateVersionPreset mocks base method.
+func (m *MockStore) DeleteTemplateVersionPreset(arg0 context.Context, arg1 database.DeleteTemplateVersionPresetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplateVersionPreset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplateVersionPreset indicates an expected call of DeleteTemplateVersionPreset.
+func (mr *MockStoreMockRecorder) DeleteTemplateVersionPreset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplateVersionPreset", reflect.TypeOf((*MockStore)(nil).DeleteTemplateVersionPreset), arg0, arg1)
+}
+
+// DeleteTwoFactorChallengeByID mocks base method.
+func (m *MockStore) DeleteTwoFactorChallengeByID(arg0 context.Context, arg1 uuid.UUID) (database.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactorChallengeByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTwoFactorChallengeByID indicates an expected call of DeleteTwoFactorChallengeByID.
+func (mr *MockStoreMockRecorder) DeleteTwoFactorChallengeByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactorChallengeByID", reflect.TypeOf((*MockStore)(nil).DeleteTwoFactorChallengeByID), arg0, arg1)
+}
+
+// DeleteTwoFactorPolicyByRole mocks base method.
+func (m *MockStore) DeleteTwoFactorPolicyByRole(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactorPolicyByRole", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTwoFactorPolicyByRole indicates an expected call of DeleteTwoFactorPolicyByRole.
+func (mr *MockStoreMockRecorder) DeleteTwoFactorPolicyByRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactorPolicyByRole", reflect.TypeOf((*MockStore)(nil).DeleteTwoFactorPolicyByRole), arg0, arg1)
+}
+
+// DeleteUserLockout mocks base method.
+func (m *MockStore) DeleteUserLockout(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLockout", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLockout indicates an expected call of DeleteUserLockout.
+func (mr *MockStoreMockRecorder) DeleteUserLockout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLockout", reflect.TypeOf((*MockStore)(nil).DeleteUserLockout), arg0, arg1)
+}
+
+// DeleteUserRecoveryCodesByUserID mocks base method.
+func (m *MockStore) DeleteUserRecoveryCodesByUserID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRecoveryCodesByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRecoveryCodesByUserID indicates an expected call of DeleteUserRecoveryCodesByUserID.
+func (mr *MockStoreMockRecorder) DeleteUserRecoveryCodesByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRecoveryCodesByUserID", reflect.TypeOf((*MockStore)(nil).DeleteUserRecoveryCodesByUserID), arg0, arg1)
+}
+
+// DeleteWebhookByID mocks base method.
+func (m *MockStore) DeleteWebhookByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookByID indicates an expected call of DeleteWebhookByID.
+func (mr *MockStoreMockRecorder) DeleteWebhookByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookByID", reflect.TypeOf((*MockStore)(nil).DeleteWebhookByID), arg0, arg1)
+}
+
+// DeleteWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPortShare(arg0 context.Context, arg1 database.DeleteWorkspaceAgentPortShareParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPortShare indicates an expected call of DeleteWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPortShare(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPortShare), arg0, arg1)
+}
+
+// DeleteWorkspaceAgentPreviousAuthTokenByAgentID mocks base method.
+func (m *MockStore) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceAgentPreviousAuthTokenByAgentID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceAgentPreviousAuthTokenByAgentID indicates an expected call of DeleteWorkspaceAgentPreviousAuthTokenByAgentID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceAgentPreviousAuthTokenByAgentID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceAgentPreviousAuthTokenByAgentID), arg0, arg1)
+}
+
+// DeleteWorkspaceFavorite mocks base method.
+func (m *MockStore) DeleteWorkspaceFavorite(arg0 context.Context, arg1 database.DeleteWorkspaceFavoriteParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceFavorite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceFavorite indicates an expected call of DeleteWorkspaceFavorite.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceFavorite(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceFavorite", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceFavorite), arg0, arg1)
+}
+
 // GetAPIKeyByID mocks base method.
 func (m *MockStore) GetAPIKeyByID(arg0 context.Context, arg1 string) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -575,6 +1074,21 @@ func (mr *MockStoreMockRecorder) GetAPIKeysLastUsedAfter(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeysLastUsedAfter", reflect.TypeOf((*MockStore)(nil).GetAPIKeysLastUsedAfter), arg0, arg1)
 }
 
+// GetActiveSessionsByUserID mocks base method.
+func (m *MockStore) GetActiveSessionsByUserID(arg0 context.Context, arg1 database.GetActiveSessionsByUserIDParams) ([]database.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessionsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessionsByUserID indicates an expected call of GetActiveSessionsByUserID.
+func (mr *MockStoreMockRecorder) GetActiveSessionsByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessionsByUserID", reflect.TypeOf((*MockStore)(nil).GetActiveSessionsByUserID), arg0, arg1)
+}
+
 // GetActiveUserCount mocks base method.
 func (m *MockStore) GetActiveUserCount(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -695,6 +1209,21 @@ func (mr *MockStoreMockRecorder) GetApplicationName(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationName", reflect.TypeOf((*MockStore)(nil).GetApplicationName), arg0)
 }
 
+// GetAuditLogsKeyset mocks base method.
+func (m *MockStore) GetAuditLogsKeyset(arg0 context.Context, arg1 database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsKeyset", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetAuditLogsKeysetRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsKeyset indicates an expected call of GetAuditLogsKeyset.
+func (mr *MockStoreMockRecorder) GetAuditLogsKeyset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsKeyset", reflect.TypeOf((*MockStore)(nil).GetAuditLogsKeyset), arg0, arg1)
+}
+
 // GetAuditLogsOffset mocks base method.
 func (m *MockStore) GetAuditLogsOffset(arg0 context.Context, arg1 database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	m.ctrl.T.Helper()
@@ -770,6 +1299,21 @@ func (mr *MockStoreMockRecorder) GetAuthorizedWorkspaces(arg0, arg1, arg2 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorizedWorkspaces", reflect.TypeOf((*MockStore)(nil).GetAuthorizedWorkspaces), arg0, arg1, arg2)
 }
 
+// GetConnectionLogsOffset mocks base method.
+func (m *MockStore) GetConnectionLogsOffset(arg0 context.Context, arg1 database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectionLogsOffset", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetConnectionLogsOffsetRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConnectionLogsOffset indicates an expected call of GetConnectionLogsOffset.
+func (mr *MockStoreMockRecorder) GetConnectionLogsOffset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectionLogsOffset", reflect.TypeOf((*MockStore)(nil).GetConnectionLogsOffset), arg0, arg1)
+}
+
 // GetDBCryptKeys mocks base method.
 func (m *MockStore) GetDBCryptKeys(arg0 context.Context) ([]database.DBCryptKey, error) {
 	m.ctrl.T.Helper()
@@ -875,6 +1419,36 @@ func (mr *MockStoreMockRecorder) GetDeploymentWorkspaceStats(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentWorkspaceStats", reflect.TypeOf((*MockStore)(nil).GetDeploymentWorkspaceStats), arg0)
 }
 
+// GetDriftedWorkspaceCount mocks base method.
+func (m *MockStore) GetDriftedWorkspaceCount(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDriftedWorkspaceCount", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDriftedWorkspaceCount indicates an expected call of GetDriftedWorkspaceCount.
+func (mr *MockStoreMockRecorder) GetDriftedWorkspaceCount(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDriftedWorkspaceCount", reflect.TypeOf((*MockStore)(nil).GetDriftedWorkspaceCount), arg0)
+}
+
+// GetExpiredSessionRecordings mocks base method.
+func (m *MockStore) GetExpiredSessionRecordings(arg0 context.Context, arg1 database.GetExpiredSessionRecordingsParams) ([]database.SessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredSessionRecordings", arg0, arg1)
+	ret0, _ := ret[0].([]database.SessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredSessionRecordings indicates an expected call of GetExpiredSessionRecordings.
+func (mr *MockStoreMockRecorder) GetExpiredSessionRecordings(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredSessionRecordings", reflect.TypeOf((*MockStore)(nil).GetExpiredSessionRecordings), arg0, arg1)
+}
+
 // GetExternalAuthLink mocks base method.
 func (m *MockStore) GetExternalAuthLink(arg0 context.Context, arg1 database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -905,6 +1479,21 @@ func (mr *MockStoreMockRecorder) GetExternalAuthLinksByUserID(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalAuthLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetExternalAuthLinksByUserID), arg0, arg1)
 }
 
+// GetFavoriteWorkspaceIDsByUserID mocks base method.
+func (m *MockStore) GetFavoriteWorkspaceIDsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFavoriteWorkspaceIDsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFavoriteWorkspaceIDsByUserID indicates an expected call of GetFavoriteWorkspaceIDsByUserID.
+func (mr *MockStoreMockRecorder) GetFavoriteWorkspaceIDsByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteWorkspaceIDsByUserID", reflect.TypeOf((*MockStore)(nil).GetFavoriteWorkspaceIDsByUserID), arg0, arg1)
+}
+
 // GetFileByHashAndCreator mocks base method.
 func (m *MockStore) GetFileByHashAndCreator(arg0 context.Context, arg1 database.GetFileByHashAndCreatorParams) (database.File, error) {
 	m.ctrl.T.Helper()
@@ -1010,6 +1599,36 @@ func (mr *MockStoreMockRecorder) GetGroupMembers(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembers", reflect.TypeOf((*MockStore)(nil).GetGroupMembers), arg0, arg1)
 }
 
+// GetGroupSyncRuns mocks base method.
+func (m *MockStore) GetGroupSyncRuns(arg0 context.Context, arg1 database.GetGroupSyncRunsParams) ([]database.GroupSyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupSyncRuns", arg0, arg1)
+	ret0, _ := ret[0].([]database.GroupSyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupSyncRuns indicates an expected call of GetGroupSyncRuns.
+func (mr *MockStoreMockRecorder) GetGroupSyncRuns(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupSyncRuns", reflect.TypeOf((*MockStore)(nil).GetGroupSyncRuns), arg0, arg1)
+}
+
+// GetGroupsByOrganizationAndUserID mocks base method.
+func (m *MockStore) GetGroupsByOrganizationAndUserID(arg0 context.Context, arg1 database.GetGroupsByOrganizationAndUserIDParams) ([]database.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupsByOrganizationAndUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupsByOrganizationAndUserID indicates an expected call of GetGroupsByOrganizationAndUserID.
+func (mr *MockStoreMockRecorder) GetGroupsByOrganizationAndUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupsByOrganizationAndUserID", reflect.TypeOf((*MockStore)(nil).GetGroupsByOrganizationAndUserID), arg0, arg1)
+}
+
 // GetGroupsByOrganizationID mocks base method.
 func (m *MockStore) GetGroupsByOrganizationID(arg0 context.Context, arg1 uuid.UUID) ([]database.Group, error) {
 	m.ctrl.T.Helper()
@@ -1055,6 +1674,36 @@ func (mr *MockStoreMockRecorder) GetHungProvisionerJobs(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHungProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetHungProvisionerJobs), arg0, arg1)
 }
 
+// GetInactiveUsers mocks base method.
+func (m *MockStore) GetInactiveUsers(arg0 context.Context, arg1 time.Time) ([]database.GetInactiveUsersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInactiveUsers", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetInactiveUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInactiveUsers indicates an expected call of GetInactiveUsers.
+func (mr *MockStoreMockRecorder) GetInactiveUsers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInactiveUsers", reflect.TypeOf((*MockStore)(nil).GetInactiveUsers), arg0, arg1)
+}
+
+// GetInboxNotificationsByUserID mocks base method.
+func (m *MockStore) GetInboxNotificationsByUserID(arg0 context.Context, arg1 database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboxNotificationsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.InboxNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboxNotificationsByUserID indicates an expected call of GetInboxNotificationsByUserID.
+func (mr *MockStoreMockRecorder) GetInboxNotificationsByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboxNotificationsByUserID", reflect.TypeOf((*MockStore)(nil).GetInboxNotificationsByUserID), arg0, arg1)
+}
+
 // GetLastUpdateCheck mocks base method.
 func (m *MockStore) GetLastUpdateCheck(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1070,6 +1719,21 @@ func (mr *MockStoreMockRecorder) GetLastUpdateCheck(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).GetLastUpdateCheck), arg0)
 }
 
+// GetLatestWorkspaceAgentStatByAgentID mocks base method.
+func (m *MockStore) GetLatestWorkspaceAgentStatByAgentID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceAgentStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceAgentStatByAgentID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceAgentStatByAgentID indicates an expected call of GetLatestWorkspaceAgentStatByAgentID.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceAgentStatByAgentID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceAgentStatByAgentID", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceAgentStatByAgentID), arg0, arg1)
+}
+
 // GetLatestWorkspaceBuildByWorkspaceID mocks base method.
 func (m *MockStore) GetLatestWorkspaceBuildByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -1130,6 +1794,21 @@ func (mr *MockStoreMockRecorder) GetLicenseByID(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseByID", reflect.TypeOf((*MockStore)(nil).GetLicenseByID), arg0, arg1)
 }
 
+// GetLicenseGracePeriodStartedAt mocks base method.
+func (m *MockStore) GetLicenseGracePeriodStartedAt(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenseGracePeriodStartedAt", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenseGracePeriodStartedAt indicates an expected call of GetLicenseGracePeriodStartedAt.
+func (mr *MockStoreMockRecorder) GetLicenseGracePeriodStartedAt(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseGracePeriodStartedAt", reflect.TypeOf((*MockStore)(nil).GetLicenseGracePeriodStartedAt), arg0)
+}
+
 // GetLicenses mocks base method.
 func (m *MockStore) GetLicenses(arg0 context.Context) ([]database.License, error) {
 	m.ctrl.T.Helper()
@@ -1145,6 +1824,21 @@ func (mr *MockStoreMockRecorder) GetLicenses(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenses", reflect.TypeOf((*MockStore)(nil).GetLicenses), arg0)
 }
 
+// GetLoginFailure mocks base method.
+func (m *MockStore) GetLoginFailure(arg0 context.Context, arg1 database.GetLoginFailureParams) (database.LoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(database.LoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginFailure indicates an expected call of GetLoginFailure.
+func (mr *MockStoreMockRecorder) GetLoginFailure(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginFailure", reflect.TypeOf((*MockStore)(nil).GetLoginFailure), arg0, arg1)
+}
+
 // GetLogoURL mocks base method.
 func (m *MockStore) GetLogoURL(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1160,6 +1854,36 @@ func (mr *MockStoreMockRecorder) GetLogoURL(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
 }
 
+// GetNotificationMessagesByUserID mocks base method.
+func (m *MockStore) GetNotificationMessagesByUserID(arg0 context.Context, arg1 database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationMessagesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationMessagesByUserID indicates an expected call of GetNotificationMessagesByUserID.
+func (mr *MockStoreMockRecorder) GetNotificationMessagesByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationMessagesByUserID", reflect.TypeOf((*MockStore)(nil).GetNotificationMessagesByUserID), arg0, arg1)
+}
+
+// GetNotificationPreferencesByUserID mocks base method.
+func (m *MockStore) GetNotificationPreferencesByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferencesByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferencesByUserID indicates an expected call of GetNotificationPreferencesByUserID.
+func (mr *MockStoreMockRecorder) GetNotificationPreferencesByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferencesByUserID", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferencesByUserID), arg0, arg1)
+}
+
 // GetOAuth2ProviderAppByID mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
@@ -1175,6 +1899,36 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppByID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppByID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppByID), arg0, arg1)
 }
 
+// GetOAuth2ProviderAppCodeByID mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppCodeByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppCodeByID indicates an expected call of GetOAuth2ProviderAppCodeByID.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByID), arg0, arg1)
+}
+
+// GetOAuth2ProviderAppCodeByPrefix mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppCodeByPrefix(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppCodeByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppCodeByPrefix indicates an expected call of GetOAuth2ProviderAppCodeByPrefix.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppCodeByPrefix(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppCodeByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppCodeByPrefix), arg0, arg1)
+}
+
 // GetOAuth2ProviderAppSecretByID mocks base method.
 func (m *MockStore) GetOAuth2ProviderAppSecretByID(arg0 context.Context, arg1 uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -1205,6 +1959,21 @@ func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppSecretsByAppID(arg0, arg1 a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppSecretsByAppID", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppSecretsByAppID), arg0, arg1)
 }
 
+// GetOAuth2ProviderAppTokenByPrefix mocks base method.
+func (m *MockStore) GetOAuth2ProviderAppTokenByPrefix(arg0 context.Context, arg1 []byte) (database.OAuth2ProviderAppToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuth2ProviderAppTokenByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuth2ProviderAppTokenByPrefix indicates an expected call of GetOAuth2ProviderAppTokenByPrefix.
+func (mr *MockStoreMockRecorder) GetOAuth2ProviderAppTokenByPrefix(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuth2ProviderAppTokenByPrefix", reflect.TypeOf((*MockStore)(nil).GetOAuth2ProviderAppTokenByPrefix), arg0, arg1)
+}
+
 // GetOAuth2ProviderApps mocks base method.
 func (m *MockStore) GetOAuth2ProviderApps(arg0 context.Context) ([]database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
@@ -1265,49 +2034,109 @@ func (mr *MockStoreMockRecorder) GetOrganizationByName(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationByName", reflect.TypeOf((*MockStore)(nil).GetOrganizationByName), arg0, arg1)
 }
 
-// GetOrganizationIDsByMemberIDs mocks base method.
-func (m *MockStore) GetOrganizationIDsByMemberIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetOrganizationIDsByMemberIDsRow, error) {
+// GetOrganizationIDsByMemberIDs mocks base method.
+func (m *MockStore) GetOrganizationIDsByMemberIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetOrganizationIDsByMemberIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationIDsByMemberIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetOrganizationIDsByMemberIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationIDsByMemberIDs indicates an expected call of GetOrganizationIDsByMemberIDs.
+func (mr *MockStoreMockRecorder) GetOrganizationIDsByMemberIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationIDsByMemberIDs", reflect.TypeOf((*MockStore)(nil).GetOrganizationIDsByMemberIDs), arg0, arg1)
+}
+
+// GetOrganizationMemberByUserID mocks base method.
+func (m *MockStore) GetOrganizationMemberByUserID(arg0 context.Context, arg1 database.GetOrganizationMemberByUserIDParams) (database.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMemberByUserID", arg0, arg1)
+	ret0, _ := ret[0].(database.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMemberByUserID indicates an expected call of GetOrganizationMemberByUserID.
+func (mr *MockStoreMockRecorder) GetOrganizationMemberByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationMemberByUserID), arg0, arg1)
+}
+
+// GetOrganizationMembers mocks base method.
+func (m *MockStore) GetOrganizationMembers(arg0 context.Context, arg1 uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMembers", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetOrganizationMembersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMembers indicates an expected call of GetOrganizationMembers.
+func (mr *MockStoreMockRecorder) GetOrganizationMembers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMembers", reflect.TypeOf((*MockStore)(nil).GetOrganizationMembers), arg0, arg1)
+}
+
+// GetOrganizationMembershipsByUserID mocks base method.
+func (m *MockStore) GetOrganizationMembershipsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationMembershipsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationMembershipsByUserID indicates an expected call of GetOrganizationMembershipsByUserID.
+func (mr *MockStoreMockRecorder) GetOrganizationMembershipsByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMembershipsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationMembershipsByUserID), arg0, arg1)
+}
+
+// GetOrganizationQuota mocks base method.
+func (m *MockStore) GetOrganizationQuota(arg0 context.Context, arg1 uuid.UUID) (database.OrganizationQuota, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationIDsByMemberIDs", arg0, arg1)
-	ret0, _ := ret[0].([]database.GetOrganizationIDsByMemberIDsRow)
+	ret := m.ctrl.Call(m, "GetOrganizationQuota", arg0, arg1)
+	ret0, _ := ret[0].(database.OrganizationQuota)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationIDsByMemberIDs indicates an expected call of GetOrganizationIDsByMemberIDs.
-func (mr *MockStoreMockRecorder) GetOrganizationIDsByMemberIDs(arg0, arg1 any) *gomock.Call {
+// GetOrganizationQuota indicates an expected call of GetOrganizationQuota.
+func (mr *MockStoreMockRecorder) GetOrganizationQuota(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationIDsByMemberIDs", reflect.TypeOf((*MockStore)(nil).GetOrganizationIDsByMemberIDs), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationQuota", reflect.TypeOf((*MockStore)(nil).GetOrganizationQuota), arg0, arg1)
 }
 
-// GetOrganizationMemberByUserID mocks base method.
-func (m *MockStore) GetOrganizationMemberByUserID(arg0 context.Context, arg1 database.GetOrganizationMemberByUserIDParams) (database.OrganizationMember, error) {
+// GetOrganizationResourceCostRollup mocks base method.
+func (m *MockStore) GetOrganizationResourceCostRollup(arg0 context.Context, arg1 database.GetOrganizationResourceCostRollupParams) ([]database.GetOrganizationResourceCostRollupRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationMemberByUserID", arg0, arg1)
-	ret0, _ := ret[0].(database.OrganizationMember)
+	ret := m.ctrl.Call(m, "GetOrganizationResourceCostRollup", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetOrganizationResourceCostRollupRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationMemberByUserID indicates an expected call of GetOrganizationMemberByUserID.
-func (mr *MockStoreMockRecorder) GetOrganizationMemberByUserID(arg0, arg1 any) *gomock.Call {
+// GetOrganizationResourceCostRollup indicates an expected call of GetOrganizationResourceCostRollup.
+func (mr *MockStoreMockRecorder) GetOrganizationResourceCostRollup(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMemberByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationMemberByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationResourceCostRollup", reflect.TypeOf((*MockStore)(nil).GetOrganizationResourceCostRollup), arg0, arg1)
 }
 
-// GetOrganizationMembershipsByUserID mocks base method.
-func (m *MockStore) GetOrganizationMembershipsByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.OrganizationMember, error) {
+// GetOrganizationTemplateVariableValues mocks base method.
+func (m *MockStore) GetOrganizationTemplateVariableValues(arg0 context.Context, arg1 uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrganizationMembershipsByUserID", arg0, arg1)
-	ret0, _ := ret[0].([]database.OrganizationMember)
+	ret := m.ctrl.Call(m, "GetOrganizationTemplateVariableValues", arg0, arg1)
+	ret0, _ := ret[0].([]database.OrganizationTemplateVariableValue)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrganizationMembershipsByUserID indicates an expected call of GetOrganizationMembershipsByUserID.
-func (mr *MockStoreMockRecorder) GetOrganizationMembershipsByUserID(arg0, arg1 any) *gomock.Call {
+// GetOrganizationTemplateVariableValues indicates an expected call of GetOrganizationTemplateVariableValues.
+func (mr *MockStoreMockRecorder) GetOrganizationTemplateVariableValues(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationMembershipsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationMembershipsByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationTemplateVariableValues", reflect.TypeOf((*MockStore)(nil).GetOrganizationTemplateVariableValues), arg0, arg1)
 }
 
 // GetOrganizations mocks base method.
@@ -1340,6 +2169,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationsByUserID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationsByUserID), arg0, arg1)
 }
 
+// GetOrphanedProvisionerJobs mocks base method.
+func (m *MockStore) GetOrphanedProvisionerJobs(arg0 context.Context, arg1 time.Time) ([]database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrphanedProvisionerJobs", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrphanedProvisionerJobs indicates an expected call of GetOrphanedProvisionerJobs.
+func (mr *MockStoreMockRecorder) GetOrphanedProvisionerJobs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrphanedProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetOrphanedProvisionerJobs), arg0, arg1)
+}
+
 // GetParameterSchemasByJobID mocks base method.
 func (m *MockStore) GetParameterSchemasByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.ParameterSchema, error) {
 	m.ctrl.T.Helper()
@@ -1355,6 +2199,36 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), arg0, arg1)
 }
 
+// GetPasskeyByID mocks base method.
+func (m *MockStore) GetPasskeyByID(arg0 context.Context, arg1 uuid.UUID) (database.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasskeyByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasskeyByID indicates an expected call of GetPasskeyByID.
+func (mr *MockStoreMockRecorder) GetPasskeyByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasskeyByID", reflect.TypeOf((*MockStore)(nil).GetPasskeyByID), arg0, arg1)
+}
+
+// GetPasskeysByUserID mocks base method.
+func (m *MockStore) GetPasskeysByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasskeysByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasskeysByUserID indicates an expected call of GetPasskeysByUserID.
+func (mr *MockStoreMockRecorder) GetPasskeysByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasskeysByUserID", reflect.TypeOf((*MockStore)(nil).GetPasskeysByUserID), arg0, arg1)
+}
+
 // GetPreviousTemplateVersion mocks base method.
 func (m *MockStore) GetPreviousTemplateVersion(arg0 context.Context, arg1 database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -1385,6 +2259,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerDaemons(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).GetProvisionerDaemons), arg0)
 }
 
+// GetProvisionerDaemonsByOrganization mocks base method.
+func (m *MockStore) GetProvisionerDaemonsByOrganization(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerDaemonsByOrganization", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerDaemon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerDaemonsByOrganization indicates an expected call of GetProvisionerDaemonsByOrganization.
+func (mr *MockStoreMockRecorder) GetProvisionerDaemonsByOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerDaemonsByOrganization", reflect.TypeOf((*MockStore)(nil).GetProvisionerDaemonsByOrganization), arg0, arg1)
+}
+
 // GetProvisionerJobByID mocks base method.
 func (m *MockStore) GetProvisionerJobByID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -1400,6 +2289,51 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByID(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByID), arg0, arg1)
 }
 
+// GetProvisionerJobIDsToArchiveLogs mocks base method.
+func (m *MockStore) GetProvisionerJobIDsToArchiveLogs(arg0 context.Context, arg1 database.GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobIDsToArchiveLogs", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobIDsToArchiveLogs indicates an expected call of GetProvisionerJobIDsToArchiveLogs.
+func (mr *MockStoreMockRecorder) GetProvisionerJobIDsToArchiveLogs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobIDsToArchiveLogs", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobIDsToArchiveLogs), arg0, arg1)
+}
+
+// GetProvisionerJobLogArchiveByJobID mocks base method.
+func (m *MockStore) GetProvisionerJobLogArchiveByJobID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobLogArchiveByJobID", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobLogArchiveByJobID indicates an expected call of GetProvisionerJobLogArchiveByJobID.
+func (mr *MockStoreMockRecorder) GetProvisionerJobLogArchiveByJobID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobLogArchiveByJobID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobLogArchiveByJobID), arg0, arg1)
+}
+
+// GetProvisionerJobLogArchiveStats mocks base method.
+func (m *MockStore) GetProvisionerJobLogArchiveStats(arg0 context.Context) (database.GetProvisionerJobLogArchiveStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobLogArchiveStats", arg0)
+	ret0, _ := ret[0].(database.GetProvisionerJobLogArchiveStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobLogArchiveStats indicates an expected call of GetProvisionerJobLogArchiveStats.
+func (mr *MockStoreMockRecorder) GetProvisionerJobLogArchiveStats(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobLogArchiveStats", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobLogArchiveStats), arg0)
+}
+
 // GetProvisionerJobsByIDs mocks base method.
 func (m *MockStore) GetProvisionerJobsByIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -1445,6 +2379,51 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobsCreatedAfter(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsCreatedAfter), arg0, arg1)
 }
 
+// GetProvisionerKeyByHashedSecret mocks base method.
+func (m *MockStore) GetProvisionerKeyByHashedSecret(arg0 context.Context, arg1 []byte) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerKeyByHashedSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerKeyByHashedSecret indicates an expected call of GetProvisionerKeyByHashedSecret.
+func (mr *MockStoreMockRecorder) GetProvisionerKeyByHashedSecret(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerKeyByHashedSecret", reflect.TypeOf((*MockStore)(nil).GetProvisionerKeyByHashedSecret), arg0, arg1)
+}
+
+// GetProvisionerKeyByID mocks base method.
+func (m *MockStore) GetProvisionerKeyByID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerKeyByID", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerKeyByID indicates an expected call of GetProvisionerKeyByID.
+func (mr *MockStoreMockRecorder) GetProvisionerKeyByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerKeyByID", reflect.TypeOf((*MockStore)(nil).GetProvisionerKeyByID), arg0, arg1)
+}
+
+// GetProvisionerKeyByName mocks base method.
+func (m *MockStore) GetProvisionerKeyByName(arg0 context.Context, arg1 database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerKeyByName", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerKeyByName indicates an expected call of GetProvisionerKeyByName.
+func (mr *MockStoreMockRecorder) GetProvisionerKeyByName(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerKeyByName", reflect.TypeOf((*MockStore)(nil).GetProvisionerKeyByName), arg0, arg1)
+}
+
 // GetProvisionerLogsAfterID mocks base method.
 func (m *MockStore) GetProvisionerLogsAfterID(arg0 context.Context, arg1 database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -1475,6 +2454,21 @@ func (mr *MockStoreMockRecorder) GetQuotaAllowanceForUser(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaAllowanceForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaAllowanceForUser), arg0, arg1)
 }
 
+// GetQuotaConsumedForOrganization mocks base method.
+func (m *MockStore) GetQuotaConsumedForOrganization(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaConsumedForOrganization", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaConsumedForOrganization indicates an expected call of GetQuotaConsumedForOrganization.
+func (mr *MockStoreMockRecorder) GetQuotaConsumedForOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConsumedForOrganization", reflect.TypeOf((*MockStore)(nil).GetQuotaConsumedForOrganization), arg0, arg1)
+}
+
 // GetQuotaConsumedForUser mocks base method.
 func (m *MockStore) GetQuotaConsumedForUser(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1520,6 +2514,21 @@ func (mr *MockStoreMockRecorder) GetReplicasUpdatedAfter(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicasUpdatedAfter", reflect.TypeOf((*MockStore)(nil).GetReplicasUpdatedAfter), arg0, arg1)
 }
 
+// GetRunningWorkspaceCounts mocks base method.
+func (m *MockStore) GetRunningWorkspaceCounts(arg0 context.Context, arg1 database.GetRunningWorkspaceCountsParams) (database.GetRunningWorkspaceCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunningWorkspaceCounts", arg0, arg1)
+	ret0, _ := ret[0].(database.GetRunningWorkspaceCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunningWorkspaceCounts indicates an expected call of GetRunningWorkspaceCounts.
+func (mr *MockStoreMockRecorder) GetRunningWorkspaceCounts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningWorkspaceCounts", reflect.TypeOf((*MockStore)(nil).GetRunningWorkspaceCounts), arg0, arg1)
+}
+
 // GetServiceBanner mocks base method.
 func (m *MockStore) GetServiceBanner(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1535,6 +2544,36 @@ func (mr *MockStoreMockRecorder) GetServiceBanner(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceBanner", reflect.TypeOf((*MockStore)(nil).GetServiceBanner), arg0)
 }
 
+// GetSessionRecordingByID mocks base method.
+func (m *MockStore) GetSessionRecordingByID(arg0 context.Context, arg1 uuid.UUID) (database.SessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(database.SessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionRecordingByID indicates an expected call of GetSessionRecordingByID.
+func (mr *MockStoreMockRecorder) GetSessionRecordingByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).GetSessionRecordingByID), arg0, arg1)
+}
+
+// GetSessionRecordingsByWorkspaceID mocks base method.
+func (m *MockStore) GetSessionRecordingsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.SessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionRecordingsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.SessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionRecordingsByWorkspaceID indicates an expected call of GetSessionRecordingsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetSessionRecordingsByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionRecordingsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetSessionRecordingsByWorkspaceID), arg0, arg1)
+}
+
 // GetTailnetAgents mocks base method.
 func (m *MockStore) GetTailnetAgents(arg0 context.Context, arg1 uuid.UUID) ([]database.TailnetAgent, error) {
 	m.ctrl.T.Helper()
@@ -1655,6 +2694,21 @@ func (mr *MockStoreMockRecorder) GetTemplateAverageBuildTime(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAverageBuildTime", reflect.TypeOf((*MockStore)(nil).GetTemplateAverageBuildTime), arg0, arg1)
 }
 
+// GetTemplateBuildInsights mocks base method.
+func (m *MockStore) GetTemplateBuildInsights(arg0 context.Context, arg1 database.GetTemplateBuildInsightsParams) (database.GetTemplateBuildInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildInsights", arg0, arg1)
+	ret0, _ := ret[0].(database.GetTemplateBuildInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildInsights indicates an expected call of GetTemplateBuildInsights.
+func (mr *MockStoreMockRecorder) GetTemplateBuildInsights(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildInsights), arg0, arg1)
+}
+
 // GetTemplateByID mocks base method.
 func (m *MockStore) GetTemplateByID(arg0 context.Context, arg1 uuid.UUID) (database.Template, error) {
 	m.ctrl.T.Helper()
@@ -1790,6 +2844,21 @@ func (mr *MockStoreMockRecorder) GetTemplateUserRoles(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateUserRoles), arg0, arg1)
 }
 
+// GetTemplateVariableValuesByTemplateID mocks base method.
+func (m *MockStore) GetTemplateVariableValuesByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVariableValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVariableValuesByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVariableValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVariableValuesByTemplateID indicates an expected call of GetTemplateVariableValuesByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateVariableValuesByTemplateID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVariableValuesByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateVariableValuesByTemplateID), arg0, arg1)
+}
+
 // GetTemplateVersionByID mocks base method.
 func (m *MockStore) GetTemplateVersionByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -1850,6 +2919,21 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), arg0, arg1)
 }
 
+// GetTemplateVersionPresets mocks base method.
+func (m *MockStore) GetTemplateVersionPresets(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPresets", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPresets indicates an expected call of GetTemplateVersionPresets.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPresets(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPresets", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPresets), arg0, arg1)
+}
+
 // GetTemplateVersionVariables mocks base method.
 func (m *MockStore) GetTemplateVersionVariables(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -1940,6 +3024,51 @@ func (mr *MockStoreMockRecorder) GetTemplatesWithFilter(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplatesWithFilter", reflect.TypeOf((*MockStore)(nil).GetTemplatesWithFilter), arg0, arg1)
 }
 
+// GetTimedOutProvisionerJobs mocks base method.
+func (m *MockStore) GetTimedOutProvisionerJobs(arg0 context.Context, arg1 time.Time) ([]database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTimedOutProvisionerJobs", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimedOutProvisionerJobs indicates an expected call of GetTimedOutProvisionerJobs.
+func (mr *MockStoreMockRecorder) GetTimedOutProvisionerJobs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimedOutProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetTimedOutProvisionerJobs), arg0, arg1)
+}
+
+// GetTwoFactorPolicies mocks base method.
+func (m *MockStore) GetTwoFactorPolicies(arg0 context.Context) ([]database.TwoFactorPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactorPolicies", arg0)
+	ret0, _ := ret[0].([]database.TwoFactorPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactorPolicies indicates an expected call of GetTwoFactorPolicies.
+func (mr *MockStoreMockRecorder) GetTwoFactorPolicies(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactorPolicies", reflect.TypeOf((*MockStore)(nil).GetTwoFactorPolicies), arg0)
+}
+
+// GetTwoFactorPolicyByRole mocks base method.
+func (m *MockStore) GetTwoFactorPolicyByRole(arg0 context.Context, arg1 string) (database.TwoFactorPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactorPolicyByRole", arg0, arg1)
+	ret0, _ := ret[0].(database.TwoFactorPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactorPolicyByRole indicates an expected call of GetTwoFactorPolicyByRole.
+func (mr *MockStoreMockRecorder) GetTwoFactorPolicyByRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactorPolicyByRole", reflect.TypeOf((*MockStore)(nil).GetTwoFactorPolicyByRole), arg0, arg1)
+}
+
 // GetUnexpiredLicenses mocks base method.
 func (m *MockStore) GetUnexpiredLicenses(arg0 context.Context) ([]database.License, error) {
 	m.ctrl.T.Helper()
@@ -1955,6 +3084,51 @@ func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), arg0)
 }
 
+// GetUnknownLoginFailure mocks base method.
+func (m *MockStore) GetUnknownLoginFailure(arg0 context.Context, arg1 database.GetUnknownLoginFailureParams) (database.UnknownLoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnknownLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(database.UnknownLoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnknownLoginFailure indicates an expected call of GetUnknownLoginFailure.
+func (mr *MockStoreMockRecorder) GetUnknownLoginFailure(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnknownLoginFailure", reflect.TypeOf((*MockStore)(nil).GetUnknownLoginFailure), arg0, arg1)
+}
+
+// GetUnknownLoginFailureTotals mocks base method.
+func (m *MockStore) GetUnknownLoginFailureTotals(arg0 context.Context, arg1 database.GetUnknownLoginFailureTotalsParams) (database.GetUnknownLoginFailureTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnknownLoginFailureTotals", arg0, arg1)
+	ret0, _ := ret[0].(database.GetUnknownLoginFailureTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnknownLoginFailureTotals indicates an expected call of GetUnknownLoginFailureTotals.
+func (mr *MockStoreMockRecorder) GetUnknownLoginFailureTotals(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnknownLoginFailureTotals", reflect.TypeOf((*MockStore)(nil).GetUnknownLoginFailureTotals), arg0, arg1)
+}
+
+// GetUnusedUserRecoveryCodes mocks base method.
+func (m *MockStore) GetUnusedUserRecoveryCodes(arg0 context.Context, arg1 uuid.UUID) ([]database.UserRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedUserRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]database.UserRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnusedUserRecoveryCodes indicates an expected call of GetUnusedUserRecoveryCodes.
+func (mr *MockStoreMockRecorder) GetUnusedUserRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedUserRecoveryCodes", reflect.TypeOf((*MockStore)(nil).GetUnusedUserRecoveryCodes), arg0, arg1)
+}
+
 // GetUserActivityInsights mocks base method.
 func (m *MockStore) GetUserActivityInsights(arg0 context.Context, arg1 database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -2030,49 +3204,94 @@ func (mr *MockStoreMockRecorder) GetUserLatencyInsights(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLatencyInsights", reflect.TypeOf((*MockStore)(nil).GetUserLatencyInsights), arg0, arg1)
 }
 
-// GetUserLinkByLinkedID mocks base method.
-func (m *MockStore) GetUserLinkByLinkedID(arg0 context.Context, arg1 string) (database.UserLink, error) {
+// GetUserLinkByLinkedID mocks base method.
+func (m *MockStore) GetUserLinkByLinkedID(arg0 context.Context, arg1 string) (database.UserLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLinkByLinkedID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLinkByLinkedID indicates an expected call of GetUserLinkByLinkedID.
+func (mr *MockStoreMockRecorder) GetUserLinkByLinkedID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByLinkedID", reflect.TypeOf((*MockStore)(nil).GetUserLinkByLinkedID), arg0, arg1)
+}
+
+// GetUserLinkByUserIDLoginType mocks base method.
+func (m *MockStore) GetUserLinkByUserIDLoginType(arg0 context.Context, arg1 database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLinkByUserIDLoginType", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLinkByUserIDLoginType indicates an expected call of GetUserLinkByUserIDLoginType.
+func (mr *MockStoreMockRecorder) GetUserLinkByUserIDLoginType(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), arg0, arg1)
+}
+
+// GetUserLinksByUserID mocks base method.
+func (m *MockStore) GetUserLinksByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.UserLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLinksByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.UserLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLinksByUserID indicates an expected call of GetUserLinksByUserID.
+func (mr *MockStoreMockRecorder) GetUserLinksByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), arg0, arg1)
+}
+
+// GetUserLockoutByUserID mocks base method.
+func (m *MockStore) GetUserLockoutByUserID(arg0 context.Context, arg1 uuid.UUID) (database.UserLockout, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserLinkByLinkedID", arg0, arg1)
-	ret0, _ := ret[0].(database.UserLink)
+	ret := m.ctrl.Call(m, "GetUserLockoutByUserID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLockout)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserLinkByLinkedID indicates an expected call of GetUserLinkByLinkedID.
-func (mr *MockStoreMockRecorder) GetUserLinkByLinkedID(arg0, arg1 any) *gomock.Call {
+// GetUserLockoutByUserID indicates an expected call of GetUserLockoutByUserID.
+func (mr *MockStoreMockRecorder) GetUserLockoutByUserID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByLinkedID", reflect.TypeOf((*MockStore)(nil).GetUserLinkByLinkedID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLockoutByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLockoutByUserID), arg0, arg1)
 }
 
-// GetUserLinkByUserIDLoginType mocks base method.
-func (m *MockStore) GetUserLinkByUserIDLoginType(arg0 context.Context, arg1 database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
+// GetUserLockouts mocks base method.
+func (m *MockStore) GetUserLockouts(arg0 context.Context, arg1 time.Time) ([]database.GetUserLockoutsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserLinkByUserIDLoginType", arg0, arg1)
-	ret0, _ := ret[0].(database.UserLink)
+	ret := m.ctrl.Call(m, "GetUserLockouts", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetUserLockoutsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserLinkByUserIDLoginType indicates an expected call of GetUserLinkByUserIDLoginType.
-func (mr *MockStoreMockRecorder) GetUserLinkByUserIDLoginType(arg0, arg1 any) *gomock.Call {
+// GetUserLockouts indicates an expected call of GetUserLockouts.
+func (mr *MockStoreMockRecorder) GetUserLockouts(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLockouts", reflect.TypeOf((*MockStore)(nil).GetUserLockouts), arg0, arg1)
 }
 
-// GetUserLinksByUserID mocks base method.
-func (m *MockStore) GetUserLinksByUserID(arg0 context.Context, arg1 uuid.UUID) ([]database.UserLink, error) {
+// GetUserSCIMExternalIDByExternalID mocks base method.
+func (m *MockStore) GetUserSCIMExternalIDByExternalID(arg0 context.Context, arg1 string) (database.UserSCIMExternalID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserLinksByUserID", arg0, arg1)
-	ret0, _ := ret[0].([]database.UserLink)
+	ret := m.ctrl.Call(m, "GetUserSCIMExternalIDByExternalID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserSCIMExternalID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserLinksByUserID indicates an expected call of GetUserLinksByUserID.
-func (mr *MockStoreMockRecorder) GetUserLinksByUserID(arg0, arg1 any) *gomock.Call {
+// GetUserSCIMExternalIDByExternalID indicates an expected call of GetUserSCIMExternalIDByExternalID.
+func (mr *MockStoreMockRecorder) GetUserSCIMExternalIDByExternalID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinksByUserID", reflect.TypeOf((*MockStore)(nil).GetUserLinksByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSCIMExternalIDByExternalID", reflect.TypeOf((*MockStore)(nil).GetUserSCIMExternalIDByExternalID), arg0, arg1)
 }
 
 // GetUsers mocks base method.
@@ -2105,6 +3324,81 @@ func (mr *MockStoreMockRecorder) GetUsersByIDs(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockStore)(nil).GetUsersByIDs), arg0, arg1)
 }
 
+// GetWebhookByID mocks base method.
+func (m *MockStore) GetWebhookByID(arg0 context.Context, arg1 uuid.UUID) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockStoreMockRecorder) GetWebhookByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockStore)(nil).GetWebhookByID), arg0, arg1)
+}
+
+// GetWebhookDeliveriesByWebhookID mocks base method.
+func (m *MockStore) GetWebhookDeliveriesByWebhookID(arg0 context.Context, arg1 database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesByWebhookID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesByWebhookID indicates an expected call of GetWebhookDeliveriesByWebhookID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveriesByWebhookID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByWebhookID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveriesByWebhookID), arg0, arg1)
+}
+
+// GetWebhookDeliveryByID mocks base method.
+func (m *MockStore) GetWebhookDeliveryByID(arg0 context.Context, arg1 uuid.UUID) (database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryByID indicates an expected call of GetWebhookDeliveryByID.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveryByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryByID", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveryByID), arg0, arg1)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks(arg0 context.Context) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", arg0)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks), arg0)
+}
+
+// GetWebhooksByEvent mocks base method.
+func (m *MockStore) GetWebhooksByEvent(arg0 context.Context, arg1 string) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksByEvent", arg0, arg1)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksByEvent indicates an expected call of GetWebhooksByEvent.
+func (mr *MockStoreMockRecorder) GetWebhooksByEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByEvent", reflect.TypeOf((*MockStore)(nil).GetWebhooksByEvent), arg0, arg1)
+}
+
 // GetWorkspaceAgentAndOwnerByAuthToken mocks base method.
 func (m *MockStore) GetWorkspaceAgentAndOwnerByAuthToken(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
 	m.ctrl.T.Helper()
@@ -2120,6 +3414,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentAndOwnerByAuthToken(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentAndOwnerByAuthToken", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentAndOwnerByAuthToken), arg0, arg1)
 }
 
+// GetWorkspaceAgentAuthTokenByIDForUpdate mocks base method.
+func (m *MockStore) GetWorkspaceAgentAuthTokenByIDForUpdate(arg0 context.Context, arg1 uuid.UUID) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentAuthTokenByIDForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentAuthTokenByIDForUpdate indicates an expected call of GetWorkspaceAgentAuthTokenByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentAuthTokenByIDForUpdate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentAuthTokenByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentAuthTokenByIDForUpdate), arg0, arg1)
+}
+
 // GetWorkspaceAgentByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceAgent, error) {
 	m.ctrl.T.Helper()
@@ -2150,6 +3459,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentByInstanceID(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentByInstanceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentByInstanceID), arg0, arg1)
 }
 
+// GetWorkspaceAgentCountsByLifecycleState mocks base method.
+func (m *MockStore) GetWorkspaceAgentCountsByLifecycleState(arg0 context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentCountsByLifecycleState", arg0)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentCountsByLifecycleStateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentCountsByLifecycleState indicates an expected call of GetWorkspaceAgentCountsByLifecycleState.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentCountsByLifecycleState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentCountsByLifecycleState", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentCountsByLifecycleState), arg0)
+}
+
+// GetWorkspaceAgentGPUsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentGPUsByAgentIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceAgentGPU, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentGPUsByAgentIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentGPU)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentGPUsByAgentIDs indicates an expected call of GetWorkspaceAgentGPUsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentGPUsByAgentIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentGPUsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentGPUsByAgentIDs), arg0, arg1)
+}
+
 // GetWorkspaceAgentLifecycleStateByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentLifecycleStateByID(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -2210,6 +3549,81 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadata(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// GetWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) GetWorkspaceAgentMetadataHistory(arg0 context.Context, arg1 database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentMetadataHistory", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentMetadataHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentMetadataHistory indicates an expected call of GetWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadataHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadataHistory), arg0, arg1)
+}
+
+// GetWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) GetWorkspaceAgentPortShare(arg0 context.Context, arg1 database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPortShare indicates an expected call of GetWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortShare(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortShare), arg0, arg1)
+}
+
+// GetWorkspaceAgentPortSharesByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceAgentPortSharesByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPortSharesByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPortSharesByWorkspaceID indicates an expected call of GetWorkspaceAgentPortSharesByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortSharesByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortSharesByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortSharesByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceAgentPortsByAgentIDs mocks base method.
+func (m *MockStore) GetWorkspaceAgentPortsByAgentIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceAgentPort, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentPortsByAgentIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentPort)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentPortsByAgentIDs indicates an expected call of GetWorkspaceAgentPortsByAgentIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentPortsByAgentIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentPortsByAgentIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentPortsByAgentIDs), arg0, arg1)
+}
+
+// GetWorkspaceAgentResourceUsageAndLabels mocks base method.
+func (m *MockStore) GetWorkspaceAgentResourceUsageAndLabels(arg0 context.Context, arg1 time.Time) ([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentResourceUsageAndLabels", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentResourceUsageAndLabelsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentResourceUsageAndLabels indicates an expected call of GetWorkspaceAgentResourceUsageAndLabels.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentResourceUsageAndLabels(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentResourceUsageAndLabels", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentResourceUsageAndLabels), arg0, arg1)
+}
+
 // GetWorkspaceAgentScriptsByAgentIDs mocks base method.
 func (m *MockStore) GetWorkspaceAgentScriptsByAgentIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
 	m.ctrl.T.Helper()
@@ -2315,6 +3729,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppByAgentIDAndSlug(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppByAgentIDAndSlug", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppByAgentIDAndSlug), arg0, arg1)
 }
 
+// GetWorkspaceAppCountsByHealth mocks base method.
+func (m *MockStore) GetWorkspaceAppCountsByHealth(arg0 context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAppCountsByHealth", arg0)
+	ret0, _ := ret[0].([]database.GetWorkspaceAppCountsByHealthRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAppCountsByHealth indicates an expected call of GetWorkspaceAppCountsByHealth.
+func (mr *MockStoreMockRecorder) GetWorkspaceAppCountsByHealth(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppCountsByHealth", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppCountsByHealth), arg0)
+}
+
 // GetWorkspaceAppsByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceAppsByAgentID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceApp, error) {
 	m.ctrl.T.Helper()
@@ -2510,6 +3939,66 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), arg0, arg1)
 }
 
+// GetWorkspaceCountsByTemplateAndStatus mocks base method.
+func (m *MockStore) GetWorkspaceCountsByTemplateAndStatus(arg0 context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceCountsByTemplateAndStatus", arg0)
+	ret0, _ := ret[0].([]database.GetWorkspaceCountsByTemplateAndStatusRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceCountsByTemplateAndStatus indicates an expected call of GetWorkspaceCountsByTemplateAndStatus.
+func (mr *MockStoreMockRecorder) GetWorkspaceCountsByTemplateAndStatus(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceCountsByTemplateAndStatus", reflect.TypeOf((*MockStore)(nil).GetWorkspaceCountsByTemplateAndStatus), arg0)
+}
+
+// GetWorkspaceDriftByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceDriftByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceDrift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceDriftByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceDrift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceDriftByWorkspaceID indicates an expected call of GetWorkspaceDriftByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceDriftByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceDriftByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceDriftByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceIDsForDriftCheck mocks base method.
+func (m *MockStore) GetWorkspaceIDsForDriftCheck(arg0 context.Context, arg1 time.Time) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceIDsForDriftCheck", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceIDsForDriftCheck indicates an expected call of GetWorkspaceIDsForDriftCheck.
+func (mr *MockStoreMockRecorder) GetWorkspaceIDsForDriftCheck(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceIDsForDriftCheck", reflect.TypeOf((*MockStore)(nil).GetWorkspaceIDsForDriftCheck), arg0, arg1)
+}
+
+// GetWorkspaceParameterChanges mocks base method.
+func (m *MockStore) GetWorkspaceParameterChanges(arg0 context.Context, arg1 database.GetWorkspaceParameterChangesParams) ([]database.GetWorkspaceParameterChangesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceParameterChanges", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceParameterChangesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceParameterChanges indicates an expected call of GetWorkspaceParameterChanges.
+func (mr *MockStoreMockRecorder) GetWorkspaceParameterChanges(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceParameterChanges", reflect.TypeOf((*MockStore)(nil).GetWorkspaceParameterChanges), arg0, arg1)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(arg0 context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2570,6 +4059,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceProxyByName(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyByName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyByName), arg0, arg1)
 }
 
+// GetWorkspaceProxyHealth mocks base method.
+func (m *MockStore) GetWorkspaceProxyHealth(arg0 context.Context) ([]database.WorkspaceProxyHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceProxyHealth", arg0)
+	ret0, _ := ret[0].([]database.WorkspaceProxyHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceProxyHealth indicates an expected call of GetWorkspaceProxyHealth.
+func (mr *MockStoreMockRecorder) GetWorkspaceProxyHealth(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceProxyHealth", reflect.TypeOf((*MockStore)(nil).GetWorkspaceProxyHealth), arg0)
+}
+
 // GetWorkspaceResourceByID mocks base method.
 func (m *MockStore) GetWorkspaceResourceByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceResource, error) {
 	m.ctrl.T.Helper()
@@ -2585,6 +4089,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourceByID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourceByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourceByID), arg0, arg1)
 }
 
+// GetWorkspaceResourceCosts mocks base method.
+func (m *MockStore) GetWorkspaceResourceCosts(arg0 context.Context, arg1 database.GetWorkspaceResourceCostsParams) ([]database.WorkspaceResourceCost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceResourceCosts", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceResourceCost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceResourceCosts indicates an expected call of GetWorkspaceResourceCosts.
+func (mr *MockStoreMockRecorder) GetWorkspaceResourceCosts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourceCosts", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourceCosts), arg0, arg1)
+}
+
 // GetWorkspaceResourceMetadataByResourceIDs mocks base method.
 func (m *MockStore) GetWorkspaceResourceMetadataByResourceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
 	m.ctrl.T.Helper()
@@ -2660,6 +4179,51 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceSnapshotByID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotByID indicates an expected call of GetWorkspaceSnapshotByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotByID), arg0, arg1)
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceSnapshotsByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceSnapshotsByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceSnapshotsByWorkspaceIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceSnapshotsByWorkspaceID indicates an expected call of GetWorkspaceSnapshotsByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceSnapshotsByWorkspaceID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceSnapshotsByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceSnapshotsByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceTimeline mocks base method.
+func (m *MockStore) GetWorkspaceTimeline(arg0 context.Context, arg1 database.GetWorkspaceTimelineParams) ([]database.GetWorkspaceTimelineRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceTimeline", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceTimelineRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceTimeline indicates an expected call of GetWorkspaceTimeline.
+func (mr *MockStoreMockRecorder) GetWorkspaceTimeline(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceTimeline", reflect.TypeOf((*MockStore)(nil).GetWorkspaceTimeline), arg0, arg1)
+}
+
 // GetWorkspaceUniqueOwnerCountByTemplateIDs mocks base method.
 func (m *MockStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
 	m.ctrl.T.Helper()
@@ -2690,6 +4254,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaces(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaces", reflect.TypeOf((*MockStore)(nil).GetWorkspaces), arg0, arg1)
 }
 
+// GetWorkspacesByIDs mocks base method.
+func (m *MockStore) GetWorkspacesByIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacesByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacesByIDs indicates an expected call of GetWorkspacesByIDs.
+func (mr *MockStoreMockRecorder) GetWorkspacesByIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacesByIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspacesByIDs), arg0, arg1)
+}
+
 // GetWorkspacesEligibleForTransition mocks base method.
 func (m *MockStore) GetWorkspacesEligibleForTransition(arg0 context.Context, arg1 time.Time) ([]database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -2706,7 +4285,7 @@ func (mr *MockStoreMockRecorder) GetWorkspacesEligibleForTransition(arg0, arg1 a
 }
 
 // InTx mocks base method.
-func (m *MockStore) InTx(arg0 func(database.Store) error, arg1 *sql.TxOptions) error {
+func (m *MockStore) InTx(arg0 func(database.Store) error, arg1 *database.TxOptions) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "InTx", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -2719,6 +4298,21 @@ func (mr *MockStoreMockRecorder) InTx(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTx", reflect.TypeOf((*MockStore)(nil).InTx), arg0, arg1)
 }
 
+// IncrementRateLimitCounter mocks base method.
+func (m *MockStore) IncrementRateLimitCounter(arg0 context.Context, arg1 database.IncrementRateLimitCounterParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementRateLimitCounter", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementRateLimitCounter indicates an expected call of IncrementRateLimitCounter.
+func (mr *MockStoreMockRecorder) IncrementRateLimitCounter(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRateLimitCounter", reflect.TypeOf((*MockStore)(nil).IncrementRateLimitCounter), arg0, arg1)
+}
+
 // InsertAPIKey mocks base method.
 func (m *MockStore) InsertAPIKey(arg0 context.Context, arg1 database.InsertAPIKeyParams) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -2764,6 +4358,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), arg0, arg1)
 }
 
+// InsertConnectionLog mocks base method.
+func (m *MockStore) InsertConnectionLog(arg0 context.Context, arg1 database.InsertConnectionLogParams) (database.ConnectionLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertConnectionLog", arg0, arg1)
+	ret0, _ := ret[0].(database.ConnectionLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertConnectionLog indicates an expected call of InsertConnectionLog.
+func (mr *MockStoreMockRecorder) InsertConnectionLog(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertConnectionLog", reflect.TypeOf((*MockStore)(nil).InsertConnectionLog), arg0, arg1)
+}
+
 // InsertDBCryptKey mocks base method.
 func (m *MockStore) InsertDBCryptKey(arg0 context.Context, arg1 database.InsertDBCryptKeyParams) error {
 	m.ctrl.T.Helper()
@@ -2880,6 +4489,36 @@ func (mr *MockStoreMockRecorder) InsertGroupMember(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupMember", reflect.TypeOf((*MockStore)(nil).InsertGroupMember), arg0, arg1)
 }
 
+// InsertGroupSyncRun mocks base method.
+func (m *MockStore) InsertGroupSyncRun(arg0 context.Context, arg1 database.InsertGroupSyncRunParams) (database.GroupSyncRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGroupSyncRun", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupSyncRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertGroupSyncRun indicates an expected call of InsertGroupSyncRun.
+func (mr *MockStoreMockRecorder) InsertGroupSyncRun(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupSyncRun", reflect.TypeOf((*MockStore)(nil).InsertGroupSyncRun), arg0, arg1)
+}
+
+// InsertInboxNotification mocks base method.
+func (m *MockStore) InsertInboxNotification(arg0 context.Context, arg1 database.InsertInboxNotificationParams) (database.InboxNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertInboxNotification", arg0, arg1)
+	ret0, _ := ret[0].(database.InboxNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertInboxNotification indicates an expected call of InsertInboxNotification.
+func (mr *MockStoreMockRecorder) InsertInboxNotification(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertInboxNotification", reflect.TypeOf((*MockStore)(nil).InsertInboxNotification), arg0, arg1)
+}
+
 // InsertLicense mocks base method.
 func (m *MockStore) InsertLicense(arg0 context.Context, arg1 database.InsertLicenseParams) (database.License, error) {
 	m.ctrl.T.Helper()
@@ -2907,7 +4546,22 @@ func (m *MockStore) InsertMissingGroups(arg0 context.Context, arg1 database.Inse
 // InsertMissingGroups indicates an expected call of InsertMissingGroups.
 func (mr *MockStoreMockRecorder) InsertMissingGroups(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), arg0, arg1)
+}
+
+// InsertNotificationMessage mocks base method.
+func (m *MockStore) InsertNotificationMessage(arg0 context.Context, arg1 database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotificationMessage", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNotificationMessage indicates an expected call of InsertNotificationMessage.
+func (mr *MockStoreMockRecorder) InsertNotificationMessage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotificationMessage", reflect.TypeOf((*MockStore)(nil).InsertNotificationMessage), arg0, arg1)
 }
 
 // InsertOAuth2ProviderApp mocks base method.
@@ -2925,6 +4579,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderApp(arg0, arg1 any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderApp", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderApp), arg0, arg1)
 }
 
+// InsertOAuth2ProviderAppCode mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppCode(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppCode", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderAppCode indicates an expected call of InsertOAuth2ProviderAppCode.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppCode", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppCode), arg0, arg1)
+}
+
 // InsertOAuth2ProviderAppSecret mocks base method.
 func (m *MockStore) InsertOAuth2ProviderAppSecret(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
 	m.ctrl.T.Helper()
@@ -2940,6 +4609,21 @@ func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppSecret(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppSecret", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppSecret), arg0, arg1)
 }
 
+// InsertOAuth2ProviderAppToken mocks base method.
+func (m *MockStore) InsertOAuth2ProviderAppToken(arg0 context.Context, arg1 database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2ProviderAppToken", arg0, arg1)
+	ret0, _ := ret[0].(database.OAuth2ProviderAppToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuth2ProviderAppToken indicates an expected call of InsertOAuth2ProviderAppToken.
+func (mr *MockStoreMockRecorder) InsertOAuth2ProviderAppToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2ProviderAppToken", reflect.TypeOf((*MockStore)(nil).InsertOAuth2ProviderAppToken), arg0, arg1)
+}
+
 // InsertOrganization mocks base method.
 func (m *MockStore) InsertOrganization(arg0 context.Context, arg1 database.InsertOrganizationParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2970,6 +4654,21 @@ func (mr *MockStoreMockRecorder) InsertOrganizationMember(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrganizationMember", reflect.TypeOf((*MockStore)(nil).InsertOrganizationMember), arg0, arg1)
 }
 
+// InsertPasskey mocks base method.
+func (m *MockStore) InsertPasskey(arg0 context.Context, arg1 database.InsertPasskeyParams) (database.Passkey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPasskey", arg0, arg1)
+	ret0, _ := ret[0].(database.Passkey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPasskey indicates an expected call of InsertPasskey.
+func (mr *MockStoreMockRecorder) InsertPasskey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPasskey", reflect.TypeOf((*MockStore)(nil).InsertPasskey), arg0, arg1)
+}
+
 // InsertProvisionerJob mocks base method.
 func (m *MockStore) InsertProvisionerJob(arg0 context.Context, arg1 database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -2985,6 +4684,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJob(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJob", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJob), arg0, arg1)
 }
 
+// InsertProvisionerJobLogArchive mocks base method.
+func (m *MockStore) InsertProvisionerJobLogArchive(arg0 context.Context, arg1 database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobLogArchive", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerJobLogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerJobLogArchive indicates an expected call of InsertProvisionerJobLogArchive.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobLogArchive(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogArchive", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogArchive), arg0, arg1)
+}
+
 // InsertProvisionerJobLogs mocks base method.
 func (m *MockStore) InsertProvisionerJobLogs(arg0 context.Context, arg1 database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -3000,6 +4714,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJobLogs(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobLogs", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobLogs), arg0, arg1)
 }
 
+// InsertProvisionerKey mocks base method.
+func (m *MockStore) InsertProvisionerKey(arg0 context.Context, arg1 database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerKey", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerKey indicates an expected call of InsertProvisionerKey.
+func (mr *MockStoreMockRecorder) InsertProvisionerKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerKey", reflect.TypeOf((*MockStore)(nil).InsertProvisionerKey), arg0, arg1)
+}
+
 // InsertReplica mocks base method.
 func (m *MockStore) InsertReplica(arg0 context.Context, arg1 database.InsertReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -3015,6 +4744,21 @@ func (mr *MockStoreMockRecorder) InsertReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertReplica", reflect.TypeOf((*MockStore)(nil).InsertReplica), arg0, arg1)
 }
 
+// InsertSessionRecording mocks base method.
+func (m *MockStore) InsertSessionRecording(arg0 context.Context, arg1 database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSessionRecording", arg0, arg1)
+	ret0, _ := ret[0].(database.SessionRecording)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSessionRecording indicates an expected call of InsertSessionRecording.
+func (mr *MockStoreMockRecorder) InsertSessionRecording(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSessionRecording", reflect.TypeOf((*MockStore)(nil).InsertSessionRecording), arg0, arg1)
+}
+
 // InsertTemplate mocks base method.
 func (m *MockStore) InsertTemplate(arg0 context.Context, arg1 database.InsertTemplateParams) error {
 	m.ctrl.T.Helper()
@@ -3058,6 +4802,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionParameter(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionParameter", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionParameter), arg0, arg1)
 }
 
+// InsertTemplateVersionPreset mocks base method.
+func (m *MockStore) InsertTemplateVersionPreset(arg0 context.Context, arg1 database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionPreset", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPreset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionPreset indicates an expected call of InsertTemplateVersionPreset.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionPreset(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionPreset", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionPreset), arg0, arg1)
+}
+
 // InsertTemplateVersionVariable mocks base method.
 func (m *MockStore) InsertTemplateVersionVariable(arg0 context.Context, arg1 database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -3073,6 +4832,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionVariable(arg0, arg1 any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionVariable", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionVariable), arg0, arg1)
 }
 
+// InsertTwoFactorChallenge mocks base method.
+func (m *MockStore) InsertTwoFactorChallenge(arg0 context.Context, arg1 database.InsertTwoFactorChallengeParams) (database.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(database.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTwoFactorChallenge indicates an expected call of InsertTwoFactorChallenge.
+func (mr *MockStoreMockRecorder) InsertTwoFactorChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTwoFactorChallenge", reflect.TypeOf((*MockStore)(nil).InsertTwoFactorChallenge), arg0, arg1)
+}
+
 // InsertUser mocks base method.
 func (m *MockStore) InsertUser(arg0 context.Context, arg1 database.InsertUserParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -3117,6 +4891,50 @@ func (mr *MockStoreMockRecorder) InsertUserLink(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), arg0, arg1)
 }
 
+// InsertUserRecoveryCode mocks base method.
+func (m *MockStore) InsertUserRecoveryCode(arg0 context.Context, arg1 database.InsertUserRecoveryCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserRecoveryCode indicates an expected call of InsertUserRecoveryCode.
+func (mr *MockStoreMockRecorder) InsertUserRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserRecoveryCode", reflect.TypeOf((*MockStore)(nil).InsertUserRecoveryCode), arg0, arg1)
+}
+
+// InsertWebhook mocks base method.
+func (m *MockStore) InsertWebhook(arg0 context.Context, arg1 database.InsertWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook.
+func (mr *MockStoreMockRecorder) InsertWebhook(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockStore)(nil).InsertWebhook), arg0, arg1)
+}
+
+// InsertWebhookDelivery mocks base method.
+func (m *MockStore) InsertWebhookDelivery(arg0 context.Context, arg1 database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookDelivery indicates an expected call of InsertWebhookDelivery.
+func (mr *MockStoreMockRecorder) InsertWebhookDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDelivery", reflect.TypeOf((*MockStore)(nil).InsertWebhookDelivery), arg0, arg1)
+}
+
 // InsertWorkspace mocks base method.
 func (m *MockStore) InsertWorkspace(arg0 context.Context, arg1 database.InsertWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -3191,6 +5009,20 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) InsertWorkspaceAgentMetadataHistory(arg0 context.Context, arg1 database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentMetadataHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentMetadataHistory indicates an expected call of InsertWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadataHistory(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadataHistory), arg0, arg1)
+}
+
 // InsertWorkspaceAgentScripts mocks base method.
 func (m *MockStore) InsertWorkspaceAgentScripts(arg0 context.Context, arg1 database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
 	m.ctrl.T.Helper()
@@ -3292,6 +5124,34 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), arg0, arg1)
 }
 
+// InsertWorkspaceEvent mocks base method.
+func (m *MockStore) InsertWorkspaceEvent(arg0 context.Context, arg1 database.InsertWorkspaceEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceEvent indicates an expected call of InsertWorkspaceEvent.
+func (mr *MockStoreMockRecorder) InsertWorkspaceEvent(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceEvent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceEvent), arg0, arg1)
+}
+
+// InsertWorkspaceFavorite mocks base method.
+func (m *MockStore) InsertWorkspaceFavorite(arg0 context.Context, arg1 database.InsertWorkspaceFavoriteParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceFavorite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceFavorite indicates an expected call of InsertWorkspaceFavorite.
+func (mr *MockStoreMockRecorder) InsertWorkspaceFavorite(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceFavorite", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceFavorite), arg0, arg1)
+}
+
 // InsertWorkspaceProxy mocks base method.
 func (m *MockStore) InsertWorkspaceProxy(arg0 context.Context, arg1 database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -3337,6 +5197,36 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceSnapshot mocks base method.
+func (m *MockStore) InsertWorkspaceSnapshot(arg0 context.Context, arg1 database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceSnapshot indicates an expected call of InsertWorkspaceSnapshot.
+func (mr *MockStoreMockRecorder) InsertWorkspaceSnapshot(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceSnapshot", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceSnapshot), arg0, arg1)
+}
+
+// ListProvisionerKeysByOrganization mocks base method.
+func (m *MockStore) ListProvisionerKeysByOrganization(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProvisionerKeysByOrganization", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProvisionerKeysByOrganization indicates an expected call of ListProvisionerKeysByOrganization.
+func (mr *MockStoreMockRecorder) ListProvisionerKeysByOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProvisionerKeysByOrganization", reflect.TypeOf((*MockStore)(nil).ListProvisionerKeysByOrganization), arg0, arg1)
+}
+
 // Ping mocks base method.
 func (m *MockStore) Ping(arg0 context.Context) (time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -3352,6 +5242,36 @@ func (mr *MockStoreMockRecorder) Ping(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
 }
 
+// RecordLoginAttempt mocks base method.
+func (m *MockStore) RecordLoginAttempt(arg0 context.Context, arg1 database.RecordLoginAttemptParams) (database.LoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(database.LoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginAttempt indicates an expected call of RecordLoginAttempt.
+func (mr *MockStoreMockRecorder) RecordLoginAttempt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginAttempt", reflect.TypeOf((*MockStore)(nil).RecordLoginAttempt), arg0, arg1)
+}
+
+// RecordUnknownLoginAttempt mocks base method.
+func (m *MockStore) RecordUnknownLoginAttempt(arg0 context.Context, arg1 database.RecordUnknownLoginAttemptParams) (database.UnknownLoginFailure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordUnknownLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(database.UnknownLoginFailure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordUnknownLoginAttempt indicates an expected call of RecordUnknownLoginAttempt.
+func (mr *MockStoreMockRecorder) RecordUnknownLoginAttempt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordUnknownLoginAttempt", reflect.TypeOf((*MockStore)(nil).RecordUnknownLoginAttempt), arg0, arg1)
+}
+
 // RegisterWorkspaceProxy mocks base method.
 func (m *MockStore) RegisterWorkspaceProxy(arg0 context.Context, arg1 database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -3367,6 +5287,20 @@ func (mr *MockStoreMockRecorder) RegisterWorkspaceProxy(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWorkspaceProxy", reflect.TypeOf((*MockStore)(nil).RegisterWorkspaceProxy), arg0, arg1)
 }
 
+// RequeueProvisionerJobByID mocks base method.
+func (m *MockStore) RequeueProvisionerJobByID(arg0 context.Context, arg1 database.RequeueProvisionerJobByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueProvisionerJobByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueProvisionerJobByID indicates an expected call of RequeueProvisionerJobByID.
+func (mr *MockStoreMockRecorder) RequeueProvisionerJobByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).RequeueProvisionerJobByID), arg0, arg1)
+}
+
 // RevokeDBCryptKey mocks base method.
 func (m *MockStore) RevokeDBCryptKey(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -3484,6 +5418,35 @@ func (mr *MockStoreMockRecorder) UpdateInactiveUsersToDormant(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInactiveUsersToDormant", reflect.TypeOf((*MockStore)(nil).UpdateInactiveUsersToDormant), arg0, arg1)
 }
 
+// UpdateInactiveUsersToSuspended mocks base method.
+func (m *MockStore) UpdateInactiveUsersToSuspended(arg0 context.Context, arg1 database.UpdateInactiveUsersToSuspendedParams) ([]database.UpdateInactiveUsersToSuspendedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInactiveUsersToSuspended", arg0, arg1)
+	ret0, _ := ret[0].([]database.UpdateInactiveUsersToSuspendedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInactiveUsersToSuspended indicates an expected call of UpdateInactiveUsersToSuspended.
+func (mr *MockStoreMockRecorder) UpdateInactiveUsersToSuspended(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInactiveUsersToSuspended", reflect.TypeOf((*MockStore)(nil).UpdateInactiveUsersToSuspended), arg0, arg1)
+}
+
+// UpdateInboxNotificationsReadByUserID mocks base method.
+func (m *MockStore) UpdateInboxNotificationsReadByUserID(arg0 context.Context, arg1 database.UpdateInboxNotificationsReadByUserIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInboxNotificationsReadByUserID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInboxNotificationsReadByUserID indicates an expected call of UpdateInboxNotificationsReadByUserID.
+func (mr *MockStoreMockRecorder) UpdateInboxNotificationsReadByUserID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInboxNotificationsReadByUserID", reflect.TypeOf((*MockStore)(nil).UpdateInboxNotificationsReadByUserID), arg0, arg1)
+}
+
 // UpdateMemberRoles mocks base method.
 func (m *MockStore) UpdateMemberRoles(arg0 context.Context, arg1 database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	m.ctrl.T.Helper()
@@ -3499,6 +5462,20 @@ func (mr *MockStoreMockRecorder) UpdateMemberRoles(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRoles", reflect.TypeOf((*MockStore)(nil).UpdateMemberRoles), arg0, arg1)
 }
 
+// UpdateNotificationMessageByID mocks base method.
+func (m *MockStore) UpdateNotificationMessageByID(arg0 context.Context, arg1 database.UpdateNotificationMessageByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationMessageByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationMessageByID indicates an expected call of UpdateNotificationMessageByID.
+func (mr *MockStoreMockRecorder) UpdateNotificationMessageByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationMessageByID", reflect.TypeOf((*MockStore)(nil).UpdateNotificationMessageByID), arg0, arg1)
+}
+
 // UpdateOAuth2ProviderAppByID mocks base method.
 func (m *MockStore) UpdateOAuth2ProviderAppByID(arg0 context.Context, arg1 database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
 	m.ctrl.T.Helper()
@@ -3529,6 +5506,20 @@ func (mr *MockStoreMockRecorder) UpdateOAuth2ProviderAppSecretByID(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2ProviderAppSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateOAuth2ProviderAppSecretByID), arg0, arg1)
 }
 
+// UpdatePasskeyUsage mocks base method.
+func (m *MockStore) UpdatePasskeyUsage(arg0 context.Context, arg1 database.UpdatePasskeyUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasskeyUsage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasskeyUsage indicates an expected call of UpdatePasskeyUsage.
+func (mr *MockStoreMockRecorder) UpdatePasskeyUsage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasskeyUsage", reflect.TypeOf((*MockStore)(nil).UpdatePasskeyUsage), arg0, arg1)
+}
+
 // UpdateProvisionerDaemonLastSeenAt mocks base method.
 func (m *MockStore) UpdateProvisionerDaemonLastSeenAt(arg0 context.Context, arg1 database.UpdateProvisionerDaemonLastSeenAtParams) error {
 	m.ctrl.T.Helper()
@@ -3557,6 +5548,20 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobByID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobByID), arg0, arg1)
 }
 
+// UpdateProvisionerJobPriorityByID mocks base method.
+func (m *MockStore) UpdateProvisionerJobPriorityByID(arg0 context.Context, arg1 database.UpdateProvisionerJobPriorityByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerJobPriorityByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerJobPriorityByID indicates an expected call of UpdateProvisionerJobPriorityByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerJobPriorityByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobPriorityByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobPriorityByID), arg0, arg1)
+}
+
 // UpdateProvisionerJobWithCancelByID mocks base method.
 func (m *MockStore) UpdateProvisionerJobWithCancelByID(arg0 context.Context, arg1 database.UpdateProvisionerJobWithCancelByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3585,6 +5590,21 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithCompleteByID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithCompleteByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithCompleteByID), arg0, arg1)
 }
 
+// UpdateProvisionerKeySecretByID mocks base method.
+func (m *MockStore) UpdateProvisionerKeySecretByID(arg0 context.Context, arg1 database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerKeySecretByID", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProvisionerKeySecretByID indicates an expected call of UpdateProvisionerKeySecretByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerKeySecretByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerKeySecretByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerKeySecretByID), arg0, arg1)
+}
+
 // UpdateReplica mocks base method.
 func (m *MockStore) UpdateReplica(arg0 context.Context, arg1 database.UpdateReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -3600,6 +5620,20 @@ func (mr *MockStoreMockRecorder) UpdateReplica(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), arg0, arg1)
 }
 
+// UpdateTailnetPeerStatusByCoordinator mocks base method.
+func (m *MockStore) UpdateTailnetPeerStatusByCoordinator(arg0 context.Context, arg1 database.UpdateTailnetPeerStatusByCoordinatorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTailnetPeerStatusByCoordinator", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTailnetPeerStatusByCoordinator indicates an expected call of UpdateTailnetPeerStatusByCoordinator.
+func (mr *MockStoreMockRecorder) UpdateTailnetPeerStatusByCoordinator(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTailnetPeerStatusByCoordinator", reflect.TypeOf((*MockStore)(nil).UpdateTailnetPeerStatusByCoordinator), arg0, arg1)
+}
+
 // UpdateTemplateACLByID mocks base method.
 func (m *MockStore) UpdateTemplateACLByID(arg0 context.Context, arg1 database.UpdateTemplateACLByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3642,6 +5676,20 @@ func (mr *MockStoreMockRecorder) UpdateTemplateActiveVersionByID(arg0, arg1 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateActiveVersionByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateActiveVersionByID), arg0, arg1)
 }
 
+// UpdateTemplateBuildRetryPolicyByID mocks base method.
+func (m *MockStore) UpdateTemplateBuildRetryPolicyByID(arg0 context.Context, arg1 database.UpdateTemplateBuildRetryPolicyByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateBuildRetryPolicyByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateBuildRetryPolicyByID indicates an expected call of UpdateTemplateBuildRetryPolicyByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateBuildRetryPolicyByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateBuildRetryPolicyByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateBuildRetryPolicyByID), arg0, arg1)
+}
+
 // UpdateTemplateDeletedByID mocks base method.
 func (m *MockStore) UpdateTemplateDeletedByID(arg0 context.Context, arg1 database.UpdateTemplateDeletedByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3656,18 +5704,74 @@ func (mr *MockStoreMockRecorder) UpdateTemplateDeletedByID(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateDeletedByID), arg0, arg1)
 }
 
-// UpdateTemplateMetaByID mocks base method.
-func (m *MockStore) UpdateTemplateMetaByID(arg0 context.Context, arg1 database.UpdateTemplateMetaByIDParams) error {
+// UpdateTemplateMaintenanceWindowByID mocks base method.
+func (m *MockStore) UpdateTemplateMaintenanceWindowByID(arg0 context.Context, arg1 database.UpdateTemplateMaintenanceWindowByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateMaintenanceWindowByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateMaintenanceWindowByID indicates an expected call of UpdateTemplateMaintenanceWindowByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateMaintenanceWindowByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMaintenanceWindowByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMaintenanceWindowByID), arg0, arg1)
+}
+
+// UpdateTemplateMaxBuildDurationByID mocks base method.
+func (m *MockStore) UpdateTemplateMaxBuildDurationByID(arg0 context.Context, arg1 database.UpdateTemplateMaxBuildDurationByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateMaxBuildDurationByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateMaxBuildDurationByID indicates an expected call of UpdateTemplateMaxBuildDurationByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateMaxBuildDurationByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMaxBuildDurationByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMaxBuildDurationByID), arg0, arg1)
+}
+
+// UpdateTemplateMaxRunningWorkspacesByID mocks base method.
+func (m *MockStore) UpdateTemplateMaxRunningWorkspacesByID(arg0 context.Context, arg1 database.UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateMaxRunningWorkspacesByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateMaxRunningWorkspacesByID indicates an expected call of UpdateTemplateMaxRunningWorkspacesByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateMaxRunningWorkspacesByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMaxRunningWorkspacesByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMaxRunningWorkspacesByID), arg0, arg1)
+}
+
+// UpdateTemplateMetaByID mocks base method.
+func (m *MockStore) UpdateTemplateMetaByID(arg0 context.Context, arg1 database.UpdateTemplateMetaByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateMetaByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateMetaByID indicates an expected call of UpdateTemplateMetaByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), arg0, arg1)
+}
+
+// UpdateTemplatePortForwardingPolicyByID mocks base method.
+func (m *MockStore) UpdateTemplatePortForwardingPolicyByID(arg0 context.Context, arg1 database.UpdateTemplatePortForwardingPolicyByIDParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTemplateMetaByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateTemplatePortForwardingPolicyByID", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateTemplateMetaByID indicates an expected call of UpdateTemplateMetaByID.
-func (mr *MockStoreMockRecorder) UpdateTemplateMetaByID(arg0, arg1 any) *gomock.Call {
+// UpdateTemplatePortForwardingPolicyByID indicates an expected call of UpdateTemplatePortForwardingPolicyByID.
+func (mr *MockStoreMockRecorder) UpdateTemplatePortForwardingPolicyByID(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateMetaByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateMetaByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplatePortForwardingPolicyByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplatePortForwardingPolicyByID), arg0, arg1)
 }
 
 // UpdateTemplateScheduleByID mocks base method.
@@ -3684,6 +5788,20 @@ func (mr *MockStoreMockRecorder) UpdateTemplateScheduleByID(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateScheduleByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateScheduleByID), arg0, arg1)
 }
 
+// UpdateTemplateSessionRecordingByID mocks base method.
+func (m *MockStore) UpdateTemplateSessionRecordingByID(arg0 context.Context, arg1 database.UpdateTemplateSessionRecordingByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateSessionRecordingByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateSessionRecordingByID indicates an expected call of UpdateTemplateSessionRecordingByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateSessionRecordingByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateSessionRecordingByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateSessionRecordingByID), arg0, arg1)
+}
+
 // UpdateTemplateVersionByID mocks base method.
 func (m *MockStore) UpdateTemplateVersionByID(arg0 context.Context, arg1 database.UpdateTemplateVersionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3726,6 +5844,34 @@ func (mr *MockStoreMockRecorder) UpdateTemplateVersionExternalAuthProvidersByJob
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionExternalAuthProvidersByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionExternalAuthProvidersByJobID), arg0, arg1)
 }
 
+// UpdateTemplateVisibilityByID mocks base method.
+func (m *MockStore) UpdateTemplateVisibilityByID(arg0 context.Context, arg1 database.UpdateTemplateVisibilityByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVisibilityByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateVisibilityByID indicates an expected call of UpdateTemplateVisibilityByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVisibilityByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVisibilityByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVisibilityByID), arg0, arg1)
+}
+
+// UpdateTemplateWorkspaceNamePolicyByID mocks base method.
+func (m *MockStore) UpdateTemplateWorkspaceNamePolicyByID(arg0 context.Context, arg1 database.UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateWorkspaceNamePolicyByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplateWorkspaceNamePolicyByID indicates an expected call of UpdateTemplateWorkspaceNamePolicyByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateWorkspaceNamePolicyByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateWorkspaceNamePolicyByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateWorkspaceNamePolicyByID), arg0, arg1)
+}
+
 // UpdateTemplateWorkspacesLastUsedAt mocks base method.
 func (m *MockStore) UpdateTemplateWorkspacesLastUsedAt(arg0 context.Context, arg1 database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	m.ctrl.T.Helper()
@@ -3783,6 +5929,21 @@ func (mr *MockStoreMockRecorder) UpdateUserHashedPassword(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserHashedPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserHashedPassword), arg0, arg1)
 }
 
+// UpdateUserIPAllowlist mocks base method.
+func (m *MockStore) UpdateUserIPAllowlist(arg0 context.Context, arg1 database.UpdateUserIPAllowlistParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserIPAllowlist", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserIPAllowlist indicates an expected call of UpdateUserIPAllowlist.
+func (mr *MockStoreMockRecorder) UpdateUserIPAllowlist(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserIPAllowlist", reflect.TypeOf((*MockStore)(nil).UpdateUserIPAllowlist), arg0, arg1)
+}
+
 // UpdateUserLastSeenAt mocks base method.
 func (m *MockStore) UpdateUserLastSeenAt(arg0 context.Context, arg1 database.UpdateUserLastSeenAtParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -3903,6 +6064,34 @@ func (mr *MockStoreMockRecorder) UpdateUserStatus(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStatus", reflect.TypeOf((*MockStore)(nil).UpdateUserStatus), arg0, arg1)
 }
 
+// UpdateWebhookDeliveryByID mocks base method.
+func (m *MockStore) UpdateWebhookDeliveryByID(arg0 context.Context, arg1 database.UpdateWebhookDeliveryByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDeliveryByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookDeliveryByID indicates an expected call of UpdateWebhookDeliveryByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookDeliveryByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDeliveryByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDeliveryByID), arg0, arg1)
+}
+
+// UpdateWebhookSecretByID mocks base method.
+func (m *MockStore) UpdateWebhookSecretByID(arg0 context.Context, arg1 database.UpdateWebhookSecretByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookSecretByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhookSecretByID indicates an expected call of UpdateWebhookSecretByID.
+func (mr *MockStoreMockRecorder) UpdateWebhookSecretByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookSecretByID", reflect.TypeOf((*MockStore)(nil).UpdateWebhookSecretByID), arg0, arg1)
+}
+
 // UpdateWorkspace mocks base method.
 func (m *MockStore) UpdateWorkspace(arg0 context.Context, arg1 database.UpdateWorkspaceParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -3918,6 +6107,34 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), arg0, arg1)
 }
 
+// UpdateWorkspaceACLByID mocks base method.
+func (m *MockStore) UpdateWorkspaceACLByID(arg0 context.Context, arg1 database.UpdateWorkspaceACLByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceACLByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceACLByID indicates an expected call of UpdateWorkspaceACLByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceACLByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceACLByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceACLByID), arg0, arg1)
+}
+
+// UpdateWorkspaceAgentAuthTokenByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentAuthTokenByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentAuthTokenByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentAuthTokenByID indicates an expected call of UpdateWorkspaceAgentAuthTokenByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentAuthTokenByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentAuthTokenByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentAuthTokenByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -4072,6 +6289,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildProvisionerStateByID(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildProvisionerStateByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildProvisionerStateByID), arg0, arg1)
 }
 
+// UpdateWorkspaceBuildRetryAtByID mocks base method.
+func (m *MockStore) UpdateWorkspaceBuildRetryAtByID(arg0 context.Context, arg1 database.UpdateWorkspaceBuildRetryAtByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceBuildRetryAtByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceBuildRetryAtByID indicates an expected call of UpdateWorkspaceBuildRetryAtByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceBuildRetryAtByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceBuildRetryAtByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceBuildRetryAtByID), arg0, arg1)
+}
+
 // UpdateWorkspaceDeletedByID mocks base method.
 func (m *MockStore) UpdateWorkspaceDeletedByID(arg0 context.Context, arg1 database.UpdateWorkspaceDeletedByIDParams) error {
 	m.ctrl.T.Helper()
@@ -4086,6 +6317,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceDeletedByID(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDeletedByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDeletedByID), arg0, arg1)
 }
 
+// UpdateWorkspaceDormancyExempt mocks base method.
+func (m *MockStore) UpdateWorkspaceDormancyExempt(arg0 context.Context, arg1 database.UpdateWorkspaceDormancyExemptParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceDormancyExempt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceDormancyExempt indicates an expected call of UpdateWorkspaceDormancyExempt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceDormancyExempt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDormancyExempt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDormancyExempt), arg0, arg1)
+}
+
 // UpdateWorkspaceDormantDeletingAt mocks base method.
 func (m *MockStore) UpdateWorkspaceDormantDeletingAt(arg0 context.Context, arg1 database.UpdateWorkspaceDormantDeletingAtParams) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -4101,6 +6346,20 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceDormantDeletingAt(arg0, arg1 any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDormantDeletingAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDormantDeletingAt), arg0, arg1)
 }
 
+// UpdateWorkspaceDriftResult mocks base method.
+func (m *MockStore) UpdateWorkspaceDriftResult(arg0 context.Context, arg1 database.UpdateWorkspaceDriftResultParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceDriftResult", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceDriftResult indicates an expected call of UpdateWorkspaceDriftResult.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceDriftResult(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceDriftResult", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceDriftResult), arg0, arg1)
+}
+
 // UpdateWorkspaceLastUsedAt mocks base method.
 func (m *MockStore) UpdateWorkspaceLastUsedAt(arg0 context.Context, arg1 database.UpdateWorkspaceLastUsedAtParams) error {
 	m.ctrl.T.Helper()
@@ -4115,6 +6374,35 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceLastUsedAt(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceLastUsedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceLastUsedAt), arg0, arg1)
 }
 
+// UpdateWorkspaceMaintenanceOptOut mocks base method.
+func (m *MockStore) UpdateWorkspaceMaintenanceOptOut(arg0 context.Context, arg1 database.UpdateWorkspaceMaintenanceOptOutParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceMaintenanceOptOut", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceMaintenanceOptOut indicates an expected call of UpdateWorkspaceMaintenanceOptOut.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceMaintenanceOptOut(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceMaintenanceOptOut", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceMaintenanceOptOut), arg0, arg1)
+}
+
+// UpdateWorkspaceOwnerByID mocks base method.
+func (m *MockStore) UpdateWorkspaceOwnerByID(arg0 context.Context, arg1 database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceOwnerByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceOwnerByID indicates an expected call of UpdateWorkspaceOwnerByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceOwnerByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceOwnerByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceOwnerByID), arg0, arg1)
+}
+
 // UpdateWorkspaceProxy mocks base method.
 func (m *MockStore) UpdateWorkspaceProxy(arg0 context.Context, arg1 database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -4200,6 +6488,21 @@ func (mr *MockStoreMockRecorder) UpsertApplicationName(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertApplicationName", reflect.TypeOf((*MockStore)(nil).UpsertApplicationName), arg0, arg1)
 }
 
+// UpsertCustomRole mocks base method.
+func (m *MockStore) UpsertCustomRole(arg0 context.Context, arg1 database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCustomRole", arg0, arg1)
+	ret0, _ := ret[0].(database.CustomRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCustomRole indicates an expected call of UpsertCustomRole.
+func (mr *MockStoreMockRecorder) UpsertCustomRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCustomRole", reflect.TypeOf((*MockStore)(nil).UpsertCustomRole), arg0, arg1)
+}
+
 // UpsertDefaultProxy mocks base method.
 func (m *MockStore) UpsertDefaultProxy(arg0 context.Context, arg1 database.UpsertDefaultProxyParams) error {
 	m.ctrl.T.Helper()
@@ -4242,6 +6545,20 @@ func (mr *MockStoreMockRecorder) UpsertLastUpdateCheck(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).UpsertLastUpdateCheck), arg0, arg1)
 }
 
+// UpsertLicenseGracePeriodStartedAt mocks base method.
+func (m *MockStore) UpsertLicenseGracePeriodStartedAt(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLicenseGracePeriodStartedAt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLicenseGracePeriodStartedAt indicates an expected call of UpsertLicenseGracePeriodStartedAt.
+func (mr *MockStoreMockRecorder) UpsertLicenseGracePeriodStartedAt(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLicenseGracePeriodStartedAt", reflect.TypeOf((*MockStore)(nil).UpsertLicenseGracePeriodStartedAt), arg0, arg1)
+}
+
 // UpsertLogoURL mocks base method.
 func (m *MockStore) UpsertLogoURL(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -4256,6 +6573,21 @@ func (mr *MockStoreMockRecorder) UpsertLogoURL(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLogoURL", reflect.TypeOf((*MockStore)(nil).UpsertLogoURL), arg0, arg1)
 }
 
+// UpsertNotificationPreference mocks base method.
+func (m *MockStore) UpsertNotificationPreference(arg0 context.Context, arg1 database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreference", arg0, arg1)
+	ret0, _ := ret[0].(database.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreference indicates an expected call of UpsertNotificationPreference.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreference(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreference", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreference), arg0, arg1)
+}
+
 // UpsertOAuthSigningKey mocks base method.
 func (m *MockStore) UpsertOAuthSigningKey(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -4270,6 +6602,36 @@ func (mr *MockStoreMockRecorder) UpsertOAuthSigningKey(arg0, arg1 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertOAuthSigningKey), arg0, arg1)
 }
 
+// UpsertOrganizationQuota mocks base method.
+func (m *MockStore) UpsertOrganizationQuota(arg0 context.Context, arg1 database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationQuota", arg0, arg1)
+	ret0, _ := ret[0].(database.OrganizationQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationQuota indicates an expected call of UpsertOrganizationQuota.
+func (mr *MockStoreMockRecorder) UpsertOrganizationQuota(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationQuota", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationQuota), arg0, arg1)
+}
+
+// UpsertOrganizationTemplateVariableValue mocks base method.
+func (m *MockStore) UpsertOrganizationTemplateVariableValue(arg0 context.Context, arg1 database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOrganizationTemplateVariableValue", arg0, arg1)
+	ret0, _ := ret[0].(database.OrganizationTemplateVariableValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOrganizationTemplateVariableValue indicates an expected call of UpsertOrganizationTemplateVariableValue.
+func (mr *MockStoreMockRecorder) UpsertOrganizationTemplateVariableValue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOrganizationTemplateVariableValue", reflect.TypeOf((*MockStore)(nil).UpsertOrganizationTemplateVariableValue), arg0, arg1)
+}
+
 // UpsertProvisionerDaemon mocks base method.
 func (m *MockStore) UpsertProvisionerDaemon(arg0 context.Context, arg1 database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -4388,6 +6750,194 @@ func (mr *MockStoreMockRecorder) UpsertTailnetTunnel(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTailnetTunnel", reflect.TypeOf((*MockStore)(nil).UpsertTailnetTunnel), arg0, arg1)
 }
 
+// UpsertTemplateVariableValue mocks base method.
+func (m *MockStore) UpsertTemplateVariableValue(arg0 context.Context, arg1 database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVariableValue", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVariableValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTemplateVariableValue indicates an expected call of UpsertTemplateVariableValue.
+func (mr *MockStoreMockRecorder) UpsertTemplateVariableValue(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVariableValue", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVariableValue), arg0, arg1)
+}
+
+// UpsertTwoFactorPolicy mocks base method.
+func (m *MockStore) UpsertTwoFactorPolicy(arg0 context.Context, arg1 database.UpsertTwoFactorPolicyParams) (database.TwoFactorPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTwoFactorPolicy", arg0, arg1)
+	ret0, _ := ret[0].(database.TwoFactorPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTwoFactorPolicy indicates an expected call of UpsertTwoFactorPolicy.
+func (mr *MockStoreMockRecorder) UpsertTwoFactorPolicy(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTwoFactorPolicy", reflect.TypeOf((*MockStore)(nil).UpsertTwoFactorPolicy), arg0, arg1)
+}
+
+// UpsertUserActivity mocks base method.
+func (m *MockStore) UpsertUserActivity(arg0 context.Context, arg1 database.UpsertUserActivityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserActivity indicates an expected call of UpsertUserActivity.
+func (mr *MockStoreMockRecorder) UpsertUserActivity(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserActivity", reflect.TypeOf((*MockStore)(nil).UpsertUserActivity), arg0, arg1)
+}
+
+// UpsertUserLockout mocks base method.
+func (m *MockStore) UpsertUserLockout(arg0 context.Context, arg1 database.UpsertUserLockoutParams) (database.UserLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserLockout", arg0, arg1)
+	ret0, _ := ret[0].(database.UserLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserLockout indicates an expected call of UpsertUserLockout.
+func (mr *MockStoreMockRecorder) UpsertUserLockout(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserLockout", reflect.TypeOf((*MockStore)(nil).UpsertUserLockout), arg0, arg1)
+}
+
+// UpsertUserSCIMExternalID mocks base method.
+func (m *MockStore) UpsertUserSCIMExternalID(arg0 context.Context, arg1 database.UpsertUserSCIMExternalIDParams) (database.UserSCIMExternalID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserSCIMExternalID", arg0, arg1)
+	ret0, _ := ret[0].(database.UserSCIMExternalID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserSCIMExternalID indicates an expected call of UpsertUserSCIMExternalID.
+func (mr *MockStoreMockRecorder) UpsertUserSCIMExternalID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserSCIMExternalID", reflect.TypeOf((*MockStore)(nil).UpsertUserSCIMExternalID), arg0, arg1)
+}
+
+// UpsertWorkspaceAgentGPUs mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentGPUs(arg0 context.Context, arg1 database.UpsertWorkspaceAgentGPUsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentGPUs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentGPUs indicates an expected call of UpsertWorkspaceAgentGPUs.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentGPUs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentGPUs", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentGPUs), arg0, arg1)
+}
+
+// UpsertWorkspaceAgentPortShare mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentPortShare(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentPortShare", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentPortShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceAgentPortShare indicates an expected call of UpsertWorkspaceAgentPortShare.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPortShare(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPortShare", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPortShare), arg0, arg1)
+}
+
+// UpsertWorkspaceAgentPorts mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentPorts(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPortsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentPorts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentPorts indicates an expected call of UpsertWorkspaceAgentPorts.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPorts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPorts", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPorts), arg0, arg1)
+}
+
+// UpsertWorkspaceAgentPreviousAuthToken mocks base method.
+func (m *MockStore) UpsertWorkspaceAgentPreviousAuthToken(arg0 context.Context, arg1 database.UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceAgentPreviousAuthToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceAgentPreviousAuthToken indicates an expected call of UpsertWorkspaceAgentPreviousAuthToken.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceAgentPreviousAuthToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceAgentPreviousAuthToken", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceAgentPreviousAuthToken), arg0, arg1)
+}
+
+// UpsertWorkspaceDriftJob mocks base method.
+func (m *MockStore) UpsertWorkspaceDriftJob(arg0 context.Context, arg1 database.UpsertWorkspaceDriftJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceDriftJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceDriftJob indicates an expected call of UpsertWorkspaceDriftJob.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceDriftJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceDriftJob", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceDriftJob), arg0, arg1)
+}
+
+// UpsertWorkspaceProxyHealth mocks base method.
+func (m *MockStore) UpsertWorkspaceProxyHealth(arg0 context.Context, arg1 database.UpsertWorkspaceProxyHealthParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceProxyHealth", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceProxyHealth indicates an expected call of UpsertWorkspaceProxyHealth.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceProxyHealth(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceProxyHealth", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceProxyHealth), arg0, arg1)
+}
+
+// UpsertWorkspaceResourceCosts mocks base method.
+func (m *MockStore) UpsertWorkspaceResourceCosts(arg0 context.Context, arg1 database.UpsertWorkspaceResourceCostsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceResourceCosts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspaceResourceCosts indicates an expected call of UpsertWorkspaceResourceCosts.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceResourceCosts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceResourceCosts", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceResourceCosts), arg0, arg1)
+}
+
+// UseUserRecoveryCode mocks base method.
+func (m *MockStore) UseUserRecoveryCode(arg0 context.Context, arg1 database.UseUserRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(database.UserRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserRecoveryCode indicates an expected call of UseUserRecoveryCode.
+func (mr *MockStoreMockRecorder) UseUserRecoveryCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseUserRecoveryCode), arg0, arg1)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
@@ -12,10 +12,29 @@ import (
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 )
 
 const (
 	delay = 10 * time.Minute
+	// userActivityRetention is how long hourly user activity is kept. It
+	// matches the default dormancy period of users.
+	userActivityRetention = 90 * 24 * time.Hour
+	// groupSyncRunRetention is how long group sync runs are kept for
+	// debugging.
+	groupSyncRunRetention = 7 * 24 * time.Hour
+	// loginFailureRetention is how long failed logins and expired lockouts
+	// are kept. Failures are only counted for the lockout duration, which is
+	// usually much shorter.
+	loginFailureRetention = 7 * 24 * time.Hour
+	// agentMetadataHistoryRetention is how long the metadata history of
+	// agents that stopped reporting is kept. The history of running agents is
+	// limited by the number of values kept per metadatum instead.
+	agentMetadataHistoryRetention = 7 * 24 * time.Hour
+	// workspaceEventRetention is how long the events shown in workspace
+	// timelines are kept. Builds stay on the timeline for as long as they
+	// exist.
+	workspaceEventRetention = 90 * 24 * time.Hour
 )
 
 // New creates a new periodically purging database instance.
@@ -45,6 +64,31 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 		eg.Go(func() error {
 			return db.DeleteOldProvisionerDaemons(ctx)
 		})
+		eg.Go(func() error {
+			// Rate limit windows are a minute long, so anything older than
+			// an hour is no longer counted.
+			return db.DeleteOldRateLimitCounters(ctx, dbtime.Now().Add(-time.Hour))
+		})
+		eg.Go(func() error {
+			return db.DeleteOldUserActivity(ctx, dbtime.Now().Add(-userActivityRetention))
+		})
+		eg.Go(func() error {
+			return db.DeleteOldGroupSyncRuns(ctx, dbtime.Now().Add(-groupSyncRunRetention))
+		})
+		eg.Go(func() error {
+			// Challenges that were never answered are only kept until they
+			// expire.
+			return db.DeleteExpiredTwoFactorChallenges(ctx, dbtime.Now())
+		})
+		eg.Go(func() error {
+			return db.DeleteOldLoginFailures(ctx, dbtime.Now().Add(-loginFailureRetention))
+		})
+		eg.Go(func() error {
+			return db.DeleteOldWorkspaceAgentMetadataHistory(ctx, dbtime.Now().Add(-agentMetadataHistoryRetention))
+		})
+		eg.Go(func() error {
+			return db.DeleteOldWorkspaceEvents(ctx, dbtime.Now().Add(-workspaceEventRetention))
+		})
 		err := eg.Wait()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -0,0 +1,312 @@
+// Package dbreplica provides a database.Store that sends expensive read-only
+// queries to Postgres read replicas.
+package dbreplica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+const (
+	wrapname = "dbreplica.Store"
+
+	// DefaultHealthCheckInterval is how often replicas are pinged when no
+	// interval is provided.
+	DefaultHealthCheckInterval = 10 * time.Second
+	// DefaultMaxLag is how far a replica may fall behind the primary when no
+	// maximum is provided.
+	DefaultMaxLag      = 10 * time.Second
+	healthCheckTimeout = 5 * time.Second
+)
+
+// lagQuery returns how many seconds the replica is behind the primary. A
+// replica that has replayed everything it received isn't behind, even if the
+// last transaction it replayed is old, since the primary may just be idle.
+// Servers that aren't replicas aren't behind either. NULL is returned by
+// replicas that haven't replayed a transaction yet.
+const lagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+END`
+
+// Options configures a replica Store.
+type Options struct {
+	Logger slog.Logger
+	// HealthCheckInterval is how often each replica is checked. A replica
+	// that can't be reached, or is too far behind the primary, is not used
+	// until it passes a check again.
+	HealthCheckInterval time.Duration
+	// MaxLag is how far a replica may fall behind the primary and still be
+	// used.
+	MaxLag time.Duration
+}
+
+// Open connects to each of the replicas with the given driver and returns a
+// Store that uses them alongside primary. See New for the queries that are
+// routed to replicas. Closing the Store closes the replica connections, but not
+// primary.
+func Open(ctx context.Context, driver string, primary *sql.DB, replicaURLs []string, opts Options) (*Store, error) {
+	replicas := make([]*sql.DB, 0, len(replicaURLs))
+	for i, u := range replicaURLs {
+		r, err := sql.Open(driver, u)
+		if err != nil {
+			for _, r := range replicas {
+				_ = r.Close()
+			}
+			return nil, xerrors.Errorf("open replica %d: %w", i, err)
+		}
+		replicas = append(replicas, r)
+	}
+	s := New(ctx, primary, replicas, opts)
+	s.closeDBs = replicas
+	return s, nil
+}
+
+// New returns a Store that sends writes and transactions to primary and
+// routes workspace, template and audit log listings to a healthy replica.
+// If no replica is healthy, or a query on a replica fails, the query falls
+// back to the primary.
+func New(ctx context.Context, primary *sql.DB, replicas []*sql.DB, opts Options) *Store {
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if opts.MaxLag <= 0 {
+		opts.MaxLag = DefaultMaxLag
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Store{
+		Store:    database.New(primary),
+		logger:   opts.Logger,
+		interval: opts.HealthCheckInterval,
+		maxLag:   opts.MaxLag,
+		cancel:   cancel,
+	}
+	for i, db := range replicas {
+		s.replicas = append(s.replicas, &replica{
+			index: i,
+			db:    db,
+			store: database.New(db),
+		})
+	}
+	if len(s.replicas) > 0 {
+		s.checkHealth(ctx)
+		s.wg.Add(1)
+		go s.healthLoop(ctx)
+	}
+	return s
+}
+
+var _ database.Store = (*Store)(nil)
+
+// Store is a database.Store that routes some read-only queries to replicas.
+type Store struct {
+	// Store is the primary database.
+	database.Store
+
+	logger   slog.Logger
+	interval time.Duration
+	maxLag   time.Duration
+	replicas []*replica
+	next     atomic.Uint64
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	closeDBs []*sql.DB
+}
+
+type replica struct {
+	index   int
+	db      *sql.DB
+	store   database.Store
+	healthy atomic.Bool
+}
+
+func (s *Store) Wrappers() []string {
+	return append(s.Store.Wrappers(), wrapname)
+}
+
+// HealthyReplicas returns the number of replicas currently used for queries.
+func (s *Store) HealthyReplicas() int {
+	count := 0
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			count++
+		}
+	}
+	return count
+}
+
+// Close stops health checking. Replica connections are only closed if the
+// Store was created with Open.
+func (s *Store) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	var err error
+	for _, db := range s.closeDBs {
+		err = errors.Join(err, db.Close())
+	}
+	return err
+}
+
+func (s *Store) healthLoop(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.checkHealth(ctx)
+	}
+}
+
+func (s *Store) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, r := range s.replicas {
+		r := r
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			err := s.checkLag(ctx, r)
+			healthy := err == nil
+			if r.healthy.Swap(healthy) != healthy {
+				if healthy {
+					s.logger.Info(ctx, "read replica is healthy", slog.F("replica", r.index))
+				} else {
+					s.logger.Warn(ctx, "read replica is unhealthy", slog.F("replica", r.index), slog.Error(err))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// checkLag returns an error if the replica can't be reached or is too far
+// behind the primary.
+func (s *Store) checkLag(ctx context.Context, r *replica) error {
+	var lag sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, lagQuery).Scan(&lag)
+	if err != nil {
+		return xerrors.Errorf("query replication lag: %w", err)
+	}
+	if !lag.Valid {
+		return xerrors.New("replica hasn't replayed a transaction yet")
+	}
+	behind := time.Duration(lag.Float64 * float64(time.Second))
+	if behind > s.maxLag {
+		return xerrors.Errorf("replica is %s behind the primary, more than the maximum of %s", behind.Round(time.Millisecond), s.maxLag)
+	}
+	return nil
+}
+
+// pick returns the next healthy replica in round-robin order, or nil if
+// none are healthy.
+func (s *Store) pick() *replica {
+	if len(s.replicas) == 0 {
+		return nil
+	}
+	start := s.next.Add(1)
+	for i := 0; i < len(s.replicas); i++ {
+		r := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose queries are all sent to the primary.
+// Replicas may not have replayed a write yet, so reads that must see a write
+// made just before use it.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// route runs fn against a healthy replica, falling back to the primary if
+// there is none or the replica returns an unexpected error.
+func route[T any](ctx context.Context, s *Store, query string, fn func(database.Store) (T, error)) (T, error) {
+	if usePrimary(ctx) {
+		return fn(s.Store)
+	}
+	r := s.pick()
+	if r == nil {
+		return fn(s.Store)
+	}
+	v, err := fn(r.store)
+	if err == nil || errors.Is(err, sql.ErrNoRows) || database.IsQueryCanceledError(err) {
+		return v, err
+	}
+	s.logger.Warn(ctx, "query on read replica failed, falling back to primary",
+		slog.F("replica", r.index),
+		slog.F("query", query),
+		slog.Error(err),
+	)
+	// The next health check will put the replica back into rotation if it
+	// recovers.
+	r.healthy.Store(false)
+	return fn(s.Store)
+}
+
+func (s *Store) GetAuditLogsKeyset(ctx context.Context, arg database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	return route(ctx, s, "GetAuditLogsKeyset", func(db database.Store) ([]database.GetAuditLogsKeysetRow, error) {
+		return db.GetAuditLogsKeyset(ctx, arg)
+	})
+}
+
+func (s *Store) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
+	return route(ctx, s, "GetAuditLogsOffset", func(db database.Store) ([]database.GetAuditLogsOffsetRow, error) {
+		return db.GetAuditLogsOffset(ctx, arg)
+	})
+}
+
+func (s *Store) GetTemplates(ctx context.Context) ([]database.Template, error) {
+	return route(ctx, s, "GetTemplates", func(db database.Store) ([]database.Template, error) {
+		return db.GetTemplates(ctx)
+	})
+}
+
+func (s *Store) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
+	return route(ctx, s, "GetTemplatesWithFilter", func(db database.Store) ([]database.Template, error) {
+		return db.GetTemplatesWithFilter(ctx, arg)
+	})
+}
+
+func (s *Store) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
+	return route(ctx, s, "GetAuthorizedTemplates", func(db database.Store) ([]database.Template, error) {
+		return db.GetAuthorizedTemplates(ctx, arg, prepared)
+	})
+}
+
+func (s *Store) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
+	return route(ctx, s, "GetWorkspaces", func(db database.Store) ([]database.GetWorkspacesRow, error) {
+		return db.GetWorkspaces(ctx, arg)
+	})
+}
+
+func (s *Store) GetAuthorizedWorkspaces(ctx context.Context, arg database.GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspacesRow, error) {
+	return route(ctx, s, "GetAuthorizedWorkspaces", func(db database.Store) ([]database.GetWorkspacesRow, error) {
+		return db.GetAuthorizedWorkspaces(ctx, arg, prepared)
+	})
+}
@@ -0,0 +1,123 @@
+package dbreplica_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbreplica"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/migrations"
+	"github.com/coder/coder/v2/coderd/database/postgres"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test requires postgres")
+	}
+
+	connectionURL, closePg, err := postgres.Open()
+	require.NoError(t, err)
+	t.Cleanup(closePg)
+	sqlDB, err := sql.Open("postgres", connectionURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, migrations.Up(sqlDB))
+
+	t.Run("RoutesToHealthyReplica", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		// Both "replicas" point at the primary, so any of them can serve the
+		// query.
+		store, err := dbreplica.Open(ctx, "postgres", sqlDB, []string{connectionURL, connectionURL}, dbreplica.Options{
+			Logger: slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+
+		require.Equal(t, 2, store.HealthyReplicas())
+		_, err = store.GetTemplates(ctx)
+		require.NoError(t, err)
+		require.Contains(t, store.Wrappers(), "dbreplica.Store")
+	})
+
+	t.Run("FallsBackToPrimary", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		store, err := dbreplica.Open(ctx, "postgres", sqlDB, []string{"postgres://unreachable.invalid:5432/coder?sslmode=disable&connect_timeout=1"}, dbreplica.Options{
+			Logger: slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = store.Close() })
+
+		require.Equal(t, 0, store.HealthyReplicas())
+		_, err = store.GetTemplates(ctx)
+		require.NoError(t, err)
+	})
+	t.Run("ReadsFromReplica", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		// Use a separate database as the replica, so it is visible which
+		// database served each query.
+		replicaURL, closeReplica, err := postgres.Open()
+		require.NoError(t, err)
+		t.Cleanup(closeReplica)
+		replicaDB, err := sql.Open("postgres", replicaURL)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = replicaDB.Close() })
+		require.NoError(t, migrations.Up(replicaDB))
+		primaryURL, closePrimary, err := postgres.Open()
+		require.NoError(t, err)
+		t.Cleanup(closePrimary)
+		primaryDB, err := sql.Open("postgres", primaryURL)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = primaryDB.Close() })
+		require.NoError(t, migrations.Up(primaryDB))
+
+		onReplica := dbgen.AuditLog(t, database.New(replicaDB), database.AuditLog{})
+		store := dbreplica.New(ctx, primaryDB, []*sql.DB{replicaDB}, dbreplica.Options{
+			Logger: slogtest.Make(t, nil),
+		})
+		t.Cleanup(func() { _ = store.Close() })
+
+		// Listings are read from the replica.
+		logs, err := store.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, onReplica.ID, logs[0].ID)
+
+		// Writes go to the primary.
+		onPrimary := dbgen.AuditLog(t, store, database.AuditLog{})
+		logs, err = database.New(primaryDB).GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, onPrimary.ID, logs[0].ID)
+
+		// Reads within a transaction use the primary too.
+		err = store.InTx(func(tx database.Store) error {
+			logs, err := tx.GetAuditLogsOffset(ctx, database.GetAuditLogsOffsetParams{Limit: 10})
+			if err != nil {
+				return err
+			}
+			require.Len(t, logs, 1)
+			require.Equal(t, onPrimary.ID, logs[0].ID)
+			return nil
+		}, nil)
+		require.NoError(t, err)
+
+		// Reads that must see an earlier write can ask for the primary.
+		logs, err = store.GetAuditLogsOffset(dbreplica.WithPrimary(ctx), database.GetAuditLogsOffsetParams{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		require.Equal(t, onPrimary.ID, logs[0].ID)
+	})
+}
@@ -0,0 +1,278 @@
+// Package dbsnapshot exports the state of a deployment from its database and
+// imports it into another database, e.g. to move a deployment between
+// PostgreSQL instances without pg_dump.
+package dbsnapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/migrations"
+)
+
+// FormatVersion is the version of the snapshot format. It's bumped on
+// incompatible changes to the layout of snapshots, not to the schema of the
+// tables, which is tracked by Header.SchemaVersion.
+const FormatVersion = 1
+
+// Tables are the tables included in a snapshot, in an order that satisfies
+// their foreign keys on import. Workspace resources, agents, logs and stats
+// aren't included, so workspaces must be restarted on the new deployment.
+//
+// OAuth tokens of user and external auth links are exported as stored. They
+// stay encrypted if database encryption is enabled, in which case the new
+// deployment must be configured with the same encryption keys.
+var Tables = []string{
+	"dbcrypt_keys",
+	"organizations",
+	"users",
+	"organization_members",
+	"groups",
+	"group_members",
+	"user_links",
+	"external_auth_links",
+	"gitsshkeys",
+	"files",
+	"templates",
+	"template_versions",
+	"template_version_parameters",
+	"template_version_variables",
+	"provisioner_jobs",
+	"workspaces",
+	"workspace_builds",
+	"workspace_build_parameters",
+}
+
+// Header is the first entry of a snapshot.
+type Header struct {
+	FormatVersion int `json:"format_version"`
+	// SchemaVersion is the migration version of the exported database. A
+	// snapshot can only be imported into a database at the same version.
+	SchemaVersion int64     `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Summary is the number of rows exported or imported per table.
+type Summary map[string]int
+
+// row is an entry of a snapshot following the header.
+type row struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// Export writes a gzipped snapshot of the database to w. All tables are read
+// in a single repeatable read transaction, so the snapshot is consistent even
+// if coderd is running.
+func Export(ctx context.Context, sqlDB *sql.DB, w io.Writer) (Summary, error) {
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+	err = enc.Encode(Header{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		CreatedAt:     dbtime.Now(),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("write header: %w", err)
+	}
+
+	summary := Summary{}
+	for _, table := range Tables {
+		n, err := exportTable(ctx, tx, enc, table)
+		if err != nil {
+			return nil, xerrors.Errorf("export %s: %w", table, err)
+		}
+		summary[table] = n
+	}
+	err = gz.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return summary, nil
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, enc *json.Encoder, table string) (int, error) {
+	// Table names come from Tables, so they're safe to interpolate.
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT row_to_json(t) FROM %s t", table))
+	if err != nil {
+		return 0, xerrors.Errorf("query rows: %w", err)
+	}
+	defer rows.Close()
+
+	var n int
+	for rows.Next() {
+		var data []byte
+		err = rows.Scan(&data)
+		if err != nil {
+			return 0, xerrors.Errorf("scan row: %w", err)
+		}
+		err = enc.Encode(row{Table: table, Row: data})
+		if err != nil {
+			return 0, xerrors.Errorf("write row: %w", err)
+		}
+		n++
+	}
+	return n, rows.Err()
+}
+
+// Import restores a snapshot read from r into a database without users or
+// organizations. The database is migrated first and must end up at the schema
+// version of the snapshot. The import is rolled back if any row fails to
+// insert.
+func Import(ctx context.Context, sqlDB *sql.DB, r io.Reader) (Summary, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, xerrors.Errorf("read gzip header: %w", err)
+	}
+	defer gz.Close()
+	dec := json.NewDecoder(gz)
+
+	var header Header
+	err = dec.Decode(&header)
+	if err != nil {
+		return nil, xerrors.Errorf("read header: %w", err)
+	}
+	if header.FormatVersion != FormatVersion {
+		return nil, xerrors.Errorf("unsupported snapshot format version %d, expected %d", header.FormatVersion, FormatVersion)
+	}
+
+	err = migrations.Up(sqlDB)
+	if err != nil {
+		return nil, xerrors.Errorf("migrate database: %w", err)
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if version != header.SchemaVersion {
+		return nil, xerrors.Errorf("snapshot was exported at schema version %d but the database is at version %d, import it with the Coder version it was exported with", header.SchemaVersion, version)
+	}
+	for _, table := range []string{"organizations", "users"} {
+		var exists bool
+		err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s)", table)).Scan(&exists)
+		if err != nil {
+			return nil, xerrors.Errorf("check %s: %w", table, err)
+		}
+		if exists {
+			return nil, xerrors.Errorf("the database already has %s, snapshots can only be imported into a new deployment", table)
+		}
+	}
+
+	summary := Summary{}
+	inserts := map[string]string{}
+	for {
+		var entry row
+		err = dec.Decode(&entry)
+		if xerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read row: %w", err)
+		}
+		if !slices.Contains(Tables, entry.Table) {
+			return nil, xerrors.Errorf("unexpected table %q in snapshot", entry.Table)
+		}
+
+		insert, ok := inserts[entry.Table]
+		if !ok {
+			insert, err = insertQuery(ctx, tx, entry.Table)
+			if err != nil {
+				return nil, xerrors.Errorf("prepare insert into %s: %w", entry.Table, err)
+			}
+			inserts[entry.Table] = insert
+		}
+		_, err = tx.ExecContext(ctx, insert, string(entry.Row))
+		if err != nil {
+			return nil, xerrors.Errorf("insert into %s: %w", entry.Table, err)
+		}
+		summary[entry.Table]++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, xerrors.Errorf("commit: %w", err)
+	}
+	return summary, nil
+}
+
+// insertQuery returns a query inserting a JSON encoded row into the table.
+// Generated columns are skipped, since they can't be inserted into.
+func insertQuery(ctx context.Context, tx *sql.Tx, table string) (string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND is_generated = 'NEVER'
+		ORDER BY ordinal_position`, table)
+	if err != nil {
+		return "", xerrors.Errorf("query columns: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		err = rows.Scan(&column)
+		if err != nil {
+			return "", xerrors.Errorf("scan column: %w", err)
+		}
+		columns = append(columns, `"`+column+`"`)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", xerrors.Errorf("table %q not found", table)
+	}
+
+	list := strings.Join(columns, ", ")
+	return fmt.Sprintf("INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM json_populate_record(NULL::%[1]s, $1::json)", table, list), nil
+}
+
+func schemaVersion(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := tx.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty)
+	if err != nil {
+		return 0, xerrors.Errorf("get schema version: %w", err)
+	}
+	if dirty {
+		return 0, xerrors.Errorf("schema version %d is dirty, a migration failed to apply", version)
+	}
+	return version, nil
+}
@@ -0,0 +1,103 @@
+package dbsnapshot_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbsnapshot"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("this test requires a postgres instance")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	src, ps, srcSQL := dbtestutil.NewDBWithSQLDB(t)
+	org := dbgen.Organization(t, src, database.Organization{})
+	user := dbgen.User(t, src, database.User{})
+	dbgen.OrganizationMember(t, src, database.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+	})
+	file := dbgen.File(t, src, database.File{CreatedBy: user.ID})
+	template := dbgen.Template(t, src, database.Template{
+		OrganizationID: org.ID,
+		CreatedBy:      user.ID,
+	})
+	version := dbgen.TemplateVersion(t, src, database.TemplateVersion{
+		OrganizationID: org.ID,
+		TemplateID:     uuid.NullUUID{UUID: template.ID, Valid: true},
+		CreatedBy:      user.ID,
+	})
+	workspace := dbgen.Workspace(t, src, database.Workspace{
+		OwnerID:        user.ID,
+		OrganizationID: org.ID,
+		TemplateID:     template.ID,
+	})
+	job := dbgen.ProvisionerJob(t, src, ps, database.ProvisionerJob{
+		OrganizationID: org.ID,
+		InitiatorID:    user.ID,
+		FileID:         file.ID,
+	})
+	build := dbgen.WorkspaceBuild(t, src, database.WorkspaceBuild{
+		WorkspaceID:       workspace.ID,
+		TemplateVersionID: version.ID,
+		InitiatorID:       user.ID,
+		JobID:             job.ID,
+		ProvisionerState:  []byte("state"),
+	})
+	dbgen.ExternalAuthLink(t, src, database.ExternalAuthLink{
+		ProviderID: "github",
+		UserID:     user.ID,
+	})
+
+	var buf bytes.Buffer
+	exported, err := dbsnapshot.Export(ctx, srcSQL, &buf)
+	require.NoError(t, err)
+	require.Equal(t, 1, exported["users"])
+	require.Equal(t, 1, exported["workspaces"])
+	require.Equal(t, 1, exported["external_auth_links"])
+
+	dst, _, dstSQL := dbtestutil.NewDBWithSQLDB(t)
+	snapshot := buf.Bytes()
+	imported, err := dbsnapshot.Import(ctx, dstSQL, bytes.NewReader(snapshot))
+	require.NoError(t, err)
+	for table, n := range exported {
+		require.Equal(t, n, imported[table], table)
+	}
+
+	gotUser, err := dst.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, gotUser.Username)
+	require.Equal(t, user.HashedPassword, gotUser.HashedPassword)
+	require.Equal(t, user.RBACRoles, gotUser.RBACRoles)
+
+	gotFile, err := dst.GetFileByID(ctx, file.ID)
+	require.NoError(t, err)
+	require.Equal(t, file.Data, gotFile.Data)
+
+	gotBuild, err := dst.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, build.ID, gotBuild.ID)
+	require.Equal(t, build.ProvisionerState, gotBuild.ProvisionerState)
+
+	gotJob, err := dst.GetProvisionerJobByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, job.JobStatus, gotJob.JobStatus)
+
+	// Importing twice would overwrite the deployment.
+	_, err = dbsnapshot.Import(ctx, dstSQL, bytes.NewReader(snapshot))
+	require.ErrorContains(t, err, "new deployment")
+}
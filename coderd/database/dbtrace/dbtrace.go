@@ -0,0 +1,3401 @@
+// Code generated by scripts/dbgen.
+// Any function can be edited and will not be overwritten.
+// New database functions are automatically generated!
+package dbtrace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/tracing"
+)
+
+var (
+	// Force these imports, for some reason the autogen does not include them.
+	_ uuid.UUID
+	_ rbac.Action
+)
+
+const wrapname = "dbtrace.traceStore"
+
+// New returns a database.Store that starts a span for every query.
+func New(s database.Store, tp trace.TracerProvider) database.Store {
+	// Don't double-wrap.
+	if slices.Contains(s.Wrappers(), wrapname) {
+		return s
+	}
+	return &traceStore{
+		s:      s,
+		tracer: tp.Tracer(tracing.TracerName),
+	}
+}
+
+var _ database.Store = (*traceStore)(nil)
+
+type traceStore struct {
+	s      database.Store
+	tracer trace.Tracer
+}
+
+func (t traceStore) DeleteUnknownLoginFailure(ctx context.Context, arg database.DeleteUnknownLoginFailureParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteUnknownLoginFailure", arg)
+	r0 := t.s.DeleteUnknownLoginFailure(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) GetWorkspaceAgentAuthTokenByIDForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentAuthTokenByIDForUpdate", id)
+	r0, r1 := t.s.GetWorkspaceAgentAuthTokenByIDForUpdate(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+// startSpan starts a child span for the query. The number of values passed
+// to the query is recorded so large batch queries are easy to spot.
+func (t traceStore) startSpan(ctx context.Context, query string, args ...any) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "database."+query, trace.WithAttributes(
+		attribute.String("db.query", query),
+		attribute.Int("db.arg_cardinality", argCardinality(args...)),
+	))
+}
+
+// endSpan records the error status of the query and ends the span. Not found
+// errors are expected in normal operation and are not marked as errors.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// argCardinality counts the values passed to a query. Slices count as their
+// length, and slice fields of generated *Params structs are expanded the same
+// way. Any other struct, such as time.Time or sql.NullString, is one value.
+func argCardinality(args ...any) int {
+	count := 0
+	for _, arg := range args {
+		count += valueCardinality(reflect.ValueOf(arg))
+	}
+	return count
+}
+
+func valueCardinality(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		// UUIDs are arrays and byte slices are opaque values.
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return 1
+		}
+		return v.Len()
+	case reflect.Struct:
+		if !isParamsType(v.Type()) {
+			return 1
+		}
+		count := 0
+		for i := 0; i < v.NumField(); i++ {
+			if k := v.Field(i).Kind(); k == reflect.Slice || k == reflect.Array {
+				count += valueCardinality(v.Field(i))
+				continue
+			}
+			count++
+		}
+		return count
+	default:
+		return 1
+	}
+}
+
+// isParamsType reports whether t is a query parameter struct generated by
+// sqlc, e.g. database.GetUsersParams.
+func isParamsType(t reflect.Type) bool {
+	return t.PkgPath() == reflect.TypeOf(database.GetUsersParams{}).PkgPath() &&
+		strings.HasSuffix(t.Name(), "Params")
+}
+
+func (t traceStore) Wrappers() []string {
+	return append(t.s.Wrappers(), wrapname)
+}
+
+func (t traceStore) InTx(f func(database.Store) error, options *database.TxOptions) error {
+	// InTx is not given a context, so the span for each query inside the
+	// transaction is parented to the context passed to that query.
+	return t.s.InTx(func(tx database.Store) error {
+		return f(&traceStore{s: tx, tracer: t.tracer})
+	}, options)
+}
+
+func (t traceStore) Ping(ctx context.Context) (time.Duration, error) {
+	ctx, span := t.startSpan(ctx, "Ping")
+	r0, r1 := t.s.Ping(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
+	ctx, span := t.startSpan(ctx, "AcquireLock", pgAdvisoryXactLock)
+	r0 := t.s.AcquireLock(ctx, pgAdvisoryXactLock)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) AcquireNotificationMessages(ctx context.Context, arg database.AcquireNotificationMessagesParams) ([]database.NotificationMessage, error) {
+	ctx, span := t.startSpan(ctx, "AcquireNotificationMessages", arg)
+	r0, r1 := t.s.AcquireNotificationMessages(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) AcquireProvisionerJob(ctx context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "AcquireProvisionerJob", arg)
+	r0, r1 := t.s.AcquireProvisionerJob(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) AcquireWebhookDeliveries(ctx context.Context, arg database.AcquireWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	ctx, span := t.startSpan(ctx, "AcquireWebhookDeliveries", arg)
+	r0, r1 := t.s.AcquireWebhookDeliveries(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) ActivityBumpWorkspace(ctx context.Context, arg database.ActivityBumpWorkspaceParams) error {
+	ctx, span := t.startSpan(ctx, "ActivityBumpWorkspace", arg)
+	r0 := t.s.ActivityBumpWorkspace(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "AllUserIDs")
+	r0, r1 := t.s.AllUserIDs(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) ArchiveUnusedTemplateVersions(ctx context.Context, arg database.ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "ArchiveUnusedTemplateVersions", arg)
+	r0, r1 := t.s.ArchiveUnusedTemplateVersions(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) BatchUpdateWorkspaceLastUsedAt(ctx context.Context, arg database.BatchUpdateWorkspaceLastUsedAtParams) error {
+	ctx, span := t.startSpan(ctx, "BatchUpdateWorkspaceLastUsedAt", arg)
+	r0 := t.s.BatchUpdateWorkspaceLastUsedAt(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) CleanTailnetCoordinators(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "CleanTailnetCoordinators")
+	r0 := t.s.CleanTailnetCoordinators(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) CleanTailnetLostPeers(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "CleanTailnetLostPeers")
+	r0 := t.s.CleanTailnetLostPeers(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) CleanTailnetTunnels(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "CleanTailnetTunnels")
+	r0 := t.s.CleanTailnetTunnels(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) CountLoginFailuresByUserID(ctx context.Context, arg database.CountLoginFailuresByUserIDParams) (int64, error) {
+	ctx, span := t.startSpan(ctx, "CountLoginFailuresByUserID", arg)
+	r0, r1 := t.s.CountLoginFailuresByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := t.startSpan(ctx, "CountUnreadInboxNotificationsByUserID", userID)
+	r0, r1 := t.s.CountUnreadInboxNotificationsByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := t.startSpan(ctx, "CountUnusedUserRecoveryCodes", userID)
+	r0, r1 := t.s.CountUnusedUserRecoveryCodes(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) CustomRolesByName(ctx context.Context, lookupRoles []string) ([]database.CustomRole, error) {
+	ctx, span := t.startSpan(ctx, "CustomRolesByName", lookupRoles)
+	r0, r1 := t.s.CustomRolesByName(ctx, lookupRoles)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteAPIKeyByID(ctx context.Context, id string) error {
+	ctx, span := t.startSpan(ctx, "DeleteAPIKeyByID", id)
+	r0 := t.s.DeleteAPIKeyByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteAPIKeyByIDReturning(ctx context.Context, id string) (database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "DeleteAPIKeyByIDReturning", id)
+	r0, r1 := t.s.DeleteAPIKeyByIDReturning(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteAPIKeysByUserID", userID)
+	r0 := t.s.DeleteAPIKeysByUserID(ctx, userID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteAPIKeysByUserIDReturning(ctx context.Context, userID uuid.UUID) ([]database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "DeleteAPIKeysByUserIDReturning", userID)
+	r0, r1 := t.s.DeleteAPIKeysByUserIDReturning(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg database.DeleteAllTailnetClientSubscriptionsParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteAllTailnetClientSubscriptions", arg)
+	r0 := t.s.DeleteAllTailnetClientSubscriptions(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteAllTailnetTunnels(ctx context.Context, arg database.DeleteAllTailnetTunnelsParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteAllTailnetTunnels", arg)
+	r0 := t.s.DeleteAllTailnetTunnels(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteApplicationConnectAPIKeysByUserID", userID)
+	r0 := t.s.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteCoordinator", id)
+	r0 := t.s.DeleteCoordinator(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteExpiredTwoFactorChallenges(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteExpiredTwoFactorChallenges", beforeTime)
+	r0 := t.s.DeleteExpiredTwoFactorChallenges(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteExternalAuthLink(ctx context.Context, arg database.DeleteExternalAuthLinkParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteExternalAuthLink", arg)
+	r0 := t.s.DeleteExternalAuthLink(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteGitSSHKey", userID)
+	r0 := t.s.DeleteGitSSHKey(ctx, userID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteGroupByID", id)
+	r0 := t.s.DeleteGroupByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteGroupMemberFromGroup(ctx context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteGroupMemberFromGroup", arg)
+	r0 := t.s.DeleteGroupMemberFromGroup(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg database.DeleteGroupMembersByOrgAndUserParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteGroupMembersByOrgAndUser", arg)
+	r0 := t.s.DeleteGroupMembersByOrgAndUser(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteLicense(ctx context.Context, id int32) (int32, error) {
+	ctx, span := t.startSpan(ctx, "DeleteLicense", id)
+	r0, r1 := t.s.DeleteLicense(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteLoginFailure(ctx context.Context, arg database.DeleteLoginFailureParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteLoginFailure", arg)
+	r0 := t.s.DeleteLoginFailure(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteOAuth2ProviderAppByID", id)
+	r0 := t.s.DeleteOAuth2ProviderAppByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	ctx, span := t.startSpan(ctx, "DeleteOAuth2ProviderAppCodeByID", id)
+	r0, r1 := t.s.DeleteOAuth2ProviderAppCodeByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteOAuth2ProviderAppSecretByID", id)
+	r0 := t.s.DeleteOAuth2ProviderAppSecretByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldGroupSyncRuns(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldGroupSyncRuns", beforeTime)
+	r0 := t.s.DeleteOldGroupSyncRuns(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldLoginFailures(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldLoginFailures", beforeTime)
+	r0 := t.s.DeleteOldLoginFailures(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldProvisionerDaemons(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldProvisionerDaemons")
+	r0 := t.s.DeleteOldProvisionerDaemons(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldRateLimitCounters(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldRateLimitCounters", beforeTime)
+	r0 := t.s.DeleteOldRateLimitCounters(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldUserActivity(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldUserActivity", beforeTime)
+	r0 := t.s.DeleteOldUserActivity(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldWorkspaceAgentLogs")
+	r0 := t.s.DeleteOldWorkspaceAgentLogs(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldWorkspaceAgentMetadataHistory", beforeTime)
+	r0 := t.s.DeleteOldWorkspaceAgentMetadataHistory(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldWorkspaceAgentStats")
+	r0 := t.s.DeleteOldWorkspaceAgentStats(ctx)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOldWorkspaceEvents(ctx context.Context, beforeTime time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteOldWorkspaceEvents", beforeTime)
+	r0 := t.s.DeleteOldWorkspaceEvents(ctx, beforeTime)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteOrganization", id)
+	r0 := t.s.DeleteOrganization(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOrganizationMember(ctx context.Context, arg database.DeleteOrganizationMemberParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteOrganizationMember", arg)
+	r0 := t.s.DeleteOrganizationMember(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteOrganizationTemplateVariableValue(ctx context.Context, arg database.DeleteOrganizationTemplateVariableValueParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteOrganizationTemplateVariableValue", arg)
+	r0 := t.s.DeleteOrganizationTemplateVariableValue(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeletePasskeyByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeletePasskeyByID", id)
+	r0 := t.s.DeletePasskeyByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteProvisionerJobLogsByJobID", jobID)
+	r0 := t.s.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteProvisionerKey", id)
+	r0 := t.s.DeleteProvisionerKey(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	ctx, span := t.startSpan(ctx, "DeleteReplicasUpdatedBefore", updatedAt)
+	r0 := t.s.DeleteReplicasUpdatedBefore(ctx, updatedAt)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteSessionRecordingByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteSessionRecordingByID", id)
+	r0 := t.s.DeleteSessionRecordingByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteTailnetAgent(ctx context.Context, arg database.DeleteTailnetAgentParams) (database.DeleteTailnetAgentRow, error) {
+	ctx, span := t.startSpan(ctx, "DeleteTailnetAgent", arg)
+	r0, r1 := t.s.DeleteTailnetAgent(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteTailnetClient(ctx context.Context, arg database.DeleteTailnetClientParams) (database.DeleteTailnetClientRow, error) {
+	ctx, span := t.startSpan(ctx, "DeleteTailnetClient", arg)
+	r0, r1 := t.s.DeleteTailnetClient(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteTailnetClientSubscription(ctx context.Context, arg database.DeleteTailnetClientSubscriptionParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteTailnetClientSubscription", arg)
+	r0 := t.s.DeleteTailnetClientSubscription(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteTailnetPeer(ctx context.Context, arg database.DeleteTailnetPeerParams) (database.DeleteTailnetPeerRow, error) {
+	ctx, span := t.startSpan(ctx, "DeleteTailnetPeer", arg)
+	r0, r1 := t.s.DeleteTailnetPeer(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteTailnetTunnel(ctx context.Context, arg database.DeleteTailnetTunnelParams) (database.DeleteTailnetTunnelRow, error) {
+	ctx, span := t.startSpan(ctx, "DeleteTailnetTunnel", arg)
+	r0, r1 := t.s.DeleteTailnetTunnel(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteTemplateVariableValue(ctx context.Context, arg database.DeleteTemplateVariableValueParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteTemplateVariableValue", arg)
+	r0 := t.s.DeleteTemplateVariableValue(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteTemplateVersionPreset(ctx context.Context, arg database.DeleteTemplateVersionPresetParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteTemplateVersionPreset", arg)
+	r0 := t.s.DeleteTemplateVersionPreset(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteTwoFactorChallengeByID(ctx context.Context, id uuid.UUID) (database.TwoFactorChallenge, error) {
+	ctx, span := t.startSpan(ctx, "DeleteTwoFactorChallengeByID", id)
+	r0, r1 := t.s.DeleteTwoFactorChallengeByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) DeleteTwoFactorPolicyByRole(ctx context.Context, role string) error {
+	ctx, span := t.startSpan(ctx, "DeleteTwoFactorPolicyByRole", role)
+	r0 := t.s.DeleteTwoFactorPolicyByRole(ctx, role)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteUserLockout(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteUserLockout", userID)
+	r0 := t.s.DeleteUserLockout(ctx, userID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteUserRecoveryCodesByUserID", userID)
+	r0 := t.s.DeleteUserRecoveryCodesByUserID(ctx, userID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteWebhookByID", id)
+	r0 := t.s.DeleteWebhookByID(ctx, id)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteWorkspaceAgentPortShare(ctx context.Context, arg database.DeleteWorkspaceAgentPortShareParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteWorkspaceAgentPortShare", arg)
+	r0 := t.s.DeleteWorkspaceAgentPortShare(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx context.Context, agentID uuid.UUID) error {
+	ctx, span := t.startSpan(ctx, "DeleteWorkspaceAgentPreviousAuthTokenByAgentID", agentID)
+	r0 := t.s.DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx, agentID)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) DeleteWorkspaceFavorite(ctx context.Context, arg database.DeleteWorkspaceFavoriteParams) error {
+	ctx, span := t.startSpan(ctx, "DeleteWorkspaceFavorite", arg)
+	r0 := t.s.DeleteWorkspaceFavorite(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetAPIKeyByID", id)
+	r0, r1 := t.s.GetAPIKeyByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAPIKeyByName(ctx context.Context, arg database.GetAPIKeyByNameParams) (database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetAPIKeyByName", arg)
+	r0, r1 := t.s.GetAPIKeyByName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAPIKeysByLoginType(ctx context.Context, loginType database.LoginType) ([]database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetAPIKeysByLoginType", loginType)
+	r0, r1 := t.s.GetAPIKeysByLoginType(ctx, loginType)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAPIKeysByUserID(ctx context.Context, arg database.GetAPIKeysByUserIDParams) ([]database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetAPIKeysByUserID", arg)
+	r0, r1 := t.s.GetAPIKeysByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetAPIKeysLastUsedAfter", lastUsed)
+	r0, r1 := t.s.GetAPIKeysLastUsedAfter(ctx, lastUsed)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetActiveSessionsByUserID(ctx context.Context, arg database.GetActiveSessionsByUserIDParams) ([]database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "GetActiveSessionsByUserID", arg)
+	r0, r1 := t.s.GetActiveSessionsByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetActiveUserCount(ctx context.Context) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetActiveUserCount")
+	r0, r1 := t.s.GetActiveUserCount(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetActiveWorkspaceBuildsByTemplateID", templateID)
+	r0, r1 := t.s.GetActiveWorkspaceBuildsByTemplateID(ctx, templateID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAllTailnetAgents(ctx context.Context) ([]database.TailnetAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetAllTailnetAgents")
+	r0, r1 := t.s.GetAllTailnetAgents(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAllTailnetCoordinators(ctx context.Context) ([]database.TailnetCoordinator, error) {
+	ctx, span := t.startSpan(ctx, "GetAllTailnetCoordinators")
+	r0, r1 := t.s.GetAllTailnetCoordinators(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAllTailnetPeers(ctx context.Context) ([]database.TailnetPeer, error) {
+	ctx, span := t.startSpan(ctx, "GetAllTailnetPeers")
+	r0, r1 := t.s.GetAllTailnetPeers(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAllTailnetTunnels(ctx context.Context) ([]database.TailnetTunnel, error) {
+	ctx, span := t.startSpan(ctx, "GetAllTailnetTunnels")
+	r0, r1 := t.s.GetAllTailnetTunnels(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAppSecurityKey(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetAppSecurityKey")
+	r0, r1 := t.s.GetAppSecurityKey(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetApplicationName(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetApplicationName")
+	r0, r1 := t.s.GetApplicationName(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuditLogsKeyset(ctx context.Context, arg database.GetAuditLogsKeysetParams) ([]database.GetAuditLogsKeysetRow, error) {
+	ctx, span := t.startSpan(ctx, "GetAuditLogsKeyset", arg)
+	r0, r1 := t.s.GetAuditLogsKeyset(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
+	ctx, span := t.startSpan(ctx, "GetAuditLogsOffset", arg)
+	r0, r1 := t.s.GetAuditLogsOffset(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
+	ctx, span := t.startSpan(ctx, "GetAuthorizationUserRoles", userID)
+	r0, r1 := t.s.GetAuthorizationUserRoles(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetConnectionLogsOffset(ctx context.Context, arg database.GetConnectionLogsOffsetParams) ([]database.GetConnectionLogsOffsetRow, error) {
+	ctx, span := t.startSpan(ctx, "GetConnectionLogsOffset", arg)
+	r0, r1 := t.s.GetConnectionLogsOffset(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDBCryptKeys(ctx context.Context) ([]database.DBCryptKey, error) {
+	ctx, span := t.startSpan(ctx, "GetDBCryptKeys")
+	r0, r1 := t.s.GetDBCryptKeys(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDERPMeshKey(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetDERPMeshKey")
+	r0, r1 := t.s.GetDERPMeshKey(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDefaultProxyConfig(ctx context.Context) (database.GetDefaultProxyConfigRow, error) {
+	ctx, span := t.startSpan(ctx, "GetDefaultProxyConfig")
+	r0, r1 := t.s.GetDefaultProxyConfig(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]database.GetDeploymentDAUsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetDeploymentDAUs", tzOffset)
+	r0, r1 := t.s.GetDeploymentDAUs(ctx, tzOffset)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDeploymentID(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetDeploymentID")
+	r0, r1 := t.s.GetDeploymentID(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (database.GetDeploymentWorkspaceAgentStatsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetDeploymentWorkspaceAgentStats", createdAt)
+	r0, r1 := t.s.GetDeploymentWorkspaceAgentStats(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDeploymentWorkspaceStats(ctx context.Context) (database.GetDeploymentWorkspaceStatsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetDeploymentWorkspaceStats")
+	r0, r1 := t.s.GetDeploymentWorkspaceStats(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetDriftedWorkspaceCount(ctx context.Context) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetDriftedWorkspaceCount")
+	r0, r1 := t.s.GetDriftedWorkspaceCount(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetExpiredSessionRecordings(ctx context.Context, arg database.GetExpiredSessionRecordingsParams) ([]database.SessionRecording, error) {
+	ctx, span := t.startSpan(ctx, "GetExpiredSessionRecordings", arg)
+	r0, r1 := t.s.GetExpiredSessionRecordings(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetExternalAuthLink(ctx context.Context, arg database.GetExternalAuthLinkParams) (database.ExternalAuthLink, error) {
+	ctx, span := t.startSpan(ctx, "GetExternalAuthLink", arg)
+	r0, r1 := t.s.GetExternalAuthLink(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.ExternalAuthLink, error) {
+	ctx, span := t.startSpan(ctx, "GetExternalAuthLinksByUserID", userID)
+	r0, r1 := t.s.GetExternalAuthLinksByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetFavoriteWorkspaceIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "GetFavoriteWorkspaceIDsByUserID", userID)
+	r0, r1 := t.s.GetFavoriteWorkspaceIDsByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
+	ctx, span := t.startSpan(ctx, "GetFileByHashAndCreator", arg)
+	r0, r1 := t.s.GetFileByHashAndCreator(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetFileByID(ctx context.Context, id uuid.UUID) (database.File, error) {
+	ctx, span := t.startSpan(ctx, "GetFileByID", id)
+	r0, r1 := t.s.GetFileByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]database.GetFileTemplatesRow, error) {
+	ctx, span := t.startSpan(ctx, "GetFileTemplates", fileID)
+	r0, r1 := t.s.GetFileTemplates(ctx, fileID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (database.GitSSHKey, error) {
+	ctx, span := t.startSpan(ctx, "GetGitSSHKey", userID)
+	r0, r1 := t.s.GetGitSSHKey(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupByID(ctx context.Context, id uuid.UUID) (database.Group, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupByID", id)
+	r0, r1 := t.s.GetGroupByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupByOrgAndName(ctx context.Context, arg database.GetGroupByOrgAndNameParams) (database.Group, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupByOrgAndName", arg)
+	r0, r1 := t.s.GetGroupByOrgAndName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]database.User, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupMembers", groupID)
+	r0, r1 := t.s.GetGroupMembers(ctx, groupID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupSyncRuns(ctx context.Context, arg database.GetGroupSyncRunsParams) ([]database.GroupSyncRun, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupSyncRuns", arg)
+	r0, r1 := t.s.GetGroupSyncRuns(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupsByOrganizationAndUserID(ctx context.Context, arg database.GetGroupsByOrganizationAndUserIDParams) ([]database.Group, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupsByOrganizationAndUserID", arg)
+	r0, r1 := t.s.GetGroupsByOrganizationAndUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
+	ctx, span := t.startSpan(ctx, "GetGroupsByOrganizationID", organizationID)
+	r0, r1 := t.s.GetGroupsByOrganizationID(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetHealthSettings(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetHealthSettings")
+	r0, r1 := t.s.GetHealthSettings(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetHungProvisionerJobs(ctx context.Context, hungSince time.Time) ([]database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetHungProvisionerJobs", hungSince)
+	r0, r1 := t.s.GetHungProvisionerJobs(ctx, hungSince)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetInactiveUsers(ctx context.Context, inactiveSince time.Time) ([]database.GetInactiveUsersRow, error) {
+	ctx, span := t.startSpan(ctx, "GetInactiveUsers", inactiveSince)
+	r0, r1 := t.s.GetInactiveUsers(ctx, inactiveSince)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetInboxNotificationsByUserID(ctx context.Context, arg database.GetInboxNotificationsByUserIDParams) ([]database.InboxNotification, error) {
+	ctx, span := t.startSpan(ctx, "GetInboxNotificationsByUserID", arg)
+	r0, r1 := t.s.GetInboxNotificationsByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetLastUpdateCheck")
+	r0, r1 := t.s.GetLastUpdateCheck(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLatestWorkspaceAgentStatByAgentID(ctx context.Context, agentID uuid.UUID) (database.WorkspaceAgentStat, error) {
+	ctx, span := t.startSpan(ctx, "GetLatestWorkspaceAgentStatByAgentID", agentID)
+	r0, r1 := t.s.GetLatestWorkspaceAgentStatByAgentID(ctx, agentID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetLatestWorkspaceBuildByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLatestWorkspaceBuilds(ctx context.Context) ([]database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetLatestWorkspaceBuilds")
+	r0, r1 := t.s.GetLatestWorkspaceBuilds(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetLatestWorkspaceBuildsByWorkspaceIDs", ids)
+	r0, r1 := t.s.GetLatestWorkspaceBuildsByWorkspaceIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLicenseByID(ctx context.Context, id int32) (database.License, error) {
+	ctx, span := t.startSpan(ctx, "GetLicenseByID", id)
+	r0, r1 := t.s.GetLicenseByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLicenseGracePeriodStartedAt(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetLicenseGracePeriodStartedAt")
+	r0, r1 := t.s.GetLicenseGracePeriodStartedAt(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLicenses(ctx context.Context) ([]database.License, error) {
+	ctx, span := t.startSpan(ctx, "GetLicenses")
+	r0, r1 := t.s.GetLicenses(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLoginFailure(ctx context.Context, arg database.GetLoginFailureParams) (database.LoginFailure, error) {
+	ctx, span := t.startSpan(ctx, "GetLoginFailure", arg)
+	r0, r1 := t.s.GetLoginFailure(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetLogoURL(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetLogoURL")
+	r0, r1 := t.s.GetLogoURL(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetNotificationMessagesByUserID(ctx context.Context, arg database.GetNotificationMessagesByUserIDParams) ([]database.NotificationMessage, error) {
+	ctx, span := t.startSpan(ctx, "GetNotificationMessagesByUserID", arg)
+	r0, r1 := t.s.GetNotificationMessagesByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]database.NotificationPreference, error) {
+	ctx, span := t.startSpan(ctx, "GetNotificationPreferencesByUserID", userID)
+	r0, r1 := t.s.GetNotificationPreferencesByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderApp, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppByID", id)
+	r0, r1 := t.s.GetOAuth2ProviderAppByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppCode, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppCodeByID", id)
+	r0, r1 := t.s.GetOAuth2ProviderAppCodeByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (database.OAuth2ProviderAppCode, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppCodeByPrefix", secretPrefix)
+	r0, r1 := t.s.GetOAuth2ProviderAppCodeByPrefix(ctx, secretPrefix)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (database.OAuth2ProviderAppSecret, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppSecretByID", id)
+	r0, r1 := t.s.GetOAuth2ProviderAppSecretByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]database.OAuth2ProviderAppSecret, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppSecretsByAppID", appID)
+	r0, r1 := t.s.GetOAuth2ProviderAppSecretsByAppID(ctx, appID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (database.OAuth2ProviderAppToken, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderAppTokenByPrefix", hashPrefix)
+	r0, r1 := t.s.GetOAuth2ProviderAppTokenByPrefix(ctx, hashPrefix)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuth2ProviderApps(ctx context.Context) ([]database.OAuth2ProviderApp, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuth2ProviderApps")
+	r0, r1 := t.s.GetOAuth2ProviderApps(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetOAuthSigningKey")
+	r0, r1 := t.s.GetOAuthSigningKey(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationByID(ctx context.Context, id uuid.UUID) (database.Organization, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationByID", id)
+	r0, r1 := t.s.GetOrganizationByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationByName(ctx context.Context, name string) (database.Organization, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationByName", name)
+	r0, r1 := t.s.GetOrganizationByName(ctx, name)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetOrganizationIDsByMemberIDsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationIDsByMemberIDs", ids)
+	r0, r1 := t.s.GetOrganizationIDsByMemberIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationMemberByUserID(ctx context.Context, arg database.GetOrganizationMemberByUserIDParams) (database.OrganizationMember, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationMemberByUserID", arg)
+	r0, r1 := t.s.GetOrganizationMemberByUserID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]database.GetOrganizationMembersRow, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationMembers", organizationID)
+	r0, r1 := t.s.GetOrganizationMembers(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]database.OrganizationMember, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationMembershipsByUserID", userID)
+	r0, r1 := t.s.GetOrganizationMembershipsByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (database.OrganizationQuota, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationQuota", organizationID)
+	r0, r1 := t.s.GetOrganizationQuota(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationResourceCostRollup(ctx context.Context, arg database.GetOrganizationResourceCostRollupParams) ([]database.GetOrganizationResourceCostRollupRow, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationResourceCostRollup", arg)
+	r0, r1 := t.s.GetOrganizationResourceCostRollup(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationTemplateVariableValues", organizationID)
+	r0, r1 := t.s.GetOrganizationTemplateVariableValues(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizations(ctx context.Context) ([]database.Organization, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizations")
+	r0, r1 := t.s.GetOrganizations(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]database.Organization, error) {
+	ctx, span := t.startSpan(ctx, "GetOrganizationsByUserID", userID)
+	r0, r1 := t.s.GetOrganizationsByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetOrphanedProvisionerJobs(ctx context.Context, staleSince time.Time) ([]database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetOrphanedProvisionerJobs", staleSince)
+	r0, r1 := t.s.GetOrphanedProvisionerJobs(ctx, staleSince)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
+	ctx, span := t.startSpan(ctx, "GetParameterSchemasByJobID", jobID)
+	r0, r1 := t.s.GetParameterSchemasByJobID(ctx, jobID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetPasskeyByID(ctx context.Context, id uuid.UUID) (database.Passkey, error) {
+	ctx, span := t.startSpan(ctx, "GetPasskeyByID", id)
+	r0, r1 := t.s.GetPasskeyByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]database.Passkey, error) {
+	ctx, span := t.startSpan(ctx, "GetPasskeysByUserID", userID)
+	r0, r1 := t.s.GetPasskeysByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetPreviousTemplateVersion", arg)
+	r0, r1 := t.s.GetPreviousTemplateVersion(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerDaemons(ctx context.Context) ([]database.ProvisionerDaemon, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerDaemons")
+	r0, r1 := t.s.GetProvisionerDaemons(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerDaemon, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerDaemonsByOrganization", organizationID)
+	r0, r1 := t.s.GetProvisionerDaemonsByOrganization(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobByID", id)
+	r0, r1 := t.s.GetProvisionerJobByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobIDsToArchiveLogs(ctx context.Context, arg database.GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobIDsToArchiveLogs", arg)
+	r0, r1 := t.s.GetProvisionerJobIDsToArchiveLogs(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (database.ProvisionerJobLogArchive, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobLogArchiveByJobID", jobID)
+	r0, r1 := t.s.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobLogArchiveStats(ctx context.Context) (database.GetProvisionerJobLogArchiveStatsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobLogArchiveStats")
+	r0, r1 := t.s.GetProvisionerJobLogArchiveStats(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobsByIDs", ids)
+	r0, r1 := t.s.GetProvisionerJobsByIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]database.GetProvisionerJobsByIDsWithQueuePositionRow, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobsByIDsWithQueuePosition", ids)
+	r0, r1 := t.s.GetProvisionerJobsByIDsWithQueuePosition(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerJobsCreatedAfter", createdAt)
+	r0, r1 := t.s.GetProvisionerJobsCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerKeyByHashedSecret", hashedSecret)
+	r0, r1 := t.s.GetProvisionerKeyByHashedSecret(ctx, hashedSecret)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerKeyByID", id)
+	r0, r1 := t.s.GetProvisionerKeyByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerKeyByName(ctx context.Context, arg database.GetProvisionerKeyByNameParams) (database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerKeyByName", arg)
+	r0, r1 := t.s.GetProvisionerKeyByName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
+	ctx, span := t.startSpan(ctx, "GetProvisionerLogsAfterID", arg)
+	r0, r1 := t.s.GetProvisionerLogsAfterID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetQuotaAllowanceForUser", userID)
+	r0, r1 := t.s.GetQuotaAllowanceForUser(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetQuotaConsumedForOrganization", organizationID)
+	r0, r1 := t.s.GetQuotaConsumedForOrganization(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetQuotaConsumedForUser", ownerID)
+	r0, r1 := t.s.GetQuotaConsumedForUser(ctx, ownerID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
+	ctx, span := t.startSpan(ctx, "GetReplicaByID", id)
+	r0, r1 := t.s.GetReplicaByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]database.Replica, error) {
+	ctx, span := t.startSpan(ctx, "GetReplicasUpdatedAfter", updatedAt)
+	r0, r1 := t.s.GetReplicasUpdatedAfter(ctx, updatedAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetRunningWorkspaceCounts(ctx context.Context, arg database.GetRunningWorkspaceCountsParams) (database.GetRunningWorkspaceCountsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetRunningWorkspaceCounts", arg)
+	r0, r1 := t.s.GetRunningWorkspaceCounts(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetServiceBanner(ctx context.Context) (string, error) {
+	ctx, span := t.startSpan(ctx, "GetServiceBanner")
+	r0, r1 := t.s.GetServiceBanner(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (database.SessionRecording, error) {
+	ctx, span := t.startSpan(ctx, "GetSessionRecordingByID", id)
+	r0, r1 := t.s.GetSessionRecordingByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.SessionRecording, error) {
+	ctx, span := t.startSpan(ctx, "GetSessionRecordingsByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]database.TailnetAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetTailnetAgents", id)
+	r0, r1 := t.s.GetTailnetAgents(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]database.TailnetClient, error) {
+	ctx, span := t.startSpan(ctx, "GetTailnetClientsForAgent", agentID)
+	r0, r1 := t.s.GetTailnetClientsForAgent(ctx, agentID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]database.TailnetPeer, error) {
+	ctx, span := t.startSpan(ctx, "GetTailnetPeers", id)
+	r0, r1 := t.s.GetTailnetPeers(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTailnetTunnelPeerBindings(ctx context.Context, srcID uuid.UUID) ([]database.GetTailnetTunnelPeerBindingsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTailnetTunnelPeerBindings", srcID)
+	r0, r1 := t.s.GetTailnetTunnelPeerBindings(ctx, srcID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]database.GetTailnetTunnelPeerIDsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTailnetTunnelPeerIDs", srcID)
+	r0, r1 := t.s.GetTailnetTunnelPeerIDs(ctx, srcID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateAppInsights", arg)
+	r0, r1 := t.s.GetTemplateAppInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateAppInsightsByTemplate(ctx context.Context, arg database.GetTemplateAppInsightsByTemplateParams) ([]database.GetTemplateAppInsightsByTemplateRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateAppInsightsByTemplate", arg)
+	r0, r1 := t.s.GetTemplateAppInsightsByTemplate(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateAverageBuildTime", arg)
+	r0, r1 := t.s.GetTemplateAverageBuildTime(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateBuildInsights(ctx context.Context, arg database.GetTemplateBuildInsightsParams) (database.GetTemplateBuildInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateBuildInsights", arg)
+	r0, r1 := t.s.GetTemplateBuildInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateByID", id)
+	r0, r1 := t.s.GetTemplateByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateByOrganizationAndName(ctx context.Context, arg database.GetTemplateByOrganizationAndNameParams) (database.Template, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateByOrganizationAndName", arg)
+	r0, r1 := t.s.GetTemplateByOrganizationAndName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateDAUs(ctx context.Context, arg database.GetTemplateDAUsParams) ([]database.GetTemplateDAUsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateDAUs", arg)
+	r0, r1 := t.s.GetTemplateDAUs(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateInsights(ctx context.Context, arg database.GetTemplateInsightsParams) (database.GetTemplateInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateInsights", arg)
+	r0, r1 := t.s.GetTemplateInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateInsightsByInterval(ctx context.Context, arg database.GetTemplateInsightsByIntervalParams) ([]database.GetTemplateInsightsByIntervalRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateInsightsByInterval", arg)
+	r0, r1 := t.s.GetTemplateInsightsByInterval(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateInsightsByTemplate(ctx context.Context, arg database.GetTemplateInsightsByTemplateParams) ([]database.GetTemplateInsightsByTemplateRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateInsightsByTemplate", arg)
+	r0, r1 := t.s.GetTemplateInsightsByTemplate(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateParameterInsights(ctx context.Context, arg database.GetTemplateParameterInsightsParams) ([]database.GetTemplateParameterInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateParameterInsights", arg)
+	r0, r1 := t.s.GetTemplateParameterInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVariableValue, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVariableValuesByTemplateID", templateID)
+	r0, r1 := t.s.GetTemplateVariableValuesByTemplateID(ctx, templateID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionByID", id)
+	r0, r1 := t.s.GetTemplateVersionByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionByJobID", jobID)
+	r0, r1 := t.s.GetTemplateVersionByJobID(ctx, jobID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg database.GetTemplateVersionByTemplateIDAndNameParams) (database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionByTemplateIDAndName", arg)
+	r0, r1 := t.s.GetTemplateVersionByTemplateIDAndName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionParameter, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionParameters", templateVersionID)
+	r0, r1 := t.s.GetTemplateVersionParameters(ctx, templateVersionID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionPreset, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionPresets", templateVersionID)
+	r0, r1 := t.s.GetTemplateVersionPresets(ctx, templateVersionID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionVariables", templateVersionID)
+	r0, r1 := t.s.GetTemplateVersionVariables(ctx, templateVersionID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionsByIDs", ids)
+	r0, r1 := t.s.GetTemplateVersionsByIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionsByTemplateID(ctx context.Context, arg database.GetTemplateVersionsByTemplateIDParams) ([]database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionsByTemplateID", arg)
+	r0, r1 := t.s.GetTemplateVersionsByTemplateID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.TemplateVersion, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateVersionsCreatedAfter", createdAt)
+	r0, r1 := t.s.GetTemplateVersionsCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplates(ctx context.Context) ([]database.Template, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplates")
+	r0, r1 := t.s.GetTemplates(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplatesWithFilter(ctx context.Context, arg database.GetTemplatesWithFilterParams) ([]database.Template, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplatesWithFilter", arg)
+	r0, r1 := t.s.GetTemplatesWithFilter(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "GetTimedOutProvisionerJobs", now)
+	r0, r1 := t.s.GetTimedOutProvisionerJobs(ctx, now)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTwoFactorPolicies(ctx context.Context) ([]database.TwoFactorPolicy, error) {
+	ctx, span := t.startSpan(ctx, "GetTwoFactorPolicies")
+	r0, r1 := t.s.GetTwoFactorPolicies(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTwoFactorPolicyByRole(ctx context.Context, role string) (database.TwoFactorPolicy, error) {
+	ctx, span := t.startSpan(ctx, "GetTwoFactorPolicyByRole", role)
+	r0, r1 := t.s.GetTwoFactorPolicyByRole(ctx, role)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUnexpiredLicenses(ctx context.Context) ([]database.License, error) {
+	ctx, span := t.startSpan(ctx, "GetUnexpiredLicenses")
+	r0, r1 := t.s.GetUnexpiredLicenses(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUnknownLoginFailure(ctx context.Context, arg database.GetUnknownLoginFailureParams) (database.UnknownLoginFailure, error) {
+	ctx, span := t.startSpan(ctx, "GetUnknownLoginFailure", arg)
+	r0, r1 := t.s.GetUnknownLoginFailure(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUnknownLoginFailureTotals(ctx context.Context, arg database.GetUnknownLoginFailureTotalsParams) (database.GetUnknownLoginFailureTotalsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetUnknownLoginFailureTotals", arg)
+	r0, r1 := t.s.GetUnknownLoginFailureTotals(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]database.UserRecoveryCode, error) {
+	ctx, span := t.startSpan(ctx, "GetUnusedUserRecoveryCodes", userID)
+	r0, r1 := t.s.GetUnusedUserRecoveryCodes(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserActivityInsights(ctx context.Context, arg database.GetUserActivityInsightsParams) ([]database.GetUserActivityInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetUserActivityInsights", arg)
+	r0, r1 := t.s.GetUserActivityInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "GetUserByEmailOrUsername", arg)
+	r0, r1 := t.s.GetUserByEmailOrUsername(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "GetUserByID", id)
+	r0, r1 := t.s.GetUserByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserCount(ctx context.Context) (int64, error) {
+	ctx, span := t.startSpan(ctx, "GetUserCount")
+	r0, r1 := t.s.GetUserCount(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLatencyInsights", arg)
+	r0, r1 := t.s.GetUserLatencyInsights(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLinkByLinkedID", linkedID)
+	r0, r1 := t.s.GetUserLinkByLinkedID(ctx, linkedID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg database.GetUserLinkByUserIDLoginTypeParams) (database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLinkByUserIDLoginType", arg)
+	r0, r1 := t.s.GetUserLinkByUserIDLoginType(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLinksByUserID", userID)
+	r0, r1 := t.s.GetUserLinksByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (database.UserLockout, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLockoutByUserID", userID)
+	r0, r1 := t.s.GetUserLockoutByUserID(ctx, userID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserLockouts(ctx context.Context, now time.Time) ([]database.GetUserLockoutsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetUserLockouts", now)
+	r0, r1 := t.s.GetUserLockouts(ctx, now)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUserSCIMExternalIDByExternalID(ctx context.Context, externalID string) (database.UserSCIMExternalID, error) {
+	ctx, span := t.startSpan(ctx, "GetUserSCIMExternalIDByExternalID", externalID)
+	r0, r1 := t.s.GetUserSCIMExternalIDByExternalID(ctx, externalID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
+	ctx, span := t.startSpan(ctx, "GetUsers", arg)
+	r0, r1 := t.s.GetUsers(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]database.User, error) {
+	ctx, span := t.startSpan(ctx, "GetUsersByIDs", ids)
+	r0, r1 := t.s.GetUsersByIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	ctx, span := t.startSpan(ctx, "GetWebhookByID", id)
+	r0, r1 := t.s.GetWebhookByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg database.GetWebhookDeliveriesByWebhookIDParams) ([]database.WebhookDelivery, error) {
+	ctx, span := t.startSpan(ctx, "GetWebhookDeliveriesByWebhookID", arg)
+	r0, r1 := t.s.GetWebhookDeliveriesByWebhookID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (database.WebhookDelivery, error) {
+	ctx, span := t.startSpan(ctx, "GetWebhookDeliveryByID", id)
+	r0, r1 := t.s.GetWebhookDeliveryByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	ctx, span := t.startSpan(ctx, "GetWebhooks")
+	r0, r1 := t.s.GetWebhooks(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWebhooksByEvent(ctx context.Context, event string) ([]database.Webhook, error) {
+	ctx, span := t.startSpan(ctx, "GetWebhooksByEvent", event)
+	r0, r1 := t.s.GetWebhooksByEvent(ctx, event)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentAndOwnerByAuthToken", authToken)
+	r0, r1 := t.s.GetWorkspaceAgentAndOwnerByAuthToken(ctx, authToken)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentByID", id)
+	r0, r1 := t.s.GetWorkspaceAgentByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentByInstanceID", authInstanceID)
+	r0, r1 := t.s.GetWorkspaceAgentByInstanceID(ctx, authInstanceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentCountsByLifecycleState")
+	r0, r1 := t.s.GetWorkspaceAgentCountsByLifecycleState(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentGPUsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentGPU, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentGPUsByAgentIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAgentGPUsByAgentIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentLifecycleStateByID", id)
+	r0, r1 := t.s.GetWorkspaceAgentLifecycleStateByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentLogSource, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentLogSourcesByAgentIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAgentLogSourcesByAgentIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentLogsAfter(ctx context.Context, arg database.GetWorkspaceAgentLogsAfterParams) ([]database.WorkspaceAgentLog, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentLogsAfter", arg)
+	r0, r1 := t.s.GetWorkspaceAgentLogsAfter(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID database.GetWorkspaceAgentMetadataParams) ([]database.WorkspaceAgentMetadatum, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentMetadata", workspaceAgentID)
+	r0, r1 := t.s.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentMetadataHistory", arg)
+	r0, r1 := t.s.GetWorkspaceAgentMetadataHistory(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentPortShare(ctx context.Context, arg database.GetWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentPortShare", arg)
+	r0, r1 := t.s.GetWorkspaceAgentPortShare(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentPortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgentPortShare, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentPortSharesByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetWorkspaceAgentPortSharesByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentPortsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentPort, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentPortsByAgentIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAgentPortsByAgentIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentResourceUsageAndLabels", createdAt)
+	r0, r1 := t.s.GetWorkspaceAgentResourceUsageAndLabels(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgentScript, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentScriptsByAgentIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAgentScriptsByAgentIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentStats", createdAt)
+	r0, r1 := t.s.GetWorkspaceAgentStats(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsAndLabelsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentStatsAndLabels", createdAt)
+	r0, r1 := t.s.GetWorkspaceAgentStatsAndLabels(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentsByResourceIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAgentsByResourceIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentsCreatedAfter", createdAt)
+	r0, r1 := t.s.GetWorkspaceAgentsCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAgentsInLatestBuildByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAppByAgentIDAndSlug", arg)
+	r0, r1 := t.s.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAppCountsByHealth(ctx context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAppCountsByHealth")
+	r0, r1 := t.s.GetWorkspaceAppCountsByHealth(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAppsByAgentID", agentID)
+	r0, r1 := t.s.GetWorkspaceAppsByAgentID(ctx, agentID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceApp, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAppsByAgentIDs", ids)
+	r0, r1 := t.s.GetWorkspaceAppsByAgentIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceApp, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceAppsCreatedAfter", createdAt)
+	r0, r1 := t.s.GetWorkspaceAppsCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildByID", id)
+	r0, r1 := t.s.GetWorkspaceBuildByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildByJobID", jobID)
+	r0, r1 := t.s.GetWorkspaceBuildByJobID(ctx, jobID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildByWorkspaceIDAndBuildNumber", arg)
+	r0, r1 := t.s.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]database.WorkspaceBuildParameter, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildParameters", workspaceBuildID)
+	r0, r1 := t.s.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildsByWorkspaceID", arg)
+	r0, r1 := t.s.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceBuild, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceBuildsCreatedAfter", createdAt)
+	r0, r1 := t.s.GetWorkspaceBuildsCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (database.GetWorkspaceByAgentIDRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceByAgentID", agentID)
+	r0, r1 := t.s.GetWorkspaceByAgentID(ctx, agentID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceByID", id)
+	r0, r1 := t.s.GetWorkspaceByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndNameParams) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceByOwnerIDAndName", arg)
+	r0, r1 := t.s.GetWorkspaceByOwnerIDAndName(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceByWorkspaceAppID", workspaceAppID)
+	r0, r1 := t.s.GetWorkspaceByWorkspaceAppID(ctx, workspaceAppID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceCountsByTemplateAndStatus")
+	r0, r1 := t.s.GetWorkspaceCountsByTemplateAndStatus(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceDriftByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceDrift, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceDriftByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetWorkspaceDriftByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceIDsForDriftCheck", lastCheckBefore)
+	r0, r1 := t.s.GetWorkspaceIDsForDriftCheck(ctx, lastCheckBefore)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceParameterChanges(ctx context.Context, arg database.GetWorkspaceParameterChangesParams) ([]database.GetWorkspaceParameterChangesRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceParameterChanges", arg)
+	r0, r1 := t.s.GetWorkspaceParameterChanges(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceProxies")
+	r0, r1 := t.s.GetWorkspaceProxies(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceProxyByHostname(ctx context.Context, arg database.GetWorkspaceProxyByHostnameParams) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceProxyByHostname", arg)
+	r0, r1 := t.s.GetWorkspaceProxyByHostname(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceProxyByID", id)
+	r0, r1 := t.s.GetWorkspaceProxyByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceProxyByName(ctx context.Context, name string) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceProxyByName", name)
+	r0, r1 := t.s.GetWorkspaceProxyByName(ctx, name)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceProxyHealth(ctx context.Context) ([]database.WorkspaceProxyHealth, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceProxyHealth")
+	r0, r1 := t.s.GetWorkspaceProxyHealth(ctx)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (database.WorkspaceResource, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourceByID", id)
+	r0, r1 := t.s.GetWorkspaceResourceByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourceCosts(ctx context.Context, arg database.GetWorkspaceResourceCostsParams) ([]database.WorkspaceResourceCost, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourceCosts", arg)
+	r0, r1 := t.s.GetWorkspaceResourceCosts(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResourceMetadatum, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourceMetadataByResourceIDs", ids)
+	r0, r1 := t.s.GetWorkspaceResourceMetadataByResourceIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceResourceMetadatum, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourceMetadataCreatedAfter", createdAt)
+	r0, r1 := t.s.GetWorkspaceResourceMetadataCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]database.WorkspaceResource, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourcesByJobID", jobID)
+	r0, r1 := t.s.GetWorkspaceResourcesByJobID(ctx, jobID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]database.WorkspaceResource, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourcesByJobIDs", ids)
+	r0, r1 := t.s.GetWorkspaceResourcesByJobIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]database.WorkspaceResource, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceResourcesCreatedAfter", createdAt)
+	r0, r1 := t.s.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (database.WorkspaceSnapshot, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceSnapshotByID", id)
+	r0, r1 := t.s.GetWorkspaceSnapshotByID(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceSnapshotsByWorkspaceID", workspaceID)
+	r0, r1 := t.s.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspaceID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceTimeline(ctx context.Context, arg database.GetWorkspaceTimelineParams) ([]database.GetWorkspaceTimelineRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceTimeline", arg)
+	r0, r1 := t.s.GetWorkspaceTimeline(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]database.GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaceUniqueOwnerCountByTemplateIDs", templateIds)
+	r0, r1 := t.s.GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx, templateIds)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspaces", arg)
+	r0, r1 := t.s.GetWorkspaces(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspacesByIDs(ctx context.Context, ids []uuid.UUID) ([]database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspacesByIDs", ids)
+	r0, r1 := t.s.GetWorkspacesByIDs(ctx, ids)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "GetWorkspacesEligibleForTransition", now)
+	r0, r1 := t.s.GetWorkspacesEligibleForTransition(ctx, now)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) IncrementRateLimitCounter(ctx context.Context, arg database.IncrementRateLimitCounterParams) (int32, error) {
+	ctx, span := t.startSpan(ctx, "IncrementRateLimitCounter", arg)
+	r0, r1 := t.s.IncrementRateLimitCounter(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertAPIKey(ctx context.Context, arg database.InsertAPIKeyParams) (database.APIKey, error) {
+	ctx, span := t.startSpan(ctx, "InsertAPIKey", arg)
+	r0, r1 := t.s.InsertAPIKey(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (database.Group, error) {
+	ctx, span := t.startSpan(ctx, "InsertAllUsersGroup", organizationID)
+	r0, r1 := t.s.InsertAllUsersGroup(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertAuditLog(ctx context.Context, arg database.InsertAuditLogParams) (database.AuditLog, error) {
+	ctx, span := t.startSpan(ctx, "InsertAuditLog", arg)
+	r0, r1 := t.s.InsertAuditLog(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertConnectionLog(ctx context.Context, arg database.InsertConnectionLogParams) (database.ConnectionLog, error) {
+	ctx, span := t.startSpan(ctx, "InsertConnectionLog", arg)
+	r0, r1 := t.s.InsertConnectionLog(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertDBCryptKey(ctx context.Context, arg database.InsertDBCryptKeyParams) error {
+	ctx, span := t.startSpan(ctx, "InsertDBCryptKey", arg)
+	r0 := t.s.InsertDBCryptKey(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertDERPMeshKey(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "InsertDERPMeshKey", value)
+	r0 := t.s.InsertDERPMeshKey(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertDeploymentID(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "InsertDeploymentID", value)
+	r0 := t.s.InsertDeploymentID(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertExternalAuthLink(ctx context.Context, arg database.InsertExternalAuthLinkParams) (database.ExternalAuthLink, error) {
+	ctx, span := t.startSpan(ctx, "InsertExternalAuthLink", arg)
+	r0, r1 := t.s.InsertExternalAuthLink(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertFile(ctx context.Context, arg database.InsertFileParams) (database.File, error) {
+	ctx, span := t.startSpan(ctx, "InsertFile", arg)
+	r0, r1 := t.s.InsertFile(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertGitSSHKey(ctx context.Context, arg database.InsertGitSSHKeyParams) (database.GitSSHKey, error) {
+	ctx, span := t.startSpan(ctx, "InsertGitSSHKey", arg)
+	r0, r1 := t.s.InsertGitSSHKey(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertGroup(ctx context.Context, arg database.InsertGroupParams) (database.Group, error) {
+	ctx, span := t.startSpan(ctx, "InsertGroup", arg)
+	r0, r1 := t.s.InsertGroup(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
+	ctx, span := t.startSpan(ctx, "InsertGroupMember", arg)
+	r0 := t.s.InsertGroupMember(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertGroupSyncRun(ctx context.Context, arg database.InsertGroupSyncRunParams) (database.GroupSyncRun, error) {
+	ctx, span := t.startSpan(ctx, "InsertGroupSyncRun", arg)
+	r0, r1 := t.s.InsertGroupSyncRun(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertInboxNotification(ctx context.Context, arg database.InsertInboxNotificationParams) (database.InboxNotification, error) {
+	ctx, span := t.startSpan(ctx, "InsertInboxNotification", arg)
+	r0, r1 := t.s.InsertInboxNotification(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertLicense(ctx context.Context, arg database.InsertLicenseParams) (database.License, error) {
+	ctx, span := t.startSpan(ctx, "InsertLicense", arg)
+	r0, r1 := t.s.InsertLicense(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertMissingGroups(ctx context.Context, arg database.InsertMissingGroupsParams) ([]database.Group, error) {
+	ctx, span := t.startSpan(ctx, "InsertMissingGroups", arg)
+	r0, r1 := t.s.InsertMissingGroups(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertNotificationMessage(ctx context.Context, arg database.InsertNotificationMessageParams) (database.NotificationMessage, error) {
+	ctx, span := t.startSpan(ctx, "InsertNotificationMessage", arg)
+	r0, r1 := t.s.InsertNotificationMessage(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOAuth2ProviderApp(ctx context.Context, arg database.InsertOAuth2ProviderAppParams) (database.OAuth2ProviderApp, error) {
+	ctx, span := t.startSpan(ctx, "InsertOAuth2ProviderApp", arg)
+	r0, r1 := t.s.InsertOAuth2ProviderApp(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOAuth2ProviderAppCode(ctx context.Context, arg database.InsertOAuth2ProviderAppCodeParams) (database.OAuth2ProviderAppCode, error) {
+	ctx, span := t.startSpan(ctx, "InsertOAuth2ProviderAppCode", arg)
+	r0, r1 := t.s.InsertOAuth2ProviderAppCode(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOAuth2ProviderAppSecret(ctx context.Context, arg database.InsertOAuth2ProviderAppSecretParams) (database.OAuth2ProviderAppSecret, error) {
+	ctx, span := t.startSpan(ctx, "InsertOAuth2ProviderAppSecret", arg)
+	r0, r1 := t.s.InsertOAuth2ProviderAppSecret(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOAuth2ProviderAppToken(ctx context.Context, arg database.InsertOAuth2ProviderAppTokenParams) (database.OAuth2ProviderAppToken, error) {
+	ctx, span := t.startSpan(ctx, "InsertOAuth2ProviderAppToken", arg)
+	r0, r1 := t.s.InsertOAuth2ProviderAppToken(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
+	ctx, span := t.startSpan(ctx, "InsertOrganization", arg)
+	r0, r1 := t.s.InsertOrganization(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertOrganizationMember(ctx context.Context, arg database.InsertOrganizationMemberParams) (database.OrganizationMember, error) {
+	ctx, span := t.startSpan(ctx, "InsertOrganizationMember", arg)
+	r0, r1 := t.s.InsertOrganizationMember(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertPasskey(ctx context.Context, arg database.InsertPasskeyParams) (database.Passkey, error) {
+	ctx, span := t.startSpan(ctx, "InsertPasskey", arg)
+	r0, r1 := t.s.InsertPasskey(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertProvisionerJob(ctx context.Context, arg database.InsertProvisionerJobParams) (database.ProvisionerJob, error) {
+	ctx, span := t.startSpan(ctx, "InsertProvisionerJob", arg)
+	r0, r1 := t.s.InsertProvisionerJob(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertProvisionerJobLogArchive(ctx context.Context, arg database.InsertProvisionerJobLogArchiveParams) (database.ProvisionerJobLogArchive, error) {
+	ctx, span := t.startSpan(ctx, "InsertProvisionerJobLogArchive", arg)
+	r0, r1 := t.s.InsertProvisionerJobLogArchive(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
+	ctx, span := t.startSpan(ctx, "InsertProvisionerJobLogs", arg)
+	r0, r1 := t.s.InsertProvisionerJobLogs(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertProvisionerKey(ctx context.Context, arg database.InsertProvisionerKeyParams) (database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "InsertProvisionerKey", arg)
+	r0, r1 := t.s.InsertProvisionerKey(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertReplica(ctx context.Context, arg database.InsertReplicaParams) (database.Replica, error) {
+	ctx, span := t.startSpan(ctx, "InsertReplica", arg)
+	r0, r1 := t.s.InsertReplica(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertSessionRecording(ctx context.Context, arg database.InsertSessionRecordingParams) (database.SessionRecording, error) {
+	ctx, span := t.startSpan(ctx, "InsertSessionRecording", arg)
+	r0, r1 := t.s.InsertSessionRecording(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertTemplate(ctx context.Context, arg database.InsertTemplateParams) error {
+	ctx, span := t.startSpan(ctx, "InsertTemplate", arg)
+	r0 := t.s.InsertTemplate(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
+	ctx, span := t.startSpan(ctx, "InsertTemplateVersion", arg)
+	r0 := t.s.InsertTemplateVersion(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertTemplateVersionParameter(ctx context.Context, arg database.InsertTemplateVersionParameterParams) (database.TemplateVersionParameter, error) {
+	ctx, span := t.startSpan(ctx, "InsertTemplateVersionParameter", arg)
+	r0, r1 := t.s.InsertTemplateVersionParameter(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertTemplateVersionPreset(ctx context.Context, arg database.InsertTemplateVersionPresetParams) (database.TemplateVersionPreset, error) {
+	ctx, span := t.startSpan(ctx, "InsertTemplateVersionPreset", arg)
+	r0, r1 := t.s.InsertTemplateVersionPreset(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
+	ctx, span := t.startSpan(ctx, "InsertTemplateVersionVariable", arg)
+	r0, r1 := t.s.InsertTemplateVersionVariable(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertTwoFactorChallenge(ctx context.Context, arg database.InsertTwoFactorChallengeParams) (database.TwoFactorChallenge, error) {
+	ctx, span := t.startSpan(ctx, "InsertTwoFactorChallenge", arg)
+	r0, r1 := t.s.InsertTwoFactorChallenge(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertUser(ctx context.Context, arg database.InsertUserParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "InsertUser", arg)
+	r0, r1 := t.s.InsertUser(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertUserGroupsByName(ctx context.Context, arg database.InsertUserGroupsByNameParams) error {
+	ctx, span := t.startSpan(ctx, "InsertUserGroupsByName", arg)
+	r0 := t.s.InsertUserGroupsByName(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertUserLink(ctx context.Context, arg database.InsertUserLinkParams) (database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "InsertUserLink", arg)
+	r0, r1 := t.s.InsertUserLink(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertUserRecoveryCode(ctx context.Context, arg database.InsertUserRecoveryCodeParams) error {
+	ctx, span := t.startSpan(ctx, "InsertUserRecoveryCode", arg)
+	r0 := t.s.InsertUserRecoveryCode(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWebhook(ctx context.Context, arg database.InsertWebhookParams) (database.Webhook, error) {
+	ctx, span := t.startSpan(ctx, "InsertWebhook", arg)
+	r0, r1 := t.s.InsertWebhook(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWebhookDelivery(ctx context.Context, arg database.InsertWebhookDeliveryParams) (database.WebhookDelivery, error) {
+	ctx, span := t.startSpan(ctx, "InsertWebhookDelivery", arg)
+	r0, r1 := t.s.InsertWebhookDelivery(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspace(ctx context.Context, arg database.InsertWorkspaceParams) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspace", arg)
+	r0, r1 := t.s.InsertWorkspace(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgent(ctx context.Context, arg database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgent", arg)
+	r0, r1 := t.s.InsertWorkspaceAgent(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgentLogSources(ctx context.Context, arg database.InsertWorkspaceAgentLogSourcesParams) ([]database.WorkspaceAgentLogSource, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentLogSources", arg)
+	r0, r1 := t.s.InsertWorkspaceAgentLogSources(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentLogs", arg)
+	r0, r1 := t.s.InsertWorkspaceAgentLogs(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg database.InsertWorkspaceAgentMetadataParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentMetadata", arg)
+	r0 := t.s.InsertWorkspaceAgentMetadata(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentMetadataHistory", arg)
+	r0 := t.s.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceAgentScripts(ctx context.Context, arg database.InsertWorkspaceAgentScriptsParams) ([]database.WorkspaceAgentScript, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentScripts", arg)
+	r0, r1 := t.s.InsertWorkspaceAgentScripts(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgentStat(ctx context.Context, arg database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentStat", arg)
+	r0, r1 := t.s.InsertWorkspaceAgentStat(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAgentStats(ctx context.Context, arg database.InsertWorkspaceAgentStatsParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAgentStats", arg)
+	r0 := t.s.InsertWorkspaceAgentStats(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceApp(ctx context.Context, arg database.InsertWorkspaceAppParams) (database.WorkspaceApp, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceApp", arg)
+	r0, r1 := t.s.InsertWorkspaceApp(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceAppStats(ctx context.Context, arg database.InsertWorkspaceAppStatsParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceAppStats", arg)
+	r0 := t.s.InsertWorkspaceAppStats(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceBuild", arg)
+	r0 := t.s.InsertWorkspaceBuild(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceBuildParameters(ctx context.Context, arg database.InsertWorkspaceBuildParametersParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceBuildParameters", arg)
+	r0 := t.s.InsertWorkspaceBuildParameters(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceEvent(ctx context.Context, arg database.InsertWorkspaceEventParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceEvent", arg)
+	r0 := t.s.InsertWorkspaceEvent(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceFavorite(ctx context.Context, arg database.InsertWorkspaceFavoriteParams) error {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceFavorite", arg)
+	r0 := t.s.InsertWorkspaceFavorite(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceProxy", arg)
+	r0, r1 := t.s.InsertWorkspaceProxy(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceResource(ctx context.Context, arg database.InsertWorkspaceResourceParams) (database.WorkspaceResource, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceResource", arg)
+	r0, r1 := t.s.InsertWorkspaceResource(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg database.InsertWorkspaceResourceMetadataParams) ([]database.WorkspaceResourceMetadatum, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceResourceMetadata", arg)
+	r0, r1 := t.s.InsertWorkspaceResourceMetadata(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) InsertWorkspaceSnapshot(ctx context.Context, arg database.InsertWorkspaceSnapshotParams) (database.WorkspaceSnapshot, error) {
+	ctx, span := t.startSpan(ctx, "InsertWorkspaceSnapshot", arg)
+	r0, r1 := t.s.InsertWorkspaceSnapshot(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "ListProvisionerKeysByOrganization", organizationID)
+	r0, r1 := t.s.ListProvisionerKeysByOrganization(ctx, organizationID)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) RecordLoginAttempt(ctx context.Context, arg database.RecordLoginAttemptParams) (database.LoginFailure, error) {
+	ctx, span := t.startSpan(ctx, "RecordLoginAttempt", arg)
+	r0, r1 := t.s.RecordLoginAttempt(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) RecordUnknownLoginAttempt(ctx context.Context, arg database.RecordUnknownLoginAttemptParams) (database.UnknownLoginFailure, error) {
+	ctx, span := t.startSpan(ctx, "RecordUnknownLoginAttempt", arg)
+	r0, r1 := t.s.RecordUnknownLoginAttempt(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "RegisterWorkspaceProxy", arg)
+	r0, r1 := t.s.RegisterWorkspaceProxy(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) RequeueProvisionerJobByID(ctx context.Context, arg database.RequeueProvisionerJobByIDParams) error {
+	ctx, span := t.startSpan(ctx, "RequeueProvisionerJobByID", arg)
+	r0 := t.s.RequeueProvisionerJobByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
+	ctx, span := t.startSpan(ctx, "RevokeDBCryptKey", activeKeyDigest)
+	r0 := t.s.RevokeDBCryptKey(ctx, activeKeyDigest)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
+	ctx, span := t.startSpan(ctx, "TryAcquireLock", pgTryAdvisoryXactLock)
+	r0, r1 := t.s.TryAcquireLock(ctx, pgTryAdvisoryXactLock)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UnarchiveTemplateVersion(ctx context.Context, arg database.UnarchiveTemplateVersionParams) error {
+	ctx, span := t.startSpan(ctx, "UnarchiveTemplateVersion", arg)
+	r0 := t.s.UnarchiveTemplateVersion(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKeyByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateAPIKeyByID", arg)
+	r0 := t.s.UpdateAPIKeyByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateExternalAuthLink(ctx context.Context, arg database.UpdateExternalAuthLinkParams) (database.ExternalAuthLink, error) {
+	ctx, span := t.startSpan(ctx, "UpdateExternalAuthLink", arg)
+	r0, r1 := t.s.UpdateExternalAuthLink(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGitSSHKeyParams) (database.GitSSHKey, error) {
+	ctx, span := t.startSpan(ctx, "UpdateGitSSHKey", arg)
+	r0, r1 := t.s.UpdateGitSSHKey(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateGroupByID(ctx context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
+	ctx, span := t.startSpan(ctx, "UpdateGroupByID", arg)
+	r0, r1 := t.s.UpdateGroupByID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateInactiveUsersToDormant(ctx context.Context, lastSeenAfter database.UpdateInactiveUsersToDormantParams) ([]database.UpdateInactiveUsersToDormantRow, error) {
+	ctx, span := t.startSpan(ctx, "UpdateInactiveUsersToDormant", lastSeenAfter)
+	r0, r1 := t.s.UpdateInactiveUsersToDormant(ctx, lastSeenAfter)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateInactiveUsersToSuspended(ctx context.Context, arg database.UpdateInactiveUsersToSuspendedParams) ([]database.UpdateInactiveUsersToSuspendedRow, error) {
+	ctx, span := t.startSpan(ctx, "UpdateInactiveUsersToSuspended", arg)
+	r0, r1 := t.s.UpdateInactiveUsersToSuspended(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateInboxNotificationsReadByUserID(ctx context.Context, arg database.UpdateInboxNotificationsReadByUserIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateInboxNotificationsReadByUserID", arg)
+	r0 := t.s.UpdateInboxNotificationsReadByUserID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
+	ctx, span := t.startSpan(ctx, "UpdateMemberRoles", arg)
+	r0, r1 := t.s.UpdateMemberRoles(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateNotificationMessageByID(ctx context.Context, arg database.UpdateNotificationMessageByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateNotificationMessageByID", arg)
+	r0 := t.s.UpdateNotificationMessageByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateOAuth2ProviderAppByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppByIDParams) (database.OAuth2ProviderApp, error) {
+	ctx, span := t.startSpan(ctx, "UpdateOAuth2ProviderAppByID", arg)
+	r0, r1 := t.s.UpdateOAuth2ProviderAppByID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg database.UpdateOAuth2ProviderAppSecretByIDParams) (database.OAuth2ProviderAppSecret, error) {
+	ctx, span := t.startSpan(ctx, "UpdateOAuth2ProviderAppSecretByID", arg)
+	r0, r1 := t.s.UpdateOAuth2ProviderAppSecretByID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdatePasskeyUsage(ctx context.Context, arg database.UpdatePasskeyUsageParams) error {
+	ctx, span := t.startSpan(ctx, "UpdatePasskeyUsage", arg)
+	r0 := t.s.UpdatePasskeyUsage(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg database.UpdateProvisionerDaemonLastSeenAtParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerDaemonLastSeenAt", arg)
+	r0 := t.s.UpdateProvisionerDaemonLastSeenAt(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerJobByID", arg)
+	r0 := t.s.UpdateProvisionerJobByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerJobPriorityByID(ctx context.Context, arg database.UpdateProvisionerJobPriorityByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerJobPriorityByID", arg)
+	r0 := t.s.UpdateProvisionerJobPriorityByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg database.UpdateProvisionerJobWithCancelByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerJobWithCancelByID", arg)
+	r0 := t.s.UpdateProvisionerJobWithCancelByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg database.UpdateProvisionerJobWithCompleteByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerJobWithCompleteByID", arg)
+	r0 := t.s.UpdateProvisionerJobWithCompleteByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateProvisionerKeySecretByID(ctx context.Context, arg database.UpdateProvisionerKeySecretByIDParams) (database.ProvisionerKey, error) {
+	ctx, span := t.startSpan(ctx, "UpdateProvisionerKeySecretByID", arg)
+	r0, r1 := t.s.UpdateProvisionerKeySecretByID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
+	ctx, span := t.startSpan(ctx, "UpdateReplica", arg)
+	r0, r1 := t.s.UpdateReplica(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg database.UpdateTailnetPeerStatusByCoordinatorParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTailnetPeerStatusByCoordinator", arg)
+	r0 := t.s.UpdateTailnetPeerStatusByCoordinator(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateACLByID", arg)
+	r0 := t.s.UpdateTemplateACLByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateAccessControlByID(ctx context.Context, arg database.UpdateTemplateAccessControlByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateAccessControlByID", arg)
+	r0 := t.s.UpdateTemplateAccessControlByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateActiveVersionByID", arg)
+	r0 := t.s.UpdateTemplateActiveVersionByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateBuildRetryPolicyByID(ctx context.Context, arg database.UpdateTemplateBuildRetryPolicyByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateBuildRetryPolicyByID", arg)
+	r0 := t.s.UpdateTemplateBuildRetryPolicyByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateDeletedByID(ctx context.Context, arg database.UpdateTemplateDeletedByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateDeletedByID", arg)
+	r0 := t.s.UpdateTemplateDeletedByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateMaintenanceWindowByID(ctx context.Context, arg database.UpdateTemplateMaintenanceWindowByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateMaintenanceWindowByID", arg)
+	r0 := t.s.UpdateTemplateMaintenanceWindowByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateMaxBuildDurationByID(ctx context.Context, arg database.UpdateTemplateMaxBuildDurationByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateMaxBuildDurationByID", arg)
+	r0 := t.s.UpdateTemplateMaxBuildDurationByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateMaxRunningWorkspacesByID(ctx context.Context, arg database.UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateMaxRunningWorkspacesByID", arg)
+	r0 := t.s.UpdateTemplateMaxRunningWorkspacesByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateMetaByID(ctx context.Context, arg database.UpdateTemplateMetaByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateMetaByID", arg)
+	r0 := t.s.UpdateTemplateMetaByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplatePortForwardingPolicyByID(ctx context.Context, arg database.UpdateTemplatePortForwardingPolicyByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplatePortForwardingPolicyByID", arg)
+	r0 := t.s.UpdateTemplatePortForwardingPolicyByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateScheduleByID(ctx context.Context, arg database.UpdateTemplateScheduleByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateScheduleByID", arg)
+	r0 := t.s.UpdateTemplateScheduleByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateSessionRecordingByID(ctx context.Context, arg database.UpdateTemplateSessionRecordingByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateSessionRecordingByID", arg)
+	r0 := t.s.UpdateTemplateSessionRecordingByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateVersionByID", arg)
+	r0 := t.s.UpdateTemplateVersionByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg database.UpdateTemplateVersionDescriptionByJobIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateVersionDescriptionByJobID", arg)
+	r0 := t.s.UpdateTemplateVersionDescriptionByJobID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg database.UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateVersionExternalAuthProvidersByJobID", arg)
+	r0 := t.s.UpdateTemplateVersionExternalAuthProvidersByJobID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateVisibilityByID(ctx context.Context, arg database.UpdateTemplateVisibilityByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateVisibilityByID", arg)
+	r0 := t.s.UpdateTemplateVisibilityByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateWorkspaceNamePolicyByID(ctx context.Context, arg database.UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateWorkspaceNamePolicyByID", arg)
+	r0 := t.s.UpdateTemplateWorkspaceNamePolicyByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateTemplateWorkspacesLastUsedAt", arg)
+	r0 := t.s.UpdateTemplateWorkspacesLastUsedAt(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateUserAppearanceSettings(ctx context.Context, arg database.UpdateUserAppearanceSettingsParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserAppearanceSettings", arg)
+	r0, r1 := t.s.UpdateUserAppearanceSettings(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserDeletedByID(ctx context.Context, arg database.UpdateUserDeletedByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateUserDeletedByID", arg)
+	r0 := t.s.UpdateUserDeletedByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateUserHashedPassword(ctx context.Context, arg database.UpdateUserHashedPasswordParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateUserHashedPassword", arg)
+	r0 := t.s.UpdateUserHashedPassword(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateUserIPAllowlist(ctx context.Context, arg database.UpdateUserIPAllowlistParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserIPAllowlist", arg)
+	r0, r1 := t.s.UpdateUserIPAllowlist(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserLastSeenAt(ctx context.Context, arg database.UpdateUserLastSeenAtParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserLastSeenAt", arg)
+	r0, r1 := t.s.UpdateUserLastSeenAt(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserLink(ctx context.Context, arg database.UpdateUserLinkParams) (database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserLink", arg)
+	r0, r1 := t.s.UpdateUserLink(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserLinkedID(ctx context.Context, arg database.UpdateUserLinkedIDParams) (database.UserLink, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserLinkedID", arg)
+	r0, r1 := t.s.UpdateUserLinkedID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserLoginType(ctx context.Context, arg database.UpdateUserLoginTypeParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserLoginType", arg)
+	r0, r1 := t.s.UpdateUserLoginType(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserProfile(ctx context.Context, arg database.UpdateUserProfileParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserProfile", arg)
+	r0, r1 := t.s.UpdateUserProfile(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserQuietHoursSchedule(ctx context.Context, arg database.UpdateUserQuietHoursScheduleParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserQuietHoursSchedule", arg)
+	r0, r1 := t.s.UpdateUserQuietHoursSchedule(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserRoles(ctx context.Context, arg database.UpdateUserRolesParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserRoles", arg)
+	r0, r1 := t.s.UpdateUserRoles(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateUserStatus(ctx context.Context, arg database.UpdateUserStatusParams) (database.User, error) {
+	ctx, span := t.startSpan(ctx, "UpdateUserStatus", arg)
+	r0, r1 := t.s.UpdateUserStatus(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateWebhookDeliveryByID(ctx context.Context, arg database.UpdateWebhookDeliveryByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWebhookDeliveryByID", arg)
+	r0 := t.s.UpdateWebhookDeliveryByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWebhookSecretByID(ctx context.Context, arg database.UpdateWebhookSecretByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWebhookSecretByID", arg)
+	r0 := t.s.UpdateWebhookSecretByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWorkspaceParams) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspace", arg)
+	r0, r1 := t.s.UpdateWorkspace(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateWorkspaceACLByID(ctx context.Context, arg database.UpdateWorkspaceACLByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceACLByID", arg)
+	r0 := t.s.UpdateWorkspaceACLByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentAuthTokenByID", arg)
+	r0 := t.s.UpdateWorkspaceAgentAuthTokenByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentConnectionByID", arg)
+	r0 := t.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg database.UpdateWorkspaceAgentLifecycleStateByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentLifecycleStateByID", arg)
+	r0 := t.s.UpdateWorkspaceAgentLifecycleStateByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg database.UpdateWorkspaceAgentLogOverflowByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentLogOverflowByID", arg)
+	r0 := t.s.UpdateWorkspaceAgentLogOverflowByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentMetadata(ctx context.Context, arg database.UpdateWorkspaceAgentMetadataParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentMetadata", arg)
+	r0 := t.s.UpdateWorkspaceAgentMetadata(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg database.UpdateWorkspaceAgentStartupByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAgentStartupByID", arg)
+	r0 := t.s.UpdateWorkspaceAgentStartupByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database.UpdateWorkspaceAppHealthByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAppHealthByID", arg)
+	r0 := t.s.UpdateWorkspaceAppHealthByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAutomaticUpdates", arg)
+	r0 := t.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceAutostart", arg)
+	r0 := t.s.UpdateWorkspaceAutostart(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceBuildCostByID(ctx context.Context, arg database.UpdateWorkspaceBuildCostByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceBuildCostByID", arg)
+	r0 := t.s.UpdateWorkspaceBuildCostByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg database.UpdateWorkspaceBuildDeadlineByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceBuildDeadlineByID", arg)
+	r0 := t.s.UpdateWorkspaceBuildDeadlineByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg database.UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceBuildProvisionerStateByID", arg)
+	r0 := t.s.UpdateWorkspaceBuildProvisionerStateByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceBuildRetryAtByID(ctx context.Context, arg database.UpdateWorkspaceBuildRetryAtByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceBuildRetryAtByID", arg)
+	r0 := t.s.UpdateWorkspaceBuildRetryAtByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceDeletedByID(ctx context.Context, arg database.UpdateWorkspaceDeletedByIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceDeletedByID", arg)
+	r0 := t.s.UpdateWorkspaceDeletedByID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceDormancyExempt(ctx context.Context, arg database.UpdateWorkspaceDormancyExemptParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceDormancyExempt", arg)
+	r0 := t.s.UpdateWorkspaceDormancyExempt(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg database.UpdateWorkspaceDormantDeletingAtParams) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceDormantDeletingAt", arg)
+	r0, r1 := t.s.UpdateWorkspaceDormantDeletingAt(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateWorkspaceDriftResult(ctx context.Context, arg database.UpdateWorkspaceDriftResultParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceDriftResult", arg)
+	r0 := t.s.UpdateWorkspaceDriftResult(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceLastUsedAt(ctx context.Context, arg database.UpdateWorkspaceLastUsedAtParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceLastUsedAt", arg)
+	r0 := t.s.UpdateWorkspaceLastUsedAt(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceMaintenanceOptOut(ctx context.Context, arg database.UpdateWorkspaceMaintenanceOptOutParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceMaintenanceOptOut", arg)
+	r0 := t.s.UpdateWorkspaceMaintenanceOptOut(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceOwnerByID(ctx context.Context, arg database.UpdateWorkspaceOwnerByIDParams) (database.Workspace, error) {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceOwnerByID", arg)
+	r0, r1 := t.s.UpdateWorkspaceOwnerByID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceProxy", arg)
+	r0, r1 := t.s.UpdateWorkspaceProxy(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceProxyDeleted", arg)
+	r0 := t.s.UpdateWorkspaceProxyDeleted(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWorkspaceTTLParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspaceTTL", arg)
+	r0 := t.s.UpdateWorkspaceTTL(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg database.UpdateWorkspacesDormantDeletingAtByTemplateIDParams) error {
+	ctx, span := t.startSpan(ctx, "UpdateWorkspacesDormantDeletingAtByTemplateID", arg)
+	r0 := t.s.UpdateWorkspacesDormantDeletingAtByTemplateID(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertAppSecurityKey(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertAppSecurityKey", value)
+	r0 := t.s.UpsertAppSecurityKey(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertApplicationName(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertApplicationName", value)
+	r0 := t.s.UpsertApplicationName(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertCustomRole(ctx context.Context, arg database.UpsertCustomRoleParams) (database.CustomRole, error) {
+	ctx, span := t.startSpan(ctx, "UpsertCustomRole", arg)
+	r0, r1 := t.s.UpsertCustomRole(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertDefaultProxy(ctx context.Context, arg database.UpsertDefaultProxyParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertDefaultProxy", arg)
+	r0 := t.s.UpsertDefaultProxy(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertHealthSettings(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertHealthSettings", value)
+	r0 := t.s.UpsertHealthSettings(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertLastUpdateCheck(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertLastUpdateCheck", value)
+	r0 := t.s.UpsertLastUpdateCheck(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertLicenseGracePeriodStartedAt(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertLicenseGracePeriodStartedAt", value)
+	r0 := t.s.UpsertLicenseGracePeriodStartedAt(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertLogoURL(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertLogoURL", value)
+	r0 := t.s.UpsertLogoURL(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertNotificationPreference(ctx context.Context, arg database.UpsertNotificationPreferenceParams) (database.NotificationPreference, error) {
+	ctx, span := t.startSpan(ctx, "UpsertNotificationPreference", arg)
+	r0, r1 := t.s.UpsertNotificationPreference(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertOAuthSigningKey(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertOAuthSigningKey", value)
+	r0 := t.s.UpsertOAuthSigningKey(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertOrganizationQuota(ctx context.Context, arg database.UpsertOrganizationQuotaParams) (database.OrganizationQuota, error) {
+	ctx, span := t.startSpan(ctx, "UpsertOrganizationQuota", arg)
+	r0, r1 := t.s.UpsertOrganizationQuota(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertOrganizationTemplateVariableValue(ctx context.Context, arg database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	ctx, span := t.startSpan(ctx, "UpsertOrganizationTemplateVariableValue", arg)
+	r0, r1 := t.s.UpsertOrganizationTemplateVariableValue(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertProvisionerDaemon(ctx context.Context, arg database.UpsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
+	ctx, span := t.startSpan(ctx, "UpsertProvisionerDaemon", arg)
+	r0, r1 := t.s.UpsertProvisionerDaemon(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertServiceBanner(ctx context.Context, value string) error {
+	ctx, span := t.startSpan(ctx, "UpsertServiceBanner", value)
+	r0 := t.s.UpsertServiceBanner(ctx, value)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertTailnetAgent(ctx context.Context, arg database.UpsertTailnetAgentParams) (database.TailnetAgent, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetAgent", arg)
+	r0, r1 := t.s.UpsertTailnetAgent(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTailnetClient(ctx context.Context, arg database.UpsertTailnetClientParams) (database.TailnetClient, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetClient", arg)
+	r0, r1 := t.s.UpsertTailnetClient(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTailnetClientSubscription(ctx context.Context, arg database.UpsertTailnetClientSubscriptionParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetClientSubscription", arg)
+	r0 := t.s.UpsertTailnetClientSubscription(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (database.TailnetCoordinator, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetCoordinator", id)
+	r0, r1 := t.s.UpsertTailnetCoordinator(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTailnetPeer(ctx context.Context, arg database.UpsertTailnetPeerParams) (database.TailnetPeer, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetPeer", arg)
+	r0, r1 := t.s.UpsertTailnetPeer(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTailnetTunnel(ctx context.Context, arg database.UpsertTailnetTunnelParams) (database.TailnetTunnel, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTailnetTunnel", arg)
+	r0, r1 := t.s.UpsertTailnetTunnel(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTemplateVariableValue(ctx context.Context, arg database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTemplateVariableValue", arg)
+	r0, r1 := t.s.UpsertTemplateVariableValue(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertTwoFactorPolicy(ctx context.Context, arg database.UpsertTwoFactorPolicyParams) (database.TwoFactorPolicy, error) {
+	ctx, span := t.startSpan(ctx, "UpsertTwoFactorPolicy", arg)
+	r0, r1 := t.s.UpsertTwoFactorPolicy(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertUserActivity(ctx context.Context, arg database.UpsertUserActivityParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertUserActivity", arg)
+	r0 := t.s.UpsertUserActivity(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertUserLockout(ctx context.Context, arg database.UpsertUserLockoutParams) (database.UserLockout, error) {
+	ctx, span := t.startSpan(ctx, "UpsertUserLockout", arg)
+	r0, r1 := t.s.UpsertUserLockout(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertUserSCIMExternalID(ctx context.Context, arg database.UpsertUserSCIMExternalIDParams) (database.UserSCIMExternalID, error) {
+	ctx, span := t.startSpan(ctx, "UpsertUserSCIMExternalID", arg)
+	r0, r1 := t.s.UpsertUserSCIMExternalID(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertWorkspaceAgentGPUs(ctx context.Context, arg database.UpsertWorkspaceAgentGPUsParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceAgentGPUs", arg)
+	r0 := t.s.UpsertWorkspaceAgentGPUs(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertWorkspaceAgentPortShare(ctx context.Context, arg database.UpsertWorkspaceAgentPortShareParams) (database.WorkspaceAgentPortShare, error) {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceAgentPortShare", arg)
+	r0, r1 := t.s.UpsertWorkspaceAgentPortShare(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) UpsertWorkspaceAgentPorts(ctx context.Context, arg database.UpsertWorkspaceAgentPortsParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceAgentPorts", arg)
+	r0 := t.s.UpsertWorkspaceAgentPorts(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertWorkspaceAgentPreviousAuthToken(ctx context.Context, arg database.UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceAgentPreviousAuthToken", arg)
+	r0 := t.s.UpsertWorkspaceAgentPreviousAuthToken(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertWorkspaceDriftJob(ctx context.Context, arg database.UpsertWorkspaceDriftJobParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceDriftJob", arg)
+	r0 := t.s.UpsertWorkspaceDriftJob(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertWorkspaceProxyHealth(ctx context.Context, arg database.UpsertWorkspaceProxyHealthParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceProxyHealth", arg)
+	r0 := t.s.UpsertWorkspaceProxyHealth(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UpsertWorkspaceResourceCosts(ctx context.Context, arg database.UpsertWorkspaceResourceCostsParams) error {
+	ctx, span := t.startSpan(ctx, "UpsertWorkspaceResourceCosts", arg)
+	r0 := t.s.UpsertWorkspaceResourceCosts(ctx, arg)
+	endSpan(span, r0)
+	return r0
+}
+
+func (t traceStore) UseUserRecoveryCode(ctx context.Context, arg database.UseUserRecoveryCodeParams) (database.UserRecoveryCode, error) {
+	ctx, span := t.startSpan(ctx, "UseUserRecoveryCode", arg)
+	r0, r1 := t.s.UseUserRecoveryCode(ctx, arg)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
+	ctx, span := t.startSpan(ctx, "GetAuthorizedTemplates", arg, prepared)
+	r0, r1 := t.s.GetAuthorizedTemplates(ctx, arg, prepared)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateGroupRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateGroup, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateGroupRoles", id)
+	r0, r1 := t.s.GetTemplateGroupRoles(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetTemplateUserRoles(ctx context.Context, id uuid.UUID) ([]database.TemplateUser, error) {
+	ctx, span := t.startSpan(ctx, "GetTemplateUserRoles", id)
+	r0, r1 := t.s.GetTemplateUserRoles(ctx, id)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuthorizedWorkspaces(ctx context.Context, arg database.GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]database.GetWorkspacesRow, error) {
+	ctx, span := t.startSpan(ctx, "GetAuthorizedWorkspaces", arg, prepared)
+	r0, r1 := t.s.GetAuthorizedWorkspaces(ctx, arg, prepared)
+	endSpan(span, r1)
+	return r0, r1
+}
+
+func (t traceStore) GetAuthorizedUsers(ctx context.Context, arg database.GetUsersParams, prepared rbac.PreparedAuthorized) ([]database.GetUsersRow, error) {
+	ctx, span := t.startSpan(ctx, "GetAuthorizedUsers", arg, prepared)
+	r0, r1 := t.s.GetAuthorizedUsers(ctx, arg, prepared)
+	endSpan(span, r1)
+	return r0, r1
+}
@@ -0,0 +1,29 @@
+package dbtrace
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+func TestArgCardinality(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 0, argCardinality())
+	require.Equal(t, 1, argCardinality(uuid.New()))
+	require.Equal(t, 3, argCardinality([]uuid.UUID{uuid.New(), uuid.New(), uuid.New()}))
+	require.Equal(t, 1, argCardinality([]byte("opaque")))
+	// Struct params count each field, expanding slices.
+	require.Equal(t, 9, argCardinality(database.GetUsersParams{
+		Status:   []database.UserStatus{database.UserStatusActive, database.UserStatusDormant},
+		RbacRole: []string{"owner"},
+	}))
+	// Other structs are a single value.
+	require.Equal(t, 2, argCardinality(uuid.New(), time.Now()))
+	require.Equal(t, 2, argCardinality(uuid.NullUUID{UUID: uuid.New(), Valid: true}, sql.NullString{}))
+}
@@ -19,7 +19,9 @@ CREATE TYPE audit_action AS ENUM (
     'stop',
     'login',
     'logout',
-    'register'
+    'register',
+    'port_forward',
+    'ip_rejected'
 );
 
 CREATE TYPE automatic_updates AS ENUM (
@@ -33,7 +35,17 @@ CREATE TYPE build_reason AS ENUM (
     'autostop',
     'dormancy',
     'failedstop',
-    'autodelete'
+    'autodelete',
+    'maintenance',
+    'template_update',
+    'batch',
+    'retry'
+);
+
+CREATE TYPE connection_type AS ENUM (
+    'ssh',
+    'reconnecting_pty',
+    'port_forwarding'
 );
 
 CREATE TYPE display_app AS ENUM (
@@ -46,7 +58,13 @@ CREATE TYPE display_app AS ENUM (
 
 CREATE TYPE group_source AS ENUM (
     'user',
-    'oidc'
+    'oidc',
+    'scim'
+);
+
+CREATE TYPE group_sync_trigger AS ENUM (
+    'login',
+    'background'
 );
 
 CREATE TYPE log_level AS ENUM (
@@ -67,7 +85,9 @@ CREATE TYPE login_type AS ENUM (
     'github',
     'oidc',
     'token',
-    'none'
+    'none',
+    'oauth2_provider_app',
+    'ldap'
 );
 
 COMMENT ON TYPE login_type IS 'Specifies the method of authentication. "none" is a special case in which no authentication method is allowed.';
@@ -109,7 +129,8 @@ COMMENT ON TYPE provisioner_job_status IS 'Computed status of a provisioner job.
 CREATE TYPE provisioner_job_type AS ENUM (
     'template_version_import',
     'workspace_build',
-    'template_version_dry_run'
+    'template_version_dry_run',
+    'workspace_build_dry_run'
 );
 
 CREATE TYPE provisioner_storage_method AS ENUM (
@@ -118,7 +139,9 @@ CREATE TYPE provisioner_storage_method AS ENUM (
 
 CREATE TYPE provisioner_type AS ENUM (
     'echo',
-    'terraform'
+    'terraform',
+    'pulumi',
+    'kubernetes'
 );
 
 CREATE TYPE resource_type AS ENUM (
@@ -134,7 +157,11 @@ CREATE TYPE resource_type AS ENUM (
     'license',
     'workspace_proxy',
     'convert_login',
-    'health_settings'
+    'health_settings',
+    'passkey',
+    'two_factor_policy',
+    'webhook',
+    'organization_member'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -147,6 +174,20 @@ CREATE TYPE tailnet_status AS ENUM (
     'lost'
 );
 
+CREATE TYPE template_visibility AS ENUM (
+    'private',
+    'unlisted',
+    'public'
+);
+
+COMMENT ON TYPE template_visibility IS 'Defines who can see a template: private, unlisted, or public.';
+
+CREATE TYPE two_factor_challenge_purpose AS ENUM (
+    'register_passkey',
+    'login',
+    'enroll_passkey'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -167,6 +208,14 @@ CREATE TYPE workspace_agent_lifecycle_state AS ENUM (
     'off'
 );
 
+CREATE TYPE workspace_agent_script_phase AS ENUM (
+    'pre_start',
+    'start',
+    'post_start',
+    'pre_stop',
+    'stop'
+);
+
 CREATE TYPE workspace_agent_subsystem AS ENUM (
     'envbuilder',
     'envbox',
@@ -181,12 +230,30 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_event_type AS ENUM (
+    'agent_connected',
+    'agent_disconnected',
+    'ttl_changed',
+    'app_health_changed'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
     'delete'
 );
 
+CREATE FUNCTION delete_deleted_oauth2_provider_app_token_api_key() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+BEGIN
+	DELETE FROM api_keys
+	WHERE id = OLD.api_key_id;
+	RETURN OLD;
+END;
+$$;
+
 CREATE FUNCTION delete_deleted_user_api_keys() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -215,6 +282,45 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION insert_workspace_parameter_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+	build workspace_builds%ROWTYPE;
+	previous_value text;
+BEGIN
+	SELECT * INTO build FROM workspace_builds WHERE id = NEW.workspace_build_id;
+
+	-- Compare with the value in the previous build of the workspace, if any.
+	SELECT
+		workspace_build_parameters.value INTO previous_value
+	FROM
+		workspace_build_parameters
+	WHERE
+		workspace_build_parameters.name = NEW.name
+		AND workspace_build_parameters.workspace_build_id = (
+			SELECT
+				workspace_builds.id
+			FROM
+				workspace_builds
+			WHERE
+				workspace_builds.workspace_id = build.workspace_id
+				AND workspace_builds.build_number < build.build_number
+			ORDER BY
+				workspace_builds.build_number DESC
+			LIMIT 1
+		);
+
+	IF previous_value IS DISTINCT FROM NEW.value THEN
+		INSERT INTO workspace_parameter_changes
+			(workspace_id, workspace_build_id, build_number, name, old_value, new_value, initiator_id, created_at)
+		VALUES
+			(build.workspace_id, build.id, build.build_number, NEW.name, previous_value, NEW.value, build.initiator_id, build.created_at);
+	END IF;
+	RETURN NULL;
+END;
+$$;
+
 CREATE FUNCTION tailnet_notify_agent_change() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -339,11 +445,20 @@ CREATE TABLE api_keys (
     lifetime_seconds bigint DEFAULT 86400 NOT NULL,
     ip_address inet DEFAULT '0.0.0.0'::inet NOT NULL,
     scope api_key_scope DEFAULT 'all'::api_key_scope NOT NULL,
-    token_name text DEFAULT ''::text NOT NULL
+    token_name text DEFAULT ''::text NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    allowed_workspace_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    ip_allowlist text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+COMMENT ON COLUMN api_keys.scopes IS 'Fine-grained scopes the key is limited to, e.g. workspace:read. If empty, the key is only limited by its scope.';
+
+COMMENT ON COLUMN api_keys.allowed_workspace_ids IS 'Workspaces a key with fine-grained scopes is limited to. If empty, the key is not limited to specific workspaces.';
+
+COMMENT ON COLUMN api_keys.ip_allowlist IS 'CIDR ranges the key may be used from, in addition to the allowlist of its user. If empty, the key is only limited by the allowlist of its user.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -362,6 +477,40 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE connection_logs (
+    id uuid NOT NULL,
+    "time" timestamp with time zone NOT NULL,
+    organization_id uuid NOT NULL,
+    workspace_owner_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_name text NOT NULL,
+    agent_name text NOT NULL,
+    type connection_type NOT NULL,
+    port integer,
+    ip inet,
+    user_agent text NOT NULL,
+    user_id uuid NOT NULL,
+    api_key_id text NOT NULL
+);
+
+COMMENT ON TABLE connection_logs IS 'Connections opened by clients to workspace agents. Like audit logs, rows are never updated.';
+
+COMMENT ON COLUMN connection_logs.port IS 'Forwarded port of port_forwarding connections.';
+
+COMMENT ON COLUMN connection_logs.api_key_id IS 'ID of the API key that authenticated the connection.';
+
+CREATE TABLE custom_roles (
+    name text NOT NULL,
+    display_name text NOT NULL,
+    site_permissions jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE custom_roles IS 'Custom site wide roles defined by administrators.';
+
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The permissions granted by the role, as a list of resource and action pairs.';
+
 CREATE TABLE dbcrypt_keys (
     number integer NOT NULL,
     active_key_digest text,
@@ -424,6 +573,29 @@ CREATE TABLE group_members (
     group_id uuid NOT NULL
 );
 
+CREATE TABLE group_sync_runs (
+    id uuid NOT NULL,
+    trigger group_sync_trigger NOT NULL,
+    user_id uuid,
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone NOT NULL,
+    users_synced integer DEFAULT 0 NOT NULL,
+    users_failed integer DEFAULT 0 NOT NULL,
+    idp_groups text[] DEFAULT '{}'::text[] NOT NULL,
+    groups text[] DEFAULT '{}'::text[] NOT NULL,
+    error text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE group_sync_runs IS 'Records of group sync runs, kept for debugging group sync.';
+
+COMMENT ON COLUMN group_sync_runs.user_id IS 'The user whose groups were synced at login. Null for background runs, which sync every OIDC user, and for logins that failed before the user was known.';
+
+COMMENT ON COLUMN group_sync_runs.idp_groups IS 'The groups returned by the identity provider at login, before nested groups are resolved.';
+
+COMMENT ON COLUMN group_sync_runs.groups IS 'The Coder groups the user was synced to at login, after nested groups are resolved and the mapping, filter and prefix stripping are applied.';
+
+COMMENT ON COLUMN group_sync_runs.error IS 'The errors that occurred during the run, if any.';
+
 CREATE TABLE groups (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -438,6 +610,20 @@ COMMENT ON COLUMN groups.display_name IS 'Display name is a custom, human-friend
 
 COMMENT ON COLUMN groups.source IS 'Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.';
 
+CREATE TABLE inbox_notifications (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    title text NOT NULL,
+    content text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    read_at timestamp with time zone
+);
+
+COMMENT ON TABLE inbox_notifications IS 'Notifications shown to users in the dashboard, regardless of how else they are delivered.';
+
+COMMENT ON COLUMN inbox_notifications.read_at IS 'When the user marked the notification as read. NULL while it is unread.';
+
 CREATE TABLE licenses (
     id integer NOT NULL,
     uploaded_at timestamp with time zone NOT NULL,
@@ -458,6 +644,67 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE login_failures (
+    user_id uuid NOT NULL,
+    ip_address inet NOT NULL,
+    failed_attempts integer DEFAULT 0 NOT NULL,
+    last_failed_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE login_failures IS 'Failed password logins per user and IP address, used to slow down brute-force attacks.';
+
+COMMENT ON COLUMN login_failures.failed_attempts IS 'The number of failed attempts since the count was last reset by a successful login or by the failures expiring.';
+
+CREATE TABLE notification_messages (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    sent_at timestamp with time zone,
+    next_attempt_at timestamp with time zone,
+    method text DEFAULT 'smtp'::text NOT NULL
+);
+
+COMMENT ON TABLE notification_messages IS 'Notifications sent to users, kept to report their delivery status.';
+
+COMMENT ON COLUMN notification_messages.template IS 'Template the notification is rendered with.';
+
+COMMENT ON COLUMN notification_messages.labels IS 'Values the template is rendered with.';
+
+COMMENT ON COLUMN notification_messages.next_attempt_at IS 'When the notification should next be sent. NULL once it has been sent or has run out of attempts.';
+
+COMMENT ON COLUMN notification_messages.method IS 'Method the notification is delivered with, such as smtp, slack or teams.';
+
+CREATE TABLE notification_preferences (
+    user_id uuid NOT NULL,
+    template text NOT NULL,
+    disabled boolean DEFAULT false NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE notification_preferences IS 'Notification templates users have opted out of or back into. Templates without a preference are enabled.';
+
+CREATE TABLE oauth2_provider_app_codes (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    secret_prefix bytea NOT NULL,
+    hashed_secret bytea NOT NULL,
+    user_id uuid NOT NULL,
+    app_id uuid NOT NULL,
+    nonce text DEFAULT ''::text NOT NULL,
+    code_challenge text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE oauth2_provider_app_codes IS 'Codes are meant to be exchanged for access tokens.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.nonce IS 'The nonce the app sent with the authorization request. It is returned in the ID token.';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge the app sent with the authorization request. Empty if the app did not use PKCE.';
+
 CREATE TABLE oauth2_provider_app_secrets (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -469,6 +716,18 @@ CREATE TABLE oauth2_provider_app_secrets (
 
 COMMENT ON COLUMN oauth2_provider_app_secrets.display_secret IS 'The tail end of the original secret so secrets can be differentiated.';
 
+CREATE TABLE oauth2_provider_app_tokens (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL,
+    hash_prefix bytea NOT NULL,
+    refresh_hash bytea NOT NULL,
+    app_secret_id uuid NOT NULL,
+    api_key_id text NOT NULL
+);
+
+COMMENT ON COLUMN oauth2_provider_app_tokens.refresh_hash IS 'Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.';
+
 CREATE TABLE oauth2_provider_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -488,6 +747,31 @@ CREATE TABLE organization_members (
     roles text[] DEFAULT '{organization-member}'::text[] NOT NULL
 );
 
+CREATE TABLE organization_quotas (
+    organization_id uuid NOT NULL,
+    max_running_workspaces_per_user integer DEFAULT 0 NOT NULL,
+    max_daily_cost integer DEFAULT 0 NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN organization_quotas.max_running_workspaces_per_user IS 'The maximum number of running workspaces of a user in the organization. Zero disables the limit.';
+
+COMMENT ON COLUMN organization_quotas.max_daily_cost IS 'The maximum total daily cost of the workspaces in the organization. Zero disables the limit.';
+
+CREATE TABLE organization_template_variable_values (
+    organization_id uuid NOT NULL,
+    name text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    sensitive boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE organization_template_variable_values IS 'Default values of template variables for all templates of an organization. They are used when a template version is pushed without a value for the variable.';
+
+COMMENT ON COLUMN organization_template_variable_values.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
 CREATE TABLE organizations (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -528,6 +812,25 @@ CREATE TABLE parameter_values (
     destination_scheme parameter_destination_scheme NOT NULL
 );
 
+CREATE TABLE passkeys (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    name text NOT NULL,
+    credential_id bytea NOT NULL,
+    public_key bytea NOT NULL,
+    sign_count bigint DEFAULT 0 NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    last_used_at timestamp with time zone
+);
+
+COMMENT ON TABLE passkeys IS 'WebAuthn credentials registered by users as a second factor for password logins.';
+
+COMMENT ON COLUMN passkeys.credential_id IS 'The credential ID chosen by the authenticator.';
+
+COMMENT ON COLUMN passkeys.public_key IS 'The COSE encoded public key of the credential.';
+
+COMMENT ON COLUMN passkeys.sign_count IS 'The signature counter last reported by the authenticator, used to detect cloned authenticators.';
+
 CREATE TABLE provisioner_daemons (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -537,11 +840,28 @@ CREATE TABLE provisioner_daemons (
     tags jsonb DEFAULT '{}'::jsonb NOT NULL,
     last_seen_at timestamp with time zone,
     version text DEFAULT ''::text NOT NULL,
-    api_version text DEFAULT '1.0'::text NOT NULL
+    api_version text DEFAULT '1.0'::text NOT NULL,
+    organization_id uuid
 );
 
 COMMENT ON COLUMN provisioner_daemons.api_version IS 'The API version of the provisioner daemon';
 
+COMMENT ON COLUMN provisioner_daemons.organization_id IS 'The organization the daemon acquires jobs from. NULL means jobs from every organization.';
+
+CREATE TABLE provisioner_job_log_archives (
+    job_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    log_count integer NOT NULL,
+    uncompressed_size bigint NOT NULL,
+    compressed_logs bytea NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS 'Logs of completed provisioner jobs, compressed to save space. Archived logs are removed from provisioner_job_logs.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.uncompressed_size IS 'The size of the logs in bytes before they were compressed.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.compressed_logs IS 'A gzip compressed JSON array of the archived provisioner_job_logs rows.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -595,11 +915,41 @@ CASE
         WHEN (started_at IS NULL) THEN 'pending'::provisioner_job_status
         ELSE 'running'::provisioner_job_status
     END
-END) STORED NOT NULL
+END) STORED NOT NULL,
+    priority integer DEFAULT 0 NOT NULL,
+    tag_requirements jsonb DEFAULT '[]'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN provisioner_jobs.job_status IS 'Computed column to track the status of the job.';
 
+COMMENT ON COLUMN provisioner_jobs.priority IS 'Pending jobs with a higher priority are acquired first. Within a priority, workspace builds are preferred over other job types.';
+
+COMMENT ON COLUMN provisioner_jobs.tag_requirements IS 'Requirements on the tags of the provisioner daemons that may acquire the job, in addition to exactly matching tags. Each requirement has a key, an operator (in or not_in) and values, which may contain * wildcards.';
+
+CREATE TABLE provisioner_keys (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    organization_id uuid NOT NULL,
+    name character varying(64) NOT NULL,
+    hashed_secret bytea NOT NULL,
+    tags jsonb DEFAULT '{}'::jsonb NOT NULL,
+    rotated_at timestamp with time zone
+);
+
+COMMENT ON TABLE provisioner_keys IS 'Keys that authenticate external provisioner daemons to an organization.';
+
+COMMENT ON COLUMN provisioner_keys.tags IS 'The tags of every daemon authenticated with the key. Daemons may not set other values for these tags.';
+
+COMMENT ON COLUMN provisioner_keys.rotated_at IS 'When the secret of the key was last replaced.';
+
+CREATE UNLOGGED TABLE rate_limit_counters (
+    key text NOT NULL,
+    window_start timestamp with time zone NOT NULL,
+    count integer DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE rate_limit_counters IS 'Request counts per rate limit key and window, shared by all replicas.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -615,6 +965,26 @@ CREATE TABLE replicas (
     "primary" boolean DEFAULT true NOT NULL
 );
 
+CREATE TABLE session_recordings (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone NOT NULL,
+    object_key text NOT NULL,
+    size_bytes bigint NOT NULL,
+    expires_at timestamp with time zone
+);
+
+COMMENT ON TABLE session_recordings IS 'Web terminal sessions recorded in asciinema format. The recordings themselves are kept in the session recording store.';
+
+COMMENT ON COLUMN session_recordings.object_key IS 'Key of the recording in the session recording store.';
+
+COMMENT ON COLUMN session_recordings.expires_at IS 'Time after which the recording is deleted. NULL keeps the recording forever.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value character varying(8192) NOT NULL
@@ -663,6 +1033,20 @@ CREATE TABLE tailnet_tunnels (
     updated_at timestamp with time zone NOT NULL
 );
 
+CREATE TABLE template_variable_values (
+    template_id uuid NOT NULL,
+    name text NOT NULL,
+    value text NOT NULL,
+    value_key_id text,
+    sensitive boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_variable_values IS 'Values of template variables set on a template. They take precedence over the values the active version was pushed with, so they can be changed without pushing a new version.';
+
+COMMENT ON COLUMN template_variable_values.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -681,6 +1065,7 @@ CREATE TABLE template_version_parameters (
     display_name text DEFAULT ''::text NOT NULL,
     display_order integer DEFAULT 0 NOT NULL,
     ephemeral boolean DEFAULT false NOT NULL,
+    display_condition text DEFAULT ''::text NOT NULL,
     CONSTRAINT validation_monotonic_order CHECK ((validation_monotonic = ANY (ARRAY['increasing'::text, 'decreasing'::text, ''::text])))
 );
 
@@ -716,6 +1101,19 @@ COMMENT ON COLUMN template_version_parameters.display_order IS 'Specifies the or
 
 COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephemeral parameter will not be preserved between consecutive workspace builds.';
 
+COMMENT ON COLUMN template_version_parameters.display_condition IS 'Expression over the values of other parameters. The parameter is only displayed and validated if it is met.';
+
+CREATE TABLE template_version_presets (
+    template_version_id uuid NOT NULL,
+    name text NOT NULL,
+    parameters jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of parameter values of a template version, which can be selected when creating a workspace.';
+
+COMMENT ON COLUMN template_version_presets.parameters IS 'Array of parameter names and values, validated against the parameters of the template version when the preset is created.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -753,13 +1151,22 @@ CREATE TABLE template_versions (
     created_by uuid NOT NULL,
     external_auth_providers text[],
     message character varying(1048576) DEFAULT ''::character varying NOT NULL,
-    archived boolean DEFAULT false NOT NULL
+    archived boolean DEFAULT false NOT NULL,
+    git_commit_sha text DEFAULT ''::text NOT NULL,
+    git_branch text DEFAULT ''::text NOT NULL,
+    git_tag text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN template_versions.external_auth_providers IS 'IDs of External auth providers for a specific template version';
 
 COMMENT ON COLUMN template_versions.message IS 'Message describing the changes in this version of the template, similar to a Git commit message. Like a commit message, this should be a short, high-level description of the changes in this version of the template. This message is immutable and should not be updated after the fact.';
 
+COMMENT ON COLUMN template_versions.git_commit_sha IS 'SHA of the git commit the template version was pushed from, if the template directory is a git repository.';
+
+COMMENT ON COLUMN template_versions.git_branch IS 'Branch checked out when the template version was pushed, empty for a detached HEAD.';
+
+COMMENT ON COLUMN template_versions.git_tag IS 'Tag pointing at the git commit the template version was pushed from, if any.';
+
 CREATE TABLE users (
     id uuid NOT NULL,
     email text NOT NULL,
@@ -775,7 +1182,8 @@ CREATE TABLE users (
     last_seen_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
     quiet_hours_schedule text DEFAULT ''::text NOT NULL,
     theme_preference text DEFAULT ''::text NOT NULL,
-    name text DEFAULT ''::text NOT NULL
+    name text DEFAULT ''::text NOT NULL,
+    ip_allowlist text[] DEFAULT '{}'::text[] NOT NULL
 );
 
 COMMENT ON COLUMN users.quiet_hours_schedule IS 'Daily (!) cron schedule (with optional CRON_TZ) signifying the start of the user''s quiet hours. If empty, the default quiet hours on the instance is used instead.';
@@ -784,6 +1192,8 @@ COMMENT ON COLUMN users.theme_preference IS '"" can be interpreted as "the user
 
 COMMENT ON COLUMN users.name IS 'Name of the Coder user';
 
+COMMENT ON COLUMN users.ip_allowlist IS 'CIDR ranges the API keys of the user may be used from. If empty, the keys may be used from any address.';
+
 CREATE VIEW visible_users AS
  SELECT users.id,
     users.username,
@@ -805,6 +1215,9 @@ CREATE VIEW template_version_with_user AS
     template_versions.external_auth_providers,
     template_versions.message,
     template_versions.archived,
+    template_versions.git_commit_sha,
+    template_versions.git_branch,
+    template_versions.git_tag,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.template_versions
@@ -840,7 +1253,20 @@ CREATE TABLE templates (
     autostart_block_days_of_week smallint DEFAULT 0 NOT NULL,
     require_active_version boolean DEFAULT false NOT NULL,
     deprecated text DEFAULT ''::text NOT NULL,
-    use_max_ttl boolean DEFAULT false NOT NULL
+    use_max_ttl boolean DEFAULT false NOT NULL,
+    maintenance_window_schedule text DEFAULT ''::text NOT NULL,
+    maintenance_window_duration bigint DEFAULT 0 NOT NULL,
+    visibility template_visibility DEFAULT 'public'::template_visibility NOT NULL,
+    max_build_duration bigint DEFAULT 0 NOT NULL,
+    workspace_name_pattern text DEFAULT ''::text NOT NULL,
+    workspace_name_description text DEFAULT ''::text NOT NULL,
+    max_running_workspaces integer DEFAULT 0 NOT NULL,
+    port_forwarding_deny_by_default boolean DEFAULT false NOT NULL,
+    port_forwarding_allowed_ports integer[] DEFAULT '{}'::integer[] NOT NULL,
+    session_recording_enabled boolean DEFAULT false NOT NULL,
+    session_recording_retention bigint DEFAULT 0 NOT NULL,
+    build_retry_max_attempts integer DEFAULT 0 NOT NULL,
+    build_retry_backoff bigint DEFAULT 0 NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -861,6 +1287,32 @@ COMMENT ON COLUMN templates.autostart_block_days_of_week IS 'A bitmap of days of
 
 COMMENT ON COLUMN templates.deprecated IS 'If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.';
 
+COMMENT ON COLUMN templates.maintenance_window_schedule IS 'A cron expression for the start of the maintenance window. During the window, running workspaces on an outdated template version are stopped and rebuilt on the active version. Empty disables maintenance.';
+
+COMMENT ON COLUMN templates.maintenance_window_duration IS 'The duration of the maintenance window in nanoseconds.';
+
+COMMENT ON COLUMN templates.visibility IS 'Restricts who can see the template, on top of its ACL.';
+
+COMMENT ON COLUMN templates.max_build_duration IS 'The maximum duration of workspace builds in nanoseconds. Builds running for longer are failed and aborted. Zero disables the limit.';
+
+COMMENT ON COLUMN templates.workspace_name_pattern IS 'A regular expression that the names of workspaces created from the template must match. Empty allows any valid name.';
+
+COMMENT ON COLUMN templates.workspace_name_description IS 'A human readable description of workspace_name_pattern shown when a name doesn''t match.';
+
+COMMENT ON COLUMN templates.max_running_workspaces IS 'The maximum number of running workspaces of the template. Zero disables the limit.';
+
+COMMENT ON COLUMN templates.port_forwarding_deny_by_default IS 'Denies forwarding and sharing agent ports, except for those in port_forwarding_allowed_ports.';
+
+COMMENT ON COLUMN templates.port_forwarding_allowed_ports IS 'The agent ports that may be forwarded and shared when port_forwarding_deny_by_default is set.';
+
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Records the web terminal sessions of workspaces of the template.';
+
+COMMENT ON COLUMN templates.session_recording_retention IS 'Duration in nanoseconds session recordings are kept for. Zero keeps them forever.';
+
+COMMENT ON COLUMN templates.build_retry_max_attempts IS 'The maximum number of attempts of a workspace build that failed transiently, including the first attempt. Values below 2 disable retries.';
+
+COMMENT ON COLUMN templates.build_retry_backoff IS 'Duration in nanoseconds before the first retry of a failed workspace build. The delay doubles with every attempt.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -890,6 +1342,19 @@ CREATE VIEW template_with_users AS
     templates.require_active_version,
     templates.deprecated,
     templates.use_max_ttl,
+    templates.maintenance_window_schedule,
+    templates.maintenance_window_duration,
+    templates.visibility,
+    templates.max_build_duration,
+    templates.workspace_name_pattern,
+    templates.workspace_name_description,
+    templates.max_running_workspaces,
+    templates.port_forwarding_deny_by_default,
+    templates.port_forwarding_allowed_ports,
+    templates.session_recording_enabled,
+    templates.session_recording_retention,
+    templates.build_retry_max_attempts,
+    templates.build_retry_backoff,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.templates
@@ -897,6 +1362,56 @@ CREATE VIEW template_with_users AS
 
 COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
 
+CREATE TABLE two_factor_challenges (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    purpose two_factor_challenge_purpose NOT NULL,
+    challenge bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE two_factor_challenges IS 'Pending WebAuthn challenges. Each challenge can only be answered once.';
+
+CREATE TABLE two_factor_policies (
+    id uuid NOT NULL,
+    role text NOT NULL,
+    enforce_after timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE two_factor_policies IS 'Site roles whose members must use a second factor for password logins.';
+
+COMMENT ON COLUMN two_factor_policies.enforce_after IS 'Password logins without a second factor are rejected after this time, which gives users time to register a passkey.';
+
+CREATE TABLE unknown_login_failures (
+    login_hash bytea NOT NULL,
+    ip_address inet NOT NULL,
+    failed_attempts integer DEFAULT 0 NOT NULL,
+    last_failed_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE unknown_login_failures IS 'Failed password logins for emails and usernames that don''t belong to a user, throttled like those of existing users so that the responses don''t reveal which users exist.';
+
+COMMENT ON COLUMN unknown_login_failures.login_hash IS 'SHA256 of the lowercased email or username. The login itself isn''t stored, it might be a mistyped password.';
+
+COMMENT ON COLUMN unknown_login_failures.failed_attempts IS 'The number of failed attempts since the count was last reset by the failures expiring.';
+
+CREATE TABLE user_activity (
+    user_id uuid NOT NULL,
+    hour timestamp with time zone NOT NULL,
+    category text NOT NULL,
+    workspace_id uuid DEFAULT '00000000-0000-0000-0000-000000000000'::uuid NOT NULL,
+    count integer DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE user_activity IS 'Number of API requests made by each user, by hour, API category and workspace.';
+
+COMMENT ON COLUMN user_activity.category IS 'The API category of the requests, e.g. workspaces or templates.';
+
+COMMENT ON COLUMN user_activity.workspace_id IS 'The workspace the requests were about, or the nil UUID if they were not about a workspace.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -915,6 +1430,82 @@ COMMENT ON COLUMN user_links.oauth_refresh_token_key_id IS 'The ID of the key us
 
 COMMENT ON COLUMN user_links.debug_context IS 'Debug information includes information like id_token and userinfo claims.';
 
+CREATE TABLE user_lockouts (
+    user_id uuid NOT NULL,
+    failed_attempts integer NOT NULL,
+    locked_at timestamp with time zone NOT NULL,
+    locked_until timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_lockouts IS 'Users that can''t log in with a password until locked_until because of too many failed attempts.';
+
+COMMENT ON COLUMN user_lockouts.failed_attempts IS 'The number of failed attempts from all IP addresses that caused the lockout.';
+
+CREATE TABLE user_recovery_codes (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    hashed_code bytea NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_recovery_codes IS 'Single use codes that can be used instead of a passkey to complete a login.';
+
+CREATE TABLE user_scim_external_ids (
+    user_id uuid NOT NULL,
+    external_id text NOT NULL
+);
+
+COMMENT ON TABLE user_scim_external_ids IS 'The identifier a SCIM identity provider assigned to a user it provisioned.';
+
+CREATE TABLE webhook_deliveries (
+    id uuid NOT NULL,
+    webhook_id uuid NOT NULL,
+    event text NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    status_code integer,
+    last_error text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    delivered_at timestamp with time zone,
+    next_attempt_at timestamp with time zone
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Every attempt to send an event to a webhook, kept for auditing and replay.';
+
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'HTTP status code of the most recent attempt, if a response was received.';
+
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery should next be attempted. NULL once the delivery has succeeded or run out of attempts.';
+
+CREATE TABLE webhooks (
+    id uuid NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    events text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    secret_key_id text
+);
+
+COMMENT ON TABLE webhooks IS 'HTTPS endpoints registered by deployment admins to receive signed lifecycle events.';
+
+COMMENT ON COLUMN webhooks.secret IS 'Shared secret used to sign each delivery with HMAC-SHA256.';
+
+COMMENT ON COLUMN webhooks.events IS 'Event types the webhook is subscribed to.';
+
+COMMENT ON COLUMN webhooks.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted';
+
+CREATE TABLE workspace_agent_gpus (
+    agent_id uuid NOT NULL,
+    index integer NOT NULL,
+    vendor text NOT NULL,
+    model text NOT NULL,
+    memory_bytes bigint NOT NULL,
+    driver_version text NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_gpus IS 'GPUs that workspace agents detected in their workspace on startup.';
+
 CREATE TABLE workspace_agent_log_sources (
     workspace_agent_id uuid NOT NULL,
     id uuid NOT NULL,
@@ -944,6 +1535,44 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE UNLOGGED TABLE workspace_agent_metadata_history (
+    workspace_agent_id uuid NOT NULL,
+    key character varying(127) NOT NULL,
+    value character varying(65535) DEFAULT ''::character varying NOT NULL,
+    error character varying(65535) DEFAULT ''::character varying NOT NULL,
+    collected_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS 'Recent values of workspace agent metadata. Only a limited number of values is kept per metadatum, older ones are deleted as new ones are recorded.';
+
+CREATE TABLE workspace_agent_port_shares (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    port integer NOT NULL,
+    share_level app_sharing_level NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_port_shares IS 'Agent ports that workspace owners shared with other users. Port-based app URLs of shared ports use the share level instead of owner.';
+
+COMMENT ON COLUMN workspace_agent_port_shares.agent_name IS 'Shares reference agents by name, so they are kept across workspace builds.';
+
+CREATE TABLE workspace_agent_ports (
+    agent_id uuid NOT NULL,
+    port integer NOT NULL,
+    process_name text NOT NULL,
+    discovered_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_ports IS 'TCP ports that workspace agents found listening in their workspace. They are exposed as discovered apps.';
+
+CREATE TABLE workspace_agent_previous_auth_tokens (
+    agent_id uuid NOT NULL,
+    auth_token uuid NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_previous_auth_tokens IS 'The token an agent used before its last rotation. It stays valid until it expires so requests already in flight are not rejected.';
+
 CREATE TABLE workspace_agent_scripts (
     workspace_agent_id uuid NOT NULL,
     log_source_id uuid NOT NULL,
@@ -954,9 +1583,12 @@ CREATE TABLE workspace_agent_scripts (
     start_blocks_login boolean NOT NULL,
     run_on_start boolean NOT NULL,
     run_on_stop boolean NOT NULL,
-    timeout_seconds integer NOT NULL
+    timeout_seconds integer NOT NULL,
+    phase workspace_agent_script_phase DEFAULT 'start'::workspace_agent_script_phase NOT NULL
 );
 
+COMMENT ON COLUMN workspace_agent_scripts.phase IS 'The phase of the start or stop lifecycle the script runs in. The agent runs the phases in order, and the scripts of a phase in parallel.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -983,7 +1615,13 @@ CREATE TABLE workspace_agent_stats (
     session_count_vscode bigint DEFAULT 0 NOT NULL,
     session_count_jetbrains bigint DEFAULT 0 NOT NULL,
     session_count_reconnecting_pty bigint DEFAULT 0 NOT NULL,
-    session_count_ssh bigint DEFAULT 0 NOT NULL
+    session_count_ssh bigint DEFAULT 0 NOT NULL,
+    cpu_used_cores double precision DEFAULT 0 NOT NULL,
+    cpu_total_cores double precision DEFAULT 0 NOT NULL,
+    memory_used_bytes bigint DEFAULT 0 NOT NULL,
+    memory_total_bytes bigint DEFAULT 0 NOT NULL,
+    disk_used_bytes bigint DEFAULT 0 NOT NULL,
+    disk_total_bytes bigint DEFAULT 0 NOT NULL
 );
 
 CREATE TABLE workspace_agents (
@@ -1127,9 +1765,15 @@ CREATE TABLE workspace_builds (
     deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
     reason build_reason DEFAULT 'initiator'::build_reason NOT NULL,
     daily_cost integer DEFAULT 0 NOT NULL,
-    max_deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+    max_deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    attempt integer DEFAULT 1 NOT NULL,
+    retry_at timestamp with time zone
 );
 
+COMMENT ON COLUMN workspace_builds.attempt IS 'Attempt number of the build. Builds retrying a failed build have the attempt number of the failed build plus one.';
+
+COMMENT ON COLUMN workspace_builds.retry_at IS 'Time at which the failed build is retried. NULL if the build did not fail, or failed for good.';
+
 CREATE VIEW workspace_build_with_user AS
  SELECT workspace_builds.id,
     workspace_builds.created_at,
@@ -1145,6 +1789,8 @@ CREATE VIEW workspace_build_with_user AS
     workspace_builds.reason,
     workspace_builds.daily_cost,
     workspace_builds.max_deadline,
+    workspace_builds.attempt,
+    workspace_builds.retry_at,
     COALESCE(visible_users.avatar_url, ''::text) AS initiator_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS initiator_by_username
    FROM (public.workspace_builds
@@ -1152,6 +1798,70 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_drift (
+    workspace_id uuid NOT NULL,
+    job_id uuid NOT NULL,
+    build_id uuid,
+    checked_at timestamp with time zone,
+    drifted_resources text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_drift IS 'The result of the latest drift check of each running workspace.';
+
+COMMENT ON COLUMN workspace_drift.job_id IS 'The dry-run job of the latest drift check. While it runs, the other columns hold the result of the check before it.';
+
+COMMENT ON COLUMN workspace_drift.build_id IS 'The workspace build whose state the last completed check planned against.';
+
+COMMENT ON COLUMN workspace_drift.drifted_resources IS 'The addresses of the resources that were changed outside of Coder.';
+
+CREATE TABLE workspace_events (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    type workspace_event_type NOT NULL,
+    user_id uuid,
+    agent_id uuid,
+    app_id uuid,
+    old_value text DEFAULT ''::text NOT NULL,
+    new_value text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE workspace_events IS 'Events of a workspace that are not recorded elsewhere. Together with the builds of the workspace they make up its timeline.';
+
+COMMENT ON COLUMN workspace_events.user_id IS 'The user who caused the event. Null for events caused by the workspace itself.';
+
+COMMENT ON COLUMN workspace_events.old_value IS 'The value before the change: the TTL in milliseconds for ttl_changed, empty if autostop was disabled, and the app health for app_health_changed.';
+
+COMMENT ON COLUMN workspace_events.new_value IS 'The value after the change, in the same format as old_value.';
+
+CREATE TABLE workspace_favorites (
+    user_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE workspace_favorites IS 'Workspaces that a user has pinned to the top of their workspace list.';
+
+CREATE TABLE workspace_parameter_changes (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_id uuid NOT NULL,
+    workspace_build_id uuid NOT NULL,
+    build_number integer NOT NULL,
+    name text NOT NULL,
+    old_value text,
+    new_value text NOT NULL,
+    initiator_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_parameter_changes IS 'History of the parameter values of workspaces. Rows are recorded when build parameters are inserted, and are never updated.';
+
+COMMENT ON COLUMN workspace_parameter_changes.old_value IS 'Value of the parameter in the previous build, or NULL if the parameter wasn''t set.';
+
+COMMENT ON COLUMN workspace_parameter_changes.initiator_id IS 'User who started the build that changed the value.';
+
 CREATE TABLE workspace_proxies (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -1191,6 +1901,40 @@ CREATE SEQUENCE workspace_proxies_region_id_seq
 
 ALTER SEQUENCE workspace_proxies_region_id_seq OWNED BY workspace_proxies.region_id;
 
+CREATE TABLE workspace_proxy_health (
+    proxy_id uuid NOT NULL,
+    status text NOT NULL,
+    latency_ms integer NOT NULL,
+    derp_reachable boolean NOT NULL,
+    version_skew boolean NOT NULL,
+    errors text[] DEFAULT '{}'::text[] NOT NULL,
+    warnings text[] DEFAULT '{}'::text[] NOT NULL,
+    checked_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_proxy_health IS 'The latest health check of each workspace proxy, run periodically by coderd.';
+
+COMMENT ON COLUMN workspace_proxy_health.latency_ms IS 'The round trip time of the health check request from coderd to the proxy.';
+
+COMMENT ON COLUMN workspace_proxy_health.version_skew IS 'Whether the major or minor version of the proxy differs from coderd.';
+
+CREATE TABLE workspace_resource_costs (
+    workspace_id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    date date NOT NULL,
+    resource_type character varying(192) NOT NULL,
+    resource_name character varying(64) NOT NULL,
+    currency text NOT NULL,
+    daily_cost integer NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_resource_costs IS 'The daily cost of each workspace resource, recorded once per day the resource existed.';
+
+COMMENT ON COLUMN workspace_resource_costs.currency IS 'The currency of the cost, taken from the "currency" metadata of the resource.';
+
+COMMENT ON COLUMN workspace_resource_costs.daily_cost IS 'The last known daily cost of the resource on the date.';
+
 CREATE TABLE workspace_resource_metadata (
     workspace_resource_id uuid NOT NULL,
     key character varying(1024) NOT NULL,
@@ -1218,9 +1962,25 @@ CREATE TABLE workspace_resources (
     hide boolean DEFAULT false NOT NULL,
     icon character varying(256) DEFAULT ''::character varying NOT NULL,
     instance_type character varying(256),
-    daily_cost integer DEFAULT 0 NOT NULL
+    daily_cost integer DEFAULT 0 NOT NULL,
+    snapshot boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspace_resources.snapshot IS 'Whether the resource is captured by snapshots of the workspace.';
+
+CREATE TABLE workspace_snapshots (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    build_id uuid NOT NULL,
+    name text NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of the snapshot-able resources of workspaces.';
+
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The workspace build that took the snapshot.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1235,9 +1995,24 @@ CREATE TABLE workspaces (
     last_used_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
     dormant_at timestamp with time zone,
     deleting_at timestamp with time zone,
-    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL
+    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL,
+    maintenance_opt_out boolean DEFAULT false NOT NULL,
+    user_acl jsonb DEFAULT '{}'::jsonb NOT NULL,
+    ephemeral boolean DEFAULT false NOT NULL,
+    ephemeral_api_key_id text,
+    dormancy_exempt boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.maintenance_opt_out IS 'Prevents template maintenance windows from stopping and rebuilding the workspace.';
+
+COMMENT ON COLUMN workspaces.user_acl IS 'Users the workspace is shared with, mapped to the actions they can perform on it.';
+
+COMMENT ON COLUMN workspaces.ephemeral IS 'Ephemeral workspaces are deleted, rather than stopped, once they outlive their TTL or the API token that created them expires.';
+
+COMMENT ON COLUMN workspaces.ephemeral_api_key_id IS 'The API token that created the ephemeral workspace, if any. The workspace is deleted when the token expires or is deleted.';
+
+COMMENT ON COLUMN workspaces.dormancy_exempt IS 'Prevents the workspace from becoming dormant or being deleted for inactivity.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
@@ -1259,6 +2034,12 @@ ALTER TABLE ONLY api_keys
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY connection_logs
+    ADD CONSTRAINT connection_logs_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY custom_roles
+    ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (name);
+
 ALTER TABLE ONLY dbcrypt_keys
     ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
 
@@ -1283,24 +2064,51 @@ ALTER TABLE ONLY gitsshkeys
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
 
+ALTER TABLE ONLY group_sync_runs
+    ADD CONSTRAINT group_sync_runs_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY inbox_notifications
+    ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY login_failures
+    ADD CONSTRAINT login_failures_pkey PRIMARY KEY (user_id, ip_address);
+
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, template);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_app_id_hashed_secret_key UNIQUE (app_id, hashed_secret);
 
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY oauth2_provider_apps
     ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
 
@@ -1310,6 +2118,12 @@ ALTER TABLE ONLY oauth2_provider_apps
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
+ALTER TABLE ONLY organization_quotas
+    ADD CONSTRAINT organization_quotas_pkey PRIMARY KEY (organization_id);
+
+ALTER TABLE ONLY organization_template_variable_values
+    ADD CONSTRAINT organization_template_variable_values_pkey PRIMARY KEY (organization_id, name);
+
 ALTER TABLE ONLY organizations
     ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
 
@@ -1325,15 +2139,33 @@ ALTER TABLE ONLY parameter_values
 ALTER TABLE ONLY parameter_values
     ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
 
+ALTER TABLE ONLY passkeys
+    ADD CONSTRAINT passkeys_credential_id_key UNIQUE (credential_id);
+
+ALTER TABLE ONLY passkeys
+    ADD CONSTRAINT passkeys_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_keys
+    ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY rate_limit_counters
+    ADD CONSTRAINT rate_limit_counters_pkey PRIMARY KEY (key, window_start);
+
+ALTER TABLE ONLY session_recordings
+    ADD CONSTRAINT session_recordings_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY site_configs
     ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 
@@ -1355,9 +2187,15 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
 
+ALTER TABLE ONLY template_variable_values
+    ADD CONSTRAINT template_variable_values_pkey PRIMARY KEY (template_id, name);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (template_version_id, name);
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -1370,18 +2208,66 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY two_factor_challenges
+    ADD CONSTRAINT two_factor_challenges_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY two_factor_policies
+    ADD CONSTRAINT two_factor_policies_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY two_factor_policies
+    ADD CONSTRAINT two_factor_policies_role_key UNIQUE (role);
+
+ALTER TABLE ONLY unknown_login_failures
+    ADD CONSTRAINT unknown_login_failures_pkey PRIMARY KEY (login_hash, ip_address);
+
+ALTER TABLE ONLY user_activity
+    ADD CONSTRAINT user_activity_pkey PRIMARY KEY (user_id, hour, category, workspace_id);
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_lockouts
+    ADD CONSTRAINT user_lockouts_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_recovery_codes
+    ADD CONSTRAINT user_recovery_codes_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY user_scim_external_ids
+    ADD CONSTRAINT user_scim_external_ids_external_id_key UNIQUE (external_id);
+
+ALTER TABLE ONLY user_scim_external_ids
+    ADD CONSTRAINT user_scim_external_ids_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_gpus
+    ADD CONSTRAINT workspace_agent_gpus_pkey PRIMARY KEY (agent_id, vendor, index);
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_pkey PRIMARY KEY (workspace_agent_id, key, collected_at);
+
+ALTER TABLE ONLY workspace_agent_port_shares
+    ADD CONSTRAINT workspace_agent_port_shares_pkey PRIMARY KEY (workspace_id, agent_name, port);
+
+ALTER TABLE ONLY workspace_agent_ports
+    ADD CONSTRAINT workspace_agent_ports_pkey PRIMARY KEY (agent_id, port);
+
+ALTER TABLE ONLY workspace_agent_previous_auth_tokens
+    ADD CONSTRAINT workspace_agent_previous_auth_tokens_pkey PRIMARY KEY (agent_id);
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -1412,12 +2298,30 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_drift
+    ADD CONSTRAINT workspace_drift_pkey PRIMARY KEY (workspace_id);
+
+ALTER TABLE ONLY workspace_events
+    ADD CONSTRAINT workspace_events_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_favorites
+    ADD CONSTRAINT workspace_favorites_pkey PRIMARY KEY (user_id, workspace_id);
+
+ALTER TABLE ONLY workspace_parameter_changes
+    ADD CONSTRAINT workspace_parameter_changes_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
 
+ALTER TABLE ONLY workspace_proxy_health
+    ADD CONSTRAINT workspace_proxy_health_pkey PRIMARY KEY (proxy_id);
+
+ALTER TABLE ONLY workspace_resource_costs
+    ADD CONSTRAINT workspace_resource_costs_pkey PRIMARY KEY (workspace_id, date, resource_type, resource_name);
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 
@@ -1427,9 +2331,17 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_name_key UNIQUE (workspace_id, name);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
+CREATE INDEX group_sync_runs_started_at_idx ON group_sync_runs USING btree (started_at DESC);
+
 CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
 
 CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
@@ -1446,6 +2358,12 @@ CREATE INDEX idx_audit_log_user_id ON audit_logs USING btree (user_id);
 
 CREATE INDEX idx_audit_logs_time_desc ON audit_logs USING btree ("time" DESC);
 
+CREATE INDEX idx_audit_logs_time_id_desc ON audit_logs USING btree ("time" DESC, id DESC);
+
+CREATE INDEX idx_connection_logs_time_desc ON connection_logs USING btree ("time" DESC);
+
+CREATE INDEX idx_connection_logs_workspace_id ON connection_logs USING btree (workspace_id);
+
 CREATE INDEX idx_organization_member_organization_id_uuid ON organization_members USING btree (organization_id);
 
 CREATE INDEX idx_organization_member_user_id_uuid ON organization_members USING btree (user_id);
@@ -1458,6 +2376,10 @@ CREATE UNIQUE INDEX idx_provisioner_daemons_name_owner_key ON provisioner_daemon
 
 COMMENT ON INDEX idx_provisioner_daemons_name_owner_key IS 'Allow unique provisioner daemon names by user';
 
+CREATE INDEX idx_session_recordings_expires_at ON session_recordings USING btree (expires_at) WHERE (expires_at IS NOT NULL);
+
+CREATE INDEX idx_session_recordings_workspace_id ON session_recordings USING btree (workspace_id);
+
 CREATE INDEX idx_tailnet_agents_coordinator ON tailnet_agents USING btree (coordinator_id);
 
 CREATE INDEX idx_tailnet_clients_coordinator ON tailnet_clients USING btree (coordinator_id);
@@ -1468,18 +2390,52 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX inbox_notifications_user_id_created_at_idx ON inbox_notifications USING btree (user_id, created_at DESC);
+
+CREATE INDEX inbox_notifications_user_id_unread_idx ON inbox_notifications USING btree (user_id) WHERE (read_at IS NULL);
+
+CREATE INDEX login_failures_last_failed_at_idx ON login_failures USING btree (last_failed_at);
+
+CREATE INDEX notification_messages_next_attempt_at_idx ON notification_messages USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+
+CREATE INDEX notification_messages_user_id_created_at_idx ON notification_messages USING btree (user_id, created_at DESC);
+
+CREATE INDEX passkeys_user_id_idx ON passkeys USING btree (user_id);
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
+CREATE UNIQUE INDEX provisioner_keys_hashed_secret_idx ON provisioner_keys USING btree (hashed_secret);
+
+CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+
+CREATE INDEX rate_limit_counters_window_start_idx ON rate_limit_counters USING btree (window_start);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
+CREATE INDEX unknown_login_failures_last_failed_at_idx ON unknown_login_failures USING btree (last_failed_at);
+
+CREATE INDEX user_activity_hour_idx ON user_activity USING btree (hour);
+
+CREATE INDEX user_recovery_codes_user_id_idx ON user_recovery_codes USING btree (user_id);
+
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+
+CREATE INDEX workspace_agent_metadata_history_collected_at_idx ON workspace_agent_metadata_history USING btree (collected_at);
+
+CREATE INDEX workspace_agent_previous_auth_tokens_auth_token_idx ON workspace_agent_previous_auth_tokens USING btree (auth_token);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
+CREATE INDEX workspace_agent_stats_agent_id_created_at_idx ON workspace_agent_stats USING btree (agent_id, created_at DESC);
+
 CREATE INDEX workspace_agent_stats_template_id_created_at_user_id_idx ON workspace_agent_stats USING btree (template_id, created_at, user_id) INCLUDE (session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, connection_median_latency_ms) WHERE (connection_count > 0);
 
 COMMENT ON INDEX workspace_agent_stats_template_id_created_at_user_id_idx IS 'Support index for template insights endpoint to build interval reports faster.';
@@ -1490,8 +2446,16 @@ CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (r
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_events_created_at_idx ON workspace_events USING btree (created_at);
+
+CREATE INDEX workspace_events_workspace_id_created_at_idx ON workspace_events USING btree (workspace_id, created_at DESC);
+
+CREATE INDEX workspace_parameter_changes_workspace_id_build_number_idx ON workspace_parameter_changes USING btree (workspace_id, build_number DESC);
+
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
+CREATE INDEX workspace_resource_costs_organization_id_date_idx ON workspace_resource_costs USING btree (organization_id, date);
+
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
 
 CREATE UNIQUE INDEX workspaces_owner_id_lower_idx ON workspaces USING btree (owner_id, lower((name)::text)) WHERE (deleted = false);
@@ -1508,8 +2472,12 @@ CREATE TRIGGER tailnet_notify_peer_change AFTER INSERT OR DELETE OR UPDATE ON ta
 
 CREATE TRIGGER tailnet_notify_tunnel_change AFTER INSERT OR DELETE OR UPDATE ON tailnet_tunnels FOR EACH ROW EXECUTE FUNCTION tailnet_notify_tunnel_change();
 
+CREATE TRIGGER trigger_delete_oauth2_provider_app_token AFTER DELETE ON oauth2_provider_app_tokens FOR EACH ROW EXECUTE FUNCTION delete_deleted_oauth2_provider_app_token_api_key();
+
 CREATE TRIGGER trigger_insert_apikeys BEFORE INSERT ON api_keys FOR EACH ROW EXECUTE FUNCTION insert_apikey_fail_if_user_deleted();
 
+CREATE TRIGGER trigger_insert_workspace_build_parameters AFTER INSERT ON workspace_build_parameters FOR EACH ROW EXECUTE FUNCTION insert_workspace_parameter_change();
+
 CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW WHEN ((new.deleted = true)) EXECUTE FUNCTION delete_deleted_user_api_keys();
 
 ALTER TABLE ONLY api_keys
@@ -1530,27 +2498,75 @@ ALTER TABLE ONLY group_members
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY group_sync_runs
+    ADD CONSTRAINT group_sync_runs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY inbox_notifications
+    ADD CONSTRAINT inbox_notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY login_failures
+    ADD CONSTRAINT login_failures_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_messages
+    ADD CONSTRAINT notification_messages_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY notification_preferences
+    ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_codes
+    ADD CONSTRAINT oauth2_provider_app_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY oauth2_provider_app_secrets
     ADD CONSTRAINT oauth2_provider_app_secrets_app_id_fkey FOREIGN KEY (app_id) REFERENCES oauth2_provider_apps(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_api_key_id_fkey FOREIGN KEY (api_key_id) REFERENCES api_keys(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY oauth2_provider_app_tokens
+    ADD CONSTRAINT oauth2_provider_app_tokens_app_secret_id_fkey FOREIGN KEY (app_secret_id) REFERENCES oauth2_provider_app_secrets(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY organization_quotas
+    ADD CONSTRAINT organization_quotas_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY organization_template_variable_values
+    ADD CONSTRAINT organization_template_variable_values_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY organization_template_variable_values
+    ADD CONSTRAINT organization_template_variable_values_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY passkeys
+    ADD CONSTRAINT passkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY provisioner_daemons
+    ADD CONSTRAINT provisioner_daemons_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY provisioner_job_log_archives
+    ADD CONSTRAINT provisioner_job_log_archives_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY provisioner_jobs
     ADD CONSTRAINT provisioner_jobs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_keys
+    ADD CONSTRAINT provisioner_keys_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY tailnet_agents
     ADD CONSTRAINT tailnet_agents_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
@@ -1566,9 +2582,18 @@ ALTER TABLE ONLY tailnet_peers
 ALTER TABLE ONLY tailnet_tunnels
     ADD CONSTRAINT tailnet_tunnels_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_variable_values
+    ADD CONSTRAINT template_variable_values_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_variable_values
+    ADD CONSTRAINT template_variable_values_value_key_id_fkey FOREIGN KEY (value_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_presets
+    ADD CONSTRAINT template_version_presets_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -1587,6 +2612,12 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY two_factor_challenges
+    ADD CONSTRAINT two_factor_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_activity
+    ADD CONSTRAINT user_activity_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_oauth_access_token_key_id_fkey FOREIGN KEY (oauth_access_token_key_id) REFERENCES dbcrypt_keys(active_key_digest);
 
@@ -1596,12 +2627,42 @@ ALTER TABLE ONLY user_links
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_lockouts
+    ADD CONSTRAINT user_lockouts_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_recovery_codes
+    ADD CONSTRAINT user_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_scim_external_ids
+    ADD CONSTRAINT user_scim_external_ids_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhook_deliveries
+    ADD CONSTRAINT webhook_deliveries_webhook_id_fkey FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY webhooks
+    ADD CONSTRAINT webhooks_secret_key_id_fkey FOREIGN KEY (secret_key_id) REFERENCES dbcrypt_keys(active_key_digest);
+
+ALTER TABLE ONLY workspace_agent_gpus
+    ADD CONSTRAINT workspace_agent_gpus_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_log_sources
     ADD CONSTRAINT workspace_agent_log_sources_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_workspace_agent_id_key_fkey FOREIGN KEY (workspace_agent_id, key) REFERENCES workspace_agent_metadata(workspace_agent_id, key) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_port_shares
+    ADD CONSTRAINT workspace_agent_port_shares_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_ports
+    ADD CONSTRAINT workspace_agent_ports_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_previous_auth_tokens
+    ADD CONSTRAINT workspace_agent_previous_auth_tokens_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_scripts
     ADD CONSTRAINT workspace_agent_scripts_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -1635,12 +2696,54 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_drift
+    ADD CONSTRAINT workspace_drift_build_id_fkey FOREIGN KEY (build_id) REFERENCES workspace_builds(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_drift
+    ADD CONSTRAINT workspace_drift_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_drift
+    ADD CONSTRAINT workspace_drift_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_events
+    ADD CONSTRAINT workspace_events_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_favorites
+    ADD CONSTRAINT workspace_favorites_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_favorites
+    ADD CONSTRAINT workspace_favorites_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_parameter_changes
+    ADD CONSTRAINT workspace_parameter_changes_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_parameter_changes
+    ADD CONSTRAINT workspace_parameter_changes_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_proxy_health
+    ADD CONSTRAINT workspace_proxy_health_proxy_id_fkey FOREIGN KEY (proxy_id) REFERENCES workspace_proxies(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_resource_costs
+    ADD CONSTRAINT workspace_resource_costs_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_resource_costs
+    ADD CONSTRAINT workspace_resource_costs_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_build_id_fkey FOREIGN KEY (build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE RESTRICT;
+
+ALTER TABLE ONLY workspace_snapshots
+    ADD CONSTRAINT workspace_snapshots_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
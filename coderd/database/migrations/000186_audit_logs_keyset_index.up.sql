@@ -0,0 +1,2 @@
+-- Supports keyset pagination of audit logs, which orders by time and then ID.
+CREATE INDEX idx_audit_logs_time_id_desc ON audit_logs USING btree ("time" DESC, id DESC);
@@ -0,0 +1,8 @@
+CREATE TABLE workspace_favorites (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (user_id, workspace_id)
+);
+
+COMMENT ON TABLE workspace_favorites IS 'Workspaces that a user has pinned to the top of their workspace list.';
@@ -0,0 +1,34 @@
+CREATE TABLE webhooks (
+	id uuid NOT NULL,
+	url text NOT NULL,
+	secret text NOT NULL,
+	events text[] NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE webhooks IS 'HTTPS endpoints registered by deployment admins to receive signed lifecycle events.';
+COMMENT ON COLUMN webhooks.secret IS 'Shared secret used to sign each delivery with HMAC-SHA256.';
+COMMENT ON COLUMN webhooks.events IS 'Event types the webhook is subscribed to.';
+
+CREATE TABLE webhook_deliveries (
+	id uuid NOT NULL,
+	webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+	event text NOT NULL,
+	payload jsonb NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	status_code integer,
+	last_error text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	delivered_at timestamp with time zone,
+	next_attempt_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE webhook_deliveries IS 'Every attempt to send an event to a webhook, kept for auditing and replay.';
+COMMENT ON COLUMN webhook_deliveries.status_code IS 'HTTP status code of the most recent attempt, if a response was received.';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When the delivery should next be attempted. NULL once the delivery has succeeded or run out of attempts.';
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries USING btree (webhook_id, created_at DESC);
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
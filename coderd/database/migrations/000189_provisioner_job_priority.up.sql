@@ -0,0 +1,3 @@
+ALTER TABLE ONLY provisioner_jobs
+    ADD COLUMN priority integer NOT NULL DEFAULT 0;
+COMMENT ON COLUMN provisioner_jobs.priority IS 'Pending jobs with a higher priority are acquired first. Within a priority, workspace builds are preferred over other job types.';
@@ -0,0 +1,2 @@
+ALTER TABLE ONLY provisioner_daemons
+    DROP COLUMN organization_id;
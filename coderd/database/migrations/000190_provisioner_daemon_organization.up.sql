@@ -0,0 +1,3 @@
+ALTER TABLE ONLY provisioner_daemons
+    ADD COLUMN organization_id uuid REFERENCES organizations(id) ON DELETE CASCADE;
+COMMENT ON COLUMN provisioner_daemons.organization_id IS 'The organization the daemon acquires jobs from. NULL means jobs from every organization.';
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS workspace_agent_stats_agent_id_created_at_idx;
+
+ALTER TABLE ONLY workspace_agent_stats
+    DROP COLUMN cpu_used_cores,
+    DROP COLUMN cpu_total_cores,
+    DROP COLUMN memory_used_bytes,
+    DROP COLUMN memory_total_bytes,
+    DROP COLUMN disk_used_bytes,
+    DROP COLUMN disk_total_bytes;
@@ -0,0 +1,10 @@
+ALTER TABLE ONLY workspace_agent_stats
+    ADD COLUMN cpu_used_cores double precision NOT NULL DEFAULT 0,
+    ADD COLUMN cpu_total_cores double precision NOT NULL DEFAULT 0,
+    ADD COLUMN memory_used_bytes bigint NOT NULL DEFAULT 0,
+    ADD COLUMN memory_total_bytes bigint NOT NULL DEFAULT 0,
+    ADD COLUMN disk_used_bytes bigint NOT NULL DEFAULT 0,
+    ADD COLUMN disk_total_bytes bigint NOT NULL DEFAULT 0;
+
+-- Supports fetching the latest stat reported by an agent.
+CREATE INDEX workspace_agent_stats_agent_id_created_at_idx ON workspace_agent_stats USING btree (agent_id, created_at DESC);
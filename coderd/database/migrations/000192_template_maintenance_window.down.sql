@@ -0,0 +1,25 @@
+-- It's not possible to delete enum values, so 'maintenance' is left on
+-- build_reason.
+DROP VIEW template_with_users;
+
+ALTER TABLE templates
+    DROP COLUMN maintenance_window_schedule,
+    DROP COLUMN maintenance_window_duration;
+
+ALTER TABLE workspaces DROP COLUMN maintenance_opt_out;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,30 @@
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'maintenance';
+
+ALTER TABLE templates
+    ADD COLUMN maintenance_window_schedule text NOT NULL DEFAULT '',
+    ADD COLUMN maintenance_window_duration bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.maintenance_window_schedule IS 'A cron expression for the start of the maintenance window. During the window, running workspaces on an outdated template version are stopped and rebuilt on the active version. Empty disables maintenance.';
+COMMENT ON COLUMN templates.maintenance_window_duration IS 'The duration of the maintenance window in nanoseconds.';
+
+ALTER TABLE workspaces ADD COLUMN maintenance_opt_out boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspaces.maintenance_opt_out IS 'Prevents template maintenance windows from stopping and rebuilding the workspace.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'template_update' and 'batch'
+-- are left on build_reason.
@@ -0,0 +1,2 @@
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'template_update';
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'batch';
@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'scim' is left on group_source.
+DROP TABLE IF EXISTS user_scim_external_ids;
@@ -0,0 +1,8 @@
+ALTER TYPE group_source ADD VALUE IF NOT EXISTS 'scim';
+
+CREATE TABLE user_scim_external_ids (
+	user_id uuid PRIMARY KEY NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	external_id text NOT NULL UNIQUE
+);
+
+COMMENT ON TABLE user_scim_external_ids IS 'The identifier a SCIM identity provider assigned to a user it provisioned.';
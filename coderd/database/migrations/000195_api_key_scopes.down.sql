@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+	DROP COLUMN allowed_workspace_ids,
+	DROP COLUMN scopes;
@@ -0,0 +1,7 @@
+ALTER TABLE api_keys
+	ADD COLUMN scopes text[] NOT NULL DEFAULT '{}',
+	ADD COLUMN allowed_workspace_ids uuid[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.scopes IS 'Fine-grained scopes the key is limited to, e.g. workspace:read. If empty, the key is only limited by its scope.';
+
+COMMENT ON COLUMN api_keys.allowed_workspace_ids IS 'Workspaces a key with fine-grained scopes is limited to. If empty, the key is not limited to specific workspaces.';
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trigger_delete_oauth2_provider_app_token ON oauth2_provider_app_tokens;
+DROP FUNCTION IF EXISTS delete_deleted_oauth2_provider_app_token_api_key;
+
+DROP TABLE IF EXISTS oauth2_provider_app_tokens;
+DROP TABLE IF EXISTS oauth2_provider_app_codes;
+
+-- It's not possible to delete enum values, so 'oauth2_provider_app' is left on
+-- login_type.
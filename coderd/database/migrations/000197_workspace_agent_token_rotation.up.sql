@@ -0,0 +1,10 @@
+CREATE TABLE workspace_agent_previous_auth_tokens (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	auth_token uuid NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (agent_id)
+);
+
+COMMENT ON TABLE workspace_agent_previous_auth_tokens IS 'The token an agent used before its last rotation. It stays valid until it expires so requests already in flight are not rejected.';
+
+CREATE INDEX workspace_agent_previous_auth_tokens_auth_token_idx ON workspace_agent_previous_auth_tokens USING btree (auth_token);
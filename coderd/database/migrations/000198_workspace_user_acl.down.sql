@@ -0,0 +1 @@
+ALTER TABLE workspaces DROP COLUMN user_acl;
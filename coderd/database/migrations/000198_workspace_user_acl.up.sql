@@ -0,0 +1,3 @@
+ALTER TABLE workspaces ADD COLUMN user_acl jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+COMMENT ON COLUMN workspaces.user_acl IS 'Users the workspace is shared with, mapped to the actions they can perform on it.';
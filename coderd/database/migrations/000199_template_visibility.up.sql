@@ -0,0 +1,29 @@
+CREATE TYPE template_visibility AS ENUM (
+	'private',
+	'unlisted',
+	'public'
+);
+
+COMMENT ON TYPE template_visibility IS 'Defines who can see a template: private, unlisted, or public.';
+
+ALTER TABLE templates ADD COLUMN visibility template_visibility NOT NULL DEFAULT 'public';
+
+COMMENT ON COLUMN templates.visibility IS 'Restricts who can see the template, on top of its ACL.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
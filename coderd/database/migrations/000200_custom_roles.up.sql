@@ -0,0 +1,11 @@
+CREATE TABLE custom_roles (
+	name text NOT NULL PRIMARY KEY,
+	display_name text NOT NULL,
+	site_permissions jsonb NOT NULL DEFAULT '[]'::jsonb,
+	created_at timestamp with time zone NOT NULL DEFAULT now(),
+	updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE custom_roles IS 'Custom site wide roles defined by administrators.';
+
+COMMENT ON COLUMN custom_roles.site_permissions IS 'The permissions granted by the role, as a list of resource and action pairs.';
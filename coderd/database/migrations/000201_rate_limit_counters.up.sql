@@ -0,0 +1,12 @@
+-- Counters are cheap to lose, so the table is unlogged to avoid adding WAL
+-- traffic for every API request.
+CREATE UNLOGGED TABLE rate_limit_counters (
+	key text NOT NULL,
+	window_start timestamp with time zone NOT NULL,
+	count integer NOT NULL DEFAULT 0,
+	PRIMARY KEY (key, window_start)
+);
+
+COMMENT ON TABLE rate_limit_counters IS 'Request counts per rate limit key and window, shared by all replicas.';
+
+CREATE INDEX rate_limit_counters_window_start_idx ON rate_limit_counters (window_start);
@@ -0,0 +1,13 @@
+CREATE TABLE provisioner_job_log_archives (
+	job_id uuid NOT NULL PRIMARY KEY REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	log_count integer NOT NULL,
+	uncompressed_size bigint NOT NULL,
+	compressed_logs bytea NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_log_archives IS 'Logs of completed provisioner jobs, compressed to save space. Archived logs are removed from provisioner_job_logs.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.uncompressed_size IS 'The size of the logs in bytes before they were compressed.';
+
+COMMENT ON COLUMN provisioner_job_log_archives.compressed_logs IS 'A gzip compressed JSON array of the archived provisioner_job_logs rows.';
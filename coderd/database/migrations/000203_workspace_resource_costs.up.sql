@@ -0,0 +1,19 @@
+CREATE TABLE workspace_resource_costs (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	date date NOT NULL,
+	resource_type character varying(192) NOT NULL,
+	resource_name character varying(64) NOT NULL,
+	currency text NOT NULL,
+	daily_cost integer NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_id, date, resource_type, resource_name)
+);
+
+CREATE INDEX workspace_resource_costs_organization_id_date_idx ON workspace_resource_costs USING btree (organization_id, date);
+
+COMMENT ON TABLE workspace_resource_costs IS 'The daily cost of each workspace resource, recorded once per day the resource existed.';
+
+COMMENT ON COLUMN workspace_resource_costs.currency IS 'The currency of the cost, taken from the "currency" metadata of the resource.';
+
+COMMENT ON COLUMN workspace_resource_costs.daily_cost IS 'The last known daily cost of the resource on the date.';
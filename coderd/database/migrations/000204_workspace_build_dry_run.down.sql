@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'workspace_build_dry_run' is
+-- left on provisioner_job_type.
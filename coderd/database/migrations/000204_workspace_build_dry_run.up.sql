@@ -0,0 +1 @@
+ALTER TYPE provisioner_job_type ADD VALUE IF NOT EXISTS 'workspace_build_dry_run';
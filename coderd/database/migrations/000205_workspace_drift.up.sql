@@ -0,0 +1,17 @@
+CREATE TABLE workspace_drift (
+	workspace_id uuid NOT NULL PRIMARY KEY REFERENCES workspaces (id) ON DELETE CASCADE,
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	build_id uuid REFERENCES workspace_builds (id) ON DELETE SET NULL,
+	checked_at timestamp with time zone,
+	drifted_resources text[] NOT NULL DEFAULT '{}'::text[],
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_drift IS 'The result of the latest drift check of each running workspace.';
+
+COMMENT ON COLUMN workspace_drift.job_id IS 'The dry-run job of the latest drift check. While it runs, the other columns hold the result of the check before it.';
+
+COMMENT ON COLUMN workspace_drift.build_id IS 'The workspace build whose state the last completed check planned against.';
+
+COMMENT ON COLUMN workspace_drift.drifted_resources IS 'The addresses of the resources that were changed outside of Coder.';
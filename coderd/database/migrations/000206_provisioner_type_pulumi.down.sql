@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'pulumi' is left on
+-- provisioner_type.
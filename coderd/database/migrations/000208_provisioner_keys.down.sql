@@ -0,0 +1 @@
+DROP TABLE IF EXISTS provisioner_keys;
@@ -0,0 +1,19 @@
+CREATE TABLE provisioner_keys (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	name varchar(64) NOT NULL,
+	hashed_secret bytea NOT NULL,
+	tags jsonb NOT NULL DEFAULT '{}'::jsonb,
+	rotated_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
+
+CREATE UNIQUE INDEX provisioner_keys_hashed_secret_idx ON provisioner_keys USING btree (hashed_secret);
+
+COMMENT ON TABLE provisioner_keys IS 'Keys that authenticate external provisioner daemons to an organization.';
+
+COMMENT ON COLUMN provisioner_keys.tags IS 'The tags of every daemon authenticated with the key. Daemons may not set other values for these tags.';
+
+COMMENT ON COLUMN provisioner_keys.rotated_at IS 'When the secret of the key was last replaced.';
@@ -0,0 +1,2 @@
+ALTER TABLE ONLY provisioner_jobs
+    DROP COLUMN tag_requirements;
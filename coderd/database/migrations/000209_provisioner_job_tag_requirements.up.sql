@@ -0,0 +1,3 @@
+ALTER TABLE ONLY provisioner_jobs
+    ADD COLUMN tag_requirements jsonb NOT NULL DEFAULT '[]'::jsonb;
+COMMENT ON COLUMN provisioner_jobs.tag_requirements IS 'Requirements on the tags of the provisioner daemons that may acquire the job, in addition to exactly matching tags. Each requirement has a key, an operator (in or not_in) and values, which may contain * wildcards.';
@@ -0,0 +1,21 @@
+ALTER TABLE templates ADD COLUMN max_build_duration bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_build_duration IS 'The maximum duration of workspace builds in nanoseconds. Builds running for longer are failed and aborted. Zero disables the limit.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
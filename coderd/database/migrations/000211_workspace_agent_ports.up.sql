@@ -0,0 +1,9 @@
+CREATE TABLE workspace_agent_ports (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	port integer NOT NULL,
+	process_name text NOT NULL,
+	discovered_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (agent_id, port)
+);
+
+COMMENT ON TABLE workspace_agent_ports IS 'TCP ports that workspace agents found listening in their workspace. They are exposed as discovered apps.';
@@ -0,0 +1,11 @@
+CREATE TABLE workspace_agent_gpus (
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	index integer NOT NULL,
+	vendor text NOT NULL,
+	model text NOT NULL,
+	memory_bytes bigint NOT NULL,
+	driver_version text NOT NULL,
+	PRIMARY KEY (agent_id, vendor, index)
+);
+
+COMMENT ON TABLE workspace_agent_gpus IS 'GPUs that workspace agents detected in their workspace on startup.';
@@ -0,0 +1,3 @@
+ALTER TABLE workspace_agent_scripts DROP COLUMN IF EXISTS phase;
+
+DROP TYPE IF EXISTS workspace_agent_script_phase;
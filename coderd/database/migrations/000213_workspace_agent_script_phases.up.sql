@@ -0,0 +1,8 @@
+CREATE TYPE workspace_agent_script_phase AS ENUM ('pre_start', 'start', 'post_start', 'pre_stop', 'stop');
+
+ALTER TABLE workspace_agent_scripts ADD COLUMN phase workspace_agent_script_phase NOT NULL DEFAULT 'start';
+
+-- Scripts that only run on stop were shutdown scripts.
+UPDATE workspace_agent_scripts SET phase = 'stop' WHERE run_on_stop AND NOT run_on_start;
+
+COMMENT ON COLUMN workspace_agent_scripts.phase IS 'The phase of the start or stop lifecycle the script runs in. The agent runs the phases in order, and the scripts of a phase in parallel.';
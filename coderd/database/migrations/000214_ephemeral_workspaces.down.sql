@@ -0,0 +1,3 @@
+ALTER TABLE workspaces DROP COLUMN IF EXISTS ephemeral_api_key_id;
+
+ALTER TABLE workspaces DROP COLUMN IF EXISTS ephemeral;
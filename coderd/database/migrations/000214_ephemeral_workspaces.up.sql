@@ -0,0 +1,7 @@
+ALTER TABLE workspaces ADD COLUMN ephemeral boolean NOT NULL DEFAULT false;
+
+ALTER TABLE workspaces ADD COLUMN ephemeral_api_key_id text;
+
+COMMENT ON COLUMN workspaces.ephemeral IS 'Ephemeral workspaces are deleted, rather than stopped, once they outlive their TTL or the API token that created them expires.';
+
+COMMENT ON COLUMN workspaces.ephemeral_api_key_id IS 'The API token that created the ephemeral workspace, if any. The workspace is deleted when the token expires or is deleted.';
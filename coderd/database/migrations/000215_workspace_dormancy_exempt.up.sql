@@ -0,0 +1,3 @@
+ALTER TABLE workspaces ADD COLUMN dormancy_exempt boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspaces.dormancy_exempt IS 'Prevents the workspace from becoming dormant or being deleted for inactivity.';
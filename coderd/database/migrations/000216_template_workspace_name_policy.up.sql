@@ -0,0 +1,23 @@
+ALTER TABLE templates ADD COLUMN workspace_name_pattern text NOT NULL DEFAULT '';
+ALTER TABLE templates ADD COLUMN workspace_name_description text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN templates.workspace_name_pattern IS 'A regular expression that the names of workspaces created from the template must match. Empty allows any valid name.';
+COMMENT ON COLUMN templates.workspace_name_description IS 'A human readable description of workspace_name_pattern shown when a name doesn''t match.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,31 @@
+CREATE TABLE organization_quotas (
+	organization_id uuid NOT NULL PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+	max_running_workspaces_per_user integer NOT NULL DEFAULT 0,
+	max_daily_cost integer NOT NULL DEFAULT 0,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON COLUMN organization_quotas.max_running_workspaces_per_user IS 'The maximum number of running workspaces of a user in the organization. Zero disables the limit.';
+COMMENT ON COLUMN organization_quotas.max_daily_cost IS 'The maximum total daily cost of the workspaces in the organization. Zero disables the limit.';
+
+ALTER TABLE templates ADD COLUMN max_running_workspaces integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_running_workspaces IS 'The maximum number of running workspaces of the template. Zero disables the limit.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,3 @@
+ALTER TABLE workspace_resources DROP COLUMN snapshot;
+
+DROP TABLE workspace_snapshots;
@@ -0,0 +1,17 @@
+CREATE TABLE workspace_snapshots (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+	created_at timestamp with time zone NOT NULL,
+	UNIQUE (workspace_id, name)
+);
+
+COMMENT ON TABLE workspace_snapshots IS 'Snapshots of the snapshot-able resources of workspaces.';
+
+COMMENT ON COLUMN workspace_snapshots.build_id IS 'The workspace build that took the snapshot.';
+
+ALTER TABLE workspace_resources ADD COLUMN snapshot boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_resources.snapshot IS 'Whether the resource is captured by snapshots of the workspace.';
@@ -0,0 +1 @@
+DROP TABLE workspace_proxy_health;
@@ -0,0 +1,16 @@
+CREATE TABLE workspace_proxy_health (
+	proxy_id uuid NOT NULL PRIMARY KEY REFERENCES workspace_proxies (id) ON DELETE CASCADE,
+	status text NOT NULL,
+	latency_ms integer NOT NULL,
+	derp_reachable boolean NOT NULL,
+	version_skew boolean NOT NULL,
+	errors text[] NOT NULL DEFAULT '{}',
+	warnings text[] NOT NULL DEFAULT '{}',
+	checked_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_proxy_health IS 'The latest health check of each workspace proxy, run periodically by coderd.';
+
+COMMENT ON COLUMN workspace_proxy_health.latency_ms IS 'The round trip time of the health check request from coderd to the proxy.';
+
+COMMENT ON COLUMN workspace_proxy_health.version_skew IS 'Whether the major or minor version of the proxy differs from coderd.';
@@ -0,0 +1,28 @@
+-- It's not possible to drop enum values from enum types, so the down migration
+-- leaves 'port_forward' in place.
+ALTER TYPE audit_action
+  ADD VALUE IF NOT EXISTS 'port_forward';
+
+ALTER TABLE templates ADD COLUMN port_forwarding_deny_by_default boolean NOT NULL DEFAULT false;
+ALTER TABLE templates ADD COLUMN port_forwarding_allowed_ports integer[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN templates.port_forwarding_deny_by_default IS 'Denies forwarding and sharing agent ports, except for those in port_forwarding_allowed_ports.';
+COMMENT ON COLUMN templates.port_forwarding_allowed_ports IS 'The agent ports that may be forwarded and shared when port_forwarding_deny_by_default is set.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
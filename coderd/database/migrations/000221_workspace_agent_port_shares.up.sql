@@ -0,0 +1,11 @@
+CREATE TABLE workspace_agent_port_shares (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	port integer NOT NULL,
+	share_level app_sharing_level NOT NULL,
+	PRIMARY KEY (workspace_id, agent_name, port)
+);
+
+COMMENT ON TABLE workspace_agent_port_shares IS 'Agent ports that workspace owners shared with other users. Port-based app URLs of shared ports use the share level instead of owner.';
+
+COMMENT ON COLUMN workspace_agent_port_shares.agent_name IS 'Shares reference agents by name, so they are kept across workspace builds.';
@@ -0,0 +1,27 @@
+-- The view will be rebuilt with the new columns
+DROP VIEW template_version_with_user;
+
+ALTER TABLE template_versions
+	ADD COLUMN git_commit_sha text NOT NULL DEFAULT '',
+	ADD COLUMN git_branch text NOT NULL DEFAULT '',
+	ADD COLUMN git_tag text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN template_versions.git_commit_sha IS 'SHA of the git commit the template version was pushed from, if the template directory is a git repository.';
+COMMENT ON COLUMN template_versions.git_branch IS 'Branch checked out when the template version was pushed, empty for a detached HEAD.';
+COMMENT ON COLUMN template_versions.git_tag IS 'Tag pointing at the git commit the template version was pushed from, if any.';
+
+CREATE VIEW
+	template_version_with_user
+AS
+SELECT
+	template_versions.*,
+	coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+	coalesce(visible_users.username, '') AS created_by_username
+FROM
+	template_versions
+		LEFT JOIN
+	visible_users
+	ON
+			template_versions.created_by = visible_users.id;
+
+COMMENT ON VIEW template_version_with_user IS 'Joins in the username + avatar url of the created by user.';
@@ -0,0 +1,3 @@
+DROP TABLE organization_template_variable_values;
+
+DROP TABLE template_variable_values;
@@ -0,0 +1,29 @@
+CREATE TABLE template_variable_values (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	value text NOT NULL,
+	value_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	sensitive boolean NOT NULL DEFAULT false,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_id, name)
+);
+
+COMMENT ON TABLE template_variable_values IS 'Values of template variables set on a template. They take precedence over the values the active version was pushed with, so they can be changed without pushing a new version.';
+
+COMMENT ON COLUMN template_variable_values.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
+
+CREATE TABLE organization_template_variable_values (
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	value text NOT NULL,
+	value_key_id text REFERENCES dbcrypt_keys (active_key_digest),
+	sensitive boolean NOT NULL DEFAULT false,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (organization_id, name)
+);
+
+COMMENT ON TABLE organization_template_variable_values IS 'Default values of template variables for all templates of an organization. They are used when a template version is pushed without a value for the variable.';
+
+COMMENT ON COLUMN organization_template_variable_values.value_key_id IS 'The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted';
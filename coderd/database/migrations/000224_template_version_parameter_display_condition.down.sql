@@ -0,0 +1 @@
+ALTER TABLE template_version_parameters DROP COLUMN display_condition;
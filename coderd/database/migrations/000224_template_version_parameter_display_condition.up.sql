@@ -0,0 +1,3 @@
+ALTER TABLE template_version_parameters ADD COLUMN display_condition text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN template_version_parameters.display_condition IS 'Expression over the values of other parameters. The parameter is only displayed and validated if it is met.';
@@ -0,0 +1,11 @@
+CREATE TABLE template_version_presets (
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	parameters jsonb NOT NULL DEFAULT '[]'::jsonb,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (template_version_id, name)
+);
+
+COMMENT ON TABLE template_version_presets IS 'Named sets of parameter values of a template version, which can be selected when creating a workspace.';
+
+COMMENT ON COLUMN template_version_presets.parameters IS 'Array of parameter names and values, validated against the parameters of the template version when the preset is created.';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_insert_workspace_build_parameters ON workspace_build_parameters;
+DROP FUNCTION IF EXISTS insert_workspace_parameter_change;
+DROP TABLE IF EXISTS workspace_parameter_changes;
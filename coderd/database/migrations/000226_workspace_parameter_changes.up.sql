@@ -0,0 +1,63 @@
+CREATE TABLE workspace_parameter_changes (
+	id uuid NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	workspace_build_id uuid NOT NULL REFERENCES workspace_builds (id) ON DELETE CASCADE,
+	build_number integer NOT NULL,
+	name text NOT NULL,
+	old_value text,
+	new_value text NOT NULL,
+	initiator_id uuid NOT NULL,
+	created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_parameter_changes IS 'History of the parameter values of workspaces. Rows are recorded when build parameters are inserted, and are never updated.';
+
+COMMENT ON COLUMN workspace_parameter_changes.old_value IS 'Value of the parameter in the previous build, or NULL if the parameter wasn''t set.';
+
+COMMENT ON COLUMN workspace_parameter_changes.initiator_id IS 'User who started the build that changed the value.';
+
+CREATE INDEX workspace_parameter_changes_workspace_id_build_number_idx ON workspace_parameter_changes USING btree (workspace_id, build_number DESC);
+
+CREATE FUNCTION insert_workspace_parameter_change() RETURNS trigger
+	LANGUAGE plpgsql
+	AS $$
+DECLARE
+	build workspace_builds%ROWTYPE;
+	previous_value text;
+BEGIN
+	SELECT * INTO build FROM workspace_builds WHERE id = NEW.workspace_build_id;
+
+	-- Compare with the value in the previous build of the workspace, if any.
+	SELECT
+		workspace_build_parameters.value INTO previous_value
+	FROM
+		workspace_build_parameters
+	WHERE
+		workspace_build_parameters.name = NEW.name
+		AND workspace_build_parameters.workspace_build_id = (
+			SELECT
+				workspace_builds.id
+			FROM
+				workspace_builds
+			WHERE
+				workspace_builds.workspace_id = build.workspace_id
+				AND workspace_builds.build_number < build.build_number
+			ORDER BY
+				workspace_builds.build_number DESC
+			LIMIT 1
+		);
+
+	IF previous_value IS DISTINCT FROM NEW.value THEN
+		INSERT INTO workspace_parameter_changes
+			(workspace_id, workspace_build_id, build_number, name, old_value, new_value, initiator_id, created_at)
+		VALUES
+			(build.workspace_id, build.id, build.build_number, NEW.name, previous_value, NEW.value, build.initiator_id, build.created_at);
+	END IF;
+	RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trigger_insert_workspace_build_parameters
+	AFTER INSERT ON workspace_build_parameters
+	FOR EACH ROW
+	EXECUTE FUNCTION insert_workspace_parameter_change();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS connection_logs;
+DROP TYPE IF EXISTS connection_type;
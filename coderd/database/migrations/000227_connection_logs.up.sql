@@ -0,0 +1,31 @@
+CREATE TYPE connection_type AS ENUM (
+	'ssh',
+	'reconnecting_pty',
+	'port_forwarding'
+);
+
+CREATE TABLE connection_logs (
+	id uuid NOT NULL PRIMARY KEY,
+	"time" timestamp with time zone NOT NULL,
+	organization_id uuid NOT NULL,
+	workspace_owner_id uuid NOT NULL,
+	workspace_id uuid NOT NULL,
+	workspace_name text NOT NULL,
+	agent_name text NOT NULL,
+	type connection_type NOT NULL,
+	port integer,
+	ip inet,
+	user_agent text NOT NULL,
+	user_id uuid NOT NULL,
+	api_key_id text NOT NULL
+);
+
+COMMENT ON TABLE connection_logs IS 'Connections opened by clients to workspace agents. Like audit logs, rows are never updated.';
+
+COMMENT ON COLUMN connection_logs.port IS 'Forwarded port of port_forwarding connections.';
+
+COMMENT ON COLUMN connection_logs.api_key_id IS 'ID of the API key that authenticated the connection.';
+
+CREATE INDEX idx_connection_logs_time_desc ON connection_logs USING btree ("time" DESC);
+
+CREATE INDEX idx_connection_logs_workspace_id ON connection_logs USING btree (workspace_id);
@@ -0,0 +1,47 @@
+ALTER TABLE templates ADD COLUMN session_recording_enabled boolean NOT NULL DEFAULT false;
+ALTER TABLE templates ADD COLUMN session_recording_retention bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.session_recording_enabled IS 'Records the web terminal sessions of workspaces of the template.';
+COMMENT ON COLUMN templates.session_recording_retention IS 'Duration in nanoseconds session recordings are kept for. Zero keeps them forever.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+CREATE TABLE session_recordings (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL,
+	template_id uuid NOT NULL,
+	workspace_id uuid NOT NULL,
+	agent_id uuid NOT NULL,
+	user_id uuid NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone NOT NULL,
+	object_key text NOT NULL,
+	size_bytes bigint NOT NULL,
+	expires_at timestamp with time zone
+);
+
+COMMENT ON TABLE session_recordings IS 'Web terminal sessions recorded in asciinema format. The recordings themselves are kept in the session recording store.';
+
+COMMENT ON COLUMN session_recordings.object_key IS 'Key of the recording in the session recording store.';
+
+COMMENT ON COLUMN session_recordings.expires_at IS 'Time after which the recording is deleted. NULL keeps the recording forever.';
+
+CREATE INDEX idx_session_recordings_workspace_id ON session_recordings USING btree (workspace_id);
+
+CREATE INDEX idx_session_recordings_expires_at ON session_recordings USING btree (expires_at) WHERE (expires_at IS NOT NULL);
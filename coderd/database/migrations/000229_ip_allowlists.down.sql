@@ -0,0 +1,3 @@
+ALTER TABLE api_keys DROP COLUMN ip_allowlist;
+
+ALTER TABLE users DROP COLUMN ip_allowlist;
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN ip_allowlist text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN users.ip_allowlist IS 'CIDR ranges the API keys of the user may be used from. If empty, the keys may be used from any address.';
+
+ALTER TABLE api_keys ADD COLUMN ip_allowlist text[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.ip_allowlist IS 'CIDR ranges the key may be used from, in addition to the allowlist of its user. If empty, the key is only limited by the allowlist of its user.';
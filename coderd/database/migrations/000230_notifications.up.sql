@@ -0,0 +1,30 @@
+CREATE TABLE notification_messages (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}'::jsonb,
+	attempts integer NOT NULL DEFAULT 0,
+	last_error text NOT NULL DEFAULT '',
+	created_at timestamp with time zone NOT NULL,
+	sent_at timestamp with time zone,
+	next_attempt_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE notification_messages IS 'Notifications sent to users, kept to report their delivery status.';
+COMMENT ON COLUMN notification_messages.template IS 'Template the notification is rendered with.';
+COMMENT ON COLUMN notification_messages.labels IS 'Values the template is rendered with.';
+COMMENT ON COLUMN notification_messages.next_attempt_at IS 'When the notification should next be sent. NULL once it has been sent or has run out of attempts.';
+
+CREATE INDEX notification_messages_user_id_created_at_idx ON notification_messages USING btree (user_id, created_at DESC);
+CREATE INDEX notification_messages_next_attempt_at_idx ON notification_messages USING btree (next_attempt_at) WHERE (next_attempt_at IS NOT NULL);
+
+CREATE TABLE notification_preferences (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template text NOT NULL,
+	disabled boolean NOT NULL DEFAULT false,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, template)
+);
+
+COMMENT ON TABLE notification_preferences IS 'Notification templates users have opted out of or back into. Templates without a preference are enabled.';
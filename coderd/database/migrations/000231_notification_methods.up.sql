@@ -0,0 +1,3 @@
+ALTER TABLE notification_messages ADD COLUMN method text NOT NULL DEFAULT 'smtp';
+
+COMMENT ON COLUMN notification_messages.method IS 'Method the notification is delivered with, such as smtp, slack or teams.';
@@ -0,0 +1,16 @@
+CREATE TABLE inbox_notifications (
+	id uuid NOT NULL,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	template text NOT NULL,
+	title text NOT NULL,
+	content text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	read_at timestamp with time zone,
+	PRIMARY KEY (id)
+);
+
+COMMENT ON TABLE inbox_notifications IS 'Notifications shown to users in the dashboard, regardless of how else they are delivered.';
+COMMENT ON COLUMN inbox_notifications.read_at IS 'When the user marked the notification as read. NULL while it is unread.';
+
+CREATE INDEX inbox_notifications_user_id_created_at_idx ON inbox_notifications USING btree (user_id, created_at DESC);
+CREATE INDEX inbox_notifications_user_id_unread_idx ON inbox_notifications USING btree (user_id) WHERE (read_at IS NULL);
@@ -0,0 +1,43 @@
+-- It's not possible to delete enum values, so 'retry' is left on build_reason.
+
+DROP VIEW template_with_users;
+
+ALTER TABLE templates DROP COLUMN build_retry_max_attempts;
+ALTER TABLE templates DROP COLUMN build_retry_backoff;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+DROP VIEW workspace_build_with_user;
+
+ALTER TABLE workspace_builds DROP COLUMN attempt;
+ALTER TABLE workspace_builds DROP COLUMN retry_at;
+
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
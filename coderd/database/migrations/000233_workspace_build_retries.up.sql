@@ -0,0 +1,49 @@
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'retry';
+
+ALTER TABLE templates ADD COLUMN build_retry_max_attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE templates ADD COLUMN build_retry_backoff bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.build_retry_max_attempts IS 'The maximum number of attempts of a workspace build that failed transiently, including the first attempt. Values below 2 disable retries.';
+COMMENT ON COLUMN templates.build_retry_backoff IS 'Duration in nanoseconds before the first retry of a failed workspace build. The delay doubles with every attempt.';
+
+DROP VIEW template_with_users;
+
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+ALTER TABLE workspace_builds ADD COLUMN attempt integer NOT NULL DEFAULT 1;
+ALTER TABLE workspace_builds ADD COLUMN retry_at timestamp with time zone;
+
+COMMENT ON COLUMN workspace_builds.attempt IS 'Attempt number of the build. Builds retrying a failed build have the attempt number of the failed build plus one.';
+COMMENT ON COLUMN workspace_builds.retry_at IS 'Time at which the failed build is retried. NULL if the build did not fail, or failed for good.';
+
+DROP VIEW workspace_build_with_user;
+
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
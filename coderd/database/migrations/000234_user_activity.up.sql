@@ -0,0 +1,14 @@
+CREATE TABLE user_activity (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	hour timestamp with time zone NOT NULL,
+	category text NOT NULL,
+	workspace_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+	count integer NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, hour, category, workspace_id)
+);
+
+COMMENT ON TABLE user_activity IS 'Number of API requests made by each user, by hour, API category and workspace.';
+COMMENT ON COLUMN user_activity.category IS 'The API category of the requests, e.g. workspaces or templates.';
+COMMENT ON COLUMN user_activity.workspace_id IS 'The workspace the requests were about, or the nil UUID if they were not about a workspace.';
+
+CREATE INDEX user_activity_hour_idx ON user_activity (hour);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS group_sync_runs;
+DROP TYPE IF EXISTS group_sync_trigger;
@@ -0,0 +1,25 @@
+CREATE TYPE group_sync_trigger AS ENUM (
+	'login',
+	'background'
+);
+
+CREATE TABLE group_sync_runs (
+	id uuid PRIMARY KEY,
+	trigger group_sync_trigger NOT NULL,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	started_at timestamp with time zone NOT NULL,
+	finished_at timestamp with time zone NOT NULL,
+	users_synced integer NOT NULL DEFAULT 0,
+	users_failed integer NOT NULL DEFAULT 0,
+	idp_groups text[] NOT NULL DEFAULT '{}',
+	groups text[] NOT NULL DEFAULT '{}',
+	error text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE group_sync_runs IS 'Records of group sync runs, kept for debugging group sync.';
+COMMENT ON COLUMN group_sync_runs.user_id IS 'The user whose groups were synced at login. Null for background runs, which sync every OIDC user, and for logins that failed before the user was known.';
+COMMENT ON COLUMN group_sync_runs.idp_groups IS 'The groups returned by the identity provider at login, before nested groups are resolved.';
+COMMENT ON COLUMN group_sync_runs.groups IS 'The Coder groups the user was synced to at login, after nested groups are resolved and the mapping, filter and prefix stripping are applied.';
+COMMENT ON COLUMN group_sync_runs.error IS 'The errors that occurred during the run, if any.';
+
+CREATE INDEX group_sync_runs_started_at_idx ON group_sync_runs (started_at DESC);
@@ -0,0 +1 @@
+-- It's not possible to delete enum values, so 'ldap' is left on login_type.
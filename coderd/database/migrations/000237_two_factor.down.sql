@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS two_factor_policies;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TYPE IF EXISTS two_factor_challenge_purpose;
+DROP TABLE IF EXISTS passkeys;
+-- It's not possible to delete enum values, so 'passkey' and
+-- 'two_factor_policy' are left on resource_type.
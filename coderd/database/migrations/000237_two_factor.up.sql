@@ -0,0 +1,59 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'passkey';
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'two_factor_policy';
+
+CREATE TABLE passkeys (
+	id uuid PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	credential_id bytea NOT NULL UNIQUE,
+	public_key bytea NOT NULL,
+	sign_count bigint NOT NULL DEFAULT 0,
+	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone
+);
+
+COMMENT ON TABLE passkeys IS 'WebAuthn credentials registered by users as a second factor for password logins.';
+COMMENT ON COLUMN passkeys.credential_id IS 'The credential ID chosen by the authenticator.';
+COMMENT ON COLUMN passkeys.public_key IS 'The COSE encoded public key of the credential.';
+COMMENT ON COLUMN passkeys.sign_count IS 'The signature counter last reported by the authenticator, used to detect cloned authenticators.';
+
+CREATE INDEX passkeys_user_id_idx ON passkeys (user_id);
+
+CREATE TYPE two_factor_challenge_purpose AS ENUM (
+	'register_passkey',
+	'login'
+);
+
+CREATE TABLE two_factor_challenges (
+	id uuid PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	purpose two_factor_challenge_purpose NOT NULL,
+	challenge bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE two_factor_challenges IS 'Pending WebAuthn challenges. Each challenge can only be answered once.';
+
+CREATE TABLE user_recovery_codes (
+	id uuid PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	hashed_code bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	used_at timestamp with time zone
+);
+
+COMMENT ON TABLE user_recovery_codes IS 'Single use codes that can be used instead of a passkey to complete a login.';
+
+CREATE INDEX user_recovery_codes_user_id_idx ON user_recovery_codes (user_id);
+
+CREATE TABLE two_factor_policies (
+	id uuid PRIMARY KEY,
+	role text NOT NULL UNIQUE,
+	enforce_after timestamp with time zone NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE two_factor_policies IS 'Site roles whose members must use a second factor for password logins.';
+COMMENT ON COLUMN two_factor_policies.enforce_after IS 'Password logins without a second factor are rejected after this time, which gives users time to register a passkey.';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_lockouts;
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,22 @@
+CREATE TABLE login_failures (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	ip_address inet NOT NULL,
+	failed_attempts integer NOT NULL DEFAULT 0,
+	last_failed_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, ip_address)
+);
+
+COMMENT ON TABLE login_failures IS 'Failed password logins per user and IP address, used to slow down brute-force attacks.';
+COMMENT ON COLUMN login_failures.failed_attempts IS 'The number of failed attempts since the count was last reset by a successful login or by the failures expiring.';
+
+CREATE INDEX login_failures_last_failed_at_idx ON login_failures (last_failed_at);
+
+CREATE TABLE user_lockouts (
+	user_id uuid PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	failed_attempts integer NOT NULL,
+	locked_at timestamp with time zone NOT NULL,
+	locked_until timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_lockouts IS 'Users that can''t log in with a password until locked_until because of too many failed attempts.';
+COMMENT ON COLUMN user_lockouts.failed_attempts IS 'The number of failed attempts from all IP addresses that caused the lockout.';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS workspace_agent_metadata_history;
@@ -0,0 +1,13 @@
+CREATE UNLOGGED TABLE workspace_agent_metadata_history (
+	workspace_agent_id uuid NOT NULL,
+	key varchar(127) NOT NULL,
+	value varchar(65535) NOT NULL DEFAULT '',
+	error varchar(65535) NOT NULL DEFAULT '',
+	collected_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (workspace_agent_id, key, collected_at),
+	FOREIGN KEY (workspace_agent_id, key) REFERENCES workspace_agent_metadata (workspace_agent_id, key) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS 'Recent values of workspace agent metadata. Only a limited number of values is kept per metadatum, older ones are deleted as new ones are recorded.';
+
+CREATE INDEX workspace_agent_metadata_history_collected_at_idx ON workspace_agent_metadata_history (collected_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS workspace_events;
+DROP TYPE IF EXISTS workspace_event_type;
@@ -0,0 +1,26 @@
+CREATE TYPE workspace_event_type AS ENUM (
+	'agent_connected',
+	'agent_disconnected',
+	'ttl_changed',
+	'app_health_changed'
+);
+
+CREATE TABLE workspace_events (
+	id uuid PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL,
+	type workspace_event_type NOT NULL,
+	user_id uuid,
+	agent_id uuid,
+	app_id uuid,
+	old_value text NOT NULL DEFAULT '',
+	new_value text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE workspace_events IS 'Events of a workspace that are not recorded elsewhere. Together with the builds of the workspace they make up its timeline.';
+COMMENT ON COLUMN workspace_events.user_id IS 'The user who caused the event. Null for events caused by the workspace itself.';
+COMMENT ON COLUMN workspace_events.old_value IS 'The value before the change: the TTL in milliseconds for ttl_changed, empty if autostop was disabled, and the app health for app_health_changed.';
+COMMENT ON COLUMN workspace_events.new_value IS 'The value after the change, in the same format as old_value.';
+
+CREATE INDEX workspace_events_workspace_id_created_at_idx ON workspace_events (workspace_id, created_at DESC);
+CREATE INDEX workspace_events_created_at_idx ON workspace_events (created_at);
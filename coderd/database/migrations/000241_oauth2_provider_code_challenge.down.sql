@@ -0,0 +1,3 @@
+ALTER TABLE oauth2_provider_app_codes
+	DROP COLUMN nonce,
+	DROP COLUMN code_challenge;
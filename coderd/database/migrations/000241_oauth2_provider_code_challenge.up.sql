@@ -0,0 +1,6 @@
+ALTER TABLE oauth2_provider_app_codes
+	ADD COLUMN nonce text NOT NULL DEFAULT '',
+	ADD COLUMN code_challenge text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN oauth2_provider_app_codes.nonce IS 'The nonce the app sent with the authorization request. It is returned in the ID token.';
+COMMENT ON COLUMN oauth2_provider_app_codes.code_challenge IS 'The S256 PKCE code challenge the app sent with the authorization request. Empty if the app did not use PKCE.';
@@ -0,0 +1,4 @@
+ALTER TABLE webhooks
+	DROP COLUMN secret_key_id;
+-- It's not possible to delete enum values, so 'webhook' is left on
+-- resource_type.
@@ -0,0 +1,6 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'webhook';
+
+ALTER TABLE webhooks
+	ADD COLUMN secret_key_id text REFERENCES dbcrypt_keys (active_key_digest);
+
+COMMENT ON COLUMN webhooks.secret_key_id IS 'The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted';
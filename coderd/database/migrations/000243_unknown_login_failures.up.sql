@@ -0,0 +1,13 @@
+CREATE TABLE unknown_login_failures (
+	login_hash bytea NOT NULL,
+	ip_address inet NOT NULL,
+	failed_attempts integer NOT NULL DEFAULT 0,
+	last_failed_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (login_hash, ip_address)
+);
+
+COMMENT ON TABLE unknown_login_failures IS 'Failed password logins for emails and usernames that don''t belong to a user, throttled like those of existing users so that the responses don''t reveal which users exist.';
+COMMENT ON COLUMN unknown_login_failures.login_hash IS 'SHA256 of the lowercased email or username. The login itself isn''t stored, it might be a mistyped password.';
+COMMENT ON COLUMN unknown_login_failures.failed_attempts IS 'The number of failed attempts since the count was last reset by the failures expiring.';
+
+CREATE INDEX unknown_login_failures_last_failed_at_idx ON unknown_login_failures (last_failed_at);
@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'organization_member' is left
+-- on resource_type.
@@ -0,0 +1,2 @@
+-- It's not possible to delete enum values, so 'enroll_passkey' is left on
+-- two_factor_challenge_purpose. Deleted recovery codes can't be restored.
@@ -0,0 +1,6 @@
+ALTER TYPE two_factor_challenge_purpose ADD VALUE IF NOT EXISTS 'enroll_passkey';
+
+-- Recovery codes used to be stored as unsalted SHA-256 hashes. They are now
+-- hashed like passwords, so the old codes can't be checked anymore and users
+-- have to generate new ones.
+DELETE FROM user_recovery_codes;
@@ -0,0 +1,7 @@
+INSERT INTO workspace_favorites
+	(user_id, workspace_id, created_at)
+VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+	'2023-12-01 10:00:00+00'
+);
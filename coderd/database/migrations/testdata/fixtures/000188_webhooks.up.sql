@@ -0,0 +1,25 @@
+INSERT INTO webhooks
+	(id, url, secret, events, created_at, updated_at)
+VALUES (
+	'7b4e8d2a-1f3c-4a5e-9d6b-2c8f0e1a3b5d',
+	'https://example.com/coder-events',
+	'secret',
+	'{workspace_build.succeeded,workspace_build.failed}',
+	'2023-06-15 10:23:54+00',
+	'2023-06-15 10:23:54+00'
+);
+
+INSERT INTO webhook_deliveries
+	(id, webhook_id, event, payload, attempts, status_code, last_error, created_at, delivered_at, next_attempt_at)
+VALUES (
+	'c2f1a9e4-6d3b-4f8a-b7c5-1e9d0a2b4c6f',
+	'7b4e8d2a-1f3c-4a5e-9d6b-2c8f0e1a3b5d',
+	'workspace_build.failed',
+	'{}',
+	5,
+	500,
+	'unexpected status code 500',
+	'2023-06-15 10:25:33+00',
+	NULL,
+	NULL
+);
@@ -0,0 +1,6 @@
+INSERT INTO user_scim_external_ids
+	(user_id, external_id)
+VALUES (
+	'30095c71-380b-457a-8995-97b8ee6e5307',
+	'00u1a2b3c4d5e6f7g8h9'
+);
@@ -0,0 +1,7 @@
+INSERT INTO workspace_agent_previous_auth_tokens
+	(agent_id, auth_token, expires_at)
+VALUES (
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	'a9b4c1e2-7d7f-4b1a-9a43-3a6f0c8d2e51',
+	'2022-11-02 13:18:45.046432+02'
+) ON CONFLICT DO NOTHING;
@@ -0,0 +1,7 @@
+INSERT INTO custom_roles
+	(name, display_name, site_permissions)
+VALUES (
+	'template-viewer',
+	'Template Viewer',
+	'[{"negate": false, "resource_type": "template", "action": "read"}]'
+);
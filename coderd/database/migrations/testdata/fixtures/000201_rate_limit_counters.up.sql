@@ -0,0 +1,4 @@
+INSERT INTO rate_limit_counters
+	(key, window_start, count)
+VALUES
+	('127.0.0.1:/api/v2/users/login', date_trunc('minute', now()), 3);
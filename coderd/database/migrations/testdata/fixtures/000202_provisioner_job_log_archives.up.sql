@@ -0,0 +1,5 @@
+INSERT INTO provisioner_job_log_archives
+	(job_id, created_at, log_count, uncompressed_size, compressed_logs)
+VALUES
+	('424a58cb-61d6-4627-9907-613c396c4a38', now(), 0, 2, decode('1f8b08000000000002038b8e050029bb4c0d02000000', 'hex'))
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO workspace_resource_costs
+	(workspace_id, organization_id, date, resource_type, resource_name, currency, daily_cost, updated_at)
+VALUES
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '2024-03-01', 'aws_instance', 'dev', 'USD', 10, '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
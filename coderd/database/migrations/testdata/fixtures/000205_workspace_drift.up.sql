@@ -0,0 +1,5 @@
+INSERT INTO workspace_drift
+	(workspace_id, job_id, build_id, checked_at, drifted_resources, created_at, updated_at)
+VALUES
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', '52a90399-a53d-4644-be3c-47ee18a5716e', 'a8c0b8c5-c9a8-4f33-93a4-8142e6858244', '2024-03-01 10:00:00+00', '{docker_container.workspace}', '2024-03-01 09:55:00+00', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
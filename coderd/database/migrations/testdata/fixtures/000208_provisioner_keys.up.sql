@@ -0,0 +1,5 @@
+INSERT INTO provisioner_keys
+	(id, created_at, organization_id, name, hashed_secret, tags)
+VALUES
+	('e2b5d6a5-0c5d-4d0a-a8a3-0f6a0c9f3b8e', '2024-03-01 10:00:00+00', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 'isolated', '\xdeadbeef'::bytea, '{"network": "isolated"}'::jsonb)
+ON CONFLICT DO NOTHING;
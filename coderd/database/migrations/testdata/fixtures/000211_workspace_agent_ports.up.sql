@@ -0,0 +1,8 @@
+INSERT INTO workspace_agent_ports
+	(agent_id, port, process_name, discovered_at)
+VALUES (
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	3000,
+	'node',
+	'2022-11-02 13:18:45.046432+02'
+) ON CONFLICT DO NOTHING;
@@ -0,0 +1,10 @@
+INSERT INTO workspace_agent_gpus
+	(agent_id, index, vendor, model, memory_bytes, driver_version)
+VALUES (
+	'45e89705-e09d-4850-bcec-f9a937f5d78d',
+	0,
+	'nvidia',
+	'NVIDIA A100-SXM4-40GB',
+	42949672960,
+	'535.104.05'
+) ON CONFLICT DO NOTHING;
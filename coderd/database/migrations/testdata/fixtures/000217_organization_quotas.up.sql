@@ -0,0 +1,5 @@
+INSERT INTO organization_quotas
+	(organization_id, max_running_workspaces_per_user, max_daily_cost, updated_at)
+VALUES
+	('bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 3, 500, '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO workspace_snapshots
+	(id, workspace_id, build_id, name, created_by, created_at)
+VALUES
+	('6d3c7f1e-2b59-4c8a-9e0f-4a1b8d2c5e37', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'a8c0b8c5-c9a8-4f33-93a4-8142e6858244', 'before-upgrade', '30095c71-380b-457a-8995-97b8ee6e5307', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
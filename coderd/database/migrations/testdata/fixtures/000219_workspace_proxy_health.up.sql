@@ -0,0 +1,5 @@
+INSERT INTO workspace_proxy_health
+	(proxy_id, status, latency_ms, derp_reachable, version_skew, errors, warnings, checked_at)
+VALUES
+	('cf8ede8c-ff47-441f-a738-d92e4e34a657', 'ok', 42, true, false, '{}', '{}', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
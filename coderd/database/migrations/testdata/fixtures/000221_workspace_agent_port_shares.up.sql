@@ -0,0 +1,5 @@
+INSERT INTO workspace_agent_port_shares
+	(workspace_id, agent_name, port, share_level)
+VALUES
+	('3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'main', 8080, 'public')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+INSERT INTO template_variable_values
+	(template_id, name, value, sensitive, created_at, updated_at)
+VALUES
+	('4cc1f466-f326-477e-8762-9d0c6781fc56', 'region', 'eu-west-1', false, '2024-03-01 10:00:00+00', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO organization_template_variable_values
+	(organization_id, name, value, sensitive, created_at, updated_at)
+VALUES
+	('bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', 'registry', 'registry.example.com', false, '2024-03-01 10:00:00+00', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
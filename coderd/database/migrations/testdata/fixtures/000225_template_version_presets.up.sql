@@ -0,0 +1,5 @@
+INSERT INTO template_version_presets
+	(template_version_id, name, parameters, created_at)
+VALUES
+	('4e681a60-83da-42c2-902e-6535376ebb77', 'large', '[{"name": "cpu", "value": "8"}]', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO workspace_parameter_changes
+	(id, workspace_id, workspace_build_id, build_number, name, old_value, new_value, initiator_id, created_at)
+VALUES
+	('8d3a5c2e-6f0b-4c1d-9e7a-2b4f6c8d0e1f', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'a8c0b8c5-c9a8-4f33-93a4-8142e6858244', 1, 'cpu', NULL, '4', '30095c71-380b-457a-8995-97b8ee6e5307', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO connection_logs
+	(id, "time", organization_id, workspace_owner_id, workspace_id, workspace_name, agent_name, type, port, ip, user_agent, user_id, api_key_id)
+VALUES
+	('b6a0e1c4-2d7f-4e3a-9c5b-1f8d2a6e4c90', '2024-03-01 10:00:00+00', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '30095c71-380b-457a-8995-97b8ee6e5307', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', 'workspace-1', 'main', 'ssh', NULL, '127.0.0.1', 'coder/v2.10.0', '30095c71-380b-457a-8995-97b8ee6e5307', 'peuLZhMXt4')
+ON CONFLICT DO NOTHING;
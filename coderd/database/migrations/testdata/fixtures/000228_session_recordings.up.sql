@@ -0,0 +1,5 @@
+INSERT INTO session_recordings
+	(id, organization_id, template_id, workspace_id, agent_id, user_id, started_at, ended_at, object_key, size_bytes, expires_at)
+VALUES
+	('5e2c7a1f-9b84-4d36-a0c2-8f1e3b6d7a25', 'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1', '4cc1f466-f326-477e-8762-9d0c6781fc56', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', '7a1ce5f8-8d00-431c-ad1b-97a846512804', '30095c71-380b-457a-8995-97b8ee6e5307', '2024-03-01 10:00:00+00', '2024-03-01 10:30:00+00', '3a9a1feb-e89d-457c-9d53-ac751b198ebe/5e2c7a1f-9b84-4d36-a0c2-8f1e3b6d7a25.cast', 4096, NULL)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+INSERT INTO notification_messages
+	(id, user_id, template, labels, attempts, last_error, created_at, sent_at, next_attempt_at)
+VALUES
+	('5d1c8e2f-3a7b-4c9d-8e6f-0a1b2c3d4e5f', '30095c71-380b-457a-8995-97b8ee6e5307', 'workspace_build_failed', '{"workspace_name": "workspace-1"}', 1, '', '2024-03-01 10:00:00+00', '2024-03-01 10:00:02+00', NULL)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO notification_preferences
+	(user_id, template, disabled, updated_at)
+VALUES
+	('30095c71-380b-457a-8995-97b8ee6e5307', 'user_account_created', true, '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+INSERT INTO inbox_notifications
+	(id, user_id, template, title, content, created_at, read_at)
+VALUES
+	('8f2b6c1d-4e3a-4b5c-9d7e-1a2b3c4d5e6f', '30095c71-380b-457a-8995-97b8ee6e5307', 'workspace_build_failed', 'Workspace "workspace-1" failed to start', 'Build #1 of your workspace "workspace-1" failed to start.', '2024-03-01 10:00:00+00', NULL)
+ON CONFLICT DO NOTHING;
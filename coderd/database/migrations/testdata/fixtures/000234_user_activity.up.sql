@@ -0,0 +1,5 @@
+INSERT INTO user_activity
+	(user_id, hour, category, workspace_id, count)
+VALUES
+	('30095c71-380b-457a-8995-97b8ee6e5307', '2024-03-01 10:00:00+00', 'workspaces', '00000000-0000-0000-0000-000000000000', 3)
+ON CONFLICT DO NOTHING;
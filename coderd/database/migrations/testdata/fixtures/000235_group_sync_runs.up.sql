@@ -0,0 +1,6 @@
+INSERT INTO group_sync_runs
+	(id, trigger, user_id, started_at, finished_at, users_synced, users_failed, idp_groups, groups, error)
+VALUES
+	('7a1b3c52-90c9-4b7e-9e5c-4f0c6b0d8a11', 'login', '30095c71-380b-457a-8995-97b8ee6e5307', '2024-03-01 10:00:00+00', '2024-03-01 10:00:01+00', 1, 0, '{engineering}', '{engineering,everyone}', ''),
+	('0d8f2e6a-3b5c-4c1e-8f7a-2a9b4c6d1e23', 'background', NULL, '2024-03-01 11:00:00+00', '2024-03-01 11:00:05+00', 12, 1, '{}', '{}', 'user 30095c71-380b-457a-8995-97b8ee6e5307: lookup groups: not found')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,23 @@
+INSERT INTO passkeys
+	(id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at)
+VALUES
+	('4c3f9b1e-6d2a-4e8b-9a17-3b5d2c8f0e41', '30095c71-380b-457a-8995-97b8ee6e5307', 'YubiKey', '\x0102030405060708', '\xa5010203262001215820', 3, '2024-03-01 10:00:00+00', '2024-03-02 09:00:00+00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO two_factor_challenges
+	(id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	('8e2d4a6c-1b3f-4d5e-8a9b-0c1d2e3f4a5b', '30095c71-380b-457a-8995-97b8ee6e5307', 'login', '\x00112233445566778899aabbccddeeff', '2024-03-01 10:00:00+00', '2024-03-01 10:05:00+00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_recovery_codes
+	(id, user_id, hashed_code, created_at, used_at)
+VALUES
+	('b7c1e3d5-2f4a-4b6c-9d8e-1a2b3c4d5e6f', '30095c71-380b-457a-8995-97b8ee6e5307', '\xdeadbeef', '2024-03-01 10:00:00+00', NULL)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO two_factor_policies
+	(id, role, enforce_after, created_at, updated_at)
+VALUES
+	('1f2e3d4c-5b6a-4798-8a7b-6c5d4e3f2a1b', 'owner', '2024-04-01 00:00:00+00', '2024-03-01 10:00:00+00', '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+INSERT INTO login_failures
+	(user_id, ip_address, failed_attempts, last_failed_at)
+VALUES
+	('30095c71-380b-457a-8995-97b8ee6e5307', '192.168.0.10', 4, '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_lockouts
+	(user_id, failed_attempts, locked_at, locked_until)
+VALUES
+	('30095c71-380b-457a-8995-97b8ee6e5307', 10, '2024-03-01 10:00:00+00', '2024-03-01 10:30:00+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+INSERT INTO workspace_agent_metadata_history
+	(workspace_agent_id, key, value, error, collected_at)
+VALUES
+	('45e89705-e09d-4850-bcec-f9a937f5d78d', 'ahem', '0.42', '', '2024-03-01 10:00:00+00'),
+	('45e89705-e09d-4850-bcec-f9a937f5d78d', 'ahem', '0.57', '', '2024-03-01 10:00:10+00')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+INSERT INTO workspace_events
+	(id, workspace_id, created_at, type, user_id, agent_id, app_id, old_value, new_value)
+VALUES
+	('5b2f8c1e-7d4a-4e0b-9c3f-1a6e2d8b4f70', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', '2024-03-01 10:00:00+00', 'agent_connected', NULL, '45e89705-e09d-4850-bcec-f9a937f5d78d', NULL, '', ''),
+	('c4a7e9d2-3f1b-4a8c-b6e5-0d2f9a7c1e38', '3a9a1feb-e89d-457c-9d53-ac751b198ebe', '2024-03-01 10:05:00+00', 'ttl_changed', '30095c71-380b-457a-8995-97b8ee6e5307', NULL, NULL, '28800000', '14400000')
+ON CONFLICT DO NOTHING;
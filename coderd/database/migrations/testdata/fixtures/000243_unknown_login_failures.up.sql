@@ -0,0 +1,5 @@
+INSERT INTO unknown_login_failures
+	(login_hash, ip_address, failed_attempts, last_failed_at)
+VALUES
+	(sha256('nobody@coder.com'), '192.168.0.10', 4, '2024-03-01 10:00:00+00')
+ON CONFLICT DO NOTHING;
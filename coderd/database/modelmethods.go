@@ -1,11 +1,14 @@
 package database
 
 import (
+	"net/netip"
 	"sort"
 	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -85,6 +88,20 @@ func (g Group) Auditable(users []User) AuditableGroup {
 	}
 }
 
+// AuditableOrganizationMember is an organization membership with the name of
+// the user, which is the target of its audit logs.
+type AuditableOrganizationMember struct {
+	OrganizationMember
+	Username string `json:"username"`
+}
+
+func (m OrganizationMember) Auditable(username string) AuditableOrganizationMember {
+	return AuditableOrganizationMember{
+		OrganizationMember: m,
+		Username:           username,
+	}
+}
+
 const EveryoneGroup = "Everyone"
 
 func (s APIKeyScope) ToRBAC() rbac.ScopeName {
@@ -103,27 +120,133 @@ func (k APIKey) RBACObject() rbac.Object {
 		WithOwner(k.UserID.String())
 }
 
+// RBACScope returns the scope that limits what the key can do. Keys with
+// fine-grained scopes are limited to the union of those scopes, and to their
+// allowed workspaces if any are set. The templates of the allowed workspaces
+// must be passed in so the workspaces can still be rendered.
+func (k APIKey) RBACScope(templateIDs ...uuid.UUID) (rbac.ExpandableScope, error) {
+	if len(k.Scopes) == 0 {
+		return rbac.ScopeName(k.Scope), nil
+	}
+
+	names := make([]rbac.ScopeName, 0, len(k.Scopes))
+	for _, name := range k.Scopes {
+		names = append(names, rbac.ScopeName(name))
+	}
+	var allowList []string
+	if len(k.AllowedWorkspaceIDs) > 0 {
+		// The key owner is always allowed so the key can read its own user.
+		allowList = append(allowList, k.UserID.String())
+		for _, id := range k.AllowedWorkspaceIDs {
+			allowList = append(allowList, id.String())
+		}
+		for _, id := range templateIDs {
+			if !slices.Contains(allowList, id.String()) {
+				allowList = append(allowList, id.String())
+			}
+		}
+	}
+	return rbac.CombinedScope(rbac.CombinedScopeParams{
+		Scopes:      names,
+		AllowIDList: allowList,
+	})
+}
+
+// AllowsIP returns whether the key may be used from addr. It does not check the
+// allowlist of the key's user.
+func (k APIKey) AllowsIP(addr netip.Addr) bool {
+	return IPAllowlistContains(k.IPAllowlist, addr)
+}
+
+// AllowsIP returns whether the API keys of the user may be used from addr.
+func (r GetAuthorizationUserRolesRow) AllowsIP(addr netip.Addr) bool {
+	return IPAllowlistContains(r.IPAllowlist, addr)
+}
+
+// IPAllowlistContains returns whether addr is in one of the CIDR ranges of an
+// allowlist. An empty allowlist contains every address. Entries that don't
+// parse are ignored, so a corrupt allowlist denies rather than allows.
+func IPAllowlistContains(allowlist []string, addr netip.Addr) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, entry := range allowlist {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			continue
+		}
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c OAuth2ProviderAppCode) RBACObject() rbac.Object {
+	return rbac.ResourceOAuth2ProviderAppCodeToken.WithID(c.ID).
+		WithOwner(c.UserID.String())
+}
+
+// RBACObject returns the template as an RBAC object. Private templates are
+// only visible to template admins, so only the admin entries of their ACL
+// apply.
 func (t Template) RBACObject() rbac.Object {
-	return rbac.ResourceTemplate.WithID(t.ID).
-		InOrg(t.OrganizationID).
-		WithACLUserList(t.UserACL).
-		WithGroupACL(t.GroupACL)
+	return templateRBACObject(t.ID, t.OrganizationID, t.UserACL, t.GroupACL, t.Visibility == TemplateVisibilityPrivate)
+}
+
+// ListRBACObject is used when listing templates. Unlisted templates can be
+// used by anyone on their ACL, but are only listed for template admins.
+func (t Template) ListRBACObject() rbac.Object {
+	return templateRBACObject(t.ID, t.OrganizationID, t.UserACL, t.GroupACL, t.Visibility != TemplateVisibilityPublic)
 }
 
 func (t GetFileTemplatesRow) RBACObject() rbac.Object {
-	return rbac.ResourceTemplate.WithID(t.TemplateID).
-		InOrg(t.TemplateOrganizationID).
-		WithACLUserList(t.UserACL).
-		WithGroupACL(t.GroupACL)
+	return templateRBACObject(t.TemplateID, t.TemplateOrganizationID, t.UserACL, t.GroupACL, t.Visibility == TemplateVisibilityPrivate)
+}
+
+// templateRBACObject must be kept in sync with the template variable
+// converters in regosql, which apply the same ACL filtering in SQL.
+func templateRBACObject(id, orgID uuid.UUID, userACL, groupACL TemplateACL, hidden bool) rbac.Object {
+	if hidden {
+		userACL = templateAdminACL(userACL)
+		groupACL = templateAdminACL(groupACL)
+	}
+	return rbac.ResourceTemplate.WithID(id).
+		InOrg(orgID).
+		WithACLUserList(userACL).
+		WithGroupACL(groupACL)
+}
+
+// templateAdminACL returns the entries of a template ACL that grant every
+// action.
+func templateAdminACL(acl TemplateACL) TemplateACL {
+	admins := TemplateACL{}
+	for id, actions := range acl {
+		if slices.Contains(actions, rbac.WildcardSymbol) {
+			admins[id] = actions
+		}
+	}
+	return admins
 }
 
 func (t Template) DeepCopy() Template {
 	cpy := t
 	cpy.UserACL = maps.Clone(t.UserACL)
 	cpy.GroupACL = maps.Clone(t.GroupACL)
+	cpy.PortForwardingAllowedPorts = slices.Clone(t.PortForwardingAllowedPorts)
 	return cpy
 }
 
+// AllowsPortForwarding returns whether the port of an agent in a workspace of
+// the template may be forwarded or shared.
+func (t Template) AllowsPortForwarding(port uint16) bool {
+	if !t.PortForwardingDenyByDefault {
+		return true
+	}
+	return slices.Contains(t.PortForwardingAllowedPorts, int32(port))
+}
+
 // AutostartAllowedDays returns the inverse of 'AutostartBlockDaysOfWeek'.
 // It is more useful to have the days that are allowed to autostart from a UX
 // POV. The database prefers the 0 value being 'all days allowed'.
@@ -155,7 +278,8 @@ func (w GetWorkspaceByAgentIDRow) RBACObject() rbac.Object {
 func (w Workspace) RBACObject() rbac.Object {
 	return rbac.ResourceWorkspace.WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL)
 }
 
 func (w Workspace) ExecutionRBAC() rbac.Object {
@@ -167,7 +291,8 @@ func (w Workspace) ExecutionRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceExecution.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL)
 }
 
 func (w Workspace) ApplicationConnectRBAC() rbac.Object {
@@ -179,7 +304,8 @@ func (w Workspace) ApplicationConnectRBAC() rbac.Object {
 	return rbac.ResourceWorkspaceApplicationConnect.
 		WithID(w.ID).
 		InOrg(w.OrganizationID).
-		WithOwner(w.OwnerID.String())
+		WithOwner(w.OwnerID.String()).
+		WithACLUserList(w.UserACL)
 }
 
 func (w Workspace) WorkspaceBuildRBAC(transition WorkspaceTransition) rbac.Object {
@@ -212,6 +338,13 @@ func (m OrganizationMember) RBACObject() rbac.Object {
 		WithOwner(m.UserID.String())
 }
 
+func (m GetOrganizationMembersRow) RBACObject() rbac.Object {
+	return rbac.ResourceOrganizationMember.
+		WithID(m.UserID).
+		InOrg(m.OrganizationID).
+		WithOwner(m.UserID.String())
+}
+
 func (m GetOrganizationIDsByMemberIDsRow) RBACObject() rbac.Object {
 	// TODO: This feels incorrect as we are really returning a list of orgmembers.
 	// This return type should be refactored to return a list of orgmembers, not this
@@ -226,7 +359,17 @@ func (o Organization) RBACObject() rbac.Object {
 }
 
 func (p ProvisionerDaemon) RBACObject() rbac.Object {
-	return rbac.ResourceProvisionerDaemon.WithID(p.ID)
+	obj := rbac.ResourceProvisionerDaemon.WithID(p.ID)
+	if p.OrganizationID.Valid {
+		obj = obj.InOrg(p.OrganizationID.UUID)
+	}
+	return obj
+}
+
+// RBACObject returns the key as a provisioner daemon of its organization,
+// since it permits creating daemons there.
+func (k ProvisionerKey) RBACObject() rbac.Object {
+	return rbac.ResourceProvisionerDaemon.WithID(k.ID).InOrg(k.OrganizationID)
 }
 
 func (w WorkspaceProxy) RBACObject() rbac.Object {
@@ -263,6 +406,14 @@ func (u GitSSHKey) RBACObject() rbac.Object {
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
 }
 
+func (p Passkey) RBACObject() rbac.Object {
+	return rbac.ResourceUserData.WithID(p.UserID).WithOwner(p.UserID.String())
+}
+
+func (l UserLockout) RBACObject() rbac.Object {
+	return rbac.ResourceUserObject(l.UserID)
+}
+
 func (u ExternalAuthLink) RBACObject() rbac.Object {
 	// I assume UserData is ok?
 	return rbac.ResourceUserData.WithID(u.UserID).WithOwner(u.UserID.String())
@@ -349,6 +500,7 @@ func ConvertUserRows(rows []GetUsersRow) []User {
 			Deleted:         r.Deleted,
 			LastSeenAt:      r.LastSeenAt,
 			ThemePreference: r.ThemePreference,
+			IPAllowlist:     r.IPAllowlist,
 		}
 	}
 
@@ -373,6 +525,11 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			DormantAt:         r.DormantAt,
 			DeletingAt:        r.DeletingAt,
 			AutomaticUpdates:  r.AutomaticUpdates,
+			MaintenanceOptOut: r.MaintenanceOptOut,
+			UserACL:           r.UserACL,
+			Ephemeral:         r.Ephemeral,
+			EphemeralAPIKeyID: r.EphemeralAPIKeyID,
+			DormancyExempt:    r.DormancyExempt,
 		}
 	}
 
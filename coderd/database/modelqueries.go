@@ -33,8 +33,14 @@ type templateQuerier interface {
 }
 
 func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]Template, error) {
+	// Templates fetched by ID are not being listed, so unlisted templates
+	// are included.
+	converter := regosql.TemplateListConverter()
+	if len(arg.IDs) > 0 {
+		converter = regosql.TemplateConverter()
+	}
 	authorizedFilter, err := prepared.CompileToSQL(ctx, regosql.ConvertConfig{
-		VariableConverter: regosql.TemplateConverter(),
+		VariableConverter: converter,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("compile authorized filter: %w", err)
@@ -90,6 +96,19 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.RequireActiveVersion,
 			&i.Deprecated,
 			&i.UseMaxTtl,
+			&i.MaintenanceWindowSchedule,
+			&i.MaintenanceWindowDuration,
+			&i.Visibility,
+			&i.MaxBuildDuration,
+			&i.WorkspaceNamePattern,
+			&i.WorkspaceNameDescription,
+			&i.MaxRunningWorkspaces,
+			&i.PortForwardingDenyByDefault,
+			pq.Array(&i.PortForwardingAllowedPorts),
+			&i.SessionRecordingEnabled,
+			&i.SessionRecordingRetention,
+			&i.BuildRetryMaxAttempts,
+			&i.BuildRetryBackoff,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -198,7 +217,9 @@ type workspaceQuerier interface {
 // This code is copied from `GetWorkspaces` and adds the authorized filter WHERE
 // clause.
 func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspacesParams, prepared rbac.PreparedAuthorized) ([]GetWorkspacesRow, error) {
-	authorizedFilter, err := prepared.CompileToSQL(ctx, rbac.ConfigWithoutACL())
+	authorizedFilter, err := prepared.CompileToSQL(ctx, regosql.ConvertConfig{
+		VariableConverter: regosql.WorkspaceConverter(),
+	})
 	if err != nil {
 		return nil, xerrors.Errorf("compile authorized filter: %w", err)
 	}
@@ -225,6 +246,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.Dormant,
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
+		arg.Favorite,
+		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -250,6 +273,11 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.DormantAt,
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
+			&i.MaintenanceOptOut,
+			&i.UserACL,
+			&i.Ephemeral,
+			&i.EphemeralAPIKeyID,
+			&i.DormancyExempt,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -319,6 +347,7 @@ func (q *sqlQuerier) GetAuthorizedUsers(ctx context.Context, arg GetUsersParams,
 			&i.QuietHoursSchedule,
 			&i.ThemePreference,
 			&i.Name,
+			pq.Array(&i.IPAllowlist),
 			&i.Count,
 		); err != nil {
 			return nil, err
@@ -138,14 +138,16 @@ func AllAppSharingLevelValues() []AppSharingLevel {
 type AuditAction string
 
 const (
-	AuditActionCreate   AuditAction = "create"
-	AuditActionWrite    AuditAction = "write"
-	AuditActionDelete   AuditAction = "delete"
-	AuditActionStart    AuditAction = "start"
-	AuditActionStop     AuditAction = "stop"
-	AuditActionLogin    AuditAction = "login"
-	AuditActionLogout   AuditAction = "logout"
-	AuditActionRegister AuditAction = "register"
+	AuditActionCreate      AuditAction = "create"
+	AuditActionWrite       AuditAction = "write"
+	AuditActionDelete      AuditAction = "delete"
+	AuditActionStart       AuditAction = "start"
+	AuditActionStop        AuditAction = "stop"
+	AuditActionLogin       AuditAction = "login"
+	AuditActionLogout      AuditAction = "logout"
+	AuditActionRegister    AuditAction = "register"
+	AuditActionPortForward AuditAction = "port_forward"
+	AuditActionIpRejected  AuditAction = "ip_rejected"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -192,7 +194,9 @@ func (e AuditAction) Valid() bool {
 		AuditActionStop,
 		AuditActionLogin,
 		AuditActionLogout,
-		AuditActionRegister:
+		AuditActionRegister,
+		AuditActionPortForward,
+		AuditActionIpRejected:
 		return true
 	}
 	return false
@@ -208,6 +212,8 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
+		AuditActionPortForward,
+		AuditActionIpRejected,
 	}
 }
 
@@ -272,12 +278,16 @@ func AllAutomaticUpdatesValues() []AutomaticUpdates {
 type BuildReason string
 
 const (
-	BuildReasonInitiator  BuildReason = "initiator"
-	BuildReasonAutostart  BuildReason = "autostart"
-	BuildReasonAutostop   BuildReason = "autostop"
-	BuildReasonDormancy   BuildReason = "dormancy"
-	BuildReasonFailedstop BuildReason = "failedstop"
-	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonInitiator      BuildReason = "initiator"
+	BuildReasonAutostart      BuildReason = "autostart"
+	BuildReasonAutostop       BuildReason = "autostop"
+	BuildReasonDormancy       BuildReason = "dormancy"
+	BuildReasonFailedstop     BuildReason = "failedstop"
+	BuildReasonAutodelete     BuildReason = "autodelete"
+	BuildReasonMaintenance    BuildReason = "maintenance"
+	BuildReasonTemplateUpdate BuildReason = "template_update"
+	BuildReasonBatch          BuildReason = "batch"
+	BuildReasonRetry          BuildReason = "retry"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -322,7 +332,11 @@ func (e BuildReason) Valid() bool {
 		BuildReasonAutostop,
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
-		BuildReasonAutodelete:
+		BuildReasonAutodelete,
+		BuildReasonMaintenance,
+		BuildReasonTemplateUpdate,
+		BuildReasonBatch,
+		BuildReasonRetry:
 		return true
 	}
 	return false
@@ -336,6 +350,71 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonDormancy,
 		BuildReasonFailedstop,
 		BuildReasonAutodelete,
+		BuildReasonMaintenance,
+		BuildReasonTemplateUpdate,
+		BuildReasonBatch,
+		BuildReasonRetry,
+	}
+}
+
+type ConnectionType string
+
+const (
+	ConnectionTypeSsh             ConnectionType = "ssh"
+	ConnectionTypeReconnectingPty ConnectionType = "reconnecting_pty"
+	ConnectionTypePortForwarding  ConnectionType = "port_forwarding"
+)
+
+func (e *ConnectionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ConnectionType(s)
+	case string:
+		*e = ConnectionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ConnectionType: %T", src)
+	}
+	return nil
+}
+
+type NullConnectionType struct {
+	ConnectionType ConnectionType `json:"connection_type"`
+	Valid          bool           `json:"valid"` // Valid is true if ConnectionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullConnectionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ConnectionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ConnectionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullConnectionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ConnectionType), nil
+}
+
+func (e ConnectionType) Valid() bool {
+	switch e {
+	case ConnectionTypeSsh,
+		ConnectionTypeReconnectingPty,
+		ConnectionTypePortForwarding:
+		return true
+	}
+	return false
+}
+
+func AllConnectionTypeValues() []ConnectionType {
+	return []ConnectionType{
+		ConnectionTypeSsh,
+		ConnectionTypeReconnectingPty,
+		ConnectionTypePortForwarding,
 	}
 }
 
@@ -411,6 +490,7 @@ type GroupSource string
 const (
 	GroupSourceUser GroupSource = "user"
 	GroupSourceOidc GroupSource = "oidc"
+	GroupSourceScim GroupSource = "scim"
 )
 
 func (e *GroupSource) Scan(src interface{}) error {
@@ -451,7 +531,8 @@ func (ns NullGroupSource) Value() (driver.Value, error) {
 func (e GroupSource) Valid() bool {
 	switch e {
 	case GroupSourceUser,
-		GroupSourceOidc:
+		GroupSourceOidc,
+		GroupSourceScim:
 		return true
 	}
 	return false
@@ -461,6 +542,65 @@ func AllGroupSourceValues() []GroupSource {
 	return []GroupSource{
 		GroupSourceUser,
 		GroupSourceOidc,
+		GroupSourceScim,
+	}
+}
+
+type GroupSyncTrigger string
+
+const (
+	GroupSyncTriggerLogin      GroupSyncTrigger = "login"
+	GroupSyncTriggerBackground GroupSyncTrigger = "background"
+)
+
+func (e *GroupSyncTrigger) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = GroupSyncTrigger(s)
+	case string:
+		*e = GroupSyncTrigger(s)
+	default:
+		return fmt.Errorf("unsupported scan type for GroupSyncTrigger: %T", src)
+	}
+	return nil
+}
+
+type NullGroupSyncTrigger struct {
+	GroupSyncTrigger GroupSyncTrigger `json:"group_sync_trigger"`
+	Valid            bool             `json:"valid"` // Valid is true if GroupSyncTrigger is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullGroupSyncTrigger) Scan(value interface{}) error {
+	if value == nil {
+		ns.GroupSyncTrigger, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.GroupSyncTrigger.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullGroupSyncTrigger) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.GroupSyncTrigger), nil
+}
+
+func (e GroupSyncTrigger) Valid() bool {
+	switch e {
+	case GroupSyncTriggerLogin,
+		GroupSyncTriggerBackground:
+		return true
+	}
+	return false
+}
+
+func AllGroupSyncTriggerValues() []GroupSyncTrigger {
+	return []GroupSyncTrigger{
+		GroupSyncTriggerLogin,
+		GroupSyncTriggerBackground,
 	}
 }
 
@@ -593,11 +733,13 @@ func AllLogSourceValues() []LogSource {
 type LoginType string
 
 const (
-	LoginTypePassword LoginType = "password"
-	LoginTypeGithub   LoginType = "github"
-	LoginTypeOIDC     LoginType = "oidc"
-	LoginTypeToken    LoginType = "token"
-	LoginTypeNone     LoginType = "none"
+	LoginTypePassword          LoginType = "password"
+	LoginTypeGithub            LoginType = "github"
+	LoginTypeOIDC              LoginType = "oidc"
+	LoginTypeToken             LoginType = "token"
+	LoginTypeNone              LoginType = "none"
+	LoginTypeOAuth2ProviderApp LoginType = "oauth2_provider_app"
+	LoginTypeLDAP              LoginType = "ldap"
 )
 
 func (e *LoginType) Scan(src interface{}) error {
@@ -641,7 +783,9 @@ func (e LoginType) Valid() bool {
 		LoginTypeGithub,
 		LoginTypeOIDC,
 		LoginTypeToken,
-		LoginTypeNone:
+		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP:
 		return true
 	}
 	return false
@@ -654,6 +798,8 @@ func AllLoginTypeValues() []LoginType {
 		LoginTypeOIDC,
 		LoginTypeToken,
 		LoginTypeNone,
+		LoginTypeOAuth2ProviderApp,
+		LoginTypeLDAP,
 	}
 }
 
@@ -975,6 +1121,7 @@ const (
 	ProvisionerJobTypeTemplateVersionImport ProvisionerJobType = "template_version_import"
 	ProvisionerJobTypeWorkspaceBuild        ProvisionerJobType = "workspace_build"
 	ProvisionerJobTypeTemplateVersionDryRun ProvisionerJobType = "template_version_dry_run"
+	ProvisionerJobTypeWorkspaceBuildDryRun  ProvisionerJobType = "workspace_build_dry_run"
 )
 
 func (e *ProvisionerJobType) Scan(src interface{}) error {
@@ -1016,7 +1163,8 @@ func (e ProvisionerJobType) Valid() bool {
 	switch e {
 	case ProvisionerJobTypeTemplateVersionImport,
 		ProvisionerJobTypeWorkspaceBuild,
-		ProvisionerJobTypeTemplateVersionDryRun:
+		ProvisionerJobTypeTemplateVersionDryRun,
+		ProvisionerJobTypeWorkspaceBuildDryRun:
 		return true
 	}
 	return false
@@ -1027,6 +1175,7 @@ func AllProvisionerJobTypeValues() []ProvisionerJobType {
 		ProvisionerJobTypeTemplateVersionImport,
 		ProvisionerJobTypeWorkspaceBuild,
 		ProvisionerJobTypeTemplateVersionDryRun,
+		ProvisionerJobTypeWorkspaceBuildDryRun,
 	}
 }
 
@@ -1088,8 +1237,10 @@ func AllProvisionerStorageMethodValues() []ProvisionerStorageMethod {
 type ProvisionerType string
 
 const (
-	ProvisionerTypeEcho      ProvisionerType = "echo"
-	ProvisionerTypeTerraform ProvisionerType = "terraform"
+	ProvisionerTypeEcho       ProvisionerType = "echo"
+	ProvisionerTypeTerraform  ProvisionerType = "terraform"
+	ProvisionerTypePulumi     ProvisionerType = "pulumi"
+	ProvisionerTypeKubernetes ProvisionerType = "kubernetes"
 )
 
 func (e *ProvisionerType) Scan(src interface{}) error {
@@ -1130,7 +1281,9 @@ func (ns NullProvisionerType) Value() (driver.Value, error) {
 func (e ProvisionerType) Valid() bool {
 	switch e {
 	case ProvisionerTypeEcho,
-		ProvisionerTypeTerraform:
+		ProvisionerTypeTerraform,
+		ProvisionerTypePulumi,
+		ProvisionerTypeKubernetes:
 		return true
 	}
 	return false
@@ -1140,25 +1293,31 @@ func AllProvisionerTypeValues() []ProvisionerType {
 	return []ProvisionerType{
 		ProvisionerTypeEcho,
 		ProvisionerTypeTerraform,
+		ProvisionerTypePulumi,
+		ProvisionerTypeKubernetes,
 	}
 }
 
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
-	ResourceTypeHealthSettings  ResourceType = "health_settings"
+	ResourceTypeOrganization       ResourceType = "organization"
+	ResourceTypeTemplate           ResourceType = "template"
+	ResourceTypeTemplateVersion    ResourceType = "template_version"
+	ResourceTypeUser               ResourceType = "user"
+	ResourceTypeWorkspace          ResourceType = "workspace"
+	ResourceTypeGitSshKey          ResourceType = "git_ssh_key"
+	ResourceTypeApiKey             ResourceType = "api_key"
+	ResourceTypeGroup              ResourceType = "group"
+	ResourceTypeWorkspaceBuild     ResourceType = "workspace_build"
+	ResourceTypeLicense            ResourceType = "license"
+	ResourceTypeWorkspaceProxy     ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin       ResourceType = "convert_login"
+	ResourceTypeHealthSettings     ResourceType = "health_settings"
+	ResourceTypePasskey            ResourceType = "passkey"
+	ResourceTypeTwoFactorPolicy    ResourceType = "two_factor_policy"
+	ResourceTypeWebhook            ResourceType = "webhook"
+	ResourceTypeOrganizationMember ResourceType = "organization_member"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1210,7 +1369,11 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
-		ResourceTypeHealthSettings:
+		ResourceTypeHealthSettings,
+		ResourceTypePasskey,
+		ResourceTypeTwoFactorPolicy,
+		ResourceTypeWebhook,
+		ResourceTypeOrganizationMember:
 		return true
 	}
 	return false
@@ -1231,6 +1394,10 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
 		ResourceTypeHealthSettings,
+		ResourceTypePasskey,
+		ResourceTypeTwoFactorPolicy,
+		ResourceTypeWebhook,
+		ResourceTypeOrganizationMember,
 	}
 }
 
@@ -1350,6 +1517,129 @@ func AllTailnetStatusValues() []TailnetStatus {
 	}
 }
 
+// Defines who can see a template: private, unlisted, or public.
+type TemplateVisibility string
+
+const (
+	TemplateVisibilityPrivate  TemplateVisibility = "private"
+	TemplateVisibilityUnlisted TemplateVisibility = "unlisted"
+	TemplateVisibilityPublic   TemplateVisibility = "public"
+)
+
+func (e *TemplateVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVisibility(s)
+	case string:
+		*e = TemplateVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVisibility struct {
+	TemplateVisibility TemplateVisibility `json:"template_visibility"`
+	Valid              bool               `json:"valid"` // Valid is true if TemplateVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVisibility), nil
+}
+
+func (e TemplateVisibility) Valid() bool {
+	switch e {
+	case TemplateVisibilityPrivate,
+		TemplateVisibilityUnlisted,
+		TemplateVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVisibilityValues() []TemplateVisibility {
+	return []TemplateVisibility{
+		TemplateVisibilityPrivate,
+		TemplateVisibilityUnlisted,
+		TemplateVisibilityPublic,
+	}
+}
+
+type TwoFactorChallengePurpose string
+
+const (
+	TwoFactorChallengePurposeRegisterPasskey TwoFactorChallengePurpose = "register_passkey"
+	TwoFactorChallengePurposeLogin           TwoFactorChallengePurpose = "login"
+	TwoFactorChallengePurposeEnrollPasskey   TwoFactorChallengePurpose = "enroll_passkey"
+)
+
+func (e *TwoFactorChallengePurpose) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TwoFactorChallengePurpose(s)
+	case string:
+		*e = TwoFactorChallengePurpose(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TwoFactorChallengePurpose: %T", src)
+	}
+	return nil
+}
+
+type NullTwoFactorChallengePurpose struct {
+	TwoFactorChallengePurpose TwoFactorChallengePurpose `json:"two_factor_challenge_purpose"`
+	Valid                     bool                      `json:"valid"` // Valid is true if TwoFactorChallengePurpose is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTwoFactorChallengePurpose) Scan(value interface{}) error {
+	if value == nil {
+		ns.TwoFactorChallengePurpose, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TwoFactorChallengePurpose.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTwoFactorChallengePurpose) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TwoFactorChallengePurpose), nil
+}
+
+func (e TwoFactorChallengePurpose) Valid() bool {
+	switch e {
+	case TwoFactorChallengePurposeRegisterPasskey,
+		TwoFactorChallengePurposeLogin,
+		TwoFactorChallengePurposeEnrollPasskey:
+		return true
+	}
+	return false
+}
+
+func AllTwoFactorChallengePurposeValues() []TwoFactorChallengePurpose {
+	return []TwoFactorChallengePurpose{
+		TwoFactorChallengePurposeRegisterPasskey,
+		TwoFactorChallengePurposeLogin,
+		TwoFactorChallengePurposeEnrollPasskey,
+	}
+}
+
 // Defines the users status: active, dormant, or suspended.
 type UserStatus string
 
@@ -1491,6 +1781,73 @@ func AllWorkspaceAgentLifecycleStateValues() []WorkspaceAgentLifecycleState {
 	}
 }
 
+type WorkspaceAgentScriptPhase string
+
+const (
+	WorkspaceAgentScriptPhasePreStart  WorkspaceAgentScriptPhase = "pre_start"
+	WorkspaceAgentScriptPhaseStart     WorkspaceAgentScriptPhase = "start"
+	WorkspaceAgentScriptPhasePostStart WorkspaceAgentScriptPhase = "post_start"
+	WorkspaceAgentScriptPhasePreStop   WorkspaceAgentScriptPhase = "pre_stop"
+	WorkspaceAgentScriptPhaseStop      WorkspaceAgentScriptPhase = "stop"
+)
+
+func (e *WorkspaceAgentScriptPhase) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceAgentScriptPhase(s)
+	case string:
+		*e = WorkspaceAgentScriptPhase(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceAgentScriptPhase: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceAgentScriptPhase struct {
+	WorkspaceAgentScriptPhase WorkspaceAgentScriptPhase `json:"workspace_agent_script_phase"`
+	Valid                     bool                      `json:"valid"` // Valid is true if WorkspaceAgentScriptPhase is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceAgentScriptPhase) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceAgentScriptPhase, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceAgentScriptPhase.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceAgentScriptPhase) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceAgentScriptPhase), nil
+}
+
+func (e WorkspaceAgentScriptPhase) Valid() bool {
+	switch e {
+	case WorkspaceAgentScriptPhasePreStart,
+		WorkspaceAgentScriptPhaseStart,
+		WorkspaceAgentScriptPhasePostStart,
+		WorkspaceAgentScriptPhasePreStop,
+		WorkspaceAgentScriptPhaseStop:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceAgentScriptPhaseValues() []WorkspaceAgentScriptPhase {
+	return []WorkspaceAgentScriptPhase{
+		WorkspaceAgentScriptPhasePreStart,
+		WorkspaceAgentScriptPhaseStart,
+		WorkspaceAgentScriptPhasePostStart,
+		WorkspaceAgentScriptPhasePreStop,
+		WorkspaceAgentScriptPhaseStop,
+	}
+}
+
 type WorkspaceAgentSubsystem string
 
 const (
@@ -1619,6 +1976,70 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceEventType string
+
+const (
+	WorkspaceEventTypeAgentConnected    WorkspaceEventType = "agent_connected"
+	WorkspaceEventTypeAgentDisconnected WorkspaceEventType = "agent_disconnected"
+	WorkspaceEventTypeTtlChanged        WorkspaceEventType = "ttl_changed"
+	WorkspaceEventTypeAppHealthChanged  WorkspaceEventType = "app_health_changed"
+)
+
+func (e *WorkspaceEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceEventType(s)
+	case string:
+		*e = WorkspaceEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceEventType: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceEventType struct {
+	WorkspaceEventType WorkspaceEventType `json:"workspace_event_type"`
+	Valid              bool               `json:"valid"` // Valid is true if WorkspaceEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceEventType), nil
+}
+
+func (e WorkspaceEventType) Valid() bool {
+	switch e {
+	case WorkspaceEventTypeAgentConnected,
+		WorkspaceEventTypeAgentDisconnected,
+		WorkspaceEventTypeTtlChanged,
+		WorkspaceEventTypeAppHealthChanged:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceEventTypeValues() []WorkspaceEventType {
+	return []WorkspaceEventType{
+		WorkspaceEventTypeAgentConnected,
+		WorkspaceEventTypeAgentDisconnected,
+		WorkspaceEventTypeTtlChanged,
+		WorkspaceEventTypeAppHealthChanged,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1694,6 +2115,12 @@ type APIKey struct {
 	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
 	Scope           APIKeyScope `db:"scope" json:"scope"`
 	TokenName       string      `db:"token_name" json:"token_name"`
+	// Fine-grained scopes the key is limited to, e.g. workspace:read. If empty, the key is only limited by its scope.
+	Scopes []string `db:"scopes" json:"scopes"`
+	// Workspaces a key with fine-grained scopes is limited to. If empty, the key is not limited to specific workspaces.
+	AllowedWorkspaceIDs []uuid.UUID `db:"allowed_workspace_ids" json:"allowed_workspace_ids"`
+	// CIDR ranges the key may be used from, in addition to the allowlist of its user. If empty, the key is only limited by the allowlist of its user.
+	IPAllowlist []string `db:"ip_allowlist" json:"ip_allowlist"`
 }
 
 type AuditLog struct {
@@ -1714,6 +2141,35 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+// Connections opened by clients to workspace agents. Like audit logs, rows are never updated.
+type ConnectionLog struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	Time             time.Time      `db:"time" json:"time"`
+	OrganizationID   uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID      uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string         `db:"workspace_name" json:"workspace_name"`
+	AgentName        string         `db:"agent_name" json:"agent_name"`
+	Type             ConnectionType `db:"type" json:"type"`
+	// Forwarded port of port_forwarding connections.
+	Port      sql.NullInt32 `db:"port" json:"port"`
+	Ip        pqtype.Inet   `db:"ip" json:"ip"`
+	UserAgent string        `db:"user_agent" json:"user_agent"`
+	UserID    uuid.UUID     `db:"user_id" json:"user_id"`
+	// ID of the API key that authenticated the connection.
+	APIKeyID string `db:"api_key_id" json:"api_key_id"`
+}
+
+// Custom site wide roles defined by administrators.
+type CustomRole struct {
+	Name        string `db:"name" json:"name"`
+	DisplayName string `db:"display_name" json:"display_name"`
+	// The permissions granted by the role, as a list of resource and action pairs.
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to store the keys used to encrypt the database.
 type DBCryptKey struct {
 	// An integer used to identify the key.
@@ -1779,6 +2235,36 @@ type GroupMember struct {
 	GroupID uuid.UUID `db:"group_id" json:"group_id"`
 }
 
+// Records of group sync runs, kept for debugging group sync.
+type GroupSyncRun struct {
+	ID      uuid.UUID        `db:"id" json:"id"`
+	Trigger GroupSyncTrigger `db:"trigger" json:"trigger"`
+	// The user whose groups were synced at login. Null for background runs, which sync every OIDC user, and for logins that failed before the user was known.
+	UserID      uuid.NullUUID `db:"user_id" json:"user_id"`
+	StartedAt   time.Time     `db:"started_at" json:"started_at"`
+	FinishedAt  time.Time     `db:"finished_at" json:"finished_at"`
+	UsersSynced int32         `db:"users_synced" json:"users_synced"`
+	UsersFailed int32         `db:"users_failed" json:"users_failed"`
+	// The groups returned by the identity provider at login, before nested groups are resolved.
+	IDPGroups []string `db:"idp_groups" json:"idp_groups"`
+	// The Coder groups the user was synced to at login, after nested groups are resolved and the mapping, filter and prefix stripping are applied.
+	Groups []string `db:"groups" json:"groups"`
+	// The errors that occurred during the run, if any.
+	Error string `db:"error" json:"error"`
+}
+
+// Notifications shown to users in the dashboard, regardless of how else they are delivered.
+type InboxNotification struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Title     string    `db:"title" json:"title"`
+	Content   string    `db:"content" json:"content"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// When the user marked the notification as read. NULL while it is unread.
+	ReadAt sql.NullTime `db:"read_at" json:"read_at"`
+}
+
 type License struct {
 	ID         int32     `db:"id" json:"id"`
 	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
@@ -1788,6 +2274,41 @@ type License struct {
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
 }
 
+// Failed password logins per user and IP address, used to slow down brute-force attacks.
+type LoginFailure struct {
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+	// The number of failed attempts since the count was last reset by a successful login or by the failures expiring.
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LastFailedAt   time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// Notifications sent to users, kept to report their delivery status.
+type NotificationMessage struct {
+	ID     uuid.UUID `db:"id" json:"id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// Template the notification is rendered with.
+	Template string `db:"template" json:"template"`
+	// Values the template is rendered with.
+	Labels    json.RawMessage `db:"labels" json:"labels"`
+	Attempts  int32           `db:"attempts" json:"attempts"`
+	LastError string          `db:"last_error" json:"last_error"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	SentAt    sql.NullTime    `db:"sent_at" json:"sent_at"`
+	// When the notification should next be sent. NULL once it has been sent or has run out of attempts.
+	NextAttemptAt sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
+	// Method the notification is delivered with, such as smtp, slack or teams.
+	Method string `db:"method" json:"method"`
+}
+
+// Notification templates users have opted out of or back into. Templates without a preference are enabled.
+type NotificationPreference struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // A table used to configure apps that can use Coder as an OAuth2 provider, the reverse of what we are calling external authentication.
 type OAuth2ProviderApp struct {
 	ID          uuid.UUID `db:"id" json:"id"`
@@ -1798,6 +2319,21 @@ type OAuth2ProviderApp struct {
 	CallbackURL string    `db:"callback_url" json:"callback_url"`
 }
 
+// Codes are meant to be exchanged for access tokens.
+type OAuth2ProviderAppCode struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret []byte    `db:"hashed_secret" json:"hashed_secret"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	AppID        uuid.UUID `db:"app_id" json:"app_id"`
+	// The nonce the app sent with the authorization request. It is returned in the ID token.
+	Nonce string `db:"nonce" json:"nonce"`
+	// The S256 PKCE code challenge the app sent with the authorization request. Empty if the app did not use PKCE.
+	CodeChallenge string `db:"code_challenge" json:"code_challenge"`
+}
+
 type OAuth2ProviderAppSecret struct {
 	ID           uuid.UUID    `db:"id" json:"id"`
 	CreatedAt    time.Time    `db:"created_at" json:"created_at"`
@@ -1808,6 +2344,17 @@ type OAuth2ProviderAppSecret struct {
 	AppID         uuid.UUID `db:"app_id" json:"app_id"`
 }
 
+type OAuth2ProviderAppToken struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt  time.Time `db:"expires_at" json:"expires_at"`
+	HashPrefix []byte    `db:"hash_prefix" json:"hash_prefix"`
+	// Refresh tokens provide a way to refresh an access token (API key). An expired API key can be refreshed if this token is not yet expired, meaning this expiry can outlive an API key.
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
+}
+
 type Organization struct {
 	ID          uuid.UUID `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
@@ -1824,6 +2371,27 @@ type OrganizationMember struct {
 	Roles          []string  `db:"roles" json:"roles"`
 }
 
+type OrganizationQuota struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// The maximum number of running workspaces of a user in the organization. Zero disables the limit.
+	MaxRunningWorkspacesPerUser int32 `db:"max_running_workspaces_per_user" json:"max_running_workspaces_per_user"`
+	// The maximum total daily cost of the workspaces in the organization. Zero disables the limit.
+	MaxDailyCost int32     `db:"max_daily_cost" json:"max_daily_cost"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Default values of template variables for all templates of an organization. They are used when a template version is pushed without a value for the variable.
+type OrganizationTemplateVariableValue struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	Value          string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	Sensitive  bool           `db:"sensitive" json:"sensitive"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
+}
+
 type ParameterSchema struct {
 	ID                       uuid.UUID                  `db:"id" json:"id"`
 	CreatedAt                time.Time                  `db:"created_at" json:"created_at"`
@@ -1856,6 +2424,21 @@ type ParameterValue struct {
 	DestinationScheme ParameterDestinationScheme `db:"destination_scheme" json:"destination_scheme"`
 }
 
+// WebAuthn credentials registered by users as a second factor for password logins.
+type Passkey struct {
+	ID     uuid.UUID `db:"id" json:"id"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Name   string    `db:"name" json:"name"`
+	// The credential ID chosen by the authenticator.
+	CredentialID []byte `db:"credential_id" json:"credential_id"`
+	// The COSE encoded public key of the credential.
+	PublicKey []byte `db:"public_key" json:"public_key"`
+	// The signature counter last reported by the authenticator, used to detect cloned authenticators.
+	SignCount  int64        `db:"sign_count" json:"sign_count"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
 type ProvisionerDaemon struct {
 	ID           uuid.UUID         `db:"id" json:"id"`
 	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
@@ -1867,6 +2450,8 @@ type ProvisionerDaemon struct {
 	Version      string            `db:"version" json:"version"`
 	// The API version of the provisioner daemon
 	APIVersion string `db:"api_version" json:"api_version"`
+	// The organization the daemon acquires jobs from. NULL means jobs from every organization.
+	OrganizationID uuid.NullUUID `db:"organization_id" json:"organization_id"`
 }
 
 type ProvisionerJob struct {
@@ -1890,6 +2475,21 @@ type ProvisionerJob struct {
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
 	// Computed column to track the status of the job.
 	JobStatus ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	// Pending jobs with a higher priority are acquired first. Within a priority, workspace builds are preferred over other job types.
+	Priority int32 `db:"priority" json:"priority"`
+	// Requirements on the tags of the provisioner daemons that may acquire the job, in addition to exactly matching tags. Each requirement has a key, an operator (in or not_in) and values, which may contain * wildcards.
+	TagRequirements ProvisionerTagRequirements `db:"tag_requirements" json:"tag_requirements"`
+}
+
+// Logs of completed provisioner jobs, compressed to save space. Archived logs are removed from provisioner_job_logs.
+type ProvisionerJobLogArchive struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	LogCount  int32     `db:"log_count" json:"log_count"`
+	// The size of the logs in bytes before they were compressed.
+	UncompressedSize int64 `db:"uncompressed_size" json:"uncompressed_size"`
+	// A gzip compressed JSON array of the archived provisioner_job_logs rows.
+	CompressedLogs []byte `db:"compressed_logs" json:"compressed_logs"`
 }
 
 type ProvisionerJobLog struct {
@@ -1902,6 +2502,26 @@ type ProvisionerJobLog struct {
 	ID        int64     `db:"id" json:"id"`
 }
 
+// Keys that authenticate external provisioner daemons to an organization.
+type ProvisionerKey struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	// The tags of every daemon authenticated with the key. Daemons may not set other values for these tags.
+	Tags StringMap `db:"tags" json:"tags"`
+	// When the secret of the key was last replaced.
+	RotatedAt sql.NullTime `db:"rotated_at" json:"rotated_at"`
+}
+
+// Request counts per rate limit key and window, shared by all replicas.
+type RateLimitCounter struct {
+	Key         string    `db:"key" json:"key"`
+	WindowStart time.Time `db:"window_start" json:"window_start"`
+	Count       int32     `db:"count" json:"count"`
+}
+
 type Replica struct {
 	ID              uuid.UUID    `db:"id" json:"id"`
 	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
@@ -1917,6 +2537,23 @@ type Replica struct {
 	Primary         bool         `db:"primary" json:"primary"`
 }
 
+// Web terminal sessions recorded in asciinema format. The recordings themselves are kept in the session recording store.
+type SessionRecording struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID        uuid.UUID `db:"agent_id" json:"agent_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	StartedAt      time.Time `db:"started_at" json:"started_at"`
+	EndedAt        time.Time `db:"ended_at" json:"ended_at"`
+	// Key of the recording in the session recording store.
+	ObjectKey string `db:"object_key" json:"object_key"`
+	SizeBytes int64  `db:"size_bytes" json:"size_bytes"`
+	// Time after which the recording is deleted. NULL keeps the recording forever.
+	ExpiresAt sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
 type SiteConfig struct {
 	Key   string `db:"key" json:"key"`
 	Value string `db:"value" json:"value"`
@@ -1966,36 +2603,49 @@ type TailnetTunnel struct {
 
 // Joins in the username + avatar url of the created by user.
 type Template struct {
-	ID                            uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                     time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                     time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID                uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted                       bool            `db:"deleted" json:"deleted"`
-	Name                          string          `db:"name" json:"name"`
-	Provisioner                   ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID               uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                   string          `db:"description" json:"description"`
-	DefaultTTL                    int64           `db:"default_ttl" json:"default_ttl"`
-	CreatedBy                     uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                          string          `db:"icon" json:"icon"`
-	UserACL                       TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                      TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                   string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs  bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	MaxTTL                        int64           `db:"max_ttl" json:"max_ttl"`
-	AllowUserAutostart            bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
-	FailureTTL                    int64           `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64           `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64           `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-	AutostopRequirementDaysOfWeek int16           `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64           `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16           `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	RequireActiveVersion          bool            `db:"require_active_version" json:"require_active_version"`
-	Deprecated                    string          `db:"deprecated" json:"deprecated"`
-	UseMaxTtl                     bool            `db:"use_max_ttl" json:"use_max_ttl"`
-	CreatedByAvatarURL            string          `db:"created_by_avatar_url" json:"created_by_avatar_url"`
-	CreatedByUsername             string          `db:"created_by_username" json:"created_by_username"`
+	ID                            uuid.UUID          `db:"id" json:"id"`
+	CreatedAt                     time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt                     time.Time          `db:"updated_at" json:"updated_at"`
+	OrganizationID                uuid.UUID          `db:"organization_id" json:"organization_id"`
+	Deleted                       bool               `db:"deleted" json:"deleted"`
+	Name                          string             `db:"name" json:"name"`
+	Provisioner                   ProvisionerType    `db:"provisioner" json:"provisioner"`
+	ActiveVersionID               uuid.UUID          `db:"active_version_id" json:"active_version_id"`
+	Description                   string             `db:"description" json:"description"`
+	DefaultTTL                    int64              `db:"default_ttl" json:"default_ttl"`
+	CreatedBy                     uuid.UUID          `db:"created_by" json:"created_by"`
+	Icon                          string             `db:"icon" json:"icon"`
+	UserACL                       TemplateACL        `db:"user_acl" json:"user_acl"`
+	GroupACL                      TemplateACL        `db:"group_acl" json:"group_acl"`
+	DisplayName                   string             `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs  bool               `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	MaxTTL                        int64              `db:"max_ttl" json:"max_ttl"`
+	AllowUserAutostart            bool               `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool               `db:"allow_user_autostop" json:"allow_user_autostop"`
+	FailureTTL                    int64              `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64              `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64              `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
+	AutostopRequirementDaysOfWeek int16              `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64              `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16              `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	RequireActiveVersion          bool               `db:"require_active_version" json:"require_active_version"`
+	Deprecated                    string             `db:"deprecated" json:"deprecated"`
+	UseMaxTtl                     bool               `db:"use_max_ttl" json:"use_max_ttl"`
+	MaintenanceWindowSchedule     string             `db:"maintenance_window_schedule" json:"maintenance_window_schedule"`
+	MaintenanceWindowDuration     int64              `db:"maintenance_window_duration" json:"maintenance_window_duration"`
+	Visibility                    TemplateVisibility `db:"visibility" json:"visibility"`
+	MaxBuildDuration              int64              `db:"max_build_duration" json:"max_build_duration"`
+	WorkspaceNamePattern          string             `db:"workspace_name_pattern" json:"workspace_name_pattern"`
+	WorkspaceNameDescription      string             `db:"workspace_name_description" json:"workspace_name_description"`
+	MaxRunningWorkspaces          int32              `db:"max_running_workspaces" json:"max_running_workspaces"`
+	PortForwardingDenyByDefault   bool               `db:"port_forwarding_deny_by_default" json:"port_forwarding_deny_by_default"`
+	PortForwardingAllowedPorts    []int32            `db:"port_forwarding_allowed_ports" json:"port_forwarding_allowed_ports"`
+	SessionRecordingEnabled       bool               `db:"session_recording_enabled" json:"session_recording_enabled"`
+	SessionRecordingRetention     int64              `db:"session_recording_retention" json:"session_recording_retention"`
+	BuildRetryMaxAttempts         int32              `db:"build_retry_max_attempts" json:"build_retry_max_attempts"`
+	BuildRetryBackoff             int64              `db:"build_retry_backoff" json:"build_retry_backoff"`
+	CreatedByAvatarURL            string             `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+	CreatedByUsername             string             `db:"created_by_username" json:"created_by_username"`
 }
 
 type TemplateTable struct {
@@ -2036,6 +2686,44 @@ type TemplateTable struct {
 	// If set to a non empty string, the template will no longer be able to be used. The message will be displayed to the user.
 	Deprecated string `db:"deprecated" json:"deprecated"`
 	UseMaxTtl  bool   `db:"use_max_ttl" json:"use_max_ttl"`
+	// A cron expression for the start of the maintenance window. During the window, running workspaces on an outdated template version are stopped and rebuilt on the active version. Empty disables maintenance.
+	MaintenanceWindowSchedule string `db:"maintenance_window_schedule" json:"maintenance_window_schedule"`
+	// The duration of the maintenance window in nanoseconds.
+	MaintenanceWindowDuration int64 `db:"maintenance_window_duration" json:"maintenance_window_duration"`
+	// Restricts who can see the template, on top of its ACL.
+	Visibility TemplateVisibility `db:"visibility" json:"visibility"`
+	// The maximum duration of workspace builds in nanoseconds. Builds running for longer are failed and aborted. Zero disables the limit.
+	MaxBuildDuration int64 `db:"max_build_duration" json:"max_build_duration"`
+	// A regular expression that the names of workspaces created from the template must match. Empty allows any valid name.
+	WorkspaceNamePattern string `db:"workspace_name_pattern" json:"workspace_name_pattern"`
+	// A human readable description of workspace_name_pattern shown when a name doesn't match.
+	WorkspaceNameDescription string `db:"workspace_name_description" json:"workspace_name_description"`
+	// The maximum number of running workspaces of the template. Zero disables the limit.
+	MaxRunningWorkspaces int32 `db:"max_running_workspaces" json:"max_running_workspaces"`
+	// Denies forwarding and sharing agent ports, except for those in port_forwarding_allowed_ports.
+	PortForwardingDenyByDefault bool `db:"port_forwarding_deny_by_default" json:"port_forwarding_deny_by_default"`
+	// The agent ports that may be forwarded and shared when port_forwarding_deny_by_default is set.
+	PortForwardingAllowedPorts []int32 `db:"port_forwarding_allowed_ports" json:"port_forwarding_allowed_ports"`
+	// Records the web terminal sessions of workspaces of the template.
+	SessionRecordingEnabled bool `db:"session_recording_enabled" json:"session_recording_enabled"`
+	// Duration in nanoseconds session recordings are kept for. Zero keeps them forever.
+	SessionRecordingRetention int64 `db:"session_recording_retention" json:"session_recording_retention"`
+	// The maximum number of attempts of a workspace build that failed transiently, including the first attempt. Values below 2 disable retries.
+	BuildRetryMaxAttempts int32 `db:"build_retry_max_attempts" json:"build_retry_max_attempts"`
+	// Duration in nanoseconds before the first retry of a failed workspace build. The delay doubles with every attempt.
+	BuildRetryBackoff int64 `db:"build_retry_backoff" json:"build_retry_backoff"`
+}
+
+// Values of template variables set on a template. They take precedence over the values the active version was pushed with, so they can be changed without pushing a new version.
+type TemplateVariableValue struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
+	Value      string    `db:"value" json:"value"`
+	// The ID of the key used to encrypt the value. If this is NULL, the value is not encrypted
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	Sensitive  bool           `db:"sensitive" json:"sensitive"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -2052,6 +2740,9 @@ type TemplateVersion struct {
 	ExternalAuthProviders []string      `db:"external_auth_providers" json:"external_auth_providers"`
 	Message               string        `db:"message" json:"message"`
 	Archived              bool          `db:"archived" json:"archived"`
+	GitCommitSha          string        `db:"git_commit_sha" json:"git_commit_sha"`
+	GitBranch             string        `db:"git_branch" json:"git_branch"`
+	GitTag                string        `db:"git_tag" json:"git_tag"`
 	CreatedByAvatarURL    string        `db:"created_by_avatar_url" json:"created_by_avatar_url"`
 	CreatedByUsername     string        `db:"created_by_username" json:"created_by_username"`
 }
@@ -2090,6 +2781,17 @@ type TemplateVersionParameter struct {
 	DisplayOrder int32 `db:"display_order" json:"display_order"`
 	// The value of an ephemeral parameter will not be preserved between consecutive workspace builds.
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
+	// Expression over the values of other parameters. The parameter is only displayed and validated if it is met.
+	DisplayCondition string `db:"display_condition" json:"display_condition"`
+}
+
+// Named sets of parameter values of a template version, which can be selected when creating a workspace.
+type TemplateVersionPreset struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	// Array of parameter names and values, validated against the parameters of the template version when the preset is created.
+	Parameters json.RawMessage `db:"parameters" json:"parameters"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
 }
 
 type TemplateVersionTable struct {
@@ -2107,6 +2809,12 @@ type TemplateVersionTable struct {
 	// Message describing the changes in this version of the template, similar to a Git commit message. Like a commit message, this should be a short, high-level description of the changes in this version of the template. This message is immutable and should not be updated after the fact.
 	Message  string `db:"message" json:"message"`
 	Archived bool   `db:"archived" json:"archived"`
+	// SHA of the git commit the template version was pushed from, if the template directory is a git repository.
+	GitCommitSha string `db:"git_commit_sha" json:"git_commit_sha"`
+	// Branch checked out when the template version was pushed, empty for a detached HEAD.
+	GitBranch string `db:"git_branch" json:"git_branch"`
+	// Tag pointing at the git commit the template version was pushed from, if any.
+	GitTag string `db:"git_tag" json:"git_tag"`
 }
 
 type TemplateVersionVariable struct {
@@ -2127,6 +2835,36 @@ type TemplateVersionVariable struct {
 	Sensitive bool `db:"sensitive" json:"sensitive"`
 }
 
+// Pending WebAuthn challenges. Each challenge can only be answered once.
+type TwoFactorChallenge struct {
+	ID        uuid.UUID                 `db:"id" json:"id"`
+	UserID    uuid.UUID                 `db:"user_id" json:"user_id"`
+	Purpose   TwoFactorChallengePurpose `db:"purpose" json:"purpose"`
+	Challenge []byte                    `db:"challenge" json:"challenge"`
+	CreatedAt time.Time                 `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time                 `db:"expires_at" json:"expires_at"`
+}
+
+// Site roles whose members must use a second factor for password logins.
+type TwoFactorPolicy struct {
+	ID   uuid.UUID `db:"id" json:"id"`
+	Role string    `db:"role" json:"role"`
+	// Password logins without a second factor are rejected after this time, which gives users time to register a passkey.
+	EnforceAfter time.Time `db:"enforce_after" json:"enforce_after"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Failed password logins for emails and usernames that don't belong to a user, throttled like those of existing users so that the responses don't reveal which users exist.
+type UnknownLoginFailure struct {
+	// SHA256 of the lowercased email or username. The login itself isn't stored, it might be a mistyped password.
+	LoginHash []byte      `db:"login_hash" json:"login_hash"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+	// The number of failed attempts since the count was last reset by the failures expiring.
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LastFailedAt   time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
 type User struct {
 	ID             uuid.UUID      `db:"id" json:"id"`
 	Email          string         `db:"email" json:"email"`
@@ -2146,6 +2884,19 @@ type User struct {
 	ThemePreference string `db:"theme_preference" json:"theme_preference"`
 	// Name of the Coder user
 	Name string `db:"name" json:"name"`
+	// CIDR ranges the API keys of the user may be used from. If empty, the keys may be used from any address.
+	IPAllowlist []string `db:"ip_allowlist" json:"ip_allowlist"`
+}
+
+// Number of API requests made by each user, by hour, API category and workspace.
+type UserActivity struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Hour   time.Time `db:"hour" json:"hour"`
+	// The API category of the requests, e.g. workspaces or templates.
+	Category string `db:"category" json:"category"`
+	// The workspace the requests were about, or the nil UUID if they were not about a workspace.
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Count       int32     `db:"count" json:"count"`
 }
 
 type UserLink struct {
@@ -2163,6 +2914,30 @@ type UserLink struct {
 	DebugContext json.RawMessage `db:"debug_context" json:"debug_context"`
 }
 
+// Users that can't log in with a password until locked_until because of too many failed attempts.
+type UserLockout struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// The number of failed attempts from all IP addresses that caused the lockout.
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LockedAt       time.Time `db:"locked_at" json:"locked_at"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+}
+
+// Single use codes that can be used instead of a passkey to complete a login.
+type UserRecoveryCode struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	UserID     uuid.UUID    `db:"user_id" json:"user_id"`
+	HashedCode []byte       `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	UsedAt     sql.NullTime `db:"used_at" json:"used_at"`
+}
+
+// The identifier a SCIM identity provider assigned to a user it provisioned.
+type UserSCIMExternalID struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	ExternalID string    `db:"external_id" json:"external_id"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID `db:"id" json:"id"`
@@ -2170,6 +2945,36 @@ type VisibleUser struct {
 	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
 }
 
+// HTTPS endpoints registered by deployment admins to receive signed lifecycle events.
+type Webhook struct {
+	ID  uuid.UUID `db:"id" json:"id"`
+	Url string    `db:"url" json:"url"`
+	// Shared secret used to sign each delivery with HMAC-SHA256.
+	Secret string `db:"secret" json:"secret"`
+	// Event types the webhook is subscribed to.
+	Events    []string  `db:"events" json:"events"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// The ID of the key used to encrypt the secret. If this is NULL, the secret is not encrypted
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+}
+
+// Every attempt to send an event to a webhook, kept for auditing and replay.
+type WebhookDelivery struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	WebhookID uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event     string          `db:"event" json:"event"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	Attempts  int32           `db:"attempts" json:"attempts"`
+	// HTTP status code of the most recent attempt, if a response was received.
+	StatusCode  sql.NullInt32 `db:"status_code" json:"status_code"`
+	LastError   string        `db:"last_error" json:"last_error"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	DeliveredAt sql.NullTime  `db:"delivered_at" json:"delivered_at"`
+	// When the delivery should next be attempted. NULL once the delivery has succeeded or run out of attempts.
+	NextAttemptAt sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
+}
+
 type Workspace struct {
 	ID                uuid.UUID        `db:"id" json:"id"`
 	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
@@ -2185,6 +2990,16 @@ type Workspace struct {
 	DormantAt         sql.NullTime     `db:"dormant_at" json:"dormant_at"`
 	DeletingAt        sql.NullTime     `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	// Prevents template maintenance windows from stopping and rebuilding the workspace.
+	MaintenanceOptOut bool `db:"maintenance_opt_out" json:"maintenance_opt_out"`
+	// Users the workspace is shared with, mapped to the actions they can perform on it.
+	UserACL TemplateACL `db:"user_acl" json:"user_acl"`
+	// Ephemeral workspaces are deleted, rather than stopped, once they outlive their TTL or the API token that created them expires.
+	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
+	// The API token that created the ephemeral workspace, if any. The workspace is deleted when the token expires or is deleted.
+	EphemeralAPIKeyID sql.NullString `db:"ephemeral_api_key_id" json:"ephemeral_api_key_id"`
+	// Prevents the workspace from becoming dormant or being deleted for inactivity.
+	DormancyExempt bool `db:"dormancy_exempt" json:"dormancy_exempt"`
 }
 
 type WorkspaceAgent struct {
@@ -2239,6 +3054,16 @@ type WorkspaceAgentLog struct {
 	LogSourceID uuid.UUID `db:"log_source_id" json:"log_source_id"`
 }
 
+// GPUs that workspace agents detected in their workspace on startup.
+type WorkspaceAgentGPU struct {
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	Index         int32     `db:"index" json:"index"`
+	Vendor        string    `db:"vendor" json:"vendor"`
+	Model         string    `db:"model" json:"model"`
+	MemoryBytes   int64     `db:"memory_bytes" json:"memory_bytes"`
+	DriverVersion string    `db:"driver_version" json:"driver_version"`
+}
+
 type WorkspaceAgentLogSource struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	ID               uuid.UUID `db:"id" json:"id"`
@@ -2259,6 +3084,39 @@ type WorkspaceAgentMetadatum struct {
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
 }
 
+// Recent values of workspace agent metadata. Only a limited number of values is kept per metadatum, older ones are deleted as new ones are recorded.
+type WorkspaceAgentMetadataHistory struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+}
+
+// TCP ports that workspace agents found listening in their workspace. They are exposed as discovered apps.
+type WorkspaceAgentPort struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	Port         int32     `db:"port" json:"port"`
+	ProcessName  string    `db:"process_name" json:"process_name"`
+	DiscoveredAt time.Time `db:"discovered_at" json:"discovered_at"`
+}
+
+// Agent ports that workspace owners shared with other users. Port-based app URLs of shared ports use the share level instead of owner.
+type WorkspaceAgentPortShare struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// Shares reference agents by name, so they are kept across workspace builds.
+	AgentName  string          `db:"agent_name" json:"agent_name"`
+	Port       int32           `db:"port" json:"port"`
+	ShareLevel AppSharingLevel `db:"share_level" json:"share_level"`
+}
+
+// The token an agent used before its last rotation. It stays valid until it expires so requests already in flight are not rejected.
+type WorkspaceAgentPreviousAuthToken struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	AuthToken uuid.UUID `db:"auth_token" json:"auth_token"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
 type WorkspaceAgentScript struct {
 	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
 	LogSourceID      uuid.UUID `db:"log_source_id" json:"log_source_id"`
@@ -2270,6 +3128,8 @@ type WorkspaceAgentScript struct {
 	RunOnStart       bool      `db:"run_on_start" json:"run_on_start"`
 	RunOnStop        bool      `db:"run_on_stop" json:"run_on_stop"`
 	TimeoutSeconds   int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	// The phase of the start or stop lifecycle the script runs in. The agent runs the phases in order, and the scripts of a phase in parallel.
+	Phase WorkspaceAgentScriptPhase `db:"phase" json:"phase"`
 }
 
 type WorkspaceAgentStat struct {
@@ -2290,6 +3150,12 @@ type WorkspaceAgentStat struct {
 	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
 	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
 	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
+	CPUUsedCores                float64         `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPUTotalCores               float64         `db:"cpu_total_cores" json:"cpu_total_cores"`
+	MemoryUsedBytes             int64           `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryTotalBytes            int64           `db:"memory_total_bytes" json:"memory_total_bytes"`
+	DiskUsedBytes               int64           `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes              int64           `db:"disk_total_bytes" json:"disk_total_bytes"`
 }
 
 type WorkspaceApp struct {
@@ -2350,6 +3216,8 @@ type WorkspaceBuild struct {
 	Reason               BuildReason         `db:"reason" json:"reason"`
 	DailyCost            int32               `db:"daily_cost" json:"daily_cost"`
 	MaxDeadline          time.Time           `db:"max_deadline" json:"max_deadline"`
+	Attempt              int32               `db:"attempt" json:"attempt"`
+	RetryAt              sql.NullTime        `db:"retry_at" json:"retry_at"`
 	InitiatorByAvatarUrl string              `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
 	InitiatorByUsername  string              `db:"initiator_by_username" json:"initiator_by_username"`
 }
@@ -2377,6 +3245,62 @@ type WorkspaceBuildTable struct {
 	Reason            BuildReason         `db:"reason" json:"reason"`
 	DailyCost         int32               `db:"daily_cost" json:"daily_cost"`
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
+	// Attempt number of the build. Builds retrying a failed build have the attempt number of the failed build plus one.
+	Attempt int32 `db:"attempt" json:"attempt"`
+	// Time at which the failed build is retried. NULL if the build did not fail, or failed for good.
+	RetryAt sql.NullTime `db:"retry_at" json:"retry_at"`
+}
+
+// The result of the latest drift check of each running workspace.
+type WorkspaceDrift struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The dry-run job of the latest drift check. While it runs, the other columns hold the result of the check before it.
+	JobID uuid.UUID `db:"job_id" json:"job_id"`
+	// The workspace build whose state the last completed check planned against.
+	BuildID   uuid.NullUUID `db:"build_id" json:"build_id"`
+	CheckedAt sql.NullTime  `db:"checked_at" json:"checked_at"`
+	// The addresses of the resources that were changed outside of Coder.
+	DriftedResources []string  `db:"drifted_resources" json:"drifted_resources"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Events of a workspace that are not recorded elsewhere. Together with the builds of the workspace they make up its timeline.
+type WorkspaceEvent struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	WorkspaceID uuid.UUID          `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time          `db:"created_at" json:"created_at"`
+	Type        WorkspaceEventType `db:"type" json:"type"`
+	// The user who caused the event. Null for events caused by the workspace itself.
+	UserID  uuid.NullUUID `db:"user_id" json:"user_id"`
+	AgentID uuid.NullUUID `db:"agent_id" json:"agent_id"`
+	AppID   uuid.NullUUID `db:"app_id" json:"app_id"`
+	// The value before the change: the TTL in milliseconds for ttl_changed, empty if autostop was disabled, and the app health for app_health_changed.
+	OldValue string `db:"old_value" json:"old_value"`
+	// The value after the change, in the same format as old_value.
+	NewValue string `db:"new_value" json:"new_value"`
+}
+
+// Workspaces that a user has pinned to the top of their workspace list.
+type WorkspaceFavorite struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// History of the parameter values of workspaces. Rows are recorded when build parameters are inserted, and are never updated.
+type WorkspaceParameterChange struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceID      uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	BuildNumber      int32     `db:"build_number" json:"build_number"`
+	Name             string    `db:"name" json:"name"`
+	// Value of the parameter in the previous build, or NULL if the parameter wasn't set.
+	OldValue sql.NullString `db:"old_value" json:"old_value"`
+	NewValue string         `db:"new_value" json:"new_value"`
+	// User who started the build that changed the value.
+	InitiatorID uuid.UUID `db:"initiator_id" json:"initiator_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
 type WorkspaceProxy struct {
@@ -2402,6 +3326,20 @@ type WorkspaceProxy struct {
 	Version  string `db:"version" json:"version"`
 }
 
+// The latest health check of each workspace proxy, run periodically by coderd.
+type WorkspaceProxyHealth struct {
+	ProxyID uuid.UUID `db:"proxy_id" json:"proxy_id"`
+	Status  string    `db:"status" json:"status"`
+	// The round trip time of the health check request from coderd to the proxy.
+	LatencyMS     int32 `db:"latency_ms" json:"latency_ms"`
+	DerpReachable bool  `db:"derp_reachable" json:"derp_reachable"`
+	// Whether the major or minor version of the proxy differs from coderd.
+	VersionSkew bool      `db:"version_skew" json:"version_skew"`
+	Errors      []string  `db:"errors" json:"errors"`
+	Warnings    []string  `db:"warnings" json:"warnings"`
+	CheckedAt   time.Time `db:"checked_at" json:"checked_at"`
+}
+
 type WorkspaceResource struct {
 	ID           uuid.UUID           `db:"id" json:"id"`
 	CreatedAt    time.Time           `db:"created_at" json:"created_at"`
@@ -2413,6 +3351,22 @@ type WorkspaceResource struct {
 	Icon         string              `db:"icon" json:"icon"`
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
+	// Whether the resource is captured by snapshots of the workspace.
+	Snapshot bool `db:"snapshot" json:"snapshot"`
+}
+
+// The daily cost of each workspace resource, recorded once per day the resource existed.
+type WorkspaceResourceCost struct {
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Date           time.Time `db:"date" json:"date"`
+	ResourceType   string    `db:"resource_type" json:"resource_type"`
+	ResourceName   string    `db:"resource_name" json:"resource_name"`
+	// The currency of the cost, taken from the "currency" metadata of the resource.
+	Currency string `db:"currency" json:"currency"`
+	// The last known daily cost of the resource on the date.
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type WorkspaceResourceMetadatum struct {
@@ -2422,3 +3376,14 @@ type WorkspaceResourceMetadatum struct {
 	Sensitive           bool           `db:"sensitive" json:"sensitive"`
 	ID                  int64          `db:"id" json:"id"`
 }
+
+// Snapshots of the snapshot-able resources of workspaces.
+type WorkspaceSnapshot struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The workspace build that took the snapshot.
+	BuildID   uuid.UUID `db:"build_id" json:"build_id"`
+	Name      string    `db:"name" json:"name"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
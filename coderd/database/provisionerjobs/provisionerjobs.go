@@ -3,23 +3,29 @@ package provisionerjobs
 import (
 	"encoding/json"
 
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionertags"
 )
 
 const EventJobPosted = "provisioner_job_posted"
 
 type JobPosting struct {
-	ProvisionerType database.ProvisionerType `json:"type"`
-	Tags            map[string]string        `json:"tags"`
+	OrganizationID  uuid.UUID                     `json:"organization_id"`
+	ProvisionerType database.ProvisionerType      `json:"type"`
+	Tags            map[string]string             `json:"tags"`
+	TagRequirements []provisionertags.Requirement `json:"tag_requirements,omitempty"`
 }
 
 func PostJob(ps pubsub.Pubsub, job database.ProvisionerJob) error {
 	msg, err := json.Marshal(JobPosting{
+		OrganizationID:  job.OrganizationID,
 		ProvisionerType: job.Provisioner,
 		Tags:            job.Tags,
+		TagRequirements: job.TagRequirements,
 	})
 	if err != nil {
 		return xerrors.Errorf("marshal job posting: %w", err)
@@ -160,6 +160,53 @@ func (q *msgQueue) dropped() {
 	q.cond.Broadcast()
 }
 
+// eventQueues tracks the subscriber queues for each event. Callers must
+// synchronize access.
+type eventQueues map[string]map[uuid.UUID]*msgQueue
+
+// add registers the queue, returning its ID and whether it is the first
+// queue for the event.
+func (e eventQueues) add(event string, q *msgQueue) (id uuid.UUID, first bool) {
+	eventQs, ok := e[event]
+	if !ok {
+		eventQs = make(map[uuid.UUID]*msgQueue)
+		e[event] = eventQs
+	}
+	id = uuid.New()
+	eventQs[id] = q
+	return id, !ok
+}
+
+// remove closes and removes the queue, returning whether it was the last
+// queue for the event.
+func (e eventQueues) remove(event string, id uuid.UUID) (last bool) {
+	eventQs := e[event]
+	if q, ok := eventQs[id]; ok {
+		q.close()
+		delete(eventQs, id)
+	}
+	if len(eventQs) > 0 {
+		return false
+	}
+	delete(e, event)
+	return true
+}
+
+func (e eventQueues) enqueue(event string, msg []byte) {
+	for _, q := range e[event] {
+		q.enqueue(msg)
+	}
+}
+
+// dropped records that messages might have been dropped for every queue.
+func (e eventQueues) dropped() {
+	for _, eventQs := range e {
+		for _, q := range eventQs {
+			q.dropped()
+		}
+	}
+}
+
 // Pubsub implementation using PostgreSQL.
 type pgPubsub struct {
 	ctx              context.Context
@@ -1,10 +1,15 @@
 package pubsub
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/testutil"
@@ -138,3 +143,47 @@ func Test_msgQueue_Full(t *testing.T) {
 	// for the error, so we read 2 less than we sent.
 	require.Equal(t, BufferSize, n)
 }
+
+func Test_redisConn_do(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	conn := &redisConn{conn: client, r: bufio.NewReader(client)}
+
+	go func() {
+		// Consume the command before replying, since writes to a pipe block
+		// until they are read.
+		command := "*3\r\n$4\r\nECHO\r\n$1\r\na\r\n$0\r\n\r\n"
+		_, _ = io.ReadFull(server, make([]byte, len(command)))
+		_, _ = io.WriteString(server, "*2\r\n*2\r\n$3\r\nkey\r\n*1\r\n*2\r\n$3\r\n1-0\r\n*2\r\n$1\r\nm\r\n$5\r\nhello\r\n:7\r\n")
+		_, _ = io.ReadFull(server, make([]byte, len(command)))
+		_, _ = io.WriteString(server, "-ERR unknown command\r\n")
+	}()
+
+	reply, err := conn.do("ECHO", "a", "")
+	require.NoError(t, err)
+	require.Equal(t, []any{
+		[]any{[]byte("key"), []any{[]any{[]byte("1-0"), []any{[]byte("m"), []byte("hello")}}}},
+		int64(7),
+	}, reply)
+
+	_, err = conn.do("ECHO", "a", "")
+	var redisErr redisError
+	require.ErrorAs(t, err, &redisErr)
+	require.Equal(t, redisError("ERR unknown command"), redisErr)
+}
+
+func Test_natsSubject(t *testing.T) {
+	t.Parallel()
+
+	subject, err := natsSubject("workspace:" + uuid.NewString())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(subject, natsSubjectPrefix+"workspace:"))
+
+	for _, event := range []string{"", "has space", "wild*", "tail>"} {
+		_, err := natsSubject(event)
+		require.Error(t, err, event)
+	}
+}
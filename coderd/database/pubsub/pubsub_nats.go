@@ -0,0 +1,420 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+)
+
+const (
+	// natsSubjectPrefix namespaces the subjects used in NATS.
+	natsSubjectPrefix = "coder.pubsub."
+	// natsReadTimeout is how long the connection can be idle before it is
+	// considered dead. NATS servers ping clients every two minutes by
+	// default.
+	natsReadTimeout = 5 * time.Minute
+)
+
+// Pubsub implementation using core NATS. Each event is published to its own
+// subject.
+type natsPubsub struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   slog.Logger
+	url      *url.URL
+	runDone  chan struct{}
+	mut      sync.Mutex
+	queues   eventQueues
+	sids     map[string]int64
+	sidEvent map[int64]string
+	nextSID  int64
+
+	// connMut guards writes to conn, which is nil while reconnecting. It must
+	// not be locked before mut.
+	connMut    sync.Mutex
+	conn       *natsConn
+	maxPayload int64
+}
+
+// NewNATS creates a new Pubsub implementation using NATS. The URL is in the
+// form nats://[[user]:password@]host[:port], or tls:// for TLS. A username
+// without a password is used as a token.
+func NewNATS(ctx context.Context, logger slog.Logger, rawURL string) (Pubsub, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse nats url: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, xerrors.Errorf("nats url scheme must be nats or tls, got %q", u.Scheme)
+	}
+	conn, err := dialNATS(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start a new context that will be canceled when the pubsub is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &natsPubsub{
+		ctx:        ctx,
+		cancel:     cancel,
+		logger:     logger.Named("pubsub_nats"),
+		url:        u,
+		runDone:    make(chan struct{}),
+		queues:     make(eventQueues),
+		sids:       make(map[string]int64),
+		sidEvent:   make(map[int64]string),
+		conn:       conn,
+		maxPayload: conn.maxPayload,
+	}
+	go p.run(conn)
+	return p, nil
+}
+
+// Subscribe calls the listener when an event matching the name is received.
+func (p *natsPubsub) Subscribe(event string, listener Listener) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(p.ctx, listener, nil))
+}
+
+func (p *natsPubsub) SubscribeWithErr(event string, listener ListenerWithErr) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(p.ctx, nil, listener))
+}
+
+func (p *natsPubsub) subscribeQueue(event string, newQ *msgQueue) (cancel func(), err error) {
+	subject, err := natsSubject(event)
+	if err != nil {
+		newQ.close()
+		return nil, err
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	id, first := p.queues.add(event, newQ)
+	if first {
+		p.nextSID++
+		sid := p.nextSID
+		p.sids[event] = sid
+		p.sidEvent[sid] = event
+		// If we're disconnected, the subscription is sent on reconnect.
+		_ = p.write("SUB " + subject + " " + strconv.FormatInt(sid, 10) + "\r\n")
+	}
+	return func() {
+		p.mut.Lock()
+		defer p.mut.Unlock()
+		if !p.queues.remove(event, id) {
+			return
+		}
+		sid := p.sids[event]
+		delete(p.sids, event)
+		delete(p.sidEvent, sid)
+		_ = p.write("UNSUB " + strconv.FormatInt(sid, 10) + "\r\n")
+	}, nil
+}
+
+func (p *natsPubsub) Publish(event string, message []byte) error {
+	subject, err := natsSubject(event)
+	if err != nil {
+		return err
+	}
+	p.connMut.Lock()
+	maxPayload := p.maxPayload
+	p.connMut.Unlock()
+	if int64(len(message)) > maxPayload {
+		return xerrors.Errorf("message is %d bytes, larger than the server's maximum payload of %d bytes", len(message), maxPayload)
+	}
+	err = p.write("PUB " + subject + " " + strconv.Itoa(len(message)) + "\r\n" + string(message) + "\r\n")
+	if err != nil {
+		return xerrors.Errorf("publish: %w", err)
+	}
+	return nil
+}
+
+// write sends a protocol message on the current connection.
+func (p *natsPubsub) write(msg string) error {
+	p.connMut.Lock()
+	defer p.connMut.Unlock()
+	if p.conn == nil {
+		return xerrors.New("not connected to nats")
+	}
+	_, err := io.WriteString(p.conn.conn, msg)
+	return err
+}
+
+// Close closes the pubsub instance.
+func (p *natsPubsub) Close() error {
+	p.cancel()
+	<-p.runDone
+	return nil
+}
+
+// run reads from the connection until the pubsub is closed, reconnecting
+// whenever the connection is lost.
+func (p *natsPubsub) run(conn *natsConn) {
+	defer close(p.runDone)
+
+	for {
+		// Closing the connection interrupts a blocked read.
+		stop := context.AfterFunc(p.ctx, func() { _ = conn.Close() })
+		err := p.receive(conn)
+		stop()
+		_ = conn.Close()
+		p.connMut.Lock()
+		p.conn = nil
+		p.connMut.Unlock()
+		if p.ctx.Err() != nil {
+			return
+		}
+		p.logger.Warn(p.ctx, "lost connection to nats, reconnecting", slog.Error(err))
+		p.recordReconnect()
+
+		for {
+			conn, err = dialNATS(p.ctx, p.url)
+			if err == nil {
+				break
+			}
+			p.logger.Warn(p.ctx, "failed to connect to nats", slog.Error(err))
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+		p.resubscribe(conn)
+	}
+}
+
+// resubscribe makes the connection current and subscribes to every event
+// with subscribers.
+func (p *natsPubsub) resubscribe(conn *natsConn) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.connMut.Lock()
+	defer p.connMut.Unlock()
+	p.conn = conn
+	p.maxPayload = conn.maxPayload
+	for event, sid := range p.sids {
+		// Subjects were validated when subscribing.
+		subject, _ := natsSubject(event)
+		_, err := io.WriteString(conn.conn, "SUB "+subject+" "+strconv.FormatInt(sid, 10)+"\r\n")
+		if err != nil {
+			// The read loop notices the broken connection.
+			return
+		}
+	}
+}
+
+// receive handles protocol messages from the server until the connection
+// fails.
+func (p *natsPubsub) receive(conn *natsConn) error {
+	for {
+		_ = conn.conn.SetReadDeadline(time.Now().Add(natsReadTimeout))
+		line, err := conn.readLine()
+		if err != nil {
+			return err
+		}
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "MSG":
+			// MSG <subject> <sid> [reply-to] <size>
+			fields := strings.Fields(args)
+			if len(fields) < 3 {
+				return xerrors.Errorf("malformed message %q", line)
+			}
+			sid, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return xerrors.Errorf("malformed message %q", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil || size < 0 {
+				return xerrors.Errorf("malformed message %q", line)
+			}
+			payload := make([]byte, size+2)
+			_, err = io.ReadFull(conn.r, payload)
+			if err != nil {
+				return xerrors.Errorf("read message payload: %w", err)
+			}
+			p.receiveMessage(sid, payload[:size])
+		case "PING":
+			err = p.write("PONG\r\n")
+			if err != nil {
+				return err
+			}
+		case "-ERR":
+			// Fatal errors are followed by the server closing the
+			// connection.
+			p.logger.Warn(p.ctx, "nats server returned an error", slog.F("error", args))
+		}
+	}
+}
+
+func (p *natsPubsub) receiveMessage(sid int64, message []byte) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	event, ok := p.sidEvent[sid]
+	if !ok {
+		return
+	}
+	p.queues.enqueue(event, message)
+}
+
+func (p *natsPubsub) recordReconnect() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.queues.dropped()
+}
+
+// natsSubject returns the subject an event is published to. Subjects cannot
+// contain whitespace or wildcards.
+func natsSubject(event string) (string, error) {
+	if event == "" || strings.ContainsAny(event, " \t\r\n*>") {
+		return "", xerrors.Errorf("event %q cannot be used as a nats subject", event)
+	}
+	return natsSubjectPrefix + event, nil
+}
+
+// natsConn is a connection speaking the core NATS client protocol.
+type natsConn struct {
+	conn       net.Conn
+	r          *bufio.Reader
+	maxPayload int64
+}
+
+// natsServerInfo is sent by the server when a client connects.
+type natsServerInfo struct {
+	MaxPayload  int64 `json:"max_payload"`
+	TLSRequired bool  `json:"tls_required"`
+}
+
+// natsConnectOptions are sent by the client to finish connecting.
+type natsConnectOptions struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	Protocol  int    `json:"protocol"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// dialNATS connects and authenticates to the NATS server in the URL.
+func dialNATS(ctx context.Context, u *url.URL) (*natsConn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, xerrors.Errorf("dial nats: %w", err)
+	}
+	conn, err := handshakeNATS(ctx, netConn, u)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func handshakeNATS(ctx context.Context, netConn net.Conn, u *url.URL) (*natsConn, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	_ = netConn.SetDeadline(deadline)
+
+	conn := &natsConn{conn: netConn, r: bufio.NewReader(netConn)}
+	line, err := conn.readLine()
+	if err != nil {
+		return nil, xerrors.Errorf("read server info: %w", err)
+	}
+	rawInfo, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return nil, xerrors.Errorf("expected server info, got %q", line)
+	}
+	var info natsServerInfo
+	err = json.Unmarshal([]byte(rawInfo), &info)
+	if err != nil {
+		return nil, xerrors.Errorf("parse server info: %w", err)
+	}
+	conn.maxPayload = info.MaxPayload
+	if conn.maxPayload <= 0 {
+		// The default maximum payload of NATS servers.
+		conn.maxPayload = 1 << 20
+	}
+
+	if u.Scheme == "tls" || info.TLSRequired {
+		tlsConn := tls.Client(netConn, &tls.Config{
+			ServerName: u.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		})
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("tls handshake: %w", err)
+		}
+		conn.conn = tlsConn
+		conn.r = bufio.NewReader(tlsConn)
+	}
+
+	opts := natsConnectOptions{
+		Name:     "coderd-" + uuid.NewString(),
+		Lang:     "go",
+		Version:  buildinfo.Version(),
+		Protocol: 1,
+	}
+	if password, ok := u.User.Password(); ok {
+		opts.User = u.User.Username()
+		opts.Pass = password
+	} else if u.User != nil {
+		opts.AuthToken = u.User.Username()
+	}
+	rawOpts, err := json.Marshal(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal connect options: %w", err)
+	}
+	_, err = io.WriteString(conn.conn, "CONNECT "+string(rawOpts)+"\r\nPING\r\n")
+	if err != nil {
+		return nil, xerrors.Errorf("write connect: %w", err)
+	}
+	for {
+		line, err = conn.readLine()
+		if err != nil {
+			return nil, xerrors.Errorf("read connect response: %w", err)
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return nil, xerrors.Errorf("connect to nats: %s", line)
+		}
+		if line == "PONG" {
+			break
+		}
+	}
+
+	_ = conn.conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (c *natsConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *natsConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,129 @@
+package pubsub_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPubsubNATS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PublishSubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ps, err := pubsub.NewNATS(ctx, slogtest.Make(t, nil), "nats://token@"+fakeNATSServer(t))
+		require.NoError(t, err)
+		defer ps.Close()
+
+		messages := make(chan string, 1)
+		cancel, err := ps.SubscribeWithErr("workspace:test", func(_ context.Context, message []byte, err error) {
+			if err == nil {
+				messages <- string(message)
+			}
+		})
+		require.NoError(t, err)
+		defer cancel()
+
+		err = ps.Publish("workspace:test", []byte("hello"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", testutil.RequireRecvCtx(ctx, t, messages))
+	})
+
+	t.Run("InvalidEvent", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		ps, err := pubsub.NewNATS(ctx, slogtest.Make(t, nil), "nats://"+fakeNATSServer(t))
+		require.NoError(t, err)
+		defer ps.Close()
+
+		err = ps.Publish("no wildcards>", []byte("hello"))
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidScheme", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		_, err := pubsub.NewNATS(ctx, slogtest.Make(t, nil), "redis://localhost")
+		require.Error(t, err)
+	})
+}
+
+// fakeNATSServer serves enough of the NATS protocol to deliver published
+// messages back to subscribers on the same connection.
+func fakeNATSServer(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeNATS(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func serveFakeNATS(conn net.Conn) {
+	defer conn.Close()
+
+	var (
+		mut  sync.Mutex
+		sids = map[string]string{}
+	)
+	_, _ = io.WriteString(conn, `INFO {"max_payload":1048576}`+"\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch op {
+		case "PING":
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		case "SUB":
+			subject, sid, _ := strings.Cut(args, " ")
+			mut.Lock()
+			sids[subject] = sid
+			mut.Unlock()
+		case "PUB":
+			subject, rawSize, _ := strings.Cut(args, " ")
+			size, err := strconv.Atoi(rawSize)
+			if err != nil {
+				return
+			}
+			payload := make([]byte, size+2)
+			_, err = io.ReadFull(r, payload)
+			if err != nil {
+				return
+			}
+			mut.Lock()
+			sid, ok := sids[subject]
+			mut.Unlock()
+			if ok {
+				_, _ = io.WriteString(conn, "MSG "+subject+" "+sid+" "+rawSize+"\r\n"+string(payload))
+			}
+		}
+	}
+}
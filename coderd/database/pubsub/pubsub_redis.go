@@ -0,0 +1,456 @@
+package pubsub
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+)
+
+const (
+	// redisKeyPrefix namespaces the streams created in Redis.
+	redisKeyPrefix = "coder:pubsub:"
+	// redisStreamMaxLen bounds how many messages are kept for each event.
+	// Subscribers read messages as they arrive, so only enough history to
+	// cover a reconnect is needed.
+	redisStreamMaxLen = "1000"
+	// redisStreamTTL is how long an event's stream is kept after the last
+	// message was published to it.
+	redisStreamTTL = time.Hour
+	// redisReadBlock is how long a read waits for new messages before it is
+	// retried.
+	redisReadBlock = 5 * time.Second
+)
+
+// Pubsub implementation using Redis Streams. Each event is published to its
+// own stream, which every replica reads from while it has subscribers.
+type redisPubsub struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   slog.Logger
+	url      *url.URL
+	readDone chan struct{}
+	// wakeupKey is a stream only this replica reads from. Adding to it
+	// interrupts a blocked read so new subscriptions take effect right away.
+	wakeupKey string
+
+	// cmdMut guards cmdConn, which is used to publish.
+	cmdMut  sync.Mutex
+	cmdConn *redisConn
+
+	mut    sync.Mutex
+	queues eventQueues
+	// lastIDs is the ID of the last message read from each subscribed
+	// event's stream.
+	lastIDs  map[string]string
+	wakeupID string
+}
+
+// NewRedis creates a new Pubsub implementation using Redis Streams. The URL is
+// in the form redis://[[username]:password@]host[:port][/database], or
+// rediss:// for TLS. Redis Cluster is not supported.
+func NewRedis(ctx context.Context, logger slog.Logger, rawURL string) (Pubsub, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse redis url: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, xerrors.Errorf("redis url scheme must be redis or rediss, got %q", u.Scheme)
+	}
+	conn, err := dialRedis(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start a new context that will be canceled when the pubsub is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &redisPubsub{
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logger.Named("pubsub_redis"),
+		url:       u,
+		readDone:  make(chan struct{}),
+		wakeupKey: redisKeyPrefix + "wakeup:" + uuid.NewString(),
+		cmdConn:   conn,
+		queues:    make(eventQueues),
+		lastIDs:   make(map[string]string),
+		// The wakeup stream only ever holds its latest message, so reading
+		// it from the start only causes a single spurious wakeup.
+		wakeupID: "0-0",
+	}
+	go p.read()
+	return p, nil
+}
+
+// Subscribe calls the listener when an event matching the name is received.
+func (p *redisPubsub) Subscribe(event string, listener Listener) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(p.ctx, listener, nil))
+}
+
+func (p *redisPubsub) SubscribeWithErr(event string, listener ListenerWithErr) (cancel func(), err error) {
+	return p.subscribeQueue(event, newMsgQueue(p.ctx, nil, listener))
+}
+
+func (p *redisPubsub) subscribeQueue(event string, newQ *msgQueue) (cancel func(), err error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if _, ok := p.lastIDs[event]; !ok {
+		startID, err := p.wakeup()
+		if err != nil {
+			// Close the queue so we don't leak its goroutine.
+			newQ.close()
+			return nil, xerrors.Errorf("wake up reader: %w", err)
+		}
+		p.lastIDs[event] = startID
+	}
+	id, _ := p.queues.add(event, newQ)
+	return func() {
+		p.mut.Lock()
+		defer p.mut.Unlock()
+		if p.queues.remove(event, id) {
+			delete(p.lastIDs, event)
+		}
+	}, nil
+}
+
+// wakeup interrupts the blocked read so it starts reading new streams. It
+// returns an ID that only messages published after the call are greater
+// than.
+func (p *redisPubsub) wakeup() (string, error) {
+	reply, err := p.command("XADD", p.wakeupKey, "MAXLEN", "1", "*", "w", "1")
+	if err != nil {
+		return "", err
+	}
+	id, _ := reply.([]byte)
+	millis, _, ok := strings.Cut(string(id), "-")
+	if !ok {
+		return "", xerrors.Errorf("unexpected stream id %q", id)
+	}
+	ms, err := strconv.ParseUint(millis, 10, 64)
+	if err != nil || ms == 0 {
+		return "", xerrors.Errorf("unexpected stream id %q", id)
+	}
+	// Stream IDs are generated from the Redis clock, but a message published
+	// to another stream in the same millisecond may have a lower sequence
+	// number. Start from the end of the previous millisecond instead, at
+	// the cost of maybe delivering a message published just before.
+	return strconv.FormatUint(ms-1, 10) + "-18446744073709551615", nil
+}
+
+func (p *redisPubsub) Publish(event string, message []byte) error {
+	key := redisKeyPrefix + event
+	_, err := p.command("XADD", key, "MAXLEN", "~", redisStreamMaxLen, "*", "m", string(message))
+	if err != nil {
+		return xerrors.Errorf("xadd: %w", err)
+	}
+	_, err = p.command("EXPIRE", key, strconv.Itoa(int(redisStreamTTL.Seconds())))
+	if err != nil {
+		return xerrors.Errorf("expire: %w", err)
+	}
+	return nil
+}
+
+// command runs a command on the command connection, reconnecting if needed.
+func (p *redisPubsub) command(args ...string) (any, error) {
+	p.cmdMut.Lock()
+	defer p.cmdMut.Unlock()
+	if p.cmdConn == nil {
+		conn, err := dialRedis(p.ctx, p.url)
+		if err != nil {
+			return nil, err
+		}
+		p.cmdConn = conn
+	}
+	reply, err := p.cmdConn.do(args...)
+	var redisErr redisError
+	if err != nil && !xerrors.As(err, &redisErr) {
+		// The connection is in an unknown state, so start over next time.
+		_ = p.cmdConn.Close()
+		p.cmdConn = nil
+	}
+	return reply, err
+}
+
+// Close closes the pubsub instance.
+func (p *redisPubsub) Close() error {
+	p.cancel()
+	<-p.readDone
+
+	p.cmdMut.Lock()
+	defer p.cmdMut.Unlock()
+	if p.cmdConn == nil {
+		return nil
+	}
+	_, _ = p.cmdConn.do("DEL", p.wakeupKey)
+	err := p.cmdConn.Close()
+	p.cmdConn = nil
+	return err
+}
+
+// read receives messages from the streams with subscribers until the pubsub
+// is closed.
+func (p *redisPubsub) read() {
+	defer close(p.readDone)
+
+	var (
+		conn *redisConn
+		stop func() bool
+	)
+	defer func() {
+		if conn != nil {
+			stop()
+			_ = conn.Close()
+		}
+	}()
+	for p.ctx.Err() == nil {
+		if conn == nil {
+			var err error
+			conn, err = dialRedis(p.ctx, p.url)
+			if err != nil {
+				p.logger.Warn(p.ctx, "failed to connect to redis", slog.Error(err))
+				select {
+				case <-p.ctx.Done():
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			// Closing the connection interrupts a blocked read.
+			stop = context.AfterFunc(p.ctx, func() { _ = conn.Close() })
+		}
+
+		reply, err := conn.do(p.readArgs()...)
+		if err != nil {
+			if p.ctx.Err() != nil {
+				return
+			}
+			p.logger.Warn(p.ctx, "failed to read from redis, reconnecting", slog.Error(err))
+			stop()
+			_ = conn.Close()
+			conn = nil
+			p.recordReconnect()
+			continue
+		}
+		p.readReceive(reply)
+	}
+}
+
+// readArgs builds the command to read new messages from every subscribed
+// event's stream.
+func (p *redisPubsub) readArgs() []string {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	keys := make([]string, 0, len(p.lastIDs)+1)
+	ids := make([]string, 0, len(p.lastIDs)+1)
+	for event, id := range p.lastIDs {
+		keys = append(keys, redisKeyPrefix+event)
+		ids = append(ids, id)
+	}
+	keys = append(keys, p.wakeupKey)
+	ids = append(ids, p.wakeupID)
+
+	args := []string{
+		"XREAD", "COUNT", "100",
+		"BLOCK", strconv.FormatInt(redisReadBlock.Milliseconds(), 10),
+		"STREAMS",
+	}
+	args = append(args, keys...)
+	return append(args, ids...)
+}
+
+// readReceive delivers the messages from an XREAD reply, which is a list of
+// [key, [[id, [field, value, ...]], ...]] entries or nil if the read timed
+// out.
+func (p *redisPubsub) readReceive(reply any) {
+	streams, _ := reply.([]any)
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	for _, rawStream := range streams {
+		stream, _ := rawStream.([]any)
+		if len(stream) != 2 {
+			continue
+		}
+		key, _ := stream[0].([]byte)
+		entries, _ := stream[1].([]any)
+		for _, rawEntry := range entries {
+			entry, _ := rawEntry.([]any)
+			if len(entry) != 2 {
+				continue
+			}
+			id, _ := entry[0].([]byte)
+			if string(key) == p.wakeupKey {
+				p.wakeupID = string(id)
+				continue
+			}
+			event := strings.TrimPrefix(string(key), redisKeyPrefix)
+			if _, ok := p.lastIDs[event]; !ok {
+				// The last subscriber unsubscribed during the read.
+				continue
+			}
+			p.lastIDs[event] = string(id)
+			fields, _ := entry[1].([]any)
+			for i := 0; i+1 < len(fields); i += 2 {
+				if field, _ := fields[i].([]byte); string(field) == "m" {
+					message, _ := fields[i+1].([]byte)
+					p.queues.enqueue(event, message)
+				}
+			}
+		}
+	}
+}
+
+func (p *redisPubsub) recordReconnect() {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.queues.dropped()
+}
+
+// redisError is an error reply from Redis. The connection can still be used
+// after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a minimal client for the Redis serialization protocol.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialRedis connects to Redis, authenticating and selecting the database
+// from the URL.
+func dialRedis(ctx context.Context, u *url.URL) (*redisConn, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if u.Scheme == "rediss" {
+		dialer := &tls.Dialer{Config: &tls.Config{
+			ServerName: u.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		}}
+		netConn, err = dialer.DialContext(ctx, "tcp", host)
+	} else {
+		var dialer net.Dialer
+		netConn, err = dialer.DialContext(ctx, "tcp", host)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("dial redis: %w", err)
+	}
+	conn := &redisConn{conn: netConn, r: bufio.NewReader(netConn)}
+
+	if password, ok := u.User.Password(); ok {
+		args := []string{"AUTH", password}
+		if username := u.User.Username(); username != "" {
+			args = []string{"AUTH", username, password}
+		}
+		_, err = conn.do(args...)
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("authenticate to redis: %w", err)
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		_, err = conn.do("SELECT", db)
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("select redis database: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (c *redisConn) Close() error {
+	return c.conn.Close()
+}
+
+// do sends a command and reads its reply.
+func (c *redisConn) do(args ...string) (any, error) {
+	var buf bytes.Buffer
+	buf.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	_, err := c.conn.Write(buf.Bytes())
+	if err != nil {
+		return nil, xerrors.Errorf("write command: %w", err)
+	}
+	return c.readReply()
+}
+
+// readReply reads a reply. Simple strings and bulk strings are returned as
+// []byte, integers as int64, and arrays as []any.
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, xerrors.Errorf("read reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, xerrors.New("read reply: empty line")
+	}
+	switch line[0] {
+	case '+':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		n, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("parse integer reply: %w", err)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, xerrors.Errorf("parse bulk string length: %w", err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		_, err = io.ReadFull(c.r, data)
+		if err != nil {
+			return nil, xerrors.Errorf("read bulk string: %w", err)
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, xerrors.Errorf("parse array length: %w", err)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := c.readReply()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	default:
+		return nil, xerrors.Errorf("unexpected reply %q", line)
+	}
+}
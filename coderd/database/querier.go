@@ -17,6 +17,11 @@ type sqlcQuerier interface {
 	// This must be called from within a transaction. The lock will be automatically
 	// released when the transaction ends.
 	AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error
+	// Acquires notifications that are due to be sent. The next attempt is pushed
+	// back to lease_until so that a crashed replica does not hold on to the
+	// notification forever, and SKIP LOCKED prevents multiple replicas from
+	// acquiring the same notification.
+	AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error)
 	// Acquires the lock for a single job that isn't started, completed,
 	// canceled, and that matches an array of provisioner types.
 	//
@@ -24,6 +29,11 @@ type sqlcQuerier interface {
 	// multiple provisioners from acquiring the same jobs. See:
 	// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 	AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error)
+	// Acquires deliveries that are due to be attempted. The next attempt is
+	// pushed back to lease_until so that a crashed replica does not hold on to
+	// the delivery forever, and SKIP LOCKED prevents multiple replicas from
+	// acquiring the same delivery.
+	AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]WebhookDelivery, error)
 	// Bumps the workspace deadline by 1 hour. If the workspace bump will
 	// cross an autostart threshold, then the bump is autostart + TTL. This
 	// is the deadline behavior if the workspace was to autostart from a stopped
@@ -46,41 +56,92 @@ type sqlcQuerier interface {
 	CleanTailnetCoordinators(ctx context.Context) error
 	CleanTailnetLostPeers(ctx context.Context) error
 	CleanTailnetTunnels(ctx context.Context) error
+	// Counts the failed logins of the user from all IP addresses since the given
+	// time.
+	CountLoginFailuresByUserID(ctx context.Context, arg CountLoginFailuresByUserIDParams) (int64, error)
+	CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CustomRolesByName(ctx context.Context, lookupRoles []string) ([]CustomRole, error)
 	DeleteAPIKeyByID(ctx context.Context, id string) error
+	// DeleteAPIKeyByIDReturning returns the deleted key, so only one of several
+	// concurrent deletes of the same key succeeds.
+	DeleteAPIKeyByIDReturning(ctx context.Context, id string) (APIKey, error)
 	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	// DeleteAPIKeysByUserIDReturning returns the deleted keys, so each of them
+	// can be audited.
+	DeleteAPIKeysByUserIDReturning(ctx context.Context, userID uuid.UUID) ([]APIKey, error)
 	DeleteAllTailnetClientSubscriptions(ctx context.Context, arg DeleteAllTailnetClientSubscriptionsParams) error
 	DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
+	DeleteExpiredTwoFactorChallenges(ctx context.Context, beforeTime time.Time) error
 	DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
 	DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error
 	DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
+	DeleteLoginFailure(ctx context.Context, arg DeleteLoginFailureParams) error
 	DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error
+	// DeleteOAuth2ProviderAppCodeByID returns the deleted code, so only one of
+	// several concurrent deletes of the same code succeeds.
+	DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error)
 	DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error
+	DeleteOldGroupSyncRuns(ctx context.Context, beforeTime time.Time) error
+	DeleteOldLoginFailures(ctx context.Context, beforeTime time.Time) error
 	// Delete provisioner daemons that have been created at least a week ago
 	// and have not connected to coderd since a week.
 	// A provisioner daemon with "zeroed" last_seen_at column indicates possible
 	// connectivity issues (no provisioner daemon activity since registration).
 	DeleteOldProvisionerDaemons(ctx context.Context) error
+	DeleteOldRateLimitCounters(ctx context.Context, beforeTime time.Time) error
+	DeleteOldUserActivity(ctx context.Context, beforeTime time.Time) error
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
 	DeleteOldWorkspaceAgentLogs(ctx context.Context) error
+	// Deletes the metadata history of agents that stopped reporting, e.g. because
+	// their workspace was stopped.
+	DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, beforeTime time.Time) error
 	DeleteOldWorkspaceAgentStats(ctx context.Context) error
+	DeleteOldWorkspaceEvents(ctx context.Context, beforeTime time.Time) error
+	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error
+	DeleteOrganizationTemplateVariableValue(ctx context.Context, arg DeleteOrganizationTemplateVariableValueParams) error
+	DeletePasskeyByID(ctx context.Context, id uuid.UUID) error
+	DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error
+	DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
+	DeleteSessionRecordingByID(ctx context.Context, id uuid.UUID) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
 	DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error)
 	DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error
 	DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error)
 	DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error)
+	DeleteTemplateVariableValue(ctx context.Context, arg DeleteTemplateVariableValueParams) error
+	DeleteTemplateVersionPreset(ctx context.Context, arg DeleteTemplateVersionPresetParams) error
+	// Challenges are deleted when they are answered, so that each challenge can
+	// only be used once.
+	DeleteTwoFactorChallengeByID(ctx context.Context, id uuid.UUID) (TwoFactorChallenge, error)
+	DeleteTwoFactorPolicyByRole(ctx context.Context, role string) error
+	DeleteUnknownLoginFailure(ctx context.Context, arg DeleteUnknownLoginFailureParams) error
+	// Unlocks the user and forgets their failed logins, so that they aren't
+	// locked out again by their next failed attempt.
+	DeleteUserLockout(ctx context.Context, userID uuid.UUID) error
+	DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteWebhookByID(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error
+	DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx context.Context, agentID uuid.UUID) error
+	DeleteWorkspaceFavorite(ctx context.Context, arg DeleteWorkspaceFavoriteParams) error
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
 	// there is no unique constraint on empty token names
 	GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error)
 	GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error)
 	GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUserIDParams) ([]APIKey, error)
 	GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error)
+	// Sessions are the keys users get by signing in, as opposed to tokens and the
+	// keys of OAuth2 provider apps. Keys scoped to application_connect are derived
+	// from a session, so they aren't sessions of their own.
+	GetActiveSessionsByUserID(ctx context.Context, arg GetActiveSessionsByUserIDParams) ([]APIKey, error)
 	GetActiveUserCount(ctx context.Context) (int64, error)
 	GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error)
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
@@ -90,12 +151,17 @@ type sqlcQuerier interface {
 	GetAllTailnetTunnels(ctx context.Context) ([]TailnetTunnel, error)
 	GetAppSecurityKey(ctx context.Context) (string, error)
 	GetApplicationName(ctx context.Context) (string, error)
+	// GetAuditLogsKeyset returns audit logs a page at a time using the time and ID
+	// of the last log on the previous page as a cursor. Unlike GetAuditLogsOffset,
+	// it does not count the matching logs and does not slow down on later pages.
+	GetAuditLogsKeyset(ctx context.Context, arg GetAuditLogsKeysetParams) ([]GetAuditLogsKeysetRow, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error)
 	// This function returns roles for authorization purposes. Implied member roles
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
+	GetConnectionLogsOffset(ctx context.Context, arg GetConnectionLogsOffsetParams) ([]GetConnectionLogsOffsetRow, error)
 	GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error)
 	GetDERPMeshKey(ctx context.Context) (string, error)
 	GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error)
@@ -103,8 +169,13 @@ type sqlcQuerier interface {
 	GetDeploymentID(ctx context.Context) (string, error)
 	GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error)
 	GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploymentWorkspaceStatsRow, error)
+	GetDriftedWorkspaceCount(ctx context.Context) (int64, error)
+	// Returns the recordings past their retention, oldest first. Their objects must
+	// be deleted from the store before the rows are deleted.
+	GetExpiredSessionRecordings(ctx context.Context, arg GetExpiredSessionRecordingsParams) ([]SessionRecording, error)
 	GetExternalAuthLink(ctx context.Context, arg GetExternalAuthLinkParams) (ExternalAuthLink, error)
 	GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]ExternalAuthLink, error)
+	GetFavoriteWorkspaceIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error)
 	GetFileByID(ctx context.Context, id uuid.UUID) (File, error)
 	// Get all templates that use a file.
@@ -115,41 +186,91 @@ type sqlcQuerier interface {
 	// If the group is a user made group, then we need to check the group_members table.
 	// If it is the "Everyone" group, then we need to check the organization_members table.
 	GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error)
+	// Returns group sync runs, most recent first. If a user is given, only the
+	// runs that synced that user at login are returned.
+	GetGroupSyncRuns(ctx context.Context, arg GetGroupSyncRunsParams) ([]GroupSyncRun, error)
+	GetGroupsByOrganizationAndUserID(ctx context.Context, arg GetGroupsByOrganizationAndUserIDParams) ([]Group, error)
 	GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error)
 	GetHealthSettings(ctx context.Context) (string, error)
 	GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error)
+	// Returns the users that have not used Coder since the given time, least
+	// recently seen first, with their most recent recorded activity. Users created
+	// since then are not considered inactive, and suspended users are left out.
+	GetInactiveUsers(ctx context.Context, inactiveSince time.Time) ([]GetInactiveUsersRow, error)
+	GetInboxNotificationsByUserID(ctx context.Context, arg GetInboxNotificationsByUserIDParams) ([]InboxNotification, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
+	GetLatestWorkspaceAgentStatByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentStat, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
+	// Returns an empty string unless every license has expired and the read-only
+	// grace period has started.
+	GetLicenseGracePeriodStartedAt(ctx context.Context) (string, error)
 	GetLicenses(ctx context.Context) ([]License, error)
+	GetLoginFailure(ctx context.Context, arg GetLoginFailureParams) (LoginFailure, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	GetNotificationMessagesByUserID(ctx context.Context, arg GetNotificationMessagesByUserIDParams) ([]NotificationMessage, error)
+	GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error)
 	GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error)
+	GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error)
+	GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error)
 	GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error)
 	GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error)
+	GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error)
 	GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
 	GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error)
 	GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error)
+	GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]GetOrganizationMembersRow, error)
 	GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error)
+	GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error)
+	// Sums the recorded resource costs of the workspaces in an organization per
+	// owner, template and currency. Every record is the cost of a single day, so
+	// the sum is the total cost over the date range.
+	GetOrganizationResourceCostRollup(ctx context.Context, arg GetOrganizationResourceCostRollupParams) ([]GetOrganizationResourceCostRollupRow, error)
+	GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]OrganizationTemplateVariableValue, error)
 	GetOrganizations(ctx context.Context) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
+	// Returns running jobs acquired by provisioner daemons that have not sent a
+	// heartbeat since the given time.
+	GetOrphanedProvisionerJobs(ctx context.Context, staleSince time.Time) ([]ProvisionerJob, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	GetPasskeyByID(ctx context.Context, id uuid.UUID) (Passkey, error)
+	GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]Passkey, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
+	// Returns the daemons that can acquire jobs from the organization, including
+	// those that are not scoped to any organization.
+	GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error)
 	GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	// Returns completed jobs that still have logs in provisioner_job_logs, oldest
+	// first.
+	GetProvisionerJobIDsToArchiveLogs(ctx context.Context, arg GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error)
+	GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error)
+	GetProvisionerJobLogArchiveStats(ctx context.Context) (GetProvisionerJobLogArchiveStatsRow, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
+	GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error)
+	GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error)
+	GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error)
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
 	GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error)
 	GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
+	// Counts the running workspaces of an owner in an organization and of a
+	// template. A workspace is running if its latest build started it and didn't
+	// fail or get canceled. The given workspace isn't counted, so restarting or
+	// updating it isn't limited.
+	GetRunningWorkspaceCounts(ctx context.Context, arg GetRunningWorkspaceCountsParams) (GetRunningWorkspaceCountsRow, error)
 	GetServiceBanner(ctx context.Context) (string, error)
+	GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (SessionRecording, error)
+	GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]SessionRecording, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
 	GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error)
@@ -161,6 +282,11 @@ type sqlcQuerier interface {
 	GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error)
 	GetTemplateAppInsightsByTemplate(ctx context.Context, arg GetTemplateAppInsightsByTemplateParams) ([]GetTemplateAppInsightsByTemplateRow, error)
 	GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error)
+	// GetTemplateBuildInsights returns the number of finished workspace builds of a
+	// template in the given timeframe, how many of them failed, and the median and
+	// 95th percentile execution time in seconds of the successful ones. Canceled
+	// builds are not counted. The percentiles are -1 if no build succeeded.
+	GetTemplateBuildInsights(ctx context.Context, arg GetTemplateBuildInsightsParams) (GetTemplateBuildInsightsRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error)
@@ -179,17 +305,32 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateVariableValue, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error)
 	GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error)
 	GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error)
 	GetTemplates(ctx context.Context) ([]Template, error)
 	GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error)
+	// Returns running workspace builds that started before the given time minus
+	// the max build duration of their template.
+	GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]ProvisionerJob, error)
+	GetTwoFactorPolicies(ctx context.Context) ([]TwoFactorPolicy, error)
+	GetTwoFactorPolicyByRole(ctx context.Context, role string) (TwoFactorPolicy, error)
 	GetUnexpiredLicenses(ctx context.Context) ([]License, error)
+	GetUnknownLoginFailure(ctx context.Context, arg GetUnknownLoginFailureParams) (UnknownLoginFailure, error)
+	// Sums up the failed logins for an email or username that doesn't belong to a
+	// user from all IP addresses since the given time, and returns when the last
+	// one happened.
+	GetUnknownLoginFailureTotals(ctx context.Context, arg GetUnknownLoginFailureTotalsParams) (GetUnknownLoginFailureTotalsRow, error)
+	// Recovery codes are salted, so they can't be looked up by their hash. The
+	// code a user enters is compared with each of their unused codes instead.
+	GetUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]UserRecoveryCode, error)
 	// GetUserActivityInsights returns the ranking with top active users.
 	// The result can be filtered on template_ids, meaning only user data from workspaces
 	// based on those templates will be included.
@@ -209,19 +350,41 @@ type sqlcQuerier interface {
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
 	GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error)
+	GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLockout, error)
+	// Returns the users that are locked out at the given time.
+	GetUserLockouts(ctx context.Context, now time.Time) ([]GetUserLockoutsRow, error)
+	GetUserSCIMExternalIDByExternalID(ctx context.Context, externalID string) (UserSCIMExternalID, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	// This shouldn't check for deleted, because it's frequently used
 	// to look up references to actions. eg. a user could build a workspace
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error)
+	GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error)
+	GetWebhooks(ctx context.Context) ([]Webhook, error)
+	GetWebhooksByEvent(ctx context.Context, event string) ([]Webhook, error)
 	GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (GetWorkspaceAgentAndOwnerByAuthTokenRow, error)
+	// Locks the agent's row, so concurrent rotations of its token are serialized.
+	GetWorkspaceAgentAuthTokenByIDForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
 	GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error)
+	// Counts the agents in the latest build of workspaces that are not deleted by
+	// lifecycle state.
+	GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]GetWorkspaceAgentCountsByLifecycleStateRow, error)
+	GetWorkspaceAgentGPUsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentGPU, error)
 	GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error)
 	GetWorkspaceAgentLogSourcesByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentLogSource, error)
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorkspaceAgentMetadataParams) ([]WorkspaceAgentMetadatum, error)
+	// Returns the recorded values of the metadata of an agent collected after
+	// @since, oldest first. Without keys, the values of all metadata are returned.
+	GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]WorkspaceAgentMetadataHistory, error)
+	GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	GetWorkspaceAgentPortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error)
+	GetWorkspaceAgentPortsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentPort, error)
+	GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentResourceUsageAndLabelsRow, error)
 	GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
 	GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error)
@@ -229,6 +392,9 @@ type sqlcQuerier interface {
 	GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error)
+	// Counts the apps in the latest build of workspaces that are not deleted by
+	// health.
+	GetWorkspaceAppCountsByHealth(ctx context.Context) ([]GetWorkspaceAppCountsByHealthRow, error)
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
@@ -242,6 +408,16 @@ type sqlcQuerier interface {
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	// Counts the workspaces that are not deleted by template, organization and the
+	// status of their latest build.
+	GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]GetWorkspaceCountsByTemplateAndStatusRow, error)
+	GetWorkspaceDriftByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDrift, error)
+	// Returns the running workspaces that are due a drift check. A workspace is
+	// skipped while its latest build or its previous drift check has not finished,
+	// so only one plan runs against its state at a time, and never against state
+	// that a build is still changing.
+	GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error)
+	GetWorkspaceParameterChanges(ctx context.Context, arg GetWorkspaceParameterChangesParams) ([]GetWorkspaceParameterChangesRow, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -253,21 +429,34 @@ type sqlcQuerier interface {
 	GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error)
 	GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error)
 	GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error)
+	GetWorkspaceProxyHealth(ctx context.Context) ([]WorkspaceProxyHealth, error)
 	GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID) (WorkspaceResource, error)
+	GetWorkspaceResourceCosts(ctx context.Context, arg GetWorkspaceResourceCostsParams) ([]WorkspaceResourceCost, error)
 	GetWorkspaceResourceMetadataByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourceMetadataCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResourceMetadatum, error)
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error)
+	GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]GetWorkspaceSnapshotsByWorkspaceIDRow, error)
+	// Returns the timeline of a workspace, newest first. It's made up of the
+	// recorded workspace events plus the start and completion of every build.
+	GetWorkspaceTimeline(ctx context.Context, arg GetWorkspaceTimelineParams) ([]GetWorkspaceTimelineRow, error)
 	GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Context, templateIds []uuid.UUID) ([]GetWorkspaceUniqueOwnerCountByTemplateIDsRow, error)
 	GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]GetWorkspacesRow, error)
+	GetWorkspacesByIDs(ctx context.Context, ids []uuid.UUID) ([]Workspace, error)
 	GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]Workspace, error)
+	// Counts a request against the key for the window, returning the number of
+	// requests made in the window so far. The upsert is atomic, so replicas
+	// counting the same key concurrently never lose a request.
+	IncrementRateLimitCounter(ctx context.Context, arg IncrementRateLimitCounterParams) (int32, error)
 	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error)
 	// We use the organization_id as the id
 	// for simplicity since all users is
 	// every member of the org.
 	InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertConnectionLog(ctx context.Context, arg InsertConnectionLogParams) (ConnectionLog, error)
 	InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error
 	InsertDERPMeshKey(ctx context.Context, value string) error
 	InsertDeploymentID(ctx context.Context, value string) error
@@ -276,32 +465,50 @@ type sqlcQuerier interface {
 	InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error)
 	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
+	InsertGroupSyncRun(ctx context.Context, arg InsertGroupSyncRunParams) (GroupSyncRun, error)
+	InsertInboxNotification(ctx context.Context, arg InsertInboxNotificationParams) (InboxNotification, error)
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
 	// Inserts any group by name that does not exist. All new groups are given
 	// a random uuid, are inserted into the same organization. They have the default
 	// values for avatar, display name, and quota allowance (all zero values).
 	// If the name conflicts, do nothing.
 	InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error)
+	InsertNotificationMessage(ctx context.Context, arg InsertNotificationMessageParams) (NotificationMessage, error)
 	InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error)
+	InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error)
 	InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error)
+	InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
+	InsertPasskey(ctx context.Context, arg InsertPasskeyParams) (Passkey, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
+	InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) (ProvisionerJobLogArchive, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
+	InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
+	InsertSessionRecording(ctx context.Context, arg InsertSessionRecordingParams) (SessionRecording, error)
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
+	InsertTemplateVersionPreset(ctx context.Context, arg InsertTemplateVersionPresetParams) (TemplateVersionPreset, error)
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
+	InsertTwoFactorChallenge(ctx context.Context, arg InsertTwoFactorChallengeParams) (TwoFactorChallenge, error)
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
 	// InsertUserGroupsByName adds a user to all provided groups, if they exist.
 	InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
+	InsertUserRecoveryCode(ctx context.Context, arg InsertUserRecoveryCodeParams) error
+	InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error)
+	InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
 	InsertWorkspaceAgentLogSources(ctx context.Context, arg InsertWorkspaceAgentLogSourcesParams) ([]WorkspaceAgentLogSource, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
+	// Records the values of the metadata of an agent and deletes the oldest values
+	// of each metadatum, so that at most @keep values are kept. Values of keys the
+	// agent doesn't have are ignored, like in UpdateWorkspaceAgentMetadata.
+	InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error
 	InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error)
 	InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error)
 	InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error
@@ -309,10 +516,28 @@ type sqlcQuerier interface {
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceEvent(ctx context.Context, arg InsertWorkspaceEventParams) error
+	InsertWorkspaceFavorite(ctx context.Context, arg InsertWorkspaceFavoriteParams) error
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
+	InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error)
+	ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error)
+	// Counts a login attempt of the user from the IP address before the password
+	// is checked, and forgotten again if it succeeds. The count starts over if the
+	// previous attempt was before reset_before. Once the count reaches
+	// backoff_threshold, the attempt is only counted if the previous one was long
+	// enough ago, and no row is returned otherwise. Checking and counting in one
+	// statement keeps concurrent attempts from all getting through.
+	RecordLoginAttempt(ctx context.Context, arg RecordLoginAttemptParams) (LoginFailure, error)
+	// Counts a login attempt for an email or username that doesn't belong to a
+	// user, or for a directory login, like RecordLoginAttempt. No row is returned
+	// while the IP address is backing off.
+	RecordUnknownLoginAttempt(ctx context.Context, arg RecordUnknownLoginAttemptParams) (UnknownLoginFailure, error)
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
+	// Returns a running job to the queue, so it is acquired by another
+	// provisioner daemon.
+	RequeueProvisionerJobByID(ctx context.Context, arg RequeueProvisionerJobByIDParams) error
 	RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error
 	// Non blocking lock. Returns true if the lock was acquired, false otherwise.
 	//
@@ -326,27 +551,49 @@ type sqlcQuerier interface {
 	UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error)
 	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
 	UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error)
+	// Suspends the users that have not used Coder since the given time. Owners are
+	// never suspended, so the deployment can't be locked out.
+	UpdateInactiveUsersToSuspended(ctx context.Context, arg UpdateInactiveUsersToSuspendedParams) ([]UpdateInactiveUsersToSuspendedRow, error)
+	// Marks the listed notifications of the user as read, or all of them if none
+	// are listed.
+	UpdateInboxNotificationsReadByUserID(ctx context.Context, arg UpdateInboxNotificationsReadByUserIDParams) error
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
+	UpdateNotificationMessageByID(ctx context.Context, arg UpdateNotificationMessageByIDParams) error
 	UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error)
 	UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error)
+	UpdatePasskeyUsage(ctx context.Context, arg UpdatePasskeyUsageParams) error
 	UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
+	UpdateProvisionerJobPriorityByID(ctx context.Context, arg UpdateProvisionerJobPriorityByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
+	// Replaces the secret of the key. Daemons authenticated with the old secret
+	// are disconnected.
+	UpdateProvisionerKeySecretByID(ctx context.Context, arg UpdateProvisionerKeySecretByIDParams) (ProvisionerKey, error)
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
+	UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) error
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
 	UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
+	UpdateTemplateBuildRetryPolicyByID(ctx context.Context, arg UpdateTemplateBuildRetryPolicyByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
+	UpdateTemplateMaintenanceWindowByID(ctx context.Context, arg UpdateTemplateMaintenanceWindowByIDParams) error
+	UpdateTemplateMaxBuildDurationByID(ctx context.Context, arg UpdateTemplateMaxBuildDurationByIDParams) error
+	UpdateTemplateMaxRunningWorkspacesByID(ctx context.Context, arg UpdateTemplateMaxRunningWorkspacesByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
+	UpdateTemplatePortForwardingPolicyByID(ctx context.Context, arg UpdateTemplatePortForwardingPolicyByIDParams) error
 	UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error
+	UpdateTemplateSessionRecordingByID(ctx context.Context, arg UpdateTemplateSessionRecordingByIDParams) error
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
 	UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error
+	UpdateTemplateVisibilityByID(ctx context.Context, arg UpdateTemplateVisibilityByIDParams) error
+	UpdateTemplateWorkspaceNamePolicyByID(ctx context.Context, arg UpdateTemplateWorkspaceNamePolicyByIDParams) error
 	UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg UpdateTemplateWorkspacesLastUsedAtParams) error
 	UpdateUserAppearanceSettings(ctx context.Context, arg UpdateUserAppearanceSettingsParams) (User, error)
 	UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error
 	UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error
+	UpdateUserIPAllowlist(ctx context.Context, arg UpdateUserIPAllowlistParams) (User, error)
 	UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error)
 	UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error)
 	UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error)
@@ -355,7 +602,11 @@ type sqlcQuerier interface {
 	UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error)
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
+	UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error
+	UpdateWebhookSecretByID(ctx context.Context, arg UpdateWebhookSecretByIDParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error
+	UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
 	UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error
@@ -367,9 +618,17 @@ type sqlcQuerier interface {
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
 	UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error
 	UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error
+	// Schedules a failed build to be retried, or clears the schedule with NULL.
+	UpdateWorkspaceBuildRetryAtByID(ctx context.Context, arg UpdateWorkspaceBuildRetryAtByIDParams) error
 	UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateWorkspaceDeletedByIDParams) error
+	UpdateWorkspaceDormancyExempt(ctx context.Context, arg UpdateWorkspaceDormancyExemptParams) error
 	UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg UpdateWorkspaceDormantDeletingAtParams) (Workspace, error)
+	// Records the result of a drift check. Dry-runs that are not the latest drift
+	// check of the workspace are ignored.
+	UpdateWorkspaceDriftResult(ctx context.Context, arg UpdateWorkspaceDriftResultParams) error
 	UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWorkspaceLastUsedAtParams) error
+	UpdateWorkspaceMaintenanceOptOut(ctx context.Context, arg UpdateWorkspaceMaintenanceOptOutParams) error
+	UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) (Workspace, error)
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
@@ -377,14 +636,19 @@ type sqlcQuerier interface {
 	UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesDormantDeletingAtByTemplateIDParams) error
 	UpsertAppSecurityKey(ctx context.Context, value string) error
 	UpsertApplicationName(ctx context.Context, value string) error
+	UpsertCustomRole(ctx context.Context, arg UpsertCustomRoleParams) (CustomRole, error)
 	// The default proxy is implied and not actually stored in the database.
 	// So we need to store it's configuration here for display purposes.
 	// The functional values are immutable and controlled implicitly.
 	UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error
 	UpsertHealthSettings(ctx context.Context, value string) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
+	UpsertLicenseGracePeriodStartedAt(ctx context.Context, value string) error
 	UpsertLogoURL(ctx context.Context, value string) error
+	UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error)
 	UpsertOAuthSigningKey(ctx context.Context, value string) error
+	UpsertOrganizationQuota(ctx context.Context, arg UpsertOrganizationQuotaParams) (OrganizationQuota, error)
+	UpsertOrganizationTemplateVariableValue(ctx context.Context, arg UpsertOrganizationTemplateVariableValueParams) (OrganizationTemplateVariableValue, error)
 	UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error)
 	UpsertServiceBanner(ctx context.Context, value string) error
 	UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error)
@@ -393,6 +657,31 @@ type sqlcQuerier interface {
 	UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error)
 	UpsertTailnetPeer(ctx context.Context, arg UpsertTailnetPeerParams) (TailnetPeer, error)
 	UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetTunnelParams) (TailnetTunnel, error)
+	UpsertTemplateVariableValue(ctx context.Context, arg UpsertTemplateVariableValueParams) (TemplateVariableValue, error)
+	UpsertTwoFactorPolicy(ctx context.Context, arg UpsertTwoFactorPolicyParams) (TwoFactorPolicy, error)
+	// Adds request counts to the hourly totals of each user, API category and
+	// workspace. Every (user, hour, category, workspace) must appear at most once.
+	UpsertUserActivity(ctx context.Context, arg UpsertUserActivityParams) error
+	UpsertUserLockout(ctx context.Context, arg UpsertUserLockoutParams) (UserLockout, error)
+	UpsertUserSCIMExternalID(ctx context.Context, arg UpsertUserSCIMExternalIDParams) (UserSCIMExternalID, error)
+	// Replaces the listening ports of an agent. Ports that are still listening
+	// keep the time they were first discovered.
+	// Replaces the GPUs of an agent with the ones it detected on startup.
+	UpsertWorkspaceAgentGPUs(ctx context.Context, arg UpsertWorkspaceAgentGPUsParams) error
+	UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error)
+	UpsertWorkspaceAgentPorts(ctx context.Context, arg UpsertWorkspaceAgentPortsParams) error
+	UpsertWorkspaceAgentPreviousAuthToken(ctx context.Context, arg UpsertWorkspaceAgentPreviousAuthTokenParams) error
+	// Starts a new drift check of a workspace. The result of the previous check
+	// is kept until the new one completes.
+	UpsertWorkspaceDriftJob(ctx context.Context, arg UpsertWorkspaceDriftJobParams) error
+	UpsertWorkspaceProxyHealth(ctx context.Context, arg UpsertWorkspaceProxyHealthParams) error
+	// Records the daily cost of the resources of the latest build of every
+	// workspace that is not deleted. Recording again on the same date replaces
+	// the earlier record, so the last known cost of a day wins.
+	UpsertWorkspaceResourceCosts(ctx context.Context, arg UpsertWorkspaceResourceCostsParams) error
+	// Marks an unused recovery code of the user as used. No rows are returned if
+	// the user has no such code, or if it was already used.
+	UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (UserRecoveryCode, error)
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
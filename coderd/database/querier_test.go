@@ -394,6 +394,70 @@ func TestQueuePosition(t *testing.T) {
 	}
 }
 
+func TestQueuePriority(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.SkipNow()
+	}
+	sqlDB := testSQLDB(t)
+	err := migrations.Up(sqlDB)
+	require.NoError(t, err)
+	db := database.New(sqlDB)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	now := dbtime.Now()
+	newJob := func(jobType database.ProvisionerJobType, createdAt time.Time) database.ProvisionerJob {
+		return dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Type:           jobType,
+			CreatedAt:      createdAt,
+			Tags:           database.StringMap{},
+		})
+	}
+	oldImport := newJob(database.ProvisionerJobTypeTemplateVersionImport, now.Add(-3*time.Minute))
+	bumpedImport := newJob(database.ProvisionerJobTypeTemplateVersionImport, now.Add(-2*time.Minute))
+	build := newJob(database.ProvisionerJobTypeWorkspaceBuild, now.Add(-time.Minute))
+
+	err = db.UpdateProvisionerJobPriorityByID(ctx, database.UpdateProvisionerJobPriorityByIDParams{
+		ID:        bumpedImport.ID,
+		Priority:  1,
+		UpdatedAt: now,
+	})
+	require.NoError(t, err)
+
+	// Bumped jobs come first, then workspace builds, then everything else.
+	expected := []uuid.UUID{bumpedImport.ID, build.ID, oldImport.ID}
+	queued, err := db.GetProvisionerJobsByIDsWithQueuePosition(ctx, expected)
+	require.NoError(t, err)
+	require.Len(t, queued, len(expected))
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].QueuePosition < queued[j].QueuePosition
+	})
+	for index, job := range queued {
+		require.Equal(t, expected[index], job.ProvisionerJob.ID)
+		require.Equal(t, int64(index+1), job.QueuePosition)
+	}
+
+	for _, id := range expected {
+		job, err := db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			StartedAt: sql.NullTime{
+				Time:  dbtime.Now(),
+				Valid: true,
+			},
+			Types: database.AllProvisionerTypeValues(),
+			WorkerID: uuid.NullUUID{
+				UUID:  uuid.New(),
+				Valid: true,
+			},
+			Tags: json.RawMessage("{}"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, id, job.ID)
+	}
+}
+
 func TestUserLastSeenFilter(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
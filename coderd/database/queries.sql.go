@@ -111,6 +111,39 @@ func (q *sqlQuerier) DeleteAPIKeyByID(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteAPIKeyByIDReturning = `-- name: DeleteAPIKeyByIDReturning :one
+DELETE FROM
+	api_keys
+WHERE
+	id = $1
+RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
+`
+
+// DeleteAPIKeyByIDReturning returns the deleted key, so only one of several
+// concurrent deletes of the same key succeeds.
+func (q *sqlQuerier) DeleteAPIKeyByIDReturning(ctx context.Context, id string) (APIKey, error) {
+	row := q.db.QueryRowContext(ctx, deleteAPIKeyByIDReturning, id)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.LastUsed,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LoginType,
+		&i.LifetimeSeconds,
+		&i.IPAddress,
+		&i.Scope,
+		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowedWorkspaceIDs),
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
+}
+
 const deleteAPIKeysByUserID = `-- name: DeleteAPIKeysByUserID :exec
 DELETE FROM
 	api_keys
@@ -123,6 +156,55 @@ func (q *sqlQuerier) DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID
 	return err
 }
 
+const deleteAPIKeysByUserIDReturning = `-- name: DeleteAPIKeysByUserIDReturning :many
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1
+RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
+`
+
+// DeleteAPIKeysByUserIDReturning returns the deleted keys, so each of them
+// can be audited.
+func (q *sqlQuerier) DeleteAPIKeysByUserIDReturning(ctx context.Context, userID uuid.UUID) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, deleteAPIKeysByUserIDReturning, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowedWorkspaceIDs),
+			pq.Array(&i.IPAllowlist),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteApplicationConnectAPIKeysByUserID = `-- name: DeleteApplicationConnectAPIKeysByUserID :exec
 DELETE FROM
 	api_keys
@@ -138,7 +220,7 @@ func (q *sqlQuerier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context
 
 const getAPIKeyByID = `-- name: GetAPIKeyByID :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
 FROM
 	api_keys
 WHERE
@@ -163,13 +245,16 @@ func (q *sqlQuerier) GetAPIKeyByID(ctx context.Context, id string) (APIKey, erro
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowedWorkspaceIDs),
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
 const getAPIKeyByName = `-- name: GetAPIKeyByName :one
 SELECT
-	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
 FROM
 	api_keys
 WHERE
@@ -202,12 +287,15 @@ func (q *sqlQuerier) GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNamePar
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowedWorkspaceIDs),
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
 const getAPIKeysByLoginType = `-- name: GetAPIKeysByLoginType :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist FROM api_keys WHERE login_type = $1
 `
 
 func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginType) ([]APIKey, error) {
@@ -232,6 +320,9 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowedWorkspaceIDs),
+			pq.Array(&i.IPAllowlist),
 		); err != nil {
 			return nil, err
 		}
@@ -247,7 +338,7 @@ func (q *sqlQuerier) GetAPIKeysByLoginType(ctx context.Context, loginType LoginT
 }
 
 const getAPIKeysByUserID = `-- name: GetAPIKeysByUserID :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE login_type = $1 AND user_id = $2
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist FROM api_keys WHERE login_type = $1 AND user_id = $2
 `
 
 type GetAPIKeysByUserIDParams struct {
@@ -277,6 +368,9 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowedWorkspaceIDs),
+			pq.Array(&i.IPAllowlist),
 		); err != nil {
 			return nil, err
 		}
@@ -292,7 +386,7 @@ func (q *sqlQuerier) GetAPIKeysByUserID(ctx context.Context, arg GetAPIKeysByUse
 }
 
 const getAPIKeysLastUsedAfter = `-- name: GetAPIKeysLastUsedAfter :many
-SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name FROM api_keys WHERE last_used > $1
+SELECT id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist FROM api_keys WHERE last_used > $1
 `
 
 func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.Time) ([]APIKey, error) {
@@ -317,6 +411,70 @@ func (q *sqlQuerier) GetAPIKeysLastUsedAfter(ctx context.Context, lastUsed time.
 			&i.IPAddress,
 			&i.Scope,
 			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowedWorkspaceIDs),
+			pq.Array(&i.IPAllowlist),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveSessionsByUserID = `-- name: GetActiveSessionsByUserID :many
+SELECT
+	id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
+FROM
+	api_keys
+WHERE
+	user_id = $1 AND
+	login_type IN ('password'::login_type, 'github'::login_type, 'oidc'::login_type, 'ldap'::login_type) AND
+	scope = 'all'::api_key_scope AND
+	expires_at > $2 :: timestamptz
+ORDER BY
+	created_at DESC
+`
+
+type GetActiveSessionsByUserIDParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Now    time.Time `db:"now" json:"now"`
+}
+
+// Sessions are the keys users get by signing in, as opposed to tokens and the
+// keys of OAuth2 provider apps. Keys scoped to application_connect are derived
+// from a session, so they aren't sessions of their own.
+func (q *sqlQuerier) GetActiveSessionsByUserID(ctx context.Context, arg GetActiveSessionsByUserIDParams) ([]APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveSessionsByUserID, arg.UserID, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.HashedSecret,
+			&i.UserID,
+			&i.LastUsed,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LoginType,
+			&i.LifetimeSeconds,
+			&i.IPAddress,
+			&i.Scope,
+			&i.TokenName,
+			pq.Array(&i.Scopes),
+			pq.Array(&i.AllowedWorkspaceIDs),
+			pq.Array(&i.IPAllowlist),
 		); err != nil {
 			return nil, err
 		}
@@ -345,7 +503,10 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scopes,
+		allowed_workspace_ids,
+		ip_allowlist
 	)
 VALUES
 	($1,
@@ -354,22 +515,27 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE $2::bigint
 	 END
-	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name
+	 , $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+	 -- Callers that don't use fine-grained scopes may pass NULL.
+	 COALESCE($13::text[], '{}'), COALESCE($14::uuid[], '{}'), COALESCE($15::text[], '{}')) RETURNING id, hashed_secret, user_id, last_used, expires_at, created_at, updated_at, login_type, lifetime_seconds, ip_address, scope, token_name, scopes, allowed_workspace_ids, ip_allowlist
 `
 
 type InsertAPIKeyParams struct {
-	ID              string      `db:"id" json:"id"`
-	LifetimeSeconds int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
-	HashedSecret    []byte      `db:"hashed_secret" json:"hashed_secret"`
-	IPAddress       pqtype.Inet `db:"ip_address" json:"ip_address"`
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	LastUsed        time.Time   `db:"last_used" json:"last_used"`
-	ExpiresAt       time.Time   `db:"expires_at" json:"expires_at"`
-	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
-	LoginType       LoginType   `db:"login_type" json:"login_type"`
-	Scope           APIKeyScope `db:"scope" json:"scope"`
-	TokenName       string      `db:"token_name" json:"token_name"`
+	ID                  string      `db:"id" json:"id"`
+	LifetimeSeconds     int64       `db:"lifetime_seconds" json:"lifetime_seconds"`
+	HashedSecret        []byte      `db:"hashed_secret" json:"hashed_secret"`
+	IPAddress           pqtype.Inet `db:"ip_address" json:"ip_address"`
+	UserID              uuid.UUID   `db:"user_id" json:"user_id"`
+	LastUsed            time.Time   `db:"last_used" json:"last_used"`
+	ExpiresAt           time.Time   `db:"expires_at" json:"expires_at"`
+	CreatedAt           time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time   `db:"updated_at" json:"updated_at"`
+	LoginType           LoginType   `db:"login_type" json:"login_type"`
+	Scope               APIKeyScope `db:"scope" json:"scope"`
+	TokenName           string      `db:"token_name" json:"token_name"`
+	Scopes              []string    `db:"scopes" json:"scopes"`
+	AllowedWorkspaceIDs []uuid.UUID `db:"allowed_workspace_ids" json:"allowed_workspace_ids"`
+	IPAllowlist         []string    `db:"ip_allowlist" json:"ip_allowlist"`
 }
 
 func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error) {
@@ -386,6 +552,9 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		arg.LoginType,
 		arg.Scope,
 		arg.TokenName,
+		pq.Array(arg.Scopes),
+		pq.Array(arg.AllowedWorkspaceIDs),
+		pq.Array(arg.IPAllowlist),
 	)
 	var i APIKey
 	err := row.Scan(
@@ -401,6 +570,9 @@ func (q *sqlQuerier) InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (
 		&i.IPAddress,
 		&i.Scope,
 		&i.TokenName,
+		pq.Array(&i.Scopes),
+		pq.Array(&i.AllowedWorkspaceIDs),
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
@@ -433,7 +605,7 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
-const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
+const getAuditLogsKeyset = `-- name: GetAuditLogsKeyset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
     users.username AS user_username,
@@ -441,8 +613,7 @@ SELECT
     users.created_at AS user_created_at,
     users.status AS user_status,
     users.rbac_roles AS user_roles,
-    users.avatar_url AS user_avatar_url,
-    COUNT(audit_logs.*) OVER () AS count
+    users.avatar_url AS user_avatar_url
 FROM
     audit_logs
     LEFT JOIN users ON audit_logs.user_id = users.id
@@ -473,75 +644,79 @@ FROM
 WHERE
     -- Filter resource_type
 	CASE
-		WHEN $3 :: text != '' THEN
-			resource_type = $3 :: resource_type
+		WHEN $1 :: text != '' THEN
+			resource_type = $1 :: resource_type
 		ELSE true
 	END
 	-- Filter resource_id
 	AND CASE
-		WHEN $4 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			resource_id = $4
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			resource_id = $2
 		ELSE true
 	END
 	-- Filter by resource_target
 	AND CASE
-		WHEN $5 :: text != '' THEN
-			resource_target = $5
+		WHEN $3 :: text != '' THEN
+			resource_target = $3
 		ELSE true
 	END
 	-- Filter action
 	AND CASE
-		WHEN $6 :: text != '' THEN
-			action = $6 :: audit_action
+		WHEN $4 :: text != '' THEN
+			action = $4 :: audit_action
 		ELSE true
 	END
 	-- Filter by user_id
 	AND CASE
-		WHEN $7 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			user_id = $7
+		WHEN $5 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $5
 		ELSE true
 	END
 	-- Filter by username
 	AND CASE
-		WHEN $8 :: text != '' THEN
-			user_id = (SELECT id FROM users WHERE lower(username) = lower($8) AND deleted = false)
+		WHEN $6 :: text != '' THEN
+			user_id = (SELECT id FROM users WHERE lower(username) = lower($6) AND deleted = false)
 		ELSE true
 	END
 	-- Filter by user_email
 	AND CASE
-		WHEN $9 :: text != '' THEN
-			users.email = $9
+		WHEN $7 :: text != '' THEN
+			users.email = $7
 		ELSE true
 	END
 	-- Filter by date_from
 	AND CASE
-		WHEN $10 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			"time" >= $10
+		WHEN $8 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= $8
 		ELSE true
 	END
 	-- Filter by date_to
 	AND CASE
-		WHEN $11 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			"time" <= $11
+		WHEN $9 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" <= $9
 		ELSE true
 	END
     -- Filter by build_reason
     AND CASE
-	    WHEN $12::text != '' THEN
-            workspace_builds.reason::text = $12
+	    WHEN $10::text != '' THEN
+            workspace_builds.reason::text = $10
         ELSE true
     END
+	-- Only return logs older than the cursor. The cursor is the time and ID of
+	-- the last log on the previous page.
+	AND CASE
+		WHEN $11 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			("time", audit_logs.id) < ($12 :: timestamp with time zone, $11 :: uuid)
+		ELSE true
+	END
 ORDER BY
-    "time" DESC
+    "time" DESC,
+    audit_logs.id DESC
 LIMIT
-    $1
-OFFSET
-    $2
+    $13 :: int
 `
 
-type GetAuditLogsOffsetParams struct {
-	Limit          int32     `db:"limit" json:"limit"`
-	Offset         int32     `db:"offset" json:"offset"`
+type GetAuditLogsKeysetParams struct {
 	ResourceType   string    `db:"resource_type" json:"resource_type"`
 	ResourceID     uuid.UUID `db:"resource_id" json:"resource_id"`
 	ResourceTarget string    `db:"resource_target" json:"resource_target"`
@@ -552,9 +727,12 @@ type GetAuditLogsOffsetParams struct {
 	DateFrom       time.Time `db:"date_from" json:"date_from"`
 	DateTo         time.Time `db:"date_to" json:"date_to"`
 	BuildReason    string    `db:"build_reason" json:"build_reason"`
+	CursorID       uuid.UUID `db:"cursor_id" json:"cursor_id"`
+	CursorTime     time.Time `db:"cursor_time" json:"cursor_time"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-type GetAuditLogsOffsetRow struct {
+type GetAuditLogsKeysetRow struct {
 	ID               uuid.UUID       `db:"id" json:"id"`
 	Time             time.Time       `db:"time" json:"time"`
 	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
@@ -576,15 +754,13 @@ type GetAuditLogsOffsetRow struct {
 	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
 	UserRoles        pq.StringArray  `db:"user_roles" json:"user_roles"`
 	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
-	Count            int64           `db:"count" json:"count"`
 }
 
-// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
-// ID.
-func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAuditLogsOffset,
-		arg.Limit,
-		arg.Offset,
+// GetAuditLogsKeyset returns audit logs a page at a time using the time and ID
+// of the last log on the previous page as a cursor. Unlike GetAuditLogsOffset,
+// it does not count the matching logs and does not slow down on later pages.
+func (q *sqlQuerier) GetAuditLogsKeyset(ctx context.Context, arg GetAuditLogsKeysetParams) ([]GetAuditLogsKeysetRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsKeyset,
 		arg.ResourceType,
 		arg.ResourceID,
 		arg.ResourceTarget,
@@ -595,14 +771,17 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 		arg.DateFrom,
 		arg.DateTo,
 		arg.BuildReason,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.LimitOpt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAuditLogsOffsetRow
+	var items []GetAuditLogsKeysetRow
 	for rows.Next() {
-		var i GetAuditLogsOffsetRow
+		var i GetAuditLogsKeysetRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Time,
@@ -625,7 +804,6 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 			&i.UserStatus,
 			&i.UserRoles,
 			&i.UserAvatarUrl,
-			&i.Count,
 		); err != nil {
 			return nil, err
 		}
@@ -640,106 +818,3283 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 	return items, nil
 }
 
-const insertAuditLog = `-- name: InsertAuditLog :one
-INSERT INTO
-	audit_logs (
-        id,
-        "time",
-        user_id,
-        organization_id,
-        ip,
-        user_agent,
-        resource_type,
-        resource_id,
-        resource_target,
-        action,
-        diff,
-        status_code,
-        additional_fields,
-        request_id,
-        resource_icon
-    )
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
-`
-
-type InsertAuditLogParams struct {
-	ID               uuid.UUID       `db:"id" json:"id"`
-	Time             time.Time       `db:"time" json:"time"`
-	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
-	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Ip               pqtype.Inet     `db:"ip" json:"ip"`
-	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
-	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
-	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
-	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
-	Action           AuditAction     `db:"action" json:"action"`
-	Diff             json.RawMessage `db:"diff" json:"diff"`
-	StatusCode       int32           `db:"status_code" json:"status_code"`
-	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
-	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
-	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
-}
-
-func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error) {
-	row := q.db.QueryRowContext(ctx, insertAuditLog,
-		arg.ID,
-		arg.Time,
-		arg.UserID,
-		arg.OrganizationID,
-		arg.Ip,
-		arg.UserAgent,
-		arg.ResourceType,
-		arg.ResourceID,
-		arg.ResourceTarget,
-		arg.Action,
-		arg.Diff,
-		arg.StatusCode,
-		arg.AdditionalFields,
-		arg.RequestID,
-		arg.ResourceIcon,
-	)
-	var i AuditLog
-	err := row.Scan(
-		&i.ID,
-		&i.Time,
-		&i.UserID,
-		&i.OrganizationID,
-		&i.Ip,
-		&i.UserAgent,
-		&i.ResourceType,
-		&i.ResourceID,
-		&i.ResourceTarget,
-		&i.Action,
-		&i.Diff,
-		&i.StatusCode,
-		&i.AdditionalFields,
-		&i.RequestID,
-		&i.ResourceIcon,
-	)
-	return i, err
-}
-
-const getDBCryptKeys = `-- name: GetDBCryptKeys :many
-SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
-`
-
-func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
-	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []DBCryptKey
-	for rows.Next() {
-		var i DBCryptKey
-		if err := rows.Scan(
-			&i.Number,
-			&i.ActiveKeyDigest,
+const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
+SELECT
+    audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
+    users.username AS user_username,
+    users.email AS user_email,
+    users.created_at AS user_created_at,
+    users.status AS user_status,
+    users.rbac_roles AS user_roles,
+    users.avatar_url AS user_avatar_url,
+    COUNT(audit_logs.*) OVER () AS count
+FROM
+    audit_logs
+    LEFT JOIN users ON audit_logs.user_id = users.id
+    LEFT JOIN
+        -- First join on workspaces to get the initial workspace create
+        -- to workspace build 1 id. This is because the first create is
+        -- is a different audit log than subsequent starts.
+        workspaces ON
+		    audit_logs.resource_type = 'workspace' AND
+			audit_logs.resource_id = workspaces.id
+    LEFT JOIN
+	    workspace_builds ON
+            -- Get the reason from the build if the resource type
+            -- is a workspace_build
+            (
+			    audit_logs.resource_type = 'workspace_build'
+                AND audit_logs.resource_id = workspace_builds.id
+			)
+            OR
+            -- Get the reason from the build #1 if this is the first
+            -- workspace create.
+            (
+				audit_logs.resource_type = 'workspace' AND
+				audit_logs.action = 'create' AND
+				workspaces.id = workspace_builds.workspace_id AND
+				workspace_builds.build_number = 1
+			)
+WHERE
+    -- Filter resource_type
+	CASE
+		WHEN $3 :: text != '' THEN
+			resource_type = $3 :: resource_type
+		ELSE true
+	END
+	-- Filter resource_id
+	AND CASE
+		WHEN $4 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			resource_id = $4
+		ELSE true
+	END
+	-- Filter by resource_target
+	AND CASE
+		WHEN $5 :: text != '' THEN
+			resource_target = $5
+		ELSE true
+	END
+	-- Filter action
+	AND CASE
+		WHEN $6 :: text != '' THEN
+			action = $6 :: audit_action
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN $7 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $7
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN $8 :: text != '' THEN
+			user_id = (SELECT id FROM users WHERE lower(username) = lower($8) AND deleted = false)
+		ELSE true
+	END
+	-- Filter by user_email
+	AND CASE
+		WHEN $9 :: text != '' THEN
+			users.email = $9
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN $10 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" >= $10
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN $11 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			"time" <= $11
+		ELSE true
+	END
+    -- Filter by build_reason
+    AND CASE
+	    WHEN $12::text != '' THEN
+            workspace_builds.reason::text = $12
+        ELSE true
+    END
+ORDER BY
+    "time" DESC,
+    audit_logs.id DESC
+LIMIT
+    $1
+OFFSET
+    $2
+`
+
+type GetAuditLogsOffsetParams struct {
+	Limit          int32     `db:"limit" json:"limit"`
+	Offset         int32     `db:"offset" json:"offset"`
+	ResourceType   string    `db:"resource_type" json:"resource_type"`
+	ResourceID     uuid.UUID `db:"resource_id" json:"resource_id"`
+	ResourceTarget string    `db:"resource_target" json:"resource_target"`
+	Action         string    `db:"action" json:"action"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	Username       string    `db:"username" json:"username"`
+	Email          string    `db:"email" json:"email"`
+	DateFrom       time.Time `db:"date_from" json:"date_from"`
+	DateTo         time.Time `db:"date_to" json:"date_to"`
+	BuildReason    string    `db:"build_reason" json:"build_reason"`
+}
+
+type GetAuditLogsOffsetRow struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	Time             time.Time       `db:"time" json:"time"`
+	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
+	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Ip               pqtype.Inet     `db:"ip" json:"ip"`
+	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
+	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
+	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
+	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
+	Action           AuditAction     `db:"action" json:"action"`
+	Diff             json.RawMessage `db:"diff" json:"diff"`
+	StatusCode       int32           `db:"status_code" json:"status_code"`
+	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
+	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
+	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+	UserUsername     sql.NullString  `db:"user_username" json:"user_username"`
+	UserEmail        sql.NullString  `db:"user_email" json:"user_email"`
+	UserCreatedAt    sql.NullTime    `db:"user_created_at" json:"user_created_at"`
+	UserStatus       NullUserStatus  `db:"user_status" json:"user_status"`
+	UserRoles        pq.StringArray  `db:"user_roles" json:"user_roles"`
+	UserAvatarUrl    sql.NullString  `db:"user_avatar_url" json:"user_avatar_url"`
+	Count            int64           `db:"count" json:"count"`
+}
+
+// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
+// ID.
+func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsOffset,
+		arg.Limit,
+		arg.Offset,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.ResourceTarget,
+		arg.Action,
+		arg.UserID,
+		arg.Username,
+		arg.Email,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.BuildReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuditLogsOffsetRow
+	for rows.Next() {
+		var i GetAuditLogsOffsetRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+			&i.AdditionalFields,
+			&i.RequestID,
+			&i.ResourceIcon,
+			&i.UserUsername,
+			&i.UserEmail,
+			&i.UserCreatedAt,
+			&i.UserStatus,
+			&i.UserRoles,
+			&i.UserAvatarUrl,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAuditLog = `-- name: InsertAuditLog :one
+INSERT INTO
+	audit_logs (
+        id,
+        "time",
+        user_id,
+        organization_id,
+        ip,
+        user_agent,
+        resource_type,
+        resource_id,
+        resource_target,
+        action,
+        diff,
+        status_code,
+        additional_fields,
+        request_id,
+        resource_icon
+    )
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
+`
+
+type InsertAuditLogParams struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	Time             time.Time       `db:"time" json:"time"`
+	UserID           uuid.UUID       `db:"user_id" json:"user_id"`
+	OrganizationID   uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Ip               pqtype.Inet     `db:"ip" json:"ip"`
+	UserAgent        sql.NullString  `db:"user_agent" json:"user_agent"`
+	ResourceType     ResourceType    `db:"resource_type" json:"resource_type"`
+	ResourceID       uuid.UUID       `db:"resource_id" json:"resource_id"`
+	ResourceTarget   string          `db:"resource_target" json:"resource_target"`
+	Action           AuditAction     `db:"action" json:"action"`
+	Diff             json.RawMessage `db:"diff" json:"diff"`
+	StatusCode       int32           `db:"status_code" json:"status_code"`
+	AdditionalFields json.RawMessage `db:"additional_fields" json:"additional_fields"`
+	RequestID        uuid.UUID       `db:"request_id" json:"request_id"`
+	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
+}
+
+func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error) {
+	row := q.db.QueryRowContext(ctx, insertAuditLog,
+		arg.ID,
+		arg.Time,
+		arg.UserID,
+		arg.OrganizationID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.ResourceTarget,
+		arg.Action,
+		arg.Diff,
+		arg.StatusCode,
+		arg.AdditionalFields,
+		arg.RequestID,
+		arg.ResourceIcon,
+	)
+	var i AuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.Time,
+		&i.UserID,
+		&i.OrganizationID,
+		&i.Ip,
+		&i.UserAgent,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.ResourceTarget,
+		&i.Action,
+		&i.Diff,
+		&i.StatusCode,
+		&i.AdditionalFields,
+		&i.RequestID,
+		&i.ResourceIcon,
+	)
+	return i, err
+}
+
+const getConnectionLogsOffset = `-- name: GetConnectionLogsOffset :many
+SELECT
+	connection_logs.id, connection_logs.time, connection_logs.organization_id, connection_logs.workspace_owner_id, connection_logs.workspace_id, connection_logs.workspace_name, connection_logs.agent_name, connection_logs.type, connection_logs.port, connection_logs.ip, connection_logs.user_agent, connection_logs.user_id, connection_logs.api_key_id,
+	COALESCE(users.username, '') :: text AS user_username,
+	COALESCE(owners.username, '') :: text AS workspace_owner_username,
+	COUNT(connection_logs.id, connection_logs.time, connection_logs.organization_id, connection_logs.workspace_owner_id, connection_logs.workspace_id, connection_logs.workspace_name, connection_logs.agent_name, connection_logs.type, connection_logs.port, connection_logs.ip, connection_logs.user_agent, connection_logs.user_id, connection_logs.api_key_id) OVER () AS count
+FROM
+	connection_logs
+	LEFT JOIN users ON connection_logs.user_id = users.id
+	LEFT JOIN users owners ON connection_logs.workspace_owner_id = owners.id
+WHERE
+	-- Filter by type
+	CASE
+		WHEN $1 :: text != '' THEN
+			connection_logs.type = $1 :: connection_type
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			connection_logs.workspace_id = $2
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			connection_logs.user_id = $3
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			lower(users.username) = lower($4)
+		ELSE true
+	END
+	-- Filter by workspace_owner
+	AND CASE
+		WHEN $5 :: text != '' THEN
+			lower(owners.username) = lower($5)
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connection_logs."time" >= $6
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN $7 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connection_logs."time" <= $7
+		ELSE true
+	END
+ORDER BY
+	connection_logs."time" DESC,
+	connection_logs.id DESC
+LIMIT
+	NULLIF($8 :: int, 0)
+OFFSET
+	$9
+`
+
+type GetConnectionLogsOffsetParams struct {
+	Type           string    `db:"type" json:"type"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	Username       string    `db:"username" json:"username"`
+	WorkspaceOwner string    `db:"workspace_owner" json:"workspace_owner"`
+	DateFrom       time.Time `db:"date_from" json:"date_from"`
+	DateTo         time.Time `db:"date_to" json:"date_to"`
+	LimitOpt       int32     `db:"limit_opt" json:"limit_opt"`
+	OffsetOpt      int32     `db:"offset_opt" json:"offset_opt"`
+}
+
+type GetConnectionLogsOffsetRow struct {
+	ID                     uuid.UUID      `db:"id" json:"id"`
+	Time                   time.Time      `db:"time" json:"time"`
+	OrganizationID         uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID       uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID            uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName          string         `db:"workspace_name" json:"workspace_name"`
+	AgentName              string         `db:"agent_name" json:"agent_name"`
+	Type                   ConnectionType `db:"type" json:"type"`
+	Port                   sql.NullInt32  `db:"port" json:"port"`
+	Ip                     pqtype.Inet    `db:"ip" json:"ip"`
+	UserAgent              string         `db:"user_agent" json:"user_agent"`
+	UserID                 uuid.UUID      `db:"user_id" json:"user_id"`
+	APIKeyID               string         `db:"api_key_id" json:"api_key_id"`
+	UserUsername           string         `db:"user_username" json:"user_username"`
+	WorkspaceOwnerUsername string         `db:"workspace_owner_username" json:"workspace_owner_username"`
+	Count                  int64          `db:"count" json:"count"`
+}
+
+func (q *sqlQuerier) GetConnectionLogsOffset(ctx context.Context, arg GetConnectionLogsOffsetParams) ([]GetConnectionLogsOffsetRow, error) {
+	rows, err := q.db.QueryContext(ctx, getConnectionLogsOffset,
+		arg.Type,
+		arg.WorkspaceID,
+		arg.UserID,
+		arg.Username,
+		arg.WorkspaceOwner,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.LimitOpt,
+		arg.OffsetOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetConnectionLogsOffsetRow
+	for rows.Next() {
+		var i GetConnectionLogsOffsetRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.OrganizationID,
+			&i.WorkspaceOwnerID,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.AgentName,
+			&i.Type,
+			&i.Port,
+			&i.Ip,
+			&i.UserAgent,
+			&i.UserID,
+			&i.APIKeyID,
+			&i.UserUsername,
+			&i.WorkspaceOwnerUsername,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertConnectionLog = `-- name: InsertConnectionLog :one
+INSERT INTO
+	connection_logs (
+		id,
+		"time",
+		organization_id,
+		workspace_owner_id,
+		workspace_id,
+		workspace_name,
+		agent_name,
+		type,
+		port,
+		ip,
+		user_agent,
+		user_id,
+		api_key_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, time, organization_id, workspace_owner_id, workspace_id, workspace_name, agent_name, type, port, ip, user_agent, user_id, api_key_id
+`
+
+type InsertConnectionLogParams struct {
+	ID               uuid.UUID      `db:"id" json:"id"`
+	Time             time.Time      `db:"time" json:"time"`
+	OrganizationID   uuid.UUID      `db:"organization_id" json:"organization_id"`
+	WorkspaceOwnerID uuid.UUID      `db:"workspace_owner_id" json:"workspace_owner_id"`
+	WorkspaceID      uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName    string         `db:"workspace_name" json:"workspace_name"`
+	AgentName        string         `db:"agent_name" json:"agent_name"`
+	Type             ConnectionType `db:"type" json:"type"`
+	Port             sql.NullInt32  `db:"port" json:"port"`
+	Ip               pqtype.Inet    `db:"ip" json:"ip"`
+	UserAgent        string         `db:"user_agent" json:"user_agent"`
+	UserID           uuid.UUID      `db:"user_id" json:"user_id"`
+	APIKeyID         string         `db:"api_key_id" json:"api_key_id"`
+}
+
+func (q *sqlQuerier) InsertConnectionLog(ctx context.Context, arg InsertConnectionLogParams) (ConnectionLog, error) {
+	row := q.db.QueryRowContext(ctx, insertConnectionLog,
+		arg.ID,
+		arg.Time,
+		arg.OrganizationID,
+		arg.WorkspaceOwnerID,
+		arg.WorkspaceID,
+		arg.WorkspaceName,
+		arg.AgentName,
+		arg.Type,
+		arg.Port,
+		arg.Ip,
+		arg.UserAgent,
+		arg.UserID,
+		arg.APIKeyID,
+	)
+	var i ConnectionLog
+	err := row.Scan(
+		&i.ID,
+		&i.Time,
+		&i.OrganizationID,
+		&i.WorkspaceOwnerID,
+		&i.WorkspaceID,
+		&i.WorkspaceName,
+		&i.AgentName,
+		&i.Type,
+		&i.Port,
+		&i.Ip,
+		&i.UserAgent,
+		&i.UserID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const getDBCryptKeys = `-- name: GetDBCryptKeys :many
+SELECT number, active_key_digest, revoked_key_digest, created_at, revoked_at, test FROM dbcrypt_keys ORDER BY number ASC
+`
+
+func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
+	rows, err := q.db.QueryContext(ctx, getDBCryptKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DBCryptKey
+	for rows.Next() {
+		var i DBCryptKey
+		if err := rows.Scan(
+			&i.Number,
+			&i.ActiveKeyDigest,
 			&i.RevokedKeyDigest,
 			&i.CreatedAt,
-			&i.RevokedAt,
-			&i.Test,
+			&i.RevokedAt,
+			&i.Test,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
+INSERT INTO dbcrypt_keys
+	(number, active_key_digest, created_at, test)
+VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+`
+
+type InsertDBCryptKeyParams struct {
+	Number          int32  `db:"number" json:"number"`
+	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
+	Test            string `db:"test" json:"test"`
+}
+
+func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
+	return err
+}
+
+const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
+UPDATE dbcrypt_keys
+SET
+	revoked_key_digest = active_key_digest,
+	active_key_digest = revoked_key_digest,
+	revoked_at = CURRENT_TIMESTAMP
+WHERE
+	active_key_digest = $1::text
+AND
+	revoked_key_digest IS NULL
+`
+
+func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
+	_, err := q.db.ExecContext(ctx, revokeDBCryptKey, activeKeyDigest)
+	return err
+}
+
+const deleteExternalAuthLink = `-- name: DeleteExternalAuthLink :exec
+DELETE FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
+`
+
+type DeleteExternalAuthLinkParams struct {
+	ProviderID string    `db:"provider_id" json:"provider_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error {
+	_, err := q.db.ExecContext(ctx, deleteExternalAuthLink, arg.ProviderID, arg.UserID)
+	return err
+}
+
+const getExternalAuthLink = `-- name: GetExternalAuthLink :one
+SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
+`
+
+type GetExternalAuthLinkParams struct {
+	ProviderID string    `db:"provider_id" json:"provider_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetExternalAuthLink(ctx context.Context, arg GetExternalAuthLinkParams) (ExternalAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, getExternalAuthLink, arg.ProviderID, arg.UserID)
+	var i ExternalAuthLink
+	err := row.Scan(
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.OAuthExtra,
+	)
+	return i, err
+}
+
+const getExternalAuthLinksByUserID = `-- name: GetExternalAuthLinksByUserID :many
+SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra FROM external_auth_links WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]ExternalAuthLink, error) {
+	rows, err := q.db.QueryContext(ctx, getExternalAuthLinksByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExternalAuthLink
+	for rows.Next() {
+		var i ExternalAuthLink
+		if err := rows.Scan(
+			&i.ProviderID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
+			&i.OAuthExtra,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertExternalAuthLink = `-- name: InsertExternalAuthLink :one
+INSERT INTO external_auth_links (
+    provider_id,
+    user_id,
+    created_at,
+    updated_at,
+    oauth_access_token,
+    oauth_access_token_key_id,
+    oauth_refresh_token,
+    oauth_refresh_token_key_id,
+    oauth_expiry,
+	oauth_extra
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+	$10
+) RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra
+`
+
+type InsertExternalAuthLinkParams struct {
+	ProviderID             string                `db:"provider_id" json:"provider_id"`
+	UserID                 uuid.UUID             `db:"user_id" json:"user_id"`
+	CreatedAt              time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt              time.Time             `db:"updated_at" json:"updated_at"`
+	OAuthAccessToken       string                `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString        `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string                `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString        `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time             `db:"oauth_expiry" json:"oauth_expiry"`
+	OAuthExtra             pqtype.NullRawMessage `db:"oauth_extra" json:"oauth_extra"`
+}
+
+func (q *sqlQuerier) InsertExternalAuthLink(ctx context.Context, arg InsertExternalAuthLinkParams) (ExternalAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, insertExternalAuthLink,
+		arg.ProviderID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.OAuthExtra,
+	)
+	var i ExternalAuthLink
+	err := row.Scan(
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.OAuthExtra,
+	)
+	return i, err
+}
+
+const updateExternalAuthLink = `-- name: UpdateExternalAuthLink :one
+UPDATE external_auth_links SET
+    updated_at = $3,
+    oauth_access_token = $4,
+    oauth_access_token_key_id = $5,
+    oauth_refresh_token = $6,
+    oauth_refresh_token_key_id = $7,
+    oauth_expiry = $8,
+	oauth_extra = $9
+WHERE provider_id = $1 AND user_id = $2 RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra
+`
+
+type UpdateExternalAuthLinkParams struct {
+	ProviderID             string                `db:"provider_id" json:"provider_id"`
+	UserID                 uuid.UUID             `db:"user_id" json:"user_id"`
+	UpdatedAt              time.Time             `db:"updated_at" json:"updated_at"`
+	OAuthAccessToken       string                `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString        `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string                `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString        `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time             `db:"oauth_expiry" json:"oauth_expiry"`
+	OAuthExtra             pqtype.NullRawMessage `db:"oauth_extra" json:"oauth_extra"`
+}
+
+func (q *sqlQuerier) UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error) {
+	row := q.db.QueryRowContext(ctx, updateExternalAuthLink,
+		arg.ProviderID,
+		arg.UserID,
+		arg.UpdatedAt,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.OAuthExtra,
+	)
+	var i ExternalAuthLink
+	err := row.Scan(
+		&i.ProviderID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.OAuthExtra,
+	)
+	return i, err
+}
+
+const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
+SELECT
+	hash, created_at, created_by, mimetype, data, id
+FROM
+	files
+WHERE
+	hash = $1
+AND
+	created_by = $2
+LIMIT
+	1
+`
+
+type GetFileByHashAndCreatorParams struct {
+	Hash      string    `db:"hash" json:"hash"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+}
+
+func (q *sqlQuerier) GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, getFileByHashAndCreator, arg.Hash, arg.CreatedBy)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+		&i.ID,
+	)
+	return i, err
+}
+
+const getFileByID = `-- name: GetFileByID :one
+SELECT
+	hash, created_at, created_by, mimetype, data, id
+FROM
+	files
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetFileByID(ctx context.Context, id uuid.UUID) (File, error) {
+	row := q.db.QueryRowContext(ctx, getFileByID, id)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+		&i.ID,
+	)
+	return i, err
+}
+
+const getFileTemplates = `-- name: GetFileTemplates :many
+SELECT
+	files.id AS file_id,
+	files.created_by AS file_created_by,
+	templates.id AS template_id,
+	templates.organization_id AS template_organization_id,
+	templates.created_by AS template_created_by,
+	templates.user_acl,
+	templates.group_acl,
+	templates.visibility
+FROM
+	templates
+INNER JOIN
+	template_versions
+	ON templates.id = template_versions.template_id
+INNER JOIN
+	provisioner_jobs
+	ON job_id = provisioner_jobs.id
+INNER JOIN
+	files
+	ON files.id = provisioner_jobs.file_id
+WHERE
+    -- Only fetch template version associated files.
+	storage_method = 'file'
+	AND provisioner_jobs.type = 'template_version_import'
+	AND file_id = $1
+`
+
+type GetFileTemplatesRow struct {
+	FileID                 uuid.UUID          `db:"file_id" json:"file_id"`
+	FileCreatedBy          uuid.UUID          `db:"file_created_by" json:"file_created_by"`
+	TemplateID             uuid.UUID          `db:"template_id" json:"template_id"`
+	TemplateOrganizationID uuid.UUID          `db:"template_organization_id" json:"template_organization_id"`
+	TemplateCreatedBy      uuid.UUID          `db:"template_created_by" json:"template_created_by"`
+	UserACL                TemplateACL        `db:"user_acl" json:"user_acl"`
+	GroupACL               TemplateACL        `db:"group_acl" json:"group_acl"`
+	Visibility             TemplateVisibility `db:"visibility" json:"visibility"`
+}
+
+// Get all templates that use a file.
+func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]GetFileTemplatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFileTemplates, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFileTemplatesRow
+	for rows.Next() {
+		var i GetFileTemplatesRow
+		if err := rows.Scan(
+			&i.FileID,
+			&i.FileCreatedBy,
+			&i.TemplateID,
+			&i.TemplateOrganizationID,
+			&i.TemplateCreatedBy,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertFile = `-- name: InsertFile :one
+INSERT INTO
+	files (id, hash, created_at, created_by, mimetype, "data")
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id
+`
+
+type InsertFileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Hash      string    `db:"hash" json:"hash"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+	Mimetype  string    `db:"mimetype" json:"mimetype"`
+	Data      []byte    `db:"data" json:"data"`
+}
+
+func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File, error) {
+	row := q.db.QueryRowContext(ctx, insertFile,
+		arg.ID,
+		arg.Hash,
+		arg.CreatedAt,
+		arg.CreatedBy,
+		arg.Mimetype,
+		arg.Data,
+	)
+	var i File
+	err := row.Scan(
+		&i.Hash,
+		&i.CreatedAt,
+		&i.CreatedBy,
+		&i.Mimetype,
+		&i.Data,
+		&i.ID,
+	)
+	return i, err
+}
+
+const deleteGitSSHKey = `-- name: DeleteGitSSHKey :exec
+DELETE FROM
+	gitsshkeys
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, userID)
+	return err
+}
+
+const getGitSSHKey = `-- name: GetGitSSHKey :one
+SELECT
+	user_id, created_at, updated_at, private_key, public_key
+FROM
+	gitsshkeys
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, getGitSSHKey, userID)
+	var i GitSSHKey
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
+	)
+	return i, err
+}
+
+const insertGitSSHKey = `-- name: InsertGitSSHKey :one
+INSERT INTO
+	gitsshkeys (
+		user_id,
+		created_at,
+		updated_at,
+		private_key,
+		public_key
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at, private_key, public_key
+`
+
+type InsertGitSSHKeyParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	PrivateKey string    `db:"private_key" json:"private_key"`
+	PublicKey  string    `db:"public_key" json:"public_key"`
+}
+
+func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, insertGitSSHKey,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.PrivateKey,
+		arg.PublicKey,
+	)
+	var i GitSSHKey
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
+	)
+	return i, err
+}
+
+const updateGitSSHKey = `-- name: UpdateGitSSHKey :one
+UPDATE
+	gitsshkeys
+SET
+	updated_at = $2,
+	private_key = $3,
+	public_key = $4
+WHERE
+	user_id = $1
+RETURNING
+	user_id, created_at, updated_at, private_key, public_key
+`
+
+type UpdateGitSSHKeyParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	PrivateKey string    `db:"private_key" json:"private_key"`
+	PublicKey  string    `db:"public_key" json:"public_key"`
+}
+
+func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error) {
+	row := q.db.QueryRowContext(ctx, updateGitSSHKey,
+		arg.UserID,
+		arg.UpdatedAt,
+		arg.PrivateKey,
+		arg.PublicKey,
+	)
+	var i GitSSHKey
+	err := row.Scan(
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PrivateKey,
+		&i.PublicKey,
+	)
+	return i, err
+}
+
+const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
+DELETE FROM
+	group_members
+WHERE
+	user_id = $1 AND
+	group_id = $2
+`
+
+type DeleteGroupMemberFromGroupParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
+	return err
+}
+
+const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
+DELETE FROM
+	group_members
+WHERE
+	group_members.user_id = $1
+	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
+`
+
+type DeleteGroupMembersByOrgAndUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
+	return err
+}
+
+const getGroupMembers = `-- name: GetGroupMembers :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.theme_preference, users.name, users.ip_allowlist
+FROM
+	users
+LEFT JOIN
+	group_members
+ON
+	group_members.user_id = users.id AND
+	group_members.group_id = $1
+LEFT JOIN
+	organization_members
+ON
+	organization_members.user_id = users.id AND
+	organization_members.organization_id = $1
+WHERE
+	-- In either case, the group_id will only match an org or a group.
+    (group_members.group_id = $1
+         OR
+     organization_members.organization_id = $1)
+AND
+	users.deleted = 'false'
+`
+
+// If the group is a user made group, then we need to check the group_members table.
+// If it is the "Everyone" group, then we need to check the organization_members table.
+func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.ThemePreference,
+			&i.Name,
+			pq.Array(&i.IPAllowlist),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroupMember = `-- name: InsertGroupMember :exec
+INSERT INTO
+    group_members (user_id, group_id)
+VALUES
+    ($1, $2)
+`
+
+type InsertGroupMemberParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.UserID, arg.GroupID)
+	return err
+}
+
+const insertUserGroupsByName = `-- name: InsertUserGroupsByName :exec
+WITH groups AS (
+    SELECT
+        id
+    FROM
+        groups
+    WHERE
+        groups.organization_id = $2 AND
+        groups.name = ANY($3 :: text [])
+)
+INSERT INTO
+    group_members (user_id, group_id)
+SELECT
+    $1,
+    groups.id
+FROM
+    groups
+`
+
+type InsertUserGroupsByNameParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	GroupNames     []string  `db:"group_names" json:"group_names"`
+}
+
+// InsertUserGroupsByName adds a user to all provided groups, if they exist.
+func (q *sqlQuerier) InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserGroupsByName, arg.UserID, arg.OrganizationID, pq.Array(arg.GroupNames))
+	return err
+}
+
+const deleteGroupByID = `-- name: DeleteGroupByID :exec
+DELETE FROM
+	groups
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
+	return err
+}
+
+const getGroupByID = `-- name: GetGroupByID :one
+SELECT
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByID, id)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
+SELECT
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	organization_id = $1
+AND
+	name = $2
+LIMIT
+	1
+`
+
+type GetGroupByOrgAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, getGroupByOrgAndName, arg.OrganizationID, arg.Name)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const getGroupsByOrganizationAndUserID = `-- name: GetGroupsByOrganizationAndUserID :many
+SELECT
+	groups.id, groups.name, groups.organization_id, groups.avatar_url, groups.quota_allowance, groups.display_name, groups.source
+FROM
+	groups
+INNER JOIN
+	group_members
+ON
+	group_members.group_id = groups.id
+WHERE
+	group_members.user_id = $1
+	AND groups.organization_id = $2
+`
+
+type GetGroupsByOrganizationAndUserIDParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetGroupsByOrganizationAndUserID(ctx context.Context, arg GetGroupsByOrganizationAndUserIDParams) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationAndUserID, arg.UserID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			&i.AvatarURL,
+			&i.QuotaAllowance,
+			&i.DisplayName,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
+SELECT
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+FROM
+	groups
+WHERE
+	organization_id = $1
+`
+
+func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			&i.AvatarURL,
+			&i.QuotaAllowance,
+			&i.DisplayName,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAllUsersGroup = `-- name: InsertAllUsersGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	organization_id
+)
+VALUES
+	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+// We use the organization_id as the id
+// for simplicity since all users is
+// every member of the org.
+func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertAllUsersGroup, organizationID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const insertGroup = `-- name: InsertGroup :one
+INSERT INTO groups (
+	id,
+	name,
+	display_name,
+	organization_id,
+	avatar_url,
+	quota_allowance
+)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+type InsertGroupParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	DisplayName    string    `db:"display_name" json:"display_name"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+}
+
+func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, insertGroup,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.OrganizationID,
+		arg.AvatarURL,
+		arg.QuotaAllowance,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const insertMissingGroups = `-- name: InsertMissingGroups :many
+INSERT INTO groups (
+	id,
+	name,
+	organization_id,
+    source
+)
+SELECT
+    gen_random_uuid(),
+    group_name,
+    $1,
+    $2
+FROM
+    UNNEST($3 :: text[]) AS group_name
+ON CONFLICT DO NOTHING
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+type InsertMissingGroupsParams struct {
+	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
+	Source         GroupSource `db:"source" json:"source"`
+	GroupNames     []string    `db:"group_names" json:"group_names"`
+}
+
+// Inserts any group by name that does not exist. All new groups are given
+// a random uuid, are inserted into the same organization. They have the default
+// values for avatar, display name, and quota allowance (all zero values).
+// If the name conflicts, do nothing.
+func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, insertMissingGroups, arg.OrganizationID, arg.Source, pq.Array(arg.GroupNames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			&i.AvatarURL,
+			&i.QuotaAllowance,
+			&i.DisplayName,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateGroupByID = `-- name: UpdateGroupByID :one
+UPDATE
+	groups
+SET
+	name = $1,
+	display_name = $2,
+	avatar_url = $3,
+	quota_allowance = $4
+WHERE
+	id = $5
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+`
+
+type UpdateGroupByIDParams struct {
+	Name           string    `db:"name" json:"name"`
+	DisplayName    string    `db:"display_name" json:"display_name"`
+	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	ID             uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupByID,
+		arg.Name,
+		arg.DisplayName,
+		arg.AvatarURL,
+		arg.QuotaAllowance,
+		arg.ID,
+	)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+	)
+	return i, err
+}
+
+const deleteOldGroupSyncRuns = `-- name: DeleteOldGroupSyncRuns :exec
+DELETE FROM group_sync_runs WHERE started_at < $1
+`
+
+func (q *sqlQuerier) DeleteOldGroupSyncRuns(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldGroupSyncRuns, beforeTime)
+	return err
+}
+
+const getGroupSyncRuns = `-- name: GetGroupSyncRuns :many
+SELECT
+	id, trigger, user_id, started_at, finished_at, users_synced, users_failed, idp_groups, groups, error
+FROM
+	group_sync_runs
+WHERE
+	CASE
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $1
+		ELSE true
+	END
+ORDER BY
+	started_at DESC, id DESC
+LIMIT
+	$2 :: int
+`
+
+type GetGroupSyncRunsParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	LimitCount int32     `db:"limit_count" json:"limit_count"`
+}
+
+// Returns group sync runs, most recent first. If a user is given, only the
+// runs that synced that user at login are returned.
+func (q *sqlQuerier) GetGroupSyncRuns(ctx context.Context, arg GetGroupSyncRunsParams) ([]GroupSyncRun, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupSyncRuns, arg.UserID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupSyncRun
+	for rows.Next() {
+		var i GroupSyncRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.Trigger,
+			&i.UserID,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.UsersSynced,
+			&i.UsersFailed,
+			pq.Array(&i.IDPGroups),
+			pq.Array(&i.Groups),
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroupSyncRun = `-- name: InsertGroupSyncRun :one
+INSERT INTO
+	group_sync_runs (id, trigger, user_id, started_at, finished_at, users_synced, users_failed, idp_groups, groups, error)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, trigger, user_id, started_at, finished_at, users_synced, users_failed, idp_groups, groups, error
+`
+
+type InsertGroupSyncRunParams struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	Trigger     GroupSyncTrigger `db:"trigger" json:"trigger"`
+	UserID      uuid.NullUUID    `db:"user_id" json:"user_id"`
+	StartedAt   time.Time        `db:"started_at" json:"started_at"`
+	FinishedAt  time.Time        `db:"finished_at" json:"finished_at"`
+	UsersSynced int32            `db:"users_synced" json:"users_synced"`
+	UsersFailed int32            `db:"users_failed" json:"users_failed"`
+	IDPGroups   []string         `db:"idp_groups" json:"idp_groups"`
+	Groups      []string         `db:"groups" json:"groups"`
+	Error       string           `db:"error" json:"error"`
+}
+
+func (q *sqlQuerier) InsertGroupSyncRun(ctx context.Context, arg InsertGroupSyncRunParams) (GroupSyncRun, error) {
+	row := q.db.QueryRowContext(ctx, insertGroupSyncRun,
+		arg.ID,
+		arg.Trigger,
+		arg.UserID,
+		arg.StartedAt,
+		arg.FinishedAt,
+		arg.UsersSynced,
+		arg.UsersFailed,
+		pq.Array(arg.IDPGroups),
+		pq.Array(arg.Groups),
+		arg.Error,
+	)
+	var i GroupSyncRun
+	err := row.Scan(
+		&i.ID,
+		&i.Trigger,
+		&i.UserID,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.UsersSynced,
+		&i.UsersFailed,
+		pq.Array(&i.IDPGroups),
+		pq.Array(&i.Groups),
+		&i.Error,
+	)
+	return i, err
+}
+
+const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
+WITH app_stats_by_user_and_agent AS (
+	SELECT
+		s.start_time,
+		60 as seconds,
+		w.template_id,
+		was.user_id,
+		was.agent_id,
+		was.access_method,
+		was.slug_or_port,
+		wa.display_name,
+		wa.icon,
+		(wa.slug IS NOT NULL)::boolean AS is_app,
+		-- Requests are reported per session, count them in the first minute
+		-- of the session within the timeframe so they're only counted once.
+		COALESCE(SUM(was.requests) FILTER (
+			WHERE s.start_time = GREATEST(date_trunc('minute', was.session_started_at), date_trunc('minute', $2::timestamptz))
+		), 0) AS requests,
+		MAX(was.session_ended_at) AS last_used_at
+	FROM workspace_app_stats was
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
+	)
+	-- We do a left join here because we want to include user IDs that have used
+	-- e.g. ports when counting active users.
+	LEFT JOIN workspace_apps wa ON (
+		wa.agent_id = was.agent_id
+		AND wa.slug = was.slug_or_port
+	)
+	-- This table contains both 1 minute entries and >1 minute entries,
+	-- to calculate this with our uniqueness constraints, we generate series
+	-- for the longer intervals.
+	CROSS JOIN LATERAL generate_series(
+		date_trunc('minute', was.session_started_at),
+		-- Subtract 1 microsecond to avoid creating an extra series.
+		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
+		'1 minute'::interval
+	) s(start_time)
+	WHERE
+		s.start_time >= $2::timestamptz
+		-- Subtract one minute because the series only contains the start time.
+		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
+	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.access_method, was.slug_or_port, wa.display_name, wa.icon, wa.slug
+)
+
+SELECT
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	-- Return IDs so we can combine this with GetTemplateInsights.
+	array_agg(DISTINCT user_id)::uuid[] AS active_user_ids,
+	access_method,
+	slug_or_port,
+	display_name,
+	icon,
+	is_app,
+	SUM(seconds) AS usage_seconds,
+	SUM(requests)::bigint AS requests,
+	MAX(last_used_at)::timestamptz AS last_used_at
+FROM app_stats_by_user_and_agent
+GROUP BY access_method, slug_or_port, display_name, icon, is_app
+`
+
+type GetTemplateAppInsightsParams struct {
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateAppInsightsRow struct {
+	TemplateIDs   []uuid.UUID    `db:"template_ids" json:"template_ids"`
+	ActiveUserIDs []uuid.UUID    `db:"active_user_ids" json:"active_user_ids"`
+	AccessMethod  string         `db:"access_method" json:"access_method"`
+	SlugOrPort    string         `db:"slug_or_port" json:"slug_or_port"`
+	DisplayName   sql.NullString `db:"display_name" json:"display_name"`
+	Icon          sql.NullString `db:"icon" json:"icon"`
+	IsApp         bool           `db:"is_app" json:"is_app"`
+	UsageSeconds  int64          `db:"usage_seconds" json:"usage_seconds"`
+	Requests      int64          `db:"requests" json:"requests"`
+	LastUsedAt    time.Time      `db:"last_used_at" json:"last_used_at"`
+}
+
+// GetTemplateAppInsights returns the aggregate usage of each app in a given
+// timeframe. The result can be filtered on template_ids, meaning only user data
+// from workspaces based on those templates will be included.
+func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateAppInsightsRow
+	for rows.Next() {
+		var i GetTemplateAppInsightsRow
+		if err := rows.Scan(
+			pq.Array(&i.TemplateIDs),
+			pq.Array(&i.ActiveUserIDs),
+			&i.AccessMethod,
+			&i.SlugOrPort,
+			&i.DisplayName,
+			&i.Icon,
+			&i.IsApp,
+			&i.UsageSeconds,
+			&i.Requests,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateAppInsightsByTemplate = `-- name: GetTemplateAppInsightsByTemplate :many
+WITH app_stats_by_user_and_agent AS (
+	SELECT
+		s.start_time,
+		60 as seconds,
+		w.template_id,
+		was.user_id,
+		was.agent_id,
+		was.slug_or_port,
+		wa.display_name,
+		(wa.slug IS NOT NULL)::boolean AS is_app
+	FROM workspace_app_stats was
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+	)
+	-- We do a left join here because we want to include user IDs that have used
+	-- e.g. ports when counting active users.
+	LEFT JOIN workspace_apps wa ON (
+		wa.agent_id = was.agent_id
+		AND wa.slug = was.slug_or_port
+	)
+	-- This table contains both 1 minute entries and >1 minute entries,
+	-- to calculate this with our uniqueness constraints, we generate series
+	-- for the longer intervals.
+	CROSS JOIN LATERAL generate_series(
+		date_trunc('minute', was.session_started_at),
+		-- Subtract 1 microsecond to avoid creating an extra series.
+		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
+		'1 minute'::interval
+	) s(start_time)
+	WHERE
+		s.start_time >= $1::timestamptz
+		-- Subtract one minute because the series only contains the start time.
+		AND s.start_time < ($2::timestamptz) - '1 minute'::interval
+	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.slug_or_port, wa.display_name, wa.slug
+)
+
+SELECT
+	template_id,
+	display_name,
+	slug_or_port,
+	COALESCE(COUNT(DISTINCT user_id))::bigint AS active_users,
+	SUM(seconds) AS usage_seconds
+FROM app_stats_by_user_and_agent
+WHERE is_app IS TRUE
+GROUP BY template_id, display_name, slug_or_port
+`
+
+type GetTemplateAppInsightsByTemplateParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateAppInsightsByTemplateRow struct {
+	TemplateID   uuid.UUID      `db:"template_id" json:"template_id"`
+	DisplayName  sql.NullString `db:"display_name" json:"display_name"`
+	SlugOrPort   string         `db:"slug_or_port" json:"slug_or_port"`
+	ActiveUsers  int64          `db:"active_users" json:"active_users"`
+	UsageSeconds int64          `db:"usage_seconds" json:"usage_seconds"`
+}
+
+func (q *sqlQuerier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg GetTemplateAppInsightsByTemplateParams) ([]GetTemplateAppInsightsByTemplateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAppInsightsByTemplate, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateAppInsightsByTemplateRow
+	for rows.Next() {
+		var i GetTemplateAppInsightsByTemplateRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.DisplayName,
+			&i.SlugOrPort,
+			&i.ActiveUsers,
+			&i.UsageSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateBuildInsights = `-- name: GetTemplateBuildInsights :one
+WITH builds AS (
+	SELECT
+		pj.job_status,
+		EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec
+	FROM workspace_builds wb
+	JOIN template_versions tv ON (tv.id = wb.template_version_id)
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	WHERE
+		tv.template_id = $1::uuid
+		AND wb.created_at >= $2::timestamptz
+		AND wb.created_at < $3::timestamptz
+		AND pj.job_status IN ('succeeded', 'failed')
+)
+
+SELECT
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE job_status = 'failed') AS failed_builds,
+	COALESCE((PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY exec_time_sec) FILTER (WHERE job_status = 'succeeded')), -1)::FLOAT AS build_time_p50,
+	COALESCE((PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY exec_time_sec) FILTER (WHERE job_status = 'succeeded')), -1)::FLOAT AS build_time_p95
+FROM builds
+`
+
+type GetTemplateBuildInsightsParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	StartTime  time.Time `db:"start_time" json:"start_time"`
+	EndTime    time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateBuildInsightsRow struct {
+	TotalBuilds  int64   `db:"total_builds" json:"total_builds"`
+	FailedBuilds int64   `db:"failed_builds" json:"failed_builds"`
+	BuildTimeP50 float64 `db:"build_time_p50" json:"build_time_p50"`
+	BuildTimeP95 float64 `db:"build_time_p95" json:"build_time_p95"`
+}
+
+// GetTemplateBuildInsights returns the number of finished workspace builds of a
+// template in the given timeframe, how many of them failed, and the median and
+// 95th percentile execution time in seconds of the successful ones. Canceled
+// builds are not counted. The percentiles are -1 if no build succeeded.
+func (q *sqlQuerier) GetTemplateBuildInsights(ctx context.Context, arg GetTemplateBuildInsightsParams) (GetTemplateBuildInsightsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateBuildInsights, arg.TemplateID, arg.StartTime, arg.EndTime)
+	var i GetTemplateBuildInsightsRow
+	err := row.Scan(
+		&i.TotalBuilds,
+		&i.FailedBuilds,
+		&i.BuildTimeP50,
+		&i.BuildTimeP95,
+	)
+	return i, err
+}
+
+const getTemplateInsights = `-- name: GetTemplateInsights :one
+WITH agent_stats_by_interval_and_user AS (
+	SELECT
+		date_trunc('minute', was.created_at),
+		was.user_id,
+		array_agg(was.template_id) AS template_ids,
+		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
+		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
+		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
+		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
+	FROM workspace_agent_stats was
+	WHERE
+		was.created_at >= $1::timestamptz
+		AND was.created_at < $2::timestamptz
+		AND was.connection_count > 0
+		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
+	GROUP BY date_trunc('minute', was.created_at), was.user_id
+), template_ids AS (
+	SELECT array_agg(DISTINCT template_id) AS ids
+	FROM agent_stats_by_interval_and_user, unnest(template_ids) template_id
+	WHERE template_id IS NOT NULL
+)
+
+SELECT
+	COALESCE((SELECT ids FROM template_ids), '{}')::uuid[] AS template_ids,
+	-- Return IDs so we can combine this with GetTemplateAppInsights.
+	COALESCE(array_agg(DISTINCT user_id), '{}')::uuid[] AS active_user_ids,
+	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
+	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
+	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
+	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
+FROM agent_stats_by_interval_and_user
+`
+
+type GetTemplateInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateInsightsRow struct {
+	TemplateIDs                 []uuid.UUID `db:"template_ids" json:"template_ids"`
+	ActiveUserIDs               []uuid.UUID `db:"active_user_ids" json:"active_user_ids"`
+	UsageVscodeSeconds          int64       `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
+	UsageJetbrainsSeconds       int64       `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
+	UsageReconnectingPtySeconds int64       `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
+	UsageSshSeconds             int64       `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+}
+
+// GetTemplateInsights has a granularity of 5 minutes where if a session/app was
+// in use during a minute, we will add 5 minutes to the total usage for that
+// session/app (per user).
+func (q *sqlQuerier) GetTemplateInsights(ctx context.Context, arg GetTemplateInsightsParams) (GetTemplateInsightsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	var i GetTemplateInsightsRow
+	err := row.Scan(
+		pq.Array(&i.TemplateIDs),
+		pq.Array(&i.ActiveUserIDs),
+		&i.UsageVscodeSeconds,
+		&i.UsageJetbrainsSeconds,
+		&i.UsageReconnectingPtySeconds,
+		&i.UsageSshSeconds,
+	)
+	return i, err
+}
+
+const getTemplateInsightsByInterval = `-- name: GetTemplateInsightsByInterval :many
+WITH ts AS (
+	SELECT
+		d::timestamptz AS from_,
+		CASE
+			WHEN (d::timestamptz + ($1::int || ' day')::interval) <= $2::timestamptz
+			THEN (d::timestamptz + ($1::int || ' day')::interval)
+			ELSE $2::timestamptz
+		END AS to_
+	FROM
+		-- Subtract 1 microsecond from end_time to avoid including the next interval in the results.
+		generate_series($3::timestamptz, ($2::timestamptz) - '1 microsecond'::interval, ($1::int || ' day')::interval) AS d
+), unflattened_usage_by_interval AS (
+	-- We select data from both workspace agent stats and workspace app stats to
+	-- get a complete picture of usage. This matches how usage is calculated by
+	-- the combination of GetTemplateInsights and GetTemplateAppInsights. We use
+	-- a union all to avoid a costly distinct operation.
+	--
+	-- Note that one query must perform a left join so that all intervals are
+	-- present at least once.
+	SELECT
+		ts.from_, ts.to_,
+		was.template_id,
+		was.user_id
+	FROM ts
+	LEFT JOIN workspace_agent_stats was ON (
+		was.created_at >= ts.from_
+		AND was.created_at < ts.to_
+		AND was.connection_count > 0
+		AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN was.template_id = ANY($4::uuid[]) ELSE TRUE END
+	)
+	GROUP BY ts.from_, ts.to_, was.template_id, was.user_id
+
+	UNION ALL
+
+	SELECT
+		ts.from_, ts.to_,
+		w.template_id,
+		was.user_id
+	FROM ts
+	JOIN workspace_app_stats was ON (
+		(was.session_started_at >= ts.from_ AND was.session_started_at < ts.to_)
+		OR (was.session_ended_at > ts.from_ AND was.session_ended_at < ts.to_)
+		OR (was.session_started_at < ts.from_ AND was.session_ended_at >= ts.to_)
+	)
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+		AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN w.template_id = ANY($4::uuid[]) ELSE TRUE END
+	)
+	GROUP BY ts.from_, ts.to_, w.template_id, was.user_id
+)
+
+SELECT
+	from_ AS start_time,
+	to_ AS end_time,
+	array_remove(array_agg(DISTINCT template_id), NULL)::uuid[] AS template_ids,
+	COUNT(DISTINCT user_id) AS active_users
+FROM unflattened_usage_by_interval
+GROUP BY from_, to_
+`
+
+type GetTemplateInsightsByIntervalParams struct {
+	IntervalDays int32       `db:"interval_days" json:"interval_days"`
+	EndTime      time.Time   `db:"end_time" json:"end_time"`
+	StartTime    time.Time   `db:"start_time" json:"start_time"`
+	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateInsightsByIntervalRow struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	ActiveUsers int64       `db:"active_users" json:"active_users"`
+}
+
+// GetTemplateInsightsByInterval returns all intervals between start and end
+// time, if end time is a partial interval, it will be included in the results and
+// that interval will be shorter than a full one. If there is no data for a selected
+// interval/template, it will be included in the results with 0 active users.
+func (q *sqlQuerier) GetTemplateInsightsByInterval(ctx context.Context, arg GetTemplateInsightsByIntervalParams) ([]GetTemplateInsightsByIntervalRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateInsightsByInterval,
+		arg.IntervalDays,
+		arg.EndTime,
+		arg.StartTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateInsightsByIntervalRow
+	for rows.Next() {
+		var i GetTemplateInsightsByIntervalRow
+		if err := rows.Scan(
+			&i.StartTime,
+			&i.EndTime,
+			pq.Array(&i.TemplateIDs),
+			&i.ActiveUsers,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateInsightsByTemplate = `-- name: GetTemplateInsightsByTemplate :many
+WITH agent_stats_by_interval_and_user AS (
+	SELECT
+		date_trunc('minute', was.created_at) AS created_at_trunc,
+		was.template_id,
+		was.user_id,
+		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
+		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
+		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
+		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
+	FROM workspace_agent_stats was
+	WHERE
+		was.created_at >= $1::timestamptz
+		AND was.created_at < $2::timestamptz
+		AND was.connection_count > 0
+	GROUP BY created_at_trunc, was.template_id, was.user_id
+)
+
+SELECT
+	template_id,
+	COALESCE(COUNT(DISTINCT user_id))::bigint AS active_users,
+	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
+	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
+	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
+	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
+FROM agent_stats_by_interval_and_user
+GROUP BY template_id
+`
+
+type GetTemplateInsightsByTemplateParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+}
+
+type GetTemplateInsightsByTemplateRow struct {
+	TemplateID                  uuid.UUID `db:"template_id" json:"template_id"`
+	ActiveUsers                 int64     `db:"active_users" json:"active_users"`
+	UsageVscodeSeconds          int64     `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
+	UsageJetbrainsSeconds       int64     `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
+	UsageReconnectingPtySeconds int64     `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
+	UsageSshSeconds             int64     `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+}
+
+func (q *sqlQuerier) GetTemplateInsightsByTemplate(ctx context.Context, arg GetTemplateInsightsByTemplateParams) ([]GetTemplateInsightsByTemplateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateInsightsByTemplate, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateInsightsByTemplateRow
+	for rows.Next() {
+		var i GetTemplateInsightsByTemplateRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.ActiveUsers,
+			&i.UsageVscodeSeconds,
+			&i.UsageJetbrainsSeconds,
+			&i.UsageReconnectingPtySeconds,
+			&i.UsageSshSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateParameterInsights = `-- name: GetTemplateParameterInsights :many
+WITH latest_workspace_builds AS (
+	SELECT
+		wb.id,
+		wbmax.template_id,
+		wb.template_version_id
+	FROM (
+		SELECT
+			tv.template_id, wbmax.workspace_id, MAX(wbmax.build_number) as max_build_number
+		FROM workspace_builds wbmax
+		JOIN template_versions tv ON (tv.id = wbmax.template_version_id)
+		WHERE
+			wbmax.created_at >= $1::timestamptz
+			AND wbmax.created_at < $2::timestamptz
+			AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN tv.template_id = ANY($3::uuid[]) ELSE TRUE END
+		GROUP BY tv.template_id, wbmax.workspace_id
+	) wbmax
+	JOIN workspace_builds wb ON (
+		wb.workspace_id = wbmax.workspace_id
+		AND wb.build_number = wbmax.max_build_number
+	)
+), unique_template_params AS (
+	SELECT
+		ROW_NUMBER() OVER () AS num,
+		array_agg(DISTINCT wb.template_id)::uuid[] AS template_ids,
+		array_agg(wb.id)::uuid[] AS workspace_build_ids,
+		tvp.name,
+		tvp.type,
+		tvp.display_name,
+		tvp.description,
+		tvp.options
+	FROM latest_workspace_builds wb
+	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
+	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
+)
+
+SELECT
+	utp.num,
+	utp.template_ids,
+	utp.name,
+	utp.type,
+	utp.display_name,
+	utp.description,
+	utp.options,
+	wbp.value,
+	COUNT(wbp.value) AS count
+FROM unique_template_params utp
+JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
+GROUP BY utp.num, utp.template_ids, utp.name, utp.type, utp.display_name, utp.description, utp.options, wbp.value
+`
+
+type GetTemplateParameterInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateParameterInsightsRow struct {
+	Num         int64           `db:"num" json:"num"`
+	TemplateIDs []uuid.UUID     `db:"template_ids" json:"template_ids"`
+	Name        string          `db:"name" json:"name"`
+	Type        string          `db:"type" json:"type"`
+	DisplayName string          `db:"display_name" json:"display_name"`
+	Description string          `db:"description" json:"description"`
+	Options     json.RawMessage `db:"options" json:"options"`
+	Value       string          `db:"value" json:"value"`
+	Count       int64           `db:"count" json:"count"`
+}
+
+// GetTemplateParameterInsights does for each template in a given timeframe,
+// look for the latest workspace build (for every workspace) that has been
+// created in the timeframe and return the aggregate usage counts of parameter
+// values.
+func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateParameterInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateParameterInsightsRow
+	for rows.Next() {
+		var i GetTemplateParameterInsightsRow
+		if err := rows.Scan(
+			&i.Num,
+			pq.Array(&i.TemplateIDs),
+			&i.Name,
+			&i.Type,
+			&i.DisplayName,
+			&i.Description,
+			&i.Options,
+			&i.Value,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserActivityInsights = `-- name: GetUserActivityInsights :many
+WITH app_stats AS (
+	SELECT
+		s.start_time,
+		was.user_id,
+		w.template_id,
+		60 as seconds
+	FROM workspace_app_stats was
+	JOIN workspaces w ON (
+		w.id = was.workspace_id
+		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
+	)
+	-- This table contains both 1 minute entries and >1 minute entries,
+	-- to calculate this with our uniqueness constraints, we generate series
+	-- for the longer intervals.
+	CROSS JOIN LATERAL generate_series(
+		date_trunc('minute', was.session_started_at),
+		-- Subtract 1 microsecond to avoid creating an extra series.
+		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
+		'1 minute'::interval
+	) s(start_time)
+	WHERE
+		s.start_time >= $2::timestamptz
+		-- Subtract one minute because the series only contains the start time.
+		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
+	GROUP BY s.start_time, w.template_id, was.user_id
+), session_stats AS (
+	SELECT
+		date_trunc('minute', was.created_at) as start_time,
+		was.user_id,
+		was.template_id,
+		CASE WHEN
+			SUM(was.session_count_vscode) > 0 OR
+			SUM(was.session_count_jetbrains) > 0 OR
+			SUM(was.session_count_reconnecting_pty) > 0 OR
+			SUM(was.session_count_ssh) > 0
+		THEN 60 ELSE 0 END as seconds
+	FROM workspace_agent_stats was
+	WHERE
+		was.created_at >= $2::timestamptz
+		AND was.created_at < $3::timestamptz
+		AND was.connection_count > 0
+		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN was.template_id = ANY($1::uuid[]) ELSE TRUE END
+	GROUP BY date_trunc('minute', was.created_at), was.user_id, was.template_id
+), combined_stats AS (
+	SELECT
+		user_id,
+		template_id,
+		start_time,
+		seconds
+	FROM app_stats
+	UNION
+	SELECT
+		user_id,
+		template_id,
+		start_time,
+		seconds
+	FROM session_stats
+)
+SELECT
+	users.id as user_id,
+	users.username,
+	users.avatar_url,
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	SUM(seconds) AS usage_seconds
+FROM combined_stats
+JOIN users ON (users.id = combined_stats.user_id)
+GROUP BY users.id, username, avatar_url
+ORDER BY user_id ASC
+`
+
+type GetUserActivityInsightsParams struct {
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+}
+
+type GetUserActivityInsightsRow struct {
+	UserID       uuid.UUID   `db:"user_id" json:"user_id"`
+	Username     string      `db:"username" json:"username"`
+	AvatarURL    string      `db:"avatar_url" json:"avatar_url"`
+	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
+	UsageSeconds int64       `db:"usage_seconds" json:"usage_seconds"`
+}
+
+// GetUserActivityInsights returns the ranking with top active users.
+// The result can be filtered on template_ids, meaning only user data from workspaces
+// based on those templates will be included.
+// Note: When selecting data from multiple templates or the entire deployment,
+// be aware that it may lead to an increase in "usage" numbers (cumulative). In such cases,
+// users may be counted multiple times for the same time interval if they have used multiple templates
+// simultaneously.
+func (q *sqlQuerier) GetUserActivityInsights(ctx context.Context, arg GetUserActivityInsightsParams) ([]GetUserActivityInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserActivityInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserActivityInsightsRow
+	for rows.Next() {
+		var i GetUserActivityInsightsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.AvatarURL,
+			pq.Array(&i.TemplateIDs),
+			&i.UsageSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
+SELECT
+	workspace_agent_stats.user_id,
+	users.username,
+	users.avatar_url,
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+FROM workspace_agent_stats
+JOIN users ON (users.id = workspace_agent_stats.user_id)
+WHERE
+	workspace_agent_stats.created_at >= $1
+	AND workspace_agent_stats.created_at < $2
+	AND workspace_agent_stats.connection_median_latency_ms > 0
+	AND workspace_agent_stats.connection_count > 0
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY workspace_agent_stats.user_id, users.username, users.avatar_url
+ORDER BY user_id ASC
+`
+
+type GetUserLatencyInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetUserLatencyInsightsRow struct {
+	UserID                       uuid.UUID   `db:"user_id" json:"user_id"`
+	Username                     string      `db:"username" json:"username"`
+	AvatarURL                    string      `db:"avatar_url" json:"avatar_url"`
+	TemplateIDs                  []uuid.UUID `db:"template_ids" json:"template_ids"`
+	WorkspaceConnectionLatency50 float64     `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64     `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+}
+
+// GetUserLatencyInsights returns the median and 95th percentile connection
+// latency that users have experienced. The result can be filtered on
+// template_ids, meaning only user data from workspaces based on those templates
+// will be included.
+func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLatencyInsightsParams) ([]GetUserLatencyInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLatencyInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserLatencyInsightsRow
+	for rows.Next() {
+		var i GetUserLatencyInsightsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.AvatarURL,
+			pq.Array(&i.TemplateIDs),
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteLicense = `-- name: DeleteLicense :one
+DELETE
+FROM licenses
+WHERE id = $1
+RETURNING id
+`
+
+func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
+	row := q.db.QueryRowContext(ctx, deleteLicense, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getLicenseByID = `-- name: GetLicenseByID :one
+SELECT
+	id, uploaded_at, jwt, exp, uuid
+FROM
+	licenses
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, error) {
+	row := q.db.QueryRowContext(ctx, getLicenseByID, id)
+	var i License
+	err := row.Scan(
+		&i.ID,
+		&i.UploadedAt,
+		&i.JWT,
+		&i.Exp,
+		&i.UUID,
+	)
+	return i, err
+}
+
+const getLicenses = `-- name: GetLicenses :many
+SELECT id, uploaded_at, jwt, exp, uuid
+FROM licenses
+ORDER BY (id)
+`
+
+func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
+	rows, err := q.db.QueryContext(ctx, getLicenses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []License
+	for rows.Next() {
+		var i License
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploadedAt,
+			&i.JWT,
+			&i.Exp,
+			&i.UUID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnexpiredLicenses = `-- name: GetUnexpiredLicenses :many
+SELECT id, uploaded_at, jwt, exp, uuid
+FROM licenses
+WHERE exp > NOW()
+ORDER BY (id)
+`
+
+func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error) {
+	rows, err := q.db.QueryContext(ctx, getUnexpiredLicenses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []License
+	for rows.Next() {
+		var i License
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploadedAt,
+			&i.JWT,
+			&i.Exp,
+			&i.UUID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertLicense = `-- name: InsertLicense :one
+INSERT INTO
+	licenses (
+	uploaded_at,
+	jwt,
+	exp,
+	uuid
+)
+VALUES
+	($1, $2, $3, $4) RETURNING id, uploaded_at, jwt, exp, uuid
+`
+
+type InsertLicenseParams struct {
+	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
+	JWT        string    `db:"jwt" json:"jwt"`
+	Exp        time.Time `db:"exp" json:"exp"`
+	UUID       uuid.UUID `db:"uuid" json:"uuid"`
+}
+
+func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
+	row := q.db.QueryRowContext(ctx, insertLicense,
+		arg.UploadedAt,
+		arg.JWT,
+		arg.Exp,
+		arg.UUID,
+	)
+	var i License
+	err := row.Scan(
+		&i.ID,
+		&i.UploadedAt,
+		&i.JWT,
+		&i.Exp,
+		&i.UUID,
+	)
+	return i, err
+}
+
+const countLoginFailuresByUserID = `-- name: CountLoginFailuresByUserID :one
+SELECT
+	COALESCE(SUM(failed_attempts), 0) :: bigint
+FROM
+	login_failures
+WHERE
+	user_id = $1
+	AND last_failed_at >= $2
+`
+
+type CountLoginFailuresByUserIDParams struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Since  time.Time `db:"since" json:"since"`
+}
+
+// Counts the failed logins of the user from all IP addresses since the given
+// time.
+func (q *sqlQuerier) CountLoginFailuresByUserID(ctx context.Context, arg CountLoginFailuresByUserIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countLoginFailuresByUserID, arg.UserID, arg.Since)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const deleteLoginFailure = `-- name: DeleteLoginFailure :exec
+DELETE FROM
+	login_failures
+WHERE
+	user_id = $1
+	AND ip_address = $2
+`
+
+type DeleteLoginFailureParams struct {
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+}
+
+func (q *sqlQuerier) DeleteLoginFailure(ctx context.Context, arg DeleteLoginFailureParams) error {
+	_, err := q.db.ExecContext(ctx, deleteLoginFailure, arg.UserID, arg.IPAddress)
+	return err
+}
+
+const deleteOldLoginFailures = `-- name: DeleteOldLoginFailures :exec
+WITH lockouts AS (
+	DELETE FROM
+		user_lockouts
+	WHERE
+		locked_until < $1
+), unknown_failures AS (
+	DELETE FROM
+		unknown_login_failures
+	WHERE
+		last_failed_at < $1
+)
+DELETE FROM
+	login_failures
+WHERE
+	last_failed_at < $1
+`
+
+func (q *sqlQuerier) DeleteOldLoginFailures(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldLoginFailures, beforeTime)
+	return err
+}
+
+const deleteUnknownLoginFailure = `-- name: DeleteUnknownLoginFailure :exec
+DELETE FROM
+	unknown_login_failures
+WHERE
+	login_hash = $1
+	AND ip_address = $2
+`
+
+type DeleteUnknownLoginFailureParams struct {
+	LoginHash []byte      `db:"login_hash" json:"login_hash"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+}
+
+func (q *sqlQuerier) DeleteUnknownLoginFailure(ctx context.Context, arg DeleteUnknownLoginFailureParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUnknownLoginFailure, arg.LoginHash, arg.IPAddress)
+	return err
+}
+
+const deleteUserLockout = `-- name: DeleteUserLockout :exec
+WITH failures AS (
+	DELETE FROM
+		login_failures
+	WHERE
+		login_failures.user_id = $1
+)
+DELETE FROM
+	user_lockouts
+WHERE
+	user_lockouts.user_id = $1
+`
+
+// Unlocks the user and forgets their failed logins, so that they aren't
+// locked out again by their next failed attempt.
+func (q *sqlQuerier) DeleteUserLockout(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserLockout, userID)
+	return err
+}
+
+const getLoginFailure = `-- name: GetLoginFailure :one
+SELECT
+	user_id, ip_address, failed_attempts, last_failed_at
+FROM
+	login_failures
+WHERE
+	user_id = $1
+	AND ip_address = $2
+`
+
+type GetLoginFailureParams struct {
+	UserID    uuid.UUID   `db:"user_id" json:"user_id"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+}
+
+func (q *sqlQuerier) GetLoginFailure(ctx context.Context, arg GetLoginFailureParams) (LoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, getLoginFailure, arg.UserID, arg.IPAddress)
+	var i LoginFailure
+	err := row.Scan(
+		&i.UserID,
+		&i.IPAddress,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const getUnknownLoginFailure = `-- name: GetUnknownLoginFailure :one
+SELECT
+	login_hash, ip_address, failed_attempts, last_failed_at
+FROM
+	unknown_login_failures
+WHERE
+	login_hash = $1
+	AND ip_address = $2
+`
+
+type GetUnknownLoginFailureParams struct {
+	LoginHash []byte      `db:"login_hash" json:"login_hash"`
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
+}
+
+func (q *sqlQuerier) GetUnknownLoginFailure(ctx context.Context, arg GetUnknownLoginFailureParams) (UnknownLoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, getUnknownLoginFailure, arg.LoginHash, arg.IPAddress)
+	var i UnknownLoginFailure
+	err := row.Scan(
+		&i.LoginHash,
+		&i.IPAddress,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const getUnknownLoginFailureTotals = `-- name: GetUnknownLoginFailureTotals :one
+SELECT
+	COALESCE(SUM(failed_attempts), 0) :: bigint AS failed_attempts,
+	COALESCE(MAX(last_failed_at), '0001-01-01 00:00:00+00') :: timestamptz AS last_failed_at
+FROM
+	unknown_login_failures
+WHERE
+	login_hash = $1
+	AND last_failed_at >= $2
+`
+
+type GetUnknownLoginFailureTotalsParams struct {
+	LoginHash []byte    `db:"login_hash" json:"login_hash"`
+	Since     time.Time `db:"since" json:"since"`
+}
+
+type GetUnknownLoginFailureTotalsRow struct {
+	FailedAttempts int64     `db:"failed_attempts" json:"failed_attempts"`
+	LastFailedAt   time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+// Sums up the failed logins for an email or username that doesn't belong to a
+// user from all IP addresses since the given time, and returns when the last
+// one happened.
+func (q *sqlQuerier) GetUnknownLoginFailureTotals(ctx context.Context, arg GetUnknownLoginFailureTotalsParams) (GetUnknownLoginFailureTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUnknownLoginFailureTotals, arg.LoginHash, arg.Since)
+	var i GetUnknownLoginFailureTotalsRow
+	err := row.Scan(&i.FailedAttempts, &i.LastFailedAt)
+	return i, err
+}
+
+const getUserLockoutByUserID = `-- name: GetUserLockoutByUserID :one
+SELECT
+	user_id, failed_attempts, locked_at, locked_until
+FROM
+	user_lockouts
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLockoutByUserID(ctx context.Context, userID uuid.UUID) (UserLockout, error) {
+	row := q.db.QueryRowContext(ctx, getUserLockoutByUserID, userID)
+	var i UserLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.LockedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const getUserLockouts = `-- name: GetUserLockouts :many
+SELECT
+	user_lockouts.user_id, user_lockouts.failed_attempts, user_lockouts.locked_at, user_lockouts.locked_until,
+	users.username
+FROM
+	user_lockouts
+JOIN
+	users ON users.id = user_lockouts.user_id
+WHERE
+	user_lockouts.locked_until > $1
+ORDER BY
+	user_lockouts.locked_at DESC
+`
+
+type GetUserLockoutsRow struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LockedAt       time.Time `db:"locked_at" json:"locked_at"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+	Username       string    `db:"username" json:"username"`
+}
+
+// Returns the users that are locked out at the given time.
+func (q *sqlQuerier) GetUserLockouts(ctx context.Context, now time.Time) ([]GetUserLockoutsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLockouts, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserLockoutsRow
+	for rows.Next() {
+		var i GetUserLockoutsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FailedAttempts,
+			&i.LockedAt,
+			&i.LockedUntil,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordLoginAttempt = `-- name: RecordLoginAttempt :one
+INSERT INTO
+	login_failures (user_id, ip_address, failed_attempts, last_failed_at)
+VALUES
+	($1, $2, 1, $3)
+ON CONFLICT
+	(user_id, ip_address)
+DO UPDATE SET
+	failed_attempts = CASE
+		WHEN login_failures.last_failed_at < $4 :: timestamptz THEN 1
+		ELSE login_failures.failed_attempts + 1
+	END,
+	last_failed_at = $3
+WHERE
+	login_failures.last_failed_at < $4 :: timestamptz
+	OR login_failures.failed_attempts < $5 :: bigint
+	OR login_failures.last_failed_at + make_interval(secs => LEAST(2 ^ LEAST(GREATEST(login_failures.failed_attempts - $5 :: bigint, 0), 30), $6 :: bigint)) <= $3 :: timestamptz
+RETURNING
+	user_id, ip_address, failed_attempts, last_failed_at
+`
+
+type RecordLoginAttemptParams struct {
+	UserID            uuid.UUID   `db:"user_id" json:"user_id"`
+	IPAddress         pqtype.Inet `db:"ip_address" json:"ip_address"`
+	AttemptedAt       time.Time   `db:"attempted_at" json:"attempted_at"`
+	ResetBefore       time.Time   `db:"reset_before" json:"reset_before"`
+	BackoffThreshold  int64       `db:"backoff_threshold" json:"backoff_threshold"`
+	BackoffMaxSeconds int64       `db:"backoff_max_seconds" json:"backoff_max_seconds"`
+}
+
+// Counts a login attempt of the user from the IP address before the password
+// is checked, and forgotten again if it succeeds. The count starts over if the
+// previous attempt was before reset_before. Once the count reaches
+// backoff_threshold, the attempt is only counted if the previous one was long
+// enough ago, and no row is returned otherwise. Checking and counting in one
+// statement keeps concurrent attempts from all getting through.
+func (q *sqlQuerier) RecordLoginAttempt(ctx context.Context, arg RecordLoginAttemptParams) (LoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, recordLoginAttempt,
+		arg.UserID,
+		arg.IPAddress,
+		arg.AttemptedAt,
+		arg.ResetBefore,
+		arg.BackoffThreshold,
+		arg.BackoffMaxSeconds,
+	)
+	var i LoginFailure
+	err := row.Scan(
+		&i.UserID,
+		&i.IPAddress,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const recordUnknownLoginAttempt = `-- name: RecordUnknownLoginAttempt :one
+INSERT INTO
+	unknown_login_failures (login_hash, ip_address, failed_attempts, last_failed_at)
+VALUES
+	($1, $2, 1, $3)
+ON CONFLICT
+	(login_hash, ip_address)
+DO UPDATE SET
+	failed_attempts = CASE
+		WHEN unknown_login_failures.last_failed_at < $4 :: timestamptz THEN 1
+		ELSE unknown_login_failures.failed_attempts + 1
+	END,
+	last_failed_at = $3
+WHERE
+	unknown_login_failures.last_failed_at < $4 :: timestamptz
+	OR unknown_login_failures.failed_attempts < $5 :: bigint
+	OR unknown_login_failures.last_failed_at + make_interval(secs => LEAST(2 ^ LEAST(GREATEST(unknown_login_failures.failed_attempts - $5 :: bigint, 0), 30), $6 :: bigint)) <= $3 :: timestamptz
+RETURNING
+	login_hash, ip_address, failed_attempts, last_failed_at
+`
+
+type RecordUnknownLoginAttemptParams struct {
+	LoginHash         []byte      `db:"login_hash" json:"login_hash"`
+	IPAddress         pqtype.Inet `db:"ip_address" json:"ip_address"`
+	AttemptedAt       time.Time   `db:"attempted_at" json:"attempted_at"`
+	ResetBefore       time.Time   `db:"reset_before" json:"reset_before"`
+	BackoffThreshold  int64       `db:"backoff_threshold" json:"backoff_threshold"`
+	BackoffMaxSeconds int64       `db:"backoff_max_seconds" json:"backoff_max_seconds"`
+}
+
+// Counts a login attempt for an email or username that doesn't belong to a
+// user, or for a directory login, like RecordLoginAttempt. No row is returned
+// while the IP address is backing off.
+func (q *sqlQuerier) RecordUnknownLoginAttempt(ctx context.Context, arg RecordUnknownLoginAttemptParams) (UnknownLoginFailure, error) {
+	row := q.db.QueryRowContext(ctx, recordUnknownLoginAttempt,
+		arg.LoginHash,
+		arg.IPAddress,
+		arg.AttemptedAt,
+		arg.ResetBefore,
+		arg.BackoffThreshold,
+		arg.BackoffMaxSeconds,
+	)
+	var i UnknownLoginFailure
+	err := row.Scan(
+		&i.LoginHash,
+		&i.IPAddress,
+		&i.FailedAttempts,
+		&i.LastFailedAt,
+	)
+	return i, err
+}
+
+const upsertUserLockout = `-- name: UpsertUserLockout :one
+INSERT INTO
+	user_lockouts (user_id, failed_attempts, locked_at, locked_until)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	failed_attempts = $2,
+	locked_at = $3,
+	locked_until = $4
+RETURNING
+	user_id, failed_attempts, locked_at, locked_until
+`
+
+type UpsertUserLockoutParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	FailedAttempts int32     `db:"failed_attempts" json:"failed_attempts"`
+	LockedAt       time.Time `db:"locked_at" json:"locked_at"`
+	LockedUntil    time.Time `db:"locked_until" json:"locked_until"`
+}
+
+func (q *sqlQuerier) UpsertUserLockout(ctx context.Context, arg UpsertUserLockoutParams) (UserLockout, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserLockout,
+		arg.UserID,
+		arg.FailedAttempts,
+		arg.LockedAt,
+		arg.LockedUntil,
+	)
+	var i UserLockout
+	err := row.Scan(
+		&i.UserID,
+		&i.FailedAttempts,
+		&i.LockedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const acquireLock = `-- name: AcquireLock :exec
+SELECT pg_advisory_xact_lock($1)
+`
+
+// Blocks until the lock is acquired.
+//
+// This must be called from within a transaction. The lock will be automatically
+// released when the transaction ends.
+func (q *sqlQuerier) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
+	_, err := q.db.ExecContext(ctx, acquireLock, pgAdvisoryXactLock)
+	return err
+}
+
+const tryAcquireLock = `-- name: TryAcquireLock :one
+SELECT pg_try_advisory_xact_lock($1)
+`
+
+// Non blocking lock. Returns true if the lock was acquired, false otherwise.
+//
+// This must be called from within a transaction. The lock will be automatically
+// released when the transaction ends.
+func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, tryAcquireLock, pgTryAdvisoryXactLock)
+	var pg_try_advisory_xact_lock bool
+	err := row.Scan(&pg_try_advisory_xact_lock)
+	return pg_try_advisory_xact_lock, err
+}
+
+const acquireNotificationMessages = `-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	next_attempt_at = $1 :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.next_attempt_at <= $2 :: timestamptz
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			$3 :: int
+	) RETURNING id, user_id, template, labels, attempts, last_error, created_at, sent_at, next_attempt_at, method
+`
+
+type AcquireNotificationMessagesParams struct {
+	LeaseUntil time.Time `db:"lease_until" json:"lease_until"`
+	Now        time.Time `db:"now" json:"now"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// Acquires notifications that are due to be sent. The next attempt is pushed
+// back to lease_until so that a crashed replica does not hold on to the
+// notification forever, and SKIP LOCKED prevents multiple replicas from
+// acquiring the same notification.
+func (q *sqlQuerier) AcquireNotificationMessages(ctx context.Context, arg AcquireNotificationMessagesParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, acquireNotificationMessages, arg.LeaseUntil, arg.Now, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Labels,
+			&i.Attempts,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.NextAttemptAt,
+			&i.Method,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUnreadInboxNotificationsByUserID = `-- name: CountUnreadInboxNotificationsByUserID :one
+SELECT
+	COUNT(*)
+FROM
+	inbox_notifications
+WHERE
+	user_id = $1
+	AND read_at IS NULL
+`
+
+func (q *sqlQuerier) CountUnreadInboxNotificationsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnreadInboxNotificationsByUserID, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getInboxNotificationsByUserID = `-- name: GetInboxNotificationsByUserID :many
+SELECT
+	id, user_id, template, title, content, created_at, read_at
+FROM
+	inbox_notifications
+WHERE
+	user_id = $1
+	AND CASE
+		-- The pagination cursor is the last ID of the previous page. The
+		-- query is ordered by newest first, so select all older rows.
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			(created_at, id) < (
+				SELECT
+					created_at, id
+				FROM
+					inbox_notifications
+				WHERE
+					id = $2
+			)
+		)
+		ELSE true
+	END
+	AND CASE
+		WHEN $3 :: boolean THEN read_at IS NULL
+		ELSE true
+	END
+ORDER BY
+	created_at DESC, id DESC
+OFFSET
+	$4
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($5 :: int, 0)
+`
+
+type GetInboxNotificationsByUserIDParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	AfterID    uuid.UUID `db:"after_id" json:"after_id"`
+	UnreadOnly bool      `db:"unread_only" json:"unread_only"`
+	OffsetOpt  int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetInboxNotificationsByUserID(ctx context.Context, arg GetInboxNotificationsByUserIDParams) ([]InboxNotification, error) {
+	rows, err := q.db.QueryContext(ctx, getInboxNotificationsByUserID,
+		arg.UserID,
+		arg.AfterID,
+		arg.UnreadOnly,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InboxNotification
+	for rows.Next() {
+		var i InboxNotification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationMessagesByUserID = `-- name: GetNotificationMessagesByUserID :many
+SELECT
+	id, user_id, template, labels, attempts, last_error, created_at, sent_at, next_attempt_at, method
+FROM
+	notification_messages
+WHERE
+	user_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($2 :: int, 0)
+`
+
+type GetNotificationMessagesByUserIDParams struct {
+	UserID   uuid.UUID `db:"user_id" json:"user_id"`
+	LimitOpt int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetNotificationMessagesByUserID(ctx context.Context, arg GetNotificationMessagesByUserIDParams) ([]NotificationMessage, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationMessagesByUserID, arg.UserID, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationMessage
+	for rows.Next() {
+		var i NotificationMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Template,
+			&i.Labels,
+			&i.Attempts,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.NextAttemptAt,
+			&i.Method,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationPreferencesByUserID = `-- name: GetNotificationPreferencesByUserID :many
+SELECT
+	user_id, template, disabled, updated_at
+FROM
+	notification_preferences
+WHERE
+	user_id = $1
+ORDER BY
+	template ASC
+`
+
+func (q *sqlQuerier) GetNotificationPreferencesByUserID(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationPreferencesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Template,
+			&i.Disabled,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -754,351 +4109,373 @@ func (q *sqlQuerier) GetDBCryptKeys(ctx context.Context) ([]DBCryptKey, error) {
 	return items, nil
 }
 
-const insertDBCryptKey = `-- name: InsertDBCryptKey :exec
-INSERT INTO dbcrypt_keys
-	(number, active_key_digest, created_at, test)
-VALUES ($1::int, $2::text, CURRENT_TIMESTAMP, $3::text)
+const insertInboxNotification = `-- name: InsertInboxNotification :one
+INSERT INTO
+	inbox_notifications (id, user_id, template, title, content, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, user_id, template, title, content, created_at, read_at
 `
 
-type InsertDBCryptKeyParams struct {
-	Number          int32  `db:"number" json:"number"`
-	ActiveKeyDigest string `db:"active_key_digest" json:"active_key_digest"`
-	Test            string `db:"test" json:"test"`
+type InsertInboxNotificationParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Title     string    `db:"title" json:"title"`
+	Content   string    `db:"content" json:"content"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertDBCryptKey(ctx context.Context, arg InsertDBCryptKeyParams) error {
-	_, err := q.db.ExecContext(ctx, insertDBCryptKey, arg.Number, arg.ActiveKeyDigest, arg.Test)
-	return err
+func (q *sqlQuerier) InsertInboxNotification(ctx context.Context, arg InsertInboxNotificationParams) (InboxNotification, error) {
+	row := q.db.QueryRowContext(ctx, insertInboxNotification,
+		arg.ID,
+		arg.UserID,
+		arg.Template,
+		arg.Title,
+		arg.Content,
+		arg.CreatedAt,
+	)
+	var i InboxNotification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Template,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.ReadAt,
+	)
+	return i, err
 }
 
-const revokeDBCryptKey = `-- name: RevokeDBCryptKey :exec
-UPDATE dbcrypt_keys
+const insertNotificationMessage = `-- name: InsertNotificationMessage :one
+INSERT INTO
+	notification_messages (id, user_id, template, labels, created_at, next_attempt_at, method)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, user_id, template, labels, attempts, last_error, created_at, sent_at, next_attempt_at, method
+`
+
+type InsertNotificationMessageParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	UserID        uuid.UUID       `db:"user_id" json:"user_id"`
+	Template      string          `db:"template" json:"template"`
+	Labels        json.RawMessage `db:"labels" json:"labels"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	NextAttemptAt sql.NullTime    `db:"next_attempt_at" json:"next_attempt_at"`
+	Method        string          `db:"method" json:"method"`
+}
+
+func (q *sqlQuerier) InsertNotificationMessage(ctx context.Context, arg InsertNotificationMessageParams) (NotificationMessage, error) {
+	row := q.db.QueryRowContext(ctx, insertNotificationMessage,
+		arg.ID,
+		arg.UserID,
+		arg.Template,
+		arg.Labels,
+		arg.CreatedAt,
+		arg.NextAttemptAt,
+		arg.Method,
+	)
+	var i NotificationMessage
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Template,
+		&i.Labels,
+		&i.Attempts,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.SentAt,
+		&i.NextAttemptAt,
+		&i.Method,
+	)
+	return i, err
+}
+
+const updateInboxNotificationsReadByUserID = `-- name: UpdateInboxNotificationsReadByUserID :exec
+UPDATE
+	inbox_notifications
 SET
-	revoked_key_digest = active_key_digest,
-	active_key_digest = revoked_key_digest,
-	revoked_at = CURRENT_TIMESTAMP
+	read_at = $1 :: timestamptz
 WHERE
-	active_key_digest = $1::text
-AND
-	revoked_key_digest IS NULL
+	user_id = $2
+	AND read_at IS NULL
+	AND CASE
+		WHEN cardinality($3 :: uuid[]) > 0 THEN id = ANY($3 :: uuid[])
+		ELSE true
+	END
 `
 
-func (q *sqlQuerier) RevokeDBCryptKey(ctx context.Context, activeKeyDigest string) error {
-	_, err := q.db.ExecContext(ctx, revokeDBCryptKey, activeKeyDigest)
+type UpdateInboxNotificationsReadByUserIDParams struct {
+	ReadAt time.Time   `db:"read_at" json:"read_at"`
+	UserID uuid.UUID   `db:"user_id" json:"user_id"`
+	IDs    []uuid.UUID `db:"ids" json:"ids"`
+}
+
+// Marks the listed notifications of the user as read, or all of them if none
+// are listed.
+func (q *sqlQuerier) UpdateInboxNotificationsReadByUserID(ctx context.Context, arg UpdateInboxNotificationsReadByUserIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateInboxNotificationsReadByUserID, arg.ReadAt, arg.UserID, pq.Array(arg.IDs))
 	return err
 }
 
-const deleteExternalAuthLink = `-- name: DeleteExternalAuthLink :exec
-DELETE FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
+const updateNotificationMessageByID = `-- name: UpdateNotificationMessageByID :exec
+UPDATE
+	notification_messages
+SET
+	attempts = $2,
+	last_error = $3,
+	sent_at = $4,
+	next_attempt_at = $5
+WHERE
+	id = $1
 `
 
-type DeleteExternalAuthLinkParams struct {
-	ProviderID string    `db:"provider_id" json:"provider_id"`
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+type UpdateNotificationMessageByIDParams struct {
+	ID            uuid.UUID    `db:"id" json:"id"`
+	Attempts      int32        `db:"attempts" json:"attempts"`
+	LastError     string       `db:"last_error" json:"last_error"`
+	SentAt        sql.NullTime `db:"sent_at" json:"sent_at"`
+	NextAttemptAt sql.NullTime `db:"next_attempt_at" json:"next_attempt_at"`
 }
 
-func (q *sqlQuerier) DeleteExternalAuthLink(ctx context.Context, arg DeleteExternalAuthLinkParams) error {
-	_, err := q.db.ExecContext(ctx, deleteExternalAuthLink, arg.ProviderID, arg.UserID)
+func (q *sqlQuerier) UpdateNotificationMessageByID(ctx context.Context, arg UpdateNotificationMessageByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateNotificationMessageByID,
+		arg.ID,
+		arg.Attempts,
+		arg.LastError,
+		arg.SentAt,
+		arg.NextAttemptAt,
+	)
 	return err
 }
 
-const getExternalAuthLink = `-- name: GetExternalAuthLink :one
-SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra FROM external_auth_links WHERE provider_id = $1 AND user_id = $2
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :one
+INSERT INTO
+	notification_preferences (user_id, template, disabled, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, template)
+DO UPDATE SET
+	disabled = $3,
+	updated_at = $4
+RETURNING user_id, template, disabled, updated_at
 `
 
-type GetExternalAuthLinkParams struct {
-	ProviderID string    `db:"provider_id" json:"provider_id"`
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+type UpsertNotificationPreferenceParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Template  string    `db:"template" json:"template"`
+	Disabled  bool      `db:"disabled" json:"disabled"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) GetExternalAuthLink(ctx context.Context, arg GetExternalAuthLinkParams) (ExternalAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, getExternalAuthLink, arg.ProviderID, arg.UserID)
-	var i ExternalAuthLink
+func (q *sqlQuerier) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRowContext(ctx, upsertNotificationPreference,
+		arg.UserID,
+		arg.Template,
+		arg.Disabled,
+		arg.UpdatedAt,
+	)
+	var i NotificationPreference
 	err := row.Scan(
-		&i.ProviderID,
 		&i.UserID,
-		&i.CreatedAt,
+		&i.Template,
+		&i.Disabled,
 		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.OAuthExtra,
 	)
 	return i, err
 }
 
-const getExternalAuthLinksByUserID = `-- name: GetExternalAuthLinksByUserID :many
-SELECT provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra FROM external_auth_links WHERE user_id = $1
+const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
+DELETE FROM oauth2_provider_apps WHERE id = $1
 `
 
-func (q *sqlQuerier) GetExternalAuthLinksByUserID(ctx context.Context, userID uuid.UUID) ([]ExternalAuthLink, error) {
-	rows, err := q.db.QueryContext(ctx, getExternalAuthLinksByUserID, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ExternalAuthLink
-	for rows.Next() {
-		var i ExternalAuthLink
-		if err := rows.Scan(
-			&i.ProviderID,
-			&i.UserID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
-			&i.OAuthExtra,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
+	return err
 }
 
-const insertExternalAuthLink = `-- name: InsertExternalAuthLink :one
-INSERT INTO external_auth_links (
-    provider_id,
-    user_id,
-    created_at,
-    updated_at,
-    oauth_access_token,
-    oauth_access_token_key_id,
-    oauth_refresh_token,
-    oauth_refresh_token_key_id,
-    oauth_expiry,
-	oauth_extra
-) VALUES (
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6,
-    $7,
-    $8,
-    $9,
-	$10
-) RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra
+const deleteOAuth2ProviderAppCodeByID = `-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, nonce, code_challenge
 `
 
-type InsertExternalAuthLinkParams struct {
-	ProviderID             string                `db:"provider_id" json:"provider_id"`
-	UserID                 uuid.UUID             `db:"user_id" json:"user_id"`
-	CreatedAt              time.Time             `db:"created_at" json:"created_at"`
-	UpdatedAt              time.Time             `db:"updated_at" json:"updated_at"`
-	OAuthAccessToken       string                `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString        `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string                `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString        `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time             `db:"oauth_expiry" json:"oauth_expiry"`
-	OAuthExtra             pqtype.NullRawMessage `db:"oauth_extra" json:"oauth_extra"`
-}
-
-func (q *sqlQuerier) InsertExternalAuthLink(ctx context.Context, arg InsertExternalAuthLinkParams) (ExternalAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, insertExternalAuthLink,
-		arg.ProviderID,
-		arg.UserID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.OAuthExtra,
-	)
-	var i ExternalAuthLink
+// DeleteOAuth2ProviderAppCodeByID returns the deleted code, so only one of
+// several concurrent deletes of the same code succeeds.
+func (q *sqlQuerier) DeleteOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, deleteOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
 	err := row.Scan(
-		&i.ProviderID,
-		&i.UserID,
+		&i.ID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.OAuthExtra,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Nonce,
+		&i.CodeChallenge,
 	)
 	return i, err
 }
 
-const updateExternalAuthLink = `-- name: UpdateExternalAuthLink :one
-UPDATE external_auth_links SET
-    updated_at = $3,
-    oauth_access_token = $4,
-    oauth_access_token_key_id = $5,
-    oauth_refresh_token = $6,
-    oauth_refresh_token_key_id = $7,
-    oauth_expiry = $8,
-	oauth_extra = $9
-WHERE provider_id = $1 AND user_id = $2 RETURNING provider_id, user_id, created_at, updated_at, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, oauth_extra
+const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
+DELETE FROM oauth2_provider_app_secrets WHERE id = $1
 `
 
-type UpdateExternalAuthLinkParams struct {
-	ProviderID             string                `db:"provider_id" json:"provider_id"`
-	UserID                 uuid.UUID             `db:"user_id" json:"user_id"`
-	UpdatedAt              time.Time             `db:"updated_at" json:"updated_at"`
-	OAuthAccessToken       string                `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString        `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string                `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString        `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time             `db:"oauth_expiry" json:"oauth_expiry"`
-	OAuthExtra             pqtype.NullRawMessage `db:"oauth_extra" json:"oauth_extra"`
+func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
+	return err
 }
 
-func (q *sqlQuerier) UpdateExternalAuthLink(ctx context.Context, arg UpdateExternalAuthLinkParams) (ExternalAuthLink, error) {
-	row := q.db.QueryRowContext(ctx, updateExternalAuthLink,
-		arg.ProviderID,
-		arg.UserID,
-		arg.UpdatedAt,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.OAuthExtra,
-	)
-	var i ExternalAuthLink
+const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
+	var i OAuth2ProviderApp
 	err := row.Scan(
-		&i.ProviderID,
-		&i.UserID,
+		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.OAuthExtra,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
 	)
 	return i, err
 }
 
-const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
-SELECT
-	hash, created_at, created_by, mimetype, data, id
-FROM
-	files
-WHERE
-	hash = $1
-AND
-	created_by = $2
-LIMIT
-	1
+const getOAuth2ProviderAppCodeByID = `-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, nonce, code_challenge FROM oauth2_provider_app_codes WHERE id = $1
 `
 
-type GetFileByHashAndCreatorParams struct {
-	Hash      string    `db:"hash" json:"hash"`
-	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByID, id)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Nonce,
+		&i.CodeChallenge,
+	)
+	return i, err
 }
 
-func (q *sqlQuerier) GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error) {
-	row := q.db.QueryRowContext(ctx, getFileByHashAndCreator, arg.Hash, arg.CreatedBy)
-	var i File
+const getOAuth2ProviderAppCodeByPrefix = `-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, nonce, code_challenge FROM oauth2_provider_app_codes WHERE secret_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppCodeByPrefix(ctx context.Context, secretPrefix []byte) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppCodeByPrefix, secretPrefix)
+	var i OAuth2ProviderAppCode
 	err := row.Scan(
-		&i.Hash,
-		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
 		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Nonce,
+		&i.CodeChallenge,
 	)
 	return i, err
 }
 
-const getFileByID = `-- name: GetFileByID :one
-SELECT
-	hash, created_at, created_by, mimetype, data, id
-FROM
-	files
-WHERE
-	id = $1
-LIMIT
-	1
+const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE id = $1
 `
 
-func (q *sqlQuerier) GetFileByID(ctx context.Context, id uuid.UUID) (File, error) {
-	row := q.db.QueryRowContext(ctx, getFileByID, id)
-	var i File
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
+	var i OAuth2ProviderAppSecret
 	err := row.Scan(
-		&i.Hash,
-		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
 		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
 	)
 	return i, err
 }
 
-const getFileTemplates = `-- name: GetFileTemplates :many
-SELECT
-	files.id AS file_id,
-	files.created_by AS file_created_by,
-	templates.id AS template_id,
-	templates.organization_id AS template_organization_id,
-	templates.created_by AS template_created_by,
-	templates.user_acl,
-	templates.group_acl
-FROM
-	templates
-INNER JOIN
-	template_versions
-	ON templates.id = template_versions.template_id
-INNER JOIN
-	provisioner_jobs
-	ON job_id = provisioner_jobs.id
-INNER JOIN
-	files
-	ON files.id = provisioner_jobs.file_id
-WHERE
-    -- Only fetch template version associated files.
-	storage_method = 'file'
-	AND provisioner_jobs.type = 'template_version_import'
-	AND file_id = $1
+const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
+SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC
 `
 
-type GetFileTemplatesRow struct {
-	FileID                 uuid.UUID   `db:"file_id" json:"file_id"`
-	FileCreatedBy          uuid.UUID   `db:"file_created_by" json:"file_created_by"`
-	TemplateID             uuid.UUID   `db:"template_id" json:"template_id"`
-	TemplateOrganizationID uuid.UUID   `db:"template_organization_id" json:"template_organization_id"`
-	TemplateCreatedBy      uuid.UUID   `db:"template_created_by" json:"template_created_by"`
-	UserACL                TemplateACL `db:"user_acl" json:"user_acl"`
-	GroupACL               TemplateACL `db:"group_acl" json:"group_acl"`
+func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OAuth2ProviderAppSecret
+	for rows.Next() {
+		var i OAuth2ProviderAppSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.HashedSecret,
+			&i.DisplaySecret,
+			&i.AppID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// Get all templates that use a file.
-func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]GetFileTemplatesRow, error) {
-	rows, err := q.db.QueryContext(ctx, getFileTemplates, fileID)
+const getOAuth2ProviderAppTokenByPrefix = `-- name: GetOAuth2ProviderAppTokenByPrefix :one
+SELECT id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id FROM oauth2_provider_app_tokens WHERE hash_prefix = $1
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderAppTokenByPrefix(ctx context.Context, hashPrefix []byte) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppTokenByPrefix, hashPrefix)
+	var i OAuth2ProviderAppToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
+	)
+	return i, err
+}
+
+const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
+SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+`
+
+func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
+	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetFileTemplatesRow
+	var items []OAuth2ProviderApp
 	for rows.Next() {
-		var i GetFileTemplatesRow
+		var i OAuth2ProviderApp
 		if err := rows.Scan(
-			&i.FileID,
-			&i.FileCreatedBy,
-			&i.TemplateID,
-			&i.TemplateOrganizationID,
-			&i.TemplateCreatedBy,
-			&i.UserACL,
-			&i.GroupACL,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Icon,
+			&i.CallbackURL,
 		); err != nil {
 			return nil, err
 		}
@@ -1113,242 +4490,440 @@ func (q *sqlQuerier) GetFileTemplates(ctx context.Context, fileID uuid.UUID) ([]
 	return items, nil
 }
 
-const insertFile = `-- name: InsertFile :one
-INSERT INTO
-	files (id, hash, created_at, created_by, mimetype, "data")
-VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING hash, created_at, created_by, mimetype, data, id
+const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
+INSERT INTO oauth2_provider_apps (
+    id,
+    created_at,
+    updated_at,
+    name,
+    icon,
+    callback_url
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+) RETURNING id, created_at, updated_at, name, icon, callback_url
+`
+
+type InsertOAuth2ProviderAppParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
+	)
+	var i OAuth2ProviderApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppCode = `-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+    id,
+    created_at,
+    expires_at,
+    secret_prefix,
+    hashed_secret,
+    app_id,
+    user_id,
+    nonce,
+    code_challenge
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING id, created_at, expires_at, secret_prefix, hashed_secret, user_id, app_id, nonce, code_challenge
+`
+
+type InsertOAuth2ProviderAppCodeParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	SecretPrefix  []byte    `db:"secret_prefix" json:"secret_prefix"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	Nonce         string    `db:"nonce" json:"nonce"`
+	CodeChallenge string    `db:"code_challenge" json:"code_challenge"`
+}
+
+func (q *sqlQuerier) InsertOAuth2ProviderAppCode(ctx context.Context, arg InsertOAuth2ProviderAppCodeParams) (OAuth2ProviderAppCode, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppCode,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.SecretPrefix,
+		arg.HashedSecret,
+		arg.AppID,
+		arg.UserID,
+		arg.Nonce,
+		arg.CodeChallenge,
+	)
+	var i OAuth2ProviderAppCode
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.SecretPrefix,
+		&i.HashedSecret,
+		&i.UserID,
+		&i.AppID,
+		&i.Nonce,
+		&i.CodeChallenge,
+	)
+	return i, err
+}
+
+const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
+INSERT INTO oauth2_provider_app_secrets (
+    id,
+    created_at,
+    hashed_secret,
+    display_secret,
+    app_id
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
 `
 
-type InsertFileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Hash      string    `db:"hash" json:"hash"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	CreatedBy uuid.UUID `db:"created_by" json:"created_by"`
-	Mimetype  string    `db:"mimetype" json:"mimetype"`
-	Data      []byte    `db:"data" json:"data"`
+type InsertOAuth2ProviderAppSecretParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
+	DisplaySecret string    `db:"display_secret" json:"display_secret"`
+	AppID         uuid.UUID `db:"app_id" json:"app_id"`
 }
 
-func (q *sqlQuerier) InsertFile(ctx context.Context, arg InsertFileParams) (File, error) {
-	row := q.db.QueryRowContext(ctx, insertFile,
+func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
 		arg.ID,
-		arg.Hash,
 		arg.CreatedAt,
-		arg.CreatedBy,
-		arg.Mimetype,
-		arg.Data,
+		arg.HashedSecret,
+		arg.DisplaySecret,
+		arg.AppID,
 	)
-	var i File
+	var i OAuth2ProviderAppSecret
 	err := row.Scan(
-		&i.Hash,
-		&i.CreatedAt,
-		&i.CreatedBy,
-		&i.Mimetype,
-		&i.Data,
 		&i.ID,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
 	)
 	return i, err
 }
 
-const deleteGitSSHKey = `-- name: DeleteGitSSHKey :exec
-DELETE FROM
-	gitsshkeys
-WHERE
-	user_id = $1
+const insertOAuth2ProviderAppToken = `-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+    id,
+    created_at,
+    expires_at,
+    hash_prefix,
+    refresh_hash,
+    app_secret_id,
+    api_key_id
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING id, created_at, expires_at, hash_prefix, refresh_hash, app_secret_id, api_key_id
 `
 
-func (q *sqlQuerier) DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteGitSSHKey, userID)
-	return err
+type InsertOAuth2ProviderAppTokenParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
+	HashPrefix  []byte    `db:"hash_prefix" json:"hash_prefix"`
+	RefreshHash []byte    `db:"refresh_hash" json:"refresh_hash"`
+	AppSecretID uuid.UUID `db:"app_secret_id" json:"app_secret_id"`
+	APIKeyID    string    `db:"api_key_id" json:"api_key_id"`
 }
 
-const getGitSSHKey = `-- name: GetGitSSHKey :one
-SELECT
-	user_id, created_at, updated_at, private_key, public_key
-FROM
-	gitsshkeys
-WHERE
-	user_id = $1
-`
-
-func (q *sqlQuerier) GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, getGitSSHKey, userID)
-	var i GitSSHKey
+func (q *sqlQuerier) InsertOAuth2ProviderAppToken(ctx context.Context, arg InsertOAuth2ProviderAppTokenParams) (OAuth2ProviderAppToken, error) {
+	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppToken,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.HashPrefix,
+		arg.RefreshHash,
+		arg.AppSecretID,
+		arg.APIKeyID,
+	)
+	var i OAuth2ProviderAppToken
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
+		&i.ExpiresAt,
+		&i.HashPrefix,
+		&i.RefreshHash,
+		&i.AppSecretID,
+		&i.APIKeyID,
 	)
 	return i, err
 }
 
-const insertGitSSHKey = `-- name: InsertGitSSHKey :one
-INSERT INTO
-	gitsshkeys (
-		user_id,
-		created_at,
-		updated_at,
-		private_key,
-		public_key
-	)
-VALUES
-	($1, $2, $3, $4, $5) RETURNING user_id, created_at, updated_at, private_key, public_key
+const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
+UPDATE oauth2_provider_apps SET
+    updated_at = $2,
+    name = $3,
+    icon = $4,
+    callback_url = $5
+WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url
 `
 
-type InsertGitSSHKeyParams struct {
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	PrivateKey string    `db:"private_key" json:"private_key"`
-	PublicKey  string    `db:"public_key" json:"public_key"`
+type UpdateOAuth2ProviderAppByIDParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	Name        string    `db:"name" json:"name"`
+	Icon        string    `db:"icon" json:"icon"`
+	CallbackURL string    `db:"callback_url" json:"callback_url"`
 }
 
-func (q *sqlQuerier) InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, insertGitSSHKey,
-		arg.UserID,
-		arg.CreatedAt,
+func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+		arg.ID,
 		arg.UpdatedAt,
-		arg.PrivateKey,
-		arg.PublicKey,
+		arg.Name,
+		arg.Icon,
+		arg.CallbackURL,
 	)
-	var i GitSSHKey
+	var i OAuth2ProviderApp
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
+		&i.Name,
+		&i.Icon,
+		&i.CallbackURL,
 	)
 	return i, err
 }
 
-const updateGitSSHKey = `-- name: UpdateGitSSHKey :one
-UPDATE
-	gitsshkeys
-SET
-	updated_at = $2,
-	private_key = $3,
-	public_key = $4
-WHERE
-	user_id = $1
-RETURNING
-	user_id, created_at, updated_at, private_key, public_key
+const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
+UPDATE oauth2_provider_app_secrets SET
+    last_used_at = $2
+WHERE id = $1 RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
 `
 
-type UpdateGitSSHKeyParams struct {
-	UserID     uuid.UUID `db:"user_id" json:"user_id"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	PrivateKey string    `db:"private_key" json:"private_key"`
-	PublicKey  string    `db:"public_key" json:"public_key"`
+type UpdateOAuth2ProviderAppSecretByIDParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
 }
 
-func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error) {
-	row := q.db.QueryRowContext(ctx, updateGitSSHKey,
-		arg.UserID,
-		arg.UpdatedAt,
-		arg.PrivateKey,
-		arg.PublicKey,
-	)
-	var i GitSSHKey
+func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error) {
+	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppSecretByID, arg.ID, arg.LastUsedAt)
+	var i OAuth2ProviderAppSecret
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.PrivateKey,
-		&i.PublicKey,
+		&i.LastUsedAt,
+		&i.HashedSecret,
+		&i.DisplaySecret,
+		&i.AppID,
 	)
 	return i, err
 }
 
-const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :exec
 DELETE FROM
-	group_members
+	organization_members
 WHERE
-	user_id = $1 AND
-	group_id = $2
+	organization_id = $1
+	AND user_id = $2
 `
 
-type DeleteGroupMemberFromGroupParams struct {
-	UserID  uuid.UUID `db:"user_id" json:"user_id"`
-	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+type DeleteOrganizationMemberParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) DeleteOrganizationMember(ctx context.Context, arg DeleteOrganizationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationMember, arg.OrganizationID, arg.UserID)
+	return err
+}
+
+const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+SELECT
+    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+FROM
+    organization_members
+WHERE
+    user_id = ANY($1 :: uuid [ ])
+GROUP BY
+    user_id
+`
+
+type GetOrganizationIDsByMemberIDsRow struct {
+	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
+	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
+}
+
+func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrganizationIDsByMemberIDsRow
+	for rows.Next() {
+		var i GetOrganizationIDsByMemberIDsRow
+		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+SELECT
+	user_id, organization_id, created_at, updated_at, roles
+FROM
+	organization_members
+WHERE
+	organization_id = $1
+	AND user_id = $2
+LIMIT
+	1
+`
+
+type GetOrganizationMemberByUserIDParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
-	return err
+func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
+	var i OrganizationMember
+	err := row.Scan(
+		&i.UserID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
+	)
+	return i, err
 }
 
-const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
-DELETE FROM
-	group_members
+const getOrganizationMembers = `-- name: GetOrganizationMembers :many
+SELECT
+	organization_members.user_id, organization_members.organization_id, organization_members.created_at, organization_members.updated_at, organization_members.roles,
+	users.username
+FROM
+	organization_members
+	INNER JOIN users ON users.id = organization_members.user_id
 WHERE
-	group_members.user_id = $1
-	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
+	organization_members.organization_id = $1
+	AND users.deleted = false
+ORDER BY
+	users.username
 `
 
-type DeleteGroupMembersByOrgAndUserParams struct {
+type GetOrganizationMembersRow struct {
 	UserID         uuid.UUID `db:"user_id" json:"user_id"`
 	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	Roles          []string  `db:"roles" json:"roles"`
+	Username       string    `db:"username" json:"username"`
 }
 
-func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
-	return err
+func (q *sqlQuerier) GetOrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]GetOrganizationMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationMembers, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrganizationMembersRow
+	for rows.Next() {
+		var i GetOrganizationMembersRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.OrganizationID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			pq.Array(&i.Roles),
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getGroupMembers = `-- name: GetGroupMembers :many
+const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule, users.theme_preference, users.name
+	user_id, organization_id, created_at, updated_at, roles
 FROM
-	users
-LEFT JOIN
-	group_members
-ON
-	group_members.user_id = users.id AND
-	group_members.group_id = $1
-LEFT JOIN
 	organization_members
-ON
-	organization_members.user_id = users.id AND
-	organization_members.organization_id = $1
 WHERE
-	-- In either case, the group_id will only match an org or a group.
-    (group_members.group_id = $1
-         OR
-     organization_members.organization_id = $1)
-AND
-	users.deleted = 'false'
+  user_id = $1
 `
 
-// If the group is a user made group, then we need to check the group_members table.
-// If it is the "Everyone" group, then we need to check the organization_members table.
-func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationMembershipsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []OrganizationMember
 	for rows.Next() {
-		var i User
+		var i OrganizationMember
 		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.UserID,
+			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.ThemePreference,
-			&i.Name,
+			pq.Array(&i.Roles),
 		); err != nil {
 			return nil, err
 		}
@@ -1363,282 +4938,157 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 	return items, nil
 }
 
-const insertGroupMember = `-- name: InsertGroupMember :exec
+const insertOrganizationMember = `-- name: InsertOrganizationMember :one
 INSERT INTO
-    group_members (user_id, group_id)
+	organization_members (
+		organization_id,
+		user_id,
+		created_at,
+		updated_at,
+		roles
+	)
 VALUES
-    ($1, $2)
-`
-
-type InsertGroupMemberParams struct {
-	UserID  uuid.UUID `db:"user_id" json:"user_id"`
-	GroupID uuid.UUID `db:"group_id" json:"group_id"`
-}
-
-func (q *sqlQuerier) InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error {
-	_, err := q.db.ExecContext(ctx, insertGroupMember, arg.UserID, arg.GroupID)
-	return err
-}
-
-const insertUserGroupsByName = `-- name: InsertUserGroupsByName :exec
-WITH groups AS (
-    SELECT
-        id
-    FROM
-        groups
-    WHERE
-        groups.organization_id = $2 AND
-        groups.name = ANY($3 :: text [])
-)
-INSERT INTO
-    group_members (user_id, group_id)
-SELECT
-    $1,
-    groups.id
-FROM
-    groups
+	($1, $2, $3, $4, $5) RETURNING user_id, organization_id, created_at, updated_at, roles
 `
 
-type InsertUserGroupsByNameParams struct {
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+type InsertOrganizationMemberParams struct {
 	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	GroupNames     []string  `db:"group_names" json:"group_names"`
-}
-
-// InsertUserGroupsByName adds a user to all provided groups, if they exist.
-func (q *sqlQuerier) InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error {
-	_, err := q.db.ExecContext(ctx, insertUserGroupsByName, arg.UserID, arg.OrganizationID, pq.Array(arg.GroupNames))
-	return err
-}
-
-const deleteGroupByID = `-- name: DeleteGroupByID :exec
-DELETE FROM
-	groups
-WHERE
-	id = $1
-`
-
-func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupByID, id)
-	return err
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	Roles          []string  `db:"roles" json:"roles"`
 }
 
-const getGroupByID = `-- name: GetGroupByID :one
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
-WHERE
-	id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error) {
-	row := q.db.QueryRowContext(ctx, getGroupByID, id)
-	var i Group
+func (q *sqlQuerier) InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, insertOrganizationMember,
+		arg.OrganizationID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		pq.Array(arg.Roles),
+	)
+	var i OrganizationMember
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
+		&i.UserID,
 		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
 	)
 	return i, err
 }
 
-const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
+const updateMemberRoles = `-- name: UpdateMemberRoles :one
+UPDATE
+	organization_members
+SET
+	-- Remove all duplicates from the roles.
+	roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
-	organization_id = $1
-AND
-	name = $2
-LIMIT
-	1
+	user_id = $2
+	AND organization_id = $3
+RETURNING user_id, organization_id, created_at, updated_at, roles
 `
 
-type GetGroupByOrgAndNameParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	Name           string    `db:"name" json:"name"`
+type UpdateMemberRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	OrgID        uuid.UUID `db:"org_id" json:"org_id"`
 }
 
-func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, getGroupByOrgAndName, arg.OrganizationID, arg.Name)
-	var i Group
+func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error) {
+	row := q.db.QueryRowContext(ctx, updateMemberRoles, pq.Array(arg.GrantedRoles), arg.UserID, arg.OrgID)
+	var i OrganizationMember
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
+		&i.UserID,
 		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Roles),
 	)
 	return i, err
 }
 
-const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
-SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
-FROM
-	groups
+const deleteOrganization = `-- name: DeleteOrganization :exec
+DELETE FROM
+	organizations
 WHERE
-	organization_id = $1
+	id = $1
 `
 
-func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error) {
-	rows, err := q.db.QueryContext(ctx, getGroupsByOrganizationID, organizationID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Group
-	for rows.Next() {
-		var i Group
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.OrganizationID,
-			&i.AvatarURL,
-			&i.QuotaAllowance,
-			&i.DisplayName,
-			&i.Source,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganization, id)
+	return err
 }
 
-const insertAllUsersGroup = `-- name: InsertAllUsersGroup :one
-INSERT INTO groups (
-	id,
-	name,
-	organization_id
-)
-VALUES
-	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+const getOrganizationByID = `-- name: GetOrganizationByID :one
+SELECT
+	id, name, description, created_at, updated_at
+FROM
+	organizations
+WHERE
+	id = $1
 `
 
-// We use the organization_id as the id
-// for simplicity since all users is
-// every member of the org.
-func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error) {
-	row := q.db.QueryRowContext(ctx, insertAllUsersGroup, organizationID)
-	var i Group
+func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByID, id)
+	var i Organization
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const insertGroup = `-- name: InsertGroup :one
-INSERT INTO groups (
-	id,
-	name,
-	display_name,
-	organization_id,
-	avatar_url,
-	quota_allowance
-)
-VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+const getOrganizationByName = `-- name: GetOrganizationByName :one
+SELECT
+	id, name, description, created_at, updated_at
+FROM
+	organizations
+WHERE
+	LOWER("name") = LOWER($1)
+LIMIT
+	1
 `
 
-type InsertGroupParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-}
-
-func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, insertGroup,
-		arg.ID,
-		arg.Name,
-		arg.DisplayName,
-		arg.OrganizationID,
-		arg.AvatarURL,
-		arg.QuotaAllowance,
-	)
-	var i Group
+func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationByName, name)
+	var i Organization
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const insertMissingGroups = `-- name: InsertMissingGroups :many
-INSERT INTO groups (
-	id,
-	name,
-	organization_id,
-    source
-)
+const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-    gen_random_uuid(),
-    group_name,
-    $1,
-    $2
+	id, name, description, created_at, updated_at
 FROM
-    UNNEST($3 :: text[]) AS group_name
-ON CONFLICT DO NOTHING
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	organizations
 `
 
-type InsertMissingGroupsParams struct {
-	OrganizationID uuid.UUID   `db:"organization_id" json:"organization_id"`
-	Source         GroupSource `db:"source" json:"source"`
-	GroupNames     []string    `db:"group_names" json:"group_names"`
-}
-
-// Inserts any group by name that does not exist. All new groups are given
-// a random uuid, are inserted into the same organization. They have the default
-// values for avatar, display name, and quota allowance (all zero values).
-// If the name conflicts, do nothing.
-func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error) {
-	rows, err := q.db.QueryContext(ctx, insertMissingGroups, arg.OrganizationID, arg.Source, pq.Array(arg.GroupNames))
+func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizations)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Group
+	var items []Organization
 	for rows.Next() {
-		var i Group
+		var i Organization
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
-			&i.OrganizationID,
-			&i.AvatarURL,
-			&i.QuotaAllowance,
-			&i.DisplayName,
-			&i.Source,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1653,140 +5103,123 @@ func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingG
 	return items, nil
 }
 
-const updateGroupByID = `-- name: UpdateGroupByID :one
-UPDATE
-	groups
-SET
-	name = $1,
-	display_name = $2,
-	avatar_url = $3,
-	quota_allowance = $4
+const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
+SELECT
+	id, name, description, created_at, updated_at
+FROM
+	organizations
 WHERE
-	id = $5
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
-`
-
-type UpdateGroupByIDParams struct {
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-	ID             uuid.UUID `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
-	row := q.db.QueryRowContext(ctx, updateGroupByID,
-		arg.Name,
-		arg.DisplayName,
-		arg.AvatarURL,
-		arg.QuotaAllowance,
-		arg.ID,
-	)
-	var i Group
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.OrganizationID,
-		&i.AvatarURL,
-		&i.QuotaAllowance,
-		&i.DisplayName,
-		&i.Source,
+	id IN (
+		SELECT
+			organization_id
+		FROM
+			organization_members
+		WHERE
+			user_id = $1
 	)
-	return i, err
-}
+`
 
-const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
-WITH app_stats_by_user_and_agent AS (
-	SELECT
-		s.start_time,
-		60 as seconds,
-		w.template_id,
-		was.user_id,
-		was.agent_id,
-		was.access_method,
-		was.slug_or_port,
-		wa.display_name,
-		wa.icon,
-		(wa.slug IS NOT NULL)::boolean AS is_app
-	FROM workspace_app_stats was
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
-	)
-	-- We do a left join here because we want to include user IDs that have used
-	-- e.g. ports when counting active users.
-	LEFT JOIN workspace_apps wa ON (
-		wa.agent_id = was.agent_id
-		AND wa.slug = was.slug_or_port
-	)
-	-- This table contains both 1 minute entries and >1 minute entries,
-	-- to calculate this with our uniqueness constraints, we generate series
-	-- for the longer intervals.
-	CROSS JOIN LATERAL generate_series(
-		date_trunc('minute', was.session_started_at),
-		-- Subtract 1 microsecond to avoid creating an extra series.
-		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
-		'1 minute'::interval
-	) s(start_time)
-	WHERE
-		s.start_time >= $2::timestamptz
-		-- Subtract one minute because the series only contains the start time.
-		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
-	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.access_method, was.slug_or_port, wa.display_name, wa.icon, wa.slug
-)
+func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Organization
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-SELECT
-	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
-	-- Return IDs so we can combine this with GetTemplateInsights.
-	array_agg(DISTINCT user_id)::uuid[] AS active_user_ids,
-	access_method,
-	slug_or_port,
-	display_name,
-	icon,
-	is_app,
-	SUM(seconds) AS usage_seconds
-FROM app_stats_by_user_and_agent
-GROUP BY access_method, slug_or_port, display_name, icon, is_app
+const insertOrganization = `-- name: InsertOrganization :one
+INSERT INTO
+	organizations (id, "name", description, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
 `
 
-type GetTemplateAppInsightsParams struct {
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
+type InsertOrganizationParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-type GetTemplateAppInsightsRow struct {
-	TemplateIDs   []uuid.UUID    `db:"template_ids" json:"template_ids"`
-	ActiveUserIDs []uuid.UUID    `db:"active_user_ids" json:"active_user_ids"`
-	AccessMethod  string         `db:"access_method" json:"access_method"`
-	SlugOrPort    string         `db:"slug_or_port" json:"slug_or_port"`
-	DisplayName   sql.NullString `db:"display_name" json:"display_name"`
-	Icon          sql.NullString `db:"icon" json:"icon"`
-	IsApp         bool           `db:"is_app" json:"is_app"`
-	UsageSeconds  int64          `db:"usage_seconds" json:"usage_seconds"`
+func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, insertOrganization,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-// GetTemplateAppInsights returns the aggregate usage of each app in a given
-// timeframe. The result can be filtered on template_ids, meaning only user data
-// from workspaces based on those templates will be included.
-func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateAppInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
+SELECT
+	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type, index
+FROM
+	parameter_schemas
+WHERE
+	job_id = $1
+ORDER BY
+	index
+`
+
+func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error) {
+	rows, err := q.db.QueryContext(ctx, getParameterSchemasByJobID, jobID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateAppInsightsRow
+	var items []ParameterSchema
 	for rows.Next() {
-		var i GetTemplateAppInsightsRow
+		var i ParameterSchema
 		if err := rows.Scan(
-			pq.Array(&i.TemplateIDs),
-			pq.Array(&i.ActiveUserIDs),
-			&i.AccessMethod,
-			&i.SlugOrPort,
-			&i.DisplayName,
-			&i.Icon,
-			&i.IsApp,
-			&i.UsageSeconds,
+			&i.ID,
+			&i.CreatedAt,
+			&i.JobID,
+			&i.Name,
+			&i.Description,
+			&i.DefaultSourceScheme,
+			&i.DefaultSourceValue,
+			&i.AllowOverrideSource,
+			&i.DefaultDestinationScheme,
+			&i.AllowOverrideDestination,
+			&i.DefaultRefresh,
+			&i.RedisplayValue,
+			&i.ValidationError,
+			&i.ValidationCondition,
+			&i.ValidationTypeSystem,
+			&i.ValidationValueType,
+			&i.Index,
 		); err != nil {
 			return nil, err
 		}
@@ -1801,82 +5234,49 @@ func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplate
 	return items, nil
 }
 
-const getTemplateAppInsightsByTemplate = `-- name: GetTemplateAppInsightsByTemplate :many
-WITH app_stats_by_user_and_agent AS (
-	SELECT
-		s.start_time,
-		60 as seconds,
-		w.template_id,
-		was.user_id,
-		was.agent_id,
-		was.slug_or_port,
-		wa.display_name,
-		(wa.slug IS NOT NULL)::boolean AS is_app
-	FROM workspace_app_stats was
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-	)
-	-- We do a left join here because we want to include user IDs that have used
-	-- e.g. ports when counting active users.
-	LEFT JOIN workspace_apps wa ON (
-		wa.agent_id = was.agent_id
-		AND wa.slug = was.slug_or_port
-	)
-	-- This table contains both 1 minute entries and >1 minute entries,
-	-- to calculate this with our uniqueness constraints, we generate series
-	-- for the longer intervals.
-	CROSS JOIN LATERAL generate_series(
-		date_trunc('minute', was.session_started_at),
-		-- Subtract 1 microsecond to avoid creating an extra series.
-		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
-		'1 minute'::interval
-	) s(start_time)
-	WHERE
-		s.start_time >= $1::timestamptz
-		-- Subtract one minute because the series only contains the start time.
-		AND s.start_time < ($2::timestamptz) - '1 minute'::interval
-	GROUP BY s.start_time, w.template_id, was.user_id, was.agent_id, was.slug_or_port, wa.display_name, wa.slug
+const deleteOldProvisionerDaemons = `-- name: DeleteOldProvisionerDaemons :exec
+DELETE FROM provisioner_daemons WHERE (
+	(created_at < (NOW() - INTERVAL '7 days') AND last_seen_at IS NULL) OR
+	(last_seen_at IS NOT NULL AND last_seen_at < (NOW() - INTERVAL '7 days'))
 )
-
-SELECT
-	template_id,
-	display_name,
-	slug_or_port,
-	COALESCE(COUNT(DISTINCT user_id))::bigint AS active_users,
-	SUM(seconds) AS usage_seconds
-FROM app_stats_by_user_and_agent
-WHERE is_app IS TRUE
-GROUP BY template_id, display_name, slug_or_port
 `
 
-type GetTemplateAppInsightsByTemplateParams struct {
-	StartTime time.Time `db:"start_time" json:"start_time"`
-	EndTime   time.Time `db:"end_time" json:"end_time"`
+// Delete provisioner daemons that have been created at least a week ago
+// and have not connected to coderd since a week.
+// A provisioner daemon with "zeroed" last_seen_at column indicates possible
+// connectivity issues (no provisioner daemon activity since registration).
+func (q *sqlQuerier) DeleteOldProvisionerDaemons(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldProvisionerDaemons)
+	return err
 }
 
-type GetTemplateAppInsightsByTemplateRow struct {
-	TemplateID   uuid.UUID      `db:"template_id" json:"template_id"`
-	DisplayName  sql.NullString `db:"display_name" json:"display_name"`
-	SlugOrPort   string         `db:"slug_or_port" json:"slug_or_port"`
-	ActiveUsers  int64          `db:"active_users" json:"active_users"`
-	UsageSeconds int64          `db:"usage_seconds" json:"usage_seconds"`
-}
+const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
+SELECT
+	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+FROM
+	provisioner_daemons
+`
 
-func (q *sqlQuerier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg GetTemplateAppInsightsByTemplateParams) ([]GetTemplateAppInsightsByTemplateRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateAppInsightsByTemplate, arg.StartTime, arg.EndTime)
+func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateAppInsightsByTemplateRow
+	var items []ProvisionerDaemon
 	for rows.Next() {
-		var i GetTemplateAppInsightsByTemplateRow
+		var i ProvisionerDaemon
 		if err := rows.Scan(
-			&i.TemplateID,
-			&i.DisplayName,
-			&i.SlugOrPort,
-			&i.ActiveUsers,
-			&i.UsageSeconds,
+			&i.ID,
+			&i.CreatedAt,
+			&i.Name,
+			pq.Array(&i.Provisioners),
+			&i.ReplicaID,
+			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.APIVersion,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -1891,174 +5291,197 @@ func (q *sqlQuerier) GetTemplateAppInsightsByTemplate(ctx context.Context, arg G
 	return items, nil
 }
 
-const getTemplateInsights = `-- name: GetTemplateInsights :one
-WITH agent_stats_by_interval_and_user AS (
-	SELECT
-		date_trunc('minute', was.created_at),
-		was.user_id,
-		array_agg(was.template_id) AS template_ids,
-		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
-		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
-		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
-		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
-	FROM workspace_agent_stats was
-	WHERE
-		was.created_at >= $1::timestamptz
-		AND was.created_at < $2::timestamptz
-		AND was.connection_count > 0
-		AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN was.template_id = ANY($3::uuid[]) ELSE TRUE END
-	GROUP BY date_trunc('minute', was.created_at), was.user_id
-), template_ids AS (
-	SELECT array_agg(DISTINCT template_id) AS ids
-	FROM agent_stats_by_interval_and_user, unnest(template_ids) template_id
-	WHERE template_id IS NOT NULL
-)
+const getProvisionerDaemonsByOrganization = `-- name: GetProvisionerDaemonsByOrganization :many
+SELECT
+	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+FROM
+	provisioner_daemons
+WHERE
+	organization_id IS NULL
+	OR organization_id = $1 :: uuid
+`
+
+// Returns the daemons that can acquire jobs from the organization, including
+// those that are not scoped to any organization.
+func (q *sqlQuerier) GetProvisionerDaemonsByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerDaemonsByOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerDaemon
+	for rows.Next() {
+		var i ProvisionerDaemon
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Name,
+			pq.Array(&i.Provisioners),
+			&i.ReplicaID,
+			&i.Tags,
+			&i.LastSeenAt,
+			&i.Version,
+			&i.APIVersion,
+			&i.OrganizationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-SELECT
-	COALESCE((SELECT ids FROM template_ids), '{}')::uuid[] AS template_ids,
-	-- Return IDs so we can combine this with GetTemplateAppInsights.
-	COALESCE(array_agg(DISTINCT user_id), '{}')::uuid[] AS active_user_ids,
-	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
-	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
-	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
-	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
-FROM agent_stats_by_interval_and_user
+const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
+UPDATE provisioner_daemons
+SET
+	last_seen_at = $1
+WHERE
+	id = $2
+AND
+	last_seen_at <= $1
 `
 
-type GetTemplateInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+type UpdateProvisionerDaemonLastSeenAtParams struct {
+	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
+	ID         uuid.UUID    `db:"id" json:"id"`
 }
 
-type GetTemplateInsightsRow struct {
-	TemplateIDs                 []uuid.UUID `db:"template_ids" json:"template_ids"`
-	ActiveUserIDs               []uuid.UUID `db:"active_user_ids" json:"active_user_ids"`
-	UsageVscodeSeconds          int64       `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
-	UsageJetbrainsSeconds       int64       `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
-	UsageReconnectingPtySeconds int64       `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
-	UsageSshSeconds             int64       `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
+	return err
 }
 
-// GetTemplateInsights has a granularity of 5 minutes where if a session/app was
-// in use during a minute, we will add 5 minutes to the total usage for that
-// session/app (per user).
-func (q *sqlQuerier) GetTemplateInsights(ctx context.Context, arg GetTemplateInsightsParams) (GetTemplateInsightsRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
-	var i GetTemplateInsightsRow
+const upsertProvisionerDaemon = `-- name: UpsertProvisionerDaemon :one
+INSERT INTO
+	provisioner_daemons (
+		id,
+		created_at,
+		"name",
+		provisioners,
+		tags,
+		last_seen_at,
+		"version",
+		api_version,
+		organization_id
+	)
+VALUES (
+	gen_random_uuid(),
+	$1,
+	$2,
+	$3,
+	$4,
+	$5,
+	$6,
+	$7,
+	$8
+) ON CONFLICT("name", LOWER(COALESCE(tags ->> 'owner'::text, ''::text))) DO UPDATE SET
+	provisioners = $3,
+	tags = $4,
+	last_seen_at = $5,
+	"version" = $6,
+	api_version = $7,
+	organization_id = $8
+WHERE
+	-- Only ones with the same tags are allowed clobber
+	provisioner_daemons.tags <@ $4 :: jsonb
+RETURNING id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version, organization_id
+`
+
+type UpsertProvisionerDaemonParams struct {
+	CreatedAt      time.Time         `db:"created_at" json:"created_at"`
+	Name           string            `db:"name" json:"name"`
+	Provisioners   []ProvisionerType `db:"provisioners" json:"provisioners"`
+	Tags           StringMap         `db:"tags" json:"tags"`
+	LastSeenAt     sql.NullTime      `db:"last_seen_at" json:"last_seen_at"`
+	Version        string            `db:"version" json:"version"`
+	APIVersion     string            `db:"api_version" json:"api_version"`
+	OrganizationID uuid.NullUUID     `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
+	row := q.db.QueryRowContext(ctx, upsertProvisionerDaemon,
+		arg.CreatedAt,
+		arg.Name,
+		pq.Array(arg.Provisioners),
+		arg.Tags,
+		arg.LastSeenAt,
+		arg.Version,
+		arg.APIVersion,
+		arg.OrganizationID,
+	)
+	var i ProvisionerDaemon
 	err := row.Scan(
-		pq.Array(&i.TemplateIDs),
-		pq.Array(&i.ActiveUserIDs),
-		&i.UsageVscodeSeconds,
-		&i.UsageJetbrainsSeconds,
-		&i.UsageReconnectingPtySeconds,
-		&i.UsageSshSeconds,
+		&i.ID,
+		&i.CreatedAt,
+		&i.Name,
+		pq.Array(&i.Provisioners),
+		&i.ReplicaID,
+		&i.Tags,
+		&i.LastSeenAt,
+		&i.Version,
+		&i.APIVersion,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
-const getTemplateInsightsByInterval = `-- name: GetTemplateInsightsByInterval :many
-WITH ts AS (
-	SELECT
-		d::timestamptz AS from_,
-		CASE
-			WHEN (d::timestamptz + ($1::int || ' day')::interval) <= $2::timestamptz
-			THEN (d::timestamptz + ($1::int || ' day')::interval)
-			ELSE $2::timestamptz
-		END AS to_
-	FROM
-		-- Subtract 1 microsecond from end_time to avoid including the next interval in the results.
-		generate_series($3::timestamptz, ($2::timestamptz) - '1 microsecond'::interval, ($1::int || ' day')::interval) AS d
-), unflattened_usage_by_interval AS (
-	-- We select data from both workspace agent stats and workspace app stats to
-	-- get a complete picture of usage. This matches how usage is calculated by
-	-- the combination of GetTemplateInsights and GetTemplateAppInsights. We use
-	-- a union all to avoid a costly distinct operation.
-	--
-	-- Note that one query must perform a left join so that all intervals are
-	-- present at least once.
-	SELECT
-		ts.from_, ts.to_,
-		was.template_id,
-		was.user_id
-	FROM ts
-	LEFT JOIN workspace_agent_stats was ON (
-		was.created_at >= ts.from_
-		AND was.created_at < ts.to_
-		AND was.connection_count > 0
-		AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN was.template_id = ANY($4::uuid[]) ELSE TRUE END
-	)
-	GROUP BY ts.from_, ts.to_, was.template_id, was.user_id
+const deleteProvisionerJobLogsByJobID = `-- name: DeleteProvisionerJobLogsByJobID :exec
+DELETE FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
+`
 
-	UNION ALL
+func (q *sqlQuerier) DeleteProvisionerJobLogsByJobID(ctx context.Context, jobID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerJobLogsByJobID, jobID)
+	return err
+}
 
-	SELECT
-		ts.from_, ts.to_,
-		w.template_id,
-		was.user_id
-	FROM ts
-	JOIN workspace_app_stats was ON (
-		(was.session_started_at >= ts.from_ AND was.session_started_at < ts.to_)
-		OR (was.session_ended_at > ts.from_ AND was.session_ended_at < ts.to_)
-		OR (was.session_started_at < ts.from_ AND was.session_ended_at >= ts.to_)
+const getProvisionerJobIDsToArchiveLogs = `-- name: GetProvisionerJobIDsToArchiveLogs :many
+SELECT
+	provisioner_jobs.id
+FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.completed_at < $1 :: timestamptz
+	AND EXISTS (
+		SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
 	)
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-		AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN w.template_id = ANY($4::uuid[]) ELSE TRUE END
+	AND NOT EXISTS (
+		SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
 	)
-	GROUP BY ts.from_, ts.to_, w.template_id, was.user_id
-)
-
-SELECT
-	from_ AS start_time,
-	to_ AS end_time,
-	array_remove(array_agg(DISTINCT template_id), NULL)::uuid[] AS template_ids,
-	COUNT(DISTINCT user_id) AS active_users
-FROM unflattened_usage_by_interval
-GROUP BY from_, to_
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	$2 :: int
 `
 
-type GetTemplateInsightsByIntervalParams struct {
-	IntervalDays int32       `db:"interval_days" json:"interval_days"`
-	EndTime      time.Time   `db:"end_time" json:"end_time"`
-	StartTime    time.Time   `db:"start_time" json:"start_time"`
-	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
-}
-
-type GetTemplateInsightsByIntervalRow struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-	ActiveUsers int64       `db:"active_users" json:"active_users"`
+type GetProvisionerJobIDsToArchiveLogsParams struct {
+	CompletedBefore time.Time `db:"completed_before" json:"completed_before"`
+	LimitOpt        int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-// GetTemplateInsightsByInterval returns all intervals between start and end
-// time, if end time is a partial interval, it will be included in the results and
-// that interval will be shorter than a full one. If there is no data for a selected
-// interval/template, it will be included in the results with 0 active users.
-func (q *sqlQuerier) GetTemplateInsightsByInterval(ctx context.Context, arg GetTemplateInsightsByIntervalParams) ([]GetTemplateInsightsByIntervalRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateInsightsByInterval,
-		arg.IntervalDays,
-		arg.EndTime,
-		arg.StartTime,
-		pq.Array(arg.TemplateIDs),
-	)
+// Returns completed jobs that still have logs in provisioner_job_logs, oldest
+// first.
+func (q *sqlQuerier) GetProvisionerJobIDsToArchiveLogs(ctx context.Context, arg GetProvisionerJobIDsToArchiveLogsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobIDsToArchiveLogs, arg.CompletedBefore, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateInsightsByIntervalRow
+	var items []uuid.UUID
 	for rows.Next() {
-		var i GetTemplateInsightsByIntervalRow
-		if err := rows.Scan(
-			&i.StartTime,
-			&i.EndTime,
-			pq.Array(&i.TemplateIDs),
-			&i.ActiveUsers,
-		); err != nil {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -2069,65 +5492,84 @@ func (q *sqlQuerier) GetTemplateInsightsByInterval(ctx context.Context, arg GetT
 	return items, nil
 }
 
-const getTemplateInsightsByTemplate = `-- name: GetTemplateInsightsByTemplate :many
-WITH agent_stats_by_interval_and_user AS (
-	SELECT
-		date_trunc('minute', was.created_at) AS created_at_trunc,
-		was.template_id,
-		was.user_id,
-		CASE WHEN SUM(was.session_count_vscode) > 0 THEN 60 ELSE 0 END AS usage_vscode_seconds,
-		CASE WHEN SUM(was.session_count_jetbrains) > 0 THEN 60 ELSE 0 END AS usage_jetbrains_seconds,
-		CASE WHEN SUM(was.session_count_reconnecting_pty) > 0 THEN 60 ELSE 0 END AS usage_reconnecting_pty_seconds,
-		CASE WHEN SUM(was.session_count_ssh) > 0 THEN 60 ELSE 0 END AS usage_ssh_seconds
-	FROM workspace_agent_stats was
-	WHERE
-		was.created_at >= $1::timestamptz
-		AND was.created_at < $2::timestamptz
-		AND was.connection_count > 0
-	GROUP BY created_at_trunc, was.template_id, was.user_id
-)
-
+const getProvisionerJobLogArchiveByJobID = `-- name: GetProvisionerJobLogArchiveByJobID :one
 SELECT
-	template_id,
-	COALESCE(COUNT(DISTINCT user_id))::bigint AS active_users,
-	COALESCE(SUM(usage_vscode_seconds), 0)::bigint AS usage_vscode_seconds,
-	COALESCE(SUM(usage_jetbrains_seconds), 0)::bigint AS usage_jetbrains_seconds,
-	COALESCE(SUM(usage_reconnecting_pty_seconds), 0)::bigint AS usage_reconnecting_pty_seconds,
-	COALESCE(SUM(usage_ssh_seconds), 0)::bigint AS usage_ssh_seconds
-FROM agent_stats_by_interval_and_user
-GROUP BY template_id
+	job_id, created_at, log_count, uncompressed_size, compressed_logs
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerJobLogArchiveByJobID(ctx context.Context, jobID uuid.UUID) (ProvisionerJobLogArchive, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobLogArchiveByJobID, jobID)
+	var i ProvisionerJobLogArchive
+	err := row.Scan(
+		&i.JobID,
+		&i.CreatedAt,
+		&i.LogCount,
+		&i.UncompressedSize,
+		&i.CompressedLogs,
+	)
+	return i, err
+}
+
+const getProvisionerJobLogArchiveStats = `-- name: GetProvisionerJobLogArchiveStats :one
+SELECT
+	COUNT(*) AS archive_count,
+	COALESCE(SUM(octet_length(compressed_logs)), 0) :: bigint AS compressed_size,
+	COALESCE(SUM(uncompressed_size), 0) :: bigint AS uncompressed_size
+FROM
+	provisioner_job_log_archives
 `
 
-type GetTemplateInsightsByTemplateParams struct {
-	StartTime time.Time `db:"start_time" json:"start_time"`
-	EndTime   time.Time `db:"end_time" json:"end_time"`
+type GetProvisionerJobLogArchiveStatsRow struct {
+	ArchiveCount     int64 `db:"archive_count" json:"archive_count"`
+	CompressedSize   int64 `db:"compressed_size" json:"compressed_size"`
+	UncompressedSize int64 `db:"uncompressed_size" json:"uncompressed_size"`
 }
 
-type GetTemplateInsightsByTemplateRow struct {
-	TemplateID                  uuid.UUID `db:"template_id" json:"template_id"`
-	ActiveUsers                 int64     `db:"active_users" json:"active_users"`
-	UsageVscodeSeconds          int64     `db:"usage_vscode_seconds" json:"usage_vscode_seconds"`
-	UsageJetbrainsSeconds       int64     `db:"usage_jetbrains_seconds" json:"usage_jetbrains_seconds"`
-	UsageReconnectingPtySeconds int64     `db:"usage_reconnecting_pty_seconds" json:"usage_reconnecting_pty_seconds"`
-	UsageSshSeconds             int64     `db:"usage_ssh_seconds" json:"usage_ssh_seconds"`
+func (q *sqlQuerier) GetProvisionerJobLogArchiveStats(ctx context.Context) (GetProvisionerJobLogArchiveStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobLogArchiveStats)
+	var i GetProvisionerJobLogArchiveStatsRow
+	err := row.Scan(&i.ArchiveCount, &i.CompressedSize, &i.UncompressedSize)
+	return i, err
 }
 
-func (q *sqlQuerier) GetTemplateInsightsByTemplate(ctx context.Context, arg GetTemplateInsightsByTemplateParams) ([]GetTemplateInsightsByTemplateRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateInsightsByTemplate, arg.StartTime, arg.EndTime)
+const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+SELECT
+	job_id, created_at, source, level, stage, output, id
+FROM
+	provisioner_job_logs
+WHERE
+	job_id = $1
+	AND (
+		id > $2
+	) ORDER BY id ASC
+`
+
+type GetProvisionerLogsAfterIDParams struct {
+	JobID        uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAfter int64     `db:"created_after" json:"created_after"`
+}
+
+func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateInsightsByTemplateRow
+	var items []ProvisionerJobLog
 	for rows.Next() {
-		var i GetTemplateInsightsByTemplateRow
+		var i ProvisionerJobLog
 		if err := rows.Scan(
-			&i.TemplateID,
-			&i.ActiveUsers,
-			&i.UsageVscodeSeconds,
-			&i.UsageJetbrainsSeconds,
-			&i.UsageReconnectingPtySeconds,
-			&i.UsageSshSeconds,
+			&i.JobID,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
 		); err != nil {
 			return nil, err
 		}
@@ -2142,98 +5584,85 @@ func (q *sqlQuerier) GetTemplateInsightsByTemplate(ctx context.Context, arg GetT
 	return items, nil
 }
 
-const getTemplateParameterInsights = `-- name: GetTemplateParameterInsights :many
-WITH latest_workspace_builds AS (
-	SELECT
-		wb.id,
-		wbmax.template_id,
-		wb.template_version_id
-	FROM (
-		SELECT
-			tv.template_id, wbmax.workspace_id, MAX(wbmax.build_number) as max_build_number
-		FROM workspace_builds wbmax
-		JOIN template_versions tv ON (tv.id = wbmax.template_version_id)
-		WHERE
-			wbmax.created_at >= $1::timestamptz
-			AND wbmax.created_at < $2::timestamptz
-			AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN tv.template_id = ANY($3::uuid[]) ELSE TRUE END
-		GROUP BY tv.template_id, wbmax.workspace_id
-	) wbmax
-	JOIN workspace_builds wb ON (
-		wb.workspace_id = wbmax.workspace_id
-		AND wb.build_number = wbmax.max_build_number
+const insertProvisionerJobLogArchive = `-- name: InsertProvisionerJobLogArchive :one
+INSERT INTO
+	provisioner_job_log_archives (job_id, created_at, log_count, uncompressed_size, compressed_logs)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING job_id, created_at, log_count, uncompressed_size, compressed_logs
+`
+
+type InsertProvisionerJobLogArchiveParams struct {
+	JobID            uuid.UUID `db:"job_id" json:"job_id"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	LogCount         int32     `db:"log_count" json:"log_count"`
+	UncompressedSize int64     `db:"uncompressed_size" json:"uncompressed_size"`
+	CompressedLogs   []byte    `db:"compressed_logs" json:"compressed_logs"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobLogArchive(ctx context.Context, arg InsertProvisionerJobLogArchiveParams) (ProvisionerJobLogArchive, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerJobLogArchive,
+		arg.JobID,
+		arg.CreatedAt,
+		arg.LogCount,
+		arg.UncompressedSize,
+		arg.CompressedLogs,
 	)
-), unique_template_params AS (
-	SELECT
-		ROW_NUMBER() OVER () AS num,
-		array_agg(DISTINCT wb.template_id)::uuid[] AS template_ids,
-		array_agg(wb.id)::uuid[] AS workspace_build_ids,
-		tvp.name,
-		tvp.type,
-		tvp.display_name,
-		tvp.description,
-		tvp.options
-	FROM latest_workspace_builds wb
-	JOIN template_version_parameters tvp ON (tvp.template_version_id = wb.template_version_id)
-	GROUP BY tvp.name, tvp.type, tvp.display_name, tvp.description, tvp.options
-)
+	var i ProvisionerJobLogArchive
+	err := row.Scan(
+		&i.JobID,
+		&i.CreatedAt,
+		&i.LogCount,
+		&i.UncompressedSize,
+		&i.CompressedLogs,
+	)
+	return i, err
+}
 
+const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
+INSERT INTO
+	provisioner_job_logs
 SELECT
-	utp.num,
-	utp.template_ids,
-	utp.name,
-	utp.type,
-	utp.display_name,
-	utp.description,
-	utp.options,
-	wbp.value,
-	COUNT(wbp.value) AS count
-FROM unique_template_params utp
-JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
-GROUP BY utp.num, utp.template_ids, utp.name, utp.type, utp.display_name, utp.description, utp.options, wbp.value
+	$1 :: uuid AS job_id,
+	unnest($2 :: timestamptz [ ]) AS created_at,
+	unnest($3 :: log_source [ ]) AS source,
+	unnest($4 :: log_level [ ]) AS LEVEL,
+	unnest($5 :: VARCHAR(128) [ ]) AS stage,
+	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
 `
 
-type GetTemplateParameterInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-}
-
-type GetTemplateParameterInsightsRow struct {
-	Num         int64           `db:"num" json:"num"`
-	TemplateIDs []uuid.UUID     `db:"template_ids" json:"template_ids"`
-	Name        string          `db:"name" json:"name"`
-	Type        string          `db:"type" json:"type"`
-	DisplayName string          `db:"display_name" json:"display_name"`
-	Description string          `db:"description" json:"description"`
-	Options     json.RawMessage `db:"options" json:"options"`
-	Value       string          `db:"value" json:"value"`
-	Count       int64           `db:"count" json:"count"`
+type InsertProvisionerJobLogsParams struct {
+	JobID     uuid.UUID   `db:"job_id" json:"job_id"`
+	CreatedAt []time.Time `db:"created_at" json:"created_at"`
+	Source    []LogSource `db:"source" json:"source"`
+	Level     []LogLevel  `db:"level" json:"level"`
+	Stage     []string    `db:"stage" json:"stage"`
+	Output    []string    `db:"output" json:"output"`
 }
 
-// GetTemplateParameterInsights does for each template in a given timeframe,
-// look for the latest workspace build (for every workspace) that has been
-// created in the timeframe and return the aggregate usage counts of parameter
-// values.
-func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateParameterInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
+	rows, err := q.db.QueryContext(ctx, insertProvisionerJobLogs,
+		arg.JobID,
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.Source),
+		pq.Array(arg.Level),
+		pq.Array(arg.Stage),
+		pq.Array(arg.Output),
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateParameterInsightsRow
+	var items []ProvisionerJobLog
 	for rows.Next() {
-		var i GetTemplateParameterInsightsRow
+		var i ProvisionerJobLog
 		if err := rows.Scan(
-			&i.Num,
-			pq.Array(&i.TemplateIDs),
-			&i.Name,
-			&i.Type,
-			&i.DisplayName,
-			&i.Description,
-			&i.Options,
-			&i.Value,
-			&i.Count,
+			&i.JobID,
+			&i.CreatedAt,
+			&i.Source,
+			&i.Level,
+			&i.Stage,
+			&i.Output,
+			&i.ID,
 		); err != nil {
 			return nil, err
 		}
@@ -2248,113 +5677,149 @@ func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTe
 	return items, nil
 }
 
-const getUserActivityInsights = `-- name: GetUserActivityInsights :many
-WITH app_stats AS (
-	SELECT
-		s.start_time,
-		was.user_id,
-		w.template_id,
-		60 as seconds
-	FROM workspace_app_stats was
-	JOIN workspaces w ON (
-		w.id = was.workspace_id
-		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN w.template_id = ANY($1::uuid[]) ELSE TRUE END
-	)
-	-- This table contains both 1 minute entries and >1 minute entries,
-	-- to calculate this with our uniqueness constraints, we generate series
-	-- for the longer intervals.
-	CROSS JOIN LATERAL generate_series(
-		date_trunc('minute', was.session_started_at),
-		-- Subtract 1 microsecond to avoid creating an extra series.
-		date_trunc('minute', was.session_ended_at - '1 microsecond'::interval),
-		'1 minute'::interval
-	) s(start_time)
-	WHERE
-		s.start_time >= $2::timestamptz
-		-- Subtract one minute because the series only contains the start time.
-		AND s.start_time < ($3::timestamptz) - '1 minute'::interval
-	GROUP BY s.start_time, w.template_id, was.user_id
-), session_stats AS (
-	SELECT
-		date_trunc('minute', was.created_at) as start_time,
-		was.user_id,
-		was.template_id,
-		CASE WHEN
-			SUM(was.session_count_vscode) > 0 OR
-			SUM(was.session_count_jetbrains) > 0 OR
-			SUM(was.session_count_reconnecting_pty) > 0 OR
-			SUM(was.session_count_ssh) > 0
-		THEN 60 ELSE 0 END as seconds
-	FROM workspace_agent_stats was
-	WHERE
-		was.created_at >= $2::timestamptz
-		AND was.created_at < $3::timestamptz
-		AND was.connection_count > 0
-		AND CASE WHEN COALESCE(array_length($1::uuid[], 1), 0) > 0 THEN was.template_id = ANY($1::uuid[]) ELSE TRUE END
-	GROUP BY date_trunc('minute', was.created_at), was.user_id, was.template_id
-), combined_stats AS (
-	SELECT
-		user_id,
-		template_id,
-		start_time,
-		seconds
-	FROM app_stats
-	UNION
-	SELECT
-		user_id,
-		template_id,
-		start_time,
-		seconds
-	FROM session_stats
-)
-SELECT
-	users.id as user_id,
-	users.username,
-	users.avatar_url,
-	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
-	SUM(seconds) AS usage_seconds
-FROM combined_stats
-JOIN users ON (users.id = combined_stats.user_id)
-GROUP BY users.id, username, avatar_url
-ORDER BY user_id ASC
+const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
+UPDATE
+	provisioner_jobs
+SET
+	started_at = $1,
+	updated_at = $1,
+	worker_id = $2
+WHERE
+	id = (
+		SELECT
+			id
+		FROM
+			provisioner_jobs AS nested
+		WHERE
+			nested.started_at IS NULL
+			-- Ensure the caller has the correct provisioner.
+			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
+			-- Ensure the caller satisfies all job tags.
+			AND nested.tags <@ $4 :: jsonb
+			-- Ensure the caller satisfies all job tag requirements. This must
+			-- match provisionertags.Matches: "*" in values is a wildcard, and
+			-- callers without the tag only satisfy "not_in" requirements.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					jsonb_to_recordset(nested.tag_requirements) AS requirement("key" text, "operator" text, "values" jsonb)
+				WHERE
+					EXISTS (
+						SELECT
+							1
+						FROM
+							jsonb_array_elements_text(requirement."values") AS pattern(value)
+						WHERE
+							($4 :: jsonb) ->> requirement."key" LIKE replace(replace(replace(replace(pattern.value, '\', '\\'), '%', '\%'), '_', '\_'), '*', '%')
+					) != (requirement."operator" = 'in')
+			)
+			-- Organization-scoped daemons only acquire jobs from their organization.
+			AND ($5 :: uuid IS NULL OR nested.organization_id = $5 :: uuid)
+		ORDER BY
+			-- Admins can bump a job ahead of the queue. Otherwise, prefer
+			-- workspace builds, which users are waiting on, over imports.
+			nested.priority DESC,
+			nested.type = 'workspace_build' DESC,
+			nested.created_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			1
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
 `
 
-type GetUserActivityInsightsParams struct {
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
+type AcquireProvisionerJobParams struct {
+	StartedAt      sql.NullTime      `db:"started_at" json:"started_at"`
+	WorkerID       uuid.NullUUID     `db:"worker_id" json:"worker_id"`
+	Types          []ProvisionerType `db:"types" json:"types"`
+	Tags           json.RawMessage   `db:"tags" json:"tags"`
+	OrganizationID uuid.NullUUID     `db:"organization_id" json:"organization_id"`
 }
 
-type GetUserActivityInsightsRow struct {
-	UserID       uuid.UUID   `db:"user_id" json:"user_id"`
-	Username     string      `db:"username" json:"username"`
-	AvatarURL    string      `db:"avatar_url" json:"avatar_url"`
-	TemplateIDs  []uuid.UUID `db:"template_ids" json:"template_ids"`
-	UsageSeconds int64       `db:"usage_seconds" json:"usage_seconds"`
+// Acquires the lock for a single job that isn't started, completed,
+// canceled, and that matches an array of provisioner types.
+//
+// SKIP LOCKED is used to jump over locked rows. This prevents
+// multiple provisioners from acquiring the same jobs. See:
+// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
+func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
+		arg.StartedAt,
+		arg.WorkerID,
+		pq.Array(arg.Types),
+		arg.Tags,
+		arg.OrganizationID,
+	)
+	var i ProvisionerJob
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
+		&i.Error,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
+		&i.JobStatus,
+		&i.Priority,
+		&i.TagRequirements,
+	)
+	return i, err
 }
 
-// GetUserActivityInsights returns the ranking with top active users.
-// The result can be filtered on template_ids, meaning only user data from workspaces
-// based on those templates will be included.
-// Note: When selecting data from multiple templates or the entire deployment,
-// be aware that it may lead to an increase in "usage" numbers (cumulative). In such cases,
-// users may be counted multiple times for the same time interval if they have used multiple templates
-// simultaneously.
-func (q *sqlQuerier) GetUserActivityInsights(ctx context.Context, arg GetUserActivityInsightsParams) ([]GetUserActivityInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserActivityInsights, pq.Array(arg.TemplateIDs), arg.StartTime, arg.EndTime)
+const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
+FROM
+	provisioner_jobs
+WHERE
+	updated_at < $1
+	AND started_at IS NOT NULL
+	AND completed_at IS NULL
+`
+
+func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getHungProvisionerJobs, updatedAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserActivityInsightsRow
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i GetUserActivityInsightsRow
+		var i ProvisionerJob
 		if err := rows.Scan(
-			&i.UserID,
-			&i.Username,
-			&i.AvatarURL,
-			pq.Array(&i.TemplateIDs),
-			&i.UsageSeconds,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+			&i.Priority,
+			&i.TagRequirements,
 		); err != nil {
 			return nil, err
 		}
@@ -2369,61 +5834,58 @@ func (q *sqlQuerier) GetUserActivityInsights(ctx context.Context, arg GetUserAct
 	return items, nil
 }
 
-const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
+const getOrphanedProvisionerJobs = `-- name: GetOrphanedProvisionerJobs :many
 SELECT
-	workspace_agent_stats.user_id,
-	users.username,
-	users.avatar_url,
-	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
-	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-FROM workspace_agent_stats
-JOIN users ON (users.id = workspace_agent_stats.user_id)
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
+FROM
+	provisioner_jobs
 WHERE
-	workspace_agent_stats.created_at >= $1
-	AND workspace_agent_stats.created_at < $2
-	AND workspace_agent_stats.connection_median_latency_ms > 0
-	AND workspace_agent_stats.connection_count > 0
-	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
-GROUP BY workspace_agent_stats.user_id, users.username, users.avatar_url
-ORDER BY user_id ASC
+	started_at IS NOT NULL
+	AND completed_at IS NULL
+	AND worker_id IN (
+		SELECT
+			id
+		FROM
+			provisioner_daemons
+		WHERE
+			last_seen_at < $1 :: timestamptz
+	)
 `
 
-type GetUserLatencyInsightsParams struct {
-	StartTime   time.Time   `db:"start_time" json:"start_time"`
-	EndTime     time.Time   `db:"end_time" json:"end_time"`
-	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
-}
-
-type GetUserLatencyInsightsRow struct {
-	UserID                       uuid.UUID   `db:"user_id" json:"user_id"`
-	Username                     string      `db:"username" json:"username"`
-	AvatarURL                    string      `db:"avatar_url" json:"avatar_url"`
-	TemplateIDs                  []uuid.UUID `db:"template_ids" json:"template_ids"`
-	WorkspaceConnectionLatency50 float64     `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64     `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-}
+// Returns running jobs acquired by provisioner daemons that have not sent a
+// heartbeat since the given time.
 
-// GetUserLatencyInsights returns the median and 95th percentile connection
-// latency that users have experienced. The result can be filtered on
-// template_ids, meaning only user data from workspaces based on those templates
-// will be included.
-func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLatencyInsightsParams) ([]GetUserLatencyInsightsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLatencyInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+func (q *sqlQuerier) GetOrphanedProvisionerJobs(ctx context.Context, staleSince time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getOrphanedProvisionerJobs, staleSince)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUserLatencyInsightsRow
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i GetUserLatencyInsightsRow
+		var i ProvisionerJob
 		if err := rows.Scan(
-			&i.UserID,
-			&i.Username,
-			&i.AvatarURL,
-			pq.Array(&i.TemplateIDs),
-			&i.WorkspaceConnectionLatency50,
-			&i.WorkspaceConnectionLatency95,
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+			&i.Priority,
+			&i.TagRequirements,
 		); err != nil {
 			return nil, err
 		}
@@ -2438,64 +5900,84 @@ func (q *sqlQuerier) GetUserLatencyInsights(ctx context.Context, arg GetUserLate
 	return items, nil
 }
 
-const deleteLicense = `-- name: DeleteLicense :one
-DELETE
-FROM licenses
-WHERE id = $1
-RETURNING id
-`
-
-func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
-	row := q.db.QueryRowContext(ctx, deleteLicense, id)
-	err := row.Scan(&id)
-	return id, err
-}
-
-const getLicenseByID = `-- name: GetLicenseByID :one
+const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, uploaded_at, jwt, exp, uuid
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
 FROM
-	licenses
+	provisioner_jobs
 WHERE
 	id = $1
-LIMIT
-	1
 `
 
-func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, error) {
-	row := q.db.QueryRowContext(ctx, getLicenseByID, id)
-	var i License
+func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobByID, id)
+	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
-		&i.UploadedAt,
-		&i.JWT,
-		&i.Exp,
-		&i.UUID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
+		&i.Error,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
+		&i.JobStatus,
+		&i.Priority,
+		&i.TagRequirements,
 	)
 	return i, err
 }
 
-const getLicenses = `-- name: GetLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
-FROM licenses
-ORDER BY (id)
+const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
+FROM
+	provisioner_jobs
+WHERE
+	id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
-	rows, err := q.db.QueryContext(ctx, getLicenses)
+func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []License
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i License
+		var i ProvisionerJob
 		if err := rows.Scan(
 			&i.ID,
-			&i.UploadedAt,
-			&i.JWT,
-			&i.Exp,
-			&i.UUID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+			&i.Priority,
+			&i.TagRequirements,
 		); err != nil {
 			return nil, err
 		}
@@ -2510,28 +5992,79 @@ func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
 	return items, nil
 }
 
-const getUnexpiredLicenses = `-- name: GetUnexpiredLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
-FROM licenses
-WHERE exp > NOW()
-ORDER BY (id)
+const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
+WITH unstarted_jobs AS (
+    SELECT
+        id, created_at, priority, type
+    FROM
+        provisioner_jobs
+    WHERE
+        started_at IS NULL
+),
+queue_position AS (
+    SELECT
+        id,
+        -- This must match the order in AcquireProvisionerJob.
+        ROW_NUMBER() OVER (ORDER BY priority DESC, type = 'workspace_build' DESC, created_at ASC) AS queue_position
+    FROM
+        unstarted_jobs
+),
+queue_size AS (
+	SELECT COUNT(*) as count FROM unstarted_jobs
+)
+SELECT
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status, pj.priority, pj.tag_requirements,
+    COALESCE(qp.queue_position, 0) AS queue_position,
+    COALESCE(qs.count, 0) AS queue_size
+FROM
+	provisioner_jobs pj
+LEFT JOIN
+	queue_position qp ON qp.id = pj.id
+LEFT JOIN
+	queue_size qs ON TRUE
+WHERE
+	pj.id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error) {
-	rows, err := q.db.QueryContext(ctx, getUnexpiredLicenses)
+type GetProvisionerJobsByIDsWithQueuePositionRow struct {
+	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
+	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
+	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+}
+
+func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDsWithQueuePosition, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []License
+	var items []GetProvisionerJobsByIDsWithQueuePositionRow
 	for rows.Next() {
-		var i License
+		var i GetProvisionerJobsByIDsWithQueuePositionRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UploadedAt,
-			&i.JWT,
-			&i.Exp,
-			&i.UUID,
+			&i.ProvisionerJob.ID,
+			&i.ProvisionerJob.CreatedAt,
+			&i.ProvisionerJob.UpdatedAt,
+			&i.ProvisionerJob.StartedAt,
+			&i.ProvisionerJob.CanceledAt,
+			&i.ProvisionerJob.CompletedAt,
+			&i.ProvisionerJob.Error,
+			&i.ProvisionerJob.OrganizationID,
+			&i.ProvisionerJob.InitiatorID,
+			&i.ProvisionerJob.Provisioner,
+			&i.ProvisionerJob.StorageMethod,
+			&i.ProvisionerJob.Type,
+			&i.ProvisionerJob.Input,
+			&i.ProvisionerJob.WorkerID,
+			&i.ProvisionerJob.FileID,
+			&i.ProvisionerJob.Tags,
+			&i.ProvisionerJob.ErrorCode,
+			&i.ProvisionerJob.TraceMetadata,
+			&i.ProvisionerJob.JobStatus,
+			&i.ProvisionerJob.Priority,
+			&i.ProvisionerJob.TagRequirements,
+			&i.QueuePosition,
+			&i.QueueSize,
 		); err != nil {
 			return nil, err
 		}
@@ -2546,145 +6079,41 @@ func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error
 	return items, nil
 }
 
-const insertLicense = `-- name: InsertLicense :one
-INSERT INTO
-	licenses (
-	uploaded_at,
-	jwt,
-	exp,
-	uuid
-)
-VALUES
-	($1, $2, $3, $4) RETURNING id, uploaded_at, jwt, exp, uuid
-`
-
-type InsertLicenseParams struct {
-	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
-	JWT        string    `db:"jwt" json:"jwt"`
-	Exp        time.Time `db:"exp" json:"exp"`
-	UUID       uuid.UUID `db:"uuid" json:"uuid"`
-}
-
-func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
-	row := q.db.QueryRowContext(ctx, insertLicense,
-		arg.UploadedAt,
-		arg.JWT,
-		arg.Exp,
-		arg.UUID,
-	)
-	var i License
-	err := row.Scan(
-		&i.ID,
-		&i.UploadedAt,
-		&i.JWT,
-		&i.Exp,
-		&i.UUID,
-	)
-	return i, err
-}
-
-const acquireLock = `-- name: AcquireLock :exec
-SELECT pg_advisory_xact_lock($1)
-`
-
-// Blocks until the lock is acquired.
-//
-// This must be called from within a transaction. The lock will be automatically
-// released when the transaction ends.
-func (q *sqlQuerier) AcquireLock(ctx context.Context, pgAdvisoryXactLock int64) error {
-	_, err := q.db.ExecContext(ctx, acquireLock, pgAdvisoryXactLock)
-	return err
-}
-
-const tryAcquireLock = `-- name: TryAcquireLock :one
-SELECT pg_try_advisory_xact_lock($1)
-`
-
-// Non blocking lock. Returns true if the lock was acquired, false otherwise.
-//
-// This must be called from within a transaction. The lock will be automatically
-// released when the transaction ends.
-func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error) {
-	row := q.db.QueryRowContext(ctx, tryAcquireLock, pgTryAdvisoryXactLock)
-	var pg_try_advisory_xact_lock bool
-	err := row.Scan(&pg_try_advisory_xact_lock)
-	return pg_try_advisory_xact_lock, err
-}
-
-const deleteOAuth2ProviderAppByID = `-- name: DeleteOAuth2ProviderAppByID :exec
-DELETE FROM oauth2_provider_apps WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppByID, id)
-	return err
-}
-
-const deleteOAuth2ProviderAppSecretByID = `-- name: DeleteOAuth2ProviderAppSecretByID :exec
-DELETE FROM oauth2_provider_app_secrets WHERE id = $1
-`
-
-func (q *sqlQuerier) DeleteOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteOAuth2ProviderAppSecretByID, id)
-	return err
-}
-
-const getOAuth2ProviderAppByID = `-- name: GetOAuth2ProviderAppByID :one
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps WHERE id = $1
-`
-
-func (q *sqlQuerier) GetOAuth2ProviderAppByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderApp, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppByID, id)
-	var i OAuth2ProviderApp
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Icon,
-		&i.CallbackURL,
-	)
-	return i, err
-}
-
-const getOAuth2ProviderAppSecretByID = `-- name: GetOAuth2ProviderAppSecretByID :one
-SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE id = $1
-`
-
-func (q *sqlQuerier) GetOAuth2ProviderAppSecretByID(ctx context.Context, id uuid.UUID) (OAuth2ProviderAppSecret, error) {
-	row := q.db.QueryRowContext(ctx, getOAuth2ProviderAppSecretByID, id)
-	var i OAuth2ProviderAppSecret
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.LastUsedAt,
-		&i.HashedSecret,
-		&i.DisplaySecret,
-		&i.AppID,
-	)
-	return i, err
-}
-
-const getOAuth2ProviderAppSecretsByAppID = `-- name: GetOAuth2ProviderAppSecretsByAppID :many
-SELECT id, created_at, last_used_at, hashed_secret, display_secret, app_id FROM oauth2_provider_app_secrets WHERE app_id = $1 ORDER BY (created_at, id) ASC
+const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements FROM provisioner_jobs WHERE created_at > $1
 `
 
-func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, appID uuid.UUID) ([]OAuth2ProviderAppSecret, error) {
-	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderAppSecretsByAppID, appID)
+func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []OAuth2ProviderAppSecret
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i OAuth2ProviderAppSecret
+		var i ProvisionerJob
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.LastUsedAt,
-			&i.HashedSecret,
-			&i.DisplaySecret,
-			&i.AppID,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+			&i.Priority,
+			&i.TagRequirements,
 		); err != nil {
 			return nil, err
 		}
@@ -2699,26 +6128,58 @@ func (q *sqlQuerier) GetOAuth2ProviderAppSecretsByAppID(ctx context.Context, app
 	return items, nil
 }
 
-const getOAuth2ProviderApps = `-- name: GetOAuth2ProviderApps :many
-SELECT id, created_at, updated_at, name, icon, callback_url FROM oauth2_provider_apps ORDER BY (name, id) ASC
+const getTimedOutProvisionerJobs = `-- name: GetTimedOutProvisionerJobs :many
+SELECT
+	provisioner_jobs.id, provisioner_jobs.created_at, provisioner_jobs.updated_at, provisioner_jobs.started_at, provisioner_jobs.canceled_at, provisioner_jobs.completed_at, provisioner_jobs.error, provisioner_jobs.organization_id, provisioner_jobs.initiator_id, provisioner_jobs.provisioner, provisioner_jobs.storage_method, provisioner_jobs.type, provisioner_jobs.input, provisioner_jobs.worker_id, provisioner_jobs.file_id, provisioner_jobs.tags, provisioner_jobs.error_code, provisioner_jobs.trace_metadata, provisioner_jobs.job_status, provisioner_jobs.priority, provisioner_jobs.tag_requirements
+FROM
+	provisioner_jobs
+JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+JOIN
+	templates ON templates.id = workspaces.template_id
+WHERE
+	provisioner_jobs.started_at IS NOT NULL
+	AND provisioner_jobs.completed_at IS NULL
+	AND templates.max_build_duration > 0
+	AND provisioner_jobs.started_at < $1 :: timestamptz - (templates.max_build_duration / 1000 / 1000 / 1000 || ' seconds')::interval
 `
 
-func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2ProviderApp, error) {
-	rows, err := q.db.QueryContext(ctx, getOAuth2ProviderApps)
+// Returns running workspace builds that started before the given time minus
+// the max build duration of their template.
+
+func (q *sqlQuerier) GetTimedOutProvisionerJobs(ctx context.Context, now time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getTimedOutProvisionerJobs, now)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []OAuth2ProviderApp
+	var items []ProvisionerJob
 	for rows.Next() {
-		var i OAuth2ProviderApp
+		var i ProvisionerJob
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Name,
-			&i.Icon,
-			&i.CallbackURL,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.JobStatus,
+			&i.Priority,
+			&i.TagRequirements,
 		); err != nil {
 			return nil, err
 		}
@@ -2733,186 +6194,447 @@ func (q *sqlQuerier) GetOAuth2ProviderApps(ctx context.Context) ([]OAuth2Provide
 	return items, nil
 }
 
-const insertOAuth2ProviderApp = `-- name: InsertOAuth2ProviderApp :one
-INSERT INTO oauth2_provider_apps (
-    id,
-    created_at,
-    updated_at,
-    name,
-    icon,
-    callback_url
-) VALUES(
-    $1,
-    $2,
-    $3,
-    $4,
-    $5,
-    $6
-) RETURNING id, created_at, updated_at, name, icon, callback_url
+const insertProvisionerJob = `-- name: InsertProvisionerJob :one
+INSERT INTO
+	provisioner_jobs (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		initiator_id,
+		provisioner,
+		storage_method,
+		file_id,
+		"type",
+		"input",
+		tags,
+		trace_metadata,
+		tag_requirements
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status, priority, tag_requirements
 `
 
-type InsertOAuth2ProviderAppParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+type InsertProvisionerJobParams struct {
+	ID              uuid.UUID                  `db:"id" json:"id"`
+	CreatedAt       time.Time                  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time                  `db:"updated_at" json:"updated_at"`
+	OrganizationID  uuid.UUID                  `db:"organization_id" json:"organization_id"`
+	InitiatorID     uuid.UUID                  `db:"initiator_id" json:"initiator_id"`
+	Provisioner     ProvisionerType            `db:"provisioner" json:"provisioner"`
+	StorageMethod   ProvisionerStorageMethod   `db:"storage_method" json:"storage_method"`
+	FileID          uuid.UUID                  `db:"file_id" json:"file_id"`
+	Type            ProvisionerJobType         `db:"type" json:"type"`
+	Input           json.RawMessage            `db:"input" json:"input"`
+	Tags            StringMap                  `db:"tags" json:"tags"`
+	TraceMetadata   pqtype.NullRawMessage      `db:"trace_metadata" json:"trace_metadata"`
+	TagRequirements ProvisionerTagRequirements `db:"tag_requirements" json:"tag_requirements"`
 }
 
-func (q *sqlQuerier) InsertOAuth2ProviderApp(ctx context.Context, arg InsertOAuth2ProviderAppParams) (OAuth2ProviderApp, error) {
-	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderApp,
+func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerJob,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.Name,
-		arg.Icon,
-		arg.CallbackURL,
+		arg.OrganizationID,
+		arg.InitiatorID,
+		arg.Provisioner,
+		arg.StorageMethod,
+		arg.FileID,
+		arg.Type,
+		arg.Input,
+		arg.Tags,
+		arg.TraceMetadata,
+		arg.TagRequirements,
 	)
-	var i OAuth2ProviderApp
+	var i ProvisionerJob
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.StartedAt,
+		&i.CanceledAt,
+		&i.CompletedAt,
+		&i.Error,
+		&i.OrganizationID,
+		&i.InitiatorID,
+		&i.Provisioner,
+		&i.StorageMethod,
+		&i.Type,
+		&i.Input,
+		&i.WorkerID,
+		&i.FileID,
+		&i.Tags,
+		&i.ErrorCode,
+		&i.TraceMetadata,
+		&i.JobStatus,
+		&i.Priority,
+		&i.TagRequirements,
+	)
+	return i, err
+}
+
+const requeueProvisionerJobByID = `-- name: RequeueProvisionerJobByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL
+WHERE
+	id = $1
+	AND completed_at IS NULL
+`
+
+type RequeueProvisionerJobByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Returns a running job to the queue, so it is acquired by another
+// provisioner daemon.
+func (q *sqlQuerier) RequeueProvisionerJobByID(ctx context.Context, arg RequeueProvisionerJobByIDParams) error {
+	_, err := q.db.ExecContext(ctx, requeueProvisionerJobByID, arg.ID, arg.UpdatedAt)
+	return err
+}
+
+const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobByID, arg.ID, arg.UpdatedAt)
+	return err
+}
+
+const updateProvisionerJobPriorityByID = `-- name: UpdateProvisionerJobPriorityByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	priority = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobPriorityByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Priority  int32     `db:"priority" json:"priority"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobPriorityByID(ctx context.Context, arg UpdateProvisionerJobPriorityByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobPriorityByID, arg.ID, arg.Priority, arg.UpdatedAt)
+	return err
+}
+
+const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	canceled_at = $2,
+	completed_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobWithCancelByIDParams struct {
+	ID          uuid.UUID    `db:"id" json:"id"`
+	CanceledAt  sql.NullTime `db:"canceled_at" json:"canceled_at"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt, arg.CompletedAt)
+	return err
+}
+
+const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	completed_at = $3,
+	error = $4,
+	error_code = $5
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobWithCompleteByIDParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
+	Error       sql.NullString `db:"error" json:"error"`
+	ErrorCode   sql.NullString `db:"error_code" json:"error_code"`
+}
+
+func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.CompletedAt,
+		arg.Error,
+		arg.ErrorCode,
+	)
+	return err
+}
+
+const deleteProvisionerKey = `-- name: DeleteProvisionerKey :exec
+DELETE FROM
+	provisioner_keys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteProvisionerKey(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteProvisionerKey, id)
+	return err
+}
+
+const getProvisionerKeyByHashedSecret = `-- name: GetProvisionerKeyByHashedSecret :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags, rotated_at
+FROM
+	provisioner_keys
+WHERE
+	hashed_secret = $1
+`
+
+func (q *sqlQuerier) GetProvisionerKeyByHashedSecret(ctx context.Context, hashedSecret []byte) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByHashedSecret, hashedSecret)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
 		&i.Name,
-		&i.Icon,
-		&i.CallbackURL,
+		&i.HashedSecret,
+		&i.Tags,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
-const insertOAuth2ProviderAppSecret = `-- name: InsertOAuth2ProviderAppSecret :one
-INSERT INTO oauth2_provider_app_secrets (
-    id,
-    created_at,
-    hashed_secret,
-    display_secret,
-    app_id
-) VALUES(
-    $1,
-    $2,
-    $3,
-    $4,
-    $5
-) RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
+const getProvisionerKeyByID = `-- name: GetProvisionerKeyByID :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags, rotated_at
+FROM
+	provisioner_keys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetProvisionerKeyByID(ctx context.Context, id uuid.UUID) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByID, id)
+	var i ProvisionerKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.Name,
+		&i.HashedSecret,
+		&i.Tags,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const getProvisionerKeyByName = `-- name: GetProvisionerKeyByName :one
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags, rotated_at
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+	AND lower("name") = lower($2)
 `
 
-type InsertOAuth2ProviderAppSecretParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CreatedAt     time.Time `db:"created_at" json:"created_at"`
-	HashedSecret  []byte    `db:"hashed_secret" json:"hashed_secret"`
-	DisplaySecret string    `db:"display_secret" json:"display_secret"`
-	AppID         uuid.UUID `db:"app_id" json:"app_id"`
+type GetProvisionerKeyByNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) InsertOAuth2ProviderAppSecret(ctx context.Context, arg InsertOAuth2ProviderAppSecretParams) (OAuth2ProviderAppSecret, error) {
-	row := q.db.QueryRowContext(ctx, insertOAuth2ProviderAppSecret,
-		arg.ID,
-		arg.CreatedAt,
-		arg.HashedSecret,
-		arg.DisplaySecret,
-		arg.AppID,
-	)
-	var i OAuth2ProviderAppSecret
+func (q *sqlQuerier) GetProvisionerKeyByName(ctx context.Context, arg GetProvisionerKeyByNameParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerKeyByName, arg.OrganizationID, arg.Name)
+	var i ProvisionerKey
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.LastUsedAt,
+		&i.OrganizationID,
+		&i.Name,
 		&i.HashedSecret,
-		&i.DisplaySecret,
-		&i.AppID,
+		&i.Tags,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
-const updateOAuth2ProviderAppByID = `-- name: UpdateOAuth2ProviderAppByID :one
-UPDATE oauth2_provider_apps SET
-    updated_at = $2,
-    name = $3,
-    icon = $4,
-    callback_url = $5
-WHERE id = $1 RETURNING id, created_at, updated_at, name, icon, callback_url
+const insertProvisionerKey = `-- name: InsertProvisionerKey :one
+INSERT INTO
+	provisioner_keys (
+		id,
+		created_at,
+		organization_id,
+		"name",
+		hashed_secret,
+		tags
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, created_at, organization_id, name, hashed_secret, tags, rotated_at
 `
 
-type UpdateOAuth2ProviderAppByIDParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	Name        string    `db:"name" json:"name"`
-	Icon        string    `db:"icon" json:"icon"`
-	CallbackURL string    `db:"callback_url" json:"callback_url"`
+type InsertProvisionerKeyParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
+	HashedSecret   []byte    `db:"hashed_secret" json:"hashed_secret"`
+	Tags           StringMap `db:"tags" json:"tags"`
 }
 
-func (q *sqlQuerier) UpdateOAuth2ProviderAppByID(ctx context.Context, arg UpdateOAuth2ProviderAppByIDParams) (OAuth2ProviderApp, error) {
-	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppByID,
+func (q *sqlQuerier) InsertProvisionerKey(ctx context.Context, arg InsertProvisionerKeyParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerKey,
 		arg.ID,
-		arg.UpdatedAt,
+		arg.CreatedAt,
+		arg.OrganizationID,
 		arg.Name,
-		arg.Icon,
-		arg.CallbackURL,
+		arg.HashedSecret,
+		arg.Tags,
 	)
-	var i OAuth2ProviderApp
+	var i ProvisionerKey
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.OrganizationID,
 		&i.Name,
-		&i.Icon,
-		&i.CallbackURL,
+		&i.HashedSecret,
+		&i.Tags,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
-const updateOAuth2ProviderAppSecretByID = `-- name: UpdateOAuth2ProviderAppSecretByID :one
-UPDATE oauth2_provider_app_secrets SET
-    last_used_at = $2
-WHERE id = $1 RETURNING id, created_at, last_used_at, hashed_secret, display_secret, app_id
+const listProvisionerKeysByOrganization = `-- name: ListProvisionerKeysByOrganization :many
+SELECT
+	id, created_at, organization_id, name, hashed_secret, tags, rotated_at
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+ORDER BY
+	lower("name") ASC
 `
 
-type UpdateOAuth2ProviderAppSecretByIDParams struct {
-	ID         uuid.UUID    `db:"id" json:"id"`
-	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+func (q *sqlQuerier) ListProvisionerKeysByOrganization(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
+	rows, err := q.db.QueryContext(ctx, listProvisionerKeysByOrganization, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerKey
+	for rows.Next() {
+		var i ProvisionerKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.OrganizationID,
+			&i.Name,
+			&i.HashedSecret,
+			&i.Tags,
+			&i.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateOAuth2ProviderAppSecretByID(ctx context.Context, arg UpdateOAuth2ProviderAppSecretByIDParams) (OAuth2ProviderAppSecret, error) {
-	row := q.db.QueryRowContext(ctx, updateOAuth2ProviderAppSecretByID, arg.ID, arg.LastUsedAt)
-	var i OAuth2ProviderAppSecret
+const updateProvisionerKeySecretByID = `-- name: UpdateProvisionerKeySecretByID :one
+UPDATE
+	provisioner_keys
+SET
+	hashed_secret = $1,
+	rotated_at = $2
+WHERE
+	id = $3
+RETURNING id, created_at, organization_id, name, hashed_secret, tags, rotated_at
+`
+
+type UpdateProvisionerKeySecretByIDParams struct {
+	HashedSecret []byte       `db:"hashed_secret" json:"hashed_secret"`
+	RotatedAt    sql.NullTime `db:"rotated_at" json:"rotated_at"`
+	ID           uuid.UUID    `db:"id" json:"id"`
+}
+
+// Replaces the secret of the key. Daemons authenticated with the old secret
+// are disconnected.
+func (q *sqlQuerier) UpdateProvisionerKeySecretByID(ctx context.Context, arg UpdateProvisionerKeySecretByIDParams) (ProvisionerKey, error) {
+	row := q.db.QueryRowContext(ctx, updateProvisionerKeySecretByID, arg.HashedSecret, arg.RotatedAt, arg.ID)
+	var i ProvisionerKey
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.LastUsedAt,
+		&i.OrganizationID,
+		&i.Name,
 		&i.HashedSecret,
-		&i.DisplaySecret,
-		&i.AppID,
+		&i.Tags,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
-const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
+const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
-    user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
 FROM
-    organization_members
+	workspace_proxies
 WHERE
-    user_id = ANY($1 :: uuid [ ])
-GROUP BY
-    user_id
+	deleted = false
 `
 
-type GetOrganizationIDsByMemberIDsRow struct {
-	UserID          uuid.UUID   `db:"user_id" json:"user_id"`
-	OrganizationIDs []uuid.UUID `db:"organization_IDs" json:"organization_IDs"`
-}
-
-func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uuid.UUID) ([]GetOrganizationIDsByMemberIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationIDsByMemberIDs, pq.Array(ids))
+func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetOrganizationIDsByMemberIDsRow
+	var items []WorkspaceProxy
 	for rows.Next() {
-		var i GetOrganizationIDsByMemberIDsRow
-		if err := rows.Scan(&i.UserID, pq.Array(&i.OrganizationIDs)); err != nil {
+		var i WorkspaceProxy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Url,
+			&i.WildcardHostname,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.TokenHashedSecret,
+			&i.RegionID,
+			&i.DerpEnabled,
+			&i.DerpOnly,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -2926,60 +6648,164 @@ func (q *sqlQuerier) GetOrganizationIDsByMemberIDs(ctx context.Context, ids []uu
 	return items, nil
 }
 
-const getOrganizationMemberByUserID = `-- name: GetOrganizationMemberByUserID :one
+const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+FROM
+	workspace_proxies
+WHERE
+	-- Validate that the @hostname has been sanitized and is not empty. This
+	-- doesn't prevent SQL injection (already prevented by using prepared
+	-- queries), but it does prevent carefully crafted hostnames from matching
+	-- when they shouldn't.
+	--
+	-- Periods don't need to be escaped because they're not special characters
+	-- in SQL matches unlike regular expressions.
+	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
+	deleted = false AND
+
+	-- Validate that the hostname matches either the wildcard hostname or the
+	-- access URL (ignoring scheme, port and path).
+	(
+		(
+			$2 :: bool = true AND
+			url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*'
+		) OR
+		(
+			$3 :: bool = true AND
+			$1 :: text LIKE replace(wildcard_hostname, '*', '%')
+		)
+	)
+LIMIT
+	1
+`
+
+type GetWorkspaceProxyByHostnameParams struct {
+	Hostname              string `db:"hostname" json:"hostname"`
+	AllowAccessUrl        bool   `db:"allow_access_url" json:"allow_access_url"`
+	AllowWildcardHostname bool   `db:"allow_wildcard_hostname" json:"allow_wildcard_hostname"`
+}
+
+// Finds a workspace proxy that has an access URL or app hostname that matches
+// the provided hostname. This is to check if a hostname matches any workspace
+// proxy.
+//
+// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
+// this query. The scheme, port and path should be stripped.
+func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, arg.Hostname, arg.AllowAccessUrl, arg.AllowWildcardHostname)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
+SELECT
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+FROM
+	workspace_proxies
+WHERE
+	id = $1
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
 SELECT
-	user_id, organization_id, created_at, updated_at, roles
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
 FROM
-	organization_members
+	workspace_proxies
 WHERE
-	organization_id = $1
-	AND user_id = $2
+	name = $1
+	AND deleted = false
 LIMIT
 	1
 `
 
-type GetOrganizationMemberByUserIDParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-}
-
-func (q *sqlQuerier) GetOrganizationMemberByUserID(ctx context.Context, arg GetOrganizationMemberByUserIDParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationMemberByUserID, arg.OrganizationID, arg.UserID)
-	var i OrganizationMember
+func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByName, name)
+	var i WorkspaceProxy
 	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		pq.Array(&i.Roles),
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
 	)
 	return i, err
 }
 
-const getOrganizationMembershipsByUserID = `-- name: GetOrganizationMembershipsByUserID :many
+const getWorkspaceProxyHealth = `-- name: GetWorkspaceProxyHealth :many
 SELECT
-	user_id, organization_id, created_at, updated_at, roles
+	proxy_id, status, latency_ms, derp_reachable, version_skew, errors, warnings, checked_at
 FROM
-	organization_members
-WHERE
-  user_id = $1
+	workspace_proxy_health
 `
 
-func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationMembershipsByUserID, userID)
+func (q *sqlQuerier) GetWorkspaceProxyHealth(ctx context.Context) ([]WorkspaceProxyHealth, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceProxyHealth)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []OrganizationMember
+	var items []WorkspaceProxyHealth
 	for rows.Next() {
-		var i OrganizationMember
+		var i WorkspaceProxyHealth
 		if err := rows.Scan(
-			&i.UserID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			pq.Array(&i.Roles),
+			&i.ProxyID,
+			&i.Status,
+			&i.LatencyMS,
+			&i.DerpReachable,
+			&i.VersionSkew,
+			pq.Array(&i.Errors),
+			pq.Array(&i.Warnings),
+			&i.CheckedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2994,145 +6820,315 @@ func (q *sqlQuerier) GetOrganizationMembershipsByUserID(ctx context.Context, use
 	return items, nil
 }
 
-const insertOrganizationMember = `-- name: InsertOrganizationMember :one
+const insertWorkspaceProxy = `-- name: InsertWorkspaceProxy :one
 INSERT INTO
-	organization_members (
-		organization_id,
-		user_id,
+	workspace_proxies (
+		id,
+		url,
+		wildcard_hostname,
+		name,
+		display_name,
+		icon,
+		derp_enabled,
+		derp_only,
+		token_hashed_secret,
 		created_at,
 		updated_at,
-		roles
+		deleted
 	)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING user_id, organization_id, created_at, updated_at, roles
+	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
 `
 
-type InsertOrganizationMemberParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
-	Roles          []string  `db:"roles" json:"roles"`
+type InsertWorkspaceProxyParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	Name              string    `db:"name" json:"name"`
+	DisplayName       string    `db:"display_name" json:"display_name"`
+	Icon              string    `db:"icon" json:"icon"`
+	DerpEnabled       bool      `db:"derp_enabled" json:"derp_enabled"`
+	DerpOnly          bool      `db:"derp_only" json:"derp_only"`
+	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, insertOrganizationMember,
-		arg.OrganizationID,
-		arg.UserID,
+func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceProxy,
+		arg.ID,
+		arg.Name,
+		arg.DisplayName,
+		arg.Icon,
+		arg.DerpEnabled,
+		arg.DerpOnly,
+		arg.TokenHashedSecret,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		pq.Array(arg.Roles),
 	)
-	var i OrganizationMember
+	var i WorkspaceProxy
 	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		pq.Array(&i.Roles),
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
 	)
 	return i, err
 }
 
-const updateMemberRoles = `-- name: UpdateMemberRoles :one
+const registerWorkspaceProxy = `-- name: RegisterWorkspaceProxy :one
 UPDATE
-	organization_members
+	workspace_proxies
 SET
-	-- Remove all duplicates from the roles.
-	roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+	url = $1 :: text,
+	wildcard_hostname = $2 :: text,
+	derp_enabled = $3 :: boolean,
+	derp_only = $4 :: boolean,
+	version = $5 :: text,
+	updated_at = Now()
 WHERE
-	user_id = $2
-	AND organization_id = $3
-RETURNING user_id, organization_id, created_at, updated_at, roles
+	id = $6
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
 `
 
-type UpdateMemberRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
-	OrgID        uuid.UUID `db:"org_id" json:"org_id"`
+type RegisterWorkspaceProxyParams struct {
+	Url              string    `db:"url" json:"url"`
+	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
+	DerpEnabled      bool      `db:"derp_enabled" json:"derp_enabled"`
+	DerpOnly         bool      `db:"derp_only" json:"derp_only"`
+	Version          string    `db:"version" json:"version"`
+	ID               uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, registerWorkspaceProxy,
+		arg.Url,
+		arg.WildcardHostname,
+		arg.DerpEnabled,
+		arg.DerpOnly,
+		arg.Version,
+		arg.ID,
+	)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
+UPDATE
+	workspace_proxies
+SET
+	-- These values should always be provided.
+	name = $1,
+	display_name = $2,
+	icon = $3,
+	-- Only update the token if a new one is provided.
+	-- So this is an optional field.
+	token_hashed_secret = CASE
+		WHEN length($4 :: bytea) > 0  THEN $4 :: bytea
+		ELSE  workspace_proxies.token_hashed_secret
+	END,
+	-- Always update this timestamp.
+	updated_at = Now()
+WHERE
+	id = $5
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+`
+
+type UpdateWorkspaceProxyParams struct {
+	Name              string    `db:"name" json:"name"`
+	DisplayName       string    `db:"display_name" json:"display_name"`
+	Icon              string    `db:"icon" json:"icon"`
+	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
+	ID                uuid.UUID `db:"id" json:"id"`
+}
+
+// This allows editing the properties of a workspace proxy.
+func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
+		arg.Name,
+		arg.DisplayName,
+		arg.Icon,
+		arg.TokenHashedSecret,
+		arg.ID,
+	)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
+UPDATE
+	workspace_proxies
+SET
+	updated_at = Now(),
+	deleted = $1
+WHERE
+	id = $2
+`
+
+type UpdateWorkspaceProxyDeletedParams struct {
+	Deleted bool      `db:"deleted" json:"deleted"`
+	ID      uuid.UUID `db:"id" json:"id"`
 }
 
-func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error) {
-	row := q.db.QueryRowContext(ctx, updateMemberRoles, pq.Array(arg.GrantedRoles), arg.UserID, arg.OrgID)
-	var i OrganizationMember
-	err := row.Scan(
-		&i.UserID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Roles),
+func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceProxyDeleted, arg.Deleted, arg.ID)
+	return err
+}
+
+const upsertWorkspaceProxyHealth = `-- name: UpsertWorkspaceProxyHealth :exec
+INSERT INTO
+	workspace_proxy_health (proxy_id, status, latency_ms, derp_reachable, version_skew, errors, warnings, checked_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (proxy_id) DO UPDATE SET
+	status = $2,
+	latency_ms = $3,
+	derp_reachable = $4,
+	version_skew = $5,
+	errors = $6,
+	warnings = $7,
+	checked_at = $8
+`
+
+type UpsertWorkspaceProxyHealthParams struct {
+	ProxyID       uuid.UUID `db:"proxy_id" json:"proxy_id"`
+	Status        string    `db:"status" json:"status"`
+	LatencyMS     int32     `db:"latency_ms" json:"latency_ms"`
+	DerpReachable bool      `db:"derp_reachable" json:"derp_reachable"`
+	VersionSkew   bool      `db:"version_skew" json:"version_skew"`
+	Errors        []string  `db:"errors" json:"errors"`
+	Warnings      []string  `db:"warnings" json:"warnings"`
+	CheckedAt     time.Time `db:"checked_at" json:"checked_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceProxyHealth(ctx context.Context, arg UpsertWorkspaceProxyHealthParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceProxyHealth,
+		arg.ProxyID,
+		arg.Status,
+		arg.LatencyMS,
+		arg.DerpReachable,
+		arg.VersionSkew,
+		pq.Array(arg.Errors),
+		pq.Array(arg.Warnings),
+		arg.CheckedAt,
 	)
-	return i, err
+	return err
 }
 
-const getOrganizationByID = `-- name: GetOrganizationByID :one
+const getOrganizationQuota = `-- name: GetOrganizationQuota :one
 SELECT
-	id, name, description, created_at, updated_at
+	organization_id, max_running_workspaces_per_user, max_daily_cost, updated_at
 FROM
-	organizations
+	organization_quotas
 WHERE
-	id = $1
+	organization_id = $1
 `
 
-func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationByID, id)
-	var i Organization
+func (q *sqlQuerier) GetOrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationQuota, organizationID)
+	var i OrganizationQuota
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.MaxRunningWorkspacesPerUser,
+		&i.MaxDailyCost,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getOrganizationByName = `-- name: GetOrganizationByName :one
+const getOrganizationResourceCostRollup = `-- name: GetOrganizationResourceCostRollup :many
 SELECT
-	id, name, description, created_at, updated_at
+	workspaces.owner_id,
+	workspaces.template_id,
+	workspace_resource_costs.currency,
+	COUNT(DISTINCT workspace_resource_costs.workspace_id)::bigint AS workspace_count,
+	SUM(workspace_resource_costs.daily_cost)::bigint AS total_cost
 FROM
-	organizations
+	workspace_resource_costs
+JOIN workspaces ON
+	workspaces.id = workspace_resource_costs.workspace_id
 WHERE
-	LOWER("name") = LOWER($1)
-LIMIT
-	1
+	workspace_resource_costs.organization_id = $1
+	AND workspace_resource_costs.date >= $2::date
+	AND workspace_resource_costs.date <= $3::date
+GROUP BY
+	workspaces.owner_id, workspaces.template_id, workspace_resource_costs.currency
+ORDER BY
+	workspaces.owner_id, workspaces.template_id, workspace_resource_costs.currency
 `
 
-func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, getOrganizationByName, name)
-	var i Organization
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type GetOrganizationResourceCostRollupParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	StartDate      time.Time `db:"start_date" json:"start_date"`
+	EndDate        time.Time `db:"end_date" json:"end_date"`
 }
 
-const getOrganizations = `-- name: GetOrganizations :many
-SELECT
-	id, name, description, created_at, updated_at
-FROM
-	organizations
-`
+type GetOrganizationResourceCostRollupRow struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	Currency       string    `db:"currency" json:"currency"`
+	WorkspaceCount int64     `db:"workspace_count" json:"workspace_count"`
+	TotalCost      int64     `db:"total_cost" json:"total_cost"`
+}
 
-func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizations)
+// Sums the recorded resource costs of the workspaces in an organization per
+// owner, template and currency. Every record is the cost of a single day, so
+// the sum is the total cost over the date range.
+func (q *sqlQuerier) GetOrganizationResourceCostRollup(ctx context.Context, arg GetOrganizationResourceCostRollupParams) ([]GetOrganizationResourceCostRollupRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationResourceCostRollup, arg.OrganizationID, arg.StartDate, arg.EndDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Organization
+	var items []GetOrganizationResourceCostRollupRow
 	for rows.Next() {
-		var i Organization
+		var i GetOrganizationResourceCostRollupRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.TemplateID,
+			&i.Currency,
+			&i.WorkspaceCount,
+			&i.TotalCost,
 		); err != nil {
 			return nil, err
 		}
@@ -3147,36 +7143,178 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 	return items, nil
 }
 
-const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
+const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
 SELECT
-	id, name, description, created_at, updated_at
+	coalesce(SUM(quota_allowance), 0)::BIGINT
 FROM
-	organizations
+	groups g
+LEFT JOIN group_members gm ON
+	g.id = gm.group_id
 WHERE
-	id = (
-		SELECT
-			organization_id
-		FROM
-			organization_members
-		WHERE
-			user_id = $1
+	user_id = $1
+OR
+    g.id = g.organization_id
+`
+
+func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getQuotaConsumedForOrganization = `-- name: GetQuotaConsumedForOrganization :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.organization_id = $1
+`
+
+func (q *sqlQuerier) GetQuotaConsumedForOrganization(ctx context.Context, organizationID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForOrganization, organizationID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1
+`
+
+func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getRunningWorkspaceCounts = `-- name: GetRunningWorkspaceCounts :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	transition,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+SELECT
+	COUNT(*) FILTER (WHERE workspaces.owner_id = $1 AND workspaces.organization_id = $2) AS owner_count,
+	COUNT(*) FILTER (WHERE workspaces.template_id = $3) AS template_count
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN provisioner_jobs ON
+	provisioner_jobs.id = latest_builds.job_id
+WHERE
+	NOT workspaces.deleted
+	AND workspaces.id != $4
+	AND latest_builds.transition = 'start'
+	AND provisioner_jobs.canceled_at IS NULL
+	AND provisioner_jobs.error IS NULL
+`
+
+type GetRunningWorkspaceCountsParams struct {
+	OwnerID        uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+}
+
+type GetRunningWorkspaceCountsRow struct {
+	OwnerCount    int64 `db:"owner_count" json:"owner_count"`
+	TemplateCount int64 `db:"template_count" json:"template_count"`
+}
+
+// Counts the running workspaces of an owner in an organization and of a
+// template. A workspace is running if its latest build started it and didn't
+// fail or get canceled. The given workspace isn't counted, so restarting or
+// updating it isn't limited.
+func (q *sqlQuerier) GetRunningWorkspaceCounts(ctx context.Context, arg GetRunningWorkspaceCountsParams) (GetRunningWorkspaceCountsRow, error) {
+	row := q.db.QueryRowContext(ctx, getRunningWorkspaceCounts,
+		arg.OwnerID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.WorkspaceID,
 	)
+	var i GetRunningWorkspaceCountsRow
+	err := row.Scan(&i.OwnerCount, &i.TemplateCount)
+	return i, err
+}
+
+const getWorkspaceResourceCosts = `-- name: GetWorkspaceResourceCosts :many
+SELECT
+	workspace_id, organization_id, date, resource_type, resource_name, currency, daily_cost, updated_at
+FROM
+	workspace_resource_costs
+WHERE
+	workspace_id = $1
+	AND date >= $2::date
+	AND date <= $3::date
+ORDER BY
+	date, resource_type, resource_name
 `
 
-func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error) {
-	rows, err := q.db.QueryContext(ctx, getOrganizationsByUserID, userID)
+type GetWorkspaceResourceCostsParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	StartDate   time.Time `db:"start_date" json:"start_date"`
+	EndDate     time.Time `db:"end_date" json:"end_date"`
+}
+
+func (q *sqlQuerier) GetWorkspaceResourceCosts(ctx context.Context, arg GetWorkspaceResourceCostsParams) ([]WorkspaceResourceCost, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceResourceCosts, arg.WorkspaceID, arg.StartDate, arg.EndDate)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Organization
+	var items []WorkspaceResourceCost
 	for rows.Next() {
-		var i Organization
+		var i WorkspaceResourceCost
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.CreatedAt,
+			&i.WorkspaceID,
+			&i.OrganizationID,
+			&i.Date,
+			&i.ResourceType,
+			&i.ResourceName,
+			&i.Currency,
+			&i.DailyCost,
 			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -3192,134 +7330,198 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.U
 	return items, nil
 }
 
-const insertOrganization = `-- name: InsertOrganization :one
+const upsertOrganizationQuota = `-- name: UpsertOrganizationQuota :one
 INSERT INTO
-	organizations (id, "name", description, created_at, updated_at)
+	organization_quotas (organization_id, max_running_workspaces_per_user, max_daily_cost, updated_at)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
+	($1, $2, $3, $4)
+ON CONFLICT
+	(organization_id)
+DO UPDATE SET
+	max_running_workspaces_per_user = $2,
+	max_daily_cost = $3,
+	updated_at = $4
+RETURNING organization_id, max_running_workspaces_per_user, max_daily_cost, updated_at
 `
 
-type InsertOrganizationParams struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+type UpsertOrganizationQuotaParams struct {
+	OrganizationID              uuid.UUID `db:"organization_id" json:"organization_id"`
+	MaxRunningWorkspacesPerUser int32     `db:"max_running_workspaces_per_user" json:"max_running_workspaces_per_user"`
+	MaxDailyCost                int32     `db:"max_daily_cost" json:"max_daily_cost"`
+	UpdatedAt                   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error) {
-	row := q.db.QueryRowContext(ctx, insertOrganization,
-		arg.ID,
-		arg.Name,
-		arg.Description,
-		arg.CreatedAt,
+func (q *sqlQuerier) UpsertOrganizationQuota(ctx context.Context, arg UpsertOrganizationQuotaParams) (OrganizationQuota, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationQuota,
+		arg.OrganizationID,
+		arg.MaxRunningWorkspacesPerUser,
+		arg.MaxDailyCost,
 		arg.UpdatedAt,
 	)
-	var i Organization
+	var i OrganizationQuota
 	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
+		&i.OrganizationID,
+		&i.MaxRunningWorkspacesPerUser,
+		&i.MaxDailyCost,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getParameterSchemasByJobID = `-- name: GetParameterSchemasByJobID :many
-SELECT
-	id, created_at, job_id, name, description, default_source_scheme, default_source_value, allow_override_source, default_destination_scheme, allow_override_destination, default_refresh, redisplay_value, validation_error, validation_condition, validation_type_system, validation_value_type, index
-FROM
-	parameter_schemas
-WHERE
-	job_id = $1
-ORDER BY
-	index
+const upsertWorkspaceResourceCosts = `-- name: UpsertWorkspaceResourceCosts :exec
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+INSERT INTO
+	workspace_resource_costs (workspace_id, organization_id, date, resource_type, resource_name, currency, daily_cost, updated_at)
+SELECT
+	workspaces.id,
+	workspaces.organization_id,
+	$1::date,
+	workspace_resources.type,
+	workspace_resources.name,
+	MAX(COALESCE(workspace_resource_metadata.value, $2::text)),
+	SUM(workspace_resources.daily_cost)::integer,
+	$3::timestamptz
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN workspace_resources ON
+	workspace_resources.job_id = latest_builds.job_id
+LEFT JOIN workspace_resource_metadata ON
+	workspace_resource_metadata.workspace_resource_id = workspace_resources.id
+	AND workspace_resource_metadata.key = 'currency'
+WHERE
+	NOT workspaces.deleted
+	AND workspace_resources.daily_cost > 0
+GROUP BY
+	workspaces.id, workspaces.organization_id, workspace_resources.type, workspace_resources.name
+ON CONFLICT
+	(workspace_id, date, resource_type, resource_name)
+DO UPDATE SET
+	currency = EXCLUDED.currency,
+	daily_cost = EXCLUDED.daily_cost,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertWorkspaceResourceCostsParams struct {
+	Date            time.Time `db:"date" json:"date"`
+	DefaultCurrency string    `db:"default_currency" json:"default_currency"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Records the daily cost of the resources of the latest build of every
+// workspace that is not deleted. Recording again on the same date replaces
+// the earlier record, so the last known cost of a day wins.
+func (q *sqlQuerier) UpsertWorkspaceResourceCosts(ctx context.Context, arg UpsertWorkspaceResourceCostsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceResourceCosts, arg.Date, arg.DefaultCurrency, arg.UpdatedAt)
+	return err
+}
+
+const deleteOldRateLimitCounters = `-- name: DeleteOldRateLimitCounters :exec
+DELETE FROM rate_limit_counters WHERE window_start < $1
+`
+
+func (q *sqlQuerier) DeleteOldRateLimitCounters(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldRateLimitCounters, beforeTime)
+	return err
+}
+
+const incrementRateLimitCounter = `-- name: IncrementRateLimitCounter :one
+INSERT INTO
+	rate_limit_counters (key, window_start, count)
+VALUES
+	($1, $2, 1)
+ON CONFLICT
+	(key, window_start)
+DO UPDATE SET
+	count = rate_limit_counters.count + 1
+RETURNING count
 `
 
-func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error) {
-	rows, err := q.db.QueryContext(ctx, getParameterSchemasByJobID, jobID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ParameterSchema
-	for rows.Next() {
-		var i ParameterSchema
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.JobID,
-			&i.Name,
-			&i.Description,
-			&i.DefaultSourceScheme,
-			&i.DefaultSourceValue,
-			&i.AllowOverrideSource,
-			&i.DefaultDestinationScheme,
-			&i.AllowOverrideDestination,
-			&i.DefaultRefresh,
-			&i.RedisplayValue,
-			&i.ValidationError,
-			&i.ValidationCondition,
-			&i.ValidationTypeSystem,
-			&i.ValidationValueType,
-			&i.Index,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type IncrementRateLimitCounterParams struct {
+	Key         string    `db:"key" json:"key"`
+	WindowStart time.Time `db:"window_start" json:"window_start"`
 }
 
-const deleteOldProvisionerDaemons = `-- name: DeleteOldProvisionerDaemons :exec
-DELETE FROM provisioner_daemons WHERE (
-	(created_at < (NOW() - INTERVAL '7 days') AND last_seen_at IS NULL) OR
-	(last_seen_at IS NOT NULL AND last_seen_at < (NOW() - INTERVAL '7 days'))
-)
+// Counts a request against the key for the window, returning the number of
+// requests made in the window so far. The upsert is atomic, so replicas
+// counting the same key concurrently never lose a request.
+func (q *sqlQuerier) IncrementRateLimitCounter(ctx context.Context, arg IncrementRateLimitCounterParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementRateLimitCounter, arg.Key, arg.WindowStart)
+	var count int32
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
+DELETE FROM replicas WHERE updated_at < $1
 `
 
-// Delete provisioner daemons that have been created at least a week ago
-// and have not connected to coderd since a week.
-// A provisioner daemon with "zeroed" last_seen_at column indicates possible
-// connectivity issues (no provisioner daemon activity since registration).
-func (q *sqlQuerier) DeleteOldProvisionerDaemons(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldProvisionerDaemons)
+func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
 	return err
 }
 
-const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
-SELECT
-	id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version
-FROM
-	provisioner_daemons
+const getReplicaByID = `-- name: GetReplicaByID :one
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
 `
 
-func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerDaemons)
+func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
+	)
+	return i, err
+}
+
+const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
+`
+
+func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
+	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerDaemon
+	var items []Replica
 	for rows.Next() {
-		var i ProvisionerDaemon
+		var i Replica
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.Name,
-			pq.Array(&i.Provisioners),
-			&i.ReplicaID,
-			&i.Tags,
-			&i.LastSeenAt,
+			&i.StartedAt,
+			&i.StoppedAt,
+			&i.UpdatedAt,
+			&i.Hostname,
+			&i.RegionID,
+			&i.RelayAddress,
+			&i.DatabaseLatency,
 			&i.Version,
-			&i.APIVersion,
+			&i.Error,
+			&i.Primary,
 		); err != nil {
 			return nil, err
 		}
@@ -3334,128 +7536,156 @@ func (q *sqlQuerier) GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDa
 	return items, nil
 }
 
-const updateProvisionerDaemonLastSeenAt = `-- name: UpdateProvisionerDaemonLastSeenAt :exec
-UPDATE provisioner_daemons
-SET
-	last_seen_at = $1
-WHERE
-	id = $2
-AND
-	last_seen_at <= $1
+const insertReplica = `-- name: InsertReplica :one
+INSERT INTO replicas (
+    id,
+    created_at,
+    started_at,
+    updated_at,
+    hostname,
+    region_id,
+    relay_address,
+    version,
+    database_latency,
+	"primary"
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
 `
 
-type UpdateProvisionerDaemonLastSeenAtParams struct {
-	LastSeenAt sql.NullTime `db:"last_seen_at" json:"last_seen_at"`
-	ID         uuid.UUID    `db:"id" json:"id"`
+type InsertReplicaParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	StartedAt       time.Time `db:"started_at" json:"started_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Hostname        string    `db:"hostname" json:"hostname"`
+	RegionID        int32     `db:"region_id" json:"region_id"`
+	RelayAddress    string    `db:"relay_address" json:"relay_address"`
+	Version         string    `db:"version" json:"version"`
+	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
+	Primary         bool      `db:"primary" json:"primary"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerDaemonLastSeenAt(ctx context.Context, arg UpdateProvisionerDaemonLastSeenAtParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerDaemonLastSeenAt, arg.LastSeenAt, arg.ID)
-	return err
+func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, insertReplica,
+		arg.ID,
+		arg.CreatedAt,
+		arg.StartedAt,
+		arg.UpdatedAt,
+		arg.Hostname,
+		arg.RegionID,
+		arg.RelayAddress,
+		arg.Version,
+		arg.DatabaseLatency,
+		arg.Primary,
+	)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
+	)
+	return i, err
 }
 
-const upsertProvisionerDaemon = `-- name: UpsertProvisionerDaemon :one
-INSERT INTO
-	provisioner_daemons (
-		id,
-		created_at,
-		"name",
-		provisioners,
-		tags,
-		last_seen_at,
-		"version",
-		api_version
-	)
-VALUES (
-	gen_random_uuid(),
-	$1,
-	$2,
-	$3,
-	$4,
-	$5,
-	$6,
-	$7
-) ON CONFLICT("name", LOWER(COALESCE(tags ->> 'owner'::text, ''::text))) DO UPDATE SET
-	provisioners = $3,
-	tags = $4,
-	last_seen_at = $5,
-	"version" = $6,
-	api_version = $7
-WHERE
-	-- Only ones with the same tags are allowed clobber
-	provisioner_daemons.tags <@ $4 :: jsonb
-RETURNING id, created_at, name, provisioners, replica_id, tags, last_seen_at, version, api_version
+const updateReplica = `-- name: UpdateReplica :one
+UPDATE replicas SET
+    updated_at = $2,
+    started_at = $3,
+    stopped_at = $4,
+    relay_address = $5,
+    region_id = $6,
+    hostname = $7,
+    version = $8,
+    error = $9,
+    database_latency = $10,
+	"primary" = $11
+WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
 `
 
-type UpsertProvisionerDaemonParams struct {
-	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
-	Name         string            `db:"name" json:"name"`
-	Provisioners []ProvisionerType `db:"provisioners" json:"provisioners"`
-	Tags         StringMap         `db:"tags" json:"tags"`
-	LastSeenAt   sql.NullTime      `db:"last_seen_at" json:"last_seen_at"`
-	Version      string            `db:"version" json:"version"`
-	APIVersion   string            `db:"api_version" json:"api_version"`
+type UpdateReplicaParams struct {
+	ID              uuid.UUID    `db:"id" json:"id"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
+	StartedAt       time.Time    `db:"started_at" json:"started_at"`
+	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
+	RelayAddress    string       `db:"relay_address" json:"relay_address"`
+	RegionID        int32        `db:"region_id" json:"region_id"`
+	Hostname        string       `db:"hostname" json:"hostname"`
+	Version         string       `db:"version" json:"version"`
+	Error           string       `db:"error" json:"error"`
+	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
+	Primary         bool         `db:"primary" json:"primary"`
 }
 
-func (q *sqlQuerier) UpsertProvisionerDaemon(ctx context.Context, arg UpsertProvisionerDaemonParams) (ProvisionerDaemon, error) {
-	row := q.db.QueryRowContext(ctx, upsertProvisionerDaemon,
-		arg.CreatedAt,
-		arg.Name,
-		pq.Array(arg.Provisioners),
-		arg.Tags,
-		arg.LastSeenAt,
+func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, updateReplica,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.StartedAt,
+		arg.StoppedAt,
+		arg.RelayAddress,
+		arg.RegionID,
+		arg.Hostname,
 		arg.Version,
-		arg.APIVersion,
+		arg.Error,
+		arg.DatabaseLatency,
+		arg.Primary,
 	)
-	var i ProvisionerDaemon
+	var i Replica
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.Name,
-		pq.Array(&i.Provisioners),
-		&i.ReplicaID,
-		&i.Tags,
-		&i.LastSeenAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
 		&i.Version,
-		&i.APIVersion,
+		&i.Error,
+		&i.Primary,
 	)
 	return i, err
 }
 
-const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
+const customRolesByName = `-- name: CustomRolesByName :many
 SELECT
-	job_id, created_at, source, level, stage, output, id
+	*
 FROM
-	provisioner_job_logs
+	custom_roles
 WHERE
-	job_id = $1
-	AND (
-		id > $2
-	) ORDER BY id ASC
+	-- Return every custom role if no names are given.
+	CASE WHEN cardinality($1 :: text[]) > 0 THEN
+		name = ANY($1 :: text[])
+	ELSE true
+	END
+ORDER BY
+	name ASC
 `
 
-type GetProvisionerLogsAfterIDParams struct {
-	JobID        uuid.UUID `db:"job_id" json:"job_id"`
-	CreatedAfter int64     `db:"created_after" json:"created_after"`
-}
-
-func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerLogsAfterID, arg.JobID, arg.CreatedAfter)
+func (q *sqlQuerier) CustomRolesByName(ctx context.Context, lookupRoles []string) ([]CustomRole, error) {
+	rows, err := q.db.QueryContext(ctx, customRolesByName, pq.Array(lookupRoles))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJobLog
+	var items []CustomRole
 	for rows.Next() {
-		var i ProvisionerJobLog
+		var i CustomRole
 		if err := rows.Scan(
-			&i.JobID,
+			&i.Name,
+			&i.DisplayName,
+			&i.SitePermissions,
 			&i.CreatedAt,
-			&i.Source,
-			&i.Level,
-			&i.Stage,
-			&i.Output,
-			&i.ID,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3470,178 +7700,102 @@ func (q *sqlQuerier) GetProvisionerLogsAfterID(ctx context.Context, arg GetProvi
 	return items, nil
 }
 
-const insertProvisionerJobLogs = `-- name: InsertProvisionerJobLogs :many
+const upsertCustomRole = `-- name: UpsertCustomRole :one
 INSERT INTO
-	provisioner_job_logs
-SELECT
-	$1 :: uuid AS job_id,
-	unnest($2 :: timestamptz [ ]) AS created_at,
-	unnest($3 :: log_source [ ]) AS source,
-	unnest($4 :: log_level [ ]) AS LEVEL,
-	unnest($5 :: VARCHAR(128) [ ]) AS stage,
-	unnest($6 :: VARCHAR(1024) [ ]) AS output RETURNING job_id, created_at, source, level, stage, output, id
-`
-
-type InsertProvisionerJobLogsParams struct {
-	JobID     uuid.UUID   `db:"job_id" json:"job_id"`
-	CreatedAt []time.Time `db:"created_at" json:"created_at"`
-	Source    []LogSource `db:"source" json:"source"`
-	Level     []LogLevel  `db:"level" json:"level"`
-	Stage     []string    `db:"stage" json:"stage"`
-	Output    []string    `db:"output" json:"output"`
-}
-
-func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error) {
-	rows, err := q.db.QueryContext(ctx, insertProvisionerJobLogs,
-		arg.JobID,
-		pq.Array(arg.CreatedAt),
-		pq.Array(arg.Source),
-		pq.Array(arg.Level),
-		pq.Array(arg.Stage),
-		pq.Array(arg.Output),
+	custom_roles (
+		name,
+		display_name,
+		site_permissions,
+		created_at,
+		updated_at
 	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ProvisionerJobLog
-	for rows.Next() {
-		var i ProvisionerJobLog
-		if err := rows.Scan(
-			&i.JobID,
-			&i.CreatedAt,
-			&i.Source,
-			&i.Level,
-			&i.Stage,
-			&i.Output,
-			&i.ID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
-UPDATE
-	provisioner_jobs
-SET
-	started_at = $1,
-	updated_at = $1,
-	worker_id = $2
-WHERE
-	id = (
-		SELECT
-			id
-		FROM
-			provisioner_jobs AS nested
-		WHERE
-			nested.started_at IS NULL
-			-- Ensure the caller has the correct provisioner.
-			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
-			-- Ensure the caller satisfies all job tags.
-			AND nested.tags <@ $4 :: jsonb
-		ORDER BY
-			nested.created_at
-		FOR UPDATE
-		SKIP LOCKED
-		LIMIT
-			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+VALUES (
+	$1,
+	$2,
+	$3,
+	now(),
+	now()
+)
+ON CONFLICT (name)
+	DO UPDATE SET
+		display_name = $2,
+		site_permissions = $3,
+		updated_at = now()
+RETURNING *
 `
 
-type AcquireProvisionerJobParams struct {
-	StartedAt sql.NullTime      `db:"started_at" json:"started_at"`
-	WorkerID  uuid.NullUUID     `db:"worker_id" json:"worker_id"`
-	Types     []ProvisionerType `db:"types" json:"types"`
-	Tags      json.RawMessage   `db:"tags" json:"tags"`
+type UpsertCustomRoleParams struct {
+	Name            string          `db:"name" json:"name"`
+	DisplayName     string          `db:"display_name" json:"display_name"`
+	SitePermissions json.RawMessage `db:"site_permissions" json:"site_permissions"`
 }
 
-// Acquires the lock for a single job that isn't started, completed,
-// canceled, and that matches an array of provisioner types.
-//
-// SKIP LOCKED is used to jump over locked rows. This prevents
-// multiple provisioners from acquiring the same jobs. See:
-// https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
-func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvisionerJobParams) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, acquireProvisionerJob,
-		arg.StartedAt,
-		arg.WorkerID,
-		pq.Array(arg.Types),
-		arg.Tags,
-	)
-	var i ProvisionerJob
+func (q *sqlQuerier) UpsertCustomRole(ctx context.Context, arg UpsertCustomRoleParams) (CustomRole, error) {
+	row := q.db.QueryRowContext(ctx, upsertCustomRole, arg.Name, arg.DisplayName, arg.SitePermissions)
+	var i CustomRole
 	err := row.Scan(
-		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.SitePermissions,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
-		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
-		&i.JobStatus,
 	)
 	return i, err
 }
 
-const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
+const deleteSessionRecordingByID = `-- name: DeleteSessionRecordingByID :exec
+DELETE FROM
+	session_recordings
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteSessionRecordingByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSessionRecordingByID, id)
+	return err
+}
+
+const getExpiredSessionRecordings = `-- name: GetExpiredSessionRecordings :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, organization_id, template_id, workspace_id, agent_id, user_id, started_at, ended_at, object_key, size_bytes, expires_at
 FROM
-	provisioner_jobs
+	session_recordings
 WHERE
-	updated_at < $1
-	AND started_at IS NOT NULL
-	AND completed_at IS NULL
+	expires_at <= $1 :: timestamptz
+ORDER BY
+	expires_at ASC
+LIMIT
+	$2 :: int
 `
 
-func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getHungProvisionerJobs, updatedAt)
+type GetExpiredSessionRecordingsParams struct {
+	Now      time.Time `db:"now" json:"now"`
+	LimitOpt int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// Returns the recordings past their retention, oldest first. Their objects must
+// be deleted from the store before the rows are deleted.
+func (q *sqlQuerier) GetExpiredSessionRecordings(ctx context.Context, arg GetExpiredSessionRecordingsParams) ([]SessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredSessionRecordings, arg.Now, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJob
+	var items []SessionRecording
 	for rows.Next() {
-		var i ProvisionerJob
+		var i SessionRecording
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
 			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
-			&i.JobStatus,
+			&i.TemplateID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.UserID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ObjectKey,
+			&i.SizeBytes,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3656,80 +7810,66 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 	return items, nil
 }
 
-const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
+const getSessionRecordingByID = `-- name: GetSessionRecordingByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, organization_id, template_id, workspace_id, agent_id, user_id, started_at, ended_at, object_key, size_bytes, expires_at
 FROM
-	provisioner_jobs
+	session_recordings
 WHERE
 	id = $1
 `
 
-func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, getProvisionerJobByID, id)
-	var i ProvisionerJob
+func (q *sqlQuerier) GetSessionRecordingByID(ctx context.Context, id uuid.UUID) (SessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, getSessionRecordingByID, id)
+	var i SessionRecording
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
 		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
-		&i.JobStatus,
+		&i.TemplateID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ObjectKey,
+		&i.SizeBytes,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
-const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
+const getSessionRecordingsByWorkspaceID = `-- name: GetSessionRecordingsByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+	id, organization_id, template_id, workspace_id, agent_id, user_id, started_at, ended_at, object_key, size_bytes, expires_at
 FROM
-	provisioner_jobs
+	session_recordings
 WHERE
-	id = ANY($1 :: uuid [ ])
+	workspace_id = $1
+ORDER BY
+	started_at DESC, id DESC
 `
-
-func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDs, pq.Array(ids))
+
+func (q *sqlQuerier) GetSessionRecordingsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]SessionRecording, error) {
+	rows, err := q.db.QueryContext(ctx, getSessionRecordingsByWorkspaceID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ProvisionerJob
+	var items []SessionRecording
 	for rows.Next() {
-		var i ProvisionerJob
+		var i SessionRecording
 		if err := rows.Scan(
 			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
 			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
-			&i.JobStatus,
+			&i.TemplateID,
+			&i.WorkspaceID,
+			&i.AgentID,
+			&i.UserID,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ObjectKey,
+			&i.SizeBytes,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3744,767 +7884,824 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 	return items, nil
 }
 
-const getProvisionerJobsByIDsWithQueuePosition = `-- name: GetProvisionerJobsByIDsWithQueuePosition :many
-WITH unstarted_jobs AS (
-    SELECT
-        id, created_at
-    FROM
-        provisioner_jobs
-    WHERE
-        started_at IS NULL
-),
-queue_position AS (
-    SELECT
-        id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
-    FROM
-        unstarted_jobs
-),
-queue_size AS (
-	SELECT COUNT(*) as count FROM unstarted_jobs
-)
-SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.job_status,
-    COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
-FROM
-	provisioner_jobs pj
-LEFT JOIN
-	queue_position qp ON qp.id = pj.id
-LEFT JOIN
-	queue_size qs ON TRUE
-WHERE
-	pj.id = ANY($1 :: uuid [ ])
+const insertSessionRecording = `-- name: InsertSessionRecording :one
+INSERT INTO
+	session_recordings (
+		id,
+		organization_id,
+		template_id,
+		workspace_id,
+		agent_id,
+		user_id,
+		started_at,
+		ended_at,
+		object_key,
+		size_bytes,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, organization_id, template_id, workspace_id, agent_id, user_id, started_at, ended_at, object_key, size_bytes, expires_at
+`
+
+type InsertSessionRecordingParams struct {
+	ID             uuid.UUID    `db:"id" json:"id"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.UUID    `db:"template_id" json:"template_id"`
+	WorkspaceID    uuid.UUID    `db:"workspace_id" json:"workspace_id"`
+	AgentID        uuid.UUID    `db:"agent_id" json:"agent_id"`
+	UserID         uuid.UUID    `db:"user_id" json:"user_id"`
+	StartedAt      time.Time    `db:"started_at" json:"started_at"`
+	EndedAt        time.Time    `db:"ended_at" json:"ended_at"`
+	ObjectKey      string       `db:"object_key" json:"object_key"`
+	SizeBytes      int64        `db:"size_bytes" json:"size_bytes"`
+	ExpiresAt      sql.NullTime `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertSessionRecording(ctx context.Context, arg InsertSessionRecordingParams) (SessionRecording, error) {
+	row := q.db.QueryRowContext(ctx, insertSessionRecording,
+		arg.ID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.WorkspaceID,
+		arg.AgentID,
+		arg.UserID,
+		arg.StartedAt,
+		arg.EndedAt,
+		arg.ObjectKey,
+		arg.SizeBytes,
+		arg.ExpiresAt,
+	)
+	var i SessionRecording
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.WorkspaceID,
+		&i.AgentID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ObjectKey,
+		&i.SizeBytes,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getAppSecurityKey = `-- name: GetAppSecurityKey :one
+SELECT value FROM site_configs WHERE key = 'app_signing_key'
+`
+
+func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getApplicationName = `-- name: GetApplicationName :one
+SELECT value FROM site_configs WHERE key = 'application_name'
+`
+
+func (q *sqlQuerier) GetApplicationName(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getApplicationName)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getDERPMeshKey = `-- name: GetDERPMeshKey :one
+SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
+`
+
+func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
+	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
+`
+
+type GetDefaultProxyConfigRow struct {
+	DisplayName string `db:"display_name" json:"display_name"`
+	IconUrl     string `db:"icon_url" json:"icon_url"`
+}
+
+func (q *sqlQuerier) GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultProxyConfig)
+	var i GetDefaultProxyConfigRow
+	err := row.Scan(&i.DisplayName, &i.IconUrl)
+	return i, err
+}
+
+const getDeploymentID = `-- name: GetDeploymentID :one
+SELECT value FROM site_configs WHERE key = 'deployment_id'
+`
+
+func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentID)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getHealthSettings = `-- name: GetHealthSettings :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'health_settings'), '{}') :: text AS health_settings
+`
+
+func (q *sqlQuerier) GetHealthSettings(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getHealthSettings)
+	var health_settings string
+	err := row.Scan(&health_settings)
+	return health_settings, err
+}
+
+const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
+SELECT value FROM site_configs WHERE key = 'last_update_check'
+`
+
+func (q *sqlQuerier) GetLastUpdateCheck(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLastUpdateCheck)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getLicenseGracePeriodStartedAt = `-- name: GetLicenseGracePeriodStartedAt :one
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'license_grace_period_started_at'), '') :: text AS license_grace_period_started_at
+`
+
+// Returns an empty string unless every license has expired and the read-only
+// grace period has started.
+func (q *sqlQuerier) GetLicenseGracePeriodStartedAt(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLicenseGracePeriodStartedAt)
+	var license_grace_period_started_at string
+	err := row.Scan(&license_grace_period_started_at)
+	return license_grace_period_started_at, err
+}
+
+const getLogoURL = `-- name: GetLogoURL :one
+SELECT value FROM site_configs WHERE key = 'logo_url'
+`
+
+func (q *sqlQuerier) GetLogoURL(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLogoURL)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getOAuthSigningKey = `-- name: GetOAuthSigningKey :one
+SELECT value FROM site_configs WHERE key = 'oauth_signing_key'
+`
+
+func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getOAuthSigningKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getServiceBanner = `-- name: GetServiceBanner :one
+SELECT value FROM site_configs WHERE key = 'service_banner'
+`
+
+func (q *sqlQuerier) GetServiceBanner(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getServiceBanner)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const insertDERPMeshKey = `-- name: InsertDERPMeshKey :exec
+INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
+`
+
+func (q *sqlQuerier) InsertDERPMeshKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, insertDERPMeshKey, value)
+	return err
+}
+
+const insertDeploymentID = `-- name: InsertDeploymentID :exec
+INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1)
+`
+
+func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, insertDeploymentID, value)
+	return err
+}
+
+const upsertAppSecurityKey = `-- name: UpsertAppSecurityKey :exec
+INSERT INTO site_configs (key, value) VALUES ('app_signing_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'app_signing_key'
+`
+
+func (q *sqlQuerier) UpsertAppSecurityKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertAppSecurityKey, value)
+	return err
+}
+
+const upsertApplicationName = `-- name: UpsertApplicationName :exec
+INSERT INTO site_configs (key, value) VALUES ('application_name', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'application_name'
+`
+
+func (q *sqlQuerier) UpsertApplicationName(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertApplicationName, value)
+	return err
+}
+
+const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
+INSERT INTO site_configs (key, value)
+VALUES
+    ('default_proxy_display_name', $1 :: text),
+    ('default_proxy_icon_url', $2 :: text)
+ON CONFLICT
+    (key)
+DO UPDATE SET value = EXCLUDED.value WHERE site_configs.key = EXCLUDED.key
+`
+
+type UpsertDefaultProxyParams struct {
+	DisplayName string `db:"display_name" json:"display_name"`
+	IconUrl     string `db:"icon_url" json:"icon_url"`
+}
+
+// The default proxy is implied and not actually stored in the database.
+// So we need to store it's configuration here for display purposes.
+// The functional values are immutable and controlled implicitly.
+func (q *sqlQuerier) UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertDefaultProxy, arg.DisplayName, arg.IconUrl)
+	return err
+}
+
+const upsertHealthSettings = `-- name: UpsertHealthSettings :exec
+INSERT INTO site_configs (key, value) VALUES ('health_settings', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'health_settings'
+`
+
+func (q *sqlQuerier) UpsertHealthSettings(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertHealthSettings, value)
+	return err
+}
+
+const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
+INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
+`
+
+func (q *sqlQuerier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertLastUpdateCheck, value)
+	return err
+}
+
+const upsertLicenseGracePeriodStartedAt = `-- name: UpsertLicenseGracePeriodStartedAt :exec
+INSERT INTO site_configs (key, value) VALUES ('license_grace_period_started_at', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'license_grace_period_started_at'
 `
 
-type GetProvisionerJobsByIDsWithQueuePositionRow struct {
-	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
-	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
-	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+func (q *sqlQuerier) UpsertLicenseGracePeriodStartedAt(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertLicenseGracePeriodStartedAt, value)
+	return err
 }
 
-func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsByIDsWithQueuePosition, pq.Array(ids))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetProvisionerJobsByIDsWithQueuePositionRow
-	for rows.Next() {
-		var i GetProvisionerJobsByIDsWithQueuePositionRow
-		if err := rows.Scan(
-			&i.ProvisionerJob.ID,
-			&i.ProvisionerJob.CreatedAt,
-			&i.ProvisionerJob.UpdatedAt,
-			&i.ProvisionerJob.StartedAt,
-			&i.ProvisionerJob.CanceledAt,
-			&i.ProvisionerJob.CompletedAt,
-			&i.ProvisionerJob.Error,
-			&i.ProvisionerJob.OrganizationID,
-			&i.ProvisionerJob.InitiatorID,
-			&i.ProvisionerJob.Provisioner,
-			&i.ProvisionerJob.StorageMethod,
-			&i.ProvisionerJob.Type,
-			&i.ProvisionerJob.Input,
-			&i.ProvisionerJob.WorkerID,
-			&i.ProvisionerJob.FileID,
-			&i.ProvisionerJob.Tags,
-			&i.ProvisionerJob.ErrorCode,
-			&i.ProvisionerJob.TraceMetadata,
-			&i.ProvisionerJob.JobStatus,
-			&i.QueuePosition,
-			&i.QueueSize,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const upsertLogoURL = `-- name: UpsertLogoURL :exec
+INSERT INTO site_configs (key, value) VALUES ('logo_url', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'logo_url'
+`
+
+func (q *sqlQuerier) UpsertLogoURL(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertLogoURL, value)
+	return err
 }
 
-const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status FROM provisioner_jobs WHERE created_at > $1
+const upsertOAuthSigningKey = `-- name: UpsertOAuthSigningKey :exec
+INSERT INTO site_configs (key, value) VALUES ('oauth_signing_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'oauth_signing_key'
 `
 
-func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
-	rows, err := q.db.QueryContext(ctx, getProvisionerJobsCreatedAfter, createdAt)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ProvisionerJob
-	for rows.Next() {
-		var i ProvisionerJob
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.StartedAt,
-			&i.CanceledAt,
-			&i.CompletedAt,
-			&i.Error,
-			&i.OrganizationID,
-			&i.InitiatorID,
-			&i.Provisioner,
-			&i.StorageMethod,
-			&i.Type,
-			&i.Input,
-			&i.WorkerID,
-			&i.FileID,
-			&i.Tags,
-			&i.ErrorCode,
-			&i.TraceMetadata,
-			&i.JobStatus,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertOAuthSigningKey, value)
+	return err
 }
 
-const insertProvisionerJob = `-- name: InsertProvisionerJob :one
-INSERT INTO
-	provisioner_jobs (
-		id,
-		created_at,
-		updated_at,
-		organization_id,
-		initiator_id,
-		provisioner,
-		storage_method,
-		file_id,
-		"type",
-		"input",
-		tags,
-		trace_metadata
-	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, job_status
+const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
+INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
 `
 
-type InsertProvisionerJobParams struct {
-	ID             uuid.UUID                `db:"id" json:"id"`
-	CreatedAt      time.Time                `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time                `db:"updated_at" json:"updated_at"`
-	OrganizationID uuid.UUID                `db:"organization_id" json:"organization_id"`
-	InitiatorID    uuid.UUID                `db:"initiator_id" json:"initiator_id"`
-	Provisioner    ProvisionerType          `db:"provisioner" json:"provisioner"`
-	StorageMethod  ProvisionerStorageMethod `db:"storage_method" json:"storage_method"`
-	FileID         uuid.UUID                `db:"file_id" json:"file_id"`
-	Type           ProvisionerJobType       `db:"type" json:"type"`
-	Input          json.RawMessage          `db:"input" json:"input"`
-	Tags           StringMap                `db:"tags" json:"tags"`
-	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
+	return err
 }
 
-func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error) {
-	row := q.db.QueryRowContext(ctx, insertProvisionerJob,
-		arg.ID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.OrganizationID,
-		arg.InitiatorID,
-		arg.Provisioner,
-		arg.StorageMethod,
-		arg.FileID,
-		arg.Type,
-		arg.Input,
-		arg.Tags,
-		arg.TraceMetadata,
-	)
-	var i ProvisionerJob
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.StartedAt,
-		&i.CanceledAt,
-		&i.CompletedAt,
-		&i.Error,
-		&i.OrganizationID,
-		&i.InitiatorID,
-		&i.Provisioner,
-		&i.StorageMethod,
-		&i.Type,
-		&i.Input,
-		&i.WorkerID,
-		&i.FileID,
-		&i.Tags,
-		&i.ErrorCode,
-		&i.TraceMetadata,
-		&i.JobStatus,
-	)
-	return i, err
+const cleanTailnetCoordinators = `-- name: CleanTailnetCoordinators :exec
+DELETE
+FROM tailnet_coordinators
+WHERE heartbeat_at < now() - INTERVAL '24 HOURS'
+`
+
+func (q *sqlQuerier) CleanTailnetCoordinators(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, cleanTailnetCoordinators)
+	return err
 }
 
-const updateProvisionerJobByID = `-- name: UpdateProvisionerJobByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	updated_at = $2
-WHERE
-	id = $1
+const cleanTailnetLostPeers = `-- name: CleanTailnetLostPeers :exec
+DELETE
+FROM tailnet_peers
+WHERE updated_at < now() - INTERVAL '24 HOURS' AND status = 'lost'::tailnet_status
 `
 
-type UpdateProvisionerJobByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+func (q *sqlQuerier) CleanTailnetLostPeers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, cleanTailnetLostPeers)
+	return err
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobByID, arg.ID, arg.UpdatedAt)
+const cleanTailnetTunnels = `-- name: CleanTailnetTunnels :exec
+DELETE FROM tailnet_tunnels
+WHERE updated_at < now() - INTERVAL '24 HOURS' AND
+      NOT EXISTS (
+        SELECT 1 FROM tailnet_peers
+        WHERE id = tailnet_tunnels.src_id AND coordinator_id = tailnet_tunnels.coordinator_id
+      )
+`
+
+func (q *sqlQuerier) CleanTailnetTunnels(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, cleanTailnetTunnels)
 	return err
 }
 
-const updateProvisionerJobWithCancelByID = `-- name: UpdateProvisionerJobWithCancelByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	canceled_at = $2,
-	completed_at = $3
-WHERE
-	id = $1
+const deleteAllTailnetClientSubscriptions = `-- name: DeleteAllTailnetClientSubscriptions :exec
+DELETE
+FROM tailnet_client_subscriptions
+WHERE client_id = $1 and coordinator_id = $2
 `
 
-type UpdateProvisionerJobWithCancelByIDParams struct {
-	ID          uuid.UUID    `db:"id" json:"id"`
-	CanceledAt  sql.NullTime `db:"canceled_at" json:"canceled_at"`
-	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+type DeleteAllTailnetClientSubscriptionsParams struct {
+	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCancelByID, arg.ID, arg.CanceledAt, arg.CompletedAt)
+func (q *sqlQuerier) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg DeleteAllTailnetClientSubscriptionsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAllTailnetClientSubscriptions, arg.ClientID, arg.CoordinatorID)
 	return err
 }
 
-const updateProvisionerJobWithCompleteByID = `-- name: UpdateProvisionerJobWithCompleteByID :exec
-UPDATE
-	provisioner_jobs
-SET
-	updated_at = $2,
-	completed_at = $3,
-	error = $4,
-	error_code = $5
-WHERE
-	id = $1
+const deleteAllTailnetTunnels = `-- name: DeleteAllTailnetTunnels :exec
+DELETE
+FROM tailnet_tunnels
+WHERE coordinator_id = $1 and src_id = $2
 `
 
-type UpdateProvisionerJobWithCompleteByIDParams struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
-	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
-	Error       sql.NullString `db:"error" json:"error"`
-	ErrorCode   sql.NullString `db:"error_code" json:"error_code"`
+type DeleteAllTailnetTunnelsParams struct {
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
 }
 
-func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithCompleteByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.CompletedAt,
-		arg.Error,
-		arg.ErrorCode,
-	)
+func (q *sqlQuerier) DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAllTailnetTunnels, arg.CoordinatorID, arg.SrcID)
 	return err
 }
 
-const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
-FROM
-	workspace_proxies
-WHERE
-	deleted = false
+const deleteCoordinator = `-- name: DeleteCoordinator :exec
+DELETE
+FROM tailnet_coordinators
+WHERE id = $1
 `
 
-func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceProxies)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceProxy
-	for rows.Next() {
-		var i WorkspaceProxy
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Url,
-			&i.WildcardHostname,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Deleted,
-			&i.TokenHashedSecret,
-			&i.RegionID,
-			&i.DerpEnabled,
-			&i.DerpOnly,
-			&i.Version,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteCoordinator, id)
+	return err
 }
 
-const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
-FROM
-	workspace_proxies
-WHERE
-	-- Validate that the @hostname has been sanitized and is not empty. This
-	-- doesn't prevent SQL injection (already prevented by using prepared
-	-- queries), but it does prevent carefully crafted hostnames from matching
-	-- when they shouldn't.
-	--
-	-- Periods don't need to be escaped because they're not special characters
-	-- in SQL matches unlike regular expressions.
-	$1 :: text SIMILAR TO '[a-zA-Z0-9._-]+' AND
-	deleted = false AND
-
-	-- Validate that the hostname matches either the wildcard hostname or the
-	-- access URL (ignoring scheme, port and path).
-	(
-		(
-			$2 :: bool = true AND
-			url SIMILAR TO '[^:]*://' || $1 :: text || '([:/]?%)*'
-		) OR
-		(
-			$3 :: bool = true AND
-			$1 :: text LIKE replace(wildcard_hostname, '*', '%')
-		)
-	)
-LIMIT
-	1
+const deleteTailnetAgent = `-- name: DeleteTailnetAgent :one
+DELETE
+FROM tailnet_agents
+WHERE id = $1 and coordinator_id = $2
+RETURNING id, coordinator_id
 `
 
-type GetWorkspaceProxyByHostnameParams struct {
-	Hostname              string `db:"hostname" json:"hostname"`
-	AllowAccessUrl        bool   `db:"allow_access_url" json:"allow_access_url"`
-	AllowWildcardHostname bool   `db:"allow_wildcard_hostname" json:"allow_wildcard_hostname"`
+type DeleteTailnetAgentParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
 }
 
-// Finds a workspace proxy that has an access URL or app hostname that matches
-// the provided hostname. This is to check if a hostname matches any workspace
-// proxy.
-//
-// The hostname must be sanitized to only contain [a-zA-Z0-9.-] before calling
-// this query. The scheme, port and path should be stripped.
-func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWorkspaceProxyByHostnameParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByHostname, arg.Hostname, arg.AllowAccessUrl, arg.AllowWildcardHostname)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
+type DeleteTailnetAgentRow struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetAgent, arg.ID, arg.CoordinatorID)
+	var i DeleteTailnetAgentRow
+	err := row.Scan(&i.ID, &i.CoordinatorID)
 	return i, err
 }
 
-const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
-FROM
-	workspace_proxies
-WHERE
-	id = $1
-LIMIT
-	1
+const deleteTailnetClient = `-- name: DeleteTailnetClient :one
+DELETE
+FROM tailnet_clients
+WHERE id = $1 and coordinator_id = $2
+RETURNING id, coordinator_id
 `
 
-func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByID, id)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
+type DeleteTailnetClientParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+type DeleteTailnetClientRow struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID)
+	var i DeleteTailnetClientRow
+	err := row.Scan(&i.ID, &i.CoordinatorID)
 	return i, err
 }
 
-const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
-SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
-FROM
-	workspace_proxies
-WHERE
-	name = $1
-	AND deleted = false
-LIMIT
-	1
+const deleteTailnetClientSubscription = `-- name: DeleteTailnetClientSubscription :exec
+DELETE
+FROM tailnet_client_subscriptions
+WHERE client_id = $1 and agent_id = $2 and coordinator_id = $3
 `
 
-func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceProxyByName, name)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
-	return i, err
+type DeleteTailnetClientSubscriptionParams struct {
+	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTailnetClientSubscription, arg.ClientID, arg.AgentID, arg.CoordinatorID)
+	return err
 }
 
-const insertWorkspaceProxy = `-- name: InsertWorkspaceProxy :one
-INSERT INTO
-	workspace_proxies (
-		id,
-		url,
-		wildcard_hostname,
-		name,
-		display_name,
-		icon,
-		derp_enabled,
-		derp_only,
-		token_hashed_secret,
-		created_at,
-		updated_at,
-		deleted
-	)
-VALUES
-	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+const deleteTailnetPeer = `-- name: DeleteTailnetPeer :one
+DELETE
+FROM tailnet_peers
+WHERE id = $1 and coordinator_id = $2
+RETURNING id, coordinator_id
 `
 
-type InsertWorkspaceProxyParams struct {
-	ID                uuid.UUID `db:"id" json:"id"`
-	Name              string    `db:"name" json:"name"`
-	DisplayName       string    `db:"display_name" json:"display_name"`
-	Icon              string    `db:"icon" json:"icon"`
-	DerpEnabled       bool      `db:"derp_enabled" json:"derp_enabled"`
-	DerpOnly          bool      `db:"derp_only" json:"derp_only"`
-	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
-	CreatedAt         time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+type DeleteTailnetPeerParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceProxy,
-		arg.ID,
-		arg.Name,
-		arg.DisplayName,
-		arg.Icon,
-		arg.DerpEnabled,
-		arg.DerpOnly,
-		arg.TokenHashedSecret,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-	)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
+type DeleteTailnetPeerRow struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetPeer, arg.ID, arg.CoordinatorID)
+	var i DeleteTailnetPeerRow
+	err := row.Scan(&i.ID, &i.CoordinatorID)
 	return i, err
 }
 
-const registerWorkspaceProxy = `-- name: RegisterWorkspaceProxy :one
-UPDATE
-	workspace_proxies
-SET
-	url = $1 :: text,
-	wildcard_hostname = $2 :: text,
-	derp_enabled = $3 :: boolean,
-	derp_only = $4 :: boolean,
-	version = $5 :: text,
-	updated_at = Now()
-WHERE
-	id = $6
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+const deleteTailnetTunnel = `-- name: DeleteTailnetTunnel :one
+DELETE
+FROM tailnet_tunnels
+WHERE coordinator_id = $1 and src_id = $2 and dst_id = $3
+RETURNING coordinator_id, src_id, dst_id
 `
 
-type RegisterWorkspaceProxyParams struct {
-	Url              string    `db:"url" json:"url"`
-	WildcardHostname string    `db:"wildcard_hostname" json:"wildcard_hostname"`
-	DerpEnabled      bool      `db:"derp_enabled" json:"derp_enabled"`
-	DerpOnly         bool      `db:"derp_only" json:"derp_only"`
-	Version          string    `db:"version" json:"version"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type DeleteTailnetTunnelParams struct {
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
+	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
 }
 
-func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, registerWorkspaceProxy,
-		arg.Url,
-		arg.WildcardHostname,
-		arg.DerpEnabled,
-		arg.DerpOnly,
-		arg.Version,
-		arg.ID,
-	)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
+type DeleteTailnetTunnelRow struct {
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
+	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
+}
+
+func (q *sqlQuerier) DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error) {
+	row := q.db.QueryRowContext(ctx, deleteTailnetTunnel, arg.CoordinatorID, arg.SrcID, arg.DstID)
+	var i DeleteTailnetTunnelRow
+	err := row.Scan(&i.CoordinatorID, &i.SrcID, &i.DstID)
 	return i, err
 }
 
-const updateWorkspaceProxy = `-- name: UpdateWorkspaceProxy :one
-UPDATE
-	workspace_proxies
-SET
-	-- These values should always be provided.
-	name = $1,
-	display_name = $2,
-	icon = $3,
-	-- Only update the token if a new one is provided.
-	-- So this is an optional field.
-	token_hashed_secret = CASE
-		WHEN length($4 :: bytea) > 0  THEN $4 :: bytea
-		ELSE  workspace_proxies.token_hashed_secret
-	END,
-	-- Always update this timestamp.
-	updated_at = Now()
-WHERE
-	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, version
+const getAllTailnetAgents = `-- name: GetAllTailnetAgents :many
+SELECT id, coordinator_id, updated_at, node
+FROM tailnet_agents
 `
 
-type UpdateWorkspaceProxyParams struct {
-	Name              string    `db:"name" json:"name"`
-	DisplayName       string    `db:"display_name" json:"display_name"`
-	Icon              string    `db:"icon" json:"icon"`
-	TokenHashedSecret []byte    `db:"token_hashed_secret" json:"token_hashed_secret"`
-	ID                uuid.UUID `db:"id" json:"id"`
+func (q *sqlQuerier) GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetAgents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetAgent
+	for rows.Next() {
+		var i TailnetAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// This allows editing the properties of a workspace proxy.
-func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error) {
-	row := q.db.QueryRowContext(ctx, updateWorkspaceProxy,
-		arg.Name,
-		arg.DisplayName,
-		arg.Icon,
-		arg.TokenHashedSecret,
-		arg.ID,
-	)
-	var i WorkspaceProxy
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Url,
-		&i.WildcardHostname,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Deleted,
-		&i.TokenHashedSecret,
-		&i.RegionID,
-		&i.DerpEnabled,
-		&i.DerpOnly,
-		&i.Version,
-	)
-	return i, err
-}
+const getAllTailnetCoordinators = `-- name: GetAllTailnetCoordinators :many
 
-const updateWorkspaceProxyDeleted = `-- name: UpdateWorkspaceProxyDeleted :exec
-UPDATE
-	workspace_proxies
-SET
-	updated_at = Now(),
-	deleted = $1
-WHERE
-	id = $2
+SELECT id, heartbeat_at FROM tailnet_coordinators
+`
+
+// For PG Coordinator HTMLDebug
+func (q *sqlQuerier) GetAllTailnetCoordinators(ctx context.Context) ([]TailnetCoordinator, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetCoordinators)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetCoordinator
+	for rows.Next() {
+		var i TailnetCoordinator
+		if err := rows.Scan(&i.ID, &i.HeartbeatAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllTailnetPeers = `-- name: GetAllTailnetPeers :many
+SELECT id, coordinator_id, updated_at, node, status FROM tailnet_peers
 `
 
-type UpdateWorkspaceProxyDeletedParams struct {
-	Deleted bool      `db:"deleted" json:"deleted"`
-	ID      uuid.UUID `db:"id" json:"id"`
+func (q *sqlQuerier) GetAllTailnetPeers(ctx context.Context) ([]TailnetPeer, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetPeers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetPeer
+	for rows.Next() {
+		var i TailnetPeer
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceProxyDeleted, arg.Deleted, arg.ID)
-	return err
+const getAllTailnetTunnels = `-- name: GetAllTailnetTunnels :many
+SELECT coordinator_id, src_id, dst_id, updated_at FROM tailnet_tunnels
+`
+
+func (q *sqlQuerier) GetAllTailnetTunnels(ctx context.Context) ([]TailnetTunnel, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTailnetTunnels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetTunnel
+	for rows.Next() {
+		var i TailnetTunnel
+		if err := rows.Scan(
+			&i.CoordinatorID,
+			&i.SrcID,
+			&i.DstID,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
-SELECT
-	coalesce(SUM(quota_allowance), 0)::BIGINT
-FROM
-	groups g
-LEFT JOIN group_members gm ON
-	g.id = gm.group_id
-WHERE
-	user_id = $1
-OR
-    g.id = g.organization_id
+const getTailnetAgents = `-- name: GetTailnetAgents :many
+SELECT id, coordinator_id, updated_at, node
+FROM tailnet_agents
+WHERE id = $1
 `
 
-func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetAgents, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetAgent
+	for rows.Next() {
+		var i TailnetAgent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
-WITH latest_builds AS (
-SELECT
-	DISTINCT ON
-	(workspace_id) id,
-	workspace_id,
-	daily_cost
-FROM
-	workspace_builds wb
-ORDER BY
-	workspace_id,
-	created_at DESC
+const getTailnetClientsForAgent = `-- name: GetTailnetClientsForAgent :many
+SELECT id, coordinator_id, updated_at, node
+FROM tailnet_clients
+WHERE id IN (
+	SELECT tailnet_client_subscriptions.client_id
+	FROM tailnet_client_subscriptions
+	WHERE tailnet_client_subscriptions.agent_id = $1
 )
-SELECT
-	coalesce(SUM(daily_cost), 0)::BIGINT
-FROM
-	workspaces
-JOIN latest_builds ON
-	latest_builds.workspace_id = workspaces.id
-WHERE NOT deleted AND workspaces.owner_id = $1
 `
 
-func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+func (q *sqlQuerier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetClientsForAgent, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetClient
+	for rows.Next() {
+		var i TailnetClient
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
-DELETE FROM replicas WHERE updated_at < $1
+const getTailnetPeers = `-- name: GetTailnetPeers :many
+SELECT id, coordinator_id, updated_at, node, status FROM tailnet_peers WHERE id = $1
 `
 
-func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error {
-	_, err := q.db.ExecContext(ctx, deleteReplicasUpdatedBefore, updatedAt)
-	return err
+func (q *sqlQuerier) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetPeers, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TailnetPeer
+	for rows.Next() {
+		var i TailnetPeer
+		if err := rows.Scan(
+			&i.ID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getReplicaByID = `-- name: GetReplicaByID :one
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
+const getTailnetTunnelPeerBindings = `-- name: GetTailnetTunnelPeerBindings :many
+SELECT tailnet_tunnels.dst_id as peer_id, tailnet_peers.coordinator_id, tailnet_peers.updated_at, tailnet_peers.node, tailnet_peers.status
+FROM tailnet_tunnels
+INNER JOIN tailnet_peers ON tailnet_tunnels.dst_id = tailnet_peers.id
+WHERE tailnet_tunnels.src_id = $1
+UNION
+SELECT tailnet_tunnels.src_id as peer_id, tailnet_peers.coordinator_id, tailnet_peers.updated_at, tailnet_peers.node, tailnet_peers.status
+FROM tailnet_tunnels
+INNER JOIN tailnet_peers ON tailnet_tunnels.src_id = tailnet_peers.id
+WHERE tailnet_tunnels.dst_id = $1
 `
 
-func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, getReplicaByID, id)
-	var i Replica
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
-		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
-		&i.Error,
-		&i.Primary,
-	)
-	return i, err
+type GetTailnetTunnelPeerBindingsRow struct {
+	PeerID        uuid.UUID     `db:"peer_id" json:"peer_id"`
+	CoordinatorID uuid.UUID     `db:"coordinator_id" json:"coordinator_id"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+	Node          []byte        `db:"node" json:"node"`
+	Status        TailnetStatus `db:"status" json:"status"`
+}
+
+func (q *sqlQuerier) GetTailnetTunnelPeerBindings(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerBindingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetTunnelPeerBindings, srcID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTailnetTunnelPeerBindingsRow
+	for rows.Next() {
+		var i GetTailnetTunnelPeerBindingsRow
+		if err := rows.Scan(
+			&i.PeerID,
+			&i.CoordinatorID,
+			&i.UpdatedAt,
+			&i.Node,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
+const getTailnetTunnelPeerIDs = `-- name: GetTailnetTunnelPeerIDs :many
+SELECT dst_id as peer_id, coordinator_id, updated_at
+FROM tailnet_tunnels
+WHERE tailnet_tunnels.src_id = $1
+UNION
+SELECT src_id as peer_id, coordinator_id, updated_at
+FROM tailnet_tunnels
+WHERE tailnet_tunnels.dst_id = $1
 `
 
-func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
-	rows, err := q.db.QueryContext(ctx, getReplicasUpdatedAfter, updatedAt)
+type GetTailnetTunnelPeerIDsRow struct {
+	PeerID        uuid.UUID `db:"peer_id" json:"peer_id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTailnetTunnelPeerIDs, srcID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Replica
+	var items []GetTailnetTunnelPeerIDsRow
 	for rows.Next() {
-		var i Replica
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.StartedAt,
-			&i.StoppedAt,
-			&i.UpdatedAt,
-			&i.Hostname,
-			&i.RegionID,
-			&i.RelayAddress,
-			&i.DatabaseLatency,
-			&i.Version,
-			&i.Error,
-			&i.Primary,
-		); err != nil {
+		var i GetTailnetTunnelPeerIDsRow
+		if err := rows.Scan(&i.PeerID, &i.CoordinatorID, &i.UpdatedAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -4518,661 +8715,1118 @@ func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time
 	return items, nil
 }
 
-const insertReplica = `-- name: InsertReplica :one
-INSERT INTO replicas (
-    id,
-    created_at,
-    started_at,
-    updated_at,
-    hostname,
-    region_id,
-    relay_address,
-    version,
-    database_latency,
-	"primary"
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+const updateTailnetPeerStatusByCoordinator = `-- name: UpdateTailnetPeerStatusByCoordinator :exec
+UPDATE
+	tailnet_peers
+SET
+	status = $2,
+	updated_at = now() at time zone 'utc'
+WHERE
+	coordinator_id = $1
 `
 
-type InsertReplicaParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	CreatedAt       time.Time `db:"created_at" json:"created_at"`
-	StartedAt       time.Time `db:"started_at" json:"started_at"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	Hostname        string    `db:"hostname" json:"hostname"`
-	RegionID        int32     `db:"region_id" json:"region_id"`
-	RelayAddress    string    `db:"relay_address" json:"relay_address"`
-	Version         string    `db:"version" json:"version"`
-	DatabaseLatency int32     `db:"database_latency" json:"database_latency"`
-	Primary         bool      `db:"primary" json:"primary"`
+type UpdateTailnetPeerStatusByCoordinatorParams struct {
+	CoordinatorID uuid.UUID     `db:"coordinator_id" json:"coordinator_id"`
+	Status        TailnetStatus `db:"status" json:"status"`
 }
 
-func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, insertReplica,
+func (q *sqlQuerier) UpdateTailnetPeerStatusByCoordinator(ctx context.Context, arg UpdateTailnetPeerStatusByCoordinatorParams) error {
+	_, err := q.db.ExecContext(ctx, updateTailnetPeerStatusByCoordinator, arg.CoordinatorID, arg.Status)
+	return err
+}
+
+const upsertTailnetAgent = `-- name: UpsertTailnetAgent :one
+INSERT INTO
+	tailnet_agents (
+	id,
+	coordinator_id,
+	node,
+	updated_at
+)
+VALUES
+	($1, $2, $3, now() at time zone 'utc')
+ON CONFLICT (id, coordinator_id)
+DO UPDATE SET
+	id = $1,
+	coordinator_id = $2,
+	node = $3,
+	updated_at = now() at time zone 'utc'
+RETURNING id, coordinator_id, updated_at, node
+`
+
+type UpsertTailnetAgentParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetAgent, arg.ID, arg.CoordinatorID, arg.Node)
+	var i TailnetAgent
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.UpdatedAt,
+		&i.Node,
+	)
+	return i, err
+}
+
+const upsertTailnetClient = `-- name: UpsertTailnetClient :one
+INSERT INTO
+	tailnet_clients (
+	id,
+	coordinator_id,
+	node,
+	updated_at
+)
+VALUES
+	($1, $2, $3, now() at time zone 'utc')
+ON CONFLICT (id, coordinator_id)
+DO UPDATE SET
+	id = $1,
+	coordinator_id = $2,
+	node = $3,
+	updated_at = now() at time zone 'utc'
+RETURNING id, coordinator_id, updated_at, node
+`
+
+type UpsertTailnetClientParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetClient, arg.ID, arg.CoordinatorID, arg.Node)
+	var i TailnetClient
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.UpdatedAt,
+		&i.Node,
+	)
+	return i, err
+}
+
+const upsertTailnetClientSubscription = `-- name: UpsertTailnetClientSubscription :exec
+INSERT INTO
+	tailnet_client_subscriptions (
+	client_id,
+	coordinator_id,
+	agent_id,
+	updated_at
+)
+VALUES
+	($1, $2, $3, now() at time zone 'utc')
+ON CONFLICT (client_id, coordinator_id, agent_id)
+DO UPDATE SET
+	client_id = $1,
+	coordinator_id = $2,
+	agent_id = $3,
+	updated_at = now() at time zone 'utc'
+`
+
+type UpsertTailnetClientSubscriptionParams struct {
+	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+}
+
+func (q *sqlQuerier) UpsertTailnetClientSubscription(ctx context.Context, arg UpsertTailnetClientSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTailnetClientSubscription, arg.ClientID, arg.CoordinatorID, arg.AgentID)
+	return err
+}
+
+const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
+INSERT INTO
+	tailnet_coordinators (
+	id,
+	heartbeat_at
+)
+VALUES
+	($1, now() at time zone 'utc')
+ON CONFLICT (id)
+DO UPDATE SET
+  id = $1,
+  heartbeat_at = now() at time zone 'utc'
+RETURNING id, heartbeat_at
+`
+
+func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
+	var i TailnetCoordinator
+	err := row.Scan(&i.ID, &i.HeartbeatAt)
+	return i, err
+}
+
+const upsertTailnetPeer = `-- name: UpsertTailnetPeer :one
+INSERT INTO
+	tailnet_peers (
+	id,
+	coordinator_id,
+	node,
+	status,
+	updated_at
+)
+VALUES
+	($1, $2, $3, $4, now() at time zone 'utc')
+ON CONFLICT (id, coordinator_id)
+DO UPDATE SET
+	id = $1,
+	coordinator_id = $2,
+	node = $3,
+	status = $4,
+	updated_at = now() at time zone 'utc'
+RETURNING id, coordinator_id, updated_at, node, status
+`
+
+type UpsertTailnetPeerParams struct {
+	ID            uuid.UUID     `db:"id" json:"id"`
+	CoordinatorID uuid.UUID     `db:"coordinator_id" json:"coordinator_id"`
+	Node          []byte        `db:"node" json:"node"`
+	Status        TailnetStatus `db:"status" json:"status"`
+}
+
+func (q *sqlQuerier) UpsertTailnetPeer(ctx context.Context, arg UpsertTailnetPeerParams) (TailnetPeer, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetPeer,
 		arg.ID,
-		arg.CreatedAt,
-		arg.StartedAt,
-		arg.UpdatedAt,
-		arg.Hostname,
-		arg.RegionID,
-		arg.RelayAddress,
-		arg.Version,
-		arg.DatabaseLatency,
-		arg.Primary,
+		arg.CoordinatorID,
+		arg.Node,
+		arg.Status,
 	)
-	var i Replica
+	var i TailnetPeer
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
+		&i.CoordinatorID,
 		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
-		&i.Error,
-		&i.Primary,
+		&i.Node,
+		&i.Status,
 	)
 	return i, err
 }
 
-const updateReplica = `-- name: UpdateReplica :one
-UPDATE replicas SET
-    updated_at = $2,
-    started_at = $3,
-    stopped_at = $4,
-    relay_address = $5,
-    region_id = $6,
-    hostname = $7,
-    version = $8,
-    error = $9,
-    database_latency = $10,
-	"primary" = $11
-WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+const upsertTailnetTunnel = `-- name: UpsertTailnetTunnel :one
+INSERT INTO
+	tailnet_tunnels (
+	coordinator_id,
+	src_id,
+	dst_id,
+	updated_at
+)
+VALUES
+	($1, $2, $3, now() at time zone 'utc')
+ON CONFLICT (coordinator_id, src_id, dst_id)
+DO UPDATE SET
+	coordinator_id = $1,
+	src_id = $2,
+	dst_id = $3,
+	updated_at = now() at time zone 'utc'
+RETURNING coordinator_id, src_id, dst_id, updated_at
 `
 
-type UpdateReplicaParams struct {
-	ID              uuid.UUID    `db:"id" json:"id"`
-	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
-	StartedAt       time.Time    `db:"started_at" json:"started_at"`
-	StoppedAt       sql.NullTime `db:"stopped_at" json:"stopped_at"`
-	RelayAddress    string       `db:"relay_address" json:"relay_address"`
-	RegionID        int32        `db:"region_id" json:"region_id"`
-	Hostname        string       `db:"hostname" json:"hostname"`
-	Version         string       `db:"version" json:"version"`
-	Error           string       `db:"error" json:"error"`
-	DatabaseLatency int32        `db:"database_latency" json:"database_latency"`
-	Primary         bool         `db:"primary" json:"primary"`
+type UpsertTailnetTunnelParams struct {
+	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
+	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
 }
 
-func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error) {
-	row := q.db.QueryRowContext(ctx, updateReplica,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.StartedAt,
-		arg.StoppedAt,
-		arg.RelayAddress,
-		arg.RegionID,
-		arg.Hostname,
-		arg.Version,
-		arg.Error,
-		arg.DatabaseLatency,
-		arg.Primary,
-	)
-	var i Replica
+func (q *sqlQuerier) UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetTunnelParams) (TailnetTunnel, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetTunnel, arg.CoordinatorID, arg.SrcID, arg.DstID)
+	var i TailnetTunnel
 	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.StartedAt,
-		&i.StoppedAt,
+		&i.CoordinatorID,
+		&i.SrcID,
+		&i.DstID,
 		&i.UpdatedAt,
-		&i.Hostname,
-		&i.RegionID,
-		&i.RelayAddress,
-		&i.DatabaseLatency,
-		&i.Version,
-		&i.Error,
-		&i.Primary,
 	)
 	return i, err
 }
 
-const getAppSecurityKey = `-- name: GetAppSecurityKey :one
-SELECT value FROM site_configs WHERE key = 'app_signing_key'
+const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
+WITH build_times AS (
+SELECT
+	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
+	workspace_builds.transition
+FROM
+	workspace_builds
+JOIN template_versions ON
+	workspace_builds.template_version_id = template_versions.id
+JOIN provisioner_jobs pj ON
+	workspace_builds.job_id = pj.id
+WHERE
+	template_versions.template_id = $1 AND
+		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
+		(pj.started_at > $2) AND
+		(pj.canceled_at IS NULL) AND
+		((pj.error IS NULL) OR (pj.error = ''))
+ORDER BY
+	workspace_builds.created_at DESC
+)
+SELECT
+	-- Postgres offers no clear way to DRY this short of a function or other
+	-- complexities.
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
+	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
+	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
+FROM build_times
 `
 
-func (q *sqlQuerier) GetAppSecurityKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getAppSecurityKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+type GetTemplateAverageBuildTimeParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
 }
 
-const getApplicationName = `-- name: GetApplicationName :one
-SELECT value FROM site_configs WHERE key = 'application_name'
-`
+type GetTemplateAverageBuildTimeRow struct {
+	Start50  float64 `db:"start_50" json:"start_50"`
+	Stop50   float64 `db:"stop_50" json:"stop_50"`
+	Delete50 float64 `db:"delete_50" json:"delete_50"`
+	Start95  float64 `db:"start_95" json:"start_95"`
+	Stop95   float64 `db:"stop_95" json:"stop_95"`
+	Delete95 float64 `db:"delete_95" json:"delete_95"`
+}
 
-func (q *sqlQuerier) GetApplicationName(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getApplicationName)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
+	var i GetTemplateAverageBuildTimeRow
+	err := row.Scan(
+		&i.Start50,
+		&i.Stop50,
+		&i.Delete50,
+		&i.Start95,
+		&i.Stop95,
+		&i.Delete95,
+	)
+	return i, err
 }
 
-const getDERPMeshKey = `-- name: GetDERPMeshKey :one
-SELECT value FROM site_configs WHERE key = 'derp_mesh_key'
+const getTemplateByID = `-- name: GetTemplateByID :one
+SELECT
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, maintenance_window_schedule, maintenance_window_duration, visibility, max_build_duration, workspace_name_pattern, workspace_name_description, max_running_workspaces, port_forwarding_deny_by_default, port_forwarding_allowed_ports, session_recording_enabled, session_recording_retention, build_retry_max_attempts, build_retry_backoff, created_by_avatar_url, created_by_username
+FROM
+	template_with_users
+WHERE
+	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetDERPMeshKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getDERPMeshKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.MaxTTL,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.AutostartBlockDaysOfWeek,
+		&i.RequireActiveVersion,
+		&i.Deprecated,
+		&i.UseMaxTtl,
+		&i.MaintenanceWindowSchedule,
+		&i.MaintenanceWindowDuration,
+		&i.Visibility,
+		&i.MaxBuildDuration,
+		&i.WorkspaceNamePattern,
+		&i.WorkspaceNameDescription,
+		&i.MaxRunningWorkspaces,
+		&i.PortForwardingDenyByDefault,
+		pq.Array(&i.PortForwardingAllowedPorts),
+		&i.SessionRecordingEnabled,
+		&i.SessionRecordingRetention,
+		&i.BuildRetryMaxAttempts,
+		&i.BuildRetryBackoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
+	return i, err
 }
 
-const getDefaultProxyConfig = `-- name: GetDefaultProxyConfig :one
+const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_display_name'), 'Default') :: text AS display_name,
-	COALESCE((SELECT value FROM site_configs WHERE key = 'default_proxy_icon_url'), '/emojis/1f3e1.png') :: text AS icon_url
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, maintenance_window_schedule, maintenance_window_duration, visibility, max_build_duration, workspace_name_pattern, workspace_name_description, max_running_workspaces, port_forwarding_deny_by_default, port_forwarding_allowed_ports, session_recording_enabled, session_recording_retention, build_retry_max_attempts, build_retry_backoff, created_by_avatar_url, created_by_username
+FROM
+	template_with_users AS templates
+WHERE
+	organization_id = $1
+	AND deleted = $2
+	AND LOWER("name") = LOWER($3)
+LIMIT
+	1
 `
 
-type GetDefaultProxyConfigRow struct {
-	DisplayName string `db:"display_name" json:"display_name"`
-	IconUrl     string `db:"icon_url" json:"icon_url"`
+type GetTemplateByOrganizationAndNameParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Deleted        bool      `db:"deleted" json:"deleted"`
+	Name           string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetDefaultProxyConfig(ctx context.Context) (GetDefaultProxyConfigRow, error) {
-	row := q.db.QueryRowContext(ctx, getDefaultProxyConfig)
-	var i GetDefaultProxyConfigRow
-	err := row.Scan(&i.DisplayName, &i.IconUrl)
+func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OrganizationID,
+		&i.Deleted,
+		&i.Name,
+		&i.Provisioner,
+		&i.ActiveVersionID,
+		&i.Description,
+		&i.DefaultTTL,
+		&i.CreatedBy,
+		&i.Icon,
+		&i.UserACL,
+		&i.GroupACL,
+		&i.DisplayName,
+		&i.AllowUserCancelWorkspaceJobs,
+		&i.MaxTTL,
+		&i.AllowUserAutostart,
+		&i.AllowUserAutostop,
+		&i.FailureTTL,
+		&i.TimeTilDormant,
+		&i.TimeTilDormantAutoDelete,
+		&i.AutostopRequirementDaysOfWeek,
+		&i.AutostopRequirementWeeks,
+		&i.AutostartBlockDaysOfWeek,
+		&i.RequireActiveVersion,
+		&i.Deprecated,
+		&i.UseMaxTtl,
+		&i.MaintenanceWindowSchedule,
+		&i.MaintenanceWindowDuration,
+		&i.Visibility,
+		&i.MaxBuildDuration,
+		&i.WorkspaceNamePattern,
+		&i.WorkspaceNameDescription,
+		&i.MaxRunningWorkspaces,
+		&i.PortForwardingDenyByDefault,
+		pq.Array(&i.PortForwardingAllowedPorts),
+		&i.SessionRecordingEnabled,
+		&i.SessionRecordingRetention,
+		&i.BuildRetryMaxAttempts,
+		&i.BuildRetryBackoff,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
+	)
 	return i, err
 }
 
-const getDeploymentID = `-- name: GetDeploymentID :one
-SELECT value FROM site_configs WHERE key = 'deployment_id'
+const getTemplates = `-- name: GetTemplates :many
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, maintenance_window_schedule, maintenance_window_duration, visibility, max_build_duration, workspace_name_pattern, workspace_name_description, max_running_workspaces, port_forwarding_deny_by_default, port_forwarding_allowed_ports, session_recording_enabled, session_recording_retention, build_retry_max_attempts, build_retry_backoff, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+ORDER BY (name, id) ASC
 `
 
-func (q *sqlQuerier) GetDeploymentID(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getDeploymentID)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
+			&i.Name,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
+			&i.CreatedBy,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.MaxTTL,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.AutostartBlockDaysOfWeek,
+			&i.RequireActiveVersion,
+			&i.Deprecated,
+			&i.UseMaxTtl,
+			&i.MaintenanceWindowSchedule,
+			&i.MaintenanceWindowDuration,
+			&i.Visibility,
+			&i.MaxBuildDuration,
+			&i.WorkspaceNamePattern,
+			&i.WorkspaceNameDescription,
+			&i.MaxRunningWorkspaces,
+			&i.PortForwardingDenyByDefault,
+			pq.Array(&i.PortForwardingAllowedPorts),
+			&i.SessionRecordingEnabled,
+			&i.SessionRecordingRetention,
+			&i.BuildRetryMaxAttempts,
+			&i.BuildRetryBackoff,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getHealthSettings = `-- name: GetHealthSettings :one
+const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	COALESCE((SELECT value FROM site_configs WHERE key = 'health_settings'), '{}') :: text AS health_settings
-`
-
-func (q *sqlQuerier) GetHealthSettings(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getHealthSettings)
-	var health_settings string
-	err := row.Scan(&health_settings)
-	return health_settings, err
-}
-
-const getLastUpdateCheck = `-- name: GetLastUpdateCheck :one
-SELECT value FROM site_configs WHERE key = 'last_update_check'
-`
-
-func (q *sqlQuerier) GetLastUpdateCheck(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getLastUpdateCheck)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getLogoURL = `-- name: GetLogoURL :one
-SELECT value FROM site_configs WHERE key = 'logo_url'
-`
-
-func (q *sqlQuerier) GetLogoURL(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getLogoURL)
-	var value string
-	err := row.Scan(&value)
-	return value, err
-}
-
-const getOAuthSigningKey = `-- name: GetOAuthSigningKey :one
-SELECT value FROM site_configs WHERE key = 'oauth_signing_key'
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, maintenance_window_schedule, maintenance_window_duration, visibility, max_build_duration, workspace_name_pattern, workspace_name_description, max_running_workspaces, port_forwarding_deny_by_default, port_forwarding_allowed_ports, session_recording_enabled, session_recording_retention, build_retry_max_attempts, build_retry_backoff, created_by_avatar_url, created_by_username
+FROM
+	template_with_users AS templates
+WHERE
+	-- Optionally include deleted templates
+	templates.deleted = $1
+	-- Filter by organization_id
+	AND CASE
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			organization_id = $2
+		ELSE true
+	END
+	-- Filter by exact name
+	AND CASE
+		WHEN $3 :: text != '' THEN
+			LOWER("name") = LOWER($3)
+		ELSE true
+	END
+	-- Filter by ids
+	AND CASE
+		WHEN array_length($4 :: uuid[], 1) > 0 THEN
+			id = ANY($4)
+		ELSE true
+	END
+	-- Filter by deprecated
+	AND CASE
+		WHEN $5 :: boolean IS NOT NULL THEN
+			CASE
+				WHEN $5 :: boolean THEN
+					deprecated != ''
+				ELSE
+					deprecated = ''
+			END
+		ELSE true
+	END
+  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
+  -- @authorize_filter
+ORDER BY (name, id) ASC
 `
 
-func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getOAuthSigningKey)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+type GetTemplatesWithFilterParams struct {
+	Deleted        bool         `db:"deleted" json:"deleted"`
+	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
+	ExactName      string       `db:"exact_name" json:"exact_name"`
+	IDs            []uuid.UUID  `db:"ids" json:"ids"`
+	Deprecated     sql.NullBool `db:"deprecated" json:"deprecated"`
 }
 
-const getServiceBanner = `-- name: GetServiceBanner :one
-SELECT value FROM site_configs WHERE key = 'service_banner'
-`
-
-func (q *sqlQuerier) GetServiceBanner(ctx context.Context) (string, error) {
-	row := q.db.QueryRowContext(ctx, getServiceBanner)
-	var value string
-	err := row.Scan(&value)
-	return value, err
+func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
+		arg.Deleted,
+		arg.OrganizationID,
+		arg.ExactName,
+		pq.Array(arg.IDs),
+		arg.Deprecated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OrganizationID,
+			&i.Deleted,
+			&i.Name,
+			&i.Provisioner,
+			&i.ActiveVersionID,
+			&i.Description,
+			&i.DefaultTTL,
+			&i.CreatedBy,
+			&i.Icon,
+			&i.UserACL,
+			&i.GroupACL,
+			&i.DisplayName,
+			&i.AllowUserCancelWorkspaceJobs,
+			&i.MaxTTL,
+			&i.AllowUserAutostart,
+			&i.AllowUserAutostop,
+			&i.FailureTTL,
+			&i.TimeTilDormant,
+			&i.TimeTilDormantAutoDelete,
+			&i.AutostopRequirementDaysOfWeek,
+			&i.AutostopRequirementWeeks,
+			&i.AutostartBlockDaysOfWeek,
+			&i.RequireActiveVersion,
+			&i.Deprecated,
+			&i.UseMaxTtl,
+			&i.MaintenanceWindowSchedule,
+			&i.MaintenanceWindowDuration,
+			&i.Visibility,
+			&i.MaxBuildDuration,
+			&i.WorkspaceNamePattern,
+			&i.WorkspaceNameDescription,
+			&i.MaxRunningWorkspaces,
+			&i.PortForwardingDenyByDefault,
+			pq.Array(&i.PortForwardingAllowedPorts),
+			&i.SessionRecordingEnabled,
+			&i.SessionRecordingRetention,
+			&i.BuildRetryMaxAttempts,
+			&i.BuildRetryBackoff,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const insertDERPMeshKey = `-- name: InsertDERPMeshKey :exec
-INSERT INTO site_configs (key, value) VALUES ('derp_mesh_key', $1)
+const insertTemplate = `-- name: InsertTemplate :exec
+INSERT INTO
+	templates (
+		id,
+		created_at,
+		updated_at,
+		organization_id,
+		"name",
+		provisioner,
+		active_version_id,
+		description,
+		created_by,
+		icon,
+		user_acl,
+		group_acl,
+		display_name,
+		allow_user_cancel_workspace_jobs,
+		visibility
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 `
 
-func (q *sqlQuerier) InsertDERPMeshKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, insertDERPMeshKey, value)
-	return err
+type InsertTemplateParams struct {
+	ID                           uuid.UUID          `db:"id" json:"id"`
+	CreatedAt                    time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt                    time.Time          `db:"updated_at" json:"updated_at"`
+	OrganizationID               uuid.UUID          `db:"organization_id" json:"organization_id"`
+	Name                         string             `db:"name" json:"name"`
+	Provisioner                  ProvisionerType    `db:"provisioner" json:"provisioner"`
+	ActiveVersionID              uuid.UUID          `db:"active_version_id" json:"active_version_id"`
+	Description                  string             `db:"description" json:"description"`
+	CreatedBy                    uuid.UUID          `db:"created_by" json:"created_by"`
+	Icon                         string             `db:"icon" json:"icon"`
+	UserACL                      TemplateACL        `db:"user_acl" json:"user_acl"`
+	GroupACL                     TemplateACL        `db:"group_acl" json:"group_acl"`
+	DisplayName                  string             `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool               `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	Visibility                   TemplateVisibility `db:"visibility" json:"visibility"`
 }
 
-const insertDeploymentID = `-- name: InsertDeploymentID :exec
-INSERT INTO site_configs (key, value) VALUES ('deployment_id', $1)
-`
-
-func (q *sqlQuerier) InsertDeploymentID(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, insertDeploymentID, value)
+func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplate,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Provisioner,
+		arg.ActiveVersionID,
+		arg.Description,
+		arg.CreatedBy,
+		arg.Icon,
+		arg.UserACL,
+		arg.GroupACL,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+		arg.Visibility,
+	)
 	return err
 }
 
-const upsertAppSecurityKey = `-- name: UpsertAppSecurityKey :exec
-INSERT INTO site_configs (key, value) VALUES ('app_signing_key', $1)
-ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'app_signing_key'
+const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
+UPDATE
+	templates
+SET
+	group_acl = $1,
+	user_acl = $2
+WHERE
+	id = $3
 `
 
-func (q *sqlQuerier) UpsertAppSecurityKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertAppSecurityKey, value)
-	return err
+type UpdateTemplateACLByIDParams struct {
+	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
+	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
+	ID       uuid.UUID   `db:"id" json:"id"`
 }
 
-const upsertApplicationName = `-- name: UpsertApplicationName :exec
-INSERT INTO site_configs (key, value) VALUES ('application_name', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'application_name'
-`
-
-func (q *sqlQuerier) UpsertApplicationName(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertApplicationName, value)
+func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
 	return err
 }
 
-const upsertDefaultProxy = `-- name: UpsertDefaultProxy :exec
-INSERT INTO site_configs (key, value)
-VALUES
-    ('default_proxy_display_name', $1 :: text),
-    ('default_proxy_icon_url', $2 :: text)
-ON CONFLICT
-    (key)
-DO UPDATE SET value = EXCLUDED.value WHERE site_configs.key = EXCLUDED.key
+const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
+UPDATE
+	templates
+SET
+	require_active_version = $2,
+	deprecated = $3
+WHERE
+	id = $1
 `
 
-type UpsertDefaultProxyParams struct {
-	DisplayName string `db:"display_name" json:"display_name"`
-	IconUrl     string `db:"icon_url" json:"icon_url"`
+type UpdateTemplateAccessControlByIDParams struct {
+	ID                   uuid.UUID `db:"id" json:"id"`
+	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
+	Deprecated           string    `db:"deprecated" json:"deprecated"`
 }
 
-// The default proxy is implied and not actually stored in the database.
-// So we need to store it's configuration here for display purposes.
-// The functional values are immutable and controlled implicitly.
-func (q *sqlQuerier) UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error {
-	_, err := q.db.ExecContext(ctx, upsertDefaultProxy, arg.DisplayName, arg.IconUrl)
+func (q *sqlQuerier) UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID, arg.ID, arg.RequireActiveVersion, arg.Deprecated)
 	return err
 }
 
-const upsertHealthSettings = `-- name: UpsertHealthSettings :exec
-INSERT INTO site_configs (key, value) VALUES ('health_settings', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'health_settings'
+const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
+UPDATE
+	templates
+SET
+	active_version_id = $2,
+	updated_at = $3
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) UpsertHealthSettings(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertHealthSettings, value)
-	return err
+type UpdateTemplateActiveVersionByIDParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
-const upsertLastUpdateCheck = `-- name: UpsertLastUpdateCheck :exec
-INSERT INTO site_configs (key, value) VALUES ('last_update_check', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update_check'
-`
-
-func (q *sqlQuerier) UpsertLastUpdateCheck(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertLastUpdateCheck, value)
+func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
 	return err
 }
 
-const upsertLogoURL = `-- name: UpsertLogoURL :exec
-INSERT INTO site_configs (key, value) VALUES ('logo_url', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'logo_url'
+const updateTemplateBuildRetryPolicyByID = `-- name: UpdateTemplateBuildRetryPolicyByID :exec
+UPDATE
+	templates
+SET
+	build_retry_max_attempts = $2,
+	build_retry_backoff = $3,
+	updated_at = $4
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) UpsertLogoURL(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertLogoURL, value)
-	return err
+type UpdateTemplateBuildRetryPolicyByIDParams struct {
+	ID                    uuid.UUID `db:"id" json:"id"`
+	BuildRetryMaxAttempts int32     `db:"build_retry_max_attempts" json:"build_retry_max_attempts"`
+	BuildRetryBackoff     int64     `db:"build_retry_backoff" json:"build_retry_backoff"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
 }
 
-const upsertOAuthSigningKey = `-- name: UpsertOAuthSigningKey :exec
-INSERT INTO site_configs (key, value) VALUES ('oauth_signing_key', $1)
-ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'oauth_signing_key'
-`
-
-func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertOAuthSigningKey, value)
+func (q *sqlQuerier) UpdateTemplateBuildRetryPolicyByID(ctx context.Context, arg UpdateTemplateBuildRetryPolicyByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateBuildRetryPolicyByID,
+		arg.ID,
+		arg.BuildRetryMaxAttempts,
+		arg.BuildRetryBackoff,
+		arg.UpdatedAt,
+	)
 	return err
 }
 
-const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
-INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
-ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
+const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
+UPDATE
+	templates
+SET
+	deleted = $2,
+	updated_at = $3
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) UpsertServiceBanner(ctx context.Context, value string) error {
-	_, err := q.db.ExecContext(ctx, upsertServiceBanner, value)
-	return err
+type UpdateTemplateDeletedByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Deleted   bool      `db:"deleted" json:"deleted"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-const cleanTailnetCoordinators = `-- name: CleanTailnetCoordinators :exec
-DELETE
-FROM tailnet_coordinators
-WHERE heartbeat_at < now() - INTERVAL '24 HOURS'
-`
-
-func (q *sqlQuerier) CleanTailnetCoordinators(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, cleanTailnetCoordinators)
+func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
 	return err
 }
 
-const cleanTailnetLostPeers = `-- name: CleanTailnetLostPeers :exec
-DELETE
-FROM tailnet_peers
-WHERE updated_at < now() - INTERVAL '24 HOURS' AND status = 'lost'::tailnet_status
+const updateTemplateMaintenanceWindowByID = `-- name: UpdateTemplateMaintenanceWindowByID :exec
+UPDATE
+	templates
+SET
+	maintenance_window_schedule = $2,
+	maintenance_window_duration = $3,
+	updated_at = $4
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) CleanTailnetLostPeers(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, cleanTailnetLostPeers)
-	return err
+type UpdateTemplateMaintenanceWindowByIDParams struct {
+	ID                        uuid.UUID `db:"id" json:"id"`
+	MaintenanceWindowSchedule string    `db:"maintenance_window_schedule" json:"maintenance_window_schedule"`
+	MaintenanceWindowDuration int64     `db:"maintenance_window_duration" json:"maintenance_window_duration"`
+	UpdatedAt                 time.Time `db:"updated_at" json:"updated_at"`
 }
 
-const cleanTailnetTunnels = `-- name: CleanTailnetTunnels :exec
-DELETE FROM tailnet_tunnels
-WHERE updated_at < now() - INTERVAL '24 HOURS' AND
-      NOT EXISTS (
-        SELECT 1 FROM tailnet_peers
-        WHERE id = tailnet_tunnels.src_id AND coordinator_id = tailnet_tunnels.coordinator_id
-      )
-`
-
-func (q *sqlQuerier) CleanTailnetTunnels(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, cleanTailnetTunnels)
+func (q *sqlQuerier) UpdateTemplateMaintenanceWindowByID(ctx context.Context, arg UpdateTemplateMaintenanceWindowByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMaintenanceWindowByID,
+		arg.ID,
+		arg.MaintenanceWindowSchedule,
+		arg.MaintenanceWindowDuration,
+		arg.UpdatedAt,
+	)
 	return err
 }
 
-const deleteAllTailnetClientSubscriptions = `-- name: DeleteAllTailnetClientSubscriptions :exec
-DELETE
-FROM tailnet_client_subscriptions
-WHERE client_id = $1 and coordinator_id = $2
+const updateTemplateMaxBuildDurationByID = `-- name: UpdateTemplateMaxBuildDurationByID :exec
+UPDATE
+	templates
+SET
+	max_build_duration = $2,
+	updated_at = $3
+WHERE
+	id = $1
 `
 
-type DeleteAllTailnetClientSubscriptionsParams struct {
-	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+type UpdateTemplateMaxBuildDurationByIDParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	MaxBuildDuration int64     `db:"max_build_duration" json:"max_build_duration"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteAllTailnetClientSubscriptions(ctx context.Context, arg DeleteAllTailnetClientSubscriptionsParams) error {
-	_, err := q.db.ExecContext(ctx, deleteAllTailnetClientSubscriptions, arg.ClientID, arg.CoordinatorID)
+func (q *sqlQuerier) UpdateTemplateMaxBuildDurationByID(ctx context.Context, arg UpdateTemplateMaxBuildDurationByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMaxBuildDurationByID, arg.ID, arg.MaxBuildDuration, arg.UpdatedAt)
 	return err
 }
 
-const deleteAllTailnetTunnels = `-- name: DeleteAllTailnetTunnels :exec
-DELETE
-FROM tailnet_tunnels
-WHERE coordinator_id = $1 and src_id = $2
+const updateTemplateMaxRunningWorkspacesByID = `-- name: UpdateTemplateMaxRunningWorkspacesByID :exec
+UPDATE
+	templates
+SET
+	max_running_workspaces = $2,
+	updated_at = $3
+WHERE
+	id = $1
 `
 
-type DeleteAllTailnetTunnelsParams struct {
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
+type UpdateTemplateMaxRunningWorkspacesByIDParams struct {
+	ID                   uuid.UUID `db:"id" json:"id"`
+	MaxRunningWorkspaces int32     `db:"max_running_workspaces" json:"max_running_workspaces"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteAllTailnetTunnels(ctx context.Context, arg DeleteAllTailnetTunnelsParams) error {
-	_, err := q.db.ExecContext(ctx, deleteAllTailnetTunnels, arg.CoordinatorID, arg.SrcID)
+func (q *sqlQuerier) UpdateTemplateMaxRunningWorkspacesByID(ctx context.Context, arg UpdateTemplateMaxRunningWorkspacesByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMaxRunningWorkspacesByID, arg.ID, arg.MaxRunningWorkspaces, arg.UpdatedAt)
 	return err
 }
 
-const deleteCoordinator = `-- name: DeleteCoordinator :exec
-DELETE
-FROM tailnet_coordinators
-WHERE id = $1
+const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
+UPDATE
+	templates
+SET
+	updated_at = $2,
+	description = $3,
+	name = $4,
+	icon = $5,
+	display_name = $6,
+	allow_user_cancel_workspace_jobs = $7,
+	group_acl = $8
+WHERE
+	id = $1
 `
 
-func (q *sqlQuerier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteCoordinator, id)
-	return err
+type UpdateTemplateMetaByIDParams struct {
+	ID                           uuid.UUID   `db:"id" json:"id"`
+	UpdatedAt                    time.Time   `db:"updated_at" json:"updated_at"`
+	Description                  string      `db:"description" json:"description"`
+	Name                         string      `db:"name" json:"name"`
+	Icon                         string      `db:"icon" json:"icon"`
+	DisplayName                  string      `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs bool        `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	GroupACL                     TemplateACL `db:"group_acl" json:"group_acl"`
 }
 
-const deleteTailnetAgent = `-- name: DeleteTailnetAgent :one
-DELETE
-FROM tailnet_agents
-WHERE id = $1 and coordinator_id = $2
-RETURNING id, coordinator_id
-`
-
-type DeleteTailnetAgentParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Description,
+		arg.Name,
+		arg.Icon,
+		arg.DisplayName,
+		arg.AllowUserCancelWorkspaceJobs,
+		arg.GroupACL,
+	)
+	return err
 }
 
-type DeleteTailnetAgentRow struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+const updateTemplatePortForwardingPolicyByID = `-- name: UpdateTemplatePortForwardingPolicyByID :exec
+UPDATE
+	templates
+SET
+	port_forwarding_deny_by_default = $2,
+	port_forwarding_allowed_ports = $3,
+	updated_at = $4
+WHERE
+	id = $1
+`
+
+type UpdateTemplatePortForwardingPolicyByIDParams struct {
+	ID                          uuid.UUID `db:"id" json:"id"`
+	PortForwardingDenyByDefault bool      `db:"port_forwarding_deny_by_default" json:"port_forwarding_deny_by_default"`
+	PortForwardingAllowedPorts  []int32   `db:"port_forwarding_allowed_ports" json:"port_forwarding_allowed_ports"`
+	UpdatedAt                   time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetAgent, arg.ID, arg.CoordinatorID)
-	var i DeleteTailnetAgentRow
-	err := row.Scan(&i.ID, &i.CoordinatorID)
-	return i, err
+func (q *sqlQuerier) UpdateTemplatePortForwardingPolicyByID(ctx context.Context, arg UpdateTemplatePortForwardingPolicyByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplatePortForwardingPolicyByID, arg.ID, arg.PortForwardingDenyByDefault, pq.Array(arg.PortForwardingAllowedPorts), arg.UpdatedAt)
+	return err
 }
 
-const deleteTailnetClient = `-- name: DeleteTailnetClient :one
-DELETE
-FROM tailnet_clients
-WHERE id = $1 and coordinator_id = $2
-RETURNING id, coordinator_id
+const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
+UPDATE
+	templates
+SET
+	updated_at = $2,
+	allow_user_autostart = $3,
+	allow_user_autostop = $4,
+	default_ttl = $5,
+	use_max_ttl = $6,
+	max_ttl = $7,
+	autostop_requirement_days_of_week = $8,
+	autostop_requirement_weeks = $9,
+	autostart_block_days_of_week = $10,
+	failure_ttl = $11,
+	time_til_dormant = $12,
+	time_til_dormant_autodelete = $13
+WHERE
+	id = $1
 `
 
-type DeleteTailnetClientParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-type DeleteTailnetClientRow struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+type UpdateTemplateScheduleByIDParams struct {
+	ID                            uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
+	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
+	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
+	UseMaxTtl                     bool      `db:"use_max_ttl" json:"use_max_ttl"`
+	MaxTTL                        int64     `db:"max_ttl" json:"max_ttl"`
+	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
+	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
+	AutostartBlockDaysOfWeek      int16     `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
+	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
+	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
+	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
 }
 
-func (q *sqlQuerier) DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetClient, arg.ID, arg.CoordinatorID)
-	var i DeleteTailnetClientRow
-	err := row.Scan(&i.ID, &i.CoordinatorID)
-	return i, err
+func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.AllowUserAutostart,
+		arg.AllowUserAutostop,
+		arg.DefaultTTL,
+		arg.UseMaxTtl,
+		arg.MaxTTL,
+		arg.AutostopRequirementDaysOfWeek,
+		arg.AutostopRequirementWeeks,
+		arg.AutostartBlockDaysOfWeek,
+		arg.FailureTTL,
+		arg.TimeTilDormant,
+		arg.TimeTilDormantAutoDelete,
+	)
+	return err
 }
 
-const deleteTailnetClientSubscription = `-- name: DeleteTailnetClientSubscription :exec
-DELETE
-FROM tailnet_client_subscriptions
-WHERE client_id = $1 and agent_id = $2 and coordinator_id = $3
+const updateTemplateSessionRecordingByID = `-- name: UpdateTemplateSessionRecordingByID :exec
+UPDATE
+	templates
+SET
+	session_recording_enabled = $2,
+	session_recording_retention = $3,
+	updated_at = $4
+WHERE
+	id = $1
 `
 
-type DeleteTailnetClientSubscriptionParams struct {
-	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
-	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+type UpdateTemplateSessionRecordingByIDParams struct {
+	ID                        uuid.UUID `db:"id" json:"id"`
+	SessionRecordingEnabled   bool      `db:"session_recording_enabled" json:"session_recording_enabled"`
+	SessionRecordingRetention int64     `db:"session_recording_retention" json:"session_recording_retention"`
+	UpdatedAt                 time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteTailnetClientSubscription(ctx context.Context, arg DeleteTailnetClientSubscriptionParams) error {
-	_, err := q.db.ExecContext(ctx, deleteTailnetClientSubscription, arg.ClientID, arg.AgentID, arg.CoordinatorID)
+func (q *sqlQuerier) UpdateTemplateSessionRecordingByID(ctx context.Context, arg UpdateTemplateSessionRecordingByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateSessionRecordingByID,
+		arg.ID,
+		arg.SessionRecordingEnabled,
+		arg.SessionRecordingRetention,
+		arg.UpdatedAt,
+	)
 	return err
 }
 
-const deleteTailnetPeer = `-- name: DeleteTailnetPeer :one
-DELETE
-FROM tailnet_peers
-WHERE id = $1 and coordinator_id = $2
-RETURNING id, coordinator_id
+const updateTemplateVisibilityByID = `-- name: UpdateTemplateVisibilityByID :exec
+UPDATE
+	templates
+SET
+	visibility = $2,
+	updated_at = $3
+WHERE
+	id = $1
 `
 
-type DeleteTailnetPeerParams struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-}
-
-type DeleteTailnetPeerRow struct {
-	ID            uuid.UUID `db:"id" json:"id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
+type UpdateTemplateVisibilityByIDParams struct {
+	ID         uuid.UUID          `db:"id" json:"id"`
+	Visibility TemplateVisibility `db:"visibility" json:"visibility"`
+	UpdatedAt  time.Time          `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteTailnetPeer(ctx context.Context, arg DeleteTailnetPeerParams) (DeleteTailnetPeerRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetPeer, arg.ID, arg.CoordinatorID)
-	var i DeleteTailnetPeerRow
-	err := row.Scan(&i.ID, &i.CoordinatorID)
-	return i, err
+func (q *sqlQuerier) UpdateTemplateVisibilityByID(ctx context.Context, arg UpdateTemplateVisibilityByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVisibilityByID, arg.ID, arg.Visibility, arg.UpdatedAt)
+	return err
 }
 
-const deleteTailnetTunnel = `-- name: DeleteTailnetTunnel :one
-DELETE
-FROM tailnet_tunnels
-WHERE coordinator_id = $1 and src_id = $2 and dst_id = $3
-RETURNING coordinator_id, src_id, dst_id
+const updateTemplateWorkspaceNamePolicyByID = `-- name: UpdateTemplateWorkspaceNamePolicyByID :exec
+UPDATE
+	templates
+SET
+	workspace_name_pattern = $2,
+	workspace_name_description = $3,
+	updated_at = $4
+WHERE
+	id = $1
 `
 
-type DeleteTailnetTunnelParams struct {
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
-	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
-}
-
-type DeleteTailnetTunnelRow struct {
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
-	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
+type UpdateTemplateWorkspaceNamePolicyByIDParams struct {
+	ID                       uuid.UUID `db:"id" json:"id"`
+	WorkspaceNamePattern     string    `db:"workspace_name_pattern" json:"workspace_name_pattern"`
+	WorkspaceNameDescription string    `db:"workspace_name_description" json:"workspace_name_description"`
+	UpdatedAt                time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) DeleteTailnetTunnel(ctx context.Context, arg DeleteTailnetTunnelParams) (DeleteTailnetTunnelRow, error) {
-	row := q.db.QueryRowContext(ctx, deleteTailnetTunnel, arg.CoordinatorID, arg.SrcID, arg.DstID)
-	var i DeleteTailnetTunnelRow
-	err := row.Scan(&i.CoordinatorID, &i.SrcID, &i.DstID)
-	return i, err
+func (q *sqlQuerier) UpdateTemplateWorkspaceNamePolicyByID(ctx context.Context, arg UpdateTemplateWorkspaceNamePolicyByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateWorkspaceNamePolicyByID, arg.ID, arg.WorkspaceNamePattern, arg.WorkspaceNameDescription, arg.UpdatedAt)
+	return err
 }
 
-const getAllTailnetAgents = `-- name: GetAllTailnetAgents :many
-SELECT id, coordinator_id, updated_at, node
-FROM tailnet_agents
+const deleteOrganizationTemplateVariableValue = `-- name: DeleteOrganizationTemplateVariableValue :exec
+DELETE FROM
+	organization_template_variable_values
+WHERE
+	organization_id = $1
+	AND name = $2
 `
 
-func (q *sqlQuerier) GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetAgents)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TailnetAgent
-	for rows.Next() {
-		var i TailnetAgent
-		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type DeleteOrganizationTemplateVariableValueParams struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	Name           string    `db:"name" json:"name"`
 }
 
-const getAllTailnetCoordinators = `-- name: GetAllTailnetCoordinators :many
+func (q *sqlQuerier) DeleteOrganizationTemplateVariableValue(ctx context.Context, arg DeleteOrganizationTemplateVariableValueParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOrganizationTemplateVariableValue, arg.OrganizationID, arg.Name)
+	return err
+}
 
-SELECT id, heartbeat_at FROM tailnet_coordinators
+const deleteTemplateVariableValue = `-- name: DeleteTemplateVariableValue :exec
+DELETE FROM
+	template_variable_values
+WHERE
+	template_id = $1
+	AND name = $2
 `
 
-// For PG Coordinator HTMLDebug
-func (q *sqlQuerier) GetAllTailnetCoordinators(ctx context.Context) ([]TailnetCoordinator, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetCoordinators)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TailnetCoordinator
-	for rows.Next() {
-		var i TailnetCoordinator
-		if err := rows.Scan(&i.ID, &i.HeartbeatAt); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type DeleteTemplateVariableValueParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Name       string    `db:"name" json:"name"`
 }
 
-const getAllTailnetPeers = `-- name: GetAllTailnetPeers :many
-SELECT id, coordinator_id, updated_at, node, status FROM tailnet_peers
-`
-
-func (q *sqlQuerier) GetAllTailnetPeers(ctx context.Context) ([]TailnetPeer, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetPeers)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TailnetPeer
-	for rows.Next() {
-		var i TailnetPeer
-		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
-			&i.Status,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteTemplateVariableValue(ctx context.Context, arg DeleteTemplateVariableValueParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVariableValue, arg.TemplateID, arg.Name)
+	return err
 }
 
-const getAllTailnetTunnels = `-- name: GetAllTailnetTunnels :many
-SELECT coordinator_id, src_id, dst_id, updated_at FROM tailnet_tunnels
+const getOrganizationTemplateVariableValues = `-- name: GetOrganizationTemplateVariableValues :many
+SELECT
+	organization_id, name, value, value_key_id, sensitive, created_at, updated_at
+FROM
+	organization_template_variable_values
+WHERE
+	organization_id = $1
+ORDER BY
+	name ASC
 `
 
-func (q *sqlQuerier) GetAllTailnetTunnels(ctx context.Context) ([]TailnetTunnel, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTailnetTunnels)
+func (q *sqlQuerier) GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]OrganizationTemplateVariableValue, error) {
+	rows, err := q.db.QueryContext(ctx, getOrganizationTemplateVariableValues, organizationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetTunnel
+	var items []OrganizationTemplateVariableValue
 	for rows.Next() {
-		var i TailnetTunnel
+		var i OrganizationTemplateVariableValue
 		if err := rows.Scan(
-			&i.CoordinatorID,
-			&i.SrcID,
-			&i.DstID,
+			&i.OrganizationID,
+			&i.Name,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.Sensitive,
+			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
@@ -5188,26 +9842,34 @@ func (q *sqlQuerier) GetAllTailnetTunnels(ctx context.Context) ([]TailnetTunnel,
 	return items, nil
 }
 
-const getTailnetAgents = `-- name: GetTailnetAgents :many
-SELECT id, coordinator_id, updated_at, node
-FROM tailnet_agents
-WHERE id = $1
+const getTemplateVariableValuesByTemplateID = `-- name: GetTemplateVariableValuesByTemplateID :many
+SELECT
+	template_id, name, value, value_key_id, sensitive, created_at, updated_at
+FROM
+	template_variable_values
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC
 `
 
-func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetAgents, id)
+func (q *sqlQuerier) GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]TemplateVariableValue, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVariableValuesByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetAgent
+	var items []TemplateVariableValue
 	for rows.Next() {
-		var i TailnetAgent
+		var i TemplateVariableValue
 		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
+			&i.TemplateID,
+			&i.Name,
+			&i.Value,
+			&i.ValueKeyID,
+			&i.Sensitive,
+			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.Node,
 		); err != nil {
 			return nil, err
 		}
@@ -5222,63 +9884,134 @@ func (q *sqlQuerier) GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]Tail
 	return items, nil
 }
 
-const getTailnetClientsForAgent = `-- name: GetTailnetClientsForAgent :many
-SELECT id, coordinator_id, updated_at, node
-FROM tailnet_clients
-WHERE id IN (
-	SELECT tailnet_client_subscriptions.client_id
-	FROM tailnet_client_subscriptions
-	WHERE tailnet_client_subscriptions.agent_id = $1
-)
+const upsertOrganizationTemplateVariableValue = `-- name: UpsertOrganizationTemplateVariableValue :one
+INSERT INTO
+	organization_template_variable_values (organization_id, name, value, value_key_id, sensitive, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(organization_id, name)
+DO UPDATE SET
+	value = $3,
+	value_key_id = $4,
+	sensitive = $5,
+	updated_at = $7
+RETURNING organization_id, name, value, value_key_id, sensitive, created_at, updated_at
+`
+
+type UpsertOrganizationTemplateVariableValueParams struct {
+	OrganizationID uuid.UUID      `db:"organization_id" json:"organization_id"`
+	Name           string         `db:"name" json:"name"`
+	Value          string         `db:"value" json:"value"`
+	ValueKeyID     sql.NullString `db:"value_key_id" json:"value_key_id"`
+	Sensitive      bool           `db:"sensitive" json:"sensitive"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertOrganizationTemplateVariableValue(ctx context.Context, arg UpsertOrganizationTemplateVariableValueParams) (OrganizationTemplateVariableValue, error) {
+	row := q.db.QueryRowContext(ctx, upsertOrganizationTemplateVariableValue,
+		arg.OrganizationID,
+		arg.Name,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.Sensitive,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i OrganizationTemplateVariableValue
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.Name,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.Sensitive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertTemplateVariableValue = `-- name: UpsertTemplateVariableValue :one
+INSERT INTO
+	template_variable_values (template_id, name, value, value_key_id, sensitive, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(template_id, name)
+DO UPDATE SET
+	value = $3,
+	value_key_id = $4,
+	sensitive = $5,
+	updated_at = $7
+RETURNING template_id, name, value, value_key_id, sensitive, created_at, updated_at
 `
 
-func (q *sqlQuerier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetClientsForAgent, agentID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TailnetClient
-	for rows.Next() {
-		var i TailnetClient
-		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpsertTemplateVariableValueParams struct {
+	TemplateID uuid.UUID      `db:"template_id" json:"template_id"`
+	Name       string         `db:"name" json:"name"`
+	Value      string         `db:"value" json:"value"`
+	ValueKeyID sql.NullString `db:"value_key_id" json:"value_key_id"`
+	Sensitive  bool           `db:"sensitive" json:"sensitive"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-const getTailnetPeers = `-- name: GetTailnetPeers :many
-SELECT id, coordinator_id, updated_at, node, status FROM tailnet_peers WHERE id = $1
+func (q *sqlQuerier) UpsertTemplateVariableValue(ctx context.Context, arg UpsertTemplateVariableValueParams) (TemplateVariableValue, error) {
+	row := q.db.QueryRowContext(ctx, upsertTemplateVariableValue,
+		arg.TemplateID,
+		arg.Name,
+		arg.Value,
+		arg.ValueKeyID,
+		arg.Sensitive,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i TemplateVariableValue
+	err := row.Scan(
+		&i.TemplateID,
+		&i.Name,
+		&i.Value,
+		&i.ValueKeyID,
+		&i.Sensitive,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, display_condition FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
 `
 
-func (q *sqlQuerier) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]TailnetPeer, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetPeers, id)
+func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TailnetPeer
+	var items []TemplateVersionParameter
 	for rows.Next() {
-		var i TailnetPeer
+		var i TemplateVersionParameter
 		if err := rows.Scan(
-			&i.ID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
-			&i.Status,
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Description,
+			&i.Type,
+			&i.Mutable,
+			&i.DefaultValue,
+			&i.Icon,
+			&i.Options,
+			&i.ValidationRegex,
+			&i.ValidationMin,
+			&i.ValidationMax,
+			&i.ValidationError,
+			&i.ValidationMonotonic,
+			&i.Required,
+			&i.DisplayName,
+			&i.DisplayOrder,
+			&i.Ephemeral,
+			&i.DisplayCondition,
 		); err != nil {
 			return nil, err
 		}
@@ -5293,81 +10026,161 @@ func (q *sqlQuerier) GetTailnetPeers(ctx context.Context, id uuid.UUID) ([]Tailn
 	return items, nil
 }
 
-const getTailnetTunnelPeerBindings = `-- name: GetTailnetTunnelPeerBindings :many
-SELECT tailnet_tunnels.dst_id as peer_id, tailnet_peers.coordinator_id, tailnet_peers.updated_at, tailnet_peers.node, tailnet_peers.status
-FROM tailnet_tunnels
-INNER JOIN tailnet_peers ON tailnet_tunnels.dst_id = tailnet_peers.id
-WHERE tailnet_tunnels.src_id = $1
-UNION
-SELECT tailnet_tunnels.src_id as peer_id, tailnet_peers.coordinator_id, tailnet_peers.updated_at, tailnet_peers.node, tailnet_peers.status
-FROM tailnet_tunnels
-INNER JOIN tailnet_peers ON tailnet_tunnels.src_id = tailnet_peers.id
-WHERE tailnet_tunnels.dst_id = $1
+const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+INSERT INTO
+    template_version_parameters (
+        template_version_id,
+        name,
+        description,
+        type,
+        mutable,
+        default_value,
+        icon,
+        options,
+        validation_regex,
+        validation_min,
+        validation_max,
+        validation_error,
+        validation_monotonic,
+        required,
+        display_name,
+        display_order,
+        ephemeral,
+        display_condition
+    )
+VALUES
+    (
+        $1,
+        $2,
+        $3,
+        $4,
+        $5,
+        $6,
+        $7,
+        $8,
+        $9,
+        $10,
+        $11,
+        $12,
+        $13,
+        $14,
+        $15,
+        $16,
+        $17,
+        $18
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, display_condition
+`
+
+type InsertTemplateVersionParameterParams struct {
+	TemplateVersionID   uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Name                string          `db:"name" json:"name"`
+	Description         string          `db:"description" json:"description"`
+	Type                string          `db:"type" json:"type"`
+	Mutable             bool            `db:"mutable" json:"mutable"`
+	DefaultValue        string          `db:"default_value" json:"default_value"`
+	Icon                string          `db:"icon" json:"icon"`
+	Options             json.RawMessage `db:"options" json:"options"`
+	ValidationRegex     string          `db:"validation_regex" json:"validation_regex"`
+	ValidationMin       sql.NullInt32   `db:"validation_min" json:"validation_min"`
+	ValidationMax       sql.NullInt32   `db:"validation_max" json:"validation_max"`
+	ValidationError     string          `db:"validation_error" json:"validation_error"`
+	ValidationMonotonic string          `db:"validation_monotonic" json:"validation_monotonic"`
+	Required            bool            `db:"required" json:"required"`
+	DisplayName         string          `db:"display_name" json:"display_name"`
+	DisplayOrder        int32           `db:"display_order" json:"display_order"`
+	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+	DisplayCondition    string          `db:"display_condition" json:"display_condition"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Mutable,
+		arg.DefaultValue,
+		arg.Icon,
+		arg.Options,
+		arg.ValidationRegex,
+		arg.ValidationMin,
+		arg.ValidationMax,
+		arg.ValidationError,
+		arg.ValidationMonotonic,
+		arg.Required,
+		arg.DisplayName,
+		arg.DisplayOrder,
+		arg.Ephemeral,
+		arg.DisplayCondition,
+	)
+	var i TemplateVersionParameter
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Mutable,
+		&i.DefaultValue,
+		&i.Icon,
+		&i.Options,
+		&i.ValidationRegex,
+		&i.ValidationMin,
+		&i.ValidationMax,
+		&i.ValidationError,
+		&i.ValidationMonotonic,
+		&i.Required,
+		&i.DisplayName,
+		&i.DisplayOrder,
+		&i.Ephemeral,
+		&i.DisplayCondition,
+	)
+	return i, err
+}
+
+const deleteTemplateVersionPreset = `-- name: DeleteTemplateVersionPreset :exec
+DELETE FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+	AND name = $2
 `
 
-type GetTailnetTunnelPeerBindingsRow struct {
-	PeerID        uuid.UUID     `db:"peer_id" json:"peer_id"`
-	CoordinatorID uuid.UUID     `db:"coordinator_id" json:"coordinator_id"`
-	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
-	Node          []byte        `db:"node" json:"node"`
-	Status        TailnetStatus `db:"status" json:"status"`
+type DeleteTemplateVersionPresetParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetTailnetTunnelPeerBindings(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerBindingsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetTunnelPeerBindings, srcID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetTailnetTunnelPeerBindingsRow
-	for rows.Next() {
-		var i GetTailnetTunnelPeerBindingsRow
-		if err := rows.Scan(
-			&i.PeerID,
-			&i.CoordinatorID,
-			&i.UpdatedAt,
-			&i.Node,
-			&i.Status,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *sqlQuerier) DeleteTemplateVersionPreset(ctx context.Context, arg DeleteTemplateVersionPresetParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTemplateVersionPreset, arg.TemplateVersionID, arg.Name)
+	return err
 }
 
-const getTailnetTunnelPeerIDs = `-- name: GetTailnetTunnelPeerIDs :many
-SELECT dst_id as peer_id, coordinator_id, updated_at
-FROM tailnet_tunnels
-WHERE tailnet_tunnels.src_id = $1
-UNION
-SELECT src_id as peer_id, coordinator_id, updated_at
-FROM tailnet_tunnels
-WHERE tailnet_tunnels.dst_id = $1
+const getTemplateVersionPresets = `-- name: GetTemplateVersionPresets :many
+SELECT
+	template_version_id, name, parameters, created_at
+FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+ORDER BY
+	name ASC
 `
 
-type GetTailnetTunnelPeerIDsRow struct {
-	PeerID        uuid.UUID `db:"peer_id" json:"peer_id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUID) ([]GetTailnetTunnelPeerIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTailnetTunnelPeerIDs, srcID)
+func (q *sqlQuerier) GetTemplateVersionPresets(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionPreset, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPresets, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTailnetTunnelPeerIDsRow
+	var items []TemplateVersionPreset
 	for rows.Next() {
-		var i GetTailnetTunnelPeerIDsRow
-		if err := rows.Scan(&i.PeerID, &i.CoordinatorID, &i.UpdatedAt); err != nil {
+		var i TemplateVersionPreset
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.Name,
+			&i.Parameters,
+			&i.CreatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -5381,423 +10194,342 @@ func (q *sqlQuerier) GetTailnetTunnelPeerIDs(ctx context.Context, srcID uuid.UUI
 	return items, nil
 }
 
-const upsertTailnetAgent = `-- name: UpsertTailnetAgent :one
+const insertTemplateVersionPreset = `-- name: InsertTemplateVersionPreset :one
 INSERT INTO
-	tailnet_agents (
-	id,
-	coordinator_id,
-	node,
-	updated_at
-)
+	template_version_presets (template_version_id, name, parameters, created_at)
 VALUES
-	($1, $2, $3, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
-DO UPDATE SET
-	id = $1,
-	coordinator_id = $2,
-	node = $3,
-	updated_at = now() at time zone 'utc'
-RETURNING id, coordinator_id, updated_at, node
+	($1, $2, $3, $4)
+RETURNING template_version_id, name, parameters, created_at
 `
 
-type UpsertTailnetAgentParams struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
-	Node          json.RawMessage `db:"node" json:"node"`
+type InsertTemplateVersionPresetParams struct {
+	TemplateVersionID uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	Name              string          `db:"name" json:"name"`
+	Parameters        json.RawMessage `db:"parameters" json:"parameters"`
+	CreatedAt         time.Time       `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetAgent, arg.ID, arg.CoordinatorID, arg.Node)
-	var i TailnetAgent
-	err := row.Scan(
-		&i.ID,
-		&i.CoordinatorID,
-		&i.UpdatedAt,
-		&i.Node,
+func (q *sqlQuerier) InsertTemplateVersionPreset(ctx context.Context, arg InsertTemplateVersionPresetParams) (TemplateVersionPreset, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionPreset,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Parameters,
+		arg.CreatedAt,
 	)
-	return i, err
-}
-
-const upsertTailnetClient = `-- name: UpsertTailnetClient :one
-INSERT INTO
-	tailnet_clients (
-	id,
-	coordinator_id,
-	node,
-	updated_at
-)
-VALUES
-	($1, $2, $3, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
-DO UPDATE SET
-	id = $1,
-	coordinator_id = $2,
-	node = $3,
-	updated_at = now() at time zone 'utc'
-RETURNING id, coordinator_id, updated_at, node
-`
-
-type UpsertTailnetClientParams struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
-	Node          json.RawMessage `db:"node" json:"node"`
-}
-
-func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetClient, arg.ID, arg.CoordinatorID, arg.Node)
-	var i TailnetClient
+	var i TemplateVersionPreset
 	err := row.Scan(
-		&i.ID,
-		&i.CoordinatorID,
-		&i.UpdatedAt,
-		&i.Node,
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Parameters,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
-const upsertTailnetClientSubscription = `-- name: UpsertTailnetClientSubscription :exec
-INSERT INTO
-	tailnet_client_subscriptions (
-	client_id,
-	coordinator_id,
-	agent_id,
-	updated_at
-)
-VALUES
-	($1, $2, $3, now() at time zone 'utc')
-ON CONFLICT (client_id, coordinator_id, agent_id)
-DO UPDATE SET
-	client_id = $1,
-	coordinator_id = $2,
-	agent_id = $3,
-	updated_at = now() at time zone 'utc'
-`
-
-type UpsertTailnetClientSubscriptionParams struct {
-	ClientID      uuid.UUID `db:"client_id" json:"client_id"`
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
-}
-
-func (q *sqlQuerier) UpsertTailnetClientSubscription(ctx context.Context, arg UpsertTailnetClientSubscriptionParams) error {
-	_, err := q.db.ExecContext(ctx, upsertTailnetClientSubscription, arg.ClientID, arg.CoordinatorID, arg.AgentID)
-	return err
-}
-
-const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
-INSERT INTO
-	tailnet_coordinators (
-	id,
-	heartbeat_at
-)
-VALUES
-	($1, now() at time zone 'utc')
-ON CONFLICT (id)
-DO UPDATE SET
-  id = $1,
-  heartbeat_at = now() at time zone 'utc'
-RETURNING id, heartbeat_at
-`
-
-func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
-	var i TailnetCoordinator
-	err := row.Scan(&i.ID, &i.HeartbeatAt)
-	return i, err
-}
-
-const upsertTailnetPeer = `-- name: UpsertTailnetPeer :one
-INSERT INTO
-	tailnet_peers (
-	id,
-	coordinator_id,
-	node,
-	status,
-	updated_at
-)
-VALUES
-	($1, $2, $3, $4, now() at time zone 'utc')
-ON CONFLICT (id, coordinator_id)
-DO UPDATE SET
-	id = $1,
-	coordinator_id = $2,
-	node = $3,
-	status = $4,
-	updated_at = now() at time zone 'utc'
-RETURNING id, coordinator_id, updated_at, node, status
+const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
+UPDATE
+	template_versions
+SET
+	archived = true,
+	updated_at = $1
+FROM
+	-- Archive all versions that are returned from this query.
+	(
+		SELECT
+			scoped_template_versions.id
+		FROM
+			-- Scope an archive to a single template and ignore already archived template versions
+			(
+				SELECT
+					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived
+				FROM
+					template_versions
+				WHERE
+					template_versions.template_id = $2 :: uuid
+					AND
+					archived = false
+					AND
+					-- This allows archiving a specific template version.
+					CASE
+						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
+							template_versions.id = $3 :: uuid
+						ELSE
+							true
+						END
+			) AS scoped_template_versions
+			LEFT JOIN
+				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
+			LEFT JOIN
+				templates ON scoped_template_versions.template_id = templates.id
+		WHERE
+		  -- Actively used template versions (meaning the latest build is using
+		  -- the version) are never archived. A "restart" command on the workspace,
+		  -- even if failed, would use the version. So it cannot be archived until
+		  -- the build is outdated.
+		NOT EXISTS (
+			-- Return all "used" versions, where "used" is defined as being
+			-- used by a latest workspace build.
+			SELECT template_version_id FROM (
+				SELECT
+					DISTINCT ON (workspace_id) template_version_id, transition
+				FROM
+					workspace_builds
+				ORDER BY workspace_id, build_number DESC
+				) AS used_versions
+			WHERE
+				used_versions.transition != 'delete'
+				AND
+				scoped_template_versions.id = used_versions.template_version_id
+		)
+		-- Also never archive the active template version
+		AND active_version_id != scoped_template_versions.id
+		AND CASE
+			-- Optionally, only archive versions that match a given
+			-- job status like 'failed'.
+			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
+				provisioner_jobs.job_status = $4 :: provisioner_job_status
+			ELSE
+				true
+		END
+		-- Pending or running jobs should not be archived, as they are "in progress"
+		AND provisioner_jobs.job_status != 'running'
+		AND provisioner_jobs.job_status != 'pending'
+	) AS archived_versions
+WHERE
+	template_versions.id IN (archived_versions.id)
+RETURNING template_versions.id
 `
 
-type UpsertTailnetPeerParams struct {
-	ID            uuid.UUID     `db:"id" json:"id"`
-	CoordinatorID uuid.UUID     `db:"coordinator_id" json:"coordinator_id"`
-	Node          []byte        `db:"node" json:"node"`
-	Status        TailnetStatus `db:"status" json:"status"`
+type ArchiveUnusedTemplateVersionsParams struct {
+	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
+	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
+	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
 }
 
-func (q *sqlQuerier) UpsertTailnetPeer(ctx context.Context, arg UpsertTailnetPeerParams) (TailnetPeer, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetPeer,
-		arg.ID,
-		arg.CoordinatorID,
-		arg.Node,
-		arg.Status,
-	)
-	var i TailnetPeer
-	err := row.Scan(
-		&i.ID,
-		&i.CoordinatorID,
-		&i.UpdatedAt,
-		&i.Node,
-		&i.Status,
+// Archiving templates is a soft delete action, so is reversible.
+// Archiving prevents the version from being used and discovered
+// by listing.
+// Only unused template versions will be archived, which are any versions not
+// referenced by the latest build of a workspace.
+func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
+		arg.UpdatedAt,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.JobStatus,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const upsertTailnetTunnel = `-- name: UpsertTailnetTunnel :one
-INSERT INTO
-	tailnet_tunnels (
-	coordinator_id,
-	src_id,
-	dst_id,
-	updated_at
-)
-VALUES
-	($1, $2, $3, now() at time zone 'utc')
-ON CONFLICT (coordinator_id, src_id, dst_id)
-DO UPDATE SET
-	coordinator_id = $1,
-	src_id = $2,
-	dst_id = $3,
-	updated_at = now() at time zone 'utc'
-RETURNING coordinator_id, src_id, dst_id, updated_at
+const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	created_at < (
+		SELECT created_at
+		FROM template_version_with_user AS tv
+		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
+	)
+	AND organization_id = $1
+	AND template_id = $3
+ORDER BY created_at DESC
+LIMIT 1
 `
 
-type UpsertTailnetTunnelParams struct {
-	CoordinatorID uuid.UUID `db:"coordinator_id" json:"coordinator_id"`
-	SrcID         uuid.UUID `db:"src_id" json:"src_id"`
-	DstID         uuid.UUID `db:"dst_id" json:"dst_id"`
+type GetPreviousTemplateVersionParams struct {
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	Name           string        `db:"name" json:"name"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
 }
 
-func (q *sqlQuerier) UpsertTailnetTunnel(ctx context.Context, arg UpsertTailnetTunnelParams) (TailnetTunnel, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetTunnel, arg.CoordinatorID, arg.SrcID, arg.DstID)
-	var i TailnetTunnel
+func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
+	var i TemplateVersion
 	err := row.Scan(
-		&i.CoordinatorID,
-		&i.SrcID,
-		&i.DstID,
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.ExternalAuthProviders),
+		&i.Message,
+		&i.Archived,
+		&i.GitCommitSha,
+		&i.GitBranch,
+		&i.GitTag,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplateAverageBuildTime = `-- name: GetTemplateAverageBuildTime :one
-WITH build_times AS (
+const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
 SELECT
-	EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec,
-	workspace_builds.transition
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
 FROM
-	workspace_builds
-JOIN template_versions ON
-	workspace_builds.template_version_id = template_versions.id
-JOIN provisioner_jobs pj ON
-	workspace_builds.job_id = pj.id
+	template_version_with_user AS template_versions
 WHERE
-	template_versions.template_id = $1 AND
-		(pj.completed_at IS NOT NULL) AND (pj.started_at IS NOT NULL) AND
-		(pj.started_at > $2) AND
-		(pj.canceled_at IS NULL) AND
-		((pj.error IS NULL) OR (pj.error = ''))
-ORDER BY
-	workspace_builds.created_at DESC
-)
-SELECT
-	-- Postgres offers no clear way to DRY this short of a function or other
-	-- complexities.
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_50,
-	coalesce((PERCENTILE_DISC(0.5) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_50,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'start')), -1)::FLOAT AS start_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'stop')), -1)::FLOAT AS stop_95,
-	coalesce((PERCENTILE_DISC(0.95) WITHIN GROUP(ORDER BY exec_time_sec) FILTER (WHERE transition = 'delete')), -1)::FLOAT AS delete_95
-FROM build_times
+	id = $1
 `
 
-type GetTemplateAverageBuildTimeParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	StartTime  sql.NullTime  `db:"start_time" json:"start_time"`
-}
-
-type GetTemplateAverageBuildTimeRow struct {
-	Start50  float64 `db:"start_50" json:"start_50"`
-	Stop50   float64 `db:"stop_50" json:"stop_50"`
-	Delete50 float64 `db:"delete_50" json:"delete_50"`
-	Start95  float64 `db:"start_95" json:"start_95"`
-	Stop95   float64 `db:"stop_95" json:"stop_95"`
-	Delete95 float64 `db:"delete_95" json:"delete_95"`
-}
-
-func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateAverageBuildTime, arg.TemplateID, arg.StartTime)
-	var i GetTemplateAverageBuildTimeRow
+func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
+	var i TemplateVersion
 	err := row.Scan(
-		&i.Start50,
-		&i.Stop50,
-		&i.Delete50,
-		&i.Start95,
-		&i.Stop95,
-		&i.Delete95,
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.ExternalAuthProviders),
+		&i.Message,
+		&i.Archived,
+		&i.GitCommitSha,
+		&i.GitBranch,
+		&i.GitTag,
+		&i.CreatedByAvatarURL,
+		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplateByID = `-- name: GetTemplateByID :one
+const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
 FROM
-	template_with_users
+	template_version_with_user AS template_versions
 WHERE
-	id = $1
-LIMIT
-	1
+	job_id = $1
 `
 
-func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByID, id)
-	var i Template
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
-		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
-		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.MaxTTL,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.AutostartBlockDaysOfWeek,
-		&i.RequireActiveVersion,
-		&i.Deprecated,
-		&i.UseMaxTtl,
+func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
+	var i TemplateVersion
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Readme,
+		&i.JobID,
+		&i.CreatedBy,
+		pq.Array(&i.ExternalAuthProviders),
+		&i.Message,
+		&i.Archived,
+		&i.GitCommitSha,
+		&i.GitBranch,
+		&i.GitTag,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
+const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
 FROM
-	template_with_users AS templates
+	template_version_with_user AS template_versions
 WHERE
-	organization_id = $1
-	AND deleted = $2
-	AND LOWER("name") = LOWER($3)
-LIMIT
-	1
+	template_id = $1
+	AND "name" = $2
 `
 
-type GetTemplateByOrganizationAndNameParams struct {
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	Deleted        bool      `db:"deleted" json:"deleted"`
-	Name           string    `db:"name" json:"name"`
+type GetTemplateVersionByTemplateIDAndNameParams struct {
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	Name       string        `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateByOrganizationAndName, arg.OrganizationID, arg.Deleted, arg.Name)
-	var i Template
+func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
+	var i TemplateVersion
 	err := row.Scan(
 		&i.ID,
+		&i.TemplateID,
+		&i.OrganizationID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.OrganizationID,
-		&i.Deleted,
 		&i.Name,
-		&i.Provisioner,
-		&i.ActiveVersionID,
-		&i.Description,
-		&i.DefaultTTL,
+		&i.Readme,
+		&i.JobID,
 		&i.CreatedBy,
-		&i.Icon,
-		&i.UserACL,
-		&i.GroupACL,
-		&i.DisplayName,
-		&i.AllowUserCancelWorkspaceJobs,
-		&i.MaxTTL,
-		&i.AllowUserAutostart,
-		&i.AllowUserAutostop,
-		&i.FailureTTL,
-		&i.TimeTilDormant,
-		&i.TimeTilDormantAutoDelete,
-		&i.AutostopRequirementDaysOfWeek,
-		&i.AutostopRequirementWeeks,
-		&i.AutostartBlockDaysOfWeek,
-		&i.RequireActiveVersion,
-		&i.Deprecated,
-		&i.UseMaxTtl,
+		pq.Array(&i.ExternalAuthProviders),
+		&i.Message,
+		&i.Archived,
+		&i.GitCommitSha,
+		&i.GitBranch,
+		&i.GitTag,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username FROM template_with_users AS templates
-ORDER BY (name, id) ASC
+const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
+SELECT
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
+FROM
+	template_version_with_user AS template_versions
+WHERE
+	id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplates)
+func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Template
+	var items []TemplateVersion
 	for rows.Next() {
-		var i Template
+		var i TemplateVersion
 		if err := rows.Scan(
 			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.OrganizationID,
-			&i.Deleted,
 			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
+			&i.Readme,
+			&i.JobID,
 			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.MaxTTL,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.AutostartBlockDaysOfWeek,
-			&i.RequireActiveVersion,
-			&i.Deprecated,
-			&i.UseMaxTtl,
+			pq.Array(&i.ExternalAuthProviders),
+			&i.Message,
+			&i.Archived,
+			&i.GitCommitSha,
+			&i.GitBranch,
+			&i.GitTag,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -5814,100 +10546,132 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 	return items, nil
 }
 
-const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
+const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, time_til_dormant, time_til_dormant_autodelete, autostop_requirement_days_of_week, autostop_requirement_weeks, autostart_block_days_of_week, require_active_version, deprecated, use_max_ttl, created_by_avatar_url, created_by_username
+	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username
 FROM
-	template_with_users AS templates
+	template_version_with_user AS template_versions
 WHERE
-	-- Optionally include deleted templates
-	templates.deleted = $1
-	-- Filter by organization_id
-	AND CASE
-		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
-			organization_id = $2
-		ELSE true
-	END
-	-- Filter by exact name
-	AND CASE
-		WHEN $3 :: text != '' THEN
-			LOWER("name") = LOWER($3)
-		ELSE true
-	END
-	-- Filter by ids
-	AND CASE
-		WHEN array_length($4 :: uuid[], 1) > 0 THEN
-			id = ANY($4)
-		ELSE true
+	template_id = $1 :: uuid
+	  AND CASE
+		-- If no filter is provided, default to returning ALL template versions.
+		-- The called should always provide a filter if they want to omit
+		-- archived versions.
+		WHEN $2 :: boolean IS NULL THEN true
+		ELSE template_versions.archived = $2 :: boolean
 	END
-	-- Filter by deprecated
 	AND CASE
-		WHEN $5 :: boolean IS NOT NULL THEN
-			CASE
-				WHEN $5 :: boolean THEN
-					deprecated != ''
-				ELSE
-					deprecated = ''
-			END
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the created_at field, so select all
+			-- rows after the cursor.
+			(created_at, id) > (
+				SELECT
+					created_at, id
+				FROM
+					template_versions
+				WHERE
+					id = $3
+			)
+		)
 		ELSE true
 	END
-  -- Authorize Filter clause will be injected below in GetAuthorizedTemplates
-  -- @authorize_filter
-ORDER BY (name, id) ASC
+ORDER BY
+    -- Deterministic and consistent ordering of all rows, even if they share
+    -- a timestamp. This is to ensure consistent pagination.
+	(created_at, id) ASC OFFSET $4
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($5 :: int, 0)
 `
 
-type GetTemplatesWithFilterParams struct {
-	Deleted        bool         `db:"deleted" json:"deleted"`
-	OrganizationID uuid.UUID    `db:"organization_id" json:"organization_id"`
-	ExactName      string       `db:"exact_name" json:"exact_name"`
-	IDs            []uuid.UUID  `db:"ids" json:"ids"`
-	Deprecated     sql.NullBool `db:"deprecated" json:"deprecated"`
+type GetTemplateVersionsByTemplateIDParams struct {
+	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
+	Archived   sql.NullBool `db:"archived" json:"archived"`
+	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
+	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
 }
 
-func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplatesWithFilter,
-		arg.Deleted,
-		arg.OrganizationID,
-		arg.ExactName,
-		pq.Array(arg.IDs),
-		arg.Deprecated,
+func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
+		arg.TemplateID,
+		arg.Archived,
+		arg.AfterID,
+		arg.OffsetOpt,
+		arg.LimitOpt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Template
+	var items []TemplateVersion
 	for rows.Next() {
-		var i Template
+		var i TemplateVersion
 		if err := rows.Scan(
 			&i.ID,
+			&i.TemplateID,
+			&i.OrganizationID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Name,
+			&i.Readme,
+			&i.JobID,
+			&i.CreatedBy,
+			pq.Array(&i.ExternalAuthProviders),
+			&i.Message,
+			&i.Archived,
+			&i.GitCommitSha,
+			&i.GitBranch,
+			&i.GitTag,
+			&i.CreatedByAvatarURL,
+			&i.CreatedByUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
+SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, git_commit_sha, git_branch, git_tag, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersion
+	for rows.Next() {
+		var i TemplateVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
 			&i.OrganizationID,
-			&i.Deleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 			&i.Name,
-			&i.Provisioner,
-			&i.ActiveVersionID,
-			&i.Description,
-			&i.DefaultTTL,
+			&i.Readme,
+			&i.JobID,
 			&i.CreatedBy,
-			&i.Icon,
-			&i.UserACL,
-			&i.GroupACL,
-			&i.DisplayName,
-			&i.AllowUserCancelWorkspaceJobs,
-			&i.MaxTTL,
-			&i.AllowUserAutostart,
-			&i.AllowUserAutostop,
-			&i.FailureTTL,
-			&i.TimeTilDormant,
-			&i.TimeTilDormantAutoDelete,
-			&i.AutostopRequirementDaysOfWeek,
-			&i.AutostopRequirementWeeks,
-			&i.AutostartBlockDaysOfWeek,
-			&i.RequireActiveVersion,
-			&i.Deprecated,
-			&i.UseMaxTtl,
+			pq.Array(&i.ExternalAuthProviders),
+			&i.Message,
+			&i.Archived,
+			&i.GitCommitSha,
+			&i.GitBranch,
+			&i.GitTag,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -5924,275 +10688,178 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 	return items, nil
 }
 
-const insertTemplate = `-- name: InsertTemplate :exec
+const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
 INSERT INTO
-	templates (
+	template_versions (
 		id,
+		template_id,
+		organization_id,
 		created_at,
 		updated_at,
-		organization_id,
 		"name",
-		provisioner,
-		active_version_id,
-		description,
+		message,
+		readme,
+		job_id,
 		created_by,
-		icon,
-		user_acl,
-		group_acl,
-		display_name,
-		allow_user_cancel_workspace_jobs
+		git_commit_sha,
+		git_branch,
+		git_tag
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 `
 
-type InsertTemplateParams struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Name                         string          `db:"name" json:"name"`
-	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                  string          `db:"description" json:"description"`
-	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                         string          `db:"icon" json:"icon"`
-	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+type InsertTemplateVersionParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+	Name           string        `db:"name" json:"name"`
+	Message        string        `db:"message" json:"message"`
+	Readme         string        `db:"readme" json:"readme"`
+	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
+	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+	GitCommitSha   string        `db:"git_commit_sha" json:"git_commit_sha"`
+	GitBranch      string        `db:"git_branch" json:"git_branch"`
+	GitTag         string        `db:"git_tag" json:"git_tag"`
 }
 
-func (q *sqlQuerier) InsertTemplate(ctx context.Context, arg InsertTemplateParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplate,
+func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
 		arg.ID,
+		arg.TemplateID,
+		arg.OrganizationID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
-		arg.OrganizationID,
 		arg.Name,
-		arg.Provisioner,
-		arg.ActiveVersionID,
-		arg.Description,
+		arg.Message,
+		arg.Readme,
+		arg.JobID,
 		arg.CreatedBy,
-		arg.Icon,
-		arg.UserACL,
-		arg.GroupACL,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
+		arg.GitCommitSha,
+		arg.GitBranch,
+		arg.GitTag,
 	)
 	return err
 }
 
-const updateTemplateACLByID = `-- name: UpdateTemplateACLByID :exec
-UPDATE
-	templates
-SET
-	group_acl = $1,
-	user_acl = $2
-WHERE
-	id = $3
-`
-
-type UpdateTemplateACLByIDParams struct {
-	GroupACL TemplateACL `db:"group_acl" json:"group_acl"`
-	UserACL  TemplateACL `db:"user_acl" json:"user_acl"`
-	ID       uuid.UUID   `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateACLByID, arg.GroupACL, arg.UserACL, arg.ID)
-	return err
-}
-
-const updateTemplateAccessControlByID = `-- name: UpdateTemplateAccessControlByID :exec
+const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
 UPDATE
-	templates
+	template_versions
 SET
-	require_active_version = $2,
-	deprecated = $3
+	archived = false,
+	updated_at = $1
 WHERE
-	id = $1
+		id = $2
 `
 
-type UpdateTemplateAccessControlByIDParams struct {
-	ID                   uuid.UUID `db:"id" json:"id"`
-	RequireActiveVersion bool      `db:"require_active_version" json:"require_active_version"`
-	Deprecated           string    `db:"deprecated" json:"deprecated"`
+type UnarchiveTemplateVersionParams struct {
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 }
 
-func (q *sqlQuerier) UpdateTemplateAccessControlByID(ctx context.Context, arg UpdateTemplateAccessControlByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateAccessControlByID, arg.ID, arg.RequireActiveVersion, arg.Deprecated)
+// This will always work regardless of the current state of the template version.
+func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
+	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
 	return err
 }
 
-const updateTemplateActiveVersionByID = `-- name: UpdateTemplateActiveVersionByID :exec
+const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
 UPDATE
-	templates
+	template_versions
 SET
-	active_version_id = $2,
-	updated_at = $3
+	template_id = $2,
+	updated_at = $3,
+	name = $4,
+	message = $5
 WHERE
 	id = $1
 `
 
-type UpdateTemplateActiveVersionByIDParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	ActiveVersionID uuid.UUID `db:"active_version_id" json:"active_version_id"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+type UpdateTemplateVersionByIDParams struct {
+	ID         uuid.UUID     `db:"id" json:"id"`
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+	Name       string        `db:"name" json:"name"`
+	Message    string        `db:"message" json:"message"`
 }
 
-func (q *sqlQuerier) UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateActiveVersionByID, arg.ID, arg.ActiveVersionID, arg.UpdatedAt)
+func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
+		arg.ID,
+		arg.TemplateID,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Message,
+	)
 	return err
 }
 
-const updateTemplateDeletedByID = `-- name: UpdateTemplateDeletedByID :exec
+const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
 UPDATE
-	templates
+	template_versions
 SET
-	deleted = $2,
+	readme = $2,
 	updated_at = $3
 WHERE
-	id = $1
+	job_id = $1
 `
 
-type UpdateTemplateDeletedByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Deleted   bool      `db:"deleted" json:"deleted"`
+type UpdateTemplateVersionDescriptionByJobIDParams struct {
+	JobID     uuid.UUID `db:"job_id" json:"job_id"`
+	Readme    string    `db:"readme" json:"readme"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateDeletedByID, arg.ID, arg.Deleted, arg.UpdatedAt)
-	return err
-}
-
-const updateTemplateMetaByID = `-- name: UpdateTemplateMetaByID :exec
-UPDATE
-	templates
-SET
-	updated_at = $2,
-	description = $3,
-	name = $4,
-	icon = $5,
-	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7,
-	group_acl = $8
-WHERE
-	id = $1
-`
-
-type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID   `db:"id" json:"id"`
-	UpdatedAt                    time.Time   `db:"updated_at" json:"updated_at"`
-	Description                  string      `db:"description" json:"description"`
-	Name                         string      `db:"name" json:"name"`
-	Icon                         string      `db:"icon" json:"icon"`
-	DisplayName                  string      `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool        `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	GroupACL                     TemplateACL `db:"group_acl" json:"group_acl"`
-}
-
-func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateMetaByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.Description,
-		arg.Name,
-		arg.Icon,
-		arg.DisplayName,
-		arg.AllowUserCancelWorkspaceJobs,
-		arg.GroupACL,
-	)
+func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
 	return err
 }
 
-const updateTemplateScheduleByID = `-- name: UpdateTemplateScheduleByID :exec
+const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
 UPDATE
-	templates
+	template_versions
 SET
-	updated_at = $2,
-	allow_user_autostart = $3,
-	allow_user_autostop = $4,
-	default_ttl = $5,
-	use_max_ttl = $6,
-	max_ttl = $7,
-	autostop_requirement_days_of_week = $8,
-	autostop_requirement_weeks = $9,
-	autostart_block_days_of_week = $10,
-	failure_ttl = $11,
-	time_til_dormant = $12,
-	time_til_dormant_autodelete = $13
+	external_auth_providers = $2,
+	updated_at = $3
 WHERE
-	id = $1
+	job_id = $1
 `
 
-type UpdateTemplateScheduleByIDParams struct {
-	ID                            uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                     time.Time `db:"updated_at" json:"updated_at"`
-	AllowUserAutostart            bool      `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop             bool      `db:"allow_user_autostop" json:"allow_user_autostop"`
-	DefaultTTL                    int64     `db:"default_ttl" json:"default_ttl"`
-	UseMaxTtl                     bool      `db:"use_max_ttl" json:"use_max_ttl"`
-	MaxTTL                        int64     `db:"max_ttl" json:"max_ttl"`
-	AutostopRequirementDaysOfWeek int16     `db:"autostop_requirement_days_of_week" json:"autostop_requirement_days_of_week"`
-	AutostopRequirementWeeks      int64     `db:"autostop_requirement_weeks" json:"autostop_requirement_weeks"`
-	AutostartBlockDaysOfWeek      int16     `db:"autostart_block_days_of_week" json:"autostart_block_days_of_week"`
-	FailureTTL                    int64     `db:"failure_ttl" json:"failure_ttl"`
-	TimeTilDormant                int64     `db:"time_til_dormant" json:"time_til_dormant"`
-	TimeTilDormantAutoDelete      int64     `db:"time_til_dormant_autodelete" json:"time_til_dormant_autodelete"`
-}
-
-func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateScheduleByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.AllowUserAutostart,
-		arg.AllowUserAutostop,
-		arg.DefaultTTL,
-		arg.UseMaxTtl,
-		arg.MaxTTL,
-		arg.AutostopRequirementDaysOfWeek,
-		arg.AutostopRequirementWeeks,
-		arg.AutostartBlockDaysOfWeek,
-		arg.FailureTTL,
-		arg.TimeTilDormant,
-		arg.TimeTilDormantAutoDelete,
-	)
+type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
+	JobID                 uuid.UUID `db:"job_id" json:"job_id"`
+	ExternalAuthProviders []string  `db:"external_auth_providers" json:"external_auth_providers"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, pq.Array(arg.ExternalAuthProviders), arg.UpdatedAt)
 	return err
 }
 
-const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
+SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
 `
 
-func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionParameters, templateVersionID)
+func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersionParameter
+	var items []TemplateVersionVariable
 	for rows.Next() {
-		var i TemplateVersionParameter
+		var i TemplateVersionVariable
 		if err := rows.Scan(
 			&i.TemplateVersionID,
 			&i.Name,
 			&i.Description,
 			&i.Type,
-			&i.Mutable,
+			&i.Value,
 			&i.DefaultValue,
-			&i.Icon,
-			&i.Options,
-			&i.ValidationRegex,
-			&i.ValidationMin,
-			&i.ValidationMax,
-			&i.ValidationError,
-			&i.ValidationMonotonic,
 			&i.Required,
-			&i.DisplayName,
-			&i.DisplayOrder,
-			&i.Ephemeral,
+			&i.Sensitive,
 		); err != nil {
 			return nil, err
 		}
@@ -6207,26 +10874,17 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 	return items, nil
 }
 
-const insertTemplateVersionParameter = `-- name: InsertTemplateVersionParameter :one
+const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
 INSERT INTO
-    template_version_parameters (
+    template_version_variables (
         template_version_id,
         name,
         description,
         type,
-        mutable,
+        value,
         default_value,
-        icon,
-        options,
-        validation_regex,
-        validation_min,
-        validation_max,
-        validation_error,
-        validation_monotonic,
         required,
-        display_name,
-        display_order,
-        ephemeral
+        sensitive
     )
 VALUES
     (
@@ -6237,186 +10895,215 @@ VALUES
         $5,
         $6,
         $7,
-        $8,
-        $9,
-        $10,
-        $11,
-        $12,
-        $13,
-        $14,
-        $15,
-        $16,
-        $17
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral
+        $8
+    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
 `
 
-type InsertTemplateVersionParameterParams struct {
-	TemplateVersionID   uuid.UUID       `db:"template_version_id" json:"template_version_id"`
-	Name                string          `db:"name" json:"name"`
-	Description         string          `db:"description" json:"description"`
-	Type                string          `db:"type" json:"type"`
-	Mutable             bool            `db:"mutable" json:"mutable"`
-	DefaultValue        string          `db:"default_value" json:"default_value"`
-	Icon                string          `db:"icon" json:"icon"`
-	Options             json.RawMessage `db:"options" json:"options"`
-	ValidationRegex     string          `db:"validation_regex" json:"validation_regex"`
-	ValidationMin       sql.NullInt32   `db:"validation_min" json:"validation_min"`
-	ValidationMax       sql.NullInt32   `db:"validation_max" json:"validation_max"`
-	ValidationError     string          `db:"validation_error" json:"validation_error"`
-	ValidationMonotonic string          `db:"validation_monotonic" json:"validation_monotonic"`
-	Required            bool            `db:"required" json:"required"`
-	DisplayName         string          `db:"display_name" json:"display_name"`
-	DisplayOrder        int32           `db:"display_order" json:"display_order"`
-	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+type InsertTemplateVersionVariableParams struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	Name              string    `db:"name" json:"name"`
+	Description       string    `db:"description" json:"description"`
+	Type              string    `db:"type" json:"type"`
+	Value             string    `db:"value" json:"value"`
+	DefaultValue      string    `db:"default_value" json:"default_value"`
+	Required          bool      `db:"required" json:"required"`
+	Sensitive         bool      `db:"sensitive" json:"sensitive"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
 		arg.TemplateVersionID,
 		arg.Name,
 		arg.Description,
 		arg.Type,
-		arg.Mutable,
+		arg.Value,
 		arg.DefaultValue,
-		arg.Icon,
-		arg.Options,
-		arg.ValidationRegex,
-		arg.ValidationMin,
-		arg.ValidationMax,
-		arg.ValidationError,
-		arg.ValidationMonotonic,
 		arg.Required,
-		arg.DisplayName,
-		arg.DisplayOrder,
-		arg.Ephemeral,
+		arg.Sensitive,
 	)
-	var i TemplateVersionParameter
+	var i TemplateVersionVariable
 	err := row.Scan(
 		&i.TemplateVersionID,
 		&i.Name,
 		&i.Description,
 		&i.Type,
-		&i.Mutable,
+		&i.Value,
 		&i.DefaultValue,
-		&i.Icon,
-		&i.Options,
-		&i.ValidationRegex,
-		&i.ValidationMin,
-		&i.ValidationMax,
-		&i.ValidationError,
-		&i.ValidationMonotonic,
 		&i.Required,
-		&i.DisplayName,
-		&i.DisplayOrder,
-		&i.Ephemeral,
+		&i.Sensitive,
 	)
 	return i, err
 }
 
-const archiveUnusedTemplateVersions = `-- name: ArchiveUnusedTemplateVersions :many
-UPDATE
-	template_versions
-SET
-	archived = true,
-	updated_at = $1
+const countUnusedUserRecoveryCodes = `-- name: CountUnusedUserRecoveryCodes :one
+SELECT
+	COUNT(*)
 FROM
-	-- Archive all versions that are returned from this query.
-	(
-		SELECT
-			scoped_template_versions.id
-		FROM
-			-- Scope an archive to a single template and ignore already archived template versions
-			(
-				SELECT
-					id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived
-				FROM
-					template_versions
-				WHERE
-					template_versions.template_id = $2 :: uuid
-					AND
-					archived = false
-					AND
-					-- This allows archiving a specific template version.
-					CASE
-						WHEN $3::uuid  != '00000000-0000-0000-0000-000000000000'::uuid THEN
-							template_versions.id = $3 :: uuid
-						ELSE
-							true
-						END
-			) AS scoped_template_versions
-			LEFT JOIN
-				provisioner_jobs ON scoped_template_versions.job_id = provisioner_jobs.id
-			LEFT JOIN
-				templates ON scoped_template_versions.template_id = templates.id
-		WHERE
-		  -- Actively used template versions (meaning the latest build is using
-		  -- the version) are never archived. A "restart" command on the workspace,
-		  -- even if failed, would use the version. So it cannot be archived until
-		  -- the build is outdated.
-		NOT EXISTS (
-			-- Return all "used" versions, where "used" is defined as being
-			-- used by a latest workspace build.
-			SELECT template_version_id FROM (
-				SELECT
-					DISTINCT ON (workspace_id) template_version_id, transition
-				FROM
-					workspace_builds
-				ORDER BY workspace_id, build_number DESC
-				) AS used_versions
-			WHERE
-				used_versions.transition != 'delete'
-				AND
-				scoped_template_versions.id = used_versions.template_version_id
-		)
-		-- Also never archive the active template version
-		AND active_version_id != scoped_template_versions.id
-		AND CASE
-			-- Optionally, only archive versions that match a given
-			-- job status like 'failed'.
-			WHEN $4 :: provisioner_job_status IS NOT NULL THEN
-				provisioner_jobs.job_status = $4 :: provisioner_job_status
-			ELSE
-				true
-		END
-		-- Pending or running jobs should not be archived, as they are "in progress"
-		AND provisioner_jobs.job_status != 'running'
-		AND provisioner_jobs.job_status != 'pending'
-	) AS archived_versions
+	user_recovery_codes
 WHERE
-	template_versions.id IN (archived_versions.id)
-RETURNING template_versions.id
+	user_id = $1
+	AND used_at IS NULL
 `
 
-type ArchiveUnusedTemplateVersionsParams struct {
-	UpdatedAt         time.Time                `db:"updated_at" json:"updated_at"`
-	TemplateID        uuid.UUID                `db:"template_id" json:"template_id"`
-	TemplateVersionID uuid.UUID                `db:"template_version_id" json:"template_version_id"`
-	JobStatus         NullProvisionerJobStatus `db:"job_status" json:"job_status"`
+func (q *sqlQuerier) CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnusedUserRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteExpiredTwoFactorChallenges = `-- name: DeleteExpiredTwoFactorChallenges :exec
+DELETE FROM two_factor_challenges WHERE expires_at < $1
+`
+
+func (q *sqlQuerier) DeleteExpiredTwoFactorChallenges(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredTwoFactorChallenges, beforeTime)
+	return err
+}
+
+const deletePasskeyByID = `-- name: DeletePasskeyByID :exec
+DELETE FROM passkeys WHERE id = $1
+`
+
+func (q *sqlQuerier) DeletePasskeyByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePasskeyByID, id)
+	return err
+}
+
+const deleteTwoFactorChallengeByID = `-- name: DeleteTwoFactorChallengeByID :one
+DELETE FROM two_factor_challenges WHERE id = $1 RETURNING id, user_id, purpose, challenge, created_at, expires_at
+`
+
+// Challenges are deleted when they are answered, so that each challenge can
+// only be used once.
+func (q *sqlQuerier) DeleteTwoFactorChallengeByID(ctx context.Context, id uuid.UUID) (TwoFactorChallenge, error) {
+	row := q.db.QueryRowContext(ctx, deleteTwoFactorChallengeByID, id)
+	var i TwoFactorChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.Challenge,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteTwoFactorPolicyByRole = `-- name: DeleteTwoFactorPolicyByRole :exec
+DELETE FROM two_factor_policies WHERE role = $1
+`
+
+func (q *sqlQuerier) DeleteTwoFactorPolicyByRole(ctx context.Context, role string) error {
+	_, err := q.db.ExecContext(ctx, deleteTwoFactorPolicyByRole, role)
+	return err
+}
+
+const deleteUserRecoveryCodesByUserID = `-- name: DeleteUserRecoveryCodesByUserID :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserRecoveryCodesByUserID(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserRecoveryCodesByUserID, userID)
+	return err
+}
+
+const getPasskeyByID = `-- name: GetPasskeyByID :one
+SELECT
+	id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM
+	passkeys
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetPasskeyByID(ctx context.Context, id uuid.UUID) (Passkey, error) {
+	row := q.db.QueryRowContext(ctx, getPasskeyByID, id)
+	var i Passkey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const getPasskeysByUserID = `-- name: GetPasskeysByUserID :many
+SELECT
+	id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+FROM
+	passkeys
+WHERE
+	user_id = $1
+ORDER BY
+	created_at, id
+`
+
+func (q *sqlQuerier) GetPasskeysByUserID(ctx context.Context, userID uuid.UUID) ([]Passkey, error) {
+	rows, err := q.db.QueryContext(ctx, getPasskeysByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Passkey
+	for rows.Next() {
+		var i Passkey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-// Archiving templates is a soft delete action, so is reversible.
-// Archiving prevents the version from being used and discovered
-// by listing.
-// Only unused template versions will be archived, which are any versions not
-// referenced by the latest build of a workspace.
-func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg ArchiveUnusedTemplateVersionsParams) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, archiveUnusedTemplateVersions,
-		arg.UpdatedAt,
-		arg.TemplateID,
-		arg.TemplateVersionID,
-		arg.JobStatus,
-	)
+const getTwoFactorPolicies = `-- name: GetTwoFactorPolicies :many
+SELECT
+	id, role, enforce_after, created_at, updated_at
+FROM
+	two_factor_policies
+ORDER BY
+	role
+`
+
+func (q *sqlQuerier) GetTwoFactorPolicies(ctx context.Context) ([]TwoFactorPolicy, error) {
+	rows, err := q.db.QueryContext(ctx, getTwoFactorPolicies)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []uuid.UUID
+	var items []TwoFactorPolicy
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
+		var i TwoFactorPolicy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Role,
+			&i.EnforceAfter,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -6427,183 +11114,344 @@ func (q *sqlQuerier) ArchiveUnusedTemplateVersions(ctx context.Context, arg Arch
 	return items, nil
 }
 
-const getPreviousTemplateVersion = `-- name: GetPreviousTemplateVersion :one
+const getTwoFactorPolicyByRole = `-- name: GetTwoFactorPolicyByRole :one
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, role, enforce_after, created_at, updated_at
 FROM
-	template_version_with_user AS template_versions
+	two_factor_policies
 WHERE
-	created_at < (
-		SELECT created_at
-		FROM template_version_with_user AS tv
-		WHERE tv.organization_id = $1 AND tv.name = $2 AND tv.template_id = $3
-	)
-	AND organization_id = $1
-	AND template_id = $3
-ORDER BY created_at DESC
-LIMIT 1
+	role = $1
 `
 
-type GetPreviousTemplateVersionParams struct {
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	Name           string        `db:"name" json:"name"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
-}
-
-func (q *sqlQuerier) GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getPreviousTemplateVersion, arg.OrganizationID, arg.Name, arg.TemplateID)
-	var i TemplateVersion
+func (q *sqlQuerier) GetTwoFactorPolicyByRole(ctx context.Context, role string) (TwoFactorPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getTwoFactorPolicyByRole, role)
+	var i TwoFactorPolicy
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
+		&i.Role,
+		&i.EnforceAfter,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.ExternalAuthProviders),
-		&i.Message,
-		&i.Archived,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplateVersionByID = `-- name: GetTemplateVersionByID :one
+const getUnusedUserRecoveryCodes = `-- name: GetUnusedUserRecoveryCodes :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	id, user_id, hashed_code, created_at, used_at
 FROM
-	template_version_with_user AS template_versions
+	user_recovery_codes
 WHERE
-	id = $1
+	user_id = $1
+	AND used_at IS NULL
+ORDER BY
+	created_at, id
 `
 
-func (q *sqlQuerier) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByID, id)
-	var i TemplateVersion
+// Recovery codes are salted, so they can't be looked up by their hash. The
+// code a user enters is compared with each of their unused codes instead.
+func (q *sqlQuerier) GetUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]UserRecoveryCode, error) {
+	rows, err := q.db.QueryContext(ctx, getUnusedUserRecoveryCodes, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRecoveryCode
+	for rows.Next() {
+		var i UserRecoveryCode
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.HashedCode,
+			&i.CreatedAt,
+			&i.UsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertPasskey = `-- name: InsertPasskey :one
+INSERT INTO
+	passkeys (id, user_id, name, credential_id, public_key, sign_count, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at
+`
+
+type InsertPasskeyParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	Name         string    `db:"name" json:"name"`
+	CredentialID []byte    `db:"credential_id" json:"credential_id"`
+	PublicKey    []byte    `db:"public_key" json:"public_key"`
+	SignCount    int64     `db:"sign_count" json:"sign_count"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertPasskey(ctx context.Context, arg InsertPasskeyParams) (Passkey, error) {
+	row := q.db.QueryRowContext(ctx, insertPasskey,
+		arg.ID,
+		arg.UserID,
+		arg.Name,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.CreatedAt,
+	)
+	var i Passkey
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
+		&i.UserID,
 		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.ExternalAuthProviders),
-		&i.Message,
-		&i.Archived,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
-const getTemplateVersionByJobID = `-- name: GetTemplateVersionByJobID :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
+const insertTwoFactorChallenge = `-- name: InsertTwoFactorChallenge :one
+INSERT INTO
+	two_factor_challenges (id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, purpose, challenge, created_at, expires_at
+`
+
+type InsertTwoFactorChallengeParams struct {
+	ID        uuid.UUID                 `db:"id" json:"id"`
+	UserID    uuid.UUID                 `db:"user_id" json:"user_id"`
+	Purpose   TwoFactorChallengePurpose `db:"purpose" json:"purpose"`
+	Challenge []byte                    `db:"challenge" json:"challenge"`
+	CreatedAt time.Time                 `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time                 `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) InsertTwoFactorChallenge(ctx context.Context, arg InsertTwoFactorChallengeParams) (TwoFactorChallenge, error) {
+	row := q.db.QueryRowContext(ctx, insertTwoFactorChallenge,
+		arg.ID,
+		arg.UserID,
+		arg.Purpose,
+		arg.Challenge,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	var i TwoFactorChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Purpose,
+		&i.Challenge,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const insertUserRecoveryCode = `-- name: InsertUserRecoveryCode :exec
+INSERT INTO
+	user_recovery_codes (id, user_id, hashed_code, created_at)
+VALUES
+	($1, $2, $3, $4)
+`
+
+type InsertUserRecoveryCodeParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	HashedCode []byte    `db:"hashed_code" json:"hashed_code"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertUserRecoveryCode(ctx context.Context, arg InsertUserRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserRecoveryCode,
+		arg.ID,
+		arg.UserID,
+		arg.HashedCode,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const updatePasskeyUsage = `-- name: UpdatePasskeyUsage :exec
+UPDATE
+	passkeys
+SET
+	sign_count = $2,
+	last_used_at = $3
 WHERE
-	job_id = $1
+	id = $1
 `
 
-func (q *sqlQuerier) GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByJobID, jobID)
-	var i TemplateVersion
+type UpdatePasskeyUsageParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	SignCount  int64        `db:"sign_count" json:"sign_count"`
+	LastUsedAt sql.NullTime `db:"last_used_at" json:"last_used_at"`
+}
+
+func (q *sqlQuerier) UpdatePasskeyUsage(ctx context.Context, arg UpdatePasskeyUsageParams) error {
+	_, err := q.db.ExecContext(ctx, updatePasskeyUsage, arg.ID, arg.SignCount, arg.LastUsedAt)
+	return err
+}
+
+const upsertTwoFactorPolicy = `-- name: UpsertTwoFactorPolicy :one
+INSERT INTO
+	two_factor_policies (id, role, enforce_after, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (role) DO UPDATE SET
+	enforce_after = $3,
+	updated_at = $5
+RETURNING id, role, enforce_after, created_at, updated_at
+`
+
+type UpsertTwoFactorPolicyParams struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	Role         string    `db:"role" json:"role"`
+	EnforceAfter time.Time `db:"enforce_after" json:"enforce_after"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertTwoFactorPolicy(ctx context.Context, arg UpsertTwoFactorPolicyParams) (TwoFactorPolicy, error) {
+	row := q.db.QueryRowContext(ctx, upsertTwoFactorPolicy,
+		arg.ID,
+		arg.Role,
+		arg.EnforceAfter,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i TwoFactorPolicy
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
+		&i.Role,
+		&i.EnforceAfter,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.ExternalAuthProviders),
-		&i.Message,
-		&i.Archived,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
 	)
 	return i, err
 }
 
-const getTemplateVersionByTemplateIDAndName = `-- name: GetTemplateVersionByTemplateIDAndName :one
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
+const useUserRecoveryCode = `-- name: UseUserRecoveryCode :one
+UPDATE
+	user_recovery_codes
+SET
+	used_at = $1
 WHERE
-	template_id = $1
-	AND "name" = $2
+	id = $2
+	AND user_id = $3
+	AND used_at IS NULL
+RETURNING id, user_id, hashed_code, created_at, used_at
 `
 
-type GetTemplateVersionByTemplateIDAndNameParams struct {
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	Name       string        `db:"name" json:"name"`
+type UseUserRecoveryCodeParams struct {
+	UsedAt sql.NullTime `db:"used_at" json:"used_at"`
+	ID     uuid.UUID    `db:"id" json:"id"`
+	UserID uuid.UUID    `db:"user_id" json:"user_id"`
 }
 
-func (q *sqlQuerier) GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error) {
-	row := q.db.QueryRowContext(ctx, getTemplateVersionByTemplateIDAndName, arg.TemplateID, arg.Name)
-	var i TemplateVersion
+// Marks an unused recovery code of the user as used. No rows are returned if
+// the user has no such code, or if it was already used.
+func (q *sqlQuerier) UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (UserRecoveryCode, error) {
+	row := q.db.QueryRowContext(ctx, useUserRecoveryCode, arg.UsedAt, arg.ID, arg.UserID)
+	var i UserRecoveryCode
 	err := row.Scan(
 		&i.ID,
-		&i.TemplateID,
-		&i.OrganizationID,
+		&i.UserID,
+		&i.HashedCode,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Name,
-		&i.Readme,
-		&i.JobID,
-		&i.CreatedBy,
-		pq.Array(&i.ExternalAuthProviders),
-		&i.Message,
-		&i.Archived,
-		&i.CreatedByAvatarURL,
-		&i.CreatedByUsername,
+		&i.UsedAt,
 	)
 	return i, err
 }
 
-const getTemplateVersionsByIDs = `-- name: GetTemplateVersionsByIDs :many
+const deleteOldUserActivity = `-- name: DeleteOldUserActivity :exec
+DELETE FROM user_activity WHERE hour < $1
+`
+
+func (q *sqlQuerier) DeleteOldUserActivity(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldUserActivity, beforeTime)
+	return err
+}
+
+const getInactiveUsers = `-- name: GetInactiveUsers :many
 SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
+	users.id,
+	users.username,
+	users.email,
+	users.avatar_url,
+	users.status,
+	users.last_seen_at,
+	last_activity.category AS last_activity_category,
+	last_activity.workspace_id AS last_activity_workspace_id,
+	last_activity.hour AS last_activity_hour
 FROM
-	template_version_with_user AS template_versions
+	users
+LEFT JOIN LATERAL (
+	SELECT
+		category, workspace_id, hour
+	FROM
+		user_activity
+	WHERE
+		user_activity.user_id = users.id
+	ORDER BY
+		hour DESC, count DESC
+	LIMIT 1
+) AS last_activity ON true
 WHERE
-	id = ANY($1 :: uuid [ ])
-`
-
-func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByIDs, pq.Array(ids))
+	users.deleted = false
+	AND users.status != 'suspended'::user_status
+	AND users.last_seen_at < $1 :: timestamp
+	AND (users.last_seen_at != '0001-01-01 00:00:00' :: timestamp OR users.created_at < $1)
+ORDER BY
+	users.last_seen_at ASC, users.username ASC
+`
+
+type GetInactiveUsersRow struct {
+	ID                      uuid.UUID      `db:"id" json:"id"`
+	Username                string         `db:"username" json:"username"`
+	Email                   string         `db:"email" json:"email"`
+	AvatarURL               string         `db:"avatar_url" json:"avatar_url"`
+	Status                  UserStatus     `db:"status" json:"status"`
+	LastSeenAt              time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	LastActivityCategory    sql.NullString `db:"last_activity_category" json:"last_activity_category"`
+	LastActivityWorkspaceID uuid.NullUUID  `db:"last_activity_workspace_id" json:"last_activity_workspace_id"`
+	LastActivityHour        sql.NullTime   `db:"last_activity_hour" json:"last_activity_hour"`
+}
+
+// Returns the users that have not used Coder since the given time, least
+// recently seen first, with their most recent recorded activity. Users that
+// have never been seen are only inactive if they were created before then, and
+// suspended users are left out.
+func (q *sqlQuerier) GetInactiveUsers(ctx context.Context, inactiveSince time.Time) ([]GetInactiveUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getInactiveUsers, inactiveSince)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []GetInactiveUsersRow
 	for rows.Next() {
-		var i TemplateVersion
+		var i GetInactiveUsersRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.ExternalAuthProviders),
-			&i.Message,
-			&i.Archived,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
+			&i.Username,
+			&i.Email,
+			&i.AvatarURL,
+			&i.Status,
+			&i.LastSeenAt,
+			&i.LastActivityCategory,
+			&i.LastActivityWorkspaceID,
+			&i.LastActivityHour,
 		); err != nil {
 			return nil, err
 		}
@@ -6618,87 +11466,44 @@ func (q *sqlQuerier) GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UU
 	return items, nil
 }
 
-const getTemplateVersionsByTemplateID = `-- name: GetTemplateVersionsByTemplateID :many
-SELECT
-	id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username
-FROM
-	template_version_with_user AS template_versions
+const updateInactiveUsersToSuspended = `-- name: UpdateInactiveUsersToSuspended :many
+UPDATE
+	users
+SET
+	status = 'suspended'::user_status,
+	updated_at = $1
 WHERE
-	template_id = $1 :: uuid
-	  AND CASE
-		-- If no filter is provided, default to returning ALL template versions.
-		-- The called should always provide a filter if they want to omit
-		-- archived versions.
-		WHEN $2 :: boolean IS NULL THEN true
-		ELSE template_versions.archived = $2 :: boolean
-	END
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the created_at field, so select all
-			-- rows after the cursor.
-			(created_at, id) > (
-				SELECT
-					created_at, id
-				FROM
-					template_versions
-				WHERE
-					id = $3
-			)
-		)
-		ELSE true
-	END
-ORDER BY
-    -- Deterministic and consistent ordering of all rows, even if they share
-    -- a timestamp. This is to ensure consistent pagination.
-	(created_at, id) ASC OFFSET $4
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($5 :: int, 0)
+	last_seen_at < $2 :: timestamp
+	AND (last_seen_at != '0001-01-01 00:00:00' :: timestamp OR created_at < $2)
+	AND status != 'suspended'::user_status
+	AND deleted = false
+	AND NOT ('owner' = ANY(rbac_roles))
+RETURNING id, email, last_seen_at
 `
 
-type GetTemplateVersionsByTemplateIDParams struct {
-	TemplateID uuid.UUID    `db:"template_id" json:"template_id"`
-	Archived   sql.NullBool `db:"archived" json:"archived"`
-	AfterID    uuid.UUID    `db:"after_id" json:"after_id"`
-	OffsetOpt  int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt   int32        `db:"limit_opt" json:"limit_opt"`
+type UpdateInactiveUsersToSuspendedParams struct {
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	InactiveSince time.Time `db:"inactive_since" json:"inactive_since"`
 }
 
-func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsByTemplateID,
-		arg.TemplateID,
-		arg.Archived,
-		arg.AfterID,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+type UpdateInactiveUsersToSuspendedRow struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+// Suspends the users that have not used Coder since the given time. Owners are
+// never suspended, so the deployment can't be locked out.
+func (q *sqlQuerier) UpdateInactiveUsersToSuspended(ctx context.Context, arg UpdateInactiveUsersToSuspendedParams) ([]UpdateInactiveUsersToSuspendedRow, error) {
+	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToSuspended, arg.UpdatedAt, arg.InactiveSince)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []UpdateInactiveUsersToSuspendedRow
 	for rows.Next() {
-		var i TemplateVersion
-		if err := rows.Scan(
-			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.ExternalAuthProviders),
-			&i.Message,
-			&i.Archived,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
-		); err != nil {
+		var i UpdateInactiveUsersToSuspendedRow
+		if err := rows.Scan(&i.ID, &i.Email, &i.LastSeenAt); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -6712,34 +11517,122 @@ func (q *sqlQuerier) GetTemplateVersionsByTemplateID(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getTemplateVersionsCreatedAfter = `-- name: GetTemplateVersionsCreatedAfter :many
-SELECT id, template_id, organization_id, created_at, updated_at, name, readme, job_id, created_by, external_auth_providers, message, archived, created_by_avatar_url, created_by_username FROM template_version_with_user AS template_versions WHERE created_at > $1
+const upsertUserActivity = `-- name: UpsertUserActivity :exec
+INSERT INTO
+	user_activity (user_id, hour, category, workspace_id, count)
+SELECT
+	unnest($1 :: uuid [ ]) AS user_id,
+	unnest($2 :: timestamptz [ ]) AS hour,
+	unnest($3 :: text [ ]) AS category,
+	unnest($4 :: uuid [ ]) AS workspace_id,
+	unnest($5 :: integer [ ]) AS count
+ON CONFLICT
+	(user_id, hour, category, workspace_id)
+DO UPDATE SET
+	count = user_activity.count + EXCLUDED.count
 `
 
-func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, createdAt time.Time) ([]TemplateVersion, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionsCreatedAfter, createdAt)
+type UpsertUserActivityParams struct {
+	UserID      []uuid.UUID `db:"user_id" json:"user_id"`
+	Hour        []time.Time `db:"hour" json:"hour"`
+	Category    []string    `db:"category" json:"category"`
+	WorkspaceID []uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Count       []int32     `db:"count" json:"count"`
+}
+
+// Adds request counts to the hourly totals of each user, API category and
+// workspace. Every (user, hour, category, workspace) must appear at most once.
+func (q *sqlQuerier) UpsertUserActivity(ctx context.Context, arg UpsertUserActivityParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserActivity,
+		pq.Array(arg.UserID),
+		pq.Array(arg.Hour),
+		pq.Array(arg.Category),
+		pq.Array(arg.WorkspaceID),
+		pq.Array(arg.Count),
+	)
+	return err
+}
+
+const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+FROM
+	user_links
+WHERE
+	linked_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.DebugContext,
+	)
+	return i, err
+}
+
+const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
+SELECT
+	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+FROM
+	user_links
+WHERE
+	user_id = $1 AND login_type = $2
+`
+
+type GetUserLinkByUserIDLoginTypeParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
+}
+
+func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.DebugContext,
+	)
+	return i, err
+}
+
+const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
+SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context FROM user_links WHERE user_id = $1
+`
+
+func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
+	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []TemplateVersion
+	var items []UserLink
 	for rows.Next() {
-		var i TemplateVersion
+		var i UserLink
 		if err := rows.Scan(
-			&i.ID,
-			&i.TemplateID,
-			&i.OrganizationID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Name,
-			&i.Readme,
-			&i.JobID,
-			&i.CreatedBy,
-			pq.Array(&i.ExternalAuthProviders),
-			&i.Message,
-			&i.Archived,
-			&i.CreatedByAvatarURL,
-			&i.CreatedByUsername,
+			&i.UserID,
+			&i.LoginType,
+			&i.LinkedID,
+			&i.OAuthAccessToken,
+			&i.OAuthRefreshToken,
+			&i.OAuthExpiry,
+			&i.OAuthAccessTokenKeyID,
+			&i.OAuthRefreshTokenKeyID,
+			&i.DebugContext,
 		); err != nil {
 			return nil, err
 		}
@@ -6754,324 +11647,575 @@ func (q *sqlQuerier) GetTemplateVersionsCreatedAfter(ctx context.Context, create
 	return items, nil
 }
 
-const insertTemplateVersion = `-- name: InsertTemplateVersion :exec
+const insertUserLink = `-- name: InsertUserLink :one
 INSERT INTO
-	template_versions (
-		id,
-		template_id,
-		organization_id,
-		created_at,
-		updated_at,
-		"name",
-		message,
-		readme,
-		job_id,
-		created_by
+	user_links (
+		user_id,
+		login_type,
+		linked_id,
+		oauth_access_token,
+		oauth_access_token_key_id,
+		oauth_refresh_token,
+		oauth_refresh_token_key_id,
+		oauth_expiry,
+	    debug_context
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-`
-
-type InsertTemplateVersionParams struct {
-	ID             uuid.UUID     `db:"id" json:"id"`
-	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
-	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
-	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
-	Name           string        `db:"name" json:"name"`
-	Message        string        `db:"message" json:"message"`
-	Readme         string        `db:"readme" json:"readme"`
-	JobID          uuid.UUID     `db:"job_id" json:"job_id"`
-	CreatedBy      uuid.UUID     `db:"created_by" json:"created_by"`
+	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+`
+
+type InsertUserLinkParams struct {
+	UserID                 uuid.UUID       `db:"user_id" json:"user_id"`
+	LoginType              LoginType       `db:"login_type" json:"login_type"`
+	LinkedID               string          `db:"linked_id" json:"linked_id"`
+	OAuthAccessToken       string          `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString  `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string          `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString  `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time       `db:"oauth_expiry" json:"oauth_expiry"`
+	DebugContext           json.RawMessage `db:"debug_context" json:"debug_context"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, insertTemplateVersion,
-		arg.ID,
-		arg.TemplateID,
-		arg.OrganizationID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
-		arg.Readme,
-		arg.JobID,
-		arg.CreatedBy,
+func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, insertUserLink,
+		arg.UserID,
+		arg.LoginType,
+		arg.LinkedID,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.DebugContext,
 	)
-	return err
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.DebugContext,
+	)
+	return i, err
 }
 
-const unarchiveTemplateVersion = `-- name: UnarchiveTemplateVersion :exec
+const updateUserLink = `-- name: UpdateUserLink :one
 UPDATE
-	template_versions
+	user_links
 SET
-	archived = false,
-	updated_at = $1
+	oauth_access_token = $1,
+	oauth_access_token_key_id = $2,
+	oauth_refresh_token = $3,
+	oauth_refresh_token_key_id = $4,
+	oauth_expiry = $5,
+	debug_context = $6
 WHERE
-		id = $2
+	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
 `
 
-type UnarchiveTemplateVersionParams struct {
-	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+type UpdateUserLinkParams struct {
+	OAuthAccessToken       string          `db:"oauth_access_token" json:"oauth_access_token"`
+	OAuthAccessTokenKeyID  sql.NullString  `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
+	OAuthRefreshToken      string          `db:"oauth_refresh_token" json:"oauth_refresh_token"`
+	OAuthRefreshTokenKeyID sql.NullString  `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
+	OAuthExpiry            time.Time       `db:"oauth_expiry" json:"oauth_expiry"`
+	DebugContext           json.RawMessage `db:"debug_context" json:"debug_context"`
+	UserID                 uuid.UUID       `db:"user_id" json:"user_id"`
+	LoginType              LoginType       `db:"login_type" json:"login_type"`
 }
 
-// This will always work regardless of the current state of the template version.
-func (q *sqlQuerier) UnarchiveTemplateVersion(ctx context.Context, arg UnarchiveTemplateVersionParams) error {
-	_, err := q.db.ExecContext(ctx, unarchiveTemplateVersion, arg.UpdatedAt, arg.TemplateVersionID)
-	return err
+func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLink,
+		arg.OAuthAccessToken,
+		arg.OAuthAccessTokenKeyID,
+		arg.OAuthRefreshToken,
+		arg.OAuthRefreshTokenKeyID,
+		arg.OAuthExpiry,
+		arg.DebugContext,
+		arg.UserID,
+		arg.LoginType,
+	)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.DebugContext,
+	)
+	return i, err
 }
 
-const updateTemplateVersionByID = `-- name: UpdateTemplateVersionByID :exec
+const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
 UPDATE
-	template_versions
+	user_links
 SET
-	template_id = $2,
-	updated_at = $3,
-	name = $4,
-	message = $5
+	linked_id = $1
 WHERE
-	id = $1
+	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
 `
 
-type UpdateTemplateVersionByIDParams struct {
-	ID         uuid.UUID     `db:"id" json:"id"`
-	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
-	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
-	Name       string        `db:"name" json:"name"`
-	Message    string        `db:"message" json:"message"`
+type UpdateUserLinkedIDParams struct {
+	LinkedID  string    `db:"linked_id" json:"linked_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LoginType LoginType `db:"login_type" json:"login_type"`
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionByID,
-		arg.ID,
-		arg.TemplateID,
-		arg.UpdatedAt,
-		arg.Name,
-		arg.Message,
+func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
+	var i UserLink
+	err := row.Scan(
+		&i.UserID,
+		&i.LoginType,
+		&i.LinkedID,
+		&i.OAuthAccessToken,
+		&i.OAuthRefreshToken,
+		&i.OAuthExpiry,
+		&i.OAuthAccessTokenKeyID,
+		&i.OAuthRefreshTokenKeyID,
+		&i.DebugContext,
 	)
-	return err
+	return i, err
 }
 
-const updateTemplateVersionDescriptionByJobID = `-- name: UpdateTemplateVersionDescriptionByJobID :exec
-UPDATE
-	template_versions
-SET
-	readme = $2,
-	updated_at = $3
+const getUserSCIMExternalIDByExternalID = `-- name: GetUserSCIMExternalIDByExternalID :one
+SELECT
+	user_id, external_id
+FROM
+	user_scim_external_ids
 WHERE
-	job_id = $1
+	external_id = $1
 `
 
-type UpdateTemplateVersionDescriptionByJobIDParams struct {
-	JobID     uuid.UUID `db:"job_id" json:"job_id"`
-	Readme    string    `db:"readme" json:"readme"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+func (q *sqlQuerier) GetUserSCIMExternalIDByExternalID(ctx context.Context, externalID string) (UserSCIMExternalID, error) {
+	row := q.db.QueryRowContext(ctx, getUserSCIMExternalIDByExternalID, externalID)
+	var i UserSCIMExternalID
+	err := row.Scan(&i.UserID, &i.ExternalID)
+	return i, err
 }
 
-func (q *sqlQuerier) UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionDescriptionByJobID, arg.JobID, arg.Readme, arg.UpdatedAt)
-	return err
+const upsertUserSCIMExternalID = `-- name: UpsertUserSCIMExternalID :one
+INSERT INTO
+	user_scim_external_ids (user_id, external_id)
+VALUES
+	($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+	external_id = $2
+RETURNING user_id, external_id
+`
+
+type UpsertUserSCIMExternalIDParams struct {
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	ExternalID string    `db:"external_id" json:"external_id"`
 }
 
-const updateTemplateVersionExternalAuthProvidersByJobID = `-- name: UpdateTemplateVersionExternalAuthProvidersByJobID :exec
-UPDATE
-	template_versions
-SET
-	external_auth_providers = $2,
-	updated_at = $3
+func (q *sqlQuerier) UpsertUserSCIMExternalID(ctx context.Context, arg UpsertUserSCIMExternalIDParams) (UserSCIMExternalID, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserSCIMExternalID, arg.UserID, arg.ExternalID)
+	var i UserSCIMExternalID
+	err := row.Scan(&i.UserID, &i.ExternalID)
+	return i, err
+}
+
+const allUserIDs = `-- name: AllUserIDs :many
+SELECT DISTINCT id FROM USERS
+`
+
+// AllUserIDs returns all UserIDs regardless of user status or deletion.
+func (q *sqlQuerier) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, allUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveUserCount = `-- name: GetActiveUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	status = 'active'::user_status AND deleted = false
+`
+
+func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getActiveUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
+SELECT
+	-- username is returned just to help for logging purposes
+	-- status is used to enforce 'suspended' users, as all roles are ignored
+	--	when suspended.
+	id, username, status,
+	-- ip_allowlist limits the addresses the user's API keys may be used from.
+	ip_allowlist,
+	-- All user roles, including their org roles.
+	array_cat(
+		-- All users are members
+		array_append(users.rbac_roles, 'member'),
+		(
+			SELECT
+				array_agg(org_roles)
+			FROM
+				organization_members,
+				-- All org_members get the org-member role for their orgs
+				unnest(
+					array_append(roles, 'organization-member:' || organization_members.organization_id::text)
+				) AS org_roles
+			WHERE
+				user_id = users.id
+		)
+	) :: text[] AS roles,
+	-- All groups the user is in.
+	(
+		SELECT
+			array_agg(
+				group_members.group_id :: text
+			)
+		FROM
+			group_members
+		WHERE
+			user_id = users.id
+	) :: text[] AS groups
+FROM
+	users
 WHERE
-	job_id = $1
+	id = $1
 `
 
-type UpdateTemplateVersionExternalAuthProvidersByJobIDParams struct {
-	JobID                 uuid.UUID `db:"job_id" json:"job_id"`
-	ExternalAuthProviders []string  `db:"external_auth_providers" json:"external_auth_providers"`
-	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateTemplateVersionExternalAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionExternalAuthProvidersByJobIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateTemplateVersionExternalAuthProvidersByJobID, arg.JobID, pq.Array(arg.ExternalAuthProviders), arg.UpdatedAt)
-	return err
+type GetAuthorizationUserRolesRow struct {
+	ID          uuid.UUID  `db:"id" json:"id"`
+	Username    string     `db:"username" json:"username"`
+	Status      UserStatus `db:"status" json:"status"`
+	IPAllowlist []string   `db:"ip_allowlist" json:"ip_allowlist"`
+	Roles       []string   `db:"roles" json:"roles"`
+	Groups      []string   `db:"groups" json:"groups"`
 }
 
-const getTemplateVersionVariables = `-- name: GetTemplateVersionVariables :many
-SELECT template_version_id, name, description, type, value, default_value, required, sensitive FROM template_version_variables WHERE template_version_id = $1
-`
-
-func (q *sqlQuerier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateVersionVariables, templateVersionID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []TemplateVersionVariable
-	for rows.Next() {
-		var i TemplateVersionVariable
-		if err := rows.Scan(
-			&i.TemplateVersionID,
-			&i.Name,
-			&i.Description,
-			&i.Type,
-			&i.Value,
-			&i.DefaultValue,
-			&i.Required,
-			&i.Sensitive,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// This function returns roles for authorization purposes. Implied member roles
+// are included.
+func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
+	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
+	var i GetAuthorizationUserRolesRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Status,
+		pq.Array(&i.IPAllowlist),
+		pq.Array(&i.Roles),
+		pq.Array(&i.Groups),
+	)
+	return i, err
 }
 
-const insertTemplateVersionVariable = `-- name: InsertTemplateVersionVariable :one
-INSERT INTO
-    template_version_variables (
-        template_version_id,
-        name,
-        description,
-        type,
-        value,
-        default_value,
-        required,
-        sensitive
-    )
-VALUES
-    (
-        $1,
-        $2,
-        $3,
-        $4,
-        $5,
-        $6,
-        $7,
-        $8
-    ) RETURNING template_version_id, name, description, type, value, default_value, required, sensitive
+const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
+FROM
+	users
+WHERE
+	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
+	deleted = false
+LIMIT
+	1
 `
 
-type InsertTemplateVersionVariableParams struct {
-	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
-	Name              string    `db:"name" json:"name"`
-	Description       string    `db:"description" json:"description"`
-	Type              string    `db:"type" json:"type"`
-	Value             string    `db:"value" json:"value"`
-	DefaultValue      string    `db:"default_value" json:"default_value"`
-	Required          bool      `db:"required" json:"required"`
-	Sensitive         bool      `db:"sensitive" json:"sensitive"`
+type GetUserByEmailOrUsernameParams struct {
+	Username string `db:"username" json:"username"`
+	Email    string `db:"email" json:"email"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionVariable,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.Value,
-		arg.DefaultValue,
-		arg.Required,
-		arg.Sensitive,
-	)
-	var i TemplateVersionVariable
+func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+	var i User
 	err := row.Scan(
-		&i.TemplateVersionID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
 		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Value,
-		&i.DefaultValue,
-		&i.Required,
-		&i.Sensitive,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const getUserLinkByLinkedID = `-- name: GetUserLinkByLinkedID :one
+const getUserByID = `-- name: GetUserByID :one
 SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 FROM
-	user_links
+	users
 WHERE
-	linked_id = $1
+	id = $1
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByLinkedID, linkedID)
-	var i UserLink
+func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.DebugContext,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const getUserLinkByUserIDLoginType = `-- name: GetUserLinkByUserIDLoginType :one
-SELECT
-	user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
-FROM
-	user_links
-WHERE
-	user_id = $1 AND login_type = $2
+const getUserCount = `-- name: GetUserCount :one
+SELECT
+	COUNT(*)
+FROM
+	users
+WHERE
+	deleted = false
+`
+
+func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT
+	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist, COUNT(*) OVER() AS count
+FROM
+	users
+WHERE
+	users.deleted = false
+	AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the username field, so select all
+			-- rows after the cursor.
+			(LOWER(username)) > (
+				SELECT
+					LOWER(username)
+				FROM
+					users
+				WHERE
+					id = $1
+			)
+		)
+		ELSE true
+	END
+	-- Start filters
+	-- Filter by name, email or username
+	AND CASE
+		WHEN $2 :: text != '' THEN (
+			email ILIKE concat('%', $2, '%')
+			OR username ILIKE concat('%', $2, '%')
+		)
+		ELSE true
+	END
+	-- Filter by status
+	AND CASE
+		-- @status needs to be a text because it can be empty, If it was
+		-- user_status enum, it would not.
+		WHEN cardinality($3 :: user_status[]) > 0 THEN
+			status = ANY($3 :: user_status[])
+		ELSE true
+	END
+	-- Filter by rbac_roles
+	AND CASE
+		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
+		-- everyone is a member.
+		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
+			rbac_roles && $4 :: text[]
+		ELSE true
+	END
+	-- Filter by last_seen
+	AND CASE
+		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at <= $5
+		ELSE true
+	END
+	AND CASE
+		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			last_seen_at >= $6
+		ELSE true
+	END
+	-- End of filters
+
+	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
+	-- @authorize_filter
+ORDER BY
+	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
+	LOWER(username) ASC OFFSET $7
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($8 :: int, 0)
 `
 
-type GetUserLinkByUserIDLoginTypeParams struct {
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+type GetUsersParams struct {
+	AfterID        uuid.UUID    `db:"after_id" json:"after_id"`
+	Search         string       `db:"search" json:"search"`
+	Status         []UserStatus `db:"status" json:"status"`
+	RbacRole       []string     `db:"rbac_role" json:"rbac_role"`
+	LastSeenBefore time.Time    `db:"last_seen_before" json:"last_seen_before"`
+	LastSeenAfter  time.Time    `db:"last_seen_after" json:"last_seen_after"`
+	OffsetOpt      int32        `db:"offset_opt" json:"offset_opt"`
+	LimitOpt       int32        `db:"limit_opt" json:"limit_opt"`
 }
 
-func (q *sqlQuerier) GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, getUserLinkByUserIDLoginType, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.DebugContext,
+type GetUsersRow struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	Email              string         `db:"email" json:"email"`
+	Username           string         `db:"username" json:"username"`
+	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
+	Status             UserStatus     `db:"status" json:"status"`
+	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType          LoginType      `db:"login_type" json:"login_type"`
+	AvatarURL          string         `db:"avatar_url" json:"avatar_url"`
+	Deleted            bool           `db:"deleted" json:"deleted"`
+	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
+	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+	ThemePreference    string         `db:"theme_preference" json:"theme_preference"`
+	Name               string         `db:"name" json:"name"`
+	IPAllowlist        []string       `db:"ip_allowlist" json:"ip_allowlist"`
+	Count              int64          `db:"count" json:"count"`
+}
+
+// This will never return deleted users.
+func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers,
+		arg.AfterID,
+		arg.Search,
+		pq.Array(arg.Status),
+		pq.Array(arg.RbacRole),
+		arg.LastSeenBefore,
+		arg.LastSeenAfter,
+		arg.OffsetOpt,
+		arg.LimitOpt,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUsersRow
+	for rows.Next() {
+		var i GetUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.ThemePreference,
+			&i.Name,
+			pq.Array(&i.IPAllowlist),
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const getUserLinksByUserID = `-- name: GetUserLinksByUserID :many
-SELECT user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context FROM user_links WHERE user_id = $1
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist FROM users WHERE id = ANY($1 :: uuid [ ])
 `
 
-func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID) ([]UserLink, error) {
-	rows, err := q.db.QueryContext(ctx, getUserLinksByUserID, userID)
+// This shouldn't check for deleted, because it's frequently used
+// to look up references to actions. eg. a user could build a workspace
+// for another user, then be deleted... we still want them to appear!
+func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UserLink
+	var items []User
 	for rows.Next() {
-		var i UserLink
+		var i User
 		if err := rows.Scan(
-			&i.UserID,
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
 			&i.LoginType,
-			&i.LinkedID,
-			&i.OAuthAccessToken,
-			&i.OAuthRefreshToken,
-			&i.OAuthExpiry,
-			&i.OAuthAccessTokenKeyID,
-			&i.OAuthRefreshTokenKeyID,
-			&i.DebugContext,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+			&i.ThemePreference,
+			&i.Name,
+			pq.Array(&i.IPAllowlist),
 		); err != nil {
 			return nil, err
 		}
@@ -7086,163 +12230,102 @@ func (q *sqlQuerier) GetUserLinksByUserID(ctx context.Context, userID uuid.UUID)
 	return items, nil
 }
 
-const insertUserLink = `-- name: InsertUserLink :one
+const insertUser = `-- name: InsertUser :one
 INSERT INTO
-	user_links (
-		user_id,
-		login_type,
-		linked_id,
-		oauth_access_token,
-		oauth_access_token_key_id,
-		oauth_refresh_token,
-		oauth_refresh_token_key_id,
-		oauth_expiry,
-	    debug_context
+	users (
+		id,
+		email,
+		username,
+		hashed_password,
+		created_at,
+		updated_at,
+		rbac_roles,
+		login_type
 	)
 VALUES
-	( $1, $2, $3, $4, $5, $6, $7, $8, $9 ) RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
-`
-
-type InsertUserLinkParams struct {
-	UserID                 uuid.UUID       `db:"user_id" json:"user_id"`
-	LoginType              LoginType       `db:"login_type" json:"login_type"`
-	LinkedID               string          `db:"linked_id" json:"linked_id"`
-	OAuthAccessToken       string          `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString  `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string          `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString  `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time       `db:"oauth_expiry" json:"oauth_expiry"`
-	DebugContext           json.RawMessage `db:"debug_context" json:"debug_context"`
-}
-
-func (q *sqlQuerier) InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, insertUserLink,
-		arg.UserID,
-		arg.LoginType,
-		arg.LinkedID,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.DebugContext,
-	)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.DebugContext,
-	)
-	return i, err
-}
-
-const updateUserLink = `-- name: UpdateUserLink :one
-UPDATE
-	user_links
-SET
-	oauth_access_token = $1,
-	oauth_access_token_key_id = $2,
-	oauth_refresh_token = $3,
-	oauth_refresh_token_key_id = $4,
-	oauth_expiry = $5,
-	debug_context = $6
-WHERE
-	user_id = $7 AND login_type = $8 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-type UpdateUserLinkParams struct {
-	OAuthAccessToken       string          `db:"oauth_access_token" json:"oauth_access_token"`
-	OAuthAccessTokenKeyID  sql.NullString  `db:"oauth_access_token_key_id" json:"oauth_access_token_key_id"`
-	OAuthRefreshToken      string          `db:"oauth_refresh_token" json:"oauth_refresh_token"`
-	OAuthRefreshTokenKeyID sql.NullString  `db:"oauth_refresh_token_key_id" json:"oauth_refresh_token_key_id"`
-	OAuthExpiry            time.Time       `db:"oauth_expiry" json:"oauth_expiry"`
-	DebugContext           json.RawMessage `db:"debug_context" json:"debug_context"`
-	UserID                 uuid.UUID       `db:"user_id" json:"user_id"`
-	LoginType              LoginType       `db:"login_type" json:"login_type"`
+type InsertUserParams struct {
+	ID             uuid.UUID      `db:"id" json:"id"`
+	Email          string         `db:"email" json:"email"`
+	Username       string         `db:"username" json:"username"`
+	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
+	LoginType      LoginType      `db:"login_type" json:"login_type"`
 }
 
-func (q *sqlQuerier) UpdateUserLink(ctx context.Context, arg UpdateUserLinkParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLink,
-		arg.OAuthAccessToken,
-		arg.OAuthAccessTokenKeyID,
-		arg.OAuthRefreshToken,
-		arg.OAuthRefreshTokenKeyID,
-		arg.OAuthExpiry,
-		arg.DebugContext,
-		arg.UserID,
+func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, insertUser,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.HashedPassword,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.RBACRoles,
 		arg.LoginType,
 	)
-	var i UserLink
+	var i User
 	err := row.Scan(
-		&i.UserID,
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
 		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.DebugContext,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const updateUserLinkedID = `-- name: UpdateUserLinkedID :one
+const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
 UPDATE
-	user_links
+    users
 SET
-	linked_id = $1
+    status = 'dormant'::user_status,
+	updated_at = $1
 WHERE
-	user_id = $2 AND login_type = $3 RETURNING user_id, login_type, linked_id, oauth_access_token, oauth_refresh_token, oauth_expiry, oauth_access_token_key_id, oauth_refresh_token_key_id, debug_context
+    last_seen_at < $2 :: timestamp
+    AND status = 'active'::user_status
+RETURNING id, email, last_seen_at
 `
 
-type UpdateUserLinkedIDParams struct {
-	LinkedID  string    `db:"linked_id" json:"linked_id"`
-	UserID    uuid.UUID `db:"user_id" json:"user_id"`
-	LoginType LoginType `db:"login_type" json:"login_type"`
+type UpdateInactiveUsersToDormantParams struct {
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
 }
 
-func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinkedIDParams) (UserLink, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLinkedID, arg.LinkedID, arg.UserID, arg.LoginType)
-	var i UserLink
-	err := row.Scan(
-		&i.UserID,
-		&i.LoginType,
-		&i.LinkedID,
-		&i.OAuthAccessToken,
-		&i.OAuthRefreshToken,
-		&i.OAuthExpiry,
-		&i.OAuthAccessTokenKeyID,
-		&i.OAuthRefreshTokenKeyID,
-		&i.DebugContext,
-	)
-	return i, err
+type UpdateInactiveUsersToDormantRow struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
 }
 
-const allUserIDs = `-- name: AllUserIDs :many
-SELECT DISTINCT id FROM USERS
-`
-
-// AllUserIDs returns all UserIDs regardless of user status or deletion.
-func (q *sqlQuerier) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
-	rows, err := q.db.QueryContext(ctx, allUserIDs)
+func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
+	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []uuid.UUID
+	var items []UpdateInactiveUsersToDormantRow
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
+		var i UpdateInactiveUsersToDormantRow
+		if err := rows.Scan(&i.ID, &i.Email, &i.LastSeenAt); err != nil {
 			return nil, err
 		}
-		items = append(items, id)
+		items = append(items, i)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -7253,104 +12336,243 @@ func (q *sqlQuerier) AllUserIDs(ctx context.Context) ([]uuid.UUID, error) {
 	return items, nil
 }
 
-const getActiveUserCount = `-- name: GetActiveUserCount :one
-SELECT
-	COUNT(*)
-FROM
+const updateUserAppearanceSettings = `-- name: UpdateUserAppearanceSettings :one
+UPDATE
 	users
+SET
+	theme_preference = $2,
+	updated_at = $3
 WHERE
-	status = 'active'::user_status AND deleted = false
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-func (q *sqlQuerier) GetActiveUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getActiveUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type UpdateUserAppearanceSettingsParams struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
 }
 
-const getAuthorizationUserRoles = `-- name: GetAuthorizationUserRoles :one
-SELECT
-	-- username is returned just to help for logging purposes
-	-- status is used to enforce 'suspended' users, as all roles are ignored
-	--	when suspended.
-	id, username, status,
-	-- All user roles, including their org roles.
-	array_cat(
-		-- All users are members
-		array_append(users.rbac_roles, 'member'),
-		(
-			SELECT
-				array_agg(org_roles)
-			FROM
-				organization_members,
-				-- All org_members get the org-member role for their orgs
-				unnest(
-					array_append(roles, 'organization-member:' || organization_members.organization_id::text)
-				) AS org_roles
-			WHERE
-				user_id = users.id
-		)
-	) :: text[] AS roles,
-	-- All groups the user is in.
-	(
-		SELECT
-			array_agg(
-				group_members.group_id :: text
-			)
-		FROM
-			group_members
-		WHERE
-			user_id = users.id
-	) :: text[] AS groups
-FROM
+func (q *sqlQuerier) UpdateUserAppearanceSettings(ctx context.Context, arg UpdateUserAppearanceSettingsParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserAppearanceSettings, arg.ID, arg.ThemePreference, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
+}
+
+const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
+UPDATE
+	users
+SET
+	deleted = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserDeletedByIDParams struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Deleted bool      `db:"deleted" json:"deleted"`
+}
+
+func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
+	return err
+}
+
+const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
+UPDATE
+	users
+SET
+	hashed_password = $2
+WHERE
+	id = $1
+`
+
+type UpdateUserHashedPasswordParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
+}
+
+func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserIPAllowlist = `-- name: UpdateUserIPAllowlist :one
+UPDATE
+	users
+SET
+	ip_allowlist = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
+`
+
+type UpdateUserIPAllowlistParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	IPAllowlist []string  `db:"ip_allowlist" json:"ip_allowlist"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserIPAllowlist(ctx context.Context, arg UpdateUserIPAllowlistParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserIPAllowlist, arg.ID, pq.Array(arg.IPAllowlist), arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
+}
+
+const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
+UPDATE
+	users
+SET
+	last_seen_at = $2,
+	updated_at = $3
+WHERE
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
+`
+
+type UpdateUserLastSeenAtParams struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
+}
+
+const updateUserLoginType = `-- name: UpdateUserLoginType :one
+UPDATE
 	users
+SET
+	login_type = $1,
+	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
+		users.hashed_password
+	ELSE
+		-- If the login type is not password, then the password should be
+        -- cleared.
+		'':: bytea
+	END
 WHERE
-	id = $1
+	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-type GetAuthorizationUserRolesRow struct {
-	ID       uuid.UUID  `db:"id" json:"id"`
-	Username string     `db:"username" json:"username"`
-	Status   UserStatus `db:"status" json:"status"`
-	Roles    []string   `db:"roles" json:"roles"`
-	Groups   []string   `db:"groups" json:"groups"`
+type UpdateUserLoginTypeParams struct {
+	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
 }
 
-// This function returns roles for authorization purposes. Implied member roles
-// are included.
-func (q *sqlQuerier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error) {
-	row := q.db.QueryRowContext(ctx, getAuthorizationUserRoles, userID)
-	var i GetAuthorizationUserRolesRow
+func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
+	var i User
 	err := row.Scan(
 		&i.ID,
+		&i.Email,
 		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 		&i.Status,
-		pq.Array(&i.Roles),
-		pq.Array(&i.Groups),
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const getUserByEmailOrUsername = `-- name: GetUserByEmailOrUsername :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
-FROM
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE
 	users
+SET
+	email = $2,
+	username = $3,
+	avatar_url = $4,
+	updated_at = $5,
+	name = $6
 WHERE
-	(LOWER(username) = LOWER($1) OR LOWER(email) = LOWER($2)) AND
-	deleted = false
-LIMIT
-	1
+	id = $1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-type GetUserByEmailOrUsernameParams struct {
-	Username string `db:"username" json:"username"`
-	Email    string `db:"email" json:"email"`
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Username  string    `db:"username" json:"username"`
+	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmailOrUsername, arg.Username, arg.Email)
+func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.Email,
+		arg.Username,
+		arg.AvatarURL,
+		arg.UpdatedAt,
+		arg.Name,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -7368,23 +12590,28 @@ func (q *sqlQuerier) GetUserByEmailOrUsername(ctx context.Context, arg GetUserBy
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
-FROM
+const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+UPDATE
 	users
+SET
+	quiet_hours_schedule = $2
 WHERE
 	id = $1
-LIMIT
-	1
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
+type UpdateUserQuietHoursScheduleParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+}
+
+func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -7402,166 +12629,143 @@ func (q *sqlQuerier) GetUserByID(ctx context.Context, id uuid.UUID) (User, error
 		&i.QuietHoursSchedule,
 		&i.ThemePreference,
 		&i.Name,
+		pq.Array(&i.IPAllowlist),
 	)
 	return i, err
 }
 
-const getUserCount = `-- name: GetUserCount :one
-SELECT
-	COUNT(*)
-FROM
+const updateUserRoles = `-- name: UpdateUserRoles :one
+UPDATE
 	users
+SET
+	-- Remove all duplicates from the roles.
+	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
 WHERE
-	deleted = false
+	id = $2
+RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-func (q *sqlQuerier) GetUserCount(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getUserCount)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type UpdateUserRolesParams struct {
+	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
+	ID           uuid.UUID `db:"id" json:"id"`
 }
 
-const getUsers = `-- name: GetUsers :many
-SELECT
-	id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, COUNT(*) OVER() AS count
-FROM
+func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE
 	users
+SET
+	status = $2,
+	updated_at = $3
 WHERE
-	users.deleted = false
-	AND CASE
-		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the username field, so select all
-			-- rows after the cursor.
-			(LOWER(username)) > (
-				SELECT
-					LOWER(username)
-				FROM
-					users
-				WHERE
-					id = $1
-			)
-		)
-		ELSE true
-	END
-	-- Start filters
-	-- Filter by name, email or username
-	AND CASE
-		WHEN $2 :: text != '' THEN (
-			email ILIKE concat('%', $2, '%')
-			OR username ILIKE concat('%', $2, '%')
-		)
-		ELSE true
-	END
-	-- Filter by status
-	AND CASE
-		-- @status needs to be a text because it can be empty, If it was
-		-- user_status enum, it would not.
-		WHEN cardinality($3 :: user_status[]) > 0 THEN
-			status = ANY($3 :: user_status[])
-		ELSE true
-	END
-	-- Filter by rbac_roles
-	AND CASE
-		-- @rbac_role allows filtering by rbac roles. If 'member' is included, show everyone, as
-		-- everyone is a member.
-		WHEN cardinality($4 :: text[]) > 0 AND 'member' != ANY($4 :: text[]) THEN
-			rbac_roles && $4 :: text[]
-		ELSE true
-	END
-	-- Filter by last_seen
-	AND CASE
-		WHEN $5 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at <= $5
-		ELSE true
-	END
-	AND CASE
-		WHEN $6 :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
-			last_seen_at >= $6
-		ELSE true
-	END
-	-- End of filters
-
-	-- Authorize Filter clause will be injected below in GetAuthorizedUsers
-	-- @authorize_filter
-ORDER BY
-	-- Deterministic and consistent ordering of all users. This is to ensure consistent pagination.
-	LOWER(username) ASC OFFSET $7
-LIMIT
-	-- A null limit means "no limit", so 0 means return all
-	NULLIF($8 :: int, 0)
+	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name, ip_allowlist
 `
 
-type GetUsersParams struct {
-	AfterID        uuid.UUID    `db:"after_id" json:"after_id"`
-	Search         string       `db:"search" json:"search"`
-	Status         []UserStatus `db:"status" json:"status"`
-	RbacRole       []string     `db:"rbac_role" json:"rbac_role"`
-	LastSeenBefore time.Time    `db:"last_seen_before" json:"last_seen_before"`
-	LastSeenAfter  time.Time    `db:"last_seen_after" json:"last_seen_after"`
-	OffsetOpt      int32        `db:"offset_opt" json:"offset_opt"`
-	LimitOpt       int32        `db:"limit_opt" json:"limit_opt"`
+type UpdateUserStatusParams struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Status    UserStatus `db:"status" json:"status"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Username,
+		&i.HashedPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.RBACRoles,
+		&i.LoginType,
+		&i.AvatarURL,
+		&i.Deleted,
+		&i.LastSeenAt,
+		&i.QuietHoursSchedule,
+		&i.ThemePreference,
+		&i.Name,
+		pq.Array(&i.IPAllowlist),
+	)
+	return i, err
 }
 
-type GetUsersRow struct {
-	ID                 uuid.UUID      `db:"id" json:"id"`
-	Email              string         `db:"email" json:"email"`
-	Username           string         `db:"username" json:"username"`
-	HashedPassword     []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
-	Status             UserStatus     `db:"status" json:"status"`
-	RBACRoles          pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType          LoginType      `db:"login_type" json:"login_type"`
-	AvatarURL          string         `db:"avatar_url" json:"avatar_url"`
-	Deleted            bool           `db:"deleted" json:"deleted"`
-	LastSeenAt         time.Time      `db:"last_seen_at" json:"last_seen_at"`
-	QuietHoursSchedule string         `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
-	ThemePreference    string         `db:"theme_preference" json:"theme_preference"`
-	Name               string         `db:"name" json:"name"`
-	Count              int64          `db:"count" json:"count"`
+const acquireWebhookDeliveries = `-- name: AcquireWebhookDeliveries :many
+UPDATE
+	webhook_deliveries
+SET
+	next_attempt_at = $1 :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.next_attempt_at <= $2 :: timestamptz
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			$3 :: int
+	) RETURNING id, webhook_id, event, payload, attempts, status_code, last_error, created_at, delivered_at, next_attempt_at
+`
+
+type AcquireWebhookDeliveriesParams struct {
+	LeaseUntil time.Time `db:"lease_until" json:"lease_until"`
+	Now        time.Time `db:"now" json:"now"`
+	LimitOpt   int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-// This will never return deleted users.
-func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUsers,
-		arg.AfterID,
-		arg.Search,
-		pq.Array(arg.Status),
-		pq.Array(arg.RbacRole),
-		arg.LastSeenBefore,
-		arg.LastSeenAfter,
-		arg.OffsetOpt,
-		arg.LimitOpt,
-	)
+// Acquires deliveries that are due to be attempted. The next attempt is
+// pushed back to lease_until so that a crashed replica does not hold on to
+// the delivery forever, and SKIP LOCKED prevents multiple replicas from
+// acquiring the same delivery.
+func (q *sqlQuerier) AcquireWebhookDeliveries(ctx context.Context, arg AcquireWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, acquireWebhookDeliveries, arg.LeaseUntil, arg.Now, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetUsersRow
+	var items []WebhookDelivery
 	for rows.Next() {
-		var i GetUsersRow
+		var i WebhookDelivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.LastError,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.ThemePreference,
-			&i.Name,
-			&i.Count,
+			&i.DeliveredAt,
+			&i.NextAttemptAt,
 		); err != nil {
 			return nil, err
 		}
@@ -7576,38 +12780,81 @@ func (q *sqlQuerier) GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUse
 	return items, nil
 }
 
-const getUsersByIDs = `-- name: GetUsersByIDs :many
-SELECT id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name FROM users WHERE id = ANY($1 :: uuid [ ])
+const deleteWebhookByID = `-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1
 `
 
-// This shouldn't check for deleted, because it's frequently used
-// to look up references to actions. eg. a user could build a workspace
-// for another user, then be deleted... we still want them to appear!
-func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getUsersByIDs, pq.Array(ids))
+func (q *sqlQuerier) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWebhookByID, id)
+	return err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT
+	id, url, secret, events, created_at, updated_at, secret_key_id
+FROM
+	webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SecretKeyID,
+	)
+	return i, err
+}
+
+const getWebhookDeliveriesByWebhookID = `-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	id, webhook_id, event, payload, attempts, status_code, last_error, created_at, delivered_at, next_attempt_at
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($2 :: int, 0)
+`
+
+type GetWebhookDeliveriesByWebhookIDParams struct {
+	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWebhookDeliveriesByWebhookID(ctx context.Context, arg GetWebhookDeliveriesByWebhookIDParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveriesByWebhookID, arg.WebhookID, arg.LimitOpt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []WebhookDelivery
 	for rows.Next() {
-		var i User
+		var i WebhookDelivery
 		if err := rows.Scan(
 			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.LastError,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
-			&i.ThemePreference,
-			&i.Name,
+			&i.DeliveredAt,
+			&i.NextAttemptAt,
 		); err != nil {
 			return nil, err
 		}
@@ -7622,98 +12869,60 @@ func (q *sqlQuerier) GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User
 	return items, nil
 }
 
-const insertUser = `-- name: InsertUser :one
-INSERT INTO
-	users (
-		id,
-		email,
-		username,
-		hashed_password,
-		created_at,
-		updated_at,
-		rbac_roles,
-		login_type
-	)
-VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+const getWebhookDeliveryByID = `-- name: GetWebhookDeliveryByID :one
+SELECT
+	id, webhook_id, event, payload, attempts, status_code, last_error, created_at, delivered_at, next_attempt_at
+FROM
+	webhook_deliveries
+WHERE
+	id = $1
 `
 
-type InsertUserParams struct {
-	ID             uuid.UUID      `db:"id" json:"id"`
-	Email          string         `db:"email" json:"email"`
-	Username       string         `db:"username" json:"username"`
-	HashedPassword []byte         `db:"hashed_password" json:"hashed_password"`
-	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
-	RBACRoles      pq.StringArray `db:"rbac_roles" json:"rbac_roles"`
-	LoginType      LoginType      `db:"login_type" json:"login_type"`
-}
-
-func (q *sqlQuerier) InsertUser(ctx context.Context, arg InsertUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, insertUser,
-		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.HashedPassword,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.RBACRoles,
-		arg.LoginType,
-	)
-	var i User
+func (q *sqlQuerier) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDeliveryByID, id)
+	var i WebhookDelivery
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Attempts,
+		&i.StatusCode,
+		&i.LastError,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		&i.DeliveredAt,
+		&i.NextAttemptAt,
 	)
 	return i, err
 }
 
-const updateInactiveUsersToDormant = `-- name: UpdateInactiveUsersToDormant :many
-UPDATE
-    users
-SET
-    status = 'dormant'::user_status,
-	updated_at = $1
-WHERE
-    last_seen_at < $2 :: timestamp
-    AND status = 'active'::user_status
-RETURNING id, email, last_seen_at
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT
+	id, url, secret, events, created_at, updated_at, secret_key_id
+FROM
+	webhooks
+ORDER BY
+	created_at ASC
 `
 
-type UpdateInactiveUsersToDormantParams struct {
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
-	LastSeenAfter time.Time `db:"last_seen_after" json:"last_seen_after"`
-}
-
-type UpdateInactiveUsersToDormantRow struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	Email      string    `db:"email" json:"email"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-}
-
-func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error) {
-	rows, err := q.db.QueryContext(ctx, updateInactiveUsersToDormant, arg.UpdatedAt, arg.LastSeenAfter)
+func (q *sqlQuerier) GetWebhooks(ctx context.Context) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []UpdateInactiveUsersToDormantRow
+	var items []Webhook
 	for rows.Next() {
-		var i UpdateInactiveUsersToDormantRow
-		if err := rows.Scan(&i.ID, &i.Email, &i.LastSeenAt); err != nil {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SecretKeyID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -7727,332 +12936,304 @@ func (q *sqlQuerier) UpdateInactiveUsersToDormant(ctx context.Context, arg Updat
 	return items, nil
 }
 
-const updateUserAppearanceSettings = `-- name: UpdateUserAppearanceSettings :one
-UPDATE
-	users
-SET
-	theme_preference = $2,
-	updated_at = $3
-WHERE
-	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
-`
-
-type UpdateUserAppearanceSettingsParams struct {
-	ID              uuid.UUID `db:"id" json:"id"`
-	ThemePreference string    `db:"theme_preference" json:"theme_preference"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-}
-
-func (q *sqlQuerier) UpdateUserAppearanceSettings(ctx context.Context, arg UpdateUserAppearanceSettingsParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserAppearanceSettings, arg.ID, arg.ThemePreference, arg.UpdatedAt)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
-	)
-	return i, err
-}
-
-const updateUserDeletedByID = `-- name: UpdateUserDeletedByID :exec
-UPDATE
-	users
-SET
-	deleted = $2
-WHERE
-	id = $1
-`
-
-type UpdateUserDeletedByIDParams struct {
-	ID      uuid.UUID `db:"id" json:"id"`
-	Deleted bool      `db:"deleted" json:"deleted"`
-}
-
-func (q *sqlQuerier) UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserDeletedByID, arg.ID, arg.Deleted)
-	return err
-}
-
-const updateUserHashedPassword = `-- name: UpdateUserHashedPassword :exec
-UPDATE
-	users
-SET
-	hashed_password = $2
+const getWebhooksByEvent = `-- name: GetWebhooksByEvent :many
+SELECT
+	id, url, secret, events, created_at, updated_at, secret_key_id
+FROM
+	webhooks
 WHERE
-	id = $1
+	$1 :: text = ANY(events)
+ORDER BY
+	created_at ASC
 `
 
-type UpdateUserHashedPasswordParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	HashedPassword []byte    `db:"hashed_password" json:"hashed_password"`
-}
-
-func (q *sqlQuerier) UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, updateUserHashedPassword, arg.ID, arg.HashedPassword)
-	return err
+func (q *sqlQuerier) GetWebhooksByEvent(ctx context.Context, event string) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooksByEvent, event)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Secret,
+			pq.Array(&i.Events),
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SecretKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateUserLastSeenAt = `-- name: UpdateUserLastSeenAt :one
-UPDATE
-	users
-SET
-	last_seen_at = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+const insertWebhook = `-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (id, url, secret, secret_key_id, events, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, url, secret, events, created_at, updated_at, secret_key_id
 `
 
-type UpdateUserLastSeenAtParams struct {
-	ID         uuid.UUID `db:"id" json:"id"`
-	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+type InsertWebhookParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Url         string         `db:"url" json:"url"`
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
+	Events      []string       `db:"events" json:"events"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) UpdateUserLastSeenAt(ctx context.Context, arg UpdateUserLastSeenAtParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLastSeenAt, arg.ID, arg.LastSeenAt, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) InsertWebhook(ctx context.Context, arg InsertWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhook,
+		arg.ID,
+		arg.Url,
+		arg.Secret,
+		arg.SecretKeyID,
+		pq.Array(arg.Events),
+		arg.CreatedAt,
+		arg.UpdatedAt,
+	)
+	var i Webhook
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.Url,
+		&i.Secret,
+		pq.Array(&i.Events),
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		&i.SecretKeyID,
 	)
 	return i, err
 }
 
-const updateUserLoginType = `-- name: UpdateUserLoginType :one
-UPDATE
-	users
-SET
-	login_type = $1,
-	hashed_password = CASE WHEN $1 = 'password' :: login_type THEN
-		users.hashed_password
-	ELSE
-		-- If the login type is not password, then the password should be
-        -- cleared.
-		'':: bytea
-	END
-WHERE
-	id = $2 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+const insertWebhookDelivery = `-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (id, webhook_id, event, payload, created_at, next_attempt_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, webhook_id, event, payload, attempts, status_code, last_error, created_at, delivered_at, next_attempt_at
 `
 
-type UpdateUserLoginTypeParams struct {
-	NewLoginType LoginType `db:"new_login_type" json:"new_login_type"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+type InsertWebhookDeliveryParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	WebhookID     uuid.UUID       `db:"webhook_id" json:"webhook_id"`
+	Event         string          `db:"event" json:"event"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	NextAttemptAt sql.NullTime    `db:"next_attempt_at" json:"next_attempt_at"`
 }
 
-func (q *sqlQuerier) UpdateUserLoginType(ctx context.Context, arg UpdateUserLoginTypeParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserLoginType, arg.NewLoginType, arg.UserID)
-	var i User
+func (q *sqlQuerier) InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, insertWebhookDelivery,
+		arg.ID,
+		arg.WebhookID,
+		arg.Event,
+		arg.Payload,
+		arg.CreatedAt,
+		arg.NextAttemptAt,
+	)
+	var i WebhookDelivery
 	err := row.Scan(
 		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
+		&i.WebhookID,
+		&i.Event,
+		&i.Payload,
+		&i.Attempts,
+		&i.StatusCode,
+		&i.LastError,
 		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		&i.DeliveredAt,
+		&i.NextAttemptAt,
 	)
 	return i, err
 }
 
-const updateUserProfile = `-- name: UpdateUserProfile :one
+const updateWebhookDeliveryByID = `-- name: UpdateWebhookDeliveryByID :exec
 UPDATE
-	users
+	webhook_deliveries
 SET
-	email = $2,
-	username = $3,
-	avatar_url = $4,
-	updated_at = $5,
-	name = $6
+	attempts = $2,
+	status_code = $3,
+	last_error = $4,
+	delivered_at = $5,
+	next_attempt_at = $6
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
 `
 
-type UpdateUserProfileParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Username  string    `db:"username" json:"username"`
-	AvatarURL string    `db:"avatar_url" json:"avatar_url"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-	Name      string    `db:"name" json:"name"`
+type UpdateWebhookDeliveryByIDParams struct {
+	ID            uuid.UUID     `db:"id" json:"id"`
+	Attempts      int32         `db:"attempts" json:"attempts"`
+	StatusCode    sql.NullInt32 `db:"status_code" json:"status_code"`
+	LastError     string        `db:"last_error" json:"last_error"`
+	DeliveredAt   sql.NullTime  `db:"delivered_at" json:"delivered_at"`
+	NextAttemptAt sql.NullTime  `db:"next_attempt_at" json:"next_attempt_at"`
 }
 
-func (q *sqlQuerier) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserProfile,
+func (q *sqlQuerier) UpdateWebhookDeliveryByID(ctx context.Context, arg UpdateWebhookDeliveryByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDeliveryByID,
 		arg.ID,
-		arg.Email,
-		arg.Username,
-		arg.AvatarURL,
-		arg.UpdatedAt,
-		arg.Name,
-	)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		arg.Attempts,
+		arg.StatusCode,
+		arg.LastError,
+		arg.DeliveredAt,
+		arg.NextAttemptAt,
 	)
-	return i, err
+	return err
 }
 
-const updateUserQuietHoursSchedule = `-- name: UpdateUserQuietHoursSchedule :one
+const updateWebhookSecretByID = `-- name: UpdateWebhookSecretByID :exec
 UPDATE
-	users
+	webhooks
 SET
-	quiet_hours_schedule = $2
+	secret = $2,
+	secret_key_id = $3
 WHERE
 	id = $1
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
 `
 
-type UpdateUserQuietHoursScheduleParams struct {
-	ID                 uuid.UUID `db:"id" json:"id"`
-	QuietHoursSchedule string    `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
+type UpdateWebhookSecretByIDParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	Secret      string         `db:"secret" json:"secret"`
+	SecretKeyID sql.NullString `db:"secret_key_id" json:"secret_key_id"`
 }
 
-func (q *sqlQuerier) UpdateUserQuietHoursSchedule(ctx context.Context, arg UpdateUserQuietHoursScheduleParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserQuietHoursSchedule, arg.ID, arg.QuietHoursSchedule)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
-	)
-	return i, err
+func (q *sqlQuerier) UpdateWebhookSecretByID(ctx context.Context, arg UpdateWebhookSecretByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookSecretByID, arg.ID, arg.Secret, arg.SecretKeyID)
+	return err
 }
 
-const updateUserRoles = `-- name: UpdateUserRoles :one
-UPDATE
-	users
-SET
-	-- Remove all duplicates from the roles.
-	rbac_roles = ARRAY(SELECT DISTINCT UNNEST($1 :: text[]))
+const deleteWorkspaceAgentPortShare = `-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_shares
 WHERE
-	id = $2
-RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
 `
 
-type UpdateUserRolesParams struct {
-	GrantedRoles []string  `db:"granted_roles" json:"granted_roles"`
-	ID           uuid.UUID `db:"id" json:"id"`
+type DeleteWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
 }
 
-func (q *sqlQuerier) UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserRoles, pq.Array(arg.GrantedRoles), arg.ID)
-	var i User
+func (q *sqlQuerier) DeleteWorkspaceAgentPortShare(ctx context.Context, arg DeleteWorkspaceAgentPortShareParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	return err
+}
+
+const getWorkspaceAgentPortShare = `-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_agent_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3
+`
+
+type GetWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	Port        int32     `db:"port" json:"port"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentPortShare(ctx context.Context, arg GetWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentPortShare, arg.WorkspaceID, arg.AgentName, arg.Port)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
 	)
 	return i, err
 }
 
-const updateUserStatus = `-- name: UpdateUserStatus :one
-UPDATE
-	users
-SET
-	status = $2,
-	updated_at = $3
-WHERE
-	id = $1 RETURNING id, email, username, hashed_password, created_at, updated_at, status, rbac_roles, login_type, avatar_url, deleted, last_seen_at, quiet_hours_schedule, theme_preference, name
+const getWorkspaceAgentPortSharesByWorkspaceID = `-- name: GetWorkspaceAgentPortSharesByWorkspaceID :many
+SELECT
+	workspace_id, agent_name, port, share_level
+FROM
+	workspace_agent_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentPortSharesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentPortSharesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPortShare
+	for rows.Next() {
+		var i WorkspaceAgentPortShare
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.AgentName,
+			&i.Port,
+			&i.ShareLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceAgentPortShare = `-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_shares (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(workspace_id, agent_name, port)
+DO UPDATE SET
+	share_level = $4
+RETURNING workspace_id, agent_name, port, share_level
 `
 
-type UpdateUserStatusParams struct {
-	ID        uuid.UUID  `db:"id" json:"id"`
-	Status    UserStatus `db:"status" json:"status"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+type UpsertWorkspaceAgentPortShareParams struct {
+	WorkspaceID uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	AgentName   string          `db:"agent_name" json:"agent_name"`
+	Port        int32           `db:"port" json:"port"`
+	ShareLevel  AppSharingLevel `db:"share_level" json:"share_level"`
 }
 
-func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUserStatus, arg.ID, arg.Status, arg.UpdatedAt)
-	var i User
+func (q *sqlQuerier) UpsertWorkspaceAgentPortShare(ctx context.Context, arg UpsertWorkspaceAgentPortShareParams) (WorkspaceAgentPortShare, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceAgentPortShare,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.Port,
+		arg.ShareLevel,
+	)
+	var i WorkspaceAgentPortShare
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Username,
-		&i.HashedPassword,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Status,
-		&i.RBACRoles,
-		&i.LoginType,
-		&i.AvatarURL,
-		&i.Deleted,
-		&i.LastSeenAt,
-		&i.QuietHoursSchedule,
-		&i.ThemePreference,
-		&i.Name,
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.Port,
+		&i.ShareLevel,
 	)
 	return i, err
 }
@@ -8070,6 +13251,29 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	return err
 }
 
+const deleteOldWorkspaceAgentMetadataHistory = `-- name: DeleteOldWorkspaceAgentMetadataHistory :exec
+DELETE FROM workspace_agent_metadata_history WHERE collected_at < $1
+`
+
+// Deletes the metadata history of agents that stopped reporting, e.g. because
+// their workspace was stopped.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentMetadataHistory(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentMetadataHistory, beforeTime)
+	return err
+}
+
+const deleteWorkspaceAgentPreviousAuthTokenByAgentID = `-- name: DeleteWorkspaceAgentPreviousAuthTokenByAgentID :exec
+DELETE FROM
+	workspace_agent_previous_auth_tokens
+WHERE
+	agent_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx context.Context, agentID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceAgentPreviousAuthTokenByAgentID, agentID)
+	return err
+}
+
 const getWorkspaceAgentAndOwnerByAuthToken = `-- name: GetWorkspaceAgentAndOwnerByAuthToken :one
 SELECT
 	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.expanded_directory, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_apps, workspace_agents.api_version,
@@ -8113,7 +13317,19 @@ WHERE
 	-- TODO: we can add more conditions here, such as:
 	-- 1) The user must be active
 	-- 2) The workspace must be running
-	workspace_agents.auth_token = $1
+	(
+		workspace_agents.auth_token = $1
+		-- Tokens stay valid for a while after they are rotated.
+		OR workspace_agents.id IN (
+			SELECT
+				agent_id
+			FROM
+				workspace_agent_previous_auth_tokens
+			WHERE
+				workspace_agent_previous_auth_tokens.auth_token = $1
+				AND workspace_agent_previous_auth_tokens.expires_at > NOW()
+		)
+	)
 AND
 	workspaces.deleted = FALSE
 GROUP BY
@@ -8186,6 +13402,24 @@ func (q *sqlQuerier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, a
 	return i, err
 }
 
+const getWorkspaceAgentAuthTokenByIDForUpdate = `-- name: GetWorkspaceAgentAuthTokenByIDForUpdate :one
+SELECT
+	auth_token
+FROM
+	workspace_agents
+WHERE
+	id = $1
+FOR UPDATE
+`
+
+// Locks the agent's row, so concurrent rotations of its token are serialized.
+func (q *sqlQuerier) GetWorkspaceAgentAuthTokenByIDForUpdate(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentAuthTokenByIDForUpdate, id)
+	var auth_token uuid.UUID
+	err := row.Scan(&auth_token)
+	return auth_token, err
+}
+
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
 	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version
@@ -8282,6 +13516,104 @@ func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInst
 	return i, err
 }
 
+const getWorkspaceAgentCountsByLifecycleState = `-- name: GetWorkspaceAgentCountsByLifecycleState :many
+SELECT
+	workspace_agents.lifecycle_state,
+	COUNT(*) AS count
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+WHERE
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+GROUP BY
+	workspace_agents.lifecycle_state
+`
+
+type GetWorkspaceAgentCountsByLifecycleStateRow struct {
+	LifecycleState WorkspaceAgentLifecycleState `db:"lifecycle_state" json:"lifecycle_state"`
+	Count          int64                        `db:"count" json:"count"`
+}
+
+// Counts the agents in the latest build of workspaces that are not deleted by
+// lifecycle state.
+func (q *sqlQuerier) GetWorkspaceAgentCountsByLifecycleState(ctx context.Context) ([]GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentCountsByLifecycleState)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentCountsByLifecycleStateRow
+	for rows.Next() {
+		var i GetWorkspaceAgentCountsByLifecycleStateRow
+		if err := rows.Scan(&i.LifecycleState, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentGPUsByAgentIDs = `-- name: GetWorkspaceAgentGPUsByAgentIDs :many
+SELECT
+	agent_id, index, vendor, model, memory_bytes, driver_version
+FROM
+	workspace_agent_gpus
+WHERE
+	agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	vendor ASC,
+	index ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentGPUsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentGPU, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentGPUsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentGPU
+	for rows.Next() {
+		var i WorkspaceAgentGPU
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Index,
+			&i.Vendor,
+			&i.Model,
+			&i.MemoryBytes,
+			&i.DriverVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentLifecycleStateByID = `-- name: GetWorkspaceAgentLifecycleStateByID :one
 SELECT
 	lifecycle_state,
@@ -8434,6 +13766,95 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, arg GetWorks
 	return items, nil
 }
 
+const getWorkspaceAgentMetadataHistory = `-- name: GetWorkspaceAgentMetadataHistory :many
+SELECT
+	workspace_agent_id, key, value, error, collected_at
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = $1
+	AND collected_at > $2
+	AND CASE WHEN COALESCE(array_length($3::text[], 1), 0) > 0 THEN key = ANY($3::text[]) ELSE TRUE END
+ORDER BY
+	key, collected_at
+`
+
+type GetWorkspaceAgentMetadataHistoryParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Since            time.Time `db:"since" json:"since"`
+	Keys             []string  `db:"keys" json:"keys"`
+}
+
+// Returns the recorded values of the metadata of an agent collected after
+// @since, oldest first. Without keys, the values of all metadata are returned.
+func (q *sqlQuerier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]WorkspaceAgentMetadataHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadataHistory, arg.WorkspaceAgentID, arg.Since, pq.Array(arg.Keys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMetadataHistory
+	for rows.Next() {
+		var i WorkspaceAgentMetadataHistory
+		if err := rows.Scan(
+			&i.WorkspaceAgentID,
+			&i.Key,
+			&i.Value,
+			&i.Error,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentPortsByAgentIDs = `-- name: GetWorkspaceAgentPortsByAgentIDs :many
+SELECT
+	agent_id, port, process_name, discovered_at
+FROM
+	workspace_agent_ports
+WHERE
+	agent_id = ANY($1 :: uuid [ ])
+ORDER BY
+	port ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAgentPortsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentPort, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentPortsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentPort
+	for rows.Next() {
+		var i WorkspaceAgentPort
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Port,
+			&i.ProcessName,
+			&i.DiscoveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
 	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, expanded_directory, logs_length, logs_overflowed, started_at, ready_at, subsystems, display_apps, api_version
@@ -8862,24 +14283,112 @@ VALUES
 	($1, $2, $3, $4, $5, $6)
 `
 
-type InsertWorkspaceAgentMetadataParams struct {
-	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
-	DisplayName      string    `db:"display_name" json:"display_name"`
-	Key              string    `db:"key" json:"key"`
-	Script           string    `db:"script" json:"script"`
-	Timeout          int64     `db:"timeout" json:"timeout"`
-	Interval         int64     `db:"interval" json:"interval"`
+type InsertWorkspaceAgentMetadataParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	DisplayName      string    `db:"display_name" json:"display_name"`
+	Key              string    `db:"key" json:"key"`
+	Script           string    `db:"script" json:"script"`
+	Timeout          int64     `db:"timeout" json:"timeout"`
+	Interval         int64     `db:"interval" json:"interval"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadata,
+		arg.WorkspaceAgentID,
+		arg.DisplayName,
+		arg.Key,
+		arg.Script,
+		arg.Timeout,
+		arg.Interval,
+	)
+	return err
+}
+
+const insertWorkspaceAgentMetadataHistory = `-- name: InsertWorkspaceAgentMetadataHistory :exec
+WITH metadata AS (
+	SELECT
+		unnest($1::text[]) AS key,
+		unnest($2::text[]) AS value,
+		unnest($3::text[]) AS error,
+		unnest($4::timestamptz[]) AS collected_at
+), inserted AS (
+	INSERT INTO
+		workspace_agent_metadata_history (workspace_agent_id, key, value, error, collected_at)
+	SELECT
+		wam.workspace_agent_id, m.key, m.value, m.error, m.collected_at
+	FROM
+		metadata m
+	JOIN
+		workspace_agent_metadata wam
+	ON
+		wam.workspace_agent_id = $5::uuid
+		AND wam.key = m.key
+	ON CONFLICT DO NOTHING
+)
+DELETE FROM
+	workspace_agent_metadata_history wamh
+USING (
+	SELECT
+		key,
+		collected_at,
+		row_number() OVER (PARTITION BY key ORDER BY collected_at DESC) AS n
+	FROM
+		workspace_agent_metadata_history
+	WHERE
+		workspace_agent_id = $5::uuid
+		AND key = ANY($1::text[])
+) ranked
+WHERE
+	wamh.workspace_agent_id = $5::uuid
+	AND wamh.key = ranked.key
+	AND wamh.collected_at = ranked.collected_at
+	-- The values inserted above aren't visible to this statement, so one old
+	-- value less is kept to make room for them.
+	AND ranked.n >= $6::int
+`
+
+type InsertWorkspaceAgentMetadataHistoryParams struct {
+	Key              []string    `db:"key" json:"key"`
+	Value            []string    `db:"value" json:"value"`
+	Error            []string    `db:"error" json:"error"`
+	CollectedAt      []time.Time `db:"collected_at" json:"collected_at"`
+	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Keep             int32       `db:"keep" json:"keep"`
+}
+
+// Records the values of the metadata of an agent and deletes the oldest values
+// of each metadatum, so that at most @keep values are kept. Values of keys the
+// agent doesn't have are ignored, like in UpdateWorkspaceAgentMetadata.
+func (q *sqlQuerier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadataHistory,
+		pq.Array(arg.Key),
+		pq.Array(arg.Value),
+		pq.Array(arg.Error),
+		pq.Array(arg.CollectedAt),
+		arg.WorkspaceAgentID,
+		arg.Keep,
+	)
+	return err
+}
+
+const updateWorkspaceAgentAuthTokenByID = `-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentAuthTokenByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	AuthToken uuid.UUID `db:"auth_token" json:"auth_token"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadata,
-		arg.WorkspaceAgentID,
-		arg.DisplayName,
-		arg.Key,
-		arg.Script,
-		arg.Timeout,
-		arg.Interval,
-	)
+func (q *sqlQuerier) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentAuthTokenByID, arg.ID, arg.AuthToken, arg.UpdatedAt)
 	return err
 }
 
@@ -9004,75 +14513,594 @@ func (q *sqlQuerier) UpdateWorkspaceAgentMetadata(ctx context.Context, arg Updat
 	return err
 }
 
-const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
-UPDATE
+const updateWorkspaceAgentStartupByID = `-- name: UpdateWorkspaceAgentStartupByID :exec
+UPDATE
+	workspace_agents
+SET
+	version = $2,
+	expanded_directory = $3,
+	subsystems = $4,
+	api_version = $5
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentStartupByIDParams struct {
+	ID                uuid.UUID                 `db:"id" json:"id"`
+	Version           string                    `db:"version" json:"version"`
+	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
+	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+	APIVersion        string                    `db:"api_version" json:"api_version"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+		arg.ID,
+		arg.Version,
+		arg.ExpandedDirectory,
+		pq.Array(arg.Subsystems),
+		arg.APIVersion,
+	)
+	return err
+}
+
+const upsertWorkspaceAgentGPUs = `-- name: UpsertWorkspaceAgentGPUs :exec
+WITH removed AS (
+	DELETE FROM
+		workspace_agent_gpus
+	WHERE
+		agent_id = $1 :: uuid
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest($2 :: integer [ ], $3 :: text [ ]) AS reported(index, vendor)
+			WHERE
+				reported.index = workspace_agent_gpus.index
+				AND reported.vendor = workspace_agent_gpus.vendor
+		)
+)
+INSERT INTO
+	workspace_agent_gpus (agent_id, index, vendor, model, memory_bytes, driver_version)
+SELECT
+	$1 :: uuid AS agent_id,
+	unnest($2 :: integer [ ]) AS index,
+	unnest($3 :: text [ ]) AS vendor,
+	unnest($4 :: text [ ]) AS model,
+	unnest($5 :: bigint [ ]) AS memory_bytes,
+	unnest($6 :: text [ ]) AS driver_version
+ON CONFLICT (agent_id, vendor, index) DO UPDATE SET
+	model = EXCLUDED.model,
+	memory_bytes = EXCLUDED.memory_bytes,
+	driver_version = EXCLUDED.driver_version
+`
+
+type UpsertWorkspaceAgentGPUsParams struct {
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	Index         []int32   `db:"index" json:"index"`
+	Vendor        []string  `db:"vendor" json:"vendor"`
+	Model         []string  `db:"model" json:"model"`
+	MemoryBytes   []int64   `db:"memory_bytes" json:"memory_bytes"`
+	DriverVersion []string  `db:"driver_version" json:"driver_version"`
+}
+
+// Replaces the GPUs of an agent with the ones it detected on startup.
+func (q *sqlQuerier) UpsertWorkspaceAgentGPUs(ctx context.Context, arg UpsertWorkspaceAgentGPUsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentGPUs,
+		arg.AgentID,
+		pq.Array(arg.Index),
+		pq.Array(arg.Vendor),
+		pq.Array(arg.Model),
+		pq.Array(arg.MemoryBytes),
+		pq.Array(arg.DriverVersion),
+	)
+	return err
+}
+
+const upsertWorkspaceAgentPorts = `-- name: UpsertWorkspaceAgentPorts :exec
+WITH removed AS (
+	DELETE FROM
+		workspace_agent_ports
+	WHERE
+		agent_id = $1 :: uuid
+		AND NOT (port = ANY($2 :: integer [ ]))
+)
+INSERT INTO
+	workspace_agent_ports (agent_id, port, process_name, discovered_at)
+SELECT
+	$1 :: uuid AS agent_id,
+	unnest($2 :: integer [ ]) AS port,
+	unnest($3 :: text [ ]) AS process_name,
+	$4 :: timestamptz AS discovered_at
+ON CONFLICT (agent_id, port) DO UPDATE SET
+	process_name = EXCLUDED.process_name
+`
+
+type UpsertWorkspaceAgentPortsParams struct {
+	AgentID      uuid.UUID `db:"agent_id" json:"agent_id"`
+	Port         []int32   `db:"port" json:"port"`
+	ProcessName  []string  `db:"process_name" json:"process_name"`
+	DiscoveredAt time.Time `db:"discovered_at" json:"discovered_at"`
+}
+
+// Replaces the listening ports of an agent. Ports that are still listening
+// keep the time they were first discovered.
+func (q *sqlQuerier) UpsertWorkspaceAgentPorts(ctx context.Context, arg UpsertWorkspaceAgentPortsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentPorts,
+		arg.AgentID,
+		pq.Array(arg.Port),
+		pq.Array(arg.ProcessName),
+		arg.DiscoveredAt,
+	)
+	return err
+}
+
+const upsertWorkspaceAgentPreviousAuthToken = `-- name: UpsertWorkspaceAgentPreviousAuthToken :exec
+INSERT INTO
+	workspace_agent_previous_auth_tokens (
+		agent_id,
+		auth_token,
+		expires_at
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (agent_id) DO UPDATE SET
+	auth_token = $2,
+	expires_at = $3
+`
+
+type UpsertWorkspaceAgentPreviousAuthTokenParams struct {
+	AgentID   uuid.UUID `db:"agent_id" json:"agent_id"`
+	AuthToken uuid.UUID `db:"auth_token" json:"auth_token"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceAgentPreviousAuthToken(ctx context.Context, arg UpsertWorkspaceAgentPreviousAuthTokenParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceAgentPreviousAuthToken, arg.AgentID, arg.AuthToken, arg.ExpiresAt)
+	return err
+}
+
+const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
+DELETE FROM workspace_agent_stats WHERE created_at < NOW() - INTERVAL '180 days'
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
+	return err
+}
+
+const getDeploymentDAUs = `-- name: GetDeploymentDAUs :many
+SELECT
+	(created_at at TIME ZONE cast($1::integer as text))::date as date,
+	user_id
+FROM
+	workspace_agent_stats
+WHERE
+	connection_count > 0
+GROUP BY
+	date, user_id
+ORDER BY
+	date ASC
+`
+
+type GetDeploymentDAUsRow struct {
+	Date   time.Time `db:"date" json:"date"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]GetDeploymentDAUsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDeploymentDAUs, tzOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDeploymentDAUsRow
+	for rows.Next() {
+		var i GetDeploymentDAUsRow
+		if err := rows.Scan(&i.Date, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeploymentWorkspaceAgentStats = `-- name: GetDeploymentWorkspaceAgentStats :one
+WITH agent_stats AS (
+	SELECT
+		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+	 FROM workspace_agent_stats
+	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
+), latest_agent_stats AS (
+	SELECT
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+	 FROM (
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		FROM workspace_agent_stats WHERE created_at > $1
+	) AS a WHERE a.rn = 1
+)
+SELECT workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats, latest_agent_stats
+`
+
+type GetDeploymentWorkspaceAgentStatsRow struct {
+	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+}
+
+func (q *sqlQuerier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentStats, createdAt)
+	var i GetDeploymentWorkspaceAgentStatsRow
+	err := row.Scan(
+		&i.WorkspaceRxBytes,
+		&i.WorkspaceTxBytes,
+		&i.WorkspaceConnectionLatency50,
+		&i.WorkspaceConnectionLatency95,
+		&i.SessionCountVSCode,
+		&i.SessionCountSSH,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+	)
+	return i, err
+}
+
+const getLatestWorkspaceAgentStatByAgentID = `-- name: GetLatestWorkspaceAgentStatByAgentID :one
+SELECT
+	id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_total_cores, memory_used_bytes, memory_total_bytes, disk_used_bytes, disk_total_bytes
+FROM
+	workspace_agent_stats
+WHERE
+	agent_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceAgentStatByAgentID(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentStat, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceAgentStatByAgentID, agentID)
+	var i WorkspaceAgentStat
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.AgentID,
+		&i.WorkspaceID,
+		&i.TemplateID,
+		&i.ConnectionsByProto,
+		&i.ConnectionCount,
+		&i.RxPackets,
+		&i.RxBytes,
+		&i.TxPackets,
+		&i.TxBytes,
+		&i.ConnectionMedianLatencyMS,
+		&i.SessionCountVSCode,
+		&i.SessionCountJetBrains,
+		&i.SessionCountReconnectingPTY,
+		&i.SessionCountSSH,
+		&i.CPUUsedCores,
+		&i.CPUTotalCores,
+		&i.MemoryUsedBytes,
+		&i.MemoryTotalBytes,
+		&i.DiskUsedBytes,
+		&i.DiskTotalBytes,
+	)
+	return i, err
+}
+
+const getTemplateDAUs = `-- name: GetTemplateDAUs :many
+SELECT
+	(created_at at TIME ZONE cast($2::integer as text))::date as date,
+	user_id
+FROM
+	workspace_agent_stats
+WHERE
+	template_id = $1 AND
+	connection_count > 0
+GROUP BY
+	date, user_id
+ORDER BY
+	date ASC
+`
+
+type GetTemplateDAUsParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	TzOffset   int32     `db:"tz_offset" json:"tz_offset"`
+}
+
+type GetTemplateDAUsRow struct {
+	Date   time.Time `db:"date" json:"date"`
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+func (q *sqlQuerier) GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateDAUs, arg.TemplateID, arg.TzOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateDAUsRow
+	for rows.Next() {
+		var i GetTemplateDAUsRow
+		if err := rows.Scan(&i.Date, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentResourceUsageAndLabels = `-- name: GetWorkspaceAgentResourceUsageAndLabels :many
+SELECT DISTINCT ON (workspace_agent_stats.agent_id)
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name,
+	workspace_agent_stats.cpu_used_cores, workspace_agent_stats.cpu_total_cores,
+	workspace_agent_stats.memory_used_bytes, workspace_agent_stats.memory_total_bytes,
+	workspace_agent_stats.disk_used_bytes, workspace_agent_stats.disk_total_bytes
+FROM
+	workspace_agent_stats
+JOIN
+	users
+ON
+	users.id = workspace_agent_stats.user_id
+JOIN
 	workspace_agents
-SET
-	version = $2,
-	expanded_directory = $3,
-	subsystems = $4,
-	api_version = $5
+ON
+	workspace_agents.id = workspace_agent_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = workspace_agent_stats.workspace_id
 WHERE
-	id = $1
+	workspace_agent_stats.created_at > $1
+	-- Agents that don't report resource usage send zero totals.
+	AND (workspace_agent_stats.cpu_total_cores > 0 OR workspace_agent_stats.memory_total_bytes > 0 OR workspace_agent_stats.disk_total_bytes > 0)
+ORDER BY
+	workspace_agent_stats.agent_id, workspace_agent_stats.created_at DESC
 `
 
-type UpdateWorkspaceAgentStartupByIDParams struct {
-	ID                uuid.UUID                 `db:"id" json:"id"`
-	Version           string                    `db:"version" json:"version"`
-	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
-	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
-	APIVersion        string                    `db:"api_version" json:"api_version"`
+type GetWorkspaceAgentResourceUsageAndLabelsRow struct {
+	Username         string  `db:"username" json:"username"`
+	AgentName        string  `db:"agent_name" json:"agent_name"`
+	WorkspaceName    string  `db:"workspace_name" json:"workspace_name"`
+	CPUUsedCores     float64 `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPUTotalCores    float64 `db:"cpu_total_cores" json:"cpu_total_cores"`
+	MemoryUsedBytes  int64   `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryTotalBytes int64   `db:"memory_total_bytes" json:"memory_total_bytes"`
+	DiskUsedBytes    int64   `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes   int64   `db:"disk_total_bytes" json:"disk_total_bytes"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
-		arg.ID,
-		arg.Version,
-		arg.ExpandedDirectory,
-		pq.Array(arg.Subsystems),
-		arg.APIVersion,
-	)
-	return err
+func (q *sqlQuerier) GetWorkspaceAgentResourceUsageAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentResourceUsageAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentResourceUsageAndLabels, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentResourceUsageAndLabelsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentResourceUsageAndLabelsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.CPUUsedCores,
+			&i.CPUTotalCores,
+			&i.MemoryUsedBytes,
+			&i.MemoryTotalBytes,
+			&i.DiskUsedBytes,
+			&i.DiskTotalBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const deleteOldWorkspaceAgentStats = `-- name: DeleteOldWorkspaceAgentStats :exec
-DELETE FROM workspace_agent_stats WHERE created_at < NOW() - INTERVAL '180 days'
+const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		template_id,
+		MIN(created_at)::timestamptz AS aggregated_from,
+		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
+		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
+		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
+	 FROM workspace_agent_stats
+	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0 GROUP BY user_id, agent_id, workspace_id, template_id
+), latest_agent_stats AS (
+	SELECT
+		a.agent_id,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
+	 FROM (
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		FROM workspace_agent_stats WHERE created_at > $1
+	) AS a WHERE a.rn = 1 GROUP BY a.user_id, a.agent_id, a.workspace_id, a.template_id
+)
+SELECT user_id, agent_stats.agent_id, workspace_id, template_id, aggregated_from, workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, latest_agent_stats.agent_id, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats JOIN latest_agent_stats ON agent_stats.agent_id = latest_agent_stats.agent_id
 `
 
-func (q *sqlQuerier) DeleteOldWorkspaceAgentStats(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentStats)
-	return err
+type GetWorkspaceAgentStatsRow struct {
+	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
+	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
+	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
+	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
+	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
+	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
+	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
+	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
+	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
+	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
 }
 
-const getDeploymentDAUs = `-- name: GetDeploymentDAUs :many
+func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStats, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentStatsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentStatsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.AgentID,
+			&i.WorkspaceID,
+			&i.TemplateID,
+			&i.AggregatedFrom,
+			&i.WorkspaceRxBytes,
+			&i.WorkspaceTxBytes,
+			&i.WorkspaceConnectionLatency50,
+			&i.WorkspaceConnectionLatency95,
+			&i.AgentID_2,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentStatsAndLabels = `-- name: GetWorkspaceAgentStatsAndLabels :many
+WITH agent_stats AS (
+	SELECT
+		user_id,
+		agent_id,
+		workspace_id,
+		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+	 FROM workspace_agent_stats
+		WHERE workspace_agent_stats.created_at > $1
+		GROUP BY user_id, agent_id, workspace_id
+), latest_agent_stats AS (
+	SELECT
+		a.agent_id,
+		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
+		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
+		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
+		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
+		coalesce(SUM(connection_count), 0)::bigint AS connection_count,
+		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
+	 FROM (
+		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
+		FROM workspace_agent_stats
+		-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
+		WHERE created_at > $1 AND connection_median_latency_ms > 0
+	) AS a
+	WHERE a.rn = 1
+	GROUP BY a.user_id, a.agent_id, a.workspace_id
+)
 SELECT
-	(created_at at TIME ZONE cast($1::integer as text))::date as date,
-	user_id
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
+	session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty,
+	connection_count, connection_median_latency_ms
 FROM
-	workspace_agent_stats
-WHERE
-	connection_count > 0
-GROUP BY
-	date, user_id
-ORDER BY
-	date ASC
+	agent_stats
+JOIN
+	latest_agent_stats
+ON
+	agent_stats.agent_id = latest_agent_stats.agent_id
+JOIN
+	users
+ON
+	users.id = agent_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = agent_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = agent_stats.workspace_id
 `
 
-type GetDeploymentDAUsRow struct {
-	Date   time.Time `db:"date" json:"date"`
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
+type GetWorkspaceAgentStatsAndLabelsRow struct {
+	Username                    string  `db:"username" json:"username"`
+	AgentName                   string  `db:"agent_name" json:"agent_name"`
+	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
+	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
+	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
+	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
 }
 
-func (q *sqlQuerier) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]GetDeploymentDAUsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getDeploymentDAUs, tzOffset)
+func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStatsAndLabels, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetDeploymentDAUsRow
+	var items []GetWorkspaceAgentStatsAndLabelsRow
 	for rows.Next() {
-		var i GetDeploymentDAUsRow
-		if err := rows.Scan(&i.Date, &i.UserID); err != nil {
+		var i GetWorkspaceAgentStatsAndLabelsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.RxBytes,
+			&i.TxBytes,
+			&i.SessionCountVSCode,
+			&i.SessionCountSSH,
+			&i.SessionCountJetBrains,
+			&i.SessionCountReconnectingPTY,
+			&i.ConnectionCount,
+			&i.ConnectionMedianLatencyMS,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -9086,92 +15114,303 @@ func (q *sqlQuerier) GetDeploymentDAUs(ctx context.Context, tzOffset int32) ([]G
 	return items, nil
 }
 
-const getDeploymentWorkspaceAgentStats = `-- name: GetDeploymentWorkspaceAgentStats :one
-WITH agent_stats AS (
-	SELECT
-		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-	 FROM workspace_agent_stats
-	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0
-), latest_agent_stats AS (
-	SELECT
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-		FROM workspace_agent_stats WHERE created_at > $1
-	) AS a WHERE a.rn = 1
-)
-SELECT workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats, latest_agent_stats
+const insertWorkspaceAgentStat = `-- name: InsertWorkspaceAgentStat :one
+INSERT INTO
+	workspace_agent_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		template_id,
+		agent_id,
+		connections_by_proto,
+		connection_count,
+		rx_packets,
+		rx_bytes,
+		tx_packets,
+		tx_bytes,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_ssh,
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_total_cores,
+		memory_used_bytes,
+		memory_total_bytes,
+		disk_used_bytes,
+		disk_total_bytes
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, cpu_used_cores, cpu_total_cores, memory_used_bytes, memory_total_bytes, disk_used_bytes, disk_total_bytes
 `
 
-type GetDeploymentWorkspaceAgentStatsRow struct {
-	WorkspaceRxBytes             int64   `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64   `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64 `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64 `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	SessionCountVSCode           int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+type InsertWorkspaceAgentStatParams struct {
+	ID                          uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
+	UserID                      uuid.UUID       `db:"user_id" json:"user_id"`
+	WorkspaceID                 uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	TemplateID                  uuid.UUID       `db:"template_id" json:"template_id"`
+	AgentID                     uuid.UUID       `db:"agent_id" json:"agent_id"`
+	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
+	ConnectionCount             int64           `db:"connection_count" json:"connection_count"`
+	RxPackets                   int64           `db:"rx_packets" json:"rx_packets"`
+	RxBytes                     int64           `db:"rx_bytes" json:"rx_bytes"`
+	TxPackets                   int64           `db:"tx_packets" json:"tx_packets"`
+	TxBytes                     int64           `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          int64           `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
+	ConnectionMedianLatencyMS   float64         `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	CPUUsedCores                float64         `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPUTotalCores               float64         `db:"cpu_total_cores" json:"cpu_total_cores"`
+	MemoryUsedBytes             int64           `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryTotalBytes            int64           `db:"memory_total_bytes" json:"memory_total_bytes"`
+	DiskUsedBytes               int64           `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes              int64           `db:"disk_total_bytes" json:"disk_total_bytes"`
 }
 
-func (q *sqlQuerier) GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getDeploymentWorkspaceAgentStats, createdAt)
-	var i GetDeploymentWorkspaceAgentStatsRow
+func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentStat,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.TemplateID,
+		arg.AgentID,
+		arg.ConnectionsByProto,
+		arg.ConnectionCount,
+		arg.RxPackets,
+		arg.RxBytes,
+		arg.TxPackets,
+		arg.TxBytes,
+		arg.SessionCountVSCode,
+		arg.SessionCountJetBrains,
+		arg.SessionCountReconnectingPTY,
+		arg.SessionCountSSH,
+		arg.ConnectionMedianLatencyMS,
+		arg.CPUUsedCores,
+		arg.CPUTotalCores,
+		arg.MemoryUsedBytes,
+		arg.MemoryTotalBytes,
+		arg.DiskUsedBytes,
+		arg.DiskTotalBytes,
+	)
+	var i WorkspaceAgentStat
 	err := row.Scan(
-		&i.WorkspaceRxBytes,
-		&i.WorkspaceTxBytes,
-		&i.WorkspaceConnectionLatency50,
-		&i.WorkspaceConnectionLatency95,
+		&i.ID,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.AgentID,
+		&i.WorkspaceID,
+		&i.TemplateID,
+		&i.ConnectionsByProto,
+		&i.ConnectionCount,
+		&i.RxPackets,
+		&i.RxBytes,
+		&i.TxPackets,
+		&i.TxBytes,
+		&i.ConnectionMedianLatencyMS,
 		&i.SessionCountVSCode,
-		&i.SessionCountSSH,
 		&i.SessionCountJetBrains,
 		&i.SessionCountReconnectingPTY,
+		&i.SessionCountSSH,
+		&i.CPUUsedCores,
+		&i.CPUTotalCores,
+		&i.MemoryUsedBytes,
+		&i.MemoryTotalBytes,
+		&i.DiskUsedBytes,
+		&i.DiskTotalBytes,
 	)
 	return i, err
 }
 
-const getTemplateDAUs = `-- name: GetTemplateDAUs :many
+const insertWorkspaceAgentStats = `-- name: InsertWorkspaceAgentStats :exec
+INSERT INTO
+	workspace_agent_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		template_id,
+		agent_id,
+		connections_by_proto,
+		connection_count,
+		rx_packets,
+		rx_bytes,
+		tx_packets,
+		tx_bytes,
+		session_count_vscode,
+		session_count_jetbrains,
+		session_count_reconnecting_pty,
+		session_count_ssh,
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_total_cores,
+		memory_used_bytes,
+		memory_total_bytes,
+		disk_used_bytes,
+		disk_total_bytes
+	)
 SELECT
-	(created_at at TIME ZONE cast($2::integer as text))::date as date,
-	user_id
+	unnest($1 :: uuid[]) AS id,
+	unnest($2 :: timestamptz[]) AS created_at,
+	unnest($3 :: uuid[]) AS user_id,
+	unnest($4 :: uuid[]) AS workspace_id,
+	unnest($5 :: uuid[]) AS template_id,
+	unnest($6 :: uuid[]) AS agent_id,
+	jsonb_array_elements($7 :: jsonb) AS connections_by_proto,
+	unnest($8 :: bigint[]) AS connection_count,
+	unnest($9 :: bigint[]) AS rx_packets,
+	unnest($10 :: bigint[]) AS rx_bytes,
+	unnest($11 :: bigint[]) AS tx_packets,
+	unnest($12 :: bigint[]) AS tx_bytes,
+	unnest($13 :: bigint[]) AS session_count_vscode,
+	unnest($14 :: bigint[]) AS session_count_jetbrains,
+	unnest($15 :: bigint[]) AS session_count_reconnecting_pty,
+	unnest($16 :: bigint[]) AS session_count_ssh,
+	unnest($17 :: double precision[]) AS connection_median_latency_ms,
+	unnest($18 :: double precision[]) AS cpu_used_cores,
+	unnest($19 :: double precision[]) AS cpu_total_cores,
+	unnest($20 :: bigint[]) AS memory_used_bytes,
+	unnest($21 :: bigint[]) AS memory_total_bytes,
+	unnest($22 :: bigint[]) AS disk_used_bytes,
+	unnest($23 :: bigint[]) AS disk_total_bytes
+`
+
+type InsertWorkspaceAgentStatsParams struct {
+	ID                          []uuid.UUID     `db:"id" json:"id"`
+	CreatedAt                   []time.Time     `db:"created_at" json:"created_at"`
+	UserID                      []uuid.UUID     `db:"user_id" json:"user_id"`
+	WorkspaceID                 []uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	TemplateID                  []uuid.UUID     `db:"template_id" json:"template_id"`
+	AgentID                     []uuid.UUID     `db:"agent_id" json:"agent_id"`
+	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
+	ConnectionCount             []int64         `db:"connection_count" json:"connection_count"`
+	RxPackets                   []int64         `db:"rx_packets" json:"rx_packets"`
+	RxBytes                     []int64         `db:"rx_bytes" json:"rx_bytes"`
+	TxPackets                   []int64         `db:"tx_packets" json:"tx_packets"`
+	TxBytes                     []int64         `db:"tx_bytes" json:"tx_bytes"`
+	SessionCountVSCode          []int64         `db:"session_count_vscode" json:"session_count_vscode"`
+	SessionCountJetBrains       []int64         `db:"session_count_jetbrains" json:"session_count_jetbrains"`
+	SessionCountReconnectingPTY []int64         `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
+	SessionCountSSH             []int64         `db:"session_count_ssh" json:"session_count_ssh"`
+	ConnectionMedianLatencyMS   []float64       `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+	CPUUsedCores                []float64       `db:"cpu_used_cores" json:"cpu_used_cores"`
+	CPUTotalCores               []float64       `db:"cpu_total_cores" json:"cpu_total_cores"`
+	MemoryUsedBytes             []int64         `db:"memory_used_bytes" json:"memory_used_bytes"`
+	MemoryTotalBytes            []int64         `db:"memory_total_bytes" json:"memory_total_bytes"`
+	DiskUsedBytes               []int64         `db:"disk_used_bytes" json:"disk_used_bytes"`
+	DiskTotalBytes              []int64         `db:"disk_total_bytes" json:"disk_total_bytes"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentStats,
+		pq.Array(arg.ID),
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.UserID),
+		pq.Array(arg.WorkspaceID),
+		pq.Array(arg.TemplateID),
+		pq.Array(arg.AgentID),
+		arg.ConnectionsByProto,
+		pq.Array(arg.ConnectionCount),
+		pq.Array(arg.RxPackets),
+		pq.Array(arg.RxBytes),
+		pq.Array(arg.TxPackets),
+		pq.Array(arg.TxBytes),
+		pq.Array(arg.SessionCountVSCode),
+		pq.Array(arg.SessionCountJetBrains),
+		pq.Array(arg.SessionCountReconnectingPTY),
+		pq.Array(arg.SessionCountSSH),
+		pq.Array(arg.ConnectionMedianLatencyMS),
+		pq.Array(arg.CPUUsedCores),
+		pq.Array(arg.CPUTotalCores),
+		pq.Array(arg.MemoryUsedBytes),
+		pq.Array(arg.MemoryTotalBytes),
+		pq.Array(arg.DiskUsedBytes),
+		pq.Array(arg.DiskTotalBytes),
+	)
+	return err
+}
+
+const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+`
+
+type GetWorkspaceAppByAgentIDAndSlugParams struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	Slug    string    `db:"slug" json:"slug"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAppByAgentIDAndSlug, arg.AgentID, arg.Slug)
+	var i WorkspaceApp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.AgentID,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Command,
+		&i.Url,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
+		&i.Subdomain,
+		&i.SharingLevel,
+		&i.Slug,
+		&i.External,
+	)
+	return i, err
+}
+
+const getWorkspaceAppCountsByHealth = `-- name: GetWorkspaceAppCountsByHealth :many
+SELECT
+	workspace_apps.health,
+	COUNT(*) AS count
 FROM
-	workspace_agent_stats
+	workspace_apps
+JOIN
+	workspace_agents ON workspace_apps.agent_id = workspace_agents.id
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
 WHERE
-	template_id = $1 AND
-	connection_count > 0
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
 GROUP BY
-	date, user_id
-ORDER BY
-	date ASC
+	workspace_apps.health
 `
 
-type GetTemplateDAUsParams struct {
-	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
-	TzOffset   int32     `db:"tz_offset" json:"tz_offset"`
-}
-
-type GetTemplateDAUsRow struct {
-	Date   time.Time `db:"date" json:"date"`
-	UserID uuid.UUID `db:"user_id" json:"user_id"`
+type GetWorkspaceAppCountsByHealthRow struct {
+	Health WorkspaceAppHealth `db:"health" json:"health"`
+	Count  int64              `db:"count" json:"count"`
 }
 
-func (q *sqlQuerier) GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTemplateDAUs, arg.TemplateID, arg.TzOffset)
+// Counts the apps in the latest build of workspaces that are not deleted by
+// health.
+func (q *sqlQuerier) GetWorkspaceAppCountsByHealth(ctx context.Context) ([]GetWorkspaceAppCountsByHealthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppCountsByHealth)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetTemplateDAUsRow
+	var items []GetWorkspaceAppCountsByHealthRow
 	for rows.Next() {
-		var i GetTemplateDAUsRow
-		if err := rows.Scan(&i.Date, &i.UserID); err != nil {
+		var i GetWorkspaceAppCountsByHealthRow
+		if err := rows.Scan(&i.Health, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -9185,77 +15424,35 @@ func (q *sqlQuerier) GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsPar
 	return items, nil
 }
 
-const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		template_id,
-		MIN(created_at)::timestamptz AS aggregated_from,
-		coalesce(SUM(rx_bytes), 0)::bigint AS workspace_rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS workspace_tx_bytes,
-		coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_50,
-		coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY connection_median_latency_ms)), -1)::FLOAT AS workspace_connection_latency_95
-	 FROM workspace_agent_stats
-	 	-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-		WHERE workspace_agent_stats.created_at > $1 AND connection_median_latency_ms > 0 GROUP BY user_id, agent_id, workspace_id, template_id
-), latest_agent_stats AS (
-	SELECT
-		a.agent_id,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty
-	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-		FROM workspace_agent_stats WHERE created_at > $1
-	) AS a WHERE a.rn = 1 GROUP BY a.user_id, a.agent_id, a.workspace_id, a.template_id
-)
-SELECT user_id, agent_stats.agent_id, workspace_id, template_id, aggregated_from, workspace_rx_bytes, workspace_tx_bytes, workspace_connection_latency_50, workspace_connection_latency_95, latest_agent_stats.agent_id, session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty FROM agent_stats JOIN latest_agent_stats ON agent_stats.agent_id = latest_agent_stats.agent_id
+const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
 `
 
-type GetWorkspaceAgentStatsRow struct {
-	UserID                       uuid.UUID `db:"user_id" json:"user_id"`
-	AgentID                      uuid.UUID `db:"agent_id" json:"agent_id"`
-	WorkspaceID                  uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	TemplateID                   uuid.UUID `db:"template_id" json:"template_id"`
-	AggregatedFrom               time.Time `db:"aggregated_from" json:"aggregated_from"`
-	WorkspaceRxBytes             int64     `db:"workspace_rx_bytes" json:"workspace_rx_bytes"`
-	WorkspaceTxBytes             int64     `db:"workspace_tx_bytes" json:"workspace_tx_bytes"`
-	WorkspaceConnectionLatency50 float64   `db:"workspace_connection_latency_50" json:"workspace_connection_latency_50"`
-	WorkspaceConnectionLatency95 float64   `db:"workspace_connection_latency_95" json:"workspace_connection_latency_95"`
-	AgentID_2                    uuid.UUID `db:"agent_id_2" json:"agent_id_2"`
-	SessionCountVSCode           int64     `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH              int64     `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains        int64     `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY  int64     `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-}
-
-func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStats, createdAt)
+func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentID, agentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentStatsRow
+	var items []WorkspaceApp
 	for rows.Next() {
-		var i GetWorkspaceAgentStatsRow
+		var i WorkspaceApp
 		if err := rows.Scan(
-			&i.UserID,
+			&i.ID,
+			&i.CreatedAt,
 			&i.AgentID,
-			&i.WorkspaceID,
-			&i.TemplateID,
-			&i.AggregatedFrom,
-			&i.WorkspaceRxBytes,
-			&i.WorkspaceTxBytes,
-			&i.WorkspaceConnectionLatency50,
-			&i.WorkspaceConnectionLatency95,
-			&i.AgentID_2,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
 		); err != nil {
 			return nil, err
 		}
@@ -9270,94 +15467,78 @@ func (q *sqlQuerier) GetWorkspaceAgentStats(ctx context.Context, createdAt time.
 	return items, nil
 }
 
-const getWorkspaceAgentStatsAndLabels = `-- name: GetWorkspaceAgentStatsAndLabels :many
-WITH agent_stats AS (
-	SELECT
-		user_id,
-		agent_id,
-		workspace_id,
-		coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
-		coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
-	 FROM workspace_agent_stats
-		WHERE workspace_agent_stats.created_at > $1
-		GROUP BY user_id, agent_id, workspace_id
-), latest_agent_stats AS (
-	SELECT
-		a.agent_id,
-		coalesce(SUM(session_count_vscode), 0)::bigint AS session_count_vscode,
-		coalesce(SUM(session_count_ssh), 0)::bigint AS session_count_ssh,
-		coalesce(SUM(session_count_jetbrains), 0)::bigint AS session_count_jetbrains,
-		coalesce(SUM(session_count_reconnecting_pty), 0)::bigint AS session_count_reconnecting_pty,
-		coalesce(SUM(connection_count), 0)::bigint AS connection_count,
-		coalesce(MAX(connection_median_latency_ms), 0)::float AS connection_median_latency_ms
-	 FROM (
-		SELECT id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh, ROW_NUMBER() OVER(PARTITION BY agent_id ORDER BY created_at DESC) AS rn
-		FROM workspace_agent_stats
-		-- The greater than 0 is to support legacy agents that don't report connection_median_latency_ms.
-		WHERE created_at > $1 AND connection_median_latency_ms > 0
-	) AS a
-	WHERE a.rn = 1
-	GROUP BY a.user_id, a.agent_id, a.workspace_id
-)
-SELECT
-	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name, rx_bytes, tx_bytes,
-	session_count_vscode, session_count_ssh, session_count_jetbrains, session_count_reconnecting_pty,
-	connection_count, connection_median_latency_ms
-FROM
-	agent_stats
-JOIN
-	latest_agent_stats
-ON
-	agent_stats.agent_id = latest_agent_stats.agent_id
-JOIN
-	users
-ON
-	users.id = agent_stats.user_id
-JOIN
-	workspace_agents
-ON
-	workspace_agents.id = agent_stats.agent_id
-JOIN
-	workspaces
-ON
-	workspaces.id = agent_stats.workspace_id
+const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
 `
 
-type GetWorkspaceAgentStatsAndLabelsRow struct {
-	Username                    string  `db:"username" json:"username"`
-	AgentName                   string  `db:"agent_name" json:"agent_name"`
-	WorkspaceName               string  `db:"workspace_name" json:"workspace_name"`
-	RxBytes                     int64   `db:"rx_bytes" json:"rx_bytes"`
-	TxBytes                     int64   `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          int64   `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountSSH             int64   `db:"session_count_ssh" json:"session_count_ssh"`
-	SessionCountJetBrains       int64   `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY int64   `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	ConnectionCount             int64   `db:"connection_count" json:"connection_count"`
-	ConnectionMedianLatencyMS   float64 `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceApp
+	for rows.Next() {
+		var i WorkspaceApp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.AgentID,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentStatsAndLabels, createdAt)
+const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetWorkspaceAgentStatsAndLabelsRow
+	var items []WorkspaceApp
 	for rows.Next() {
-		var i GetWorkspaceAgentStatsAndLabelsRow
+		var i WorkspaceApp
 		if err := rows.Scan(
-			&i.Username,
-			&i.AgentName,
-			&i.WorkspaceName,
-			&i.RxBytes,
-			&i.TxBytes,
-			&i.SessionCountVSCode,
-			&i.SessionCountSSH,
-			&i.SessionCountJetBrains,
-			&i.SessionCountReconnectingPTY,
-			&i.ConnectionCount,
-			&i.ConnectionMedianLatencyMS,
+			&i.ID,
+			&i.CreatedAt,
+			&i.AgentID,
+			&i.DisplayName,
+			&i.Icon,
+			&i.Command,
+			&i.Url,
+			&i.HealthcheckUrl,
+			&i.HealthcheckInterval,
+			&i.HealthcheckThreshold,
+			&i.Health,
+			&i.Subdomain,
+			&i.SharingLevel,
+			&i.Slug,
+			&i.External,
 		); err != nil {
 			return nil, err
 		}
@@ -9372,239 +15553,283 @@ func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, create
 	return items, nil
 }
 
-const insertWorkspaceAgentStat = `-- name: InsertWorkspaceAgentStat :one
+const insertWorkspaceApp = `-- name: InsertWorkspaceApp :one
 INSERT INTO
-	workspace_agent_stats (
-		id,
-		created_at,
-		user_id,
-		workspace_id,
-		template_id,
-		agent_id,
-		connections_by_proto,
-		connection_count,
-		rx_packets,
-		rx_bytes,
-		tx_packets,
-		tx_bytes,
-		session_count_vscode,
-		session_count_jetbrains,
-		session_count_reconnecting_pty,
-		session_count_ssh,
-		connection_median_latency_ms
-	)
+    workspace_apps (
+        id,
+        created_at,
+        agent_id,
+        slug,
+        display_name,
+        icon,
+        command,
+        url,
+        external,
+        subdomain,
+        sharing_level,
+        healthcheck_url,
+        healthcheck_interval,
+        healthcheck_threshold,
+        health
+    )
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at, user_id, agent_id, workspace_id, template_id, connections_by_proto, connection_count, rx_packets, rx_bytes, tx_packets, tx_bytes, connection_median_latency_ms, session_count_vscode, session_count_jetbrains, session_count_reconnecting_pty, session_count_ssh
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external
 `
 
-type InsertWorkspaceAgentStatParams struct {
-	ID                          uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
-	UserID                      uuid.UUID       `db:"user_id" json:"user_id"`
-	WorkspaceID                 uuid.UUID       `db:"workspace_id" json:"workspace_id"`
-	TemplateID                  uuid.UUID       `db:"template_id" json:"template_id"`
-	AgentID                     uuid.UUID       `db:"agent_id" json:"agent_id"`
-	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
-	ConnectionCount             int64           `db:"connection_count" json:"connection_count"`
-	RxPackets                   int64           `db:"rx_packets" json:"rx_packets"`
-	RxBytes                     int64           `db:"rx_bytes" json:"rx_bytes"`
-	TxPackets                   int64           `db:"tx_packets" json:"tx_packets"`
-	TxBytes                     int64           `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          int64           `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountJetBrains       int64           `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY int64           `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	SessionCountSSH             int64           `db:"session_count_ssh" json:"session_count_ssh"`
-	ConnectionMedianLatencyMS   float64         `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+type InsertWorkspaceAppParams struct {
+	ID                   uuid.UUID          `db:"id" json:"id"`
+	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
+	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
+	Slug                 string             `db:"slug" json:"slug"`
+	DisplayName          string             `db:"display_name" json:"display_name"`
+	Icon                 string             `db:"icon" json:"icon"`
+	Command              sql.NullString     `db:"command" json:"command"`
+	Url                  sql.NullString     `db:"url" json:"url"`
+	External             bool               `db:"external" json:"external"`
+	Subdomain            bool               `db:"subdomain" json:"subdomain"`
+	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
+	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
+	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
+	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
+	Health               WorkspaceAppHealth `db:"health" json:"health"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentStat,
+func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceApp,
 		arg.ID,
 		arg.CreatedAt,
-		arg.UserID,
-		arg.WorkspaceID,
-		arg.TemplateID,
 		arg.AgentID,
-		arg.ConnectionsByProto,
-		arg.ConnectionCount,
-		arg.RxPackets,
-		arg.RxBytes,
-		arg.TxPackets,
-		arg.TxBytes,
-		arg.SessionCountVSCode,
-		arg.SessionCountJetBrains,
-		arg.SessionCountReconnectingPTY,
-		arg.SessionCountSSH,
-		arg.ConnectionMedianLatencyMS,
+		arg.Slug,
+		arg.DisplayName,
+		arg.Icon,
+		arg.Command,
+		arg.Url,
+		arg.External,
+		arg.Subdomain,
+		arg.SharingLevel,
+		arg.HealthcheckUrl,
+		arg.HealthcheckInterval,
+		arg.HealthcheckThreshold,
+		arg.Health,
 	)
-	var i WorkspaceAgentStat
+	var i WorkspaceApp
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.UserID,
 		&i.AgentID,
-		&i.WorkspaceID,
-		&i.TemplateID,
-		&i.ConnectionsByProto,
-		&i.ConnectionCount,
-		&i.RxPackets,
-		&i.RxBytes,
-		&i.TxPackets,
-		&i.TxBytes,
-		&i.ConnectionMedianLatencyMS,
-		&i.SessionCountVSCode,
-		&i.SessionCountJetBrains,
-		&i.SessionCountReconnectingPTY,
-		&i.SessionCountSSH,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Command,
+		&i.Url,
+		&i.HealthcheckUrl,
+		&i.HealthcheckInterval,
+		&i.HealthcheckThreshold,
+		&i.Health,
+		&i.Subdomain,
+		&i.SharingLevel,
+		&i.Slug,
+		&i.External,
 	)
 	return i, err
 }
 
-const insertWorkspaceAgentStats = `-- name: InsertWorkspaceAgentStats :exec
+const updateWorkspaceAppHealthByID = `-- name: UpdateWorkspaceAppHealthByID :exec
+UPDATE
+	workspace_apps
+SET
+	health = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAppHealthByIDParams struct {
+	ID     uuid.UUID          `db:"id" json:"id"`
+	Health WorkspaceAppHealth `db:"health" json:"health"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAppHealthByID, arg.ID, arg.Health)
+	return err
+}
+
+const insertWorkspaceAppStats = `-- name: InsertWorkspaceAppStats :exec
 INSERT INTO
-	workspace_agent_stats (
-		id,
-		created_at,
+	workspace_app_stats (
 		user_id,
 		workspace_id,
-		template_id,
 		agent_id,
-		connections_by_proto,
-		connection_count,
-		rx_packets,
-		rx_bytes,
-		tx_packets,
-		tx_bytes,
-		session_count_vscode,
-		session_count_jetbrains,
-		session_count_reconnecting_pty,
-		session_count_ssh,
-		connection_median_latency_ms
+		access_method,
+		slug_or_port,
+		session_id,
+		session_started_at,
+		session_ended_at,
+		requests
 	)
 SELECT
-	unnest($1 :: uuid[]) AS id,
-	unnest($2 :: timestamptz[]) AS created_at,
-	unnest($3 :: uuid[]) AS user_id,
-	unnest($4 :: uuid[]) AS workspace_id,
-	unnest($5 :: uuid[]) AS template_id,
-	unnest($6 :: uuid[]) AS agent_id,
-	jsonb_array_elements($7 :: jsonb) AS connections_by_proto,
-	unnest($8 :: bigint[]) AS connection_count,
-	unnest($9 :: bigint[]) AS rx_packets,
-	unnest($10 :: bigint[]) AS rx_bytes,
-	unnest($11 :: bigint[]) AS tx_packets,
-	unnest($12 :: bigint[]) AS tx_bytes,
-	unnest($13 :: bigint[]) AS session_count_vscode,
-	unnest($14 :: bigint[]) AS session_count_jetbrains,
-	unnest($15 :: bigint[]) AS session_count_reconnecting_pty,
-	unnest($16 :: bigint[]) AS session_count_ssh,
-	unnest($17 :: double precision[]) AS connection_median_latency_ms
+	unnest($1::uuid[]) AS user_id,
+	unnest($2::uuid[]) AS workspace_id,
+	unnest($3::uuid[]) AS agent_id,
+	unnest($4::text[]) AS access_method,
+	unnest($5::text[]) AS slug_or_port,
+	unnest($6::uuid[]) AS session_id,
+	unnest($7::timestamptz[]) AS session_started_at,
+	unnest($8::timestamptz[]) AS session_ended_at,
+	unnest($9::int[]) AS requests
+ON CONFLICT
+	(user_id, agent_id, session_id)
+DO
+	UPDATE SET
+		session_ended_at = EXCLUDED.session_ended_at,
+		requests = EXCLUDED.requests
+	WHERE
+		workspace_app_stats.user_id = EXCLUDED.user_id
+		AND workspace_app_stats.agent_id = EXCLUDED.agent_id
+		AND workspace_app_stats.session_id = EXCLUDED.session_id
+		-- Since stats are updated in place as time progresses, we only
+		-- want to update this row if it's fresh.
+		AND workspace_app_stats.session_ended_at <= EXCLUDED.session_ended_at
+		AND workspace_app_stats.requests <= EXCLUDED.requests
 `
 
-type InsertWorkspaceAgentStatsParams struct {
-	ID                          []uuid.UUID     `db:"id" json:"id"`
-	CreatedAt                   []time.Time     `db:"created_at" json:"created_at"`
-	UserID                      []uuid.UUID     `db:"user_id" json:"user_id"`
-	WorkspaceID                 []uuid.UUID     `db:"workspace_id" json:"workspace_id"`
-	TemplateID                  []uuid.UUID     `db:"template_id" json:"template_id"`
-	AgentID                     []uuid.UUID     `db:"agent_id" json:"agent_id"`
-	ConnectionsByProto          json.RawMessage `db:"connections_by_proto" json:"connections_by_proto"`
-	ConnectionCount             []int64         `db:"connection_count" json:"connection_count"`
-	RxPackets                   []int64         `db:"rx_packets" json:"rx_packets"`
-	RxBytes                     []int64         `db:"rx_bytes" json:"rx_bytes"`
-	TxPackets                   []int64         `db:"tx_packets" json:"tx_packets"`
-	TxBytes                     []int64         `db:"tx_bytes" json:"tx_bytes"`
-	SessionCountVSCode          []int64         `db:"session_count_vscode" json:"session_count_vscode"`
-	SessionCountJetBrains       []int64         `db:"session_count_jetbrains" json:"session_count_jetbrains"`
-	SessionCountReconnectingPTY []int64         `db:"session_count_reconnecting_pty" json:"session_count_reconnecting_pty"`
-	SessionCountSSH             []int64         `db:"session_count_ssh" json:"session_count_ssh"`
-	ConnectionMedianLatencyMS   []float64       `db:"connection_median_latency_ms" json:"connection_median_latency_ms"`
+type InsertWorkspaceAppStatsParams struct {
+	UserID           []uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID      []uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentID          []uuid.UUID `db:"agent_id" json:"agent_id"`
+	AccessMethod     []string    `db:"access_method" json:"access_method"`
+	SlugOrPort       []string    `db:"slug_or_port" json:"slug_or_port"`
+	SessionID        []uuid.UUID `db:"session_id" json:"session_id"`
+	SessionStartedAt []time.Time `db:"session_started_at" json:"session_started_at"`
+	SessionEndedAt   []time.Time `db:"session_ended_at" json:"session_ended_at"`
+	Requests         []int32     `db:"requests" json:"requests"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentStats,
-		pq.Array(arg.ID),
-		pq.Array(arg.CreatedAt),
+func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAppStats,
 		pq.Array(arg.UserID),
 		pq.Array(arg.WorkspaceID),
-		pq.Array(arg.TemplateID),
 		pq.Array(arg.AgentID),
-		arg.ConnectionsByProto,
-		pq.Array(arg.ConnectionCount),
-		pq.Array(arg.RxPackets),
-		pq.Array(arg.RxBytes),
-		pq.Array(arg.TxPackets),
-		pq.Array(arg.TxBytes),
-		pq.Array(arg.SessionCountVSCode),
-		pq.Array(arg.SessionCountJetBrains),
-		pq.Array(arg.SessionCountReconnectingPTY),
-		pq.Array(arg.SessionCountSSH),
-		pq.Array(arg.ConnectionMedianLatencyMS),
+		pq.Array(arg.AccessMethod),
+		pq.Array(arg.SlugOrPort),
+		pq.Array(arg.SessionID),
+		pq.Array(arg.SessionStartedAt),
+		pq.Array(arg.SessionEndedAt),
+		pq.Array(arg.Requests),
 	)
 	return err
 }
 
-const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+const getWorkspaceBuildParameters = `-- name: GetWorkspaceBuildParameters :many
+SELECT
+    workspace_build_id, name, value
+FROM
+    workspace_build_parameters
+WHERE
+    workspace_build_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildParameters, workspaceBuildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildParameter
+	for rows.Next() {
+		var i WorkspaceBuildParameter
+		if err := rows.Scan(&i.WorkspaceBuildID, &i.Name, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceBuildParameters = `-- name: InsertWorkspaceBuildParameters :exec
+INSERT INTO
+    workspace_build_parameters (workspace_build_id, name, value)
+SELECT
+    $1 :: uuid AS workspace_build_id,
+    unnest($2 :: text[]) AS name,
+    unnest($3 :: text[]) AS value
+RETURNING workspace_build_id, name, value
 `
 
-type GetWorkspaceAppByAgentIDAndSlugParams struct {
-	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
-	Slug    string    `db:"slug" json:"slug"`
+type InsertWorkspaceBuildParametersParams struct {
+	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
+	Name             []string  `db:"name" json:"name"`
+	Value            []string  `db:"value" json:"value"`
 }
 
-func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceAppByAgentIDAndSlug, arg.AgentID, arg.Slug)
-	var i WorkspaceApp
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.AgentID,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Command,
-		&i.Url,
-		&i.HealthcheckUrl,
-		&i.HealthcheckInterval,
-		&i.HealthcheckThreshold,
-		&i.Health,
-		&i.Subdomain,
-		&i.SharingLevel,
-		&i.Slug,
-		&i.External,
-	)
-	return i, err
+func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildParameters, arg.WorkspaceBuildID, pq.Array(arg.Name), pq.Array(arg.Value))
+	return err
 }
 
-const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
+const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.attempt, wb.retry_at, wb.initiator_by_avatar_url, wb.initiator_by_username
+FROM (
+    SELECT
+        workspace_id, MAX(build_number) as max_build_number
+    FROM
+		workspace_build_with_user AS workspace_builds
+    WHERE
+        workspace_id IN (
+			SELECT
+				id
+			FROM
+				workspaces
+			WHERE
+				template_id = $1
+		)
+    GROUP BY
+        workspace_id
+) m
+JOIN
+	workspace_build_with_user AS wb
+	ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
+JOIN
+	provisioner_jobs AS pj
+	ON wb.job_id = pj.id
+WHERE
+	wb.transition = 'start'::workspace_transition
+AND
+	pj.completed_at IS NOT NULL
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentID, agentID)
+func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getActiveWorkspaceBuildsByTemplateID, templateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceApp
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.ProvisionerState,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.Attempt,
+			&i.RetryAt,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
 		); err != nil {
 			return nil, err
 		}
@@ -9619,35 +15844,88 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 	return items, nil
 }
 
-const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
+const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_id = $1
+ORDER BY
+    build_number desc
+LIMIT
+	1
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsByAgentIDs, pq.Array(ids))
+func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildByWorkspaceID, workspaceID)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.Attempt,
+		&i.RetryAt,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+	)
+	return i, err
+}
+
+const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.attempt, wb.retry_at, wb.initiator_by_avatar_url, wb.initiator_by_username
+FROM (
+    SELECT
+        workspace_id, MAX(build_number) as max_build_number
+    FROM
+		workspace_build_with_user AS workspace_builds
+    GROUP BY
+        workspace_id
+) m
+JOIN
+	 workspace_build_with_user AS wb
+ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuilds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceApp
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.ProvisionerState,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.Attempt,
+			&i.RetryAt,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
 		); err != nil {
 			return nil, err
 		}
@@ -9662,35 +15940,51 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 	return items, nil
 }
 
-const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.attempt, wb.retry_at, wb.initiator_by_avatar_url, wb.initiator_by_username
+FROM (
+    SELECT
+        workspace_id, MAX(build_number) as max_build_number
+    FROM
+		workspace_build_with_user AS workspace_builds
+    WHERE
+        workspace_id = ANY($1 :: uuid [ ])
+    GROUP BY
+        workspace_id
+) m
+JOIN
+	 workspace_build_with_user AS wb
+ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
 `
 
-func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceAppsCreatedAfter, createdAt)
+func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuildsByWorkspaceIDs, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceApp
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceApp
+		var i WorkspaceBuild
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.AgentID,
-			&i.DisplayName,
-			&i.Icon,
-			&i.Command,
-			&i.Url,
-			&i.HealthcheckUrl,
-			&i.HealthcheckInterval,
-			&i.HealthcheckThreshold,
-			&i.Health,
-			&i.Subdomain,
-			&i.SharingLevel,
-			&i.Slug,
-			&i.External,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.ProvisionerState,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.Attempt,
+			&i.RetryAt,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
 		); err != nil {
 			return nil, err
 		}
@@ -9705,190 +15999,206 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 	return items, nil
 }
 
-const insertWorkspaceApp = `-- name: InsertWorkspaceApp :one
-INSERT INTO
-    workspace_apps (
-        id,
-        created_at,
-        agent_id,
-        slug,
-        display_name,
-        icon,
-        command,
-        url,
-        external,
-        subdomain,
-        sharing_level,
-        healthcheck_url,
-        healthcheck_interval,
-        healthcheck_threshold,
-        health
-    )
-VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external
+const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	id = $1
+LIMIT
+	1
 `
 
-type InsertWorkspaceAppParams struct {
-	ID                   uuid.UUID          `db:"id" json:"id"`
-	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
-	AgentID              uuid.UUID          `db:"agent_id" json:"agent_id"`
-	Slug                 string             `db:"slug" json:"slug"`
-	DisplayName          string             `db:"display_name" json:"display_name"`
-	Icon                 string             `db:"icon" json:"icon"`
-	Command              sql.NullString     `db:"command" json:"command"`
-	Url                  sql.NullString     `db:"url" json:"url"`
-	External             bool               `db:"external" json:"external"`
-	Subdomain            bool               `db:"subdomain" json:"subdomain"`
-	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
-	HealthcheckUrl       string             `db:"healthcheck_url" json:"healthcheck_url"`
-	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
-	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
-	Health               WorkspaceAppHealth `db:"health" json:"health"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error) {
-	row := q.db.QueryRowContext(ctx, insertWorkspaceApp,
-		arg.ID,
-		arg.CreatedAt,
-		arg.AgentID,
-		arg.Slug,
-		arg.DisplayName,
-		arg.Icon,
-		arg.Command,
-		arg.Url,
-		arg.External,
-		arg.Subdomain,
-		arg.SharingLevel,
-		arg.HealthcheckUrl,
-		arg.HealthcheckInterval,
-		arg.HealthcheckThreshold,
-		arg.Health,
-	)
-	var i WorkspaceApp
+func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByID, id)
+	var i WorkspaceBuild
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
-		&i.AgentID,
-		&i.DisplayName,
-		&i.Icon,
-		&i.Command,
-		&i.Url,
-		&i.HealthcheckUrl,
-		&i.HealthcheckInterval,
-		&i.HealthcheckThreshold,
-		&i.Health,
-		&i.Subdomain,
-		&i.SharingLevel,
-		&i.Slug,
-		&i.External,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.Attempt,
+		&i.RetryAt,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
 	)
 	return i, err
 }
 
-const updateWorkspaceAppHealthByID = `-- name: UpdateWorkspaceAppHealthByID :exec
-UPDATE
-	workspace_apps
-SET
-	health = $2
+const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
+SELECT
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username
+FROM
+	workspace_build_with_user AS workspace_builds
 WHERE
-	id = $1
+	job_id = $1
+LIMIT
+	1
 `
 
-type UpdateWorkspaceAppHealthByIDParams struct {
-	ID     uuid.UUID          `db:"id" json:"id"`
-	Health WorkspaceAppHealth `db:"health" json:"health"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAppHealthByID, arg.ID, arg.Health)
-	return err
+func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByJobID, jobID)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.Attempt,
+		&i.RetryAt,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
+	)
+	return i, err
 }
 
-const insertWorkspaceAppStats = `-- name: InsertWorkspaceAppStats :exec
-INSERT INTO
-	workspace_app_stats (
-		user_id,
-		workspace_id,
-		agent_id,
-		access_method,
-		slug_or_port,
-		session_id,
-		session_started_at,
-		session_ended_at,
-		requests
-	)
+const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	unnest($1::uuid[]) AS user_id,
-	unnest($2::uuid[]) AS workspace_id,
-	unnest($3::uuid[]) AS agent_id,
-	unnest($4::text[]) AS access_method,
-	unnest($5::text[]) AS slug_or_port,
-	unnest($6::uuid[]) AS session_id,
-	unnest($7::timestamptz[]) AS session_started_at,
-	unnest($8::timestamptz[]) AS session_ended_at,
-	unnest($9::int[]) AS requests
-ON CONFLICT
-	(user_id, agent_id, session_id)
-DO
-	UPDATE SET
-		session_ended_at = EXCLUDED.session_ended_at,
-		requests = EXCLUDED.requests
-	WHERE
-		workspace_app_stats.user_id = EXCLUDED.user_id
-		AND workspace_app_stats.agent_id = EXCLUDED.agent_id
-		AND workspace_app_stats.session_id = EXCLUDED.session_id
-		-- Since stats are updated in place as time progresses, we only
-		-- want to update this row if it's fresh.
-		AND workspace_app_stats.session_ended_at <= EXCLUDED.session_ended_at
-		AND workspace_app_stats.requests <= EXCLUDED.requests
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username
+FROM
+	workspace_build_with_user AS workspace_builds
+WHERE
+	workspace_id = $1
+	AND build_number = $2
 `
 
-type InsertWorkspaceAppStatsParams struct {
-	UserID           []uuid.UUID `db:"user_id" json:"user_id"`
-	WorkspaceID      []uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	AgentID          []uuid.UUID `db:"agent_id" json:"agent_id"`
-	AccessMethod     []string    `db:"access_method" json:"access_method"`
-	SlugOrPort       []string    `db:"slug_or_port" json:"slug_or_port"`
-	SessionID        []uuid.UUID `db:"session_id" json:"session_id"`
-	SessionStartedAt []time.Time `db:"session_started_at" json:"session_started_at"`
-	SessionEndedAt   []time.Time `db:"session_ended_at" json:"session_ended_at"`
-	Requests         []int32     `db:"requests" json:"requests"`
+type GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	BuildNumber int32     `db:"build_number" json:"build_number"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceAppStats,
-		pq.Array(arg.UserID),
-		pq.Array(arg.WorkspaceID),
-		pq.Array(arg.AgentID),
-		pq.Array(arg.AccessMethod),
-		pq.Array(arg.SlugOrPort),
-		pq.Array(arg.SessionID),
-		pq.Array(arg.SessionStartedAt),
-		pq.Array(arg.SessionEndedAt),
-		pq.Array(arg.Requests),
+func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByWorkspaceIDAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
+	var i WorkspaceBuild
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.TemplateVersionID,
+		&i.BuildNumber,
+		&i.Transition,
+		&i.InitiatorID,
+		&i.ProvisionerState,
+		&i.JobID,
+		&i.Deadline,
+		&i.Reason,
+		&i.DailyCost,
+		&i.MaxDeadline,
+		&i.Attempt,
+		&i.RetryAt,
+		&i.InitiatorByAvatarUrl,
+		&i.InitiatorByUsername,
 	)
-	return err
+	return i, err
 }
 
-const getWorkspaceBuildParameters = `-- name: GetWorkspaceBuildParameters :many
+const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
-    workspace_build_id, name, value
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username
 FROM
-    workspace_build_parameters
+	workspace_build_with_user AS workspace_builds
 WHERE
-    workspace_build_id = $1
+	workspace_builds.workspace_id = $1
+	AND workspace_builds.created_at > $2
+    AND CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		-- This is an important option for scripts that need to paginate without
+		-- duplicating or missing data.
+		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			-- The pagination cursor is the last ID of the previous page.
+			-- The query is ordered by the build_number field, so select all
+			-- rows after the cursor.
+			build_number > (
+				SELECT
+					build_number
+				FROM
+					workspace_builds
+				WHERE
+					id = $3
+			)
+		)
+		ELSE true
+END
+	-- Filter by the reason the build was created, e.g. autostart or batch.
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			workspace_builds.reason = $4 :: build_reason
+		ELSE true
+	END
+ORDER BY
+    build_number desc OFFSET $5
+LIMIT
+    -- A null limit means "no limit", so 0 means return all
+    NULLIF($6 :: int, 0)
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildParameters, workspaceBuildID)
+type GetWorkspaceBuildsByWorkspaceIDParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Since       time.Time `db:"since" json:"since"`
+	AfterID     uuid.UUID `db:"after_id" json:"after_id"`
+	Reason      string    `db:"reason" json:"reason"`
+	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
+	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsByWorkspaceID,
+		arg.WorkspaceID,
+		arg.Since,
+		arg.AfterID,
+		arg.Reason,
+		arg.OffsetOpt,
+		arg.LimitOpt,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuildParameter
+	var items []WorkspaceBuild
 	for rows.Next() {
-		var i WorkspaceBuildParameter
-		if err := rows.Scan(&i.WorkspaceBuildID, &i.Name, &i.Value); err != nil {
+		var i WorkspaceBuild
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WorkspaceID,
+			&i.TemplateVersionID,
+			&i.BuildNumber,
+			&i.Transition,
+			&i.InitiatorID,
+			&i.ProvisionerState,
+			&i.JobID,
+			&i.Deadline,
+			&i.Reason,
+			&i.DailyCost,
+			&i.MaxDeadline,
+			&i.Attempt,
+			&i.RetryAt,
+			&i.InitiatorByAvatarUrl,
+			&i.InitiatorByUsername,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -9902,60 +16212,12 @@ func (q *sqlQuerier) GetWorkspaceBuildParameters(ctx context.Context, workspaceB
 	return items, nil
 }
 
-const insertWorkspaceBuildParameters = `-- name: InsertWorkspaceBuildParameters :exec
-INSERT INTO
-    workspace_build_parameters (workspace_build_id, name, value)
-SELECT
-    $1 :: uuid AS workspace_build_id,
-    unnest($2 :: text[]) AS name,
-    unnest($3 :: text[]) AS value
-RETURNING workspace_build_id, name, value
-`
-
-type InsertWorkspaceBuildParametersParams struct {
-	WorkspaceBuildID uuid.UUID `db:"workspace_build_id" json:"workspace_build_id"`
-	Name             []string  `db:"name" json:"name"`
-	Value            []string  `db:"value" json:"value"`
-}
-
-func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceBuildParameters, arg.WorkspaceBuildID, pq.Array(arg.Name), pq.Array(arg.Value))
-	return err
-}
-
-const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
-FROM (
-    SELECT
-        workspace_id, MAX(build_number) as max_build_number
-    FROM
-		workspace_build_with_user AS workspace_builds
-    WHERE
-        workspace_id IN (
-			SELECT
-				id
-			FROM
-				workspaces
-			WHERE
-				template_id = $1
-		)
-    GROUP BY
-        workspace_id
-) m
-JOIN
-	workspace_build_with_user AS wb
-	ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
-JOIN
-	provisioner_jobs AS pj
-	ON wb.job_id = pj.id
-WHERE
-	wb.transition = 'start'::workspace_transition
-AND
-	pj.completed_at IS NOT NULL
+const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, attempt, retry_at, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
 `
 
-func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, templateID uuid.UUID) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getActiveWorkspaceBuildsByTemplateID, templateID)
+func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsCreatedAfter, createdAt)
 	if err != nil {
 		return nil, err
 	}
@@ -9978,6 +16240,8 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.Attempt,
+			&i.RetryAt,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -9994,145 +16258,254 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 	return items, nil
 }
 
-const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
+const insertWorkspaceBuild = `-- name: InsertWorkspaceBuild :exec
+INSERT INTO
+	workspace_builds (
+		id,
+		created_at,
+		updated_at,
+		workspace_id,
+		template_version_id,
+		"build_number",
+		transition,
+		initiator_id,
+		job_id,
+		provisioner_state,
+		deadline,
+		max_deadline,
+		reason,
+		attempt
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+`
+
+type InsertWorkspaceBuildParams struct {
+	ID                uuid.UUID           `db:"id" json:"id"`
+	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
+	WorkspaceID       uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID uuid.UUID           `db:"template_version_id" json:"template_version_id"`
+	BuildNumber       int32               `db:"build_number" json:"build_number"`
+	Transition        WorkspaceTransition `db:"transition" json:"transition"`
+	InitiatorID       uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	JobID             uuid.UUID           `db:"job_id" json:"job_id"`
+	ProvisionerState  []byte              `db:"provisioner_state" json:"provisioner_state"`
+	Deadline          time.Time           `db:"deadline" json:"deadline"`
+	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
+	Reason            BuildReason         `db:"reason" json:"reason"`
+	Attempt           int32               `db:"attempt" json:"attempt"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceBuild,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.WorkspaceID,
+		arg.TemplateVersionID,
+		arg.BuildNumber,
+		arg.Transition,
+		arg.InitiatorID,
+		arg.JobID,
+		arg.ProvisionerState,
+		arg.Deadline,
+		arg.MaxDeadline,
+		arg.Reason,
+		arg.Attempt,
+	)
+	return err
+}
+
+const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+UPDATE
+	workspace_builds
+SET
+	daily_cost = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildCostByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
+}
+
+const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
+UPDATE
+	workspace_builds
+SET
+	deadline = $1::timestamptz,
+	max_deadline = $2::timestamptz,
+	updated_at = $3::timestamptz
+WHERE id = $4::uuid
+`
+
+type UpdateWorkspaceBuildDeadlineByIDParams struct {
+	Deadline    time.Time `db:"deadline" json:"deadline"`
+	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
+		arg.Deadline,
+		arg.MaxDeadline,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
+UPDATE
+	workspace_builds
+SET
+	provisioner_state = $1::bytea,
+	updated_at = $2::timestamptz
+WHERE id = $3::uuid
+`
+
+type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ID               uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const updateWorkspaceBuildRetryAtByID = `-- name: UpdateWorkspaceBuildRetryAtByID :exec
+UPDATE
+	workspace_builds
+SET
+	retry_at = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildRetryAtByIDParams struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	RetryAt   sql.NullTime `db:"retry_at" json:"retry_at"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+}
+
+// Schedules a failed build to be retried, or clears the schedule with NULL.
+func (q *sqlQuerier) UpdateWorkspaceBuildRetryAtByID(ctx context.Context, arg UpdateWorkspaceBuildRetryAtByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildRetryAtByID, arg.ID, arg.RetryAt, arg.UpdatedAt)
+	return err
+}
+
+const getDriftedWorkspaceCount = `-- name: GetDriftedWorkspaceCount :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	COUNT(*)
 FROM
-	workspace_build_with_user AS workspace_builds
+	workspace_drift
+JOIN workspaces ON
+	workspaces.id = workspace_drift.workspace_id
 WHERE
-	workspace_id = $1
-ORDER BY
-    build_number desc
-LIMIT
-	1
+	cardinality(workspace_drift.drifted_resources) > 0
+	AND NOT workspaces.deleted
 `
 
-func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getLatestWorkspaceBuildByWorkspaceID, workspaceID)
-	var i WorkspaceBuild
+func (q *sqlQuerier) GetDriftedWorkspaceCount(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getDriftedWorkspaceCount)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getWorkspaceDriftByWorkspaceID = `-- name: GetWorkspaceDriftByWorkspaceID :one
+SELECT
+	workspace_id, job_id, build_id, checked_at, drifted_resources, created_at, updated_at
+FROM
+	workspace_drift
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceDriftByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceDrift, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceDriftByWorkspaceID, workspaceID)
+	var i WorkspaceDrift
 	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
 		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.ProvisionerState,
 		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
+		&i.BuildID,
+		&i.CheckedAt,
+		pq.Array(&i.DriftedResources),
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
-FROM (
-    SELECT
-        workspace_id, MAX(build_number) as max_build_number
-    FROM
-		workspace_build_with_user AS workspace_builds
-    GROUP BY
-        workspace_id
-) m
-JOIN
-	 workspace_build_with_user AS wb
-ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
-`
-
-func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuilds)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []WorkspaceBuild
-	for rows.Next() {
-		var i WorkspaceBuild
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.ProvisionerState,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
-FROM (
-    SELECT
-        workspace_id, MAX(build_number) as max_build_number
-    FROM
-		workspace_build_with_user AS workspace_builds
-    WHERE
-        workspace_id = ANY($1 :: uuid [ ])
-    GROUP BY
-        workspace_id
-) m
-JOIN
-	 workspace_build_with_user AS wb
-ON m.workspace_id = wb.workspace_id AND m.max_build_number = wb.build_number
+const getWorkspaceIDsForDriftCheck = `-- name: GetWorkspaceIDsForDriftCheck :many
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	transition,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+SELECT
+	workspaces.id
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN provisioner_jobs ON
+	provisioner_jobs.id = latest_builds.job_id
+LEFT JOIN workspace_drift ON
+	workspace_drift.workspace_id = workspaces.id
+LEFT JOIN provisioner_jobs AS drift_jobs ON
+	drift_jobs.id = workspace_drift.job_id
+WHERE
+	NOT workspaces.deleted
+	AND workspaces.dormant_at IS NULL
+	AND latest_builds.transition = 'start'::workspace_transition
+	AND provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+	AND (
+		workspace_drift.workspace_id IS NULL
+		OR (
+			drift_jobs.completed_at IS NOT NULL
+			AND drift_jobs.created_at < $1::timestamptz
+		)
+	)
+ORDER BY
+	workspaces.id
 `
 
-func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getLatestWorkspaceBuildsByWorkspaceIDs, pq.Array(ids))
+// Returns the running workspaces that are due a drift check. A workspace is
+// skipped while its latest build or its previous drift check has not finished,
+// so only one plan runs against its state at a time, and never against state
+// that a build is still changing.
+func (q *sqlQuerier) GetWorkspaceIDsForDriftCheck(ctx context.Context, lastCheckBefore time.Time) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceIDsForDriftCheck, lastCheckBefore)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuild
+	var items []uuid.UUID
 	for rows.Next() {
-		var i WorkspaceBuild
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.ProvisionerState,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-		); err != nil {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -10143,161 +16516,206 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 	return items, nil
 }
 
-const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
-FROM
-	workspace_build_with_user AS workspace_builds
+const updateWorkspaceDriftResult = `-- name: UpdateWorkspaceDriftResult :exec
+UPDATE
+	workspace_drift
+SET
+	build_id = $1,
+	checked_at = $2::timestamptz,
+	drifted_resources = $3,
+	updated_at = $2
 WHERE
-	id = $1
-LIMIT
-	1
+	workspace_id = $4
+	AND job_id = $5
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByID, id)
-	var i WorkspaceBuild
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.ProvisionerState,
-		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-	)
-	return i, err
+type UpdateWorkspaceDriftResultParams struct {
+	BuildID          uuid.NullUUID `db:"build_id" json:"build_id"`
+	CheckedAt        time.Time     `db:"checked_at" json:"checked_at"`
+	DriftedResources []string      `db:"drifted_resources" json:"drifted_resources"`
+	WorkspaceID      uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	JobID            uuid.UUID     `db:"job_id" json:"job_id"`
 }
 
-const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
-FROM
-	workspace_build_with_user AS workspace_builds
-WHERE
-	job_id = $1
-LIMIT
-	1
-`
-
-func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByJobID, jobID)
-	var i WorkspaceBuild
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.ProvisionerState,
-		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
+// Records the result of a drift check. Dry-runs that are not the latest drift
+// check of the workspace are ignored.
+func (q *sqlQuerier) UpdateWorkspaceDriftResult(ctx context.Context, arg UpdateWorkspaceDriftResultParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceDriftResult,
+		arg.BuildID,
+		arg.CheckedAt,
+		pq.Array(arg.DriftedResources),
+		arg.WorkspaceID,
+		arg.JobID,
 	)
-	return i, err
+	return err
 }
 
-const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
-SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
-FROM
-	workspace_build_with_user AS workspace_builds
-WHERE
-	workspace_id = $1
-	AND build_number = $2
+const upsertWorkspaceDriftJob = `-- name: UpsertWorkspaceDriftJob :exec
+INSERT INTO
+	workspace_drift (workspace_id, job_id, created_at, updated_at)
+VALUES
+	($1, $2, $3, $3)
+ON CONFLICT
+	(workspace_id)
+DO UPDATE SET
+	job_id = EXCLUDED.job_id,
+	updated_at = EXCLUDED.updated_at
 `
 
-type GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams struct {
+type UpsertWorkspaceDriftJobParams struct {
 	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	BuildNumber int32     `db:"build_number" json:"build_number"`
+	JobID       uuid.UUID `db:"job_id" json:"job_id"`
+	Now         time.Time `db:"now" json:"now"`
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error) {
-	row := q.db.QueryRowContext(ctx, getWorkspaceBuildByWorkspaceIDAndBuildNumber, arg.WorkspaceID, arg.BuildNumber)
-	var i WorkspaceBuild
-	err := row.Scan(
-		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.WorkspaceID,
-		&i.TemplateVersionID,
-		&i.BuildNumber,
-		&i.Transition,
-		&i.InitiatorID,
-		&i.ProvisionerState,
-		&i.JobID,
-		&i.Deadline,
-		&i.Reason,
-		&i.DailyCost,
-		&i.MaxDeadline,
-		&i.InitiatorByAvatarUrl,
-		&i.InitiatorByUsername,
-	)
-	return i, err
+// Starts a new drift check of a workspace. The result of the previous check
+// is kept until the new one completes.
+func (q *sqlQuerier) UpsertWorkspaceDriftJob(ctx context.Context, arg UpsertWorkspaceDriftJobParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceDriftJob, arg.WorkspaceID, arg.JobID, arg.Now)
+	return err
 }
 
-const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
+const deleteOldWorkspaceEvents = `-- name: DeleteOldWorkspaceEvents :exec
+DELETE FROM workspace_events WHERE created_at < $1
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceEvents(ctx context.Context, beforeTime time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceEvents, beforeTime)
+	return err
+}
+
+const getWorkspaceTimeline = `-- name: GetWorkspaceTimeline :many
+WITH timeline AS (
+	SELECT
+		workspace_events.id,
+		workspace_events.created_at,
+		workspace_events.type :: text AS type,
+		workspace_events.user_id,
+		NULL :: uuid AS build_id,
+		0 :: int AS build_number,
+		'' :: text AS build_reason,
+		'' :: text AS transition,
+		'' :: text AS job_status,
+		workspace_events.agent_id,
+		COALESCE(workspace_agents.name, '') :: text AS agent_name,
+		workspace_events.app_id,
+		COALESCE(workspace_apps.slug, '') :: text AS app_slug,
+		workspace_events.old_value,
+		workspace_events.new_value
+	FROM
+		workspace_events
+	LEFT JOIN
+		workspace_agents ON workspace_agents.id = workspace_events.agent_id
+	LEFT JOIN
+		workspace_apps ON workspace_apps.id = workspace_events.app_id
+	WHERE
+		workspace_events.workspace_id = $1
+	UNION ALL
+	SELECT
+		workspace_builds.id,
+		workspace_builds.created_at,
+		'build_started',
+		workspace_builds.initiator_id,
+		workspace_builds.id,
+		workspace_builds.build_number,
+		workspace_builds.reason :: text,
+		workspace_builds.transition :: text,
+		provisioner_jobs.job_status :: text,
+		NULL,
+		'',
+		NULL,
+		'',
+		'',
+		''
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = $1
+	UNION ALL
+	-- The job ID tells the completion apart from the start of the build.
+	SELECT
+		provisioner_jobs.id,
+		provisioner_jobs.completed_at,
+		'build_completed',
+		workspace_builds.initiator_id,
+		workspace_builds.id,
+		workspace_builds.build_number,
+		workspace_builds.reason :: text,
+		workspace_builds.transition :: text,
+		provisioner_jobs.job_status :: text,
+		NULL,
+		'',
+		NULL,
+		'',
+		'',
+		''
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = $1
+		AND provisioner_jobs.completed_at IS NOT NULL
+)
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, type, user_id, build_id, build_number, build_reason, transition, job_status, agent_id, agent_name, app_id, app_slug, old_value, new_value
 FROM
-	workspace_build_with_user AS workspace_builds
+	timeline
 WHERE
-	workspace_builds.workspace_id = $1
-	AND workspace_builds.created_at > $2
-    AND CASE
+	CASE
 		-- This allows using the last element on a page as effectively a cursor.
-		-- This is an important option for scripts that need to paginate without
-		-- duplicating or missing data.
-		WHEN $3 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
-			-- The pagination cursor is the last ID of the previous page.
-			-- The query is ordered by the build_number field, so select all
-			-- rows after the cursor.
-			build_number > (
+		WHEN $2 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			(timeline.created_at, timeline.id) < (
 				SELECT
-					build_number
+					page_cursor.created_at, page_cursor.id
 				FROM
-					workspace_builds
+					timeline AS page_cursor
 				WHERE
-					id = $3
+					page_cursor.id = $2
 			)
 		)
 		ELSE true
-END
+	END
 ORDER BY
-    build_number desc OFFSET $4
+	timeline.created_at DESC, timeline.id DESC
+OFFSET $3
 LIMIT
-    -- A null limit means "no limit", so 0 means return all
-    NULLIF($5 :: int, 0)
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF($4 :: int, 0)
 `
 
-type GetWorkspaceBuildsByWorkspaceIDParams struct {
+type GetWorkspaceTimelineParams struct {
 	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
-	Since       time.Time `db:"since" json:"since"`
 	AfterID     uuid.UUID `db:"after_id" json:"after_id"`
 	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
 	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
 }
 
-func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsByWorkspaceID,
+type GetWorkspaceTimelineRow struct {
+	ID          uuid.UUID     `db:"id" json:"id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+	Type        string        `db:"type" json:"type"`
+	UserID      uuid.NullUUID `db:"user_id" json:"user_id"`
+	BuildID     uuid.NullUUID `db:"build_id" json:"build_id"`
+	BuildNumber int32         `db:"build_number" json:"build_number"`
+	BuildReason string        `db:"build_reason" json:"build_reason"`
+	Transition  string        `db:"transition" json:"transition"`
+	JobStatus   string        `db:"job_status" json:"job_status"`
+	AgentID     uuid.NullUUID `db:"agent_id" json:"agent_id"`
+	AgentName   string        `db:"agent_name" json:"agent_name"`
+	AppID       uuid.NullUUID `db:"app_id" json:"app_id"`
+	AppSlug     string        `db:"app_slug" json:"app_slug"`
+	OldValue    string        `db:"old_value" json:"old_value"`
+	NewValue    string        `db:"new_value" json:"new_value"`
+}
+
+// Returns the timeline of a workspace, newest first. It's made up of the
+// recorded workspace events plus the start and completion of every build.
+func (q *sqlQuerier) GetWorkspaceTimeline(ctx context.Context, arg GetWorkspaceTimelineParams) ([]GetWorkspaceTimelineRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceTimeline,
 		arg.WorkspaceID,
-		arg.Since,
 		arg.AfterID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
@@ -10306,26 +16724,25 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuild
+	var items []GetWorkspaceTimelineRow
 	for rows.Next() {
-		var i WorkspaceBuild
+		var i GetWorkspaceTimelineRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
+			&i.Type,
+			&i.UserID,
+			&i.BuildID,
 			&i.BuildNumber,
+			&i.BuildReason,
 			&i.Transition,
-			&i.InitiatorID,
-			&i.ProvisionerState,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
+			&i.JobStatus,
+			&i.AgentID,
+			&i.AgentName,
+			&i.AppID,
+			&i.AppSlug,
+			&i.OldValue,
+			&i.NewValue,
 		); err != nil {
 			return nil, err
 		}
@@ -10340,40 +16757,79 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
+const insertWorkspaceEvent = `-- name: InsertWorkspaceEvent :exec
+INSERT INTO
+	workspace_events (id, workspace_id, created_at, type, user_id, agent_id, app_id, old_value, new_value)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9)
 `
 
-func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
-	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildsCreatedAfter, createdAt)
+type InsertWorkspaceEventParams struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	WorkspaceID uuid.UUID          `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time          `db:"created_at" json:"created_at"`
+	Type        WorkspaceEventType `db:"type" json:"type"`
+	UserID      uuid.NullUUID      `db:"user_id" json:"user_id"`
+	AgentID     uuid.NullUUID      `db:"agent_id" json:"agent_id"`
+	AppID       uuid.NullUUID      `db:"app_id" json:"app_id"`
+	OldValue    string             `db:"old_value" json:"old_value"`
+	NewValue    string             `db:"new_value" json:"new_value"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceEvent(ctx context.Context, arg InsertWorkspaceEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceEvent,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.CreatedAt,
+		arg.Type,
+		arg.UserID,
+		arg.AgentID,
+		arg.AppID,
+		arg.OldValue,
+		arg.NewValue,
+	)
+	return err
+}
+
+const deleteWorkspaceFavorite = `-- name: DeleteWorkspaceFavorite :exec
+DELETE FROM
+	workspace_favorites
+WHERE
+	user_id = $1 AND workspace_id = $2
+`
+
+type DeleteWorkspaceFavoriteParams struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+}
+
+func (q *sqlQuerier) DeleteWorkspaceFavorite(ctx context.Context, arg DeleteWorkspaceFavoriteParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceFavorite, arg.UserID, arg.WorkspaceID)
+	return err
+}
+
+const getFavoriteWorkspaceIDsByUserID = `-- name: GetFavoriteWorkspaceIDsByUserID :many
+SELECT
+	workspace_id
+FROM
+	workspace_favorites
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetFavoriteWorkspaceIDsByUserID(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getFavoriteWorkspaceIDsByUserID, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []WorkspaceBuild
+	var items []uuid.UUID
 	for rows.Next() {
-		var i WorkspaceBuild
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.WorkspaceID,
-			&i.TemplateVersionID,
-			&i.BuildNumber,
-			&i.Transition,
-			&i.InitiatorID,
-			&i.ProvisionerState,
-			&i.JobID,
-			&i.Deadline,
-			&i.Reason,
-			&i.DailyCost,
-			&i.MaxDeadline,
-			&i.InitiatorByAvatarUrl,
-			&i.InitiatorByUsername,
-		); err != nil {
+		var workspace_id uuid.UUID
+		if err := rows.Scan(&workspace_id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, workspace_id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -10384,131 +16840,100 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 	return items, nil
 }
 
-const insertWorkspaceBuild = `-- name: InsertWorkspaceBuild :exec
+const insertWorkspaceFavorite = `-- name: InsertWorkspaceFavorite :exec
 INSERT INTO
-	workspace_builds (
-		id,
-		created_at,
-		updated_at,
-		workspace_id,
-		template_version_id,
-		"build_number",
-		transition,
-		initiator_id,
-		job_id,
-		provisioner_state,
-		deadline,
-		max_deadline,
-		reason
-	)
+	workspace_favorites (user_id, workspace_id, created_at)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	($1, $2, $3)
+ON CONFLICT (user_id, workspace_id) DO NOTHING
 `
 
-type InsertWorkspaceBuildParams struct {
-	ID                uuid.UUID           `db:"id" json:"id"`
-	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID       uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber       int32               `db:"build_number" json:"build_number"`
-	Transition        WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID       uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	JobID             uuid.UUID           `db:"job_id" json:"job_id"`
-	ProvisionerState  []byte              `db:"provisioner_state" json:"provisioner_state"`
-	Deadline          time.Time           `db:"deadline" json:"deadline"`
-	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
-	Reason            BuildReason         `db:"reason" json:"reason"`
+type InsertWorkspaceFavoriteParams struct {
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
-	_, err := q.db.ExecContext(ctx, insertWorkspaceBuild,
-		arg.ID,
-		arg.CreatedAt,
-		arg.UpdatedAt,
-		arg.WorkspaceID,
-		arg.TemplateVersionID,
-		arg.BuildNumber,
-		arg.Transition,
-		arg.InitiatorID,
-		arg.JobID,
-		arg.ProvisionerState,
-		arg.Deadline,
-		arg.MaxDeadline,
-		arg.Reason,
-	)
+func (q *sqlQuerier) InsertWorkspaceFavorite(ctx context.Context, arg InsertWorkspaceFavoriteParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceFavorite, arg.UserID, arg.WorkspaceID, arg.CreatedAt)
 	return err
 }
 
-const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
-UPDATE
-	workspace_builds
-SET
-	daily_cost = $2
+const getWorkspaceParameterChanges = `-- name: GetWorkspaceParameterChanges :many
+SELECT
+	workspace_parameter_changes.id, workspace_parameter_changes.workspace_id, workspace_parameter_changes.workspace_build_id, workspace_parameter_changes.build_number, workspace_parameter_changes.name, workspace_parameter_changes.old_value, workspace_parameter_changes.new_value, workspace_parameter_changes.initiator_id, workspace_parameter_changes.created_at,
+	COALESCE(visible_users.username, '') :: text AS initiator_username
+FROM
+	workspace_parameter_changes
+	LEFT JOIN visible_users ON workspace_parameter_changes.initiator_id = visible_users.id
 WHERE
-	id = $1
-`
-
-type UpdateWorkspaceBuildCostByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
-	return err
-}
-
-const updateWorkspaceBuildDeadlineByID = `-- name: UpdateWorkspaceBuildDeadlineByID :exec
-UPDATE
-	workspace_builds
-SET
-	deadline = $1::timestamptz,
-	max_deadline = $2::timestamptz,
-	updated_at = $3::timestamptz
-WHERE id = $4::uuid
-`
-
-type UpdateWorkspaceBuildDeadlineByIDParams struct {
-	Deadline    time.Time `db:"deadline" json:"deadline"`
-	MaxDeadline time.Time `db:"max_deadline" json:"max_deadline"`
-	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
-	ID          uuid.UUID `db:"id" json:"id"`
-}
-
-func (q *sqlQuerier) UpdateWorkspaceBuildDeadlineByID(ctx context.Context, arg UpdateWorkspaceBuildDeadlineByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildDeadlineByID,
-		arg.Deadline,
-		arg.MaxDeadline,
-		arg.UpdatedAt,
-		arg.ID,
-	)
-	return err
-}
-
-const updateWorkspaceBuildProvisionerStateByID = `-- name: UpdateWorkspaceBuildProvisionerStateByID :exec
-UPDATE
-	workspace_builds
-SET
-	provisioner_state = $1::bytea,
-	updated_at = $2::timestamptz
-WHERE id = $3::uuid
+	workspace_parameter_changes.workspace_id = $1
+	-- Filter by the name of the parameter.
+	AND CASE
+		WHEN $2 :: text != '' THEN
+			workspace_parameter_changes.name = $2
+		ELSE true
+	END
+ORDER BY
+	workspace_parameter_changes.build_number DESC,
+	workspace_parameter_changes.name ASC
 `
 
-type UpdateWorkspaceBuildProvisionerStateByIDParams struct {
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ID               uuid.UUID `db:"id" json:"id"`
+type GetWorkspaceParameterChangesParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildProvisionerStateByID(ctx context.Context, arg UpdateWorkspaceBuildProvisionerStateByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildProvisionerStateByID, arg.ProvisionerState, arg.UpdatedAt, arg.ID)
-	return err
+type GetWorkspaceParameterChangesRow struct {
+	ID                uuid.UUID      `db:"id" json:"id"`
+	WorkspaceID       uuid.UUID      `db:"workspace_id" json:"workspace_id"`
+	WorkspaceBuildID  uuid.UUID      `db:"workspace_build_id" json:"workspace_build_id"`
+	BuildNumber       int32          `db:"build_number" json:"build_number"`
+	Name              string         `db:"name" json:"name"`
+	OldValue          sql.NullString `db:"old_value" json:"old_value"`
+	NewValue          string         `db:"new_value" json:"new_value"`
+	InitiatorID       uuid.UUID      `db:"initiator_id" json:"initiator_id"`
+	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
+	InitiatorUsername string         `db:"initiator_username" json:"initiator_username"`
+}
+
+func (q *sqlQuerier) GetWorkspaceParameterChanges(ctx context.Context, arg GetWorkspaceParameterChangesParams) ([]GetWorkspaceParameterChangesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceParameterChanges, arg.WorkspaceID, arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceParameterChangesRow
+	for rows.Next() {
+		var i GetWorkspaceParameterChangesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.WorkspaceBuildID,
+			&i.BuildNumber,
+			&i.Name,
+			&i.OldValue,
+			&i.NewValue,
+			&i.InitiatorID,
+			&i.CreatedAt,
+			&i.InitiatorUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot
 FROM
 	workspace_resources
 WHERE
@@ -10529,6 +16954,7 @@ func (q *sqlQuerier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID)
 		&i.Icon,
 		&i.InstanceType,
 		&i.DailyCost,
+		&i.Snapshot,
 	)
 	return i, err
 }
@@ -10608,7 +17034,7 @@ func (q *sqlQuerier) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Contex
 
 const getWorkspaceResourcesByJobID = `-- name: GetWorkspaceResourcesByJobID :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot
 FROM
 	workspace_resources
 WHERE
@@ -10635,6 +17061,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.Snapshot,
 		); err != nil {
 			return nil, err
 		}
@@ -10651,7 +17078,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 
 const getWorkspaceResourcesByJobIDs = `-- name: GetWorkspaceResourcesByJobIDs :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot
 FROM
 	workspace_resources
 WHERE
@@ -10678,6 +17105,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.Snapshot,
 		); err != nil {
 			return nil, err
 		}
@@ -10693,7 +17121,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 }
 
 const getWorkspaceResourcesCreatedAfter = `-- name: GetWorkspaceResourcesCreatedAfter :many
-SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost FROM workspace_resources WHERE created_at > $1
+SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot FROM workspace_resources WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error) {
@@ -10716,6 +17144,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.Snapshot,
 		); err != nil {
 			return nil, err
 		}
@@ -10732,9 +17161,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 
 const insertWorkspaceResource = `-- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, snapshot
 `
 
 type InsertWorkspaceResourceParams struct {
@@ -10748,6 +17177,7 @@ type InsertWorkspaceResourceParams struct {
 	Icon         string              `db:"icon" json:"icon"`
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
+	Snapshot     bool                `db:"snapshot" json:"snapshot"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error) {
@@ -10762,6 +17192,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		arg.Icon,
 		arg.InstanceType,
 		arg.DailyCost,
+		arg.Snapshot,
 	)
 	var i WorkspaceResource
 	err := row.Scan(
@@ -10775,6 +17206,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		&i.Icon,
 		&i.InstanceType,
 		&i.DailyCost,
+		&i.Snapshot,
 	)
 	return i, err
 }
@@ -10935,7 +17367,7 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.maintenance_opt_out, workspaces.user_acl, workspaces.ephemeral, workspaces.ephemeral_api_key_id, workspaces.dormancy_exempt,
 	templates.name as template_name
 FROM
 	workspaces
@@ -10989,6 +17421,11 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.Workspace.DormantAt,
 		&i.Workspace.DeletingAt,
 		&i.Workspace.AutomaticUpdates,
+		&i.Workspace.MaintenanceOptOut,
+		&i.Workspace.UserACL,
+		&i.Workspace.Ephemeral,
+		&i.Workspace.EphemeralAPIKeyID,
+		&i.Workspace.DormancyExempt,
 		&i.TemplateName,
 	)
 	return i, err
@@ -10996,7 +17433,7 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
 FROM
 	workspaces
 WHERE
@@ -11023,13 +17460,18 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
 FROM
 	workspaces
 WHERE
@@ -11063,13 +17505,18 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
 FROM
 	workspaces
 WHERE
@@ -11122,8 +17569,77 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
+	)
+	return i, err
+}
+
+const getWorkspaceCountsByTemplateAndStatus = `-- name: GetWorkspaceCountsByTemplateAndStatus :many
+SELECT
+	templates.name AS template_name,
+	organizations.name AS organization_name,
+	workspace_builds.transition,
+	provisioner_jobs.job_status,
+	COUNT(*) AS count
+FROM
+	workspaces
+JOIN
+	templates ON workspaces.template_id = templates.id
+JOIN
+	organizations ON workspaces.organization_id = organizations.id
+JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
 	)
-	return i, err
+GROUP BY
+	templates.name, organizations.name, workspace_builds.transition, provisioner_jobs.job_status
+`
+
+type GetWorkspaceCountsByTemplateAndStatusRow struct {
+	TemplateName     string               `db:"template_name" json:"template_name"`
+	OrganizationName string               `db:"organization_name" json:"organization_name"`
+	Transition       WorkspaceTransition  `db:"transition" json:"transition"`
+	JobStatus        ProvisionerJobStatus `db:"job_status" json:"job_status"`
+	Count            int64                `db:"count" json:"count"`
+}
+
+// Counts the workspaces that are not deleted by template, organization and the
+// status of their latest build.
+func (q *sqlQuerier) GetWorkspaceCountsByTemplateAndStatus(ctx context.Context) ([]GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceCountsByTemplateAndStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceCountsByTemplateAndStatusRow
+	for rows.Next() {
+		var i GetWorkspaceCountsByTemplateAndStatusRow
+		if err := rows.Scan(&i.TemplateName, &i.OrganizationName, &i.Transition, &i.JobStatus, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getWorkspaceUniqueOwnerCountByTemplateIDs = `-- name: GetWorkspaceUniqueOwnerCountByTemplateIDs :many
@@ -11166,7 +17682,7 @@ func (q *sqlQuerier) GetWorkspaceUniqueOwnerCountByTemplateIDs(ctx context.Conte
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.maintenance_opt_out, workspaces.user_acl, workspaces.ephemeral, workspaces.ephemeral_api_key_id, workspaces.dormancy_exempt,
 	COALESCE(template_name.template_name, 'unknown') as template_name,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
@@ -11347,9 +17863,17 @@ WHERE
 				  workspaces.last_used_at >= $12
 		  ELSE true
 	END
+	-- Filter by workspaces the requesting user has favorited
+	AND CASE
+		WHEN $13 :: boolean THEN
+			workspaces.id IN (SELECT workspace_id FROM workspace_favorites WHERE workspace_favorites.user_id = $14 :: uuid)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ORDER BY
+	-- Workspaces the requesting user has favorited are listed first.
+	workspaces.id IN (SELECT workspace_id FROM workspace_favorites WHERE workspace_favorites.user_id = $14 :: uuid) DESC,
 	(latest_build.completed_at IS NOT NULL AND
 		latest_build.canceled_at IS NULL AND
 		latest_build.error IS NULL AND
@@ -11358,11 +17882,11 @@ ORDER BY
 	LOWER(workspaces.name) ASC
 LIMIT
 	CASE
-		WHEN $14 :: integer > 0 THEN
-			$14
+		WHEN $16 :: integer > 0 THEN
+			$16
 	END
 OFFSET
-	$13
+	$15
 `
 
 type GetWorkspacesParams struct {
@@ -11378,6 +17902,8 @@ type GetWorkspacesParams struct {
 	Dormant                               bool        `db:"dormant" json:"dormant"`
 	LastUsedBefore                        time.Time   `db:"last_used_before" json:"last_used_before"`
 	LastUsedAfter                         time.Time   `db:"last_used_after" json:"last_used_after"`
+	Favorite                              bool        `db:"favorite" json:"favorite"`
+	RequesterID                           uuid.UUID   `db:"requester_id" json:"requester_id"`
 	Offset                                int32       `db:"offset_" json:"offset_"`
 	Limit                                 int32       `db:"limit_" json:"limit_"`
 }
@@ -11397,6 +17923,11 @@ type GetWorkspacesRow struct {
 	DormantAt           sql.NullTime     `db:"dormant_at" json:"dormant_at"`
 	DeletingAt          sql.NullTime     `db:"deleting_at" json:"deleting_at"`
 	AutomaticUpdates    AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	MaintenanceOptOut   bool             `db:"maintenance_opt_out" json:"maintenance_opt_out"`
+	UserACL             TemplateACL      `db:"user_acl" json:"user_acl"`
+	Ephemeral           bool             `db:"ephemeral" json:"ephemeral"`
+	EphemeralAPIKeyID   sql.NullString   `db:"ephemeral_api_key_id" json:"ephemeral_api_key_id"`
+	DormancyExempt      bool             `db:"dormancy_exempt" json:"dormancy_exempt"`
 	TemplateName        string           `db:"template_name" json:"template_name"`
 	TemplateVersionID   uuid.UUID        `db:"template_version_id" json:"template_version_id"`
 	TemplateVersionName sql.NullString   `db:"template_version_name" json:"template_version_name"`
@@ -11417,6 +17948,8 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 		arg.Dormant,
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
+		arg.Favorite,
+		arg.RequesterID,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -11442,6 +17975,11 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.DormantAt,
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
+			&i.MaintenanceOptOut,
+			&i.UserACL,
+			&i.Ephemeral,
+			&i.EphemeralAPIKeyID,
+			&i.DormancyExempt,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -11462,7 +18000,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 
 const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.maintenance_opt_out, workspaces.user_acl, workspaces.ephemeral, workspaces.ephemeral_api_key_id, workspaces.dormancy_exempt
 FROM
 	workspaces
 LEFT JOIN
@@ -11510,18 +18048,51 @@ WHERE
 			workspace_builds.transition = 'start'::workspace_transition
 		) OR
 
+		-- If the workspace's most recent build failed transiently, it is
+		-- eligible to be retried once its backoff elapsed.
+		(
+			workspace_builds.retry_at IS NOT NULL AND
+			workspace_builds.retry_at <= $1 :: timestamptz
+		) OR
+
 		-- If the workspace's template has an inactivity_ttl set
-		-- it may be eligible for dormancy.
+		-- it may be eligible for dormancy, unless it is exempt.
 		(
 			templates.time_til_dormant > 0 AND
-			workspaces.dormant_at IS NULL
+			workspaces.dormant_at IS NULL AND
+			NOT workspaces.dormancy_exempt
 		) OR
 
 		-- If the workspace's template has a time_til_dormant_autodelete set
 		-- and the workspace is already dormant.
 		(
 			templates.time_til_dormant_autodelete > 0 AND
-			workspaces.dormant_at IS NOT NULL
+			workspaces.dormant_at IS NOT NULL AND
+			NOT workspaces.dormancy_exempt
+		) OR
+
+		-- If the workspace's template has a maintenance window and the
+		-- workspace is running an outdated version, it may be eligible for
+		-- a maintenance stop. The caller checks whether the window is open.
+		(
+			templates.maintenance_window_schedule != '' AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			workspace_builds.template_version_id != templates.active_version_id AND
+			NOT workspaces.maintenance_opt_out
+		) OR
+
+		-- If the workspace was stopped for maintenance it is eligible to be
+		-- started again on the active version.
+		(
+			workspace_builds.transition = 'stop'::workspace_transition AND
+			workspace_builds.reason = 'maintenance'::build_reason
+		) OR
+
+		-- Ephemeral workspaces are eligible for deletion. The caller checks
+		-- whether the workspace outlived its TTL or the API key that created
+		-- it expired.
+		(
+			workspaces.ephemeral
 		)
 	) AND workspaces.deleted = 'false'
 `
@@ -11550,6 +18121,63 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 			&i.DormantAt,
 			&i.DeletingAt,
 			&i.AutomaticUpdates,
+			&i.MaintenanceOptOut,
+			&i.UserACL,
+			&i.Ephemeral,
+			&i.EphemeralAPIKeyID,
+			&i.DormancyExempt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspacesByIDs = `-- name: GetWorkspacesByIDs :many
+SELECT
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
+FROM
+	workspaces
+WHERE
+	id = ANY($1 :: uuid[])
+`
+
+func (q *sqlQuerier) GetWorkspacesByIDs(ctx context.Context, ids []uuid.UUID) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacesByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workspace
+	for rows.Next() {
+		var i Workspace
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Deleted,
+			&i.Name,
+			&i.AutostartSchedule,
+			&i.Ttl,
+			&i.LastUsedAt,
+			&i.DormantAt,
+			&i.DeletingAt,
+			&i.AutomaticUpdates,
+			&i.MaintenanceOptOut,
+			&i.UserACL,
+			&i.Ephemeral,
+			&i.EphemeralAPIKeyID,
+			&i.DormancyExempt,
 		); err != nil {
 			return nil, err
 		}
@@ -11577,10 +18205,12 @@ INSERT INTO
 		autostart_schedule,
 		ttl,
 		last_used_at,
-		automatic_updates
+		automatic_updates,
+		ephemeral,
+		ephemeral_api_key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
 `
 
 type InsertWorkspaceParams struct {
@@ -11595,6 +18225,8 @@ type InsertWorkspaceParams struct {
 	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
 	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
 	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	Ephemeral         bool             `db:"ephemeral" json:"ephemeral"`
+	EphemeralAPIKeyID sql.NullString   `db:"ephemeral_api_key_id" json:"ephemeral_api_key_id"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
@@ -11610,6 +18242,8 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.Ttl,
 		arg.LastUsedAt,
 		arg.AutomaticUpdates,
+		arg.Ephemeral,
+		arg.EphemeralAPIKeyID,
 	)
 	var i Workspace
 	err := row.Scan(
@@ -11627,6 +18261,11 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
 	)
 	return i, err
 }
@@ -11657,7 +18296,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
 `
 
 type UpdateWorkspaceParams struct {
@@ -11683,10 +18322,34 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
 	)
 	return i, err
 }
 
+const updateWorkspaceACLByID = `-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = $1
+WHERE
+	id = $2
+`
+
+type UpdateWorkspaceACLByIDParams struct {
+	UserACL TemplateACL `db:"user_acl" json:"user_acl"`
+	ID      uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceACLByID(ctx context.Context, arg UpdateWorkspaceACLByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceACLByID, arg.UserACL, arg.ID)
+	return err
+}
+
 const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
 UPDATE
 	workspaces
@@ -11744,6 +18407,25 @@ func (q *sqlQuerier) UpdateWorkspaceDeletedByID(ctx context.Context, arg UpdateW
 	return err
 }
 
+const updateWorkspaceDormancyExempt = `-- name: UpdateWorkspaceDormancyExempt :exec
+UPDATE
+	workspaces
+SET
+	dormancy_exempt = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceDormancyExemptParams struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	DormancyExempt bool      `db:"dormancy_exempt" json:"dormancy_exempt"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceDormancyExempt(ctx context.Context, arg UpdateWorkspaceDormancyExemptParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceDormancyExempt, arg.ID, arg.DormancyExempt)
+	return err
+}
+
 const updateWorkspaceDormantDeletingAt = `-- name: UpdateWorkspaceDormantDeletingAt :one
 UPDATE
     workspaces
@@ -11769,7 +18451,7 @@ WHERE
     workspaces.id = $1
     AND templates.id = workspaces.template_id
 RETURNING
-    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates
+    workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.dormant_at, workspaces.deleting_at, workspaces.automatic_updates, workspaces.maintenance_opt_out, workspaces.user_acl, workspaces.ephemeral, workspaces.ephemeral_api_key_id, workspaces.dormancy_exempt
 `
 
 type UpdateWorkspaceDormantDeletingAtParams struct {
@@ -11795,6 +18477,11 @@ func (q *sqlQuerier) UpdateWorkspaceDormantDeletingAt(ctx context.Context, arg U
 		&i.DormantAt,
 		&i.DeletingAt,
 		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
 	)
 	return i, err
 }
@@ -11818,6 +18505,70 @@ func (q *sqlQuerier) UpdateWorkspaceLastUsedAt(ctx context.Context, arg UpdateWo
 	return err
 }
 
+const updateWorkspaceMaintenanceOptOut = `-- name: UpdateWorkspaceMaintenanceOptOut :exec
+UPDATE
+	workspaces
+SET
+	maintenance_opt_out = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceMaintenanceOptOutParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	MaintenanceOptOut bool      `db:"maintenance_opt_out" json:"maintenance_opt_out"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceMaintenanceOptOut(ctx context.Context, arg UpdateWorkspaceMaintenanceOptOutParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceMaintenanceOptOut, arg.ID, arg.MaintenanceOptOut)
+	return err
+}
+
+const updateWorkspaceOwnerByID = `-- name: UpdateWorkspaceOwnerByID :one
+UPDATE
+	workspaces
+SET
+	owner_id = $1,
+	updated_at = $2
+WHERE
+	id = $3
+	AND deleted = false
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, dormant_at, deleting_at, automatic_updates, maintenance_opt_out, user_acl, ephemeral, ephemeral_api_key_id, dormancy_exempt
+`
+
+type UpdateWorkspaceOwnerByIDParams struct {
+	OwnerID   uuid.UUID `db:"owner_id" json:"owner_id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID        uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceOwnerByID(ctx context.Context, arg UpdateWorkspaceOwnerByIDParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceOwnerByID, arg.OwnerID, arg.UpdatedAt, arg.ID)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.DormantAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+		&i.MaintenanceOptOut,
+		&i.UserACL,
+		&i.Ephemeral,
+		&i.EphemeralAPIKeyID,
+		&i.DormancyExempt,
+	)
+	return i, err
+}
+
 const updateWorkspaceTTL = `-- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -11864,7 +18615,7 @@ func (q *sqlQuerier) UpdateWorkspacesDormantDeletingAtByTemplateID(ctx context.C
 }
 
 const getWorkspaceAgentScriptsByAgentIDs = `-- name: GetWorkspaceAgentScriptsByAgentIDs :many
-SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
+SELECT workspace_agent_id, log_source_id, log_path, created_at, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, phase FROM workspace_agent_scripts WHERE workspace_agent_id = ANY($1 :: uuid [ ])
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgentScript, error) {
@@ -11887,6 +18638,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 			&i.RunOnStart,
 			&i.RunOnStop,
 			&i.TimeoutSeconds,
+			&i.Phase,
 		); err != nil {
 			return nil, err
 		}
@@ -11903,7 +18655,7 @@ func (q *sqlQuerier) GetWorkspaceAgentScriptsByAgentIDs(ctx context.Context, ids
 
 const insertWorkspaceAgentScripts = `-- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, phase)
 SELECT
 	$1 :: uuid AS workspace_agent_id,
 	$2 :: timestamptz AS created_at,
@@ -11914,21 +18666,23 @@ SELECT
 	unnest($7 :: boolean [ ]) AS start_blocks_login,
 	unnest($8 :: boolean [ ]) AS run_on_start,
 	unnest($9 :: boolean [ ]) AS run_on_stop,
-	unnest($10 :: integer [ ]) AS timeout_seconds
-RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds
+	unnest($10 :: integer [ ]) AS timeout_seconds,
+	unnest($11 :: workspace_agent_script_phase [ ]) AS phase
+RETURNING workspace_agent_scripts.workspace_agent_id, workspace_agent_scripts.log_source_id, workspace_agent_scripts.log_path, workspace_agent_scripts.created_at, workspace_agent_scripts.script, workspace_agent_scripts.cron, workspace_agent_scripts.start_blocks_login, workspace_agent_scripts.run_on_start, workspace_agent_scripts.run_on_stop, workspace_agent_scripts.timeout_seconds, workspace_agent_scripts.phase
 `
 
 type InsertWorkspaceAgentScriptsParams struct {
-	WorkspaceAgentID uuid.UUID   `db:"workspace_agent_id" json:"workspace_agent_id"`
-	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
-	LogSourceID      []uuid.UUID `db:"log_source_id" json:"log_source_id"`
-	LogPath          []string    `db:"log_path" json:"log_path"`
-	Script           []string    `db:"script" json:"script"`
-	Cron             []string    `db:"cron" json:"cron"`
-	StartBlocksLogin []bool      `db:"start_blocks_login" json:"start_blocks_login"`
-	RunOnStart       []bool      `db:"run_on_start" json:"run_on_start"`
-	RunOnStop        []bool      `db:"run_on_stop" json:"run_on_stop"`
-	TimeoutSeconds   []int32     `db:"timeout_seconds" json:"timeout_seconds"`
+	WorkspaceAgentID uuid.UUID                   `db:"workspace_agent_id" json:"workspace_agent_id"`
+	CreatedAt        time.Time                   `db:"created_at" json:"created_at"`
+	LogSourceID      []uuid.UUID                 `db:"log_source_id" json:"log_source_id"`
+	LogPath          []string                    `db:"log_path" json:"log_path"`
+	Script           []string                    `db:"script" json:"script"`
+	Cron             []string                    `db:"cron" json:"cron"`
+	StartBlocksLogin []bool                      `db:"start_blocks_login" json:"start_blocks_login"`
+	RunOnStart       []bool                      `db:"run_on_start" json:"run_on_start"`
+	RunOnStop        []bool                      `db:"run_on_stop" json:"run_on_stop"`
+	TimeoutSeconds   []int32                     `db:"timeout_seconds" json:"timeout_seconds"`
+	Phase            []WorkspaceAgentScriptPhase `db:"phase" json:"phase"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg InsertWorkspaceAgentScriptsParams) ([]WorkspaceAgentScript, error) {
@@ -11943,6 +18697,7 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 		pq.Array(arg.RunOnStart),
 		pq.Array(arg.RunOnStop),
 		pq.Array(arg.TimeoutSeconds),
+		pq.Array(arg.Phase),
 	)
 	if err != nil {
 		return nil, err
@@ -11962,6 +18717,85 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 			&i.RunOnStart,
 			&i.RunOnStop,
 			&i.TimeoutSeconds,
+			&i.Phase,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceSnapshotByID = `-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	id, workspace_id, build_id, name, created_by, created_at
+FROM
+	workspace_snapshots
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceSnapshotByID(ctx context.Context, id uuid.UUID) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceSnapshotByID, id)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.BuildID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWorkspaceSnapshotsByWorkspaceID = `-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	workspace_snapshots.id, workspace_snapshots.workspace_id, workspace_snapshots.build_id, workspace_snapshots.name, workspace_snapshots.created_by, workspace_snapshots.created_at,
+	workspace_builds.job_id,
+	provisioner_jobs.job_status
+FROM
+	workspace_snapshots
+JOIN
+	workspace_builds ON workspace_builds.id = workspace_snapshots.build_id
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_snapshots.workspace_id = $1
+ORDER BY
+	workspace_snapshots.created_at DESC
+`
+
+type GetWorkspaceSnapshotsByWorkspaceIDRow struct {
+	WorkspaceSnapshot WorkspaceSnapshot    `db:"workspace_snapshot" json:"workspace_snapshot"`
+	JobID             uuid.UUID            `db:"job_id" json:"job_id"`
+	JobStatus         ProvisionerJobStatus `db:"job_status" json:"job_status"`
+}
+
+func (q *sqlQuerier) GetWorkspaceSnapshotsByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]GetWorkspaceSnapshotsByWorkspaceIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceSnapshotsByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceSnapshotsByWorkspaceIDRow
+	for rows.Next() {
+		var i GetWorkspaceSnapshotsByWorkspaceIDRow
+		if err := rows.Scan(
+			&i.WorkspaceSnapshot.ID,
+			&i.WorkspaceSnapshot.WorkspaceID,
+			&i.WorkspaceSnapshot.BuildID,
+			&i.WorkspaceSnapshot.Name,
+			&i.WorkspaceSnapshot.CreatedBy,
+			&i.WorkspaceSnapshot.CreatedAt,
+			&i.JobID,
+			&i.JobStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -11975,3 +18809,40 @@ func (q *sqlQuerier) InsertWorkspaceAgentScripts(ctx context.Context, arg Insert
 	}
 	return items, nil
 }
+
+const insertWorkspaceSnapshot = `-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (id, workspace_id, build_id, name, created_by, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, workspace_id, build_id, name, created_by, created_at
+`
+
+type InsertWorkspaceSnapshotParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	BuildID     uuid.UUID `db:"build_id" json:"build_id"`
+	Name        string    `db:"name" json:"name"`
+	CreatedBy   uuid.UUID `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceSnapshot(ctx context.Context, arg InsertWorkspaceSnapshotParams) (WorkspaceSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceSnapshot,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.BuildID,
+		arg.Name,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i WorkspaceSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.BuildID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -30,6 +30,22 @@ SELECT * FROM api_keys WHERE login_type = $1;
 -- name: GetAPIKeysByUserID :many
 SELECT * FROM api_keys WHERE login_type = $1 AND user_id = $2;
 
+-- name: GetActiveSessionsByUserID :many
+-- Sessions are the keys users get by signing in, as opposed to tokens and the
+-- keys of OAuth2 provider apps. Keys scoped to application_connect are derived
+-- from a session, so they aren't sessions of their own.
+SELECT
+	*
+FROM
+	api_keys
+WHERE
+	user_id = @user_id AND
+	login_type IN ('password'::login_type, 'github'::login_type, 'oidc'::login_type, 'ldap'::login_type) AND
+	scope = 'all'::api_key_scope AND
+	expires_at > @now :: timestamptz
+ORDER BY
+	created_at DESC;
+
 -- name: InsertAPIKey :one
 INSERT INTO
 	api_keys (
@@ -44,7 +60,10 @@ INSERT INTO
 		updated_at,
 		login_type,
 		scope,
-		token_name
+		token_name,
+		scopes,
+		allowed_workspace_ids,
+		ip_allowlist
 	)
 VALUES
 	(@id,
@@ -53,7 +72,9 @@ VALUES
 	     WHEN 0 THEN 86400
 		 ELSE @lifetime_seconds::bigint
 	 END
-	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name) RETURNING *;
+	 , @hashed_secret, @ip_address, @user_id, @last_used, @expires_at, @created_at, @updated_at, @login_type, @scope, @token_name,
+	 -- Callers that don't use fine-grained scopes may pass NULL.
+	 COALESCE(@scopes::text[], '{}'), COALESCE(@allowed_workspace_ids::uuid[], '{}'), COALESCE(@ip_allowlist::text[], '{}')) RETURNING *;
 
 -- name: UpdateAPIKeyByID :exec
 UPDATE
@@ -71,6 +92,15 @@ DELETE FROM
 WHERE
 	id = $1;
 
+-- DeleteAPIKeyByIDReturning returns the deleted key, so only one of several
+-- concurrent deletes of the same key succeeds.
+-- name: DeleteAPIKeyByIDReturning :one
+DELETE FROM
+	api_keys
+WHERE
+	id = $1
+RETURNING *;
+
 -- name: DeleteApplicationConnectAPIKeysByUserID :exec
 DELETE FROM
 	api_keys
@@ -83,3 +113,12 @@ DELETE FROM
 	api_keys
 WHERE
 	user_id = $1;
+
+-- DeleteAPIKeysByUserIDReturning returns the deleted keys, so each of them
+-- can be audited.
+-- name: DeleteAPIKeysByUserIDReturning :many
+DELETE FROM
+	api_keys
+WHERE
+	user_id = $1
+RETURNING *;
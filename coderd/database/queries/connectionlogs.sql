@@ -0,0 +1,80 @@
+-- name: InsertConnectionLog :one
+INSERT INTO
+	connection_logs (
+		id,
+		"time",
+		organization_id,
+		workspace_owner_id,
+		workspace_id,
+		workspace_name,
+		agent_name,
+		type,
+		port,
+		ip,
+		user_agent,
+		user_id,
+		api_key_id
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
+
+-- name: GetConnectionLogsOffset :many
+SELECT
+	connection_logs.*,
+	COALESCE(users.username, '') :: text AS user_username,
+	COALESCE(owners.username, '') :: text AS workspace_owner_username,
+	COUNT(connection_logs.*) OVER () AS count
+FROM
+	connection_logs
+	LEFT JOIN users ON connection_logs.user_id = users.id
+	LEFT JOIN users owners ON connection_logs.workspace_owner_id = owners.id
+WHERE
+	-- Filter by type
+	CASE
+		WHEN @type :: text != '' THEN
+			connection_logs.type = @type :: connection_type
+		ELSE true
+	END
+	-- Filter by workspace_id
+	AND CASE
+		WHEN @workspace_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			connection_logs.workspace_id = @workspace_id
+		ELSE true
+	END
+	-- Filter by user_id
+	AND CASE
+		WHEN @user_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			connection_logs.user_id = @user_id
+		ELSE true
+	END
+	-- Filter by username
+	AND CASE
+		WHEN @username :: text != '' THEN
+			lower(users.username) = lower(@username)
+		ELSE true
+	END
+	-- Filter by workspace_owner
+	AND CASE
+		WHEN @workspace_owner :: text != '' THEN
+			lower(owners.username) = lower(@workspace_owner)
+		ELSE true
+	END
+	-- Filter by date_from
+	AND CASE
+		WHEN @date_from :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connection_logs."time" >= @date_from
+		ELSE true
+	END
+	-- Filter by date_to
+	AND CASE
+		WHEN @date_to :: timestamp with time zone != '0001-01-01 00:00:00Z' THEN
+			connection_logs."time" <= @date_to
+		ELSE true
+	END
+ORDER BY
+	connection_logs."time" DESC,
+	connection_logs.id DESC
+LIMIT
+	NULLIF(@limit_opt :: int, 0)
+OFFSET
+	@offset_opt;
@@ -36,7 +36,8 @@ SELECT
 	templates.organization_id AS template_organization_id,
 	templates.created_by AS template_created_by,
 	templates.user_acl,
-	templates.group_acl
+	templates.group_acl,
+	templates.visibility
 FROM
 	templates
 INNER JOIN
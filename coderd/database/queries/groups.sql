@@ -28,6 +28,19 @@ FROM
 WHERE
 	organization_id = $1;
 
+-- name: GetGroupsByOrganizationAndUserID :many
+SELECT
+	groups.*
+FROM
+	groups
+INNER JOIN
+	group_members
+ON
+	group_members.group_id = groups.id
+WHERE
+	group_members.user_id = @user_id
+	AND groups.organization_id = @organization_id;
+
 -- name: InsertGroup :one
 INSERT INTO groups (
 	id,
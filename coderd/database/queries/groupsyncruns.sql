@@ -0,0 +1,27 @@
+-- name: InsertGroupSyncRun :one
+INSERT INTO
+	group_sync_runs (id, trigger, user_id, started_at, finished_at, users_synced, users_failed, idp_groups, groups, error)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetGroupSyncRuns :many
+-- Returns group sync runs, most recent first. If a user is given, only the
+-- runs that synced that user at login are returned.
+SELECT
+	*
+FROM
+	group_sync_runs
+WHERE
+	CASE
+		WHEN @user_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = @user_id
+		ELSE true
+	END
+ORDER BY
+	started_at DESC, id DESC
+LIMIT
+	@limit_count :: int;
+
+-- name: DeleteOldGroupSyncRuns :exec
+DELETE FROM group_sync_runs WHERE started_at < @before_time;
@@ -177,7 +177,13 @@ WITH app_stats_by_user_and_agent AS (
 		was.slug_or_port,
 		wa.display_name,
 		wa.icon,
-		(wa.slug IS NOT NULL)::boolean AS is_app
+		(wa.slug IS NOT NULL)::boolean AS is_app,
+		-- Requests are reported per session, count them in the first minute
+		-- of the session within the timeframe so they're only counted once.
+		COALESCE(SUM(was.requests) FILTER (
+			WHERE s.start_time = GREATEST(date_trunc('minute', was.session_started_at), date_trunc('minute', @start_time::timestamptz))
+		), 0) AS requests,
+		MAX(was.session_ended_at) AS last_used_at
 	FROM workspace_app_stats was
 	JOIN workspaces w ON (
 		w.id = was.workspace_id
@@ -214,7 +220,9 @@ SELECT
 	display_name,
 	icon,
 	is_app,
-	SUM(seconds) AS usage_seconds
+	SUM(seconds) AS usage_seconds,
+	SUM(requests)::bigint AS requests,
+	MAX(last_used_at)::timestamptz AS last_used_at
 FROM app_stats_by_user_and_agent
 GROUP BY access_method, slug_or_port, display_name, icon, is_app;
 
@@ -382,3 +390,29 @@ SELECT
 FROM unique_template_params utp
 JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
 GROUP BY utp.num, utp.template_ids, utp.name, utp.type, utp.display_name, utp.description, utp.options, wbp.value;
+
+-- name: GetTemplateBuildInsights :one
+-- GetTemplateBuildInsights returns the number of finished workspace builds of a
+-- template in the given timeframe, how many of them failed, and the median and
+-- 95th percentile execution time in seconds of the successful ones. Canceled
+-- builds are not counted. The percentiles are -1 if no build succeeded.
+WITH builds AS (
+	SELECT
+		pj.job_status,
+		EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at))::FLOAT AS exec_time_sec
+	FROM workspace_builds wb
+	JOIN template_versions tv ON (tv.id = wb.template_version_id)
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	WHERE
+		tv.template_id = @template_id::uuid
+		AND wb.created_at >= @start_time::timestamptz
+		AND wb.created_at < @end_time::timestamptz
+		AND pj.job_status IN ('succeeded', 'failed')
+)
+
+SELECT
+	COUNT(*) AS total_builds,
+	COUNT(*) FILTER (WHERE job_status = 'failed') AS failed_builds,
+	COALESCE((PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY exec_time_sec) FILTER (WHERE job_status = 'succeeded')), -1)::FLOAT AS build_time_p50,
+	COALESCE((PERCENTILE_DISC(0.95) WITHIN GROUP (ORDER BY exec_time_sec) FILTER (WHERE job_status = 'succeeded')), -1)::FLOAT AS build_time_p95
+FROM builds;
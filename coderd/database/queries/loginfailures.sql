@@ -0,0 +1,171 @@
+-- name: GetLoginFailure :one
+SELECT
+	*
+FROM
+	login_failures
+WHERE
+	user_id = @user_id
+	AND ip_address = @ip_address;
+
+-- name: RecordLoginAttempt :one
+-- Counts a login attempt of the user from the IP address before the password
+-- is checked, and forgotten again if it succeeds. The count starts over if the
+-- previous attempt was before reset_before. Once the count reaches
+-- backoff_threshold, the attempt is only counted if the previous one was long
+-- enough ago, and no row is returned otherwise. Checking and counting in one
+-- statement keeps concurrent attempts from all getting through.
+INSERT INTO
+	login_failures (user_id, ip_address, failed_attempts, last_failed_at)
+VALUES
+	(@user_id, @ip_address, 1, @attempted_at)
+ON CONFLICT
+	(user_id, ip_address)
+DO UPDATE SET
+	failed_attempts = CASE
+		WHEN login_failures.last_failed_at < @reset_before :: timestamptz THEN 1
+		ELSE login_failures.failed_attempts + 1
+	END,
+	last_failed_at = @attempted_at
+WHERE
+	login_failures.last_failed_at < @reset_before :: timestamptz
+	OR login_failures.failed_attempts < @backoff_threshold :: bigint
+	OR login_failures.last_failed_at + make_interval(secs => LEAST(2 ^ LEAST(GREATEST(login_failures.failed_attempts - @backoff_threshold :: bigint, 0), 30), @backoff_max_seconds :: bigint)) <= @attempted_at :: timestamptz
+RETURNING
+	*;
+
+-- name: CountLoginFailuresByUserID :one
+-- Counts the failed logins of the user from all IP addresses since the given
+-- time.
+SELECT
+	COALESCE(SUM(failed_attempts), 0) :: bigint
+FROM
+	login_failures
+WHERE
+	user_id = @user_id
+	AND last_failed_at >= @since;
+
+-- name: DeleteLoginFailure :exec
+DELETE FROM
+	login_failures
+WHERE
+	user_id = @user_id
+	AND ip_address = @ip_address;
+
+-- name: GetUserLockoutByUserID :one
+SELECT
+	*
+FROM
+	user_lockouts
+WHERE
+	user_id = @user_id;
+
+-- name: GetUserLockouts :many
+-- Returns the users that are locked out at the given time.
+SELECT
+	user_lockouts.*,
+	users.username
+FROM
+	user_lockouts
+JOIN
+	users ON users.id = user_lockouts.user_id
+WHERE
+	user_lockouts.locked_until > @now
+ORDER BY
+	user_lockouts.locked_at DESC;
+
+-- name: UpsertUserLockout :one
+INSERT INTO
+	user_lockouts (user_id, failed_attempts, locked_at, locked_until)
+VALUES
+	(@user_id, @failed_attempts, @locked_at, @locked_until)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	failed_attempts = @failed_attempts,
+	locked_at = @locked_at,
+	locked_until = @locked_until
+RETURNING
+	*;
+
+-- name: DeleteUserLockout :exec
+-- Unlocks the user and forgets their failed logins, so that they aren't
+-- locked out again by their next failed attempt.
+WITH failures AS (
+	DELETE FROM
+		login_failures
+	WHERE
+		login_failures.user_id = @user_id
+)
+DELETE FROM
+	user_lockouts
+WHERE
+	user_lockouts.user_id = @user_id;
+
+-- name: GetUnknownLoginFailure :one
+SELECT
+	*
+FROM
+	unknown_login_failures
+WHERE
+	login_hash = @login_hash
+	AND ip_address = @ip_address;
+
+-- name: RecordUnknownLoginAttempt :one
+-- Counts a login attempt for an email or username that doesn't belong to a
+-- user, or for a directory login, like RecordLoginAttempt. No row is returned
+-- while the IP address is backing off.
+INSERT INTO
+	unknown_login_failures (login_hash, ip_address, failed_attempts, last_failed_at)
+VALUES
+	(@login_hash, @ip_address, 1, @attempted_at)
+ON CONFLICT
+	(login_hash, ip_address)
+DO UPDATE SET
+	failed_attempts = CASE
+		WHEN unknown_login_failures.last_failed_at < @reset_before :: timestamptz THEN 1
+		ELSE unknown_login_failures.failed_attempts + 1
+	END,
+	last_failed_at = @attempted_at
+WHERE
+	unknown_login_failures.last_failed_at < @reset_before :: timestamptz
+	OR unknown_login_failures.failed_attempts < @backoff_threshold :: bigint
+	OR unknown_login_failures.last_failed_at + make_interval(secs => LEAST(2 ^ LEAST(GREATEST(unknown_login_failures.failed_attempts - @backoff_threshold :: bigint, 0), 30), @backoff_max_seconds :: bigint)) <= @attempted_at :: timestamptz
+RETURNING
+	*;
+
+-- name: GetUnknownLoginFailureTotals :one
+-- Sums up the failed logins for an email or username that doesn't belong to a
+-- user from all IP addresses since the given time, and returns when the last
+-- one happened.
+SELECT
+	COALESCE(SUM(failed_attempts), 0) :: bigint AS failed_attempts,
+	COALESCE(MAX(last_failed_at), '0001-01-01 00:00:00+00') :: timestamptz AS last_failed_at
+FROM
+	unknown_login_failures
+WHERE
+	login_hash = @login_hash
+	AND last_failed_at >= @since;
+
+-- name: DeleteUnknownLoginFailure :exec
+DELETE FROM
+	unknown_login_failures
+WHERE
+	login_hash = @login_hash
+	AND ip_address = @ip_address;
+
+-- name: DeleteOldLoginFailures :exec
+WITH lockouts AS (
+	DELETE FROM
+		user_lockouts
+	WHERE
+		locked_until < @before_time
+), unknown_failures AS (
+	DELETE FROM
+		unknown_login_failures
+	WHERE
+		last_failed_at < @before_time
+)
+DELETE FROM
+	login_failures
+WHERE
+	last_failed_at < @before_time;
@@ -0,0 +1,139 @@
+-- name: InsertNotificationMessage :one
+INSERT INTO
+	notification_messages (id, user_id, template, labels, created_at, next_attempt_at, method)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetNotificationMessagesByUserID :many
+SELECT
+	*
+FROM
+	notification_messages
+WHERE
+	user_id = @user_id
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- Acquires notifications that are due to be sent. The next attempt is pushed
+-- back to lease_until so that a crashed replica does not hold on to the
+-- notification forever, and SKIP LOCKED prevents multiple replicas from
+-- acquiring the same notification.
+-- name: AcquireNotificationMessages :many
+UPDATE
+	notification_messages
+SET
+	next_attempt_at = @lease_until :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			notification_messages AS nested
+		WHERE
+			nested.next_attempt_at <= @now :: timestamptz
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			@limit_opt :: int
+	) RETURNING *;
+
+-- name: UpdateNotificationMessageByID :exec
+UPDATE
+	notification_messages
+SET
+	attempts = $2,
+	last_error = $3,
+	sent_at = $4,
+	next_attempt_at = $5
+WHERE
+	id = $1;
+
+-- name: GetNotificationPreferencesByUserID :many
+SELECT
+	*
+FROM
+	notification_preferences
+WHERE
+	user_id = $1
+ORDER BY
+	template ASC;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO
+	notification_preferences (user_id, template, disabled, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, template)
+DO UPDATE SET
+	disabled = $3,
+	updated_at = $4
+RETURNING *;
+
+-- name: InsertInboxNotification :one
+INSERT INTO
+	inbox_notifications (id, user_id, template, title, content, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetInboxNotificationsByUserID :many
+SELECT
+	*
+FROM
+	inbox_notifications
+WHERE
+	user_id = @user_id
+	AND CASE
+		-- The pagination cursor is the last ID of the previous page. The
+		-- query is ordered by newest first, so select all older rows.
+		WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			(created_at, id) < (
+				SELECT
+					created_at, id
+				FROM
+					inbox_notifications
+				WHERE
+					id = @after_id
+			)
+		)
+		ELSE true
+	END
+	AND CASE
+		WHEN @unread_only :: boolean THEN read_at IS NULL
+		ELSE true
+	END
+ORDER BY
+	created_at DESC, id DESC
+OFFSET
+	@offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: CountUnreadInboxNotificationsByUserID :one
+SELECT
+	COUNT(*)
+FROM
+	inbox_notifications
+WHERE
+	user_id = $1
+	AND read_at IS NULL;
+
+-- Marks the listed notifications of the user as read, or all of them if none
+-- are listed.
+-- name: UpdateInboxNotificationsReadByUserID :exec
+UPDATE
+	inbox_notifications
+SET
+	read_at = @read_at :: timestamptz
+WHERE
+	user_id = @user_id
+	AND read_at IS NULL
+	AND CASE
+		WHEN cardinality(@ids :: uuid[]) > 0 THEN id = ANY(@ids :: uuid[])
+		ELSE true
+	END;
@@ -60,3 +60,59 @@ WHERE id = $1 RETURNING *;
 
 -- name: DeleteOAuth2ProviderAppSecretByID :exec
 DELETE FROM oauth2_provider_app_secrets WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByID :one
+SELECT * FROM oauth2_provider_app_codes WHERE id = $1;
+
+-- name: GetOAuth2ProviderAppCodeByPrefix :one
+SELECT * FROM oauth2_provider_app_codes WHERE secret_prefix = $1;
+
+-- name: InsertOAuth2ProviderAppCode :one
+INSERT INTO oauth2_provider_app_codes (
+    id,
+    created_at,
+    expires_at,
+    secret_prefix,
+    hashed_secret,
+    app_id,
+    user_id,
+    nonce,
+    code_challenge
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+) RETURNING *;
+
+-- DeleteOAuth2ProviderAppCodeByID returns the deleted code, so only one of
+-- several concurrent deletes of the same code succeeds.
+-- name: DeleteOAuth2ProviderAppCodeByID :one
+DELETE FROM oauth2_provider_app_codes WHERE id = $1 RETURNING *;
+
+-- name: InsertOAuth2ProviderAppToken :one
+INSERT INTO oauth2_provider_app_tokens (
+    id,
+    created_at,
+    expires_at,
+    hash_prefix,
+    refresh_hash,
+    app_secret_id,
+    api_key_id
+) VALUES(
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+) RETURNING *;
+
+-- name: GetOAuth2ProviderAppTokenByPrefix :one
+SELECT * FROM oauth2_provider_app_tokens WHERE hash_prefix = $1;
@@ -22,6 +22,26 @@ VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
 
 
+-- name: GetOrganizationMembers :many
+SELECT
+	organization_members.*,
+	users.username
+FROM
+	organization_members
+	INNER JOIN users ON users.id = organization_members.user_id
+WHERE
+	organization_members.organization_id = @organization_id
+	AND users.deleted = false
+ORDER BY
+	users.username;
+
+-- name: DeleteOrganizationMember :exec
+DELETE FROM
+	organization_members
+WHERE
+	organization_id = @organization_id
+	AND user_id = @user_id;
+
 -- name: GetOrganizationMembershipsByUserID :many
 SELECT
 	*
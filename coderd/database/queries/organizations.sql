@@ -28,7 +28,7 @@ SELECT
 FROM
 	organizations
 WHERE
-	id = (
+	id IN (
 		SELECT
 			organization_id
 		FROM
@@ -42,3 +42,9 @@ INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: DeleteOrganization :exec
+DELETE FROM
+	organizations
+WHERE
+	id = $1;
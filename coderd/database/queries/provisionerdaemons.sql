@@ -4,6 +4,17 @@ SELECT
 FROM
 	provisioner_daemons;
 
+-- name: GetProvisionerDaemonsByOrganization :many
+-- Returns the daemons that can acquire jobs from the organization, including
+-- those that are not scoped to any organization.
+SELECT
+	*
+FROM
+	provisioner_daemons
+WHERE
+	organization_id IS NULL
+	OR organization_id = @organization_id :: uuid;
+
 -- name: DeleteOldProvisionerDaemons :exec
 -- Delete provisioner daemons that have been created at least a week ago
 -- and have not connected to coderd since a week.
@@ -24,7 +35,8 @@ INSERT INTO
 		tags,
 		last_seen_at,
 		"version",
-		api_version
+		api_version,
+		organization_id
 	)
 VALUES (
 	gen_random_uuid(),
@@ -34,13 +46,15 @@ VALUES (
 	@tags,
 	@last_seen_at,
 	@version,
-	@api_version
+	@api_version,
+	@organization_id
 ) ON CONFLICT("name", LOWER(COALESCE(tags ->> 'owner'::text, ''::text))) DO UPDATE SET
 	provisioners = @provisioners,
 	tags = @tags,
 	last_seen_at = @last_seen_at,
 	"version" = @version,
-	api_version = @api_version
+	api_version = @api_version,
+	organization_id = @organization_id
 WHERE
 	-- Only ones with the same tags are allowed clobber
 	provisioner_daemons.tags <@ @tags :: jsonb
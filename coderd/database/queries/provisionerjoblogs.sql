@@ -1,3 +1,45 @@
+-- name: DeleteProvisionerJobLogsByJobID :exec
+DELETE FROM
+	provisioner_job_logs
+WHERE
+	job_id = @job_id;
+
+-- name: GetProvisionerJobIDsToArchiveLogs :many
+-- Returns completed jobs that still have logs in provisioner_job_logs, oldest
+-- first.
+SELECT
+	provisioner_jobs.id
+FROM
+	provisioner_jobs
+WHERE
+	provisioner_jobs.completed_at < @completed_before :: timestamptz
+	AND EXISTS (
+		SELECT 1 FROM provisioner_job_logs WHERE provisioner_job_logs.job_id = provisioner_jobs.id
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM provisioner_job_log_archives WHERE provisioner_job_log_archives.job_id = provisioner_jobs.id
+	)
+ORDER BY
+	provisioner_jobs.completed_at ASC
+LIMIT
+	@limit_opt :: int;
+
+-- name: GetProvisionerJobLogArchiveByJobID :one
+SELECT
+	*
+FROM
+	provisioner_job_log_archives
+WHERE
+	job_id = @job_id;
+
+-- name: GetProvisionerJobLogArchiveStats :one
+SELECT
+	COUNT(*) AS archive_count,
+	COALESCE(SUM(octet_length(compressed_logs)), 0) :: bigint AS compressed_size,
+	COALESCE(SUM(uncompressed_size), 0) :: bigint AS uncompressed_size
+FROM
+	provisioner_job_log_archives;
+
 -- name: GetProvisionerLogsAfterID :many
 SELECT
 	*
@@ -9,6 +51,12 @@ WHERE
 		id > @created_after
 	) ORDER BY id ASC;
 
+-- name: InsertProvisionerJobLogArchive :one
+INSERT INTO
+	provisioner_job_log_archives (job_id, created_at, log_count, uncompressed_size, compressed_logs)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
 -- name: InsertProvisionerJobLogs :many
 INSERT INTO
 	provisioner_job_logs
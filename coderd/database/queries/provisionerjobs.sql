@@ -23,7 +23,31 @@ WHERE
 			AND nested.provisioner = ANY(@types :: provisioner_type [ ])
 			-- Ensure the caller satisfies all job tags.
 			AND nested.tags <@ @tags :: jsonb
+			-- Ensure the caller satisfies all job tag requirements. This must
+			-- match provisionertags.Matches: "*" in values is a wildcard, and
+			-- callers without the tag only satisfy "not_in" requirements.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					jsonb_to_recordset(nested.tag_requirements) AS requirement("key" text, "operator" text, "values" jsonb)
+				WHERE
+					EXISTS (
+						SELECT
+							1
+						FROM
+							jsonb_array_elements_text(requirement."values") AS pattern(value)
+						WHERE
+							(@tags :: jsonb) ->> requirement."key" LIKE replace(replace(replace(replace(pattern.value, '\', '\\'), '%', '\%'), '_', '\_'), '*', '%')
+					) != (requirement."operator" = 'in')
+			)
+			-- Organization-scoped daemons only acquire jobs from their organization.
+			AND (sqlc.narg('organization_id') :: uuid IS NULL OR nested.organization_id = sqlc.narg('organization_id') :: uuid)
 		ORDER BY
+			-- Admins can bump a job ahead of the queue. Otherwise, prefer
+			-- workspace builds, which users are waiting on, over imports.
+			nested.priority DESC,
+			nested.type = 'workspace_build' DESC,
 			nested.created_at
 		FOR UPDATE
 		SKIP LOCKED
@@ -50,7 +74,7 @@ WHERE
 -- name: GetProvisionerJobsByIDsWithQueuePosition :many
 WITH unstarted_jobs AS (
     SELECT
-        id, created_at
+        id, created_at, priority, type
     FROM
         provisioner_jobs
     WHERE
@@ -59,7 +83,8 @@ WITH unstarted_jobs AS (
 queue_position AS (
     SELECT
         id,
-        ROW_NUMBER() OVER (ORDER BY created_at ASC) AS queue_position
+        -- This must match the order in AcquireProvisionerJob.
+        ROW_NUMBER() OVER (ORDER BY priority DESC, type = 'workspace_build' DESC, created_at ASC) AS queue_position
     FROM
         unstarted_jobs
 ),
@@ -96,10 +121,11 @@ INSERT INTO
 		"type",
 		"input",
 		tags,
-		trace_metadata
+		trace_metadata,
+		tag_requirements
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateProvisionerJobByID :exec
 UPDATE
@@ -109,6 +135,15 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateProvisionerJobPriorityByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	priority = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: UpdateProvisionerJobWithCancelByID :exec
 UPDATE
 	provisioner_jobs
@@ -138,3 +173,54 @@ WHERE
 	updated_at < $1
 	AND started_at IS NOT NULL
 	AND completed_at IS NULL;
+
+-- name: GetOrphanedProvisionerJobs :many
+-- Returns running jobs acquired by provisioner daemons that have not sent a
+-- heartbeat since the given time.
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NOT NULL
+	AND completed_at IS NULL
+	AND worker_id IN (
+		SELECT
+			id
+		FROM
+			provisioner_daemons
+		WHERE
+			last_seen_at < @stale_since :: timestamptz
+	);
+
+-- name: GetTimedOutProvisionerJobs :many
+-- Returns running workspace builds that started before the given time minus
+-- the max build duration of their template.
+SELECT
+	provisioner_jobs.*
+FROM
+	provisioner_jobs
+JOIN
+	workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+JOIN
+	templates ON templates.id = workspaces.template_id
+WHERE
+	provisioner_jobs.started_at IS NOT NULL
+	AND provisioner_jobs.completed_at IS NULL
+	AND templates.max_build_duration > 0
+	AND provisioner_jobs.started_at < @now :: timestamptz - (templates.max_build_duration / 1000 / 1000 / 1000 || ' seconds')::interval;
+
+-- name: RequeueProvisionerJobByID :exec
+-- Returns a running job to the queue, so it is acquired by another
+-- provisioner daemon.
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL
+WHERE
+	id = $1
+	AND completed_at IS NULL;
@@ -0,0 +1,65 @@
+-- name: InsertProvisionerKey :one
+INSERT INTO
+	provisioner_keys (
+		id,
+		created_at,
+		organization_id,
+		"name",
+		hashed_secret,
+		tags
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetProvisionerKeyByID :one
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	id = $1;
+
+-- name: GetProvisionerKeyByHashedSecret :one
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	hashed_secret = $1;
+
+-- name: GetProvisionerKeyByName :one
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	organization_id = @organization_id
+	AND lower("name") = lower(@name);
+
+-- name: ListProvisionerKeysByOrganization :many
+SELECT
+	*
+FROM
+	provisioner_keys
+WHERE
+	organization_id = $1
+ORDER BY
+	lower("name") ASC;
+
+-- name: UpdateProvisionerKeySecretByID :one
+-- Replaces the secret of the key. Daemons authenticated with the old secret
+-- are disconnected.
+UPDATE
+	provisioner_keys
+SET
+	hashed_secret = @hashed_secret,
+	rotated_at = @rotated_at
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: DeleteProvisionerKey :exec
+DELETE FROM
+	provisioner_keys
+WHERE
+	id = $1;
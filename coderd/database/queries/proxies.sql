@@ -129,3 +129,23 @@ WHERE
 	)
 LIMIT
 	1;
+
+-- name: GetWorkspaceProxyHealth :many
+SELECT
+	*
+FROM
+	workspace_proxy_health;
+
+-- name: UpsertWorkspaceProxyHealth :exec
+INSERT INTO
+	workspace_proxy_health (proxy_id, status, latency_ms, derp_reachable, version_skew, errors, warnings, checked_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (proxy_id) DO UPDATE SET
+	status = $2,
+	latency_ms = $3,
+	derp_reachable = $4,
+	version_skew = $5,
+	errors = $6,
+	warnings = $7,
+	checked_at = $8;
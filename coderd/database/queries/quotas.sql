@@ -1,3 +1,34 @@
+-- name: GetOrganizationQuota :one
+SELECT
+	*
+FROM
+	organization_quotas
+WHERE
+	organization_id = $1;
+
+-- name: GetOrganizationResourceCostRollup :many
+-- Sums the recorded resource costs of the workspaces in an organization per
+-- owner, template and currency. Every record is the cost of a single day, so
+-- the sum is the total cost over the date range.
+SELECT
+	workspaces.owner_id,
+	workspaces.template_id,
+	workspace_resource_costs.currency,
+	COUNT(DISTINCT workspace_resource_costs.workspace_id)::bigint AS workspace_count,
+	SUM(workspace_resource_costs.daily_cost)::bigint AS total_cost
+FROM
+	workspace_resource_costs
+JOIN workspaces ON
+	workspaces.id = workspace_resource_costs.workspace_id
+WHERE
+	workspace_resource_costs.organization_id = @organization_id
+	AND workspace_resource_costs.date >= @start_date::date
+	AND workspace_resource_costs.date <= @end_date::date
+GROUP BY
+	workspaces.owner_id, workspaces.template_id, workspace_resource_costs.currency
+ORDER BY
+	workspaces.owner_id, workspaces.template_id, workspace_resource_costs.currency;
+
 -- name: GetQuotaAllowanceForUser :one
 SELECT
 	coalesce(SUM(quota_allowance), 0)::BIGINT
@@ -10,6 +41,27 @@ WHERE
 OR
     g.id = g.organization_id;
 
+-- name: GetQuotaConsumedForOrganization :one
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.organization_id = $1;
+
 -- name: GetQuotaConsumedForUser :one
 WITH latest_builds AS (
 SELECT
@@ -30,3 +82,108 @@ FROM
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
 WHERE NOT deleted AND workspaces.owner_id = $1;
+
+-- name: GetRunningWorkspaceCounts :one
+-- Counts the running workspaces of an owner in an organization and of a
+-- template. A workspace is running if its latest build started it and didn't
+-- fail or get canceled. The given workspace isn't counted, so restarting or
+-- updating it isn't limited.
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	transition,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+SELECT
+	COUNT(*) FILTER (WHERE workspaces.owner_id = @owner_id AND workspaces.organization_id = @organization_id) AS owner_count,
+	COUNT(*) FILTER (WHERE workspaces.template_id = @template_id) AS template_count
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN provisioner_jobs ON
+	provisioner_jobs.id = latest_builds.job_id
+WHERE
+	NOT workspaces.deleted
+	AND workspaces.id != @workspace_id
+	AND latest_builds.transition = 'start'
+	AND provisioner_jobs.canceled_at IS NULL
+	AND provisioner_jobs.error IS NULL;
+
+-- name: GetWorkspaceResourceCosts :many
+SELECT
+	*
+FROM
+	workspace_resource_costs
+WHERE
+	workspace_id = @workspace_id
+	AND date >= @start_date::date
+	AND date <= @end_date::date
+ORDER BY
+	date, resource_type, resource_name;
+
+-- name: UpsertOrganizationQuota :one
+INSERT INTO
+	organization_quotas (organization_id, max_running_workspaces_per_user, max_daily_cost, updated_at)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(organization_id)
+DO UPDATE SET
+	max_running_workspaces_per_user = $2,
+	max_daily_cost = $3,
+	updated_at = $4
+RETURNING *;
+
+-- name: UpsertWorkspaceResourceCosts :exec
+-- Records the daily cost of the resources of the latest build of every
+-- workspace that is not deleted. Recording again on the same date replaces
+-- the earlier record, so the last known cost of a day wins.
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+INSERT INTO
+	workspace_resource_costs (workspace_id, organization_id, date, resource_type, resource_name, currency, daily_cost, updated_at)
+SELECT
+	workspaces.id,
+	workspaces.organization_id,
+	@date::date,
+	workspace_resources.type,
+	workspace_resources.name,
+	MAX(COALESCE(workspace_resource_metadata.value, @default_currency::text)),
+	SUM(workspace_resources.daily_cost)::integer,
+	@updated_at::timestamptz
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN workspace_resources ON
+	workspace_resources.job_id = latest_builds.job_id
+LEFT JOIN workspace_resource_metadata ON
+	workspace_resource_metadata.workspace_resource_id = workspace_resources.id
+	AND workspace_resource_metadata.key = 'currency'
+WHERE
+	NOT workspaces.deleted
+	AND workspace_resources.daily_cost > 0
+GROUP BY
+	workspaces.id, workspaces.organization_id, workspace_resources.type, workspace_resources.name
+ON CONFLICT
+	(workspace_id, date, resource_type, resource_name)
+DO UPDATE SET
+	currency = EXCLUDED.currency,
+	daily_cost = EXCLUDED.daily_cost,
+	updated_at = EXCLUDED.updated_at;
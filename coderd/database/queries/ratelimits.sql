@@ -0,0 +1,16 @@
+-- name: IncrementRateLimitCounter :one
+-- Counts a request against the key for the window, returning the number of
+-- requests made in the window so far. The upsert is atomic, so replicas
+-- counting the same key concurrently never lose a request.
+INSERT INTO
+	rate_limit_counters (key, window_start, count)
+VALUES
+	($1, $2, 1)
+ON CONFLICT
+	(key, window_start)
+DO UPDATE SET
+	count = rate_limit_counters.count + 1
+RETURNING count;
+
+-- name: DeleteOldRateLimitCounters :exec
+DELETE FROM rate_limit_counters WHERE window_start < @before_time;
@@ -0,0 +1,36 @@
+-- name: CustomRolesByName :many
+SELECT
+	*
+FROM
+	custom_roles
+WHERE
+	-- Return every custom role if no names are given.
+	CASE WHEN cardinality(@lookup_roles :: text[]) > 0 THEN
+		name = ANY(@lookup_roles :: text[])
+	ELSE true
+	END
+ORDER BY
+	name ASC;
+
+-- name: UpsertCustomRole :one
+INSERT INTO
+	custom_roles (
+		name,
+		display_name,
+		site_permissions,
+		created_at,
+		updated_at
+	)
+VALUES (
+	@name,
+	@display_name,
+	@site_permissions,
+	now(),
+	now()
+)
+ON CONFLICT (name)
+	DO UPDATE SET
+		display_name = @display_name,
+		site_permissions = @site_permissions,
+		updated_at = now()
+RETURNING *;
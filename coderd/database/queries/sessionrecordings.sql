@@ -0,0 +1,55 @@
+-- name: InsertSessionRecording :one
+INSERT INTO
+	session_recordings (
+		id,
+		organization_id,
+		template_id,
+		workspace_id,
+		agent_id,
+		user_id,
+		started_at,
+		ended_at,
+		object_key,
+		size_bytes,
+		expires_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+
+-- name: GetSessionRecordingByID :one
+SELECT
+	*
+FROM
+	session_recordings
+WHERE
+	id = $1;
+
+-- name: GetSessionRecordingsByWorkspaceID :many
+SELECT
+	*
+FROM
+	session_recordings
+WHERE
+	workspace_id = $1
+ORDER BY
+	started_at DESC, id DESC;
+
+-- name: GetExpiredSessionRecordings :many
+-- Returns the recordings past their retention, oldest first. Their objects must
+-- be deleted from the store before the rows are deleted.
+SELECT
+	*
+FROM
+	session_recordings
+WHERE
+	expires_at <= @now :: timestamptz
+ORDER BY
+	expires_at ASC
+LIMIT
+	@limit_opt :: int;
+
+-- name: DeleteSessionRecordingByID :exec
+DELETE FROM
+	session_recordings
+WHERE
+	id = $1;
@@ -36,6 +36,17 @@ ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'last_update
 -- name: GetLastUpdateCheck :one
 SELECT value FROM site_configs WHERE key = 'last_update_check';
 
+-- name: GetLicenseGracePeriodStartedAt :one
+-- Returns an empty string unless every license has expired and the read-only
+-- grace period has started.
+SELECT
+	COALESCE((SELECT value FROM site_configs WHERE key = 'license_grace_period_started_at'), '') :: text AS license_grace_period_started_at
+;
+
+-- name: UpsertLicenseGracePeriodStartedAt :exec
+INSERT INTO site_configs (key, value) VALUES ('license_grace_period_started_at', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'license_grace_period_started_at';
+
 -- name: UpsertServiceBanner :exec
 INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner';
@@ -149,6 +149,15 @@ DO UPDATE SET
 	updated_at = now() at time zone 'utc'
 RETURNING *;
 
+-- name: UpdateTailnetPeerStatusByCoordinator :exec
+UPDATE
+	tailnet_peers
+SET
+	status = $2,
+	updated_at = now() at time zone 'utc'
+WHERE
+	coordinator_id = $1;
+
 -- name: DeleteTailnetPeer :one
 DELETE
 FROM tailnet_peers
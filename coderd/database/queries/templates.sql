@@ -83,10 +83,11 @@ INSERT INTO
 		user_acl,
 		group_acl,
 		display_name,
-		allow_user_cancel_workspace_jobs
+		allow_user_cancel_workspace_jobs,
+		visibility
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
 
 -- name: UpdateTemplateActiveVersionByID :exec
 UPDATE
@@ -192,3 +193,88 @@ SET
 WHERE
 	id = $1
 ;
+
+-- name: UpdateTemplateBuildRetryPolicyByID :exec
+UPDATE
+	templates
+SET
+	build_retry_max_attempts = $2,
+	build_retry_backoff = $3,
+	updated_at = $4
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateMaintenanceWindowByID :exec
+UPDATE
+	templates
+SET
+	maintenance_window_schedule = $2,
+	maintenance_window_duration = $3,
+	updated_at = $4
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateMaxBuildDurationByID :exec
+UPDATE
+	templates
+SET
+	max_build_duration = $2,
+	updated_at = $3
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateMaxRunningWorkspacesByID :exec
+UPDATE
+	templates
+SET
+	max_running_workspaces = $2,
+	updated_at = $3
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplatePortForwardingPolicyByID :exec
+UPDATE
+	templates
+SET
+	port_forwarding_deny_by_default = $2,
+	port_forwarding_allowed_ports = $3,
+	updated_at = $4
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateSessionRecordingByID :exec
+UPDATE
+	templates
+SET
+	session_recording_enabled = $2,
+	session_recording_retention = $3,
+	updated_at = $4
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateVisibilityByID :exec
+UPDATE
+	templates
+SET
+	visibility = $2,
+	updated_at = $3
+WHERE
+	id = $1
+;
+
+-- name: UpdateTemplateWorkspaceNamePolicyByID :exec
+UPDATE
+	templates
+SET
+	workspace_name_pattern = $2,
+	workspace_name_description = $3,
+	updated_at = $4
+WHERE
+	id = $1
+;
@@ -0,0 +1,61 @@
+-- name: GetTemplateVariableValuesByTemplateID :many
+SELECT
+	*
+FROM
+	template_variable_values
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC;
+
+-- name: UpsertTemplateVariableValue :one
+INSERT INTO
+	template_variable_values (template_id, name, value, value_key_id, sensitive, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(template_id, name)
+DO UPDATE SET
+	value = $3,
+	value_key_id = $4,
+	sensitive = $5,
+	updated_at = $7
+RETURNING *;
+
+-- name: DeleteTemplateVariableValue :exec
+DELETE FROM
+	template_variable_values
+WHERE
+	template_id = $1
+	AND name = $2;
+
+-- name: GetOrganizationTemplateVariableValues :many
+SELECT
+	*
+FROM
+	organization_template_variable_values
+WHERE
+	organization_id = $1
+ORDER BY
+	name ASC;
+
+-- name: UpsertOrganizationTemplateVariableValue :one
+INSERT INTO
+	organization_template_variable_values (organization_id, name, value, value_key_id, sensitive, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT
+	(organization_id, name)
+DO UPDATE SET
+	value = $3,
+	value_key_id = $4,
+	sensitive = $5,
+	updated_at = $7
+RETURNING *;
+
+-- name: DeleteOrganizationTemplateVariableValue :exec
+DELETE FROM
+	organization_template_variable_values
+WHERE
+	organization_id = $1
+	AND name = $2;
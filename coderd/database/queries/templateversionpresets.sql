@@ -0,0 +1,23 @@
+-- name: GetTemplateVersionPresets :many
+SELECT
+	*
+FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+ORDER BY
+	name ASC;
+
+-- name: InsertTemplateVersionPreset :one
+INSERT INTO
+	template_version_presets (template_version_id, name, parameters, created_at)
+VALUES
+	($1, $2, $3, $4)
+RETURNING *;
+
+-- name: DeleteTemplateVersionPreset :exec
+DELETE FROM
+	template_version_presets
+WHERE
+	template_version_id = $1
+	AND name = $2;
@@ -87,10 +87,13 @@ INSERT INTO
 		message,
 		readme,
 		job_id,
-		created_by
+		created_by,
+		git_commit_sha,
+		git_branch,
+		git_tag
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
 
 -- name: UpdateTemplateVersionByID :exec
 UPDATE
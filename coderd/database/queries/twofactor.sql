@@ -0,0 +1,124 @@
+-- name: InsertPasskey :one
+INSERT INTO
+	passkeys (id, user_id, name, credential_id, public_key, sign_count, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetPasskeysByUserID :many
+SELECT
+	*
+FROM
+	passkeys
+WHERE
+	user_id = $1
+ORDER BY
+	created_at, id;
+
+-- name: GetPasskeyByID :one
+SELECT
+	*
+FROM
+	passkeys
+WHERE
+	id = $1;
+
+-- name: UpdatePasskeyUsage :exec
+UPDATE
+	passkeys
+SET
+	sign_count = $2,
+	last_used_at = $3
+WHERE
+	id = $1;
+
+-- name: DeletePasskeyByID :exec
+DELETE FROM passkeys WHERE id = $1;
+
+-- name: InsertTwoFactorChallenge :one
+INSERT INTO
+	two_factor_challenges (id, user_id, purpose, challenge, created_at, expires_at)
+VALUES
+	($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: DeleteTwoFactorChallengeByID :one
+-- Challenges are deleted when they are answered, so that each challenge can
+-- only be used once.
+DELETE FROM two_factor_challenges WHERE id = $1 RETURNING *;
+
+-- name: DeleteExpiredTwoFactorChallenges :exec
+DELETE FROM two_factor_challenges WHERE expires_at < @before_time;
+
+-- name: InsertUserRecoveryCode :exec
+INSERT INTO
+	user_recovery_codes (id, user_id, hashed_code, created_at)
+VALUES
+	($1, $2, $3, $4);
+
+-- name: DeleteUserRecoveryCodesByUserID :exec
+DELETE FROM user_recovery_codes WHERE user_id = $1;
+
+-- name: GetUnusedUserRecoveryCodes :many
+-- Recovery codes are salted, so they can't be looked up by their hash. The
+-- code a user enters is compared with each of their unused codes instead.
+SELECT
+	*
+FROM
+	user_recovery_codes
+WHERE
+	user_id = $1
+	AND used_at IS NULL
+ORDER BY
+	created_at, id;
+
+-- name: UseUserRecoveryCode :one
+-- Marks an unused recovery code of the user as used. No rows are returned if
+-- the user has no such code, or if it was already used.
+UPDATE
+	user_recovery_codes
+SET
+	used_at = @used_at
+WHERE
+	id = @id
+	AND user_id = @user_id
+	AND used_at IS NULL
+RETURNING *;
+
+-- name: CountUnusedUserRecoveryCodes :one
+SELECT
+	COUNT(*)
+FROM
+	user_recovery_codes
+WHERE
+	user_id = $1
+	AND used_at IS NULL;
+
+-- name: GetTwoFactorPolicies :many
+SELECT
+	*
+FROM
+	two_factor_policies
+ORDER BY
+	role;
+
+-- name: GetTwoFactorPolicyByRole :one
+SELECT
+	*
+FROM
+	two_factor_policies
+WHERE
+	role = $1;
+
+-- name: UpsertTwoFactorPolicy :one
+INSERT INTO
+	two_factor_policies (id, role, enforce_after, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (role) DO UPDATE SET
+	enforce_after = $3,
+	updated_at = $5
+RETURNING *;
+
+-- name: DeleteTwoFactorPolicyByRole :exec
+DELETE FROM two_factor_policies WHERE role = $1;
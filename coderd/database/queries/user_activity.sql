@@ -0,0 +1,70 @@
+-- name: UpsertUserActivity :exec
+-- Adds request counts to the hourly totals of each user, API category and
+-- workspace. Every (user, hour, category, workspace) must appear at most once.
+INSERT INTO
+	user_activity (user_id, hour, category, workspace_id, count)
+SELECT
+	unnest(@user_id :: uuid [ ]) AS user_id,
+	unnest(@hour :: timestamptz [ ]) AS hour,
+	unnest(@category :: text [ ]) AS category,
+	unnest(@workspace_id :: uuid [ ]) AS workspace_id,
+	unnest(@count :: integer [ ]) AS count
+ON CONFLICT
+	(user_id, hour, category, workspace_id)
+DO UPDATE SET
+	count = user_activity.count + EXCLUDED.count;
+
+-- name: GetInactiveUsers :many
+-- Returns the users that have not used Coder since the given time, least
+-- recently seen first, with their most recent recorded activity. Users that
+-- have never been seen are only inactive if they were created before then, and
+-- suspended users are left out.
+SELECT
+	users.id,
+	users.username,
+	users.email,
+	users.avatar_url,
+	users.status,
+	users.last_seen_at,
+	last_activity.category AS last_activity_category,
+	last_activity.workspace_id AS last_activity_workspace_id,
+	last_activity.hour AS last_activity_hour
+FROM
+	users
+LEFT JOIN LATERAL (
+	SELECT
+		category, workspace_id, hour
+	FROM
+		user_activity
+	WHERE
+		user_activity.user_id = users.id
+	ORDER BY
+		hour DESC, count DESC
+	LIMIT 1
+) AS last_activity ON true
+WHERE
+	users.deleted = false
+	AND users.status != 'suspended'::user_status
+	AND users.last_seen_at < @inactive_since :: timestamp
+	AND (users.last_seen_at != '0001-01-01 00:00:00' :: timestamp OR users.created_at < @inactive_since)
+ORDER BY
+	users.last_seen_at ASC, users.username ASC;
+
+-- name: UpdateInactiveUsersToSuspended :many
+-- Suspends the users that have not used Coder since the given time. Owners are
+-- never suspended, so the deployment can't be locked out.
+UPDATE
+	users
+SET
+	status = 'suspended'::user_status,
+	updated_at = @updated_at
+WHERE
+	last_seen_at < @inactive_since :: timestamp
+	AND (last_seen_at != '0001-01-01 00:00:00' :: timestamp OR created_at < @inactive_since)
+	AND status != 'suspended'::user_status
+	AND deleted = false
+	AND NOT ('owner' = ANY(rbac_roles))
+RETURNING id, email, last_seen_at;
+
+-- name: DeleteOldUserActivity :exec
+DELETE FROM user_activity WHERE hour < @before_time;
@@ -0,0 +1,16 @@
+-- name: GetUserSCIMExternalIDByExternalID :one
+SELECT
+	*
+FROM
+	user_scim_external_ids
+WHERE
+	external_id = $1;
+
+-- name: UpsertUserSCIMExternalID :one
+INSERT INTO
+	user_scim_external_ids (user_id, external_id)
+VALUES
+	($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+	external_id = $2
+RETURNING *;
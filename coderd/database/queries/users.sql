@@ -94,6 +94,16 @@ WHERE
 	id = $1
 RETURNING *;
 
+-- name: UpdateUserIPAllowlist :one
+UPDATE
+	users
+SET
+	ip_allowlist = $2,
+	updated_at = $3
+WHERE
+	id = $1
+RETURNING *;
+
 -- name: UpdateUserRoles :one
 UPDATE
 	users
@@ -221,6 +231,8 @@ SELECT
 	-- status is used to enforce 'suspended' users, as all roles are ignored
 	--	when suspended.
 	id, username, status,
+	-- ip_allowlist limits the addresses the user's API keys may be used from.
+	ip_allowlist,
 	-- All user roles, including their org roles.
 	array_cat(
 		-- All users are members
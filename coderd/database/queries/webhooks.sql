@@ -0,0 +1,110 @@
+-- name: GetWebhooks :many
+SELECT
+	*
+FROM
+	webhooks
+ORDER BY
+	created_at ASC;
+
+-- name: GetWebhookByID :one
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: GetWebhooksByEvent :many
+SELECT
+	*
+FROM
+	webhooks
+WHERE
+	@event :: text = ANY(events)
+ORDER BY
+	created_at ASC;
+
+-- name: InsertWebhook :one
+INSERT INTO
+	webhooks (id, url, secret, secret_key_id, events, created_at, updated_at)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateWebhookSecretByID :exec
+UPDATE
+	webhooks
+SET
+	secret = $2,
+	secret_key_id = $3
+WHERE
+	id = $1;
+
+-- name: DeleteWebhookByID :exec
+DELETE FROM
+	webhooks
+WHERE
+	id = $1;
+
+-- name: InsertWebhookDelivery :one
+INSERT INTO
+	webhook_deliveries (id, webhook_id, event, payload, created_at, next_attempt_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	id = $1;
+
+-- name: GetWebhookDeliveriesByWebhookID :many
+SELECT
+	*
+FROM
+	webhook_deliveries
+WHERE
+	webhook_id = @webhook_id
+ORDER BY
+	created_at DESC
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- Acquires deliveries that are due to be attempted. The next attempt is
+-- pushed back to lease_until so that a crashed replica does not hold on to
+-- the delivery forever, and SKIP LOCKED prevents multiple replicas from
+-- acquiring the same delivery.
+-- name: AcquireWebhookDeliveries :many
+UPDATE
+	webhook_deliveries
+SET
+	next_attempt_at = @lease_until :: timestamptz
+WHERE
+	id IN (
+		SELECT
+			id
+		FROM
+			webhook_deliveries AS nested
+		WHERE
+			nested.next_attempt_at <= @now :: timestamptz
+		ORDER BY
+			nested.next_attempt_at
+		FOR UPDATE
+		SKIP LOCKED
+		LIMIT
+			@limit_opt :: int
+	) RETURNING *;
+
+-- name: UpdateWebhookDeliveryByID :exec
+UPDATE
+	webhook_deliveries
+SET
+	attempts = $2,
+	status_code = $3,
+	last_error = $4,
+	delivered_at = $5,
+	next_attempt_at = $6
+WHERE
+	id = $1;
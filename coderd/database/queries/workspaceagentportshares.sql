@@ -0,0 +1,38 @@
+-- name: GetWorkspaceAgentPortShare :one
+SELECT
+	*
+FROM
+	workspace_agent_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
+
+-- name: GetWorkspaceAgentPortSharesByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_agent_port_shares
+WHERE
+	workspace_id = $1
+ORDER BY
+	agent_name ASC, port ASC;
+
+-- name: UpsertWorkspaceAgentPortShare :one
+INSERT INTO
+	workspace_agent_port_shares (workspace_id, agent_name, port, share_level)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT
+	(workspace_id, agent_name, port)
+DO UPDATE SET
+	share_level = $4
+RETURNING *;
+
+-- name: DeleteWorkspaceAgentPortShare :exec
+DELETE FROM
+	workspace_agent_port_shares
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+	AND port = $3;
@@ -95,6 +95,116 @@ SET
 WHERE
 	id = $1;
 
+-- name: GetWorkspaceAgentAuthTokenByIDForUpdate :one
+-- Locks the agent's row, so concurrent rotations of its token are serialized.
+SELECT
+	auth_token
+FROM
+	workspace_agents
+WHERE
+	id = $1
+FOR UPDATE;
+
+-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
+-- name: UpsertWorkspaceAgentPreviousAuthToken :exec
+INSERT INTO
+	workspace_agent_previous_auth_tokens (
+		agent_id,
+		auth_token,
+		expires_at
+	)
+VALUES
+	($1, $2, $3)
+ON CONFLICT (agent_id) DO UPDATE SET
+	auth_token = $2,
+	expires_at = $3;
+
+-- name: DeleteWorkspaceAgentPreviousAuthTokenByAgentID :exec
+DELETE FROM
+	workspace_agent_previous_auth_tokens
+WHERE
+	agent_id = $1;
+
+-- name: UpsertWorkspaceAgentPorts :exec
+-- Replaces the listening ports of an agent. Ports that are still listening
+-- keep the time they were first discovered.
+WITH removed AS (
+	DELETE FROM
+		workspace_agent_ports
+	WHERE
+		agent_id = @agent_id :: uuid
+		AND NOT (port = ANY(@port :: integer [ ]))
+)
+INSERT INTO
+	workspace_agent_ports (agent_id, port, process_name, discovered_at)
+SELECT
+	@agent_id :: uuid AS agent_id,
+	unnest(@port :: integer [ ]) AS port,
+	unnest(@process_name :: text [ ]) AS process_name,
+	@discovered_at :: timestamptz AS discovered_at
+ON CONFLICT (agent_id, port) DO UPDATE SET
+	process_name = EXCLUDED.process_name;
+
+-- name: GetWorkspaceAgentPortsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_ports
+WHERE
+	agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	port ASC;
+
+-- name: UpsertWorkspaceAgentGPUs :exec
+-- Replaces the GPUs of an agent with the ones it detected on startup.
+WITH removed AS (
+	DELETE FROM
+		workspace_agent_gpus
+	WHERE
+		agent_id = @agent_id :: uuid
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				unnest(@index :: integer [ ], @vendor :: text [ ]) AS reported(index, vendor)
+			WHERE
+				reported.index = workspace_agent_gpus.index
+				AND reported.vendor = workspace_agent_gpus.vendor
+		)
+)
+INSERT INTO
+	workspace_agent_gpus (agent_id, index, vendor, model, memory_bytes, driver_version)
+SELECT
+	@agent_id :: uuid AS agent_id,
+	unnest(@index :: integer [ ]) AS index,
+	unnest(@vendor :: text [ ]) AS vendor,
+	unnest(@model :: text [ ]) AS model,
+	unnest(@memory_bytes :: bigint [ ]) AS memory_bytes,
+	unnest(@driver_version :: text [ ]) AS driver_version
+ON CONFLICT (agent_id, vendor, index) DO UPDATE SET
+	model = EXCLUDED.model,
+	memory_bytes = EXCLUDED.memory_bytes,
+	driver_version = EXCLUDED.driver_version;
+
+-- name: GetWorkspaceAgentGPUsByAgentIDs :many
+SELECT
+	*
+FROM
+	workspace_agent_gpus
+WHERE
+	agent_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	vendor ASC,
+	index ASC;
+
 -- name: InsertWorkspaceAgentMetadata :exec
 INSERT INTO
 	workspace_agent_metadata (
@@ -137,6 +247,70 @@ WHERE
 	workspace_agent_id = $1
 	AND CASE WHEN COALESCE(array_length(sqlc.arg('keys')::text[], 1), 0) > 0 THEN key = ANY(sqlc.arg('keys')::text[]) ELSE TRUE END;
 
+-- name: InsertWorkspaceAgentMetadataHistory :exec
+-- Records the values of the metadata of an agent and deletes the oldest values
+-- of each metadatum, so that at most @keep values are kept. Values of keys the
+-- agent doesn't have are ignored, like in UpdateWorkspaceAgentMetadata.
+WITH metadata AS (
+	SELECT
+		unnest(sqlc.arg('key')::text[]) AS key,
+		unnest(sqlc.arg('value')::text[]) AS value,
+		unnest(sqlc.arg('error')::text[]) AS error,
+		unnest(sqlc.arg('collected_at')::timestamptz[]) AS collected_at
+), inserted AS (
+	INSERT INTO
+		workspace_agent_metadata_history (workspace_agent_id, key, value, error, collected_at)
+	SELECT
+		wam.workspace_agent_id, m.key, m.value, m.error, m.collected_at
+	FROM
+		metadata m
+	JOIN
+		workspace_agent_metadata wam
+	ON
+		wam.workspace_agent_id = sqlc.arg('workspace_agent_id')::uuid
+		AND wam.key = m.key
+	ON CONFLICT DO NOTHING
+)
+DELETE FROM
+	workspace_agent_metadata_history wamh
+USING (
+	SELECT
+		key,
+		collected_at,
+		row_number() OVER (PARTITION BY key ORDER BY collected_at DESC) AS n
+	FROM
+		workspace_agent_metadata_history
+	WHERE
+		workspace_agent_id = sqlc.arg('workspace_agent_id')::uuid
+		AND key = ANY(sqlc.arg('key')::text[])
+) ranked
+WHERE
+	wamh.workspace_agent_id = sqlc.arg('workspace_agent_id')::uuid
+	AND wamh.key = ranked.key
+	AND wamh.collected_at = ranked.collected_at
+	-- The values inserted above aren't visible to this statement, so one old
+	-- value less is kept to make room for them.
+	AND ranked.n >= sqlc.arg('keep')::int;
+
+-- name: GetWorkspaceAgentMetadataHistory :many
+-- Returns the recorded values of the metadata of an agent collected after
+-- @since, oldest first. Without keys, the values of all metadata are returned.
+SELECT
+	*
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = @workspace_agent_id
+	AND collected_at > @since
+	AND CASE WHEN COALESCE(array_length(sqlc.arg('keys')::text[], 1), 0) > 0 THEN key = ANY(sqlc.arg('keys')::text[]) ELSE TRUE END
+ORDER BY
+	key, collected_at;
+
+-- name: DeleteOldWorkspaceAgentMetadataHistory :exec
+-- Deletes the metadata history of agents that stopped reporting, e.g. because
+-- their workspace was stopped.
+DELETE FROM workspace_agent_metadata_history WHERE collected_at < @before_time;
+
 -- name: UpdateWorkspaceAgentLogOverflowByID :exec
 UPDATE
 	workspace_agents
@@ -255,7 +429,19 @@ WHERE
 	-- TODO: we can add more conditions here, such as:
 	-- 1) The user must be active
 	-- 2) The workspace must be running
-	workspace_agents.auth_token = @auth_token
+	(
+		workspace_agents.auth_token = @auth_token
+		-- Tokens stay valid for a while after they are rotated.
+		OR workspace_agents.id IN (
+			SELECT
+				agent_id
+			FROM
+				workspace_agent_previous_auth_tokens
+			WHERE
+				workspace_agent_previous_auth_tokens.auth_token = @auth_token
+				AND workspace_agent_previous_auth_tokens.expires_at > NOW()
+		)
+	)
 AND
 	workspaces.deleted = FALSE
 GROUP BY
@@ -268,3 +454,30 @@ GROUP BY
 ORDER BY
 	workspace_builds.build_number DESC
 LIMIT 1;
+
+-- name: GetWorkspaceAgentCountsByLifecycleState :many
+-- Counts the agents in the latest build of workspaces that are not deleted by
+-- lifecycle state.
+SELECT
+	workspace_agents.lifecycle_state,
+	COUNT(*) AS count
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+WHERE
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+GROUP BY
+	workspace_agents.lifecycle_state;
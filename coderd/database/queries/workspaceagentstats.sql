@@ -17,10 +17,16 @@ INSERT INTO
 		session_count_jetbrains,
 		session_count_reconnecting_pty,
 		session_count_ssh,
-		connection_median_latency_ms
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_total_cores,
+		memory_used_bytes,
+		memory_total_bytes,
+		disk_used_bytes,
+		disk_total_bytes
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23) RETURNING *;
 
 -- name: InsertWorkspaceAgentStats :exec
 INSERT INTO
@@ -41,7 +47,13 @@ INSERT INTO
 		session_count_jetbrains,
 		session_count_reconnecting_pty,
 		session_count_ssh,
-		connection_median_latency_ms
+		connection_median_latency_ms,
+		cpu_used_cores,
+		cpu_total_cores,
+		memory_used_bytes,
+		memory_total_bytes,
+		disk_used_bytes,
+		disk_total_bytes
 	)
 SELECT
 	unnest(@id :: uuid[]) AS id,
@@ -60,7 +72,13 @@ SELECT
 	unnest(@session_count_jetbrains :: bigint[]) AS session_count_jetbrains,
 	unnest(@session_count_reconnecting_pty :: bigint[]) AS session_count_reconnecting_pty,
 	unnest(@session_count_ssh :: bigint[]) AS session_count_ssh,
-	unnest(@connection_median_latency_ms :: double precision[]) AS connection_median_latency_ms;
+	unnest(@connection_median_latency_ms :: double precision[]) AS connection_median_latency_ms,
+	unnest(@cpu_used_cores :: double precision[]) AS cpu_used_cores,
+	unnest(@cpu_total_cores :: double precision[]) AS cpu_total_cores,
+	unnest(@memory_used_bytes :: bigint[]) AS memory_used_bytes,
+	unnest(@memory_total_bytes :: bigint[]) AS memory_total_bytes,
+	unnest(@disk_used_bytes :: bigint[]) AS disk_used_bytes,
+	unnest(@disk_total_bytes :: bigint[]) AS disk_total_bytes;
 
 -- name: GetTemplateDAUs :many
 SELECT
@@ -195,3 +213,42 @@ JOIN
 	workspaces
 ON
 	workspaces.id = agent_stats.workspace_id;
+
+-- name: GetLatestWorkspaceAgentStatByAgentID :one
+SELECT
+	*
+FROM
+	workspace_agent_stats
+WHERE
+	agent_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1;
+
+-- name: GetWorkspaceAgentResourceUsageAndLabels :many
+SELECT DISTINCT ON (workspace_agent_stats.agent_id)
+	users.username, workspace_agents.name AS agent_name, workspaces.name AS workspace_name,
+	workspace_agent_stats.cpu_used_cores, workspace_agent_stats.cpu_total_cores,
+	workspace_agent_stats.memory_used_bytes, workspace_agent_stats.memory_total_bytes,
+	workspace_agent_stats.disk_used_bytes, workspace_agent_stats.disk_total_bytes
+FROM
+	workspace_agent_stats
+JOIN
+	users
+ON
+	users.id = workspace_agent_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = workspace_agent_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = workspace_agent_stats.workspace_id
+WHERE
+	workspace_agent_stats.created_at > $1
+	-- Agents that don't report resource usage send zero totals.
+	AND (workspace_agent_stats.cpu_total_cores > 0 OR workspace_agent_stats.memory_total_bytes > 0 OR workspace_agent_stats.disk_total_bytes > 0)
+ORDER BY
+	workspace_agent_stats.agent_id, workspace_agent_stats.created_at DESC;
@@ -39,3 +39,32 @@ SET
 	health = $2
 WHERE
 	id = $1;
+
+-- name: GetWorkspaceAppCountsByHealth :many
+-- Counts the apps in the latest build of workspaces that are not deleted by
+-- health.
+SELECT
+	workspace_apps.health,
+	COUNT(*) AS count
+FROM
+	workspace_apps
+JOIN
+	workspace_agents ON workspace_apps.agent_id = workspace_agents.id
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+WHERE
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+GROUP BY
+	workspace_apps.health;
@@ -57,6 +57,12 @@ WHERE
 		)
 		ELSE true
 END
+	-- Filter by the reason the build was created, e.g. autostart or batch.
+	AND CASE
+		WHEN @reason :: text != '' THEN
+			workspace_builds.reason = @reason :: build_reason
+		ELSE true
+	END
 ORDER BY
     build_number desc OFFSET @offset_opt
 LIMIT
@@ -120,10 +126,11 @@ INSERT INTO
 		provisioner_state,
 		deadline,
 		max_deadline,
-		reason
+		reason,
+		attempt
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
 
 -- name: UpdateWorkspaceBuildCostByID :exec
 UPDATE
@@ -150,6 +157,16 @@ SET
 	updated_at = @updated_at::timestamptz
 WHERE id = @id::uuid;
 
+-- Schedules a failed build to be retried, or clears the schedule with NULL.
+-- name: UpdateWorkspaceBuildRetryAtByID :exec
+UPDATE
+	workspace_builds
+SET
+	retry_at = $2,
+	updated_at = $3
+WHERE
+	id = $1;
+
 -- name: GetActiveWorkspaceBuildsByTemplateID :many
 SELECT wb.*
 FROM (
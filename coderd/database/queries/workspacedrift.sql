@@ -0,0 +1,89 @@
+-- name: GetDriftedWorkspaceCount :one
+SELECT
+	COUNT(*)
+FROM
+	workspace_drift
+JOIN workspaces ON
+	workspaces.id = workspace_drift.workspace_id
+WHERE
+	cardinality(workspace_drift.drifted_resources) > 0
+	AND NOT workspaces.deleted;
+
+-- name: GetWorkspaceDriftByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_drift
+WHERE
+	workspace_id = $1;
+
+-- name: GetWorkspaceIDsForDriftCheck :many
+-- Returns the running workspaces that are due a drift check. A workspace is
+-- skipped while its latest build or its previous drift check has not finished,
+-- so only one plan runs against its state at a time, and never against state
+-- that a build is still changing.
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) workspace_id,
+	transition,
+	job_id
+FROM
+	workspace_builds
+ORDER BY
+	workspace_id,
+	build_number DESC
+)
+SELECT
+	workspaces.id
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+JOIN provisioner_jobs ON
+	provisioner_jobs.id = latest_builds.job_id
+LEFT JOIN workspace_drift ON
+	workspace_drift.workspace_id = workspaces.id
+LEFT JOIN provisioner_jobs AS drift_jobs ON
+	drift_jobs.id = workspace_drift.job_id
+WHERE
+	NOT workspaces.deleted
+	AND workspaces.dormant_at IS NULL
+	AND latest_builds.transition = 'start'::workspace_transition
+	AND provisioner_jobs.job_status = 'succeeded'::provisioner_job_status
+	AND (
+		workspace_drift.workspace_id IS NULL
+		OR (
+			drift_jobs.completed_at IS NOT NULL
+			AND drift_jobs.created_at < @last_check_before::timestamptz
+		)
+	)
+ORDER BY
+	workspaces.id;
+
+-- name: UpdateWorkspaceDriftResult :exec
+-- Records the result of a drift check. Dry-runs that are not the latest drift
+-- check of the workspace are ignored.
+UPDATE
+	workspace_drift
+SET
+	build_id = @build_id,
+	checked_at = @checked_at::timestamptz,
+	drifted_resources = @drifted_resources,
+	updated_at = @checked_at
+WHERE
+	workspace_id = @workspace_id
+	AND job_id = @job_id;
+
+-- name: UpsertWorkspaceDriftJob :exec
+-- Starts a new drift check of a workspace. The result of the previous check
+-- is kept until the new one completes.
+INSERT INTO
+	workspace_drift (workspace_id, job_id, created_at, updated_at)
+VALUES
+	(@workspace_id, @job_id, @now, @now)
+ON CONFLICT
+	(workspace_id)
+DO UPDATE SET
+	job_id = EXCLUDED.job_id,
+	updated_at = EXCLUDED.updated_at;
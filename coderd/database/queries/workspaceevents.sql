@@ -0,0 +1,111 @@
+-- name: InsertWorkspaceEvent :exec
+INSERT INTO
+	workspace_events (id, workspace_id, created_at, type, user_id, agent_id, app_id, old_value, new_value)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetWorkspaceTimeline :many
+-- Returns the timeline of a workspace, newest first. It's made up of the
+-- recorded workspace events plus the start and completion of every build.
+WITH timeline AS (
+	SELECT
+		workspace_events.id,
+		workspace_events.created_at,
+		workspace_events.type :: text AS type,
+		workspace_events.user_id,
+		NULL :: uuid AS build_id,
+		0 :: int AS build_number,
+		'' :: text AS build_reason,
+		'' :: text AS transition,
+		'' :: text AS job_status,
+		workspace_events.agent_id,
+		COALESCE(workspace_agents.name, '') :: text AS agent_name,
+		workspace_events.app_id,
+		COALESCE(workspace_apps.slug, '') :: text AS app_slug,
+		workspace_events.old_value,
+		workspace_events.new_value
+	FROM
+		workspace_events
+	LEFT JOIN
+		workspace_agents ON workspace_agents.id = workspace_events.agent_id
+	LEFT JOIN
+		workspace_apps ON workspace_apps.id = workspace_events.app_id
+	WHERE
+		workspace_events.workspace_id = @workspace_id
+	UNION ALL
+	SELECT
+		workspace_builds.id,
+		workspace_builds.created_at,
+		'build_started',
+		workspace_builds.initiator_id,
+		workspace_builds.id,
+		workspace_builds.build_number,
+		workspace_builds.reason :: text,
+		workspace_builds.transition :: text,
+		provisioner_jobs.job_status :: text,
+		NULL,
+		'',
+		NULL,
+		'',
+		'',
+		''
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = @workspace_id
+	UNION ALL
+	-- The job ID tells the completion apart from the start of the build.
+	SELECT
+		provisioner_jobs.id,
+		provisioner_jobs.completed_at,
+		'build_completed',
+		workspace_builds.initiator_id,
+		workspace_builds.id,
+		workspace_builds.build_number,
+		workspace_builds.reason :: text,
+		workspace_builds.transition :: text,
+		provisioner_jobs.job_status :: text,
+		NULL,
+		'',
+		NULL,
+		'',
+		'',
+		''
+	FROM
+		workspace_builds
+	JOIN
+		provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+	WHERE
+		workspace_builds.workspace_id = @workspace_id
+		AND provisioner_jobs.completed_at IS NOT NULL
+)
+SELECT
+	*
+FROM
+	timeline
+WHERE
+	CASE
+		-- This allows using the last element on a page as effectively a cursor.
+		WHEN @after_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN (
+			(timeline.created_at, timeline.id) < (
+				SELECT
+					page_cursor.created_at, page_cursor.id
+				FROM
+					timeline AS page_cursor
+				WHERE
+					page_cursor.id = @after_id
+			)
+		)
+		ELSE true
+	END
+ORDER BY
+	timeline.created_at DESC, timeline.id DESC
+OFFSET @offset_opt
+LIMIT
+	-- A null limit means "no limit", so 0 means return all
+	NULLIF(@limit_opt :: int, 0);
+
+-- name: DeleteOldWorkspaceEvents :exec
+DELETE FROM workspace_events WHERE created_at < @before_time;
@@ -0,0 +1,20 @@
+-- name: GetFavoriteWorkspaceIDsByUserID :many
+SELECT
+	workspace_id
+FROM
+	workspace_favorites
+WHERE
+	user_id = @user_id;
+
+-- name: InsertWorkspaceFavorite :exec
+INSERT INTO
+	workspace_favorites (user_id, workspace_id, created_at)
+VALUES
+	(@user_id, @workspace_id, @created_at)
+ON CONFLICT (user_id, workspace_id) DO NOTHING;
+
+-- name: DeleteWorkspaceFavorite :exec
+DELETE FROM
+	workspace_favorites
+WHERE
+	user_id = @user_id AND workspace_id = @workspace_id;
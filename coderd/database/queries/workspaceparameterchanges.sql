@@ -0,0 +1,18 @@
+-- name: GetWorkspaceParameterChanges :many
+SELECT
+	workspace_parameter_changes.*,
+	COALESCE(visible_users.username, '') :: text AS initiator_username
+FROM
+	workspace_parameter_changes
+	LEFT JOIN visible_users ON workspace_parameter_changes.initiator_id = visible_users.id
+WHERE
+	workspace_parameter_changes.workspace_id = @workspace_id
+	-- Filter by the name of the parameter.
+	AND CASE
+		WHEN @name :: text != '' THEN
+			workspace_parameter_changes.name = @name
+		ELSE true
+	END
+ORDER BY
+	workspace_parameter_changes.build_number DESC,
+	workspace_parameter_changes.name ASC;
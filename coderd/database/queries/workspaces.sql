@@ -259,9 +259,17 @@ WHERE
 				  workspaces.last_used_at >= @last_used_after
 		  ELSE true
 	END
+	-- Filter by workspaces the requesting user has favorited
+	AND CASE
+		WHEN @favorite :: boolean THEN
+			workspaces.id IN (SELECT workspace_id FROM workspace_favorites WHERE workspace_favorites.user_id = @requester_id :: uuid)
+		ELSE true
+	END
 	-- Authorize Filter clause will be injected below in GetAuthorizedWorkspaces
 	-- @authorize_filter
 ORDER BY
+	-- Workspaces the requesting user has favorited are listed first.
+	workspaces.id IN (SELECT workspace_id FROM workspace_favorites WHERE workspace_favorites.user_id = @requester_id :: uuid) DESC,
 	(latest_build.completed_at IS NOT NULL AND
 		latest_build.canceled_at IS NULL AND
 		latest_build.error IS NULL AND
@@ -297,6 +305,46 @@ WHERE
 	template_id = ANY(@template_ids :: uuid[]) AND deleted = false
 GROUP BY template_id;
 
+-- name: GetWorkspaceCountsByTemplateAndStatus :many
+-- Counts the workspaces that are not deleted by template, organization and the
+-- status of their latest build.
+SELECT
+	templates.name AS template_name,
+	organizations.name AS organization_name,
+	workspace_builds.transition,
+	provisioner_jobs.job_status,
+	COUNT(*) AS count
+FROM
+	workspaces
+JOIN
+	templates ON workspaces.template_id = templates.id
+JOIN
+	organizations ON workspaces.organization_id = organizations.id
+JOIN
+	workspace_builds ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+WHERE
+	workspaces.deleted = false AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+GROUP BY
+	templates.name, organizations.name, workspace_builds.transition, provisioner_jobs.job_status;
+
+-- name: GetWorkspacesByIDs :many
+SELECT
+	*
+FROM
+	workspaces
+WHERE
+	id = ANY(@ids :: uuid[]);
+
 -- name: InsertWorkspace :one
 INSERT INTO
 	workspaces (
@@ -310,10 +358,12 @@ INSERT INTO
 		autostart_schedule,
 		ttl,
 		last_used_at,
-		automatic_updates
+		automatic_updates,
+		ephemeral,
+		ephemeral_api_key_id
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -333,6 +383,17 @@ WHERE
 	AND deleted = false
 RETURNING *;
 
+-- name: UpdateWorkspaceOwnerByID :one
+UPDATE
+	workspaces
+SET
+	owner_id = @owner_id,
+	updated_at = @updated_at
+WHERE
+	id = @id
+	AND deleted = false
+RETURNING *;
+
 -- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -477,18 +538,51 @@ WHERE
 			workspace_builds.transition = 'start'::workspace_transition
 		) OR
 
+		-- If the workspace's most recent build failed transiently, it is
+		-- eligible to be retried once its backoff elapsed.
+		(
+			workspace_builds.retry_at IS NOT NULL AND
+			workspace_builds.retry_at <= @now :: timestamptz
+		) OR
+
 		-- If the workspace's template has an inactivity_ttl set
-		-- it may be eligible for dormancy.
+		-- it may be eligible for dormancy, unless it is exempt.
 		(
 			templates.time_til_dormant > 0 AND
-			workspaces.dormant_at IS NULL
+			workspaces.dormant_at IS NULL AND
+			NOT workspaces.dormancy_exempt
 		) OR
 
 		-- If the workspace's template has a time_til_dormant_autodelete set
 		-- and the workspace is already dormant.
 		(
 			templates.time_til_dormant_autodelete > 0 AND
-			workspaces.dormant_at IS NOT NULL
+			workspaces.dormant_at IS NOT NULL AND
+			NOT workspaces.dormancy_exempt
+		) OR
+
+		-- If the workspace's template has a maintenance window and the
+		-- workspace is running an outdated version, it may be eligible for
+		-- a maintenance stop. The caller checks whether the window is open.
+		(
+			templates.maintenance_window_schedule != '' AND
+			workspace_builds.transition = 'start'::workspace_transition AND
+			workspace_builds.template_version_id != templates.active_version_id AND
+			NOT workspaces.maintenance_opt_out
+		) OR
+
+		-- If the workspace was stopped for maintenance it is eligible to be
+		-- started again on the active version.
+		(
+			workspace_builds.transition = 'stop'::workspace_transition AND
+			workspace_builds.reason = 'maintenance'::build_reason
+		) OR
+
+		-- Ephemeral workspaces are eligible for deletion. The caller checks
+		-- whether the workspace outlived its TTL or the API key that created
+		-- it expired.
+		(
+			workspaces.ephemeral
 		)
 	) AND workspaces.deleted = 'false';
 
@@ -547,3 +641,27 @@ SET
 	automatic_updates = $2
 WHERE
 		id = $1;
+
+-- name: UpdateWorkspaceMaintenanceOptOut :exec
+UPDATE
+	workspaces
+SET
+	maintenance_opt_out = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceACLByID :exec
+UPDATE
+	workspaces
+SET
+	user_acl = @user_acl
+WHERE
+	id = @id;
+
+-- name: UpdateWorkspaceDormancyExempt :exec
+UPDATE
+	workspaces
+SET
+	dormancy_exempt = $2
+WHERE
+	id = $1;
@@ -1,6 +1,6 @@
 -- name: InsertWorkspaceAgentScripts :many
 INSERT INTO
-	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds)
+	workspace_agent_scripts (workspace_agent_id, created_at, log_source_id, log_path, script, cron, start_blocks_login, run_on_start, run_on_stop, timeout_seconds, phase)
 SELECT
 	@workspace_agent_id :: uuid AS workspace_agent_id,
 	@created_at :: timestamptz AS created_at,
@@ -11,7 +11,8 @@ SELECT
 	unnest(@start_blocks_login :: boolean [ ]) AS start_blocks_login,
 	unnest(@run_on_start :: boolean [ ]) AS run_on_start,
 	unnest(@run_on_stop :: boolean [ ]) AS run_on_stop,
-	unnest(@timeout_seconds :: integer [ ]) AS timeout_seconds
+	unnest(@timeout_seconds :: integer [ ]) AS timeout_seconds,
+	unnest(@phase :: workspace_agent_script_phase [ ]) AS phase
 RETURNING workspace_agent_scripts.*;
 
 -- name: GetWorkspaceAgentScriptsByAgentIDs :many
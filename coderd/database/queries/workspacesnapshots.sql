@@ -0,0 +1,29 @@
+-- name: GetWorkspaceSnapshotByID :one
+SELECT
+	*
+FROM
+	workspace_snapshots
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceSnapshotsByWorkspaceID :many
+SELECT
+	sqlc.embed(workspace_snapshots),
+	workspace_builds.job_id,
+	provisioner_jobs.job_status
+FROM
+	workspace_snapshots
+JOIN
+	workspace_builds ON workspace_builds.id = workspace_snapshots.build_id
+JOIN
+	provisioner_jobs ON provisioner_jobs.id = workspace_builds.job_id
+WHERE
+	workspace_snapshots.workspace_id = $1
+ORDER BY
+	workspace_snapshots.created_at DESC;
+
+-- name: InsertWorkspaceSnapshot :one
+INSERT INTO
+	workspace_snapshots (id, workspace_id, build_id, name, created_by, created_at)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/provisionertags"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -85,3 +86,24 @@ func (m *StringMap) Scan(src interface{}) error {
 func (m StringMap) Value() (driver.Value, error) {
 	return json.Marshal(m)
 }
+
+// ProvisionerTagRequirements are the tag requirements of a provisioner job.
+type ProvisionerTagRequirements []provisionertags.Requirement
+
+func (r *ProvisionerTagRequirements) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, r)
+	case string:
+		return json.Unmarshal([]byte(src), r)
+	}
+	return xerrors.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, r)
+}
+
+func (r ProvisionerTagRequirements) Value() (driver.Value, error) {
+	if r == nil {
+		// The column is not nullable.
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
@@ -9,6 +9,8 @@ const (
 	UniqueAgentStatsPkey                                    UniqueConstraint = "agent_stats_pkey"                                         // ALTER TABLE ONLY workspace_agent_stats ADD CONSTRAINT agent_stats_pkey PRIMARY KEY (id);
 	UniqueAPIKeysPkey                                       UniqueConstraint = "api_keys_pkey"                                            // ALTER TABLE ONLY api_keys ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 	UniqueAuditLogsPkey                                     UniqueConstraint = "audit_logs_pkey"                                          // ALTER TABLE ONLY audit_logs ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
+	UniqueConnectionLogsPkey                                UniqueConstraint = "connection_logs_pkey"                                     // ALTER TABLE ONLY connection_logs ADD CONSTRAINT connection_logs_pkey PRIMARY KEY (id);
+	UniqueCustomRolesPkey                                   UniqueConstraint = "custom_roles_pkey"                                        // ALTER TABLE ONLY custom_roles ADD CONSTRAINT custom_roles_pkey PRIMARY KEY (name);
 	UniqueDbcryptKeysActiveKeyDigestKey                     UniqueConstraint = "dbcrypt_keys_active_key_digest_key"                       // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_active_key_digest_key UNIQUE (active_key_digest);
 	UniqueDbcryptKeysPkey                                   UniqueConstraint = "dbcrypt_keys_pkey"                                        // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_pkey PRIMARY KEY (number);
 	UniqueDbcryptKeysRevokedKeyDigestKey                    UniqueConstraint = "dbcrypt_keys_revoked_key_digest_key"                      // ALTER TABLE ONLY dbcrypt_keys ADD CONSTRAINT dbcrypt_keys_revoked_key_digest_key UNIQUE (revoked_key_digest);
@@ -17,23 +19,40 @@ const (
 	UniqueGitAuthLinksProviderIDUserIDKey                   UniqueConstraint = "git_auth_links_provider_id_user_id_key"                   // ALTER TABLE ONLY external_auth_links ADD CONSTRAINT git_auth_links_provider_id_user_id_key UNIQUE (provider_id, user_id);
 	UniqueGitSSHKeysPkey                                    UniqueConstraint = "gitsshkeys_pkey"                                          // ALTER TABLE ONLY gitsshkeys ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
 	UniqueGroupMembersUserIDGroupIDKey                      UniqueConstraint = "group_members_user_id_group_id_key"                       // ALTER TABLE ONLY group_members ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
+	UniqueGroupSyncRunsPkey                                 UniqueConstraint = "group_sync_runs_pkey"                                     // ALTER TABLE ONLY group_sync_runs ADD CONSTRAINT group_sync_runs_pkey PRIMARY KEY (id);
 	UniqueGroupsNameOrganizationIDKey                       UniqueConstraint = "groups_name_organization_id_key"                          // ALTER TABLE ONLY groups ADD CONSTRAINT groups_name_organization_id_key UNIQUE (name, organization_id);
 	UniqueGroupsPkey                                        UniqueConstraint = "groups_pkey"                                              // ALTER TABLE ONLY groups ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
+	UniqueInboxNotificationsPkey                            UniqueConstraint = "inbox_notifications_pkey"                                 // ALTER TABLE ONLY inbox_notifications ADD CONSTRAINT inbox_notifications_pkey PRIMARY KEY (id);
 	UniqueLicensesJWTKey                                    UniqueConstraint = "licenses_jwt_key"                                         // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 	UniqueLicensesPkey                                      UniqueConstraint = "licenses_pkey"                                            // ALTER TABLE ONLY licenses ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
+	UniqueLoginFailuresPkey                                 UniqueConstraint = "login_failures_pkey"                                      // ALTER TABLE ONLY login_failures ADD CONSTRAINT login_failures_pkey PRIMARY KEY (user_id, ip_address);
+	UniqueNotificationMessagesPkey                          UniqueConstraint = "notification_messages_pkey"                               // ALTER TABLE ONLY notification_messages ADD CONSTRAINT notification_messages_pkey PRIMARY KEY (id);
+	UniqueNotificationPreferencesPkey                       UniqueConstraint = "notification_preferences_pkey"                            // ALTER TABLE ONLY notification_preferences ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, template);
+	UniqueOauth2ProviderAppCodesPkey                        UniqueConstraint = "oauth2_provider_app_codes_pkey"                           // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppCodesSecretPrefixKey             UniqueConstraint = "oauth2_provider_app_codes_secret_prefix_key"              // ALTER TABLE ONLY oauth2_provider_app_codes ADD CONSTRAINT oauth2_provider_app_codes_secret_prefix_key UNIQUE (secret_prefix);
 	UniqueOauth2ProviderAppSecretsAppIDHashedSecretKey      UniqueConstraint = "oauth2_provider_app_secrets_app_id_hashed_secret_key"     // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_app_id_hashed_secret_key UNIQUE (app_id, hashed_secret);
 	UniqueOauth2ProviderAppSecretsPkey                      UniqueConstraint = "oauth2_provider_app_secrets_pkey"                         // ALTER TABLE ONLY oauth2_provider_app_secrets ADD CONSTRAINT oauth2_provider_app_secrets_pkey PRIMARY KEY (id);
+	UniqueOauth2ProviderAppTokensHashPrefixKey              UniqueConstraint = "oauth2_provider_app_tokens_hash_prefix_key"               // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_hash_prefix_key UNIQUE (hash_prefix);
+	UniqueOauth2ProviderAppTokensPkey                       UniqueConstraint = "oauth2_provider_app_tokens_pkey"                          // ALTER TABLE ONLY oauth2_provider_app_tokens ADD CONSTRAINT oauth2_provider_app_tokens_pkey PRIMARY KEY (id);
 	UniqueOauth2ProviderAppsNameKey                         UniqueConstraint = "oauth2_provider_apps_name_key"                            // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_name_key UNIQUE (name);
 	UniqueOauth2ProviderAppsPkey                            UniqueConstraint = "oauth2_provider_apps_pkey"                                // ALTER TABLE ONLY oauth2_provider_apps ADD CONSTRAINT oauth2_provider_apps_pkey PRIMARY KEY (id);
 	UniqueOrganizationMembersPkey                           UniqueConstraint = "organization_members_pkey"                                // ALTER TABLE ONLY organization_members ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
+	UniqueOrganizationQuotasPkey                            UniqueConstraint = "organization_quotas_pkey"                                 // ALTER TABLE ONLY organization_quotas ADD CONSTRAINT organization_quotas_pkey PRIMARY KEY (organization_id);
+	UniqueOrganizationTemplateVariableValuesPkey            UniqueConstraint = "organization_template_variable_values_pkey"               // ALTER TABLE ONLY organization_template_variable_values ADD CONSTRAINT organization_template_variable_values_pkey PRIMARY KEY (organization_id, name);
 	UniqueOrganizationsPkey                                 UniqueConstraint = "organizations_pkey"                                       // ALTER TABLE ONLY organizations ADD CONSTRAINT organizations_pkey PRIMARY KEY (id);
 	UniqueParameterSchemasJobIDNameKey                      UniqueConstraint = "parameter_schemas_job_id_name_key"                        // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_job_id_name_key UNIQUE (job_id, name);
 	UniqueParameterSchemasPkey                              UniqueConstraint = "parameter_schemas_pkey"                                   // ALTER TABLE ONLY parameter_schemas ADD CONSTRAINT parameter_schemas_pkey PRIMARY KEY (id);
 	UniqueParameterValuesPkey                               UniqueConstraint = "parameter_values_pkey"                                    // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_pkey PRIMARY KEY (id);
 	UniqueParameterValuesScopeIDNameKey                     UniqueConstraint = "parameter_values_scope_id_name_key"                       // ALTER TABLE ONLY parameter_values ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
+	UniquePasskeysCredentialIDKey                           UniqueConstraint = "passkeys_credential_id_key"                               // ALTER TABLE ONLY passkeys ADD CONSTRAINT passkeys_credential_id_key UNIQUE (credential_id);
+	UniquePasskeysPkey                                      UniqueConstraint = "passkeys_pkey"                                            // ALTER TABLE ONLY passkeys ADD CONSTRAINT passkeys_pkey PRIMARY KEY (id);
 	UniqueProvisionerDaemonsPkey                            UniqueConstraint = "provisioner_daemons_pkey"                                 // ALTER TABLE ONLY provisioner_daemons ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
+	UniqueProvisionerJobLogArchivesPkey                     UniqueConstraint = "provisioner_job_log_archives_pkey"                        // ALTER TABLE ONLY provisioner_job_log_archives ADD CONSTRAINT provisioner_job_log_archives_pkey PRIMARY KEY (job_id);
 	UniqueProvisionerJobLogsPkey                            UniqueConstraint = "provisioner_job_logs_pkey"                                // ALTER TABLE ONLY provisioner_job_logs ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 	UniqueProvisionerJobsPkey                               UniqueConstraint = "provisioner_jobs_pkey"                                    // ALTER TABLE ONLY provisioner_jobs ADD CONSTRAINT provisioner_jobs_pkey PRIMARY KEY (id);
+	UniqueProvisionerKeysPkey                               UniqueConstraint = "provisioner_keys_pkey"                                    // ALTER TABLE ONLY provisioner_keys ADD CONSTRAINT provisioner_keys_pkey PRIMARY KEY (id);
+	UniqueRateLimitCountersPkey                             UniqueConstraint = "rate_limit_counters_pkey"                                 // ALTER TABLE ONLY rate_limit_counters ADD CONSTRAINT rate_limit_counters_pkey PRIMARY KEY (key, window_start);
+	UniqueSessionRecordingsPkey                             UniqueConstraint = "session_recordings_pkey"                                  // ALTER TABLE ONLY session_recordings ADD CONSTRAINT session_recordings_pkey PRIMARY KEY (id);
 	UniqueSiteConfigsKeyKey                                 UniqueConstraint = "site_configs_key_key"                                     // ALTER TABLE ONLY site_configs ADD CONSTRAINT site_configs_key_key UNIQUE (key);
 	UniqueTailnetAgentsPkey                                 UniqueConstraint = "tailnet_agents_pkey"                                      // ALTER TABLE ONLY tailnet_agents ADD CONSTRAINT tailnet_agents_pkey PRIMARY KEY (id, coordinator_id);
 	UniqueTailnetClientSubscriptionsPkey                    UniqueConstraint = "tailnet_client_subscriptions_pkey"                        // ALTER TABLE ONLY tailnet_client_subscriptions ADD CONSTRAINT tailnet_client_subscriptions_pkey PRIMARY KEY (client_id, coordinator_id, agent_id);
@@ -41,15 +60,33 @@ const (
 	UniqueTailnetCoordinatorsPkey                           UniqueConstraint = "tailnet_coordinators_pkey"                                // ALTER TABLE ONLY tailnet_coordinators ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
 	UniqueTailnetPeersPkey                                  UniqueConstraint = "tailnet_peers_pkey"                                       // ALTER TABLE ONLY tailnet_peers ADD CONSTRAINT tailnet_peers_pkey PRIMARY KEY (id, coordinator_id);
 	UniqueTailnetTunnelsPkey                                UniqueConstraint = "tailnet_tunnels_pkey"                                     // ALTER TABLE ONLY tailnet_tunnels ADD CONSTRAINT tailnet_tunnels_pkey PRIMARY KEY (coordinator_id, src_id, dst_id);
+	UniqueTemplateVariableValuesPkey                        UniqueConstraint = "template_variable_values_pkey"                            // ALTER TABLE ONLY template_variable_values ADD CONSTRAINT template_variable_values_pkey PRIMARY KEY (template_id, name);
 	UniqueTemplateVersionParametersTemplateVersionIDNameKey UniqueConstraint = "template_version_parameters_template_version_id_name_key" // ALTER TABLE ONLY template_version_parameters ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
+	UniqueTemplateVersionPresetsPkey                        UniqueConstraint = "template_version_presets_pkey"                            // ALTER TABLE ONLY template_version_presets ADD CONSTRAINT template_version_presets_pkey PRIMARY KEY (template_version_id, name);
 	UniqueTemplateVersionVariablesTemplateVersionIDNameKey  UniqueConstraint = "template_version_variables_template_version_id_name_key"  // ALTER TABLE ONLY template_version_variables ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 	UniqueTemplateVersionsPkey                              UniqueConstraint = "template_versions_pkey"                                   // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_pkey PRIMARY KEY (id);
 	UniqueTemplateVersionsTemplateIDNameKey                 UniqueConstraint = "template_versions_template_id_name_key"                   // ALTER TABLE ONLY template_versions ADD CONSTRAINT template_versions_template_id_name_key UNIQUE (template_id, name);
 	UniqueTemplatesPkey                                     UniqueConstraint = "templates_pkey"                                           // ALTER TABLE ONLY templates ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
+	UniqueTwoFactorChallengesPkey                           UniqueConstraint = "two_factor_challenges_pkey"                               // ALTER TABLE ONLY two_factor_challenges ADD CONSTRAINT two_factor_challenges_pkey PRIMARY KEY (id);
+	UniqueTwoFactorPoliciesPkey                             UniqueConstraint = "two_factor_policies_pkey"                                 // ALTER TABLE ONLY two_factor_policies ADD CONSTRAINT two_factor_policies_pkey PRIMARY KEY (id);
+	UniqueTwoFactorPoliciesRoleKey                          UniqueConstraint = "two_factor_policies_role_key"                             // ALTER TABLE ONLY two_factor_policies ADD CONSTRAINT two_factor_policies_role_key UNIQUE (role);
+	UniqueUnknownLoginFailuresPkey                          UniqueConstraint = "unknown_login_failures_pkey"                              // ALTER TABLE ONLY unknown_login_failures ADD CONSTRAINT unknown_login_failures_pkey PRIMARY KEY (login_hash, ip_address);
+	UniqueUserActivityPkey                                  UniqueConstraint = "user_activity_pkey"                                       // ALTER TABLE ONLY user_activity ADD CONSTRAINT user_activity_pkey PRIMARY KEY (user_id, hour, category, workspace_id);
 	UniqueUserLinksPkey                                     UniqueConstraint = "user_links_pkey"                                          // ALTER TABLE ONLY user_links ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
+	UniqueUserLockoutsPkey                                  UniqueConstraint = "user_lockouts_pkey"                                       // ALTER TABLE ONLY user_lockouts ADD CONSTRAINT user_lockouts_pkey PRIMARY KEY (user_id);
+	UniqueUserRecoveryCodesPkey                             UniqueConstraint = "user_recovery_codes_pkey"                                 // ALTER TABLE ONLY user_recovery_codes ADD CONSTRAINT user_recovery_codes_pkey PRIMARY KEY (id);
+	UniqueUserScimExternalIDsExternalIDKey                  UniqueConstraint = "user_scim_external_ids_external_id_key"                   // ALTER TABLE ONLY user_scim_external_ids ADD CONSTRAINT user_scim_external_ids_external_id_key UNIQUE (external_id);
+	UniqueUserScimExternalIDsPkey                           UniqueConstraint = "user_scim_external_ids_pkey"                              // ALTER TABLE ONLY user_scim_external_ids ADD CONSTRAINT user_scim_external_ids_pkey PRIMARY KEY (user_id);
 	UniqueUsersPkey                                         UniqueConstraint = "users_pkey"                                               // ALTER TABLE ONLY users ADD CONSTRAINT users_pkey PRIMARY KEY (id);
+	UniqueWebhookDeliveriesPkey                             UniqueConstraint = "webhook_deliveries_pkey"                                  // ALTER TABLE ONLY webhook_deliveries ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY (id);
+	UniqueWebhooksPkey                                      UniqueConstraint = "webhooks_pkey"                                            // ALTER TABLE ONLY webhooks ADD CONSTRAINT webhooks_pkey PRIMARY KEY (id);
+	UniqueWorkspaceAgentGpusPkey                            UniqueConstraint = "workspace_agent_gpus_pkey"                                // ALTER TABLE ONLY workspace_agent_gpus ADD CONSTRAINT workspace_agent_gpus_pkey PRIMARY KEY (agent_id, vendor, index);
 	UniqueWorkspaceAgentLogSourcesPkey                      UniqueConstraint = "workspace_agent_log_sources_pkey"                         // ALTER TABLE ONLY workspace_agent_log_sources ADD CONSTRAINT workspace_agent_log_sources_pkey PRIMARY KEY (workspace_agent_id, id);
 	UniqueWorkspaceAgentMetadataPkey                        UniqueConstraint = "workspace_agent_metadata_pkey"                            // ALTER TABLE ONLY workspace_agent_metadata ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
+	UniqueWorkspaceAgentMetadataHistoryPkey                 UniqueConstraint = "workspace_agent_metadata_history_pkey"                    // ALTER TABLE ONLY workspace_agent_metadata_history ADD CONSTRAINT workspace_agent_metadata_history_pkey PRIMARY KEY (workspace_agent_id, key, collected_at);
+	UniqueWorkspaceAgentPortSharesPkey                      UniqueConstraint = "workspace_agent_port_shares_pkey"                         // ALTER TABLE ONLY workspace_agent_port_shares ADD CONSTRAINT workspace_agent_port_shares_pkey PRIMARY KEY (workspace_id, agent_name, port);
+	UniqueWorkspaceAgentPortsPkey                           UniqueConstraint = "workspace_agent_ports_pkey"                               // ALTER TABLE ONLY workspace_agent_ports ADD CONSTRAINT workspace_agent_ports_pkey PRIMARY KEY (agent_id, port);
+	UniqueWorkspaceAgentPreviousAuthTokensPkey              UniqueConstraint = "workspace_agent_previous_auth_tokens_pkey"                // ALTER TABLE ONLY workspace_agent_previous_auth_tokens ADD CONSTRAINT workspace_agent_previous_auth_tokens_pkey PRIMARY KEY (agent_id);
 	UniqueWorkspaceAgentStartupLogsPkey                     UniqueConstraint = "workspace_agent_startup_logs_pkey"                        // ALTER TABLE ONLY workspace_agent_logs ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAgentsPkey                               UniqueConstraint = "workspace_agents_pkey"                                    // ALTER TABLE ONLY workspace_agents ADD CONSTRAINT workspace_agents_pkey PRIMARY KEY (id);
 	UniqueWorkspaceAppStatsPkey                             UniqueConstraint = "workspace_app_stats_pkey"                                 // ALTER TABLE ONLY workspace_app_stats ADD CONSTRAINT workspace_app_stats_pkey PRIMARY KEY (id);
@@ -60,11 +97,19 @@ const (
 	UniqueWorkspaceBuildsJobIDKey                           UniqueConstraint = "workspace_builds_job_id_key"                              // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 	UniqueWorkspaceBuildsPkey                               UniqueConstraint = "workspace_builds_pkey"                                    // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_pkey PRIMARY KEY (id);
 	UniqueWorkspaceBuildsWorkspaceIDBuildNumberKey          UniqueConstraint = "workspace_builds_workspace_id_build_number_key"           // ALTER TABLE ONLY workspace_builds ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
+	UniqueWorkspaceDriftPkey                                UniqueConstraint = "workspace_drift_pkey"                                     // ALTER TABLE ONLY workspace_drift ADD CONSTRAINT workspace_drift_pkey PRIMARY KEY (workspace_id);
+	UniqueWorkspaceEventsPkey                               UniqueConstraint = "workspace_events_pkey"                                    // ALTER TABLE ONLY workspace_events ADD CONSTRAINT workspace_events_pkey PRIMARY KEY (id);
+	UniqueWorkspaceFavoritesPkey                            UniqueConstraint = "workspace_favorites_pkey"                                 // ALTER TABLE ONLY workspace_favorites ADD CONSTRAINT workspace_favorites_pkey PRIMARY KEY (user_id, workspace_id);
+	UniqueWorkspaceParameterChangesPkey                     UniqueConstraint = "workspace_parameter_changes_pkey"                         // ALTER TABLE ONLY workspace_parameter_changes ADD CONSTRAINT workspace_parameter_changes_pkey PRIMARY KEY (id);
 	UniqueWorkspaceProxiesPkey                              UniqueConstraint = "workspace_proxies_pkey"                                   // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 	UniqueWorkspaceProxiesRegionIDUnique                    UniqueConstraint = "workspace_proxies_region_id_unique"                       // ALTER TABLE ONLY workspace_proxies ADD CONSTRAINT workspace_proxies_region_id_unique UNIQUE (region_id);
+	UniqueWorkspaceProxyHealthPkey                          UniqueConstraint = "workspace_proxy_health_pkey"                              // ALTER TABLE ONLY workspace_proxy_health ADD CONSTRAINT workspace_proxy_health_pkey PRIMARY KEY (proxy_id);
+	UniqueWorkspaceResourceCostsPkey                        UniqueConstraint = "workspace_resource_costs_pkey"                            // ALTER TABLE ONLY workspace_resource_costs ADD CONSTRAINT workspace_resource_costs_pkey PRIMARY KEY (workspace_id, date, resource_type, resource_name);
 	UniqueWorkspaceResourceMetadataName                     UniqueConstraint = "workspace_resource_metadata_name"                         // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_name UNIQUE (workspace_resource_id, key);
 	UniqueWorkspaceResourceMetadataPkey                     UniqueConstraint = "workspace_resource_metadata_pkey"                         // ALTER TABLE ONLY workspace_resource_metadata ADD CONSTRAINT workspace_resource_metadata_pkey PRIMARY KEY (id);
 	UniqueWorkspaceResourcesPkey                            UniqueConstraint = "workspace_resources_pkey"                                 // ALTER TABLE ONLY workspace_resources ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSnapshotsPkey                            UniqueConstraint = "workspace_snapshots_pkey"                                 // ALTER TABLE ONLY workspace_snapshots ADD CONSTRAINT workspace_snapshots_pkey PRIMARY KEY (id);
+	UniqueWorkspaceSnapshotsWorkspaceIDNameKey              UniqueConstraint = "workspace_snapshots_workspace_id_name_key"                // ALTER TABLE ONLY workspace_snapshots ADD CONSTRAINT workspace_snapshots_workspace_id_name_key UNIQUE (workspace_id, name);
 	UniqueWorkspacesPkey                                    UniqueConstraint = "workspaces_pkey"                                          // ALTER TABLE ONLY workspaces ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 	UniqueIndexAPIKeyName                                   UniqueConstraint = "idx_api_key_name"                                         // CREATE UNIQUE INDEX idx_api_key_name ON api_keys USING btree (user_id, token_name) WHERE (login_type = 'token'::login_type);
 	UniqueIndexOrganizationName                             UniqueConstraint = "idx_organization_name"                                    // CREATE UNIQUE INDEX idx_organization_name ON organizations USING btree (name);
@@ -72,6 +117,8 @@ const (
 	UniqueIndexProvisionerDaemonsNameOwnerKey               UniqueConstraint = "idx_provisioner_daemons_name_owner_key"                   // CREATE UNIQUE INDEX idx_provisioner_daemons_name_owner_key ON provisioner_daemons USING btree (name, lower(COALESCE((tags ->> 'owner'::text), ''::text)));
 	UniqueIndexUsersEmail                                   UniqueConstraint = "idx_users_email"                                          // CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted = false);
 	UniqueIndexUsersUsername                                UniqueConstraint = "idx_users_username"                                       // CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
+	UniqueProvisionerKeysHashedSecretIndex                  UniqueConstraint = "provisioner_keys_hashed_secret_idx"                       // CREATE UNIQUE INDEX provisioner_keys_hashed_secret_idx ON provisioner_keys USING btree (hashed_secret);
+	UniqueProvisionerKeysOrganizationIDNameIndex            UniqueConstraint = "provisioner_keys_organization_id_name_idx"                // CREATE UNIQUE INDEX provisioner_keys_organization_id_name_idx ON provisioner_keys USING btree (organization_id, lower((name)::text));
 	UniqueTemplatesOrganizationIDNameIndex                  UniqueConstraint = "templates_organization_id_name_idx"                       // CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 	UniqueUsersEmailLowerIndex                              UniqueConstraint = "users_email_lower_idx"                                    // CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 	UniqueUsersUsernameLowerIndex                           UniqueConstraint = "users_username_lower_idx"                                 // CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
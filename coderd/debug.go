@@ -46,6 +46,37 @@ func (api *API) debugTailnet(rw http.ResponseWriter, r *http.Request) {
 	api.agentProvider.ServeHTTPDebug(rw, r)
 }
 
+// @Summary Rotate tailnet node keys
+// @ID rotate-tailnet-node-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 202 {object} codersdk.Response
+// @Router /debug/tailnet/rotate-keys [post]
+func (api *API) debugRotateNodeKeys(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceDeploymentValues) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Insufficient permissions to rotate node keys.",
+		})
+		return
+	}
+
+	err := api.nodeKeyRotator.forceRotation()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to rotate node keys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.Response{
+		Message: "Connected agents and clients were asked to rotate their node keys.",
+	})
+}
+
 // @Summary Debug Info Deployment Health
 // @ID debug-info-deployment-health
 // @Security CoderSessionToken
@@ -117,6 +148,8 @@ func formatHealthcheck(ctx context.Context, rw http.ResponseWriter, r *http.Requ
 			hc.DERP.Dismissed = true
 		case codersdk.HealthSectionDatabase:
 			hc.Database.Dismissed = true
+		case codersdk.HealthSectionPubsub:
+			hc.Pubsub.Dismissed = true
 		case codersdk.HealthSectionWebsocket:
 			hc.Websocket.Dismissed = true
 		case codersdk.HealthSectionWorkspaceProxy:
@@ -136,6 +169,7 @@ func formatHealthcheck(ctx context.Context, rw http.ResponseWriter, r *http.Requ
 		_, _ = fmt.Fprintln(rw, "access_url:", hc.AccessURL.Healthy)
 		_, _ = fmt.Fprintln(rw, "websocket:", hc.Websocket.Healthy)
 		_, _ = fmt.Fprintln(rw, "database:", hc.Database.Healthy)
+		_, _ = fmt.Fprintln(rw, "pubsub:", hc.Pubsub.Healthy)
 
 	case "", "json":
 		httpapi.WriteIndent(ctx, rw, http.StatusOK, hc)
@@ -275,6 +309,70 @@ func validateHealthSettings(settings codersdk.HealthSettings) error {
 	return nil
 }
 
+// @Summary Get group sync runs
+// @ID get-group-sync-runs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Param user_id query string false "Only return the login runs of the user" format(uuid)
+// @Param limit query int false "Maximum number of runs to return, defaults to 50"
+// @Success 200 {array} codersdk.GroupSyncRun
+// @Router /debug/group-sync-runs [get]
+func (api *API) debugGroupSyncRuns(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	userID := p.UUID(vals, uuid.Nil, "user_id")
+	limit := p.Int(vals, 50, "limit")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) == 0 && (limit < 1 || limit > 1000) {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: "Must be between 1 and 1000.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	rows, err := api.Database.GetGroupSyncRuns(ctx, database.GetGroupSyncRunsParams{
+		UserID:     userID,
+		LimitCount: int32(limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching group sync runs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	runs := make([]codersdk.GroupSyncRun, 0, len(rows))
+	for _, row := range rows {
+		run := codersdk.GroupSyncRun{
+			ID:          row.ID,
+			Trigger:     codersdk.GroupSyncTrigger(row.Trigger),
+			StartedAt:   row.StartedAt,
+			FinishedAt:  row.FinishedAt,
+			UsersSynced: row.UsersSynced,
+			UsersFailed: row.UsersFailed,
+			IDPGroups:   row.IDPGroups,
+			Groups:      row.Groups,
+			Error:       row.Error,
+		}
+		if row.UserID.Valid {
+			id := row.UserID.UUID
+			run.UserID = &id
+		}
+		runs = append(runs, run)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, runs)
+}
+
 // For some reason the swagger docs need to be attached to a function.
 //
 // @Summary Debug Info Websocket Test
@@ -9,12 +9,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/groupsync"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
 	"github.com/coder/coder/v2/codersdk"
@@ -236,6 +240,7 @@ func TestDebugHealth(t *testing.T) {
 		assert.Contains(t, resStr, "access_url: false")
 		assert.Contains(t, resStr, "websocket: false")
 		assert.Contains(t, resStr, "database: false")
+		assert.Contains(t, resStr, "pubsub: false")
 	})
 }
 
@@ -363,6 +368,85 @@ func TestHealthSettings(t *testing.T) {
 	})
 }
 
+func TestDebugRotateNodeKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		res, err := client.Request(ctx, http.MethodPost, "/api/v2/debug/tailnet/rotate-keys", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusAccepted, res.StatusCode)
+	})
+
+	t.Run("Member", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		res, err := memberClient.Request(ctx, http.MethodPost, "/api/v2/debug/tailnet/rotate-keys", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		// Debug endpoints are hidden from users who can't read debug info.
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestDebugGroupSyncRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	logger := slogtest.Make(t, nil)
+	groupsync.RecordRun(ctx, logger, db, database.InsertGroupSyncRunParams{
+		Trigger:     database.GroupSyncTriggerBackground,
+		StartedAt:   dbtime.Now().Add(-time.Minute),
+		UsersSynced: 2,
+	})
+	groupsync.RecordRun(ctx, logger, db, database.InsertGroupSyncRunParams{
+		Trigger:     database.GroupSyncTriggerLogin,
+		UserID:      uuid.NullUUID{UUID: member.ID, Valid: true},
+		StartedAt:   dbtime.Now(),
+		UsersSynced: 1,
+		IDPGroups:   []string{"frontend"},
+		Groups:      []string{"engineering", "frontend"},
+	})
+
+	runs, err := client.GroupSyncRuns(ctx, codersdk.GroupSyncRunsRequest{})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, codersdk.GroupSyncTriggerLogin, runs[0].Trigger)
+	require.Equal(t, codersdk.GroupSyncTriggerBackground, runs[1].Trigger)
+	require.Nil(t, runs[1].UserID)
+
+	runs, err = client.GroupSyncRuns(ctx, codersdk.GroupSyncRunsRequest{UserID: member.ID})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, member.ID, *runs[0].UserID)
+	require.Equal(t, []string{"frontend"}, runs[0].IDPGroups)
+	require.Equal(t, []string{"engineering", "frontend"}, runs[0].Groups)
+
+	runs, err = client.GroupSyncRuns(ctx, codersdk.GroupSyncRunsRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	_, err = memberClient.GroupSyncRuns(ctx, codersdk.GroupSyncRunsRequest{})
+	var sdkErr *codersdk.Error
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+}
+
 func TestDebugWebsocket(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,150 @@
+package groupsync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	// usersPageSize is how many users are synced per page of users.
+	usersPageSize = 100
+	// maxRunErrors is how many user errors are kept on a background run.
+	maxRunErrors = 10
+)
+
+// SetGroupsFunc replaces the groups of a user with the named groups, see
+// coderd.Options.SetUserGroups.
+type SetGroupsFunc func(ctx context.Context, logger slog.Logger, db database.Store, userID uuid.UUID, groupNames []string, createMissingGroups bool) error
+
+// BackgroundOptions configures SyncInBackground.
+type BackgroundOptions struct {
+	// Config must have a Directory, which the groups of users are looked up
+	// in.
+	Config   Config
+	Interval time.Duration
+	// CreateMissingGroups creates the Coder groups that don't exist yet.
+	CreateMissingGroups bool
+	SetGroups           SetGroupsFunc
+}
+
+// SyncInBackground syncs the groups of every OIDC user from the Directory at
+// the given interval, so changes in the identity provider are applied without
+// waiting for users to log in again. Suspended users are skipped. Each pass is
+// recorded as a sync run. The returned function stops syncing.
+func SyncInBackground(ctx context.Context, logger slog.Logger, db database.Store, opts BackgroundOptions) func() {
+	logger = logger.Named("group_sync")
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system syncs groups without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+	ticker := time.NewTicker(opts.Interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			run := syncAll(ctx, logger, db, opts)
+			if ctx.Err() != nil {
+				return
+			}
+			RecordRun(ctx, logger, db, run)
+			logger.Debug(ctx, "synced groups of oidc users",
+				slog.F("users_synced", run.UsersSynced),
+				slog.F("users_failed", run.UsersFailed),
+			)
+		}
+	}()
+
+	return func() {
+		cancelFunc()
+		<-done
+	}
+}
+
+func syncAll(ctx context.Context, logger slog.Logger, db database.Store, opts BackgroundOptions) database.InsertGroupSyncRunParams {
+	run := database.InsertGroupSyncRunParams{
+		Trigger:   database.GroupSyncTriggerBackground,
+		StartedAt: dbtime.Now(),
+	}
+	var errs []string
+	fail := func(err error) {
+		run.UsersFailed++
+		if len(errs) < maxRunErrors {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	afterID := uuid.Nil
+	for {
+		users, err := db.GetUsers(ctx, database.GetUsersParams{
+			AfterID:  afterID,
+			Status:   []database.UserStatus{database.UserStatusActive, database.UserStatusDormant},
+			LimitOpt: usersPageSize,
+		})
+		if err != nil {
+			errs = append(errs, xerrors.Errorf("get users: %w", err).Error())
+			break
+		}
+		for _, user := range users {
+			if user.LoginType != database.LoginTypeOIDC {
+				continue
+			}
+			err := syncUser(ctx, logger, db, opts, user.ID, user.Email)
+			if err != nil {
+				if ctx.Err() != nil {
+					return run
+				}
+				logger.Warn(ctx, "failed to sync groups of user",
+					slog.F("user_id", user.ID),
+					slog.Error(err),
+				)
+				fail(xerrors.Errorf("user %s: %w", user.ID, err))
+				continue
+			}
+			run.UsersSynced++
+		}
+		if len(users) < usersPageSize {
+			break
+		}
+		afterID = users[len(users)-1].ID
+	}
+
+	if run.UsersFailed > maxRunErrors {
+		errs = append(errs, fmt.Sprintf("and %d more", int(run.UsersFailed)-maxRunErrors))
+	}
+	run.Error = strings.Join(errs, "\n")
+	return run
+}
+
+func syncUser(ctx context.Context, logger slog.Logger, db database.Store, opts BackgroundOptions, userID uuid.UUID, email string) error {
+	groups, err := opts.Config.Directory.UserGroups(ctx, email)
+	if err != nil {
+		return xerrors.Errorf("get groups: %w", err)
+	}
+	resolved, err := opts.Config.Resolve(ctx, groups)
+	if err != nil {
+		return xerrors.Errorf("resolve groups: %w", err)
+	}
+	err = opts.SetGroups(ctx, logger, db, userID, resolved, opts.CreateMissingGroups)
+	if err != nil {
+		return xerrors.Errorf("set groups: %w", err)
+	}
+	return nil
+}
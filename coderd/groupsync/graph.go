@@ -0,0 +1,148 @@
+package groupsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/xerrors"
+)
+
+const defaultGraphURL = "https://graph.microsoft.com"
+
+// GraphOptions configures a GraphDirectory.
+type GraphOptions struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// BaseURL is the Microsoft Graph API. Defaults to
+	// https://graph.microsoft.com.
+	BaseURL string
+	// TokenURL is where access tokens are requested. Defaults to the token
+	// endpoint of the tenant.
+	TokenURL string
+	// HTTPClient is used for token and API requests.
+	HTTPClient *http.Client
+}
+
+// GraphDirectory looks up groups in Microsoft Entra ID with the Microsoft
+// Graph API. It authenticates as an application with the client credentials
+// flow, so the application must be granted the GroupMember.Read.All
+// permission.
+type GraphDirectory struct {
+	client  *http.Client
+	baseURL string
+}
+
+var _ Directory = &GraphDirectory{}
+
+// NewGraphDirectory returns a Directory backed by Microsoft Graph. Access
+// tokens are requested with ctx, so it should live as long as the directory.
+func NewGraphDirectory(ctx context.Context, opts GraphOptions) *GraphDirectory {
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultGraphURL
+	}
+	tokenURL := opts.TokenURL
+	if tokenURL == "" {
+		tokenURL = fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(opts.TenantID))
+	}
+	if opts.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, opts.HTTPClient)
+	}
+	cfg := &clientcredentials.Config{
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       []string{defaultGraphURL + "/.default"},
+	}
+	return &GraphDirectory{
+		client:  cfg.Client(ctx),
+		baseURL: baseURL,
+	}
+}
+
+// UserGroups returns the display names of the groups the user is a direct
+// member of. The user is looked up by their user principal name, which is
+// usually their email.
+func (d *GraphDirectory) UserGroups(ctx context.Context, email string) ([]string, error) {
+	return d.listGroups(ctx, fmt.Sprintf("%s/v1.0/users/%s/memberOf/microsoft.graph.group?$select=displayName,id", d.baseURL, url.PathEscape(email)))
+}
+
+// ParentGroups returns the display names of the groups the named group is a
+// direct member of. If several groups have the name, the parents of each of
+// them are returned.
+func (d *GraphDirectory) ParentGroups(ctx context.Context, group string) ([]string, error) {
+	query := url.Values{}
+	query.Set("$filter", fmt.Sprintf("displayName eq '%s'", strings.ReplaceAll(group, "'", "''")))
+	query.Set("$select", "displayName,id")
+	matches, err := d.list(ctx, fmt.Sprintf("%s/v1.0/groups?%s", d.baseURL, query.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []string
+	for _, match := range matches {
+		names, err := d.listGroups(ctx, fmt.Sprintf("%s/v1.0/groups/%s/memberOf/microsoft.graph.group?$select=displayName,id", d.baseURL, url.PathEscape(match.ID)))
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, names...)
+	}
+	return parents, nil
+}
+
+type graphGroup struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+func (d *GraphDirectory) listGroups(ctx context.Context, u string) ([]string, error) {
+	groups, err := d.list(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.DisplayName)
+	}
+	return names, nil
+}
+
+// list fetches every page of a collection of groups.
+func (d *GraphDirectory) list(ctx context.Context, u string) ([]graphGroup, error) {
+	var groups []graphGroup
+	for u != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, xerrors.Errorf("create request: %w", err)
+		}
+		res, err := d.client.Do(req)
+		if err != nil {
+			return nil, xerrors.Errorf("request graph: %w", err)
+		}
+		if res.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+			_ = res.Body.Close()
+			return nil, xerrors.Errorf("graph responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+		}
+		var page struct {
+			Value    []graphGroup `json:"value"`
+			NextLink string       `json:"@odata.nextLink"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&page)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("decode graph response: %w", err)
+		}
+		groups = append(groups, page.Value...)
+		u = page.NextLink
+	}
+	return groups, nil
+}
@@ -0,0 +1,87 @@
+package groupsync_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/groupsync"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGraphDirectory(t *testing.T) {
+	t.Parallel()
+
+	type group struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	}
+	writePage := func(rw http.ResponseWriter, next string, groups ...group) {
+		page := map[string]any{"value": groups}
+		if next != "" {
+			page["@odata.nextLink"] = next
+		}
+		_ = json.NewEncoder(rw).Encode(page)
+	}
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+			rw.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(rw).Encode(map[string]any{
+				"access_token": "token",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1.0/users/alice@coder.com/memberOf/microsoft.graph.group":
+			if r.URL.Query().Get("page") == "2" {
+				writePage(rw, "", group{ID: "2", DisplayName: "design"})
+				return
+			}
+			writePage(rw, srv.URL+r.URL.Path+"?page=2", group{ID: "1", DisplayName: "frontend"})
+		case "/v1.0/groups":
+			assert.Equal(t, "displayName eq 'frontend'", r.URL.Query().Get("$filter"))
+			writePage(rw, "", group{ID: "1", DisplayName: "frontend"})
+		case "/v1.0/groups/1/memberOf/microsoft.graph.group":
+			writePage(rw, "", group{ID: "3", DisplayName: "engineering"})
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"error":{"code":"Request_ResourceNotFound"}}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	dir := groupsync.NewGraphDirectory(ctx, groupsync.GraphOptions{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		BaseURL:      srv.URL,
+		TokenURL:     srv.URL + "/token",
+		HTTPClient:   srv.Client(),
+	})
+
+	groups, err := dir.UserGroups(ctx, "alice@coder.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"frontend", "design"}, groups)
+
+	parents, err := dir.ParentGroups(ctx, "frontend")
+	require.NoError(t, err)
+	require.Equal(t, []string{"engineering"}, parents)
+
+	_, err = dir.UserGroups(ctx, "bob@coder.com")
+	require.ErrorContains(t, err, "Request_ResourceNotFound")
+}
@@ -0,0 +1,178 @@
+// Package groupsync maps the groups a user belongs to in their identity
+// provider to Coder groups. Groups nested in other groups can be resolved with
+// a Directory, so members of a nested group are also synced to the groups it
+// belongs to.
+package groupsync
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// DefaultMaxDepth is how many levels of nested groups are resolved if no
+// depth is configured.
+const DefaultMaxDepth = 5
+
+// Directory looks up group memberships in the identity provider.
+type Directory interface {
+	// UserGroups returns the names of the groups the user with the given
+	// email is a direct member of.
+	UserGroups(ctx context.Context, email string) ([]string, error)
+	// ParentGroups returns the names of the groups the given group is a
+	// direct member of.
+	ParentGroups(ctx context.Context, group string) ([]string, error)
+}
+
+// Config controls how the groups returned by the identity provider are mapped
+// to Coder groups.
+type Config struct {
+	// Directory resolves nested groups. If nil, groups are used as the
+	// identity provider returns them.
+	Directory Directory
+	// MaxDepth is how many levels of nested groups are resolved. Defaults to
+	// DefaultMaxDepth.
+	MaxDepth int
+	// Mapping renames groups from the identity provider.
+	// map[idpGroupName]coderGroupName
+	Mapping map[string]string
+	// RegexFilter drops any group, after the mapping is applied, that
+	// doesn't match.
+	RegexFilter *regexp.Regexp
+	// StripPrefixes are removed from the start of group names after the
+	// filter is applied. Only the first matching prefix is removed.
+	StripPrefixes []string
+}
+
+// Flatten returns the given groups along with every group they are nested
+// in, up to MaxDepth levels. Cycles between groups are ignored. Without a
+// Directory, the groups are returned unchanged.
+func (c Config) Flatten(ctx context.Context, groups []string) ([]string, error) {
+	if c.Directory == nil {
+		return groups, nil
+	}
+	maxDepth := c.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	seen := make(map[string]struct{}, len(groups))
+	flattened := make([]string, 0, len(groups))
+	level := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if _, ok := seen[group]; ok {
+			continue
+		}
+		seen[group] = struct{}{}
+		flattened = append(flattened, group)
+		level = append(level, group)
+	}
+
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, group := range level {
+			parents, err := c.Directory.ParentGroups(ctx, group)
+			if err != nil {
+				return nil, xerrors.Errorf("get parent groups of %q: %w", group, err)
+			}
+			for _, parent := range parents {
+				if _, ok := seen[parent]; ok {
+					continue
+				}
+				seen[parent] = struct{}{}
+				flattened = append(flattened, parent)
+				next = append(next, parent)
+			}
+		}
+		level = next
+	}
+	return flattened, nil
+}
+
+// Map renames groups with the Mapping. Groups without a mapping keep their
+// name.
+func (c Config) Map(groups []string) []string {
+	mapped := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if to, ok := c.Mapping[group]; ok {
+			group = to
+		}
+		mapped = append(mapped, group)
+	}
+	return mapped
+}
+
+// Filter drops the groups that don't match the RegexFilter and strips the
+// prefixes from the rest. Groups that are empty once their prefix is removed
+// are dropped, as are duplicates.
+func (c Config) Filter(groups []string) []string {
+	seen := make(map[string]struct{}, len(groups))
+	filtered := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if c.RegexFilter != nil && !c.RegexFilter.MatchString(group) {
+			continue
+		}
+		for _, prefix := range c.StripPrefixes {
+			if prefix != "" && strings.HasPrefix(group, prefix) {
+				group = strings.TrimPrefix(group, prefix)
+				break
+			}
+		}
+		if group == "" {
+			continue
+		}
+		if _, ok := seen[group]; ok {
+			continue
+		}
+		seen[group] = struct{}{}
+		filtered = append(filtered, group)
+	}
+	return filtered
+}
+
+// Resolve returns the Coder groups for the given identity provider groups,
+// sorted by name. Nested groups are resolved first, then the mapping, filter
+// and prefix stripping are applied.
+func (c Config) Resolve(ctx context.Context, groups []string) ([]string, error) {
+	flattened, err := c.Flatten(ctx, groups)
+	if err != nil {
+		return nil, err
+	}
+	resolved := c.Filter(c.Map(flattened))
+	sort.Strings(resolved)
+	return resolved, nil
+}
+
+// RecordRun stores a sync run, so it can be inspected on the debug routes.
+// The run finishes now. Failing to store the run is logged, but doesn't fail
+// the sync.
+func RecordRun(ctx context.Context, logger slog.Logger, db database.Store, run database.InsertGroupSyncRunParams) {
+	if run.ID == uuid.Nil {
+		run.ID = uuid.New()
+	}
+	run.FinishedAt = dbtime.Now()
+	if run.IDPGroups == nil {
+		run.IDPGroups = []string{}
+	}
+	if run.Groups == nil {
+		run.Groups = []string{}
+	}
+	//nolint:gocritic // Sync runs are recorded by the system.
+	_, err := db.InsertGroupSyncRun(dbauthz.AsSystemRestricted(ctx), run)
+	if err != nil {
+		logger.Warn(ctx, "failed to record group sync run",
+			slog.F("trigger", run.Trigger),
+			slog.Error(err),
+		)
+	}
+}
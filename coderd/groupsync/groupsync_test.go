@@ -0,0 +1,147 @@
+package groupsync_test
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/groupsync"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// fakeDirectory maps users and groups to the groups they are direct members
+// of.
+type fakeDirectory struct {
+	users   map[string][]string
+	parents map[string][]string
+}
+
+func (d fakeDirectory) UserGroups(_ context.Context, email string) ([]string, error) {
+	groups, ok := d.users[email]
+	if !ok {
+		return nil, xerrors.Errorf("user %q not found", email)
+	}
+	return groups, nil
+}
+
+func (d fakeDirectory) ParentGroups(_ context.Context, group string) ([]string, error) {
+	return d.parents[group], nil
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	dir := fakeDirectory{
+		parents: map[string][]string{
+			"frontend":       {"engineering"},
+			"engineering":    {"coder-everyone", "staff"},
+			"staff":          {"engineering"}, // A cycle.
+			"coder-everyone": {"level-4"},
+			"level-4":        {"level-5"},
+		},
+	}
+
+	t.Run("NoDirectory", func(t *testing.T) {
+		t.Parallel()
+
+		groups, err := groupsync.Config{}.Resolve(context.Background(), []string{"frontend", "design"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"design", "frontend"}, groups)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		t.Parallel()
+
+		groups, err := groupsync.Config{Directory: dir}.Resolve(context.Background(), []string{"frontend"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"coder-everyone", "engineering", "frontend", "level-4", "level-5", "staff"}, groups)
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		t.Parallel()
+
+		groups, err := groupsync.Config{Directory: dir, MaxDepth: 1}.Resolve(context.Background(), []string{"frontend"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"engineering", "frontend"}, groups)
+	})
+
+	t.Run("MapFilterStrip", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := groupsync.Config{
+			Directory:     dir,
+			Mapping:       map[string]string{"frontend": "coder-frontend"},
+			RegexFilter:   regexp.MustCompile("^coder-"),
+			StripPrefixes: []string{"coder-"},
+		}
+		groups, err := cfg.Resolve(context.Background(), []string{"frontend", "coder-"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"everyone", "frontend"}, groups)
+	})
+}
+
+func TestSyncInBackground(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	synced := dbgen.User(t, db, database.User{LoginType: database.LoginTypeOIDC})
+	missing := dbgen.User(t, db, database.User{LoginType: database.LoginTypeOIDC})
+	_ = dbgen.User(t, db, database.User{LoginType: database.LoginTypeOIDC, Status: database.UserStatusSuspended})
+	_ = dbgen.User(t, db, database.User{LoginType: database.LoginTypePassword})
+
+	dir := fakeDirectory{
+		users: map[string][]string{
+			synced.Email: {"frontend"},
+		},
+		parents: map[string][]string{
+			"frontend": {"engineering"},
+		},
+	}
+
+	var mu sync.Mutex
+	set := make(map[uuid.UUID][]string)
+	ctx := testutil.Context(t, testutil.WaitShort)
+	stop := groupsync.SyncInBackground(ctx, slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), db, groupsync.BackgroundOptions{
+		Config:   groupsync.Config{Directory: dir},
+		Interval: time.Millisecond,
+		SetGroups: func(_ context.Context, _ slog.Logger, _ database.Store, userID uuid.UUID, groupNames []string, _ bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			set[userID] = groupNames
+			return nil
+		},
+	})
+	t.Cleanup(stop)
+
+	var runs []database.GroupSyncRun
+	require.Eventually(t, func() bool {
+		var err error
+		runs, err = db.GetGroupSyncRuns(ctx, database.GetGroupSyncRunsParams{LimitCount: 1})
+		return err == nil && len(runs) > 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+	stop()
+
+	run := runs[0]
+	require.Equal(t, database.GroupSyncTriggerBackground, run.Trigger)
+	require.False(t, run.UserID.Valid)
+	require.EqualValues(t, 1, run.UsersSynced)
+	require.EqualValues(t, 1, run.UsersFailed)
+	require.Contains(t, run.Error, missing.ID.String())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, set, 1)
+	require.Equal(t, []string{"engineering", "frontend"}, set[synced.ID])
+}
@@ -31,6 +31,7 @@ const (
 	warningNodeUsesWebsocket = `Node uses WebSockets because the "Upgrade: DERP" header may be blocked on the load balancer.`
 	oneNodeUnhealthy         = "Region is operational, but performance might be degraded as one node is unhealthy."
 	missingNodeReport        = "Missing node health report, probably a developer error."
+	nodeExcluded             = "Node %q in region %d failed %d consecutive health checks and is excluded from the DERP map until it recovers: %s"
 )
 
 // @typescript-generate Report
@@ -47,6 +48,10 @@ type Report struct {
 	NetcheckErr  *string          `json:"netcheck_err"`
 	NetcheckLogs []string         `json:"netcheck_logs"`
 
+	// FailingNodes are the nodes that failed the continuous health checks run
+	// by coderd and the workspace proxies.
+	FailingNodes []FailingNode `json:"failing_nodes"`
+
 	Error *string `json:"error"`
 }
 
@@ -68,6 +73,8 @@ type RegionReport struct {
 type NodeReport struct {
 	mu            sync.Mutex
 	clientCounter int
+	// forceWebSockets skips the "Upgrade: derp" attempt when connecting.
+	forceWebSockets bool
 
 	// Healthy is deprecated and left for backward compatibility purposes, use `Severity` instead.
 	Healthy  bool             `json:"healthy"`
@@ -99,6 +106,9 @@ type ReportOptions struct {
 	Dismissed bool
 
 	DERPMap *tailcfg.DERPMap
+	// Monitor reports the nodes failing the continuous health checks, if they
+	// are enabled.
+	Monitor *Monitor
 }
 
 func (r *Report) Run(ctx context.Context, opts *ReportOptions) {
@@ -108,6 +118,7 @@ func (r *Report) Run(ctx context.Context, opts *ReportOptions) {
 	r.Dismissed = opts.Dismissed
 
 	r.Regions = map[int]*RegionReport{}
+	r.FailingNodes = []FailingNode{}
 
 	wg := &sync.WaitGroup{}
 	mu := sync.Mutex{}
@@ -162,6 +173,19 @@ func (r *Report) Run(ctx context.Context, opts *ReportOptions) {
 			r.Severity = regionReport.Severity
 		}
 	}
+
+	if opts.Monitor != nil {
+		r.FailingNodes = opts.Monitor.FailingNodes()
+		for _, node := range r.FailingNodes {
+			if !node.Excluded {
+				continue
+			}
+			r.Warnings = append(r.Warnings, health.Messagef(health.CodeDERPNodeExcluded, nodeExcluded, node.NodeName, node.RegionID, node.ConsecutiveFailures, node.Error))
+			if r.Severity == health.SeverityOK {
+				r.Severity = health.SeverityWarning
+			}
+		}
+	}
 }
 
 func (r *RegionReport) Run(ctx context.Context) {
@@ -491,6 +515,7 @@ func (r *NodeReport) derpClient(ctx context.Context, derpURL *url.URL) (*derphtt
 		r.writeClientErr(id, err)
 		return nil, id, err
 	}
+	client.ForceWebsockets = r.forceWebSockets
 
 	go func() {
 		<-ctx.Done()
@@ -0,0 +1,342 @@
+package derphealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/coderd/util/slice"
+)
+
+const (
+	// SourceCoderd is the source of the failing nodes probed by coderd.
+	SourceCoderd = "coderd"
+
+	// remoteReportTTL is how long the failing nodes reported by a workspace
+	// proxy are kept. Proxies report on every registration, so a report older
+	// than this is from a proxy that has stopped.
+	remoteReportTTL = 2 * time.Minute
+)
+
+// @typescript-generate FailingNode
+type FailingNode struct {
+	// Source is where the node was probed from: "coderd", or the name of the
+	// workspace proxy that reported it.
+	Source              string    `json:"source"`
+	RegionID            int       `json:"region_id"`
+	NodeName            string    `json:"node_name"`
+	HostName            string    `json:"host_name"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailingSince        time.Time `json:"failing_since" format:"date-time"`
+	Error               string    `json:"error"`
+	// Excluded is true if the node is excluded from the DERP map until it
+	// recovers.
+	Excluded bool `json:"excluded"`
+}
+
+type MonitorOptions struct {
+	Logger slog.Logger
+	// DERPMap returns the DERP map to probe. It is called before every round
+	// of probes, so nodes that are added or removed are picked up.
+	DERPMap func() *tailcfg.DERPMap
+	// Interval between rounds of probes.
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failed probes after which
+	// a node is excluded. Defaults to 3.
+	FailureThreshold int
+	// Probe checks a single node. Defaults to the node health check.
+	Probe func(ctx context.Context, node *tailcfg.DERPNode) error
+	// ForceWebSockets makes the default probe connect to nodes over
+	// WebSockets, like clients do when the deployment forces them.
+	ForceWebSockets bool
+}
+
+// Monitor continuously probes every node of a DERP map. Nodes that fail
+// consecutive probes are excluded from the DERP map returned by Filter until a
+// probe succeeds again, so clients and agents stop connecting through them.
+type Monitor struct {
+	opts   MonitorOptions
+	cancel context.CancelFunc
+	closed chan struct{}
+
+	mu     sync.Mutex
+	nodes  map[string]*nodeState
+	remote map[string]remoteReport
+}
+
+type nodeState struct {
+	regionID     int
+	hostName     string
+	failures     int
+	failingSince time.Time
+	err          string
+}
+
+type remoteReport struct {
+	nodes      []FailingNode
+	receivedAt time.Time
+}
+
+// NewMonitor starts probing the nodes of the DERP map. Close must be called to
+// stop it.
+func NewMonitor(opts MonitorOptions) *Monitor {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 3
+	}
+	if opts.Probe == nil {
+		forceWebSockets := opts.ForceWebSockets
+		opts.Probe = func(ctx context.Context, node *tailcfg.DERPNode) error {
+			return probeNode(ctx, node, forceWebSockets)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Monitor{
+		opts:   opts,
+		cancel: cancel,
+		closed: make(chan struct{}),
+		nodes:  map[string]*nodeState{},
+		remote: map[string]remoteReport{},
+	}
+	go m.run(ctx)
+	return m
+}
+
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.closed)
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		m.probe(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) probe(ctx context.Context) {
+	derpMap := m.opts.DERPMap()
+	if derpMap == nil {
+		return
+	}
+
+	type result struct {
+		regionID int
+		node     *tailcfg.DERPNode
+		err      error
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []result
+	)
+	for _, region := range derpMap.Regions {
+		for _, node := range region.Nodes {
+			regionID, node := region.RegionID, node
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := m.opts.Probe(ctx, node)
+				mu.Lock()
+				results = append(results, result{regionID: regionID, node: node, err: err})
+				mu.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+	// Probes cut short by shutdown say nothing about the nodes.
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]struct{}, len(results))
+	for _, res := range results {
+		seen[res.node.Name] = struct{}{}
+		state, ok := m.nodes[res.node.Name]
+		if !ok {
+			state = &nodeState{}
+			m.nodes[res.node.Name] = state
+		}
+		state.regionID = res.regionID
+		state.hostName = res.node.HostName
+
+		logger := m.opts.Logger.With(
+			slog.F("region_id", res.regionID),
+			slog.F("node_name", res.node.Name),
+			slog.F("host_name", res.node.HostName),
+		)
+		if res.err == nil {
+			if state.failures >= m.opts.FailureThreshold {
+				logger.Info(ctx, "DERP node recovered, adding it back to the DERP map")
+			}
+			*state = nodeState{regionID: res.regionID, hostName: res.node.HostName}
+			continue
+		}
+		if state.failures == 0 {
+			state.failingSince = time.Now()
+		}
+		state.failures++
+		state.err = res.err.Error()
+		if state.failures == m.opts.FailureThreshold {
+			logger.Warn(ctx, "DERP node failed consecutive health checks, excluding it from the DERP map",
+				slog.F("failures", state.failures),
+				slog.Error(res.err),
+			)
+		}
+	}
+	// Forget nodes that were removed from the DERP map.
+	for name := range m.nodes {
+		if _, ok := seen[name]; !ok {
+			delete(m.nodes, name)
+		}
+	}
+}
+
+// Filter returns a copy of derpMap without the excluded nodes. Regions left
+// without nodes are removed. If every node would be excluded, derpMap is
+// returned as is, since clients can't connect at all without DERP.
+func (m *Monitor) Filter(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap {
+	if derpMap == nil {
+		return nil
+	}
+	m.mu.Lock()
+	excluded := map[string]struct{}{}
+	for name, state := range m.nodes {
+		if state.failures >= m.opts.FailureThreshold {
+			excluded[name] = struct{}{}
+		}
+	}
+	m.mu.Unlock()
+	if len(excluded) == 0 {
+		return derpMap
+	}
+
+	filtered := derpMap.Clone()
+	var relays int
+	for id, region := range filtered.Regions {
+		nodes := make([]*tailcfg.DERPNode, 0, len(region.Nodes))
+		for _, node := range region.Nodes {
+			if _, ok := excluded[node.Name]; ok {
+				continue
+			}
+			nodes = append(nodes, node)
+			if !node.STUNOnly {
+				relays++
+			}
+		}
+		if len(nodes) == 0 {
+			delete(filtered.Regions, id)
+			continue
+		}
+		region.Nodes = nodes
+	}
+	if relays == 0 {
+		return derpMap
+	}
+	return filtered
+}
+
+// ReportFailingNodes records the failing nodes seen by a workspace proxy,
+// replacing its previous report. They are surfaced in the health report, but
+// aren't excluded from the DERP map, since they may only be unreachable from
+// the proxy.
+func (m *Monitor) ReportFailingNodes(source string, nodes []FailingNode) {
+	reported := make([]FailingNode, 0, len(nodes))
+	for _, node := range nodes {
+		node.Source = source
+		node.Excluded = false
+		reported = append(reported, node)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remote[source] = remoteReport{
+		nodes:      reported,
+		receivedAt: time.Now(),
+	}
+}
+
+// FailingNodes returns the nodes that failed their last probe, followed by the
+// nodes reported as failing by workspace proxies.
+func (m *Monitor) FailingNodes() []FailingNode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodes := []FailingNode{}
+	for name, state := range m.nodes {
+		if state.failures == 0 {
+			continue
+		}
+		nodes = append(nodes, FailingNode{
+			Source:              SourceCoderd,
+			RegionID:            state.regionID,
+			NodeName:            name,
+			HostName:            state.hostName,
+			ConsecutiveFailures: state.failures,
+			FailingSince:        state.failingSince,
+			Error:               state.err,
+			Excluded:            state.failures >= m.opts.FailureThreshold,
+		})
+	}
+	for source, report := range m.remote {
+		if time.Since(report.receivedAt) > remoteReportTTL {
+			delete(m.remote, source)
+			continue
+		}
+		nodes = append(nodes, report.nodes...)
+	}
+	slices.SortFunc(nodes, func(a, b FailingNode) int {
+		if a.Source != b.Source {
+			// Nodes probed by coderd come first.
+			if a.Source == SourceCoderd {
+				return -1
+			}
+			if b.Source == SourceCoderd {
+				return 1
+			}
+			return slice.Ascending(a.Source, b.Source)
+		}
+		if a.RegionID != b.RegionID {
+			return slice.Ascending(a.RegionID, b.RegionID)
+		}
+		return slice.Ascending(a.NodeName, b.NodeName)
+	})
+	return nodes
+}
+
+// Close stops probing and waits for the running probes to return.
+func (m *Monitor) Close() error {
+	m.cancel()
+	<-m.closed
+	return nil
+}
+
+// probeNode runs the node health check, and returns an error if the node
+// can't relay messages or answer STUN requests.
+func probeNode(ctx context.Context, node *tailcfg.DERPNode, forceWebSockets bool) error {
+	report := NodeReport{Node: node, Healthy: true, forceWebSockets: forceWebSockets}
+	report.Run(ctx)
+	if report.Severity != health.SeverityError {
+		return nil
+	}
+	if report.Error != nil {
+		return xerrors.New(*report.Error)
+	}
+	if report.STUN.Error != nil {
+		return xerrors.New(*report.STUN.Error)
+	}
+	for _, errs := range report.ClientErrs {
+		if len(errs) > 0 {
+			return xerrors.New(errs[len(errs)-1])
+		}
+	}
+	return xerrors.New("could not exchange messages through the node")
+}
@@ -0,0 +1,156 @@
+package derphealth_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+	"tailscale.com/tailcfg"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMonitor(t *testing.T) {
+	t.Parallel()
+
+	derpMap := func() *tailcfg.DERPMap {
+		return &tailcfg.DERPMap{
+			Regions: map[int]*tailcfg.DERPRegion{
+				1: {
+					RegionID: 1,
+					Nodes: []*tailcfg.DERPNode{
+						{Name: "1a", RegionID: 1, HostName: "1a.example.com"},
+						{Name: "1b", RegionID: 1, HostName: "1b.example.com"},
+					},
+				},
+				2: {
+					RegionID: 2,
+					Nodes: []*tailcfg.DERPNode{
+						{Name: "2a", RegionID: 2, HostName: "2a.example.com"},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("ExcludesAndRecovers", func(t *testing.T) {
+		t.Parallel()
+
+		probe := newFakeProbe()
+		probe.fail("1b", "2a")
+		monitor := derphealth.NewMonitor(derphealth.MonitorOptions{
+			Logger:           slogtest.Make(t, nil),
+			DERPMap:          derpMap,
+			Interval:         testutil.IntervalFast,
+			FailureThreshold: 2,
+			Probe:            probe.run,
+		})
+		defer monitor.Close()
+
+		require.Eventually(t, func() bool {
+			return len(monitor.Filter(derpMap()).Regions) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		filtered := monitor.Filter(derpMap())
+		require.Len(t, filtered.Regions[1].Nodes, 1)
+		require.Equal(t, "1a", filtered.Regions[1].Nodes[0].Name)
+
+		failing := monitor.FailingNodes()
+		require.Len(t, failing, 2)
+		for _, node := range failing {
+			require.Equal(t, derphealth.SourceCoderd, node.Source)
+			require.True(t, node.Excluded)
+			require.Equal(t, "probe failed", node.Error)
+			require.GreaterOrEqual(t, node.ConsecutiveFailures, 2)
+		}
+		require.Equal(t, "1b", failing[0].NodeName)
+		require.Equal(t, "2a", failing[1].NodeName)
+
+		probe.fail()
+		require.Eventually(t, func() bool {
+			return len(monitor.FailingNodes()) == 0
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Len(t, monitor.Filter(derpMap()).Regions, 2)
+	})
+
+	t.Run("FailOpen", func(t *testing.T) {
+		t.Parallel()
+
+		probe := newFakeProbe()
+		probe.fail("1a", "1b", "2a")
+		monitor := derphealth.NewMonitor(derphealth.MonitorOptions{
+			Logger:           slogtest.Make(t, nil),
+			DERPMap:          derpMap,
+			Interval:         testutil.IntervalFast,
+			FailureThreshold: 1,
+			Probe:            probe.run,
+		})
+		defer monitor.Close()
+
+		require.Eventually(t, func() bool {
+			return len(monitor.FailingNodes()) == 3
+		}, testutil.WaitShort, testutil.IntervalFast)
+		// Excluding every node would leave clients without any relay.
+		require.Len(t, monitor.Filter(derpMap()).Regions, 2)
+	})
+
+	t.Run("ReportFailingNodes", func(t *testing.T) {
+		t.Parallel()
+
+		monitor := derphealth.NewMonitor(derphealth.MonitorOptions{
+			Logger:   slogtest.Make(t, nil),
+			DERPMap:  derpMap,
+			Interval: time.Hour,
+			Probe:    newFakeProbe().run,
+		})
+		defer monitor.Close()
+
+		monitor.ReportFailingNodes("proxy", []derphealth.FailingNode{{
+			RegionID:            2,
+			NodeName:            "2a",
+			ConsecutiveFailures: 5,
+			Error:               "probe failed",
+			Excluded:            true,
+		}})
+
+		failing := monitor.FailingNodes()
+		require.Len(t, failing, 1)
+		require.Equal(t, "proxy", failing[0].Source)
+		require.Equal(t, "2a", failing[0].NodeName)
+		// Nodes failing from a proxy may be reachable from coderd, so they
+		// aren't excluded.
+		require.False(t, failing[0].Excluded)
+		require.Len(t, monitor.Filter(derpMap()).Regions, 2)
+	})
+}
+
+type fakeProbe struct {
+	mu      sync.Mutex
+	failing map[string]struct{}
+}
+
+func newFakeProbe() *fakeProbe {
+	return &fakeProbe{failing: map[string]struct{}{}}
+}
+
+func (p *fakeProbe) fail(names ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing = map[string]struct{}{}
+	for _, name := range names {
+		p.failing[name] = struct{}{}
+	}
+}
+
+func (p *fakeProbe) run(_ context.Context, node *tailcfg.DERPNode) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.failing[node.Name]; ok {
+		return xerrors.New("probe failed")
+	}
+	return nil
+}
@@ -23,6 +23,11 @@ const (
 	CodeDatabasePingFailed Code = "EDB01"
 	CodeDatabasePingSlow   Code = "EDB02"
 
+	CodePubsubSubscribe   Code = "EPS01"
+	CodePubsubPublish     Code = "EPS02"
+	CodePubsubNotReceived Code = "EPS03"
+	CodePubsubSlow        Code = "EPS04"
+
 	CodeWebsocketDial Code = "EWS01"
 	CodeWebsocketEcho Code = "EWS02"
 	CodeWebsocketMsg  Code = "EWS03"
@@ -34,6 +39,7 @@ const (
 
 	CodeDERPNodeUsesWebsocket Code = `EDERP01`
 	CodeDERPOneNodeUnhealthy  Code = `EDERP02`
+	CodeDERPNodeExcluded      Code = `EDERP03`
 
 	CodeProvisionerDaemonsNoProvisionerDaemons     Code = `EPD01`
 	CodeProvisionerDaemonVersionMismatch           Code = `EPD02`
@@ -17,6 +17,7 @@ type Checker interface {
 	AccessURL(ctx context.Context, opts *AccessURLReportOptions) AccessURLReport
 	Websocket(ctx context.Context, opts *WebsocketReportOptions) WebsocketReport
 	Database(ctx context.Context, opts *DatabaseReportOptions) DatabaseReport
+	Pubsub(ctx context.Context, opts *PubsubReportOptions) PubsubReport
 	WorkspaceProxy(ctx context.Context, opts *WorkspaceProxyReportOptions) WorkspaceProxyReport
 	ProvisionerDaemons(ctx context.Context, opts *ProvisionerDaemonsReportDeps) ProvisionerDaemonsReport
 }
@@ -37,6 +38,7 @@ type Report struct {
 	AccessURL          AccessURLReport          `json:"access_url"`
 	Websocket          WebsocketReport          `json:"websocket"`
 	Database           DatabaseReport           `json:"database"`
+	Pubsub             PubsubReport             `json:"pubsub"`
 	WorkspaceProxy     WorkspaceProxyReport     `json:"workspace_proxy"`
 	ProvisionerDaemons ProvisionerDaemonsReport `json:"provisioner_daemons"`
 
@@ -47,6 +49,7 @@ type Report struct {
 type ReportOptions struct {
 	AccessURL          AccessURLReportOptions
 	Database           DatabaseReportOptions
+	Pubsub             PubsubReportOptions
 	DerpHealth         derphealth.ReportOptions
 	Websocket          WebsocketReportOptions
 	WorkspaceProxy     WorkspaceProxyReportOptions
@@ -81,6 +84,12 @@ func (defaultChecker) Database(ctx context.Context, opts *DatabaseReportOptions)
 	return report
 }
 
+func (defaultChecker) Pubsub(ctx context.Context, opts *PubsubReportOptions) PubsubReport {
+	var report PubsubReport
+	report.Run(ctx, opts)
+	return report
+}
+
 func (defaultChecker) WorkspaceProxy(ctx context.Context, opts *WorkspaceProxyReportOptions) WorkspaceProxyReport {
 	var report WorkspaceProxyReport
 	report.Run(ctx, opts)
@@ -151,6 +160,18 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 		report.Database = opts.Checker.Database(ctx, &opts.Database)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				report.Pubsub.Error = health.Errorf(health.CodeUnknown, "pubsub report panic: %s", err)
+			}
+		}()
+
+		report.Pubsub = opts.Checker.Pubsub(ctx, &opts.Pubsub)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -192,6 +213,9 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 	if report.Database.Severity.Value() > health.SeverityWarning.Value() {
 		report.FailingSections = append(report.FailingSections, codersdk.HealthSectionDatabase)
 	}
+	if report.Pubsub.Severity.Value() > health.SeverityWarning.Value() {
+		report.FailingSections = append(report.FailingSections, codersdk.HealthSectionPubsub)
+	}
 	if report.WorkspaceProxy.Severity.Value() > health.SeverityWarning.Value() {
 		report.FailingSections = append(report.FailingSections, codersdk.HealthSectionWorkspaceProxy)
 	}
@@ -216,6 +240,9 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 	if report.Database.Severity.Value() > report.Severity.Value() {
 		report.Severity = report.Database.Severity
 	}
+	if report.Pubsub.Severity.Value() > report.Severity.Value() {
+		report.Severity = report.Pubsub.Severity
+	}
 	if report.WorkspaceProxy.Severity.Value() > report.Severity.Value() {
 		report.Severity = report.WorkspaceProxy.Severity
 	}
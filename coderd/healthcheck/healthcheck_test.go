@@ -17,6 +17,7 @@ type testChecker struct {
 	AccessURLReport          healthcheck.AccessURLReport
 	WebsocketReport          healthcheck.WebsocketReport
 	DatabaseReport           healthcheck.DatabaseReport
+	PubsubReport             healthcheck.PubsubReport
 	WorkspaceProxyReport     healthcheck.WorkspaceProxyReport
 	ProvisionerDaemonsReport healthcheck.ProvisionerDaemonsReport
 }
@@ -37,6 +38,10 @@ func (c *testChecker) Database(context.Context, *healthcheck.DatabaseReportOptio
 	return c.DatabaseReport
 }
 
+func (c *testChecker) Pubsub(context.Context, *healthcheck.PubsubReportOptions) healthcheck.PubsubReport {
+	return c.PubsubReport
+}
+
 func (c *testChecker) WorkspaceProxy(context.Context, *healthcheck.WorkspaceProxyReportOptions) healthcheck.WorkspaceProxyReport {
 	return c.WorkspaceProxyReport
 }
@@ -73,6 +78,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -103,6 +112,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -134,6 +147,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -164,6 +181,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -194,6 +215,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -224,6 +249,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  false,
 				Severity: health.SeverityError,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -235,6 +264,40 @@ func TestHealthcheck(t *testing.T) {
 		healthy:         false,
 		severity:        health.SeverityError,
 		failingSections: []codersdk.HealthSection{codersdk.HealthSectionDatabase},
+	}, {
+		name: "PubsubFail",
+		checker: &testChecker{
+			DERPReport: derphealth.Report{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
+			AccessURLReport: healthcheck.AccessURLReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
+			WebsocketReport: healthcheck.WebsocketReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
+			DatabaseReport: healthcheck.DatabaseReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  false,
+				Severity: health.SeverityError,
+			},
+			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
+			ProvisionerDaemonsReport: healthcheck.ProvisionerDaemonsReport{
+				Severity: health.SeverityOK,
+			},
+		},
+		healthy:         false,
+		severity:        health.SeverityError,
+		failingSections: []codersdk.HealthSection{codersdk.HealthSectionPubsub},
 	}, {
 		name: "ProxyFail",
 		checker: &testChecker{
@@ -254,6 +317,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  false,
 				Severity: health.SeverityError,
@@ -284,6 +351,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Warnings: []health.Message{{Message: "foobar", Code: "EFOOBAR"}},
@@ -315,6 +386,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -345,6 +420,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  true,
 				Severity: health.SeverityOK,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  true,
+				Severity: health.SeverityOK,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  true,
 				Severity: health.SeverityOK,
@@ -377,6 +456,10 @@ func TestHealthcheck(t *testing.T) {
 				Healthy:  false,
 				Severity: health.SeverityError,
 			},
+			PubsubReport: healthcheck.PubsubReport{
+				Healthy:  false,
+				Severity: health.SeverityError,
+			},
 			WorkspaceProxyReport: healthcheck.WorkspaceProxyReport{
 				Healthy:  false,
 				Severity: health.SeverityError,
@@ -391,6 +474,7 @@ func TestHealthcheck(t *testing.T) {
 			codersdk.HealthSectionAccessURL,
 			codersdk.HealthSectionWebsocket,
 			codersdk.HealthSectionDatabase,
+			codersdk.HealthSectionPubsub,
 			codersdk.HealthSectionWorkspaceProxy,
 			codersdk.HealthSectionProvisionerDaemons,
 		},
@@ -417,6 +501,8 @@ func TestHealthcheck(t *testing.T) {
 			assert.Equal(t, c.checker.WebsocketReport.Severity, report.Websocket.Severity)
 			assert.Equal(t, c.checker.DatabaseReport.Healthy, report.Database.Healthy)
 			assert.Equal(t, c.checker.DatabaseReport.Severity, report.Database.Severity)
+			assert.Equal(t, c.checker.PubsubReport.Healthy, report.Pubsub.Healthy)
+			assert.Equal(t, c.checker.PubsubReport.Severity, report.Pubsub.Severity)
 			assert.NotZero(t, report.Time)
 			assert.NotZero(t, report.CoderVersion)
 		})
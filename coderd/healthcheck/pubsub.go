@@ -0,0 +1,98 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+)
+
+const (
+	PubsubDefaultThreshold = 100 * time.Millisecond
+)
+
+// @typescript-generate PubsubReport
+type PubsubReport struct {
+	// Healthy is deprecated and left for backward compatibility purposes, use `Severity` instead.
+	Healthy   bool             `json:"healthy"`
+	Severity  health.Severity  `json:"severity" enums:"ok,warning,error"`
+	Warnings  []health.Message `json:"warnings"`
+	Dismissed bool             `json:"dismissed"`
+
+	Latency     string  `json:"latency"`
+	LatencyMS   int64   `json:"latency_ms"`
+	ThresholdMS int64   `json:"threshold_ms"`
+	Error       *string `json:"error"`
+}
+
+type PubsubReportOptions struct {
+	Pubsub    pubsub.Pubsub
+	Threshold time.Duration
+
+	Dismissed bool
+}
+
+// Run publishes a message on a unique event and measures how long it takes
+// to be delivered back to a subscriber.
+func (r *PubsubReport) Run(ctx context.Context, opts *PubsubReportOptions) {
+	r.Warnings = []health.Message{}
+	r.Severity = health.SeverityOK
+	r.Dismissed = opts.Dismissed
+
+	r.ThresholdMS = opts.Threshold.Milliseconds()
+	if r.ThresholdMS == 0 {
+		r.ThresholdMS = PubsubDefaultThreshold.Milliseconds()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var (
+		event    = "healthcheck:" + uuid.NewString()
+		message  = []byte(uuid.NewString())
+		received = make(chan struct{})
+	)
+	cancelSubscribe, err := opts.Pubsub.Subscribe(event, func(_ context.Context, msg []byte) {
+		if string(msg) != string(message) {
+			return
+		}
+		select {
+		case <-received:
+		default:
+			close(received)
+		}
+	})
+	if err != nil {
+		r.Error = health.Errorf(health.CodePubsubSubscribe, "subscribe: %s", err)
+		r.Severity = health.SeverityError
+		return
+	}
+	defer cancelSubscribe()
+
+	start := time.Now()
+	err = opts.Pubsub.Publish(event, message)
+	if err != nil {
+		r.Error = health.Errorf(health.CodePubsubPublish, "publish: %s", err)
+		r.Severity = health.SeverityError
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		r.Error = health.Errorf(health.CodePubsubNotReceived, "published message was not received: %s", ctx.Err())
+		r.Severity = health.SeverityError
+		return
+	case <-received:
+	}
+
+	latency := time.Since(start)
+	r.Latency = latency.String()
+	r.LatencyMS = latency.Milliseconds()
+	if r.LatencyMS >= r.ThresholdMS {
+		r.Severity = health.SeverityWarning
+		r.Warnings = append(r.Warnings, health.Messagef(health.CodePubsubSlow, "pubsub message delivery above threshold"))
+	}
+	r.Healthy = true
+}
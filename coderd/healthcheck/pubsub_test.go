@@ -0,0 +1,115 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/coderd/healthcheck/health"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPubsub(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.PubsubReport{}
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.PubsubReportOptions{Pubsub: pubsub.NewInMemory()})
+
+		assert.True(t, report.Healthy)
+		assert.Equal(t, health.SeverityOK, report.Severity)
+		assert.NotEmpty(t, report.Latency)
+		assert.Equal(t, healthcheck.PubsubDefaultThreshold.Milliseconds(), report.ThresholdMS)
+		assert.Empty(t, report.Warnings)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("PublishError", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.PubsubReport{}
+			ps          = &fakePubsub{Pubsub: pubsub.NewInMemory(), publishErr: xerrors.New("connection refused")}
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.PubsubReportOptions{Pubsub: ps, Dismissed: true})
+
+		assert.False(t, report.Healthy)
+		assert.True(t, report.Dismissed)
+		assert.Equal(t, health.SeverityError, report.Severity)
+		require.NotNil(t, report.Error)
+		assert.Contains(t, *report.Error, "connection refused")
+		assert.Contains(t, *report.Error, health.CodePubsubPublish)
+	})
+
+	t.Run("NotReceived", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.IntervalMedium)
+			report      = healthcheck.PubsubReport{}
+			ps          = &fakePubsub{Pubsub: pubsub.NewInMemory(), drop: true}
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.PubsubReportOptions{Pubsub: ps})
+
+		assert.False(t, report.Healthy)
+		assert.Equal(t, health.SeverityError, report.Severity)
+		require.NotNil(t, report.Error)
+		assert.Contains(t, *report.Error, health.CodePubsubNotReceived)
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.PubsubReport{}
+			ps          = &fakePubsub{Pubsub: pubsub.NewInMemory(), delay: 10 * time.Millisecond}
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.PubsubReportOptions{Pubsub: ps, Threshold: time.Millisecond})
+
+		assert.True(t, report.Healthy)
+		assert.Equal(t, health.SeverityWarning, report.Severity)
+		assert.Equal(t, time.Millisecond.Milliseconds(), report.ThresholdMS)
+		require.Len(t, report.Warnings, 1)
+		assert.Equal(t, health.CodePubsubSlow, report.Warnings[0].Code)
+	})
+}
+
+// fakePubsub fails, drops or delays published messages.
+type fakePubsub struct {
+	pubsub.Pubsub
+	publishErr error
+	drop       bool
+	delay      time.Duration
+}
+
+func (p *fakePubsub) Publish(event string, message []byte) error {
+	if p.publishErr != nil {
+		return p.publishErr
+	}
+	if p.drop {
+		return nil
+	}
+	time.Sleep(p.delay)
+	return p.Pubsub.Publish(event, message)
+}
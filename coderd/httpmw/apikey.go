@@ -9,21 +9,26 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
+	"github.com/coder/coder/v2/coderd/useractivity"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -113,6 +118,20 @@ type ExtractAPIKeyConfig struct {
 	// SessionTokenFunc is a custom function that can be used to extract the API
 	// key. If nil, the default behavior is used.
 	SessionTokenFunc func(r *http.Request) string
+
+	// LifetimePolicy returns the lifetime policy for a user with the given
+	// roles. It is used to refresh and truncate the expiry of keys, and may be
+	// nil to only refresh keys as set by DisableSessionExpiryRefresh.
+	LifetimePolicy func(roles []string) apikey.LifetimePolicy
+
+	// IPRejected is called when a request is rejected because it was made from
+	// outside the IP allowlist of the API key or its user. It is used to audit
+	// the rejection, and may be nil.
+	IPRejected func(r *http.Request, key database.APIKey)
+
+	// ActivityTracker records the API category and workspace of each
+	// authenticated request, and may be nil.
+	ActivityTracker *useractivity.Tracker
 }
 
 // ExtractAPIKeyMW calls ExtractAPIKey with the given config on each request,
@@ -120,6 +139,8 @@ type ExtractAPIKeyConfig struct {
 func ExtractAPIKeyMW(cfg ExtractAPIKeyConfig) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			// Nested API key middleware must not count the request twice.
+			_, nested := APIKeyOptional(r)
 			keyPtr, authzPtr, ok := ExtractAPIKey(rw, r, cfg)
 			if !ok {
 				return
@@ -140,10 +161,30 @@ func ExtractAPIKeyMW(cfg ExtractAPIKeyConfig) func(http.Handler) http.Handler {
 			ctx = dbauthz.As(ctx, authz.Actor)
 
 			next.ServeHTTP(rw, r.WithContext(ctx))
+			if cfg.ActivityTracker != nil && !nested {
+				recordActivity(cfg.ActivityTracker, r, key.UserID)
+			}
 		})
 	}
 }
 
+// recordActivity counts the request in the user's activity. It runs after the
+// request is handled, when the router has matched the full route pattern and
+// URL parameters.
+func recordActivity(tracker *useractivity.Tracker, r *http.Request, userID uuid.UUID) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return
+	}
+	// Only workspace IDs are recorded. Routes that take a workspace name
+	// count towards the category alone.
+	workspaceID, err := uuid.Parse(rctx.URLParam("workspace"))
+	if err != nil {
+		workspaceID = uuid.Nil
+	}
+	tracker.Record(userID, useractivity.Category(rctx.RoutePattern()), workspaceID)
+}
+
 func APIKeyFromRequest(ctx context.Context, db database.Store, sessionTokenFunc func(r *http.Request) string, r *http.Request) (*database.APIKey, codersdk.Response, bool) {
 	tokenFunc := APITokenFromRequest
 	if sessionTokenFunc != nil {
@@ -327,6 +368,31 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	// If the key is valid, we also fetch the user roles and status.
+	// The roles are used for RBAC authorize checks, and the status
+	// is to block 'suspended' users from accessing the platform.
+	//nolint:gocritic // system needs to update user roles
+	roles, err := cfg.DB.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), key.UserID)
+	if err != nil {
+		return write(http.StatusUnauthorized, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Internal error fetching user's roles. %s", err.Error()),
+		})
+	}
+
+	// The IP allowlists are checked before the key is marked as used, so a
+	// rejected request doesn't extend the key.
+	remoteAddr := RequestAddr(r)
+	if !key.AllowsIP(remoteAddr) || !roles.AllowsIP(remoteAddr) {
+		if cfg.IPRejected != nil {
+			cfg.IPRejected(r, *key)
+		}
+		return write(http.StatusForbidden, codersdk.Response{
+			Message: "API key can't be used from this IP address.",
+			Detail:  fmt.Sprintf("%s is not in the IP allowlist of the API key or its user.", remoteAddr),
+		})
+	}
+
 	// Only update LastUsed once an hour to prevent database spam.
 	if now.Sub(key.LastUsed) > time.Hour {
 		key.LastUsed = now
@@ -344,15 +410,33 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		}
 		changed = true
 	}
+	policy := apikey.LifetimePolicy{DisableExpiryRefresh: cfg.DisableSessionExpiryRefresh}
+	if cfg.LifetimePolicy != nil {
+		policy = cfg.LifetimePolicy(roles.Roles)
+		policy.DisableExpiryRefresh = policy.DisableExpiryRefresh || cfg.DisableSessionExpiryRefresh
+	}
 	// Only update the ExpiresAt once an hour to prevent database spam.
 	// We extend the ExpiresAt to reduce re-authentication.
-	if !cfg.DisableSessionExpiryRefresh {
+	if !policy.DisableExpiryRefresh {
 		apiKeyLifetime := time.Duration(key.LifetimeSeconds) * time.Second
 		if key.ExpiresAt.Sub(now) <= apiKeyLifetime-time.Hour {
 			key.ExpiresAt = now.Add(apiKeyLifetime)
 			changed = true
 		}
 	}
+	// Keys that outlive the lifetime policy of the user's roles, e.g. because
+	// it was lowered after they were created, are shortened if the policy
+	// truncates them.
+	if expiresAt := policy.TruncatedExpiry(*key, now); !expiresAt.Equal(key.ExpiresAt) {
+		if !expiresAt.After(now) {
+			return optionalWrite(http.StatusUnauthorized, codersdk.Response{
+				Message: SignedOutErrorMessage,
+				Detail:  fmt.Sprintf("API key expired at %q, the end of the maximum lifetime allowed for its user.", expiresAt.String()),
+			})
+		}
+		key.ExpiresAt = expiresAt
+		changed = true
+	}
 	if changed {
 		//nolint:gocritic // System needs to update API Key LastUsed
 		err := cfg.DB.UpdateAPIKeyByID(dbauthz.AsSystemRestricted(ctx), database.UpdateAPIKeyByIDParams{
@@ -408,18 +492,6 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		}
 	}
 
-	// If the key is valid, we also fetch the user roles and status.
-	// The roles are used for RBAC authorize checks, and the status
-	// is to block 'suspended' users from accessing the platform.
-	//nolint:gocritic // system needs to update user roles
-	roles, err := cfg.DB.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), key.UserID)
-	if err != nil {
-		return write(http.StatusUnauthorized, codersdk.Response{
-			Message: internalErrorMessage,
-			Detail:  fmt.Sprintf("Internal error fetching user's roles. %s", err.Error()),
-		})
-	}
-
 	if roles.Status == database.UserStatusDormant {
 		// If coder confirms that the dormant user is valid, it can switch their account to active.
 		// nolint:gocritic
@@ -443,20 +515,84 @@ func ExtractAPIKey(rw http.ResponseWriter, r *http.Request, cfg ExtractAPIKeyCon
 		})
 	}
 
+	// Keys limited to workspaces must also be able to read the templates of
+	// those workspaces.
+	var templateIDs []uuid.UUID
+	if len(key.AllowedWorkspaceIDs) > 0 {
+		//nolint:gocritic // System needs to fetch the workspaces to build the key's scope.
+		workspaces, err := cfg.DB.GetWorkspacesByIDs(dbauthz.AsSystemRestricted(ctx), key.AllowedWorkspaceIDs)
+		if err != nil {
+			return write(http.StatusInternalServerError, codersdk.Response{
+				Message: internalErrorMessage,
+				Detail:  fmt.Sprintf("Internal error fetching allowed workspaces. %s", err.Error()),
+			})
+		}
+		found := make(map[uuid.UUID]uuid.UUID, len(workspaces))
+		for _, workspace := range workspaces {
+			if workspace.Deleted {
+				continue
+			}
+			found[workspace.ID] = workspace.TemplateID
+		}
+		// Fail closed if a workspace the key is limited to is gone, rather
+		// than silently narrowing what the key can access.
+		for _, id := range key.AllowedWorkspaceIDs {
+			templateID, ok := found[id]
+			if !ok {
+				return write(http.StatusUnauthorized, codersdk.Response{
+					Message: SignedOutErrorMessage,
+					Detail:  fmt.Sprintf("API key is limited to workspace %s, which has been deleted.", id),
+				})
+			}
+			templateIDs = append(templateIDs, templateID)
+		}
+	}
+
+	scope, err := key.RBACScope(templateIDs...)
+	if err != nil {
+		return write(http.StatusUnauthorized, codersdk.Response{
+			Message: SignedOutErrorMessage,
+			Detail:  fmt.Sprintf("API key has an invalid scope: %s", err.Error()),
+		})
+	}
+
+	//nolint:gocritic // System needs to expand the user's custom roles.
+	expandedRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), cfg.DB, roles.Roles)
+	if err != nil {
+		return write(http.StatusInternalServerError, codersdk.Response{
+			Message: internalErrorMessage,
+			Detail:  fmt.Sprintf("Failed to expand authenticated user roles: %s", err.Error()),
+		})
+	}
+
 	// Actor is the user's authorization context.
 	authz := Authorization{
 		ActorName: roles.Username,
 		Actor: rbac.Subject{
 			ID:     key.UserID.String(),
-			Roles:  rbac.RoleNames(roles.Roles),
+			Roles:  expandedRoles,
 			Groups: roles.Groups,
-			Scope:  rbac.ScopeName(key.Scope),
+			Scope:  scope,
 		}.WithCachedASTValue(),
 	}
 
 	return key, &authz, true
 }
 
+// RequestAddr returns the address of the client of a request. The address is
+// invalid if it can't be parsed, which no IP allowlist contains.
+func RequestAddr(r *http.Request) netip.Addr {
+	addr, err := netip.ParseAddr(r.RemoteAddr)
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+		if err != nil {
+			return netip.Addr{}
+		}
+		addr = addrPort.Addr()
+	}
+	return addr.Unmap()
+}
+
 // APITokenFromRequest returns the api token from the request.
 // Find the session token from:
 // 1: The cookie
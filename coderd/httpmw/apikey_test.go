@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
 
+	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
@@ -433,6 +434,62 @@ func TestAPIKey(t *testing.T) {
 		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
 	})
 
+	t.Run("LifetimePolicy", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db   = dbmem.New()
+			user = dbgen.User(t, db, database.User{})
+			// The token was created before the maximum token lifetime was
+			// lowered to a week.
+			sentAPIKey, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				LoginType: database.LoginTypeToken,
+				CreatedAt: dbtime.Now().AddDate(0, 0, -3),
+				LastUsed:  dbtime.Now().AddDate(0, 0, -1),
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 27),
+			})
+			_, expiredToken = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				LoginType: database.LoginTypeToken,
+				CreatedAt: dbtime.Now().AddDate(0, 0, -10),
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 20),
+			})
+			policy = apikey.LifetimePolicy{
+				MaxTokenLifetime:     7 * 24 * time.Hour,
+				DisableExpiryRefresh: true,
+			}
+		)
+		cfg := httpmw.ExtractAPIKeyConfig{
+			DB: db,
+			LifetimePolicy: func([]string) apikey.LifetimePolicy {
+				return policy
+			},
+		}
+		do := func(token string) *http.Response {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(codersdk.SessionTokenHeader, token)
+			rw := httptest.NewRecorder()
+			httpmw.ExtractAPIKeyMW(cfg)(successHandler).ServeHTTP(rw, r)
+			res := rw.Result()
+			t.Cleanup(func() { _ = res.Body.Close() })
+			return res
+		}
+
+		// Existing keys are grandfathered unless the policy truncates them.
+		require.Equal(t, http.StatusOK, do(token).StatusCode)
+		require.Equal(t, http.StatusOK, do(expiredToken).StatusCode)
+		gotAPIKey, err := db.GetAPIKeyByID(context.Background(), sentAPIKey.ID)
+		require.NoError(t, err)
+		require.Equal(t, sentAPIKey.ExpiresAt, gotAPIKey.ExpiresAt)
+
+		policy.Truncate = true
+		require.Equal(t, http.StatusOK, do(token).StatusCode)
+		gotAPIKey, err = db.GetAPIKeyByID(context.Background(), sentAPIKey.ID)
+		require.NoError(t, err)
+		require.WithinDuration(t, sentAPIKey.CreatedAt.Add(policy.MaxTokenLifetime), gotAPIKey.ExpiresAt, time.Second)
+		require.Equal(t, http.StatusUnauthorized, do(expiredToken).StatusCode)
+	})
+
 	t.Run("OAuthNotExpired", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -548,6 +605,89 @@ func TestAPIKey(t *testing.T) {
 		require.Equal(t, net.ParseIP("1.1.1.1"), gotAPIKey.IPAddress.IPNet.IP)
 	})
 
+	t.Run("KeyIPAllowlist", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db                = dbmem.New()
+			user              = dbgen.User(t, db, database.User{})
+			sentAPIKey, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:      user.ID,
+				LastUsed:    dbtime.Now().AddDate(0, 0, -1),
+				ExpiresAt:   dbtime.Now().AddDate(0, 0, 1),
+				IPAllowlist: []string{"10.0.0.0/8"},
+			})
+			rejected []database.APIKey
+		)
+		cfg := httpmw.ExtractAPIKeyConfig{
+			DB: db,
+			IPRejected: func(_ *http.Request, key database.APIKey) {
+				rejected = append(rejected, key)
+			},
+		}
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "1.1.1.1:1234"
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+		rw := httptest.NewRecorder()
+		httpmw.ExtractAPIKeyMW(cfg)(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.Len(t, rejected, 1)
+		require.Equal(t, sentAPIKey.ID, rejected[0].ID)
+
+		// The rejected request must not mark the key as used.
+		gotAPIKey, err := db.GetAPIKeyByID(r.Context(), sentAPIKey.ID)
+		require.NoError(t, err)
+		require.Equal(t, sentAPIKey.LastUsed, gotAPIKey.LastUsed)
+
+		r = httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.1.2.3"
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+		rw = httptest.NewRecorder()
+		httpmw.ExtractAPIKeyMW(cfg)(successHandler).ServeHTTP(rw, r)
+		res = rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, rejected, 1)
+	})
+
+	t.Run("UserIPAllowlist", func(t *testing.T) {
+		t.Parallel()
+		var (
+			db   = dbmem.New()
+			user = dbgen.User(t, db, database.User{
+				IPAllowlist: []string{"2001:db8::/32"},
+			})
+			_, token = dbgen.APIKey(t, db, database.APIKey{
+				UserID:    user.ID,
+				ExpiresAt: dbtime.Now().AddDate(0, 0, 1),
+			})
+		)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.1.2.3"
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+		rw := httptest.NewRecorder()
+		httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res := rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		r = httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "[2001:db8::1]:1234"
+		r.Header.Set(codersdk.SessionTokenHeader, token)
+		rw = httptest.NewRecorder()
+		httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB: db,
+		})(successHandler).ServeHTTP(rw, r)
+		res = rw.Result()
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
 	t.Run("RedirectToLogin", func(t *testing.T) {
 		t.Parallel()
 		var (
@@ -11,8 +11,9 @@ import (
 )
 
 type (
-	organizationParamContextKey       struct{}
-	organizationMemberParamContextKey struct{}
+	organizationParamContextKey          struct{}
+	organizationMemberParamContextKey    struct{}
+	organizationNonMemberParamContextKey struct{}
 )
 
 // OrganizationParam returns the organization from the ExtractOrganizationParam handler.
@@ -34,6 +35,17 @@ func OrganizationMemberParam(r *http.Request) OrganizationMember {
 	return organizationMember
 }
 
+// OrganizationNonMemberParam returns the user to add to the organization from the
+// ExtractOrganizationNonMemberParam handler. Only the user ID, organization ID and
+// username are set.
+func OrganizationNonMemberParam(r *http.Request) OrganizationMember {
+	organizationMember, ok := r.Context().Value(organizationNonMemberParamContextKey{}).(OrganizationMember)
+	if !ok {
+		panic("developer error: organization non-member param middleware not provided")
+	}
+	return organizationMember
+}
+
 // ExtractOrganizationParam grabs an organization from the "organization" URL parameter.
 // This middleware requires the API key middleware higher in the call stack for authentication.
 func ExtractOrganizationParam(db database.Store) func(http.Handler) http.Handler {
@@ -120,3 +132,33 @@ func ExtractOrganizationMemberParam(db database.Store) func(http.Handler) http.H
 		})
 	}
 }
+
+// ExtractOrganizationNonMemberParam grabs a user that is not yet a member of the
+// organization from the "user" URL parameter, so they can be added to it.
+// This middleware requires the ExtractOrganization middleware higher in the stack.
+func ExtractOrganizationNonMemberParam(db database.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			// Organization admins can add users to their organization without
+			// having permission to read them, so the user is resolved as the
+			// system. As in ExtractOrganizationMemberParam, only the ID and
+			// username are passed on to the API handler.
+			// nolint:gocritic
+			user, ok := extractUserContext(dbauthz.AsSystemRestricted(ctx), db, rw, r)
+			if !ok {
+				return
+			}
+			organization := OrganizationParam(r)
+
+			ctx = context.WithValue(ctx, organizationNonMemberParamContextKey{}, OrganizationMember{
+				OrganizationMember: database.OrganizationMember{
+					OrganizationID: organization.ID,
+					UserID:         user.ID,
+				},
+				Username: user.Username,
+			})
+			next.ServeHTTP(rw, r.WithContext(ctx))
+		})
+	}
+}
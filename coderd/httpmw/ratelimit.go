@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/httprate"
+	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
@@ -19,6 +23,173 @@ import (
 // RateLimit returns a handler that limits requests per-minute based
 // on IP, endpoint, and user ID (if available).
 func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler {
+	return RateLimitWithOptions(RateLimitOptions{
+		Count:  count,
+		Window: window,
+	})
+}
+
+// RateLimitOptions configures RateLimitWithOptions.
+type RateLimitOptions struct {
+	// Count is the number of requests allowed per window. Zero or negative
+	// values mean no rate limit.
+	Count  int
+	Window time.Duration
+	// DB stores the request counters when set, so the limit applies across
+	// every replica. Otherwise each replica counts requests in memory.
+	DB        database.Store
+	Overrides RateLimitOverrides
+}
+
+// RateLimitOverrides replace the default limit for some requests. Zero or
+// negative limits disable the rate limit for matching requests.
+type RateLimitOverrides struct {
+	// Endpoints maps path prefixes to limits. The longest matching prefix
+	// wins.
+	Endpoints map[string]int
+	// Users maps user IDs to limits. They take precedence over endpoint
+	// limits.
+	Users map[uuid.UUID]int
+}
+
+// ParseRateLimitOverrides parses endpoint overrides in the form
+// "<path prefix>=<limit>" and user overrides in the form
+// "<user ID>=<limit>".
+func ParseRateLimitOverrides(endpoints []string, users []string) (RateLimitOverrides, error) {
+	overrides := RateLimitOverrides{
+		Endpoints: make(map[string]int, len(endpoints)),
+		Users:     make(map[uuid.UUID]int, len(users)),
+	}
+	for _, endpoint := range endpoints {
+		path, limit, err := parseRateLimitOverride(endpoint)
+		if err != nil {
+			return RateLimitOverrides{}, xerrors.Errorf("parse endpoint rate limit %q: %w", endpoint, err)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return RateLimitOverrides{}, xerrors.Errorf("parse endpoint rate limit %q: path must start with a slash", endpoint)
+		}
+		overrides.Endpoints[path] = limit
+	}
+	for _, user := range users {
+		rawID, limit, err := parseRateLimitOverride(user)
+		if err != nil {
+			return RateLimitOverrides{}, xerrors.Errorf("parse user rate limit %q: %w", user, err)
+		}
+		userID, err := uuid.Parse(rawID)
+		if err != nil {
+			return RateLimitOverrides{}, xerrors.Errorf("parse user rate limit %q: invalid user ID: %w", user, err)
+		}
+		overrides.Users[userID] = limit
+	}
+	return overrides, nil
+}
+
+func parseRateLimitOverride(raw string) (string, int, error) {
+	key, rawLimit, ok := strings.Cut(raw, "=")
+	if !ok || key == "" {
+		return "", 0, xerrors.New("must be in the form <key>=<limit>")
+	}
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil {
+		return "", 0, xerrors.Errorf("invalid limit: %w", err)
+	}
+	return key, limit, nil
+}
+
+// limit returns the number of requests allowed per window for the request.
+func (o RateLimitOptions) limit(r *http.Request) int {
+	if apiKey, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey); ok {
+		if limit, ok := o.Overrides.Users[apiKey.UserID]; ok {
+			return limit
+		}
+	}
+	var (
+		limit   = o.Count
+		longest = -1
+	)
+	for prefix, prefixLimit := range o.Overrides.Endpoints {
+		if len(prefix) > longest && strings.HasPrefix(r.URL.Path, prefix) {
+			limit = prefixLimit
+			longest = len(prefix)
+		}
+	}
+	return limit
+}
+
+// RateLimitWithOptions returns a handler that limits requests based on IP,
+// endpoint, and user ID (if available).
+func RateLimitWithOptions(opts RateLimitOptions) func(http.Handler) http.Handler {
+	if opts.DB != nil {
+		return sharedRateLimit(opts)
+	}
+	if len(opts.Overrides.Endpoints) == 0 && len(opts.Overrides.Users) == 0 {
+		return memoryRateLimit(opts.Count, opts.Window)
+	}
+
+	// httprate only supports a single limit, so requests are routed to a
+	// limiter for each distinct limit. A key always maps to the same limit,
+	// so the counters never overlap.
+	limits := map[int]struct{}{opts.Count: {}}
+	for _, limit := range opts.Overrides.Endpoints {
+		limits[limit] = struct{}{}
+	}
+	for _, limit := range opts.Overrides.Users {
+		limits[limit] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		handlers := make(map[int]http.Handler, len(limits))
+		for limit := range limits {
+			handlers[limit] = memoryRateLimit(limit, opts.Window)(next)
+		}
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			handlers[opts.limit(r)].ServeHTTP(rw, r)
+		})
+	}
+}
+
+// rateLimitKey returns the key requests are counted under. Requests are
+// counted per user, or per IP address for unauthenticated requests. Owners
+// may bypass the rate limit for load tests and automation.
+func rateLimitKey(r *http.Request) (key string, bypass bool, err error) {
+	// Prioritize by user, but fallback to IP.
+	apiKey, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey)
+	if !ok {
+		key, err = httprate.KeyByIP(r)
+		return key, false, err
+	}
+
+	if ok, _ := strconv.ParseBool(r.Header.Get(codersdk.BypassRatelimitHeader)); !ok {
+		// No bypass attempt, just ratelimit.
+		return apiKey.UserID.String(), false, nil
+	}
+
+	// Allow Owner to bypass rate limiting for load tests
+	// and automation.
+	auth := UserAuthorization(r)
+
+	// We avoid using rbac.Authorizer since rego is CPU-intensive
+	// and undermines the DoS-prevention goal of the rate limiter.
+	for _, role := range auth.Actor.SafeRoleNames() {
+		if role == rbac.RoleOwner() {
+			return apiKey.UserID.String(), true, nil
+		}
+	}
+
+	return apiKey.UserID.String(), false, xerrors.Errorf(
+		"%q provided but user is not %v",
+		codersdk.BypassRatelimitHeader, rbac.RoleOwner(),
+	)
+}
+
+func writeRateLimited(rw http.ResponseWriter, r *http.Request, count int, window time.Duration) {
+	httpapi.Write(r.Context(), rw, http.StatusTooManyRequests, codersdk.Response{
+		Message: fmt.Sprintf("You've been rate limited for sending more than %v requests in %v.", count, window),
+	})
+}
+
+// memoryRateLimit counts requests in memory, so the limit applies per
+// replica.
+func memoryRateLimit(count int, window time.Duration) func(http.Handler) http.Handler {
 	// -1 is no rate limit
 	if count <= 0 {
 		return func(handler http.Handler) http.Handler {
@@ -30,43 +201,72 @@ func RateLimit(count int, window time.Duration) func(http.Handler) http.Handler
 		count,
 		window,
 		httprate.WithKeyFuncs(func(r *http.Request) (string, error) {
-			// Prioritize by user, but fallback to IP.
-			apiKey, ok := r.Context().Value(apiKeyContextKey{}).(database.APIKey)
-			if !ok {
-				return httprate.KeyByIP(r)
+			key, bypass, err := rateLimitKey(r)
+			if bypass {
+				// HACK: use a random key each time to
+				// de facto disable rate limiting. The
+				// `httprate` package has no
+				// support for selectively changing the limit
+				// for particular keys.
+				return cryptorand.String(16)
 			}
+			return key, err
+		}, httprate.KeyByEndpoint),
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			writeRateLimited(w, r, count, window)
+		}),
+	)
+}
 
-			if ok, _ := strconv.ParseBool(r.Header.Get(codersdk.BypassRatelimitHeader)); !ok {
-				// No bypass attempt, just ratelimit.
-				return apiKey.UserID.String(), nil
+// sharedRateLimit counts requests in the database, so the limit applies
+// across every replica. Requests are counted in fixed windows.
+func sharedRateLimit(opts RateLimitOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			limit := opts.limit(r)
+			// -1 is no rate limit
+			if limit <= 0 {
+				next.ServeHTTP(rw, r)
+				return
 			}
-
-			// Allow Owner to bypass rate limiting for load tests
-			// and automation.
-			auth := UserAuthorization(r)
-
-			// We avoid using rbac.Authorizer since rego is CPU-intensive
-			// and undermines the DoS-prevention goal of the rate limiter.
-			for _, role := range auth.Actor.SafeRoleNames() {
-				if role == rbac.RoleOwner() {
-					// HACK: use a random key each time to
-					// de facto disable rate limiting. The
-					// `httprate` package has no
-					// support for selectively changing the limit
-					// for particular keys.
-					return cryptorand.String(16)
-				}
+			key, bypass, err := rateLimitKey(r)
+			if err != nil {
+				// Match the status httprate uses for key errors.
+				http.Error(rw, err.Error(), http.StatusPreconditionRequired)
+				return
+			}
+			if bypass {
+				next.ServeHTTP(rw, r)
+				return
 			}
 
-			return apiKey.UserID.String(), xerrors.Errorf(
-				"%q provided but user is not %v",
-				codersdk.BypassRatelimitHeader, rbac.RoleOwner(),
-			)
-		}, httprate.KeyByEndpoint),
-		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
-			httpapi.Write(r.Context(), w, http.StatusTooManyRequests, codersdk.Response{
-				Message: fmt.Sprintf("You've been rate limited for sending more than %v requests in %v.", count, window),
+			windowStart := dbtime.Now().Truncate(opts.Window)
+			//nolint:gocritic // Requests are counted before they are
+			// authorized, and unauthenticated requests are counted too.
+			count, err := opts.DB.IncrementRateLimitCounter(dbauthz.AsSystemRestricted(r.Context()), database.IncrementRateLimitCounterParams{
+				Key:         key + ":" + r.URL.Path,
+				WindowStart: windowStart,
 			})
-		}),
-	)
+			if err != nil {
+				// Fail open, an unavailable database should not take down
+				// every endpoint with it.
+				next.ServeHTTP(rw, r)
+				return
+			}
+
+			remaining := limit - int(count)
+			if remaining < 0 {
+				remaining = 0
+			}
+			rw.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			rw.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			rw.Header().Set("X-RateLimit-Reset", strconv.FormatInt(windowStart.Add(opts.Window).Unix(), 10))
+			if int(count) > limit {
+				rw.Header().Set("Retry-After", strconv.Itoa(int(time.Until(windowStart.Add(opts.Window)).Seconds())+1))
+				writeRateLimited(rw, r, limit, opts.Window)
+				return
+			}
+			next.ServeHTTP(rw, r)
+		})
+	}
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/database"
@@ -145,4 +146,109 @@ func TestRateLimit(t *testing.T) {
 			require.False(t, resp.StatusCode == http.StatusTooManyRequests)
 		}
 	})
+	t.Run("Overrides", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		u := dbgen.User(t, db, database.User{})
+		_, key := dbgen.APIKey(t, db, database.APIKey{UserID: u.ID})
+
+		rtr := chi.NewRouter()
+		rtr.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+			DB:       db,
+			Optional: true,
+		}))
+		rtr.Use(httpmw.RateLimitWithOptions(httpmw.RateLimitOptions{
+			Count:  1,
+			Window: time.Minute,
+			Overrides: httpmw.RateLimitOverrides{
+				Endpoints: map[string]int{"/unlimited": -1, "/limited": 3},
+				Users:     map[uuid.UUID]int{u.ID: 2},
+			},
+		}))
+		rtr.Get("/*", func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+
+		limited := func(path string, sessionToken string, n int) int {
+			var count int
+			remoteAddr := randRemoteAddr()
+			for i := 0; i < n; i++ {
+				req := httptest.NewRequest("GET", path, nil)
+				req.RemoteAddr = remoteAddr
+				if sessionToken != "" {
+					req.Header.Set(codersdk.SessionTokenHeader, sessionToken)
+				}
+				rec := httptest.NewRecorder()
+				rtr.ServeHTTP(rec, req)
+				resp := rec.Result()
+				_ = resp.Body.Close()
+				if resp.StatusCode == http.StatusTooManyRequests {
+					count++
+				}
+			}
+			return count
+		}
+
+		require.Equal(t, 4, limited("/", "", 5))
+		require.Equal(t, 0, limited("/unlimited/path", "", 5))
+		require.Equal(t, 2, limited("/limited", "", 5))
+		// The user limit takes precedence over the endpoint limit.
+		require.Equal(t, 3, limited("/limited", key, 5))
+	})
+
+	t.Run("Shared", func(t *testing.T) {
+		t.Parallel()
+
+		db := dbmem.New()
+		// Each router acts as a separate replica.
+		routers := make([]chi.Router, 2)
+		for i := range routers {
+			routers[i] = chi.NewRouter()
+			routers[i].Use(httpmw.RateLimitWithOptions(httpmw.RateLimitOptions{
+				Count: 2,
+				// A long window keeps the requests from straddling two windows.
+				Window: time.Hour,
+				DB:     db,
+			}))
+			routers[i].Get("/", func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+		}
+
+		remoteAddr := randRemoteAddr()
+		for i := 0; i < 4; i++ {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = remoteAddr
+			rec := httptest.NewRecorder()
+			routers[i%2].ServeHTTP(rec, req)
+			resp := rec.Result()
+			_ = resp.Body.Close()
+			require.Equal(t, i >= 2, resp.StatusCode == http.StatusTooManyRequests, "request %d", i)
+			require.Equal(t, "2", resp.Header.Get("X-RateLimit-Limit"))
+		}
+	})
+}
+
+func TestParseRateLimitOverrides(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	overrides, err := httpmw.ParseRateLimitOverrides(
+		[]string{"/api/v2/users/login=10", "/api/v2/files=-1"},
+		[]string{userID.String() + "=1000"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"/api/v2/users/login": 10, "/api/v2/files": -1}, overrides.Endpoints)
+	require.Equal(t, map[uuid.UUID]int{userID: 1000}, overrides.Users)
+
+	for _, invalid := range [][2][]string{
+		{{"api/v2/users=10"}, nil},
+		{{"/api/v2/users"}, nil},
+		{{"/api/v2/users=ten"}, nil},
+		{nil, {"admin=10"}},
+	} {
+		_, err := httpmw.ParseRateLimitOverrides(invalid[0], invalid[1])
+		require.Error(t, err, invalid)
+	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -93,17 +94,14 @@ func ExtractWorkspaceAgent(opts ExtractWorkspaceAgentConfig) func(http.Handler)
 				return
 			}
 
-			subject := rbac.Subject{
-				ID:     row.OwnerID.String(),
-				Roles:  rbac.RoleNames(row.OwnerRoles),
-				Groups: row.OwnerGroups,
-				Scope: rbac.WorkspaceAgentScope(rbac.WorkspaceAgentScopeParams{
-					WorkspaceID: row.WorkspaceID,
-					OwnerID:     row.OwnerID,
-					TemplateID:  row.TemplateID,
-					VersionID:   row.TemplateVersionID,
-				}),
-			}.WithCachedASTValue()
+			subject, err := WorkspaceAgentSubject(ctx, opts.DB, row)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error expanding workspace owner roles.",
+					Detail:  err.Error(),
+				})
+				return
+			}
 
 			ctx = context.WithValue(ctx, workspaceAgentContextKey{}, row.WorkspaceAgent)
 			// Also set the dbauthz actor for the request.
@@ -112,3 +110,25 @@ func ExtractWorkspaceAgent(opts ExtractWorkspaceAgentConfig) func(http.Handler)
 		})
 	}
 }
+
+// WorkspaceAgentSubject returns the subject a workspace agent acts as: the
+// owner of the workspace, scoped to the workspace.
+func WorkspaceAgentSubject(ctx context.Context, db database.Store, row database.GetWorkspaceAgentAndOwnerByAuthTokenRow) (rbac.Subject, error) {
+	//nolint:gocritic // System needs to expand the owner's custom roles.
+	roles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), db, row.OwnerRoles)
+	if err != nil {
+		return rbac.Subject{}, err
+	}
+
+	return rbac.Subject{
+		ID:     row.OwnerID.String(),
+		Roles:  roles,
+		Groups: row.OwnerGroups,
+		Scope: rbac.WorkspaceAgentScope(rbac.WorkspaceAgentScopeParams{
+			WorkspaceID: row.WorkspaceID,
+			OwnerID:     row.OwnerID,
+			TemplateID:  row.TemplateID,
+			VersionID:   row.TemplateVersionID,
+		}),
+	}.WithCachedASTValue(), nil
+}
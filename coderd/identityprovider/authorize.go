@@ -0,0 +1,145 @@
+package identityprovider
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// codeLifetime is how long an authorization code can be exchanged for.
+const codeLifetime = 10 * time.Minute
+
+// Authorize issues an authorization code to an app on behalf of the signed in
+// user and redirects back to the app. Apps are registered by administrators,
+// so the user is not asked for consent.
+func Authorize(db database.Store) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		apiKey := httpmw.APIKey(r)
+
+		query := r.URL.Query()
+		clientID, err := uuid.Parse(query.Get("client_id"))
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid client_id.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		//nolint:gocritic // The user cannot read OAuth2 apps, but they can
+		// authorize any app that has been registered.
+		app, err := db.GetOAuth2ProviderAppByID(dbauthz.AsSystemRestricted(ctx), clientID)
+		if errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Unknown client_id.",
+			})
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching OAuth2 application.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		// Errors up to this point cannot be sent to the app, since the redirect
+		// URI has not been verified yet.
+		redirectURL, err := validateRedirectURI(app.CallbackURL, query.Get("redirect_uri"))
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid redirect_uri.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		params := redirectURL.Query()
+		if state := query.Get("state"); state != "" {
+			params.Set("state", state)
+		}
+		if responseType := query.Get("response_type"); responseType != "code" {
+			params.Set("error", "unsupported_response_type")
+			params.Set("error_description", "Only the \"code\" response type is supported.")
+			redirectURL.RawQuery = params.Encode()
+			http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+			return
+		}
+		codeChallenge := query.Get("code_challenge")
+		if codeChallenge != "" && query.Get("code_challenge_method") != "S256" {
+			params.Set("error", "invalid_request")
+			params.Set("error_description", "Only the \"S256\" code challenge method is supported.")
+			redirectURL.RawQuery = params.Encode()
+			http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+			return
+		}
+
+		code, err := generateSecret()
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to generate OAuth2 authorization code.",
+			})
+			return
+		}
+		_, err = db.InsertOAuth2ProviderAppCode(ctx, database.InsertOAuth2ProviderAppCodeParams{
+			ID:            uuid.New(),
+			CreatedAt:     dbtime.Now(),
+			ExpiresAt:     dbtime.Now().Add(codeLifetime),
+			SecretPrefix:  []byte(code.Prefix),
+			HashedSecret:  code.Hashed,
+			AppID:         app.ID,
+			UserID:        apiKey.UserID,
+			Nonce:         query.Get("nonce"),
+			CodeChallenge: codeChallenge,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error creating OAuth2 authorization code.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+
+		params.Set("code", code.Formatted)
+		redirectURL.RawQuery = params.Encode()
+		http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+	}
+}
+
+// validateRedirectURI returns the URL to redirect back to. The redirect URI
+// must be on the same origin as the app's callback URL and under its path. If
+// no redirect URI was requested, the callback URL is used.
+func validateRedirectURI(callbackURL string, redirectURI string) (*url.URL, error) {
+	callback, err := url.Parse(callbackURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse callback url: %w", err)
+	}
+	if redirectURI == "" {
+		return callback, nil
+	}
+	redirect, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, xerrors.Errorf("parse redirect uri: %w", err)
+	}
+	if redirect.Scheme != callback.Scheme || redirect.Host != callback.Host {
+		return nil, xerrors.Errorf("redirect uri must have the same origin as the callback url %q", callbackURL)
+	}
+	callbackPath := strings.TrimSuffix(callback.Path, "/")
+	if redirect.Path != callbackPath && !strings.HasPrefix(redirect.Path, callbackPath+"/") {
+		return nil, xerrors.Errorf("redirect uri must be a subpath of the callback url %q", callbackURL)
+	}
+	return redirect, nil
+}
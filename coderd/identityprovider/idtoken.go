@@ -0,0 +1,80 @@
+package identityprovider
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// SigningKey signs the ID tokens issued to OAuth2 apps.
+type SigningKey struct {
+	id         string
+	privateKey ed25519.PrivateKey
+}
+
+// NewSigningKey derives an Ed25519 signing key from the deployment's OAuth
+// signing key, so every replica signs with the same key.
+func NewSigningKey(deploymentKey [32]byte) SigningKey {
+	seed := sha256.Sum256(append([]byte("coder oauth2 provider id token"), deploymentKey[:]...))
+	privateKey := ed25519.NewKeyFromSeed(seed[:])
+	keyID := sha256.Sum256(privateKey.Public().(ed25519.PublicKey))
+	return SigningKey{
+		id:         hex.EncodeToString(keyID[:8]),
+		privateKey: privateKey,
+	}
+}
+
+// JWKS returns the public half of the key as a JSON Web Key Set.
+func (k SigningKey) JWKS() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       k.privateKey.Public(),
+			KeyID:     k.id,
+			Algorithm: string(jose.EdDSA),
+			Use:       "sig",
+		}},
+	}
+}
+
+// IDTokenClaims are the claims included in ID tokens.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username"`
+	// Nonce is the nonce the app sent with the authorization request.
+	Nonce string `json:"nonce,omitempty"`
+}
+
+// signIDToken issues an ID token for the user to the app.
+func (k SigningKey) signIDToken(issuer string, appID string, user database.User, nonce string, lifetime time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{appID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(lifetime)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Email:             user.Email,
+		EmailVerified:     true,
+		Name:              user.Name,
+		PreferredUsername: user.Username,
+		Nonce:             nonce,
+	})
+	token.Header["kid"] = k.id
+	signed, err := token.SignedString(k.privateKey)
+	if err != nil {
+		return "", xerrors.Errorf("sign id token: %w", err)
+	}
+	return signed, nil
+}
@@ -0,0 +1,99 @@
+package identityprovider
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// DiscoveryResponse is served from the OpenID Connect discovery endpoint. See
+// https://openid.net/specs/openid-connect-discovery-1_0.html.
+type DiscoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// Discovery serves the OpenID Connect provider configuration.
+func Discovery(accessURL *url.URL) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, DiscoveryResponse{
+			Issuer:                            accessURL.String(),
+			AuthorizationEndpoint:             accessURL.JoinPath("/oauth2/authorize").String(),
+			TokenEndpoint:                     accessURL.JoinPath("/oauth2/tokens").String(),
+			UserinfoEndpoint:                  accessURL.JoinPath("/oauth2/userinfo").String(),
+			JWKSURI:                           accessURL.JoinPath("/oauth2/jwks").String(),
+			ResponseTypesSupported:            []string{"code"},
+			GrantTypesSupported:               []string{"authorization_code", "refresh_token"},
+			SubjectTypesSupported:             []string{"public"},
+			IDTokenSigningAlgValuesSupported:  []string{"EdDSA"},
+			ScopesSupported:                   []string{"openid", "email", "profile"},
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+			ClaimsSupported:                   []string{"sub", "email", "email_verified", "name", "preferred_username", "nonce"},
+			CodeChallengeMethodsSupported:     []string{"S256"},
+		})
+	}
+}
+
+// JWKS serves the keys ID tokens are signed with.
+func JWKS(key SigningKey) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, key.JWKS())
+	}
+}
+
+// UserInfoResponse is served from the userinfo endpoint.
+type UserInfoResponse struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username"`
+}
+
+// UserInfo returns the claims about the user the access token belongs to.
+func UserInfo(db database.Store) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		apiKey := httpmw.APIKey(r)
+		user, err := db.GetUserByID(ctx, apiKey.UserID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching user.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, UserInfoResponse{
+			Subject:           user.ID.String(),
+			Email:             user.Email,
+			EmailVerified:     true,
+			Name:              user.Name,
+			PreferredUsername: user.Username,
+		})
+	}
+}
+
+// SessionTokenFunc reads access tokens from the Authorization header, falling
+// back to the usual session token locations.
+func SessionTokenFunc(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return httpmw.APITokenFromRequest(r)
+}
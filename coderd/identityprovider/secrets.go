@@ -0,0 +1,82 @@
+package identityprovider
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// secret is an authorization code or refresh token handed to an OAuth2 app.
+type secret struct {
+	// Formatted is the value handed to the app, formatted as
+	// ${prefix}_${secret}.
+	Formatted string
+	// Prefix is used to look up the secret in the database.
+	Prefix string
+	// Hashed is the SHA256 hash of the secret half, which is what gets stored.
+	Hashed []byte
+}
+
+// generateSecret generates a new secret with a random lookup prefix.
+func generateSecret() (secret, error) {
+	// Prefixes only need to be long enough to be unique, while the secret is
+	// the same length as the client secrets.
+	prefix, err := cryptorand.String(10)
+	if err != nil {
+		return secret{}, xerrors.Errorf("generate prefix: %w", err)
+	}
+	raw, err := cryptorand.String(40)
+	if err != nil {
+		return secret{}, xerrors.Errorf("generate secret: %w", err)
+	}
+	hashed := sha256.Sum256([]byte(raw))
+	return secret{
+		Formatted: fmt.Sprintf("%s_%s", prefix, raw),
+		Prefix:    prefix,
+		Hashed:    hashed[:],
+	}, nil
+}
+
+// parsedSecret is a secret received back from an app.
+type parsedSecret struct {
+	prefix string
+	secret string
+}
+
+// parseSecret splits a formatted secret into its prefix and secret halves.
+func parseSecret(formatted string) (parsedSecret, error) {
+	prefix, raw, ok := strings.Cut(formatted, "_")
+	if !ok || prefix == "" || raw == "" {
+		return parsedSecret{}, xerrors.New("incorrectly formatted secret")
+	}
+	return parsedSecret{prefix: prefix, secret: raw}, nil
+}
+
+// matches reports whether the secret hashes to the stored hash.
+func (p parsedSecret) matches(hashed []byte) bool {
+	return hashMatches(p.secret, hashed)
+}
+
+// hashMatches compares the SHA256 hash of raw against hashed in constant time.
+func hashMatches(raw string, hashed []byte) bool {
+	sum := sha256.Sum256([]byte(raw))
+	return subtle.ConstantTimeCompare(sum[:], hashed) == 1
+}
+
+// verifyCodeChallenge reports whether the PKCE code verifier matches the S256
+// code challenge. See RFC 7636 section 4.6. A verifier must be sent if and only
+// if the authorization request had a challenge.
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if challenge == "" || verifier == "" {
+		return challenge == verifier
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,290 @@
+package identityprovider
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// accessTokenLifetime is how long access and ID tokens are valid for. Apps
+// use the refresh token to get new ones.
+const accessTokenLifetime = time.Hour
+
+// errInvalidGrant is returned when a code or refresh token is unknown,
+// expired, or was issued to a different app.
+var errInvalidGrant = xerrors.New("invalid grant")
+
+// TokenResponse is returned by the token endpoint. See RFC 6749 section 5.1.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+}
+
+// TokenError is returned by the token endpoint. See RFC 6749 section 5.2.
+type TokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// TokensConfig configures the token endpoint.
+type TokensConfig struct {
+	DB         database.Store
+	AccessURL  *url.URL
+	SigningKey SigningKey
+	// RefreshLifetime is how long refresh tokens are valid for. It matches the
+	// deployment's session duration.
+	RefreshLifetime time.Duration
+}
+
+// Tokens exchanges authorization codes and refresh tokens for access tokens.
+// The access tokens are regular API keys limited to reading the user.
+func Tokens(cfg TokensConfig) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		//nolint:gocritic // The app is authenticated by its client secret, not
+		// as a user.
+		ctx = dbauthz.AsSystemRestricted(ctx)
+
+		if err := r.ParseForm(); err != nil {
+			writeTokenError(ctx, rw, http.StatusBadRequest, "invalid_request", "Invalid form body.")
+			return
+		}
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID = r.PostForm.Get("client_id")
+			clientSecret = r.PostForm.Get("client_secret")
+		}
+		app, appSecret, err := authenticateClient(ctx, cfg.DB, clientID, clientSecret)
+		if err != nil {
+			writeTokenError(ctx, rw, http.StatusUnauthorized, "invalid_client", "Invalid client credentials.")
+			return
+		}
+
+		var (
+			userID uuid.UUID
+			nonce  string
+		)
+		switch grantType := r.PostForm.Get("grant_type"); grantType {
+		case "authorization_code":
+			userID, nonce, err = exchangeCode(ctx, cfg.DB, app, r.PostForm.Get("code"), r.PostForm.Get("code_verifier"))
+		case "refresh_token":
+			userID, err = exchangeRefreshToken(ctx, cfg.DB, app, r.PostForm.Get("refresh_token"))
+		default:
+			writeTokenError(ctx, rw, http.StatusBadRequest, "unsupported_grant_type", fmt.Sprintf("Grant type %q is not supported.", grantType))
+			return
+		}
+		if errors.Is(err, errInvalidGrant) {
+			writeTokenError(ctx, rw, http.StatusBadRequest, "invalid_grant", "The code or refresh token is invalid or expired.")
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+
+		resp, err := issueTokens(ctx, cfg, r, app, appSecret, userID, nonce)
+		if errors.Is(err, errInvalidGrant) {
+			writeTokenError(ctx, rw, http.StatusBadRequest, "invalid_grant", "The user is suspended or no longer exists.")
+			return
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+
+		rw.Header().Set("Cache-Control", "no-store")
+		rw.Header().Set("Pragma", "no-cache")
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+	}
+}
+
+func writeTokenError(ctx context.Context, rw http.ResponseWriter, status int, code string, description string) {
+	httpapi.Write(ctx, rw, status, TokenError{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
+
+// authenticateClient returns the app and the secret that matches the client
+// credentials.
+func authenticateClient(ctx context.Context, db database.Store, clientID string, clientSecret string) (database.OAuth2ProviderApp, database.OAuth2ProviderAppSecret, error) {
+	appID, err := uuid.Parse(clientID)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, xerrors.Errorf("parse client id: %w", err)
+	}
+	app, err := db.GetOAuth2ProviderAppByID(ctx, appID)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, xerrors.Errorf("get app: %w", err)
+	}
+	secrets, err := db.GetOAuth2ProviderAppSecretsByAppID(ctx, app.ID)
+	if err != nil {
+		return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, xerrors.Errorf("get app secrets: %w", err)
+	}
+	for _, secret := range secrets {
+		if !hashMatches(clientSecret, secret.HashedSecret) {
+			continue
+		}
+		secret, err = db.UpdateOAuth2ProviderAppSecretByID(ctx, database.UpdateOAuth2ProviderAppSecretByIDParams{
+			ID:         secret.ID,
+			LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		if err != nil {
+			return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, xerrors.Errorf("update app secret: %w", err)
+		}
+		return app, secret, nil
+	}
+	return database.OAuth2ProviderApp{}, database.OAuth2ProviderAppSecret{}, xerrors.New("no matching client secret")
+}
+
+// exchangeCode consumes an authorization code, returning the user and the
+// nonce it was issued for. If the app sent a PKCE code challenge, the verifier
+// must match it.
+func exchangeCode(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, formatted string, codeVerifier string) (uuid.UUID, string, error) {
+	parsed, err := parseSecret(formatted)
+	if err != nil {
+		return uuid.Nil, "", errInvalidGrant
+	}
+	code, err := db.GetOAuth2ProviderAppCodeByPrefix(ctx, []byte(parsed.prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, "", errInvalidGrant
+	}
+	if err != nil {
+		return uuid.Nil, "", xerrors.Errorf("get code: %w", err)
+	}
+	if code.AppID != app.ID || !parsed.matches(code.HashedSecret) {
+		return uuid.Nil, "", errInvalidGrant
+	}
+	// Codes can only be used once, so delete it whether or not it expired or
+	// the verifier matches. Only one request gets the deleted row back.
+	code, err = db.DeleteOAuth2ProviderAppCodeByID(ctx, code.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another request consumed the code first.
+		return uuid.Nil, "", errInvalidGrant
+	}
+	if err != nil {
+		return uuid.Nil, "", xerrors.Errorf("delete code: %w", err)
+	}
+	if dbtime.Now().After(code.ExpiresAt) || !verifyCodeChallenge(code.CodeChallenge, codeVerifier) {
+		return uuid.Nil, "", errInvalidGrant
+	}
+	return code.UserID, code.Nonce, nil
+}
+
+// exchangeRefreshToken consumes a refresh token, returning the user it was
+// issued for. The access token issued alongside it is revoked.
+func exchangeRefreshToken(ctx context.Context, db database.Store, app database.OAuth2ProviderApp, formatted string) (uuid.UUID, error) {
+	parsed, err := parseSecret(formatted)
+	if err != nil {
+		return uuid.Nil, errInvalidGrant
+	}
+	token, err := db.GetOAuth2ProviderAppTokenByPrefix(ctx, []byte(parsed.prefix))
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, errInvalidGrant
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get token: %w", err)
+	}
+	if !parsed.matches(token.RefreshHash) || dbtime.Now().After(token.ExpiresAt) {
+		return uuid.Nil, errInvalidGrant
+	}
+	secret, err := db.GetOAuth2ProviderAppSecretByID(ctx, token.AppSecretID)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get app secret: %w", err)
+	}
+	if secret.AppID != app.ID {
+		return uuid.Nil, errInvalidGrant
+	}
+	// Deleting the API key deletes the token along with it. Only one of
+	// several concurrent refreshes gets the key back; the others find it
+	// already gone.
+	key, err := db.DeleteAPIKeyByIDReturning(ctx, token.APIKeyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, errInvalidGrant
+	}
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("delete api key: %w", err)
+	}
+	return key.UserID, nil
+}
+
+// issueTokens creates an access token, refresh token and ID token for the
+// user. The nonce is only set when exchanging an authorization code.
+func issueTokens(ctx context.Context, cfg TokensConfig, r *http.Request, app database.OAuth2ProviderApp, appSecret database.OAuth2ProviderAppSecret, userID uuid.UUID, nonce string) (TokenResponse, error) {
+	user, err := cfg.DB.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return TokenResponse{}, errInvalidGrant
+	}
+	if err != nil {
+		return TokenResponse{}, xerrors.Errorf("get user: %w", err)
+	}
+	if user.Deleted || user.Status == database.UserStatusSuspended {
+		return TokenResponse{}, errInvalidGrant
+	}
+
+	keyParams, accessToken, err := apikey.Generate(apikey.CreateParams{
+		UserID:          user.ID,
+		LoginType:       database.LoginTypeOAuth2ProviderApp,
+		LifetimeSeconds: int64(accessTokenLifetime.Seconds()),
+		TokenName:       fmt.Sprintf("%s_oauth2_app", app.ID),
+		RemoteAddr:      r.RemoteAddr,
+		Scopes:          []rbac.ScopeName{rbac.ScopeUserRead},
+	})
+	if err != nil {
+		return TokenResponse{}, xerrors.Errorf("generate api key: %w", err)
+	}
+	refreshToken, err := generateSecret()
+	if err != nil {
+		return TokenResponse{}, xerrors.Errorf("generate refresh token: %w", err)
+	}
+	err = cfg.DB.InTx(func(tx database.Store) error {
+		key, err := tx.InsertAPIKey(ctx, keyParams)
+		if err != nil {
+			return xerrors.Errorf("insert api key: %w", err)
+		}
+		_, err = tx.InsertOAuth2ProviderAppToken(ctx, database.InsertOAuth2ProviderAppTokenParams{
+			ID:          uuid.New(),
+			CreatedAt:   dbtime.Now(),
+			ExpiresAt:   dbtime.Now().Add(cfg.RefreshLifetime),
+			HashPrefix:  []byte(refreshToken.Prefix),
+			RefreshHash: refreshToken.Hashed,
+			AppSecretID: appSecret.ID,
+			APIKeyID:    key.ID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert oauth2 app token: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	idToken, err := cfg.SigningKey.signIDToken(cfg.AccessURL.String(), app.ID.String(), user, nonce, accessTokenLifetime)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	return TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTokenLifetime.Seconds()),
+		RefreshToken: refreshToken.Formatted,
+		IDToken:      idToken,
+	}, nil
+}
@@ -0,0 +1,218 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"nhooyr.io/websocket"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get user inbox notifications
+// @ID get-user-inbox-notifications
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param unread_only query bool false "Only return unread notifications"
+// @Param after_id query string false "After ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {object} codersdk.InboxNotificationsResponse
+// @Router /users/{user}/inbox [get]
+func (api *API) userInboxNotifications(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	paginationParams, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+	parser := httpapi.NewQueryParamParser()
+	unreadOnly := parser.Boolean(r.URL.Query(), false, "unread_only")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	notifs, err := api.Database.GetInboxNotificationsByUserID(ctx, database.GetInboxNotificationsByUserIDParams{
+		UserID:     user.ID,
+		AfterID:    paginationParams.AfterID,
+		UnreadOnly: unreadOnly,
+		OffsetOpt:  int32(paginationParams.Offset),
+		LimitOpt:   int32(paginationParams.Limit),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching inbox notifications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	unread, err := api.Database.CountUnreadInboxNotificationsByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error counting unread inbox notifications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.InboxNotificationsResponse{
+		Notifications: db2sdk.InboxNotifications(notifs),
+		UnreadCount:   unread,
+	})
+}
+
+// @Summary Mark user inbox notifications as read
+// @ID mark-user-inbox-notifications-as-read
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.MarkInboxNotificationsReadRequest true "Notifications to mark as read"
+// @Success 204
+// @Router /users/{user}/inbox/read [put]
+func (api *API) markUserInboxNotificationsRead(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.MarkInboxNotificationsReadRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateInboxNotificationsReadByUserID(ctx, database.UpdateInboxNotificationsReadByUserIDParams{
+		ReadAt: dbtime.Now(),
+		UserID: user.ID,
+		IDs:    req.IDs,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error marking inbox notifications as read.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Watch user inbox notifications
+// @Description It accepts a WebSocket connection and writes an event for
+// @Description every notification that is added to the inbox of the user.
+// @ID watch-user-inbox-notifications
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 101
+// @Router /users/{user}/inbox/watch [get]
+func (api *API) watchUserInboxNotifications(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		logger = api.Logger.Named("inbox_watcher").With(slog.F("user_id", user.ID))
+	)
+
+	// Watching the inbox requires the same permission as reading it, and the
+	// unread count is sent with every event anyway.
+	_, err := api.Database.CountUnreadInboxNotificationsByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error counting unread inbox notifications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	notifs := make(chan database.InboxNotification, 8)
+	cancelSubscribe, err := api.Pubsub.Subscribe(notifications.InboxNotifyChannel(user.ID), func(_ context.Context, message []byte) {
+		var notif database.InboxNotification
+		err := json.Unmarshal(message, &notif)
+		if err != nil {
+			logger.Warn(ctx, "unmarshal inbox notification", slog.Error(err))
+			return
+		}
+		select {
+		case notifs <- notif:
+		default:
+			// The watcher is not keeping up. The notification is still
+			// listed in the inbox.
+		}
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to subscribe to inbox notifications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer cancelSubscribe()
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	defer api.WebsocketWaitGroup.Done()
+
+	conn, err := websocket.Accept(rw, r, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to accept websocket.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	go httpapi.Heartbeat(ctx, conn)
+
+	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageText)
+	defer wsNetConn.Close() // Also closes conn.
+
+	encoder := json.NewEncoder(wsNetConn)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notif := <-notifs:
+			unread, err := api.Database.CountUnreadInboxNotificationsByUserID(ctx, user.ID)
+			if err != nil {
+				logger.Warn(ctx, "count unread inbox notifications", slog.Error(err))
+				return
+			}
+			err = encoder.Encode(codersdk.InboxNotificationEvent{
+				Notification: db2sdk.InboxNotification(notif),
+				UnreadCount:  unread,
+			})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestUserInboxNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ListAndMarkRead", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		inbox, err := member.InboxNotifications(ctx, codersdk.Me, codersdk.InboxNotificationsRequest{})
+		require.NoError(t, err)
+		require.Len(t, inbox.Notifications, 1)
+		require.EqualValues(t, 1, inbox.UnreadCount)
+		notif := inbox.Notifications[0]
+		require.Equal(t, codersdk.NotificationTemplateUserAccountCreated, notif.Template)
+		require.Equal(t, "Your Coder account has been created", notif.Title)
+		require.Equal(t, coderdtest.FirstUserParams.Username+" created a Coder account for you.", notif.Content)
+		require.Nil(t, notif.ReadAt)
+
+		err = member.MarkInboxNotificationsRead(ctx, codersdk.Me, codersdk.MarkInboxNotificationsReadRequest{
+			IDs: []uuid.UUID{notif.ID},
+		})
+		require.NoError(t, err)
+
+		inbox, err = member.InboxNotifications(ctx, codersdk.Me, codersdk.InboxNotificationsRequest{})
+		require.NoError(t, err)
+		require.Len(t, inbox.Notifications, 1)
+		require.Zero(t, inbox.UnreadCount)
+		require.NotNil(t, inbox.Notifications[0].ReadAt)
+
+		inbox, err = member.InboxNotifications(ctx, codersdk.Me, codersdk.InboxNotificationsRequest{UnreadOnly: true})
+		require.NoError(t, err)
+		require.Empty(t, inbox.Notifications)
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api := coderdtest.NewWithAPI(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		events, stream, err := member.WatchInboxNotifications(ctx, codersdk.Me)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = stream.Close() })
+
+		api.Notifications.Enqueue(context.Background(), memberUser.ID, codersdk.NotificationTemplateWorkspaceBuildFailed, map[string]string{
+			"workspace_name": "dev",
+			"build_number":   "2",
+			"transition":     "start",
+		})
+
+		event := testutil.RequireRecvCtx(ctx, t, events)
+		require.Equal(t, codersdk.NotificationTemplateWorkspaceBuildFailed, event.Notification.Template)
+		require.Equal(t, `Workspace "dev" failed to start`, event.Notification.Title)
+		// The account created notification is still unread.
+		require.EqualValues(t, 2, event.UnreadCount)
+	})
+
+	t.Run("OtherUserNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The user param middleware rejects users the member can't see.
+		_, err := member.InboxNotifications(ctx, owner.UserID.String(), codersdk.InboxNotificationsRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, _, err = member.WatchInboxNotifications(ctx, owner.UserID.String())
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
@@ -410,6 +411,159 @@ func (api *API) insightsTemplates(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about a template
+// @ID get-insights-about-a-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param template path string true "Template ID" format(uuid)
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Success 200 {object} codersdk.TemplateSummaryInsightsResponse
+// @Router /templates/{template}/insights [get]
+func (api *API) templateSummaryInsights(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	p := httpapi.NewQueryParamParser().
+		Required("start_time").
+		Required("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, time.Now(), startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	templateIDs := []uuid.UUID{template.ID}
+	var builds database.GetTemplateBuildInsightsRow
+	var dailyUsage []database.GetTemplateInsightsByIntervalRow
+	var parameterRows []database.GetTemplateParameterInsightsRow
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(3)
+
+	eg.Go(func() error {
+		var err error
+		builds, err = api.Database.GetTemplateBuildInsights(egCtx, database.GetTemplateBuildInsightsParams{
+			TemplateID: template.ID,
+			StartTime:  startTime,
+			EndTime:    endTime,
+		})
+		if err != nil {
+			return xerrors.Errorf("get template build insights: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		var err error
+		dailyUsage, err = api.Database.GetTemplateInsightsByInterval(egCtx, database.GetTemplateInsightsByIntervalParams{
+			StartTime:    startTime,
+			EndTime:      endTime,
+			TemplateIDs:  templateIDs,
+			IntervalDays: codersdk.InsightsReportIntervalDay.Days(),
+		})
+		if err != nil {
+			return xerrors.Errorf("get template daily insights: %w", err)
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		var err error
+		parameterRows, err = api.Database.GetTemplateParameterInsights(egCtx, database.GetTemplateParameterInsightsParams{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: templateIDs,
+		})
+		if err != nil {
+			return xerrors.Errorf("get template parameter insights: %w", err)
+		}
+		return nil
+	})
+
+	err := eg.Wait()
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	parametersUsage, err := db2sdk.TemplateInsightsParameters(parameterRows)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template parameter insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.TemplateSummaryInsightsResponse{
+		TemplateID:       template.ID,
+		StartTime:        startTime,
+		EndTime:          endTime,
+		Builds:           convertTemplateBuildInsights(builds),
+		DailyActiveUsers: make([]codersdk.TemplateInsightsIntervalReport, 0, len(dailyUsage)),
+		ParametersUsage:  parametersUsage,
+	}
+	for _, row := range dailyUsage {
+		resp.DailyActiveUsers = append(resp.DailyActiveUsers, codersdk.TemplateInsightsIntervalReport{
+			StartTime:   row.StartTime.In(startTime.Location()),
+			EndTime:     row.EndTime.In(startTime.Location()),
+			Interval:    codersdk.InsightsReportIntervalDay,
+			TemplateIDs: row.TemplateIDs,
+			ActiveUsers: row.ActiveUsers,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// convertTemplateBuildInsights converts the build time percentiles from
+// seconds to milliseconds and computes the success rate. Percentiles are left
+// unset when no build succeeded.
+func convertTemplateBuildInsights(row database.GetTemplateBuildInsightsRow) codersdk.TemplateBuildInsights {
+	convertMillis := func(sec float64) *int64 {
+		if sec < 0 {
+			return nil
+		}
+		ms := int64(sec * 1000)
+		return &ms
+	}
+
+	var successRate float64
+	if row.TotalBuilds > 0 {
+		successRate = float64(row.TotalBuilds-row.FailedBuilds) / float64(row.TotalBuilds)
+	}
+	return codersdk.TemplateBuildInsights{
+		TotalBuilds:  row.TotalBuilds,
+		FailedBuilds: row.FailedBuilds,
+		SuccessRate:  successRate,
+		BuildTime: codersdk.TransitionStats{
+			P50: convertMillis(row.BuildTimeP50),
+			P95: convertMillis(row.BuildTimeP95),
+		},
+	}
+}
+
 func convertTemplateInsightsTemplateIDs(usage database.GetTemplateInsightsRow, appUsage []database.GetTemplateAppInsightsRow) []uuid.UUID {
 	templateIDSet := make(map[uuid.UUID]struct{})
 	for _, id := range usage.TemplateIDs {
@@ -520,6 +674,7 @@ func convertTemplateInsightsApps(usage database.GetTemplateInsightsRow, appUsage
 		if !app.IsApp {
 			continue
 		}
+		lastUsedAt := app.LastUsedAt
 		apps = append(apps, codersdk.TemplateAppUsage{
 			TemplateIDs: app.TemplateIDs,
 			Type:        codersdk.TemplateAppsTypeApp,
@@ -527,6 +682,9 @@ func convertTemplateInsightsApps(usage database.GetTemplateInsightsRow, appUsage
 			Slug:        app.SlugOrPort,
 			Icon:        app.Icon.String,
 			Seconds:     app.UsageSeconds,
+			Requests:    app.Requests,
+			ActiveUsers: int64(len(app.ActiveUserIDs)),
+			LastUsedAt:  &lastUsedAt,
 		})
 	}
 
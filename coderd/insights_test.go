@@ -2295,3 +2295,86 @@ func TestGenericInsights_RBAC(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateSummaryInsights(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Builds", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		y, m, d := time.Now().UTC().Date()
+		today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.TemplateSummaryInsights(ctx, template.ID, codersdk.TemplateSummaryInsightsRequest{
+			StartTime: today,
+			EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+		})
+		require.NoError(t, err)
+		require.Equal(t, template.ID, resp.TemplateID)
+		require.Equal(t, int64(1), resp.Builds.TotalBuilds)
+		require.Equal(t, int64(0), resp.Builds.FailedBuilds)
+		require.Equal(t, 1.0, resp.Builds.SuccessRate)
+		require.NotNil(t, resp.Builds.BuildTime.P50)
+		require.NotNil(t, resp.Builds.BuildTime.P95)
+		require.Len(t, resp.DailyActiveUsers, 1)
+		require.NotNil(t, resp.ParametersUsage)
+	})
+
+	t.Run("NoBuilds", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		y, m, d := time.Now().UTC().Date()
+		today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		resp, err := client.TemplateSummaryInsights(ctx, template.ID, codersdk.TemplateSummaryInsightsRequest{
+			StartTime: today.AddDate(0, 0, -7),
+			EndTime:   today,
+		})
+		require.NoError(t, err)
+		require.Zero(t, resp.Builds.TotalBuilds)
+		require.Zero(t, resp.Builds.SuccessRate)
+		require.Nil(t, resp.Builds.BuildTime.P50)
+		require.Nil(t, resp.Builds.BuildTime.P95)
+		require.Len(t, resp.DailyActiveUsers, 7)
+	})
+
+	t.Run("AsRegularUser", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		regular, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+		y, m, d := time.Now().UTC().Date()
+		today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		_, err := regular.TemplateSummaryInsights(ctx, template.ID, codersdk.TemplateSummaryInsightsRequest{
+			StartTime: today.AddDate(0, 0, -1),
+			EndTime:   today,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,72 @@
+package ldapauth
+
+import (
+	"context"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+// conn is a connection to the server. Requests are sent one at a time, so
+// a conn must not be used concurrently.
+type conn struct {
+	ldap *ldap.Conn
+	// broken is set once the connection can't be reused, such as after a
+	// network error.
+	broken bool
+}
+
+// do runs a request of fn on the connection. go-ldap requests can't be
+// canceled, so the connection is closed to end the request once ctx is done.
+func (c *conn) do(ctx context.Context, fn func(l *ldap.Conn) error) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.ldap.Close()
+	})
+	err := fn(c.ldap)
+	if !stop() {
+		c.broken = true
+		return xerrors.Errorf("request canceled: %w", ctx.Err())
+	}
+	if ldap.IsErrorWithCode(err, ldap.ErrorNetwork) || c.ldap.IsClosing() {
+		c.broken = true
+	}
+	return err
+}
+
+// bind authenticates the connection with a simple bind. An empty DN and
+// password is an anonymous bind.
+func (c *conn) bind(ctx context.Context, dn, password string) error {
+	return c.do(ctx, func(l *ldap.Conn) error {
+		_, err := l.SimpleBind(&ldap.SimpleBindRequest{
+			Username:           dn,
+			Password:           password,
+			AllowEmptyPassword: dn == "" && password == "",
+		})
+		return err
+	})
+}
+
+// search returns the entries found by req. Exceeding the size limit still
+// returns the entries found up to the limit.
+func (c *conn) search(ctx context.Context, req *ldap.SearchRequest) ([]*ldap.Entry, error) {
+	var entries []*ldap.Entry
+	err := c.do(ctx, func(l *ldap.Conn) error {
+		res, err := l.Search(req)
+		if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return err
+		}
+		if res != nil {
+			entries = res.Entries
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// close sends an unbind request and closes the connection.
+func (c *conn) close() error {
+	if c.broken {
+		return c.ldap.Close()
+	}
+	return c.ldap.Unbind()
+}
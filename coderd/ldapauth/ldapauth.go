@@ -0,0 +1,386 @@
+// Package ldapauth authenticates users against an LDAP directory, like
+// OpenLDAP or Active Directory. Users are found with a search as a service
+// account, and their password is checked by binding as them. The protocol is
+// spoken by github.com/go-ldap/ldap.
+package ldapauth
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultUserFilter        = "(uid=%s)"
+	defaultUsernameAttribute = "uid"
+	defaultEmailAttribute    = "mail"
+	defaultPoolSize          = 4
+	defaultTimeout           = 10 * time.Second
+
+	// userAccountControl is the Active Directory attribute with the flags of
+	// an account.
+	userAccountControl = "userAccountControl"
+	// accountDisable is the userAccountControl flag of disabled accounts.
+	accountDisable = 0x2
+)
+
+var (
+	// ErrInvalidCredentials is returned when the username doesn't exist or
+	// the password is wrong.
+	ErrInvalidCredentials = xerrors.New("invalid username or password")
+	// ErrNotFound is returned when a user doesn't exist in the directory.
+	ErrNotFound = xerrors.New("user not found")
+)
+
+// Config configures an Authenticator.
+type Config struct {
+	// URL of the server, like ldaps://ldap.example.com. The port defaults to
+	// 389 for ldap:// and 636 for ldaps://.
+	URL string
+	// StartTLS upgrades ldap:// connections to TLS before binding.
+	StartTLS bool
+	// TLSConfig is used for ldaps:// and StartTLS. Defaults to verifying the
+	// server with the system roots.
+	TLSConfig *tls.Config
+	// BindDN and BindPassword are the service account that users are
+	// searched with. Searches are anonymous if BindDN is empty.
+	BindDN       string
+	BindPassword string
+	// SearchBase is the DN that users are searched under.
+	SearchBase string
+	// UserFilter finds the user that is logging in, with %s replaced by the
+	// escaped username. Defaults to "(uid=%s)".
+	UserFilter string
+	// UsernameAttribute is the attribute the Coder username is taken from.
+	// Defaults to "uid".
+	UsernameAttribute string
+	// EmailAttribute is the attribute the email is taken from. Defaults to
+	// "mail".
+	EmailAttribute string
+	// GroupsAttribute lists the groups of a user, like "memberOf". Groups
+	// aren't returned if it's empty.
+	GroupsAttribute string
+	// PoolSize is how many idle connections are kept open. Defaults to 4.
+	PoolSize int
+	// Timeout applies to dialing and to every request. Defaults to 10
+	// seconds.
+	Timeout time.Duration
+}
+
+// User is a user entry in the directory.
+type User struct {
+	DN       string
+	Username string
+	Email    string
+	// Groups are the names of the groups the user is a member of, taken
+	// from the first RDN of each group DN.
+	Groups []string
+	// Disabled is true for disabled Active Directory accounts.
+	Disabled bool
+}
+
+// Authenticator checks the credentials of users against the directory. It is
+// safe for concurrent use.
+type Authenticator struct {
+	cfg       Config
+	address   string
+	tls       bool
+	tlsConfig *tls.Config
+	pool      *pool
+}
+
+// New validates the config and returns an Authenticator. Connections are
+// dialed when they are first needed.
+func New(cfg Config) (*Authenticator, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	port := u.Port()
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		if cfg.StartTLS {
+			return nil, xerrors.New("StartTLS can't be used with ldaps://")
+		}
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, xerrors.Errorf("url scheme must be ldap or ldaps, got %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, xerrors.New("url must have a host")
+	}
+
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = defaultUserFilter
+	}
+	if !strings.Contains(cfg.UserFilter, "%s") {
+		return nil, xerrors.Errorf("user filter %q must contain %%s", cfg.UserFilter)
+	}
+	_, err = ldap.CompileFilter(userFilter(cfg.UserFilter, "user"))
+	if err != nil {
+		return nil, xerrors.Errorf("user filter: %w", err)
+	}
+	if cfg.UsernameAttribute == "" {
+		cfg.UsernameAttribute = defaultUsernameAttribute
+	}
+	if cfg.EmailAttribute == "" {
+		cfg.EmailAttribute = defaultEmailAttribute
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = defaultPoolSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.TLSConfig != nil {
+		tlsConfig = cfg.TLSConfig.Clone()
+	} else {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	a := &Authenticator{
+		cfg:       cfg,
+		address:   net.JoinHostPort(u.Hostname(), port),
+		tls:       u.Scheme == "ldaps",
+		tlsConfig: tlsConfig,
+	}
+	a.pool = newPool(cfg.PoolSize, a.dial)
+	return a, nil
+}
+
+// Authenticate finds the user with the given username and checks their
+// password. ErrInvalidCredentials is returned if the user doesn't exist or
+// the password is wrong.
+func (a *Authenticator) Authenticate(ctx context.Context, username, password string) (User, error) {
+	// Servers treat a bind with an empty password as an anonymous bind,
+	// which succeeds without checking anything.
+	if username == "" || password == "" {
+		return User{}, ErrInvalidCredentials
+	}
+
+	var user User
+	err := a.withConn(ctx, func(c *conn) error {
+		e, err := a.search(ctx, c, userFilter(a.cfg.UserFilter, ldap.EscapeFilter(username)))
+		if xerrors.Is(err, ErrNotFound) {
+			return ErrInvalidCredentials
+		}
+		if err != nil {
+			return err
+		}
+
+		bindErr := c.bind(ctx, e.DN, password)
+		// The connection is now bound as the user, or is anonymous if the
+		// bind failed, so it's bound as the service account again before
+		// it's reused.
+		serviceErr := a.bindService(ctx, c)
+		if serviceErr != nil {
+			c.broken = true
+		}
+		if ldap.IsErrorWithCode(bindErr, ldap.LDAPResultInvalidCredentials) {
+			return ErrInvalidCredentials
+		}
+		if bindErr != nil {
+			return xerrors.Errorf("bind as user: %w", bindErr)
+		}
+		if serviceErr != nil {
+			return xerrors.Errorf("bind as service account: %w", serviceErr)
+		}
+		user = a.user(e)
+		return nil
+	})
+	return user, err
+}
+
+// Lookup returns the user with the given DN, or ErrNotFound if the entry
+// doesn't exist.
+func (a *Authenticator) Lookup(ctx context.Context, dn string) (User, error) {
+	var user User
+	err := a.withConn(ctx, func(c *conn) error {
+		entries, err := c.search(ctx, a.searchRequest(dn, ldap.ScopeBaseObject, "(objectClass=*)", 1))
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return xerrors.Errorf("search for %q: %w", dn, err)
+		}
+		// The entry exists, since the search didn't fail, so it must be
+		// hidden from the service account.
+		if len(entries) == 0 {
+			return xerrors.Errorf("entry %q can't be read by the service account", dn)
+		}
+		user = a.user(entries[0])
+		return nil
+	})
+	return user, err
+}
+
+// LookupEmail returns the user with the given email under the search base,
+// or ErrNotFound if there is none.
+func (a *Authenticator) LookupEmail(ctx context.Context, email string) (User, error) {
+	var user User
+	err := a.withConn(ctx, func(c *conn) error {
+		e, err := a.search(ctx, c, "("+a.cfg.EmailAttribute+"="+ldap.EscapeFilter(email)+")")
+		if err != nil {
+			return err
+		}
+		user = a.user(e)
+		return nil
+	})
+	return user, err
+}
+
+// GroupsEnabled returns whether the groups of users are returned.
+func (a *Authenticator) GroupsEnabled() bool {
+	return a.cfg.GroupsAttribute != ""
+}
+
+// Close closes the idle connections.
+func (a *Authenticator) Close() {
+	a.pool.close()
+}
+
+// search returns the single entry under the search base that matches the
+// filter.
+func (a *Authenticator) search(ctx context.Context, c *conn, filter string) (*ldap.Entry, error) {
+	// Two entries are enough to tell that the filter is ambiguous.
+	entries, err := c.search(ctx, a.searchRequest(a.cfg.SearchBase, ldap.ScopeWholeSubtree, filter, 2))
+	if err != nil {
+		return nil, xerrors.Errorf("search for user: %w", err)
+	}
+	switch len(entries) {
+	case 0:
+		return nil, ErrNotFound
+	case 1:
+		return entries[0], nil
+	default:
+		return nil, xerrors.Errorf("filter %q matches more than one entry", filter)
+	}
+}
+
+func (a *Authenticator) searchRequest(baseDN string, scope int, filter string, sizeLimit int) *ldap.SearchRequest {
+	return ldap.NewSearchRequest(baseDN, scope, ldap.NeverDerefAliases, sizeLimit,
+		int(a.cfg.Timeout/time.Second), false, filter, a.attributes(), nil)
+}
+
+func (a *Authenticator) attributes() []string {
+	attrs := []string{a.cfg.UsernameAttribute, a.cfg.EmailAttribute, userAccountControl}
+	if a.cfg.GroupsAttribute != "" {
+		attrs = append(attrs, a.cfg.GroupsAttribute)
+	}
+	return attrs
+}
+
+// user converts an entry to a User. Attribute names are case insensitive.
+func (a *Authenticator) user(e *ldap.Entry) User {
+	user := User{
+		DN:       e.DN,
+		Username: e.GetEqualFoldAttributeValue(a.cfg.UsernameAttribute),
+		Email:    e.GetEqualFoldAttributeValue(a.cfg.EmailAttribute),
+	}
+	if a.cfg.GroupsAttribute != "" {
+		for _, group := range e.GetEqualFoldAttributeValues(a.cfg.GroupsAttribute) {
+			user.Groups = append(user.Groups, groupName(group))
+		}
+	}
+	flags, err := strconv.ParseInt(e.GetEqualFoldAttributeValue(userAccountControl), 10, 64)
+	if err == nil {
+		user.Disabled = flags&accountDisable != 0
+	}
+	return user
+}
+
+// withConn runs fn with a connection from the pool. Idle connections may have
+// been closed by the server, so fn is retried once on a new connection if an
+// idle one turns out to be broken.
+func (a *Authenticator) withConn(ctx context.Context, fn func(c *conn) error) error {
+	c, pooled, err := a.pool.get(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(c)
+	if err != nil && c.broken && pooled {
+		_ = c.close()
+		c, err = a.dial(ctx)
+		if err != nil {
+			return err
+		}
+		err = fn(c)
+	}
+	a.pool.put(c)
+	return err
+}
+
+// dial opens a connection bound as the service account.
+func (a *Authenticator) dial(ctx context.Context) (*conn, error) {
+	dialer := &net.Dialer{Timeout: a.cfg.Timeout}
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if a.tls {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: a.tlsConfig}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", a.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", a.address)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("dial %s: %w", a.address, err)
+	}
+
+	l := ldap.NewConn(netConn, a.tls)
+	l.Start()
+	l.SetTimeout(a.cfg.Timeout)
+	c := &conn{ldap: l}
+	if a.cfg.StartTLS {
+		err = c.do(ctx, func(l *ldap.Conn) error {
+			return l.StartTLS(a.tlsConfig)
+		})
+		if err != nil {
+			_ = c.close()
+			return nil, xerrors.Errorf("start tls: %w", err)
+		}
+	}
+	err = a.bindService(ctx, c)
+	if err != nil {
+		_ = c.close()
+		return nil, xerrors.Errorf("bind as service account: %w", err)
+	}
+	return c, nil
+}
+
+func (a *Authenticator) bindService(ctx context.Context, c *conn) error {
+	return c.bind(ctx, a.cfg.BindDN, a.cfg.BindPassword)
+}
+
+func userFilter(filter, username string) string {
+	return strings.ReplaceAll(filter, "%s", username)
+}
+
+// groupName returns the value of the first RDN of a group DN, so
+// "cn=developers,ou=groups,dc=example,dc=com" becomes "developers". Values
+// that aren't DNs are returned as they are.
+func groupName(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return dn
+	}
+	return parsed.RDNs[0].Attributes[0].Value
+}
@@ -0,0 +1,360 @@
+package ldapauth
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGroupName(t *testing.T) {
+	t.Parallel()
+
+	for dn, name := range map[string]string{
+		"cn=developers,ou=groups,dc=example,dc=com": "developers",
+		`CN=Smith\, John,OU=Groups`:                 "Smith, John",
+		`cn=a\2Cb,dc=example`:                       "a,b",
+		"developers":                                "developers",
+		"":                                          "",
+	} {
+		require.Equal(t, name, groupName(dn), dn)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	srv := newFakeServer(t, map[string]fakeEntry{
+		"cn=service,dc=example,dc=com": {password: "service-password"},
+		"uid=alice,ou=people,dc=example,dc=com": {password: "alice-password", attrs: map[string][]string{
+			"uid":      {"alice"},
+			"mail":     {"alice@example.com"},
+			"memberOf": {"cn=developers,ou=groups,dc=example,dc=com", "cn=admins,ou=groups,dc=example,dc=com"},
+		}},
+		"uid=bob,ou=people,dc=example,dc=com": {password: "bob-password", attrs: map[string][]string{
+			"uid":                {"bob"},
+			"mail":               {"bob@example.com"},
+			"userAccountControl": {"514"},
+		}},
+	})
+	auth, err := New(Config{
+		URL:             "ldap://" + srv.addr(),
+		BindDN:          "cn=service,dc=example,dc=com",
+		BindPassword:    "service-password",
+		SearchBase:      "ou=people,dc=example,dc=com",
+		GroupsAttribute: "memberOf",
+	})
+	require.NoError(t, err)
+	t.Cleanup(auth.Close)
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	user, err := auth.Authenticate(ctx, "alice", "alice-password")
+	require.NoError(t, err)
+	require.Equal(t, User{
+		DN:       "uid=alice,ou=people,dc=example,dc=com",
+		Username: "alice",
+		Email:    "alice@example.com",
+		Groups:   []string{"developers", "admins"},
+	}, user)
+
+	user, err = auth.Authenticate(ctx, "bob", "bob-password")
+	require.NoError(t, err)
+	require.True(t, user.Disabled)
+
+	for _, creds := range [][2]string{
+		{"alice", "wrong"},
+		{"alice", ""},
+		{"carol", "carol-password"},
+		{"*", "alice-password"},
+	} {
+		_, err = auth.Authenticate(ctx, creds[0], creds[1])
+		require.ErrorIs(t, err, ErrInvalidCredentials, creds[0])
+	}
+
+	// Every request reused the connection bound as the service account.
+	require.EqualValues(t, 1, srv.dials.Load())
+
+	// Idle connections closed by the server are replaced.
+	srv.closeConns()
+	_, err = auth.Authenticate(ctx, "alice", "alice-password")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, srv.dials.Load())
+
+	user, err = auth.Lookup(ctx, "uid=alice,ou=people,dc=example,dc=com")
+	require.NoError(t, err)
+	require.Equal(t, "alice", user.Username)
+	_, err = auth.Lookup(ctx, "uid=carol,ou=people,dc=example,dc=com")
+	require.ErrorIs(t, err, ErrNotFound)
+	user, err = auth.LookupEmail(ctx, "bob@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "bob", user.Username)
+
+	_, err = New(Config{URL: "http://" + srv.addr()})
+	require.Error(t, err)
+	_, err = New(Config{URL: "ldaps://" + srv.addr(), StartTLS: true})
+	require.Error(t, err)
+	_, err = New(Config{URL: "ldap://" + srv.addr(), UserFilter: "(uid=alice)"})
+	require.Error(t, err)
+}
+
+func TestSyncUsers(t *testing.T) {
+	t.Parallel()
+
+	srv := newFakeServer(t, map[string]fakeEntry{
+		"uid=alice,ou=people,dc=example,dc=com": {attrs: map[string][]string{
+			"uid":  {"alice"},
+			"mail": {"alice@example.com"},
+		}},
+		// Carol was moved to another OU since she last logged in.
+		"uid=carol,ou=admins,dc=example,dc=com": {attrs: map[string][]string{
+			"uid":  {"carol"},
+			"mail": {"carol@example.com"},
+		}},
+		"uid=dave,ou=people,dc=example,dc=com": {attrs: map[string][]string{
+			"uid":                {"dave"},
+			"mail":               {"dave@example.com"},
+			"userAccountControl": {"514"},
+		}},
+	})
+	auth, err := New(Config{URL: "ldap://" + srv.addr(), SearchBase: "dc=example,dc=com"})
+	require.NoError(t, err)
+	t.Cleanup(auth.Close)
+
+	db := dbmem.New()
+	ldapUser := func(email, dn string) database.User {
+		user := dbgen.User(t, db, database.User{Email: email, LoginType: database.LoginTypeLDAP})
+		dbgen.UserLink(t, db, database.UserLink{UserID: user.ID, LoginType: database.LoginTypeLDAP, LinkedID: dn})
+		return user
+	}
+	alice := ldapUser("alice@example.com", "uid=alice,ou=people,dc=example,dc=com")
+	bob := ldapUser("bob@example.com", "uid=bob,ou=people,dc=example,dc=com")
+	carol := ldapUser("carol@example.com", "uid=carol,ou=people,dc=example,dc=com")
+	dave := ldapUser("dave@example.com", "uid=dave,ou=people,dc=example,dc=com")
+	// Users of other login types aren't in the directory.
+	password := dbgen.User(t, db, database.User{})
+
+	mockAuditor := audit.NewMock()
+	var auditor atomic.Pointer[audit.Auditor]
+	var a audit.Auditor = mockAuditor
+	auditor.Store(&a)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	suspended, err := syncUsers(ctx, slogtest.Make(t, nil), db, auth, &auditor)
+	require.NoError(t, err)
+	require.Equal(t, 2, suspended)
+
+	for _, want := range []struct {
+		user   database.User
+		status database.UserStatus
+	}{
+		{alice, database.UserStatusActive},
+		{bob, database.UserStatusSuspended},
+		{carol, database.UserStatusActive},
+		{dave, database.UserStatusSuspended},
+		{password, database.UserStatusActive},
+	} {
+		user, err := db.GetUserByID(ctx, want.user.ID)
+		require.NoError(t, err)
+		require.Equal(t, want.status, user.Status, want.user.Email)
+	}
+	require.Len(t, mockAuditor.AuditLogs(), 2)
+}
+
+type fakeEntry struct {
+	password string
+	attrs    map[string][]string
+}
+
+// fakeServer is an LDAP server that answers binds and searches from a fixed
+// set of entries. Anonymous binds are allowed.
+type fakeServer struct {
+	t        *testing.T
+	listener net.Listener
+	entries  map[string]fakeEntry
+	dials    atomic.Int64
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newFakeServer(t *testing.T, entries map[string]fakeEntry) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &fakeServer{t: t, listener: listener, entries: entries}
+	t.Cleanup(func() {
+		_ = listener.Close()
+		s.closeConns()
+	})
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.dials.Add(1)
+			s.mu.Lock()
+			s.conns = append(s.conns, c)
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeServer) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeServer) serve(c net.Conn) {
+	defer c.Close()
+	for {
+		msg, err := ber.ReadPacket(c)
+		if err != nil {
+			return
+		}
+		if !assert.Len(s.t, msg.Children, 2) {
+			return
+		}
+		id, ok := msg.Children[0].Value.(int64)
+		if !assert.True(s.t, ok, "message id") {
+			return
+		}
+		write := func(op *ber.Packet) {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+			envelope.AppendChild(op)
+			_, _ = c.Write(envelope.Bytes())
+		}
+
+		op := msg.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			// The password is a context-specific value, which isn't decoded.
+			dn, password := fakeString(op.Children[1]), op.Children[2].Data.String()
+			code := ldap.LDAPResultInvalidCredentials
+			if e, ok := s.entries[dn]; (dn == "" && password == "") || (ok && e.password != "" && e.password == password) {
+				code = ldap.LDAPResultSuccess
+			}
+			write(fakeResult(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationSearchRequest:
+			base := strings.ToLower(fakeString(op.Children[0]))
+			scope, _ := op.Children[1].Value.(int64)
+			filter := op.Children[6]
+			code := ldap.LDAPResultSuccess
+			if _, ok := s.entries[base]; scope == ldap.ScopeBaseObject && !ok {
+				code = ldap.LDAPResultNoSuchObject
+			}
+			for dn, e := range s.entries {
+				inScope := dn == base || (scope != ldap.ScopeBaseObject && strings.HasSuffix(dn, ","+base))
+				if code == ldap.LDAPResultSuccess && inScope && fakeMatch(filter, e.attrs) {
+					write(fakeEntryPacket(dn, e.attrs))
+				}
+			}
+			write(fakeResult(ldap.ApplicationSearchResultDone, code))
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			assert.Failf(s.t, "unexpected operation", "tag %d", op.Tag)
+			return
+		}
+	}
+}
+
+// fakeMatch evaluates the filters go-ldap compiles to, apart from substring
+// and ordering matches, which the tests don't use.
+func fakeMatch(filter *ber.Packet, attrs map[string][]string) bool {
+	values := func(name string) []string {
+		for k, v := range attrs {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		return nil
+	}
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !fakeMatch(child, attrs) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if fakeMatch(child, attrs) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !fakeMatch(filter.Children[0], attrs)
+	case ldap.FilterPresent:
+		// Every entry is an object.
+		attr := filter.Data.String()
+		return strings.EqualFold(attr, "objectClass") || len(values(attr)) > 0
+	case ldap.FilterEqualityMatch:
+		for _, v := range values(fakeString(filter.Children[0])) {
+			if strings.EqualFold(v, fakeString(filter.Children[1])) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func fakeString(p *ber.Packet) string {
+	return p.Data.String()
+}
+
+func fakeResult(tag ber.Tag, code int) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return p
+}
+
+func fakeEntryPacket(dn string, attrs map[string][]string) *ber.Packet {
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	for name, values := range attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, ""))
+		}
+		attr.AppendChild(set)
+		attributes.AppendChild(attr)
+	}
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+	p.AppendChild(attributes)
+	return p
+}
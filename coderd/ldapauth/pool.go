@@ -0,0 +1,58 @@
+package ldapauth
+
+import (
+	"context"
+)
+
+// pool keeps idle connections open, bound as the service account, so
+// logins don't dial the server every time.
+type pool struct {
+	idle chan *conn
+	dial func(ctx context.Context) (*conn, error)
+}
+
+func newPool(size int, dial func(ctx context.Context) (*conn, error)) *pool {
+	return &pool{
+		idle: make(chan *conn, size),
+		dial: dial,
+	}
+}
+
+// get returns an idle connection, or dials a new one if there are none.
+// pooled is true for idle connections, which may have been closed by the
+// server since they were last used.
+func (p *pool) get(ctx context.Context) (c *conn, pooled bool, err error) {
+	select {
+	case c := <-p.idle:
+		return c, true, nil
+	default:
+	}
+	c, err = p.dial(ctx)
+	return c, false, err
+}
+
+// put returns a connection to the pool. Broken connections, and connections
+// that don't fit in the pool, are closed.
+func (p *pool) put(c *conn) {
+	if c.broken {
+		_ = c.close()
+		return
+	}
+	select {
+	case p.idle <- c:
+	default:
+		_ = c.close()
+	}
+}
+
+// close closes every idle connection.
+func (p *pool) close() {
+	for {
+		select {
+		case c := <-p.idle:
+			_ = c.close()
+		default:
+			return
+		}
+	}
+}
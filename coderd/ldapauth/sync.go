@@ -0,0 +1,168 @@
+package ldapauth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// usersPageSize is how many users are checked per page of users.
+const usersPageSize = 100
+
+// SyncUserStatus suspends LDAP users whose directory account was deleted or
+// disabled, checking at the given interval. Users are looked up by the DN
+// they last logged in with, and by their email if the DN no longer exists,
+// so users whose entry was moved aren't suspended. The returned function
+// stops syncing.
+func SyncUserStatus(ctx context.Context, logger slog.Logger, db database.Store, auth *Authenticator, auditor *atomic.Pointer[audit.Auditor], interval time.Duration) func() {
+	logger = logger.Named("ldap_sync")
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system suspends users without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			suspended, err := syncUsers(ctx, logger, db, auth, auditor)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				logger.Error(ctx, "can't sync status of ldap users", slog.Error(err))
+				continue
+			}
+			logger.Debug(ctx, "synced status of ldap users", slog.F("num_suspended", suspended))
+		}
+	}()
+
+	return func() {
+		cancelFunc()
+		<-done
+	}
+}
+
+func syncUsers(ctx context.Context, logger slog.Logger, db database.Store, auth *Authenticator, auditor *atomic.Pointer[audit.Auditor]) (int, error) {
+	var suspended int
+	afterID := uuid.Nil
+	for {
+		users, err := db.GetUsers(ctx, database.GetUsersParams{
+			AfterID:  afterID,
+			Status:   []database.UserStatus{database.UserStatusActive, database.UserStatusDormant},
+			LimitOpt: usersPageSize,
+		})
+		if err != nil {
+			return suspended, xerrors.Errorf("get users: %w", err)
+		}
+		for _, user := range users {
+			if user.LoginType != database.LoginTypeLDAP {
+				continue
+			}
+			active, err := directoryActive(ctx, db, auth, user.ID, user.Email)
+			if err != nil {
+				if ctx.Err() != nil {
+					return suspended, ctx.Err()
+				}
+				// The user is left alone, since a directory that can't be
+				// reached mustn't suspend everyone.
+				logger.Warn(ctx, "can't check directory account of user", slog.F("user_id", user.ID), slog.Error(err))
+				continue
+			}
+			if active {
+				continue
+			}
+			err = suspendUser(ctx, logger, db, auditor, user.ID)
+			if err != nil {
+				logger.Error(ctx, "can't suspend ldap user", slog.F("user_id", user.ID), slog.Error(err))
+				continue
+			}
+			suspended++
+		}
+		if len(users) < usersPageSize {
+			return suspended, nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// directoryActive returns whether the directory account of the user exists
+// and isn't disabled.
+func directoryActive(ctx context.Context, db database.Store, auth *Authenticator, userID uuid.UUID, email string) (bool, error) {
+	link, err := db.GetUserLinkByUserIDLoginType(ctx, database.GetUserLinkByUserIDLoginTypeParams{
+		UserID:    userID,
+		LoginType: database.LoginTypeLDAP,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, xerrors.Errorf("get user link: %w", err)
+	}
+	if err == nil && link.LinkedID != "" {
+		dirUser, err := auth.Lookup(ctx, link.LinkedID)
+		if err == nil {
+			return !dirUser.Disabled, nil
+		}
+		if !xerrors.Is(err, ErrNotFound) {
+			return false, err
+		}
+	}
+
+	dirUser, err := auth.LookupEmail(ctx, email)
+	if xerrors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !dirUser.Disabled, nil
+}
+
+func suspendUser(ctx context.Context, logger slog.Logger, db database.Store, auditor *atomic.Pointer[audit.Auditor], userID uuid.UUID) error {
+	user, err := db.GetUserByID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	suspended, err := db.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+		ID:        user.ID,
+		Status:    database.UserStatusSuspended,
+		UpdatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("update user status: %w", err)
+	}
+	logger.Info(ctx, "suspended user whose directory account was deleted or disabled",
+		slog.F("user_id", user.ID),
+		slog.F("email", user.Email),
+	)
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.User]{
+		Audit:     *auditor.Load(),
+		Log:       logger,
+		UserID:    user.ID,
+		RequestID: uuid.New(),
+		Status:    http.StatusOK,
+		Action:    database.AuditActionWrite,
+		Old:       user,
+		New:       suspended,
+	})
+	return nil
+}
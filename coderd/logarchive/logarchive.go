@@ -0,0 +1,235 @@
+// Package logarchive compresses the logs of completed provisioner jobs so
+// provisioner_job_logs does not grow unbounded.
+package logarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	delay = 10 * time.Minute
+	// batchSize is how many jobs are archived at a time.
+	batchSize = 100
+	// maxBatches bounds how many batches are archived per tick, so a large
+	// backlog is worked through gradually.
+	maxBatches = 10
+)
+
+// Options configure which logs are archived.
+type Options struct {
+	// After is how long a job must have been completed for before its logs
+	// are archived.
+	After time.Duration
+	// Registerer registers the archive size metrics, if set.
+	Registerer prometheus.Registerer
+}
+
+// New periodically archives the logs of completed provisioner jobs. It is the
+// caller's responsibility to call Close on the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, opts Options) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system archives logs without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	metrics := newMetrics(opts.Registerer)
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(delay)
+
+		err := archive(ctx, logger, db, dbtime.Now().Add(-opts.After))
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Error(ctx, "failed to archive provisioner job logs", slog.Error(err))
+		}
+		stats, err := db.GetProvisionerJobLogArchiveStats(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Error(ctx, "failed to get provisioner job log archive stats", slog.Error(err))
+			return
+		}
+		metrics.archives.Set(float64(stats.ArchiveCount))
+		metrics.compressedBytes.Set(float64(stats.CompressedSize))
+		metrics.uncompressedBytes.Set(float64(stats.UncompressedSize))
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ticker.Stop()
+				doTick()
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+type metrics struct {
+	archives          prometheus.Gauge
+	compressedBytes   prometheus.Gauge
+	uncompressedBytes prometheus.Gauge
+}
+
+func newMetrics(registerer prometheus.Registerer) metrics {
+	m := metrics{
+		archives: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "provisioner_job_log_archive",
+			Name:      "jobs",
+			Help:      "The number of provisioner jobs with archived logs.",
+		}),
+		compressedBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "provisioner_job_log_archive",
+			Name:      "compressed_bytes",
+			Help:      "The total size of archived provisioner job logs after compression.",
+		}),
+		uncompressedBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "provisioner_job_log_archive",
+			Name:      "uncompressed_bytes",
+			Help:      "The total size of archived provisioner job logs before compression.",
+		}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.archives, m.compressedBytes, m.uncompressedBytes)
+	}
+	return m
+}
+
+// archive archives the logs of jobs completed before the given time.
+func archive(ctx context.Context, logger slog.Logger, db database.Store, completedBefore time.Time) error {
+	for i := 0; i < maxBatches; i++ {
+		jobIDs, err := db.GetProvisionerJobIDsToArchiveLogs(ctx, database.GetProvisionerJobIDsToArchiveLogsParams{
+			CompletedBefore: completedBefore,
+			LimitOpt:        batchSize,
+		})
+		if err != nil {
+			return xerrors.Errorf("get jobs to archive: %w", err)
+		}
+		for _, jobID := range jobIDs {
+			err = ArchiveJob(ctx, db, jobID)
+			if err != nil {
+				return xerrors.Errorf("archive job %s: %w", jobID, err)
+			}
+		}
+		if len(jobIDs) > 0 {
+			logger.Debug(ctx, "archived provisioner job logs", slog.F("jobs", len(jobIDs)))
+		}
+		if len(jobIDs) < batchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ArchiveJob compresses the logs of the job into an archive and removes them
+// from provisioner_job_logs.
+func ArchiveJob(ctx context.Context, db database.Store, jobID uuid.UUID) error {
+	return db.InTx(func(tx database.Store) error {
+		logs, err := tx.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+			JobID: jobID,
+		})
+		if err != nil {
+			return xerrors.Errorf("get logs: %w", err)
+		}
+		compressed, uncompressedSize, err := compress(logs)
+		if err != nil {
+			return xerrors.Errorf("compress logs: %w", err)
+		}
+		_, err = tx.InsertProvisionerJobLogArchive(ctx, database.InsertProvisionerJobLogArchiveParams{
+			JobID:            jobID,
+			CreatedAt:        dbtime.Now(),
+			LogCount:         int32(len(logs)),
+			UncompressedSize: uncompressedSize,
+			CompressedLogs:   compressed,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert archive: %w", err)
+		}
+		err = tx.DeleteProvisionerJobLogsByJobID(ctx, jobID)
+		if err != nil {
+			return xerrors.Errorf("delete logs: %w", err)
+		}
+		return nil
+	}, nil)
+}
+
+func compress(logs []database.ProvisionerJobLog) ([]byte, int64, error) {
+	data, err := json.Marshal(logs)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("marshal logs: %w", err)
+	}
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("create gzip writer: %w", err)
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, 0, xerrors.Errorf("write logs: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, 0, xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return buf.Bytes(), int64(len(data)), nil
+}
+
+// Decompress returns the logs stored in the archive.
+func Decompress(archive database.ProvisionerJobLogArchive) ([]database.ProvisionerJobLog, error) {
+	r, err := gzip.NewReader(bytes.NewReader(archive.CompressedLogs))
+	if err != nil {
+		return nil, xerrors.Errorf("create gzip reader: %w", err)
+	}
+	defer r.Close()
+	var logs []database.ProvisionerJobLog
+	err = json.NewDecoder(r).Decode(&logs)
+	if err != nil {
+		return nil, xerrors.Errorf("decode logs: %w", err)
+	}
+	return logs, nil
+}
@@ -0,0 +1,101 @@
+package logarchive_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/logarchive"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// Ensures no goroutines leak.
+func TestNew(t *testing.T) {
+	t.Parallel()
+	archiver := logarchive.New(context.Background(), slogtest.Make(t, nil), dbmem.New(), logarchive.Options{
+		After: time.Hour,
+	})
+	err := archiver.Close()
+	require.NoError(t, err)
+}
+
+func TestArchiveJob(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmem.New()
+	now := dbtime.Now()
+
+	job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		StartedAt:   sql.NullTime{Time: now.Add(-49 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-48 * time.Hour), Valid: true},
+	})
+	logs, err := db.InsertProvisionerJobLogs(ctx, database.InsertProvisionerJobLogsParams{
+		JobID:     job.ID,
+		CreatedAt: []time.Time{now, now},
+		Source:    []database.LogSource{database.LogSourceProvisioner, database.LogSourceProvisioner},
+		Level:     []database.LogLevel{database.LogLevelInfo, database.LogLevelError},
+		Stage:     []string{"Planning", "Planning"},
+		Output:    []string{"hello", "world"},
+	})
+	require.NoError(t, err)
+
+	jobIDs, err := db.GetProvisionerJobIDsToArchiveLogs(ctx, database.GetProvisionerJobIDsToArchiveLogsParams{
+		CompletedBefore: now.Add(-24 * time.Hour),
+		LimitOpt:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{job.ID}, jobIDs)
+
+	err = logarchive.ArchiveJob(ctx, db, job.ID)
+	require.NoError(t, err)
+
+	// The logs are removed from the table.
+	remaining, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: job.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+
+	// The archive holds the original logs.
+	archive, err := db.GetProvisionerJobLogArchiveByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, archive.LogCount)
+	archived, err := logarchive.Decompress(archive)
+	require.NoError(t, err)
+	require.Len(t, archived, len(logs))
+	for i := range logs {
+		require.Equal(t, logs[i].ID, archived[i].ID)
+		require.Equal(t, logs[i].Output, archived[i].Output)
+		require.Equal(t, logs[i].Level, archived[i].Level)
+	}
+
+	stats, err := db.GetProvisionerJobLogArchiveStats(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stats.ArchiveCount)
+	require.EqualValues(t, len(archive.CompressedLogs), stats.CompressedSize)
+	require.EqualValues(t, archive.UncompressedSize, stats.UncompressedSize)
+
+	// The job is not picked up again.
+	jobIDs, err = db.GetProvisionerJobIDsToArchiveLogs(ctx, database.GetProvisionerJobIDsToArchiveLogsParams{
+		CompletedBefore: now.Add(-24 * time.Hour),
+		LimitOpt:        10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, jobIDs)
+}
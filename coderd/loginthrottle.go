@@ -0,0 +1,431 @@
+package coderd
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// loginThrottler slows down password guessing, for password and LDAP logins. Once the failed logins of a
+// user from an IP address reach the backoff threshold, every further attempt
+// from that address has to wait twice as long as the previous one.
+// Once the failures of a user from all addresses reach the lockout threshold,
+// the user can't sign in with a password at all until the lockout expires or
+// an admin unlocks them.
+type loginThrottler struct {
+	logger slog.Logger
+	db     database.Store
+
+	backoffThreshold int64
+	backoffMax       time.Duration
+	lockoutThreshold int64
+	lockoutDuration  time.Duration
+
+	failures  prometheus.Counter
+	throttled *prometheus.CounterVec
+	lockouts  prometheus.Counter
+}
+
+func newLoginThrottler(logger slog.Logger, db database.Store, registerer prometheus.Registerer, cfg codersdk.LoginThrottleConfig) *loginThrottler {
+	factory := promauto.With(registerer)
+	return &loginThrottler{
+		logger:           logger,
+		db:               db,
+		backoffThreshold: cfg.BackoffThreshold.Value(),
+		backoffMax:       cfg.BackoffMax.Value(),
+		lockoutThreshold: cfg.LockoutThreshold.Value(),
+		lockoutDuration:  cfg.LockoutDuration.Value(),
+		failures: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "login",
+			Name:      "failed_attempts_total",
+			Help:      "The total number of password logins of existing users that failed because of a wrong password.",
+		}),
+		throttled: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "login",
+			Name:      "throttled_total",
+			Help:      "The total number of password logins rejected because of earlier failures.",
+		}, []string{"reason"}),
+		lockouts: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "login",
+			Name:      "lockouts_total",
+			Help:      "The total number of users locked out after too many failed password logins.",
+		}),
+	}
+}
+
+// attempt counts an attempt of the user to sign in from ip and returns
+// whether it may go ahead. If not, a response telling the client when to retry
+// has been written. The attempt is counted before the credentials are checked,
+// in the same statement that checks the backoff, so concurrent guesses can't
+// all get through before the first one is recorded. Logins that don't belong to
+// a user (userID is uuid.Nil) are throttled the same way, so that the responses
+// don't reveal which users exist.
+func (t *loginThrottler) attempt(ctx context.Context, rw http.ResponseWriter, userID uuid.UUID, login string, ip pqtype.Inet) bool {
+	//nolint:gocritic // Login failures are tracked by the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	now := dbtime.Now()
+
+	if t.lockoutThreshold > 0 {
+		lockedUntil, err := t.lockedUntil(ctx, userID, login, now)
+		if err != nil {
+			t.internalError(ctx, rw, "fetch user lockout", err)
+			return false
+		}
+		if lockedUntil.After(now) {
+			t.writeLockedOut(ctx, rw, lockedUntil.Sub(now))
+			return false
+		}
+	}
+
+	err := t.recordAttempt(ctx, userID, login, ip, now)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		attempts, lastFailedAt, err := t.loginFailures(ctx, userID, login, ip)
+		if err != nil {
+			t.internalError(ctx, rw, "fetch login failures", err)
+			return false
+		}
+		t.throttled.WithLabelValues("backoff").Inc()
+		writeLoginThrottled(ctx, rw, lastFailedAt.Add(t.backoff(attempts)).Sub(now), "Too many failed login attempts. Try again later.")
+		return false
+	}
+	if err != nil {
+		t.internalError(ctx, rw, "record login attempt", err)
+		return false
+	}
+
+	if t.lockoutThreshold > 0 {
+		// Attempts that raced past the lockout check above are caught here,
+		// the total includes every attempt recorded so far.
+		attempts, err := t.totalAttempts(ctx, userID, login, now)
+		if err != nil {
+			t.internalError(ctx, rw, "count login failures", err)
+			return false
+		}
+		if attempts > t.lockoutThreshold {
+			t.writeLockedOut(ctx, rw, t.lockoutDuration)
+			return false
+		}
+	}
+	return true
+}
+
+// recordAttempt counts an attempt from ip, or returns sql.ErrNoRows if ip is
+// backing off.
+func (t *loginThrottler) recordAttempt(ctx context.Context, userID uuid.UUID, login string, ip pqtype.Inet, now time.Time) error {
+	threshold := t.backoffThreshold
+	if threshold <= 0 {
+		threshold = math.MaxInt64
+	}
+	maxSeconds := int64(t.backoffMax.Seconds())
+	if maxSeconds <= 0 {
+		maxSeconds = 1 << 30
+	}
+	if userID == uuid.Nil {
+		_, err := t.db.RecordUnknownLoginAttempt(ctx, database.RecordUnknownLoginAttemptParams{
+			LoginHash:         loginHash(login),
+			IPAddress:         ip,
+			AttemptedAt:       now,
+			ResetBefore:       now.Add(-t.lockoutDuration),
+			BackoffThreshold:  threshold,
+			BackoffMaxSeconds: maxSeconds,
+		})
+		return err
+	}
+	_, err := t.db.RecordLoginAttempt(ctx, database.RecordLoginAttemptParams{
+		UserID:            userID,
+		IPAddress:         ip,
+		AttemptedAt:       now,
+		ResetBefore:       now.Add(-t.lockoutDuration),
+		BackoffThreshold:  threshold,
+		BackoffMaxSeconds: maxSeconds,
+	})
+	return err
+}
+
+// totalAttempts returns the number of failed and in-flight attempts of the
+// user from all addresses within the lockout duration.
+func (t *loginThrottler) totalAttempts(ctx context.Context, userID uuid.UUID, login string, now time.Time) (int64, error) {
+	if userID == uuid.Nil {
+		totals, err := t.db.GetUnknownLoginFailureTotals(ctx, database.GetUnknownLoginFailureTotalsParams{
+			LoginHash: loginHash(login),
+			Since:     now.Add(-t.lockoutDuration),
+		})
+		return totals.FailedAttempts, err
+	}
+	return t.db.CountLoginFailuresByUserID(ctx, database.CountLoginFailuresByUserIDParams{
+		UserID: userID,
+		Since:  now.Add(-t.lockoutDuration),
+	})
+}
+
+func (t *loginThrottler) writeLockedOut(ctx context.Context, rw http.ResponseWriter, retryAfter time.Duration) {
+	t.throttled.WithLabelValues("lockout").Inc()
+	writeLoginThrottled(ctx, rw, retryAfter,
+		"Your account is temporarily locked because of too many failed login attempts. Try again later, or contact an admin to unlock it.")
+}
+
+// lockedUntil returns when the lockout of the user ends. Logins that don't
+// belong to a user are locked out once their failures from all addresses
+// reach the lockout threshold, for as long as a user would be.
+func (t *loginThrottler) lockedUntil(ctx context.Context, userID uuid.UUID, login string, now time.Time) (time.Time, error) {
+	if userID == uuid.Nil {
+		totals, err := t.db.GetUnknownLoginFailureTotals(ctx, database.GetUnknownLoginFailureTotalsParams{
+			LoginHash: loginHash(login),
+			Since:     now.Add(-t.lockoutDuration),
+		})
+		if err != nil || totals.FailedAttempts < t.lockoutThreshold {
+			return time.Time{}, err
+		}
+		return totals.LastFailedAt.Add(t.lockoutDuration), nil
+	}
+	lockout, err := t.db.GetUserLockoutByUserID(ctx, userID)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return lockout.LockedUntil, err
+}
+
+// loginFailures returns the number of failed logins from ip and when the last one
+// happened.
+func (t *loginThrottler) loginFailures(ctx context.Context, userID uuid.UUID, login string, ip pqtype.Inet) (int64, time.Time, error) {
+	if userID == uuid.Nil {
+		failure, err := t.db.GetUnknownLoginFailure(ctx, database.GetUnknownLoginFailureParams{
+			LoginHash: loginHash(login),
+			IPAddress: ip,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return 0, time.Time{}, nil
+		}
+		return int64(failure.FailedAttempts), failure.LastFailedAt, err
+	}
+	failure, err := t.db.GetLoginFailure(ctx, database.GetLoginFailureParams{
+		UserID:    userID,
+		IPAddress: ip,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	return int64(failure.FailedAttempts), failure.LastFailedAt, err
+}
+
+// failed locks the user out if their attempts, which attempt already counted,
+// have failed too often. Errors are only logged, the client already gets told
+// that the credentials were wrong.
+func (t *loginThrottler) failed(ctx context.Context, userID uuid.UUID, ip pqtype.Inet) {
+	if userID == uuid.Nil {
+		// Unknown logins are locked out by attempt once their failures add
+		// up, there is no user to record a lockout for.
+		return
+	}
+	t.failures.Inc()
+	if t.lockoutThreshold <= 0 {
+		return
+	}
+
+	//nolint:gocritic // Login failures are tracked by the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	now := dbtime.Now()
+	failures, err := t.totalAttempts(ctx, userID, "", now)
+	if err != nil {
+		t.logger.Error(ctx, "count login failures", slog.F("user_id", userID), slog.Error(err))
+		return
+	}
+	if failures < t.lockoutThreshold {
+		return
+	}
+	_, err = t.db.UpsertUserLockout(ctx, database.UpsertUserLockoutParams{
+		UserID:         userID,
+		FailedAttempts: int32(failures),
+		LockedAt:       now,
+		LockedUntil:    now.Add(t.lockoutDuration),
+	})
+	if err != nil {
+		t.logger.Error(ctx, "lock out user", slog.F("user_id", userID), slog.Error(err))
+		return
+	}
+	t.lockouts.Inc()
+	t.logger.Warn(ctx, "locked out user after failed logins",
+		slog.F("user_id", userID),
+		slog.F("failed_attempts", failures),
+		slog.F("ip", ip.IPNet.IP.String()),
+	)
+}
+
+// succeeded forgets the attempts of the user from ip, including the one that
+// just succeeded. Failures from other addresses still count towards a lockout.
+func (t *loginThrottler) succeeded(ctx context.Context, userID uuid.UUID, login string, ip pqtype.Inet) {
+	//nolint:gocritic // Login failures are tracked by the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var err error
+	if userID == uuid.Nil {
+		err = t.db.DeleteUnknownLoginFailure(ctx, database.DeleteUnknownLoginFailureParams{
+			LoginHash: loginHash(login),
+			IPAddress: ip,
+		})
+	} else {
+		err = t.db.DeleteLoginFailure(ctx, database.DeleteLoginFailureParams{
+			UserID:    userID,
+			IPAddress: ip,
+		})
+	}
+	if err != nil {
+		t.logger.Error(ctx, "delete login failures", slog.F("user_id", userID), slog.Error(err))
+	}
+}
+
+// backoff returns how long to wait after the given number of failures before
+// trying again.
+func (t *loginThrottler) backoff(failures int64) time.Duration {
+	if failures < t.backoffThreshold {
+		return 0
+	}
+	delay := time.Second << min(failures-t.backoffThreshold, 30)
+	if t.backoffMax > 0 && delay > t.backoffMax {
+		return t.backoffMax
+	}
+	return delay
+}
+
+func (t *loginThrottler) internalError(ctx context.Context, rw http.ResponseWriter, msg string, err error) {
+	t.logger.Error(ctx, msg, slog.Error(err))
+	httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+		Message: "Internal error.",
+	})
+}
+
+func writeLoginThrottled(ctx context.Context, rw http.ResponseWriter, retryAfter time.Duration, msg string) {
+	rw.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+		Message: msg,
+	})
+}
+
+// loginHash returns the key failed logins for an email or username that
+// doesn't belong to a user are tracked by. Logins are matched
+// case-insensitively, like users are looked up.
+func loginHash(login string) []byte {
+	hash := sha256.Sum256([]byte(strings.ToLower(login)))
+	return hash[:]
+}
+
+// loginIP returns the address failed logins are tracked by. Requests without
+// a parseable address share the unspecified address.
+func loginIP(r *http.Request) pqtype.Inet {
+	ip := net.IPv4zero.To4()
+	if addr := httpmw.RequestAddr(r); addr.IsValid() {
+		ip = net.IP(addr.AsSlice())
+	}
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+		},
+		Valid: true,
+	}
+}
+
+// @Summary Get locked out users
+// @ID get-locked-out-users
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Success 200 {array} codersdk.UserLockout
+// @Router /users/lockouts [get]
+func (api *API) userLockouts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rows, err := api.Database.GetUserLockouts(ctx, dbtime.Now())
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching locked out users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	lockouts := make([]codersdk.UserLockout, 0, len(rows))
+	for _, row := range rows {
+		lockouts = append(lockouts, convertUserLockout(row))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, lockouts)
+}
+
+// Unlocking a user also forgets their failed logins, so that the next wrong
+// password doesn't lock them out again.
+//
+// @Summary Unlock user
+// @ID unlock-user
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/lockout [delete]
+func (api *API) deleteUserLockout(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: json.RawMessage(`{"lockout":"cleared"}`),
+	})
+	defer commitAudit()
+	aReq.Old = user
+
+	err := api.Database.DeleteUserLockout(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unlocking user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The user itself doesn't change, but the audit log should show that the
+	// lockout was cleared.
+	aReq.New = user
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+func convertUserLockout(row database.GetUserLockoutsRow) codersdk.UserLockout {
+	return codersdk.UserLockout{
+		UserID:         row.UserID,
+		Username:       row.Username,
+		FailedAttempts: row.FailedAttempts,
+		LockedAt:       row.LockedAt,
+		LockedUntil:    row.LockedUntil,
+	}
+}
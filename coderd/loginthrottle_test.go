@@ -0,0 +1,234 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dv := coderdtest.DeploymentValues(t)
+	dv.LoginThrottle.BackoffThreshold = 2
+	dv.LoginThrottle.BackoffMax = clibase.Duration(time.Hour)
+	dv.LoginThrottle.LockoutThreshold = 0
+	client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	anon := codersdk.New(client.URL)
+
+	var apiErr *codersdk.Error
+	for i := 0; i < 2; i++ {
+		_, err := anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    memberUser.Email,
+			Password: "wrong-password",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	}
+
+	// Even the right password is rejected until the backoff is over.
+	_, err := anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "SomeSecurePassword!",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+
+	// Other users aren't affected.
+	_, err = anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+		Email:    coderdtest.FirstUserParams.Email,
+		Password: coderdtest.FirstUserParams.Password,
+	})
+	require.NoError(t, err)
+
+	// Backoff only applies to a single address, so it never locks the user out.
+	lockouts, err := client.UserLockouts(ctx)
+	require.NoError(t, err)
+	require.Empty(t, lockouts)
+}
+
+// Attempts are counted before the password is checked, so concurrent guesses
+// can't all get through before the first failure is recorded.
+func TestLoginBackoffConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dv := coderdtest.DeploymentValues(t)
+	dv.LoginThrottle.BackoffThreshold = 2
+	dv.LoginThrottle.BackoffMax = clibase.Duration(time.Hour)
+	dv.LoginThrottle.LockoutThreshold = 0
+	client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+	owner := coderdtest.CreateFirstUser(t, client)
+	_, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	anon := codersdk.New(client.URL)
+
+	const attempts = 10
+	statuses := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+				Email:    memberUser.Email,
+				Password: "wrong-password",
+			})
+			var apiErr *codersdk.Error
+			if assert.ErrorAs(t, err, &apiErr) {
+				statuses <- apiErr.StatusCode()
+			}
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	checked := 0
+	for status := range statuses {
+		if status == http.StatusUnauthorized {
+			checked++
+			continue
+		}
+		require.Equal(t, http.StatusTooManyRequests, status)
+	}
+	require.Equal(t, 2, checked)
+}
+
+func TestLoginLockout(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	dv := coderdtest.DeploymentValues(t)
+	dv.LoginThrottle.BackoffThreshold = 0
+	dv.LoginThrottle.LockoutThreshold = 3
+	dv.LoginThrottle.LockoutDuration = clibase.Duration(time.Hour)
+	client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv, Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	anon := codersdk.New(client.URL)
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "SomeSecurePassword!",
+	}
+
+	var apiErr *codersdk.Error
+	for i := 0; i < 3; i++ {
+		_, err := anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    memberUser.Email,
+			Password: "wrong-password",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	}
+	_, err := anon.LoginWithPassword(ctx, login)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+
+	lockouts, err := client.UserLockouts(ctx)
+	require.NoError(t, err)
+	require.Len(t, lockouts, 1)
+	require.Equal(t, memberUser.ID, lockouts[0].UserID)
+	require.Equal(t, memberUser.Username, lockouts[0].Username)
+	require.EqualValues(t, 3, lockouts[0].FailedAttempts)
+	require.True(t, lockouts[0].LockedUntil.After(time.Now()))
+
+	// Members can't see or clear lockouts.
+	_, err = member.UserLockouts(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	err = member.UnlockUser(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.UnlockUser(ctx, memberUser.ID.String())
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeUser,
+		ResourceID:   memberUser.ID,
+		Action:       database.AuditActionWrite,
+	}))
+	lockouts, err = client.UserLockouts(ctx)
+	require.NoError(t, err)
+	require.Empty(t, lockouts)
+
+	// Unlocking forgets the failures, so one more doesn't lock the user again.
+	_, err = anon.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "wrong-password",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	_, err = anon.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+}
+
+// Logins for emails that don't belong to a user are throttled like those of
+// existing users, so the responses don't reveal which users exist.
+func TestLoginThrottleUnknownUser(t *testing.T) {
+	t.Parallel()
+
+	attempt := func(ctx context.Context, t *testing.T, client *codersdk.Client, email string) (int, string) {
+		t.Helper()
+		_, err := client.LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+			Email:    email,
+			Password: "wrong-password",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		return apiErr.StatusCode(), apiErr.Message
+	}
+
+	for _, tc := range []struct {
+		name             string
+		backoffThreshold int64
+		lockoutThreshold int64
+	}{
+		{name: "Backoff", backoffThreshold: 2},
+		{name: "Lockout", lockoutThreshold: 3},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Context(t, testutil.WaitLong)
+			dv := coderdtest.DeploymentValues(t)
+			dv.LoginThrottle.BackoffThreshold = clibase.Int64(tc.backoffThreshold)
+			dv.LoginThrottle.BackoffMax = clibase.Duration(time.Hour)
+			dv.LoginThrottle.LockoutThreshold = clibase.Int64(tc.lockoutThreshold)
+			dv.LoginThrottle.LockoutDuration = clibase.Duration(time.Hour)
+			client := coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+			owner := coderdtest.CreateFirstUser(t, client)
+			_, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+			anon := codersdk.New(client.URL)
+
+			var throttled bool
+			for i := 0; i < 5; i++ {
+				knownStatus, knownMessage := attempt(ctx, t, anon, memberUser.Email)
+				unknownStatus, unknownMessage := attempt(ctx, t, anon, "nobody@coder.com")
+				require.Equal(t, knownStatus, unknownStatus, "attempt %d", i)
+				require.Equal(t, knownMessage, unknownMessage, "attempt %d", i)
+				throttled = throttled || unknownStatus == http.StatusTooManyRequests
+			}
+			require.True(t, throttled)
+
+			// Failures for one unknown email don't throttle another.
+			status, _ := attempt(ctx, t, anon, "somebody@coder.com")
+			require.Equal(t, http.StatusUnauthorized, status)
+		})
+	}
+}
@@ -2,13 +2,19 @@ package coderd
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
 
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/rbac"
 
 	"github.com/coder/coder/v2/coderd/database"
@@ -17,6 +23,203 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// @Summary List organization members
+// @ID list-organization-members
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.OrganizationMemberWithName
+// @Router /organizations/{organization}/members [get]
+func (api *API) listMembers(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+
+	members, err := api.Database.GetOrganizationMembers(ctx, organization.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization members.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.OrganizationMemberWithName, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, codersdk.OrganizationMemberWithName{
+			Username: member.Username,
+			OrganizationMember: convertOrganizationMember(database.OrganizationMember{
+				UserID:         member.UserID,
+				OrganizationID: member.OrganizationID,
+				CreatedAt:      member.CreatedAt,
+				UpdatedAt:      member.UpdatedAt,
+				Roles:          member.Roles,
+			}),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Add organization member
+// @ID add-organization-member
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.OrganizationMember
+// @Router /organizations/{organization}/members/{user} [post]
+func (api *API) postOrganizationMember(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		member            = httpmw.OrganizationNonMemberParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableOrganizationMember](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	inserted, err := api.Database.InsertOrganizationMember(ctx, database.InsertOrganizationMemberParams{
+		OrganizationID: organization.ID,
+		UserID:         member.UserID,
+		CreatedAt:      dbtime.Now(),
+		UpdatedAt:      dbtime.Now(),
+		Roles:          []string{},
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("User %q is already a member of the organization.", member.Username),
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error adding organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	aReq.New = inserted.Auditable(member.Username)
+	httpapi.Write(ctx, rw, http.StatusCreated, convertOrganizationMember(inserted))
+}
+
+// @Summary Remove organization member
+// @ID remove-organization-member
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.Response
+// @Router /organizations/{organization}/members/{user} [delete]
+func (api *API) deleteOrganizationMember(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		member            = httpmw.OrganizationMemberParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableOrganizationMember](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = member.OrganizationMember.Auditable(member.Username)
+
+	if apiKey.UserID == member.UserID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot remove yourself from an organization.",
+		})
+		return
+	}
+
+	// The checks run in the same transaction as the removal, so a workspace
+	// created in the meantime can't be orphaned.
+	err := api.Database.InTx(func(tx database.Store) error {
+		// The checks must see every workspace and membership of the user,
+		// not just the ones the caller can read.
+		// nolint:gocritic
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		workspaces, err := tx.GetWorkspaces(sysCtx, database.GetWorkspacesParams{
+			OwnerID: member.UserID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get workspaces: %w", err)
+		}
+		for _, workspace := range workspaces {
+			if workspace.OrganizationID == organization.ID {
+				return httpError{
+					code: http.StatusBadRequest,
+					msg:  "The user's workspaces in the organization must be deleted or transferred before they can be removed.",
+				}
+			}
+		}
+
+		memberships, err := tx.GetOrganizationIDsByMemberIDs(sysCtx, []uuid.UUID{member.UserID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get organization memberships: %w", err)
+		}
+		if len(memberships) == 0 || len(memberships[0].OrganizationIDs) <= 1 {
+			return httpError{
+				code: http.StatusBadRequest,
+				msg:  "Users must belong to at least one organization.",
+			}
+		}
+
+		err = tx.DeleteGroupMembersByOrgAndUser(ctx, database.DeleteGroupMembersByOrgAndUserParams{
+			OrganizationID: organization.ID,
+			UserID:         member.UserID,
+		})
+		if err != nil {
+			return xerrors.Errorf("remove from groups: %w", err)
+		}
+		err = tx.DeleteOrganizationMember(ctx, database.DeleteOrganizationMemberParams{
+			OrganizationID: organization.ID,
+			UserID:         member.UserID,
+		})
+		if err != nil {
+			return xerrors.Errorf("remove organization member: %w", err)
+		}
+		return nil
+	}, nil)
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpErr.Write(rw, r)
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error removing organization member.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: fmt.Sprintf("User %q has been removed from the organization.", member.Username),
+	})
+}
+
 // @Summary Assign role to organization member
 // @ID assign-role-to-organization-member
 // @Security CoderSessionToken
@@ -30,11 +233,20 @@ import (
 // @Router /organizations/{organization}/members/{user}/roles [put]
 func (api *API) putMemberRoles(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx          = r.Context()
-		organization = httpmw.OrganizationParam(r)
-		member       = httpmw.OrganizationMemberParam(r)
-		apiKey       = httpmw.APIKey(r)
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		member            = httpmw.OrganizationMemberParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableOrganizationMember](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
 	)
+	defer commitAudit()
+	aReq.Old = member.OrganizationMember.Auditable(member.Username)
 
 	if apiKey.UserID == member.UserID {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -60,6 +272,7 @@ func (api *API) putMemberRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	aReq.New = updatedUser.Auditable(member.Username)
 	httpapi.Write(ctx, rw, http.StatusOK, convertOrganizationMember(updatedUser))
 }
 
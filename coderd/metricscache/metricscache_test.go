@@ -422,6 +422,7 @@ func TestCache_BuildTime(t *testing.T) {
 			err := db.InsertTemplate(ctx, database.InsertTemplateParams{
 				ID:          id,
 				Provisioner: database.ProvisionerTypeEcho,
+				Visibility:  database.TemplateVisibilityPublic,
 			})
 			require.NoError(t, err)
 			template, err := db.GetTemplateByID(ctx, id)
@@ -0,0 +1,235 @@
+// Package metricsexport pushes aggregated agent and workspace stats to metrics
+// backends, for deployments where Prometheus cannot scrape coderd.
+package metricsexport
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// Metric is a single gauge value pushed to a sink.
+type Metric struct {
+	Name   string
+	Value  float64
+	Labels map[string]string
+}
+
+// Sink delivers batches of metrics to a metrics backend.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Send delivers the metrics, which were all collected at the same time.
+	Send(ctx context.Context, collectedAt time.Time, metrics []Metric) error
+}
+
+// Options tunes how often an Exporter pushes and how it batches.
+type Options struct {
+	// Interval is how often stats are collected and pushed. Defaults to 1
+	// minute.
+	Interval time.Duration
+	// BatchSize is the most metrics sent to a sink at once. Defaults to 500.
+	BatchSize int
+	// Registerer registers the delivery metrics, if set.
+	Registerer prometheus.Registerer
+}
+
+// Exporter periodically collects aggregated stats from the database and
+// pushes them to every sink.
+type Exporter struct {
+	log   slog.Logger
+	db    database.Store
+	sinks []Sink
+	opts  Options
+
+	batches  *prometheus.CounterVec
+	dropped  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New starts pushing stats to the sinks until the context is canceled or the
+// exporter is closed.
+func New(ctx context.Context, logger slog.Logger, db database.Store, sinks []Sink, opts Options) *Exporter {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+
+	batches := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "metrics_export",
+		Name:      "batches_total",
+		Help:      "The number of metric batches pushed to a sink, by whether they were delivered.",
+	}, []string{"sink", "status"})
+	dropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "metrics_export",
+		Name:      "dropped_metrics_total",
+		Help:      "The number of metrics that could not be delivered to a sink.",
+	}, []string{"sink"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "metrics_export",
+		Name:      "send_duration_seconds",
+		Help:      "The time taken to push a batch of metrics to a sink.",
+		Buckets:   []float64{0.005, 0.010, 0.025, 0.050, 0.100, 0.500, 1, 5, 10, 30},
+	}, []string{"sink"})
+	if opts.Registerer != nil {
+		opts.Registerer.MustRegister(batches, dropped, duration)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	e := &Exporter{
+		log:      logger.Named("metrics_export"),
+		db:       db,
+		sinks:    sinks,
+		opts:     opts,
+		batches:  batches,
+		dropped:  dropped,
+		duration: duration,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go e.run(ctx)
+	return e
+}
+
+// Close stops the exporter.
+func (e *Exporter) Close() error {
+	e.cancel()
+	<-e.done
+	return nil
+}
+
+func (e *Exporter) run(ctx context.Context) {
+	defer close(e.done)
+
+	// nolint:gocritic // The exporter aggregates the stats of all workspaces.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Agent stats are only pushed for the interval since the last push, so
+	// the bytes sent and received are not counted twice.
+	createdAfter := time.Now()
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		collectedAt := time.Now()
+		metrics := e.collect(ctx, createdAfter)
+		createdAfter = collectedAt
+		for _, sink := range e.sinks {
+			e.push(ctx, sink, collectedAt, metrics)
+		}
+	}
+}
+
+// collect gathers the same stats that are exposed to Prometheus. Stats that
+// fail to load are logged and left out of this push.
+func (e *Exporter) collect(ctx context.Context, createdAfter time.Time) []Metric {
+	var metrics []Metric
+
+	agentStats, err := e.db.GetWorkspaceAgentStatsAndLabels(ctx, createdAfter)
+	if err != nil {
+		e.log.Error(ctx, "can't get agent stats", slog.Error(err))
+	}
+	for _, stat := range agentStats {
+		labels := map[string]string{
+			"agent_name":     stat.AgentName,
+			"username":       stat.Username,
+			"workspace_name": stat.WorkspaceName,
+		}
+		metrics = append(metrics,
+			Metric{Name: "coderd_agentstats_rx_bytes", Value: float64(stat.RxBytes), Labels: labels},
+			Metric{Name: "coderd_agentstats_tx_bytes", Value: float64(stat.TxBytes), Labels: labels},
+			Metric{Name: "coderd_agentstats_connection_count", Value: float64(stat.ConnectionCount), Labels: labels},
+			Metric{Name: "coderd_agentstats_connection_median_latency_seconds", Value: stat.ConnectionMedianLatencyMS / 1000.0, Labels: labels},
+			Metric{Name: "coderd_agentstats_session_count_jetbrains", Value: float64(stat.SessionCountJetBrains), Labels: labels},
+			Metric{Name: "coderd_agentstats_session_count_reconnecting_pty", Value: float64(stat.SessionCountReconnectingPTY), Labels: labels},
+			Metric{Name: "coderd_agentstats_session_count_ssh", Value: float64(stat.SessionCountSSH), Labels: labels},
+			Metric{Name: "coderd_agentstats_session_count_vscode", Value: float64(stat.SessionCountVSCode), Labels: labels},
+		)
+	}
+
+	workspaces, err := e.db.GetWorkspaceCountsByTemplateAndStatus(ctx)
+	if err != nil {
+		e.log.Error(ctx, "can't get workspace counts", slog.Error(err))
+	}
+	for _, row := range workspaces {
+		metrics = append(metrics, Metric{
+			Name:  "coderd_workspaces",
+			Value: float64(row.Count),
+			Labels: map[string]string{
+				"template_name":        row.TemplateName,
+				"organization_name":    row.OrganizationName,
+				"workspace_transition": string(row.Transition),
+				"status":               string(row.JobStatus),
+			},
+		})
+	}
+
+	agents, err := e.db.GetWorkspaceAgentCountsByLifecycleState(ctx)
+	if err != nil {
+		e.log.Error(ctx, "can't get workspace agent counts", slog.Error(err))
+	}
+	for _, row := range agents {
+		metrics = append(metrics, Metric{
+			Name:   "coderd_workspace_agents",
+			Value:  float64(row.Count),
+			Labels: map[string]string{"lifecycle_state": string(row.LifecycleState)},
+		})
+	}
+
+	apps, err := e.db.GetWorkspaceAppCountsByHealth(ctx)
+	if err != nil {
+		e.log.Error(ctx, "can't get workspace app counts", slog.Error(err))
+	}
+	for _, row := range apps {
+		metrics = append(metrics, Metric{
+			Name:   "coderd_workspace_apps",
+			Value:  float64(row.Count),
+			Labels: map[string]string{"health": string(row.Health)},
+		})
+	}
+
+	return metrics
+}
+
+// push sends the metrics to the sink in batches. A batch that fails is
+// dropped rather than retried, since the next push carries fresh values.
+func (e *Exporter) push(ctx context.Context, sink Sink, collectedAt time.Time, metrics []Metric) {
+	for start := 0; start < len(metrics); start += e.opts.BatchSize {
+		batch := metrics[start:min(start+e.opts.BatchSize, len(metrics))]
+
+		sendCtx, cancel := context.WithTimeout(ctx, e.opts.Interval)
+		timer := prometheus.NewTimer(e.duration.WithLabelValues(sink.Name()))
+		err := sink.Send(sendCtx, collectedAt, batch)
+		timer.ObserveDuration()
+		cancel()
+		if err != nil {
+			e.batches.WithLabelValues(sink.Name(), "failure").Inc()
+			e.dropped.WithLabelValues(sink.Name()).Add(float64(len(batch)))
+			e.log.Warn(ctx, "failed to push metrics",
+				slog.F("sink", sink.Name()),
+				slog.F("count", len(batch)),
+				slog.Error(err),
+			)
+			continue
+		}
+		e.batches.WithLabelValues(sink.Name(), "success").Inc()
+	}
+}
@@ -0,0 +1,262 @@
+package metricsexport_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/metricsexport"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		registry := prometheus.NewRegistry()
+		sink := &memorySink{sent: make(chan []metricsexport.Metric, 16)}
+		exporter := metricsexport.New(context.Background(), slogtest.Make(t, nil), fakeStore{}, []metricsexport.Sink{sink}, metricsexport.Options{
+			Interval:   testutil.IntervalFast,
+			BatchSize:  3,
+			Registerer: registry,
+		})
+		t.Cleanup(func() {
+			_ = exporter.Close()
+		})
+
+		// 8 agent stats, 1 workspace count, 1 agent count and 1 app count are
+		// split into batches of 3, 3, 3 and 2.
+		var sent []metricsexport.Metric
+		for _, want := range []int{3, 3, 3, 2} {
+			batch := <-sink.sent
+			require.Len(t, batch, want)
+			sent = append(sent, batch...)
+		}
+
+		names := map[string]metricsexport.Metric{}
+		for _, metric := range sent {
+			names[metric.Name] = metric
+		}
+		assert.Equal(t, float64(1024), names["coderd_agentstats_rx_bytes"].Value)
+		assert.Equal(t, "main", names["coderd_agentstats_rx_bytes"].Labels["agent_name"])
+		assert.Equal(t, 0.5, names["coderd_agentstats_connection_median_latency_seconds"].Value)
+		assert.Equal(t, float64(2), names["coderd_workspaces"].Value)
+		assert.Equal(t, "docker", names["coderd_workspaces"].Labels["template_name"])
+		assert.Equal(t, float64(4), names["coderd_workspace_agents"].Value)
+		assert.Equal(t, float64(5), names["coderd_workspace_apps"].Value)
+
+		require.Eventually(t, func() bool {
+			return counterValue(t, registry, "coderd_metrics_export_batches_total", "success") >= 4
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		t.Parallel()
+
+		registry := prometheus.NewRegistry()
+		sink := &memorySink{sent: make(chan []metricsexport.Metric, 16), err: xerrors.New("unavailable")}
+		exporter := metricsexport.New(context.Background(), slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), fakeStore{}, []metricsexport.Sink{sink}, metricsexport.Options{
+			Interval:   testutil.IntervalFast,
+			Registerer: registry,
+		})
+		t.Cleanup(func() {
+			_ = exporter.Close()
+		})
+
+		<-sink.sent
+		require.Eventually(t, func() bool {
+			return counterValue(t, registry, "coderd_metrics_export_dropped_metrics_total", "") >= 11
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
+
+func TestStatsdSink(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	sink, err := metricsexport.NewStatsdSink(conn.LocalAddr().String())
+	require.NoError(t, err)
+	require.Equal(t, "statsd", sink.Name())
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	err = sink.Send(ctx, time.Now(), []metricsexport.Metric{
+		{Name: "coderd_workspace_apps", Value: 3, Labels: map[string]string{"health": "healthy"}},
+		{Name: "coderd_workspaces", Value: 1.5, Labels: map[string]string{"template_name": "a|b", "status": "running"}},
+	})
+	require.NoError(t, err)
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(testutil.WaitShort))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"coderd_workspace_apps:3|g|#health:healthy",
+		"coderd_workspaces:1.5|g|#status:running,template_name:a_b",
+	}, "\n"), string(buf[:n]))
+
+	_, err = metricsexport.NewStatsdSink("localhost")
+	require.Error(t, err)
+}
+
+func TestOTLPSink(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan *collectormetricspb.ExportMetricsServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var req collectormetricspb.ExportMetricsServiceRequest
+		if !assert.NoError(t, proto.Unmarshal(body, &req)) {
+			return
+		}
+		received <- &req
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	endpoint, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sink, err := metricsexport.NewOTLPSink(endpoint, srv.Client())
+	require.NoError(t, err)
+	require.Equal(t, "otlp", sink.Name())
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	collectedAt := time.Now()
+	err = sink.Send(ctx, collectedAt, []metricsexport.Metric{
+		{Name: "coderd_workspace_apps", Value: 3, Labels: map[string]string{"health": "healthy"}},
+		{Name: "coderd_workspace_apps", Value: 1, Labels: map[string]string{"health": "unhealthy"}},
+		{Name: "coderd_workspaces", Value: 2},
+	})
+	require.NoError(t, err)
+
+	req := <-received
+	require.Len(t, req.ResourceMetrics, 1)
+	require.Len(t, req.ResourceMetrics[0].ScopeMetrics, 1)
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "coderd_workspace_apps", metrics[0].Name)
+	points := metrics[0].GetGauge().DataPoints
+	require.Len(t, points, 2)
+	assert.Equal(t, float64(3), points[0].GetAsDouble())
+	assert.Equal(t, "health", points[0].Attributes[0].Key)
+	assert.Equal(t, "healthy", points[0].Attributes[0].Value.GetStringValue())
+	assert.Equal(t, uint64(collectedAt.UnixNano()), points[0].TimeUnixNano)
+	assert.Equal(t, "coderd_workspaces", metrics[1].Name)
+
+	_, err = metricsexport.NewOTLPSink(&url.URL{Scheme: "grpc", Host: "localhost:4317"}, nil)
+	require.Error(t, err)
+}
+
+type memorySink struct {
+	mu   sync.Mutex
+	sent chan []metricsexport.Metric
+	err  error
+}
+
+func (*memorySink) Name() string {
+	return "memory"
+}
+
+func (s *memorySink) Send(_ context.Context, _ time.Time, metrics []metricsexport.Metric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.sent <- metrics:
+	default:
+	}
+	return s.err
+}
+
+// fakeStore returns fixed stats for every push.
+type fakeStore struct {
+	database.Store
+}
+
+func (fakeStore) GetWorkspaceAgentStatsAndLabels(context.Context, time.Time) ([]database.GetWorkspaceAgentStatsAndLabelsRow, error) {
+	return []database.GetWorkspaceAgentStatsAndLabelsRow{{
+		Username:                  "admin",
+		WorkspaceName:             "dev",
+		AgentName:                 "main",
+		RxBytes:                   1024,
+		TxBytes:                   2048,
+		ConnectionCount:           1,
+		ConnectionMedianLatencyMS: 500,
+	}}, nil
+}
+
+func (fakeStore) GetWorkspaceCountsByTemplateAndStatus(context.Context) ([]database.GetWorkspaceCountsByTemplateAndStatusRow, error) {
+	return []database.GetWorkspaceCountsByTemplateAndStatusRow{{
+		TemplateName:     "docker",
+		OrganizationName: "coder",
+		Transition:       database.WorkspaceTransitionStart,
+		JobStatus:        database.ProvisionerJobStatusSucceeded,
+		Count:            2,
+	}}, nil
+}
+
+func (fakeStore) GetWorkspaceAgentCountsByLifecycleState(context.Context) ([]database.GetWorkspaceAgentCountsByLifecycleStateRow, error) {
+	return []database.GetWorkspaceAgentCountsByLifecycleStateRow{{
+		LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+		Count:          4,
+	}}, nil
+}
+
+func (fakeStore) GetWorkspaceAppCountsByHealth(context.Context) ([]database.GetWorkspaceAppCountsByHealthRow, error) {
+	return []database.GetWorkspaceAppCountsByHealthRow{{
+		Health: database.WorkspaceAppHealthHealthy,
+		Count:  5,
+	}}, nil
+}
+
+// counterValue sums the memory sink's counters with the given name and, if
+// set, status label.
+func counterValue(t *testing.T, registry *prometheus.Registry, name, status string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["sink"] != "memory" || (status != "" && labels["status"] != status) {
+				continue
+			}
+			total += metric.GetCounter().GetValue()
+		}
+	}
+	return total
+}
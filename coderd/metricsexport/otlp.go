@@ -0,0 +1,126 @@
+package metricsexport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+)
+
+type otlpSink struct {
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewOTLPSink returns a sink that POSTs metrics as OTLP gauges to an
+// OTLP/HTTP endpoint, e.g. http://otel-collector:4318/v1/metrics. The path
+// defaults to /v1/metrics if the URL has none.
+func NewOTLPSink(endpoint *url.URL, client *http.Client) (Sink, error) {
+	switch endpoint.Scheme {
+	case "http", "https":
+	default:
+		return nil, xerrors.Errorf("unsupported OTLP scheme %q, must be http or https", endpoint.Scheme)
+	}
+	if endpoint.Host == "" {
+		return nil, xerrors.New("OTLP endpoint must include a host")
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint = endpoint.JoinPath("/v1/metrics")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &otlpSink{endpoint: endpoint, client: client}, nil
+}
+
+func (*otlpSink) Name() string {
+	return "otlp"
+}
+
+func (s *otlpSink) Send(ctx context.Context, collectedAt time.Time, metrics []Metric) error {
+	body, err := proto.Marshal(otlpRequest(collectedAt, metrics))
+	if err != nil {
+		return xerrors.Errorf("marshal metrics: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+// otlpRequest groups the metrics by name, with a data point for each set of
+// labels.
+func otlpRequest(collectedAt time.Time, metrics []Metric) *collectormetricspb.ExportMetricsServiceRequest {
+	var (
+		byName = map[string]*metricspb.Gauge{}
+		pbs    []*metricspb.Metric
+	)
+	for _, metric := range metrics {
+		gauge, ok := byName[metric.Name]
+		if !ok {
+			gauge = &metricspb.Gauge{}
+			byName[metric.Name] = gauge
+			pbs = append(pbs, &metricspb.Metric{
+				Name: metric.Name,
+				Data: &metricspb.Metric_Gauge{Gauge: gauge},
+			})
+		}
+
+		keys := make([]string, 0, len(metric.Labels))
+		for key := range metric.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		attributes := make([]*commonpb.KeyValue, 0, len(keys))
+		for _, key := range keys {
+			attributes = append(attributes, otlpString(key, metric.Labels[key]))
+		}
+		gauge.DataPoints = append(gauge.DataPoints, &metricspb.NumberDataPoint{
+			Attributes:   attributes,
+			TimeUnixNano: uint64(collectedAt.UnixNano()),
+			Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: metric.Value},
+		})
+	}
+
+	return &collectormetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{otlpString("service.name", "coderd")},
+			},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   &commonpb.InstrumentationScope{Name: "github.com/coder/coder/v2/coderd/metricsexport"},
+				Metrics: pbs,
+			}},
+		}},
+	}
+}
+
+func otlpString(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
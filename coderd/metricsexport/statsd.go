@@ -0,0 +1,119 @@
+package metricsexport
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// statsdMaxPacketSize keeps packets under the common Ethernet MTU, so they
+// are not fragmented or dropped on the way to the statsd server.
+const statsdMaxPacketSize = 1432
+
+type statsdSink struct {
+	address string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewStatsdSink returns a sink that writes metrics as statsd gauges over UDP.
+// Labels are sent as DogStatsD tags, which most statsd servers understand.
+func NewStatsdSink(address string) (Sink, error) {
+	_, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid statsd address %q: %w", address, err)
+	}
+	return &statsdSink{address: address}, nil
+}
+
+func (*statsdSink) Name() string {
+	return "statsd"
+}
+
+func (s *statsdSink) Send(ctx context.Context, _ time.Time, metrics []Metric) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "udp", s.address)
+		if err != nil {
+			return xerrors.Errorf("dial statsd: %w", err)
+		}
+		s.conn = conn
+	}
+
+	var packet bytes.Buffer
+	for _, metric := range metrics {
+		line := formatStatsd(metric)
+		if packet.Len() > 0 && packet.Len()+1+len(line) > statsdMaxPacketSize {
+			err := s.write(packet.Bytes())
+			if err != nil {
+				return err
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if packet.Len() == 0 {
+		return nil
+	}
+	return s.write(packet.Bytes())
+}
+
+func (s *statsdSink) write(packet []byte) error {
+	_, err := s.conn.Write(packet)
+	if err != nil {
+		// Redial on the next send, in case the server address now resolves
+		// somewhere else.
+		_ = s.conn.Close()
+		s.conn = nil
+		return xerrors.Errorf("write statsd packet: %w", err)
+	}
+	return nil
+}
+
+// formatStatsd formats the metric as a gauge, e.g.
+// "coderd_workspace_apps:3|g|#health:healthy".
+func formatStatsd(metric Metric) string {
+	var sb strings.Builder
+	sb.WriteString(statsdEscape(metric.Name))
+	sb.WriteByte(':')
+	sb.WriteString(strconv.FormatFloat(metric.Value, 'f', -1, 64))
+	sb.WriteString("|g")
+
+	keys := make([]string, 0, len(metric.Labels))
+	for key := range metric.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			sb.WriteString("|#")
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(statsdEscape(key))
+		sb.WriteByte(':')
+		sb.WriteString(statsdEscape(metric.Labels[key]))
+	}
+	return sb.String()
+}
+
+var statsdReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", ",", "_", "#", "_", "\n", "_")
+
+// statsdEscape replaces the characters that delimit the statsd format.
+func statsdEscape(s string) string {
+	return statsdReplacer.Replace(s)
+}
@@ -0,0 +1,129 @@
+package coderd
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+const (
+	// nodeKeyRotationChannel is published to when every node key must be
+	// rotated. Each replica asks the peers connected to its own coordinator.
+	nodeKeyRotationChannel = "tailnet_rotate_node_keys"
+
+	// nodeKeyCheckInterval is how often node keys that are due for rotation
+	// are looked for, unless the rotation interval is shorter.
+	nodeKeyCheckInterval = time.Hour
+)
+
+// nodeKeyRotator asks the agents and clients connected to the coordinator to
+// rotate their Wireguard node keys. Keys are rotated once they have been used
+// for longer than the rotation interval, and all at once when an admin forces
+// it.
+type nodeKeyRotator struct {
+	logger      slog.Logger
+	pubsub      pubsub.Pubsub
+	coordinator *atomic.Pointer[tailnet.Coordinator]
+	rotations   *prometheus.CounterVec
+
+	cancel    context.CancelFunc
+	cancelSub func()
+	closed    chan struct{}
+}
+
+// newNodeKeyRotator starts rotating node keys older than interval. Keys are
+// only rotated when forced if interval is 0.
+func newNodeKeyRotator(
+	logger slog.Logger,
+	ps pubsub.Pubsub,
+	coordinator *atomic.Pointer[tailnet.Coordinator],
+	registerer prometheus.Registerer,
+	interval time.Duration,
+) (*nodeKeyRotator, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &nodeKeyRotator{
+		logger:      logger,
+		pubsub:      ps,
+		coordinator: coordinator,
+		rotations: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "tailnet",
+			Name:      "node_key_rotations_total",
+			Help:      "The total number of agents and clients asked to rotate their node key.",
+		}, []string{"reason"}),
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+	cancelSub, err := ps.Subscribe(nodeKeyRotationChannel, func(_ context.Context, _ []byte) {
+		r.rotate(time.Now(), "forced")
+	})
+	if err != nil {
+		cancel()
+		return nil, xerrors.Errorf("subscribe to node key rotations: %w", err)
+	}
+	r.cancelSub = cancelSub
+
+	if interval <= 0 {
+		close(r.closed)
+		return r, nil
+	}
+	go r.run(ctx, interval)
+	return r, nil
+}
+
+func (r *nodeKeyRotator) run(ctx context.Context, interval time.Duration) {
+	defer close(r.closed)
+	check := nodeKeyCheckInterval
+	if interval < check {
+		check = interval
+	}
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.rotate(time.Now().Add(-interval), "scheduled")
+		}
+	}
+}
+
+// rotate asks the peers with node keys used since before olderThan to rotate
+// them.
+func (r *nodeKeyRotator) rotate(olderThan time.Time, reason string) {
+	asked := (*r.coordinator.Load()).RotateNodeKeys(olderThan)
+	if asked == 0 {
+		return
+	}
+	r.rotations.WithLabelValues(reason).Add(float64(asked))
+	r.logger.Info(context.Background(), "asked peers to rotate their node keys",
+		slog.F("peers", asked),
+		slog.F("reason", reason),
+	)
+}
+
+// forceRotation asks every agent and client connected to any replica to
+// rotate its node key.
+func (r *nodeKeyRotator) forceRotation() error {
+	err := r.pubsub.Publish(nodeKeyRotationChannel, []byte{})
+	if err != nil {
+		return xerrors.Errorf("publish node key rotation: %w", err)
+	}
+	return nil
+}
+
+func (r *nodeKeyRotator) Close() error {
+	r.cancelSub()
+	r.cancel()
+	<-r.closed
+	return nil
+}
@@ -0,0 +1,166 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxUserNotifications is the number of recent notifications returned for a
+// user.
+const maxUserNotifications = 100
+
+// @Summary Get user notifications
+// @ID get-user-notifications
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationMessage
+// @Router /users/{user}/notifications [get]
+func (api *API) userNotifications(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	msgs, err := api.Database.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+		UserID:   user.ID,
+		LimitOpt: maxUserNotifications,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notifications.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.NotificationMessages(msgs))
+}
+
+// @Summary Get user notification preferences
+// @ID get-user-notification-preferences
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [get]
+func (api *API) userNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	prefs, err := api.Database.GetNotificationPreferencesByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(prefs))
+}
+
+// @Summary Update user notification preferences
+// @ID update-user-notification-preferences
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateNotificationPreferencesRequest true "Notification preferences"
+// @Success 200 {array} codersdk.NotificationPreference
+// @Router /users/{user}/notifications/preferences [put]
+func (api *API) putUserNotificationPreferences(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	var req codersdk.UpdateNotificationPreferencesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	for _, pref := range req.Preferences {
+		if !pref.Template.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown notification template %q.", pref.Template),
+				Validations: []codersdk.ValidationError{
+					{Field: "preferences", Detail: fmt.Sprintf("template must be one of %v", codersdk.NotificationTemplates)},
+				},
+			})
+			return
+		}
+	}
+
+	err := api.Database.InTx(func(tx database.Store) error {
+		now := dbtime.Now()
+		for _, pref := range req.Preferences {
+			_, err := tx.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+				UserID:    user.ID,
+				Template:  string(pref.Template),
+				Disabled:  pref.Disabled,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	prefs, err := api.Database.GetNotificationPreferencesByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching notification preferences.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertNotificationPreferences(prefs))
+}
+
+// convertNotificationPreferences returns a preference for every template.
+// Templates the user has no preference for are enabled.
+func convertNotificationPreferences(prefs []database.NotificationPreference) []codersdk.NotificationPreference {
+	disabled := make(map[string]bool, len(prefs))
+	for _, pref := range prefs {
+		disabled[pref.Template] = pref.Disabled
+	}
+	converted := make([]codersdk.NotificationPreference, 0, len(codersdk.NotificationTemplates))
+	for _, template := range codersdk.NotificationTemplates {
+		converted = append(converted, codersdk.NotificationPreference{
+			Template: template,
+			Disabled: disabled[string(template)],
+		})
+	}
+	return converted
+}
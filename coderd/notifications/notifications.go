@@ -0,0 +1,431 @@
+// Package notifications renders notifications from templates and sends them
+// to users, retrying the ones that could not be sent. Every notification is
+// also kept in the inbox of the user, which is shown in the dashboard.
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultMaxAttempts   = 5
+	defaultRetryInterval = 5 * time.Minute
+	defaultPollInterval  = 10 * time.Second
+	defaultBatchSize     = 10
+	defaultSendTimeout   = 30 * time.Second
+
+	// leaseDuration is how long an acquired notification is held before
+	// another replica may send it. It must exceed the send timeout.
+	leaseDuration = 2 * time.Minute
+)
+
+// InboxNotifyChannel is the pubsub channel the inbox notifications of a user
+// are published to as they are created.
+func InboxNotifyChannel(userID uuid.UUID) string {
+	return fmt.Sprintf("inbox_notifications:%s", userID)
+}
+
+// Sender delivers rendered notifications to users.
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// Poster posts rendered notifications to a chat channel.
+type Poster interface {
+	Post(ctx context.Context, title, text string) error
+}
+
+// Route posts notifications of the listed templates to a chat channel, in
+// addition to sending them to the user. Routes ignore the preferences of
+// users since the channel is shared.
+type Route struct {
+	Method    codersdk.NotificationMethod
+	Poster    Poster
+	Templates []codersdk.NotificationTemplate
+}
+
+func (r Route) matches(template codersdk.NotificationTemplate) bool {
+	for _, t := range r.Templates {
+		if t == template {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueuer queues notifications to be sent to users. Errors are logged
+// rather than returned so that a notification that can't be sent never fails
+// the operation it is about.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string)
+}
+
+// NewNoop returns an Enqueuer that discards all notifications.
+func NewNoop() Enqueuer {
+	return noopEnqueuer{}
+}
+
+type noopEnqueuer struct{}
+
+func (noopEnqueuer) Enqueue(context.Context, uuid.UUID, codersdk.NotificationTemplate, map[string]string) {
+}
+
+type Options struct {
+	// Sender sends the notifications to users. Notifications are only sent
+	// to users when it is set.
+	Sender Sender
+	// Routes post notifications to chat channels.
+	Routes []Route
+	// Pubsub is used to publish new inbox notifications to watchers. Inbox
+	// notifications are not published when it is nil.
+	Pubsub pubsub.Pubsub
+	// AccessURL is used to link to the deployment from notifications.
+	AccessURL *url.URL
+	// MaxAttempts is the number of attempts made before a notification is
+	// marked as failed.
+	MaxAttempts int32
+	// RetryInterval is the delay before the first retry. It doubles with
+	// every subsequent attempt.
+	RetryInterval time.Duration
+	// PollInterval is how often notifications that are due are looked up.
+	// Newly queued notifications are sent immediately.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of notifications sent at once.
+	BatchSize int32
+}
+
+// Dispatcher stores queued notifications and sends them in the background,
+// retrying failures with exponential backoff.
+type Dispatcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wake   chan struct{}
+
+	db   database.Store
+	log  slog.Logger
+	opts Options
+}
+
+var _ Enqueuer = (*Dispatcher)(nil)
+
+// New returns a Dispatcher and starts sending notifications.
+func New(ctx context.Context, db database.Store, log slog.Logger, opts Options) *Dispatcher {
+	if opts.AccessURL == nil {
+		opts.AccessURL = &url.URL{}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultRetryInterval
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	//nolint:gocritic // The dispatcher sends notifications to all users.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	d := &Dispatcher{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		wake:   make(chan struct{}, 1),
+		db:     db,
+		log:    log,
+		opts:   opts,
+	}
+	go d.run()
+	return d
+}
+
+// Enqueue adds a notification to the inbox of the user and queues it to be
+// sent to them, unless they opted out of the template. The notification is
+// also queued for every route the template is posted to.
+func (d *Dispatcher) Enqueue(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) {
+	err := d.enqueue(ctx, userID, template, labels)
+	if err != nil {
+		d.log.Error(ctx, "enqueue notification",
+			slog.F("user_id", userID),
+			slog.F("template", template),
+			slog.Error(err),
+		)
+	}
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) error {
+	//nolint:gocritic // Notifications are queued on behalf of the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	prefs, err := d.db.GetNotificationPreferencesByUserID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf("get notification preferences: %w", err)
+	}
+	disabled := false
+	for _, pref := range prefs {
+		if pref.Template == string(template) && pref.Disabled {
+			disabled = true
+		}
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	now := dbtime.Now()
+
+	var methods []codersdk.NotificationMethod
+	if !disabled {
+		err = d.insertInbox(ctx, userID, template, labels, now)
+		if err != nil {
+			return err
+		}
+		if d.opts.Sender != nil {
+			methods = append(methods, codersdk.NotificationMethodSMTP)
+		}
+	}
+	for _, route := range d.opts.Routes {
+		if route.matches(template) {
+			methods = append(methods, route.Method)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(labels)
+	if err != nil {
+		return xerrors.Errorf("marshal labels: %w", err)
+	}
+	for _, method := range methods {
+		_, err = d.db.InsertNotificationMessage(ctx, database.InsertNotificationMessageParams{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Template:      string(template),
+			Labels:        raw,
+			CreatedAt:     now,
+			NextAttemptAt: sql.NullTime{Time: now, Valid: true},
+			Method:        string(method),
+		})
+		if err != nil {
+			return xerrors.Errorf("insert %s notification: %w", method, err)
+		}
+	}
+	d.Wake()
+	return nil
+}
+
+// insertInbox adds the notification to the inbox of the user and publishes it
+// to watchers of the inbox.
+func (d *Dispatcher) insertInbox(ctx context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string, now time.Time) error {
+	user, err := d.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	title, content, err := render(inboxTemplates, template, templateData{
+		UserName:  user.Username,
+		AccessURL: d.opts.AccessURL.String(),
+		Labels:    labels,
+	})
+	if err != nil {
+		return err
+	}
+	notif, err := d.db.InsertInboxNotification(ctx, database.InsertInboxNotificationParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Template:  string(template),
+		Title:     title,
+		Content:   content,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return xerrors.Errorf("insert inbox notification: %w", err)
+	}
+
+	if d.opts.Pubsub == nil {
+		return nil
+	}
+	payload, err := json.Marshal(notif)
+	if err != nil {
+		return xerrors.Errorf("marshal inbox notification: %w", err)
+	}
+	// Watchers only miss the notification until they next list the inbox,
+	// so failing to publish it must not keep it from being sent.
+	err = d.opts.Pubsub.Publish(InboxNotifyChannel(userID), payload)
+	if err != nil {
+		d.log.Warn(ctx, "publish inbox notification",
+			slog.F("user_id", userID),
+			slog.Error(err),
+		)
+	}
+	return nil
+}
+
+// enabled reports whether notifications are delivered anywhere besides the
+// inbox.
+func (d *Dispatcher) enabled() bool {
+	return d.opts.Sender != nil || len(d.opts.Routes) > 0
+}
+
+// Wake sends due notifications without waiting for the next poll.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops sending notifications. Notifications that are being sent are
+// retried once their lease expires.
+func (d *Dispatcher) Close() error {
+	d.cancel()
+	<-d.done
+	return nil
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		if !d.enabled() {
+			continue
+		}
+		err := d.sendDue()
+		if err != nil && d.ctx.Err() == nil {
+			d.log.Warn(d.ctx, "send notifications", slog.Error(err))
+		}
+	}
+}
+
+func (d *Dispatcher) sendDue() error {
+	now := dbtime.Now()
+	msgs, err := d.db.AcquireNotificationMessages(d.ctx, database.AcquireNotificationMessagesParams{
+		LeaseUntil: now.Add(leaseDuration),
+		Now:        now,
+		LimitOpt:   d.opts.BatchSize,
+	})
+	if err != nil {
+		return xerrors.Errorf("acquire notifications: %w", err)
+	}
+	for _, msg := range msgs {
+		err = d.attempt(msg)
+		if err != nil {
+			return xerrors.Errorf("update notification %s: %w", msg.ID, err)
+		}
+	}
+	return nil
+}
+
+// attempt sends a single notification and records the result. Notifications
+// that can never be sent, e.g. because the user was deleted, fail without
+// being retried.
+func (d *Dispatcher) attempt(msg database.NotificationMessage) error {
+	retry, sendErr := d.send(msg)
+
+	params := database.UpdateNotificationMessageByIDParams{
+		ID:       msg.ID,
+		Attempts: msg.Attempts + 1,
+	}
+	switch {
+	case sendErr == nil:
+		params.SentAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+	case !retry || params.Attempts >= d.opts.MaxAttempts:
+		params.LastError = sendErr.Error()
+		d.log.Warn(d.ctx, "notification failed permanently",
+			slog.F("notification_id", msg.ID),
+			slog.F("user_id", msg.UserID),
+			slog.F("template", msg.Template),
+			slog.F("attempts", params.Attempts),
+			slog.Error(sendErr),
+		)
+	default:
+		params.LastError = sendErr.Error()
+		backoff := d.opts.RetryInterval << (params.Attempts - 1)
+		params.NextAttemptAt = sql.NullTime{Time: dbtime.Now().Add(backoff), Valid: true}
+	}
+	return d.db.UpdateNotificationMessageByID(d.ctx, params)
+}
+
+// send renders and sends the notification. It reports whether a failure is
+// worth retrying.
+func (d *Dispatcher) send(msg database.NotificationMessage) (bool, error) {
+	user, err := d.db.GetUserByID(d.ctx, msg.UserID)
+	if err != nil {
+		return !xerrors.Is(err, sql.ErrNoRows), xerrors.Errorf("get user: %w", err)
+	}
+	if user.Deleted {
+		return false, xerrors.New("user is deleted")
+	}
+
+	var labels map[string]string
+	err = json.Unmarshal(msg.Labels, &labels)
+	if err != nil {
+		return false, xerrors.Errorf("unmarshal labels: %w", err)
+	}
+	name := codersdk.NotificationTemplate(msg.Template)
+	data := templateData{
+		UserName:  user.Username,
+		AccessURL: d.opts.AccessURL.String(),
+		Labels:    labels,
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, defaultSendTimeout)
+	defer cancel()
+
+	method := codersdk.NotificationMethod(msg.Method)
+	if method == codersdk.NotificationMethodSMTP {
+		if d.opts.Sender == nil {
+			return false, xerrors.New("email notifications are not configured")
+		}
+		subject, body, err := render(templates, name, data)
+		if err != nil {
+			return false, err
+		}
+		err = d.opts.Sender.Send(ctx, user.Email, subject, body)
+		if err != nil {
+			return true, xerrors.Errorf("send: %w", err)
+		}
+		return true, nil
+	}
+
+	for _, route := range d.opts.Routes {
+		if route.Method != method {
+			continue
+		}
+		title, text, err := render(chatTemplates, name, data)
+		if err != nil {
+			return false, err
+		}
+		err = route.Poster.Post(ctx, title, text)
+		if err != nil {
+			return true, xerrors.Errorf("post to %s: %w", method, err)
+		}
+		return true, nil
+	}
+	return false, xerrors.Errorf("%s notifications are not configured", method)
+}
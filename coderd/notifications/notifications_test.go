@@ -0,0 +1,289 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+type sentMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type fakeSender struct {
+	mu   sync.Mutex
+	err  error
+	sent chan sentMessage
+}
+
+func (s *fakeSender) Send(_ context.Context, to, subject, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.sent <- sentMessage{To: to, Subject: subject, Body: body}
+	return nil
+}
+
+type postedMessage struct {
+	Title string
+	Text  string
+}
+
+type fakePoster chan postedMessage
+
+func (p fakePoster) Post(ctx context.Context, title, text string) error {
+	select {
+	case p <- postedMessage{Title: title, Text: text}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sends", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitLong)
+			db, _  = dbtestutil.NewDB(t)
+			log    = slogtest.Make(t, nil)
+			sender = &fakeSender{sent: make(chan sentMessage, 1)}
+		)
+		user := dbgen.User(t, db, database.User{Username: "bob", Email: "bob@coder.com"})
+
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{Sender: sender})
+		defer dispatcher.Close()
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceBuildFailed, map[string]string{
+			"workspace_name": "dev",
+			"transition":     "start",
+			"build_number":   "3",
+			"reason":         "terraform exited",
+		})
+
+		msg := testutil.RequireRecvCtx(ctx, t, sender.sent)
+		require.Equal(t, "bob@coder.com", msg.To)
+		require.Equal(t, `Workspace "dev" failed to start`, msg.Subject)
+		require.Contains(t, msg.Body, "Hi bob,")
+		require.Contains(t, msg.Body, `Build #3 of your workspace "dev" failed to start.`)
+		require.Contains(t, msg.Body, "Reason: terraform exited")
+
+		require.Eventually(t, func() bool {
+			msgs, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+				UserID: user.ID,
+			})
+			if !assert.NoError(t, err) || !assert.Len(t, msgs, 1) {
+				return false
+			}
+			return msgs[0].SentAt.Valid && !msgs[0].NextAttemptAt.Valid && msgs[0].Attempts == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("RetriesThenFails", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitLong)
+			db, _  = dbtestutil.NewDB(t)
+			log    = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+			sender = &fakeSender{err: xerrors.New("connection refused")}
+		)
+		user := dbgen.User(t, db, database.User{})
+
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{
+			Sender:        sender,
+			MaxAttempts:   2,
+			RetryInterval: time.Millisecond,
+			PollInterval:  testutil.IntervalFast,
+		})
+		defer dispatcher.Close()
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserAccountCreated, nil)
+
+		var msg database.NotificationMessage
+		require.Eventually(t, func() bool {
+			msgs, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+				UserID: user.ID,
+			})
+			if !assert.NoError(t, err) || len(msgs) != 1 {
+				return false
+			}
+			msg = msgs[0]
+			return msg.Attempts == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.False(t, msg.SentAt.Valid)
+		require.False(t, msg.NextAttemptAt.Valid)
+		require.Contains(t, msg.LastError, "connection refused")
+	})
+
+	t.Run("SkipsDisabledTemplates", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitLong)
+			db, _  = dbtestutil.NewDB(t)
+			log    = slogtest.Make(t, nil)
+			sender = &fakeSender{sent: make(chan sentMessage, 1)}
+		)
+		user := dbgen.User(t, db, database.User{})
+		_, err := db.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+			UserID:    user.ID,
+			Template:  string(codersdk.NotificationTemplateWorkspaceDeleting),
+			Disabled:  true,
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{Sender: sender})
+		defer dispatcher.Close()
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceDeleting, nil)
+
+		msgs, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+		inbox, err := db.GetInboxNotificationsByUserID(ctx, database.GetInboxNotificationsByUserIDParams{
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, inbox)
+	})
+
+	t.Run("Inbox", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitLong)
+			db, ps = dbtestutil.NewDB(t)
+			log    = slogtest.Make(t, nil)
+		)
+		user := dbgen.User(t, db, database.User{})
+
+		published := make(chan database.InboxNotification, 1)
+		cancel, err := ps.Subscribe(notifications.InboxNotifyChannel(user.ID), func(_ context.Context, message []byte) {
+			var notif database.InboxNotification
+			if assert.NoError(t, json.Unmarshal(message, &notif)) {
+				published <- notif
+			}
+		})
+		require.NoError(t, err)
+		defer cancel()
+
+		// The inbox doesn't need a sender.
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{Pubsub: ps})
+		defer dispatcher.Close()
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserAccountCreated, map[string]string{
+			"created_by": "alice",
+		})
+
+		notif := testutil.RequireRecvCtx(ctx, t, published)
+		require.Equal(t, "Your Coder account has been created", notif.Title)
+		require.Equal(t, "alice created a Coder account for you.", notif.Content)
+		require.False(t, notif.ReadAt.Valid)
+
+		inbox, err := db.GetInboxNotificationsByUserID(ctx, database.GetInboxNotificationsByUserIDParams{
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, inbox, 1)
+		require.Equal(t, notif.ID, inbox[0].ID)
+	})
+
+	t.Run("Routes", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx    = testutil.Context(t, testutil.WaitLong)
+			db, _  = dbtestutil.NewDB(t)
+			log    = slogtest.Make(t, nil)
+			poster = make(fakePoster, 1)
+		)
+		user := dbgen.User(t, db, database.User{Username: "bob"})
+		// Routes post to a shared channel, so they ignore the preferences
+		// of the user.
+		_, err := db.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+			UserID:    user.ID,
+			Template:  string(codersdk.NotificationTemplateWorkspaceBuildFailed),
+			Disabled:  true,
+			UpdatedAt: dbtime.Now(),
+		})
+		require.NoError(t, err)
+
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{
+			Routes: []notifications.Route{{
+				Method:    codersdk.NotificationMethodSlack,
+				Poster:    poster,
+				Templates: []codersdk.NotificationTemplate{codersdk.NotificationTemplateWorkspaceBuildFailed},
+			}},
+		})
+		defer dispatcher.Close()
+		// Templates that aren't routed are not posted.
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserAccountCreated, nil)
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateWorkspaceBuildFailed, map[string]string{
+			"workspace_name": "dev",
+			"transition":     "start",
+			"build_number":   "3",
+		})
+
+		msg := testutil.RequireRecvCtx(ctx, t, poster)
+		require.Equal(t, `Workspace "dev" failed to start`, msg.Title)
+		require.Contains(t, msg.Text, `Build #3 of the workspace "dev" of bob failed to start.`)
+
+		require.Eventually(t, func() bool {
+			msgs, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+				UserID: user.ID,
+			})
+			if !assert.NoError(t, err) || !assert.Len(t, msgs, 1) {
+				return false
+			}
+			return msgs[0].Method == string(codersdk.NotificationMethodSlack) && msgs[0].SentAt.Valid
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("NoSender", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, nil)
+		)
+		user := dbgen.User(t, db, database.User{})
+
+		dispatcher := notifications.New(ctx, db, log, notifications.Options{})
+		defer dispatcher.Close()
+		dispatcher.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserAccountCreated, nil)
+
+		msgs, err := db.GetNotificationMessagesByUserID(ctx, database.GetNotificationMessagesByUserIDParams{
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+	})
+}
@@ -0,0 +1,119 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+var _ Sender = (*SMTPSender)(nil)
+
+// SMTPSender sends notifications as plain text emails through an SMTP
+// server. STARTTLS is used when the server supports it.
+type SMTPSender struct {
+	// Smarthost is the SMTP server in host:port form.
+	Smarthost string
+	From      string
+	// Username and Password authenticate with PLAIN auth when the username
+	// is set.
+	Username string
+	Password string
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	host, _, err := net.SplitHostPort(s.Smarthost)
+	if err != nil {
+		return xerrors.Errorf("parse smarthost: %w", err)
+	}
+	msg, err := s.message(to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Smarthost)
+	if err != nil {
+		return xerrors.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return xerrors.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		})
+		if err != nil {
+			return xerrors.Errorf("starttls: %w", err)
+		}
+	}
+	if s.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", s.Username, s.Password, host))
+		if err != nil {
+			return xerrors.Errorf("auth: %w", err)
+		}
+	}
+	err = client.Mail(s.From)
+	if err != nil {
+		return xerrors.Errorf("mail from: %w", err)
+	}
+	err = client.Rcpt(to)
+	if err != nil {
+		return xerrors.Errorf("rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return xerrors.Errorf("data: %w", err)
+	}
+	_, err = w.Write(msg)
+	if err != nil {
+		return xerrors.Errorf("write message: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return xerrors.Errorf("close message: %w", err)
+	}
+	return client.Quit()
+}
+
+func (s *SMTPSender) message(to, subject, body string) ([]byte, error) {
+	// Strip newlines so the subject can't inject headers.
+	subject = strings.NewReplacer("\r", "", "\n", " ").Replace(subject)
+
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "From: %s\r\n", s.From)
+	_, _ = fmt.Fprintf(&buf, "To: %s\r\n", to)
+	_, _ = fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	_, _ = fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	_, _ = buf.WriteString("MIME-Version: 1.0\r\n")
+	_, _ = buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	_, _ = buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	_, err := w.Write([]byte(body))
+	if err != nil {
+		return nil, xerrors.Errorf("encode body: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("encode body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+package notifications_test
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSMTPSender(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	data := make(chan string, 1)
+	go serveSMTP(t, l, data)
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	sender := &notifications.SMTPSender{
+		Smarthost: l.Addr().String(),
+		From:      "coder@coder.com",
+	}
+	err = sender.Send(ctx, "bob@coder.com", "Hello\r\nBcc: eve@coder.com", "Hi bob,\n")
+	require.NoError(t, err)
+
+	msg := testutil.RequireRecvCtx(ctx, t, data)
+	require.Contains(t, msg, "From: coder@coder.com\r\n")
+	require.Contains(t, msg, "To: bob@coder.com\r\n")
+	// Newlines in the subject must not inject headers.
+	require.Contains(t, msg, "Subject: Hello Bcc: eve@coder.com\r\n")
+	require.Contains(t, msg, "Hi bob,")
+}
+
+// serveSMTP accepts a single connection and speaks just enough SMTP to
+// receive one message, which is sent on data.
+func serveSMTP(t *testing.T, l net.Listener, data chan<- string) {
+	conn, err := l.Accept()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	reply := func(code int, msg string) {
+		assert.NoError(t, tp.PrintfLine("%d %s", code, msg))
+	}
+	reply(220, "localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply(250, "localhost")
+		case "MAIL", "RCPT":
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "Go ahead")
+			body, err := readDotBytes(tp.R)
+			if !assert.NoError(t, err) {
+				return
+			}
+			data <- body
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "Bye")
+			return
+		default:
+			reply(502, "Not implemented")
+		}
+	}
+}
+
+func readDotBytes(r *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" {
+			return sb.String(), nil
+		}
+		_, _ = sb.WriteString(line)
+	}
+}
@@ -0,0 +1,142 @@
+package notifications
+
+import (
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// templateData is available to every template. Labels are set by whoever
+// queued the notification and differ per template.
+type templateData struct {
+	UserName  string
+	AccessURL string
+	Labels    map[string]string
+}
+
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func mustTemplate(name, subject, body string) notificationTemplate {
+	return notificationTemplate{
+		subject: template.Must(template.New(name + "_subject").Option("missingkey=zero").Parse(subject)),
+		body:    template.Must(template.New(name + "_body").Option("missingkey=zero").Parse(body)),
+	}
+}
+
+var templates = map[codersdk.NotificationTemplate]notificationTemplate{
+	codersdk.NotificationTemplateWorkspaceDeleting: mustTemplate(
+		string(codersdk.NotificationTemplateWorkspaceDeleting),
+		`Workspace "{{ .Labels.workspace_name }}" will be deleted soon`,
+		`Hi {{ .UserName }},
+
+Your workspace "{{ .Labels.workspace_name }}" has not been used for a while and
+is now dormant. It will be deleted on {{ .Labels.deleting_at }} unless you
+start it before then.
+
+View your workspaces: {{ .AccessURL }}/workspaces
+`,
+	),
+	codersdk.NotificationTemplateWorkspaceBuildFailed: mustTemplate(
+		string(codersdk.NotificationTemplateWorkspaceBuildFailed),
+		`Workspace "{{ .Labels.workspace_name }}" failed to {{ .Labels.transition }}`,
+		`Hi {{ .UserName }},
+
+Build #{{ .Labels.build_number }} of your workspace "{{ .Labels.workspace_name }}" failed to {{ .Labels.transition }}.
+{{- with .Labels.reason }}
+
+Reason: {{ . }}
+{{- end }}
+
+View your workspaces: {{ .AccessURL }}/workspaces
+`,
+	),
+	codersdk.NotificationTemplateUserAccountCreated: mustTemplate(
+		string(codersdk.NotificationTemplateUserAccountCreated),
+		`Your Coder account has been created`,
+		`Hi {{ .UserName }},
+
+{{ with .Labels.created_by }}{{ . }} created a Coder account for you{{ else }}A Coder account has been created for you{{ end }}.
+
+Log in: {{ .AccessURL }}/login
+`,
+	),
+}
+
+// inboxTemplates are shown in the inbox of the user. The subject is used as
+// the title of the notification and the body as its content.
+var inboxTemplates = map[codersdk.NotificationTemplate]notificationTemplate{
+	codersdk.NotificationTemplateWorkspaceDeleting: mustTemplate(
+		"inbox_"+string(codersdk.NotificationTemplateWorkspaceDeleting),
+		`Workspace "{{ .Labels.workspace_name }}" will be deleted soon`,
+		`Your workspace "{{ .Labels.workspace_name }}" is dormant and will be deleted on {{ .Labels.deleting_at }} unless you start it before then.`,
+	),
+	codersdk.NotificationTemplateWorkspaceBuildFailed: mustTemplate(
+		"inbox_"+string(codersdk.NotificationTemplateWorkspaceBuildFailed),
+		`Workspace "{{ .Labels.workspace_name }}" failed to {{ .Labels.transition }}`,
+		`Build #{{ .Labels.build_number }} of your workspace "{{ .Labels.workspace_name }}" failed to {{ .Labels.transition }}.{{ with .Labels.reason }} Reason: {{ . }}{{ end }}`,
+	),
+	codersdk.NotificationTemplateUserAccountCreated: mustTemplate(
+		"inbox_"+string(codersdk.NotificationTemplateUserAccountCreated),
+		`Your Coder account has been created`,
+		`{{ with .Labels.created_by }}{{ . }} created a Coder account for you{{ else }}A Coder account has been created for you{{ end }}.`,
+	),
+}
+
+// chatTemplates are posted to chat channels. The subject is used as the title
+// of the message. Unlike emails they are read by the whole channel, so they
+// name the user the notification is about.
+var chatTemplates = map[codersdk.NotificationTemplate]notificationTemplate{
+	codersdk.NotificationTemplateWorkspaceDeleting: mustTemplate(
+		"chat_"+string(codersdk.NotificationTemplateWorkspaceDeleting),
+		`Workspace "{{ .Labels.workspace_name }}" will be deleted soon`,
+		`The dormant workspace "{{ .Labels.workspace_name }}" of {{ .UserName }} will be deleted on {{ .Labels.deleting_at }}.
+
+{{ .AccessURL }}/@{{ .UserName }}/{{ .Labels.workspace_name }}`,
+	),
+	codersdk.NotificationTemplateWorkspaceBuildFailed: mustTemplate(
+		"chat_"+string(codersdk.NotificationTemplateWorkspaceBuildFailed),
+		`Workspace "{{ .Labels.workspace_name }}" failed to {{ .Labels.transition }}`,
+		`Build #{{ .Labels.build_number }} of the workspace "{{ .Labels.workspace_name }}" of {{ .UserName }} failed to {{ .Labels.transition }}.
+{{- with .Labels.reason }}
+
+Reason: {{ . }}
+{{- end }}
+
+{{ .AccessURL }}/@{{ .UserName }}/{{ .Labels.workspace_name }}`,
+	),
+	codersdk.NotificationTemplateUserAccountCreated: mustTemplate(
+		"chat_"+string(codersdk.NotificationTemplateUserAccountCreated),
+		`User "{{ .UserName }}" was created`,
+		`{{ with .Labels.created_by }}{{ . }} created the account of {{ $.UserName }}{{ else }}The account of {{ .UserName }} was created{{ end }}.
+
+{{ .AccessURL }}/users`,
+	),
+}
+
+// render returns the subject and body of a notification from one of the
+// template sets.
+func render(set map[codersdk.NotificationTemplate]notificationTemplate, name codersdk.NotificationTemplate, data templateData) (subject string, body string, err error) {
+	tmpl, ok := set[name]
+	if !ok {
+		return "", "", xerrors.Errorf("unknown notification template %q", name)
+	}
+	var sb strings.Builder
+	err = tmpl.subject.Execute(&sb, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render subject: %w", err)
+	}
+	subject = sb.String()
+
+	sb.Reset()
+	err = tmpl.body.Execute(&sb, data)
+	if err != nil {
+		return "", "", xerrors.Errorf("render body: %w", err)
+	}
+	return subject, sb.String(), nil
+}
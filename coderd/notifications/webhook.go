@@ -0,0 +1,76 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// SlackPoster posts notifications to a Slack channel through an incoming
+// webhook.
+type SlackPoster struct {
+	WebhookURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Poster = (*SlackPoster)(nil)
+
+func (p *SlackPoster) Post(ctx context.Context, title, text string) error {
+	return postJSON(ctx, p.HTTPClient, p.WebhookURL, map[string]string{
+		"text": "*" + title + "*\n" + text,
+	})
+}
+
+// TeamsPoster posts notifications to a Microsoft Teams channel through an
+// incoming webhook connector.
+type TeamsPoster struct {
+	WebhookURL string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Poster = (*TeamsPoster)(nil)
+
+func (p *TeamsPoster) Post(ctx context.Context, title, text string) error {
+	return postJSON(ctx, p.HTTPClient, p.WebhookURL, map[string]string{
+		"@type":    "MessageCard",
+		"@context": "https://schema.org/extensions",
+		"summary":  title,
+		"title":    title,
+		"text":     text,
+	})
+}
+
+// postJSON POSTs the payload to the URL. Any response other than a 2xx is
+// treated as a failure.
+func postJSON(ctx context.Context, client *http.Client, url string, payload any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
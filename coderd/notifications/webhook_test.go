@@ -0,0 +1,79 @@
+package notifications_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/notifications"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPosters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Slack", func(t *testing.T) {
+		t.Parallel()
+
+		payloads := make(chan map[string]string, 1)
+		srv := httptest.NewServer(payloadHandler(t, payloads, http.StatusOK))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		poster := &notifications.SlackPoster{WebhookURL: srv.URL}
+		err := poster.Post(ctx, "Build failed", "Workspace dev failed to start.")
+		require.NoError(t, err)
+
+		payload := testutil.RequireRecvCtx(ctx, t, payloads)
+		require.Equal(t, "*Build failed*\nWorkspace dev failed to start.", payload["text"])
+	})
+
+	t.Run("Teams", func(t *testing.T) {
+		t.Parallel()
+
+		payloads := make(chan map[string]string, 1)
+		srv := httptest.NewServer(payloadHandler(t, payloads, http.StatusOK))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		poster := &notifications.TeamsPoster{WebhookURL: srv.URL}
+		err := poster.Post(ctx, "Build failed", "Workspace dev failed to start.")
+		require.NoError(t, err)
+
+		payload := testutil.RequireRecvCtx(ctx, t, payloads)
+		require.Equal(t, "MessageCard", payload["@type"])
+		require.Equal(t, "Build failed", payload["title"])
+		require.Equal(t, "Workspace dev failed to start.", payload["text"])
+	})
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		t.Parallel()
+
+		payloads := make(chan map[string]string, 1)
+		srv := httptest.NewServer(payloadHandler(t, payloads, http.StatusForbidden))
+		t.Cleanup(srv.Close)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		poster := &notifications.SlackPoster{WebhookURL: srv.URL}
+		err := poster.Post(ctx, "Build failed", "Workspace dev failed to start.")
+		require.ErrorContains(t, err, "unexpected status code 403")
+	})
+}
+
+// payloadHandler decodes the JSON payload of each request, sends it on
+// payloads and responds with status.
+func payloadHandler(t *testing.T, payloads chan<- map[string]string, status int) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload)) {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payloads <- payload
+		rw.WriteHeader(status)
+	}
+}
@@ -0,0 +1,108 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+type sentNotification struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type fakeNotificationSender chan sentNotification
+
+func (s fakeNotificationSender) Send(ctx context.Context, to, subject, body string) error {
+	select {
+	case s <- sentNotification{To: to, Subject: subject, Body: body}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestUserNotifications(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AccountCreated", func(t *testing.T) {
+		t.Parallel()
+
+		sender := make(fakeNotificationSender, 1)
+		client := coderdtest.New(t, &coderdtest.Options{NotificationSender: sender})
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		sent := testutil.RequireRecvCtx(ctx, t, sender)
+		require.Equal(t, member.Email, sent.To)
+		require.Equal(t, "Your Coder account has been created", sent.Subject)
+		require.Contains(t, sent.Body, coderdtest.FirstUserParams.Username+" created a Coder account for you")
+
+		require.Eventually(t, func() bool {
+			msgs, err := client.UserNotifications(ctx, member.ID.String())
+			if err != nil || len(msgs) != 1 {
+				return false
+			}
+			return msgs[0].Status == codersdk.NotificationMessageStatusSent
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("Preferences", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Every template is enabled by default.
+		prefs, err := member.NotificationPreferences(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Len(t, prefs, len(codersdk.NotificationTemplates))
+		for _, pref := range prefs {
+			require.False(t, pref.Disabled)
+		}
+
+		prefs, err = member.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{
+				{Template: codersdk.NotificationTemplateWorkspaceDeleting, Disabled: true},
+			},
+		})
+		require.NoError(t, err)
+		for _, pref := range prefs {
+			require.Equal(t, pref.Template == codersdk.NotificationTemplateWorkspaceDeleting, pref.Disabled)
+		}
+
+		_, err = member.UpdateNotificationPreferences(ctx, codersdk.Me, codersdk.UpdateNotificationPreferencesRequest{
+			Preferences: []codersdk.NotificationPreference{
+				{Template: "unknown", Disabled: true},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OtherUserNotFound", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The user param middleware rejects users the member can't see.
+		_, err := member.UserNotifications(ctx, owner.UserID.String())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
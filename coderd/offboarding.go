@@ -0,0 +1,429 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// userOffboarding is what changes when a user is offboarded. The workspaces,
+// groups and user are the old values, the updated fields the new ones.
+type userOffboarding struct {
+	user              database.User
+	updatedUser       database.User
+	workspaces        []database.Workspace
+	updatedWorkspaces []database.Workspace
+	apiKeys           []database.APIKey
+	externalAuthLinks []database.ExternalAuthLink
+	groups            []database.Group
+	groupMembers      map[uuid.UUID][]database.User
+}
+
+// @Summary Offboard user
+// @ID offboard-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.OffboardUserRequest true "Offboard user request"
+// @Success 200 {object} codersdk.OffboardUserResponse
+// @Router /users/{user}/offboard [post]
+func (api *API) postUserOffboard(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.OffboardUserRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !req.DryRun {
+		// A dry run changes nothing, so there is nothing to audit.
+		aReq.Old = user
+	}
+
+	switch {
+	case user.ID == apiKey.UserID:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot offboard yourself.",
+		})
+		return
+	case slice.Contains(user.RBACRoles, rbac.RoleOwner()):
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("You cannot offboard a user with the %q role. You must remove the role first.", rbac.RoleOwner()),
+		})
+		return
+	case req.WorkspaceAction == codersdk.OffboardWorkspaceActionTransfer && req.TransferTo == uuid.Nil:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "A user to transfer the workspaces to is required.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "transfer_to",
+				Detail: "required when the workspace action is transfer",
+			}},
+		})
+		return
+	case req.WorkspaceAction == codersdk.OffboardWorkspaceActionTransfer && req.TransferTo == user.ID:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot transfer workspaces to the user being offboarded.",
+		})
+		return
+	}
+
+	// Offboarding takes away the user's access to Coder, so it needs the same
+	// permission as deleting the user.
+	if !api.Authorize(r, rbac.ActionDelete, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var off userOffboarding
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		off, err = api.offboardUser(r, tx, user, req)
+		return err
+	}, nil)
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpErr.Write(rw, r)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error offboarding user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if !req.DryRun {
+		aReq.New = off.updatedUser
+		api.auditOffboarding(r, off)
+	}
+
+	organizationIDs, err := userOrganizationIDs(ctx, api, off.updatedUser)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user's organizations.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.OffboardUserResponse{
+		DryRun:                       req.DryRun,
+		WorkspaceAction:              req.WorkspaceAction,
+		User:                         db2sdk.User(off.updatedUser, organizationIDs),
+		Workspaces:                   make([]codersdk.OffboardedWorkspace, 0, len(off.workspaces)),
+		RevokedAPIKeyIDs:             make([]string, 0, len(off.apiKeys)),
+		RevokedExternalAuthProviders: make([]string, 0, len(off.externalAuthLinks)),
+		RemovedGroups:                make([]codersdk.OffboardedGroup, 0, len(off.groups)),
+	}
+	for _, workspace := range off.workspaces {
+		offboarded := codersdk.OffboardedWorkspace{
+			ID:             workspace.ID,
+			Name:           workspace.Name,
+			OrganizationID: workspace.OrganizationID,
+		}
+		if req.WorkspaceAction == codersdk.OffboardWorkspaceActionTransfer {
+			newOwnerID := req.TransferTo
+			offboarded.NewOwnerID = &newOwnerID
+		}
+		resp.Workspaces = append(resp.Workspaces, offboarded)
+	}
+	for _, key := range off.apiKeys {
+		resp.RevokedAPIKeyIDs = append(resp.RevokedAPIKeyIDs, key.ID)
+	}
+	for _, link := range off.externalAuthLinks {
+		resp.RevokedExternalAuthProviders = append(resp.RevokedExternalAuthProviders, link.ProviderID)
+	}
+	for _, group := range off.groups {
+		resp.RemovedGroups = append(resp.RemovedGroups, codersdk.OffboardedGroup{
+			ID:             group.ID,
+			Name:           group.Name,
+			OrganizationID: group.OrganizationID,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// offboardUser finds what has to change to offboard the user, checks that the
+// caller may change it, and unless it's a dry run, makes the changes. It runs
+// in a transaction, so a failure part way leaves the user as they were.
+// Expected failures are returned as an httpError.
+func (api *API) offboardUser(r *http.Request, tx database.Store, user database.User, req codersdk.OffboardUserRequest) (userOffboarding, error) {
+	// The caller is authorized for every change below. Reads must not leave
+	// out resources the caller can't see, or the user would keep access to
+	// them.
+	//nolint:gocritic // Offboarding needs to see all of the user's resources.
+	ctx := dbauthz.AsSystemRestricted(r.Context())
+	off := userOffboarding{
+		user:         user,
+		updatedUser:  user,
+		groupMembers: map[uuid.UUID][]database.User{},
+	}
+	transfer := req.WorkspaceAction == codersdk.OffboardWorkspaceActionTransfer
+
+	var newOwner database.User
+	var newOwnerOrganizationIDs []uuid.UUID
+	if transfer {
+		var err error
+		newOwner, err = tx.GetUserByID(ctx, req.TransferTo)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && newOwner.Deleted) {
+			return off, httpError{
+				code: http.StatusBadRequest,
+				msg:  "The user to transfer the workspaces to does not exist.",
+			}
+		}
+		if err != nil {
+			return off, xerrors.Errorf("get user to transfer to: %w", err)
+		}
+		if newOwner.Status == database.UserStatusSuspended {
+			return off, httpError{
+				code: http.StatusBadRequest,
+				msg:  fmt.Sprintf("Workspaces can't be transferred to %q, the user is suspended.", newOwner.Username),
+			}
+		}
+		memberships, err := tx.GetOrganizationIDsByMemberIDs(ctx, []uuid.UUID{newOwner.ID})
+		if err != nil {
+			return off, xerrors.Errorf("get organizations of user to transfer to: %w", err)
+		}
+		if len(memberships) > 0 {
+			newOwnerOrganizationIDs = memberships[0].OrganizationIDs
+		}
+	}
+
+	rows, err := tx.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		return off, xerrors.Errorf("get workspaces: %w", err)
+	}
+	off.workspaces = database.ConvertWorkspaceRows(rows)
+	for _, workspace := range off.workspaces {
+		if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+			return off, httpError{
+				code: http.StatusForbidden,
+				msg:  fmt.Sprintf("You are not allowed to update the workspace %q.", workspace.Name),
+			}
+		}
+		if !transfer {
+			continue
+		}
+		if !slice.Contains(newOwnerOrganizationIDs, workspace.OrganizationID) {
+			return off, httpError{
+				code: http.StatusBadRequest,
+				msg:  fmt.Sprintf("%q is not a member of the organization of the workspace %q.", newOwner.Username, workspace.Name),
+			}
+		}
+		_, err := tx.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+			OwnerID: newOwner.ID,
+			Name:    workspace.Name,
+		})
+		if err == nil {
+			return off, httpError{
+				code: http.StatusConflict,
+				msg:  fmt.Sprintf("%q already has a workspace named %q. Rename one of the workspaces and try again.", newOwner.Username, workspace.Name),
+			}
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return off, xerrors.Errorf("get workspace of user to transfer to: %w", err)
+		}
+	}
+
+	for _, loginType := range database.AllLoginTypeValues() {
+		keys, err := tx.GetAPIKeysByUserID(ctx, database.GetAPIKeysByUserIDParams{
+			LoginType: loginType,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return off, xerrors.Errorf("get %s api keys: %w", loginType, err)
+		}
+		off.apiKeys = append(off.apiKeys, keys...)
+	}
+
+	off.externalAuthLinks, err = tx.GetExternalAuthLinksByUserID(ctx, user.ID)
+	if err != nil {
+		return off, xerrors.Errorf("get external auth links: %w", err)
+	}
+
+	memberships, err := tx.GetOrganizationIDsByMemberIDs(ctx, []uuid.UUID{user.ID})
+	if err != nil {
+		return off, xerrors.Errorf("get organizations: %w", err)
+	}
+	for _, membership := range memberships {
+		for _, organizationID := range membership.OrganizationIDs {
+			groups, err := tx.GetGroupsByOrganizationAndUserID(ctx, database.GetGroupsByOrganizationAndUserIDParams{
+				OrganizationID: organizationID,
+				UserID:         user.ID,
+			})
+			if err != nil {
+				return off, xerrors.Errorf("get groups: %w", err)
+			}
+			for _, group := range groups {
+				if !api.Authorize(r, rbac.ActionUpdate, group) {
+					return off, httpError{
+						code: http.StatusForbidden,
+						msg:  fmt.Sprintf("You are not allowed to update the group %q.", group.Name),
+					}
+				}
+				members, err := tx.GetGroupMembers(ctx, group.ID)
+				if err != nil {
+					return off, xerrors.Errorf("get members of group %q: %w", group.Name, err)
+				}
+				off.groups = append(off.groups, group)
+				off.groupMembers[group.ID] = members
+			}
+		}
+	}
+
+	if req.DryRun {
+		return off, nil
+	}
+
+	now := dbtime.Now()
+	for _, workspace := range off.workspaces {
+		// Workspaces that are already dormant are left as they are.
+		updated := workspace
+		var err error
+		switch {
+		case transfer:
+			updated, err = tx.UpdateWorkspaceOwnerByID(ctx, database.UpdateWorkspaceOwnerByIDParams{
+				ID:        workspace.ID,
+				OwnerID:   newOwner.ID,
+				UpdatedAt: now,
+			})
+		case !workspace.DormantAt.Valid:
+			updated, err = tx.UpdateWorkspaceDormantDeletingAt(ctx, database.UpdateWorkspaceDormantDeletingAtParams{
+				ID: workspace.ID,
+				DormantAt: sql.NullTime{
+					Time:  now,
+					Valid: true,
+				},
+			})
+		}
+		if err != nil {
+			return off, xerrors.Errorf("%s workspace %q: %w", req.WorkspaceAction, workspace.Name, err)
+		}
+		off.updatedWorkspaces = append(off.updatedWorkspaces, updated)
+	}
+
+	err = tx.DeleteAPIKeysByUserID(ctx, user.ID)
+	if err != nil {
+		return off, xerrors.Errorf("delete api keys: %w", err)
+	}
+
+	for _, link := range off.externalAuthLinks {
+		err = tx.DeleteExternalAuthLink(ctx, database.DeleteExternalAuthLinkParams{
+			ProviderID: link.ProviderID,
+			UserID:     user.ID,
+		})
+		if err != nil {
+			return off, xerrors.Errorf("delete external auth link %q: %w", link.ProviderID, err)
+		}
+	}
+
+	for _, group := range off.groups {
+		err = tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+			UserID:  user.ID,
+			GroupID: group.ID,
+		})
+		if err != nil {
+			return off, xerrors.Errorf("remove from group %q: %w", group.Name, err)
+		}
+	}
+
+	off.updatedUser, err = tx.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+		ID:        user.ID,
+		Status:    database.UserStatusSuspended,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return off, xerrors.Errorf("suspend user: %w", err)
+	}
+	return off, nil
+}
+
+// auditOffboarding records every change made by offboarding a user, with the
+// request ID of the offboarding request so they can be found together. The
+// suspension of the user is audited by the request itself.
+func (api *API) auditOffboarding(r *http.Request, off userOffboarding) {
+	var (
+		ctx       = r.Context()
+		auditor   = *api.Auditor.Load()
+		userID    = httpmw.APIKey(r).UserID
+		requestID = httpmw.RequestID(r)
+	)
+	for i, workspace := range off.workspaces {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.Workspace]{
+			Audit:          auditor,
+			Log:            api.Logger,
+			UserID:         userID,
+			RequestID:      requestID,
+			Status:         http.StatusOK,
+			Action:         database.AuditActionWrite,
+			OrganizationID: workspace.OrganizationID,
+			IP:             r.RemoteAddr,
+			Old:            workspace,
+			New:            off.updatedWorkspaces[i],
+		})
+	}
+	for _, key := range off.apiKeys {
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.APIKey]{
+			Audit:     auditor,
+			Log:       api.Logger,
+			UserID:    userID,
+			RequestID: requestID,
+			Status:    http.StatusOK,
+			Action:    database.AuditActionDelete,
+			IP:        r.RemoteAddr,
+			Old:       key,
+		})
+	}
+	for _, group := range off.groups {
+		members := off.groupMembers[group.ID]
+		remaining := make([]database.User, 0, len(members))
+		for _, member := range members {
+			if member.ID != off.user.ID {
+				remaining = append(remaining, member)
+			}
+		}
+		audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.AuditableGroup]{
+			Audit:          auditor,
+			Log:            api.Logger,
+			UserID:         userID,
+			RequestID:      requestID,
+			Status:         http.StatusOK,
+			Action:         database.AuditActionWrite,
+			OrganizationID: group.OrganizationID,
+			IP:             r.RemoteAddr,
+			Old:            group.Auditable(members),
+			New:            group.Auditable(remaining),
+		})
+	}
+}
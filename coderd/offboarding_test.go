@@ -0,0 +1,201 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestOffboardUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Transfer", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, newOwner := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        member.ID,
+		}).Do().Workspace
+		group := dbgen.Group(t, db, database.Group{OrganizationID: owner.OrganizationID})
+		dbgen.GroupMember(t, db, database.GroupMember{GroupID: group.ID, UserID: member.ID})
+		dbgen.GroupMember(t, db, database.GroupMember{GroupID: group.ID, UserID: newOwner.ID})
+		link := dbgen.ExternalAuthLink(t, db, database.ExternalAuthLink{UserID: member.ID})
+
+		auditor.ResetLogs()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		req := codersdk.OffboardUserRequest{
+			WorkspaceAction: codersdk.OffboardWorkspaceActionTransfer,
+			TransferTo:      newOwner.ID,
+			DryRun:          true,
+		}
+
+		// A dry run reports the changes without making them.
+		res, err := client.OffboardUser(ctx, member.Username, req)
+		require.NoError(t, err)
+		require.True(t, res.DryRun)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, workspace.ID, res.Workspaces[0].ID)
+		require.NotNil(t, res.Workspaces[0].NewOwnerID)
+		require.Equal(t, newOwner.ID, *res.Workspaces[0].NewOwnerID)
+		require.NotEmpty(t, res.RevokedAPIKeyIDs)
+		require.Equal(t, []string{link.ProviderID}, res.RevokedExternalAuthProviders)
+		require.Len(t, res.RemovedGroups, 1)
+		require.Equal(t, group.ID, res.RemovedGroups[0].ID)
+		require.Equal(t, codersdk.UserStatusActive, res.User.Status)
+		_, err = memberClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Empty(t, auditor.AuditLogs())
+
+		req.DryRun = false
+		res, err = client.OffboardUser(ctx, member.Username, req)
+		require.NoError(t, err)
+		require.False(t, res.DryRun)
+		require.Equal(t, codersdk.UserStatusSuspended, res.User.Status)
+
+		got, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, newOwner.ID, got.OwnerID)
+
+		_, err = memberClient.User(ctx, codersdk.Me)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		//nolint:gocritic // Checking the database directly.
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		links, err := db.GetExternalAuthLinksByUserID(sysCtx, member.ID)
+		require.NoError(t, err)
+		require.Empty(t, links)
+		members, err := db.GetGroupMembers(sysCtx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, newOwner.ID, members[0].ID)
+
+		for _, resource := range []struct {
+			typ database.ResourceType
+			id  uuid.UUID
+		}{
+			{database.ResourceTypeUser, member.ID},
+			{database.ResourceTypeWorkspace, workspace.ID},
+			{database.ResourceTypeGroup, group.ID},
+		} {
+			found := false
+			for _, alog := range auditor.AuditLogs() {
+				if alog.ResourceType == resource.typ && alog.ResourceID == resource.id {
+					found = true
+				}
+			}
+			assert.True(t, found, "expected audit log for %s %s", resource.typ, resource.id)
+		}
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        member.ID,
+		}).Do().Workspace
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.OffboardUser(ctx, member.Username, codersdk.OffboardUserRequest{
+			WorkspaceAction: codersdk.OffboardWorkspaceActionArchive,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Nil(t, res.Workspaces[0].NewOwnerID)
+
+		got, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, member.ID, got.OwnerID)
+		require.NotNil(t, got.DormantAt)
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		_, newOwner := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        member.ID,
+		}).Do().Workspace
+		dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: owner.OrganizationID,
+			OwnerID:        newOwner.ID,
+			Name:           workspace.Name,
+		}).Do()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.OffboardUser(ctx, member.Username, codersdk.OffboardUserRequest{
+			WorkspaceAction: codersdk.OffboardWorkspaceActionTransfer,
+			TransferTo:      newOwner.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Nothing was changed.
+		got, err := memberClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusActive, got.Status)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.OffboardUser(ctx, codersdk.Me, codersdk.OffboardUserRequest{
+			WorkspaceAction: codersdk.OffboardWorkspaceActionArchive,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NoPermission", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		// Template admins can see other users, but not delete them.
+		templateAdminClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleTemplateAdmin())
+		_, other := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := templateAdminClient.OffboardUser(ctx, other.Username, codersdk.OffboardUserRequest{
+			WorkspaceAction: codersdk.OffboardWorkspaceActionArchive,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -42,8 +45,18 @@ func (*API) organization(rw http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} codersdk.Organization
 // @Router /organizations [post]
 func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	apiKey := httpmw.APIKey(r)
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
 
 	var req codersdk.CreateOrganizationRequest
 	if !httpapi.Read(ctx, rw, r, &req) {
@@ -82,12 +95,9 @@ func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 			UserID:         apiKey.UserID,
 			CreatedAt:      dbtime.Now(),
 			UpdatedAt:      dbtime.Now(),
+			// The creator administers the organization.
 			Roles: []string{
-				// TODO: When organizations are allowed to be created, we should
-				// come back to determining the default role of the person who
-				// creates the org. Until that happens, all users in an organization
-				// should be just regular members.
-				rbac.RoleOrgMember(organization.ID),
+				rbac.RoleOrgAdmin(organization.ID),
 			},
 		})
 		if err != nil {
@@ -108,9 +118,139 @@ func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	aReq.New = organization
 	httpapi.Write(ctx, rw, http.StatusCreated, convertOrganization(organization))
 }
 
+// @Summary Delete organization
+// @ID delete-organization
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /organizations/{organization} [delete]
+func (api *API) deleteOrganization(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		organization      = httpmw.OrganizationParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Organization](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = organization
+
+	// Organization admins can't delete their own organization, so this is
+	// not scoped to the organization.
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceOrganization.WithID(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// The checks run in the same serializable transaction as the delete. A
+	// template or membership changed concurrently makes the transaction
+	// conflict and run again, so the checks can't be bypassed.
+	err := api.Database.InTx(func(tx database.Store) error {
+		// The checks must see every template and member, not just the ones
+		// the caller can read.
+		// nolint:gocritic
+		sysCtx := dbauthz.AsSystemRestricted(ctx)
+		templates, err := tx.GetTemplatesWithFilter(sysCtx, database.GetTemplatesWithFilterParams{
+			OrganizationID: organization.ID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("get templates: %w", err)
+		}
+		if len(templates) > 0 {
+			return httpError{
+				code: http.StatusBadRequest,
+				msg:  "All templates must be deleted before an organization can be removed.",
+			}
+		}
+
+		// Every user must belong to at least one organization.
+		members, err := tx.GetOrganizationMembers(sysCtx, organization.ID)
+		if err != nil {
+			return xerrors.Errorf("get organization members: %w", err)
+		}
+		if len(members) > 0 {
+			userIDs := make([]uuid.UUID, 0, len(members))
+			for _, member := range members {
+				userIDs = append(userIDs, member.UserID)
+			}
+			memberships, err := tx.GetOrganizationIDsByMemberIDs(sysCtx, userIDs)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get organization memberships: %w", err)
+			}
+			organizationCount := make(map[uuid.UUID]int, len(memberships))
+			for _, membership := range memberships {
+				organizationCount[membership.UserID] = len(membership.OrganizationIDs)
+			}
+			var only []string
+			for _, member := range members {
+				if organizationCount[member.UserID] <= 1 {
+					only = append(only, member.Username)
+				}
+			}
+			if len(only) > 0 {
+				return httpError{
+					code:   http.StatusBadRequest,
+					msg:    "Every member must belong to another organization before an organization can be removed.",
+					detail: fmt.Sprintf("Only members of this organization: %s", strings.Join(only, ", ")),
+				}
+			}
+		}
+
+		// Deleted workspaces and templates are kept for their history, so
+		// they can still block the delete. Templates are deleted along with
+		// the organization, unless a workspace was built from them.
+		err = tx.DeleteOrganization(ctx, organization.ID)
+		switch {
+		case database.IsForeignKeyViolation(err, database.ForeignKeyWorkspacesOrganizationID):
+			return httpError{
+				code:   http.StatusBadRequest,
+				msg:    "Organization still has workspaces and cannot be removed.",
+				detail: "Deleted workspaces are kept for their history, and block the removal too.",
+			}
+		case database.IsForeignKeyViolation(err, database.ForeignKeyWorkspacesTemplateID):
+			return httpError{
+				code:   http.StatusBadRequest,
+				msg:    "Organization has deleted templates that workspaces were built from and cannot be removed.",
+				detail: "Deleted templates are kept for the history of their workspaces.",
+			}
+		}
+		return err
+	}, &database.TxOptions{
+		Isolation:    sql.LevelSerializable,
+		TxIdentifier: "delete_organization",
+	})
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		httpErr.Write(rw, r)
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Organization has been deleted!",
+	})
+}
+
 // convertOrganization consumes the database representation and outputs an API friendly representation.
 func convertOrganization(organization database.Organization) codersdk.Organization {
 	return codersdk.Organization{
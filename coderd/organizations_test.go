@@ -7,7 +7,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -110,3 +113,181 @@ func TestPostOrganizationsByUser(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestDeleteOrganization(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "new",
+		})
+		require.NoError(t, err)
+
+		err = client.DeleteOrganization(ctx, org.ID)
+		require.NoError(t, err)
+		_, err = client.Organization(ctx, org.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		for _, action := range []database.AuditAction{database.AuditActionCreate, database.AuditActionDelete} {
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				ResourceType: database.ResourceTypeOrganization,
+				ResourceID:   org.ID,
+				Action:       action,
+			}), "missing %s audit log", action)
+		}
+	})
+
+	t.Run("OnlyOrganizationOfMembers", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.DeleteOrganization(ctx, user.OrganizationID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OrgAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "new",
+		})
+		require.NoError(t, err)
+		adminClient, admin := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = client.AddOrganizationMember(ctx, org.ID, admin.Username)
+		require.NoError(t, err)
+		_, err = client.UpdateOrganizationMemberRoles(ctx, org.ID, admin.Username, codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleOrgAdmin(org.ID)},
+		})
+		require.NoError(t, err)
+
+		// Organization admins can't delete their organization.
+		err = adminClient.DeleteOrganization(ctx, org.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("AddAndRemove", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "new",
+		})
+		require.NoError(t, err)
+
+		added, err := client.AddOrganizationMember(ctx, org.ID, member.Username)
+		require.NoError(t, err)
+		require.Equal(t, member.ID, added.UserID)
+		require.Equal(t, org.ID, added.OrganizationID)
+
+		_, err = client.AddOrganizationMember(ctx, org.ID, member.Username)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		members, err := client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		for _, m := range members {
+			if m.UserID != user.UserID {
+				continue
+			}
+			// The creator of an organization administers it.
+			require.Len(t, m.Roles, 1)
+			require.Equal(t, rbac.RoleOrgAdmin(org.ID), m.Roles[0].Name)
+		}
+
+		err = client.RemoveOrganizationMember(ctx, org.ID, member.Username)
+		require.NoError(t, err)
+		members, err = client.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, user.UserID, members[0].UserID)
+
+		for _, action := range []database.AuditAction{database.AuditActionCreate, database.AuditActionDelete} {
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				ResourceType:   database.ResourceTypeOrganizationMember,
+				ResourceID:     member.ID,
+				ResourceTarget: member.Username,
+				Action:         action,
+			}), "missing %s audit log", action)
+		}
+	})
+
+	t.Run("LastOrganization", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		_, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.RemoveOrganizationMember(ctx, user.OrganizationID, member.Username)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("OrgAdmin", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		adminClient, admin := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, other := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "new",
+		})
+		require.NoError(t, err)
+		_, outsider := coderdtest.CreateAnotherUser(t, client, org.ID)
+		_, err = client.AddOrganizationMember(ctx, org.ID, admin.Username)
+		require.NoError(t, err)
+		_, err = client.UpdateOrganizationMemberRoles(ctx, org.ID, admin.Username, codersdk.UpdateRoles{
+			Roles: []string{rbac.RoleOrgAdmin(org.ID)},
+		})
+		require.NoError(t, err)
+
+		// Organization admins manage the members of their organization.
+		_, err = adminClient.AddOrganizationMember(ctx, org.ID, other.Username)
+		require.NoError(t, err)
+		members, err := adminClient.OrganizationMembers(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 4)
+		err = adminClient.RemoveOrganizationMember(ctx, org.ID, other.Username)
+		require.NoError(t, err)
+
+		// They only see themselves in other organizations.
+		members, err = adminClient.OrganizationMembers(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, members, 1)
+		require.Equal(t, admin.ID, members[0].UserID)
+		_, err = adminClient.AddOrganizationMember(ctx, user.OrganizationID, outsider.Username)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,92 @@
+package prometheusmetrics
+
+import (
+	"expvar"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"tailscale.com/derp"
+	"tailscale.com/metrics"
+)
+
+// DERPServer exports the metrics of an embedded DERP server. The region
+// labels identify the DERP region the server relays traffic for, so the
+// metrics of coderd and every workspace proxy can be told apart.
+func DERPServer(registerer prometheus.Registerer, server *derp.Server, regionID int, regionName string) error {
+	labels := prometheus.Labels{
+		"region_id":   strconv.Itoa(regionID),
+		"region_name": regionName,
+	}
+	desc := func(name, help string, variableLabels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("coderd", "derp_server", name), help, variableLabels, labels)
+	}
+	return registerer.Register(&derpServerCollector{
+		server:          server,
+		bytesReceived:   desc("bytes_received_total", "Total bytes received from DERP clients."),
+		bytesSent:       desc("bytes_sent_total", "Total bytes sent to DERP clients."),
+		packetsReceived: desc("packets_received_total", "Total packets received from DERP clients."),
+		packetsSent:     desc("packets_sent_total", "Total packets sent to DERP clients."),
+		packetsDropped:  desc("packets_dropped_total", "Total packets dropped by the DERP server.", "reason"),
+		accepts:         desc("accepts_total", "Total connections accepted by the DERP server."),
+		clients:         desc("clients", "The number of clients currently connected to the DERP server."),
+		homeClients:     desc("home_clients", "The number of connected clients that use this DERP server as their home region."),
+	})
+}
+
+type derpServerCollector struct {
+	server *derp.Server
+
+	bytesReceived   *prometheus.Desc
+	bytesSent       *prometheus.Desc
+	packetsReceived *prometheus.Desc
+	packetsSent     *prometheus.Desc
+	packetsDropped  *prometheus.Desc
+	accepts         *prometheus.Desc
+	clients         *prometheus.Desc
+	homeClients     *prometheus.Desc
+}
+
+var _ prometheus.Collector = new(derpServerCollector)
+
+func (c *derpServerCollector) Describe(descCh chan<- *prometheus.Desc) {
+	descCh <- c.bytesReceived
+	descCh <- c.bytesSent
+	descCh <- c.packetsReceived
+	descCh <- c.packetsSent
+	descCh <- c.packetsDropped
+	descCh <- c.accepts
+	descCh <- c.clients
+	descCh <- c.homeClients
+}
+
+func (c *derpServerCollector) Collect(metricsCh chan<- prometheus.Metric) {
+	set, ok := c.server.ExpVar().(*metrics.Set)
+	if !ok {
+		return
+	}
+	value := func(name string) float64 {
+		v, ok := set.Get(name).(*expvar.Int)
+		if !ok {
+			return 0
+		}
+		return float64(v.Value())
+	}
+
+	metricsCh <- prometheus.MustNewConstMetric(c.bytesReceived, prometheus.CounterValue, value("bytes_received"))
+	metricsCh <- prometheus.MustNewConstMetric(c.bytesSent, prometheus.CounterValue, value("bytes_sent"))
+	metricsCh <- prometheus.MustNewConstMetric(c.packetsReceived, prometheus.CounterValue, value("packets_received"))
+	metricsCh <- prometheus.MustNewConstMetric(c.packetsSent, prometheus.CounterValue, value("packets_sent"))
+	metricsCh <- prometheus.MustNewConstMetric(c.accepts, prometheus.CounterValue, value("accepts"))
+	metricsCh <- prometheus.MustNewConstMetric(c.clients, prometheus.GaugeValue, value("gauge_current_connections"))
+	metricsCh <- prometheus.MustNewConstMetric(c.homeClients, prometheus.GaugeValue, value("gauge_current_home_connections"))
+
+	if dropped, ok := set.Get("counter_packets_dropped_reason").(*metrics.LabelMap); ok {
+		dropped.Do(func(kv expvar.KeyValue) {
+			v, ok := kv.Value.(*expvar.Int)
+			if !ok {
+				return
+			}
+			metricsCh <- prometheus.MustNewConstMetric(c.packetsDropped, prometheus.CounterValue, float64(v.Value()), kv.Key)
+		})
+	}
+}
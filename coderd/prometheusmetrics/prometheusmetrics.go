@@ -25,10 +25,11 @@ import (
 )
 
 const (
-	templateNameLabel  = "template_name"
-	agentNameLabel     = "agent_name"
-	usernameLabel      = "username"
-	workspaceNameLabel = "workspace_name"
+	templateNameLabel     = "template_name"
+	agentNameLabel        = "agent_name"
+	usernameLabel         = "username"
+	workspaceNameLabel    = "workspace_name"
+	organizationNameLabel = "organization_name"
 )
 
 // ActiveUsers tracks the number of users that have authenticated within the past hour.
@@ -112,8 +113,12 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 			return
 		}
 		jobIDs := make([]uuid.UUID, 0, len(builds))
+		// Failed builds that are retried are counted separately from
+		// those that failed for good.
+		retrying := make(map[uuid.UUID]bool, len(builds))
 		for _, build := range builds {
 			jobIDs = append(jobIDs, build.JobID)
+			retrying[build.JobID] = build.RetryAt.Valid
 		}
 		jobs, err := db.GetProvisionerJobsByIDs(ctx, jobIDs)
 		if err != nil {
@@ -122,8 +127,110 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 
 		gauge.Reset()
 		for _, job := range jobs {
-			status := codersdk.ProvisionerJobStatus(job.JobStatus)
-			gauge.WithLabelValues(string(status)).Add(1)
+			status := string(codersdk.ProvisionerJobStatus(job.JobStatus))
+			if job.JobStatus == database.ProvisionerJobStatusFailed && retrying[job.ID] {
+				status = "retrying"
+			}
+			gauge.WithLabelValues(status).Add(1)
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				doTick()
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
+// WorkspaceCounts tracks the number of workspaces by template, organization and
+// latest build status, and the number of their agents and apps by state. The
+// counts are computed by aggregate queries, so they are cheap to collect even
+// for large deployments.
+func WorkspaceCounts(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = 1 * time.Minute
+	}
+
+	workspacesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Name:      "workspaces",
+		Help:      "The number of workspaces by template, organization and latest build status.",
+	}, []string{templateNameLabel, organizationNameLabel, "workspace_transition", "status"}))
+	err := registerer.Register(workspacesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentsGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Name:      "workspace_agents",
+		Help:      "The number of agents in the latest build of workspaces by lifecycle state.",
+	}, []string{"lifecycle_state"}))
+	err = registerer.Register(agentsGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	appsGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Name:      "workspace_apps",
+		Help:      "The number of apps in the latest build of workspaces by health.",
+	}, []string{"health"}))
+	err = registerer.Register(appsGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// nolint:gocritic // Prometheus must count the workspaces of all users.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(duration)
+
+		workspaces, err := db.GetWorkspaceCountsByTemplateAndStatus(ctx)
+		if err != nil {
+			logger.Error(ctx, "can't get workspace counts", slog.Error(err))
+		} else {
+			for _, row := range workspaces {
+				workspacesGauge.WithLabelValues(VectorOperationSet, float64(row.Count), row.TemplateName, row.OrganizationName, string(row.Transition), string(row.JobStatus))
+			}
+			workspacesGauge.Commit()
+		}
+
+		agents, err := db.GetWorkspaceAgentCountsByLifecycleState(ctx)
+		if err != nil {
+			logger.Error(ctx, "can't get workspace agent counts", slog.Error(err))
+		} else {
+			for _, row := range agents {
+				agentsGauge.WithLabelValues(VectorOperationSet, float64(row.Count), string(row.LifecycleState))
+			}
+			agentsGauge.Commit()
+		}
+
+		apps, err := db.GetWorkspaceAppCountsByHealth(ctx)
+		if err != nil {
+			logger.Error(ctx, "can't get workspace app counts", slog.Error(err))
+		} else {
+			for _, row := range apps {
+				appsGauge.WithLabelValues(VectorOperationSet, float64(row.Count), string(row.Health))
+			}
+			appsGauge.Commit()
 		}
 	}
 
@@ -195,6 +302,17 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 		return nil, err
 	}
 
+	agentsGPUsGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agents",
+		Name:      "gpus",
+		Help:      "The number of GPUs detected by agents.",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel, "vendor", "model"}))
+	err = registerer.Register(agentsGPUsGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	metricsCollectorAgents := prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "coderd",
 		Subsystem: "prometheusmetrics",
@@ -305,6 +423,16 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 						}
 					}
 
+					// Collect information about detected GPUs
+					gpus, err := db.GetWorkspaceAgentGPUsByAgentIDs(ctx, []uuid.UUID{agent.ID})
+					if err != nil {
+						logger.Error(ctx, "can't get workspace agent gpus", slog.F("agent_id", agent.ID), slog.Error(err))
+					}
+
+					for _, gpu := range gpus {
+						agentsGPUsGauge.WithLabelValues(VectorOperationAdd, 1, agent.Name, user.Username, workspace.Name, gpu.Vendor, gpu.Model)
+					}
+
 					// Collect information about registered applications
 					apps, err := db.GetWorkspaceAppsByAgentID(ctx, agent.ID)
 					if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -322,6 +450,7 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 			agentsConnectionsGauge.Commit()
 			agentsConnectionLatenciesGauge.Commit()
 			agentsAppsGauge.Commit()
+			agentsGPUsGauge.Commit()
 
 		done:
 			logger.Debug(ctx, "agent metrics collection is done")
@@ -335,6 +464,85 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 	}, nil
 }
 
+// ProvisionerDaemons tracks the liveness of provisioner daemons. A daemon is
+// up when it has sent a heartbeat within staleAfter.
+func ProvisionerDaemons(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, staleAfter, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = 1 * time.Minute
+	}
+
+	daemonsUpGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_daemons",
+		Name:      "up",
+		Help:      "Whether the provisioner daemon has sent a heartbeat recently.",
+	}, []string{"daemon_id", "daemon_name"}))
+	err := registerer.Register(daemonsUpGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonsLastSeenGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_daemons",
+		Name:      "last_seen_timestamp_seconds",
+		Help:      "The time of the last heartbeat of the provisioner daemon, in seconds since the Unix epoch.",
+	}, []string{"daemon_id", "daemon_name"}))
+	err = registerer.Register(daemonsLastSeenGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// nolint:gocritic // Prometheus must collect metrics for all provisioner daemons.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(duration)
+
+		daemons, err := db.GetProvisionerDaemons(ctx)
+		if err != nil {
+			logger.Error(ctx, "can't get provisioner daemons", slog.Error(err))
+			return
+		}
+
+		now := dbtime.Now()
+		for _, daemon := range daemons {
+			up := 0.0
+			if daemon.LastSeenAt.Valid && now.Sub(daemon.LastSeenAt.Time) < staleAfter {
+				up = 1
+			}
+			daemonsUpGauge.WithLabelValues(VectorOperationSet, up, daemon.ID.String(), daemon.Name)
+			if daemon.LastSeenAt.Valid {
+				daemonsLastSeenGauge.WithLabelValues(VectorOperationSet, float64(daemon.LastSeenAt.Time.Unix()), daemon.ID.String(), daemon.Name)
+			}
+		}
+		daemonsUpGauge.Commit()
+		daemonsLastSeenGauge.Commit()
+	}
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				doTick()
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
 func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, initialCreateAfter time.Time, duration time.Duration) (func(), error) {
 	if duration == 0 {
 		duration = 1 * time.Minute
@@ -504,3 +712,145 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 		<-done
 	}, nil
 }
+
+// AgentResourceUsage exports the latest CPU, memory and disk usage reported by
+// each workspace agent.
+func AgentResourceUsage(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, initialCreateAfter time.Time, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = 1 * time.Minute
+	}
+
+	metricsCollectorAgentResourceUsage := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "prometheusmetrics",
+		Name:      "agentresourceusage_execution_seconds",
+		Help:      "Histogram for duration of agent resource usage metrics collection in seconds.",
+		Buckets:   []float64{0.001, 0.005, 0.010, 0.025, 0.050, 0.100, 0.500, 1, 5, 10, 30},
+	})
+	err := registerer.Register(metricsCollectorAgentResourceUsage)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsCPUUsedCoresGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "cpu_used_cores",
+		Help:      "The number of CPU cores in use by the workspace",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsCPUUsedCoresGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsCPUTotalCoresGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "cpu_total_cores",
+		Help:      "The number of CPU cores available to the workspace",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsCPUTotalCoresGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsMemoryUsedBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "memory_used_bytes",
+		Help:      "The memory in use by the workspace in bytes",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsMemoryUsedBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsMemoryTotalBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "memory_total_bytes",
+		Help:      "The memory available to the workspace in bytes",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsMemoryTotalBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsDiskUsedBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "disk_used_bytes",
+		Help:      "The disk space in use on the agent home volume in bytes",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsDiskUsedBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsDiskTotalBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "disk_total_bytes",
+		Help:      "The size of the agent home volume in bytes",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentStatsDiskTotalBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	createdAfter := initialCreateAfter
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			logger.Debug(ctx, "agent resource usage metrics collection is starting")
+			timer := prometheus.NewTimer(metricsCollectorAgentResourceUsage)
+
+			checkpoint := time.Now()
+			usage, err := db.GetWorkspaceAgentResourceUsageAndLabels(ctx, createdAfter)
+			if err != nil {
+				logger.Error(ctx, "can't get agent resource usage", slog.Error(err))
+			} else {
+				for _, agentStat := range usage {
+					agentStatsCPUUsedCoresGauge.WithLabelValues(VectorOperationSet, agentStat.CPUUsedCores, agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+					agentStatsCPUTotalCoresGauge.WithLabelValues(VectorOperationSet, agentStat.CPUTotalCores, agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+					agentStatsMemoryUsedBytesGauge.WithLabelValues(VectorOperationSet, float64(agentStat.MemoryUsedBytes), agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+					agentStatsMemoryTotalBytesGauge.WithLabelValues(VectorOperationSet, float64(agentStat.MemoryTotalBytes), agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+					agentStatsDiskUsedBytesGauge.WithLabelValues(VectorOperationSet, float64(agentStat.DiskUsedBytes), agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+					agentStatsDiskTotalBytesGauge.WithLabelValues(VectorOperationSet, float64(agentStat.DiskTotalBytes), agentStat.AgentName, agentStat.Username, agentStat.WorkspaceName)
+				}
+
+				if len(usage) > 0 {
+					agentStatsCPUUsedCoresGauge.Commit()
+					agentStatsCPUTotalCoresGauge.Commit()
+					agentStatsMemoryUsedBytesGauge.Commit()
+					agentStatsMemoryTotalBytesGauge.Commit()
+					agentStatsDiskUsedBytesGauge.Commit()
+					agentStatsDiskTotalBytesGauge.Commit()
+				}
+			}
+
+			logger.Debug(ctx, "agent resource usage metrics collection is done", slog.F("len", len(usage)))
+			timer.ObserveDuration()
+
+			createdAfter = checkpoint
+			ticker.Reset(duration)
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -19,13 +21,17 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
 	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbmem"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
@@ -164,7 +170,7 @@ func TestWorkspaces(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	insertFailed := func(db database.Store) {
+	insertFailed := func(db database.Store) database.ProvisionerJob {
 		job := insertRunning(db)
 		err := db.UpdateProvisionerJobWithCompleteByID(context.Background(), database.UpdateProvisionerJobWithCompleteByIDParams{
 			ID: job.ID,
@@ -178,6 +184,21 @@ func TestWorkspaces(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
+		return job
+	}
+
+	insertRetrying := func(db database.Store) {
+		job := insertFailed(db)
+		build, err := db.GetWorkspaceBuildByJobID(context.Background(), job.ID)
+		require.NoError(t, err)
+		err = db.UpdateWorkspaceBuildRetryAtByID(context.Background(), database.UpdateWorkspaceBuildRetryAtByIDParams{
+			ID: build.ID,
+			RetryAt: sql.NullTime{
+				Time:  dbtime.Now().Add(time.Minute),
+				Valid: true,
+			},
+		})
+		require.NoError(t, err)
 	}
 
 	insertSuccess := func(db database.Store) {
@@ -210,16 +231,18 @@ func TestWorkspaces(t *testing.T) {
 			insertCanceled(db)
 			insertFailed(db)
 			insertFailed(db)
+			insertRetrying(db)
 			insertSuccess(db)
 			insertSuccess(db)
 			insertSuccess(db)
 			insertRunning(db)
 			return db
 		},
-		Total: 7,
+		Total: 8,
 		Status: map[codersdk.ProvisionerJobStatus]int{
 			codersdk.ProvisionerJobCanceled:  1,
 			codersdk.ProvisionerJobFailed:    2,
+			"retrying":                       1,
 			codersdk.ProvisionerJobSucceeded: 3,
 			codersdk.ProvisionerJobRunning:   1,
 		},
@@ -259,6 +282,134 @@ func TestWorkspaces(t *testing.T) {
 	}
 }
 
+func TestProvisionerDaemons(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	upsertDaemon := func(name string, lastSeenAt time.Time) database.ProvisionerDaemon {
+		daemon, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
+			CreatedAt:    lastSeenAt,
+			Name:         name,
+			Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho},
+			Tags:         database.StringMap{},
+			LastSeenAt: sql.NullTime{
+				Time:  lastSeenAt,
+				Valid: true,
+			},
+			Version:    "v0.0.0",
+			APIVersion: "1.0",
+		})
+		require.NoError(t, err)
+		return daemon
+	}
+	alive := upsertDaemon("alive", dbtime.Now())
+	stale := upsertDaemon("stale", dbtime.Now().Add(-10*time.Minute))
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.ProvisionerDaemons(context.Background(), slogtest.Make(t, nil), registry, db, 3*time.Minute, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		up := map[string]float64{}
+		for _, family := range metrics {
+			if family.GetName() != "coderd_provisioner_daemons_up" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "daemon_id" {
+						up[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return len(up) == 2 && up[alive.ID.String()] == 1 && up[stale.ID.String()] == 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func TestWorkspaceCounts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := dbmem.New()
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	tpl := dbgen.Template(t, db, database.Template{OrganizationID: org.ID, CreatedBy: user.ID})
+	insertWorkspace := func(transition database.WorkspaceTransition) (database.Workspace, database.WorkspaceResource) {
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			TemplateID:     tpl.ID,
+		})
+		job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			StartedAt:      sql.NullTime{Time: dbtime.Now(), Valid: true},
+			CompletedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+		})
+		_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID: workspace.ID,
+			JobID:       job.ID,
+			Transition:  transition,
+		})
+		return workspace, dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: job.ID})
+	}
+
+	_, resource := insertWorkspace(database.WorkspaceTransitionStart)
+	readyAgent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID})
+	err := db.UpdateWorkspaceAgentLifecycleStateByID(ctx, database.UpdateWorkspaceAgentLifecycleStateByIDParams{
+		ID:             readyAgent.ID,
+		LifecycleState: database.WorkspaceAgentLifecycleStateReady,
+	})
+	require.NoError(t, err)
+	_ = dbgen.WorkspaceApp(t, db, database.WorkspaceApp{AgentID: readyAgent.ID, Health: database.WorkspaceAppHealthHealthy})
+
+	_, resource = insertWorkspace(database.WorkspaceTransitionStart)
+	createdAgent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID})
+	_ = dbgen.WorkspaceApp(t, db, database.WorkspaceApp{AgentID: createdAgent.ID, Health: database.WorkspaceAppHealthUnhealthy})
+
+	_, _ = insertWorkspace(database.WorkspaceTransitionStop)
+
+	// Deleted workspaces are not counted.
+	deleted, resource := insertWorkspace(database.WorkspaceTransitionStart)
+	deletedAgent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID})
+	_ = dbgen.WorkspaceApp(t, db, database.WorkspaceApp{AgentID: deletedAgent.ID})
+	err = db.UpdateWorkspaceDeletedByID(ctx, database.UpdateWorkspaceDeletedByIDParams{ID: deleted.ID, Deleted: true})
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.WorkspaceCounts(ctx, slogtest.Make(t, nil), registry, db, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	want := map[string]float64{
+		"coderd_workspace_agents{ready}":   1,
+		"coderd_workspace_agents{created}": 1,
+		"coderd_workspace_apps{healthy}":   1,
+		"coderd_workspace_apps{unhealthy}": 1,
+	}
+	// Labels are gathered sorted by name.
+	want[fmt.Sprintf("coderd_workspaces{%s,succeeded,%s,start}", org.Name, tpl.Name)] = 2
+	want[fmt.Sprintf("coderd_workspaces{%s,succeeded,%s,stop}", org.Name, tpl.Name)] = 1
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		got := map[string]float64{}
+		for _, family := range metrics {
+			for _, metric := range family.GetMetric() {
+				labels := make([]string, 0, len(metric.GetLabel()))
+				for _, label := range metric.GetLabel() {
+					labels = append(labels, label.GetValue())
+				}
+				got[fmt.Sprintf("%s{%s}", family.GetName(), strings.Join(labels, ","))] = metric.GetGauge().GetValue()
+			}
+		}
+		return assert.ObjectsAreEqual(want, got)
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func TestAgents(t *testing.T) {
 	t.Parallel()
 
@@ -303,6 +454,21 @@ func TestAgents(t *testing.T) {
 	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
 	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
 
+	// nolint:gocritic // Reporting GPUs is done by the agent in practice.
+	sysCtx := dbauthz.AsSystemRestricted(context.Background())
+	agents, err := db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(sysCtx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	err = db.UpsertWorkspaceAgentGPUs(sysCtx, database.UpsertWorkspaceAgentGPUsParams{
+		AgentID:       agents[0].ID,
+		Index:         []int32{0},
+		Vendor:        []string{"nvidia"},
+		Model:         []string{"NVIDIA A100-SXM4-40GB"},
+		MemoryBytes:   []int64{42949672960},
+		DriverVersion: []string{"535.104.05"},
+	})
+	require.NoError(t, err)
+
 	// given
 	derpMap, _ := tailnettest.RunDERPAndSTUN(t)
 	derpMapFn := func() *tailcfg.DERPMap {
@@ -328,6 +494,7 @@ func TestAgents(t *testing.T) {
 	var agentsUp bool
 	var agentsConnections bool
 	var agentsApps bool
+	var agentsGPUs bool
 	var agentsExecutionInSeconds bool
 	require.Eventually(t, func() bool {
 		metrics, err := registry.Gather()
@@ -363,13 +530,21 @@ func TestAgents(t *testing.T) {
 				assert.Equal(t, workspace.Name, metric.Metric[0].Label[4].GetValue())     // Workspace name
 				assert.Equal(t, 1, int(metric.Metric[0].Gauge.GetValue()))                // Metric value
 				agentsApps = true
+			case "coderd_agents_gpus":
+				assert.Equal(t, "testagent", metric.Metric[0].Label[0].GetValue())             // Agent name
+				assert.Equal(t, "NVIDIA A100-SXM4-40GB", metric.Metric[0].Label[1].GetValue()) // Model
+				assert.Equal(t, "testuser", metric.Metric[0].Label[2].GetValue())              // Username
+				assert.Equal(t, "nvidia", metric.Metric[0].Label[3].GetValue())                // Vendor
+				assert.Equal(t, workspace.Name, metric.Metric[0].Label[4].GetValue())          // Workspace name
+				assert.Equal(t, 1, int(metric.Metric[0].Gauge.GetValue()))                     // Metric value
+				agentsGPUs = true
 			case "coderd_prometheusmetrics_agents_execution_seconds":
 				agentsExecutionInSeconds = true
 			default:
 				require.FailNowf(t, "unexpected metric collected", "metric: %s", metric.GetName())
 			}
 		}
-		return agentsUp && agentsConnections && agentsApps && agentsExecutionInSeconds
+		return agentsUp && agentsConnections && agentsApps && agentsGPUs && agentsExecutionInSeconds
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
@@ -499,6 +674,86 @@ func TestAgentStats(t *testing.T) {
 	assert.EqualValues(t, golden, collected)
 }
 
+func TestAgentResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	t.Cleanup(cancelFunc)
+
+	db := dbmem.New()
+	user := dbgen.User(t, db, database.User{Username: "testuser"})
+	workspace := dbgen.Workspace(t, db, database.Workspace{OwnerID: user.ID, Name: "workspace"})
+	job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{})
+	resource := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{JobID: job.ID})
+	agent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID, Name: "main"})
+	legacyAgent := dbgen.WorkspaceAgent(t, db, database.WorkspaceAgent{ResourceID: resource.ID, Name: "legacy"})
+
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:        dbtime.Now().Add(-30 * time.Second),
+		UserID:           user.ID,
+		WorkspaceID:      workspace.ID,
+		AgentID:          agent.ID,
+		CPUUsedCores:     1,
+		CPUTotalCores:    4,
+		MemoryUsedBytes:  1024,
+		MemoryTotalBytes: 4096,
+		DiskUsedBytes:    2048,
+		DiskTotalBytes:   8192,
+	})
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:        dbtime.Now(),
+		UserID:           user.ID,
+		WorkspaceID:      workspace.ID,
+		AgentID:          agent.ID,
+		CPUUsedCores:     2,
+		CPUTotalCores:    4,
+		MemoryUsedBytes:  3072,
+		MemoryTotalBytes: 4096,
+		DiskUsedBytes:    4096,
+		DiskTotalBytes:   8192,
+	})
+	// Agents that don't report resource usage are not exported.
+	dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:   dbtime.Now(),
+		UserID:      user.ID,
+		WorkspaceID: workspace.ID,
+		AgentID:     legacyAgent.ID,
+	})
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.AgentResourceUsage(ctx, slogtest.Make(t, nil), registry, db, time.Now().Add(-time.Minute), time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	expected := map[string]float64{
+		"coderd_agentstats_cpu_used_cores":     2,
+		"coderd_agentstats_cpu_total_cores":    4,
+		"coderd_agentstats_memory_used_bytes":  3072,
+		"coderd_agentstats_memory_total_bytes": 4096,
+		"coderd_agentstats_disk_used_bytes":    4096,
+		"coderd_agentstats_disk_total_bytes":   8192,
+	}
+	collected := map[string]float64{}
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+
+		for _, metric := range metrics {
+			if metric.GetName() == "coderd_prometheusmetrics_agentresourceusage_execution_seconds" {
+				continue
+			}
+			for _, m := range metric.Metric {
+				// agent:username:workspace
+				require.Equal(t, "main", m.Label[0].GetValue())
+				require.Equal(t, "testuser", m.Label[1].GetValue())
+				require.Equal(t, "workspace", m.Label[2].GetValue())
+				collected[metric.GetName()] = m.Gauge.GetValue()
+			}
+		}
+		return reflect.DeepEqual(expected, collected)
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
 func prepareWorkspaceAndAgent(t *testing.T, client *codersdk.Client, user codersdk.CreateFirstUserResponse, workspaceNum int) *agentsdk.Client {
 	authToken := uuid.NewString()
 
@@ -518,3 +773,50 @@ func prepareWorkspaceAndAgent(t *testing.T, client *codersdk.Client, user coders
 	agentClient.SetSessionToken(authToken)
 	return agentClient
 }
+
+func TestDERPServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logf := tailnet.Logger(slogtest.Make(t, nil))
+	derpServer := derp.NewServer(key.NewNode(), logf)
+	defer derpServer.Close()
+	srv := httptest.NewServer(derphttp.Handler(derpServer))
+	defer srv.Close()
+
+	registry := prometheus.NewRegistry()
+	err := prometheusmetrics.DERPServer(registry, derpServer, 999, "Coder Embedded Relay")
+	require.NoError(t, err)
+
+	clientKey := key.NewNode()
+	client, err := derphttp.NewClient(clientKey, srv.URL+"/derp", logf)
+	require.NoError(t, err)
+	defer client.Close()
+	err = client.Connect(ctx)
+	require.NoError(t, err)
+	// Relay a packet to the client itself.
+	err = client.Send(clientKey.Public(), []byte("hello"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		if !assert.NoError(t, err) {
+			return false
+		}
+		values := map[string]float64{}
+		for _, metric := range metrics {
+			for _, m := range metric.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range m.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				assert.Equal(t, "999", labels["region_id"])
+				assert.Equal(t, "Coder Embedded Relay", labels["region_name"])
+				values[metric.GetName()] = m.GetGauge().GetValue() + m.GetCounter().GetValue()
+			}
+		}
+		return values["coderd_derp_server_clients"] == 1 &&
+			values["coderd_derp_server_accepts_total"] == 1 &&
+			values["coderd_derp_server_bytes_received_total"] == 5
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
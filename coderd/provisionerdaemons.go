@@ -0,0 +1,61 @@
+package coderd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Purge provisioner cache
+// @ID purge-provisioner-cache
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param older_than query string false "Only purge entries cached longer ago than this duration, e.g. 720h"
+// @Success 200 {object} codersdk.PurgeProvisionerCacheResponse
+// @Router /provisionerdaemons/cache [delete]
+func (api *API) deleteProvisionerCache(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceProvisionerDaemon) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if api.ProvisionerCache == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provisioner shared cache is not enabled.",
+			Detail:  "Enable it with --provisioner-shared-cache.",
+		})
+		return
+	}
+
+	var olderThan time.Duration
+	if raw := r.URL.Query().Get("older_than"); raw != "" {
+		var err error
+		olderThan, err = time.ParseDuration(raw)
+		if err != nil || olderThan < 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param \"older_than\" must be a positive duration.",
+				Validations: []codersdk.ValidationError{
+					{Field: "older_than", Detail: "Must be a duration, e.g. 720h"},
+				},
+			})
+			return
+		}
+	}
+
+	stats, err := api.ProvisionerCache.Purge(ctx, time.Now().Add(-olderThan))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error purging provisioner cache.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.PurgeProvisionerCacheResponse{
+		DeletedObjects: stats.Objects,
+		DeletedBytes:   stats.Bytes,
+	})
+}
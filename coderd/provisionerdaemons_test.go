@@ -0,0 +1,72 @@
+package coderd_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPurgeProvisionerCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cache, err := tfcache.New(ctx, tfcache.Options{Dir: t.TempDir()})
+		require.NoError(t, err)
+		client := coderdtest.New(t, &coderdtest.Options{ProvisionerCache: cache})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		workdir := t.TempDir()
+		err = os.WriteFile(filepath.Join(workdir, ".terraform.lock.hcl"), []byte("lock"), 0o600)
+		require.NoError(t, err)
+		err = cache.Save(ctx, workdir, "key")
+		require.NoError(t, err)
+
+		res, err := client.PurgeProvisionerCache(ctx, time.Hour)
+		require.NoError(t, err)
+		require.Zero(t, res.DeletedObjects)
+
+		// The manifest and the lock file archive.
+		res, err = client.PurgeProvisionerCache(ctx, 0)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, res.DeletedObjects)
+		require.Positive(t, res.DeletedBytes)
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.PurgeProvisionerCache(ctx, 0)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cache, err := tfcache.New(ctx, tfcache.Options{Dir: t.TempDir()})
+		require.NoError(t, err)
+		client := coderdtest.New(t, &coderdtest.Options{ProvisionerCache: cache})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		_, err = member.PurgeProvisionerCache(ctx, 0)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
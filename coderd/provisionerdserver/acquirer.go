@@ -18,6 +18,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionertags"
 )
 
 const (
@@ -85,20 +86,22 @@ func NewAcquirer(ctx context.Context, logger slog.Logger, store AcquirerStore, p
 }
 
 // AcquireJob acquires a job with one of the given provisioner types and compatible
-// tags from the database.  The call blocks until a job is acquired, the context is
+// tags from the database.  If organizationID is valid, only jobs from that
+// organization are acquired.  The call blocks until a job is acquired, the context is
 // done, or the database returns an error _other_ than that no jobs are available.
 // If no jobs are available, this method handles retrying as appropriate.
 func (a *Acquirer) AcquireJob(
-	ctx context.Context, worker uuid.UUID, pt []database.ProvisionerType, tags Tags,
+	ctx context.Context, worker uuid.UUID, organizationID uuid.NullUUID, pt []database.ProvisionerType, tags Tags,
 ) (
 	retJob database.ProvisionerJob, retErr error,
 ) {
 	logger := a.logger.With(
 		slog.F("worker_id", worker),
+		slog.F("organization_id", organizationID),
 		slog.F("provisioner_types", pt),
 		slog.F("tags", tags))
 	logger.Debug(ctx, "acquiring job")
-	dk := domainKey(pt, tags)
+	dk := domainKey(organizationID, pt, tags)
 	dbTags, err := tags.ToJSON()
 	if err != nil {
 		return database.ProvisionerJob{}, err
@@ -106,7 +109,7 @@ func (a *Acquirer) AcquireJob(
 	// buffer of 1 so that cancel doesn't deadlock while writing to the channel
 	clearance := make(chan struct{}, 1)
 	for {
-		a.want(pt, tags, clearance)
+		a.want(organizationID, pt, tags, clearance)
 		select {
 		case <-ctx.Done():
 			err := ctx.Err()
@@ -128,8 +131,9 @@ func (a *Acquirer) AcquireJob(
 					UUID:  worker,
 					Valid: true,
 				},
-				Types: pt,
-				Tags:  dbTags,
+				Types:          pt,
+				Tags:           dbTags,
+				OrganizationID: organizationID,
 			})
 			if xerrors.Is(err, sql.ErrNoRows) {
 				logger.Debug(ctx, "no job available")
@@ -152,8 +156,8 @@ func (a *Acquirer) AcquireJob(
 }
 
 // want signals that an acquiree wants clearance to query for a job with the given dKey.
-func (a *Acquirer) want(pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
-	dk := domainKey(pt, tags)
+func (a *Acquirer) want(organizationID uuid.NullUUID, pt []database.ProvisionerType, tags Tags, clearance chan<- struct{}) {
+	dk := domainKey(organizationID, pt, tags)
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	cleared := false
@@ -161,13 +165,14 @@ func (a *Acquirer) want(pt []database.ProvisionerType, tags Tags, clearance chan
 	if !ok {
 		ctx, cancel := context.WithCancel(a.ctx)
 		d = domain{
-			ctx:       ctx,
-			cancel:    cancel,
-			a:         a,
-			key:       dk,
-			pt:        pt,
-			tags:      tags,
-			acquirees: make(map[chan<- struct{}]*acquiree),
+			ctx:            ctx,
+			cancel:         cancel,
+			a:              a,
+			key:            dk,
+			organizationID: organizationID,
+			pt:             pt,
+			tags:           tags,
+			acquirees:      make(map[chan<- struct{}]*acquiree),
 		}
 		a.q[dk] = d
 		go d.poll(a.backupPollDuration)
@@ -399,18 +404,22 @@ func (*Acquirer) clearOrPendLocked(d domain) {
 
 type dKey string
 
-// domainKey generates a canonical map key for the given provisioner types and
-// tags.  It uses the null byte (0x00) as a delimiter because it is an
+// domainKey generates a canonical map key for the given organization, provisioner
+// types and tags.  It uses the null byte (0x00) as a delimiter because it is an
 // unprintable control character and won't show up in any "reasonable" set of
 // string tags, even in non-Latin scripts.  It is important that Tags are
 // validated not to contain this control character prior to use.
-func domainKey(pt []database.ProvisionerType, tags Tags) dKey {
+func domainKey(organizationID uuid.NullUUID, pt []database.ProvisionerType, tags Tags) dKey {
 	// make a copy of pt before sorting, so that we don't mutate the original
 	// slice or underlying array.
 	pts := make([]database.ProvisionerType, len(pt))
 	copy(pts, pt)
 	slices.Sort(pts)
 	sb := strings.Builder{}
+	if organizationID.Valid {
+		_, _ = sb.WriteString(organizationID.UUID.String())
+	}
+	_ = sb.WriteByte(0x00)
 	for _, t := range pts {
 		_, _ = sb.WriteString(string(t))
 		_ = sb.WriteByte(0x00)
@@ -441,33 +450,32 @@ type acquiree struct {
 	pending bool
 }
 
-// domain represents a set of acquirees with the same provisioner types and
-// tags.  Acquirees in the same domain are restricted such that only one queries
+// domain represents a set of acquirees with the same organization, provisioner
+// types and tags.  Acquirees in the same domain are restricted such that only one queries
 // the database at a time.
 type domain struct {
-	ctx       context.Context
-	cancel    context.CancelFunc
-	a         *Acquirer
-	key       dKey
-	pt        []database.ProvisionerType
-	tags      Tags
-	acquirees map[chan<- struct{}]*acquiree
+	ctx    context.Context
+	cancel context.CancelFunc
+	a      *Acquirer
+	key    dKey
+	// organizationID is valid if the domain only acquires jobs from a single
+	// organization.
+	organizationID uuid.NullUUID
+	pt             []database.ProvisionerType
+	tags           Tags
+	acquirees      map[chan<- struct{}]*acquiree
 }
 
 func (d domain) contains(p provisionerjobs.JobPosting) bool {
-	if !slices.Contains(d.pt, p.ProvisionerType) {
+	// Postings from older versions don't include the organization, so we
+	// can't rule them out.
+	if d.organizationID.Valid && p.OrganizationID != uuid.Nil && p.OrganizationID != d.organizationID.UUID {
 		return false
 	}
-	for k, v := range p.Tags {
-		dv, ok := d.tags[k]
-		if !ok {
-			return false
-		}
-		if v != dv {
-			return false
-		}
+	if !slices.Contains(d.pt, p.ProvisionerType) {
+		return false
 	}
-	return true
+	return provisionertags.Matches(d.tags, p.Tags, p.TagRequirements)
 }
 
 func (d domain) poll(dur time.Duration) {
@@ -250,6 +250,54 @@ func TestAcquirer_DifferentDomains(t *testing.T) {
 	acquiree0.requireCanceled(ctx)
 }
 
+// TestAcquirer_DifferentOrganizations tests that acquirees scoped to an
+// organization don't acquire jobs from other organizations.
+func TestAcquirer_DifferentOrganizations(t *testing.T) {
+	t.Parallel()
+	fs := newFakeTaggedStore(t)
+	ps := pubsub.NewInMemory()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+
+	pt := []database.ProvisionerType{database.ProvisionerTypeEcho}
+	tags := provisionerdserver.Tags{
+		"foo": "bar",
+	}
+	worker0 := uuid.New()
+	acquiree0 := newTestAcquiree(t, worker0, pt, tags)
+	acquiree0.organizationID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	worker1 := uuid.New()
+	acquiree1 := newTestAcquiree(t, worker1, pt, tags)
+	jobID := uuid.New()
+	fs.jobs = []database.ProvisionerJob{
+		{ID: jobID, OrganizationID: uuid.New(), Provisioner: database.ProvisionerTypeEcho, Tags: database.StringMap{"foo": "bar"}},
+	}
+
+	uut := provisionerdserver.NewAcquirer(ctx, logger.Named("acquirer"), fs, ps)
+
+	ctx0, cancel0 := context.WithCancel(ctx)
+	defer cancel0()
+	acquiree0.startAcquire(ctx0, uut)
+	select {
+	case params := <-fs.params:
+		require.Equal(t, worker0, params.WorkerID.UUID)
+		require.Equal(t, acquiree0.organizationID, params.OrganizationID)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for call to database from worker0")
+	}
+	acquiree0.requireBlocked()
+
+	// worker1 is not scoped to an organization, so it is in a different
+	// domain and acquires the job.
+	acquiree1.startAcquire(ctx, uut)
+	job := acquiree1.success(ctx)
+	require.Equal(t, jobID, job.ID)
+
+	cancel0()
+	acquiree0.requireCanceled(ctx)
+}
+
 func TestAcquirer_BackupPoll(t *testing.T) {
 	t.Parallel()
 	fs := newFakeOrderedStore()
@@ -425,6 +473,9 @@ jobLoop:
 		if !slices.Contains(params.Types, job.Provisioner) {
 			continue
 		}
+		if params.OrganizationID.Valid && params.OrganizationID.UUID != job.OrganizationID {
+			continue
+		}
 		for k, v := range job.Tags {
 			pv, ok := tags[k]
 			if !ok {
@@ -444,12 +495,13 @@ jobLoop:
 // testAcquiree is a helper type that handles asynchronously calling AcquireJob
 // and asserting whether or not it returns, blocks, or is canceled.
 type testAcquiree struct {
-	t        *testing.T
-	workerID uuid.UUID
-	pt       []database.ProvisionerType
-	tags     provisionerdserver.Tags
-	ec       chan error
-	jc       chan database.ProvisionerJob
+	t              *testing.T
+	workerID       uuid.UUID
+	organizationID uuid.NullUUID
+	pt             []database.ProvisionerType
+	tags           provisionerdserver.Tags
+	ec             chan error
+	jc             chan database.ProvisionerJob
 }
 
 func newTestAcquiree(t *testing.T, workerID uuid.UUID, pt []database.ProvisionerType, tags provisionerdserver.Tags) *testAcquiree {
@@ -465,7 +517,7 @@ func newTestAcquiree(t *testing.T, workerID uuid.UUID, pt []database.Provisioner
 
 func (a *testAcquiree) startAcquire(ctx context.Context, uut *provisionerdserver.Acquirer) {
 	go func() {
-		j, e := uut.AcquireJob(ctx, a.workerID, a.pt, a.tags)
+		j, e := uut.AcquireJob(ctx, a.workerID, a.organizationID, a.pt, a.tags)
 		a.ec <- e
 		a.jc <- j
 	}()
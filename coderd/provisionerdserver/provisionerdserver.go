@@ -32,10 +32,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/webhooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
 	"github.com/coder/coder/v2/provisioner"
@@ -72,6 +74,18 @@ type Options struct {
 	// The default function just calls UpdateProvisionerDaemonLastSeenAt.
 	// This is mainly used for testing.
 	HeartbeatFn func(context.Context) error
+
+	// Webhooks receives workspace build lifecycle events. Defaults to a
+	// publisher that discards them.
+	Webhooks webhooks.Publisher
+
+	// Notifications notifies workspace owners of failed builds they did not
+	// start themselves. Defaults to discarding notifications.
+	Notifications notifications.Enqueuer
+
+	// OrganizationID scopes the daemon to jobs from a single organization.
+	// If not valid, the daemon acquires jobs from every organization.
+	OrganizationID uuid.NullUUID
 }
 
 type server struct {
@@ -104,6 +118,11 @@ type server struct {
 
 	heartbeatInterval time.Duration
 	heartbeatFn       func(ctx context.Context) error
+
+	webhooks      webhooks.Publisher
+	notifications notifications.Enqueuer
+
+	organizationID uuid.NullUUID
 }
 
 // We use the null byte (0x00) in generating a canonical map key for tags, so
@@ -183,6 +202,12 @@ func NewServer(
 	if options.HeartbeatInterval == 0 {
 		options.HeartbeatInterval = DefaultHeartbeatInterval
 	}
+	if options.Webhooks == nil {
+		options.Webhooks = webhooks.NewNoop()
+	}
+	if options.Notifications == nil {
+		options.Notifications = notifications.NewNoop()
+	}
 
 	s := &server{
 		lifecycleCtx:                lifecycleCtx,
@@ -207,6 +232,9 @@ func NewServer(
 		acquireJobLongPollDur:       options.AcquireJobLongPollDur,
 		heartbeatInterval:           options.HeartbeatInterval,
 		heartbeatFn:                 options.HeartbeatFn,
+		webhooks:                    options.Webhooks,
+		notifications:               options.Notifications,
+		organizationID:              options.OrganizationID,
 	}
 
 	if s.heartbeatFn == nil {
@@ -287,7 +315,7 @@ func (s *server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Acquire
 	// database.
 	acqCtx, acqCancel := context.WithTimeout(ctx, s.acquireJobLongPollDur)
 	defer acqCancel()
-	job, err := s.Acquirer.AcquireJob(acqCtx, s.ID, s.Provisioners, s.Tags)
+	job, err := s.Acquirer.AcquireJob(acqCtx, s.ID, s.organizationID, s.Provisioners, s.Tags)
 	if xerrors.Is(err, context.DeadlineExceeded) {
 		s.Logger.Debug(ctx, "successful cancel")
 		return &proto.AcquiredJob{}, nil
@@ -324,7 +352,7 @@ func (s *server) AcquireJobWithCancel(stream proto.DRPCProvisionerDaemon_Acquire
 	}()
 	jec := make(chan jobAndErr, 1)
 	go func() {
-		job, err := s.Acquirer.AcquireJob(acqCtx, s.ID, s.Provisioners, s.Tags)
+		job, err := s.Acquirer.AcquireJob(acqCtx, s.ID, s.organizationID, s.Provisioners, s.Tags)
 		jec <- jobAndErr{job: job, err: err}
 	}()
 	var recvErr error
@@ -452,9 +480,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get template version: %s", err))
 		}
-		templateVariables, err := s.Database.GetTemplateVersionVariables(ctx, templateVersion.ID)
-		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
-			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
+		templateVariables, err := s.getTemplateVersionVariables(ctx, templateVersion)
+		if err != nil {
+			return nil, failJob(err.Error())
 		}
 		template, err := s.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
 		if err != nil {
@@ -468,6 +496,7 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
+		s.webhooks.Publish(ctx, codersdk.WebhookEventWorkspaceBuildStarted, workspaceBuildWebhookData(workspace, workspaceBuild, ""))
 
 		var workspaceOwnerOIDCAccessToken string
 		if s.OIDCConfig != nil {
@@ -565,6 +594,8 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 					TemplateName:                  template.Name,
 					TemplateVersion:               templateVersion.Name,
 					WorkspaceOwnerSessionToken:    sessionToken,
+					WorkspaceSnapshotId:           nullUUIDString(input.SnapshotID),
+					WorkspaceRestoreSnapshotId:    nullUUIDString(input.RestoreSnapshotID),
 				},
 				LogLevel: input.LogLevel,
 			},
@@ -580,9 +611,9 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get template version: %s", err))
 		}
-		templateVariables, err := s.Database.GetTemplateVersionVariables(ctx, templateVersion.ID)
-		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
-			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
+		templateVariables, err := s.getTemplateVersionVariables(ctx, templateVersion)
+		if err != nil {
+			return nil, failJob(err.Error())
 		}
 
 		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
@@ -595,6 +626,69 @@ func (s *server) acquireProtoJob(ctx context.Context, job database.ProvisionerJo
 				},
 			},
 		}
+	case database.ProvisionerJobTypeWorkspaceBuildDryRun:
+		var input WorkspaceBuildDryRunJob
+		err = json.Unmarshal(job.Input, &input)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("unmarshal job input %q: %s", job.Input, err))
+		}
+		workspace, err := s.Database.GetWorkspaceByID(ctx, input.WorkspaceID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get workspace: %s", err))
+		}
+		templateVersion, err := s.Database.GetTemplateVersionByID(ctx, input.TemplateVersionID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get template version: %s", err))
+		}
+		templateVariables, err := s.getTemplateVersionVariables(ctx, templateVersion)
+		if err != nil {
+			return nil, failJob(err.Error())
+		}
+		template, err := s.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get template: %s", err))
+		}
+		owner, err := s.Database.GetUserByID(ctx, workspace.OwnerID)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("get owner: %s", err))
+		}
+		// The plan is computed against the state of the build the dry-run was
+		// requested for, so the resulting resources are a delta to it.
+		var state []byte
+		if input.LastBuildID.Valid {
+			lastBuild, err := s.Database.GetWorkspaceBuildByID(ctx, input.LastBuildID.UUID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get last workspace build: %s", err))
+			}
+			state = lastBuild.ProvisionerState
+		}
+		transition, err := convertWorkspaceTransition(input.Transition)
+		if err != nil {
+			return nil, failJob(fmt.Sprintf("convert workspace transition: %s", err))
+		}
+
+		// Dry-runs never apply, so no session token or external auth tokens
+		// are handed to the provisioner.
+		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
+			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
+				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
+				VariableValues:      asVariableValues(templateVariables),
+				Metadata: &sdkproto.Metadata{
+					CoderUrl:            s.AccessURL.String(),
+					WorkspaceTransition: transition,
+					WorkspaceName:       workspace.Name,
+					WorkspaceOwner:      owner.Username,
+					WorkspaceOwnerEmail: owner.Email,
+					WorkspaceOwnerName:  owner.Name,
+					WorkspaceId:         workspace.ID.String(),
+					WorkspaceOwnerId:    owner.ID.String(),
+					TemplateId:          template.ID.String(),
+					TemplateName:        template.Name,
+					TemplateVersion:     templateVersion.Name,
+				},
+				State: state,
+			},
+		}
 	case database.ProvisionerJobTypeTemplateVersionImport:
 		var input TemplateVersionImportJob
 		err = json.Unmarshal(job.Input, &input)
@@ -685,6 +779,56 @@ func (s *server) includeLastVariableValues(ctx context.Context, templateVersionI
 	return values, nil
 }
 
+// getTemplateVersionVariables returns the variables of a template version,
+// with the values set on its template replacing the values it was pushed with.
+func (s *server) getTemplateVersionVariables(ctx context.Context, templateVersion database.TemplateVersion) ([]database.TemplateVersionVariable, error) {
+	variables, err := s.Database.GetTemplateVersionVariables(ctx, templateVersion.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get template version variables: %w", err)
+	}
+	if !templateVersion.TemplateID.Valid {
+		return variables, nil
+	}
+	values, err := s.templateVariableValues(ctx, templateVersion.TemplateID.UUID)
+	if err != nil {
+		return nil, err
+	}
+	for i, variable := range variables {
+		if value, ok := values[variable.Name]; ok {
+			variables[i].Value = value.Value
+			variables[i].Sensitive = variable.Sensitive || value.Sensitive
+		}
+	}
+	return variables, nil
+}
+
+// templateVariableValues returns the variable values set on a template by name.
+func (s *server) templateVariableValues(ctx context.Context, templateID uuid.UUID) (map[string]database.TemplateVariableValue, error) {
+	values, err := s.Database.GetTemplateVariableValuesByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template variable values: %w", err)
+	}
+	byName := make(map[string]database.TemplateVariableValue, len(values))
+	for _, value := range values {
+		byName[value.Name] = value
+	}
+	return byName, nil
+}
+
+// organizationTemplateVariableValues returns the default template variable
+// values of an organization by name.
+func (s *server) organizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) (map[string]database.OrganizationTemplateVariableValue, error) {
+	values, err := s.Database.GetOrganizationTemplateVariableValues(ctx, organizationID)
+	if err != nil {
+		return nil, xerrors.Errorf("get organization template variable values: %w", err)
+	}
+	byName := make(map[string]database.OrganizationTemplateVariableValue, len(values))
+	for _, value := range values {
+		byName[value.Name] = value
+	}
+	return byName, nil
+}
+
 func (s *server) CommitQuota(ctx context.Context, request *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error) {
 	ctx, span := s.startTrace(ctx, tracing.FuncName())
 	defer span.End()
@@ -747,6 +891,9 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 	if err != nil {
 		return nil, xerrors.Errorf("update job: %w", err)
 	}
+	// Jobs that coderd completed while they were running, e.g. because they
+	// exceeded the max build duration of their template, must be aborted.
+	canceled := job.CanceledAt.Valid || job.CompletedAt.Valid
 
 	if len(request.Logs) > 0 {
 		//nolint:exhaustruct // We append to the additional fields below.
@@ -814,12 +961,32 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 			return nil, xerrors.Errorf("get template version by job id: %w", err)
 		}
 
+		organizationValues, err := s.organizationTemplateVariableValues(ctx, templateVersion.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		templateValues := map[string]database.TemplateVariableValue{}
+		if templateVersion.TemplateID.Valid {
+			templateValues, err = s.templateVariableValues(ctx, templateVersion.TemplateID.UUID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		var variableValues []*sdkproto.VariableValue
 		var variablesWithMissingValues []string
+		var variablesWithInvalidValues []string
 		for _, templateVariable := range request.TemplateVariables {
 			s.Logger.Debug(ctx, "insert template variable", slog.F("template_version_id", templateVersion.ID), slog.F("template_variable", redactTemplateVariable(templateVariable)))
 
+			// The defaults of the organization are used for variables the
+			// version is pushed without a value for.
 			value := templateVariable.DefaultValue
+			sensitive := templateVariable.Sensitive
+			if v, ok := organizationValues[templateVariable.Name]; ok {
+				value = v.Value
+				sensitive = sensitive || v.Sensitive
+			}
 			for _, v := range request.UserVariableValues {
 				if v.Name == templateVariable.Name {
 					value = v.Value
@@ -827,14 +994,27 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 				}
 			}
 
-			if templateVariable.Required && value == "" {
+			// Values set on the template take precedence, but aren't stored
+			// with the version as they're applied to every build.
+			provisionValue, provisionSensitive := value, sensitive
+			if v, ok := templateValues[templateVariable.Name]; ok {
+				provisionValue = v.Value
+				provisionSensitive = sensitive || v.Sensitive
+			}
+
+			if templateVariable.Required && provisionValue == "" {
 				variablesWithMissingValues = append(variablesWithMissingValues, templateVariable.Name)
 			}
+			if provisionValue != "" {
+				if err := codersdk.ValidateTemplateVariableValue(templateVariable.Type, provisionValue); err != nil {
+					variablesWithInvalidValues = append(variablesWithInvalidValues, fmt.Sprintf("%s (%s)", templateVariable.Name, err))
+				}
+			}
 
 			variableValues = append(variableValues, &sdkproto.VariableValue{
 				Name:      templateVariable.Name,
-				Value:     value,
-				Sensitive: templateVariable.Sensitive,
+				Value:     provisionValue,
+				Sensitive: provisionSensitive,
 			})
 
 			_, err = s.Database.InsertTemplateVersionVariable(ctx, database.InsertTemplateVersionVariableParams{
@@ -844,7 +1024,7 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 				Type:              templateVariable.Type,
 				DefaultValue:      templateVariable.DefaultValue,
 				Required:          templateVariable.Required,
-				Sensitive:         templateVariable.Sensitive,
+				Sensitive:         sensitive,
 				Value:             value,
 			})
 			if err != nil {
@@ -855,15 +1035,18 @@ func (s *server) UpdateJob(ctx context.Context, request *proto.UpdateJobRequest)
 		if len(variablesWithMissingValues) > 0 {
 			return nil, xerrors.Errorf("required template variables need values: %s", strings.Join(variablesWithMissingValues, ", "))
 		}
+		if len(variablesWithInvalidValues) > 0 {
+			return nil, xerrors.Errorf("template variables have values of the wrong type: %s", strings.Join(variablesWithInvalidValues, ", "))
+		}
 
 		return &proto.UpdateJobResponse{
-			Canceled:       job.CanceledAt.Valid,
+			Canceled:       canceled,
 			VariableValues: variableValues,
 		}, nil
 	}
 
 	return &proto.UpdateJobResponse{
-		Canceled: job.CanceledAt.Valid,
+		Canceled: canceled,
 	}, nil
 }
 
@@ -956,10 +1139,37 @@ func (s *server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*proto.
 			return nil, err
 		}
 
+		// Failures with an error code are caused by the template or its
+		// variables, and canceled builds were stopped on purpose. Neither
+		// succeeds by trying again.
+		if failJob.ErrorCode == "" && !job.CanceledAt.Valid {
+			build.RetryAt, err = s.scheduleBuildRetry(ctx, build)
+			if err != nil {
+				s.Logger.Error(ctx, "schedule workspace build retry", slog.F("workspace_build_id", build.ID), slog.Error(err))
+			}
+		}
+
 		err = s.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(build.WorkspaceID), []byte{})
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+
+		workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+		if err != nil {
+			s.Logger.Error(ctx, "webhook - get workspace", slog.Error(err))
+		} else {
+			s.webhooks.Publish(ctx, codersdk.WebhookEventWorkspaceBuildFailed, workspaceBuildWebhookData(workspace, build, failJob.Error))
+			// Owners know about failures of the builds they start, and
+			// failures that are retried might still resolve themselves.
+			if build.InitiatorID != workspace.OwnerID && !build.RetryAt.Valid {
+				s.notifications.Enqueue(ctx, workspace.OwnerID, codersdk.NotificationTemplateWorkspaceBuildFailed, map[string]string{
+					"workspace_name": workspace.Name,
+					"transition":     string(build.Transition),
+					"build_number":   strconv.FormatInt(int64(build.BuildNumber), 10),
+					"reason":         failJob.Error,
+				})
+			}
+		}
 	case *proto.FailedJob_TemplateImport_:
 	}
 
@@ -1122,6 +1332,7 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 				Required:            richParameter.Required,
 				DisplayOrder:        richParameter.Order,
 				Ephemeral:           richParameter.Ephemeral,
+				DisplayCondition:    richParameter.DisplayCondition,
 			})
 			if err != nil {
 				return nil, xerrors.Errorf("insert parameter: %w", err)
@@ -1130,6 +1341,23 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 
 		var completedError sql.NullString
 
+		// Display conditions can only be checked once all parameters are
+		// known, as they refer to each other.
+		conditionParameters := make([]codersdk.TemplateVersionParameter, 0, len(jobType.TemplateImport.RichParameters))
+		for _, richParameter := range jobType.TemplateImport.RichParameters {
+			conditionParameters = append(conditionParameters, codersdk.TemplateVersionParameter{
+				Name:             richParameter.Name,
+				DisplayCondition: richParameter.DisplayCondition,
+			})
+		}
+		err = codersdk.ValidateParameterDisplayConditions(conditionParameters)
+		if err != nil {
+			completedError = sql.NullString{
+				String: fmt.Sprintf("invalid parameter display conditions: %s", err),
+				Valid:  true,
+			}
+		}
+
 		for _, externalAuthProvider := range jobType.TemplateImport.ExternalAuthProviders {
 			contains := false
 			for _, configuredProvider := range s.ExternalAuthConfigs {
@@ -1327,6 +1555,10 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			return nil, xerrors.Errorf("complete job: %w", err)
 		}
 
+		if getWorkspaceError == nil {
+			s.webhooks.Publish(ctx, codersdk.WebhookEventWorkspaceBuildSucceeded, workspaceBuildWebhookData(workspace, workspaceBuild, ""))
+		}
+
 		// audit the outcome of the workspace build
 		if getWorkspaceError == nil {
 			auditor := s.Auditor.Load()
@@ -1376,13 +1608,39 @@ func (s *server) CompleteJob(ctx context.Context, completed *proto.CompletedJob)
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
 	case *proto.CompletedJob_TemplateDryRun_:
+		transition := database.WorkspaceTransitionStart
+		if job.Type == database.ProvisionerJobTypeWorkspaceBuildDryRun {
+			var input WorkspaceBuildDryRunJob
+			err = json.Unmarshal(job.Input, &input)
+			if err != nil {
+				return nil, xerrors.Errorf("unmarshal workspace build dry-run input: %w", err)
+			}
+			transition = input.Transition
+
+			// Only the latest drift check of the workspace is recorded, so
+			// this is a no-op for dry-runs requested through the API.
+			drifted := jobType.TemplateDryRun.DriftedResources
+			if drifted == nil {
+				drifted = []string{}
+			}
+			err = s.Database.UpdateWorkspaceDriftResult(ctx, database.UpdateWorkspaceDriftResultParams{
+				BuildID:          input.LastBuildID,
+				CheckedAt:        dbtime.Now(),
+				DriftedResources: drifted,
+				WorkspaceID:      input.WorkspaceID,
+				JobID:            jobID,
+			})
+			if err != nil {
+				return nil, xerrors.Errorf("update workspace drift: %w", err)
+			}
+		}
 		for _, resource := range jobType.TemplateDryRun.Resources {
 			s.Logger.Info(ctx, "inserting template dry-run job resource",
 				slog.F("job_id", job.ID.String()),
 				slog.F("resource_name", resource.Name),
 				slog.F("resource_type", resource.Type))
 
-			err = InsertWorkspaceResource(ctx, s.Database, jobID, database.WorkspaceTransitionStart, resource, telemetrySnapshot)
+			err = InsertWorkspaceResource(ctx, s.Database, jobID, transition, resource, telemetrySnapshot)
 			if err != nil {
 				return nil, xerrors.Errorf("insert resource: %w", err)
 			}
@@ -1445,6 +1703,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		Hide:       protoResource.Hide,
 		Icon:       protoResource.Icon,
 		DailyCost:  protoResource.DailyCost,
+		Snapshot:   protoResource.Snapshot,
 		InstanceType: sql.NullString{
 			String: protoResource.InstanceType,
 			Valid:  protoResource.InstanceType != "",
@@ -1555,8 +1814,22 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 		scriptStartBlocksLogin := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStart := make([]bool, 0, len(prAgent.Scripts))
 		scriptRunOnStop := make([]bool, 0, len(prAgent.Scripts))
+		scriptPhase := make([]database.WorkspaceAgentScriptPhase, 0, len(prAgent.Scripts))
 
 		for _, script := range prAgent.Scripts {
+			phase := database.WorkspaceAgentScriptPhase(script.Phase)
+			if phase == "" {
+				// Templates that don't set a phase run scripts with the
+				// start or stop scripts, as before phases existed.
+				phase = database.WorkspaceAgentScriptPhaseStart
+				if script.RunOnStop && !script.RunOnStart {
+					phase = database.WorkspaceAgentScriptPhaseStop
+				}
+			}
+			if !phase.Valid() {
+				return xerrors.Errorf("script %q has invalid phase %q", script.DisplayName, script.Phase)
+			}
+
 			logSourceIDs = append(logSourceIDs, uuid.New())
 			logSourceDisplayNames = append(logSourceDisplayNames, script.DisplayName)
 			logSourceIcons = append(logSourceIcons, script.Icon)
@@ -1567,6 +1840,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			scriptStartBlocksLogin = append(scriptStartBlocksLogin, script.StartBlocksLogin)
 			scriptRunOnStart = append(scriptRunOnStart, script.RunOnStart)
 			scriptRunOnStop = append(scriptRunOnStop, script.RunOnStop)
+			scriptPhase = append(scriptPhase, phase)
 		}
 
 		_, err = db.InsertWorkspaceAgentLogSources(ctx, database.InsertWorkspaceAgentLogSourcesParams{
@@ -1591,6 +1865,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			StartBlocksLogin: scriptStartBlocksLogin,
 			RunOnStart:       scriptRunOnStart,
 			RunOnStop:        scriptRunOnStop,
+			Phase:            scriptPhase,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert agent scripts: %w", err)
@@ -1847,6 +2122,56 @@ func convertWorkspaceTransition(transition database.WorkspaceTransition) (sdkpro
 	}
 }
 
+// nullUUIDString returns the string form of id, or an empty string if it isn't
+// set.
+func nullUUIDString(id uuid.NullUUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return id.UUID.String()
+}
+
+// scheduleBuildRetry schedules a retry of a failed build if the retry policy
+// of its template allows another attempt. The time of the retry is returned.
+func (s *server) scheduleBuildRetry(ctx context.Context, build database.WorkspaceBuild) (sql.NullTime, error) {
+	workspace, err := s.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		return sql.NullTime{}, xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := s.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return sql.NullTime{}, xerrors.Errorf("get template: %w", err)
+	}
+	retryAt, ok := buildRetryAt(template, build, dbtime.Now())
+	if !ok {
+		return sql.NullTime{}, nil
+	}
+	err = s.Database.UpdateWorkspaceBuildRetryAtByID(ctx, database.UpdateWorkspaceBuildRetryAtByIDParams{
+		ID:        build.ID,
+		RetryAt:   sql.NullTime{Time: retryAt, Valid: true},
+		UpdatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		return sql.NullTime{}, xerrors.Errorf("update workspace build: %w", err)
+	}
+	return sql.NullTime{Time: retryAt, Valid: true}, nil
+}
+
+// buildRetryAt returns when a build that failed transiently is retried
+// according to the retry policy of its template. The backoff of the template
+// doubles with every attempt. False is returned if the policy does not allow
+// another attempt.
+func buildRetryAt(template database.Template, build database.WorkspaceBuild, now time.Time) (time.Time, bool) {
+	if build.Attempt >= template.BuildRetryMaxAttempts {
+		return time.Time{}, false
+	}
+	backoff := time.Duration(template.BuildRetryBackoff)
+	for i := int32(1); i < build.Attempt; i++ {
+		backoff *= 2
+	}
+	return now.Add(backoff), true
+}
+
 func auditActionFromTransition(transition database.WorkspaceTransition) database.AuditAction {
 	switch transition {
 	case database.WorkspaceTransitionStart:
@@ -1860,6 +2185,19 @@ func auditActionFromTransition(transition database.WorkspaceTransition) database
 	}
 }
 
+func workspaceBuildWebhookData(workspace database.Workspace, build database.WorkspaceBuild, buildErr string) codersdk.WebhookWorkspaceBuildData {
+	return codersdk.WebhookWorkspaceBuildData{
+		WorkspaceID:   workspace.ID,
+		WorkspaceName: workspace.Name,
+		OwnerID:       workspace.OwnerID,
+		TemplateID:    workspace.TemplateID,
+		BuildID:       build.ID,
+		BuildNumber:   build.BuildNumber,
+		Transition:    codersdk.WorkspaceTransition(build.Transition),
+		Error:         buildErr,
+	}
+}
+
 type TemplateVersionImportJob struct {
 	TemplateVersionID  uuid.UUID                `json:"template_version_id"`
 	UserVariableValues []codersdk.VariableValue `json:"user_variable_values"`
@@ -1870,6 +2208,11 @@ type WorkspaceProvisionJob struct {
 	WorkspaceBuildID uuid.UUID `json:"workspace_build_id"`
 	DryRun           bool      `json:"dry_run"`
 	LogLevel         string    `json:"log_level,omitempty"`
+	// SnapshotID is set on builds that take a snapshot of the workspace.
+	SnapshotID uuid.NullUUID `json:"snapshot_id"`
+	// RestoreSnapshotID is set on builds that restore a snapshot of the
+	// workspace.
+	RestoreSnapshotID uuid.NullUUID `json:"restore_snapshot_id"`
 }
 
 // TemplateVersionDryRunJob is the payload for the "template_version_dry_run" job type.
@@ -1879,6 +2222,17 @@ type TemplateVersionDryRunJob struct {
 	RichParameterValues []database.WorkspaceBuildParameter `json:"rich_parameter_values"`
 }
 
+// WorkspaceBuildDryRunJob is the payload for the "workspace_build_dry_run" job type.
+type WorkspaceBuildDryRunJob struct {
+	WorkspaceID       uuid.UUID                    `json:"workspace_id"`
+	TemplateVersionID uuid.UUID                    `json:"template_version_id"`
+	Transition        database.WorkspaceTransition `json:"transition"`
+	// LastBuildID is the build whose provisioner state is planned against.
+	// It is not set for workspaces without builds.
+	LastBuildID         uuid.NullUUID                      `json:"last_build_id"`
+	RichParameterValues []database.WorkspaceBuildParameter `json:"rich_parameter_values"`
+}
+
 func asVariableValues(templateVariables []database.TemplateVersionVariable) []*sdkproto.VariableValue {
 	var apiVariableValues []*sdkproto.VariableValue
 	for _, v := range templateVariables {
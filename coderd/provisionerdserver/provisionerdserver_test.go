@@ -33,6 +33,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/externalauth"
+	"github.com/coder/coder/v2/coderd/notifications"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
@@ -438,6 +439,81 @@ func TestAcquireJob(t *testing.T) {
 			require.NoError(t, err)
 			require.JSONEq(t, string(want), string(got))
 		})
+		t.Run(tc.name+"_WorkspaceBuildDryRun", func(t *testing.T) {
+			t.Parallel()
+			srv, db, ps, _ := setup(t, false, nil)
+			ctx := context.Background()
+
+			user := dbgen.User(t, db, database.User{})
+			template := dbgen.Template(t, db, database.Template{
+				Name:        "template",
+				Provisioner: database.ProvisionerTypeEcho,
+				CreatedBy:   user.ID,
+			})
+			version := dbgen.TemplateVersion(t, db, database.TemplateVersion{
+				TemplateID: uuid.NullUUID{UUID: template.ID, Valid: true},
+				CreatedBy:  user.ID,
+			})
+			workspace := dbgen.Workspace(t, db, database.Workspace{
+				TemplateID: template.ID,
+				OwnerID:    user.ID,
+			})
+			build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID:       workspace.ID,
+				TemplateVersionID: version.ID,
+				JobID:             uuid.New(),
+				ProvisionerState:  []byte("state"),
+			})
+			file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+			_ = dbgen.ProvisionerJob(t, db, ps, database.ProvisionerJob{
+				InitiatorID:   user.ID,
+				Provisioner:   database.ProvisionerTypeEcho,
+				StorageMethod: database.ProvisionerStorageMethodFile,
+				FileID:        file.ID,
+				Type:          database.ProvisionerJobTypeWorkspaceBuildDryRun,
+				Input: must(json.Marshal(provisionerdserver.WorkspaceBuildDryRunJob{
+					WorkspaceID:       workspace.ID,
+					TemplateVersionID: version.ID,
+					Transition:        database.WorkspaceTransitionStop,
+					LastBuildID:       uuid.NullUUID{UUID: build.ID, Valid: true},
+					RichParameterValues: []database.WorkspaceBuildParameter{{
+						Name:  "region",
+						Value: "eu",
+					}},
+				})),
+			})
+
+			job, err := tc.acquire(ctx, srv)
+			require.NoError(t, err)
+
+			got, err := json.Marshal(job.Type)
+			require.NoError(t, err)
+
+			want, err := json.Marshal(&proto.AcquiredJob_TemplateDryRun_{
+				TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
+					RichParameterValues: []*sdkproto.RichParameterValue{{
+						Name:  "region",
+						Value: "eu",
+					}},
+					Metadata: &sdkproto.Metadata{
+						CoderUrl:            (&url.URL{}).String(),
+						WorkspaceTransition: sdkproto.WorkspaceTransition_STOP,
+						WorkspaceName:       workspace.Name,
+						WorkspaceOwner:      user.Username,
+						WorkspaceOwnerEmail: user.Email,
+						WorkspaceOwnerName:  user.Name,
+						WorkspaceId:         workspace.ID.String(),
+						WorkspaceOwnerId:    user.ID.String(),
+						TemplateId:          template.ID.String(),
+						TemplateName:        template.Name,
+						TemplateVersion:     version.Name,
+					},
+					State: []byte("state"),
+				},
+			})
+			require.NoError(t, err)
+			require.JSONEq(t, string(want), string(got))
+		})
 		t.Run(tc.name+"_TemplateVersionImport", func(t *testing.T) {
 			t.Parallel()
 			srv, db, ps, _ := setup(t, false, nil)
@@ -818,9 +894,12 @@ func TestFailJob(t *testing.T) {
 		//
 		//	(*Server).FailJob       audit log - get build {"error": "sql: no rows in result set"}
 		ignoreLogErrors := true
-		srv, db, ps, pd := setup(t, ignoreLogErrors, &overrides{})
+		enqueuer := &fakeEnqueuer{}
+		srv, db, ps, pd := setup(t, ignoreLogErrors, &overrides{notifications: enqueuer})
 		workspace, err := db.InsertWorkspace(ctx, database.InsertWorkspaceParams{
 			ID:               uuid.New(),
+			OwnerID:          uuid.New(),
+			Name:             "dev",
 			AutomaticUpdates: database.AutomaticUpdatesNever,
 		})
 		require.NoError(t, err)
@@ -828,7 +907,9 @@ func TestFailJob(t *testing.T) {
 		err = db.InsertWorkspaceBuild(ctx, database.InsertWorkspaceBuildParams{
 			ID:          buildID,
 			WorkspaceID: workspace.ID,
+			BuildNumber: 2,
 			Transition:  database.WorkspaceTransitionStart,
+			InitiatorID: uuid.New(),
 			Reason:      database.BuildReasonInitiator,
 		})
 		require.NoError(t, err)
@@ -874,6 +955,7 @@ func TestFailJob(t *testing.T) {
 					State: []byte("some state"),
 				},
 			},
+			Error: "terraform exited",
 		})
 		require.NoError(t, err)
 		<-publishedWorkspace
@@ -881,6 +963,100 @@ func TestFailJob(t *testing.T) {
 		build, err := db.GetWorkspaceBuildByID(ctx, buildID)
 		require.NoError(t, err)
 		require.Equal(t, "some state", string(build.ProvisionerState))
+
+		// The owner is notified of failed builds started by someone else.
+		require.Equal(t, []fakeNotification{{
+			UserID:   workspace.OwnerID,
+			Template: codersdk.NotificationTemplateWorkspaceBuildFailed,
+			Labels: map[string]string{
+				"workspace_name": "dev",
+				"transition":     "start",
+				"build_number":   "2",
+				"reason":         "terraform exited",
+			},
+		}}, enqueuer.notifications())
+	})
+	t.Run("WorkspaceBuildRetry", func(t *testing.T) {
+		t.Parallel()
+		enqueuer := &fakeEnqueuer{}
+		srv, db, _, pd := setup(t, false, &overrides{notifications: enqueuer})
+		template := dbgen.Template(t, db, database.Template{})
+		err := db.UpdateTemplateBuildRetryPolicyByID(ctx, database.UpdateTemplateBuildRetryPolicyByIDParams{
+			ID:                    template.ID,
+			BuildRetryMaxAttempts: 3,
+			BuildRetryBackoff:     int64(time.Minute),
+			UpdatedAt:             dbtime.Now(),
+		})
+		require.NoError(t, err)
+		user := dbgen.User(t, db, database.User{})
+		workspace := dbgen.Workspace(t, db, database.Workspace{
+			TemplateID: template.ID,
+			OwnerID:    user.ID,
+		})
+
+		failBuild := func(attempt int32, errorCode string) database.WorkspaceBuild {
+			buildID := uuid.New()
+			input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+				WorkspaceBuildID: buildID,
+			})
+			require.NoError(t, err)
+			job, err := db.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
+				ID:            uuid.New(),
+				Input:         input,
+				Provisioner:   database.ProvisionerTypeEcho,
+				Type:          database.ProvisionerJobTypeWorkspaceBuild,
+				StorageMethod: database.ProvisionerStorageMethodFile,
+			})
+			require.NoError(t, err)
+			dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				ID:          buildID,
+				WorkspaceID: workspace.ID,
+				BuildNumber: attempt,
+				JobID:       job.ID,
+				Attempt:     attempt,
+			})
+			_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+				StartedAt: sql.NullTime{
+					Time:  dbtime.Now(),
+					Valid: true,
+				},
+				WorkerID: uuid.NullUUID{
+					UUID:  pd.ID,
+					Valid: true,
+				},
+				Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+			})
+			require.NoError(t, err)
+
+			_, err = srv.FailJob(ctx, &proto.FailedJob{
+				JobId: job.ID.String(),
+				Type: &proto.FailedJob_WorkspaceBuild_{
+					WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{},
+				},
+				Error:     "connection reset by peer",
+				ErrorCode: errorCode,
+			})
+			require.NoError(t, err)
+			build, err := db.GetWorkspaceBuildByID(ctx, buildID)
+			require.NoError(t, err)
+			return build
+		}
+
+		// The backoff doubles with every attempt.
+		before := dbtime.Now()
+		build := failBuild(2, "")
+		require.True(t, build.RetryAt.Valid)
+		require.WithinDuration(t, before.Add(2*time.Minute), build.RetryAt.Time, time.Minute)
+		// Retried failures are not notified.
+		require.Empty(t, enqueuer.notifications())
+
+		// The last attempt is not retried.
+		build = failBuild(3, "")
+		require.False(t, build.RetryAt.Valid)
+
+		// Failures with an error code are not transient.
+		build = failBuild(1, "REQUIRED_TEMPLATE_VARIABLES")
+		require.False(t, build.RetryAt.Valid)
 	})
 }
 
@@ -1664,6 +1840,54 @@ func TestInsertWorkspaceResource(t *testing.T) {
 		}, agent.DisplayApps)
 	})
 
+	t.Run("ScriptPhases", func(t *testing.T) {
+		t.Parallel()
+		db := dbmem.New()
+		job := uuid.New()
+		err := insert(db, job, &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Startup",
+					RunOnStart:  true,
+				}, {
+					DisplayName: "Shutdown",
+					RunOnStop:   true,
+				}, {
+					DisplayName: "Drain",
+					RunOnStop:   true,
+					Phase:       "pre_stop",
+				}},
+			}},
+		})
+		require.NoError(t, err)
+		resources, err := db.GetWorkspaceResourcesByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		agents, err := db.GetWorkspaceAgentsByResourceIDs(ctx, []uuid.UUID{resources[0].ID})
+		require.NoError(t, err)
+		require.Len(t, agents, 1)
+		scripts, err := db.GetWorkspaceAgentScriptsByAgentIDs(ctx, []uuid.UUID{agents[0].ID})
+		require.NoError(t, err)
+		require.Len(t, scripts, 3)
+		require.Equal(t, database.WorkspaceAgentScriptPhaseStart, scripts[0].Phase)
+		require.Equal(t, database.WorkspaceAgentScriptPhaseStop, scripts[1].Phase)
+		require.Equal(t, database.WorkspaceAgentScriptPhasePreStop, scripts[2].Phase)
+
+		err = insert(db, uuid.New(), &sdkproto.Resource{
+			Name: "something",
+			Type: "aws_instance",
+			Agents: []*sdkproto.Agent{{
+				Scripts: []*sdkproto.Script{{
+					DisplayName: "Startup",
+					Phase:       "whenever",
+				}},
+			}},
+		})
+		require.ErrorContains(t, err, "invalid phase")
+	})
+
 	t.Run("AllDisplayApps", func(t *testing.T) {
 		t.Parallel()
 		db := dbmem.New()
@@ -1727,6 +1951,7 @@ type overrides struct {
 	acquireJobLongPollDuration  time.Duration
 	heartbeatFn                 func(ctx context.Context) error
 	heartbeatInterval           time.Duration
+	notifications               notifications.Enqueuer
 }
 
 func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisionerDaemonServer, database.Store, pubsub.Pubsub, database.ProvisionerDaemon) {
@@ -1815,12 +2040,36 @@ func setup(t *testing.T, ignoreLogErrors bool, ov *overrides) (proto.DRPCProvisi
 			AcquireJobLongPollDur: pollDur,
 			HeartbeatInterval:     ov.heartbeatInterval,
 			HeartbeatFn:           ov.heartbeatFn,
+			Notifications:         ov.notifications,
 		},
 	)
 	require.NoError(t, err)
 	return srv, db, ps, daemon
 }
 
+type fakeNotification struct {
+	UserID   uuid.UUID
+	Template codersdk.NotificationTemplate
+	Labels   map[string]string
+}
+
+type fakeEnqueuer struct {
+	mu   sync.Mutex
+	sent []fakeNotification
+}
+
+func (f *fakeEnqueuer) Enqueue(_ context.Context, userID uuid.UUID, template codersdk.NotificationTemplate, labels map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, fakeNotification{UserID: userID, Template: template, Labels: labels})
+}
+
+func (f *fakeEnqueuer) notifications() []fakeNotification {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeNotification(nil), f.sent...)
+}
+
 func must[T any](value T, err error) T {
 	if err != nil {
 		panic(err)
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"sort"
@@ -19,8 +20,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/logarchive"
+	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -214,6 +219,84 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
 }
 
+// @Summary Update provisioner job priority
+// @ID update-provisioner-job-priority
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param job path string true "Job ID" format(uuid)
+// @Param request body codersdk.UpdateProvisionerJobPriorityRequest true "Update priority request"
+// @Success 200 {object} codersdk.ProvisionerJob
+// @Router /organizations/{organization}/provisionerjobs/{job}/priority [put]
+func (api *API) putProvisionerJobPriority(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+	jobID, ok := httpmw.ParseUUIDParam(rw, r, "job")
+	if !ok {
+		return
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon.InOrg(organization.ID)) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateProvisionerJobPriorityRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, jobID)
+	if httpapi.Is404Error(err) || (err == nil && job.OrganizationID != organization.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if job.JobStatus != database.ProvisionerJobStatusPending {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only pending jobs can be reprioritized.",
+			Detail:  fmt.Sprintf("Job status is %q.", job.JobStatus),
+		})
+		return
+	}
+
+	err = api.Database.UpdateProvisionerJobPriorityByID(ctx, database.UpdateProvisionerJobPriorityByIDParams{
+		ID:        job.ID,
+		Priority:  req.Priority,
+		UpdatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating provisioner job priority.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	jobs, err := api.Database.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{job.ID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(jobs) == 0 {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJob(jobs[0]))
+}
+
 func convertProvisionerJobLogs(provisionerJobLogs []database.ProvisionerJobLog) []codersdk.ProvisionerJobLog {
 	sdk := make([]codersdk.ProvisionerJobLog, 0, len(provisionerJobLogs))
 	for _, log := range provisionerJobLogs {
@@ -242,6 +325,7 @@ func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionR
 		ErrorCode:     codersdk.JobErrorCode(provisionerJob.ErrorCode.String),
 		FileID:        provisionerJob.FileID,
 		Tags:          provisionerJob.Tags,
+		Priority:      provisionerJob.Priority,
 		QueuePosition: int(pj.QueuePosition),
 		QueueSize:     int(pj.QueueSize),
 	}
@@ -264,10 +348,7 @@ func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionR
 }
 
 func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID, after int64, rw http.ResponseWriter) {
-	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
-		JobID:        jobID,
-		CreatedAfter: after,
-	})
+	logs, err := getProvisionerLogsAfterID(ctx, db, jobID, after)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching provisioner logs.",
@@ -281,6 +362,38 @@ func fetchAndWriteLogs(ctx context.Context, db database.Store, jobID uuid.UUID,
 	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJobLogs(logs))
 }
 
+// getProvisionerLogsAfterID returns the logs of the job after the given ID,
+// reading them from the job's log archive once they have been archived.
+func getProvisionerLogsAfterID(ctx context.Context, db database.Store, jobID uuid.UUID, after int64) ([]database.ProvisionerJobLog, error) {
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID:        jobID,
+		CreatedAfter: after,
+	})
+	if err != nil || len(logs) > 0 {
+		return logs, err
+	}
+	// Logs are removed from provisioner_job_logs in the same transaction
+	// they are archived in, so no logs means they might be archived.
+	archive, err := db.GetProvisionerJobLogArchiveByJobID(ctx, jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return logs, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get log archive: %w", err)
+	}
+	archived, err := logarchive.Decompress(archive)
+	if err != nil {
+		return nil, xerrors.Errorf("decompress log archive: %w", err)
+	}
+	logs = make([]database.ProvisionerJobLog, 0, len(archived))
+	for _, log := range archived {
+		if log.ID > after {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
 func jobIsComplete(logger slog.Logger, job database.ProvisionerJob) bool {
 	status := codersdk.ProvisionerJobStatus(job.JobStatus)
 	switch status {
@@ -480,10 +593,7 @@ func (f *logFollower) listener(_ context.Context, message []byte, err error) {
 // connection.
 func (f *logFollower) query() error {
 	f.logger.Debug(f.ctx, "querying logs", slog.F("after", f.after))
-	logs, err := f.db.GetProvisionerLogsAfterID(f.ctx, database.GetProvisionerLogsAfterIDParams{
-		JobID:        f.jobID,
-		CreatedAfter: f.after,
-	})
+	logs, err := getProvisionerLogsAfterID(f.ctx, f.db, f.jobID, f.after)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return xerrors.Errorf("error fetching logs: %w", err)
 	}
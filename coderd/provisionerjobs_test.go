@@ -2,11 +2,13 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
@@ -90,3 +92,48 @@ func TestProvisionerJobLogs(t *testing.T) {
 		}
 	})
 }
+
+func TestProvisionerJobPriority(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Bump", func(t *testing.T) {
+		t.Parallel()
+		// No provisioner daemon, so jobs remain pending.
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		first := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		second := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		version, err := client.TemplateVersion(ctx, second.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, version.Job.QueuePosition)
+
+		job, err := client.UpdateProvisionerJobPriority(ctx, user.OrganizationID, second.Job.ID, codersdk.UpdateProvisionerJobPriorityRequest{
+			Priority: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), job.Priority)
+		require.Equal(t, 1, job.QueuePosition)
+
+		version, err = client.TemplateVersion(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, version.Job.QueuePosition)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.UpdateProvisionerJobPriority(ctx, user.OrganizationID, version.Job.ID, codersdk.UpdateProvisionerJobPriorityRequest{
+			Priority: 1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
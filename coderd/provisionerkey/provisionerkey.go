@@ -0,0 +1,55 @@
+// Package provisionerkey generates the keys that authenticate external
+// provisioner daemons to an organization.
+package provisionerkey
+
+import (
+	"crypto/sha256"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// secretLength is the length of the secret of a key. Keys are only compared by
+// their hash, so they are long enough to not be guessed.
+const secretLength = 43
+
+// New generates a key for the organization, returning the secret as well as
+// the database representation. Only the hash of the secret is stored, so the
+// secret must be shown to the user when the key is created.
+func New(organizationID uuid.UUID, name string, tags map[string]string) (database.InsertProvisionerKeyParams, string, error) {
+	secret, hashed, err := NewSecret()
+	if err != nil {
+		return database.InsertProvisionerKeyParams{}, "", err
+	}
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	return database.InsertProvisionerKeyParams{
+		ID:             uuid.New(),
+		CreatedAt:      dbtime.Now(),
+		OrganizationID: organizationID,
+		Name:           name,
+		HashedSecret:   hashed,
+		Tags:           tags,
+	}, secret, nil
+}
+
+// NewSecret generates a secret and its hash. It is used when creating and
+// rotating keys.
+func NewSecret() (secret string, hashed []byte, err error) {
+	secret, err = cryptorand.String(secretLength)
+	if err != nil {
+		return "", nil, xerrors.Errorf("generate secret: %w", err)
+	}
+	return secret, HashSecret(secret), nil
+}
+
+// HashSecret returns the hash of the secret, which keys are looked up by.
+func HashSecret(secret string) []byte {
+	hashed := sha256.Sum256([]byte(secret))
+	return hashed[:]
+}
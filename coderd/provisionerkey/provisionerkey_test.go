@@ -0,0 +1,30 @@
+package provisionerkey_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/provisionerkey"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	params, secret, err := provisionerkey.New(orgID, "isolated", map[string]string{"network": "isolated"})
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+	require.Equal(t, orgID, params.OrganizationID)
+	require.Equal(t, "isolated", params.Name)
+	require.Equal(t, "isolated", params.Tags["network"])
+	require.Equal(t, provisionerkey.HashSecret(secret), params.HashedSecret)
+	require.NotContains(t, string(params.HashedSecret), secret)
+
+	other, otherSecret, err := provisionerkey.New(orgID, "other", nil)
+	require.NoError(t, err)
+	require.NotEqual(t, secret, otherSecret)
+	require.NotEqual(t, params.HashedSecret, other.HashedSecret)
+	require.NotNil(t, other.Tags)
+}
@@ -0,0 +1,191 @@
+// Package provisionertags matches the tags of provisioner daemons against the
+// tags and requirements of provisioner jobs. The SQL of AcquireProvisionerJob
+// must match jobs in the same way.
+package provisionertags
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+type Operator string
+
+const (
+	OperatorIn    Operator = "in"
+	OperatorNotIn Operator = "not_in"
+)
+
+// Wildcard matches any sequence of characters in the values of requirements.
+const Wildcard = "*"
+
+// Requirement constrains the value of a tag of the provisioner daemons that
+// may acquire a job.
+type Requirement struct {
+	Key      string   `json:"key"`
+	Operator Operator `json:"operator"`
+	// Values may contain wildcards. A daemon without the tag satisfies "not_in"
+	// requirements, but never "in" requirements.
+	Values []string `json:"values"`
+}
+
+// Parse parses a requirement expression. These are supported:
+//
+//	gpu=true
+//	region=eu-*
+//	os!=windows
+//	region in [eu-west, eu-central]
+//	region not in [us-*, ap-*]
+func Parse(expr string) (Requirement, error) {
+	expr = strings.TrimSpace(expr)
+	var (
+		key      string
+		operator Operator
+		values   []string
+	)
+	if i := strings.IndexByte(expr, '['); i != -1 {
+		if !strings.HasSuffix(expr, "]") {
+			return Requirement{}, xerrors.Errorf("requirement %q: list must end with ]", expr)
+		}
+		fields := strings.Fields(expr[:i])
+		switch {
+		case len(fields) == 2 && fields[1] == "in":
+			operator = OperatorIn
+		case len(fields) == 3 && fields[1] == "not" && fields[2] == "in":
+			operator = OperatorNotIn
+		default:
+			return Requirement{}, xerrors.Errorf("requirement %q: expected <key> in [<values>] or <key> not in [<values>]", expr)
+		}
+		key = fields[0]
+		// An empty list has no values, rather than a single empty one.
+		if list := strings.TrimSpace(expr[i+1 : len(expr)-1]); list != "" {
+			for _, value := range strings.Split(list, ",") {
+				values = append(values, strings.TrimSpace(value))
+			}
+		}
+	} else {
+		operator = OperatorIn
+		sep := "="
+		if strings.Contains(expr, "!=") {
+			operator = OperatorNotIn
+			sep = "!="
+		}
+		var value string
+		var ok bool
+		key, value, ok = strings.Cut(expr, sep)
+		if !ok {
+			return Requirement{}, xerrors.Errorf("requirement %q: expected <key>=<value>, <key>!=<value> or <key> in [<values>]", expr)
+		}
+		key = strings.TrimSpace(key)
+		values = []string{strings.TrimSpace(value)}
+	}
+
+	r := Requirement{Key: key, Operator: operator, Values: values}
+	if err := r.Valid(); err != nil {
+		return Requirement{}, xerrors.Errorf("requirement %q: %w", expr, err)
+	}
+	return r, nil
+}
+
+// ParseAll parses every requirement expression.
+func ParseAll(exprs []string) ([]Requirement, error) {
+	requirements := make([]Requirement, 0, len(exprs))
+	for _, expr := range exprs {
+		r, err := Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, r)
+	}
+	return requirements, nil
+}
+
+// Valid returns an error if the requirement can't be matched.
+func (r Requirement) Valid() error {
+	if r.Key == "" || strings.ContainsAny(r.Key, " \t=![],") {
+		return xerrors.Errorf("invalid key %q", r.Key)
+	}
+	if r.Operator != OperatorIn && r.Operator != OperatorNotIn {
+		return xerrors.Errorf("unknown operator %q", r.Operator)
+	}
+	if len(r.Values) == 0 {
+		return xerrors.New("at least one value is required")
+	}
+	for _, value := range r.Values {
+		if strings.ContainsAny(value, "\x00[],") {
+			return xerrors.Errorf("invalid value %q", value)
+		}
+	}
+	return nil
+}
+
+// String formats the requirement as an expression accepted by Parse.
+func (r Requirement) String() string {
+	if len(r.Values) == 1 {
+		if r.Operator == OperatorNotIn {
+			return r.Key + "!=" + r.Values[0]
+		}
+		return r.Key + "=" + r.Values[0]
+	}
+	operator := "in"
+	if r.Operator == OperatorNotIn {
+		operator = "not in"
+	}
+	return fmt.Sprintf("%s %s [%s]", r.Key, operator, strings.Join(r.Values, ", "))
+}
+
+// Satisfied returns whether a daemon with the tags satisfies the requirement.
+func (r Requirement) Satisfied(tags map[string]string) bool {
+	value, ok := tags[r.Key]
+	matched := false
+	if ok {
+		for _, pattern := range r.Values {
+			if matchValue(pattern, value) {
+				matched = true
+				break
+			}
+		}
+	}
+	return matched == (r.Operator == OperatorIn)
+}
+
+// Matches returns whether a daemon with the tags may acquire a job. The
+// daemon must have every tag of the job with the same value, and satisfy
+// every requirement of the job.
+func Matches(daemonTags, jobTags map[string]string, requirements []Requirement) bool {
+	for k, v := range jobTags {
+		dv, ok := daemonTags[k]
+		if !ok || dv != v {
+			return false
+		}
+	}
+	for _, r := range requirements {
+		if !r.Satisfied(daemonTags) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchValue reports whether value matches pattern, where wildcards in pattern
+// match any sequence of characters.
+func matchValue(pattern, value string) bool {
+	parts := strings.Split(pattern, Wildcard)
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i == -1 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
@@ -0,0 +1,98 @@
+package provisionertags_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/provisionertags"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Expr     string
+		Expected provisionertags.Requirement
+		Error    bool
+	}{
+		{
+			Expr:     "gpu=true",
+			Expected: provisionertags.Requirement{Key: "gpu", Operator: provisionertags.OperatorIn, Values: []string{"true"}},
+		},
+		{
+			Expr:     " os != windows ",
+			Expected: provisionertags.Requirement{Key: "os", Operator: provisionertags.OperatorNotIn, Values: []string{"windows"}},
+		},
+		{
+			Expr:     "region in [eu-west, eu-central]",
+			Expected: provisionertags.Requirement{Key: "region", Operator: provisionertags.OperatorIn, Values: []string{"eu-west", "eu-central"}},
+		},
+		{
+			Expr:     "region not in [us-*]",
+			Expected: provisionertags.Requirement{Key: "region", Operator: provisionertags.OperatorNotIn, Values: []string{"us-*"}},
+		},
+		{Expr: "gpu", Error: true},
+		{Expr: "=true", Error: true},
+		{Expr: "region in [eu-west", Error: true},
+		{Expr: "region within [eu-west]", Error: true},
+		{Expr: "region in []", Error: true},
+	} {
+		tc := tc
+		t.Run(tc.Expr, func(t *testing.T) {
+			t.Parallel()
+			r, err := provisionertags.Parse(tc.Expr)
+			if tc.Error {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, r)
+
+			// Formatted requirements must parse to the same requirement.
+			again, err := provisionertags.Parse(r.String())
+			require.NoError(t, err)
+			require.Equal(t, r, again)
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	daemonTags := map[string]string{
+		"scope":  "organization",
+		"owner":  "",
+		"region": "eu-west",
+		"gpu":    "true",
+	}
+	for _, tc := range []struct {
+		Name         string
+		JobTags      map[string]string
+		Requirements []string
+		Matches      bool
+	}{
+		{Name: "NoRequirements", JobTags: map[string]string{"scope": "organization"}, Matches: true},
+		{Name: "MissingTag", JobTags: map[string]string{"os": "linux"}, Matches: false},
+		{Name: "DifferentTag", JobTags: map[string]string{"region": "eu-central"}, Matches: false},
+		{Name: "Equal", Requirements: []string{"gpu=true"}, Matches: true},
+		{Name: "In", Requirements: []string{"region in [eu-central, eu-west]"}, Matches: true},
+		{Name: "NotIn", Requirements: []string{"region in [us-east, us-west]"}, Matches: false},
+		{Name: "Wildcard", Requirements: []string{"region=eu-*"}, Matches: true},
+		{Name: "WildcardMiddle", Requirements: []string{"region=e*-w*t"}, Matches: true},
+		{Name: "WildcardNoMatch", Requirements: []string{"region=us-*"}, Matches: false},
+		{Name: "NotEqual", Requirements: []string{"gpu!=true"}, Matches: false},
+		{Name: "NotEqualMissingTag", Requirements: []string{"os!=windows"}, Matches: true},
+		{Name: "EqualMissingTag", Requirements: []string{"os=*"}, Matches: false},
+		{Name: "All", JobTags: map[string]string{"scope": "organization"}, Requirements: []string{"gpu=true", "region=us-*"}, Matches: false},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			requirements, err := provisionertags.ParseAll(tc.Requirements)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Matches, provisionertags.Matches(daemonTags, tc.JobTags, requirements))
+		})
+	}
+}
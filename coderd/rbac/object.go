@@ -214,6 +214,22 @@ var (
 	ResourceOAuth2ProviderAppSecret = Object{
 		Type: "oauth2_app_secrets",
 	}
+
+	// ResourceOAuth2ProviderAppCodeToken CRUD. Codes and tokens are owned by
+	// the user that authorized the app.
+	//	create/delete = Make or delete an OAuth2 app code or token.
+	//	read = Read an OAuth2 app code or token to exchange or refresh it.
+	ResourceOAuth2ProviderAppCodeToken = Object{
+		Type: "oauth2_app_code_token",
+	}
+
+	// ResourceWebhook CRUD. Deliveries are included with their webhook.
+	//	create = Register a webhook or queue a delivery.
+	//	read = Read webhooks and their deliveries.
+	//	delete = Remove a webhook.
+	ResourceWebhook = Object{
+		Type: "webhook",
+	}
 )
 
 // ResourceUserObject is a helper function to create a user object for authz checks.
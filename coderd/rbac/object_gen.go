@@ -12,6 +12,7 @@ func AllResources() []Object {
 		ResourceGroup,
 		ResourceLicense,
 		ResourceOAuth2ProviderApp,
+		ResourceOAuth2ProviderAppCodeToken,
 		ResourceOAuth2ProviderAppSecret,
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
@@ -25,6 +26,7 @@ func AllResources() []Object {
 		ResourceTemplateInsights,
 		ResourceUser,
 		ResourceUserData,
+		ResourceWebhook,
 		ResourceWildcard,
 		ResourceWorkspace,
 		ResourceWorkspaceApplicationConnect,
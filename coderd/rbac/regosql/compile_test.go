@@ -242,6 +242,33 @@ neq(input.object.owner, "");
 				p("false")),
 			VariableConverter: regosql.TemplateConverter(),
 		},
+		{
+			Name:    "TemplatePrivateACL",
+			Queries: []string{`"read" in input.object.acl_user_list.me`},
+			ExpectedSQL: p("(CASE WHEN visibility = 'private' THEN (SELECT coalesce(jsonb_object_agg(key, value), '{}' :: jsonb) FROM jsonb_each(user_acl) WHERE value ? '*') ELSE user_acl END)" +
+				"->'me' ? 'read'"),
+			VariableConverter: regosql.TemplateConverter(),
+		},
+		{
+			Name:    "TemplateUnlistedACL",
+			Queries: []string{`"read" in input.object.acl_group_list[input.object.org_owner]`},
+			ExpectedSQL: p("(CASE WHEN visibility != 'public' THEN (SELECT coalesce(jsonb_object_agg(key, value), '{}' :: jsonb) FROM jsonb_each(group_acl) WHERE value ? '*') ELSE group_acl END)" +
+				"->organization_id :: text ? 'read'"),
+			VariableConverter: regosql.TemplateListConverter(),
+		},
+		{
+			Name: "WorkspaceUserACL",
+			Queries: []string{
+				`input.object.org_owner != "";
+				input.object.org_owner in {"05f58202-4bfc-43ce-9ba4-5ff6e0174a71"};
+				"read" in input.object.acl_group_list[input.object.org_owner]`,
+
+				`"read" in input.object.acl_user_list.me`,
+			},
+			ExpectedSQL: p(p("(organization_id :: text != '') AND (organization_id :: text = ANY(ARRAY ['05f58202-4bfc-43ce-9ba4-5ff6e0174a71'])) AND (false)") + " OR " +
+				p("user_acl->'me' ? 'read'")),
+			VariableConverter: regosql.WorkspaceConverter(),
+		},
 		{
 			Name: "UserNoOrgOwner",
 			Queries: []string{
@@ -1,6 +1,10 @@
 package regosql
 
-import "github.com/coder/coder/v2/coderd/rbac/regosql/sqltypes"
+import (
+	"fmt"
+
+	"github.com/coder/coder/v2/coderd/rbac/regosql/sqltypes"
+)
 
 func resourceIDMatcher() sqltypes.VariableMatcher {
 	return sqltypes.StringVarMatcher("id :: text", []string{"input", "object", "id"})
@@ -22,15 +26,51 @@ func userACLMatcher(m sqltypes.VariableMatcher) sqltypes.VariableMatcher {
 	return ACLGroupMatcher(m, "user_acl", []string{"input", "object", "acl_user_list"})
 }
 
-func TemplateConverter() *sqltypes.VariableConverter {
+// templateACLMatchers matches the ACL columns of templates. Rows matching the
+// hidden SQL condition only keep the ACL entries that grant every action, so
+// only template admins can see them.
+func templateACLMatchers(m sqltypes.VariableMatcher, hidden string) []sqltypes.VariableMatcher {
+	acl := func(column string) string {
+		return fmt.Sprintf("(CASE WHEN %s THEN (SELECT coalesce(jsonb_object_agg(key, value), '{}' :: jsonb) FROM jsonb_each(%s) WHERE value ? '*') ELSE %s END)", hidden, column, column)
+	}
+	return []sqltypes.VariableMatcher{
+		ACLGroupMatcher(m, acl("group_acl"), []string{"input", "object", "acl_group_list"}),
+		ACLGroupMatcher(m, acl("user_acl"), []string{"input", "object", "acl_user_list"}),
+	}
+}
+
+func templateConverter(hidden string) *sqltypes.VariableConverter {
 	matcher := sqltypes.NewVariableConverter().RegisterMatcher(
 		resourceIDMatcher(),
 		organizationOwnerMatcher(),
 		// Templates have no user owner, only owner by an organization.
 		sqltypes.AlwaysFalse(userOwnerMatcher()),
 	)
+	matcher.RegisterMatcher(templateACLMatchers(matcher, hidden)...)
+	return matcher
+}
+
+// TemplateConverter hides private templates from everyone but template
+// admins.
+func TemplateConverter() *sqltypes.VariableConverter {
+	return templateConverter("visibility = 'private'")
+}
+
+// TemplateListConverter is used when listing templates, and also hides
+// unlisted templates from everyone but template admins.
+func TemplateListConverter() *sqltypes.VariableConverter {
+	return templateConverter("visibility != 'public'")
+}
+
+func WorkspaceConverter() *sqltypes.VariableConverter {
+	matcher := sqltypes.NewVariableConverter().RegisterMatcher(
+		resourceIDMatcher(),
+		organizationOwnerMatcher(),
+		userOwnerMatcher(),
+	)
 	matcher.RegisterMatcher(
-		groupACLMatcher(matcher),
+		// Workspaces are only shared with users, not groups.
+		sqltypes.AlwaysFalse(groupACLMatcher(matcher)),
 		userACLMatcher(matcher),
 	)
 	return matcher
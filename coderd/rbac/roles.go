@@ -19,6 +19,10 @@ const (
 
 	orgAdmin  string = "organization-admin"
 	orgMember string = "organization-member"
+
+	// customSiteRole is a placeholder for all custom site wide roles. It is
+	// only used to check who can assign custom roles.
+	customSiteRole string = "custom-site-role"
 )
 
 func init() {
@@ -60,6 +64,12 @@ func RoleMember() string {
 	return roleName(member, "")
 }
 
+// CustomSiteRole is a placeholder name for any custom site wide role. Custom
+// roles are stored in the database, so they are not known to this package.
+func CustomSiteRole() string {
+	return roleName(customSiteRole, "")
+}
+
 func RoleOrgAdmin(organizationID uuid.UUID) string {
 	return roleName(orgAdmin, organizationID.String())
 }
@@ -300,22 +310,24 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 //	map[actor_role][assign_role]<can_assign>
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:          true,
+		auditor:        true,
+		member:         true,
+		orgAdmin:       true,
+		orgMember:      true,
+		templateAdmin:  true,
+		userAdmin:      true,
+		customSiteRole: true,
 	},
 	owner: {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:          true,
+		auditor:        true,
+		member:         true,
+		orgAdmin:       true,
+		orgMember:      true,
+		templateAdmin:  true,
+		userAdmin:      true,
+		customSiteRole: true,
 	},
 	userAdmin: {
 		member:    true,
@@ -383,7 +395,7 @@ func (roles Roles) Expand() ([]Role, error) {
 func (roles Roles) Names() []string {
 	names := make([]string, 0, len(roles))
 	for _, r := range roles {
-		return append(names, r.Name)
+		names = append(names, r.Name)
 	}
 	return names
 }
@@ -0,0 +1,83 @@
+// Package rolestore expands role names into roles, looking up custom roles in
+// the database. Built in roles never touch the database.
+package rolestore
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+)
+
+// Expand expands the role names into roles. Built in roles are resolved by
+// the rbac package, and any other name is treated as a custom role. Custom
+// roles that no longer exist in the database are omitted, so deleting a role
+// revokes it from every user it was assigned to.
+func Expand(ctx context.Context, db database.Store, names []string) (rbac.Roles, error) {
+	roles := make(rbac.Roles, 0, len(names))
+	var lookup []string
+	for _, name := range names {
+		builtIn, err := rbac.RoleByName(name)
+		if err == nil {
+			roles = append(roles, builtIn)
+			continue
+		}
+		lookup = append(lookup, name)
+	}
+	if len(lookup) == 0 {
+		return roles, nil
+	}
+
+	customRoles, err := db.CustomRolesByName(ctx, lookup)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch custom roles: %w", err)
+	}
+	for _, customRole := range customRoles {
+		role, err := ConvertDBRole(customRole)
+		if err != nil {
+			return nil, xerrors.Errorf("convert custom role %q: %w", customRole.Name, err)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// ConvertDBRole converts a custom role stored in the database into a role the
+// rbac package can evaluate.
+func ConvertDBRole(dbRole database.CustomRole) (rbac.Role, error) {
+	role := rbac.Role{
+		Name:        dbRole.Name,
+		DisplayName: dbRole.DisplayName,
+		Site:        []rbac.Permission{},
+		Org:         map[string][]rbac.Permission{},
+		User:        []rbac.Permission{},
+	}
+	if err := json.Unmarshal(dbRole.SitePermissions, &role.Site); err != nil {
+		return rbac.Role{}, xerrors.Errorf("unmarshal site permissions: %w", err)
+	}
+	return role, nil
+}
+
+// ConvertRoleToDB converts a role into the form stored in the database. Only
+// site wide permissions can be stored.
+func ConvertRoleToDB(role rbac.Role) (database.UpsertCustomRoleParams, error) {
+	if len(role.Org) > 0 || len(role.User) > 0 {
+		return database.UpsertCustomRoleParams{}, xerrors.New("custom roles can only have site wide permissions")
+	}
+	site := role.Site
+	if site == nil {
+		site = []rbac.Permission{}
+	}
+	sitePermissions, err := json.Marshal(site)
+	if err != nil {
+		return database.UpsertCustomRoleParams{}, xerrors.Errorf("marshal site permissions: %w", err)
+	}
+	return database.UpsertCustomRoleParams{
+		Name:            role.Name,
+		DisplayName:     role.DisplayName,
+		SitePermissions: sitePermissions,
+	}, nil
+}
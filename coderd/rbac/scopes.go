@@ -2,8 +2,11 @@ package rbac
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 
 	"golang.org/x/xerrors"
 )
@@ -81,6 +84,140 @@ var builtinScopes = map[ScopeName]Scope{
 	},
 }
 
+// Fine-grained scopes limit an API token to a subset of resources and actions.
+// Unlike the builtin scopes, any number of them can be combined on a token.
+const (
+	ScopeWorkspaceRead  ScopeName = "workspace:read"
+	ScopeWorkspaceWrite ScopeName = "workspace:write"
+	ScopeTemplateRead   ScopeName = "template:read"
+	ScopeTemplateWrite  ScopeName = "template:write"
+	ScopeUserRead       ScopeName = "user:read"
+)
+
+// FineGrainedScope describes a scope that can be combined with others on an
+// API token.
+type FineGrainedScope struct {
+	Name        ScopeName
+	DisplayName string
+	Permissions map[string][]Action
+}
+
+var fineGrainedScopes = []FineGrainedScope{
+	{
+		Name:        ScopeWorkspaceRead,
+		DisplayName: "Read workspaces and their builds",
+		// Workspaces are returned with their template and owner, so those
+		// must be readable too.
+		Permissions: map[string][]Action{
+			ResourceWorkspace.Type:      {ActionRead},
+			ResourceWorkspaceBuild.Type: {ActionRead},
+			ResourceTemplate.Type:       {ActionRead},
+			ResourceUser.Type:           {ActionRead},
+		},
+	},
+	{
+		Name:        ScopeWorkspaceWrite,
+		DisplayName: "Create, update, build, delete and connect to workspaces",
+		Permissions: map[string][]Action{
+			ResourceWorkspace.Type:                   {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
+			ResourceWorkspaceBuild.Type:              {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
+			ResourceWorkspaceExecution.Type:          {ActionCreate},
+			ResourceWorkspaceApplicationConnect.Type: {ActionCreate},
+			ResourceTemplate.Type:                    {ActionRead},
+			ResourceUser.Type:                        {ActionRead},
+		},
+	},
+	{
+		Name:        ScopeTemplateRead,
+		DisplayName: "Read templates and their versions",
+		Permissions: map[string][]Action{
+			ResourceTemplate.Type: {ActionRead},
+		},
+	},
+	{
+		Name:        ScopeTemplateWrite,
+		DisplayName: "Create, update and delete templates",
+		Permissions: map[string][]Action{
+			ResourceTemplate.Type: {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
+			ResourceFile.Type:     {ActionCreate, ActionRead},
+		},
+	},
+	{
+		Name:        ScopeUserRead,
+		DisplayName: "Read users",
+		Permissions: map[string][]Action{
+			ResourceUser.Type: {ActionRead},
+		},
+	},
+}
+
+// FineGrainedScopes returns all scopes that can be combined on an API token.
+func FineGrainedScopes() []FineGrainedScope {
+	return slices.Clone(fineGrainedScopes)
+}
+
+// IsFineGrainedScope returns whether the name is a fine-grained scope.
+func IsFineGrainedScope(name ScopeName) bool {
+	return slices.ContainsFunc(fineGrainedScopes, func(s FineGrainedScope) bool {
+		return s.Name == name
+	})
+}
+
+// CombinedScopeParams are the fine-grained scopes of an API token.
+type CombinedScopeParams struct {
+	Scopes []ScopeName
+	// AllowIDList limits the scope to the given resource IDs. If empty, all
+	// resources are allowed.
+	AllowIDList []string
+}
+
+// CombinedScope returns a single scope that grants the union of the
+// permissions of the given fine-grained scopes.
+func CombinedScope(params CombinedScopeParams) (Scope, error) {
+	if len(params.Scopes) == 0 {
+		return Scope{}, xerrors.New("at least one scope is required")
+	}
+
+	names := make([]string, 0, len(params.Scopes))
+	perms := make(map[string][]Action)
+	for _, name := range params.Scopes {
+		idx := slices.IndexFunc(fineGrainedScopes, func(s FineGrainedScope) bool {
+			return s.Name == name
+		})
+		if idx < 0 {
+			return Scope{}, xerrors.Errorf("no scope named %q", name)
+		}
+		names = append(names, string(name))
+		for resource, actions := range fineGrainedScopes[idx].Permissions {
+			for _, action := range actions {
+				if !slices.Contains(perms[resource], action) {
+					perms[resource] = append(perms[resource], action)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	allowList := []string{WildcardSymbol}
+	if len(params.AllowIDList) > 0 {
+		allowList = slices.Clone(params.AllowIDList)
+		sort.Strings(allowList)
+	}
+
+	return Scope{
+		Role: Role{
+			// The name must be unique for the set of permissions and the
+			// allow list, as it is used to compare subjects when caching.
+			Name:        fmt.Sprintf("Scope_%s[%s]", strings.Join(names, ","), strings.Join(allowList, ",")),
+			DisplayName: strings.Join(names, ", "),
+			Site:        Permissions(perms),
+			Org:         map[string][]Permission{},
+			User:        []Permission{},
+		},
+		AllowIDList: allowList,
+	}, nil
+}
+
 type ExpandableScope interface {
 	Expand() (Scope, error)
 	// Name is for logging and tracing purposes, we want to know the human
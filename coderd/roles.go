@@ -1,13 +1,19 @@
 package coderd
 
 import (
+	"fmt"
 	"net/http"
 
+	"golang.org/x/exp/slices"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
 
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 )
 
 // assignableSiteRoles returns all site wide roles that can be assigned.
@@ -27,8 +33,170 @@ func (api *API) assignableSiteRoles(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoles, err := api.Database.CustomRolesByName(ctx, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	roles := rbac.SiteRoles()
-	httpapi.Write(ctx, rw, http.StatusOK, assignableRoles(actorRoles.Actor.Roles, roles))
+	assignable := assignableRoles(actorRoles.Actor.Roles, roles)
+	for _, role := range customRoles {
+		assignable = append(assignable, codersdk.AssignableRoles{
+			Role: codersdk.Role{
+				Name:        role.Name,
+				DisplayName: role.DisplayName,
+			},
+			Assignable: rbac.CanAssignRole(actorRoles.Actor.Roles, rbac.CustomSiteRole()),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, assignable)
+}
+
+// @Summary Get custom site roles
+// @ID get-custom-site-roles
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Members
+// @Success 200 {array} codersdk.CustomRole
+// @Router /users/roles/custom [get]
+func (api *API) customSiteRoles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	customRoles, err := api.Database.CustomRolesByName(ctx, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching custom roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.CustomRole, 0, len(customRoles))
+	for _, role := range customRoles {
+		c, err := convertCustomRole(role)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting custom role.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		converted = append(converted, c)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// patchRole creates or updates a custom site wide role. A role cannot grant
+// any permission the caller does not have themselves.
+//
+// @Summary Upsert a custom site role
+// @ID upsert-a-custom-site-role
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Members
+// @Param request body codersdk.CustomRole true "Upsert role request"
+// @Success 200 {object} codersdk.CustomRole
+// @Router /users/roles [patch]
+func (api *API) patchRole(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.CustomRole
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if err := httpapi.NameValid(req.Name); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid role name.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if _, err := rbac.RoleByName(req.Name); err == nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("%q is a built in role and cannot be modified.", req.Name),
+		})
+		return
+	}
+
+	permissions := make([]rbac.Permission, 0, len(req.SitePermissions))
+	var validErrs []codersdk.ValidationError
+	for i, perm := range req.SitePermissions {
+		field := fmt.Sprintf("site_permissions[%d]", i)
+		if !slices.ContainsFunc(rbac.AllResources(), func(obj rbac.Object) bool {
+			return obj.Type == string(perm.ResourceType)
+		}) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  field + ".resource_type",
+				Detail: fmt.Sprintf("unknown resource type %q", perm.ResourceType),
+			})
+		}
+		action := rbac.Action(perm.Action)
+		if action != rbac.WildcardSymbol && !slices.Contains(rbac.AllActions(), action) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  field + ".action",
+				Detail: fmt.Sprintf("unknown action %q", perm.Action),
+			})
+		}
+		permissions = append(permissions, rbac.Permission{
+			Negate:       perm.Negate,
+			ResourceType: string(perm.ResourceType),
+			Action:       action,
+		})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid role permissions.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	args, err := rolestore.ConvertRoleToDB(rbac.Role{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Site:        permissions,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	inserted, err := api.Database.UpsertCustomRole(ctx, args)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You cannot create a role with permissions you do not have.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted, err := convertCustomRole(inserted)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting custom role.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
 }
 
 // assignableSiteRoles returns all org wide roles that can be assigned.
@@ -74,3 +242,23 @@ func assignableRoles(actorRoles rbac.ExpandableRoles, roles []rbac.Role) []coder
 	}
 	return assignable
 }
+
+func convertCustomRole(dbRole database.CustomRole) (codersdk.CustomRole, error) {
+	role, err := rolestore.ConvertDBRole(dbRole)
+	if err != nil {
+		return codersdk.CustomRole{}, err
+	}
+	permissions := make([]codersdk.Permission, 0, len(role.Site))
+	for _, perm := range role.Site {
+		permissions = append(permissions, codersdk.Permission{
+			Negate:       perm.Negate,
+			ResourceType: codersdk.RBACResource(perm.ResourceType),
+			Action:       string(perm.Action),
+		})
+	}
+	return codersdk.CustomRole{
+		Name:            role.Name,
+		DisplayName:     role.DisplayName,
+		SitePermissions: permissions,
+	}, nil
+}
@@ -13,6 +13,64 @@ import (
 	"github.com/coder/coder/v2/testutil"
 )
 
+func TestCustomRoles(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	userAdmin, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID, rbac.RoleUserAdmin())
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	auditViewer := codersdk.CustomRole{
+		Name:        "audit-viewer",
+		DisplayName: "Audit Viewer",
+		SitePermissions: []codersdk.Permission{{
+			ResourceType: codersdk.ResourceAuditLog,
+			Action:       codersdk.ActionRead,
+		}},
+	}
+
+	// The user admin cannot grant permissions they do not have.
+	_, err := userAdmin.PatchRole(ctx, auditViewer)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Built in roles cannot be overwritten.
+	_, err = client.PatchRole(ctx, codersdk.CustomRole{Name: rbac.RoleOwner()})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	role, err := client.PatchRole(ctx, auditViewer)
+	require.NoError(t, err)
+	require.Equal(t, auditViewer, role)
+
+	roles, err := client.ListCustomRoles(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.CustomRole{auditViewer}, roles)
+
+	siteRoles, err := client.ListSiteRoles(ctx)
+	require.NoError(t, err)
+	require.Contains(t, siteRoles, codersdk.AssignableRoles{
+		Role:       codersdk.Role{Name: auditViewer.Name, DisplayName: auditViewer.DisplayName},
+		Assignable: true,
+	})
+
+	// Members cannot read the audit log until they are given the role.
+	_, err = member.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+	require.Error(t, err)
+
+	_, err = client.UpdateUserRoles(ctx, memberUser.ID.String(), codersdk.UpdateRoles{
+		Roles: []string{auditViewer.Name},
+	})
+	require.NoError(t, err)
+
+	_, err = member.AuditLogs(ctx, codersdk.AuditLogsRequest{})
+	require.NoError(t, err)
+}
+
 func TestListRoles(t *testing.T) {
 	t.Parallel()
 
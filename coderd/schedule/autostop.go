@@ -70,7 +70,9 @@ type AutostopTime struct {
 // past this value, so it denotes the absolute deadline that the workspace build
 // must be stopped by. MaxDeadline is calculated using the template's "autostop
 // requirement" settings and the user's "quiet hours" settings to pick a time
-// outside of working hours.
+// outside of working hours. When the legacy max TTL is in use, the max deadline
+// is deferred to the start of the user's quiet hours if they have set their
+// own schedule.
 //
 // Deadline is a cost saving measure, while max deadline is a
 // compliance/updating measure.
@@ -114,6 +116,23 @@ func CalculateAutostop(ctx context.Context, params CalculateAutostopParams) (Aut
 	// configured or entitled to use the new feature flag yet.
 	if templateSchedule.UseMaxTTL && templateSchedule.MaxTTL > 0 {
 		autostop.MaxDeadline = now.Add(templateSchedule.MaxTTL)
+
+		// If the user has configured their own quiet hours, defer the forced
+		// stop to the start of their next quiet hours window so it doesn't
+		// interrupt them during working hours. The deployment default is
+		// ignored here to preserve the existing max_ttl behavior.
+		userQuietHoursSchedule, err := params.UserQuietHoursScheduleStore.Get(ctx, db, workspace.OwnerID)
+		if err != nil {
+			return autostop, xerrors.Errorf("get user quiet hours schedule options: %w", err)
+		}
+		if userQuietHoursSchedule.Schedule != nil && userQuietHoursSchedule.UserSet {
+			// Next is not inclusive, so step back a second so a max deadline
+			// that falls exactly on the start of quiet hours is kept as-is.
+			autostop.MaxDeadline = userQuietHoursSchedule.Schedule.Next(autostop.MaxDeadline.Add(-time.Second))
+			if autostop.MaxDeadline.IsZero() {
+				return autostop, xerrors.New("could not find next occurrence of user quiet hours schedule for max ttl")
+			}
+		}
 	}
 
 	// Otherwise, use the autostop_requirement algorithm.
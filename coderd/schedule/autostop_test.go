@@ -79,6 +79,9 @@ func TestCalculateAutoStop(t *testing.T) {
 		templateMaxTTL              time.Duration
 		templateAutostopRequirement schedule.TemplateAutostopRequirement
 		userQuietHoursSchedule      string
+		// userSetQuietHours marks the quiet hours schedule as set by the user
+		// rather than inherited from the deployment default.
+		userSetQuietHours bool
 		// workspaceTTL is usually copied from the template's TTL when the
 		// workspace is made, so it takes precedence unless
 		// templateAllowAutostop is false.
@@ -401,6 +404,34 @@ func TestCalculateAutoStop(t *testing.T) {
 			// expectedDeadline is copied from expectedMaxDeadline.
 			expectedMaxDeadline: fridayEveningSydney.Add(time.Hour).In(time.UTC),
 		},
+		{
+			name:                   "MaxTTLDeferredToUserQuietHours",
+			now:                    fridayEveningSydney.In(time.UTC),
+			templateAllowAutostop:  false,
+			templateDefaultTTL:     0,
+			useMaxTTL:              true,
+			templateMaxTTL:         time.Hour,
+			userQuietHoursSchedule: sydneyQuietHours,
+			userSetQuietHours:      true,
+			workspaceTTL:           0,
+			// The max TTL expires at 11pm, so the stop is deferred until the
+			// user's quiet hours start at midnight.
+			expectedMaxDeadline: saturdayMidnightSydney.In(time.UTC),
+		},
+		{
+			name:                   "MaxTTLOnUserQuietHours",
+			now:                    fridayEveningSydney.In(time.UTC),
+			templateAllowAutostop:  false,
+			templateDefaultTTL:     0,
+			useMaxTTL:              true,
+			templateMaxTTL:         2 * time.Hour,
+			userQuietHoursSchedule: sydneyQuietHours,
+			userSetQuietHours:      true,
+			workspaceTTL:           0,
+			// The max TTL expires exactly when quiet hours start, so it
+			// shouldn't be pushed to the following day.
+			expectedMaxDeadline: saturdayMidnightSydney.In(time.UTC),
+		},
 	}
 
 	for _, c := range cases {
@@ -440,7 +471,7 @@ func TestCalculateAutoStop(t *testing.T) {
 
 					return schedule.UserQuietHoursScheduleOptions{
 						Schedule: sched,
-						UserSet:  false,
+						UserSet:  c.userSetQuietHours,
 					}, nil
 				},
 			}
@@ -232,6 +232,67 @@ func TestSearchAudit(t *testing.T) {
 	}
 }
 
+func TestSearchConnectionLogs(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		Name                  string
+		Query                 string
+		Expected              database.GetConnectionLogsOffsetParams
+		ExpectedErrorContains string
+	}{
+		{
+			Name:     "Empty",
+			Query:    "",
+			Expected: database.GetConnectionLogsOffsetParams{},
+		},
+		{
+			Name:  "DefaultType",
+			Query: "ssh",
+			Expected: database.GetConnectionLogsOffsetParams{
+				Type: "ssh",
+			},
+		},
+		{
+			Name:  "WorkspaceOwner",
+			Query: "type:port_forwarding workspace_owner:Alice",
+			Expected: database.GetConnectionLogsOffsetParams{
+				Type:           "port_forwarding",
+				WorkspaceOwner: "alice",
+			},
+		},
+		// Failures
+		{
+			Name:                  "InvalidType",
+			Query:                 "type:telnet",
+			ExpectedErrorContains: "not a valid value",
+		},
+		{
+			Name:                  "ExtraKeys",
+			Query:                 `foo:bar`,
+			ExpectedErrorContains: `"foo" is not a valid query param`,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			values, errs := searchquery.ConnectionLogs(c.Query)
+			if c.ExpectedErrorContains != "" {
+				require.True(t, len(errs) > 0, "expect some errors")
+				var s strings.Builder
+				for _, err := range errs {
+					_, _ = s.WriteString(fmt.Sprintf("%s: %s\n", err.Field, err.Detail))
+				}
+				require.Contains(t, s.String(), c.ExpectedErrorContains)
+			} else {
+				require.Len(t, errs, 0, "expected no error")
+				require.Equal(t, c.Expected, values, "expected values")
+			}
+		})
+	}
+}
+
 func TestSearchUsers(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
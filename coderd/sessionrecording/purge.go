@@ -0,0 +1,104 @@
+package sessionrecording
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	purgeDelay = 10 * time.Minute
+	// purgeBatchSize is how many recordings are deleted at a time.
+	purgeBatchSize = 100
+)
+
+// NewPurger periodically deletes the recordings that are past the retention
+// of their template. It is the caller's responsibility to call Close on the
+// returned instance.
+func NewPurger(ctx context.Context, logger slog.Logger, db database.Store, store Store) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system purges recordings without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(purgeDelay)
+
+		err := Purge(ctx, logger, db, store, dbtime.Now())
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error(ctx, "failed to purge expired session recordings", slog.Error(err))
+		}
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ticker.Stop()
+				doTick()
+			}
+		}
+	}()
+	return &purger{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type purger struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (p *purger) Close() error {
+	p.cancel()
+	<-p.closed
+	return nil
+}
+
+// Purge deletes the recordings that expired before now, from the store first
+// so a failure never leaves an object without its row.
+func Purge(ctx context.Context, logger slog.Logger, db database.Store, store Store, now time.Time) error {
+	for {
+		recordings, err := db.GetExpiredSessionRecordings(ctx, database.GetExpiredSessionRecordingsParams{
+			Now:      now,
+			LimitOpt: purgeBatchSize,
+		})
+		if err != nil {
+			return xerrors.Errorf("get expired recordings: %w", err)
+		}
+		for _, recording := range recordings {
+			err = store.Delete(ctx, recording.ObjectKey)
+			if err != nil {
+				return xerrors.Errorf("delete recording %s from store: %w", recording.ID, err)
+			}
+			err = db.DeleteSessionRecordingByID(ctx, recording.ID)
+			if err != nil {
+				return xerrors.Errorf("delete recording %s: %w", recording.ID, err)
+			}
+		}
+		if len(recordings) > 0 {
+			logger.Debug(ctx, "purged expired session recordings", slog.F("recordings", len(recordings)))
+		}
+		if len(recordings) < purgeBatchSize {
+			return nil
+		}
+	}
+}
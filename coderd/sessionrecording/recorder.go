@@ -0,0 +1,119 @@
+package sessionrecording
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxRecordingSize bounds the size of a recording. Output written after a
+// recording reaches it is not recorded.
+const MaxRecordingSize = 64 << 20
+
+// ContentType is the media type of recordings.
+const ContentType = "application/x-asciicast"
+
+// header is the first line of an asciinema v2 recording.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type header struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env"`
+}
+
+// Recorder records the output of a terminal as asciinema v2 output events.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	now       func() time.Time
+	buf       bytes.Buffer
+	pending   []byte
+	truncated bool
+}
+
+// NewRecorder starts a recording of a terminal of the given size.
+func NewRecorder(start time.Time, width, height uint16) *Recorder {
+	return newRecorder(start, width, height, time.Now)
+}
+
+func newRecorder(start time.Time, width, height uint16, now func() time.Time) *Recorder {
+	r := &Recorder{
+		start: start,
+		now:   now,
+	}
+	// Marshaling the header can't fail.
+	data, _ := json.Marshal(header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Env: map[string]string{
+			"TERM": "xterm-256color",
+		},
+	})
+	_, _ = r.buf.Write(data)
+	_ = r.buf.WriteByte('\n')
+	return r
+}
+
+// Write records p as an output event. It never fails, so the recording can't
+// interrupt the session.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.truncated {
+		return len(p), nil
+	}
+
+	// Events are JSON strings, so a UTF-8 sequence split across writes is
+	// held back until it is complete.
+	data := append(bytes.Clone(r.pending), p...)
+	r.pending = nil
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(data); i++ {
+		if !utf8.RuneStart(data[len(data)-i]) {
+			continue
+		}
+		if !utf8.FullRune(data[len(data)-i:]) {
+			r.pending = data[len(data)-i:]
+			data = data[:len(data)-i]
+		}
+		break
+	}
+	if len(data) == 0 {
+		return len(p), nil
+	}
+
+	text, _ := json.Marshal(string(data))
+	elapsed := r.now().Sub(r.start).Seconds()
+	if r.buf.Len()+len(text)+32 > MaxRecordingSize {
+		r.truncated = true
+		return len(p), nil
+	}
+	_ = r.buf.WriteByte('[')
+	_, _ = r.buf.WriteString(strconv.FormatFloat(elapsed, 'f', 6, 64))
+	_, _ = r.buf.WriteString(`, "o", `)
+	_, _ = r.buf.Write(text)
+	_, _ = r.buf.WriteString("]\n")
+	return len(p), nil
+}
+
+// Truncated returns whether output was dropped because the recording reached
+// MaxRecordingSize.
+func (r *Recorder) Truncated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.truncated
+}
+
+// Bytes returns the recording so far.
+func (r *Recorder) Bytes() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return bytes.Clone(r.buf.Bytes())
+}
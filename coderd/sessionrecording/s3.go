@@ -0,0 +1,150 @@
+package sessionrecording
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/xerrors"
+)
+
+// S3StoreOptions configures the bucket recordings are kept in.
+type S3StoreOptions struct {
+	Bucket string
+	// Region defaults to the region in the AWS config.
+	Region string
+	// Prefix is prepended to every object key.
+	Prefix string
+	// Endpoint overrides the S3 endpoint for S3-compatible storage. Objects
+	// are addressed with path-style URLs when it is set.
+	Endpoint *url.URL
+	// Credentials defaults to the AWS SDK's default credential chain.
+	Credentials aws.CredentialsProvider
+	HTTPClient  *http.Client
+}
+
+type s3Store struct {
+	opts   S3StoreOptions
+	signer *v4.Signer
+}
+
+// NewS3Store returns a store that keeps recordings as objects in an S3
+// bucket.
+func NewS3Store(ctx context.Context, opts S3StoreOptions) (Store, error) {
+	if opts.Bucket == "" {
+		return nil, xerrors.New("s3 bucket must be set")
+	}
+	if opts.Credentials == nil || opts.Region == "" {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("load aws config: %w", err)
+		}
+		if opts.Credentials == nil {
+			opts.Credentials = cfg.Credentials
+		}
+		if opts.Region == "" {
+			opts.Region = cfg.Region
+		}
+	}
+	if opts.Region == "" {
+		return nil, xerrors.New("s3 region must be set")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &s3Store{
+		opts:   opts,
+		signer: v4.NewSigner(),
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	res, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return xerrors.Errorf("put object: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readError(res, key)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("get object: %w", err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		_ = res.Body.Close()
+		return nil, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, readError(res, key)
+	}
+	return res.Body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	res, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return xerrors.Errorf("delete object: %w", err)
+	}
+	defer res.Body.Close()
+	// S3 returns 204 whether or not the object existed, but S3-compatible
+	// stores may return 404.
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return readError(res, key)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	payloadHash := sha256.Sum256(body)
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(s.opts.Prefix+key).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", "application/x-asciicast")
+	}
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	creds, err := s.opts.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "s3", s.opts.Region, time.Now().UTC())
+	if err != nil {
+		return nil, xerrors.Errorf("sign request: %w", err)
+	}
+	return s.opts.HTTPClient.Do(req)
+}
+
+func (s *s3Store) objectURL(key string) *url.URL {
+	if s.opts.Endpoint != nil {
+		return s.opts.Endpoint.JoinPath(s.opts.Bucket, key)
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.opts.Bucket, s.opts.Region),
+		Path:   "/" + key,
+	}
+}
+
+func readError(res *http.Response, key string) error {
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return xerrors.Errorf("object %q: unexpected status code %d: %s", key, res.StatusCode, msg)
+}
@@ -0,0 +1,161 @@
+package sessionrecording_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	rec := sessionrecording.NewRecorder(start, 120, 40)
+	_, _ = rec.Write([]byte("hello "))
+	// "é" split across two writes must not be mangled.
+	_, _ = rec.Write([]byte{'w', 0xc3})
+	_, _ = rec.Write([]byte{0xa9, '\r', '\n'})
+	require.False(t, rec.Truncated())
+
+	scanner := bufio.NewScanner(bytes.NewReader(rec.Bytes()))
+	require.True(t, scanner.Scan())
+	var header struct {
+		Version   int   `json:"version"`
+		Width     int   `json:"width"`
+		Height    int   `json:"height"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	require.Equal(t, 2, header.Version)
+	require.Equal(t, 120, header.Width)
+	require.Equal(t, 40, header.Height)
+	require.Equal(t, start.Unix(), header.Timestamp)
+
+	var output string
+	var last float64
+	for scanner.Scan() {
+		var event []any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+		elapsed, ok := event[0].(float64)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, elapsed, last)
+		last = elapsed
+		require.Equal(t, "o", event[1])
+		data, ok := event[2].(string)
+		require.True(t, ok)
+		output += data
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, "hello wé\r\n", output)
+}
+
+func TestDirStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	store, err := sessionrecording.NewStore(ctx, "file://"+t.TempDir(), nil)
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, "workspace/recording.cast")
+	require.ErrorIs(t, err, sessionrecording.ErrNotFound)
+
+	err = store.Put(ctx, "workspace/recording.cast", []byte("recording"))
+	require.NoError(t, err)
+	r, err := store.Get(ctx, "workspace/recording.cast")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, "recording", string(data))
+
+	err = store.Put(ctx, "../escape.cast", []byte("recording"))
+	require.Error(t, err)
+
+	require.NoError(t, store.Delete(ctx, "workspace/recording.cast"))
+	require.NoError(t, store.Delete(ctx, "workspace/recording.cast"))
+	_, err = store.Get(ctx, "workspace/recording.cast")
+	require.ErrorIs(t, err, sessionrecording.ErrNotFound)
+}
+
+func TestNewStore(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{
+		"ftp://example.com/recordings",
+		"file://example.com/recordings",
+		"file:relative",
+		"s3:///prefix?region=us-east-1",
+	} {
+		_, err := sessionrecording.NewStore(context.Background(), rawURL, nil)
+		require.Error(t, err, rawURL)
+	}
+}
+
+// Ensures no goroutines leak.
+func TestNewPurger(t *testing.T) {
+	t.Parallel()
+	store, err := sessionrecording.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	purger := sessionrecording.NewPurger(context.Background(), slogtest.Make(t, nil), dbmem.New(), store)
+	err = purger.Close()
+	require.NoError(t, err)
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmem.New()
+	store, err := sessionrecording.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+	now := dbtime.Now()
+
+	expired := dbgen.SessionRecording(t, db, database.SessionRecording{
+		ExpiresAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true},
+	})
+	retained := dbgen.SessionRecording(t, db, database.SessionRecording{
+		ExpiresAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+	})
+	forever := dbgen.SessionRecording(t, db, database.SessionRecording{})
+	for _, recording := range []database.SessionRecording{expired, retained, forever} {
+		require.NoError(t, store.Put(ctx, recording.ObjectKey, []byte("recording")))
+	}
+
+	err = sessionrecording.Purge(ctx, slogtest.Make(t, nil), db, store, now)
+	require.NoError(t, err)
+
+	_, err = db.GetSessionRecordingByID(ctx, expired.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = store.Get(ctx, expired.ObjectKey)
+	require.ErrorIs(t, err, sessionrecording.ErrNotFound)
+
+	for _, recording := range []database.SessionRecording{retained, forever} {
+		_, err = db.GetSessionRecordingByID(ctx, recording.ID)
+		require.NoError(t, err)
+		r, err := store.Get(ctx, recording.ObjectKey)
+		require.NoError(t, err)
+		_ = r.Close()
+	}
+}
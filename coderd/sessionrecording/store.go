@@ -0,0 +1,142 @@
+// Package sessionrecording records web terminal sessions in the asciinema v2
+// format and keeps the recordings in a Store.
+package sessionrecording
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrNotFound is returned by Store.Get when there is no recording with the
+// key.
+var ErrNotFound = xerrors.New("recording not found")
+
+// Store keeps session recordings outside of the database. Keys are slash
+// separated, e.g. "<workspace id>/<recording id>.cast".
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the recording. Deleting a missing recording is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore returns the store for the URL, either a directory
+// ("file:///var/lib/coder/recordings") or an S3 bucket
+// ("s3://bucket/prefix?region=us-east-1"). S3 buckets accept an "endpoint"
+// query parameter for S3-compatible storage.
+func NewStore(ctx context.Context, rawURL string, httpClient *http.Client) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, xerrors.Errorf("file urls must not have a host, got %q", u.Host)
+		}
+		return NewDirStore(u.Path)
+	case "s3":
+		opts := S3StoreOptions{
+			Bucket:     u.Host,
+			Region:     u.Query().Get("region"),
+			Prefix:     strings.TrimPrefix(u.Path, "/"),
+			HTTPClient: httpClient,
+		}
+		if opts.Prefix != "" && !strings.HasSuffix(opts.Prefix, "/") {
+			opts.Prefix += "/"
+		}
+		if endpoint := u.Query().Get("endpoint"); endpoint != "" {
+			opts.Endpoint, err = url.Parse(endpoint)
+			if err != nil {
+				return nil, xerrors.Errorf("parse endpoint: %w", err)
+			}
+		}
+		return NewS3Store(ctx, opts)
+	default:
+		return nil, xerrors.Errorf("unsupported scheme %q, must be file or s3", u.Scheme)
+	}
+}
+
+type dirStore struct {
+	dir string
+}
+
+// NewDirStore returns a store that keeps recordings as files in the directory.
+// The directory is created if it doesn't exist.
+func NewDirStore(dir string) (Store, error) {
+	if !filepath.IsAbs(dir) {
+		return nil, xerrors.Errorf("directory %q must be absolute", dir)
+	}
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create directory: %w", err)
+	}
+	return &dirStore{dir: dir}, nil
+}
+
+func (s *dirStore) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return "", xerrors.Errorf("invalid key %q", key)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+func (s *dirStore) Put(_ context.Context, key string, data []byte) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0o700)
+	if err != nil {
+		return xerrors.Errorf("create directory: %w", err)
+	}
+	// Write to a temporary file first so a partial recording is never read.
+	tmp := name + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return xerrors.Errorf("write recording: %w", err)
+	}
+	err = os.Rename(tmp, name)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return xerrors.Errorf("rename recording: %w", err)
+	}
+	return nil
+}
+
+func (s *dirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open recording: %w", err)
+	}
+	return f, nil
+}
+
+func (s *dirStore) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("remove recording: %w", err)
+	}
+	return nil
+}
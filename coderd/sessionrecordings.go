@@ -0,0 +1,232 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get session recordings of workspace
+// @ID get-session-recordings-of-workspace
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Audit
+// @Param workspace_id query string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.SessionRecording
+// @Router /sessionrecordings [get]
+func (api *API) sessionRecordings(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	values := r.URL.Query()
+	parser := httpapi.NewQueryParamParser()
+	workspaceID := parser.Required("workspace_id").UUID(values, uuid.Nil, "workspace_id")
+	parser.ErrorExcessParams(values)
+	if len(parser.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return
+	}
+
+	recordings, err := api.Database.GetSessionRecordingsByWorkspaceID(ctx, workspaceID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	resp := make([]codersdk.SessionRecording, 0, len(recordings))
+	for _, recording := range recordings {
+		resp = append(resp, convertSessionRecording(recording))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get session recording
+// @ID get-session-recording
+// @Security CoderSessionToken
+// @Tags Audit
+// @Param sessionrecording path string true "Session recording ID" format(uuid)
+// @Success 200
+// @Router /sessionrecordings/{sessionrecording} [get]
+func (api *API) sessionRecording(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := httpmw.ParseUUIDParam(rw, r, "sessionrecording")
+	if !ok {
+		return
+	}
+	recording, err := api.Database.GetSessionRecordingByID(ctx, id)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	if api.SessionRecordingStore == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Session recording storage is not configured.",
+		})
+		return
+	}
+	cast, err := api.SessionRecordingStore.Get(ctx, recording.ObjectKey)
+	if errors.Is(err, sessionrecording.ErrNotFound) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The session recording is missing from storage.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	defer cast.Close()
+
+	rw.Header().Set("Content-Type", sessionrecording.ContentType)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.ID.String()+".cast"))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(rw, cast)
+}
+
+// terminalRecorder records the web terminals opened through the workspace app
+// server of coderd, for templates with session recording enabled.
+type terminalRecorder struct {
+	api *API
+}
+
+var _ workspaceapps.TerminalRecorder = terminalRecorder{}
+
+func (t terminalRecorder) RecordTerminal(r *http.Request, token workspaceapps.SignedToken, width, height uint16) (io.WriteCloser, error) {
+	// nolint:gocritic // The request was already authorized by the token.
+	sysCtx := dbauthz.AsSystemRestricted(r.Context())
+	workspace, err := t.api.Database.GetWorkspaceByID(sysCtx, token.WorkspaceID)
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace: %w", err)
+	}
+	template, err := t.api.Database.GetTemplateByID(sysCtx, workspace.TemplateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get template: %w", err)
+	}
+	if !template.SessionRecordingEnabled {
+		return nil, nil //nolint:nilnil
+	}
+	if t.api.SessionRecordingStore == nil {
+		// The template requires the session to be recorded, so it is refused
+		// rather than left unrecorded.
+		return nil, xerrors.New("session recording is enabled for the template, but session recording storage is not configured")
+	}
+
+	startedAt := dbtime.Now()
+	id := uuid.New()
+	return &terminalRecording{
+		api:       t.api,
+		recorder:  sessionrecording.NewRecorder(startedAt, width, height),
+		retention: time.Duration(template.SessionRecordingRetention),
+		params: database.InsertSessionRecordingParams{
+			ID:             id,
+			OrganizationID: workspace.OrganizationID,
+			TemplateID:     template.ID,
+			WorkspaceID:    workspace.ID,
+			AgentID:        token.AgentID,
+			UserID:         token.UserID,
+			StartedAt:      startedAt,
+			ObjectKey:      path.Join(workspace.ID.String(), id.String()+".cast"),
+		},
+	}, nil
+}
+
+// terminalRecording stores the recording of a session once it ends.
+type terminalRecording struct {
+	api       *API
+	recorder  *sessionrecording.Recorder
+	retention time.Duration
+	params    database.InsertSessionRecordingParams
+}
+
+func (t *terminalRecording) Write(p []byte) (int, error) {
+	return t.recorder.Write(p)
+}
+
+func (t *terminalRecording) Close() error {
+	// The session has ended, so the request context is already canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	logger := t.api.Logger.With(
+		slog.F("session_recording_id", t.params.ID),
+		slog.F("workspace_id", t.params.WorkspaceID),
+		slog.F("agent_id", t.params.AgentID),
+	)
+
+	if t.recorder.Truncated() {
+		logger.Warn(ctx, "session recording reached the maximum size and was truncated",
+			slog.F("max_size", sessionrecording.MaxRecordingSize))
+	}
+	data := t.recorder.Bytes()
+	err := t.api.SessionRecordingStore.Put(ctx, t.params.ObjectKey, data)
+	if err != nil {
+		logger.Error(ctx, "store session recording", slog.Error(err))
+		return xerrors.Errorf("store recording: %w", err)
+	}
+
+	t.params.EndedAt = dbtime.Now()
+	t.params.SizeBytes = int64(len(data))
+	if t.retention > 0 {
+		t.params.ExpiresAt = sql.NullTime{Time: t.params.EndedAt.Add(t.retention), Valid: true}
+	}
+	// nolint:gocritic // Session recordings are recorded by the system on
+	//                 // behalf of the user that opened the session.
+	_, err = t.api.Database.InsertSessionRecording(dbauthz.AsSystemRestricted(ctx), t.params)
+	if err != nil {
+		logger.Error(ctx, "insert session recording", slog.Error(err))
+		return xerrors.Errorf("insert recording: %w", err)
+	}
+	return nil
+}
+
+func convertSessionRecording(recording database.SessionRecording) codersdk.SessionRecording {
+	sdk := codersdk.SessionRecording{
+		ID:             recording.ID,
+		OrganizationID: recording.OrganizationID,
+		TemplateID:     recording.TemplateID,
+		WorkspaceID:    recording.WorkspaceID,
+		AgentID:        recording.AgentID,
+		UserID:         recording.UserID,
+		StartedAt:      recording.StartedAt,
+		EndedAt:        recording.EndedAt,
+		SizeBytes:      recording.SizeBytes,
+	}
+	if recording.ExpiresAt.Valid {
+		expiresAt := recording.ExpiresAt.Time
+		sdk.ExpiresAt = &expiresAt
+	}
+	return sdk
+}
@@ -0,0 +1,141 @@
+package coderd_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/coderd/sessionrecording"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSessionRecordings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Recorded", func(t *testing.T) {
+		t.Parallel()
+
+		store, err := sessionrecording.NewDirStore(t.TempDir())
+		require.NoError(t, err)
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			SessionRecordingStore: store,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+		agentID := resources[0].Agents[0].ID
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err = client.UpdateTemplateMeta(ctx, r.Workspace.TemplateID, codersdk.UpdateTemplateMeta{
+			SessionRecording: &codersdk.TemplateSessionRecording{
+				Enabled:         true,
+				RetentionMillis: time.Hour.Milliseconds(),
+			},
+		})
+		require.NoError(t, err)
+
+		conn, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   agentID,
+			Reconnect: uuid.New(),
+			Width:     80,
+			Height:    24,
+			Command:   "echo recorded-output",
+		})
+		require.NoError(t, err)
+		var output strings.Builder
+		buf := make([]byte, 1024)
+		for !strings.Contains(output.String(), "recorded-output") {
+			n, err := conn.Read(buf)
+			require.NoError(t, err)
+			_, _ = output.Write(buf[:n])
+		}
+		require.NoError(t, conn.Close())
+
+		var recordings []codersdk.SessionRecording
+		require.Eventually(t, func() bool {
+			recordings, err = client.SessionRecordings(ctx, r.Workspace.ID)
+			return err == nil && len(recordings) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		recording := recordings[0]
+		require.Equal(t, r.Workspace.ID, recording.WorkspaceID)
+		require.Equal(t, r.Workspace.TemplateID, recording.TemplateID)
+		require.Equal(t, agentID, recording.AgentID)
+		require.Equal(t, user.UserID, recording.UserID)
+		require.NotNil(t, recording.ExpiresAt)
+		require.WithinDuration(t, recording.EndedAt.Add(time.Hour), *recording.ExpiresAt, time.Second)
+
+		cast, err := client.SessionRecording(ctx, recording.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, len(cast), recording.SizeBytes)
+		require.Contains(t, string(cast), `"version":2`)
+		require.Contains(t, string(cast), "recorded-output")
+
+		// Only users that can read audit logs can read session recordings.
+		_, err = member.SessionRecordings(ctx, r.Workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		_, err = member.SessionRecording(ctx, recording.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("NoStorage", func(t *testing.T) {
+		t.Parallel()
+
+		// Refusing the session logs an error.
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{
+			Logger: &logger,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+		_ = agenttest.New(t, client.URL, r.AgentToken)
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, r.Workspace.TemplateID, codersdk.UpdateTemplateMeta{
+			SessionRecording: &codersdk.TemplateSessionRecording{
+				Enabled: true,
+			},
+		})
+		require.NoError(t, err)
+
+		// The session must be recorded, so it is refused.
+		conn, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   resources[0].Agents[0].ID,
+			Reconnect: uuid.New(),
+			Width:     80,
+			Height:    24,
+			Command:   "echo unrecorded-output",
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Read(make([]byte, 1024))
+		require.ErrorContains(t, err, "record session")
+
+		recordings, err := client.SessionRecordings(ctx, r.Workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, recordings)
+	})
+}
@@ -0,0 +1,198 @@
+// Package templatediff computes the differences between two template
+// versions, so a template update can be reviewed before workspaces are
+// rebuilt.
+package templatediff
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/diff"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// MaxDiffFileSize is the largest file a textual diff is computed for. Larger
+// files are still reported as changed, but without a diff.
+const MaxDiffFileSize = 1 << 20
+
+// ExtractFiles returns the contents of the regular files in a tar archive,
+// keyed by their cleaned path.
+func ExtractFiles(r io.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read tar header: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", header.Name, err)
+		}
+		files[name] = data
+	}
+}
+
+// Files compares the files of two template versions. Unchanged files are
+// omitted, and the result is sorted by path.
+func Files(base, target map[string][]byte) ([]codersdk.TemplateVersionFileDiff, error) {
+	diffs := make([]codersdk.TemplateVersionFileDiff, 0)
+	for name, data := range target {
+		baseData, ok := base[name]
+		switch {
+		case !ok:
+			d, err := fileDiff(name, codersdk.TemplateVersionFileAdded, nil, data)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, d)
+		case !bytes.Equal(baseData, data):
+			d, err := fileDiff(name, codersdk.TemplateVersionFileModified, baseData, data)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, d)
+		}
+	}
+	for name, data := range base {
+		if _, ok := target[name]; ok {
+			continue
+		}
+		d, err := fileDiff(name, codersdk.TemplateVersionFileRemoved, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, d)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs, nil
+}
+
+func fileDiff(name string, status codersdk.TemplateVersionFileStatus, base, target []byte) (codersdk.TemplateVersionFileDiff, error) {
+	d := codersdk.TemplateVersionFileDiff{
+		Path:   name,
+		Status: status,
+		Binary: isBinary(base) || isBinary(target),
+	}
+	if d.Binary || len(base) > MaxDiffFileSize || len(target) > MaxDiffFileSize {
+		return d, nil
+	}
+	var buf bytes.Buffer
+	err := diff.Text("a/"+name, "b/"+name, base, target, &buf)
+	if err != nil {
+		return codersdk.TemplateVersionFileDiff{}, xerrors.Errorf("diff %q: %w", name, err)
+	}
+	d.Diff = buf.String()
+	return d, nil
+}
+
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) != -1 || !utf8.Valid(data)
+}
+
+// Parameters compares the rich parameters of two template versions by name.
+// Each result is sorted by parameter name.
+func Parameters(base, target []codersdk.TemplateVersionParameter) (added, removed []codersdk.TemplateVersionParameter, changed []codersdk.TemplateVersionParameterChange) {
+	added = make([]codersdk.TemplateVersionParameter, 0)
+	removed = make([]codersdk.TemplateVersionParameter, 0)
+	changed = make([]codersdk.TemplateVersionParameterChange, 0)
+
+	baseByName := make(map[string]codersdk.TemplateVersionParameter, len(base))
+	for _, param := range base {
+		baseByName[param.Name] = param
+	}
+	targetByName := make(map[string]codersdk.TemplateVersionParameter, len(target))
+	for _, param := range target {
+		targetByName[param.Name] = param
+		baseParam, ok := baseByName[param.Name]
+		if !ok {
+			added = append(added, param)
+			continue
+		}
+		if !reflect.DeepEqual(baseParam, param) {
+			changed = append(changed, codersdk.TemplateVersionParameterChange{
+				Name: param.Name,
+				Old:  baseParam,
+				New:  param,
+			})
+		}
+	}
+	for _, param := range base {
+		if _, ok := targetByName[param.Name]; !ok {
+			removed = append(removed, param)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].Name < added[j].Name })
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Name < removed[j].Name })
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	return added, removed, changed
+}
+
+// Metadata is the provisioner metadata of a template version that is compared
+// between versions.
+type Metadata struct {
+	Provisioner           string
+	Tags                  map[string]string
+	ExternalAuthProviders []string
+	Readme                string
+}
+
+// MetadataChanges returns the metadata fields that differ between two template
+// versions. Tags are compared individually as "tags.<key>".
+func MetadataChanges(base, target Metadata) []codersdk.TemplateVersionMetadataChange {
+	changes := make([]codersdk.TemplateVersionMetadataChange, 0)
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, codersdk.TemplateVersionMetadataChange{
+				Field: field,
+				Old:   before,
+				New:   after,
+			})
+		}
+	}
+
+	add("provisioner", base.Provisioner, target.Provisioner)
+
+	keys := make([]string, 0, len(base.Tags)+len(target.Tags))
+	for key := range base.Tags {
+		keys = append(keys, key)
+	}
+	for key := range target.Tags {
+		if _, ok := base.Tags[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add("tags."+key, base.Tags[key], target.Tags[key])
+	}
+
+	add("external_auth_providers", joinSorted(base.ExternalAuthProviders), joinSorted(target.ExternalAuthProviders))
+	add("readme", base.Readme, target.Readme)
+	return changes
+}
+
+func joinSorted(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
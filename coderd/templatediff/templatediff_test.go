@@ -0,0 +1,116 @@
+package templatediff_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/templatediff"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestExtractFiles(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "modules", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for name, content := range map[string]string{
+		"./main.tf":        "resource {}",
+		"modules/../a.txt": "a",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	files, err := templatediff.ExtractFiles(&buf)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"main.tf": []byte("resource {}"),
+		"a.txt":   []byte("a"),
+	}, files)
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	diffs, err := templatediff.Files(map[string][]byte{
+		"main.tf":    []byte("a\nb\n"),
+		"README.md":  []byte("readme\n"),
+		"removed.tf": []byte("gone\n"),
+		"image.png":  {0x89, 0x50, 0x00},
+	}, map[string][]byte{
+		"main.tf":   []byte("a\nc\n"),
+		"README.md": []byte("readme\n"),
+		"added.tf":  []byte("new\n"),
+		"image.png": {0x89, 0x51, 0x00},
+	})
+	require.NoError(t, err)
+	require.Len(t, diffs, 4)
+
+	require.Equal(t, "added.tf", diffs[0].Path)
+	require.Equal(t, codersdk.TemplateVersionFileAdded, diffs[0].Status)
+	require.Contains(t, diffs[0].Diff, "+new")
+
+	require.Equal(t, "image.png", diffs[1].Path)
+	require.Equal(t, codersdk.TemplateVersionFileModified, diffs[1].Status)
+	require.True(t, diffs[1].Binary)
+	require.Empty(t, diffs[1].Diff)
+
+	require.Equal(t, "main.tf", diffs[2].Path)
+	require.Equal(t, codersdk.TemplateVersionFileModified, diffs[2].Status)
+	require.Contains(t, diffs[2].Diff, "--- a/main.tf")
+	require.Contains(t, diffs[2].Diff, "+++ b/main.tf")
+	require.Contains(t, diffs[2].Diff, "-b")
+	require.Contains(t, diffs[2].Diff, "+c")
+
+	require.Equal(t, "removed.tf", diffs[3].Path)
+	require.Equal(t, codersdk.TemplateVersionFileRemoved, diffs[3].Status)
+	require.Contains(t, diffs[3].Diff, "-gone")
+}
+
+func TestParameters(t *testing.T) {
+	t.Parallel()
+
+	added, removed, changed := templatediff.Parameters([]codersdk.TemplateVersionParameter{
+		{Name: "region", DefaultValue: "us"},
+		{Name: "legacy"},
+		{Name: "same", Description: "unchanged"},
+	}, []codersdk.TemplateVersionParameter{
+		{Name: "same", Description: "unchanged"},
+		{Name: "size"},
+		{Name: "region", DefaultValue: "eu"},
+	})
+	require.Len(t, added, 1)
+	require.Equal(t, "size", added[0].Name)
+	require.Len(t, removed, 1)
+	require.Equal(t, "legacy", removed[0].Name)
+	require.Len(t, changed, 1)
+	require.Equal(t, "region", changed[0].Name)
+	require.Equal(t, "us", changed[0].Old.DefaultValue)
+	require.Equal(t, "eu", changed[0].New.DefaultValue)
+}
+
+func TestMetadataChanges(t *testing.T) {
+	t.Parallel()
+
+	changes := templatediff.MetadataChanges(templatediff.Metadata{
+		Provisioner:           "terraform",
+		Tags:                  map[string]string{"scope": "organization", "owner": ""},
+		ExternalAuthProviders: []string{"github", "gitlab"},
+		Readme:                "old",
+	}, templatediff.Metadata{
+		Provisioner:           "terraform",
+		Tags:                  map[string]string{"scope": "organization", "region": "eu"},
+		ExternalAuthProviders: []string{"gitlab", "github"},
+		Readme:                "new",
+	})
+	require.Equal(t, []codersdk.TemplateVersionMetadataChange{
+		{Field: "tags.region", Old: "", New: "eu"},
+		{Field: "readme", Old: "old", New: "new"},
+	}, changes)
+}
@@ -1,15 +1,18 @@
 package coderd
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/audit"
@@ -20,12 +23,17 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 )
 
+// maxBuildRetryAttempts bounds the build retry policy of templates, so builds
+// that keep failing are given up on eventually.
+const maxBuildRetryAttempts = 10
+
 // Returns a single template.
 //
 // @Summary Get template metadata by ID
@@ -302,6 +310,13 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 	if dormantAutoDeletionTTL < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodeletion_ms", Detail: "Must be a positive integer."})
 	}
+	visibility := database.TemplateVisibilityPublic
+	if createTemplate.Visibility != "" {
+		visibility = database.TemplateVisibility(createTemplate.Visibility)
+		if !visibility.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "visibility", Detail: fmt.Sprintf("Visibility %q is not one of %v.", createTemplate.Visibility, database.AllTemplateVisibilityValues())})
+		}
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -344,6 +359,7 @@ func (api *API) postTemplateByOrganization(rw http.ResponseWriter, r *http.Reque
 			DisplayName:                  createTemplate.DisplayName,
 			Icon:                         createTemplate.Icon,
 			AllowUserCancelWorkspaceJobs: allowUserCancelWorkspaceJobs,
+			Visibility:                   visibility,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template: %s", err)
@@ -615,6 +631,30 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.DeprecationMessage != nil {
 		deprecationMessage = *req.DeprecationMessage
 	}
+	maintenanceSchedule := template.MaintenanceWindowSchedule
+	maintenanceDuration := time.Duration(template.MaintenanceWindowDuration)
+	if req.MaintenanceWindow != nil {
+		maintenanceSchedule = req.MaintenanceWindow.Schedule
+		maintenanceDuration = time.Duration(req.MaintenanceWindow.DurationMillis) * time.Millisecond
+		if maintenanceSchedule != "" {
+			_, err = cron.Weekly(maintenanceSchedule)
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "maintenance_window.schedule", Detail: err.Error()})
+			}
+			if maintenanceDuration < time.Minute {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "maintenance_window.duration_ms", Detail: "Value must be at least one minute."})
+			}
+		} else {
+			maintenanceDuration = 0
+		}
+	}
+	visibility := template.Visibility
+	if req.Visibility != nil {
+		visibility = database.TemplateVisibility(*req.Visibility)
+		if !visibility.Valid() {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "visibility", Detail: fmt.Sprintf("Visibility %q is not one of %v.", *req.Visibility, database.AllTemplateVisibilityValues())})
+		}
+	}
 
 	// The minimum valid value for a dormant TTL is 1 minute. This is
 	// to ensure an uninformed user does not send an unintentionally
@@ -629,6 +669,71 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.TimeTilDormantAutoDeleteMillis < 0 || (req.TimeTilDormantAutoDeleteMillis > 0 && req.TimeTilDormantAutoDeleteMillis < minTTL) {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "time_til_dormant_autodelete_ms", Detail: "Value must be at least one minute."})
 	}
+	maxBuildDuration := time.Duration(template.MaxBuildDuration)
+	if req.MaxBuildDurationMillis != nil {
+		if *req.MaxBuildDurationMillis < 0 || (*req.MaxBuildDurationMillis > 0 && *req.MaxBuildDurationMillis < minTTL) {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_build_duration_ms", Detail: "Value must be at least one minute."})
+		}
+		maxBuildDuration = time.Duration(*req.MaxBuildDurationMillis) * time.Millisecond
+	}
+	workspaceNamePattern := template.WorkspaceNamePattern
+	workspaceNameDescription := template.WorkspaceNameDescription
+	if req.WorkspaceNamePolicy != nil {
+		workspaceNamePattern = req.WorkspaceNamePolicy.Pattern
+		workspaceNameDescription = req.WorkspaceNamePolicy.Description
+		if workspaceNamePattern != "" {
+			_, err = regexp.Compile(workspaceNamePattern)
+			if err != nil {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "workspace_name_policy.pattern", Detail: err.Error()})
+			}
+		} else {
+			workspaceNameDescription = ""
+		}
+	}
+	maxRunningWorkspaces := template.MaxRunningWorkspaces
+	if req.MaxRunningWorkspaces != nil {
+		if *req.MaxRunningWorkspaces < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_running_workspaces", Detail: "Must be a positive integer or zero for no limit."})
+		}
+		maxRunningWorkspaces = *req.MaxRunningWorkspaces
+	}
+	portForwardingDenyByDefault := template.PortForwardingDenyByDefault
+	portForwardingAllowedPorts := template.PortForwardingAllowedPorts
+	if req.PortForwardingPolicy != nil {
+		portForwardingDenyByDefault = req.PortForwardingPolicy.DenyByDefault
+		portForwardingAllowedPorts = make([]int32, 0, len(req.PortForwardingPolicy.AllowedPorts))
+		for _, port := range req.PortForwardingPolicy.AllowedPorts {
+			if port == 0 {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "port_forwarding_policy.allowed_ports", Detail: "Ports must be between 1 and 65535."})
+				break
+			}
+			if !slices.Contains(portForwardingAllowedPorts, int32(port)) {
+				portForwardingAllowedPorts = append(portForwardingAllowedPorts, int32(port))
+			}
+		}
+		slices.Sort(portForwardingAllowedPorts)
+	}
+	sessionRecordingEnabled := template.SessionRecordingEnabled
+	sessionRecordingRetention := time.Duration(template.SessionRecordingRetention)
+	if req.SessionRecording != nil {
+		if req.SessionRecording.RetentionMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "session_recording.retention_ms", Detail: "Must be a positive duration or zero to keep recordings forever."})
+		}
+		sessionRecordingEnabled = req.SessionRecording.Enabled
+		sessionRecordingRetention = time.Duration(req.SessionRecording.RetentionMillis) * time.Millisecond
+	}
+	buildRetryMaxAttempts := template.BuildRetryMaxAttempts
+	buildRetryBackoff := time.Duration(template.BuildRetryBackoff)
+	if req.BuildRetryPolicy != nil {
+		if req.BuildRetryPolicy.MaxAttempts < 0 || req.BuildRetryPolicy.MaxAttempts > maxBuildRetryAttempts {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "build_retry_policy.max_attempts", Detail: fmt.Sprintf("Must be between 0 and %d.", maxBuildRetryAttempts)})
+		}
+		if req.BuildRetryPolicy.BackoffMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "build_retry_policy.backoff_ms", Detail: "Must be a positive duration or zero to retry immediately."})
+		}
+		buildRetryMaxAttempts = req.BuildRetryPolicy.MaxAttempts
+		buildRetryBackoff = time.Duration(req.BuildRetryPolicy.BackoffMillis) * time.Millisecond
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -657,7 +762,20 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.TimeTilDormantMillis == time.Duration(template.TimeTilDormant).Milliseconds() &&
 			req.TimeTilDormantAutoDeleteMillis == time.Duration(template.TimeTilDormantAutoDelete).Milliseconds() &&
 			req.RequireActiveVersion == template.RequireActiveVersion &&
-			(deprecationMessage == template.Deprecated) {
+			(deprecationMessage == template.Deprecated) &&
+			maintenanceSchedule == template.MaintenanceWindowSchedule &&
+			maintenanceDuration == time.Duration(template.MaintenanceWindowDuration) &&
+			visibility == template.Visibility &&
+			maxBuildDuration == time.Duration(template.MaxBuildDuration) &&
+			workspaceNamePattern == template.WorkspaceNamePattern &&
+			workspaceNameDescription == template.WorkspaceNameDescription &&
+			maxRunningWorkspaces == template.MaxRunningWorkspaces &&
+			portForwardingDenyByDefault == template.PortForwardingDenyByDefault &&
+			slices.Equal(portForwardingAllowedPorts, template.PortForwardingAllowedPorts) &&
+			sessionRecordingEnabled == template.SessionRecordingEnabled &&
+			sessionRecordingRetention == time.Duration(template.SessionRecordingRetention) &&
+			buildRetryMaxAttempts == template.BuildRetryMaxAttempts &&
+			buildRetryBackoff == time.Duration(template.BuildRetryBackoff) {
 			return nil
 		}
 
@@ -697,6 +815,104 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		if maintenanceSchedule != template.MaintenanceWindowSchedule ||
+			maintenanceDuration != time.Duration(template.MaintenanceWindowDuration) {
+			err = tx.UpdateTemplateMaintenanceWindowByID(ctx, database.UpdateTemplateMaintenanceWindowByIDParams{
+				ID:                        template.ID,
+				MaintenanceWindowSchedule: maintenanceSchedule,
+				MaintenanceWindowDuration: int64(maintenanceDuration),
+				UpdatedAt:                 dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template maintenance window: %w", err)
+			}
+		}
+
+		if visibility != template.Visibility {
+			err = tx.UpdateTemplateVisibilityByID(ctx, database.UpdateTemplateVisibilityByIDParams{
+				ID:         template.ID,
+				Visibility: visibility,
+				UpdatedAt:  dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template visibility: %w", err)
+			}
+		}
+
+		if maxBuildDuration != time.Duration(template.MaxBuildDuration) {
+			err = tx.UpdateTemplateMaxBuildDurationByID(ctx, database.UpdateTemplateMaxBuildDurationByIDParams{
+				ID:               template.ID,
+				MaxBuildDuration: int64(maxBuildDuration),
+				UpdatedAt:        dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template max build duration: %w", err)
+			}
+		}
+
+		if workspaceNamePattern != template.WorkspaceNamePattern ||
+			workspaceNameDescription != template.WorkspaceNameDescription {
+			err = tx.UpdateTemplateWorkspaceNamePolicyByID(ctx, database.UpdateTemplateWorkspaceNamePolicyByIDParams{
+				ID:                       template.ID,
+				WorkspaceNamePattern:     workspaceNamePattern,
+				WorkspaceNameDescription: workspaceNameDescription,
+				UpdatedAt:                dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template workspace name policy: %w", err)
+			}
+		}
+
+		if maxRunningWorkspaces != template.MaxRunningWorkspaces {
+			err = tx.UpdateTemplateMaxRunningWorkspacesByID(ctx, database.UpdateTemplateMaxRunningWorkspacesByIDParams{
+				ID:                   template.ID,
+				MaxRunningWorkspaces: maxRunningWorkspaces,
+				UpdatedAt:            dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template max running workspaces: %w", err)
+			}
+		}
+
+		if portForwardingDenyByDefault != template.PortForwardingDenyByDefault ||
+			!slices.Equal(portForwardingAllowedPorts, template.PortForwardingAllowedPorts) {
+			err = tx.UpdateTemplatePortForwardingPolicyByID(ctx, database.UpdateTemplatePortForwardingPolicyByIDParams{
+				ID:                          template.ID,
+				PortForwardingDenyByDefault: portForwardingDenyByDefault,
+				PortForwardingAllowedPorts:  portForwardingAllowedPorts,
+				UpdatedAt:                   dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template port forwarding policy: %w", err)
+			}
+		}
+
+		if sessionRecordingEnabled != template.SessionRecordingEnabled ||
+			sessionRecordingRetention != time.Duration(template.SessionRecordingRetention) {
+			err = tx.UpdateTemplateSessionRecordingByID(ctx, database.UpdateTemplateSessionRecordingByIDParams{
+				ID:                        template.ID,
+				SessionRecordingEnabled:   sessionRecordingEnabled,
+				SessionRecordingRetention: int64(sessionRecordingRetention),
+				UpdatedAt:                 dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template session recording: %w", err)
+			}
+		}
+
+		if buildRetryMaxAttempts != template.BuildRetryMaxAttempts ||
+			buildRetryBackoff != time.Duration(template.BuildRetryBackoff) {
+			err = tx.UpdateTemplateBuildRetryPolicyByID(ctx, database.UpdateTemplateBuildRetryPolicyByIDParams{
+				ID:                    template.ID,
+				BuildRetryMaxAttempts: buildRetryMaxAttempts,
+				BuildRetryBackoff:     int64(buildRetryBackoff),
+				UpdatedAt:             dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("update template build retry policy: %w", err)
+			}
+		}
+
 		updated, err = tx.GetTemplateByID(ctx, template.ID)
 		if err != nil {
 			return xerrors.Errorf("fetch updated template metadata: %w", err)
@@ -760,6 +976,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	}
 	aReq.New = updated
 
+	api.Webhooks.Publish(ctx, codersdk.WebhookEventTemplateUpdated, templateWebhookData(updated))
+
 	httpapi.Write(ctx, rw, http.StatusOK, api.convertTemplate(updated))
 }
 
@@ -797,6 +1015,35 @@ func (api *API) templateDAUs(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Generate workspace name for template
+// @ID generate-workspace-name-for-template
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.GenerateWorkspaceNameResponse
+// @Router /templates/{template}/workspace-name [get]
+func (api *API) templateWorkspaceName(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		apiKey   = httpmw.APIKey(r)
+	)
+
+	name, err := generateWorkspaceName(ctx, api.Database, template, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating workspace name.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.GenerateWorkspaceNameResponse{
+		Name: name,
+	})
+}
+
 // @Summary Get template examples by organization
 // @ID get-template-examples-by-organization
 // @Security CoderSessionToken
@@ -816,7 +1063,7 @@ func (api *API) templateExamples(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ex, err := examples.List()
+	ex, err := api.listExamples(ctx)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching examples.",
@@ -828,6 +1075,62 @@ func (api *API) templateExamples(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, ex)
 }
 
+// @Summary Get template example archive by organization
+// @ID get-template-example-archive-by-organization
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param example path string true "Example ID"
+// @Success 200
+// @Router /organizations/{organization}/templates/examples/{example}/archive [get]
+func (api *API) templateExampleArchive(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+		exampleID    = chi.URLParam(r, "example")
+	)
+
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceTemplate.InOrg(organization.ID)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	tar, err := api.exampleArchive(ctx, exampleID)
+	if xerrors.Is(err, examples.ErrNotFound) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching example.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", codersdk.ContentTypeTar)
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(tar)
+}
+
+// listExamples returns the starter templates of the registry mirror if one is
+// configured, and the examples embedded in Coder otherwise.
+func (api *API) listExamples(ctx context.Context) ([]codersdk.TemplateExample, error) {
+	if api.examplesMirror != nil {
+		return api.examplesMirror.List(ctx)
+	}
+	return examples.List()
+}
+
+// exampleArchive returns the tar of a starter template from the registry
+// mirror if one is configured, and from the embedded examples otherwise.
+func (api *API) exampleArchive(ctx context.Context, exampleID string) ([]byte, error) {
+	if api.examplesMirror != nil {
+		return api.examplesMirror.Archive(ctx, exampleID)
+	}
+	return examples.Archive(exampleID)
+}
+
 func (api *API) convertTemplates(templates []database.Template) []codersdk.Template {
 	apiTemplates := make([]codersdk.Template, 0, len(templates))
 
@@ -861,6 +1164,11 @@ func (api *API) convertTemplate(
 		autostopRequirementWeeks = 1
 	}
 
+	allowedPorts := make([]uint16, 0, len(template.PortForwardingAllowedPorts))
+	for _, port := range template.PortForwardingAllowedPorts {
+		allowedPorts = append(allowedPorts, uint16(port))
+	}
+
 	return codersdk.Template{
 		ID:                             template.ID,
 		CreatedAt:                      template.CreatedAt,
@@ -896,5 +1204,28 @@ func (api *API) convertTemplate(
 		RequireActiveVersion: templateAccessControl.RequireActiveVersion,
 		Deprecated:           templateAccessControl.IsDeprecated(),
 		DeprecationMessage:   templateAccessControl.Deprecated,
+		MaintenanceWindow: codersdk.TemplateMaintenanceWindow{
+			Schedule:       template.MaintenanceWindowSchedule,
+			DurationMillis: time.Duration(template.MaintenanceWindowDuration).Milliseconds(),
+		},
+		Visibility:             codersdk.TemplateVisibility(template.Visibility),
+		MaxBuildDurationMillis: time.Duration(template.MaxBuildDuration).Milliseconds(),
+		WorkspaceNamePolicy: codersdk.TemplateWorkspaceNamePolicy{
+			Pattern:     template.WorkspaceNamePattern,
+			Description: template.WorkspaceNameDescription,
+		},
+		MaxRunningWorkspaces: template.MaxRunningWorkspaces,
+		PortForwardingPolicy: codersdk.TemplatePortForwardingPolicy{
+			DenyByDefault: template.PortForwardingDenyByDefault,
+			AllowedPorts:  allowedPorts,
+		},
+		SessionRecording: codersdk.TemplateSessionRecording{
+			Enabled:         template.SessionRecordingEnabled,
+			RetentionMillis: time.Duration(template.SessionRecordingRetention).Milliseconds(),
+		},
+		BuildRetryPolicy: codersdk.TemplateBuildRetryPolicy{
+			MaxAttempts:   template.BuildRetryMaxAttempts,
+			BackoffMillis: time.Duration(template.BuildRetryBackoff).Milliseconds(),
+		},
 	}
 }
@@ -1304,6 +1304,351 @@ func TestPatchTemplateMeta(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, "max_ttl_ms")
 	})
+
+	t.Run("MaintenanceWindow", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		window := codersdk.TemplateMaintenanceWindow{
+			Schedule:       "CRON_TZ=UTC 0 2 * * SUN",
+			DurationMillis: time.Hour.Milliseconds(),
+		}
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaintenanceWindow: &window,
+		})
+		require.NoError(t, err)
+		require.Equal(t, window, updated.MaintenanceWindow)
+
+		// Omitting the window leaves it unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.Equal(t, window, updated.MaintenanceWindow)
+
+		// An empty schedule disables maintenance.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaintenanceWindow: &codersdk.TemplateMaintenanceWindow{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateMaintenanceWindow{}, updated.MaintenanceWindow)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaintenanceWindow: &codersdk.TemplateMaintenanceWindow{
+				Schedule:       "not a schedule",
+				DurationMillis: time.Hour.Milliseconds(),
+			},
+		})
+		require.ErrorContains(t, err, "maintenance_window.schedule")
+	})
+
+	t.Run("MaxBuildDuration", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxBuildDurationMillis)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxBuildDurationMillis: ptr.Ref(time.Hour.Milliseconds()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, time.Hour.Milliseconds(), updated.MaxBuildDurationMillis)
+
+		// Omitting the duration leaves it unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.Equal(t, time.Hour.Milliseconds(), updated.MaxBuildDurationMillis)
+
+		// Zero removes the limit.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxBuildDurationMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		require.Zero(t, updated.MaxBuildDurationMillis)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxBuildDurationMillis: ptr.Ref(int64(1000)),
+		})
+		require.ErrorContains(t, err, "max_build_duration_ms")
+	})
+
+	t.Run("WorkspaceNamePolicy", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.WorkspaceNamePolicy.Pattern)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		policy := codersdk.TemplateWorkspaceNamePolicy{
+			Pattern:     "^dev-[a-z-]+$",
+			Description: "Workspace names must start with dev-.",
+		}
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			WorkspaceNamePolicy: &policy,
+		})
+		require.NoError(t, err)
+		require.Equal(t, policy, updated.WorkspaceNamePolicy)
+
+		// Omitting the policy leaves it unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.Equal(t, policy, updated.WorkspaceNamePolicy)
+
+		generated, err := client.GenerateWorkspaceName(ctx, template.ID)
+		require.NoError(t, err)
+		require.Regexp(t, policy.Pattern, generated.Name)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			WorkspaceNamePolicy: &codersdk.TemplateWorkspaceNamePolicy{Pattern: "dev-("},
+		})
+		require.ErrorContains(t, err, "workspace_name_policy.pattern")
+
+		// An empty pattern removes the policy.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			WorkspaceNamePolicy: &codersdk.TemplateWorkspaceNamePolicy{},
+		})
+		require.NoError(t, err)
+		require.Empty(t, updated.WorkspaceNamePolicy)
+	})
+
+	t.Run("MaxRunningWorkspaces", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxRunningWorkspaces)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxRunningWorkspaces: ptr.Ref[int32](-1),
+		})
+		require.ErrorContains(t, err, "max_running_workspaces")
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxRunningWorkspaces: ptr.Ref[int32](1),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, updated.MaxRunningWorkspaces)
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		// The template's only running workspace can still be restarted.
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+		// But another workspace can't be started.
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "second",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "the limit is 1")
+	})
+
+	t.Run("PortForwardingPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.False(t, template.PortForwardingPolicy.DenyByDefault)
+		require.Empty(t, template.PortForwardingPolicy.AllowedPorts)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PortForwardingPolicy: &codersdk.TemplatePortForwardingPolicy{
+				DenyByDefault: true,
+				AllowedPorts:  []uint16{0},
+			},
+		})
+		require.ErrorContains(t, err, "port_forwarding_policy.allowed_ports")
+
+		// Allowed ports are deduplicated and sorted.
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PortForwardingPolicy: &codersdk.TemplatePortForwardingPolicy{
+				DenyByDefault: true,
+				AllowedPorts:  []uint16{8080, 3000, 8080},
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, updated.PortForwardingPolicy.DenyByDefault)
+		require.Equal(t, []uint16{3000, 8080}, updated.PortForwardingPolicy.AllowedPorts)
+
+		// Omitting the policy leaves it unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.True(t, updated.PortForwardingPolicy.DenyByDefault)
+		require.Equal(t, []uint16{3000, 8080}, updated.PortForwardingPolicy.AllowedPorts)
+	})
+
+	t.Run("SessionRecording", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.False(t, template.SessionRecording.Enabled)
+		require.Zero(t, template.SessionRecording.RetentionMillis)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			SessionRecording: &codersdk.TemplateSessionRecording{
+				Enabled:         true,
+				RetentionMillis: -1,
+			},
+		})
+		require.ErrorContains(t, err, "session_recording.retention_ms")
+
+		retention := (30 * 24 * time.Hour).Milliseconds()
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			SessionRecording: &codersdk.TemplateSessionRecording{
+				Enabled:         true,
+				RetentionMillis: retention,
+			},
+		})
+		require.NoError(t, err)
+		require.True(t, updated.SessionRecording.Enabled)
+		require.Equal(t, retention, updated.SessionRecording.RetentionMillis)
+
+		// Omitting the settings leaves them unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.True(t, updated.SessionRecording.Enabled)
+		require.Equal(t, retention, updated.SessionRecording.RetentionMillis)
+	})
+
+	t.Run("BuildRetryPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.BuildRetryPolicy.MaxAttempts)
+		require.Zero(t, template.BuildRetryPolicy.BackoffMillis)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			BuildRetryPolicy: &codersdk.TemplateBuildRetryPolicy{
+				MaxAttempts: 100,
+			},
+		})
+		require.ErrorContains(t, err, "build_retry_policy.max_attempts")
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			BuildRetryPolicy: &codersdk.TemplateBuildRetryPolicy{
+				MaxAttempts:   3,
+				BackoffMillis: -1,
+			},
+		})
+		require.ErrorContains(t, err, "build_retry_policy.backoff_ms")
+
+		backoff := time.Minute.Milliseconds()
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			BuildRetryPolicy: &codersdk.TemplateBuildRetryPolicy{
+				MaxAttempts:   3,
+				BackoffMillis: backoff,
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, updated.BuildRetryPolicy.MaxAttempts)
+		require.Equal(t, backoff, updated.BuildRetryPolicy.BackoffMillis)
+
+		// Omitting the policy leaves it unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "changed",
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, updated.BuildRetryPolicy.MaxAttempts)
+		require.Equal(t, backoff, updated.BuildRetryPolicy.BackoffMillis)
+	})
+
+	t.Run("Visibility", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Equal(t, codersdk.TemplateVisibilityPublic, template.Visibility)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		updateVisibility := func(visibility codersdk.TemplateVisibility) {
+			updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+				Visibility: &visibility,
+			})
+			require.NoError(t, err)
+			require.Equal(t, visibility, updated.Visibility)
+		}
+
+		// Unlisted templates can be used by members, but are not listed.
+		updateVisibility(codersdk.TemplateVisibilityUnlisted)
+		templates, err := member.TemplatesByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, templates)
+		_, err = member.Template(ctx, template.ID)
+		require.NoError(t, err)
+
+		// Private templates are hidden from members entirely.
+		updateVisibility(codersdk.TemplateVisibilityPrivate)
+		_, err = member.Template(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		// Template admins still see them listed.
+		templates, err = client.TemplatesByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+
+		updateVisibility(codersdk.TemplateVisibilityPublic)
+		templates, err = member.TemplatesByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+
+		invalid := codersdk.TemplateVisibility("secret")
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Visibility: &invalid,
+		})
+		require.ErrorContains(t, err, "visibility")
+	})
 }
 
 func TestDeleteTemplate(t *testing.T) {
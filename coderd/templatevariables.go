@@ -0,0 +1,364 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template variable values
+// @ID get-template-variable-values
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVariableValue
+// @Router /templates/{template}/variables [get]
+func (api *API) templateVariableValues(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	values, err := api.Database.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to read the variable values of this template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template variable values.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.TemplateVariableValue, 0, len(values))
+	for _, value := range values {
+		resp = append(resp, convertTemplateVariableValue(value.Name, value.Value, value.Sensitive, value.UpdatedAt))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Upsert template variable value
+// @ID upsert-template-variable-value
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param variable path string true "Variable name"
+// @Param request body codersdk.UpsertTemplateVariableValueRequest true "Upsert template variable value request"
+// @Success 200 {object} codersdk.TemplateVariableValue
+// @Router /templates/{template}/variables/{variable} [put]
+func (api *API) putTemplateVariableValue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	name := chi.URLParam(r, "variable")
+
+	var req codersdk.UpsertTemplateVariableValueRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Values can only be set for the variables of the active version, so they
+	// can be validated against the type of the variable.
+	variables, err := api.Database.GetTemplateVersionVariables(ctx, template.ActiveVersionID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version variables.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	var (
+		variable database.TemplateVersionVariable
+		found    bool
+	)
+	for _, v := range variables {
+		if v.Name == name {
+			variable = v
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("The active version of the template has no variable %q.", name),
+		})
+		return
+	}
+	if req.Value == "" && variable.Required {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Variable %q is required.", name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "value",
+				Detail: "Required variables can't be set to an empty value.",
+			}},
+		})
+		return
+	}
+	if req.Value != "" {
+		if err := codersdk.ValidateTemplateVariableValue(variable.Type, req.Value); err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid value for variable %q of type %q.", name, variable.Type),
+				Validations: []codersdk.ValidationError{{
+					Field:  "value",
+					Detail: err.Error(),
+				}},
+			})
+			return
+		}
+	}
+
+	now := dbtime.Now()
+	value, err := api.Database.UpsertTemplateVariableValue(ctx, database.UpsertTemplateVariableValueParams{
+		TemplateID: template.ID,
+		Name:       name,
+		Value:      req.Value,
+		Sensitive:  req.Sensitive || variable.Sensitive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to set the variable values of this template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting template variable value.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVariableValue(value.Name, value.Value, value.Sensitive, value.UpdatedAt))
+}
+
+// @Summary Delete template variable value
+// @ID delete-template-variable-value
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param variable path string true "Variable name"
+// @Success 204
+// @Router /templates/{template}/variables/{variable} [delete]
+func (api *API) deleteTemplateVariableValue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+	name := chi.URLParam(r, "variable")
+
+	values, err := api.Database.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to unset the variable values of this template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template variable values.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	found := false
+	for _, value := range values {
+		if value.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Variable %q has no value set on the template.", name),
+		})
+		return
+	}
+
+	err = api.Database.DeleteTemplateVariableValue(ctx, database.DeleteTemplateVariableValueParams{
+		TemplateID: template.ID,
+		Name:       name,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unsetting template variable value.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get organization template variable values
+// @ID get-organization-template-variable-values
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVariableValue
+// @Router /organizations/{organization}/template-variables [get]
+func (api *API) organizationTemplateVariableValues(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+
+	values, err := api.Database.GetOrganizationTemplateVariableValues(ctx, organization.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to read the template variable values of this organization.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization template variable values.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.TemplateVariableValue, 0, len(values))
+	for _, value := range values {
+		resp = append(resp, convertTemplateVariableValue(value.Name, value.Value, value.Sensitive, value.UpdatedAt))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Upsert organization template variable value
+// @ID upsert-organization-template-variable-value
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param variable path string true "Variable name"
+// @Param request body codersdk.UpsertTemplateVariableValueRequest true "Upsert template variable value request"
+// @Success 200 {object} codersdk.TemplateVariableValue
+// @Router /organizations/{organization}/template-variables/{variable} [put]
+func (api *API) putOrganizationTemplateVariableValue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+	name := chi.URLParam(r, "variable")
+
+	var req codersdk.UpsertTemplateVariableValueRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	// Default values are validated against the type of the variable when a
+	// version using them is pushed.
+	if req.Value == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Default values of template variables can't be empty.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "value",
+				Detail: "Unset the default value instead.",
+			}},
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	value, err := api.Database.UpsertOrganizationTemplateVariableValue(ctx, database.UpsertOrganizationTemplateVariableValueParams{
+		OrganizationID: organization.ID,
+		Name:           name,
+		Value:          req.Value,
+		Sensitive:      req.Sensitive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to set the template variable values of this organization.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting organization template variable value.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVariableValue(value.Name, value.Value, value.Sensitive, value.UpdatedAt))
+}
+
+// @Summary Delete organization template variable value
+// @ID delete-organization-template-variable-value
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param variable path string true "Variable name"
+// @Success 204
+// @Router /organizations/{organization}/template-variables/{variable} [delete]
+func (api *API) deleteOrganizationTemplateVariableValue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+	name := chi.URLParam(r, "variable")
+
+	values, err := api.Database.GetOrganizationTemplateVariableValues(ctx, organization.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to unset the template variable values of this organization.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization template variable values.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	found := false
+	for _, value := range values {
+		if value.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Variable %q has no default value in the organization.", name),
+		})
+		return
+	}
+
+	err = api.Database.DeleteOrganizationTemplateVariableValue(ctx, database.DeleteOrganizationTemplateVariableValueParams{
+		OrganizationID: organization.ID,
+		Name:           name,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unsetting organization template variable value.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func convertTemplateVariableValue(name, value string, sensitive bool, updatedAt time.Time) codersdk.TemplateVariableValue {
+	if sensitive {
+		value = redacted
+	}
+	return codersdk.TemplateVariableValue{
+		Name:      name,
+		Value:     value,
+		Sensitive: sensitive,
+		UpdatedAt: updatedAt,
+	}
+}
@@ -0,0 +1,138 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVariableValues(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, templateVariablesResponses([]*proto.TemplateVariable{
+		{Name: "region", Type: "string", DefaultValue: "us-east-1"},
+		{Name: "replicas", Type: "number", DefaultValue: "1"},
+		{Name: "token", Type: "string", DefaultValue: "default", Sensitive: true},
+	}))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	region, err := client.UpsertTemplateVariableValue(ctx, template.ID, "region", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "eu-west-1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "region", region.Name)
+	require.Equal(t, "eu-west-1", region.Value)
+	require.False(t, region.Sensitive)
+
+	// Values of sensitive variables are always sensitive, and redacted.
+	token, err := client.UpsertTemplateVariableValue(ctx, template.ID, "token", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "secret",
+	})
+	require.NoError(t, err)
+	require.True(t, token.Sensitive)
+	require.Equal(t, "*redacted*", token.Value)
+
+	values, err := client.TemplateVariableValues(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateVariableValue{region, token}, values)
+
+	var apiErr *codersdk.Error
+	_, err = client.UpsertTemplateVariableValue(ctx, template.ID, "replicas", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "many",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.UpsertTemplateVariableValue(ctx, template.ID, "missing", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "value",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Members can't read or set the values of a template.
+	_, err = member.TemplateVariableValues(ctx, template.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	_, err = member.UpsertTemplateVariableValue(ctx, template.ID, "region", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "ap-south-1",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.DeleteTemplateVariableValue(ctx, template.ID, "region")
+	require.NoError(t, err)
+	values, err = client.TemplateVariableValues(ctx, template.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateVariableValue{token}, values)
+
+	err = client.DeleteTemplateVariableValue(ctx, template.ID, "region")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestOrganizationTemplateVariableValues(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	region, err := client.UpsertOrganizationTemplateVariableValue(ctx, owner.OrganizationID, "region", codersdk.UpsertTemplateVariableValueRequest{
+		Value: "eu-west-1",
+	})
+	require.NoError(t, err)
+	values, err := client.OrganizationTemplateVariableValues(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateVariableValue{region}, values)
+
+	// The default of the organization is used for required variables pushed
+	// without a value.
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, templateVariablesResponses([]*proto.TemplateVariable{
+		{Name: "region", Type: "string", Required: true},
+	}))
+	version = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	require.Empty(t, version.Job.Error)
+	variables, err := client.TemplateVersionVariables(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, variables, 1)
+	require.Equal(t, "eu-west-1", variables[0].Value)
+
+	var apiErr *codersdk.Error
+	_, err = client.UpsertOrganizationTemplateVariableValue(ctx, owner.OrganizationID, "region", codersdk.UpsertTemplateVariableValueRequest{})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = client.DeleteOrganizationTemplateVariableValue(ctx, owner.OrganizationID, "region")
+	require.NoError(t, err)
+	values, err = client.OrganizationTemplateVariableValues(ctx, owner.OrganizationID)
+	require.NoError(t, err)
+	require.Empty(t, values)
+}
+
+func templateVariablesResponses(templateVariables []*proto.TemplateVariable) *echo.Responses {
+	return &echo.Responses{
+		Parse: []*proto.Response{{
+			Type: &proto.Response_Parse{
+				Parse: &proto.ParseComplete{
+					TemplateVariables: templateVariables,
+				},
+			},
+		}},
+		ProvisionPlan:  echo.PlanComplete,
+		ProvisionApply: echo.ApplyComplete,
+	}
+}
@@ -0,0 +1,285 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get template version presets
+// @ID get-template-version-presets
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200 {array} codersdk.TemplateVersionPreset
+// @Router /templateversions/{templateversion}/presets [get]
+func (api *API) templateVersionPresets(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	presets, err := api.Database.GetTemplateVersionPresets(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.TemplateVersionPreset, 0, len(presets))
+	for _, preset := range presets {
+		converted, err := convertTemplateVersionPreset(preset)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error converting template version preset.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		resp = append(resp, converted)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create template version preset
+// @ID create-template-version-preset
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionPresetRequest true "Create template version preset request"
+// @Success 201 {object} codersdk.TemplateVersionPreset
+// @Router /templateversions/{templateversion}/presets [post]
+func (api *API) postTemplateVersionPreset(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	var req codersdk.CreateTemplateVersionPresetRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// The values are validated against the parameters of the version, which
+	// are only known once it has been imported.
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !job.CompletedAt.Valid || job.Error.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Presets can only be created for template versions that were imported successfully.",
+		})
+		return
+	}
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	parameters, err := convertTemplateVersionParameters(dbParameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version parameter.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	seen := make(map[string]struct{}, len(req.Parameters))
+	for _, value := range req.Parameters {
+		if _, ok := seen[value.Name]; ok {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "parameters",
+				Detail: fmt.Sprintf("parameter %q is given more than once", value.Name),
+			})
+			continue
+		}
+		seen[value.Name] = struct{}{}
+
+		var parameter *codersdk.TemplateVersionParameter
+		for i := range parameters {
+			if parameters[i].Name == value.Name {
+				parameter = &parameters[i]
+				break
+			}
+		}
+		if parameter == nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "parameters",
+				Detail: fmt.Sprintf("the template version has no parameter %q", value.Name),
+			})
+			continue
+		}
+		value := value
+		if err := codersdk.ValidateWorkspaceBuildParameter(*parameter, &value, nil); err != nil {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  "parameters",
+				Detail: err.Error(),
+			})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Preset values don't satisfy the parameters of the template version.",
+			Validations: validations,
+		})
+		return
+	}
+
+	rawParameters, err := json.Marshal(req.Parameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error marshaling preset parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	preset, err := api.Database.InsertTemplateVersionPreset(ctx, database.InsertTemplateVersionPresetParams{
+		TemplateVersionID: templateVersion.ID,
+		Name:              req.Name,
+		Parameters:        rawParameters,
+		CreatedAt:         dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to create presets for this template version.",
+		})
+		return
+	}
+	if database.IsUniqueViolation(err, database.UniqueTemplateVersionPresetsPkey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("Preset %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{
+				Field:  "name",
+				Detail: "This value is already in use and should be unique.",
+			}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template version preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := convertTemplateVersionPreset(preset)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, resp)
+}
+
+// @Summary Delete template version preset
+// @ID delete-template-version-preset
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param preset path string true "Preset name"
+// @Success 204
+// @Router /templateversions/{templateversion}/presets/{preset} [delete]
+func (api *API) deleteTemplateVersionPreset(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+	name := chi.URLParam(r, "preset")
+
+	presets, err := api.Database.GetTemplateVersionPresets(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version presets.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	found := false
+	for _, preset := range presets {
+		if preset.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Preset %q doesn't exist.", name),
+		})
+		return
+	}
+
+	err = api.Database.DeleteTemplateVersionPreset(ctx, database.DeleteTemplateVersionPresetParams{
+		TemplateVersionID: templateVersion.ID,
+		Name:              name,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You are not authorized to delete presets of this template version.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting template version preset.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// applyTemplateVersionPreset returns the given parameter values, with the
+// values of the preset added for the parameters that aren't given.
+func applyTemplateVersionPreset(preset database.TemplateVersionPreset, values []codersdk.WorkspaceBuildParameter) ([]codersdk.WorkspaceBuildParameter, error) {
+	converted, err := convertTemplateVersionPreset(preset)
+	if err != nil {
+		return nil, err
+	}
+	applied := append([]codersdk.WorkspaceBuildParameter{}, values...)
+next:
+	for _, presetValue := range converted.Parameters {
+		for _, value := range values {
+			if value.Name == presetValue.Name {
+				continue next
+			}
+		}
+		applied = append(applied, presetValue)
+	}
+	return applied, nil
+}
+
+func convertTemplateVersionPreset(preset database.TemplateVersionPreset) (codersdk.TemplateVersionPreset, error) {
+	parameters := make([]codersdk.WorkspaceBuildParameter, 0)
+	err := json.Unmarshal(preset.Parameters, &parameters)
+	if err != nil {
+		return codersdk.TemplateVersionPreset{}, xerrors.Errorf("unmarshal parameters of preset %q: %w", preset.Name, err)
+	}
+	return codersdk.TemplateVersionPreset{
+		Name:       preset.Name,
+		Parameters: parameters,
+		CreatedAt:  preset.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionPresets(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, presetParametersResponses())
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	preset, err := client.CreateTemplateVersionPreset(ctx, version.ID, codersdk.CreateTemplateVersionPresetRequest{
+		Name: "large",
+		Parameters: []codersdk.WorkspaceBuildParameter{
+			{Name: "cpu", Value: "16"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "large", preset.Name)
+	require.Equal(t, []codersdk.WorkspaceBuildParameter{{Name: "cpu", Value: "16"}}, preset.Parameters)
+
+	presets, err := client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	require.Equal(t, "large", presets[0].Name)
+
+	// Members can use presets, but not manage them.
+	presets, err = member.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	_, err = member.CreateTemplateVersionPreset(ctx, version.ID, codersdk.CreateTemplateVersionPresetRequest{
+		Name:       "small",
+		Parameters: []codersdk.WorkspaceBuildParameter{{Name: "cpu", Value: "1"}},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	_, err = client.CreateTemplateVersionPreset(ctx, version.ID, codersdk.CreateTemplateVersionPresetRequest{
+		Name:       "large",
+		Parameters: []codersdk.WorkspaceBuildParameter{{Name: "cpu", Value: "8"}},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+	// Values must satisfy the validation of the parameters.
+	for _, parameters := range [][]codersdk.WorkspaceBuildParameter{
+		{{Name: "cpu", Value: "64"}},
+		{{Name: "cpu", Value: "two"}},
+		{{Name: "gpu", Value: "true"}},
+		{{Name: "cpu", Value: "2"}, {Name: "cpu", Value: "4"}},
+	} {
+		_, err = client.CreateTemplateVersionPreset(ctx, version.ID, codersdk.CreateTemplateVersionPresetRequest{
+			Name:       "invalid",
+			Parameters: parameters,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "parameters", apiErr.Validations[0].Field)
+	}
+
+	// The values of the preset are used for the parameters that aren't given.
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Preset = "large"
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}
+	})
+	workspaceBuild := coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+	workspaceBuildParameters, err := member.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+		{Name: "cpu", Value: "16"},
+		{Name: "region", Value: "eu"},
+	}, workspaceBuildParameters)
+
+	_, err = member.CreateWorkspace(ctx, owner.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "unknown-preset",
+		Preset:     "huge",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	require.Len(t, apiErr.Validations, 1)
+	require.Equal(t, "preset", apiErr.Validations[0].Field)
+
+	err = member.DeleteTemplateVersionPreset(ctx, version.ID, "large")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.DeleteTemplateVersionPreset(ctx, version.ID, "large")
+	require.NoError(t, err)
+	presets, err = client.TemplateVersionPresets(ctx, version.ID)
+	require.NoError(t, err)
+	require.Empty(t, presets)
+
+	err = client.DeleteTemplateVersionPreset(ctx, version.ID, "large")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func presetParametersResponses() *echo.Responses {
+	return &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{
+							Name:          "cpu",
+							Type:          "number",
+							DefaultValue:  "2",
+							Mutable:       true,
+							ValidationMin: ptr.Ref(int32(1)),
+							ValidationMax: ptr.Ref(int32(16)),
+						},
+						{
+							Name:         "region",
+							Type:         "string",
+							DefaultValue: "us",
+							Mutable:      true,
+						},
+					},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	}
+}
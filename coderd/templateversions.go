@@ -1,6 +1,7 @@
 package coderd
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
@@ -27,7 +28,9 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/parameter"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/provisionertags"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/templatediff"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
@@ -274,6 +277,221 @@ func (api *API) templateVersionRichParameters(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusOK, templateVersionParameters)
 }
 
+// @Summary Get template version diff
+// @ID get-template-version-diff
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param base query string false "Base template version ID, defaults to the active version of the template" format(uuid)
+// @Success 200 {object} codersdk.TemplateVersionDiff
+// @Router /templateversions/{templateversion}/diff [get]
+func (api *API) templateVersionDiff(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	templateVersion := httpmw.TemplateVersionParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	baseID := p.UUID(vals, uuid.Nil, "base")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	if baseID == uuid.Nil {
+		if !templateVersion.TemplateID.Valid {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "A base template version is required for versions without a template.",
+			})
+			return
+		}
+		template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if err != nil {
+			if httpapi.Is404Error(err) {
+				httpapi.ResourceNotFound(rw)
+				return
+			}
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		baseID = template.ActiveVersionID
+	}
+
+	baseVersion, err := api.Database.GetTemplateVersionByID(ctx, baseID)
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+				Message: fmt.Sprintf("Base template version %q not found.", baseID),
+			})
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching base template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	base, ok := api.templateVersionDiffSource(ctx, rw, baseVersion)
+	if !ok {
+		return
+	}
+	target, ok := api.templateVersionDiffSource(ctx, rw, templateVersion)
+	if !ok {
+		return
+	}
+
+	diff := codersdk.TemplateVersionDiff{
+		BaseVersionID:   baseVersion.ID,
+		TargetVersionID: templateVersion.ID,
+		Metadata:        templatediff.MetadataChanges(base.metadata, target.metadata),
+	}
+	diff.AddedParameters, diff.RemovedParameters, diff.ChangedParameters = templatediff.Parameters(base.parameters, target.parameters)
+	// Users that can read both versions may still not be allowed to read the
+	// source code, in which case only the file changes are omitted.
+	if base.files != nil && target.files != nil {
+		diff.Files, err = templatediff.Files(base.files, target.files)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error comparing template version files.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, diff)
+}
+
+type templateVersionDiffSource struct {
+	parameters []codersdk.TemplateVersionParameter
+	metadata   templatediff.Metadata
+	// files is nil if the user is not allowed to read the source code.
+	files map[string][]byte
+}
+
+// templateVersionDiffSource fetches the parts of a template version that are
+// compared by the template version diff.
+func (api *API) templateVersionDiffSource(ctx context.Context, rw http.ResponseWriter, templateVersion database.TemplateVersion) (templateVersionDiffSource, bool) {
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return templateVersionDiffSource{}, false
+	}
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Job hasn't completed!",
+			Detail:  fmt.Sprintf("Template version %q is still being imported.", templateVersion.ID),
+		})
+		return templateVersionDiffSource{}, false
+	}
+
+	dbParameters, err := api.Database.GetTemplateVersionParameters(ctx, templateVersion.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version parameters.",
+			Detail:  err.Error(),
+		})
+		return templateVersionDiffSource{}, false
+	}
+	parameters, err := convertTemplateVersionParameters(dbParameters)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template version parameter.",
+			Detail:  err.Error(),
+		})
+		return templateVersionDiffSource{}, false
+	}
+
+	source := templateVersionDiffSource{
+		parameters: parameters,
+		metadata: templatediff.Metadata{
+			Provisioner:           string(job.Provisioner),
+			Tags:                  job.Tags,
+			ExternalAuthProviders: templateVersion.ExternalAuthProviders,
+			Readme:                templateVersion.Readme,
+		},
+	}
+
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if httpapi.Is404Error(err) {
+		return source, true
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version files.",
+			Detail:  err.Error(),
+		})
+		return templateVersionDiffSource{}, false
+	}
+	if file.Mimetype != codersdk.ContentTypeTar {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Template version %q has unsupported source type %q.", templateVersion.ID, file.Mimetype),
+		})
+		return templateVersionDiffSource{}, false
+	}
+	source.files, err = templatediff.ExtractFiles(bytes.NewReader(file.Data))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error extracting template version files.",
+			Detail:  err.Error(),
+		})
+		return templateVersionDiffSource{}, false
+	}
+	return source, true
+}
+
+// @Summary Get template version archive
+// @ID get-template-version-archive
+// @Security CoderSessionToken
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Success 200
+// @Router /templateversions/{templateversion}/archive [get]
+func (api *API) templateVersionArchive(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Reading the file is authorized separately, as not everyone who can read
+	// a template version may read its source code.
+	file, err := api.Database.GetFileByID(ctx, job.FileID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.Header().Set("Content-Type", file.Mimetype)
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", templateVersion.Name+".tar"))
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(file.Data)
+}
+
 // @Summary Get external auth by template version
 // @ID get-external-auth-by-template-version
 // @Security CoderSessionToken
@@ -493,7 +711,8 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		Type:           database.ProvisionerJobTypeTemplateVersionDryRun,
 		Input:          input,
 		// Copy tags from the previous run.
-		Tags: job.Tags,
+		Tags:            job.Tags,
+		TagRequirements: job.TagRequirements,
 		TraceMetadata: pqtype.NullRawMessage{
 			Valid:      true,
 			RawMessage: metadataRaw,
@@ -1275,6 +1494,7 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	aReq.New = newTemplate
 
 	api.publishTemplateUpdate(ctx, template.ID)
+	api.Webhooks.Publish(ctx, codersdk.WebhookEventTemplateUpdated, templateWebhookData(newTemplate))
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
@@ -1333,6 +1553,14 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 
 	// Ensures the "owner" is properly applied.
 	tags := provisionersdk.MutateTags(apiKey.UserID, req.ProvisionerTags)
+	tagRequirements, err := provisionertags.ParseAll(req.ProvisionerRequirements)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid provisioner requirements.",
+			Validations: []codersdk.ValidationError{{Field: "provisioner_requirements", Detail: err.Error()}},
+		})
+		return
+	}
 
 	if req.ExampleID != "" && req.FileID != uuid.Nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -1342,7 +1570,6 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 	}
 
 	var file database.File
-	var err error
 	// if example id is specified we need to copy the embedded tar into a new file in the database
 	if req.ExampleID != "" {
 		if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceFile.WithOwner(apiKey.UserID.String())) {
@@ -1355,8 +1582,8 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			return
 		}
 
-		// lookup template tar from embedded examples
-		tar, err := examples.Archive(req.ExampleID)
+		// lookup template tar from the embedded examples or the registry mirror
+		tar, err := api.exampleArchive(ctx, req.ExampleID)
 		if err != nil {
 			if xerrors.Is(err, examples.ErrNotFound) {
 				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -1446,17 +1673,18 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		}
 
 		provisionerJob, err = tx.InsertProvisionerJob(ctx, database.InsertProvisionerJobParams{
-			ID:             jobID,
-			CreatedAt:      dbtime.Now(),
-			UpdatedAt:      dbtime.Now(),
-			OrganizationID: organization.ID,
-			InitiatorID:    apiKey.UserID,
-			Provisioner:    database.ProvisionerType(req.Provisioner),
-			StorageMethod:  database.ProvisionerStorageMethodFile,
-			FileID:         file.ID,
-			Type:           database.ProvisionerJobTypeTemplateVersionImport,
-			Input:          jobInput,
-			Tags:           tags,
+			ID:              jobID,
+			CreatedAt:       dbtime.Now(),
+			UpdatedAt:       dbtime.Now(),
+			OrganizationID:  organization.ID,
+			InitiatorID:     apiKey.UserID,
+			Provisioner:     database.ProvisionerType(req.Provisioner),
+			StorageMethod:   database.ProvisionerStorageMethodFile,
+			FileID:          file.ID,
+			Type:            database.ProvisionerJobTypeTemplateVersionImport,
+			Input:           jobInput,
+			Tags:            tags,
+			TagRequirements: tagRequirements,
 			TraceMetadata: pqtype.NullRawMessage{
 				Valid:      true,
 				RawMessage: traceMetadataRaw,
@@ -1478,6 +1706,11 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			req.Name = namesgenerator.GetRandomName(1)
 		}
 
+		var git codersdk.TemplateVersionGitMetadata
+		if req.Git != nil {
+			git = *req.Git
+		}
+
 		err = tx.InsertTemplateVersion(ctx, database.InsertTemplateVersionParams{
 			ID:             templateVersionID,
 			TemplateID:     templateID,
@@ -1489,6 +1722,9 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 			Readme:         "",
 			JobID:          provisionerJob.ID,
 			CreatedBy:      apiKey.UserID,
+			GitCommitSha:   git.CommitSHA,
+			GitBranch:      git.Branch,
+			GitTag:         git.Tag,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert template version: %w", err)
@@ -1601,10 +1837,22 @@ func convertTemplateVersion(version database.TemplateVersion, job codersdk.Provi
 			AvatarURL: version.CreatedByAvatarURL,
 		},
 		Archived: version.Archived,
+		Git:      convertTemplateVersionGitMetadata(version),
 		Warnings: warnings,
 	}
 }
 
+func convertTemplateVersionGitMetadata(version database.TemplateVersion) *codersdk.TemplateVersionGitMetadata {
+	if version.GitCommitSha == "" {
+		return nil
+	}
+	return &codersdk.TemplateVersionGitMetadata{
+		CommitSHA: version.GitCommitSha,
+		Branch:    version.GitBranch,
+		Tag:       version.GitTag,
+	}
+}
+
 func convertTemplateVersionParameters(dbParams []database.TemplateVersionParameter) ([]codersdk.TemplateVersionParameter, error) {
 	params := make([]codersdk.TemplateVersionParameter, 0)
 	for _, dbParameter := range dbParams {
@@ -1663,6 +1911,7 @@ func convertTemplateVersionParameter(param database.TemplateVersionParameter) (c
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		DisplayCondition:     param.DisplayCondition,
 	}, nil
 }
 
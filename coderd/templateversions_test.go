@@ -3,7 +3,9 @@ package coderd_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
@@ -129,6 +131,25 @@ func TestPostTemplateVersionsByOrganization(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
 	})
 
+	t.Run("InvalidProvisionerRequirements", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod:           codersdk.ProvisionerStorageMethodFile,
+			FileID:                  uuid.New(),
+			Provisioner:             codersdk.ProvisionerTypeEcho,
+			ProvisionerRequirements: []string{"region in [eu-west"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("WithParameters", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
@@ -160,6 +181,49 @@ func TestPostTemplateVersionsByOrganization(t *testing.T) {
 		assert.Equal(t, database.AuditActionCreate, auditor.AuditLogs()[1].Action)
 	})
 
+	t.Run("GitMetadata", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		data, err := echo.Tar(&echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ApplyComplete,
+		})
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		file, err := client.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(data))
+		require.NoError(t, err)
+		git := &codersdk.TemplateVersionGitMetadata{
+			CommitSHA: "4b825dc642cb6eb9a060e54bf8d69288fbee4904",
+			Branch:    "main",
+			Tag:       "v1.0.0",
+		}
+		version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			FileID:        file.ID,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+			Git:           git,
+		})
+		require.NoError(t, err)
+		require.Equal(t, git, version.Git)
+
+		version, err = client.TemplateVersion(ctx, version.ID)
+		require.NoError(t, err)
+		require.Equal(t, git, version.Git)
+
+		_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			FileID:        file.ID,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+			Git:           &codersdk.TemplateVersionGitMetadata{CommitSHA: "not-a-sha"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("Example", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, nil)
@@ -1111,6 +1175,79 @@ func TestTemplateExamples(t *testing.T) {
 		require.NoError(t, err)
 		require.EqualValues(t, ls, ex)
 	})
+	t.Run("Archive", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		archive, err := client.TemplateExampleArchive(ctx, user.OrganizationID, "docker")
+		require.NoError(t, err)
+		expected, err := examples.Archive("docker")
+		require.NoError(t, err)
+		require.Equal(t, expected, archive)
+
+		_, err = client.TemplateExampleArchive(ctx, user.OrganizationID, "missing")
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+	t.Run("Mirror", func(t *testing.T) {
+		t.Parallel()
+
+		archive, err := examples.Archive("docker")
+		require.NoError(t, err)
+		mirrored := examples.MirrorExample{
+			TemplateExample: codersdk.TemplateExample{
+				ID:          "internal-docker",
+				Name:        "Internal Docker",
+				Description: "Docker template of the internal mirror",
+				Tags:        []string{},
+			},
+			SHA256: examples.ArchiveChecksum(archive),
+		}
+		mirror := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/examples/index.json":
+				_ = json.NewEncoder(rw).Encode([]examples.MirrorExample{mirrored})
+			case "/examples/internal-docker.tar":
+				_, _ = rw.Write(archive)
+			default:
+				rw.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(mirror.Close)
+
+		dv := coderdtest.DeploymentValues(t)
+		require.NoError(t, dv.ExamplesRegistryURL.Set(mirror.URL+"/examples"))
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		ex, err := client.TemplateExamples(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.TemplateExample{mirrored.TemplateExample}, ex)
+
+		got, err := client.TemplateExampleArchive(ctx, user.OrganizationID, "internal-docker")
+		require.NoError(t, err)
+		require.Equal(t, archive, got)
+
+		// Template versions are created from the mirrored examples.
+		version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			Name:          "internal",
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			ExampleID:     "internal-docker",
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "internal", version.Name)
+	})
 }
 
 func TestTemplateVersionVariables(t *testing.T) {
@@ -1544,6 +1681,117 @@ func TestTemplateVersionParameters_Order(t *testing.T) {
 	require.Equal(t, thirdParameterName, templateRichParameters[4].Name)
 }
 
+func TestTemplateVersionDiff(t *testing.T) {
+	t.Parallel()
+
+	planWithParameters := func(parameters ...*proto.RichParameter) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{{
+				Type: &proto.Response_Plan{
+					Plan: &proto.PlanComplete{
+						Parameters: parameters,
+					},
+				},
+			}},
+			ProvisionApply: echo.ApplyComplete,
+		}
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, planWithParameters(
+		&proto.RichParameter{Name: "region", Type: "string", Description: "Region"},
+		&proto.RichParameter{Name: "legacy", Type: "bool"},
+	))
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	updated := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, planWithParameters(
+		&proto.RichParameter{Name: "region", Type: "string", Description: "Cloud region"},
+		&proto.RichParameter{Name: "size", Type: "number"},
+	), template.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, updated.ID)
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		diff, err := client.TemplateVersionDiff(ctx, updated.ID, uuid.Nil)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, diff.BaseVersionID)
+		require.Equal(t, updated.ID, diff.TargetVersionID)
+		require.Len(t, diff.AddedParameters, 1)
+		require.Equal(t, "size", diff.AddedParameters[0].Name)
+		require.Len(t, diff.RemovedParameters, 1)
+		require.Equal(t, "legacy", diff.RemovedParameters[0].Name)
+		require.Len(t, diff.ChangedParameters, 1)
+		require.Equal(t, "region", diff.ChangedParameters[0].Name)
+		require.Equal(t, "Region", diff.ChangedParameters[0].Old.Description)
+		require.Equal(t, "Cloud region", diff.ChangedParameters[0].New.Description)
+		require.NotEmpty(t, diff.Files)
+		require.Empty(t, diff.Metadata)
+	})
+
+	t.Run("SameVersion", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		diff, err := client.TemplateVersionDiff(ctx, version.ID, version.ID)
+		require.NoError(t, err)
+		require.Empty(t, diff.AddedParameters)
+		require.Empty(t, diff.RemovedParameters)
+		require.Empty(t, diff.ChangedParameters)
+		require.NotNil(t, diff.Files)
+		require.Empty(t, diff.Files)
+		require.Empty(t, diff.Metadata)
+	})
+
+	t.Run("BaseNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateVersionDiff(ctx, updated.ID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestTemplateVersionArchive(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	data, err := echo.Tar(&echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionApply: echo.ApplyComplete,
+	})
+	require.NoError(t, err)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	file, err := client.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(data))
+	require.NoError(t, err)
+	version, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+		StorageMethod: codersdk.ProvisionerStorageMethodFile,
+		FileID:        file.ID,
+		Provisioner:   codersdk.ProvisionerTypeEcho,
+	})
+	require.NoError(t, err)
+
+	archive, err := client.TemplateVersionArchive(ctx, version.ID)
+	require.NoError(t, err)
+	require.Equal(t, data, archive)
+
+	// Members can't read the source code of templates they can't edit.
+	_, err = member.TemplateVersionArchive(ctx, version.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestTemplateArchiveVersions(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,1101 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/twofactor"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get passkeys by user
+// @ID get-passkeys-by-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {array} codersdk.Passkey
+// @Router /users/{user}/passkeys [get]
+func (api *API) passkeys(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	passkeys, err := api.Database.GetPasskeysByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.Passkey, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		converted = append(converted, convertPasskey(passkey))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// Passkeys are registered by their owner, since registering one requires the
+// authenticator to be present.
+//
+// @Summary Start passkey registration
+// @ID start-passkey-registration
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.PasskeyRegistration
+// @Router /users/{user}/passkeys/challenge [post]
+func (api *API) postPasskeyChallenge(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only register passkeys for yourself.",
+		})
+		return
+	}
+
+	passkeys, err := api.Database.GetPasskeysByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	existing := make([][]byte, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		existing = append(existing, passkey.CredentialID)
+	}
+
+	challenge, err := api.insertTwoFactorChallenge(ctx, user.ID, database.TwoFactorChallengePurposeRegisterPasskey)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating challenge.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.PasskeyRegistration{
+		ChallengeID: challenge.ID,
+		ExpiresAt:   challenge.ExpiresAt,
+		Options:     rp.CreationOptions(challenge.Challenge, user.ID, user.Username, user.Name, existing),
+	})
+}
+
+// @Summary Create passkey
+// @ID create-passkey
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreatePasskeyRequest true "Create passkey request"
+// @Success 201 {object} codersdk.Passkey
+// @Router /users/{user}/passkeys [post]
+func (api *API) postPasskey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Passkey](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only register passkeys for yourself.",
+		})
+		return
+	}
+
+	var req codersdk.CreatePasskeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	challenge, ok := api.useTwoFactorChallenge(ctx, rw, req.ChallengeID, database.TwoFactorChallengePurposeRegisterPasskey)
+	if !ok {
+		return
+	}
+	if challenge.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The challenge is invalid or expired. Start the registration again.",
+		})
+		return
+	}
+
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	cred, err := rp.VerifyRegistration(challenge.Challenge, req)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	passkey, err := api.Database.InsertPasskey(ctx, database.InsertPasskeyParams{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		Name:         req.Name,
+		CredentialID: cred.ID,
+		PublicKey:    cred.PublicKey,
+		SignCount:    int64(cred.SignCount),
+		CreatedAt:    dbtime.Now(),
+	})
+	if database.IsUniqueViolation(err, database.UniquePasskeysCredentialIDKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "This passkey is already registered.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = passkey
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertPasskey(passkey))
+}
+
+// Admins can delete the passkeys of users who lost them.
+//
+// @Summary Delete passkey
+// @ID delete-passkey
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param passkey path string true "Passkey ID" format(uuid)
+// @Success 204
+// @Router /users/{user}/passkeys/{passkey} [delete]
+func (api *API) deletePasskey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Passkey](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	passkeyID, ok := httpmw.ParseUUIDParam(rw, r, "passkey")
+	if !ok {
+		return
+	}
+	passkey, err := api.Database.GetPasskeyByID(ctx, passkeyID)
+	if httpapi.Is404Error(err) || (err == nil && passkey.UserID != user.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = passkey
+
+	err = api.Database.DeletePasskeyByID(ctx, passkey.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// @Summary Get user two-factor status
+// @ID get-user-two-factor-status
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserTwoFactor
+// @Router /users/{user}/two-factor [get]
+func (api *API) userTwoFactor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	passkeys, err := api.Database.GetPasskeysByUserID(ctx, user.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	remaining, err := api.Database.CountUnusedUserRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error counting recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	policy, found, err := api.twoFactorPolicy(ctx, append([]string{rbac.RoleMember()}, user.RBACRoles...))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching two-factor policies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	status := codersdk.UserTwoFactor{
+		Enabled:                len(passkeys) > 0,
+		Required:               found,
+		RecoveryCodesRemaining: remaining,
+	}
+	if found {
+		status.EnforceAfter = &policy.EnforceAfter
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, status)
+}
+
+// Generating recovery codes invalidates the previous ones. The codes are only
+// returned once.
+//
+// @Summary Regenerate recovery codes
+// @ID regenerate-recovery-codes
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 201 {object} codersdk.RecoveryCodes
+// @Router /users/{user}/two-factor/recovery-codes [post]
+func (api *API) postRecoveryCodes(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		user   = httpmw.UserParam(r)
+		apiKey = httpmw.APIKey(r)
+	)
+	aReq, commitAudit := audit.InitRequest[database.User](rw, &audit.RequestParams{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: json.RawMessage(`{"recovery_codes":"regenerated"}`),
+	})
+	defer commitAudit()
+	aReq.Old = user
+
+	if apiKey.UserID != user.ID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can only generate recovery codes for yourself.",
+		})
+		return
+	}
+
+	passkeys, err := api.Database.GetPasskeysByUserID(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if len(passkeys) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Register a passkey before generating recovery codes.",
+		})
+		return
+	}
+
+	codes, hashes, err := twofactor.GenerateRecoveryCodes()
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = api.Database.InTx(func(tx database.Store) error {
+		err := tx.DeleteUserRecoveryCodesByUserID(ctx, user.ID)
+		if err != nil {
+			return xerrors.Errorf("delete recovery codes: %w", err)
+		}
+		now := dbtime.Now()
+		for _, hash := range hashes {
+			err = tx.InsertUserRecoveryCode(ctx, database.InsertUserRecoveryCodeParams{
+				ID:         uuid.New(),
+				UserID:     user.ID,
+				HashedCode: hash,
+				CreatedAt:  now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert recovery code: %w", err)
+			}
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving recovery codes.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The user itself doesn't change, but the audit log should show that the
+	// codes were regenerated.
+	aReq.New = user
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.RecoveryCodes{Codes: codes})
+}
+
+// Answers the second factor challenge returned by a password login.
+//
+// @Summary Log in user with second factor
+// @ID log-in-user-with-second-factor
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithSecondFactorRequest true "Second factor login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/login/second-factor [post]
+func (api *API) postLoginSecondFactor(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *api.Auditor.Load(),
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.LoginWithSecondFactorRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if (req.Passkey == nil) == (req.RecoveryCode == "") {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Either a passkey or a recovery code must be provided.",
+		})
+		return
+	}
+
+	challenge, ok := api.useTwoFactorChallenge(ctx, rw, req.ChallengeID, database.TwoFactorChallengePurposeLogin)
+	if !ok {
+		return
+	}
+	aReq.UserID = challenge.UserID
+
+	//nolint:gocritic // In order to login, we need to get the user first!
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), challenge.UserID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	//nolint:gocritic // System needs to fetch user roles in order to login user.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	// The user may have been suspended or converted since the password was
+	// checked.
+	if roles.Status != database.UserStatusActive || !passwordLoginType(user.LoginType) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account can no longer log in with a password. Contact an admin for assistance.",
+		})
+		return
+	}
+
+	if req.Passkey != nil {
+		ok = api.verifyPasskeyAssertion(ctx, rw, challenge, *req.Passkey)
+	} else {
+		ok = api.useRecoveryCode(ctx, rw, user.ID, req.RecoveryCode)
+	}
+	if !ok {
+		return
+	}
+
+	cookie, key, ok := api.createPasswordLoginSession(ctx, rw, r, user, roles)
+	if !ok {
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// Registers the first passkey of a user whose password login returned a
+// passkey enrollment, and finishes the login.
+//
+// @Summary Log in user with passkey enrollment
+// @ID log-in-user-with-passkey-enrollment
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.CreatePasskeyRequest true "Create passkey request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/login/passkey-enrollment [post]
+func (api *API) postLoginPasskeyEnrollment(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	var req codersdk.CreatePasskeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	challenge, ok := api.useTwoFactorChallenge(ctx, rw, req.ChallengeID, database.TwoFactorChallengePurposeEnrollPasskey)
+	if !ok {
+		return
+	}
+	aReq.UserID = challenge.UserID
+
+	//nolint:gocritic // In order to login, we need to get the user first!
+	user, err := api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), challenge.UserID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	//nolint:gocritic // System needs to fetch user roles in order to login user.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		logger.Error(ctx, "unable to fetch authorization user roles", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error.",
+		})
+		return
+	}
+	// The user may have been suspended or converted since the password was
+	// checked.
+	if roles.Status != database.UserStatusActive || !passwordLoginType(user.LoginType) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account can no longer log in with a password. Contact an admin for assistance.",
+		})
+		return
+	}
+
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	cred, err := rp.VerifyRegistration(challenge.Challenge, req)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	//nolint:gocritic // The user isn't logged in yet.
+	passkey, err := api.Database.InsertPasskey(dbauthz.AsSystemRestricted(ctx), database.InsertPasskeyParams{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		Name:         req.Name,
+		CredentialID: cred.ID,
+		PublicKey:    cred.PublicKey,
+		SignCount:    int64(cred.SignCount),
+		CreatedAt:    dbtime.Now(),
+	})
+	if database.IsUniqueViolation(err, database.UniquePasskeysCredentialIDKey) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "This passkey is already registered.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating passkey.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.Passkey]{
+		Audit:     auditor,
+		Log:       api.Logger,
+		UserID:    user.ID,
+		RequestID: httpmw.RequestID(r),
+		Status:    http.StatusCreated,
+		Action:    database.AuditActionCreate,
+		New:       passkey,
+		IP:        r.RemoteAddr,
+	})
+
+	cookie, key, ok := api.createPasswordLoginSession(ctx, rw, r, user, roles)
+	if !ok {
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+func (api *API) verifyPasskeyAssertion(ctx context.Context, rw http.ResponseWriter, challenge database.TwoFactorChallenge, assertion codersdk.PasskeyAssertion) bool {
+	credentialID, err := twofactor.DecodeBase64URL(assertion.CredentialID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid credential ID.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	//nolint:gocritic // The user isn't logged in yet.
+	passkeys, err := api.Database.GetPasskeysByUserID(dbauthz.AsSystemRestricted(ctx), challenge.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching passkeys.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	var (
+		passkey database.Passkey
+		found   bool
+	)
+	for _, p := range passkeys {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			passkey, found = p, true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Unknown passkey.",
+		})
+		return false
+	}
+
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	signCount, err := rp.VerifyAssertion(challenge.Challenge, twofactor.Credential{
+		ID:        passkey.CredentialID,
+		PublicKey: passkey.PublicKey,
+		SignCount: uint32(passkey.SignCount),
+	}, assertion)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Passkey verification failed.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+
+	//nolint:gocritic // The user isn't logged in yet.
+	err = api.Database.UpdatePasskeyUsage(dbauthz.AsSystemRestricted(ctx), database.UpdatePasskeyUsageParams{
+		ID:         passkey.ID,
+		SignCount:  int64(signCount),
+		LastUsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating passkey.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func (api *API) useRecoveryCode(ctx context.Context, rw http.ResponseWriter, userID uuid.UUID, code string) bool {
+	//nolint:gocritic // The user isn't logged in yet.
+	codes, err := api.Database.GetUnusedUserRecoveryCodes(dbauthz.AsSystemRestricted(ctx), userID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching recovery codes.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	var (
+		match database.UserRecoveryCode
+		found bool
+	)
+	for _, c := range codes {
+		ok, err := twofactor.CompareRecoveryCode(c.HashedCode, code)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error comparing recovery codes.",
+				Detail:  err.Error(),
+			})
+			return false
+		}
+		if ok {
+			match, found = c, true
+			break
+		}
+	}
+	if !found {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid or already used recovery code.",
+		})
+		return false
+	}
+
+	// Marking the code as used only succeeds once, even if the same code is
+	// entered in two logins at the same time.
+	//nolint:gocritic // The user isn't logged in yet.
+	_, err = api.Database.UseUserRecoveryCode(dbauthz.AsSystemRestricted(ctx), database.UseUserRecoveryCodeParams{
+		UsedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+		ID:     match.ID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid or already used recovery code.",
+		})
+		return false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error using recovery code.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// secondFactorLogin returns the response that holds back the session of a user
+// who logged in with a password until they answer a second factor challenge,
+// or register a passkey once a policy requires one. nil is returned if the user
+// can get a session right away.
+func (api *API) secondFactorLogin(ctx context.Context, user database.User, roles []string) (*codersdk.LoginWithPasswordResponse, error) {
+	challenge, err := api.secondFactorChallenge(ctx, user)
+	if err != nil {
+		return nil, xerrors.Errorf("create second factor challenge: %w", err)
+	}
+	if challenge != nil {
+		return &codersdk.LoginWithPasswordResponse{SecondFactor: challenge}, nil
+	}
+	enforced, err := api.twoFactorEnforced(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+	if !enforced {
+		return nil, nil
+	}
+	// The user has no passkey yet, so they register one to finish the login.
+	enrollment, err := api.passkeyEnrollment(ctx, user)
+	if err != nil {
+		return nil, xerrors.Errorf("create passkey enrollment: %w", err)
+	}
+	return &codersdk.LoginWithPasswordResponse{PasskeyEnrollment: enrollment}, nil
+}
+
+// passwordLoginType returns whether users of the login type log in with a
+// password, which a second factor is asked for after.
+func passwordLoginType(loginType database.LoginType) bool {
+	return loginType == database.LoginTypePassword || loginType == database.LoginTypeLDAP
+}
+
+// secondFactorChallenge returns the challenge a user has to answer to finish
+// a password login, or nil if the user has no passkeys.
+func (api *API) secondFactorChallenge(ctx context.Context, user database.User) (*codersdk.SecondFactorChallenge, error) {
+	//nolint:gocritic // The user isn't logged in yet.
+	passkeys, err := api.Database.GetPasskeysByUserID(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		return nil, xerrors.Errorf("get passkeys: %w", err)
+	}
+	if len(passkeys) == 0 {
+		return nil, nil
+	}
+	credentialIDs := make([][]byte, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		credentialIDs = append(credentialIDs, passkey.CredentialID)
+	}
+
+	challenge, err := api.insertTwoFactorChallenge(ctx, user.ID, database.TwoFactorChallengePurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	return &codersdk.SecondFactorChallenge{
+		ChallengeID: challenge.ID,
+		ExpiresAt:   challenge.ExpiresAt,
+		Options:     rp.RequestOptions(challenge.Challenge, credentialIDs),
+	}, nil
+}
+
+func (api *API) insertTwoFactorChallenge(ctx context.Context, userID uuid.UUID, purpose database.TwoFactorChallengePurpose) (database.TwoFactorChallenge, error) {
+	raw, err := twofactor.NewChallenge()
+	if err != nil {
+		return database.TwoFactorChallenge{}, err
+	}
+	now := dbtime.Now()
+	//nolint:gocritic // Challenges are not owned by users.
+	challenge, err := api.Database.InsertTwoFactorChallenge(dbauthz.AsSystemRestricted(ctx), database.InsertTwoFactorChallengeParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		Challenge: raw,
+		CreatedAt: now,
+		ExpiresAt: now.Add(twofactor.ChallengeTimeout),
+	})
+	if err != nil {
+		return database.TwoFactorChallenge{}, xerrors.Errorf("insert challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// useTwoFactorChallenge deletes a challenge, so it can't be answered twice,
+// and checks that it is still valid. If 'false' is returned, the appropriate
+// error was written to the ResponseWriter.
+func (api *API) useTwoFactorChallenge(ctx context.Context, rw http.ResponseWriter, id uuid.UUID, purpose database.TwoFactorChallengePurpose) (database.TwoFactorChallenge, bool) {
+	//nolint:gocritic // Challenges are not owned by users.
+	challenge, err := api.Database.DeleteTwoFactorChallengeByID(dbauthz.AsSystemRestricted(ctx), id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching challenge.",
+			Detail:  err.Error(),
+		})
+		return database.TwoFactorChallenge{}, false
+	}
+	if err != nil || challenge.Purpose != purpose || dbtime.Now().After(challenge.ExpiresAt) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "The challenge is invalid or expired. Try again.",
+		})
+		return database.TwoFactorChallenge{}, false
+	}
+	return challenge, true
+}
+
+// twoFactorEnforced returns true if a policy for one of the roles requires a
+// second factor now.
+func (api *API) twoFactorEnforced(ctx context.Context, roles []string) (bool, error) {
+	policy, found, err := api.twoFactorPolicy(ctx, roles)
+	if err != nil {
+		return false, err
+	}
+	return found && dbtime.Now().After(policy.EnforceAfter), nil
+}
+
+// passkeyEnrollment returns the registration a user without a passkey has to
+// complete to finish a password login, once a policy requires a second
+// factor.
+func (api *API) passkeyEnrollment(ctx context.Context, user database.User) (*codersdk.PasskeyRegistration, error) {
+	challenge, err := api.insertTwoFactorChallenge(ctx, user.ID, database.TwoFactorChallengePurposeEnrollPasskey)
+	if err != nil {
+		return nil, err
+	}
+	rp := twofactor.RelyingPartyFromURL(api.AccessURL)
+	return &codersdk.PasskeyRegistration{
+		ChallengeID: challenge.ID,
+		ExpiresAt:   challenge.ExpiresAt,
+		Options:     rp.CreationOptions(challenge.Challenge, user.ID, user.Username, user.Name, nil),
+	}, nil
+}
+
+// twoFactorPolicy returns the policy for the given roles that is enforced
+// first, if any.
+func (api *API) twoFactorPolicy(ctx context.Context, roles []string) (database.TwoFactorPolicy, bool, error) {
+	//nolint:gocritic // Users need to know the policies that apply to them,
+	// including before they log in.
+	policies, err := api.Database.GetTwoFactorPolicies(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return database.TwoFactorPolicy{}, false, xerrors.Errorf("get two-factor policies: %w", err)
+	}
+	var (
+		earliest database.TwoFactorPolicy
+		found    bool
+	)
+	for _, policy := range policies {
+		if !slices.Contains(roles, policy.Role) {
+			continue
+		}
+		if !found || policy.EnforceAfter.Before(earliest.EnforceAfter) {
+			earliest, found = policy, true
+		}
+	}
+	return earliest, found, nil
+}
+
+// @Summary Get two-factor policies
+// @ID get-two-factor-policies
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Authorization
+// @Success 200 {array} codersdk.TwoFactorPolicy
+// @Router /two-factor/policies [get]
+func (api *API) twoFactorPolicies(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	policies, err := api.Database.GetTwoFactorPolicies(ctx)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching two-factor policies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	converted := make([]codersdk.TwoFactorPolicy, 0, len(policies))
+	for _, policy := range policies {
+		converted = append(converted, convertTwoFactorPolicy(policy))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// Members of the role have until enforce_after to register a passkey. After
+// that, their password logins are rejected until they have one.
+//
+// @Summary Upsert two-factor policy
+// @ID upsert-two-factor-policy
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param role path string true "Site role name"
+// @Param request body codersdk.UpsertTwoFactorPolicyRequest true "Upsert two-factor policy request"
+// @Success 200 {object} codersdk.TwoFactorPolicy
+// @Router /two-factor/policies/{role} [put]
+func (api *API) putTwoFactorPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = chi.URLParam(r, "role")
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TwoFactorPolicy](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.UpsertTwoFactorPolicyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if !api.validateTwoFactorPolicyRole(ctx, rw, role) {
+		return
+	}
+
+	old, err := api.Database.GetTwoFactorPolicyByRole(ctx, role)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching two-factor policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		aReq.Action = database.AuditActionCreate
+	}
+	aReq.Old = old
+
+	now := dbtime.Now()
+	policy, err := api.Database.UpsertTwoFactorPolicy(ctx, database.UpsertTwoFactorPolicyParams{
+		ID:           uuid.New(),
+		Role:         role,
+		EnforceAfter: req.EnforceAfter,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error saving two-factor policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = policy
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTwoFactorPolicy(policy))
+}
+
+// @Summary Delete two-factor policy
+// @ID delete-two-factor-policy
+// @Security CoderSessionToken
+// @Tags Authorization
+// @Param role path string true "Site role name"
+// @Success 204
+// @Router /two-factor/policies/{role} [delete]
+func (api *API) deleteTwoFactorPolicy(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		role              = chi.URLParam(r, "role")
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TwoFactorPolicy](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	policy, err := api.Database.GetTwoFactorPolicyByRole(ctx, role)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching two-factor policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = policy
+
+	err = api.Database.DeleteTwoFactorPolicyByRole(ctx, role)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting two-factor policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
+// validateTwoFactorPolicyRole checks that policies are only created for site
+// roles, since logins are not scoped to an organization.
+func (api *API) validateTwoFactorPolicyRole(ctx context.Context, rw http.ResponseWriter, role string) bool {
+	if _, ok := rbac.IsOrgRole(role); ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Two-factor policies can only be set for site roles.",
+		})
+		return false
+	}
+	if _, err := rbac.RoleByName(role); err == nil {
+		return true
+	}
+	//nolint:gocritic // Looking up whether the role exists.
+	customRoles, err := api.Database.CustomRolesByName(dbauthz.AsSystemRestricted(ctx), []string{role})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching roles.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	if len(customRoles) == 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Role not found.",
+			Detail:  fmt.Sprintf("No site role is named %q.", role),
+		})
+		return false
+	}
+	return true
+}
+
+func convertPasskey(passkey database.Passkey) codersdk.Passkey {
+	converted := codersdk.Passkey{
+		ID:        passkey.ID,
+		UserID:    passkey.UserID,
+		Name:      passkey.Name,
+		CreatedAt: passkey.CreatedAt,
+	}
+	if passkey.LastUsedAt.Valid {
+		converted.LastUsedAt = &passkey.LastUsedAt.Time
+	}
+	return converted
+}
+
+func convertTwoFactorPolicy(policy database.TwoFactorPolicy) codersdk.TwoFactorPolicy {
+	return codersdk.TwoFactorPolicy{
+		ID:           policy.ID,
+		Role:         policy.Role,
+		EnforceAfter: policy.EnforceAfter,
+		CreatedAt:    policy.CreatedAt,
+		UpdatedAt:    policy.UpdatedAt,
+	}
+}
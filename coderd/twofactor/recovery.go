@@ -0,0 +1,47 @@
+package twofactor
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/userpassword"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// RecoveryCodeCount is how many recovery codes users get at a time.
+const RecoveryCodeCount = 10
+
+// GenerateRecoveryCodes returns new recovery codes, and the hashes to store
+// them as. Codes look like "ab3de-fg7hk".
+func GenerateRecoveryCodes() (codes []string, hashes [][]byte, err error) {
+	codes = make([]string, 0, RecoveryCodeCount)
+	hashes = make([][]byte, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		code, err := cryptorand.StringCharset(cryptorand.Human, 10)
+		if err != nil {
+			return nil, nil, xerrors.Errorf("generate recovery code: %w", err)
+		}
+		code = code[:5] + "-" + code[5:]
+		hash, err := userpassword.Hash(normalizeRecoveryCode(code))
+		if err != nil {
+			return nil, nil, xerrors.Errorf("hash recovery code: %w", err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, []byte(hash))
+	}
+	return codes, hashes, nil
+}
+
+// CompareRecoveryCode checks a recovery code against the hash it is stored
+// as. Codes are hashed like passwords, with a salt, so a leaked database
+// doesn't reveal them. They are normalized first, so they can be typed in any
+// case and with or without the dash.
+func CompareRecoveryCode(hash []byte, code string) (bool, error) {
+	return userpassword.Compare(string(hash), normalizeRecoveryCode(code))
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
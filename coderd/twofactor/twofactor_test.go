@@ -0,0 +1,153 @@
+package twofactor_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/twofactor"
+	"github.com/coder/coder/v2/coderd/twofactor/twofactortest"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestPasskey(t *testing.T) {
+	t.Parallel()
+
+	accessURL, err := url.Parse("https://coder.example.com:8443")
+	require.NoError(t, err)
+	rp := twofactor.RelyingPartyFromURL(accessURL)
+	require.Equal(t, "coder.example.com", rp.ID)
+	require.Equal(t, "https://coder.example.com:8443", rp.Origin)
+
+	register := func(t *testing.T, authenticator *twofactortest.Authenticator) twofactor.Credential {
+		t.Helper()
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		registration := codersdk.PasskeyRegistration{
+			ChallengeID: uuid.New(),
+			Options:     rp.CreationOptions(challenge, uuid.New(), "alice", "", nil),
+		}
+		cred, err := rp.VerifyRegistration(challenge, authenticator.Register(t, registration, rp.Origin, "laptop"))
+		require.NoError(t, err)
+		return cred
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+		authenticator := twofactortest.New(t)
+		cred := register(t, authenticator)
+		require.Equal(t, authenticator.CredentialID, cred.ID)
+
+		for i := 0; i < 2; i++ {
+			challenge, err := twofactor.NewChallenge()
+			require.NoError(t, err)
+			opts := rp.RequestOptions(challenge, [][]byte{cred.ID})
+			count, err := rp.VerifyAssertion(challenge, cred, authenticator.Assert(t, opts, rp.Origin))
+			require.NoError(t, err)
+			require.Equal(t, authenticator.SignCount, count)
+			cred.SignCount = count
+		}
+	})
+
+	t.Run("WrongOrigin", func(t *testing.T) {
+		t.Parallel()
+		authenticator := twofactortest.New(t)
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		registration := codersdk.PasskeyRegistration{
+			Options: rp.CreationOptions(challenge, uuid.New(), "alice", "Alice", nil),
+		}
+		_, err = rp.VerifyRegistration(challenge, authenticator.Register(t, registration, "https://evil.example.com", "laptop"))
+		require.ErrorContains(t, err, "origin")
+	})
+
+	t.Run("WrongRelyingParty", func(t *testing.T) {
+		t.Parallel()
+		authenticator := twofactortest.New(t)
+		cred := register(t, authenticator)
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		opts := rp.RequestOptions(challenge, [][]byte{cred.ID})
+		opts.RelyingPartyID = "evil.example.com"
+		_, err = rp.VerifyAssertion(challenge, cred, authenticator.Assert(t, opts, rp.Origin))
+		require.ErrorContains(t, err, "RP Hash mismatch")
+	})
+
+	t.Run("WrongChallenge", func(t *testing.T) {
+		t.Parallel()
+		authenticator := twofactortest.New(t)
+		cred := register(t, authenticator)
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		other, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		opts := rp.RequestOptions(other, [][]byte{cred.ID})
+		_, err = rp.VerifyAssertion(challenge, cred, authenticator.Assert(t, opts, rp.Origin))
+		require.ErrorContains(t, err, "challenge")
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		t.Parallel()
+		cred := register(t, twofactortest.New(t))
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		opts := rp.RequestOptions(challenge, [][]byte{cred.ID})
+		other := twofactortest.New(t)
+		other.CredentialID = cred.ID
+		_, err = rp.VerifyAssertion(challenge, cred, other.Assert(t, opts, rp.Origin))
+		require.ErrorContains(t, err, "signature")
+	})
+
+	t.Run("WrongCredential", func(t *testing.T) {
+		t.Parallel()
+		cred := register(t, twofactortest.New(t))
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		opts := rp.RequestOptions(challenge, [][]byte{cred.ID})
+		_, err = rp.VerifyAssertion(challenge, cred, twofactortest.New(t).Assert(t, opts, rp.Origin))
+		require.ErrorContains(t, err, "another credential")
+	})
+
+	t.Run("ClonedAuthenticator", func(t *testing.T) {
+		t.Parallel()
+		authenticator := twofactortest.New(t)
+		cred := register(t, authenticator)
+		cred.SignCount = 5
+		authenticator.SignCount = 4
+		challenge, err := twofactor.NewChallenge()
+		require.NoError(t, err)
+		opts := rp.RequestOptions(challenge, [][]byte{cred.ID})
+		_, err = rp.VerifyAssertion(challenge, cred, authenticator.Assert(t, opts, rp.Origin))
+		require.ErrorContains(t, err, "cloned")
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, hashes, err := twofactor.GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, twofactor.RecoveryCodeCount)
+	require.Len(t, hashes, twofactor.RecoveryCodeCount)
+	for i, code := range codes {
+		require.Regexp(t, `^[a-z0-9]{5}-[a-z0-9]{5}$`, code)
+		ok, err := twofactor.CompareRecoveryCode(hashes[i], code)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	// Codes can be typed in any case, with or without the dash.
+	hash := hashes[0]
+	code := codes[0]
+	for _, typed := range []string{strings.ToUpper(code), strings.ReplaceAll(code, "-", ""), strings.ReplaceAll(code, "-", " ")} {
+		ok, err := twofactor.CompareRecoveryCode(hash, typed)
+		require.NoError(t, err)
+		require.True(t, ok, typed)
+	}
+	ok, err := twofactor.CompareRecoveryCode(hash, codes[1])
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,122 @@
+// Package twofactortest provides a fake WebAuthn authenticator for tests.
+package twofactortest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Authenticator is a fake passkey. It answers registration and login
+// challenges the way a browser and a security key would together.
+type Authenticator struct {
+	Key          *ecdsa.PrivateKey
+	CredentialID []byte
+	// SignCount is incremented on every assertion. Set it back to simulate a
+	// cloned authenticator.
+	SignCount uint32
+}
+
+func New(t *testing.T) *Authenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &Authenticator{
+		Key:          key,
+		CredentialID: id,
+	}
+}
+
+// Register answers a registration challenge. The origin is the origin the
+// browser would report, usually the access URL of the deployment.
+func (a *Authenticator) Register(t *testing.T, registration codersdk.PasskeyRegistration, origin, name string) codersdk.CreatePasskeyRequest {
+	t.Helper()
+	opts := registration.Options
+
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.Key.X.FillBytes(x)
+	a.Key.Y.FillBytes(y)
+	publicKey, err := cbor.Marshal(map[int64]any{
+		1:  2,  // Key type: EC2.
+		3:  -7, // Algorithm: ES256.
+		-1: 1,  // Curve: P-256.
+		-2: x,
+		-3: y,
+	})
+	require.NoError(t, err)
+
+	authData := a.authenticatorData(opts.RelyingParty.ID, 0x41) // User present, attested data.
+	authData = append(authData, make([]byte, 16)...)            // AAGUID.
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.CredentialID)))
+	authData = append(authData, a.CredentialID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := cbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	require.NoError(t, err)
+
+	return codersdk.CreatePasskeyRequest{
+		ChallengeID:       registration.ChallengeID,
+		Name:              name,
+		ClientDataJSON:    encode(clientData(t, "webauthn.create", opts.Challenge, origin)),
+		AttestationObject: encode(attestation),
+	}
+}
+
+// Assert answers a login challenge.
+func (a *Authenticator) Assert(t *testing.T, opts codersdk.PasskeyRequestOptions, origin string) codersdk.PasskeyAssertion {
+	t.Helper()
+	a.SignCount++
+	authData := a.authenticatorData(opts.RelyingPartyID, 0x01) // User present.
+	data := clientData(t, "webauthn.get", opts.Challenge, origin)
+	clientDataHash := sha256.Sum256(data)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.Key, digest[:])
+	require.NoError(t, err)
+
+	return codersdk.PasskeyAssertion{
+		CredentialID:      encode(a.CredentialID),
+		ClientDataJSON:    encode(data),
+		AuthenticatorData: encode(authData),
+		Signature:         encode(signature),
+	}
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, a.SignCount)
+}
+
+func clientData(t *testing.T, typ, challenge, origin string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,261 @@
+// Package twofactor implements the second factor of password logins: WebAuthn
+// passkeys, and recovery codes for when a passkey is lost.
+//
+// Responses from authenticators are verified with go-webauthn. Attestation
+// is not requested, since any authenticator the user owns is accepted, and
+// user verification is not required, since the password was already checked.
+package twofactor
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// ChallengeTimeout is how long users have to answer a challenge.
+const ChallengeTimeout = 5 * time.Minute
+
+const challengeSize = 32
+
+// supportedAlgorithms are the COSE algorithms of the public keys passkeys may
+// have, in order of preference.
+var supportedAlgorithms = []webauthncose.COSEAlgorithmIdentifier{
+	webauthncose.AlgES256,
+	webauthncose.AlgEdDSA,
+	webauthncose.AlgRS256,
+}
+
+// RelyingParty is the deployment passkeys are registered with. Browsers only
+// let a site use passkeys registered with its own origin.
+type RelyingParty struct {
+	// ID is the hostname of the deployment.
+	ID   string
+	Name string
+	// Origin is the scheme, hostname and port the dashboard is served from.
+	Origin string
+}
+
+// RelyingPartyFromURL returns the relying party of a deployment served from
+// the access URL.
+func RelyingPartyFromURL(accessURL *url.URL) RelyingParty {
+	return RelyingParty{
+		ID:     accessURL.Hostname(),
+		Name:   "Coder",
+		Origin: accessURL.Scheme + "://" + accessURL.Host,
+	}
+}
+
+// Credential is a registered passkey.
+type Credential struct {
+	ID []byte
+	// PublicKey is COSE encoded.
+	PublicKey []byte
+	SignCount uint32
+}
+
+// NewChallenge returns random bytes for an authenticator to sign.
+func NewChallenge() ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	_, err := rand.Read(challenge)
+	if err != nil {
+		return nil, xerrors.Errorf("read random bytes: %w", err)
+	}
+	return challenge, nil
+}
+
+// EncodeBase64URL encodes binary values the way browsers do in WebAuthn JSON.
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL decodes binary values sent by browsers. Padding is
+// tolerated, since some client libraries add it.
+func DecodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// CreationOptions returns the options for registering a new passkey for a
+// user. Passkeys the user already has are excluded, so the same authenticator
+// isn't registered twice.
+func (rp RelyingParty) CreationOptions(challenge []byte, userID uuid.UUID, username, displayName string, existing [][]byte) codersdk.PasskeyCreationOptions {
+	if displayName == "" {
+		displayName = username
+	}
+	exclude := make([]codersdk.PasskeyCredentialDescriptor, 0, len(existing))
+	for _, id := range existing {
+		exclude = append(exclude, codersdk.PasskeyCredentialDescriptor{Type: string(protocol.PublicKeyCredentialType), ID: EncodeBase64URL(id)})
+	}
+	params := make([]codersdk.PasskeyCredentialParameter, 0, len(supportedAlgorithms))
+	for _, alg := range supportedAlgorithms {
+		params = append(params, codersdk.PasskeyCredentialParameter{Type: string(protocol.PublicKeyCredentialType), Alg: int64(alg)})
+	}
+	return codersdk.PasskeyCreationOptions{
+		Challenge:    EncodeBase64URL(challenge),
+		RelyingParty: codersdk.PasskeyRelyingParty{ID: rp.ID, Name: rp.Name},
+		User: codersdk.PasskeyUser{
+			ID:          EncodeBase64URL(userID[:]),
+			Name:        username,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   params,
+		Timeout:            ChallengeTimeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: codersdk.PasskeyAuthenticatorSelection{
+			ResidentKey:      string(protocol.ResidentKeyRequirementDiscouraged),
+			UserVerification: string(protocol.VerificationDiscouraged),
+		},
+		Attestation: string(protocol.PreferNoAttestation),
+	}
+}
+
+// RequestOptions returns the options for signing a login challenge with one
+// of the given passkeys.
+func (rp RelyingParty) RequestOptions(challenge []byte, credentialIDs [][]byte) codersdk.PasskeyRequestOptions {
+	allow := make([]codersdk.PasskeyCredentialDescriptor, 0, len(credentialIDs))
+	for _, id := range credentialIDs {
+		allow = append(allow, codersdk.PasskeyCredentialDescriptor{Type: string(protocol.PublicKeyCredentialType), ID: EncodeBase64URL(id)})
+	}
+	return codersdk.PasskeyRequestOptions{
+		Challenge:        EncodeBase64URL(challenge),
+		RelyingPartyID:   rp.ID,
+		Timeout:          ChallengeTimeout.Milliseconds(),
+		AllowCredentials: allow,
+		UserVerification: string(protocol.VerificationDiscouraged),
+	}
+}
+
+// VerifyRegistration checks the response of an authenticator to a
+// registration challenge, and returns the new credential.
+func (rp RelyingParty) VerifyRegistration(challenge []byte, req codersdk.CreatePasskeyRequest) (Credential, error) {
+	clientData, err := DecodeBase64URL(req.ClientDataJSON)
+	if err != nil {
+		return Credential{}, xerrors.Errorf("decode client data: %w", err)
+	}
+	attestationObject, err := DecodeBase64URL(req.AttestationObject)
+	if err != nil {
+		return Credential{}, xerrors.Errorf("decode attestation object: %w", err)
+	}
+
+	raw := protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{Type: string(protocol.PublicKeyCredentialType)},
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientData},
+			AttestationObject:     attestationObject,
+		},
+	}
+	response, err := raw.AttestationResponse.Parse()
+	if err != nil {
+		return Credential{}, protocolError("parse attestation", err)
+	}
+	// The credential ID is taken from the attested credential data, so
+	// clients don't have to send it separately.
+	credentialID := response.AttestationObject.AuthData.AttData.CredentialID
+	raw.ID = EncodeBase64URL(credentialID)
+	raw.RawID = credentialID
+	parsed := &protocol.ParsedCredentialCreationData{
+		ParsedPublicKeyCredential: protocol.ParsedPublicKeyCredential{
+			ParsedCredential: protocol.ParsedCredential{ID: raw.ID, Type: raw.Type},
+			RawID:            credentialID,
+		},
+		Response: *response,
+		Raw:      raw,
+	}
+	err = parsed.Verify(EncodeBase64URL(challenge), false, rp.ID, []string{rp.Origin})
+	if err != nil {
+		return Credential{}, protocolError("verify registration", err)
+	}
+
+	cred, err := webauthn.MakeNewCredential(parsed)
+	if err != nil {
+		return Credential{}, protocolError("create credential", err)
+	}
+	// Reject keys that could never verify a login.
+	_, err = webauthncose.ParsePublicKey(cred.PublicKey)
+	if err != nil {
+		return Credential{}, protocolError("parse public key", err)
+	}
+	return Credential{
+		ID:        cred.ID,
+		PublicKey: cred.PublicKey,
+		SignCount: cred.Authenticator.SignCount,
+	}, nil
+}
+
+// VerifyAssertion checks the response of an authenticator to a login
+// challenge, and returns the new signature counter of the credential.
+func (rp RelyingParty) VerifyAssertion(challenge []byte, cred Credential, assertion codersdk.PasskeyAssertion) (uint32, error) {
+	credentialID, err := DecodeBase64URL(assertion.CredentialID)
+	if err != nil {
+		return 0, xerrors.Errorf("decode credential id: %w", err)
+	}
+	if !bytes.Equal(credentialID, cred.ID) {
+		return 0, xerrors.New("assertion is for another credential")
+	}
+	clientData, err := DecodeBase64URL(assertion.ClientDataJSON)
+	if err != nil {
+		return 0, xerrors.Errorf("decode client data: %w", err)
+	}
+	authData, err := DecodeBase64URL(assertion.AuthenticatorData)
+	if err != nil {
+		return 0, xerrors.Errorf("decode authenticator data: %w", err)
+	}
+	signature, err := DecodeBase64URL(assertion.Signature)
+	if err != nil {
+		return 0, xerrors.Errorf("decode signature: %w", err)
+	}
+
+	parsed, err := protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{
+				ID:   EncodeBase64URL(credentialID),
+				Type: string(protocol.PublicKeyCredentialType),
+			},
+			RawID: credentialID,
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientData},
+			AuthenticatorData:     authData,
+			Signature:             signature,
+		},
+	}.Parse()
+	if err != nil {
+		return 0, protocolError("parse assertion", err)
+	}
+	err = parsed.Verify(EncodeBase64URL(challenge), rp.ID, []string{rp.Origin}, "", false, cred.PublicKey)
+	if err != nil {
+		return 0, protocolError("verify assertion", err)
+	}
+
+	// Authenticators that don't count signatures always send zero. Otherwise
+	// the counter must increase, or the authenticator may have been cloned.
+	authenticator := webauthn.Authenticator{SignCount: cred.SignCount}
+	authenticator.UpdateCounter(parsed.Response.AuthenticatorData.Counter)
+	if authenticator.CloneWarning {
+		return 0, xerrors.New("signature counter did not increase, the authenticator may have been cloned")
+	}
+	return authenticator.SignCount, nil
+}
+
+// protocolError includes the developer information of WebAuthn errors, which
+// says what actually failed to verify.
+func protocolError(action string, err error) error {
+	var perr *protocol.Error
+	if errors.As(err, &perr) && perr.DevInfo != "" {
+		return xerrors.Errorf("%s: %s: %s", action, perr.Details, strings.TrimSpace(perr.DevInfo))
+	}
+	return xerrors.Errorf("%s: %w", action, err)
+}
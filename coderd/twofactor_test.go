@@ -0,0 +1,293 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/twofactor/twofactortest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPasskeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	origin := client.URL.Scheme + "://" + client.URL.Host
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "SomeSecurePassword!",
+	}
+
+	// Passkeys can only be registered by their owner.
+	_, err := client.StartPasskeyRegistration(ctx, memberUser.ID.String())
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Recovery codes need a passkey.
+	_, err = member.RegenerateRecoveryCodes(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	authenticator := twofactortest.New(t)
+	registration, err := member.StartPasskeyRegistration(ctx, codersdk.Me)
+	require.NoError(t, err)
+	passkey, err := member.CreatePasskey(ctx, codersdk.Me, authenticator.Register(t, registration, origin, "laptop"))
+	require.NoError(t, err)
+	require.Equal(t, "laptop", passkey.Name)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypePasskey,
+		ResourceID:   passkey.ID,
+		Action:       database.AuditActionCreate,
+	}))
+
+	// Challenges can only be answered once.
+	_, err = member.CreatePasskey(ctx, codersdk.Me, authenticator.Register(t, registration, origin, "laptop"))
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+	passkeys, err := client.Passkeys(ctx, memberUser.ID.String())
+	require.NoError(t, err)
+	require.Len(t, passkeys, 1)
+	require.Equal(t, passkey.ID, passkeys[0].ID)
+
+	recovery, err := member.RegenerateRecoveryCodes(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.Len(t, recovery.Codes, 10)
+	status, err := member.UserTwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.True(t, status.Enabled)
+	require.False(t, status.Required)
+	require.EqualValues(t, 10, status.RecoveryCodesRemaining)
+
+	t.Run("Passkey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anon := codersdk.New(client.URL)
+
+		resp, err := anon.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		require.Empty(t, resp.SessionToken)
+		require.NotNil(t, resp.SecondFactor)
+
+		resp, err = anon.LoginWithSecondFactor(ctx, codersdk.LoginWithSecondFactorRequest{
+			ChallengeID: resp.SecondFactor.ChallengeID,
+			Passkey:     ptr.Ref(authenticator.Assert(t, resp.SecondFactor.Options, origin)),
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.SessionToken)
+
+		anon.SetSessionToken(resp.SessionToken)
+		me, err := anon.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, memberUser.ID, me.ID)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeApiKey,
+			UserID:       memberUser.ID,
+			Action:       database.AuditActionLogin,
+			StatusCode:   http.StatusCreated,
+		}))
+	})
+
+	t.Run("RecoveryCode", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anon := codersdk.New(client.URL)
+
+		resp, err := anon.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		resp, err = anon.LoginWithSecondFactor(ctx, codersdk.LoginWithSecondFactorRequest{
+			ChallengeID:  resp.SecondFactor.ChallengeID,
+			RecoveryCode: recovery.Codes[0],
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.SessionToken)
+
+		// Recovery codes can only be used once.
+		resp, err = anon.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		_, err = anon.LoginWithSecondFactor(ctx, codersdk.LoginWithSecondFactorRequest{
+			ChallengeID:  resp.SecondFactor.ChallengeID,
+			RecoveryCode: recovery.Codes[0],
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+
+	t.Run("WrongPasskey", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		anon := codersdk.New(client.URL)
+
+		resp, err := anon.LoginWithPassword(ctx, login)
+		require.NoError(t, err)
+		assertion := twofactortest.New(t).Assert(t, resp.SecondFactor.Options, origin)
+		_, err = anon.LoginWithSecondFactor(ctx, codersdk.LoginWithSecondFactorRequest{
+			ChallengeID: resp.SecondFactor.ChallengeID,
+			Passkey:     &assertion,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	})
+}
+
+func TestDeletePasskey(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	origin := client.URL.Scheme + "://" + client.URL.Host
+
+	registration, err := member.StartPasskeyRegistration(ctx, codersdk.Me)
+	require.NoError(t, err)
+	passkey, err := member.CreatePasskey(ctx, codersdk.Me, twofactortest.New(t).Register(t, registration, origin, "laptop"))
+	require.NoError(t, err)
+
+	// Admins can delete the passkeys of users who lost them.
+	err = client.DeletePasskey(ctx, memberUser.ID.String(), passkey.ID)
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypePasskey,
+		ResourceID:   passkey.ID,
+		Action:       database.AuditActionDelete,
+	}))
+
+	resp, err := codersdk.New(client.URL).LoginWithPassword(ctx, codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "SomeSecurePassword!",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.SessionToken)
+	require.Nil(t, resp.SecondFactor)
+
+	err = client.DeletePasskey(ctx, memberUser.ID.String(), passkey.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestTwoFactorPolicies(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	origin := client.URL.Scheme + "://" + client.URL.Host
+	login := codersdk.LoginWithPasswordRequest{
+		Email:    memberUser.Email,
+		Password: "SomeSecurePassword!",
+	}
+
+	// Members can't change policies.
+	_, err := member.UpsertTwoFactorPolicy(ctx, rbac.RoleMember(), codersdk.UpsertTwoFactorPolicyRequest{
+		EnforceAfter: time.Now(),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Policies only apply to site roles.
+	_, err = client.UpsertTwoFactorPolicy(ctx, rbac.RoleOrgAdmin(owner.OrganizationID), codersdk.UpsertTwoFactorPolicyRequest{
+		EnforceAfter: time.Now(),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	_, err = client.UpsertTwoFactorPolicy(ctx, "not-a-role", codersdk.UpsertTwoFactorPolicyRequest{
+		EnforceAfter: time.Now(),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Users have until the policy is enforced to register a passkey.
+	policy, err := client.UpsertTwoFactorPolicy(ctx, rbac.RoleMember(), codersdk.UpsertTwoFactorPolicyRequest{
+		EnforceAfter: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeTwoFactorPolicy,
+		ResourceID:   policy.ID,
+		Action:       database.AuditActionCreate,
+	}))
+	status, err := member.UserTwoFactor(ctx, codersdk.Me)
+	require.NoError(t, err)
+	require.False(t, status.Enabled)
+	require.True(t, status.Required)
+	_, err = codersdk.New(client.URL).LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+
+	policy, err = client.UpsertTwoFactorPolicy(ctx, rbac.RoleMember(), codersdk.UpsertTwoFactorPolicyRequest{
+		EnforceAfter: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeTwoFactorPolicy,
+		ResourceID:   policy.ID,
+		Action:       database.AuditActionWrite,
+	}))
+
+	// Once enforced, users without a passkey have to register one to finish
+	// logging in.
+	anon := codersdk.New(client.URL)
+	resp, err := anon.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+	require.Empty(t, resp.SessionToken)
+	require.NotNil(t, resp.PasskeyEnrollment)
+
+	// A registration challenge doesn't work for enrollment.
+	registration, err := member.StartPasskeyRegistration(ctx, codersdk.Me)
+	require.NoError(t, err)
+	authenticator := twofactortest.New(t)
+	_, err = anon.LoginWithPasskeyEnrollment(ctx, authenticator.Register(t, registration, origin, "laptop"))
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+	resp, err = anon.LoginWithPasskeyEnrollment(ctx, authenticator.Register(t, *resp.PasskeyEnrollment, origin, "laptop"))
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.SessionToken)
+	passkeys, err := client.Passkeys(ctx, memberUser.ID.String())
+	require.NoError(t, err)
+	require.Len(t, passkeys, 1)
+	require.Equal(t, "laptop", passkeys[0].Name)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypePasskey,
+		ResourceID:   passkeys[0].ID,
+		Action:       database.AuditActionCreate,
+	}))
+
+	// From then on the passkey is asked for as usual.
+	resp, err = anon.LoginWithPassword(ctx, login)
+	require.NoError(t, err)
+	require.Empty(t, resp.SessionToken)
+	require.NotNil(t, resp.SecondFactor)
+
+	policies, err := client.TwoFactorPolicies(ctx)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	require.Equal(t, rbac.RoleMember(), policies[0].Role)
+
+	err = client.DeleteTwoFactorPolicy(ctx, rbac.RoleMember())
+	require.NoError(t, err)
+	policies, err = client.TwoFactorPolicies(ctx)
+	require.NoError(t, err)
+	require.Empty(t, policies)
+}
@@ -6,16 +6,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand" //#nosec // this is only used for shuffling an array to pick random jobs to unhang
+	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/google/uuid"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/provisionersdk"
 )
@@ -36,6 +44,15 @@ const (
 	// MaxJobsPerRun is the maximum number of hung jobs that the detector will
 	// terminate in a single run.
 	MaxJobsPerRun = 10
+
+	// StaleDaemonDuration is the duration of time since the last heartbeat of
+	// a provisioner daemon before the jobs it is running are considered
+	// orphaned. Daemons send a heartbeat every minute.
+	StaleDaemonDuration = 3 * time.Minute
+
+	// MaxJobRequeues is the number of times an orphaned template version
+	// import or dry-run is returned to the queue before it is terminated.
+	MaxJobRequeues = 2
 )
 
 // HungJobLogMessages are written to provisioner job logs when a job is hung and
@@ -48,6 +65,35 @@ var HungJobLogMessages = []string{
 	"",
 }
 
+// OrphanedJobLogMessages are written to provisioner job logs when the
+// provisioner daemon running a job stops sending heartbeats and the job is
+// terminated.
+var OrphanedJobLogMessages = []string{
+	"",
+	"====================",
+	"Coder: The provisioner daemon running this build stopped responding. The build will be terminated.",
+	"====================",
+	"",
+}
+
+// TimedOutJobLogMessages are written to provisioner job logs when a workspace
+// build exceeds the max build duration of its template and is terminated.
+func TimedOutJobLogMessages(maxBuildDuration time.Duration) []string {
+	return []string{
+		"",
+		"====================",
+		fmt.Sprintf("Coder: Build has exceeded the max build duration of %s and will be terminated.", maxBuildDuration),
+		"====================",
+		"",
+	}
+}
+
+// requeuedJobLogMessage is written to provisioner job logs when the
+// provisioner daemon running a job stops sending heartbeats and the job is
+// returned to the queue. It is also used to count the times a job has been
+// requeued.
+const requeuedJobLogMessage = "Coder: The provisioner daemon running this job stopped responding. The job has been returned to the queue."
+
 // acquireLockError is returned when the detector fails to acquire a lock and
 // cancels the current run.
 type acquireLockError struct{}
@@ -69,17 +115,23 @@ func (e jobInelligibleError) Error() string {
 }
 
 // Detector automatically detects hung provisioner jobs, sends messages into the
-// build log and terminates them as failed.
+// build log and terminates them as failed. Jobs of provisioner daemons that
+// stopped sending heartbeats are terminated or returned to the queue, and
+// workspace builds that exceed the max build duration of their template are
+// terminated.
 type Detector struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	done   chan struct{}
 
-	db     database.Store
-	pubsub pubsub.Pubsub
-	log    slog.Logger
-	tick   <-chan time.Time
-	stats  chan<- Stats
+	db      database.Store
+	pubsub  pubsub.Pubsub
+	log     slog.Logger
+	tick    <-chan time.Time
+	stats   chan<- Stats
+	auditor *atomic.Pointer[audit.Auditor]
+
+	timedOutBuilds *prometheus.CounterVec
 }
 
 // Stats contains statistics about the last run of the detector.
@@ -87,6 +139,13 @@ type Stats struct {
 	// TerminatedJobIDs contains the IDs of all jobs that were detected as hung and
 	// terminated.
 	TerminatedJobIDs []uuid.UUID
+	// RequeuedJobIDs contains the IDs of all jobs that were returned to the
+	// queue because their provisioner daemon stopped sending heartbeats.
+	RequeuedJobIDs []uuid.UUID
+	// TimedOutJobIDs contains the IDs of all workspace build jobs that were
+	// terminated because they exceeded the max build duration of their
+	// template.
+	TimedOutJobIDs []uuid.UUID
 	// Error is the fatal error that occurred during the last run of the
 	// detector, if any. Error may be set to AcquireLockError if the detector
 	// failed to acquire a lock.
@@ -110,6 +169,26 @@ func New(ctx context.Context, db database.Store, pub pubsub.Pubsub, log slog.Log
 	return d
 }
 
+// WithAuditor will cause the detector to audit workspace builds that it
+// terminates because they exceeded the max build duration of their template.
+func (d *Detector) WithAuditor(auditor *atomic.Pointer[audit.Auditor]) *Detector {
+	d.auditor = auditor
+	return d
+}
+
+// WithPrometheusRegistry will cause the detector to count the workspace builds
+// that it terminates because they exceeded the max build duration of their
+// template.
+func (d *Detector) WithPrometheusRegistry(reg prometheus.Registerer) *Detector {
+	d.timedOutBuilds = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "timed_out_total",
+		Help:      "The number of workspace builds terminated for exceeding the max build duration of their template.",
+	}, []string{"template_name"})
+	return d
+}
+
 // WithStatsChannel will cause Executor to push a RunStats to ch after
 // every tick. This push is blocking, so if ch is not read, the detector will
 // hang. This should only be used in tests.
@@ -169,6 +248,8 @@ func (d *Detector) run(t time.Time) Stats {
 
 	stats := Stats{
 		TerminatedJobIDs: []uuid.UUID{},
+		RequeuedJobIDs:   []uuid.UUID{},
+		TimedOutJobIDs:   []uuid.UUID{},
 		Error:            nil,
 	}
 
@@ -207,6 +288,65 @@ func (d *Detector) run(t time.Time) Stats {
 		stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
 	}
 
+	// Find all workspace builds that have been running for longer than the
+	// max build duration of their template.
+	timedOutJobs, err := d.db.GetTimedOutProvisionerJobs(ctx, t)
+	if err != nil {
+		stats.Error = xerrors.Errorf("get timed out provisioner jobs: %w", err)
+		return stats
+	}
+	if len(timedOutJobs) > MaxJobsPerRun {
+		rand.Shuffle(len(timedOutJobs), func(i, j int) {
+			timedOutJobs[i], timedOutJobs[j] = timedOutJobs[j], timedOutJobs[i]
+		})
+		timedOutJobs = timedOutJobs[:MaxJobsPerRun]
+	}
+	for _, job := range timedOutJobs {
+		log := d.log.With(slog.F("job_id", job.ID))
+
+		err := d.timeOutJob(ctx, log, job.ID, t)
+		if err != nil {
+			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobInelligibleError{})) {
+				log.Error(ctx, "error terminating timed out provisioner job", slog.Error(err))
+			}
+			continue
+		}
+
+		stats.TimedOutJobIDs = append(stats.TimedOutJobIDs, job.ID)
+	}
+
+	// Find all provisioner jobs that are currently running on a provisioner
+	// daemon that has not sent a heartbeat recently. Jobs that are also hung
+	// have been terminated above, and are skipped.
+	orphanedJobs, err := d.db.GetOrphanedProvisionerJobs(ctx, t.Add(-StaleDaemonDuration))
+	if err != nil {
+		stats.Error = xerrors.Errorf("get orphaned provisioner jobs: %w", err)
+		return stats
+	}
+	if len(orphanedJobs) > MaxJobsPerRun {
+		rand.Shuffle(len(orphanedJobs), func(i, j int) {
+			orphanedJobs[i], orphanedJobs[j] = orphanedJobs[j], orphanedJobs[i]
+		})
+		orphanedJobs = orphanedJobs[:MaxJobsPerRun]
+	}
+	for _, job := range orphanedJobs {
+		log := d.log.With(slog.F("job_id", job.ID), slog.F("worker_id", job.WorkerID.UUID))
+
+		requeued, err := reapOrphanedJob(ctx, log, d.db, d.pubsub, job.ID, t)
+		if err != nil {
+			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobInelligibleError{})) {
+				log.Error(ctx, "error reaping orphaned provisioner job", slog.Error(err))
+			}
+			continue
+		}
+
+		if requeued {
+			stats.RequeuedJobIDs = append(stats.RequeuedJobIDs, job.ID)
+		} else {
+			stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
+		}
+	}
+
 	return stats
 }
 
@@ -253,113 +393,381 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 			"threshold", HungJobDuration,
 		)
 
-		// First, get the latest logs from the build so we can make sure
-		// our messages are in the latest stage.
+		lowestLogID, err = insertJobLogs(ctx, db, job, HungJobLogMessages)
+		if err != nil {
+			return xerrors.Errorf("insert logs for hung job: %w", err)
+		}
+
+		return failJob(ctx, db, job, "Coder: Build has been detected as hung for 5 minutes and has been terminated by hang detector.")
+	}, nil)
+	if err != nil {
+		return xerrors.Errorf("in tx: %w", err)
+	}
+
+	return publishJobLogs(pub, jobID, lowestLogID, true)
+}
+
+// timeOutJob terminates a workspace build that has exceeded the max build
+// duration of its template. The provisioner daemon running the build is told
+// to abort it the next time it sends an update.
+func (d *Detector) timeOutJob(ctx context.Context, log slog.Logger, jobID uuid.UUID, now time.Time) error {
+	var (
+		lowestLogID int64
+		build       database.WorkspaceBuild
+		workspace   database.Workspace
+		template    database.Template
+	)
+
+	err := d.db.InTx(func(db database.Store) error {
+		locked, err := db.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("hang-detector:%s", jobID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			// This error is ignored.
+			return acquireLockError{}
+		}
+
+		// Refetch the job and its template while we hold the lock.
+		job, err := db.GetProvisionerJobByID(ctx, jobID)
+		if err != nil {
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+		if !job.StartedAt.Valid {
+			return jobInelligibleError{
+				Err: xerrors.New("job is not started"),
+			}
+		}
+		if job.CompletedAt.Valid {
+			return jobInelligibleError{
+				Err: xerrors.Errorf("job is completed (status %s)", job.JobStatus),
+			}
+		}
+		build, err = db.GetWorkspaceBuildByJobID(ctx, job.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace build: %w", err)
+		}
+		workspace, err = db.GetWorkspaceByID(ctx, build.WorkspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		template, err = db.GetTemplateByID(ctx, workspace.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template: %w", err)
+		}
+		maxBuildDuration := time.Duration(template.MaxBuildDuration)
+		if maxBuildDuration <= 0 || job.StartedAt.Time.After(now.Add(-maxBuildDuration)) {
+			return jobInelligibleError{
+				Err: xerrors.New("job has not exceeded the max build duration"),
+			}
+		}
+
+		log.Warn(ctx, "detected timed out provisioner job, forcefully terminating",
+			slog.F("max_build_duration", maxBuildDuration),
+			slog.F("template_id", template.ID),
+		)
+
+		lowestLogID, err = insertJobLogs(ctx, db, job, TimedOutJobLogMessages(maxBuildDuration))
+		if err != nil {
+			return xerrors.Errorf("insert logs for timed out job: %w", err)
+		}
+
+		err = failJob(ctx, db, job, fmt.Sprintf("Coder: Build has exceeded the max build duration of %s and has been terminated.", maxBuildDuration))
+		if err != nil {
+			return err
+		}
+		build, err = db.GetWorkspaceBuildByJobID(ctx, job.ID)
+		if err != nil {
+			return xerrors.Errorf("get terminated workspace build: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return xerrors.Errorf("in tx: %w", err)
+	}
+
+	if d.timedOutBuilds != nil {
+		d.timedOutBuilds.WithLabelValues(template.Name).Inc()
+	}
+	d.auditTimedOutBuild(ctx, jobID, workspace, build)
+
+	return publishJobLogs(d.pubsub, jobID, lowestLogID, true)
+}
+
+// auditTimedOutBuild audits the failure of a workspace build that exceeded the
+// max build duration of its template, like provisionerd audits failed builds.
+func (d *Detector) auditTimedOutBuild(ctx context.Context, jobID uuid.UUID, workspace database.Workspace, build database.WorkspaceBuild) {
+	if d.auditor == nil {
+		return
+	}
+	auditor := d.auditor.Load()
+	if auditor == nil {
+		return
+	}
+
+	previousBuild, err := d.db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+		WorkspaceID: workspace.ID,
+		BuildNumber: build.BuildNumber - 1,
+	})
+	if err != nil {
+		previousBuild = database.WorkspaceBuild{}
+	}
+	additionalFields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName: workspace.Name,
+		BuildNumber:   strconv.FormatInt(int64(build.BuildNumber), 10),
+		BuildReason:   build.Reason,
+	})
+	if err != nil {
+		d.log.Error(ctx, "marshal resource info for timed out job", slog.Error(err))
+	}
+
+	action := database.AuditActionStart
+	switch build.Transition {
+	case database.WorkspaceTransitionStop:
+		action = database.AuditActionStop
+	case database.WorkspaceTransitionDelete:
+		action = database.AuditActionDelete
+	}
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.WorkspaceBuild]{
+		Audit:            *auditor,
+		Log:              d.log,
+		UserID:           build.InitiatorID,
+		OrganizationID:   workspace.OrganizationID,
+		RequestID:        jobID,
+		Action:           action,
+		Old:              previousBuild,
+		New:              build,
+		Status:           http.StatusRequestTimeout,
+		AdditionalFields: additionalFields,
+	})
+}
+
+// reapOrphanedJob terminates or requeues a job whose provisioner daemon has
+// stopped sending heartbeats. Workspace builds are terminated, because the
+// daemon may have changed resources without reporting the new state.
+// Template version imports and dry-runs are returned to the queue, unless
+// they have already been requeued MaxJobRequeues times. It returns whether
+// the job was requeued.
+func reapOrphanedJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobID uuid.UUID, now time.Time) (bool, error) {
+	var (
+		lowestLogID int64
+		requeued    bool
+		requeuedJob database.ProvisionerJob
+	)
+
+	err := db.InTx(func(db database.Store) error {
+		locked, err := db.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("hang-detector:%s", jobID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			// This error is ignored.
+			return acquireLockError{}
+		}
+
+		// Refetch the job and its daemon while we hold the lock.
+		job, err := db.GetProvisionerJobByID(ctx, jobID)
+		if err != nil {
+			return xerrors.Errorf("get provisioner job: %w", err)
+		}
+		if !job.StartedAt.Valid || !job.WorkerID.Valid {
+			return jobInelligibleError{
+				Err: xerrors.New("job is not started"),
+			}
+		}
+		if job.CompletedAt.Valid {
+			return jobInelligibleError{
+				Err: xerrors.Errorf("job is completed (status %s)", job.JobStatus),
+			}
+		}
+		orphaned, err := db.GetOrphanedProvisionerJobs(ctx, now.Add(-StaleDaemonDuration))
+		if err != nil {
+			return xerrors.Errorf("get orphaned provisioner jobs: %w", err)
+		}
+		if !slices.ContainsFunc(orphaned, func(j database.ProvisionerJob) bool { return j.ID == job.ID }) {
+			return jobInelligibleError{
+				Err: xerrors.New("provisioner daemon has sent a heartbeat recently"),
+			}
+		}
+
 		logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
 			JobID:        job.ID,
 			CreatedAfter: 0,
 		})
 		if err != nil {
-			return xerrors.Errorf("get logs for hung job: %w", err)
-		}
-		logStage := ""
-		if len(logs) != 0 {
-			logStage = logs[len(logs)-1].Stage
-		}
-		if logStage == "" {
-			logStage = "Unknown"
-		}
-
-		// Insert the messages into the build log.
-		insertParams := database.InsertProvisionerJobLogsParams{
-			JobID:     job.ID,
-			CreatedAt: nil,
-			Source:    nil,
-			Level:     nil,
-			Stage:     nil,
-			Output:    nil,
-		}
-		now := dbtime.Now()
-		for i, msg := range HungJobLogMessages {
-			// Set the created at in a way that ensures each message has
-			// a unique timestamp so they will be sorted correctly.
-			insertParams.CreatedAt = append(insertParams.CreatedAt, now.Add(time.Millisecond*time.Duration(i)))
-			insertParams.Level = append(insertParams.Level, database.LogLevelError)
-			insertParams.Stage = append(insertParams.Stage, logStage)
-			insertParams.Source = append(insertParams.Source, database.LogSourceProvisionerDaemon)
-			insertParams.Output = append(insertParams.Output, msg)
-		}
-		newLogs, err := db.InsertProvisionerJobLogs(ctx, insertParams)
-		if err != nil {
-			return xerrors.Errorf("insert logs for hung job: %w", err)
+			return xerrors.Errorf("get logs for orphaned job: %w", err)
 		}
-		lowestLogID = newLogs[0].ID
-
-		// Mark the job as failed.
-		now = dbtime.Now()
-		err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
-			ID:        job.ID,
-			UpdatedAt: now,
-			CompletedAt: sql.NullTime{
-				Time:  now,
-				Valid: true,
-			},
-			Error: sql.NullString{
-				String: "Coder: Build has been detected as hung for 5 minutes and has been terminated by hang detector.",
-				Valid:  true,
-			},
-			ErrorCode: sql.NullString{
-				Valid: false,
-			},
-		})
-		if err != nil {
-			return xerrors.Errorf("mark job as failed: %w", err)
+		requeues := 0
+		for _, l := range logs {
+			if l.Output == requeuedJobLogMessage {
+				requeues++
+			}
 		}
 
-		// If the provisioner job is a workspace build, copy the
-		// provisioner state from the previous build to this workspace
-		// build.
-		if job.Type == database.ProvisionerJobTypeWorkspaceBuild {
-			build, err := db.GetWorkspaceBuildByJobID(ctx, job.ID)
+		if job.Type != database.ProvisionerJobTypeWorkspaceBuild && requeues < MaxJobRequeues {
+			log.Warn(ctx, "detected orphaned provisioner job, returning it to the queue",
+				slog.F("threshold", StaleDaemonDuration),
+				slog.F("requeues", requeues),
+			)
+
+			lowestLogID, err = insertJobLogs(ctx, db, job, []string{"", requeuedJobLogMessage, ""})
 			if err != nil {
-				return xerrors.Errorf("get workspace build for workspace build job by job id: %w", err)
+				return xerrors.Errorf("insert logs for orphaned job: %w", err)
 			}
-
-			// Only copy the provisioner state if there's no state in
-			// the current build.
-			if len(build.ProvisionerState) == 0 {
-				// Get the previous build if it exists.
-				prevBuild, err := db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
-					WorkspaceID: build.WorkspaceID,
-					BuildNumber: build.BuildNumber - 1,
-				})
-				if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
-					return xerrors.Errorf("get previous workspace build: %w", err)
-				}
-				if err == nil {
-					err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
-						ID:               build.ID,
-						UpdatedAt:        dbtime.Now(),
-						ProvisionerState: prevBuild.ProvisionerState,
-					})
-					if err != nil {
-						return xerrors.Errorf("update workspace build by id: %w", err)
-					}
-				}
+			err = db.RequeueProvisionerJobByID(ctx, database.RequeueProvisionerJobByIDParams{
+				ID:        job.ID,
+				UpdatedAt: dbtime.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("requeue job: %w", err)
 			}
+			requeued = true
+			requeuedJob = job
+			return nil
 		}
 
-		return nil
+		log.Warn(ctx, "detected orphaned provisioner job, forcefully terminating",
+			slog.F("threshold", StaleDaemonDuration),
+			slog.F("requeues", requeues),
+		)
+
+		lowestLogID, err = insertJobLogs(ctx, db, job, OrphanedJobLogMessages)
+		if err != nil {
+			return xerrors.Errorf("insert logs for orphaned job: %w", err)
+		}
+
+		return failJob(ctx, db, job, "Coder: The provisioner daemon running this build stopped responding and the build has been terminated.")
 	}, nil)
 	if err != nil {
-		return xerrors.Errorf("in tx: %w", err)
+		return false, xerrors.Errorf("in tx: %w", err)
 	}
 
-	// Publish the new log notification to pubsub. Use the lowest log ID
-	// inserted so the log stream will fetch everything after that point.
+	// Requeued jobs continue to stream logs when they are acquired again.
+	err = publishJobLogs(pub, jobID, lowestLogID, !requeued)
+	if err != nil {
+		return false, err
+	}
+	if requeued {
+		// Wake up provisioner daemons waiting for jobs.
+		err = provisionerjobs.PostJob(pub, requeuedJob)
+		if err != nil {
+			return false, xerrors.Errorf("post requeued job: %w", err)
+		}
+	}
+	return requeued, nil
+}
+
+// insertJobLogs inserts the messages into the build log, in the latest stage
+// of the job. It returns the lowest ID of the inserted logs.
+func insertJobLogs(ctx context.Context, db database.Store, job database.ProvisionerJob, messages []string) (int64, error) {
+	// First, get the latest logs from the build so we can make sure
+	// our messages are in the latest stage.
+	logs, err := db.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID:        job.ID,
+		CreatedAfter: 0,
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("get logs: %w", err)
+	}
+	logStage := ""
+	if len(logs) != 0 {
+		logStage = logs[len(logs)-1].Stage
+	}
+	if logStage == "" {
+		logStage = "Unknown"
+	}
+
+	// Insert the messages into the build log.
+	insertParams := database.InsertProvisionerJobLogsParams{
+		JobID:     job.ID,
+		CreatedAt: nil,
+		Source:    nil,
+		Level:     nil,
+		Stage:     nil,
+		Output:    nil,
+	}
+	now := dbtime.Now()
+	for i, msg := range messages {
+		// Set the created at in a way that ensures each message has
+		// a unique timestamp so they will be sorted correctly.
+		insertParams.CreatedAt = append(insertParams.CreatedAt, now.Add(time.Millisecond*time.Duration(i)))
+		insertParams.Level = append(insertParams.Level, database.LogLevelError)
+		insertParams.Stage = append(insertParams.Stage, logStage)
+		insertParams.Source = append(insertParams.Source, database.LogSourceProvisionerDaemon)
+		insertParams.Output = append(insertParams.Output, msg)
+	}
+	newLogs, err := db.InsertProvisionerJobLogs(ctx, insertParams)
+	if err != nil {
+		return 0, xerrors.Errorf("insert logs: %w", err)
+	}
+	return newLogs[0].ID, nil
+}
+
+// failJob marks the job as failed. Workspace builds keep the provisioner
+// state of the previous build.
+func failJob(ctx context.Context, db database.Store, job database.ProvisionerJob, message string) error {
+	now := dbtime.Now()
+	err := db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:        job.ID,
+		UpdatedAt: now,
+		CompletedAt: sql.NullTime{
+			Time:  now,
+			Valid: true,
+		},
+		Error: sql.NullString{
+			String: message,
+			Valid:  true,
+		},
+		ErrorCode: sql.NullString{
+			Valid: false,
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("mark job as failed: %w", err)
+	}
+
+	// If the provisioner job is a workspace build, copy the
+	// provisioner state from the previous build to this workspace
+	// build.
+	if job.Type == database.ProvisionerJobTypeWorkspaceBuild {
+		build, err := db.GetWorkspaceBuildByJobID(ctx, job.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace build for workspace build job by job id: %w", err)
+		}
+
+		// Only copy the provisioner state if there's no state in
+		// the current build.
+		if len(build.ProvisionerState) == 0 {
+			// Get the previous build if it exists.
+			prevBuild, err := db.GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx, database.GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams{
+				WorkspaceID: build.WorkspaceID,
+				BuildNumber: build.BuildNumber - 1,
+			})
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get previous workspace build: %w", err)
+			}
+			if err == nil {
+				err = db.UpdateWorkspaceBuildProvisionerStateByID(ctx, database.UpdateWorkspaceBuildProvisionerStateByIDParams{
+					ID:               build.ID,
+					UpdatedAt:        dbtime.Now(),
+					ProvisionerState: prevBuild.ProvisionerState,
+				})
+				if err != nil {
+					return xerrors.Errorf("update workspace build by id: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// publishJobLogs publishes the new log notification to pubsub. Use the lowest
+// log ID inserted so the log stream will fetch everything after that point.
+func publishJobLogs(pub pubsub.Pubsub, jobID uuid.UUID, lowestLogID int64, endOfLogs bool) error {
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
 		CreatedAfter: lowestLogID - 1,
-		EndOfLogs:    true,
+		EndOfLogs:    endOfLogs,
 	})
 	if err != nil {
 		return xerrors.Errorf("marshal log notification: %w", err)
@@ -368,6 +776,5 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 	if err != nil {
 		return xerrors.Errorf("publish log notification: %w", err)
 	}
-
 	return nil
 }
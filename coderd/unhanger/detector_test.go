@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"go.uber.org/goleak"
 
 	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
@@ -591,6 +594,303 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 	detector.Wait()
 }
 
+func TestDetectorOrphanedJobs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = slogtest.Make(t, nil)
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan unhanger.Stats)
+	)
+
+	var (
+		now         = time.Now()
+		fourMinAgo  = now.Add(-time.Minute * 4)
+		org         = dbgen.Organization(t, db, database.Organization{})
+		user        = dbgen.User(t, db, database.User{})
+		file        = dbgen.File(t, db, database.File{})
+		staleDaemon = upsertDaemon(t, db, "stale", fourMinAgo)
+		aliveDaemon = upsertDaemon(t, db, "alive", now)
+		template    = dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			TemplateID: uuid.NullUUID{
+				UUID:  template.ID,
+				Valid: true,
+			},
+			CreatedBy: user.ID,
+		})
+		workspace = dbgen.Workspace(t, db, database.Workspace{
+			OwnerID:        user.ID,
+			OrganizationID: org.ID,
+			TemplateID:     template.ID,
+		})
+		newJob = func(daemon database.ProvisionerDaemon, jobType database.ProvisionerJobType) database.ProvisionerJob {
+			return dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+				CreatedAt: fourMinAgo,
+				StartedAt: sql.NullTime{
+					Time:  fourMinAgo,
+					Valid: true,
+				},
+				WorkerID: uuid.NullUUID{
+					UUID:  daemon.ID,
+					Valid: true,
+				},
+				OrganizationID: org.ID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         file.ID,
+				Type:           jobType,
+				Input:          []byte("{}"),
+			})
+		}
+
+		// Jobs of a daemon that stopped sending heartbeats.
+		workspaceBuildJob = newJob(staleDaemon, database.ProvisionerJobTypeWorkspaceBuild)
+		_                 = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+			WorkspaceID:       workspace.ID,
+			TemplateVersionID: templateVersion.ID,
+			BuildNumber:       1,
+			JobID:             workspaceBuildJob.ID,
+		})
+		templateImportJob = newJob(staleDaemon, database.ProvisionerJobTypeTemplateVersionImport)
+
+		// Job of a daemon that is alive.
+		aliveJob = newJob(aliveDaemon, database.ProvisionerJobTypeTemplateVersionImport)
+	)
+
+	detector := unhanger.New(ctx, db, pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector.Start()
+	tickCh <- now
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{workspaceBuildJob.ID}, stats.TerminatedJobIDs)
+	require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.RequeuedJobIDs)
+
+	// The workspace build was terminated.
+	job, err := db.GetProvisionerJobByID(ctx, workspaceBuildJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "provisioner daemon running this build stopped responding")
+
+	// The template import was returned to the queue.
+	job, err = db.GetProvisionerJobByID(ctx, templateImportJob.ID)
+	require.NoError(t, err)
+	require.False(t, job.StartedAt.Valid)
+	require.False(t, job.WorkerID.Valid)
+	require.False(t, job.CompletedAt.Valid)
+	require.Equal(t, database.ProvisionerJobStatusPending, job.JobStatus)
+
+	// The job of the alive daemon was not changed.
+	job, err = db.GetProvisionerJobByID(ctx, aliveJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.StartedAt.Valid)
+	require.Equal(t, aliveDaemon.ID, job.WorkerID.UUID)
+	require.False(t, job.CompletedAt.Valid)
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorOrphanedJobRequeueLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = slogtest.Make(t, nil)
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan unhanger.Stats)
+	)
+
+	var (
+		now         = time.Now()
+		fourMinAgo  = now.Add(-time.Minute * 4)
+		org         = dbgen.Organization(t, db, database.Organization{})
+		user        = dbgen.User(t, db, database.User{})
+		file        = dbgen.File(t, db, database.File{})
+		staleDaemon = upsertDaemon(t, db, "stale", fourMinAgo)
+		workerID    = uuid.NullUUID{
+			UUID:  staleDaemon.ID,
+			Valid: true,
+		}
+		templateImportJob = dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+			CreatedAt: fourMinAgo,
+			StartedAt: sql.NullTime{
+				Time:  fourMinAgo,
+				Valid: true,
+			},
+			WorkerID:       workerID,
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+	)
+
+	tags, err := json.Marshal(templateImportJob.Tags)
+	require.NoError(t, err)
+
+	detector := unhanger.New(ctx, db, pubsub, log, tickCh).WithStatsChannel(statsCh)
+	detector.Start()
+
+	for i := 0; i < unhanger.MaxJobRequeues; i++ {
+		tickCh <- now
+		stats := <-statsCh
+		require.NoError(t, stats.Error)
+		require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.RequeuedJobIDs)
+
+		// The stale daemon acquires the job again.
+		job, err := db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			StartedAt: sql.NullTime{
+				Time:  fourMinAgo,
+				Valid: true,
+			},
+			WorkerID: workerID,
+			Types:    []database.ProvisionerType{database.ProvisionerTypeEcho},
+			Tags:     tags,
+		})
+		require.NoError(t, err)
+		require.Equal(t, templateImportJob.ID, job.ID)
+	}
+
+	tickCh <- now
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RequeuedJobIDs)
+	require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.TerminatedJobIDs)
+
+	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.Contains(t, job.Error.String, "provisioner daemon running this build stopped responding")
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorTimedOutWorkspaceBuild(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = slogtest.Make(t, nil)
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan unhanger.Stats)
+		auditor    = audit.NewMock()
+	)
+
+	var (
+		now          = time.Now()
+		twentyMinAgo = now.Add(-time.Minute * 20)
+		fiveMinAgo   = now.Add(-time.Minute * 5)
+		org          = dbgen.Organization(t, db, database.Organization{})
+		user         = dbgen.User(t, db, database.User{})
+		file         = dbgen.File(t, db, database.File{})
+		template     = dbgen.Template(t, db, database.Template{
+			OrganizationID: org.ID,
+			CreatedBy:      user.ID,
+		})
+		templateVersion = dbgen.TemplateVersion(t, db, database.TemplateVersion{
+			OrganizationID: org.ID,
+			TemplateID: uuid.NullUUID{
+				UUID:  template.ID,
+				Valid: true,
+			},
+			CreatedBy: user.ID,
+		})
+		newBuild = func(startedAt time.Time) database.ProvisionerJob {
+			workspace := dbgen.Workspace(t, db, database.Workspace{
+				OwnerID:        user.ID,
+				OrganizationID: org.ID,
+				TemplateID:     template.ID,
+			})
+			job := dbgen.ProvisionerJob(t, db, pubsub, database.ProvisionerJob{
+				CreatedAt: startedAt,
+				StartedAt: sql.NullTime{
+					Time:  startedAt,
+					Valid: true,
+				},
+				OrganizationID: org.ID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				FileID:         file.ID,
+				Type:           database.ProvisionerJobTypeWorkspaceBuild,
+				Input:          []byte("{}"),
+			})
+			// Recently updated, so the job is not hung.
+			err := db.UpdateProvisionerJobByID(ctx, database.UpdateProvisionerJobByIDParams{
+				ID:        job.ID,
+				UpdatedAt: now,
+			})
+			require.NoError(t, err)
+			_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+				WorkspaceID:       workspace.ID,
+				TemplateVersionID: templateVersion.ID,
+				BuildNumber:       1,
+				JobID:             job.ID,
+			})
+			return job
+		}
+
+		timedOutJob = newBuild(twentyMinAgo)
+		recentJob   = newBuild(fiveMinAgo)
+	)
+	err := db.UpdateTemplateMaxBuildDurationByID(ctx, database.UpdateTemplateMaxBuildDurationByIDParams{
+		ID:               template.ID,
+		MaxBuildDuration: int64(10 * time.Minute),
+		UpdatedAt:        now,
+	})
+	require.NoError(t, err)
+
+	var auditorPtr atomic.Pointer[audit.Auditor]
+	var a audit.Auditor = auditor
+	auditorPtr.Store(&a)
+	detector := unhanger.New(ctx, db, pubsub, log, tickCh).WithAuditor(&auditorPtr).WithStatsChannel(statsCh)
+	detector.Start()
+	tickCh <- now
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.TerminatedJobIDs)
+	require.Equal(t, []uuid.UUID{timedOutJob.ID}, stats.TimedOutJobIDs)
+
+	// The build that exceeded the max build duration was terminated.
+	job, err := db.GetProvisionerJobByID(ctx, timedOutJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "exceeded the max build duration of 10m0s")
+
+	// The recent build was not changed.
+	job, err = db.GetProvisionerJobByID(ctx, recentJob.ID)
+	require.NoError(t, err)
+	require.False(t, job.CompletedAt.Valid)
+
+	// The termination was audited.
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, 1)
+	require.Equal(t, timedOutJob.ID, logs[0].RequestID)
+	require.Equal(t, int32(http.StatusRequestTimeout), logs[0].StatusCode)
+	require.Equal(t, database.ResourceTypeWorkspaceBuild, logs[0].ResourceType)
+
+	detector.Close()
+	detector.Wait()
+}
+
 func TestDetectorPushesLogs(t *testing.T) {
 	t.Parallel()
 
@@ -788,3 +1088,22 @@ func TestDetectorMaxJobsPerRun(t *testing.T) {
 	detector.Close()
 	detector.Wait()
 }
+
+func upsertDaemon(t *testing.T, db database.Store, name string, lastSeenAt time.Time) database.ProvisionerDaemon {
+	t.Helper()
+
+	daemon, err := db.UpsertProvisionerDaemon(context.Background(), database.UpsertProvisionerDaemonParams{
+		CreatedAt:    lastSeenAt,
+		Name:         name,
+		Provisioners: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Tags:         database.StringMap{},
+		LastSeenAt: sql.NullTime{
+			Time:  lastSeenAt,
+			Valid: true,
+		},
+		Version:    "v0.0.0",
+		APIVersion: "1.0",
+	})
+	require.NoError(t, err)
+	return daemon
+}
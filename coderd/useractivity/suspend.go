@@ -0,0 +1,62 @@
+package useractivity
+
+import (
+	"context"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+// suspendInterval is the time between checks for inactive users.
+const suspendInterval = time.Hour
+
+// SuspendInactiveUsers suspends users that have not used Coder for the idle
+// period, checking every hour. Owners are never suspended.
+func SuspendInactiveUsers(ctx context.Context, logger slog.Logger, db database.Store, idlePeriod time.Duration) func() {
+	return SuspendInactiveUsersWithOptions(ctx, logger, db, suspendInterval, idlePeriod)
+}
+
+// SuspendInactiveUsersWithOptions suspends users that have not used Coder for
+// the idle period, checking at the given interval.
+func SuspendInactiveUsersWithOptions(ctx context.Context, logger slog.Logger, db database.Store, checkInterval, idlePeriod time.Duration) func() {
+	logger = logger.Named("inactive_users")
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system suspends users without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+	ticker := time.NewTicker(checkInterval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			inactiveSince := dbtime.Now().Add(-idlePeriod)
+			suspended, err := db.UpdateInactiveUsersToSuspended(ctx, database.UpdateInactiveUsersToSuspendedParams{
+				UpdatedAt:     dbtime.Now(),
+				InactiveSince: inactiveSince,
+			})
+			if err != nil {
+				logger.Error(ctx, "can't suspend inactive users", slog.Error(err))
+				continue
+			}
+			for _, u := range suspended {
+				logger.Info(ctx, "inactive user has been suspended", slog.F("email", u.Email), slog.F("last_seen_at", u.LastSeenAt))
+			}
+			logger.Debug(ctx, "checked for inactive users", slog.F("idle_period", idlePeriod), slog.F("num_suspended", len(suspended)))
+		}
+	}()
+
+	return func() {
+		cancelFunc()
+		<-done
+	}
+}
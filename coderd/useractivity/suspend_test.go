@@ -0,0 +1,51 @@
+package useractivity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/useractivity"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSuspendInactiveUsers(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	idlePeriod := 90 * 24 * time.Hour
+	longAgo := dbtime.Now().Add(-idlePeriod - time.Hour)
+	idle := dbgen.User(t, db, database.User{CreatedAt: longAgo, LastSeenAt: longAgo})
+	idleDormant := dbgen.User(t, db, database.User{CreatedAt: longAgo, LastSeenAt: longAgo, Status: database.UserStatusDormant})
+	idleOwner := dbgen.User(t, db, database.User{CreatedAt: longAgo, LastSeenAt: longAgo, RBACRoles: []string{rbac.RoleOwner()}})
+	active := dbgen.User(t, db, database.User{CreatedAt: longAgo, LastSeenAt: dbtime.Now()})
+	// New users have never been seen, but are not idle yet.
+	newUser := dbgen.User(t, db, database.User{})
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	stop := useractivity.SuspendInactiveUsersWithOptions(ctx, slogtest.Make(t, nil), db, time.Millisecond, idlePeriod)
+	t.Cleanup(stop)
+
+	statusOf := func(id uuid.UUID) database.UserStatus {
+		user, err := db.GetUserByID(ctx, id)
+		require.NoError(t, err)
+		return user.Status
+	}
+	require.Eventually(t, func() bool {
+		return statusOf(idle.ID) == database.UserStatusSuspended &&
+			statusOf(idleDormant.ID) == database.UserStatusSuspended
+	}, testutil.WaitShort, testutil.IntervalFast)
+	assert.Equal(t, database.UserStatusActive, statusOf(idleOwner.ID))
+	assert.Equal(t, database.UserStatusActive, statusOf(active.ID))
+	assert.Equal(t, database.UserStatusActive, statusOf(newUser.ID))
+}
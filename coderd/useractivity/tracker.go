@@ -0,0 +1,162 @@
+// Package useractivity records which parts of the API users are active in,
+// aggregated by hour, and suspends users that have been idle for too long.
+package useractivity
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const defaultFlushInterval = time.Minute
+
+// Options tunes how often a Tracker writes to the database.
+type Options struct {
+	// FlushInterval is how often the counts are added to the database.
+	// Defaults to 1 minute.
+	FlushInterval time.Duration
+}
+
+type activityKey struct {
+	userID      uuid.UUID
+	hour        time.Time
+	category    string
+	workspaceID uuid.UUID
+}
+
+// Tracker counts API requests by user, API category and workspace in memory,
+// and periodically adds the counts to the hourly totals in the database. This
+// keeps a write per request off the database.
+type Tracker struct {
+	db   database.Store
+	log  slog.Logger
+	opts Options
+
+	mu     sync.Mutex
+	counts map[activityKey]int32
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New starts a Tracker that flushes until the context is canceled or it is
+// closed.
+func New(ctx context.Context, db database.Store, logger slog.Logger, opts Options) *Tracker {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	t := &Tracker{
+		db:     db,
+		log:    logger,
+		opts:   opts,
+		counts: map[activityKey]int32{},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go t.run(ctx)
+	return t
+}
+
+// Record counts a request made by the user. The workspace is uuid.Nil if the
+// request was not about a workspace.
+func (t *Tracker) Record(userID uuid.UUID, category string, workspaceID uuid.UUID) {
+	key := activityKey{
+		userID:      userID,
+		hour:        dbtime.Now().Truncate(time.Hour),
+		category:    category,
+		workspaceID: workspaceID,
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counts[key]++
+}
+
+// Flush adds the counts recorded so far to the database. Counts that fail to
+// be written are dropped, as activity is cheap to lose.
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	counts := t.counts
+	t.counts = map[activityKey]int32{}
+	t.mu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+	arg := database.UpsertUserActivityParams{
+		UserID:      make([]uuid.UUID, 0, len(counts)),
+		Hour:        make([]time.Time, 0, len(counts)),
+		Category:    make([]string, 0, len(counts)),
+		WorkspaceID: make([]uuid.UUID, 0, len(counts)),
+		Count:       make([]int32, 0, len(counts)),
+	}
+	for key, count := range counts {
+		arg.UserID = append(arg.UserID, key.userID)
+		arg.Hour = append(arg.Hour, key.hour)
+		arg.Category = append(arg.Category, key.category)
+		arg.WorkspaceID = append(arg.WorkspaceID, key.workspaceID)
+		arg.Count = append(arg.Count, count)
+	}
+	//nolint:gocritic // The tracker records the activity of all users.
+	return t.db.UpsertUserActivity(dbauthz.AsSystemRestricted(ctx), arg)
+}
+
+// Close stops the tracker and flushes the counts that are left.
+func (t *Tracker) Close() error {
+	t.cancel()
+	<-t.done
+	return nil
+}
+
+func (t *Tracker) run(ctx context.Context) {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Use a fresh context, so the counts recorded since the last
+			// flush are not lost on shutdown.
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := t.Flush(flushCtx)
+			cancel()
+			if err != nil {
+				t.log.Warn(ctx, "failed to flush user activity on close", slog.Error(err))
+			}
+			return
+		case <-ticker.C:
+		}
+
+		err := t.Flush(ctx)
+		if err != nil {
+			t.log.Error(ctx, "failed to flush user activity", slog.Error(err))
+		}
+	}
+}
+
+// Category returns the API category of a route pattern, which is the first
+// path element under /api/v2, e.g. "workspaces" for
+// /api/v2/workspaces/{workspace}/builds. Routes outside of the API, such as
+// path-based apps, are in the "other" category.
+func Category(routePattern string) string {
+	rest, ok := strings.CutPrefix(routePattern, "/api/v2/")
+	if !ok {
+		return "other"
+	}
+	category, _, _ := strings.Cut(rest, "/")
+	if category == "" || strings.ContainsAny(category, "{*") {
+		return "other"
+	}
+	return category
+}
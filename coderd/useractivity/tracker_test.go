@@ -0,0 +1,95 @@
+package useractivity_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/useractivity"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	db := dbmem.New()
+	// The user was last seen long ago, so the report includes them.
+	user := dbgen.User(t, db, database.User{
+		CreatedAt:  dbtime.Now().Add(-60 * 24 * time.Hour),
+		LastSeenAt: dbtime.Now().Add(-40 * 24 * time.Hour),
+	})
+	workspaceID := uuid.New()
+
+	tracker := useractivity.New(context.Background(), db, slogtest.Make(t, nil), useractivity.Options{
+		// Only flush explicitly.
+		FlushInterval: time.Hour,
+	})
+	t.Cleanup(func() {
+		_ = tracker.Close()
+	})
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	tracker.Record(user.ID, "workspaces", workspaceID)
+	tracker.Record(user.ID, "workspaces", workspaceID)
+	require.NoError(t, tracker.Flush(ctx))
+	// Counts are added to the same hour across flushes.
+	tracker.Record(user.ID, "workspaces", workspaceID)
+	require.NoError(t, tracker.Flush(ctx))
+	// Flushing with nothing recorded is a no-op.
+	require.NoError(t, tracker.Flush(ctx))
+
+	rows, err := db.GetInactiveUsers(ctx, dbtime.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, user.ID, rows[0].ID)
+	require.True(t, rows[0].LastActivityCategory.Valid)
+	assert.Equal(t, "workspaces", rows[0].LastActivityCategory.String)
+	assert.Equal(t, workspaceID, rows[0].LastActivityWorkspaceID.UUID)
+	assert.WithinDuration(t, dbtime.Now(), rows[0].LastActivityHour.Time, time.Hour)
+
+	// Counts recorded before close are flushed. The busiest category of the
+	// hour is reported.
+	for i := 0; i < 5; i++ {
+		tracker.Record(user.ID, "templates", uuid.Nil)
+	}
+	require.NoError(t, tracker.Close())
+	rows, err = db.GetInactiveUsers(ctx, dbtime.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "templates", rows[0].LastActivityCategory.String)
+	assert.Equal(t, uuid.Nil, rows[0].LastActivityWorkspaceID.UUID)
+
+	err = db.DeleteOldUserActivity(ctx, dbtime.Now().Add(time.Hour))
+	require.NoError(t, err)
+	rows, err = db.GetInactiveUsers(ctx, dbtime.Now().Add(-30*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.False(t, rows[0].LastActivityCategory.Valid, "activity should be deleted")
+}
+
+func TestCategory(t *testing.T) {
+	t.Parallel()
+
+	for pattern, want := range map[string]string{
+		"/api/v2/workspaces/{workspace}/builds":                "workspaces",
+		"/api/v2/users/{user}/workspace/{workspacename}":       "users",
+		"/api/v2/templates/":                                   "templates",
+		"/api/v2/{param}":                                      "other",
+		"/@{user}/{workspace_and_agent}/apps/{workspaceapp}/*": "other",
+		"/api/v2/": "other",
+		"/api/v2/workspaceagents/{workspaceagent}/pty":           "workspaceagents",
+		"/api/v2/organizations/{organization}/templates/{name}/": "organizations",
+	} {
+		assert.Equal(t, want, useractivity.Category(pattern), pattern)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/google/go-github/v43/github"
 	"github.com/google/uuid"
 	"github.com/moby/moby/pkg/namesgenerator"
+	"github.com/sqlc-dev/pqtype"
 	"golang.org/x/oauth2"
 	"golang.org/x/xerrors"
 
@@ -29,10 +30,13 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/groupsync"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/ldapauth"
 	"github.com/coder/coder/v2/coderd/promoauth"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/rolestore"
 	"github.com/coder/coder/v2/coderd/userpassword"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -90,7 +94,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	switch req.ToType {
 	case codersdk.LoginTypeGithub, codersdk.LoginTypeOIDC:
 		// Allowed!
-	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken:
+	case codersdk.LoginTypeNone, codersdk.LoginTypePassword, codersdk.LoginTypeToken, codersdk.LoginTypeLDAP:
 		// These login types are not allowed to be converted to at this time.
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Cannot convert to login type %q.", req.ToType),
@@ -104,7 +108,7 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	// This handles the email/pass checking.
-	user, _, ok := api.loginRequest(ctx, rw, codersdk.LoginWithPasswordRequest{
+	user, _, ok := api.loginRequest(ctx, rw, loginIP(r), codersdk.LoginWithPasswordRequest{
 		Email:    user.Email,
 		Password: req.Password,
 	})
@@ -199,7 +203,11 @@ func (api *API) postConvertLoginType(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// Authenticates the user with an email and password.
+// Authenticates the user with an email and password. Users with a passkey get
+// a second factor challenge instead of a session token, which is answered with
+// postLoginSecondFactor. Users who must have a second factor but have no
+// passkey get a passkey enrollment instead, which is completed with
+// postLoginPasskeyEnrollment.
 //
 // @Summary Log in user
 // @ID log-in-user
@@ -229,7 +237,7 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, roles, ok := api.loginRequest(ctx, rw, loginWithPassword)
+	user, roles, ok := api.loginRequest(ctx, rw, loginIP(r), loginWithPassword)
 	// 'user.ID' will be empty, or will be an actual value. Either is correct
 	// here.
 	aReq.UserID = user.ID
@@ -238,9 +246,55 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	secondFactor, err := api.secondFactorLogin(ctx, user, roles.Roles)
+	if err != nil {
+		logger.Error(ctx, "unable to check second factor", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error checking second factor.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if secondFactor != nil {
+		// The login is audited once the second factor is answered.
+		aReq.UserID = uuid.Nil
+		httpapi.Write(ctx, rw, http.StatusCreated, secondFactor)
+		return
+	}
+
+	cookie, key, ok := api.createPasswordLoginSession(ctx, rw, r, user, roles)
+	if !ok {
+		return
+	}
+	aReq.New = *key
+
+	http.SetCookie(rw, cookie)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: cookie.Value,
+	})
+}
+
+// createPasswordLoginSession creates the API key of a user who logged in with
+// their password, or with their directory password for LDAP users, once the
+// second factor was answered. If 'false' is returned, the appropriate error was
+// written to the ResponseWriter.
+func (api *API) createPasswordLoginSession(ctx context.Context, rw http.ResponseWriter, r *http.Request, user database.User, roles database.GetAuthorizationUserRolesRow) (*http.Cookie, *database.APIKey, bool) {
+	logger := api.Logger.Named(userAuthLoggerName)
+
+	//nolint:gocritic // System needs to expand the user's custom roles.
+	expandedRoles, err := rolestore.Expand(dbauthz.AsSystemRestricted(ctx), api.Database, roles.Roles)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error expanding user roles.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+
 	userSubj := rbac.Subject{
 		ID:     user.ID.String(),
-		Roles:  rbac.RoleNames(roles.Roles),
+		Roles:  expandedRoles,
 		Groups: roles.Groups,
 		Scope:  rbac.ScopeAll,
 	}
@@ -248,9 +302,10 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 	//nolint:gocritic // Creating the API key as the user instead of as system.
 	cookie, key, err := api.createAPIKey(dbauthz.As(ctx, userSubj), apikey.CreateParams{
 		UserID:           user.ID,
-		LoginType:        database.LoginTypePassword,
+		LoginType:        user.LoginType,
 		RemoteAddr:       r.RemoteAddr,
 		DeploymentValues: api.DeploymentValues,
+		Roles:            user.RBACRoles,
 	})
 	if err != nil {
 		logger.Error(ctx, "unable to create API key", slog.Error(err))
@@ -258,16 +313,9 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 			Message: "Failed to create API key.",
 			Detail:  err.Error(),
 		})
-		return
+		return nil, nil, false
 	}
-
-	aReq.New = *key
-
-	http.SetCookie(rw, cookie)
-
-	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
-		SessionToken: cookie.Value,
-	})
+	return cookie, key, true
 }
 
 // loginRequest will process a LoginWithPasswordRequest and return the user if
@@ -275,8 +323,9 @@ func (api *API) postLogin(rw http.ResponseWriter, r *http.Request) {
 // and the appropriate error will be written to the ResponseWriter.
 //
 // The user struct is always returned, even if authentication failed. This is
-// to support knowing what user attempted to login.
-func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
+// to support knowing what user attempted to login. Failed attempts from ip are
+// throttled, whether the user exists or not.
+func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, ip pqtype.Inet, req codersdk.LoginWithPasswordRequest) (database.User, database.GetAuthorizationUserRolesRow, bool) {
 	logger := api.Logger.Named(userAuthLoggerName)
 
 	//nolint:gocritic // In order to login, we need to get the user first!
@@ -290,6 +339,9 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 		})
 		return user, database.GetAuthorizationUserRolesRow{}, false
 	}
+	if !api.loginThrottler.attempt(ctx, rw, user.ID, req.Email, ip) {
+		return user, database.GetAuthorizationUserRolesRow{}, false
+	}
 
 	// If the user doesn't exist, it will be a default struct.
 	equal, err := userpassword.Compare(string(user.HashedPassword), req.Password)
@@ -302,6 +354,7 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 	}
 
 	if !equal {
+		api.loginThrottler.failed(ctx, user.ID, ip)
 		// This message is the same as above to remove ease in detecting whether
 		// users are registered or not. Attackers still could with a timing attack.
 		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
@@ -309,6 +362,7 @@ func (api *API) loginRequest(ctx context.Context, rw http.ResponseWriter, req co
 		})
 		return user, database.GetAuthorizationUserRolesRow{}, false
 	}
+	api.loginThrottler.succeeded(ctx, user.ID, req.Email, ip)
 
 	// If password authentication is disabled and the user does not have the
 	// owner role, block the request.
@@ -449,6 +503,81 @@ type GithubOAuth2Config struct {
 	AllowEveryone      bool
 	AllowOrganizations []string
 	AllowTeams         []GithubOAuth2Team
+
+	teamCache githubTeamCache
+}
+
+// githubTeamCacheTTL is how long a successful team membership lookup can
+// stand in for GitHub when the GitHub API is unavailable.
+const githubTeamCacheTTL = time.Hour
+
+// githubTeamCache remembers successful GitHub team membership lookups. Team
+// membership is checked against GitHub on every login, and the cache is only
+// consulted if GitHub fails to answer (e.g. rate limits or outages), so a
+// transient error doesn't lock out users that were members moments ago.
+type githubTeamCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func (c *githubTeamCache) member(org, team, username string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.entries[githubTeamCacheKey(org, team, username)]
+	return ok && time.Now().Before(expires)
+}
+
+func (c *githubTeamCache) add(org, team, username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]time.Time)
+	}
+	now := time.Now()
+	for key, expires := range c.entries {
+		if !now.Before(expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[githubTeamCacheKey(org, team, username)] = now.Add(githubTeamCacheTTL)
+}
+
+func (c *githubTeamCache) remove(org, team, username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, githubTeamCacheKey(org, team, username))
+}
+
+func githubTeamCacheKey(org, team, username string) string {
+	return strings.ToLower(org + "/" + team + "/" + username)
+}
+
+// teamMember returns whether the user is a member of the given team.
+func (c *GithubOAuth2Config) teamMember(ctx context.Context, logger slog.Logger, client *http.Client, team GithubOAuth2Team, username string) bool {
+	_, err := c.TeamMembership(ctx, client, team.Organization, team.Slug, username)
+	if err == nil {
+		c.teamCache.add(team.Organization, team.Slug, username)
+		return true
+	}
+
+	// GitHub responds with a 404 if the user isn't a member, and a 403 if the
+	// calling user may not have permission to the requested team!
+	var ghErr *github.ErrorResponse
+	if xerrors.As(err, &ghErr) && ghErr.Response != nil &&
+		(ghErr.Response.StatusCode == http.StatusNotFound || ghErr.Response.StatusCode == http.StatusForbidden) {
+		c.teamCache.remove(team.Organization, team.Slug, username)
+		return false
+	}
+
+	cached := c.teamCache.member(team.Organization, team.Slug, username)
+	logger.Warn(ctx, "oauth2: unable to fetch github team membership",
+		slog.F("organization", team.Organization),
+		slog.F("team", team.Slug),
+		slog.F("username", username),
+		slog.F("using_cached_membership", cached),
+		slog.Error(err),
+	)
+	return cached
 }
 
 // @Summary Get authentication methods
@@ -479,6 +608,7 @@ func (api *API) userAuthMethods(rw http.ResponseWriter, r *http.Request) {
 			SignInText: signInText,
 			IconURL:    iconURL,
 		},
+		LDAP: codersdk.AuthMethod{Enabled: api.LDAPConfig != nil},
 	})
 }
 
@@ -509,6 +639,16 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 
 	logger := api.Logger.Named(userAuthLoggerName)
 
+	ghUser, err := api.GithubOAuth2Config.AuthenticatedUser(ctx, oauthClient)
+	if err != nil {
+		logger.Error(ctx, "oauth2: unable to fetch authenticated user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching authenticated Github user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	var selectedMemberships []*github.Membership
 	var organizationNames []string
 	redirect := state.Redirect
@@ -537,26 +677,17 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 			}
 		}
 		if len(selectedMemberships) == 0 {
+			api.suspendGithubUser(ctx, r, ghUser)
 			httpmw.CustomRedirectToLogin(rw, r, redirect, "You aren't a member of the authorized Github organizations!", http.StatusUnauthorized)
 			return
 		}
 	}
 
-	ghUser, err := api.GithubOAuth2Config.AuthenticatedUser(ctx, oauthClient)
-	if err != nil {
-		logger.Error(ctx, "oauth2: unable to fetch authenticated user", slog.Error(err))
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error fetching authenticated Github user.",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
 	// The default if no teams are specified is to allow all.
 	if !api.GithubOAuth2Config.AllowEveryone && len(api.GithubOAuth2Config.AllowTeams) > 0 {
-		var allowedTeam *github.Membership
+		inAllowedTeam := false
 		for _, allowTeam := range api.GithubOAuth2Config.AllowTeams {
-			if allowedTeam != nil {
+			if inAllowedTeam {
 				break
 			}
 			for _, selectedMembership := range selectedMemberships {
@@ -566,14 +697,14 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 					continue
 				}
 
-				allowedTeam, err = api.GithubOAuth2Config.TeamMembership(ctx, oauthClient, allowTeam.Organization, allowTeam.Slug, *ghUser.Login)
-				// The calling user may not have permission to the requested team!
-				if err != nil {
-					continue
+				if api.GithubOAuth2Config.teamMember(ctx, logger, oauthClient, allowTeam, ghUser.GetLogin()) {
+					inAllowedTeam = true
+					break
 				}
 			}
 		}
-		if allowedTeam == nil {
+		if !inAllowedTeam {
+			api.suspendGithubUser(ctx, r, ghUser)
 			httpmw.CustomRedirectToLogin(rw, r, redirect, fmt.Sprintf("You aren't a member of an authorized team in the %v Github organization(s)!", organizationNames), http.StatusUnauthorized)
 			return
 		}
@@ -662,6 +793,254 @@ func (api *API) userOAuth2Github(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
+// suspendGithubUser suspends the Coder user linked to the GitHub account, if
+// there is one. It is called when a user no longer belongs to the allowed
+// organizations or teams, so access granted by GitHub is revoked along with
+// any sessions the user still has.
+func (api *API) suspendGithubUser(ctx context.Context, r *http.Request, ghUser *github.User) {
+	logger := api.Logger.Named(userAuthLoggerName)
+
+	link, err := api.Database.GetUserLinkByLinkedID(ctx, githubLinkedID(ghUser))
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "oauth2: unable to get github user link", slog.Error(err))
+		return
+	}
+
+	user, err := api.Database.GetUserByID(ctx, link.UserID)
+	if err != nil {
+		logger.Error(ctx, "oauth2: unable to get github user", slog.F("user_id", link.UserID), slog.Error(err))
+		return
+	}
+	if user.Deleted || user.Status == database.UserStatusSuspended || user.LoginType != database.LoginTypeGithub {
+		return
+	}
+
+	suspended, err := api.Database.UpdateUserStatus(ctx, database.UpdateUserStatusParams{
+		ID:        user.ID,
+		Status:    database.UserStatusSuspended,
+		UpdatedAt: dbtime.Now(),
+	})
+	if err != nil {
+		logger.Error(ctx, "oauth2: unable to suspend github user", slog.F("user_id", user.ID), slog.Error(err))
+		return
+	}
+	logger.Info(ctx, "suspended github user that lost access",
+		slog.F("user_id", user.ID),
+		slog.F("github_login", ghUser.GetLogin()),
+	)
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.User]{
+		Audit:     *api.Auditor.Load(),
+		Log:       api.Logger,
+		UserID:    user.ID,
+		RequestID: httpmw.RequestID(r),
+		Status:    http.StatusOK,
+		Action:    database.AuditActionWrite,
+		IP:        r.RemoteAddr,
+		Old:       user,
+		New:       suspended,
+	})
+}
+
+// LDAPConfig enables logging in with the username and password of an account
+// in an LDAP directory.
+type LDAPConfig struct {
+	// Authenticate checks the credentials of a user, see
+	// ldapauth.Authenticator.
+	Authenticate func(ctx context.Context, username, password string) (ldapauth.User, error)
+	AllowSignups bool
+	// UsingGroups syncs users to the Coder groups they are members of in the
+	// directory.
+	UsingGroups bool
+	// CreateMissingGroups creates the Coder groups that don't exist yet.
+	CreateMissingGroups bool
+}
+
+// Authenticates the user with the username and password of their LDAP
+// account. Second factors are asked for like for password logins.
+//
+// @Summary Log in user with LDAP
+// @ID log-in-user-with-ldap
+// @Accept json
+// @Produce json
+// @Tags Authorization
+// @Param request body codersdk.LoginWithLDAPRequest true "Login request"
+// @Success 201 {object} codersdk.LoginWithPasswordResponse
+// @Router /users/ldap/login [post]
+func (api *API) postLDAPLogin(rw http.ResponseWriter, r *http.Request) {
+	var (
+		// postLDAPLogin is a system function.
+		//nolint:gocritic
+		ctx               = dbauthz.AsSystemRestricted(r.Context())
+		auditor           = api.Auditor.Load()
+		logger            = api.Logger.Named(userAuthLoggerName)
+		aReq, commitAudit = audit.InitRequest[database.APIKey](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionLogin,
+		})
+	)
+	aReq.Old = database.APIKey{}
+	defer commitAudit()
+
+	if api.LDAPConfig == nil {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "LDAP authentication is not enabled.",
+		})
+		return
+	}
+
+	var req codersdk.LoginWithLDAPRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Directory logins are throttled like password logins. There is no user to
+	// tie the attempts to before the directory has been asked, so they're
+	// tracked by the username like logins of unknown users.
+	ip := loginIP(r)
+	throttleLogin := "ldap:" + req.Username
+	if !api.loginThrottler.attempt(ctx, rw, uuid.Nil, throttleLogin, ip) {
+		return
+	}
+
+	ldapUser, err := api.LDAPConfig.Authenticate(ctx, req.Username, req.Password)
+	if xerrors.Is(err, ldapauth.ErrInvalidCredentials) {
+		api.loginThrottler.failed(ctx, uuid.Nil, ip)
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Incorrect username or password.",
+		})
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to authenticate user", slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error authenticating with the LDAP directory.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.loginThrottler.succeeded(ctx, uuid.Nil, throttleLogin, ip)
+	if ldapUser.Disabled {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your directory account is disabled. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+	if ldapUser.Email == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Your directory account doesn't have an email address.",
+		})
+		return
+	}
+
+	username := ldapUser.Username
+	if username == "" {
+		username = req.Username
+	}
+	if httpapi.NameValid(username) != nil {
+		username = httpapi.UsernameFrom(username)
+	}
+
+	user, link, err := findLinkedUser(ctx, api.Database, ldapUser.DN, ldapUser.Email)
+	if err != nil {
+		logger.Error(ctx, "ldap: unable to find linked user", slog.F("dn", ldapUser.DN), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to find linked user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.UserID = user.ID
+
+	// If a new user is authenticating for the first time
+	// the audit action is 'register', not 'login'
+	if user.ID == uuid.Nil {
+		aReq.Action = database.AuditActionRegister
+	}
+	// Sessions of suspended users are rejected, so the login is too.
+	if user.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Your account is suspended. Contact an admin to reactivate your account.",
+		})
+		return
+	}
+
+	params := (&oauthLoginParams{
+		User: user,
+		Link: link,
+		// There are no OAuth tokens to store on the link.
+		State:        httpmw.OAuth2State{Token: &oauth2.Token{}},
+		LinkedID:     ldapUser.DN,
+		LoginType:    database.LoginTypeLDAP,
+		AllowSignups: api.LDAPConfig.AllowSignups,
+		Email:        ldapUser.Email,
+		Username:     username,
+		// The directory has no avatars, so the current one is kept.
+		AvatarURL:           user.AvatarURL,
+		UsingGroups:         api.LDAPConfig.UsingGroups,
+		CreateMissingGroups: api.LDAPConfig.CreateMissingGroups,
+		IDPGroups:           ldapUser.Groups,
+		Groups:              ldapUser.Groups,
+		DebugContext:        OauthDebugContext{},
+	}).SetInitAuditRequest(func(params *audit.RequestParams) (*audit.Request[database.User], func()) {
+		return audit.InitRequest[database.User](rw, params)
+	})
+	// Directory passwords are checked like local ones, so the same second
+	// factor is required. The user is synced first, so new users and group
+	// changes are picked up either way.
+	var secondFactor *codersdk.LoginWithPasswordResponse
+	params.HoldSession = func(user database.User) (bool, error) {
+		roles, err := api.Database.GetAuthorizationUserRoles(ctx, user.ID)
+		if err != nil {
+			return false, xerrors.Errorf("get user roles: %w", err)
+		}
+		secondFactor, err = api.secondFactorLogin(ctx, user, roles.Roles)
+		return secondFactor != nil, err
+	}
+	cookies, key, err := api.oauthLogin(r, params)
+	defer params.CommitAuditLogs()
+	var httpErr httpError
+	if xerrors.As(err, &httpErr) {
+		// Logins from the API are answered with JSON, never a page.
+		httpErr.renderStaticPage = false
+		httpErr.Write(rw, r)
+		return
+	}
+	if err != nil {
+		logger.Error(ctx, "ldap: login failed", slog.F("user", username), slog.Error(err))
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to process LDAP login.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if secondFactor != nil {
+		// The login is audited once the second factor is answered.
+		aReq.UserID = uuid.Nil
+		httpapi.Write(ctx, rw, http.StatusCreated, secondFactor)
+		return
+	}
+	aReq.New = key
+	aReq.UserID = key.UserID
+
+	var sessionToken string
+	for _, cookie := range cookies {
+		if cookie.Name == codersdk.SessionTokenCookie {
+			sessionToken = cookie.Value
+		}
+		http.SetCookie(rw, cookie)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.LoginWithPasswordResponse{
+		SessionToken: sessionToken,
+	})
+}
+
 type OIDCConfig struct {
 	promoauth.OAuth2Config
 
@@ -706,6 +1085,19 @@ type OIDCConfig struct {
 	// to groups within Coder.
 	// map[oidcGroupName]coderGroupName
 	GroupMapping map[string]string
+	// GroupPrefixStrip lists prefixes that are removed from group names after
+	// the GroupFilter is applied. Only the first matching prefix is removed.
+	GroupPrefixStrip []string
+	// GroupDirectory resolves the groups that the groups returned by the OIDC
+	// provider are nested in, so users are also synced to those groups. If
+	// nil, only the groups in the claims are used.
+	GroupDirectory groupsync.Directory
+	// GroupNestingDepth is how many levels of nested groups are resolved.
+	GroupNestingDepth int
+	// GroupSyncInterval is how often the groups of every OIDC user are synced
+	// from the GroupDirectory in the background. If zero, groups are only
+	// synced when users log in.
+	GroupSyncInterval time.Duration
 	// UserRoleField selects the claim field to be used as the created user's
 	// roles. If the field is the empty string, then no role updates
 	// will ever come from the OIDC provider.
@@ -717,6 +1109,12 @@ type OIDCConfig struct {
 	// UserRolesDefault is the default set of roles to assign to a user if role sync
 	// is enabled.
 	UserRolesDefault []string
+	// GroupOrganizationRoleMapping controls which organization roles are
+	// granted to members of groups returned by the OIDC provider. Group names
+	// are matched after GroupMapping is applied. Roles are resynced on every
+	// login, so roles granted by a group are revoked once the user leaves it.
+	// map[groupName][]organizationRoleName
+	GroupOrganizationRoleMapping map[string][]string
 	// SignInText is the text to display on the OIDC login button
 	SignInText string
 	// IconURL points to the URL of an icon to display on the OIDC login button
@@ -727,6 +1125,22 @@ func (cfg OIDCConfig) RoleSyncEnabled() bool {
 	return cfg.UserRoleField != ""
 }
 
+func (cfg OIDCConfig) OrganizationRoleSyncEnabled() bool {
+	return cfg.GroupField != "" && len(cfg.GroupOrganizationRoleMapping) > 0
+}
+
+// GroupSync returns how the groups returned by the OIDC provider are mapped
+// to Coder groups.
+func (cfg OIDCConfig) GroupSync() groupsync.Config {
+	return groupsync.Config{
+		Directory:     cfg.GroupDirectory,
+		MaxDepth:      cfg.GroupNestingDepth,
+		Mapping:       cfg.GroupMapping,
+		RegexFilter:   cfg.GroupFilter,
+		StripPrefixes: cfg.GroupPrefixStrip,
+	}
+}
+
 // @Summary OpenID Connect Callback
 // @ID openid-connect-callback
 // @Security CoderSessionToken
@@ -927,7 +1341,7 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx = slog.With(ctx, slog.F("email", email), slog.F("username", username))
-	usingGroups, groups, groupErr := api.oidcGroups(ctx, mergedClaims)
+	usingGroups, idpGroups, groups, groupErr := api.oidcGroups(ctx, mergedClaims)
 	if groupErr != nil {
 		groupErr.Write(rw, r)
 		return
@@ -939,6 +1353,8 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	orgRoles := api.oidcOrganizationRoles(ctx, groups)
+
 	user, link, err := findLinkedUser(ctx, api.Database, oidcLinkedID(idToken), email)
 	if err != nil {
 		logger.Error(ctx, "oauth2: unable to find linked user", slog.F("email", email), slog.Error(err))
@@ -956,21 +1372,24 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	params := (&oauthLoginParams{
-		User:                user,
-		Link:                link,
-		State:               state,
-		LinkedID:            oidcLinkedID(idToken),
-		LoginType:           database.LoginTypeOIDC,
-		AllowSignups:        api.OIDCConfig.AllowSignups,
-		Email:               email,
-		Username:            username,
-		AvatarURL:           picture,
-		UsingRoles:          api.OIDCConfig.RoleSyncEnabled(),
-		Roles:               roles,
-		UsingGroups:         usingGroups,
-		Groups:              groups,
-		CreateMissingGroups: api.OIDCConfig.CreateMissingGroups,
-		GroupFilter:         api.OIDCConfig.GroupFilter,
+		User:                   user,
+		Link:                   link,
+		State:                  state,
+		LinkedID:               oidcLinkedID(idToken),
+		LoginType:              database.LoginTypeOIDC,
+		AllowSignups:           api.OIDCConfig.AllowSignups,
+		Email:                  email,
+		Username:               username,
+		AvatarURL:              picture,
+		UsingRoles:             api.OIDCConfig.RoleSyncEnabled(),
+		Roles:                  roles,
+		UsingOrganizationRoles: api.OIDCConfig.OrganizationRoleSyncEnabled(),
+		OrganizationRoles:      orgRoles,
+		UsingGroups:            usingGroups,
+		IDPGroups:              idpGroups,
+		Groups:                 groups,
+		CreateMissingGroups:    api.OIDCConfig.CreateMissingGroups,
+		GroupSync:              api.OIDCConfig.GroupSync(),
 		DebugContext: OauthDebugContext{
 			IDTokenClaims:  idtokenClaims,
 			UserInfoClaims: userInfoClaims,
@@ -1007,10 +1426,13 @@ func (api *API) userOIDC(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, redirect, http.StatusTemporaryRedirect)
 }
 
-// oidcGroups returns the groups for the user from the OIDC claims.
-func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interface{}) (bool, []string, *httpError) {
+// oidcGroups returns the groups for the user from the OIDC claims, as the
+// OIDC provider returned them and after nested groups are resolved and the
+// group mapping is applied.
+func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interface{}) (bool, []string, []string, *httpError) {
 	logger := api.Logger.Named(userAuthLoggerName)
 	usingGroups := false
+	var idpGroups []string
 	var groups []string
 
 	// If the GroupField is the empty string, then groups from OIDC are not used.
@@ -1029,7 +1451,7 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 					slog.F("type", fmt.Sprintf("%T", groupsRaw)),
 					slog.Error(err),
 				)
-				return false, nil, &httpError{
+				return false, nil, nil, &httpError{
 					code:             http.StatusBadRequest,
 					msg:              "Failed to sync groups from OIDC claims",
 					detail:           err.Error(),
@@ -1042,10 +1464,29 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 				slog.F("groups", parsedGroups),
 			)
 
-			for _, group := range parsedGroups {
-				if mappedGroup, ok := api.OIDCConfig.GroupMapping[group]; ok {
-					group = mappedGroup
+			idpGroups = parsedGroups
+			groupSync := api.OIDCConfig.GroupSync()
+			started := dbtime.Now()
+			flattened, err := groupSync.Flatten(ctx, parsedGroups)
+			if err != nil {
+				logger.Error(ctx, "failed to resolve nested oidc groups", slog.Error(err))
+				// The user isn't known yet, so the run isn't tied to them.
+				groupsync.RecordRun(ctx, logger, api.Database, database.InsertGroupSyncRunParams{
+					Trigger:     database.GroupSyncTriggerLogin,
+					StartedAt:   started,
+					UsersFailed: 1,
+					IDPGroups:   parsedGroups,
+					Error:       err.Error(),
+				})
+				return false, nil, nil, &httpError{
+					code:             http.StatusInternalServerError,
+					msg:              "Failed to resolve nested groups",
+					detail:           err.Error(),
+					renderStaticPage: true,
 				}
+			}
+
+			for _, group := range groupSync.Map(flattened) {
 				if _, ok := api.OIDCConfig.GroupAllowList[group]; ok {
 					inAllowList = true
 				}
@@ -1062,7 +1503,7 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 			if len(groups) == 0 {
 				detail = "You are currently not a member of any groups! Ask an administrator to add you to an authorized group to login."
 			}
-			return usingGroups, groups, &httpError{
+			return usingGroups, idpGroups, groups, &httpError{
 				code:             http.StatusForbidden,
 				msg:              "Not a member of an allowed group",
 				detail:           detail,
@@ -1077,7 +1518,7 @@ func (api *API) oidcGroups(ctx context.Context, mergedClaims map[string]interfac
 		logger.Debug(ctx, "claim 'groups' was returned, but 'oidc-group-field' is not set, check your coder oidc settings")
 	}
 
-	return usingGroups, groups, nil
+	return usingGroups, idpGroups, groups, nil
 }
 
 // oidcRoles returns the roles for the user from the OIDC claims.
@@ -1135,6 +1576,32 @@ func (api *API) oidcRoles(ctx context.Context, mergedClaims map[string]interface
 	return roles, nil
 }
 
+// oidcOrganizationRoles returns the organization roles granted by the
+// user's OIDC groups. Groups without an entry in the mapping grant no roles.
+func (api *API) oidcOrganizationRoles(ctx context.Context, groups []string) []string {
+	if !api.OIDCConfig.OrganizationRoleSyncEnabled() {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	roles := make([]string, 0)
+	for _, group := range groups {
+		for _, role := range api.OIDCConfig.GroupOrganizationRoleMapping[group] {
+			if _, ok := seen[role]; ok {
+				continue
+			}
+			seen[role] = struct{}{}
+			roles = append(roles, role)
+		}
+	}
+
+	api.Logger.Debug(ctx, "organization roles mapped from oidc groups",
+		slog.F("groups", groups),
+		slog.F("roles", roles),
+	)
+	return roles
+}
+
 // claimFields returns the sorted list of fields in the claims map.
 func claimFields(claims map[string]interface{}) []string {
 	fields := []string{}
@@ -1192,17 +1659,27 @@ type oauthLoginParams struct {
 	Username     string
 	AvatarURL    string
 	// Is UsingGroups is true, then the user will be assigned
-	// to the Groups provided.
+	// to the Groups provided, once GroupSync filters them. IDPGroups are the
+	// groups as the auth provider returned them.
 	UsingGroups         bool
 	CreateMissingGroups bool
+	IDPGroups           []string
 	Groups              []string
-	GroupFilter         *regexp.Regexp
+	GroupSync           groupsync.Config
 	// Is UsingRoles is true, then the user will be assigned
 	// the roles provided.
 	UsingRoles bool
 	Roles      []string
+	// If UsingOrganizationRoles is true, then the user's organization roles
+	// will be replaced with the roles provided.
+	UsingOrganizationRoles bool
+	OrganizationRoles      []string
 
 	DebugContext OauthDebugContext
+	// HoldSession is called once the user has been synced, before their
+	// session is created. If it returns true, no session is created, like
+	// when a second factor has to be answered first.
+	HoldSession func(user database.User) (bool, error)
 
 	commitLock       sync.Mutex
 	initAuditRequest func(params *audit.RequestParams) *audit.Request[database.User]
@@ -1269,12 +1746,20 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		logger  = api.Logger.Named(userAuthLoggerName)
 	)
 
-	var isConvertLoginType bool
+	groupSyncRun := database.InsertGroupSyncRunParams{
+		Trigger:   database.GroupSyncTriggerLogin,
+		IDPGroups: params.IDPGroups,
+	}
+	var (
+		isConvertLoginType bool
+		userCreated        bool
+	)
 	err := api.Database.InTx(func(tx database.Store) error {
 		var (
 			link database.UserLink
 			err  error
 		)
+		userCreated = false
 
 		user = params.User
 		link = params.Link
@@ -1367,6 +1852,7 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			if err != nil {
 				return xerrors.Errorf("create user: %w", err)
 			}
+			userCreated = true
 		}
 
 		// Activate dormant user on sigin
@@ -1425,18 +1911,12 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 
 		// Ensure groups are correct.
 		if params.UsingGroups {
-			filtered := params.Groups
-			if params.GroupFilter != nil {
-				filtered = make([]string, 0, len(params.Groups))
-				for _, group := range params.Groups {
-					if params.GroupFilter.MatchString(group) {
-						filtered = append(filtered, group)
-					}
-				}
-			}
+			groupSyncRun.StartedAt = dbtime.Now()
+			groupSyncRun.UserID = uuid.NullUUID{UUID: user.ID, Valid: true}
+			groupSyncRun.Groups = params.GroupSync.Filter(params.Groups)
 
 			//nolint:gocritic
-			err := api.Options.SetUserGroups(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, filtered, params.CreateMissingGroups)
+			err := api.Options.SetUserGroups(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, groupSyncRun.Groups, params.CreateMissingGroups)
 			if err != nil {
 				return xerrors.Errorf("set user groups: %w", err)
 			}
@@ -1473,6 +1953,15 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 			}
 		}
 
+		// Ensure organization roles are correct.
+		if params.UsingOrganizationRoles {
+			//nolint:gocritic
+			err := api.Options.SetUserOrganizationRoles(dbauthz.AsSystemRestricted(ctx), logger, tx, user.ID, params.OrganizationRoles)
+			if err != nil {
+				return xerrors.Errorf("set user organization roles: %w", err)
+			}
+		}
+
 		needsUpdate := false
 		if user.AvatarURL != params.AvatarURL {
 			user.AvatarURL = params.AvatarURL
@@ -1513,9 +2002,23 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 
 		return nil
 	}, nil)
+	// Record the group sync once the transaction is over, so a failed login
+	// is recorded as a failed sync.
+	if groupSyncRun.UserID.Valid {
+		if err != nil {
+			groupSyncRun.UsersFailed = 1
+			groupSyncRun.Error = err.Error()
+		} else {
+			groupSyncRun.UsersSynced = 1
+		}
+		groupsync.RecordRun(ctx, logger, api.Database, groupSyncRun)
+	}
 	if err != nil {
 		return nil, database.APIKey{}, xerrors.Errorf("in tx: %w", err)
 	}
+	if userCreated {
+		api.PublishUserCreated(ctx, user)
+	}
 
 	var key database.APIKey
 	oldKey, _, ok := httpmw.APIKeyFromRequest(ctx, api.Database, nil, r)
@@ -1543,12 +2046,22 @@ func (api *API) oauthLogin(r *http.Request, params *oauthLoginParams) ([]*http.C
 		// as the user needs to be forced to log back in.
 		key = *oldKey
 	} else {
+		if params.HoldSession != nil {
+			hold, err := params.HoldSession(user)
+			if err != nil {
+				return nil, database.APIKey{}, xerrors.Errorf("hold session: %w", err)
+			}
+			if hold {
+				return nil, database.APIKey{}, nil
+			}
+		}
 		//nolint:gocritic
 		cookie, newKey, err := api.createAPIKey(dbauthz.AsSystemRestricted(ctx), apikey.CreateParams{
 			UserID:           user.ID,
 			LoginType:        params.LoginType,
 			DeploymentValues: api.DeploymentValues,
 			RemoteAddr:       r.RemoteAddr,
+			Roles:            user.RBACRoles,
 		})
 		if err != nil {
 			return nil, database.APIKey{}, xerrors.Errorf("create API key: %w", err)
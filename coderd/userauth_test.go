@@ -8,7 +8,9 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/golang-jwt/jwt/v4"
@@ -18,6 +20,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -25,6 +28,10 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/ldapauth"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/twofactor/twofactortest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -162,6 +169,204 @@ func TestUserAuthMethods(t *testing.T) {
 	})
 }
 
+func TestUserLDAP(t *testing.T) {
+	t.Parallel()
+
+	const password = "SomeSecurePassword!"
+	authenticate := func(_ context.Context, username, pass string) (ldapauth.User, error) {
+		if pass != password {
+			return ldapauth.User{}, ldapauth.ErrInvalidCredentials
+		}
+		return ldapauth.User{
+			DN:       "uid=" + username + ",ou=people,dc=example,dc=com",
+			Username: username,
+			Email:    username + "@coder.com",
+			Disabled: username == "disabled",
+		}, nil
+	}
+	login := func(t *testing.T, client *codersdk.Client, username, pass string) (*codersdk.Client, error) {
+		t.Helper()
+		ctx := testutil.Context(t, testutil.WaitLong)
+		res, err := client.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{
+			Username: username,
+			Password: pass,
+		})
+		if err != nil {
+			return nil, err
+		}
+		userClient := codersdk.New(client.URL)
+		userClient.SetSessionToken(res.SessionToken)
+		return userClient, nil
+	}
+	requireStatus := func(t *testing.T, err error, status int) {
+		t.Helper()
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, status, apiErr.StatusCode())
+	}
+
+	t.Run("Signup", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			Auditor: auditor,
+			LDAPConfig: &coderd.LDAPConfig{
+				Authenticate: authenticate,
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		methods, err := client.AuthMethods(ctx)
+		require.NoError(t, err)
+		require.True(t, methods.LDAP.Enabled)
+
+		userClient, err := login(t, client, "alice", password)
+		require.NoError(t, err)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, "alice@coder.com", user.Email)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			Action: database.AuditActionRegister,
+			UserID: user.ID,
+		}))
+
+		// Logging in again uses the same user.
+		userClient, err = login(t, client, "alice", password)
+		require.NoError(t, err)
+		again, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, again.ID)
+
+		_, err = login(t, client, "alice", "wrong")
+		requireStatus(t, err, http.StatusUnauthorized)
+		_, err = login(t, client, "disabled", password)
+		requireStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("SignupsDisabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{Authenticate: authenticate},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := login(t, client, "alice", password)
+		requireStatus(t, err, http.StatusForbidden)
+	})
+
+	t.Run("Suspended", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Authenticate: authenticate,
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		userClient, err := login(t, client, "alice", password)
+		require.NoError(t, err)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		_, err = client.UpdateUserStatus(ctx, user.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+
+		_, err = login(t, client, "alice", password)
+		requireStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("SecondFactor", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			LDAPConfig: &coderd.LDAPConfig{
+				Authenticate: authenticate,
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		origin := client.URL.Scheme + "://" + client.URL.Host
+		anon := codersdk.New(client.URL)
+		_, err := client.UpsertTwoFactorPolicy(ctx, rbac.RoleMember(), codersdk.UpsertTwoFactorPolicyRequest{
+			EnforceAfter: time.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		// The user is created before the passkey is asked for.
+		resp, err := anon.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{Username: "alice", Password: password})
+		require.NoError(t, err)
+		require.Empty(t, resp.SessionToken)
+		require.NotNil(t, resp.PasskeyEnrollment)
+		authenticator := twofactortest.New(t)
+		resp, err = anon.LoginWithPasskeyEnrollment(ctx, authenticator.Register(t, *resp.PasskeyEnrollment, origin, "laptop"))
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.SessionToken)
+
+		resp, err = anon.LoginWithLDAP(ctx, codersdk.LoginWithLDAPRequest{Username: "alice", Password: password})
+		require.NoError(t, err)
+		require.Empty(t, resp.SessionToken)
+		require.NotNil(t, resp.SecondFactor)
+		resp, err = anon.LoginWithSecondFactor(ctx, codersdk.LoginWithSecondFactorRequest{
+			ChallengeID: resp.SecondFactor.ChallengeID,
+			Passkey:     ptr.Ref(authenticator.Assert(t, resp.SecondFactor.Options, origin)),
+		})
+		require.NoError(t, err)
+		userClient := codersdk.New(client.URL)
+		userClient.SetSessionToken(resp.SessionToken)
+		user, err := userClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, "alice", user.Username)
+		require.Equal(t, codersdk.LoginTypeLDAP, user.LoginType)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.LoginThrottle.BackoffThreshold = 0
+		dv.LoginThrottle.LockoutThreshold = 3
+		dv.LoginThrottle.LockoutDuration = clibase.Duration(time.Hour)
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: dv,
+			LDAPConfig: &coderd.LDAPConfig{
+				Authenticate: authenticate,
+				AllowSignups: true,
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		// A successful login forgets the failures before it.
+		_, err := login(t, client, "alice", "wrong")
+		requireStatus(t, err, http.StatusUnauthorized)
+		_, err = login(t, client, "alice", password)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err = login(t, client, "alice", "wrong")
+			requireStatus(t, err, http.StatusUnauthorized)
+		}
+		_, err = login(t, client, "alice", password)
+		requireStatus(t, err, http.StatusTooManyRequests)
+
+		// Other directory users aren't affected.
+		_, err = login(t, client, "bob", password)
+		require.NoError(t, err)
+	})
+
+	t.Run("NotEnabled", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		_, err := login(t, client, "alice", password)
+		requireStatus(t, err, http.StatusNotFound)
+	})
+}
+
 // nolint:bodyclose
 func TestUserOAuth2Github(t *testing.T) {
 	t.Parallel()
@@ -182,6 +387,11 @@ func TestUserOAuth2Github(t *testing.T) {
 						},
 					}}, nil
 				},
+				AuthenticatedUser: func(ctx context.Context, client *http.Client) (*github.User, error) {
+					return &github.User{
+						Login: github.String("kyle"),
+					}, nil
+				},
 			},
 		})
 
@@ -553,6 +763,106 @@ func TestUserOAuth2Github(t *testing.T) {
 		require.Len(t, auditor.AuditLogs(), numLogs)
 		require.Equal(t, database.AuditActionRegister, auditor.AuditLogs()[numLogs-1].Action)
 	})
+	t.Run("SuspendedWhenRemovedFromTeam", func(t *testing.T) {
+		t.Parallel()
+		var removed atomic.Bool
+		client := coderdtest.New(t, &coderdtest.Options{
+			GithubOAuth2Config: &coderd.GithubOAuth2Config{
+				AllowSignups:       true,
+				AllowOrganizations: []string{"coder"},
+				AllowTeams:         []coderd.GithubOAuth2Team{{"coder", "frontend"}},
+				OAuth2Config:       &testutil.OAuth2Config{},
+				ListOrganizationMemberships: func(ctx context.Context, client *http.Client) ([]*github.Membership, error) {
+					return []*github.Membership{{
+						State: &stateActive,
+						Organization: &github.Organization{
+							Login: github.String("coder"),
+						},
+					}}, nil
+				},
+				TeamMembership: func(ctx context.Context, client *http.Client, org, team, username string) (*github.Membership, error) {
+					if removed.Load() {
+						return nil, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+					}
+					return &github.Membership{}, nil
+				},
+				AuthenticatedUser: func(ctx context.Context, client *http.Client) (*github.User, error) {
+					return &github.User{
+						Login: github.String("kyle"),
+						ID:    i64ptr(1234),
+					}, nil
+				},
+				ListEmails: func(ctx context.Context, client *http.Client) ([]*github.UserEmail, error) {
+					return []*github.UserEmail{{
+						Email:    github.String("kyle@coder.com"),
+						Verified: github.Bool(true),
+						Primary:  github.Bool(true),
+					}}, nil
+				},
+			},
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		resp := oauth2Callback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		// Membership is checked again on the next login.
+		removed.Store(true)
+		resp = oauth2Callback(t, client)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		user, err := client.User(ctx, "kyle")
+		require.NoError(t, err)
+		require.Equal(t, codersdk.UserStatusSuspended, user.Status)
+	})
+	t.Run("CachedTeamMembershipOnGithubError", func(t *testing.T) {
+		t.Parallel()
+		var unavailable atomic.Bool
+		client := coderdtest.New(t, &coderdtest.Options{
+			GithubOAuth2Config: &coderd.GithubOAuth2Config{
+				AllowSignups:       true,
+				AllowOrganizations: []string{"coder"},
+				AllowTeams:         []coderd.GithubOAuth2Team{{"coder", "frontend"}},
+				OAuth2Config:       &testutil.OAuth2Config{},
+				ListOrganizationMemberships: func(ctx context.Context, client *http.Client) ([]*github.Membership, error) {
+					return []*github.Membership{{
+						State: &stateActive,
+						Organization: &github.Organization{
+							Login: github.String("coder"),
+						},
+					}}, nil
+				},
+				TeamMembership: func(ctx context.Context, client *http.Client, org, team, username string) (*github.Membership, error) {
+					if unavailable.Load() {
+						return nil, xerrors.New("github is down")
+					}
+					return &github.Membership{}, nil
+				},
+				AuthenticatedUser: func(ctx context.Context, client *http.Client) (*github.User, error) {
+					return &github.User{
+						Login: github.String("kyle"),
+						ID:    i64ptr(1234),
+					}, nil
+				},
+				ListEmails: func(ctx context.Context, client *http.Client) ([]*github.UserEmail, error) {
+					return []*github.UserEmail{{
+						Email:    github.String("kyle@coder.com"),
+						Verified: github.Bool(true),
+						Primary:  github.Bool(true),
+					}}, nil
+				},
+			},
+		})
+
+		resp := oauth2Callback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+
+		// The previous lookup is used when GitHub fails to respond.
+		unavailable.Store(true)
+		resp = oauth2Callback(t, client)
+		require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	})
 	t.Run("SignupFailedInactiveInOrg", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{
@@ -12,10 +12,12 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbreplica"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/gitsshkey"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -190,6 +192,7 @@ func (api *API) postFirstUser(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	api.PublishUserCreated(ctx, user)
 
 	telemetryUser := telemetry.ConvertUser(user)
 	// Send the initial users email address!
@@ -311,6 +314,80 @@ func (api *API) GetUsers(rw http.ResponseWriter, r *http.Request) ([]database.Us
 	return users, userRows[0].Count, true
 }
 
+// defaultInactiveDays is the number of days without activity after which a
+// user is reported as inactive, unless the report asks for another number.
+const defaultInactiveDays = 30
+
+// @Summary Get inactive users
+// @ID get-inactive-users
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param days query int false "Days without activity, defaults to 30"
+// @Success 200 {object} codersdk.InactiveUsersResponse
+// @Router /users/inactive [get]
+func (api *API) inactiveUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	days := p.Int(vals, defaultInactiveDays, "days")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) == 0 && days < 1 {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "days",
+			Detail: "Must be at least 1.",
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	inactiveSince := dbtime.Now().AddDate(0, 0, -days)
+	rows, err := api.Database.GetInactiveUsers(ctx, inactiveSince)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching inactive users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.InactiveUsersResponse{
+		InactiveSince: inactiveSince,
+		Users:         make([]codersdk.InactiveUser, 0, len(rows)),
+	}
+	for _, row := range rows {
+		user := codersdk.InactiveUser{
+			ID:         row.ID,
+			Username:   row.Username,
+			Email:      row.Email,
+			AvatarURL:  row.AvatarURL,
+			Status:     codersdk.UserStatus(row.Status),
+			LastSeenAt: row.LastSeenAt,
+		}
+		if row.LastActivityCategory.Valid {
+			user.LastActivity = &codersdk.UserAPIActivity{
+				Category: row.LastActivityCategory.String,
+				Hour:     row.LastActivityHour.Time,
+			}
+			if row.LastActivityWorkspaceID.Valid && row.LastActivityWorkspaceID.UUID != uuid.Nil {
+				workspaceID := row.LastActivityWorkspaceID.UUID
+				user.LastActivity.WorkspaceID = &workspaceID
+			}
+		}
+		resp.Users = append(resp.Users, user)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 // Creates a new user.
 //
 // @Summary Create new user
@@ -441,6 +518,8 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 		loginType = database.LoginTypeOIDC
 	case codersdk.LoginTypeGithub:
 		loginType = database.LoginTypeGithub
+	case codersdk.LoginTypeLDAP:
+		loginType = database.LoginTypeLDAP
 	default:
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf("Unsupported login type %q for manually creating new users.", req.UserLoginType),
@@ -466,12 +545,20 @@ func (api *API) postUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = user
+	api.PublishUserCreated(ctx, user)
 
 	// Report when users are added!
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Users: []telemetry.User{telemetry.ConvertUser(user)},
 	})
 
+	labels := map[string]string{}
+	creator, err := api.Database.GetUserByID(ctx, httpmw.APIKey(r).UserID)
+	if err == nil {
+		labels["created_by"] = creator.Username
+	}
+	api.Notifications.Enqueue(ctx, user.ID, codersdk.NotificationTemplateUserAccountCreated, labels)
+
 	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.User(user, []uuid.UUID{req.OrganizationID}))
 }
 
@@ -504,7 +591,9 @@ func (api *API) deleteUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+	// A workspace created just before must stop the deletion, so the check
+	// can't be served by a replica that hasn't caught up yet.
+	workspaces, err := api.Database.GetWorkspaces(dbreplica.WithPrimary(ctx), database.GetWorkspacesParams{
 		OwnerID: user.ID,
 	})
 	if err != nil {
@@ -817,6 +906,103 @@ func (api *API) putUserAppearanceSettings(rw http.ResponseWriter, r *http.Reques
 	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.User(updatedUser, organizationIDs))
 }
 
+// @Summary Get user IP allowlist
+// @ID get-user-ip-allowlist
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.UserIPAllowlist
+// @Router /users/{user}/ip-allowlist [get]
+func (api *API) userIPAllowlist(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx  = r.Context()
+		user = httpmw.UserParam(r)
+	)
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserIPAllowlist(user))
+}
+
+// @Summary Update user IP allowlist
+// @ID update-user-ip-allowlist
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserIPAllowlistRequest true "New IP allowlist"
+// @Success 200 {object} codersdk.UserIPAllowlist
+// @Router /users/{user}/ip-allowlist [put]
+func (api *API) putUserIPAllowlist(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		user              = httpmw.UserParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.User](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = user
+
+	var params codersdk.UpdateUserIPAllowlistRequest
+	if !httpapi.Read(ctx, rw, r, &params) {
+		return
+	}
+	allowlist, err := apikey.NormalizeIPAllowlist(params.IPAllowlist)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid IP allowlist.",
+			Detail:  err.Error(),
+			Validations: []codersdk.ValidationError{{
+				Field:  "ip_allowlist",
+				Detail: "Must only contain CIDR ranges or IP addresses.",
+			}},
+		})
+		return
+	}
+	// Prevent admins from locking themselves out.
+	if apiKey.UserID == user.ID && !database.IPAllowlistContains(allowlist, httpmw.RequestAddr(r)) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "You cannot set an IP allowlist that excludes your own address.",
+			Detail:  fmt.Sprintf("Your address is %s.", r.RemoteAddr),
+		})
+		return
+	}
+
+	updatedUser, err := api.Database.UpdateUserIPAllowlist(ctx, database.UpdateUserIPAllowlistParams{
+		ID:          user.ID,
+		IPAllowlist: allowlist,
+		UpdatedAt:   dbtime.Now(),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updatedUser
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertUserIPAllowlist(updatedUser))
+}
+
+func convertUserIPAllowlist(user database.User) codersdk.UserIPAllowlist {
+	allowlist := user.IPAllowlist
+	if allowlist == nil {
+		allowlist = []string{}
+	}
+	return codersdk.UserIPAllowlist{IPAllowlist: allowlist}
+}
+
 // @Summary Update user password
 // @ID update-user-password
 // @Security CoderSessionToken
@@ -1057,17 +1243,14 @@ func (api *API) putUserRoles(rw http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateSiteUserRoles will ensure only site wide roles are passed in as arguments.
-// If an organization role is included, an error is returned.
+// If an organization role is included, an error is returned. Roles that are
+// not built in must be custom roles, which the database layer validates.
 func UpdateSiteUserRoles(ctx context.Context, db database.Store, args database.UpdateUserRolesParams) (database.User, error) {
 	// Enforce only site wide roles.
 	for _, r := range args.GrantedRoles {
 		if _, ok := rbac.IsOrgRole(r); ok {
 			return database.User{}, xerrors.Errorf("Must only update site wide roles")
 		}
-
-		if _, err := rbac.RoleByName(r); err != nil {
-			return database.User{}, xerrors.Errorf("%q is not a supported role", r)
-		}
 	}
 
 	updatedUser, err := db.UpdateUserRoles(ctx, args)
@@ -1164,7 +1347,7 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 	}
 
 	var user database.User
-	return user, req.OrganizationID, store.InTx(func(tx database.Store) error {
+	err := store.InTx(func(tx database.Store) error {
 		orgRoles := make([]string, 0)
 		// If no organization is provided, create a new one for the user.
 		if req.OrganizationID == uuid.Nil {
@@ -1248,6 +1431,18 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		}
 		return nil
 	}, nil)
+	return user, req.OrganizationID, err
+}
+
+// PublishUserCreated sends the user.created webhook event. CreateUser may run
+// inside a caller's transaction, so callers must only publish once the
+// outermost transaction has committed.
+func (api *API) PublishUserCreated(ctx context.Context, user database.User) {
+	api.Webhooks.Publish(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	})
 }
 
 func convertUsers(users []database.User, organizationIDsByUserID map[uuid.UUID][]uuid.UUID) []codersdk.User {
@@ -1281,16 +1476,32 @@ func usernameWithID(id uuid.UUID, users []database.User) (string, bool) {
 }
 
 func convertAPIKey(k database.APIKey) codersdk.APIKey {
+	scopes := make([]codersdk.APITokenScope, 0, len(k.Scopes))
+	for _, scope := range k.Scopes {
+		scopes = append(scopes, codersdk.APITokenScope(scope))
+	}
+	allowedWorkspaceIDs := k.AllowedWorkspaceIDs
+	if allowedWorkspaceIDs == nil {
+		allowedWorkspaceIDs = []uuid.UUID{}
+	}
+	ipAllowlist := k.IPAllowlist
+	if ipAllowlist == nil {
+		ipAllowlist = []string{}
+	}
+
 	return codersdk.APIKey{
-		ID:              k.ID,
-		UserID:          k.UserID,
-		LastUsed:        k.LastUsed,
-		ExpiresAt:       k.ExpiresAt,
-		CreatedAt:       k.CreatedAt,
-		UpdatedAt:       k.UpdatedAt,
-		LoginType:       codersdk.LoginType(k.LoginType),
-		Scope:           codersdk.APIKeyScope(k.Scope),
-		LifetimeSeconds: k.LifetimeSeconds,
-		TokenName:       k.TokenName,
+		ID:                  k.ID,
+		UserID:              k.UserID,
+		LastUsed:            k.LastUsed,
+		ExpiresAt:           k.ExpiresAt,
+		CreatedAt:           k.CreatedAt,
+		UpdatedAt:           k.UpdatedAt,
+		LoginType:           codersdk.LoginType(k.LoginType),
+		Scope:               codersdk.APIKeyScope(k.Scope),
+		LifetimeSeconds:     k.LifetimeSeconds,
+		TokenName:           k.TokenName,
+		Scopes:              scopes,
+		AllowedWorkspaceIDs: allowedWorkspaceIDs,
+		IPAllowlist:         ipAllowlist,
 	}
 }
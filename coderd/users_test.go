@@ -1084,6 +1084,62 @@ func TestInitialRoles(t *testing.T) {
 	}, "should be a member and admin")
 }
 
+func TestUserIPAllowlist(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	// Members can't change their own allowlist.
+	_, err := member.UpdateUserIPAllowlist(ctx, codersdk.Me, codersdk.UpdateUserIPAllowlistRequest{
+		IPAllowlist: []string{"127.0.0.1"},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	allowlist, err := client.UpdateUserIPAllowlist(ctx, memberUser.ID.String(), codersdk.UpdateUserIPAllowlistRequest{
+		IPAllowlist: []string{"192.0.2.1/24"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.0/24"}, allowlist.IPAllowlist)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		ResourceType: database.ResourceTypeUser,
+		ResourceID:   memberUser.ID,
+		Action:       database.AuditActionWrite,
+	}))
+	allowlist, err = client.UserIPAllowlist(ctx, memberUser.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, []string{"192.0.2.0/24"}, allowlist.IPAllowlist)
+
+	// The test client connects over loopback, so the member is rejected.
+	_, err = member.User(ctx, codersdk.Me)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Admins can't lock themselves out.
+	_, err = client.UpdateUserIPAllowlist(ctx, codersdk.Me, codersdk.UpdateUserIPAllowlistRequest{
+		IPAllowlist: []string{"192.0.2.0/24"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.UpdateUserIPAllowlist(ctx, codersdk.Me, codersdk.UpdateUserIPAllowlistRequest{
+		IPAllowlist: []string{"not-an-ip"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	allowlist, err = client.UpdateUserIPAllowlist(ctx, memberUser.ID.String(), codersdk.UpdateUserIPAllowlistRequest{})
+	require.NoError(t, err)
+	require.Empty(t, allowlist.IPAllowlist)
+	_, err = member.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+}
+
 func TestPutUserSuspend(t *testing.T) {
 	t.Parallel()
 
@@ -1673,6 +1729,62 @@ func TestDormantUser(t *testing.T) {
 	require.Equal(t, codersdk.UserStatusActive, users.Users[0].Status)
 }
 
+func TestInactiveUsers(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	longAgo := dbtime.Now().Add(-60 * 24 * time.Hour)
+	inactive := dbgen.User(t, db, database.User{
+		CreatedAt:  longAgo,
+		LastSeenAt: longAgo,
+	})
+	workspaceID := uuid.New()
+	err := db.UpsertUserActivity(dbauthz.AsSystemRestricted(context.Background()), database.UpsertUserActivityParams{
+		UserID:      []uuid.UUID{inactive.ID},
+		Hour:        []time.Time{longAgo.Truncate(time.Hour)},
+		Category:    []string{"workspaces"},
+		WorkspaceID: []uuid.UUID{workspaceID},
+		Count:       []int32{2},
+	})
+	require.NoError(t, err)
+	// Suspended users are not reported, however long ago they were seen.
+	_ = dbgen.User(t, db, database.User{
+		CreatedAt:  longAgo,
+		LastSeenAt: longAgo,
+		Status:     database.UserStatusSuspended,
+	})
+	// Users that have never been seen are not reported until they have
+	// existed for the whole period.
+	_ = dbgen.User(t, db, database.User{})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	resp, err := client.InactiveUsers(ctx, 30)
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	require.Equal(t, inactive.ID, resp.Users[0].ID)
+	require.NotNil(t, resp.Users[0].LastActivity)
+	require.Equal(t, "workspaces", resp.Users[0].LastActivity.Category)
+	require.NotNil(t, resp.Users[0].LastActivity.WorkspaceID)
+	require.Equal(t, workspaceID, *resp.Users[0].LastActivity.WorkspaceID)
+
+	resp, err = client.InactiveUsers(ctx, 90)
+	require.NoError(t, err)
+	require.Empty(t, resp.Users)
+
+	_, err = client.InactiveUsers(ctx, 0)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Members can't list the other users.
+	_, err = memberClient.InactiveUsers(ctx, 30)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
+
 // TestSuspendedPagination is when the after_id is a suspended record.
 // The database query should still return the correct page, as the after_id
 // is in a subquery that finds the record regardless of its status.
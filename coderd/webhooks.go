@@ -0,0 +1,293 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+// maxWebhookDeliveries is the number of recent deliveries returned for a
+// webhook.
+const maxWebhookDeliveries = 100
+
+// @Summary Get webhooks
+// @ID get-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Success 200 {array} codersdk.Webhook
+// @Router /webhooks [get]
+func (api *API) webhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	webhooks, err := api.Database.GetWebhooks(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.Webhooks(webhooks))
+}
+
+// @Summary Create webhook
+// @ID create-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Webhooks
+// @Param request body codersdk.CreateWebhookRequest true "Create webhook request"
+// @Success 201 {object} codersdk.Webhook
+// @Router /webhooks [post]
+func (api *API) postWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceWebhook) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if err := api.Webhooks.ValidateURL(req.URL); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid webhook URL.",
+			Validations: []codersdk.ValidationError{
+				{Field: "url", Detail: fmt.Sprintf("%q %s", req.URL, err)},
+			},
+		})
+		return
+	}
+
+	events := make([]string, 0, len(req.Events))
+	seen := make(map[codersdk.WebhookEvent]bool, len(req.Events))
+	for _, event := range req.Events {
+		if !event.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Unknown webhook event %q.", event),
+				Validations: []codersdk.ValidationError{
+					{Field: "events", Detail: fmt.Sprintf("must be one of %v", codersdk.WebhookEvents)},
+				},
+			})
+			return
+		}
+		if seen[event] {
+			continue
+		}
+		seen[event] = true
+		events = append(events, string(event))
+	}
+
+	secret, err := cryptorand.String(32)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating webhook secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	now := dbtime.Now()
+	webhook, err := api.Database.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        uuid.New(),
+		Url:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = webhook
+
+	resp := db2sdk.Webhook(webhook)
+	resp.Secret = webhook.Secret
+	httpapi.Write(ctx, rw, http.StatusCreated, resp)
+}
+
+// @Summary Delete webhook
+// @ID delete-webhook
+// @Security CoderSessionToken
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 204
+// @Router /webhooks/{webhook} [delete]
+func (api *API) deleteWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = *api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Webhook](rw, &audit.RequestParams{
+			Audit:   auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = webhook
+
+	err := api.Database.DeleteWebhookByID(ctx, webhook.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get webhook deliveries
+// @ID get-webhook-deliveries
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Success 200 {array} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries [get]
+func (api *API) webhookDeliveries(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := api.Database.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+		WebhookID: webhook.ID,
+		LimitOpt:  maxWebhookDeliveries,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook deliveries.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, db2sdk.WebhookDeliveries(deliveries))
+}
+
+// @Summary Replay webhook delivery
+// @ID replay-webhook-delivery
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Webhooks
+// @Param webhook path string true "Webhook ID" format(uuid)
+// @Param delivery path string true "Delivery ID" format(uuid)
+// @Success 201 {object} codersdk.WebhookDelivery
+// @Router /webhooks/{webhook}/deliveries/{delivery}/replay [post]
+func (api *API) replayWebhookDelivery(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	webhook, ok := api.webhookParam(rw, r)
+	if !ok {
+		return
+	}
+	deliveryID, ok := httpmw.ParseUUIDParam(rw, r, "delivery")
+	if !ok {
+		return
+	}
+
+	delivery, err := api.Database.GetWebhookDeliveryByID(ctx, deliveryID)
+	if httpapi.Is404Error(err) || (err == nil && delivery.WebhookID != webhook.ID) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook delivery.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if db2sdk.WebhookDelivery(delivery).Status != codersdk.WebhookDeliveryStatusFailed {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only failed deliveries can be replayed.",
+		})
+		return
+	}
+
+	replay, err := api.Webhooks.Replay(ctx, delivery)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error replaying webhook delivery.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, db2sdk.WebhookDelivery(replay))
+}
+
+func templateWebhookData(template database.Template) codersdk.WebhookTemplateData {
+	return codersdk.WebhookTemplateData{
+		ID:              template.ID,
+		OrganizationID:  template.OrganizationID,
+		Name:            template.Name,
+		ActiveVersionID: template.ActiveVersionID,
+	}
+}
+
+// webhookParam fetches the webhook from the "webhook" URL parameter, writing
+// an error response if it cannot be found.
+func (api *API) webhookParam(rw http.ResponseWriter, r *http.Request) (database.Webhook, bool) {
+	ctx := r.Context()
+	webhookID, ok := httpmw.ParseUUIDParam(rw, r, "webhook")
+	if !ok {
+		return database.Webhook{}, false
+	}
+
+	webhook, err := api.Database.GetWebhookByID(ctx, webhookID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.Webhook{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook.",
+			Detail:  err.Error(),
+		})
+		return database.Webhook{}, false
+	}
+	return webhook, true
+}
@@ -0,0 +1,430 @@
+// Package webhooks delivers signed JSON events to HTTPS endpoints registered
+// by deployment administrators.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	defaultMaxAttempts  = 5
+	defaultRetryBackoff = 30 * time.Second
+	defaultPollInterval = 10 * time.Second
+	defaultBatchSize    = 10
+	defaultTimeout      = 10 * time.Second
+	// defaultLeaseDuration is how long an acquired delivery is held before
+	// another replica may attempt it.
+	defaultLeaseDuration = time.Minute
+
+	// maxResponseBytes is how much of a failed response body is stored as
+	// the delivery error.
+	maxResponseBytes = 1024
+)
+
+// Publisher enqueues events for delivery to every webhook subscribed to
+// them. Errors are logged rather than returned so that a misbehaving webhook
+// never fails the operation that produced the event.
+type Publisher interface {
+	Publish(ctx context.Context, event codersdk.WebhookEvent, data any)
+}
+
+// NewNoop returns a Publisher that discards all events.
+func NewNoop() Publisher {
+	return noopPublisher{}
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, codersdk.WebhookEvent, any) {}
+
+// Sign returns the signature of body for the given secret, in the format sent
+// in the codersdk.WebhookSignatureHeader header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Options struct {
+	// HTTPClient is used to send deliveries. Defaults to a client with a
+	// short timeout.
+	HTTPClient *http.Client
+	// MaxAttempts is the number of attempts made before a delivery is
+	// marked as failed.
+	MaxAttempts int32
+	// RetryBackoff is the delay before the first retry. It doubles with
+	// every subsequent attempt.
+	RetryBackoff time.Duration
+	// PollInterval is how often deliveries that are due are looked up.
+	// Newly published events are attempted immediately.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of deliveries attempted at once.
+	BatchSize int32
+	// LeaseDuration is how long acquired deliveries are held before another
+	// replica may attempt them. The deliveries of a batch are sent
+	// concurrently, and requests still in flight after three quarters of the
+	// lease are canceled, so the results are recorded before it expires.
+	LeaseDuration time.Duration
+	// AllowPrivateAddresses permits deliveries to loopback, private and
+	// link-local addresses. When false, the HTTPClient transport is wrapped
+	// so that connections to those addresses are refused, including ones
+	// reached through redirects or hostnames that resolve to them. This
+	// also applies to the address of a proxy configured on the transport.
+	AllowPrivateAddresses bool
+}
+
+// ErrPrivateAddress is returned when a webhook URL refers to an address that
+// deliveries may not be sent to.
+var ErrPrivateAddress = xerrors.New("address is not publicly routable")
+
+// Dispatcher stores published events as deliveries and sends them in the
+// background, retrying failures with exponential backoff.
+type Dispatcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	wake   chan struct{}
+
+	db   database.Store
+	log  slog.Logger
+	opts Options
+}
+
+var _ Publisher = (*Dispatcher)(nil)
+
+// New returns a Dispatcher and starts delivering events.
+func New(ctx context.Context, db database.Store, log slog.Logger, opts Options) *Dispatcher {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if !opts.AllowPrivateAddresses {
+		client := *opts.HTTPClient
+		client.Transport = publicTransport(client.Transport)
+		opts.HTTPClient = &client
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultRetryBackoff
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = defaultLeaseDuration
+	}
+
+	//nolint:gocritic // The dispatcher manages deliveries for all webhooks.
+	ctx, cancel := context.WithCancel(dbauthz.AsSystemRestricted(ctx))
+	d := &Dispatcher{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		wake:   make(chan struct{}, 1),
+		db:     db,
+		log:    log,
+		opts:   opts,
+	}
+	go d.run()
+	return d
+}
+
+// ValidateURL returns an error if deliveries can't be sent to raw. Only
+// absolute HTTPS URLs are accepted and, unless private addresses are allowed,
+// hosts that are loopback, private or link-local are refused. Hostnames are
+// checked again when they're resolved for each delivery.
+func (d *Dispatcher) ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return xerrors.Errorf("parse url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return xerrors.New("must be an absolute HTTPS URL")
+	}
+	if d.opts.AllowPrivateAddresses {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !isPublicAddr(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// Publish enqueues the event for every webhook subscribed to it.
+func (d *Dispatcher) Publish(ctx context.Context, event codersdk.WebhookEvent, data any) {
+	err := d.publish(ctx, event, data)
+	if err != nil {
+		d.log.Error(ctx, "publish webhook event", slog.F("event", event), slog.Error(err))
+	}
+}
+
+func (d *Dispatcher) publish(ctx context.Context, event codersdk.WebhookEvent, data any) error {
+	//nolint:gocritic // Events are published on behalf of the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	webhooks, err := d.db.GetWebhooksByEvent(ctx, string(event))
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return xerrors.Errorf("marshal data: %w", err)
+	}
+	now := dbtime.Now()
+	payload, err := json.Marshal(codersdk.WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		Timestamp: now,
+		Data:      raw,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+
+	for _, webhook := range webhooks {
+		_, err := d.db.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			ID:            uuid.New(),
+			WebhookID:     webhook.ID,
+			Event:         string(event),
+			Payload:       payload,
+			CreatedAt:     now,
+			NextAttemptAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("insert delivery for webhook %s: %w", webhook.ID, err)
+		}
+	}
+	d.Wake()
+	return nil
+}
+
+// Replay enqueues a new delivery with the same payload as the given one. The
+// caller's context is used to authorize the insert.
+func (d *Dispatcher) Replay(ctx context.Context, delivery database.WebhookDelivery) (database.WebhookDelivery, error) {
+	now := dbtime.Now()
+	replay, err := d.db.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+		ID:            uuid.New(),
+		WebhookID:     delivery.WebhookID,
+		Event:         delivery.Event,
+		Payload:       delivery.Payload,
+		CreatedAt:     now,
+		NextAttemptAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return database.WebhookDelivery{}, xerrors.Errorf("insert delivery: %w", err)
+	}
+	d.Wake()
+	return replay, nil
+}
+
+// Wake attempts due deliveries without waiting for the next poll.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops delivering events. Deliveries that are in flight are retried
+// once their lease expires.
+func (d *Dispatcher) Close() error {
+	d.cancel()
+	<-d.done
+	return nil
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		err := d.deliverDue()
+		if err != nil && d.ctx.Err() == nil {
+			d.log.Warn(d.ctx, "deliver webhooks", slog.Error(err))
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue() error {
+	now := dbtime.Now()
+	deliveries, err := d.db.AcquireWebhookDeliveries(d.ctx, database.AcquireWebhookDeliveriesParams{
+		LeaseUntil: now.Add(d.opts.LeaseDuration),
+		Now:        now,
+		LimitOpt:   d.opts.BatchSize,
+	})
+	if err != nil {
+		return xerrors.Errorf("acquire deliveries: %w", err)
+	}
+
+	// One slow receiver must not hold up the rest of the batch past the
+	// lease, or another replica would send the same deliveries again. The
+	// last quarter of the lease is left for recording the results.
+	sendCtx, cancel := context.WithDeadline(d.ctx, now.Add(d.opts.LeaseDuration*3/4))
+	defer cancel()
+	var eg errgroup.Group
+	for _, delivery := range deliveries {
+		delivery := delivery
+		eg.Go(func() error {
+			webhook, err := d.db.GetWebhookByID(d.ctx, delivery.WebhookID)
+			if err != nil {
+				// The webhook was deleted along with its deliveries.
+				if xerrors.Is(err, sql.ErrNoRows) {
+					return nil
+				}
+				return xerrors.Errorf("get webhook %s: %w", delivery.WebhookID, err)
+			}
+			err = d.attempt(sendCtx, webhook, delivery)
+			if err != nil {
+				return xerrors.Errorf("update delivery %s: %w", delivery.ID, err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// attempt sends a single delivery and records the result. The request is
+// canceled when ctx is done, but the result is recorded regardless.
+func (d *Dispatcher) attempt(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) error {
+	statusCode, sendErr := d.send(ctx, webhook, delivery)
+
+	params := database.UpdateWebhookDeliveryByIDParams{
+		ID:         delivery.ID,
+		Attempts:   delivery.Attempts + 1,
+		StatusCode: sql.NullInt32{Int32: int32(statusCode), Valid: statusCode != 0},
+	}
+	switch {
+	case sendErr == nil:
+		params.DeliveredAt = sql.NullTime{Time: dbtime.Now(), Valid: true}
+	case params.Attempts >= d.opts.MaxAttempts:
+		params.LastError = sendErr.Error()
+		d.log.Warn(d.ctx, "webhook delivery failed permanently",
+			slog.F("webhook_id", webhook.ID),
+			slog.F("delivery_id", delivery.ID),
+			slog.F("attempts", params.Attempts),
+			slog.Error(sendErr),
+		)
+	default:
+		params.LastError = sendErr.Error()
+		backoff := d.opts.RetryBackoff << (params.Attempts - 1)
+		params.NextAttemptAt = sql.NullTime{Time: dbtime.Now().Add(backoff), Valid: true}
+	}
+	return d.db.UpdateWebhookDeliveryByID(d.ctx, params)
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Coder/"+buildinfo.Version())
+	req.Header.Set(codersdk.WebhookEventHeader, delivery.Event)
+	req.Header.Set(codersdk.WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(codersdk.WebhookSignatureHeader, Sign(webhook.Secret, delivery.Payload))
+
+	res, err := d.opts.HTTPClient.Do(req)
+	if err != nil {
+		return 0, xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
+		return res.StatusCode, xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return res.StatusCode, nil
+}
+
+// publicTransport returns a copy of rt that refuses to connect to addresses
+// that aren't publicly routable. The check runs on the resolved address of
+// every connection, so DNS rebinding and redirects can't bypass it.
+func publicTransport(rt http.RoundTripper) http.RoundTripper {
+	var transport *http.Transport
+	switch typed := rt.(type) {
+	case nil:
+		//nolint:forcetypeassert // http.DefaultTransport is always an *http.Transport.
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = typed.Clone()
+	default:
+		// There's no dialer to guard, so custom transports are responsible
+		// for their own checks.
+		return rt
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublicAddr(ip) {
+				return xerrors.Errorf("dial %s: %w", address, ErrPrivateAddress)
+			}
+			return nil
+		},
+	}
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,328 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/webhooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delivers", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan *http.Request, 1)
+		bodies := make(chan []byte, 1)
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			received <- r
+			bodies <- body
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, nil)
+		)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:    srv.URL,
+			Events: []string{string(codersdk.WebhookEventUserCreated)},
+		})
+
+		dispatcher := webhooks.New(ctx, db, log, webhooks.Options{
+			HTTPClient:            srv.Client(),
+			AllowPrivateAddresses: true,
+		})
+		defer dispatcher.Close()
+		dispatcher.Publish(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{Username: "bob"})
+
+		req := testutil.RequireRecvCtx(ctx, t, received)
+		body := testutil.RequireRecvCtx(ctx, t, bodies)
+		require.Equal(t, string(codersdk.WebhookEventUserCreated), req.Header.Get(codersdk.WebhookEventHeader))
+		require.Equal(t, webhooks.Sign(webhook.Secret, body), req.Header.Get(codersdk.WebhookSignatureHeader))
+
+		var payload codersdk.WebhookPayload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		require.Equal(t, codersdk.WebhookEventUserCreated, payload.Event)
+		var data codersdk.WebhookUserData
+		require.NoError(t, json.Unmarshal(payload.Data, &data))
+		require.Equal(t, "bob", data.Username)
+
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			if !assert.NoError(t, err) || !assert.Len(t, deliveries, 1) {
+				return false
+			}
+			return deliveries[0].DeliveredAt.Valid &&
+				deliveries[0].ID.String() == req.Header.Get(codersdk.WebhookDeliveryHeader)
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("RetriesThenFails", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("oops"))
+		}))
+		t.Cleanup(srv.Close)
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:    srv.URL,
+			Events: []string{string(codersdk.WebhookEventTemplateUpdated)},
+		})
+
+		dispatcher := webhooks.New(ctx, db, log, webhooks.Options{
+			HTTPClient:            srv.Client(),
+			MaxAttempts:           2,
+			RetryBackoff:          time.Millisecond,
+			PollInterval:          testutil.IntervalFast,
+			AllowPrivateAddresses: true,
+		})
+		defer dispatcher.Close()
+		dispatcher.Publish(ctx, codersdk.WebhookEventTemplateUpdated, codersdk.WebhookTemplateData{})
+
+		var delivery database.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			if !assert.NoError(t, err) || len(deliveries) != 1 {
+				return false
+			}
+			delivery = deliveries[0]
+			return delivery.Attempts == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.False(t, delivery.DeliveredAt.Valid)
+		require.False(t, delivery.NextAttemptAt.Valid)
+		require.Equal(t, int32(http.StatusInternalServerError), delivery.StatusCode.Int32)
+		require.Contains(t, delivery.LastError, "oops")
+	})
+
+	t.Run("SlowReceiver", func(t *testing.T) {
+		t.Parallel()
+
+		// The slow receiver doesn't answer until the test is over.
+		var slowRequests atomic.Int32
+		release := make(chan struct{})
+		slow := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			slowRequests.Add(1)
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		t.Cleanup(slow.Close)
+		t.Cleanup(func() { close(release) })
+		fast := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(fast.Close)
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		)
+		slowWebhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:    slow.URL,
+			Events: []string{string(codersdk.WebhookEventUserCreated)},
+		})
+		fastWebhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:    fast.URL,
+			Events: []string{string(codersdk.WebhookEventUserCreated)},
+		})
+
+		// Both servers use the same certificate, so either client trusts
+		// both of them.
+		dispatcher := webhooks.New(ctx, db, log, webhooks.Options{
+			HTTPClient:            slow.Client(),
+			MaxAttempts:           1,
+			PollInterval:          testutil.IntervalFast,
+			LeaseDuration:         2 * time.Second,
+			AllowPrivateAddresses: true,
+		})
+		defer dispatcher.Close()
+		dispatcher.Publish(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{})
+
+		delivery := func(webhookID uuid.UUID) database.WebhookDelivery {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhookID,
+			})
+			require.NoError(t, err)
+			require.Len(t, deliveries, 1)
+			return deliveries[0]
+		}
+
+		// The fast receiver isn't held up by the slow one.
+		require.Eventually(t, func() bool {
+			return delivery(fastWebhook.ID).DeliveredAt.Valid
+		}, time.Second, testutil.IntervalFast)
+
+		// The slow request is canceled and its result recorded before the
+		// lease expires, so it is sent exactly once.
+		require.Eventually(t, func() bool {
+			return delivery(slowWebhook.ID).Attempts == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		slowDelivery := delivery(slowWebhook.ID)
+		require.False(t, slowDelivery.DeliveredAt.Valid)
+		require.Contains(t, slowDelivery.LastError, context.DeadlineExceeded.Error())
+		require.EqualValues(t, 1, slowRequests.Load())
+	})
+
+	t.Run("RefusesPrivateAddresses", func(t *testing.T) {
+		t.Parallel()
+
+		received := make(chan struct{}, 1)
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- struct{}{}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Url:    srv.URL,
+			Events: []string{string(codersdk.WebhookEventUserCreated)},
+		})
+
+		dispatcher := webhooks.New(ctx, db, log, webhooks.Options{
+			HTTPClient:   srv.Client(),
+			MaxAttempts:  1,
+			PollInterval: testutil.IntervalFast,
+		})
+		defer dispatcher.Close()
+		dispatcher.Publish(ctx, codersdk.WebhookEventUserCreated, codersdk.WebhookUserData{})
+
+		var delivery database.WebhookDelivery
+		require.Eventually(t, func() bool {
+			deliveries, err := db.GetWebhookDeliveriesByWebhookID(ctx, database.GetWebhookDeliveriesByWebhookIDParams{
+				WebhookID: webhook.ID,
+			})
+			if !assert.NoError(t, err) || len(deliveries) != 1 {
+				return false
+			}
+			delivery = deliveries[0]
+			return delivery.Attempts == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.False(t, delivery.DeliveredAt.Valid)
+		require.Contains(t, delivery.LastError, webhooks.ErrPrivateAddress.Error())
+		require.Empty(t, received)
+	})
+
+	t.Run("IgnoresUnsubscribedEvents", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx   = testutil.Context(t, testutil.WaitLong)
+			db, _ = dbtestutil.NewDB(t)
+			log   = slogtest.Make(t, nil)
+		)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Events: []string{string(codersdk.WebhookEventUserCreated)},
+		})
+
+		dispatcher := webhooks.New(ctx, db, log, webhooks.Options{})
+		defer dispatcher.Close()
+		dispatcher.Publish(ctx, codersdk.WebhookEventTemplateUpdated, codersdk.WebhookTemplateData{})
+
+		deliveries, err := db.GetWebhookDeliveriesByWebhookID(context.Background(), database.GetWebhookDeliveriesByWebhookIDParams{
+			WebhookID: webhook.ID,
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+}
+
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = testutil.Context(t, testutil.WaitShort)
+		db, _ = dbtestutil.NewDB(t)
+		log   = slogtest.Make(t, nil)
+	)
+	dispatcher := webhooks.New(ctx, db, log, webhooks.Options{})
+	defer dispatcher.Close()
+	permissive := webhooks.New(ctx, db, log, webhooks.Options{AllowPrivateAddresses: true})
+	defer permissive.Close()
+
+	for _, tc := range []struct {
+		url     string
+		public  bool
+		private bool
+	}{
+		{url: "https://example.com/hook", public: true, private: true},
+		{url: "https://93.184.216.34/hook", public: true, private: true},
+		{url: "http://example.com/hook"},
+		{url: "example.com/hook"},
+		{url: "https://localhost/hook", private: true},
+		{url: "https://api.localhost/hook", private: true},
+		{url: "https://127.0.0.1:8443/hook", private: true},
+		{url: "https://10.0.0.1/hook", private: true},
+		{url: "https://169.254.169.254/latest/meta-data", private: true},
+		{url: "https://[::1]/hook", private: true},
+		{url: "https://[::ffff:192.168.0.1]/hook", private: true},
+		{url: "https://0.0.0.0/hook", private: true},
+	} {
+		err := dispatcher.ValidateURL(tc.url)
+		if tc.public {
+			require.NoError(t, err, tc.url)
+		} else {
+			require.Error(t, err, tc.url)
+		}
+		err = permissive.ValidateURL(tc.url)
+		if tc.private {
+			require.NoError(t, err, tc.url)
+		} else {
+			require.Error(t, err, tc.url)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// Computed with: printf 'hello' | openssl dgst -sha256 -hmac secret
+	require.Equal(t,
+		"sha256=88aab3ede8d3adf94d26ab90d3bafd4a2083070c3bcce9c014ee04a443847c0b",
+		webhooks.Sign("secret", []byte("hello")),
+	)
+}
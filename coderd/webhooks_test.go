@@ -0,0 +1,162 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL: "https://example.com/coder",
+			Events: []codersdk.WebhookEvent{
+				codersdk.WebhookEventTemplateUpdated,
+				codersdk.WebhookEventTemplateUpdated,
+			},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, webhook.Secret)
+		require.Equal(t, []codersdk.WebhookEvent{codersdk.WebhookEventTemplateUpdated}, webhook.Events)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeWebhook,
+			ResourceID:   webhook.ID,
+			Action:       database.AuditActionCreate,
+		}))
+
+		webhooks, err := client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, webhook.ID, webhooks[0].ID)
+		require.Empty(t, webhooks[0].Secret)
+
+		err = client.DeleteWebhook(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.True(t, auditor.Contains(t, database.AuditLog{
+			ResourceType: database.ResourceTypeWebhook,
+			ResourceID:   webhook.ID,
+			Action:       database.AuditActionDelete,
+		}))
+		webhooks, err = client.Webhooks(ctx)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+
+	t.Run("RequiresHTTPS", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "http://example.com/coder",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("RefusesPrivateAddresses", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, u := range []string{
+			"https://localhost/coder",
+			"https://127.0.0.1/coder",
+			"https://169.254.169.254/latest/meta-data",
+		} {
+			_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+				URL:    u,
+				Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, u)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), u)
+		}
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.WebhooksAllowPrivateAddresses = true
+		client = coderdtest.New(t, &coderdtest.Options{DeploymentValues: dv})
+		_ = coderdtest.CreateFirstUser(t, client)
+		_, err := client.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "https://127.0.0.1/coder",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		owner := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.Webhooks(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.CreateWebhook(ctx, codersdk.CreateWebhookRequest{
+			URL:    "https://example.com/coder",
+			Events: []codersdk.WebhookEvent{codersdk.WebhookEventUserCreated},
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		t.Parallel()
+
+		client, db := coderdtest.NewWithDatabase(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			// Nothing listens here, so deliveries fail quickly.
+			Url: "https://127.0.0.1:1/coder",
+		})
+		failed := dbgen.WebhookDelivery(t, db, database.WebhookDelivery{
+			WebhookID: webhook.ID,
+		})
+
+		deliveries, err := client.WebhookDeliveries(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, codersdk.WebhookDeliveryStatusFailed, deliveries[0].Status)
+
+		replay, err := client.ReplayWebhookDelivery(ctx, webhook.ID, failed.ID)
+		require.NoError(t, err)
+		require.NotEqual(t, failed.ID, replay.ID)
+		require.Equal(t, codersdk.WebhookDeliveryStatusPending, replay.Status)
+
+		// Only failed deliveries can be replayed.
+		_, err = client.ReplayWebhookDelivery(ctx, webhook.ID, replay.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,103 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentPortShares(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: owner.OrganizationID,
+		OwnerID:        memberUser.ID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Name = "dev"
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	share, err := member.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  "dev",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelAuthenticated,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAgentPortShareLevelAuthenticated, share.ShareLevel)
+	require.Equal(t, "8080--dev--"+r.Workspace.Name+"--"+memberUser.Username, share.SubdomainName)
+
+	// Sharing the port again changes its share level.
+	share, err = member.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  "dev",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+	})
+	require.NoError(t, err)
+	require.Equal(t, codersdk.WorkspaceAgentPortShareLevelPublic, share.ShareLevel)
+
+	shares, err := member.WorkspaceAgentPortShares(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.WorkspaceAgentPortShare{share}, shares)
+
+	var apiErr *codersdk.Error
+	_, err = member.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  "missing",
+		Port:       8080,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = member.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  "dev",
+		Port:       8080,
+		ShareLevel: "owner",
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Ports denied by the template can't be shared.
+	_, err = client.UpdateTemplateMeta(ctx, r.Workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		PortForwardingPolicy: &codersdk.TemplatePortForwardingPolicy{
+			DenyByDefault: true,
+			AllowedPorts:  []uint16{8080},
+		},
+	})
+	require.NoError(t, err)
+	_, err = member.UpsertWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+		AgentName:  "dev",
+		Port:       3000,
+		ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = member.DeleteWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+		AgentName: "dev",
+		Port:      8080,
+	})
+	require.NoError(t, err)
+	shares, err = member.WorkspaceAgentPortShares(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, shares)
+
+	err = member.DeleteWorkspaceAgentPortShare(ctx, r.Workspace.ID, codersdk.DeleteWorkspaceAgentPortShareRequest{
+		AgentName: "dev",
+		Port:      8080,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -30,6 +30,7 @@ import (
 	"cdr.dev/slog"
 	agentproto "github.com/coder/coder/v2/agent/proto"
 	"github.com/coder/coder/v2/coderd/agentapi"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/autobuild"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
@@ -61,6 +62,8 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		dbApps     []database.WorkspaceApp
 		scripts    []database.WorkspaceAgentScript
 		logSources []database.WorkspaceAgentLogSource
+		ports      []database.WorkspaceAgentPort
+		gpus       []database.WorkspaceAgentGPU
 	)
 
 	var eg errgroup.Group
@@ -78,6 +81,16 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		logSources, err = api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
 		return err
 	})
+	eg.Go(func() (err error) {
+		//nolint:gocritic // TODO: can we make this not require system restricted?
+		ports, err = api.Database.GetWorkspaceAgentPortsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+		return err
+	})
+	eg.Go(func() (err error) {
+		//nolint:gocritic // TODO: can we make this not require system restricted?
+		gpus, err = api.Database.GetWorkspaceAgentGPUsByAgentIDs(dbauthz.AsSystemRestricted(ctx), []uuid.UUID{workspaceAgent.ID})
+		return err
+	})
 	err := eg.Wait()
 	if httpapi.Is404Error(err) {
 		httpapi.ResourceNotFound(rw)
@@ -135,6 +148,8 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	apiAgent.DiscoveredApps = db2sdk.DiscoveredApps(ports, dbApps, workspaceAgent, owner.Username, workspace)
+	apiAgent.GPUs = db2sdk.WorkspaceAgentGPUs(gpus)
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiAgent)
 }
@@ -227,6 +242,7 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		MOTDFile:                 manifest.MotdPath,
 		DisableDirectConnections: manifest.DisableDirectConnections,
 		Metadata:                 agentproto.SDKAgentMetadataDescriptionsFromProto(manifest.Metadata),
+		QUICAddress:              api.agentQUICAddress(),
 	})
 }
 
@@ -298,6 +314,34 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 		seen[s] = true
 	}
 
+	gpus := database.UpsertWorkspaceAgentGPUsParams{
+		AgentID:       apiAgent.ID,
+		Index:         make([]int32, 0, len(req.GPUs)),
+		Vendor:        make([]string, 0, len(req.GPUs)),
+		Model:         make([]string, 0, len(req.GPUs)),
+		MemoryBytes:   make([]int64, 0, len(req.GPUs)),
+		DriverVersion: make([]string, 0, len(req.GPUs)),
+	}
+	seenGPUs := make(map[string]bool, len(req.GPUs))
+	for _, gpu := range req.GPUs {
+		// Each vendor indexes its GPUs separately, so the index is only
+		// unique per vendor.
+		key := fmt.Sprintf("%s/%d", gpu.Vendor, gpu.Index)
+		if gpu.Index < 0 || gpu.MemoryBytes < 0 || seenGPUs[key] {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid workspace agent GPU provided.",
+				Detail:  fmt.Sprintf("invalid or duplicate %s GPU %d", gpu.Vendor, gpu.Index),
+			})
+			return
+		}
+		seenGPUs[key] = true
+		gpus.Index = append(gpus.Index, gpu.Index)
+		gpus.Vendor = append(gpus.Vendor, gpu.Vendor)
+		gpus.Model = append(gpus.Model, gpu.Model)
+		gpus.MemoryBytes = append(gpus.MemoryBytes, gpu.MemoryBytes)
+		gpus.DriverVersion = append(gpus.DriverVersion, gpu.DriverVersion)
+	}
+
 	if err := api.Database.UpdateWorkspaceAgentStartupByID(ctx, database.UpdateWorkspaceAgentStartupByIDParams{
 		ID:                apiAgent.ID,
 		Version:           req.Version,
@@ -312,6 +356,14 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := api.Database.UpsertWorkspaceAgentGPUs(ctx, gpus); err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error setting agent GPUs",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, nil)
 }
 
@@ -760,6 +812,7 @@ func (api *API) workspaceAgentLogs(rw http.ResponseWriter, r *http.Request) {
 func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+	workspace := httpmw.WorkspaceParam(r)
 
 	apiAgent, err := db2sdk.WorkspaceAgent(
 		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
@@ -834,8 +887,20 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 		appPorts[uint16(portNum)] = struct{}{}
 	}
 
-	// Filter out ports that are globally blocked, in-use by applications, or
-	// common non-HTTP ports such as databases, FTP, SSH, etc.
+	// nolint:gocritic // The workspace is already authorized, and its owner may
+	//                 // not be allowed to read the template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Filter out ports that are globally blocked, in-use by applications,
+	// denied by the template, or common non-HTTP ports such as databases, FTP,
+	// SSH, etc.
 	filteredPorts := make([]codersdk.WorkspaceAgentListeningPort, 0, len(portsResponse.Ports))
 	for _, port := range portsResponse.Ports {
 		if port.Port < codersdk.WorkspaceAgentMinimumListeningPort {
@@ -847,6 +912,9 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 		if _, ok := codersdk.WorkspaceAgentIgnoredListeningPorts[port.Port]; ok {
 			continue
 		}
+		if !template.AllowsPortForwarding(port.Port) {
+			continue
+		}
 		filteredPorts = append(filteredPorts, port)
 	}
 
@@ -854,6 +922,223 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary Run netcheck from workspace agent
+// @Description The agent probes the DERP regions of the deployment, and reports
+// @Description the NAT type and UDP reachability of the workspace.
+// @ID run-netcheck-from-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentNetcheckResponse
+// @Router /workspaceagents/{workspaceagent}/netcheck [get]
+func (api *API) workspaceAgentNetcheck(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	apiAgent, err := db2sdk.WorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, nil, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	netcheck, err := agentConn.Netcheck(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error running netcheck.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, netcheck)
+}
+
+// @Summary Authorize port forwarding for workspace agent
+// @ID authorize-port-forwarding-for-workspace-agent
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param request body codersdk.AuthorizeWorkspaceAgentPortForwardRequest true "Port forward request"
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/port-forward [post]
+func (api *API) workspaceAgentAuthorizePortForward(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceAgent = httpmw.WorkspaceAgentParam(r)
+		workspace      = httpmw.WorkspaceParam(r)
+		auditor        = api.Auditor.Load()
+	)
+
+	var req codersdk.AuthorizeWorkspaceAgentPortForwardRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	portForwardInfo, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName: workspace.Name,
+		AgentName:     workspaceAgent.Name,
+		Port:          req.Port,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "marshal port forward info", slog.Error(err))
+	}
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionPortForward,
+		AdditionalFields: portForwardInfo,
+	})
+	defer commitAudit()
+
+	// nolint:gocritic // The workspace is already authorized, and its owner may
+	//                 // not be allowed to read the template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	if !template.AllowsPortForwarding(req.Port) {
+		// Only denied attempts are audited.
+		aReq.Old = workspace
+		aReq.New = workspace
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: fmt.Sprintf("Forwarding port %d of agent %q is not allowed by the template.", req.Port, workspaceAgent.Name),
+		})
+		return
+	}
+
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		api.recordConnectionLog(ctx, r, apiKey, workspace, workspaceAgent.Name, database.ConnectionTypePortForwarding, sql.NullInt32{Int32: int32(req.Port), Valid: true})
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace agent resource usage
+// @ID get-workspace-agent-resource-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceAgentResourceUsage
+// @Router /workspaceagents/{workspaceagent}/resource-usage [get]
+func (api *API) workspaceAgentResourceUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	stat, err := api.Database.GetLatestWorkspaceAgentStatByAgentID(ctx, workspaceAgent.ID)
+	if httpapi.Is404Error(err) {
+		// The agent hasn't reported any stats yet.
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentResourceUsage{})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent stats.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentResourceUsage{
+		CollectedAt:      &stat.CreatedAt,
+		CPUUsedCores:     stat.CPUUsedCores,
+		CPUTotalCores:    stat.CPUTotalCores,
+		MemoryUsedBytes:  stat.MemoryUsedBytes,
+		MemoryTotalBytes: stat.MemoryTotalBytes,
+		DiskUsedBytes:    stat.DiskUsedBytes,
+		DiskTotalBytes:   stat.DiskTotalBytes,
+	})
+}
+
+// Values are only kept when the deployment sets --agent-metadata-history.
+//
+// @Summary Get workspace agent metadata history
+// @ID get-workspace-agent-metadata-history
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param keys query string false "Comma-separated metadata keys"
+// @Param since query string false "Only values collected after this time" format(date-time)
+// @Success 200 {array} codersdk.WorkspaceAgentMetadataHistory
+// @Router /workspaceagents/{workspaceagent}/metadata-history [get]
+func (api *API) workspaceAgentMetadataHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	keys := p.Strings(vals, nil, "keys")
+	since := p.Time3339Nano(vals, time.Time{}, "since")
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	rows, err := api.Database.GetWorkspaceAgentMetadataHistory(ctx, database.GetWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		Since:            since,
+		Keys:             keys,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata history.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Rows are ordered by key, so each key's values are contiguous.
+	history := make([]codersdk.WorkspaceAgentMetadataHistory, 0)
+	for _, row := range rows {
+		if len(history) == 0 || history[len(history)-1].Key != row.Key {
+			history = append(history, codersdk.WorkspaceAgentMetadataHistory{
+				Key:    row.Key,
+				Values: []codersdk.WorkspaceAgentMetadataHistoryValue{},
+			})
+		}
+		last := &history[len(history)-1]
+		last.Values = append(last.Values, codersdk.WorkspaceAgentMetadataHistoryValue{
+			CollectedAt: row.CollectedAt,
+			Value:       row.Value,
+			Error:       row.Error,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, history)
+}
+
 // Deprecated: use api.tailnet.AgentConn instead.
 // See: https://github.com/coder/coder/issues/8218
 func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
@@ -1172,6 +1457,19 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Clients tell what the connection is for, so it can be recorded in the
+	// connection log.
+	connectionType := database.ConnectionType(r.URL.Query().Get("connection_type"))
+	if connectionType != "" && !connectionType.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid connection type.",
+			Validations: []codersdk.ValidationError{
+				{Field: "connection_type", Detail: fmt.Sprintf("%q is not a valid connection type", connectionType)},
+			},
+		})
+		return
+	}
+
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
@@ -1191,6 +1489,10 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 
 	go httpapi.Heartbeat(ctx, conn)
 
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok && connectionType != "" {
+		api.recordConnectionLog(ctx, r, apiKey, workspace, workspaceAgent.Name, connectionType, sql.NullInt32{})
+	}
+
 	defer conn.Close(websocket.StatusNormalClosure, "")
 	err = api.TailnetClientService.ServeClient(ctx, version, wsNetConn, uuid.New(), workspaceAgent.ID)
 	if err != nil && !xerrors.Is(err, io.EOF) && !xerrors.Is(err, context.Canceled) {
@@ -1231,6 +1533,7 @@ func convertScripts(dbScripts []database.WorkspaceAgentScript) []codersdk.Worksp
 			RunOnStop:        dbScript.RunOnStop,
 			StartBlocksLogin: dbScript.StartBlocksLogin,
 			Timeout:          time.Duration(dbScript.TimeoutSeconds) * time.Second,
+			Phase:            codersdk.WorkspaceAgentScriptPhase(dbScript.Phase),
 		})
 	}
 	return scripts
@@ -1313,6 +1616,12 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		SessionCountJetbrains:       req.SessionCountJetBrains,
 		SessionCountReconnectingPty: req.SessionCountReconnectingPTY,
 		SessionCountSsh:             req.SessionCountSSH,
+		CpuUsedCores:                req.CPUUsedCores,
+		CpuTotalCores:               req.CPUTotalCores,
+		MemoryUsedBytes:             req.MemoryUsedBytes,
+		MemoryTotalBytes:            req.MemoryTotalBytes,
+		DiskUsedBytes:               req.DiskUsedBytes,
+		DiskTotalBytes:              req.DiskTotalBytes,
 		Metrics:                     make([]*agentproto.Stats_Metric, len(req.Metrics)),
 	}
 	for i, metric := range req.Metrics {
@@ -1488,6 +1797,20 @@ func (api *API) workspaceAgentUpdateMetadata(ctx context.Context, workspaceAgent
 		return err
 	}
 
+	if keep := api.DeploymentValues.AgentMetadataHistory.Value(); keep > 0 {
+		err = api.Database.InsertWorkspaceAgentMetadataHistory(ctx, database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: workspaceAgent.ID,
+			Key:              datum.Key,
+			Value:            datum.Value,
+			Error:            datum.Error,
+			CollectedAt:      datum.CollectedAt,
+			Keep:             int32(keep),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	err = api.Pubsub.Publish(agentapi.WatchWorkspaceAgentMetadataChannel(workspaceAgent.ID), payload)
 	if err != nil {
 		return err
@@ -1806,6 +2129,204 @@ func (api *API) workspaceAgentReportLifecycle(rw http.ResponseWriter, r *http.Re
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// errWorkspaceAgentTokenReplaced is returned when an agent's token changed
+// before it could be rotated, either by a concurrent rotation or a revocation.
+var errWorkspaceAgentTokenReplaced = xerrors.New("workspace agent token was replaced")
+
+// workspaceAgentTokenAuditFields describes a change to an agent's token in an
+// audit log.
+func workspaceAgentTokenAuditFields(ctx context.Context, logger slog.Logger, workspace database.Workspace, agent database.WorkspaceAgent, change string) json.RawMessage {
+	fields, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName: workspace.Name,
+		AgentName:     agent.Name,
+		AgentToken:    change,
+	})
+	if err != nil {
+		logger.Warn(ctx, "marshal agent token audit fields", slog.Error(err))
+	}
+	return fields
+}
+
+// workspaceAgentTokenOverlap is how long an agent's previous token remains
+// valid after rotation, so requests already in flight with it still succeed.
+const workspaceAgentTokenOverlap = 15 * time.Minute
+
+// @Summary Rotate workspace agent token
+// @ID rotate-workspace-agent-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} agentsdk.RotateTokenResponse
+// @Router /workspaceagents/me/rotate-token [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceAgentRotateToken(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+
+	// The previous token is still accepted during the overlap, but must not
+	// be able to replace the token the agent is using.
+	token, err := uuid.Parse(httpmw.APITokenFromRequest(r))
+	if err != nil || token != workspaceAgent.AuthToken {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only the current workspace agent token can be rotated.",
+		})
+		return
+	}
+
+	row, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	workspace := row.Workspace
+
+	newToken := uuid.New()
+	expiresAt := dbtime.Now().Add(workspaceAgentTokenOverlap)
+	err = api.Database.InTx(func(tx database.Store) error {
+		// Concurrent rotations wait for each other here, and only the first
+		// one still holds the current token once it has the lock.
+		current, err := tx.GetWorkspaceAgentAuthTokenByIDForUpdate(ctx, workspaceAgent.ID)
+		if err != nil {
+			return xerrors.Errorf("lock auth token: %w", err)
+		}
+		if current != token {
+			return errWorkspaceAgentTokenReplaced
+		}
+		err = tx.UpsertWorkspaceAgentPreviousAuthToken(ctx, database.UpsertWorkspaceAgentPreviousAuthTokenParams{
+			AgentID:   workspaceAgent.ID,
+			AuthToken: workspaceAgent.AuthToken,
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert previous auth token: %w", err)
+		}
+		err = tx.UpdateWorkspaceAgentAuthTokenByID(ctx, database.UpdateWorkspaceAgentAuthTokenByIDParams{
+			ID:        workspaceAgent.ID,
+			AuthToken: newToken,
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update auth token: %w", err)
+		}
+		return nil
+	}, nil)
+	if errors.Is(err, errWorkspaceAgentTokenReplaced) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The workspace agent token was replaced while rotating it.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rotating workspace agent token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.Logger.Info(ctx, "rotated workspace agent token",
+		slog.F("workspace_agent_id", workspaceAgent.ID),
+		slog.F("previous_token_expires_at", expiresAt),
+	)
+	// The agent acts as the owner of the workspace, and has no API key to
+	// attribute the audit log to.
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.Workspace]{
+		Audit:            *api.Auditor.Load(),
+		Log:              api.Logger,
+		UserID:           workspace.OwnerID,
+		OrganizationID:   workspace.OrganizationID,
+		RequestID:        httpmw.RequestID(r),
+		IP:               r.RemoteAddr,
+		Status:           http.StatusOK,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: workspaceAgentTokenAuditFields(ctx, api.Logger, workspace, workspaceAgent, "rotated"),
+		Old:              workspace,
+		New:              workspace,
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.RotateTokenResponse{
+		SessionToken:           newToken.String(),
+		PreviousTokenExpiresAt: expiresAt,
+	})
+}
+
+// @Summary Revoke workspace agent token
+// @ID revoke-workspace-agent-token
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/revoke-token [post]
+func (api *API) workspaceAgentRevokeToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx            = r.Context()
+		workspaceAgent = httpmw.WorkspaceAgentParam(r)
+		workspace      = httpmw.WorkspaceParam(r)
+		auditor        = api.Auditor.Load()
+	)
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionWrite,
+		AdditionalFields: workspaceAgentTokenAuditFields(ctx, api.Logger, workspace, workspaceAgent, "revoked"),
+	})
+	aReq.Old = workspace
+	defer commitAudit()
+
+	// Anyone who may update the workspace can revoke its agents' tokens.
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	// The agent is given a token nobody knows, so it has to be rebuilt to
+	// connect again. The row is locked so a rotation in flight can't bring
+	// back a token derived from the revoked one.
+	err := api.Database.InTx(func(tx database.Store) error {
+		_, err := tx.GetWorkspaceAgentAuthTokenByIDForUpdate(ctx, workspaceAgent.ID)
+		if err != nil {
+			return xerrors.Errorf("lock auth token: %w", err)
+		}
+		err = tx.DeleteWorkspaceAgentPreviousAuthTokenByAgentID(ctx, workspaceAgent.ID)
+		if err != nil {
+			return xerrors.Errorf("delete previous auth token: %w", err)
+		}
+		err = tx.UpdateWorkspaceAgentAuthTokenByID(ctx, database.UpdateWorkspaceAgentAuthTokenByIDParams{
+			ID:        workspaceAgent.ID,
+			AuthToken: uuid.New(),
+			UpdatedAt: dbtime.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update auth token: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking workspace agent token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	err = api.Pubsub.Publish(workspaceAgentTokenRevokedChannel(workspaceAgent.ID), []byte{})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to publish agent token revocation",
+			slog.F("workspace_agent_id", workspaceAgent.ID), slog.Error(err))
+	}
+
+	aReq.New = workspace
+	api.Logger.Info(ctx, "revoked workspace agent token", slog.F("workspace_agent_id", workspaceAgent.ID))
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Submit workspace agent application health
 // @ID submit-workspace-agent-application-health
 // @Security CoderSessionToken
@@ -1839,7 +2360,10 @@ func (api *API) postWorkspaceAppHealth(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var newApps []database.WorkspaceApp
+	var (
+		newApps    []database.WorkspaceApp
+		oldHealths []database.WorkspaceAppHealth
+	)
 	for id, newHealth := range req.Healths {
 		old := func() *database.WorkspaceApp {
 			for _, app := range apps {
@@ -1882,6 +2406,7 @@ func (api *API) postWorkspaceAppHealth(rw http.ResponseWriter, r *http.Request)
 		if old.Health == database.WorkspaceAppHealth(newHealth) {
 			continue
 		}
+		oldHealths = append(oldHealths, old.Health)
 		old.Health = database.WorkspaceAppHealth(newHealth)
 
 		newApps = append(newApps, *old)
@@ -1925,11 +2450,93 @@ func (api *API) postWorkspaceAppHealth(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	for i, app := range newApps {
+		// The health has already been updated, so a missing event isn't
+		// worth failing the request over.
+		err = api.Database.InsertWorkspaceEvent(ctx, database.InsertWorkspaceEventParams{
+			ID:          uuid.New(),
+			WorkspaceID: workspace.ID,
+			CreatedAt:   dbtime.Now(),
+			Type:        database.WorkspaceEventTypeAppHealthChanged,
+			AgentID:     uuid.NullUUID{UUID: workspaceAgent.ID, Valid: true},
+			AppID:       uuid.NullUUID{UUID: app.ID, Valid: true},
+			OldValue:    string(oldHealths[i]),
+			NewValue:    string(app.Health),
+		})
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to insert workspace app health event",
+				slog.F("app_id", app.ID),
+				slog.Error(err),
+			)
+		}
+	}
 	api.publishWorkspaceUpdate(ctx, workspace.ID)
 
 	httpapi.Write(ctx, rw, http.StatusOK, nil)
 }
 
+// @Summary Submit workspace agent listening ports
+// @ID submit-workspace-agent-listening-ports
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Agents
+// @Param request body agentsdk.PostListeningPortsRequest true "Listening ports request"
+// @Success 204
+// @Router /workspaceagents/me/listening-ports [post]
+func (api *API) postWorkspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	var req agentsdk.PostListeningPortsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	params := database.UpsertWorkspaceAgentPortsParams{
+		AgentID:      workspaceAgent.ID,
+		Port:         make([]int32, 0, len(req.Ports)),
+		ProcessName:  make([]string, 0, len(req.Ports)),
+		DiscoveredAt: dbtime.Now(),
+	}
+	seen := make(map[uint16]struct{}, len(req.Ports))
+	for _, port := range req.Ports {
+		// Only TCP ports can be forwarded, and ports used by the agent itself
+		// are not apps of the workspace.
+		if port.Network != "tcp" || port.Port < codersdk.WorkspaceAgentMinimumListeningPort {
+			continue
+		}
+		if _, ok := codersdk.WorkspaceAgentIgnoredListeningPorts[port.Port]; ok {
+			continue
+		}
+		if _, ok := seen[port.Port]; ok {
+			continue
+		}
+		seen[port.Port] = struct{}{}
+		params.Port = append(params.Port, int32(port.Port))
+		params.ProcessName = append(params.ProcessName, port.ProcessName)
+	}
+
+	err := api.Database.UpsertWorkspaceAgentPorts(ctx, params)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating listening ports.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	row, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.publishWorkspaceUpdate(ctx, row.Workspace.ID)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // workspaceAgentsExternalAuth returns an access token for a given URL
 // or finds a provider by ID.
 //
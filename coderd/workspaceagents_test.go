@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -24,6 +26,7 @@ import (
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/agent/agenttest"
 	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/coderdtest/oidctest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -787,6 +790,129 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentFiles(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "artifacts", "build.log")
+
+	file, err := client.WorkspaceAgentUploadFile(ctx, agentID, path, strings.NewReader("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "build.log", file.Name)
+	require.Equal(t, path, file.Path)
+	require.EqualValues(t, 5, file.Size)
+
+	files, err := client.WorkspaceAgentListFiles(ctx, agentID, filepath.Dir(path))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Dir(path), files.Path)
+	require.Len(t, files.Files, 1)
+	require.Equal(t, path, files.Files[0].Path)
+	require.False(t, files.Files[0].IsDir)
+
+	content, err := client.WorkspaceAgentDownloadFile(ctx, agentID, path)
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	_ = content.Close()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	_, err = client.WorkspaceAgentDownloadFile(ctx, agentID, filepath.Join(dir, "missing"))
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	_, err = client.WorkspaceAgentDownloadFile(ctx, agentID, dir)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Users that can't open a terminal in the workspace can't access its files.
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err = member.WorkspaceAgentListFiles(ctx, agentID, dir)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestWorkspaceAgentNetcheck(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+	_ = agenttest.New(t, client.URL, r.AgentToken)
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	netcheck, err := client.WorkspaceAgentNetcheck(ctx, agentID)
+	require.NoError(t, err)
+	// The test DERP map has no STUN servers, so the netcheck itself may fail,
+	// but it must have been run by the agent.
+	require.True(t, netcheck.Netcheck != nil || netcheck.NetcheckErr != nil)
+	require.NotNil(t, netcheck.NetcheckLogs)
+
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err = member.WorkspaceAgentNetcheck(ctx, agentID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestWorkspaceAgentAuthorizePortForward(t *testing.T) {
+	t.Parallel()
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	// All ports can be forwarded by default.
+	err = client.AuthorizeWorkspaceAgentPortForward(ctx, agentID, 8080)
+	require.NoError(t, err)
+
+	_, err = client.UpdateTemplateMeta(ctx, r.Workspace.TemplateID, codersdk.UpdateTemplateMeta{
+		PortForwardingPolicy: &codersdk.TemplatePortForwardingPolicy{
+			DenyByDefault: true,
+			AllowedPorts:  []uint16{3000},
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.AuthorizeWorkspaceAgentPortForward(ctx, agentID, 3000)
+	require.NoError(t, err)
+
+	numLogs := len(auditor.AuditLogs())
+	err = client.AuthorizeWorkspaceAgentPortForward(ctx, agentID, 8080)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Only the denied attempt is audited.
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, numLogs+1)
+	require.Equal(t, database.AuditActionPortForward, logs[numLogs].Action)
+	require.Equal(t, r.Workspace.ID, logs[numLogs].ResourceID)
+	require.Contains(t, string(logs[numLogs].AdditionalFields), `"port":8080`)
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client, db := coderdtest.NewWithDatabase(t, nil)
@@ -870,6 +996,79 @@ func TestWorkspaceAgentAppHealth(t *testing.T) {
 	require.EqualValues(t, codersdk.WorkspaceAppHealthUnhealthy, manifest.Apps[1].Health)
 }
 
+func TestWorkspaceAgentDiscoveredApps(t *testing.T) {
+	t.Parallel()
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		// Subdomain names are only generated for named agents, the same as
+		// for regular apps.
+		agents[0].Name = "dev"
+		agents[0].Apps = []*proto.App{
+			{
+				Slug: "code-server",
+				Url:  "http://localhost:13337",
+			},
+		}
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(r.AgentToken)
+
+	err := agentClient.PostListeningPorts(ctx, agentsdk.PostListeningPortsRequest{
+		Ports: []codersdk.WorkspaceAgentListeningPort{
+			{ProcessName: "node", Network: "tcp", Port: 3000},
+			{ProcessName: "node", Network: "tcp", Port: 3000},
+			// Served by the code-server app.
+			{ProcessName: "code-server", Network: "tcp", Port: 13337},
+			// Below the minimum listening port.
+			{ProcessName: "sshd", Network: "tcp", Port: 22},
+		},
+	})
+	require.NoError(t, err)
+
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+	require.Len(t, agent.DiscoveredApps, 1)
+	discovered := agent.DiscoveredApps[0]
+	require.EqualValues(t, 3000, discovered.Port)
+	require.Equal(t, "node", discovered.ProcessName)
+	require.Equal(t, fmt.Sprintf("3000--dev--%s--%s", workspace.Name, workspace.OwnerName), discovered.SubdomainName)
+	require.False(t, discovered.DiscoveredAt.IsZero())
+
+	// Ports that are still listening keep the time they were discovered.
+	err = agentClient.PostListeningPorts(ctx, agentsdk.PostListeningPortsRequest{
+		Ports: []codersdk.WorkspaceAgentListeningPort{
+			{ProcessName: "python", Network: "tcp", Port: 8080},
+			{ProcessName: "node", Network: "tcp", Port: 3000},
+		},
+	})
+	require.NoError(t, err)
+
+	agent, err = client.WorkspaceAgent(ctx, agent.ID)
+	require.NoError(t, err)
+	require.Len(t, agent.DiscoveredApps, 2)
+	require.EqualValues(t, 3000, agent.DiscoveredApps[0].Port)
+	require.True(t, discovered.DiscoveredAt.Equal(agent.DiscoveredApps[0].DiscoveredAt))
+	require.EqualValues(t, 8080, agent.DiscoveredApps[1].Port)
+	require.Equal(t, "python", agent.DiscoveredApps[1].ProcessName)
+
+	// Ports that stopped listening are removed.
+	err = agentClient.PostListeningPorts(ctx, agentsdk.PostListeningPortsRequest{})
+	require.NoError(t, err)
+
+	agent, err = client.WorkspaceAgent(ctx, agent.ID)
+	require.NoError(t, err)
+	require.Empty(t, agent.DiscoveredApps)
+}
+
 func TestWorkspaceAgentReportStats(t *testing.T) {
 	t.Parallel()
 
@@ -994,6 +1193,52 @@ func TestWorkspaceAgentReportStats(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentResourceUsage(t *testing.T) {
+	t.Parallel()
+
+	client, db := coderdtest.NewWithDatabase(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent().Do()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	// No stats have been reported yet.
+	usage, err := client.WorkspaceAgentResourceUsage(ctx, agentID)
+	require.NoError(t, err)
+	require.Nil(t, usage.CollectedAt)
+
+	stat := dbgen.WorkspaceAgentStat(t, db, database.WorkspaceAgentStat{
+		CreatedAt:        dbtime.Now(),
+		UserID:           user.UserID,
+		WorkspaceID:      r.Workspace.ID,
+		TemplateID:       r.Workspace.TemplateID,
+		AgentID:          agentID,
+		CPUUsedCores:     1.5,
+		CPUTotalCores:    4,
+		MemoryUsedBytes:  1024,
+		MemoryTotalBytes: 4096,
+		DiskUsedBytes:    2048,
+		DiskTotalBytes:   8192,
+	})
+
+	usage, err = client.WorkspaceAgentResourceUsage(ctx, agentID)
+	require.NoError(t, err)
+	require.NotNil(t, usage.CollectedAt)
+	require.True(t, stat.CreatedAt.Equal(*usage.CollectedAt))
+	require.Equal(t, 1.5, usage.CPUUsedCores)
+	require.Equal(t, 4.0, usage.CPUTotalCores)
+	require.EqualValues(t, 1024, usage.MemoryUsedBytes)
+	require.EqualValues(t, 4096, usage.MemoryTotalBytes)
+	require.EqualValues(t, 2048, usage.DiskUsedBytes)
+	require.EqualValues(t, 8192, usage.DiskTotalBytes)
+}
+
 func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	t.Parallel()
 
@@ -1062,6 +1307,81 @@ func TestWorkspaceAgent_LifecycleState(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor := audit.NewMock()
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{Auditor: auditor})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        member.ID,
+	}).WithAgent().Do()
+	workspace, err := memberClient.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	oldClient := agentsdk.New(client.URL)
+	oldClient.SetSessionToken(r.AgentToken)
+	newClient := agentsdk.New(client.URL)
+	newClient.SetSessionToken(r.AgentToken)
+
+	resp, err := newClient.RotateToken(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, r.AgentToken, resp.SessionToken)
+	require.Equal(t, resp.SessionToken, newClient.SDK.SessionToken())
+	require.True(t, resp.PreviousTokenExpiresAt.After(time.Now()))
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		UserID:     member.ID,
+		ResourceID: r.Workspace.ID,
+		Action:     database.AuditActionWrite,
+	}))
+
+	// Both tokens work while the previous one has not expired.
+	_, err = newClient.Manifest(ctx)
+	require.NoError(t, err)
+	_, err = oldClient.Manifest(ctx)
+	require.NoError(t, err)
+
+	// The previous token can't be used to rotate again.
+	var sdkErr *codersdk.Error
+	_, err = oldClient.RotateToken(ctx)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+
+	conn, err := newClient.Listen(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Users who can't update the workspace can't revoke its agent tokens.
+	err = otherClient.RevokeWorkspaceAgentToken(ctx, agentID)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusNotFound, sdkErr.StatusCode())
+
+	auditor.ResetLogs()
+	err = memberClient.RevokeWorkspaceAgentToken(ctx, agentID)
+	require.NoError(t, err)
+	require.True(t, auditor.Contains(t, database.AuditLog{
+		UserID:     member.ID,
+		ResourceID: r.Workspace.ID,
+		Action:     database.AuditActionWrite,
+		StatusCode: http.StatusNoContent,
+	}))
+
+	// Revoking closes the agent's live connection.
+	testutil.RequireRecvCtx(ctx, t, conn.Closed())
+
+	_, err = newClient.Manifest(ctx)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+	_, err = oldClient.Manifest(ctx)
+	require.ErrorAs(t, err, &sdkErr)
+	require.Equal(t, http.StatusUnauthorized, sdkErr.StatusCode())
+}
+
 func TestWorkspaceAgent_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -1234,6 +1554,83 @@ func (s *testWAMErrorStore) GetWorkspaceAgentMetadata(ctx context.Context, arg d
 	return s.Store.GetWorkspaceAgentMetadata(ctx, arg)
 }
 
+func TestWorkspaceAgentMetadataHistory(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.AgentMetadataHistory = 3
+	client, db := coderdtest.NewWithDatabase(t, &coderdtest.Options{DeploymentValues: dv})
+	user := coderdtest.CreateFirstUser(t, client)
+	r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+		OrganizationID: user.OrganizationID,
+		OwnerID:        user.UserID,
+	}).WithAgent(func(agents []*proto.Agent) []*proto.Agent {
+		agents[0].Metadata = []*proto.Agent_Metadata{
+			{DisplayName: "Load", Key: "load", Script: "cat /proc/loadavg", Interval: 10, Timeout: 3},
+			{DisplayName: "Host", Key: "host", Script: "hostname", Interval: 10, Timeout: 3},
+		}
+		return agents
+	}).Do()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	workspace, err := client.Workspace(ctx, r.Workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(r.AgentToken)
+
+	// Nothing has been reported yet.
+	history, err := client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{})
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	for i := 1; i <= 5; i++ {
+		err := agentClient.PostMetadata(ctx, agentsdk.PostMetadataRequest{
+			Metadata: []agentsdk.Metadata{
+				{
+					Key: "load",
+					WorkspaceAgentMetadataResult: codersdk.WorkspaceAgentMetadataResult{
+						CollectedAt: time.Now(),
+						Value:       strconv.Itoa(i),
+					},
+				},
+				{
+					Key: "host",
+					WorkspaceAgentMetadataResult: codersdk.WorkspaceAgentMetadataResult{
+						CollectedAt: time.Now(),
+						Value:       "dev",
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// Only the last three values of each key are kept.
+	history, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "host", history[0].Key)
+	require.Len(t, history[0].Values, 3)
+	require.Equal(t, "load", history[1].Key)
+	values := make([]string, 0, len(history[1].Values))
+	for _, v := range history[1].Values {
+		values = append(values, v.Value)
+	}
+	require.Equal(t, []string{"3", "4", "5"}, values)
+
+	history, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Keys:  []string{"load"},
+		Since: history[1].Values[0].CollectedAt,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "load", history[0].Key)
+	require.Len(t, history[0].Values, 2)
+	require.Equal(t, "5", history[0].Values[1].Value)
+}
+
 func TestWorkspaceAgent_Metadata_CatchMemoryLeak(t *testing.T) {
 	t.Parallel()
 
@@ -1392,6 +1789,13 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 				codersdk.AgentSubsystemEnvbox,
 				codersdk.AgentSubsystemExectrace,
 			}
+			expectedGPUs = []codersdk.WorkspaceAgentGPU{{
+				Index:         0,
+				Vendor:        "nvidia",
+				Model:         "NVIDIA A100-SXM4-40GB",
+				MemoryBytes:   42949672960,
+				DriverVersion: "535.104.05",
+			}}
 		)
 
 		err := agentClient.PostStartup(ctx, agentsdk.PostStartupRequest{
@@ -1402,6 +1806,7 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 				expectedSubsystems[1],
 				expectedSubsystems[0],
 			},
+			GPUs: expectedGPUs,
 		})
 		require.NoError(t, err)
 
@@ -1415,6 +1820,8 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 		// Sorted
 		require.Equal(t, expectedSubsystems, wsagent.Subsystems)
 		require.Equal(t, coderd.AgentAPIVersionREST, wsagent.APIVersion)
+		require.Equal(t, expectedGPUs, wsagent.GPUs)
+		require.Equal(t, expectedGPUs, workspace.LatestBuild.Resources[0].Agents[0].GPUs)
 	})
 
 	t.Run("InvalidSemver", func(t *testing.T) {
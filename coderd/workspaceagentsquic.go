@@ -0,0 +1,215 @@
+package coderd
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/quic-go/quic-go"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	drpcsdk "github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+// agentQUICHandshakeTimeout bounds how long an agent has to authenticate
+// after opening a QUIC connection.
+const agentQUICHandshakeTimeout = 10 * time.Second
+
+// ListenAgentQUIC listens for workspace agent connections over QUIC on the
+// given UDP address. Pass the listener to New with Options.AgentQUICListener.
+func ListenAgentQUIC(address string, tlsConfig *tls.Config) (*quic.Listener, error) {
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{drpcsdk.QUICNextProto}
+	return quic.ListenAddr(address, tlsConfig, drpcsdk.QUICConfig())
+}
+
+// agentQUICAddress returns the address agents dial to connect over QUIC, or
+// an empty string if QUIC is disabled.
+func (api *API) agentQUICAddress() string {
+	if api.AgentQUICListener == nil {
+		return ""
+	}
+	addr, ok := api.AgentQUICListener.Addr().(*net.UDPAddr)
+	if !ok {
+		return ""
+	}
+	return net.JoinHostPort(api.AccessURL.Hostname(), strconv.Itoa(addr.Port))
+}
+
+// serveAgentQUIC accepts agent connections until the listener is closed.
+func (api *API) serveAgentQUIC(listener *quic.Listener) {
+	for {
+		conn, err := listener.Accept(api.ctx)
+		if err != nil {
+			if api.ctx.Err() == nil && !xerrors.Is(err, quic.ErrServerClosed) {
+				api.Logger.Warn(api.ctx, "accept agent QUIC connection", slog.Error(err))
+			}
+			return
+		}
+
+		api.WebsocketWaitMutex.Lock()
+		api.WebsocketWaitGroup.Add(1)
+		api.WebsocketWaitMutex.Unlock()
+		go func() {
+			defer api.WebsocketWaitGroup.Done()
+			api.workspaceAgentQUIC(conn)
+		}()
+	}
+}
+
+// workspaceAgentQUIC serves the agent RPC API over a QUIC connection. It is
+// the QUIC equivalent of workspaceAgentRPC.
+func (api *API) workspaceAgentQUIC(conn quic.Connection) {
+	// The connection lives as long as the handler, and the handler stops
+	// when either coderd shuts down or the agent disconnects.
+	ctx, cancel := context.WithCancel(api.ctx)
+	defer cancel()
+	context.AfterFunc(conn.Context(), cancel)
+	context.AfterFunc(ctx, func() { _ = conn.CloseWithError(0, "") })
+	logger := api.Logger.With(slog.F("remote_addr", conn.RemoteAddr().String()))
+
+	row, build, err := api.authenticateAgentQUIC(ctx, conn)
+	if err != nil {
+		logger.Debug(ctx, "reject agent QUIC connection", slog.Error(err))
+		return
+	}
+	workspaceAgent := row.WorkspaceAgent
+
+	subject, err := httpmw.WorkspaceAgentSubject(ctx, api.Database, row)
+	if err != nil {
+		logger.Error(ctx, "expand workspace owner roles", slog.Error(err))
+		return
+	}
+	ctx = dbauthz.As(ctx, subject)
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, build.WorkspaceID)
+	if err != nil {
+		logger.Error(ctx, "fetch workspace", slog.Error(err))
+		return
+	}
+
+	logger.Debug(ctx, "accepting agent QUIC connection",
+		slog.F("owner", row.OwnerName),
+		slog.F("workspace", workspace.Name),
+		slog.F("name", workspaceAgent.Name),
+	)
+
+	monitor := api.startAgentWebsocketMonitor(ctx, workspaceAgent, build, quicPinger{conn: conn})
+	defer monitor.close()
+
+	agentAPI := api.newAgentAPI(workspaceAgent, build)
+
+	streamID := tailnet.StreamID{
+		Name: fmt.Sprintf("%s-%s-%s", row.OwnerName, workspace.Name, workspaceAgent.Name),
+		ID:   workspaceAgent.ID,
+		Auth: tailnet.AgentTunnelAuth{},
+	}
+	ctx = tailnet.WithStreamID(ctx, streamID)
+	err = agentAPI.Serve(ctx, drpcsdk.QUICListener(conn))
+	if err != nil && ctx.Err() == nil {
+		logger.Warn(ctx, "workspace agent QUIC RPC listen error", slog.Error(err))
+	}
+}
+
+// authenticateAgentQUIC reads the handshake an agent sends on the first
+// stream of a QUIC connection and replies with the outcome. Only agents of
+// the latest build of their workspace are accepted.
+func (api *API) authenticateAgentQUIC(ctx context.Context, conn quic.Connection) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, database.WorkspaceBuild, error) {
+	ctx, cancel := context.WithTimeout(ctx, agentQUICHandshakeTimeout)
+	defer cancel()
+
+	stream, err := conn.AcceptStream(ctx)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("accept handshake stream: %w", err)
+	}
+	defer stream.Close()
+	deadline, _ := ctx.Deadline()
+	_ = stream.SetDeadline(deadline)
+
+	var handshake agentsdk.QUICHandshake
+	err = json.NewDecoder(stream).Decode(&handshake)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("read handshake: %w", err)
+	}
+
+	row, build, err := api.agentQUICBuild(ctx, handshake.SessionToken)
+	var res agentsdk.QUICHandshakeResponse
+	if err != nil {
+		res.Error = err.Error()
+	}
+	writeErr := json.NewEncoder(stream).Encode(res)
+	if err != nil {
+		// Closing the connection right away could discard the response, so
+		// leave it to the agent until the handshake times out.
+		if writeErr == nil {
+			_ = stream.Close()
+			<-ctx.Done()
+		}
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, err
+	}
+	if writeErr != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("write handshake response: %w", writeErr)
+	}
+	return row, build, nil
+}
+
+// agentQUICBuild returns the agent a session token belongs to and the
+// build the agent is part of.
+func (api *API) agentQUICBuild(ctx context.Context, sessionToken string) (database.GetWorkspaceAgentAndOwnerByAuthTokenRow, database.WorkspaceBuild, error) {
+	token, err := uuid.Parse(sessionToken)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.New("workspace agent token invalid")
+	}
+
+	//nolint:gocritic // System needs to be able to get workspace agents.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	row, err := api.Database.GetWorkspaceAgentAndOwnerByAuthToken(ctx, token)
+	if err != nil {
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.New("workspace agent not authorized")
+		}
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("check workspace agent authorization: %w", err)
+	}
+
+	resource, err := api.Database.GetWorkspaceResourceByID(ctx, row.WorkspaceAgent.ResourceID)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("fetch workspace agent resource: %w", err)
+	}
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, resource.JobID)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("fetch workspace build: %w", err)
+	}
+	err = checkBuildIsLatest(ctx, api.Database, build)
+	if err != nil {
+		return database.GetWorkspaceAgentAndOwnerByAuthTokenRow{}, database.WorkspaceBuild{}, xerrors.Errorf("agent trying to connect from non-latest build: %w", err)
+	}
+	return row, build, nil
+}
+
+// quicPinger adapts a QUIC connection to the agent connection monitor.
+// QUIC sends its own keep-alives and closes idle connections, so a ping
+// only reports whether the connection is still open.
+type quicPinger struct {
+	conn quic.Connection
+}
+
+func (p quicPinger) Ping(context.Context) error {
+	return p.conn.Context().Err()
+}
+
+func (p quicPinger) Close(_ websocket.StatusCode, reason string) error {
+	return p.conn.CloseWithError(0, reason)
+}
@@ -0,0 +1,171 @@
+package coderd_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/agent/agenttest"
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbfake"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentQUIC(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Connect", func(t *testing.T) {
+		t.Parallel()
+
+		client, db, api, transport := newQUICServer(t)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+		agentClient.SDK.HTTPClient = &http.Client{Transport: transport}
+		manifest, err := agentClient.Manifest(testutil.Context(t, testutil.WaitLong))
+		require.NoError(t, err)
+		require.NotEmpty(t, manifest.QUICAddress)
+		require.NotNil(t, api.AgentQUICListener)
+
+		registry := prometheus.NewRegistry()
+		_ = agenttest.New(t, client.URL, r.AgentToken, func(o *agent.Options) {
+			o.Client = agentClient
+			o.PrometheusRegistry = registry
+		})
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+
+		require.Eventually(t, func() bool {
+			return hasMetric(t, registry, "agent_coderd_connect_seconds", "quic")
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		// Coordination goes through the QUIC connection, so the agent is only
+		// reachable if the agent API is served over it.
+		ctx := testutil.Context(t, testutil.WaitLong)
+		conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.True(t, conn.AwaitReachable(ctx))
+		require.False(t, hasMetric(t, registry, "agent_coderd_connect_seconds", "websocket"))
+		require.Zero(t, counterValue(t, registry, "agent_coderd_quic_fallbacks_total"))
+	})
+
+	t.Run("RejectInvalidToken", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, api, transport := newQUICServer(t)
+		udpAddr, ok := api.AgentQUICListener.Addr().(*net.UDPAddr)
+		require.True(t, ok)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(uuid.NewString())
+		agentClient.SDK.HTTPClient = &http.Client{Transport: transport}
+		_, err := agentClient.ListenQUIC(testutil.Context(t, testutil.WaitLong), net.JoinHostPort("localhost", strconv.Itoa(udpAddr.Port)))
+		require.ErrorContains(t, err, "workspace agent not authorized")
+	})
+
+	t.Run("FallbackToWebsocket", func(t *testing.T) {
+		t.Parallel()
+
+		client, db, api, transport := newQUICServer(t)
+		user := coderdtest.CreateFirstUser(t, client)
+		r := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        user.UserID,
+		}).WithAgent().Do()
+
+		// The address stays in the manifest, but nothing answers on it, like
+		// when a firewall drops UDP.
+		require.NoError(t, api.AgentQUICListener.Close())
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(r.AgentToken)
+		agentClient.SDK.HTTPClient = &http.Client{Transport: transport}
+		registry := prometheus.NewRegistry()
+		_ = agenttest.New(t, client.URL, r.AgentToken, func(o *agent.Options) {
+			o.Client = agentClient
+			o.PrometheusRegistry = registry
+		})
+		coderdtest.AwaitWorkspaceAgents(t, client, r.Workspace.ID)
+
+		require.Eventually(t, func() bool {
+			return hasMetric(t, registry, "agent_coderd_connect_seconds", "websocket")
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, float64(1), counterValue(t, registry, "agent_coderd_quic_fallbacks_total"))
+	})
+}
+
+// newQUICServer starts coderd with TLS and agent QUIC connections enabled,
+// and returns a transport that trusts its certificate.
+func newQUICServer(t *testing.T) (*codersdk.Client, database.Store, *coderd.API, *http.Transport) {
+	t.Helper()
+
+	cert := testutil.GenerateTLSCertificate(t, "localhost")
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		},
+	}
+
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		TLSCertificates: []tls.Certificate{cert},
+		AgentQUIC:       true,
+	})
+	client.HTTPClient = &http.Client{Transport: transport}
+	t.Cleanup(transport.CloseIdleConnections)
+	return client, api.Database, api, transport
+}
+
+func hasMetric(t *testing.T, registry *prometheus.Registry, name, transport string) bool {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "transport" && label.GetValue() == transport {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func counterValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name && len(family.GetMetric()) > 0 {
+			return family.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
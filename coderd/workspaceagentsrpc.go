@@ -102,7 +102,26 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 	monitor := api.startAgentWebsocketMonitor(closeCtx, workspaceAgent, build, conn)
 	defer monitor.close()
 
-	agentAPI := agentapi.New(agentapi.Options{
+	agentAPI := api.newAgentAPI(workspaceAgent, build)
+
+	streamID := tailnet.StreamID{
+		Name: fmt.Sprintf("%s-%s-%s", owner.Username, workspace.Name, workspaceAgent.Name),
+		ID:   workspaceAgent.ID,
+		Auth: tailnet.AgentTunnelAuth{},
+	}
+	ctx = tailnet.WithStreamID(ctx, streamID)
+	err = agentAPI.Serve(ctx, mux)
+	if err != nil {
+		api.Logger.Warn(ctx, "workspace agent RPC listen error", slog.Error(err))
+		_ = conn.Close(websocket.StatusInternalError, err.Error())
+		return
+	}
+}
+
+// newAgentAPI returns the API served to a workspace agent over its RPC
+// connection, whichever transport the agent connected with.
+func (api *API) newAgentAPI(workspaceAgent database.WorkspaceAgent, build database.WorkspaceBuild) *agentapi.API {
+	return agentapi.New(agentapi.Options{
 		AgentID: workspaceAgent.ID,
 
 		Ctx:                               api.ctx,
@@ -121,6 +140,7 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		AgentInactiveDisconnectTimeout:  api.AgentInactiveDisconnectTimeout,
 		AgentFallbackTroubleshootingURL: api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
 		AgentStatsRefreshInterval:       api.AgentStatsRefreshInterval,
+		AgentMetadataHistory:            api.DeploymentValues.AgentMetadataHistory.Value(),
 		DisableDirectConnections:        api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 		DerpForceWebSockets:             api.DeploymentValues.DERP.Config.ForceWebSockets.Value(),
 		DerpMapUpdateFrequency:          api.Options.DERPMapUpdateFrequency,
@@ -130,19 +150,6 @@ func (api *API) workspaceAgentRPC(rw http.ResponseWriter, r *http.Request) {
 		WorkspaceID:          build.WorkspaceID, // saves the extra lookup later
 		UpdateAgentMetricsFn: api.UpdateAgentMetrics,
 	})
-
-	streamID := tailnet.StreamID{
-		Name: fmt.Sprintf("%s-%s-%s", owner.Username, workspace.Name, workspaceAgent.Name),
-		ID:   workspaceAgent.ID,
-		Auth: tailnet.AgentTunnelAuth{},
-	}
-	ctx = tailnet.WithStreamID(ctx, streamID)
-	err = agentAPI.Serve(ctx, mux)
-	if err != nil {
-		api.Logger.Warn(ctx, "workspace agent RPC listen error", slog.Error(err))
-		_ = conn.Close(websocket.StatusInternalError, err.Error())
-		return
-	}
 }
 
 func ensureLatestBuild(ctx context.Context, db database.Store, logger slog.Logger, rw http.ResponseWriter, workspaceAgent database.WorkspaceAgent) (database.WorkspaceBuild, bool) {
@@ -195,7 +202,7 @@ func checkBuildIsLatest(ctx context.Context, db database.Store, build database.W
 
 func (api *API) startAgentWebsocketMonitor(ctx context.Context,
 	workspaceAgent database.WorkspaceAgent, workspaceBuild database.WorkspaceBuild,
-	conn *websocket.Conn,
+	conn pingerCloser,
 ) *agentWebsocketMonitor {
 	monitor := &agentWebsocketMonitor{
 		apiCtx:            api.ctx,
@@ -213,11 +220,26 @@ func (api *API) startAgentWebsocketMonitor(ctx context.Context,
 		),
 	}
 	monitor.init()
+
+	// Revoking the agent's token closes its connection, no matter which
+	// replica the agent is connected to.
+	cancelSub, err := api.Pubsub.Subscribe(workspaceAgentTokenRevokedChannel(workspaceAgent.ID), func(_ context.Context, _ []byte) {
+		monitor.revoke()
+	})
+	if err != nil {
+		monitor.logger.Warn(ctx, "failed to subscribe to agent token revocations", slog.Error(err))
+	} else {
+		monitor.cancelSub = cancelSub
+	}
 	monitor.start(ctx)
 
 	return monitor
 }
 
+func workspaceAgentTokenRevokedChannel(agentID uuid.UUID) string {
+	return fmt.Sprintf("workspace_agent_token_revoked:%s", agentID)
+}
+
 type workspaceUpdater interface {
 	publishWorkspaceUpdate(ctx context.Context, workspaceID uuid.UUID)
 }
@@ -230,6 +252,7 @@ type pingerCloser interface {
 type agentWebsocketMonitor struct {
 	apiCtx         context.Context
 	cancel         context.CancelFunc
+	cancelSub      func()
 	wg             sync.WaitGroup
 	workspaceAgent database.WorkspaceAgent
 	workspaceBuild database.WorkspaceBuild
@@ -242,6 +265,8 @@ type agentWebsocketMonitor struct {
 
 	// state manipulated by both sendPings() and monitor() goroutines: needs to be threadsafe
 	lastPing atomic.Pointer[time.Time]
+	// revoked receives when the agent's token is revoked.
+	revoked chan struct{}
 
 	// state manipulated only by monitor() goroutine: does not need to be threadsafe
 	firstConnectedAt  sql.NullTime
@@ -296,6 +321,28 @@ func (m *agentWebsocketMonitor) updateConnectionTimes(ctx context.Context) error
 	return nil
 }
 
+// insertEvent records a connection change of the agent on the timeline of its
+// workspace. Failing to do so doesn't affect the connection.
+func (m *agentWebsocketMonitor) insertEvent(ctx context.Context, eventType database.WorkspaceEventType) {
+	//nolint:gocritic // The agent connecting isn't a user action.
+	err := m.db.InsertWorkspaceEvent(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceEventParams{
+		ID:          uuid.New(),
+		WorkspaceID: m.workspaceBuild.WorkspaceID,
+		CreatedAt:   dbtime.Now(),
+		Type:        eventType,
+		AgentID: uuid.NullUUID{
+			UUID:  m.workspaceAgent.ID,
+			Valid: true,
+		},
+	})
+	if err != nil && !xerrors.Is(err, context.Canceled) && !database.IsQueryCanceledError(err) {
+		m.logger.Warn(ctx, "failed to insert workspace event",
+			slog.F("type", eventType),
+			slog.Error(err),
+		)
+	}
+}
+
 func (m *agentWebsocketMonitor) init() {
 	now := dbtime.Now()
 	m.firstConnectedAt = m.workspaceAgent.FirstConnectedAt
@@ -311,6 +358,16 @@ func (m *agentWebsocketMonitor) init() {
 	}
 	m.disconnectedAt = m.workspaceAgent.DisconnectedAt
 	m.lastPing.Store(ptr.Ref(time.Now())) // Since the agent initiated the request, assume it's alive.
+	m.revoked = make(chan struct{}, 1)
+}
+
+// revoke makes the monitor close the connection because the agent's token
+// was revoked.
+func (m *agentWebsocketMonitor) revoke() {
+	select {
+	case m.revoked <- struct{}{}:
+	default:
+	}
 }
 
 func (m *agentWebsocketMonitor) start(ctx context.Context) {
@@ -329,6 +386,9 @@ func (m *agentWebsocketMonitor) start(ctx context.Context) {
 }
 
 func (m *agentWebsocketMonitor) monitor(ctx context.Context) {
+	// Only agents that were seen connecting are recorded as disconnecting on
+	// the workspace timeline.
+	connected := false
 	defer func() {
 		// If connection closed then context will be canceled, try to
 		// ensure our final update is sent. By waiting at most the agent
@@ -362,6 +422,9 @@ func (m *agentWebsocketMonitor) monitor(ctx context.Context) {
 				)
 			}
 		}
+		if connected {
+			m.insertEvent(finalCtx, database.WorkspaceEventTypeAgentDisconnected)
+		}
 		m.updater.publishWorkspaceUpdate(finalCtx, m.workspaceBuild.WorkspaceID)
 	}()
 	reason := "disconnect"
@@ -376,6 +439,8 @@ func (m *agentWebsocketMonitor) monitor(ctx context.Context) {
 		reason = err.Error()
 		return
 	}
+	connected = true
+	m.insertEvent(ctx, database.WorkspaceEventTypeAgentConnected)
 	m.updater.publishWorkspaceUpdate(ctx, m.workspaceBuild.WorkspaceID)
 
 	ticker := time.NewTicker(m.pingPeriod)
@@ -385,6 +450,9 @@ func (m *agentWebsocketMonitor) monitor(ctx context.Context) {
 		case <-ctx.Done():
 			reason = "canceled"
 			return
+		case <-m.revoked:
+			reason = "token revoked"
+			return
 		case <-ticker.C:
 		}
 
@@ -417,6 +485,9 @@ func (m *agentWebsocketMonitor) monitor(ctx context.Context) {
 }
 
 func (m *agentWebsocketMonitor) close() {
+	if m.cancelSub != nil {
+		m.cancelSub()
+	}
 	m.cancel()
 	m.wg.Wait()
 }
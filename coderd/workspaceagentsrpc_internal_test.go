@@ -75,6 +75,19 @@ func TestAgentWebsocketMonitor_ContextCancel(t *testing.T) {
 	mDB.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), build.WorkspaceID).
 		AnyTimes().
 		Return(database.WorkspaceBuild{ID: build.ID}, nil)
+	agentConnected := mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentConnected),
+	).
+		Times(1).
+		Return(nil)
+	mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentDisconnected),
+	).
+		After(agentConnected).
+		Times(1).
+		Return(nil)
 
 	closeCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -151,6 +164,19 @@ func TestAgentWebsocketMonitor_PingTimeout(t *testing.T) {
 	mDB.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), build.WorkspaceID).
 		AnyTimes().
 		Return(database.WorkspaceBuild{ID: build.ID}, nil)
+	agentConnected := mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentConnected),
+	).
+		Times(1).
+		Return(nil)
+	mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentDisconnected),
+	).
+		After(agentConnected).
+		MaxTimes(1).
+		Return(nil)
 
 	go uut.monitor(ctx)
 	fConn.requireEventuallyClosed(t, websocket.StatusGoingAway, "ping timeout")
@@ -211,6 +237,19 @@ func TestAgentWebsocketMonitor_BuildOutdated(t *testing.T) {
 	mDB.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), build.WorkspaceID).
 		AnyTimes().
 		Return(database.WorkspaceBuild{ID: uuid.New()}, nil)
+	agentConnected := mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentConnected),
+	).
+		Times(1).
+		Return(nil)
+	mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentDisconnected),
+	).
+		After(agentConnected).
+		MaxTimes(1).
+		Return(nil)
 
 	go uut.monitor(ctx)
 	fConn.requireEventuallyClosed(t, websocket.StatusGoingAway, "build is outdated")
@@ -288,6 +327,19 @@ func TestAgentWebsocketMonitor_StartClose(t *testing.T) {
 	mDB.EXPECT().GetLatestWorkspaceBuildByWorkspaceID(gomock.Any(), build.WorkspaceID).
 		AnyTimes().
 		Return(database.WorkspaceBuild{ID: build.ID}, nil)
+	agentConnected := mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentConnected),
+	).
+		Times(1).
+		Return(nil)
+	mDB.EXPECT().InsertWorkspaceEvent(
+		gomock.Any(),
+		workspaceEvent(build.WorkspaceID, agent.ID, database.WorkspaceEventTypeAgentDisconnected),
+	).
+		After(agentConnected).
+		Times(1).
+		Return(nil)
 
 	uut.start(ctx)
 	closed := make(chan struct{})
@@ -441,3 +493,41 @@ func (connectionUpdateMatcher) Got(x interface{}) string {
 	return fmt.Sprintf("{agent=%s, replica=%s, disconnected=%t}",
 		args.ID, args.LastConnectedReplicaID.UUID, args.DisconnectedAt.Valid)
 }
+
+type workspaceEventMatcher struct {
+	workspaceID uuid.UUID
+	agentID     uuid.UUID
+	eventType   database.WorkspaceEventType
+}
+
+func workspaceEvent(workspaceID, agentID uuid.UUID, eventType database.WorkspaceEventType) workspaceEventMatcher {
+	return workspaceEventMatcher{
+		workspaceID: workspaceID,
+		agentID:     agentID,
+		eventType:   eventType,
+	}
+}
+
+func (m workspaceEventMatcher) Matches(x interface{}) bool {
+	args, ok := x.(database.InsertWorkspaceEventParams)
+	if !ok {
+		return false
+	}
+	return args.WorkspaceID == m.workspaceID &&
+		args.AgentID.Valid && args.AgentID.UUID == m.agentID &&
+		args.Type == m.eventType
+}
+
+func (m workspaceEventMatcher) String() string {
+	return fmt.Sprintf("{workspace=%s, agent=%s, type=%s}",
+		m.workspaceID.String(), m.agentID.String(), m.eventType)
+}
+
+func (workspaceEventMatcher) Got(x interface{}) string {
+	args, ok := x.(database.InsertWorkspaceEventParams)
+	if !ok {
+		return fmt.Sprintf("type=%T", x)
+	}
+	return fmt.Sprintf("{workspace=%s, agent=%s, type=%s}",
+		args.WorkspaceID, args.AgentID.UUID, args.Type)
+}
@@ -3,17 +3,21 @@ package workspaceapps
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/apikey"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -35,11 +39,14 @@ type DBTokenProvider struct {
 	OAuth2Configs                 *httpmw.OAuth2Configs
 	WorkspaceAgentInactiveTimeout time.Duration
 	SigningKey                    SecurityKey
+	// Auditor records attempts to access ports that the template of the
+	// workspace doesn't allow to be forwarded.
+	Auditor *atomic.Pointer[audit.Auditor]
 }
 
 var _ SignedTokenProvider = &DBTokenProvider{}
 
-func NewDBTokenProvider(log slog.Logger, accessURL *url.URL, authz rbac.Authorizer, db database.Store, cfg *codersdk.DeploymentValues, oauth2Cfgs *httpmw.OAuth2Configs, workspaceAgentInactiveTimeout time.Duration, signingKey SecurityKey) SignedTokenProvider {
+func NewDBTokenProvider(log slog.Logger, accessURL *url.URL, authz rbac.Authorizer, db database.Store, cfg *codersdk.DeploymentValues, oauth2Cfgs *httpmw.OAuth2Configs, workspaceAgentInactiveTimeout time.Duration, signingKey SecurityKey, auditor *atomic.Pointer[audit.Auditor]) SignedTokenProvider {
 	if workspaceAgentInactiveTimeout == 0 {
 		workspaceAgentInactiveTimeout = 1 * time.Minute
 	}
@@ -53,6 +60,7 @@ func NewDBTokenProvider(log slog.Logger, accessURL *url.URL, authz rbac.Authoriz
 		OAuth2Configs:                 oauth2Cfgs,
 		WorkspaceAgentInactiveTimeout: workspaceAgentInactiveTimeout,
 		SigningKey:                    signingKey,
+		Auditor:                       auditor,
 	}
 }
 
@@ -86,6 +94,9 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		OAuth2Configs:               p.OAuth2Configs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: p.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy: func(roles []string) apikey.LifetimePolicy {
+			return apikey.PolicyForRoles(p.DeploymentValues, roles)
+		},
 		// Optional is true to allow for public apps. If the authorization check
 		// (later on) fails and the user is not authenticated, they will be
 		// redirected to the login page or app auth endpoint using code below.
@@ -191,6 +202,15 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	// Check that the template allows the port to be forwarded. This is only
+	// checked once the user is known to have access to the app, so denied
+	// attempts can be attributed to them.
+	if dbReq.DeniedPort != 0 {
+		p.auditPortForwardDenied(ctx, r, apiKey, dbReq)
+		WriteWorkspaceAppPortForbidden(p.Logger, p.DashboardURL, rw, r, &appReq)
+		return nil, "", false
+	}
+
 	// Check that the agent is online.
 	agentStatus := dbReq.Agent.Status(p.WorkspaceAgentInactiveTimeout)
 	if agentStatus.Status != database.WorkspaceAgentStatusConnected {
@@ -222,6 +242,36 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	return &token, tokenStr, true
 }
 
+// auditPortForwardDenied audits an attempt to access a port that the template
+// of the workspace doesn't allow to be forwarded.
+func (p *DBTokenProvider) auditPortForwardDenied(ctx context.Context, r *http.Request, apiKey *database.APIKey, dbReq *databaseRequest) {
+	if p.Auditor == nil || apiKey == nil {
+		return
+	}
+	portForwardInfo, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceName:  dbReq.Workspace.Name,
+		WorkspaceOwner: dbReq.User.Username,
+		AgentName:      dbReq.Agent.Name,
+		Port:           dbReq.DeniedPort,
+	})
+	if err != nil {
+		p.Logger.Warn(ctx, "marshal port forward info", slog.Error(err))
+	}
+
+	audit.BackgroundAudit(ctx, &audit.BackgroundAuditParams[database.Workspace]{
+		Audit:            *p.Auditor.Load(),
+		Log:              p.Logger,
+		UserID:           apiKey.UserID,
+		OrganizationID:   dbReq.Workspace.OrganizationID,
+		IP:               r.RemoteAddr,
+		Status:           http.StatusForbidden,
+		Action:           database.AuditActionPortForward,
+		AdditionalFields: portForwardInfo,
+		Old:              dbReq.Workspace,
+		New:              dbReq.Workspace,
+	})
+}
+
 // authorizeRequest returns true/false if the request is authorized. The returned []string
 // are warnings that aid in debugging. These messages do not prevent authorization,
 // but may indicate that the request is not configured correctly.
@@ -621,6 +621,50 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "http://127.0.0.1:9090", token.AppURL)
 	})
 
+	t.Run("PortSubdomainShared", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		_, err := client.UpsertWorkspaceAgentPortShare(ctx, workspace.ID, codersdk.UpsertWorkspaceAgentPortShareRequest{
+			AgentName:  agentName,
+			Port:       9191,
+			ShareLevel: codersdk.WorkspaceAgentPortShareLevelPublic,
+		})
+		require.NoError(t, err)
+
+		for _, port := range []string{"9191", "9292"} {
+			req := (workspaceapps.Request{
+				AccessMethod:      workspaceapps.AccessMethodSubdomain,
+				BasePath:          "/",
+				UsernameOrID:      me.Username,
+				WorkspaceNameOrID: workspace.Name,
+				AgentNameOrID:     agentName,
+				AppSlugOrPort:     port,
+			}).Normalize()
+
+			// The request is unauthenticated, so only the shared port is
+			// accessible.
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/", nil)
+			token, ok := workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+				Logger:              api.Logger,
+				SignedTokenProvider: api.WorkspaceAppsProvider,
+				DashboardURL:        api.AccessURL,
+				PathAppBaseURL:      api.AccessURL,
+				AppHostname:         api.AppHostname,
+				AppRequest:          req,
+			})
+			_ = rw.Result().Body.Close()
+			if port == "9191" {
+				require.True(t, ok)
+				require.Equal(t, "http://127.0.0.1:9191", token.AppURL)
+			} else {
+				require.False(t, ok)
+				require.Nil(t, token)
+			}
+		}
+	})
+
 	t.Run("Terminal", func(t *testing.T) {
 		t.Parallel()
 
@@ -39,6 +39,32 @@ func WriteWorkspaceApp404(log slog.Logger, accessURL *url.URL, rw http.ResponseW
 	})
 }
 
+// WriteWorkspaceAppPortForbidden writes a HTML 403 error page for a port-based
+// workspace app whose port the template doesn't allow to be forwarded. If
+// appReq is not nil, it will be used to log the request details at debug level.
+func WriteWorkspaceAppPortForbidden(log slog.Logger, accessURL *url.URL, rw http.ResponseWriter, r *http.Request, appReq *Request) {
+	if appReq != nil {
+		slog.Helper()
+		log.Debug(r.Context(),
+			"workspace app port forwarding denied by template",
+			slog.F("username_or_id", appReq.UsernameOrID),
+			slog.F("workspace_and_agent", appReq.WorkspaceAndAgent),
+			slog.F("workspace_name_or_id", appReq.WorkspaceNameOrID),
+			slog.F("agent_name_or_id", appReq.AgentNameOrID),
+			slog.F("app_slug_or_port", appReq.AppSlugOrPort),
+			slog.F("hostname_prefix", appReq.Prefix),
+		)
+	}
+
+	site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+		Status:       http.StatusForbidden,
+		Title:        "Port Forwarding Denied",
+		Description:  "The template of this workspace doesn't allow this port to be forwarded. Ask a template administrator to allow it.",
+		RetryEnabled: false,
+		DashboardURL: accessURL.String(),
+	})
+}
+
 // WriteWorkspaceApp500 writes a HTML 500 error page for a workspace app. If
 // appReq is not nil, it's fields will be added to the logged error message.
 func WriteWorkspaceApp500(log slog.Logger, accessURL *url.URL, rw http.ResponseWriter, r *http.Request, appReq *Request, err error, msg string) {
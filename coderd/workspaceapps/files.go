@@ -0,0 +1,175 @@
+package workspaceapps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary List files in workspace agent
+// @ID list-files-in-workspace-agent
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string false "Directory path, relative to the home directory"
+// @Success 200 {object} codersdk.WorkspaceAgentListFilesResponse
+// @Router /workspaceagents/{workspaceagent}/files/list [get]
+func (s *Server) workspaceAgentListFiles(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	agentConn, release, ok := s.dialFilesAgent(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	files, err := agentConn.ListFiles(ctx, r.URL.Query().Get("path"))
+	if err != nil {
+		writeAgentFilesError(ctx, rw, err, "Internal error listing files.")
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, files)
+}
+
+// @Summary Download file from workspace agent
+// @ID download-file-from-workspace-agent
+// @Security CoderSessionToken
+// @Produce application/octet-stream
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Success 200
+// @Router /workspaceagents/{workspaceagent}/files/download [get]
+func (s *Server) workspaceAgentDownloadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := requiredFilePath(rw, r)
+	if !ok {
+		return
+	}
+	agentConn, release, ok := s.dialFilesAgent(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	content, err := agentConn.DownloadFile(ctx, path)
+	if err != nil {
+		writeAgentFilesError(ctx, rw, err, "Internal error downloading file.")
+		return
+	}
+	defer content.Close()
+
+	// The agent may run on Windows, so both separators are accepted.
+	name := path[strings.LastIndexAny(path, `/\`)+1:]
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	rw.WriteHeader(http.StatusOK)
+	_, err = io.Copy(rw, content)
+	if err != nil {
+		s.Logger.Debug(ctx, "copy file from workspace agent", slog.F("path", path), slog.Error(err))
+	}
+}
+
+// @Summary Upload file to workspace agent
+// @ID upload-file-to-workspace-agent
+// @Security CoderSessionToken
+// @Accept application/octet-stream
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param path query string true "File path, relative to the home directory"
+// @Success 201 {object} codersdk.WorkspaceAgentFile
+// @Router /workspaceagents/{workspaceagent}/files/upload [post]
+func (s *Server) workspaceAgentUploadFile(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path, ok := requiredFilePath(rw, r)
+	if !ok {
+		return
+	}
+	agentConn, release, ok := s.dialFilesAgent(rw, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	file, err := agentConn.UploadFile(ctx, path, r.Body)
+	if err != nil {
+		writeAgentFilesError(ctx, rw, err, "Internal error uploading file.")
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, file)
+}
+
+// dialFilesAgent authorizes a request for the files of a workspace agent and
+// dials the agent. Files give the same access to the workspace as a terminal,
+// so they are authorized like one.
+func (s *Server) dialFilesAgent(rw http.ResponseWriter, r *http.Request) (*codersdk.WorkspaceAgentConn, func(), bool) {
+	ctx := r.Context()
+	agentID := chi.URLParam(r, "workspaceagent")
+	appToken, ok := ResolveRequest(rw, r, ResolveRequestOptions{
+		Logger:              s.Logger,
+		SignedTokenProvider: s.SignedTokenProvider,
+		DashboardURL:        s.DashboardURL,
+		PathAppBaseURL:      s.AccessURL,
+		AppHostname:         s.Hostname,
+		AppRequest: Request{
+			AccessMethod:  AccessMethodTerminal,
+			BasePath:      fmt.Sprintf("/api/v2/workspaceagents/%s/files", agentID),
+			AgentNameOrID: agentID,
+		},
+		AppPath:  "",
+		AppQuery: "",
+	})
+	if !ok {
+		return nil, nil, false
+	}
+
+	agentConn, release, err := s.AgentProvider.AgentConn(ctx, appToken.AgentID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to dial workspace agent.",
+			Detail:  err.Error(),
+		})
+		return nil, nil, false
+	}
+	return agentConn, release, true
+}
+
+func requiredFilePath(rw http.ResponseWriter, r *http.Request) (string, bool) {
+	parser := httpapi.NewQueryParamParser()
+	path := parser.Required("path").String(r.URL.Query(), "", "path")
+	if len(parser.Errors) > 0 {
+		httpapi.Write(r.Context(), rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid query parameters.",
+			Validations: parser.Errors,
+		})
+		return "", false
+	}
+	return path, true
+}
+
+// writeAgentFilesError forwards client errors returned by the agent, such as
+// a missing file.
+func writeAgentFilesError(ctx context.Context, rw http.ResponseWriter, err error, message string) {
+	var sdkErr *codersdk.Error
+	if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() < http.StatusInternalServerError {
+		httpapi.Write(ctx, rw, sdkErr.StatusCode(), sdkErr.Response)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+		Message: message,
+		Detail:  err.Error(),
+	})
+}
@@ -3,6 +3,7 @@ package workspaceapps
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httputil"
@@ -80,10 +81,30 @@ type AgentProvider interface {
 	Close() error
 }
 
+// ConnectionLogger records the connections opened to workspace agents through
+// the Server.
+type ConnectionLogger interface {
+	// LogTerminalConnection is called once the web terminal of the token's
+	// agent is connected.
+	LogTerminalConnection(r *http.Request, token SignedToken)
+}
+
+// TerminalRecorder records the web terminal sessions opened through the
+// Server.
+type TerminalRecorder interface {
+	// RecordTerminal is called before the web terminal of the token's agent
+	// is connected. It returns the writer the output of the terminal is
+	// copied to, or nil if the session isn't recorded. The writer is closed
+	// when the session ends. Sessions that must be recorded but can't be are
+	// refused by returning an error.
+	RecordTerminal(r *http.Request, token SignedToken, width, height uint16) (io.WriteCloser, error)
+}
+
 // Server serves workspace apps endpoints, including:
 // - Path-based apps
 // - Subdomain app middleware
 // - Workspace reconnecting-pty (aka. web terminal)
+// - Workspace agent files
 type Server struct {
 	Logger slog.Logger
 
@@ -115,6 +136,10 @@ type Server struct {
 
 	AgentProvider  AgentProvider
 	StatsCollector *StatsCollector
+	// ConnectionLogger is optional.
+	ConnectionLogger ConnectionLogger
+	// TerminalRecorder is optional.
+	TerminalRecorder TerminalRecorder
 
 	websocketWaitMutex sync.Mutex
 	websocketWaitGroup sync.WaitGroup
@@ -149,6 +174,9 @@ func (s *Server) Attach(r chi.Router) {
 	r.Route("/@{user}/{workspace_and_agent}/apps/{workspaceapp}", servePathApps)
 
 	r.Get("/api/v2/workspaceagents/{workspaceagent}/pty", s.workspaceAgentPTY)
+	r.Get("/api/v2/workspaceagents/{workspaceagent}/files/list", s.workspaceAgentListFiles)
+	r.Get("/api/v2/workspaceagents/{workspaceagent}/files/download", s.workspaceAgentDownloadFile)
+	r.Post("/api/v2/workspaceagents/{workspaceagent}/files/upload", s.workspaceAgentUploadFile)
 }
 
 // handleAPIKeySmuggling is called by the proxy path and subdomain handlers to
@@ -685,6 +713,19 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 
 	go httpapi.Heartbeat(ctx, conn)
 
+	var recording io.WriteCloser
+	if s.TerminalRecorder != nil {
+		recording, err = s.TerminalRecorder.RecordTerminal(r, *appToken, uint16(width), uint16(height))
+		if err != nil {
+			log.Error(ctx, "record terminal session", slog.Error(err))
+			_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("record session: %s", err))
+			return
+		}
+		if recording != nil {
+			defer recording.Close()
+		}
+	}
+
 	agentConn, release, err := s.AgentProvider.AgentConn(ctx, appToken.AgentID)
 	if err != nil {
 		log.Debug(ctx, "dial workspace agent", slog.Error(err))
@@ -702,6 +743,15 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	defer ptNetConn.Close()
 	log.Debug(ctx, "obtained PTY")
 
+	if s.ConnectionLogger != nil {
+		s.ConnectionLogger.LogTerminalConnection(r, *appToken)
+	}
+
+	var ptyConn io.ReadWriteCloser = ptNetConn
+	if recording != nil {
+		ptyConn = recordedConn{Conn: ptNetConn, output: io.TeeReader(ptNetConn, recording)}
+	}
+
 	report := newStatsReportFromSignedToken(*appToken)
 	s.collectStats(report)
 	defer func() {
@@ -709,10 +759,20 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		s.collectStats(report)
 	}()
 
-	agentssh.Bicopy(ctx, wsNetConn, ptNetConn)
+	agentssh.Bicopy(ctx, wsNetConn, ptyConn)
 	log.Debug(ctx, "pty Bicopy finished")
 }
 
+// recordedConn copies everything read from the PTY into a recording.
+type recordedConn struct {
+	net.Conn
+	output io.Reader
+}
+
+func (c recordedConn) Read(p []byte) (int, error) {
+	return c.output.Read(p)
+}
+
 func (s *Server) collectStats(stats StatsReport) {
 	if s.StatsCollector != nil {
 		s.StatsCollector.Collect(stats)
@@ -204,6 +204,9 @@ type databaseRequest struct {
 	// AppSharingLevel is the sharing level of the app. This is forced to be set
 	// to AppSharingLevelOwner if the access method is terminal.
 	AppSharingLevel database.AppSharingLevel
+	// DeniedPort is set if the app is a port that the template of the
+	// workspace doesn't allow to be forwarded.
+	DeniedPort uint16
 }
 
 // getDatabase does queries to get the owner user, workspace and agent
@@ -293,6 +296,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		appURL                string
 		appSharingLevel       database.AppSharingLevel
 		appHealth             = database.WorkspaceAppHealthDisabled
+		deniedPort            uint16
 		portUint, portUintErr = strconv.ParseUint(r.AppSlugOrPort, 10, 16)
 	)
 	if portUintErr == nil {
@@ -317,6 +321,14 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		// This is only supported for subdomain-based applications.
 		appURL = fmt.Sprintf("http://127.0.0.1:%d", portUint)
 		appSharingLevel = database.AppSharingLevelOwner
+
+		template, err := db.GetTemplateByID(ctx, workspace.TemplateID)
+		if err != nil {
+			return nil, xerrors.Errorf("get template: %w", err)
+		}
+		if !template.AllowsPortForwarding(uint16(portUint)) {
+			deniedPort = uint16(portUint)
+		}
 	} else {
 		for _, app := range apps {
 			if app.Slug == r.AppSlugOrPort {
@@ -366,6 +378,21 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		}
 	}
 
+	// Ports that the owner shared with other users are accessible at the
+	// share level. Ports denied by the template can't be shared.
+	if portUintErr == nil && deniedPort == 0 {
+		share, err := db.GetWorkspaceAgentPortShare(ctx, database.GetWorkspaceAgentPortShareParams{
+			WorkspaceID: workspace.ID,
+			AgentName:   agent.Name,
+			Port:        int32(portUint),
+		})
+		if err == nil {
+			appSharingLevel = share.ShareLevel
+		} else if !xerrors.Is(err, sql.ErrNoRows) {
+			return nil, xerrors.Errorf("get workspace agent port share: %w", err)
+		}
+	}
+
 	appURLParsed, err := url.Parse(appURL)
 	if err != nil {
 		return nil, xerrors.Errorf("parse app URL %q: %w", appURL, err)
@@ -379,6 +406,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		AppURL:          appURLParsed,
 		AppHealth:       appHealth,
 		AppSharingLevel: appSharingLevel,
+		DeniedPort:      deniedPort,
 	}, nil
 }
 
@@ -3,10 +3,12 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -25,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
@@ -89,6 +92,8 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 		data.apps,
 		data.scripts,
 		data.logSources,
+		data.ports,
+		data.gpus,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -112,6 +117,7 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
 // @Param since query string false "Since timestamp" format(date-time)
+// @Param reason query string false "Build reason" Enums(initiator,autostart,autostop,template_update,batch,retry)
 // @Success 200 {array} codersdk.WorkspaceBuild
 // @Router /workspaces/{workspace}/builds [get]
 func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
@@ -138,6 +144,17 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	reason := r.URL.Query().Get("reason")
+	if reason != "" && !database.BuildReason(reason).Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid build reason %q.", reason),
+			Validations: []codersdk.ValidationError{
+				{Field: "reason", Detail: fmt.Sprintf("must be one of %v", database.AllBuildReasonValues())},
+			},
+		})
+		return
+	}
+
 	var workspaceBuilds []database.WorkspaceBuild
 	// Ensure all db calls happen in the same tx
 	err := api.Database.InTx(func(store database.Store) error {
@@ -166,6 +183,7 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			OffsetOpt:   int32(paginationParams.Offset),
 			LimitOpt:    int32(paginationParams.Limit),
 			Since:       dbtime.Time(since),
+			Reason:      reason,
 		}
 		workspaceBuilds, err = store.GetWorkspaceBuildsByWorkspaceID(ctx, req)
 		if xerrors.Is(err, sql.ErrNoRows) {
@@ -205,6 +223,8 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		data.apps,
 		data.scripts,
 		data.logSources,
+		data.ports,
+		data.gpus,
 		data.templateVersions,
 	)
 	if err != nil {
@@ -303,6 +323,8 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 		data.apps,
 		data.scripts,
 		data.logSources,
+		data.ports,
+		data.gpus,
 		data.templateVersions[0],
 	)
 	if err != nil {
@@ -346,6 +368,20 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 
 	if createBuild.TemplateVersionID != uuid.Nil {
 		builder = builder.VersionID(createBuild.TemplateVersionID)
+
+		// Record builds that move the workspace to a different template
+		// version so they can be told apart from regular starts and stops.
+		latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil && !httpapi.Is404Error(err) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching latest workspace build.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if err == nil && latestBuild.TemplateVersionID != createBuild.TemplateVersionID {
+			builder = builder.Reason(database.BuildReasonTemplateUpdate)
+		}
 	}
 
 	if createBuild.Orphan {
@@ -366,6 +402,16 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	if len(createBuild.ProvisionerState) > 0 {
 		builder = builder.State(createBuild.ProvisionerState)
 	}
+	if createBuild.DryRun {
+		if createBuild.Orphan || len(createBuild.ProvisionerState) > 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Dry-runs always plan against the state of the latest build.",
+				Detail:  "Orphan and ProvisionerState cannot be set alongside DryRun.",
+			})
+			return
+		}
+		builder = builder.DryRun()
+	}
 
 	workspaceBuild, provisionerJob, err := builder.Build(
 		ctx,
@@ -404,6 +450,12 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		// Client probably doesn't care about this error, so just log it.
 		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
 	}
+	if createBuild.DryRun {
+		httpapi.Write(ctx, rw, http.StatusCreated, convertProvisionerJob(database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+		}))
+		return
+	}
 
 	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
 		workspace.OwnerID,
@@ -439,6 +491,8 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		[]database.WorkspaceApp{},
 		[]database.WorkspaceAgentScript{},
 		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentPort{},
+		[]database.WorkspaceAgentGPU{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -454,6 +508,481 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
 }
 
+// @Summary Get workspace build dry-run by job ID
+// @ID get-workspace-build-dry-run-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Success 200 {object} codersdk.ProvisionerJob
+// @Router /workspaces/{workspace}/dry-run/{jobID} [get]
+func (api *API) workspaceBuildDryRun(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	job, ok := api.fetchWorkspaceBuildDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertProvisionerJob(job))
+}
+
+// @Summary Get workspace build dry-run logs by job ID
+// @ID get-workspace-build-dry-run-logs-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Param before query int false "Before Unix timestamp"
+// @Param after query int false "After Unix timestamp"
+// @Param follow query bool false "Follow log stream"
+// @Success 200 {array} codersdk.ProvisionerJobLog
+// @Router /workspaces/{workspace}/dry-run/{jobID}/logs [get]
+func (api *API) workspaceBuildDryRunLogs(rw http.ResponseWriter, r *http.Request) {
+	job, ok := api.fetchWorkspaceBuildDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+
+	api.provisionerJobLogs(rw, r, job.ProvisionerJob)
+}
+
+// @Summary Get workspace build dry-run resources by job ID
+// @ID get-workspace-build-dry-run-resources-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceBuildDryRunResources
+// @Router /workspaces/{workspace}/dry-run/{jobID}/resources [get]
+func (api *API) workspaceBuildDryRunResources(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	job, ok := api.fetchWorkspaceBuildDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+	if !job.ProvisionerJob.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job hasn't completed!",
+		})
+		return
+	}
+	if job.ProvisionerJob.Error.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job failed, so no resources were planned.",
+			Detail:  job.ProvisionerJob.Error.String,
+		})
+		return
+	}
+
+	planned, err := api.Database.GetWorkspaceResourcesByJobID(ctx, job.ProvisionerJob.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching dry-run resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	var current []database.WorkspaceResource
+	latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err == nil {
+		current, err = api.Database.GetWorkspaceResourcesByJobID(ctx, latestBuild.JobID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace resources.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceBuildDryRunResources(current, planned))
+}
+
+// @Summary Cancel workspace build dry-run by job ID
+// @ID cancel-workspace-build-dry-run-by-job-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param jobID path string true "Job ID" format(uuid)
+// @Success 200 {object} codersdk.Response
+// @Router /workspaces/{workspace}/dry-run/{jobID}/cancel [patch]
+func (api *API) patchWorkspaceBuildDryRunCancel(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	job, ok := api.fetchWorkspaceBuildDryRunJob(rw, r)
+	if !ok {
+		return
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	if job.ProvisionerJob.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job has already completed.",
+		})
+		return
+	}
+	if job.ProvisionerJob.CanceledAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job has already been marked as canceled.",
+		})
+		return
+	}
+
+	err := api.Database.UpdateProvisionerJobWithCancelByID(ctx, database.UpdateProvisionerJobWithCancelByIDParams{
+		ID: job.ProvisionerJob.ID,
+		CanceledAt: sql.NullTime{
+			Time:  dbtime.Now(),
+			Valid: true,
+		},
+		CompletedAt: sql.NullTime{
+			Time: dbtime.Now(),
+			// If the job is running, don't mark it completed!
+			Valid: !job.ProvisionerJob.WorkerID.Valid,
+		},
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Job has been marked as canceled.",
+	})
+}
+
+func (api *API) fetchWorkspaceBuildDryRunJob(rw http.ResponseWriter, r *http.Request) (database.GetProvisionerJobsByIDsWithQueuePositionRow, bool) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		jobID     = chi.URLParam(r, "jobID")
+	)
+
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Job ID %q must be a valid UUID.", jobID),
+			Detail:  err.Error(),
+		})
+		return database.GetProvisionerJobsByIDsWithQueuePositionRow{}, false
+	}
+
+	jobs, err := api.Database.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{jobUUID})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return database.GetProvisionerJobsByIDsWithQueuePositionRow{}, false
+	}
+	if len(jobs) == 0 || jobs[0].ProvisionerJob.Type != database.ProvisionerJobTypeWorkspaceBuildDryRun {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Workspace build dry-run %q not found.", jobUUID),
+		})
+		return database.GetProvisionerJobsByIDsWithQueuePositionRow{}, false
+	}
+	job := jobs[0]
+
+	// Verify that the dry-run belongs to the workspace in the request. Reading
+	// the workspace is already authorized by the workspace param middleware.
+	var input provisionerdserver.WorkspaceBuildDryRunJob
+	err = json.Unmarshal(job.ProvisionerJob.Input, &input)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unmarshalling job metadata.",
+			Detail:  err.Error(),
+		})
+		return database.GetProvisionerJobsByIDsWithQueuePositionRow{}, false
+	}
+	if input.WorkspaceID != workspace.ID {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: fmt.Sprintf("Workspace build dry-run %q not found.", jobUUID),
+		})
+		return database.GetProvisionerJobsByIDsWithQueuePositionRow{}, false
+	}
+
+	return job, true
+}
+
+// convertWorkspaceBuildDryRunResources compares the resources of the latest
+// build with the resources planned by a dry-run. Resources are matched by
+// their type and name.
+func convertWorkspaceBuildDryRunResources(current, planned []database.WorkspaceResource) codersdk.WorkspaceBuildDryRunResources {
+	key := func(resource database.WorkspaceResource) string {
+		return resource.Type + "." + resource.Name
+	}
+	convert := func(resource database.WorkspaceResource) codersdk.WorkspaceBuildDryRunResource {
+		return codersdk.WorkspaceBuildDryRunResource{
+			Type:      resource.Type,
+			Name:      resource.Name,
+			DailyCost: resource.DailyCost,
+		}
+	}
+
+	delta := codersdk.WorkspaceBuildDryRunResources{
+		Added:     make([]codersdk.WorkspaceBuildDryRunResource, 0),
+		Removed:   make([]codersdk.WorkspaceBuildDryRunResource, 0),
+		Unchanged: make([]codersdk.WorkspaceBuildDryRunResource, 0),
+	}
+	currentByKey := make(map[string]database.WorkspaceResource, len(current))
+	for _, resource := range current {
+		currentByKey[key(resource)] = resource
+		delta.DailyCostChange -= resource.DailyCost
+	}
+	plannedByKey := make(map[string]database.WorkspaceResource, len(planned))
+	for _, resource := range planned {
+		plannedByKey[key(resource)] = resource
+		delta.DailyCost += resource.DailyCost
+		if _, ok := currentByKey[key(resource)]; ok {
+			delta.Unchanged = append(delta.Unchanged, convert(resource))
+		} else {
+			delta.Added = append(delta.Added, convert(resource))
+		}
+	}
+	for _, resource := range current {
+		if _, ok := plannedByKey[key(resource)]; !ok {
+			delta.Removed = append(delta.Removed, convert(resource))
+		}
+	}
+	delta.DailyCostChange += delta.DailyCost
+
+	for _, resources := range [][]codersdk.WorkspaceBuildDryRunResource{delta.Added, delta.Removed, delta.Unchanged} {
+		slices.SortFunc(resources, func(a, b codersdk.WorkspaceBuildDryRunResource) int {
+			if a.Type != b.Type {
+				return strings.Compare(a.Type, b.Type)
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	return delta
+}
+
+// @Summary Create workspace builds in batch
+// @ID create-workspace-builds-in-batch
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Builds
+// @Param request body codersdk.BatchWorkspaceBuildRequest true "Batch workspace build request"
+// @Success 200 {object} codersdk.BatchWorkspaceBuildResponse
+// @Router /workspaces/batch-builds [post]
+func (api *API) postBatchWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	var req codersdk.BatchWorkspaceBuildRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if len(req.WorkspaceIDs) == 0 || len(req.WorkspaceIDs) > codersdk.MaxBatchWorkspaceBuilds {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Between 1 and %d workspace IDs must be provided.", codersdk.MaxBatchWorkspaceBuilds),
+		})
+		return
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(req.WorkspaceIDs))
+	workspaceIDs := make([]uuid.UUID, 0, len(req.WorkspaceIDs))
+	for _, workspaceID := range req.WorkspaceIDs {
+		if _, ok := seen[workspaceID]; ok {
+			continue
+		}
+		seen[workspaceID] = struct{}{}
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+
+	// Every build is created in the same transaction, so either all of the
+	// workspaces are built or none of them are.
+	var (
+		workspaces []database.Workspace
+		builds     []database.WorkspaceBuild
+		jobs       []database.ProvisionerJob
+		failedID   uuid.UUID
+	)
+	err := database.ReadModifyUpdate(api.Database, func(tx database.Store) error {
+		workspaces, builds, jobs, failedID = workspaces[:0], builds[:0], jobs[:0], uuid.Nil
+		for _, workspaceID := range workspaceIDs {
+			workspace, build, job, err := api.batchWorkspaceBuild(r, tx, apiKey.UserID, workspaceID, database.WorkspaceTransition(req.Transition))
+			if err != nil {
+				failedID = workspaceID
+				return err
+			}
+			workspaces = append(workspaces, workspace)
+			builds = append(builds, build)
+			jobs = append(jobs, job)
+		}
+		return nil
+	})
+	resp := codersdk.BatchWorkspaceBuildResponse{
+		Results: make([]codersdk.BatchWorkspaceBuildResult, 0, len(workspaceIDs)),
+	}
+	if err != nil {
+		errResp := api.batchWorkspaceBuildError(ctx, failedID, err)
+		for _, workspaceID := range workspaceIDs {
+			result := codersdk.BatchWorkspaceBuildResult{
+				WorkspaceID: workspaceID,
+				Error:       errResp,
+			}
+			if failedID != uuid.Nil && workspaceID != failedID {
+				result.Error = &codersdk.Response{
+					Message: "Workspace was not built because the build for another workspace in the batch failed.",
+					Detail:  fmt.Sprintf("build for workspace %s failed: %s", failedID, errResp.Message),
+				}
+			}
+			resp.Results = append(resp.Results, result)
+		}
+		httpapi.Write(ctx, rw, http.StatusOK, resp)
+		return
+	}
+
+	for i, workspace := range workspaces {
+		err = provisionerjobs.PostJob(api.Pubsub, jobs[i])
+		if err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+		api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+		result := codersdk.BatchWorkspaceBuildResult{WorkspaceID: workspace.ID}
+		result.Build, result.Error = api.convertBatchWorkspaceBuild(ctx, workspace, builds[i], jobs[i])
+		resp.Results = append(resp.Results, result)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// batchWorkspaceBuild creates a single build for postBatchWorkspaceBuilds
+// using the batch transaction.
+func (api *API) batchWorkspaceBuild(r *http.Request, tx database.Store, initiatorID uuid.UUID, workspaceID uuid.UUID, transition database.WorkspaceTransition) (database.Workspace, database.WorkspaceBuild, database.ProvisionerJob, error) {
+	ctx := r.Context()
+
+	workspace, err := tx.GetWorkspaceByID(ctx, workspaceID)
+	if httpapi.Is404Error(err) {
+		return database.Workspace{}, database.WorkspaceBuild{}, database.ProvisionerJob{}, wsbuilder.BuildError{
+			Status:  http.StatusNotFound,
+			Message: "Workspace not found.",
+			Wrapped: err,
+		}
+	}
+	if err != nil {
+		return database.Workspace{}, database.WorkspaceBuild{}, database.ProvisionerJob{}, wsbuilder.BuildError{
+			Status:  http.StatusInternalServerError,
+			Message: "Internal error fetching workspace.",
+			Wrapped: err,
+		}
+	}
+	if workspace.Deleted {
+		return database.Workspace{}, database.WorkspaceBuild{}, database.ProvisionerJob{}, wsbuilder.BuildError{
+			Status:  http.StatusBadRequest,
+			Message: "Workspace has been deleted.",
+			Wrapped: xerrors.Errorf("workspace %s is deleted", workspaceID),
+		}
+	}
+
+	builder := wsbuilder.New(workspace, transition).
+		Initiator(initiatorID).
+		Reason(database.BuildReasonBatch).
+		DeploymentValues(api.Options.DeploymentValues)
+	workspaceBuild, provisionerJob, err := builder.Build(
+		ctx,
+		tx,
+		func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+		audit.WorkspaceBuildBaggageFromRequest(r),
+	)
+	if err != nil {
+		return database.Workspace{}, database.WorkspaceBuild{}, database.ProvisionerJob{}, err
+	}
+	return workspace, *workspaceBuild, *provisionerJob, nil
+}
+
+// batchWorkspaceBuildError converts the error that rolled back a batch into
+// the response reported for the workspace that caused it.
+func (api *API) batchWorkspaceBuildError(ctx context.Context, workspaceID uuid.UUID, err error) *codersdk.Response {
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.F("workspace_id", workspaceID), slog.Error(buildErr.Wrapped))
+		}
+		return &codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		}
+	}
+	return &codersdk.Response{
+		Message: "Error posting new build",
+		Detail:  err.Error(),
+	}
+}
+
+// convertBatchWorkspaceBuild converts a build created by
+// postBatchWorkspaceBuilds once the batch has been committed.
+func (api *API) convertBatchWorkspaceBuild(ctx context.Context, workspace database.Workspace, workspaceBuild database.WorkspaceBuild, provisionerJob database.ProvisionerJob) (*codersdk.WorkspaceBuild, *codersdk.Response) {
+	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
+		workspace.OwnerID,
+		workspaceBuild.InitiatorID,
+	})
+	if err != nil {
+		return nil, &codersdk.Response{
+			Message: "Internal error getting user.",
+			Detail:  err.Error(),
+		}
+	}
+	ownerName, exists := usernameWithID(workspace.OwnerID, users)
+	if !exists {
+		return nil, &codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  "owner not found for workspace",
+		}
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: provisionerJob,
+			QueuePosition:  0,
+		},
+		ownerName,
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentPort{},
+		[]database.WorkspaceAgentGPU{},
+		database.TemplateVersion{},
+	)
+	if err != nil {
+		return nil, &codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		}
+	}
+	return &apiBuild, nil
+}
+
 // @Summary Cancel workspace build
 // @ID cancel-workspace-build
 // @Security CoderSessionToken
@@ -674,6 +1203,8 @@ type workspaceBuildsData struct {
 	apps             []database.WorkspaceApp
 	scripts          []database.WorkspaceAgentScript
 	logSources       []database.WorkspaceAgentLogSource
+	ports            []database.WorkspaceAgentPort
+	gpus             []database.WorkspaceAgentGPU
 }
 
 func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.Workspace, workspaceBuilds []database.WorkspaceBuild) (workspaceBuildsData, error) {
@@ -681,7 +1212,10 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 	for _, workspace := range workspaces {
 		userIDs = append(userIDs, workspace.OwnerID)
 	}
-	users, err := api.Database.GetUsersByIDs(ctx, userIDs)
+	// Workspaces can be shared with users who cannot read the owner, but the
+	// owner's name is still needed to show the workspace.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), userIDs)
 	if err != nil {
 		return workspaceBuildsData{}, xerrors.Errorf("get users: %w", err)
 	}
@@ -756,6 +1290,8 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		apps       []database.WorkspaceApp
 		scripts    []database.WorkspaceAgentScript
 		logSources []database.WorkspaceAgentLogSource
+		ports      []database.WorkspaceAgentPort
+		gpus       []database.WorkspaceAgentGPU
 	)
 
 	var eg errgroup.Group
@@ -774,6 +1310,16 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		logSources, err = api.Database.GetWorkspaceAgentLogSourcesByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
 		return err
 	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting workspace agent ports by agent IDs is a system function.
+		ports, err = api.Database.GetWorkspaceAgentPortsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+		return err
+	})
+	eg.Go(func() (err error) {
+		// nolint:gocritic // Getting workspace agent GPUs by agent IDs is a system function.
+		gpus, err = api.Database.GetWorkspaceAgentGPUsByAgentIDs(dbauthz.AsSystemRestricted(ctx), agentIDs)
+		return err
+	})
 	err = eg.Wait()
 	if err != nil {
 		return workspaceBuildsData{}, err
@@ -789,6 +1335,8 @@ func (api *API) workspaceBuildsData(ctx context.Context, workspaces []database.W
 		apps:             apps,
 		scripts:          scripts,
 		logSources:       logSources,
+		ports:            ports,
+		gpus:             gpus,
 	}, nil
 }
 
@@ -803,6 +1351,8 @@ func (api *API) convertWorkspaceBuilds(
 	agentApps []database.WorkspaceApp,
 	agentScripts []database.WorkspaceAgentScript,
 	agentLogSources []database.WorkspaceAgentLogSource,
+	agentPorts []database.WorkspaceAgentPort,
+	agentGPUs []database.WorkspaceAgentGPU,
 	templateVersions []database.TemplateVersion,
 ) ([]codersdk.WorkspaceBuild, error) {
 	workspaceByID := map[uuid.UUID]database.Workspace{}
@@ -849,6 +1399,8 @@ func (api *API) convertWorkspaceBuilds(
 			agentApps,
 			agentScripts,
 			agentLogSources,
+			agentPorts,
+			agentGPUs,
 			templateVersion,
 		)
 		if err != nil {
@@ -872,6 +1424,8 @@ func (api *API) convertWorkspaceBuild(
 	agentApps []database.WorkspaceApp,
 	agentScripts []database.WorkspaceAgentScript,
 	agentLogSources []database.WorkspaceAgentLogSource,
+	agentPorts []database.WorkspaceAgentPort,
+	agentGPUs []database.WorkspaceAgentGPU,
 	templateVersion database.TemplateVersion,
 ) (codersdk.WorkspaceBuild, error) {
 	resourcesByJobID := map[uuid.UUID][]database.WorkspaceResource{}
@@ -898,6 +1452,14 @@ func (api *API) convertWorkspaceBuild(
 	for _, logSource := range agentLogSources {
 		logSourcesByAgentID[logSource.WorkspaceAgentID] = append(logSourcesByAgentID[logSource.WorkspaceAgentID], logSource)
 	}
+	portsByAgentID := map[uuid.UUID][]database.WorkspaceAgentPort{}
+	for _, port := range agentPorts {
+		portsByAgentID[port.AgentID] = append(portsByAgentID[port.AgentID], port)
+	}
+	gpusByAgentID := map[uuid.UUID][]database.WorkspaceAgentGPU{}
+	for _, gpu := range agentGPUs {
+		gpusByAgentID[gpu.AgentID] = append(gpusByAgentID[gpu.AgentID], gpu)
+	}
 
 	resources := resourcesByJobID[job.ProvisionerJob.ID]
 	apiResources := make([]codersdk.WorkspaceResource, 0)
@@ -915,6 +1477,8 @@ func (api *API) convertWorkspaceBuild(
 			if err != nil {
 				return codersdk.WorkspaceBuild{}, xerrors.Errorf("converting workspace agent: %w", err)
 			}
+			apiAgent.DiscoveredApps = db2sdk.DiscoveredApps(portsByAgentID[agent.ID], apps, agent, ownerName, workspace)
+			apiAgent.GPUs = db2sdk.WorkspaceAgentGPUs(gpusByAgentID[agent.ID])
 			apiAgents = append(apiAgents, apiAgent)
 		}
 		metadata := append(make([]database.WorkspaceResourceMetadatum, 0), metadataByResourceID[resource.ID]...)
@@ -943,6 +1507,8 @@ func (api *API) convertWorkspaceBuild(
 		Resources:           apiResources,
 		Status:              convertWorkspaceStatus(apiJob.Status, transition),
 		DailyCost:           build.DailyCost,
+		Attempt:             build.Attempt,
+		RetryAt:             codersdk.NewNullTime(build.RetryAt.Time, build.RetryAt.Valid),
 	}, nil
 }
 
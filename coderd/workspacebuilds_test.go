@@ -231,6 +231,59 @@ func TestWorkspaceBuilds(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("FilterByReason", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.BatchWorkspaceBuilds(ctx, codersdk.BatchWorkspaceBuildRequest{
+			WorkspaceIDs: []uuid.UUID{workspace.ID},
+			Transition:   codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Results, 1)
+		require.NotNil(t, resp.Results[0].Build)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, resp.Results[0].Build.ID)
+
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      codersdk.BuildReasonBatch,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, resp.Results[0].Build.ID, builds[0].ID)
+
+		builds, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      codersdk.BuildReasonInitiator,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, workspace.LatestBuild.ID, builds[0].ID)
+
+		builds, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      codersdk.BuildReasonAutostop,
+		})
+		require.NoError(t, err)
+		require.Empty(t, builds)
+
+		_, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      "bogus",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
+
 	t.Run("PaginateNonExistentRow", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -998,6 +1051,31 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Equal(t, workspace.LatestBuild.BuildNumber+1, build.BuildNumber)
+		require.Equal(t, codersdk.BuildReasonInitiator, build.Reason)
+	})
+
+	t.Run("TemplateUpdate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, newVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			TemplateVersionID: newVersion.ID,
+			Transition:        codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.BuildReasonTemplateUpdate, build.Reason)
+		require.Equal(t, user.UserID, build.InitiatorID)
 	})
 
 	t.Run("WithState", func(t *testing.T) {
@@ -1055,3 +1133,161 @@ func TestPostWorkspaceBuild(t *testing.T) {
 		require.Len(t, res.Workspaces, 0)
 	})
 }
+
+func TestWorkspaceBuildDryRun(t *testing.T) {
+	t.Parallel()
+
+	resources := func(resources ...*proto.Resource) []*proto.Response {
+		return []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Resources: resources,
+				},
+			},
+		}}
+	}
+	instance := &proto.Resource{Name: "dev", Type: "aws_instance", DailyCost: 10}
+	volume := &proto.Resource{Name: "home", Type: "aws_ebs_volume", DailyCost: 2}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlanMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_START: resources(instance, volume),
+			proto.WorkspaceTransition_STOP:  resources(volume),
+		},
+		ProvisionApplyMap: map[proto.WorkspaceTransition][]*proto.Response{
+			proto.WorkspaceTransition_START: {{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{instance, volume},
+					},
+				},
+			}},
+		},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		job, err := client.CreateWorkspaceBuildDryRun(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			job, err = client.WorkspaceBuildDryRun(ctx, workspace.ID, job.ID)
+			return assert.NoError(t, err) && job.Status == codersdk.ProvisionerJobSucceeded
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		delta, err := client.WorkspaceBuildDryRunResources(ctx, workspace.ID, job.ID)
+		require.NoError(t, err)
+		require.Empty(t, delta.Added)
+		require.Equal(t, []codersdk.WorkspaceBuildDryRunResource{{
+			Type: "aws_instance", Name: "dev", DailyCost: 10,
+		}}, delta.Removed)
+		require.Equal(t, []codersdk.WorkspaceBuildDryRunResource{{
+			Type: "aws_ebs_volume", Name: "home", DailyCost: 2,
+		}}, delta.Unchanged)
+		require.EqualValues(t, 2, delta.DailyCost)
+		require.EqualValues(t, -10, delta.DailyCostChange)
+
+		// The dry-run must not have changed the workspace.
+		latest, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, workspace.LatestBuild.ID, latest.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, latest.LatestBuild.Transition)
+	})
+
+	t.Run("OtherWorkspace", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		job, err := client.CreateWorkspaceBuildDryRun(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+
+		other := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		_, err = client.WorkspaceBuildDryRun(ctx, other.ID, job.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("Orphan", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.CreateWorkspaceBuildDryRun(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+			Orphan:     true,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestPostBatchWorkspaceBuilds(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	workspaceA := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	workspaceB := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspaceA.LatestBuild.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspaceB.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// A failure for one workspace prevents builds for all of them.
+	missing := uuid.New()
+	resp, err := client.BatchWorkspaceBuilds(ctx, codersdk.BatchWorkspaceBuildRequest{
+		WorkspaceIDs: []uuid.UUID{missing, workspaceA.ID, workspaceB.ID},
+		Transition:   codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+	require.Equal(t, missing, resp.Results[0].WorkspaceID)
+	require.Equal(t, "Workspace not found.", resp.Results[0].Error.Message)
+	for _, result := range resp.Results {
+		require.Nil(t, result.Build)
+		require.NotNil(t, result.Error)
+	}
+	workspace, err := client.Workspace(ctx, workspaceB.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspaceB.LatestBuild.ID, workspace.LatestBuild.ID)
+
+	resp, err = client.BatchWorkspaceBuilds(ctx, codersdk.BatchWorkspaceBuildRequest{
+		WorkspaceIDs: []uuid.UUID{workspaceA.ID, workspaceB.ID, workspaceA.ID},
+		Transition:   codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	// Duplicate IDs are only built once.
+	require.Len(t, resp.Results, 2)
+	for i, workspaceID := range []uuid.UUID{workspaceA.ID, workspaceB.ID} {
+		require.Equal(t, workspaceID, resp.Results[i].WorkspaceID)
+		require.Nil(t, resp.Results[i].Error)
+		require.NotNil(t, resp.Results[i].Build)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, resp.Results[i].Build.Transition)
+		require.Equal(t, codersdk.BuildReasonBatch, resp.Results[i].Build.Reason)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, resp.Results[i].Build.ID)
+	}
+
+	_, err = client.BatchWorkspaceBuilds(ctx, codersdk.BatchWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
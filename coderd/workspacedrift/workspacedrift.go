@@ -0,0 +1,193 @@
+// Package workspacedrift periodically plans running workspaces against their
+// Terraform state to detect infrastructure that was changed outside of Coder.
+package workspacedrift
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+const (
+	delay = time.Minute
+	// maxChecks bounds how many drift checks are started per tick, so
+	// provisioners are not flooded with plans when detection is enabled.
+	maxChecks = 25
+)
+
+// Options configure how often workspaces are checked for drift.
+type Options struct {
+	// Interval is how long after a drift check of a workspace was started
+	// before it is checked again.
+	Interval time.Duration
+	// Registerer registers the drift metrics, if set.
+	Registerer prometheus.Registerer
+}
+
+// New periodically starts drift checks of running workspaces. Each check is
+// a dry-run of the latest build, and its result is recorded by provisionerd
+// when the job completes. It is the caller's responsibility to call Close on
+// the returned instance.
+func New(ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.Pubsub, opts Options) io.Closer {
+	closed := make(chan struct{})
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system checks workspaces for drift without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	metrics := newMetrics(opts.Registerer)
+
+	tickDelay := delay
+	if opts.Interval < tickDelay {
+		tickDelay = opts.Interval
+	}
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(tickDelay)
+
+		started, err := check(ctx, logger, db, ps, dbtime.Now().Add(-opts.Interval))
+		metrics.checks.Add(float64(started))
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Error(ctx, "failed to check workspaces for drift", slog.Error(err))
+		}
+		count, err := db.GetDriftedWorkspaceCount(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Error(ctx, "failed to get drifted workspace count", slog.Error(err))
+			return
+		}
+		metrics.driftedWorkspaces.Set(float64(count))
+	}
+
+	go func() {
+		defer close(closed)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ticker.Stop()
+				doTick()
+			}
+		}
+	}()
+	return &instance{
+		cancel: cancelFunc,
+		closed: closed,
+	}
+}
+
+type instance struct {
+	cancel context.CancelFunc
+	closed chan struct{}
+}
+
+func (i *instance) Close() error {
+	i.cancel()
+	<-i.closed
+	return nil
+}
+
+type metrics struct {
+	checks            prometheus.Counter
+	driftedWorkspaces prometheus.Gauge
+}
+
+func newMetrics(registerer prometheus.Registerer) metrics {
+	m := metrics{
+		checks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "workspace_drift",
+			Name:      "checks_total",
+			Help:      "The number of workspace drift checks started.",
+		}),
+		driftedWorkspaces: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "workspace_drift",
+			Name:      "drifted_workspaces",
+			Help:      "The number of workspaces whose latest drift check found drifted resources.",
+		}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(m.checks, m.driftedWorkspaces)
+	}
+	return m
+}
+
+// check starts drift checks of the workspaces that were last checked before
+// the given time, and returns how many were started.
+func check(ctx context.Context, logger slog.Logger, db database.Store, ps pubsub.Pubsub, lastCheckBefore time.Time) (int, error) {
+	workspaceIDs, err := db.GetWorkspaceIDsForDriftCheck(ctx, lastCheckBefore)
+	if err != nil {
+		return 0, xerrors.Errorf("get workspaces to check: %w", err)
+	}
+	if len(workspaceIDs) > maxChecks {
+		workspaceIDs = workspaceIDs[:maxChecks]
+	}
+	started := 0
+	for _, workspaceID := range workspaceIDs {
+		err = CheckWorkspace(ctx, db, ps, workspaceID)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return started, err
+			}
+			// A single workspace failing to plan, e.g. because its template
+			// version is missing parameters, must not block the others.
+			logger.Warn(ctx, "failed to start drift check", slog.F("workspace_id", workspaceID), slog.Error(err))
+			continue
+		}
+		started++
+	}
+	return started, nil
+}
+
+// CheckWorkspace starts a drift check of the workspace by planning its latest
+// build.
+func CheckWorkspace(ctx context.Context, db database.Store, ps pubsub.Pubsub, workspaceID uuid.UUID) error {
+	workspace, err := db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return xerrors.Errorf("get workspace: %w", err)
+	}
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).DryRun()
+	_, job, err := builder.Build(ctx, db, nil, audit.WorkspaceBuildBaggage{IP: "127.0.0.1"})
+	if err != nil {
+		return xerrors.Errorf("build dry-run: %w", err)
+	}
+	err = db.UpsertWorkspaceDriftJob(ctx, database.UpsertWorkspaceDriftJobParams{
+		WorkspaceID: workspaceID,
+		JobID:       job.ID,
+		Now:         dbtime.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("record drift job: %w", err)
+	}
+	err = provisionerjobs.PostJob(ps, *job)
+	if err != nil {
+		return xerrors.Errorf("post job: %w", err)
+	}
+	return nil
+}
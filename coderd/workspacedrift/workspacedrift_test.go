@@ -0,0 +1,118 @@
+package workspacedrift_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/workspacedrift"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// Ensures no goroutines leak.
+func TestNew(t *testing.T) {
+	t.Parallel()
+	detector := workspacedrift.New(context.Background(), slogtest.Make(t, nil), dbmem.New(), pubsub.NewInMemory(), workspacedrift.Options{
+		Interval: time.Hour,
+	})
+	err := detector.Close()
+	require.NoError(t, err)
+}
+
+func TestWorkspacesForDriftCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	db := dbmem.New()
+	now := dbtime.Now()
+
+	workspace := dbgen.Workspace(t, db, database.Workspace{})
+	buildJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		StartedAt:   sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+	})
+	build := dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID: workspace.ID,
+		JobID:       buildJob.ID,
+		Transition:  database.WorkspaceTransitionStart,
+	})
+	// Stopped workspaces are never checked.
+	stopped := dbgen.Workspace(t, db, database.Workspace{})
+	stopJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		StartedAt:   sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+		CompletedAt: sql.NullTime{Time: now.Add(-2 * time.Hour), Valid: true},
+	})
+	dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID: stopped.ID,
+		JobID:       stopJob.ID,
+		Transition:  database.WorkspaceTransitionStop,
+	})
+
+	// Workspaces that were never checked are due.
+	ids, err := db.GetWorkspaceIDsForDriftCheck(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{workspace.ID}, ids)
+
+	// A pending drift check holds off the next one.
+	driftJob := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{
+		Type:      database.ProvisionerJobTypeWorkspaceBuildDryRun,
+		CreatedAt: now.Add(-90 * time.Minute),
+	})
+	err = db.UpsertWorkspaceDriftJob(ctx, database.UpsertWorkspaceDriftJobParams{
+		WorkspaceID: workspace.ID,
+		JobID:       driftJob.ID,
+		Now:         now,
+	})
+	require.NoError(t, err)
+	ids, err = db.GetWorkspaceIDsForDriftCheck(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	// Once the check completes, the result is recorded and the workspace is
+	// due again after the interval.
+	err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:          driftJob.ID,
+		UpdatedAt:   now,
+		CompletedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	err = db.UpdateWorkspaceDriftResult(ctx, database.UpdateWorkspaceDriftResultParams{
+		BuildID:          uuid.NullUUID{UUID: build.ID, Valid: true},
+		CheckedAt:        now,
+		DriftedResources: []string{"docker_container.workspace"},
+		WorkspaceID:      workspace.ID,
+		JobID:            driftJob.ID,
+	})
+	require.NoError(t, err)
+	ids, err = db.GetWorkspaceIDsForDriftCheck(ctx, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, ids)
+	ids, err = db.GetWorkspaceIDsForDriftCheck(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{workspace.ID}, ids)
+
+	drift, err := db.GetWorkspaceDriftByWorkspaceID(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"docker_container.workspace"}, drift.DriftedResources)
+	require.Equal(t, build.ID, drift.BuildID.UUID)
+
+	count, err := db.GetDriftedWorkspaceCount(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+}
@@ -0,0 +1,96 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/moby/moby/pkg/namesgenerator"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// maxWorkspaceNameAttempts bounds the number of random names tried when
+// generating a name that complies with a template's naming policy.
+const maxWorkspaceNameAttempts = 100
+
+// workspaceNameMatchesPolicy reports whether name complies with the workspace
+// naming policy of the template.
+func workspaceNameMatchesPolicy(template database.Template, name string) (bool, error) {
+	if template.WorkspaceNamePattern == "" {
+		return true, nil
+	}
+	re, err := regexp.Compile(template.WorkspaceNamePattern)
+	if err != nil {
+		return false, xerrors.Errorf("compile workspace name pattern: %w", err)
+	}
+	return re.MatchString(name), nil
+}
+
+// workspaceNamePolicyResponse explains the naming policy of the template to a
+// user whose workspace name doesn't comply with it.
+func workspaceNamePolicyResponse(template database.Template, name string) codersdk.Response {
+	detail := template.WorkspaceNameDescription
+	if detail == "" {
+		detail = fmt.Sprintf("Workspace names must match the regular expression %q.", template.WorkspaceNamePattern)
+	}
+	return codersdk.Response{
+		Message: fmt.Sprintf("Workspace name %q doesn't comply with the naming policy of template %q.", name, template.Name),
+		Detail:  detail,
+		Validations: []codersdk.ValidationError{{
+			Field:  "name",
+			Detail: detail,
+		}},
+	}
+}
+
+// generateWorkspaceName returns a valid workspace name that complies with the
+// naming policy of the template and isn't used by the owner's workspaces.
+// Random names are tried as is and after the literal prefix of the pattern,
+// e.g. "dev-" for "^dev-[a-z-]+$".
+func generateWorkspaceName(ctx context.Context, db database.Store, template database.Template, ownerID uuid.UUID) (string, error) {
+	var prefix string
+	if template.WorkspaceNamePattern != "" {
+		// LiteralPrefix doesn't look past a leading anchor, but patterns
+		// usually start with one.
+		re, err := regexp.Compile(strings.TrimPrefix(template.WorkspaceNamePattern, "^"))
+		if err != nil {
+			return "", xerrors.Errorf("compile workspace name pattern: %w", err)
+		}
+		prefix, _ = re.LiteralPrefix()
+	}
+
+	for i := 0; i < maxWorkspaceNameAttempts; i++ {
+		random := strings.ReplaceAll(namesgenerator.GetRandomName(0), "_", "-")
+		for _, name := range []string{prefix + random, random} {
+			if httpapi.NameValid(name) != nil {
+				continue
+			}
+			ok, err := workspaceNameMatchesPolicy(template, name)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				continue
+			}
+			_, err = db.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+				OwnerID: ownerID,
+				Name:    name,
+			})
+			if errors.Is(err, sql.ErrNoRows) {
+				return name, nil
+			}
+			if err != nil {
+				return "", xerrors.Errorf("get workspace by name: %w", err)
+			}
+		}
+	}
+	return "", xerrors.Errorf("no name complying with the naming policy was found after %d attempts", maxWorkspaceNameAttempts)
+}
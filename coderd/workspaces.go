@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -19,6 +21,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbreplica"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -80,7 +83,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	data, err := api.workspaceData(ctx, httpmw.APIKey(r).UserID, []database.Workspace{workspace})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching workspace resources.",
@@ -106,6 +109,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		data.builds[0],
 		data.templates[0],
 		ownerName,
+		data.favorites[workspace.ID],
 		api.Options.AllowWorkspaceRenames,
 	))
 }
@@ -118,7 +122,7 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 // @Security CoderSessionToken
 // @Produce json
 // @Tags Workspaces
-// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before."
+// @Param q query string false "Search query in the format `key:value`. Available keys are: owner, template, name, status, has-agent, dormant, last_used_after, last_used_before, favorite."
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
 // @Success 200 {object} codersdk.WorkspacesResponse
@@ -146,6 +150,8 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 		filter.OwnerID = apiKey.UserID
 		filter.OwnerUsername = ""
 	}
+	// Favorites are per user, so they are always relative to the caller.
+	filter.RequesterID = apiKey.UserID
 
 	// Workspaces do not have ACL columns.
 	prepared, err := api.HTTPAuth.AuthorizeSQLFilter(r, rbac.ActionRead, rbac.ResourceWorkspace.Type)
@@ -175,7 +181,7 @@ func (api *API) workspaces(rw http.ResponseWriter, r *http.Request) {
 
 	workspaces := database.ConvertWorkspaceRows(workspaceRows)
 
-	data, err := api.workspaceData(ctx, workspaces)
+	data, err := api.workspaceData(ctx, apiKey.UserID, workspaces)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching workspace resources.",
@@ -252,7 +258,7 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	data, err := api.workspaceData(ctx, httpmw.APIKey(r).UserID, []database.Workspace{workspace})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching workspace resources.",
@@ -278,6 +284,7 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		data.builds[0],
 		data.templates[0],
 		ownerName,
+		data.favorites[workspace.ID],
 		api.Options.AllowWorkspaceRenames,
 	))
 }
@@ -414,6 +421,19 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	nameOK, err := workspaceNameMatchesPolicy(template, createWorkspace.Name)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error checking workspace name policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !nameOK {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, workspaceNamePolicyResponse(template, createWorkspace.Name))
+		return
+	}
+
 	dbAutostartSchedule, err := validWorkspaceSchedule(createWorkspace.AutostartSchedule)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -447,6 +467,22 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Ephemeral workspaces are deleted once they outlive their TTL or the
+	// API token that created them expires, so they need at least one.
+	var ephemeralAPIKeyID sql.NullString
+	if createWorkspace.Ephemeral {
+		if apiKey.LoginType == database.LoginTypeToken {
+			ephemeralAPIKeyID = sql.NullString{String: apiKey.ID, Valid: true}
+		}
+		if !dbTTL.Valid && !ephemeralAPIKeyID.Valid {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Ephemeral workspaces must have a time to shutdown or be created with an API token.",
+				Validations: []codersdk.ValidationError{{Field: "ttl_ms", Detail: "required for ephemeral workspaces created without an API token"}},
+			})
+			return
+		}
+	}
+
 	// back-compatibility: default to "never" if not included.
 	dbAU := database.AutomaticUpdatesNever
 	if createWorkspace.AutomaticUpdates != "" {
@@ -460,6 +496,40 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		}
 	}
 
+	richParameterValues := createWorkspace.RichParameterValues
+	if createWorkspace.Preset != "" {
+		templateVersionID := template.ActiveVersionID
+		if createWorkspace.TemplateVersionID != uuid.Nil {
+			templateVersionID = createWorkspace.TemplateVersionID
+		}
+		presets, err := api.Database.GetTemplateVersionPresets(ctx, templateVersionID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template version presets.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		idx := slices.IndexFunc(presets, func(preset database.TemplateVersionPreset) bool {
+			return preset.Name == createWorkspace.Preset
+		})
+		if idx == -1 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     fmt.Sprintf("Preset %q doesn't exist.", createWorkspace.Preset),
+				Validations: []codersdk.ValidationError{{Field: "preset", Detail: "preset not found for the template version"}},
+			})
+			return
+		}
+		richParameterValues, err = applyTemplateVersionPreset(presets[idx], richParameterValues)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error applying template version preset.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	// TODO: This should be a system call as the actor might not be able to
 	// read other workspaces. Ideally we check the error on create and look for
 	// a postgres conflict error.
@@ -505,8 +575,10 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 			Ttl:               dbTTL,
 			// The workspaces page will sort by last used at, and it's useful to
 			// have the newly created workspace at the top of the list!
-			LastUsedAt:       dbtime.Now(),
-			AutomaticUpdates: dbAU,
+			LastUsedAt:        dbtime.Now(),
+			AutomaticUpdates:  dbAU,
+			Ephemeral:         createWorkspace.Ephemeral,
+			EphemeralAPIKeyID: ephemeralAPIKeyID,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert workspace: %w", err)
@@ -516,7 +588,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 			Reason(database.BuildReasonInitiator).
 			Initiator(apiKey.UserID).
 			ActiveVersion().
-			RichParameterValues(createWorkspace.RichParameterValues)
+			RichParameterValues(richParameterValues)
 		if createWorkspace.TemplateVersionID != uuid.Nil {
 			builder = builder.VersionID(createWorkspace.TemplateVersionID)
 		}
@@ -572,6 +644,8 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		[]database.WorkspaceApp{},
 		[]database.WorkspaceAgentScript{},
 		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentPort{},
+		[]database.WorkspaceAgentGPU{},
 		database.TemplateVersion{},
 	)
 	if err != nil {
@@ -587,6 +661,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		apiBuild,
 		template,
 		member.Username,
+		false,
 		api.Options.AllowWorkspaceRenames,
 	))
 }
@@ -638,6 +713,28 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 		name = req.Name
+
+		// nolint:gocritic // The template may be private to the workspace owner.
+		template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		nameOK, err := workspaceNameMatchesPolicy(template, name)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error checking workspace name policy.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !nameOK {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, workspaceNamePolicyResponse(template, name))
+			return
+		}
 	}
 
 	newWorkspace, err := api.Database.UpdateWorkspace(ctx, database.UpdateWorkspaceParams{
@@ -808,12 +905,32 @@ func (api *API) putWorkspaceTTL(rw http.ResponseWriter, r *http.Request) {
 		if validityErr != nil {
 			return codersdk.ValidationError{Field: "ttl_ms", Detail: validityErr.Error()}
 		}
+		if workspace.Ephemeral && !workspace.EphemeralAPIKeyID.Valid && !dbTTL.Valid {
+			return codersdk.ValidationError{Field: "ttl_ms", Detail: "Ephemeral workspaces created without an API token must have a TTL."}
+		}
 		if err := s.UpdateWorkspaceTTL(ctx, database.UpdateWorkspaceTTLParams{
 			ID:  workspace.ID,
 			Ttl: dbTTL,
 		}); err != nil {
 			return xerrors.Errorf("update workspace time until shutdown: %w", err)
 		}
+		if dbTTL != workspace.Ttl {
+			err = s.InsertWorkspaceEvent(ctx, database.InsertWorkspaceEventParams{
+				ID:          uuid.New(),
+				WorkspaceID: workspace.ID,
+				CreatedAt:   dbtime.Now(),
+				Type:        database.WorkspaceEventTypeTtlChanged,
+				UserID: uuid.NullUUID{
+					UUID:  httpmw.APIKey(r).UserID,
+					Valid: true,
+				},
+				OldValue: workspaceEventTTLValue(workspace.Ttl),
+				NewValue: workspaceEventTTLValue(dbTTL),
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace event: %w", err)
+			}
+		}
 
 		return nil
 	}, nil)
@@ -898,7 +1015,7 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+	data, err := api.workspaceData(ctx, httpmw.APIKey(r).UserID, []database.Workspace{workspace})
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Internal error fetching workspace resources.",
@@ -926,6 +1043,7 @@ func (api *API) putWorkspaceDormant(rw http.ResponseWriter, r *http.Request) {
 		data.builds[0],
 		data.templates[0],
 		ownerName,
+		data.favorites[workspace.ID],
 		api.Options.AllowWorkspaceRenames,
 	))
 }
@@ -1081,6 +1199,451 @@ func (api *API) putWorkspaceAutoupdates(rw http.ResponseWriter, r *http.Request)
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// @Summary Update workspace maintenance opt-out by ID
+// @ID update-workspace-maintenance-opt-out-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceMaintenanceOptOutRequest true "Maintenance opt-out request"
+// @Success 204
+// @Router /workspaces/{workspace}/maintenance-opt-out [put]
+func (api *API) putWorkspaceMaintenanceOptOut(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceMaintenanceOptOutRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceMaintenanceOptOut(ctx, database.UpdateWorkspaceMaintenanceOptOutParams{
+		ID:                workspace.ID,
+		MaintenanceOptOut: req.OptOut,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace maintenance opt-out setting",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	newWorkspace := workspace
+	newWorkspace.MaintenanceOptOut = req.OptOut
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace ACL
+// @ID get-workspace-acl
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceACL
+// @Router /workspaces/{workspace}/acl [get]
+func (api *API) workspaceACL(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	userIDs := make([]uuid.UUID, 0, len(workspace.UserACL))
+	for id := range workspace.UserACL {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	// Anyone who can read the workspace can see who it is shared with, even
+	// if they cannot read those users otherwise.
+	// nolint:gocritic
+	users, err := api.Database.GetUsersByIDs(dbauthz.AsSystemRestricted(ctx), userIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching users.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	acl := codersdk.WorkspaceACL{
+		Users: make([]codersdk.WorkspaceUser, 0, len(users)),
+	}
+	for _, user := range users {
+		acl.Users = append(acl.Users, codersdk.WorkspaceUser{
+			MinimalUser: codersdk.MinimalUser{
+				ID:        user.ID,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
+			},
+			Role: convertToWorkspaceRole(workspace.UserACL[user.ID.String()]),
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, acl)
+}
+
+// @Summary Update workspace ACL
+// @ID update-workspace-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceACL true "Update workspace ACL request"
+// @Success 204
+// @Router /workspaces/{workspace}/acl [patch]
+func (api *API) patchWorkspaceACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	userRoles := make(map[uuid.UUID]codersdk.WorkspaceRole, len(req.UserRoles))
+	var validErrs []codersdk.ValidationError
+	for userQuery, role := range req.UserRoles {
+		if convertSDKWorkspaceRole(role) == nil && role != codersdk.WorkspaceRoleDeleted {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_roles", Detail: fmt.Sprintf("Role %q is not a valid workspace role.", role)})
+			continue
+		}
+		user, err := api.resolveWorkspaceACLUser(ctx, userQuery)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_roles", Detail: fmt.Sprintf("Failed to find user %q: %s", userQuery, err.Error())})
+			continue
+		}
+		if user.ID == workspace.OwnerID {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "user_roles", Detail: "A workspace cannot be shared with its owner."})
+			continue
+		}
+		userRoles[user.ID] = role
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var newWorkspace database.Workspace
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		newWorkspace, err = tx.GetWorkspaceByID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get workspace by ID: %w", err)
+		}
+
+		userACL := make(database.TemplateACL, len(newWorkspace.UserACL))
+		for id, actions := range newWorkspace.UserACL {
+			userACL[id] = actions
+		}
+		for id, role := range userRoles {
+			// An empty role implies deletion.
+			if role == codersdk.WorkspaceRoleDeleted {
+				delete(userACL, id.String())
+				continue
+			}
+			userACL[id.String()] = convertSDKWorkspaceRole(role)
+		}
+
+		err = tx.UpdateWorkspaceACLByID(ctx, database.UpdateWorkspaceACLByIDParams{
+			ID:      workspace.ID,
+			UserACL: userACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace ACL by ID: %w", err)
+		}
+		newWorkspace.UserACL = userACL
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace ACL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// resolveWorkspaceACLUser looks up a user by ID or username. Workspace owners
+// usually cannot read other users, so this is done as the system.
+func (api *API) resolveWorkspaceACLUser(ctx context.Context, userQuery string) (database.User, error) {
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	if userID, err := uuid.Parse(userQuery); err == nil {
+		return api.Database.GetUserByID(ctx, userID)
+	}
+	return api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+		Username: userQuery,
+	})
+}
+
+func convertToWorkspaceRole(actions []rbac.Action) codersdk.WorkspaceRole {
+	if slices.Equal(actions, convertSDKWorkspaceRole(codersdk.WorkspaceRoleUse)) {
+		return codersdk.WorkspaceRoleUse
+	}
+	return ""
+}
+
+// convertSDKWorkspaceRole returns the actions a role grants. They apply to the
+// workspace itself, so it can be read, and to connecting to it over SSH and to
+// its apps, which both use the create action.
+func convertSDKWorkspaceRole(role codersdk.WorkspaceRole) []rbac.Action {
+	if role == codersdk.WorkspaceRoleUse {
+		return []rbac.Action{rbac.ActionRead, rbac.ActionCreate}
+	}
+	return nil
+}
+
+// @Summary Update workspace dormancy exemption by ID
+// @ID update-workspace-dormancy-exemption-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceDormancyExemptRequest true "Dormancy exemption request"
+// @Success 204
+// @Router /workspaces/{workspace}/dormancy-exempt [put]
+func (api *API) putWorkspaceDormancyExempt(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceDormancyExemptRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	// Only users that can update the template, e.g. template admins, may
+	// exempt workspaces from its dormancy settings.
+	err := api.Database.UpdateWorkspaceDormancyExempt(ctx, database.UpdateWorkspaceDormancyExemptParams{
+		ID:             workspace.ID,
+		DormancyExempt: req.Exempt,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace dormancy exemption.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	newWorkspace := workspace
+	newWorkspace.DormancyExempt = req.Exempt
+	aReq.New = newWorkspace
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Favorite workspace by ID
+// @ID favorite-workspace-by-id
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/favorite [put]
+func (api *API) putFavoriteWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		apiKey    = httpmw.APIKey(r)
+	)
+
+	err := api.Database.InsertWorkspaceFavorite(ctx, database.InsertWorkspaceFavoriteParams{
+		UserID:      apiKey.UserID,
+		WorkspaceID: workspace.ID,
+		CreatedAt:   dbtime.Now(),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error favoriting workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Unfavorite workspace by ID
+// @ID unfavorite-workspace-by-id
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 204
+// @Router /workspaces/{workspace}/favorite [delete]
+func (api *API) deleteFavoriteWorkspace(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx       = r.Context()
+		workspace = httpmw.WorkspaceParam(r)
+		apiKey    = httpmw.APIKey(r)
+	)
+
+	err := api.Database.DeleteWorkspaceFavorite(ctx, database.DeleteWorkspaceFavoriteParams{
+		UserID:      apiKey.UserID,
+		WorkspaceID: workspace.ID,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error unfavoriting workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get workspace drift
+// @ID get-workspace-drift
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceDrift
+// @Router /workspaces/{workspace}/drift [get]
+func (api *API) workspaceDrift(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	drift, err := api.Database.GetWorkspaceDriftByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The workspace has not been checked for drift.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace drift.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.WorkspaceDrift{
+		WorkspaceID:      drift.WorkspaceID,
+		JobID:            drift.JobID,
+		DriftedResources: drift.DriftedResources,
+	}
+	if drift.BuildID.Valid {
+		resp.BuildID = &drift.BuildID.UUID
+	}
+	if drift.CheckedAt.Valid {
+		resp.CheckedAt = &drift.CheckedAt.Time
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get workspace parameter history
+// @ID get-workspace-parameter-history
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param name query string false "Parameter name"
+// @Success 200 {array} codersdk.WorkspaceParameterChange
+// @Router /workspaces/{workspace}/parameters/history [get]
+func (api *API) workspaceParameterHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	changes, err := api.Database.GetWorkspaceParameterChanges(ctx, database.GetWorkspaceParameterChangesParams{
+		WorkspaceID: workspace.ID,
+		Name:        r.URL.Query().Get("name"),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace parameter history.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.WorkspaceParameterChange, 0, len(changes))
+	for _, change := range changes {
+		converted := codersdk.WorkspaceParameterChange{
+			WorkspaceBuildID:  change.WorkspaceBuildID,
+			BuildNumber:       change.BuildNumber,
+			Name:              change.Name,
+			NewValue:          change.NewValue,
+			InitiatorID:       change.InitiatorID,
+			InitiatorUsername: change.InitiatorUsername,
+			CreatedAt:         change.CreatedAt,
+		}
+		if change.OldValue.Valid {
+			converted.OldValue = ptr.Ref(change.OldValue.String)
+		}
+		resp = append(resp, converted)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 // @Summary Resolve workspace autostart by id.
 // @ID resolve-workspace-autostart-by-id
 // @Security CoderSessionToken
@@ -1095,7 +1658,8 @@ func (api *API) resolveAutostart(rw http.ResponseWriter, r *http.Request) {
 		workspace = httpmw.WorkspaceParam(r)
 	)
 
-	template, err := api.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	// nolint:gocritic // The template may be private to the workspace owner.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
@@ -1213,7 +1777,7 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		data, err := api.workspaceData(ctx, []database.Workspace{workspace})
+		data, err := api.workspaceData(ctx, httpmw.APIKey(r).UserID, []database.Workspace{workspace})
 		if err != nil {
 			_ = sendEvent(ctx, codersdk.ServerSentEvent{
 				Type: codersdk.ServerSentEventTypeError,
@@ -1252,6 +1816,7 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 				data.builds[0],
 				data.templates[0],
 				ownerName,
+				data.favorites[workspace.ID],
 				api.Options.AllowWorkspaceRenames,
 			),
 		})
@@ -1307,6 +1872,7 @@ type workspaceData struct {
 	templates    []database.Template
 	builds       []codersdk.WorkspaceBuild
 	users        []database.User
+	favorites    map[uuid.UUID]bool
 	allowRenames bool
 }
 
@@ -1314,7 +1880,8 @@ type workspaceData struct {
 // does not have the correct perms to read a given template, the template will
 // not be returned.
 // So the caller must check the templates & users exist before using them.
-func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspace) (workspaceData, error) {
+// Favorites are those of requesterID.
+func (api *API) workspaceData(ctx context.Context, requesterID uuid.UUID, workspaces []database.Workspace) (workspaceData, error) {
 	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
 	templateIDs := make([]uuid.UUID, 0, len(workspaces))
 	for _, workspace := range workspaces {
@@ -1322,7 +1889,12 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		templateIDs = append(templateIDs, workspace.TemplateID)
 	}
 
-	templates, err := api.Database.GetTemplatesWithFilter(ctx, database.GetTemplatesWithFilterParams{
+	// The requester can read these workspaces, so they can also see the
+	// templates they were built from, even if the template is private. The
+	// workspaces may have just been written, so their templates are read from
+	// the primary, which has them for sure.
+	// nolint:gocritic
+	templates, err := api.Database.GetTemplatesWithFilter(dbreplica.WithPrimary(dbauthz.AsSystemRestricted(ctx)), database.GetTemplatesWithFilterParams{
 		IDs: templateIDs,
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -1352,16 +1924,30 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		data.apps,
 		data.scripts,
 		data.logSources,
+		data.ports,
+		data.gpus,
 		data.templateVersions,
 	)
 	if err != nil {
 		return workspaceData{}, xerrors.Errorf("convert workspace builds: %w", err)
 	}
 
+	// Tokens scoped to workspaces can't read the requester's user data, so
+	// they see no favorites.
+	favoriteIDs, err := api.Database.GetFavoriteWorkspaceIDsByUserID(ctx, requesterID)
+	if err != nil && !dbauthz.IsNotAuthorizedError(err) {
+		return workspaceData{}, xerrors.Errorf("get favorite workspaces: %w", err)
+	}
+	favorites := make(map[uuid.UUID]bool, len(favoriteIDs))
+	for _, id := range favoriteIDs {
+		favorites[id] = true
+	}
+
 	return workspaceData{
 		templates:    templates,
 		builds:       apiBuilds,
 		users:        data.users,
+		favorites:    favorites,
 		allowRenames: api.Options.AllowWorkspaceRenames,
 	}, nil
 }
@@ -1405,6 +1991,7 @@ func convertWorkspaces(workspaces []database.Workspace, data workspaceData) ([]c
 			build,
 			template,
 			owner.Username,
+			data.favorites[workspace.ID],
 			data.allowRenames,
 		))
 	}
@@ -1416,6 +2003,7 @@ func convertWorkspace(
 	workspaceBuild codersdk.WorkspaceBuild,
 	template database.Template,
 	ownerName string,
+	favorite bool,
 	allowRenames bool,
 ) codersdk.Workspace {
 	var autostartSchedule *string
@@ -1470,8 +2058,12 @@ func convertWorkspace(
 			Healthy:       len(failingAgents) == 0,
 			FailingAgents: failingAgents,
 		},
-		AutomaticUpdates: codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
-		AllowRenames:     allowRenames,
+		AutomaticUpdates:  codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
+		AllowRenames:      allowRenames,
+		Favorite:          favorite,
+		MaintenanceOptOut: workspace.MaintenanceOptOut,
+		Ephemeral:         workspace.Ephemeral,
+		DormancyExempt:    workspace.DormancyExempt,
 	}
 }
 
@@ -1484,6 +2076,16 @@ func convertWorkspaceTTLMillis(i sql.NullInt64) *int64 {
 	return &millis
 }
 
+// workspaceEventTTLValue formats a workspace TTL as it's stored on ttl_changed
+// workspace events: in milliseconds, or empty if autostop is disabled.
+func workspaceEventTTLValue(i sql.NullInt64) string {
+	millis := convertWorkspaceTTLMillis(i)
+	if millis == nil {
+		return ""
+	}
+	return strconv.FormatInt(*millis, 10)
+}
+
 func validWorkspaceTTLMillis(millis *int64, templateDefault, templateMax time.Duration) (sql.NullInt64, error) {
 	if templateDefault == 0 && templateMax != 0 || (templateMax > 0 && templateDefault > templateMax) {
 		templateDefault = templateMax
@@ -32,6 +32,7 @@ import (
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/schedule/cron"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/workspacedrift"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -365,6 +366,41 @@ func TestWorkspace(t *testing.T) {
 		require.Error(t, err, "create workspace with archived version")
 		require.ErrorContains(t, err, "Archived template versions cannot")
 	})
+
+	t.Run("PrivateTemplate", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		private := codersdk.TemplateVisibilityPrivate
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Visibility: &private,
+		})
+		require.NoError(t, err)
+
+		// The owner can no longer see the template, but still sees their
+		// workspace and the template it was built from.
+		_, err = member.Template(ctx, template.ID)
+		require.Error(t, err)
+		ws, err := member.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ID, ws.TemplateID)
+		require.Equal(t, template.Name, ws.TemplateName)
+		workspaces, err := member.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Owner: memberUser.Username,
+		})
+		require.NoError(t, err)
+		require.Len(t, workspaces.Workspaces, 1)
+		require.Equal(t, workspace.ID, workspaces.Workspaces[0].ID)
+	})
 }
 
 func TestResolveAutostart(t *testing.T) {
@@ -576,6 +612,60 @@ func TestPostWorkspacesByOrganization(t *testing.T) {
 		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
 	})
 
+	t.Run("NamePolicy", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			AllowWorkspaceRenames:    true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			WorkspaceNamePolicy: &codersdk.TemplateWorkspaceNamePolicy{
+				Pattern:     "^dev-",
+				Description: "Workspace names must start with dev-.",
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "workspace",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "Workspace names must start with dev-.", apiErr.Detail)
+		require.Len(t, apiErr.Validations, 1)
+		require.Equal(t, "name", apiErr.Validations[0].Field)
+
+		generated, err := client.GenerateWorkspaceName(ctx, template.ID)
+		require.NoError(t, err)
+		workspace, err := client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       generated.Name,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		err = client.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+			Name: "renamed",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		err = client.UpdateWorkspace(ctx, workspace.ID, codersdk.UpdateWorkspaceRequest{
+			Name: "dev-renamed",
+		})
+		require.NoError(t, err)
+	})
+
 	t.Run("AlreadyExists", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -2181,6 +2271,130 @@ func TestUpdateWorkspaceAutomaticUpdates_NotFound(t *testing.T) {
 	require.Contains(t, coderSDKErr.Message, "Resource not found", "unexpected response code")
 }
 
+func TestWorkspaceACL(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	shared, sharedUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Workspaces are not visible to other members until they are shared.
+	_, err := shared.Workspace(ctx, workspace.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	err = member.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserRoles: map[string]codersdk.WorkspaceRole{
+			sharedUser.Username: codersdk.WorkspaceRoleUse,
+		},
+	})
+	require.NoError(t, err)
+
+	acl, err := member.WorkspaceACL(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Len(t, acl.Users, 1)
+	require.Equal(t, sharedUser.ID, acl.Users[0].ID)
+	require.Equal(t, codersdk.WorkspaceRoleUse, acl.Users[0].Role)
+
+	got, err := shared.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, workspace.ID, got.ID)
+
+	// Shared users can use the workspace, but not change who it is shared with.
+	err = shared.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserRoles: map[string]codersdk.WorkspaceRole{
+			sharedUser.ID.String(): codersdk.WorkspaceRoleDeleted,
+		},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	// Owners cannot share workspaces with themselves.
+	err = member.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserRoles: map[string]codersdk.WorkspaceRole{
+			workspace.OwnerID.String(): codersdk.WorkspaceRoleUse,
+		},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	err = member.UpdateWorkspaceACL(ctx, workspace.ID, codersdk.UpdateWorkspaceACL{
+		UserRoles: map[string]codersdk.WorkspaceRole{
+			sharedUser.ID.String(): codersdk.WorkspaceRoleDeleted,
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = shared.Workspace(ctx, workspace.ID)
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
+func TestWorkspaceFavorite(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	first := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = "aaa"
+	})
+	second := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.Name = "zzz"
+	})
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, first.LatestBuild.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, second.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	err := client.FavoriteWorkspace(ctx, second.ID)
+	require.NoError(t, err)
+	// Favoriting twice is a no-op.
+	err = client.FavoriteWorkspace(ctx, second.ID)
+	require.NoError(t, err)
+
+	workspace, err := client.Workspace(ctx, second.ID)
+	require.NoError(t, err)
+	require.True(t, workspace.Favorite)
+
+	// Favorites are listed first.
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 2)
+	require.Equal(t, second.ID, res.Workspaces[0].ID)
+	require.True(t, res.Workspaces[0].Favorite)
+	require.False(t, res.Workspaces[1].Favorite)
+
+	res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{FilterQuery: "favorite:true"})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, second.ID, res.Workspaces[0].ID)
+
+	err = client.UnfavoriteWorkspace(ctx, second.ID)
+	require.NoError(t, err)
+	workspace, err = client.Workspace(ctx, second.ID)
+	require.NoError(t, err)
+	require.False(t, workspace.Favorite)
+
+	// Users cannot favorite workspaces they cannot read.
+	err = member.FavoriteWorkspace(ctx, first.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestWorkspaceWatcher(t *testing.T) {
 	t.Parallel()
 	client, closeFunc := coderdtest.NewWithProvisionerCloser(t, &coderdtest.Options{
@@ -2829,6 +3043,194 @@ func TestWorkspaceWithEphemeralRichParameters(t *testing.T) {
 	require.ElementsMatch(t, expectedBuildParameters, workspaceBuildParameters)
 }
 
+func TestWorkspaceWithConditionalRichParameters(t *testing.T) {
+	t.Parallel()
+
+	const (
+		machineParameterName = "machine"
+		driverParameterName  = "gpu_driver"
+		driverParameterValue = "nvidia-535"
+	)
+
+	richParametersResponses := func(driverDisplayCondition string) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Response{
+				{
+					Type: &proto.Response_Plan{
+						Plan: &proto.PlanComplete{
+							Parameters: []*proto.RichParameter{
+								{
+									Name:         machineParameterName,
+									Type:         "string",
+									DefaultValue: "cpu",
+									Mutable:      true,
+									Order:        1,
+								},
+								{
+									Name:             driverParameterName,
+									Type:             "string",
+									Required:         true,
+									Mutable:          true,
+									Order:            2,
+									DisplayCondition: driverDisplayCondition,
+								},
+							},
+						},
+					},
+				},
+			},
+			ProvisionApply: echo.ApplyComplete,
+		}
+	}
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	t.Run("InvalidCondition", func(t *testing.T) {
+		t.Parallel()
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, richParametersResponses(`gpu == "yes"`))
+		version = coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, version.Job.Status)
+		require.Contains(t, version.Job.Error, "invalid parameter display conditions")
+	})
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, richParametersResponses(`machine == "gpu"`))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		templateRichParameters, err := client.TemplateVersionRichParameters(ctx, version.ID)
+		require.NoError(t, err)
+		require.Len(t, templateRichParameters, 2)
+		require.Empty(t, templateRichParameters[0].DisplayCondition)
+		require.Equal(t, `machine == "gpu"`, templateRichParameters[1].DisplayCondition)
+
+		// The driver is required, but hidden for the default machine, so it
+		// doesn't need a value.
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		workspaceBuild := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+		workspaceBuildParameters, err := client.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+			{Name: machineParameterName, Value: "cpu"},
+			{Name: driverParameterName, Value: ""},
+		}, workspaceBuildParameters)
+
+		// Once displayed, the driver is validated like any other parameter.
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "gpu",
+			RichParameterValues: []codersdk.WorkspaceBuildParameter{
+				{Name: machineParameterName, Value: "gpu"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, driverParameterName)
+
+		workspaceBuild, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			RichParameterValues: []codersdk.WorkspaceBuildParameter{
+				{Name: machineParameterName, Value: "gpu"},
+				{Name: driverParameterName, Value: driverParameterValue},
+			},
+		})
+		require.NoError(t, err)
+		workspaceBuild = coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspaceBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+		workspaceBuildParameters, err = client.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+			{Name: machineParameterName, Value: "gpu"},
+			{Name: driverParameterName, Value: driverParameterValue},
+		}, workspaceBuildParameters)
+	})
+}
+
+func TestWorkspaceParameterHistory(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					Parameters: []*proto.RichParameter{
+						{Name: "instance_size", Type: "string", DefaultValue: "small", Mutable: true},
+						{Name: "region", Type: "string", DefaultValue: "us", Mutable: true},
+					},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, member, owner.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Builds that don't change any value aren't recorded.
+	build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStop,
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, build.ID)
+
+	// An admin changes the instance size.
+	build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{
+			{Name: "instance_size", Value: "large"},
+		},
+	})
+	require.NoError(t, err)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+
+	history, err := member.WorkspaceParameterHistory(ctx, workspace.ID, "")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	// The latest change comes first.
+	require.Equal(t, build.ID, history[0].WorkspaceBuildID)
+	require.Equal(t, int32(3), history[0].BuildNumber)
+	require.Equal(t, "instance_size", history[0].Name)
+	require.NotNil(t, history[0].OldValue)
+	require.Equal(t, "small", *history[0].OldValue)
+	require.Equal(t, "large", history[0].NewValue)
+	require.Equal(t, owner.UserID, history[0].InitiatorID)
+	require.Equal(t, coderdtest.FirstUserParams.Username, history[0].InitiatorUsername)
+
+	// The first build sets the initial values.
+	for _, change := range history[1:] {
+		require.Equal(t, int32(1), change.BuildNumber)
+		require.Nil(t, change.OldValue)
+		require.Equal(t, memberUser.ID, change.InitiatorID)
+		require.Equal(t, memberUser.Username, change.InitiatorUsername)
+	}
+	require.Equal(t, "instance_size", history[1].Name)
+	require.Equal(t, "region", history[2].Name)
+
+	history, err = member.WorkspaceParameterHistory(ctx, workspace.ID, "region")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, "us", history[0].NewValue)
+}
+
 func TestWorkspaceDormant(t *testing.T) {
 	t.Parallel()
 
@@ -2928,3 +3330,47 @@ func TestWorkspaceDormant(t *testing.T) {
 		coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStop, database.WorkspaceTransitionStart)
 	})
 }
+
+func TestWorkspaceDrift(t *testing.T) {
+	t.Parallel()
+
+	client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	defer closer.Close()
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Response{{
+			Type: &proto.Response_Plan{
+				Plan: &proto.PlanComplete{
+					DriftedResources: []string{"docker_container.workspace"},
+				},
+			},
+		}},
+		ProvisionApply: echo.ApplyComplete,
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Workspaces that were never checked have no drift.
+	_, err := client.WorkspaceDrift(ctx, workspace.ID)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+	//nolint:gocritic // Drift checks are started by the system.
+	err = workspacedrift.CheckWorkspace(dbauthz.AsSystemRestricted(ctx), api.Database, api.Pubsub, workspace.ID)
+	require.NoError(t, err)
+
+	var drift codersdk.WorkspaceDrift
+	require.Eventually(t, func() bool {
+		drift, err = client.WorkspaceDrift(ctx, workspace.ID)
+		return assert.NoError(t, err) && drift.CheckedAt != nil
+	}, testutil.WaitLong, testutil.IntervalFast)
+	require.Equal(t, []string{"docker_container.workspace"}, drift.DriftedResources)
+	require.NotNil(t, drift.BuildID)
+	require.Equal(t, workspace.LatestBuild.ID, *drift.BuildID)
+}
@@ -0,0 +1,311 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/database/provisionerjobs"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace snapshots
+// @ID get-workspace-snapshots
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [get]
+func (api *API) workspaceSnapshots(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	snapshots, err := api.Database.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	jobIDs := make([]uuid.UUID, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		jobIDs = append(jobIDs, snapshot.JobID)
+	}
+	// nolint:gocritic // Getting workspace resources by job ID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobIDs(dbauthz.AsSystemRestricted(ctx), jobIDs)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := make([]codersdk.WorkspaceSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		resp = append(resp, convertWorkspaceSnapshot(snapshot.WorkspaceSnapshot, snapshot.JobID, snapshot.JobStatus, resources))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Create workspace snapshot
+// @ID create-workspace-snapshot
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceSnapshotRequest true "Create workspace snapshot request"
+// @Success 201 {object} codersdk.WorkspaceSnapshot
+// @Router /workspaces/{workspace}/snapshots [post]
+func (api *API) postWorkspaceSnapshot(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.CreateWorkspaceSnapshotRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		req.Name = "snapshot-" + dbtime.Now().Format("20060102-150405")
+	}
+
+	snapshots, err := api.Database.GetWorkspaceSnapshotsByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.WorkspaceSnapshot.Name == req.Name {
+			httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+				Message: fmt.Sprintf("A snapshot named %q already exists.", req.Name),
+				Validations: []codersdk.ValidationError{{
+					Field:  "name",
+					Detail: "This value is already in use and should be unique.",
+				}},
+			})
+			return
+		}
+	}
+
+	latestBuild, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The workspace has no builds to snapshot.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if latestBuild.Transition == database.WorkspaceTransitionDelete {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Deleted workspaces can't be snapshotted.",
+		})
+		return
+	}
+
+	// Taking a snapshot leaves the workspace as it is, so the build repeats
+	// the transition of the latest build.
+	snapshotID := uuid.New()
+	builder := wsbuilder.New(workspace, latestBuild.Transition).
+		Initiator(apiKey.UserID).
+		DeploymentValues(api.Options.DeploymentValues).
+		Snapshot(snapshotID, req.Name)
+	_, provisionerJob, err := builder.Build(
+		ctx,
+		api.Database,
+		func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+		audit.WorkspaceBuildBaggageFromRequest(r),
+	)
+	if !api.writeSnapshotBuildError(rw, r, err) {
+		return
+	}
+	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	snapshot, err := api.Database.GetWorkspaceSnapshotByID(ctx, snapshotID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace snapshot.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusCreated, convertWorkspaceSnapshot(snapshot, provisionerJob.ID, provisionerJob.JobStatus, nil))
+}
+
+// @Summary Restore workspace snapshot
+// @ID restore-workspace-snapshot
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param snapshot path string true "Snapshot ID" format(uuid)
+// @Success 201 {object} codersdk.WorkspaceBuild
+// @Router /workspaces/{workspace}/snapshots/{snapshot}/restore [post]
+func (api *API) postWorkspaceSnapshotRestore(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	workspace := httpmw.WorkspaceParam(r)
+
+	snapshotID, ok := httpmw.ParseUUIDParam(rw, r, "snapshot")
+	if !ok {
+		return
+	}
+
+	// Restored resources are recreated from the snapshot, so the workspace
+	// is started by the build.
+	builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+		Initiator(apiKey.UserID).
+		DeploymentValues(api.Options.DeploymentValues).
+		RestoreSnapshot(snapshotID)
+	workspaceBuild, provisionerJob, err := builder.Build(
+		ctx,
+		api.Database,
+		func(action rbac.Action, object rbac.Objecter) bool {
+			return api.Authorize(r, action, object)
+		},
+		audit.WorkspaceBuildBaggageFromRequest(r),
+	)
+	if !api.writeSnapshotBuildError(rw, r, err) {
+		return
+	}
+	err = provisionerjobs.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+
+	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
+		workspace.OwnerID,
+		workspaceBuild.InitiatorID,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error getting user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	ownerName, exists := usernameWithID(workspace.OwnerID, users)
+	if !exists {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  "owner not found for workspace",
+		})
+		return
+	}
+
+	apiBuild, err := api.convertWorkspaceBuild(
+		*workspaceBuild,
+		workspace,
+		database.GetProvisionerJobsByIDsWithQueuePositionRow{
+			ProvisionerJob: *provisionerJob,
+			QueuePosition:  0,
+		},
+		ownerName,
+		[]database.WorkspaceResource{},
+		[]database.WorkspaceResourceMetadatum{},
+		[]database.WorkspaceAgent{},
+		[]database.WorkspaceApp{},
+		[]database.WorkspaceAgentScript{},
+		[]database.WorkspaceAgentLogSource{},
+		[]database.WorkspaceAgentPort{},
+		[]database.WorkspaceAgentGPU{},
+		database.TemplateVersion{},
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, apiBuild)
+}
+
+// writeSnapshotBuildError writes the response for an error returned by a
+// snapshot or restore build. It returns true if there was no error.
+func (api *API) writeSnapshotBuildError(rw http.ResponseWriter, r *http.Request, err error) bool {
+	ctx := r.Context()
+	if err == nil {
+		return true
+	}
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusForbidden
+		}
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		})
+		return false
+	}
+	httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+		Message: "Error posting new build",
+		Detail:  err.Error(),
+	})
+	return false
+}
+
+// convertWorkspaceSnapshot converts a snapshot taken by the build with the
+// given job. Only the snapshot-able resources of the job are listed.
+func convertWorkspaceSnapshot(snapshot database.WorkspaceSnapshot, jobID uuid.UUID, status database.ProvisionerJobStatus, resources []database.WorkspaceResource) codersdk.WorkspaceSnapshot {
+	names := []string{}
+	for _, resource := range resources {
+		if resource.JobID != jobID || !resource.Snapshot {
+			continue
+		}
+		names = append(names, resource.Type+"."+resource.Name)
+	}
+	slices.Sort(names)
+	return codersdk.WorkspaceSnapshot{
+		ID:          snapshot.ID,
+		WorkspaceID: snapshot.WorkspaceID,
+		Name:        snapshot.Name,
+		BuildID:     snapshot.BuildID,
+		CreatedBy:   snapshot.CreatedBy,
+		CreatedAt:   snapshot.CreatedAt,
+		Status:      codersdk.ProvisionerJobStatus(status),
+		Resources:   names,
+	}
+}
@@ -0,0 +1,139 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceSnapshots(t *testing.T) {
+	t.Parallel()
+
+	snapshotResponses := func(snapshot bool) *echo.Responses {
+		return &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Response{{
+				Type: &proto.Response_Apply{
+					Apply: &proto.ApplyComplete{
+						Resources: []*proto.Resource{{
+							Name:     "home",
+							Type:     "docker_volume",
+							Snapshot: snapshot,
+						}, {
+							Name: "main",
+							Type: "docker_container",
+						}},
+					},
+				},
+			}},
+		}
+	}
+
+	t.Run("CreateAndRestore", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses(true))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-upgrade",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "before-upgrade", snapshot.Name)
+		require.Equal(t, workspace.ID, snapshot.WorkspaceID)
+		require.Equal(t, user.UserID, snapshot.CreatedBy)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.BuildID)
+
+		_, err = client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{
+			Name: "before-upgrade",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		snapshots, err := client.WorkspaceSnapshots(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		require.Equal(t, snapshot.ID, snapshots[0].ID)
+		require.Equal(t, codersdk.ProvisionerJobSucceeded, snapshots[0].Status)
+		require.Equal(t, []string{"docker_volume.home"}, snapshots[0].Resources)
+
+		build, err := client.RestoreWorkspaceSnapshot(ctx, workspace.ID, snapshot.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, build.Transition)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+	})
+
+	t.Run("DefaultName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses(true))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		snapshot, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{})
+		require.NoError(t, err)
+		require.Regexp(t, `^snapshot-\d{8}-\d{6}$`, snapshot.Name)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, snapshot.BuildID)
+	})
+
+	t.Run("NoSnapshotResources", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses(false))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateWorkspaceSnapshot(ctx, workspace.ID, codersdk.CreateWorkspaceSnapshotRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("RestoreNotFound", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, snapshotResponses(true))
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.RestoreWorkspaceSnapshot(ctx, workspace.ID, uuid.New())
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
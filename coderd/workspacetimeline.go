@@ -0,0 +1,100 @@
+package coderd
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get workspace timeline
+// @ID get-workspace-timeline
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param after_id query string false "After ID" format(uuid)
+// @Param limit query int false "Page limit"
+// @Param offset query int false "Page offset"
+// @Success 200 {array} codersdk.WorkspaceTimelineEvent
+// @Router /workspaces/{workspace}/timeline [get]
+func (api *API) workspaceTimeline(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	paginationParams, ok := parsePagination(rw, r)
+	if !ok {
+		return
+	}
+
+	// The events of the timeline come from several tables, so an after_id
+	// that doesn't exist returns an empty page rather than an error.
+	rows, err := api.Database.GetWorkspaceTimeline(ctx, database.GetWorkspaceTimelineParams{
+		WorkspaceID: workspace.ID,
+		AfterID:     paginationParams.AfterID,
+		OffsetOpt:   int32(paginationParams.Offset),
+		LimitOpt:    int32(paginationParams.Limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace timeline.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	events := make([]codersdk.WorkspaceTimelineEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, convertWorkspaceTimelineEvent(row))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, events)
+}
+
+func convertWorkspaceTimelineEvent(row database.GetWorkspaceTimelineRow) codersdk.WorkspaceTimelineEvent {
+	event := codersdk.WorkspaceTimelineEvent{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt,
+		Type:        codersdk.WorkspaceTimelineEventType(row.Type),
+		UserID:      nullUUIDPtr(row.UserID),
+		BuildID:     nullUUIDPtr(row.BuildID),
+		BuildNumber: row.BuildNumber,
+		BuildReason: codersdk.BuildReason(row.BuildReason),
+		Transition:  codersdk.WorkspaceTransition(row.Transition),
+		JobStatus:   codersdk.ProvisionerJobStatus(row.JobStatus),
+		AgentID:     nullUUIDPtr(row.AgentID),
+		AgentName:   row.AgentName,
+		AppID:       nullUUIDPtr(row.AppID),
+		AppSlug:     row.AppSlug,
+	}
+	switch event.Type {
+	case codersdk.WorkspaceTimelineEventTypeAppHealthChanged:
+		event.OldHealth = codersdk.WorkspaceAppHealth(row.OldValue)
+		event.NewHealth = codersdk.WorkspaceAppHealth(row.NewValue)
+	case codersdk.WorkspaceTimelineEventTypeTTLChanged:
+		event.OldTTLMillis = parseTimelineTTL(row.OldValue)
+		event.NewTTLMillis = parseTimelineTTL(row.NewValue)
+	}
+	return event
+}
+
+func nullUUIDPtr(id uuid.NullUUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	return &id.UUID
+}
+
+// parseTimelineTTL parses the TTL stored on ttl_changed events. See
+// workspaceEventTTLValue.
+func parseTimelineTTL(value string) *int64 {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &millis
+}
@@ -0,0 +1,76 @@
+package coderd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceTimeline(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	ttl := (90 * time.Minute).Milliseconds()
+	err := client.UpdateWorkspaceTTL(ctx, workspace.ID, codersdk.UpdateWorkspaceTTLRequest{
+		TTLMillis: &ttl,
+	})
+	require.NoError(t, err)
+
+	events, err := client.WorkspaceTimeline(ctx, workspace.ID, codersdk.Pagination{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	// Newest first.
+	require.Equal(t, codersdk.WorkspaceTimelineEventTypeTTLChanged, events[0].Type)
+	require.NotNil(t, events[0].UserID)
+	require.Equal(t, user.UserID, *events[0].UserID)
+	require.Equal(t, workspace.TTLMillis, events[0].OldTTLMillis)
+	require.NotNil(t, events[0].NewTTLMillis)
+	require.Equal(t, ttl, *events[0].NewTTLMillis)
+
+	require.Equal(t, codersdk.WorkspaceTimelineEventTypeBuildCompleted, events[1].Type)
+	require.NotNil(t, events[1].BuildID)
+	require.Equal(t, workspace.LatestBuild.ID, *events[1].BuildID)
+	require.Equal(t, codersdk.ProvisionerJobSucceeded, events[1].JobStatus)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, events[1].Transition)
+
+	require.Equal(t, codersdk.WorkspaceTimelineEventTypeBuildStarted, events[2].Type)
+	require.Equal(t, workspace.LatestBuild.ID, events[2].ID)
+	require.Equal(t, int32(1), events[2].BuildNumber)
+	require.Equal(t, codersdk.BuildReasonInitiator, events[2].BuildReason)
+
+	t.Run("Pagination", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		page, err := client.WorkspaceTimeline(ctx, workspace.ID, codersdk.Pagination{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, events[0].ID, page[0].ID)
+
+		page, err = client.WorkspaceTimeline(ctx, workspace.ID, codersdk.Pagination{AfterID: events[0].ID, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, events[1].ID, page[0].ID)
+
+		page, err = client.WorkspaceTimeline(ctx, workspace.ID, codersdk.Pagination{Offset: 2})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, events[2].ID, page[0].ID)
+	})
+}
@@ -14,11 +14,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
@@ -48,6 +50,10 @@ type Builder struct {
 	richParameterValues []codersdk.WorkspaceBuildParameter
 	initiator           uuid.UUID
 	reason              database.BuildReason
+	dryRun              bool
+	snapshotID          uuid.NullUUID
+	snapshotName        string
+	restoreSnapshotID   uuid.NullUUID
 
 	// used during build, makes function arguments less verbose
 	ctx   context.Context
@@ -158,6 +164,31 @@ func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Build
 	return b
 }
 
+// DryRun makes Build plan the build without applying it. Only a provisioner
+// job is inserted, and no workspace build is returned.
+func (b Builder) DryRun() Builder {
+	// nolint: revive
+	b.dryRun = true
+	return b
+}
+
+// Snapshot makes the build take a snapshot of the snapshot-able resources of
+// the workspace. The snapshot is recorded with the given ID and name.
+func (b Builder) Snapshot(id uuid.UUID, name string) Builder {
+	// nolint: revive
+	b.snapshotID = uuid.NullUUID{UUID: id, Valid: true}
+	b.snapshotName = name
+	return b
+}
+
+// RestoreSnapshot makes the build restore the snapshot-able resources of the
+// workspace from the given snapshot.
+func (b Builder) RestoreSnapshot(id uuid.UUID) Builder {
+	// nolint: revive
+	b.restoreSnapshotID = uuid.NullUUID{UUID: id, Valid: true}
+	return b
+}
+
 // SetLastWorkspaceBuildInTx prepopulates the Builder's cache with the last workspace build.  This allows us
 // to avoid a repeated database query when the Builder's caller also needs the workspace build, e.g. auto-start &
 // auto-stop.
@@ -258,6 +289,14 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	if err != nil {
 		return nil, nil, err
 	}
+	err = b.checkRunningWorkspaceLimits()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = b.checkSnapshot()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	template, err := b.getTemplate()
 	if err != nil {
@@ -280,10 +319,20 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 		b.reason = database.BuildReasonInitiator
 	}
 
+	if b.dryRun {
+		provisionerJob, err := b.insertDryRunJob(template, templateVersionJob)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, provisionerJob, nil
+	}
+
 	workspaceBuildID := uuid.New()
 	input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
-		WorkspaceBuildID: workspaceBuildID,
-		LogLevel:         b.logLevel,
+		WorkspaceBuildID:  workspaceBuildID,
+		LogLevel:          b.logLevel,
+		SnapshotID:        b.snapshotID,
+		RestoreSnapshotID: b.restoreSnapshotID,
 	})
 	if err != nil {
 		return nil, nil, BuildError{
@@ -311,6 +360,8 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 		FileID:         templateVersionJob.FileID,
 		Input:          input,
 		Tags:           tags,
+		// Builds run on the same provisioners as the template version.
+		TagRequirements: templateVersionJob.TagRequirements,
 		TraceMetadata: pqtype.NullRawMessage{
 			Valid:      true,
 			RawMessage: traceMetadataRaw,
@@ -332,6 +383,10 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	if err != nil {
 		return nil, nil, BuildError{http.StatusInternalServerError, "compute build state", err}
 	}
+	attempt, err := b.getAttempt()
+	if err != nil {
+		return nil, nil, BuildError{http.StatusInternalServerError, "compute build attempt", err}
+	}
 
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
@@ -349,6 +404,7 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			Reason:            b.reason,
 			Deadline:          time.Time{}, // set by provisioner upon completion
 			MaxDeadline:       time.Time{}, // set by provisioner upon completion
+			Attempt:           attempt,
 		})
 		if err != nil {
 			code := http.StatusInternalServerError
@@ -372,6 +428,23 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			return BuildError{http.StatusInternalServerError, "insert workspace build parameters: %w", err}
 		}
 
+		if b.snapshotID.Valid {
+			_, err = store.InsertWorkspaceSnapshot(b.ctx, database.InsertWorkspaceSnapshotParams{
+				ID:          b.snapshotID.UUID,
+				WorkspaceID: b.workspace.ID,
+				BuildID:     workspaceBuildID,
+				Name:        b.snapshotName,
+				CreatedBy:   b.initiator,
+				CreatedAt:   now,
+			})
+			if database.IsUniqueViolation(err, database.UniqueWorkspaceSnapshotsWorkspaceIDNameKey) {
+				return BuildError{http.StatusConflict, fmt.Sprintf("A snapshot named %q already exists.", b.snapshotName), err}
+			}
+			if err != nil {
+				return BuildError{http.StatusInternalServerError, "insert workspace snapshot", err}
+			}
+		}
+
 		workspaceBuild, err = store.GetWorkspaceBuildByID(b.ctx, workspaceBuildID)
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "get workspace build", err}
@@ -386,6 +459,77 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	return &workspaceBuild, &provisionerJob, nil
 }
 
+// insertDryRunJob inserts a provisioner job that plans the build against the
+// state of the last build.
+func (b *Builder) insertDryRunJob(template *database.Template, templateVersionJob *database.ProvisionerJob) (*database.ProvisionerJob, error) {
+	if b.state.orphan || b.state.explicit != nil {
+		return nil, BuildError{http.StatusBadRequest, "Dry-runs always use the state of the last build", xerrors.New("state cannot be set for dry-runs")}
+	}
+	templateVersionID, err := b.getTemplateVersionID()
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "compute template version ID", err}
+	}
+	var lastBuildID uuid.NullUUID
+	lastBuild, err := b.getLastBuild()
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, BuildError{http.StatusInternalServerError, "get last build", err}
+	}
+	if err == nil {
+		lastBuildID = uuid.NullUUID{UUID: lastBuild.ID, Valid: true}
+	}
+	names, values, err := b.getParameters()
+	if err != nil {
+		// getParameters already wraps errors in BuildError
+		return nil, err
+	}
+	parameters := make([]database.WorkspaceBuildParameter, 0, len(names))
+	for i, name := range names {
+		parameters = append(parameters, database.WorkspaceBuildParameter{
+			Name:  name,
+			Value: values[i],
+		})
+	}
+
+	input, err := json.Marshal(provisionerdserver.WorkspaceBuildDryRunJob{
+		WorkspaceID:         b.workspace.ID,
+		TemplateVersionID:   templateVersionID,
+		Transition:          b.trans,
+		LastBuildID:         lastBuildID,
+		RichParameterValues: parameters,
+	})
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "marshal dry-run job", err}
+	}
+	traceMetadataRaw, err := json.Marshal(tracing.MetadataFromContext(b.ctx))
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "marshal metadata", err}
+	}
+
+	now := dbtime.Now()
+	provisionerJob, err := b.store.InsertProvisionerJob(b.ctx, database.InsertProvisionerJobParams{
+		ID:              uuid.New(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		InitiatorID:     b.initiator,
+		OrganizationID:  template.OrganizationID,
+		Provisioner:     template.Provisioner,
+		Type:            database.ProvisionerJobTypeWorkspaceBuildDryRun,
+		StorageMethod:   templateVersionJob.StorageMethod,
+		FileID:          templateVersionJob.FileID,
+		Input:           input,
+		Tags:            provisionersdk.MutateTags(b.workspace.OwnerID, templateVersionJob.Tags),
+		TagRequirements: templateVersionJob.TagRequirements,
+		TraceMetadata: pqtype.NullRawMessage{
+			Valid:      true,
+			RawMessage: traceMetadataRaw,
+		},
+	})
+	if err != nil {
+		return nil, BuildError{http.StatusInternalServerError, "insert provisioner job", err}
+	}
+	return &provisionerJob, nil
+}
+
 func (b *Builder) getTemplate() (*database.Template, error) {
 	if b.template != nil {
 		return b.template, nil
@@ -480,6 +624,19 @@ func (b *Builder) getBuildNumber() (int32, error) {
 	return bld.BuildNumber + 1, nil
 }
 
+// getAttempt returns the attempt number of the build. Only retries of a failed
+// build count as another attempt, every other build starts over at 1.
+func (b *Builder) getAttempt() (int32, error) {
+	if b.reason != database.BuildReasonRetry {
+		return 1, nil
+	}
+	bld, err := b.getLastBuild()
+	if err != nil {
+		return 0, xerrors.Errorf("get last build to compute attempt: %w", err)
+	}
+	return bld.Attempt + 1, nil
+}
+
 func (b *Builder) getState() ([]byte, error) {
 	if b.state.orphan {
 		// Orphan means empty state.
@@ -516,23 +673,65 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 	resolver := codersdk.ParameterResolver{
 		Rich: db2sdk.WorkspaceBuildParameters(lastBuildParameters),
 	}
+	tvps := make([]codersdk.TemplateVersionParameter, 0, len(templateVersionParameters))
 	for _, templateVersionParameter := range templateVersionParameters {
 		tvp, err := db2sdk.TemplateVersionParameter(templateVersionParameter)
 		if err != nil {
 			return nil, nil, BuildError{http.StatusInternalServerError, "failed to convert template version parameter", err}
 		}
-		value, err := resolver.ValidateResolve(
-			tvp,
-			b.findNewBuildParameterValue(templateVersionParameter.Name),
-		)
-		if err != nil {
-			// At this point, we've queried all the data we need from the database,
-			// so the only errors are problems with the request (missing data, failed
-			// validation, immutable parameters, etc.)
-			return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", templateVersionParameter.Name), err}
+		tvps = append(tvps, tvp)
+	}
+
+	// A parameter is resolved once all parameters its display condition
+	// depends on are, so the condition can be evaluated against their final
+	// values. Hidden parameters aren't validated and always get their default
+	// value, regardless of what was requested.
+	resolved := make(map[string]string, len(tvps))
+	for len(resolved) < len(tvps) {
+		progressed := false
+		for _, tvp := range tvps {
+			if _, ok := resolved[tvp.Name]; ok {
+				continue
+			}
+			condition, err := codersdk.ParseParameterDisplayCondition(tvp.DisplayCondition)
+			if err != nil {
+				return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", tvp.Name), err}
+			}
+			ready := true
+			for _, dependency := range condition.Parameters() {
+				if _, ok := resolved[dependency]; !ok {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			progressed = true
+
+			if !condition.Met(resolved) {
+				resolved[tvp.Name] = tvp.DefaultValue
+				continue
+			}
+			value, err := resolver.ValidateResolve(
+				tvp,
+				b.findNewBuildParameterValue(tvp.Name),
+			)
+			if err != nil {
+				// At this point, we've queried all the data we need from the database,
+				// so the only errors are problems with the request (missing data, failed
+				// validation, immutable parameters, etc.)
+				return nil, nil, BuildError{http.StatusBadRequest, fmt.Sprintf("Unable to validate parameter %q", tvp.Name), err}
+			}
+			resolved[tvp.Name] = value
 		}
-		names = append(names, templateVersionParameter.Name)
-		values = append(values, value)
+		if !progressed {
+			return nil, nil, BuildError{http.StatusBadRequest, "Unable to resolve parameters", xerrors.New("parameter display conditions depend on each other or on unknown parameters")}
+		}
+	}
+	for _, tvp := range tvps {
+		names = append(names, tvp.Name)
+		values = append(values, resolved[tvp.Name])
 	}
 	return names, values, nil
 }
@@ -765,3 +964,111 @@ func (b *Builder) checkRunningBuild() error {
 	}
 	return nil
 }
+
+// checkRunningWorkspaceLimits enforces the maximum number of running workspaces
+// per user of the organization quota, and per template, when a workspace is
+// started. Other transitions never increase the number of running workspaces.
+func (b *Builder) checkRunningWorkspaceLimits() error {
+	if b.trans != database.WorkspaceTransitionStart || b.dryRun {
+		return nil
+	}
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+
+	// The limits count the workspaces of all users, which the initiator may
+	// not be able to read.
+	//nolint:gocritic // Enforcing quotas is a system function.
+	ctx := dbauthz.AsSystemRestricted(b.ctx)
+	var maxPerUser int32
+	quota, err := b.store.GetOrganizationQuota(ctx, b.workspace.OrganizationID)
+	if err == nil {
+		maxPerUser = quota.MaxRunningWorkspacesPerUser
+	} else if !xerrors.Is(err, sql.ErrNoRows) {
+		return BuildError{http.StatusInternalServerError, "failed to fetch organization quota", err}
+	}
+	if maxPerUser <= 0 && template.MaxRunningWorkspaces <= 0 {
+		return nil
+	}
+
+	counts, err := b.store.GetRunningWorkspaceCounts(ctx, database.GetRunningWorkspaceCountsParams{
+		OwnerID:        b.workspace.OwnerID,
+		OrganizationID: b.workspace.OrganizationID,
+		TemplateID:     template.ID,
+		WorkspaceID:    b.workspace.ID,
+	})
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to count running workspaces", err}
+	}
+	if maxPerUser > 0 && counts.OwnerCount >= int64(maxPerUser) {
+		msg := fmt.Sprintf("The owner of this workspace has %d running workspaces in this organization, the limit is %d. Stop a workspace before starting another.", counts.OwnerCount, maxPerUser)
+		return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+	}
+	if template.MaxRunningWorkspaces > 0 && counts.TemplateCount >= int64(template.MaxRunningWorkspaces) {
+		msg := fmt.Sprintf("Template %q has %d running workspaces, the limit is %d. Try again once another workspace of the template has stopped.", template.Name, counts.TemplateCount, template.MaxRunningWorkspaces)
+		return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+	}
+	return nil
+}
+
+// checkSnapshot verifies that builds taking a snapshot run against a
+// workspace with snapshot-able resources, and that builds restoring a
+// snapshot use a completed snapshot of the same workspace.
+func (b *Builder) checkSnapshot() error {
+	if !b.snapshotID.Valid && !b.restoreSnapshotID.Valid {
+		return nil
+	}
+	if b.dryRun {
+		msg := "Dry-runs can't take or restore snapshots."
+		return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+	}
+
+	if b.snapshotID.Valid {
+		lastBuild, err := b.getLastBuild()
+		if xerrors.Is(err, sql.ErrNoRows) {
+			msg := "The workspace has no builds to snapshot."
+			return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch prior build", err}
+		}
+		resources, err := b.store.GetWorkspaceResourcesByJobID(b.ctx, lastBuild.JobID)
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch workspace resources", err}
+		}
+		if !slices.ContainsFunc(resources, func(resource database.WorkspaceResource) bool {
+			return resource.Snapshot
+		}) {
+			msg := "The workspace has no snapshot-able resources."
+			return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+	}
+
+	if b.restoreSnapshotID.Valid {
+		snapshot, err := b.store.GetWorkspaceSnapshotByID(b.ctx, b.restoreSnapshotID.UUID)
+		if httpapi.Is404Error(err) {
+			return BuildError{http.StatusNotFound, "Snapshot not found.", err}
+		}
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch snapshot", err}
+		}
+		if snapshot.WorkspaceID != b.workspace.ID {
+			msg := "The snapshot belongs to a different workspace."
+			return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+		build, err := b.store.GetWorkspaceBuildByID(b.ctx, snapshot.BuildID)
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch snapshot build", err}
+		}
+		job, err := b.store.GetProvisionerJobByID(b.ctx, build.JobID)
+		if err != nil {
+			return BuildError{http.StatusInternalServerError, "failed to fetch snapshot job", err}
+		}
+		if codersdk.ProvisionerJobStatus(job.JobStatus) != codersdk.ProvisionerJobSucceeded {
+			msg := fmt.Sprintf("Snapshot %q has not completed successfully and can't be restored.", snapshot.Name)
+			return BuildError{http.StatusBadRequest, msg, xerrors.New(msg)}
+		}
+	}
+	return nil
+}
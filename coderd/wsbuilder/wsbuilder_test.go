@@ -81,6 +81,7 @@ func TestBuilder_NoOptions(t *testing.T) {
 			asrt.Equal(userID, bld.InitiatorID)
 			asrt.Equal(database.WorkspaceTransitionStart, bld.Transition)
 			asrt.Equal(database.BuildReasonInitiator, bld.Reason)
+			asrt.Equal(int32(1), bld.Attempt)
 			asrt.Equal(buildID, bld.ID)
 		}),
 		withBuild,
@@ -132,6 +133,40 @@ func TestBuilder_Initiator(t *testing.T) {
 	req.NoError(err)
 }
 
+func TestBuilder_Retry(t *testing.T) {
+	t.Parallel()
+	req := require.New(t)
+	asrt := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mDB := expectDB(t,
+		// Inputs
+		withTemplate,
+		withInactiveVersion(nil),
+		withLastBuildFound,
+		withRichParameters(nil),
+		withParameterSchemas(inactiveJobID, nil),
+
+		// Outputs
+		expectProvisionerJob(func(job database.InsertProvisionerJobParams) {}),
+		withInTx,
+		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
+			asrt.Equal(database.BuildReasonRetry, bld.Reason)
+			asrt.Equal(int32(2), bld.Attempt)
+		}),
+		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
+		}),
+		withBuild,
+	)
+
+	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Reason(database.BuildReasonRetry)
+	_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+	req.NoError(err)
+}
+
 func TestBuilder_Baggage(t *testing.T) {
 	t.Parallel()
 	req := require.New(t)
@@ -625,6 +660,94 @@ func TestWorkspaceBuildWithRichParameters(t *testing.T) {
 	})
 }
 
+func TestBuilder_RunningWorkspaceLimits(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                 string
+		maxPerUser           int32
+		maxRunningWorkspaces int32
+		counts               database.GetRunningWorkspaceCountsRow
+	}{
+		{
+			name:       "Organization",
+			maxPerUser: 2,
+			counts:     database.GetRunningWorkspaceCountsRow{OwnerCount: 2, TemplateCount: 2},
+		},
+		{
+			name:                 "Template",
+			maxRunningWorkspaces: 3,
+			counts:               database.GetRunningWorkspaceCountsRow{OwnerCount: 1, TemplateCount: 3},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := require.New(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			mDB := expectDB(t,
+				func(mTx *dbmock.MockStore) {
+					mTx.EXPECT().GetTemplateByID(gomock.Any(), templateID).
+						Times(1).
+						Return(database.Template{
+							ID:                   templateID,
+							OrganizationID:       orgID,
+							Provisioner:          database.ProvisionerTypeTerraform,
+							ActiveVersionID:      activeVersionID,
+							MaxRunningWorkspaces: tc.maxRunningWorkspaces,
+						}, nil)
+					mTx.EXPECT().GetTemplateVersionByID(gomock.Any(), inactiveVersionID).
+						Times(1).
+						Return(database.TemplateVersion{
+							ID:             inactiveVersionID,
+							TemplateID:     uuid.NullUUID{UUID: templateID, Valid: true},
+							OrganizationID: orgID,
+							JobID:          inactiveJobID,
+						}, nil)
+					mTx.EXPECT().GetProvisionerJobByID(gomock.Any(), inactiveJobID).
+						Times(1).
+						Return(database.ProvisionerJob{
+							ID:          inactiveJobID,
+							Type:        database.ProvisionerJobTypeTemplateVersionImport,
+							StartedAt:   sql.NullTime{Time: dbtime.Now(), Valid: true},
+							CompletedAt: sql.NullTime{Time: dbtime.Now(), Valid: true},
+						}, nil)
+				},
+				withLastBuildFound,
+				func(mTx *dbmock.MockStore) {
+					quotaCall := mTx.EXPECT().GetOrganizationQuota(gomock.Any(), orgID).Times(1)
+					if tc.maxPerUser > 0 {
+						quotaCall.Return(database.OrganizationQuota{
+							OrganizationID:              orgID,
+							MaxRunningWorkspacesPerUser: tc.maxPerUser,
+						}, nil)
+					} else {
+						quotaCall.Return(database.OrganizationQuota{}, sql.ErrNoRows)
+					}
+					mTx.EXPECT().GetRunningWorkspaceCounts(gomock.Any(), database.GetRunningWorkspaceCountsParams{
+						OwnerID:        userID,
+						OrganizationID: orgID,
+						TemplateID:     templateID,
+						WorkspaceID:    workspaceID,
+					}).
+						Times(1).
+						Return(tc.counts, nil)
+				},
+			)
+
+			ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID, OrganizationID: orgID}
+			uut := wsbuilder.New(ws, database.WorkspaceTransitionStart)
+			_, _, err := uut.Build(ctx, mDB, nil, audit.WorkspaceBuildBaggage{})
+			bldErr := wsbuilder.BuildError{}
+			req.ErrorAs(err, &bldErr)
+			req.Equal(http.StatusForbidden, bldErr.Status)
+		})
+	}
+}
+
 type txExpect func(mTx *dbmock.MockStore)
 
 func expectDB(t *testing.T, opts ...txExpect) *dbmock.MockStore {
@@ -636,9 +759,9 @@ func expectDB(t *testing.T, opts ...txExpect) *dbmock.MockStore {
 	// we expect to be run in a transaction; we use mTx to record the
 	// "in transaction" calls.
 	mDB.EXPECT().InTx(
-		gomock.Any(), gomock.Eq(&sql.TxOptions{Isolation: sql.LevelRepeatableRead}),
+		gomock.Any(), gomock.Eq(&database.TxOptions{Isolation: sql.LevelRepeatableRead}),
 	).
-		DoAndReturn(func(f func(database.Store) error, _ *sql.TxOptions) error {
+		DoAndReturn(func(f func(database.Store) error, _ *database.TxOptions) error {
 			err := f(mTx)
 			return err
 		})
@@ -659,12 +782,15 @@ func withTemplate(mTx *dbmock.MockStore) {
 			Provisioner:     database.ProvisionerTypeTerraform,
 			ActiveVersionID: activeVersionID,
 		}, nil)
+	mTx.EXPECT().GetOrganizationQuota(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(database.OrganizationQuota{}, sql.ErrNoRows)
 }
 
 // withInTx runs the given functions on the same db mock.
 func withInTx(mTx *dbmock.MockStore) {
 	mTx.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-		func(f func(store database.Store) error, _ *sql.TxOptions) error {
+		func(f func(store database.Store) error, _ *database.TxOptions) error {
 			return f(mTx)
 		},
 	)
@@ -763,6 +889,7 @@ func withLastBuildFound(mTx *dbmock.MockStore) {
 			JobID:             lastBuildJobID,
 			ProvisionerState:  []byte("last build state"),
 			Reason:            database.BuildReasonInitiator,
+			Attempt:           1,
 		}, nil)
 
 	mTx.EXPECT().GetProvisionerJobByID(gomock.Any(), lastBuildJobID).
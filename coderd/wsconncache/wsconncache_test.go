@@ -319,6 +319,10 @@ func (*client) PostAppHealth(_ context.Context, _ agentsdk.PostAppHealthsRequest
 	return nil
 }
 
+func (*client) PostListeningPorts(_ context.Context, _ agentsdk.PostListeningPortsRequest) error {
+	return nil
+}
+
 func (*client) PostMetadata(_ context.Context, _ agentsdk.PostMetadataRequest) error {
 	return nil
 }
@@ -331,6 +335,14 @@ func (*client) PatchLogs(_ context.Context, _ agentsdk.PatchLogs) error {
 	return nil
 }
 
+func (*client) PostLogSource(_ context.Context, req agentsdk.PostLogSource) (codersdk.WorkspaceAgentLogSource, error) {
+	return codersdk.WorkspaceAgentLogSource{ID: req.ID}, nil
+}
+
 func (*client) GetServiceBanner(_ context.Context) (codersdk.ServiceBannerConfig, error) {
 	return codersdk.ServiceBannerConfig{}, nil
 }
+
+func (*client) RotateToken(_ context.Context) (agentsdk.RotateTokenResponse, error) {
+	return agentsdk.RotateTokenResponse{}, nil
+}
@@ -2,6 +2,7 @@ package agentsdk
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	"cloud.google.com/go/compute/metadata"
 	"github.com/google/uuid"
 	"github.com/hashicorp/yamux"
+	"github.com/quic-go/quic-go"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"storj.io/drpc"
@@ -122,6 +124,10 @@ type Manifest struct {
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
 	Scripts                  []codersdk.WorkspaceAgentScript              `json:"scripts"`
+	// QUICAddress is the UDP address agents can dial to reach coderd over
+	// QUIC instead of a WebSocket. It is empty when the deployment does not
+	// accept QUIC connections.
+	QUICAddress string `json:"quic_address,omitempty"`
 }
 
 type LogSource struct {
@@ -332,6 +338,71 @@ func (c *Client) Listen(ctx context.Context) (drpc.Conn, error) {
 	return drpcsdk.MultiplexedConn(session), nil
 }
 
+// QUICHandshake is the first message an agent sends on a QUIC connection
+// to authenticate itself.
+type QUICHandshake struct {
+	SessionToken string `json:"session_token"`
+}
+
+// QUICHandshakeResponse is the reply to a QUICHandshake. Error is empty if
+// the agent was authenticated.
+type QUICHandshakeResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// ListenQUIC connects to coderd over QUIC at the address from the manifest.
+// The returned connection serves the same API as Listen.
+func (c *Client) ListenQUIC(ctx context.Context, address string) (drpc.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, xerrors.Errorf("parse address: %w", err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	if transport, ok := c.SDK.HTTPClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+	tlsConfig.NextProtos = []string{drpcsdk.QUICNextProto}
+
+	conn, err := quic.DialAddr(ctx, address, tlsConfig, drpcsdk.QUICConfig())
+	if err != nil {
+		return nil, xerrors.Errorf("dial: %w", err)
+	}
+	err = quicHandshake(ctx, conn, c.SDK.SessionToken())
+	if err != nil {
+		_ = conn.CloseWithError(0, "handshake failed")
+		return nil, err
+	}
+	return drpcsdk.MultiplexedQUICConn(conn), nil
+}
+
+func quicHandshake(ctx context.Context, conn quic.Connection, sessionToken string) error {
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return xerrors.Errorf("open handshake stream: %w", err)
+	}
+	defer stream.CancelRead(0)
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = stream.SetDeadline(deadline)
+	}
+	err = json.NewEncoder(stream).Encode(QUICHandshake{SessionToken: sessionToken})
+	if err != nil {
+		return xerrors.Errorf("write handshake: %w", err)
+	}
+	_ = stream.Close()
+	var res QUICHandshakeResponse
+	err = json.NewDecoder(stream).Decode(&res)
+	if err != nil {
+		return xerrors.Errorf("read handshake response: %w", err)
+	}
+	if res.Error != "" {
+		return xerrors.Errorf("handshake rejected: %s", res.Error)
+	}
+	return nil
+}
+
 type PostAppHealthsRequest struct {
 	// Healths is a map of the workspace app name and the health of the app.
 	Healths map[uuid.UUID]codersdk.WorkspaceAppHealth
@@ -351,6 +422,26 @@ func (c *Client) PostAppHealth(ctx context.Context, req PostAppHealthsRequest) e
 	return nil
 }
 
+// PostListeningPortsRequest is the set of TCP ports listening in the
+// workspace.
+type PostListeningPortsRequest struct {
+	Ports []codersdk.WorkspaceAgentListeningPort `json:"ports"`
+}
+
+// PostListeningPorts replaces the listening ports of the agent, which are
+// exposed as discovered apps.
+func (c *Client) PostListeningPorts(ctx context.Context, req PostListeningPortsRequest) error {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/listening-ports", req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // AuthenticateResponse is returned when an instance ID
 // has been exchanged for a session token.
 // @typescript-ignore AuthenticateResponse
@@ -586,6 +677,19 @@ type Stats struct {
 	// that are normal, non-tagged SSH sessions.
 	SessionCountSSH int64 `json:"session_count_ssh"`
 
+	// CPUUsedCores is the number of CPU cores in use by the workspace.
+	CPUUsedCores float64 `json:"cpu_used_cores"`
+	// CPUTotalCores is the number of CPU cores available to the workspace.
+	CPUTotalCores float64 `json:"cpu_total_cores"`
+	// MemoryUsedBytes is the memory in use by the workspace.
+	MemoryUsedBytes int64 `json:"memory_used_bytes"`
+	// MemoryTotalBytes is the memory available to the workspace.
+	MemoryTotalBytes int64 `json:"memory_total_bytes"`
+	// DiskUsedBytes is the disk space in use on the agent's home volume.
+	DiskUsedBytes int64 `json:"disk_used_bytes"`
+	// DiskTotalBytes is the size of the agent's home volume.
+	DiskTotalBytes int64 `json:"disk_total_bytes"`
+
 	// Metrics collected by the agent
 	Metrics []AgentMetric `json:"metrics"`
 }
@@ -656,10 +760,41 @@ func (c *Client) PostLifecycle(ctx context.Context, req PostLifecycleRequest) er
 	return nil
 }
 
+// RotateTokenResponse is returned when an agent rotates its session token.
+type RotateTokenResponse struct {
+	SessionToken string `json:"session_token"`
+	// PreviousTokenExpiresAt is when the token the agent used before the
+	// rotation stops being accepted.
+	PreviousTokenExpiresAt time.Time `json:"previous_token_expires_at" format:"date-time"`
+}
+
+// RotateToken replaces the agent's session token with a new one. The client
+// uses the new token for all subsequent requests.
+func (c *Client) RotateToken(ctx context.Context) (RotateTokenResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/rotate-token", nil)
+	if err != nil {
+		return RotateTokenResponse{}, xerrors.Errorf("rotate token request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return RotateTokenResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var resp RotateTokenResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return RotateTokenResponse{}, xerrors.Errorf("decode response: %w", err)
+	}
+	c.SetSessionToken(resp.SessionToken)
+	return resp, nil
+}
+
 type PostStartupRequest struct {
 	Version           string                    `json:"version"`
 	ExpandedDirectory string                    `json:"expanded_directory"`
 	Subsystems        []codersdk.AgentSubsystem `json:"subsystems"`
+	// GPUs are the GPUs detected in the workspace. They replace the GPUs
+	// previously reported by the agent.
+	GPUs []codersdk.WorkspaceAgentGPU `json:"gpus,omitempty"`
 }
 
 func (c *Client) PostStartup(ctx context.Context, req PostStartupRequest) error {
@@ -18,10 +18,19 @@ type APIKey struct {
 	ExpiresAt       time.Time   `json:"expires_at" validate:"required" format:"date-time"`
 	CreatedAt       time.Time   `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt       time.Time   `json:"updated_at" validate:"required" format:"date-time"`
-	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token"`
+	LoginType       LoginType   `json:"login_type" validate:"required" enums:"password,github,oidc,token,ldap"`
 	Scope           APIKeyScope `json:"scope" validate:"required" enums:"all,application_connect"`
 	TokenName       string      `json:"token_name" validate:"required"`
 	LifetimeSeconds int64       `json:"lifetime_seconds" validate:"required"`
+	// Scopes are the fine-grained scopes the key is limited to. If empty,
+	// the key is only limited by its scope.
+	Scopes []APITokenScope `json:"scopes" enums:"workspace:read,workspace:write,template:read,template:write,user:read"`
+	// AllowedWorkspaceIDs are the workspaces a key with fine-grained scopes
+	// is limited to. If empty, the key is not limited to specific workspaces.
+	AllowedWorkspaceIDs []uuid.UUID `json:"allowed_workspace_ids" format:"uuid"`
+	// IPAllowlist holds the CIDR ranges the key can be used from. If empty,
+	// the key is only limited by the IP allowlist of its user.
+	IPAllowlist []string `json:"ip_allowlist"`
 }
 
 // LoginType is the type of login used to create the API key.
@@ -33,6 +42,7 @@ const (
 	LoginTypeGithub   LoginType = "github"
 	LoginTypeOIDC     LoginType = "oidc"
 	LoginTypeToken    LoginType = "token"
+	LoginTypeLDAP     LoginType = "ldap"
 	// LoginTypeNone is used if no login method is available for this user.
 	// If this is set, the user has no method of logging in.
 	// API keys can still be created by an owner and used by the user.
@@ -50,10 +60,30 @@ const (
 	APIKeyScopeApplicationConnect APIKeyScope = "application_connect"
 )
 
+// APITokenScope is a fine-grained scope that limits what a token can do.
+// Any number of them can be combined on a token.
+type APITokenScope string
+
+const (
+	APITokenScopeWorkspaceRead  APITokenScope = "workspace:read"
+	APITokenScopeWorkspaceWrite APITokenScope = "workspace:write"
+	APITokenScopeTemplateRead   APITokenScope = "template:read"
+	APITokenScopeTemplateWrite  APITokenScope = "template:write"
+	APITokenScopeUserRead       APITokenScope = "user:read"
+)
+
 type CreateTokenRequest struct {
 	Lifetime  time.Duration `json:"lifetime"`
 	Scope     APIKeyScope   `json:"scope" enums:"all,application_connect"`
 	TokenName string        `json:"token_name"`
+	// Scopes limits the token to the given fine-grained scopes. They cannot
+	// be combined with the application_connect scope.
+	Scopes []APITokenScope `json:"scopes,omitempty" enums:"workspace:read,workspace:write,template:read,template:write,user:read"`
+	// AllowedWorkspaceIDs limits a token with fine-grained scopes to the
+	// given workspaces.
+	AllowedWorkspaceIDs []uuid.UUID `json:"allowed_workspace_ids,omitempty" format:"uuid"`
+	// IPAllowlist limits the token to the given CIDR ranges or IP addresses.
+	IPAllowlist []string `json:"ip_allowlist,omitempty"`
 }
 
 // GenerateAPIKeyResponse contains an API key for a user.
@@ -108,6 +138,15 @@ type APIKeyWithOwner struct {
 
 type TokenConfig struct {
 	MaxTokenLifetime time.Duration `json:"max_token_lifetime"`
+	// Scopes are the fine-grained scopes that can be set on a token.
+	Scopes []APITokenScopeDescription `json:"scopes"`
+}
+
+// APITokenScopeDescription describes a fine-grained scope that can be set on
+// a token.
+type APITokenScopeDescription struct {
+	Name        APITokenScope `json:"name" enums:"workspace:read,workspace:write,template:read,template:write,user:read"`
+	DisplayName string        `json:"display_name"`
 }
 
 // asRequestOption returns a function that can be used in (*Client).Request.
@@ -175,6 +214,34 @@ func (c *Client) DeleteAPIKey(ctx context.Context, userID string, id string) err
 	return nil
 }
 
+// UserSessions returns the active sessions of the user, newest first.
+func (c *Client) UserSessions(ctx context.Context, userID string) ([]APIKey, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/sessions", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var sessions []APIKey
+	return sessions, json.NewDecoder(res.Body).Decode(&sessions)
+}
+
+// DeleteUserSessions signs the user out of all of their sessions and deletes
+// all of their API keys, including tokens.
+func (c *Client) DeleteUserSessions(ctx context.Context, userID string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/sessions", userID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // GetTokenConfig returns deployment options related to token management
 func (c *Client) GetTokenConfig(ctx context.Context, userID string) (TokenConfig, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/keys/tokens/tokenconfig", userID), nil)
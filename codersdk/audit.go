@@ -14,19 +14,23 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
-	ResourceTypeHealthSettings  ResourceType = "health_settings"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeOrganization    ResourceType = "organization"
+	ResourceTypeTemplate           ResourceType = "template"
+	ResourceTypeTemplateVersion    ResourceType = "template_version"
+	ResourceTypeUser               ResourceType = "user"
+	ResourceTypeWorkspace          ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild     ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey          ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey             ResourceType = "api_key"
+	ResourceTypeGroup              ResourceType = "group"
+	ResourceTypeLicense            ResourceType = "license"
+	ResourceTypeConvertLogin       ResourceType = "convert_login"
+	ResourceTypeHealthSettings     ResourceType = "health_settings"
+	ResourceTypeWorkspaceProxy     ResourceType = "workspace_proxy"
+	ResourceTypeOrganization       ResourceType = "organization"
+	ResourceTypePasskey            ResourceType = "passkey"
+	ResourceTypeTwoFactorPolicy    ResourceType = "two_factor_policy"
+	ResourceTypeWebhook            ResourceType = "webhook"
+	ResourceTypeOrganizationMember ResourceType = "organization_member"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -59,6 +63,14 @@ func (r ResourceType) FriendlyString() string {
 		return "organization"
 	case ResourceTypeHealthSettings:
 		return "health_settings"
+	case ResourceTypePasskey:
+		return "passkey"
+	case ResourceTypeTwoFactorPolicy:
+		return "two-factor policy"
+	case ResourceTypeWebhook:
+		return "webhook"
+	case ResourceTypeOrganizationMember:
+		return "organization member"
 	default:
 		return "unknown"
 	}
@@ -67,14 +79,16 @@ func (r ResourceType) FriendlyString() string {
 type AuditAction string
 
 const (
-	AuditActionCreate   AuditAction = "create"
-	AuditActionWrite    AuditAction = "write"
-	AuditActionDelete   AuditAction = "delete"
-	AuditActionStart    AuditAction = "start"
-	AuditActionStop     AuditAction = "stop"
-	AuditActionLogin    AuditAction = "login"
-	AuditActionLogout   AuditAction = "logout"
-	AuditActionRegister AuditAction = "register"
+	AuditActionCreate      AuditAction = "create"
+	AuditActionWrite       AuditAction = "write"
+	AuditActionDelete      AuditAction = "delete"
+	AuditActionStart       AuditAction = "start"
+	AuditActionStop        AuditAction = "stop"
+	AuditActionLogin       AuditAction = "login"
+	AuditActionLogout      AuditAction = "logout"
+	AuditActionRegister    AuditAction = "register"
+	AuditActionPortForward AuditAction = "port_forward"
+	AuditActionIPRejected  AuditAction = "ip_rejected"
 )
 
 func (a AuditAction) Friendly() string {
@@ -95,6 +109,10 @@ func (a AuditAction) Friendly() string {
 		return "logged out"
 	case AuditActionRegister:
 		return "registered"
+	case AuditActionPortForward:
+		return "forwarded a port of"
+	case AuditActionIPRejected:
+		return "was rejected from a disallowed IP address with"
 	default:
 		return "unknown"
 	}
@@ -133,12 +151,20 @@ type AuditLog struct {
 
 type AuditLogsRequest struct {
 	SearchQuery string `json:"q,omitempty"`
+	// Cursor is the NextCursor returned by the previous page. It can't be
+	// combined with Offset, and is much faster than Offset for later pages.
+	Cursor string `json:"cursor,omitempty"`
 	Pagination
 }
 
 type AuditLogResponse struct {
 	AuditLogs []AuditLog `json:"audit_logs"`
-	Count     int64      `json:"count"`
+	// Count is the total number of matching audit logs. It is not computed
+	// when paginating with a cursor.
+	Count int64 `json:"count"`
+	// NextCursor can be passed as the cursor to fetch the next page. It is
+	// empty if there are no more audit logs.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type CreateTestAuditLogRequest struct {
@@ -159,6 +185,9 @@ func (c *Client) AuditLogs(ctx context.Context, req AuditLogsRequest) (AuditLogR
 			params = append(params, req.SearchQuery)
 		}
 		q.Set("q", strings.Join(params, " "))
+		if req.Cursor != "" {
+			q.Set("cursor", req.Cursor)
+		}
 		r.URL.RawQuery = q.Encode()
 	})
 	if err != nil {
@@ -0,0 +1,73 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConnectionType is the kind of connection a client opened to a workspace
+// agent.
+type ConnectionType string
+
+const (
+	ConnectionTypeSSH             ConnectionType = "ssh"
+	ConnectionTypeReconnectingPTY ConnectionType = "reconnecting_pty"
+	ConnectionTypePortForwarding  ConnectionType = "port_forwarding"
+)
+
+// ConnectionLog records a connection opened by a client to a workspace agent.
+type ConnectionLog struct {
+	ID                     uuid.UUID      `json:"id" format:"uuid"`
+	Time                   time.Time      `json:"time" format:"date-time"`
+	OrganizationID         uuid.UUID      `json:"organization_id" format:"uuid"`
+	WorkspaceOwnerID       uuid.UUID      `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerUsername string         `json:"workspace_owner_username"`
+	WorkspaceID            uuid.UUID      `json:"workspace_id" format:"uuid"`
+	WorkspaceName          string         `json:"workspace_name"`
+	AgentName              string         `json:"agent_name"`
+	Type                   ConnectionType `json:"type" enums:"ssh,reconnecting_pty,port_forwarding"`
+	// Port is the forwarded port of port_forwarding connections.
+	Port      *int32     `json:"port,omitempty"`
+	IP        netip.Addr `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+	UserID    uuid.UUID  `json:"user_id" format:"uuid"`
+	Username  string     `json:"username"`
+	// APIKeyID is the ID of the API key that authenticated the connection.
+	APIKeyID string `json:"api_key_id"`
+}
+
+type ConnectionLogsRequest struct {
+	SearchQuery string `json:"q,omitempty"`
+	Pagination
+}
+
+type ConnectionLogResponse struct {
+	ConnectionLogs []ConnectionLog `json:"connection_logs"`
+	// Count is the total number of matching connection logs.
+	Count int64 `json:"count"`
+}
+
+// ConnectionLogs retrieves connection logs, latest first.
+func (c *Client) ConnectionLogs(ctx context.Context, req ConnectionLogsRequest) (ConnectionLogResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/connectionlogs", nil, req.Pagination.asRequestOption(), func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("q", req.SearchQuery)
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return ConnectionLogResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ConnectionLogResponse{}, ReadBodyAsError(res)
+	}
+
+	var logRes ConnectionLogResponse
+	return logRes, json.NewDecoder(res.Body).Decode(&logRes)
+}
@@ -116,6 +116,9 @@ type Entitlements struct {
 	Trial            bool                    `json:"trial"`
 	RequireTelemetry bool                    `json:"require_telemetry"`
 	RefreshedAt      time.Time               `json:"refreshed_at" format:"date-time"`
+	// ReadOnlyUntil is set while every license has expired but enterprise
+	// features stay available read-only for the license grace period.
+	ReadOnlyUntil *time.Time `json:"read_only_until,omitempty" format:"date-time"`
 }
 
 func (c *Client) Entitlements(ctx context.Context) (Entitlements, error) {
@@ -144,14 +147,20 @@ type DeploymentValues struct {
 	JobHangDetectorInterval         clibase.Duration                     `json:"job_hang_detector_interval,omitempty"`
 	DERP                            DERP                                 `json:"derp,omitempty" typescript:",notnull"`
 	Prometheus                      PrometheusConfig                     `json:"prometheus,omitempty" typescript:",notnull"`
+	MetricsExport                   MetricsExportConfig                  `json:"metrics_export,omitempty" typescript:",notnull"`
 	Pprof                           PprofConfig                          `json:"pprof,omitempty" typescript:",notnull"`
 	ProxyTrustedHeaders             clibase.StringArray                  `json:"proxy_trusted_headers,omitempty" typescript:",notnull"`
 	ProxyTrustedOrigins             clibase.StringArray                  `json:"proxy_trusted_origins,omitempty" typescript:",notnull"`
 	CacheDir                        clibase.String                       `json:"cache_directory,omitempty" typescript:",notnull"`
 	InMemoryDatabase                clibase.Bool                         `json:"in_memory_database,omitempty" typescript:",notnull"`
 	PostgresURL                     clibase.String                       `json:"pg_connection_url,omitempty" typescript:",notnull"`
+	PostgresReplicaURLs             clibase.StringArray                  `json:"pg_replica_connection_urls,omitempty" typescript:",notnull"`
+	PostgresReplicaMaxLag           clibase.Duration                     `json:"pg_replica_max_lag,omitempty" typescript:",notnull"`
+	PubsubBackend                   clibase.String                       `json:"pubsub_backend,omitempty" typescript:",notnull"`
+	PubsubURL                       clibase.String                       `json:"pubsub_url,omitempty" typescript:",notnull"`
 	OAuth2                          OAuth2Config                         `json:"oauth2,omitempty" typescript:",notnull"`
 	OIDC                            OIDCConfig                           `json:"oidc,omitempty" typescript:",notnull"`
+	LDAP                            LDAPConfig                           `json:"ldap,omitempty" typescript:",notnull"`
 	Telemetry                       TelemetryConfig                      `json:"telemetry,omitempty" typescript:",notnull"`
 	TLS                             TLSConfig                            `json:"tls,omitempty" typescript:",notnull"`
 	Trace                           TraceConfig                          `json:"trace,omitempty" typescript:",notnull"`
@@ -162,11 +171,16 @@ type DeploymentValues struct {
 	MetricsCacheRefreshInterval     clibase.Duration                     `json:"metrics_cache_refresh_interval,omitempty" typescript:",notnull"`
 	AgentStatRefreshInterval        clibase.Duration                     `json:"agent_stat_refresh_interval,omitempty" typescript:",notnull"`
 	AgentFallbackTroubleshootingURL clibase.URL                          `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
+	AgentMetadataHistory            clibase.Int64                        `json:"agent_metadata_history,omitempty" typescript:",notnull"`
+	AgentQUICAddress                clibase.String                       `json:"agent_quic_address,omitempty" typescript:",notnull"`
 	BrowserOnly                     clibase.Bool                         `json:"browser_only,omitempty" typescript:",notnull"`
 	SCIMAPIKey                      clibase.String                       `json:"scim_api_key,omitempty" typescript:",notnull"`
 	ExternalTokenEncryptionKeys     clibase.StringArray                  `json:"external_token_encryption_keys,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKMSURL   clibase.String                       `json:"external_token_encryption_kms_url,omitempty" typescript:",notnull"`
+	ExternalTokenEncryptionKMSToken clibase.String                       `json:"external_token_encryption_kms_token,omitempty" typescript:",notnull"`
 	Provisioner                     ProvisionerConfig                    `json:"provisioner,omitempty" typescript:",notnull"`
 	RateLimit                       RateLimitConfig                      `json:"rate_limit,omitempty" typescript:",notnull"`
+	LoginThrottle                   LoginThrottleConfig                  `json:"login_throttle,omitempty" typescript:",notnull"`
 	Experiments                     clibase.StringArray                  `json:"experiments,omitempty" typescript:",notnull"`
 	UpdateCheck                     clibase.Bool                         `json:"update_check,omitempty" typescript:",notnull"`
 	MaxTokenLifetime                clibase.Duration                     `json:"max_token_lifetime,omitempty" typescript:",notnull"`
@@ -176,6 +190,11 @@ type DeploymentValues struct {
 	DisablePathApps                 clibase.Bool                         `json:"disable_path_apps,omitempty" typescript:",notnull"`
 	SessionDuration                 clibase.Duration                     `json:"max_session_expiry,omitempty" typescript:",notnull"`
 	DisableSessionExpiryRefresh     clibase.Bool                         `json:"disable_session_expiry_refresh,omitempty" typescript:",notnull"`
+	OwnerMaxTokenLifetime           clibase.Duration                     `json:"owner_max_token_lifetime,omitempty" typescript:",notnull"`
+	OwnerSessionDuration            clibase.Duration                     `json:"owner_max_session_expiry,omitempty" typescript:",notnull"`
+	OwnerDisableExpiryRefresh       clibase.Bool                         `json:"owner_disable_session_expiry_refresh,omitempty" typescript:",notnull"`
+	TruncateTokenLifetimes          clibase.Bool                         `json:"truncate_token_lifetimes,omitempty" typescript:",notnull"`
+	MaxConcurrentSessions           clibase.Int64                        `json:"max_concurrent_sessions,omitempty" typescript:",notnull"`
 	DisablePasswordAuth             clibase.Bool                         `json:"disable_password_auth,omitempty" typescript:",notnull"`
 	Support                         SupportConfig                        `json:"support,omitempty" typescript:",notnull"`
 	ExternalAuthConfigs             clibase.Struct[[]ExternalAuthConfig] `json:"external_auth,omitempty" typescript:",notnull"`
@@ -188,6 +207,13 @@ type DeploymentValues struct {
 	WebTerminalRenderer             clibase.String                       `json:"web_terminal_renderer,omitempty" typescript:",notnull"`
 	AllowWorkspaceRenames           clibase.Bool                         `json:"allow_workspace_renames,omitempty" typescript:",notnull"`
 	Healthcheck                     HealthcheckConfig                    `json:"healthcheck,omitempty" typescript:",notnull"`
+	AuditLogExport                  AuditLogExportConfig                 `json:"audit_log_export,omitempty" typescript:",notnull"`
+	ExamplesRegistryURL             clibase.URL                          `json:"examples_registry_url,omitempty" typescript:",notnull"`
+	SessionRecordingStorageURL      clibase.String                       `json:"session_recording_storage_url,omitempty" typescript:",notnull"`
+	LicenseGracePeriod              clibase.Duration                     `json:"license_grace_period,omitempty" typescript:",notnull"`
+	SuspendInactiveUsersAfter       clibase.Duration                     `json:"suspend_inactive_users_after,omitempty" typescript:",notnull"`
+	Notifications                   NotificationsConfig                  `json:"notifications,omitempty" typescript:",notnull"`
+	WebhooksAllowPrivateAddresses   clibase.Bool                         `json:"webhooks_allow_private_addresses,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -245,10 +271,12 @@ type DERPServerConfig struct {
 }
 
 type DERPConfig struct {
-	BlockDirect     clibase.Bool   `json:"block_direct" typescript:",notnull"`
-	ForceWebSockets clibase.Bool   `json:"force_websockets" typescript:",notnull"`
-	URL             clibase.String `json:"url" typescript:",notnull"`
-	Path            clibase.String `json:"path" typescript:",notnull"`
+	BlockDirect             clibase.Bool     `json:"block_direct" typescript:",notnull"`
+	ForceWebSockets         clibase.Bool     `json:"force_websockets" typescript:",notnull"`
+	URL                     clibase.String   `json:"url" typescript:",notnull"`
+	Path                    clibase.String   `json:"path" typescript:",notnull"`
+	HealthCheckInterval     clibase.Duration `json:"health_check_interval" typescript:",notnull"`
+	NodeKeyRotationInterval clibase.Duration `json:"node_key_rotation_interval" typescript:",notnull"`
 }
 
 type PrometheusConfig struct {
@@ -258,6 +286,15 @@ type PrometheusConfig struct {
 	CollectDBMetrics  clibase.Bool     `json:"collect_db_metrics" typescript:",notnull"`
 }
 
+// MetricsExportConfig configures pushing aggregated agent and workspace stats
+// to a metrics backend, for deployments where Prometheus cannot scrape coderd.
+type MetricsExportConfig struct {
+	StatsdAddress clibase.String   `json:"statsd_address" typescript:",notnull"`
+	OTLPEndpoint  clibase.URL      `json:"otlp_endpoint" typescript:",notnull"`
+	Interval      clibase.Duration `json:"interval" typescript:",notnull"`
+	BatchSize     clibase.Int64    `json:"batch_size" typescript:",notnull"`
+}
+
 type PprofConfig struct {
 	Enable  clibase.Bool     `json:"enable" typescript:",notnull"`
 	Address clibase.HostPort `json:"address" typescript:",notnull"`
@@ -282,26 +319,50 @@ type OIDCConfig struct {
 	ClientID     clibase.String `json:"client_id" typescript:",notnull"`
 	ClientSecret clibase.String `json:"client_secret" typescript:",notnull"`
 	// ClientKeyFile & ClientCertFile are used in place of ClientSecret for PKI auth.
-	ClientKeyFile       clibase.String                      `json:"client_key_file" typescript:",notnull"`
-	ClientCertFile      clibase.String                      `json:"client_cert_file" typescript:",notnull"`
-	EmailDomain         clibase.StringArray                 `json:"email_domain" typescript:",notnull"`
-	IssuerURL           clibase.String                      `json:"issuer_url" typescript:",notnull"`
-	Scopes              clibase.StringArray                 `json:"scopes" typescript:",notnull"`
-	IgnoreEmailVerified clibase.Bool                        `json:"ignore_email_verified" typescript:",notnull"`
-	UsernameField       clibase.String                      `json:"username_field" typescript:",notnull"`
-	EmailField          clibase.String                      `json:"email_field" typescript:",notnull"`
-	AuthURLParams       clibase.Struct[map[string]string]   `json:"auth_url_params" typescript:",notnull"`
-	IgnoreUserInfo      clibase.Bool                        `json:"ignore_user_info" typescript:",notnull"`
-	GroupAutoCreate     clibase.Bool                        `json:"group_auto_create" typescript:",notnull"`
-	GroupRegexFilter    clibase.Regexp                      `json:"group_regex_filter" typescript:",notnull"`
-	GroupAllowList      clibase.StringArray                 `json:"group_allow_list" typescript:",notnull"`
-	GroupField          clibase.String                      `json:"groups_field" typescript:",notnull"`
-	GroupMapping        clibase.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
-	UserRoleField       clibase.String                      `json:"user_role_field" typescript:",notnull"`
-	UserRoleMapping     clibase.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
-	UserRolesDefault    clibase.StringArray                 `json:"user_roles_default" typescript:",notnull"`
-	SignInText          clibase.String                      `json:"sign_in_text" typescript:",notnull"`
-	IconURL             clibase.URL                         `json:"icon_url" typescript:",notnull"`
+	ClientKeyFile                 clibase.String                      `json:"client_key_file" typescript:",notnull"`
+	ClientCertFile                clibase.String                      `json:"client_cert_file" typescript:",notnull"`
+	EmailDomain                   clibase.StringArray                 `json:"email_domain" typescript:",notnull"`
+	IssuerURL                     clibase.String                      `json:"issuer_url" typescript:",notnull"`
+	Scopes                        clibase.StringArray                 `json:"scopes" typescript:",notnull"`
+	IgnoreEmailVerified           clibase.Bool                        `json:"ignore_email_verified" typescript:",notnull"`
+	UsernameField                 clibase.String                      `json:"username_field" typescript:",notnull"`
+	EmailField                    clibase.String                      `json:"email_field" typescript:",notnull"`
+	AuthURLParams                 clibase.Struct[map[string]string]   `json:"auth_url_params" typescript:",notnull"`
+	IgnoreUserInfo                clibase.Bool                        `json:"ignore_user_info" typescript:",notnull"`
+	GroupAutoCreate               clibase.Bool                        `json:"group_auto_create" typescript:",notnull"`
+	GroupRegexFilter              clibase.Regexp                      `json:"group_regex_filter" typescript:",notnull"`
+	GroupAllowList                clibase.StringArray                 `json:"group_allow_list" typescript:",notnull"`
+	GroupField                    clibase.String                      `json:"groups_field" typescript:",notnull"`
+	GroupMapping                  clibase.Struct[map[string]string]   `json:"group_mapping" typescript:",notnull"`
+	UserRoleField                 clibase.String                      `json:"user_role_field" typescript:",notnull"`
+	UserRoleMapping               clibase.Struct[map[string][]string] `json:"user_role_mapping" typescript:",notnull"`
+	UserRolesDefault              clibase.StringArray                 `json:"user_roles_default" typescript:",notnull"`
+	GroupOrganizationRoleMapping  clibase.Struct[map[string][]string] `json:"group_organization_role_mapping" typescript:",notnull"`
+	GroupPrefixStrip              clibase.StringArray                 `json:"group_prefix_strip" typescript:",notnull"`
+	GroupSyncInterval             clibase.Duration                    `json:"group_sync_interval" typescript:",notnull"`
+	NestedGroupsMaxDepth          clibase.Int64                       `json:"nested_groups_max_depth" typescript:",notnull"`
+	NestedGroupsGraphTenantID     clibase.String                      `json:"nested_groups_graph_tenant_id" typescript:",notnull"`
+	NestedGroupsGraphClientID     clibase.String                      `json:"nested_groups_graph_client_id" typescript:",notnull"`
+	NestedGroupsGraphClientSecret clibase.String                      `json:"nested_groups_graph_client_secret" typescript:",notnull"`
+	SignInText                    clibase.String                      `json:"sign_in_text" typescript:",notnull"`
+	IconURL                       clibase.URL                         `json:"icon_url" typescript:",notnull"`
+}
+
+type LDAPConfig struct {
+	URL               clibase.String   `json:"url" typescript:",notnull"`
+	StartTLS          clibase.Bool     `json:"start_tls" typescript:",notnull"`
+	CAFile            clibase.String   `json:"ca_file" typescript:",notnull"`
+	BindDN            clibase.String   `json:"bind_dn" typescript:",notnull"`
+	BindPassword      clibase.String   `json:"bind_password" typescript:",notnull"`
+	UserSearchBase    clibase.String   `json:"user_search_base" typescript:",notnull"`
+	UserFilter        clibase.String   `json:"user_filter" typescript:",notnull"`
+	UsernameAttribute clibase.String   `json:"username_attribute" typescript:",notnull"`
+	EmailAttribute    clibase.String   `json:"email_attribute" typescript:",notnull"`
+	GroupsAttribute   clibase.String   `json:"groups_attribute" typescript:",notnull"`
+	GroupAutoCreate   clibase.Bool     `json:"group_auto_create" typescript:",notnull"`
+	AllowSignups      clibase.Bool     `json:"allow_signups" typescript:",notnull"`
+	PoolSize          clibase.Int64    `json:"pool_size" typescript:",notnull"`
+	SyncInterval      clibase.Duration `json:"sync_interval" typescript:",notnull"`
 }
 
 type TelemetryConfig struct {
@@ -364,17 +425,33 @@ type ExternalAuthConfig struct {
 }
 
 type ProvisionerConfig struct {
-	Daemons             clibase.Int64    `json:"daemons" typescript:",notnull"`
-	DaemonsEcho         clibase.Bool     `json:"daemons_echo" typescript:",notnull"`
-	DaemonPollInterval  clibase.Duration `json:"daemon_poll_interval" typescript:",notnull"`
-	DaemonPollJitter    clibase.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
-	ForceCancelInterval clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
-	DaemonPSK           clibase.String   `json:"daemon_psk" typescript:",notnull"`
+	Daemons                clibase.Int64    `json:"daemons" typescript:",notnull"`
+	DaemonsEcho            clibase.Bool     `json:"daemons_echo" typescript:",notnull"`
+	DaemonPollInterval     clibase.Duration `json:"daemon_poll_interval" typescript:",notnull"`
+	DaemonPollJitter       clibase.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
+	ForceCancelInterval    clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
+	DaemonPSK              clibase.String   `json:"daemon_psk" typescript:",notnull"`
+	JobLogArchiveAge       clibase.Duration `json:"job_log_archive_age" typescript:",notnull"`
+	DriftDetectionInterval clibase.Duration `json:"drift_detection_interval" typescript:",notnull"`
+	SharedCache            clibase.Bool     `json:"shared_cache" typescript:",notnull"`
+	SharedCacheS3Bucket    clibase.String   `json:"shared_cache_s3_bucket" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
-	DisableAll clibase.Bool  `json:"disable_all" typescript:",notnull"`
-	API        clibase.Int64 `json:"api" typescript:",notnull"`
+	DisableAll clibase.Bool        `json:"disable_all" typescript:",notnull"`
+	API        clibase.Int64       `json:"api" typescript:",notnull"`
+	Shared     clibase.Bool        `json:"shared" typescript:",notnull"`
+	Endpoints  clibase.StringArray `json:"endpoints" typescript:",notnull"`
+	Users      clibase.StringArray `json:"users" typescript:",notnull"`
+}
+
+// LoginThrottleConfig controls how failed password logins are slowed down
+// and when accounts are locked out.
+type LoginThrottleConfig struct {
+	BackoffThreshold clibase.Int64    `json:"backoff_threshold" typescript:",notnull"`
+	BackoffMax       clibase.Duration `json:"backoff_max" typescript:",notnull"`
+	LockoutThreshold clibase.Int64    `json:"lockout_threshold" typescript:",notnull"`
+	LockoutDuration  clibase.Duration `json:"lockout_duration" typescript:",notnull"`
 }
 
 type SwaggerConfig struct {
@@ -408,6 +485,42 @@ type HealthcheckConfig struct {
 	ThresholdDatabase clibase.Duration `json:"threshold_database" typescript:",notnull"`
 }
 
+// AuditLogExportConfig configures streaming audit logs to systems outside of
+// Coder. Each sink that is configured receives every audit log.
+type AuditLogExportConfig struct {
+	S3Bucket      clibase.String `json:"s3_bucket" typescript:",notnull"`
+	S3Region      clibase.String `json:"s3_region" typescript:",notnull"`
+	S3Prefix      clibase.String `json:"s3_prefix" typescript:",notnull"`
+	WebhookURL    clibase.URL    `json:"webhook_url" typescript:",notnull"`
+	SyslogAddress clibase.URL    `json:"syslog_address" typescript:",notnull"`
+	BufferSize    clibase.Int64  `json:"buffer_size" typescript:",notnull"`
+}
+
+// NotificationsConfig configures how notifications are sent to users.
+type NotificationsConfig struct {
+	MaxSendAttempts clibase.Int64              `json:"max_send_attempts" typescript:",notnull"`
+	RetryInterval   clibase.Duration           `json:"retry_interval" typescript:",notnull"`
+	Email           NotificationsEmailConfig   `json:"email" typescript:",notnull"`
+	Slack           NotificationsWebhookConfig `json:"slack" typescript:",notnull"`
+	Teams           NotificationsWebhookConfig `json:"teams" typescript:",notnull"`
+}
+
+// NotificationsEmailConfig configures sending notifications as emails through
+// an SMTP server.
+type NotificationsEmailConfig struct {
+	From      clibase.String `json:"from" typescript:",notnull"`
+	Smarthost clibase.String `json:"smarthost" typescript:",notnull"`
+	Username  clibase.String `json:"username" typescript:",notnull"`
+	Password  clibase.String `json:"password" typescript:",notnull"`
+}
+
+// NotificationsWebhookConfig configures posting notifications to a chat
+// channel through an incoming webhook.
+type NotificationsWebhookConfig struct {
+	WebhookURL clibase.String      `json:"webhook_url" typescript:",notnull"`
+	Templates  clibase.StringArray `json:"templates" typescript:",notnull"`
+}
+
 const (
 	annotationFormatDuration = "format_duration"
 	annotationEnterpriseKey  = "enterprise"
@@ -493,6 +606,11 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name:   "Prometheus",
 			YAML:   "prometheus",
 		}
+		deploymentGroupIntrospectionMetricsExport = clibase.Group{
+			Parent: &deploymentGroupIntrospection,
+			Name:   "Metrics Export",
+			YAML:   "metricsExport",
+		}
 		deploymentGroupIntrospectionTracing = clibase.Group{
 			Parent: &deploymentGroupIntrospection,
 			Name:   "Tracing",
@@ -522,6 +640,11 @@ func (c *DeploymentValues) Options() clibase.OptionSet {
 			Name: "OIDC",
 			YAML: "oidc",
 		}
+		deploymentGroupLDAP = clibase.Group{
+			Name:        "LDAP",
+			Description: "Configure login and user-provisioning with an LDAP directory, like OpenLDAP or Active Directory.",
+			YAML:        "ldap",
+		}
 		deploymentGroupTelemetry = clibase.Group{
 			Name: "Telemetry",
 			YAML: "telemetry",
@@ -539,6 +662,31 @@ when required by your organization's security policy.`,
 			Description: "Allow users to set quiet hours schedules each day for workspaces to avoid workspaces stopping during the day due to template max TTL.",
 			YAML:        "userQuietHoursSchedule",
 		}
+		deploymentGroupAuditLogExport = clibase.Group{
+			Name:        "Audit Log Export",
+			Description: "Stream audit logs to S3, a webhook, or syslog as they are recorded.",
+			YAML:        "auditLogExport",
+		}
+		deploymentGroupNotifications = clibase.Group{
+			Name:        "Notifications",
+			Description: "Configure how notifications are processed and delivered.",
+			YAML:        "notifications",
+		}
+		deploymentGroupNotificationsEmail = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Email",
+			YAML:   "email",
+		}
+		deploymentGroupNotificationsSlack = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Slack",
+			YAML:   "slack",
+		}
+		deploymentGroupNotificationsTeams = clibase.Group{
+			Parent: &deploymentGroupNotifications,
+			Name:   "Microsoft Teams",
+			YAML:   "teams",
+		}
 		deploymentGroupDangerous = clibase.Group{
 			Name: "⚠️ Dangerous",
 			YAML: "dangerous",
@@ -905,6 +1053,28 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupNetworkingDERP,
 			YAML:        "configPath",
 		},
+		{
+			Name:        "DERP Health Check Interval",
+			Description: "How often every DERP node is probed. Nodes that fail 3 consecutive probes are excluded from the DERP map given to clients and agents until they recover. Set to 0 to disable.",
+			Flag:        "derp-health-check-interval",
+			Env:         "CODER_DERP_HEALTH_CHECK_INTERVAL",
+			Default:     time.Minute.String(),
+			Value:       &c.DERP.Config.HealthCheckInterval,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "healthCheckInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true").Mark(annotationExternalProxies, "true"),
+		},
+		{
+			Name:        "Node Key Rotation Interval",
+			Description: "How long agents and clients may use the same WireGuard node key. Once it is older, the coordinator asks them to generate a new key and share it with their peers. Set to 0 to disable.",
+			Flag:        "node-key-rotation-interval",
+			Env:         "CODER_NODE_KEY_ROTATION_INTERVAL",
+			Default:     (7 * 24 * time.Hour).String(),
+			Value:       &c.DERP.Config.NodeKeyRotationInterval,
+			Group:       &deploymentGroupNetworkingDERP,
+			YAML:        "nodeKeyRotationInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		// TODO: support Git Auth settings.
 		// Prometheus settings
 		{
@@ -947,6 +1117,46 @@ when required by your organization's security policy.`,
 			YAML:        "collect_db_metrics",
 			Default:     "false",
 		},
+		// Metrics export settings
+		{
+			Name:        "Metrics Export Statsd Address",
+			Description: "Push aggregated agent and workspace stats to this statsd server over UDP, e.g. 127.0.0.1:8125. Labels are sent as DogStatsD tags.",
+			Flag:        "metrics-export-statsd-address",
+			Env:         "CODER_METRICS_EXPORT_STATSD_ADDRESS",
+			Value:       &c.MetricsExport.StatsdAddress,
+			Group:       &deploymentGroupIntrospectionMetricsExport,
+			YAML:        "statsdAddress",
+		},
+		{
+			Name:        "Metrics Export OTLP Endpoint",
+			Description: "Push aggregated agent and workspace stats to this OTLP/HTTP endpoint, e.g. http://otel-collector:4318/v1/metrics.",
+			Flag:        "metrics-export-otlp-endpoint",
+			Env:         "CODER_METRICS_EXPORT_OTLP_ENDPOINT",
+			Value:       &c.MetricsExport.OTLPEndpoint,
+			Group:       &deploymentGroupIntrospectionMetricsExport,
+			YAML:        "otlpEndpoint",
+		},
+		{
+			Name:        "Metrics Export Interval",
+			Description: "How often stats are pushed to the metrics export endpoints.",
+			Flag:        "metrics-export-interval",
+			Env:         "CODER_METRICS_EXPORT_INTERVAL",
+			Default:     time.Minute.String(),
+			Value:       &c.MetricsExport.Interval,
+			Group:       &deploymentGroupIntrospectionMetricsExport,
+			YAML:        "interval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Metrics Export Batch Size",
+			Description: "The most metrics pushed to a metrics export endpoint at once.",
+			Flag:        "metrics-export-batch-size",
+			Env:         "CODER_METRICS_EXPORT_BATCH_SIZE",
+			Default:     "500",
+			Value:       &c.MetricsExport.BatchSize,
+			Group:       &deploymentGroupIntrospectionMetricsExport,
+			YAML:        "batchSize",
+		},
 		// Pprof settings
 		{
 			Name:        "pprof Enable",
@@ -1246,6 +1456,74 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "userRoleDefault",
 		},
+		{
+			Name:        "OIDC Group Organization Role Mapping",
+			Description: "A map of group names from the OIDC groups claim, after the group mapping is applied, and the organization roles in Coder they should grant. Roles are synced on every login, so removing a user from a group in the identity provider revokes the roles it granted. Requires the OIDC group field to be set.",
+			Flag:        "oidc-group-organization-role-mapping",
+			Env:         "CODER_OIDC_GROUP_ORGANIZATION_ROLE_MAPPING",
+			Default:     "{}",
+			Value:       &c.OIDC.GroupOrganizationRoleMapping,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "groupOrganizationRoleMapping",
+		},
+		{
+			Name:        "OIDC Group Prefix Strip",
+			Description: "Prefixes to remove from group names after the group mapping and regex filter are applied, e.g. 'coder-' syncs the 'coder-frontend' group to the 'frontend' group in Coder. Only the first matching prefix is removed.",
+			Flag:        "oidc-group-prefix-strip",
+			Env:         "CODER_OIDC_GROUP_PREFIX_STRIP",
+			Default:     "",
+			Value:       &c.OIDC.GroupPrefixStrip,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "groupPrefixStrip",
+		},
+		{
+			Name:        "OIDC Group Sync Interval",
+			Description: "How often to sync the groups of every OIDC user from the nested groups directory, so changes in the identity provider apply without users logging in again. Requires a nested groups directory to be configured. Set to 0 to only sync groups at login.",
+			Flag:        "oidc-group-sync-interval",
+			Env:         "CODER_OIDC_GROUP_SYNC_INTERVAL",
+			Default:     "0s",
+			Value:       &c.OIDC.GroupSyncInterval,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "groupSyncInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "OIDC Nested Groups Max Depth",
+			Description: "How many levels of parent groups to resolve from the nested groups directory.",
+			Flag:        "oidc-nested-groups-max-depth",
+			Env:         "CODER_OIDC_NESTED_GROUPS_MAX_DEPTH",
+			Default:     "5",
+			Value:       &c.OIDC.NestedGroupsMaxDepth,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "nestedGroupsMaxDepth",
+		},
+		{
+			Name:        "OIDC Nested Groups Graph Tenant ID",
+			Description: "Microsoft Entra ID tenant to resolve nested groups in with the Microsoft Graph API.",
+			Flag:        "oidc-nested-groups-graph-tenant-id",
+			Env:         "CODER_OIDC_NESTED_GROUPS_GRAPH_TENANT_ID",
+			Value:       &c.OIDC.NestedGroupsGraphTenantID,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "nestedGroupsGraphTenantID",
+		},
+		{
+			Name:        "OIDC Nested Groups Graph Client ID",
+			Description: "Client ID of the application used to resolve nested groups with the Microsoft Graph API. The application needs the GroupMember.Read.All permission. Setting this enables nested groups.",
+			Flag:        "oidc-nested-groups-graph-client-id",
+			Env:         "CODER_OIDC_NESTED_GROUPS_GRAPH_CLIENT_ID",
+			Value:       &c.OIDC.NestedGroupsGraphClientID,
+			Group:       &deploymentGroupOIDC,
+			YAML:        "nestedGroupsGraphClientID",
+		},
+		{
+			Name:        "OIDC Nested Groups Graph Client Secret",
+			Description: "Client secret of the application used to resolve nested groups with the Microsoft Graph API.",
+			Flag:        "oidc-nested-groups-graph-client-secret",
+			Env:         "CODER_OIDC_NESTED_GROUPS_GRAPH_CLIENT_SECRET",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.OIDC.NestedGroupsGraphClientSecret,
+			Group:       &deploymentGroupOIDC,
+		},
 		{
 			Name:        "OpenID Connect sign in text",
 			Description: "The text to show on the OpenID Connect sign in button.",
@@ -1265,6 +1543,142 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupOIDC,
 			YAML:        "iconURL",
 		},
+		// LDAP settings
+		{
+			Name:        "LDAP URL",
+			Description: "URL of the LDAP server, like ldaps://ldap.example.com. Logging in with LDAP is enabled when set.",
+			Flag:        "ldap-url",
+			Env:         "CODER_LDAP_URL",
+			Value:       &c.LDAP.URL,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "url",
+		},
+		{
+			Name:        "LDAP StartTLS",
+			Description: "Upgrade ldap:// connections to TLS with StartTLS before binding.",
+			Flag:        "ldap-start-tls",
+			Env:         "CODER_LDAP_START_TLS",
+			Default:     "false",
+			Value:       &c.LDAP.StartTLS,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "startTLS",
+		},
+		{
+			Name:        "LDAP CA File",
+			Description: "Path to a PEM file of the CA certificates the LDAP server is verified with, instead of the system roots.",
+			Flag:        "ldap-ca-file",
+			Env:         "CODER_LDAP_CA_FILE",
+			Value:       &c.LDAP.CAFile,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "caFile",
+		},
+		{
+			Name:        "LDAP Bind DN",
+			Description: "DN of the service account that users are searched with. Searches are anonymous if empty.",
+			Flag:        "ldap-bind-dn",
+			Env:         "CODER_LDAP_BIND_DN",
+			Value:       &c.LDAP.BindDN,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "bindDN",
+		},
+		{
+			Name:        "LDAP Bind Password",
+			Description: "Password of the service account that users are searched with.",
+			Flag:        "ldap-bind-password",
+			Env:         "CODER_LDAP_BIND_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.LDAP.BindPassword,
+			Group:       &deploymentGroupLDAP,
+		},
+		{
+			Name:        "LDAP User Search Base",
+			Description: "DN that users are searched under, like ou=people,dc=example,dc=com.",
+			Flag:        "ldap-user-search-base",
+			Env:         "CODER_LDAP_USER_SEARCH_BASE",
+			Value:       &c.LDAP.UserSearchBase,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userSearchBase",
+		},
+		{
+			Name:        "LDAP User Filter",
+			Description: "Filter that finds the user logging in, with %s replaced by their username. Use (sAMAccountName=%s) for Active Directory.",
+			Flag:        "ldap-user-filter",
+			Env:         "CODER_LDAP_USER_FILTER",
+			Default:     "(uid=%s)",
+			Value:       &c.LDAP.UserFilter,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "userFilter",
+		},
+		{
+			Name:        "LDAP Username Attribute",
+			Description: "Attribute the username of new users is taken from.",
+			Flag:        "ldap-username-attribute",
+			Env:         "CODER_LDAP_USERNAME_ATTRIBUTE",
+			Default:     "uid",
+			Value:       &c.LDAP.UsernameAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "usernameAttribute",
+		},
+		{
+			Name:        "LDAP Email Attribute",
+			Description: "Attribute the email of users is taken from.",
+			Flag:        "ldap-email-attribute",
+			Env:         "CODER_LDAP_EMAIL_ATTRIBUTE",
+			Default:     "mail",
+			Value:       &c.LDAP.EmailAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "emailAttribute",
+		},
+		{
+			Name:        "LDAP Groups Attribute",
+			Description: "Attribute that lists the groups of a user, like memberOf. Users are synced to the Coder groups named after the first RDN of each group DN. Groups aren't synced if empty.",
+			Flag:        "ldap-groups-attribute",
+			Env:         "CODER_LDAP_GROUPS_ATTRIBUTE",
+			Value:       &c.LDAP.GroupsAttribute,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupsAttribute",
+		},
+		{
+			Name:        "LDAP Group Auto Create",
+			Description: "Automatically creates missing groups from the groups attribute of a user.",
+			Flag:        "ldap-group-auto-create",
+			Env:         "CODER_LDAP_GROUP_AUTO_CREATE",
+			Default:     "false",
+			Value:       &c.LDAP.GroupAutoCreate,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "groupAutoCreate",
+		},
+		{
+			Name:        "LDAP Allow Signups",
+			Description: "Whether new users can sign up with LDAP.",
+			Flag:        "ldap-allow-signups",
+			Env:         "CODER_LDAP_ALLOW_SIGNUPS",
+			Default:     "true",
+			Value:       &c.LDAP.AllowSignups,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "allowSignups",
+		},
+		{
+			Name:        "LDAP Pool Size",
+			Description: "How many idle connections to the LDAP server are kept open.",
+			Flag:        "ldap-pool-size",
+			Env:         "CODER_LDAP_POOL_SIZE",
+			Default:     "4",
+			Value:       &c.LDAP.PoolSize,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "poolSize",
+		},
+		{
+			Name:        "LDAP Sync Interval",
+			Description: "How often to suspend LDAP users whose directory account was deleted or disabled. Set to 0 to disable.",
+			Flag:        "ldap-sync-interval",
+			Env:         "CODER_LDAP_SYNC_INTERVAL",
+			Default:     "0s",
+			Value:       &c.LDAP.SyncInterval,
+			Group:       &deploymentGroupLDAP,
+			YAML:        "syncInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		// Telemetry settings
 		{
 			Name:        "Telemetry Enable",
@@ -1397,6 +1811,47 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			YAML:        "daemonPSK",
 		},
+		{
+			Name:        "Job Log Archive Age",
+			Description: "Compress the logs of provisioner jobs that completed longer ago than this to save database space. Archived logs are still returned by the API. Set to 0 to disable.",
+			Flag:        "provisioner-job-log-archive-age",
+			Env:         "CODER_PROVISIONER_JOB_LOG_ARCHIVE_AGE",
+			Default:     (30 * 24 * time.Hour).String(),
+			Value:       &c.Provisioner.JobLogArchiveAge,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "jobLogArchiveAge",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Drift Detection Interval",
+			Description: "How often to plan running workspaces against their Terraform state to detect resources that were changed outside of Coder. Each check queues a dry-run job for a provisioner daemon. Set to 0 to disable.",
+			Flag:        "provisioner-drift-detection-interval",
+			Env:         "CODER_PROVISIONER_DRIFT_DETECTION_INTERVAL",
+			Default:     "0s",
+			Value:       &c.Provisioner.DriftDetectionInterval,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "driftDetectionInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Provisioner Shared Cache",
+			Description: "Cache the Terraform providers and modules installed by builds in the cache directory, so the built-in provisioner daemons share them instead of downloading them for every build.",
+			Flag:        "provisioner-shared-cache",
+			Env:         "CODER_PROVISIONER_SHARED_CACHE",
+			Default:     "false",
+			Value:       &c.Provisioner.SharedCache,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "sharedCache",
+		},
+		{
+			Name:        "Provisioner Shared Cache S3 Bucket",
+			Description: "Store the shared provisioner cache in this S3 bucket as well, so it is shared with provisioner daemons on other hosts. The AWS region and credentials are read from the standard AWS environment variables and config files.",
+			Flag:        "provisioner-shared-cache-s3-bucket",
+			Env:         "CODER_PROVISIONER_SHARED_CACHE_S3_BUCKET",
+			Value:       &c.Provisioner.SharedCacheS3Bucket,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "sharedCacheS3Bucket",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -1420,6 +1875,33 @@ when required by your organization's security policy.`,
 			Hidden:      true,
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Shared Rate Limits",
+			Description: "Count requests in the database so rate limits apply across all replicas. Otherwise each replica enforces the rate limits separately, so the effective limit grows with the number of replicas. This adds a database write to every rate limited request.",
+			Flag:        "rate-limit-shared",
+			Env:         "CODER_RATE_LIMIT_SHARED",
+			Value:       &c.RateLimit.Shared,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "rateLimitShared",
+		},
+		{
+			Name:        "Endpoint Rate Limits",
+			Description: "Override the API rate limit for specific endpoints, in the form <path prefix>=<requests per minute>, e.g. /api/v2/workspaces=60. The longest matching prefix wins. Zero or negative values disable the rate limit for matching requests.",
+			Flag:        "rate-limit-endpoints",
+			Env:         "CODER_RATE_LIMIT_ENDPOINTS",
+			Value:       &c.RateLimit.Endpoints,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "rateLimitEndpoints",
+		},
+		{
+			Name:        "User Rate Limits",
+			Description: "Override the API rate limit for specific users, in the form <user ID>=<requests per minute>. User limits take precedence over endpoint limits. Zero or negative values disable the rate limit for the user.",
+			Flag:        "rate-limit-users",
+			Env:         "CODER_RATE_LIMIT_USERS",
+			Value:       &c.RateLimit.Users,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "rateLimitUsers",
+		},
 		// Logging settings
 		{
 			Name:          "Verbose",
@@ -1597,6 +2079,40 @@ when required by your organization's security policy.`,
 			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
 			Value:       &c.PostgresURL,
 		},
+		{
+			Name:        "Postgres Replica Connection URLs",
+			Description: "URLs of PostgreSQL read replicas of the database. Workspace, template and audit log listings are sent to a healthy replica, while writes and transactions always use the primary database.",
+			Flag:        "postgres-replica-urls",
+			Env:         "CODER_PG_REPLICA_CONNECTION_URLS",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.PostgresReplicaURLs,
+		},
+		{
+			Name:        "Postgres Replica Max Lag",
+			Description: "How far a PostgreSQL read replica may fall behind the primary database before it stops serving queries, until it catches up again.",
+			Flag:        "postgres-replica-max-lag",
+			Env:         "CODER_PG_REPLICA_MAX_LAG",
+			Default:     "10s",
+			Value:       &c.PostgresReplicaMaxLag,
+			YAML:        "pgReplicaMaxLag",
+		},
+		{
+			Name:        "Pubsub Backend",
+			Description: "The backend used to send notifications between replicas. Accepted values are \"postgres\", \"redis\", or \"nats\". Redis and NATS lift the payload size limit of PostgreSQL notifications and take notification traffic off the database in large deployments.",
+			Flag:        "pubsub-backend",
+			Env:         "CODER_PUBSUB_BACKEND",
+			Default:     "postgres",
+			Value:       &c.PubsubBackend,
+			YAML:        "pubsubBackend",
+		},
+		{
+			Name:        "Pubsub Connection URL",
+			Description: "URL of the Redis or NATS server used when --pubsub-backend is \"redis\" or \"nats\", e.g. redis://:password@localhost:6379/0 or nats://localhost:4222. Use rediss:// or tls:// for TLS.",
+			Flag:        "pubsub-url",
+			Env:         "CODER_PUBSUB_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.PubsubURL,
+		},
 		{
 			Name:        "Secure Auth Cookie",
 			Description: "Controls if the 'Secure' property is set on browser session cookies.",
@@ -1670,6 +2186,23 @@ when required by your organization's security policy.`,
 			Value:       &c.AgentFallbackTroubleshootingURL,
 			YAML:        "agentFallbackTroubleshootingURL",
 		},
+		{
+			Name:        "Agent Metadata History",
+			Description: "The number of recent values of each workspace agent metadatum to keep, so that dashboards can graph short-term trends. Older values are deleted as new ones are reported. Zero keeps only the latest value.",
+			Flag:        "agent-metadata-history",
+			Env:         "CODER_AGENT_METADATA_HISTORY",
+			Default:     "0",
+			Value:       &c.AgentMetadataHistory,
+			YAML:        "agentMetadataHistory",
+		},
+		{
+			Name:        "Agent QUIC Address",
+			Description: "The UDP address to accept workspace agent connections over QUIC on, e.g. \":3443\". Agents fall back to a WebSocket if they can't reach it. Requires TLS to be enabled. Leave empty to disable.",
+			Flag:        "agent-quic-address",
+			Env:         "CODER_AGENT_QUIC_ADDRESS",
+			Value:       &c.AgentQUICAddress,
+			YAML:        "agentQUICAddress",
+		},
 		{
 			Name:        "Browser Only",
 			Description: "Whether Coder only allows connections to workspaces via the browser.",
@@ -1680,6 +2213,15 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupNetworking,
 			YAML:        "browserOnly",
 		},
+		{
+			Name:        "Webhooks Allow Private Addresses",
+			Description: "Allow webhooks to deliver events to loopback, private and link-local addresses. By default these are refused so that webhooks can't be used to reach internal services.",
+			Flag:        "webhooks-allow-private-addresses",
+			Env:         "CODER_WEBHOOKS_ALLOW_PRIVATE_ADDRESSES",
+			Value:       &c.WebhooksAllowPrivateAddresses,
+			Group:       &deploymentGroupNetworking,
+			YAML:        "webhooksAllowPrivateAddresses",
+		},
 		{
 			Name:        "SCIM API Key",
 			Description: "Enables SCIM and sets the authentication header for the built-in SCIM server. New users are automatically created with OIDC authentication.",
@@ -1696,6 +2238,82 @@ when required by your organization's security policy.`,
 			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
 			Value:       &c.ExternalTokenEncryptionKeys,
 		},
+		{
+			Name:        "External Token Encryption KMS URL",
+			Description: "Unwrap the external token encryption keys with this key management service on startup, so the keys are only stored wrapped (envelope encryption). Only the HashiCorp Vault transit secrets engine is supported, in the form https://vault.example.com:8200/{mount}/{key}. When set, each of the external token encryption keys must be a ciphertext returned by the KMS.",
+			Flag:        "external-token-encryption-kms-url",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalTokenEncryptionKMSURL,
+		},
+		{
+			Name:        "External Token Encryption KMS Token",
+			Description: "The token used to authenticate with the external token encryption KMS.",
+			Flag:        "external-token-encryption-kms-token",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_TOKEN",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.ExternalTokenEncryptionKMSToken,
+		},
+		{
+			Name:        "Audit Log Export S3 Bucket",
+			Description: "Upload audit logs to this S3 bucket as newline-delimited JSON. Credentials are loaded from the standard AWS environment variables, shared config, or instance role.",
+			Flag:        "audit-log-export-s3-bucket",
+			Env:         "CODER_AUDIT_LOG_EXPORT_S3_BUCKET",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogExport.S3Bucket,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "s3Bucket",
+		},
+		{
+			Name:        "Audit Log Export S3 Region",
+			Description: "The region of the audit log S3 bucket. Defaults to the region in the AWS config.",
+			Flag:        "audit-log-export-s3-region",
+			Env:         "CODER_AUDIT_LOG_EXPORT_S3_REGION",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogExport.S3Region,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "s3Region",
+		},
+		{
+			Name:        "Audit Log Export S3 Prefix",
+			Description: "A prefix for the keys of audit log objects uploaded to S3.",
+			Flag:        "audit-log-export-s3-prefix",
+			Env:         "CODER_AUDIT_LOG_EXPORT_S3_PREFIX",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogExport.S3Prefix,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "s3Prefix",
+		},
+		{
+			Name:        "Audit Log Export Webhook URL",
+			Description: "POST batches of audit logs to this URL as a JSON array.",
+			Flag:        "audit-log-export-webhook-url",
+			Env:         "CODER_AUDIT_LOG_EXPORT_WEBHOOK_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditLogExport.WebhookURL,
+			Group:       &deploymentGroupAuditLogExport,
+		},
+		{
+			Name:        "Audit Log Export Syslog Address",
+			Description: "Send audit logs to this syslog server as RFC 5424 messages. The scheme must be udp, tcp, or tls, e.g. tcp://syslog.example.com:514.",
+			Flag:        "audit-log-export-syslog-address",
+			Env:         "CODER_AUDIT_LOG_EXPORT_SYSLOG_ADDRESS",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditLogExport.SyslogAddress,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "syslogAddress",
+		},
+		{
+			Name:        "Audit Log Export Buffer Size",
+			Description: "How many audit logs can wait to be exported to each sink. Audit logs are dropped when the buffer is full.",
+			Flag:        "audit-log-export-buffer-size",
+			Env:         "CODER_AUDIT_LOG_EXPORT_BUFFER_SIZE",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Default:     "1024",
+			Value:       &c.AuditLogExport.BufferSize,
+			Group:       &deploymentGroupAuditLogExport,
+			YAML:        "bufferSize",
+		},
 		{
 			Name:        "Disable Path Apps",
 			Description: "Disable workspace apps that are not served from subdomains. Path-based apps can make requests to the Coder API and pose a security risk when the workspace serves malicious JavaScript. This is recommended for security purposes if a --wildcard-access-url is configured.",
@@ -1737,6 +2355,98 @@ when required by your organization's security policy.`,
 			Group: &deploymentGroupNetworkingHTTP,
 			YAML:  "disableSessionExpiryRefresh",
 		},
+		{
+			Name:        "Owner Max Token Lifetime",
+			Description: "The maximum lifetime duration users with the owner role can specify when creating an API token. If unset, --max-token-lifetime applies to owners as well.",
+			Flag:        "owner-max-token-lifetime",
+			Env:         "CODER_OWNER_MAX_TOKEN_LIFETIME",
+			Value:       &c.OwnerMaxTokenLifetime,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "ownerMaxTokenLifetime",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Owner Session Duration",
+			Description: "The token expiry duration for browser sessions of users with the owner role. If unset, --session-duration applies to owners as well.",
+			Flag:        "owner-session-duration",
+			Env:         "CODER_OWNER_SESSION_DURATION",
+			Value:       &c.OwnerSessionDuration,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "ownerSessionDuration",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Owner Disable Session Expiry Refresh",
+			Description: "Disable automatic session expiry bumping due to activity for users with the owner role only, so their sessions become invalid after the session expiry duration has been reached.",
+			Flag:        "owner-disable-session-expiry-refresh",
+			Env:         "CODER_OWNER_DISABLE_SESSION_EXPIRY_REFRESH",
+
+			Value: &c.OwnerDisableExpiryRefresh,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "ownerDisableSessionExpiryRefresh",
+		},
+		{
+			Name:        "Truncate Token Lifetimes",
+			Description: "Shorten existing API keys to the lifetime allowed for the roles of their user the next time they are used. Otherwise, keys created before a lifetime was lowered keep their original expiry.",
+			Flag:        "truncate-token-lifetimes",
+			Env:         "CODER_TRUNCATE_TOKEN_LIFETIMES",
+
+			Value: &c.TruncateTokenLifetimes,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "truncateTokenLifetimes",
+		},
+		{
+			Name:        "Max Concurrent Sessions",
+			Description: "The maximum number of sessions a user can be signed in with at once. Signing in beyond the limit signs the user out of their oldest session. Zero means no limit.",
+			Flag:        "max-concurrent-sessions",
+			Env:         "CODER_MAX_CONCURRENT_SESSIONS",
+
+			Value: &c.MaxConcurrentSessions,
+			Group: &deploymentGroupNetworkingHTTP,
+			YAML:  "maxConcurrentSessions",
+		},
+		{
+			Name:        "Login Backoff Threshold",
+			Description: "The number of failed password logins for a user from one IP address before further attempts from that address are delayed. The delay starts at one second and doubles with every failure. Zero disables the backoff.",
+			Flag:        "login-backoff-threshold",
+			Env:         "CODER_LOGIN_BACKOFF_THRESHOLD",
+			Default:     "3",
+			Value:       &c.LoginThrottle.BackoffThreshold,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginBackoffThreshold",
+		},
+		{
+			Name:        "Login Backoff Max",
+			Description: "The longest delay between failed password logins for a user from one IP address.",
+			Flag:        "login-backoff-max",
+			Env:         "CODER_LOGIN_BACKOFF_MAX",
+			Default:     (5 * time.Minute).String(),
+			Value:       &c.LoginThrottle.BackoffMax,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginBackoffMax",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Login Lockout Threshold",
+			Description: "The number of failed password logins for a user, from any IP address, before the account is locked. Locked accounts can't sign in with a password until the lockout expires or an admin unlocks them. Zero disables lockouts.",
+			Flag:        "login-lockout-threshold",
+			Env:         "CODER_LOGIN_LOCKOUT_THRESHOLD",
+			Default:     "10",
+			Value:       &c.LoginThrottle.LockoutThreshold,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginLockoutThreshold",
+		},
+		{
+			Name:        "Login Lockout Duration",
+			Description: "How long accounts stay locked. Failed password logins older than this don't count towards a lockout.",
+			Flag:        "login-lockout-duration",
+			Env:         "CODER_LOGIN_LOCKOUT_DURATION",
+			Default:     (30 * time.Minute).String(),
+			Value:       &c.LoginThrottle.LockoutDuration,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "loginLockoutDuration",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
 		{
 			Name:        "Disable Password Authentication",
 			Description: "Disable password authentication. This is recommended for security purposes in production deployments that rely on an identity provider. Any user with the owner role will be able to sign in with their password regardless of this setting to avoid potential lock out. If you are locked out of your account, you can use the `coder server create-admin` command to create a new admin user directly in the database.",
@@ -1868,6 +2578,137 @@ Write out the current server config as YAML to stdout.`,
 			Value:       &c.AllowWorkspaceRenames,
 			YAML:        "allowWorkspaceRenames",
 		},
+		{
+			Name:        "Examples Registry URL",
+			Description: "URL of an internal mirror of the starter templates, e.g. for air-gapped deployments. When set, starter templates are listed and downloaded from the mirror instead of the examples embedded in Coder. The mirror must serve an index.json listing the examples with the SHA256 checksum of their archive, and an <id>.tar archive for every example.",
+			Flag:        "examples-registry-url",
+			Env:         "CODER_EXAMPLES_REGISTRY_URL",
+			Value:       &c.ExamplesRegistryURL,
+			YAML:        "examplesRegistryURL",
+		},
+		{
+			Name:        "Session Recording Storage URL",
+			Description: "URL of the object storage session recordings of web terminals are written to, either file:///path/to/dir or s3://bucket/prefix?region=us-east-1. S3 credentials are read from the default AWS credential chain. Web terminals of templates with session recording enabled are refused when this is not set.",
+			Flag:        "session-recording-storage-url",
+			Env:         "CODER_SESSION_RECORDING_STORAGE_URL",
+			Value:       &c.SessionRecordingStorageURL,
+			YAML:        "sessionRecordingStorageURL",
+		},
+		{
+			Name:        "License Grace Period",
+			Description: "How long enterprise features stay available read-only once every license has expired, to give time to renew. Changes to enterprise features are refused and a warning is shown until the period ends, when the features are disabled. Set to 0 to disable the features as soon as the licenses expire.",
+			Flag:        "license-grace-period",
+			Env:         "CODER_LICENSE_GRACE_PERIOD",
+			Default:     "0s",
+			Value:       &c.LicenseGracePeriod,
+			YAML:        "licenseGracePeriod",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Suspend Inactive Users After",
+			Description: "Suspend users that have not used Coder for this long, e.g. 2160h for 90 days. Owners are never suspended. Suspended users can't log in until an admin activates them again. Set to 0 to disable.",
+			Flag:        "suspend-inactive-users-after",
+			Env:         "CODER_SUSPEND_INACTIVE_USERS_AFTER",
+			Default:     "0s",
+			Value:       &c.SuspendInactiveUsersAfter,
+			YAML:        "suspendInactiveUsersAfter",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Max Send Attempts",
+			Description: "The maximum number of times a notification is sent before it is marked as failed.",
+			Flag:        "notifications-max-send-attempts",
+			Env:         "CODER_NOTIFICATIONS_MAX_SEND_ATTEMPTS",
+			Default:     "5",
+			Value:       &c.Notifications.MaxSendAttempts,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "maxSendAttempts",
+		},
+		{
+			Name:        "Notifications: Retry Interval",
+			Description: "The delay before a notification that could not be sent is retried. It doubles with every subsequent attempt.",
+			Flag:        "notifications-retry-interval",
+			Env:         "CODER_NOTIFICATIONS_RETRY_INTERVAL",
+			Default:     (5 * time.Minute).String(),
+			Value:       &c.Notifications.RetryInterval,
+			Group:       &deploymentGroupNotifications,
+			YAML:        "retryInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationFormatDuration, "true"),
+		},
+		{
+			Name:        "Notifications: Email: From Address",
+			Description: "The sender's address of notification emails.",
+			Flag:        "notifications-email-from",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_FROM",
+			Value:       &c.Notifications.Email.From,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "from",
+		},
+		{
+			Name:        "Notifications: Email: Smarthost",
+			Description: "The SMTP server notification emails are sent through, in host:port form. Notifications are only sent when this is set.",
+			Flag:        "notifications-email-smarthost",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_SMARTHOST",
+			Value:       &c.Notifications.Email.Smarthost,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "smarthost",
+		},
+		{
+			Name:        "Notifications: Email: Auth Username",
+			Description: "The username to authenticate to the SMTP server with.",
+			Flag:        "notifications-email-auth-username",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_USERNAME",
+			Value:       &c.Notifications.Email.Username,
+			Group:       &deploymentGroupNotificationsEmail,
+			YAML:        "authUsername",
+		},
+		{
+			Name:        "Notifications: Email: Auth Password",
+			Description: "The password to authenticate to the SMTP server with.",
+			Flag:        "notifications-email-auth-password",
+			Env:         "CODER_NOTIFICATIONS_EMAIL_AUTH_PASSWORD",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Email.Password,
+			Group:       &deploymentGroupNotificationsEmail,
+		},
+		{
+			Name:        "Notifications: Slack: Webhook URL",
+			Description: "The Slack incoming webhook URL notifications are posted to. Notifications are only posted to Slack when this is set.",
+			Flag:        "notifications-slack-webhook-url",
+			Env:         "CODER_NOTIFICATIONS_SLACK_WEBHOOK_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Slack.WebhookURL,
+			Group:       &deploymentGroupNotificationsSlack,
+		},
+		{
+			Name:        "Notifications: Slack: Templates",
+			Description: "The notification templates that are posted to Slack.",
+			Flag:        "notifications-slack-templates",
+			Env:         "CODER_NOTIFICATIONS_SLACK_TEMPLATES",
+			Default:     string(NotificationTemplateWorkspaceBuildFailed),
+			Value:       &c.Notifications.Slack.Templates,
+			Group:       &deploymentGroupNotificationsSlack,
+			YAML:        "templates",
+		},
+		{
+			Name:        "Notifications: Microsoft Teams: Webhook URL",
+			Description: "The Microsoft Teams incoming webhook URL notifications are posted to. Notifications are only posted to Microsoft Teams when this is set.",
+			Flag:        "notifications-teams-webhook-url",
+			Env:         "CODER_NOTIFICATIONS_TEAMS_WEBHOOK_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.Notifications.Teams.WebhookURL,
+			Group:       &deploymentGroupNotificationsTeams,
+		},
+		{
+			Name:        "Notifications: Microsoft Teams: Templates",
+			Description: "The notification templates that are posted to Microsoft Teams.",
+			Flag:        "notifications-teams-templates",
+			Env:         "CODER_NOTIFICATIONS_TEAMS_TEMPLATES",
+			Default:     string(NotificationTemplateWorkspaceBuildFailed),
+			Value:       &c.Notifications.Teams.Templates,
+			Group:       &deploymentGroupNotificationsTeams,
+			YAML:        "templates",
+		},
 		// Healthcheck Options
 		{
 			Name:        "Health Check Refresh",
@@ -1938,9 +2779,9 @@ func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
 			continue
 		}
 
-		// This only works with string values for now.
+		// This only works with string and URL values for now.
 		switch v := opt.Value.(type) {
-		case *clibase.String, *clibase.StringArray:
+		case *clibase.String, *clibase.StringArray, *clibase.URL:
 			err := v.Set("")
 			if err != nil {
 				panic(err)
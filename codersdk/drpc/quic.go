@@ -0,0 +1,127 @@
+package drpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"storj.io/drpc"
+	"storj.io/drpc/drpcconn"
+)
+
+const (
+	// QUICNextProto is the ALPN protocol negotiated by agents connecting to
+	// coderd over QUIC.
+	QUICNextProto = "coder-drpc"
+)
+
+// QUICConfig returns the QUIC configuration shared by both ends of an agent
+// connection.
+func QUICConfig() *quic.Config {
+	return &quic.Config{
+		HandshakeIdleTimeout: 10 * time.Second,
+		MaxIdleTimeout:       30 * time.Second,
+		KeepAlivePeriod:      10 * time.Second,
+	}
+}
+
+// MultiplexedQUICConn returns a multiplexed dRPC connection from a QUIC
+// connection. Every request opens its own QUIC stream, so a lost packet
+// only stalls the request it belongs to.
+func MultiplexedQUICConn(conn quic.Connection) drpc.Conn {
+	return &multiplexedQUIC{conn}
+}
+
+type multiplexedQUIC struct {
+	conn quic.Connection
+}
+
+func (m *multiplexedQUIC) Close() error {
+	return m.conn.CloseWithError(0, "")
+}
+
+func (m *multiplexedQUIC) Closed() <-chan struct{} {
+	return m.conn.Context().Done()
+}
+
+func (m *multiplexedQUIC) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, inMessage, outMessage drpc.Message) error {
+	stream, err := m.conn.OpenStreamSync(ctx)
+	if err != nil {
+		return err
+	}
+	dConn := drpcconn.New(QUICStreamConn(m.conn, stream))
+	defer func() {
+		_ = dConn.Close()
+	}()
+	return dConn.Invoke(ctx, rpc, enc, inMessage, outMessage)
+}
+
+func (m *multiplexedQUIC) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	stream, err := m.conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dConn := drpcconn.New(QUICStreamConn(m.conn, stream))
+	dStream, err := dConn.NewStream(ctx, rpc, enc)
+	if err != nil {
+		_ = dConn.Close()
+		return nil, err
+	}
+	go func() {
+		<-dStream.Context().Done()
+		_ = dConn.Close()
+	}()
+	return dStream, nil
+}
+
+// QUICStreamConn wraps a QUIC stream in a net.Conn. Closing the returned
+// connection closes both directions of the stream.
+func QUICStreamConn(conn quic.Connection, stream quic.Stream) net.Conn {
+	return &quicStreamConn{Stream: stream, conn: conn}
+}
+
+type quicStreamConn struct {
+	quic.Stream
+	conn quic.Connection
+}
+
+func (c *quicStreamConn) Close() error {
+	c.Stream.CancelRead(0)
+	return c.Stream.Close()
+}
+
+func (c *quicStreamConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *quicStreamConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// QUICListener returns a net.Listener that accepts the streams opened by
+// the peer of a QUIC connection. It is the server side of
+// MultiplexedQUICConn.
+func QUICListener(conn quic.Connection) net.Listener {
+	return &quicListener{conn: conn}
+}
+
+type quicListener struct {
+	conn quic.Connection
+}
+
+func (l *quicListener) Accept() (net.Conn, error) {
+	stream, err := l.conn.AcceptStream(l.conn.Context())
+	if err != nil {
+		return nil, err
+	}
+	return QUICStreamConn(l.conn, stream), nil
+}
+
+func (l *quicListener) Close() error {
+	return l.conn.CloseWithError(0, "")
+}
+
+func (l *quicListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
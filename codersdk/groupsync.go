@@ -0,0 +1,74 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupSyncTrigger is what started a group sync run.
+type GroupSyncTrigger string
+
+const (
+	GroupSyncTriggerLogin      GroupSyncTrigger = "login"
+	GroupSyncTriggerBackground GroupSyncTrigger = "background"
+)
+
+// GroupSyncRun is a run of OIDC group sync, recorded for debugging. Login runs
+// sync the user that logged in. Background runs sync every OIDC user from the
+// nested groups directory.
+type GroupSyncRun struct {
+	ID      uuid.UUID        `json:"id" format:"uuid"`
+	Trigger GroupSyncTrigger `json:"trigger" enums:"login,background"`
+	// UserID is the user that was synced at login.
+	UserID      *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	StartedAt   time.Time  `json:"started_at" format:"date-time"`
+	FinishedAt  time.Time  `json:"finished_at" format:"date-time"`
+	UsersSynced int32      `json:"users_synced"`
+	UsersFailed int32      `json:"users_failed"`
+	// IDPGroups are the groups the identity provider returned at login,
+	// before nested groups were resolved.
+	IDPGroups []string `json:"idp_groups"`
+	// Groups are the Coder groups the user was synced to at login.
+	Groups []string `json:"groups"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// GroupSyncRunsRequest filters the group sync runs.
+type GroupSyncRunsRequest struct {
+	// UserID only returns the login runs of the user.
+	UserID uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	// Limit is the maximum number of runs to return. Defaults to 50.
+	Limit int `json:"limit,omitempty"`
+}
+
+func (r GroupSyncRunsRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if r.UserID != uuid.Nil {
+			q.Set("user_id", r.UserID.String())
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// GroupSyncRuns returns the most recent group sync runs first.
+func (c *Client) GroupSyncRuns(ctx context.Context, req GroupSyncRunsRequest) ([]GroupSyncRun, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/group-sync-runs", nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var runs []GroupSyncRun
+	return runs, json.NewDecoder(res.Body).Decode(&runs)
+}
@@ -16,6 +16,7 @@ const (
 	HealthSectionAccessURL          HealthSection = "AccessURL"
 	HealthSectionWebsocket          HealthSection = "Websocket"
 	HealthSectionDatabase           HealthSection = "Database"
+	HealthSectionPubsub             HealthSection = "Pubsub"
 	HealthSectionWorkspaceProxy     HealthSection = "WorkspaceProxy"
 	HealthSectionProvisionerDaemons HealthSection = "ProvisionerDaemons"
 )
@@ -25,6 +26,7 @@ var HealthSections = []HealthSection{
 	HealthSectionAccessURL,
 	HealthSectionWebsocket,
 	HealthSectionDatabase,
+	HealthSectionPubsub,
 	HealthSectionWorkspaceProxy,
 	HealthSectionProvisionerDaemons,
 }
@@ -0,0 +1,143 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
+)
+
+// InboxNotification is a notification shown to a user in the dashboard.
+type InboxNotification struct {
+	ID        uuid.UUID            `json:"id" format:"uuid"`
+	UserID    uuid.UUID            `json:"user_id" format:"uuid"`
+	Template  NotificationTemplate `json:"template"`
+	Title     string               `json:"title"`
+	Content   string               `json:"content"`
+	CreatedAt time.Time            `json:"created_at" format:"date-time"`
+	ReadAt    *time.Time           `json:"read_at,omitempty" format:"date-time"`
+}
+
+type InboxNotificationsRequest struct {
+	// UnreadOnly excludes notifications that have been marked as read.
+	UnreadOnly bool `json:"unread_only"`
+	Pagination
+}
+
+type InboxNotificationsResponse struct {
+	Notifications []InboxNotification `json:"notifications"`
+	// UnreadCount is the number of unread notifications in the inbox, which
+	// may exceed the number of notifications returned.
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkInboxNotificationsReadRequest struct {
+	// IDs are the notifications to mark as read. All notifications are
+	// marked as read if it is empty.
+	IDs []uuid.UUID `json:"ids" format:"uuid"`
+}
+
+// InboxNotificationEvent is sent to watchers of an inbox when a notification
+// is added to it.
+type InboxNotificationEvent struct {
+	Notification InboxNotification `json:"notification"`
+	UnreadCount  int64             `json:"unread_count"`
+}
+
+// InboxNotifications returns the notifications in the inbox of a user, newest
+// first.
+func (c *Client) InboxNotifications(ctx context.Context, user string, req InboxNotificationsRequest) (InboxNotificationsResponse, error) {
+	u := fmt.Sprintf("/api/v2/users/%s/inbox", user)
+	if req.UnreadOnly {
+		u += "?unread_only=true"
+	}
+	res, err := c.Request(ctx, http.MethodGet, u, nil, req.Pagination.asRequestOption())
+	if err != nil {
+		return InboxNotificationsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return InboxNotificationsResponse{}, ReadBodyAsError(res)
+	}
+	var resp InboxNotificationsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// MarkInboxNotificationsRead marks notifications in the inbox of a user as
+// read.
+func (c *Client) MarkInboxNotificationsRead(ctx context.Context, user string, req MarkInboxNotificationsReadRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/inbox/read", user), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WatchInboxNotifications streams the notifications added to the inbox of a
+// user until the context is canceled or the closer is closed.
+func (c *Client) WatchInboxNotifications(ctx context.Context, user string) (<-chan InboxNotificationEvent, io.Closer, error) {
+	reqURL, err := c.URL.Parse(fmt.Sprintf("/api/v2/users/%s/inbox/watch", user))
+	if err != nil {
+		return nil, nil, err
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("create cookie jar: %w", err)
+	}
+	jar.SetCookies(reqURL, []*http.Cookie{{
+		Name:  SessionTokenCookie,
+		Value: c.SessionToken(),
+	}})
+	httpClient := &http.Client{
+		Jar:       jar,
+		Transport: c.HTTPClient.Transport,
+	}
+	conn, res, err := websocket.Dial(ctx, reqURL.String(), &websocket.DialOptions{
+		HTTPClient:      httpClient,
+		CompressionMode: websocket.CompressionDisabled,
+	})
+	if err != nil {
+		if res == nil {
+			return nil, nil, err
+		}
+		return nil, nil, ReadBodyAsError(res)
+	}
+
+	events := make(chan InboxNotificationEvent, 1)
+	closed := make(chan struct{})
+	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageText)
+	decoder := json.NewDecoder(wsNetConn)
+	go func() {
+		defer close(closed)
+		defer close(events)
+		defer conn.Close(websocket.StatusGoingAway, "")
+		for {
+			var event InboxNotificationEvent
+			err := decoder.Decode(&event)
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case events <- event:
+			}
+		}
+	}()
+	return events, closeFunc(func() error {
+		_ = wsNetConn.Close()
+		<-closed
+		return nil
+	}), nil
+}
@@ -216,6 +216,11 @@ type TemplateAppUsage struct {
 	Slug        string           `json:"slug" example:"vscode"`
 	Icon        string           `json:"icon"`
 	Seconds     int64            `json:"seconds" example:"80500"`
+	// Requests, ActiveUsers and LastUsedAt are only reported for template
+	// apps, they're recorded by the workspace app stats reporter.
+	Requests    int64      `json:"requests,omitempty" example:"1200"`
+	ActiveUsers int64      `json:"active_users,omitempty" example:"12"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" format:"date-time"`
 }
 
 // TemplateParameterUsage shows the usage of a parameter for one or more
@@ -280,3 +285,53 @@ func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsReque
 	var result TemplateInsightsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// TemplateSummaryInsightsResponse is the response from the insights endpoint of
+// a single template.
+type TemplateSummaryInsightsResponse struct {
+	TemplateID       uuid.UUID                        `json:"template_id" format:"uuid"`
+	StartTime        time.Time                        `json:"start_time" format:"date-time"`
+	EndTime          time.Time                        `json:"end_time" format:"date-time"`
+	Builds           TemplateBuildInsights            `json:"builds"`
+	DailyActiveUsers []TemplateInsightsIntervalReport `json:"daily_active_users"`
+	ParametersUsage  []TemplateParameterUsage         `json:"parameters_usage"`
+}
+
+// TemplateBuildInsights summarizes the finished workspace builds of a
+// template. Canceled builds are not counted.
+type TemplateBuildInsights struct {
+	TotalBuilds  int64 `json:"total_builds" example:"120"`
+	FailedBuilds int64 `json:"failed_builds" example:"6"`
+	// SuccessRate is the share of builds that succeeded, between 0 and 1. It
+	// is 0 if there were no builds.
+	SuccessRate float64 `json:"success_rate" example:"0.95"`
+	// BuildTime is the median and 95th percentile duration of the successful
+	// builds in milliseconds.
+	BuildTime TransitionStats `json:"build_time"`
+}
+
+type TemplateSummaryInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+}
+
+// TemplateSummaryInsights returns build, usage and parameter insights for a
+// single template in the given timeframe.
+func (c *Client) TemplateSummaryInsights(ctx context.Context, templateID uuid.UUID, req TemplateSummaryInsightsRequest) (TemplateSummaryInsightsResponse, error) {
+	qp := url.Values{}
+	qp.Add("start_time", req.StartTime.Format(insightsTimeLayout))
+	qp.Add("end_time", req.EndTime.Format(insightsTimeLayout))
+
+	reqURL := fmt.Sprintf("/api/v2/templates/%s/insights?%s", templateID, qp.Encode())
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return TemplateSummaryInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TemplateSummaryInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result TemplateSummaryInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
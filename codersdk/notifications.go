@@ -0,0 +1,124 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTemplate identifies the kind of a notification and the
+// template it is rendered with.
+type NotificationTemplate string
+
+const (
+	NotificationTemplateWorkspaceDeleting    NotificationTemplate = "workspace_deleting"
+	NotificationTemplateWorkspaceBuildFailed NotificationTemplate = "workspace_build_failed"
+	NotificationTemplateUserAccountCreated   NotificationTemplate = "user_account_created"
+)
+
+// NotificationTemplates is every template a notification can be sent with.
+var NotificationTemplates = []NotificationTemplate{
+	NotificationTemplateWorkspaceDeleting,
+	NotificationTemplateWorkspaceBuildFailed,
+	NotificationTemplateUserAccountCreated,
+}
+
+func (t NotificationTemplate) Valid() bool {
+	for _, template := range NotificationTemplates {
+		if t == template {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationMethod is how a notification is delivered.
+type NotificationMethod string
+
+const (
+	NotificationMethodSMTP  NotificationMethod = "smtp"
+	NotificationMethodSlack NotificationMethod = "slack"
+	NotificationMethodTeams NotificationMethod = "teams"
+)
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessageStatusPending NotificationMessageStatus = "pending"
+	NotificationMessageStatusSent    NotificationMessageStatus = "sent"
+	NotificationMessageStatusFailed  NotificationMessageStatus = "failed"
+)
+
+// NotificationMessage is a notification sent to a user and its delivery
+// status.
+type NotificationMessage struct {
+	ID        uuid.UUID                 `json:"id" format:"uuid"`
+	UserID    uuid.UUID                 `json:"user_id" format:"uuid"`
+	Template  NotificationTemplate      `json:"template"`
+	Method    NotificationMethod        `json:"method" enums:"smtp,slack,teams"`
+	Labels    map[string]string         `json:"labels"`
+	Status    NotificationMessageStatus `json:"status" enums:"pending,sent,failed"`
+	Attempts  int32                     `json:"attempts"`
+	LastError string                    `json:"last_error"`
+	CreatedAt time.Time                 `json:"created_at" format:"date-time"`
+	SentAt    *time.Time                `json:"sent_at,omitempty" format:"date-time"`
+}
+
+// NotificationPreference is whether a user receives notifications of a
+// template.
+type NotificationPreference struct {
+	Template NotificationTemplate `json:"template"`
+	Disabled bool                 `json:"disabled"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"required"`
+}
+
+// UserNotifications returns the most recent notifications sent to a user.
+func (c *Client) UserNotifications(ctx context.Context, user string) ([]NotificationMessage, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var msgs []NotificationMessage
+	return msgs, json.NewDecoder(res.Body).Decode(&msgs)
+}
+
+// NotificationPreferences returns the preference of a user for every
+// notification template.
+func (c *Client) NotificationPreferences(ctx context.Context, user string) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var prefs []NotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
+
+// UpdateNotificationPreferences opts a user out of or back into the
+// notification templates in the request. Other templates are left as is.
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, user string, req UpdateNotificationPreferencesRequest) ([]NotificationPreference, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/notifications/preferences", user), req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var prefs []NotificationPreference
+	return prefs, json.NewDecoder(res.Body).Decode(&prefs)
+}
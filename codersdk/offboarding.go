@@ -0,0 +1,76 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// OffboardWorkspaceAction is what happens to the workspaces of a user that is
+// offboarded. Transferred workspaces are given to another user. Archived
+// workspaces are marked dormant, so they can't be started and are deleted once
+// the template's dormancy auto-delete period has passed.
+type OffboardWorkspaceAction string
+
+const (
+	OffboardWorkspaceActionTransfer OffboardWorkspaceAction = "transfer"
+	OffboardWorkspaceActionArchive  OffboardWorkspaceAction = "archive"
+)
+
+// OffboardUserRequest offboards a user. Their workspaces are transferred or
+// archived, their API keys and external auth links are revoked, they are
+// removed from their groups, and they are suspended.
+type OffboardUserRequest struct {
+	WorkspaceAction OffboardWorkspaceAction `json:"workspace_action" validate:"required,oneof=transfer archive" enums:"transfer,archive"`
+	// TransferTo is the user that receives the workspaces. It is required
+	// when the workspace action is "transfer".
+	TransferTo uuid.UUID `json:"transfer_to,omitempty" format:"uuid"`
+	// DryRun reports what would change without changing anything.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// OffboardUserResponse lists what was changed when offboarding a user, or what
+// would change in a dry run.
+type OffboardUserResponse struct {
+	DryRun                       bool                    `json:"dry_run"`
+	WorkspaceAction              OffboardWorkspaceAction `json:"workspace_action" enums:"transfer,archive"`
+	User                         User                    `json:"user"`
+	Workspaces                   []OffboardedWorkspace   `json:"workspaces"`
+	RevokedAPIKeyIDs             []string                `json:"revoked_api_key_ids"`
+	RevokedExternalAuthProviders []string                `json:"revoked_external_auth_providers"`
+	RemovedGroups                []OffboardedGroup       `json:"removed_groups"`
+}
+
+// OffboardedWorkspace is a workspace of an offboarded user.
+type OffboardedWorkspace struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	// NewOwnerID is set if the workspace was transferred.
+	NewOwnerID *uuid.UUID `json:"new_owner_id,omitempty" format:"uuid"`
+}
+
+// OffboardedGroup is a group an offboarded user was removed from.
+type OffboardedGroup struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+}
+
+// OffboardUser offboards a user. All changes are made in a single
+// transaction, so either all of them are made or none are.
+func (c *Client) OffboardUser(ctx context.Context, user string, req OffboardUserRequest) (OffboardUserResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/offboard", user), req)
+	if err != nil {
+		return OffboardUserResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OffboardUserResponse{}, ReadBodyAsError(res)
+	}
+	var resp OffboardUserResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
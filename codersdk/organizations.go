@@ -20,8 +20,10 @@ const (
 type ProvisionerType string
 
 const (
-	ProvisionerTypeEcho      ProvisionerType = "echo"
-	ProvisionerTypeTerraform ProvisionerType = "terraform"
+	ProvisionerTypeEcho       ProvisionerType = "echo"
+	ProvisionerTypeTerraform  ProvisionerType = "terraform"
+	ProvisionerTypePulumi     ProvisionerType = "pulumi"
+	ProvisionerTypeKubernetes ProvisionerType = "kubernetes"
 )
 
 // Organization is the JSON representation of a Coder organization.
@@ -40,6 +42,13 @@ type OrganizationMember struct {
 	Roles          []Role    `db:"roles" json:"roles"`
 }
 
+// OrganizationMemberWithName is an organization member with the username of
+// the user.
+type OrganizationMemberWithName struct {
+	Username string `json:"username"`
+	OrganizationMember
+}
+
 // CreateTemplateVersionRequest enables callers to create a new Template Version.
 type CreateTemplateVersionRequest struct {
 	Name    string `json:"name,omitempty" validate:"omitempty,template_version_name"`
@@ -49,8 +58,14 @@ type CreateTemplateVersionRequest struct {
 	StorageMethod   ProvisionerStorageMethod `json:"storage_method" validate:"oneof=file,required" enums:"file"`
 	FileID          uuid.UUID                `json:"file_id,omitempty" validate:"required_without=ExampleID" format:"uuid"`
 	ExampleID       string                   `json:"example_id,omitempty" validate:"required_without=FileID"`
-	Provisioner     ProvisionerType          `json:"provisioner" validate:"oneof=terraform echo,required"`
+	Provisioner     ProvisionerType          `json:"provisioner" validate:"oneof=terraform echo pulumi kubernetes,required"`
 	ProvisionerTags map[string]string        `json:"tags"`
+	// ProvisionerRequirements constrain the tags of the provisioner daemons
+	// that may build the template version, e.g. "region in [eu-west, eu-*]" or
+	// "gpu=true". Jobs of workspaces built from the version inherit them.
+	ProvisionerRequirements []string `json:"provisioner_requirements,omitempty"`
+	// Git optionally records the git commit the version was created from.
+	Git *TemplateVersionGitMetadata `json:"git,omitempty"`
 
 	UserVariableValues []VariableValue `json:"user_variable_values,omitempty"`
 }
@@ -130,6 +145,9 @@ type CreateTemplateRequest struct {
 	// RequireActiveVersion mandates that workspaces are built with the active
 	// template version.
 	RequireActiveVersion bool `json:"require_active_version"`
+
+	// Visibility restricts who can see the template. Defaults to public.
+	Visibility TemplateVisibility `json:"visibility,omitempty" enums:"private,unlisted,public"`
 }
 
 // CreateWorkspaceRequest provides options for creating a new workspace.
@@ -146,6 +164,12 @@ type CreateWorkspaceRequest struct {
 	// during the initial provision.
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
 	AutomaticUpdates    AutomaticUpdates          `json:"automatic_updates,omitempty"`
+	// Preset is the name of a preset of the template version. Its values are
+	// used for the parameters that aren't in RichParameterValues.
+	Preset string `json:"preset,omitempty"`
+	// Ephemeral creates a workspace that is deleted, rather than stopped,
+	// once it outlives its TTL or the API token used to create it expires.
+	Ephemeral bool `json:"ephemeral,omitempty"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -163,6 +187,67 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return organization, json.NewDecoder(res.Body).Decode(&organization)
 }
 
+// DeleteOrganization deletes an organization. Its templates must be deleted
+// first, and every member must belong to another organization.
+func (c *Client) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s", id.String()), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OrganizationMembers lists the members of an organization.
+func (c *Client) OrganizationMembers(ctx context.Context, organizationID uuid.UUID) ([]OrganizationMemberWithName, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/members", organizationID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var members []OrganizationMemberWithName
+	return members, json.NewDecoder(res.Body).Decode(&members)
+}
+
+// AddOrganizationMember adds a user to an organization.
+func (c *Client) AddOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) (OrganizationMember, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return OrganizationMember{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return OrganizationMember{}, ReadBodyAsError(res)
+	}
+
+	var member OrganizationMember
+	return member, json.NewDecoder(res.Body).Decode(&member)
+}
+
+// RemoveOrganizationMember removes a user from an organization and its groups.
+func (c *Client) RemoveOrganizationMember(ctx context.Context, organizationID uuid.UUID, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/members/%s", organizationID, user), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // ProvisionerDaemons returns provisioner daemons available.
 func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
 	res, err := c.Request(ctx, http.MethodGet,
@@ -183,6 +268,26 @@ func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, e
 	return daemons, json.NewDecoder(res.Body).Decode(&daemons)
 }
 
+// OrganizationProvisionerDaemons returns the provisioner daemons that can
+// acquire jobs from the organization.
+func (c *Client) OrganizationProvisionerDaemons(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerDaemon, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var daemons []ProvisionerDaemon
+	return daemons, json.NewDecoder(res.Body).Decode(&daemons)
+}
+
 // CreateTemplateVersion processes source-code and optionally associates the version with a template.
 // Executing without a template is useful for validating source-code.
 func (c *Client) CreateTemplateVersion(ctx context.Context, organizationID uuid.UUID, req CreateTemplateVersionRequest) (TemplateVersion, error) {
@@ -45,6 +45,8 @@ type ProvisionerDaemon struct {
 	APIVersion   string            `json:"api_version"`
 	Provisioners []ProvisionerType `json:"provisioners"`
 	Tags         map[string]string `json:"tags"`
+	// OrganizationID is set if the daemon only acquires jobs from one organization.
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty" format:"uuid"`
 }
 
 // ProvisionerJobStatus represents the at-time state of a job.
@@ -97,6 +99,55 @@ type ProvisionerJob struct {
 	Tags          map[string]string    `json:"tags"`
 	QueuePosition int                  `json:"queue_position"`
 	QueueSize     int                  `json:"queue_size"`
+	Priority      int32                `json:"priority"`
+}
+
+// UpdateProvisionerJobPriorityRequest reorders a pending job in the queue.
+// Jobs with a higher priority are acquired first. The default is zero.
+type UpdateProvisionerJobPriorityRequest struct {
+	Priority int32 `json:"priority"`
+}
+
+// UpdateProvisionerJobPriority changes the priority of a pending job so it
+// is acquired ahead of (or behind) other jobs in the organization's queue.
+func (c *Client) UpdateProvisionerJobPriority(ctx context.Context, organizationID, jobID uuid.UUID, req UpdateProvisionerJobPriorityRequest) (ProvisionerJob, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/provisionerjobs/%s/priority", organizationID, jobID), req)
+	if err != nil {
+		return ProvisionerJob{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerJob{}, ReadBodyAsError(res)
+	}
+	var job ProvisionerJob
+	return job, json.NewDecoder(res.Body).Decode(&job)
+}
+
+// PurgeProvisionerCacheResponse reports what was removed from the shared
+// provisioner cache.
+type PurgeProvisionerCacheResponse struct {
+	DeletedObjects int64 `json:"deleted_objects"`
+	DeletedBytes   int64 `json:"deleted_bytes"`
+}
+
+// PurgeProvisionerCache removes the Terraform providers and modules that were
+// cached longer ago than olderThan from the shared provisioner cache. Zero
+// purges everything.
+func (c *Client) PurgeProvisionerCache(ctx context.Context, olderThan time.Duration) (PurgeProvisionerCacheResponse, error) {
+	var opts []RequestOption
+	if olderThan > 0 {
+		opts = append(opts, WithQueryParam("older_than", olderThan.String()))
+	}
+	res, err := c.Request(ctx, http.MethodDelete, "/api/v2/provisionerdaemons/cache", nil, opts...)
+	if err != nil {
+		return PurgeProvisionerCacheResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return PurgeProvisionerCacheResponse{}, ReadBodyAsError(res)
+	}
+	var resp PurgeProvisionerCacheResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
 // ProvisionerJobLog represents the provisioner log entry annotated with source and level.
@@ -180,15 +231,20 @@ type ServeProvisionerDaemonRequest struct {
 	ID uuid.UUID `json:"id" format:"uuid"`
 	// Name is the human-readable unique identifier for the daemon.
 	Name string `json:"name" example:"my-cool-provisioner-daemon"`
-	// Organization is the organization for the URL.  At present provisioner daemons ARE NOT scoped to organizations
-	// and so the organization ID is optional.
+	// Organization is the organization for the URL.  Provisioner daemons are only scoped to the organization if
+	// OrganizationScoped is set, so otherwise the organization ID is optional.
 	Organization uuid.UUID `json:"organization" format:"uuid"`
+	// OrganizationScoped restricts the daemon to jobs from Organization.
+	OrganizationScoped bool `json:"organization_scoped"`
 	// Provisioners is a list of provisioner types hosted by the provisioner daemon
 	Provisioners []ProvisionerType `json:"provisioners"`
 	// Tags is a map of key-value pairs that tag the jobs this provisioner daemon can handle
 	Tags map[string]string `json:"tags"`
 	// PreSharedKey is an authentication key to use on the API instead of the normal session token from the client.
 	PreSharedKey string `json:"pre_shared_key"`
+	// ProvisionerKey is a provisioner key of the organization to use on the API instead of the normal session token
+	// from the client. The organization and tags of the key are enforced on the daemon.
+	ProvisionerKey string `json:"provisioner_key"`
 }
 
 // ServeProvisionerDaemon returns the gRPC service for a provisioner daemon
@@ -208,6 +264,9 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 	for key, value := range req.Tags {
 		query.Add("tag", fmt.Sprintf("%s=%s", key, value))
 	}
+	if req.OrganizationScoped {
+		query.Add("organization_scoped", "true")
+	}
 	serverURL.RawQuery = query.Encode()
 	httpClient := &http.Client{
 		Transport: c.HTTPClient.Transport,
@@ -215,8 +274,13 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 	headers := http.Header{}
 
 	headers.Set(BuildVersionHeader, buildinfo.Version())
-	if req.PreSharedKey == "" {
-		// use session token if we don't have a PSK.
+	switch {
+	case req.ProvisionerKey != "":
+		headers.Set(ProvisionerDaemonKey, req.ProvisionerKey)
+	case req.PreSharedKey != "":
+		headers.Set(ProvisionerDaemonPSK, req.PreSharedKey)
+	default:
+		// use session token if we don't have a PSK or provisioner key.
 		jar, err := cookiejar.New(nil)
 		if err != nil {
 			return nil, xerrors.Errorf("create cookie jar: %w", err)
@@ -226,8 +290,6 @@ func (c *Client) ServeProvisionerDaemon(ctx context.Context, req ServeProvisione
 			Value: c.SessionToken(),
 		}})
 		httpClient.Jar = jar
-	} else {
-		headers.Set(ProvisionerDaemonPSK, req.PreSharedKey)
 	}
 
 	conn, res, err := websocket.Dial(ctx, serverURL.String(), &websocket.DialOptions{
@@ -0,0 +1,109 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// ProvisionerKey authenticates external provisioner daemons to an
+// organization. Daemons authenticated with a key are scoped to its
+// organization and always have its tags.
+type ProvisionerKey struct {
+	ID             uuid.UUID         `json:"id" format:"uuid"`
+	CreatedAt      time.Time         `json:"created_at" format:"date-time"`
+	OrganizationID uuid.UUID         `json:"organization_id" format:"uuid"`
+	Name           string            `json:"name"`
+	Tags           map[string]string `json:"tags"`
+	RotatedAt      NullTime          `json:"rotated_at,omitempty" format:"date-time"`
+}
+
+type CreateProvisionerKeyRequest struct {
+	Name string            `json:"name" validate:"required,username"`
+	Tags map[string]string `json:"tags"`
+}
+
+// CreateProvisionerKeyResponse contains the secret of a provisioner key. It is
+// only returned when the key is created or rotated.
+type CreateProvisionerKeyResponse struct {
+	ProvisionerKey
+	Key string `json:"key"`
+}
+
+// CreateProvisionerKey creates a provisioner key for the organization.
+func (c *Client) CreateProvisionerKey(ctx context.Context, organizationID uuid.UUID, req CreateProvisionerKeyRequest) (CreateProvisionerKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys", organizationID.String()),
+		req,
+	)
+	if err != nil {
+		return CreateProvisionerKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return CreateProvisionerKeyResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateProvisionerKeyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// ListProvisionerKeys lists the provisioner keys of the organization.
+func (c *Client) ListProvisionerKeys(ctx context.Context, organizationID uuid.UUID) ([]ProvisionerKey, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var keys []ProvisionerKey
+	return keys, json.NewDecoder(res.Body).Decode(&keys)
+}
+
+// RotateProvisionerKey replaces the secret of a provisioner key. Daemons
+// authenticated with the previous secret are disconnected.
+func (c *Client) RotateProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string) (CreateProvisionerKeyResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s/rotate", organizationID.String(), name),
+		nil,
+	)
+	if err != nil {
+		return CreateProvisionerKeyResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return CreateProvisionerKeyResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateProvisionerKeyResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DeleteProvisionerKey revokes a provisioner key. Daemons authenticated with
+// the key are disconnected.
+func (c *Client) DeleteProvisionerKey(ctx context.Context, organizationID uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerkeys/%s", organizationID.String(), name),
+		nil,
+	)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
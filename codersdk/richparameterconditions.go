@@ -0,0 +1,270 @@
+package codersdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/xerrors"
+)
+
+// ParameterDisplayCondition is a parsed display condition of a parameter. A
+// condition is one or more clauses joined by "&&", where each clause compares
+// the value of another parameter:
+//
+//	machine == "gpu"
+//	region != "eu" && machine in ["gpu", "tpu"]
+//
+// Values are double-quoted strings, as values of all parameter types are
+// compared as strings.
+// @typescript-ignore ParameterDisplayCondition
+type ParameterDisplayCondition struct {
+	clauses []parameterConditionClause
+}
+
+type parameterConditionClause struct {
+	parameter string
+	negate    bool
+	values    []string
+}
+
+// ParseParameterDisplayCondition parses the display condition of a parameter.
+// An empty condition is always met.
+func ParseParameterDisplayCondition(condition string) (ParameterDisplayCondition, error) {
+	p := &conditionParser{input: condition}
+	var parsed ParameterDisplayCondition
+	p.skipSpace()
+	if p.done() {
+		return parsed, nil
+	}
+	for {
+		clause, err := p.clause()
+		if err != nil {
+			return ParameterDisplayCondition{}, xerrors.Errorf("parse display condition %q: %w", condition, err)
+		}
+		parsed.clauses = append(parsed.clauses, clause)
+		p.skipSpace()
+		if p.done() {
+			return parsed, nil
+		}
+		if !p.consume("&&") {
+			return ParameterDisplayCondition{}, xerrors.Errorf("parse display condition %q: expected \"&&\" at offset %d", condition, p.pos)
+		}
+	}
+}
+
+// Parameters returns the sorted names of the parameters the condition
+// depends on.
+func (c ParameterDisplayCondition) Parameters() []string {
+	seen := map[string]struct{}{}
+	var names []string
+	for _, clause := range c.clauses {
+		if _, ok := seen[clause.parameter]; ok {
+			continue
+		}
+		seen[clause.parameter] = struct{}{}
+		names = append(names, clause.parameter)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Met returns whether the condition is met by the given parameter values.
+// Parameters without a value are compared as empty strings.
+func (c ParameterDisplayCondition) Met(values map[string]string) bool {
+	for _, clause := range c.clauses {
+		value := values[clause.parameter]
+		matched := false
+		for _, v := range clause.values {
+			if v == value {
+				matched = true
+				break
+			}
+		}
+		if matched == clause.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// ParameterDisplayed returns whether a parameter is displayed, and its value
+// validated, given the values of the other parameters.
+func ParameterDisplayed(p TemplateVersionParameter, values map[string]string) (bool, error) {
+	condition, err := ParseParameterDisplayCondition(p.DisplayCondition)
+	if err != nil {
+		return false, err
+	}
+	return condition.Met(values), nil
+}
+
+// ValidateParameterDisplayConditions validates that the display conditions of
+// the parameters parse, and only depend on other parameters without forming a
+// cycle.
+func ValidateParameterDisplayConditions(params []TemplateVersionParameter) error {
+	dependencies := make(map[string][]string, len(params))
+	for _, p := range params {
+		dependencies[p.Name] = nil
+	}
+	for _, p := range params {
+		condition, err := ParseParameterDisplayCondition(p.DisplayCondition)
+		if err != nil {
+			return xerrors.Errorf("parameter %q: %w", p.Name, err)
+		}
+		for _, name := range condition.Parameters() {
+			if name == p.Name {
+				return xerrors.Errorf("parameter %q: display condition can't depend on the parameter itself", p.Name)
+			}
+			if _, ok := dependencies[name]; !ok {
+				return xerrors.Errorf("parameter %q: display condition depends on unknown parameter %q", p.Name, name)
+			}
+		}
+		dependencies[p.Name] = condition.Parameters()
+	}
+
+	// Depth-first search for cycles, visiting parameters in a stable order.
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(params))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return xerrors.Errorf("display conditions of parameter %q depend on each other", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, p := range params {
+		if err := visit(p.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type conditionParser struct {
+	input string
+	pos   int
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *conditionParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *conditionParser) consume(token string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) clause() (parameterConditionClause, error) {
+	var clause parameterConditionClause
+	name, err := p.identifier()
+	if err != nil {
+		return clause, err
+	}
+	clause.parameter = name
+
+	switch {
+	case p.consume("=="):
+	case p.consume("!="):
+		clause.negate = true
+	case p.consume("in"):
+		values, err := p.list()
+		if err != nil {
+			return clause, err
+		}
+		clause.values = values
+		return clause, nil
+	default:
+		return clause, xerrors.Errorf("expected \"==\", \"!=\" or \"in\" at offset %d", p.pos)
+	}
+	value, err := p.str()
+	if err != nil {
+		return clause, err
+	}
+	clause.values = []string{value}
+	return clause, nil
+}
+
+func (p *conditionParser) identifier() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	for !p.done() {
+		c := rune(p.input[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '-' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", xerrors.Errorf("expected a parameter name at offset %d", start)
+	}
+	return p.input[start:p.pos], nil
+}
+
+func (p *conditionParser) list() ([]string, error) {
+	if !p.consume("[") {
+		return nil, xerrors.Errorf("expected \"[\" at offset %d", p.pos)
+	}
+	var values []string
+	for {
+		value, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.consume("]") {
+			return values, nil
+		}
+		if !p.consume(",") {
+			return nil, xerrors.Errorf("expected \",\" or \"]\" at offset %d", p.pos)
+		}
+	}
+}
+
+func (p *conditionParser) str() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.done() || p.input[p.pos] != '"' {
+		return "", xerrors.Errorf("expected a double-quoted value at offset %d", start)
+	}
+	p.pos++
+	for !p.done() {
+		switch p.input[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			value, err := strconv.Unquote(p.input[start:p.pos])
+			if err != nil {
+				return "", xerrors.Errorf("invalid value at offset %d: %w", start, err)
+			}
+			return value, nil
+		}
+		p.pos++
+	}
+	return "", xerrors.Errorf("unterminated value at offset %d", start)
+}
@@ -0,0 +1,133 @@
+package codersdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestParseParameterDisplayCondition(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name       string
+		Condition  string
+		Parameters []string
+		Met        map[string]string
+		NotMet     map[string]string
+		Error      string
+	}{{
+		Name:   "Empty",
+		Met:    map[string]string{},
+		NotMet: nil,
+	}, {
+		Name:       "Equal",
+		Condition:  `machine == "gpu"`,
+		Parameters: []string{"machine"},
+		Met:        map[string]string{"machine": "gpu"},
+		NotMet:     map[string]string{"machine": "cpu"},
+	}, {
+		Name:       "NotEqual",
+		Condition:  `machine != "gpu"`,
+		Parameters: []string{"machine"},
+		Met:        map[string]string{"machine": "cpu"},
+		NotMet:     map[string]string{"machine": "gpu"},
+	}, {
+		Name:       "In",
+		Condition:  `machine in ["gpu", "tpu"]`,
+		Parameters: []string{"machine"},
+		Met:        map[string]string{"machine": "tpu"},
+		NotMet:     map[string]string{"machine": "cpu"},
+	}, {
+		Name:       "And",
+		Condition:  `region == "eu" && machine in ["gpu"] && gpu-count != "0"`,
+		Parameters: []string{"gpu-count", "machine", "region"},
+		Met:        map[string]string{"region": "eu", "machine": "gpu", "gpu-count": "2"},
+		NotMet:     map[string]string{"region": "eu", "machine": "gpu", "gpu-count": "0"},
+	}, {
+		Name:       "Escaped",
+		Condition:  `label == "say \"hi\""`,
+		Parameters: []string{"label"},
+		Met:        map[string]string{"label": `say "hi"`},
+		NotMet:     map[string]string{"label": "say hi"},
+	}, {
+		Name:      "MissingOperator",
+		Condition: `machine "gpu"`,
+		Error:     `expected "==", "!=" or "in"`,
+	}, {
+		Name:      "UnquotedValue",
+		Condition: `machine == gpu`,
+		Error:     "expected a double-quoted value",
+	}, {
+		Name:      "Unterminated",
+		Condition: `machine == "gpu`,
+		Error:     "unterminated value",
+	}, {
+		Name:      "TrailingAnd",
+		Condition: `machine == "gpu" &&`,
+		Error:     "expected a parameter name",
+	}, {
+		Name:      "Or",
+		Condition: `machine == "gpu" || machine == "tpu"`,
+		Error:     `expected "&&"`,
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			condition, err := codersdk.ParseParameterDisplayCondition(tc.Condition)
+			if tc.Error != "" {
+				require.ErrorContains(t, err, tc.Error)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Parameters, condition.Parameters())
+			require.True(t, condition.Met(tc.Met))
+			if tc.NotMet != nil {
+				require.False(t, condition.Met(tc.NotMet))
+			}
+		})
+	}
+}
+
+func TestValidateParameterDisplayConditions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		err := codersdk.ValidateParameterDisplayConditions([]codersdk.TemplateVersionParameter{
+			{Name: "machine"},
+			{Name: "gpu_count", DisplayCondition: `machine == "gpu"`},
+			{Name: "gpu_driver", DisplayCondition: `machine == "gpu" && gpu_count != "0"`},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+		err := codersdk.ValidateParameterDisplayConditions([]codersdk.TemplateVersionParameter{
+			{Name: "gpu_count", DisplayCondition: `machine == "gpu"`},
+		})
+		require.ErrorContains(t, err, `unknown parameter "machine"`)
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		t.Parallel()
+		err := codersdk.ValidateParameterDisplayConditions([]codersdk.TemplateVersionParameter{
+			{Name: "machine", DisplayCondition: `machine == "gpu"`},
+		})
+		require.ErrorContains(t, err, "itself")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		t.Parallel()
+		err := codersdk.ValidateParameterDisplayConditions([]codersdk.TemplateVersionParameter{
+			{Name: "a", DisplayCondition: `b == "x"`},
+			{Name: "b", DisplayCondition: `c == "x"`},
+			{Name: "c", DisplayCondition: `a == "x"`},
+		})
+		require.ErrorContains(t, err, "depend on each other")
+	})
+}
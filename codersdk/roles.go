@@ -19,6 +19,20 @@ type AssignableRoles struct {
 	Assignable bool `json:"assignable"`
 }
 
+// Permission allows, or with Negate denies, an action on a resource type.
+type Permission struct {
+	Negate       bool         `json:"negate"`
+	ResourceType RBACResource `json:"resource_type"`
+	Action       string       `json:"action"`
+}
+
+// CustomRole is a site wide role defined by an administrator.
+type CustomRole struct {
+	Name            string       `json:"name"`
+	DisplayName     string       `json:"display_name"`
+	SitePermissions []Permission `json:"site_permissions"`
+}
+
 // ListSiteRoles lists all assignable site wide roles.
 func (c *Client) ListSiteRoles(ctx context.Context) ([]AssignableRoles, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/roles", nil)
@@ -46,3 +60,31 @@ func (c *Client) ListOrganizationRoles(ctx context.Context, org uuid.UUID) ([]As
 	var roles []AssignableRoles
 	return roles, json.NewDecoder(res.Body).Decode(&roles)
 }
+
+// ListCustomRoles lists all custom site wide roles.
+func (c *Client) ListCustomRoles(ctx context.Context) ([]CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/roles/custom", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var roles []CustomRole
+	return roles, json.NewDecoder(res.Body).Decode(&roles)
+}
+
+// PatchRole creates or updates a custom site wide role.
+func (c *Client) PatchRole(ctx context.Context, req CustomRole) (CustomRole, error) {
+	res, err := c.Request(ctx, http.MethodPatch, "/api/v2/users/roles", req)
+	if err != nil {
+		return CustomRole{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return CustomRole{}, ReadBodyAsError(res)
+	}
+	var role CustomRole
+	return role, json.NewDecoder(res.Body).Decode(&role)
+}
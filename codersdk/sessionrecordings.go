@@ -0,0 +1,57 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionRecording is a recorded web terminal session. The recording itself
+// is in the asciinema v2 format.
+type SessionRecording struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	WorkspaceID    uuid.UUID `json:"workspace_id" format:"uuid"`
+	AgentID        uuid.UUID `json:"agent_id" format:"uuid"`
+	// UserID is the user that opened the session.
+	UserID    uuid.UUID `json:"user_id" format:"uuid"`
+	StartedAt time.Time `json:"started_at" format:"date-time"`
+	EndedAt   time.Time `json:"ended_at" format:"date-time"`
+	SizeBytes int64     `json:"size_bytes"`
+	// ExpiresAt is when the recording is deleted, if the template has a
+	// retention period.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" format:"date-time"`
+}
+
+// SessionRecordings lists the recorded sessions of the workspace, latest first.
+func (c *Client) SessionRecordings(ctx context.Context, workspaceID uuid.UUID) ([]SessionRecording, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/sessionrecordings?workspace_id=%s", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var recordings []SessionRecording
+	return recordings, json.NewDecoder(res.Body).Decode(&recordings)
+}
+
+// SessionRecording downloads the asciinema recording of the session.
+func (c *Client) SessionRecording(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/sessionrecordings/%s", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -61,6 +62,29 @@ type Template struct {
 	// RequireActiveVersion mandates that workspaces are built with the active
 	// template version.
 	RequireActiveVersion bool `json:"require_active_version"`
+	// MaintenanceWindow is when running workspaces on an outdated template
+	// version are stopped and rebuilt on the active version.
+	MaintenanceWindow TemplateMaintenanceWindow `json:"maintenance_window"`
+	// Visibility restricts who can see the template, on top of its ACL.
+	Visibility TemplateVisibility `json:"visibility" enums:"private,unlisted,public"`
+	// MaxBuildDurationMillis is how long workspace builds may run before they
+	// are failed and aborted. Zero means builds never time out.
+	MaxBuildDurationMillis int64 `json:"max_build_duration_ms"`
+	// WorkspaceNamePolicy restricts the names of workspaces created from the
+	// template.
+	WorkspaceNamePolicy TemplateWorkspaceNamePolicy `json:"workspace_name_policy"`
+	// MaxRunningWorkspaces is the maximum number of workspaces of the template
+	// that can run at the same time. Zero means no limit.
+	MaxRunningWorkspaces int32 `json:"max_running_workspaces"`
+	// PortForwardingPolicy restricts which agent ports of workspaces created
+	// from the template can be forwarded and shared.
+	PortForwardingPolicy TemplatePortForwardingPolicy `json:"port_forwarding_policy"`
+	// SessionRecording configures recording the web terminal sessions of
+	// workspaces created from the template.
+	SessionRecording TemplateSessionRecording `json:"session_recording"`
+	// BuildRetryPolicy configures retrying workspace builds of the template
+	// that failed transiently.
+	BuildRetryPolicy TemplateBuildRetryPolicy `json:"build_retry_policy"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -141,6 +165,72 @@ type TemplateAutostopRequirement struct {
 	Weeks int64 `json:"weeks"`
 }
 
+type TemplateMaintenanceWindow struct {
+	// Schedule is a weekly cron expression for the start of the window, with
+	// a timezone specified via a CRON_TZ prefix (otherwise UTC will be used).
+	// If empty, no maintenance happens.
+	Schedule string `json:"schedule"`
+	// DurationMillis is how long the window stays open after each start.
+	DurationMillis int64 `json:"duration_ms"`
+}
+
+type TemplateWorkspaceNamePolicy struct {
+	// Pattern is a regular expression that the names of new and renamed
+	// workspaces must match. If empty, any valid name is allowed.
+	Pattern string `json:"pattern"`
+	// Description explains the naming convention to users whose workspace
+	// name doesn't match the pattern.
+	Description string `json:"description"`
+}
+
+type TemplatePortForwardingPolicy struct {
+	// DenyByDefault denies forwarding and sharing agent ports with
+	// `coder port-forward` and port-based workspace app URLs. Attempts to
+	// forward a denied port are audited.
+	DenyByDefault bool `json:"deny_by_default"`
+	// AllowedPorts are the ports that may still be forwarded and shared when
+	// DenyByDefault is set.
+	AllowedPorts []uint16 `json:"allowed_ports"`
+}
+
+type TemplateSessionRecording struct {
+	// Enabled records the web terminal sessions of workspaces created from
+	// the template. Sessions are refused if the deployment has no session
+	// recording storage.
+	Enabled bool `json:"enabled"`
+	// RetentionMillis is how long recordings are kept for. Zero keeps them
+	// forever.
+	RetentionMillis int64 `json:"retention_ms"`
+}
+
+type TemplateBuildRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a workspace build,
+	// including the first one. Values below 2 disable retries. Builds that
+	// were canceled or failed because of the template are never retried.
+	MaxAttempts int32 `json:"max_attempts"`
+	// BackoffMillis is how long to wait before the first retry. The delay
+	// doubles with every attempt.
+	BackoffMillis int64 `json:"backoff_ms"`
+}
+
+// GenerateWorkspaceNameResponse is a workspace name that complies with the
+// naming policy of a template and isn't used by the user's workspaces.
+type GenerateWorkspaceNameResponse struct {
+	Name string `json:"name"`
+}
+
+// TemplateVisibility restricts who can see a template. Private templates are
+// only visible to template admins. Unlisted templates can be used by anyone
+// with access, but are only listed for template admins. Public templates are
+// visible to everyone with access.
+type TemplateVisibility string
+
+const (
+	TemplateVisibilityPrivate  TemplateVisibility = "private"
+	TemplateVisibilityUnlisted TemplateVisibility = "unlisted"
+	TemplateVisibilityPublic   TemplateVisibility = "public"
+)
+
 type TransitionStats struct {
 	P50 *int64 `example:"123"`
 	P95 *int64 `example:"146"`
@@ -247,6 +337,29 @@ type UpdateTemplateMeta struct {
 	// and must be explicitly granted to users or groups in the permissions settings
 	// of the template.
 	DisableEveryoneGroupAccess bool `json:"disable_everyone_group_access"`
+	// MaintenanceWindow if set, replaces the template's maintenance window.
+	// Pass an empty schedule to disable maintenance.
+	MaintenanceWindow *TemplateMaintenanceWindow `json:"maintenance_window,omitempty"`
+	// Visibility if set, changes who can see the template.
+	Visibility *TemplateVisibility `json:"visibility,omitempty" enums:"private,unlisted,public"`
+	// MaxBuildDurationMillis if set, changes how long workspace builds may run.
+	// Pass zero to remove the limit.
+	MaxBuildDurationMillis *int64 `json:"max_build_duration_ms,omitempty"`
+	// WorkspaceNamePolicy if set, replaces the template's workspace naming
+	// policy. Pass an empty pattern to allow any valid name.
+	WorkspaceNamePolicy *TemplateWorkspaceNamePolicy `json:"workspace_name_policy,omitempty"`
+	// MaxRunningWorkspaces if set, changes how many workspaces of the template
+	// can run at the same time. Pass zero to remove the limit.
+	MaxRunningWorkspaces *int32 `json:"max_running_workspaces,omitempty"`
+	// PortForwardingPolicy if set, replaces the template's port forwarding
+	// policy.
+	PortForwardingPolicy *TemplatePortForwardingPolicy `json:"port_forwarding_policy,omitempty"`
+	// SessionRecording if set, replaces the template's session recording
+	// settings.
+	SessionRecording *TemplateSessionRecording `json:"session_recording,omitempty"`
+	// BuildRetryPolicy if set, replaces the template's build retry policy.
+	// Pass zero max attempts to disable retries.
+	BuildRetryPolicy *TemplateBuildRetryPolicy `json:"build_retry_policy,omitempty"`
 }
 
 type TemplateExample struct {
@@ -378,6 +491,21 @@ func (c *Client) TemplateACL(ctx context.Context, templateID uuid.UUID) (Templat
 	return acl, json.NewDecoder(res.Body).Decode(&acl)
 }
 
+// GenerateWorkspaceName returns an unused workspace name for the user that
+// complies with the naming policy of the template.
+func (c *Client) GenerateWorkspaceName(ctx context.Context, templateID uuid.UUID) (GenerateWorkspaceNameResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/workspace-name", templateID), nil)
+	if err != nil {
+		return GenerateWorkspaceNameResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return GenerateWorkspaceNameResponse{}, ReadBodyAsError(res)
+	}
+	var resp GenerateWorkspaceNameResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // UpdateActiveTemplateVersion updates the active template version to the ID provided.
 // The template version must be attached to the template.
 func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.UUID, req UpdateActiveTemplateVersion) error {
@@ -484,3 +612,18 @@ func (c *Client) TemplateExamples(ctx context.Context, organizationID uuid.UUID)
 	var templateExamples []TemplateExample
 	return templateExamples, json.NewDecoder(res.Body).Decode(&templateExamples)
 }
+
+// TemplateExampleArchive downloads the tar archive of an example template
+// through coderd, from the examples embedded in coder or the registry mirror
+// of the deployment.
+func (c *Client) TemplateExampleArchive(ctx context.Context, organizationID uuid.UUID, exampleID string) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/templates/examples/%s/archive", organizationID, exampleID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
@@ -0,0 +1,152 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TemplateVariableValue is the value of a template variable managed through
+// the API. Values set on a template take precedence over the values its
+// versions were pushed with, and are used by builds without pushing a new
+// version. Values set on an organization are the defaults of its templates.
+type TemplateVariableValue struct {
+	Name string `json:"name"`
+	// Value is redacted for sensitive values.
+	Value     string    `json:"value"`
+	Sensitive bool      `json:"sensitive"`
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+type UpsertTemplateVariableValueRequest struct {
+	Value string `json:"value"`
+	// Sensitive values are encrypted at rest if database encryption is
+	// enabled, and are redacted by the API. Values of variables marked as
+	// sensitive in the template are always sensitive.
+	Sensitive bool `json:"sensitive"`
+}
+
+// ValidateTemplateVariableValue validates a value of a template variable
+// against its Terraform type. Values of lists, sets and tuples are JSON arrays,
+// and values of maps and objects are JSON objects. Errors never contain the
+// value, as it may be sensitive.
+func ValidateTemplateVariableValue(typ, value string) error {
+	switch {
+	case typ == "" || typ == "string" || typ == "any":
+		return nil
+	case typ == "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return xerrors.New("value must be a number")
+		}
+	case typ == "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return xerrors.New("value must be \"true\" or \"false\"")
+		}
+	case strings.HasPrefix(typ, "list") || strings.HasPrefix(typ, "set") || strings.HasPrefix(typ, "tuple"):
+		var list []any
+		if err := json.Unmarshal([]byte(value), &list); err != nil {
+			return xerrors.New("value must be a JSON array, e.g. [\"a\", \"b\"]")
+		}
+	case strings.HasPrefix(typ, "map") || strings.HasPrefix(typ, "object"):
+		var object map[string]any
+		if err := json.Unmarshal([]byte(value), &object); err != nil {
+			return xerrors.New("value must be a JSON object, e.g. {\"key\": \"value\"}")
+		}
+	}
+	return nil
+}
+
+// TemplateVariableValues returns the variable values set on a template.
+func (c *Client) TemplateVariableValues(ctx context.Context, template uuid.UUID) ([]TemplateVariableValue, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/variables", template), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var values []TemplateVariableValue
+	return values, json.NewDecoder(res.Body).Decode(&values)
+}
+
+// UpsertTemplateVariableValue sets the value of a variable of a template. It is
+// used by the next builds of the template.
+func (c *Client) UpsertTemplateVariableValue(ctx context.Context, template uuid.UUID, name string, req UpsertTemplateVariableValueRequest) (TemplateVariableValue, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/templates/%s/variables/%s", template, name), req)
+	if err != nil {
+		return TemplateVariableValue{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVariableValue{}, ReadBodyAsError(res)
+	}
+	var value TemplateVariableValue
+	return value, json.NewDecoder(res.Body).Decode(&value)
+}
+
+// DeleteTemplateVariableValue unsets the value of a variable of a template, so
+// builds use the value the active version was pushed with.
+func (c *Client) DeleteTemplateVariableValue(ctx context.Context, template uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templates/%s/variables/%s", template, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// OrganizationTemplateVariableValues returns the default template variable
+// values of an organization.
+func (c *Client) OrganizationTemplateVariableValues(ctx context.Context, organization uuid.UUID) ([]TemplateVariableValue, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/template-variables", organization), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var values []TemplateVariableValue
+	return values, json.NewDecoder(res.Body).Decode(&values)
+}
+
+// UpsertOrganizationTemplateVariableValue sets the default value of a template
+// variable for the templates of an organization. It is used by template
+// versions pushed without a value for the variable.
+func (c *Client) UpsertOrganizationTemplateVariableValue(ctx context.Context, organization uuid.UUID, name string, req UpsertTemplateVariableValueRequest) (TemplateVariableValue, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/template-variables/%s", organization, name), req)
+	if err != nil {
+		return TemplateVariableValue{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVariableValue{}, ReadBodyAsError(res)
+	}
+	var value TemplateVariableValue
+	return value, json.NewDecoder(res.Body).Decode(&value)
+}
+
+// DeleteOrganizationTemplateVariableValue unsets the default value of a
+// template variable of an organization.
+func (c *Client) DeleteOrganizationTemplateVariableValue(ctx context.Context, organization uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/organizations/%s/template-variables/%s", organization, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
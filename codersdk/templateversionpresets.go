@@ -0,0 +1,71 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// TemplateVersionPreset is a named set of parameter values of a template
+// version, e.g. "small" or "gpu". Selecting a preset when creating a workspace
+// uses its values for the parameters that aren't given explicitly.
+type TemplateVersionPreset struct {
+	Name       string                    `json:"name"`
+	Parameters []WorkspaceBuildParameter `json:"parameters"`
+	CreatedAt  time.Time                 `json:"created_at" format:"date-time"`
+}
+
+type CreateTemplateVersionPresetRequest struct {
+	Name string `json:"name" validate:"template_version_name,required"`
+	// Parameters must satisfy the validation of the parameters of the template
+	// version.
+	Parameters []WorkspaceBuildParameter `json:"parameters" validate:"required"`
+}
+
+// TemplateVersionPresets returns the presets of a template version.
+func (c *Client) TemplateVersionPresets(ctx context.Context, version uuid.UUID) ([]TemplateVersionPreset, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/presets", version), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var presets []TemplateVersionPreset
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
+
+// CreateTemplateVersionPreset creates a preset of a template version. The
+// import job of the version must have completed, so the values can be
+// validated against its parameters.
+func (c *Client) CreateTemplateVersionPreset(ctx context.Context, version uuid.UUID, req CreateTemplateVersionPresetRequest) (TemplateVersionPreset, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/presets", version), req)
+	if err != nil {
+		return TemplateVersionPreset{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateVersionPreset{}, ReadBodyAsError(res)
+	}
+	var preset TemplateVersionPreset
+	return preset, json.NewDecoder(res.Body).Decode(&preset)
+}
+
+// DeleteTemplateVersionPreset deletes a preset of a template version.
+func (c *Client) DeleteTemplateVersionPreset(ctx context.Context, version uuid.UUID, name string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/templateversions/%s/presets/%s", version, name), nil)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -30,10 +30,30 @@ type TemplateVersion struct {
 	Readme         string         `json:"readme"`
 	CreatedBy      MinimalUser    `json:"created_by"`
 	Archived       bool           `json:"archived"`
+	// Git is the git commit the version was created from, if it is known.
+	Git *TemplateVersionGitMetadata `json:"git,omitempty"`
 
 	Warnings []TemplateVersionWarning `json:"warnings,omitempty" enums:"DEPRECATED_PARAMETERS"`
 }
 
+// TemplateVersionGitMetadata describes the git commit a template version was
+// created from, so workspaces can be traced back to the source of their
+// template.
+type TemplateVersionGitMetadata struct {
+	CommitSHA string `json:"commit_sha" validate:"required,hexadecimal,max=64"`
+	// Branch is empty if the commit was pushed from a detached HEAD.
+	Branch string `json:"branch,omitempty" validate:"max=255"`
+	Tag    string `json:"tag,omitempty" validate:"max=255"`
+}
+
+// ShortCommitSHA returns the abbreviated commit SHA, as shown by git.
+func (m TemplateVersionGitMetadata) ShortCommitSHA() string {
+	if len(m.CommitSHA) > 7 {
+		return m.CommitSHA[:7]
+	}
+	return m.CommitSHA
+}
+
 type TemplateVersionExternalAuth struct {
 	ID              string `json:"id"`
 	Type            string `json:"type"`
@@ -68,6 +88,10 @@ type TemplateVersionParameter struct {
 	ValidationMonotonic  ValidationMonotonicOrder         `json:"validation_monotonic,omitempty" enums:"increasing,decreasing"`
 	Required             bool                             `json:"required"`
 	Ephemeral            bool                             `json:"ephemeral"`
+	// DisplayCondition is an expression over the values of other parameters,
+	// e.g. `machine == "gpu"`. The parameter is only displayed and validated
+	// if the condition is met. See ParameterDisplayCondition.
+	DisplayCondition string `json:"display_condition,omitempty"`
 }
 
 // TemplateVersionParameterOption represents a selectable option for a template parameter.
@@ -82,13 +106,62 @@ type TemplateVersionParameterOption struct {
 type TemplateVersionVariable struct {
 	Name         string `json:"name"`
 	Description  string `json:"description"`
-	Type         string `json:"type" enums:"string,number,bool"`
+	Type         string `json:"type" enums:"string,number,bool,list(string)"`
 	Value        string `json:"value"`
 	DefaultValue string `json:"default_value"`
 	Required     bool   `json:"required"`
 	Sensitive    bool   `json:"sensitive"`
 }
 
+type TemplateVersionFileStatus string
+
+const (
+	TemplateVersionFileAdded    TemplateVersionFileStatus = "added"
+	TemplateVersionFileRemoved  TemplateVersionFileStatus = "removed"
+	TemplateVersionFileModified TemplateVersionFileStatus = "modified"
+)
+
+// TemplateVersionFileDiff is a file that changed between two template
+// versions.
+type TemplateVersionFileDiff struct {
+	Path   string                    `json:"path"`
+	Status TemplateVersionFileStatus `json:"status" enums:"added,removed,modified"`
+	Binary bool                      `json:"binary"`
+	// Diff is the unified diff of the file. It is empty for binary files and
+	// files larger than 1 MiB.
+	Diff string `json:"diff"`
+}
+
+// TemplateVersionParameterChange is a rich parameter that exists in both
+// template versions, but differs between them.
+type TemplateVersionParameterChange struct {
+	Name string                   `json:"name"`
+	Old  TemplateVersionParameter `json:"old"`
+	New  TemplateVersionParameter `json:"new"`
+}
+
+// TemplateVersionMetadataChange is a provisioner metadata field that differs
+// between two template versions.
+type TemplateVersionMetadataChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TemplateVersionDiff is the structural difference between a base template
+// version and a target template version.
+type TemplateVersionDiff struct {
+	BaseVersionID   uuid.UUID `json:"base_version_id" format:"uuid"`
+	TargetVersionID uuid.UUID `json:"target_version_id" format:"uuid"`
+	// Files is null if the user is not allowed to read the source of both
+	// template versions.
+	Files             []TemplateVersionFileDiff        `json:"files"`
+	AddedParameters   []TemplateVersionParameter       `json:"added_parameters"`
+	RemovedParameters []TemplateVersionParameter       `json:"removed_parameters"`
+	ChangedParameters []TemplateVersionParameterChange `json:"changed_parameters"`
+	Metadata          []TemplateVersionMetadataChange  `json:"metadata"`
+}
+
 type PatchTemplateVersionRequest struct {
 	Name    string  `json:"name" validate:"omitempty,template_version_name"`
 	Message *string `json:"message,omitempty" validate:"omitempty,lt=1048577"`
@@ -163,6 +236,40 @@ func (c *Client) TemplateVersionResources(ctx context.Context, version uuid.UUID
 	return resources, json.NewDecoder(res.Body).Decode(&resources)
 }
 
+// TemplateVersionDiff returns what changed from the base template version to
+// the given template version. If base is uuid.Nil, the active version of the
+// template is used as the base.
+func (c *Client) TemplateVersionDiff(ctx context.Context, version, base uuid.UUID) (TemplateVersionDiff, error) {
+	path := fmt.Sprintf("/api/v2/templateversions/%s/diff", version)
+	if base != uuid.Nil {
+		path += "?base=" + base.String()
+	}
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return TemplateVersionDiff{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionDiff{}, ReadBodyAsError(res)
+	}
+	var diff TemplateVersionDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
+
+// TemplateVersionArchive returns the tar archive a template version was created
+// from, exactly as it was uploaded.
+func (c *Client) TemplateVersionArchive(ctx context.Context, version uuid.UUID) ([]byte, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/archive", version), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	return io.ReadAll(res.Body)
+}
+
 // TemplateVersionVariables returns resources a template version variables.
 func (c *Client) TemplateVersionVariables(ctx context.Context, version uuid.UUID) ([]TemplateVersionVariable, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/variables", version), nil)
@@ -0,0 +1,310 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Binary WebAuthn values, like challenges, credential IDs and signatures, are
+// sent as unpadded base64url strings, which is how browsers serialize them in
+// PublicKeyCredential.toJSON().
+
+// Passkey is a WebAuthn credential registered as a second factor for password
+// logins.
+type Passkey struct {
+	ID         uuid.UUID  `json:"id" format:"uuid"`
+	UserID     uuid.UUID  `json:"user_id" format:"uuid"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at" format:"date-time"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" format:"date-time"`
+}
+
+// PasskeyRegistration is returned when a user starts registering a passkey.
+// Options are passed to navigator.credentials.create() in the browser.
+type PasskeyRegistration struct {
+	ChallengeID uuid.UUID              `json:"challenge_id" format:"uuid"`
+	ExpiresAt   time.Time              `json:"expires_at" format:"date-time"`
+	Options     PasskeyCreationOptions `json:"options"`
+}
+
+// PasskeyCreationOptions are the PublicKeyCredentialCreationOptions from the
+// WebAuthn specification.
+type PasskeyCreationOptions struct {
+	Challenge              string                        `json:"challenge"`
+	RelyingParty           PasskeyRelyingParty           `json:"rp"`
+	User                   PasskeyUser                   `json:"user"`
+	PubKeyCredParams       []PasskeyCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                         `json:"timeout"`
+	ExcludeCredentials     []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                        `json:"attestation"`
+}
+
+type PasskeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type PasskeyUser struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type PasskeyCredentialParameter struct {
+	Type string `json:"type"`
+	// Alg is a COSE algorithm identifier.
+	Alg int64 `json:"alg"`
+}
+
+type PasskeyCredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type PasskeyAuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// PasskeyRequestOptions are the PublicKeyCredentialRequestOptions from the
+// WebAuthn specification. They are passed to navigator.credentials.get() in
+// the browser.
+type PasskeyRequestOptions struct {
+	Challenge        string                        `json:"challenge"`
+	RelyingPartyID   string                        `json:"rpId"`
+	Timeout          int64                         `json:"timeout"`
+	AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                        `json:"userVerification"`
+}
+
+// CreatePasskeyRequest finishes the registration of a passkey with the
+// response of the authenticator.
+type CreatePasskeyRequest struct {
+	ChallengeID       uuid.UUID `json:"challenge_id" validate:"required" format:"uuid"`
+	Name              string    `json:"name" validate:"required"`
+	ClientDataJSON    string    `json:"client_data_json" validate:"required"`
+	AttestationObject string    `json:"attestation_object" validate:"required"`
+}
+
+// PasskeyAssertion is the response of an authenticator to a login challenge.
+type PasskeyAssertion struct {
+	CredentialID      string `json:"credential_id" validate:"required"`
+	ClientDataJSON    string `json:"client_data_json" validate:"required"`
+	AuthenticatorData string `json:"authenticator_data" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+}
+
+// SecondFactorChallenge is returned instead of a session token when a user
+// with a passkey logs in with their password.
+type SecondFactorChallenge struct {
+	ChallengeID uuid.UUID             `json:"challenge_id" format:"uuid"`
+	ExpiresAt   time.Time             `json:"expires_at" format:"date-time"`
+	Options     PasskeyRequestOptions `json:"options"`
+}
+
+// LoginWithSecondFactorRequest answers a SecondFactorChallenge with either a
+// passkey assertion or a recovery code.
+type LoginWithSecondFactorRequest struct {
+	ChallengeID  uuid.UUID         `json:"challenge_id" validate:"required" format:"uuid"`
+	Passkey      *PasskeyAssertion `json:"passkey,omitempty"`
+	RecoveryCode string            `json:"recovery_code,omitempty"`
+}
+
+// RecoveryCodes are shown once, when they are generated. Each code can be used
+// once instead of a passkey.
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// UserTwoFactor is the second factor status of a user.
+type UserTwoFactor struct {
+	// Enabled is true if the user has registered a passkey.
+	Enabled bool `json:"enabled"`
+	// Required is true if a policy requires the user to use a second factor.
+	Required bool `json:"required"`
+	// EnforceAfter is when the earliest policy that applies to the user is
+	// enforced.
+	EnforceAfter           *time.Time `json:"enforce_after,omitempty" format:"date-time"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorPolicy requires members of a site role to use a second factor for
+// password logins.
+type TwoFactorPolicy struct {
+	ID   uuid.UUID `json:"id" format:"uuid"`
+	Role string    `json:"role"`
+	// EnforceAfter is when password logins without a second factor start
+	// being rejected, which gives users time to register a passkey.
+	EnforceAfter time.Time `json:"enforce_after" format:"date-time"`
+	CreatedAt    time.Time `json:"created_at" format:"date-time"`
+	UpdatedAt    time.Time `json:"updated_at" format:"date-time"`
+}
+
+type UpsertTwoFactorPolicyRequest struct {
+	EnforceAfter time.Time `json:"enforce_after" format:"date-time"`
+}
+
+// Passkeys returns the passkeys of a user.
+func (c *Client) Passkeys(ctx context.Context, user string) ([]Passkey, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/passkeys", user), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var passkeys []Passkey
+	return passkeys, json.NewDecoder(res.Body).Decode(&passkeys)
+}
+
+// StartPasskeyRegistration returns the options to create a passkey with.
+// Users can only register passkeys for themselves.
+func (c *Client) StartPasskeyRegistration(ctx context.Context, user string) (PasskeyRegistration, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/passkeys/challenge", user), nil)
+	if err != nil {
+		return PasskeyRegistration{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return PasskeyRegistration{}, ReadBodyAsError(res)
+	}
+	var resp PasskeyRegistration
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// CreatePasskey finishes the registration of a passkey.
+func (c *Client) CreatePasskey(ctx context.Context, user string, req CreatePasskeyRequest) (Passkey, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/passkeys", user), req)
+	if err != nil {
+		return Passkey{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Passkey{}, ReadBodyAsError(res)
+	}
+	var passkey Passkey
+	return passkey, json.NewDecoder(res.Body).Decode(&passkey)
+}
+
+// DeletePasskey deletes a passkey of a user.
+func (c *Client) DeletePasskey(ctx context.Context, user string, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/passkeys/%s", user, id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UserTwoFactor returns the second factor status of a user.
+func (c *Client) UserTwoFactor(ctx context.Context, user string) (UserTwoFactor, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/two-factor", user), nil)
+	if err != nil {
+		return UserTwoFactor{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserTwoFactor{}, ReadBodyAsError(res)
+	}
+	var resp UserTwoFactor
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user. The old codes
+// stop working.
+func (c *Client) RegenerateRecoveryCodes(ctx context.Context, user string) (RecoveryCodes, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/two-factor/recovery-codes", user), nil)
+	if err != nil {
+		return RecoveryCodes{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return RecoveryCodes{}, ReadBodyAsError(res)
+	}
+	var resp RecoveryCodes
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginWithSecondFactor finishes a password login that returned a
+// SecondFactorChallenge. Call `SetSessionToken()` to apply the newly acquired
+// token to the client.
+func (c *Client) LoginWithSecondFactor(ctx context.Context, req LoginWithSecondFactorRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/second-factor", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// LoginWithPasskeyEnrollment finishes a password login that returned a
+// PasskeyEnrollment by registering the user's first passkey. Call
+// `SetSessionToken()` to apply the newly acquired token to the client.
+func (c *Client) LoginWithPasskeyEnrollment(ctx context.Context, req CreatePasskeyRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/login/passkey-enrollment", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// TwoFactorPolicies returns the site roles that must use a second factor.
+func (c *Client) TwoFactorPolicies(ctx context.Context) ([]TwoFactorPolicy, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/two-factor/policies", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var policies []TwoFactorPolicy
+	return policies, json.NewDecoder(res.Body).Decode(&policies)
+}
+
+// UpsertTwoFactorPolicy requires members of a site role to use a second
+// factor.
+func (c *Client) UpsertTwoFactorPolicy(ctx context.Context, role string, req UpsertTwoFactorPolicyRequest) (TwoFactorPolicy, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/two-factor/policies/%s", role), req)
+	if err != nil {
+		return TwoFactorPolicy{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TwoFactorPolicy{}, ReadBodyAsError(res)
+	}
+	var policy TwoFactorPolicy
+	return policy, json.NewDecoder(res.Body).Decode(&policy)
+}
+
+// DeleteTwoFactorPolicy stops requiring a second factor for a site role.
+func (c *Client) DeleteTwoFactorPolicy(ctx context.Context, role string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/two-factor/policies/%s", role), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -64,6 +64,46 @@ type GetUsersResponse struct {
 	Count int    `json:"count"`
 }
 
+// InactiveUser is a user that has not used Coder since the cutoff of an
+// inactive users report.
+type InactiveUser struct {
+	ID         uuid.UUID  `json:"id" format:"uuid"`
+	Username   string     `json:"username"`
+	Email      string     `json:"email" format:"email"`
+	AvatarURL  string     `json:"avatar_url" format:"uri"`
+	Status     UserStatus `json:"status" enums:"active,dormant"`
+	LastSeenAt time.Time  `json:"last_seen_at" format:"date-time"`
+	// LastActivity is the most recent API activity of the user, if it is still
+	// retained. Activity is kept for 90 days.
+	LastActivity *UserAPIActivity `json:"last_activity,omitempty"`
+}
+
+// UserAPIActivity is the API activity of a user within an hour.
+type UserAPIActivity struct {
+	// Category is the API the user made requests to, e.g. "workspaces".
+	Category string `json:"category"`
+	// WorkspaceID is set if the requests were about a workspace.
+	WorkspaceID *uuid.UUID `json:"workspace_id,omitempty" format:"uuid"`
+	Hour        time.Time  `json:"hour" format:"date-time"`
+}
+
+// InactiveUsersResponse lists the users that have not used Coder since
+// InactiveSince, least recently seen first.
+type InactiveUsersResponse struct {
+	InactiveSince time.Time      `json:"inactive_since" format:"date-time"`
+	Users         []InactiveUser `json:"users"`
+}
+
+// UserLockout is a user that can't sign in with a password until LockedUntil
+// because of too many failed attempts.
+type UserLockout struct {
+	UserID         uuid.UUID `json:"user_id" format:"uuid"`
+	Username       string    `json:"username"`
+	FailedAttempts int32     `json:"failed_attempts"`
+	LockedAt       time.Time `json:"locked_at" format:"date-time"`
+	LockedUntil    time.Time `json:"locked_until" format:"date-time"`
+}
+
 // @typescript-ignore LicensorTrialRequest
 type LicensorTrialRequest struct {
 	DeploymentID string `json:"deployment_id"`
@@ -126,6 +166,18 @@ type UpdateUserAppearanceSettingsRequest struct {
 	ThemePreference string `json:"theme_preference" validate:"required"`
 }
 
+// UserIPAllowlist limits the addresses the API keys of a user can be used
+// from.
+type UserIPAllowlist struct {
+	// IPAllowlist holds CIDR ranges. If empty, the keys of the user can be used
+	// from any address.
+	IPAllowlist []string `json:"ip_allowlist"`
+}
+
+type UpdateUserIPAllowlistRequest struct {
+	IPAllowlist []string `json:"ip_allowlist"`
+}
+
 type UpdateUserPasswordRequest struct {
 	OldPassword string `json:"old_password" validate:""`
 	Password    string `json:"password" validate:"required"`
@@ -186,7 +238,22 @@ type LoginWithPasswordRequest struct {
 
 // LoginWithPasswordResponse contains a session token for the newly authenticated user.
 type LoginWithPasswordResponse struct {
-	SessionToken string `json:"session_token" validate:"required"`
+	// SessionToken is empty when a second factor is required.
+	SessionToken string `json:"session_token"`
+	// SecondFactor is set instead of the session token when the user has a
+	// passkey. The login is finished with LoginWithSecondFactor.
+	SecondFactor *SecondFactorChallenge `json:"second_factor,omitempty"`
+	// PasskeyEnrollment is set instead of the session token when a policy
+	// requires the user to have a second factor, but they have no passkey.
+	// The login is finished by registering one with LoginWithPasskeyEnrollment.
+	PasskeyEnrollment *PasskeyRegistration `json:"passkey_enrollment,omitempty"`
+}
+
+// LoginWithLDAPRequest enables callers to authenticate with the username and
+// password of their LDAP account.
+type LoginWithLDAPRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 type OAuthConversionResponse struct {
@@ -205,6 +272,7 @@ type AuthMethods struct {
 	Password AuthMethod     `json:"password"`
 	Github   AuthMethod     `json:"github"`
 	OIDC     OIDCAuthMethod `json:"oidc"`
+	LDAP     AuthMethod     `json:"ldap"`
 }
 
 type AuthMethod struct {
@@ -340,6 +408,35 @@ func (c *Client) UpdateUserAppearanceSettings(ctx context.Context, user string,
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// UserIPAllowlist returns the IP allowlist of a user.
+func (c *Client) UserIPAllowlist(ctx context.Context, user string) (UserIPAllowlist, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/ip-allowlist", user), nil)
+	if err != nil {
+		return UserIPAllowlist{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserIPAllowlist{}, ReadBodyAsError(res)
+	}
+	var resp UserIPAllowlist
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateUserIPAllowlist sets the IP allowlist of a user. Only admins can
+// update it.
+func (c *Client) UpdateUserIPAllowlist(ctx context.Context, user string, req UpdateUserIPAllowlistRequest) (UserIPAllowlist, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/ip-allowlist", user), req)
+	if err != nil {
+		return UserIPAllowlist{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return UserIPAllowlist{}, ReadBodyAsError(res)
+	}
+	var resp UserIPAllowlist
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // UpdateUserPassword updates a user password.
 // It calls PUT /users/{user}/password
 func (c *Client) UpdateUserPassword(ctx context.Context, user string, req UpdateUserPasswordRequest) error {
@@ -417,6 +514,26 @@ func (c *Client) LoginWithPassword(ctx context.Context, req LoginWithPasswordReq
 	return resp, nil
 }
 
+// LoginWithLDAP creates a session token authenticating with the username and
+// password of an LDAP account. Call `SetSessionToken()` to apply the newly
+// acquired token to the client.
+func (c *Client) LoginWithLDAP(ctx context.Context, req LoginWithLDAPRequest) (LoginWithPasswordResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/users/ldap/login", req)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return LoginWithPasswordResponse{}, ReadBodyAsError(res)
+	}
+	var resp LoginWithPasswordResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return LoginWithPasswordResponse{}, err
+	}
+	return resp, nil
+}
+
 // ConvertLoginType will send a request to convert the user from password
 // based authentication to oauth based. The response has the oauth state code
 // to use in the oauth flow.
@@ -531,6 +648,52 @@ func (c *Client) Users(ctx context.Context, req UsersRequest) (GetUsersResponse,
 	return usersRes, json.NewDecoder(res.Body).Decode(&usersRes)
 }
 
+// InactiveUsers returns the users that have not used Coder for the given
+// number of days.
+func (c *Client) InactiveUsers(ctx context.Context, days int) (InactiveUsersResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/inactive?days=%d", days), nil)
+	if err != nil {
+		return InactiveUsersResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return InactiveUsersResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp InactiveUsersResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UserLockouts returns the users that are currently locked out.
+func (c *Client) UserLockouts(ctx context.Context) ([]UserLockout, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/lockouts", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var lockouts []UserLockout
+	return lockouts, json.NewDecoder(res.Body).Decode(&lockouts)
+}
+
+// UnlockUser clears the lockout and failed password logins of a user.
+func (c *Client) UnlockUser(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/lockout", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // OrganizationsByUser returns all organizations the user is a member of.
 func (c *Client) OrganizationsByUser(ctx context.Context, user string) ([]Organization, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/organizations", user), nil)
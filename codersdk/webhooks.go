@@ -0,0 +1,226 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// WebhookEventHeader is the header containing the event name of a
+	// webhook delivery.
+	WebhookEventHeader = "X-Coder-Webhook-Event"
+	// WebhookDeliveryHeader is the header containing the unique ID of a
+	// webhook delivery. Receivers can use it to deduplicate retries.
+	WebhookDeliveryHeader = "X-Coder-Webhook-Delivery"
+	// WebhookSignatureHeader is the header containing the HMAC-SHA256
+	// signature of the request body, keyed with the webhook secret and
+	// formatted as "sha256=<hex>".
+	WebhookSignatureHeader = "X-Coder-Webhook-Signature"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventWorkspaceBuildStarted   WebhookEvent = "workspace_build.started"
+	WebhookEventWorkspaceBuildSucceeded WebhookEvent = "workspace_build.succeeded"
+	WebhookEventWorkspaceBuildFailed    WebhookEvent = "workspace_build.failed"
+	WebhookEventUserCreated             WebhookEvent = "user.created"
+	WebhookEventTemplateUpdated         WebhookEvent = "template.updated"
+	WebhookEventWorkspaceMaintenance    WebhookEvent = "workspace.maintenance"
+	WebhookEventWorkspaceDormant        WebhookEvent = "workspace.dormant"
+)
+
+// WebhookEvents is every event a webhook can subscribe to.
+var WebhookEvents = []WebhookEvent{
+	WebhookEventWorkspaceBuildStarted,
+	WebhookEventWorkspaceBuildSucceeded,
+	WebhookEventWorkspaceBuildFailed,
+	WebhookEventUserCreated,
+	WebhookEventTemplateUpdated,
+	WebhookEventWorkspaceMaintenance,
+	WebhookEventWorkspaceDormant,
+}
+
+func (e WebhookEvent) Valid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	ID     uuid.UUID      `json:"id" format:"uuid"`
+	URL    string         `json:"url"`
+	Events []WebhookEvent `json:"events"`
+	// Secret is used to sign deliveries. It is only returned when the
+	// webhook is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string         `json:"url" validate:"required,url"`
+	Events []WebhookEvent `json:"events" validate:"required,min=1"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+type WebhookDelivery struct {
+	ID        uuid.UUID             `json:"id" format:"uuid"`
+	WebhookID uuid.UUID             `json:"webhook_id" format:"uuid"`
+	Event     WebhookEvent          `json:"event"`
+	Status    WebhookDeliveryStatus `json:"status" enums:"pending,delivered,failed"`
+	Attempts  int32                 `json:"attempts"`
+	// StatusCode is the HTTP status code of the last attempt. It is zero if
+	// the endpoint could not be reached.
+	StatusCode    int32      `json:"status_code"`
+	LastError     string     `json:"last_error"`
+	CreatedAt     time.Time  `json:"created_at" format:"date-time"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty" format:"date-time"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" format:"date-time"`
+}
+
+// WebhookPayload is the JSON body sent to webhook endpoints.
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id" format:"uuid"`
+	Event     WebhookEvent    `json:"event"`
+	Timestamp time.Time       `json:"timestamp" format:"date-time"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookWorkspaceBuildData is the payload data for workspace build events.
+type WebhookWorkspaceBuildData struct {
+	WorkspaceID   uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName string              `json:"workspace_name"`
+	OwnerID       uuid.UUID           `json:"owner_id" format:"uuid"`
+	TemplateID    uuid.UUID           `json:"template_id" format:"uuid"`
+	BuildID       uuid.UUID           `json:"build_id" format:"uuid"`
+	BuildNumber   int32               `json:"build_number"`
+	Transition    WorkspaceTransition `json:"transition"`
+	// Error is only set for failed builds.
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookUserData is the payload data for user events.
+type WebhookUserData struct {
+	ID       uuid.UUID `json:"id" format:"uuid"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+}
+
+// WebhookTemplateData is the payload data for template events.
+type WebhookTemplateData struct {
+	ID              uuid.UUID `json:"id" format:"uuid"`
+	OrganizationID  uuid.UUID `json:"organization_id" format:"uuid"`
+	Name            string    `json:"name"`
+	ActiveVersionID uuid.UUID `json:"active_version_id" format:"uuid"`
+}
+
+// WebhookWorkspaceMaintenanceData is the payload data sent when a template
+// maintenance window stops a workspace to rebuild it on the active version.
+type WebhookWorkspaceMaintenanceData struct {
+	WorkspaceID     uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName   string    `json:"workspace_name"`
+	OwnerID         uuid.UUID `json:"owner_id" format:"uuid"`
+	TemplateID      uuid.UUID `json:"template_id" format:"uuid"`
+	ActiveVersionID uuid.UUID `json:"active_version_id" format:"uuid"`
+}
+
+// WebhookWorkspaceDormantData is the payload data sent when a workspace is
+// marked dormant for inactivity, so that its owner can be notified.
+type WebhookWorkspaceDormantData struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	OwnerID       uuid.UUID `json:"owner_id" format:"uuid"`
+	OwnerUsername string    `json:"owner_username"`
+	OwnerEmail    string    `json:"owner_email"`
+	TemplateID    uuid.UUID `json:"template_id" format:"uuid"`
+	DormantAt     time.Time `json:"dormant_at" format:"date-time"`
+	// DeletingAt is when the workspace will be deleted, if the template
+	// deletes dormant workspaces.
+	DeletingAt *time.Time `json:"deleting_at,omitempty" format:"date-time"`
+}
+
+// Webhooks returns all registered webhooks.
+func (c *Client) Webhooks(ctx context.Context) ([]Webhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/webhooks", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var webhooks []Webhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+// CreateWebhook registers an HTTPS endpoint to receive events. The returned
+// webhook includes the signing secret, which cannot be retrieved again.
+func (c *Client) CreateWebhook(ctx context.Context, req CreateWebhookRequest) (Webhook, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/webhooks", req)
+	if err != nil {
+		return Webhook{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Webhook{}, ReadBodyAsError(res)
+	}
+	var webhook Webhook
+	return webhook, json.NewDecoder(res.Body).Decode(&webhook)
+}
+
+// DeleteWebhook removes a webhook and its delivery history.
+func (c *Client) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/webhooks/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the most recent deliveries for a webhook.
+func (c *Client) WebhookDeliveries(ctx context.Context, id uuid.UUID) ([]WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/webhooks/%s/deliveries", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var deliveries []WebhookDelivery
+	return deliveries, json.NewDecoder(res.Body).Decode(&deliveries)
+}
+
+// ReplayWebhookDelivery queues a new delivery with the same payload as a
+// failed delivery.
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (WebhookDelivery, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/webhooks/%s/deliveries/%s/replay", webhookID, deliveryID), nil)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WebhookDelivery{}, ReadBodyAsError(res)
+	}
+	var delivery WebhookDelivery
+	return delivery, json.NewDecoder(res.Body).Decode(&delivery)
+}
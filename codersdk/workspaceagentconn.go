@@ -9,17 +9,22 @@ import (
 	"net"
 	"net/http"
 	"net/netip"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/yamux"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
 	"tailscale.com/ipn/ipnstate"
+	"tailscale.com/net/netcheck"
 	"tailscale.com/net/speedtest"
 
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -44,10 +49,13 @@ const (
 	// WorkspaceAgentHTTPAPIServerPort serves a HTTP server with endpoints for e.g.
 	// gathering agent statistics.
 	WorkspaceAgentHTTPAPIServerPort = 4
+	// WorkspaceAgentReconnectingPTYMuxPort serves reconnecting PTY sessions
+	// multiplexed over a single connection.
+	WorkspaceAgentReconnectingPTYMuxPort = 5
 
 	// WorkspaceAgentMinimumListeningPort is the minimum port that the listening-ports
 	// endpoint will return to the client, and the minimum port that is accepted
-	// by the proxy applications endpoint. Coder consumes ports 1-4 at the
+	// by the proxy applications endpoint. Coder consumes ports 1-5 at the
 	// moment, and we reserve some extra ports for future use. Port 9 and up are
 	// available for the user.
 	//
@@ -144,6 +152,11 @@ func NewWorkspaceAgentConn(conn *tailnet.Conn, opts WorkspaceAgentConnOptions) *
 type WorkspaceAgentConn struct {
 	*tailnet.Conn
 	opts WorkspaceAgentConnOptions
+
+	// ptyMux carries the reconnecting PTY sessions to the agent. It is dialed
+	// with the first session, and again once it is closed.
+	ptyMuxMutex sync.Mutex
+	ptyMux      *yamux.Session
 }
 
 // @typescript-ignore WorkspaceAgentConnOptions
@@ -179,6 +192,99 @@ func (c *WorkspaceAgentConn) Ping(ctx context.Context) (time.Duration, bool, *ip
 	return c.Conn.Ping(ctx, c.agentAddress())
 }
 
+// WorkspaceAgentPingStats summarizes the pings sent to a workspace agent over
+// a sampling window.
+type WorkspaceAgentPingStats struct {
+	Sent     int `json:"sent"`
+	Received int `json:"received"`
+	// PacketLoss is the percentage of pings that were not answered.
+	PacketLoss   float64 `json:"packet_loss"`
+	MinLatencyMS float64 `json:"min_latency_ms"`
+	AvgLatencyMS float64 `json:"avg_latency_ms"`
+	MaxLatencyMS float64 `json:"max_latency_ms"`
+	// P2P is whether the last answered ping was sent directly to the agent,
+	// rather than relayed through DERP.
+	P2P bool `json:"p2p"`
+	// Endpoint is the address of the agent the last direct ping was sent to.
+	Endpoint string `json:"endpoint,omitempty"`
+	// DERPRegionID is the region that relayed the last relayed ping.
+	DERPRegionID int `json:"derp_region_id,omitempty"`
+	// DERPLatencies are the latencies from this end of the connection to
+	// every DERP region, fastest first.
+	DERPLatencies []WorkspaceAgentDERPLatency `json:"derp_latencies"`
+
+	totalLatency time.Duration
+}
+
+// AddPing records a ping. A nil pong records a ping that was not answered.
+func (s *WorkspaceAgentPingStats) AddPing(latency time.Duration, p2p bool, pong *ipnstate.PingResult) {
+	s.Sent++
+	if pong != nil {
+		ms := float64(latency) / float64(time.Millisecond)
+		if s.Received == 0 || ms < s.MinLatencyMS {
+			s.MinLatencyMS = ms
+		}
+		if ms > s.MaxLatencyMS {
+			s.MaxLatencyMS = ms
+		}
+		s.Received++
+		s.totalLatency += latency
+		s.AvgLatencyMS = float64(s.totalLatency) / float64(time.Millisecond) / float64(s.Received)
+
+		s.P2P = p2p
+		if p2p {
+			s.Endpoint = pong.Endpoint
+		} else {
+			s.DERPRegionID = pong.DERPRegionID
+		}
+	}
+	s.PacketLoss = float64(s.Sent-s.Received) / float64(s.Sent) * 100
+}
+
+// WorkspaceAgentDERPLatency is the latency to a DERP region.
+type WorkspaceAgentDERPLatency struct {
+	RegionID   int     `json:"region_id"`
+	RegionName string  `json:"region_name"`
+	LatencyMS  float64 `json:"latency_ms"`
+}
+
+// DERPLatencies returns the latencies from this end of the connection to
+// every DERP region, fastest first. They are measured periodically, so they
+// may be empty right after connecting.
+func (c *WorkspaceAgentConn) DERPLatencies() []WorkspaceAgentDERPLatency {
+	derpMap := c.DERPMap()
+	latencies := map[int]float64{}
+	// Latencies are keyed by region ID and IP version, e.g. "1-v4".
+	for key, seconds := range c.Node().DERPLatency {
+		regionID, err := strconv.Atoi(strings.Split(key, "-")[0])
+		if err != nil {
+			continue
+		}
+		ms := seconds * 1000
+		if prev, ok := latencies[regionID]; !ok || ms < prev {
+			latencies[regionID] = ms
+		}
+	}
+
+	out := make([]WorkspaceAgentDERPLatency, 0, len(latencies))
+	for regionID, ms := range latencies {
+		latency := WorkspaceAgentDERPLatency{
+			RegionID:  regionID,
+			LatencyMS: ms,
+		}
+		if derpMap != nil {
+			if region, ok := derpMap.Regions[regionID]; ok {
+				latency.RegionName = region.RegionName
+			}
+		}
+		out = append(out, latency)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].LatencyMS < out[j].LatencyMS
+	})
+	return out
+}
+
 // Close ends the connection to the workspace agent.
 func (c *WorkspaceAgentConn) Close() error {
 	var cerr error
@@ -188,6 +294,11 @@ func (c *WorkspaceAgentConn) Close() error {
 			return nil
 		}
 	}
+	c.ptyMuxMutex.Lock()
+	if c.ptyMux != nil {
+		_ = c.ptyMux.Close()
+	}
+	c.ptyMuxMutex.Unlock()
 	if cerr != nil {
 		return multierror.Append(cerr, c.Conn.Close())
 	}
@@ -223,7 +334,7 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 		return nil, xerrors.Errorf("workspace agent not reachable in time: %v", ctx.Err())
 	}
 
-	conn, err := c.Conn.DialContextTCP(ctx, netip.AddrPortFrom(c.agentAddress(), WorkspaceAgentReconnectingPTYPort))
+	conn, err := c.reconnectingPTYConn(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -248,6 +359,44 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	return conn, nil
 }
 
+// reconnectingPTYConn opens a stream for a reconnecting PTY session on the
+// multiplexed connection to the agent. Agents that don't multiplex sessions
+// are dialed once per session instead.
+func (c *WorkspaceAgentConn) reconnectingPTYConn(ctx context.Context) (net.Conn, error) {
+	c.ptyMuxMutex.Lock()
+	defer c.ptyMuxMutex.Unlock()
+
+	if c.ptyMux == nil || c.ptyMux.IsClosed() {
+		conn, err := c.Conn.DialContextTCP(ctx, netip.AddrPortFrom(c.agentAddress(), WorkspaceAgentReconnectingPTYMuxPort))
+		if err != nil {
+			return c.Conn.DialContextTCP(ctx, netip.AddrPortFrom(c.agentAddress(), WorkspaceAgentReconnectingPTYPort))
+		}
+		session, err := yamux.Client(conn, ReconnectingPTYMuxConfig())
+		if err != nil {
+			_ = conn.Close()
+			return nil, xerrors.Errorf("multiplex reconnecting pty: %w", err)
+		}
+		c.ptyMux = session
+	}
+	stream, err := c.ptyMux.OpenStream()
+	if err != nil {
+		return nil, xerrors.Errorf("open reconnecting pty stream: %w", err)
+	}
+	return stream, nil
+}
+
+// ReconnectingPTYMuxConfig configures both ends of the multiplexed connection
+// for reconnecting PTY sessions. Every session has its own flow control window,
+// so a client that stops reading one session does not stall the others.
+func ReconnectingPTYMuxConfig() *yamux.Config {
+	config := yamux.DefaultConfig()
+	config.LogOutput = io.Discard
+	// Terminal output is bursty, so let a session buffer more than the 256 KiB
+	// default before the PTY is blocked on the client.
+	config.MaxStreamWindowSize = 1024 * 1024
+	return config
+}
+
 // SSH pipes the SSH protocol over the returned net.Conn.
 // This connects to the built-in SSH server in the workspace agent.
 func (c *WorkspaceAgentConn) SSH(ctx context.Context) (*gonet.TCPConn, error) {
@@ -362,6 +511,102 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// WorkspaceAgentNetcheckResponse is a netcheck run by the agent from inside
+// the workspace.
+type WorkspaceAgentNetcheckResponse struct {
+	Netcheck     *netcheck.Report `json:"netcheck"`
+	NetcheckErr  *string          `json:"netcheck_err"`
+	NetcheckLogs []string         `json:"netcheck_logs"`
+}
+
+// Netcheck asks the agent to probe the DERP regions, and report the NAT type
+// and UDP reachability of the workspace.
+func (c *WorkspaceAgentConn) Netcheck(ctx context.Context) (WorkspaceAgentNetcheckResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/netcheck", nil)
+	if err != nil {
+		return WorkspaceAgentNetcheckResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentNetcheckResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentNetcheckResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// WorkspaceAgentFile is a file or directory in the workspace.
+type WorkspaceAgentFile struct {
+	Name string `json:"name"`
+	// Path is the absolute path of the file.
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	Mode       string    `json:"mode"`
+	IsDir      bool      `json:"is_dir"`
+	ModifiedAt time.Time `json:"modified_at" format:"date-time"`
+}
+
+type WorkspaceAgentListFilesResponse struct {
+	// Path is the absolute path of the directory.
+	Path string `json:"path"`
+	// Files are sorted by name.
+	Files []WorkspaceAgentFile `json:"files"`
+}
+
+// ListFiles lists the files in a directory of the workspace. Relative paths
+// are relative to the home directory of the agent user.
+func (c *WorkspaceAgentConn) ListFiles(ctx context.Context, path string) (WorkspaceAgentListFilesResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/list?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentListFilesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// DownloadFile returns the contents of a file in the workspace. The caller
+// must close the returned reader.
+func (c *WorkspaceAgentConn) DownloadFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/files/download?"+url.Values{"path": {path}}.Encode(), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// UploadFile writes the contents to a file in the workspace, replacing the
+// file if it exists. Missing parent directories are created.
+func (c *WorkspaceAgentConn) UploadFile(ctx context.Context, path string, content io.Reader) (WorkspaceAgentFile, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodPost, "/api/v0/files/upload?"+url.Values{"path": {path}}.Encode(), content)
+	if err != nil {
+		return WorkspaceAgentFile{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
@@ -0,0 +1,31 @@
+package codersdk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/ipn/ipnstate"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestWorkspaceAgentPingStats(t *testing.T) {
+	t.Parallel()
+
+	var stats codersdk.WorkspaceAgentPingStats
+	stats.AddPing(20*time.Millisecond, false, &ipnstate.PingResult{DERPRegionID: 1})
+	stats.AddPing(0, false, nil)
+	stats.AddPing(10*time.Millisecond, true, &ipnstate.PingResult{Endpoint: "10.0.0.2:41641"})
+	stats.AddPing(30*time.Millisecond, true, &ipnstate.PingResult{Endpoint: "10.0.0.2:41641"})
+
+	require.Equal(t, 4, stats.Sent)
+	require.Equal(t, 3, stats.Received)
+	require.InDelta(t, 25.0, stats.PacketLoss, 0.001)
+	require.InDelta(t, 10.0, stats.MinLatencyMS, 0.001)
+	require.InDelta(t, 20.0, stats.AvgLatencyMS, 0.001)
+	require.InDelta(t, 30.0, stats.MaxLatencyMS, 0.001)
+	require.True(t, stats.P2P)
+	require.Equal(t, "10.0.0.2:41641", stats.Endpoint)
+	require.Equal(t, 1, stats.DERPRegionID)
+}
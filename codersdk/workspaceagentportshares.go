@@ -0,0 +1,89 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type WorkspaceAgentPortShareLevel string
+
+const (
+	WorkspaceAgentPortShareLevelAuthenticated WorkspaceAgentPortShareLevel = "authenticated"
+	WorkspaceAgentPortShareLevelPublic        WorkspaceAgentPortShareLevel = "public"
+)
+
+// Valid returns whether the share level can be set on a port. Ports that
+// aren't shared are only accessible by the workspace owner.
+func (l WorkspaceAgentPortShareLevel) Valid() bool {
+	return l == WorkspaceAgentPortShareLevelAuthenticated || l == WorkspaceAgentPortShareLevelPublic
+}
+
+// WorkspaceAgentPortShare shares an agent port with other users through its
+// port-based app URL. Shares are kept across builds of the workspace.
+type WorkspaceAgentPortShare struct {
+	WorkspaceID uuid.UUID                    `json:"workspace_id" format:"uuid"`
+	AgentName   string                       `json:"agent_name"`
+	Port        int32                        `json:"port"`
+	ShareLevel  WorkspaceAgentPortShareLevel `json:"share_level" enums:"authenticated,public"`
+	// SubdomainName is the name of the subdomain the port is shared on. Append
+	// the app hostname of the deployment to get the URL of the port.
+	SubdomainName string `json:"subdomain_name"`
+}
+
+type UpsertWorkspaceAgentPortShareRequest struct {
+	AgentName  string                       `json:"agent_name" validate:"required"`
+	Port       int32                        `json:"port" validate:"required,min=1,max=65535"`
+	ShareLevel WorkspaceAgentPortShareLevel `json:"share_level" validate:"required" enums:"authenticated,public"`
+}
+
+type DeleteWorkspaceAgentPortShareRequest struct {
+	AgentName string `json:"agent_name" validate:"required"`
+	Port      int32  `json:"port" validate:"required"`
+}
+
+// WorkspaceAgentPortShares returns the shared ports of a workspace.
+func (c *Client) WorkspaceAgentPortShares(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgentPortShare, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var shares []WorkspaceAgentPortShare
+	return shares, json.NewDecoder(res.Body).Decode(&shares)
+}
+
+// UpsertWorkspaceAgentPortShare shares an agent port of a workspace, or
+// changes the share level of a port that is already shared.
+func (c *Client) UpsertWorkspaceAgentPortShare(ctx context.Context, workspaceID uuid.UUID, req UpsertWorkspaceAgentPortShareRequest) (WorkspaceAgentPortShare, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return WorkspaceAgentPortShare{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentPortShare{}, ReadBodyAsError(res)
+	}
+	var share WorkspaceAgentPortShare
+	return share, json.NewDecoder(res.Body).Decode(&share)
+}
+
+// DeleteWorkspaceAgentPortShare stops sharing an agent port of a workspace.
+func (c *Client) DeleteWorkspaceAgentPortShare(ctx context.Context, workspaceID uuid.UUID, req DeleteWorkspaceAgentPortShareRequest) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspaces/%s/port-share", workspaceID), req)
+	if err != nil {
+		return xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -134,6 +134,19 @@ type WorkspaceAgentMetadata struct {
 	Description WorkspaceAgentMetadataDescription `json:"description"`
 }
 
+// WorkspaceAgentMetadataHistory is the recent values of a metadatum, oldest
+// first. Only as many values as the deployment keeps are returned.
+type WorkspaceAgentMetadataHistory struct {
+	Key    string                               `json:"key"`
+	Values []WorkspaceAgentMetadataHistoryValue `json:"values"`
+}
+
+type WorkspaceAgentMetadataHistoryValue struct {
+	CollectedAt time.Time `json:"collected_at" format:"date-time"`
+	Value       string    `json:"value"`
+	Error       string    `json:"error"`
+}
+
 type DisplayApp string
 
 const (
@@ -178,6 +191,12 @@ type WorkspaceAgent struct {
 	LogSources               []WorkspaceAgentLogSource `json:"log_sources"`
 	Scripts                  []WorkspaceAgentScript    `json:"scripts"`
 
+	// DiscoveredApps are the ports found listening in the workspace that are
+	// not served by one of the agent's apps.
+	DiscoveredApps []WorkspaceAgentDiscoveredApp `json:"discovered_apps"`
+	// GPUs are the GPUs the agent detected in the workspace on startup.
+	GPUs []WorkspaceAgentGPU `json:"gpus"`
+
 	// StartupScriptBehavior is a legacy field that is deprecated in favor
 	// of the `coder_script` resource. It's only referenced by old clients.
 	// Deprecated: Remove in the future!
@@ -201,6 +220,47 @@ type WorkspaceAgentScript struct {
 	RunOnStop        bool          `json:"run_on_stop"`
 	StartBlocksLogin bool          `json:"start_blocks_login"`
 	Timeout          time.Duration `json:"timeout"`
+	// Phase orders the script within the start or stop lifecycle of the
+	// agent.
+	Phase WorkspaceAgentScriptPhase `json:"phase" enums:"pre_start,start,post_start,pre_stop,stop"`
+}
+
+// WorkspaceAgentScriptPhase is the phase of the start or stop lifecycle that a
+// script runs in. When the workspace starts, the agent runs the "pre_start",
+// "start" and "post_start" phases in order. When it stops, the agent runs
+// "pre_stop" and then "stop". The scripts of a phase run in parallel.
+type WorkspaceAgentScriptPhase string
+
+// WorkspaceAgentScriptPhase enums.
+const (
+	WorkspaceAgentScriptPhasePreStart  WorkspaceAgentScriptPhase = "pre_start"
+	WorkspaceAgentScriptPhaseStart     WorkspaceAgentScriptPhase = "start"
+	WorkspaceAgentScriptPhasePostStart WorkspaceAgentScriptPhase = "post_start"
+	WorkspaceAgentScriptPhasePreStop   WorkspaceAgentScriptPhase = "pre_stop"
+	WorkspaceAgentScriptPhaseStop      WorkspaceAgentScriptPhase = "stop"
+)
+
+// WorkspaceAgentDiscoveredApp is a TCP port the agent found listening in the
+// workspace. It can be opened like an app without declaring it in the
+// template.
+type WorkspaceAgentDiscoveredApp struct {
+	Port         uint16    `json:"port"`
+	ProcessName  string    `json:"process_name"`
+	DiscoveredAt time.Time `json:"discovered_at" format:"date-time"`
+	// SubdomainName is the port forwarding domain of the port on the
+	// `coder server`.
+	SubdomainName string `json:"subdomain_name"`
+}
+
+// WorkspaceAgentGPU is a GPU the agent detected in the workspace.
+type WorkspaceAgentGPU struct {
+	// Index is the index of the GPU reported by the driver.
+	Index int32 `json:"index"`
+	// Vendor is "nvidia" or "amd".
+	Vendor        string `json:"vendor"`
+	Model         string `json:"model"`
+	MemoryBytes   int64  `json:"memory_bytes"`
+	DriverVersion string `json:"driver_version"`
 }
 
 type WorkspaceAgentHealth struct {
@@ -256,6 +316,9 @@ type DialWorkspaceAgentOptions struct {
 	// BlockEndpoints forced a direct connection through DERP. The Client may
 	// have DisableDirect set which will override this value.
 	BlockEndpoints bool
+	// ConnectionType is recorded in the connection log of the deployment.
+	// Connections without a type aren't recorded.
+	ConnectionType ConnectionType
 }
 
 func (c *Client) DialWorkspaceAgent(dialCtx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
@@ -315,6 +378,9 @@ func (c *Client) DialWorkspaceAgent(dialCtx context.Context, agentID uuid.UUID,
 	}
 	q := coordinateURL.Query()
 	q.Add("version", tailnet.CurrentVersion.String())
+	if options.ConnectionType != "" {
+		q.Add("connection_type", string(options.ConnectionType))
+	}
 	coordinateURL.RawQuery = q.Encode()
 	closedCoordinator := make(chan struct{})
 	// Must only ever be used once, send error OR close to avoid
@@ -584,6 +650,22 @@ func (c *Client) WorkspaceAgent(ctx context.Context, id uuid.UUID) (WorkspaceAge
 	return workspaceAgent, nil
 }
 
+// RevokeWorkspaceAgentToken immediately invalidates the agent's current and
+// previous session tokens and closes its connections. The agent can no longer
+// authenticate until the workspace is rebuilt. Anyone who may update the
+// workspace may revoke its agents' tokens.
+func (c *Client) RevokeWorkspaceAgentToken(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/revoke-token", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 type IssueReconnectingPTYSignedTokenRequest struct {
 	// URL is the URL of the reconnecting-pty endpoint you are connecting to.
 	URL     string    `json:"url" validate:"required"`
@@ -684,6 +766,150 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentNetcheck runs a netcheck from inside the workspace of the
+// agent. It takes a few seconds to complete.
+func (c *Client) WorkspaceAgentNetcheck(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentNetcheckResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/netcheck", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentNetcheckResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentNetcheckResponse{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceAgentNetcheckResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// AuthorizeWorkspaceAgentPortForwardRequest asks whether a port of the agent
+// may be forwarded.
+type AuthorizeWorkspaceAgentPortForwardRequest struct {
+	Port uint16 `json:"port" validate:"required"`
+}
+
+// AuthorizeWorkspaceAgentPortForward returns an error if the port forwarding
+// policy of the workspace's template doesn't allow the port of the agent to be
+// forwarded. Denied attempts are audited, and allowed ones are recorded in the
+// connection log.
+func (c *Client) AuthorizeWorkspaceAgentPortForward(ctx context.Context, agentID uuid.UUID, port uint16) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/port-forward", agentID), AuthorizeWorkspaceAgentPortForwardRequest{
+		Port: port,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceAgentListFiles lists the files in a directory of the workspace.
+// Relative paths are relative to the home directory of the agent user.
+func (c *Client) WorkspaceAgentListFiles(ctx context.Context, agentID uuid.UUID, path string) (WorkspaceAgentListFilesResponse, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/list", agentID), nil, WithQueryParam("path", path))
+	if err != nil {
+		return WorkspaceAgentListFilesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentListFilesResponse{}, ReadBodyAsError(res)
+	}
+	var files WorkspaceAgentListFilesResponse
+	return files, json.NewDecoder(res.Body).Decode(&files)
+}
+
+// WorkspaceAgentDownloadFile returns the contents of a file in the workspace.
+// The caller must close the returned reader.
+func (c *Client) WorkspaceAgentDownloadFile(ctx context.Context, agentID uuid.UUID, path string) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/files/download", agentID), nil, WithQueryParam("path", path))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentUploadFile writes the contents to a file in the workspace,
+// replacing the file if it exists.
+func (c *Client) WorkspaceAgentUploadFile(ctx context.Context, agentID uuid.UUID, path string, content io.Reader) (WorkspaceAgentFile, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/files/upload", agentID), content, WithQueryParam("path", path))
+	if err != nil {
+		return WorkspaceAgentFile{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceAgentFile{}, ReadBodyAsError(res)
+	}
+	var file WorkspaceAgentFile
+	return file, json.NewDecoder(res.Body).Decode(&file)
+}
+
+// WorkspaceAgentResourceUsage is the most recent CPU, memory and disk usage
+// reported by a workspace agent.
+type WorkspaceAgentResourceUsage struct {
+	// CollectedAt is empty if the agent has not reported stats yet.
+	CollectedAt      *time.Time `json:"collected_at,omitempty" format:"date-time"`
+	CPUUsedCores     float64    `json:"cpu_used_cores"`
+	CPUTotalCores    float64    `json:"cpu_total_cores"`
+	MemoryUsedBytes  int64      `json:"memory_used_bytes"`
+	MemoryTotalBytes int64      `json:"memory_total_bytes"`
+	DiskUsedBytes    int64      `json:"disk_used_bytes"`
+	DiskTotalBytes   int64      `json:"disk_total_bytes"`
+}
+
+// WorkspaceAgentResourceUsage returns the latest resource usage reported by
+// the workspace agent.
+func (c *Client) WorkspaceAgentResourceUsage(ctx context.Context, agentID uuid.UUID) (WorkspaceAgentResourceUsage, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/resource-usage", agentID), nil)
+	if err != nil {
+		return WorkspaceAgentResourceUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentResourceUsage{}, ReadBodyAsError(res)
+	}
+	var usage WorkspaceAgentResourceUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
+
+// WorkspaceAgentMetadataHistoryRequest filters the metadata history of a
+// workspace agent. Empty fields don't filter.
+type WorkspaceAgentMetadataHistoryRequest struct {
+	// Keys limits the history to these metadata keys.
+	Keys []string `json:"keys,omitempty"`
+	// Since only returns values collected after this time.
+	Since time.Time `json:"since,omitempty" format:"date-time"`
+}
+
+// WorkspaceAgentMetadataHistory returns the recent values of the workspace
+// agent's metadata. It's empty unless the deployment keeps metadata history.
+func (c *Client) WorkspaceAgentMetadataHistory(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentMetadataHistoryRequest) ([]WorkspaceAgentMetadataHistory, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/metadata-history", agentID), nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if len(req.Keys) > 0 {
+			q.Set("keys", strings.Join(req.Keys, ","))
+		}
+		if !req.Since.IsZero() {
+			q.Set("since", req.Since.UTC().Format(time.RFC3339Nano))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var history []WorkspaceAgentMetadataHistory
+	return history, json.NewDecoder(res.Body).Decode(&history)
+}
+
 //nolint:revive // Follow is a control flag on the server as well.
 func (c *Client) WorkspaceAgentLogsAfter(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []WorkspaceAgentLog, io.Closer, error) {
 	var queryParams []string
@@ -46,6 +46,15 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "template_update" is used when a user builds a workspace on a different
+	// template version than its previous build, e.g. when updating it.
+	BuildReasonTemplateUpdate BuildReason = "template_update"
+	// "batch" is used when a build is created through the batch workspace
+	// builds endpoint, e.g. by an administrator stopping many workspaces.
+	BuildReasonBatch BuildReason = "batch"
+	// "retry" is used when a build that failed transiently is automatically
+	// retried according to the build retry policy of the template.
+	BuildReasonRetry BuildReason = "retry"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -65,12 +74,17 @@ type WorkspaceBuild struct {
 	InitiatorID         uuid.UUID           `json:"initiator_id" format:"uuid"`
 	InitiatorUsername   string              `json:"initiator_name"`
 	Job                 ProvisionerJob      `json:"job"`
-	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
+	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop,template_update,batch,retry"`
 	Resources           []WorkspaceResource `json:"resources"`
 	Deadline            NullTime            `json:"deadline,omitempty" format:"date-time"`
 	MaxDeadline         NullTime            `json:"max_deadline,omitempty" format:"date-time"`
 	Status              WorkspaceStatus     `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
 	DailyCost           int32               `json:"daily_cost"`
+	// Attempt counts the automatic retries of a failed build, starting at 1.
+	Attempt int32 `json:"attempt"`
+	// RetryAt is when the failed build is retried automatically. It is
+	// unset if the build did not fail, or failed for good.
+	RetryAt NullTime `json:"retry_at,omitempty" format:"date-time"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
@@ -102,6 +116,26 @@ type WorkspaceBuildParameter struct {
 	Value string `json:"value"`
 }
 
+// WorkspaceBuildDryRunResource is a resource planned by a workspace build
+// dry-run. Resources are identified by their type and name.
+type WorkspaceBuildDryRunResource struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	DailyCost int32  `json:"daily_cost"`
+}
+
+// WorkspaceBuildDryRunResources compares the resources planned by a workspace
+// build dry-run with the resources of the latest build of the workspace.
+type WorkspaceBuildDryRunResources struct {
+	Added     []WorkspaceBuildDryRunResource `json:"added"`
+	Removed   []WorkspaceBuildDryRunResource `json:"removed"`
+	Unchanged []WorkspaceBuildDryRunResource `json:"unchanged"`
+	// DailyCost is the daily cost of all planned resources.
+	DailyCost int32 `json:"daily_cost"`
+	// DailyCostChange is the difference to the daily cost of the latest build.
+	DailyCostChange int32 `json:"daily_cost_change"`
+}
+
 // WorkspaceBuild returns a single workspace build for a workspace.
 // If history is "", the latest version is returned.
 func (c *Client) WorkspaceBuild(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
@@ -173,3 +207,68 @@ func (c *Client) WorkspaceBuildParameters(ctx context.Context, build uuid.UUID)
 	var params []WorkspaceBuildParameter
 	return params, json.NewDecoder(res.Body).Decode(&params)
 }
+
+// CreateWorkspaceBuildDryRun plans a workspace build without applying it.
+// The returned job can be followed with WorkspaceBuildDryRunLogsAfter.
+func (c *Client) CreateWorkspaceBuildDryRun(ctx context.Context, workspace uuid.UUID, req CreateWorkspaceBuildRequest) (ProvisionerJob, error) {
+	req.DryRun = true
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds", workspace), req)
+	if err != nil {
+		return ProvisionerJob{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ProvisionerJob{}, ReadBodyAsError(res)
+	}
+	var job ProvisionerJob
+	return job, json.NewDecoder(res.Body).Decode(&job)
+}
+
+// WorkspaceBuildDryRun returns the provisioner job of a workspace build
+// dry-run.
+func (c *Client) WorkspaceBuildDryRun(ctx context.Context, workspace, job uuid.UUID) (ProvisionerJob, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/dry-run/%s", workspace, job), nil)
+	if err != nil {
+		return ProvisionerJob{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerJob{}, ReadBodyAsError(res)
+	}
+	var j ProvisionerJob
+	return j, json.NewDecoder(res.Body).Decode(&j)
+}
+
+// WorkspaceBuildDryRunResources returns the resource changes planned by a
+// finished workspace build dry-run.
+func (c *Client) WorkspaceBuildDryRunResources(ctx context.Context, workspace, job uuid.UUID) (WorkspaceBuildDryRunResources, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/dry-run/%s/resources", workspace, job), nil)
+	if err != nil {
+		return WorkspaceBuildDryRunResources{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildDryRunResources{}, ReadBodyAsError(res)
+	}
+	var resources WorkspaceBuildDryRunResources
+	return resources, json.NewDecoder(res.Body).Decode(&resources)
+}
+
+// WorkspaceBuildDryRunLogsAfter streams logs for a workspace build dry-run
+// that occurred after a specific log ID.
+func (c *Client) WorkspaceBuildDryRunLogsAfter(ctx context.Context, workspace, job uuid.UUID, after int64) (<-chan ProvisionerJobLog, io.Closer, error) {
+	return c.provisionerJobLogsAfter(ctx, fmt.Sprintf("/api/v2/workspaces/%s/dry-run/%s/logs", workspace, job), after)
+}
+
+// CancelWorkspaceBuildDryRun marks a workspace build dry-run job as canceled.
+func (c *Client) CancelWorkspaceBuildDryRun(ctx context.Context, workspace, job uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/workspaces/%s/dry-run/%s/cancel", workspace, job), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CostDateLayout is the layout of the dates used by cost records.
+const CostDateLayout = "2006-01-02"
+
+// CostsRequest is the date range to report costs for. Both dates are
+// inclusive. Zero values use the server defaults: the 30 days up to and
+// including today.
+type CostsRequest struct {
+	StartDate time.Time `json:"start_date" format:"date-time"`
+	EndDate   time.Time `json:"end_date" format:"date-time"`
+}
+
+func (r CostsRequest) query() string {
+	qp := url.Values{}
+	if !r.StartDate.IsZero() {
+		qp.Add("start_date", r.StartDate.Format(CostDateLayout))
+	}
+	if !r.EndDate.IsZero() {
+		qp.Add("end_date", r.EndDate.Format(CostDateLayout))
+	}
+	if len(qp) == 0 {
+		return ""
+	}
+	return "?" + qp.Encode()
+}
+
+// CostTotal is the total cost in a single currency.
+type CostTotal struct {
+	Currency string `json:"currency"`
+	Total    int64  `json:"total"`
+}
+
+// WorkspaceResourceCost is the recorded daily cost of a workspace resource on
+// a single date.
+type WorkspaceResourceCost struct {
+	Date         string `json:"date" format:"date"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Currency     string `json:"currency"`
+	DailyCost    int32  `json:"daily_cost"`
+}
+
+type WorkspaceCosts struct {
+	WorkspaceID uuid.UUID               `json:"workspace_id" format:"uuid"`
+	StartDate   string                  `json:"start_date" format:"date"`
+	EndDate     string                  `json:"end_date" format:"date"`
+	Resources   []WorkspaceResourceCost `json:"resources"`
+	// Totals is the cost over the whole date range, per currency.
+	Totals []CostTotal `json:"totals"`
+}
+
+// OrganizationCostRollup is the cost of the workspaces of an owner created
+// from a template, in a single currency.
+type OrganizationCostRollup struct {
+	OwnerID        uuid.UUID `json:"owner_id" format:"uuid"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+	Currency       string    `json:"currency"`
+	WorkspaceCount int64     `json:"workspace_count"`
+	TotalCost      int64     `json:"total_cost"`
+}
+
+type OrganizationCosts struct {
+	OrganizationID uuid.UUID                `json:"organization_id" format:"uuid"`
+	StartDate      string                   `json:"start_date" format:"date"`
+	EndDate        string                   `json:"end_date" format:"date"`
+	Rollups        []OrganizationCostRollup `json:"rollups"`
+	// Totals is the cost of all workspaces over the whole date range, per
+	// currency.
+	Totals []CostTotal `json:"totals"`
+}
+
+// WorkspaceCosts returns the recorded resource costs of a workspace.
+func (c *Client) WorkspaceCosts(ctx context.Context, workspaceID uuid.UUID, req CostsRequest) (WorkspaceCosts, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/costs%s", workspaceID, req.query()), nil)
+	if err != nil {
+		return WorkspaceCosts{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceCosts{}, ReadBodyAsError(res)
+	}
+	var costs WorkspaceCosts
+	return costs, json.NewDecoder(res.Body).Decode(&costs)
+}
+
+// OrganizationCosts returns the resource costs of the workspaces in an
+// organization, rolled up per owner, template and currency.
+func (c *Client) OrganizationCosts(ctx context.Context, organizationID uuid.UUID, req CostsRequest) (OrganizationCosts, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/costs%s", organizationID, req.query()), nil)
+	if err != nil {
+		return OrganizationCosts{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OrganizationCosts{}, ReadBodyAsError(res)
+	}
+	var costs OrganizationCosts
+	return costs, json.NewDecoder(res.Body).Decode(&costs)
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/xerrors"
@@ -33,6 +37,15 @@ type WorkspaceProxyStatus struct {
 	// Report provides more information about the health of the workspace proxy.
 	Report    ProxyHealthReport `json:"report,omitempty" table:"report"`
 	CheckedAt time.Time         `json:"checked_at" table:"checked_at" format:"date-time"`
+	// LatencyMS is the round trip time in milliseconds of the health check
+	// request from coderd to the workspace proxy.
+	LatencyMS int64 `json:"latency_ms,omitempty" table:"latency_ms"`
+	// DERPReachable is true if DERP is enabled on the workspace proxy and
+	// coderd can reach it.
+	DERPReachable bool `json:"derp_reachable,omitempty" table:"derp_reachable"`
+	// VersionSkew is true if the major or minor version of the workspace
+	// proxy differs from coderd.
+	VersionSkew bool `json:"version_skew,omitempty" table:"version_skew"`
 }
 
 // ProxyHealthReport is a report of the health of the workspace proxy.
@@ -221,3 +234,78 @@ func (c *Client) Regions(ctx context.Context) ([]Region, error) {
 	var regions RegionsResponse[Region]
 	return regions.Regions, json.NewDecoder(res.Body).Decode(&regions)
 }
+
+// RegionLatency is the round-trip time from the client to a region.
+type RegionLatency struct {
+	Region  Region
+	Latency time.Duration
+	// Err is set if the region could not be reached.
+	Err error
+}
+
+// RegionLatencies measures the round-trip time to the latency check endpoint
+// of every healthy region concurrently. Each region is probed a few times and
+// the fastest response is kept, so a slow first TLS handshake doesn't skew the
+// result. The results are sorted from fastest to slowest, with unreachable
+// regions last.
+func RegionLatencies(ctx context.Context, httpClient *http.Client, regions []Region) []RegionLatency {
+	const samples = 3
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	latencies := make([]RegionLatency, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		latencies[i].Region = region
+		if !region.Healthy || region.PathAppURL == "" {
+			latencies[i].Err = xerrors.New("region is unhealthy")
+			continue
+		}
+		wg.Add(1)
+		go func(lat *RegionLatency) {
+			defer wg.Done()
+			for s := 0; s < samples; s++ {
+				rtt, err := probeLatency(ctx, httpClient, lat.Region.PathAppURL)
+				if err != nil {
+					lat.Err = err
+					return
+				}
+				if lat.Latency == 0 || rtt < lat.Latency {
+					lat.Latency = rtt
+				}
+			}
+		}(&latencies[i])
+	}
+	wg.Wait()
+
+	sort.SliceStable(latencies, func(i, j int) bool {
+		if (latencies[i].Err == nil) != (latencies[j].Err == nil) {
+			return latencies[i].Err == nil
+		}
+		return latencies[i].Latency < latencies[j].Latency
+	})
+	return latencies
+}
+
+func probeLatency(ctx context.Context, httpClient *http.Client, baseURL string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/latency-check", nil)
+	if err != nil {
+		return 0, xerrors.Errorf("create request: %w", err)
+	}
+	start := time.Now()
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return 0, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return rtt, nil
+}
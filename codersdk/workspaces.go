@@ -58,6 +58,17 @@ type Workspace struct {
 	Health           WorkspaceHealth  `json:"health"`
 	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" enums:"always,never"`
 	AllowRenames     bool             `json:"allow_renames"`
+	// Favorite is true if the requesting user has pinned this workspace.
+	Favorite bool `json:"favorite"`
+	// MaintenanceOptOut is true if the template's maintenance window should
+	// not stop and rebuild this workspace.
+	MaintenanceOptOut bool `json:"maintenance_opt_out"`
+	// Ephemeral workspaces are deleted, rather than stopped, once they
+	// outlive their TTL or the API token that created them expires.
+	Ephemeral bool `json:"ephemeral"`
+	// DormancyExempt is true if the workspace never becomes dormant or is
+	// deleted for inactivity.
+	DormancyExempt bool `json:"dormancy_exempt"`
 }
 
 func (w Workspace) FullName() string {
@@ -89,8 +100,10 @@ const (
 type CreateWorkspaceBuildRequest struct {
 	TemplateVersionID uuid.UUID           `json:"template_version_id,omitempty" format:"uuid"`
 	Transition        WorkspaceTransition `json:"transition" validate:"oneof=create start stop delete,required"`
-	DryRun            bool                `json:"dry_run,omitempty"`
-	ProvisionerState  []byte              `json:"state,omitempty"`
+	// DryRun plans the build without applying it. A provisioner job is
+	// returned instead of a workspace build, and the workspace is unchanged.
+	DryRun           bool   `json:"dry_run,omitempty"`
+	ProvisionerState []byte `json:"state,omitempty"`
 	// Orphan may be set for the Destroy transition.
 	Orphan bool `json:"orphan,omitempty"`
 	// ParameterValues are optional. It will write params to the 'workspace' scope.
@@ -102,6 +115,33 @@ type CreateWorkspaceBuildRequest struct {
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
 }
 
+// MaxBatchWorkspaceBuilds is the maximum number of workspaces that can be
+// built in a single batch request.
+const MaxBatchWorkspaceBuilds = 100
+
+// BatchWorkspaceBuildRequest starts a build with the same transition for each
+// workspace.
+type BatchWorkspaceBuildRequest struct {
+	WorkspaceIDs []uuid.UUID         `json:"workspace_ids" validate:"required" format:"uuid"`
+	Transition   WorkspaceTransition `json:"transition" validate:"oneof=start stop delete,required"`
+}
+
+// BatchWorkspaceBuildResult is the outcome of the build for one workspace in
+// a batch. Exactly one of Build and Error is set.
+type BatchWorkspaceBuildResult struct {
+	WorkspaceID uuid.UUID       `json:"workspace_id" format:"uuid"`
+	Build       *WorkspaceBuild `json:"build,omitempty"`
+	Error       *Response       `json:"error,omitempty"`
+}
+
+// BatchWorkspaceBuildResponse reports the outcome for each workspace in the
+// request. The batch is atomic: if the build for any workspace fails, no
+// builds are queued and every result has an error, with the one for the
+// workspace that failed explaining why.
+type BatchWorkspaceBuildResponse struct {
+	Results []BatchWorkspaceBuildResult `json:"results"`
+}
+
 type WorkspaceOptions struct {
 	IncludeDeleted bool `json:"include_deleted,omitempty"`
 }
@@ -148,13 +188,17 @@ type WorkspaceBuildsRequest struct {
 	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid" typescript:"-"`
 	Pagination
 	Since time.Time `json:"since,omitempty" format:"date-time"`
+	// Reason filters the builds to those created for the given reason.
+	Reason BuildReason `json:"reason,omitempty"`
 }
 
 func (c *Client) WorkspaceBuilds(ctx context.Context, req WorkspaceBuildsRequest) ([]WorkspaceBuild, error) {
 	res, err := c.Request(
 		ctx, http.MethodGet,
 		fmt.Sprintf("/api/v2/workspaces/%s/builds", req.WorkspaceID),
-		nil, req.Pagination.asRequestOption(), WithQueryParam("since", req.Since.Format(time.RFC3339)),
+		nil, req.Pagination.asRequestOption(),
+		WithQueryParam("since", req.Since.Format(time.RFC3339)),
+		WithQueryParam("reason", string(req.Reason)),
 	)
 	if err != nil {
 		return nil, err
@@ -181,6 +225,22 @@ func (c *Client) CreateWorkspaceBuild(ctx context.Context, workspace uuid.UUID,
 	return workspaceBuild, json.NewDecoder(res.Body).Decode(&workspaceBuild)
 }
 
+// BatchWorkspaceBuilds queues a build for each of the workspaces. The builds
+// are created in a single transaction, so a failure for one workspace is
+// reported in its result and prevents builds for all of the others.
+func (c *Client) BatchWorkspaceBuilds(ctx context.Context, req BatchWorkspaceBuildRequest) (BatchWorkspaceBuildResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaces/batch-builds", req)
+	if err != nil {
+		return BatchWorkspaceBuildResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return BatchWorkspaceBuildResponse{}, ReadBodyAsError(res)
+	}
+	var resp BatchWorkspaceBuildResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) WatchWorkspace(ctx context.Context, id uuid.UUID) (<-chan Workspace, error) {
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
@@ -349,6 +409,194 @@ func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UU
 	return nil
 }
 
+// UpdateWorkspaceMaintenanceOptOutRequest is a request to update whether a
+// workspace is excluded from its template's maintenance window.
+type UpdateWorkspaceMaintenanceOptOutRequest struct {
+	OptOut bool `json:"opt_out"`
+}
+
+// UpdateWorkspaceMaintenanceOptOut sets the maintenance opt-out setting for
+// workspace by id.
+func (c *Client) UpdateWorkspaceMaintenanceOptOut(ctx context.Context, id uuid.UUID, req UpdateWorkspaceMaintenanceOptOutRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/maintenance-opt-out", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace maintenance opt-out: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UpdateWorkspaceDormancyExemptRequest is a request to update whether a
+// workspace is exempt from the dormancy settings of its template.
+type UpdateWorkspaceDormancyExemptRequest struct {
+	Exempt bool `json:"exempt"`
+}
+
+// UpdateWorkspaceDormancyExempt sets whether the workspace by id is exempt
+// from dormancy. It requires permission to update the template.
+func (c *Client) UpdateWorkspaceDormancyExempt(ctx context.Context, id uuid.UUID, req UpdateWorkspaceDormancyExemptRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/dormancy-exempt", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace dormancy exempt: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// FavoriteWorkspace pins a workspace to the top of the authenticated user's
+// workspace list.
+func (c *Client) FavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/favorite", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, nil)
+	if err != nil {
+		return xerrors.Errorf("favorite workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// UnfavoriteWorkspace unpins a workspace for the authenticated user.
+func (c *Client) UnfavoriteWorkspace(ctx context.Context, id uuid.UUID) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/favorite", id.String())
+	res, err := c.Request(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return xerrors.Errorf("unfavorite workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceRole is the access a workspace is shared with. The "use" role
+// allows connecting to the workspace over SSH and to its apps, but not changing
+// or rebuilding it.
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleUse     WorkspaceRole = "use"
+	WorkspaceRoleDeleted WorkspaceRole = ""
+)
+
+// WorkspaceACL lists the users a workspace is shared with.
+type WorkspaceACL struct {
+	Users []WorkspaceUser `json:"users"`
+}
+
+type WorkspaceUser struct {
+	MinimalUser
+	Role WorkspaceRole `json:"role" enums:"use"`
+}
+
+type UpdateWorkspaceACL struct {
+	// UserRoles maps a user ID or username to a role. An empty role stops
+	// sharing the workspace with the user.
+	UserRoles map[string]WorkspaceRole `json:"user_roles,omitempty" example:"4df59e74-c027-470b-ab4d-cbba8963a5e9:use"`
+}
+
+// WorkspaceACL returns the users a workspace is shared with.
+func (c *Client) WorkspaceACL(ctx context.Context, id uuid.UUID) (WorkspaceACL, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/acl", id.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return WorkspaceACL{}, xerrors.Errorf("get workspace acl: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceACL{}, ReadBodyAsError(res)
+	}
+	var acl WorkspaceACL
+	return acl, json.NewDecoder(res.Body).Decode(&acl)
+}
+
+// UpdateWorkspaceACL shares a workspace with users, or stops sharing it.
+func (c *Client) UpdateWorkspaceACL(ctx context.Context, id uuid.UUID, req UpdateWorkspaceACL) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/acl", id.String())
+	res, err := c.Request(ctx, http.MethodPatch, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace acl: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceDrift is the result of the latest drift check of a workspace.
+// Drift checks plan the latest build of a running workspace, and report the
+// resources Terraform found to have changed outside of Coder.
+type WorkspaceDrift struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	// JobID is the dry-run job of the latest drift check. It may still be
+	// running, in which case CheckedAt refers to the previous check.
+	JobID uuid.UUID `json:"job_id" format:"uuid"`
+	// BuildID is the build that was checked.
+	BuildID *uuid.UUID `json:"build_id,omitempty" format:"uuid"`
+	// CheckedAt is unset until the first drift check completes.
+	CheckedAt        *time.Time `json:"checked_at,omitempty" format:"date-time"`
+	DriftedResources []string   `json:"drifted_resources"`
+}
+
+// WorkspaceDrift returns the result of the latest drift check of a workspace.
+func (c *Client) WorkspaceDrift(ctx context.Context, id uuid.UUID) (WorkspaceDrift, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/drift", id.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return WorkspaceDrift{}, xerrors.Errorf("get workspace drift: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceDrift{}, ReadBodyAsError(res)
+	}
+	var drift WorkspaceDrift
+	return drift, json.NewDecoder(res.Body).Decode(&drift)
+}
+
+// WorkspaceParameterChange is a change of the value of a parameter of a
+// workspace, recorded when a build sets a value that differs from the
+// previous build.
+type WorkspaceParameterChange struct {
+	WorkspaceBuildID uuid.UUID `json:"workspace_build_id" format:"uuid"`
+	BuildNumber      int32     `json:"build_number"`
+	Name             string    `json:"name"`
+	// OldValue is unset if the parameter wasn't set by the previous build.
+	OldValue          *string   `json:"old_value,omitempty"`
+	NewValue          string    `json:"new_value"`
+	InitiatorID       uuid.UUID `json:"initiator_id" format:"uuid"`
+	InitiatorUsername string    `json:"initiator_username"`
+	CreatedAt         time.Time `json:"created_at" format:"date-time"`
+}
+
+// WorkspaceParameterHistory returns the changes of the parameter values of a
+// workspace, latest first. If name is set, only the changes of that parameter
+// are returned.
+func (c *Client) WorkspaceParameterHistory(ctx context.Context, id uuid.UUID, name string) ([]WorkspaceParameterChange, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/parameters/history", id.String())
+	res, err := c.Request(ctx, http.MethodGet, path, nil, WithQueryParam("name", name))
+	if err != nil {
+		return nil, xerrors.Errorf("get workspace parameter history: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var changes []WorkspaceParameterChange
+	return changes, json.NewDecoder(res.Body).Decode(&changes)
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
@@ -454,6 +702,43 @@ func (c *Client) WorkspaceQuota(ctx context.Context, userID string) (WorkspaceQu
 	return quota, json.NewDecoder(res.Body).Decode(&quota)
 }
 
+// OrganizationQuota limits the workspaces of an organization, in addition to
+// the daily cost budgets of its users. Zero values mean no limit.
+type OrganizationQuota struct {
+	// MaxRunningWorkspacesPerUser is the maximum number of workspaces each
+	// user can run at the same time in the organization.
+	MaxRunningWorkspacesPerUser int32 `json:"max_running_workspaces_per_user"`
+	// MaxDailyCost is the maximum total daily cost of the workspaces in the
+	// organization.
+	MaxDailyCost int32 `json:"max_daily_cost"`
+}
+
+func (c *Client) OrganizationQuota(ctx context.Context, organizationID uuid.UUID) (OrganizationQuota, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID), nil)
+	if err != nil {
+		return OrganizationQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OrganizationQuota{}, ReadBodyAsError(res)
+	}
+	var quota OrganizationQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
+func (c *Client) UpdateOrganizationQuota(ctx context.Context, organizationID uuid.UUID, req OrganizationQuota) (OrganizationQuota, error) {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/organizations/%s/quota", organizationID), req)
+	if err != nil {
+		return OrganizationQuota{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return OrganizationQuota{}, ReadBodyAsError(res)
+	}
+	var quota OrganizationQuota
+	return quota, json.NewDecoder(res.Body).Decode(&quota)
+}
+
 type ResolveAutostartResponse struct {
 	ParameterMismatch bool `json:"parameter_mismatch"`
 }
@@ -0,0 +1,80 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// WorkspaceSnapshot is a snapshot of the snapshot-able resources of a
+// workspace. Templates declare snapshot-able resources with the "snapshot"
+// attribute of "coder_metadata".
+type WorkspaceSnapshot struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID `json:"workspace_id" format:"uuid"`
+	Name        string    `json:"name"`
+	// BuildID is the workspace build that takes the snapshot.
+	BuildID   uuid.UUID `json:"build_id" format:"uuid"`
+	CreatedBy uuid.UUID `json:"created_by" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	// Status is the status of the build that takes the snapshot. Only
+	// succeeded snapshots can be restored.
+	Status ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	// Resources are the snapshot-able resources captured by the snapshot, as
+	// "<type>.<name>". They are known once the snapshot has succeeded.
+	Resources []string `json:"resources"`
+}
+
+type CreateWorkspaceSnapshotRequest struct {
+	// Name defaults to a name derived from the current time.
+	Name string `json:"name,omitempty" validate:"omitempty,workspace_name"`
+}
+
+// WorkspaceSnapshots returns the snapshots of a workspace, newest first.
+func (c *Client) WorkspaceSnapshots(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var snapshots []WorkspaceSnapshot
+	return snapshots, json.NewDecoder(res.Body).Decode(&snapshots)
+}
+
+// CreateWorkspaceSnapshot starts a build that takes a snapshot of the
+// snapshot-able resources of a workspace.
+func (c *Client) CreateWorkspaceSnapshot(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceSnapshotRequest) (WorkspaceSnapshot, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots", workspaceID), req)
+	if err != nil {
+		return WorkspaceSnapshot{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceSnapshot{}, ReadBodyAsError(res)
+	}
+	var snapshot WorkspaceSnapshot
+	return snapshot, json.NewDecoder(res.Body).Decode(&snapshot)
+}
+
+// RestoreWorkspaceSnapshot starts a build that restores the snapshot-able
+// resources of a workspace from a snapshot.
+func (c *Client) RestoreWorkspaceSnapshot(ctx context.Context, workspaceID, snapshotID uuid.UUID) (WorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/snapshots/%s/restore", workspaceID, snapshotID), nil)
+	if err != nil {
+		return WorkspaceBuild{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return WorkspaceBuild{}, ReadBodyAsError(res)
+	}
+	var build WorkspaceBuild
+	return build, json.NewDecoder(res.Body).Decode(&build)
+}
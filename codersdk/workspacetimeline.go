@@ -0,0 +1,69 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceTimelineEventType string
+
+const (
+	WorkspaceTimelineEventTypeBuildStarted      WorkspaceTimelineEventType = "build_started"
+	WorkspaceTimelineEventTypeBuildCompleted    WorkspaceTimelineEventType = "build_completed"
+	WorkspaceTimelineEventTypeAgentConnected    WorkspaceTimelineEventType = "agent_connected"
+	WorkspaceTimelineEventTypeAgentDisconnected WorkspaceTimelineEventType = "agent_disconnected"
+	WorkspaceTimelineEventTypeTTLChanged        WorkspaceTimelineEventType = "ttl_changed"
+	WorkspaceTimelineEventTypeAppHealthChanged  WorkspaceTimelineEventType = "app_health_changed"
+)
+
+// WorkspaceTimelineEvent is an entry on the timeline of a workspace. Which of
+// the optional fields are set depends on the type of the event.
+type WorkspaceTimelineEvent struct {
+	ID        uuid.UUID                  `json:"id" format:"uuid"`
+	CreatedAt time.Time                  `json:"created_at" format:"date-time"`
+	Type      WorkspaceTimelineEventType `json:"type" enums:"build_started,build_completed,agent_connected,agent_disconnected,ttl_changed,app_health_changed"`
+	// UserID is the user who caused the event. It is unset for events caused
+	// by the workspace itself, like agents connecting.
+	UserID *uuid.UUID `json:"user_id,omitempty" format:"uuid"`
+	// BuildID is set for build_started and build_completed events.
+	BuildID     *uuid.UUID          `json:"build_id,omitempty" format:"uuid"`
+	BuildNumber int32               `json:"build_number,omitempty"`
+	BuildReason BuildReason         `json:"build_reason,omitempty" enums:"initiator,autostart,autostop,template_update,batch,retry"`
+	Transition  WorkspaceTransition `json:"transition,omitempty" enums:"start,stop,delete"`
+	// JobStatus is the current status of the build, not its status at the
+	// time of the event.
+	JobStatus ProvisionerJobStatus `json:"job_status,omitempty" enums:"pending,running,succeeded,canceling,canceled,failed"`
+	// AgentID is set for agent and app events.
+	AgentID   *uuid.UUID `json:"agent_id,omitempty" format:"uuid"`
+	AgentName string     `json:"agent_name,omitempty"`
+	// AppID is set for app_health_changed events.
+	AppID     *uuid.UUID         `json:"app_id,omitempty" format:"uuid"`
+	AppSlug   string             `json:"app_slug,omitempty"`
+	OldHealth WorkspaceAppHealth `json:"old_health,omitempty" enums:"disabled,initializing,healthy,unhealthy"`
+	NewHealth WorkspaceAppHealth `json:"new_health,omitempty" enums:"disabled,initializing,healthy,unhealthy"`
+	// OldTTLMillis is the TTL before a ttl_changed event. It is unset if
+	// autostop was disabled.
+	OldTTLMillis *int64 `json:"old_ttl_ms,omitempty"`
+	// NewTTLMillis is the TTL after a ttl_changed event. It is unset if
+	// autostop was disabled.
+	NewTTLMillis *int64 `json:"new_ttl_ms,omitempty"`
+}
+
+// WorkspaceTimeline returns the timeline of a workspace, newest first.
+func (c *Client) WorkspaceTimeline(ctx context.Context, workspaceID uuid.UUID, page Pagination) ([]WorkspaceTimelineEvent, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/timeline", workspaceID), nil, page.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var events []WorkspaceTimelineEvent
+	return events, json.NewDecoder(res.Body).Decode(&events)
+}
@@ -0,0 +1,250 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/retry"
+)
+
+// Sink delivers batches of audit logs to a system outside of Coder.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Send delivers the audit logs. Failed sends are retried with the same
+	// batch, so sinks should not partially deliver a batch if they can avoid
+	// it.
+	Send(ctx context.Context, logs []ExportedAuditLog) error
+}
+
+// ExportedAuditLog is the format audit logs are sent to sinks in.
+type ExportedAuditLog struct {
+	ID               uuid.UUID             `json:"id"`
+	Time             time.Time             `json:"time"`
+	UserID           uuid.UUID             `json:"user_id"`
+	OrganizationID   uuid.UUID             `json:"organization_id"`
+	IP               string                `json:"ip"`
+	UserAgent        string                `json:"user_agent"`
+	ResourceType     database.ResourceType `json:"resource_type"`
+	ResourceID       uuid.UUID             `json:"resource_id"`
+	ResourceTarget   string                `json:"resource_target"`
+	ResourceIcon     string                `json:"resource_icon"`
+	Action           database.AuditAction  `json:"action"`
+	Diff             json.RawMessage       `json:"diff"`
+	StatusCode       int32                 `json:"status_code"`
+	AdditionalFields json.RawMessage       `json:"additional_fields"`
+	RequestID        uuid.UUID             `json:"request_id"`
+	Actor            *audit.Actor          `json:"actor,omitempty"`
+}
+
+func exportedAuditLog(alog database.AuditLog, details audit.BackendDetails) ExportedAuditLog {
+	exported := ExportedAuditLog{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     alog.ResourceType,
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           alog.Action,
+		Diff:             alog.Diff,
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: alog.AdditionalFields,
+		RequestID:        alog.RequestID,
+		Actor:            details.Actor,
+	}
+	if alog.Ip.Valid {
+		exported.IP = alog.Ip.IPNet.IP.String()
+	}
+	return exported
+}
+
+// ExporterOptions tunes how an Exporter buffers and retries.
+type ExporterOptions struct {
+	// BufferSize is how many audit logs can wait to be sent before new ones
+	// are dropped. Defaults to 1024.
+	BufferSize int
+	// BatchSize is the most audit logs sent to the sink at once. Defaults to
+	// 100.
+	BatchSize int
+	// FlushInterval is how long a partial batch waits before it is sent.
+	// Defaults to 5 seconds.
+	FlushInterval time.Duration
+	// MaxAttempts is how many times a batch is sent before it is dropped.
+	// Defaults to 5.
+	MaxAttempts int
+	// Registerer registers the dropped events counter, if set.
+	Registerer prometheus.Registerer
+}
+
+// Exporter is a backend that streams audit logs to a sink in the background,
+// so a slow or unavailable sink never blocks the request being audited.
+type Exporter struct {
+	log     slog.Logger
+	sink    Sink
+	opts    ExporterOptions
+	queue   chan ExportedAuditLog
+	dropped prometheus.Counter
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ audit.Backend = (*Exporter)(nil)
+
+// NewExporter starts streaming audit logs to the sink until the context is
+// canceled or the exporter is closed.
+func NewExporter(ctx context.Context, logger slog.Logger, sink Sink, opts ExporterOptions) *Exporter {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   "coderd",
+		Subsystem:   "audit_export",
+		Name:        "dropped_events_total",
+		Help:        "The number of audit logs that could not be exported, either because the buffer was full or the sink kept failing.",
+		ConstLabels: prometheus.Labels{"sink": sink.Name()},
+	})
+	if opts.Registerer != nil {
+		opts.Registerer.MustRegister(dropped)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	e := &Exporter{
+		log:     logger.Named("audit_export").With(slog.F("sink", sink.Name())),
+		sink:    sink,
+		opts:    opts,
+		queue:   make(chan ExportedAuditLog, opts.BufferSize),
+		dropped: dropped,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (*Exporter) Decision() audit.FilterDecision {
+	return audit.FilterDecisionExport
+}
+
+// Export queues the audit log to be sent. It never blocks, and drops the
+// audit log if the buffer is full.
+func (e *Exporter) Export(ctx context.Context, alog database.AuditLog, details audit.BackendDetails) error {
+	select {
+	case e.queue <- exportedAuditLog(alog, details):
+	default:
+		e.dropped.Inc()
+		e.log.Warn(ctx, "audit export buffer is full, dropping audit log", slog.F("audit_log_id", alog.ID))
+	}
+	return nil
+}
+
+// Close stops the exporter, making a final attempt to send any queued audit
+// logs.
+func (e *Exporter) Close() error {
+	e.closeOnce.Do(e.cancel)
+	<-e.done
+	return nil
+}
+
+func (e *Exporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ExportedAuditLog, 0, e.opts.BatchSize)
+	for {
+		select {
+		case <-e.ctx.Done():
+			e.flush(batch)
+			return
+		case exported := <-e.queue:
+			batch = append(batch, exported)
+			if len(batch) < e.opts.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		e.send(e.ctx, batch)
+		batch = make([]ExportedAuditLog, 0, e.opts.BatchSize)
+	}
+}
+
+// flush makes a single attempt to send the batch and anything left in the
+// queue once the exporter is stopping.
+func (e *Exporter) flush(batch []ExportedAuditLog) {
+	// Only this goroutine reads from the queue, so it cannot be drained
+	// from under us.
+	for len(e.queue) > 0 {
+		batch = append(batch, <-e.queue)
+	}
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := e.sink.Send(ctx, batch)
+	if err != nil {
+		e.dropped.Add(float64(len(batch)))
+		e.log.Error(ctx, "failed to export audit logs on shutdown", slog.F("count", len(batch)), slog.Error(err))
+	}
+}
+
+// send delivers the batch, retrying with a backoff. The batch is dropped
+// after the configured number of attempts.
+func (e *Exporter) send(ctx context.Context, batch []ExportedAuditLog) {
+	var (
+		attempt int
+		err     error
+	)
+	for r := retry.New(time.Second, 30*time.Second); r.Wait(ctx); {
+		attempt++
+		err = e.sink.Send(ctx, batch)
+		if err == nil {
+			return
+		}
+		e.log.Warn(ctx, "failed to export audit logs", slog.F("attempt", attempt), slog.Error(err))
+		if attempt >= e.opts.MaxAttempts {
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		// The exporter is stopping, so leave the batch for the final flush.
+		e.flush(batch)
+		return
+	}
+	e.dropped.Add(float64(len(batch)))
+	e.log.Error(ctx, "dropped audit logs after repeated export failures",
+		slog.F("count", len(batch)),
+		slog.F("attempts", attempt),
+		slog.Error(err),
+	)
+}
@@ -0,0 +1,372 @@
+package backends_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		sink := &memorySink{}
+		exporter := backends.NewExporter(ctx, slogtest.Make(t, nil), sink, backends.ExporterOptions{
+			BatchSize: 2,
+		})
+		defer exporter.Close()
+
+		alogs := []database.AuditLog{audittest.RandomLog(), audittest.RandomLog()}
+		for _, alog := range alogs {
+			err := exporter.Export(ctx, alog, audit.BackendDetails{})
+			require.NoError(t, err)
+		}
+		require.Eventually(t, func() bool {
+			return len(sink.batches()) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		batch := sink.batches()[0]
+		require.Len(t, batch, 2)
+		assert.Equal(t, alogs[0].ID, batch[0].ID)
+		assert.Equal(t, alogs[1].ID, batch[1].ID)
+	})
+
+	t.Run("FlushesOnClose", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		sink := &memorySink{}
+		exporter := backends.NewExporter(ctx, slogtest.Make(t, nil), sink, backends.ExporterOptions{})
+
+		alog := audittest.RandomLog()
+		err := exporter.Export(ctx, alog, audit.BackendDetails{})
+		require.NoError(t, err)
+		require.NoError(t, exporter.Close())
+
+		batches := sink.batches()
+		require.Len(t, batches, 1)
+		require.Len(t, batches[0], 1)
+		require.Equal(t, alog.ID, batches[0][0].ID)
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitMedium)
+
+		sink := &memorySink{failures: 1}
+		exporter := backends.NewExporter(ctx, slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), sink, backends.ExporterOptions{
+			BatchSize: 1,
+		})
+		defer exporter.Close()
+
+		err := exporter.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return len(sink.batches()) == 1
+		}, testutil.WaitMedium, testutil.IntervalFast)
+	})
+
+	t.Run("DropsWhenFull", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		sink := &memorySink{
+			sending: make(chan struct{}, 1),
+			release: make(chan struct{}),
+		}
+		registry := prometheus.NewRegistry()
+		exporter := backends.NewExporter(ctx, slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), sink, backends.ExporterOptions{
+			BufferSize: 1,
+			BatchSize:  1,
+			Registerer: registry,
+		})
+		defer exporter.Close()
+
+		// The first audit log is being sent, the second waits in the buffer,
+		// and the third is dropped.
+		err := exporter.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+		require.NoError(t, err)
+		testutil.RequireRecvCtx(ctx, t, sink.sending)
+		for i := 0; i < 2; i++ {
+			err = exporter.Export(ctx, audittest.RandomLog(), audit.BackendDetails{})
+			require.NoError(t, err)
+		}
+		require.Equal(t, float64(1), droppedEvents(t, registry))
+
+		close(sink.release)
+		require.Eventually(t, func() bool {
+			return len(sink.batches()) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	received := make(chan []backends.ExportedAuditLog, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var logs []backends.ExportedAuditLog
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&logs))
+		received <- logs
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sink := backends.NewWebhookSink(u, srv.Client())
+
+	alog := exportedLog()
+	err = sink.Send(ctx, []backends.ExportedAuditLog{alog})
+	require.NoError(t, err)
+	logs := testutil.RequireRecvCtx(ctx, t, received)
+	require.Len(t, logs, 1)
+	require.Equal(t, alog.ID, logs[0].ID)
+
+	t.Run("ErrorStatus", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		err = backends.NewWebhookSink(u, srv.Client()).Send(ctx, []backends.ExportedAuditLog{exportedLog()})
+		require.ErrorContains(t, err, "503")
+	})
+}
+
+func TestSyslogSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("UDP", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		sink, err := backends.NewSyslogSink(&url.URL{Scheme: "udp", Host: conn.LocalAddr().String()})
+		require.NoError(t, err)
+		alog := exportedLog()
+		err = sink.Send(ctx, []backends.ExportedAuditLog{alog})
+		require.NoError(t, err)
+
+		buf := make([]byte, 64*1024)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		requireSyslogMessage(t, alog, string(buf[:n]))
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = ln.Close() })
+
+		messages := make(chan string, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			// Messages are framed by their length followed by a space.
+			r := bufio.NewReader(conn)
+			var length int
+			_, err = fmt.Fscanf(r, "%d ", &length)
+			if !assert.NoError(t, err) {
+				return
+			}
+			msg := make([]byte, length)
+			_, err = io.ReadFull(r, msg)
+			if !assert.NoError(t, err) {
+				return
+			}
+			messages <- string(msg)
+		}()
+
+		sink, err := backends.NewSyslogSink(&url.URL{Scheme: "tcp", Host: ln.Addr().String()})
+		require.NoError(t, err)
+		alog := exportedLog()
+		err = sink.Send(ctx, []backends.ExportedAuditLog{alog})
+		require.NoError(t, err)
+		requireSyslogMessage(t, alog, testutil.RequireRecvCtx(ctx, t, messages))
+	})
+
+	t.Run("InvalidScheme", func(t *testing.T) {
+		t.Parallel()
+		_, err := backends.NewSyslogSink(&url.URL{Scheme: "http", Host: "localhost:514"})
+		require.Error(t, err)
+	})
+}
+
+func TestS3Sink(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+
+	type upload struct {
+		path string
+		body string
+	}
+	uploads := make(chan upload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Contains(t, r.Header.Get("Authorization"), "Credential=access-key/")
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		uploads <- upload{path: r.URL.Path, body: string(body)}
+	}))
+	t.Cleanup(srv.Close)
+
+	endpoint, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	sink, err := backends.NewS3Sink(ctx, backends.S3SinkOptions{
+		Bucket:      "audit",
+		Region:      "us-east-1",
+		Prefix:      "coder/",
+		Endpoint:    endpoint,
+		Credentials: credentials.NewStaticCredentialsProvider("access-key", "secret-key", ""),
+		HTTPClient:  srv.Client(),
+	})
+	require.NoError(t, err)
+
+	alogs := []backends.ExportedAuditLog{exportedLog(), exportedLog()}
+	err = sink.Send(ctx, alogs)
+	require.NoError(t, err)
+
+	got := testutil.RequireRecvCtx(ctx, t, uploads)
+	require.True(t, strings.HasPrefix(got.path, "/audit/coder/"), got.path)
+	require.True(t, strings.HasSuffix(got.path, ".ndjson"), got.path)
+	lines := strings.Split(strings.TrimSpace(got.body), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var alog backends.ExportedAuditLog
+		require.NoError(t, json.Unmarshal([]byte(line), &alog))
+		require.Equal(t, alogs[i].ID, alog.ID)
+	}
+}
+
+func exportedLog() backends.ExportedAuditLog {
+	alog := audittest.RandomLog()
+	return backends.ExportedAuditLog{
+		ID:           alog.ID,
+		Time:         alog.Time,
+		UserID:       alog.UserID,
+		ResourceType: alog.ResourceType,
+		ResourceID:   alog.ResourceID,
+		Action:       alog.Action,
+		Diff:         alog.Diff,
+		StatusCode:   alog.StatusCode,
+	}
+}
+
+func requireSyslogMessage(t *testing.T, alog backends.ExportedAuditLog, msg string) {
+	t.Helper()
+	require.True(t, strings.HasPrefix(msg, "<110>1 "), msg)
+	header, body, ok := strings.Cut(msg, " coder - audit - ")
+	require.True(t, ok, msg)
+	require.NotEmpty(t, header)
+	var got backends.ExportedAuditLog
+	require.NoError(t, json.Unmarshal([]byte(body), &got))
+	require.Equal(t, alog.ID, got.ID)
+}
+
+func droppedEvents(t *testing.T, registry *prometheus.Registry) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "coderd_audit_export_dropped_events_total" {
+			continue
+		}
+		var total float64
+		for _, metric := range family.GetMetric() {
+			total += metric.GetCounter().GetValue()
+		}
+		return total
+	}
+	return 0
+}
+
+// memorySink records the batches it is sent.
+type memorySink struct {
+	// failures is how many sends fail before they start succeeding.
+	failures int
+	// sending is signaled when a send starts, if set.
+	sending chan struct{}
+	// release blocks sends until it is closed, if set.
+	release chan struct{}
+
+	mu   sync.Mutex
+	sent [][]backends.ExportedAuditLog
+}
+
+func (*memorySink) Name() string {
+	return "memory"
+}
+
+func (s *memorySink) Send(ctx context.Context, logs []backends.ExportedAuditLog) error {
+	if s.sending != nil {
+		select {
+		case s.sending <- struct{}{}:
+		default:
+		}
+	}
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return xerrors.New("sink unavailable")
+	}
+	s.sent = append(s.sent, logs)
+	return nil
+}
+
+func (s *memorySink) batches() [][]backends.ExportedAuditLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]backends.ExportedAuditLog{}, s.sent...)
+}
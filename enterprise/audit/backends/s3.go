@@ -0,0 +1,129 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// S3SinkOptions configures where audit logs are uploaded.
+type S3SinkOptions struct {
+	Bucket string
+	// Region defaults to the region in the AWS config.
+	Region string
+	// Prefix is prepended to every object key.
+	Prefix string
+	// Endpoint overrides the S3 endpoint for S3-compatible storage. Objects
+	// are addressed with path-style URLs when it is set.
+	Endpoint *url.URL
+	// Credentials defaults to the AWS SDK's default credential chain.
+	Credentials aws.CredentialsProvider
+	HTTPClient  *http.Client
+}
+
+type s3Sink struct {
+	opts   S3SinkOptions
+	signer *v4.Signer
+}
+
+// NewS3Sink returns a sink that uploads each batch of audit logs as a
+// newline-delimited JSON object. Objects are keyed by date, e.g.
+// "<prefix>2024/01/02/<uuid>.ndjson".
+func NewS3Sink(ctx context.Context, opts S3SinkOptions) (Sink, error) {
+	if opts.Bucket == "" {
+		return nil, xerrors.New("s3 bucket must be set")
+	}
+	if opts.Credentials == nil || opts.Region == "" {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("load aws config: %w", err)
+		}
+		if opts.Credentials == nil {
+			opts.Credentials = cfg.Credentials
+		}
+		if opts.Region == "" {
+			opts.Region = cfg.Region
+		}
+	}
+	if opts.Region == "" {
+		return nil, xerrors.New("s3 region must be set")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &s3Sink{
+		opts:   opts,
+		signer: v4.NewSigner(),
+	}, nil
+}
+
+func (*s3Sink) Name() string {
+	return "s3"
+}
+
+func (s *s3Sink) Send(ctx context.Context, logs []ExportedAuditLog) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, alog := range logs {
+		err := enc.Encode(alog)
+		if err != nil {
+			return xerrors.Errorf("marshal audit log: %w", err)
+		}
+	}
+	payloadHash := sha256.Sum256(body.Bytes())
+
+	now := time.Now().UTC()
+	key := s.opts.Prefix + path.Join(now.Format("2006/01/02"), uuid.NewString()+".ndjson")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	creds, err := s.opts.Credentials.Retrieve(ctx)
+	if err != nil {
+		return xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = s.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), "s3", s.opts.Region, now)
+	if err != nil {
+		return xerrors.Errorf("sign request: %w", err)
+	}
+
+	res, err := s.opts.HTTPClient.Do(req)
+	if err != nil {
+		return xerrors.Errorf("put object: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("put object %q: unexpected status code %d: %s", key, res.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (s *s3Sink) objectURL(key string) *url.URL {
+	if s.opts.Endpoint != nil {
+		return s.opts.Endpoint.JoinPath(s.opts.Bucket, key)
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.opts.Bucket, s.opts.Region),
+		Path:   "/" + key,
+	}
+}
@@ -0,0 +1,126 @@
+package backends
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// syslogPriority is the "log audit" facility (13) with the informational
+// severity (6).
+const syslogPriority = 13*8 + 6
+
+type syslogSink struct {
+	network  string
+	address  string
+	hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink returns a sink that writes each audit log as an RFC 5424
+// message with a JSON body. The URL scheme picks the transport: udp, tcp, or
+// tls.
+func NewSyslogSink(u *url.URL) (Sink, error) {
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, xerrors.Errorf("unsupported syslog scheme %q, must be one of udp, tcp, or tls", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, xerrors.New("syslog address must include a host")
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSink{
+		network:  u.Scheme,
+		address:  u.Host,
+		hostname: hostname,
+	}, nil
+}
+
+func (*syslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *syslogSink) Send(ctx context.Context, logs []ExportedAuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = s.conn.SetWriteDeadline(deadline)
+	} else {
+		_ = s.conn.SetWriteDeadline(time.Time{})
+	}
+
+	for _, alog := range logs {
+		msg, err := s.format(alog)
+		if err != nil {
+			return err
+		}
+		_, err = s.conn.Write(msg)
+		if err != nil {
+			// Reconnect on the next attempt, the connection may have been
+			// closed by the server.
+			_ = s.conn.Close()
+			s.conn = nil
+			return xerrors.Errorf("write syslog message: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.network == "tls" {
+		dialer := &tls.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", s.address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, s.network, s.address)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("dial syslog %s://%s: %w", s.network, s.address, err)
+	}
+	return conn, nil
+}
+
+// format builds the RFC 5424 message for the audit log. Messages sent over a
+// stream are prefixed with their length, as described in RFC 6587.
+func (s *syslogSink) format(alog ExportedAuditLog) ([]byte, error) {
+	body, err := json.Marshal(alog)
+	if err != nil {
+		return nil, xerrors.Errorf("marshal audit log: %w", err)
+	}
+	msg := fmt.Sprintf("<%d>1 %s %s coder - audit - %s",
+		syslogPriority,
+		alog.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		body,
+	)
+	if s.network == "udp" {
+		return []byte(msg), nil
+	}
+	return []byte(fmt.Sprintf("%d %s", len(msg), msg)), nil
+}
@@ -0,0 +1,54 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/xerrors"
+)
+
+type webhookSink struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// NewWebhookSink returns a sink that POSTs each batch of audit logs to the
+// URL as a JSON array. Any response other than a 2xx is treated as a failure.
+func NewWebhookSink(u *url.URL, client *http.Client) Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &webhookSink{url: u, client: client}
+}
+
+func (*webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Send(ctx context.Context, logs []ExportedAuditLog) error {
+	body, err := json.Marshal(logs)
+	if err != nil {
+		return xerrors.Errorf("marshal audit logs: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status code %d: %s", res.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
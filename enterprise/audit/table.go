@@ -16,15 +16,20 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":          {codersdk.AuditActionCreate},
+	"Template":           {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":    {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":               {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionLogout},
+	"Workspace":          {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionPortForward},
+	"WorkspaceBuild":     {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":              {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":             {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete, codersdk.AuditActionIPRejected},
+	"License":            {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Passkey":            {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"TwoFactorPolicy":    {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Webhook":            {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"Organization":       {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"OrganizationMember": {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
 }
 
 type Action string
@@ -88,6 +93,19 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"time_til_dormant_autodelete":       ActionTrack,
 		"require_active_version":            ActionTrack,
 		"deprecated":                        ActionTrack,
+		"maintenance_window_schedule":       ActionTrack,
+		"maintenance_window_duration":       ActionTrack,
+		"visibility":                        ActionTrack,
+		"max_build_duration":                ActionTrack,
+		"max_running_workspaces":            ActionTrack,
+		"port_forwarding_deny_by_default":   ActionTrack,
+		"port_forwarding_allowed_ports":     ActionTrack,
+		"session_recording_enabled":         ActionTrack,
+		"session_recording_retention":       ActionTrack,
+		"build_retry_max_attempts":          ActionTrack,
+		"build_retry_backoff":               ActionTrack,
+		"workspace_name_pattern":            ActionTrack,
+		"workspace_name_description":        ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                      ActionTrack,
@@ -104,6 +122,9 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"created_by_avatar_url":   ActionIgnore,
 		"created_by_username":     ActionIgnore,
 		"archived":                ActionTrack,
+		"git_commit_sha":          ActionTrack,
+		"git_branch":              ActionTrack,
+		"git_tag":                 ActionTrack,
 	},
 	&database.User{}: {
 		"id":                   ActionTrack,
@@ -121,22 +142,28 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"quiet_hours_schedule": ActionTrack,
 		"theme_preference":     ActionIgnore,
 		"name":                 ActionTrack,
+		"ip_allowlist":         ActionTrack,
 	},
 	&database.Workspace{}: {
-		"id":                 ActionTrack,
-		"created_at":         ActionIgnore, // Never changes.
-		"updated_at":         ActionIgnore, // Changes, but is implicit and not helpful in a diff.
-		"owner_id":           ActionTrack,
-		"organization_id":    ActionIgnore, // Never changes.
-		"template_id":        ActionTrack,
-		"deleted":            ActionIgnore, // Changes, but is implicit when a delete event is fired.
-		"name":               ActionTrack,
-		"autostart_schedule": ActionTrack,
-		"ttl":                ActionTrack,
-		"last_used_at":       ActionIgnore,
-		"dormant_at":         ActionTrack,
-		"deleting_at":        ActionTrack,
-		"automatic_updates":  ActionTrack,
+		"id":                   ActionTrack,
+		"created_at":           ActionIgnore, // Never changes.
+		"updated_at":           ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"owner_id":             ActionTrack,
+		"organization_id":      ActionIgnore, // Never changes.
+		"template_id":          ActionTrack,
+		"deleted":              ActionIgnore, // Changes, but is implicit when a delete event is fired.
+		"name":                 ActionTrack,
+		"autostart_schedule":   ActionTrack,
+		"ttl":                  ActionTrack,
+		"last_used_at":         ActionIgnore,
+		"dormant_at":           ActionTrack,
+		"deleting_at":          ActionTrack,
+		"automatic_updates":    ActionTrack,
+		"maintenance_opt_out":  ActionTrack,
+		"user_acl":             ActionTrack,
+		"ephemeral":            ActionTrack,
+		"ephemeral_api_key_id": ActionIgnore, // Never changes.
+		"dormancy_exempt":      ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                      ActionIgnore,
@@ -153,6 +180,8 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"reason":                  ActionIgnore,
 		"daily_cost":              ActionIgnore,
 		"max_deadline":            ActionIgnore,
+		"attempt":                 ActionIgnore,
+		"retry_at":                ActionIgnore,
 		"initiator_by_avatar_url": ActionIgnore,
 		"initiator_by_username":   ActionIgnore,
 	},
@@ -167,18 +196,21 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"source":          ActionIgnore,
 	},
 	&database.APIKey{}: {
-		"id":               ActionIgnore,
-		"hashed_secret":    ActionIgnore,
-		"user_id":          ActionTrack,
-		"last_used":        ActionTrack,
-		"expires_at":       ActionTrack,
-		"created_at":       ActionTrack,
-		"updated_at":       ActionIgnore,
-		"login_type":       ActionIgnore,
-		"lifetime_seconds": ActionIgnore,
-		"ip_address":       ActionIgnore,
-		"scope":            ActionIgnore,
-		"token_name":       ActionIgnore,
+		"id":                    ActionIgnore,
+		"hashed_secret":         ActionIgnore,
+		"user_id":               ActionTrack,
+		"last_used":             ActionTrack,
+		"expires_at":            ActionTrack,
+		"created_at":            ActionTrack,
+		"updated_at":            ActionIgnore,
+		"login_type":            ActionIgnore,
+		"lifetime_seconds":      ActionIgnore,
+		"ip_address":            ActionIgnore,
+		"scope":                 ActionIgnore,
+		"token_name":            ActionIgnore,
+		"scopes":                ActionIgnore,
+		"allowed_workspace_ids": ActionIgnore,
+		"ip_allowlist":          ActionTrack,
 	},
 	&database.AuditOAuthConvertState{}: {
 		"created_at":      ActionTrack,
@@ -216,6 +248,47 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"region_id":           ActionTrack,
 		"version":             ActionTrack,
 	},
+	&database.Passkey{}: {
+		"id":            ActionTrack,
+		"user_id":       ActionTrack,
+		"name":          ActionTrack,
+		"credential_id": ActionTrack,
+		"public_key":    ActionIgnore, // Only useful to the server.
+		"sign_count":    ActionIgnore, // Changes on every login.
+		"created_at":    ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"last_used_at":  ActionIgnore, // Changes on every login.
+	},
+	&database.TwoFactorPolicy{}: {
+		"id":            ActionIgnore,
+		"role":          ActionTrack,
+		"enforce_after": ActionTrack,
+		"created_at":    ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":    ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.Webhook{}: {
+		"id":            ActionTrack,
+		"url":           ActionTrack,
+		"secret":        ActionSecret,
+		"secret_key_id": ActionIgnore, // Changes when the secret is re-encrypted.
+		"events":        ActionTrack,
+		"created_at":    ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":    ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.Organization{}: {
+		"id":          ActionTrack,
+		"name":        ActionTrack,
+		"description": ActionTrack,
+		"created_at":  ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":  ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
+	&database.AuditableOrganizationMember{}: {
+		"user_id":         ActionTrack,
+		"organization_id": ActionIgnore, // Never changes.
+		"username":        ActionIgnore, // The target of the audit log.
+		"roles":           ActionTrack,
+		"created_at":      ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+	},
 }
 
 // auditMap converts a map of struct pointers to a map of struct names as
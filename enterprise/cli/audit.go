@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func (r *RootCmd) audit() *clibase.Cmd {
+	var (
+		searchQuery string
+		limit       int64
+		cursor      string
+		formatter   = cliui.NewOutputFormatter(
+			cliui.TableFormat([]auditLogTableRow{}, []string{"time", "user", "action", "resource type", "resource target", "status code"}),
+			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
+		)
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "audit",
+		Short: "List audit logs",
+		Long:  "If there are more audit logs than the limit, a cursor for the next page is printed to stderr.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			res, err := client.AuditLogs(inv.Context(), codersdk.AuditLogsRequest{
+				SearchQuery: searchQuery,
+				Cursor:      cursor,
+				Pagination: codersdk.Pagination{
+					Limit: int(limit),
+				},
+			})
+			if err != nil {
+				return xerrors.Errorf("get audit logs: %w", err)
+			}
+
+			out, err := formatter.Format(inv.Context(), auditLogsToRows(res.AuditLogs...))
+			if err != nil {
+				return xerrors.Errorf("display audit logs: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			if res.NextCursor != "" {
+				_, _ = fmt.Fprintf(inv.Stderr, "\nNext page: coder audit --cursor %s\n", res.NextCursor)
+			}
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "search",
+			FlagShorthand: "s",
+			Description:   "Filter audit logs with a search query, e.g. \"resource_type:workspace action:delete\".",
+			Value:         clibase.StringOf(&searchQuery),
+		},
+		{
+			Flag:        "limit",
+			Description: "Maximum number of audit logs to return.",
+			Default:     "25",
+			Value:       clibase.Int64Of(&limit),
+		},
+		{
+			Flag:        "cursor",
+			Description: "Cursor returned by a previous call to fetch the next page of audit logs.",
+			Value:       clibase.StringOf(&cursor),
+		},
+	}
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type auditLogTableRow struct {
+	// For json output:
+	AuditLog codersdk.AuditLog `table:"-"`
+
+	// For table output:
+	ID             uuid.UUID `json:"-" table:"id"`
+	Time           time.Time `json:"-" table:"time,default_sort_desc"`
+	User           string    `json:"-" table:"user"`
+	Action         string    `json:"-" table:"action"`
+	ResourceType   string    `json:"-" table:"resource type"`
+	ResourceTarget string    `json:"-" table:"resource target"`
+	StatusCode     int32     `json:"-" table:"status code"`
+	IP             string    `json:"-" table:"ip"`
+}
+
+func auditLogsToRows(logs ...codersdk.AuditLog) []auditLogTableRow {
+	rows := make([]auditLogTableRow, 0, len(logs))
+	for _, log := range logs {
+		user := ""
+		if log.User != nil {
+			user = log.User.Username
+		}
+		rows = append(rows, auditLogTableRow{
+			AuditLog:       log,
+			ID:             log.ID,
+			Time:           log.Time,
+			User:           user,
+			Action:         string(log.Action),
+			ResourceType:   string(log.ResourceType),
+			ResourceTarget: log.ResourceTarget,
+			StatusCode:     log.StatusCode,
+			IP:             log.IP.String(),
+		})
+	}
+
+	return rows
+}
@@ -17,6 +17,7 @@ func (r *RootCmd) groupList() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
 		cliui.TableFormat([]groupTableRow{}, nil),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 	)
 
 	client := new(codersdk.Client)
@@ -136,6 +136,23 @@ func validJWT(s string) error {
 	return xerrors.New("Invalid license")
 }
 
+// withHumanExpiry adds the expiry of each license in a human readable format
+// to its claims, for the structured output of licenses.
+func withHumanExpiry(data any) (any, error) {
+	list, ok := data.([]codersdk.License)
+	if !ok {
+		return nil, xerrors.Errorf("invalid data type %T", data)
+	}
+	for i := range list {
+		humanExp, err := list[i].ExpiresAt()
+		if err == nil {
+			list[i].Claims[codersdk.LicenseExpiryClaim+"_human"] = humanExp.Format(time.RFC3339)
+		}
+	}
+
+	return list, nil
+}
+
 func (r *RootCmd) licensesList() *clibase.Cmd {
 	type tableLicense struct {
 		ID         int32     `table:"id,default_sort"`
@@ -191,20 +208,8 @@ func (r *RootCmd) licensesList() *clibase.Cmd {
 				}
 				return out, nil
 			}),
-		cliui.ChangeFormatterData(cliui.JSONFormat(), func(data any) (any, error) {
-			list, ok := data.([]codersdk.License)
-			if !ok {
-				return nil, xerrors.Errorf("invalid data type %T", data)
-			}
-			for i := range list {
-				humanExp, err := list[i].ExpiresAt()
-				if err == nil {
-					list[i].Claims[codersdk.LicenseExpiryClaim+"_human"] = humanExp.Format(time.RFC3339)
-				}
-			}
-
-			return list, nil
-		}),
+		cliui.ChangeFormatterData(cliui.JSONFormat(), withHumanExpiry),
+		cliui.ChangeFormatterData(cliui.YAMLFormat(), withHumanExpiry),
 	)
 
 	client := new(codersdk.Client)
@@ -4,8 +4,11 @@ package cli
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"time"
 
@@ -22,9 +25,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/drpc"
+	"github.com/coder/coder/v2/provisioner/kubernetes"
+	"github.com/coder/coder/v2/provisioner/pulumi"
 	"github.com/coder/coder/v2/provisioner/terraform"
 	"github.com/coder/coder/v2/provisionerd"
 	provisionerdproto "github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 )
@@ -38,6 +44,7 @@ func (r *RootCmd) provisionerDaemons() *clibase.Cmd {
 		},
 		Children: []*clibase.Cmd{
 			r.provisionerDaemonStart(),
+			r.provisionerKeys(),
 		},
 	}
 
@@ -54,6 +61,30 @@ func validateProvisionerDaemonName(name string) error {
 	return nil
 }
 
+// configureClientCertificate makes the client present a certificate, for
+// deployments that authenticate clients with mTLS.
+func configureClientCertificate(client *codersdk.Client, certFile, keyFile string) error {
+	if certFile == "" || keyFile == "" {
+		return xerrors.New("both a client certificate and key must be provided")
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return xerrors.Errorf("load client certificate: %w", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	// Keep the headers set by the root command.
+	if headerTransport, ok := client.HTTPClient.Transport.(*codersdk.HeaderTransport); ok {
+		headerTransport.Transport = transport
+	} else {
+		client.HTTPClient.Transport = transport
+	}
+	return nil
+}
+
 func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 	var (
 		cacheDir       string
@@ -62,11 +93,18 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 		logStackdriver string
 		logFilter      []string
 		name           string
+		rawOrg         string
 		rawTags        []string
 		pollInterval   time.Duration
 		pollJitter     time.Duration
 		preSharedKey   string
+		provisionerKey string
+		tlsClientCert  string
+		tlsClientKey   string
 		verbose        bool
+
+		sharedCache         bool
+		sharedCacheS3Bucket string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -98,6 +136,14 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				return err
 			}
 
+			var orgID uuid.UUID
+			if rawOrg != "" {
+				orgID, err = uuid.Parse(rawOrg)
+				if err != nil {
+					return xerrors.Errorf("parse organization ID %q: %w", rawOrg, err)
+				}
+			}
+
 			logOpts := []clilog.Option{
 				clilog.WithFilter(logFilter...),
 				clilog.WithHuman(logHuman),
@@ -122,6 +168,20 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				logger.Info(ctx, "see https://github.com/coder/coder/issues/6442 for details")
 			}
 
+			if tlsClientCert != "" || tlsClientKey != "" {
+				err = configureClientCertificate(client, tlsClientCert, tlsClientKey)
+				if err != nil {
+					return err
+				}
+			}
+
+			if preSharedKey != "" && provisionerKey != "" {
+				return xerrors.New("cannot provide both a pre-shared key and a provisioner key")
+			}
+			if provisionerKey != "" {
+				logger.Info(ctx, "provisioner key auth automatically sets the organization and tags of the key")
+			}
+
 			// When authorizing with a PSK, we automatically scope the provisionerd
 			// to organization. Scoping to user with PSK auth is not a valid configuration.
 			if preSharedKey != "" {
@@ -139,6 +199,20 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				return err
 			}
 
+			var cache *tfcache.Cache
+			if sharedCache {
+				cacheOpts := tfcache.Options{
+					Dir: filepath.Join(cacheDir, "shared-cache"),
+				}
+				if sharedCacheS3Bucket != "" {
+					cacheOpts.S3 = &tfcache.S3StoreOptions{Bucket: sharedCacheS3Bucket}
+				}
+				cache, err = tfcache.New(ctx, cacheOpts)
+				if err != nil {
+					return xerrors.Errorf("create shared cache: %w", err)
+				}
+			}
+
 			terraformClient, terraformServer := drpc.MemTransportPipe()
 			go func() {
 				<-ctx.Done()
@@ -157,6 +231,7 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 						WorkDirectory: tempDir,
 					},
 					CachePath: cacheDir,
+					Cache:     cache,
 				})
 				if err != nil && !xerrors.Is(err, context.Canceled) {
 					select {
@@ -166,21 +241,103 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 				}
 			}()
 
-			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", tags), slog.F("name", name))
-
 			connector := provisionerd.LocalProvisioners{
 				string(database.ProvisionerTypeTerraform): proto.NewDRPCProvisionerClient(terraformClient),
 			}
+			provisioners := []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeTerraform,
+			}
+
+			// Unlike Terraform, Pulumi is not installed automatically, so its
+			// jobs are only acquired when it is already available.
+			pulumiPath, err := pulumi.BinaryPath(ctx)
+			if err != nil {
+				logger.Debug(ctx, "pulumi provisioner disabled", slog.Error(err))
+			} else {
+				pulumiDir := filepath.Join(cacheDir, "pulumi")
+				err = os.MkdirAll(pulumiDir, 0o700)
+				if err != nil {
+					return xerrors.Errorf("mkdir pulumi dir: %w", err)
+				}
+
+				pulumiClient, pulumiServer := drpc.MemTransportPipe()
+				go func() {
+					<-ctx.Done()
+					_ = pulumiClient.Close()
+					_ = pulumiServer.Close()
+				}()
+				go func() {
+					defer cancel()
+
+					err := pulumi.Serve(ctx, &pulumi.ServeOptions{
+						ServeOptions: &provisionersdk.ServeOptions{
+							Listener:      pulumiServer,
+							Logger:        logger.Named("pulumi"),
+							WorkDirectory: tempDir,
+						},
+						BinaryPath: pulumiPath,
+						CachePath:  pulumiDir,
+					})
+					if err != nil && !xerrors.Is(err, context.Canceled) {
+						select {
+						case errCh <- err:
+						default:
+						}
+					}
+				}()
+
+				connector[string(database.ProvisionerTypePulumi)] = proto.NewDRPCProvisionerClient(pulumiClient)
+				provisioners = append(provisioners, codersdk.ProvisionerTypePulumi)
+			}
+
+			// Kubernetes manifests are applied with the service account of the
+			// pod, so they are only supported when running in a cluster.
+			kubernetesConfig, err := kubernetes.InClusterConfig()
+			if err != nil {
+				logger.Debug(ctx, "kubernetes provisioner disabled", slog.Error(err))
+			} else {
+				kubernetesClient, kubernetesServer := drpc.MemTransportPipe()
+				go func() {
+					<-ctx.Done()
+					_ = kubernetesClient.Close()
+					_ = kubernetesServer.Close()
+				}()
+				go func() {
+					defer cancel()
+
+					err := kubernetes.Serve(ctx, &kubernetes.ServeOptions{
+						ServeOptions: &provisionersdk.ServeOptions{
+							Listener:      kubernetesServer,
+							Logger:        logger.Named("kubernetes"),
+							WorkDirectory: tempDir,
+						},
+						Config: kubernetesConfig,
+					})
+					if err != nil && !xerrors.Is(err, context.Canceled) {
+						select {
+						case errCh <- err:
+						default:
+						}
+					}
+				}()
+
+				connector[string(database.ProvisionerTypeKubernetes)] = proto.NewDRPCProvisionerClient(kubernetesClient)
+				provisioners = append(provisioners, codersdk.ProvisionerTypeKubernetes)
+			}
+
+			logger.Info(ctx, "starting provisioner daemon", slog.F("tags", tags), slog.F("name", name), slog.F("organization_id", rawOrg), slog.F("provisioners", provisioners))
+
 			id := uuid.New()
 			srv := provisionerd.New(func(ctx context.Context) (provisionerdproto.DRPCProvisionerDaemonClient, error) {
 				return client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
-					ID:   id,
-					Name: name,
-					Provisioners: []codersdk.ProvisionerType{
-						codersdk.ProvisionerTypeTerraform,
-					},
-					Tags:         tags,
-					PreSharedKey: preSharedKey,
+					ID:                 id,
+					Name:               name,
+					Provisioners:       provisioners,
+					Tags:               tags,
+					PreSharedKey:       preSharedKey,
+					ProvisionerKey:     provisionerKey,
+					Organization:       orgID,
+					OrganizationScoped: orgID != uuid.Nil,
 				})
 			}, &provisionerd.Options{
 				Logger:         logger,
@@ -250,6 +407,37 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 			Description: "Pre-shared key to authenticate with Coder server.",
 			Value:       clibase.StringOf(&preSharedKey),
 		},
+		{
+			Flag:        "key",
+			Env:         "CODER_PROVISIONER_DAEMON_KEY",
+			Description: "Provisioner key to authenticate with Coder server. The daemon is scoped to the organization of the key, and has its tags.",
+			Value:       clibase.StringOf(&provisionerKey),
+		},
+		{
+			Flag:        "tls-client-cert-file",
+			Env:         "CODER_PROVISIONER_DAEMON_TLS_CLIENT_CERT_FILE",
+			Description: "Path to a PEM-encoded certificate to present to Coder server, if it requires client certificates.",
+			Value:       clibase.StringOf(&tlsClientCert),
+		},
+		{
+			Flag:        "tls-client-key-file",
+			Env:         "CODER_PROVISIONER_DAEMON_TLS_CLIENT_KEY_FILE",
+			Description: "Path to the PEM-encoded key of the client certificate.",
+			Value:       clibase.StringOf(&tlsClientKey),
+		},
+		{
+			Flag:        "shared-cache",
+			Env:         "CODER_PROVISIONER_DAEMON_SHARED_CACHE",
+			Description: "Cache the Terraform providers and modules installed by builds in the cache directory, so they are not downloaded again for every build.",
+			Value:       clibase.BoolOf(&sharedCache),
+			Default:     "false",
+		},
+		{
+			Flag:        "shared-cache-s3-bucket",
+			Env:         "CODER_PROVISIONER_DAEMON_SHARED_CACHE_S3_BUCKET",
+			Description: "Store the shared cache in this S3 bucket as well, so it is shared with provisioner daemons on other hosts. The AWS region and credentials are read from the standard AWS environment variables and config files.",
+			Value:       clibase.StringOf(&sharedCacheS3Bucket),
+		},
 		{
 			Flag:        "name",
 			Env:         "CODER_PROVISIONER_DAEMON_NAME",
@@ -257,6 +445,13 @@ func (r *RootCmd) provisionerDaemonStart() *clibase.Cmd {
 			Value:       clibase.StringOf(&name),
 			Default:     "",
 		},
+		{
+			Flag:        "org",
+			Env:         "CODER_PROVISIONER_DAEMON_ORGANIZATION",
+			Description: "ID of the organization to acquire jobs from. Defaults to acquiring jobs from every organization.",
+			Value:       clibase.StringOf(&rawOrg),
+			Default:     "",
+		},
 		{
 			Flag:        "verbose",
 			Env:         "CODER_PROVISIONER_DAEMON_VERBOSE",
@@ -0,0 +1,245 @@
+//go:build !slim
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/v2/cli"
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/pretty"
+)
+
+func (r *RootCmd) provisionerKeys() *clibase.Cmd {
+	cmd := &clibase.Cmd{
+		Use:     "keys",
+		Short:   "Manage provisioner keys",
+		Aliases: []string{"key"},
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.provisionerKeyCreate(),
+			r.provisionerKeyList(),
+			r.provisionerKeyRotate(),
+			r.provisionerKeyDelete(),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyCreate() *clibase.Cmd {
+	var rawTags []string
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "create <name>",
+		Short: "Create a provisioner key",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			tags, err := agpl.ParseProvisionerTags(rawTags)
+			if err != nil {
+				return err
+			}
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			res, err := client.CreateProvisionerKey(ctx, org.ID, codersdk.CreateProvisionerKeyRequest{
+				Name: inv.Args[0],
+				Tags: tags,
+			})
+			if err != nil {
+				return xerrors.Errorf("create provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Successfully created provisioner key %s! Save the key, it will not be displayed again:\n\n", pretty.Sprint(cliui.DefaultStyles.Keyword, res.Name))
+			_, _ = fmt.Fprintln(inv.Stdout, res.Key)
+			return nil
+		},
+	}
+
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:          "tag",
+			FlagShorthand: "t",
+			Env:           "CODER_PROVISIONERD_TAGS",
+			Description:   "Tags of the provisioner daemons authenticated with the key.",
+			Value:         clibase.StringArrayOf(&rawTags),
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyList() *clibase.Cmd {
+	formatter := cliui.NewOutputFormatter(
+		cliui.TableFormat([]provisionerKeyTableRow{}, nil),
+		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
+	)
+
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "list",
+		Short: "List provisioner keys",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			keys, err := client.ListProvisionerKeys(ctx, org.ID)
+			if err != nil {
+				return xerrors.Errorf("list provisioner keys: %w", err)
+			}
+
+			if len(keys) == 0 {
+				_, _ = fmt.Fprintln(inv.Stderr, "No provisioner keys found.")
+				return nil
+			}
+
+			rows := make([]provisionerKeyTableRow, 0, len(keys))
+			for _, key := range keys {
+				row := provisionerKeyTableRow{
+					ProvisionerKey: key,
+					Name:           key.Name,
+					CreatedAt:      key.CreatedAt,
+					Tags:           formatProvisionerTags(key.Tags),
+				}
+				if key.RotatedAt.Valid {
+					row.RotatedAt = &key.RotatedAt.Time
+				}
+				rows = append(rows, row)
+			}
+			out, err := formatter.Format(ctx, rows)
+			if err != nil {
+				return xerrors.Errorf("display provisioner keys: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(inv.Stdout, out)
+			return nil
+		},
+	}
+
+	formatter.AttachOptions(&cmd.Options)
+	return cmd
+}
+
+type provisionerKeyTableRow struct {
+	// For json output:
+	ProvisionerKey codersdk.ProvisionerKey `table:"-"`
+
+	// For table output:
+	Name      string     `json:"-" table:"name,default_sort"`
+	CreatedAt time.Time  `json:"-" table:"created_at"`
+	RotatedAt *time.Time `json:"-" table:"rotated_at"`
+	Tags      string     `json:"-" table:"tags"`
+}
+
+// formatProvisionerTags formats tags as they are passed to --tag.
+func formatProvisionerTags(tags map[string]string) string {
+	formatted := make([]string, 0, len(tags))
+	for k, v := range tags {
+		formatted = append(formatted, k+"="+v)
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, " ")
+}
+
+func (r *RootCmd) provisionerKeyRotate() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "rotate <name>",
+		Short: "Replace the secret of a provisioner key, disconnecting the daemons that use it",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			res, err := client.RotateProvisionerKey(ctx, org.ID, inv.Args[0])
+			if err != nil {
+				return xerrors.Errorf("rotate provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stderr, "Successfully rotated provisioner key %s! Save the new key, it will not be displayed again:\n\n", pretty.Sprint(cliui.DefaultStyles.Keyword, res.Name))
+			_, _ = fmt.Fprintln(inv.Stdout, res.Key)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func (r *RootCmd) provisionerKeyDelete() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "delete <name>",
+		Short: "Delete a provisioner key, disconnecting the daemons that use it",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			var (
+				ctx  = inv.Context()
+				name = inv.Args[0]
+			)
+
+			_, err := cliui.Prompt(inv, cliui.PromptOptions{
+				Text:      fmt.Sprintf("Delete provisioner key %s?", pretty.Sprint(cliui.DefaultStyles.Keyword, name)),
+				IsConfirm: true,
+				Default:   cliui.ConfirmNo,
+			})
+			if err != nil {
+				return err
+			}
+
+			org, err := agpl.CurrentOrganization(inv, client)
+			if err != nil {
+				return xerrors.Errorf("current organization: %w", err)
+			}
+
+			err = client.DeleteProvisionerKey(ctx, org.ID, name)
+			if err != nil {
+				return xerrors.Errorf("delete provisioner key: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(inv.Stdout, "Successfully deleted provisioner key %s!\n", pretty.Sprint(cliui.DefaultStyles.Keyword, name))
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		cliui.SkipPromptOption(),
+	}
+
+	return cmd
+}
@@ -57,6 +57,7 @@ func (r *RootCmd) proxyServer() *clibase.Cmd {
 		proxySessionToken clibase.String
 		primaryAccessURL  clibase.URL
 		derpOnly          clibase.Bool
+		drainTimeout      clibase.Duration
 	)
 	opts.Add(
 		// Options only for external workspace proxies
@@ -100,6 +101,18 @@ func (r *RootCmd) proxyServer() *clibase.Cmd {
 			Group:       &externalProxyOptionGroup,
 			Hidden:      false,
 		},
+		clibase.Option{
+			Name: "Drain Timeout",
+			Description: "How long to wait for open app sessions, such as terminals, to close when shutting down. " +
+				"New sessions are rejected and the /healthz endpoint fails while draining. Set to 0 to close them immediately.",
+			Flag:    "drain-timeout",
+			Env:     "CODER_PROXY_DRAIN_TIMEOUT",
+			YAML:    "drainTimeout",
+			Default: "0s",
+			Value:   &drainTimeout,
+			Group:   &externalProxyOptionGroup,
+			Hidden:  false,
+		},
 	)
 
 	cmd := &clibase.Cmd{
@@ -245,24 +258,25 @@ func (r *RootCmd) proxyServer() *clibase.Cmd {
 			}
 
 			proxy, err := wsproxy.New(ctx, &wsproxy.Options{
-				Logger:                 logger,
-				Experiments:            coderd.ReadExperiments(logger, cfg.Experiments.Value()),
-				HTTPClient:             httpClient,
-				DashboardURL:           primaryAccessURL.Value(),
-				AccessURL:              cfg.AccessURL.Value(),
-				AppHostname:            appHostname,
-				AppHostnameRegex:       appHostnameRegex,
-				RealIPConfig:           realIPConfig,
-				Tracing:                tracer,
-				PrometheusRegistry:     prometheusRegistry,
-				APIRateLimit:           int(cfg.RateLimit.API.Value()),
-				SecureAuthCookie:       cfg.SecureAuthCookie.Value(),
-				DisablePathApps:        cfg.DisablePathApps.Value(),
-				ProxySessionToken:      proxySessionToken.Value(),
-				AllowAllCors:           cfg.Dangerous.AllowAllCors.Value(),
-				DERPEnabled:            cfg.DERP.Server.Enable.Value(),
-				DERPOnly:               derpOnly.Value(),
-				DERPServerRelayAddress: cfg.DERP.Server.RelayURL.String(),
+				Logger:                  logger,
+				Experiments:             coderd.ReadExperiments(logger, cfg.Experiments.Value()),
+				HTTPClient:              httpClient,
+				DashboardURL:            primaryAccessURL.Value(),
+				AccessURL:               cfg.AccessURL.Value(),
+				AppHostname:             appHostname,
+				AppHostnameRegex:        appHostnameRegex,
+				RealIPConfig:            realIPConfig,
+				Tracing:                 tracer,
+				PrometheusRegistry:      prometheusRegistry,
+				APIRateLimit:            int(cfg.RateLimit.API.Value()),
+				SecureAuthCookie:        cfg.SecureAuthCookie.Value(),
+				DisablePathApps:         cfg.DisablePathApps.Value(),
+				ProxySessionToken:       proxySessionToken.Value(),
+				AllowAllCors:            cfg.Dangerous.AllowAllCors.Value(),
+				DERPEnabled:             cfg.DERP.Server.Enable.Value(),
+				DERPOnly:                derpOnly.Value(),
+				DERPServerRelayAddress:  cfg.DERP.Server.RelayURL.String(),
+				DERPHealthCheckInterval: cfg.DERP.Config.HealthCheckInterval.Value(),
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace proxy: %w", err)
@@ -335,6 +349,18 @@ func (r *RootCmd) proxyServer() *clibase.Cmd {
 				cliui.Errorf(inv.Stderr, "Notify systemd failed: %s", err)
 			}
 
+			// Wait for open app sessions to close before shutting down the
+			// API server, which would kill them.
+			if drainTimeout.Value() > 0 {
+				cliui.Infof(inv.Stdout, "Draining app sessions (timeout %s)...\n", drainTimeout.Value())
+				err = shutdownWithTimeout(proxy.Drain, drainTimeout.Value())
+				if err != nil {
+					cliui.Warnf(inv.Stderr, "Closing remaining app sessions: %s\n", err)
+				} else {
+					cliui.Info(inv.Stdout, "Drained app sessions\n")
+				}
+			}
+
 			// Stop accepting new connections without interrupting
 			// in-flight requests, give in-flight requests 5 seconds to
 			// complete.
@@ -17,6 +17,7 @@ func (r *RootCmd) enterpriseOnly() []*clibase.Cmd {
 		r.licenses(),
 		r.groups(),
 		r.provisionerDaemons(),
+		r.audit(),
 	}
 }
 
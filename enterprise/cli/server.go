@@ -5,7 +5,6 @@ package cli
 import (
 	"context"
 	"database/sql"
-	"encoding/base64"
 	"errors"
 	"io"
 	"net/url"
@@ -20,6 +19,7 @@ import (
 	"github.com/coder/coder/v2/enterprise/audit/backends"
 	"github.com/coder/coder/v2/enterprise/coderd"
 	"github.com/coder/coder/v2/enterprise/coderd/dormancy"
+	"github.com/coder/coder/v2/enterprise/coderd/workspacecosts"
 	"github.com/coder/coder/v2/enterprise/dbcrypt"
 	"github.com/coder/coder/v2/enterprise/trialer"
 	"github.com/coder/coder/v2/tailnet"
@@ -52,11 +52,20 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 			}
 		}
 		options.DERPServer.SetMeshKey(meshKey)
+
+		auditBackends := []audit.Backend{
+			backends.NewPostgres(options.Database, true),
+			backends.NewSlog(options.Logger),
+		}
+		exporters, err := auditExporters(ctx, options)
+		if err != nil {
+			return nil, nil, err
+		}
+		auditBackends = append(auditBackends, exporters...)
 		options.Auditor = audit.NewAuditor(
 			options.Database,
 			audit.DefaultFilter,
-			backends.NewPostgres(options.Database, true),
-			backends.NewSlog(options.Logger),
+			auditBackends...,
 		)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
@@ -73,17 +82,21 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 			DefaultQuietHoursSchedule: options.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
 			ProvisionerDaemonPSK:      options.DeploymentValues.Provisioner.DaemonPSK.Value(),
 
-			CheckInactiveUsersCancelFunc: dormancy.CheckInactiveUsers(ctx, options.Logger, options.Database),
+			CheckInactiveUsersCancelFunc:   dormancy.CheckInactiveUsers(ctx, options.Logger, options.Database),
+			RecordWorkspaceCostsCancelFunc: workspacecosts.Record(ctx, options.Logger, options.Database),
 		}
 
 		if encKeys := options.DeploymentValues.ExternalTokenEncryptionKeys.Value(); len(encKeys) != 0 {
-			keys := make([][]byte, 0, len(encKeys))
-			for idx, ek := range encKeys {
-				dk, err := base64.StdEncoding.DecodeString(ek)
+			var kms dbcrypt.KMS
+			if kmsURL := options.DeploymentValues.ExternalTokenEncryptionKMSURL.Value(); kmsURL != "" {
+				kms, err = dbcrypt.NewKMS(kmsURL, options.DeploymentValues.ExternalTokenEncryptionKMSToken.Value(), nil)
 				if err != nil {
-					return nil, nil, xerrors.Errorf("decode external-token-encryption-key %d: %w", idx, err)
+					return nil, nil, xerrors.Errorf("initialize external-token-encryption-kms: %w", err)
 				}
-				keys = append(keys, dk)
+			}
+			keys, err := dbcrypt.DecodeKeys(ctx, kms, encKeys)
+			if err != nil {
+				return nil, nil, xerrors.Errorf("decode external-token-encryption-keys: %w", err)
 			}
 			cs, err := dbcrypt.NewCiphers(keys...)
 			if err != nil {
@@ -104,3 +117,41 @@ func (r *RootCmd) Server(_ func()) *clibase.Cmd {
 	)
 	return cmd
 }
+
+// auditExporters returns a backend for each configured audit log export sink.
+// The exporters stop, flushing any queued audit logs, when the context is
+// canceled.
+func auditExporters(ctx context.Context, options *agplcoderd.Options) ([]audit.Backend, error) {
+	cfg := options.DeploymentValues.AuditLogExport
+	var sinks []backends.Sink
+	if cfg.S3Bucket.Value() != "" {
+		sink, err := backends.NewS3Sink(ctx, backends.S3SinkOptions{
+			Bucket: cfg.S3Bucket.Value(),
+			Region: cfg.S3Region.Value(),
+			Prefix: cfg.S3Prefix.Value(),
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("create audit log s3 sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.WebhookURL.String() != "" {
+		sinks = append(sinks, backends.NewWebhookSink(cfg.WebhookURL.Value(), options.HTTPClient))
+	}
+	if cfg.SyslogAddress.String() != "" {
+		sink, err := backends.NewSyslogSink(cfg.SyslogAddress.Value())
+		if err != nil {
+			return nil, xerrors.Errorf("create audit log syslog sink: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	exporters := make([]audit.Backend, 0, len(sinks))
+	for _, sink := range sinks {
+		exporters = append(exporters, backends.NewExporter(ctx, options.Logger, sink, backends.ExporterOptions{
+			BufferSize: int(cfg.BufferSize.Value()),
+			Registerer: options.PrometheusRegistry,
+		}))
+	}
+	return exporters, nil
+}
@@ -4,6 +4,7 @@ package cli
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"strings"
@@ -29,6 +30,7 @@ func (r *RootCmd) dbcryptCmd() *clibase.Cmd {
 	dbcryptCmd.AddSubcommands(
 		r.dbcryptDecryptCmd(),
 		r.dbcryptDeleteCmd(),
+		r.dbcryptGenerateKeyCmd(),
 		r.dbcryptRotateCmd(),
 	)
 	return dbcryptCmd
@@ -39,6 +41,12 @@ func (*RootCmd) dbcryptRotateCmd() *clibase.Cmd {
 	cmd := &clibase.Cmd{
 		Use:   "rotate",
 		Short: "Rotate database encryption keys.",
+		Long: "The tokens of each user, the variable values of each template and " +
+			"organization, and each webhook secret are re-encrypted in their own " +
+			"transaction, which is retried if Coder changes them at the same time, " +
+			"so keys can be rotated while Coder is running. Configure Coder with " +
+			"the new key first and the old keys as fallbacks, and remove the old " +
+			"keys once the rotation completes.",
 		Handler: func(inv *clibase.Invocation) error {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
@@ -51,19 +59,13 @@ func (*RootCmd) dbcryptRotateCmd() *clibase.Cmd {
 				return err
 			}
 
-			ks := [][]byte{}
-			dk, err := base64.StdEncoding.DecodeString(flags.New)
+			kms, err := newKMS(flags.KMSURL, flags.KMSToken)
 			if err != nil {
-				return xerrors.Errorf("decode new key: %w", err)
+				return err
 			}
-			ks = append(ks, dk)
-
-			for _, k := range flags.Old {
-				dk, err := base64.StdEncoding.DecodeString(k)
-				if err != nil {
-					return xerrors.Errorf("decode old key: %w", err)
-				}
-				ks = append(ks, dk)
+			ks, err := dbcrypt.DecodeKeys(ctx, kms, append([]string{flags.New}, flags.Old...))
+			if err != nil {
+				return err
 			}
 
 			ciphers, err := dbcrypt.NewCiphers(ks...)
@@ -124,13 +126,13 @@ func (*RootCmd) dbcryptDecryptCmd() *clibase.Cmd {
 				return err
 			}
 
-			ks := make([][]byte, 0, len(flags.Keys))
-			for _, k := range flags.Keys {
-				dk, err := base64.StdEncoding.DecodeString(k)
-				if err != nil {
-					return xerrors.Errorf("decode key: %w", err)
-				}
-				ks = append(ks, dk)
+			kms, err := newKMS(flags.KMSURL, flags.KMSToken)
+			if err != nil {
+				return err
+			}
+			ks, err := dbcrypt.DecodeKeys(ctx, kms, flags.Keys)
+			if err != nil {
+				return err
 			}
 
 			ciphers, err := dbcrypt.NewCiphers(ks...)
@@ -211,10 +213,62 @@ Are you sure you want to continue?`
 	return cmd
 }
 
+func (*RootCmd) dbcryptGenerateKeyCmd() *clibase.Cmd {
+	var kmsURL, kmsToken string
+	cmd := &clibase.Cmd{
+		Use:   "generate-key",
+		Short: "Generate a new database encryption key.",
+		Long: "The key is printed base64-encoded, or wrapped by the KMS if --kms-url " +
+			"is set. Add it to the external token encryption keys to enable " +
+			"encryption, or pass it to \"coder server dbcrypt rotate\" to rotate keys.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(0),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			kms, err := newKMS(kmsURL, kmsToken)
+			if err != nil {
+				return err
+			}
+
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return xerrors.Errorf("generate key: %w", err)
+			}
+			encoded := base64.StdEncoding.EncodeToString(key)
+			if kms != nil {
+				encoded, err = kms.Wrap(ctx, key)
+				if err != nil {
+					return err
+				}
+			}
+			_, _ = fmt.Fprintln(inv.Stdout, encoded)
+			return nil
+		},
+	}
+	cmd.Options = clibase.OptionSet{
+		{
+			Flag:        "kms-url",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_URL",
+			Description: "The URL of the KMS to wrap the key with, if any. Only the HashiCorp Vault transit secrets engine is supported, in the form https://vault.example.com:8200/{mount}/{key}.",
+			Value:       clibase.StringOf(&kmsURL),
+		},
+		{
+			Flag:        "kms-token",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_TOKEN",
+			Description: "The token used to authenticate with the KMS.",
+			Value:       clibase.StringOf(&kmsToken),
+		},
+	}
+	return cmd
+}
+
 type rotateFlags struct {
 	PostgresURL string
 	New         string
 	Old         []string
+	KMSURL      string
+	KMSToken    string
 }
 
 func (f *rotateFlags) attach(opts *clibase.OptionSet) {
@@ -229,17 +283,18 @@ func (f *rotateFlags) attach(opts *clibase.OptionSet) {
 		clibase.Option{
 			Flag:        "new-key",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_NEW_KEY",
-			Description: "The new external token encryption key. Must be base64-encoded.",
+			Description: "The new external token encryption key. Must be base64-encoded, or wrapped by the KMS if --kms-url is set.",
 			Value:       clibase.StringOf(&f.New),
 		},
 		clibase.Option{
 			Flag:        "old-keys",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_ENCRYPT_OLD_KEYS",
-			Description: "The old external token encryption keys. Must be a comma-separated list of base64-encoded keys.",
+			Description: "The old external token encryption keys. Must be a comma-separated list of base64-encoded keys, or of keys wrapped by the KMS if --kms-url is set.",
 			Value:       clibase.StringArrayOf(&f.Old),
 		},
-		cliui.SkipPromptOption(),
 	)
+	*opts = append(*opts, kmsOptions(&f.KMSURL, &f.KMSToken)...)
+	*opts = append(*opts, cliui.SkipPromptOption())
 }
 
 func (f *rotateFlags) valid() error {
@@ -251,6 +306,18 @@ func (f *rotateFlags) valid() error {
 		return xerrors.Errorf("no new key provided")
 	}
 
+	for i, k := range f.Old {
+		// Pedantic, but typos here will ruin your day.
+		if k == f.New {
+			return xerrors.Errorf("old key at index %d is the same as the new key", i)
+		}
+	}
+
+	// Wrapped keys are only checked once they're unwrapped by the KMS.
+	if f.KMSURL != "" {
+		return nil
+	}
+
 	if val, err := base64.StdEncoding.DecodeString(f.New); err != nil {
 		return xerrors.Errorf("new key must be base64-encoded")
 	} else if len(val) != 32 {
@@ -263,11 +330,6 @@ func (f *rotateFlags) valid() error {
 		} else if len(val) != 32 {
 			return xerrors.Errorf("old key at index %d must be exactly 32 bytes in length", i)
 		}
-
-		// Pedantic, but typos here will ruin your day.
-		if k == f.New {
-			return xerrors.Errorf("old key at index %d is the same as the new key", i)
-		}
 	}
 
 	return nil
@@ -276,6 +338,8 @@ func (f *rotateFlags) valid() error {
 type decryptFlags struct {
 	PostgresURL string
 	Keys        []string
+	KMSURL      string
+	KMSToken    string
 }
 
 func (f *decryptFlags) attach(opts *clibase.OptionSet) {
@@ -290,11 +354,12 @@ func (f *decryptFlags) attach(opts *clibase.OptionSet) {
 		clibase.Option{
 			Flag:        "keys",
 			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_DECRYPT_KEYS",
-			Description: "Keys required to decrypt existing data. Must be a comma-separated list of base64-encoded keys.",
+			Description: "Keys required to decrypt existing data. Must be a comma-separated list of base64-encoded keys, or of keys wrapped by the KMS if --kms-url is set.",
 			Value:       clibase.StringArrayOf(&f.Keys),
 		},
-		cliui.SkipPromptOption(),
 	)
+	*opts = append(*opts, kmsOptions(&f.KMSURL, &f.KMSToken)...)
+	*opts = append(*opts, cliui.SkipPromptOption())
 }
 
 func (f *decryptFlags) valid() error {
@@ -306,6 +371,11 @@ func (f *decryptFlags) valid() error {
 		return xerrors.Errorf("no keys provided")
 	}
 
+	// Wrapped keys are only checked once they're unwrapped by the KMS.
+	if f.KMSURL != "" {
+		return nil
+	}
+
 	for i, k := range f.Keys {
 		if val, err := base64.StdEncoding.DecodeString(k); err != nil {
 			return xerrors.Errorf("key at index %d must be base64-encoded", i)
@@ -342,3 +412,32 @@ func (f *deleteFlags) valid() error {
 
 	return nil
 }
+
+func kmsOptions(kmsURL, kmsToken *string) []clibase.Option {
+	return []clibase.Option{
+		{
+			Flag:        "kms-url",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_URL",
+			Description: "The URL of the KMS that wrapped the keys, if any. Only the HashiCorp Vault transit secrets engine is supported, in the form https://vault.example.com:8200/{mount}/{key}.",
+			Value:       clibase.StringOf(kmsURL),
+		},
+		{
+			Flag:        "kms-token",
+			Env:         "CODER_EXTERNAL_TOKEN_ENCRYPTION_KMS_TOKEN",
+			Description: "The token used to authenticate with the KMS.",
+			Value:       clibase.StringOf(kmsToken),
+		},
+	}
+}
+
+// newKMS returns the KMS that wrapped the keys, or nil if keys aren't wrapped.
+func newKMS(kmsURL, kmsToken string) (dbcrypt.KMS, error) {
+	if kmsURL == "" {
+		return nil, nil
+	}
+	kms, err := dbcrypt.NewKMS(kmsURL, kmsToken, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create kms: %w", err)
+	}
+	return kms, nil
+}
@@ -1,9 +1,14 @@
 package cli_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -215,6 +220,55 @@ func TestServerDBCrypt(t *testing.T) {
 	}
 }
 
+func TestServerDBCryptGenerateKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Plain", func(t *testing.T) {
+		t.Parallel()
+
+		inv, _ := newCLI(t, "server", "dbcrypt", "generate-key")
+		var out bytes.Buffer
+		inv.Stdout = &out
+		require.NoError(t, inv.Run())
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(out.String()))
+		require.NoError(t, err)
+		require.Len(t, key, 32)
+	})
+
+	t.Run("KMS", func(t *testing.T) {
+		t.Parallel()
+
+		// The fake transit engine "encrypts" by prefixing the plaintext.
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/transit/encrypt/coder" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var req map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{
+				"ciphertext": "vault:v1:" + req["plaintext"],
+			}})
+		}))
+		t.Cleanup(srv.Close)
+
+		inv, _ := newCLI(t, "server", "dbcrypt", "generate-key",
+			"--kms-url", srv.URL+"/transit/coder",
+			"--kms-token", "s.vault-token",
+		)
+		var out bytes.Buffer
+		inv.Stdout = &out
+		require.NoError(t, inv.Run())
+
+		wrapped := strings.TrimSpace(out.String())
+		require.True(t, strings.HasPrefix(wrapped, "vault:v1:"), wrapped)
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(wrapped, "vault:v1:"))
+		require.NoError(t, err)
+		require.Len(t, key, 32)
+	})
+}
+
 func genData(t *testing.T, db database.Store) []database.User {
 	t.Helper()
 	var users []database.User
@@ -101,6 +101,7 @@ func (r *RootCmd) patchProxy() *clibase.Cmd {
 				return fmt.Sprintf("Workspace Proxy %q updated successfully.", response.Name), nil
 			}),
 			cliui.JSONFormat(),
+			cliui.YAMLFormat(),
 			// Table formatter expects a slice, make a slice of one.
 			cliui.ChangeFormatterData(cliui.TableFormat([]codersdk.WorkspaceProxy{}, []string{"proxy name", "proxy url"}),
 				func(data any) (any, error) {
@@ -339,6 +340,7 @@ func (r *RootCmd) listProxies() *clibase.Cmd {
 	formatter := cliui.NewOutputFormatter(
 		cliui.TableFormat([]codersdk.WorkspaceProxy{}, []string{"name", "url", "proxy status"}),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 		cliui.ChangeFormatterData(cliui.TextFormat(), func(data any) (any, error) {
 			resp, ok := data.([]codersdk.WorkspaceProxy)
 			if !ok {
@@ -441,6 +443,7 @@ func newUpdateProxyResponseFormatter() *updateProxyResponseFormatter {
 				response.Proxy.Name, response.ProxyToken, up.primaryAccessURL), nil
 		}),
 		cliui.JSONFormat(),
+		cliui.YAMLFormat(),
 		// Table formatter expects a slice, make a slice of one.
 		cliui.ChangeFormatterData(cliui.TableFormat([]codersdk.UpdateWorkspaceProxyResponse{}, []string{"proxy name", "proxy url", "proxy token"}),
 			func(data any) (any, error) {
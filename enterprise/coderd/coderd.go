@@ -28,6 +28,7 @@ import (
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/identityprovider"
 	"github.com/coder/coder/v2/coderd/rbac"
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/codersdk"
@@ -102,6 +103,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			psk:        options.ProvisionerDaemonPSK,
 			authorizer: options.Authorizer,
 		},
+		pgCoordMetrics: tailnet.NewMetrics(options.PrometheusRegistry),
 	}
 	defer func() {
 		if err != nil {
@@ -118,6 +120,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	}
 	api.AGPL.Options.SetUserGroups = api.setUserGroups
 	api.AGPL.Options.SetUserSiteRoles = api.setUserSiteRoles
+	api.AGPL.Options.SetUserOrganizationRoles = api.setUserOrganizationRoles
 	api.AGPL.SiteHandler.AppearanceFetcher = api.fetchAppearanceConfig
 	api.AGPL.SiteHandler.RegionsFetcher = func(ctx context.Context) (any, error) {
 		// If the user can read the workspace proxy resource, return that.
@@ -147,16 +150,22 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		OAuth2Configs:               oauthConfigs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy:              api.AGPL.LifetimePolicy,
 		Optional:                    false,
 		SessionTokenFunc:            nil, // Default behavior
+		IPRejected:                  api.AGPL.AuditRejectedIP,
+		ActivityTracker:             api.AGPL.UserActivity,
 	})
 	apiKeyMiddlewareOptional := httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
 		DB:                          options.Database,
 		OAuth2Configs:               oauthConfigs,
 		RedirectToLogin:             false,
 		DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+		LifetimePolicy:              api.AGPL.LifetimePolicy,
 		Optional:                    true,
 		SessionTokenFunc:            nil, // Default behavior
+		IPRejected:                  api.AGPL.AuditRejectedIP,
+		ActivityTracker:             api.AGPL.UserActivity,
 	})
 
 	deploymentID, err := options.Database.GetDeploymentID(ctx)
@@ -202,6 +211,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Group(func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
+					api.licenseReadOnlyMW,
 				)
 				r.Post("/", api.postWorkspaceProxy)
 				r.Get("/", api.workspaceProxies)
@@ -222,6 +232,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Route("/{workspaceproxy}", func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
+					api.licenseReadOnlyMW,
 					httpmw.ExtractWorkspaceProxyParam(api.Database, deploymentID, api.AGPL.PrimaryWorkspaceProxy),
 				)
 
@@ -234,6 +245,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Use(
 				apiKeyMiddleware,
 				api.templateRBACEnabledMW,
+				api.licenseReadOnlyMW,
 				httpmw.ExtractOrganizationParam(api.Database),
 			)
 			r.Post("/", api.postGroupByOrganization)
@@ -246,15 +258,12 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/", api.groupByOrganization)
 			})
 		})
-		// TODO: provisioner daemons are not scoped to organizations in the database, so placing them
-		// under an organization route doesn't make sense.  In order to allow the /serve endpoint to
-		// work with a pre-shared key (PSK) without an API key, these routes will simply ignore the
-		// value of {organization}.  That is, the route will work with any organization ID, whether or
-		// not it exits.  This doesn't leak any information about the existence of organizations, so is
-		// fine from a security perspective, but might be a little surprising.
-		//
-		// We may in future decide to scope provisioner daemons to organizations, so we'll keep the API
-		// route as is.
+		// Provisioner daemons are only scoped to an organization when they opt in with the
+		// organization_scoped query parameter. In order to allow the /serve endpoint to work with a
+		// pre-shared key (PSK) without an API key, these routes don't use the organization param
+		// middleware. Unscoped daemons ignore the value of {organization}, and scoped daemons only
+		// check that it exists after they are authorized, so this doesn't leak any information about
+		// the existence of organizations.
 		r.Route("/organizations/{organization}/provisionerdaemons", func(r chi.Router) {
 			r.Use(
 				api.provisionerDaemonsEnabledMW,
@@ -262,10 +271,25 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.With(apiKeyMiddleware).Get("/", api.provisionerDaemons)
 			r.With(apiKeyMiddlewareOptional).Get("/serve", api.provisionerDaemonServe)
 		})
+		r.Route("/organizations/{organization}/provisionerkeys", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.provisionerDaemonsEnabledMW,
+				api.licenseReadOnlyMW,
+				httpmw.ExtractOrganizationParam(api.Database),
+			)
+			r.Get("/", api.provisionerKeys)
+			r.Post("/", api.postProvisionerKey)
+			r.Route("/{provisionerkey}", func(r chi.Router) {
+				r.Delete("/", api.deleteProvisionerKey)
+				r.Post("/rotate", api.rotateProvisionerKey)
+			})
+		})
 		r.Route("/templates/{template}/acl", func(r chi.Router) {
 			r.Use(
 				api.templateRBACEnabledMW,
 				apiKeyMiddleware,
+				api.licenseReadOnlyMW,
 				httpmw.ExtractTemplateParam(api.Database),
 			)
 			r.Get("/available", api.templateAvailablePermissions)
@@ -276,6 +300,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Use(
 				api.templateRBACEnabledMW,
 				apiKeyMiddleware,
+				api.licenseReadOnlyMW,
 				httpmw.ExtractGroupParam(api.Database),
 			)
 			r.Get("/", api.group)
@@ -291,6 +316,32 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/", api.workspaceQuota)
 			})
 		})
+		r.Route("/workspaces/{workspace}/costs", func(r chi.Router) {
+			r.Use(
+				api.templateRBACEnabledMW,
+				apiKeyMiddleware,
+				httpmw.ExtractWorkspaceParam(options.Database),
+			)
+			r.Get("/", api.workspaceCosts)
+		})
+		r.Route("/organizations/{organization}/costs", func(r chi.Router) {
+			r.Use(
+				api.templateRBACEnabledMW,
+				apiKeyMiddleware,
+				httpmw.ExtractOrganizationParam(options.Database),
+			)
+			r.Get("/", api.organizationCosts)
+		})
+		r.Route("/organizations/{organization}/quota", func(r chi.Router) {
+			r.Use(
+				api.templateRBACEnabledMW,
+				apiKeyMiddleware,
+				api.licenseReadOnlyMW,
+				httpmw.ExtractOrganizationParam(options.Database),
+			)
+			r.Get("/", api.organizationQuota)
+			r.Put("/", api.putOrganizationQuota)
+		})
 		r.Route("/appearance", func(r chi.Router) {
 			r.Group(func(r chi.Router) {
 				r.Use(
@@ -306,6 +357,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Group(func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
+					api.licenseReadOnlyMW,
 				)
 				r.Put("/", api.putAppearance)
 			})
@@ -314,6 +366,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Use(
 				api.autostopRequirementEnabledMW,
 				apiKeyMiddleware,
+				api.licenseReadOnlyMW,
 				httpmw.ExtractUserParam(options.Database),
 			)
 
@@ -324,6 +377,7 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Use(
 				apiKeyMiddleware,
 				api.oAuth2ProviderMiddleware,
+				api.licenseReadOnlyMW,
 			)
 			r.Route("/apps", func(r chi.Router) {
 				r.Get("/", api.oAuth2ProviderApps)
@@ -349,10 +403,57 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		})
 	})
 
+	// Coder acts as an OAuth2 and OpenID Connect provider so apps can
+	// authenticate users against the deployment.
+	signingKey := identityprovider.NewSigningKey(options.OAuthSigningKey)
+	api.AGPL.RootHandler.Route("/oauth2", func(r chi.Router) {
+		r.Use(api.oAuth2ProviderMiddleware)
+		r.Group(func(r chi.Router) {
+			r.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+				DB:                          options.Database,
+				OAuth2Configs:               oauthConfigs,
+				RedirectToLogin:             true,
+				DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+				LifetimePolicy:              api.AGPL.LifetimePolicy,
+				Optional:                    false,
+				SessionTokenFunc:            nil, // Default behavior
+				IPRejected:                  api.AGPL.AuditRejectedIP,
+			}))
+			r.Get("/authorize", identityprovider.Authorize(options.Database))
+		})
+		r.Post("/tokens", identityprovider.Tokens(identityprovider.TokensConfig{
+			DB:              options.Database,
+			AccessURL:       options.AccessURL,
+			SigningKey:      signingKey,
+			RefreshLifetime: options.DeploymentValues.SessionDuration.Value(),
+		}))
+		r.Get("/jwks", identityprovider.JWKS(signingKey))
+		r.Group(func(r chi.Router) {
+			r.Use(httpmw.ExtractAPIKeyMW(httpmw.ExtractAPIKeyConfig{
+				DB:                          options.Database,
+				OAuth2Configs:               oauthConfigs,
+				RedirectToLogin:             false,
+				DisableSessionExpiryRefresh: options.DeploymentValues.DisableSessionExpiryRefresh.Value(),
+				LifetimePolicy:              api.AGPL.LifetimePolicy,
+				Optional:                    false,
+				SessionTokenFunc:            identityprovider.SessionTokenFunc,
+				IPRejected:                  api.AGPL.AuditRejectedIP,
+			}))
+			r.Get("/userinfo", identityprovider.UserInfo(options.Database))
+		})
+	})
+	api.AGPL.RootHandler.With(api.oAuth2ProviderMiddleware).
+		Get("/.well-known/openid-configuration", identityprovider.Discovery(options.AccessURL))
+
 	if len(options.SCIMAPIKey) != 0 {
 		api.AGPL.RootHandler.Route("/scim/v2", func(r chi.Router) {
 			r.Use(
+				// The root handler doesn't attach request IDs, but audit
+				// entries need one.
+				httpmw.AttachRequestID,
 				api.scimEnabledMW,
+				api.scimAuthMW,
+				api.licenseReadOnlyMW,
 			)
 			r.Post("/Users", api.scimPostUser)
 			r.Route("/Users", func(r chi.Router) {
@@ -361,6 +462,14 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/{id}", api.scimGetUser)
 				r.Patch("/{id}", api.scimPatchUser)
 			})
+			r.Post("/Groups", api.scimPostGroup)
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 		})
 	}
 
@@ -466,7 +575,8 @@ type Options struct {
 	// optional pre-shared key for authentication of external provisioner daemons
 	ProvisionerDaemonPSK string
 
-	CheckInactiveUsersCancelFunc func()
+	CheckInactiveUsersCancelFunc   func()
+	RecordWorkspaceCostsCancelFunc func()
 }
 
 type API struct {
@@ -493,6 +603,9 @@ type API struct {
 
 	licenseMetricsCollector license.MetricsCollector
 	tailnetService          *tailnet.ClientService
+	// pgCoordMetrics are shared by the high availability coordinators, which
+	// are replaced whenever the feature is toggled.
+	pgCoordMetrics *tailnet.Metrics
 }
 
 func (api *API) Close() error {
@@ -510,6 +623,9 @@ func (api *API) Close() error {
 	if api.Options.CheckInactiveUsersCancelFunc != nil {
 		api.Options.CheckInactiveUsersCancelFunc()
 	}
+	if api.Options.RecordWorkspaceCostsCancelFunc != nil {
+		api.Options.RecordWorkspaceCostsCancelFunc()
+	}
 	return api.AGPL.Close()
 }
 
@@ -519,7 +635,8 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 	entitlements, err := license.Entitlements(
 		ctx, api.Database,
-		api.Logger, len(api.replicaManager.AllPrimary()), len(api.ExternalAuthConfigs), api.LicenseKeys, map[codersdk.FeatureName]bool{
+		api.Logger, len(api.replicaManager.AllPrimary()), len(api.ExternalAuthConfigs), api.LicenseKeys,
+		api.DeploymentValues.LicenseGracePeriod.Value(), map[codersdk.FeatureName]bool{
 			codersdk.FeatureAuditLog:                   api.AuditLogging,
 			codersdk.FeatureBrowserOnly:                api.BrowserOnly,
 			codersdk.FeatureSCIM:                       len(api.SCIMAPIKey) != 0,
@@ -623,7 +740,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 	if initial, changed, enabled := featureChanged(codersdk.FeatureHighAvailability); shouldUpdate(initial, changed, enabled) {
 		var coordinator agpltailnet.Coordinator
 		if enabled {
-			haCoordinator, err := tailnet.NewPGCoord(api.ctx, api.Logger, api.Pubsub, api.Database)
+			haCoordinator, err := tailnet.NewPGCoordWithMetrics(api.ctx, api.Logger, api.Pubsub, api.Database, api.pgCoordMetrics)
 			if err != nil {
 				api.Logger.Error(ctx, "unable to set up high availability coordinator", slog.Error(err))
 				// If we try to setup the HA coordinator and it fails, nothing
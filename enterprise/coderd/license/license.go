@@ -18,6 +18,8 @@ import (
 )
 
 // Entitlements processes licenses to return whether features are enabled or not.
+// Once every license has expired, the features of the expired licenses stay
+// available read-only for the grace period, if one is set.
 func Entitlements(
 	ctx context.Context,
 	db database.Store,
@@ -25,6 +27,7 @@ func Entitlements(
 	replicaCount int,
 	externalAuthCount int,
 	keys map[string]ed25519.PublicKey,
+	gracePeriod time.Duration,
 	enablements map[codersdk.FeatureName]bool,
 ) (codersdk.Entitlements, error) {
 	now := time.Now()
@@ -63,6 +66,42 @@ func Entitlements(
 	allFeatures := false
 	allFeaturesEntitlement := codersdk.EntitlementNotEntitled
 
+	addFeatures := func(claims *Claims, entitlement codersdk.Entitlement) {
+		for featureName, featureValue := range claims.Features {
+			// Can this be negative?
+			if featureValue <= 0 {
+				continue
+			}
+
+			switch featureName {
+			// User limit has special treatment as our only non-boolean feature.
+			case codersdk.FeatureUserLimit:
+				limit := featureValue
+				priorLimit := entitlements.Features[codersdk.FeatureUserLimit]
+				if priorLimit.Limit != nil && *priorLimit.Limit > limit {
+					limit = *priorLimit.Limit
+				}
+				entitlements.Features[codersdk.FeatureUserLimit] = codersdk.Feature{
+					Enabled:     true,
+					Entitlement: entitlement,
+					Limit:       &limit,
+					Actual:      &activeUserCount,
+				}
+			default:
+				entitlements.Features[featureName] = codersdk.Feature{
+					Entitlement: maxEntitlement(entitlements.Features[featureName].Entitlement, entitlement),
+					Enabled:     enablements[featureName] || featureName.AlwaysEnable(),
+				}
+			}
+		}
+
+		if claims.AllFeatures {
+			allFeatures = true
+			allFeaturesEntitlement = maxEntitlement(allFeaturesEntitlement, entitlement)
+		}
+		entitlements.RequireTelemetry = entitlements.RequireTelemetry || claims.RequireTelemetry
+	}
+
 	// Here we loop through licenses to detect enabled features.
 	for _, l := range licenses {
 		claims, err := ParseClaims(l.JWT, keys)
@@ -96,39 +135,32 @@ func Entitlements(
 			entitlements.Warnings = append(entitlements.Warnings, fmt.Sprintf("Your license expires in %d %s.", daysToExpire, day))
 		}
 
-		for featureName, featureValue := range claims.Features {
-			// Can this be negative?
-			if featureValue <= 0 {
-				continue
-			}
+		addFeatures(claims, entitlement)
+	}
 
-			switch featureName {
-			// User limit has special treatment as our only non-boolean feature.
-			case codersdk.FeatureUserLimit:
-				limit := featureValue
-				priorLimit := entitlements.Features[codersdk.FeatureUserLimit]
-				if priorLimit.Limit != nil && *priorLimit.Limit > limit {
-					limit = *priorLimit.Limit
-				}
-				entitlements.Features[codersdk.FeatureUserLimit] = codersdk.Feature{
-					Enabled:     true,
-					Entitlement: entitlement,
-					Limit:       &limit,
-					Actual:      &activeUserCount,
-				}
-			default:
-				entitlements.Features[featureName] = codersdk.Feature{
-					Entitlement: maxEntitlement(entitlements.Features[featureName].Entitlement, entitlement),
-					Enabled:     enablements[featureName] || featureName.AlwaysEnable(),
-				}
-			}
+	if !entitlements.HasLicense && gracePeriod > 0 {
+		expired, readOnlyUntil, err := readOnlyLicenses(ctx, db, logger, keys, gracePeriod, now)
+		if err != nil {
+			return entitlements, xerrors.Errorf("check license grace period: %w", err)
 		}
-
-		if claims.AllFeatures {
-			allFeatures = true
-			allFeaturesEntitlement = maxEntitlement(allFeaturesEntitlement, entitlement)
+		for _, claims := range expired {
+			entitlements.HasLicense = true
+			entitlements.Trial = claims.Trial
+			addFeatures(claims, codersdk.EntitlementGracePeriod)
+		}
+		if len(expired) > 0 {
+			entitlements.ReadOnlyUntil = &readOnlyUntil
+			entitlements.Warnings = append(entitlements.Warnings, fmt.Sprintf(
+				"Your license has expired. Enterprise features are read-only until %s and will then be disabled. Add a new license to restore them.",
+				readOnlyUntil.UTC().Format(time.RFC1123)))
+		}
+	} else if entitlements.HasLicense && gracePeriod > 0 {
+		// A valid license ends the grace period, so the next time every
+		// license expires gets a full one.
+		err := endGracePeriod(ctx, db)
+		if err != nil {
+			return entitlements, xerrors.Errorf("end license grace period: %w", err)
 		}
-		entitlements.RequireTelemetry = entitlements.RequireTelemetry || claims.RequireTelemetry
 	}
 
 	if allFeatures {
@@ -328,6 +360,100 @@ func keyFunc(keys map[string]ed25519.PublicKey) func(*jwt.Token) (interface{}, e
 	}
 }
 
+// licenseGracePeriodLayout formats the start of the grace period in the
+// database.
+const licenseGracePeriodLayout = time.RFC3339Nano
+
+// readOnlyLicenses returns the claims of the expired licenses whose features
+// stay available read-only, and when that ends. The grace period starts the
+// first time every license is seen expired, and its start is kept in the
+// database so restarting coderd does not extend it. Once the grace period is
+// over no claims are returned.
+func readOnlyLicenses(ctx context.Context, db database.Store, logger slog.Logger, keys map[string]ed25519.PublicKey, gracePeriod time.Duration, now time.Time) ([]*Claims, time.Time, error) {
+	// nolint:gocritic // Getting expired licenses is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	licenses, err := db.GetLicenses(ctx)
+	if err != nil {
+		return nil, time.Time{}, xerrors.Errorf("get licenses: %w", err)
+	}
+	var expired []*Claims
+	for _, l := range licenses {
+		claims, err := parseExpiredClaims(l.JWT, keys, now)
+		if err != nil {
+			logger.Debug(ctx, "skipping invalid expired license",
+				slog.F("id", l.ID), slog.Error(err))
+			continue
+		}
+		expired = append(expired, claims)
+	}
+	if len(expired) == 0 {
+		return nil, time.Time{}, nil
+	}
+
+	raw, err := db.GetLicenseGracePeriodStartedAt(ctx)
+	if err != nil {
+		return nil, time.Time{}, xerrors.Errorf("get grace period start: %w", err)
+	}
+	startedAt, err := time.Parse(licenseGracePeriodLayout, raw)
+	if err != nil {
+		startedAt = now
+		err = db.UpsertLicenseGracePeriodStartedAt(ctx, startedAt.Format(licenseGracePeriodLayout))
+		if err != nil {
+			return nil, time.Time{}, xerrors.Errorf("start grace period: %w", err)
+		}
+		logger.Warn(ctx, "every license has expired, enterprise features are read-only for the license grace period",
+			slog.F("grace_period", gracePeriod.String()))
+	}
+
+	ends := startedAt.Add(gracePeriod)
+	if !now.Before(ends) {
+		return nil, ends, nil
+	}
+	return expired, ends, nil
+}
+
+// endGracePeriod forgets the start of the grace period, if there is one.
+func endGracePeriod(ctx context.Context, db database.Store) error {
+	// nolint:gocritic // The grace period is a system function.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	raw, err := db.GetLicenseGracePeriodStartedAt(ctx)
+	if err != nil {
+		return err
+	}
+	if raw == "" {
+		return nil
+	}
+	return db.UpsertLicenseGracePeriodStartedAt(ctx, "")
+}
+
+// parseExpiredClaims validates a license that is past its expiry, which
+// ParseClaims rejects.
+func parseExpiredClaims(rawJWT string, keys map[string]ed25519.PublicKey, now time.Time) (*Claims, error) {
+	tok, err := jwt.ParseWithClaims(
+		rawJWT,
+		&Claims{},
+		keyFunc(keys),
+		jwt.WithValidMethods(ValidMethods),
+		jwt.WithoutClaimsValidation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := tok.Claims.(*Claims)
+	if !ok || !tok.Valid {
+		return nil, xerrors.New("unable to parse Claims")
+	}
+	if claims.Version != uint64(CurrentVersion) {
+		return nil, ErrInvalidVersion
+	}
+	if claims.ExpiresAt == nil || now.Before(claims.ExpiresAt.Time) {
+		return nil, xerrors.New("license has not expired")
+	}
+	return claims, nil
+}
+
 // maxEntitlement is the "greater" entitlement between the given values
 func maxEntitlement(e1, e2 codersdk.Entitlement) codersdk.Entitlement {
 	if e1 == codersdk.EntitlementEntitled || e2 == codersdk.EntitlementEntitled {
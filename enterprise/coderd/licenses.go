@@ -335,6 +335,32 @@ func (api *API) deleteLicense(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusOK)
 }
 
+// licenseReadOnlyMW refuses changes to enterprise features while they are
+// read-only, because every license has expired and the license grace period
+// has started.
+func (api *API) licenseReadOnlyMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		api.entitlementsMu.RLock()
+		readOnlyUntil := api.entitlements.ReadOnlyUntil
+		api.entitlementsMu.RUnlock()
+		if readOnlyUntil != nil {
+			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+				Message: "Enterprise features are read-only because your license has expired.",
+				Detail:  fmt.Sprintf("Add a new license to make changes. The features will be disabled at %s.", readOnlyUntil.UTC().Format(time.RFC1123)),
+			})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
 func convertLicense(dl database.License, c jwt.MapClaims) codersdk.License {
 	return codersdk.License{
 		ID:         dl.ID,
@@ -4,12 +4,18 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/testutil"
@@ -187,3 +193,52 @@ func TestDeleteLicense(t *testing.T) {
 		assert.Len(t, licenses, 0)
 	})
 }
+
+func TestLicenseGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.LicenseGracePeriod = clibase.Duration(7 * 24 * time.Hour)
+	client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+		},
+		DontAddLicense: true,
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:gocritic // unit test
+	_, err := api.Database.InsertLicense(testDBAuthzRole(ctx), database.InsertLicenseParams{
+		UploadedAt: dbtime.Now(),
+		Exp:        dbtime.Now().Add(-time.Hour),
+		JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+			GraceAt:   time.Now().Add(-2 * time.Hour),
+			ExpiresAt: time.Now().Add(-time.Hour),
+		}),
+	})
+	require.NoError(t, err)
+	err = api.Pubsub.Publish(coderd.PubsubEventLicenses, []byte{})
+	require.NoError(t, err)
+
+	var entitlements codersdk.Entitlements
+	require.Eventually(t, func() bool {
+		entitlements, err = client.Entitlements(ctx)
+		assert.NoError(t, err)
+		return entitlements.ReadOnlyUntil != nil
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, codersdk.EntitlementGracePeriod, entitlements.Features[codersdk.FeatureTemplateRBAC].Entitlement)
+	require.NotEmpty(t, entitlements.Warnings)
+
+	// Enterprise features can still be read, but not changed.
+	_, err = client.GroupsByOrganization(ctx, user.OrganizationID)
+	require.NoError(t, err)
+	_, err = client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+		Name: "new",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+}
@@ -1,12 +1,25 @@
 package coderd_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/identityprovider"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -365,3 +378,282 @@ func TestOAuthAppSecrets(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestOAuth2ProviderTokenExchange(t *testing.T) {
+	t.Parallel()
+
+	ownerClient, owner := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureOAuth2Provider: 1,
+		},
+	}})
+	client, user := coderdtest.CreateAnotherUser(t, ownerClient, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	app, err := ownerClient.PostOAuth2ProviderApp(ctx, codersdk.PostOAuth2ProviderAppRequest{
+		Name:        "token-exchange",
+		CallbackURL: "http://localhost:3000/callback",
+	})
+	require.NoError(t, err)
+
+	//nolint:gocritic // OAauth2 app management requires owner permission.
+	secret, err := ownerClient.PostOAuth2ProviderAppSecret(ctx, app.ID)
+	require.NoError(t, err)
+
+	config := &oauth2.Config{
+		ClientID:     app.ID.String(),
+		ClientSecret: secret.ClientSecretFull,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   client.URL.JoinPath("/oauth2/authorize").String(),
+			TokenURL:  client.URL.JoinPath("/oauth2/tokens").String(),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: app.CallbackURL,
+		Scopes:      []string{"openid"},
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		code := authorizeOAuth2App(ctx, t, client, config, "some-state")
+		token, err := config.Exchange(ctx, code)
+		require.NoError(t, err)
+		require.NotEmpty(t, token.AccessToken)
+		require.NotEmpty(t, token.RefreshToken)
+		require.True(t, token.Expiry.After(time.Now()))
+
+		// The access token authenticates as the user.
+		appClient := codersdk.New(client.URL)
+		appClient.SetSessionToken(token.AccessToken)
+		me, err := appClient.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, me.ID)
+
+		// Codes can only be used once.
+		_, err = config.Exchange(ctx, code)
+		require.Error(t, err)
+
+		// The ID token is signed by the key in the JWKS.
+		idToken, ok := token.Extra("id_token").(string)
+		require.True(t, ok)
+		res, err := client.Request(ctx, http.MethodGet, "/oauth2/jwks", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var jwks jose.JSONWebKeySet
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&jwks))
+		require.Len(t, jwks.Keys, 1)
+		var claims identityprovider.IDTokenClaims
+		_, err = jwt.ParseWithClaims(idToken, &claims, func(*jwt.Token) (interface{}, error) {
+			return jwks.Keys[0].Key, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, user.ID.String(), claims.Subject)
+		require.Equal(t, user.Email, claims.Email)
+		require.Equal(t, jwt.ClaimStrings{app.ID.String()}, claims.Audience)
+
+		// Refreshing issues new tokens and revokes the old access token.
+		refreshed, err := config.TokenSource(ctx, &oauth2.Token{
+			RefreshToken: token.RefreshToken,
+			Expiry:       time.Now().Add(-time.Minute),
+		}).Token()
+		require.NoError(t, err)
+		require.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		_, err = appClient.User(ctx, codersdk.Me)
+		require.Error(t, err)
+
+		// The refresh token can only be used once.
+		_, err = config.TokenSource(ctx, &oauth2.Token{
+			RefreshToken: token.RefreshToken,
+			Expiry:       time.Now().Add(-time.Minute),
+		}).Token()
+		require.Error(t, err)
+
+		// The userinfo endpoint accepts the access token as a bearer token.
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.URL.JoinPath("/oauth2/userinfo").String(), nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+refreshed.AccessToken)
+		res, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var info identityprovider.UserInfoResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&info))
+		require.Equal(t, user.ID.String(), info.Subject)
+		require.Equal(t, user.Username, info.PreferredUsername)
+	})
+
+	t.Run("PKCE", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		verifier := oauth2.GenerateVerifier()
+		authorize := func() string {
+			return authorizeOAuth2App(ctx, t, client, config, "",
+				oauth2.S256ChallengeOption(verifier),
+				oauth2.SetAuthURLParam("nonce", "some-nonce"))
+		}
+
+		// The code is consumed even if the verifier is missing or wrong.
+		code := authorize()
+		_, err := config.Exchange(ctx, code)
+		require.Error(t, err)
+		_, err = config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+		require.Error(t, err)
+
+		_, err = config.Exchange(ctx, authorize(), oauth2.VerifierOption(oauth2.GenerateVerifier()))
+		require.Error(t, err)
+
+		token, err := config.Exchange(ctx, authorize(), oauth2.VerifierOption(verifier))
+		require.NoError(t, err)
+
+		// The ID token carries the nonce from the authorization request.
+		idToken, ok := token.Extra("id_token").(string)
+		require.True(t, ok)
+		var claims identityprovider.IDTokenClaims
+		_, _, err = jwt.NewParser().ParseUnverified(idToken, &claims)
+		require.NoError(t, err)
+		require.Equal(t, "some-nonce", claims.Nonce)
+
+		// A verifier can't be sent for a code issued without a challenge.
+		_, err = config.Exchange(ctx, authorizeOAuth2App(ctx, t, client, config, ""), oauth2.VerifierOption(verifier))
+		require.Error(t, err)
+	})
+
+	t.Run("PlainChallenge", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res := requestOAuth2Authorize(ctx, t, client, config, "",
+			oauth2.SetAuthURLParam("code_challenge", oauth2.GenerateVerifier()),
+			oauth2.SetAuthURLParam("code_challenge_method", "plain"))
+		defer res.Body.Close()
+		require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err)
+		require.Equal(t, "invalid_request", location.Query().Get("error"))
+		require.Empty(t, location.Query().Get("code"))
+	})
+
+	t.Run("BadSecret", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		code := authorizeOAuth2App(ctx, t, client, config, "")
+		badConfig := *config
+		badConfig.ClientSecret = "not-the-secret"
+		_, err := badConfig.Exchange(ctx, code)
+		var retrieveErr *oauth2.RetrieveError
+		require.ErrorAs(t, err, &retrieveErr)
+		require.Equal(t, http.StatusUnauthorized, retrieveErr.Response.StatusCode)
+	})
+
+	t.Run("ConcurrentRefresh", func(t *testing.T) {
+		t.Parallel()
+		if !dbtestutil.WillUsePostgres() {
+			t.Skip("test requires postgres")
+		}
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		code := authorizeOAuth2App(ctx, t, client, config, "")
+		token, err := config.Exchange(ctx, code)
+		require.NoError(t, err)
+
+		// Only one of several simultaneous refreshes with the same token
+		// succeeds; the rest are rejected as an invalid grant.
+		const refreshes = 5
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			refreshed int
+			rejected  int
+		)
+		for i := 0; i < refreshes; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := config.TokenSource(ctx, &oauth2.Token{
+					RefreshToken: token.RefreshToken,
+					Expiry:       time.Now().Add(-time.Minute),
+				}).Token()
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					refreshed++
+					return
+				}
+				var retrieveErr *oauth2.RetrieveError
+				if assert.ErrorAs(t, err, &retrieveErr) {
+					assert.Equal(t, http.StatusBadRequest, retrieveErr.Response.StatusCode)
+					assert.Equal(t, "invalid_grant", retrieveErr.ErrorCode)
+				}
+				rejected++
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, 1, refreshed)
+		require.Equal(t, refreshes-1, rejected)
+	})
+
+	t.Run("BadRedirectURI", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		badConfig := *config
+		badConfig.RedirectURL = "http://example.com/callback"
+		res := requestOAuth2Authorize(ctx, t, client, &badConfig, "")
+		defer res.Body.Close()
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Discovery", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		res, err := client.Request(ctx, http.MethodGet, "/.well-known/openid-configuration", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var discovery identityprovider.DiscoveryResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&discovery))
+		require.Equal(t, config.Endpoint.TokenURL, discovery.TokenEndpoint)
+		require.Equal(t, client.URL.JoinPath("/oauth2/jwks").String(), discovery.JWKSURI)
+	})
+}
+
+// requestOAuth2Authorize makes an authorization request as the client's user
+// without following the redirect back to the app.
+func requestOAuth2Authorize(ctx context.Context, t *testing.T, client *codersdk.Client, config *oauth2.Config, state string, opts ...oauth2.AuthCodeOption) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.AuthCodeURL(state, opts...), nil)
+	require.NoError(t, err)
+	req.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := httpClient.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+// authorizeOAuth2App authorizes the app as the client's user and returns the
+// issued code.
+func authorizeOAuth2App(ctx context.Context, t *testing.T, client *codersdk.Client, config *oauth2.Config, state string, opts ...oauth2.AuthCodeOption) string {
+	t.Helper()
+
+	res := requestOAuth2Authorize(ctx, t, client, config, state, opts...)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusTemporaryRedirect, res.StatusCode)
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, state, location.Query().Get("state"))
+	code := location.Query().Get("code")
+	require.NotEmpty(t, code)
+	return code
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/coder/coder/v2/provisionersdk"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/hashicorp/yamux"
 	"github.com/moby/moby/pkg/namesgenerator"
@@ -32,6 +33,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/provisionerkey"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
@@ -66,7 +68,17 @@ func (api *API) provisionerDaemonsEnabledMW(next http.Handler) http.Handler {
 // @Router /organizations/{organization}/provisionerdaemons [get]
 func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	daemons, err := api.Database.GetProvisionerDaemons(ctx)
+	var (
+		daemons []database.ProvisionerDaemon
+		err     error
+	)
+	// Older clients request "default" as the organization, so only filter
+	// when given an organization ID.
+	if orgID, parseErr := uuid.Parse(chi.URLParam(r, "organization")); parseErr == nil {
+		daemons, err = api.Database.GetProvisionerDaemonsByOrganization(ctx, orgID)
+	} else {
+		daemons, err = api.Database.GetProvisionerDaemons(ctx)
+	}
 	if errors.Is(err, sql.ErrNoRows) {
 		err = nil
 	}
@@ -101,8 +113,9 @@ type provisionerDaemonAuth struct {
 }
 
 // authorize returns mutated tags and true if the given HTTP request is authorized to access the provisioner daemon
-// protobuf API, and returns nil, false otherwise.
-func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]string) (map[string]string, bool) {
+// protobuf API, and returns nil, false otherwise. Daemons scoped to an organization may be created by users who
+// can create provisioner daemons in that organization.
+func (p *provisionerDaemonAuth) authorize(r *http.Request, organizationID uuid.NullUUID, tags map[string]string) (map[string]string, bool) {
 	ctx := r.Context()
 	apiKey, ok := httpmw.APIKeyOptional(r)
 	if ok {
@@ -113,7 +126,11 @@ func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]strin
 			return tags, true
 		}
 		ua := httpmw.UserAuthorization(r)
-		if err := p.authorizer.Authorize(ctx, ua.Actor, rbac.ActionCreate, rbac.ResourceProvisionerDaemon); err == nil {
+		obj := rbac.ResourceProvisionerDaemon
+		if organizationID.Valid {
+			obj = obj.InOrg(organizationID.UUID)
+		}
+		if err := p.authorizer.Authorize(ctx, ua.Actor, rbac.ActionCreate, obj); err == nil {
 			// User is allowed to create provisioner daemons
 			return tags, true
 		}
@@ -131,6 +148,44 @@ func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]strin
 	return nil, false
 }
 
+// authorizeProvisionerKey authenticates a daemon with a provisioner key. The
+// daemon may only be scoped to the organization of the key, and may not set
+// other values for the tags of the key.
+func (api *API) authorizeProvisionerKey(rw http.ResponseWriter, r *http.Request, secret string, organizationID uuid.NullUUID, tags map[string]string) (database.ProvisionerKey, bool) {
+	ctx := r.Context()
+	//nolint:gocritic // Provisioner key auth means no actor in request.
+	key, err := api.Database.GetProvisionerKeyByHashedSecret(dbauthz.AsSystemRestricted(ctx), provisionerkey.HashSecret(secret))
+	if httpapi.Is404Error(err) {
+		api.Logger.Warn(ctx, "provisioner daemon serve request with invalid provisioner key")
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Invalid provisioner key.",
+		})
+		return database.ProvisionerKey{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner key.",
+			Detail:  err.Error(),
+		})
+		return database.ProvisionerKey{}, false
+	}
+	if organizationID.Valid && organizationID.UUID != key.OrganizationID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "The provisioner key belongs to a different organization.",
+		})
+		return database.ProvisionerKey{}, false
+	}
+	for k, v := range tags {
+		if keyValue, ok := key.Tags[k]; ok && keyValue != v {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: fmt.Sprintf("The provisioner key requires the tag %s=%s.", k, keyValue),
+			})
+			return database.ProvisionerKey{}, false
+		}
+	}
+	return key, true
+}
+
 // Serves the provisioner daemon protobuf API over a WebSocket.
 //
 // @Summary Serve provisioner daemon
@@ -138,6 +193,7 @@ func (p *provisionerDaemonAuth) authorize(r *http.Request, tags map[string]strin
 // @Security CoderSessionToken
 // @Tags Enterprise
 // @Param organization path string true "Organization ID" format(uuid)
+// @Param organization_scoped query bool false "Only acquire jobs from the organization"
 // @Success 101
 // @Router /organizations/{organization}/provisionerdaemons/serve [get]
 func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request) {
@@ -175,6 +231,10 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			provisionersMap[codersdk.ProvisionerTypeEcho] = struct{}{}
 		case string(codersdk.ProvisionerTypeTerraform):
 			provisionersMap[codersdk.ProvisionerTypeTerraform] = struct{}{}
+		case string(codersdk.ProvisionerTypePulumi):
+			provisionersMap[codersdk.ProvisionerTypePulumi] = struct{}{}
+		case string(codersdk.ProvisionerTypeKubernetes):
+			provisionersMap[codersdk.ProvisionerTypeKubernetes] = struct{}{}
 		default:
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message: fmt.Sprintf("Unknown provisioner type %q", provisioner),
@@ -190,7 +250,36 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		api.Logger.Warn(ctx, "unnamed provisioner daemon")
 	}
 
-	tags, authorized := api.provisionerDaemonAuth.authorize(r, tags)
+	var organizationID uuid.NullUUID
+	if r.URL.Query().Get("organization_scoped") == "true" {
+		orgID, ok := httpmw.ParseUUIDParam(rw, r, "organization")
+		if !ok {
+			return
+		}
+		organizationID = uuid.NullUUID{UUID: orgID, Valid: true}
+	}
+
+	var (
+		provisionerKey *database.ProvisionerKey
+		authorized     bool
+	)
+	if secret := r.Header.Get(codersdk.ProvisionerDaemonKey); secret != "" {
+		key, ok := api.authorizeProvisionerKey(rw, r, secret, organizationID, tags)
+		if !ok {
+			return
+		}
+		provisionerKey = &key
+		// Daemons authenticated with a key are always scoped to its
+		// organization, and always have its tags.
+		organizationID = uuid.NullUUID{UUID: key.OrganizationID, Valid: true}
+		for k, v := range key.Tags {
+			tags[k] = v
+		}
+		tags[provisionersdk.TagScope] = provisionersdk.ScopeOrganization
+		tags, authorized = provisionersdk.MutateTags(uuid.Nil, tags), true
+	} else {
+		tags, authorized = api.provisionerDaemonAuth.authorize(r, organizationID, tags)
+	}
 	if !authorized {
 		api.Logger.Warn(ctx, "unauthorized provisioner daemon serve request", slog.F("tags", tags))
 		httpapi.Write(ctx, rw, http.StatusForbidden,
@@ -201,6 +290,23 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 	api.Logger.Debug(ctx, "provisioner authorized", slog.F("tags", tags))
+	if organizationID.Valid {
+		// Only check the organization exists after authorizing, so that
+		// unauthenticated requests can't probe for organizations.
+		//nolint:gocritic // PSK auth means no actor in request.
+		_, err := api.Database.GetOrganizationByID(dbauthz.AsSystemRestricted(ctx), organizationID.UUID)
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching organization.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
 	if err := provisionerdserver.Tags(tags).Valid(); err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Given tags are not acceptable to the service",
@@ -218,19 +324,24 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 			provisioners = append(provisioners, database.ProvisionerTypeTerraform)
 		case codersdk.ProvisionerTypeEcho:
 			provisioners = append(provisioners, database.ProvisionerTypeEcho)
+		case codersdk.ProvisionerTypePulumi:
+			provisioners = append(provisioners, database.ProvisionerTypePulumi)
+		case codersdk.ProvisionerTypeKubernetes:
+			provisioners = append(provisioners, database.ProvisionerTypeKubernetes)
 		}
 	}
 
 	log := api.Logger.With(
 		slog.F("name", name),
+		slog.F("organization_id", organizationID),
 		slog.F("provisioners", provisioners),
 		slog.F("tags", tags),
 	)
 
 	authCtx := ctx
-	if r.Header.Get(codersdk.ProvisionerDaemonPSK) != "" {
-		//nolint:gocritic // PSK auth means no actor in request,
-		// so use system restricted.
+	if r.Header.Get(codersdk.ProvisionerDaemonPSK) != "" || provisionerKey != nil {
+		//nolint:gocritic // PSK and provisioner key auth means no actor in
+		// request, so use system restricted.
 		authCtx = dbauthz.AsSystemRestricted(ctx)
 	}
 
@@ -244,13 +355,14 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 	// Create the daemon in the database.
 	now := dbtime.Now()
 	daemon, err := api.Database.UpsertProvisionerDaemon(authCtx, database.UpsertProvisionerDaemonParams{
-		Name:         name,
-		Provisioners: provisioners,
-		Tags:         tags,
-		CreatedAt:    now,
-		LastSeenAt:   sql.NullTime{Time: now, Valid: true},
-		Version:      versionHdrVal,
-		APIVersion:   apiVersion,
+		Name:           name,
+		Provisioners:   provisioners,
+		Tags:           tags,
+		CreatedAt:      now,
+		LastSeenAt:     sql.NullTime{Time: now, Valid: true},
+		Version:        versionHdrVal,
+		APIVersion:     apiVersion,
+		OrganizationID: organizationID,
 	})
 	if err != nil {
 		if !xerrors.Is(err, context.Canceled) {
@@ -329,6 +441,9 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		provisionerdserver.Options{
 			ExternalAuthConfigs: api.ExternalAuthConfigs,
 			OIDCConfig:          api.OIDCConfig,
+			Webhooks:            api.AGPL.Webhooks,
+			Notifications:       api.AGPL.Notifications,
+			OrganizationID:      organizationID,
 		},
 	)
 	if err != nil {
@@ -343,6 +458,18 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("drpc register provisioner daemon: %s", err))
 		return
 	}
+	if provisionerKey != nil {
+		// Disconnect the daemon as soon as its key is deleted or rotated.
+		cancelSub, err := api.Pubsub.Subscribe(provisionerKeyChannel(*provisionerKey), func(_ context.Context, _ []byte) {
+			logger.Info(ctx, "provisioner key revoked, disconnecting daemon", slog.F("provisioner_key_id", provisionerKey.ID))
+			_ = conn.Close(websocket.StatusPolicyViolation, "provisioner key revoked")
+		})
+		if err != nil {
+			_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("subscribe to provisioner key: %s", err))
+			return
+		}
+		defer cancelSub()
+	}
 	server := drpcserver.NewWithOptions(mux, drpcserver.Options{
 		Log: func(err error) {
 			if xerrors.Is(err, io.EOF) {
@@ -0,0 +1,218 @@
+package coderd
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/provisionerkey"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// provisionerKeyChannel is published to when a provisioner key is deleted or
+// rotated, so daemons authenticated with it are disconnected on every replica.
+func provisionerKeyChannel(key database.ProvisionerKey) string {
+	return fmt.Sprintf("provisioner_key:%s", key.ID)
+}
+
+// @Summary Create provisioner key
+// @ID create-provisioner-key
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateProvisionerKeyRequest true "Create provisioner key request"
+// @Success 201 {object} codersdk.CreateProvisionerKeyResponse
+// @Router /organizations/{organization}/provisionerkeys [post]
+func (api *API) postProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	var req codersdk.CreateProvisionerKeyRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if err := validateProvisionerKeyTags(req.Tags); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid provisioner key tags.",
+			Validations: []codersdk.ValidationError{{Field: "tags", Detail: err.Error()}},
+		})
+		return
+	}
+
+	params, secret, err := provisionerkey.New(org.ID, req.Name, req.Tags)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	key, err := api.Database.InsertProvisionerKey(ctx, params)
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message:     fmt.Sprintf("A provisioner key named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{{Field: "name", Detail: "Provisioner key names must be unique"}},
+		})
+		return
+	}
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateProvisionerKeyResponse{
+		ProvisionerKey: db2sdk.ProvisionerKey(key),
+		Key:            secret,
+	})
+}
+
+// @Summary List provisioner keys
+// @ID list-provisioner-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ProvisionerKey
+// @Router /organizations/{organization}/provisionerkeys [get]
+func (api *API) provisionerKeys(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = r.Context()
+		org = httpmw.OrganizationParam(r)
+	)
+
+	keys, err := api.Database.ListProvisionerKeysByOrganization(ctx, org.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	apiKeys := make([]codersdk.ProvisionerKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, db2sdk.ProvisionerKey(key))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiKeys)
+}
+
+// @Summary Rotate provisioner key
+// @ID rotate-provisioner-key
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param provisionerkey path string true "Provisioner key name"
+// @Success 200 {object} codersdk.CreateProvisionerKeyResponse
+// @Router /organizations/{organization}/provisionerkeys/{provisionerkey}/rotate [post]
+func (api *API) rotateProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key, ok := api.provisionerKeyParam(rw, r)
+	if !ok {
+		return
+	}
+
+	secret, hashed, err := provisionerkey.NewSecret()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	key, err = api.Database.UpdateProvisionerKeySecretByID(ctx, database.UpdateProvisionerKeySecretByIDParams{
+		ID:           key.ID,
+		HashedSecret: hashed,
+		RotatedAt:    sql.NullTime{Time: dbtime.Now(), Valid: true},
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	api.publishProvisionerKeyRevoked(r, key)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.CreateProvisionerKeyResponse{
+		ProvisionerKey: db2sdk.ProvisionerKey(key),
+		Key:            secret,
+	})
+}
+
+// @Summary Delete provisioner key
+// @ID delete-provisioner-key
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param provisionerkey path string true "Provisioner key name"
+// @Success 204
+// @Router /organizations/{organization}/provisionerkeys/{provisionerkey} [delete]
+func (api *API) deleteProvisionerKey(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key, ok := api.provisionerKeyParam(rw, r)
+	if !ok {
+		return
+	}
+
+	err := api.Database.DeleteProvisionerKey(ctx, key.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	api.publishProvisionerKeyRevoked(r, key)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// provisionerKeyParam fetches the key named by the {provisionerkey} URL
+// parameter in the organization.
+func (api *API) provisionerKeyParam(rw http.ResponseWriter, r *http.Request) (database.ProvisionerKey, bool) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+	key, err := api.Database.GetProvisionerKeyByName(ctx, database.GetProvisionerKeyByNameParams{
+		OrganizationID: org.ID,
+		Name:           chi.URLParam(r, "provisionerkey"),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.ProvisionerKey{}, false
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return database.ProvisionerKey{}, false
+	}
+	return key, true
+}
+
+// publishProvisionerKeyRevoked disconnects the daemons authenticated with the
+// previous secret of the key. The key has already been changed, so on failure
+// the daemons stay connected until they reconnect and are rejected.
+func (api *API) publishProvisionerKeyRevoked(r *http.Request, key database.ProvisionerKey) {
+	err := api.Pubsub.Publish(provisionerKeyChannel(key), []byte{})
+	if err != nil {
+		api.Logger.Warn(r.Context(), "publish provisioner key revoked",
+			slog.F("provisioner_key_id", key.ID), slog.Error(err))
+	}
+}
+
+// validateProvisionerKeyTags checks the tags of a provisioner key. Daemons
+// authenticated with a key are scoped to its organization, so keys may not
+// set the scope or owner of daemons.
+func validateProvisionerKeyTags(tags map[string]string) error {
+	if scope, ok := tags[provisionersdk.TagScope]; ok && scope != provisionersdk.ScopeOrganization {
+		return xerrors.Errorf("the %q tag must be %q", provisionersdk.TagScope, provisionersdk.ScopeOrganization)
+	}
+	if owner, ok := tags[provisionersdk.TagOwner]; ok && owner != "" {
+		return xerrors.Errorf("the %q tag may not be set", provisionersdk.TagOwner)
+	}
+	return provisionerdserver.Tags(tags).Valid()
+}
@@ -0,0 +1,255 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestProvisionerKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // Only owners can manage provisioner keys.
+		created, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+			Tags: map[string]string{"data_center": "chicago"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Key)
+		require.Equal(t, "chicago", created.Name)
+		require.False(t, created.RotatedAt.Valid)
+
+		//nolint:gocritic // Only owners can manage provisioner keys.
+		_, err = client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		keys, err := client.ListProvisionerKeys(ctx, user.OrganizationID) //nolint:gocritic // Test assertion.
+		require.NoError(t, err)
+		if assert.Len(t, keys, 1) {
+			assert.Equal(t, created.ID, keys[0].ID)
+			assert.Equal(t, map[string]string{"data_center": "chicago"}, keys[0].Tags)
+		}
+
+		//nolint:gocritic // Only owners can manage provisioner keys.
+		rotated, err := client.RotateProvisionerKey(ctx, user.OrganizationID, "chicago")
+		require.NoError(t, err)
+		require.NotEqual(t, created.Key, rotated.Key)
+		require.True(t, rotated.RotatedAt.Valid)
+
+		err = client.DeleteProvisionerKey(ctx, user.OrganizationID, "chicago") //nolint:gocritic // Only owners can manage provisioner keys.
+		require.NoError(t, err)
+		keys, err = client.ListProvisionerKeys(ctx, user.OrganizationID) //nolint:gocritic // Test assertion.
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("InvalidTags", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		//nolint:gocritic // Only owners can manage provisioner keys.
+		_, err := client.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "user-scoped",
+			Tags: map[string]string{provisionersdk.TagScope: provisionersdk.ScopeUser},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.CreateProvisionerKey(ctx, user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "mine",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestProvisionerDaemonServeProvisionerKey(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.CreateProvisionerKeyResponse) {
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		//nolint:gocritic // Only owners can manage provisioner keys.
+		key, err := client.CreateProvisionerKey(testutil.Context(t, testutil.WaitLong), user.OrganizationID, codersdk.CreateProvisionerKeyRequest{
+			Name: "chicago",
+			Tags: map[string]string{"data_center": "chicago"},
+		})
+		require.NoError(t, err)
+		return client, user, key
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client, user, key := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		srv, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:           map[string]string{},
+			ProvisionerKey: key.Key,
+		})
+		require.NoError(t, err)
+		err = srv.DRPCConn().Close()
+		require.NoError(t, err)
+
+		daemons, err := client.ProvisionerDaemons(ctx) //nolint:gocritic // Test assertion.
+		require.NoError(t, err)
+		if assert.Len(t, daemons, 1) {
+			assert.Equal(t, &user.OrganizationID, daemons[0].OrganizationID)
+			assert.Equal(t, "chicago", daemons[0].Tags["data_center"])
+			assert.Equal(t, provisionersdk.ScopeOrganization, daemons[0].Tags[provisionersdk.TagScope])
+		}
+	})
+
+	t.Run("BadKey", func(t *testing.T) {
+		t.Parallel()
+		client, _, _ := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		_, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:           map[string]string{},
+			ProvisionerKey: "the wrong key",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("ConflictingTags", func(t *testing.T) {
+		t.Parallel()
+		client, _, key := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		_, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:           map[string]string{"data_center": "denver"},
+			ProvisionerKey: key.Key,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("OtherOrganization", func(t *testing.T) {
+		t.Parallel()
+		client, _, key := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		_, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:               map[string]string{},
+			Organization:       uuid.New(),
+			OrganizationScoped: true,
+			ProvisionerKey:     key.Key,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		t.Parallel()
+		client, user, key := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		another := codersdk.New(client.URL)
+		srv, err := another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:           map[string]string{},
+			ProvisionerKey: key.Key,
+		})
+		require.NoError(t, err)
+		defer srv.DRPCConn().Close()
+
+		err = client.DeleteProvisionerKey(ctx, user.OrganizationID, key.Name) //nolint:gocritic // Only owners can manage provisioner keys.
+		require.NoError(t, err)
+
+		select {
+		case <-srv.DRPCConn().Closed():
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the daemon to be disconnected")
+		}
+
+		_, err = another.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			ID:   uuid.New(),
+			Name: testutil.MustRandString(t, 32),
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags:           map[string]string{},
+			ProvisionerKey: key.Key,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
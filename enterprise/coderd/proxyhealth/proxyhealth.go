@@ -14,10 +14,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
@@ -42,6 +44,10 @@ const (
 	Unregistered Status = "unregistered"
 )
 
+// drainReportTTL is how long the drain report of a proxy replica is kept if the
+// replica doesn't deregister, e.g. because it was killed while draining.
+const drainReportTTL = time.Minute
+
 type Options struct {
 	// Interval is the interval at which the proxy health is checked.
 	Interval   time.Duration
@@ -65,9 +71,17 @@ type ProxyHealth struct {
 	cache      *atomic.Pointer[map[uuid.UUID]ProxyStatus]
 	proxyHosts *atomic.Pointer[[]string]
 
+	// Drain reports of proxy replicas that are shutting down, keyed by
+	// replica ID.
+	drainMu  sync.Mutex
+	draining map[uuid.UUID]drainReport
+
 	// PromMetrics
 	healthCheckDuration prometheus.Histogram
 	healthCheckResults  *prometheusmetrics.CachedGaugeVec
+	latency             *prometheusmetrics.CachedGaugeVec
+	derpReachable       *prometheusmetrics.CachedGaugeVec
+	versionSkew         *prometheusmetrics.CachedGaugeVec
 }
 
 func New(opts *Options) (*ProxyHealth, error) {
@@ -110,6 +124,33 @@ func New(opts *Options) (*ProxyHealth, error) {
 		}, []string{"proxy_id"}))
 	opts.Prometheus.MustRegister(healthCheckResults)
 
+	latency := prometheusmetrics.NewCachedGaugeVec(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "proxyhealth",
+			Name:      "latency_seconds",
+			Help:      "The round trip time of the last health check of the proxy from this replica.",
+		}, []string{"proxy_id"}))
+	opts.Prometheus.MustRegister(latency)
+
+	derpReachable := prometheusmetrics.NewCachedGaugeVec(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "proxyhealth",
+			Name:      "derp_reachable",
+			Help:      "Whether the DERP server of the proxy is reachable (1) or not (0). Proxies with DERP disabled report 0.",
+		}, []string{"proxy_id"}))
+	opts.Prometheus.MustRegister(derpReachable)
+
+	versionSkew := prometheusmetrics.NewCachedGaugeVec(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "proxyhealth",
+			Name:      "version_skew",
+			Help:      "Whether the major or minor version of the proxy differs from coderd (1) or not (0).",
+		}, []string{"proxy_id"}))
+	opts.Prometheus.MustRegister(versionSkew)
+
 	return &ProxyHealth{
 		db:                  opts.DB,
 		interval:            opts.Interval,
@@ -117,8 +158,12 @@ func New(opts *Options) (*ProxyHealth, error) {
 		client:              client,
 		cache:               &atomic.Pointer[map[uuid.UUID]ProxyStatus]{},
 		proxyHosts:          &atomic.Pointer[[]string]{},
+		draining:            map[uuid.UUID]drainReport{},
 		healthCheckDuration: healthCheckDuration,
 		healthCheckResults:  healthCheckResults,
+		latency:             latency,
+		derpReachable:       derpReachable,
+		versionSkew:         versionSkew,
 	}, nil
 }
 
@@ -128,6 +173,11 @@ func (p *ProxyHealth) Run(ctx context.Context) {
 	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
+	err := p.loadPersistedHealth(ctx)
+	if err != nil {
+		p.logger.Warn(ctx, "load persisted proxy health", slog.Error(err))
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -139,6 +189,7 @@ func (p *ProxyHealth) Run(ctx context.Context) {
 				continue
 			}
 			p.storeProxyHealth(statuses)
+			p.persistProxyHealth(ctx, statuses)
 		}
 	}
 }
@@ -166,6 +217,86 @@ func (p *ProxyHealth) ForceUpdate(ctx context.Context) error {
 	}
 
 	p.storeProxyHealth(statuses)
+	p.persistProxyHealth(ctx, statuses)
+	return nil
+}
+
+// persistProxyHealth stores the statuses in the database, so the last known
+// health of the proxies is available to replicas that haven't checked them
+// yet, e.g. right after they start.
+func (p *ProxyHealth) persistProxyHealth(ctx context.Context, statuses map[uuid.UUID]ProxyStatus) {
+	for id, status := range statuses {
+		errs := status.Report.Errors
+		if errs == nil {
+			errs = []string{}
+		}
+		warnings := status.Report.Warnings
+		if warnings == nil {
+			warnings = []string{}
+		}
+		//nolint:gocritic // Proxy health is a system service.
+		err := p.db.UpsertWorkspaceProxyHealth(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspaceProxyHealthParams{
+			ProxyID:       id,
+			Status:        string(status.Status),
+			LatencyMS:     int32(status.Latency.Milliseconds()),
+			DerpReachable: status.DERPReachable,
+			VersionSkew:   status.VersionSkew,
+			Errors:        errs,
+			Warnings:      warnings,
+			CheckedAt:     status.CheckedAt,
+		})
+		if err != nil {
+			p.logger.Warn(ctx, "persist proxy health", slog.F("proxy_id", id), slog.Error(err))
+		}
+	}
+}
+
+// loadPersistedHealth fills the cache with the last persisted health of the
+// proxies, unless a health check already completed.
+func (p *ProxyHealth) loadPersistedHealth(ctx context.Context) error {
+	//nolint:gocritic // Proxy health is a system service.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	proxies, err := p.db.GetWorkspaceProxies(ctx)
+	if err != nil {
+		return xerrors.Errorf("get workspace proxies: %w", err)
+	}
+	rows, err := p.db.GetWorkspaceProxyHealth(ctx)
+	if err != nil {
+		return xerrors.Errorf("get workspace proxy health: %w", err)
+	}
+	health := make(map[uuid.UUID]database.WorkspaceProxyHealth, len(rows))
+	for _, row := range rows {
+		health[row.ProxyID] = row
+	}
+
+	statuses := map[uuid.UUID]ProxyStatus{}
+	var proxyHosts []string
+	for _, proxy := range proxies {
+		row, ok := health[proxy.ID]
+		if !ok || proxy.Deleted {
+			continue
+		}
+		status := ProxyStatus{
+			Proxy:         proxy,
+			Status:        Status(row.Status),
+			Latency:       time.Duration(row.LatencyMS) * time.Millisecond,
+			DERPReachable: row.DerpReachable,
+			VersionSkew:   row.VersionSkew,
+			Report: codersdk.ProxyHealthReport{
+				Errors:   row.Errors,
+				Warnings: row.Warnings,
+			},
+			CheckedAt: row.CheckedAt,
+		}
+		if u, err := url.Parse(proxy.Url); err == nil && proxy.Url != "" {
+			status.ProxyHost = u.Host
+			proxyHosts = append(proxyHosts, u.Host)
+		}
+		statuses[proxy.ID] = status
+	}
+	if p.cache.CompareAndSwap(nil, &statuses) {
+		p.proxyHosts.CompareAndSwap(nil, &proxyHosts)
+	}
 	return nil
 }
 
@@ -197,6 +328,15 @@ type ProxyStatus struct {
 	Status    Status
 	Report    codersdk.ProxyHealthReport
 	CheckedAt time.Time
+	// Latency is the round trip time of the health check request. It's zero
+	// if the proxy is unreachable.
+	Latency time.Duration
+	// DERPReachable is true if DERP is enabled on the proxy and its latency
+	// check endpoint responded.
+	DERPReachable bool
+	// VersionSkew is true if the major or minor version of the proxy differs
+	// from coderd.
+	VersionSkew bool
 }
 
 // ProxyHosts returns the host:port of all healthy proxies.
@@ -266,8 +406,10 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 			}
 			req = req.WithContext(gctx)
 
+			start := time.Now()
 			resp, err := p.client.Do(req)
 			if err == nil {
+				status.Latency = time.Since(start)
 				defer resp.Body.Close()
 			}
 			// A switch statement felt easier to categorize the different cases than
@@ -352,6 +494,21 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 			}
 			status.ProxyHost = u.Host
 
+			if proxy.DerpEnabled && status.Status != Unreachable {
+				derpErr := p.checkDERP(gctx, proxy.Url)
+				if derpErr != nil {
+					status.Report.Warnings = append(status.Report.Warnings, fmt.Sprintf("derp is enabled but unreachable: %s", derpErr.Error()))
+				} else {
+					status.DERPReachable = true
+				}
+			}
+			status.VersionSkew = proxy.Version != "" && !buildinfo.VersionsMatch(proxy.Version, buildinfo.Version())
+			status.Report.Warnings = append(status.Report.Warnings, p.drainWarnings(proxy.ID, now)...)
+
+			p.latency.WithLabelValues(prometheusmetrics.VectorOperationSet, status.Latency.Seconds(), proxy.ID.String())
+			p.derpReachable.WithLabelValues(prometheusmetrics.VectorOperationSet, boolGauge(status.DERPReachable), proxy.ID.String())
+			p.versionSkew.WithLabelValues(prometheusmetrics.VectorOperationSet, boolGauge(status.VersionSkew), proxy.ID.String())
+
 			// Set the prometheus metric correctly.
 			switch status.Status {
 			case Healthy:
@@ -377,6 +534,82 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 		return nil, xerrors.Errorf("group run: %w", err)
 	}
 	p.healthCheckResults.Commit()
+	p.latency.Commit()
+	p.derpReachable.Commit()
+	p.versionSkew.Commit()
 
 	return proxyStatus, nil
 }
+
+type drainReport struct {
+	proxyID    uuid.UUID
+	hostname   string
+	sessions   int64
+	receivedAt time.Time
+}
+
+// ReportDraining records that a replica of a proxy is shutting down and waiting
+// for its open app sessions to close. Draining replicas are reported as
+// warnings in the health of the proxy until they deregister.
+func (p *ProxyHealth) ReportDraining(proxyID, replicaID uuid.UUID, hostname string, sessions int64) {
+	p.drainMu.Lock()
+	defer p.drainMu.Unlock()
+	p.draining[replicaID] = drainReport{
+		proxyID:    proxyID,
+		hostname:   hostname,
+		sessions:   sessions,
+		receivedAt: time.Now(),
+	}
+}
+
+// ReplicaStopped forgets the drain report of a replica that deregistered.
+func (p *ProxyHealth) ReplicaStopped(replicaID uuid.UUID) {
+	p.drainMu.Lock()
+	defer p.drainMu.Unlock()
+	delete(p.draining, replicaID)
+}
+
+func (p *ProxyHealth) drainWarnings(proxyID uuid.UUID, now time.Time) []string {
+	p.drainMu.Lock()
+	defer p.drainMu.Unlock()
+
+	var warnings []string
+	for replicaID, report := range p.draining {
+		if now.Sub(report.receivedAt) > drainReportTTL {
+			delete(p.draining, replicaID)
+			continue
+		}
+		if report.proxyID != proxyID {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("replica %q is shutting down, waiting for %d open app sessions to close", report.hostname, report.sessions))
+	}
+	slices.Sort(warnings)
+	return warnings
+}
+
+// checkDERP checks that the DERP server of the proxy responds to latency
+// checks, which clients use when UDP is blocked.
+func (p *ProxyHealth) checkDERP(ctx context.Context, proxyURL string) error {
+	reqURL := fmt.Sprintf("%s/derp/latency-check", strings.TrimSuffix(proxyURL, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return xerrors.Errorf("new request: %w", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("unexpected status code %d from %q", resp.StatusCode, reqURL)
+	}
+	return nil
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
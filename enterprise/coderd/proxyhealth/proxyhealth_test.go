@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
@@ -181,3 +182,110 @@ func TestProxyHealth_Unreachable(t *testing.T) {
 		require.Equal(t, ph.HealthStatus()[p.ID].Status, proxyhealth.Unreachable, "expect unreachable proxy")
 	}
 }
+
+func TestProxyHealth_Details(t *testing.T) {
+	t.Parallel()
+	db := dbmem.New()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/derp/latency-check" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}))
+	defer srv.Close()
+
+	srvNoDERP := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/derp/latency-check" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}))
+	defer srvNoDERP.Close()
+
+	registerDERPProxy := func(url string) database.WorkspaceProxy {
+		proxy, _ := dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+		proxy, err := db.RegisterWorkspaceProxy(ctx, database.RegisterWorkspaceProxyParams{
+			ID:          proxy.ID,
+			Url:         url,
+			DerpEnabled: true,
+			Version:     `v2.34.5-test+beefcake`,
+		})
+		require.NoError(t, err, "failed to update proxy")
+		return proxy
+	}
+	derp := registerDERPProxy(srv.URL)
+	noDERP := registerDERPProxy(srvNoDERP.URL)
+
+	ph, err := proxyhealth.New(&proxyhealth.Options{
+		Interval: 0,
+		DB:       db,
+		Logger:   slogtest.Make(t, nil),
+		Client:   srv.Client(),
+	})
+	require.NoError(t, err, "failed to create proxy health")
+
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+
+	statuses := ph.HealthStatus()
+	require.Equal(t, proxyhealth.Healthy, statuses[derp.ID].Status)
+	require.True(t, statuses[derp.ID].DERPReachable)
+	require.Positive(t, statuses[derp.ID].Latency)
+	require.Equal(t, proxyhealth.Healthy, statuses[noDERP.ID].Status)
+	require.False(t, statuses[noDERP.ID].DERPReachable)
+	require.Len(t, statuses[noDERP.ID].Report.Warnings, 1)
+
+	// The results are persisted for other replicas.
+	persisted, err := db.GetWorkspaceProxyHealth(ctx)
+	require.NoError(t, err)
+	require.Len(t, persisted, 2)
+	for _, h := range persisted {
+		require.Equal(t, string(proxyhealth.Healthy), h.Status)
+		require.Equal(t, h.ProxyID == derp.ID, h.DerpReachable)
+	}
+}
+
+func TestProxyHealth_Draining(t *testing.T) {
+	t.Parallel()
+	db := dbmem.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}))
+	defer srv.Close()
+
+	draining := insertProxy(t, db, srv.URL)
+	other := insertProxy(t, db, srv.URL)
+
+	ph, err := proxyhealth.New(&proxyhealth.Options{
+		Interval: 0,
+		DB:       db,
+		Logger:   slogtest.Make(t, nil),
+		Client:   srv.Client(),
+	})
+	require.NoError(t, err, "failed to create proxy health")
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	replicaID := uuid.New()
+	ph.ReportDraining(draining.ID, replicaID, "proxy-0", 3)
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+
+	statuses := ph.HealthStatus()
+	require.Equal(t, proxyhealth.Healthy, statuses[draining.ID].Status, "draining doesn't affect the status")
+	require.Equal(t, []string{`replica "proxy-0" is shutting down, waiting for 3 open app sessions to close`}, statuses[draining.ID].Report.Warnings)
+	require.Empty(t, statuses[other.ID].Report.Warnings)
+
+	ph.ReplicaStopped(replicaID)
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+	require.Empty(t, ph.HealthStatus()[draining.ID].Report.Warnings)
+}
@@ -1,10 +1,13 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -15,10 +18,12 @@ import (
 	"golang.org/x/xerrors"
 
 	agpl "github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -37,12 +42,57 @@ func (api *API) scimEnabledMW(next http.Handler) http.Handler {
 	})
 }
 
+// scimAuthMW rejects requests that do not carry the SCIM API key. The
+// identity provider authenticates with this key instead of a session token.
+func (api *API) scimAuthMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !api.scimVerifyAuthHeader(r) {
+			_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
 func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	hdr := []byte(r.Header.Get("Authorization"))
 
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
+// scimAudit records a change made by the identity provider in the audit log.
+// SCIM requests aren't made on behalf of a Coder user, so the entry has no
+// user attached to it.
+func scimAudit[T audit.Auditable](api *API, r *http.Request, status int, action database.AuditAction, before, after T) {
+	auditor := api.AGPL.Auditor.Load()
+	audit.BackgroundAudit(r.Context(), &audit.BackgroundAuditParams[T]{
+		Audit:     *auditor,
+		Log:       api.Logger,
+		RequestID: httpmw.RequestID(r),
+		Status:    status,
+		Action:    action,
+		IP:        r.RemoteAddr,
+		Old:       before,
+		New:       after,
+	})
+}
+
+// scimOrganizationID returns the organization users and groups are
+// provisioned into. Once multi-organization support is added, we should
+// enable a configuration map of user email to organization.
+func (api *API) scimOrganizationID(ctx context.Context) (uuid.UUID, error) {
+	//nolint:gocritic // needed for SCIM
+	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(organizations) == 0 {
+		return uuid.Nil, nil
+	}
+	return organizations[0].ID, nil
+}
+
 // scimGetUsers intentionally always returns no users. This is done to always force
 // Okta to try and create each user individually, this way we don't need to
 // implement fetching users twice.
@@ -56,12 +106,7 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 // @Router /scim/v2/Users [get]
 //
 //nolint:revive
-func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
-		return
-	}
-
+func (api *API) scimGetUsers(rw http.ResponseWriter, _ *http.Request) {
 	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
 		TotalResults: 0,
 		StartIndex:   1,
@@ -84,12 +129,7 @@ func (api *API) scimGetUsers(rw http.ResponseWriter, r *http.Request) {
 // @Router /scim/v2/Users/{id} [get]
 //
 //nolint:revive
-func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
-		return
-	}
-
+func (api *API) scimGetUser(rw http.ResponseWriter, _ *http.Request) {
 	_ = handlerutil.WriteError(rw, spec.ErrNotFound)
 }
 
@@ -98,10 +138,11 @@ func (api *API) scimGetUser(rw http.ResponseWriter, r *http.Request) {
 // need these fields, so it was much simpler to use our own struct. This was
 // tested only with Okta.
 type SCIMUser struct {
-	Schemas  []string `json:"schemas"`
-	ID       string   `json:"id"`
-	UserName string   `json:"userName"`
-	Name     struct {
+	Schemas    []string `json:"schemas"`
+	ID         string   `json:"id"`
+	ExternalID string   `json:"externalId"`
+	UserName   string   `json:"userName"`
+	Name       struct {
 		GivenName  string `json:"givenName"`
 		FamilyName string `json:"familyName"`
 	} `json:"name"`
@@ -119,6 +160,8 @@ type SCIMUser struct {
 }
 
 // scimPostUser creates a new user, or returns the existing user if it exists.
+// Existing users are matched by the external ID the identity provider assigned
+// them, falling back to their email or username.
 //
 // @Summary SCIM 2.0: Create new user
 // @ID scim-create-new-user
@@ -130,10 +173,6 @@ type SCIMUser struct {
 // @Router /scim/v2/Users [post]
 func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
-		return
-	}
 
 	var sUser SCIMUser
 	err := json.NewDecoder(r.Body).Decode(&sUser)
@@ -155,11 +194,7 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	//nolint:gocritic
-	dbUser, err := api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
-		Email:    email,
-		Username: sUser.UserName,
-	})
+	dbUser, err := api.scimExistingUser(ctx, sUser.ExternalID, email, sUser.UserName)
 	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 		_ = handlerutil.WriteError(rw, err)
 		return
@@ -170,7 +205,7 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 
 		if sUser.Active && dbUser.Status == database.UserStatusSuspended {
 			//nolint:gocritic
-			_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
+			newUser, err := api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
 				ID: dbUser.ID,
 				// The user will get transitioned to Active after logging in.
 				Status:    database.UserStatusDormant,
@@ -180,6 +215,13 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 				_ = handlerutil.WriteError(rw, err)
 				return
 			}
+			scimAudit(api, r, http.StatusOK, database.AuditActionWrite, dbUser, newUser)
+		}
+
+		err = api.scimLinkExternalID(ctx, dbUser.ID, sUser.ExternalID)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
 		}
 
 		httpapi.Write(ctx, rw, http.StatusOK, sUser)
@@ -200,21 +242,12 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		sUser.UserName = httpapi.UsernameFrom(sUser.UserName)
 	}
 
-	var organizationID uuid.UUID
-	//nolint:gocritic
-	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	organizationID, err := api.scimOrganizationID(ctx)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	if len(organizations) > 0 {
-		// Add the user to the first organization. Once multi-organization
-		// support is added, we should enable a configuration map of user
-		// email to organization.
-		organizationID = organizations[0].ID
-	}
-
 	//nolint:gocritic // needed for SCIM
 	dbUser, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
@@ -228,6 +261,14 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	scimAudit(api, r, http.StatusOK, database.AuditActionCreate, database.User{}, dbUser)
+	api.AGPL.PublishUserCreated(ctx, dbUser)
+
+	err = api.scimLinkExternalID(ctx, dbUser.ID, sUser.ExternalID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
 
 	sUser.ID = dbUser.ID.String()
 	sUser.UserName = dbUser.Username
@@ -235,6 +276,46 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
 
+// scimExistingUser looks up a user previously provisioned by the identity
+// provider. sql.ErrNoRows is returned if there is no such user.
+func (api *API) scimExistingUser(ctx context.Context, externalID, email, username string) (database.User, error) {
+	if externalID != "" {
+		//nolint:gocritic // needed for SCIM
+		link, err := api.Database.GetUserSCIMExternalIDByExternalID(dbauthz.AsSystemRestricted(ctx), externalID)
+		if err == nil {
+			//nolint:gocritic // needed for SCIM
+			return api.Database.GetUserByID(dbauthz.AsSystemRestricted(ctx), link.UserID)
+		}
+		if !xerrors.Is(err, sql.ErrNoRows) {
+			return database.User{}, err
+		}
+	}
+
+	//nolint:gocritic // needed for SCIM
+	return api.Database.GetUserByEmailOrUsername(dbauthz.AsSystemRestricted(ctx), database.GetUserByEmailOrUsernameParams{
+		Email:    email,
+		Username: username,
+	})
+}
+
+// scimLinkExternalID remembers the external ID the identity provider assigned
+// to a user. It's a no-op if the identity provider didn't send one.
+func (api *API) scimLinkExternalID(ctx context.Context, userID uuid.UUID, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+
+	//nolint:gocritic // needed for SCIM
+	_, err := api.Database.UpsertUserSCIMExternalID(dbauthz.AsSystemRestricted(ctx), database.UpsertUserSCIMExternalIDParams{
+		UserID:     userID,
+		ExternalID: externalID,
+	})
+	if database.IsUniqueViolation(err, database.UniqueUserScimExternalIDsExternalIDKey) {
+		return scimError(http.StatusConflict, "uniqueness")
+	}
+	return err
+}
+
 // scimPatchUser supports suspending and activating users only.
 //
 // @Summary SCIM 2.0: Update user account
@@ -248,10 +329,6 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 // @Router /scim/v2/Users/{id} [patch]
 func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	if !api.scimVerifyAuthHeader(r) {
-		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
-		return
-	}
 
 	id := chi.URLParam(r, "id")
 
@@ -285,7 +362,7 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
+	newUser, err := api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
 		ID:        dbUser.ID,
 		Status:    status,
 		UpdatedAt: dbtime.Now(),
@@ -294,6 +371,499 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
+	scimAudit(api, r, http.StatusOK, database.AuditActionWrite, dbUser, newUser)
+
+	err = api.scimLinkExternalID(ctx, dbUser.ID, sUser.ExternalID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
+
+// SCIMGroup is the subset of the SCIM group resource that we support. Members
+// are referenced by the ID returned when the user was provisioned.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display"`
+}
+
+// SCIMPatchOp is a SCIM PATCH request. Only the operations needed to keep
+// group names and memberships in sync are supported.
+type SCIMPatchOp struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+var scimMemberFilterRegex = regexp.MustCompile(`^members\[value eq "([^"]+)"\]$`)
+
+func convertSCIMGroup(group database.Group, members []database.User) SCIMGroup {
+	sGroup := SCIMGroup{
+		Schemas:     []string{"urn:ietf:params:scim:schemas:core:2.0:Group"},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	sGroup.Meta.ResourceType = "Group"
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	return sGroup
+}
+
+// scimGetGroups intentionally always returns no groups. Like users, this
+// forces the identity provider to push each group, and creating a group that
+// already exists returns the existing one.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Success 200
+// @Router /scim/v2/Groups [get]
+//
+//nolint:revive
+func (api *API) scimGetGroups(rw http.ResponseWriter, _ *http.Request) {
+	_ = handlerutil.WriteSearchResultToResponse(rw, &service.QueryResponse{
+		TotalResults: 0,
+		StartIndex:   1,
+		ItemsPerPage: 0,
+		Resources:    []scimjson.Serializable{},
+	})
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertSCIMGroup(group, members))
+}
+
+// scimPostGroup creates a group in the default organization, or returns the
+// group with the same name if it exists. The given members are added to the
+// group.
+//
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	if sGroup.DisplayName == "" || sGroup.DisplayName == database.EveryoneGroup {
+		_ = handlerutil.WriteError(rw, scimError(http.StatusBadRequest, "invalidValue"))
+		return
+	}
+
+	add, err := scimMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	created, err := api.Database.InsertMissingGroups(dbauthz.AsSystemRestricted(ctx), database.InsertMissingGroupsParams{
+		OrganizationID: organizationID,
+		GroupNames:     []string{sGroup.DisplayName},
+		Source:         database.GroupSourceScim,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	group, err := api.Database.GetGroupByOrgAndName(dbauthz.AsSystemRestricted(ctx), database.GetGroupByOrgAndNameParams{
+		OrganizationID: organizationID,
+		Name:           sGroup.DisplayName,
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	oldMembers, newMembers, err := api.scimUpdateGroupMembers(ctx, group.ID, func(members map[uuid.UUID]struct{}) {
+		for _, id := range add {
+			members[id] = struct{}{}
+		}
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	if len(created) > 0 {
+		scimAudit(api, r, http.StatusOK, database.AuditActionCreate, database.AuditableGroup{}, group.Auditable(newMembers))
+	} else if len(newMembers) != len(oldMembers) {
+		scimAudit(api, r, http.StatusOK, database.AuditActionWrite, group.Auditable(oldMembers), group.Auditable(newMembers))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertSCIMGroup(group, newMembers))
+}
+
+// scimPatchGroup renames a group or changes its members.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Produce application/scim+json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchOp true "Patch operations"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	var patch SCIMPatchOp
+	err := json.NewDecoder(r.Body).Decode(&patch)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	// Validate the whole request before changing anything.
+	name := group.Name
+	err = applySCIMGroupPatch(&name, map[uuid.UUID]struct{}{}, patch.Operations)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	oldGroup := group
+	if name != group.Name {
+		if name == "" || name == database.EveryoneGroup {
+			_ = handlerutil.WriteError(rw, scimError(http.StatusBadRequest, "invalidValue"))
+			return
+		}
+		//nolint:gocritic // needed for SCIM
+		group, err = api.Database.UpdateGroupByID(dbauthz.AsSystemRestricted(ctx), database.UpdateGroupByIDParams{
+			ID:             group.ID,
+			Name:           name,
+			DisplayName:    group.DisplayName,
+			AvatarURL:      group.AvatarURL,
+			QuotaAllowance: group.QuotaAllowance,
+		})
+		if database.IsUniqueViolation(err) {
+			_ = handlerutil.WriteError(rw, scimError(http.StatusConflict, "uniqueness"))
+			return
+		}
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	oldMembers, newMembers, err := api.scimUpdateGroupMembers(ctx, group.ID, func(members map[uuid.UUID]struct{}) {
+		// The operations were validated above, and the name was already
+		// applied.
+		_ = applySCIMGroupPatch(new(string), members, patch.Operations)
+	})
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	scimAudit(api, r, http.StatusOK, database.AuditActionWrite, oldGroup.Auditable(oldMembers), group.Auditable(newMembers))
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertSCIMGroup(group, newMembers))
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	group, ok := api.scimGroupParam(rw, r)
+	if !ok {
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembers(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.DeleteGroupByID(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	scimAudit(api, r, http.StatusNoContent, database.AuditActionDelete, group.Auditable(members), database.AuditableGroup{})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroupParam fetches the group in the URL. The "Everyone" group is
+// managed by Coder, so it's never exposed over SCIM.
+func (api *API) scimGroupParam(rw http.ResponseWriter, r *http.Request) (database.Group, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		_ = handlerutil.WriteError(rw, scimError(http.StatusBadRequest, "invalidId"))
+		return database.Group{}, false
+	}
+
+	//nolint:gocritic // needed for SCIM
+	group, err := api.Database.GetGroupByID(dbauthz.AsSystemRestricted(r.Context()), id)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && group.IsEveryone()) {
+		_ = handlerutil.WriteError(rw, scimError(spec.ErrNotFound.Status, spec.ErrNotFound.Type))
+		return database.Group{}, false
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return database.Group{}, false
+	}
+	return group, true
+}
+
+// scimUpdateGroupMembers passes the current members of a group to mutate and
+// then adds or removes members so the group matches the result. The members
+// before and after the change are returned.
+func (api *API) scimUpdateGroupMembers(ctx context.Context, groupID uuid.UUID, mutate func(members map[uuid.UUID]struct{})) (oldMembers []database.User, newMembers []database.User, err error) {
+	//nolint:gocritic // needed for SCIM
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		oldMembers, err = tx.GetGroupMembers(ctx, groupID)
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+
+		current := make(map[uuid.UUID]struct{}, len(oldMembers))
+		want := make(map[uuid.UUID]struct{}, len(oldMembers))
+		for _, member := range oldMembers {
+			current[member.ID] = struct{}{}
+			want[member.ID] = struct{}{}
+		}
+		mutate(want)
+
+		for id := range current {
+			if _, ok := want[id]; ok {
+				continue
+			}
+			err := tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+				UserID:  id,
+				GroupID: groupID,
+			})
+			if err != nil {
+				return xerrors.Errorf("remove member %s: %w", id, err)
+			}
+		}
+		for id := range want {
+			if _, ok := current[id]; ok {
+				continue
+			}
+			err := tx.InsertGroupMember(ctx, database.InsertGroupMemberParams{
+				UserID:  id,
+				GroupID: groupID,
+			})
+			if database.IsForeignKeyViolation(err) {
+				return scimError(http.StatusBadRequest, "invalidValue")
+			}
+			if err != nil {
+				return xerrors.Errorf("add member %s: %w", id, err)
+			}
+		}
+
+		newMembers, err = tx.GetGroupMembers(ctx, groupID)
+		if err != nil {
+			return xerrors.Errorf("get group members: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		var sErr *spec.Error
+		if xerrors.As(err, &sErr) {
+			return nil, nil, scimError(sErr.Status, sErr.Type)
+		}
+		return nil, nil, err
+	}
+	return oldMembers, newMembers, nil
+}
+
+// applySCIMGroupPatch applies the operations of a SCIM PATCH request to the
+// name and members of a group.
+func applySCIMGroupPatch(name *string, members map[uuid.UUID]struct{}, ops []SCIMPatchOperation) error {
+	invalidValue := scimError(http.StatusBadRequest, "invalidValue")
+	for _, op := range ops {
+		verb := strings.ToLower(op.Op)
+		path := strings.TrimSpace(op.Path)
+		switch {
+		case strings.EqualFold(path, "members"):
+			var value []SCIMGroupMember
+			if len(op.Value) > 0 {
+				err := json.Unmarshal(op.Value, &value)
+				if err != nil {
+					return invalidValue
+				}
+			}
+			ids, err := scimMemberIDs(value)
+			if err != nil {
+				return err
+			}
+			switch verb {
+			case "replace":
+				for id := range members {
+					delete(members, id)
+				}
+				fallthrough
+			case "add":
+				for _, id := range ids {
+					members[id] = struct{}{}
+				}
+			case "remove":
+				if len(op.Value) == 0 {
+					for id := range members {
+						delete(members, id)
+					}
+				}
+				for _, id := range ids {
+					delete(members, id)
+				}
+			default:
+				return scimError(http.StatusBadRequest, "invalidSyntax")
+			}
+
+		case scimMemberFilterRegex.MatchString(path):
+			if verb != "remove" {
+				return scimError(http.StatusBadRequest, "invalidPath")
+			}
+			id, err := uuid.Parse(scimMemberFilterRegex.FindStringSubmatch(path)[1])
+			if err != nil {
+				return invalidValue
+			}
+			delete(members, id)
+
+		case strings.EqualFold(path, "displayName"), path == "":
+			if verb != "add" && verb != "replace" {
+				return scimError(http.StatusBadRequest, "invalidSyntax")
+			}
+			if path != "" {
+				var value string
+				err := json.Unmarshal(op.Value, &value)
+				if err != nil {
+					return invalidValue
+				}
+				*name = value
+				continue
+			}
+			// Without a path, the value holds the attributes to replace.
+			var value struct {
+				DisplayName *string            `json:"displayName"`
+				Members     *[]SCIMGroupMember `json:"members"`
+			}
+			err := json.Unmarshal(op.Value, &value)
+			if err != nil {
+				return invalidValue
+			}
+			if value.DisplayName != nil {
+				*name = *value.DisplayName
+			}
+			if value.Members != nil {
+				ids, err := scimMemberIDs(*value.Members)
+				if err != nil {
+					return err
+				}
+				if verb == "replace" {
+					for id := range members {
+						delete(members, id)
+					}
+				}
+				for _, id := range ids {
+					members[id] = struct{}{}
+				}
+			}
+
+		default:
+			return scimError(http.StatusBadRequest, "invalidPath")
+		}
+	}
+	return nil
+}
+
+// scimError returns a SCIM error that responds with the given status.
+// handlerutil.WriteError only reads the status of a wrapped *spec.Error.
+func scimError(status int, typ string) error {
+	return xerrors.Errorf("%w", &spec.Error{Status: status, Type: typ})
+}
+
+func scimMemberIDs(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, scimError(http.StatusBadRequest, "invalidValue")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
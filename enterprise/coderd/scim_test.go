@@ -8,9 +8,13 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/coderd"
@@ -248,6 +252,88 @@ func TestScim(t *testing.T) {
 			// same string before we modified it above.
 			assert.Equal(t, sUser.Name.GivenName, userRes.Users[0].Username)
 		})
+
+		t.Run("ExternalID", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			scimAPIKey := []byte("hi")
+			client, _ := coderdenttest.New(t, &coderdenttest.Options{
+				SCIMAPIKey: scimAPIKey,
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM: 1,
+					},
+				},
+			})
+
+			sUser := makeScimUser(t)
+			sUser.ExternalID = uuid.NewString()
+			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			var created coderd.SCIMUser
+			err = json.NewDecoder(res.Body).Decode(&created)
+			require.NoError(t, err)
+
+			// The identity provider changed the user's email and username,
+			// but the external ID still matches the existing user.
+			renamed := makeScimUser(t)
+			renamed.ExternalID = sUser.ExternalID
+			res, err = client.Request(ctx, "POST", "/scim/v2/Users", renamed, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			var existing coderd.SCIMUser
+			err = json.NewDecoder(res.Body).Decode(&existing)
+			require.NoError(t, err)
+			assert.Equal(t, created.ID, existing.ID)
+
+			userRes, err := client.Users(ctx, codersdk.UsersRequest{Search: renamed.Emails[0].Value})
+			require.NoError(t, err)
+			require.Len(t, userRes.Users, 0)
+		})
+
+		t.Run("Audit", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			auditor := audit.NewMock()
+			scimAPIKey := []byte("hi")
+			client, _ := coderdenttest.New(t, &coderdenttest.Options{
+				AuditLogging: true,
+				SCIMAPIKey:   scimAPIKey,
+				Options: &coderdtest.Options{
+					Auditor: auditor,
+				},
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM:     1,
+						codersdk.FeatureAuditLog: 1,
+					},
+				},
+			})
+
+			sUser := makeScimUser(t)
+			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sUser)
+			require.NoError(t, err)
+
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				Action:     database.AuditActionCreate,
+				ResourceID: uuid.MustParse(sUser.ID),
+			}))
+		})
 	})
 
 	t.Run("patchUser", func(t *testing.T) {
@@ -339,4 +425,193 @@ func TestScim(t *testing.T) {
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
 	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		setup := func(t *testing.T) (*codersdk.Client, []byte, *audit.MockAuditor) {
+			auditor := audit.NewMock()
+			scimAPIKey := []byte("hi")
+			client, _ := coderdenttest.New(t, &coderdenttest.Options{
+				AuditLogging: true,
+				SCIMAPIKey:   scimAPIKey,
+				Options: &coderdtest.Options{
+					Auditor: auditor,
+				},
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM:         1,
+						codersdk.FeatureTemplateRBAC: 1,
+						codersdk.FeatureAuditLog:     1,
+					},
+				},
+			})
+			return client, scimAPIKey, auditor
+		}
+
+		postUser := func(ctx context.Context, t *testing.T, client *codersdk.Client, scimAPIKey []byte) coderd.SCIMUser {
+			sUser := makeScimUser(t)
+			res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sUser)
+			require.NoError(t, err)
+			return sUser
+		}
+
+		postGroup := func(ctx context.Context, t *testing.T, client *codersdk.Client, scimAPIKey []byte, sGroup coderd.SCIMGroup) coderd.SCIMGroup {
+			res, err := client.Request(ctx, "POST", "/scim/v2/Groups", sGroup, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sGroup)
+			require.NoError(t, err)
+			return sGroup
+		}
+
+		t.Run("noAuth", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, _, _ := setup(t)
+
+			res, err := client.Request(ctx, "POST", "/scim/v2/Groups", struct{}{})
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		})
+
+		t.Run("Create", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey, auditor := setup(t)
+			sUser := postUser(ctx, t, client, scimAPIKey)
+
+			sGroup := postGroup(ctx, t, client, scimAPIKey, coderd.SCIMGroup{
+				DisplayName: "engineering",
+				Members:     []coderd.SCIMGroupMember{{Value: sUser.ID}},
+			})
+			require.Len(t, sGroup.Members, 1)
+			assert.Equal(t, sUser.ID, sGroup.Members[0].Value)
+
+			group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+			require.NoError(t, err)
+			assert.Equal(t, "engineering", group.Name)
+			assert.Equal(t, codersdk.GroupSourceSCIM, group.Source)
+			require.Len(t, group.Members, 1)
+			assert.Equal(t, sUser.ID, group.Members[0].ID.String())
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				Action:     database.AuditActionCreate,
+				ResourceID: group.ID,
+			}))
+
+			// Pushing the same group again returns the existing group.
+			again := postGroup(ctx, t, client, scimAPIKey, coderd.SCIMGroup{
+				DisplayName: "engineering",
+			})
+			assert.Equal(t, sGroup.ID, again.ID)
+			assert.Len(t, again.Members, 1)
+		})
+
+		t.Run("Patch", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey, auditor := setup(t)
+			first := postUser(ctx, t, client, scimAPIKey)
+			second := postUser(ctx, t, client, scimAPIKey)
+
+			sGroup := postGroup(ctx, t, client, scimAPIKey, coderd.SCIMGroup{
+				DisplayName: "engineering",
+				Members:     []coderd.SCIMGroupMember{{Value: first.ID}},
+			})
+
+			patch := coderd.SCIMPatchOp{
+				Schemas: []string{"urn:ietf:params:scim:api:messages:2.0:PatchOp"},
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "Add", Path: "members", Value: json.RawMessage(fmt.Sprintf(`[{"value":%q}]`, second.ID))},
+					{Op: "Remove", Path: fmt.Sprintf(`members[value eq %q]`, first.ID)},
+					{Op: "Replace", Path: "displayName", Value: json.RawMessage(`"platform"`)},
+				},
+			}
+			res, err := client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, patch, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+			require.NoError(t, err)
+			assert.Equal(t, "platform", group.Name)
+			require.Len(t, group.Members, 1)
+			assert.Equal(t, second.ID, group.Members[0].ID.String())
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				Action:     database.AuditActionWrite,
+				ResourceID: group.ID,
+			}))
+		})
+
+		t.Run("PatchInvalidPath", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey, _ := setup(t)
+			sGroup := postGroup(ctx, t, client, scimAPIKey, coderd.SCIMGroup{
+				DisplayName: "engineering",
+			})
+
+			patch := coderd.SCIMPatchOp{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "replace", Path: "externalId", Value: json.RawMessage(`"abc"`)},
+				},
+			}
+			res, err := client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, patch, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, scimAPIKey, auditor := setup(t)
+			sGroup := postGroup(ctx, t, client, scimAPIKey, coderd.SCIMGroup{
+				DisplayName: "engineering",
+			})
+
+			res, err := client.Request(ctx, "DELETE", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+			_, err = client.Group(ctx, uuid.MustParse(sGroup.ID))
+			require.Error(t, err)
+			require.True(t, auditor.Contains(t, database.AuditLog{
+				Action:     database.AuditActionDelete,
+				ResourceID: uuid.MustParse(sGroup.ID),
+			}))
+
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
 }
@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -10,6 +11,8 @@ import (
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -32,13 +35,43 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 			return xerrors.Errorf("expected 1 org, got %d", len(orgs))
 		}
 
-		// Delete all groups the user belongs to.
-		err = tx.DeleteGroupMembersByOrgAndUser(ctx, database.DeleteGroupMembersByOrgAndUserParams{
+		current, err := tx.GetGroupsByOrganizationAndUserID(ctx, database.GetGroupsByOrganizationAndUserIDParams{
 			UserID:         userID,
 			OrganizationID: orgs[0].ID,
 		})
 		if err != nil {
-			return xerrors.Errorf("delete user groups: %w", err)
+			return xerrors.Errorf("get user groups: %w", err)
+		}
+
+		wanted := make(map[string]struct{}, len(groupNames))
+		for _, name := range groupNames {
+			wanted[name] = struct{}{}
+		}
+
+		// Remove the user from any group the auth provider no longer returns.
+		for _, group := range current {
+			if _, ok := wanted[group.Name]; ok {
+				delete(wanted, group.Name)
+				continue
+			}
+			err = tx.DeleteGroupMemberFromGroup(ctx, database.DeleteGroupMemberFromGroupParams{
+				UserID:  userID,
+				GroupID: group.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete user from group %q: %w", group.Name, err)
+			}
+		}
+
+		if len(wanted) == 0 {
+			return nil
+		}
+		missing := make([]string, 0, len(wanted))
+		for _, name := range groupNames {
+			if _, ok := wanted[name]; ok {
+				delete(wanted, name)
+				missing = append(missing, name)
+			}
 		}
 
 		if createMissingGroups {
@@ -46,7 +79,7 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 			// nolint:gocritic
 			created, err := tx.InsertMissingGroups(dbauthz.AsSystemRestricted(ctx), database.InsertMissingGroupsParams{
 				OrganizationID: orgs[0].ID,
-				GroupNames:     groupNames,
+				GroupNames:     missing,
 				Source:         database.GroupSourceOidc,
 			})
 			if err != nil {
@@ -60,11 +93,12 @@ func (api *API) setUserGroups(ctx context.Context, logger slog.Logger, db databa
 			}
 		}
 
-		// Re-add the user to all groups returned by the auth provider.
+		// Add the user to the groups returned by the auth provider that
+		// they are not already a member of.
 		err = tx.InsertUserGroupsByName(ctx, database.InsertUserGroupsByNameParams{
 			UserID:         userID,
 			OrganizationID: orgs[0].ID,
-			GroupNames:     groupNames,
+			GroupNames:     missing,
 		})
 		if err != nil {
 			return xerrors.Errorf("insert user groups: %w", err)
@@ -99,3 +133,82 @@ func (api *API) setUserSiteRoles(ctx context.Context, logger slog.Logger, db dat
 		return nil
 	}, nil)
 }
+
+// setUserOrganizationRoles replaces the user's roles in their organization
+// with the given roles. Roles may be given by their short name (e.g.
+// "organization-admin"), and are scoped to the user's organization. The
+// organization member role is always kept.
+func (api *API) setUserOrganizationRoles(ctx context.Context, logger slog.Logger, db database.Store, userID uuid.UUID, roles []string) error {
+	api.entitlementsMu.RLock()
+	enabled := api.entitlements.Features[codersdk.FeatureUserRoleManagement].Enabled
+	api.entitlementsMu.RUnlock()
+
+	if !enabled {
+		logger.Warn(ctx, "attempted to assign OIDC organization roles without enterprise entitlement, roles left unchanged",
+			slog.F("user_id", userID), slog.F("roles", roles),
+		)
+		return nil
+	}
+
+	return db.InTx(func(tx database.Store) error {
+		orgs, err := tx.GetOrganizationsByUserID(ctx, userID)
+		if err != nil {
+			return xerrors.Errorf("get user orgs: %w", err)
+		}
+		if len(orgs) != 1 {
+			return xerrors.Errorf("expected 1 org, got %d", len(orgs))
+		}
+		orgID := orgs[0].ID
+
+		available := make(map[string]string)
+		for _, role := range rbac.OrganizationRoles(orgID) {
+			available[role.Name] = role.Name
+			name, _, _ := strings.Cut(role.Name, ":")
+			available[name] = role.Name
+		}
+
+		granted := []string{rbac.RoleOrgMember(orgID)}
+		ignored := make([]string, 0)
+		for _, role := range roles {
+			scoped, ok := available[role]
+			if !ok {
+				ignored = append(ignored, role)
+				continue
+			}
+			if !slice.Contains(granted, scoped) {
+				granted = append(granted, scoped)
+			}
+		}
+		if len(ignored) > 0 {
+			logger.Debug(ctx, "OIDC organization roles ignored in assignment",
+				slog.F("ignored", ignored),
+				slog.F("assigned", granted),
+				slog.F("user_id", userID),
+			)
+		}
+
+		member, err := tx.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: orgID,
+			UserID:         userID,
+		})
+		if err != nil {
+			return xerrors.Errorf("get organization member: %w", err)
+		}
+
+		added, removed := rbac.ChangeRoleSet(member.Roles, granted)
+		if len(added) == 0 && len(removed) == 0 {
+			return nil
+		}
+
+		_, err = tx.UpdateMemberRoles(ctx, database.UpdateMemberRolesParams{
+			GrantedRoles: granted,
+			UserID:       userID,
+			OrgID:        orgID,
+		})
+		if err != nil {
+			return xerrors.Errorf("update organization member roles: %w", err)
+		}
+
+		return nil
+	}, nil)
+}
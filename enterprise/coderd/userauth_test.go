@@ -420,6 +420,70 @@ func TestUserOIDC(t *testing.T) {
 		})
 	})
 
+	t.Run("OrganizationRoleSync", func(t *testing.T) {
+		t.Parallel()
+
+		// A user is granted organization roles through a group claim, and
+		// loses them once the group is no longer in the claim.
+		t.Run("AddThenRemoveOnReAuth", func(t *testing.T) {
+			t.Parallel()
+
+			const groupClaim = "custom-groups"
+			const adminGroup = "org-admins"
+			runner := setupOIDCTest(t, oidcTestConfig{
+				Config: func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+					cfg.GroupField = groupClaim
+					cfg.GroupOrganizationRoleMapping = map[string][]string{
+						adminGroup: {"organization-admin"},
+					}
+				},
+			})
+			orgID := runner.AdminUser.OrganizationIDs[0]
+
+			_, resp := runner.Login(t, jwt.MapClaims{
+				"email":    "alice@coder.com",
+				groupClaim: []string{adminGroup, "unmapped"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertOrganizationRoles(t, "alice", []string{rbac.RoleOrgAdmin(orgID), rbac.RoleOrgMember(orgID)})
+
+			// Login again without the group, the role should be removed.
+			_, resp = runner.Login(t, jwt.MapClaims{
+				"email":    "alice@coder.com",
+				groupClaim: []string{"unmapped"},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertOrganizationRoles(t, "alice", []string{rbac.RoleOrgMember(orgID)})
+		})
+
+		// Roles that are not organization roles are ignored.
+		t.Run("IgnoresUnknownRoles", func(t *testing.T) {
+			t.Parallel()
+
+			const groupClaim = "custom-groups"
+			const group = "bingbong"
+			runner := setupOIDCTest(t, oidcTestConfig{
+				Config: func(cfg *coderd.OIDCConfig) {
+					cfg.AllowSignups = true
+					cfg.GroupField = groupClaim
+					cfg.GroupOrganizationRoleMapping = map[string][]string{
+						group: {rbac.RoleOwner(), "random"},
+					}
+				},
+			})
+			orgID := runner.AdminUser.OrganizationIDs[0]
+
+			_, resp := runner.Login(t, jwt.MapClaims{
+				"email":    "alice@coder.com",
+				groupClaim: []string{group},
+			})
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			runner.AssertOrganizationRoles(t, "alice", []string{rbac.RoleOrgMember(orgID)})
+			runner.AssertRoles(t, "alice", []string{})
+		})
+	})
+
 	t.Run("Refresh", func(t *testing.T) {
 		t.Run("RefreshTokensMultiple", func(t *testing.T) {
 			t.Parallel()
@@ -588,16 +652,52 @@ func TestGroupSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			// From a -> b,c
+			name: "StripPrefixes",
+			modCfg: func(cfg *coderd.OIDCConfig) {
+				cfg.CreateMissingGroups = true
+				cfg.GroupFilter = regexp.MustCompile("^coder-")
+				cfg.GroupPrefixStrip = []string{"coder-"}
+			},
+			initialOrgGroups:   []string{"a", "b"},
+			initialUserGroups:  []string{"a"},
+			expectedUserGroups: []string{"b", "c"},
+			expectedOrgGroups:  []string{"a", "b", "c"},
+			claims: jwt.MapClaims{
+				"groups": []string{"coder-b", "coder-c", "other"},
+			},
+		},
+		{
+			// From a -> b,parent,grandparent
+			name: "NestedGroups",
+			modCfg: func(cfg *coderd.OIDCConfig) {
+				cfg.CreateMissingGroups = true
+				cfg.GroupDirectory = parentGroups{
+					"b":      {"parent"},
+					"parent": {"grandparent"},
+				}
+			},
+			initialOrgGroups:   []string{"a", "b"},
+			initialUserGroups:  []string{"a"},
+			expectedUserGroups: []string{"b", "parent", "grandparent"},
+			expectedOrgGroups:  []string{"a", "b", "parent", "grandparent"},
+			claims: jwt.MapClaims{
+				"groups": []string{"b"},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
+			var groupField string
 			runner := setupOIDCTest(t, oidcTestConfig{
 				Config: func(cfg *coderd.OIDCConfig) {
 					cfg.GroupField = "groups"
 					tc.modCfg(cfg)
+					groupField = cfg.GroupField
 				},
 			})
 
@@ -679,10 +779,34 @@ func TestGroupSync(t *testing.T) {
 					require.Falsef(t, userInGroup, "user should not be in group %s", group.Name)
 				}
 			}
+
+			// The login is recorded as a group sync run.
+			runs, err := runner.AdminClient.GroupSyncRuns(ctx, codersdk.GroupSyncRunsRequest{UserID: user.ID})
+			require.NoError(t, err)
+			if groupField == "" {
+				require.Empty(t, runs)
+				return
+			}
+			require.Len(t, runs, 1)
+			require.Equal(t, codersdk.GroupSyncTriggerLogin, runs[0].Trigger)
+			require.EqualValues(t, 1, runs[0].UsersSynced)
+			require.ElementsMatch(t, tc.expectedUserGroups, runs[0].Groups)
 		})
 	}
 }
 
+// parentGroups is a groupsync.Directory that maps groups to the groups they
+// are nested in.
+type parentGroups map[string][]string
+
+func (parentGroups) UserGroups(context.Context, string) ([]string, error) {
+	return nil, nil
+}
+
+func (p parentGroups) ParentGroups(_ context.Context, group string) ([]string, error) {
+	return p[group], nil
+}
+
 // oidcTestRunner is just a helper to setup and run oidc tests.
 // An actual Coderd instance is used to run the tests.
 type oidcTestRunner struct {
@@ -723,6 +847,18 @@ func (r *oidcTestRunner) AssertRoles(t *testing.T, userIdent string, roles []str
 	require.ElementsMatch(t, roles, roleNames, "expected roles")
 }
 
+func (r *oidcTestRunner) AssertOrganizationRoles(t *testing.T, userIdent string, roles []string) {
+	t.Helper()
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+	user, err := r.AdminClient.User(ctx, userIdent)
+	require.NoError(t, err)
+
+	userRoles, err := r.AdminClient.UserRoles(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.ElementsMatch(t, roles, userRoles.OrganizationRoles[user.OrganizationIDs[0]], "expected organization roles")
+}
+
 func (r *oidcTestRunner) AssertGroups(t *testing.T, userIdent string, groups []string) {
 	t.Helper()
 
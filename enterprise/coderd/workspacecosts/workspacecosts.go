@@ -0,0 +1,68 @@
+// Package workspacecosts records the daily cost of workspace resources over
+// time, so costs can be reported per workspace and rolled up per
+// organization.
+package workspacecosts
+
+import (
+	"context"
+	"time"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+)
+
+const (
+	// DefaultCurrency is the currency of resources without "currency"
+	// metadata.
+	DefaultCurrency = "USD"
+	// Time interval between consecutive recordings. Costs are recorded per
+	// day, so this only bounds how quickly a changed cost is picked up.
+	jobInterval = time.Hour
+)
+
+// Record periodically records the daily cost of workspace resources using
+// default parameters. The returned function stops recording.
+func Record(ctx context.Context, logger slog.Logger, db database.Store) func() {
+	return RecordWithOptions(ctx, logger, db, jobInterval)
+}
+
+// RecordWithOptions periodically records the daily cost of workspace resources
+// using the provided interval. Costs are recorded once immediately.
+func RecordWithOptions(ctx context.Context, logger slog.Logger, db database.Store, interval time.Duration) func() {
+	logger = logger.Named("workspacecosts")
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	//nolint:gocritic // The system records costs without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			now := dbtime.Now()
+			err := db.UpsertWorkspaceResourceCosts(ctx, database.UpsertWorkspaceResourceCostsParams{
+				Date:            now,
+				DefaultCurrency: DefaultCurrency,
+				UpdatedAt:       now,
+			})
+			if err != nil && ctx.Err() == nil {
+				logger.Error(ctx, "failed to record workspace resource costs", slog.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		cancelFunc()
+		<-done
+	}
+}
@@ -0,0 +1,105 @@
+package workspacecosts_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbmem"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
+	"github.com/coder/coder/v2/enterprise/coderd/workspacecosts"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	db := dbmem.New()
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	user := dbgen.User(t, db, database.User{})
+	workspace := dbgen.Workspace(t, db, database.Workspace{
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	})
+	job := dbgen.ProvisionerJob(t, db, nil, database.ProvisionerJob{OrganizationID: org.ID})
+	_ = dbgen.WorkspaceBuild(t, db, database.WorkspaceBuild{
+		WorkspaceID: workspace.ID,
+		JobID:       job.ID,
+		BuildNumber: 1,
+	})
+	instance := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+		JobID:     job.ID,
+		Type:      "aws_instance",
+		Name:      "dev",
+		DailyCost: 10,
+	})
+	disk := dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+		JobID:     job.ID,
+		Type:      "aws_ebs_volume",
+		Name:      "home",
+		DailyCost: 2,
+	})
+	_ = dbgen.WorkspaceResourceMetadatums(t, db, database.WorkspaceResourceMetadatum{
+		WorkspaceResourceID: disk.ID,
+		Key:                 "currency",
+		Value:               sql.NullString{String: "EUR", Valid: true},
+	})
+	// Resources without a cost are not recorded.
+	_ = dbgen.WorkspaceResource(t, db, database.WorkspaceResource{
+		JobID: job.ID,
+		Type:  "null_resource",
+		Name:  "free",
+	})
+
+	stop := workspacecosts.RecordWithOptions(ctx, logger, db, time.Hour)
+	defer stop()
+
+	var costs []database.WorkspaceResourceCost
+	require.Eventually(t, func() bool {
+		var err error
+		costs, err = db.GetWorkspaceResourceCosts(ctx, database.GetWorkspaceResourceCostsParams{
+			WorkspaceID: workspace.ID,
+			StartDate:   dbtime.Now().Add(-24 * time.Hour),
+			EndDate:     dbtime.Now(),
+		})
+		return err == nil && len(costs) == 2
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	require.Equal(t, disk.Type, costs[0].ResourceType)
+	require.Equal(t, "EUR", costs[0].Currency)
+	require.EqualValues(t, 2, costs[0].DailyCost)
+	require.Equal(t, instance.Type, costs[1].ResourceType)
+	require.Equal(t, workspacecosts.DefaultCurrency, costs[1].Currency)
+	require.EqualValues(t, 10, costs[1].DailyCost)
+
+	rollup, err := db.GetOrganizationResourceCostRollup(ctx, database.GetOrganizationResourceCostRollupParams{
+		OrganizationID: org.ID,
+		StartDate:      dbtime.Now().Add(-24 * time.Hour),
+		EndDate:        dbtime.Now(),
+	})
+	require.NoError(t, err)
+	require.Len(t, rollup, 2)
+	for _, row := range rollup {
+		require.Equal(t, user.ID, row.OwnerID)
+		require.EqualValues(t, 1, row.WorkspaceCount)
+	}
+
+	// Other organizations have nothing to roll up.
+	rollup, err = db.GetOrganizationResourceCostRollup(ctx, database.GetOrganizationResourceCostRollupParams{
+		OrganizationID: uuid.New(),
+		StartDate:      dbtime.Now().Add(-24 * time.Hour),
+		EndDate:        dbtime.Now(),
+	})
+	require.NoError(t, err)
+	require.Empty(t, rollup)
+}
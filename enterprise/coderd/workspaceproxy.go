@@ -718,6 +718,19 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		siblingsRes = append(siblingsRes, convertReplica(replica))
 	}
 
+	if api.AGPL.DERPMonitor != nil {
+		api.AGPL.DERPMonitor.ReportFailingNodes(proxy.Name, req.DERPFailingNodes)
+	}
+	if req.Draining {
+		api.Logger.Info(ctx, "workspace proxy replica is draining",
+			slog.F("proxy_name", proxy.Name),
+			slog.F("replica_id", req.ReplicaID),
+			slog.F("hostname", req.ReplicaHostname),
+			slog.F("open_sessions", req.DrainingSessions),
+		)
+		api.ProxyHealth.ReportDraining(proxy.ID, req.ReplicaID, req.ReplicaHostname, req.DrainingSessions)
+	}
+
 	// aReq.New = updatedProxy
 	httpapi.Write(ctx, rw, http.StatusCreated, wsproxysdk.RegisterWorkspaceProxyResponse{
 		AppSecurityKey:      api.AppSecurityKey.String(),
@@ -798,6 +811,7 @@ func (api *API) workspaceProxyDeregister(rw http.ResponseWriter, r *http.Request
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+	api.ProxyHealth.ReplicaStopped(req.ReplicaID)
 
 	rw.WriteHeader(http.StatusNoContent)
 	go api.forceWorkspaceProxyHealthUpdate(api.ctx)
@@ -937,11 +951,12 @@ func convertProxy(p database.WorkspaceProxy, status proxyhealth.ProxyStatus) cod
 		// is returned from.
 		u, _ := url.Parse(p.Url)
 		status = proxyhealth.ProxyStatus{
-			Proxy:     p,
-			ProxyHost: u.Host,
-			Status:    proxyhealth.Healthy,
-			Report:    codersdk.ProxyHealthReport{},
-			CheckedAt: now,
+			Proxy:         p,
+			ProxyHost:     u.Host,
+			Status:        proxyhealth.Healthy,
+			Report:        codersdk.ProxyHealthReport{},
+			CheckedAt:     now,
+			DERPReachable: p.DerpEnabled,
 		}
 		// For primary, created at / updated at are always 'now'
 		p.CreatedAt = now
@@ -965,9 +980,12 @@ func convertProxy(p database.WorkspaceProxy, status proxyhealth.ProxyStatus) cod
 		Deleted:     p.Deleted,
 		Version:     p.Version,
 		Status: codersdk.WorkspaceProxyStatus{
-			Status:    codersdk.ProxyHealthStatus(status.Status),
-			Report:    status.Report,
-			CheckedAt: status.CheckedAt,
+			Status:        codersdk.ProxyHealthStatus(status.Status),
+			Report:        status.Report,
+			CheckedAt:     status.CheckedAt,
+			LatencyMS:     status.Latency.Milliseconds(),
+			DERPReachable: status.DERPReachable,
+			VersionSkew:   status.VersionSkew,
 		},
 	}
 }
@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 
 	"cdr.dev/slog"
 
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -45,6 +49,7 @@ func (c *committer) CommitQuota(
 		consumed int64
 		budget   int64
 		permit   bool
+		reason   string
 	)
 	err = c.Database.InTx(func(s database.Store) error {
 		var err error
@@ -88,6 +93,30 @@ func (c *committer) CommitQuota(
 			return nil
 		}
 
+		// The organization may also cap the total daily cost of its
+		// workspaces, regardless of the budgets of its users.
+		orgQuota, err := s.GetOrganizationQuota(ctx, workspace.OrganizationID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if err == nil && orgQuota.MaxDailyCost > 0 && netIncrease {
+			orgConsumed, err := s.GetQuotaConsumedForOrganization(ctx, workspace.OrganizationID)
+			if err != nil {
+				return err
+			}
+			newOrgConsumed := orgConsumed + int64(request.DailyCost)
+			if newOrgConsumed > int64(orgQuota.MaxDailyCost) {
+				c.Log.Debug(
+					ctx, "over organization quota, rejecting",
+					slog.F("prev_consumed", orgConsumed),
+					slog.F("next_consumed", newOrgConsumed),
+					slog.F("max_daily_cost", orgQuota.MaxDailyCost),
+				)
+				reason = fmt.Sprintf("This build would raise the daily cost of the organization's workspaces to %d, the limit is %d.", newOrgConsumed, orgQuota.MaxDailyCost)
+				return nil
+			}
+		}
+
 		err = s.UpdateWorkspaceBuildCostByID(ctx, database.UpdateWorkspaceBuildCostByIDParams{
 			ID:        nextBuild.ID,
 			DailyCost: request.DailyCost,
@@ -98,8 +127,9 @@ func (c *committer) CommitQuota(
 		permit = true
 		consumed = newConsumed
 		return nil
-	}, &sql.TxOptions{
-		Isolation: sql.LevelSerializable,
+	}, &database.TxOptions{
+		Isolation:    sql.LevelSerializable,
+		TxIdentifier: "commit_quota",
 	})
 	if err != nil {
 		return nil, err
@@ -109,6 +139,7 @@ func (c *committer) CommitQuota(
 		Ok:              permit,
 		CreditsConsumed: int32(consumed),
 		Budget:          int32(budget),
+		Reason:          reason,
 	}, nil
 }
 
@@ -160,3 +191,255 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		Budget:          int(quotaAllowance),
 	})
 }
+
+// @Summary Get organization quota
+// @ID get-organization-quota
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {object} codersdk.OrganizationQuota
+// @Router /organizations/{organization}/quota [get]
+func (api *API) organizationQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	quota, err := api.Database.GetOrganizationQuota(ctx, org.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// No limits have been set.
+		httpapi.Write(ctx, rw, http.StatusOK, codersdk.OrganizationQuota{})
+		return
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertOrganizationQuota(quota))
+}
+
+// @Summary Update organization quota
+// @ID update-organization-quota
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.OrganizationQuota true "Organization quota"
+// @Success 200 {object} codersdk.OrganizationQuota
+// @Router /organizations/{organization}/quota [put]
+func (api *API) putOrganizationQuota(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	var req codersdk.OrganizationQuota
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	var validErrs []codersdk.ValidationError
+	if req.MaxRunningWorkspacesPerUser < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "max_running_workspaces_per_user", Detail: "Must be a positive integer or zero for no limit."})
+	}
+	if req.MaxDailyCost < 0 {
+		validErrs = append(validErrs, codersdk.ValidationError{Field: "max_daily_cost", Detail: "Must be a positive integer or zero for no limit."})
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update organization quota.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	quota, err := api.Database.UpsertOrganizationQuota(ctx, database.UpsertOrganizationQuotaParams{
+		OrganizationID:              org.ID,
+		MaxRunningWorkspacesPerUser: req.MaxRunningWorkspacesPerUser,
+		MaxDailyCost:                req.MaxDailyCost,
+		UpdatedAt:                   dbtime.Now(),
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating organization quota.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertOrganizationQuota(quota))
+}
+
+func convertOrganizationQuota(quota database.OrganizationQuota) codersdk.OrganizationQuota {
+	return codersdk.OrganizationQuota{
+		MaxRunningWorkspacesPerUser: quota.MaxRunningWorkspacesPerUser,
+		MaxDailyCost:                quota.MaxDailyCost,
+	}
+}
+
+// @Summary Get workspace costs
+// @ID get-workspace-costs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param start_date query string false "Start date, inclusive. Defaults to 29 days before the end date." format(date)
+// @Param end_date query string false "End date, inclusive. Defaults to today." format(date)
+// @Success 200 {object} codersdk.WorkspaceCosts
+// @Router /workspaces/{workspace}/costs [get]
+func (api *API) workspaceCosts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	startDate, endDate, ok := parseCostDateRange(rw, r)
+	if !ok {
+		return
+	}
+
+	costs, err := api.Database.GetWorkspaceResourceCosts(ctx, database.GetWorkspaceResourceCostsParams{
+		WorkspaceID: workspace.ID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace costs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.WorkspaceCosts{
+		WorkspaceID: workspace.ID,
+		StartDate:   startDate.Format(codersdk.CostDateLayout),
+		EndDate:     endDate.Format(codersdk.CostDateLayout),
+		Resources:   make([]codersdk.WorkspaceResourceCost, 0, len(costs)),
+	}
+	totals := make(map[string]int64)
+	for _, cost := range costs {
+		resp.Resources = append(resp.Resources, codersdk.WorkspaceResourceCost{
+			Date:         cost.Date.Format(codersdk.CostDateLayout),
+			ResourceType: cost.ResourceType,
+			ResourceName: cost.ResourceName,
+			Currency:     cost.Currency,
+			DailyCost:    cost.DailyCost,
+		})
+		totals[cost.Currency] += int64(cost.DailyCost)
+	}
+	resp.Totals = convertCostTotals(totals)
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// @Summary Get organization costs
+// @ID get-organization-costs
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param start_date query string false "Start date, inclusive. Defaults to 29 days before the end date." format(date)
+// @Param end_date query string false "End date, inclusive. Defaults to today." format(date)
+// @Success 200 {object} codersdk.OrganizationCosts
+// @Router /organizations/{organization}/costs [get]
+func (api *API) organizationCosts(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org := httpmw.OrganizationParam(r)
+
+	startDate, endDate, ok := parseCostDateRange(rw, r)
+	if !ok {
+		return
+	}
+
+	rollups, err := api.Database.GetOrganizationResourceCostRollup(ctx, database.GetOrganizationResourceCostRollupParams{
+		OrganizationID: org.ID,
+		StartDate:      startDate,
+		EndDate:        endDate,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization costs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.OrganizationCosts{
+		OrganizationID: org.ID,
+		StartDate:      startDate.Format(codersdk.CostDateLayout),
+		EndDate:        endDate.Format(codersdk.CostDateLayout),
+		Rollups:        make([]codersdk.OrganizationCostRollup, 0, len(rollups)),
+	}
+	totals := make(map[string]int64)
+	for _, rollup := range rollups {
+		resp.Rollups = append(resp.Rollups, codersdk.OrganizationCostRollup{
+			OwnerID:        rollup.OwnerID,
+			TemplateID:     rollup.TemplateID,
+			Currency:       rollup.Currency,
+			WorkspaceCount: rollup.WorkspaceCount,
+			TotalCost:      rollup.TotalCost,
+		})
+		totals[rollup.Currency] += rollup.TotalCost
+	}
+	resp.Totals = convertCostTotals(totals)
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// parseCostDateRange parses the inclusive date range of a cost request. The
+// range defaults to the 30 days up to and including today.
+func parseCostDateRange(rw http.ResponseWriter, r *http.Request) (startDate, endDate time.Time, ok bool) {
+	ctx := r.Context()
+	now := dbtime.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	vals := r.URL.Query()
+	p := httpapi.NewQueryParamParser()
+	endDate = p.Time(vals, today, "end_date", codersdk.CostDateLayout)
+	startDate = p.Time(vals, endDate.AddDate(0, 0, -29), "start_date", codersdk.CostDateLayout)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	if startDate.After(endDate) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameters have invalid values.",
+			Validations: []codersdk.ValidationError{{
+				Field:  "start_date",
+				Detail: "Start date must not be after the end date.",
+			}},
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	return startDate, endDate, true
+}
+
+func convertCostTotals(totals map[string]int64) []codersdk.CostTotal {
+	converted := make([]codersdk.CostTotal, 0, len(totals))
+	for currency, total := range totals {
+		converted = append(converted, codersdk.CostTotal{
+			Currency: currency,
+			Total:    total,
+		})
+	}
+	sort.Slice(converted, func(i, j int) bool {
+		return converted[i].Currency < converted[j].Currency
+	})
+	return converted
+}
@@ -2,8 +2,10 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbtime"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
@@ -235,6 +239,213 @@ func TestWorkspaceQuota(t *testing.T) {
 	})
 }
 
+func TestOrganizationQuota(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MaxRunningWorkspacesPerUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+		quota, err := client.OrganizationQuota(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.OrganizationQuota{}, quota)
+
+		_, err = client.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.OrganizationQuota{
+			MaxRunningWorkspacesPerUser: -1,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		quota, err = client.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.OrganizationQuota{
+			MaxRunningWorkspacesPerUser: 1,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, quota.MaxRunningWorkspacesPerUser)
+
+		// Members can read the quota, but not change it.
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		got, err := member.OrganizationQuota(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, quota, got)
+		_, err = member.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.OrganizationQuota{})
+		require.Error(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID: template.ID,
+			Name:       "second",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		require.Contains(t, apiErr.Message, "the limit is 1")
+
+		// The limit is per user, so other members can still start workspaces.
+		memberWorkspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, member, memberWorkspace.LatestBuild.ID)
+
+		// Once the first workspace is stopped, another one can be started.
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, build.ID)
+		second := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, second.LatestBuild.ID)
+	})
+
+	t.Run("MaxDailyCost", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+		// The user's budget allows three workspaces, but the organization
+		// only allows two.
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(6),
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateOrganizationQuota(ctx, user.OrganizationID, codersdk.OrganizationQuota{
+			MaxDailyCost: 4,
+		})
+		require.NoError(t, err)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  planWithCost(2),
+			ProvisionApply: applyWithCost(2),
+		})
+		coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		for i := 0; i < 2; i++ {
+			workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+			build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+			require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+		}
+
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusFailed, build.Status)
+		require.Contains(t, build.Job.Error, "daily cost of the organization")
+		verifyQuota(ctx, t, client, 4, 6)
+	})
+}
+
+func TestWorkspaceCosts(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	client, _, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+	coderdtest.NewProvisionerDaemon(t, api.AGPL)
+
+	// The workspace must fit in the quota to be built.
+	_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+		QuotaAllowance: ptr.Ref(4),
+	})
+	require.NoError(t, err)
+
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Response{{
+			Type: &proto.Response_Apply{
+				Apply: &proto.ApplyComplete{
+					Resources: []*proto.Resource{{
+						Name:      "dev",
+						Type:      "aws_instance",
+						DailyCost: 3,
+					}, {
+						Name:      "home",
+						Type:      "aws_ebs_volume",
+						DailyCost: 1,
+						Metadata: []*proto.Resource_Metadata{{
+							Key:   "currency",
+							Value: "EUR",
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJobCompleted(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJobCompleted(t, client, workspace.LatestBuild.ID)
+
+	//nolint:gocritic // Costs are recorded by the system.
+	err = api.Database.UpsertWorkspaceResourceCosts(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspaceResourceCostsParams{
+		Date:            dbtime.Now(),
+		DefaultCurrency: "USD",
+		UpdatedAt:       dbtime.Now(),
+	})
+	require.NoError(t, err)
+
+	costs, err := client.WorkspaceCosts(ctx, workspace.ID, codersdk.CostsRequest{})
+	require.NoError(t, err)
+	require.Len(t, costs.Resources, 2)
+	require.Equal(t, "aws_ebs_volume", costs.Resources[0].ResourceType)
+	require.Equal(t, "EUR", costs.Resources[0].Currency)
+	require.Equal(t, "aws_instance", costs.Resources[1].ResourceType)
+	require.Equal(t, "USD", costs.Resources[1].Currency)
+	require.Equal(t, []codersdk.CostTotal{
+		{Currency: "EUR", Total: 1},
+		{Currency: "USD", Total: 3},
+	}, costs.Totals)
+
+	orgCosts, err := client.OrganizationCosts(ctx, user.OrganizationID, codersdk.CostsRequest{})
+	require.NoError(t, err)
+	require.Len(t, orgCosts.Rollups, 2)
+	for _, rollup := range orgCosts.Rollups {
+		require.Equal(t, user.UserID, rollup.OwnerID)
+		require.Equal(t, template.ID, rollup.TemplateID)
+		require.EqualValues(t, 1, rollup.WorkspaceCount)
+	}
+	require.Equal(t, costs.Totals, orgCosts.Totals)
+
+	// Costs outside of the date range are excluded.
+	costs, err = client.WorkspaceCosts(ctx, workspace.ID, codersdk.CostsRequest{
+		StartDate: time.Now().AddDate(0, 0, -10),
+		EndDate:   time.Now().AddDate(0, 0, -5),
+	})
+	require.NoError(t, err)
+	require.Empty(t, costs.Resources)
+	require.Empty(t, costs.Totals)
+
+	// The start date must not be after the end date.
+	_, err = client.WorkspaceCosts(ctx, workspace.ID, codersdk.CostsRequest{
+		StartDate: time.Now(),
+		EndDate:   time.Now().AddDate(0, 0, -1),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
 func planWithCost(cost int32) []*proto.Response {
 	return []*proto.Response{{
 		Type: &proto.Response_Plan{
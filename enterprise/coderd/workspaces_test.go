@@ -312,6 +312,66 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		require.True(t, ws.LastUsedAt.After(dormantLastUsedAt))
 	})
 
+	t.Run("DormancyExempt", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx         = testutil.Context(t, testutil.WaitMedium)
+			ticker      = make(chan time.Time)
+			statCh      = make(chan autobuild.Stats)
+			inactiveTTL = time.Minute
+		)
+
+		client, db, user := coderdenttest.NewWithDatabase(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AutobuildTicker:       ticker,
+				AutobuildStats:        statCh,
+				TemplateScheduleStore: schedule.NewEnterpriseTemplateScheduleStore(agplUserQuietHoursScheduleStore()),
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{codersdk.FeatureAdvancedTemplateScheduling: 1},
+			},
+		})
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		tpl := dbfake.TemplateVersion(t, db).Seed(database.TemplateVersion{
+			OrganizationID: user.OrganizationID,
+			CreatedBy:      user.UserID,
+		}).Do().Template
+
+		template := coderdtest.UpdateTemplateMeta(t, client, tpl.ID, codersdk.UpdateTemplateMeta{
+			TimeTilDormantMillis: inactiveTTL.Milliseconds(),
+		})
+
+		workspace := dbfake.WorkspaceBuild(t, db, database.Workspace{
+			OrganizationID: user.OrganizationID,
+			OwnerID:        member.ID,
+			TemplateID:     template.ID,
+		}).Seed(database.WorkspaceBuild{
+			Transition: database.WorkspaceTransitionStart,
+		}).Do().Workspace
+
+		// Owners can't exempt their own workspaces.
+		err := memberClient.UpdateWorkspaceDormancyExempt(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancyExemptRequest{Exempt: true})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.UpdateWorkspaceDormancyExempt(ctx, workspace.ID, codersdk.UpdateWorkspaceDormancyExemptRequest{Exempt: true})
+		require.NoError(t, err)
+
+		// Simulate being inactive.
+		ticker <- workspace.LastUsedAt.Add(inactiveTTL * 2)
+		stats := <-statCh
+
+		// Expect no transitions since the workspace is exempt.
+		require.Len(t, stats.Transitions, 0)
+
+		ws := coderdtest.MustWorkspace(t, client, workspace.ID)
+		require.True(t, ws.DormancyExempt)
+		require.Nil(t, ws.DormantAt)
+	})
+
 	// This test serves as a regression prevention for generating
 	// audit logs in the same transaction the transition workspaces to
 	// the dormant state. The auditor that is passed to autobuild does
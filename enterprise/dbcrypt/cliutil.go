@@ -3,6 +3,7 @@ package dbcrypt
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"golang.org/x/xerrors"
 
@@ -10,8 +11,9 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 )
 
-// Rotate rotates the database encryption keys by re-encrypting all user tokens
-// with the first cipher and revoking all other ciphers.
+// Rotate rotates the database encryption keys by re-encrypting all user tokens,
+// template variable values and webhook secrets with the first cipher and
+// revoking all other ciphers.
 func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cryptDB, err := New(ctx, db, ciphers...)
@@ -73,15 +75,27 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 				}
 			}
 			return nil
-		}, &sql.TxOptions{
-			Isolation: sql.LevelRepeatableRead,
-		})
+		}, rowTxOptions())
 		if err != nil {
 			return xerrors.Errorf("update user links: %w", err)
 		}
 		log.Debug(ctx, "encrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	err = reencryptTemplateVariableValues(ctx, log, db, cryptDB, func(keyID sql.NullString) bool {
+		return keyID.Valid && keyID.String != ciphers[0].HexDigest()
+	})
+	if err != nil {
+		return err
+	}
+
+	err = reencryptWebhookSecrets(ctx, log, cryptDB, func(keyID sql.NullString) bool {
+		return keyID.String != ciphers[0].HexDigest()
+	})
+	if err != nil {
+		return err
+	}
+
 	// Revoke old keys
 	for _, c := range ciphers[1:] {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -93,7 +107,8 @@ func Rotate(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciphe
 	return nil
 }
 
-// Decrypt decrypts all user tokens and revokes all ciphers.
+// Decrypt decrypts all user tokens, template variable values and webhook
+// secrets, and revokes all ciphers.
 func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Cipher) error {
 	db := database.New(sqlDB)
 	cdb, err := New(ctx, db, ciphers...)
@@ -163,15 +178,27 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 				}
 			}
 			return nil
-		}, &sql.TxOptions{
-			Isolation: sql.LevelRepeatableRead,
-		})
+		}, rowTxOptions())
 		if err != nil {
 			return xerrors.Errorf("update user links: %w", err)
 		}
 		log.Debug(ctx, "decrypted user tokens", slog.F("user_id", uid), slog.F("current", idx+1), slog.F("cipher", ciphers[0].HexDigest()))
 	}
 
+	err = reencryptTemplateVariableValues(ctx, log, db, cryptDB, func(keyID sql.NullString) bool {
+		return keyID.Valid
+	})
+	if err != nil {
+		return err
+	}
+
+	err = reencryptWebhookSecrets(ctx, log, cryptDB, func(keyID sql.NullString) bool {
+		return keyID.Valid
+	})
+	if err != nil {
+		return err
+	}
+
 	// Revoke _all_ keys
 	for _, c := range ciphers {
 		if err := db.RevokeDBCryptKey(ctx, c.HexDigest()); err != nil {
@@ -183,6 +210,134 @@ func Decrypt(ctx context.Context, log slog.Logger, sqlDB *sql.DB, ciphers []Ciph
 	return nil
 }
 
+// rowTxOptions are the options of the transactions that re-encrypt rows.
+// coderd may update a row while it's being re-encrypted, in which case the
+// transaction conflicts and is retried with the new value, instead of
+// overwriting it with the old one.
+func rowTxOptions() *database.TxOptions {
+	return &database.TxOptions{
+		Isolation:       sql.LevelRepeatableRead,
+		RetryOnConflict: true,
+	}
+}
+
+// reencryptTemplateVariableValues updates the template variable values whose
+// key ID matches, so they are encrypted with the primary cipher of cryptDB, or
+// stored in plain text if it has none. The values of each template and
+// organization are updated in their own transaction.
+func reencryptTemplateVariableValues(ctx context.Context, log slog.Logger, db database.Store, cryptDB database.Store, match func(keyID sql.NullString) bool) error {
+	templates, err := db.GetTemplates(ctx)
+	if err != nil {
+		return xerrors.Errorf("get templates: %w", err)
+	}
+	log.Info(ctx, "updating template variable values", slog.F("template_count", len(templates)))
+	for _, template := range templates {
+		err := cryptDB.InTx(func(tx database.Store) error {
+			values, err := tx.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+			if err != nil {
+				return xerrors.Errorf("get variable values of template %s: %w", template.ID, err)
+			}
+			for _, value := range values {
+				if !match(value.ValueKeyID) {
+					continue
+				}
+				if _, err := tx.UpsertTemplateVariableValue(ctx, database.UpsertTemplateVariableValueParams{
+					TemplateID: value.TemplateID,
+					Name:       value.Name,
+					Value:      value.Value,
+					ValueKeyID: sql.NullString{}, // dbcrypt will update as required
+					Sensitive:  value.Sensitive,
+					CreatedAt:  value.CreatedAt,
+					UpdatedAt:  value.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update variable value template_id=%s name=%s: %w", value.TemplateID, value.Name, err)
+				}
+			}
+			return nil
+		}, rowTxOptions())
+		if err != nil {
+			return err
+		}
+	}
+
+	organizations, err := db.GetOrganizations(ctx)
+	if err != nil {
+		return xerrors.Errorf("get organizations: %w", err)
+	}
+	for _, organization := range organizations {
+		err := cryptDB.InTx(func(tx database.Store) error {
+			values, err := tx.GetOrganizationTemplateVariableValues(ctx, organization.ID)
+			if err != nil {
+				return xerrors.Errorf("get template variable values of organization %s: %w", organization.ID, err)
+			}
+			for _, value := range values {
+				if !match(value.ValueKeyID) {
+					continue
+				}
+				if _, err := tx.UpsertOrganizationTemplateVariableValue(ctx, database.UpsertOrganizationTemplateVariableValueParams{
+					OrganizationID: value.OrganizationID,
+					Name:           value.Name,
+					Value:          value.Value,
+					ValueKeyID:     sql.NullString{}, // dbcrypt will update as required
+					Sensitive:      value.Sensitive,
+					CreatedAt:      value.CreatedAt,
+					UpdatedAt:      value.UpdatedAt,
+				}); err != nil {
+					return xerrors.Errorf("update template variable value organization_id=%s name=%s: %w", value.OrganizationID, value.Name, err)
+				}
+			}
+			return nil
+		}, rowTxOptions())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reencryptWebhookSecrets updates the webhook secrets whose key ID matches, so
+// they are encrypted with the primary cipher of cryptDB, or stored in plain
+// text if it has none. Each webhook is updated in its own transaction.
+func reencryptWebhookSecrets(ctx context.Context, log slog.Logger, cryptDB database.Store, match func(keyID sql.NullString) bool) error {
+	webhooks, err := cryptDB.GetWebhooks(ctx)
+	if err != nil {
+		return xerrors.Errorf("get webhooks: %w", err)
+	}
+	log.Info(ctx, "updating webhook secrets", slog.F("webhook_count", len(webhooks)))
+	for _, listed := range webhooks {
+		if !match(listed.SecretKeyID) {
+			continue
+		}
+		err := cryptDB.InTx(func(tx database.Store) error {
+			// Read the webhook again, its secret may have changed since it
+			// was listed.
+			webhook, err := tx.GetWebhookByID(ctx, listed.ID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return xerrors.Errorf("get webhook %s: %w", listed.ID, err)
+			}
+			if !match(webhook.SecretKeyID) {
+				return nil
+			}
+			err = tx.UpdateWebhookSecretByID(ctx, database.UpdateWebhookSecretByIDParams{
+				ID:          webhook.ID,
+				Secret:      webhook.Secret,
+				SecretKeyID: sql.NullString{}, // dbcrypt will update as required
+			})
+			if err != nil {
+				return xerrors.Errorf("update webhook secret webhook_id=%s: %w", webhook.ID, err)
+			}
+			return nil
+		}, rowTxOptions())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // nolint: gosec
 const sqlDeleteEncryptedUserTokens = `
 BEGIN;
@@ -192,10 +347,17 @@ DELETE FROM user_links
 DELETE FROM external_auth_links
 	WHERE oauth_access_token_key_id IS NOT NULL
 	OR oauth_refresh_token_key_id IS NOT NULL;
+DELETE FROM template_variable_values
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM organization_template_variable_values
+	WHERE value_key_id IS NOT NULL;
+DELETE FROM webhooks
+	WHERE secret_key_id IS NOT NULL;
 COMMIT;
 `
 
-// Delete deletes all user tokens and revokes all ciphers.
+// Delete deletes all user tokens, encrypted template variable values and
+// encrypted webhooks, and revokes all ciphers.
 // This is a destructive operation and should only be used
 // as a last resort, for example, if the database encryption key has been
 // lost.
@@ -205,7 +367,7 @@ func Delete(ctx context.Context, log slog.Logger, sqlDB *sql.DB) error {
 	if err != nil {
 		return xerrors.Errorf("delete user links: %w", err)
 	}
-	log.Info(ctx, "deleted encrypted user tokens")
+	log.Info(ctx, "deleted encrypted user tokens, template variable values and webhooks")
 
 	log.Info(ctx, "revoking all active keys")
 	keys, err := store.GetDBCryptKeys(ctx)
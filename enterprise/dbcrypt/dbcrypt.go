@@ -60,7 +60,7 @@ type dbCrypt struct {
 	database.Store
 }
 
-func (db *dbCrypt) InTx(function func(database.Store) error, txOpts *sql.TxOptions) error {
+func (db *dbCrypt) InTx(function func(database.Store) error, txOpts *database.TxOptions) error {
 	return db.Store.InTx(func(s database.Store) error {
 		return function(&dbCrypt{
 			primaryCipherDigest: db.primaryCipherDigest,
@@ -261,6 +261,124 @@ func (db *dbCrypt) UpdateExternalAuthLink(ctx context.Context, params database.U
 	return link, nil
 }
 
+func (db *dbCrypt) GetTemplateVariableValuesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.TemplateVariableValue, error) {
+	values, err := db.Store.GetTemplateVariableValuesByTemplateID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range values {
+		if err := db.decryptField(&values[idx].Value, values[idx].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (db *dbCrypt) UpsertTemplateVariableValue(ctx context.Context, params database.UpsertTemplateVariableValueParams) (database.TemplateVariableValue, error) {
+	// Only values marked as sensitive are encrypted.
+	if params.Sensitive {
+		if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+			return database.TemplateVariableValue{}, err
+		}
+	}
+	value, err := db.Store.UpsertTemplateVariableValue(ctx, params)
+	if err != nil {
+		return database.TemplateVariableValue{}, err
+	}
+	if err := db.decryptField(&value.Value, value.ValueKeyID); err != nil {
+		return database.TemplateVariableValue{}, err
+	}
+	return value, nil
+}
+
+func (db *dbCrypt) GetOrganizationTemplateVariableValues(ctx context.Context, organizationID uuid.UUID) ([]database.OrganizationTemplateVariableValue, error) {
+	values, err := db.Store.GetOrganizationTemplateVariableValues(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range values {
+		if err := db.decryptField(&values[idx].Value, values[idx].ValueKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func (db *dbCrypt) UpsertOrganizationTemplateVariableValue(ctx context.Context, params database.UpsertOrganizationTemplateVariableValueParams) (database.OrganizationTemplateVariableValue, error) {
+	// Only values marked as sensitive are encrypted.
+	if params.Sensitive {
+		if err := db.encryptField(&params.Value, &params.ValueKeyID); err != nil {
+			return database.OrganizationTemplateVariableValue{}, err
+		}
+	}
+	value, err := db.Store.UpsertOrganizationTemplateVariableValue(ctx, params)
+	if err != nil {
+		return database.OrganizationTemplateVariableValue{}, err
+	}
+	if err := db.decryptField(&value.Value, value.ValueKeyID); err != nil {
+		return database.OrganizationTemplateVariableValue{}, err
+	}
+	return value, nil
+}
+
+func (db *dbCrypt) GetWebhooks(ctx context.Context) ([]database.Webhook, error) {
+	webhooks, err := db.Store.GetWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range webhooks {
+		if err := db.decryptField(&webhooks[idx].Secret, webhooks[idx].SecretKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return webhooks, nil
+}
+
+func (db *dbCrypt) GetWebhooksByEvent(ctx context.Context, event string) ([]database.Webhook, error) {
+	webhooks, err := db.Store.GetWebhooksByEvent(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range webhooks {
+		if err := db.decryptField(&webhooks[idx].Secret, webhooks[idx].SecretKeyID); err != nil {
+			return nil, err
+		}
+	}
+	return webhooks, nil
+}
+
+func (db *dbCrypt) GetWebhookByID(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	webhook, err := db.Store.GetWebhookByID(ctx, id)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+	if err := db.decryptField(&webhook.Secret, webhook.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (db *dbCrypt) InsertWebhook(ctx context.Context, params database.InsertWebhookParams) (database.Webhook, error) {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	webhook, err := db.Store.InsertWebhook(ctx, params)
+	if err != nil {
+		return database.Webhook{}, err
+	}
+	if err := db.decryptField(&webhook.Secret, webhook.SecretKeyID); err != nil {
+		return database.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (db *dbCrypt) UpdateWebhookSecretByID(ctx context.Context, params database.UpdateWebhookSecretByIDParams) error {
+	if err := db.encryptField(&params.Secret, &params.SecretKeyID); err != nil {
+		return err
+	}
+	return db.Store.UpdateWebhookSecretByID(ctx, params)
+}
+
 func (db *dbCrypt) encryptField(field *string, digest *sql.NullString) error {
 	// If no cipher is loaded, then we can't encrypt anything!
 	if db.ciphers == nil || db.primaryCipherDigest == "" {
@@ -370,5 +488,5 @@ func (db *dbCrypt) ensureEncrypted(ctx context.Context) error {
 			ActiveKeyDigest: db.primaryCipherDigest,
 			Test:            testValue,
 		})
-	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	}, &database.TxOptions{Isolation: sql.LevelRepeatableRead})
 }
@@ -9,6 +9,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -349,6 +350,153 @@ func TestExternalAuthLinks(t *testing.T) {
 	})
 }
 
+func TestTemplateVariableValues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("UpsertTemplateVariableValue", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		template := dbgen.Template(t, db, database.Template{})
+		value, err := crypt.UpsertTemplateVariableValue(ctx, database.UpsertTemplateVariableValueParams{
+			TemplateID: template.ID,
+			Name:       "token",
+			Value:      "secret",
+			Sensitive:  true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", value.Value)
+		_, err = crypt.UpsertTemplateVariableValue(ctx, database.UpsertTemplateVariableValueParams{
+			TemplateID: template.ID,
+			Name:       "region",
+			Value:      "eu-west-1",
+		})
+		require.NoError(t, err)
+
+		// Only sensitive values are encrypted.
+		rawValues, err := db.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, rawValues, 2)
+		require.Equal(t, "eu-west-1", rawValues[0].Value)
+		require.False(t, rawValues[0].ValueKeyID.Valid)
+		requireEncryptedEquals(t, ciphers[0], rawValues[1].Value, "secret")
+		require.Equal(t, ciphers[0].HexDigest(), rawValues[1].ValueKeyID.String)
+
+		values, err := crypt.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		require.Equal(t, "eu-west-1", values[0].Value)
+		require.Equal(t, "secret", values[1].Value)
+	})
+
+	t.Run("UpsertOrganizationTemplateVariableValue", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		org := dbgen.Organization(t, db, database.Organization{})
+		value, err := crypt.UpsertOrganizationTemplateVariableValue(ctx, database.UpsertOrganizationTemplateVariableValueParams{
+			OrganizationID: org.ID,
+			Name:           "token",
+			Value:          "secret",
+			Sensitive:      true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", value.Value)
+
+		rawValues, err := db.GetOrganizationTemplateVariableValues(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, rawValues, 1)
+		requireEncryptedEquals(t, ciphers[0], rawValues[0].Value, "secret")
+
+		values, err := crypt.GetOrganizationTemplateVariableValues(ctx, org.ID)
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		require.Equal(t, "secret", values[0].Value)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		template := dbgen.Template(t, db, database.Template{})
+		_, err := db.UpsertTemplateVariableValue(ctx, database.UpsertTemplateVariableValueParams{
+			TemplateID: template.ID,
+			Name:       "token",
+			Value:      fakeBase64RandomData(t, 32),
+			ValueKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+			Sensitive:  true,
+		})
+		require.NoError(t, err)
+		_, err = crypt.GetTemplateVariableValuesByTemplateID(ctx, template.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
+func TestWebhooks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("InsertWebhook", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		webhook, err := crypt.InsertWebhook(ctx, database.InsertWebhookParams{
+			ID:     uuid.New(),
+			Url:    "https://example.com/hook",
+			Secret: "secret",
+			Events: []string{"user.created"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", webhook.Secret)
+
+		rawWebhook, err := db.GetWebhookByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], rawWebhook.Secret, "secret")
+		require.Equal(t, ciphers[0].HexDigest(), rawWebhook.SecretKeyID.String)
+
+		got, err := crypt.GetWebhookByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, "secret", got.Secret)
+
+		webhooks, err := crypt.GetWebhooksByEvent(ctx, "user.created")
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, "secret", webhooks[0].Secret)
+	})
+
+	t.Run("UpdateWebhookSecretByID", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		webhook := dbgen.Webhook(t, db, database.Webhook{Secret: "plaintext"})
+		err := crypt.UpdateWebhookSecretByID(ctx, database.UpdateWebhookSecretByIDParams{
+			ID:     webhook.ID,
+			Secret: "plaintext",
+		})
+		require.NoError(t, err)
+
+		rawWebhook, err := db.GetWebhookByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		requireEncryptedEquals(t, ciphers[0], rawWebhook.Secret, "plaintext")
+
+		webhooks, err := crypt.GetWebhooks(ctx)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, "plaintext", webhooks[0].Secret)
+	})
+
+	t.Run("DecryptErr", func(t *testing.T) {
+		t.Parallel()
+		db, crypt, ciphers := setup(t)
+		webhook := dbgen.Webhook(t, db, database.Webhook{
+			Secret:      fakeBase64RandomData(t, 32),
+			SecretKeyID: sql.NullString{String: ciphers[0].HexDigest(), Valid: true},
+		})
+		_, err := crypt.GetWebhookByID(ctx, webhook.ID)
+		require.Error(t, err, "expected an error")
+		var derr *DecryptFailedError
+		require.ErrorAs(t, err, &derr, "expected a decrypt error")
+	})
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -620,7 +768,7 @@ func TestEncryptDecryptField(t *testing.T) {
 
 func expectInTx(mdb *dbmock.MockStore) *gomock.Call {
 	return mdb.EXPECT().InTx(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
-		func(f func(store database.Store) error, _ *sql.TxOptions) error {
+		func(f func(store database.Store) error, _ *database.TxOptions) error {
 			return f(mdb)
 		},
 	)
@@ -11,6 +11,9 @@
 // - database.UserLink.OAuthRefreshToken
 // - database.GitAuthLink.OAuthAccessToken
 // - database.GitAuthLink.OAuthRefreshToken
+// - database.TemplateVariableValue.Value, if the value is sensitive
+// - database.OrganizationTemplateVariableValue.Value, if the value is sensitive
+// - database.Webhook.Secret
 // - database.DBCryptSentinelValue
 //
 // Multiple ciphers can be provided to support key rotation. The primary cipher
@@ -18,6 +21,9 @@
 // for decryption and, as a general rule, should only be active when rotating
 // keys.
 //
+// Keys can optionally be wrapped by an external KMS (envelope encryption), in
+// which case they're unwrapped with DecodeKeys before creating the Ciphers.
+//
 // Encryption keys are stored in the database in the table `dbcrypt_keys`.
 // The table has the following schema:
 //   - number: the key number. This is used to avoid conflicts when rotating keys.
@@ -0,0 +1,179 @@
+package dbcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// KMS wraps and unwraps data encryption keys with a key encryption key that
+// never leaves an external key management service. With a KMS configured, the
+// external token encryption keys are stored wrapped, and are only unwrapped in
+// memory when coderd or the dbcrypt commands start (envelope encryption).
+type KMS interface {
+	// Wrap encrypts a data encryption key and returns the wrapped key.
+	Wrap(ctx context.Context, key []byte) (string, error)
+	// Unwrap decrypts a key previously returned by Wrap.
+	Unwrap(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// NewKMS returns the KMS for the given URL. Only the HashiCorp Vault transit
+// secrets engine is currently supported, with URLs of the form
+// https://vault.example.com:8200/{mount}/{key}. The token and the keys are
+// sent to the KMS, so plain http is only allowed for loopback addresses.
+func NewKMS(rawURL string, token string, client *http.Client) (KMS, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, xerrors.Errorf("parse kms url: %w", err)
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !isLoopback(u.Hostname()) {
+			return nil, xerrors.Errorf("kms url %q must use https, http is only allowed for loopback addresses", rawURL)
+		}
+	default:
+		return nil, xerrors.Errorf("unsupported kms url scheme %q, expected https", u.Scheme)
+	}
+	mount, key := path.Split(strings.Trim(u.Path, "/"))
+	mount = strings.Trim(mount, "/")
+	if mount == "" || key == "" {
+		return nil, xerrors.Errorf("kms url %q must have the form <address>/<mount>/<key>", rawURL)
+	}
+	if token == "" {
+		return nil, xerrors.New("a kms token is required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &vaultTransit{
+		address: &url.URL{Scheme: u.Scheme, Host: u.Host},
+		mount:   mount,
+		key:     key,
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+// isLoopback returns true if host is localhost or a loopback IP address.
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// DecodeKeys returns the raw data encryption keys. Keys are unwrapped with the
+// KMS if one is given, otherwise they must be base64-encoded.
+func DecodeKeys(ctx context.Context, kms KMS, keys []string) ([][]byte, error) {
+	decoded := make([][]byte, 0, len(keys))
+	for idx, k := range keys {
+		var (
+			dk  []byte
+			err error
+		)
+		if kms != nil {
+			dk, err = kms.Unwrap(ctx, k)
+		} else {
+			dk, err = base64.StdEncoding.DecodeString(k)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("decode key %d: %w", idx, err)
+		}
+		decoded = append(decoded, dk)
+	}
+	return decoded, nil
+}
+
+// vaultTransit implements KMS with the transit secrets engine of HashiCorp
+// Vault.
+type vaultTransit struct {
+	address *url.URL
+	mount   string
+	key     string
+	token   string
+	client  *http.Client
+}
+
+func (v *vaultTransit) Wrap(ctx context.Context, key []byte) (string, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := v.do(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	}, &resp)
+	if err != nil {
+		return "", xerrors.Errorf("wrap key: %w", err)
+	}
+	return resp.Ciphertext, nil
+}
+
+func (v *vaultTransit) Unwrap(ctx context.Context, wrapped string) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	err := v.do(ctx, "decrypt", map[string]string{
+		"ciphertext": wrapped,
+	}, &resp)
+	if err != nil {
+		return nil, xerrors.Errorf("unwrap key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, xerrors.Errorf("decode unwrapped key: %w", err)
+	}
+	return key, nil
+}
+
+func (v *vaultTransit) do(ctx context.Context, op string, body any, data any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	u := v.address.JoinPath("v1", v.mount, op, v.key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+		if json.Unmarshal(raw, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return xerrors.Errorf("vault returned %d: %s", res.StatusCode, strings.Join(vaultErr.Errors, "; "))
+		}
+		return xerrors.Errorf("vault returned %d: %s", res.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return xerrors.Errorf("decode response: %w", err)
+	}
+	err = json.Unmarshal(resp.Data, data)
+	if err != nil {
+		return xerrors.Errorf("decode response data: %w", err)
+	}
+	return nil
+}
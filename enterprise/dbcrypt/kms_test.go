@@ -0,0 +1,100 @@
+package dbcrypt_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/dbcrypt"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestVaultTransitKMS(t *testing.T) {
+	t.Parallel()
+
+	// The fake transit engine "encrypts" by prefixing the plaintext.
+	const token = "s.vault-token"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		var req map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/coder":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{
+				"ciphertext": "vault:v1:" + req["plaintext"],
+			}})
+		case "/v1/transit/decrypt/coder":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{
+				"plaintext": strings.TrimPrefix(req["ciphertext"], "vault:v1:"),
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("WrapUnwrap", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		kms, err := dbcrypt.NewKMS(srv.URL+"/transit/coder", token, srv.Client())
+		require.NoError(t, err)
+
+		key := bytes.Repeat([]byte{'a'}, 32)
+		wrapped, err := kms.Wrap(ctx, key)
+		require.NoError(t, err)
+		require.NotEqual(t, base64.StdEncoding.EncodeToString(key), wrapped)
+
+		keys, err := dbcrypt.DecodeKeys(ctx, kms, []string{wrapped})
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{key}, keys)
+
+		ciphers, err := dbcrypt.NewCiphers(keys...)
+		require.NoError(t, err)
+		require.Len(t, ciphers, 1)
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		kms, err := dbcrypt.NewKMS(srv.URL+"/transit/coder", "wrong", srv.Client())
+		require.NoError(t, err)
+		_, err = kms.Unwrap(ctx, "vault:v1:abc")
+		require.ErrorContains(t, err, "permission denied")
+	})
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := dbcrypt.NewKMS(srv.URL+"/coder", token, nil)
+		require.ErrorContains(t, err, "must have the form")
+		_, err = dbcrypt.NewKMS("awskms:///alias/coder", token, nil)
+		require.ErrorContains(t, err, "unsupported kms url scheme")
+		// The token and keys must not be sent in plain text over the network.
+		_, err = dbcrypt.NewKMS("http://vault.example.com:8200/transit/coder", token, nil)
+		require.ErrorContains(t, err, "must use https")
+		_, err = dbcrypt.NewKMS("http://localhost:8200/transit/coder", token, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("NoKMS", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+
+		key := bytes.Repeat([]byte{'b'}, 32)
+		keys, err := dbcrypt.DecodeKeys(ctx, nil, []string{base64.StdEncoding.EncodeToString(key)})
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{key}, keys)
+	})
+}
@@ -1,6 +1,7 @@
 package tailnet
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"sync"
@@ -35,6 +36,10 @@ type connIO struct {
 	mu           sync.Mutex
 	closed       bool
 	disconnected bool
+	// key is the node key of the peer, and keySince when the peer started
+	// using it or was last asked to rotate it.
+	key      []byte
+	keySince time.Time
 
 	name       string
 	start      int64
@@ -127,6 +132,7 @@ func (c *connIO) handleRequest(req *proto.CoordinateRequest) error {
 	c.logger.Debug(c.peerCtx, "got request")
 	if req.UpdateSelf != nil {
 		c.logger.Debug(c.peerCtx, "got node update", slog.F("node", req.UpdateSelf))
+		c.setKey(req.UpdateSelf.Node.GetKey())
 		b := binding{
 			bKey: bKey(c.UniqueID()),
 			node: req.UpdateSelf.Node,
@@ -190,6 +196,36 @@ func (c *connIO) handleRequest(req *proto.CoordinateRequest) error {
 	return nil
 }
 
+func (c *connIO) setKey(key []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.keySince.IsZero() && bytes.Equal(c.key, key) {
+		return
+	}
+	c.key = key
+	c.keySince = time.Now()
+}
+
+// rotateNodeKey asks the peer to rotate its node key if it has used it since
+// before olderThan. It returns whether the peer was asked.
+func (c *connIO) rotateNodeKey(olderThan time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.keySince.IsZero() || !c.keySince.Before(olderThan) {
+		return false, nil
+	}
+	select {
+	case <-c.peerCtx.Done():
+		return false, c.peerCtx.Err()
+	case c.responses <- &proto.CoordinateResponse{RotateNodeKey: true}:
+		atomic.StoreInt64(&c.lastWrite, time.Now().Unix())
+		c.keySince = time.Now()
+		return true, nil
+	default:
+		return false, agpl.ErrWouldBlock
+	}
+}
+
 func (c *connIO) UniqueID() uuid.UUID {
 	return c.id
 }
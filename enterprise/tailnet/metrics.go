@@ -0,0 +1,55 @@
+package tailnet
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	operationBind   = "bind"
+	operationTunnel = "tunnel"
+	operationMap    = "map"
+
+	dropReasonQueueFull = "queue_full"
+	dropReasonPubsub    = "pubsub"
+)
+
+// Metrics are the Prometheus metrics of the PostgreSQL coordinator. A replica
+// replaces its coordinator when the high availability feature is toggled, so
+// the metrics are created once and shared by every coordinator it starts.
+type Metrics struct {
+	latency        *prometheus.HistogramVec
+	droppedUpdates *prometheus.CounterVec
+}
+
+// NewMetrics creates the coordinator metrics, registered with reg. A nil reg
+// leaves them unregistered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	auto := promauto.With(reg)
+
+	return &Metrics{
+		latency: auto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "pgcoord",
+			Name:      "coordination_latency_seconds",
+			Help:      "Time taken to store node and tunnel updates in the database, and to query the nodes a peer needs.",
+			Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"operation"}),
+		droppedUpdates: auto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "pgcoord",
+			Name:      "dropped_node_updates_total",
+			Help:      "The number of node updates that were dropped before reaching a peer.",
+		}, []string{"reason"}),
+	}
+}
+
+func (m *Metrics) observeLatency(operation string, start time.Time) {
+	m.latency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) dropUpdate(reason string) {
+	m.droppedUpdates.WithLabelValues(reason).Inc()
+}
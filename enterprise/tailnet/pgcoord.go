@@ -61,9 +61,13 @@ const (
 // for querying the store for the nodes the connection needs.  The querier receives pubsub notifications about changes,
 // which trigger queries for the latest state.
 //
-// The querier also sends the coordinator's heartbeat, and monitors the heartbeats of other coordinators.  When
-// heartbeats cease for a coordinator, it stops using any nodes discovered from that coordinator and pushes an update
-// to affected connIOs.
+// The querier also sends the coordinator's heartbeat, and monitors the heartbeats of other coordinators.  Each
+// heartbeat renews the coordinator's lease on the peers bound to it.  When heartbeats cease for a coordinator, its
+// lease lapses, so we stop using any nodes discovered from that coordinator and push an update to affected connIOs.
+//
+// When a coordinator shuts down, it hands off its peers by marking them lost before its lease lapses.  The other
+// coordinators keep sending the lost nodes to their peers, which keep their connections while the lost peers
+// reconnect to another coordinator and bind new nodes there.
 //
 // This package uses the term "binding" to mean the act of registering an association between some connection
 // and a *proto.Node.  It uses the term "mapping" to mean the act of determining the nodes that the connection
@@ -108,11 +112,18 @@ var pgCoordSubject = rbac.Subject{
 // NewPGCoord creates a high-availability coordinator that stores state in the PostgreSQL database and
 // receives notifications of updates via the pubsub.
 func NewPGCoord(ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store) (agpl.Coordinator, error) {
-	return newPGCoordInternal(ctx, logger, ps, store)
+	return newPGCoordInternal(ctx, logger, ps, store, NewMetrics(nil))
+}
+
+// NewPGCoordWithMetrics is like NewPGCoord, but records the coordinator's metrics in m.
+func NewPGCoordWithMetrics(
+	ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store, m *Metrics,
+) (agpl.Coordinator, error) {
+	return newPGCoordInternal(ctx, logger, ps, store, m)
 }
 
 func newPGCoordInternal(
-	ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store,
+	ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store, m *Metrics,
 ) (
 	*pgCoord, error,
 ) {
@@ -135,14 +146,14 @@ func newPGCoordInternal(
 		logger:           logger,
 		pubsub:           ps,
 		store:            store,
-		binder:           newBinder(ctx, logger, id, store, bCh, fHB),
+		binder:           newBinder(ctx, logger, id, store, m, bCh, fHB),
 		bindings:         bCh,
 		newConnections:   cCh,
 		closeConnections: ccCh,
-		tunneler:         newTunneler(ctx, logger, id, store, sCh, fHB),
+		tunneler:         newTunneler(ctx, logger, id, store, m, sCh, fHB),
 		tunnelerCh:       sCh,
 		id:               id,
-		querier:          newQuerier(ctx, logger, id, ps, store, id, cCh, ccCh, numQuerierWorkers, fHB),
+		querier:          newQuerier(ctx, logger, id, ps, store, m, id, cCh, ccCh, numQuerierWorkers, fHB),
 		closed:           make(chan struct{}),
 	}
 	logger.Info(ctx, "starting coordinator")
@@ -211,6 +222,13 @@ func (c *pgCoord) Close() error {
 	return nil
 }
 
+// RotateNodeKeys asks the peers connected to this coordinator that have used
+// the same node key since before olderThan to rotate it. Peers connected to
+// other replicas are asked by their own coordinator.
+func (c *pgCoord) RotateNodeKeys(olderThan time.Time) int {
+	return c.querier.rotateNodeKeys(olderThan)
+}
+
 func (c *pgCoord) Coordinate(
 	ctx context.Context, id uuid.UUID, name string, a agpl.TunnelAuth,
 ) (
@@ -250,6 +268,7 @@ type tunneler struct {
 	logger        slog.Logger
 	coordinatorID uuid.UUID
 	store         database.Store
+	metrics       *Metrics
 	updates       <-chan tunnel
 
 	mu     sync.Mutex
@@ -261,6 +280,7 @@ func newTunneler(ctx context.Context,
 	logger slog.Logger,
 	id uuid.UUID,
 	store database.Store,
+	metrics *Metrics,
 	updates <-chan tunnel,
 	startWorkers <-chan struct{},
 ) *tunneler {
@@ -269,6 +289,7 @@ func newTunneler(ctx context.Context,
 		logger:        logger,
 		coordinatorID: id,
 		store:         store,
+		metrics:       metrics,
 		updates:       updates,
 		latest:        make(map[uuid.UUID]map[uuid.UUID]tunnel),
 		workQ:         newWorkQ[tKey](ctx),
@@ -364,6 +385,7 @@ func (t *tunneler) retrieve(k tKey) tunnel {
 }
 
 func (t *tunneler) writeOne(tun tunnel) error {
+	start := time.Now()
 	var err error
 	switch {
 	case tun.dst == uuid.Nil:
@@ -411,6 +433,9 @@ func (t *tunneler) writeOne(tun tunnel) error {
 			slog.F("active", tun.active),
 			slog.Error(err))
 	}
+	if err == nil {
+		t.metrics.observeLatency(operationTunnel, start)
+	}
 	return err
 }
 
@@ -430,6 +455,7 @@ type binder struct {
 	logger        slog.Logger
 	coordinatorID uuid.UUID
 	store         database.Store
+	metrics       *Metrics
 	bindings      <-chan binding
 
 	mu     sync.Mutex
@@ -441,6 +467,7 @@ func newBinder(ctx context.Context,
 	logger slog.Logger,
 	id uuid.UUID,
 	store database.Store,
+	metrics *Metrics,
 	bindings <-chan binding,
 	startWorkers <-chan struct{},
 ) *binder {
@@ -449,6 +476,7 @@ func newBinder(ctx context.Context,
 		logger:        logger,
 		coordinatorID: id,
 		store:         store,
+		metrics:       metrics,
 		bindings:      bindings,
 		latest:        make(map[bKey]binding),
 		workQ:         newWorkQ[bKey](ctx),
@@ -499,6 +527,7 @@ func (b *binder) worker() {
 }
 
 func (b *binder) writeOne(bnd binding) error {
+	start := time.Now()
 	var err error
 	if bnd.kind == proto.CoordinateResponse_PeerUpdate_DISCONNECTED {
 		_, err = b.store.DeleteTailnetPeer(b.ctx, database.DeleteTailnetPeerParams{
@@ -536,6 +565,9 @@ func (b *binder) writeOne(bnd binding) error {
 			slog.F("node", bnd.node),
 			slog.Error(err))
 	}
+	if err == nil {
+		b.metrics.observeLatency(operationBind, start)
+	}
 	return err
 }
 
@@ -598,9 +630,11 @@ type mapper struct {
 
 	// called to filter mappings to healthy coordinators
 	heartbeats *heartbeats
+
+	metrics *Metrics
 }
 
-func newMapper(c *connIO, logger slog.Logger, h *heartbeats) *mapper {
+func newMapper(c *connIO, logger slog.Logger, h *heartbeats, metrics *Metrics) *mapper {
 	logger = logger.With(
 		slog.F("peer_id", c.UniqueID()),
 	)
@@ -611,6 +645,7 @@ func newMapper(c *connIO, logger slog.Logger, h *heartbeats) *mapper {
 		update:     make(chan struct{}),
 		mappings:   make(chan []mapping),
 		heartbeats: h,
+		metrics:    metrics,
 		sent:       make(map[uuid.UUID]mapping),
 	}
 	go m.run()
@@ -638,6 +673,9 @@ func (m *mapper) run() {
 		}
 		if err := m.c.Enqueue(update); err != nil && !xerrors.Is(err, context.Canceled) {
 			m.logger.Error(m.ctx, "failed to enqueue node update", slog.Error(err))
+			if xerrors.Is(err, agpl.ErrWouldBlock) {
+				m.metrics.dropUpdate(dropReasonQueueFull)
+			}
 		}
 	}
 }
@@ -745,6 +783,7 @@ type querier struct {
 	coordinatorID uuid.UUID
 	pubsub        pubsub.Pubsub
 	store         database.Store
+	metrics       *Metrics
 
 	newConnections   chan *connIO
 	closeConnections chan *connIO
@@ -764,6 +803,7 @@ func newQuerier(ctx context.Context,
 	coordinatorID uuid.UUID,
 	ps pubsub.Pubsub,
 	store database.Store,
+	metrics *Metrics,
 	self uuid.UUID,
 	newConnections chan *connIO,
 	closeConnections chan *connIO,
@@ -777,6 +817,7 @@ func newQuerier(ctx context.Context,
 		coordinatorID:    coordinatorID,
 		pubsub:           ps,
 		store:            store,
+		metrics:          metrics,
 		newConnections:   newConnections,
 		closeConnections: closeConnections,
 		workQ:            newWorkQ[querierWorkKey](ctx),
@@ -824,7 +865,7 @@ func (q *querier) newConn(c *connIO) {
 		)
 		return
 	}
-	mpr := newMapper(c, q.logger, q.heartbeats)
+	mpr := newMapper(c, q.logger, q.heartbeats, q.metrics)
 	mk := mKey(c.UniqueID())
 	dup, ok := q.mappers[mk]
 	if ok {
@@ -918,6 +959,7 @@ func (q *querier) peerUpdate(peer uuid.UUID) error {
 // exist).  It then sends the mapping snapshot to the corresponding mapper, where it will get
 // transmitted to the peer.
 func (q *querier) mappingQuery(peer mKey) error {
+	start := time.Now()
 	logger := q.logger.With(slog.F("peer_id", uuid.UUID(peer)))
 	logger.Debug(q.ctx, "querying mappings")
 	bindings, err := q.store.GetTailnetTunnelPeerBindings(q.ctx, uuid.UUID(peer))
@@ -938,7 +980,12 @@ func (q *querier) mappingQuery(peer mKey) error {
 		return nil
 	}
 	logger.Debug(q.ctx, "sending mappings", slog.F("mapping_len", len(mappings)))
-	return agpl.SendCtx(q.ctx, mpr.mappings, mappings)
+	err = agpl.SendCtx(q.ctx, mpr.mappings, mappings)
+	if err != nil {
+		return err
+	}
+	q.metrics.observeLatency(operationMap, start)
+	return nil
 }
 
 func (q *querier) bindingsToMappings(bindings []database.GetTailnetTunnelPeerBindingsRow) ([]mapping, error) {
@@ -1027,6 +1074,7 @@ func (q *querier) subscribe() {
 func (q *querier) listenPeer(_ context.Context, msg []byte, err error) {
 	if xerrors.Is(err, pubsub.ErrDroppedMessages) {
 		q.logger.Warn(q.ctx, "pubsub may have dropped peer updates")
+		q.metrics.dropUpdate(dropReasonPubsub)
 		// we need to schedule a full resync of peer mappings
 		q.resyncPeerMappings()
 		return
@@ -1054,6 +1102,7 @@ func (q *querier) listenPeer(_ context.Context, msg []byte, err error) {
 func (q *querier) listenTunnel(_ context.Context, msg []byte, err error) {
 	if xerrors.Is(err, pubsub.ErrDroppedMessages) {
 		q.logger.Warn(q.ctx, "pubsub may have dropped tunnel updates")
+		q.metrics.dropUpdate(dropReasonPubsub)
 		// we need to schedule a full resync of peer mappings
 		q.resyncPeerMappings()
 		return
@@ -1125,6 +1174,25 @@ func (q *querier) updateAll() {
 	}
 }
 
+func (q *querier) rotateNodeKeys(olderThan time.Time) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var asked int
+	for _, mpr := range q.mappers {
+		ok, err := mpr.c.rotateNodeKey(olderThan)
+		if err != nil {
+			// The peer is asked again the next time keys are rotated.
+			q.logger.Warn(q.ctx, "failed to ask peer to rotate node key",
+				slog.F("peer_id", mpr.c.UniqueID()), slog.Error(err))
+			continue
+		}
+		if ok {
+			asked++
+		}
+	}
+	return asked
+}
+
 // unhealthyCloseAll marks the coordinator unhealthy and closes all connections.  We do this so that peers
 // are forced to reconnect to the coordinator, and will hopefully land on a healthy coordinator.
 func (q *querier) unhealthyCloseAll() {
@@ -1349,12 +1417,15 @@ func newHeartbeats(
 	return h
 }
 
+// filter returns the mappings of coordinators that hold a lease on their peers, that is, ourselves and the
+// coordinators we have received heartbeats from.  Lost mappings are kept regardless, since a coordinator hands off its
+// peers by marking them lost before its lease lapses, and a lost mapping never overrides a node.
 func (h *heartbeats) filter(mappings []mapping) []mapping {
 	out := make([]mapping, 0, len(mappings))
 	h.lock.RLock()
 	defer h.lock.RUnlock()
 	for _, m := range mappings {
-		ok := m.coordinator == h.self
+		ok := m.coordinator == h.self || m.kind == proto.CoordinateResponse_PeerUpdate_LOST
 		if !ok {
 			_, ok = h.coordinators[m.coordinator]
 		}
@@ -1478,7 +1549,7 @@ func (h *heartbeats) sendBeats() {
 	// send an initial heartbeat so that other coordinators can start using our bindings right away.
 	h.sendBeat()
 	close(h.firstHeartbeat) // signal binder it can start writing
-	defer h.sendDelete()
+	defer h.handoff()
 	tkr := time.NewTicker(HeartbeatPeriod)
 	defer tkr.Stop()
 	for {
@@ -1514,15 +1585,22 @@ func (h *heartbeats) sendBeat() {
 	h.failedHeartbeats = 0
 }
 
-func (h *heartbeats) sendDelete() {
+// handoff marks the peers bound to this coordinator as lost when it shuts down.  Other coordinators then tell their
+// peers that these peers are lost, rather than disconnected, until they reconnect to another coordinator.  We don't
+// delete the coordinator, since that would also delete its peers and tunnels.  Instead, its lease lapses as it stops
+// sending heartbeats, and the rows are cleaned up with other stale coordinators.
+func (h *heartbeats) handoff() {
 	// here we don't want to use the main context, since it will have been canceled
 	ctx := dbauthz.As(context.Background(), pgCoordSubject)
-	err := h.store.DeleteCoordinator(ctx, h.self)
+	err := h.store.UpdateTailnetPeerStatusByCoordinator(ctx, database.UpdateTailnetPeerStatusByCoordinatorParams{
+		CoordinatorID: h.self,
+		Status:        database.TailnetStatusLost,
+	})
 	if err != nil {
-		h.logger.Error(h.ctx, "failed to send coordinator delete", slog.Error(err))
+		h.logger.Error(h.ctx, "failed to hand off peers", slog.Error(err))
 		return
 	}
-	h.logger.Debug(h.ctx, "deleted coordinator")
+	h.logger.Debug(h.ctx, "handed off peers")
 }
 
 func (h *heartbeats) cleanupLoop() {
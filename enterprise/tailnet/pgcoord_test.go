@@ -401,7 +401,8 @@ func TestPGCoordinatorDual_Mainline(t *testing.T) {
 	err = client21.recvErr(ctx, t)
 	require.ErrorIs(t, err, io.EOF)
 
-	assertEventuallyNoAgents(ctx, t, store, agent2.id)
+	// coord2 hands off its peers by marking them lost
+	assertEventuallyLost(ctx, t, store, agent2.id)
 
 	t.Logf("close coord1")
 	err = coord1.Close()
@@ -439,9 +440,51 @@ func TestPGCoordinatorDual_Mainline(t *testing.T) {
 	require.NoError(t, err)
 	client22.waitForClose(ctx, t)
 
-	assertEventuallyNoAgents(ctx, t, store, agent1.id)
-	assertEventuallyNoClientsForAgent(ctx, t, store, agent1.id)
-	assertEventuallyNoClientsForAgent(ctx, t, store, agent2.id)
+	assertEventuallyLost(ctx, t, store, agent1.id)
+	assertEventuallyLost(ctx, t, store, client11.id)
+	assertEventuallyLost(ctx, t, store, client22.id)
+}
+
+// TestPGCoordinatorDual_Handoff tests that when a coordinator shuts down, the peers connected to other coordinators
+// see its peers as lost, rather than disconnected, until they reconnect to another coordinator.
+func TestPGCoordinatorDual_Handoff(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test only with postgres")
+	}
+	store, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coord1, err := tailnet.NewPGCoord(ctx, logger.Named("coord1"), ps, store)
+	require.NoError(t, err)
+	defer coord1.Close()
+	coord2, err := tailnet.NewPGCoord(ctx, logger.Named("coord2"), ps, store)
+	require.NoError(t, err)
+	defer coord2.Close()
+
+	p1 := agpltest.NewPeer(ctx, t, coord1, "p1")
+	defer p1.Close(ctx)
+	p2 := agpltest.NewPeer(ctx, t, coord2, "p2")
+	defer p2.Close(ctx)
+	p1.AddTunnel(p2.ID)
+	p1.UpdateDERP(1)
+	p2.UpdateDERP(2)
+	p1.AssertEventuallyHasDERP(p2.ID, 2)
+	p2.AssertEventuallyHasDERP(p1.ID, 1)
+
+	err = coord1.Close()
+	require.NoError(t, err)
+	p2.AssertEventuallyLost(p1.ID)
+	assertEventuallyLost(ctx, t, store, p1.ID)
+
+	// p1 reconnects to coord2 with a new node, which replaces the lost one
+	p1 = agpltest.NewPeer(ctx, t, coord2, "p1", p1.ID)
+	defer p1.Close(ctx)
+	p1.AddTunnel(p2.ID)
+	p1.UpdateDERP(11)
+	p2.AssertEventuallyHasDERP(p1.ID, 11)
+	p1.AssertEventuallyHasDERP(p2.ID, 2)
 }
 
 // TestPGCoordinator_MultiCoordinatorAgent tests when a single agent connects to multiple coordinators.
@@ -555,7 +598,7 @@ func TestPGCoordinator_Unhealthy(t *testing.T) {
 	mStore.EXPECT().DeleteTailnetPeer(gomock.Any(), gomock.Any()).
 		AnyTimes().Return(database.DeleteTailnetPeerRow{}, nil)
 	mStore.EXPECT().DeleteAllTailnetTunnels(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
-	mStore.EXPECT().DeleteCoordinator(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mStore.EXPECT().UpdateTailnetPeerStatusByCoordinator(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 	uut, err := tailnet.NewPGCoord(ctx, logger, ps, mStore)
 	require.NoError(t, err)
@@ -620,7 +663,7 @@ func TestPGCoordinator_Node_Empty(t *testing.T) {
 	mStore.EXPECT().CleanTailnetCoordinators(gomock.Any()).AnyTimes().Return(nil)
 	mStore.EXPECT().CleanTailnetLostPeers(gomock.Any()).AnyTimes().Return(nil)
 	mStore.EXPECT().CleanTailnetTunnels(gomock.Any()).AnyTimes().Return(nil)
-	mStore.EXPECT().DeleteCoordinator(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mStore.EXPECT().UpdateTailnetPeerStatusByCoordinator(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 
 	uut, err := tailnet.NewPGCoord(ctx, logger, ps, mStore)
 	require.NoError(t, err)
@@ -680,6 +723,21 @@ func TestPGCoordinator_Lost(t *testing.T) {
 	agpltest.LostTest(ctx, t, coordinator)
 }
 
+func TestPGCoordinator_RotateNodeKeys(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test only with postgres")
+	}
+	store, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordinator, err := tailnet.NewPGCoord(ctx, logger, ps, store)
+	require.NoError(t, err)
+	defer coordinator.Close()
+	agpltest.RotateNodeKeysTest(ctx, t, coordinator)
+}
+
 type testConn struct {
 	ws, serverWS net.Conn
 	nodeChan     chan []*agpl.Node
@@ -818,20 +876,6 @@ func assertNeverHasDERPs(ctx context.Context, t *testing.T, c *testConn, expecte
 	}
 }
 
-func assertEventuallyNoAgents(ctx context.Context, t *testing.T, store database.Store, agentID uuid.UUID) {
-	t.Helper()
-	assert.Eventually(t, func() bool {
-		agents, err := store.GetTailnetPeers(ctx, agentID)
-		if xerrors.Is(err, sql.ErrNoRows) {
-			return true
-		}
-		if err != nil {
-			t.Fatal(err)
-		}
-		return len(agents) == 0
-	}, testutil.WaitShort, testutil.IntervalFast)
-}
-
 func assertEventuallyLost(ctx context.Context, t *testing.T, store database.Store, agentID uuid.UUID) {
 	t.Helper()
 	assert.Eventually(t, func() bool {
@@ -29,8 +29,10 @@ import (
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/cliutil"
 	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/coderd/wsconncache"
@@ -76,6 +78,10 @@ type Options struct {
 	// DERPOnly determines whether this proxy only provides DERP and does not
 	// provide access to workspace apps/terminal.
 	DERPOnly bool
+	// DERPHealthCheckInterval is how often the nodes of the DERP map are
+	// probed. Failing nodes are excluded from the proxy's DERP map and reported
+	// to the primary. Zero disables the health checks.
+	DERPHealthCheckInterval time.Duration
 
 	ProxySessionToken string
 	// AllowAllCors will set all CORs headers to '*'.
@@ -124,12 +130,20 @@ type Server struct {
 	// DERP
 	derpMesh      *derpmesh.Mesh
 	latestDERPMap atomic.Pointer[tailcfg.DERPMap]
+	derpMonitor   *derphealth.Monitor
 
 	// Used for graceful shutdown. Required for the dialer.
 	ctx           context.Context
 	cancel        context.CancelFunc
 	derpCloseFunc func()
 	registerDone  <-chan struct{}
+	// registerRequest is the request the proxy registers with, before it's
+	// mutated by mutateRegister.
+	registerRequest wsproxysdk.RegisterWorkspaceProxyRequest
+
+	// Used to drain app sessions before shutting down. See Drain.
+	draining     atomic.Bool
+	openSessions atomic.Int64
 }
 
 // New creates a new workspace proxy server. This requires a primary coderd
@@ -207,23 +221,34 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		cancel:             cancel,
 	}
 
+	if opts.DERPHealthCheckInterval > 0 {
+		// The DERP map is nil until the first registration, in which case the
+		// probes are skipped.
+		s.derpMonitor = derphealth.NewMonitor(derphealth.MonitorOptions{
+			Logger:   opts.Logger.Named("net.derp-monitor"),
+			DERPMap:  s.latestDERPMap.Load,
+			Interval: opts.DERPHealthCheckInterval,
+		})
+	}
+
 	// Register the workspace proxy with the primary coderd instance and start a
 	// goroutine to periodically re-register.
 	replicaID := uuid.New()
 	osHostname := cliutil.Hostname()
+	s.registerRequest = wsproxysdk.RegisterWorkspaceProxyRequest{
+		AccessURL:           opts.AccessURL.String(),
+		WildcardHostname:    opts.AppHostname,
+		DerpEnabled:         opts.DERPEnabled,
+		DerpOnly:            opts.DERPOnly,
+		ReplicaID:           replicaID,
+		ReplicaHostname:     osHostname,
+		ReplicaError:        "",
+		ReplicaRelayAddress: opts.DERPServerRelayAddress,
+		Version:             buildinfo.Version(),
+	}
 	regResp, registerDone, err := client.RegisterWorkspaceProxyLoop(ctx, wsproxysdk.RegisterWorkspaceProxyLoopOpts{
-		Logger: opts.Logger,
-		Request: wsproxysdk.RegisterWorkspaceProxyRequest{
-			AccessURL:           opts.AccessURL.String(),
-			WildcardHostname:    opts.AppHostname,
-			DerpEnabled:         opts.DERPEnabled,
-			DerpOnly:            opts.DERPOnly,
-			ReplicaID:           replicaID,
-			ReplicaHostname:     osHostname,
-			ReplicaError:        "",
-			ReplicaRelayAddress: opts.DERPServerRelayAddress,
-			Version:             buildinfo.Version(),
-		},
+		Logger:     opts.Logger,
+		Request:    s.registerRequest,
 		MutateFn:   s.mutateRegister,
 		CallbackFn: s.handleRegister,
 		FailureFn:  s.handleRegisterFailure,
@@ -238,6 +263,19 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	}
 	derpServer.SetMeshKey(regResp.DERPMeshKey)
 
+	if opts.DERPEnabled {
+		regionName := opts.AccessURL.Hostname()
+		if regResp.DERPMap != nil {
+			if region, ok := regResp.DERPMap.Regions[int(regResp.DERPRegionID)]; ok {
+				regionName = region.RegionName
+			}
+		}
+		err = prometheusmetrics.DERPServer(opts.PrometheusRegistry, derpServer, int(regResp.DERPRegionID), regionName)
+		if err != nil {
+			return nil, xerrors.Errorf("register derp server metrics: %w", err)
+		}
+	}
+
 	secKey, err := workspaceapps.KeyFromString(regResp.AppSecurityKey)
 	if err != nil {
 		return nil, xerrors.Errorf("parse app security key: %w", err)
@@ -246,9 +284,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	agentProvider, err := coderd.NewServerTailnet(ctx,
 		s.Logger,
 		nil,
-		func() *tailcfg.DERPMap {
-			return s.latestDERPMap.Load()
-		},
+		s.derpMap,
 		regResp.DERPForceWebSockets,
 		s.DialCoordinator,
 		wsconncache.New(s.DialWorkspaceAgent, 0),
@@ -312,6 +348,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		httpmw.Logger(s.Logger),
 		prometheusMW,
 		corsMW,
+		s.trackAppSessions,
 
 		// HandleSubdomain is a middleware that handles all requests to the
 		// subdomain-based workspace apps.
@@ -386,7 +423,16 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	}
 
 	r.Get("/api/v2/buildinfo", s.buildInfo)
-	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("OK")) })
+	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		// Fail the health check while draining, so load balancers stop
+		// routing new sessions to this replica.
+		if s.draining.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("draining"))
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	})
 	// TODO: @emyrk should this be authenticated or debounced?
 	r.Get("/healthz-report", s.healthReport)
 	r.NotFound(func(rw http.ResponseWriter, r *http.Request) {
@@ -422,6 +468,9 @@ func (s *Server) Close() error {
 	case <-s.registerDone:
 	}
 	s.derpCloseFunc()
+	if s.derpMonitor != nil {
+		_ = s.derpMonitor.Close()
+	}
 	appServerErr := s.AppServer.Close()
 	if appServerErr != nil {
 		err = multierror.Append(err, appServerErr)
@@ -438,9 +487,90 @@ func (s *Server) DialWorkspaceAgent(id uuid.UUID) (*codersdk.WorkspaceAgentConn,
 	return s.SDKClient.DialWorkspaceAgent(s.ctx, id, nil)
 }
 
-func (*Server) mutateRegister(_ *wsproxysdk.RegisterWorkspaceProxyRequest) {
+func (s *Server) mutateRegister(req *wsproxysdk.RegisterWorkspaceProxyRequest) {
 	// TODO: we should probably ping replicas similarly to the replicasync
 	// package in the primary and update req.ReplicaError accordingly.
+	if s.derpMonitor != nil {
+		req.DERPFailingNodes = s.derpMonitor.FailingNodes()
+	}
+	req.Draining = s.draining.Load()
+	req.DrainingSessions = s.openSessions.Load()
+}
+
+// trackAppSessions counts the open app sessions, which are the WebSocket
+// connections to workspace apps and terminals. While draining, new sessions are
+// rejected so clients reconnect through another replica.
+func (s *Server) trackAppSessions(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// DERP clients reconnect on their own, so they don't delay shutdown.
+		if !httpapi.IsWebsocketUpgrade(r) || strings.HasPrefix(r.URL.Path, "/derp") {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		if s.draining.Load() {
+			httpapi.Write(r.Context(), rw, http.StatusServiceUnavailable, codersdk.Response{
+				Message: "This workspace proxy replica is shutting down.",
+				Detail:  "Reconnect to be routed to another replica.",
+			})
+			return
+		}
+
+		s.openSessions.Add(1)
+		defer s.openSessions.Add(-1)
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// Drain stops the proxy from accepting new app sessions and waits for the open
+// sessions to close, or for the context to be canceled. The progress is
+// reported to the primary, which shows the replica as draining in the health
+// of the proxy. Drain must be called before Close.
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	reported := int64(-1)
+	for {
+		open := s.openSessions.Load()
+		if open != reported {
+			s.reportDrain(ctx)
+			reported = open
+		}
+		if open == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("%d app sessions still open: %w", open, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportDrain re-registers the proxy right away with the drain progress,
+// instead of waiting for the next registration of the loop.
+func (s *Server) reportDrain(ctx context.Context) {
+	req := s.registerRequest
+	s.mutateRegister(&req)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	_, err := s.SDKClient.RegisterWorkspaceProxy(ctx, req)
+	if err != nil {
+		s.Logger.Warn(ctx, "failed to report drain progress to primary", slog.Error(err))
+	}
+}
+
+// derpMap returns the latest DERP map received from the primary, without the
+// nodes failing the DERP health checks.
+func (s *Server) derpMap() *tailcfg.DERPMap {
+	derpMap := s.latestDERPMap.Load()
+	if s.derpMonitor != nil {
+		return s.derpMonitor.Filter(derpMap)
+	}
+	return derpMap
 }
 
 func (s *Server) handleRegister(_ context.Context, res wsproxysdk.RegisterWorkspaceProxyResponse) error {
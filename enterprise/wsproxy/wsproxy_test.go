@@ -3,6 +3,7 @@ package wsproxy_test
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -498,3 +499,68 @@ func TestWorkspaceProxyWorkspaceApps(t *testing.T) {
 		}
 	})
 }
+
+func TestWorkspaceProxyDrain(t *testing.T) {
+	t.Parallel()
+
+	deploymentValues := coderdtest.DeploymentValues(t)
+	deploymentValues.Experiments = []string{
+		"*",
+	}
+
+	client, closer, api, _ := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: deploymentValues,
+			AppHostname:      "*.primary.test.coder.com",
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceProxy: 1,
+			},
+		},
+	})
+	t.Cleanup(func() {
+		_ = closer.Close()
+	})
+
+	proxy := coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+		Name: "best-proxy",
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// There are no open sessions, so draining completes right away.
+	err := proxy.Drain(ctx)
+	require.NoError(t, err)
+
+	get := func(path string, header http.Header) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxy.Options.AccessURL.String()+path, nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		return res.StatusCode
+	}
+	require.Equal(t, http.StatusServiceUnavailable, get("/healthz", nil))
+	// New sessions are rejected.
+	require.Equal(t, http.StatusServiceUnavailable, get(fmt.Sprintf("/api/v2/workspaceagents/%s/pty", uuid.New()), http.Header{
+		"Connection": {"Upgrade"},
+		"Upgrade":    {"websocket"},
+	}))
+
+	// The primary shows the replica as draining.
+	err = api.ProxyHealth.ForceUpdate(ctx)
+	require.NoError(t, err)
+	proxies, err := client.WorkspaceProxies(ctx)
+	require.NoError(t, err)
+	var warnings []string
+	for _, p := range proxies.Regions {
+		if p.Name == "best-proxy" {
+			warnings = p.Status.Report.Warnings
+		}
+	}
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "is shutting down, waiting for 0 open app sessions to close")
+}
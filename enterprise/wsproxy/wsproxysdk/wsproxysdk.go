@@ -17,6 +17,7 @@ import (
 	"tailscale.com/util/singleflight"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/healthcheck/derphealth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
@@ -202,6 +203,16 @@ type RegisterWorkspaceProxyRequest struct {
 	// replicas may use to connect internally for DERP meshing.
 	ReplicaRelayAddress string `json:"replica_relay_address"`
 
+	// DERPFailingNodes are the DERP nodes that are failing the health checks
+	// run by the proxy. They are surfaced in the deployment health report.
+	DERPFailingNodes []derphealth.FailingNode `json:"derp_failing_nodes"`
+
+	// Draining is set when the replica is shutting down and waiting for its
+	// open app sessions to close. DrainingSessions is the number of sessions
+	// that are still open.
+	Draining         bool  `json:"draining"`
+	DrainingSessions int64 `json:"draining_sessions"`
+
 	// Version is the Coder version of the proxy.
 	Version string `json:"version"`
 }
@@ -0,0 +1,141 @@
+package examples
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// MirrorIndex is the file of a mirror that lists its examples.
+	MirrorIndex = "index.json"
+
+	// maxMirrorArchiveSize limits the size of the archives downloaded from a
+	// mirror, it matches the maximum size of uploaded files.
+	maxMirrorArchiveSize = 10 * (10 << 20)
+)
+
+// MirrorExample is an example listed in the index of a mirror.
+type MirrorExample struct {
+	codersdk.TemplateExample
+	// SHA256 is the hex encoded checksum of the tar archive of the example.
+	SHA256 string `json:"sha256"`
+}
+
+// Mirror serves starter templates from an internal mirror instead of the
+// examples embedded in Coder, e.g. for air-gapped deployments. The mirror is a
+// plain HTTP file server with an index.json listing the examples and their
+// checksums, and a <id>.tar archive for every example.
+type Mirror struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// NewMirror returns a mirror for the given base URL.
+func NewMirror(u *url.URL, client *http.Client) *Mirror {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Mirror{url: u, client: client}
+}
+
+// List returns the examples in the index of the mirror.
+func (m *Mirror) List(ctx context.Context) ([]codersdk.TemplateExample, error) {
+	index, err := m.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]codersdk.TemplateExample, 0, len(index))
+	for _, example := range index {
+		list = append(list, example.TemplateExample)
+	}
+	return list, nil
+}
+
+// Archive returns the tar of an example, after verifying it against the
+// checksum in the index of the mirror.
+func (m *Mirror) Archive(ctx context.Context, exampleID string) ([]byte, error) {
+	index, err := m.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var selected MirrorExample
+	for _, example := range index {
+		if example.ID == exampleID {
+			selected = example
+			break
+		}
+	}
+	if selected.ID == "" {
+		return nil, xerrors.Errorf("example with id %q not found: %w", exampleID, ErrNotFound)
+	}
+
+	res, err := m.get(ctx, exampleID+".tar")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxMirrorArchiveSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("read archive: %w", err)
+	}
+	if len(data) > maxMirrorArchiveSize {
+		return nil, xerrors.Errorf("archive of example %q is larger than %d bytes", exampleID, maxMirrorArchiveSize)
+	}
+
+	if got := ArchiveChecksum(data); got != selected.SHA256 {
+		return nil, xerrors.Errorf("checksum mismatch for example %q: index has %q, archive has %q", exampleID, selected.SHA256, got)
+	}
+	return data, nil
+}
+
+func (m *Mirror) index(ctx context.Context) ([]MirrorExample, error) {
+	res, err := m.get(ctx, MirrorIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var index []MirrorExample
+	err = json.NewDecoder(res.Body).Decode(&index)
+	if err != nil {
+		return nil, xerrors.Errorf("decode %s: %w", MirrorIndex, err)
+	}
+	for _, example := range index {
+		if example.SHA256 == "" {
+			return nil, xerrors.Errorf("example %q in %s has no sha256 checksum", example.ID, MirrorIndex)
+		}
+	}
+	return index, nil
+}
+
+func (m *Mirror) get(ctx context.Context, name string) (*http.Response, error) {
+	u := m.url.JoinPath(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	res, err := m.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("get %s: %w", u, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		_ = res.Body.Close()
+		return nil, xerrors.Errorf("get %s: %s", u, res.Status)
+	}
+	return res, nil
+}
+
+// ArchiveChecksum returns the checksum of an archive in the format used by the
+// index of a mirror.
+func ArchiveChecksum(archive []byte) string {
+	sum := sha256.Sum256(archive)
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,60 @@
+package examples_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/examples"
+)
+
+func TestMirror(t *testing.T) {
+	t.Parallel()
+
+	archive, err := examples.Archive("docker")
+	require.NoError(t, err)
+	index := []examples.MirrorExample{{
+		TemplateExample: codersdk.TemplateExample{ID: "docker", Name: "Docker"},
+		SHA256:          examples.ArchiveChecksum(archive),
+	}, {
+		TemplateExample: codersdk.TemplateExample{ID: "tampered", Name: "Tampered"},
+		SHA256:          examples.ArchiveChecksum(archive),
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + examples.MirrorIndex:
+			_ = json.NewEncoder(rw).Encode(index)
+		case "/docker.tar":
+			_, _ = rw.Write(archive)
+		case "/tampered.tar":
+			_, _ = rw.Write(append(archive, 0))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	mirror := examples.NewMirror(u, nil)
+	ctx := context.Background()
+
+	list, err := mirror.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.TemplateExample{index[0].TemplateExample, index[1].TemplateExample}, list)
+
+	got, err := mirror.Archive(ctx, "docker")
+	require.NoError(t, err)
+	require.Equal(t, archive, got)
+
+	_, err = mirror.Archive(ctx, "tampered")
+	require.ErrorContains(t, err, "checksum mismatch")
+
+	_, err = mirror.Archive(ctx, "missing")
+	require.ErrorIs(t, err, examples.ErrNotFound)
+}
@@ -0,0 +1,202 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// kindResources maps the kinds that may be used in manifests to their API
+// resource. Only namespaced kinds are supported, so workspaces can't change
+// cluster-wide configuration.
+var kindResources = map[string]string{
+	"ConfigMap":             "configmaps",
+	"Deployment":            "deployments",
+	"Ingress":               "ingresses",
+	"NetworkPolicy":         "networkpolicies",
+	"PersistentVolumeClaim": "persistentvolumeclaims",
+	"Pod":                   "pods",
+	"Secret":                "secrets",
+	"Service":               "services",
+	"ServiceAccount":        "serviceaccounts",
+	"StatefulSet":           "statefulsets",
+}
+
+// objectRef identifies an object in the cluster.
+type objectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+}
+
+func (r objectRef) String() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// path returns the API path of the object.
+func (r objectRef) path() (string, error) {
+	resource, ok := kindResources[r.Kind]
+	if !ok {
+		return "", xerrors.Errorf("unsupported kind %q", r.Kind)
+	}
+	prefix := "/apis/" + r.APIVersion
+	if !strings.Contains(r.APIVersion, "/") {
+		// The core group has no name.
+		prefix = "/api/" + r.APIVersion
+	}
+	// Names and namespaces are DNS labels, so they don't need escaping.
+	return fmt.Sprintf("%s/namespaces/%s/%s/%s", prefix, r.Namespace, resource, r.Name), nil
+}
+
+// client is a minimal client of the Kubernetes API, covering what is needed
+// to apply and delete the objects of a manifest.
+type client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+func newClient(config *Config) (*client, error) {
+	if config.Host == nil {
+		return nil, xerrors.New("host is required")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.CAData) {
+			return nil, xerrors.New("no certificates found in CA data")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		}
+	}
+	return &client{
+		config: config,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   time.Minute,
+		},
+	}, nil
+}
+
+// apply creates or updates the object with a server-side apply, which takes
+// ownership of every field set in the manifest.
+func (c *client) apply(ctx context.Context, ref objectRef, object map[string]any) error {
+	path, err := ref.path()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(object)
+	if err != nil {
+		return xerrors.Errorf("marshal object: %w", err)
+	}
+	query := url.Values{"fieldManager": {"coder"}, "force": {"true"}}
+	// JSON is a subset of YAML, so the object doesn't need to be converted.
+	res, err := c.request(ctx, http.MethodPatch, path, query, "application/apply-patch+yaml", body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		return readError(res)
+	}
+	return nil
+}
+
+// delete deletes the object. An object that doesn't exist is not an error.
+func (c *client) delete(ctx context.Context, ref objectRef) error {
+	path, err := ref.path()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{
+		"kind":              "DeleteOptions",
+		"apiVersion":        "v1",
+		"propagationPolicy": "Background",
+	})
+	if err != nil {
+		return err
+	}
+	res, err := c.request(ctx, http.MethodDelete, path, nil, "application/json", body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	default:
+		return readError(res)
+	}
+}
+
+// exists returns whether the object exists.
+func (c *client) exists(ctx context.Context, ref objectRef) (bool, error) {
+	path, err := ref.path()
+	if err != nil {
+		return false, err
+	}
+	res, err := c.request(ctx, http.MethodGet, path, nil, "", nil)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, readError(res)
+	}
+}
+
+func (c *client) request(ctx context.Context, method, path string, query url.Values, contentType string, body []byte) (*http.Response, error) {
+	u := c.config.Host.JoinPath(path)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	token := c.config.BearerToken
+	if c.config.BearerTokenFile != "" {
+		data, err := os.ReadFile(c.config.BearerTokenFile)
+		if err != nil {
+			return nil, xerrors.Errorf("read token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// readError converts the Status returned by the API server to an error.
+func readError(res *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	var status struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &status) == nil && status.Message != "" {
+		return xerrors.Errorf("kubernetes api: %s (%d)", status.Message, res.StatusCode)
+	}
+	return xerrors.Errorf("kubernetes api: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Config describes how to connect to the Kubernetes API server.
+type Config struct {
+	// Host is the URL of the API server.
+	Host *url.URL
+	// BearerToken authenticates requests to the API server.
+	BearerToken string
+	// BearerTokenFile is read for every request when set, so rotated
+	// service account tokens are picked up. It takes precedence over
+	// BearerToken.
+	BearerTokenFile string
+	// CAData is the PEM encoded certificate authority of the API server. The
+	// system pool is used when it is empty.
+	CAData []byte
+	// Namespace is the namespace of objects in manifests that don't set one.
+	Namespace string
+}
+
+// InClusterConfig returns the config of the service account the process runs
+// as inside a pod. An error is returned when it is not running in a cluster.
+func InClusterConfig() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, xerrors.New("not running in a Kubernetes cluster")
+	}
+	tokenFile := serviceAccountDir + "/token"
+	if _, err := os.Stat(tokenFile); err != nil {
+		return nil, xerrors.Errorf("service account token: %w", err)
+	}
+	caData, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, xerrors.Errorf("read service account CA: %w", err)
+	}
+	namespace := "default"
+	data, err := os.ReadFile(serviceAccountDir + "/namespace")
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		namespace = strings.TrimSpace(string(data))
+	}
+	return &Config{
+		Host: &url.URL{
+			Scheme: "https",
+			Host:   net.JoinHostPort(host, port),
+		},
+		BearerTokenFile: tokenFile,
+		CAData:          caData,
+		Namespace:       namespace,
+	}, nil
+}
@@ -0,0 +1,277 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/provisioner"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// workspaceLabel is set on every object of a workspace.
+const workspaceLabel = "com.coder.workspace.id"
+
+// agentName is the name of the single agent of a workspace.
+const agentName = "main"
+
+// ManifestData is available to the manifest template.
+type ManifestData struct {
+	Workspace  ManifestWorkspace
+	Template   ManifestTemplate
+	Agent      ManifestAgent
+	AccessURL  string
+	Namespace  string
+	Variables  map[string]string
+	Parameters map[string]string
+}
+
+type ManifestWorkspace struct {
+	ID         string
+	Name       string
+	Owner      string
+	OwnerID    string
+	OwnerEmail string
+	// Transition is "start", "stop" or "destroy".
+	Transition string
+}
+
+type ManifestTemplate struct {
+	ID      string
+	Name    string
+	Version string
+}
+
+type ManifestAgent struct {
+	// Token authenticates the agent. It is set in the container
+	// environment as CODER_AGENT_TOKEN.
+	Token string
+	// InitScript downloads and starts the agent.
+	InitScript string
+}
+
+var manifestFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// manifestObject is an object of a rendered manifest.
+type manifestObject struct {
+	ref    objectRef
+	object map[string]any
+}
+
+// renderManifest renders the manifest of the project in dir and decodes its
+// objects.
+func renderManifest(dir string, project *Project, data ManifestData) ([]manifestObject, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, project.Manifest))
+	if err != nil {
+		return nil, xerrors.Errorf("read manifest: %w", err)
+	}
+	tmpl, err := template.New(project.Manifest).Funcs(manifestFuncs).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return nil, xerrors.Errorf("parse manifest: %w", err)
+	}
+	var rendered bytes.Buffer
+	err = tmpl.Execute(&rendered, data)
+	if err != nil {
+		return nil, xerrors.Errorf("render manifest: %w", err)
+	}
+	return decodeObjects(&rendered, data.Namespace, data.Workspace.ID)
+}
+
+// decodeObjects decodes the documents of a manifest. Every object is labeled
+// with the workspace, and put in the namespace unless it sets one.
+func decodeObjects(r io.Reader, namespace, workspaceID string) ([]manifestObject, error) {
+	var objects []manifestObject
+	seen := map[objectRef]struct{}{}
+	dec := yaml.NewDecoder(r)
+	for {
+		var object map[string]any
+		err := dec.Decode(&object)
+		if xerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("decode manifest: %w", err)
+		}
+		if object == nil {
+			// Empty documents are left by conditionals in the template.
+			continue
+		}
+
+		apiVersion, _ := object["apiVersion"].(string)
+		kind, _ := object["kind"].(string)
+		metadata, _ := object["metadata"].(map[string]any)
+		if apiVersion == "" || kind == "" || metadata == nil {
+			return nil, xerrors.New("objects must have an apiVersion, kind and metadata")
+		}
+		name, _ := metadata["name"].(string)
+		if name == "" {
+			return nil, xerrors.Errorf("%s objects must have a name", kind)
+		}
+		if _, ok := kindResources[kind]; !ok {
+			return nil, xerrors.Errorf("unsupported kind %q", kind)
+		}
+		objectNamespace, _ := metadata["namespace"].(string)
+		if objectNamespace == "" {
+			objectNamespace = namespace
+			metadata["namespace"] = namespace
+		}
+		labels, _ := metadata["labels"].(map[string]any)
+		if labels == nil {
+			labels = map[string]any{}
+		}
+		labels[workspaceLabel] = workspaceID
+		metadata["labels"] = labels
+
+		ref := objectRef{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  objectNamespace,
+			Name:       name,
+		}
+		if _, ok := seen[ref]; ok {
+			return nil, xerrors.Errorf("duplicate object: %s", ref)
+		}
+		seen[ref] = struct{}{}
+		objects = append(objects, manifestObject{ref: ref, object: object})
+	}
+	return objects, nil
+}
+
+// isWorkload returns whether objects of the kind run containers.
+func isWorkload(kind string) bool {
+	switch kind {
+	case "Pod", "Deployment", "StatefulSet":
+		return true
+	}
+	return false
+}
+
+// isPersistent returns whether objects of the kind are kept while a
+// workspace is stopped.
+func isPersistent(kind string) bool {
+	return kind == "PersistentVolumeClaim"
+}
+
+// convertResources produces resources consumable by Coder. The agent of the
+// project is attached to the first workload.
+func convertResources(refs []objectRef, agent *ProjectAgent, token string) ([]*proto.Resource, error) {
+	resources := make([]*proto.Resource, 0, len(refs))
+	agentAttached := false
+	for _, ref := range refs {
+		resource := &proto.Resource{
+			Name: ref.Name,
+			Type: "kubernetes_" + strings.ToLower(ref.Kind),
+			Metadata: []*proto.Resource_Metadata{{
+				Key:   "namespace",
+				Value: ref.Namespace,
+			}},
+		}
+		if agent != nil && !agentAttached && isWorkload(ref.Kind) {
+			converted, err := convertAgent(agent, token)
+			if err != nil {
+				return nil, err
+			}
+			resource.Agents = append(resource.Agents, converted)
+			agentAttached = true
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func convertAgent(agent *ProjectAgent, token string) (*proto.Agent, error) {
+	converted := &proto.Agent{
+		Name:               agentName,
+		Env:                agent.Env,
+		OperatingSystem:    agent.OperatingSystem,
+		Architecture:       agent.Architecture,
+		Directory:          agent.Directory,
+		TroubleshootingUrl: agent.TroubleshootingURL,
+		MotdFile:           agent.MOTDFile,
+		DisplayApps:        provisionersdk.DefaultDisplayApps(),
+		Auth: &proto.Agent_Token{
+			Token: token,
+		},
+	}
+	if converted.OperatingSystem == "" {
+		converted.OperatingSystem = "linux"
+	}
+	if converted.Architecture == "" {
+		converted.Architecture = "amd64"
+	}
+
+	appSlugs := map[string]struct{}{}
+	for _, app := range agent.Apps {
+		if !provisioner.AppSlugRegex.MatchString(app.Slug) {
+			return nil, xerrors.Errorf("invalid app slug %q", app.Slug)
+		}
+		if _, exists := appSlugs[app.Slug]; exists {
+			return nil, xerrors.Errorf("duplicate app slug, they must be unique per template: %q", app.Slug)
+		}
+		appSlugs[app.Slug] = struct{}{}
+
+		sharingLevel := proto.AppSharingLevel_OWNER
+		switch strings.ToLower(app.Share) {
+		case "authenticated":
+			sharingLevel = proto.AppSharingLevel_AUTHENTICATED
+		case "public":
+			sharingLevel = proto.AppSharingLevel_PUBLIC
+		}
+		displayName := app.DisplayName
+		if displayName == "" {
+			displayName = app.Slug
+		}
+		converted.Apps = append(converted.Apps, &proto.App{
+			Slug:         app.Slug,
+			DisplayName:  displayName,
+			Icon:         app.Icon,
+			Url:          app.URL,
+			Command:      app.Command,
+			Subdomain:    app.Subdomain,
+			SharingLevel: sharingLevel,
+		})
+	}
+	return converted, nil
+}
+
+// agentInitScript returns the script that downloads and starts the agent,
+// as the Coder Terraform provider does.
+func agentInitScript(accessURL, operatingSystem, architecture string) string {
+	if operatingSystem == "" {
+		operatingSystem = "linux"
+	}
+	if architecture == "" {
+		architecture = "amd64"
+	}
+	script := provisionersdk.AgentScriptEnv()["CODER_AGENT_SCRIPT_"+operatingSystem+"_"+architecture]
+	if !strings.HasSuffix(accessURL, "/") {
+		accessURL += "/"
+	}
+	script = strings.ReplaceAll(script, "${ACCESS_URL}", accessURL)
+	script = strings.ReplaceAll(script, "${AUTH_TYPE}", "token")
+	return script
+}
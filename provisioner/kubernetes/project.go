@@ -0,0 +1,188 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// defaultManifest is the manifest file of a project that doesn't set one.
+const defaultManifest = "manifest.yaml"
+
+// Project is the contents of the project file, which describes the template
+// around its manifest.
+type Project struct {
+	// Manifest is the path of the manifest, relative to the project file.
+	Manifest string `yaml:"manifest"`
+	// Namespace of objects that don't set one. Defaults to the namespace of
+	// the provisioner daemon.
+	Namespace  string             `yaml:"namespace"`
+	Agent      *ProjectAgent      `yaml:"agent"`
+	Variables  []ProjectVariable  `yaml:"variables"`
+	Parameters []ProjectParameter `yaml:"parameters"`
+}
+
+// ProjectAgent describes the agent that runs in the first Pod, Deployment or
+// StatefulSet of the manifest.
+type ProjectAgent struct {
+	OperatingSystem    string            `yaml:"os"`
+	Architecture       string            `yaml:"arch"`
+	Directory          string            `yaml:"dir"`
+	Env                map[string]string `yaml:"env"`
+	TroubleshootingURL string            `yaml:"troubleshooting_url"`
+	MOTDFile           string            `yaml:"motd_file"`
+	Apps               []ProjectApp      `yaml:"apps"`
+}
+
+type ProjectApp struct {
+	Slug        string `yaml:"slug"`
+	DisplayName string `yaml:"display_name"`
+	Icon        string `yaml:"icon"`
+	URL         string `yaml:"url"`
+	Command     string `yaml:"command"`
+	Share       string `yaml:"share"`
+	Subdomain   bool   `yaml:"subdomain"`
+}
+
+// ProjectVariable is a template-wide variable, set when the template is
+// pushed.
+type ProjectVariable struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Type        string  `yaml:"type"`
+	Default     *string `yaml:"default"`
+	Sensitive   bool    `yaml:"sensitive"`
+}
+
+// ProjectParameter is a rich parameter, set for each workspace.
+type ProjectParameter struct {
+	Name        string                   `yaml:"name"`
+	DisplayName string                   `yaml:"display_name"`
+	Description string                   `yaml:"description"`
+	Type        string                   `yaml:"type"`
+	Default     *string                  `yaml:"default"`
+	Mutable     bool                     `yaml:"mutable"`
+	Icon        string                   `yaml:"icon"`
+	Options     []ProjectParameterOption `yaml:"options"`
+}
+
+type ProjectParameterOption struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Value       string `yaml:"value"`
+	Icon        string `yaml:"icon"`
+}
+
+// LoadProject reads the project file in dir.
+func LoadProject(dir string) (*Project, error) {
+	data, err := os.ReadFile(filepath.Join(dir, provisionersdk.KubernetesProjectFile))
+	if err != nil {
+		return nil, xerrors.Errorf("read project file: %w", err)
+	}
+	var project Project
+	err = yaml.Unmarshal(data, &project)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal project file: %w", err)
+	}
+	if project.Manifest == "" {
+		project.Manifest = defaultManifest
+	}
+	if !filepath.IsLocal(project.Manifest) {
+		return nil, xerrors.Errorf("manifest %q must be in the template directory", project.Manifest)
+	}
+	for _, variable := range project.Variables {
+		if variable.Name == "" {
+			return nil, xerrors.New("variables must have a name")
+		}
+	}
+	for _, parameter := range project.Parameters {
+		if parameter.Name == "" {
+			return nil, xerrors.New("parameters must have a name")
+		}
+	}
+	return &project, nil
+}
+
+// TemplateVariables converts the variables of the project to template-wide
+// variables, processed by Coder.
+func (p *Project) TemplateVariables() []*proto.TemplateVariable {
+	variables := make([]*proto.TemplateVariable, 0, len(p.Variables))
+	for _, variable := range p.Variables {
+		variableType := variable.Type
+		if variableType == "" {
+			variableType = "string"
+		}
+		var defaultValue string
+		if variable.Default != nil {
+			defaultValue = *variable.Default
+		}
+		variables = append(variables, &proto.TemplateVariable{
+			Name:         variable.Name,
+			Description:  variable.Description,
+			Type:         variableType,
+			DefaultValue: defaultValue,
+			Required:     variable.Default == nil,
+			Sensitive:    variable.Sensitive,
+		})
+	}
+	return variables
+}
+
+// RichParameters converts the parameters of the project to rich parameters.
+func (p *Project) RichParameters() []*proto.RichParameter {
+	parameters := make([]*proto.RichParameter, 0, len(p.Parameters))
+	for i, parameter := range p.Parameters {
+		parameterType := parameter.Type
+		if parameterType == "" {
+			parameterType = "string"
+		}
+		var defaultValue string
+		if parameter.Default != nil {
+			defaultValue = *parameter.Default
+		}
+		options := make([]*proto.RichParameterOption, 0, len(parameter.Options))
+		for _, option := range parameter.Options {
+			options = append(options, &proto.RichParameterOption{
+				Name:        option.Name,
+				Description: option.Description,
+				Value:       option.Value,
+				Icon:        option.Icon,
+			})
+		}
+		parameters = append(parameters, &proto.RichParameter{
+			Name:         parameter.Name,
+			DisplayName:  parameter.DisplayName,
+			Description:  parameter.Description,
+			Type:         parameterType,
+			Mutable:      parameter.Mutable,
+			DefaultValue: defaultValue,
+			Icon:         parameter.Icon,
+			Options:      options,
+			Required:     parameter.Default == nil,
+			// Parameters are displayed in the order they are declared.
+			Order: int32(i),
+		})
+	}
+	return parameters
+}
+
+// Parse extracts template variables from the project file.
+func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-chan struct{}) *proto.ParseComplete {
+	ctx := sess.Context()
+	_, span := s.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	project, err := LoadProject(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ParseErrorf("load project: %s", err)
+	}
+	return &proto.ParseComplete{
+		TemplateVariables: project.TemplateVariables(),
+	}
+}
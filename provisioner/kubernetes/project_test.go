@@ -0,0 +1,86 @@
+package kubernetes_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/kubernetes"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestLoadProject(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		writeProject(t, dir, `
+variables:
+  - name: image
+    description: Container image.
+    default: codercom/enterprise-base:ubuntu
+  - name: registry_password
+    sensitive: true
+parameters:
+  - name: cpu
+    display_name: CPU
+    type: number
+    default: "2"
+    mutable: true
+    options:
+      - name: 2 cores
+        value: "2"
+      - name: 4 cores
+        value: "4"
+`)
+
+		project, err := kubernetes.LoadProject(dir)
+		require.NoError(t, err)
+		require.Equal(t, "manifest.yaml", project.Manifest)
+		require.Equal(t, []*proto.TemplateVariable{{
+			Name:         "image",
+			Description:  "Container image.",
+			Type:         "string",
+			DefaultValue: "codercom/enterprise-base:ubuntu",
+		}, {
+			Name:      "registry_password",
+			Type:      "string",
+			Required:  true,
+			Sensitive: true,
+		}}, project.TemplateVariables())
+
+		parameters := project.RichParameters()
+		require.Len(t, parameters, 1)
+		require.Equal(t, "cpu", parameters[0].Name)
+		require.Equal(t, "number", parameters[0].Type)
+		require.Equal(t, "2", parameters[0].DefaultValue)
+		require.True(t, parameters[0].Mutable)
+		require.False(t, parameters[0].Required)
+		require.Len(t, parameters[0].Options, 2)
+	})
+
+	t.Run("ManifestOutsideDirectory", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		writeProject(t, dir, "manifest: ../manifest.yaml\n")
+
+		_, err := kubernetes.LoadProject(dir)
+		require.ErrorContains(t, err, "must be in the template directory")
+	})
+
+	t.Run("NoProject", func(t *testing.T) {
+		t.Parallel()
+		_, err := kubernetes.LoadProject(t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+func writeProject(t *testing.T, dir, content string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, provisionersdk.KubernetesProjectFile), []byte(content), 0o600)
+	require.NoError(t, err)
+}
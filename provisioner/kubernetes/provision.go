@@ -0,0 +1,301 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+type logSink interface {
+	ProvisionLog(l proto.LogLevel, o string)
+}
+
+// state is stored by Coder for every workspace build, and lists the objects
+// that exist in the cluster.
+type state struct {
+	Objects []objectRef `json:"objects"`
+}
+
+// plan is written to the working directory by Plan, and applied by Apply.
+type plan struct {
+	Token   string          `json:"token"`
+	Objects []plannedObject `json:"objects"`
+}
+
+type plannedObject struct {
+	Ref    objectRef      `json:"ref"`
+	Object map[string]any `json:"object"`
+}
+
+func getPlanFilePath(workdir string) string {
+	return filepath.Join(workdir, "kubernetes.plan.json")
+}
+
+func decodeState(data []byte) (*state, error) {
+	var s state
+	if len(data) == 0 {
+		return &s, nil
+	}
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, xerrors.Errorf("decode state: %w", err)
+	}
+	return &s, nil
+}
+
+func (s *server) Plan(
+	sess *provisionersdk.Session, request *proto.PlanRequest, _ <-chan struct{},
+) *proto.PlanComplete {
+	_, span := s.startTrace(sess.Context(), tracing.FuncName())
+	defer span.End()
+
+	transition := request.Metadata.GetWorkspaceTransition()
+	if transition == proto.WorkspaceTransition_DESTROY && len(sess.Config.State) == 0 {
+		sess.ProvisionLog(proto.LogLevel_INFO, "The workspace has no objects, there is nothing to do")
+		return &proto.PlanComplete{}
+	}
+
+	project, err := LoadProject(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.PlanErrorf("load project: %s", err)
+	}
+	namespace := project.Namespace
+	if namespace == "" {
+		namespace = s.namespace
+	}
+
+	variables := map[string]string{}
+	for _, variable := range project.Variables {
+		if variable.Default != nil {
+			variables[variable.Name] = *variable.Default
+		}
+	}
+	for _, variable := range request.VariableValues {
+		variables[variable.Name] = variable.Value
+	}
+	parameters := map[string]string{}
+	for _, parameter := range request.RichParameterValues {
+		parameters[parameter.Name] = parameter.Value
+	}
+
+	metadata := request.Metadata
+	token := uuid.NewString()
+	var agentOS, agentArch string
+	if project.Agent != nil {
+		agentOS, agentArch = project.Agent.OperatingSystem, project.Agent.Architecture
+	}
+	objects, err := renderManifest(sess.WorkDirectory, project, ManifestData{
+		Workspace: ManifestWorkspace{
+			ID:         metadata.GetWorkspaceId(),
+			Name:       metadata.GetWorkspaceName(),
+			Owner:      metadata.GetWorkspaceOwner(),
+			OwnerID:    metadata.GetWorkspaceOwnerId(),
+			OwnerEmail: metadata.GetWorkspaceOwnerEmail(),
+			Transition: strings.ToLower(transition.String()),
+		},
+		Template: ManifestTemplate{
+			ID:      metadata.GetTemplateId(),
+			Name:    metadata.GetTemplateName(),
+			Version: metadata.GetTemplateVersion(),
+		},
+		Agent: ManifestAgent{
+			Token:      token,
+			InitScript: agentInitScript(metadata.GetCoderUrl(), agentOS, agentArch),
+		},
+		AccessURL:  metadata.GetCoderUrl(),
+		Namespace:  namespace,
+		Variables:  variables,
+		Parameters: parameters,
+	})
+	if err != nil {
+		return provisionersdk.PlanErrorf("%s", err)
+	}
+
+	// Workspaces keep their volumes while they are stopped, and have no
+	// objects once they are destroyed.
+	planned := plan{Token: token}
+	for _, object := range objects {
+		switch transition {
+		case proto.WorkspaceTransition_START:
+		case proto.WorkspaceTransition_STOP:
+			if !isPersistent(object.ref.Kind) {
+				continue
+			}
+		default:
+			continue
+		}
+		planned.Objects = append(planned.Objects, plannedObject{Ref: object.ref, Object: object.object})
+	}
+
+	data, err := json.Marshal(planned)
+	if err != nil {
+		return provisionersdk.PlanErrorf("marshal plan: %s", err)
+	}
+	err = os.WriteFile(getPlanFilePath(sess.WorkDirectory), data, 0o600)
+	if err != nil {
+		return provisionersdk.PlanErrorf("write plan: %s", err)
+	}
+
+	resources, err := convertResources(plannedRefs(planned), project.Agent, token)
+	if err != nil {
+		return provisionersdk.PlanErrorf("convert resources: %s", err)
+	}
+	return &proto.PlanComplete{
+		Resources:  resources,
+		Parameters: project.RichParameters(),
+	}
+}
+
+func (s *server) Apply(
+	sess *provisionersdk.Session, request *proto.ApplyRequest, canceledOrComplete <-chan struct{},
+) *proto.ApplyComplete {
+	ctx, span := s.startTrace(sess.Context(), tracing.FuncName())
+	defer span.End()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-canceledOrComplete:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY && len(sess.Config.State) == 0 {
+		sess.ProvisionLog(proto.LogLevel_INFO, "The workspace has no objects, there is nothing to do")
+		return &proto.ApplyComplete{}
+	}
+
+	// Earlier in the session, Plan() will have written the plan file.
+	project, err := LoadProject(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("load project: %s", err)
+	}
+	data, err := os.ReadFile(getPlanFilePath(sess.WorkDirectory))
+	if err != nil {
+		return provisionersdk.ApplyErrorf("read plan: %s", err)
+	}
+	var planned plan
+	err = json.Unmarshal(data, &planned)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("decode plan: %s", err)
+	}
+	prior, err := decodeState(sess.Config.State)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("%s", err)
+	}
+
+	current, err := s.apply(ctx, sess, prior, planned)
+	stateData, marshalErr := json.Marshal(current)
+	if marshalErr != nil {
+		return provisionersdk.ApplyErrorf("marshal state: %s", marshalErr)
+	}
+	if err != nil {
+		// Objects may have been changed before the failure, so the state
+		// is returned with the error.
+		return &proto.ApplyComplete{
+			State: stateData,
+			Error: err.Error(),
+		}
+	}
+
+	resources, err := convertResources(current.Objects, project.Agent, planned.Token)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("convert resources: %s", err)
+	}
+	return &proto.ApplyComplete{
+		State:      stateData,
+		Resources:  resources,
+		Parameters: project.RichParameters(),
+	}
+}
+
+// apply applies the planned objects and deletes the objects of the prior
+// state that are not planned. The returned state lists every object that may
+// exist, even when an error is returned.
+func (s *server) apply(ctx context.Context, logr logSink, prior *state, planned plan) (*state, error) {
+	current := &state{}
+	applied := map[objectRef]struct{}{}
+	for _, object := range planned.Objects {
+		current.Objects = append(current.Objects, object.Ref)
+		applied[object.Ref] = struct{}{}
+	}
+	// Until they are deleted, prior objects still exist.
+	var stale []objectRef
+	for _, ref := range prior.Objects {
+		if _, ok := applied[ref]; !ok {
+			stale = append(stale, ref)
+		}
+	}
+	withStale := &state{Objects: append(append([]objectRef{}, current.Objects...), stale...)}
+
+	// Objects are deleted before others are applied, so a stopped workload
+	// is gone before it is started again.
+	for i := len(stale) - 1; i >= 0; i-- {
+		ref := stale[i]
+		logr.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("Deleting %s", ref))
+		err := s.client.delete(ctx, ref)
+		if err != nil {
+			return withStale, xerrors.Errorf("delete %s: %w", ref, err)
+		}
+	}
+	err := s.waitDeleted(ctx, stale)
+	if err != nil {
+		return withStale, err
+	}
+
+	for _, object := range planned.Objects {
+		logr.ProvisionLog(proto.LogLevel_INFO, fmt.Sprintf("Applying %s", object.Ref))
+		err := s.client.apply(ctx, object.Ref, object.Object)
+		if err != nil {
+			// The prior objects were deleted, but the object that failed
+			// may have been created.
+			return current, xerrors.Errorf("apply %s: %w", object.Ref, err)
+		}
+	}
+	return current, nil
+}
+
+// waitDeleted waits for the objects to be removed from the cluster.
+func (s *server) waitDeleted(ctx context.Context, refs []objectRef) error {
+	ctx, cancel := context.WithTimeout(ctx, s.deleteTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for _, ref := range refs {
+		for {
+			exists, err := s.client.exists(ctx, ref)
+			if err != nil {
+				return xerrors.Errorf("get %s: %w", ref, err)
+			}
+			if !exists {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return xerrors.Errorf("wait for %s to be deleted: %w", ref, ctx.Err())
+			case <-ticker.C:
+			}
+		}
+	}
+	return nil
+}
+
+func plannedRefs(planned plan) []objectRef {
+	refs := make([]objectRef, 0, len(planned.Objects))
+	for _, object := range planned.Objects {
+		refs = append(refs, object.Ref)
+	}
+	return refs
+}
@@ -0,0 +1,222 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+const testManifest = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: home-{{ .Workspace.ID }}
+spec:
+  accessModes: ["ReadWriteOnce"]
+---
+{{ if eq .Workspace.Transition "start" }}
+apiVersion: v1
+kind: Pod
+metadata:
+  name: ws-{{ .Workspace.ID }}
+  namespace: other
+spec:
+  containers:
+    - name: dev
+      image: {{ .Variables.image | quote }}
+      env:
+        - name: CODER_AGENT_TOKEN
+          value: {{ .Agent.Token | quote }}
+        - name: CPU
+          value: {{ .Parameters.cpu | quote }}
+{{ end }}
+`
+
+func TestRenderManifest(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestProject(t, dir)
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+
+	objects, err := renderManifest(dir, project, ManifestData{
+		Workspace:  ManifestWorkspace{ID: "abc", Transition: "start"},
+		Agent:      ManifestAgent{Token: "token"},
+		Namespace:  "coder",
+		Variables:  map[string]string{"image": "ubuntu"},
+		Parameters: map[string]string{"cpu": "2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, objectRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "coder", Name: "home-abc"}, objects[0].ref)
+	require.Equal(t, objectRef{APIVersion: "v1", Kind: "Pod", Namespace: "other", Name: "ws-abc"}, objects[1].ref)
+	labels := objects[0].object["metadata"].(map[string]any)["labels"].(map[string]any)
+	require.Equal(t, "abc", labels[workspaceLabel])
+
+	// Stopped workspaces render without the pod.
+	objects, err = renderManifest(dir, project, ManifestData{
+		Workspace: ManifestWorkspace{ID: "abc", Transition: "stop"},
+		Namespace: "coder",
+	})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+
+	t.Run("UnsupportedKind", func(t *testing.T) {
+		t.Parallel()
+		_, err := decodeObjects(strings.NewReader("apiVersion: rbac.authorization.k8s.io/v1\nkind: ClusterRole\nmetadata:\n  name: admin\n"), "coder", "abc")
+		require.ErrorContains(t, err, "unsupported kind")
+	})
+}
+
+func TestConvertResources(t *testing.T) {
+	t.Parallel()
+
+	resources, err := convertResources([]objectRef{
+		{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "coder", Name: "home"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "coder", Name: "ws"},
+	}, &ProjectAgent{
+		Directory: "/home/coder",
+		Apps:      []ProjectApp{{Slug: "code-server", URL: "http://localhost:13337"}},
+	}, "token")
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	require.Equal(t, "kubernetes_persistentvolumeclaim", resources[0].Type)
+	require.Empty(t, resources[0].Agents)
+	require.Equal(t, "kubernetes_deployment", resources[1].Type)
+	require.Len(t, resources[1].Agents, 1)
+	agent := resources[1].Agents[0]
+	require.Equal(t, "token", agent.GetToken())
+	require.Equal(t, "linux", agent.OperatingSystem)
+	require.Equal(t, provisionersdk.DefaultDisplayApps(), agent.DisplayApps)
+	require.Equal(t, "code-server", agent.Apps[0].DisplayName)
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Context(t, testutil.WaitShort)
+	api := newFakeAPI()
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	host, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client, err := newClient(&Config{Host: host, BearerToken: "secret"})
+	require.NoError(t, err)
+	s := &server{client: client, namespace: "coder", deleteTimeout: time.Second}
+
+	pvc := objectRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "coder", Name: "home"}
+	pod := objectRef{APIVersion: "v1", Kind: "Pod", Namespace: "coder", Name: "ws"}
+	object := func(ref objectRef) plannedObject {
+		return plannedObject{Ref: ref, Object: map[string]any{
+			"apiVersion": ref.APIVersion,
+			"kind":       ref.Kind,
+			"metadata":   map[string]any{"name": ref.Name, "namespace": ref.Namespace},
+		}}
+	}
+
+	// Start.
+	current, err := s.apply(ctx, discardLogs{}, &state{}, plan{Objects: []plannedObject{object(pvc), object(pod)}})
+	require.NoError(t, err)
+	require.Equal(t, []objectRef{pvc, pod}, current.Objects)
+	require.ElementsMatch(t, []string{
+		"/api/v1/namespaces/coder/persistentvolumeclaims/home",
+		"/api/v1/namespaces/coder/pods/ws",
+	}, api.paths())
+
+	// Stop keeps the volume.
+	current, err = s.apply(ctx, discardLogs{}, current, plan{Objects: []plannedObject{object(pvc)}})
+	require.NoError(t, err)
+	require.Equal(t, []objectRef{pvc}, current.Objects)
+	require.Equal(t, []string{"/api/v1/namespaces/coder/persistentvolumeclaims/home"}, api.paths())
+
+	// Destroy.
+	current, err = s.apply(ctx, discardLogs{}, current, plan{})
+	require.NoError(t, err)
+	require.Empty(t, current.Objects)
+	require.Empty(t, api.paths())
+}
+
+func writeTestProject(t *testing.T, dir string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(dir, provisionersdk.KubernetesProjectFile), []byte("agent:\n  dir: /home/coder\n"), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(testManifest), 0o600)
+	require.NoError(t, err)
+}
+
+type discardLogs struct{}
+
+func (discardLogs) ProvisionLog(proto.LogLevel, string) {}
+
+// fakeAPI serves server-side apply, get and delete of objects.
+type fakeAPI struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{objects: map[string][]byte{}}
+}
+
+func (f *fakeAPI) paths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	paths := make([]string, 0, len(f.objects))
+	for path := range f.objects {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") != "application/apply-patch+yaml" || r.URL.Query().Get("fieldManager") != "coder" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = data
+		_, _ = w.Write(data)
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","message":"not found"}`))
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		if _, ok := f.objects[r.URL.Path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
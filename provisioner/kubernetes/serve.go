@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.14.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+type ServeOptions struct {
+	*provisionersdk.ServeOptions
+
+	// Config is used to connect to the Kubernetes API server.
+	Config *Config
+	Tracer trace.Tracer
+	// DeleteTimeout defines how long to wait for objects to be deleted
+	// when a workspace is stopped or destroyed. Defaults to 2 minutes.
+	DeleteTimeout time.Duration
+}
+
+// Serve starts a dRPC server on the provided transport speaking Kubernetes
+// provisioner.
+func Serve(ctx context.Context, options *ServeOptions) error {
+	if options.Config == nil {
+		return xerrors.New("kubernetes config is required")
+	}
+	client, err := newClient(options.Config)
+	if err != nil {
+		return xerrors.Errorf("create kubernetes client: %w", err)
+	}
+	if options.Tracer == nil {
+		options.Tracer = trace.NewNoopTracerProvider().Tracer("noop")
+	}
+	if options.DeleteTimeout == 0 {
+		options.DeleteTimeout = 2 * time.Minute
+	}
+	return provisionersdk.Serve(ctx, &server{
+		client:        client,
+		namespace:     options.Config.Namespace,
+		logger:        options.Logger,
+		tracer:        options.Tracer,
+		deleteTimeout: options.DeleteTimeout,
+	}, options.ServeOptions)
+}
+
+type server struct {
+	client        *client
+	namespace     string
+	logger        slog.Logger
+	tracer        trace.Tracer
+	deleteTimeout time.Duration
+}
+
+func (s *server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, append(opts, trace.WithAttributes(
+		semconv.ServiceNameKey.String("coderd.provisionerd.kubernetes"),
+	))...)
+}
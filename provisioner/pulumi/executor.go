@@ -0,0 +1,384 @@
+package pulumi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// stackName is the name of the single stack each workspace is deployed
+// with. Every workspace has its own state, so the name does not need to be
+// unique.
+const stackName = "coder"
+
+// resourcesOutput is the stack output that describes the resources and
+// agents of a workspace.
+const resourcesOutput = "coder_resources"
+
+// Project level configuration in Pulumi.yaml, which template variables are
+// read from, was added in 3.44.0.
+var minPulumiVersion = version.Must(version.NewVersion("3.44.0"))
+
+type executor struct {
+	logger     slog.Logger
+	server     *server
+	mut        *sync.Mutex
+	binaryPath string
+	// cachePath and workdir must not be used by multiple processes at once.
+	cachePath string
+	workdir   string
+}
+
+func (e *executor) basicEnv() []string {
+	env := safeEnviron()
+	env = append(env,
+		// State is kept in the working directory and stored by Coder, in
+		// the same way as the Terraform state.
+		"PULUMI_BACKEND_URL=file://"+filepath.ToSlash(e.workdir),
+		// Secrets in the state are only encrypted at rest by Coder, so the
+		// passphrase is intentionally empty.
+		"PULUMI_CONFIG_PASSPHRASE=",
+		"PULUMI_SKIP_UPDATE_CHECK=true",
+		"PULUMI_SKIP_CONFIRMATIONS=true",
+	)
+	if e.cachePath != "" {
+		env = append(env, "PULUMI_HOME="+e.cachePath)
+	}
+	return env
+}
+
+// execWriteOutput must only be called while the lock is held.
+func (e *executor) execWriteOutput(ctx, killCtx context.Context, args, env []string, stdOutWriter, stdErrWriter io.WriteCloser) (err error) {
+	ctx, span := e.server.startTrace(ctx, fmt.Sprintf("exec - pulumi %s", args[0]))
+	defer span.End()
+	span.SetAttributes(attribute.StringSlice("args", args))
+
+	defer func() {
+		closeErr := stdOutWriter.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+		closeErr = stdErrWriter.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if isCanarySet(env) {
+		return xerrors.New("environment variables not sanitized, this is a bug within Coder")
+	}
+
+	// #nosec
+	cmd := exec.CommandContext(killCtx, e.binaryPath, args...)
+	cmd.Dir = e.workdir
+	cmd.Env = env
+
+	// We want logs to be written in the correct order, so we wrap all logging
+	// in a sync.Mutex.
+	mut := &sync.Mutex{}
+	cmd.Stdout = syncWriter{mut, stdOutWriter}
+	cmd.Stderr = syncWriter{mut, stdErrWriter}
+
+	e.server.logger.Debug(ctx, "executing pulumi command",
+		slog.F("binary_path", e.binaryPath),
+		slog.F("args", args),
+	)
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	interruptCommandOnCancel(ctx, killCtx, e.logger, cmd)
+
+	err = cmd.Wait()
+	e.logger.Debug(ctx, "command done", slog.F("args", args), slog.Error(err))
+	return err
+}
+
+// execOutput must only be called while the lock is held.
+func (e *executor) execOutput(ctx, killCtx context.Context, args, env []string) ([]byte, error) {
+	ctx, span := e.server.startTrace(ctx, fmt.Sprintf("exec - pulumi %s", args[0]))
+	defer span.End()
+	span.SetAttributes(attribute.StringSlice("args", args))
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// #nosec
+	cmd := exec.CommandContext(killCtx, e.binaryPath, args...)
+	cmd.Dir = e.workdir
+	cmd.Env = env
+	out := &bytes.Buffer{}
+	stdErr := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = stdErr
+
+	e.server.logger.Debug(ctx, "executing pulumi command with output",
+		slog.F("binary_path", e.binaryPath),
+		slog.F("args", args),
+	)
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	interruptCommandOnCancel(ctx, killCtx, e.logger, cmd)
+
+	err = cmd.Wait()
+	if err != nil {
+		return nil, xerrors.Errorf("%s: %w", strings.TrimSpace(stdErr.String()), err)
+	}
+	return out.Bytes(), nil
+}
+
+func (e *executor) checkMinVersion(ctx context.Context) error {
+	v, err := versionFromBinaryPath(ctx, e.binaryPath)
+	if err != nil {
+		return err
+	}
+	if !v.GreaterThanOrEqual(minPulumiVersion) {
+		return xerrors.Errorf(
+			"pulumi version %q is too old. required >= %q",
+			v.String(),
+			minPulumiVersion.String())
+	}
+	return nil
+}
+
+func versionFromBinaryPath(ctx context.Context, binaryPath string) (*version.Version, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// #nosec
+	cmd := exec.CommandContext(ctx, binaryPath, "version")
+	cmd.Env = append(safeEnviron(), "PULUMI_SKIP_UPDATE_CHECK=true")
+	out, err := cmd.Output()
+	if err != nil {
+		select {
+		// `exec` library throws a `signal: killed`` error instead of the canceled context.
+		// Since we know the cause for the killed signal, we are throwing the relevant error here.
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+			return nil, err
+		}
+	}
+	return version.NewVersion(strings.TrimSpace(string(out)))
+}
+
+// selectStack creates the stack in the working directory and imports the
+// state of the workspace, if there is any. It must only be called while the
+// lock is held.
+func (e *executor) selectStack(ctx, killCtx context.Context, state []byte) error {
+	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	_, err := e.execOutput(ctx, killCtx, []string{"stack", "select", stackName, "--create", "--non-interactive"}, e.basicEnv())
+	if err != nil {
+		return xerrors.Errorf("pulumi stack select: %w", err)
+	}
+	if len(state) == 0 {
+		return nil
+	}
+
+	statefilePath := getStateFilePath(e.workdir)
+	err = os.WriteFile(statefilePath, state, 0o600)
+	if err != nil {
+		return xerrors.Errorf("write statefile %q: %w", statefilePath, err)
+	}
+	_, err = e.execOutput(ctx, killCtx, []string{"stack", "import", "--stack", stackName, "--file", statefilePath}, e.basicEnv())
+	if err != nil {
+		return xerrors.Errorf("pulumi stack import: %w", err)
+	}
+	return nil
+}
+
+// setConfig stores the template variables in the stack configuration. It
+// must only be called while the lock is held.
+func (e *executor) setConfig(ctx, killCtx context.Context, vars []*proto.VariableValue) error {
+	for _, variable := range vars {
+		args := []string{"config", "set", "--stack", stackName}
+		if variable.Sensitive {
+			args = append(args, "--secret")
+		}
+		// The separator stops values starting with a dash from being read
+		// as flags.
+		args = append(args, "--", variable.Name, variable.Value)
+		_, err := e.execOutput(ctx, killCtx, args, e.basicEnv())
+		if err != nil {
+			return xerrors.Errorf("pulumi config set %q: %w", variable.Name, err)
+		}
+	}
+	return nil
+}
+
+// preview must only be called while the lock is held.
+func (e *executor) preview(ctx, killCtx context.Context, env []string, logr logSink) error {
+	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	args := []string{"preview", "--stack", stackName, "--non-interactive", "--color", "never", "--diff"}
+	return e.execLogged(ctx, killCtx, args, env, logr)
+}
+
+func (e *executor) apply(
+	ctx, killCtx context.Context,
+	env []string,
+	logr logSink,
+	destroy bool,
+) (*proto.ApplyComplete, error) {
+	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	command := "up"
+	if destroy {
+		command = "destroy"
+	}
+	args := []string{command, "--stack", stackName, "--yes", "--skip-preview", "--non-interactive", "--color", "never"}
+	err := e.execLogged(ctx, killCtx, args, env, logr)
+	if err != nil {
+		return nil, xerrors.Errorf("pulumi %s: %w", command, err)
+	}
+
+	var resources []*proto.Resource
+	if !destroy {
+		resources, err = e.outputResources(ctx, killCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	state, err := e.exportState(ctx, killCtx)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ApplyComplete{
+		Resources: resources,
+		State:     state,
+	}, nil
+}
+
+// execLogged must only be called while the lock is held.
+func (e *executor) execLogged(ctx, killCtx context.Context, args, env []string, logr logSink) error {
+	outWriter, doneOut := logWriter(logr, proto.LogLevel_INFO)
+	errWriter, doneErr := logWriter(logr, proto.LogLevel_ERROR)
+	defer func() {
+		_ = outWriter.Close()
+		_ = errWriter.Close()
+		<-doneOut
+		<-doneErr
+	}()
+	return e.execWriteOutput(ctx, killCtx, args, env, outWriter, errWriter)
+}
+
+// outputResources must only be called while the lock is held.
+func (e *executor) outputResources(ctx, killCtx context.Context) ([]*proto.Resource, error) {
+	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	out, err := e.execOutput(ctx, killCtx, []string{"stack", "output", "--stack", stackName, "--json", "--show-secrets"}, e.basicEnv())
+	if err != nil {
+		return nil, xerrors.Errorf("pulumi stack output: %w", err)
+	}
+	var outputs map[string]json.RawMessage
+	err = json.Unmarshal(out, &outputs)
+	if err != nil {
+		return nil, xerrors.Errorf("decode stack outputs: %w", err)
+	}
+	return ConvertResources(outputs[resourcesOutput])
+}
+
+// exportState returns the state of the stack. It must only be called while
+// the lock is held.
+func (e *executor) exportState(ctx, killCtx context.Context) ([]byte, error) {
+	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	out, err := e.execOutput(ctx, killCtx, []string{"stack", "export", "--stack", stackName}, e.basicEnv())
+	if err != nil {
+		return nil, xerrors.Errorf("pulumi stack export: %w", err)
+	}
+	return out, nil
+}
+
+func getStateFilePath(workdir string) string {
+	return filepath.Join(workdir, "pulumi.state.json")
+}
+
+func interruptCommandOnCancel(ctx, killCtx context.Context, logger slog.Logger, cmd *exec.Cmd) {
+	go func() {
+		select {
+		case <-ctx.Done():
+			var err error
+			switch runtime.GOOS {
+			case "windows":
+				// Interrupts aren't supported by Windows.
+				err = cmd.Process.Kill()
+			default:
+				err = cmd.Process.Signal(os.Interrupt)
+			}
+			logger.Debug(ctx, "interrupted command", slog.F("args", cmd.Args), slog.Error(err))
+
+		case <-killCtx.Done():
+			logger.Debug(ctx, "kill context ended", slog.F("args", cmd.Args))
+		}
+	}()
+}
+
+type logSink interface {
+	ProvisionLog(l proto.LogLevel, o string)
+}
+
+// logWriter creates a WriteCloser that will log each line of text at the given level. The WriteCloser must be closed
+// by the caller to end logging, after which the returned channel will be closed to indicate that logging of the written
+// data has finished. Failure to close the WriteCloser will leak a goroutine.
+func logWriter(sink logSink, level proto.LogLevel) (io.WriteCloser, <-chan any) {
+	r, w := io.Pipe()
+	done := make(chan any)
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			sink.ProvisionLog(level, scanner.Text())
+		}
+	}()
+	return w, done
+}
+
+// syncWriter wraps an io.Writer in a sync.Mutex.
+type syncWriter struct {
+	mut *sync.Mutex
+	w   io.Writer
+}
+
+// Write implements io.Writer.
+func (sw syncWriter) Write(p []byte) (n int, err error) {
+	sw.mut.Lock()
+	defer sw.mut.Unlock()
+	return sw.w.Write(p)
+}
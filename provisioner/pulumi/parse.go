@@ -0,0 +1,136 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// projectConfigType is the schema of a key in the "config" section of
+// Pulumi.yaml.
+type projectConfigType struct {
+	Type        string      `yaml:"type"`
+	Description string      `yaml:"description"`
+	Default     interface{} `yaml:"default"`
+	Secret      bool        `yaml:"secret"`
+}
+
+// Parse extracts template variables from the project configuration.
+func (s *server) Parse(sess *provisionersdk.Session, _ *proto.ParseRequest, _ <-chan struct{}) *proto.ParseComplete {
+	ctx := sess.Context()
+	_, span := s.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	templateVariables, err := ParseProject(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ParseErrorf("parse project: %s", err)
+	}
+	return &proto.ParseComplete{
+		TemplateVariables: templateVariables,
+	}
+}
+
+// ParseProject reads the "config" section of the Pulumi project in dir and
+// converts each key to a template-wide variable, processed by Coder. Keys
+// are returned in the order they are declared.
+func ParseProject(dir string) ([]*proto.TemplateVariable, error) {
+	var (
+		data []byte
+		err  error
+	)
+	for _, name := range []string{"Pulumi.yaml", "Pulumi.yml"} {
+		data, err = os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("read project file: %w", err)
+	}
+
+	var project struct {
+		Config yaml.Node `yaml:"config"`
+	}
+	err = yaml.Unmarshal(data, &project)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal project file: %w", err)
+	}
+	if project.Config.Kind == 0 {
+		return nil, nil
+	}
+	if project.Config.Kind != yaml.MappingNode {
+		return nil, xerrors.New(`"config" must be a map`)
+	}
+
+	var templateVariables []*proto.TemplateVariable
+	for i := 0; i+1 < len(project.Config.Content); i += 2 {
+		name := project.Config.Content[i].Value
+		// Namespaced keys, such as "aws:region", configure providers
+		// rather than the program.
+		if strings.Contains(name, ":") {
+			continue
+		}
+		variable, err := convertConfigKey(name, project.Config.Content[i+1])
+		if err != nil {
+			return nil, xerrors.Errorf("convert config key %q: %w", name, err)
+		}
+		templateVariables = append(templateVariables, variable)
+	}
+	return templateVariables, nil
+}
+
+func convertConfigKey(name string, node *yaml.Node) (*proto.TemplateVariable, error) {
+	var config projectConfigType
+	if node.Kind == yaml.MappingNode {
+		err := node.Decode(&config)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// A plain value is shorthand for the default value.
+		err := node.Decode(&config.Default)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var defaultData string
+	if config.Default != nil {
+		var valid bool
+		defaultData, valid = config.Default.(string)
+		if !valid {
+			defaultDataRaw, err := json.Marshal(config.Default)
+			if err != nil {
+				return nil, xerrors.Errorf("marshal default: %w", err)
+			}
+			defaultData = string(defaultDataRaw)
+		}
+	}
+
+	// Template variables only have the scalar Terraform types, so arrays
+	// are entered as JSON strings.
+	variableType := "string"
+	switch config.Type {
+	case "integer":
+		variableType = "number"
+	case "boolean":
+		variableType = "bool"
+	}
+
+	return &proto.TemplateVariable{
+		Name:         name,
+		Description:  config.Description,
+		Type:         variableType,
+		DefaultValue: defaultData,
+		Required:     config.Default == nil,
+		Sensitive:    config.Secret,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package pulumi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/pulumi"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestParseProject(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Config", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte(`name: workspace
+runtime: go
+config:
+  region:
+    type: string
+    description: Region to deploy to.
+    default: eu-west-1
+  instanceCount:
+    type: integer
+    default: 2
+  password:
+    type: string
+    secret: true
+  zone: a
+  aws:region: us-east-1
+`), 0o600)
+		require.NoError(t, err)
+
+		variables, err := pulumi.ParseProject(dir)
+		require.NoError(t, err)
+		require.Equal(t, []*proto.TemplateVariable{{
+			Name:         "region",
+			Description:  "Region to deploy to.",
+			Type:         "string",
+			DefaultValue: "eu-west-1",
+		}, {
+			Name:         "instanceCount",
+			Type:         "number",
+			DefaultValue: "2",
+		}, {
+			Name:      "password",
+			Type:      "string",
+			Required:  true,
+			Sensitive: true,
+		}, {
+			Name:         "zone",
+			Type:         "string",
+			DefaultValue: "a",
+		}}, variables)
+	})
+
+	t.Run("NoConfig", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "Pulumi.yml"), []byte("name: workspace\nruntime: go\n"), 0o600)
+		require.NoError(t, err)
+
+		variables, err := pulumi.ParseProject(dir)
+		require.NoError(t, err)
+		require.Empty(t, variables)
+	})
+
+	t.Run("NoProject", func(t *testing.T) {
+		t.Parallel()
+		_, err := pulumi.ParseProject(t.TempDir())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,219 @@
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/terraform-provider-coder/provider"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func (s *server) setupContexts(parent context.Context, canceledOrComplete <-chan struct{}) (
+	ctx context.Context, cancel func(), killCtx context.Context, kill func(),
+) {
+	// Create a context for graceful cancellation bound to the session
+	// context. This ensures that we will perform graceful cancellation
+	// even on connection loss.
+	ctx, cancel = context.WithCancel(parent)
+
+	// Create a separate context for forceful cancellation not tied to
+	// the stream so that we can control when to terminate the process.
+	killCtx, kill = context.WithCancel(context.Background())
+
+	// Ensure processes are eventually cleaned up on graceful
+	// cancellation or disconnect.
+	go func() {
+		<-ctx.Done()
+		t := time.NewTimer(s.exitTimeout)
+		defer t.Stop()
+		select {
+		case <-t.C:
+			kill()
+		case <-killCtx.Done():
+		}
+	}()
+
+	// Process cancel
+	go func() {
+		<-canceledOrComplete
+		cancel()
+	}()
+	return ctx, cancel, killCtx, kill
+}
+
+func (s *server) Plan(
+	sess *provisionersdk.Session, request *proto.PlanRequest, canceledOrComplete <-chan struct{},
+) *proto.PlanComplete {
+	ctx, span := s.startTrace(sess.Context(), tracing.FuncName())
+	defer span.End()
+	ctx, cancel, killCtx, kill := s.setupContexts(ctx, canceledOrComplete)
+	defer cancel()
+	defer kill()
+
+	e := s.executor(sess.WorkDirectory)
+	if err := e.checkMinVersion(ctx); err != nil {
+		return provisionersdk.PlanErrorf(err.Error())
+	}
+
+	// If we're destroying, exit early if there's no state. This mirrors the
+	// Terraform provisioner, so a workspace that never provisioned can
+	// always be deleted.
+	destroy := request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY
+	if destroy && len(sess.Config.State) == 0 {
+		sess.ProvisionLog(proto.LogLevel_INFO, "The pulumi state does not exist, there is nothing to do")
+		return &proto.PlanComplete{}
+	}
+
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	err := e.selectStack(ctx, killCtx, sess.Config.State)
+	if err != nil {
+		return provisionersdk.PlanErrorf("select stack: %s", err)
+	}
+	err = e.setConfig(ctx, killCtx, request.VariableValues)
+	if err != nil {
+		return provisionersdk.PlanErrorf("set config: %s", err)
+	}
+	if destroy {
+		// "pulumi preview" cannot preview a destroy, and every resource in
+		// the stack is deleted anyway.
+		sess.ProvisionLog(proto.LogLevel_INFO, "All resources in the stack will be destroyed")
+		return &proto.PlanComplete{}
+	}
+
+	// Unlike a Terraform plan, the Pulumi program runs again on apply, so
+	// the parameter values must be kept for it.
+	err = writeRichParameters(sess.WorkDirectory, request.RichParameterValues)
+	if err != nil {
+		return provisionersdk.PlanErrorf("write parameters: %s", err)
+	}
+	env := provisionEnv(e.basicEnv(), request.Metadata, request.RichParameterValues)
+	err = e.preview(ctx, killCtx, env, sess)
+	if err != nil {
+		return provisionersdk.PlanErrorf("pulumi preview: %s", err)
+	}
+	// Resources are only known once the stack outputs have been computed,
+	// so they are reported by Apply.
+	return &proto.PlanComplete{}
+}
+
+func (s *server) Apply(
+	sess *provisionersdk.Session, request *proto.ApplyRequest, canceledOrComplete <-chan struct{},
+) *proto.ApplyComplete {
+	ctx, span := s.startTrace(sess.Context(), tracing.FuncName())
+	defer span.End()
+	ctx, cancel, killCtx, kill := s.setupContexts(ctx, canceledOrComplete)
+	defer cancel()
+	defer kill()
+
+	e := s.executor(sess.WorkDirectory)
+	if err := e.checkMinVersion(ctx); err != nil {
+		return provisionersdk.ApplyErrorf(err.Error())
+	}
+
+	destroy := request.Metadata.GetWorkspaceTransition() == proto.WorkspaceTransition_DESTROY
+	if destroy && len(sess.Config.State) == 0 {
+		sess.ProvisionLog(proto.LogLevel_INFO, "The pulumi state does not exist, there is nothing to do")
+		return &proto.ApplyComplete{}
+	}
+
+	// Earlier in the session, Plan() will have selected the stack and set
+	// its configuration.
+	richParams, err := readRichParameters(sess.WorkDirectory)
+	if err != nil {
+		return provisionersdk.ApplyErrorf("read parameters: %s", err)
+	}
+	env := provisionEnv(e.basicEnv(), request.Metadata, richParams)
+	resp, err := e.apply(ctx, killCtx, env, sess, destroy)
+	if err != nil {
+		// Pulumi can fail an update and still need to store its state. In
+		// this case, we return Complete with an explicit error message.
+		e.mut.Lock()
+		stateData, stateErr := e.exportState(ctx, killCtx)
+		e.mut.Unlock()
+		if stateErr != nil {
+			// Fall back to the state that was imported, so the workspace
+			// is never left without one.
+			stateData, _ = os.ReadFile(getStateFilePath(sess.WorkDirectory))
+		}
+		return &proto.ApplyComplete{
+			State: stateData,
+			Error: err.Error(),
+		}
+	}
+	return resp
+}
+
+func getRichParametersFilePath(workdir string) string {
+	return filepath.Join(workdir, "coder.parameters.json")
+}
+
+func writeRichParameters(workdir string, params []*proto.RichParameterValue) error {
+	values := make(map[string]string, len(params))
+	for _, param := range params {
+		values[param.Name] = param.Value
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getRichParametersFilePath(workdir), data, 0o600)
+}
+
+func readRichParameters(workdir string) ([]*proto.RichParameterValue, error) {
+	data, err := os.ReadFile(getRichParametersFilePath(workdir))
+	if xerrors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, err
+	}
+	params := make([]*proto.RichParameterValue, 0, len(values))
+	for name, value := range values {
+		params = append(params, &proto.RichParameterValue{Name: name, Value: value})
+	}
+	return params, nil
+}
+
+func provisionEnv(env []string, metadata *proto.Metadata, richParams []*proto.RichParameterValue) []string {
+	env = append(env,
+		"CODER_AGENT_URL="+metadata.GetCoderUrl(),
+		"CODER_WORKSPACE_TRANSITION="+strings.ToLower(metadata.GetWorkspaceTransition().String()),
+		"CODER_WORKSPACE_NAME="+metadata.GetWorkspaceName(),
+		"CODER_WORKSPACE_OWNER="+metadata.GetWorkspaceOwner(),
+		"CODER_WORKSPACE_OWNER_EMAIL="+metadata.GetWorkspaceOwnerEmail(),
+		"CODER_WORKSPACE_OWNER_NAME="+metadata.GetWorkspaceOwnerName(),
+		"CODER_WORKSPACE_OWNER_OIDC_ACCESS_TOKEN="+metadata.GetWorkspaceOwnerOidcAccessToken(),
+		"CODER_WORKSPACE_ID="+metadata.GetWorkspaceId(),
+		"CODER_WORKSPACE_OWNER_ID="+metadata.GetWorkspaceOwnerId(),
+		"CODER_WORKSPACE_OWNER_SESSION_TOKEN="+metadata.GetWorkspaceOwnerSessionToken(),
+		"CODER_WORKSPACE_TEMPLATE_ID="+metadata.GetTemplateId(),
+		"CODER_WORKSPACE_TEMPLATE_NAME="+metadata.GetTemplateName(),
+		"CODER_WORKSPACE_TEMPLATE_VERSION="+metadata.GetTemplateVersion(),
+		"CODER_WORKSPACE_SNAPSHOT_ID="+metadata.GetWorkspaceSnapshotId(),
+		"CODER_WORKSPACE_RESTORE_SNAPSHOT_ID="+metadata.GetWorkspaceRestoreSnapshotId(),
+	)
+	for key, value := range provisionersdk.AgentScriptEnv() {
+		env = append(env, key+"="+value)
+	}
+	for _, param := range richParams {
+		env = append(env, provider.ParameterEnvironmentVariable(param.Name)+"="+param.Value)
+	}
+	return env
+}
@@ -0,0 +1,264 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisioner"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// outputResource is an element of the "coder_resources" stack output. It
+// carries the attributes of a resource and the "coder_agent", "coder_app",
+// "coder_script" and "coder_metadata" resources the Terraform provisioner
+// associates with it.
+type outputResource struct {
+	Name         string               `json:"name"`
+	Type         string               `json:"type"`
+	Icon         string               `json:"icon"`
+	Hide         bool                 `json:"hide"`
+	InstanceType string               `json:"instance_type"`
+	DailyCost    int32                `json:"daily_cost"`
+	Snapshot     bool                 `json:"snapshot"`
+	Metadata     []outputMetadataItem `json:"metadata"`
+	Agents       []outputAgent        `json:"agents"`
+}
+
+type outputMetadataItem struct {
+	Key       string  `json:"key"`
+	Value     *string `json:"value"`
+	Sensitive bool    `json:"sensitive"`
+}
+
+type outputAgent struct {
+	Name                     string                 `json:"name"`
+	OperatingSystem          string                 `json:"operating_system"`
+	Architecture             string                 `json:"architecture"`
+	Directory                string                 `json:"directory"`
+	Token                    string                 `json:"token"`
+	InstanceID               string                 `json:"instance_id"`
+	Env                      map[string]string      `json:"env"`
+	ConnectionTimeoutSeconds int32                  `json:"connection_timeout_seconds"`
+	TroubleshootingURL       string                 `json:"troubleshooting_url"`
+	MOTDFile                 string                 `json:"motd_file"`
+	Metadata                 []outputAgentMetadata  `json:"metadata"`
+	DisplayApps              *outputAgentDisplayApp `json:"display_apps"`
+	Apps                     []outputApp            `json:"apps"`
+	Scripts                  []outputScript         `json:"scripts"`
+}
+
+type outputAgentMetadata struct {
+	Key         string `json:"key"`
+	DisplayName string `json:"display_name"`
+	Script      string `json:"script"`
+	Interval    int64  `json:"interval"`
+	Timeout     int64  `json:"timeout"`
+}
+
+type outputAgentDisplayApp struct {
+	VSCode               bool `json:"vscode"`
+	VSCodeInsiders       bool `json:"vscode_insiders"`
+	WebTerminal          bool `json:"web_terminal"`
+	SSHHelper            bool `json:"ssh_helper"`
+	PortForwardingHelper bool `json:"port_forwarding_helper"`
+}
+
+type outputApp struct {
+	Slug        string                `json:"slug"`
+	DisplayName string                `json:"display_name"`
+	Icon        string                `json:"icon"`
+	URL         string                `json:"url"`
+	External    bool                  `json:"external"`
+	Command     string                `json:"command"`
+	Share       string                `json:"share"`
+	Subdomain   bool                  `json:"subdomain"`
+	Healthcheck *outputAppHealthcheck `json:"healthcheck"`
+}
+
+type outputAppHealthcheck struct {
+	URL       string `json:"url"`
+	Interval  int32  `json:"interval"`
+	Threshold int32  `json:"threshold"`
+}
+
+type outputScript struct {
+	DisplayName      string `json:"display_name"`
+	Icon             string `json:"icon"`
+	Script           string `json:"script"`
+	Cron             string `json:"cron"`
+	LogPath          string `json:"log_path"`
+	StartBlocksLogin bool   `json:"start_blocks_login"`
+	RunOnStart       bool   `json:"run_on_start"`
+	RunOnStop        bool   `json:"run_on_stop"`
+	TimeoutSeconds   int32  `json:"timeout"`
+	Phase            string `json:"phase"`
+}
+
+// ConvertResources consumes the "coder_resources" stack output to produce
+// resources consumable by Coder. A stack without the output has no
+// resources.
+func ConvertResources(raw json.RawMessage) ([]*proto.Resource, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var outputs []outputResource
+	err := json.Unmarshal(raw, &outputs)
+	if err != nil {
+		return nil, xerrors.Errorf("decode %q output: %w", resourcesOutput, err)
+	}
+
+	agentNames := map[string]struct{}{}
+	appSlugs := map[string]struct{}{}
+	resources := make([]*proto.Resource, 0, len(outputs))
+	for _, output := range outputs {
+		if output.Name == "" || output.Type == "" {
+			return nil, xerrors.New("resources must have a name and a type")
+		}
+		resource := &proto.Resource{
+			Name:         output.Name,
+			Type:         output.Type,
+			Icon:         output.Icon,
+			Hide:         output.Hide,
+			InstanceType: output.InstanceType,
+			DailyCost:    output.DailyCost,
+			Snapshot:     output.Snapshot,
+		}
+		for _, item := range output.Metadata {
+			metadata := &proto.Resource_Metadata{
+				Key:       item.Key,
+				Sensitive: item.Sensitive,
+				IsNull:    item.Value == nil,
+			}
+			if item.Value != nil {
+				metadata.Value = *item.Value
+			}
+			resource.Metadata = append(resource.Metadata, metadata)
+		}
+
+		for _, attrs := range output.Agents {
+			if attrs.Name == "" {
+				return nil, xerrors.Errorf("agents of resource %q must have a name", output.Name)
+			}
+			if _, exists := agentNames[attrs.Name]; exists {
+				return nil, xerrors.Errorf("duplicate agent name: %s", attrs.Name)
+			}
+			agentNames[attrs.Name] = struct{}{}
+
+			agent, err := convertAgent(attrs, appSlugs)
+			if err != nil {
+				return nil, xerrors.Errorf("agent %q: %w", attrs.Name, err)
+			}
+			resource.Agents = append(resource.Agents, agent)
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func convertAgent(attrs outputAgent, appSlugs map[string]struct{}) (*proto.Agent, error) {
+	displayApps := provisionersdk.DefaultDisplayApps()
+	if attrs.DisplayApps != nil {
+		displayApps = &proto.DisplayApps{
+			Vscode:               attrs.DisplayApps.VSCode,
+			VscodeInsiders:       attrs.DisplayApps.VSCodeInsiders,
+			WebTerminal:          attrs.DisplayApps.WebTerminal,
+			PortForwardingHelper: attrs.DisplayApps.PortForwardingHelper,
+			SshHelper:            attrs.DisplayApps.SSHHelper,
+		}
+	}
+
+	agent := &proto.Agent{
+		Name:                     attrs.Name,
+		Env:                      attrs.Env,
+		OperatingSystem:          attrs.OperatingSystem,
+		Architecture:             attrs.Architecture,
+		Directory:                attrs.Directory,
+		ConnectionTimeoutSeconds: attrs.ConnectionTimeoutSeconds,
+		TroubleshootingUrl:       attrs.TroubleshootingURL,
+		MotdFile:                 attrs.MOTDFile,
+		DisplayApps:              displayApps,
+	}
+	if attrs.Token != "" {
+		agent.Auth = &proto.Agent_Token{
+			Token: attrs.Token,
+		}
+	} else {
+		// If token authentication isn't used, assume instance auth. It's
+		// our only other authentication type!
+		agent.Auth = &proto.Agent_InstanceId{
+			InstanceId: attrs.InstanceID,
+		}
+	}
+
+	for _, item := range attrs.Metadata {
+		agent.Metadata = append(agent.Metadata, &proto.Agent_Metadata{
+			Key:         item.Key,
+			DisplayName: item.DisplayName,
+			Script:      item.Script,
+			Interval:    item.Interval,
+			Timeout:     item.Timeout,
+		})
+	}
+
+	for _, app := range attrs.Apps {
+		if !provisioner.AppSlugRegex.MatchString(app.Slug) {
+			return nil, xerrors.Errorf("invalid app slug %q", app.Slug)
+		}
+		if _, exists := appSlugs[app.Slug]; exists {
+			return nil, xerrors.Errorf("duplicate app slug, they must be unique per template: %q", app.Slug)
+		}
+		appSlugs[app.Slug] = struct{}{}
+
+		var healthcheck *proto.Healthcheck
+		if app.Healthcheck != nil {
+			healthcheck = &proto.Healthcheck{
+				Url:       app.Healthcheck.URL,
+				Interval:  app.Healthcheck.Interval,
+				Threshold: app.Healthcheck.Threshold,
+			}
+		}
+
+		sharingLevel := proto.AppSharingLevel_OWNER
+		switch strings.ToLower(app.Share) {
+		case "authenticated":
+			sharingLevel = proto.AppSharingLevel_AUTHENTICATED
+		case "public":
+			sharingLevel = proto.AppSharingLevel_PUBLIC
+		}
+
+		displayName := app.DisplayName
+		if displayName == "" {
+			displayName = app.Slug
+		}
+		agent.Apps = append(agent.Apps, &proto.App{
+			Slug:         app.Slug,
+			DisplayName:  displayName,
+			Command:      app.Command,
+			External:     app.External,
+			Url:          app.URL,
+			Icon:         app.Icon,
+			Subdomain:    app.Subdomain,
+			SharingLevel: sharingLevel,
+			Healthcheck:  healthcheck,
+		})
+	}
+
+	for _, script := range attrs.Scripts {
+		agent.Scripts = append(agent.Scripts, &proto.Script{
+			DisplayName:      script.DisplayName,
+			Icon:             script.Icon,
+			Script:           script.Script,
+			Cron:             script.Cron,
+			LogPath:          script.LogPath,
+			StartBlocksLogin: script.StartBlocksLogin,
+			RunOnStart:       script.RunOnStart,
+			RunOnStop:        script.RunOnStop,
+			TimeoutSeconds:   script.TimeoutSeconds,
+			Phase:            script.Phase,
+		})
+	}
+	return agent, nil
+}
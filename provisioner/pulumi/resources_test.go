@@ -0,0 +1,90 @@
+package pulumi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/pulumi"
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+func TestConvertResources(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		resources, err := pulumi.ConvertResources(nil)
+		require.NoError(t, err)
+		require.Empty(t, resources)
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		t.Parallel()
+		resources, err := pulumi.ConvertResources([]byte(`[{
+			"name": "dev",
+			"type": "docker:index/container:Container",
+			"daily_cost": 2,
+			"metadata": [{"key": "image", "value": "ubuntu"}, {"key": "empty", "value": null}],
+			"agents": [{
+				"name": "main",
+				"operating_system": "linux",
+				"architecture": "amd64",
+				"token": "token",
+				"apps": [{"slug": "code-server", "url": "http://localhost:8080", "share": "authenticated"}],
+				"scripts": [{"display_name": "Startup", "script": "echo hi", "run_on_start": true}]
+			}]
+		}]`))
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		resource := resources[0]
+		require.Equal(t, "dev", resource.Name)
+		require.EqualValues(t, 2, resource.DailyCost)
+		require.Equal(t, []*proto.Resource_Metadata{
+			{Key: "image", Value: "ubuntu"},
+			{Key: "empty", IsNull: true},
+		}, resource.Metadata)
+
+		require.Len(t, resource.Agents, 1)
+		agent := resource.Agents[0]
+		require.Equal(t, "main", agent.Name)
+		require.Equal(t, "token", agent.GetToken())
+		require.Equal(t, provisionersdk.DefaultDisplayApps(), agent.DisplayApps)
+		require.Len(t, agent.Apps, 1)
+		require.Equal(t, "code-server", agent.Apps[0].DisplayName)
+		require.Equal(t, proto.AppSharingLevel_AUTHENTICATED, agent.Apps[0].SharingLevel)
+		require.Len(t, agent.Scripts, 1)
+		require.True(t, agent.Scripts[0].RunOnStart)
+	})
+
+	t.Run("InstanceAuth", func(t *testing.T) {
+		t.Parallel()
+		resources, err := pulumi.ConvertResources([]byte(`[{
+			"name": "dev",
+			"type": "aws:ec2/instance:Instance",
+			"agents": [{"name": "main", "instance_id": "i-123"}]
+		}]`))
+		require.NoError(t, err)
+		require.Equal(t, "i-123", resources[0].Agents[0].GetInstanceId())
+	})
+
+	t.Run("DuplicateAgentName", func(t *testing.T) {
+		t.Parallel()
+		_, err := pulumi.ConvertResources([]byte(`[
+			{"name": "a", "type": "t", "agents": [{"name": "main"}]},
+			{"name": "b", "type": "t", "agents": [{"name": "main"}]}
+		]`))
+		require.ErrorContains(t, err, "duplicate agent name")
+	})
+
+	t.Run("InvalidAppSlug", func(t *testing.T) {
+		t.Parallel()
+		_, err := pulumi.ConvertResources([]byte(`[{
+			"name": "dev",
+			"type": "t",
+			"agents": [{"name": "main", "apps": [{"slug": "Not Valid"}]}]
+		}]`))
+		require.ErrorContains(t, err, "invalid app slug")
+	})
+}
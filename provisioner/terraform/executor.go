@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
@@ -21,6 +22,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 )
 
@@ -209,13 +211,58 @@ func (e *executor) init(ctx, killCtx context.Context, logr logSink) error {
 		<-doneErr
 	}()
 
+	var (
+		cacheKey string
+		restored bool
+	)
+	if e.server.cache != nil {
+		cacheKey, restored = e.restoreCache(ctx, logr)
+	}
+
 	args := []string{
 		"init",
 		"-no-color",
 		"-input=false",
 	}
 
-	return e.execWriteOutput(ctx, killCtx, args, e.basicEnv(), outWriter, errWriter)
+	err := e.execWriteOutput(ctx, killCtx, args, e.basicEnv(), outWriter, errWriter)
+	if err != nil {
+		return err
+	}
+	if cacheKey != "" && !restored {
+		// The cache is an optimization, so failing to fill it must not fail
+		// the build.
+		err = e.server.cache.Save(ctx, e.workdir, cacheKey)
+		if err != nil {
+			e.logger.Warn(ctx, "failed to save terraform init to the shared cache", slog.Error(err))
+		}
+	}
+	return nil
+}
+
+// restoreCache restores the providers and modules of the configuration from
+// the shared cache, so "terraform init" doesn't download them. It returns the
+// cache key of the configuration, which is empty if it could not be computed.
+func (e *executor) restoreCache(ctx context.Context, logr logSink) (string, bool) {
+	v, err := e.version(ctx)
+	if err != nil {
+		e.logger.Warn(ctx, "failed to get terraform version for the shared cache", slog.Error(err))
+		return "", false
+	}
+	key, err := tfcache.Key(e.workdir, v.String())
+	if err != nil {
+		e.logger.Warn(ctx, "failed to compute shared cache key", slog.Error(err))
+		return "", false
+	}
+	restored, err := e.server.cache.Restore(ctx, e.workdir, key)
+	if err != nil {
+		e.logger.Warn(ctx, "failed to restore terraform init from the shared cache", slog.Error(err))
+		return key, false
+	}
+	if restored {
+		logr.ProvisionLog(proto.LogLevel_INFO, "Restored Terraform providers and modules from the shared cache")
+	}
+	return key, restored
 }
 
 func getPlanFilePath(workdir string) string {
@@ -263,7 +310,7 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 	if err != nil {
 		return nil, xerrors.Errorf("terraform plan: %w", err)
 	}
-	state, err := e.planResources(ctx, killCtx, planfilePath)
+	state, drifted, err := e.planResources(ctx, killCtx, planfilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -271,9 +318,24 @@ func (e *executor) plan(ctx, killCtx context.Context, env, vars []string, logr l
 		Parameters:            state.Parameters,
 		Resources:             state.Resources,
 		ExternalAuthProviders: state.ExternalAuthProviders,
+		DriftedResources:      drifted,
 	}, nil
 }
 
+// driftedResources returns the addresses of the managed resources that
+// Terraform found to have changed outside of Terraform while refreshing.
+func driftedResources(plan *tfjson.Plan) []string {
+	drifted := []string{}
+	for _, change := range plan.ResourceDrift {
+		if change.Mode != tfjson.ManagedResourceMode || change.Change == nil || change.Change.Actions.NoOp() {
+			continue
+		}
+		drifted = append(drifted, change.Address)
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
 func onlyDataResources(sm tfjson.StateModule) tfjson.StateModule {
 	filtered := sm
 	filtered.Resources = []*tfjson.StateResource{}
@@ -291,19 +353,20 @@ func onlyDataResources(sm tfjson.StateModule) tfjson.StateModule {
 	return filtered
 }
 
-// planResources must only be called while the lock is held.
-func (e *executor) planResources(ctx, killCtx context.Context, planfilePath string) (*State, error) {
+// planResources must only be called while the lock is held. It also returns
+// the resources that drifted from the prior state.
+func (e *executor) planResources(ctx, killCtx context.Context, planfilePath string) (*State, []string, error) {
 	ctx, span := e.server.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
 	plan, err := e.showPlan(ctx, killCtx, planfilePath)
 	if err != nil {
-		return nil, xerrors.Errorf("show terraform plan file: %w", err)
+		return nil, nil, xerrors.Errorf("show terraform plan file: %w", err)
 	}
 
 	rawGraph, err := e.graph(ctx, killCtx)
 	if err != nil {
-		return nil, xerrors.Errorf("graph: %w", err)
+		return nil, nil, xerrors.Errorf("graph: %w", err)
 	}
 	modules := []*tfjson.StateModule{}
 	if plan.PriorState != nil {
@@ -321,9 +384,9 @@ func (e *executor) planResources(ctx, killCtx context.Context, planfilePath stri
 
 	state, err := ConvertState(modules, rawGraph)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return state, nil
+	return state, driftedResources(plan), nil
 }
 
 // showPlan must only be called while the lock is held.
@@ -173,3 +173,33 @@ func TestOnlyDataResources(t *testing.T) {
 		})
 	}
 }
+
+func TestDriftedResources(t *testing.T) {
+	t.Parallel()
+
+	drifted := driftedResources(&tfjson.Plan{
+		ResourceDrift: []*tfjson.ResourceChange{
+			{
+				Address: "docker_volume.home",
+				Mode:    tfjson.ManagedResourceMode,
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+			},
+			{
+				Address: "aws_instance.dev",
+				Mode:    tfjson.ManagedResourceMode,
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionDelete}},
+			},
+			{
+				Address: "data.coder_workspace.me",
+				Mode:    tfjson.DataResourceMode,
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionUpdate}},
+			},
+			{
+				Address: "null_resource.unchanged",
+				Mode:    tfjson.ManagedResourceMode,
+				Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+			},
+		},
+	})
+	require.Equal(t, []string{"aws_instance.dev", "docker_volume.home"}, drifted)
+}
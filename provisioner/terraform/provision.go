@@ -200,6 +200,8 @@ func provisionEnv(
 		"CODER_WORKSPACE_TEMPLATE_ID="+metadata.GetTemplateId(),
 		"CODER_WORKSPACE_TEMPLATE_NAME="+metadata.GetTemplateName(),
 		"CODER_WORKSPACE_TEMPLATE_VERSION="+metadata.GetTemplateVersion(),
+		"CODER_WORKSPACE_SNAPSHOT_ID="+metadata.GetWorkspaceSnapshotId(),
+		"CODER_WORKSPACE_RESTORE_SNAPSHOT_ID="+metadata.GetWorkspaceRestoreSnapshotId(),
 	)
 	for key, value := range provisionersdk.AgentScriptEnv() {
 		env = append(env, key+"="+value)
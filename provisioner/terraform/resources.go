@@ -94,6 +94,7 @@ type agentScriptAttributes struct {
 	RunOnStart       bool   `mapstructure:"run_on_start"`
 	RunOnStop        bool   `mapstructure:"run_on_stop"`
 	TimeoutSeconds   int32  `mapstructure:"timeout"`
+	Phase            string `mapstructure:"phase"`
 }
 
 // A mapping of attributes on the "healthcheck" resource.
@@ -109,6 +110,7 @@ type resourceMetadataAttributes struct {
 	Hide       bool                   `mapstructure:"hide"`
 	Icon       string                 `mapstructure:"icon"`
 	DailyCost  int32                  `mapstructure:"daily_cost"`
+	Snapshot   bool                   `mapstructure:"snapshot"`
 	Items      []resourceMetadataItem `mapstructure:"item"`
 }
 
@@ -494,6 +496,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 						RunOnStart:       attrs.RunOnStart,
 						RunOnStop:        attrs.RunOnStop,
 						TimeoutSeconds:   attrs.TimeoutSeconds,
+						Phase:            attrs.Phase,
 					})
 				}
 			}
@@ -505,6 +508,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 	resourceHidden := map[string]bool{}
 	resourceIcon := map[string]string{}
 	resourceCost := map[string]int32{}
+	resourceSnapshot := map[string]bool{}
 
 	metadataTargetLabels := map[string]bool{}
 	for _, resources := range tfResourcesByLabel {
@@ -562,6 +566,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			resourceHidden[targetLabel] = attrs.Hide
 			resourceIcon[targetLabel] = attrs.Icon
 			resourceCost[targetLabel] = attrs.DailyCost
+			resourceSnapshot[targetLabel] = attrs.Snapshot
 			for _, item := range attrs.Items {
 				resourceMetadata[targetLabel] = append(resourceMetadata[targetLabel],
 					&proto.Resource_Metadata{
@@ -597,6 +602,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 				Hide:         resourceHidden[label],
 				Icon:         resourceIcon[label],
 				DailyCost:    resourceCost[label],
+				Snapshot:     resourceSnapshot[label],
 				InstanceType: applyInstanceType(resource),
 			})
 		}
@@ -622,6 +628,11 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			Order:        int32(param.Order),
 			Ephemeral:    param.Ephemeral,
 		}
+		// The display condition isn't part of the provider's parameter schema
+		// yet, so it's read from the raw attributes.
+		if condition, ok := resource.AttributeValues["display_condition"].(string); ok {
+			protoParam.DisplayCondition = condition
+		}
 		if len(param.Validation) == 1 {
 			protoParam.ValidationRegex = param.Validation[0].Regex
 			protoParam.ValidationError = param.Validation[0].Error
@@ -13,6 +13,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/unhanger"
+	"github.com/coder/coder/v2/provisionerd/tfcache"
 	"github.com/coder/coder/v2/provisionersdk"
 )
 
@@ -24,7 +25,10 @@ type ServeOptions struct {
 	BinaryPath string
 	// CachePath must not be used by multiple processes at once.
 	CachePath string
-	Tracer    trace.Tracer
+	// Cache is the shared cache of Terraform providers and modules. It may
+	// be used by multiple processes at once.
+	Cache  *tfcache.Cache
+	Tracer trace.Tracer
 
 	// ExitTimeout defines how long we will wait for a running Terraform
 	// command to exit (cleanly) if the provision was stopped. This
@@ -99,6 +103,7 @@ func Serve(ctx context.Context, options *ServeOptions) error {
 		execMut:     &sync.Mutex{},
 		binaryPath:  options.BinaryPath,
 		cachePath:   options.CachePath,
+		cache:       options.Cache,
 		logger:      options.Logger,
 		tracer:      options.Tracer,
 		exitTimeout: options.ExitTimeout,
@@ -109,6 +114,7 @@ type server struct {
 	execMut     *sync.Mutex
 	binaryPath  string
 	cachePath   string
+	cache       *tfcache.Cache
 	logger      slog.Logger
 	tracer      trace.Tracer
 	exitTimeout time.Duration
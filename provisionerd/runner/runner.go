@@ -630,6 +630,7 @@ type templateImportProvision struct {
 	Resources             []*sdkproto.Resource
 	Parameters            []*sdkproto.RichParameter
 	ExternalAuthProviders []string
+	DriftedResources      []string
 }
 
 // Performs a dry-run provision when importing a template.
@@ -656,6 +657,8 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 		stage = "Detecting persistent resources"
 	case sdkproto.WorkspaceTransition_STOP:
 		stage = "Detecting ephemeral resources"
+	case sdkproto.WorkspaceTransition_DESTROY:
+		stage = "Detecting destroyed resources"
 	}
 	// use the notStopped so that if we attempt to gracefully cancel, the stream will still be available for us
 	// to send the cancel to the provisioner
@@ -721,6 +724,7 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(
 				Resources:             c.Resources,
 				Parameters:            c.Parameters,
 				ExternalAuthProviders: c.ExternalAuthProviders,
+				DriftedResources:      c.DriftedResources,
 			}, nil
 		default:
 			return nil, xerrors.Errorf("invalid message type %q received from provisioner",
@@ -734,8 +738,9 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 	defer span.End()
 
 	// Ensure all metadata fields are set as they are all optional for dry-run.
+	// The workspace transition defaults to start, but workspace build dry-runs
+	// may plan any transition.
 	metadata := r.job.GetTemplateDryRun().GetMetadata()
-	metadata.WorkspaceTransition = sdkproto.WorkspaceTransition_START
 	if metadata.CoderUrl == "" {
 		metadata.CoderUrl = "http://localhost:3000"
 	}
@@ -763,6 +768,7 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 
 	failedJob := r.configure(&sdkproto.Config{
 		TemplateSourceArchive: r.job.GetTemplateSourceArchive(),
+		State:                 r.job.GetTemplateDryRun().GetState(),
 	})
 	if failedJob != nil {
 		return nil, failedJob
@@ -782,7 +788,8 @@ func (r *Runner) runTemplateDryRun(ctx context.Context) (*proto.CompletedJob, *p
 		JobId: r.job.JobId,
 		Type: &proto.CompletedJob_TemplateDryRun_{
 			TemplateDryRun: &proto.CompletedJob_TemplateDryRun{
-				Resources: provision.Resources,
+				Resources:        provision.Resources,
+				DriftedResources: provision.DriftedResources,
 			},
 		},
 	}, nil
@@ -882,13 +889,20 @@ func (r *Runner) commitQuota(ctx context.Context, resources []*sdkproto.Resource
 	}
 
 	if !resp.Ok {
+		output := "This build would exceed your quota. Failing."
+		if resp.Reason != "" {
+			output = resp.Reason + " Failing."
+		}
 		r.queueLog(ctx, &proto.Log{
 			Source:    proto.LogSource_PROVISIONER,
 			Level:     sdkproto.LogLevel_WARN,
 			CreatedAt: time.Now().UnixMilli(),
-			Output:    "This build would exceed your quota. Failing.",
+			Output:    output,
 			Stage:     stage,
 		})
+		if resp.Reason != "" {
+			return r.failedWorkspaceBuildf("insufficient quota: %s", resp.Reason)
+		}
 		return r.failedWorkspaceBuildf("insufficient quota")
 	}
 	return nil
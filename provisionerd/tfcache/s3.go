@@ -0,0 +1,229 @@
+package tfcache
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/xerrors"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3StoreOptions configures the S3 bucket the cache is stored in.
+type S3StoreOptions struct {
+	Bucket string
+	// Region defaults to the region in the AWS config.
+	Region string
+	// Prefix is prepended to every object key.
+	Prefix string
+	// Endpoint overrides the S3 endpoint for S3-compatible storage. Objects
+	// are addressed with path-style URLs when it is set.
+	Endpoint *url.URL
+	// Credentials defaults to the AWS SDK's default credential chain.
+	Credentials aws.CredentialsProvider
+	HTTPClient  *http.Client
+}
+
+type s3Store struct {
+	opts   S3StoreOptions
+	signer *v4.Signer
+}
+
+// NewS3Store returns a Store that keeps objects in an S3 bucket, so the cache
+// can be shared by daemons on different hosts.
+func NewS3Store(ctx context.Context, opts S3StoreOptions) (Store, error) {
+	if opts.Bucket == "" {
+		return nil, xerrors.New("s3 bucket must be set")
+	}
+	if opts.Credentials == nil || opts.Region == "" {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("load aws config: %w", err)
+		}
+		if opts.Credentials == nil {
+			opts.Credentials = cfg.Credentials
+		}
+		if opts.Region == "" {
+			opts.Region = cfg.Region
+		}
+	}
+	if opts.Region == "" {
+		return nil, xerrors.New("s3 region must be set")
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &s3Store{
+		opts:   opts,
+		signer: v4.NewSigner(),
+	}, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("get object %q: %w", key, err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		_ = res.Body.Close()
+		return nil, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, readS3Error(res, key)
+	}
+	return res.Body, nil
+}
+
+func (s *s3Store) Stat(ctx context.Context, key string) (Object, error) {
+	res, err := s.do(ctx, http.MethodHead, s.objectURL(key), nil, 0)
+	if err != nil {
+		return Object{}, xerrors.Errorf("head object %q: %w", key, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return Object{}, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return Object{}, readS3Error(res, key)
+	}
+	modTime, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+	return Object{Key: key, Size: res.ContentLength, ModTime: modTime}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if size == 0 {
+		// Otherwise the empty body would be sent chunked, which S3 rejects.
+		r = http.NoBody
+	}
+	res, err := s.do(ctx, http.MethodPut, s.objectURL(key), r, size)
+	if err != nil {
+		return xerrors.Errorf("put object %q: %w", key, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return readS3Error(res, key)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	res, err := s.do(ctx, http.MethodDelete, s.objectURL(key), nil, 0)
+	if err != nil {
+		return xerrors.Errorf("delete object %q: %w", key, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return readS3Error(res, key)
+	}
+	return nil
+}
+
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+	token := ""
+	for {
+		u := s.bucketURL()
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", s.opts.Prefix+prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = query.Encode()
+
+		res, err := s.do(ctx, http.MethodGet, u, nil, 0)
+		if err != nil {
+			return nil, xerrors.Errorf("list objects: %w", err)
+		}
+		if res.StatusCode != http.StatusOK {
+			err = readS3Error(res, prefix)
+			_ = res.Body.Close()
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(res.Body).Decode(&result)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("decode list objects: %w", err)
+		}
+		for _, content := range result.Contents {
+			objects = append(objects, Object{
+				Key:     strings.TrimPrefix(content.Key, s.opts.Prefix),
+				Size:    content.Size,
+				ModTime: content.LastModified,
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a signed request. Request bodies are streamed without being
+// hashed, as objects can be hundreds of megabytes.
+func (s *s3Store) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	payloadHash := emptyPayloadHash
+	if body != nil {
+		payloadHash = "UNSIGNED-PAYLOAD"
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := s.opts.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.opts.Region, time.Now().UTC())
+	if err != nil {
+		return nil, xerrors.Errorf("sign request: %w", err)
+	}
+	return s.opts.HTTPClient.Do(req)
+}
+
+func (s *s3Store) bucketURL() *url.URL {
+	if s.opts.Endpoint != nil {
+		return s.opts.Endpoint.JoinPath(s.opts.Bucket)
+	}
+	return &url.URL{
+		Scheme: "https",
+		Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", s.opts.Bucket, s.opts.Region),
+		Path:   "/",
+	}
+}
+
+func (s *s3Store) objectURL(key string) *url.URL {
+	return s.bucketURL().JoinPath(s.opts.Prefix + key)
+}
+
+func readS3Error(res *http.Response, key string) error {
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return xerrors.Errorf("%s %q: unexpected status code %d: %s", res.Request.Method, key, res.StatusCode, msg)
+}
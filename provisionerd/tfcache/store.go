@@ -0,0 +1,235 @@
+package tfcache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrNotFound is returned by a Store when an object does not exist.
+var ErrNotFound = xerrors.New("object not found")
+
+// Object describes an object in a Store.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store is the storage backend of the cache. Keys are slash-separated paths.
+// Objects are immutable once written, so implementations do not need to
+// handle concurrent writes of different content to the same key.
+type Store interface {
+	// Get returns the contents of an object, or ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat returns the metadata of an object, or ErrNotFound.
+	Stat(ctx context.Context, key string) (Object, error)
+	// Put writes size bytes from r to an object, replacing it if it exists.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Delete removes an object. Deleting an object that does not exist is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+	// List returns the objects whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// NewDiskStore returns a Store that keeps objects as files in dir. Writes are
+// atomic, so dir can be shared by daemons on the same host.
+func NewDiskStore(dir string) (Store, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("mkdir %q: %w", dir, err)
+	}
+	return &diskStore{dir: dir}, nil
+}
+
+type diskStore struct {
+	dir string
+}
+
+func (s *diskStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s *diskStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open %q: %w", key, err)
+	}
+	return f, nil
+}
+
+func (s *diskStore) Stat(_ context.Context, key string) (Object, error) {
+	info, err := os.Stat(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, xerrors.Errorf("stat %q: %w", key, err)
+	}
+	return Object{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (s *diskStore) Put(_ context.Context, key string, r io.Reader, size int64) error {
+	path := s.path(key)
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return xerrors.Errorf("mkdir: %w", err)
+	}
+	// Write to a temporary file first so readers never see a partial object.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return xerrors.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		return xerrors.Errorf("write %q: %w", key, err)
+	}
+	if n != size {
+		return xerrors.Errorf("write %q: wrote %d bytes, expected %d", key, n, size)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return xerrors.Errorf("close temp file: %w", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return xerrors.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
+
+func (s *diskStore) Delete(_ context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return xerrors.Errorf("remove %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *diskStore) List(_ context.Context, prefix string) ([]Object, error) {
+	objects := make([]Object, 0)
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("walk %q: %w", s.dir, err)
+	}
+	return objects, nil
+}
+
+// NewTieredStore returns a Store that reads from local before remote, and
+// copies objects that are only in remote to local. Writes and deletes go to
+// both.
+func NewTieredStore(local, remote Store) Store {
+	return &tieredStore{local: local, remote: remote}
+}
+
+type tieredStore struct {
+	local  Store
+	remote Store
+}
+
+func (s *tieredStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := s.local.Get(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		return r, err
+	}
+	obj, err := s.remote.Stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	r, err = s.remote.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	err = s.local.Put(ctx, key, r, obj.Size)
+	_ = r.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("copy %q to local store: %w", key, err)
+	}
+	return s.local.Get(ctx, key)
+}
+
+func (s *tieredStore) Stat(ctx context.Context, key string) (Object, error) {
+	obj, err := s.local.Stat(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		return obj, err
+	}
+	return s.remote.Stat(ctx, key)
+}
+
+func (s *tieredStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	err := s.local.Put(ctx, key, r, size)
+	if err != nil {
+		return err
+	}
+	local, err := s.local.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer local.Close()
+	return s.remote.Put(ctx, key, local, size)
+}
+
+func (s *tieredStore) Delete(ctx context.Context, key string) error {
+	err := s.local.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+	return s.remote.Delete(ctx, key)
+}
+
+func (s *tieredStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	local, err := s.local.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := s.remote.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(remote))
+	for _, obj := range remote {
+		seen[obj.Key] = struct{}{}
+	}
+	for _, obj := range local {
+		if _, ok := seen[obj.Key]; !ok {
+			remote = append(remote, obj)
+		}
+	}
+	return remote, nil
+}
@@ -0,0 +1,477 @@
+// Package tfcache is a content-addressable cache of the Terraform providers
+// and modules installed by "terraform init". It is shared across provisioner
+// daemons so cold builds don't download them again.
+//
+// The result of an init is stored as a manifest keyed by a hash of the
+// configuration. The manifest lists archives keyed by the SHA-256 of their
+// contents. Each provider is archived separately, so a provider is stored
+// once however many configurations use it.
+package tfcache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	manifestPrefix = "manifests/"
+	blobPrefix     = "blobs/sha256/"
+
+	dataDir  = ".terraform"
+	lockFile = ".terraform.lock.hcl"
+)
+
+// Options configure where the cache is stored.
+type Options struct {
+	// Dir is the local directory the cache is stored in. Daemons on the same
+	// host can share it.
+	Dir string
+	// S3 adds a bucket behind Dir, so daemons on different hosts share the
+	// cache.
+	S3 *S3StoreOptions
+	// Registerer registers the hit and miss metrics, if set.
+	Registerer prometheus.Registerer
+}
+
+// Cache stores the results of "terraform init".
+type Cache struct {
+	store  Store
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+// New returns a cache stored in the given locations.
+func New(ctx context.Context, opts Options) (*Cache, error) {
+	store, err := NewDiskStore(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.S3 != nil {
+		remote, err := NewS3Store(ctx, *opts.S3)
+		if err != nil {
+			return nil, xerrors.Errorf("create s3 store: %w", err)
+		}
+		store = NewTieredStore(store, remote)
+	}
+	return NewWithStore(store, opts.Registerer), nil
+}
+
+// NewWithStore returns a cache stored in store.
+func NewWithStore(store Store, registerer prometheus.Registerer) *Cache {
+	c := &Cache{
+		store: store,
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "cache_hits_total",
+			Help:      "The number of Terraform inits that restored providers and modules from the shared cache.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "cache_misses_total",
+			Help:      "The number of Terraform inits that were not found in the shared cache.",
+		}),
+	}
+	if registerer != nil {
+		registerer.MustRegister(c.hits, c.misses)
+	}
+	return c
+}
+
+// Key returns the cache key of the configuration in workdir. It covers the
+// Terraform configuration and lock files, the Terraform version and the
+// platform, which together determine what "terraform init" installs.
+func Key(workdir, terraformVersion string) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\x00%s/%s\x00", terraformVersion, runtime.GOOS, runtime.GOARCH)
+	err := filepath.WalkDir(workdir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == dataDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if !d.Type().IsRegular() || (!strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json") && name != lockFile) {
+			return nil
+		}
+		rel, err := filepath.Rel(workdir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		_, _ = h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", xerrors.Errorf("hash configuration: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type manifest struct {
+	Entries []manifestEntry `json:"entries"`
+}
+
+type manifestEntry struct {
+	Path   string `json:"path"`
+	Digest string `json:"digest"`
+}
+
+// Restore extracts the providers and modules cached for key into workdir. It
+// returns false if nothing is cached for key.
+func (c *Cache) Restore(ctx context.Context, workdir, key string) (bool, error) {
+	m, err := c.readManifest(ctx, manifestPrefix+key+".json")
+	if errors.Is(err, ErrNotFound) {
+		c.misses.Inc()
+		return false, nil
+	}
+	if err != nil {
+		c.misses.Inc()
+		return false, err
+	}
+	for _, entry := range m.Entries {
+		err = c.extract(ctx, workdir, entry.Digest)
+		if err != nil {
+			// Don't leave a partial install behind for "terraform init".
+			_ = os.RemoveAll(filepath.Join(workdir, dataDir))
+			c.misses.Inc()
+			return false, xerrors.Errorf("extract %q: %w", entry.Path, err)
+		}
+	}
+	c.hits.Inc()
+	return true, nil
+}
+
+// Save stores the providers and modules installed into workdir under key.
+// Archives that are already stored are not uploaded again.
+func (c *Cache) Save(ctx context.Context, workdir, key string) error {
+	paths, err := cachedPaths(workdir)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	m := manifest{Entries: make([]manifestEntry, 0, len(paths))}
+	for _, p := range paths {
+		digest, err := c.storeArchive(ctx, workdir, p)
+		if err != nil {
+			return xerrors.Errorf("store %q: %w", p, err)
+		}
+		m.Entries = append(m.Entries, manifestEntry{Path: p, Digest: digest})
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return xerrors.Errorf("marshal manifest: %w", err)
+	}
+	err = c.store.Put(ctx, manifestPrefix+key+".json", bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return xerrors.Errorf("store manifest: %w", err)
+	}
+	return nil
+}
+
+// PurgeStats reports what Purge removed.
+type PurgeStats struct {
+	Objects int64
+	Bytes   int64
+}
+
+// Purge removes the init results saved before the given time, and the
+// archives that no remaining result uses. Archives are uploaded before the
+// manifest that uses them, so an init that is saved concurrently may lose its
+// archives, in which case it is treated as a miss when it is restored.
+func (c *Cache) Purge(ctx context.Context, before time.Time) (PurgeStats, error) {
+	var stats PurgeStats
+	manifests, err := c.store.List(ctx, manifestPrefix)
+	if err != nil {
+		return stats, xerrors.Errorf("list manifests: %w", err)
+	}
+	used := make(map[string]struct{})
+	for _, obj := range manifests {
+		if obj.ModTime.Before(before) {
+			err = c.store.Delete(ctx, obj.Key)
+			if err != nil {
+				return stats, xerrors.Errorf("delete manifest: %w", err)
+			}
+			stats.Objects++
+			stats.Bytes += obj.Size
+			continue
+		}
+		m, err := c.readManifest(ctx, obj.Key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		for _, entry := range m.Entries {
+			used[entry.Digest] = struct{}{}
+		}
+	}
+
+	blobs, err := c.store.List(ctx, blobPrefix)
+	if err != nil {
+		return stats, xerrors.Errorf("list archives: %w", err)
+	}
+	for _, obj := range blobs {
+		if _, ok := used[strings.TrimPrefix(obj.Key, blobPrefix)]; ok || !obj.ModTime.Before(before) {
+			continue
+		}
+		err = c.store.Delete(ctx, obj.Key)
+		if err != nil {
+			return stats, xerrors.Errorf("delete archive: %w", err)
+		}
+		stats.Objects++
+		stats.Bytes += obj.Size
+	}
+	return stats, nil
+}
+
+func (c *Cache) readManifest(ctx context.Context, key string) (manifest, error) {
+	r, err := c.store.Get(ctx, key)
+	if err != nil {
+		return manifest{}, err
+	}
+	defer r.Close()
+	var m manifest
+	err = json.NewDecoder(r).Decode(&m)
+	if err != nil {
+		return manifest{}, xerrors.Errorf("decode manifest %q: %w", key, err)
+	}
+	return m, nil
+}
+
+// cachedPaths returns the paths in workdir, relative to it, that are cached:
+// the lock file, the modules, and each provider package.
+func cachedPaths(workdir string) ([]string, error) {
+	paths := make([]string, 0)
+	for _, p := range []string{lockFile, path.Join(dataDir, "modules")} {
+		_, err := os.Stat(filepath.Join(workdir, filepath.FromSlash(p)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("stat %q: %w", p, err)
+		}
+		paths = append(paths, p)
+	}
+	// Providers are installed to <hostname>/<namespace>/<type>/<version>/<os_arch>.
+	providers, err := filepath.Glob(filepath.Join(workdir, dataDir, "providers", "*", "*", "*", "*", "*"))
+	if err != nil {
+		return nil, xerrors.Errorf("find providers: %w", err)
+	}
+	for _, p := range providers {
+		rel, err := filepath.Rel(workdir, p)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths, nil
+}
+
+// storeArchive archives a path in workdir and stores it, returning the
+// digest of the archive.
+func (c *Cache) storeArchive(ctx context.Context, workdir, rel string) (string, error) {
+	tmp, err := os.CreateTemp("", "tfcache-*.tar.gz")
+	if err != nil {
+		return "", xerrors.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	h := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, h))
+	tw := tar.NewWriter(gz)
+	err = writeArchive(tw, workdir, rel)
+	if err != nil {
+		return "", err
+	}
+	err = tw.Close()
+	if err != nil {
+		return "", xerrors.Errorf("close tar writer: %w", err)
+	}
+	err = gz.Close()
+	if err != nil {
+		return "", xerrors.Errorf("close gzip writer: %w", err)
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+
+	key := blobPrefix + digest
+	_, err = c.store.Stat(ctx, key)
+	if err == nil {
+		return digest, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", xerrors.Errorf("seek temp file: %w", err)
+	}
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", xerrors.Errorf("seek temp file: %w", err)
+	}
+	err = c.store.Put(ctx, key, tmp, size)
+	if err != nil {
+		return "", err
+	}
+	return digest, nil
+}
+
+// writeArchive writes a path in workdir to tw, with names relative to
+// workdir. The path itself may be a symlink, as Terraform links providers to
+// its plugin cache directory. Headers don't include modification times, so
+// the same content always produces the same archive.
+func writeArchive(tw *tar.Writer, workdir, rel string) error {
+	root, err := filepath.EvalSymlinks(filepath.Join(workdir, filepath.FromSlash(rel)))
+	if err != nil {
+		return xerrors.Errorf("resolve %q: %w", rel, err)
+	}
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		sub, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(rel, filepath.ToSlash(sub))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    int64(info.Mode().Perm()),
+			ModTime: time.Unix(0, 0),
+		}
+		switch {
+		case d.IsDir():
+			header.Typeflag = tar.TypeDir
+			header.Name += "/"
+			return tw.WriteHeader(header)
+		case info.Mode().IsRegular():
+			header.Typeflag = tar.TypeReg
+			header.Size = info.Size()
+			err = tw.WriteHeader(header)
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		default:
+			return xerrors.Errorf("unsupported file type %q for %q", info.Mode().Type(), name)
+		}
+	})
+}
+
+// extract extracts an archive into workdir, verifying its digest.
+func (c *Cache) extract(ctx context.Context, workdir, digest string) error {
+	r, err := c.store.Get(ctx, blobPrefix+digest)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	h := sha256.New()
+	gz, err := gzip.NewReader(io.TeeReader(r, h))
+	if err != nil {
+		return xerrors.Errorf("create gzip reader: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return xerrors.Errorf("read tar header: %w", err)
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return xerrors.Errorf("invalid path %q in archive", header.Name)
+		}
+		target := filepath.Join(workdir, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, fs.FileMode(header.Mode).Perm()|0o700)
+			if err != nil {
+				return xerrors.Errorf("mkdir %q: %w", name, err)
+			}
+		case tar.TypeReg:
+			err = writeFile(target, tr, fs.FileMode(header.Mode).Perm())
+			if err != nil {
+				return xerrors.Errorf("write %q: %w", name, err)
+			}
+		default:
+			return xerrors.Errorf("unsupported type %q for %q in archive", header.Typeflag, header.Name)
+		}
+	}
+	// Drain the gzip trailer so the whole archive is hashed.
+	_, err = io.Copy(io.Discard, gz)
+	if err != nil {
+		return xerrors.Errorf("read archive: %w", err)
+	}
+	_, err = io.Copy(io.Discard, r)
+	if err != nil {
+		return xerrors.Errorf("read archive: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != digest {
+		return xerrors.Errorf("archive digest %q does not match %q", got, digest)
+	}
+	return nil
+}
+
+func writeFile(target string, r io.Reader, mode fs.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0o700)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,248 @@
+package tfcache_test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisionerd/tfcache"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		cache, err := tfcache.New(ctx, tfcache.Options{Dir: t.TempDir()})
+		require.NoError(t, err)
+
+		workdir := initWorkdir(t)
+		key, err := tfcache.Key(workdir, "1.6.0")
+		require.NoError(t, err)
+
+		restored, err := cache.Restore(ctx, workdir, key)
+		require.NoError(t, err)
+		require.False(t, restored)
+
+		err = cache.Save(ctx, workdir, key)
+		require.NoError(t, err)
+
+		// A fresh checkout of the same configuration hits the cache.
+		other := t.TempDir()
+		writeFile(t, filepath.Join(other, "main.tf"), "terraform {}")
+		writeFile(t, filepath.Join(other, ".terraform.lock.hcl"), "lock")
+		otherKey, err := tfcache.Key(other, "1.6.0")
+		require.NoError(t, err)
+		require.Equal(t, key, otherKey)
+
+		restored, err = cache.Restore(ctx, other, key)
+		require.NoError(t, err)
+		require.True(t, restored)
+		require.Equal(t, "lock", readFile(t, filepath.Join(other, ".terraform.lock.hcl")))
+		require.Equal(t, "module", readFile(t, filepath.Join(other, ".terraform", "modules", "modules.json")))
+		provider := filepath.Join(other, ".terraform", "providers", "registry.terraform.io", "coder", "coder", "0.12.0", "linux_amd64", "terraform-provider-coder")
+		require.Equal(t, "provider", readFile(t, provider))
+		info, err := os.Stat(provider)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		t.Parallel()
+		workdir := initWorkdir(t)
+		key, err := tfcache.Key(workdir, "1.6.0")
+		require.NoError(t, err)
+
+		// Other Terraform versions install differently.
+		other, err := tfcache.Key(workdir, "1.5.7")
+		require.NoError(t, err)
+		require.NotEqual(t, key, other)
+
+		// Changes to the configuration change the key.
+		writeFile(t, filepath.Join(workdir, "main.tf"), "terraform { required_version = \">= 1.0\" }")
+		other, err = tfcache.Key(workdir, "1.6.0")
+		require.NoError(t, err)
+		require.NotEqual(t, key, other)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		dir := t.TempDir()
+		cache, err := tfcache.New(ctx, tfcache.Options{Dir: dir})
+		require.NoError(t, err)
+
+		workdir := initWorkdir(t)
+		key, err := tfcache.Key(workdir, "1.6.0")
+		require.NoError(t, err)
+		err = cache.Save(ctx, workdir, key)
+		require.NoError(t, err)
+
+		// Nothing was saved before an hour ago.
+		stats, err := cache.Purge(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, stats.Objects)
+
+		stats, err = cache.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		// The manifest and the lock file, modules and provider archives.
+		require.EqualValues(t, 4, stats.Objects)
+		require.Positive(t, stats.Bytes)
+
+		restored, err := cache.Restore(ctx, t.TempDir(), key)
+		require.NoError(t, err)
+		require.False(t, restored)
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitShort)
+		srv := httptest.NewServer(newFakeS3())
+		t.Cleanup(srv.Close)
+		endpoint, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		s3 := &tfcache.S3StoreOptions{
+			Bucket:      "cache",
+			Region:      "us-east-1",
+			Prefix:      "coder/",
+			Endpoint:    endpoint,
+			Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		}
+
+		workdir := initWorkdir(t)
+		key, err := tfcache.Key(workdir, "1.6.0")
+		require.NoError(t, err)
+		cache, err := tfcache.New(ctx, tfcache.Options{Dir: t.TempDir(), S3: s3})
+		require.NoError(t, err)
+		err = cache.Save(ctx, workdir, key)
+		require.NoError(t, err)
+
+		// A daemon on another host, with an empty local cache, restores
+		// from the bucket.
+		cache, err = tfcache.New(ctx, tfcache.Options{Dir: t.TempDir(), S3: s3})
+		require.NoError(t, err)
+		other := t.TempDir()
+		restored, err := cache.Restore(ctx, other, key)
+		require.NoError(t, err)
+		require.True(t, restored)
+		require.Equal(t, "module", readFile(t, filepath.Join(other, ".terraform", "modules", "modules.json")))
+
+		stats, err := cache.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.EqualValues(t, 4, stats.Objects)
+	})
+}
+
+// initWorkdir returns a directory laid out as "terraform init" leaves it.
+func initWorkdir(t *testing.T) string {
+	t.Helper()
+	workdir := t.TempDir()
+	writeFile(t, filepath.Join(workdir, "main.tf"), "terraform {}")
+	writeFile(t, filepath.Join(workdir, ".terraform.lock.hcl"), "lock")
+	writeFile(t, filepath.Join(workdir, ".terraform", "modules", "modules.json"), "module")
+	provider := filepath.Join(workdir, ".terraform", "providers", "registry.terraform.io", "coder", "coder", "0.12.0", "linux_amd64", "terraform-provider-coder")
+	writeFile(t, provider, "provider")
+	require.NoError(t, os.Chmod(provider, 0o755))
+	return workdir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+type fakeObject struct {
+	data    []byte
+	modTime time.Time
+}
+
+// newFakeS3 returns a handler that serves the subset of the S3 API used by
+// the store, with path-style addressing.
+func newFakeS3() http.Handler {
+	var (
+		mu      sync.Mutex
+		objects = map[string]fakeObject{}
+	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if bucket != "cache" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && key == "":
+			type content struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+				Size         int64     `xml:"Size"`
+			}
+			result := struct {
+				XMLName  xml.Name  `xml:"ListBucketResult"`
+				Contents []content `xml:"Contents"`
+			}{}
+			prefix := r.URL.Query().Get("prefix")
+			for k, obj := range objects {
+				if strings.HasPrefix(k, prefix) {
+					result.Contents = append(result.Contents, content{Key: k, LastModified: obj.modTime, Size: int64(len(obj.data))})
+				}
+			}
+			sort.Slice(result.Contents, func(i, j int) bool {
+				return result.Contents[i].Key < result.Contents[j].Key
+			})
+			_ = xml.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodGet, r.Method == http.MethodHead:
+			obj, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", obj.modTime.Format(http.TimeFormat))
+			w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+			if r.Method == http.MethodGet {
+				_, _ = w.Write(obj.data)
+			}
+		case r.Method == http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[key] = fakeObject{data: data, modTime: time.Now().UTC()}
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
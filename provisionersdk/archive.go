@@ -3,7 +3,9 @@ package provisionersdk
 import (
 	"archive/tar"
 	"context"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -41,6 +43,39 @@ func DirHasLockfile(dir string) (bool, error) {
 	return dirHasExt(dir, ".terraform.lock.hcl")
 }
 
+// DirHasPulumiProject returns whether dir contains a Pulumi project file, in
+// which case it is run by the Pulumi provisioner instead of Terraform.
+func DirHasPulumiProject(dir string) (bool, error) {
+	for _, name := range []string{"Pulumi.yaml", "Pulumi.yml"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// KubernetesProjectFile is the file that makes a directory a template of the
+// Kubernetes provisioner.
+const KubernetesProjectFile = "coder-kubernetes.yaml"
+
+// DirHasKubernetesProject returns whether dir contains a Kubernetes project
+// file, in which case its manifest is applied by the Kubernetes provisioner
+// instead of Terraform.
+func DirHasKubernetesProject(dir string) (bool, error) {
+	_, err := os.Stat(filepath.Join(dir, KubernetesProjectFile))
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	return false, nil
+}
+
 // Tar archives a Terraform directory.
 func Tar(w io.Writer, logger slog.Logger, directory string, limit int64) error {
 	// The total bytes written must be under the limit, so use -1
@@ -52,6 +87,18 @@ func Tar(w io.Writer, logger slog.Logger, directory string, limit int64) error {
 	if err != nil {
 		return err
 	}
+	if !hasTf {
+		hasTf, err = DirHasPulumiProject(directory)
+		if err != nil {
+			return err
+		}
+	}
+	if !hasTf {
+		hasTf, err = DirHasKubernetesProject(directory)
+		if err != nil {
+			return err
+		}
+	}
 	if !hasTf {
 		absPath, err := filepath.Abs(directory)
 		if err != nil {
@@ -61,8 +108,8 @@ func Tar(w io.Writer, logger slog.Logger, directory string, limit int64) error {
 		// Show absolute path to aid in debugging. E.g. showing "." is
 		// useless.
 		return xerrors.Errorf(
-			"%s is not a valid template since it has no %s files",
-			absPath, tfExts,
+			"%s is not a valid template since it has no %s files, Pulumi.yaml or %s",
+			absPath, tfExts, KubernetesProjectFile,
 		)
 	}
 
@@ -89,6 +89,22 @@ func TestTar(t *testing.T) {
 		err = provisionersdk.Tar(io.Discard, log, dir, 1024)
 		require.NoError(t, err)
 	})
+	t.Run("ValidPulumi", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "Pulumi.yaml"), []byte("name: dev\nruntime: yaml\n"), 0o600)
+		require.NoError(t, err)
+		err = provisionersdk.Tar(io.Discard, log, dir, provisionersdk.TemplateArchiveLimit)
+		require.NoError(t, err)
+	})
+	t.Run("ValidKubernetes", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, provisionersdk.KubernetesProjectFile), []byte("agent:\n  os: linux\n"), 0o600)
+		require.NoError(t, err)
+		err = provisionersdk.Tar(io.Discard, log, dir, provisionersdk.TemplateArchiveLimit)
+		require.NoError(t, err)
+	})
 	t.Run("HiddenFiles", func(t *testing.T) {
 		t.Parallel()
 		dir := t.TempDir()
@@ -123,6 +123,10 @@ func updateAuditDoc(doc []byte, auditableResourcesMap AuditableResourcesMap) ([]
 		if resourceName == "AuditableGroup" {
 			readableResourceName = "Group"
 		}
+		// AuditableOrganizationMember only adds the username as the audit target.
+		if resourceName == "AuditableOrganizationMember" {
+			readableResourceName = "OrganizationMember"
+		}
 
 		// Create a string of audit actions for each resource
 		var auditActions []string
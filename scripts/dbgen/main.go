@@ -61,17 +61,37 @@ func run() error {
 	}
 
 	err = orderAndStubDatabaseFunctions(filepath.Join(databasePath, "dbmetrics", "dbmetrics.go"), "m", "metricsStore", func(params stubParams) string {
+		observe := ""
+		if params.ErrorReturn != "" {
+			observe += fmt.Sprintf("m.observeError(%q, %s)\n", params.FuncName, params.ErrorReturn)
+		}
+		if params.RowsReturn != "" {
+			observe += fmt.Sprintf("m.observeRows(%q, len(%s))\n", params.FuncName, params.RowsReturn)
+		}
 		return fmt.Sprintf(`
 start := time.Now()
 %s := m.s.%s(%s)
 m.queryLatencies.WithLabelValues("%s").Observe(time.Since(start).Seconds())
-return %s
-`, params.Returns, params.FuncName, params.Parameters, params.FuncName, params.Returns)
+%sreturn %s
+`, params.Returns, params.FuncName, params.Parameters, params.FuncName, observe, params.Returns)
 	})
 	if err != nil {
 		return xerrors.Errorf("stub dbmetrics: %w", err)
 	}
 
+	err = orderAndStubDatabaseFunctions(filepath.Join(databasePath, "dbtrace", "dbtrace.go"), "t", "traceStore", func(params stubParams) string {
+		spanArgs := append([]string{"ctx", fmt.Sprintf("%q", params.FuncName)}, strings.Split(params.Parameters, ",")[1:]...)
+		return fmt.Sprintf(`
+ctx, span := t.startSpan(%s)
+%s := t.s.%s(%s)
+endSpan(span, %s)
+return %s
+`, strings.Join(spanArgs, ","), params.Returns, params.FuncName, params.Parameters, params.ErrorReturn, params.Returns)
+	})
+	if err != nil {
+		return xerrors.Errorf("stub dbtrace: %w", err)
+	}
+
 	err = orderAndStubDatabaseFunctions(filepath.Join(databasePath, "dbauthz", "dbauthz.go"), "q", "querier", func(params stubParams) string {
 		return `panic("not implemented")`
 	})
@@ -241,6 +261,10 @@ type stubParams struct {
 	FuncName   string
 	Parameters string
 	Returns    string
+	// ErrorReturn is the name of the error return value, if any.
+	ErrorReturn string
+	// RowsReturn is the name of the slice return value, if any.
+	RowsReturn string
 }
 
 // orderAndStubDatabaseFunctions orders the functions in the file and stubs them.
@@ -383,16 +407,30 @@ func orderAndStubDatabaseFunctions(filePath, receiver, structName string, stub f
 				}
 			}
 			returns := make([]string, 0)
+			errReturn, rowsReturn := "", ""
 			if fn.Func.Results != nil {
-				for i := range fn.Func.Results.List {
-					returns = append(returns, fmt.Sprintf("r%d", i))
+				for i, r := range fn.Func.Results.List {
+					name := fmt.Sprintf("r%d", i)
+					returns = append(returns, name)
+					switch typ := r.Type.(type) {
+					case *dst.Ident:
+						if typ.Name == "error" && typ.Path == "" {
+							errReturn = name
+						}
+					case *dst.ArrayType:
+						if elt, ok := typ.Elt.(*dst.Ident); !ok || elt.Name != "byte" {
+							rowsReturn = name
+						}
+					}
 				}
 			}
 
 			funcDecl, err := compileFuncDecl(stub(stubParams{
-				FuncName:   fn.Name,
-				Parameters: strings.Join(params, ","),
-				Returns:    strings.Join(returns, ","),
+				FuncName:    fn.Name,
+				Parameters:  strings.Join(params, ","),
+				Returns:     strings.Join(returns, ","),
+				ErrorReturn: errReturn,
+				RowsReturn:  rowsReturn,
 			}))
 			if err != nil {
 				return xerrors.Errorf("compile func decl: %w", err)
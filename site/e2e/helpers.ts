@@ -510,6 +510,7 @@ const createTemplateVersionTar = async (
       instanceType: "",
       metadata: [],
       name: "dev",
+      snapshot: false,
       type: "echo",
       ...resource,
     } as Resource;
@@ -537,6 +538,7 @@ const createTemplateVersionTar = async (
       resources: [],
       parameters: [],
       externalAuthProviders: [],
+      driftedResources: [],
       ...response.plan,
     } as PlanComplete;
     response.plan.resources = response.plan.resources?.map(fillResource);
@@ -19,6 +19,7 @@ const emptyParameter: RichParameter = {
   displayName: "",
   order: 0,
   ephemeral: false,
+  displayCondition: "",
 };
 
 // firstParameter is mutable string with a default value (parameter value not required).
@@ -69,6 +69,12 @@ export interface RichParameter {
   displayName: string;
   order: number;
   ephemeral: boolean;
+  /**
+   * display_condition is an expression over the values of other parameters,
+   * e.g. `machine == "gpu"`. The parameter is only shown and validated if
+   * the condition is met.
+   */
+  displayCondition: string;
 }
 
 /** RichParameterValue holds the key/value mapping of a parameter. */
@@ -162,6 +168,7 @@ export interface Script {
   runOnStop: boolean;
   timeoutSeconds: number;
   logPath: string;
+  phase: string;
 }
 
 /** App represents a dev-accessible application on the workspace. */
@@ -198,6 +205,11 @@ export interface Resource {
   icon: string;
   instanceType: string;
   dailyCost: number;
+  /**
+   * snapshot is whether the resource is captured by snapshots of the
+   * workspace.
+   */
+  snapshot: boolean;
 }
 
 export interface Resource_Metadata {
@@ -222,6 +234,16 @@ export interface Metadata {
   workspaceOwnerSessionToken: string;
   templateId: string;
   workspaceOwnerName: string;
+  /**
+   * workspace_snapshot_id is set on builds that take a snapshot of the
+   * snapshot-able resources of the workspace.
+   */
+  workspaceSnapshotId: string;
+  /**
+   * workspace_restore_snapshot_id is set on builds that restore the
+   * snapshot-able resources of the workspace from a snapshot.
+   */
+  workspaceRestoreSnapshotId: string;
 }
 
 /** Config represents execution configuration shared by all subsequent requests in the Session */
@@ -257,6 +279,11 @@ export interface PlanComplete {
   resources: Resource[];
   parameters: RichParameter[];
   externalAuthProviders: string[];
+  /**
+   * drifted_resources are the addresses of the resources that Terraform
+   * found to have changed outside of Terraform while refreshing state.
+   */
+  driftedResources: string[];
 }
 
 /**
@@ -401,6 +428,9 @@ export const RichParameter = {
     if (message.ephemeral === true) {
       writer.uint32(136).bool(message.ephemeral);
     }
+    if (message.displayCondition !== "") {
+      writer.uint32(146).string(message.displayCondition);
+    }
     return writer;
   },
 };
@@ -644,6 +674,9 @@ export const Script = {
     if (message.logPath !== "") {
       writer.uint32(74).string(message.logPath);
     }
+    if (message.phase !== "") {
+      writer.uint32(82).string(message.phase);
+    }
     return writer;
   },
 };
@@ -731,6 +764,9 @@ export const Resource = {
     if (message.dailyCost !== 0) {
       writer.uint32(64).int32(message.dailyCost);
     }
+    if (message.snapshot === true) {
+      writer.uint32(72).bool(message.snapshot);
+    }
     return writer;
   },
 };
@@ -800,6 +836,12 @@ export const Metadata = {
     if (message.workspaceOwnerName !== "") {
       writer.uint32(106).string(message.workspaceOwnerName);
     }
+    if (message.workspaceSnapshotId !== "") {
+      writer.uint32(114).string(message.workspaceSnapshotId);
+    }
+    if (message.workspaceRestoreSnapshotId !== "") {
+      writer.uint32(122).string(message.workspaceRestoreSnapshotId);
+    }
     return writer;
   },
 };
@@ -887,6 +929,9 @@ export const PlanComplete = {
     for (const v of message.externalAuthProviders) {
       writer.uint32(34).string(v!);
     }
+    for (const v of message.driftedResources) {
+      writer.uint32(42).string(v!);
+    }
     return writer;
   },
 };
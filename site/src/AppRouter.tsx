@@ -226,6 +226,7 @@ const GroupsPage = lazy(() => import("./pages/GroupsPage/GroupsPage"));
 const IconsPage = lazy(() => import("./pages/IconsPage/IconsPage"));
 const AccessURLPage = lazy(() => import("./pages/HealthPage/AccessURLPage"));
 const DatabasePage = lazy(() => import("./pages/HealthPage/DatabasePage"));
+const PubsubPage = lazy(() => import("./pages/HealthPage/PubsubPage"));
 const DERPPage = lazy(() => import("./pages/HealthPage/DERPPage"));
 const DERPRegionPage = lazy(() => import("./pages/HealthPage/DERPRegionPage"));
 const WebsocketPage = lazy(() => import("./pages/HealthPage/WebsocketPage"));
@@ -390,6 +391,7 @@ export const AppRouter: FC = () => {
                 <Route index element={<Navigate to="access-url" />} />
                 <Route path="access-url" element={<AccessURLPage />} />
                 <Route path="database" element={<DatabasePage />} />
+                <Route path="pubsub" element={<PubsubPage />} />
                 <Route path="derp" element={<DERPPage />} />
                 <Route
                   path="derp/regions/:regionId"
@@ -20,6 +20,9 @@ export interface APIKey {
   readonly scope: APIKeyScope;
   readonly token_name: string;
   readonly lifetime_seconds: number;
+  readonly scopes: APITokenScope[];
+  readonly allowed_workspace_ids: string[];
+  readonly ip_allowlist: string[];
 }
 
 // From codersdk/apikey.go
@@ -27,6 +30,12 @@ export interface APIKeyWithOwner extends APIKey {
   readonly username: string;
 }
 
+// From codersdk/apikey.go
+export interface APITokenScopeDescription {
+  readonly name: APITokenScope;
+  readonly display_name: string;
+}
+
 // From codersdk/licenses.go
 export interface AddLicenseRequest {
   readonly license: string;
@@ -106,15 +115,27 @@ export interface AuditLog {
   readonly user?: User;
 }
 
+// From codersdk/deployment.go
+export interface AuditLogExportConfig {
+  readonly s3_bucket: string;
+  readonly s3_region: string;
+  readonly s3_prefix: string;
+  readonly webhook_url: string;
+  readonly syslog_address: string;
+  readonly buffer_size: number;
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
   readonly audit_logs: AuditLog[];
   readonly count: number;
+  readonly next_cursor?: string;
 }
 
 // From codersdk/audit.go
 export interface AuditLogsRequest extends Pagination {
   readonly q?: string;
+  readonly cursor?: string;
 }
 
 // From codersdk/users.go
@@ -127,6 +148,7 @@ export interface AuthMethods {
   readonly password: AuthMethod;
   readonly github: AuthMethod;
   readonly oidc: OIDCAuthMethod;
+  readonly ldap: AuthMethod;
 }
 
 // From codersdk/authorization.go
@@ -151,11 +173,34 @@ export interface AuthorizationRequest {
 // From codersdk/authorization.go
 export type AuthorizationResponse = Record<string, boolean>;
 
+// From codersdk/workspaceagents.go
+export interface AuthorizeWorkspaceAgentPortForwardRequest {
+  readonly port: number;
+}
+
 // From codersdk/deployment.go
 export interface AvailableExperiments {
   readonly safe: Experiment[];
 }
 
+// From codersdk/workspaces.go
+export interface BatchWorkspaceBuildRequest {
+  readonly workspace_ids: string[];
+  readonly transition: WorkspaceTransition;
+}
+
+// From codersdk/workspaces.go
+export interface BatchWorkspaceBuildResponse {
+  readonly results: BatchWorkspaceBuildResult[];
+}
+
+// From codersdk/workspaces.go
+export interface BatchWorkspaceBuildResult {
+  readonly workspace_id: string;
+  readonly build?: WorkspaceBuild;
+  readonly error?: Response;
+}
+
 // From codersdk/deployment.go
 export interface BuildInfoResponse {
   readonly external_url: string;
@@ -171,12 +216,56 @@ export interface ConnectionLatency {
   readonly p95: number;
 }
 
+// From codersdk/connectionlogs.go
+export interface ConnectionLog {
+  readonly id: string;
+  readonly time: string;
+  readonly organization_id: string;
+  readonly workspace_owner_id: string;
+  readonly workspace_owner_username: string;
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly agent_name: string;
+  readonly type: ConnectionType;
+  readonly port?: number;
+  // Named type "net/netip.Addr" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly ip: any;
+  readonly user_agent: string;
+  readonly user_id: string;
+  readonly username: string;
+  readonly api_key_id: string;
+}
+
+// From codersdk/connectionlogs.go
+export interface ConnectionLogResponse {
+  readonly connection_logs: ConnectionLog[];
+  readonly count: number;
+}
+
+// From codersdk/connectionlogs.go
+export interface ConnectionLogsRequest extends Pagination {
+  readonly q?: string;
+}
+
 // From codersdk/users.go
 export interface ConvertLoginRequest {
   readonly to_type: LoginType;
   readonly password: string;
 }
 
+// From codersdk/workspacecosts.go
+export interface CostTotal {
+  readonly currency: string;
+  readonly total: number;
+}
+
+// From codersdk/workspacecosts.go
+export interface CostsRequest {
+  readonly start_date: string;
+  readonly end_date: string;
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string;
@@ -216,6 +305,25 @@ export interface CreateOrganizationRequest {
   readonly name: string;
 }
 
+// From codersdk/twofactor.go
+export interface CreatePasskeyRequest {
+  readonly challenge_id: string;
+  readonly name: string;
+  readonly client_data_json: string;
+  readonly attestation_object: string;
+}
+
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyRequest {
+  readonly name: string;
+  readonly tags: Record<string, string>;
+}
+
+// From codersdk/provisionerkeys.go
+export interface CreateProvisionerKeyResponse extends ProvisionerKey {
+  readonly key: string;
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string;
@@ -235,6 +343,7 @@ export interface CreateTemplateRequest {
   readonly delete_ttl_ms?: number;
   readonly disable_everyone_group_access: boolean;
   readonly require_active_version: boolean;
+  readonly visibility?: TemplateVisibility;
 }
 
 // From codersdk/templateversions.go
@@ -244,6 +353,12 @@ export interface CreateTemplateVersionDryRunRequest {
   readonly user_variable_values?: VariableValue[];
 }
 
+// From codersdk/templateversionpresets.go
+export interface CreateTemplateVersionPresetRequest {
+  readonly name: string;
+  readonly parameters: WorkspaceBuildParameter[];
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateVersionRequest {
   readonly name?: string;
@@ -254,6 +369,8 @@ export interface CreateTemplateVersionRequest {
   readonly example_id?: string;
   readonly provisioner: ProvisionerType;
   readonly tags: Record<string, string>;
+  readonly provisioner_requirements?: string[];
+  readonly git?: TemplateVersionGitMetadata;
   readonly user_variable_values?: VariableValue[];
 }
 
@@ -272,6 +389,9 @@ export interface CreateTokenRequest {
   readonly lifetime: number;
   readonly scope: APIKeyScope;
   readonly token_name: string;
+  readonly scopes?: APITokenScope[];
+  readonly allowed_workspace_ids?: string[];
+  readonly ip_allowlist?: string[];
 }
 
 // From codersdk/users.go
@@ -284,6 +404,12 @@ export interface CreateUserRequest {
   readonly organization_id: string;
 }
 
+// From codersdk/webhooks.go
+export interface CreateWebhookRequest {
+  readonly url: string;
+  readonly events: WebhookEvent[];
+}
+
 // From codersdk/workspaces.go
 export interface CreateWorkspaceBuildRequest {
   readonly template_version_id?: string;
@@ -311,6 +437,20 @@ export interface CreateWorkspaceRequest {
   readonly ttl_ms?: number;
   readonly rich_parameter_values?: WorkspaceBuildParameter[];
   readonly automatic_updates?: AutomaticUpdates;
+  readonly preset?: string;
+  readonly ephemeral?: boolean;
+}
+
+// From codersdk/workspacesnapshots.go
+export interface CreateWorkspaceSnapshotRequest {
+  readonly name?: string;
+}
+
+// From codersdk/roles.go
+export interface CustomRole {
+  readonly name: string;
+  readonly display_name: string;
+  readonly site_permissions: Permission[];
 }
 
 // From codersdk/deployment.go
@@ -342,6 +482,8 @@ export interface DERPConfig {
   readonly force_websockets: boolean;
   readonly url: string;
   readonly path: string;
+  readonly health_check_interval: number;
+  readonly node_key_rotation_interval: number;
 }
 
 // From codersdk/workspaceagents.go
@@ -367,6 +509,12 @@ export interface DangerousConfig {
   readonly allow_all_cors: boolean;
 }
 
+// From codersdk/workspaceagentportshares.go
+export interface DeleteWorkspaceAgentPortShareRequest {
+  readonly agent_name: string;
+  readonly port: number;
+}
+
 // From codersdk/deployment.go
 export interface DeploymentConfig {
   readonly config?: DeploymentValues;
@@ -394,14 +542,20 @@ export interface DeploymentValues {
   readonly job_hang_detector_interval?: number;
   readonly derp?: DERP;
   readonly prometheus?: PrometheusConfig;
+  readonly metrics_export?: MetricsExportConfig;
   readonly pprof?: PprofConfig;
   readonly proxy_trusted_headers?: string[];
   readonly proxy_trusted_origins?: string[];
   readonly cache_directory?: string;
   readonly in_memory_database?: boolean;
   readonly pg_connection_url?: string;
+  readonly pg_replica_connection_urls?: string[];
+  readonly pg_replica_max_lag?: number;
+  readonly pubsub_backend?: string;
+  readonly pubsub_url?: string;
   readonly oauth2?: OAuth2Config;
   readonly oidc?: OIDCConfig;
+  readonly ldap?: LDAPConfig;
   readonly telemetry?: TelemetryConfig;
   readonly tls?: TLSConfig;
   readonly trace?: TraceConfig;
@@ -412,11 +566,16 @@ export interface DeploymentValues {
   readonly metrics_cache_refresh_interval?: number;
   readonly agent_stat_refresh_interval?: number;
   readonly agent_fallback_troubleshooting_url?: string;
+  readonly agent_metadata_history?: number;
+  readonly agent_quic_address?: string;
   readonly browser_only?: boolean;
   readonly scim_api_key?: string;
   readonly external_token_encryption_keys?: string[];
+  readonly external_token_encryption_kms_url?: string;
+  readonly external_token_encryption_kms_token?: string;
   readonly provisioner?: ProvisionerConfig;
   readonly rate_limit?: RateLimitConfig;
+  readonly login_throttle?: LoginThrottleConfig;
   readonly experiments?: string[];
   readonly update_check?: boolean;
   readonly max_token_lifetime?: number;
@@ -426,6 +585,11 @@ export interface DeploymentValues {
   readonly disable_path_apps?: boolean;
   readonly max_session_expiry?: number;
   readonly disable_session_expiry_refresh?: boolean;
+  readonly owner_max_token_lifetime?: number;
+  readonly owner_max_session_expiry?: number;
+  readonly owner_disable_session_expiry_refresh?: boolean;
+  readonly truncate_token_lifetimes?: boolean;
+  readonly max_concurrent_sessions?: number;
   readonly disable_password_auth?: boolean;
   readonly support?: SupportConfig;
   readonly external_auth?: ExternalAuthConfig[];
@@ -438,6 +602,13 @@ export interface DeploymentValues {
   readonly web_terminal_renderer?: string;
   readonly allow_workspace_renames?: boolean;
   readonly healthcheck?: HealthcheckConfig;
+  readonly audit_log_export?: AuditLogExportConfig;
+  readonly examples_registry_url?: string;
+  readonly session_recording_storage_url?: string;
+  readonly license_grace_period?: number;
+  readonly suspend_inactive_users_after?: number;
+  readonly notifications?: NotificationsConfig;
+  readonly webhooks_allow_private_addresses?: boolean;
   readonly config?: string;
   readonly write_config?: boolean;
   readonly address?: string;
@@ -452,6 +623,7 @@ export interface Entitlements {
   readonly trial: boolean;
   readonly require_telemetry: boolean;
   readonly refreshed_at: string;
+  readonly read_only_until?: string;
 }
 
 // From codersdk/deployment.go
@@ -552,6 +724,11 @@ export interface GenerateAPIKeyResponse {
   readonly key: string;
 }
 
+// From codersdk/templates.go
+export interface GenerateWorkspaceNameResponse {
+  readonly name: string;
+}
+
 // From codersdk/users.go
 export interface GetUsersResponse {
   readonly users: User[];
@@ -578,6 +755,26 @@ export interface Group {
   readonly source: GroupSource;
 }
 
+// From codersdk/groupsync.go
+export interface GroupSyncRun {
+  readonly id: string;
+  readonly trigger: GroupSyncTrigger;
+  readonly user_id?: string;
+  readonly started_at: string;
+  readonly finished_at: string;
+  readonly users_synced: number;
+  readonly users_failed: number;
+  readonly idp_groups: string[];
+  readonly groups: string[];
+  readonly error?: string;
+}
+
+// From codersdk/groupsync.go
+export interface GroupSyncRunsRequest {
+  readonly user_id?: string;
+  readonly limit?: number;
+}
+
 // From codersdk/health.go
 export interface HealthSettings {
   readonly dismissed_healthchecks: HealthSection[];
@@ -596,6 +793,51 @@ export interface HealthcheckConfig {
   readonly threshold_database: number;
 }
 
+// From codersdk/users.go
+export interface InactiveUser {
+  readonly id: string;
+  readonly username: string;
+  readonly email: string;
+  readonly avatar_url: string;
+  readonly status: UserStatus;
+  readonly last_seen_at: string;
+  readonly last_activity?: UserAPIActivity;
+}
+
+// From codersdk/users.go
+export interface InactiveUsersResponse {
+  readonly inactive_since: string;
+  readonly users: InactiveUser[];
+}
+
+// From codersdk/inbox.go
+export interface InboxNotification {
+  readonly id: string;
+  readonly user_id: string;
+  readonly template: NotificationTemplate;
+  readonly title: string;
+  readonly content: string;
+  readonly created_at: string;
+  readonly read_at?: string;
+}
+
+// From codersdk/inbox.go
+export interface InboxNotificationEvent {
+  readonly notification: InboxNotification;
+  readonly unread_count: number;
+}
+
+// From codersdk/inbox.go
+export interface InboxNotificationsRequest extends Pagination {
+  readonly unread_only: boolean;
+}
+
+// From codersdk/inbox.go
+export interface InboxNotificationsResponse {
+  readonly notifications: InboxNotification[];
+  readonly unread_count: number;
+}
+
 // From codersdk/workspaceagents.go
 export interface IssueReconnectingPTYSignedTokenRequest {
   readonly url: string;
@@ -607,6 +849,24 @@ export interface IssueReconnectingPTYSignedTokenResponse {
   readonly signed_token: string;
 }
 
+// From codersdk/deployment.go
+export interface LDAPConfig {
+  readonly url: string;
+  readonly start_tls: boolean;
+  readonly ca_file: string;
+  readonly bind_dn: string;
+  readonly bind_password: string;
+  readonly user_search_base: string;
+  readonly user_filter: string;
+  readonly username_attribute: string;
+  readonly email_attribute: string;
+  readonly groups_attribute: string;
+  readonly group_auto_create: boolean;
+  readonly allow_signups: boolean;
+  readonly pool_size: number;
+  readonly sync_interval: number;
+}
+
 // From codersdk/licenses.go
 export interface License {
   readonly id: number;
@@ -638,6 +898,20 @@ export interface LoggingConfig {
   readonly stackdriver: string;
 }
 
+// From codersdk/deployment.go
+export interface LoginThrottleConfig {
+  readonly backoff_threshold: number;
+  readonly backoff_max: number;
+  readonly lockout_threshold: number;
+  readonly lockout_duration: number;
+}
+
+// From codersdk/users.go
+export interface LoginWithLDAPRequest {
+  readonly username: string;
+  readonly password: string;
+}
+
 // From codersdk/users.go
 export interface LoginWithPasswordRequest {
   readonly email: string;
@@ -647,6 +921,28 @@ export interface LoginWithPasswordRequest {
 // From codersdk/users.go
 export interface LoginWithPasswordResponse {
   readonly session_token: string;
+  readonly second_factor?: SecondFactorChallenge;
+  readonly passkey_enrollment?: PasskeyRegistration;
+}
+
+// From codersdk/twofactor.go
+export interface LoginWithSecondFactorRequest {
+  readonly challenge_id: string;
+  readonly passkey?: PasskeyAssertion;
+  readonly recovery_code?: string;
+}
+
+// From codersdk/inbox.go
+export interface MarkInboxNotificationsReadRequest {
+  readonly ids: string[];
+}
+
+// From codersdk/deployment.go
+export interface MetricsExportConfig {
+  readonly statsd_address: string;
+  readonly otlp_endpoint: string;
+  readonly interval: number;
+  readonly batch_size: number;
 }
 
 // From codersdk/users.go
@@ -656,6 +952,49 @@ export interface MinimalUser {
   readonly avatar_url: string;
 }
 
+// From codersdk/notifications.go
+export interface NotificationMessage {
+  readonly id: string;
+  readonly user_id: string;
+  readonly template: NotificationTemplate;
+  readonly method: NotificationMethod;
+  readonly labels: Record<string, string>;
+  readonly status: NotificationMessageStatus;
+  readonly attempts: number;
+  readonly last_error: string;
+  readonly created_at: string;
+  readonly sent_at?: string;
+}
+
+// From codersdk/notifications.go
+export interface NotificationPreference {
+  readonly template: NotificationTemplate;
+  readonly disabled: boolean;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsConfig {
+  readonly max_send_attempts: number;
+  readonly retry_interval: number;
+  readonly email: NotificationsEmailConfig;
+  readonly slack: NotificationsWebhookConfig;
+  readonly teams: NotificationsWebhookConfig;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsEmailConfig {
+  readonly from: string;
+  readonly smarthost: string;
+  readonly username: string;
+  readonly password: string;
+}
+
+// From codersdk/deployment.go
+export interface NotificationsWebhookConfig {
+  readonly webhook_url: string;
+  readonly templates: string[];
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig;
@@ -730,10 +1069,50 @@ export interface OIDCConfig {
   readonly user_role_field: string;
   readonly user_role_mapping: Record<string, string[]>;
   readonly user_roles_default: string[];
+  readonly group_organization_role_mapping: Record<string, string[]>;
+  readonly group_prefix_strip: string[];
+  readonly group_sync_interval: number;
+  readonly nested_groups_max_depth: number;
+  readonly nested_groups_graph_tenant_id: string;
+  readonly nested_groups_graph_client_id: string;
+  readonly nested_groups_graph_client_secret: string;
   readonly sign_in_text: string;
   readonly icon_url: string;
 }
 
+// From codersdk/offboarding.go
+export interface OffboardUserRequest {
+  readonly workspace_action: OffboardWorkspaceAction;
+  readonly transfer_to?: string;
+  readonly dry_run?: boolean;
+}
+
+// From codersdk/offboarding.go
+export interface OffboardUserResponse {
+  readonly dry_run: boolean;
+  readonly workspace_action: OffboardWorkspaceAction;
+  readonly user: User;
+  readonly workspaces: OffboardedWorkspace[];
+  readonly revoked_api_key_ids: string[];
+  readonly revoked_external_auth_providers: string[];
+  readonly removed_groups: OffboardedGroup[];
+}
+
+// From codersdk/offboarding.go
+export interface OffboardedGroup {
+  readonly id: string;
+  readonly name: string;
+  readonly organization_id: string;
+}
+
+// From codersdk/offboarding.go
+export interface OffboardedWorkspace {
+  readonly id: string;
+  readonly name: string;
+  readonly organization_id: string;
+  readonly new_owner_id?: string;
+}
+
 // From codersdk/organizations.go
 export interface Organization {
   readonly id: string;
@@ -742,6 +1121,24 @@ export interface Organization {
   readonly updated_at: string;
 }
 
+// From codersdk/workspacecosts.go
+export interface OrganizationCostRollup {
+  readonly owner_id: string;
+  readonly template_id: string;
+  readonly currency: string;
+  readonly workspace_count: number;
+  readonly total_cost: number;
+}
+
+// From codersdk/workspacecosts.go
+export interface OrganizationCosts {
+  readonly organization_id: string;
+  readonly start_date: string;
+  readonly end_date: string;
+  readonly rollups: OrganizationCostRollup[];
+  readonly totals: CostTotal[];
+}
+
 // From codersdk/organizations.go
 export interface OrganizationMember {
   readonly user_id: string;
@@ -751,6 +1148,17 @@ export interface OrganizationMember {
   readonly roles: Role[];
 }
 
+// From codersdk/organizations.go
+export interface OrganizationMemberWithName extends OrganizationMember {
+  readonly username: string;
+}
+
+// From codersdk/workspaces.go
+export interface OrganizationQuota {
+  readonly max_running_workspaces_per_user: number;
+  readonly max_daily_cost: number;
+}
+
 // From codersdk/pagination.go
 export interface Pagination {
   readonly after_id?: string;
@@ -758,6 +1166,82 @@ export interface Pagination {
   readonly offset?: number;
 }
 
+// From codersdk/twofactor.go
+export interface Passkey {
+  readonly id: string;
+  readonly user_id: string;
+  readonly name: string;
+  readonly created_at: string;
+  readonly last_used_at?: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyAssertion {
+  readonly credential_id: string;
+  readonly client_data_json: string;
+  readonly authenticator_data: string;
+  readonly signature: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyAuthenticatorSelection {
+  readonly residentKey: string;
+  readonly userVerification: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyCreationOptions {
+  readonly challenge: string;
+  readonly rp: PasskeyRelyingParty;
+  readonly user: PasskeyUser;
+  readonly pubKeyCredParams: PasskeyCredentialParameter[];
+  readonly timeout: number;
+  readonly excludeCredentials: PasskeyCredentialDescriptor[];
+  readonly authenticatorSelection: PasskeyAuthenticatorSelection;
+  readonly attestation: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyCredentialDescriptor {
+  readonly type: string;
+  readonly id: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyCredentialParameter {
+  readonly type: string;
+  readonly alg: number;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyRegistration {
+  readonly challenge_id: string;
+  readonly expires_at: string;
+  readonly options: PasskeyCreationOptions;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyRelyingParty {
+  readonly id: string;
+  readonly name: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyRequestOptions {
+  readonly challenge: string;
+  readonly rpId: string;
+  readonly timeout: number;
+  readonly allowCredentials: PasskeyCredentialDescriptor[];
+  readonly userVerification: string;
+}
+
+// From codersdk/twofactor.go
+export interface PasskeyUser {
+  readonly id: string;
+  readonly name: string;
+  readonly displayName: string;
+}
+
 // From codersdk/groups.go
 export interface PatchGroupRequest {
   readonly add_users: string[];
@@ -783,6 +1267,13 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean;
 }
 
+// From codersdk/roles.go
+export interface Permission {
+  readonly negate: boolean;
+  readonly resource_type: RBACResource;
+  readonly action: string;
+}
+
 // From codersdk/oauth2.go
 export interface PostOAuth2ProviderAppRequest {
   readonly name: string;
@@ -812,6 +1303,10 @@ export interface ProvisionerConfig {
   readonly daemon_poll_jitter: number;
   readonly force_cancel_interval: number;
   readonly daemon_psk: string;
+  readonly job_log_archive_age: number;
+  readonly drift_detection_interval: number;
+  readonly shared_cache: boolean;
+  readonly shared_cache_s3_bucket: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -824,6 +1319,7 @@ export interface ProvisionerDaemon {
   readonly api_version: string;
   readonly provisioners: ProvisionerType[];
   readonly tags: Record<string, string>;
+  readonly organization_id?: string;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -841,6 +1337,7 @@ export interface ProvisionerJob {
   readonly tags: Record<string, string>;
   readonly queue_position: number;
   readonly queue_size: number;
+  readonly priority: number;
 }
 
 // From codersdk/provisionerdaemons.go
@@ -853,12 +1350,28 @@ export interface ProvisionerJobLog {
   readonly output: string;
 }
 
+// From codersdk/provisionerkeys.go
+export interface ProvisionerKey {
+  readonly id: string;
+  readonly created_at: string;
+  readonly organization_id: string;
+  readonly name: string;
+  readonly tags: Record<string, string>;
+  readonly rotated_at?: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface ProxyHealthReport {
   readonly errors: string[];
   readonly warnings: string[];
 }
 
+// From codersdk/provisionerdaemons.go
+export interface PurgeProvisionerCacheResponse {
+  readonly deleted_objects: number;
+  readonly deleted_bytes: number;
+}
+
 // From codersdk/workspaces.go
 export interface PutExtendWorkspaceRequest {
   readonly deadline: string;
@@ -875,6 +1388,14 @@ export interface PutOAuth2ProviderAppRequest {
 export interface RateLimitConfig {
   readonly disable_all: boolean;
   readonly api: number;
+  readonly shared: boolean;
+  readonly endpoints: string[];
+  readonly users: string[];
+}
+
+// From codersdk/twofactor.go
+export interface RecoveryCodes {
+  readonly codes: string[];
 }
 
 // From codersdk/workspaceproxy.go
@@ -934,6 +1455,13 @@ export interface SSHConfigResponse {
   readonly ssh_config_options: Record<string, string>;
 }
 
+// From codersdk/twofactor.go
+export interface SecondFactorChallenge {
+  readonly challenge_id: string;
+  readonly expires_at: string;
+  readonly options: PasskeyRequestOptions;
+}
+
 // From codersdk/serversentevents.go
 export interface ServerSentEvent {
   readonly type: ServerSentEventType;
@@ -957,6 +1485,20 @@ export interface SessionCountDeploymentStats {
   readonly reconnecting_pty: number;
 }
 
+// From codersdk/sessionrecordings.go
+export interface SessionRecording {
+  readonly id: string;
+  readonly organization_id: string;
+  readonly template_id: string;
+  readonly workspace_id: string;
+  readonly agent_id: string;
+  readonly user_id: string;
+  readonly started_at: string;
+  readonly ended_at: string;
+  readonly size_bytes: number;
+  readonly expires_at?: string;
+}
+
 // From codersdk/deployment.go
 export interface SupportConfig {
   readonly links: LinkConfig[];
@@ -1020,6 +1562,14 @@ export interface Template {
   readonly time_til_dormant_ms: number;
   readonly time_til_dormant_autodelete_ms: number;
   readonly require_active_version: boolean;
+  readonly maintenance_window: TemplateMaintenanceWindow;
+  readonly visibility: TemplateVisibility;
+  readonly max_build_duration_ms: number;
+  readonly workspace_name_policy: TemplateWorkspaceNamePolicy;
+  readonly max_running_workspaces: number;
+  readonly port_forwarding_policy: TemplatePortForwardingPolicy;
+  readonly session_recording: TemplateSessionRecording;
+  readonly build_retry_policy: TemplateBuildRetryPolicy;
 }
 
 // From codersdk/templates.go
@@ -1036,6 +1586,9 @@ export interface TemplateAppUsage {
   readonly slug: string;
   readonly icon: string;
   readonly seconds: number;
+  readonly requests?: number;
+  readonly active_users?: number;
+  readonly last_used_at?: string;
 }
 
 // From codersdk/templates.go
@@ -1049,6 +1602,20 @@ export interface TemplateAutostopRequirement {
   readonly weeks: number;
 }
 
+// From codersdk/insights.go
+export interface TemplateBuildInsights {
+  readonly total_builds: number;
+  readonly failed_builds: number;
+  readonly success_rate: number;
+  readonly build_time: TransitionStats;
+}
+
+// From codersdk/templates.go
+export interface TemplateBuildRetryPolicy {
+  readonly max_attempts: number;
+  readonly backoff_ms: number;
+}
+
 // From codersdk/templates.go
 export type TemplateBuildTimeStats = Record<
   WorkspaceTransition,
@@ -1105,6 +1672,12 @@ export interface TemplateInsightsResponse {
   readonly interval_reports?: TemplateInsightsIntervalReport[];
 }
 
+// From codersdk/templates.go
+export interface TemplateMaintenanceWindow {
+  readonly schedule: string;
+  readonly duration_ms: number;
+}
+
 // From codersdk/insights.go
 export interface TemplateParameterUsage {
   readonly template_ids: string[];
@@ -1122,11 +1695,47 @@ export interface TemplateParameterValue {
   readonly count: number;
 }
 
+// From codersdk/templates.go
+export interface TemplatePortForwardingPolicy {
+  readonly deny_by_default: boolean;
+  readonly allowed_ports: number[];
+}
+
+// From codersdk/templates.go
+export interface TemplateSessionRecording {
+  readonly enabled: boolean;
+  readonly retention_ms: number;
+}
+
+// From codersdk/insights.go
+export interface TemplateSummaryInsightsRequest {
+  readonly start_time: string;
+  readonly end_time: string;
+}
+
+// From codersdk/insights.go
+export interface TemplateSummaryInsightsResponse {
+  readonly template_id: string;
+  readonly start_time: string;
+  readonly end_time: string;
+  readonly builds: TemplateBuildInsights;
+  readonly daily_active_users: TemplateInsightsIntervalReport[];
+  readonly parameters_usage: TemplateParameterUsage[];
+}
+
 // From codersdk/templates.go
 export interface TemplateUser extends User {
   readonly role: TemplateRole;
 }
 
+// From codersdk/templatevariables.go
+export interface TemplateVariableValue {
+  readonly name: string;
+  readonly value: string;
+  readonly sensitive: boolean;
+  readonly updated_at: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersion {
   readonly id: string;
@@ -1140,9 +1749,21 @@ export interface TemplateVersion {
   readonly readme: string;
   readonly created_by: MinimalUser;
   readonly archived: boolean;
+  readonly git?: TemplateVersionGitMetadata;
   readonly warnings?: TemplateVersionWarning[];
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionDiff {
+  readonly base_version_id: string;
+  readonly target_version_id: string;
+  readonly files: TemplateVersionFileDiff[];
+  readonly added_parameters: TemplateVersionParameter[];
+  readonly removed_parameters: TemplateVersionParameter[];
+  readonly changed_parameters: TemplateVersionParameterChange[];
+  readonly metadata: TemplateVersionMetadataChange[];
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionExternalAuth {
   readonly id: string;
@@ -1153,6 +1774,28 @@ export interface TemplateVersionExternalAuth {
   readonly authenticated: boolean;
 }
 
+// From codersdk/templateversions.go
+export interface TemplateVersionFileDiff {
+  readonly path: string;
+  readonly status: TemplateVersionFileStatus;
+  readonly binary: boolean;
+  readonly diff: string;
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionGitMetadata {
+  readonly commit_sha: string;
+  readonly branch?: string;
+  readonly tag?: string;
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionMetadataChange {
+  readonly field: string;
+  readonly old: string;
+  readonly new: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionParameter {
   readonly name: string;
@@ -1171,6 +1814,14 @@ export interface TemplateVersionParameter {
   readonly validation_monotonic?: ValidationMonotonicOrder;
   readonly required: boolean;
   readonly ephemeral: boolean;
+  readonly display_condition?: string;
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionParameterChange {
+  readonly name: string;
+  readonly old: TemplateVersionParameter;
+  readonly new: TemplateVersionParameter;
 }
 
 // From codersdk/templateversions.go
@@ -1181,6 +1832,13 @@ export interface TemplateVersionParameterOption {
   readonly icon: string;
 }
 
+// From codersdk/templateversionpresets.go
+export interface TemplateVersionPreset {
+  readonly name: string;
+  readonly parameters: WorkspaceBuildParameter[];
+  readonly created_at: string;
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionVariable {
   readonly name: string;
@@ -1198,9 +1856,16 @@ export interface TemplateVersionsByTemplateRequest extends Pagination {
   readonly include_archived: boolean;
 }
 
+// From codersdk/templates.go
+export interface TemplateWorkspaceNamePolicy {
+  readonly pattern: string;
+  readonly description: string;
+}
+
 // From codersdk/apikey.go
 export interface TokenConfig {
   readonly max_token_lifetime: number;
+  readonly scopes: APITokenScopeDescription[];
 }
 
 // From codersdk/apikey.go
@@ -1222,6 +1887,15 @@ export interface TransitionStats {
   readonly P95?: number;
 }
 
+// From codersdk/twofactor.go
+export interface TwoFactorPolicy {
+  readonly id: string;
+  readonly role: string;
+  readonly enforce_after: string;
+  readonly created_at: string;
+  readonly updated_at: string;
+}
+
 // From codersdk/templates.go
 export interface UpdateActiveTemplateVersion {
   readonly id: string;
@@ -1246,6 +1920,16 @@ export interface UpdateHealthSettings {
   readonly dismissed_healthchecks: HealthSection[];
 }
 
+// From codersdk/notifications.go
+export interface UpdateNotificationPreferencesRequest {
+  readonly preferences: NotificationPreference[];
+}
+
+// From codersdk/provisionerdaemons.go
+export interface UpdateProvisionerJobPriorityRequest {
+  readonly priority: number;
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[];
@@ -1278,6 +1962,14 @@ export interface UpdateTemplateMeta {
   readonly require_active_version: boolean;
   readonly deprecation_message?: string;
   readonly disable_everyone_group_access: boolean;
+  readonly maintenance_window?: TemplateMaintenanceWindow;
+  readonly visibility?: TemplateVisibility;
+  readonly max_build_duration_ms?: number;
+  readonly workspace_name_policy?: TemplateWorkspaceNamePolicy;
+  readonly max_running_workspaces?: number;
+  readonly port_forwarding_policy?: TemplatePortForwardingPolicy;
+  readonly session_recording?: TemplateSessionRecording;
+  readonly build_retry_policy?: TemplateBuildRetryPolicy;
 }
 
 // From codersdk/users.go
@@ -1285,6 +1977,11 @@ export interface UpdateUserAppearanceSettingsRequest {
   readonly theme_preference: string;
 }
 
+// From codersdk/users.go
+export interface UpdateUserIPAllowlistRequest {
+  readonly ip_allowlist: string[];
+}
+
 // From codersdk/users.go
 export interface UpdateUserPasswordRequest {
   readonly old_password: string;
@@ -1302,6 +1999,11 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceACL {
+  readonly user_roles?: Record<string, WorkspaceRole>;
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutomaticUpdatesRequest {
   readonly automatic_updates: AutomaticUpdates;
@@ -1317,6 +2019,16 @@ export interface UpdateWorkspaceDormancy {
   readonly dormant: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceDormancyExemptRequest {
+  readonly exempt: boolean;
+}
+
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceMaintenanceOptOutRequest {
+  readonly opt_out: boolean;
+}
+
 // From codersdk/workspaceproxy.go
 export interface UpdateWorkspaceProxyResponse {
   readonly proxy: WorkspaceProxy;
@@ -1338,6 +2050,24 @@ export interface UploadResponse {
   readonly hash: string;
 }
 
+// From codersdk/templatevariables.go
+export interface UpsertTemplateVariableValueRequest {
+  readonly value: string;
+  readonly sensitive: boolean;
+}
+
+// From codersdk/twofactor.go
+export interface UpsertTwoFactorPolicyRequest {
+  readonly enforce_after: string;
+}
+
+// From codersdk/workspaceagentportshares.go
+export interface UpsertWorkspaceAgentPortShareRequest {
+  readonly agent_name: string;
+  readonly port: number;
+  readonly share_level: WorkspaceAgentPortShareLevel;
+}
+
 // From codersdk/users.go
 export interface User {
   readonly id: string;
@@ -1354,6 +2084,13 @@ export interface User {
   readonly theme_preference: string;
 }
 
+// From codersdk/users.go
+export interface UserAPIActivity {
+  readonly category: string;
+  readonly workspace_id?: string;
+  readonly hour: string;
+}
+
 // From codersdk/insights.go
 export interface UserActivity {
   readonly template_ids: string[];
@@ -1383,6 +2120,11 @@ export interface UserActivityInsightsResponse {
   readonly report: UserActivityInsightsReport;
 }
 
+// From codersdk/users.go
+export interface UserIPAllowlist {
+  readonly ip_allowlist: string[];
+}
+
 // From codersdk/insights.go
 export interface UserLatency {
   readonly template_ids: string[];
@@ -1412,6 +2154,15 @@ export interface UserLatencyInsightsResponse {
   readonly report: UserLatencyInsightsReport;
 }
 
+// From codersdk/users.go
+export interface UserLockout {
+  readonly user_id: string;
+  readonly username: string;
+  readonly failed_attempts: number;
+  readonly locked_at: string;
+  readonly locked_until: string;
+}
+
 // From codersdk/users.go
 export interface UserLoginType {
   readonly login_type: LoginType;
@@ -1439,6 +2190,14 @@ export interface UserRoles {
   readonly organization_roles: Record<string, string[]>;
 }
 
+// From codersdk/twofactor.go
+export interface UserTwoFactor {
+  readonly enabled: boolean;
+  readonly required: boolean;
+  readonly enforce_after?: string;
+  readonly recovery_codes_remaining: number;
+}
+
 // From codersdk/users.go
 export interface UsersRequest extends Pagination {
   readonly q?: string;
@@ -1456,6 +2215,85 @@ export interface VariableValue {
   readonly value: string;
 }
 
+// From codersdk/webhooks.go
+export interface Webhook {
+  readonly id: string;
+  readonly url: string;
+  readonly events: WebhookEvent[];
+  readonly secret?: string;
+  readonly created_at: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookDelivery {
+  readonly id: string;
+  readonly webhook_id: string;
+  readonly event: WebhookEvent;
+  readonly status: WebhookDeliveryStatus;
+  readonly attempts: number;
+  readonly status_code: number;
+  readonly last_error: string;
+  readonly created_at: string;
+  readonly delivered_at?: string;
+  readonly next_attempt_at?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookPayload {
+  readonly id: string;
+  readonly event: WebhookEvent;
+  readonly timestamp: string;
+  readonly data: Record<string, string>;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookTemplateData {
+  readonly id: string;
+  readonly organization_id: string;
+  readonly name: string;
+  readonly active_version_id: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookUserData {
+  readonly id: string;
+  readonly username: string;
+  readonly email: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceBuildData {
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_id: string;
+  readonly template_id: string;
+  readonly build_id: string;
+  readonly build_number: number;
+  readonly transition: WorkspaceTransition;
+  readonly error?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceDormantData {
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_id: string;
+  readonly owner_username: string;
+  readonly owner_email: string;
+  readonly template_id: string;
+  readonly dormant_at: string;
+  readonly deleting_at?: string;
+}
+
+// From codersdk/webhooks.go
+export interface WebhookWorkspaceMaintenanceData {
+  readonly workspace_id: string;
+  readonly workspace_name: string;
+  readonly owner_id: string;
+  readonly template_id: string;
+  readonly active_version_id: string;
+}
+
 // From codersdk/workspaces.go
 export interface Workspace {
   readonly id: string;
@@ -1482,6 +2320,15 @@ export interface Workspace {
   readonly health: WorkspaceHealth;
   readonly automatic_updates: AutomaticUpdates;
   readonly allow_renames: boolean;
+  readonly favorite: boolean;
+  readonly maintenance_opt_out: boolean;
+  readonly ephemeral: boolean;
+  readonly dormancy_exempt: boolean;
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceACL {
+  readonly users: WorkspaceUser[];
 }
 
 // From codersdk/workspaceagents.go
@@ -1517,15 +2364,57 @@ export interface WorkspaceAgent {
   readonly display_apps: DisplayApp[];
   readonly log_sources: WorkspaceAgentLogSource[];
   readonly scripts: WorkspaceAgentScript[];
+  readonly discovered_apps: WorkspaceAgentDiscoveredApp[];
+  readonly gpus: WorkspaceAgentGPU[];
   readonly startup_script_behavior: WorkspaceAgentStartupScriptBehavior;
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentDERPLatency {
+  readonly region_id: number;
+  readonly region_name: string;
+  readonly latency_ms: number;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentDiscoveredApp {
+  readonly port: number;
+  readonly process_name: string;
+  readonly discovered_at: string;
+  readonly subdomain_name: string;
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentFile {
+  readonly name: string;
+  readonly path: string;
+  readonly size: number;
+  readonly mode: string;
+  readonly is_dir: boolean;
+  readonly modified_at: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentGPU {
+  readonly index: number;
+  readonly vendor: string;
+  readonly model: string;
+  readonly memory_bytes: number;
+  readonly driver_version: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentHealth {
   readonly healthy: boolean;
   readonly reason?: string;
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentListFilesResponse {
+  readonly path: string;
+  readonly files: WorkspaceAgentFile[];
+}
+
 // From codersdk/workspaceagentconn.go
 export interface WorkspaceAgentListeningPort {
   readonly process_name: string;
@@ -1571,6 +2460,25 @@ export interface WorkspaceAgentMetadataDescription {
   readonly timeout: number;
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistory {
+  readonly key: string;
+  readonly values: readonly WorkspaceAgentMetadataHistoryValue[];
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistoryRequest {
+  readonly keys?: readonly string[];
+  readonly since?: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistoryValue {
+  readonly collected_at: string;
+  readonly value: string;
+  readonly error: string;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadataResult {
   readonly collected_at: string;
@@ -1579,6 +2487,49 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string;
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentNetcheckResponse {
+  // Named type "tailscale.com/net/netcheck.Report" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly netcheck?: any;
+  readonly netcheck_err?: string;
+  readonly netcheck_logs: string[];
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentPingStats {
+  readonly sent: number;
+  readonly received: number;
+  readonly packet_loss: number;
+  readonly min_latency_ms: number;
+  readonly avg_latency_ms: number;
+  readonly max_latency_ms: number;
+  readonly p2p: boolean;
+  readonly endpoint?: string;
+  readonly derp_region_id?: number;
+  readonly derp_latencies: WorkspaceAgentDERPLatency[];
+}
+
+// From codersdk/workspaceagentportshares.go
+export interface WorkspaceAgentPortShare {
+  readonly workspace_id: string;
+  readonly agent_name: string;
+  readonly port: number;
+  readonly share_level: WorkspaceAgentPortShareLevel;
+  readonly subdomain_name: string;
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentResourceUsage {
+  readonly collected_at?: string;
+  readonly cpu_used_cores: number;
+  readonly cpu_total_cores: number;
+  readonly memory_used_bytes: number;
+  readonly memory_total_bytes: number;
+  readonly disk_used_bytes: number;
+  readonly disk_total_bytes: number;
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentScript {
   readonly log_source_id: string;
@@ -1589,6 +2540,7 @@ export interface WorkspaceAgentScript {
   readonly run_on_stop: boolean;
   readonly start_blocks_login: boolean;
   readonly timeout: number;
+  readonly phase: WorkspaceAgentScriptPhase;
 }
 
 // From codersdk/workspaceapps.go
@@ -1629,6 +2581,24 @@ export interface WorkspaceBuild {
   readonly max_deadline?: string;
   readonly status: WorkspaceStatus;
   readonly daily_cost: number;
+  readonly attempt: number;
+  readonly retry_at?: string;
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildDryRunResource {
+  readonly type: string;
+  readonly name: string;
+  readonly daily_cost: number;
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildDryRunResources {
+  readonly added: WorkspaceBuildDryRunResource[];
+  readonly removed: WorkspaceBuildDryRunResource[];
+  readonly unchanged: WorkspaceBuildDryRunResource[];
+  readonly daily_cost: number;
+  readonly daily_cost_change: number;
 }
 
 // From codersdk/workspacebuilds.go
@@ -1640,6 +2610,7 @@ export interface WorkspaceBuildParameter {
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly since?: string;
+  readonly reason?: BuildReason;
 }
 
 // From codersdk/deployment.go
@@ -1648,6 +2619,15 @@ export interface WorkspaceConnectionLatencyMS {
   readonly P95: number;
 }
 
+// From codersdk/workspacecosts.go
+export interface WorkspaceCosts {
+  readonly workspace_id: string;
+  readonly start_date: string;
+  readonly end_date: string;
+  readonly resources: WorkspaceResourceCost[];
+  readonly totals: CostTotal[];
+}
+
 // From codersdk/deployment.go
 export interface WorkspaceDeploymentStats {
   readonly pending: number;
@@ -1660,6 +2640,15 @@ export interface WorkspaceDeploymentStats {
   readonly tx_bytes: number;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceDrift {
+  readonly workspace_id: string;
+  readonly job_id: string;
+  readonly build_id?: string;
+  readonly checked_at?: string;
+  readonly drifted_resources: string[];
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceFilter {
   readonly q?: string;
@@ -1676,6 +2665,18 @@ export interface WorkspaceOptions {
   readonly include_deleted?: boolean;
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceParameterChange {
+  readonly workspace_build_id: string;
+  readonly build_number: number;
+  readonly name: string;
+  readonly old_value?: string;
+  readonly new_value: string;
+  readonly initiator_id: string;
+  readonly initiator_username: string;
+  readonly created_at: string;
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
   readonly derp_enabled: boolean;
@@ -1698,6 +2699,9 @@ export interface WorkspaceProxyStatus {
   readonly status: ProxyHealthStatus;
   readonly report?: ProxyHealthReport;
   readonly checked_at: string;
+  readonly latency_ms?: number;
+  readonly derp_reachable?: boolean;
+  readonly version_skew?: boolean;
 }
 
 // From codersdk/workspaces.go
@@ -1721,6 +2725,15 @@ export interface WorkspaceResource {
   readonly daily_cost: number;
 }
 
+// From codersdk/workspacecosts.go
+export interface WorkspaceResourceCost {
+  readonly date: string;
+  readonly resource_type: string;
+  readonly resource_name: string;
+  readonly currency: string;
+  readonly daily_cost: number;
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceResourceMetadata {
   readonly key: string;
@@ -1728,6 +2741,44 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean;
 }
 
+// From codersdk/workspacesnapshots.go
+export interface WorkspaceSnapshot {
+  readonly id: string;
+  readonly workspace_id: string;
+  readonly name: string;
+  readonly build_id: string;
+  readonly created_by: string;
+  readonly created_at: string;
+  readonly status: ProvisionerJobStatus;
+  readonly resources: string[];
+}
+
+// From codersdk/workspacetimeline.go
+export interface WorkspaceTimelineEvent {
+  readonly id: string;
+  readonly created_at: string;
+  readonly type: WorkspaceTimelineEventType;
+  readonly user_id?: string;
+  readonly build_id?: string;
+  readonly build_number?: number;
+  readonly build_reason?: BuildReason;
+  readonly transition?: WorkspaceTransition;
+  readonly job_status?: ProvisionerJobStatus;
+  readonly agent_id?: string;
+  readonly agent_name?: string;
+  readonly app_id?: string;
+  readonly app_slug?: string;
+  readonly old_health?: WorkspaceAppHealth;
+  readonly new_health?: WorkspaceAppHealth;
+  readonly old_ttl_ms?: number;
+  readonly new_ttl_ms?: number;
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceUser extends MinimalUser {
+  readonly role: WorkspaceRole;
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string;
@@ -1743,6 +2794,21 @@ export interface WorkspacesResponse {
 export type APIKeyScope = "all" | "application_connect";
 export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"];
 
+// From codersdk/apikey.go
+export type APITokenScope =
+  | "template:read"
+  | "template:write"
+  | "user:read"
+  | "workspace:read"
+  | "workspace:write";
+export const APITokenScopes: APITokenScope[] = [
+  "template:read",
+  "template:write",
+  "user:read",
+  "workspace:read",
+  "workspace:write",
+];
+
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace";
 export const AgentSubsystems: AgentSubsystem[] = [
@@ -1755,8 +2821,10 @@ export const AgentSubsystems: AgentSubsystem[] = [
 export type AuditAction =
   | "create"
   | "delete"
+  | "ip_rejected"
   | "login"
   | "logout"
+  | "port_forward"
   | "register"
   | "start"
   | "stop"
@@ -1764,8 +2832,10 @@ export type AuditAction =
 export const AuditActions: AuditAction[] = [
   "create",
   "delete",
+  "ip_rejected",
   "login",
   "logout",
+  "port_forward",
   "register",
   "start",
   "stop",
@@ -1777,11 +2847,28 @@ export type AutomaticUpdates = "always" | "never";
 export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"];
 
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "initiator";
+export type BuildReason =
+  | "autostart"
+  | "autostop"
+  | "batch"
+  | "initiator"
+  | "retry"
+  | "template_update";
 export const BuildReasons: BuildReason[] = [
   "autostart",
   "autostop",
+  "batch",
   "initiator",
+  "retry",
+  "template_update",
+];
+
+// From codersdk/connectionlogs.go
+export type ConnectionType = "port_forwarding" | "reconnecting_pty" | "ssh";
+export const ConnectionTypes: ConnectionType[] = [
+  "port_forwarding",
+  "reconnecting_pty",
+  "ssh",
 ];
 
 // From codersdk/workspaceagents.go
@@ -1868,8 +2955,12 @@ export const FeatureNames: FeatureName[] = [
 ];
 
 // From codersdk/groups.go
-export type GroupSource = "oidc" | "user";
-export const GroupSources: GroupSource[] = ["oidc", "user"];
+export type GroupSource = "oidc" | "scim" | "user";
+export const GroupSources: GroupSource[] = ["oidc", "scim", "user"];
+
+// From codersdk/groupsync.go
+export type GroupSyncTrigger = "background" | "login";
+export const GroupSyncTriggers: GroupSyncTrigger[] = ["background", "login"];
 
 // From codersdk/health.go
 export type HealthSection =
@@ -1877,6 +2968,7 @@ export type HealthSection =
   | "DERP"
   | "Database"
   | "ProvisionerDaemons"
+  | "Pubsub"
   | "Websocket"
   | "WorkspaceProxy";
 export const HealthSections: HealthSection[] = [
@@ -1884,6 +2976,7 @@ export const HealthSections: HealthSection[] = [
   "DERP",
   "Database",
   "ProvisionerDaemons",
+  "Pubsub",
   "Websocket",
   "WorkspaceProxy",
 ];
@@ -1914,16 +3007,58 @@ export type LogSource = "provisioner" | "provisioner_daemon";
 export const LogSources: LogSource[] = ["provisioner", "provisioner_daemon"];
 
 // From codersdk/apikey.go
-export type LoginType = "" | "github" | "none" | "oidc" | "password" | "token";
+export type LoginType =
+  | ""
+  | "github"
+  | "ldap"
+  | "none"
+  | "oidc"
+  | "password"
+  | "token";
 export const LoginTypes: LoginType[] = [
   "",
   "github",
+  "ldap",
   "none",
   "oidc",
   "password",
   "token",
 ];
 
+// From codersdk/notifications.go
+export type NotificationMessageStatus = "failed" | "pending" | "sent";
+export const NotificationMessageStatuses: NotificationMessageStatus[] = [
+  "failed",
+  "pending",
+  "sent",
+];
+
+// From codersdk/notifications.go
+export type NotificationMethod = "slack" | "smtp" | "teams";
+export const NotificationMethods: NotificationMethod[] = [
+  "slack",
+  "smtp",
+  "teams",
+];
+
+// From codersdk/notifications.go
+export type NotificationTemplate =
+  | "user_account_created"
+  | "workspace_build_failed"
+  | "workspace_deleting";
+export const NotificationTemplates: NotificationTemplate[] = [
+  "user_account_created",
+  "workspace_build_failed",
+  "workspace_deleting",
+];
+
+// From codersdk/offboarding.go
+export type OffboardWorkspaceAction = "archive" | "transfer";
+export const OffboardWorkspaceActions: OffboardWorkspaceAction[] = [
+  "archive",
+  "transfer",
+];
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1952,8 +3087,13 @@ export type ProvisionerStorageMethod = "file";
 export const ProvisionerStorageMethods: ProvisionerStorageMethod[] = ["file"];
 
 // From codersdk/organizations.go
-export type ProvisionerType = "echo" | "terraform";
-export const ProvisionerTypes: ProvisionerType[] = ["echo", "terraform"];
+export type ProvisionerType = "echo" | "kubernetes" | "pulumi" | "terraform";
+export const ProvisionerTypes: ProvisionerType[] = [
+  "echo",
+  "kubernetes",
+  "pulumi",
+  "terraform",
+];
 
 // From codersdk/workspaceproxy.go
 export type ProxyHealthStatus =
@@ -2028,9 +3168,13 @@ export type ResourceType =
   | "health_settings"
   | "license"
   | "organization"
+  | "organization_member"
+  | "passkey"
   | "template"
   | "template_version"
+  | "two_factor_policy"
   | "user"
+  | "webhook"
   | "workspace"
   | "workspace_build"
   | "workspace_proxy";
@@ -2042,9 +3186,13 @@ export const ResourceTypes: ResourceType[] = [
   "health_settings",
   "license",
   "organization",
+  "organization_member",
+  "passkey",
   "template",
   "template_version",
+  "two_factor_policy",
   "user",
+  "webhook",
   "workspace",
   "workspace_build",
   "workspace_proxy",
@@ -2073,12 +3221,28 @@ export const TemplateInsightsSections: TemplateInsightsSection[] = [
 export type TemplateRole = "" | "admin" | "use";
 export const TemplateRoles: TemplateRole[] = ["", "admin", "use"];
 
+// From codersdk/templateversions.go
+export type TemplateVersionFileStatus = "added" | "modified" | "removed";
+export const TemplateVersionFileStatuses: TemplateVersionFileStatus[] = [
+  "added",
+  "modified",
+  "removed",
+];
+
 // From codersdk/templateversions.go
 export type TemplateVersionWarning = "UNSUPPORTED_WORKSPACES";
 export const TemplateVersionWarnings: TemplateVersionWarning[] = [
   "UNSUPPORTED_WORKSPACES",
 ];
 
+// From codersdk/templates.go
+export type TemplateVisibility = "private" | "public" | "unlisted";
+export const TemplateVisibilities: TemplateVisibility[] = [
+  "private",
+  "public",
+  "unlisted",
+];
+
 // From codersdk/users.go
 export type UserStatus = "active" | "dormant" | "suspended";
 export const UserStatuses: UserStatus[] = ["active", "dormant", "suspended"];
@@ -2090,6 +3254,33 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ];
 
+// From codersdk/webhooks.go
+export type WebhookDeliveryStatus = "delivered" | "failed" | "pending";
+export const WebhookDeliveryStatuses: WebhookDeliveryStatus[] = [
+  "delivered",
+  "failed",
+  "pending",
+];
+
+// From codersdk/webhooks.go
+export type WebhookEvent =
+  | "template.updated"
+  | "user.created"
+  | "workspace.dormant"
+  | "workspace.maintenance"
+  | "workspace_build.failed"
+  | "workspace_build.started"
+  | "workspace_build.succeeded";
+export const WebhookEvents: WebhookEvent[] = [
+  "template.updated",
+  "user.created",
+  "workspace.dormant",
+  "workspace.maintenance",
+  "workspace_build.failed",
+  "workspace_build.started",
+  "workspace_build.succeeded",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -2113,6 +3304,28 @@ export const WorkspaceAgentLifecycles: WorkspaceAgentLifecycle[] = [
   "starting",
 ];
 
+// From codersdk/workspaceagentportshares.go
+export type WorkspaceAgentPortShareLevel = "authenticated" | "public";
+export const WorkspaceAgentPortShareLevels: WorkspaceAgentPortShareLevel[] = [
+  "authenticated",
+  "public",
+];
+
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentScriptPhase =
+  | "post_start"
+  | "pre_start"
+  | "pre_stop"
+  | "start"
+  | "stop";
+export const WorkspaceAgentScriptPhases: WorkspaceAgentScriptPhase[] = [
+  "post_start",
+  "pre_start",
+  "pre_stop",
+  "start",
+  "stop",
+];
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentStartupScriptBehavior = "blocking" | "non-blocking";
 export const WorkspaceAgentStartupScriptBehaviors: WorkspaceAgentStartupScriptBehavior[] =
@@ -2152,6 +3365,10 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ];
 
+// From codersdk/workspaces.go
+export type WorkspaceRole = "" | "use";
+export const WorkspaceRoles: WorkspaceRole[] = ["", "use"];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -2177,6 +3394,23 @@ export const WorkspaceStatuses: WorkspaceStatus[] = [
   "stopping",
 ];
 
+// From codersdk/workspacetimeline.go
+export type WorkspaceTimelineEventType =
+  | "agent_connected"
+  | "agent_disconnected"
+  | "app_health_changed"
+  | "build_completed"
+  | "build_started"
+  | "ttl_changed";
+export const WorkspaceTimelineEventTypes: WorkspaceTimelineEventType[] = [
+  "agent_connected",
+  "agent_disconnected",
+  "app_health_changed",
+  "build_completed",
+  "build_started",
+  "ttl_changed",
+];
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceTransition = "delete" | "start" | "stop";
 export const WorkspaceTransitions: WorkspaceTransition[] = [
@@ -2231,6 +3465,18 @@ export interface HealthcheckProvisionerDaemonsReportItem {
   readonly warnings: HealthMessage[];
 }
 
+// From healthcheck/pubsub.go
+export interface HealthcheckPubsubReport {
+  readonly healthy: boolean;
+  readonly severity: HealthSeverity;
+  readonly warnings: HealthMessage[];
+  readonly dismissed: boolean;
+  readonly latency: string;
+  readonly latency_ms: number;
+  readonly threshold_ms: number;
+  readonly error?: string;
+}
+
 // From healthcheck/healthcheck.go
 export interface HealthcheckReport {
   readonly time: string;
@@ -2241,6 +3487,7 @@ export interface HealthcheckReport {
   readonly access_url: HealthcheckAccessURLReport;
   readonly websocket: HealthcheckWebsocketReport;
   readonly database: HealthcheckDatabaseReport;
+  readonly pubsub: HealthcheckPubsubReport;
   readonly workspace_proxy: HealthcheckWorkspaceProxyReport;
   readonly provisioner_daemons: HealthcheckProvisionerDaemonsReport;
   readonly coder_version: string;
@@ -2330,9 +3577,14 @@ export type HealthCode =
   | "EDB02"
   | "EDERP01"
   | "EDERP02"
+  | "EDERP03"
   | "EPD01"
   | "EPD02"
   | "EPD03"
+  | "EPS01"
+  | "EPS02"
+  | "EPS03"
+  | "EPS04"
   | "EUNKNOWN"
   | "EWP01"
   | "EWP02"
@@ -2350,9 +3602,14 @@ export const HealthCodes: HealthCode[] = [
   "EDB02",
   "EDERP01",
   "EDERP02",
+  "EDERP03",
   "EPD01",
   "EPD02",
   "EPD03",
+  "EPS01",
+  "EPS02",
+  "EPS03",
+  "EPS04",
   "EUNKNOWN",
   "EWP01",
   "EWP02",
@@ -2369,6 +3626,18 @@ export const HealthSeveritys: HealthSeverity[] = ["error", "ok", "warning"];
 
 // The code below is generated from coderd/healthcheck/derphealth.
 
+// From derphealth/monitor.go
+export interface DerphealthFailingNode {
+  readonly source: string;
+  readonly region_id: number;
+  readonly node_name: string;
+  readonly host_name: string;
+  readonly consecutive_failures: number;
+  readonly failing_since: string;
+  readonly error: string;
+  readonly excluded: boolean;
+}
+
 // From derphealth/derp.go
 export interface DerphealthNodeReport {
   readonly healthy: boolean;
@@ -2414,6 +3683,7 @@ export interface DerphealthReport {
   readonly netcheck?: any;
   readonly netcheck_err?: string;
   readonly netcheck_logs: string[];
+  readonly failing_nodes: DerphealthFailingNode[];
   readonly error?: string;
 }
 
@@ -5,6 +5,10 @@ import MenuItem from "@mui/material/MenuItem";
 import type { FormikContextType } from "formik";
 import dayjs from "dayjs";
 import { useNavigate } from "react-router-dom";
+import type {
+  APITokenScope,
+  APITokenScopeDescription,
+} from "api/typesGenerated";
 import {
   FormFields,
   FormSection,
@@ -24,6 +28,7 @@ import {
 interface CreateTokenFormProps {
   form: FormikContextType<CreateTokenData>;
   maxTokenLifetime?: number;
+  scopes?: APITokenScopeDescription[];
   formError: unknown;
   setFormError: (arg0: unknown) => void;
   isCreating: boolean;
@@ -33,6 +38,7 @@ interface CreateTokenFormProps {
 export const CreateTokenForm: FC<CreateTokenFormProps> = ({
   form,
   maxTokenLifetime,
+  scopes,
   formError,
   setFormError,
   isCreating,
@@ -140,6 +146,45 @@ export const CreateTokenForm: FC<CreateTokenFormProps> = ({
           </Stack>
         </FormFields>
       </FormSection>
+      {scopes && scopes.length > 0 && (
+        <FormSection
+          title="Scopes"
+          description="Limit what this token can access. Leave empty for full access."
+          classes={{ sectionInfo: classNames.sectionInfo }}
+        >
+          <FormFields>
+            <TextField
+              {...getFieldHelpers("scopes")}
+              select
+              label="Scopes"
+              value={form.values.scopes}
+              onChange={(event) => {
+                // With `multiple` set, the select value is an array.
+                const value = event.target.value as unknown;
+                void form.setFieldValue("scopes", value as APITokenScope[]);
+              }}
+              SelectProps={{
+                multiple: true,
+                renderValue: (selected: unknown) =>
+                  (selected as APITokenScope[])
+                    .map(
+                      (name) =>
+                        scopes.find((scope) => scope.name === name)
+                          ?.display_name ?? name,
+                    )
+                    .join(", "),
+              }}
+              fullWidth
+            >
+              {scopes.map((scope) => (
+                <MenuItem key={scope.name} value={scope.name}>
+                  {scope.display_name}
+                </MenuItem>
+              ))}
+            </TextField>
+          </FormFields>
+        </FormSection>
+      )}
       <FormFooter
         onCancel={() => navigate("/settings/tokens")}
         isLoading={isCreating}
@@ -17,6 +17,7 @@ import { ErrorAlert } from "components/Alert/ErrorAlert";
 const initialValues: CreateTokenData = {
   name: "",
   lifetime: 30,
+  scopes: [],
 };
 
 export const CreateTokenPage: FC = () => {
@@ -58,7 +59,8 @@ export const CreateTokenPage: FC = () => {
         {
           lifetime: values.lifetime * 24 * NANO_HOUR,
           token_name: values.name,
-          scope: "all", // tokens are currently unscoped
+          scope: "all",
+          scopes: values.scopes,
         },
         {
           onError: onCreateError,
@@ -94,11 +96,12 @@ export const CreateTokenPage: FC = () => {
       {tokenFetchFailed && <ErrorAlert error={tokenFetchError} />}
       <FullPageHorizontalForm
         title="Create Token"
-        detail="All tokens are unscoped and therefore have full resource access."
+        detail="Tokens without scopes have full resource access."
       >
         <CreateTokenForm
           form={form}
           maxTokenLifetime={tokenConfig?.max_token_lifetime}
+          scopes={tokenConfig?.scopes}
           formError={formError}
           setFormError={setFormError}
           isCreating={isCreating}
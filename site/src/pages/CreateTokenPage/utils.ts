@@ -1,8 +1,11 @@
+import type { APITokenScope } from "api/typesGenerated";
+
 export const NANO_HOUR = 3600000000000;
 
 export interface CreateTokenData {
   name: string;
   lifetime: number;
+  scopes: APITokenScope[];
 }
 
 export interface LifetimeDay {
@@ -33,6 +33,7 @@ export function HealthLayout() {
     access_url: "Access URL",
     websocket: "Websocket",
     database: "Database",
+    pubsub: "Pubsub",
     workspace_proxy: "Workspace Proxy",
     provisioner_daemons: "Provisioner Daemons",
   } as const;
@@ -0,0 +1,16 @@
+import { StoryObj, Meta } from "@storybook/react";
+import { PubsubPage } from "./PubsubPage";
+import { generateMeta } from "./storybook";
+
+const meta: Meta = {
+  title: "pages/Health/Pubsub",
+  ...generateMeta({
+    path: "/health/pubsub",
+    element: <PubsubPage />,
+  }),
+};
+
+export default meta;
+type Story = StoryObj;
+
+export const Default: Story = {};
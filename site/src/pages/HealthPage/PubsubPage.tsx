@@ -0,0 +1,63 @@
+import { useOutletContext } from "react-router-dom";
+import {
+  Header,
+  HeaderTitle,
+  HealthMessageDocsLink,
+  Main,
+  GridData,
+  GridDataLabel,
+  GridDataValue,
+  HealthyDot,
+} from "./Content";
+import { HealthcheckReport } from "api/typesGenerated";
+import { Alert } from "components/Alert/Alert";
+import { Helmet } from "react-helmet-async";
+import { pageTitle } from "utils/page";
+import { DismissWarningButton } from "./DismissWarningButton";
+
+export const PubsubPage = () => {
+  const healthStatus = useOutletContext<HealthcheckReport>();
+  const pubsub = healthStatus.pubsub;
+
+  return (
+    <>
+      <Helmet>
+        <title>{pageTitle("Pubsub - Health")}</title>
+      </Helmet>
+
+      <Header>
+        <HeaderTitle>
+          <HealthyDot severity={pubsub.severity} />
+          Pubsub
+        </HeaderTitle>
+        <DismissWarningButton healthcheck="Pubsub" />
+      </Header>
+
+      <Main>
+        {pubsub.error && <Alert severity="error">{pubsub.error}</Alert>}
+
+        {pubsub.warnings.map((warning) => {
+          return (
+            <Alert
+              actions={HealthMessageDocsLink(warning)}
+              key={warning.code}
+              severity="warning"
+            >
+              {warning.message}
+            </Alert>
+          );
+        })}
+
+        <GridData>
+          <GridDataLabel>Latency</GridDataLabel>
+          <GridDataValue>{pubsub.latency_ms}ms</GridDataValue>
+
+          <GridDataLabel>Threshold</GridDataLabel>
+          <GridDataValue>{pubsub.threshold_ms}ms</GridDataValue>
+        </GridData>
+      </Main>
+    </>
+  );
+};
+
+export default PubsubPage;
@@ -53,6 +53,9 @@ export const MockToken: TypesGen.APIKeyWithOwner = {
   scope: "all",
   lifetime_seconds: 2592000,
   token_name: "token-one",
+  scopes: [],
+  allowed_workspace_ids: [],
+  ip_allowlist: [],
   username: "admin",
 };
 
@@ -69,6 +72,9 @@ export const MockTokens: TypesGen.APIKeyWithOwner[] = [
     scope: "all",
     lifetime_seconds: 2592000,
     token_name: "token-two",
+    scopes: [],
+    allowed_workspace_ids: [],
+    ip_allowlist: [],
     username: "admin",
   },
 ];
@@ -369,6 +375,7 @@ export const MockProvisionerJob: TypesGen.ProvisionerJob = {
   },
   queue_position: 0,
   queue_size: 0,
+  priority: 0,
 };
 
 export const MockFailedProvisionerJob: TypesGen.ProvisionerJob = {
@@ -487,6 +494,29 @@ export const MockTemplate: TypesGen.Template = {
   require_active_version: false,
   deprecated: false,
   deprecation_message: "",
+  maintenance_window: {
+    schedule: "",
+    duration_ms: 0,
+  },
+  visibility: "public",
+  max_build_duration_ms: 0,
+  workspace_name_policy: {
+    pattern: "",
+    description: "",
+  },
+  max_running_workspaces: 0,
+  port_forwarding_policy: {
+    deny_by_default: false,
+    allowed_ports: [],
+  },
+  session_recording: {
+    enabled: false,
+    retention_ms: 0,
+  },
+  build_retry_policy: {
+    max_attempts: 0,
+    backoff_ms: 0,
+  },
 };
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -591,6 +621,7 @@ export const MockWorkspaceAgentScript: TypesGen.WorkspaceAgentScript = {
   script: "echo 'hello world'",
   start_blocks_login: false,
   timeout: 0,
+  phase: "start",
 };
 
 export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
@@ -619,6 +650,8 @@ export const MockWorkspaceAgent: TypesGen.WorkspaceAgent = {
   logs_overflowed: false,
   log_sources: [MockWorkspaceAgentLogSource],
   scripts: [MockWorkspaceAgentScript],
+  discovered_apps: [],
+  gpus: [],
   startup_script_behavior: "non-blocking",
   subsystems: ["envbox", "exectrace"],
   health: {
@@ -903,6 +936,7 @@ export const MockWorkspaceBuild: TypesGen.WorkspaceBuild = {
   resources: [MockWorkspaceResource],
   status: "running",
   daily_cost: 20,
+  attempt: 1,
 };
 
 export const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
@@ -925,6 +959,7 @@ export const MockWorkspaceBuildAutostart: TypesGen.WorkspaceBuild = {
   resources: [MockWorkspaceResource],
   status: "running",
   daily_cost: 20,
+  attempt: 1,
 };
 
 export const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
@@ -947,6 +982,7 @@ export const MockWorkspaceBuildAutostop: TypesGen.WorkspaceBuild = {
   resources: [MockWorkspaceResource],
   status: "running",
   daily_cost: 20,
+  attempt: 1,
 };
 
 export const MockFailedWorkspaceBuild = (
@@ -971,6 +1007,7 @@ export const MockFailedWorkspaceBuild = (
   resources: [],
   status: "failed",
   daily_cost: 20,
+  attempt: 1,
 });
 
 export const MockWorkspaceBuildStop: TypesGen.WorkspaceBuild = {
@@ -1020,6 +1057,10 @@ export const MockWorkspace: TypesGen.Workspace = {
   },
   automatic_updates: "never",
   allow_renames: true,
+  favorite: false,
+  maintenance_opt_out: false,
+  ephemeral: false,
+  dormancy_exempt: false,
 };
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -1343,6 +1384,7 @@ export const MockAuthMethodsPasswordOnly: TypesGen.AuthMethods = {
   password: { enabled: true },
   github: { enabled: false },
   oidc: { enabled: false, signInText: "", iconUrl: "" },
+  ldap: { enabled: false },
 };
 
 export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
@@ -1353,6 +1395,7 @@ export const MockAuthMethodsExternal: TypesGen.AuthMethods = {
     signInText: "Google",
     iconUrl: "/icon/google.svg",
   },
+  ldap: { enabled: false },
 };
 
 export const MockAuthMethodsAll: TypesGen.AuthMethods = {
@@ -1363,6 +1406,7 @@ export const MockAuthMethodsAll: TypesGen.AuthMethods = {
     signInText: "Google",
     iconUrl: "/icon/google.svg",
   },
+  ldap: { enabled: false },
 };
 
 export const MockGitSSHKey: TypesGen.GitSSHKey = {
@@ -2877,6 +2921,7 @@ export const MockHealth: TypesGen.HealthcheckReport = {
       "netcheck: [v1] measureAllICMPLatency: listen ip4:icmp 0.0.0.0: socket: operation not permitted",
       "netcheck: [v1] report: udp=true v6=false v6os=true mapvarydest=false hair= portmap= v4a=34.71.26.24:55368 derp=999 derpdist=999v4:2ms,10007v4:175ms,10008v4:112ms,10009v4:139ms",
     ],
+    failing_nodes: [],
   },
   access_url: {
     healthy: true,
@@ -2906,6 +2951,15 @@ export const MockHealth: TypesGen.HealthcheckReport = {
     latency_ms: 92570,
     threshold_ms: 92570,
   },
+  pubsub: {
+    healthy: true,
+    severity: "ok",
+    warnings: [],
+    dismissed: false,
+    latency: "1.503ms",
+    latency_ms: 1,
+    threshold_ms: 100,
+  },
   workspace_proxy: {
     healthy: true,
     severity: "warning",
@@ -3255,6 +3309,15 @@ export const DeploymentHealthUnhealthy: TypesGen.HealthcheckReport = {
     reachable: true,
     threshold_ms: 92570,
   },
+  pubsub: {
+    healthy: false,
+    severity: "ok",
+    warnings: [],
+    dismissed: false,
+    latency: "",
+    latency_ms: 0,
+    threshold_ms: 100,
+  },
   derp: {
     healthy: false,
     severity: "ok",
@@ -3262,6 +3325,7 @@ export const DeploymentHealthUnhealthy: TypesGen.HealthcheckReport = {
     dismissed: false,
     regions: [],
     netcheck_logs: [],
+    failing_nodes: [],
   },
   websocket: {
     healthy: false,
@@ -93,6 +93,13 @@ describe("util > workspace", () => {
         },
         "Coder",
       ],
+      [
+        {
+          ...Mocks.MockWorkspaceBuild,
+          reason: "retry",
+        },
+        "Coder",
+      ],
     ])(
       `getDisplayWorkspaceBuildInitiatedBy(%p) returns %p`,
       (build, initiatedBy) => {
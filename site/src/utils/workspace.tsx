@@ -22,6 +22,7 @@ const DisplayWorkspaceBuildStatusLanguage = {
   canceling: "Canceling",
   canceled: "Canceled",
   failed: "Failed",
+  retrying: "Retrying",
 };
 
 const DisplayAgentVersionLanguage = {
@@ -54,6 +55,15 @@ export const getDisplayWorkspaceBuildStatus = (
     // Just handle unknown as failed
     case "unknown":
     case "failed":
+      // Transient failures are retried automatically, so they may still
+      // resolve themselves.
+      if (build.retry_at) {
+        return {
+          type: "warning",
+          color: theme.experimental.roles.warning.text,
+          status: DisplayWorkspaceBuildStatusLanguage.retrying,
+        } as const;
+      }
       return {
         type: "error",
         color: theme.experimental.roles.error.text,
@@ -79,9 +89,12 @@ export const getDisplayWorkspaceBuildInitiatedBy = (
 ): string => {
   switch (build.reason) {
     case "initiator":
+    case "template_update":
+    case "batch":
       return build.initiator_name;
     case "autostart":
     case "autostop":
+    case "retry":
       return "Coder";
   }
 };
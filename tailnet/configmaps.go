@@ -239,6 +239,23 @@ func (c *configMaps) setAddresses(ips []netip.Prefix) {
 	c.Broadcast()
 }
 
+// setNodeKey replaces the node key of this node, triggering configuration of
+// the engine.  c.L MUST NOT be held.
+func (c *configMaps) setNodeKey(nodeKey key.NodePrivate) {
+	c.L.Lock()
+	defer c.L.Unlock()
+	pubKey := nodeKey.Public()
+	// netMapLocked shares the SelfNode with the maps it returns, so replace it
+	// instead of changing it in place.
+	selfNode := c.static.SelfNode.Clone()
+	selfNode.Key = pubKey
+	c.static.SelfNode = selfNode
+	c.static.NodeKey = pubKey
+	c.static.PrivateKey = nodeKey
+	c.netmapDirty = true
+	c.Broadcast()
+}
+
 // setBlockEndpoints sets whether we should block configuring endpoints we learn
 // from peers.  It triggers a configuration of the engine if the value changes.
 // nolint: revive
@@ -403,6 +420,11 @@ func (c *configMaps) updatePeerLocked(update *proto.CoordinateResponse_PeerUpdat
 	case ok && update.Kind == proto.CoordinateResponse_PeerUpdate_NODE:
 		// update
 		node.Created = lc.node.Created
+		if node.Key != lc.node.Key {
+			// The peer rotated its node key, so the engine has no status for
+			// the new key yet.  Keep the connection alive if it was before.
+			node.KeepAlive = node.KeepAlive || lc.node.KeepAlive
+		}
 		dirty = !lc.node.Equal(node)
 		lc.node = node
 		lc.lost = false
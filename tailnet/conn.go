@@ -324,6 +324,21 @@ func (c *Conn) SetNodeCallback(callback func(node *Node)) {
 	c.nodeUpdater.setCallback(callback)
 }
 
+// RotateNodeKey replaces the Wireguard node key of the connection with a new
+// one. The updated node is sent to the node callback, so peers learn about the
+// new key through the coordinator and handshake with it.
+func (c *Conn) RotateNodeKey() error {
+	if c.isClosed() {
+		return ErrConnClosed
+	}
+	nodePrivateKey := key.NewNode()
+	c.nodeUpdater.setKey(nodePrivateKey.Public())
+	c.configMaps.setNodeKey(nodePrivateKey)
+	c.logger.Info(context.Background(), "rotated node key",
+		slog.F("key_id", nodePrivateKey.Public().ShortString()))
+	return nil
+}
+
 // SetDERPMap updates the DERPMap of a connection.
 func (c *Conn) SetDERPMap(derpMap *tailcfg.DERPMap) {
 	c.configMaps.setDERPMap(DERPMapToProto(derpMap))
@@ -591,6 +606,8 @@ func (c *Conn) forwardTCP(src, dst netip.AddrPort) (handler func(net.Conn), opts
 
 // SetConnStatsCallback sets a callback to be called after maxPeriod or
 // maxConns, whichever comes first. Multiple calls overwrites the callback.
+// Physical connections relayed through DERP have a destination address of
+// tailcfg.DerpMagicIPAddr, with the DERP region ID as the port.
 func (c *Conn) SetConnStatsCallback(maxPeriod time.Duration, maxConns int, dump func(start, end time.Time, virtual, physical map[netlogtype.Connection]netlogtype.Counts)) {
 	connStats := connstats.NewStatistics(maxPeriod, maxConns, dump)
 
@@ -616,6 +633,7 @@ func (c *Conn) SetConnStatsCallback(maxPeriod time.Duration, maxConns int, dump
 	}
 
 	c.tunDevice.SetStatistics(connStats)
+	c.magicConn.SetStatistics(connStats)
 }
 
 func (c *Conn) MagicsockServeHTTPDebug(w http.ResponseWriter, r *http.Request) {
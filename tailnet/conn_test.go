@@ -159,6 +159,59 @@ func TestTailnet(t *testing.T) {
 	})
 }
 
+// TestConn_RotateNodeKey tests that peers can still connect after the node key
+// is rotated and the new node is sent to them.
+func TestConn_RotateNodeKey(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	ctx := testutil.Context(t, testutil.WaitLong)
+	derpMap, _ := tailnettest.RunDERPAndSTUN(t)
+
+	w1IP := tailnet.IP()
+	w1, err := tailnet.NewConn(&tailnet.Options{
+		Addresses: []netip.Prefix{netip.PrefixFrom(w1IP, 128)},
+		Logger:    logger.Named("w1"),
+		DERPMap:   derpMap,
+	})
+	require.NoError(t, err)
+	w2, err := tailnet.NewConn(&tailnet.Options{
+		Addresses: []netip.Prefix{netip.PrefixFrom(tailnet.IP(), 128)},
+		Logger:    logger.Named("w2"),
+		DERPMap:   derpMap,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = w1.Close()
+		_ = w2.Close()
+	})
+	stitch(t, w2, w1)
+	stitch(t, w1, w2)
+	require.True(t, w2.AwaitReachable(ctx, w1IP))
+
+	listener, err := w1.Listen("tcp", ":35565")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			nc, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = nc.Close()
+		}
+	}()
+
+	oldKey := w1.Node().Key
+	require.NoError(t, w1.RotateNodeKey())
+	require.NotEqual(t, oldKey, w1.Node().Key)
+
+	nc, err := w2.DialContextTCP(ctx, netip.AddrPortFrom(w1IP, 35565))
+	require.NoError(t, err)
+	_ = nc.Close()
+	_, ok := w2.NodeAddresses(oldKey)
+	require.False(t, ok)
+}
+
 // TestConn_PreferredDERP tests that we only trigger the NodeCallback when we have a preferred DERP server.
 func TestConn_PreferredDERP(t *testing.T) {
 	t.Parallel()
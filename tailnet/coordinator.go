@@ -1,6 +1,7 @@
 package tailnet
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -60,6 +61,10 @@ type CoordinatorV2 interface {
 	Node(id uuid.UUID) *Node
 	Close() error
 	Coordinate(ctx context.Context, id uuid.UUID, name string, a TunnelAuth) (chan<- *proto.CoordinateRequest, <-chan *proto.CoordinateResponse)
+	// RotateNodeKeys asks the peers connected to this coordinator that have
+	// used the same node key since before olderThan to rotate it. It returns
+	// the number of peers asked.
+	RotateNodeKeys(olderThan time.Time) int
 }
 
 // Node represents a node in the network.
@@ -98,6 +103,7 @@ type Node struct {
 type Coordinatee interface {
 	UpdatePeers([]*proto.CoordinateResponse_PeerUpdate) error
 	SetNodeCallback(func(*Node))
+	RotateNodeKey() error
 }
 
 type Coordination interface {
@@ -151,6 +157,13 @@ func (c *remoteCoordination) respLoop() {
 			c.sendErr(xerrors.Errorf("update peers: %w", err))
 			return
 		}
+		if resp.GetRotateNodeKey() {
+			err = c.coordinatee.RotateNodeKey()
+			if err != nil {
+				c.sendErr(xerrors.Errorf("rotate node key: %w", err))
+				return
+			}
+		}
 	}
 }
 
@@ -301,6 +314,13 @@ func (c *inMemoryCoordination) respLoop() {
 				c.sendErr(xerrors.Errorf("failed to update peers: %w", err))
 				return
 			}
+			if resp.GetRotateNodeKey() {
+				err = c.coordinatee.RotateNodeKey()
+				if err != nil {
+					c.sendErr(xerrors.Errorf("failed to rotate node key: %w", err))
+					return
+				}
+			}
 		}
 	}
 }
@@ -651,6 +671,9 @@ func (c *core) nodeUpdateLocked(p *peer, node *proto.Node) error {
 		slog.F("peer_id", p.id),
 		slog.F("node", node.String()))
 
+	if p.node == nil || !bytes.Equal(p.node.Key, node.Key) {
+		p.keySince = time.Now()
+	}
 	p.node = node
 	c.updateTunnelPeersLocked(p.id, node, proto.CoordinateResponse_PeerUpdate_NODE, "node update")
 	return nil
@@ -785,6 +808,34 @@ func (c *core) removePeerLocked(id uuid.UUID, kind proto.CoordinateResponse_Peer
 	delete(c.peers, id)
 }
 
+// RotateNodeKeys asks the connected peers that have used the same node key
+// since before olderThan to rotate it.
+func (c *coordinator) RotateNodeKeys(olderThan time.Time) int {
+	return c.core.rotateNodeKeys(olderThan)
+}
+
+func (c *core) rotateNodeKeys(olderThan time.Time) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return 0
+	}
+	var asked int
+	for _, p := range c.peers {
+		if p.node == nil || !p.keySince.Before(olderThan) {
+			continue
+		}
+		err := p.rotateNodeKeyLocked()
+		if err != nil {
+			// The peer is asked again the next time keys are rotated.
+			p.logger.Warn(context.Background(), "failed to ask peer to rotate node key", slog.Error(err))
+			continue
+		}
+		asked++
+	}
+	return asked
+}
+
 // ServeAgent accepts a WebSocket connection to an agent that
 // listens to incoming connections and publishes node updates.
 func (c *coordinator) ServeAgent(conn net.Conn, id uuid.UUID, name string) error {
@@ -1021,6 +1072,11 @@ func v1RespLoop(ctx context.Context, cancel context.CancelFunc, logger slog.Logg
 			return
 		}
 		logger.Debug(ctx, "v1RespLoop got response", slog.F("resp", resp))
+		if len(resp.GetPeerUpdates()) == 0 {
+			// v1 peers can't rotate their node key, so there is nothing to
+			// send.
+			continue
+		}
 		err = q.Enqueue(resp)
 		if err != nil && !xerrors.Is(err, context.Canceled) {
 			logger.Error(ctx, "v1RespLoop failed to enqueue v1 update", slog.Error(err))
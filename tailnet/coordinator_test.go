@@ -378,6 +378,14 @@ func TestCoordinator_Lost(t *testing.T) {
 	test.LostTest(ctx, t, coordinator)
 }
 
+func TestCoordinator_RotateNodeKeys(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordinator := tailnet.NewCoordinator(logger)
+	ctx := testutil.Context(t, testutil.WaitShort)
+	test.RotateNodeKeysTest(ctx, t, coordinator)
+}
+
 func websocketConn(ctx context.Context, t *testing.T) (client net.Conn, server net.Conn) {
 	t.Helper()
 	sc := make(chan net.Conn, 1)
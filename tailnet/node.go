@@ -24,11 +24,11 @@ type nodeUpdater struct {
 	// static
 	logger   slog.Logger
 	id       tailcfg.NodeID
-	key      key.NodePublic
 	discoKey key.DiscoPublic
 	callback func(n *Node)
 
 	// dynamic
+	key                  key.NodePublic
 	preferredDERP        int
 	derpLatency          map[string]float64
 	derpForcedWebsockets map[int]string
@@ -205,6 +205,18 @@ func (u *nodeUpdater) setAddresses(ips []netip.Prefix) {
 	u.Broadcast()
 }
 
+// setKey sets the node key, after it was rotated. u.L MUST NOT be held.
+func (u *nodeUpdater) setKey(np key.NodePublic) {
+	u.L.Lock()
+	defer u.L.Unlock()
+	if u.key == np {
+		return
+	}
+	u.key = np
+	u.dirty = true
+	u.Broadcast()
+}
+
 // setCallback sets the callback for node changes. It also triggers a call
 // for the current node immediately. u.L MUST NOT be held.
 func (u *nodeUpdater) setCallback(callback func(node *Node)) {
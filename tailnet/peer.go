@@ -21,6 +21,10 @@ type peer struct {
 	reqs   <-chan *proto.CoordinateRequest
 	auth   TunnelAuth
 	sent   map[uuid.UUID]*proto.Node
+	// keySince is when the peer started using the key of its node, or when it
+	// was last asked to rotate it. Peers that can't rotate their key are only
+	// asked again once it's old enough.
+	keySince time.Time
 
 	name       string
 	start      time.Time
@@ -82,6 +86,20 @@ func (p *peer) batchUpdateMappingLocked(others []*peer, k proto.CoordinateRespon
 	}
 }
 
+// rotateNodeKeyLocked asks the peer to rotate its node key. This method is NOT threadsafe and must be
+// called while holding the core lock.
+func (p *peer) rotateNodeKeyLocked() error {
+	select {
+	case p.resps <- &proto.CoordinateResponse{RotateNodeKey: true}:
+		p.lastWrite = time.Now()
+		p.keySince = p.lastWrite
+		p.logger.Debug(context.Background(), "asked peer to rotate node key")
+		return nil
+	default:
+		return ErrWouldBlock
+	}
+}
+
 var noResp = xerrors.New("no response needed")
 
 func (p *peer) storeMappingLocked(
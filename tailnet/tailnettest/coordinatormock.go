@@ -14,6 +14,7 @@ import (
 	net "net"
 	http "net/http"
 	reflect "reflect"
+	time "time"
 
 	tailnet "github.com/coder/coder/v2/tailnet"
 	proto "github.com/coder/coder/v2/tailnet/proto"
@@ -87,6 +88,20 @@ func (mr *MockCoordinatorMockRecorder) Node(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Node", reflect.TypeOf((*MockCoordinator)(nil).Node), arg0)
 }
 
+// RotateNodeKeys mocks base method.
+func (m *MockCoordinator) RotateNodeKeys(arg0 time.Time) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateNodeKeys", arg0)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// RotateNodeKeys indicates an expected call of RotateNodeKeys.
+func (mr *MockCoordinatorMockRecorder) RotateNodeKeys(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateNodeKeys", reflect.TypeOf((*MockCoordinator)(nil).RotateNodeKeys), arg0)
+}
+
 // ServeAgent mocks base method.
 func (m *MockCoordinator) ServeAgent(arg0 net.Conn, arg1 uuid.UUID, arg2 string) error {
 	m.ctrl.T.Helper()
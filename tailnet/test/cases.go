@@ -3,6 +3,9 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/coder/coder/v2/tailnet"
 )
@@ -53,3 +56,23 @@ func BidirectionalTunnels(ctx context.Context, t *testing.T, coordinator tailnet
 	p1.AssertEventuallyHasDERP(p2.ID, 2)
 	p2.AssertEventuallyHasDERP(p1.ID, 1)
 }
+
+func RotateNodeKeysTest(ctx context.Context, t *testing.T, coordinator tailnet.CoordinatorV2) {
+	p1 := NewPeer(ctx, t, coordinator, "p1")
+	defer p1.Close(ctx)
+	p2 := NewPeer(ctx, t, coordinator, "p2")
+	defer p2.Close(ctx)
+	p1.AddTunnel(p2.ID)
+	p1.UpdateDERP(1)
+	p2.UpdateDERP(2)
+
+	p1.AssertEventuallyHasDERP(p2.ID, 2)
+	p2.AssertEventuallyHasDERP(p1.ID, 1)
+
+	// Neither key has been used for an hour.
+	assert.Equal(t, 0, coordinator.RotateNodeKeys(time.Now().Add(-time.Hour)))
+	// A time in the future matches every key.
+	assert.Equal(t, 2, coordinator.RotateNodeKeys(time.Now().Add(time.Minute)))
+	p1.AssertEventuallyAskedToRotateNodeKey()
+	p2.AssertEventuallyAskedToRotateNodeKey()
+}
@@ -26,6 +26,8 @@ type Peer struct {
 	resps  <-chan *proto.CoordinateResponse
 	reqs   chan<- *proto.CoordinateRequest
 	peers  map[uuid.UUID]PeerStatus
+
+	askedToRotateNodeKey bool
 }
 
 func NewPeer(ctx context.Context, t testing.TB, coord tailnet.CoordinatorV2, name string, id ...uuid.UUID) *Peer {
@@ -122,6 +124,19 @@ func (p *Peer) AssertEventuallyLost(other uuid.UUID) {
 	}
 }
 
+func (p *Peer) AssertEventuallyAskedToRotateNodeKey() {
+	p.t.Helper()
+	for {
+		if p.askedToRotateNodeKey {
+			return
+		}
+		if err := p.handleOneResp(); err != nil {
+			assert.NoError(p.t, err)
+			return
+		}
+	}
+}
+
 func (p *Peer) AssertEventuallyResponsesClosed() {
 	p.t.Helper()
 	for {
@@ -145,6 +160,9 @@ func (p *Peer) handleOneResp() error {
 		if !ok {
 			return responsesClosed
 		}
+		if resp.RotateNodeKey {
+			p.askedToRotateNodeKey = true
+		}
 		for _, update := range resp.PeerUpdates {
 			id, err := uuid.FromBytes(update.Id)
 			if err != nil {